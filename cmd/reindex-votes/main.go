@@ -11,8 +11,9 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"strings"
-	"time"
+
+	"Coves/internal/core/votes"
+	"Coves/internal/db/txrunner"
 
 	_ "github.com/lib/pq"
 )
@@ -69,6 +70,7 @@ func main() {
 	}
 
 	// For each user, fetch their votes from PDS
+	indexer := votes.NewIndexer()
 	totalVotes := 0
 	for _, did := range dids {
 		votes, err := fetchVotesFromPDS(pdsURL, did)
@@ -85,7 +87,7 @@ func main() {
 
 		// Index each vote
 		for _, vote := range votes {
-			if err := indexVote(ctx, db, did, vote); err != nil {
+			if err := indexVote(ctx, db, indexer, did, vote); err != nil {
 				log.Printf("Warning: failed to index vote %s: %v", vote.URI, err)
 				continue
 			}
@@ -183,85 +185,16 @@ func fetchVotesFromPDS(pdsURL, did string) ([]Record, error) {
 	return allRecords, nil
 }
 
-func indexVote(ctx context.Context, db *sql.DB, voterDID string, record Record) error {
-	// Extract vote data from record
-	subject, ok := record.Value["subject"].(map[string]interface{})
-	if !ok {
-		return fmt.Errorf("missing subject")
-	}
-	subjectURI, _ := subject["uri"].(string)
-	subjectCID, _ := subject["cid"].(string)
-	direction, _ := record.Value["direction"].(string)
-	createdAtStr, _ := record.Value["createdAt"].(string)
-
-	if subjectURI == "" || direction == "" {
-		return fmt.Errorf("invalid vote record: missing required fields")
-	}
-
-	// Parse created_at
-	createdAt, err := time.Parse(time.RFC3339, createdAtStr)
-	if err != nil {
-		createdAt = time.Now()
-	}
-
-	// Extract rkey from URI (at://did/collection/rkey)
-	parts := strings.Split(record.URI, "/")
-	if len(parts) < 5 {
-		return fmt.Errorf("invalid URI format: %s", record.URI)
-	}
-	rkey := parts[len(parts)-1]
-
-	// Start transaction
-	tx, err := db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Insert vote
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO votes (uri, cid, rkey, voter_did, subject_uri, subject_cid, direction, created_at, indexed_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
-		ON CONFLICT (uri) DO NOTHING
-	`, record.URI, record.CID, rkey, voterDID, subjectURI, subjectCID, direction, createdAt)
-	if err != nil {
-		return fmt.Errorf("failed to insert vote: %w", err)
-	}
-
-	// Update post/comment counts
-	collection := extractCollectionFromURI(subjectURI)
-	var updateQuery string
-
-	switch collection {
-	case "social.coves.community.post":
-		if direction == "up" {
-			updateQuery = `UPDATE posts SET upvote_count = upvote_count + 1, score = upvote_count + 1 - downvote_count WHERE uri = $1 AND deleted_at IS NULL`
-		} else {
-			updateQuery = `UPDATE posts SET downvote_count = downvote_count + 1, score = upvote_count - (downvote_count + 1) WHERE uri = $1 AND deleted_at IS NULL`
-		}
-	case "social.coves.community.comment":
-		if direction == "up" {
-			updateQuery = `UPDATE comments SET upvote_count = upvote_count + 1, score = upvote_count + 1 - downvote_count WHERE uri = $1 AND deleted_at IS NULL`
-		} else {
-			updateQuery = `UPDATE comments SET downvote_count = downvote_count + 1, score = upvote_count - (downvote_count + 1) WHERE uri = $1 AND deleted_at IS NULL`
-		}
-	default:
-		// Unknown collection, just index the vote
-		return tx.Commit()
-	}
-
-	if _, err := tx.ExecContext(ctx, updateQuery, subjectURI); err != nil {
-		return fmt.Errorf("failed to update vote counts: %w", err)
-	}
-
-	return tx.Commit()
-}
-
-func extractCollectionFromURI(uri string) string {
-	// at://did:plc:xxx/social.coves.community.post/rkey
-	parts := strings.Split(uri, "/")
-	if len(parts) >= 4 {
-		return parts[3]
-	}
-	return ""
+// indexVote indexes one vote record in its own transaction
+// votes.Indexer is shared with the Jetstream vote consumer, so a reindex leaves the same
+// votes and counters behind as consuming the same records from the firehose.
+func indexVote(ctx context.Context, db *sql.DB, indexer *votes.Indexer, voterDID string, record Record) error {
+	return txrunner.WithTx(ctx, db, func(ctx context.Context) error {
+		_, _, err := indexer.IndexVote(ctx, txrunner.From(ctx, db), voterDID, votes.Record{
+			URI:   record.URI,
+			CID:   record.CID,
+			Value: record.Value,
+		})
+		return err
+	})
 }
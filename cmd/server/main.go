@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -22,34 +23,60 @@ import (
 	"Coves/internal/api/routes"
 	"Coves/internal/atproto/identity"
 	"Coves/internal/atproto/jetstream"
+	"Coves/internal/atproto/lexicon"
 	"Coves/internal/atproto/oauth"
 
+	feedshandlers "Coves/internal/api/handlers/feeds"
 	imageproxyhandlers "Coves/internal/api/handlers/imageproxy"
 	"Coves/internal/core/imageproxy"
+	"Coves/internal/core/instance"
 
 	indigoauth "github.com/bluesky-social/indigo/atproto/auth"
 	indigoidentity "github.com/bluesky-social/indigo/atproto/identity"
 	"Coves/internal/core/aggregators"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/automod"
+	"Coves/internal/core/blobrefs"
 	"Coves/internal/core/blobs"
 	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/brigade"
 	"Coves/internal/core/comments"
 	"Coves/internal/core/communities"
 	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/consumergaps"
+	"Coves/internal/core/directory"
 	"Coves/internal/core/discover"
+	"Coves/internal/core/federation"
+	"Coves/internal/core/hotrank"
+	"Coves/internal/core/live"
+	"Coves/internal/core/moderation"
+	"Coves/internal/core/notifications"
+	"Coves/internal/core/permalinks"
 	"Coves/internal/core/posts"
+	"Coves/internal/core/replay"
+	"Coves/internal/core/retention"
+	"Coves/internal/core/scheduledposts"
+	"Coves/internal/core/spamguard"
+	"Coves/internal/core/suggestions"
+	"Coves/internal/core/takedown"
 	"Coves/internal/core/timeline"
+	"Coves/internal/core/translation"
 	"Coves/internal/core/unfurl"
 	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
+	"Coves/internal/pagination"
 
 	"github.com/go-chi/chi/v5"
 	chiMiddleware "github.com/go-chi/chi/v5/middleware"
 	_ "github.com/lib/pq"
-	"github.com/pressly/goose/v3"
 
-	commentsAPI "Coves/internal/api/handlers/comments"
+	adminAPI "Coves/internal/api/handlers/admin"
+	liveAPI "Coves/internal/api/handlers/live"
 
+	"Coves/internal/db/migrate"
 	postgresRepo "Coves/internal/db/postgres"
+	"Coves/internal/db/postgres/ranking"
+	"Coves/internal/db/txrunner"
 )
 
 // Compile-time interface satisfaction checks
@@ -69,7 +96,7 @@ func main() {
 		defaultPDS = "http://localhost:3001" // Local dev PDS
 	}
 
-	// Cursor secret for HMAC signing (prevents cursor manipulation)
+	// Cursor secret for encrypting pagination cursors (prevents manipulation and hides keyset scores)
 	cursorSecret := os.Getenv("CURSOR_SECRET")
 	if cursorSecret == "" {
 		// Generate a random secret if not set (dev mode)
@@ -78,6 +105,16 @@ func main() {
 		log.Println("⚠️  WARNING: Using default cursor secret. Set CURSOR_SECRET env var in production!")
 	}
 
+	// Optional previous cursor secret, accepted for verification only while CURSOR_SECRET is rotated
+	// Remove it once clients have paged past cursors signed with the old secret
+	cursorSigner, err := pagination.NewSigner(cursorSecret, os.Getenv("CURSOR_SECRET_PREVIOUS"))
+	if err != nil {
+		log.Fatalf("Failed to configure cursor signing: %v", err)
+	}
+	if os.Getenv("CURSOR_SECRET_PREVIOUS") != "" {
+		log.Println("Cursor secret rotation: accepting cursors signed with CURSOR_SECRET_PREVIOUS")
+	}
+
 	db, err := sql.Open("postgres", dbURL)
 	if err != nil {
 		log.Fatal("Failed to connect to database:", err)
@@ -95,15 +132,25 @@ func main() {
 	log.Println("Connected to AppView database")
 
 	// Run migrations
-	if err = goose.SetDialect("postgres"); err != nil {
-		log.Fatal("Failed to set goose dialect:", err)
+	// Destructive migrations only run automatically in dev; in production they're left for
+	// an operator to apply with cmd/migrate, and the server won't start until they are
+	migrationRunner, err := migrate.NewRunner(db, "internal/db/migrations")
+	if err != nil {
+		log.Fatal("Failed to load migrations:", err)
 	}
 
-	if err = goose.Up(db, "internal/db/migrations"); err != nil {
+	schemaStatus, err := migrationRunner.Up(context.Background(), os.Getenv("IS_DEV_ENV") == "true")
+	if err != nil {
 		log.Fatal("Failed to run migrations:", err)
 	}
+	migrate.LogBlocked(schemaStatus)
 
-	log.Println("Migrations completed successfully")
+	// Fail fast rather than serve on a schema this binary wasn't built for
+	if err = schemaStatus.CheckVersion(); err != nil {
+		log.Fatal("Refusing to start: ", err)
+	}
+
+	log.Printf("Migrations completed successfully (schema version %d)", schemaStatus.Current)
 
 	r := chi.NewRouter()
 
@@ -111,6 +158,12 @@ func main() {
 	r.Use(chiMiddleware.Recoverer)
 	r.Use(chiMiddleware.RequestID)
 
+	// CORS for browser clients on other domains, ahead of the rate limiter so preflights
+	// are answered without counting against it or reaching auth
+	corsOrigins := corsAllowedOrigins(os.Getenv("IS_DEV_ENV") == "true" || os.Getenv("APPVIEW_PUBLIC_URL") == "")
+	r.Use(middleware.NewCORS(corsOrigins, "/xrpc/", "/oauth/").Middleware)
+	log.Printf("CORS allowed origins for /xrpc and /oauth: %v", corsOrigins)
+
 	// Rate limiting: 100 requests per minute per IP
 	rateLimiter := middleware.NewRateLimiter(100, 1*time.Minute)
 	r.Use(rateLimiter.Middleware)
@@ -147,6 +200,21 @@ func main() {
 			identityConfig.CacheTTL = duration
 		}
 	}
+	// How long past the TTL a cached identity is served while it refreshes (negative disables)
+	if maxStale := os.Getenv("IDENTITY_CACHE_MAX_STALE"); maxStale != "" {
+		if duration, parseErr := time.ParseDuration(maxStale); parseErr == nil {
+			identityConfig.CacheMaxStale = duration
+		}
+	}
+
+	// Instance-native did:web communities: resolve our own community DIDs locally rather
+	// than fetching their DID documents from ourselves over HTTPS
+	communityDIDWebDomain := strings.ToLower(os.Getenv("COMMUNITY_DID_WEB_DOMAIN"))
+	didWebRepo := postgresRepo.NewDIDWebRepository(db)
+	if communityDIDWebDomain != "" {
+		identityConfig.LocalDirectory = communities.NewDIDWebDirectory(didWebRepo)
+		identityConfig.LocalDIDWebDomain = communityDIDWebDomain
+	}
 
 	identityResolver := identity.NewResolver(db, identityConfig)
 
@@ -206,6 +274,7 @@ func main() {
 	// Initialize user repository and service early (needed for OAuth user indexing)
 	userRepo := postgresRepo.NewUserRepository(db)
 	userService := users.NewUserService(userRepo, identityResolver, defaultPDS)
+	userActivityRepo := postgresRepo.NewUserActivityRepository(db)
 
 	// Create OAuth handler for HTTP endpoints
 	// WithUserIndexer ensures users are indexed into local database after OAuth login
@@ -226,7 +295,7 @@ func main() {
 		HTTPClient: http.Client{Timeout: 10 * time.Second},
 	}
 
-	communityRepo := postgresRepo.NewCommunityRepository(db)
+	communityRepo := postgresRepo.NewCommunityRepository(db, cursorSigner)
 
 	// V2.0: PDS-managed DID generation
 	// Community DIDs and keys are generated entirely by the PDS
@@ -273,12 +342,32 @@ func main() {
 		log.Println("Community creation open to all authenticated users")
 	}
 
+	// Instance admins - DIDs allowed to call social.coves.admin.* endpoints (none if unset)
+	var instanceAdmins []string
+	if admins := os.Getenv("INSTANCE_ADMINS"); admins != "" {
+		for _, did := range strings.Split(admins, ",") {
+			did = strings.TrimSpace(did)
+			if did != "" {
+				instanceAdmins = append(instanceAdmins, did)
+			}
+		}
+		log.Printf("Instance admin endpoints enabled for %d DIDs", len(instanceAdmins))
+	}
+
 	// V2.0: Initialize PDS account provisioner for communities (simplified)
 	// PDS handles all DID and key generation - no Coves-side cryptography needed
-	provisioner := communities.NewPDSAccountProvisioner(instanceDomain, defaultPDS)
-	log.Printf("✅ Community provisioner initialized (PDS-managed keys)")
-	log.Printf("   - Communities will be created at: %s", defaultPDS)
-	log.Printf("   - PDS will generate and manage all DIDs and keys")
+	var provisioner communities.AccountProvisioner
+	if communityDIDWebDomain != "" {
+		provisioner = communities.NewDIDWebProvisioner(instanceDomain, defaultPDS, communityDIDWebDomain, didWebRepo)
+		log.Printf("✅ Community provisioner initialized (did:web)")
+		log.Printf("   - Communities will be created at: %s", defaultPDS)
+		log.Printf("   - Community DIDs: did:web:{name}.%s (served by this AppView)", communityDIDWebDomain)
+	} else {
+		provisioner = communities.NewPDSAccountProvisioner(instanceDomain, defaultPDS)
+		log.Printf("✅ Community provisioner initialized (PDS-managed keys)")
+		log.Printf("   - Communities will be created at: %s", defaultPDS)
+		log.Printf("   - PDS will generate and manage all DIDs and keys")
+	}
 
 	// Initialize blob upload service (moved earlier for community service)
 	blobService := blobs.NewBlobService(defaultPDS)
@@ -296,6 +385,38 @@ func main() {
 		blobService,
 	)
 
+	// Community creation limits (anti-squatting): per-user rate limit, minimum account age,
+	// and reserved names. Instance admins bypass the rate limit and account age.
+	creationPolicyConfig := communities.DefaultCreationPolicyConfig()
+	creationPolicyConfig.AdminDIDs = instanceAdmins
+	if limit := os.Getenv("COMMUNITY_CREATION_LIMIT"); limit != "" {
+		if n, parseErr := strconv.Atoi(limit); parseErr == nil && n >= 0 {
+			creationPolicyConfig.MaxCreations = n
+		} else {
+			log.Printf("Warning: Invalid COMMUNITY_CREATION_LIMIT %q, using default %d", limit, communities.DefaultMaxCreations)
+		}
+	}
+	if window := os.Getenv("COMMUNITY_CREATION_WINDOW"); window != "" {
+		if duration, parseErr := time.ParseDuration(window); parseErr == nil && duration > 0 {
+			creationPolicyConfig.Window = duration
+		} else {
+			log.Printf("Warning: Invalid COMMUNITY_CREATION_WINDOW %q, using default %s", window, communities.DefaultCreationWindow)
+		}
+	}
+	if minAge := os.Getenv("COMMUNITY_MIN_ACCOUNT_AGE"); minAge != "" {
+		if duration, parseErr := time.ParseDuration(minAge); parseErr == nil && duration >= 0 {
+			creationPolicyConfig.MinAccountAge = duration
+		} else {
+			log.Printf("Warning: Invalid COMMUNITY_MIN_ACCOUNT_AGE %q, using default %s", minAge, communities.DefaultMinAccountAge)
+		}
+	}
+	creationPolicy := communities.NewCreationPolicy(postgresRepo.NewCommunityCreationPolicyRepository(db), creationPolicyConfig)
+	if svc, ok := communityService.(interface{ SetCreationPolicy(*communities.CreationPolicy) }); ok {
+		svc.SetCreationPolicy(creationPolicy)
+		log.Printf("✅ Community creation limits: %d per %s, minimum account age %s",
+			creationPolicyConfig.MaxCreations, creationPolicyConfig.Window, creationPolicyConfig.MinAccountAge)
+	}
+
 	// Authenticate Coves instance with PDS to enable community record writes
 	// The instance needs a PDS account to write community records it owns
 	pdsHandle := os.Getenv("PDS_INSTANCE_HANDLE")
@@ -317,27 +438,148 @@ func main() {
 		log.Println("Community creation via write-forward is disabled")
 	}
 
+	// Every consumer's collections must be record lexicons checked into the repo; a typo'd
+	// collection would otherwise subscribe to nothing and silently stop indexing
+	lexiconSchemas, err := lexicon.Load()
+	if err != nil {
+		log.Fatalf("Failed to load lexicons: %v", err)
+	}
+	if err := jetstream.CheckCollections(jetstream.ConsumerRegistry, lexiconSchemas); err != nil {
+		log.Fatalf("Jetstream collection self-check failed: %v", err)
+	}
+	log.Printf("✅ Jetstream collections match the lexicons (%d consumers)", len(jetstream.ConsumerRegistry))
+
+	// consumerJetstreamURL sets the consumer's wantedCollections on a subscribe URL; any
+	// wantedCollections in a *_JETSTREAM_URL override are replaced
+	consumerJetstreamURL := func(routes jetstream.ConsumerRoutes, baseURL string) string {
+		wsURL, urlErr := routes.SubscribeURL(baseURL)
+		if urlErr != nil {
+			log.Fatalf("Invalid %s Jetstream URL: %v", routes.Name, urlErr)
+		}
+		return wsURL
+	}
+
 	// Start Jetstream consumer for read-forward user indexing
-	jetstreamURL := os.Getenv("JETSTREAM_URL")
-	if jetstreamURL == "" {
-		jetstreamURL = "wss://jetstream2.us-east.bsky.network/subscribe?wantedCollections=social.coves.actor.profile"
+	jetstreamBaseURL := os.Getenv("JETSTREAM_URL")
+	if jetstreamBaseURL == "" {
+		jetstreamBaseURL = "wss://jetstream2.us-east.bsky.network/subscribe"
 	}
+	jetstreamURL := consumerJetstreamURL(jetstream.UserConsumerRoutes, jetstreamBaseURL)
 
 	pdsFilter := os.Getenv("JETSTREAM_PDS_FILTER") // Optional: filter to specific PDS
 
+	// Per-message read limit on every Jetstream connection; larger messages are skipped
+	if value := os.Getenv("JETSTREAM_MAX_MESSAGE_BYTES"); value != "" {
+		if n, parseErr := strconv.ParseInt(value, 10, 64); parseErr == nil && n > 0 {
+			jetstream.SetMaxMessageBytes(n)
+		} else {
+			log.Printf("Warning: Invalid JETSTREAM_MAX_MESSAGE_BYTES %q, using default %d", value, jetstream.DefaultMaxMessageBytes)
+		}
+	}
+
+	// Dead letter queue shared by all record consumers
+	// Events whose $type doesn't match their collection or whose record is oversized are stored
+	// here instead of being indexed
+	deadLetterQueue := jetstream.NewPostgresDeadLetterQueue(db)
+
+	// Windows of firehose events the consumers missed: a replay cursor older than
+	// JETSTREAM_REPLAY_WINDOW that resumes more than JETSTREAM_GAP_THRESHOLD past it, or a
+	// connection without a cursor that was down longer than the threshold. Consumer positions
+	// are saved so restarts resume from them. getTimeline and getCommunity report the gaps
+	// overlapping a page until an admin marks them backfilled
+	consumerGapsRepo := postgresRepo.NewConsumerGapsRepository(db)
+	gapThreshold := consumergaps.DefaultThreshold
+	if value := os.Getenv("JETSTREAM_GAP_THRESHOLD"); value != "" {
+		if duration, parseErr := time.ParseDuration(value); parseErr == nil && duration > 0 {
+			gapThreshold = duration
+		} else {
+			log.Printf("Warning: Invalid JETSTREAM_GAP_THRESHOLD %q, using default %s", value, consumergaps.DefaultThreshold)
+		}
+	}
+	gapReplayWindow := consumergaps.DefaultReplayWindow
+	if value := os.Getenv("JETSTREAM_REPLAY_WINDOW"); value != "" {
+		if duration, parseErr := time.ParseDuration(value); parseErr == nil && duration > 0 {
+			gapReplayWindow = duration
+		} else {
+			log.Printf("Warning: Invalid JETSTREAM_REPLAY_WINDOW %q, using default %s", value, consumergaps.DefaultReplayWindow)
+		}
+	}
+
+	// Redelivered events older than the indexed record are skipped and counted; hard deletes
+	// leave their rev as a tombstone so a replayed create can't bring the record back
+	recordTombstones := postgresRepo.NewRecordTombstoneRepository(db)
+	replayGuard := jetstream.NewReplayGuard(recordTombstones)
+
 	// Create user consumer with session handle updater to sync OAuth sessions on handle changes
 	var consumerOpts []jetstream.ConsumerOption
 	if sessionUpdater, ok := baseOAuthStore.(jetstream.SessionHandleUpdater); ok {
 		consumerOpts = append(consumerOpts, jetstream.WithSessionHandleUpdater(sessionUpdater))
 		log.Println("✅ OAuth session handle sync enabled for identity changes")
 	}
+	// Neutralize votes of accounts taken down or suspended by their PDS (restored on reactivation)
+	consumerOpts = append(consumerOpts, jetstream.WithVoteNullifier(postgresRepo.NewVoteRepository(db)))
+	userPreferencesRepo := postgresRepo.NewUserPreferencesRepository(db)
+	consumerOpts = append(consumerOpts, jetstream.WithPreferencesRepository(userPreferencesRepo))
+	consumerOpts = append(consumerOpts, jetstream.WithAccountStatusRecorder(userActivityRepo))
+	consumerOpts = append(consumerOpts, jetstream.WithDeadLetterQueue(deadLetterQueue))
+	consumerOpts = append(consumerOpts, jetstream.WithReplayGuard(replayGuard))
+	// Blobs referenced by indexed records; the image proxy refuses those only hidden content references
+	blobReferenceRepo := postgresRepo.NewBlobReferenceRepository(db)
+	consumerOpts = append(consumerOpts, jetstream.WithBlobReferences(blobReferenceRepo))
+	// Record the blobs of content indexed before blob references existed, so hidden content
+	// indexed back then has its blobs blocked too
+	if backfiller, ok := blobReferenceRepo.(interface {
+		BackfillReferences(ctx context.Context, batchSize int) (int, error)
+	}); ok {
+		go func() {
+			backfilled, backfillErr := backfiller.BackfillReferences(context.Background(), 500)
+			if backfillErr != nil {
+				log.Printf("Warning: Failed to backfill blob references: %v", backfillErr)
+			}
+			if backfilled > 0 {
+				log.Printf("Backfilled blob references for %d records", backfilled)
+			}
+		}()
+	}
+	// Identity lookups run on a worker pool (events for one DID stay in order)
+	if value := os.Getenv("USER_JETSTREAM_WORKERS"); value != "" {
+		if n, parseErr := strconv.Atoi(value); parseErr == nil && n > 0 {
+			consumerOpts = append(consumerOpts, jetstream.WithIdentityWorkers(n))
+		} else {
+			log.Printf("Warning: Invalid USER_JETSTREAM_WORKERS %q, using default %d", value, jetstream.DefaultUserConsumerWorkers)
+		}
+	}
 	userConsumer := jetstream.NewUserEventConsumer(userService, identityResolver, jetstreamURL, pdsFilter, consumerOpts...)
 	ctx := context.Background()
-	go func() {
-		if startErr := userConsumer.Start(ctx); startErr != nil {
-			log.Printf("Jetstream consumer stopped: %v", startErr)
+	userConsumer.StartWorkers(ctx)
+
+	// Jetstream consumers share one connection through a dispatcher that routes events by
+	// collection; JETSTREAM_PER_CONSUMER_CONNECTIONS=true falls back to one connection per consumer
+	perConsumerJetstream := os.Getenv("JETSTREAM_PER_CONSUMER_CONNECTIONS") == "true"
+	jetstreamDispatcherURL := os.Getenv("JETSTREAM_DISPATCHER_URL")
+	if jetstreamDispatcherURL == "" {
+		jetstreamDispatcherURL = "ws://localhost:6008/subscribe"
+	}
+	jetstreamDispatcher := jetstream.NewJetstreamDispatcher(jetstreamDispatcherURL, jetstream.DefaultDispatcherWorkers, jetstream.DefaultDispatcherQueueSize)
+	jetstreamDispatcher.SetGapRecorder(consumerGapsRepo, gapThreshold, gapReplayWindow)
+
+	// startJetstreamConsumer runs a consumer on its own connection in per-consumer mode,
+	// otherwise registers its routes (collections or event kinds) with the dispatcher
+	startJetstreamConsumer := func(routes jetstream.ConsumerRoutes, connector interface{ Start(context.Context) error }, handler jetstream.EventHandler) {
+		if perConsumerJetstream {
+			go func() {
+				if startErr := connector.Start(ctx); startErr != nil {
+					log.Printf("%s Jetstream consumer stopped: %v", routes.Name, startErr)
+				}
+			}()
+			return
 		}
-	}()
+		if registerErr := jetstreamDispatcher.Register(routes.Name, handler, routes.Routes()...); registerErr != nil {
+			log.Fatalf("Failed to register %s Jetstream consumer: %v", routes.Name, registerErr)
+		}
+	}
+
+	startJetstreamConsumer(jetstream.UserConsumerRoutes, userConsumer, userConsumer.Queued())
 
 	log.Printf("Started Jetstream user consumer: %s", jetstreamURL)
 
@@ -345,13 +587,13 @@ func main() {
 	// This consumer indexes:
 	// 1. Community profiles (social.coves.community.profile) - in community's own repo
 	// 2. User subscriptions (social.coves.community.subscription) - in user's repo
-	communityJetstreamURL := os.Getenv("COMMUNITY_JETSTREAM_URL")
-	if communityJetstreamURL == "" {
-		// Local Jetstream for communities - filter to our instance's collections
-		// IMPORTANT: We listen to social.coves.community.subscription (not social.coves.community.subscribe)
-		// because subscriptions are RECORD TYPES in the communities namespace, not XRPC procedures
-		communityJetstreamURL = "ws://localhost:6008/subscribe?wantedCollections=social.coves.community.profile&wantedCollections=social.coves.community.subscription"
+	// 3. Community rules documents (social.coves.community.rules) - in community's own repo
+	// Local Jetstream for communities; the collections come from jetstream.CommunityConsumerRoutes
+	communityJetstreamBaseURL := os.Getenv("COMMUNITY_JETSTREAM_URL")
+	if communityJetstreamBaseURL == "" {
+		communityJetstreamBaseURL = "ws://localhost:6008/subscribe"
 	}
+	communityJetstreamURL := consumerJetstreamURL(jetstream.CommunityConsumerRoutes, communityJetstreamBaseURL)
 
 	// Initialize community event consumer with did:web verification
 	skipDIDWebVerification := os.Getenv("SKIP_DID_WEB_VERIFICATION") == "true"
@@ -360,19 +602,169 @@ func main() {
 		log.Println("   Set SKIP_DID_WEB_VERIFICATION=false for production")
 	}
 
+	// Instance federation allow/deny policy for remote communities
+	// Rule changes re-evaluate indexed communities in the background
+	federationService := federation.NewFederationService(postgresRepo.NewFederationRepository(db), instanceDomain)
+	go federationService.Start(ctx)
+
 	// Pass identity resolver to consumer for PLC handle resolution (source of truth)
 	communityEventConsumer := jetstream.NewCommunityEventConsumer(communityRepo, instanceDID, skipDIDWebVerification, identityResolver)
-	communityJetstreamConnector := jetstream.NewCommunityJetstreamConnector(communityEventConsumer, communityJetstreamURL)
-
-	go func() {
-		if startErr := communityJetstreamConnector.Start(ctx); startErr != nil {
-			log.Printf("Community Jetstream consumer stopped: %v", startErr)
+	communityEventConsumer.SetDeadLetterQueue(deadLetterQueue)
+	communityEventConsumer.SetReplayGuard(replayGuard)
+	communityEventConsumer.SetFederationPolicy(federationService)
+	communityEventConsumer.SetBlobReferences(blobReferenceRepo)
+
+	// Persist hostedBy verification results so restarts don't refetch every instance's DID document
+	hostVerificationTTL := jetstream.DefaultHostVerificationTTL
+	if ttl := os.Getenv("COMMUNITY_HOSTED_BY_VERIFICATION_TTL"); ttl != "" {
+		if duration, parseErr := time.ParseDuration(ttl); parseErr == nil && duration > 0 {
+			hostVerificationTTL = duration
+		} else {
+			log.Printf("Warning: Invalid COMMUNITY_HOSTED_BY_VERIFICATION_TTL %q, using default %s", ttl, jetstream.DefaultHostVerificationTTL)
 		}
-	}()
+	}
+	communityEventConsumer.SetHostVerificationStore(postgresRepo.NewHostVerificationRepository(db), hostVerificationTTL)
+
+	// Community rules documents are sanitized and indexed from social.coves.community.rules records
+	communityRulesRepo := postgresRepo.NewCommunityRulesRepository(db)
+	communityEventConsumer.SetRulesRepository(communityRulesRepo)
+	if svc, ok := communityService.(interface{ SetRulesRepository(communities.RulesRepository) }); ok {
+		svc.SetRulesRepository(communityRulesRepo)
+	}
+
+	// Owners and instance admins can delete communities; PDS accounts are deactivated after the grace period
+	// Admin removals record a moderation action in the same transaction as the deletion
+	if svc, ok := communityService.(interface {
+		SetDeletionRepository(communities.DeletionRepository)
+		SetInstanceAdmins([]string)
+		SetTxRunner(txrunner.Runner)
+	}); ok {
+		svc.SetDeletionRepository(postgresRepo.NewCommunityDeletionRepository(db))
+		svc.SetInstanceAdmins(instanceAdmins)
+		svc.SetTxRunner(txrunner.NewRunner(db))
+	}
+
+	// Admin and moderator actions are recorded to a hash-chained, append-only audit log
+	auditService := audit.NewService(postgresRepo.NewAuditRepository(db))
+	if svc, ok := communityService.(interface{ SetAuditLog(audit.Recorder) }); ok {
+		svc.SetAuditLog(auditService)
+	}
+
+	// Creations interrupted by PDS failures resume on retry; admins clean up ones that never finish
+	// PDS_ADMIN_PASSWORD lets cleanup delete the orphaned PDS accounts
+	var provisionings adminAPI.Provisionings
+	if svc, ok := communityService.(interface {
+		adminAPI.Provisionings
+		SetProvisioningRepository(communities.ProvisioningRepository)
+		SetPDSAdminPassword(string)
+	}); ok {
+		svc.SetProvisioningRepository(postgresRepo.NewCommunityProvisioningRepository(db))
+		svc.SetPDSAdminPassword(os.Getenv("PDS_ADMIN_PASSWORD"))
+		provisionings = svc
+	}
+
+	// Creations hold their name until they finish, so a concurrent creation of it fails fast
+	nameReservations := postgresRepo.NewCommunityNameReservationRepository(db)
+	if svc, ok := communityService.(interface {
+		SetNameReservationRepository(communities.NameReservationRepository)
+	}); ok {
+		svc.SetNameReservationRepository(nameReservations)
+	}
+
+	// Subscriber counts are coalesced per community and flushed every 500ms
+	// Recount first so deltas lost by an unclean shutdown don't linger (post counts drift the same way)
+	if corrected, recountErr := communityRepo.RecountSubscriberCounts(ctx); recountErr != nil {
+		log.Printf("Failed to recount community subscribers: %v", recountErr)
+	} else if corrected > 0 {
+		log.Printf("Corrected subscriber counts for %d communities", corrected)
+	}
+	if corrected, recountErr := communityRepo.RecountPostCounts(ctx); recountErr != nil {
+		log.Printf("Failed to recount community posts: %v", recountErr)
+	} else if corrected > 0 {
+		log.Printf("Corrected post counts for %d communities", corrected)
+	}
+	subscriberCounts := jetstream.NewSubscriberCountBuffer(communityRepo, jetstream.DefaultSubscriberCountFlushInterval, jetstream.DefaultSubscriberCountMaxPending)
+	communityEventConsumer.SetSubscriberCountBuffer(subscriberCounts)
+	subscriberCountCtx, subscriberCountCancel := context.WithCancel(context.Background())
+	go subscriberCounts.Start(subscriberCountCtx)
+	communityJetstreamConnector := jetstream.NewCommunityJetstreamConnector(communityEventConsumer, communityJetstreamURL)
+	startJetstreamConsumer(jetstream.CommunityConsumerRoutes, communityJetstreamConnector, communityEventConsumer)
 
 	log.Printf("Started Jetstream community consumer: %s", communityJetstreamURL)
 	log.Println("  - Indexing: social.coves.community.profile (community profiles)")
 	log.Println("  - Indexing: social.coves.community.subscription (user subscriptions)")
+	log.Println("  - Indexing: social.coves.community.rules (community rules documents)")
+
+	// Subscribes and blocks written through the AppView are indexed immediately as pending;
+	// reap the ones whose Jetstream event never arrived (reverting their subscriber counts)
+	pendingReapCtx, pendingReapCancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(1 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-pendingReapCtx.Done():
+				log.Println("Pending subscription reaper stopped")
+				return
+			case <-ticker.C:
+				cutoff := time.Now().Add(-communities.PendingConfirmationTimeout)
+				subs, reapErr := communityRepo.DeleteUnconfirmedSubscriptions(pendingReapCtx, cutoff)
+				if reapErr != nil {
+					log.Printf("Error reaping unconfirmed subscriptions: %v", reapErr)
+				}
+				blocks, reapErr := communityRepo.DeleteUnconfirmedBlocks(pendingReapCtx, cutoff)
+				if reapErr != nil {
+					log.Printf("Error reaping unconfirmed blocks: %v", reapErr)
+				}
+				if subs > 0 || blocks > 0 {
+					log.Printf("Pending reaper: removed %d unconfirmed subscriptions, %d unconfirmed blocks", subs, blocks)
+				}
+				// Names held by creations that crashed before releasing them
+				reservations, reapErr := nameReservations.DeleteExpired(pendingReapCtx, time.Now())
+				if reapErr != nil {
+					log.Printf("Error reaping expired community name reservations: %v", reapErr)
+				}
+				if reservations > 0 {
+					log.Printf("Pending reaper: removed %d expired community name reservations", reservations)
+				}
+			}
+		}
+	}()
+	log.Printf("Started pending subscription reaper (confirmation timeout %s)", communities.PendingConfirmationTimeout)
+
+	// Periodically re-verify hosting instances' DID documents and flag communities whose
+	// hostedBy claim no longer holds
+	reverifyInterval := 6 * time.Hour
+	if interval := os.Getenv("COMMUNITY_HOSTED_BY_REVERIFY_INTERVAL"); interval != "" {
+		if duration, parseErr := time.ParseDuration(interval); parseErr == nil && duration > 0 {
+			reverifyInterval = duration
+		} else {
+			log.Printf("Warning: Invalid COMMUNITY_HOSTED_BY_REVERIFY_INTERVAL %q, using default %s", interval, reverifyInterval)
+		}
+	}
+	reverifyCtx, reverifyCancel := context.WithCancel(context.Background())
+	if !skipDIDWebVerification {
+		go func() {
+			ticker := time.NewTicker(reverifyInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-reverifyCtx.Done():
+					log.Println("hostedBy re-verification job stopped")
+					return
+				case <-ticker.C:
+					checked, flagged, reverifyErr := communityEventConsumer.ReverifyHostedBy(reverifyCtx)
+					if reverifyErr != nil {
+						log.Printf("Error re-verifying hostedBy instances: %v", reverifyErr)
+					}
+					if flagged > 0 {
+						log.Printf("hostedBy re-verification: checked %d instances, flagged %d communities", checked, flagged)
+					}
+				}
+			}
+		}()
+		log.Printf("Started hostedBy re-verification job (interval %s)", reverifyInterval)
+	}
 
 	// Start OAuth session cleanup background job with cancellable context
 	cleanupCtx, cleanupCancel := context.WithCancel(context.Background())
@@ -474,9 +866,10 @@ func main() {
 
 	// Create ServiceAuthValidator for aggregator JWT authentication
 	// This validates service JWTs signed by aggregator PDSs
+	// Issuer DIDs are cached so concurrent validations share one fetch per issuer
 	serviceValidator := &indigoauth.ServiceAuthValidator{
 		Audience:        serviceDID,
-		Dir:             identityDir,
+		Dir:             identity.NewKeyDirectory(identityDir, identity.KeyDirectoryConfig{}),
 		TimestampLeeway: 30 * time.Second,
 	}
 	log.Printf("✅ Service auth validator initialized (audience: %s)", serviceDID)
@@ -494,6 +887,11 @@ func main() {
 	).WithAPIKeyValidator(apiKeyValidator)
 	log.Println("✅ Dual auth middleware initialized (OAuth + service JWT + API keys)")
 
+	// Every route goes through the registrar, which applies its auth mode and rate limit tier
+	// The auth matrix test in internal/api/routes pins each route's auth mode
+	reg := routes.NewRegistrar(r, authMiddleware)
+	reg.SetServiceAuth(dualAuth)
+
 	// Initialize unfurl cache repository
 	unfurlRepo := unfurl.NewRepository(db)
 
@@ -537,15 +935,35 @@ func main() {
 	log.Println("✅ Bluesky post service initialized")
 
 	// Initialize post service (with aggregator support)
-	postRepo := postgresRepo.NewPostRepository(db)
+	postRepo := postgresRepo.NewPostRepository(db, cursorSigner)
 	postService := posts.NewPostService(postRepo, communityService, aggregatorService, blobService, unfurlService, blueskyService, defaultPDS)
+	// Direct fetches of taken-down posts (getPost) report RecordTakenDown
+	takedownRepo := postgresRepo.NewTakedownRepository(db)
+	if svc, ok := postService.(interface{ SetTakedowns(takedown.Checker) }); ok {
+		svc.SetTakedowns(takedownRepo)
+	}
+
+	// Hash the links of posts indexed before link hashes existed (used by getDiscussions)
+	if backfiller, ok := postRepo.(interface {
+		BackfillLinkHashes(ctx context.Context, batchSize int) (int, error)
+	}); ok {
+		go func() {
+			hashed, backfillErr := backfiller.BackfillLinkHashes(context.Background(), 500)
+			if backfillErr != nil {
+				log.Printf("Warning: Failed to backfill post link hashes: %v", backfillErr)
+			}
+			if hashed > 0 {
+				log.Printf("Backfilled link hashes for %d posts", hashed)
+			}
+		}()
+	}
 
 	// Initialize vote repository (used by Jetstream consumer for indexing)
 	voteRepo := postgresRepo.NewVoteRepository(db)
 	log.Println("✅ Vote repository initialized (Jetstream indexing only)")
 
 	// Initialize comment repository (used by Jetstream consumer for indexing)
-	commentRepo := postgresRepo.NewCommentRepository(db)
+	commentRepo := postgresRepo.NewCommentRepository(db, cursorSigner)
 	log.Println("✅ Comment repository initialized (Jetstream indexing only)")
 
 	// Initialize vote cache (stores user votes from PDS to avoid eventual consistency issues)
@@ -564,23 +982,93 @@ func main() {
 	// Requires user and community repos for proper author/community hydration per lexicon
 	// OAuth client and store are needed for write operations (create, update, delete)
 	commentService := comments.NewCommentService(commentRepo, userRepo, postRepo, communityRepo, oauthClient, oauthStore, nil)
+	// Instance admins may use moderator tooling (comment search) in any community
+	if svc, ok := commentService.(interface{ SetInstanceAdmins([]string) }); ok {
+		svc.SetInstanceAdmins(instanceAdmins)
+	}
+	// Direct fetches of taken-down posts and comments report RecordTakenDown
+	if svc, ok := commentService.(interface{ SetTakedowns(takedown.Checker) }); ok {
+		svc.SetTakedowns(takedownRepo)
+	}
+	// Thread meta tells signed-in viewers whether they muted the thread
+	threadMuteRepo := postgresRepo.NewThreadMuteRepository(db)
+	if svc, ok := commentService.(interface {
+		SetThreadMutes(notifications.ThreadMuteChecker)
+	}); ok {
+		svc.SetThreadMutes(threadMuteRepo)
+	}
+	// Writing a comment discards the commenter's draft reply to the same parent
+	draftRepo := postgresRepo.NewDraftRepository(db)
+	draftService := comments.NewDraftService(draftRepo)
+	if svc, ok := commentService.(interface{ SetDrafts(comments.DraftDeleter) }); ok {
+		svc.SetDrafts(draftRepo)
+	}
 	log.Println("✅ Comment service initialized (with author/community hydration and write support)")
 
+	// Hot feeds rank by posts.hot_score; HOT_RANK_LIVE_SQL=true switches back to computing the
+	// rank in SQL per request, for comparing the two during rollout
+	if os.Getenv("HOT_RANK_LIVE_SQL") == "true" {
+		ranking.SetLiveHotRank(true)
+		log.Println("Hot feeds rank with the live SQL formula (HOT_RANK_LIVE_SQL=true)")
+	}
+
 	// Initialize feed service
-	feedRepo := postgresRepo.NewCommunityFeedRepository(db, cursorSecret)
-	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
+	feedRepo := postgresRepo.NewCommunityFeedRepository(db, cursorSigner)
+	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService, communityFeeds.WithModeratorLookup(communityRepo), communityFeeds.WithGapLookup(consumerGapsRepo))
 	log.Println("✅ Feed service initialized")
 
 	// Initialize timeline service (home feed from subscribed communities)
-	timelineRepo := postgresRepo.NewTimelineRepository(db, cursorSecret)
-	timelineService := timeline.NewTimelineService(timelineRepo)
+	timelineRepo := postgresRepo.NewTimelineRepository(db, cursorSigner)
+	timelineService := timeline.NewTimelineService(timelineRepo, timeline.WithGapLookup(consumerGapsRepo))
 	log.Println("✅ Timeline service initialized")
 
 	// Initialize discover service (public feed from all communities)
-	discoverRepo := postgresRepo.NewDiscoverRepository(db, cursorSecret)
+	discoverRepo := postgresRepo.NewDiscoverRepository(db, cursorSigner)
 	discoverService := discover.NewDiscoverService(discoverRepo)
+	// Hot and top discover pages cap runs of posts from one community and its share of a page
+	// (0 disables a limit)
+	diversityConfig := discover.DefaultDiversityConfig()
+	if value := os.Getenv("DISCOVER_MAX_CONSECUTIVE"); value != "" {
+		if n, parseErr := strconv.Atoi(value); parseErr == nil && n >= 0 {
+			diversityConfig.MaxConsecutive = n
+		} else {
+			log.Printf("Warning: Invalid DISCOVER_MAX_CONSECUTIVE %q, using default %d", value, diversityConfig.MaxConsecutive)
+		}
+	}
+	if value := os.Getenv("DISCOVER_MAX_COMMUNITY_SHARE"); value != "" {
+		if f, parseErr := strconv.ParseFloat(value, 64); parseErr == nil && f >= 0 && f <= 1 {
+			diversityConfig.MaxShare = f
+		} else {
+			log.Printf("Warning: Invalid DISCOVER_MAX_COMMUNITY_SHARE %q, using default %g", value, diversityConfig.MaxShare)
+		}
+	}
+	if value := os.Getenv("DISCOVER_OVERFETCH"); value != "" {
+		if n, parseErr := strconv.Atoi(value); parseErr == nil && n >= 1 {
+			diversityConfig.OverFetch = n
+		} else {
+			log.Printf("Warning: Invalid DISCOVER_OVERFETCH %q, using default %d", value, diversityConfig.OverFetch)
+		}
+	}
+	if svc, ok := discoverService.(interface{ SetDiversity(discover.DiversityConfig) }); ok {
+		svc.SetDiversity(diversityConfig)
+	}
 	log.Println("✅ Discover service initialized")
 
+	// Community suggestions from the viewer's Bluesky follows, cached per viewer for an hour
+	suggestionService := suggestions.NewService(postgresRepo.NewSuggestionsRepository(db), oauthClient)
+
+	// Initialize translation service (translate-on-demand for posts and comments)
+	// TRANSLATION_API_URL points at a LibreTranslate-compatible /translate endpoint; without it
+	// the endpoint reports TranslationUnavailable.
+	var translationBackend translation.Backend
+	if translationURL := os.Getenv("TRANSLATION_API_URL"); translationURL != "" {
+		translationBackend = translation.NewHTTPBackend(translationURL, os.Getenv("TRANSLATION_API_KEY"))
+		log.Printf("✅ Translation service initialized (backend: %s)", translationURL)
+	} else {
+		log.Println("Translation disabled (TRANSLATION_API_URL not set)")
+	}
+	translationService := translation.NewTranslationService(postgresRepo.NewTranslationRepository(db), translationBackend)
+
 	// Initialize image proxy (optional service for resizing/caching images)
 	imageProxyConfig := imageproxy.ConfigFromEnv()
 	var imageProxyCacheCleanupCancel context.CancelFunc = func() {} // No-op default
@@ -614,7 +1102,8 @@ func main() {
 			log.Fatalf("Failed to create image proxy service: %v", err)
 		}
 		imageProxyHandler := imageproxyhandlers.NewHandler(imageProxyService, identityResolver)
-		routes.RegisterImageProxyRoutes(r, imageProxyHandler)
+		imageProxyHandler.SetBlockList(blobReferenceRepo)
+		routes.RegisterImageProxyRoutes(reg, imageProxyHandler)
 		log.Println("✅ Image proxy enabled at /img/{preset}/plain/{did}/{cid}")
 		slog.Info("[IMAGE-PROXY] service started",
 			"base_url", imageProxyConfig.BaseURL,
@@ -640,20 +1129,84 @@ func main() {
 	// Start Jetstream consumer for posts
 	// This consumer indexes posts created in community repositories via the firehose
 	// Currently handles only CREATE operations - UPDATE/DELETE deferred until those features exist
-	postJetstreamURL := os.Getenv("POST_JETSTREAM_URL")
-	if postJetstreamURL == "" {
-		// Listen to post record creation events
-		postJetstreamURL = "ws://localhost:6008/subscribe?wantedCollections=social.coves.community.post"
+	postJetstreamBaseURL := os.Getenv("POST_JETSTREAM_URL")
+	if postJetstreamBaseURL == "" {
+		postJetstreamBaseURL = "ws://localhost:6008/subscribe"
+	}
+	postJetstreamURL := consumerJetstreamURL(jetstream.PostConsumerRoutes, postJetstreamBaseURL)
+
+	// Newly indexed posts and comments are announced to clients on social.coves.sync.subscribe
+	liveHub := live.NewHub(live.DefaultBufferSize)
+
+	// Spam heuristics at index time: posts from new accounts that repeat content across
+	// communities or post in bursts are quarantined for admin review. Instance admins and
+	// established accounts bypass the guard.
+	spamGuardConfig := spamguard.DefaultConfig()
+	spamGuardConfig.AdminDIDs = instanceAdmins
+	for _, setting := range []struct {
+		env    string
+		target *int
+	}{
+		{"SPAM_QUARANTINE_SCORE", &spamGuardConfig.QuarantineScore},
+		{"SPAM_DUPLICATE_THRESHOLD", &spamGuardConfig.DuplicateThreshold},
+		{"SPAM_RATE_THRESHOLD", &spamGuardConfig.RateThreshold},
+	} {
+		if value := os.Getenv(setting.env); value != "" {
+			if n, parseErr := strconv.Atoi(value); parseErr == nil && n > 0 {
+				*setting.target = n
+			} else {
+				log.Printf("Warning: Invalid %s %q, using default %d", setting.env, value, *setting.target)
+			}
+		}
 	}
+	for _, setting := range []struct {
+		env    string
+		target *time.Duration
+	}{
+		{"SPAM_NEW_ACCOUNT_AGE", &spamGuardConfig.NewAccountAge},
+		{"SPAM_ESTABLISHED_ACCOUNT_AGE", &spamGuardConfig.EstablishedAccountAge},
+		{"SPAM_DUPLICATE_WINDOW", &spamGuardConfig.DuplicateWindow},
+		{"SPAM_RATE_WINDOW", &spamGuardConfig.RateWindow},
+	} {
+		if value := os.Getenv(setting.env); value != "" {
+			if duration, parseErr := time.ParseDuration(value); parseErr == nil && duration > 0 {
+				*setting.target = duration
+			} else {
+				log.Printf("Warning: Invalid %s %q, using default %s", setting.env, value, *setting.target)
+			}
+		}
+	}
+	log.Printf("✅ Spam guard: quarantine at score %d (same content in >%d communities per %s, >%d posts per %s)",
+		spamGuardConfig.QuarantineScore, spamGuardConfig.DuplicateThreshold, spamGuardConfig.DuplicateWindow,
+		spamGuardConfig.RateThreshold, spamGuardConfig.RateWindow)
+
+	// Posting, commenting and voting count toward a community's weekly/monthly actives
+	communityActivityRepo := postgresRepo.NewCommunityActivityRepository(db)
+
+	// Authors of posts and comments the user consumer hasn't seen are indexed on demand, with
+	// their profile fetched from their PDS in the background
+	profileBackfiller := jetstream.NewProfileBackfiller(userRepo, jetstream.NewPDSProfileFetcher(), jetstream.DefaultProfileBackfillSweepInterval)
+	profileBackfillCtx, profileBackfillCancel := context.WithCancel(context.Background())
+	go profileBackfiller.Start(profileBackfillCtx)
+	authorIndexer := jetstream.NewAuthorIndexer(userService, identityResolver, profileBackfiller)
+
+	// Community automod rules are checked by the post and comment consumers at index time
+	automodService := automod.NewAutomodService(postgresRepo.NewAutomodRepository(db))
 
 	postEventConsumer := jetstream.NewPostEventConsumer(postRepo, communityRepo, userService, db)
+	postEventConsumer.SetAuthorIndexer(authorIndexer)
+	postEventConsumer.SetDeadLetterQueue(deadLetterQueue)
+	postEventConsumer.SetPublisher(liveHub)
+	// Account age is measured from the author's PLC genesis operation, not from when we first saw them
+	spamGuard := spamguard.NewGuard(postgresRepo.NewSpamGuardRepository(db), spamGuardConfig)
+	spamGuard.SetAccountHistory(identity.NewPLCAuditLog(identityConfig.PLCURL, identityConfig.HTTPClient))
+	postEventConsumer.SetSpamGuard(spamGuard)
+	postEventConsumer.SetActivityRecorder(communityActivityRepo)
+	postEventConsumer.SetAutomod(automodService)
+	postEventConsumer.SetBlobReferences(blobReferenceRepo)
 	postJetstreamConnector := jetstream.NewPostJetstreamConnector(postEventConsumer, postJetstreamURL)
-
-	go func() {
-		if startErr := postJetstreamConnector.Start(ctx); startErr != nil {
-			log.Printf("Post Jetstream consumer stopped: %v", startErr)
-		}
-	}()
+	postJetstreamConnector.SetGapRecorder(consumerGapsRepo, gapThreshold)
+	startJetstreamConsumer(jetstream.PostConsumerRoutes, postJetstreamConnector, postEventConsumer)
 
 	log.Printf("Started Jetstream post consumer: %s", postJetstreamURL)
 	log.Println("  - Indexing: social.coves.community.post CREATE operations")
@@ -663,23 +1216,18 @@ func main() {
 	// This consumer indexes aggregator service declarations and authorization records
 	// Following Bluesky's pattern for feed generators and labelers
 	// NOTE: Uses the same Jetstream as communities, just filtering different collections
-	aggregatorJetstreamURL := communityJetstreamURL
+	aggregatorJetstreamBaseURL := communityJetstreamBaseURL
 	// Override if specific URL needed for testing
 	if envURL := os.Getenv("AGGREGATOR_JETSTREAM_URL"); envURL != "" {
-		aggregatorJetstreamURL = envURL
-	} else if aggregatorJetstreamURL == "" {
-		// Fallback if community URL also not set
-		aggregatorJetstreamURL = "ws://localhost:6008/subscribe?wantedCollections=social.coves.aggregator.service&wantedCollections=social.coves.aggregator.authorization"
+		aggregatorJetstreamBaseURL = envURL
 	}
+	aggregatorJetstreamURL := consumerJetstreamURL(jetstream.AggregatorConsumerRoutes, aggregatorJetstreamBaseURL)
 
 	aggregatorEventConsumer := jetstream.NewAggregatorEventConsumer(aggregatorRepo)
+	aggregatorEventConsumer.SetDeadLetterQueue(deadLetterQueue)
+	aggregatorEventConsumer.SetReplayGuard(replayGuard)
 	aggregatorJetstreamConnector := jetstream.NewAggregatorJetstreamConnector(aggregatorEventConsumer, aggregatorJetstreamURL)
-
-	go func() {
-		if startErr := aggregatorJetstreamConnector.Start(ctx); startErr != nil {
-			log.Printf("Aggregator Jetstream consumer stopped: %v", startErr)
-		}
-	}()
+	startJetstreamConsumer(jetstream.AggregatorConsumerRoutes, aggregatorJetstreamConnector, aggregatorEventConsumer)
 
 	log.Printf("Started Jetstream aggregator consumer: %s", aggregatorJetstreamURL)
 	log.Println("  - Indexing: social.coves.aggregator.service (service declarations)")
@@ -687,20 +1235,17 @@ func main() {
 
 	// Start Jetstream consumer for votes
 	// This consumer indexes votes from user repositories and updates post vote counts
-	voteJetstreamURL := os.Getenv("VOTE_JETSTREAM_URL")
-	if voteJetstreamURL == "" {
-		// Listen to vote record CREATE/DELETE events from user repositories
-		voteJetstreamURL = "ws://localhost:6008/subscribe?wantedCollections=social.coves.feed.vote"
+	voteJetstreamBaseURL := os.Getenv("VOTE_JETSTREAM_URL")
+	if voteJetstreamBaseURL == "" {
+		voteJetstreamBaseURL = "ws://localhost:6008/subscribe"
 	}
+	voteJetstreamURL := consumerJetstreamURL(jetstream.VoteConsumerRoutes, voteJetstreamBaseURL)
 
 	voteEventConsumer := jetstream.NewVoteEventConsumer(voteRepo, userService, db)
+	voteEventConsumer.SetDeadLetterQueue(deadLetterQueue)
+	voteEventConsumer.SetActivityRecorder(communityActivityRepo)
 	voteJetstreamConnector := jetstream.NewVoteJetstreamConnector(voteEventConsumer, voteJetstreamURL)
-
-	go func() {
-		if startErr := voteJetstreamConnector.Start(ctx); startErr != nil {
-			log.Printf("Vote Jetstream consumer stopped: %v", startErr)
-		}
-	}()
+	startJetstreamConsumer(jetstream.VoteConsumerRoutes, voteJetstreamConnector, voteEventConsumer)
 
 	log.Printf("Started Jetstream vote consumer: %s", voteJetstreamURL)
 	log.Println("  - Indexing: social.coves.feed.vote CREATE/DELETE operations")
@@ -708,113 +1253,531 @@ func main() {
 
 	// Start Jetstream consumer for comments
 	// This consumer indexes comments from user repositories and updates parent counts
-	commentJetstreamURL := os.Getenv("COMMENT_JETSTREAM_URL")
-	if commentJetstreamURL == "" {
-		// Listen to comment record CREATE/UPDATE/DELETE events from user repositories
-		commentJetstreamURL = "ws://localhost:6008/subscribe?wantedCollections=social.coves.community.comment"
+	commentJetstreamBaseURL := os.Getenv("COMMENT_JETSTREAM_URL")
+	if commentJetstreamBaseURL == "" {
+		commentJetstreamBaseURL = "ws://localhost:6008/subscribe"
 	}
+	commentJetstreamURL := consumerJetstreamURL(jetstream.CommentConsumerRoutes, commentJetstreamBaseURL)
 
 	commentEventConsumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+	commentEventConsumer.SetDeadLetterQueue(deadLetterQueue)
+	commentEventConsumer.SetReplayGuard(replayGuard)
+	commentEventConsumer.SetPublisher(liveHub)
+	commentEventConsumer.SetActivityRecorder(communityActivityRepo)
+	commentEventConsumer.SetIdentityResolver(identityResolver)
+	commentEventConsumer.SetAuthorIndexer(authorIndexer)
+	commentEventConsumer.SetBlobReferences(blobReferenceRepo)
+	commentEventConsumer.SetAutomod(automodService)
+	commentEventConsumer.SetPostBackfill(jetstream.NewPDSPostFetcher(communityRepo), postEventConsumer)
+	// Comments deeper than this are collapsed into "continue thread" links in getComments
+	if maxDepth := os.Getenv("COMMENT_MAX_THREAD_DEPTH"); maxDepth != "" {
+		if n, parseErr := strconv.Atoi(maxDepth); parseErr == nil && n > 0 {
+			commentEventConsumer.SetMaxThreadDepth(n)
+		} else {
+			log.Printf("Warning: Invalid COMMENT_MAX_THREAD_DEPTH %q, using default %d", maxDepth, comments.DefaultMaxThreadDepth)
+		}
+	}
 	commentJetstreamConnector := jetstream.NewCommentJetstreamConnector(commentEventConsumer, commentJetstreamURL)
+	startJetstreamConsumer(jetstream.CommentConsumerRoutes, commentJetstreamConnector, commentEventConsumer)
 
+	log.Printf("Started Jetstream comment consumer: %s", commentJetstreamURL)
+	log.Println("  - Indexing: social.coves.community.comment CREATE/UPDATE/DELETE operations")
+	log.Println("  - Updating: Post comment counts and comment reply counts atomically")
+	log.Println("  - Backfilling: Unknown root posts from the community's PDS")
+
+	if !perConsumerJetstream {
+		go func() {
+			if startErr := jetstreamDispatcher.Start(ctx); startErr != nil {
+				log.Printf("Jetstream dispatcher stopped: %v", startErr)
+			}
+		}()
+		log.Printf("Started Jetstream dispatcher: one connection to %s for all consumers", jetstreamDispatcherURL)
+	}
+
+	// Start orphaned comment retry job
+	// Comments whose root post couldn't be backfilled are hidden; retry the oldest checks first
+	orphanRetryCtx, orphanRetryCancel := context.WithCancel(context.Background())
 	go func() {
-		if startErr := commentJetstreamConnector.Start(ctx); startErr != nil {
-			log.Printf("Comment Jetstream consumer stopped: %v", startErr)
+		ticker := time.NewTicker(10 * time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-orphanRetryCtx.Done():
+				log.Println("Orphaned comment retry job stopped")
+				return
+			case <-ticker.C:
+				resolved, retryErr := commentEventConsumer.ResolveOrphanedComments(orphanRetryCtx, 100)
+				if retryErr != nil {
+					log.Printf("Error resolving orphaned comments: %v", retryErr)
+				}
+				if resolved > 0 {
+					log.Printf("Orphaned comment retry: backfilled %d root posts", resolved)
+				}
+			}
 		}
 	}()
 
-	log.Printf("Started Jetstream comment consumer: %s", commentJetstreamURL)
-	log.Println("  - Indexing: social.coves.community.comment CREATE/UPDATE/DELETE operations")
-	log.Println("  - Updating: Post comment counts and comment reply counts atomically")
+	log.Println("Started orphaned comment retry job (runs every 10 minutes)")
+
+	// Start comment draft cleanup job
+	// Drafts not saved for 30 days are already hidden from getDraft; this reclaims their rows
+	draftCleanupCtx, draftCleanupCancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-draftCleanupCtx.Done():
+				log.Println("Comment draft cleanup job stopped")
+				return
+			case <-ticker.C:
+				pruned, pruneErr := draftService.PruneExpiredDrafts(draftCleanupCtx)
+				if pruneErr != nil {
+					log.Printf("Error pruning expired comment drafts: %v", pruneErr)
+				}
+				if pruned > 0 {
+					log.Printf("Comment draft cleanup: removed %d expired drafts", pruned)
+				}
+			}
+		}
+	}()
+
+	log.Println("Started comment draft cleanup job (runs hourly)")
+
+	// Start record tombstone cleanup job
+	// Deleting revs are only needed while Jetstream could still replay the original create
+	tombstoneCleanupCtx, tombstoneCleanupCancel := context.WithCancel(context.Background())
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-tombstoneCleanupCtx.Done():
+				log.Println("Record tombstone cleanup job stopped")
+				return
+			case <-ticker.C:
+				pruned, pruneErr := recordTombstones.Prune(tombstoneCleanupCtx, time.Now().Add(-replay.TombstoneRetention))
+				if pruneErr != nil {
+					log.Printf("Error pruning record tombstones: %v", pruneErr)
+				}
+				if pruned > 0 {
+					log.Printf("Record tombstone cleanup: removed %d tombstones", pruned)
+				}
+			}
+		}
+	}()
+
+	log.Println("Started record tombstone cleanup job (runs hourly)")
+
+	// Start hot rank job
+	// Rescores posts from the last week so hot feeds decay with age; votes rescore posts
+	// in between, and hot cursors pin the epoch each run publishes. Runs at startup so a fresh
+	// hot_score column is filled straight away.
+	hotRankCtx, hotRankCancel := context.WithCancel(context.Background())
+	go hotrank.NewRanker(postgresRepo.NewHotRankRepository(db)).Start(hotRankCtx, hotrank.DefaultInterval)
+
+	log.Printf("Started hot rank job (runs every %s)", hotrank.DefaultInterval)
+
+	// Start remote content retention job
+	// REMOTE_CONTENT_RETENTION_DAYS > 0 prunes remote communities' posts older than the window
+	// nightly, unless one of our users interacted with them; 0 (the default) keeps them forever
+	retentionConfig := retention.Config{}
+	if value := os.Getenv("REMOTE_CONTENT_RETENTION_DAYS"); value != "" {
+		if days, parseErr := strconv.Atoi(value); parseErr == nil && days >= 0 {
+			retentionConfig.Window = retention.WindowDays(days)
+		} else {
+			log.Printf("Warning: Invalid REMOTE_CONTENT_RETENTION_DAYS %q, keeping remote content forever", value)
+		}
+	}
+	retentionPruner := retention.NewPruner(postgresRepo.NewRetentionRepository(db, instanceDID, defaultPDS), retentionConfig)
+	retentionCtx, retentionCancel := context.WithCancel(context.Background())
+	if retentionPruner.Enabled() {
+		go retentionPruner.Start(retentionCtx, retention.DefaultInterval)
+
+		log.Printf("Started remote content retention job (runs daily; prunes remote posts older than %s days in batches of %d)",
+			os.Getenv("REMOTE_CONTENT_RETENTION_DAYS"), retentionPruner.Config().BatchSize)
+	}
+
+	// Start community active users rollup job
+	// Runs at startup and then daily, so frequent restarts don't leave the counts stale
+	activityRollupCtx, activityRollupCancel := context.WithCancel(context.Background())
+	go func() {
+		rollup := func() {
+			updated, rollupErr := communityActivityRepo.RollupActiveUsers(activityRollupCtx, time.Now())
+			if rollupErr != nil {
+				log.Printf("Error rolling up community active users: %v", rollupErr)
+			}
+			pruned, pruneErr := communityActivityRepo.PruneActivity(activityRollupCtx, time.Now().Add(-communities.ActivityRetention))
+			if pruneErr != nil {
+				log.Printf("Error pruning community activity: %v", pruneErr)
+			}
+			log.Printf("Community active users rollup: updated %d communities, pruned %d activity rows", updated, pruned)
+		}
+		rollup()
+
+		ticker := time.NewTicker(24 * time.Hour)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-activityRollupCtx.Done():
+				log.Println("Community active users rollup job stopped")
+				return
+			case <-ticker.C:
+				rollup()
+			}
+		}
+	}()
+
+	log.Println("Started community active users rollup job (runs daily)")
+
+	// Start brigade analyzer job
+	// Each run assesses the last complete bucket: posts whose vote velocity spikes far above
+	// their community's baseline, mostly from accounts new to the community, are queued for
+	// the community's moderators
+	brigadeConfig := brigade.DefaultConfig()
+	if value := os.Getenv("BRIGADE_MIN_VOTES"); value != "" {
+		if n, parseErr := strconv.Atoi(value); parseErr == nil && n > 0 {
+			brigadeConfig.MinVotes = n
+		} else {
+			log.Printf("Warning: Invalid BRIGADE_MIN_VOTES %q, using default %d", value, brigadeConfig.MinVotes)
+		}
+	}
+	for _, setting := range []struct {
+		env    string
+		target *float64
+		max    float64 // 0 for no upper bound
+	}{
+		{"BRIGADE_Z_SCORE", &brigadeConfig.ZScoreThreshold, 0},
+		{"BRIGADE_OUTSIDER_FRACTION", &brigadeConfig.OutsiderFraction, 1},
+	} {
+		if value := os.Getenv(setting.env); value != "" {
+			if f, parseErr := strconv.ParseFloat(value, 64); parseErr == nil && f > 0 && (setting.max == 0 || f <= setting.max) {
+				*setting.target = f
+			} else {
+				log.Printf("Warning: Invalid %s %q, using default %g", setting.env, value, *setting.target)
+			}
+		}
+	}
+	for _, setting := range []struct {
+		env    string
+		target *time.Duration
+	}{
+		{"BRIGADE_BUCKET_SIZE", &brigadeConfig.BucketSize},
+		{"BRIGADE_BASELINE_WINDOW", &brigadeConfig.BaselineWindow},
+	} {
+		if value := os.Getenv(setting.env); value != "" {
+			if duration, parseErr := time.ParseDuration(value); parseErr == nil && duration > 0 {
+				*setting.target = duration
+			} else {
+				log.Printf("Warning: Invalid %s %q, using default %s", setting.env, value, *setting.target)
+			}
+		}
+	}
+	brigadeAnalyzer := brigade.NewAnalyzer(postgresRepo.NewBrigadeRepository(db), brigadeConfig)
+	brigadeCtx, brigadeCancel := context.WithCancel(context.Background())
+	go func() {
+		analyze := func() {
+			report, analyzeErr := brigadeAnalyzer.Run(brigadeCtx)
+			if analyzeErr != nil {
+				log.Printf("Error analyzing vote velocity: %v", analyzeErr)
+				return
+			}
+			if report.Alerts > 0 {
+				log.Printf("Brigade analyzer: assessed %d posts, raised %d alerts", report.Assessed, report.Alerts)
+			}
+		}
+		analyze()
+
+		ticker := time.NewTicker(brigadeConfig.BucketSize)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-brigadeCtx.Done():
+				log.Println("Brigade analyzer job stopped")
+				return
+			case <-ticker.C:
+				analyze()
+			}
+		}
+	}()
+
+	log.Printf("Started brigade analyzer job (runs every %s; alerts at z-score %g with %.0f%% outsiders, min %d votes)",
+		brigadeConfig.BucketSize, brigadeConfig.ZScoreThreshold, brigadeConfig.OutsiderFraction*100, brigadeConfig.MinVotes)
+
+	// Community directory sync: serve our public communities to peer instances, and when
+	// DIRECTORY_SYNC_PEERS lists peer base URLs (comma-separated), poll their directories
+	var directoryPeers []string
+	if peers := os.Getenv("DIRECTORY_SYNC_PEERS"); peers != "" {
+		directoryPeers = strings.Split(peers, ",")
+	}
+	directoryService := directory.NewDirectoryService(postgresRepo.NewDirectoryRepository(db), federationService, identityResolver, cursorSigner, instanceDID, directoryPeers)
+	directorySyncCtx, directorySyncCancel := context.WithCancel(context.Background())
+	if len(directoryPeers) > 0 {
+		directorySyncInterval := 1 * time.Hour
+		if interval := os.Getenv("DIRECTORY_SYNC_INTERVAL"); interval != "" {
+			if duration, parseErr := time.ParseDuration(interval); parseErr == nil && duration > 0 {
+				directorySyncInterval = duration
+			} else {
+				log.Printf("Warning: Invalid DIRECTORY_SYNC_INTERVAL %q, using default %s", interval, directorySyncInterval)
+			}
+		}
+		go func() {
+			syncPeers := func() {
+				report, syncErr := directoryService.SyncPeers(directorySyncCtx)
+				if syncErr != nil {
+					log.Printf("Error syncing peer directories: %v", syncErr)
+					return
+				}
+				log.Printf("Directory sync: polled %d peers (%d failed), indexed %d communities, skipped %d",
+					report.PeersPolled, report.PeersFailed, report.Indexed, report.Skipped)
+			}
+			syncPeers()
+
+			ticker := time.NewTicker(directorySyncInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-directorySyncCtx.Done():
+					log.Println("Directory sync job stopped")
+					return
+				case <-ticker.C:
+					syncPeers()
+				}
+			}
+		}()
+		log.Printf("Started directory sync job for %d peers (runs every %s)", len(directoryPeers), directorySyncInterval)
+	}
+
+	// Start deleted community deactivation job
+	// Communities deleted more than communities.DeletionGracePeriod ago have their PDS accounts deactivated
+	deactivationCtx, deactivationCancel := context.WithCancel(context.Background())
+	if deactivator, ok := communityService.(interface {
+		DeactivateDeletedCommunities(ctx context.Context, limit int) (int, error)
+	}); ok {
+		go func() {
+			ticker := time.NewTicker(1 * time.Hour)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-deactivationCtx.Done():
+					log.Println("Deleted community deactivation job stopped")
+					return
+				case <-ticker.C:
+					deactivated, deactivateErr := deactivator.DeactivateDeletedCommunities(deactivationCtx, 50)
+					if deactivateErr != nil {
+						log.Printf("Error deactivating deleted communities: %v", deactivateErr)
+					}
+					if deactivated > 0 {
+						log.Printf("Deleted community deactivation: deactivated %d PDS accounts", deactivated)
+					}
+				}
+			}
+		}()
+
+		log.Println("Started deleted community deactivation job (runs every hour)")
+	}
 
 	// Register XRPC routes
-	routes.RegisterUserRoutes(r, userService, authMiddleware, oauthClient.ClientApp)
+	routes.RegisterUserRoutes(reg, userService, oauthClient.ClientApp)
 	log.Println("User XRPC endpoints registered")
 	log.Println("  - GET /xrpc/social.coves.actor.getprofile (public)")
 	log.Println("  - POST /xrpc/social.coves.actor.signup (public)")
 	log.Println("  - POST /xrpc/social.coves.actor.deleteAccount (requires OAuth)")
 	log.Println("  - POST /xrpc/social.coves.actor.updateProfile (requires OAuth)")
 
-	routes.RegisterCommunityRoutes(r, communityService, communityRepo, authMiddleware, allowedCommunityCreators)
+	routes.RegisterPreferencesRoutes(reg, userPreferencesRepo, oauthClient.ClientApp, nil)
+	log.Println("  - GET /xrpc/social.coves.actor.getPreferences (requires OAuth)")
+	log.Println("  - POST /xrpc/social.coves.actor.putPreferences (requires OAuth)")
+
+	// NSFW communities withhold their posts until the viewer confirms they're over 18
+	ageGate := communities.NewAgeGate(communityService, postgresRepo.NewAgeConfirmationRepository(db))
+
+	routes.RegisterCommunityRoutes(reg, communityService, communityRepo, allowedCommunityCreators, ageGate)
 	log.Println("Community XRPC endpoints registered with OAuth authentication")
+	log.Println("  - POST /xrpc/social.coves.community.confirmAge (requires OAuth)")
+	log.Println("  - POST /xrpc/social.coves.community.delete (owner or admin)")
+	log.Println("  - POST /xrpc/social.coves.community.undelete (owner or admin, within grace period)")
 
-	routes.RegisterPostRoutes(r, postService, dualAuth)
+	routes.RegisterPostRoutes(reg, postService)
 	log.Println("Post XRPC endpoints registered with dual auth (OAuth + service JWT for aggregators)")
 
-	routes.RegisterVoteRoutes(r, voteService, authMiddleware)
+	routes.RegisterGetPostRoutes(reg, postService, commentService, voteService, blueskyService, communityRepo, aggregatorRepo, ageGate)
+	log.Println("  - GET /xrpc/social.coves.feed.getPost (optional auth)")
+
+	routes.RegisterVoteRoutes(reg, voteService)
 	log.Println("Vote XRPC endpoints registered with OAuth authentication")
 
-	// Register comment write routes (create, update, delete)
-	routes.RegisterCommentRoutes(r, commentService, authMiddleware)
-	log.Println("Comment write XRPC endpoints registered")
+	// Register comment routes (getComments, create, update, delete, search)
+	routes.RegisterCommentRoutes(reg, commentService, ageGate)
+	log.Println("Comment XRPC endpoints registered")
+	log.Println("  - GET /xrpc/social.coves.community.comment.getComments (20 req/min rate limit)")
 	log.Println("  - POST /xrpc/social.coves.community.comment.create")
 	log.Println("  - POST /xrpc/social.coves.community.comment.update")
 	log.Println("  - POST /xrpc/social.coves.community.comment.delete")
+	log.Println("  - GET /xrpc/social.coves.community.comment.search (moderators only)")
 
-	routes.RegisterCommunityFeedRoutes(r, feedService, voteService, blueskyService, authMiddleware)
+	routes.RegisterCommunityFeedRoutes(reg, feedService, voteService, blueskyService, communityRepo, aggregatorRepo, ageGate)
 	log.Println("Feed XRPC endpoints registered (public with optional auth for viewer vote state)")
 
-	routes.RegisterTimelineRoutes(r, timelineService, voteService, blueskyService, authMiddleware)
+	routes.RegisterTimelineRoutes(reg, timelineService, voteService, blueskyService, communityRepo, aggregatorRepo, userPreferencesRepo)
 	log.Println("Timeline XRPC endpoints registered (requires authentication, includes viewer vote state)")
 
-	routes.RegisterDiscoverRoutes(r, discoverService, voteService, blueskyService, authMiddleware)
+	routes.RegisterDiscoverRoutes(reg, discoverService, voteService, blueskyService, communityRepo, aggregatorRepo, userPreferencesRepo, postgresRepo.NewCommunityCategoryRepository(db))
 	log.Println("Discover XRPC endpoints registered (public with optional auth for viewer vote state)")
-
-	routes.RegisterActorRoutes(r, postService, userService, voteService, blueskyService, commentService, authMiddleware)
+	log.Println("  - GET /xrpc/social.coves.discover.getDiscover")
+	log.Println("  - GET /xrpc/social.coves.discover.getFrontPage (cached 60s for anonymous visitors)")
+	log.Println("  - GET /xrpc/social.coves.discover.getCategories (counts cached 5m)")
+	routes.RegisterSuggestionRoutes(reg, suggestionService)
+	log.Println("  - GET /xrpc/social.coves.discover.getSuggestedCommunities (requires authentication)")
+
+	feedsBaseURL := os.Getenv("APPVIEW_PUBLIC_URL")
+	if feedsBaseURL == "" {
+		feedsBaseURL = "http://localhost:8080"
+	}
+	routes.RegisterFeedRoutes(reg, feedshandlers.NewHandler(communityService, feedService, discoverService, feedsBaseURL))
+	routes.RegisterTranslationRoutes(reg, translationService)
+	log.Println("Translation endpoint registered: GET /xrpc/social.coves.feed.translate (requires authentication)")
+	routes.RegisterDirectoryRoutes(reg, directoryService)
+	log.Println("Community directory registered: GET /xrpc/social.coves.sync.listCommunities")
+
+	// Public instance statistics and NodeInfo, computed at most every 5 minutes
+	instanceStatsService := instance.NewStatsService(postgresRepo.NewInstanceStatsRepository(db), instance.Config{
+		InstanceDID: instanceDID,
+		Peers:       len(directoryPeers),
+	})
+	routes.RegisterInstanceRoutes(reg, instanceStatsService, feedsBaseURL, instanceDomain)
+	log.Printf("Instance stats registered (version %s)", instance.SoftwareVersion())
+	log.Println("  - GET /xrpc/social.coves.instance.getStats")
+	log.Println("  - GET /.well-known/nodeinfo, GET /nodeinfo/2.1")
+	log.Println("RSS/Atom feeds registered (cached 5m, 60 req/min rate limit)")
+	log.Println("  - GET /feeds/community/{handle}.xml")
+	log.Println("  - GET /feeds/discover.xml")
+
+	routes.RegisterActorRoutes(reg, postService, userService, voteService, blueskyService, commentService, communityService, communityRepo, aggregatorRepo, ageGate)
+	routes.RegisterActorActivityRoutes(reg, users.NewActivityService(userActivityRepo), userService)
 	log.Println("Actor XRPC endpoints registered (public with optional auth for viewer vote state)")
 	log.Println("  - GET /xrpc/social.coves.actor.getPosts")
 	log.Println("  - GET /xrpc/social.coves.actor.getComments")
+	log.Println("  - GET /xrpc/social.coves.actor.getSubscriptions (requires auth)")
+	log.Println("  - GET /xrpc/social.coves.actor.getActivity (cached 1h per actor)")
+
+	// Notifications carry their subject's web path, from the same resolver clients can call directly
+	permalinkService := permalinks.NewPermalinkService(postgresRepo.NewPermalinkRepository(db))
+	routes.RegisterNotificationRoutes(reg, notifications.NewNotificationService(postgresRepo.NewNotificationRepository(db), permalinkService))
+	routes.RegisterThreadMuteRoutes(reg, notifications.NewThreadMuteService(threadMuteRepo))
+	routes.RegisterDraftRoutes(reg, draftService)
+	log.Println("Notification XRPC endpoints registered (requires authentication)")
+	log.Println("  - GET /xrpc/social.coves.notification.listNotifications")
+	log.Println("  - POST /xrpc/social.coves.notification.updateSeen")
+	log.Println("  - POST /xrpc/social.coves.actor.muteThread")
+	log.Println("  - POST /xrpc/social.coves.actor.unmuteThread")
+	log.Println("  - POST /xrpc/social.coves.actor.putDraft (rate limited)")
+	log.Println("  - GET /xrpc/social.coves.actor.getDraft")
+
+	routes.RegisterResolveRoutes(reg, permalinkService)
+	log.Println("AT-URI resolver registered: GET /xrpc/social.coves.resolve.uri")
+
+	liveHandler := liveAPI.NewSubscribeHandler(liveHub, communityService, postService, liveAPI.SubscribeConfig{})
+	liveHandler.SetAgeGate(ageGate)
+	routes.RegisterLiveRoutes(reg, liveHandler)
+	log.Println("Live update stream registered: GET /xrpc/social.coves.sync.subscribe (SSE)")
+
+	// Content visibility (removed / author_only) for posts and comments
+	moderationRepo := postgresRepo.NewModerationRepository(db)
+	moderationService := moderation.NewModerationService(moderationRepo)
+	if svc, ok := moderationService.(interface{ SetBrigadeQueue(brigade.QueueRepository) }); ok {
+		svc.SetBrigadeQueue(postgresRepo.NewBrigadeQueueRepository(db))
+	}
+	// Admin record takedowns; posts in communities we provisioned are deleted from the PDS too
+	if svc, ok := moderationService.(interface {
+		SetTakedowns(takedown.Repository, takedown.UpstreamDeleter)
+	}); ok {
+		svc.SetTakedowns(takedownRepo, takedown.NewCommunityRecordDeleter(communityService))
+	}
+	// Moderators edit their community's automod rules and review what it held or flagged
+	if svc, ok := moderationService.(interface {
+		SetAutomod(automod.Service, automod.QueueRepository)
+	}); ok {
+		svc.SetAutomod(automodService, postgresRepo.NewAutomodQueueRepository(db))
+	}
+	// Takedowns and removals block blobs left without visible content referencing them
+	if svc, ok := moderationService.(interface{ SetBlobReferences(blobrefs.Repository) }); ok {
+		svc.SetBlobReferences(blobReferenceRepo)
+	}
 
-	routes.RegisterAggregatorRoutes(r, aggregatorService, communityService, userService, identityResolver)
+	// Moderators schedule posts; the publisher writes due ones to the community's repo with its
+	// stored credentials, so they're indexed from the firehose like any other post
+	scheduledPostRepo := postgresRepo.NewScheduledPostRepository(db)
+	scheduledPostService := scheduledposts.NewService(scheduledPostRepo, communityService, moderationRepo)
+	if svc, ok := scheduledPostService.(interface{ SetAuditLog(audit.Recorder) }); ok {
+		svc.SetAuditLog(auditService)
+	}
+	routes.RegisterScheduledPostRoutes(reg, scheduledPostService)
+	log.Println("Scheduled post endpoints registered (community moderators only)")
+	log.Println("  - POST /xrpc/social.coves.community.schedulePost")
+	log.Println("  - GET /xrpc/social.coves.community.listScheduledPosts")
+	log.Println("  - POST /xrpc/social.coves.community.cancelScheduledPost")
+	scheduledPostPublisher := scheduledposts.NewPublisher(scheduledPostRepo, communityService, moderationRepo, scheduledposts.Config{})
+	scheduledPostCtx, scheduledPostCancel := context.WithCancel(context.Background())
+	go scheduledPostPublisher.Start(scheduledPostCtx)
+	log.Printf("Started scheduled post publisher (runs every %s)", scheduledPostPublisher.Config().Interval)
+
+	// Indexing metrics come from the post, comment and user consumers and the replay guard
+	indexingMetrics := struct {
+		*jetstream.PostEventConsumer
+		*jetstream.CommentEventConsumer
+		*jetstream.UserEventConsumer
+		*jetstream.ReplayGuard
+	}{postEventConsumer, commentEventConsumer, userConsumer, replayGuard}
+	// Who voted on a post or comment is limited to its author, community moderators and admins
+	voterPolicy := votes.NewAccessPolicy(voteRepo, moderationRepo, instanceAdmins)
+	// The collection -> consumer mapping reported by social.coves.admin.getIndexingStatus
+	indexingStatus := adminAPI.IndexingStatus{SharedConnection: !perConsumerJetstream, Comments: commentEventConsumer}
+	for _, consumer := range jetstream.ConsumerRegistry {
+		indexingStatus.Consumers = append(indexingStatus.Consumers, adminAPI.IndexingConsumer{
+			Name:        consumer.Name,
+			Collections: consumer.Collections,
+			EventKinds:  consumer.EventKinds,
+		})
+	}
+	routes.RegisterAdminRoutes(reg, federationService, voteRepo, voterPolicy, discoverService, indexingMetrics, indexingStatus, creationPolicy, moderationService, postgresRepo.NewImpersonationRepository(db), postgresRepo.NewSpamQueueRepository(db), provisionings, auditService, postgresRepo.NewConfusablesRepository(db), consumerGapsRepo, instanceAdmins)
+	log.Println("Admin XRPC endpoints registered (requires auth + INSTANCE_ADMINS)")
+	log.Println("  - GET /xrpc/social.coves.admin.listFederationRules")
+	log.Println("  - POST /xrpc/social.coves.admin.createFederationRule")
+	log.Println("  - POST /xrpc/social.coves.admin.deleteFederationRule")
+	log.Println("  - POST /xrpc/social.coves.admin.nullifyVotes")
+	log.Println("  - POST /xrpc/social.coves.admin.restoreVotes")
+	log.Println("  - GET /xrpc/social.coves.admin.getMetrics")
+	log.Println("  - GET /xrpc/social.coves.admin.getIndexingStatus")
+	log.Println("  - GET /xrpc/social.coves.admin.listFeaturedCommunities")
+	log.Println("  - POST /xrpc/social.coves.admin.addFeaturedCommunity")
+	log.Println("  - POST /xrpc/social.coves.admin.removeFeaturedCommunity")
+	log.Println("  - POST /xrpc/social.coves.admin.reorderFeaturedCommunities")
+	log.Println("  - GET /xrpc/social.coves.admin.listReservedCommunityNames")
+	log.Println("  - POST /xrpc/social.coves.admin.addReservedCommunityName")
+	log.Println("  - POST /xrpc/social.coves.admin.removeReservedCommunityName")
+	log.Println("  - POST /xrpc/social.coves.admin.setContentVisibility")
+	log.Println("  - POST /xrpc/social.coves.admin.setThreadLock")
+	log.Println("  - POST /xrpc/social.coves.admin.setPostLabel")
+	log.Println("  - GET /xrpc/social.coves.moderation.getVoters (subject author and community moderators too)")
+	log.Println("  - GET /xrpc/social.coves.moderation.listBrigadeAlerts (community moderators too)")
+	log.Println("  - POST /xrpc/social.coves.moderation.reviewBrigadeAlert (community moderators too)")
+	log.Println("  - GET /xrpc/social.coves.admin.listStuckProvisionings")
+	log.Println("  - POST /xrpc/social.coves.admin.cleanupProvisioning")
+	log.Println("  - GET /xrpc/social.coves.admin.getAuditLog")
+
+	routes.RegisterAggregatorRoutes(reg, aggregatorService, communityService, userService, identityResolver)
 	log.Println("Aggregator XRPC endpoints registered (query endpoints public, registration endpoint public)")
 
-	routes.RegisterAggregatorAPIKeyRoutes(r, authMiddleware, apiKeyService, aggregatorService)
+	routes.RegisterAggregatorAPIKeyRoutes(reg, apiKeyService, aggregatorService)
 	log.Println("✅ Aggregator API key endpoints registered")
 	log.Println("  - POST /xrpc/social.coves.aggregator.createApiKey (requires OAuth)")
 	log.Println("  - GET /xrpc/social.coves.aggregator.getApiKey (requires OAuth)")
 	log.Println("  - POST /xrpc/social.coves.aggregator.revokeApiKey (requires OAuth)")
 	log.Println("  - GET /xrpc/social.coves.aggregator.getMetrics (public)")
 
-	// Comment query API - supports optional authentication for viewer state
-	// Stricter rate limiting for expensive nested comment queries
-	commentRateLimiter := middleware.NewRateLimiter(20, 1*time.Minute)
-	commentServiceAdapter := commentsAPI.NewServiceAdapter(commentService)
-	commentHandler := commentsAPI.NewGetCommentsHandler(commentServiceAdapter)
-	r.Handle(
-		"/xrpc/social.coves.community.comment.getComments",
-		commentRateLimiter.Middleware(
-			commentsAPI.OptionalAuthMiddleware(authMiddleware, commentHandler.HandleGetComments),
-		),
-	)
-	log.Println("✅ Comment query API registered (20 req/min rate limit)")
-	log.Println("  - GET /xrpc/social.coves.community.comment.getComments")
-
-	// Configure allowed CORS origins for OAuth callback
-	// SECURITY: Never use wildcard "*" with credentials - only allow specific origins
-	var oauthAllowedOrigins []string
-	appviewPublicURL := os.Getenv("APPVIEW_PUBLIC_URL")
-	if appviewPublicURL == "" {
-		appviewPublicURL = "http://localhost:8080"
-	}
-	oauthAllowedOrigins = append(oauthAllowedOrigins, appviewPublicURL)
-
-	// In dev mode, also allow common localhost origins for testing
-	if oauthConfig.DevMode {
-		oauthAllowedOrigins = append(oauthAllowedOrigins,
-			"http://localhost:3000",
-			"http://localhost:3001",
-			"http://localhost:5173",
-			"http://127.0.0.1:8080",
-			"http://127.0.0.1:3000",
-			"http://127.0.0.1:3001",
-			"http://127.0.0.1:5173",
-		)
-		log.Printf("🧪 DEV MODE: OAuth CORS allows localhost origins for testing")
-	}
-	log.Printf("OAuth CORS allowed origins: %v", oauthAllowedOrigins)
-
 	// Register OAuth routes for authentication flow
-	routes.RegisterOAuthRoutes(r, oauthHandler, oauthAllowedOrigins)
+	routes.RegisterOAuthRoutes(reg, oauthHandler)
 	log.Println("✅ OAuth endpoints registered")
 	log.Println("  - GET /oauth/client-metadata.json")
 	log.Println("  - GET /oauth/jwks.json")
@@ -825,13 +1788,18 @@ func main() {
 	log.Println("  - POST /oauth/refresh")
 
 	// Register well-known routes for mobile app deep linking
-	routes.RegisterWellKnownRoutes(r)
+	routes.RegisterWellKnownRoutes(reg)
 	log.Println("✅ Well-known endpoints registered (mobile Universal Links & App Links)")
 	log.Println("  - GET /.well-known/apple-app-site-association (iOS Universal Links)")
 	log.Println("  - GET /.well-known/assetlinks.json (Android App Links)")
 
+	if communityDIDWebDomain != "" {
+		routes.RegisterDIDWebRoutes(reg, didWebRepo)
+		log.Println("  - GET /.well-known/did.json (did:web community DID documents)")
+	}
+
 	// Register web frontend routes (landing page, account deletion)
-	routes.RegisterWebRoutes(r, oauthClient, userService)
+	routes.RegisterWebRoutes(reg, oauthClient, userService)
 	log.Println("✅ Web frontend routes registered")
 	log.Println("  - GET / (landing page)")
 	log.Println("  - GET /delete-account (account deletion page)")
@@ -840,14 +1808,7 @@ func main() {
 	log.Println("  - GET /static/* (static assets)")
 
 	// Health check endpoints
-	healthHandler := func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte("OK")); err != nil {
-			log.Printf("Failed to write health check response: %v", err)
-		}
-	}
-	r.Get("/health", healthHandler)
-	r.Get("/xrpc/_health", healthHandler)
+	routes.RegisterHealthRoutes(reg, migrationRunner)
 
 	// Check PORT first (docker-compose), then APPVIEW_PORT (legacy)
 	port := os.Getenv("PORT")
@@ -889,6 +1850,23 @@ func main() {
 	cleanupCancel()
 	tokenRefreshCancel()
 	imageProxyCacheCleanupCancel()
+	orphanRetryCancel()
+	draftCleanupCancel()
+	tombstoneCleanupCancel()
+	hotRankCancel()
+	retentionCancel()
+	deactivationCancel()
+	activityRollupCancel()
+	brigadeCancel()
+	scheduledPostCancel()
+	directorySyncCancel()
+	pendingReapCancel()
+	reverifyCancel()
+	subscriberCountCancel()
+	profileBackfillCancel()
+	if err := subscriberCounts.Flush(ctx); err != nil {
+		log.Printf("Failed to flush pending subscriber counts: %v", err)
+	}
 
 	if err := server.Shutdown(ctx); err != nil {
 		log.Fatalf("Server shutdown error: %v", err)
@@ -896,6 +1874,42 @@ func main() {
 	log.Println("Server stopped gracefully")
 }
 
+// corsAllowedOrigins returns the origins browser clients may call /xrpc and /oauth from
+// The AppView's own origin is always allowed, plus CORS_ALLOWED_ORIGINS (comma-separated).
+// Dev mode adds the usual local dev server origins and honors "*"; in production "*" is
+// ignored, since it would let any site call the API with the user's browser.
+func corsAllowedOrigins(devMode bool) []string {
+	appviewPublicURL := os.Getenv("APPVIEW_PUBLIC_URL")
+	if appviewPublicURL == "" {
+		appviewPublicURL = "http://localhost:8080"
+	}
+	origins := []string{appviewPublicURL}
+
+	for _, origin := range strings.Split(os.Getenv("CORS_ALLOWED_ORIGINS"), ",") {
+		origin = strings.TrimSpace(origin)
+		switch {
+		case origin == "":
+		case origin == middleware.WildcardOrigin && !devMode:
+			log.Printf("Warning: ignoring CORS_ALLOWED_ORIGINS=* outside dev mode; list origins explicitly")
+		default:
+			origins = append(origins, origin)
+		}
+	}
+
+	if devMode {
+		origins = append(origins,
+			"http://localhost:3000",
+			"http://localhost:3001",
+			"http://localhost:5173",
+			"http://127.0.0.1:8080",
+			"http://127.0.0.1:3000",
+			"http://127.0.0.1:3001",
+			"http://127.0.0.1:5173",
+		)
+	}
+	return origins
+}
+
 // authenticateWithPDS creates a session on the PDS and returns an access token
 func authenticateWithPDS(pdsURL, handle, password string) (string, error) {
 	type CreateSessionRequest struct {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"Coves/internal/atproto/jetstream"
+	covesLexicon "Coves/internal/atproto/lexicon"
 	"bytes"
 	"encoding/json"
 	"flag"
@@ -54,6 +56,11 @@ func main() {
 		log.Fatalf("Cross-reference validation failed: %v", err)
 	}
 
+	// Check the Jetstream consumers subscribe to record collections defined here
+	if err := validateConsumerCollections(*schemaPath); err != nil {
+		log.Fatalf("Consumer collection validation failed: %v", err)
+	}
+
 	// Validate test data unless schemas-only flag is set
 	if !*schemasOnly {
 		fmt.Printf("\n📋 Validating test data from: %s\n", *testDataPath)
@@ -68,6 +75,21 @@ func main() {
 	fmt.Println("\n✅ All validations passed successfully!")
 }
 
+// validateConsumerCollections runs the server's startup collection self-check against the
+// lexicons on disk, so a renamed or typo'd collection fails here before it's deployed
+func validateConsumerCollections(schemaPath string) error {
+	schemas, err := covesLexicon.Parse(os.DirFS(schemaPath))
+	if err != nil {
+		return err
+	}
+	if err := jetstream.CheckCollections(jetstream.ConsumerRegistry, schemas); err != nil {
+		return err
+	}
+	fmt.Printf("✅ Jetstream consumer collections match the lexicons (%d collections)\n",
+		len(jetstream.CollectionConsumers(jetstream.ConsumerRegistry)))
+	return nil
+}
+
 // validateSchemaStructure performs additional validation checks
 func validateSchemaStructure(catalog *lexicon.BaseCatalog, schemaPath string, verbose bool) error {
 	var validationErrors []string
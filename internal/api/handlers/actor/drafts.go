@@ -0,0 +1,101 @@
+package actor
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/comments"
+)
+
+// DraftHandler saves and restores the authenticated user's comment drafts
+type DraftHandler struct {
+	service comments.DraftService
+}
+
+// NewDraftHandler creates a new draft handler
+func NewDraftHandler(service comments.DraftService) *DraftHandler {
+	return &DraftHandler{service: service}
+}
+
+// draftResponse is the putDraft and getDraft output
+type draftResponse struct {
+	Draft *comments.Draft `json:"draft"`
+}
+
+// HandlePutDraft saves the caller's draft reply to a post or comment
+// POST /xrpc/social.coves.actor.putDraft
+// Body: { "context": "at://did:plc:community/social.coves.community.post/3k...", "content": "..." }
+func (h *DraftHandler) HandlePutDraft(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	var req comments.PutDraftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.UserDID = userDID
+
+	draft, err := h.service.PutDraft(r.Context(), req)
+	if err != nil {
+		writeDraftError(w, err)
+		return
+	}
+	writeDraft(w, draft)
+}
+
+// HandleGetDraft returns the caller's draft reply to a post or comment
+// GET /xrpc/social.coves.actor.getDraft?context=at://...
+func (h *DraftHandler) HandleGetDraft(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	contextURI := r.URL.Query().Get("context")
+	if contextURI == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "context is required")
+		return
+	}
+
+	draft, err := h.service.GetDraft(r.Context(), comments.GetDraftRequest{UserDID: userDID, Context: contextURI})
+	if err != nil {
+		writeDraftError(w, err)
+		return
+	}
+	writeDraft(w, draft)
+}
+
+// writeDraft writes a draft response
+func writeDraft(w http.ResponseWriter, draft *comments.Draft) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(draftResponse{Draft: draft}); err != nil {
+		log.Printf("ERROR: Failed to encode draft response: %v", err)
+	}
+}
+
+// writeDraftError maps draft service errors to XRPC errors
+func writeDraftError(w http.ResponseWriter, err error) {
+	if common.WriteSentinelError(w, err) || common.WriteErrorKind(w, err) {
+		return
+	}
+	log.Printf("ERROR: Draft service error: %v", err)
+	xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
+}
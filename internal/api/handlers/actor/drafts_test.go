@@ -0,0 +1,99 @@
+package actor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/comments"
+)
+
+// mockDraftService keeps one draft and reports the rest as missing
+type mockDraftService struct {
+	draft *comments.Draft
+}
+
+func (m *mockDraftService) PutDraft(ctx context.Context, req comments.PutDraftRequest) (*comments.Draft, error) {
+	if !strings.HasPrefix(req.Context, "at://did:") {
+		return nil, comments.ErrInvalidDraftContext
+	}
+	m.draft = &comments.Draft{UserDID: req.UserDID, Context: req.Context, Content: req.Content, UpdatedAt: time.Now()}
+	return m.draft, nil
+}
+
+func (m *mockDraftService) GetDraft(ctx context.Context, req comments.GetDraftRequest) (*comments.Draft, error) {
+	if m.draft == nil || m.draft.UserDID != req.UserDID || m.draft.Context != req.Context {
+		return nil, comments.ErrDraftNotFound
+	}
+	return m.draft, nil
+}
+
+func (m *mockDraftService) PruneExpiredDrafts(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestDraftHandler(t *testing.T) {
+	const postURI = "at://did:plc:community/social.coves.community.post/3kroot"
+	service := &mockDraftService{}
+	handler := NewDraftHandler(service)
+
+	serve := func(h http.HandlerFunc, method, target, body, userDID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if userDID != "" {
+			req = req.WithContext(middleware.SetTestUserDID(req.Context(), userDID))
+		}
+		w := httptest.NewRecorder()
+		h(w, req)
+		return w
+	}
+
+	w := serve(handler.HandlePutDraft, http.MethodPost, "/xrpc/social.coves.actor.putDraft", `{"context":"`+postURI+`","content":"Half a thought"}`, "did:plc:me")
+	if w.Code != http.StatusOK {
+		t.Fatalf("putDraft: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if service.draft.UserDID != "did:plc:me" {
+		t.Errorf("Expected the draft to belong to the caller, got %q", service.draft.UserDID)
+	}
+
+	w = serve(handler.HandleGetDraft, http.MethodGet, "/xrpc/social.coves.actor.getDraft?context="+postURI, "", "did:plc:me")
+	if w.Code != http.StatusOK {
+		t.Fatalf("getDraft: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp draftResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Draft == nil || resp.Draft.Content != "Half a thought" || resp.Draft.Context != postURI {
+		t.Errorf("Unexpected draft: %+v", resp.Draft)
+	}
+
+	tests := []struct {
+		name       string
+		handler    http.HandlerFunc
+		method     string
+		target     string
+		body       string
+		userDID    string
+		wantStatus int
+	}{
+		{"put unauthenticated", handler.HandlePutDraft, http.MethodPost, "/", `{"context":"` + postURI + `"}`, "", http.StatusUnauthorized},
+		{"put malformed body", handler.HandlePutDraft, http.MethodPost, "/", `{"context":`, "did:plc:me", http.StatusBadRequest},
+		{"put invalid context", handler.HandlePutDraft, http.MethodPost, "/", `{"context":"https://example.com"}`, "did:plc:me", http.StatusBadRequest},
+		{"put wrong method", handler.HandlePutDraft, http.MethodGet, "/", "", "did:plc:me", http.StatusMethodNotAllowed},
+		{"get unauthenticated", handler.HandleGetDraft, http.MethodGet, "/?context=" + postURI, "", "", http.StatusUnauthorized},
+		{"get missing context", handler.HandleGetDraft, http.MethodGet, "/", "", "did:plc:me", http.StatusBadRequest},
+		{"get someone else's draft", handler.HandleGetDraft, http.MethodGet, "/?context=" + postURI, "", "did:plc:other", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := serve(tt.handler, tt.method, tt.target, tt.body, tt.userDID); w.Code != tt.wantStatus {
+				t.Errorf("Expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -1,70 +1,52 @@
 package actor
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"net/http"
 
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/posts"
 )
 
-// ErrorResponse represents an XRPC error response
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-}
-
-// writeError writes a JSON error response
-func writeError(w http.ResponseWriter, statusCode int, errorType, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(ErrorResponse{
-		Error:   errorType,
-		Message: message,
-	}); err != nil {
-		// Log encoding errors but can't send error response (headers already sent)
-		log.Printf("ERROR: Failed to encode error response: %v", err)
-	}
-}
-
 // handleServiceError maps service errors to HTTP responses
 func handleServiceError(w http.ResponseWriter, err error) {
 	// Check for handler-level errors first
 	var actorNotFound *actorNotFoundError
 	if errors.As(err, &actorNotFound) {
-		writeError(w, http.StatusNotFound, "ActorNotFound", "Actor not found")
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.ActorNotFound, "Actor not found")
 		return
 	}
 
-	// Check for service-level errors
-	switch {
-	case errors.Is(err, posts.ErrNotFound):
-		writeError(w, http.StatusNotFound, "ActorNotFound", "Actor not found")
-
-	case errors.Is(err, posts.ErrActorNotFound):
-		writeError(w, http.StatusNotFound, "ActorNotFound", "Actor not found")
-
-	case errors.Is(err, posts.ErrCommunityNotFound):
-		writeError(w, http.StatusNotFound, "CommunityNotFound", "Community not found")
+	// Actor endpoints report a missing record as a missing actor, not a missing post
+	if errors.Is(err, posts.ErrNotFound) {
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.ActorNotFound, "Actor not found")
+		return
+	}
 
-	case errors.Is(err, posts.ErrInvalidCursor):
-		writeError(w, http.StatusBadRequest, "InvalidCursor", "Invalid pagination cursor")
+	if common.WriteSentinelError(w, err) {
+		return
+	}
 
+	switch {
 	case posts.IsValidationError(err):
 		// Extract message from ValidationError for cleaner response
 		var valErr *posts.ValidationError
 		if errors.As(err, &valErr) {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", valErr.Message)
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, valErr.Message)
 		} else {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 		}
 
 	default:
+		if common.WriteErrorKind(w, err) {
+			return
+		}
 		// Internal server error - don't leak details
 		log.Printf("ERROR: Actor posts service error: %v", err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError", "An internal error occurred")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
 	}
 }
 
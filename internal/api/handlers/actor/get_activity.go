@@ -0,0 +1,120 @@
+package actor
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"Coves/internal/api/xrpcerror"
+	coreerrors "Coves/internal/core/errors"
+	"Coves/internal/core/users"
+)
+
+// GetActivityHandler serves the profile activity heatmap
+type GetActivityHandler struct {
+	activityService users.ActivityService
+	userService     users.UserService
+}
+
+// NewGetActivityHandler creates a new actor activity handler
+func NewGetActivityHandler(activityService users.ActivityService, userService users.UserService) *GetActivityHandler {
+	return &GetActivityHandler{
+		activityService: activityService,
+		userService:     userService,
+	}
+}
+
+// HandleGetActivity returns an actor's daily post and comment counts
+// GET /xrpc/social.coves.actor.getActivity?actor={did_or_handle}&days=365
+func (h *GetActivityHandler) HandleGetActivity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	actorDID, days, err := h.parseRequest(r)
+	if err != nil {
+		var actorNotFound *actorNotFoundError
+		if errors.As(err, &actorNotFound) {
+			xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.ActorNotFound, "Actor not found")
+			return
+		}
+		var resolutionFailed *resolutionFailedError
+		if errors.As(err, &resolutionFailed) {
+			log.Printf("ERROR: Actor resolution infrastructure failure: %v", err)
+			xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to resolve actor identity")
+			return
+		}
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
+		return
+	}
+
+	response, err := h.activityService.GetActivity(r.Context(), actorDID, days)
+	if err != nil {
+		log.Printf("ERROR: Actor activity service error: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
+		return
+	}
+
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("ERROR: Failed to encode actor activity response: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to encode response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(responseBytes); err != nil {
+		log.Printf("ERROR: Failed to write actor activity response: %v", err)
+	}
+}
+
+// parseRequest reads the actor (resolved to a DID) and the number of days
+func (h *GetActivityHandler) parseRequest(r *http.Request) (string, int, error) {
+	actor := r.URL.Query().Get("actor")
+	if actor == "" {
+		return "", 0, &validationError{field: "actor", message: "actor parameter is required"}
+	}
+	const maxActorLength = 2048
+	if len(actor) > maxActorLength {
+		return "", 0, &validationError{field: "actor", message: "actor parameter exceeds maximum length"}
+	}
+
+	days := users.DefaultActivityDays
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		parsed, err := strconv.Atoi(daysStr)
+		if err != nil || parsed < 1 || parsed > users.MaxActivityDays {
+			return "", 0, &validationError{field: "days", message: "days must be an integer between 1 and 366"}
+		}
+		days = parsed
+	}
+
+	actorDID, err := h.resolveActor(r, actor)
+	if err != nil {
+		return "", 0, err
+	}
+	return actorDID, days, nil
+}
+
+// resolveActor converts an actor identifier (handle or DID) to a DID
+func (h *GetActivityHandler) resolveActor(r *http.Request, actor string) (string, error) {
+	if strings.HasPrefix(actor, "did:") {
+		return actor, nil
+	}
+
+	did, err := h.userService.ResolveHandleToDID(r.Context(), actor)
+	if err != nil {
+		if r.Context().Err() != nil {
+			return "", &resolutionFailedError{actor: actor, cause: r.Context().Err()}
+		}
+		if coreerrors.IsNotFound(err) {
+			return "", &actorNotFoundError{actor: actor}
+		}
+		return "", &resolutionFailedError{actor: actor, cause: err}
+	}
+	return did, nil
+}
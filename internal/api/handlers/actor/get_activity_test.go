@@ -0,0 +1,68 @@
+package actor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Coves/internal/core/users"
+)
+
+// mockActivityService records the window it was asked for
+type mockActivityService struct {
+	did  string
+	days int
+}
+
+func (m *mockActivityService) GetActivity(ctx context.Context, did string, days int) (*users.GetActivityResponse, error) {
+	m.did, m.days = did, days
+	return &users.GetActivityResponse{Days: []users.ActivityDay{{Date: "2026-03-01", Posts: 2, Comments: 1}}}, nil
+}
+
+func TestGetActivityHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantDID    string
+		wantDays   int
+	}{
+		{name: "defaults to a year", query: "actor=did:plc:alice", wantStatus: http.StatusOK, wantDID: "did:plc:alice", wantDays: 365},
+		{name: "handle is resolved", query: "actor=alice.test&days=30", wantStatus: http.StatusOK, wantDID: "did:plc:testuser", wantDays: 30},
+		{name: "leap year", query: "actor=did:plc:alice&days=366", wantStatus: http.StatusOK, wantDID: "did:plc:alice", wantDays: 366},
+		{name: "missing actor", query: "days=30", wantStatus: http.StatusBadRequest},
+		{name: "too many days", query: "actor=did:plc:alice&days=367", wantStatus: http.StatusBadRequest},
+		{name: "zero days", query: "actor=did:plc:alice&days=0", wantStatus: http.StatusBadRequest},
+		{name: "non-numeric days", query: "actor=did:plc:alice&days=year", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockActivityService{}
+			handler := NewGetActivityHandler(service, &mockUserService{})
+
+			w := httptest.NewRecorder()
+			handler.HandleGetActivity(w, httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getActivity?"+tt.query, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			if service.did != tt.wantDID || service.days != tt.wantDays {
+				t.Errorf("Expected %s over %d days, got %s over %d", tt.wantDID, tt.wantDays, service.did, service.days)
+			}
+
+			var resp users.GetActivityResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(resp.Days) != 1 || resp.Days[0].Posts != 2 {
+				t.Errorf("Unexpected response: %+v", resp)
+			}
+		})
+	}
+}
@@ -9,7 +9,9 @@ import (
 	"strings"
 
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/comments"
+	coreerrors "Coves/internal/core/errors"
 	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
 )
@@ -38,7 +40,7 @@ func NewGetCommentsHandler(
 // GET /xrpc/social.coves.actor.getComments?actor={did_or_handle}&community=...&limit=50&cursor=...
 func (h *GetCommentsHandler) HandleGetComments(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -48,7 +50,7 @@ func (h *GetCommentsHandler) HandleGetComments(w http.ResponseWriter, r *http.Re
 		// Check if it's an actor not found error (from handle resolution)
 		var actorNotFound *actorNotFoundError
 		if errors.As(err, &actorNotFound) {
-			writeError(w, http.StatusNotFound, "ActorNotFound", "Actor not found")
+			xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.ActorNotFound, "Actor not found")
 			return
 		}
 
@@ -57,11 +59,11 @@ func (h *GetCommentsHandler) HandleGetComments(w http.ResponseWriter, r *http.Re
 		var resolutionFailed *resolutionFailedError
 		if errors.As(err, &resolutionFailed) {
 			log.Printf("ERROR: Actor resolution infrastructure failure: %v", err)
-			writeError(w, http.StatusInternalServerError, "InternalServerError", "Failed to resolve actor identity")
+			xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to resolve actor identity")
 			return
 		}
 
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 		return
 	}
 
@@ -86,7 +88,7 @@ func (h *GetCommentsHandler) HandleGetComments(w http.ResponseWriter, r *http.Re
 	responseBytes, err := json.Marshal(response)
 	if err != nil {
 		log.Printf("ERROR: Failed to encode actor comments response: %v", err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError", "Failed to encode response")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to encode response")
 		return
 	}
 
@@ -158,13 +160,9 @@ func (h *GetCommentsHandler) resolveActor(r *http.Request, actor string) (string
 			return "", &resolutionFailedError{actor: actor, cause: r.Context().Err()}
 		}
 
-		// Check for common "not found" patterns in error message
-		errStr := err.Error()
-		isNotFound := strings.Contains(errStr, "not found") ||
-			strings.Contains(errStr, "no rows") ||
-			strings.Contains(errStr, "unable to resolve")
-
-		if isNotFound {
+		// Handles that don't exist resolve to a not-found error (identity.ErrNotFound or a
+		// core sentinel), distinct from infrastructure failures
+		if coreerrors.IsNotFound(err) {
 			return "", &actorNotFoundError{actor: actor}
 		}
 
@@ -229,29 +227,27 @@ func handleCommentServiceError(w http.ResponseWriter, err error) {
 		return
 	}
 
-	errStr := err.Error()
-
 	// Check for validation errors
-	if strings.Contains(errStr, "invalid request") {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", errStr)
+	if comments.IsValidationError(err) {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 		return
 	}
 
 	// Check for not found errors
-	if comments.IsNotFound(err) || strings.Contains(errStr, "not found") {
-		writeError(w, http.StatusNotFound, "NotFound", "Resource not found")
+	if coreerrors.IsNotFound(err) {
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.NotFound, "Resource not found")
 		return
 	}
 
 	// Check for authorization errors
 	if errors.Is(err, comments.ErrNotAuthorized) {
-		writeError(w, http.StatusForbidden, "NotAuthorized", "Not authorized")
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.NotAuthorized, "Not authorized")
 		return
 	}
 
 	// Default to internal server error
 	log.Printf("ERROR: Comment service error: %v", err)
-	writeError(w, http.StatusInternalServerError, "InternalServerError", "An unexpected error occurred")
+	xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An unexpected error occurred")
 }
 
 // validationError represents a validation error for a specific field
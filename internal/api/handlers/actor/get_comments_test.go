@@ -4,11 +4,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/comments"
 	"Coves/internal/core/posts"
 	"Coves/internal/core/users"
@@ -37,6 +39,10 @@ func (m *mockCommentService) GetComments(ctx context.Context, req *comments.GetC
 	return nil, nil
 }
 
+func (m *mockCommentService) GetCommentThread(ctx context.Context, req *comments.GetCommentThreadRequest) (*comments.GetCommentThreadResponse, error) {
+	return nil, nil
+}
+
 func (m *mockCommentService) CreateComment(ctx context.Context, session *oauthlib.ClientSessionData, req comments.CreateCommentRequest) (*comments.CreateCommentResponse, error) {
 	return nil, nil
 }
@@ -49,6 +55,10 @@ func (m *mockCommentService) DeleteComment(ctx context.Context, session *oauthli
 	return nil
 }
 
+func (m *mockCommentService) SearchComments(ctx context.Context, req *comments.SearchCommentsRequest) (*comments.SearchCommentsResponse, error) {
+	return nil, nil
+}
+
 // mockUserServiceForComments implements users.UserService for testing getComments
 type mockUserServiceForComments struct {
 	resolveHandleToDIDFunc func(ctx context.Context, handle string) (string, error)
@@ -211,7 +221,7 @@ func TestGetCommentsHandler_MissingActor(t *testing.T) {
 		t.Errorf("Expected status 400, got %d", rec.Code)
 	}
 
-	var response ErrorResponse
+	var response xrpcerror.Response
 	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
@@ -237,7 +247,7 @@ func TestGetCommentsHandler_InvalidLimit(t *testing.T) {
 		t.Errorf("Expected status 400, got %d", rec.Code)
 	}
 
-	var response ErrorResponse
+	var response xrpcerror.Response
 	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
@@ -269,7 +279,7 @@ func TestGetCommentsHandler_ActorNotFound(t *testing.T) {
 		t.Errorf("Expected status 404, got %d", rec.Code)
 	}
 
-	var response ErrorResponse
+	var response xrpcerror.Response
 	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
@@ -307,7 +317,7 @@ func TestGetCommentsHandler_InvalidCursor(t *testing.T) {
 	// to return a BadRequest. An invalid cursor error falls under this category.
 	mockComments := &mockCommentService{
 		getActorCommentsFunc: func(ctx context.Context, req *comments.GetActorCommentsRequest) (*comments.GetActorCommentsResponse, error) {
-			return nil, errors.New("invalid request: invalid cursor format")
+			return nil, fmt.Errorf("%w: invalid cursor format", comments.ErrInvalidRequest)
 		},
 	}
 
@@ -326,7 +336,7 @@ func TestGetCommentsHandler_InvalidCursor(t *testing.T) {
 		t.Errorf("Expected status 400, got %d", rec.Code)
 	}
 
-	var response ErrorResponse
+	var response xrpcerror.Response
 	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
@@ -587,7 +597,7 @@ func TestGetCommentsHandler_ServiceError_Returns500(t *testing.T) {
 		t.Errorf("Expected status 500, got %d", rec.Code)
 	}
 
-	var response ErrorResponse
+	var response xrpcerror.Response
 	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
@@ -628,7 +638,7 @@ func TestGetCommentsHandler_ResolutionFailedError_Returns500(t *testing.T) {
 		t.Errorf("Expected status 500 for infrastructure failure, got %d", rec.Code)
 	}
 
-	var response ErrorResponse
+	var response xrpcerror.Response
 	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
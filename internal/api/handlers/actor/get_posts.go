@@ -10,7 +10,10 @@ import (
 
 	"Coves/internal/api/handlers/common"
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/communities"
+	coreerrors "Coves/internal/core/errors"
 	"Coves/internal/core/posts"
 	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
@@ -22,6 +25,9 @@ type GetPostsHandler struct {
 	userService    users.UserService
 	voteService    votes.Service
 	blueskyService blueskypost.Service
+	moderators     common.ModeratorLookup
+	aggregators    common.AggregatorLookup
+	ageGate        communities.AgeGate
 }
 
 // NewGetPostsHandler creates a new actor posts handler
@@ -30,6 +36,8 @@ func NewGetPostsHandler(
 	userService users.UserService,
 	voteService votes.Service,
 	blueskyService blueskypost.Service,
+	moderators common.ModeratorLookup,
+	aggregators common.AggregatorLookup,
 ) *GetPostsHandler {
 	if blueskyService == nil {
 		log.Printf("[ACTOR-HANDLER] WARNING: blueskyService is nil - Bluesky post embeds will not be resolved")
@@ -39,14 +47,22 @@ func NewGetPostsHandler(
 		userService:    userService,
 		voteService:    voteService,
 		blueskyService: blueskyService,
+		moderators:     moderators,
+		aggregators:    aggregators,
 	}
 }
 
+// SetAgeGate withholds posts in age-restricted communities from viewers who haven't confirmed their age
+func (h *GetPostsHandler) SetAgeGate(gate communities.AgeGate) {
+	h.ageGate = gate
+}
+
 // HandleGetPosts retrieves posts by an actor (user)
 // GET /xrpc/social.coves.actor.getPosts?actor={did_or_handle}&filter=posts_with_replies&community=...&limit=50&cursor=...
+// Posts in age-restricted communities are left out until the viewer confirms their age.
 func (h *GetPostsHandler) HandleGetPosts(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -56,10 +72,10 @@ func (h *GetPostsHandler) HandleGetPosts(w http.ResponseWriter, r *http.Request)
 		// Check if it's an actor not found error (from handle resolution)
 		var actorNotFound *actorNotFoundError
 		if errors.As(err, &actorNotFound) {
-			writeError(w, http.StatusNotFound, "ActorNotFound", "Actor not found")
+			xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.ActorNotFound, "Actor not found")
 			return
 		}
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 		return
 	}
 
@@ -74,9 +90,25 @@ func (h *GetPostsHandler) HandleGetPosts(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Leave out posts in communities the viewer hasn't confirmed their age for
+	response.Feed, err = common.DropAgeGatedPosts(r.Context(), h.ageGate, viewerDID, response.Feed)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
 	// Populate viewer vote state if authenticated
 	common.PopulateViewerVoteState(r.Context(), r, h.voteService, response.Feed)
 
+	// Null vote counts inside community score hiding windows
+	common.HideFeedScores(r.Context(), r, h.moderators, response.Feed)
+
+	// Badge post authors who moderate their community
+	common.AddFeedAuthorBadges(r.Context(), h.moderators, response.Feed)
+
+	// Attribute aggregator-authored posts to their aggregator
+	common.PopulateAggregatorAttribution(r.Context(), h.aggregators, response.Feed)
+
 	// Transform blob refs to URLs and resolve post embeds for all posts
 	for _, feedPost := range response.Feed {
 		if feedPost.Post != nil {
@@ -90,7 +122,7 @@ func (h *GetPostsHandler) HandleGetPosts(w http.ResponseWriter, r *http.Request)
 	responseBytes, err := json.Marshal(response)
 	if err != nil {
 		log.Printf("ERROR: Failed to encode actor posts response: %v", err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError", "Failed to encode response")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to encode response")
 		return
 	}
 
@@ -165,13 +197,9 @@ func (h *GetPostsHandler) resolveActor(r *http.Request, actor string) (string, e
 			return "", &resolutionFailedError{actor: actor, cause: r.Context().Err()}
 		}
 
-		// Check for common "not found" patterns in error message
-		errStr := err.Error()
-		isNotFound := strings.Contains(errStr, "not found") ||
-			strings.Contains(errStr, "no rows") ||
-			strings.Contains(errStr, "unable to resolve")
-
-		if isNotFound {
+		// Handles that don't exist resolve to a not-found error (identity.ErrNotFound or a
+		// core sentinel), distinct from infrastructure failures
+		if coreerrors.IsNotFound(err) {
 			return "", &actorNotFoundError{actor: actor}
 		}
 
@@ -7,7 +7,10 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/communities"
 	"Coves/internal/core/posts"
 	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
@@ -38,6 +41,14 @@ func (m *mockPostService) DeletePost(ctx context.Context, session *oauthlib.Clie
 	return nil
 }
 
+func (m *mockPostService) GetPost(ctx context.Context, req posts.GetPostRequest) (*posts.PostView, error) {
+	return nil, posts.ErrNotFound
+}
+
+func (m *mockPostService) CheckDuplicate(ctx context.Context, req posts.CheckDuplicateRequest) ([]*posts.DuplicateMatch, error) {
+	return nil, nil
+}
+
 // mockUserService implements users.UserService for testing
 type mockUserService struct {
 	resolveHandleToDIDFunc func(ctx context.Context, handle string) (string, error)
@@ -143,7 +154,7 @@ func TestGetPostsHandler_Success(t *testing.T) {
 	mockVotes := &mockVoteService{}
 	mockBluesky := &mockBlueskyService{}
 
-	handler := NewGetPostsHandler(mockPosts, mockUsers, mockVotes, mockBluesky)
+	handler := NewGetPostsHandler(mockPosts, mockUsers, mockVotes, mockBluesky, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getPosts?actor=did:plc:testuser", nil)
 	rec := httptest.NewRecorder()
@@ -164,8 +175,100 @@ func TestGetPostsHandler_Success(t *testing.T) {
 	}
 }
 
+// stubCommunityService serves communities by DID to the age gate
+// Methods the gate doesn't call fall through to the nil embedded interface and panic.
+type stubCommunityService struct {
+	communities.Service
+	communities map[string]*communities.Community
+}
+
+func (s *stubCommunityService) GetCommunity(ctx context.Context, identifier string) (*communities.Community, error) {
+	community, ok := s.communities[identifier]
+	if !ok {
+		return nil, communities.ErrCommunityNotFound
+	}
+	return community, nil
+}
+
+// stubAgeConfirmations holds confirmations as "user|community" keys
+type stubAgeConfirmations map[string]bool
+
+func (s stubAgeConfirmations) ConfirmAge(ctx context.Context, userDID, communityDID string) error {
+	s[userDID+"|"+communityDID] = true
+	return nil
+}
+
+func (s stubAgeConfirmations) HasConfirmedAge(ctx context.Context, userDID, communityDID string) (bool, error) {
+	return s[userDID+"|"+communityDID], nil
+}
+
+func TestGetPostsHandler_AgeGate(t *testing.T) {
+	const (
+		nsfwPost   = "at://did:plc:testuser/social.coves.community.post/nsfw"
+		familyPost = "at://did:plc:testuser/social.coves.community.post/family"
+		orphanPost = "at://did:plc:testuser/social.coves.community.post/orphan"
+	)
+	service := &stubCommunityService{communities: map[string]*communities.Community{
+		"did:plc:adult":  {DID: "did:plc:adult", Name: "adult", ContentWarnings: []string{"nsfw"}},
+		"did:plc:family": {DID: "did:plc:family", Name: "family"},
+	}}
+	confirmations := stubAgeConfirmations{"did:plc:confirmed|did:plc:adult": true}
+
+	mockPosts := &mockPostService{
+		getAuthorPostsFunc: func(ctx context.Context, req posts.GetAuthorPostsRequest) (*posts.GetAuthorPostsResponse, error) {
+			return &posts.GetAuthorPostsResponse{
+				Feed: []*posts.FeedViewPost{
+					{Post: &posts.PostView{URI: nsfwPost, Community: &posts.CommunityRef{DID: "did:plc:adult"}}},
+					{Post: &posts.PostView{URI: familyPost, Community: &posts.CommunityRef{DID: "did:plc:family"}}},
+					{Post: &posts.PostView{URI: orphanPost, Community: &posts.CommunityRef{DID: "did:plc:deleted"}}},
+				},
+			}, nil
+		},
+	}
+
+	tests := []struct {
+		name      string
+		viewerDID string
+		wantURIs  []string
+	}{
+		{name: "anonymous viewer", wantURIs: []string{familyPost, orphanPost}},
+		{name: "unconfirmed viewer", viewerDID: "did:plc:unconfirmed", wantURIs: []string{familyPost, orphanPost}},
+		{name: "confirmed viewer", viewerDID: "did:plc:confirmed", wantURIs: []string{nsfwPost, familyPost, orphanPost}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewGetPostsHandler(mockPosts, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{}, nil, nil)
+			handler.SetAgeGate(communities.NewAgeGate(service, confirmations))
+
+			req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getPosts?actor=did:plc:testuser", nil)
+			if tt.viewerDID != "" {
+				req = req.WithContext(middleware.SetTestUserDID(req.Context(), tt.viewerDID))
+			}
+			rec := httptest.NewRecorder()
+			handler.HandleGetPosts(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+			}
+			var response posts.GetAuthorPostsResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Feed) != len(tt.wantURIs) {
+				t.Fatalf("Expected %d posts, got %d", len(tt.wantURIs), len(response.Feed))
+			}
+			for i, want := range tt.wantURIs {
+				if response.Feed[i].Post.URI != want {
+					t.Errorf("Expected post %d to be %s, got %s", i, want, response.Feed[i].Post.URI)
+				}
+			}
+		})
+	}
+}
+
 func TestGetPostsHandler_MissingActorParameter(t *testing.T) {
-	handler := NewGetPostsHandler(&mockPostService{}, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{})
+	handler := NewGetPostsHandler(&mockPostService{}, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{}, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getPosts", nil)
 	rec := httptest.NewRecorder()
@@ -176,7 +279,7 @@ func TestGetPostsHandler_MissingActorParameter(t *testing.T) {
 		t.Errorf("Expected status 400, got %d", rec.Code)
 	}
 
-	var response ErrorResponse
+	var response xrpcerror.Response
 	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
@@ -187,7 +290,7 @@ func TestGetPostsHandler_MissingActorParameter(t *testing.T) {
 }
 
 func TestGetPostsHandler_InvalidLimitParameter(t *testing.T) {
-	handler := NewGetPostsHandler(&mockPostService{}, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{})
+	handler := NewGetPostsHandler(&mockPostService{}, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{}, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getPosts?actor=did:plc:test&limit=abc", nil)
 	rec := httptest.NewRecorder()
@@ -198,7 +301,7 @@ func TestGetPostsHandler_InvalidLimitParameter(t *testing.T) {
 		t.Errorf("Expected status 400, got %d", rec.Code)
 	}
 
-	var response ErrorResponse
+	var response xrpcerror.Response
 	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
@@ -215,7 +318,7 @@ func TestGetPostsHandler_ActorNotFound(t *testing.T) {
 		},
 	}
 
-	handler := NewGetPostsHandler(&mockPostService{}, mockUsers, &mockVoteService{}, &mockBlueskyService{})
+	handler := NewGetPostsHandler(&mockPostService{}, mockUsers, &mockVoteService{}, &mockBlueskyService{}, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getPosts?actor=nonexistent.user", nil)
 	rec := httptest.NewRecorder()
@@ -228,7 +331,7 @@ func TestGetPostsHandler_ActorNotFound(t *testing.T) {
 }
 
 func TestGetPostsHandler_ActorLengthExceedsMax(t *testing.T) {
-	handler := NewGetPostsHandler(&mockPostService{}, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{})
+	handler := NewGetPostsHandler(&mockPostService{}, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{}, nil, nil)
 
 	// Create an actor parameter that exceeds 2048 characters using valid URL characters
 	longActorBytes := make([]byte, 2100)
@@ -253,7 +356,7 @@ func TestGetPostsHandler_InvalidCursor(t *testing.T) {
 		},
 	}
 
-	handler := NewGetPostsHandler(mockPosts, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{})
+	handler := NewGetPostsHandler(mockPosts, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{}, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getPosts?actor=did:plc:test&cursor=invalid", nil)
 	rec := httptest.NewRecorder()
@@ -264,7 +367,7 @@ func TestGetPostsHandler_InvalidCursor(t *testing.T) {
 		t.Errorf("Expected status 400, got %d", rec.Code)
 	}
 
-	var response ErrorResponse
+	var response xrpcerror.Response
 	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
 		t.Fatalf("Failed to decode response: %v", err)
 	}
@@ -275,7 +378,7 @@ func TestGetPostsHandler_InvalidCursor(t *testing.T) {
 }
 
 func TestGetPostsHandler_MethodNotAllowed(t *testing.T) {
-	handler := NewGetPostsHandler(&mockPostService{}, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{})
+	handler := NewGetPostsHandler(&mockPostService{}, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{}, nil, nil)
 
 	req := httptest.NewRequest(http.MethodPost, "/xrpc/social.coves.actor.getPosts", nil)
 	rec := httptest.NewRecorder()
@@ -304,7 +407,7 @@ func TestGetPostsHandler_HandleResolution(t *testing.T) {
 		},
 	}
 
-	handler := NewGetPostsHandler(mockPosts, mockUsers, &mockVoteService{}, &mockBlueskyService{})
+	handler := NewGetPostsHandler(mockPosts, mockUsers, &mockVoteService{}, &mockBlueskyService{}, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getPosts?actor=test.user", nil)
 	rec := httptest.NewRecorder()
@@ -329,7 +432,7 @@ func TestGetPostsHandler_DirectDIDPassthrough(t *testing.T) {
 		},
 	}
 
-	handler := NewGetPostsHandler(mockPosts, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{})
+	handler := NewGetPostsHandler(mockPosts, &mockUserService{}, &mockVoteService{}, &mockBlueskyService{}, nil, nil)
 
 	// When actor is already a DID, it should pass through without resolution
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getPosts?actor=did:plc:directuser", nil)
@@ -0,0 +1,124 @@
+package actor
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/communities"
+)
+
+// GetSubscriptionsHandler handles listing the authenticated user's subscribed communities
+type GetSubscriptionsHandler struct {
+	communityService communities.Service
+}
+
+// NewGetSubscriptionsHandler creates a new subscriptions handler
+func NewGetSubscriptionsHandler(communityService communities.Service) *GetSubscriptionsHandler {
+	return &GetSubscriptionsHandler{
+		communityService: communityService,
+	}
+}
+
+// GetSubscriptionsResponse is the response for social.coves.actor.getSubscriptions
+type GetSubscriptionsResponse struct {
+	Cursor        string                                 `json:"cursor,omitempty"`
+	Subscriptions []*communities.SubscribedCommunityView `json:"subscriptions"`
+}
+
+// HandleGetSubscriptions lists the viewer's subscribed communities with community data hydrated
+// GET /xrpc/social.coves.actor.getSubscriptions?sort={subscribedAt|alphabetical|recentActivity}&q={filter}&limit=50&cursor=...
+func (h *GetSubscriptionsHandler) HandleGetSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	query := r.URL.Query()
+
+	// Parse limit (1-100, default 50)
+	limit := 50
+	if limitStr := query.Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid limit parameter: must be an integer")
+			return
+		}
+		if l < 1 {
+			limit = 1
+		} else if l > 100 {
+			limit = 100
+		} else {
+			limit = l
+		}
+	}
+
+	// Parse cursor (offset-based, matching social.coves.community.list)
+	offset := 0
+	if cursorStr := query.Get("cursor"); cursorStr != "" {
+		o, err := strconv.Atoi(cursorStr)
+		if err != nil || o < 0 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidCursor, "Invalid pagination cursor")
+			return
+		}
+		offset = o
+	}
+
+	results, err := h.communityService.GetSubscribedCommunities(r.Context(), communities.GetSubscriptionsRequest{
+		UserDID: userDID,
+		Sort:    query.Get("sort"),
+		Query:   query.Get("q"),
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		handleCommunityServiceError(w, err)
+		return
+	}
+
+	response := GetSubscriptionsResponse{
+		Subscriptions: make([]*communities.SubscribedCommunityView, len(results)),
+	}
+	for i, result := range results {
+		response.Subscriptions[i] = result.ToView()
+	}
+	if len(results) == limit {
+		// More results may be available
+		response.Cursor = strconv.Itoa(offset + len(results))
+	}
+
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		log.Printf("ERROR: Failed to encode subscriptions response: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to encode response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(responseBytes); err != nil {
+		log.Printf("ERROR: Failed to write subscriptions response: %v", err)
+	}
+}
+
+// handleCommunityServiceError maps community service errors to HTTP responses
+func handleCommunityServiceError(w http.ResponseWriter, err error) {
+	var valErr *communities.ValidationError
+	if errors.As(err, &valErr) {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, valErr.Error())
+		return
+	}
+
+	log.Printf("ERROR: Actor subscriptions service error: %v", err)
+	xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
+}
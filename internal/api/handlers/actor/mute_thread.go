@@ -0,0 +1,72 @@
+package actor
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/notifications"
+)
+
+// ThreadMuteHandler mutes and unmutes threads for the authenticated user
+type ThreadMuteHandler struct {
+	service notifications.ThreadMuteService
+}
+
+// NewThreadMuteHandler creates a new thread mute handler
+func NewThreadMuteHandler(service notifications.ThreadMuteService) *ThreadMuteHandler {
+	return &ThreadMuteHandler{service: service}
+}
+
+// HandleMuteThread stops reply and mention notifications from a post's thread
+// POST /xrpc/social.coves.actor.muteThread
+// Body: { "root": "at://did:plc:community/social.coves.community.post/3k..." }
+func (h *ThreadMuteHandler) HandleMuteThread(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.service.MuteThread)
+}
+
+// HandleUnmuteThread resumes reply and mention notifications from a post's thread
+// POST /xrpc/social.coves.actor.unmuteThread
+// Body: { "root": "at://did:plc:community/social.coves.community.post/3k..." }
+func (h *ThreadMuteHandler) HandleUnmuteThread(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.service.UnmuteThread)
+}
+
+// handle decodes the request and applies the mute change for the caller
+func (h *ThreadMuteHandler) handle(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, req notifications.ThreadMuteRequest) error) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	var req notifications.ThreadMuteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.UserDID = userDID
+
+	if err := apply(r.Context(), req); err != nil {
+		if common.WriteSentinelError(w, err) || common.WriteErrorKind(w, err) {
+			return
+		}
+		log.Printf("ERROR: Thread mute service error: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("{}\n")); err != nil {
+		log.Printf("ERROR: Failed to write thread mute response: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package actor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/notifications"
+)
+
+// mockThreadMuteService records the last request and returns err
+type mockThreadMuteService struct {
+	err    error
+	last   notifications.ThreadMuteRequest
+	action string
+}
+
+func (m *mockThreadMuteService) MuteThread(ctx context.Context, req notifications.ThreadMuteRequest) error {
+	m.action, m.last = "mute", req
+	return m.err
+}
+
+func (m *mockThreadMuteService) UnmuteThread(ctx context.Context, req notifications.ThreadMuteRequest) error {
+	m.action, m.last = "unmute", req
+	return m.err
+}
+
+func TestThreadMuteHandler(t *testing.T) {
+	const root = "at://did:plc:community/social.coves.community.post/3kroot"
+
+	tests := []struct {
+		name       string
+		method     string
+		userDID    string
+		body       string
+		serviceErr error
+		unmute     bool
+		wantStatus int
+	}{
+		{name: "mute", method: http.MethodPost, userDID: "did:plc:me", body: `{"root":"` + root + `"}`, wantStatus: http.StatusOK},
+		{name: "unmute", method: http.MethodPost, userDID: "did:plc:me", body: `{"root":"` + root + `"}`, unmute: true, wantStatus: http.StatusOK},
+		{name: "unauthenticated", method: http.MethodPost, body: `{"root":"` + root + `"}`, wantStatus: http.StatusUnauthorized},
+		{name: "wrong method", method: http.MethodGet, userDID: "did:plc:me", wantStatus: http.StatusMethodNotAllowed},
+		{name: "malformed body", method: http.MethodPost, userDID: "did:plc:me", body: `{"root":`, wantStatus: http.StatusBadRequest},
+		{name: "invalid root", method: http.MethodPost, userDID: "did:plc:me", body: `{"root":"at://did:plc:alice/social.coves.community.comment/3k"}`, serviceErr: notifications.ErrInvalidThreadRoot, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockThreadMuteService{err: tt.serviceErr}
+			handler := NewThreadMuteHandler(service)
+
+			req := httptest.NewRequest(tt.method, "/xrpc/social.coves.actor.muteThread", strings.NewReader(tt.body))
+			if tt.userDID != "" {
+				req = req.WithContext(middleware.SetTestUserDID(req.Context(), tt.userDID))
+			}
+			w := httptest.NewRecorder()
+			if tt.unmute {
+				handler.HandleUnmuteThread(w, req)
+			} else {
+				handler.HandleMuteThread(w, req)
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			wantAction := "mute"
+			if tt.unmute {
+				wantAction = "unmute"
+			}
+			if service.action != wantAction || service.last.UserDID != tt.userDID || service.last.Root != root {
+				t.Errorf("Expected %s of %s for %s, got %s of %+v", wantAction, root, tt.userDID, service.action, service.last)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// AuditHandler lets instance admins read and verify the audit log
+type AuditHandler struct {
+	service audit.Service
+	admins  Admins
+}
+
+// NewAuditHandler creates a new audit log handler
+func NewAuditHandler(service audit.Service, admins Admins) *AuditHandler {
+	return &AuditHandler{
+		service: service,
+		admins:  admins,
+	}
+}
+
+// GetAuditLogResponse is the response for social.coves.admin.getAuditLog
+// Cursor is the ID to pass back for the next (older) page, omitted on the last page.
+// Verification is only present when verifyChain=true was requested.
+type GetAuditLogResponse struct {
+	Verification *audit.Verification `json:"verification,omitempty"`
+	Cursor       string              `json:"cursor,omitempty"`
+	Entries      []*audit.Entry      `json:"entries"`
+}
+
+// HandleGetAuditLog lists recorded admin and moderator actions, newest first
+// GET /xrpc/social.coves.admin.getAuditLog?actor=did:plc:...&action=...&since=...&until=...&limit=50&cursor=...&verifyChain=true
+func (h *AuditHandler) HandleGetAuditLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	filter := audit.Filter{
+		ActorDID: query.Get("actor"),
+		Action:   query.Get("action"),
+		Limit:    audit.DefaultListLimit,
+	}
+	if s := query.Get("limit"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 1 || parsed > audit.MaxListLimit {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be between 1 and 100")
+			return
+		}
+		filter.Limit = parsed
+	}
+	if s := query.Get("cursor"); s != "" {
+		parsed, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || parsed < 1 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidCursor, "Invalid cursor")
+			return
+		}
+		filter.Before = parsed
+	}
+	for _, bound := range []struct {
+		target *time.Time
+		name   string
+	}{{&filter.Since, "since"}, {&filter.Until, "until"}} {
+		s := query.Get(bound.name)
+		if s == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, bound.name+" must be an RFC 3339 timestamp")
+			return
+		}
+		*bound.target = parsed
+	}
+
+	entries, err := h.service.GetAuditLog(r.Context(), filter)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	response := GetAuditLogResponse{Entries: entries}
+	if len(entries) == filter.Limit {
+		response.Cursor = strconv.FormatInt(entries[len(entries)-1].ID, 10)
+	}
+	if query.Get("verifyChain") == "true" {
+		if response.Verification, err = h.service.VerifyChain(r.Context()); err != nil {
+			handleServiceError(w, err)
+			return
+		}
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
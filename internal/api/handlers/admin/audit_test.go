@@ -0,0 +1,124 @@
+package admin
+
+import (
+	"Coves/internal/core/audit"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// mockAuditService records actions and returns canned listings
+type mockAuditService struct {
+	recorded []audit.Entry
+	filter   audit.Filter
+	entries  []*audit.Entry
+	verified bool
+}
+
+func (m *mockAuditService) Record(ctx context.Context, actorDID, action, subject string, details interface{}) {
+	raw, _ := json.Marshal(details)
+	m.recorded = append(m.recorded, audit.Entry{ActorDID: actorDID, Action: action, Subject: subject, Details: raw})
+}
+
+func (m *mockAuditService) GetAuditLog(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	m.filter = filter
+	return m.entries, nil
+}
+
+func (m *mockAuditService) VerifyChain(ctx context.Context) (*audit.Verification, error) {
+	m.verified = true
+	return &audit.Verification{Valid: true, Checked: len(m.entries)}, nil
+}
+
+func TestAdminActions_AreAudited(t *testing.T) {
+	auditLog := &mockAuditService{}
+	admins := NewAdmins([]string{"did:plc:admin"}).WithAudit(auditLog)
+	handler := NewFederationHandler(&mockFederationService{}, admins)
+
+	w := httptest.NewRecorder()
+	handler.HandleCreateRule(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.createFederationRule",
+		`{"pattern":"*.evil.example","mode":"deny"}`, "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// A failed action records nothing
+	w = httptest.NewRecorder()
+	handler.HandleDeleteRule(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.deleteFederationRule",
+		`{"id":7}`, "did:plc:admin"))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if len(auditLog.recorded) != 1 {
+		t.Fatalf("Expected 1 audit entry, got %d", len(auditLog.recorded))
+	}
+	entry := auditLog.recorded[0]
+	if entry.ActorDID != "did:plc:admin" || entry.Action != audit.ActionCreateFederationRule || entry.Subject != "1" {
+		t.Errorf("Unexpected audit entry: %+v", entry)
+	}
+}
+
+func TestAuditHandler_GetAuditLog(t *testing.T) {
+	auditLog := &mockAuditService{entries: []*audit.Entry{{ID: 9}, {ID: 8}}}
+	handler := NewAuditHandler(auditLog, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleGetAuditLog(w, newAdminRequest(http.MethodGet,
+		"/xrpc/social.coves.admin.getAuditLog?actor=did:plc:mod&action=social.coves.admin.takedownRecord"+
+			"&since=2026-03-01T00:00:00Z&limit=2&cursor=10&verifyChain=true", "", "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	want := audit.Filter{
+		ActorDID: "did:plc:mod",
+		Action:   audit.ActionTakedownRecord,
+		Since:    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		Before:   10,
+		Limit:    2,
+	}
+	if !auditLog.filter.Since.Equal(want.Since) || auditLog.filter.ActorDID != want.ActorDID ||
+		auditLog.filter.Action != want.Action || auditLog.filter.Before != want.Before || auditLog.filter.Limit != want.Limit {
+		t.Errorf("Expected filter %+v, got %+v", want, auditLog.filter)
+	}
+
+	var resp GetAuditLogResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Cursor != "8" {
+		t.Errorf("Expected cursor 8, got %q", resp.Cursor)
+	}
+	if !auditLog.verified || resp.Verification == nil || !resp.Verification.Valid {
+		t.Errorf("Expected a verification result, got %+v", resp.Verification)
+	}
+}
+
+func TestAuditHandler_GetAuditLogValidation(t *testing.T) {
+	handler := NewAuditHandler(&mockAuditService{}, NewAdmins([]string{"did:plc:admin"}))
+
+	tests := []struct {
+		name       string
+		query      string
+		userDID    string
+		wantStatus int
+	}{
+		{name: "not an admin", query: "", userDID: "did:plc:someone", wantStatus: http.StatusForbidden},
+		{name: "limit too high", query: "?limit=500", userDID: "did:plc:admin", wantStatus: http.StatusBadRequest},
+		{name: "bad cursor", query: "?cursor=abc", userDID: "did:plc:admin", wantStatus: http.StatusBadRequest},
+		{name: "bad since", query: "?since=yesterday", userDID: "did:plc:admin", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleGetAuditLog(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.getAuditLog"+tt.query, "", tt.userDID))
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
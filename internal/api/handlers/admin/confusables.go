@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/confusables"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultConfusableHandlesLimit = 50
+	maxConfusableHandlesLimit     = 100
+)
+
+// ConfusablesHandler lets instance admins review handles flagged as lookalikes of others
+type ConfusablesHandler struct {
+	repo   confusables.Repository
+	admins Admins
+}
+
+// NewConfusablesHandler creates a new lookalike handle review handler
+func NewConfusablesHandler(repo confusables.Repository, admins Admins) *ConfusablesHandler {
+	return &ConfusablesHandler{
+		repo:   repo,
+		admins: admins,
+	}
+}
+
+// ListConfusableHandlesResponse is the response for social.coves.admin.listConfusableHandles
+// Cursor is the offset of the next page, omitted on the last page
+type ListConfusableHandlesResponse struct {
+	Cursor     string                   `json:"cursor,omitempty"`
+	Collisions []*confusables.Collision `json:"collisions"`
+}
+
+// HandleList lists users and communities whose handle imitates another's, most recently indexed first
+// GET /xrpc/social.coves.admin.listConfusableHandles?limit=50&cursor=0
+func (h *ConfusablesHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	limit := defaultConfusableHandlesLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 1 || parsed > maxConfusableHandlesLimit {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+	offset := 0
+	if s := r.URL.Query().Get("cursor"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 0 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidCursor, "Invalid cursor")
+			return
+		}
+		offset = parsed
+	}
+
+	collisions, err := h.repo.ListCollisions(r.Context(), limit, offset)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	response := ListConfusableHandlesResponse{Collisions: collisions}
+	if len(collisions) == limit {
+		response.Cursor = strconv.Itoa(offset + limit)
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
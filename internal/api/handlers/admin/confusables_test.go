@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"Coves/internal/core/confusables"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockConfusablesRepo pages over a fixed list of collisions
+type mockConfusablesRepo struct {
+	collisions []*confusables.Collision
+}
+
+func (m *mockConfusablesRepo) ListCollisions(ctx context.Context, limit, offset int) ([]*confusables.Collision, error) {
+	if offset >= len(m.collisions) {
+		return []*confusables.Collision{}, nil
+	}
+	return m.collisions[offset:min(offset+limit, len(m.collisions))], nil
+}
+
+func TestConfusablesHandler_List(t *testing.T) {
+	repo := &mockConfusablesRepo{collisions: []*confusables.Collision{
+		{Kind: confusables.KindUser, DID: "did:plc:spoof", Handle: "xn--pple-43d.bsky.social", Skeleton: "apple.bsky.social", ConfusableWith: "did:plc:apple", ConfusableHandle: "apple.bsky.social"},
+		{Kind: confusables.KindCommunity, DID: "did:plc:c", Handle: "c-xn--nws-ued.coves.social", Skeleton: "c-news.coves.social", ConfusableWith: "did:plc:news", ConfusableHandle: "c-news.coves.social"},
+	}}
+	handler := NewConfusablesHandler(repo, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleList(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listConfusableHandles?limit=1", "", "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp ListConfusableHandlesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Collisions) != 1 || resp.Collisions[0].ConfusableHandle != "apple.bsky.social" {
+		t.Errorf("Expected the first collision with the handle it imitates, got %+v", resp.Collisions)
+	}
+	if resp.Cursor != "1" {
+		t.Errorf("Expected cursor 1, got %q", resp.Cursor)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleList(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listConfusableHandles?limit=1&cursor=1", "", "did:plc:admin"))
+	resp = ListConfusableHandlesResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Collisions) != 1 || resp.Collisions[0].Kind != confusables.KindCommunity {
+		t.Errorf("Expected the community collision, got %+v", resp.Collisions)
+	}
+}
+
+func TestConfusablesHandler_RequiresAdmin(t *testing.T) {
+	handler := NewConfusablesHandler(&mockConfusablesRepo{}, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleList(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listConfusableHandles", "", "did:plc:someone"))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestConfusablesHandler_InvalidParams(t *testing.T) {
+	handler := NewConfusablesHandler(&mockConfusablesRepo{}, NewAdmins([]string{"did:plc:admin"}))
+
+	for _, query := range []string{"?limit=0", "?limit=101", "?cursor=-1", "?cursor=abc"} {
+		w := httptest.NewRecorder()
+		handler.HandleList(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listConfusableHandles"+query, "", "did:plc:admin"))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/consumergaps"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultConsumerGapsLimit = 50
+	maxConsumerGapsLimit     = 100
+)
+
+// ConsumerGapsHandler lets instance admins review windows of firehose events the Jetstream
+// consumers missed, and mark them backfilled
+type ConsumerGapsHandler struct {
+	repo   consumergaps.Repository
+	admins Admins
+}
+
+// NewConsumerGapsHandler creates a new consumer gaps handler
+func NewConsumerGapsHandler(repo consumergaps.Repository, admins Admins) *ConsumerGapsHandler {
+	return &ConsumerGapsHandler{
+		repo:   repo,
+		admins: admins,
+	}
+}
+
+// ListConsumerGapsResponse is the response for social.coves.admin.listConsumerGaps
+// Cursor is the offset of the next page, omitted on the last page
+type ListConsumerGapsResponse struct {
+	Cursor string              `json:"cursor,omitempty"`
+	Gaps   []*consumergaps.Gap `json:"gaps"`
+}
+
+// MarkGapBackfilledRequest is the body for social.coves.admin.markGapBackfilled
+type MarkGapBackfilledRequest struct {
+	ID int64 `json:"id"`
+}
+
+// HandleList lists gaps newest first; backfilled gaps are only included on request
+// GET /xrpc/social.coves.admin.listConsumerGaps?includeBackfilled=true&limit=50&cursor=0
+func (h *ConsumerGapsHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	query := r.URL.Query()
+	limit := defaultConsumerGapsLimit
+	if s := query.Get("limit"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 1 || parsed > maxConsumerGapsLimit {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+	offset := 0
+	if s := query.Get("cursor"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 0 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidCursor, "Invalid cursor")
+			return
+		}
+		offset = parsed
+	}
+
+	gaps, err := h.repo.List(r.Context(), query.Get("includeBackfilled") == "true", limit, offset)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	response := ListConsumerGapsResponse{Gaps: gaps}
+	if len(gaps) == limit {
+		response.Cursor = strconv.Itoa(offset + limit)
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// HandleMarkBackfilled marks a gap backfilled once its records have been reindexed, so feeds
+// stop reporting it
+// POST /xrpc/social.coves.admin.markGapBackfilled
+// Body: { "id": 42 }
+func (h *ConsumerGapsHandler) HandleMarkBackfilled(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req MarkGapBackfilledRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	if req.ID < 1 {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "id is required")
+		return
+	}
+
+	gap, err := h.repo.MarkBackfilled(r.Context(), req.ID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionMarkGapBackfilled, strconv.FormatInt(req.ID, 10), gap)
+
+	writeJSONResponse(w, http.StatusOK, gap)
+}
@@ -0,0 +1,155 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/consumergaps"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// mockConsumerGapsRepo holds gaps in memory
+type mockConsumerGapsRepo struct {
+	gaps []*consumergaps.Gap
+}
+
+func (m *mockConsumerGapsRepo) RecordGap(ctx context.Context, consumer string, startTimeUS, endTimeUS int64) error {
+	return nil
+}
+
+func (m *mockConsumerGapsRepo) SavePosition(ctx context.Context, consumer string, position consumergaps.Position) error {
+	return nil
+}
+
+func (m *mockConsumerGapsRepo) LoadPosition(ctx context.Context, consumer string) (consumergaps.Position, error) {
+	return consumergaps.Position{}, nil
+}
+
+func (m *mockConsumerGapsRepo) ListUnfilled(ctx context.Context, from, to time.Time) ([]*consumergaps.Gap, error) {
+	return nil, nil
+}
+
+func (m *mockConsumerGapsRepo) List(ctx context.Context, includeBackfilled bool, limit, offset int) ([]*consumergaps.Gap, error) {
+	result := []*consumergaps.Gap{}
+	for _, gap := range m.gaps {
+		if includeBackfilled || gap.BackfilledAt == nil {
+			result = append(result, gap)
+		}
+	}
+	if offset >= len(result) {
+		return []*consumergaps.Gap{}, nil
+	}
+	return result[offset:min(offset+limit, len(result))], nil
+}
+
+func (m *mockConsumerGapsRepo) MarkBackfilled(ctx context.Context, id int64) (*consumergaps.Gap, error) {
+	for _, gap := range m.gaps {
+		if gap.ID == id {
+			if gap.BackfilledAt == nil {
+				now := time.Now()
+				gap.BackfilledAt = &now
+			}
+			return gap, nil
+		}
+	}
+	return nil, consumergaps.ErrGapNotFound
+}
+
+func newMockConsumerGapsRepo() *mockConsumerGapsRepo {
+	backfilled := time.Now().Add(-time.Hour)
+	return &mockConsumerGapsRepo{gaps: []*consumergaps.Gap{
+		{ID: 2, Consumer: "jetstream", Start: time.Now().Add(-2 * time.Hour), End: time.Now().Add(-time.Hour)},
+		{ID: 1, Consumer: "jetstream", Start: time.Now().Add(-48 * time.Hour), End: time.Now().Add(-47 * time.Hour), BackfilledAt: &backfilled},
+	}}
+}
+
+func TestConsumerGapsHandler_List(t *testing.T) {
+	handler := NewConsumerGapsHandler(newMockConsumerGapsRepo(), NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleList(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listConsumerGaps", "", "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ListConsumerGapsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Gaps) != 1 || resp.Gaps[0].ID != 2 {
+		t.Errorf("Expected only the unfilled gap, got %+v", resp.Gaps)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleList(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listConsumerGaps?includeBackfilled=true&limit=1", "", "did:plc:admin"))
+	resp = ListConsumerGapsResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Gaps) != 1 || resp.Cursor != "1" {
+		t.Errorf("Expected one gap and cursor 1, got %+v (cursor %q)", resp.Gaps, resp.Cursor)
+	}
+}
+
+func TestConsumerGapsHandler_RequiresAdmin(t *testing.T) {
+	handler := NewConsumerGapsHandler(newMockConsumerGapsRepo(), NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleMarkBackfilled(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.markGapBackfilled", `{"id":2}`, "did:plc:someone"))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestConsumerGapsHandler_MarkBackfilled(t *testing.T) {
+	repo := newMockConsumerGapsRepo()
+	handler := NewConsumerGapsHandler(repo, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleMarkBackfilled(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.markGapBackfilled", `{"id":2}`, "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var gap consumergaps.Gap
+	if err := json.Unmarshal(w.Body.Bytes(), &gap); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if gap.ID != 2 || gap.BackfilledAt == nil {
+		t.Errorf("Expected gap 2 marked backfilled, got %+v", gap)
+	}
+}
+
+func TestConsumerGapsHandler_MarkBackfilledErrors(t *testing.T) {
+	handler := NewConsumerGapsHandler(newMockConsumerGapsRepo(), NewAdmins([]string{"did:plc:admin"}))
+
+	tests := []struct {
+		name       string
+		body       string
+		wantError  string
+		wantStatus int
+	}{
+		{name: "missing id", body: `{}`, wantStatus: http.StatusBadRequest, wantError: xrpcerror.InvalidRequest},
+		{name: "invalid body", body: `{"id":"two"}`, wantStatus: http.StatusBadRequest, wantError: xrpcerror.InvalidRequest},
+		{name: "unknown gap", body: `{"id":99}`, wantStatus: http.StatusNotFound, wantError: xrpcerror.NotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleMarkBackfilled(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.markGapBackfilled", tt.body, "did:plc:admin"))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var errResp xrpcerror.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("Failed to decode error: %v", err)
+			}
+			if errResp.Error != tt.wantError {
+				t.Errorf("Expected error %s, got %s", tt.wantError, errResp.Error)
+			}
+		})
+	}
+}
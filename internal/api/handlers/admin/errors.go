@@ -0,0 +1,114 @@
+package admin
+
+import (
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/automod"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/federation"
+	"Coves/internal/core/moderation"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// Admins is the set of DIDs allowed to call instance admin endpoints, and the audit log
+// their (and moderators') actions are recorded to
+// An empty set means no one is an admin (admin endpoints always return 403)
+type Admins struct {
+	dids  map[string]bool
+	audit audit.Recorder
+}
+
+// NewAdmins builds an admin set from a list of DIDs, skipping empty entries
+func NewAdmins(dids []string) Admins {
+	admins := Admins{dids: make(map[string]bool, len(dids))}
+	for _, did := range dids {
+		if did != "" {
+			admins.dids[did] = true
+		}
+	}
+	return admins
+}
+
+// WithAudit returns the admin set recording actions to recorder
+func (a Admins) WithAudit(recorder audit.Recorder) Admins {
+	a.audit = recorder
+	return a
+}
+
+// isAdmin reports whether did is an instance admin
+func (a Admins) isAdmin(did string) bool {
+	return a.dids[did]
+}
+
+// record adds a successful action to the audit log, if one is configured
+func (a Admins) record(r *http.Request, actorDID, action, subject string, details interface{}) {
+	if a.audit != nil {
+		a.audit.Record(r.Context(), actorDID, action, subject, details)
+	}
+}
+
+// authorize checks the authenticated user is an instance admin, writing an error response if not
+// Returns the admin's DID and true when the request may proceed
+func (a Admins) authorize(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return "", false
+	}
+	if !a.isAdmin(userDID) {
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.AdminRequired, "This endpoint is restricted to instance administrators")
+		return "", false
+	}
+	return userDID, true
+}
+
+// writeJSONResponse buffers the JSON encoding before sending headers
+func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		log.Printf("ERROR: Failed to encode JSON response: %v", err)
+		xrpcerror.WriteEncodeFailure(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("ERROR: Failed to write response body: %v", err)
+	}
+}
+
+// handleServiceError maps admin service errors to HTTP responses
+func handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case federation.IsValidationError(err), discover.IsValidationError(err),
+		communities.IsValidationError(err), moderation.IsValidationError(err),
+		automod.IsValidationError(err),
+		errors.Is(err, communities.ErrReservedNameBuiltIn),
+		errors.Is(err, communities.ErrCommunityNotFlagged):
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
+	case errors.Is(err, communities.ErrReservedNameNotFound):
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.ReservedNameNotFound, err.Error())
+	case federation.IsNotFound(err),
+		errors.Is(err, discover.ErrCommunityNotFound),
+		errors.Is(err, communities.ErrCommunityNotFound),
+		errors.Is(err, discover.ErrFeaturedCommunityNotFound),
+		errors.Is(err, moderation.ErrContentNotFound):
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.NotFound, err.Error())
+	case federation.IsConflict(err):
+		xrpcerror.WriteError(w, http.StatusConflict, xrpcerror.AlreadyExists, err.Error())
+	default:
+		if common.WriteErrorKind(w, err) {
+			return
+		}
+		log.Printf("ERROR: Admin service error: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
+	}
+}
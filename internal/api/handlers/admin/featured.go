@@ -0,0 +1,146 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/discover"
+	"encoding/json"
+	"net/http"
+)
+
+// FeaturedCommunitiesHandler manages the communities featured on the logged-out front page
+type FeaturedCommunitiesHandler struct {
+	service discover.Service
+	admins  Admins
+}
+
+// NewFeaturedCommunitiesHandler creates a new featured communities handler
+func NewFeaturedCommunitiesHandler(service discover.Service, admins Admins) *FeaturedCommunitiesHandler {
+	return &FeaturedCommunitiesHandler{
+		service: service,
+		admins:  admins,
+	}
+}
+
+// FeaturedCommunitiesResponse is the response for the featured community endpoints
+type FeaturedCommunitiesResponse struct {
+	Communities []*discover.FeaturedCommunity `json:"communities"`
+}
+
+// RemoveFeaturedCommunityRequest is the body for social.coves.admin.removeFeaturedCommunity
+type RemoveFeaturedCommunityRequest struct {
+	CommunityDID string `json:"community"`
+}
+
+// ReorderFeaturedCommunitiesRequest is the body for social.coves.admin.reorderFeaturedCommunities
+type ReorderFeaturedCommunitiesRequest struct {
+	Communities []string `json:"communities"`
+}
+
+// HandleList lists featured communities in front page order
+// GET /xrpc/social.coves.admin.listFeaturedCommunities
+func (h *FeaturedCommunitiesHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	featured, err := h.service.ListFeaturedCommunities(r.Context())
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, FeaturedCommunitiesResponse{Communities: featured})
+}
+
+// HandleAdd features a community, or updates its weight if it is already featured
+// POST /xrpc/social.coves.admin.addFeaturedCommunity
+// Body: { "community": "did:plc:...", "weight": 2.0 }
+func (h *FeaturedCommunitiesHandler) HandleAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req discover.AddFeaturedCommunityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.AddedBy = adminDID
+
+	featured, err := h.service.AddFeaturedCommunity(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionAddFeaturedCommunity, req.CommunityDID, featured)
+
+	writeJSONResponse(w, http.StatusOK, featured)
+}
+
+// HandleRemove stops featuring a community
+// POST /xrpc/social.coves.admin.removeFeaturedCommunity
+// Body: { "community": "did:plc:..." }
+func (h *FeaturedCommunitiesHandler) HandleRemove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req RemoveFeaturedCommunityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.RemoveFeaturedCommunity(r.Context(), req.CommunityDID); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionRemoveFeaturedCommunity, req.CommunityDID, nil)
+
+	writeJSONResponse(w, http.StatusOK, map[string]interface{}{})
+}
+
+// HandleReorder sets the order of the featured communities
+// POST /xrpc/social.coves.admin.reorderFeaturedCommunities
+// Body: { "communities": ["did:plc:first", "did:plc:second"] }
+// The list must contain every featured community exactly once
+func (h *FeaturedCommunitiesHandler) HandleReorder(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReorderFeaturedCommunitiesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+
+	featured, err := h.service.ReorderFeaturedCommunities(r.Context(), req.Communities)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionReorderFeaturedCommunities, "", req)
+
+	writeJSONResponse(w, http.StatusOK, FeaturedCommunitiesResponse{Communities: featured})
+}
@@ -0,0 +1,146 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/discover"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockDiscoverService records featured community changes
+type mockDiscoverService struct {
+	added     []discover.AddFeaturedCommunityRequest
+	reordered [][]string
+}
+
+func (m *mockDiscoverService) GetDiscover(ctx context.Context, req discover.GetDiscoverRequest) (*discover.DiscoverResponse, error) {
+	return &discover.DiscoverResponse{}, nil
+}
+
+func (m *mockDiscoverService) GetDiscussions(ctx context.Context, req discover.GetDiscussionsRequest) (*discover.DiscoverResponse, error) {
+	return &discover.DiscoverResponse{}, nil
+}
+
+func (m *mockDiscoverService) GetFrontPage(ctx context.Context, req discover.GetFrontPageRequest) (*discover.DiscoverResponse, error) {
+	return &discover.DiscoverResponse{}, nil
+}
+
+func (m *mockDiscoverService) ListFeaturedCommunities(ctx context.Context) ([]*discover.FeaturedCommunity, error) {
+	return []*discover.FeaturedCommunity{}, nil
+}
+
+func (m *mockDiscoverService) AddFeaturedCommunity(ctx context.Context, req discover.AddFeaturedCommunityRequest) (*discover.FeaturedCommunity, error) {
+	if req.CommunityDID == "did:plc:missing" {
+		return nil, discover.ErrCommunityNotFound
+	}
+	m.added = append(m.added, req)
+	return &discover.FeaturedCommunity{CommunityDID: req.CommunityDID, AddedBy: req.AddedBy}, nil
+}
+
+func (m *mockDiscoverService) RemoveFeaturedCommunity(ctx context.Context, communityDID string) error {
+	return discover.ErrFeaturedCommunityNotFound
+}
+
+func (m *mockDiscoverService) ReorderFeaturedCommunities(ctx context.Context, communityDIDs []string) ([]*discover.FeaturedCommunity, error) {
+	if len(communityDIDs) == 0 {
+		return nil, discover.NewValidationError("communities", "communities must list every featured community")
+	}
+	m.reordered = append(m.reordered, communityDIDs)
+	return []*discover.FeaturedCommunity{}, nil
+}
+
+func TestFeaturedCommunitiesHandler_RequiresAdmin(t *testing.T) {
+	handler := NewFeaturedCommunitiesHandler(&mockDiscoverService{}, NewAdmins([]string{"did:plc:admin"}))
+
+	endpoints := []struct {
+		handle http.HandlerFunc
+		name   string
+		method string
+		body   string
+	}{
+		{name: "list", method: http.MethodGet, handle: handler.HandleList},
+		{name: "add", method: http.MethodPost, body: `{"community":"did:plc:c1"}`, handle: handler.HandleAdd},
+		{name: "remove", method: http.MethodPost, body: `{"community":"did:plc:c1"}`, handle: handler.HandleRemove},
+		{name: "reorder", method: http.MethodPost, body: `{"communities":["did:plc:c1"]}`, handle: handler.HandleReorder},
+	}
+
+	for _, ep := range endpoints {
+		t.Run(ep.name, func(t *testing.T) {
+			for _, tt := range []struct {
+				userDID    string
+				wantStatus int
+				wantError  string
+			}{
+				{userDID: "", wantStatus: http.StatusUnauthorized, wantError: "AuthRequired"},
+				{userDID: "did:plc:someone", wantStatus: http.StatusForbidden, wantError: "AdminRequired"},
+			} {
+				w := httptest.NewRecorder()
+				ep.handle(w, newAdminRequest(ep.method, "/xrpc/social.coves.admin."+ep.name, ep.body, tt.userDID))
+
+				if w.Code != tt.wantStatus {
+					t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+				}
+				var resp xrpcerror.Response
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("Failed to decode error: %v", err)
+				}
+				if resp.Error != tt.wantError {
+					t.Errorf("Expected error %s, got %s", tt.wantError, resp.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestFeaturedCommunitiesHandler_Add(t *testing.T) {
+	service := &mockDiscoverService{}
+	handler := NewFeaturedCommunitiesHandler(service, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleAdd(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.addFeaturedCommunity",
+		`{"community":"did:plc:c1","weight":3}`, "did:plc:admin"))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(service.added) != 1 {
+		t.Fatalf("Expected one community added, got %d", len(service.added))
+	}
+	added := service.added[0]
+	if added.AddedBy != "did:plc:admin" {
+		t.Errorf("Expected AddedBy to be the calling admin, got %q", added.AddedBy)
+	}
+	if added.Weight == nil || *added.Weight != 3 {
+		t.Errorf("Expected weight 3, got %v", added.Weight)
+	}
+}
+
+func TestFeaturedCommunitiesHandler_ErrorMapping(t *testing.T) {
+	handler := NewFeaturedCommunitiesHandler(&mockDiscoverService{}, NewAdmins([]string{"did:plc:admin"}))
+
+	tests := []struct {
+		handle     http.HandlerFunc
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "unknown community", handle: handler.HandleAdd, body: `{"community":"did:plc:missing"}`, wantStatus: http.StatusNotFound},
+		{name: "remove not featured", handle: handler.HandleRemove, body: `{"community":"did:plc:c1"}`, wantStatus: http.StatusNotFound},
+		{name: "invalid reorder", handle: handler.HandleReorder, body: `{"communities":[]}`, wantStatus: http.StatusBadRequest},
+		{name: "malformed body", handle: handler.HandleAdd, body: `{`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handle(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.test", tt.body, "did:plc:admin"))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/federation"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// FederationHandler handles instance federation policy administration
+type FederationHandler struct {
+	service federation.Service
+	admins  Admins
+}
+
+// NewFederationHandler creates a new federation policy handler
+func NewFederationHandler(service federation.Service, admins Admins) *FederationHandler {
+	return &FederationHandler{
+		service: service,
+		admins:  admins,
+	}
+}
+
+// ListFederationRulesResponse is the response for social.coves.admin.listFederationRules
+type ListFederationRulesResponse struct {
+	Rules []*federation.Rule `json:"rules"`
+}
+
+// DeleteFederationRuleRequest is the body for social.coves.admin.deleteFederationRule
+type DeleteFederationRuleRequest struct {
+	ID int64 `json:"id"`
+}
+
+// HandleListRules lists all federation rules
+// GET /xrpc/social.coves.admin.listFederationRules
+func (h *FederationHandler) HandleListRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	rules, err := h.service.ListRules(r.Context())
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, ListFederationRulesResponse{Rules: rules})
+}
+
+// HandleCreateRule adds an allow or deny rule for a remote instance domain
+// POST /xrpc/social.coves.admin.createFederationRule
+// Body: { "pattern": "*.example.com", "mode": "deny", "reason": "spam" }
+func (h *FederationHandler) HandleCreateRule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req federation.CreateRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.CreatedBy = adminDID
+
+	rule, err := h.service.CreateRule(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionCreateFederationRule, strconv.FormatInt(rule.ID, 10), rule)
+
+	writeJSONResponse(w, http.StatusOK, rule)
+}
+
+// HandleDeleteRule removes a federation rule
+// POST /xrpc/social.coves.admin.deleteFederationRule
+// Body: { "id": 42 }
+func (h *FederationHandler) HandleDeleteRule(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req DeleteFederationRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.service.DeleteRule(r.Context(), req.ID); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionDeleteFederationRule, strconv.FormatInt(req.ID, 10), nil)
+
+	writeJSONResponse(w, http.StatusOK, map[string]interface{}{})
+}
@@ -0,0 +1,115 @@
+package admin
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/federation"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockFederationService records created rules
+type mockFederationService struct {
+	created []federation.CreateRuleRequest
+}
+
+func (m *mockFederationService) ListRules(ctx context.Context) ([]*federation.Rule, error) {
+	return []*federation.Rule{}, nil
+}
+
+func (m *mockFederationService) CreateRule(ctx context.Context, req federation.CreateRuleRequest) (*federation.Rule, error) {
+	m.created = append(m.created, req)
+	return &federation.Rule{ID: 1, Pattern: req.Pattern, Mode: req.Mode, CreatedBy: req.CreatedBy}, nil
+}
+
+func (m *mockFederationService) DeleteRule(ctx context.Context, id int64) error {
+	return federation.ErrRuleNotFound
+}
+
+func (m *mockFederationService) IsHostBlocked(ctx context.Context, hostedByDID string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockFederationService) ReevaluateCommunities(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (m *mockFederationService) Start(ctx context.Context) {}
+
+func newAdminRequest(method, path, body, userDID string) *http.Request {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	if userDID != "" {
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserDIDKey, userDID))
+	}
+	return req
+}
+
+func TestFederationHandler_RequiresAdmin(t *testing.T) {
+	service := &mockFederationService{}
+	handler := NewFederationHandler(service, NewAdmins([]string{"did:plc:admin"}))
+	body := `{"pattern":"*.evil.example","mode":"deny"}`
+
+	tests := []struct {
+		name       string
+		userDID    string
+		wantStatus int
+		wantError  string
+	}{
+		{name: "unauthenticated", userDID: "", wantStatus: http.StatusUnauthorized, wantError: "AuthRequired"},
+		{name: "not an admin", userDID: "did:plc:someone", wantStatus: http.StatusForbidden, wantError: "AdminRequired"},
+		{name: "admin", userDID: "did:plc:admin", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleCreateRule(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.createFederationRule", body, tt.userDID))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantError != "" {
+				var resp xrpcerror.Response
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("Failed to decode error: %v", err)
+				}
+				if resp.Error != tt.wantError {
+					t.Errorf("Expected error %s, got %s", tt.wantError, resp.Error)
+				}
+			}
+		})
+	}
+
+	if len(service.created) != 1 {
+		t.Fatalf("Expected exactly one rule to be created, got %d", len(service.created))
+	}
+	if service.created[0].CreatedBy != "did:plc:admin" {
+		t.Errorf("Expected createdBy to come from the authenticated admin, got %q", service.created[0].CreatedBy)
+	}
+}
+
+func TestFederationHandler_EmptyAdminListRejectsEveryone(t *testing.T) {
+	handler := NewFederationHandler(&mockFederationService{}, NewAdmins(nil))
+
+	w := httptest.NewRecorder()
+	handler.HandleListRules(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listFederationRules", "", "did:plc:anyone"))
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 with no admins configured, got %d", w.Code)
+	}
+}
+
+func TestFederationHandler_DeleteUnknownRule(t *testing.T) {
+	handler := NewFederationHandler(&mockFederationService{}, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleDeleteRule(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.deleteFederationRule", `{"id":99}`, "did:plc:admin"))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 for unknown rule, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,159 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/communities"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Review actions for social.coves.admin.reviewImpersonationFlag
+const (
+	ImpersonationActionClear   = "clear"   // Not impersonation: unflag and stop flagging for the same reason
+	ImpersonationActionConfirm = "confirm" // Impersonation: suspend the community
+)
+
+const (
+	defaultFlaggedCommunitiesLimit = 50
+	maxFlaggedCommunitiesLimit     = 100
+)
+
+// ImpersonationHandler lets instance admins review communities flagged as impersonating others
+type ImpersonationHandler struct {
+	repo   communities.ImpersonationRepository
+	admins Admins
+}
+
+// NewImpersonationHandler creates a new impersonation review handler
+func NewImpersonationHandler(repo communities.ImpersonationRepository, admins Admins) *ImpersonationHandler {
+	return &ImpersonationHandler{
+		repo:   repo,
+		admins: admins,
+	}
+}
+
+// FlaggedCommunity is a community awaiting impersonation review
+type FlaggedCommunity struct {
+	UpdatedAt   time.Time `json:"updatedAt"`
+	DID         string    `json:"did"`
+	Handle      string    `json:"handle"`
+	Name        string    `json:"name"`
+	DisplayName string    `json:"displayName,omitempty"`
+	HostedByDID string    `json:"hostedByDid"`
+	Reason      string    `json:"reason"`
+}
+
+// ListFlaggedCommunitiesResponse is the response for social.coves.admin.listImpersonationFlags
+// Cursor is the offset of the next page, omitted on the last page
+type ListFlaggedCommunitiesResponse struct {
+	Cursor      string              `json:"cursor,omitempty"`
+	Communities []*FlaggedCommunity `json:"communities"`
+}
+
+// ReviewImpersonationRequest is the body for social.coves.admin.reviewImpersonationFlag
+type ReviewImpersonationRequest struct {
+	Community string `json:"community"`
+	Action    string `json:"action"`
+}
+
+// HandleList lists flagged communities awaiting review, most recently updated first
+// GET /xrpc/social.coves.admin.listImpersonationFlags?limit=50&cursor=0
+func (h *ImpersonationHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	limit := defaultFlaggedCommunitiesLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 1 || parsed > maxFlaggedCommunitiesLimit {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+	offset := 0
+	if s := r.URL.Query().Get("cursor"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 0 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidCursor, "Invalid cursor")
+			return
+		}
+		offset = parsed
+	}
+
+	flagged, err := h.repo.ListFlaggedCommunities(r.Context(), limit, offset)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	response := ListFlaggedCommunitiesResponse{Communities: make([]*FlaggedCommunity, 0, len(flagged))}
+	for _, c := range flagged {
+		response.Communities = append(response.Communities, &FlaggedCommunity{
+			DID:         c.DID,
+			Handle:      c.Handle,
+			Name:        c.Name,
+			DisplayName: c.DisplayName,
+			HostedByDID: c.HostedByDID,
+			Reason:      c.ImpersonationReason,
+			UpdatedAt:   c.UpdatedAt,
+		})
+	}
+	if len(flagged) == limit {
+		response.Cursor = strconv.Itoa(offset + limit)
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// HandleReview clears a flagged community or confirms the impersonation, suspending it
+// POST /xrpc/social.coves.admin.reviewImpersonationFlag
+// Body: { "community": "did:plc:...", "action": "confirm" }
+func (h *ImpersonationHandler) HandleReview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReviewImpersonationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	if req.Community == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
+		return
+	}
+
+	var err error
+	switch req.Action {
+	case ImpersonationActionClear:
+		err = h.repo.ClearImpersonationFlag(r.Context(), req.Community)
+	case ImpersonationActionConfirm:
+		err = h.repo.ConfirmImpersonation(r.Context(), req.Community)
+	default:
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "action must be clear or confirm")
+		return
+	}
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	log.Printf("Admin %s reviewed impersonation flag on %s: %s", adminDID, req.Community, req.Action)
+	h.admins.record(r, adminDID, audit.ActionReviewImpersonationFlag, req.Community, req)
+	writeJSONResponse(w, http.StatusOK, req)
+}
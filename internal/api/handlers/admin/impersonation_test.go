@@ -0,0 +1,155 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/communities"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockImpersonationRepo records review actions
+type mockImpersonationRepo struct {
+	flagged   []*communities.Community
+	cleared   []string
+	confirmed []string
+}
+
+func (m *mockImpersonationRepo) ListFlaggedCommunities(ctx context.Context, limit, offset int) ([]*communities.Community, error) {
+	if offset >= len(m.flagged) {
+		return []*communities.Community{}, nil
+	}
+	return m.flagged[offset:min(offset+limit, len(m.flagged))], nil
+}
+
+func (m *mockImpersonationRepo) ClearImpersonationFlag(ctx context.Context, did string) error {
+	if err := m.check(did); err != nil {
+		return err
+	}
+	m.cleared = append(m.cleared, did)
+	return nil
+}
+
+func (m *mockImpersonationRepo) ConfirmImpersonation(ctx context.Context, did string) error {
+	if err := m.check(did); err != nil {
+		return err
+	}
+	m.confirmed = append(m.confirmed, did)
+	return nil
+}
+
+func (m *mockImpersonationRepo) check(did string) error {
+	switch did {
+	case "did:plc:missing":
+		return communities.ErrCommunityNotFound
+	case "did:plc:clean":
+		return communities.ErrCommunityNotFlagged
+	}
+	return nil
+}
+
+func TestImpersonationHandler_List(t *testing.T) {
+	repo := &mockImpersonationRepo{flagged: []*communities.Community{
+		{DID: "did:plc:a", Handle: "c-a.evil.example", ImpersonationReason: "displayName claims !news@coves.social"},
+		{DID: "did:plc:b", Handle: "c-b.evil.example", ImpersonationReason: "name claims !b@coves.social"},
+	}}
+	handler := NewImpersonationHandler(repo, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleList(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listImpersonationFlags?limit=1", "", "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp ListFlaggedCommunitiesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Communities) != 1 || resp.Communities[0].Reason != "displayName claims !news@coves.social" {
+		t.Errorf("Expected the first flagged community with its reason, got %+v", resp.Communities)
+	}
+	if resp.Cursor != "1" {
+		t.Errorf("Expected cursor 1, got %q", resp.Cursor)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleList(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listImpersonationFlags?limit=1&cursor=1", "", "did:plc:admin"))
+	resp = ListFlaggedCommunitiesResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Communities) != 1 || resp.Communities[0].DID != "did:plc:b" {
+		t.Errorf("Expected the second flagged community, got %+v", resp.Communities)
+	}
+}
+
+func TestImpersonationHandler_RequiresAdmin(t *testing.T) {
+	handler := NewImpersonationHandler(&mockImpersonationRepo{}, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleReview(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.reviewImpersonationFlag",
+		`{"community":"did:plc:a","action":"clear"}`, "did:plc:someone"))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestImpersonationHandler_Review(t *testing.T) {
+	repo := &mockImpersonationRepo{}
+	handler := NewImpersonationHandler(repo, NewAdmins([]string{"did:plc:admin"}))
+
+	for _, body := range []string{
+		`{"community":"did:plc:a","action":"clear"}`,
+		`{"community":"did:plc:b","action":"confirm"}`,
+	} {
+		w := httptest.NewRecorder()
+		handler.HandleReview(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.reviewImpersonationFlag", body, "did:plc:admin"))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	if len(repo.cleared) != 1 || repo.cleared[0] != "did:plc:a" {
+		t.Errorf("Expected did:plc:a cleared, got %v", repo.cleared)
+	}
+	if len(repo.confirmed) != 1 || repo.confirmed[0] != "did:plc:b" {
+		t.Errorf("Expected did:plc:b confirmed, got %v", repo.confirmed)
+	}
+}
+
+func TestImpersonationHandler_ReviewErrors(t *testing.T) {
+	handler := NewImpersonationHandler(&mockImpersonationRepo{}, NewAdmins([]string{"did:plc:admin"}))
+
+	tests := []struct {
+		name       string
+		body       string
+		wantError  string
+		wantStatus int
+	}{
+		{name: "unknown action", body: `{"community":"did:plc:a","action":"ban"}`, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "missing community", body: `{"action":"clear"}`, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "not flagged", body: `{"community":"did:plc:clean","action":"confirm"}`, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "unknown community", body: `{"community":"did:plc:missing","action":"clear"}`, wantStatus: http.StatusNotFound, wantError: "NotFound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleReview(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.reviewImpersonationFlag", tt.body, "did:plc:admin"))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var resp xrpcerror.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode error: %v", err)
+			}
+			if resp.Error != tt.wantError {
+				t.Errorf("Expected error %s, got %s", tt.wantError, resp.Error)
+			}
+		})
+	}
+}
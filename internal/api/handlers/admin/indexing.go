@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/comments"
+	"net/http"
+)
+
+// IndexingConsumer is one Jetstream consumer and what it's subscribed to
+type IndexingConsumer struct {
+	Name        string   `json:"name"`
+	Collections []string `json:"collections"`
+	EventKinds  []string `json:"eventKinds,omitempty"` // identity and account events
+}
+
+// IndexingStatus describes the Jetstream consumers this process runs
+type IndexingStatus struct {
+	Comments         CommentMetrics // Optional - the response has no comment summary when nil
+	Consumers        []IndexingConsumer
+	SharedConnection bool // One dispatcher connection rather than one per consumer
+}
+
+// CommentIndexingSummary is how often the comment consumer repaired event ordering, as shares
+// of the comments it indexed since the process started; getMetrics has the full counters
+type CommentIndexingSummary struct {
+	MedianReconcileLag   string  `json:"medianReconcileLag,omitempty"` // Bucket bound, e.g. "1s"; empty before any reconciliation
+	Indexed              int64   `json:"indexed"`
+	ReconciledParents    int64   `json:"reconciledParents"`
+	ReconciledParentRate float64 `json:"reconciledParentRate"`
+	OrphansCreated       int64   `json:"orphansCreated"`
+	OrphanRate           float64 `json:"orphanRate"`
+	OrphansResolved      int64   `json:"orphansResolved"`
+	ThreadingRejections  int64   `json:"threadingRejections"`
+	Resurrections        int64   `json:"resurrections"`
+}
+
+// IndexingStatusHandler reports which collections are being indexed to instance admins
+type IndexingStatusHandler struct {
+	status IndexingStatus
+	admins Admins
+}
+
+// NewIndexingStatusHandler creates a new admin indexing status handler
+func NewIndexingStatusHandler(status IndexingStatus, admins Admins) *IndexingStatusHandler {
+	return &IndexingStatusHandler{
+		status: status,
+		admins: admins,
+	}
+}
+
+// GetIndexingStatusResponse is the response for social.coves.admin.getIndexingStatus
+type GetIndexingStatusResponse struct {
+	Collections      map[string]string       `json:"collections"` // Collection NSID -> consumer name
+	Comments         *CommentIndexingSummary `json:"comments,omitempty"`
+	Consumers        []IndexingConsumer      `json:"consumers"`
+	SharedConnection bool                    `json:"sharedConnection"`
+}
+
+// HandleGetIndexingStatus returns the active collection to consumer mapping
+// GET /xrpc/social.coves.admin.getIndexingStatus
+func (h *IndexingStatusHandler) HandleGetIndexingStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	resp := GetIndexingStatusResponse{
+		Collections:      make(map[string]string),
+		Consumers:        h.status.Consumers,
+		SharedConnection: h.status.SharedConnection,
+	}
+	if resp.Consumers == nil {
+		resp.Consumers = []IndexingConsumer{}
+	}
+	for _, consumer := range h.status.Consumers {
+		for _, collection := range consumer.Collections {
+			resp.Collections[collection] = consumer.Name
+		}
+	}
+	if h.status.Comments != nil {
+		resp.Comments = summarizeCommentMetrics(h.status.Comments.CommentConsumerMetrics())
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// summarizeCommentMetrics turns the comment consumer's counters into rates
+func summarizeCommentMetrics(metrics comments.ConsumerMetrics) *CommentIndexingSummary {
+	summary := &CommentIndexingSummary{
+		Indexed:             metrics.Indexed,
+		ReconciledParents:   metrics.ReconciledParents,
+		OrphansCreated:      metrics.OrphansCreated,
+		OrphansResolved:     metrics.OrphansResolved,
+		ThreadingRejections: metrics.ThreadingRejections,
+		Resurrections:       metrics.Resurrections,
+	}
+	if metrics.Indexed > 0 {
+		summary.ReconciledParentRate = float64(metrics.ReconciledParents) / float64(metrics.Indexed)
+		summary.OrphanRate = float64(metrics.OrphansCreated) / float64(metrics.Indexed)
+	}
+	// Buckets are cumulative: the median is in the first one holding half the observations
+	if lag := metrics.ReconcileLag; lag.Count > 0 {
+		for _, bucket := range lag.Buckets {
+			if bucket.Count*2 >= lag.Count {
+				summary.MedianReconcileLag = bucket.LE
+				break
+			}
+		}
+	}
+	return summary
+}
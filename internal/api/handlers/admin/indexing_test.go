@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"Coves/internal/core/comments"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIndexingStatusHandler(t *testing.T) {
+	handler := NewIndexingStatusHandler(IndexingStatus{
+		SharedConnection: true,
+		Consumers: []IndexingConsumer{
+			{Name: "User", Collections: []string{"social.coves.actor.profile"}, EventKinds: []string{"identity"}},
+			{Name: "Vote", Collections: []string{"social.coves.feed.vote"}},
+		},
+	}, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleGetIndexingStatus(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.getIndexingStatus", "", "did:plc:user"))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("non-admin status = %d, want 403", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleGetIndexingStatus(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.getIndexingStatus", "", "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp GetIndexingStatusResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.SharedConnection || len(resp.Consumers) != 2 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+	if resp.Collections["social.coves.feed.vote"] != "Vote" || resp.Collections["social.coves.actor.profile"] != "User" {
+		t.Errorf("collections = %v", resp.Collections)
+	}
+	if _, ok := resp.Collections["identity"]; ok {
+		t.Error("event kinds are not collections")
+	}
+	if resp.Comments != nil {
+		t.Errorf("comments summary without a comment consumer: %+v", resp.Comments)
+	}
+}
+
+type fakeCommentMetrics comments.ConsumerMetrics
+
+func (f fakeCommentMetrics) CommentConsumerMetrics() comments.ConsumerMetrics {
+	return comments.ConsumerMetrics(f)
+}
+
+func TestIndexingStatusHandler_CommentSummary(t *testing.T) {
+	handler := NewIndexingStatusHandler(IndexingStatus{
+		Comments: fakeCommentMetrics{
+			Indexed:           200,
+			ReconciledParents: 10,
+			OrphansCreated:    4,
+			OrphansResolved:   3,
+			Resurrections:     1,
+			ReconcileLag: comments.LagHistogram{
+				Count: 10,
+				Buckets: []comments.LagBucket{
+					{LE: "100ms", Count: 2},
+					{LE: "1s", Count: 7},
+					{LE: "+Inf", Count: 10},
+				},
+			},
+		},
+	}, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleGetIndexingStatus(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.getIndexingStatus", "", "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp GetIndexingStatusResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	summary := resp.Comments
+	if summary == nil {
+		t.Fatal("expected a comments summary")
+	}
+	if summary.ReconciledParentRate != 0.05 || summary.OrphanRate != 0.02 {
+		t.Errorf("rates = %v reconciled, %v orphaned", summary.ReconciledParentRate, summary.OrphanRate)
+	}
+	if summary.OrphansResolved != 3 || summary.Resurrections != 1 {
+		t.Errorf("unexpected counters: %+v", summary)
+	}
+	if summary.MedianReconcileLag != "1s" {
+		t.Errorf("median lag = %q, want 1s", summary.MedianReconcileLag)
+	}
+}
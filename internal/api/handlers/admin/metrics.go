@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/comments"
+	"net/http"
+	"time"
+)
+
+// IndexingMetrics exposes counters kept by the Jetstream consumers
+type IndexingMetrics interface {
+	CommentMetrics
+	PostsIndexedWithoutAltText() int64
+	PostsQuarantined() int64
+	UserEventQueueDepth() int64
+	IdentityResolutions() (int64, time.Duration)
+	StaleEventsSkipped() map[string]int64
+}
+
+// CommentMetrics exposes the comment consumer's out-of-order reconciliation counters
+type CommentMetrics interface {
+	CommentConsumerMetrics() comments.ConsumerMetrics
+}
+
+// MetricsHandler reports indexing metrics to instance admins
+type MetricsHandler struct {
+	metrics IndexingMetrics
+	admins  Admins
+}
+
+// NewMetricsHandler creates a new admin metrics handler
+func NewMetricsHandler(metrics IndexingMetrics, admins Admins) *MetricsHandler {
+	return &MetricsHandler{
+		metrics: metrics,
+		admins:  admins,
+	}
+}
+
+// GetMetricsResponse is the response for social.coves.admin.getMetrics
+// Counters are per process and reset on restart
+type GetMetricsResponse struct {
+	PostsIndexedWithoutAltText int64   `json:"postsIndexedWithoutAltText"`
+	PostsQuarantined           int64   `json:"postsQuarantined"`
+	UserEventQueueDepth        int64   `json:"userEventQueueDepth"`     // User events queued or being handled
+	IdentityResolutions        int64   `json:"identityResolutions"`     // Identity lookups by the user consumer
+	IdentityResolutionAvgMs    float64 `json:"identityResolutionAvgMs"` // Their mean latency
+
+	// StaleEventsSkipped counts, per collection, redelivered events skipped because the
+	// indexed record was already written or deleted by a newer commit
+	StaleEventsSkipped map[string]int64 `json:"staleEventsSkipped"`
+
+	// Comments counts replies indexed before their parent, comments indexed before their post,
+	// and other ordering repairs by the comment consumer
+	Comments comments.ConsumerMetrics `json:"comments"`
+}
+
+// HandleGetMetrics returns indexing metrics, e.g. alt text compliance for image posts, posts
+// quarantined by the spam guard, how far behind the user consumer is, how often comments
+// arrived out of order and how many replayed events were skipped
+// GET /xrpc/social.coves.admin.getMetrics
+func (h *MetricsHandler) HandleGetMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	resp := GetMetricsResponse{
+		PostsIndexedWithoutAltText: h.metrics.PostsIndexedWithoutAltText(),
+		PostsQuarantined:           h.metrics.PostsQuarantined(),
+		UserEventQueueDepth:        h.metrics.UserEventQueueDepth(),
+		StaleEventsSkipped:         h.metrics.StaleEventsSkipped(),
+		Comments:                   h.metrics.CommentConsumerMetrics(),
+	}
+	resolutions, latency := h.metrics.IdentityResolutions()
+	resp.IdentityResolutions = resolutions
+	if resolutions > 0 {
+		resp.IdentityResolutionAvgMs = float64(latency.Microseconds()) / 1000 / float64(resolutions)
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
@@ -0,0 +1,467 @@
+package admin
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/automod"
+	"Coves/internal/core/brigade"
+	"Coves/internal/core/moderation"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultModerationQueueLimit = 50
+	maxModerationQueueLimit     = 100
+)
+
+// ListBrigadeAlertsResponse is the response for social.coves.moderation.listBrigadeAlerts
+// Cursor is the offset of the next page, omitted on the last page
+type ListBrigadeAlertsResponse struct {
+	Cursor string           `json:"cursor,omitempty"`
+	Alerts []*brigade.Alert `json:"alerts"`
+}
+
+// ListAutomodQueueResponse is the response for social.coves.moderation.listAutomodQueue
+// Cursor is the offset of the next page, omitted on the last page
+type ListAutomodQueueResponse struct {
+	Cursor  string                  `json:"cursor,omitempty"`
+	Actions []*automod.ActionRecord `json:"actions"`
+}
+
+// ModerationHandler lets instance admins remove, shadow-ban or take down posts and comments,
+// lock threads and override post content labels, and lets community moderators read comment edit history,
+// configure automod and work their brigade alert and automod queues
+type ModerationHandler struct {
+	service moderation.Service
+	admins  Admins
+}
+
+// NewModerationHandler creates a new content moderation handler
+func NewModerationHandler(service moderation.Service, admins Admins) *ModerationHandler {
+	return &ModerationHandler{
+		service: service,
+		admins:  admins,
+	}
+}
+
+// HandleSetVisibility sets a post or comment's visibility state
+// POST /xrpc/social.coves.admin.setContentVisibility
+// Body: { "subject": "at://did:plc:.../social.coves.community.post/...", "state": "author_only" }
+func (h *ModerationHandler) HandleSetVisibility(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req moderation.SetVisibilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.ActorDID = adminDID
+
+	if err := h.service.SetVisibility(r.Context(), req); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionSetContentVisibility, req.Subject, req)
+
+	writeJSONResponse(w, http.StatusOK, req)
+}
+
+// HandleSetThreadLock locks or unlocks a post's comment thread
+// POST /xrpc/social.coves.admin.setThreadLock
+// Body: { "subject": "at://did:plc:.../social.coves.community.post/...", "locked": true }
+func (h *ModerationHandler) HandleSetThreadLock(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req moderation.SetThreadLockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.ActorDID = adminDID
+
+	if err := h.service.SetThreadLock(r.Context(), req); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionSetThreadLock, req.Subject, req)
+
+	writeJSONResponse(w, http.StatusOK, req)
+}
+
+// HandleSetPostLabel applies, removes or clears a moderator override of a post's content label
+// POST /xrpc/social.coves.admin.setPostLabel
+// Body: { "subject": "at://did:plc:.../social.coves.community.post/...", "label": "nsfw", "state": "applied" }
+func (h *ModerationHandler) HandleSetPostLabel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req moderation.SetPostLabelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.ActorDID = adminDID
+
+	if err := h.service.SetPostLabel(r.Context(), req); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionSetPostLabel, req.Subject, req)
+
+	writeJSONResponse(w, http.StatusOK, req)
+}
+
+// HandleTakedownRecord takes down a post or comment instance-wide
+// POST /xrpc/social.coves.admin.takedownRecord
+// Body: { "subject": "at://did:plc:.../social.coves.community.post/...", "reason": "legal", "note": "..." }
+// Posts in community repos this instance provisioned are also deleted from the PDS.
+func (h *ModerationHandler) HandleTakedownRecord(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req moderation.TakedownRecordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.ActorDID = adminDID
+
+	result, err := h.service.TakedownRecord(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionTakedownRecord, req.Subject, result)
+
+	writeJSONResponse(w, http.StatusOK, result)
+}
+
+// HandleReverseTakedown reverses a post or comment's active takedown
+// POST /xrpc/social.coves.admin.reverseTakedown
+// Body: { "subject": "at://did:plc:.../social.coves.community.post/..." }
+func (h *ModerationHandler) HandleReverseTakedown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req moderation.ReverseTakedownRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.ActorDID = adminDID
+
+	result, err := h.service.ReverseTakedown(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionReverseTakedown, req.Subject, result)
+
+	writeJSONResponse(w, http.StatusOK, result)
+}
+
+// HandleGetCommentHistory returns a comment's prior versions, newest first
+// GET /xrpc/social.coves.moderation.getCommentHistory?uri=at://did:plc:.../social.coves.community.comment/...
+// Open to the moderators of the comment's community as well as instance admins.
+func (h *ModerationHandler) HandleGetCommentHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	uri := r.URL.Query().Get("uri")
+	if uri == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "uri is required")
+		return
+	}
+
+	history, err := h.service.GetCommentHistory(r.Context(), moderation.GetCommentHistoryRequest{
+		URI:      uri,
+		ActorDID: userDID,
+		IsAdmin:  h.admins.isAdmin(userDID),
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, history)
+}
+
+// HandleListBrigadeAlerts lists a community's open brigade alerts, newest first
+// GET /xrpc/social.coves.moderation.listBrigadeAlerts?community=did:plc:...&limit=50&cursor=0
+// Open to the community's moderators as well as instance admins.
+func (h *ModerationHandler) HandleListBrigadeAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	community := r.URL.Query().Get("community")
+	if community == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
+		return
+	}
+	limit, offset, ok := parseQueuePage(w, r)
+	if !ok {
+		return
+	}
+
+	alerts, err := h.service.ListBrigadeAlerts(r.Context(), moderation.ListBrigadeAlertsRequest{
+		CommunityDID: community,
+		ActorDID:     userDID,
+		IsAdmin:      h.admins.isAdmin(userDID),
+		Limit:        limit,
+		Offset:       offset,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	response := ListBrigadeAlertsResponse{Alerts: alerts}
+	if len(alerts) == limit {
+		response.Cursor = strconv.Itoa(offset + limit)
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// HandleReviewBrigadeAlert dismisses or confirms a brigade alert
+// POST /xrpc/social.coves.moderation.reviewBrigadeAlert
+// Body: { "id": 42, "status": "dismissed" }
+func (h *ModerationHandler) HandleReviewBrigadeAlert(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	var req moderation.ReviewBrigadeAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.ActorDID = userDID
+	req.IsAdmin = h.admins.isAdmin(userDID)
+
+	if err := h.service.ReviewBrigadeAlert(r.Context(), req); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, userDID, audit.ActionReviewBrigadeAlert, strconv.FormatInt(req.ID, 10), req)
+
+	writeJSONResponse(w, http.StatusOK, req)
+}
+
+// HandleGetAutomod returns a community's automod rules
+// GET /xrpc/social.coves.moderation.getAutomod?community=did:plc:...
+// Open to the community's moderators as well as instance admins.
+func (h *ModerationHandler) HandleGetAutomod(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	community := r.URL.Query().Get("community")
+	if community == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
+		return
+	}
+
+	config, err := h.service.GetAutomod(r.Context(), moderation.GetAutomodRequest{
+		CommunityDID: community,
+		ActorDID:     userDID,
+		IsAdmin:      h.admins.isAdmin(userDID),
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, config)
+}
+
+// HandleUpdateAutomod replaces a community's automod rules
+// POST /xrpc/social.coves.moderation.updateAutomod
+// Body: { "community": "did:plc:...", "rules": [{ "pattern": "spam.example", "matchType": "domain", "action": "remove" }] }
+func (h *ModerationHandler) HandleUpdateAutomod(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	var req moderation.UpdateAutomodRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.ActorDID = userDID
+	req.IsAdmin = h.admins.isAdmin(userDID)
+
+	config, err := h.service.UpdateAutomod(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, userDID, audit.ActionUpdateAutomod, req.CommunityDID, req)
+
+	writeJSONResponse(w, http.StatusOK, config)
+}
+
+// HandleListAutomodQueue lists a community's held and flagged content awaiting review, newest first
+// GET /xrpc/social.coves.moderation.listAutomodQueue?community=did:plc:...&limit=50&cursor=0
+// Open to the community's moderators as well as instance admins.
+func (h *ModerationHandler) HandleListAutomodQueue(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	community := r.URL.Query().Get("community")
+	if community == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
+		return
+	}
+	limit, offset, ok := parseQueuePage(w, r)
+	if !ok {
+		return
+	}
+
+	actions, err := h.service.ListAutomodQueue(r.Context(), moderation.ListAutomodQueueRequest{
+		CommunityDID: community,
+		ActorDID:     userDID,
+		IsAdmin:      h.admins.isAdmin(userDID),
+		Limit:        limit,
+		Offset:       offset,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	response := ListAutomodQueueResponse{Actions: actions}
+	if len(actions) == limit {
+		response.Cursor = strconv.Itoa(offset + limit)
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// HandleReviewAutomodAction approves or removes held or flagged content
+// POST /xrpc/social.coves.moderation.reviewAutomodAction
+// Body: { "id": 42, "status": "approved" }
+func (h *ModerationHandler) HandleReviewAutomodAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	var req moderation.ReviewAutomodActionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.ActorDID = userDID
+	req.IsAdmin = h.admins.isAdmin(userDID)
+
+	if err := h.service.ReviewAutomodAction(r.Context(), req); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, userDID, audit.ActionReviewAutomodAction, strconv.FormatInt(req.ID, 10), req)
+
+	writeJSONResponse(w, http.StatusOK, req)
+}
+
+// parseQueuePage reads a moderation queue's limit and offset cursor, writing an error response
+// if either is invalid
+func parseQueuePage(w http.ResponseWriter, r *http.Request) (limit, offset int, ok bool) {
+	limit = defaultModerationQueueLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 1 || parsed > maxModerationQueueLimit {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be between 1 and 100")
+			return 0, 0, false
+		}
+		limit = parsed
+	}
+	if s := r.URL.Query().Get("cursor"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 0 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidCursor, "Invalid cursor")
+			return 0, 0, false
+		}
+		offset = parsed
+	}
+	return limit, offset, true
+}
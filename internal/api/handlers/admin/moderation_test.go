@@ -0,0 +1,565 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/automod"
+	"Coves/internal/core/brigade"
+	"Coves/internal/core/moderation"
+	"Coves/internal/core/takedown"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockModerationService records visibility changes, thread locks and label overrides
+type mockModerationService struct {
+	requests []moderation.SetVisibilityRequest
+	locks    []moderation.SetThreadLockRequest
+	labels   []moderation.SetPostLabelRequest
+	history  []moderation.GetCommentHistoryRequest
+	reviews  []moderation.ReviewBrigadeAlertRequest
+	taken    []moderation.TakedownRecordRequest
+	reversed []moderation.ReverseTakedownRequest
+	automod  []moderation.UpdateAutomodRequest
+	triaged  []moderation.ReviewAutomodActionRequest
+}
+
+func (m *mockModerationService) SetVisibility(ctx context.Context, req moderation.SetVisibilityRequest) error {
+	switch {
+	case !req.State.IsValid():
+		return moderation.NewValidationError("state", "unknown state")
+	case req.Subject == "at://did:plc:a/social.coves.community.post/missing":
+		return moderation.ErrContentNotFound
+	}
+	m.requests = append(m.requests, req)
+	return nil
+}
+
+func (m *mockModerationService) SetThreadLock(ctx context.Context, req moderation.SetThreadLockRequest) error {
+	if req.Subject == "at://did:plc:a/social.coves.community.post/missing" {
+		return moderation.ErrContentNotFound
+	}
+	m.locks = append(m.locks, req)
+	return nil
+}
+
+func (m *mockModerationService) SetPostLabel(ctx context.Context, req moderation.SetPostLabelRequest) error {
+	if !req.State.IsValid() {
+		return moderation.NewValidationError("state", "unknown state")
+	}
+	m.labels = append(m.labels, req)
+	return nil
+}
+
+func (m *mockModerationService) GetCommentHistory(ctx context.Context, req moderation.GetCommentHistoryRequest) (*moderation.CommentHistory, error) {
+	m.history = append(m.history, req)
+	if !req.IsAdmin && req.ActorDID != "did:plc:mod" {
+		return nil, moderation.ErrNotModerator
+	}
+	return &moderation.CommentHistory{URI: req.URI, Revisions: []*moderation.Revision{{CID: "bafyold", Content: "before"}}}, nil
+}
+
+func (m *mockModerationService) ListBrigadeAlerts(ctx context.Context, req moderation.ListBrigadeAlertsRequest) ([]*brigade.Alert, error) {
+	if !req.IsAdmin && req.ActorDID != "did:plc:mod" {
+		return nil, moderation.ErrNotModerator
+	}
+	alerts := []*brigade.Alert{}
+	for i := 0; i < req.Limit && i < 3-req.Offset; i++ {
+		alerts = append(alerts, &brigade.Alert{ID: int64(req.Offset + i + 1), CommunityDID: req.CommunityDID, Status: brigade.AlertOpen})
+	}
+	return alerts, nil
+}
+
+func (m *mockModerationService) ReviewBrigadeAlert(ctx context.Context, req moderation.ReviewBrigadeAlertRequest) error {
+	switch {
+	case !req.Status.IsReview():
+		return moderation.NewValidationError("status", "unknown status")
+	case req.ID == 404:
+		return brigade.ErrAlertNotFound
+	case !req.IsAdmin && req.ActorDID != "did:plc:mod":
+		return moderation.ErrNotModerator
+	}
+	m.reviews = append(m.reviews, req)
+	return nil
+}
+
+func (m *mockModerationService) TakedownRecord(ctx context.Context, req moderation.TakedownRecordRequest) (*takedown.Takedown, error) {
+	switch {
+	case !req.Reason.IsValid():
+		return nil, moderation.NewValidationError("reason", "unknown reason")
+	case req.Subject == "at://did:plc:a/social.coves.community.post/missing":
+		return nil, takedown.ErrSubjectNotFound
+	}
+	m.taken = append(m.taken, req)
+	return &takedown.Takedown{ID: 1, SubjectURI: req.Subject, Reason: req.Reason, Note: req.Note, CreatedBy: req.ActorDID, UpstreamDeleted: true}, nil
+}
+
+func (m *mockModerationService) ReverseTakedown(ctx context.Context, req moderation.ReverseTakedownRequest) (*takedown.Takedown, error) {
+	if len(m.taken) == 0 {
+		return nil, takedown.ErrTakedownNotFound
+	}
+	m.reversed = append(m.reversed, req)
+	return &takedown.Takedown{ID: 1, SubjectURI: req.Subject, ReversedBy: req.ActorDID}, nil
+}
+
+func (m *mockModerationService) GetAutomod(ctx context.Context, req moderation.GetAutomodRequest) (*moderation.AutomodConfig, error) {
+	if !req.IsAdmin && req.ActorDID != "did:plc:mod" {
+		return nil, moderation.ErrNotModerator
+	}
+	return &moderation.AutomodConfig{CommunityDID: req.CommunityDID, Rules: []automod.Rule{}}, nil
+}
+
+func (m *mockModerationService) UpdateAutomod(ctx context.Context, req moderation.UpdateAutomodRequest) (*moderation.AutomodConfig, error) {
+	if !req.IsAdmin && req.ActorDID != "did:plc:mod" {
+		return nil, moderation.ErrNotModerator
+	}
+	if err := automod.ValidateRules(req.Rules); err != nil {
+		return nil, err
+	}
+	m.automod = append(m.automod, req)
+	return &moderation.AutomodConfig{CommunityDID: req.CommunityDID, Rules: req.Rules}, nil
+}
+
+func (m *mockModerationService) ListAutomodQueue(ctx context.Context, req moderation.ListAutomodQueueRequest) ([]*automod.ActionRecord, error) {
+	if !req.IsAdmin && req.ActorDID != "did:plc:mod" {
+		return nil, moderation.ErrNotModerator
+	}
+	records := []*automod.ActionRecord{}
+	for i := 0; i < req.Limit && i < 3-req.Offset; i++ {
+		records = append(records, &automod.ActionRecord{ID: int64(req.Offset + i + 1), CommunityDID: req.CommunityDID, Status: automod.ReviewOpen})
+	}
+	return records, nil
+}
+
+func (m *mockModerationService) ReviewAutomodAction(ctx context.Context, req moderation.ReviewAutomodActionRequest) error {
+	switch {
+	case !req.Status.IsReview():
+		return moderation.NewValidationError("status", "unknown status")
+	case req.ID == 404:
+		return automod.ErrActionNotFound
+	case !req.IsAdmin && req.ActorDID != "did:plc:mod":
+		return moderation.ErrNotModerator
+	}
+	m.triaged = append(m.triaged, req)
+	return nil
+}
+
+func TestModerationHandler_RequiresAdmin(t *testing.T) {
+	service := &mockModerationService{}
+	handler := NewModerationHandler(service, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleSetVisibility(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.setContentVisibility",
+		`{"subject":"at://did:plc:a/social.coves.community.post/x","state":"removed"}`, "did:plc:someone"))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(service.requests) != 0 {
+		t.Error("Non-admins must not change visibility")
+	}
+}
+
+func TestModerationHandler_SetVisibility(t *testing.T) {
+	service := &mockModerationService{}
+	handler := NewModerationHandler(service, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleSetVisibility(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.setContentVisibility",
+		`{"subject":"at://did:plc:a/social.coves.community.post/x","state":"author_only"}`, "did:plc:admin"))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(service.requests) != 1 {
+		t.Fatalf("Expected one visibility change, got %d", len(service.requests))
+	}
+	if req := service.requests[0]; req.State != moderation.VisibilityAuthorOnly || req.ActorDID != "did:plc:admin" {
+		t.Errorf("Expected author_only set by the calling admin, got %+v", req)
+	}
+}
+
+func TestModerationHandler_ErrorMapping(t *testing.T) {
+	handler := NewModerationHandler(&mockModerationService{}, NewAdmins([]string{"did:plc:admin"}))
+
+	tests := []struct {
+		name       string
+		body       string
+		wantError  string
+		wantStatus int
+	}{
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "unknown state", body: `{"subject":"at://did:plc:a/social.coves.community.post/x","state":"hidden"}`, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "unknown subject", body: `{"subject":"at://did:plc:a/social.coves.community.post/missing","state":"removed"}`, wantStatus: http.StatusNotFound, wantError: "NotFound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleSetVisibility(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.setContentVisibility", tt.body, "did:plc:admin"))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var resp xrpcerror.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode error: %v", err)
+			}
+			if resp.Error != tt.wantError {
+				t.Errorf("Expected error %s, got %s", tt.wantError, resp.Error)
+			}
+		})
+	}
+}
+
+func TestModerationHandler_SetThreadLock(t *testing.T) {
+	service := &mockModerationService{}
+	handler := NewModerationHandler(service, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleSetThreadLock(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.setThreadLock",
+		`{"subject":"at://did:plc:a/social.coves.community.post/x","locked":true}`, "did:plc:someone"))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403 for a non-admin, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleSetThreadLock(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.setThreadLock",
+		`{"subject":"at://did:plc:a/social.coves.community.post/x","locked":true}`, "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(service.locks) != 1 || !service.locks[0].Locked || service.locks[0].ActorDID != "did:plc:admin" {
+		t.Errorf("Expected one lock by the calling admin, got %+v", service.locks)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleSetThreadLock(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.setThreadLock",
+		`{"subject":"at://did:plc:a/social.coves.community.post/missing","locked":true}`, "did:plc:admin"))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown post, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestModerationHandler_SetPostLabel(t *testing.T) {
+	service := &mockModerationService{}
+	handler := NewModerationHandler(service, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleSetPostLabel(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.setPostLabel",
+		`{"subject":"at://did:plc:a/social.coves.community.post/x","label":"nsfw","state":"applied"}`, "did:plc:someone"))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403 for a non-admin, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleSetPostLabel(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.setPostLabel",
+		`{"subject":"at://did:plc:a/social.coves.community.post/x","label":"nsfw","state":"applied"}`, "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(service.labels) != 1 || service.labels[0].State != moderation.LabelApplied || service.labels[0].ActorDID != "did:plc:admin" {
+		t.Errorf("Expected one applied label by the calling admin, got %+v", service.labels)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleSetPostLabel(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.setPostLabel",
+		`{"subject":"at://did:plc:a/social.coves.community.post/x","label":"nsfw","state":"maybe"}`, "did:plc:admin"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an unknown state, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestModerationHandler_GetCommentHistory(t *testing.T) {
+	handler := NewModerationHandler(&mockModerationService{}, NewAdmins([]string{"did:plc:admin"}))
+	path := "/xrpc/social.coves.moderation.getCommentHistory?uri=at://did:plc:a/social.coves.community.comment/c"
+
+	tests := []struct {
+		name       string
+		userDID    string
+		wantError  string
+		wantStatus int
+	}{
+		{name: "instance admin", userDID: "did:plc:admin", wantStatus: http.StatusOK},
+		{name: "community moderator", userDID: "did:plc:mod", wantStatus: http.StatusOK},
+		{name: "other user", userDID: "did:plc:someone", wantStatus: http.StatusForbidden, wantError: "Forbidden"},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized, wantError: "AuthRequired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleGetCommentHistory(w, newAdminRequest(http.MethodGet, path, "", tt.userDID))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantError != "" {
+				var resp xrpcerror.Response
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("Failed to decode error: %v", err)
+				}
+				if resp.Error != tt.wantError {
+					t.Errorf("Expected error %s, got %s", tt.wantError, resp.Error)
+				}
+				return
+			}
+			var history moderation.CommentHistory
+			if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+				t.Fatalf("Failed to decode history: %v", err)
+			}
+			if len(history.Revisions) != 1 || history.Revisions[0].Content != "before" {
+				t.Errorf("Unexpected history: %+v", history)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	handler.HandleGetCommentHistory(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.moderation.getCommentHistory", "", "did:plc:admin"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a uri, got %d", w.Code)
+	}
+}
+
+func TestModerationHandler_ListBrigadeAlerts(t *testing.T) {
+	handler := NewModerationHandler(&mockModerationService{}, NewAdmins([]string{"did:plc:admin"}))
+	path := "/xrpc/social.coves.moderation.listBrigadeAlerts?community=did:plc:community&limit=2"
+
+	tests := []struct {
+		name       string
+		path       string
+		userDID    string
+		wantCursor string
+		wantAlerts int
+		wantStatus int
+	}{
+		{name: "community moderator", path: path, userDID: "did:plc:mod", wantStatus: http.StatusOK, wantAlerts: 2, wantCursor: "2"},
+		{name: "last page", path: path + "&cursor=2", userDID: "did:plc:admin", wantStatus: http.StatusOK, wantAlerts: 1},
+		{name: "other user", path: path, userDID: "did:plc:someone", wantStatus: http.StatusForbidden},
+		{name: "anonymous", path: path, wantStatus: http.StatusUnauthorized},
+		{name: "missing community", path: "/xrpc/social.coves.moderation.listBrigadeAlerts", userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+		{name: "bad limit", path: "/xrpc/social.coves.moderation.listBrigadeAlerts?community=did:plc:community&limit=101", userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleListBrigadeAlerts(w, newAdminRequest(http.MethodGet, tt.path, "", tt.userDID))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			var resp ListBrigadeAlertsResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode alerts: %v", err)
+			}
+			if len(resp.Alerts) != tt.wantAlerts || resp.Cursor != tt.wantCursor {
+				t.Errorf("Expected %d alerts with cursor %q, got %d with %q", tt.wantAlerts, tt.wantCursor, len(resp.Alerts), resp.Cursor)
+			}
+		})
+	}
+}
+
+func TestModerationHandler_ReviewBrigadeAlert(t *testing.T) {
+	service := &mockModerationService{}
+	handler := NewModerationHandler(service, NewAdmins([]string{"did:plc:admin"}))
+	path := "/xrpc/social.coves.moderation.reviewBrigadeAlert"
+
+	tests := []struct {
+		name       string
+		body       string
+		userDID    string
+		wantStatus int
+	}{
+		{name: "community moderator", body: `{"id":1,"status":"confirmed"}`, userDID: "did:plc:mod", wantStatus: http.StatusOK},
+		{name: "instance admin", body: `{"id":2,"status":"dismissed"}`, userDID: "did:plc:admin", wantStatus: http.StatusOK},
+		{name: "other user", body: `{"id":1,"status":"dismissed"}`, userDID: "did:plc:someone", wantStatus: http.StatusForbidden},
+		{name: "unknown alert", body: `{"id":404,"status":"dismissed"}`, userDID: "did:plc:mod", wantStatus: http.StatusNotFound},
+		{name: "unknown status", body: `{"id":1,"status":"open"}`, userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+		{name: "anonymous", body: `{"id":1,"status":"dismissed"}`, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleReviewBrigadeAlert(w, newAdminRequest(http.MethodPost, path, tt.body, tt.userDID))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if len(service.reviews) != 2 || !service.reviews[1].IsAdmin || service.reviews[0].ActorDID != "did:plc:mod" {
+		t.Errorf("Expected the moderator's and the admin's reviews, got %+v", service.reviews)
+	}
+}
+
+func TestModerationHandler_UpdateAutomod(t *testing.T) {
+	service := &mockModerationService{}
+	handler := NewModerationHandler(service, NewAdmins([]string{"did:plc:admin"}))
+	path := "/xrpc/social.coves.moderation.updateAutomod"
+	rules := `[{"pattern":"spam.example","matchType":"domain","action":"remove"},{"pattern":"buy now","matchType":"keyword","action":"hold"}]`
+
+	tests := []struct {
+		name       string
+		body       string
+		userDID    string
+		wantStatus int
+	}{
+		{name: "community moderator", body: `{"community":"did:plc:community","rules":` + rules + `}`, userDID: "did:plc:mod", wantStatus: http.StatusOK},
+		{name: "instance admin clears rules", body: `{"community":"did:plc:community","rules":[]}`, userDID: "did:plc:admin", wantStatus: http.StatusOK},
+		{name: "other user", body: `{"community":"did:plc:community","rules":[]}`, userDID: "did:plc:someone", wantStatus: http.StatusForbidden},
+		{name: "unknown action", body: `{"community":"did:plc:community","rules":[{"pattern":"x","matchType":"keyword","action":"ban"}]}`, userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+		{name: "unsafe regex", body: `{"community":"did:plc:community","rules":[{"pattern":"(a+)+","matchType":"regex-lite","action":"flag"}]}`, userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+		{name: "anonymous", body: `{"community":"did:plc:community","rules":[]}`, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleUpdateAutomod(w, newAdminRequest(http.MethodPost, path, tt.body, tt.userDID))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if len(service.automod) != 2 || len(service.automod[0].Rules) != 2 || !service.automod[1].IsAdmin {
+		t.Errorf("Expected the moderator's and the admin's updates, got %+v", service.automod)
+	}
+}
+
+func TestModerationHandler_AutomodQueue(t *testing.T) {
+	service := &mockModerationService{}
+	handler := NewModerationHandler(service, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleListAutomodQueue(w, newAdminRequest(http.MethodGet,
+		"/xrpc/social.coves.moderation.listAutomodQueue?community=did:plc:community&limit=2", "", "did:plc:mod"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ListAutomodQueueResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode queue: %v", err)
+	}
+	if len(resp.Actions) != 2 || resp.Cursor != "2" {
+		t.Errorf("Expected 2 actions with cursor 2, got %d with %q", len(resp.Actions), resp.Cursor)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleListAutomodQueue(w, newAdminRequest(http.MethodGet,
+		"/xrpc/social.coves.moderation.listAutomodQueue?community=did:plc:community", "", "did:plc:someone"))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status 403 for a non-moderator, got %d", w.Code)
+	}
+
+	reviews := []struct {
+		body       string
+		userDID    string
+		wantStatus int
+	}{
+		{body: `{"id":1,"status":"approved"}`, userDID: "did:plc:mod", wantStatus: http.StatusOK},
+		{body: `{"id":404,"status":"removed"}`, userDID: "did:plc:mod", wantStatus: http.StatusNotFound},
+		{body: `{"id":1,"status":"open"}`, userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+		{body: `{"id":1,"status":"removed"}`, userDID: "did:plc:someone", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range reviews {
+		w := httptest.NewRecorder()
+		handler.HandleReviewAutomodAction(w, newAdminRequest(http.MethodPost,
+			"/xrpc/social.coves.moderation.reviewAutomodAction", tt.body, tt.userDID))
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s by %s: expected status %d, got %d: %s", tt.body, tt.userDID, tt.wantStatus, w.Code, w.Body.String())
+		}
+	}
+	if len(service.triaged) != 1 || service.triaged[0].Status != automod.ReviewApproved {
+		t.Errorf("Expected one approval, got %+v", service.triaged)
+	}
+}
+
+func TestModerationHandler_TakedownRecord(t *testing.T) {
+	service := &mockModerationService{}
+	handler := NewModerationHandler(service, NewAdmins([]string{"did:plc:admin"}))
+	body := `{"subject":"at://did:plc:a/social.coves.community.post/x","reason":"legal","note":"court order"}`
+
+	// Non-admins can neither take down nor reverse
+	w := httptest.NewRecorder()
+	handler.HandleTakedownRecord(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.takedownRecord", body, "did:plc:someone"))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403 for a non-admin, got %d: %s", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	handler.HandleReverseTakedown(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.reverseTakedown",
+		`{"subject":"at://did:plc:a/social.coves.community.post/x"}`, "did:plc:someone"))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403 for a non-admin reversal, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(service.taken) != 0 || len(service.reversed) != 0 {
+		t.Fatal("Non-admins must not take down or reverse")
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleTakedownRecord(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.takedownRecord", body, "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var result takedown.Takedown
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if result.Reason != takedown.ReasonLegal || result.CreatedBy != "did:plc:admin" || !result.UpstreamDeleted {
+		t.Errorf("Expected the takedown by the calling admin, got %+v", result)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleReverseTakedown(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.reverseTakedown",
+		`{"subject":"at://did:plc:a/social.coves.community.post/x"}`, "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(service.reversed) != 1 || service.reversed[0].ActorDID != "did:plc:admin" {
+		t.Errorf("Expected the reversal attributed to the calling admin, got %+v", service.reversed)
+	}
+}
+
+func TestModerationHandler_TakedownErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantError  string
+		wantStatus int
+		reverse    bool
+	}{
+		{name: "unknown reason", body: `{"subject":"at://did:plc:a/social.coves.community.post/x","reason":"dislike"}`, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "unindexed subject", body: `{"subject":"at://did:plc:a/social.coves.community.post/missing","reason":"spam"}`, wantStatus: http.StatusNotFound, wantError: "NotFound"},
+		{name: "no active takedown", body: `{"subject":"at://did:plc:a/social.coves.community.post/x"}`, wantStatus: http.StatusNotFound, wantError: "NotFound", reverse: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewModerationHandler(&mockModerationService{}, NewAdmins([]string{"did:plc:admin"}))
+			w := httptest.NewRecorder()
+			if tt.reverse {
+				handler.HandleReverseTakedown(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.reverseTakedown", tt.body, "did:plc:admin"))
+			} else {
+				handler.HandleTakedownRecord(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.takedownRecord", tt.body, "did:plc:admin"))
+			}
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var resp xrpcerror.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode error: %v", err)
+			}
+			if resp.Error != tt.wantError {
+				t.Errorf("Expected error %s, got %s", tt.wantError, resp.Error)
+			}
+		})
+	}
+}
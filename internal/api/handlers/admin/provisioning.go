@@ -0,0 +1,109 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/communities"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultStuckProvisioningsLimit = 50
+	maxStuckProvisioningsLimit     = 100
+)
+
+// Provisionings lists and cleans up community creations that stopped partway
+// Implemented by the community service.
+type Provisionings interface {
+	ListStuckProvisionings(ctx context.Context, limit int) ([]*communities.Provisioning, error)
+	CleanupProvisioning(ctx context.Context, name string) error
+}
+
+// ProvisioningHandler lets instance admins clean up community creations that never finished
+type ProvisioningHandler struct {
+	provisionings Provisionings
+	admins        Admins
+}
+
+// NewProvisioningHandler creates a new stuck provisioning handler
+func NewProvisioningHandler(provisionings Provisionings, admins Admins) *ProvisioningHandler {
+	return &ProvisioningHandler{
+		provisionings: provisionings,
+		admins:        admins,
+	}
+}
+
+// StuckProvisioningsResponse is the response for social.coves.admin.listStuckProvisionings
+type StuckProvisioningsResponse struct {
+	Provisionings []*communities.Provisioning `json:"provisionings"`
+}
+
+// CleanupProvisioningRequest is the body for social.coves.admin.cleanupProvisioning
+type CleanupProvisioningRequest struct {
+	Name string `json:"name"`
+}
+
+// HandleList lists creations unfinished for more than a day, oldest first
+// GET /xrpc/social.coves.admin.listStuckProvisionings?limit=50
+func (h *ProvisioningHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	limit := defaultStuckProvisioningsLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 1 || parsed > maxStuckProvisioningsLimit {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+
+	provisionings, err := h.provisionings.ListStuckProvisionings(r.Context(), limit)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, StuckProvisioningsResponse{Provisionings: provisionings})
+}
+
+// HandleCleanup deletes a stuck creation's orphaned PDS account and frees its name
+// POST /xrpc/social.coves.admin.cleanupProvisioning
+// Body: { "name": "gardening" }
+func (h *ProvisioningHandler) HandleCleanup(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req CleanupProvisioningRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	if req.Name == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "name is required")
+		return
+	}
+
+	if err := h.provisionings.CleanupProvisioning(r.Context(), req.Name); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionCleanupProvisioning, req.Name, nil)
+
+	writeJSONResponse(w, http.StatusOK, map[string]interface{}{})
+}
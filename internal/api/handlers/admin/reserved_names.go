@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/communities"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// ReservedNames manages community names no user may create
+// Implemented by *communities.CreationPolicy
+type ReservedNames interface {
+	ListReservedNames(ctx context.Context) ([]*communities.ReservedName, error)
+	AddReservedName(ctx context.Context, name, reason, adminDID string) (*communities.ReservedName, error)
+	RemoveReservedName(ctx context.Context, name string) error
+}
+
+// ReservedNamesHandler lets instance admins manage reserved community names
+type ReservedNamesHandler struct {
+	names  ReservedNames
+	admins Admins
+}
+
+// NewReservedNamesHandler creates a new reserved community names handler
+func NewReservedNamesHandler(names ReservedNames, admins Admins) *ReservedNamesHandler {
+	return &ReservedNamesHandler{
+		names:  names,
+		admins: admins,
+	}
+}
+
+// ReservedNamesResponse is the response for social.coves.admin.listReservedCommunityNames
+type ReservedNamesResponse struct {
+	Names []*communities.ReservedName `json:"names"`
+}
+
+// AddReservedNameRequest is the body for social.coves.admin.addReservedCommunityName
+type AddReservedNameRequest struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// RemoveReservedNameRequest is the body for social.coves.admin.removeReservedCommunityName
+type RemoveReservedNameRequest struct {
+	Name string `json:"name"`
+}
+
+// HandleList lists built-in and admin-reserved community names
+// GET /xrpc/social.coves.admin.listReservedCommunityNames
+func (h *ReservedNamesHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	names, err := h.names.ListReservedNames(r.Context())
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, ReservedNamesResponse{Names: names})
+}
+
+// HandleAdd reserves a community name
+// POST /xrpc/social.coves.admin.addReservedCommunityName
+// Body: { "name": "announcements", "reason": "Reserved for instance news" }
+func (h *ReservedNamesHandler) HandleAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req AddReservedNameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+
+	reserved, err := h.names.AddReservedName(r.Context(), req.Name, req.Reason, adminDID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionAddReservedName, reserved.Name, reserved)
+
+	writeJSONResponse(w, http.StatusOK, reserved)
+}
+
+// HandleRemove releases an admin-reserved community name
+// POST /xrpc/social.coves.admin.removeReservedCommunityName
+// Body: { "name": "announcements" }
+func (h *ReservedNamesHandler) HandleRemove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req RemoveReservedNameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+
+	if err := h.names.RemoveReservedName(r.Context(), req.Name); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	h.admins.record(r, adminDID, audit.ActionRemoveReservedName, req.Name, nil)
+
+	writeJSONResponse(w, http.StatusOK, map[string]interface{}{})
+}
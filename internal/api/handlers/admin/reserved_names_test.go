@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/communities"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockReservedNames records reserved name changes
+type mockReservedNames struct {
+	added []communities.ReservedName
+}
+
+func (m *mockReservedNames) ListReservedNames(ctx context.Context) ([]*communities.ReservedName, error) {
+	return []*communities.ReservedName{{Name: "admin", BuiltIn: true}}, nil
+}
+
+func (m *mockReservedNames) AddReservedName(ctx context.Context, name, reason, adminDID string) (*communities.ReservedName, error) {
+	if name == "Bad_Name" {
+		return nil, communities.NewValidationError("name", communities.ErrInvalidCommunityName.Error())
+	}
+	reserved := communities.ReservedName{Name: name, Reason: reason, AddedBy: adminDID}
+	m.added = append(m.added, reserved)
+	return &reserved, nil
+}
+
+func (m *mockReservedNames) RemoveReservedName(ctx context.Context, name string) error {
+	switch name {
+	case "admin":
+		return communities.ErrReservedNameBuiltIn
+	case "missing":
+		return communities.ErrReservedNameNotFound
+	}
+	return nil
+}
+
+func TestReservedNamesHandler_RequiresAdmin(t *testing.T) {
+	handler := NewReservedNamesHandler(&mockReservedNames{}, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleAdd(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.addReservedCommunityName",
+		`{"name":"news"}`, "did:plc:someone"))
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestReservedNamesHandler_Add(t *testing.T) {
+	names := &mockReservedNames{}
+	handler := NewReservedNamesHandler(names, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleAdd(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.addReservedCommunityName",
+		`{"name":"news","reason":"instance announcements"}`, "did:plc:admin"))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(names.added) != 1 || names.added[0].AddedBy != "did:plc:admin" {
+		t.Errorf("Expected name reserved by the calling admin, got %+v", names.added)
+	}
+}
+
+func TestReservedNamesHandler_ErrorMapping(t *testing.T) {
+	handler := NewReservedNamesHandler(&mockReservedNames{}, NewAdmins([]string{"did:plc:admin"}))
+
+	tests := []struct {
+		handle     http.HandlerFunc
+		name       string
+		body       string
+		wantError  string
+		wantStatus int
+	}{
+		{name: "invalid name", handle: handler.HandleAdd, body: `{"name":"Bad_Name"}`, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "remove built-in", handle: handler.HandleRemove, body: `{"name":"admin"}`, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "remove unknown", handle: handler.HandleRemove, body: `{"name":"missing"}`, wantStatus: http.StatusNotFound, wantError: "ReservedNameNotFound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handle(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.test", tt.body, "did:plc:admin"))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var resp xrpcerror.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode error: %v", err)
+			}
+			if resp.Error != tt.wantError {
+				t.Errorf("Expected error %s, got %s", tt.wantError, resp.Error)
+			}
+		})
+	}
+}
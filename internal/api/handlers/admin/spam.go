@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/spamguard"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// Review actions for social.coves.admin.reviewQuarantinedPost
+const (
+	SpamActionRelease = "release" // Not spam: make the post visible
+	SpamActionConfirm = "confirm" // Spam: keep the post hidden and drop it from the queue
+)
+
+const (
+	defaultQuarantinedPostsLimit = 50
+	maxQuarantinedPostsLimit     = 100
+)
+
+// SpamHandler lets instance admins review posts the spam guard quarantined at index time
+type SpamHandler struct {
+	repo   spamguard.QueueRepository
+	admins Admins
+}
+
+// NewSpamHandler creates a new spam review handler
+func NewSpamHandler(repo spamguard.QueueRepository, admins Admins) *SpamHandler {
+	return &SpamHandler{
+		repo:   repo,
+		admins: admins,
+	}
+}
+
+// ListQuarantinedPostsResponse is the response for social.coves.admin.listQuarantinedPosts
+// Cursor is the offset of the next page, omitted on the last page
+type ListQuarantinedPostsResponse struct {
+	Cursor string                       `json:"cursor,omitempty"`
+	Posts  []*spamguard.QuarantinedPost `json:"posts"`
+}
+
+// ReviewQuarantinedPostRequest is the body for social.coves.admin.reviewQuarantinedPost
+type ReviewQuarantinedPostRequest struct {
+	Post   string `json:"post"`
+	Action string `json:"action"`
+}
+
+// HandleList lists quarantined posts awaiting review, most recently indexed first
+// GET /xrpc/social.coves.admin.listQuarantinedPosts?limit=50&cursor=0
+func (h *SpamHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if _, ok := h.admins.authorize(w, r); !ok {
+		return
+	}
+
+	limit := defaultQuarantinedPostsLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 1 || parsed > maxQuarantinedPostsLimit {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+	offset := 0
+	if s := r.URL.Query().Get("cursor"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 0 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidCursor, "Invalid cursor")
+			return
+		}
+		offset = parsed
+	}
+
+	quarantined, err := h.repo.ListQuarantined(r.Context(), limit, offset)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	response := ListQuarantinedPostsResponse{Posts: quarantined}
+	if len(quarantined) == limit {
+		response.Cursor = strconv.Itoa(offset + limit)
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// HandleReview releases a quarantined post or confirms it as spam
+// POST /xrpc/social.coves.admin.reviewQuarantinedPost
+// Body: { "post": "at://did:plc:.../social.coves.community.post/...", "action": "release" }
+func (h *SpamHandler) HandleReview(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	var req ReviewQuarantinedPostRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	if req.Post == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "post is required")
+		return
+	}
+
+	var err error
+	switch req.Action {
+	case SpamActionRelease:
+		err = h.repo.Release(r.Context(), req.Post)
+	case SpamActionConfirm:
+		err = h.repo.Confirm(r.Context(), req.Post)
+	default:
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "action must be release or confirm")
+		return
+	}
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	log.Printf("Admin %s reviewed quarantined post %s: %s", adminDID, req.Post, req.Action)
+	h.admins.record(r, adminDID, audit.ActionReviewQuarantinedPost, req.Post, req)
+	writeJSONResponse(w, http.StatusOK, req)
+}
@@ -0,0 +1,125 @@
+package admin
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/spamguard"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockSpamQueue holds quarantined posts by URI and records review actions
+type mockSpamQueue struct {
+	quarantined []*spamguard.QuarantinedPost
+	released    []string
+	confirmed   []string
+}
+
+func (m *mockSpamQueue) ListQuarantined(ctx context.Context, limit, offset int) ([]*spamguard.QuarantinedPost, error) {
+	if offset >= len(m.quarantined) {
+		return []*spamguard.QuarantinedPost{}, nil
+	}
+	return m.quarantined[offset:min(offset+limit, len(m.quarantined))], nil
+}
+
+func (m *mockSpamQueue) Release(ctx context.Context, uri string) error {
+	if !m.has(uri) {
+		return spamguard.ErrPostNotQuarantined
+	}
+	m.released = append(m.released, uri)
+	return nil
+}
+
+func (m *mockSpamQueue) Confirm(ctx context.Context, uri string) error {
+	if !m.has(uri) {
+		return spamguard.ErrPostNotQuarantined
+	}
+	m.confirmed = append(m.confirmed, uri)
+	return nil
+}
+
+func (m *mockSpamQueue) has(uri string) bool {
+	for _, post := range m.quarantined {
+		if post.URI == uri {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSpamHandler_List(t *testing.T) {
+	queue := &mockSpamQueue{quarantined: []*spamguard.QuarantinedPost{
+		{URI: "at://did:plc:c/social.coves.community.post/1", Reasons: []string{spamguard.ReasonNewAccount, spamguard.ReasonRepeatedContent}},
+		{URI: "at://did:plc:c/social.coves.community.post/2", Reasons: []string{spamguard.ReasonHighRate}},
+	}}
+	handler := NewSpamHandler(queue, NewAdmins([]string{"did:plc:admin"}))
+
+	w := httptest.NewRecorder()
+	handler.HandleList(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listQuarantinedPosts?limit=1", "", "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp ListQuarantinedPostsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Posts) != 1 || len(resp.Posts[0].Reasons) != 2 {
+		t.Errorf("Expected the first quarantined post with its reasons, got %+v", resp.Posts)
+	}
+	if resp.Cursor != "1" {
+		t.Errorf("Expected cursor 1, got %q", resp.Cursor)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleList(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.admin.listQuarantinedPosts", "", "did:plc:someone"))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected non-admins to get 403, got %d", w.Code)
+	}
+}
+
+func TestSpamHandler_Review(t *testing.T) {
+	post := "at://did:plc:c/social.coves.community.post/1"
+	queue := &mockSpamQueue{quarantined: []*spamguard.QuarantinedPost{{URI: post}}}
+	handler := NewSpamHandler(queue, NewAdmins([]string{"did:plc:admin"}))
+
+	tests := []struct {
+		name       string
+		body       string
+		wantError  string
+		wantStatus int
+	}{
+		{name: "release", body: `{"post":"` + post + `","action":"release"}`, wantStatus: http.StatusOK},
+		{name: "confirm", body: `{"post":"` + post + `","action":"confirm"}`, wantStatus: http.StatusOK},
+		{name: "unknown action", body: `{"post":"` + post + `","action":"ignore"}`, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "missing post", body: `{"action":"release"}`, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "not quarantined", body: `{"post":"at://did:plc:c/social.coves.community.post/other","action":"release"}`, wantStatus: http.StatusNotFound, wantError: "NotFound"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleReview(w, newAdminRequest(http.MethodPost, "/xrpc/social.coves.admin.reviewQuarantinedPost", tt.body, "did:plc:admin"))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantError == "" {
+				return
+			}
+			var resp xrpcerror.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode error: %v", err)
+			}
+			if resp.Error != tt.wantError {
+				t.Errorf("Expected error %s, got %s", tt.wantError, resp.Error)
+			}
+		})
+	}
+
+	if len(queue.released) != 1 || len(queue.confirmed) != 1 {
+		t.Errorf("Expected one release and one confirmation, got %v and %v", queue.released, queue.confirmed)
+	}
+}
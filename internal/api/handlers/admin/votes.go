@@ -0,0 +1,176 @@
+package admin
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/votes"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// VotesHandler handles admin vote nullification for abusive accounts, and lists who voted on a
+// subject for the callers the votes access policy allows
+type VotesHandler struct {
+	repo   votes.Repository
+	policy *votes.AccessPolicy
+	admins Admins
+}
+
+// NewVotesHandler creates a new admin votes handler
+func NewVotesHandler(repo votes.Repository, policy *votes.AccessPolicy, admins Admins) *VotesHandler {
+	return &VotesHandler{
+		repo:   repo,
+		policy: policy,
+		admins: admins,
+	}
+}
+
+// GetVotersResponse is the response for social.coves.moderation.getVoters
+type GetVotersResponse struct {
+	Cursor  string         `json:"cursor,omitempty"`
+	Subject string         `json:"subject"`
+	Voters  []*votes.Voter `json:"voters"`
+}
+
+// VoterRequest is the body for social.coves.admin.nullifyVotes and restoreVotes
+type VoterRequest struct {
+	DID string `json:"did"`
+}
+
+// NullifyVotesResponse is the response for social.coves.admin.nullifyVotes
+type NullifyVotesResponse struct {
+	DID       string `json:"did"`
+	Nullified int    `json:"nullified"`
+}
+
+// RestoreVotesResponse is the response for social.coves.admin.restoreVotes
+type RestoreVotesResponse struct {
+	DID      string `json:"did"`
+	Restored int    `json:"restored"`
+}
+
+// HandleNullifyVotes neutralizes every vote cast by an account and corrects scores
+// POST /xrpc/social.coves.admin.nullifyVotes
+// Body: { "did": "did:plc:..." }
+func (h *VotesHandler) HandleNullifyVotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	voterDID, ok := decodeVoterRequest(w, r)
+	if !ok {
+		return
+	}
+
+	// Finish the batches even if the admin's client disconnects; a partial run
+	// leaves the voter nullified and is completed by calling the endpoint again
+	nullified, err := h.repo.RemoveVotesByVoter(context.WithoutCancel(r.Context()), voterDID, votes.NullifiedByAdmin)
+	if err != nil {
+		log.Printf("ERROR: Failed to nullify votes for %s: %v", voterDID, err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to nullify votes")
+		return
+	}
+
+	log.Printf("Admin %s nullified %d votes by %s", adminDID, nullified, voterDID)
+	h.admins.record(r, adminDID, audit.ActionNullifyVotes, voterDID, NullifyVotesResponse{DID: voterDID, Nullified: nullified})
+	writeJSONResponse(w, http.StatusOK, NullifyVotesResponse{DID: voterDID, Nullified: nullified})
+}
+
+// HandleRestoreVotes reverses social.coves.admin.nullifyVotes (or an account takedown)
+// POST /xrpc/social.coves.admin.restoreVotes
+// Body: { "did": "did:plc:..." }
+func (h *VotesHandler) HandleRestoreVotes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	adminDID, ok := h.admins.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	voterDID, ok := decodeVoterRequest(w, r)
+	if !ok {
+		return
+	}
+
+	restored, err := h.repo.RestoreVotesByVoter(context.WithoutCancel(r.Context()), voterDID)
+	if err != nil {
+		log.Printf("ERROR: Failed to restore votes for %s: %v", voterDID, err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to restore votes")
+		return
+	}
+
+	log.Printf("Admin %s restored %d votes by %s", adminDID, restored, voterDID)
+	h.admins.record(r, adminDID, audit.ActionRestoreVotes, voterDID, RestoreVotesResponse{DID: voterDID, Restored: restored})
+	writeJSONResponse(w, http.StatusOK, RestoreVotesResponse{DID: voterDID, Restored: restored})
+}
+
+// HandleGetVoters lists who voted on a post or comment, newest first
+// GET /xrpc/social.coves.moderation.getVoters?subject=at://...&limit=50&cursor=0
+// Open to the subject's author and its community's moderators as well as instance admins;
+// everyone else only ever sees vote counts.
+func (h *VotesHandler) HandleGetVoters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	subject := r.URL.Query().Get("subject")
+	if subject == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "subject is required")
+		return
+	}
+	limit, offset, ok := parseQueuePage(w, r)
+	if !ok {
+		return
+	}
+
+	access, err := h.policy.AuthorizeVoters(r.Context(), userDID, subject)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+	voters, err := h.repo.ListVotersForSubject(r.Context(), access, limit, offset)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	resp := GetVotersResponse{Subject: subject, Voters: voters}
+	if len(voters) == limit {
+		resp.Cursor = strconv.Itoa(offset + limit)
+	}
+	writeJSONResponse(w, http.StatusOK, resp)
+}
+
+// decodeVoterRequest parses and validates a VoterRequest, writing an error response if invalid
+func decodeVoterRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var req VoterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return "", false
+	}
+
+	did := strings.TrimSpace(req.DID)
+	if !strings.HasPrefix(did, "did:") {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "did must be a valid DID")
+		return "", false
+	}
+	return did, true
+}
@@ -0,0 +1,141 @@
+package admin
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/votes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const votersSubject = "at://did:plc:community/social.coves.community.post/3kpost"
+
+// mockVoterRepo indexes one post with two votes; the rest of votes.Repository is unused
+type mockVoterRepo struct {
+	votes.Repository
+	ownerLookups int
+}
+
+func (m *mockVoterRepo) GetSubjectOwner(ctx context.Context, subjectURI string) (*votes.SubjectOwner, error) {
+	m.ownerLookups++
+	if subjectURI != votersSubject {
+		return nil, votes.ErrSubjectNotFound
+	}
+	return &votes.SubjectOwner{AuthorDID: "did:plc:author", CommunityDID: "did:plc:community"}, nil
+}
+
+func (m *mockVoterRepo) ListVotersForSubject(ctx context.Context, access votes.VoterAccess, limit, offset int) ([]*votes.Voter, error) {
+	if access.SubjectURI() == "" {
+		return nil, votes.ErrVotersForbidden
+	}
+	voters := []*votes.Voter{
+		{DID: "did:plc:voter1", Direction: "up", CreatedAt: time.Now()},
+		{DID: "did:plc:voter2", Direction: "down", CreatedAt: time.Now()},
+	}
+	if offset >= len(voters) {
+		return []*votes.Voter{}, nil
+	}
+	return voters[offset:min(offset+limit, len(voters))], nil
+}
+
+type mockModeratorChecker map[string]bool
+
+func (m mockModeratorChecker) IsCommunityModerator(ctx context.Context, communityDID, actorDID string) (bool, error) {
+	return communityDID == "did:plc:community" && m[actorDID], nil
+}
+
+func newTestVotesHandler() (*VotesHandler, *mockVoterRepo) {
+	repo := &mockVoterRepo{}
+	policy := votes.NewAccessPolicy(repo, mockModeratorChecker{"did:plc:mod": true}, []string{"did:plc:admin"})
+	return NewVotesHandler(repo, policy, NewAdmins([]string{"did:plc:admin"})), repo
+}
+
+func TestVotesHandler_GetVotersAccess(t *testing.T) {
+	tests := []struct {
+		name       string
+		userDID    string
+		subject    string
+		wantStatus int
+	}{
+		{name: "anonymous", userDID: "", subject: votersSubject, wantStatus: http.StatusUnauthorized},
+		{name: "regular user", userDID: "did:plc:someone", subject: votersSubject, wantStatus: http.StatusForbidden},
+		{name: "voter on the subject", userDID: "did:plc:voter1", subject: votersSubject, wantStatus: http.StatusForbidden},
+		{name: "moderator of another community's subject", userDID: "did:plc:mod", subject: "at://did:plc:other/social.coves.community.post/3kx", wantStatus: http.StatusNotFound},
+		{name: "author", userDID: "did:plc:author", subject: votersSubject, wantStatus: http.StatusOK},
+		{name: "community moderator", userDID: "did:plc:mod", subject: votersSubject, wantStatus: http.StatusOK},
+		{name: "admin", userDID: "did:plc:admin", subject: votersSubject, wantStatus: http.StatusOK},
+		{name: "missing subject", userDID: "did:plc:admin", subject: "", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _ := newTestVotesHandler()
+			w := httptest.NewRecorder()
+			handler.HandleGetVoters(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.moderation.getVoters?subject="+tt.subject, "", tt.userDID))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK && strings.Contains(w.Body.String(), "did:plc:voter") {
+				t.Errorf("rejected response leaked voter DIDs: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestVotesHandler_GetVoters(t *testing.T) {
+	handler, repo := newTestVotesHandler()
+
+	w := httptest.NewRecorder()
+	handler.HandleGetVoters(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.moderation.getVoters?limit=1&subject="+votersSubject, "", "did:plc:admin"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp GetVotersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Subject != votersSubject || len(resp.Voters) != 1 || resp.Voters[0].DID != "did:plc:voter1" || resp.Cursor != "1" {
+		t.Errorf("unexpected first page: %+v", resp)
+	}
+	if repo.ownerLookups != 0 {
+		t.Errorf("admin access looked up the subject %d times, want 0", repo.ownerLookups)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleGetVoters(w, newAdminRequest(http.MethodGet, "/xrpc/social.coves.moderation.getVoters?limit=1&cursor=1&subject="+votersSubject, "", "did:plc:author"))
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Voters) != 1 || resp.Voters[0].DID != "did:plc:voter2" || resp.Voters[0].Direction != "down" {
+		t.Errorf("unexpected second page: %+v", resp)
+	}
+}
+
+// TestVoteCountViews_ExposeNoVoters guards the public, counts-only vote views against growing a
+// field that names voters
+func TestVoteCountViews_ExposeNoVoters(t *testing.T) {
+	for _, view := range []interface{}{posts.PostStats{}, comments.CommentStats{}, posts.ViewerState{}, comments.CommentViewerState{}} {
+		typ := reflect.TypeOf(view)
+		for i := 0; i < typ.NumField(); i++ {
+			tag := strings.ToLower(typ.Field(i).Tag.Get("json"))
+			if strings.Contains(tag, "did") || strings.Contains(tag, "voter") {
+				t.Errorf("%s.%s (json %q) exposes voters in a counts-only view", typ.Name(), typ.Field(i).Name, tag)
+			}
+		}
+	}
+
+	body, err := json.Marshal(posts.PostStats{Upvotes: 3, Downvotes: 1, Score: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(body), "did:") {
+		t.Errorf("post stats serialized a DID: %s", body)
+	}
+}
@@ -2,6 +2,7 @@ package aggregator
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/aggregators"
 	"context"
 	"encoding/json"
@@ -18,6 +19,8 @@ import (
 // mockAggregatorService implements aggregators.Service for testing
 type mockAggregatorService struct {
 	isAggregatorFunc func(ctx context.Context, did string) (bool, error)
+	listServicesFunc func(ctx context.Context, req aggregators.ListServicesRequest) (*aggregators.ListServicesResponse, error)
+	getServiceFunc   func(ctx context.Context, did string) (*aggregators.ServiceDetail, error)
 }
 
 func (m *mockAggregatorService) IsAggregator(ctx context.Context, did string) (bool, error) {
@@ -40,6 +43,20 @@ func (m *mockAggregatorService) ListAggregators(ctx context.Context, limit, offs
 	return nil, nil
 }
 
+func (m *mockAggregatorService) ListServices(ctx context.Context, req aggregators.ListServicesRequest) (*aggregators.ListServicesResponse, error) {
+	if m.listServicesFunc != nil {
+		return m.listServicesFunc(ctx, req)
+	}
+	return &aggregators.ListServicesResponse{Aggregators: []*aggregators.Aggregator{}}, nil
+}
+
+func (m *mockAggregatorService) GetService(ctx context.Context, did string) (*aggregators.ServiceDetail, error) {
+	if m.getServiceFunc != nil {
+		return m.getServiceFunc(ctx, did)
+	}
+	return nil, aggregators.ErrAggregatorNotFound
+}
+
 func (m *mockAggregatorService) GetAuthorizationsForAggregator(ctx context.Context, req aggregators.GetAuthorizationsRequest) ([]*aggregators.Authorization, error) {
 	return nil, nil
 }
@@ -64,14 +81,12 @@ func (m *mockAggregatorService) ValidateAggregatorPost(ctx context.Context, aggr
 	return nil
 }
 
-func (m *mockAggregatorService) RecordAggregatorPost(ctx context.Context, aggregatorDID, communityDID, postURI, postCID string) error {
-	return nil
+func (m *mockAggregatorService) GetPostQuota(ctx context.Context, aggregatorDID, communityDID string) ([]*aggregators.PostQuota, error) {
+	return nil, nil
 }
 
-// XRPCError represents an XRPC error response for testing
-type XRPCError struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
+func (m *mockAggregatorService) RecordAggregatorPost(ctx context.Context, aggregatorDID, communityDID, postURI, postCID string) error {
+	return nil
 }
 
 // Helper to create authenticated request context with OAuth session
@@ -170,12 +185,12 @@ func TestCreateAPIKeyHandler_RequiresAuth(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
-	if errResp.Error != "AuthenticationRequired" {
-		t.Errorf("Expected error AuthenticationRequired, got %s", errResp.Error)
+	if errResp.Error != "AuthRequired" {
+		t.Errorf("Expected error AuthRequired, got %s", errResp.Error)
 	}
 }
 
@@ -220,7 +235,7 @@ func TestCreateAPIKeyHandler_NotAggregator(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -253,7 +268,7 @@ func TestCreateAPIKeyHandler_AggregatorCheckError(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -286,7 +301,7 @@ func TestCreateAPIKeyHandler_MissingOAuthSession(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -426,12 +441,12 @@ func TestGetAPIKeyHandler_RequiresAuth(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
-	if errResp.Error != "AuthenticationRequired" {
-		t.Errorf("Expected error AuthenticationRequired, got %s", errResp.Error)
+	if errResp.Error != "AuthRequired" {
+		t.Errorf("Expected error AuthRequired, got %s", errResp.Error)
 	}
 }
 
@@ -475,7 +490,7 @@ func TestGetAPIKeyHandler_NotAggregator(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -507,7 +522,7 @@ func TestGetAPIKeyHandler_AggregatorCheckError(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -614,7 +629,7 @@ func TestRevokeAPIKeyHandler_NoKeyToRevoke(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -658,7 +673,7 @@ func TestRevokeAPIKeyHandler_AlreadyRevoked(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -686,12 +701,12 @@ func TestRevokeAPIKeyHandler_RequiresAuth(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
-	if errResp.Error != "AuthenticationRequired" {
-		t.Errorf("Expected error AuthenticationRequired, got %s", errResp.Error)
+	if errResp.Error != "AuthRequired" {
+		t.Errorf("Expected error AuthRequired, got %s", errResp.Error)
 	}
 }
 
@@ -736,7 +751,7 @@ func TestRevokeAPIKeyHandler_NotAggregator(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -769,7 +784,7 @@ func TestRevokeAPIKeyHandler_AggregatorCheckError(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/aggregators"
 )
 
@@ -40,14 +41,14 @@ type CreateAPIKeyResponse struct {
 // invalidated and all future requests using the old key will fail authentication.
 func (h *CreateAPIKeyHandler) HandleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get authenticated DID from context (set by RequireAuth middleware)
 	userDID := middleware.GetUserDID(r)
 	if userDID == "" {
-		writeError(w, http.StatusUnauthorized, "AuthenticationRequired", "Must be authenticated to create API key")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Must be authenticated to create API key")
 		return
 	}
 
@@ -55,18 +56,18 @@ func (h *CreateAPIKeyHandler) HandleCreateAPIKey(w http.ResponseWriter, r *http.
 	isAggregator, err := h.aggregatorService.IsAggregator(r.Context(), userDID)
 	if err != nil {
 		log.Printf("ERROR: Failed to check aggregator status: %v", err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError", "Failed to verify aggregator status")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to verify aggregator status")
 		return
 	}
 	if !isAggregator {
-		writeError(w, http.StatusForbidden, "AggregatorRequired", "Only registered aggregators can create API keys")
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.AggregatorRequired, "Only registered aggregators can create API keys")
 		return
 	}
 
 	// Get the OAuth session from context
 	oauthSession := middleware.GetOAuthSession(r)
 	if oauthSession == nil {
-		writeError(w, http.StatusUnauthorized, "OAuthSessionRequired", "OAuth session required to create API key")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.OAuthSessionRequired, "OAuth session required to create API key")
 		return
 	}
 
@@ -79,13 +80,13 @@ func (h *CreateAPIKeyHandler) HandleCreateAPIKey(w http.ResponseWriter, r *http.
 		switch {
 		case aggregators.IsNotFound(err):
 			// Aggregator not found in database - should not happen if IsAggregator check passed
-			writeError(w, http.StatusForbidden, "AggregatorRequired", "User is not a registered aggregator")
+			xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.AggregatorRequired, "User is not a registered aggregator")
 		case errors.Is(err, aggregators.ErrOAuthSessionMismatch):
 			// OAuth session DID doesn't match the requested aggregator DID
-			writeError(w, http.StatusBadRequest, "SessionMismatch", "OAuth session does not match the requested aggregator")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.SessionMismatch, "OAuth session does not match the requested aggregator")
 		default:
 			// All other errors are internal server errors
-			writeError(w, http.StatusInternalServerError, "KeyGenerationFailed", "Failed to generate API key")
+			xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.KeyGenerationFailed, "Failed to generate API key")
 		}
 		return
 	}
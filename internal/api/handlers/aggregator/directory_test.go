@@ -0,0 +1,171 @@
+package aggregator
+
+import (
+	"Coves/internal/core/aggregators"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListServicesHandler_Pagination(t *testing.T) {
+	var gotReq aggregators.ListServicesRequest
+	next := "2"
+	service := &mockAggregatorService{
+		listServicesFunc: func(ctx context.Context, req aggregators.ListServicesRequest) (*aggregators.ListServicesResponse, error) {
+			gotReq = req
+			return &aggregators.ListServicesResponse{
+				Cursor: &next,
+				Aggregators: []*aggregators.Aggregator{
+					{
+						DID:              "did:plc:agg1",
+						DisplayName:      "RSS Bot",
+						AvatarURL:        "bafyavatar",
+						PDSURL:           "https://pds.example.com",
+						MaintainerDID:    "did:plc:alice",
+						MaintainerHandle: "alice.example.com",
+						CommunitiesUsing: 7,
+						CreatedAt:        time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+					},
+					{DID: "did:plc:agg2", DisplayName: "No Avatar PDS", AvatarURL: "bafyorphan"},
+				},
+			}, nil
+		},
+	}
+	handler := NewListServicesHandler(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.aggregator.listServices?limit=2&cursor=0", nil)
+	w := httptest.NewRecorder()
+	handler.HandleListServices(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotReq.Limit != 2 || gotReq.Cursor != "0" {
+		t.Errorf("expected limit and cursor to be passed through, got %+v", gotReq)
+	}
+
+	var resp ListServicesResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Cursor == nil || *resp.Cursor != "2" {
+		t.Errorf("expected next cursor 2, got %v", resp.Cursor)
+	}
+	if len(resp.Services) != 2 {
+		t.Fatalf("expected 2 services, got %d", len(resp.Services))
+	}
+
+	first := resp.Services[0]
+	if first.CommunitiesUsing != 7 || first.MaintainerHandle == nil || *first.MaintainerHandle != "alice.example.com" {
+		t.Errorf("expected hydrated maintainer and community count, got %+v", first)
+	}
+	wantAvatar := "https://pds.example.com/xrpc/com.atproto.sync.getBlob?did=did%3Aplc%3Aagg1&cid=bafyavatar"
+	if first.Avatar == nil || *first.Avatar != wantAvatar {
+		t.Errorf("expected avatar URL %q, got %v", wantAvatar, first.Avatar)
+	}
+	if resp.Services[1].Avatar != nil {
+		t.Errorf("avatar CIDs without a known PDS must not be exposed, got %q", *resp.Services[1].Avatar)
+	}
+}
+
+func TestListServicesHandler_LastPageHasNoCursor(t *testing.T) {
+	handler := NewListServicesHandler(&mockAggregatorService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.aggregator.listServices", nil)
+	w := httptest.NewRecorder()
+	handler.HandleListServices(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := raw["cursor"]; ok {
+		t.Error("expected no cursor on the last page")
+	}
+	if services, ok := raw["services"].([]interface{}); !ok || len(services) != 0 {
+		t.Errorf("expected an empty services array, got %v", raw["services"])
+	}
+}
+
+func TestListServicesHandler_InvalidParams(t *testing.T) {
+	service := &mockAggregatorService{
+		listServicesFunc: func(ctx context.Context, req aggregators.ListServicesRequest) (*aggregators.ListServicesResponse, error) {
+			return nil, aggregators.NewValidationError("cursor", "invalid cursor")
+		},
+	}
+	handler := NewListServicesHandler(service)
+
+	for _, query := range []string{"limit=abc", "limit=0", "cursor=bogus"} {
+		req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.aggregator.listServices?"+query, nil)
+		w := httptest.NewRecorder()
+		handler.HandleListServices(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetServiceHandler(t *testing.T) {
+	service := &mockAggregatorService{
+		getServiceFunc: func(ctx context.Context, did string) (*aggregators.ServiceDetail, error) {
+			if did != "did:plc:agg1" {
+				return nil, aggregators.ErrAggregatorNotFound
+			}
+			return &aggregators.ServiceDetail{
+				Aggregator: &aggregators.Aggregator{
+					DID:          did,
+					DisplayName:  "RSS Bot",
+					ConfigSchema: []byte(`{"type":"object"}`),
+				},
+				Communities: []*aggregators.AuthorizedCommunity{{
+					DID:          "did:plc:news",
+					Handle:       "news.community.coves.test",
+					Name:         "news",
+					AvatarCID:    "bafynews",
+					PDSURL:       "https://pds.coves.test",
+					AuthorizedAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+				}},
+			}, nil
+		},
+	}
+	handler := NewGetServiceHandler(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.aggregator.getService?aggregator=did:plc:agg1", nil)
+	w := httptest.NewRecorder()
+	handler.HandleGetService(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp GetServiceResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Service.DisplayName != "RSS Bot" || resp.ConfigSchema == nil {
+		t.Errorf("unexpected service: %+v", resp)
+	}
+	if len(resp.Communities) != 1 || resp.Communities[0].Handle != "news.community.coves.test" || resp.Communities[0].Avatar == nil {
+		t.Errorf("unexpected communities: %+v", resp.Communities)
+	}
+	if resp.Communities[0].AuthorizedAt != "2025-02-01T00:00:00.000Z" {
+		t.Errorf("authorizedAt = %q", resp.Communities[0].AuthorizedAt)
+	}
+
+	for query, want := range map[string]int{
+		"":                         http.StatusBadRequest,
+		"?aggregator=did:plc:nope": http.StatusNotFound,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.aggregator.getService"+query, nil)
+		w := httptest.NewRecorder()
+		handler.HandleGetService(w, req)
+		if w.Code != want {
+			t.Errorf("%q: expected %d, got %d", query, want, w.Code)
+		}
+	}
+}
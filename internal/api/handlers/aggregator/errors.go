@@ -1,6 +1,8 @@
 package aggregator
 
 import (
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/aggregators"
 	"Coves/internal/core/communities"
 	"bytes"
@@ -9,12 +11,6 @@ import (
 	"net/http"
 )
 
-// ErrorResponse represents an XRPC error response
-type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-}
-
 // writeJSONResponse buffers the JSON encoding before sending headers.
 // This ensures that encoding failures don't result in partial responses
 // with already-sent headers. Returns true if the response was written
@@ -25,9 +21,7 @@ func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{})
 	if err := json.NewEncoder(&buf).Encode(data); err != nil {
 		log.Printf("ERROR: Failed to encode JSON response: %v", err)
 		// Send a proper error response since we haven't sent headers yet
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		_, _ = w.Write([]byte(`{"error":"InternalServerError","message":"Failed to encode response"}`))
+		xrpcerror.WriteEncodeFailure(w)
 		return false
 	}
 
@@ -40,14 +34,6 @@ func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{})
 	return true
 }
 
-// writeError writes a JSON error response with proper buffering
-func writeError(w http.ResponseWriter, statusCode int, errorType, message string) {
-	writeJSONResponse(w, statusCode, ErrorResponse{
-		Error:   errorType,
-		Message: message,
-	})
-}
-
 // handleServiceError maps service errors to HTTP responses
 // Handles errors from both aggregators and communities packages
 func handleServiceError(w http.ResponseWriter, err error) {
@@ -55,29 +41,36 @@ func handleServiceError(w http.ResponseWriter, err error) {
 		return
 	}
 
+	if common.WriteSentinelError(w, err) {
+		return
+	}
+
 	// Map domain errors to HTTP status codes
 	// Check community errors first (for ResolveCommunityIdentifier calls)
 	switch {
 	case communities.IsNotFound(err):
-		writeError(w, http.StatusNotFound, "CommunityNotFound", err.Error())
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.CommunityNotFound, err.Error())
 	case communities.IsValidationError(err):
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 	case aggregators.IsNotFound(err):
-		writeError(w, http.StatusNotFound, "NotFound", err.Error())
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.NotFound, err.Error())
 	case aggregators.IsValidationError(err):
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 	case aggregators.IsUnauthorized(err):
-		writeError(w, http.StatusForbidden, "Forbidden", err.Error())
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.Forbidden, err.Error())
 	case aggregators.IsConflict(err):
-		writeError(w, http.StatusConflict, "Conflict", err.Error())
+		xrpcerror.WriteError(w, http.StatusConflict, xrpcerror.Conflict, err.Error())
 	case aggregators.IsRateLimited(err):
-		writeError(w, http.StatusTooManyRequests, "RateLimitExceeded", err.Error())
+		xrpcerror.WriteError(w, http.StatusTooManyRequests, xrpcerror.RateLimitExceeded, err.Error())
 	case aggregators.IsNotImplemented(err):
-		writeError(w, http.StatusNotImplemented, "NotImplemented", "This feature is not yet available (Phase 2)")
+		xrpcerror.WriteError(w, http.StatusNotImplemented, xrpcerror.NotImplemented, "This feature is not yet available (Phase 2)")
 	default:
+		if common.WriteErrorKind(w, err) {
+			return
+		}
 		// Internal errors - don't leak details
 		log.Printf("ERROR: Aggregator service error: %v", err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError",
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError,
 			"An internal error occurred")
 	}
 }
@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/aggregators"
 )
 
@@ -42,14 +43,14 @@ type GetAPIKeyResponse struct {
 // NOTE: The actual key value is NEVER returned - only metadata about the key.
 func (h *GetAPIKeyHandler) HandleGetAPIKey(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get authenticated DID from context (set by RequireAuth middleware)
 	userDID := middleware.GetUserDID(r)
 	if userDID == "" {
-		writeError(w, http.StatusUnauthorized, "AuthenticationRequired", "Must be authenticated to get API key info")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Must be authenticated to get API key info")
 		return
 	}
 
@@ -57,11 +58,11 @@ func (h *GetAPIKeyHandler) HandleGetAPIKey(w http.ResponseWriter, r *http.Reques
 	isAggregator, err := h.aggregatorService.IsAggregator(r.Context(), userDID)
 	if err != nil {
 		log.Printf("ERROR: Failed to check aggregator status: %v", err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError", "Failed to verify aggregator status")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to verify aggregator status")
 		return
 	}
 	if !isAggregator {
-		writeError(w, http.StatusForbidden, "AggregatorRequired", "Only registered aggregators can get API key info")
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.AggregatorRequired, "Only registered aggregators can get API key info")
 		return
 	}
 
@@ -69,11 +70,11 @@ func (h *GetAPIKeyHandler) HandleGetAPIKey(w http.ResponseWriter, r *http.Reques
 	keyInfo, err := h.apiKeyService.GetAPIKeyInfo(r.Context(), userDID)
 	if err != nil {
 		if aggregators.IsNotFound(err) {
-			writeError(w, http.StatusNotFound, "AggregatorNotFound", "Aggregator not found")
+			xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.AggregatorNotFound, "Aggregator not found")
 			return
 		}
 		log.Printf("ERROR: Failed to get API key info for %s: %v", userDID, err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError", "Failed to get API key info")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to get API key info")
 		return
 	}
 
@@ -1,6 +1,7 @@
 package aggregator
 
 import (
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/aggregators"
 	"encoding/json"
 	"log"
@@ -25,14 +26,14 @@ func NewGetAuthorizationsHandler(service aggregators.Service) *GetAuthorizations
 // Following Bluesky's pattern for listing feed subscribers
 func (h *GetAuthorizationsHandler) HandleGetAuthorizations(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Parse request
 	req, err := h.parseRequest(r)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 		return
 	}
 
@@ -40,7 +41,7 @@ func (h *GetAuthorizationsHandler) HandleGetAuthorizations(w http.ResponseWriter
 	agg, err := h.service.GetAggregator(r.Context(), req.AggregatorDID)
 	if err != nil {
 		if aggregators.IsNotFound(err) {
-			writeError(w, http.StatusNotFound, "AggregatorNotFound", "Aggregator DID does not exist or has no service declaration")
+			xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.AggregatorNotFound, "Aggregator DID does not exist or has no service declaration")
 			return
 		}
 		handleServiceError(w, err)
@@ -112,19 +113,23 @@ type GetAuthorizationsResponse struct {
 // CommunityAuthView matches social.coves.aggregator.defs#communityAuthView
 // Shows authorization from aggregator's perspective with nested aggregator details
 type CommunityAuthView struct {
-	Config     interface{}    `json:"config,omitempty"`
-	Aggregator AggregatorView `json:"aggregator"`
-	CreatedAt  string         `json:"createdAt"`
-	RecordUri  string         `json:"recordUri,omitempty"`
-	Enabled    bool           `json:"enabled"`
+	Config          interface{}    `json:"config,omitempty"`
+	MaxPostsPerHour *int           `json:"maxPostsPerHour,omitempty"`
+	MaxPostsPerDay  *int           `json:"maxPostsPerDay,omitempty"`
+	Aggregator      AggregatorView `json:"aggregator"`
+	CreatedAt       string         `json:"createdAt"`
+	RecordUri       string         `json:"recordUri,omitempty"`
+	Enabled         bool           `json:"enabled"`
 }
 
 // toCommunityAuthView converts domain model to API view
 func toCommunityAuthView(auth *aggregators.Authorization, aggregatorView AggregatorView) CommunityAuthView {
 	view := CommunityAuthView{
-		Aggregator: aggregatorView, // Nested aggregator object
-		Enabled:    auth.Enabled,
-		CreatedAt:  auth.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+		Aggregator:      aggregatorView, // Nested aggregator object
+		Enabled:         auth.Enabled,
+		CreatedAt:       auth.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+		MaxPostsPerHour: auth.MaxPostsPerHour,
+		MaxPostsPerDay:  auth.MaxPostsPerDay,
 	}
 
 	// Add optional fields
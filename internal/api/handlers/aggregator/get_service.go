@@ -0,0 +1,98 @@
+package aggregator
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/blobs"
+	"encoding/json"
+	"net/http"
+)
+
+// GetServiceHandler handles the aggregator directory detail page
+type GetServiceHandler struct {
+	service aggregators.Service
+}
+
+// NewGetServiceHandler creates a new get service handler
+func NewGetServiceHandler(service aggregators.Service) *GetServiceHandler {
+	return &GetServiceHandler{
+		service: service,
+	}
+}
+
+// HandleGetService returns an aggregator with the communities that have enabled it
+// GET /xrpc/social.coves.aggregator.getService?aggregator=did:plc:abc123
+func (h *GetServiceHandler) HandleGetService(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	aggregatorDID := r.URL.Query().Get("aggregator")
+	if aggregatorDID == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "aggregator parameter is required")
+		return
+	}
+
+	detail, err := h.service.GetService(r.Context(), aggregatorDID)
+	if err != nil {
+		if aggregators.IsNotFound(err) {
+			xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.AggregatorNotFound, "Aggregator DID does not exist or has no service declaration")
+			return
+		}
+		handleServiceError(w, err)
+		return
+	}
+
+	response := GetServiceResponse{
+		Service:     toServiceView(detail.Aggregator),
+		Communities: make([]AuthorizedCommunityView, 0, len(detail.Communities)),
+	}
+	if len(detail.Aggregator.ConfigSchema) > 0 {
+		// ConfigSchema is already JSON, unmarshal it for the view
+		var schema interface{}
+		if err := json.Unmarshal(detail.Aggregator.ConfigSchema, &schema); err == nil {
+			response.ConfigSchema = schema
+		}
+	}
+	for _, community := range detail.Communities {
+		response.Communities = append(response.Communities, toAuthorizedCommunityView(community))
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// GetServiceResponse matches the social.coves.aggregator.getService output
+type GetServiceResponse struct {
+	ConfigSchema interface{}               `json:"configSchema,omitempty"`
+	Communities  []AuthorizedCommunityView `json:"communities"`
+	Service      ServiceView               `json:"service"`
+}
+
+// AuthorizedCommunityView matches social.coves.aggregator.defs#authorizedCommunityView
+type AuthorizedCommunityView struct {
+	DisplayName  *string `json:"displayName,omitempty"`
+	Avatar       *string `json:"avatar,omitempty"`
+	DID          string  `json:"did"`
+	Handle       string  `json:"handle"`
+	Name         string  `json:"name"`
+	AuthorizedAt string  `json:"authorizedAt"`
+}
+
+// toAuthorizedCommunityView converts an authorized community to its API view
+func toAuthorizedCommunityView(community *aggregators.AuthorizedCommunity) AuthorizedCommunityView {
+	view := AuthorizedCommunityView{
+		DID:          community.DID,
+		Handle:       community.Handle,
+		Name:         community.Name,
+		AuthorizedAt: community.AuthorizedAt.Format("2006-01-02T15:04:05.000Z"),
+	}
+	if community.DisplayName != "" {
+		view.DisplayName = &community.DisplayName
+	}
+	if community.AvatarCID != "" && community.PDSURL != "" {
+		avatar := blobs.HydrateBlobURL(community.PDSURL, community.DID, community.AvatarCID)
+		view.Avatar = &avatar
+	}
+	return view
+}
@@ -1,6 +1,7 @@
 package aggregator
 
 import (
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/aggregators"
 	"encoding/json"
 	"log"
@@ -25,14 +26,14 @@ func NewGetServicesHandler(service aggregators.Service) *GetServicesHandler {
 // Following Bluesky's pattern: app.bsky.feed.getFeedGenerators
 func (h *GetServicesHandler) HandleGetServices(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Parse DIDs from query parameter
 	didsParam := r.URL.Query().Get("dids")
 	if didsParam == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "dids parameter is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "dids parameter is required")
 		return
 	}
 
@@ -53,7 +54,7 @@ func (h *GetServicesHandler) HandleGetServices(w http.ResponseWriter, r *http.Re
 
 	// Validate we have at least one valid DID
 	if len(dids) == 0 {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "at least one valid DID is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "at least one valid DID is required")
 		return
 	}
 
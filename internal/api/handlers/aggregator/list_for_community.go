@@ -1,6 +1,7 @@
 package aggregator
 
 import (
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/aggregators"
 	"Coves/internal/core/communities"
 	"encoding/json"
@@ -28,14 +29,14 @@ func NewListForCommunityHandler(service aggregators.Service, communityService co
 // Used by community settings UI to manage aggregators
 func (h *ListForCommunityHandler) HandleListForCommunity(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Parse request
 	req, communityIdentifier, err := h.parseRequest(r)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 		return
 	}
 
@@ -126,6 +127,8 @@ type ListForCommunityResponse struct {
 // Shows authorization from community's perspective
 type AuthorizationView struct {
 	Config          interface{} `json:"config,omitempty"`
+	MaxPostsPerHour *int        `json:"maxPostsPerHour,omitempty"`
+	MaxPostsPerDay  *int        `json:"maxPostsPerDay,omitempty"`
 	CommunityHandle *string     `json:"communityHandle,omitempty"`
 	CommunityName   *string     `json:"communityName,omitempty"`
 	CreatedBy       *string     `json:"createdBy,omitempty"`
@@ -150,8 +153,10 @@ func toAuthorizationView(auth *aggregators.Authorization, communityDID string) A
 		AggregatorDID: auth.AggregatorDID,
 		CommunityDID:  communityDID,
 		// CommunityHandle and CommunityName left nil - TODO: fetch from communities service
-		Enabled:   auth.Enabled,
-		CreatedAt: auth.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+		Enabled:         auth.Enabled,
+		CreatedAt:       auth.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+		MaxPostsPerHour: auth.MaxPostsPerHour,
+		MaxPostsPerDay:  auth.MaxPostsPerDay,
 	}
 
 	// Add optional fields
@@ -0,0 +1,108 @@
+package aggregator
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/blobs"
+	"net/http"
+	"strconv"
+)
+
+// ListServicesHandler handles the public aggregator directory
+type ListServicesHandler struct {
+	service aggregators.Service
+}
+
+// NewListServicesHandler creates a new list services handler
+func NewListServicesHandler(service aggregators.Service) *ListServicesHandler {
+	return &ListServicesHandler{
+		service: service,
+	}
+}
+
+// HandleListServices lists indexed aggregators, most widely used first
+// GET /xrpc/social.coves.aggregator.listServices?limit=50&cursor=50
+func (h *ListServicesHandler) HandleListServices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	req := aggregators.ListServicesRequest{Cursor: r.URL.Query().Get("cursor")}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be a positive integer")
+			return
+		}
+		req.Limit = limit
+	}
+
+	result, err := h.service.ListServices(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	response := ListServicesResponse{
+		Cursor:   result.Cursor,
+		Services: make([]ServiceView, 0, len(result.Aggregators)),
+	}
+	for _, agg := range result.Aggregators {
+		response.Services = append(response.Services, toServiceView(agg))
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// ListServicesResponse matches the social.coves.aggregator.listServices output
+type ListServicesResponse struct {
+	Cursor   *string       `json:"cursor,omitempty"`
+	Services []ServiceView `json:"services"`
+}
+
+// ServiceView matches social.coves.aggregator.defs#serviceView
+// The directory's hydrated aggregator view: avatar URL, maintainer handle, community count
+type ServiceView struct {
+	Description      *string `json:"description,omitempty"`
+	Avatar           *string `json:"avatar,omitempty"`
+	SourceURL        *string `json:"sourceUrl,omitempty"`
+	MaintainerDID    *string `json:"maintainer,omitempty"`
+	MaintainerHandle *string `json:"maintainerHandle,omitempty"`
+	DID              string  `json:"did"`
+	DisplayName      string  `json:"displayName"`
+	CreatedAt        string  `json:"createdAt"`
+	RecordUri        string  `json:"recordUri"`
+	CommunitiesUsing int     `json:"communitiesUsing"`
+}
+
+// toServiceView converts a directory aggregator to its API view
+func toServiceView(agg *aggregators.Aggregator) ServiceView {
+	view := ServiceView{
+		DID:              agg.DID,
+		DisplayName:      agg.DisplayName,
+		CreatedAt:        agg.CreatedAt.Format("2006-01-02T15:04:05.000Z"),
+		RecordUri:        agg.RecordURI,
+		CommunitiesUsing: agg.CommunitiesUsing,
+	}
+
+	if agg.Description != "" {
+		view.Description = &agg.Description
+	}
+	// AvatarURL holds the blob CID; it can only be served once the aggregator's PDS is known
+	if agg.AvatarURL != "" && agg.PDSURL != "" {
+		avatar := blobs.HydrateBlobURL(agg.PDSURL, agg.DID, agg.AvatarURL)
+		view.Avatar = &avatar
+	}
+	if agg.SourceURL != "" {
+		view.SourceURL = &agg.SourceURL
+	}
+	if agg.MaintainerDID != "" {
+		view.MaintainerDID = &agg.MaintainerDID
+	}
+	if agg.MaintainerHandle != "" {
+		view.MaintainerHandle = &agg.MaintainerHandle
+	}
+
+	return view
+}
@@ -3,6 +3,7 @@ package aggregator
 import (
 	"net/http"
 
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/aggregators"
 )
 
@@ -29,7 +30,7 @@ type MetricsResponse struct {
 // This endpoint is intended for internal monitoring and health checks.
 func (h *MetricsHandler) HandleMetrics(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -1,6 +1,7 @@
 package aggregator
 
 import (
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/atproto/identity"
 	"Coves/internal/core/users"
 	"context"
@@ -66,20 +67,20 @@ type RegisterResponse struct {
 // 5. Similar pattern used in Bluesky's PDS for account creation
 func (h *RegisterHandler) HandleRegister(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Parse request body
 	var req RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidDID", "Invalid request body: JSON decode failed")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidDID, "Invalid request body: JSON decode failed")
 		return
 	}
 
 	// Validate input
 	if err := validateRegistrationRequest(req); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidDID", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidDID, err.Error())
 		return
 	}
 
@@ -89,7 +90,7 @@ func (h *RegisterHandler) HandleRegister(w http.ResponseWriter, r *http.Request)
 
 	// Reject HTTP explicitly (HTTPS required for domain verification)
 	if strings.HasPrefix(req.Domain, "http://") {
-		writeError(w, http.StatusBadRequest, "InvalidDID", "Domain must use HTTPS, not HTTP")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidDID, "Domain must use HTTPS, not HTTP")
 		return
 	}
 
@@ -98,14 +99,14 @@ func (h *RegisterHandler) HandleRegister(w http.ResponseWriter, r *http.Request)
 
 	// Re-validate after normalization to catch edge cases like "   " or "https://"
 	if req.Domain == "" {
-		writeError(w, http.StatusBadRequest, "InvalidDID", "Domain cannot be empty")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidDID, "Domain cannot be empty")
 		return
 	}
 
 	// Verify domain ownership via .well-known
 	if err := h.verifyDomainOwnership(r.Context(), req.DID, req.Domain); err != nil {
 		log.Printf("Domain verification failed for DID %s, domain %s: %v", req.DID, req.Domain, err)
-		writeError(w, http.StatusUnauthorized, "DomainVerificationFailed",
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.DomainVerificationFailed,
 			"Could not verify domain ownership. Ensure .well-known/atproto-did serves your DID over HTTPS")
 		return
 	}
@@ -113,7 +114,7 @@ func (h *RegisterHandler) HandleRegister(w http.ResponseWriter, r *http.Request)
 	// Check if user already exists (before CreateUser since it's idempotent)
 	existingUser, err := h.userService.GetUserByDID(r.Context(), req.DID)
 	if err == nil && existingUser != nil {
-		writeError(w, http.StatusConflict, "AlreadyRegistered",
+		xrpcerror.WriteError(w, http.StatusConflict, xrpcerror.AlreadyRegistered,
 			"This aggregator is already registered with this instance")
 		return
 	}
@@ -121,7 +122,7 @@ func (h *RegisterHandler) HandleRegister(w http.ResponseWriter, r *http.Request)
 	// Resolve DID to get handle and PDS URL
 	identityInfo, err := h.identityResolver.Resolve(r.Context(), req.DID)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "DIDResolutionFailed",
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.DIDResolutionFailed,
 			"Could not resolve DID. Please verify it exists in the PLC directory")
 		return
 	}
@@ -136,7 +137,7 @@ func (h *RegisterHandler) HandleRegister(w http.ResponseWriter, r *http.Request)
 	user, err := h.userService.CreateUser(r.Context(), createReq)
 	if err != nil {
 		log.Printf("Failed to create user for aggregator DID %s: %v", req.DID, err)
-		writeError(w, http.StatusInternalServerError, "RegistrationFailed",
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.RegistrationFailed,
 			"Failed to register aggregator")
 		return
 	}
@@ -148,11 +149,7 @@ func (h *RegisterHandler) HandleRegister(w http.ResponseWriter, r *http.Request)
 		Message: fmt.Sprintf("Aggregator registered successfully. Next step: create a service declaration record at at://%s/social.coves.aggregator.service/self", user.DID),
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
-		http.Error(w, "Failed to encode response", http.StatusInternalServerError)
-	}
+	writeJSONResponse(w, http.StatusOK, response)
 }
 
 // validateRegistrationRequest validates the registration request
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/aggregators"
 )
 
@@ -33,14 +34,14 @@ type RevokeAPIKeyResponse struct {
 // After revocation, the aggregator must complete OAuth flow again to get a new key.
 func (h *RevokeAPIKeyHandler) HandleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get authenticated DID from context (set by RequireAuth middleware)
 	userDID := middleware.GetUserDID(r)
 	if userDID == "" {
-		writeError(w, http.StatusUnauthorized, "AuthenticationRequired", "Must be authenticated to revoke API key")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Must be authenticated to revoke API key")
 		return
 	}
 
@@ -48,11 +49,11 @@ func (h *RevokeAPIKeyHandler) HandleRevokeAPIKey(w http.ResponseWriter, r *http.
 	isAggregator, err := h.aggregatorService.IsAggregator(r.Context(), userDID)
 	if err != nil {
 		log.Printf("ERROR: Failed to check aggregator status: %v", err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError", "Failed to verify aggregator status")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to verify aggregator status")
 		return
 	}
 	if !isAggregator {
-		writeError(w, http.StatusForbidden, "AggregatorRequired", "Only registered aggregators can revoke API keys")
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.AggregatorRequired, "Only registered aggregators can revoke API keys")
 		return
 	}
 
@@ -60,28 +61,28 @@ func (h *RevokeAPIKeyHandler) HandleRevokeAPIKey(w http.ResponseWriter, r *http.
 	keyInfo, err := h.apiKeyService.GetAPIKeyInfo(r.Context(), userDID)
 	if err != nil {
 		if aggregators.IsNotFound(err) {
-			writeError(w, http.StatusNotFound, "AggregatorNotFound", "Aggregator not found")
+			xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.AggregatorNotFound, "Aggregator not found")
 			return
 		}
 		log.Printf("ERROR: Failed to get API key info for %s: %v", userDID, err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError", "Failed to get API key info")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to get API key info")
 		return
 	}
 
 	if !keyInfo.HasKey {
-		writeError(w, http.StatusBadRequest, "ApiKeyNotFound", "No API key exists to revoke")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.ApiKeyNotFound, "No API key exists to revoke")
 		return
 	}
 
 	if keyInfo.IsRevoked {
-		writeError(w, http.StatusBadRequest, "ApiKeyAlreadyRevoked", "API key has already been revoked")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.ApiKeyAlreadyRevoked, "API key has already been revoked")
 		return
 	}
 
 	// Revoke the API key
 	if err := h.apiKeyService.RevokeKey(r.Context(), userDID); err != nil {
 		log.Printf("ERROR: Failed to revoke API key for %s: %v", userDID, err)
-		writeError(w, http.StatusInternalServerError, "RevocationFailed", "Failed to revoke API key")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.RevocationFailed, "Failed to revoke API key")
 		return
 	}
 
@@ -0,0 +1,75 @@
+package aggregator
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/lexicon/validate"
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// ValidateRecordHandler checks a post record against the lexicon rules the AppView indexes by
+// It's a schema check only: nothing about the author, community or quota is looked at, and
+// nothing is written. createPost with dryRun runs the full pipeline.
+type ValidateRecordHandler struct{}
+
+// NewValidateRecordHandler creates a new validate record handler
+func NewValidateRecordHandler() *ValidateRecordHandler {
+	return &ValidateRecordHandler{}
+}
+
+// ValidateRecordRequest matches the social.coves.aggregator.validateRecord input
+type ValidateRecordRequest struct {
+	Record map[string]interface{} `json:"record"`
+}
+
+// ValidateRecordResponse matches the social.coves.aggregator.validateRecord output
+type ValidateRecordResponse struct {
+	Violations []validate.Violation `json:"violations"`
+	Valid      bool                 `json:"valid"`
+}
+
+// HandleValidateRecord validates a social.coves.community.post record
+// POST /xrpc/social.coves.aggregator.validateRecord
+// An invalid record is a successful call: the violations are the answer.
+func (h *ValidateRecordHandler) HandleValidateRecord(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Same body limit as post.create, so anything that fits there can be checked here
+	r.Body = http.MaxBytesReader(w, r.Body, 1*1024*1024)
+
+	var req ValidateRecordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			xrpcerror.WriteError(w, http.StatusRequestEntityTooLarge, xrpcerror.RequestTooLarge, "Request body too large (max 1MB)")
+			return
+		}
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	if req.Record == nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "record is required")
+		return
+	}
+	if recordType, ok := req.Record["$type"].(string); ok && recordType != validate.PostCollection {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "record must be a "+validate.PostCollection)
+		return
+	}
+
+	response := ValidateRecordResponse{Valid: true, Violations: []validate.Violation{}}
+	if err := validate.Record(validate.PostCollection, req.Record); err != nil {
+		var invalid *validate.Error
+		if !errors.As(err, &invalid) {
+			handleServiceError(w, err)
+			return
+		}
+		response.Valid = false
+		response.Violations = invalid.Violations
+	}
+
+	writeJSONResponse(w, http.StatusOK, response)
+}
@@ -0,0 +1,76 @@
+package aggregator
+
+import (
+	"Coves/internal/lexicon/validate"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func validateRecord(t *testing.T, body string) (*httptest.ResponseRecorder, ValidateRecordResponse) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/xrpc/social.coves.aggregator.validateRecord", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	NewValidateRecordHandler().HandleValidateRecord(w, req)
+
+	var resp ValidateRecordResponse
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+	}
+	return w, resp
+}
+
+func TestValidateRecordHandler(t *testing.T) {
+	t.Run("valid record", func(t *testing.T) {
+		w, resp := validateRecord(t, `{"record":{"$type":"social.coves.community.post","community":"did:plc:community","author":"did:plc:agg","createdAt":"2025-01-01T00:00:00Z","title":"Hello"}}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if !resp.Valid || len(resp.Violations) != 0 {
+			t.Errorf("expected a valid record, got %+v", resp)
+		}
+	})
+
+	t.Run("invalid record lists violations", func(t *testing.T) {
+		w, resp := validateRecord(t, `{"record":{"community":"did:plc:community","author":"not-a-did","title":"`+strings.Repeat("a", validate.MaxPostTitleBytes+1)+`"}}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		if resp.Valid {
+			t.Fatal("expected an invalid record")
+		}
+		fields := map[string]bool{}
+		for _, v := range resp.Violations {
+			fields[v.Field] = true
+		}
+		for _, field := range []string{"author", "createdAt", "title"} {
+			if !fields[field] {
+				t.Errorf("expected a violation for %s, got %+v", field, resp.Violations)
+			}
+		}
+	})
+
+	for name, body := range map[string]string{
+		"missing record":   `{}`,
+		"malformed body":   `{"record":`,
+		"other collection": `{"record":{"$type":"social.coves.community.comment"}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if w, _ := validateRecord(t, body); w.Code != http.StatusBadRequest {
+				t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("GET not allowed", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewValidateRecordHandler().HandleValidateRecord(w, httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.aggregator.validateRecord", nil))
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("expected 405, got %d", w.Code)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package comments
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/comments"
 	"encoding/json"
 	"log"
@@ -53,7 +54,7 @@ type CreateCommentOutput struct {
 func (h *CreateCommentHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	// 1. Check method is POST
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -63,14 +64,14 @@ func (h *CreateCommentHandler) HandleCreate(w http.ResponseWriter, r *http.Reque
 	// 3. Parse JSON body into CreateCommentInput
 	var input CreateCommentInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	// 4. Get OAuth session from context (injected by auth middleware)
 	session := middleware.GetOAuthSession(r)
 	if session == nil {
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
@@ -79,12 +80,12 @@ func (h *CreateCommentHandler) HandleCreate(w http.ResponseWriter, r *http.Reque
 	if input.Labels != nil {
 		labelsJSON, err := json.Marshal(input.Labels)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "InvalidLabels", "Invalid labels format")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidLabels, "Invalid labels format")
 			return
 		}
 		var selfLabels comments.SelfLabels
 		if err := json.Unmarshal(labelsJSON, &selfLabels); err != nil {
-			writeError(w, http.StatusBadRequest, "InvalidLabels", "Invalid labels structure")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidLabels, "Invalid labels structure")
 			return
 		}
 		labels = &selfLabels
@@ -2,6 +2,7 @@ package comments
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/comments"
 	"encoding/json"
 	"log"
@@ -36,7 +37,7 @@ type DeleteCommentOutput struct{}
 func (h *DeleteCommentHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 	// 1. Check method is POST
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -46,14 +47,14 @@ func (h *DeleteCommentHandler) HandleDelete(w http.ResponseWriter, r *http.Reque
 	// 3. Parse JSON body into DeleteCommentInput
 	var input DeleteCommentInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	// 4. Get OAuth session from context (injected by auth middleware)
 	session := middleware.GetOAuthSession(r)
 	if session == nil {
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
@@ -3,8 +3,14 @@
 package comments
 
 import (
+	"Coves/internal/api/handlers/common"
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/views"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/posts"
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -14,12 +20,14 @@ import (
 // GetCommentsHandler handles comment retrieval for posts
 type GetCommentsHandler struct {
 	service Service
+	ageGate communities.AgeGate
 }
 
 // Service defines the interface for comment business logic
 // This will be implemented by the comments service layer in Phase 2
 type Service interface {
 	GetComments(r *http.Request, req *GetCommentsRequest) (*comments.GetCommentsResponse, error)
+	GetCommentThread(r *http.Request, req *GetCommentThreadRequest) (*comments.GetCommentThreadResponse, error)
 }
 
 // GetCommentsRequest represents the query parameters for fetching comments
@@ -34,6 +42,19 @@ type GetCommentsRequest struct {
 	Limit     int     `json:"limit,omitempty"`
 }
 
+// GetCommentThreadRequest represents the query parameters for fetching a single comment thread
+// Used when getComments is called with uri (a comment permalink) instead of post
+type GetCommentThreadRequest struct {
+	Cursor       *string `json:"cursor,omitempty"`
+	ViewerDID    *string `json:"-"`
+	CommentURI   string  `json:"uri"`
+	Sort         string  `json:"sort,omitempty"`
+	Timeframe    string  `json:"timeframe,omitempty"`
+	ParentHeight int     `json:"parentHeight,omitempty"`
+	Depth        int     `json:"depth,omitempty"`
+	Limit        int     `json:"limit,omitempty"`
+}
+
 // NewGetCommentsHandler creates a new handler for fetching comments
 func NewGetCommentsHandler(service Service) *GetCommentsHandler {
 	return &GetCommentsHandler{
@@ -41,18 +62,27 @@ func NewGetCommentsHandler(service Service) *GetCommentsHandler {
 	}
 }
 
+// SetAgeGate withholds comments in age-restricted communities from viewers who haven't confirmed their age
+func (h *GetCommentsHandler) SetAgeGate(gate communities.AgeGate) {
+	h.ageGate = gate
+}
+
 // HandleGetComments handles GET /xrpc/social.coves.feed.getComments
-// Retrieves comments on a post with threading support
+// Retrieves comments on a post with threading support, or a single comment thread
+// (ancestors, focus comment, and replies) when called with uri instead of post.
+// Comments in age-restricted communities are replaced by a gated response until the viewer confirms.
 func (h *GetCommentsHandler) HandleGetComments(w http.ResponseWriter, r *http.Request) {
 	// 1. Only allow GET method
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// 2. Parse query parameters
 	query := r.URL.Query()
 	post := query.Get("post")
+	uri := query.Get("uri")
+	parentHeightStr := query.Get("parentHeight")
 	sort := query.Get("sort")
 	timeframe := query.Get("timeframe")
 	depthStr := query.Get("depth")
@@ -60,21 +90,48 @@ func (h *GetCommentsHandler) HandleGetComments(w http.ResponseWriter, r *http.Re
 	cursor := query.Get("cursor")
 
 	// 3. Validate required parameters
-	if post == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "post parameter is required")
+	if post == "" && uri == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "post or uri parameter is required")
+		return
+	}
+	if post != "" && uri != "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "post and uri parameters are mutually exclusive")
 		return
 	}
 
+	// 3.5. Parse and validate parentHeight with default (only meaningful for uri)
+	parentHeight := 10 // Default parent height
+	if parentHeightStr != "" {
+		if uri == "" {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "parentHeight can only be used with uri")
+			return
+		}
+		parsed, err := strconv.Atoi(parentHeightStr)
+		if err != nil {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "parentHeight must be a valid integer")
+			return
+		}
+		if parsed < 0 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "parentHeight must be non-negative")
+			return
+		}
+		if parsed > 100 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "parentHeight cannot exceed 100")
+			return
+		}
+		parentHeight = parsed
+	}
+
 	// 4. Parse and validate depth with default
 	depth := 10 // Default depth
 	if depthStr != "" {
 		parsed, err := strconv.Atoi(depthStr)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "depth must be a valid integer")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "depth must be a valid integer")
 			return
 		}
 		if parsed < 0 {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "depth must be non-negative")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "depth must be non-negative")
 			return
 		}
 		depth = parsed
@@ -85,31 +142,31 @@ func (h *GetCommentsHandler) HandleGetComments(w http.ResponseWriter, r *http.Re
 	if limitStr != "" {
 		parsed, err := strconv.Atoi(limitStr)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "limit must be a valid integer")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be a valid integer")
 			return
 		}
 		if parsed < 1 {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "limit must be positive")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be positive")
 			return
 		}
 		if parsed > 100 {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "limit cannot exceed 100")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit cannot exceed 100")
 			return
 		}
 		limit = parsed
 	}
 
 	// 6. Validate sort parameter (if provided)
-	if sort != "" && sort != "hot" && sort != "top" && sort != "new" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest",
-			"sort must be one of: hot, top, new")
+	if sort != "" && sort != "best" && sort != "hot" && sort != "top" && sort != "new" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest,
+			"sort must be one of: best, hot, top, new")
 		return
 	}
 
 	// 7. Validate timeframe parameter (only valid with "top" sort)
 	if timeframe != "" {
 		if sort != "top" {
-			writeError(w, http.StatusBadRequest, "InvalidRequest",
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest,
 				"timeframe can only be used with sort=top")
 			return
 		}
@@ -118,12 +175,19 @@ func (h *GetCommentsHandler) HandleGetComments(w http.ResponseWriter, r *http.Re
 			"month": true, "year": true, "all": true,
 		}
 		if !validTimeframes[timeframe] {
-			writeError(w, http.StatusBadRequest, "InvalidRequest",
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest,
 				"timeframe must be one of: hour, day, week, month, year, all")
 			return
 		}
 	}
 
+	// 7.5. Parse response shape version (query param or Accept header)
+	version, err := views.ParseVersion(r)
+	if err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.UnsupportedVersion, err.Error())
+		return
+	}
+
 	// 8. Extract viewer DID from context (set by OptionalAuth middleware)
 	viewerDID := middleware.GetUserDID(r)
 	var viewerPtr *string
@@ -131,6 +195,29 @@ func (h *GetCommentsHandler) HandleGetComments(w http.ResponseWriter, r *http.Re
 		viewerPtr = &viewerDID
 	}
 
+	// 8.5. Comment permalink: fetch the single thread instead of the post's comments
+	if uri != "" {
+		resp, err := h.service.GetCommentThread(r, &GetCommentThreadRequest{
+			CommentURI:   uri,
+			Sort:         sort,
+			Timeframe:    timeframe,
+			ParentHeight: parentHeight,
+			Depth:        depth,
+			Limit:        limit,
+			Cursor:       ptrOrNil(cursor),
+			ViewerDID:    viewerPtr,
+		})
+		if err != nil {
+			handleServiceError(w, err)
+			return
+		}
+		if h.writeIfGated(r.Context(), w, viewerDID, resp.Post) {
+			return
+		}
+		writeResponse(w, version, views.BuildCommentThreadResponse(version, resp))
+		return
+	}
+
 	// 9. Build service request
 	req := &GetCommentsRequest{
 		PostURI:   post,
@@ -149,10 +236,38 @@ func (h *GetCommentsHandler) HandleGetComments(w http.ResponseWriter, r *http.Re
 		return
 	}
 
-	// 11. Return JSON response
-	w.Header().Set("Content-Type", "application/json")
+	// 11. Gated viewers get the post's community but no comments
+	if h.writeIfGated(r.Context(), w, viewerDID, resp.Post) {
+		return
+	}
+
+	// 12. Return JSON response
+	writeResponse(w, version, views.BuildCommentsResponse(version, resp))
+}
+
+// writeIfGated writes the gated response when the post's community is age-gated for the viewer
+// It reports whether a response was written.
+func (h *GetCommentsHandler) writeIfGated(ctx context.Context, w http.ResponseWriter, viewerDID string, post interface{}) bool {
+	postView, ok := post.(*posts.PostView)
+	if h.ageGate == nil || !ok || postView.Community == nil {
+		return false
+	}
+	community, gated, err := h.ageGate.GatedCommunity(ctx, viewerDID, postView.Community.DID)
+	if err != nil {
+		handleServiceError(w, err)
+		return true
+	}
+	if gated {
+		common.WriteAgeGatedComments(w, community)
+	}
+	return gated
+}
+
+// writeResponse writes a successful response body in the requested version's content type
+func writeResponse(w http.ResponseWriter, version views.Version, body interface{}) {
+	w.Header().Set("Content-Type", views.ContentType(version))
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
+	if err := json.NewEncoder(w).Encode(body); err != nil {
 		// Log encoding errors but don't return error response (headers already sent)
 		log.Printf("Failed to encode comments response: %v", err)
 	}
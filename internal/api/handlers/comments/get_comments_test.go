@@ -0,0 +1,246 @@
+package comments
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/posts"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockService implements the handler's Service interface for testing
+type mockService struct {
+	getCommentThreadFunc func(r *http.Request, req *GetCommentThreadRequest) (*comments.GetCommentThreadResponse, error)
+	post                 interface{}
+	getCommentsCalled    bool
+}
+
+func (m *mockService) GetComments(r *http.Request, req *GetCommentsRequest) (*comments.GetCommentsResponse, error) {
+	m.getCommentsCalled = true
+	return &comments.GetCommentsResponse{Post: m.post, Comments: []*comments.ThreadViewComment{}}, nil
+}
+
+func (m *mockService) GetCommentThread(r *http.Request, req *GetCommentThreadRequest) (*comments.GetCommentThreadResponse, error) {
+	if m.getCommentThreadFunc != nil {
+		return m.getCommentThreadFunc(r, req)
+	}
+	return nil, comments.ErrCommentNotFound
+}
+
+func TestGetCommentsHandler_CommentThreadWithDeletedAncestorStub(t *testing.T) {
+	postURI := "at://did:plc:community/social.coves.community.post/3kpost"
+	focusURI := "at://did:plc:alice/social.coves.community.comment/3kfocus"
+	removedURI := "at://did:plc:bob/social.coves.community.comment/3kremoved"
+	reason := comments.DeletionReasonModerator
+
+	var gotReq *GetCommentThreadRequest
+	service := &mockService{
+		getCommentThreadFunc: func(r *http.Request, req *GetCommentThreadRequest) (*comments.GetCommentThreadResponse, error) {
+			gotReq = req
+			return &comments.GetCommentThreadResponse{
+				Post: map[string]interface{}{"uri": postURI},
+				Thread: &comments.CommentThreadView{
+					Ancestors: []*comments.CommentView{
+						{URI: removedURI, IsDeleted: true, DeletionReason: &reason, Post: &comments.CommentRef{URI: postURI}},
+					},
+					Focus: &comments.CommentView{
+						URI:    focusURI,
+						Record: map[string]interface{}{"content": "focus"},
+						Parent: &comments.CommentRef{URI: removedURI},
+					},
+					Replies: []*comments.ThreadViewComment{},
+				},
+			}, nil
+		},
+	}
+	handler := NewGetCommentsHandler(service)
+
+	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.community.comment.getComments?uri="+focusURI+"&parentHeight=3", nil)
+	w := httptest.NewRecorder()
+	handler.HandleGetComments(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if service.getCommentsCalled {
+		t.Error("Expected uri requests not to fetch the post's comments")
+	}
+	if gotReq.CommentURI != focusURI || gotReq.ParentHeight != 3 {
+		t.Errorf("Unexpected service request: %+v", gotReq)
+	}
+
+	var body struct {
+		Thread struct {
+			Ancestors []map[string]interface{} `json:"ancestors"`
+			Focus     map[string]interface{}   `json:"focus"`
+			Replies   []interface{}            `json:"replies"`
+		} `json:"thread"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(body.Thread.Ancestors) != 1 {
+		t.Fatalf("Expected 1 ancestor, got %d", len(body.Thread.Ancestors))
+	}
+	stub := body.Thread.Ancestors[0]
+	if stub["uri"] != removedURI || stub["isDeleted"] != true || stub["deletionReason"] != reason {
+		t.Errorf("Expected removed ancestor to be a stub, got %v", stub)
+	}
+	if stub["record"] != nil {
+		t.Errorf("Expected stub to have no record, got %v", stub["record"])
+	}
+	if body.Thread.Focus["uri"] != focusURI {
+		t.Errorf("Expected focus %s, got %v", focusURI, body.Thread.Focus["uri"])
+	}
+	if body.Thread.Replies == nil {
+		t.Error("Expected replies to be an empty array, not null")
+	}
+}
+
+func TestGetCommentsHandler_CommentThreadParameterValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "neither post nor uri", query: ""},
+		{name: "post and uri together", query: "?post=at://did:plc:c/p/1&uri=at://did:plc:a/c/1"},
+		{name: "parentHeight without uri", query: "?post=at://did:plc:c/p/1&parentHeight=2"},
+		{name: "negative parentHeight", query: "?uri=at://did:plc:a/c/1&parentHeight=-1"},
+		{name: "parentHeight too large", query: "?uri=at://did:plc:a/c/1&parentHeight=101"},
+		{name: "malformed parentHeight", query: "?uri=at://did:plc:a/c/1&parentHeight=abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewGetCommentsHandler(&mockService{})
+			req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.community.comment.getComments"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.HandleGetComments(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetCommentsHandler_CommentThreadNotFound(t *testing.T) {
+	handler := NewGetCommentsHandler(&mockService{})
+	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.community.comment.getComments?uri=at://did:plc:a/c/missing", nil)
+	w := httptest.NewRecorder()
+	handler.HandleGetComments(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d", w.Code)
+	}
+	var errResp xrpcerror.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("Failed to decode error: %v", err)
+	}
+	if errResp.Error != "CommentNotFound" {
+		t.Errorf("Expected CommentNotFound error, got %q", errResp.Error)
+	}
+}
+
+// stubCommunityService serves one community to the age gate
+// Methods the gate doesn't call fall through to the nil embedded interface and panic.
+type stubCommunityService struct {
+	communities.Service
+	community *communities.Community
+}
+
+func (s *stubCommunityService) GetCommunity(ctx context.Context, identifier string) (*communities.Community, error) {
+	return s.community, nil
+}
+
+// stubAgeConfirmations holds confirmations as "user|community" keys
+type stubAgeConfirmations map[string]bool
+
+func (s stubAgeConfirmations) ConfirmAge(ctx context.Context, userDID, communityDID string) error {
+	s[userDID+"|"+communityDID] = true
+	return nil
+}
+
+func (s stubAgeConfirmations) HasConfirmedAge(ctx context.Context, userDID, communityDID string) (bool, error) {
+	return s[userDID+"|"+communityDID], nil
+}
+
+func TestGetCommentsHandler_AgeGate(t *testing.T) {
+	postURI := "at://did:plc:community/social.coves.community.post/3kpost"
+	focusURI := "at://did:plc:alice/social.coves.community.comment/3kfocus"
+	nsfw := &communities.Community{DID: "did:plc:community", Name: "adult", ContentWarnings: []string{"nsfw"}}
+	confirmations := stubAgeConfirmations{"did:plc:confirmed|did:plc:community": true}
+	post := &posts.PostView{URI: postURI, Community: &posts.CommunityRef{DID: nsfw.DID}}
+
+	tests := []struct {
+		name      string
+		query     string
+		viewerDID string
+		wantGated bool
+	}{
+		{name: "anonymous viewer", query: "?post=" + postURI, wantGated: true},
+		{name: "unconfirmed viewer", query: "?post=" + postURI, viewerDID: "did:plc:unconfirmed", wantGated: true},
+		{name: "confirmed viewer", query: "?post=" + postURI, viewerDID: "did:plc:confirmed"},
+		{name: "permalink for anonymous viewer", query: "?uri=" + focusURI, wantGated: true},
+		{name: "permalink for confirmed viewer", query: "?uri=" + focusURI, viewerDID: "did:plc:confirmed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockService{
+				post: post,
+				getCommentThreadFunc: func(r *http.Request, req *GetCommentThreadRequest) (*comments.GetCommentThreadResponse, error) {
+					return &comments.GetCommentThreadResponse{
+						Post: post,
+						Thread: &comments.CommentThreadView{
+							Focus:   &comments.CommentView{URI: focusURI, Post: &comments.CommentRef{URI: postURI}},
+							Replies: []*comments.ThreadViewComment{},
+						},
+					}, nil
+				},
+			}
+			handler := NewGetCommentsHandler(service)
+			handler.SetAgeGate(communities.NewAgeGate(&stubCommunityService{community: nsfw}, confirmations))
+
+			req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.community.comment.getComments"+tt.query, nil)
+			if tt.viewerDID != "" {
+				req = req.WithContext(middleware.SetTestUserDID(req.Context(), tt.viewerDID))
+			}
+			w := httptest.NewRecorder()
+			handler.HandleGetComments(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var body struct {
+				Post      map[string]interface{}             `json:"post"`
+				Thread    map[string]interface{}             `json:"thread"`
+				Community *communities.CommunityViewDetailed `json:"community"`
+				Comments  []interface{}                      `json:"comments"`
+				Gated     bool                               `json:"gated"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if body.Gated != tt.wantGated {
+				t.Fatalf("Expected gated=%t, got %s", tt.wantGated, w.Body.String())
+			}
+			if tt.wantGated {
+				if body.Post != nil || body.Thread != nil || len(body.Comments) != 0 {
+					t.Errorf("Expected no post or comments in a gated response, got %s", w.Body.String())
+				}
+				if body.Community == nil || body.Community.DID != nsfw.DID || !body.Community.AgeConfirmationRequired {
+					t.Errorf("Expected the community's metadata, got %+v", body.Community)
+				}
+			} else if body.Post == nil || body.Post["uri"] != postURI {
+				t.Errorf("Expected the post, got %s", w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package comments
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/comments"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// SearchCommentsHandler handles moderator comment search within a community
+type SearchCommentsHandler struct {
+	service comments.Service
+}
+
+// NewSearchCommentsHandler creates a new handler for searching a community's comments
+func NewSearchCommentsHandler(service comments.Service) *SearchCommentsHandler {
+	return &SearchCommentsHandler{
+		service: service,
+	}
+}
+
+// HandleSearch handles comment search requests
+// GET /xrpc/social.coves.community.comment.search?community=...&q=...&author=...&postUri=...&includeDeleted=false&limit=25&cursor=...
+//
+// Restricted to the community's owner and moderators, and instance admins
+func (h *SearchCommentsHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	// 1. Only allow GET method
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// 2. Get viewer DID from context (injected by auth middleware)
+	viewerDID := middleware.GetUserDID(r)
+	if viewerDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	// 3. Parse and validate query parameters
+	query := r.URL.Query()
+	req := &comments.SearchCommentsRequest{
+		ViewerDID: viewerDID,
+		Community: query.Get("community"),
+		Query:     strings.TrimSpace(query.Get("q")),
+	}
+	if req.Community == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community parameter is required")
+		return
+	}
+	if req.Query == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "q parameter is required")
+		return
+	}
+
+	if author := query.Get("author"); author != "" {
+		if !strings.HasPrefix(author, "did:") {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "author must be a DID")
+			return
+		}
+		req.Author = &author
+	}
+	if postURI := query.Get("postUri"); postURI != "" {
+		if !strings.HasPrefix(postURI, "at://") {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "postUri must be an AT-URI")
+			return
+		}
+		req.PostURI = &postURI
+	}
+
+	if includeDeleted := query.Get("includeDeleted"); includeDeleted != "" {
+		parsed, err := strconv.ParseBool(includeDeleted)
+		if err != nil {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "includeDeleted must be a boolean")
+			return
+		}
+		req.IncludeDeleted = parsed
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be a valid integer")
+			return
+		}
+		if parsed < 1 || parsed > 100 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be between 1 and 100")
+			return
+		}
+		req.Limit = parsed
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		req.Cursor = &cursor
+	}
+
+	// 4. Search comments
+	resp, err := h.service.SearchComments(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	// 5. Return results
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("Failed to encode response: %v", err)
+	}
+}
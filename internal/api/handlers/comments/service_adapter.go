@@ -35,3 +35,20 @@ func (a *ServiceAdapter) GetComments(r *http.Request, req *GetCommentsRequest) (
 	// Call core service with request context
 	return a.coreService.GetComments(r.Context(), coreReq)
 }
+
+// GetCommentThread adapts the handler request to the core service request
+// Converts handler-specific GetCommentThreadRequest to core GetCommentThreadRequest
+func (a *ServiceAdapter) GetCommentThread(r *http.Request, req *GetCommentThreadRequest) (*comments.GetCommentThreadResponse, error) {
+	coreReq := &comments.GetCommentThreadRequest{
+		CommentURI:   req.CommentURI,
+		Sort:         req.Sort,
+		Timeframe:    req.Timeframe,
+		ParentHeight: req.ParentHeight,
+		Depth:        req.Depth,
+		Limit:        req.Limit,
+		Cursor:       req.Cursor,
+		ViewerDID:    req.ViewerDID,
+	}
+
+	return a.coreService.GetCommentThread(r.Context(), coreReq)
+}
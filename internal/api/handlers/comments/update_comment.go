@@ -2,6 +2,7 @@ package comments
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/comments"
 	"encoding/json"
 	"log"
@@ -44,7 +45,7 @@ type UpdateCommentOutput struct {
 func (h *UpdateCommentHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 	// 1. Check method is POST
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -54,14 +55,14 @@ func (h *UpdateCommentHandler) HandleUpdate(w http.ResponseWriter, r *http.Reque
 	// 3. Parse JSON body into UpdateCommentInput
 	var input UpdateCommentInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	// 4. Get OAuth session from context (injected by auth middleware)
 	session := middleware.GetOAuthSession(r)
 	if session == nil {
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
@@ -70,12 +71,12 @@ func (h *UpdateCommentHandler) HandleUpdate(w http.ResponseWriter, r *http.Reque
 	if input.Labels != nil {
 		labelsJSON, err := json.Marshal(input.Labels)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "InvalidLabels", "Invalid labels format")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidLabels, "Invalid labels format")
 			return
 		}
 		var selfLabels comments.SelfLabels
 		if err := json.Unmarshal(labelsJSON, &selfLabels); err != nil {
-			writeError(w, http.StatusBadRequest, "InvalidLabels", "Invalid labels structure")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidLabels, "Invalid labels structure")
 			return
 		}
 		labels = &selfLabels
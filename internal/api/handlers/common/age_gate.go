@@ -0,0 +1,92 @@
+package common
+
+import (
+	"Coves/internal/core/communities"
+	coreerrors "Coves/internal/core/errors"
+	"Coves/internal/core/posts"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// AgeGatedFeedResponse replaces a community feed for viewers who haven't confirmed their age
+// The community's metadata is included so clients can show the confirmation screen.
+type AgeGatedFeedResponse struct {
+	Community *communities.CommunityViewDetailed `json:"community"`
+	Feed      []struct{}                         `json:"feed"`
+	Gated     bool                               `json:"gated"`
+}
+
+// AgeGatedPostResponse replaces a post for viewers who haven't confirmed their age
+type AgeGatedPostResponse struct {
+	Community *communities.CommunityViewDetailed `json:"community"`
+	Gated     bool                               `json:"gated"`
+}
+
+// AgeGatedCommentsResponse replaces a post's comments for viewers who haven't confirmed their age
+type AgeGatedCommentsResponse struct {
+	Community *communities.CommunityViewDetailed `json:"community"`
+	Comments  []struct{}                         `json:"comments"`
+	Gated     bool                               `json:"gated"`
+}
+
+// WriteAgeGatedFeed writes an empty, gated community feed
+func WriteAgeGatedFeed(w http.ResponseWriter, community *communities.Community) {
+	writeAgeGated(w, AgeGatedFeedResponse{Community: gatedView(community), Feed: []struct{}{}, Gated: true})
+}
+
+// WriteAgeGatedPost writes a gated post response carrying no post
+func WriteAgeGatedPost(w http.ResponseWriter, community *communities.Community) {
+	writeAgeGated(w, AgeGatedPostResponse{Community: gatedView(community), Gated: true})
+}
+
+// WriteAgeGatedComments writes an empty, gated comment listing
+func WriteAgeGatedComments(w http.ResponseWriter, community *communities.Community) {
+	writeAgeGated(w, AgeGatedCommentsResponse{Community: gatedView(community), Comments: []struct{}{}, Gated: true})
+}
+
+// DropAgeGatedPosts removes posts in communities that are age-gated for the viewer
+// Each community is checked once; posts whose community no longer exists are kept.
+func DropAgeGatedPosts(ctx context.Context, gate communities.AgeGate, viewerDID string, feed []*posts.FeedViewPost) ([]*posts.FeedViewPost, error) {
+	if gate == nil {
+		return feed, nil
+	}
+
+	gatedByDID := make(map[string]bool)
+	kept := make([]*posts.FeedViewPost, 0, len(feed))
+	for _, item := range feed {
+		if item == nil || item.Post == nil || item.Post.Community == nil {
+			kept = append(kept, item)
+			continue
+		}
+		did := item.Post.Community.DID
+		gated, checked := gatedByDID[did]
+		if !checked {
+			var err error
+			_, gated, err = gate.GatedCommunity(ctx, viewerDID, did)
+			if err != nil && !coreerrors.IsNotFound(err) {
+				return nil, err
+			}
+			gatedByDID[did] = gated
+		}
+		if !gated {
+			kept = append(kept, item)
+		}
+	}
+	return kept, nil
+}
+
+func gatedView(community *communities.Community) *communities.CommunityViewDetailed {
+	view := community.ToCommunityViewDetailed()
+	view.Gated = true
+	return view
+}
+
+func writeAgeGated(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("Failed to encode age-gated response: %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package common
+
+import (
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/blobs"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/posts"
+	"context"
+	"log"
+)
+
+// AggregatorLookup finds which DIDs belong to aggregators
+// Implemented by aggregators.Repository
+type AggregatorLookup interface {
+	GetServicesByDIDs(ctx context.Context, dids []string) ([]*aggregators.Aggregator, error)
+}
+
+// PopulateAggregatorAttribution sets Via on feed posts authored by an aggregator, so clients
+// can tell automated posts from human ones. All authors are checked in one query.
+// This is a no-op if lookup is nil; lookup failures are logged and leave posts unattributed.
+func PopulateAggregatorAttribution[T FeedPostProvider](
+	ctx context.Context,
+	lookup AggregatorLookup,
+	feedPosts []T,
+) {
+	if lookup == nil || len(feedPosts) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	authorDIDs := make([]string, 0, len(feedPosts))
+	for _, feedPost := range feedPosts {
+		post := feedPost.GetPost()
+		if post == nil || post.Author == nil || seen[post.Author.DID] {
+			continue
+		}
+		seen[post.Author.DID] = true
+		authorDIDs = append(authorDIDs, post.Author.DID)
+	}
+	if len(authorDIDs) == 0 {
+		return
+	}
+
+	aggs, err := lookup.GetServicesByDIDs(ctx, authorDIDs)
+	if err != nil {
+		log.Printf("Warning: failed to look up aggregator authors (%d authors): %v", len(authorDIDs), err)
+		return
+	}
+	if len(aggs) == 0 {
+		return
+	}
+
+	vias := make(map[string]*posts.ViaView, len(aggs))
+	for _, agg := range aggs {
+		via := &posts.ViaView{DID: agg.DID, DisplayName: agg.DisplayName}
+		// AvatarURL holds the blob CID
+		if agg.AvatarURL != "" {
+			if avatar := blobs.HydrateImageURL(communities.GetImageProxyConfig(), agg.PDSURL, agg.DID, agg.AvatarURL, "avatar_small"); avatar != "" {
+				via.Avatar = &avatar
+			}
+		}
+		vias[agg.DID] = via
+	}
+
+	for _, feedPost := range feedPosts {
+		if post := feedPost.GetPost(); post != nil && post.Author != nil {
+			if via, ok := vias[post.Author.DID]; ok {
+				post.Via = via
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package common
+
+import (
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/timeline"
+	"context"
+	"errors"
+	"testing"
+)
+
+type fakeAggregatorLookup struct {
+	err   error
+	aggs  []*aggregators.Aggregator
+	calls [][]string
+}
+
+func (f *fakeAggregatorLookup) GetServicesByDIDs(ctx context.Context, dids []string) ([]*aggregators.Aggregator, error) {
+	f.calls = append(f.calls, dids)
+	return f.aggs, f.err
+}
+
+func feedOf(authorDIDs ...string) []*timeline.FeedViewPost {
+	feed := make([]*timeline.FeedViewPost, 0, len(authorDIDs))
+	for _, did := range authorDIDs {
+		feed = append(feed, &timeline.FeedViewPost{Post: &posts.PostView{Author: &posts.AuthorView{DID: did}}})
+	}
+	return feed
+}
+
+func TestPopulateAggregatorAttribution(t *testing.T) {
+	t.Run("attributes aggregator posts with one lookup", func(t *testing.T) {
+		lookup := &fakeAggregatorLookup{aggs: []*aggregators.Aggregator{{
+			DID:         "did:plc:rssbot",
+			DisplayName: "RSS Bot",
+			AvatarURL:   "bafyavatar",
+			PDSURL:      "https://pds.example.com",
+		}}}
+		feed := feedOf("did:plc:rssbot", "did:plc:human", "did:plc:rssbot")
+
+		PopulateAggregatorAttribution(context.Background(), lookup, feed)
+
+		if len(lookup.calls) != 1 {
+			t.Fatalf("Expected a single batched lookup, got %d", len(lookup.calls))
+		}
+		if len(lookup.calls[0]) != 2 {
+			t.Errorf("Expected deduplicated author DIDs, got %v", lookup.calls[0])
+		}
+		for _, i := range []int{0, 2} {
+			via := feed[i].Post.Via
+			if via == nil || via.DID != "did:plc:rssbot" || via.DisplayName != "RSS Bot" {
+				t.Fatalf("Post %d: expected via the aggregator, got %+v", i, via)
+			}
+			if via.Avatar == nil || *via.Avatar == "" {
+				t.Errorf("Post %d: expected hydrated avatar URL", i)
+			}
+		}
+		if feed[1].Post.Via != nil {
+			t.Errorf("Human post should not be attributed, got %+v", feed[1].Post.Via)
+		}
+	})
+
+	t.Run("lookup failure leaves posts unattributed", func(t *testing.T) {
+		lookup := &fakeAggregatorLookup{err: errors.New("db down")}
+		feed := feedOf("did:plc:rssbot")
+
+		PopulateAggregatorAttribution(context.Background(), lookup, feed)
+
+		if feed[0].Post.Via != nil {
+			t.Errorf("Expected no attribution on error, got %+v", feed[0].Post.Via)
+		}
+	})
+
+	t.Run("nil lookup is a no-op", func(t *testing.T) {
+		feed := feedOf("did:plc:rssbot")
+		PopulateAggregatorAttribution[*timeline.FeedViewPost](context.Background(), nil, feed)
+		if feed[0].Post.Via != nil {
+			t.Error("Expected no attribution without a lookup")
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package common
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/discover"
+	coreerrors "Coves/internal/core/errors"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/takedown"
+	"Coves/internal/core/timeline"
+	"Coves/internal/core/votes"
+	"Coves/internal/lexicon/validate"
+	"errors"
+	"net/http"
+)
+
+// sentinelError maps a core sentinel error to the XRPC error it is reported as
+type sentinelError struct {
+	err     error
+	name    string
+	message string
+	status  int
+}
+
+// sentinelErrors is the single mapping from core sentinel errors to XRPC error names
+// Handlers check their endpoint-specific cases first and fall back to WriteSentinelError
+var sentinelErrors = []sentinelError{
+	{communities.ErrCommunityNotFound, xrpcerror.CommunityNotFound, "Community not found", http.StatusNotFound},
+	{posts.ErrCommunityNotFound, xrpcerror.CommunityNotFound, "Community not found", http.StatusNotFound},
+	{communityFeeds.ErrCommunityNotFound, xrpcerror.CommunityNotFound, "Community not found", http.StatusNotFound},
+	{discover.ErrCommunityNotFound, xrpcerror.CommunityNotFound, "Community not found", http.StatusNotFound},
+	{posts.ErrCommunitySuspended, xrpcerror.CommunitySuspended, "Community has been suspended", http.StatusForbidden},
+	{communities.ErrCommunityDeleted, xrpcerror.CommunityDeleted, "Community has been deleted", http.StatusGone},
+	{posts.ErrCommunityDeleted, xrpcerror.CommunityDeleted, "Community has been deleted", http.StatusGone},
+	{posts.ErrCommunityFederationBlocked, xrpcerror.FederationBlocked, "Community is hosted on a blocked instance", http.StatusForbidden},
+	{posts.ErrNotFound, xrpcerror.PostNotFound, "Post not found", http.StatusNotFound},
+	{posts.ErrActorNotFound, xrpcerror.ActorNotFound, "Actor not found", http.StatusNotFound},
+	{comments.ErrCommentNotFound, xrpcerror.CommentNotFound, "Comment not found", http.StatusNotFound},
+	{comments.ErrParentNotFound, xrpcerror.ParentNotFound, "Parent post or comment not found", http.StatusNotFound},
+	{comments.ErrRootNotFound, xrpcerror.RootNotFound, "Root post not found", http.StatusNotFound},
+	{votes.ErrVoteNotFound, xrpcerror.VoteNotFound, "No vote found for this subject", http.StatusNotFound},
+	{aggregators.ErrAggregatorNotFound, xrpcerror.AggregatorNotFound, "Aggregator not found", http.StatusNotFound},
+	{posts.ErrInvalidCursor, xrpcerror.InvalidCursor, "Invalid pagination cursor", http.StatusBadRequest},
+	{communityFeeds.ErrInvalidCursor, xrpcerror.InvalidCursor, "Invalid pagination cursor", http.StatusBadRequest},
+	{timeline.ErrInvalidCursor, xrpcerror.InvalidCursor, "Invalid pagination cursor", http.StatusBadRequest},
+	{discover.ErrInvalidCursor, xrpcerror.InvalidCursor, "Invalid pagination cursor", http.StatusBadRequest},
+	{comments.ErrInvalidCursor, xrpcerror.InvalidCursor, "Invalid pagination cursor", http.StatusBadRequest},
+	{communities.ErrInvalidCursor, xrpcerror.InvalidCursor, "Invalid pagination cursor", http.StatusBadRequest},
+	{posts.ErrRateLimitExceeded, xrpcerror.RateLimitExceeded, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests},
+	{aggregators.ErrRateLimitExceeded, xrpcerror.RateLimitExceeded, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests},
+}
+
+// WriteSentinelError writes the XRPC error for a known core sentinel error
+// Returns false, writing nothing, when err doesn't wrap one
+func WriteSentinelError(w http.ResponseWriter, err error) bool {
+	// Taken-down records report their reason category, and nothing else about the takedown
+	var takenDown *takedown.TakenDownError
+	if errors.As(err, &takenDown) {
+		xrpcerror.WriteError(w, http.StatusGone, xrpcerror.RecordTakenDown, "Record has been taken down: "+string(takenDown.Reason))
+		return true
+	}
+	// Aggregator post limits say which window tripped and when it resets
+	var rateLimited *aggregators.RateLimitError
+	if errors.As(err, &rateLimited) {
+		xrpcerror.WriteError(w, http.StatusTooManyRequests, xrpcerror.RateLimitExceeded, rateLimited.Error())
+		return true
+	}
+	// Invalid records list every violated rule so clients can fix all fields at once
+	var invalidRecord *validate.Error
+	if errors.As(err, &invalidRecord) {
+		xrpcerror.WriteErrorDetails(w, http.StatusBadRequest, xrpcerror.InvalidRequest,
+			"Record is invalid: "+invalidRecord.Collection, invalidRecord.Violations)
+		return true
+	}
+	for _, s := range sentinelErrors {
+		if errors.Is(err, s.err) {
+			xrpcerror.WriteError(w, s.status, s.name, s.message)
+			return true
+		}
+	}
+	return false
+}
+
+// errorKinds maps core error kinds to generic XRPC errors
+var errorKinds = []sentinelError{
+	{coreerrors.ErrNotFound, xrpcerror.NotFound, "Not found", http.StatusNotFound},
+	{coreerrors.ErrAlreadyExists, xrpcerror.AlreadyExists, "Already exists", http.StatusConflict},
+	{coreerrors.ErrForbidden, xrpcerror.Forbidden, "You do not have permission to perform this action", http.StatusForbidden},
+	{coreerrors.ErrUnauthorized, xrpcerror.AuthRequired, "Authentication required", http.StatusUnauthorized},
+	{coreerrors.ErrInvalidInput, xrpcerror.InvalidRequest, "Invalid request", http.StatusBadRequest},
+}
+
+// WriteErrorKind writes a generic XRPC error for a core error by its kind (not found,
+// already exists, forbidden...), using the sentinel's own message
+// Handlers call it after their specific cases, before falling back to an internal error.
+// Returns false, writing nothing, when err has no kind.
+func WriteErrorKind(w http.ResponseWriter, err error) bool {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			message := coreerrors.SentinelMessage(err)
+			if message == "" {
+				message = k.message
+			}
+			xrpcerror.WriteError(w, k.status, k.name, message)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package common
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	coreerrors "Coves/internal/core/errors"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/takedown"
+	"Coves/internal/core/timeline"
+	"Coves/internal/core/users"
+	"Coves/internal/core/votes"
+	"Coves/internal/lexicon/validate"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteSentinelError(t *testing.T) {
+	tests := []struct {
+		err            error
+		name           string
+		expectedError  string
+		expectedStatus int
+	}{
+		{
+			name:           "community not found",
+			err:            communities.ErrCommunityNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  xrpcerror.CommunityNotFound,
+		},
+		{
+			name:           "wrapped post not found",
+			err:            fmt.Errorf("loading post: %w", posts.ErrNotFound),
+			expectedStatus: http.StatusNotFound,
+			expectedError:  xrpcerror.PostNotFound,
+		},
+		{
+			name:           "parent not found",
+			err:            comments.ErrParentNotFound,
+			expectedStatus: http.StatusNotFound,
+			expectedError:  xrpcerror.ParentNotFound,
+		},
+		{
+			name:           "invalid cursor",
+			err:            timeline.ErrInvalidCursor,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  xrpcerror.InvalidCursor,
+		},
+		{
+			name:           "rate limited",
+			err:            posts.ErrRateLimitExceeded,
+			expectedStatus: http.StatusTooManyRequests,
+			expectedError:  xrpcerror.RateLimitExceeded,
+		},
+		{
+			name:           "record taken down",
+			err:            fmt.Errorf("fetching post: %w", &takedown.TakenDownError{Reason: takedown.ReasonLegal}),
+			expectedStatus: http.StatusGone,
+			expectedError:  xrpcerror.RecordTakenDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if !WriteSentinelError(w, tt.err) {
+				t.Fatal("Expected sentinel error to be handled")
+			}
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp xrpcerror.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Error != tt.expectedError {
+				t.Errorf("Expected error %s, got %s", tt.expectedError, resp.Error)
+			}
+			if resp.Message == "" {
+				t.Error("Expected a message")
+			}
+		})
+	}
+}
+
+func TestWriteSentinelError_AggregatorRateLimit(t *testing.T) {
+	resetAt := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	err := fmt.Errorf("validating post: %w",
+		&aggregators.RateLimitError{Window: aggregators.RateLimitWindowDay, Limit: 20, ResetAt: resetAt})
+
+	w := httptest.NewRecorder()
+	if !WriteSentinelError(w, err) {
+		t.Fatal("Expected rate limit error to be handled")
+	}
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status 429, got %d", w.Code)
+	}
+	var resp xrpcerror.Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Error != xrpcerror.RateLimitExceeded {
+		t.Errorf("Expected error %s, got %s", xrpcerror.RateLimitExceeded, resp.Error)
+	}
+	if !strings.Contains(resp.Message, "20 posts per day") || !strings.Contains(resp.Message, "2026-03-01T12:30:00Z") {
+		t.Errorf("Expected the window and reset time in the message, got %q", resp.Message)
+	}
+}
+
+func TestWriteSentinelError_InvalidRecord(t *testing.T) {
+	err := fmt.Errorf("building post: %w", validate.Check(validate.PostCollection, []validate.Violation{
+		{Field: "title", Code: validate.CodeTooLong, Message: "title exceeds maximum length (300 graphemes): got 301 graphemes"},
+		{Field: "tags", Code: validate.CodeTooMany, Message: "at most 8 tags are allowed: got 9"},
+	}))
+
+	w := httptest.NewRecorder()
+	if !WriteSentinelError(w, err) {
+		t.Fatal("Expected invalid record error to be handled")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+	var resp struct {
+		Error   string               `json:"error"`
+		Details []validate.Violation `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Error != xrpcerror.InvalidRequest {
+		t.Errorf("Expected error %s, got %s", xrpcerror.InvalidRequest, resp.Error)
+	}
+	if len(resp.Details) != 2 || resp.Details[0].Field != "title" || resp.Details[1].Code != validate.CodeTooMany {
+		t.Errorf("Expected every violation as a detail, got %+v", resp.Details)
+	}
+}
+
+func TestWriteSentinelError_UnknownError(t *testing.T) {
+	w := httptest.NewRecorder()
+	if WriteSentinelError(w, errors.New("database exploded")) {
+		t.Fatal("Expected unknown error not to be handled")
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("Expected nothing written, got %q", w.Body.String())
+	}
+}
+
+func TestWriteErrorKind(t *testing.T) {
+	tests := []struct {
+		err             error
+		name            string
+		expectedError   string
+		expectedMessage string
+		expectedStatus  int
+	}{
+		{
+			name:            "wrapped vote not found",
+			err:             fmt.Errorf("deleting vote: %w", fmt.Errorf("at://did:plc:alice/vote/1: %w", votes.ErrVoteNotFound)),
+			expectedStatus:  http.StatusNotFound,
+			expectedError:   xrpcerror.NotFound,
+			expectedMessage: "vote not found",
+		},
+		{
+			name:            "user already exists",
+			err:             fmt.Errorf("creating user: %w", users.ErrUserAlreadyExists),
+			expectedStatus:  http.StatusConflict,
+			expectedError:   xrpcerror.AlreadyExists,
+			expectedMessage: users.ErrUserAlreadyExists.Error(),
+		},
+		{
+			name:            "thread locked",
+			err:             comments.ErrThreadLocked,
+			expectedStatus:  http.StatusForbidden,
+			expectedError:   xrpcerror.Forbidden,
+			expectedMessage: comments.ErrThreadLocked.Error(),
+		},
+		{
+			name:            "bare kind",
+			err:             fmt.Errorf("lookup: %w", coreerrors.ErrNotFound),
+			expectedStatus:  http.StatusNotFound,
+			expectedError:   xrpcerror.NotFound,
+			expectedMessage: "Not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if !WriteErrorKind(w, tt.err) {
+				t.Fatal("Expected error kind to be handled")
+			}
+			if w.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp xrpcerror.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Error != tt.expectedError {
+				t.Errorf("Expected error %s, got %s", tt.expectedError, resp.Error)
+			}
+			if resp.Message != tt.expectedMessage {
+				t.Errorf("Expected message %q without wrapping context, got %q", tt.expectedMessage, resp.Message)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	if WriteErrorKind(w, errors.New("database exploded")) {
+		t.Error("Expected an error without a kind not to be handled")
+	}
+}
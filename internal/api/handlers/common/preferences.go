@@ -0,0 +1,32 @@
+package common
+
+import (
+	"Coves/internal/core/users"
+	"context"
+	"errors"
+	"log"
+)
+
+// PreferencesLookup reads a viewer's stored preferences
+// Implemented by users.PreferencesRepository
+type PreferencesLookup interface {
+	Get(ctx context.Context, userDID string) (*users.Preferences, error)
+}
+
+// LoadViewerPreferences returns the viewer's stored preferences merged over the server
+// defaults. Anonymous viewers, a nil lookup and failed reads get the defaults; failures are
+// logged so a preferences outage degrades feeds to their defaults rather than failing them.
+func LoadViewerPreferences(ctx context.Context, lookup PreferencesLookup, viewerDID string) *users.ViewerPreferences {
+	if lookup == nil || viewerDID == "" {
+		return users.DefaultViewerPreferences()
+	}
+
+	stored, err := lookup.Get(ctx, viewerDID)
+	if err != nil {
+		if !errors.Is(err, users.ErrPreferencesNotFound) {
+			log.Printf("Warning: Failed to load preferences for %s: %v", viewerDID, err)
+		}
+		return users.DefaultViewerPreferences()
+	}
+	return users.MergePreferences(stored.Record)
+}
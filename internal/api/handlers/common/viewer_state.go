@@ -8,6 +8,7 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"time"
 )
 
 // FeedPostProvider is implemented by any feed post wrapper that contains a PostView.
@@ -73,6 +74,75 @@ func PopulateViewerVoteState[T FeedPostProvider](
 	}
 }
 
+// ModeratorLookup reports which communities a user created or moderates, and who moderates a community
+// Implemented by communities.Repository
+type ModeratorLookup interface {
+	GetModeratedCommunityDIDs(ctx context.Context, userDID string, communityDIDs []string) (map[string]bool, error)
+	GetModeratorsForCommunities(ctx context.Context, communityDIDs []string) (map[string]map[string]bool, error)
+}
+
+// HideFeedScores nulls vote counts on feed posts inside their community's score hiding window.
+// Post authors and community moderators see real counts. When moderators is nil or the lookup
+// fails, counts stay hidden from moderators too - hiding is the safe failure mode.
+func HideFeedScores[T FeedPostProvider](
+	ctx context.Context,
+	r *http.Request,
+	moderators ModeratorLookup,
+	feedPosts []T,
+) {
+	postViews := make([]*posts.PostView, 0, len(feedPosts))
+	for _, feedPost := range feedPosts {
+		if post := feedPost.GetPost(); post != nil {
+			postViews = append(postViews, post)
+		}
+	}
+
+	now := time.Now()
+	userDID := middleware.GetUserDID(r)
+
+	var moderated map[string]bool
+	if userDID != "" && moderators != nil {
+		if communityDIDs := posts.NeedsModeratorCheck(postViews, userDID, now); len(communityDIDs) > 0 {
+			var err error
+			moderated, err = moderators.GetModeratedCommunityDIDs(ctx, userDID, communityDIDs)
+			if err != nil {
+				log.Printf("Warning: failed to get moderated communities for user %s: %v", userDID, err)
+			}
+		}
+	}
+
+	posts.HideScores(postViews, userDID, moderated, now)
+}
+
+// AddFeedAuthorBadges marks feed post authors who moderate the post's community or are the community.
+// Moderators of every community in the page are loaded in one query. When moderators is nil or
+// the lookup fails, only the community badges are set.
+func AddFeedAuthorBadges[T FeedPostProvider](
+	ctx context.Context,
+	moderators ModeratorLookup,
+	feedPosts []T,
+) {
+	postViews := make([]*posts.PostView, 0, len(feedPosts))
+	for _, feedPost := range feedPosts {
+		if post := feedPost.GetPost(); post != nil {
+			postViews = append(postViews, post)
+		}
+	}
+
+	var moderatorsByCommunity map[string]map[string]bool
+	if moderators != nil {
+		if communityDIDs := posts.BadgeCommunities(postViews); len(communityDIDs) > 0 {
+			var err error
+			moderatorsByCommunity, err = moderators.GetModeratorsForCommunities(ctx, communityDIDs)
+			if err != nil {
+				log.Printf("Warning: failed to get moderators for %d communities: %v", len(communityDIDs), err)
+			}
+		}
+	}
+
+	posts.AddAuthorBadges(postViews, moderatorsByCommunity)
+}
+
 // PopulateCommunityViewerState enriches communities with the authenticated user's subscription state.
 // This is a no-op if the request is unauthenticated.
 func PopulateCommunityViewerState(
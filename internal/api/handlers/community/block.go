@@ -2,6 +2,7 @@ package community
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/communities"
 	"encoding/json"
 	"log"
@@ -28,7 +29,7 @@ func NewBlockHandler(service communities.Service) *BlockHandler {
 // The block record's "subject" field requires format: "did", so we resolve the identifier internally.
 func (h *BlockHandler) HandleBlock(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -38,12 +39,12 @@ func (h *BlockHandler) HandleBlock(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	if req.Community == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "community is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
 		return
 	}
 
@@ -51,7 +52,7 @@ func (h *BlockHandler) HandleBlock(w http.ResponseWriter, r *http.Request) {
 	// The session contains the user's DID and credentials needed for DPoP authentication
 	session := middleware.GetOAuthSession(r)
 	if session == nil {
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
@@ -85,7 +86,7 @@ func (h *BlockHandler) HandleBlock(w http.ResponseWriter, r *http.Request) {
 // Accepts DIDs (did:plc:xxx), handles (@gaming.community.coves.social), or scoped (!gaming@coves.social)
 func (h *BlockHandler) HandleUnblock(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -95,12 +96,12 @@ func (h *BlockHandler) HandleUnblock(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	if req.Community == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "community is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
 		return
 	}
 
@@ -108,7 +109,7 @@ func (h *BlockHandler) HandleUnblock(w http.ResponseWriter, r *http.Request) {
 	// The session contains the user's DID and credentials needed for DPoP authentication
 	session := middleware.GetOAuthSession(r)
 	if session == nil {
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
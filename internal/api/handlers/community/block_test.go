@@ -33,10 +33,26 @@ func (m *blockTestService) UpdateCommunity(ctx context.Context, req communities.
 	return nil, nil
 }
 
-func (m *blockTestService) ListCommunities(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error) {
+func (m *blockTestService) GetCommunityRules(ctx context.Context, communityDID string) (*communities.CommunityRules, error) {
 	return nil, nil
 }
 
+func (m *blockTestService) UpdateCommunityRules(ctx context.Context, req communities.UpdateRulesRequest) (*communities.CommunityRules, error) {
+	return nil, nil
+}
+
+func (m *blockTestService) DeleteCommunity(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error) {
+	return nil, nil
+}
+
+func (m *blockTestService) UndeleteCommunity(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error) {
+	return nil, nil
+}
+
+func (m *blockTestService) ListCommunities(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, *string, error) {
+	return nil, nil, nil
+}
+
 func (m *blockTestService) SearchCommunities(ctx context.Context, req communities.SearchCommunitiesRequest) ([]*communities.Community, int, error) {
 	return nil, 0, nil
 }
@@ -49,14 +65,22 @@ func (m *blockTestService) UnsubscribeFromCommunity(ctx context.Context, session
 	return nil
 }
 
-func (m *blockTestService) GetUserSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*communities.Subscription, error) {
-	return nil, nil
+func (m *blockTestService) GetUserSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
 }
 
-func (m *blockTestService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit, offset int) ([]*communities.Subscription, error) {
+func (m *blockTestService) GetSubscribedCommunities(ctx context.Context, req communities.GetSubscriptionsRequest) ([]*communities.SubscribedCommunity, error) {
 	return nil, nil
 }
 
+func (m *blockTestService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
+}
+
+func (m *blockTestService) IsSubscribed(ctx context.Context, userDID, communityDID string) (bool, error) {
+	return false, nil
+}
+
 func (m *blockTestService) BlockCommunity(ctx context.Context, session *oauth.ClientSessionData, communityIdentifier string) (*communities.CommunityBlock, error) {
 	if m.blockFunc != nil {
 		return m.blockFunc(ctx, session, communityIdentifier)
@@ -281,7 +305,7 @@ func TestBlockHandler_Block_ServiceErrors(t *testing.T) {
 			name:           "community not found",
 			serviceErr:     communities.ErrCommunityNotFound,
 			expectedStatus: http.StatusNotFound,
-			expectedError:  "NotFound",
+			expectedError:  "CommunityNotFound",
 		},
 		{
 			name:           "validation error",
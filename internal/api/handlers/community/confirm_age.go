@@ -0,0 +1,60 @@
+package community
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/communities"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// ConfirmAgeHandler records viewers' age confirmations for age-restricted communities
+type ConfirmAgeHandler struct {
+	gate communities.AgeGate
+}
+
+// NewConfirmAgeHandler creates a new confirm age handler
+func NewConfirmAgeHandler(gate communities.AgeGate) *ConfirmAgeHandler {
+	return &ConfirmAgeHandler{gate: gate}
+}
+
+// HandleConfirmAge records that the caller is over 18, lifting the age gate on the community
+// POST /xrpc/social.coves.community.confirmAge
+//
+// Request body: { "community": "<identifier>" }
+func (h *ConfirmAgeHandler) HandleConfirmAge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	var req struct {
+		Community string `json:"community"` // DID, handle, or scoped identifier
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	if req.Community == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
+		return
+	}
+
+	if err := h.gate.ConfirmAge(r.Context(), userDID, req.Community); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("{}\n")); err != nil {
+		log.Printf("Failed to write confirmAge response: %v", err)
+	}
+}
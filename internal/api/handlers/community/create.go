@@ -2,6 +2,7 @@ package community
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/communities"
 	"encoding/json"
 	"net/http"
@@ -40,41 +41,41 @@ func NewCreateHandler(service communities.Service, allowedCreators []string) *Cr
 // Body matches CreateCommunityRequest
 func (h *CreateHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Parse request body
 	var req communities.CreateCommunityRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	// Extract authenticated user DID from request context (injected by auth middleware)
 	userDID := middleware.GetUserDID(r)
 	if userDID == "" {
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
 	// Check if user is allowed to create communities (if restriction is enabled)
 	if h.allowedCommunityCreators != nil && !h.allowedCommunityCreators[userDID] {
-		writeError(w, http.StatusForbidden, "CommunityCreationRestricted",
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.CommunityCreationRestricted,
 			"Community creation is restricted to authorized users")
 		return
 	}
 
 	// Client should not send createdByDid - we derive it from authenticated user
 	if req.CreatedByDID != "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest",
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest,
 			"createdByDid must not be provided - derived from authenticated user")
 		return
 	}
 
 	// Client should not send hostedByDid - we derive it from the instance
 	if req.HostedByDID != "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest",
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest,
 			"hostedByDid must not be provided - derived from instance")
 		return
 	}
@@ -43,10 +43,26 @@ func (m *mockCommunityService) UpdateCommunity(ctx context.Context, req communit
 	return nil, nil
 }
 
-func (m *mockCommunityService) ListCommunities(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error) {
+func (m *mockCommunityService) GetCommunityRules(ctx context.Context, communityDID string) (*communities.CommunityRules, error) {
 	return nil, nil
 }
 
+func (m *mockCommunityService) UpdateCommunityRules(ctx context.Context, req communities.UpdateRulesRequest) (*communities.CommunityRules, error) {
+	return nil, nil
+}
+
+func (m *mockCommunityService) DeleteCommunity(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error) {
+	return nil, nil
+}
+
+func (m *mockCommunityService) UndeleteCommunity(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error) {
+	return nil, nil
+}
+
+func (m *mockCommunityService) ListCommunities(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, *string, error) {
+	return nil, nil, nil
+}
+
 func (m *mockCommunityService) SearchCommunities(ctx context.Context, req communities.SearchCommunitiesRequest) ([]*communities.Community, int, error) {
 	return nil, 0, nil
 }
@@ -59,14 +75,22 @@ func (m *mockCommunityService) UnsubscribeFromCommunity(ctx context.Context, ses
 	return nil
 }
 
-func (m *mockCommunityService) GetUserSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*communities.Subscription, error) {
-	return nil, nil
+func (m *mockCommunityService) GetUserSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
 }
 
-func (m *mockCommunityService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit, offset int) ([]*communities.Subscription, error) {
+func (m *mockCommunityService) GetSubscribedCommunities(ctx context.Context, req communities.GetSubscriptionsRequest) ([]*communities.SubscribedCommunity, error) {
 	return nil, nil
 }
 
+func (m *mockCommunityService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
+}
+
+func (m *mockCommunityService) IsSubscribed(ctx context.Context, userDID, communityDID string) (bool, error) {
+	return false, nil
+}
+
 func (m *mockCommunityService) BlockCommunity(ctx context.Context, session *oauth.ClientSessionData, communityIdentifier string) (*communities.CommunityBlock, error) {
 	return nil, nil
 }
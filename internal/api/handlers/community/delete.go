@@ -0,0 +1,93 @@
+package community
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/communities"
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+)
+
+// DeleteHandler handles community deletion and undeletion
+type DeleteHandler struct {
+	service communities.Service
+}
+
+// NewDeleteHandler creates a new delete handler
+func NewDeleteHandler(service communities.Service) *DeleteHandler {
+	return &DeleteHandler{
+		service: service,
+	}
+}
+
+// deleteResponse reports a community's deletion state
+// DeactivatesAt is when the grace period ends and undelete stops working; omitted after an undelete.
+type deleteResponse struct {
+	DeletedAt     *time.Time `json:"deletedAt,omitempty"`
+	DeactivatesAt *time.Time `json:"deactivatesAt,omitempty"`
+	Community     string     `json:"community"`
+}
+
+// HandleDelete soft-deletes a community
+// POST /xrpc/social.coves.community.delete
+// Body: {"community": "did:plc:xxx"}
+func (h *DeleteHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.service.DeleteCommunity)
+}
+
+// HandleUndelete restores a deleted community within its grace period
+// POST /xrpc/social.coves.community.undelete
+// Body: {"community": "did:plc:xxx"}
+func (h *DeleteHandler) HandleUndelete(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.service.UndeleteCommunity)
+}
+
+func (h *DeleteHandler) handle(
+	w http.ResponseWriter,
+	r *http.Request,
+	action func(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error),
+) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req communities.DeleteCommunityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+
+	if req.CommunityDID == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
+		return
+	}
+
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+	req.ActorDID = userDID
+
+	community, err := action(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	response := deleteResponse{Community: community.DID, DeletedAt: community.DeletedAt}
+	if community.DeletedAt != nil {
+		deactivatesAt := community.DeletedAt.Add(communities.DeletionGracePeriod)
+		response.DeactivatesAt = &deactivatesAt
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("Failed to encode community delete response: %v", err)
+	}
+}
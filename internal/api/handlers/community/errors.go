@@ -1,62 +1,79 @@
 package community
 
 import (
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/atproto/pds"
 	"Coves/internal/core/communities"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
 )
 
-// XRPCError represents an XRPC error response
-type XRPCError struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-}
+// handleServiceError converts service errors to appropriate HTTP responses
+func handleServiceError(w http.ResponseWriter, err error) {
+	// Checked first: the wrapped step error would otherwise pick the status
+	var provisioningErr *communities.ProvisioningError
+	if errors.As(err, &provisioningErr) {
+		log.Printf("Community creation incomplete: %v", err)
+		xrpcerror.WriteError(w, http.StatusServiceUnavailable, xrpcerror.CommunityCreationIncomplete,
+			fmt.Sprintf("Community creation did not finish (%s); retry with the same name to resume", provisioningErr.State))
+		return
+	}
 
-// writeError writes an XRPC error response
-func writeError(w http.ResponseWriter, status int, error, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	if err := json.NewEncoder(w).Encode(XRPCError{
-		Error:   error,
-		Message: message,
-	}); err != nil {
-		log.Printf("Failed to encode error response: %v", err)
+	if common.WriteSentinelError(w, err) {
+		return
 	}
-}
 
-// handleServiceError converts service errors to appropriate HTTP responses
-func handleServiceError(w http.ResponseWriter, err error) {
 	switch {
+	// Creation policy rejections each get a distinct error name so clients can explain them
+	case errors.Is(err, communities.ErrInvalidCommunityName):
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidCommunityName, err.Error())
+	case errors.Is(err, communities.ErrCommunityNameReserved):
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.CommunityNameReserved, err.Error())
+	case errors.Is(err, communities.ErrAccountTooNew):
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.AccountTooNew, err.Error())
+	case errors.Is(err, communities.ErrTooManyCommunities):
+		xrpcerror.WriteError(w, http.StatusTooManyRequests, xrpcerror.TooManyCommunities, err.Error())
 	case communities.IsNotFound(err):
-		writeError(w, http.StatusNotFound, "NotFound", err.Error())
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.NotFound, err.Error())
 	case communities.IsConflict(err):
 		if err == communities.ErrHandleTaken {
-			writeError(w, http.StatusConflict, "NameTaken", "Community handle is already taken")
+			xrpcerror.WriteError(w, http.StatusConflict, xrpcerror.NameTaken, "Community handle is already taken")
 		} else {
-			writeError(w, http.StatusConflict, "AlreadyExists", err.Error())
+			xrpcerror.WriteError(w, http.StatusConflict, xrpcerror.AlreadyExists, err.Error())
 		}
 	case communities.IsValidationError(err):
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 	case err == communities.ErrUnauthorized:
-		writeError(w, http.StatusForbidden, "Forbidden", "You do not have permission to perform this action")
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.Forbidden, "You do not have permission to perform this action")
 	case err == communities.ErrMemberBanned:
-		writeError(w, http.StatusForbidden, "Blocked", "You are blocked from this community")
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.Blocked, "You are blocked from this community")
+	case errors.Is(err, communities.ErrFederationBlocked):
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.FederationBlocked, "This community is hosted on an instance blocked by this server")
+	case errors.Is(err, communities.ErrCommunitySuspended):
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.CommunitySuspended, "This community has been suspended by this server")
+	case errors.Is(err, communities.ErrCommunityNotDeleted):
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "This community is not deleted")
+	case errors.Is(err, communities.ErrDeletionGracePeriodOver):
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.Forbidden, "The deletion grace period has ended and the community can no longer be restored")
 	// PDS-specific errors (from DPoP authentication or PDS API calls)
 	case errors.Is(err, pds.ErrBadRequest):
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request to PDS")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request to PDS")
 	case errors.Is(err, pds.ErrNotFound):
-		writeError(w, http.StatusNotFound, "NotFound", "Record not found on PDS")
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.NotFound, "Record not found on PDS")
 	case errors.Is(err, pds.ErrConflict):
-		writeError(w, http.StatusConflict, "Conflict", "Record was modified by another operation")
+		xrpcerror.WriteError(w, http.StatusConflict, xrpcerror.Conflict, "Record was modified by another operation")
 	case errors.Is(err, pds.ErrUnauthorized), errors.Is(err, pds.ErrForbidden):
 		// PDS auth errors should prompt re-authentication
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required or session expired")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required or session expired")
 	default:
+		if common.WriteErrorKind(w, err) {
+			return
+		}
 		// Internal server error - log the actual error for debugging
 		log.Printf("XRPC handler error: %v", err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError", "An internal error occurred")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
 	}
 }
@@ -2,15 +2,19 @@ package community
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/communities"
 )
 
 // GetHandler handles community retrieval
 type GetHandler struct {
 	service communities.Service
+	ageGate communities.AgeGate
 }
 
 // NewGetHandler creates a new get handler
@@ -20,18 +24,23 @@ func NewGetHandler(service communities.Service) *GetHandler {
 	}
 }
 
+// SetAgeGate marks age-restricted communities gated for viewers who haven't confirmed their age
+func (h *GetHandler) SetAgeGate(gate communities.AgeGate) {
+	h.ageGate = gate
+}
+
 // HandleGet retrieves a community by DID or handle
 // GET /xrpc/social.coves.community.get?community={did_or_handle}
 func (h *GetHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Get community identifier from query params
 	communityID := r.URL.Query().Get("community")
 	if communityID == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "community parameter is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community parameter is required")
 		return
 	}
 
@@ -45,6 +54,26 @@ func (h *GetHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	// Convert to detailed view for API response
 	view := community.ToCommunityViewDetailed()
 
+	// Age-restricted communities are still described to gated viewers, flagged so clients ask
+	// for confirmation before loading posts
+	if h.ageGate != nil {
+		gated, gateErr := h.ageGate.Gated(r.Context(), middleware.GetUserDID(r), community)
+		if gateErr != nil {
+			log.Printf("Failed to check age confirmation for community %s: %v", community.DID, gateErr)
+			gated = true
+		}
+		view.Gated = gated
+	}
+
+	// Attach the rules document; a failure here shouldn't hide the community
+	rules, err := h.service.GetCommunityRules(r.Context(), community.DID)
+	switch {
+	case err == nil:
+		view.Rules = rules
+	case !errors.Is(err, communities.ErrRulesNotFound):
+		log.Printf("Failed to load rules for community %s: %v", community.DID, err)
+	}
+
 	// Return community data
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
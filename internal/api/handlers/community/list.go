@@ -3,6 +3,7 @@ package community
 import (
 	"Coves/internal/api/handlers/common"
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/communities"
 	"encoding/json"
 	"log"
@@ -25,10 +26,11 @@ func NewListHandler(service communities.Service, repo communities.Repository) *L
 }
 
 // HandleList lists communities with filters
-// GET /xrpc/social.coves.community.list?limit={n}&cursor={str}&sort={popular|active|new|alphabetical}&visibility={public|unlisted|private}
+// GET /xrpc/social.coves.community.list?limit={n}&cursor={str}&sort={popular|active|new|alphabetical|relevance}&visibility={public|unlisted|private}
+// The relevance sort is personal: unauthenticated callers get the popular order instead.
 func (h *ListHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -40,7 +42,7 @@ func (h *ListHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	if limitStr := query.Get("limit"); limitStr != "" {
 		l, err := strconv.Atoi(limitStr)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid limit parameter: must be an integer")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid limit parameter: must be an integer")
 			return
 		}
 		if l < 1 {
@@ -52,21 +54,6 @@ func (h *ListHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Parse cursor (offset-based for now)
-	offset := 0
-	if cursorStr := query.Get("cursor"); cursorStr != "" {
-		o, err := strconv.Atoi(cursorStr)
-		if err != nil {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid cursor parameter: must be an integer")
-			return
-		}
-		if o < 0 {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid cursor parameter: must be non-negative")
-			return
-		}
-		offset = o
-	}
-
 	// Parse sort enum (default: popular)
 	sort := query.Get("sort")
 	if sort == "" {
@@ -75,16 +62,39 @@ func (h *ListHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 
 	// Validate sort value
 	validSorts := map[string]bool{
-		"popular":      true,
-		"active":       true,
-		"new":          true,
-		"alphabetical": true,
+		"popular":                     true,
+		"active":                      true,
+		"new":                         true,
+		"alphabetical":                true,
+		communities.ListSortRelevance: true,
 	}
 	if !validSorts[sort] {
-		http.Error(w, "Invalid sort value. Must be: popular, active, new, or alphabetical", http.StatusBadRequest)
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid sort value. Must be: popular, active, new, alphabetical, or relevance")
 		return
 	}
 
+	viewerDID := middleware.GetUserDID(r)
+	if sort == communities.ListSortRelevance && viewerDID == "" {
+		sort = "popular"
+	}
+	// Relevance pages by signed keyset cursor; every other sort by offset
+	keysetCursor := sort == communities.ListSortRelevance
+
+	// Parse cursor
+	offset := 0
+	if cursorStr := query.Get("cursor"); cursorStr != "" && !keysetCursor {
+		o, err := strconv.Atoi(cursorStr)
+		if err != nil {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid cursor parameter: must be an integer")
+			return
+		}
+		if o < 0 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid cursor parameter: must be non-negative")
+			return
+		}
+		offset = o
+	}
+
 	// Validate visibility value if provided
 	visibility := query.Get("visibility")
 	if visibility != "" {
@@ -94,7 +104,7 @@ func (h *ListHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 			"private":  true,
 		}
 		if !validVisibilities[visibility] {
-			http.Error(w, "Invalid visibility value. Must be: public, unlisted, or private", http.StatusBadRequest)
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid visibility value. Must be: public, unlisted, or private")
 			return
 		}
 	}
@@ -103,9 +113,9 @@ func (h *ListHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	subscribedOnly := query.Get("subscribed") == "true"
 	var subscriberDID string
 	if subscribedOnly {
-		subscriberDID = middleware.GetUserDID(r)
+		subscriberDID = viewerDID
 		if subscriberDID == "" {
-			writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required for subscribed filter")
+			xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required for subscribed filter")
 			return
 		}
 	}
@@ -118,10 +128,14 @@ func (h *ListHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 		Category:      query.Get("category"),
 		Language:      query.Get("language"),
 		SubscriberDID: subscriberDID,
+		ViewerDID:     viewerDID,
+	}
+	if keysetCursor {
+		req.Cursor = query.Get("cursor")
 	}
 
 	// Get communities from AppView DB
-	results, err := h.service.ListCommunities(r.Context(), req)
+	results, nextCursor, err := h.service.ListCommunities(r.Context(), req)
 	if err != nil {
 		handleServiceError(w, err)
 		return
@@ -138,7 +152,11 @@ func (h *ListHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 
 	// Build response
 	var cursor string
-	if len(results) == limit {
+	if keysetCursor {
+		if nextCursor != nil {
+			cursor = *nextCursor
+		}
+	} else if len(results) == limit {
 		// More results available - return next cursor
 		cursor = strconv.Itoa(offset + len(results))
 	}
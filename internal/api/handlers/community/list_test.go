@@ -16,7 +16,8 @@ import (
 
 // listTestService implements communities.Service for list handler tests
 type listTestService struct {
-	listFunc func(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error)
+	listFunc   func(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error)
+	nextCursor *string
 }
 
 func (m *listTestService) CreateCommunity(ctx context.Context, req communities.CreateCommunityRequest) (*communities.Community, error) {
@@ -31,11 +32,28 @@ func (m *listTestService) UpdateCommunity(ctx context.Context, req communities.U
 	return nil, nil
 }
 
-func (m *listTestService) ListCommunities(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error) {
+func (m *listTestService) GetCommunityRules(ctx context.Context, communityDID string) (*communities.CommunityRules, error) {
+	return nil, nil
+}
+
+func (m *listTestService) UpdateCommunityRules(ctx context.Context, req communities.UpdateRulesRequest) (*communities.CommunityRules, error) {
+	return nil, nil
+}
+
+func (m *listTestService) DeleteCommunity(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error) {
+	return nil, nil
+}
+
+func (m *listTestService) UndeleteCommunity(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error) {
+	return nil, nil
+}
+
+func (m *listTestService) ListCommunities(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, *string, error) {
 	if m.listFunc != nil {
-		return m.listFunc(ctx, req)
+		results, err := m.listFunc(ctx, req)
+		return results, m.nextCursor, err
 	}
-	return []*communities.Community{}, nil
+	return []*communities.Community{}, m.nextCursor, nil
 }
 
 func (m *listTestService) SearchCommunities(ctx context.Context, req communities.SearchCommunitiesRequest) ([]*communities.Community, int, error) {
@@ -50,14 +68,22 @@ func (m *listTestService) UnsubscribeFromCommunity(ctx context.Context, session
 	return nil
 }
 
-func (m *listTestService) GetUserSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*communities.Subscription, error) {
-	return nil, nil
+func (m *listTestService) GetUserSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
 }
 
-func (m *listTestService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit, offset int) ([]*communities.Subscription, error) {
+func (m *listTestService) GetSubscribedCommunities(ctx context.Context, req communities.GetSubscriptionsRequest) ([]*communities.SubscribedCommunity, error) {
 	return nil, nil
 }
 
+func (m *listTestService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
+}
+
+func (m *listTestService) IsSubscribed(ctx context.Context, userDID, communityDID string) (bool, error) {
+	return false, nil
+}
+
 func (m *listTestService) BlockCommunity(ctx context.Context, session *oauth.ClientSessionData, communityIdentifier string) (*communities.CommunityBlock, error) {
 	return nil, nil
 }
@@ -117,8 +143,8 @@ func (r *listTestRepo) Delete(ctx context.Context, did string) error { return ni
 func (r *listTestRepo) UpdateCredentials(ctx context.Context, did, accessToken, refreshToken string) error {
 	return nil
 }
-func (r *listTestRepo) List(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error) {
-	return nil, nil
+func (r *listTestRepo) List(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, *string, error) {
+	return nil, nil, nil
 }
 func (r *listTestRepo) Search(ctx context.Context, req communities.SearchCommunitiesRequest) ([]*communities.Community, int, error) {
 	return nil, 0, nil
@@ -139,15 +165,39 @@ func (r *listTestRepo) GetSubscription(ctx context.Context, userDID, communityDI
 func (r *listTestRepo) GetSubscriptionByURI(ctx context.Context, recordURI string) (*communities.Subscription, error) {
 	return nil, nil
 }
-func (r *listTestRepo) ListSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*communities.Subscription, error) {
-	return nil, nil
+func (r *listTestRepo) ListSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
 }
-func (r *listTestRepo) ListSubscribers(ctx context.Context, communityDID string, limit, offset int) ([]*communities.Subscription, error) {
+func (r *listTestRepo) ListSubscribedCommunities(ctx context.Context, req communities.GetSubscriptionsRequest) ([]*communities.SubscribedCommunity, error) {
 	return nil, nil
 }
+func (r *listTestRepo) ListSubscribers(ctx context.Context, communityDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
+}
 func (r *listTestRepo) GetSubscribedCommunityDIDs(ctx context.Context, userDID string, communityDIDs []string) (map[string]bool, error) {
 	return nil, nil
 }
+func (r *listTestRepo) GetSubscribedUserDIDs(ctx context.Context, communityDID string, userDIDs []string) (map[string]bool, error) {
+	return nil, nil
+}
+func (r *listTestRepo) SubscribePendingWithCount(ctx context.Context, subscription *communities.Subscription) (*communities.Subscription, error) {
+	return nil, nil
+}
+func (r *listTestRepo) ConfirmSubscription(ctx context.Context, recordURI, recordCID string) (bool, error) {
+	return false, nil
+}
+func (r *listTestRepo) DeleteUnconfirmedSubscriptions(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+func (r *listTestRepo) BlockCommunityPending(ctx context.Context, block *communities.CommunityBlock) (*communities.CommunityBlock, error) {
+	return nil, nil
+}
+func (r *listTestRepo) ConfirmBlock(ctx context.Context, recordURI, recordCID string) (bool, error) {
+	return false, nil
+}
+func (r *listTestRepo) DeleteUnconfirmedBlocks(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
 func (r *listTestRepo) BlockCommunity(ctx context.Context, block *communities.CommunityBlock) (*communities.CommunityBlock, error) {
 	return nil, nil
 }
@@ -178,6 +228,12 @@ func (r *listTestRepo) UpdateMembership(ctx context.Context, membership *communi
 func (r *listTestRepo) ListMembers(ctx context.Context, communityDID string, limit, offset int) ([]*communities.Membership, error) {
 	return nil, nil
 }
+func (r *listTestRepo) GetModeratedCommunityDIDs(ctx context.Context, userDID string, communityDIDs []string) (map[string]bool, error) {
+	return nil, nil
+}
+func (r *listTestRepo) GetModeratorsForCommunities(ctx context.Context, communityDIDs []string) (map[string]map[string]bool, error) {
+	return nil, nil
+}
 func (r *listTestRepo) CreateModerationAction(ctx context.Context, action *communities.ModerationAction) (*communities.ModerationAction, error) {
 	return nil, nil
 }
@@ -196,6 +252,15 @@ func (r *listTestRepo) IncrementSubscriberCount(ctx context.Context, communityDI
 func (r *listTestRepo) DecrementSubscriberCount(ctx context.Context, communityDID string) error {
 	return nil
 }
+func (r *listTestRepo) AdjustSubscriberCounts(ctx context.Context, deltas map[string]int) error {
+	return nil
+}
+func (r *listTestRepo) RecountSubscriberCounts(ctx context.Context) (int, error) {
+	return 0, nil
+}
+func (r *listTestRepo) RecountPostCounts(ctx context.Context) (int, error) {
+	return 0, nil
+}
 func (r *listTestRepo) IncrementPostCount(ctx context.Context, communityDID string) error {
 	return nil
 }
@@ -472,3 +537,68 @@ func TestListHandler_MethodNotAllowed(t *testing.T) {
 		t.Errorf("Expected status 405, got %d", w.Code)
 	}
 }
+
+func TestListHandler_RelevanceSort(t *testing.T) {
+	const userDID = "did:plc:viewer"
+	nextCursor := "signed-next"
+
+	t.Run("authenticated viewer pages by keyset cursor", func(t *testing.T) {
+		var receivedRequest communities.ListCommunitiesRequest
+		mockService := &listTestService{
+			listFunc: func(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error) {
+				receivedRequest = req
+				return []*communities.Community{{DID: "did:plc:community1", Name: "one", CreatedAt: time.Now()}}, nil
+			},
+			nextCursor: &nextCursor,
+		}
+		handler := NewListHandler(mockService, &listTestRepo{})
+
+		req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.community.list?sort=relevance&limit=1&cursor=signed-current", nil)
+		req = req.WithContext(middleware.SetTestUserDID(req.Context(), userDID))
+		w := httptest.NewRecorder()
+		handler.HandleList(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		if receivedRequest.Sort != communities.ListSortRelevance || receivedRequest.ViewerDID != userDID {
+			t.Errorf("Expected relevance sort for %s, got sort %q viewer %q", userDID, receivedRequest.Sort, receivedRequest.ViewerDID)
+		}
+		if receivedRequest.Cursor != "signed-current" || receivedRequest.Offset != 0 {
+			t.Errorf("Expected the cursor passed through untouched, got cursor %q offset %d", receivedRequest.Cursor, receivedRequest.Offset)
+		}
+
+		var resp struct {
+			Cursor string `json:"cursor"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if resp.Cursor != nextCursor {
+			t.Errorf("Expected cursor %q, got %q", nextCursor, resp.Cursor)
+		}
+	})
+
+	t.Run("unauthenticated caller gets the popular order", func(t *testing.T) {
+		var receivedRequest communities.ListCommunitiesRequest
+		mockService := &listTestService{
+			listFunc: func(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error) {
+				receivedRequest = req
+				return []*communities.Community{}, nil
+			},
+		}
+		handler := NewListHandler(mockService, &listTestRepo{})
+
+		req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.community.list?sort=relevance&cursor=20", nil)
+		w := httptest.NewRecorder()
+		handler.HandleList(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		if receivedRequest.Sort != "popular" || receivedRequest.Offset != 20 || receivedRequest.Cursor != "" {
+			t.Errorf("Expected popular sort at offset 20, got sort %q offset %d cursor %q",
+				receivedRequest.Sort, receivedRequest.Offset, receivedRequest.Cursor)
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package community
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/scheduledposts"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// SchedulePostHandler lets community moderators schedule posts
+type SchedulePostHandler struct {
+	service scheduledposts.Service
+}
+
+// NewSchedulePostHandler creates a new schedule post handler
+func NewSchedulePostHandler(service scheduledposts.Service) *SchedulePostHandler {
+	return &SchedulePostHandler{service: service}
+}
+
+// scheduledPostResponse is the social.coves.community.schedulePost output
+type scheduledPostResponse struct {
+	ScheduledPost *scheduledposts.ScheduledPost `json:"scheduledPost"`
+}
+
+// listScheduledPostsResponse is the social.coves.community.listScheduledPosts output
+type listScheduledPostsResponse struct {
+	ScheduledPosts []*scheduledposts.ScheduledPost `json:"scheduledPosts"`
+}
+
+// HandleSchedulePost stores a post to be published to the community later
+// POST /xrpc/social.coves.community.schedulePost
+//
+// Request body: { "community": "<identifier>", "publishAt": "<datetime>", "recurrence": "none|weekly", "post": {...} }
+func (h *SchedulePostHandler) HandleSchedulePost(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	var req scheduledposts.ScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	if req.Community == "" || req.PublishAt.IsZero() {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community and publishAt are required")
+		return
+	}
+	req.ActorDID = userDID
+
+	post, err := h.service.SchedulePost(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(scheduledPostResponse{ScheduledPost: post}); err != nil {
+		log.Printf("Failed to encode schedulePost response: %v", err)
+	}
+}
+
+// HandleListScheduledPosts lists the community's pending and failed scheduled posts, soonest first
+// GET /xrpc/social.coves.community.listScheduledPosts?community={identifier}&limit={n}&offset={n}
+func (h *SchedulePostHandler) HandleListScheduledPosts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	query := r.URL.Query()
+	req := scheduledposts.ListRequest{Community: query.Get("community"), ActorDID: userDID}
+	if req.Community == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
+		return
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 || limit > scheduledposts.MaxListLimit {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be between 1 and 100")
+			return
+		}
+		req.Limit = limit
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "offset must be a non-negative integer")
+			return
+		}
+		req.Offset = offset
+	}
+
+	list, err := h.service.ListScheduledPosts(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(listScheduledPostsResponse{ScheduledPosts: list}); err != nil {
+		log.Printf("Failed to encode listScheduledPosts response: %v", err)
+	}
+}
+
+// HandleCancelScheduledPost stops a scheduled post from being published again
+// POST /xrpc/social.coves.community.cancelScheduledPost
+//
+// Request body: { "id": 123 }
+func (h *SchedulePostHandler) HandleCancelScheduledPost(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	var req scheduledposts.CancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	if req.ID <= 0 {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "id is required")
+		return
+	}
+	req.ActorDID = userDID
+
+	if err := h.service.CancelScheduledPost(r.Context(), req); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("{}\n")); err != nil {
+		log.Printf("Failed to write cancelScheduledPost response: %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package community
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/scheduledposts"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeScheduleService accepts posts from did:plc:mod and rejects everyone else
+type fakeScheduleService struct {
+	scheduled *scheduledposts.ScheduleRequest
+	cancelled int64
+}
+
+func (f *fakeScheduleService) SchedulePost(_ context.Context, req scheduledposts.ScheduleRequest) (*scheduledposts.ScheduledPost, error) {
+	if req.ActorDID != "did:plc:mod" {
+		return nil, scheduledposts.ErrNotModerator
+	}
+	if !req.PublishAt.After(time.Now()) {
+		return nil, scheduledposts.ErrPublishAtInPast
+	}
+	f.scheduled = &req
+	return &scheduledposts.ScheduledPost{ID: 7, CommunityDID: "did:plc:community", PublishAt: req.PublishAt, Status: scheduledposts.StatusPending}, nil
+}
+
+func (f *fakeScheduleService) ListScheduledPosts(_ context.Context, req scheduledposts.ListRequest) ([]*scheduledposts.ScheduledPost, error) {
+	if req.ActorDID != "did:plc:mod" {
+		return nil, scheduledposts.ErrNotModerator
+	}
+	return []*scheduledposts.ScheduledPost{{ID: 7, CommunityDID: "did:plc:community"}}, nil
+}
+
+func (f *fakeScheduleService) CancelScheduledPost(_ context.Context, req scheduledposts.CancelRequest) error {
+	if req.ID != 7 {
+		return scheduledposts.ErrScheduledPostNotFound
+	}
+	f.cancelled = req.ID
+	return nil
+}
+
+func newScheduleRequest(method, path, body, userDID string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if userDID != "" {
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserDIDKey, userDID))
+	}
+	return req
+}
+
+func TestSchedulePostHandler(t *testing.T) {
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+	past := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	body := func(publishAt string) string {
+		return `{"community":"did:plc:community","publishAt":"` + publishAt + `","recurrence":"weekly","post":{"title":"Weekly thread"}}`
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		userDID    string
+		wantStatus int
+	}{
+		{name: "moderator", body: body(future), userDID: "did:plc:mod", wantStatus: http.StatusOK},
+		{name: "not a moderator", body: body(future), userDID: "did:plc:member", wantStatus: http.StatusForbidden},
+		{name: "unauthenticated", body: body(future), wantStatus: http.StatusUnauthorized},
+		{name: "in the past", body: body(past), userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+		{name: "missing publishAt", body: `{"community":"did:plc:community"}`, userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &fakeScheduleService{}
+			handler := NewSchedulePostHandler(service)
+			w := httptest.NewRecorder()
+			handler.HandleSchedulePost(w, newScheduleRequest(http.MethodPost, "/xrpc/social.coves.community.schedulePost", tt.body, tt.userDID))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp scheduledPostResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.ScheduledPost == nil || resp.ScheduledPost.ID != 7 {
+				t.Errorf("unexpected response: %s", w.Body.String())
+			}
+			if service.scheduled.Recurrence != scheduledposts.RecurrenceWeekly || *service.scheduled.Post.Title != "Weekly thread" {
+				t.Errorf("unexpected request: %+v", service.scheduled)
+			}
+		})
+	}
+}
+
+func TestListScheduledPostsHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		userDID    string
+		wantStatus int
+	}{
+		{name: "moderator", path: "?community=did:plc:community&limit=10", userDID: "did:plc:mod", wantStatus: http.StatusOK},
+		{name: "not a moderator", path: "?community=did:plc:community", userDID: "did:plc:member", wantStatus: http.StatusForbidden},
+		{name: "missing community", path: "", userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+		{name: "bad limit", path: "?community=did:plc:community&limit=101", userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+		{name: "bad offset", path: "?community=did:plc:community&offset=-1", userDID: "did:plc:mod", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewSchedulePostHandler(&fakeScheduleService{})
+			w := httptest.NewRecorder()
+			handler.HandleListScheduledPosts(w, newScheduleRequest(http.MethodGet, "/xrpc/social.coves.community.listScheduledPosts"+tt.path, "", tt.userDID))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantStatus == http.StatusOK && !strings.Contains(w.Body.String(), `"scheduledPosts":[{`) {
+				t.Errorf("unexpected response: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCancelScheduledPostHandler(t *testing.T) {
+	service := &fakeScheduleService{}
+	handler := NewSchedulePostHandler(service)
+
+	w := httptest.NewRecorder()
+	handler.HandleCancelScheduledPost(w, newScheduleRequest(http.MethodPost, "/xrpc/social.coves.community.cancelScheduledPost", `{"id":7}`, "did:plc:mod"))
+	if w.Code != http.StatusOK || service.cancelled != 7 {
+		t.Fatalf("status = %d, cancelled = %d: %s", w.Code, service.cancelled, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleCancelScheduledPost(w, newScheduleRequest(http.MethodPost, "/xrpc/social.coves.community.cancelScheduledPost", `{"id":8}`, "did:plc:mod"))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown post status = %d, want 404", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.HandleCancelScheduledPost(w, newScheduleRequest(http.MethodPost, "/xrpc/social.coves.community.cancelScheduledPost", `{}`, "did:plc:mod"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("missing id status = %d, want 400", w.Code)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"strconv"
 
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/communities"
 )
 
@@ -25,7 +26,7 @@ func NewSearchHandler(service communities.Service) *SearchHandler {
 // GET /xrpc/social.coves.community.search?q={query}&limit={n}&cursor={offset}
 func (h *SearchHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -34,7 +35,7 @@ func (h *SearchHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
 
 	searchQuery := query.Get("q")
 	if searchQuery == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "q parameter is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "q parameter is required")
 		return
 	}
 
@@ -43,7 +44,7 @@ func (h *SearchHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
 	if limitStr := query.Get("limit"); limitStr != "" {
 		l, err := strconv.Atoi(limitStr)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid limit parameter: must be an integer")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid limit parameter: must be an integer")
 			return
 		}
 		if l < 1 {
@@ -60,11 +61,11 @@ func (h *SearchHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
 	if cursorStr := query.Get("cursor"); cursorStr != "" {
 		o, err := strconv.Atoi(cursorStr)
 		if err != nil {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid cursor parameter: must be an integer")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid cursor parameter: must be an integer")
 			return
 		}
 		if o < 0 {
-			writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid cursor parameter: must be non-negative")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid cursor parameter: must be non-negative")
 			return
 		}
 		offset = o
@@ -75,6 +76,7 @@ func (h *SearchHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
 		Limit:      limit,
 		Offset:     offset,
 		Visibility: query.Get("visibility"),
+		Category:   query.Get("category"),
 	}
 
 	// Search communities in AppView DB
@@ -45,10 +45,26 @@ func (m *subscribeTestService) UpdateCommunity(ctx context.Context, req communit
 	return nil, nil
 }
 
-func (m *subscribeTestService) ListCommunities(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error) {
+func (m *subscribeTestService) GetCommunityRules(ctx context.Context, communityDID string) (*communities.CommunityRules, error) {
 	return nil, nil
 }
 
+func (m *subscribeTestService) UpdateCommunityRules(ctx context.Context, req communities.UpdateRulesRequest) (*communities.CommunityRules, error) {
+	return nil, nil
+}
+
+func (m *subscribeTestService) DeleteCommunity(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error) {
+	return nil, nil
+}
+
+func (m *subscribeTestService) UndeleteCommunity(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error) {
+	return nil, nil
+}
+
+func (m *subscribeTestService) ListCommunities(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, *string, error) {
+	return nil, nil, nil
+}
+
 func (m *subscribeTestService) SearchCommunities(ctx context.Context, req communities.SearchCommunitiesRequest) ([]*communities.Community, int, error) {
 	return nil, 0, nil
 }
@@ -77,14 +93,22 @@ func (m *subscribeTestService) UnsubscribeFromCommunity(ctx context.Context, ses
 	return nil
 }
 
-func (m *subscribeTestService) GetUserSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*communities.Subscription, error) {
-	return nil, nil
+func (m *subscribeTestService) GetUserSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
 }
 
-func (m *subscribeTestService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit, offset int) ([]*communities.Subscription, error) {
+func (m *subscribeTestService) GetSubscribedCommunities(ctx context.Context, req communities.GetSubscriptionsRequest) ([]*communities.SubscribedCommunity, error) {
 	return nil, nil
 }
 
+func (m *subscribeTestService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
+}
+
+func (m *subscribeTestService) IsSubscribed(ctx context.Context, userDID, communityDID string) (bool, error) {
+	return false, nil
+}
+
 func (m *subscribeTestService) BlockCommunity(ctx context.Context, session *oauth.ClientSessionData, communityIdentifier string) (*communities.CommunityBlock, error) {
 	return nil, nil
 }
@@ -289,7 +313,7 @@ func TestSubscribeHandler_Subscribe_ServiceErrors(t *testing.T) {
 			name:           "community not found",
 			serviceErr:     communities.ErrCommunityNotFound,
 			expectedStatus: http.StatusNotFound,
-			expectedError:  "NotFound",
+			expectedError:  "CommunityNotFound",
 		},
 		{
 			name:           "validation error",
@@ -546,3 +570,34 @@ func TestUnsubscribeHandler_RequiresOAuthSession(t *testing.T) {
 
 // Ensure unused import is used
 var _ = errors.New
+
+func TestSubscribeHandler_Subscribe_FederationBlocked(t *testing.T) {
+	mockService := &subscribeTestService{
+		subscribeFunc: func(ctx context.Context, session *oauth.ClientSessionData, communityIdentifier string, contentVisibility int) (*communities.Subscription, error) {
+			return nil, communities.ErrFederationBlocked
+		},
+	}
+	handler := NewSubscribeHandler(mockService)
+
+	bodyBytes, _ := json.Marshal(map[string]interface{}{"community": "did:plc:remote"})
+	req := httptest.NewRequest(http.MethodPost, "/xrpc/social.coves.community.subscribe", bytes.NewBuffer(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.OAuthSessionKey, createTestOAuthSession("did:plc:testuser"))
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	handler.HandleSubscribe(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Error != "FederationBlocked" {
+		t.Errorf("Expected FederationBlocked error, got %q", resp.Error)
+	}
+}
@@ -2,6 +2,7 @@ package community
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/communities"
 	"encoding/json"
 	"net/http"
@@ -24,27 +25,27 @@ func NewUpdateHandler(service communities.Service) *UpdateHandler {
 // Body matches UpdateCommunityRequest
 func (h *UpdateHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Parse request body
 	var req communities.UpdateCommunityRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	// Validate required fields
 	if req.CommunityDID == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "communityDid is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "communityDid is required")
 		return
 	}
 
 	// Extract authenticated user DID from request context (injected by auth middleware)
 	userDID := middleware.GetUserDID(r)
 	if userDID == "" {
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
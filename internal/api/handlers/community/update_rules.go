@@ -0,0 +1,69 @@
+package community
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/communities"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// maxUpdateRulesBodyBytes bounds the request body: the markdown limit plus room for
+// structured rules and JSON escaping
+const maxUpdateRulesBodyBytes = 4 * communities.MaxRulesMarkdownBytes
+
+// UpdateRulesHandler handles community rules document updates
+type UpdateRulesHandler struct {
+	service communities.Service
+}
+
+// NewUpdateRulesHandler creates a new update rules handler
+func NewUpdateRulesHandler(service communities.Service) *UpdateRulesHandler {
+	return &UpdateRulesHandler{
+		service: service,
+	}
+}
+
+// HandleUpdateRules replaces a community's welcome/rules document
+// POST /xrpc/social.coves.community.updateRules
+// Body: {"community": "did:plc:xxx", "markdown": "...", "rules": [{"title": "...", "description": "..."}]}
+func (h *UpdateRulesHandler) HandleUpdateRules(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUpdateRulesBodyBytes)
+
+	var req communities.UpdateRulesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+
+	if req.CommunityDID == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
+		return
+	}
+
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+	req.UpdatedByDID = userDID
+
+	// Write-forward to the community's PDS; the AppView is updated via Jetstream
+	rules, err := h.service.UpdateCommunityRules(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(rules); err != nil {
+		log.Printf("Failed to encode community updateRules response: %v", err)
+	}
+}
@@ -2,12 +2,17 @@ package communityFeed
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 
 	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/views"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/communities"
 	"Coves/internal/core/communityFeeds"
 	"Coves/internal/core/posts"
 	"Coves/internal/core/votes"
@@ -18,10 +23,13 @@ type GetCommunityHandler struct {
 	service        communityFeeds.Service
 	voteService    votes.Service
 	blueskyService blueskypost.Service
+	moderators     common.ModeratorLookup
+	aggregators    common.AggregatorLookup
+	ageGate        communities.AgeGate
 }
 
 // NewGetCommunityHandler creates a new community feed handler
-func NewGetCommunityHandler(service communityFeeds.Service, voteService votes.Service, blueskyService blueskypost.Service) *GetCommunityHandler {
+func NewGetCommunityHandler(service communityFeeds.Service, voteService votes.Service, blueskyService blueskypost.Service, moderators common.ModeratorLookup, aggregators common.AggregatorLookup) *GetCommunityHandler {
 	if blueskyService == nil {
 		log.Printf("[COMMUNITY-HANDLER] WARNING: blueskyService is nil - Bluesky post embeds will not be resolved")
 	}
@@ -29,24 +37,51 @@ func NewGetCommunityHandler(service communityFeeds.Service, voteService votes.Se
 		service:        service,
 		voteService:    voteService,
 		blueskyService: blueskyService,
+		moderators:     moderators,
+		aggregators:    aggregators,
 	}
 }
 
+// SetAgeGate withholds age-restricted communities' posts from viewers who haven't confirmed their age
+func (h *GetCommunityHandler) SetAgeGate(gate communities.AgeGate) {
+	h.ageGate = gate
+}
+
 // HandleGetCommunity retrieves posts from a community with sorting
-// GET /xrpc/social.coves.communityFeed.getCommunity?community={did_or_handle}&sort=hot&limit=15&cursor=...
+// GET /xrpc/social.coves.communityFeed.getCommunity?community={did_or_handle}&sort=top&timeframe=week&tag=News&minScore=5&limit=15&cursor=...
 func (h *GetCommunityHandler) HandleGetCommunity(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Parse query parameters
 	req, err := h.parseRequest(r)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 		return
 	}
 
+	// Response shape version (query param or Accept header)
+	version, err := views.ParseVersion(r)
+	if err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.UnsupportedVersion, err.Error())
+		return
+	}
+
+	// Gated viewers get the community's metadata but no posts
+	if h.ageGate != nil && req.Community != "" {
+		community, gated, gateErr := h.ageGate.GatedCommunity(r.Context(), req.ViewerDID, req.Community)
+		if gateErr != nil {
+			handleServiceError(w, gateErr)
+			return
+		}
+		if gated {
+			common.WriteAgeGatedFeed(w, community)
+			return
+		}
+	}
+
 	// Get community feed
 	response, err := h.service.GetCommunityFeed(r.Context(), req)
 	if err != nil {
@@ -57,6 +92,15 @@ func (h *GetCommunityHandler) HandleGetCommunity(w http.ResponseWriter, r *http.
 	// Populate viewer vote state if authenticated
 	common.PopulateViewerVoteState(r.Context(), r, h.voteService, response.Feed)
 
+	// Null vote counts inside community score hiding windows
+	common.HideFeedScores(r.Context(), r, h.moderators, response.Feed)
+
+	// Badge post authors who moderate their community
+	common.AddFeedAuthorBadges(r.Context(), h.moderators, response.Feed)
+
+	// Attribute aggregator-authored posts to their aggregator
+	common.PopulateAggregatorAttribution(r.Context(), h.aggregators, response.Feed)
+
 	// Transform blob refs to URLs and resolve post embeds for all posts
 	for _, feedPost := range response.Feed {
 		if feedPost.Post != nil {
@@ -66,9 +110,9 @@ func (h *GetCommunityHandler) HandleGetCommunity(w http.ResponseWriter, r *http.
 	}
 
 	// Return feed
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", views.ContentType(version))
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
+	if err := json.NewEncoder(w).Encode(views.BuildFeedResponseWithGaps(version, response.Cursor, response.Feed, response.Gaps)); err != nil {
 		// Log encoding errors but don't return error response (headers already sent)
 		log.Printf("ERROR: Failed to encode feed response: %v", err)
 	}
@@ -93,6 +137,21 @@ func (h *GetCommunityHandler) parseRequest(r *http.Request) (communityFeeds.GetC
 		req.Timeframe = "day"
 	}
 
+	// Optional: tag (flair name filter)
+	req.Tag = r.URL.Query().Get("tag")
+
+	// Optional: minScore (moderators only)
+	if minScoreStr := r.URL.Query().Get("minScore"); minScoreStr != "" {
+		minScore, err := strconv.Atoi(minScoreStr)
+		if err != nil {
+			return req, fmt.Errorf("minScore must be an integer")
+		}
+		req.MinScore = &minScore
+	}
+
+	// Viewer (if authenticated) can see their own author_only posts
+	req.ViewerDID = middleware.GetUserDID(r)
+
 	// Optional: limit (default: 15, max: 50)
 	req.Limit = 15
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
@@ -0,0 +1,27 @@
+package directory
+
+import (
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/directory"
+	"log"
+	"net/http"
+)
+
+// handleServiceError maps directory service errors to HTTP responses
+func handleServiceError(w http.ResponseWriter, err error) {
+	if common.WriteSentinelError(w, err) {
+		return
+	}
+
+	switch {
+	case directory.IsValidationError(err):
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
+	default:
+		if common.WriteErrorKind(w, err) {
+			return
+		}
+		log.Printf("ERROR: Directory service error: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An error occurred while listing communities")
+	}
+}
@@ -0,0 +1,53 @@
+package directory
+
+import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/directory"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+// ListCommunitiesHandler serves this instance's community directory to peer instances
+type ListCommunitiesHandler struct {
+	service directory.Service
+}
+
+// NewListCommunitiesHandler creates a new directory listing handler
+func NewListCommunitiesHandler(service directory.Service) *ListCommunitiesHandler {
+	return &ListCommunitiesHandler{service: service}
+}
+
+// HandleListCommunities lists public communities hosted here, least recently updated first
+// Peers poll with the returned cursor to receive only communities updated since.
+// GET /xrpc/social.coves.sync.listCommunities?limit=50&cursor=...
+func (h *ListCommunitiesHandler) HandleListCommunities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	req := directory.ListRequest{Cursor: query.Get("cursor")}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be an integer")
+			return
+		}
+		req.Limit = limit
+	}
+
+	response, err := h.service.ListCommunities(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("ERROR: Failed to encode directory response: %v", err)
+	}
+}
@@ -1,43 +1,27 @@
 package discover
 
 import (
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/discover"
-	"encoding/json"
-	"errors"
 	"log"
 	"net/http"
 )
 
-// XRPCError represents an XRPC error response
-type XRPCError struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-}
-
-// writeError writes a JSON error response
-func writeError(w http.ResponseWriter, status int, errorType, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-
-	resp := XRPCError{
-		Error:   errorType,
-		Message: message,
-	}
-
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		log.Printf("ERROR: Failed to encode error response: %v", err)
-	}
-}
-
 // handleServiceError maps service errors to HTTP responses
 func handleServiceError(w http.ResponseWriter, err error) {
+	if common.WriteSentinelError(w, err) {
+		return
+	}
+
 	switch {
 	case discover.IsValidationError(err):
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
-	case errors.Is(err, discover.ErrInvalidCursor):
-		writeError(w, http.StatusBadRequest, "InvalidCursor", "The provided cursor is invalid")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 	default:
+		if common.WriteErrorKind(w, err) {
+			return
+		}
 		log.Printf("ERROR: Discover service error: %v", err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError", "An error occurred while fetching discover feed")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An error occurred while fetching discover feed")
 	}
 }
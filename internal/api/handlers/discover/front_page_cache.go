@@ -0,0 +1,62 @@
+package discover
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// FrontPageCacheTTL is how long a rendered front page is served to anonymous visitors
+// The page is identical for everyone who isn't logged in, so it is cached aggressively
+const FrontPageCacheTTL = 60 * time.Second
+
+// responseCache holds rendered responses for a short TTL
+// Concurrent misses for the same key share a single load.
+type responseCache struct {
+	entries map[string]cachedResponse
+	now     func() time.Time
+	loads   singleflight.Group
+	ttl     time.Duration
+	mu      sync.RWMutex
+}
+
+type cachedResponse struct {
+	expiresAt time.Time
+	body      []byte
+}
+
+// newResponseCache creates a response cache with the given TTL
+func newResponseCache(ttl time.Duration) *responseCache {
+	return &responseCache{
+		entries: make(map[string]cachedResponse),
+		now:     time.Now,
+		ttl:     ttl,
+	}
+}
+
+// get returns the cached body for key, loading and caching it on a miss or expiry
+// Load errors are returned to every waiting caller and not cached
+func (c *responseCache) get(key string, load func() ([]byte, error)) ([]byte, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok && c.now().Before(entry.expiresAt) {
+		return entry.body, nil
+	}
+
+	body, err, _ := c.loads.Do(key, func() (interface{}, error) {
+		body, err := load()
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.entries[key] = cachedResponse{body: body, expiresAt: c.now().Add(c.ttl)}
+		c.mu.Unlock()
+		return body, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body.([]byte), nil
+}
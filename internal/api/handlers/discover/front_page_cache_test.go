@@ -0,0 +1,61 @@
+package discover
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestResponseCache(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache := newResponseCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	loads := 0
+	load := func() ([]byte, error) {
+		loads++
+		return []byte{byte(loads)}, nil
+	}
+
+	body, err := cache.get("page", load)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loads != 1 || body[0] != 1 {
+		t.Fatalf("Expected first load, got loads=%d body=%v", loads, body)
+	}
+
+	now = now.Add(59 * time.Second)
+	body, _ = cache.get("page", load)
+	if loads != 1 || body[0] != 1 {
+		t.Errorf("Expected cached body within TTL, got loads=%d body=%v", loads, body)
+	}
+
+	body, _ = cache.get("other", load)
+	if loads != 2 || body[0] != 2 {
+		t.Errorf("Expected separate keys to load separately, got loads=%d body=%v", loads, body)
+	}
+
+	now = now.Add(2 * time.Second)
+	body, _ = cache.get("page", load)
+	if loads != 3 || body[0] != 3 {
+		t.Errorf("Expected reload after TTL, got loads=%d body=%v", loads, body)
+	}
+}
+
+func TestResponseCache_ErrorsAreNotCached(t *testing.T) {
+	cache := newResponseCache(time.Minute)
+	loadErr := errors.New("database unavailable")
+
+	if _, err := cache.get("page", func() ([]byte, error) { return nil, loadErr }); !errors.Is(err, loadErr) {
+		t.Fatalf("Expected load error, got %v", err)
+	}
+
+	body, err := cache.get("page", func() ([]byte, error) { return []byte("ok"), nil })
+	if err != nil {
+		t.Fatalf("Expected retry after error to succeed, got %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf("Expected fresh body, got %q", body)
+	}
+}
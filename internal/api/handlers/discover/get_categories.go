@@ -0,0 +1,69 @@
+package discover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/communities"
+)
+
+// CategoryCountsCacheTTL is how long category counts are served before being recounted
+// The counts are the same for everyone and only move when communities are created or change
+// categories, so a few minutes of staleness is fine.
+const CategoryCountsCacheTTL = 5 * time.Minute
+
+// GetCategoriesHandler lists the community category taxonomy with community counts
+type GetCategoriesHandler struct {
+	repo  communities.CategoryRepository
+	cache *responseCache
+}
+
+// NewGetCategoriesHandler creates a new categories handler
+func NewGetCategoriesHandler(repo communities.CategoryRepository) *GetCategoriesHandler {
+	return &GetCategoriesHandler{
+		repo:  repo,
+		cache: newResponseCache(CategoryCountsCacheTTL),
+	}
+}
+
+// HandleGetCategories returns every category in browsing order, then "other", each with the
+// number of listed communities in it
+// GET /xrpc/social.coves.discover.getCategories
+func (h *GetCategoriesHandler) HandleGetCategories(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// The count is shared with concurrent callers, so it must outlive this request
+	ctx := context.WithoutCancel(r.Context())
+	body, err := h.cache.get("categories", func() ([]byte, error) {
+		counts, err := h.repo.CountCommunities(ctx)
+		if err != nil {
+			return nil, err
+		}
+		body, err := json.Marshal(map[string]interface{}{
+			"categories": communities.CategoryCounts(counts),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode categories: %w", err)
+		}
+		return body, nil
+	})
+	if err != nil {
+		log.Printf("ERROR: Failed to count communities by category: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An error occurred while counting categories")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("ERROR: Failed to write categories response: %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package discover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Coves/internal/core/communities"
+)
+
+type fakeCategoryRepo struct {
+	counts map[string]int
+	err    error
+	calls  int
+}
+
+func (f *fakeCategoryRepo) CountCommunities(ctx context.Context) (map[string]int, error) {
+	f.calls++
+	return f.counts, f.err
+}
+
+func getCategories(t *testing.T, handler *GetCategoriesHandler) []communities.CategoryCount {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler.HandleGetCategories(w, httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.discover.getCategories", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp struct {
+		Categories []communities.CategoryCount `json:"categories"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	return resp.Categories
+}
+
+func TestGetCategories(t *testing.T) {
+	repo := &fakeCategoryRepo{counts: map[string]int{communities.CategoryGaming: 3, communities.CategoryOther: 1}}
+	handler := NewGetCategoriesHandler(repo)
+
+	categories := getCategories(t, handler)
+	if len(categories) != len(communities.Categories)+1 {
+		t.Fatalf("Expected the whole taxonomy plus other, got %d categories", len(categories))
+	}
+	byName := make(map[string]int, len(categories))
+	for _, c := range categories {
+		byName[c.Category] = c.CommunityCount
+	}
+	if byName[communities.CategoryGaming] != 3 || byName[communities.CategoryOther] != 1 || byName[communities.CategoryMusic] != 0 {
+		t.Errorf("Unexpected counts: %+v", categories)
+	}
+
+	// Counts are cached rather than recounted per request
+	repo.counts = map[string]int{communities.CategoryGaming: 10}
+	categories = getCategories(t, handler)
+	if repo.calls != 1 {
+		t.Errorf("Expected counts to be loaded once, got %d loads", repo.calls)
+	}
+	if categories[0].Category != communities.Categories[0] {
+		t.Errorf("Expected categories in browsing order, got %q first", categories[0].Category)
+	}
+}
+
+func TestGetCategories_CountError(t *testing.T) {
+	repo := &fakeCategoryRepo{err: errors.New("database unavailable")}
+	handler := NewGetCategoriesHandler(repo)
+
+	w := httptest.NewRecorder()
+	handler.HandleGetCategories(w, httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.discover.getCategories", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d", w.Code)
+	}
+
+	// Failures aren't cached
+	repo.err = nil
+	getCategories(t, handler)
+	if repo.calls != 2 {
+		t.Errorf("Expected a retry after the failed count, got %d loads", repo.calls)
+	}
+}
@@ -7,9 +7,13 @@ import (
 	"strconv"
 
 	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/views"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/blueskypost"
 	"Coves/internal/core/discover"
 	"Coves/internal/core/posts"
+	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
 )
 
@@ -18,10 +22,13 @@ type GetDiscoverHandler struct {
 	service        discover.Service
 	voteService    votes.Service
 	blueskyService blueskypost.Service
+	moderators     common.ModeratorLookup
+	aggregators    common.AggregatorLookup
+	preferences    common.PreferencesLookup
 }
 
 // NewGetDiscoverHandler creates a new discover handler
-func NewGetDiscoverHandler(service discover.Service, voteService votes.Service, blueskyService blueskypost.Service) *GetDiscoverHandler {
+func NewGetDiscoverHandler(service discover.Service, voteService votes.Service, blueskyService blueskypost.Service, moderators common.ModeratorLookup, aggregators common.AggregatorLookup) *GetDiscoverHandler {
 	if blueskyService == nil {
 		log.Printf("[DISCOVER-HANDLER] WARNING: blueskyService is nil - Bluesky post embeds will not be resolved")
 	}
@@ -29,21 +36,36 @@ func NewGetDiscoverHandler(service discover.Service, voteService votes.Service,
 		service:        service,
 		voteService:    voteService,
 		blueskyService: blueskyService,
+		moderators:     moderators,
+		aggregators:    aggregators,
 	}
 }
 
+// SetPreferences enables viewer preference defaults for sort and includeNsfw when the
+// request leaves them out; without it the server defaults apply
+func (h *GetDiscoverHandler) SetPreferences(preferences common.PreferencesLookup) {
+	h.preferences = preferences
+}
+
 // HandleGetDiscover retrieves posts from all communities (public feed)
 // GET /xrpc/social.coves.feed.getDiscover?sort=hot&limit=15&cursor=...
 // Public endpoint with optional auth - if authenticated, includes viewer vote state
 func (h *GetDiscoverHandler) HandleGetDiscover(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Parse query parameters
 	req := h.parseRequest(r)
 
+	// Response shape version (query param or Accept header)
+	version, err := views.ParseVersion(r)
+	if err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.UnsupportedVersion, err.Error())
+		return
+	}
+
 	// Get discover feed
 	response, err := h.service.GetDiscover(r.Context(), req)
 	if err != nil {
@@ -54,6 +76,15 @@ func (h *GetDiscoverHandler) HandleGetDiscover(w http.ResponseWriter, r *http.Re
 	// Populate viewer vote state if authenticated
 	common.PopulateViewerVoteState(r.Context(), r, h.voteService, response.Feed)
 
+	// Null vote counts inside community score hiding windows
+	common.HideFeedScores(r.Context(), r, h.moderators, response.Feed)
+
+	// Badge post authors who moderate their community
+	common.AddFeedAuthorBadges(r.Context(), h.moderators, response.Feed)
+
+	// Attribute aggregator-authored posts to their aggregator
+	common.PopulateAggregatorAttribution(r.Context(), h.aggregators, response.Feed)
+
 	// Transform blob refs to URLs and resolve post embeds for all posts
 	for _, feedPost := range response.Feed {
 		if feedPost.Post != nil {
@@ -63,9 +94,9 @@ func (h *GetDiscoverHandler) HandleGetDiscover(w http.ResponseWriter, r *http.Re
 	}
 
 	// Return feed
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", views.ContentType(version))
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
+	if err := json.NewEncoder(w).Encode(views.BuildFeedResponse(version, response.Cursor, response.Feed)); err != nil {
 		log.Printf("ERROR: Failed to encode discover response: %v", err)
 	}
 }
@@ -74,10 +105,19 @@ func (h *GetDiscoverHandler) HandleGetDiscover(w http.ResponseWriter, r *http.Re
 func (h *GetDiscoverHandler) parseRequest(r *http.Request) discover.GetDiscoverRequest {
 	req := discover.GetDiscoverRequest{}
 
-	// Optional: sort (default: hot)
+	// Viewer (if authenticated) can see their own author_only posts
+	req.ViewerDID = middleware.GetUserDID(r)
+
+	// Absent sort and includeNsfw fall back to the viewer's preferences (defaults when anonymous)
+	var prefs *users.ViewerPreferences
+	if r.URL.Query().Get("sort") == "" || !r.URL.Query().Has("includeNsfw") {
+		prefs = common.LoadViewerPreferences(r.Context(), h.preferences, req.ViewerDID)
+	}
+
+	// Optional: sort (default: the viewer's defaultFeedSort, hot unless set)
 	req.Sort = r.URL.Query().Get("sort")
 	if req.Sort == "" {
-		req.Sort = "hot"
+		req.Sort = prefs.DefaultFeedSort
 	}
 
 	// Optional: timeframe (default: day for top sort)
@@ -94,6 +134,13 @@ func (h *GetDiscoverHandler) parseRequest(r *http.Request) discover.GetDiscoverR
 		}
 	}
 
+	// Optional: includeNsfw (default: the viewer's includeNsfw, off unless set)
+	if r.URL.Query().Has("includeNsfw") {
+		req.IncludeNSFW, _ = strconv.ParseBool(r.URL.Query().Get("includeNsfw"))
+	} else {
+		req.IncludeNSFW = prefs.IncludeNSFW
+	}
+
 	// Optional: cursor
 	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
 		req.Cursor = &cursor
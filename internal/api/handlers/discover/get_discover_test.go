@@ -0,0 +1,61 @@
+package discover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/users"
+)
+
+type fakePreferences map[string]*users.Preferences
+
+func (f fakePreferences) Get(_ context.Context, userDID string) (*users.Preferences, error) {
+	if prefs, ok := f[userDID]; ok {
+		return prefs, nil
+	}
+	return nil, users.ErrPreferencesNotFound
+}
+
+func TestGetDiscover_PreferenceDefaults(t *testing.T) {
+	handler := NewGetDiscoverHandler(nil, nil, nil, nil, nil)
+	handler.SetPreferences(fakePreferences{
+		"did:plc:viewer": {Record: json.RawMessage(`{"defaultFeedSort":"top","includeNsfw":true}`)},
+	})
+
+	request := func(query, viewerDID string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getDiscover"+query, nil)
+		if viewerDID != "" {
+			r = r.WithContext(middleware.SetTestUserDID(r.Context(), viewerDID))
+		}
+		return r
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		viewerDID string
+		wantSort  string
+		wantNSFW  bool
+	}{
+		{name: "anonymous viewers get the server defaults", wantSort: "hot"},
+		{name: "viewer preferences fill absent params", viewerDID: "did:plc:viewer", wantSort: "top", wantNSFW: true},
+		{name: "explicit params win", query: "?sort=new&includeNsfw=false", viewerDID: "did:plc:viewer", wantSort: "new"},
+		{name: "viewers without preferences get the defaults", viewerDID: "did:plc:other", wantSort: "hot"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := handler.parseRequest(request(tt.query, tt.viewerDID))
+			if req.Sort != tt.wantSort || req.IncludeNSFW != tt.wantNSFW {
+				t.Errorf("Expected sort %q and includeNsfw %v, got %q and %v", tt.wantSort, tt.wantNSFW, req.Sort, req.IncludeNSFW)
+			}
+			if tt.wantSort == "top" && req.Timeframe != "day" {
+				t.Errorf("Expected the top sort from preferences to default its timeframe, got %q", req.Timeframe)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package discover
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/views"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/votes"
+)
+
+// GetDiscussionsHandler finds the posts discussing a link
+type GetDiscussionsHandler struct {
+	service        discover.Service
+	voteService    votes.Service
+	blueskyService blueskypost.Service
+	moderators     common.ModeratorLookup
+	aggregators    common.AggregatorLookup
+}
+
+// NewGetDiscussionsHandler creates a new discussions handler
+func NewGetDiscussionsHandler(service discover.Service, voteService votes.Service, blueskyService blueskypost.Service, moderators common.ModeratorLookup, aggregators common.AggregatorLookup) *GetDiscussionsHandler {
+	return &GetDiscussionsHandler{
+		service:        service,
+		voteService:    voteService,
+		blueskyService: blueskyService,
+		moderators:     moderators,
+		aggregators:    aggregators,
+	}
+}
+
+// HandleGetDiscussions returns posts in public communities linking to a URL, highest score first
+// GET /xrpc/social.coves.feed.getDiscussions?url=https://...&limit=15&cursor=...
+// Public endpoint with optional auth - if authenticated, includes viewer vote state
+func (h *GetDiscussionsHandler) HandleGetDiscussions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	req := discover.GetDiscussionsRequest{
+		URL:       query.Get("url"),
+		ViewerDID: middleware.GetUserDID(r),
+	}
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil {
+			req.Limit = limit
+		}
+	}
+	if cursor := query.Get("cursor"); cursor != "" {
+		req.Cursor = &cursor
+	}
+
+	version, err := views.ParseVersion(r)
+	if err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.UnsupportedVersion, err.Error())
+		return
+	}
+
+	response, err := h.service.GetDiscussions(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	common.PopulateViewerVoteState(r.Context(), r, h.voteService, response.Feed)
+
+	// Null vote counts inside community score hiding windows
+	common.HideFeedScores(r.Context(), r, h.moderators, response.Feed)
+
+	// Badge post authors who moderate their community
+	common.AddFeedAuthorBadges(r.Context(), h.moderators, response.Feed)
+
+	// Attribute aggregator-authored posts to their aggregator
+	common.PopulateAggregatorAttribution(r.Context(), h.aggregators, response.Feed)
+
+	for _, feedPost := range response.Feed {
+		if feedPost.Post != nil {
+			posts.TransformBlobRefsToURLs(feedPost.Post)
+			posts.TransformPostEmbeds(r.Context(), feedPost.Post, h.blueskyService)
+		}
+	}
+
+	w.Header().Set("Content-Type", views.ContentType(version))
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(views.BuildFeedResponse(version, response.Cursor, response.Feed)); err != nil {
+		log.Printf("ERROR: Failed to encode discussions response: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package discover
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"Coves/internal/core/discover"
+	"Coves/internal/core/posts"
+)
+
+func TestGetDiscussions(t *testing.T) {
+	hash, err := posts.LinkHash("https://www.youtube.com/watch?v=dQw4w9WgXcQ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &fakeDiscoverRepo{discussions: map[string][]*discover.FeedViewPost{
+		hash: {{Post: &posts.PostView{URI: "at://did:plc:c1/social.coves.community.post/p1", CreatedAt: time.Now()}}},
+	}}
+	handler := NewGetDiscussionsHandler(discover.NewDiscoverService(repo), nil, nil, nil, nil)
+
+	tests := []struct {
+		name      string
+		url       string
+		wantPosts int
+	}{
+		{name: "same link spelled differently", url: "https://youtu.be/dQw4w9WgXcQ?t=30", wantPosts: 1},
+		{name: "no discussions", url: "https://example.com/unseen", wantPosts: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.HandleGetDiscussions(w, httptest.NewRequest(http.MethodGet,
+				"/xrpc/social.coves.feed.getDiscussions?url="+url.QueryEscape(tt.url), nil))
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var resp struct {
+				Feed []json.RawMessage `json:"feed"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp.Feed == nil {
+				t.Error("Expected an empty feed array, not null")
+			}
+			if len(resp.Feed) != tt.wantPosts {
+				t.Errorf("Expected %d posts, got %d", tt.wantPosts, len(resp.Feed))
+			}
+		})
+	}
+}
+
+func TestGetDiscussions_InvalidURL(t *testing.T) {
+	handler := NewGetDiscussionsHandler(discover.NewDiscoverService(&fakeDiscoverRepo{}), nil, nil, nil, nil)
+
+	for _, rawURL := range []string{"", "not a url", "ftp://example.com/file"} {
+		w := httptest.NewRecorder()
+		handler.HandleGetDiscussions(w, httptest.NewRequest(http.MethodGet,
+			"/xrpc/social.coves.feed.getDiscussions?url="+url.QueryEscape(rawURL), nil))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %q, got %d: %s", rawURL, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package discover
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/views"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/votes"
+)
+
+// GetFrontPageHandler handles the logged-out home feed
+type GetFrontPageHandler struct {
+	service        discover.Service
+	voteService    votes.Service
+	blueskyService blueskypost.Service
+	moderators     common.ModeratorLookup
+	aggregators    common.AggregatorLookup
+	cache          *responseCache
+}
+
+// NewGetFrontPageHandler creates a new front page handler
+func NewGetFrontPageHandler(service discover.Service, voteService votes.Service, blueskyService blueskypost.Service, moderators common.ModeratorLookup, aggregators common.AggregatorLookup) *GetFrontPageHandler {
+	return &GetFrontPageHandler{
+		service:        service,
+		voteService:    voteService,
+		blueskyService: blueskyService,
+		moderators:     moderators,
+		aggregators:    aggregators,
+		cache:          newResponseCache(FrontPageCacheTTL),
+	}
+}
+
+// HandleGetFrontPage returns featured community posts blended with globally hot posts
+// GET /xrpc/social.coves.discover.getFrontPage?limit=25
+// Anonymous responses are cached for FrontPageCacheTTL, one entry per API version and limit;
+// authenticated requests are built fresh so they can include viewer vote state
+func (h *GetFrontPageHandler) HandleGetFrontPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	version, err := views.ParseVersion(r)
+	if err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.UnsupportedVersion, err.Error())
+		return
+	}
+
+	req := discover.GetFrontPageRequest{Limit: discover.DefaultFrontPageLimit}
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			req.Limit = limit
+		}
+	}
+
+	var body []byte
+	if middleware.GetUserDID(r) == "" {
+		// The load is shared with other anonymous visitors, so it must outlive this request
+		shared := r.WithContext(context.WithoutCancel(r.Context()))
+		key := fmt.Sprintf("v%d:%d", version, req.Limit)
+		body, err = h.cache.get(key, func() ([]byte, error) {
+			return h.render(shared, req, version)
+		})
+	} else {
+		body, err = h.render(r, req, version)
+	}
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", views.ContentType(version))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("ERROR: Failed to write front page response: %v", err)
+	}
+}
+
+// render builds and encodes the front page response
+func (h *GetFrontPageHandler) render(r *http.Request, req discover.GetFrontPageRequest, version views.Version) ([]byte, error) {
+	response, err := h.service.GetFrontPage(r.Context(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	// No-op for anonymous requests
+	common.PopulateViewerVoteState(r.Context(), r, h.voteService, response.Feed)
+
+	// Null vote counts inside community score hiding windows
+	common.HideFeedScores(r.Context(), r, h.moderators, response.Feed)
+
+	// Badge post authors who moderate their community
+	common.AddFeedAuthorBadges(r.Context(), h.moderators, response.Feed)
+
+	// Attribute aggregator-authored posts to their aggregator
+	common.PopulateAggregatorAttribution(r.Context(), h.aggregators, response.Feed)
+
+	for _, feedPost := range response.Feed {
+		if feedPost.Post != nil {
+			posts.TransformBlobRefsToURLs(feedPost.Post)
+			posts.TransformPostEmbeds(r.Context(), feedPost.Post, h.blueskyService)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(views.BuildFeedResponse(version, response.Cursor, response.Feed)); err != nil {
+		return nil, fmt.Errorf("failed to encode front page: %w", err)
+	}
+	return buf.Bytes(), nil
+}
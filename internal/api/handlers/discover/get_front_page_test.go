@@ -0,0 +1,121 @@
+package discover
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Coves/internal/core/discover"
+	"Coves/internal/core/posts"
+)
+
+// fakeDiscoverRepo serves a fixed hot feed and has no featured communities
+// Discussions are served by link hash
+type fakeDiscoverRepo struct {
+	discussions   map[string][]*discover.FeedViewPost
+	hotFeed       []*discover.FeedViewPost
+	discoverCalls int
+	hotPostCalls  int
+}
+
+func (f *fakeDiscoverRepo) GetDiscover(ctx context.Context, req discover.GetDiscoverRequest) ([]*discover.FeedViewPost, *string, error) {
+	f.discoverCalls++
+	if req.Sort != "hot" {
+		return nil, nil, nil
+	}
+	return f.hotFeed, nil, nil
+}
+
+func (f *fakeDiscoverRepo) GetDiscoverCandidates(ctx context.Context, req discover.GetDiscoverRequest) ([]*discover.DiscoverCandidate, error) {
+	f.discoverCalls++
+	if req.Sort != "hot" {
+		return nil, nil
+	}
+	candidates := make([]*discover.DiscoverCandidate, 0, len(f.hotFeed))
+	for _, post := range f.hotFeed {
+		candidates = append(candidates, &discover.DiscoverCandidate{Post: post, Cursor: post.Post.URI})
+	}
+	return candidates, nil
+}
+
+func (f *fakeDiscoverRepo) GetDiscussions(ctx context.Context, req discover.GetDiscussionsRequest) ([]*discover.FeedViewPost, *string, error) {
+	return f.discussions[req.LinkHash], nil, nil
+}
+
+func (f *fakeDiscoverRepo) GetHotPosts(ctx context.Context, communityDIDs []string, limit int) ([]*discover.RankedPost, error) {
+	f.hotPostCalls++
+	return nil, nil
+}
+
+func (f *fakeDiscoverRepo) ListFeaturedCommunities(ctx context.Context) ([]*discover.FeaturedCommunity, error) {
+	return nil, nil
+}
+
+func (f *fakeDiscoverRepo) AddFeaturedCommunity(ctx context.Context, featured *discover.FeaturedCommunity) error {
+	return nil
+}
+
+func (f *fakeDiscoverRepo) RemoveFeaturedCommunity(ctx context.Context, communityDID string) error {
+	return nil
+}
+
+func (f *fakeDiscoverRepo) ReorderFeaturedCommunities(ctx context.Context, communityDIDs []string) error {
+	return nil
+}
+
+func TestGetFrontPage_FallsBackToDiscoverHot(t *testing.T) {
+	repo := &fakeDiscoverRepo{
+		hotFeed: []*discover.FeedViewPost{
+			{Post: &posts.PostView{URI: "at://did:plc:c1/social.coves.community.post/hot1", CreatedAt: time.Now()}},
+			{Post: &posts.PostView{URI: "at://did:plc:c2/social.coves.community.post/hot2", CreatedAt: time.Now()}},
+		},
+	}
+	handler := NewGetFrontPageHandler(discover.NewDiscoverService(repo), nil, nil, nil, nil)
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		handler.HandleGetFrontPage(w, httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.discover.getFrontPage", nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var resp struct {
+			Feed []struct {
+				Post struct {
+					URI string `json:"uri"`
+				} `json:"post"`
+			} `json:"feed"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		if len(resp.Feed) != 2 {
+			t.Fatalf("Expected 2 posts, got %d", len(resp.Feed))
+		}
+		if resp.Feed[0].Post.URI != repo.hotFeed[0].Post.URI || resp.Feed[1].Post.URI != repo.hotFeed[1].Post.URI {
+			t.Errorf("Expected discover-hot order, got %+v", resp.Feed)
+		}
+	}
+
+	if repo.discoverCalls != 1 {
+		t.Errorf("Expected second anonymous request to be served from cache, got %d discover calls", repo.discoverCalls)
+	}
+	if repo.hotPostCalls != 0 {
+		t.Errorf("Expected no blended query without featured communities, got %d", repo.hotPostCalls)
+	}
+}
+
+func TestGetFrontPage_RejectsLimitOverMax(t *testing.T) {
+	handler := NewGetFrontPageHandler(discover.NewDiscoverService(&fakeDiscoverRepo{}), nil, nil, nil, nil)
+
+	w := httptest.NewRecorder()
+	handler.HandleGetFrontPage(w, httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.discover.getFrontPage?limit=51", nil))
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
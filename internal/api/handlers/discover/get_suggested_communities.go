@@ -0,0 +1,65 @@
+package discover
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/suggestions"
+)
+
+// GetSuggestedCommunitiesHandler suggests communities from the viewer's Bluesky follows
+type GetSuggestedCommunitiesHandler struct {
+	service suggestions.Service
+}
+
+// NewGetSuggestedCommunitiesHandler creates a new suggested communities handler
+func NewGetSuggestedCommunitiesHandler(service suggestions.Service) *GetSuggestedCommunitiesHandler {
+	return &GetSuggestedCommunitiesHandler{service: service}
+}
+
+// HandleGetSuggestedCommunities returns communities the viewer's follows subscribe to or are active in
+// GET /xrpc/social.coves.discover.getSuggestedCommunities?limit=20
+func (h *GetSuggestedCommunitiesHandler) HandleGetSuggestedCommunities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// Follows are read from the viewer's PDS, which needs their OAuth session
+	session := middleware.GetOAuthSession(r)
+	if session == nil {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	limit := suggestions.DefaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 1 || l > suggestions.MaxSuggestions {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be an integer between 1 and 50")
+			return
+		}
+		limit = l
+	}
+
+	resp, err := h.service.GetSuggestedCommunities(r.Context(), session, limit)
+	if err != nil {
+		if common.WriteErrorKind(w, err) {
+			return
+		}
+		log.Printf("ERROR: Suggested communities error: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An error occurred while suggesting communities")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("ERROR: Failed to encode suggested communities response: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package discover
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bluesky-social/indigo/atproto/auth/oauth"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/suggestions"
+)
+
+type fakeSuggestionService struct {
+	err       error
+	gotLimit  int
+	gotViewer string
+}
+
+func (f *fakeSuggestionService) GetSuggestedCommunities(_ context.Context, session *oauth.ClientSessionData, limit int) (*suggestions.GetSuggestedCommunitiesResponse, error) {
+	f.gotLimit = limit
+	f.gotViewer = session.AccountDID.String()
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &suggestions.GetSuggestedCommunitiesResponse{
+		Source: suggestions.SourceFollows,
+		Communities: []*suggestions.SuggestedCommunityView{{
+			Community:     &communities.CommunityView{DID: "did:plc:golang", Name: "golang", SubscriberCount: 12},
+			FollowedUsers: 3,
+		}},
+	}, nil
+}
+
+func TestGetSuggestedCommunities(t *testing.T) {
+	session := &oauth.ClientSessionData{AccountDID: syntax.DID("did:plc:viewer"), SessionID: "session"}
+	request := func(query string, authed bool) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.discover.getSuggestedCommunities"+query, nil)
+		if authed {
+			r = r.WithContext(middleware.SetTestOAuthSession(r.Context(), session))
+		}
+		return r
+	}
+
+	t.Run("returns suggestions with followed counts", func(t *testing.T) {
+		service := &fakeSuggestionService{}
+		w := httptest.NewRecorder()
+		NewGetSuggestedCommunitiesHandler(service).HandleGetSuggestedCommunities(w, request("?limit=5", true))
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 5, service.gotLimit)
+		assert.Equal(t, "did:plc:viewer", service.gotViewer)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, "follows", body["source"])
+		items := body["communities"].([]interface{})
+		require.Len(t, items, 1)
+		item := items[0].(map[string]interface{})
+		community := item["community"].(map[string]interface{})
+		assert.Equal(t, "did:plc:golang", community["did"])
+		assert.Equal(t, float64(12), community["subscriberCount"])
+		assert.Equal(t, float64(3), item["followedUsers"])
+	})
+
+	t.Run("defaults the limit", func(t *testing.T) {
+		service := &fakeSuggestionService{}
+		w := httptest.NewRecorder()
+		NewGetSuggestedCommunitiesHandler(service).HandleGetSuggestedCommunities(w, request("", true))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, suggestions.DefaultLimit, service.gotLimit)
+	})
+
+	t.Run("requires a session", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		NewGetSuggestedCommunitiesHandler(&fakeSuggestionService{}).HandleGetSuggestedCommunities(w, request("", false))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("rejects bad limits", func(t *testing.T) {
+		for _, query := range []string{"?limit=0", "?limit=51", "?limit=abc"} {
+			w := httptest.NewRecorder()
+			NewGetSuggestedCommunitiesHandler(&fakeSuggestionService{}).HandleGetSuggestedCommunities(w, request(query, true))
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		}
+	})
+
+	t.Run("hides internal errors", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		service := &fakeSuggestionService{err: errors.New("connection refused")}
+		NewGetSuggestedCommunitiesHandler(service).HandleGetSuggestedCommunities(w, request("", true))
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.NotContains(t, w.Body.String(), "connection refused")
+	})
+}
@@ -0,0 +1,69 @@
+package feeds
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// FeedCacheTTL is how long a rendered feed is served before it is rebuilt
+// Feed readers poll on a schedule, so every reader of a feed shares one render
+const FeedCacheTTL = 5 * time.Minute
+
+// feedCache holds rendered feeds for FeedCacheTTL
+// Concurrent misses for the same key share a single render; expired entries are dropped
+// whenever a new one is stored, so feeds nobody polls anymore don't accumulate
+type feedCache struct {
+	entries map[string]cachedFeed
+	now     func() time.Time
+	loads   singleflight.Group
+	ttl     time.Duration
+	mu      sync.RWMutex
+}
+
+type cachedFeed struct {
+	expiresAt time.Time
+	body      []byte
+}
+
+// newFeedCache creates a feed cache with the given TTL
+func newFeedCache(ttl time.Duration) *feedCache {
+	return &feedCache{
+		entries: make(map[string]cachedFeed),
+		now:     time.Now,
+		ttl:     ttl,
+	}
+}
+
+// get returns the cached body for key, rendering and caching it on a miss or expiry
+// Render errors are returned to every waiting caller and not cached
+func (c *feedCache) get(key string, render func() ([]byte, error)) ([]byte, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+	if ok && c.now().Before(entry.expiresAt) {
+		return entry.body, nil
+	}
+
+	body, err, _ := c.loads.Do(key, func() (interface{}, error) {
+		body, err := render()
+		if err != nil {
+			return nil, err
+		}
+		now := c.now()
+		c.mu.Lock()
+		for k, e := range c.entries {
+			if !now.Before(e.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+		c.entries[key] = cachedFeed{body: body, expiresAt: now.Add(c.ttl)}
+		c.mu.Unlock()
+		return body, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return body.([]byte), nil
+}
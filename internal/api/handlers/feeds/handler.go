@@ -0,0 +1,298 @@
+package feeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"Coves/internal/core/communities"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/posts"
+	"Coves/internal/syndication"
+)
+
+// MaxFeedItems is the number of posts in a rendered feed
+const MaxFeedItems = 50
+
+// nsfwWarning is the community content warning and post self-label for adult content
+const nsfwWarning = "nsfw"
+
+// errFeedNotFound is returned for feeds that don't exist or can't be syndicated
+// (unlisted, private and NSFW communities)
+var errFeedNotFound = errors.New("feed not found")
+
+// CommunityGetter looks up communities by handle or DID
+type CommunityGetter interface {
+	GetCommunity(ctx context.Context, identifier string) (*communities.Community, error)
+}
+
+// FrontPageGetter builds the instance front page
+type FrontPageGetter interface {
+	GetFrontPage(ctx context.Context, req discover.GetFrontPageRequest) (*discover.DiscoverResponse, error)
+}
+
+// Handler serves RSS and Atom feeds for communities and the front page
+type Handler struct {
+	communities CommunityGetter
+	feeds       communityFeeds.Service
+	frontPage   FrontPageGetter
+	cache       *feedCache
+	baseURL     string
+}
+
+// NewHandler creates a feed handler
+// baseURL is the public URL of the web frontend, used for feed and post links
+func NewHandler(communityGetter CommunityGetter, feedService communityFeeds.Service, frontPage FrontPageGetter, baseURL string) *Handler {
+	return &Handler{
+		communities: communityGetter,
+		feeds:       feedService,
+		frontPage:   frontPage,
+		cache:       newFeedCache(FeedCacheTTL),
+		baseURL:     strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// feedOptions are the query parameters shared by every feed
+type feedOptions struct {
+	atom        bool // ?format=atom; RSS 2.0 by default
+	includeNSFW bool // ?nsfw=true
+}
+
+// HandleCommunityFeed serves a community's latest posts
+// GET /feeds/community/{handle}.xml?format=rss|atom&nsfw=true
+// Unlisted and private communities 404, as do NSFW communities unless nsfw=true
+func (h *Handler) HandleCommunityFeed(w http.ResponseWriter, r *http.Request) {
+	handle, ok := strings.CutSuffix(chi.URLParam(r, "file"), ".xml")
+	if !ok || handle == "" {
+		http.NotFound(w, r)
+		return
+	}
+	opts, err := parseFeedOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := fmt.Sprintf("community:%s:%t:%t", strings.ToLower(handle), opts.atom, opts.includeNSFW)
+	// The render is shared with other readers, so it must outlive this request
+	ctx := context.WithoutCancel(r.Context())
+	body, err := h.cache.get(key, func() ([]byte, error) {
+		return h.renderCommunityFeed(ctx, handle, opts)
+	})
+	h.writeFeed(w, r, body, opts, err)
+}
+
+// HandleDiscoverFeed serves the instance front page
+// GET /feeds/discover.xml?format=rss|atom&nsfw=true
+// Posts from NSFW communities and NSFW-labeled posts are left out unless nsfw=true
+func (h *Handler) HandleDiscoverFeed(w http.ResponseWriter, r *http.Request) {
+	opts, err := parseFeedOptions(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	key := fmt.Sprintf("discover:%t:%t", opts.atom, opts.includeNSFW)
+	ctx := context.WithoutCancel(r.Context())
+	body, err := h.cache.get(key, func() ([]byte, error) {
+		return h.renderDiscoverFeed(ctx, opts)
+	})
+	h.writeFeed(w, r, body, opts, err)
+}
+
+// renderCommunityFeed builds the feed of a public community's newest posts
+func (h *Handler) renderCommunityFeed(ctx context.Context, handle string, opts feedOptions) ([]byte, error) {
+	community, err := h.communities.GetCommunity(ctx, handle)
+	if err != nil {
+		if communities.IsNotFound(err) || communities.IsValidationError(err) {
+			return nil, errFeedNotFound
+		}
+		return nil, fmt.Errorf("failed to get community: %w", err)
+	}
+	if !syndicated(community, opts) {
+		return nil, errFeedNotFound
+	}
+
+	response, err := h.feeds.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+		Community: community.DID,
+		Sort:      "new",
+		Limit:     MaxFeedItems,
+	})
+	if err != nil {
+		if errors.Is(err, communityFeeds.ErrCommunityNotFound) {
+			return nil, errFeedNotFound
+		}
+		return nil, fmt.Errorf("failed to get community feed: %w", err)
+	}
+
+	title := community.DisplayName
+	if title == "" {
+		title = community.Name
+	}
+	feed := &syndication.Feed{
+		ID:          h.communityURL(community.Handle),
+		Title:       title,
+		Link:        h.communityURL(community.Handle),
+		SelfLink:    h.baseURL + "/feeds/community/" + community.Handle + ".xml",
+		Description: community.Description,
+	}
+	for _, feedPost := range response.Feed {
+		if feedPost.Post == nil || (!opts.includeNSFW && labeledNSFW(feedPost.Post)) {
+			continue
+		}
+		feed.Items = append(feed.Items, h.feedItem(feedPost.Post))
+	}
+	return render(feed, opts)
+}
+
+// renderDiscoverFeed builds the feed of the front page
+func (h *Handler) renderDiscoverFeed(ctx context.Context, opts feedOptions) ([]byte, error) {
+	response, err := h.frontPage.GetFrontPage(ctx, discover.GetFrontPageRequest{Limit: MaxFeedItems})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get front page: %w", err)
+	}
+
+	feed := &syndication.Feed{
+		ID:          h.baseURL + "/",
+		Title:       "Coves front page",
+		Link:        h.baseURL + "/",
+		SelfLink:    h.baseURL + "/feeds/discover.xml",
+		Description: "Popular posts across Coves",
+	}
+
+	// Front page posts come from a handful of communities; look each up once
+	allowed := make(map[string]bool)
+	for _, feedPost := range response.Feed {
+		post := feedPost.Post
+		if post == nil || post.Community == nil || (!opts.includeNSFW && labeledNSFW(post)) {
+			continue
+		}
+		ok, seen := allowed[post.Community.DID]
+		if !seen {
+			community, err := h.communities.GetCommunity(ctx, post.Community.DID)
+			if err != nil && !communities.IsNotFound(err) {
+				return nil, fmt.Errorf("failed to get community: %w", err)
+			}
+			ok = err == nil && syndicated(community, opts)
+			allowed[post.Community.DID] = ok
+		}
+		if ok {
+			feed.Items = append(feed.Items, h.feedItem(post))
+		}
+	}
+	return render(feed, opts)
+}
+
+// feedItem converts a post to a feed item
+func (h *Handler) feedItem(post *posts.PostView) syndication.Item {
+	record, _ := post.Record.(map[string]interface{})
+	title, _ := record["title"].(string)
+	content, _ := record["content"].(string)
+
+	item := syndication.Item{
+		ID:        post.URI,
+		Title:     title,
+		Published: post.CreatedAt,
+		Excerpt:   syndication.Excerpt(content),
+	}
+	if post.EditedAt != nil {
+		item.Updated = *post.EditedAt
+	}
+	if post.Author != nil {
+		item.Author = post.Author.Handle
+	}
+	if post.Community != nil {
+		item.Link = h.communityURL(post.Community.Handle) + "/post/" + post.RKey
+	}
+	if item.Excerpt == "" {
+		// Link posts often have no text; the link itself is the best summary
+		embed, _ := record["embed"].(map[string]interface{})
+		item.Excerpt = posts.ExternalEmbedURI(embed)
+	}
+	if item.Title == "" {
+		item.Title = syndication.Excerpt(content)
+		if item.Title == "" {
+			item.Title = "Post by " + item.Author
+		}
+	}
+	return item
+}
+
+// communityURL returns the web page of a community
+func (h *Handler) communityURL(handle string) string {
+	return h.baseURL + "/c/" + handle
+}
+
+// writeFeed writes a rendered feed, or the error that prevented rendering it
+func (h *Handler) writeFeed(w http.ResponseWriter, r *http.Request, body []byte, opts feedOptions, err error) {
+	if err != nil {
+		if errors.Is(err, errFeedNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		log.Printf("ERROR: Failed to render feed %s: %v", r.URL.Path, err)
+		http.Error(w, "Failed to render feed", http.StatusInternalServerError)
+		return
+	}
+
+	contentType := syndication.RSSContentType
+	if opts.atom {
+		contentType = syndication.AtomContentType
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(FeedCacheTTL.Seconds())))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("ERROR: Failed to write feed response: %v", err)
+	}
+}
+
+// parseFeedOptions reads the format and nsfw query parameters
+func parseFeedOptions(r *http.Request) (feedOptions, error) {
+	var opts feedOptions
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "rss":
+	case "atom":
+		opts.atom = true
+	default:
+		return opts, fmt.Errorf("format must be rss or atom")
+	}
+	if nsfw := r.URL.Query().Get("nsfw"); nsfw != "" {
+		include, err := strconv.ParseBool(nsfw)
+		if err != nil {
+			return opts, fmt.Errorf("nsfw must be true or false")
+		}
+		opts.includeNSFW = include
+	}
+	return opts, nil
+}
+
+// render encodes the feed in the requested format
+func render(feed *syndication.Feed, opts feedOptions) ([]byte, error) {
+	if opts.atom {
+		return syndication.RenderAtom(feed)
+	}
+	return syndication.RenderRSS(feed)
+}
+
+// syndicated reports whether a community's posts may appear in public feeds
+func syndicated(community *communities.Community, opts feedOptions) bool {
+	if community.Visibility != "public" || community.FederationBlocked {
+		return false
+	}
+	return opts.includeNSFW || !slices.Contains(community.ContentWarnings, nsfwWarning)
+}
+
+// labeledNSFW reports whether the post has an effective adult label: self-applied, inherited
+// from its community or added by a moderator
+func labeledNSFW(post *posts.PostView) bool {
+	return post.Labels != nil && posts.HasAdultLabel(post.Labels.Values)
+}
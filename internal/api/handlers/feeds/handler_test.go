@@ -0,0 +1,277 @@
+package feeds
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"Coves/internal/core/communities"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/posts"
+)
+
+type fakeCommunities map[string]*communities.Community
+
+func (f fakeCommunities) GetCommunity(ctx context.Context, identifier string) (*communities.Community, error) {
+	for _, c := range f {
+		if c.Handle == identifier || c.DID == identifier {
+			return c, nil
+		}
+	}
+	return nil, communities.ErrCommunityNotFound
+}
+
+type fakeFeedService struct {
+	posts map[string][]*posts.PostView // by community DID
+	calls int
+}
+
+func (f *fakeFeedService) GetCommunityFeed(ctx context.Context, req communityFeeds.GetCommunityFeedRequest) (*communityFeeds.FeedResponse, error) {
+	f.calls++
+	resp := &communityFeeds.FeedResponse{}
+	for _, p := range f.posts[req.Community] {
+		resp.Feed = append(resp.Feed, &communityFeeds.FeedViewPost{Post: p})
+	}
+	return resp, nil
+}
+
+type fakeFrontPage []*posts.PostView
+
+func (f fakeFrontPage) GetFrontPage(ctx context.Context, req discover.GetFrontPageRequest) (*discover.DiscoverResponse, error) {
+	resp := &discover.DiscoverResponse{}
+	for _, p := range f {
+		resp.Feed = append(resp.Feed, &discover.FeedViewPost{Post: p})
+	}
+	return resp, nil
+}
+
+func testCommunity(name, visibility string, warnings ...string) *communities.Community {
+	return &communities.Community{
+		DID:             "did:plc:" + name,
+		Handle:          name + ".community.coves.test",
+		Name:            name,
+		DisplayName:     strings.ToUpper(name[:1]) + name[1:],
+		Visibility:      visibility,
+		ContentWarnings: warnings,
+	}
+}
+
+func testPost(community *communities.Community, rkey, title string, labels ...string) *posts.PostView {
+	record := map[string]interface{}{"title": title, "content": "Body of " + title}
+	if len(labels) > 0 {
+		selfLabels := posts.SelfLabels{}
+		for _, l := range labels {
+			selfLabels.Values = append(selfLabels.Values, posts.SelfLabel{Val: l})
+		}
+		record["labels"] = selfLabels
+	}
+	return &posts.PostView{
+		Labels:    posts.NewLabelsView(posts.EffectiveLabels(community.ContentWarnings, labels, posts.LabelOverrides{})),
+		URI:       "at://" + community.DID + "/social.coves.community.post/" + rkey,
+		RKey:      rkey,
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Record:    record,
+		Author:    &posts.AuthorView{DID: "did:plc:alice", Handle: "alice.test"},
+		Community: &posts.CommunityRef{DID: community.DID, Handle: community.Handle, Name: community.Name},
+	}
+}
+
+type rssItems struct {
+	Channel struct {
+		Title string `xml:"title"`
+		Items []struct {
+			Title string `xml:"title"`
+			Link  string `xml:"link"`
+			GUID  string `xml:"guid"`
+		} `xml:"item"`
+	} `xml:"channel"`
+}
+
+func setup() (*httptest.Server, *fakeFeedService) {
+	gardening := testCommunity("gardening", "public")
+	hidden := testCommunity("hidden", "unlisted")
+	secret := testCommunity("secret", "private")
+	adult := testCommunity("adult", "public", "nsfw")
+	lookup := fakeCommunities{"gardening": gardening, "hidden": hidden, "secret": secret, "adult": adult}
+
+	feedService := &fakeFeedService{posts: map[string][]*posts.PostView{
+		gardening.DID: {testPost(gardening, "p1", "Tomatoes"), testPost(gardening, "p2", "Labeled", "nsfw")},
+		adult.DID:     {testPost(adult, "a1", "Adult post")},
+		hidden.DID:    {testPost(hidden, "h1", "Hidden post")},
+	}}
+	frontPage := fakeFrontPage{
+		testPost(gardening, "p1", "Tomatoes"),
+		testPost(adult, "a1", "Adult post"),
+		testPost(secret, "s1", "Secret post"),
+		testPost(gardening, "p2", "Labeled", "nsfw"),
+	}
+
+	handler := NewHandler(lookup, feedService, frontPage, "https://coves.test/")
+	r := chi.NewRouter()
+	r.Get("/feeds/community/{file}", handler.HandleCommunityFeed)
+	r.Get("/feeds/discover.xml", handler.HandleDiscoverFeed)
+	return httptest.NewServer(r), feedService
+}
+
+func get(t *testing.T, server *httptest.Server, path string) (*http.Response, string) {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatalf("GET %s: %v", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return resp, string(body)
+}
+
+func titles(t *testing.T, body string) []string {
+	t.Helper()
+	var doc rssItems
+	if err := xml.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatalf("invalid RSS: %v\n%s", err, body)
+	}
+	var out []string
+	for _, item := range doc.Channel.Items {
+		out = append(out, item.Title)
+	}
+	return out
+}
+
+func TestCommunityFeed_RSS(t *testing.T) {
+	server, _ := setup()
+	defer server.Close()
+
+	resp, body := get(t, server, "/feeds/community/gardening.community.coves.test.xml")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", resp.StatusCode, body)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/rss+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc != "public, max-age=300" {
+		t.Errorf("Cache-Control = %q", cc)
+	}
+
+	var doc rssItems
+	if err := xml.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatalf("invalid RSS: %v", err)
+	}
+	if doc.Channel.Title != "Gardening" {
+		t.Errorf("channel title = %q", doc.Channel.Title)
+	}
+	if len(doc.Channel.Items) != 1 {
+		t.Fatalf("expected the NSFW-labeled post to be left out, got %d items", len(doc.Channel.Items))
+	}
+	item := doc.Channel.Items[0]
+	if item.Link != "https://coves.test/c/gardening.community.coves.test/post/p1" {
+		t.Errorf("link = %q", item.Link)
+	}
+	if item.GUID != "at://did:plc:gardening/social.coves.community.post/p1" {
+		t.Errorf("guid = %q", item.GUID)
+	}
+}
+
+func TestCommunityFeed_Atom(t *testing.T) {
+	server, _ := setup()
+	defer server.Close()
+
+	resp, body := get(t, server, "/feeds/community/gardening.community.coves.test.xml?format=atom")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "application/atom+xml; charset=utf-8" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(body, `<feed xmlns="http://www.w3.org/2005/Atom">`) {
+		t.Errorf("expected an Atom feed, got:\n%s", body)
+	}
+
+	resp, _ = get(t, server, "/feeds/community/gardening.community.coves.test.xml?format=json")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unknown format: expected 400, got %d", resp.StatusCode)
+	}
+}
+
+func TestCommunityFeed_Visibility(t *testing.T) {
+	server, _ := setup()
+	defer server.Close()
+
+	for _, path := range []string{
+		"/feeds/community/hidden.community.coves.test.xml",
+		"/feeds/community/secret.community.coves.test.xml",
+		"/feeds/community/adult.community.coves.test.xml",
+		"/feeds/community/missing.community.coves.test.xml",
+		"/feeds/community/gardening.community.coves.test",
+	} {
+		if resp, _ := get(t, server, path); resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s: expected 404, got %d", path, resp.StatusCode)
+		}
+	}
+
+	// NSFW communities can be opted into; unlisted ones can't
+	resp, body := get(t, server, "/feeds/community/adult.community.coves.test.xml?nsfw=true")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("nsfw=true: expected 200, got %d", resp.StatusCode)
+	}
+	if got := titles(t, body); len(got) != 1 || got[0] != "Adult post" {
+		t.Errorf("unexpected items: %v", got)
+	}
+	if resp, _ := get(t, server, "/feeds/community/hidden.community.coves.test.xml?nsfw=true"); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("unlisted with nsfw=true: expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestCommunityFeed_Cached(t *testing.T) {
+	server, feedService := setup()
+	defer server.Close()
+
+	for i := 0; i < 3; i++ {
+		if resp, _ := get(t, server, "/feeds/community/gardening.community.coves.test.xml"); resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+	}
+	if feedService.calls != 1 {
+		t.Errorf("expected one render, got %d", feedService.calls)
+	}
+
+	// Formats are cached separately
+	get(t, server, "/feeds/community/gardening.community.coves.test.xml?format=atom")
+	if feedService.calls != 2 {
+		t.Errorf("expected a render per format, got %d", feedService.calls)
+	}
+}
+
+func TestDiscoverFeed(t *testing.T) {
+	server, _ := setup()
+	defer server.Close()
+
+	resp, body := get(t, server, "/feeds/discover.xml")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.StatusCode)
+	}
+	if got := titles(t, body); len(got) != 1 || got[0] != "Tomatoes" {
+		t.Errorf("expected only the SFW public post, got %v", got)
+	}
+
+	_, body = get(t, server, "/feeds/discover.xml?nsfw=true")
+	got := titles(t, body)
+	if len(got) != 3 {
+		t.Errorf("nsfw=true: expected NSFW posts but not the private community's, got %v", got)
+	}
+	for _, title := range got {
+		if title == "Secret post" {
+			t.Error("posts from private communities are never syndicated")
+		}
+	}
+}
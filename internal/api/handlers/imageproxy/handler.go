@@ -12,6 +12,7 @@ import (
 	"github.com/go-chi/chi/v5"
 
 	"Coves/internal/atproto/identity"
+	"Coves/internal/core/blobrefs"
 	"Coves/internal/core/imageproxy"
 )
 
@@ -24,12 +25,20 @@ type Service interface {
 	// cid: the content identifier of the blob
 	// pdsURL: the URL of the user's PDS
 	GetImage(ctx context.Context, preset, did, cid, pdsURL string) ([]byte, error)
+
+	// GetIdenticon returns the generated fallback avatar (an SVG) for a DID.
+	GetIdenticon(did string) ([]byte, error)
 }
 
+// imageCacheControl caches blob images briefly and without immutable: a blob can be blocked
+// after it was served, and a revalidation is refused once it is, so cached copies expire within the hour
+const imageCacheControl = "public, max-age=3600"
+
 // Handler handles HTTP requests for the image proxy.
 type Handler struct {
 	service          Service
 	identityResolver identity.Resolver
+	blockList        blobrefs.BlockList // Optional: every blob is served when nil
 }
 
 // NewHandler creates a new image proxy handler.
@@ -40,6 +49,12 @@ func NewHandler(service Service, resolver identity.Resolver) *Handler {
 	}
 }
 
+// SetBlockList configures the blobs the proxy refuses to serve (those referenced only by
+// taken-down, removed or deleted content)
+func (h *Handler) SetBlockList(blockList blobrefs.BlockList) {
+	h.blockList = blockList
+}
+
 // HandleImage handles GET /img/{preset}/plain/{did}/{cid}
 // It fetches the image from the user's PDS, transforms it according to the preset,
 // and returns the result with appropriate caching headers.
@@ -77,6 +92,14 @@ func (h *Handler) HandleImage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Blocked blobs are refused before any cache or PDS is consulted, even for a client
+	// revalidating a copy it already has
+	if h.isBlocked(r.Context(), cid) {
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusUnavailableForLegalReasons)
+		return
+	}
+
 	// Generate ETag for caching
 	etag := fmt.Sprintf(`"%s-%s"`, preset, cid)
 
@@ -116,7 +139,7 @@ func (h *Handler) HandleImage(w http.ResponseWriter, r *http.Request) {
 
 	// Set response headers
 	w.Header().Set("Content-Type", "image/jpeg")
-	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("Cache-Control", imageCacheControl)
 	w.Header().Set("ETag", etag)
 
 	// Write image data
@@ -131,6 +154,62 @@ func (h *Handler) HandleImage(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// HandleIdenticon handles GET /img/identicon/{did}
+// It returns the DID's generated fallback avatar, which hydration links to for users and
+// communities without an avatar blob. The image depends only on the DID, so no PDS or DID
+// resolution is involved.
+func (h *Handler) HandleIdenticon(w http.ResponseWriter, r *http.Request) {
+	did := chi.URLParam(r, "did")
+	if err := imageproxy.ValidateDID(did); err != nil {
+		writeErrorResponse(w, http.StatusBadRequest, "invalid DID format")
+		return
+	}
+
+	etag := fmt.Sprintf(`"identicon-%s"`, imageproxy.IdenticonVersion)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	svg, err := h.service.GetIdenticon(did)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	// Not immutable: a new IdenticonVersion changes the image behind the same URL
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "public, max-age=604800")
+	w.Header().Set("ETag", etag)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'")
+
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(svg); err != nil {
+		slog.Warn("[IMAGE-PROXY] failed to write identicon response",
+			"did", did,
+			"error", err,
+		)
+	}
+}
+
+// isBlocked reports whether the blob is on the block list
+// A failed lookup serves the blob: the block list can't take the whole proxy down with it.
+func (h *Handler) isBlocked(ctx context.Context, cid string) bool {
+	if h.blockList == nil {
+		return false
+	}
+	blocked, err := h.blockList.IsBlocked(ctx, cid)
+	if err != nil {
+		slog.Warn("[IMAGE-PROXY] failed to check blob block list, serving blob",
+			"cid", cid,
+			"error", err,
+		)
+		return false
+	}
+	return blocked
+}
+
 // getPDSEndpoint extracts the PDS service endpoint from a DID document.
 func getPDSEndpoint(doc *identity.DIDDocument) string {
 	if doc == nil {
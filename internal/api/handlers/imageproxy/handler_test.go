@@ -26,6 +26,10 @@ type mockService struct {
 	getImageFunc func(ctx context.Context, preset, did, cid, pdsURL string) ([]byte, error)
 }
 
+func (m *mockService) GetIdenticon(did string) ([]byte, error) {
+	return imageproxy.Identicon(did), nil
+}
+
 func (m *mockService) GetImage(ctx context.Context, preset, did, cid, pdsURL string) ([]byte, error) {
 	if m.getImageFunc != nil {
 		return m.getImageFunc(ctx, preset, did, cid, pdsURL)
@@ -132,7 +136,7 @@ func TestHandler_HandleImage_Success(t *testing.T) {
 
 	// Verify Cache-Control
 	cacheControl := w.Header().Get("Cache-Control")
-	expectedCacheControl := "public, max-age=31536000, immutable"
+	expectedCacheControl := "public, max-age=3600"
 	if cacheControl != expectedCacheControl {
 		t.Errorf("Expected Cache-Control %q, got %q", expectedCacheControl, cacheControl)
 	}
@@ -661,3 +665,139 @@ func TestHandler_HandleImage_InvalidCID(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_HandleIdenticon(t *testing.T) {
+	handler := NewHandler(&mockService{}, &mockIdentityResolver{})
+
+	req := createTestRequest(http.MethodGet, "/img/identicon/"+validTestDID, map[string]string{"did": validTestDID})
+	w := httptest.NewRecorder()
+	handler.HandleIdenticon(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "image/svg+xml" {
+		t.Errorf("Expected Content-Type image/svg+xml, got %s", contentType)
+	}
+	if w.Header().Get("Cache-Control") == "" || w.Header().Get("ETag") == "" {
+		t.Error("Expected Cache-Control and ETag headers")
+	}
+	if w.Body.String() != string(imageproxy.Identicon(validTestDID)) {
+		t.Error("Expected the DID's identicon")
+	}
+
+	// A matching ETag skips rendering
+	req = createTestRequest(http.MethodGet, "/img/identicon/"+validTestDID, map[string]string{"did": validTestDID})
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	handler.HandleIdenticon(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304, got %d", w.Code)
+	}
+}
+
+func TestHandler_HandleIdenticon_InvalidDID(t *testing.T) {
+	handler := NewHandler(&mockService{}, &mockIdentityResolver{})
+
+	for _, did := range []string{"", "not-a-did", "did:plc:..%2F..%2Fetc"} {
+		req := createTestRequest(http.MethodGet, "/img/identicon/"+did, map[string]string{"did": did})
+		w := httptest.NewRecorder()
+		handler.HandleIdenticon(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %q, got %d", did, w.Code)
+		}
+	}
+}
+
+// mockBlockList implements blobrefs.BlockList for testing
+type mockBlockList struct {
+	blocked map[string]bool
+	err     error
+}
+
+func (m *mockBlockList) IsBlocked(ctx context.Context, cid string) (bool, error) {
+	return m.blocked[cid], m.err
+}
+
+func TestHandler_HandleImage_BlockedBlob_Returns451(t *testing.T) {
+	mockSvc := &mockService{
+		getImageFunc: func(ctx context.Context, preset, did, cid, pdsURL string) ([]byte, error) {
+			t.Error("Expected a blocked blob not to be fetched")
+			return nil, nil
+		},
+	}
+	mockResolver := &mockIdentityResolver{
+		resolveDIDFunc: func(ctx context.Context, did string) (*identity.DIDDocument, error) {
+			t.Error("Expected a blocked blob's DID not to be resolved")
+			return nil, errors.New("unexpected")
+		},
+	}
+	handler := NewHandler(mockSvc, mockResolver)
+	handler.SetBlockList(&mockBlockList{blocked: map[string]bool{validTestCID: true}})
+
+	params := map[string]string{"preset": "avatar", "did": validTestDID, "cid": validTestCID}
+
+	t.Run("fetch", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.HandleImage(w, createTestRequest(http.MethodGet, "/img/avatar/plain/"+validTestDID+"/"+validTestCID, params))
+
+		if w.Code != http.StatusUnavailableForLegalReasons {
+			t.Errorf("Expected status 451, got %d", w.Code)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("Expected no body, got %q", w.Body.String())
+		}
+		if got := w.Header().Get("Cache-Control"); got != "no-store" {
+			t.Errorf("Expected Cache-Control no-store, got %q", got)
+		}
+	})
+
+	t.Run("revalidation", func(t *testing.T) {
+		req := createTestRequest(http.MethodGet, "/img/avatar/plain/"+validTestDID+"/"+validTestCID, params)
+		req.Header.Set("If-None-Match", `"avatar-`+validTestCID+`"`)
+		w := httptest.NewRecorder()
+		handler.HandleImage(w, req)
+
+		if w.Code != http.StatusUnavailableForLegalReasons {
+			t.Errorf("Expected status 451 instead of 304, got %d", w.Code)
+		}
+	})
+}
+
+func TestHandler_HandleImage_BlockList_ServesUnblocked(t *testing.T) {
+	expectedImage := []byte{0xFF, 0xD8, 0xFF, 0xE0}
+	mockSvc := &mockService{
+		getImageFunc: func(ctx context.Context, preset, did, cid, pdsURL string) ([]byte, error) {
+			return expectedImage, nil
+		},
+	}
+	mockResolver := &mockIdentityResolver{
+		resolveDIDFunc: func(ctx context.Context, did string) (*identity.DIDDocument, error) {
+			return &identity.DIDDocument{
+				DID:     did,
+				Service: []identity.Service{{Type: "AtprotoPersonalDataServer", ServiceEndpoint: "https://pds.example.com"}},
+			}, nil
+		},
+	}
+	params := map[string]string{"preset": "avatar", "did": validTestDID, "cid": validTestCID}
+
+	for name, blockList := range map[string]*mockBlockList{
+		"other blob blocked": {blocked: map[string]bool{"bafkreiother": true}},
+		"lookup fails":       {err: errors.New("database unavailable")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			handler := NewHandler(mockSvc, mockResolver)
+			handler.SetBlockList(blockList)
+
+			w := httptest.NewRecorder()
+			handler.HandleImage(w, createTestRequest(http.MethodGet, "/img/avatar/plain/"+validTestDID+"/"+validTestCID, params))
+
+			if w.Code != http.StatusOK {
+				t.Errorf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+			}
+			if w.Body.Len() != len(expectedImage) {
+				t.Errorf("Expected body length %d, got %d", len(expectedImage), w.Body.Len())
+			}
+		})
+	}
+}
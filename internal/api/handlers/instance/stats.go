@@ -0,0 +1,69 @@
+package instance
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/instance"
+)
+
+// Handler serves the public instance statistics and the NodeInfo documents built from them
+type Handler struct {
+	service  instance.Service
+	baseURL  string
+	nodeName string
+}
+
+// NewHandler creates an instance stats handler
+// baseURL is the instance's public URL, used for NodeInfo links and as its homepage.
+func NewHandler(service instance.Service, baseURL, nodeName string) *Handler {
+	return &Handler{service: service, baseURL: baseURL, nodeName: nodeName}
+}
+
+// HandleGetStats returns the instance statistics for the public transparency page
+// GET /xrpc/social.coves.instance.getStats
+func (h *Handler) HandleGetStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	stats, err := h.service.GetStats(r.Context())
+	if err != nil {
+		log.Printf("ERROR: Failed to get instance stats: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
+		return
+	}
+	writeCachedJSON(w, "application/json", stats)
+}
+
+// HandleNodeInfoLinks serves the NodeInfo discovery document
+// GET /.well-known/nodeinfo
+func (h *Handler) HandleNodeInfoLinks(w http.ResponseWriter, r *http.Request) {
+	writeCachedJSON(w, "application/json", instance.NewNodeInfoLinks(h.baseURL))
+}
+
+// HandleNodeInfo serves the NodeInfo 2.1 document
+// GET /nodeinfo/2.1
+func (h *Handler) HandleNodeInfo(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.service.GetStats(r.Context())
+	if err != nil {
+		log.Printf("ERROR: Failed to get instance stats for NodeInfo: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
+		return
+	}
+	writeCachedJSON(w, instance.NodeInfoContentType, instance.NewNodeInfo(stats, h.nodeName, h.baseURL))
+}
+
+// writeCachedJSON writes body, letting caches keep it as long as the service does
+func writeCachedJSON(w http.ResponseWriter, contentType string, body interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(instance.StatsCacheTTL.Seconds())))
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Printf("ERROR: Failed to encode instance response: %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+package instance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"Coves/internal/core/instance"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStatsService struct {
+	err error
+}
+
+func (s fakeStatsService) GetStats(context.Context) (*instance.Stats, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &instance.Stats{
+		GeneratedAt:   time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		StartedAt:     time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC),
+		Uptime:        3600,
+		Software:      instance.Software{Name: "coves", Version: "v1.2.3"},
+		Communities:   3,
+		Users:         101,
+		Posts:         instance.Window{Day: 2, Week: 10},
+		Comments:      instance.Window{Day: 5, Week: 30},
+		ActiveUsers:   instance.ActiveUsers{Day: 4, Week: 12, Month: 20, HalfYear: 50},
+		Federation:    instance.Federation{RemoteCommunities: 7, Peers: 2},
+		LocalPosts:    40,
+		LocalComments: 90,
+	}, nil
+}
+
+func serve(t *testing.T, handler http.HandlerFunc, path string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, path, nil))
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	return w, body
+}
+
+func TestHandleGetStats(t *testing.T) {
+	handler := NewHandler(fakeStatsService{}, "https://coves.test", "coves.test")
+
+	w, body := serve(t, handler.HandleGetStats, "/xrpc/social.coves.instance.getStats")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+
+	assert.Equal(t, map[string]interface{}{
+		"generatedAt":   "2026-03-01T12:00:00Z",
+		"startedAt":     "2026-03-01T11:00:00Z",
+		"uptimeSeconds": float64(3600),
+		"software":      map[string]interface{}{"name": "coves", "version": "v1.2.3"},
+		"communities":   float64(3),
+		"users":         float64(101),
+		"posts":         map[string]interface{}{"day": float64(2), "week": float64(10)},
+		"comments":      map[string]interface{}{"day": float64(5), "week": float64(30)},
+		"activeUsers":   map[string]interface{}{"day": float64(4), "week": float64(12), "month": float64(20), "halfYear": float64(50)},
+		"federation":    map[string]interface{}{"remoteCommunities": float64(7), "peers": float64(2)},
+	}, body)
+}
+
+func TestHandleGetStats_Error(t *testing.T) {
+	handler := NewHandler(fakeStatsService{err: errors.New("db down")}, "https://coves.test", "coves.test")
+
+	w, body := serve(t, handler.HandleGetStats, "/xrpc/social.coves.instance.getStats")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "InternalServerError", body["error"])
+}
+
+func TestHandleNodeInfoLinks(t *testing.T) {
+	handler := NewHandler(fakeStatsService{}, "https://coves.test", "coves.test")
+
+	w, body := serve(t, handler.HandleNodeInfoLinks, "/.well-known/nodeinfo")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, map[string]interface{}{
+		"links": []interface{}{map[string]interface{}{
+			"rel":  "http://nodeinfo.diaspora.software/ns/schema/2.1",
+			"href": "https://coves.test/nodeinfo/2.1",
+		}},
+	}, body)
+}
+
+func TestHandleNodeInfo(t *testing.T) {
+	handler := NewHandler(fakeStatsService{}, "https://coves.test", "coves.test")
+
+	w, body := serve(t, handler.HandleNodeInfo, "/nodeinfo/2.1")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, instance.NodeInfoContentType, w.Header().Get("Content-Type"))
+
+	assert.Equal(t, "2.1", body["version"])
+	assert.Equal(t, map[string]interface{}{"name": "coves", "version": "v1.2.3", "homepage": "https://coves.test"}, body["software"])
+	assert.Equal(t, []interface{}{"atprotocol"}, body["protocols"])
+	assert.Equal(t, map[string]interface{}{"inbound": []interface{}{}, "outbound": []interface{}{}}, body["services"])
+	assert.Equal(t, false, body["openRegistrations"])
+	assert.Equal(t, map[string]interface{}{
+		"users":         map[string]interface{}{"total": float64(101), "activeMonth": float64(20), "activeHalfyear": float64(50)},
+		"localPosts":    float64(40),
+		"localComments": float64(90),
+	}, body["usage"])
+	assert.Equal(t, map[string]interface{}{
+		"nodeName":    "coves.test",
+		"communities": float64(3),
+		"federation":  map[string]interface{}{"remoteCommunities": float64(7), "peers": float64(2)},
+	}, body["metadata"])
+}
+
+func TestHandleNodeInfo_Error(t *testing.T) {
+	handler := NewHandler(fakeStatsService{err: errors.New("db down")}, "https://coves.test", "coves.test")
+
+	w := httptest.NewRecorder()
+	handler.HandleNodeInfo(w, httptest.NewRequest(http.MethodGet, "/nodeinfo/2.1", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
@@ -0,0 +1,294 @@
+package live
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/communities"
+	liveCore "Coves/internal/core/live"
+	"Coves/internal/core/posts"
+)
+
+// Subscribe handler defaults
+const (
+	DefaultMaxTopics              = 20
+	DefaultMaxTimelineCommunities = 500
+	DefaultHeartbeat              = 15 * time.Second
+	DefaultMaxDuration            = 30 * time.Minute
+)
+
+// SubscribeConfig configures NewSubscribeHandler
+// Zero values use the defaults above.
+type SubscribeConfig struct {
+	MaxTopics              int           // Topics a single connection may subscribe to
+	MaxTimelineCommunities int           // Subscribed communities the timeline topic follows
+	Heartbeat              time.Duration // Interval between keep-alive comments
+	MaxDuration            time.Duration // Connections are closed after this long; clients reconnect
+}
+
+// errAgeGated is returned for topics in an age-restricted community the viewer hasn't confirmed their age for
+var errAgeGated = errors.New("community requires age confirmation")
+
+// SubscribeHandler streams live events to clients over server-sent events
+type SubscribeHandler struct {
+	hub              *liveCore.Hub
+	communityService communities.Service
+	postService      posts.Service
+	ageGate          communities.AgeGate
+	cfg              SubscribeConfig
+}
+
+// NewSubscribeHandler creates a handler streaming events from hub
+// Post topics are checked with postService like getPost, community topics with communityService.
+func NewSubscribeHandler(hub *liveCore.Hub, communityService communities.Service, postService posts.Service, cfg SubscribeConfig) *SubscribeHandler {
+	if cfg.MaxTopics <= 0 {
+		cfg.MaxTopics = DefaultMaxTopics
+	}
+	if cfg.MaxTimelineCommunities <= 0 {
+		cfg.MaxTimelineCommunities = DefaultMaxTimelineCommunities
+	}
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = DefaultHeartbeat
+	}
+	if cfg.MaxDuration <= 0 {
+		cfg.MaxDuration = DefaultMaxDuration
+	}
+	return &SubscribeHandler{hub: hub, communityService: communityService, postService: postService, cfg: cfg}
+}
+
+// SetAgeGate refuses post and community topics in age-restricted communities to viewers who
+// haven't confirmed their age
+func (h *SubscribeHandler) SetAgeGate(gate communities.AgeGate) {
+	h.ageGate = gate
+}
+
+// HandleSubscribe streams events for the requested topics until the client disconnects
+// GET /xrpc/social.coves.sync.subscribe?topic=post:{uri}&topic=community:{did}&topic=timeline
+//
+// Post and community topics get the same checks as getPost and getCommunity: a post the viewer
+// can't read, or a community that's suspended, deleted, federation-blocked or age-gated for
+// them, fails the request with the error the read endpoint would return.
+//
+// Each event is sent as "event: <type>" with a JSON {type, uri, parentUri, topic} payload.
+// The stream ends with "event: close" when the connection reaches its maximum duration,
+// or "event: error" when the client reads too slowly to keep up; clients reconnect and
+// catch up through the regular read endpoints.
+func (h *SubscribeHandler) HandleSubscribe(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	requested := r.URL.Query()["topic"]
+	if len(requested) == 0 {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "At least one topic is required")
+		return
+	}
+	if len(requested) > h.cfg.MaxTopics {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest,
+			fmt.Sprintf("Too many topics (max %d)", h.cfg.MaxTopics))
+		return
+	}
+
+	topics := make(map[string]string, len(requested))
+	for _, topic := range requested {
+		if !liveCore.ValidTopic(topic) {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest,
+				fmt.Sprintf("Invalid topic %q: expected post:<uri>, community:<did> or timeline", topic))
+			return
+		}
+		if topic != liveCore.TimelineTopic {
+			topics[topic] = topic
+		}
+	}
+
+	viewerDID := middleware.GetUserDID(r)
+	for topic := range topics {
+		if err := h.authorizeTopic(r.Context(), viewerDID, topic); err != nil {
+			writeTopicError(w, err)
+			return
+		}
+	}
+
+	// The timeline follows every community the viewer subscribes to
+	for _, topic := range requested {
+		if topic != liveCore.TimelineTopic {
+			continue
+		}
+		if viewerDID == "" {
+			xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "The timeline topic requires authentication")
+			return
+		}
+		subs, _, err := h.communityService.GetUserSubscriptions(r.Context(), viewerDID, h.cfg.MaxTimelineCommunities, nil)
+		if err != nil {
+			log.Printf("ERROR: Failed to load subscriptions for live timeline: %v", err)
+			xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to load subscriptions")
+			return
+		}
+		for _, sub := range subs {
+			communityTopic := liveCore.CommunityTopic(sub.CommunityDID)
+			if _, explicit := topics[communityTopic]; explicit {
+				continue
+			}
+			// Like getTimeline, communities that can't be read are left out rather than failing
+			// the stream; the timeline isn't age-gated
+			available, err := h.communityAvailable(r.Context(), sub.CommunityDID)
+			if err != nil {
+				log.Printf("ERROR: Failed to check community %s for live timeline: %v", sub.CommunityDID, err)
+				xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to load subscriptions")
+				return
+			}
+			if available {
+				topics[communityTopic] = liveCore.TimelineTopic
+			}
+		}
+		break
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Streaming not supported")
+		return
+	}
+
+	sub := h.hub.Subscribe(topics)
+	defer sub.Close()
+
+	// A server-wide write timeout would cut the stream short; the stream enforces MaxDuration itself
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no") // Disable proxy buffering (nginx)
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(h.cfg.Heartbeat)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(h.cfg.MaxDuration)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case event := <-sub.Events():
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("ERROR: Failed to encode live event: %v", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-sub.Dropped():
+			_, _ = fmt.Fprint(w, "event: error\ndata: {\"error\":\"ConsumerTooSlow\"}\n\n")
+			flusher.Flush()
+			return
+
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-deadline.C:
+			_, _ = fmt.Fprint(w, "event: close\ndata: {}\n\n")
+			flusher.Flush()
+			return
+		}
+	}
+}
+
+// authorizeTopic checks the viewer may read what a post or community topic announces
+func (h *SubscribeHandler) authorizeTopic(ctx context.Context, viewerDID, topic string) error {
+	postURI, communityDID := liveCore.TopicSubject(topic)
+	switch {
+	case postURI != "":
+		// getPost's checks: takedowns, visibility to the viewer and the post's community
+		postView, err := h.postService.GetPost(ctx, posts.GetPostRequest{URI: postURI, ViewerDID: viewerDID})
+		if err != nil {
+			return err
+		}
+		if postView.Community == nil || h.ageGate == nil {
+			return nil
+		}
+		_, gated, err := h.ageGate.GatedCommunity(ctx, viewerDID, postView.Community.DID)
+		if err != nil {
+			return err
+		}
+		if gated {
+			return errAgeGated
+		}
+		return nil
+
+	case communityDID != "":
+		community, err := h.communityService.GetCommunity(ctx, communityDID)
+		if err != nil {
+			return err
+		}
+		if community.FederationBlocked {
+			return communities.ErrFederationBlocked
+		}
+		if h.ageGate == nil {
+			return nil
+		}
+		gated, err := h.ageGate.Gated(ctx, viewerDID, community)
+		if err != nil {
+			return err
+		}
+		if gated {
+			return errAgeGated
+		}
+		return nil
+	}
+	return nil
+}
+
+// communityAvailable reports whether a community's posts can be read at all: it exists and
+// isn't suspended, deleted or federation-blocked
+func (h *SubscribeHandler) communityAvailable(ctx context.Context, communityDID string) (bool, error) {
+	community, err := h.communityService.GetCommunity(ctx, communityDID)
+	switch {
+	case err == nil:
+		return !community.FederationBlocked, nil
+	case communities.IsNotFound(err), errors.Is(err, communities.ErrCommunitySuspended), errors.Is(err, communities.ErrCommunityDeleted):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// writeTopicError writes the error a topic's read endpoint would have returned
+func writeTopicError(w http.ResponseWriter, err error) {
+	if common.WriteSentinelError(w, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, errAgeGated):
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.Forbidden, "Confirm your age to follow this community")
+	case errors.Is(err, communities.ErrCommunitySuspended):
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.CommunitySuspended, "This community has been suspended by this server")
+	case errors.Is(err, communities.ErrFederationBlocked):
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.FederationBlocked, "This community is hosted on an instance blocked by this server")
+	default:
+		if common.WriteErrorKind(w, err) {
+			return
+		}
+		log.Printf("ERROR: Failed to check live topic: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to check topic")
+	}
+}
@@ -0,0 +1,365 @@
+package live
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/communities"
+	liveCore "Coves/internal/core/live"
+	"Coves/internal/core/posts"
+)
+
+// mockCommunityService returns a fixed subscription list for the timeline topic
+// Every community exists and is readable unless it has an error or override.
+type mockCommunityService struct {
+	communities.Service
+	errs       map[string]error
+	overrides  map[string]*communities.Community
+	subscribed []string
+}
+
+func (m *mockCommunityService) GetCommunity(ctx context.Context, identifier string) (*communities.Community, error) {
+	if err := m.errs[identifier]; err != nil {
+		return nil, err
+	}
+	if community, ok := m.overrides[identifier]; ok {
+		return community, nil
+	}
+	return &communities.Community{DID: identifier, Visibility: "public"}, nil
+}
+
+func (m *mockCommunityService) GetUserSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	subs := make([]*communities.Subscription, 0, len(m.subscribed))
+	for _, did := range m.subscribed {
+		subs = append(subs, &communities.Subscription{UserDID: userDID, CommunityDID: did})
+	}
+	return subs, nil, nil
+}
+
+// mockPostService serves every post in did:plc:c unless it has an error
+type mockPostService struct {
+	posts.Service
+	errs map[string]error
+}
+
+func (m *mockPostService) GetPost(ctx context.Context, req posts.GetPostRequest) (*posts.PostView, error) {
+	if err := m.errs[req.URI]; err != nil {
+		return nil, err
+	}
+	return &posts.PostView{URI: req.URI, Community: &posts.CommunityRef{DID: "did:plc:c"}}, nil
+}
+
+// stubAgeGate gates the listed communities for every viewer
+type stubAgeGate struct {
+	communities.AgeGate
+	gated map[string]bool
+}
+
+func (g *stubAgeGate) Gated(ctx context.Context, viewerDID string, community *communities.Community) (bool, error) {
+	return g.gated[community.DID], nil
+}
+
+func (g *stubAgeGate) GatedCommunity(ctx context.Context, viewerDID, identifier string) (*communities.Community, bool, error) {
+	return &communities.Community{DID: identifier}, g.gated[identifier], nil
+}
+
+// sseEvent is one parsed server-sent event
+type sseEvent struct {
+	name string
+	data string
+}
+
+// streamClient connects to the stream and parses events off it as they arrive
+type streamClient struct {
+	events chan sseEvent
+	resp   *http.Response
+}
+
+func connect(t *testing.T, server *httptest.Server, topics ...string) *streamClient {
+	t.Helper()
+	query := url.Values{"topic": topics}
+	resp, err := http.Get(server.URL + "/xrpc/social.coves.sync.subscribe?" + query.Encode())
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	client := &streamClient{events: make(chan sseEvent, 16), resp: resp}
+	go func() {
+		defer close(client.events)
+		scanner := bufio.NewScanner(resp.Body)
+		var current sseEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if current.name != "" {
+					client.events <- current
+				}
+				current = sseEvent{}
+			case strings.HasPrefix(line, "event: "):
+				current.name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				current.data = strings.TrimPrefix(line, "data: ")
+			case line == ": heartbeat":
+				client.events <- sseEvent{name: "heartbeat"}
+			}
+		}
+	}()
+	return client
+}
+
+// next waits for the next named event (heartbeats included)
+func (c *streamClient) next(t *testing.T) sseEvent {
+	t.Helper()
+	select {
+	case event, ok := <-c.events:
+		if !ok {
+			t.Fatal("Stream closed unexpectedly")
+		}
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for an event")
+	}
+	return sseEvent{}
+}
+
+// waitForSubscribers blocks until the handler has registered with the hub
+func waitForSubscribers(t *testing.T, hub *liveCore.Hub, topic string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for hub.Subscribers(topic) != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected %d subscribers on %s, got %d", n, topic, hub.Subscribers(topic))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func newTestServer(t *testing.T, hub *liveCore.Hub, service communities.Service, cfg SubscribeConfig, userDID string) *httptest.Server {
+	return newTestServerWithPosts(t, hub, service, &mockPostService{}, nil, cfg, userDID)
+}
+
+func newTestServerWithPosts(t *testing.T, hub *liveCore.Hub, service communities.Service, postService posts.Service, gate communities.AgeGate, cfg SubscribeConfig, userDID string) *httptest.Server {
+	handler := NewSubscribeHandler(hub, service, postService, cfg)
+	if gate != nil {
+		handler.SetAgeGate(gate)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userDID != "" {
+			r = r.WithContext(middleware.SetTestUserDID(r.Context(), userDID))
+		}
+		handler.HandleSubscribe(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSubscribe_StreamsSubscribedTopicsOnly(t *testing.T) {
+	hub := liveCore.NewHub(8)
+	server := newTestServer(t, hub, &mockCommunityService{}, SubscribeConfig{Heartbeat: time.Hour}, "")
+
+	postURI := "at://did:plc:c/social.coves.community.post/1"
+	client := connect(t, server, "post:"+postURI)
+	waitForSubscribers(t, hub, liveCore.PostTopic(postURI), 1)
+
+	// What the comment consumer publishes after indexing, plus noise on other topics
+	hub.Publish(liveCore.PostTopic("at://did:plc:c/social.coves.community.post/2"), liveCore.Event{Type: liveCore.EventComment, URI: "at://elsewhere"})
+	hub.Publish(liveCore.CommunityTopic("did:plc:c"), liveCore.Event{Type: liveCore.EventPost, URI: "at://newpost"})
+	hub.Publish(liveCore.PostTopic(postURI), liveCore.Event{Type: liveCore.EventComment, URI: "at://reply", ParentURI: postURI})
+
+	event := client.next(t)
+	if event.name != liveCore.EventComment {
+		t.Fatalf("Expected a comment event, got %+v", event)
+	}
+	var payload liveCore.Event
+	if err := json.Unmarshal([]byte(event.data), &payload); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if payload.URI != "at://reply" || payload.ParentURI != postURI || payload.Topic != "post:"+postURI {
+		t.Errorf("Unexpected event payload: %+v", payload)
+	}
+
+	select {
+	case event := <-client.events:
+		t.Errorf("Unsubscribed topic leaked: %+v", event)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestSubscribe_TimelineFollowsSubscribedCommunities(t *testing.T) {
+	hub := liveCore.NewHub(8)
+	service := &mockCommunityService{subscribed: []string{"did:plc:gardening"}}
+	server := newTestServer(t, hub, service, SubscribeConfig{Heartbeat: time.Hour}, "did:plc:viewer")
+
+	client := connect(t, server, "timeline")
+	waitForSubscribers(t, hub, liveCore.CommunityTopic("did:plc:gardening"), 1)
+
+	hub.Publish(liveCore.CommunityTopic("did:plc:cooking"), liveCore.Event{Type: liveCore.EventPost, URI: "at://cooking"})
+	hub.Publish(liveCore.CommunityTopic("did:plc:gardening"), liveCore.Event{Type: liveCore.EventPost, URI: "at://gardening"})
+
+	event := client.next(t)
+	var payload liveCore.Event
+	if err := json.Unmarshal([]byte(event.data), &payload); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if payload.URI != "at://gardening" || payload.Topic != liveCore.TimelineTopic {
+		t.Errorf("Expected the subscribed community's post on the timeline topic, got %+v", payload)
+	}
+}
+
+func TestSubscribe_HeartbeatAndMaxDuration(t *testing.T) {
+	hub := liveCore.NewHub(8)
+	cfg := SubscribeConfig{Heartbeat: 20 * time.Millisecond, MaxDuration: 100 * time.Millisecond}
+	server := newTestServer(t, hub, &mockCommunityService{}, cfg, "")
+
+	client := connect(t, server, "community:did:plc:c")
+	if event := client.next(t); event.name != "heartbeat" {
+		t.Fatalf("Expected a heartbeat, got %+v", event)
+	}
+	for {
+		event := client.next(t)
+		if event.name == "close" {
+			break
+		}
+		if event.name != "heartbeat" {
+			t.Fatalf("Unexpected event: %+v", event)
+		}
+	}
+	waitForSubscribers(t, hub, liveCore.CommunityTopic("did:plc:c"), 0)
+}
+
+func TestSubscribe_DropsSlowClient(t *testing.T) {
+	hub := liveCore.NewHub(1)
+	topic := liveCore.CommunityTopic("did:plc:c")
+	sub := hub.Subscribe(map[string]string{topic: topic})
+	hub.Publish(topic, liveCore.Event{Type: liveCore.EventPost})
+	hub.Publish(topic, liveCore.Event{Type: liveCore.EventPost})
+
+	select {
+	case <-sub.Dropped():
+	default:
+		t.Fatal("Expected a subscriber that stopped reading to be dropped")
+	}
+
+	// A connected client that's dropped is told why before the stream ends
+	server := newTestServer(t, hub, &mockCommunityService{}, SubscribeConfig{Heartbeat: time.Hour}, "")
+	client := connect(t, server, "community:did:plc:other")
+	waitForSubscribers(t, hub, liveCore.CommunityTopic("did:plc:other"), 1)
+	for i := 0; i < 50; i++ {
+		hub.Publish(liveCore.CommunityTopic("did:plc:other"), liveCore.Event{Type: liveCore.EventPost, URI: "at://flood"})
+	}
+	for event := range client.events {
+		if event.name == "error" {
+			if !strings.Contains(event.data, "ConsumerTooSlow") {
+				t.Errorf("Unexpected error event: %+v", event)
+			}
+			return
+		}
+	}
+	// The flood may also have been delivered in full before the buffer filled up
+	t.Log("Client kept up with the flood")
+}
+
+func TestSubscribe_RejectsBadRequests(t *testing.T) {
+	hub := liveCore.NewHub(8)
+	server := newTestServer(t, hub, &mockCommunityService{}, SubscribeConfig{MaxTopics: 2}, "")
+
+	tests := []struct {
+		name       string
+		topics     []string
+		wantStatus int
+	}{
+		{name: "no topics", wantStatus: http.StatusBadRequest},
+		{name: "unknown topic", topics: []string{"firehose"}, wantStatus: http.StatusBadRequest},
+		{name: "too many topics", topics: []string{"community:did:plc:a", "community:did:plc:b", "community:did:plc:c"}, wantStatus: http.StatusBadRequest},
+		{name: "timeline needs auth", topics: []string{"timeline"}, wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "/xrpc/social.coves.sync.subscribe?" + url.Values{"topic": tt.topics}.Encode())
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+		})
+	}
+}
+
+func TestSubscribe_ChecksTopicAccess(t *testing.T) {
+	hub := liveCore.NewHub(8)
+	hiddenPost := "at://did:plc:c/social.coves.community.post/hidden"
+	service := &mockCommunityService{
+		errs: map[string]error{
+			"did:plc:suspended": communities.ErrCommunitySuspended,
+			"did:plc:deleted":   communities.ErrCommunityDeleted,
+			"did:plc:missing":   communities.ErrCommunityNotFound,
+		},
+		overrides: map[string]*communities.Community{
+			"did:plc:blocked": {DID: "did:plc:blocked", FederationBlocked: true},
+		},
+	}
+	postService := &mockPostService{errs: map[string]error{hiddenPost: posts.ErrNotFound}}
+	gate := &stubAgeGate{gated: map[string]bool{"did:plc:nsfw": true}}
+	server := newTestServerWithPosts(t, hub, service, postService, gate, SubscribeConfig{}, "")
+
+	tests := []struct {
+		name       string
+		topic      string
+		wantStatus int
+	}{
+		{name: "hidden or missing post", topic: "post:" + hiddenPost, wantStatus: http.StatusNotFound},
+		{name: "suspended community", topic: "community:did:plc:suspended", wantStatus: http.StatusForbidden},
+		{name: "deleted community", topic: "community:did:plc:deleted", wantStatus: http.StatusGone},
+		{name: "unknown community", topic: "community:did:plc:missing", wantStatus: http.StatusNotFound},
+		{name: "federation-blocked community", topic: "community:did:plc:blocked", wantStatus: http.StatusForbidden},
+		{name: "age-gated community", topic: "community:did:plc:nsfw", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Get(server.URL + "/xrpc/social.coves.sync.subscribe?" + url.Values{"topic": {tt.topic}}.Encode())
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, resp.StatusCode)
+			}
+		})
+	}
+	if n := hub.Subscribers(liveCore.PostTopic(hiddenPost)); n != 0 {
+		t.Errorf("Expected refused topics not to be subscribed, got %d subscribers", n)
+	}
+}
+
+func TestSubscribe_TimelineSkipsUnavailableCommunities(t *testing.T) {
+	hub := liveCore.NewHub(8)
+	service := &mockCommunityService{
+		subscribed: []string{"did:plc:gardening", "did:plc:suspended", "did:plc:blocked"},
+		errs:       map[string]error{"did:plc:suspended": communities.ErrCommunitySuspended},
+		overrides:  map[string]*communities.Community{"did:plc:blocked": {DID: "did:plc:blocked", FederationBlocked: true}},
+	}
+	server := newTestServer(t, hub, service, SubscribeConfig{Heartbeat: time.Hour}, "did:plc:viewer")
+
+	connect(t, server, "timeline")
+	waitForSubscribers(t, hub, liveCore.CommunityTopic("did:plc:gardening"), 1)
+	for _, did := range []string{"did:plc:suspended", "did:plc:blocked"} {
+		if n := hub.Subscribers(liveCore.CommunityTopic(did)); n != 0 {
+			t.Errorf("Expected the timeline to leave out %s, got %d subscribers", did, n)
+		}
+	}
+}
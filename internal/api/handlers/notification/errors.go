@@ -0,0 +1,38 @@
+package notification
+
+import (
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/xrpcerror"
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// handleServiceError maps notification service errors to HTTP responses
+func handleServiceError(w http.ResponseWriter, err error) {
+	if common.WriteSentinelError(w, err) {
+		return
+	}
+	if common.WriteErrorKind(w, err) {
+		return
+	}
+	log.Printf("ERROR: Notification service error: %v", err)
+	xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An error occurred while processing notifications")
+}
+
+// writeJSONResponse buffers the JSON encoding before sending headers
+func writeJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		log.Printf("ERROR: Failed to encode JSON response: %v", err)
+		xrpcerror.WriteEncodeFailure(w)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("ERROR: Failed to write response body: %v", err)
+	}
+}
@@ -0,0 +1,111 @@
+package notification
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/notifications"
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 100
+)
+
+// ListNotificationsResponse is the response for social.coves.notification.listNotifications
+// Cursor is the offset of the next page, omitted on the last page
+type ListNotificationsResponse struct {
+	Cursor        string                        `json:"cursor,omitempty"`
+	Notifications []*notifications.Notification `json:"notifications"`
+}
+
+// Handler serves the authenticated user's notifications
+type Handler struct {
+	service notifications.Service
+}
+
+// NewHandler creates a new notification handler
+func NewHandler(service notifications.Service) *Handler {
+	return &Handler{service: service}
+}
+
+// HandleListNotifications lists the caller's notifications, latest activity first
+// Replies to the same post or comment within an hour are one notification.
+// GET /xrpc/social.coves.notification.listNotifications?limit=50&cursor=0
+func (h *Handler) HandleListNotifications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	limit := defaultListLimit
+	if s := r.URL.Query().Get("limit"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 1 || parsed > maxListLimit {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "limit must be between 1 and 100")
+			return
+		}
+		limit = parsed
+	}
+	offset := 0
+	if s := r.URL.Query().Get("cursor"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed < 0 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidCursor, "Invalid cursor")
+			return
+		}
+		offset = parsed
+	}
+
+	list, err := h.service.ListNotifications(r.Context(), notifications.ListNotificationsRequest{
+		RecipientDID: userDID,
+		Limit:        limit,
+		Offset:       offset,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	response := ListNotificationsResponse{Notifications: list}
+	if len(list) == limit {
+		response.Cursor = strconv.Itoa(offset + limit)
+	}
+	writeJSONResponse(w, http.StatusOK, response)
+}
+
+// HandleUpdateSeen marks the caller's notifications read
+// POST /xrpc/social.coves.notification.updateSeen
+// Body: { "ids": [42, 43] }
+func (h *Handler) HandleUpdateSeen(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	var req notifications.UpdateSeenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	req.RecipientDID = userDID
+
+	if err := h.service.UpdateSeen(r.Context(), req); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	writeJSONResponse(w, http.StatusOK, map[string]interface{}{})
+}
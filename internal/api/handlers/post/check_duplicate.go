@@ -0,0 +1,67 @@
+package post
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/posts"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// CheckDuplicateHandler answers the composer's "already posted here" check
+type CheckDuplicateHandler struct {
+	service posts.Service
+}
+
+// NewCheckDuplicateHandler creates a new check duplicate handler
+func NewCheckDuplicateHandler(service posts.Service) *CheckDuplicateHandler {
+	return &CheckDuplicateHandler{
+		service: service,
+	}
+}
+
+// CheckDuplicateResponse matches the social.coves.feed.checkDuplicate output
+type CheckDuplicateResponse struct {
+	Matches []*posts.DuplicateMatch `json:"matches"`
+}
+
+// HandleCheckDuplicate lists posts in a community sharing a link or a similar title
+// GET /xrpc/social.coves.feed.checkDuplicate?community=...&url=...&title=...
+// An empty matches list means the post is clear to submit.
+func (h *CheckDuplicateHandler) HandleCheckDuplicate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	query := r.URL.Query()
+	req := posts.CheckDuplicateRequest{
+		Community: query.Get("community"),
+		URL:       query.Get("url"),
+		Title:     query.Get("title"),
+		ViewerDID: userDID,
+	}
+	if req.Community == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community parameter is required")
+		return
+	}
+
+	matches, err := h.service.CheckDuplicate(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(CheckDuplicateResponse{Matches: matches}); err != nil {
+		log.Printf("Failed to encode duplicate check response: %v", err)
+	}
+}
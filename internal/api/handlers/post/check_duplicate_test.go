@@ -0,0 +1,107 @@
+package post
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/posts"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func checkDuplicate(handler *CheckDuplicateHandler, target, viewerDID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if viewerDID != "" {
+		req = req.WithContext(middleware.SetTestUserDID(req.Context(), viewerDID))
+	}
+	w := httptest.NewRecorder()
+	handler.HandleCheckDuplicate(w, req)
+	return w
+}
+
+func TestCheckDuplicateHandler(t *testing.T) {
+	title := "Rust 2.0 released"
+	score := 42
+	var gotReq posts.CheckDuplicateRequest
+	service := &mockPostService{
+		checkDuplicateFunc: func(ctx context.Context, req posts.CheckDuplicateRequest) ([]*posts.DuplicateMatch, error) {
+			gotReq = req
+			return []*posts.DuplicateMatch{{
+				URI:       testPostURI,
+				Title:     &title,
+				Match:     posts.DuplicateMatchURL,
+				Score:     &score,
+				CreatedAt: time.Now().Add(-72 * time.Hour),
+			}}, nil
+		},
+	}
+	handler := NewCheckDuplicateHandler(service)
+
+	w := checkDuplicate(handler, "/xrpc/social.coves.feed.checkDuplicate?community=rust.community.coves.social&url=https://example.com/a&title=Rust", "did:plc:viewer")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotReq.Community != "rust.community.coves.social" || gotReq.URL != "https://example.com/a" || gotReq.Title != "Rust" || gotReq.ViewerDID != "did:plc:viewer" {
+		t.Errorf("Expected the query to be passed through, got %+v", gotReq)
+	}
+
+	var resp CheckDuplicateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Matches) != 1 || resp.Matches[0].URI != testPostURI || resp.Matches[0].Match != posts.DuplicateMatchURL {
+		t.Errorf("Unexpected matches: %+v", resp.Matches)
+	}
+}
+
+func TestCheckDuplicateHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		viewerDID  string
+		serviceErr error
+		wantStatus int
+	}{
+		{
+			name:       "unauthenticated",
+			target:     "/xrpc/social.coves.feed.checkDuplicate?community=did:plc:community&url=https://example.com",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "missing community",
+			target:     "/xrpc/social.coves.feed.checkDuplicate?url=https://example.com",
+			viewerDID:  "did:plc:viewer",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid url",
+			target:     "/xrpc/social.coves.feed.checkDuplicate?community=did:plc:community&url=notaurl",
+			viewerDID:  "did:plc:viewer",
+			serviceErr: posts.NewValidationError("url", "invalid URL"),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "private community",
+			target:     "/xrpc/social.coves.feed.checkDuplicate?community=did:plc:community&title=Something+long",
+			viewerDID:  "did:plc:viewer",
+			serviceErr: posts.ErrNotAuthorized,
+			wantStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockPostService{
+				checkDuplicateFunc: func(ctx context.Context, req posts.CheckDuplicateRequest) ([]*posts.DuplicateMatch, error) {
+					return nil, tt.serviceErr
+				},
+			}
+			w := checkDuplicate(NewCheckDuplicateHandler(service), tt.target, tt.viewerDID)
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -2,8 +2,10 @@ package post
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/posts"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -26,7 +28,7 @@ func NewCreateHandler(service posts.Service) *CreateHandler {
 func (h *CreateHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	// 1. Check HTTP method
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -38,25 +40,26 @@ func (h *CreateHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	var req posts.CreatePostRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		// Check if error is due to body size limit
-		if err.Error() == "http: request body too large" {
-			writeError(w, http.StatusRequestEntityTooLarge, "RequestTooLarge",
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			xrpcerror.WriteError(w, http.StatusRequestEntityTooLarge, xrpcerror.RequestTooLarge,
 				"Request body too large (max 1MB)")
 			return
 		}
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	// 4. Extract authenticated user DID from request context (injected by auth middleware)
 	userDID := middleware.GetUserDID(r)
 	if userDID == "" {
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
 	// 5. Validate required fields
 	if req.Community == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "community is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "community is required")
 		return
 	}
 
@@ -72,7 +75,7 @@ func (h *CreateHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 
 	// Scoped handles must include @ symbol
 	if strings.HasPrefix(req.Community, "!") && !strings.Contains(req.Community, "@") {
-		writeError(w, http.StatusBadRequest, "InvalidRequest",
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest,
 			"scoped handle must include @ symbol (!name@instance)")
 		return
 	}
@@ -80,7 +83,7 @@ func (h *CreateHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	// 6. SECURITY: Reject client-provided authorDid
 	// This prevents users from impersonating other users
 	if req.AuthorDID != "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest",
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest,
 			"authorDid must not be provided - derived from authenticated user")
 		return
 	}
@@ -2,6 +2,7 @@ package post
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/posts"
 	"encoding/json"
 	"errors"
@@ -37,7 +38,7 @@ type DeletePostOutput struct{}
 func (h *DeleteHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 	// 1. Check method is POST
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -47,14 +48,14 @@ func (h *DeleteHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 	// 3. Parse JSON body into DeletePostInput
 	var input DeletePostInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	// 4. Get OAuth session from context (injected by auth middleware)
 	session := middleware.GetOAuthSession(r)
 	if session == nil {
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
@@ -84,21 +85,21 @@ func (h *DeleteHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
 func handleDeleteError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.Is(err, posts.ErrNotFound):
-		writeError(w, http.StatusNotFound, "PostNotFound", "Post not found")
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.PostNotFound, "Post not found")
 
 	case errors.Is(err, posts.ErrNotAuthorized):
-		writeError(w, http.StatusForbidden, "NotAuthorized", "You are not authorized to delete this post")
+		xrpcerror.WriteError(w, http.StatusForbidden, xrpcerror.NotAuthorized, "You are not authorized to delete this post")
 
 	case errors.Is(err, posts.ErrCommunityNotFound):
-		writeError(w, http.StatusNotFound, "CommunityNotFound", "Community not found")
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.CommunityNotFound, "Community not found")
 
 	case posts.IsValidationError(err):
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
 
 	default:
 		// Don't leak internal error details to clients
 		log.Printf("Unexpected error in post delete handler: %v", err)
-		writeError(w, http.StatusInternalServerError, "InternalServerError",
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError,
 			"An internal error occurred")
 	}
 }
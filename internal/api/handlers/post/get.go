@@ -0,0 +1,174 @@
+package post
+
+import (
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/views"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/votes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+const (
+	// defaultCommentLimit is the number of top-level comments included when commentLimit is unset
+	defaultCommentLimit = 10
+	// maxCommentLimit caps commentLimit; longer threads are paged through getComments
+	maxCommentLimit = 50
+)
+
+// GetHandler handles single post retrieval for permalink pages
+type GetHandler struct {
+	service        posts.Service
+	commentService comments.Service
+	voteService    votes.Service
+	blueskyService blueskypost.Service
+	moderators     common.ModeratorLookup
+	aggregators    common.AggregatorLookup
+	ageGate        communities.AgeGate
+}
+
+// NewGetHandler creates a new handler for fetching a single post
+// commentService may be nil, in which case includeComments is ignored.
+func NewGetHandler(
+	service posts.Service,
+	commentService comments.Service,
+	voteService votes.Service,
+	blueskyService blueskypost.Service,
+	moderators common.ModeratorLookup,
+	aggregators common.AggregatorLookup,
+) *GetHandler {
+	return &GetHandler{
+		service:        service,
+		commentService: commentService,
+		voteService:    voteService,
+		blueskyService: blueskyService,
+		moderators:     moderators,
+		aggregators:    aggregators,
+	}
+}
+
+// SetAgeGate withholds posts in age-restricted communities from viewers who haven't confirmed their age
+func (h *GetHandler) SetAgeGate(gate communities.AgeGate) {
+	h.ageGate = gate
+}
+
+// GetPostOutput matches the lexicon output schema for social.coves.feed.getPost
+type GetPostOutput struct {
+	Post     *posts.PostView               `json:"post"`
+	Comments []*comments.ThreadViewComment `json:"comments,omitempty"`
+}
+
+// HandleGetPost retrieves a single post by AT-URI
+// GET /xrpc/social.coves.feed.getPost?uri=at://...&includeComments=true&commentLimit=10
+//
+// The post gets the same viewer state, score hiding, badges and attribution as feed posts.
+// With includeComments=true the first commentLimit top-level comments, sorted hot, are included.
+// Posts in age-restricted communities are replaced by a gated response until the viewer confirms.
+func (h *GetHandler) HandleGetPost(w http.ResponseWriter, r *http.Request) {
+	// 1. Check method is GET
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	// 2. Parse query parameters
+	query := r.URL.Query()
+	uri := query.Get("uri")
+	if uri == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "uri parameter is required")
+		return
+	}
+
+	includeComments := false
+	if raw := query.Get("includeComments"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "includeComments must be a boolean")
+			return
+		}
+		includeComments = parsed
+	}
+
+	commentLimit := defaultCommentLimit
+	if raw := query.Get("commentLimit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "commentLimit must be a positive integer")
+			return
+		}
+		commentLimit = min(parsed, maxCommentLimit)
+	}
+
+	// 3. Response shape version (query param or Accept header)
+	version, err := views.ParseVersion(r)
+	if err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.UnsupportedVersion, err.Error())
+		return
+	}
+
+	// 4. Load the post (optional auth: viewer DID is empty for anonymous requests)
+	viewerDID := middleware.GetUserDID(r)
+	postView, err := h.service.GetPost(r.Context(), posts.GetPostRequest{URI: uri, ViewerDID: viewerDID})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	// 5. Gated viewers get the post's community but not the post
+	if h.ageGate != nil && postView.Community != nil {
+		community, gated, gateErr := h.ageGate.GatedCommunity(r.Context(), viewerDID, postView.Community.DID)
+		if gateErr != nil {
+			handleServiceError(w, gateErr)
+			return
+		}
+		if gated {
+			common.WriteAgeGatedPost(w, community)
+			return
+		}
+	}
+
+	// 6. Hydrate the post exactly as feeds do
+	feed := []*posts.FeedViewPost{{Post: postView}}
+	common.PopulateViewerVoteState(r.Context(), r, h.voteService, feed)
+	common.HideFeedScores(r.Context(), r, h.moderators, feed)
+	common.AddFeedAuthorBadges(r.Context(), h.moderators, feed)
+	common.PopulateAggregatorAttribution(r.Context(), h.aggregators, feed)
+	posts.TransformBlobRefsToURLs(postView)
+	posts.TransformPostEmbeds(r.Context(), postView, h.blueskyService)
+
+	output := GetPostOutput{Post: postView}
+
+	// 7. Optionally include the first top-level comments, loaded in batch by the comment service
+	if includeComments && h.commentService != nil {
+		var viewerPtr *string
+		if viewerDID != "" {
+			viewerPtr = &viewerDID
+		}
+		resp, err := h.commentService.GetComments(r.Context(), &comments.GetCommentsRequest{
+			PostURI:   postView.URI,
+			Sort:      "best",
+			Depth:     0,
+			Limit:     commentLimit,
+			ViewerDID: viewerPtr,
+		})
+		if err != nil {
+			handleServiceError(w, err)
+			return
+		}
+		output.Comments = resp.Comments
+	}
+
+	// 8. Return the post
+	w.Header().Set("Content-Type", views.ContentType(version))
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(views.BuildPostResponse(version, output.Post, output.Comments)); err != nil {
+		log.Printf("Failed to encode getPost response: %v", err)
+	}
+}
@@ -0,0 +1,363 @@
+package post
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/takedown"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	oauthlib "github.com/bluesky-social/indigo/atproto/auth/oauth"
+)
+
+const testPostURI = "at://did:plc:community/social.coves.community.post/3kpost"
+
+// mockPostService implements posts.Service for testing
+type mockPostService struct {
+	getPostFunc        func(ctx context.Context, req posts.GetPostRequest) (*posts.PostView, error)
+	checkDuplicateFunc func(ctx context.Context, req posts.CheckDuplicateRequest) ([]*posts.DuplicateMatch, error)
+}
+
+func (m *mockPostService) CreatePost(ctx context.Context, req posts.CreatePostRequest) (*posts.CreatePostResponse, error) {
+	return nil, nil
+}
+
+func (m *mockPostService) GetAuthorPosts(ctx context.Context, req posts.GetAuthorPostsRequest) (*posts.GetAuthorPostsResponse, error) {
+	return nil, nil
+}
+
+func (m *mockPostService) DeletePost(ctx context.Context, session *oauthlib.ClientSessionData, req posts.DeletePostRequest) error {
+	return nil
+}
+
+func (m *mockPostService) CheckDuplicate(ctx context.Context, req posts.CheckDuplicateRequest) ([]*posts.DuplicateMatch, error) {
+	if m.checkDuplicateFunc != nil {
+		return m.checkDuplicateFunc(ctx, req)
+	}
+	return []*posts.DuplicateMatch{}, nil
+}
+
+func (m *mockPostService) GetPost(ctx context.Context, req posts.GetPostRequest) (*posts.PostView, error) {
+	if m.getPostFunc != nil {
+		return m.getPostFunc(ctx, req)
+	}
+	return &posts.PostView{
+		URI:       req.URI,
+		Author:    &posts.AuthorView{DID: "did:plc:author", Handle: "author.coves.social"},
+		Community: &posts.CommunityRef{DID: "did:plc:community"},
+		Stats:     &posts.PostStats{Upvotes: 3, Score: 3},
+	}, nil
+}
+
+// mockCommentService serves a post's top-level comments
+// Methods the handler doesn't call fall through to the nil embedded interface and panic.
+type mockCommentService struct {
+	comments.Service
+	gotReq *comments.GetCommentsRequest
+}
+
+func (m *mockCommentService) GetComments(ctx context.Context, req *comments.GetCommentsRequest) (*comments.GetCommentsResponse, error) {
+	m.gotReq = req
+	return &comments.GetCommentsResponse{
+		Comments: []*comments.ThreadViewComment{
+			{Comment: &comments.CommentView{URI: "at://did:plc:alice/social.coves.community.comment/3kc1"}},
+			{Comment: &comments.CommentView{URI: "at://did:plc:bob/social.coves.community.comment/3kc2"}},
+		},
+	}, nil
+}
+
+func TestGetPostHandler_Success(t *testing.T) {
+	var gotReq posts.GetPostRequest
+	service := &mockPostService{
+		getPostFunc: func(ctx context.Context, req posts.GetPostRequest) (*posts.PostView, error) {
+			gotReq = req
+			return &posts.PostView{URI: req.URI, Community: &posts.CommunityRef{DID: "did:plc:community"}}, nil
+		},
+	}
+	commentService := &mockCommentService{}
+	handler := NewGetHandler(service, commentService, nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getPost?uri="+testPostURI, nil)
+	req = req.WithContext(middleware.SetTestUserDID(req.Context(), "did:plc:viewer"))
+	w := httptest.NewRecorder()
+	handler.HandleGetPost(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if gotReq.URI != testPostURI || gotReq.ViewerDID != "did:plc:viewer" {
+		t.Errorf("Unexpected service request: %+v", gotReq)
+	}
+	if commentService.gotReq != nil {
+		t.Error("Expected comments not to be loaded without includeComments")
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	post, ok := body["post"].(map[string]interface{})
+	if !ok || post["uri"] != testPostURI {
+		t.Errorf("Expected post %s in response, got %v", testPostURI, body["post"])
+	}
+	if _, ok := body["comments"]; ok {
+		t.Error("Expected no comments key without includeComments")
+	}
+}
+
+func TestGetPostHandler_IncludeComments(t *testing.T) {
+	commentService := &mockCommentService{}
+	handler := NewGetHandler(&mockPostService{}, commentService, nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getPost?uri="+testPostURI+"&includeComments=true&commentLimit=5", nil)
+	w := httptest.NewRecorder()
+	handler.HandleGetPost(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	got := commentService.gotReq
+	if got == nil {
+		t.Fatal("Expected comments to be loaded")
+	}
+	if got.PostURI != testPostURI || got.Sort != "best" || got.Depth != 0 || got.Limit != 5 {
+		t.Errorf("Expected the first 5 top-level comments sorted best, got %+v", got)
+	}
+	if got.ViewerDID != nil {
+		t.Errorf("Expected no viewer for an anonymous request, got %s", *got.ViewerDID)
+	}
+
+	var body struct {
+		Comments []struct {
+			Comment struct {
+				URI string `json:"uri"`
+			} `json:"comment"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(body.Comments) != 2 {
+		t.Fatalf("Expected 2 comments, got %d", len(body.Comments))
+	}
+	if body.Comments[0].Comment.URI != "at://did:plc:alice/social.coves.community.comment/3kc1" {
+		t.Errorf("Expected comments in service order, got %s first", body.Comments[0].Comment.URI)
+	}
+}
+
+func TestGetPostHandler_CommentLimitCapped(t *testing.T) {
+	commentService := &mockCommentService{}
+	handler := NewGetHandler(&mockPostService{}, commentService, nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getPost?uri="+testPostURI+"&includeComments=true&commentLimit=500", nil)
+	w := httptest.NewRecorder()
+	handler.HandleGetPost(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if commentService.gotReq.Limit != maxCommentLimit {
+		t.Errorf("Expected limit capped at %d, got %d", maxCommentLimit, commentService.gotReq.Limit)
+	}
+}
+
+func TestGetPostHandler_Errors(t *testing.T) {
+	tests := []struct {
+		err        error
+		name       string
+		query      string
+		wantError  string
+		wantStatus int
+	}{
+		{
+			name:       "missing uri",
+			query:      "",
+			wantStatus: http.StatusBadRequest,
+			wantError:  xrpcerror.InvalidRequest,
+		},
+		{
+			name:       "invalid includeComments",
+			query:      "?uri=" + testPostURI + "&includeComments=maybe",
+			wantStatus: http.StatusBadRequest,
+			wantError:  xrpcerror.InvalidRequest,
+		},
+		{
+			name:       "invalid commentLimit",
+			query:      "?uri=" + testPostURI + "&includeComments=true&commentLimit=0",
+			wantStatus: http.StatusBadRequest,
+			wantError:  xrpcerror.InvalidRequest,
+		},
+		{
+			name:       "malformed uri",
+			query:      "?uri=not-a-uri",
+			err:        posts.NewValidationError("uri", "uri must be a valid AT-URI"),
+			wantStatus: http.StatusBadRequest,
+			wantError:  xrpcerror.InvalidRequest,
+		},
+		{
+			name:       "unknown or deleted post",
+			query:      "?uri=" + testPostURI,
+			err:        posts.ErrNotFound,
+			wantStatus: http.StatusNotFound,
+			wantError:  xrpcerror.PostNotFound,
+		},
+		{
+			name:       "taken-down post",
+			query:      "?uri=" + testPostURI,
+			err:        &takedown.TakenDownError{Reason: takedown.ReasonLegal},
+			wantStatus: http.StatusGone,
+			wantError:  xrpcerror.RecordTakenDown,
+		},
+		{
+			name:       "suspended community",
+			query:      "?uri=" + testPostURI,
+			err:        posts.ErrCommunitySuspended,
+			wantStatus: http.StatusForbidden,
+			wantError:  xrpcerror.CommunitySuspended,
+		},
+		{
+			name:       "deleted community",
+			query:      "?uri=" + testPostURI,
+			err:        posts.ErrCommunityDeleted,
+			wantStatus: http.StatusGone,
+			wantError:  xrpcerror.CommunityDeleted,
+		},
+		{
+			name:       "federation-blocked community",
+			query:      "?uri=" + testPostURI,
+			err:        posts.ErrCommunityFederationBlocked,
+			wantStatus: http.StatusForbidden,
+			wantError:  xrpcerror.FederationBlocked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &mockPostService{
+				getPostFunc: func(ctx context.Context, req posts.GetPostRequest) (*posts.PostView, error) {
+					if tt.err == nil {
+						t.Fatal("Expected the request to be rejected before reaching the service")
+					}
+					return nil, tt.err
+				},
+			}
+			commentService := &mockCommentService{}
+			handler := NewGetHandler(service, commentService, nil, nil, nil, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getPost"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.HandleGetPost(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode error: %v", err)
+			}
+			if body["error"] != tt.wantError {
+				t.Errorf("Expected error %s, got %v", tt.wantError, body["error"])
+			}
+			if commentService.gotReq != nil {
+				t.Error("Expected no comment load for an unavailable post")
+			}
+		})
+	}
+}
+
+// stubCommunityService serves one community to the age gate
+// Methods the gate doesn't call fall through to the nil embedded interface and panic.
+type stubCommunityService struct {
+	communities.Service
+	community *communities.Community
+}
+
+func (s *stubCommunityService) GetCommunity(ctx context.Context, identifier string) (*communities.Community, error) {
+	return s.community, nil
+}
+
+// stubAgeConfirmations holds confirmations as "user|community" keys
+type stubAgeConfirmations map[string]bool
+
+func (s stubAgeConfirmations) ConfirmAge(ctx context.Context, userDID, communityDID string) error {
+	s[userDID+"|"+communityDID] = true
+	return nil
+}
+
+func (s stubAgeConfirmations) HasConfirmedAge(ctx context.Context, userDID, communityDID string) (bool, error) {
+	return s[userDID+"|"+communityDID], nil
+}
+
+func TestGetPostHandler_AgeGate(t *testing.T) {
+	nsfw := &communities.Community{DID: "did:plc:community", Name: "adult", ContentWarnings: []string{"nsfw"}}
+	confirmations := stubAgeConfirmations{"did:plc:confirmed|did:plc:community": true}
+
+	tests := []struct {
+		name      string
+		viewerDID string
+		community *communities.Community
+		wantGated bool
+	}{
+		{name: "anonymous viewer", community: nsfw, wantGated: true},
+		{name: "unconfirmed viewer", viewerDID: "did:plc:unconfirmed", community: nsfw, wantGated: true},
+		{name: "confirmed viewer", viewerDID: "did:plc:confirmed", community: nsfw},
+		{name: "community without nsfw warning", community: &communities.Community{DID: "did:plc:community", Name: "family"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewGetHandler(&mockPostService{}, nil, nil, nil, nil, nil)
+			handler.SetAgeGate(communities.NewAgeGate(&stubCommunityService{community: tt.community}, confirmations))
+
+			req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getPost?uri="+testPostURI, nil)
+			if tt.viewerDID != "" {
+				req = req.WithContext(middleware.SetTestUserDID(req.Context(), tt.viewerDID))
+			}
+			w := httptest.NewRecorder()
+			handler.HandleGetPost(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var body struct {
+				Post      *posts.PostView                    `json:"post"`
+				Community *communities.CommunityViewDetailed `json:"community"`
+				Gated     bool                               `json:"gated"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if body.Gated != tt.wantGated {
+				t.Fatalf("Expected gated=%t, got %s", tt.wantGated, w.Body.String())
+			}
+			if tt.wantGated {
+				if body.Post != nil {
+					t.Error("Expected no post in a gated response")
+				}
+				if body.Community == nil || body.Community.DID != nsfw.DID || !body.Community.AgeConfirmationRequired {
+					t.Errorf("Expected the community's metadata, got %+v", body.Community)
+				}
+			} else if body.Post == nil || body.Post.URI != testPostURI {
+				t.Errorf("Expected the post, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGetPostHandler_MethodNotAllowed(t *testing.T) {
+	handler := NewGetHandler(&mockPostService{}, nil, nil, nil, nil, nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/xrpc/social.coves.feed.getPost?uri="+testPostURI, nil)
+	w := httptest.NewRecorder()
+	handler.HandleGetPost(w, req)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", w.Code)
+	}
+}
@@ -0,0 +1,52 @@
+package resolve
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/permalinks"
+)
+
+// URIHandler resolves AT-URIs to web paths
+type URIHandler struct {
+	service permalinks.Service
+}
+
+// NewURIHandler creates a new AT-URI resolve handler
+func NewURIHandler(service permalinks.Service) *URIHandler {
+	return &URIHandler{service: service}
+}
+
+// HandleResolveURI returns the canonical web path of a post, comment or community
+// GET /xrpc/social.coves.resolve.uri?uri=at://...
+func (h *URIHandler) HandleResolveURI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	uri := r.URL.Query().Get("uri")
+	if uri == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "uri is required")
+		return
+	}
+
+	permalink, err := h.service.Resolve(r.Context(), uri)
+	if err != nil {
+		if common.WriteErrorKind(w, err) {
+			return
+		}
+		log.Printf("ERROR: Failed to resolve %s: %v", uri, err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(permalink); err != nil {
+		log.Printf("ERROR: Failed to encode resolve response: %v", err)
+	}
+}
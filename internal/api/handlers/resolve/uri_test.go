@@ -0,0 +1,94 @@
+package resolve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"Coves/internal/core/permalinks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo serves one subject of each type
+type fakeRepo struct{}
+
+func (fakeRepo) GetSubjects(_ context.Context, uris []string) (map[string]*permalinks.Subject, error) {
+	known := map[string]*permalinks.Subject{
+		"at://did:plc:gaming/social.coves.community.post/3kpost": {
+			Type: permalinks.SubjectPost, CommunityDID: "did:plc:gaming", CommunityHandle: "c-gaming.coves.local", PostRKey: "3kpost",
+		},
+		"at://did:plc:alice/social.coves.community.comment/3kcomment": {
+			Type: permalinks.SubjectComment, CommunityDID: "did:plc:gaming", CommunityHandle: "c-gaming.coves.local",
+			PostRKey: "3kpost", CommentRKey: "3kcomment", Deleted: true,
+		},
+		"at://did:plc:gaming": {
+			Type: permalinks.SubjectCommunity, CommunityDID: "did:plc:gaming", CommunityHandle: "c-gaming.coves.local",
+		},
+	}
+	found := make(map[string]*permalinks.Subject)
+	for _, uri := range uris {
+		if subject, ok := known[uri]; ok {
+			found[uri] = subject
+		}
+	}
+	return found, nil
+}
+
+func doResolve(t *testing.T, method, uri string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := NewURIHandler(permalinks.NewPermalinkService(fakeRepo{}))
+	req := httptest.NewRequest(method, "/xrpc/social.coves.resolve.uri?"+url.Values{"uri": {uri}}.Encode(), nil)
+	w := httptest.NewRecorder()
+	handler.HandleResolveURI(w, req)
+	return w
+}
+
+func TestHandleResolveURI(t *testing.T) {
+	tests := []struct {
+		name string
+		uri  string
+		want permalinks.Permalink
+	}{
+		{
+			name: "post",
+			uri:  "at://did:plc:gaming/social.coves.community.post/3kpost",
+			want: permalinks.Permalink{URI: "at://did:plc:gaming/social.coves.community.post/3kpost", Path: "/c/c-gaming.coves.local/post/3kpost", Type: "post"},
+		},
+		{
+			name: "deleted comment",
+			uri:  "at://did:plc:alice/social.coves.community.comment/3kcomment",
+			want: permalinks.Permalink{
+				URI: "at://did:plc:alice/social.coves.community.comment/3kcomment", Path: "/c/c-gaming.coves.local/post/3kpost/comment/3kcomment",
+				Type: "comment", Deleted: true,
+			},
+		},
+		{
+			name: "community",
+			uri:  "at://did:plc:gaming/social.coves.community.profile/self",
+			want: permalinks.Permalink{URI: "at://did:plc:gaming", Path: "/c/c-gaming.coves.local", Type: "community"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doResolve(t, http.MethodGet, tt.uri)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var got permalinks.Permalink
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHandleResolveURI_Errors(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, doResolve(t, http.MethodGet, "").Code, "missing uri")
+	assert.Equal(t, http.StatusBadRequest, doResolve(t, http.MethodGet, "at://did:plc:alice/social.coves.community.vote/3kvote").Code, "unsupported collection")
+	assert.Equal(t, http.StatusNotFound, doResolve(t, http.MethodGet, "at://did:plc:gaming/social.coves.community.post/3kmissing").Code, "unindexed post")
+	assert.Equal(t, http.StatusMethodNotAllowed, doResolve(t, http.MethodPost, "at://did:plc:gaming").Code)
+}
@@ -9,9 +9,12 @@ import (
 
 	"Coves/internal/api/handlers/common"
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/views"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/blueskypost"
 	"Coves/internal/core/posts"
 	"Coves/internal/core/timeline"
+	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
 )
 
@@ -20,10 +23,13 @@ type GetTimelineHandler struct {
 	service        timeline.Service
 	voteService    votes.Service
 	blueskyService blueskypost.Service
+	moderators     common.ModeratorLookup
+	aggregators    common.AggregatorLookup
+	preferences    common.PreferencesLookup
 }
 
 // NewGetTimelineHandler creates a new timeline handler
-func NewGetTimelineHandler(service timeline.Service, voteService votes.Service, blueskyService blueskypost.Service) *GetTimelineHandler {
+func NewGetTimelineHandler(service timeline.Service, voteService votes.Service, blueskyService blueskypost.Service, moderators common.ModeratorLookup, aggregators common.AggregatorLookup) *GetTimelineHandler {
 	if blueskyService == nil {
 		log.Printf("[TIMELINE-HANDLER] WARNING: blueskyService is nil - Bluesky post embeds will not be resolved")
 	}
@@ -31,29 +37,44 @@ func NewGetTimelineHandler(service timeline.Service, voteService votes.Service,
 		service:        service,
 		voteService:    voteService,
 		blueskyService: blueskyService,
+		moderators:     moderators,
+		aggregators:    aggregators,
 	}
 }
 
+// SetPreferences enables viewer preference defaults for sort and includeNsfw when the
+// request leaves them out; without it the server defaults apply
+func (h *GetTimelineHandler) SetPreferences(preferences common.PreferencesLookup) {
+	h.preferences = preferences
+}
+
 // HandleGetTimeline retrieves posts from all communities the user subscribes to
 // GET /xrpc/social.coves.feed.getTimeline?sort=hot&limit=15&cursor=...
 // Requires authentication (user must be logged in)
 func (h *GetTimelineHandler) HandleGetTimeline(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Extract authenticated user DID from context (set by RequireAuth middleware)
 	userDID := middleware.GetUserDID(r)
 	if userDID == "" || !strings.HasPrefix(userDID, "did:") {
-		writeError(w, http.StatusUnauthorized, "AuthenticationRequired", "User must be authenticated to view timeline")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "User must be authenticated to view timeline")
 		return
 	}
 
 	// Parse query parameters
 	req, err := h.parseRequest(r, userDID)
 	if err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
+		return
+	}
+
+	// Response shape version (query param or Accept header)
+	version, err := views.ParseVersion(r)
+	if err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.UnsupportedVersion, err.Error())
 		return
 	}
 
@@ -67,6 +88,15 @@ func (h *GetTimelineHandler) HandleGetTimeline(w http.ResponseWriter, r *http.Re
 	// Populate viewer vote state if authenticated
 	common.PopulateViewerVoteState(r.Context(), r, h.voteService, response.Feed)
 
+	// Null vote counts inside community score hiding windows
+	common.HideFeedScores(r.Context(), r, h.moderators, response.Feed)
+
+	// Badge post authors who moderate their community
+	common.AddFeedAuthorBadges(r.Context(), h.moderators, response.Feed)
+
+	// Attribute aggregator-authored posts to their aggregator
+	common.PopulateAggregatorAttribution(r.Context(), h.aggregators, response.Feed)
+
 	// Transform blob refs to URLs and resolve post embeds for all posts
 	for _, feedPost := range response.Feed {
 		if feedPost.Post != nil {
@@ -76,9 +106,9 @@ func (h *GetTimelineHandler) HandleGetTimeline(w http.ResponseWriter, r *http.Re
 	}
 
 	// Return feed
-	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Type", views.ContentType(version))
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(response); err != nil {
+	if err := json.NewEncoder(w).Encode(views.BuildFeedResponseWithGaps(version, response.Cursor, response.Feed, response.Gaps)); err != nil {
 		// Log encoding errors but don't return error response (headers already sent)
 		log.Printf("ERROR: Failed to encode timeline response: %v", err)
 	}
@@ -90,10 +120,16 @@ func (h *GetTimelineHandler) parseRequest(r *http.Request, userDID string) (time
 		UserDID: userDID, // Set from authenticated context
 	}
 
-	// Optional: sort (default: hot)
+	// Absent sort and includeNsfw fall back to the viewer's preferences
+	var prefs *users.ViewerPreferences
+	if r.URL.Query().Get("sort") == "" || !r.URL.Query().Has("includeNsfw") {
+		prefs = common.LoadViewerPreferences(r.Context(), h.preferences, userDID)
+	}
+
+	// Optional: sort (default: the viewer's defaultFeedSort, hot unless set)
 	req.Sort = r.URL.Query().Get("sort")
 	if req.Sort == "" {
-		req.Sort = "hot"
+		req.Sort = prefs.DefaultFeedSort
 	}
 
 	// Optional: timeframe (default: day for top sort)
@@ -110,6 +146,13 @@ func (h *GetTimelineHandler) parseRequest(r *http.Request, userDID string) (time
 		}
 	}
 
+	// Optional: includeNsfw (default: the viewer's includeNsfw, off unless set)
+	if r.URL.Query().Has("includeNsfw") {
+		req.IncludeNSFW, _ = strconv.ParseBool(r.URL.Query().Get("includeNsfw"))
+	} else {
+		req.IncludeNSFW = prefs.IncludeNSFW
+	}
+
 	// Optional: cursor
 	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
 		req.Cursor = &cursor
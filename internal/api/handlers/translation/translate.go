@@ -0,0 +1,72 @@
+package translation
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+
+	"Coves/internal/api/handlers/common"
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/core/translation"
+)
+
+// TranslateHandler translates posts and comments for signed-in users
+type TranslateHandler struct {
+	service translation.Service
+}
+
+// NewTranslateHandler creates a new translate handler
+func NewTranslateHandler(service translation.Service) *TranslateHandler {
+	return &TranslateHandler{service: service}
+}
+
+// HandleTranslate returns a post or comment's current text in another language
+// GET /xrpc/social.coves.feed.translate?uri=at://...&lang=de
+func (h *TranslateHandler) HandleTranslate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+	if middleware.GetUserDID(r) == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	query := r.URL.Query()
+	req := translation.TranslateRequest{SubjectURI: query.Get("uri"), TargetLang: query.Get("lang")}
+	if req.SubjectURI == "" || req.TargetLang == "" {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "uri and lang are required")
+		return
+	}
+
+	result, err := h.service.Translate(r.Context(), req)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("ERROR: Failed to encode translation response: %v", err)
+	}
+}
+
+// handleServiceError maps translation service errors to HTTP responses
+func handleServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, translation.ErrNotConfigured):
+		xrpcerror.WriteError(w, http.StatusNotImplemented, xrpcerror.TranslationUnavailable, "Translation is not available on this instance")
+	case errors.Is(err, translation.ErrBackendFailed):
+		log.Printf("ERROR: Translation backend error: %v", err)
+		xrpcerror.WriteError(w, http.StatusBadGateway, xrpcerror.TranslationFailed, "The translation service failed, try again later")
+	default:
+		if common.WriteErrorKind(w, err) {
+			return
+		}
+		log.Printf("ERROR: Translation service error: %v", err)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
+	}
+}
@@ -0,0 +1,128 @@
+package translation
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/translation"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCommentURI = "at://did:plc:alice/social.coves.community.comment/3kcomment"
+
+// fakeRepo serves one comment and caches nothing
+type fakeRepo struct{}
+
+func (fakeRepo) GetSubject(_ context.Context, uri string) (*translation.Subject, error) {
+	if uri != testCommentURI {
+		return nil, translation.ErrSubjectNotFound
+	}
+	return &translation.Subject{URI: uri, CID: "bafycomment", Content: "Guten Morgen", Lang: "de"}, nil
+}
+
+func (fakeRepo) GetTranslation(context.Context, string, string, string) (*translation.Translation, error) {
+	return nil, nil
+}
+
+func (fakeRepo) SaveTranslation(context.Context, *translation.Translation) error { return nil }
+
+// newLibreTranslate starts a LibreTranslate-style backend answering with status and body,
+// recording the request body it received
+func newLibreTranslate(t *testing.T, status int, body string, received *map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if received != nil {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(received))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func doTranslate(handler *TranslateHandler, method, userDID string, params url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/xrpc/social.coves.feed.translate?"+params.Encode(), nil)
+	if userDID != "" {
+		req = req.WithContext(middleware.SetTestUserDID(req.Context(), userDID))
+	}
+	w := httptest.NewRecorder()
+	handler.HandleTranslate(w, req)
+	return w
+}
+
+func TestHandleTranslate(t *testing.T) {
+	var received map[string]any
+	backend := newLibreTranslate(t, http.StatusOK, `{"translatedText":["Good morning"]}`, &received)
+	handler := NewTranslateHandler(translation.NewTranslationService(fakeRepo{}, translation.NewHTTPBackend(backend.URL, "secret")))
+
+	w := doTranslate(handler, http.MethodGet, "did:plc:me", url.Values{"uri": {testCommentURI}, "lang": {"en"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var result map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, map[string]any{
+		"uri":        testCommentURI,
+		"cid":        "bafycomment",
+		"sourceLang": "de",
+		"targetLang": "en",
+		"content":    "Good morning",
+	}, result)
+
+	assert.Equal(t, []any{"Guten Morgen"}, received["q"])
+	assert.Equal(t, "de", received["source"])
+	assert.Equal(t, "en", received["target"])
+	assert.Equal(t, "secret", received["api_key"])
+}
+
+func TestHandleTranslate_Errors(t *testing.T) {
+	working := newLibreTranslate(t, http.StatusOK, `{"translatedText":["Good morning"]}`, nil)
+	failing := newLibreTranslate(t, http.StatusInternalServerError, `{"error":"model not loaded"}`, nil)
+	valid := url.Values{"uri": {testCommentURI}, "lang": {"en"}}
+
+	tests := []struct {
+		name       string
+		backendURL string
+		method     string
+		userDID    string
+		params     url.Values
+		wantStatus int
+		wantError  string
+	}{
+		{name: "wrong method", backendURL: working.URL, method: http.MethodPost, userDID: "did:plc:me", params: valid, wantStatus: http.StatusMethodNotAllowed},
+		{name: "unauthenticated", backendURL: working.URL, method: http.MethodGet, params: valid, wantStatus: http.StatusUnauthorized},
+		{name: "missing lang", backendURL: working.URL, method: http.MethodGet, userDID: "did:plc:me", params: url.Values{"uri": {testCommentURI}}, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "invalid language", backendURL: working.URL, method: http.MethodGet, userDID: "did:plc:me", params: url.Values{"uri": {testCommentURI}, "lang": {"??"}}, wantStatus: http.StatusBadRequest, wantError: "InvalidRequest"},
+		{name: "unknown subject", backendURL: working.URL, method: http.MethodGet, userDID: "did:plc:me", params: url.Values{"uri": {"at://did:plc:alice/social.coves.community.comment/3kgone"}, "lang": {"en"}}, wantStatus: http.StatusNotFound, wantError: "NotFound"},
+		{name: "not configured", method: http.MethodGet, userDID: "did:plc:me", params: valid, wantStatus: http.StatusNotImplemented, wantError: "TranslationUnavailable"},
+		{name: "backend failure", backendURL: failing.URL, method: http.MethodGet, userDID: "did:plc:me", params: valid, wantStatus: http.StatusBadGateway, wantError: "TranslationFailed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var backend translation.Backend
+			if tt.backendURL != "" {
+				backend = translation.NewHTTPBackend(tt.backendURL, "")
+			}
+			handler := NewTranslateHandler(translation.NewTranslationService(fakeRepo{}, backend))
+
+			w := doTranslate(handler, tt.method, tt.userDID, tt.params)
+			assert.Equal(t, tt.wantStatus, w.Code, w.Body.String())
+			if tt.wantError != "" {
+				var body struct {
+					Error string `json:"error"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+				assert.Equal(t, tt.wantError, body.Error)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/users"
 )
 
@@ -41,7 +42,7 @@ type DeleteAccountResponse struct {
 func (h *DeleteHandler) HandleDeleteAccount(w http.ResponseWriter, r *http.Request) {
 	// 1. Check HTTP method
 	if r.Method != http.MethodPost {
-		writeJSONError(w, http.StatusMethodNotAllowed, "MethodNotAllowed", "Method not allowed")
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
@@ -49,7 +50,7 @@ func (h *DeleteHandler) HandleDeleteAccount(w http.ResponseWriter, r *http.Reque
 	// SECURITY: This ensures users can ONLY delete their own account
 	userDID := middleware.GetUserDID(r)
 	if userDID == "" {
-		writeJSONError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
@@ -74,7 +75,7 @@ func (h *DeleteHandler) HandleDeleteAccount(w http.ResponseWriter, r *http.Reque
 			slog.String("did", userDID),
 			slog.String("error", err.Error()),
 		)
-		writeJSONError(w, http.StatusInternalServerError, "InternalServerError", "Failed to encode response")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to encode response")
 		return
 	}
 
@@ -88,55 +89,32 @@ func (h *DeleteHandler) HandleDeleteAccount(w http.ResponseWriter, r *http.Reque
 	}
 }
 
-// writeJSONError writes a JSON error response
-// Marshals JSON before writing headers to catch encoding errors
-func writeJSONError(w http.ResponseWriter, statusCode int, errorType, message string) {
-	responseBytes, err := json.Marshal(map[string]interface{}{
-		"error":   errorType,
-		"message": message,
-	})
-	if err != nil {
-		// Fallback to plain text if JSON encoding fails (should never happen with simple strings)
-		slog.Error("failed to marshal error response", slog.String("error", err.Error()))
-		w.Header().Set("Content-Type", "text/plain")
-		w.WriteHeader(statusCode)
-		_, _ = w.Write([]byte(message))
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if _, writeErr := w.Write(responseBytes); writeErr != nil {
-		slog.Warn("failed to write error response", slog.String("error", writeErr.Error()))
-	}
-}
-
 // handleServiceError maps service errors to HTTP responses
 func handleServiceError(w http.ResponseWriter, err error, userDID string) {
 	// Check for specific error types
 	switch {
 	case errors.Is(err, users.ErrUserNotFound):
-		writeJSONError(w, http.StatusNotFound, "AccountNotFound", "Account not found")
+		xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.AccountNotFound, "Account not found")
 
 	case errors.Is(err, context.DeadlineExceeded):
 		slog.Error("account deletion timed out",
 			slog.String("did", userDID),
 			slog.String("error", err.Error()),
 		)
-		writeJSONError(w, http.StatusGatewayTimeout, "Timeout", "Request timed out")
+		xrpcerror.WriteError(w, http.StatusGatewayTimeout, xrpcerror.Timeout, "Request timed out")
 
 	case errors.Is(err, context.Canceled):
 		slog.Info("account deletion canceled",
 			slog.String("did", userDID),
 			slog.String("error", err.Error()),
 		)
-		writeJSONError(w, http.StatusBadRequest, "RequestCanceled", "Request was canceled")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.RequestCanceled, "Request was canceled")
 
 	default:
 		// Check for InvalidDIDError
 		var invalidDIDErr *users.InvalidDIDError
 		if errors.As(err, &invalidDIDErr) {
-			writeJSONError(w, http.StatusBadRequest, "InvalidDID", invalidDIDErr.Error())
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidDID, invalidDIDErr.Error())
 			return
 		}
 
@@ -145,6 +123,6 @@ func handleServiceError(w http.ResponseWriter, err error, userDID string) {
 			slog.String("did", userDID),
 			slog.String("error", err.Error()),
 		)
-		writeJSONError(w, http.StatusInternalServerError, "InternalServerError", "An internal error occurred")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "An internal error occurred")
 	}
 }
@@ -0,0 +1,228 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/users"
+
+	"github.com/bluesky-social/indigo/atproto/auth/oauth"
+)
+
+// maxPreferencesBodySize bounds putPreferences bodies; hidden communities are the largest field
+const maxPreferencesBodySize = 64 * 1024
+
+// PreferencesHandler handles the viewer preferences endpoints
+// GET  /xrpc/social.coves.actor.getPreferences
+// POST /xrpc/social.coves.actor.putPreferences
+// Preferences live in the user's social.coves.actor.preferences record; the local copy is
+// updated optimistically on write and replaced when the Jetstream consumer indexes the record.
+type PreferencesHandler struct {
+	repo             users.PreferencesRepository
+	oauthClient      *oauth.ClientApp
+	pdsClientFactory PDSClientFactory
+}
+
+// NewPreferencesHandler creates a new preferences handler.
+// Panics if oauthClient is nil - use NewPreferencesHandlerWithFactory for testing.
+func NewPreferencesHandler(repo users.PreferencesRepository, oauthClient *oauth.ClientApp) *PreferencesHandler {
+	if oauthClient == nil {
+		panic("NewPreferencesHandler: oauthClient is required")
+	}
+	return &PreferencesHandler{repo: repo, oauthClient: oauthClient}
+}
+
+// NewPreferencesHandlerWithFactory creates a new preferences handler with a custom PDS client factory.
+// Panics if factory is nil.
+func NewPreferencesHandlerWithFactory(repo users.PreferencesRepository, factory PDSClientFactory) *PreferencesHandler {
+	if factory == nil {
+		panic("NewPreferencesHandlerWithFactory: factory is required")
+	}
+	return &PreferencesHandler{repo: repo, pdsClientFactory: factory}
+}
+
+// getPDSClient creates a PDS client from an OAuth session, preferring the custom factory
+func (h *PreferencesHandler) getPDSClient(ctx context.Context, session *oauth.ClientSessionData) (pds.Client, error) {
+	if h.pdsClientFactory != nil {
+		return h.pdsClientFactory(ctx, session)
+	}
+	if h.oauthClient == nil {
+		return nil, fmt.Errorf("OAuth client not configured")
+	}
+	return pds.NewFromOAuthSession(ctx, h.oauthClient, session)
+}
+
+// HandleGet returns the authenticated user's preferences merged over the server defaults
+func (h *PreferencesHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	stored, err := h.repo.Get(r.Context(), userDID)
+	if err != nil && !errors.Is(err, users.ErrPreferencesNotFound) {
+		slog.Error("failed to get preferences",
+			slog.String("did", userDID),
+			slog.String("error", err.Error()),
+		)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to get preferences")
+		return
+	}
+
+	var record json.RawMessage
+	if stored != nil {
+		record = stored.Record
+	}
+	writePreferences(w, userDID, users.MergePreferences(record))
+}
+
+// HandlePut validates the update, writes the merged record to the user's PDS and
+// returns the effective preferences
+func (h *PreferencesHandler) HandlePut(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if r.Method != http.MethodPost {
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userDID := middleware.GetUserDID(r)
+	if userDID == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
+		return
+	}
+
+	session := middleware.GetOAuthSession(r)
+	if session == nil || session.HostURL == "" {
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.MissingSession, "Missing PDS credentials")
+		return
+	}
+
+	// Unknown fields are rejected so typos don't silently do nothing
+	r.Body = http.MaxBytesReader(w, r.Body, maxPreferencesBodySize)
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	var update users.PreferencesUpdate
+	if err := decoder.Decode(&update); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
+		return
+	}
+	if err := update.Validate(); err != nil {
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, err.Error())
+		return
+	}
+
+	// Start from the stored record so keys this AppView doesn't know about survive the write
+	record := map[string]interface{}{}
+	stored, err := h.repo.Get(ctx, userDID)
+	switch {
+	case err == nil:
+		if len(stored.Record) > 0 {
+			decoder := json.NewDecoder(bytes.NewReader(stored.Record))
+			decoder.UseNumber()
+			if err := decoder.Decode(&record); err != nil {
+				slog.Warn("discarding undecodable stored preferences",
+					slog.String("did", userDID),
+					slog.String("error", err.Error()),
+				)
+				record = map[string]interface{}{}
+			}
+		}
+	case !errors.Is(err, users.ErrPreferencesNotFound):
+		slog.Error("failed to get preferences",
+			slog.String("did", userDID),
+			slog.String("error", err.Error()),
+		)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to update preferences")
+		return
+	}
+	update.Apply(record)
+	record["$type"] = users.PreferencesCollection
+	record["updatedAt"] = time.Now().UTC().Format(time.RFC3339)
+
+	pdsClient, err := h.getPDSClient(ctx, session)
+	if err != nil {
+		slog.Error("failed to create PDS client",
+			slog.String("did", userDID),
+			slog.String("error", err.Error()),
+		)
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.SessionError,
+			"Failed to restore session. Please sign in again.")
+		return
+	}
+
+	uri, cid, err := pdsClient.PutRecord(ctx, users.PreferencesCollection, "self", record, "")
+	if err != nil {
+		slog.Error("failed to put preferences record to PDS",
+			slog.String("did", userDID),
+			slog.String("pds_url", session.HostURL),
+			slog.String("error", err.Error()),
+		)
+		switch {
+		case errors.Is(err, pds.ErrUnauthorized), errors.Is(err, pds.ErrForbidden):
+			xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthExpired, "Your session may have expired. Please re-authenticate.")
+		case errors.Is(err, pds.ErrRateLimited):
+			xrpcerror.WriteError(w, http.StatusTooManyRequests, xrpcerror.RateLimitExceeded, "Too many requests. Please try again later.")
+		default:
+			xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.PDSError, "Failed to update preferences")
+		}
+		return
+	}
+
+	// Optimistic local update so the next feed request sees the change before Jetstream
+	// delivers the record; the consumer's upsert replaces it with the same content
+	prefs, err := users.ParsePreferences(userDID, uri, cid, record)
+	if err == nil {
+		err = h.repo.Upsert(ctx, prefs)
+	}
+	if err != nil {
+		slog.Warn("failed to cache preferences locally",
+			slog.String("did", userDID),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to encode response")
+		return
+	}
+	writePreferences(w, userDID, users.MergePreferences(raw))
+}
+
+// writePreferences writes the effective preferences response
+func writePreferences(w http.ResponseWriter, userDID string, prefs *users.ViewerPreferences) {
+	responseBytes, err := json.Marshal(prefs)
+	if err != nil {
+		slog.Error("failed to marshal preferences response",
+			slog.String("did", userDID),
+			slog.String("error", err.Error()),
+		)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to encode response")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, writeErr := w.Write(responseBytes); writeErr != nil {
+		slog.Warn("failed to write preferences response",
+			slog.String("did", userDID),
+			slog.String("error", writeErr.Error()),
+		)
+	}
+}
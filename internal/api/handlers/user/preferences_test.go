@@ -0,0 +1,172 @@
+package user
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/users"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockPreferencesRepo keeps preferences in memory
+type mockPreferencesRepo struct {
+	prefs map[string]*users.Preferences
+}
+
+func newMockPreferencesRepo() *mockPreferencesRepo {
+	return &mockPreferencesRepo{prefs: make(map[string]*users.Preferences)}
+}
+
+func (m *mockPreferencesRepo) Upsert(_ context.Context, prefs *users.Preferences) error {
+	m.prefs[prefs.UserDID] = prefs
+	return nil
+}
+
+func (m *mockPreferencesRepo) Get(_ context.Context, userDID string) (*users.Preferences, error) {
+	prefs, ok := m.prefs[userDID]
+	if !ok {
+		return nil, users.ErrPreferencesNotFound
+	}
+	return prefs, nil
+}
+
+func (m *mockPreferencesRepo) Delete(_ context.Context, userDID string) error {
+	delete(m.prefs, userDID)
+	return nil
+}
+
+func preferencesRequest(t *testing.T, method, body, userDID string) *http.Request {
+	t.Helper()
+	req := httptest.NewRequest(method, "/xrpc/social.coves.actor.putPreferences", bytes.NewBufferString(body))
+	if userDID == "" {
+		return req
+	}
+	return setTestOAuthSession(req, userDID, createTestOAuthSession(userDID))
+}
+
+func decodePreferences(t *testing.T, w *httptest.ResponseRecorder) *users.ViewerPreferences {
+	t.Helper()
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var prefs users.ViewerPreferences
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &prefs))
+	return &prefs
+}
+
+func TestPreferencesHandler_GetReturnsDefaultsWithoutRecord(t *testing.T) {
+	handler := NewPreferencesHandlerWithFactory(newMockPreferencesRepo(), createMockFactory(&mockPDSClient{}, nil))
+
+	w := httptest.NewRecorder()
+	handler.HandleGet(w, preferencesRequest(t, http.MethodGet, "", "did:plc:test123"))
+
+	assert.Equal(t, users.DefaultViewerPreferences(), decodePreferences(t, w))
+}
+
+func TestPreferencesHandler_GetMergesStoredValues(t *testing.T) {
+	repo := newMockPreferencesRepo()
+	repo.prefs["did:plc:test123"] = &users.Preferences{
+		UserDID: "did:plc:test123",
+		Record:  json.RawMessage(`{"defaultFeedSort":"new","hiddenCommunities":["did:plc:noisy"]}`),
+	}
+	handler := NewPreferencesHandlerWithFactory(repo, createMockFactory(&mockPDSClient{}, nil))
+
+	w := httptest.NewRecorder()
+	handler.HandleGet(w, preferencesRequest(t, http.MethodGet, "", "did:plc:test123"))
+
+	prefs := decodePreferences(t, w)
+	assert.Equal(t, "new", prefs.DefaultFeedSort)
+	assert.Equal(t, []string{"did:plc:noisy"}, prefs.HiddenCommunities)
+	assert.False(t, prefs.IncludeNSFW)
+}
+
+func TestPreferencesHandler_PutWritesRecordAndCachesLocally(t *testing.T) {
+	repo := newMockPreferencesRepo()
+	repo.prefs["did:plc:test123"] = &users.Preferences{
+		UserDID: "did:plc:test123",
+		Record:  json.RawMessage(`{"$type":"social.coves.actor.preferences","hideAggregatorPosts":true,"somethingNew":7}`),
+	}
+	client := &mockPDSClient{
+		putRecordURI: "at://did:plc:test123/social.coves.actor.preferences/self",
+		putRecordCID: "bafyprefs",
+	}
+	handler := NewPreferencesHandlerWithFactory(repo, createMockFactory(client, nil))
+
+	w := httptest.NewRecorder()
+	handler.HandlePut(w, preferencesRequest(t, http.MethodPost,
+		`{"defaultFeedSort":"top","includeNsfw":true,"languages":["en"]}`, "did:plc:test123"))
+
+	prefs := decodePreferences(t, w)
+	assert.Equal(t, "top", prefs.DefaultFeedSort)
+	assert.True(t, prefs.IncludeNSFW)
+	assert.True(t, prefs.HideAggregatorPosts, "stored values the update leaves out are kept")
+	assert.Equal(t, []string{"en"}, prefs.Languages)
+
+	record, ok := client.putRecordValue.(map[string]interface{})
+	require.True(t, ok, "expected a record written to the PDS")
+	assert.Equal(t, users.PreferencesCollection, record["$type"])
+	assert.Equal(t, json.Number("7"), record["somethingNew"], "unknown keys survive the write")
+	assert.NotEmpty(t, record["updatedAt"])
+
+	cached := repo.prefs["did:plc:test123"]
+	assert.Equal(t, "bafyprefs", cached.CID)
+	assert.True(t, cached.HideAggregatorPosts)
+	assert.Equal(t, "top", users.MergePreferences(cached.Record).DefaultFeedSort)
+}
+
+func TestPreferencesHandler_PutRejectsInvalidInput(t *testing.T) {
+	tests := map[string]string{
+		"unknown sort":       `{"defaultFeedSort":"controversial"}`,
+		"unknown field":      `{"defaultFeedSrot":"new"}`,
+		"bad language":       `{"languages":["not a language"]}`,
+		"non-DID community":  `{"hiddenCommunities":["c-news.coves.social"]}`,
+		"wrongly typed bool": `{"hideSeen":"yes"}`,
+	}
+	for name, body := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &mockPDSClient{}
+			handler := NewPreferencesHandlerWithFactory(newMockPreferencesRepo(), createMockFactory(client, nil))
+
+			w := httptest.NewRecorder()
+			handler.HandlePut(w, preferencesRequest(t, http.MethodPost, body, "did:plc:test123"))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Nil(t, client.putRecordValue, "invalid preferences must not reach the PDS")
+		})
+	}
+}
+
+func TestPreferencesHandler_PutMapsPDSErrors(t *testing.T) {
+	repo := newMockPreferencesRepo()
+	client := &mockPDSClient{putRecordError: pds.ErrUnauthorized}
+	handler := NewPreferencesHandlerWithFactory(repo, createMockFactory(client, nil))
+
+	w := httptest.NewRecorder()
+	handler.HandlePut(w, preferencesRequest(t, http.MethodPost, `{"hideSeen":true}`, "did:plc:test123"))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Contains(t, w.Body.String(), "AuthExpired")
+	assert.Empty(t, repo.prefs, "failed writes must not update the local cache")
+}
+
+func TestPreferencesHandler_RequiresAuth(t *testing.T) {
+	handler := NewPreferencesHandlerWithFactory(newMockPreferencesRepo(), createMockFactory(nil, errors.New("unused")))
+
+	for _, tc := range []struct {
+		serve  http.HandlerFunc
+		method string
+	}{
+		{handler.HandleGet, http.MethodGet},
+		{handler.HandlePut, http.MethodPost},
+	} {
+		w := httptest.NewRecorder()
+		tc.serve(w, preferencesRequest(t, tc.method, `{"hideSeen":true}`, ""))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	}
+}
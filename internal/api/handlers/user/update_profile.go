@@ -9,6 +9,7 @@ import (
 	"net/http"
 
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/atproto/pds"
 
 	"github.com/bluesky-social/indigo/atproto/auth/oauth"
@@ -106,26 +107,26 @@ func (h *UpdateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 
 	// Check HTTP method
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// 1. Get authenticated user from context
 	userDID := middleware.GetUserDID(r)
 	if userDID == "" {
-		writeUpdateProfileError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
 	// Get OAuth session for PDS URL and access token
 	session := middleware.GetOAuthSession(r)
 	if session == nil {
-		writeUpdateProfileError(w, http.StatusUnauthorized, "MissingSession", "Missing PDS credentials")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.MissingSession, "Missing PDS credentials")
 		return
 	}
 
 	if session.HostURL == "" {
-		writeUpdateProfileError(w, http.StatusUnauthorized, "MissingCredentials", "Missing PDS credentials")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.MissingCredentials, "Missing PDS credentials")
 		return
 	}
 
@@ -133,20 +134,20 @@ func (h *UpdateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodySize)
 	var req UpdateProfileRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		writeUpdateProfileError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	// Validate displayName length
 	if req.DisplayName != nil && len(*req.DisplayName) > MaxDisplayNameLength {
-		writeUpdateProfileError(w, http.StatusBadRequest, "DisplayNameTooLong",
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.DisplayNameTooLong,
 			fmt.Sprintf("Display name exceeds %d character limit", MaxDisplayNameLength))
 		return
 	}
 
 	// Validate bio length
 	if req.Bio != nil && len(*req.Bio) > MaxBioLength {
-		writeUpdateProfileError(w, http.StatusBadRequest, "BioTooLong",
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.BioTooLong,
 			fmt.Sprintf("Bio exceeds %d character limit", MaxBioLength))
 		return
 	}
@@ -155,16 +156,16 @@ func (h *UpdateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	if len(req.AvatarBlob) > 0 {
 		// Validate mime type is provided when blob is provided
 		if req.AvatarMimeType == "" {
-			writeUpdateProfileError(w, http.StatusBadRequest, "InvalidRequest", "Avatar blob provided without mime type")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Avatar blob provided without mime type")
 			return
 		}
 		// Validate size (1MB max for avatar per lexicon)
 		if len(req.AvatarBlob) > MaxAvatarBlobSize {
-			writeUpdateProfileError(w, http.StatusBadRequest, "AvatarTooLarge", "Avatar exceeds 1MB limit")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.AvatarTooLarge, "Avatar exceeds 1MB limit")
 			return
 		}
 		if !isValidImageMimeType(req.AvatarMimeType) {
-			writeUpdateProfileError(w, http.StatusBadRequest, "InvalidMimeType", "Invalid avatar mime type")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidMimeType, "Invalid avatar mime type")
 			return
 		}
 	}
@@ -172,16 +173,16 @@ func (h *UpdateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	if len(req.BannerBlob) > 0 {
 		// Validate mime type is provided when blob is provided
 		if req.BannerMimeType == "" {
-			writeUpdateProfileError(w, http.StatusBadRequest, "InvalidRequest", "Banner blob provided without mime type")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Banner blob provided without mime type")
 			return
 		}
 		// Validate size (2MB max for banner per lexicon)
 		if len(req.BannerBlob) > MaxBannerBlobSize {
-			writeUpdateProfileError(w, http.StatusBadRequest, "BannerTooLarge", "Banner exceeds 2MB limit")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.BannerTooLarge, "Banner exceeds 2MB limit")
 			return
 		}
 		if !isValidImageMimeType(req.BannerMimeType) {
-			writeUpdateProfileError(w, http.StatusBadRequest, "InvalidMimeType", "Invalid banner mime type")
+			xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidMimeType, "Invalid banner mime type")
 			return
 		}
 	}
@@ -193,7 +194,7 @@ func (h *UpdateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 			slog.String("did", userDID),
 			slog.String("error", err.Error()),
 		)
-		writeUpdateProfileError(w, http.StatusUnauthorized, "SessionError",
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.SessionError,
 			"Failed to restore session. Please sign in again.")
 		return
 	}
@@ -224,19 +225,19 @@ func (h *UpdateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 			// Map specific PDS errors to user-friendly messages
 			switch {
 			case errors.Is(err, pds.ErrUnauthorized), errors.Is(err, pds.ErrForbidden):
-				writeUpdateProfileError(w, http.StatusUnauthorized, "AuthExpired", "Your session may have expired. Please re-authenticate.")
+				xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthExpired, "Your session may have expired. Please re-authenticate.")
 			case errors.Is(err, pds.ErrRateLimited):
-				writeUpdateProfileError(w, http.StatusTooManyRequests, "RateLimited", "Too many requests. Please try again later.")
+				xrpcerror.WriteError(w, http.StatusTooManyRequests, xrpcerror.RateLimitExceeded, "Too many requests. Please try again later.")
 			case errors.Is(err, pds.ErrPayloadTooLarge):
-				writeUpdateProfileError(w, http.StatusRequestEntityTooLarge, "AvatarTooLarge", "Avatar exceeds PDS size limit.")
+				xrpcerror.WriteError(w, http.StatusRequestEntityTooLarge, xrpcerror.AvatarTooLarge, "Avatar exceeds PDS size limit.")
 			default:
-				writeUpdateProfileError(w, http.StatusInternalServerError, "BlobUploadFailed", "Failed to upload avatar")
+				xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.BlobUploadFailed, "Failed to upload avatar")
 			}
 			return
 		}
 		if avatarRef == nil || avatarRef.Ref == nil || avatarRef.Type == "" {
 			slog.Error("invalid blob reference returned from avatar upload", slog.String("did", userDID))
-			writeUpdateProfileError(w, http.StatusInternalServerError, "BlobUploadFailed", "Invalid avatar blob reference")
+			xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.BlobUploadFailed, "Invalid avatar blob reference")
 			return
 		}
 		profile["avatar"] = map[string]interface{}{
@@ -258,19 +259,19 @@ func (h *UpdateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 			// Map specific PDS errors to user-friendly messages
 			switch {
 			case errors.Is(err, pds.ErrUnauthorized), errors.Is(err, pds.ErrForbidden):
-				writeUpdateProfileError(w, http.StatusUnauthorized, "AuthExpired", "Your session may have expired. Please re-authenticate.")
+				xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthExpired, "Your session may have expired. Please re-authenticate.")
 			case errors.Is(err, pds.ErrRateLimited):
-				writeUpdateProfileError(w, http.StatusTooManyRequests, "RateLimited", "Too many requests. Please try again later.")
+				xrpcerror.WriteError(w, http.StatusTooManyRequests, xrpcerror.RateLimitExceeded, "Too many requests. Please try again later.")
 			case errors.Is(err, pds.ErrPayloadTooLarge):
-				writeUpdateProfileError(w, http.StatusRequestEntityTooLarge, "BannerTooLarge", "Banner exceeds PDS size limit.")
+				xrpcerror.WriteError(w, http.StatusRequestEntityTooLarge, xrpcerror.BannerTooLarge, "Banner exceeds PDS size limit.")
 			default:
-				writeUpdateProfileError(w, http.StatusInternalServerError, "BlobUploadFailed", "Failed to upload banner")
+				xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.BlobUploadFailed, "Failed to upload banner")
 			}
 			return
 		}
 		if bannerRef == nil || bannerRef.Ref == nil || bannerRef.Type == "" {
 			slog.Error("invalid blob reference returned from banner upload", slog.String("did", userDID))
-			writeUpdateProfileError(w, http.StatusInternalServerError, "BlobUploadFailed", "Invalid banner blob reference")
+			xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.BlobUploadFailed, "Invalid banner blob reference")
 			return
 		}
 		profile["banner"] = map[string]interface{}{
@@ -292,13 +293,13 @@ func (h *UpdateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		// Map PDS errors to user-friendly messages
 		switch {
 		case errors.Is(err, pds.ErrUnauthorized), errors.Is(err, pds.ErrForbidden):
-			writeUpdateProfileError(w, http.StatusUnauthorized, "AuthExpired", "Your session may have expired. Please re-authenticate.")
+			xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthExpired, "Your session may have expired. Please re-authenticate.")
 		case errors.Is(err, pds.ErrRateLimited):
-			writeUpdateProfileError(w, http.StatusTooManyRequests, "RateLimited", "Too many requests. Please try again later.")
+			xrpcerror.WriteError(w, http.StatusTooManyRequests, xrpcerror.RateLimitExceeded, "Too many requests. Please try again later.")
 		case errors.Is(err, pds.ErrPayloadTooLarge):
-			writeUpdateProfileError(w, http.StatusRequestEntityTooLarge, "PayloadTooLarge", "Profile data exceeds PDS size limit.")
+			xrpcerror.WriteError(w, http.StatusRequestEntityTooLarge, xrpcerror.RequestTooLarge, "Profile data exceeds PDS size limit.")
 		default:
-			writeUpdateProfileError(w, http.StatusInternalServerError, "PDSError", "Failed to update profile")
+			xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.PDSError, "Failed to update profile")
 		}
 		return
 	}
@@ -313,7 +314,7 @@ func (h *UpdateProfileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 			slog.String("did", userDID),
 			slog.String("error", err.Error()),
 		)
-		writeUpdateProfileError(w, http.StatusInternalServerError, "InternalError", "Failed to encode response")
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "Failed to encode response")
 		return
 	}
 
@@ -336,25 +337,3 @@ func isValidImageMimeType(mimeType string) bool {
 		return false
 	}
 }
-
-// writeUpdateProfileError writes a JSON error response for update profile failures
-func writeUpdateProfileError(w http.ResponseWriter, statusCode int, errorType, message string) {
-	responseBytes, err := json.Marshal(map[string]interface{}{
-		"error":   errorType,
-		"message": message,
-	})
-	if err != nil {
-		// Fallback to plain text if JSON encoding fails
-		slog.Error("failed to marshal error response", slog.String("error", err.Error()))
-		w.Header().Set("Content-Type", "text/plain")
-		w.WriteHeader(statusCode)
-		_, _ = w.Write([]byte(message))
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if _, writeErr := w.Write(responseBytes); writeErr != nil {
-		slog.Warn("failed to write error response", slog.String("error", writeErr.Error()))
-	}
-}
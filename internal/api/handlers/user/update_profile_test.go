@@ -26,6 +26,7 @@ type mockPDSClient struct {
 	putRecordError  error
 	putRecordURI    string
 	putRecordCID    string
+	putRecordValue  any // Last record written by PutRecord
 }
 
 func (m *mockPDSClient) CreateRecord(_ context.Context, _ string, _ string, _ any) (string, string, error) {
@@ -44,13 +45,18 @@ func (m *mockPDSClient) GetRecord(_ context.Context, _ string, _ string) (*pds.R
 	return nil, nil
 }
 
-func (m *mockPDSClient) PutRecord(_ context.Context, _ string, _ string, _ any, _ string) (string, string, error) {
+func (m *mockPDSClient) PutRecord(_ context.Context, _ string, _ string, record any, _ string) (string, string, error) {
 	if m.putRecordError != nil {
 		return "", "", m.putRecordError
 	}
+	m.putRecordValue = record
 	return m.putRecordURI, m.putRecordCID, nil
 }
 
+func (m *mockPDSClient) ApplyWrites(_ context.Context, _ string, _ []pds.WriteOp) ([]pds.WriteResult, error) {
+	return nil, nil
+}
+
 func (m *mockPDSClient) UploadBlob(_ context.Context, _ []byte, _ string) (*blobs.BlobRef, error) {
 	if m.uploadBlobError != nil {
 		return nil, m.uploadBlobError
@@ -513,7 +519,7 @@ func TestUpdateProfileHandler_AvatarUploadRateLimited(t *testing.T) {
 	handler.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusTooManyRequests, w.Code)
-	assert.Contains(t, w.Body.String(), "RateLimited")
+	assert.Contains(t, w.Body.String(), "RateLimitExceeded")
 }
 
 // TestUpdateProfileHandler_AvatarUploadPayloadTooLarge tests avatar upload payload size error
@@ -638,7 +644,7 @@ func TestUpdateProfileHandler_PutRecordRateLimited(t *testing.T) {
 	handler.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusTooManyRequests, w.Code)
-	assert.Contains(t, w.Body.String(), "RateLimited")
+	assert.Contains(t, w.Body.String(), "RateLimitExceeded")
 }
 
 // TestUpdateProfileHandler_PutRecordPayloadTooLarge tests PutRecord payload size error
@@ -664,7 +670,7 @@ func TestUpdateProfileHandler_PutRecordPayloadTooLarge(t *testing.T) {
 	handler.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
-	assert.Contains(t, w.Body.String(), "PayloadTooLarge")
+	assert.Contains(t, w.Body.String(), "RequestTooLarge")
 }
 
 // TestUpdateProfileHandler_PutRecordForbidden tests PutRecord forbidden error
@@ -1049,6 +1055,10 @@ func (m *mockPDSClientWithNilBannerRef) PutRecord(_ context.Context, _ string, _
 	return "", "", nil
 }
 
+func (m *mockPDSClientWithNilBannerRef) ApplyWrites(_ context.Context, _ string, _ []pds.WriteOp) ([]pds.WriteResult, error) {
+	return nil, nil
+}
+
 func (m *mockPDSClientWithNilBannerRef) UploadBlob(_ context.Context, _ []byte, _ string) (*blobs.BlobRef, error) {
 	// Return nil to simulate invalid response
 	return nil, nil
@@ -1181,7 +1191,7 @@ func TestUpdateProfileHandler_BannerUploadRateLimited(t *testing.T) {
 	handler.ServeHTTP(w, req)
 
 	assert.Equal(t, http.StatusTooManyRequests, w.Code)
-	assert.Contains(t, w.Body.String(), "RateLimited")
+	assert.Contains(t, w.Body.String(), "RateLimitExceeded")
 }
 
 // TestUpdateProfileHandler_BannerUploadPayloadTooLarge tests banner upload payload size error
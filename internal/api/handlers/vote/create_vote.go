@@ -2,6 +2,7 @@ package vote
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/votes"
 	"encoding/json"
 	"log"
@@ -47,41 +48,41 @@ type CreateVoteOutput struct {
 // - If vote exists with different direction: updates to new direction
 func (h *CreateVoteHandler) HandleCreateVote(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		xrpcerror.WriteError(w, http.StatusMethodNotAllowed, xrpcerror.MethodNotAllowed, "Method not allowed")
 		return
 	}
 
 	// Parse request body
 	var input CreateVoteInput
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "Invalid request body")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "Invalid request body")
 		return
 	}
 
 	// Validate required fields
 	if input.Subject.URI == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "subject.uri is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "subject.uri is required")
 		return
 	}
 	if input.Subject.CID == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "subject.cid is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "subject.cid is required")
 		return
 	}
 	if input.Direction == "" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "direction is required")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "direction is required")
 		return
 	}
 
 	// Validate direction
 	if input.Direction != "up" && input.Direction != "down" {
-		writeError(w, http.StatusBadRequest, "InvalidRequest", "direction must be 'up' or 'down'")
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "direction must be 'up' or 'down'")
 		return
 	}
 
 	// Get OAuth session from context (injected by auth middleware)
 	session := middleware.GetOAuthSession(r)
 	if session == nil {
-		writeError(w, http.StatusUnauthorized, "AuthRequired", "Authentication required")
+		xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, "Authentication required")
 		return
 	}
 
@@ -2,6 +2,7 @@ package vote
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/votes"
 	"bytes"
 	"context"
@@ -140,7 +141,7 @@ func TestCreateVoteHandler_RequiresAuth(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -204,7 +205,7 @@ func TestCreateVoteHandler_InvalidDirection(t *testing.T) {
 			}
 
 			// Check error response
-			var errResp XRPCError
+			var errResp xrpcerror.Response
 			if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 				t.Fatalf("Failed to decode error response: %v", err)
 			}
@@ -290,7 +291,7 @@ func TestCreateVoteHandler_MissingFields(t *testing.T) {
 			}
 
 			// Check error response
-			var errResp XRPCError
+			var errResp xrpcerror.Response
 			if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 				t.Fatalf("Failed to decode error response: %v", err)
 			}
@@ -328,7 +329,7 @@ func TestCreateVoteHandler_InvalidJSON(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -435,7 +436,7 @@ func TestCreateVoteHandler_ServiceError(t *testing.T) {
 			}
 
 			// Check error response
-			var errResp XRPCError
+			var errResp xrpcerror.Response
 			if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 				t.Fatalf("Failed to decode error response: %v", err)
 			}
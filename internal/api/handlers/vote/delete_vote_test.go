@@ -2,6 +2,7 @@ package vote
 
 import (
 	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/votes"
 	"bytes"
 	"context"
@@ -100,7 +101,7 @@ func TestDeleteVoteHandler_RequiresAuth(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -173,7 +174,7 @@ func TestDeleteVoteHandler_MissingFields(t *testing.T) {
 			}
 
 			// Check error response
-			var errResp XRPCError
+			var errResp xrpcerror.Response
 			if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 				t.Fatalf("Failed to decode error response: %v", err)
 			}
@@ -211,7 +212,7 @@ func TestDeleteVoteHandler_InvalidJSON(t *testing.T) {
 	}
 
 	// Check error response
-	var errResp XRPCError
+	var errResp xrpcerror.Response
 	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 		t.Fatalf("Failed to decode error response: %v", err)
 	}
@@ -317,7 +318,7 @@ func TestDeleteVoteHandler_ServiceError(t *testing.T) {
 			}
 
 			// Check error response
-			var errResp XRPCError
+			var errResp xrpcerror.Response
 			if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
 				t.Fatalf("Failed to decode error response: %v", err)
 			}
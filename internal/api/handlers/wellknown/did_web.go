@@ -0,0 +1,108 @@
+package wellknown
+
+import (
+	"Coves/internal/core/communities"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// didDocument is a W3C DID document in the shape atproto expects
+// Spec: https://atproto.com/specs/did
+type didDocument struct {
+	Context            []string             `json:"@context"`
+	ID                 string               `json:"id"`
+	AlsoKnownAs        []string             `json:"alsoKnownAs"`
+	VerificationMethod []verificationMethod `json:"verificationMethod"`
+	Service            []didService         `json:"service"`
+}
+
+type verificationMethod struct {
+	ID                 string `json:"id"`
+	Type               string `json:"type"`
+	Controller         string `json:"controller"`
+	PublicKeyMultibase string `json:"publicKeyMultibase"`
+}
+
+type didService struct {
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
+}
+
+// DIDDocumentHandler serves DID documents for this instance's did:web communities
+type DIDDocumentHandler struct {
+	docs communities.DIDWebRepository
+}
+
+// NewDIDDocumentHandler creates a handler serving did:web documents from docs
+func NewDIDDocumentHandler(docs communities.DIDWebRepository) *DIDDocumentHandler {
+	return &DIDDocumentHandler{docs: docs}
+}
+
+// HandleDIDDocument serves the DID document for the community whose did:web host is the request host
+// GET /.well-known/did.json
+//
+// A did:web DID resolves to https://{host}/.well-known/did.json, so the requested DID is
+// derived from the Host header (did:web percent-encodes a port separator as %3A).
+//
+// Spec: https://w3c-ccg.github.io/did-method-web/
+func (h *DIDDocumentHandler) HandleDIDDocument(w http.ResponseWriter, r *http.Request) {
+	host := strings.ToLower(r.Host)
+	if host == "" {
+		http.Error(w, "not found", http.StatusNotFound)
+		return
+	}
+	did := "did:web:" + strings.ReplaceAll(host, ":", "%3A")
+
+	doc, err := h.docs.GetByDID(r.Context(), did)
+	if err != nil {
+		if errors.Is(err, communities.ErrDIDWebDocumentNotFound) {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		slog.Error("failed to load did:web document", "did", did, "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	body := didDocument{
+		Context: []string{
+			"https://www.w3.org/ns/did/v1",
+			"https://w3id.org/security/multikey/v1",
+			"https://w3id.org/security/suites/secp256k1-2019/v1",
+		},
+		ID:          doc.DID,
+		AlsoKnownAs: []string{"at://" + doc.Handle},
+		VerificationMethod: []verificationMethod{
+			{
+				ID:                 doc.DID + "#atproto",
+				Type:               "Multikey",
+				Controller:         doc.DID,
+				PublicKeyMultibase: strings.TrimPrefix(doc.SigningKey, "did:key:"),
+			},
+		},
+		Service: []didService{
+			{
+				ID:              "#atproto_pds",
+				Type:            "AtprotoPersonalDataServer",
+				ServiceEndpoint: doc.PDSURL,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(body); err != nil {
+		slog.Error("failed to encode did:web document", "did", did, "error", err)
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		slog.Warn("failed to write did:web document", "did", did, "error", err)
+	}
+}
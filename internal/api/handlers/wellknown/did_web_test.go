@@ -0,0 +1,110 @@
+package wellknown
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// fakeDIDWebRepo serves documents from a map keyed by DID
+type fakeDIDWebRepo struct {
+	docs map[string]*communities.DIDWebDocument
+}
+
+func (f *fakeDIDWebRepo) Create(ctx context.Context, doc *communities.DIDWebDocument) error {
+	f.docs[doc.DID] = doc
+	return nil
+}
+
+func (f *fakeDIDWebRepo) GetByDID(ctx context.Context, did string) (*communities.DIDWebDocument, error) {
+	if doc, ok := f.docs[did]; ok {
+		return doc, nil
+	}
+	return nil, communities.ErrDIDWebDocumentNotFound
+}
+
+func (f *fakeDIDWebRepo) GetByHandle(ctx context.Context, handle string) (*communities.DIDWebDocument, error) {
+	for _, doc := range f.docs {
+		if doc.Handle == handle {
+			return doc, nil
+		}
+	}
+	return nil, communities.ErrDIDWebDocumentNotFound
+}
+
+func (f *fakeDIDWebRepo) Delete(ctx context.Context, did string) error {
+	delete(f.docs, did)
+	return nil
+}
+
+func TestHandleDIDDocument(t *testing.T) {
+	repo := &fakeDIDWebRepo{docs: map[string]*communities.DIDWebDocument{
+		"did:web:gaming.communities.coves.social": {
+			DID:        "did:web:gaming.communities.coves.social",
+			Handle:     "c-gaming.coves.social",
+			SigningKey: "did:key:zQ3shXjHeiBuRCKmM36cuYnm7YEMzhGnCmCyW92sRJ9pribSF",
+			PDSURL:     "https://pds.coves.social",
+		},
+		"did:web:local.test%3A8080": {
+			DID:        "did:web:local.test%3A8080",
+			Handle:     "c-local.local.test",
+			SigningKey: "did:key:zQ3shXjHeiBuRCKmM36cuYnm7YEMzhGnCmCyW92sRJ9pribSF",
+			PDSURL:     "http://localhost:3001",
+		},
+	}}
+	handler := NewDIDDocumentHandler(repo)
+
+	t.Run("serves the document for the request host", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/.well-known/did.json", nil)
+		req.Host = "Gaming.Communities.Coves.Social"
+		w := httptest.NewRecorder()
+		handler.HandleDIDDocument(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var doc didDocument
+		if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+			t.Fatalf("Failed to decode DID document: %v", err)
+		}
+		if doc.ID != "did:web:gaming.communities.coves.social" {
+			t.Errorf("Expected id to be the community DID, got %s", doc.ID)
+		}
+		if len(doc.AlsoKnownAs) != 1 || doc.AlsoKnownAs[0] != "at://c-gaming.coves.social" {
+			t.Errorf("Expected handle in alsoKnownAs, got %v", doc.AlsoKnownAs)
+		}
+		if len(doc.VerificationMethod) != 1 || doc.VerificationMethod[0].ID != doc.ID+"#atproto" ||
+			doc.VerificationMethod[0].PublicKeyMultibase != "zQ3shXjHeiBuRCKmM36cuYnm7YEMzhGnCmCyW92sRJ9pribSF" {
+			t.Errorf("Unexpected verification method: %+v", doc.VerificationMethod)
+		}
+		if len(doc.Service) != 1 || doc.Service[0].ServiceEndpoint != "https://pds.coves.social" {
+			t.Errorf("Unexpected services: %+v", doc.Service)
+		}
+	})
+
+	t.Run("port is percent-encoded", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/.well-known/did.json", nil)
+		req.Host = "local.test:8080"
+		w := httptest.NewRecorder()
+		handler.HandleDIDDocument(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d", w.Code)
+		}
+	})
+
+	t.Run("unknown host is not found", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/.well-known/did.json", nil)
+		req.Host = "other.communities.coves.social"
+		w := httptest.NewRecorder()
+		handler.HandleDIDDocument(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", w.Code)
+		}
+	})
+}
@@ -135,6 +135,22 @@ func (m *mockAPIKeyServiceRepository) IsAggregator(ctx context.Context, did stri
 	return false, nil
 }
 
+func (m *mockAPIKeyServiceRepository) ListServices(ctx context.Context, limit, offset int) ([]*aggregators.Aggregator, error) {
+	return nil, nil
+}
+
+func (m *mockAPIKeyServiceRepository) GetServicesByDIDs(ctx context.Context, dids []string) ([]*aggregators.Aggregator, error) {
+	return nil, nil
+}
+
+func (m *mockAPIKeyServiceRepository) GetService(ctx context.Context, did string) (*aggregators.Aggregator, error) {
+	return nil, aggregators.ErrAggregatorNotFound
+}
+
+func (m *mockAPIKeyServiceRepository) ListAuthorizedCommunities(ctx context.Context, aggregatorDID string, limit int) ([]*aggregators.AuthorizedCommunity, error) {
+	return nil, nil
+}
+
 func (m *mockAPIKeyServiceRepository) CreateAuthorization(ctx context.Context, auth *aggregators.Authorization) error {
 	return nil
 }
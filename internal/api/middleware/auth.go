@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"Coves/internal/api/xrpcerror"
+	"Coves/internal/atproto/identity"
 	"Coves/internal/atproto/oauth"
 	"context"
-	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -340,15 +342,7 @@ func extractBearerToken(authHeader string) (string, bool) {
 
 // writeAuthError writes a JSON error response for authentication failures
 func writeAuthError(w http.ResponseWriter, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusUnauthorized)
-	// Use json.NewEncoder to properly escape the message and prevent injection
-	if err := json.NewEncoder(w).Encode(map[string]string{
-		"error":   "AuthenticationRequired",
-		"message": message,
-	}); err != nil {
-		log.Printf("Failed to write auth error response: %v", err)
-	}
+	xrpcerror.WriteError(w, http.StatusUnauthorized, xrpcerror.AuthRequired, message)
 }
 
 // DualAuthMiddleware enforces authentication using either OAuth sealed tokens (for users),
@@ -453,6 +447,13 @@ func (m *DualAuthMiddleware) handleServiceAuth(w http.ResponseWriter, r *http.Re
 	// The ServiceAuthValidator skips the lexicon method check when lexMethod is nil.
 	// This is intentional - we want aggregators to authenticate globally, not per-endpoint.
 	did, err := m.serviceValidator.Validate(r.Context(), token, nil)
+	if errors.Is(err, identity.ErrKeyFetchFailed) {
+		// The issuer's key couldn't be fetched; the token may well be valid, so ask for a retry
+		log.Printf("[AUTH_FAILURE] type=service_jwt_key_unavailable ip=%s method=%s path=%s error=%v",
+			r.RemoteAddr, r.Method, r.URL.Path, err)
+		xrpcerror.WriteError(w, http.StatusServiceUnavailable, xrpcerror.DIDResolutionFailed, "Could not fetch the issuer's signing key, try again later")
+		return
+	}
 	if err != nil {
 		log.Printf("[AUTH_FAILURE] type=service_jwt_invalid ip=%s method=%s path=%s error=%v",
 			r.RemoteAddr, r.Method, r.URL.Path, err)
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"Coves/internal/atproto/identity"
 	"Coves/internal/atproto/oauth"
 	"context"
 	"encoding/base64"
@@ -878,8 +879,8 @@ func TestWriteAuthError_JSONEscaping(t *testing.T) {
 			}
 
 			// Verify fields
-			if response["error"] != "AuthenticationRequired" {
-				t.Errorf("expected error 'AuthenticationRequired', got %s", response["error"])
+			if response["error"] != "AuthRequired" {
+				t.Errorf("expected error 'AuthRequired', got %s", response["error"])
 			}
 			if response["message"] != tt.message {
 				t.Errorf("expected message %q, got %q", tt.message, response["message"])
@@ -890,11 +891,15 @@ func TestWriteAuthError_JSONEscaping(t *testing.T) {
 
 // Mock ServiceAuthValidator for testing
 type mockServiceAuthValidator struct {
-	shouldFail bool
+	err        error // returned as is when set
 	returnDID  syntax.DID
+	shouldFail bool
 }
 
 func (m *mockServiceAuthValidator) Validate(ctx context.Context, tokenString string, lexMethod *syntax.NSID) (syntax.DID, error) {
+	if m.err != nil {
+		return "", m.err
+	}
 	if m.shouldFail {
 		return "", fmt.Errorf("mock validation failure")
 	}
@@ -1031,6 +1036,28 @@ func TestDualAuthMiddleware_ServiceJWT_InvalidJWT(t *testing.T) {
 	}
 }
 
+// TestDualAuthMiddleware_ServiceJWT_KeyUnavailable tests that a failed issuer key fetch is a 503, not a 401
+func TestDualAuthMiddleware_ServiceJWT_KeyUnavailable(t *testing.T) {
+	validator := &mockServiceAuthValidator{
+		err: fmt.Errorf("token is unverifiable: %w", fmt.Errorf("%w: PLC down", identity.ErrKeyFetchFailed)),
+	}
+	middleware := NewDualAuthMiddleware(newMockOAuthClient(), newMockOAuthStore(), validator, &mockAggregatorChecker{aggregators: make(map[string]bool)})
+
+	handler := middleware.RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	}))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	req.Header.Set("Authorization", "Bearer some.service.jwt")
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+}
+
 // TestDualAuthMiddleware_ServiceJWT_NotAggregator tests service JWT auth with non-aggregator DID
 func TestDualAuthMiddleware_ServiceJWT_NotAggregator(t *testing.T) {
 	client := newMockOAuthClient()
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORSMaxAge is how long browsers may cache a preflight response
+// Chromium caps it at 2 hours; Firefox allows 24.
+const CORSMaxAge = 2 * time.Hour
+
+// WildcardOrigin allows any origin, without credentials. Meant for development.
+const WildcardOrigin = "*"
+
+var (
+	// corsAllowedMethods are the methods XRPC and OAuth routes use
+	corsAllowedMethods = "GET, POST, OPTIONS"
+
+	// corsAllowedHeaders are the request headers clients may send cross-origin
+	corsAllowedHeaders = "Accept, Authorization, Content-Type, DPoP, Idempotency-Key, If-None-Match"
+
+	// corsExposedHeaders are the response headers scripts may read cross-origin
+	corsExposedHeaders = "Retry-After, RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset"
+)
+
+// CORS applies a per-origin cross-origin policy to the paths under its prefixes
+// Origins on the allow list get their own origin echoed back with credentials allowed.
+// WildcardOrigin lets every other origin call without credentials; it never grants
+// credentials. Preflights are answered here, so mount it ahead of rate limits and auth.
+type CORS struct {
+	origins   map[string]bool
+	prefixes  []string
+	anyOrigin bool
+}
+
+// NewCORS creates a CORS policy for paths starting with any of prefixes
+// allowedOrigins are scheme://host[:port] origins; WildcardOrigin may be one of them.
+func NewCORS(allowedOrigins []string, prefixes ...string) *CORS {
+	c := &CORS{
+		origins:  make(map[string]bool, len(allowedOrigins)),
+		prefixes: prefixes,
+	}
+	for _, origin := range allowedOrigins {
+		origin = normalizeOrigin(origin)
+		switch origin {
+		case "":
+		case WildcardOrigin:
+			c.anyOrigin = true
+		default:
+			c.origins[origin] = true
+		}
+	}
+	return c
+}
+
+// Middleware returns the CORS middleware
+// A preflight from an origin the policy doesn't allow is refused with 403; other requests
+// from such origins are served without CORS headers, so the browser withholds the response.
+func (c *CORS) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !c.applies(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Add("Vary", "Origin")
+
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// Same-origin or not a browser
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		explicit := c.origins[normalizeOrigin(origin)]
+		if !explicit && !c.anyOrigin {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if explicit {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+		} else {
+			header.Set("Access-Control-Allow-Origin", WildcardOrigin)
+		}
+
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			header.Set("Access-Control-Allow-Methods", corsAllowedMethods)
+			header.Set("Access-Control-Allow-Headers", corsAllowedHeaders)
+			header.Set("Access-Control-Max-Age", strconv.Itoa(int(CORSMaxAge.Seconds())))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		header.Set("Access-Control-Expose-Headers", corsExposedHeaders)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// applies reports whether path is under one of the policy's prefixes
+func (c *CORS) applies(path string) bool {
+	for _, prefix := range c.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeOrigin lowercases an origin and drops a trailing slash, as origins are compared
+// case-insensitively and config values are often copied from URLs
+func normalizeOrigin(origin string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
+}
@@ -0,0 +1,141 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func corsTestHandler(policy *CORS) (http.Handler, *int) {
+	calls := 0
+	return policy.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	})), &calls
+}
+
+func TestCORS_AllowedOrigin(t *testing.T) {
+	handler, calls := corsTestHandler(NewCORS([]string{"https://app.example/"}, "/xrpc/"))
+
+	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.community.get", nil)
+	req.Header.Set("Origin", "https://App.example")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if *calls != 1 {
+		t.Fatalf("Expected the handler to run once, ran %d times", *calls)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://App.example" {
+		t.Errorf("Expected the origin to be echoed, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Expected credentials for an explicitly allowed origin, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Expose-Headers"); got != corsExposedHeaders {
+		t.Errorf("Expected rate limit headers exposed, got %q", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Expected Vary: Origin, got %q", got)
+	}
+}
+
+func TestCORS_DisallowedOrigin(t *testing.T) {
+	handler, calls := corsTestHandler(NewCORS([]string{"https://app.example"}, "/xrpc/"))
+
+	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.community.get", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if *calls != 1 {
+		t.Errorf("Expected a simple request to be served (the browser withholds it), ran %d times", *calls)
+	}
+	for _, name := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials", "Access-Control-Expose-Headers"} {
+		if got := rec.Header().Get(name); got != "" {
+			t.Errorf("Expected no %s for a disallowed origin, got %q", name, got)
+		}
+	}
+
+	preflight := httptest.NewRequest(http.MethodOptions, "/xrpc/social.coves.community.create", nil)
+	preflight.Header.Set("Origin", "https://evil.example")
+	preflight.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, preflight)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("Expected a disallowed preflight to be refused with 403, got %d", rec.Code)
+	}
+	if *calls != 1 {
+		t.Errorf("Expected the preflight not to reach the handler")
+	}
+}
+
+func TestCORS_WildcardNeverGrantsCredentials(t *testing.T) {
+	handler, _ := corsTestHandler(NewCORS([]string{WildcardOrigin, "https://app.example"}, "/xrpc/"))
+
+	tests := []struct {
+		origin      string
+		wantOrigin  string
+		credentials string
+	}{
+		{origin: "https://anyone.example", wantOrigin: "*", credentials: ""},
+		{origin: "https://app.example", wantOrigin: "https://app.example", credentials: "true"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.community.get", nil)
+		req.Header.Set("Origin", tt.origin)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+			t.Errorf("%s: expected Access-Control-Allow-Origin %q, got %q", tt.origin, tt.wantOrigin, got)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != tt.credentials {
+			t.Errorf("%s: expected Access-Control-Allow-Credentials %q, got %q", tt.origin, tt.credentials, got)
+		}
+	}
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	handler, calls := corsTestHandler(NewCORS([]string{"https://app.example"}, "/xrpc/", "/oauth/"))
+
+	req := httptest.NewRequest(http.MethodOptions, "/oauth/refresh", nil)
+	req.Header.Set("Origin", "https://app.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "authorization, idempotency-key")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", rec.Code)
+	}
+	if *calls != 0 {
+		t.Errorf("Expected the preflight to short-circuit")
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Headers"); got != corsAllowedHeaders {
+		t.Errorf("Expected allowed headers %q, got %q", corsAllowedHeaders, got)
+	}
+	if got := rec.Header().Get("Access-Control-Max-Age"); got != "7200" {
+		t.Errorf("Expected a 2 hour max age, got %q", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Expected credentials on the preflight, got %q", got)
+	}
+}
+
+func TestCORS_OtherPathsUntouched(t *testing.T) {
+	handler, calls := corsTestHandler(NewCORS([]string{WildcardOrigin}, "/xrpc/", "/oauth/"))
+
+	req := httptest.NewRequest(http.MethodOptions, "/feeds/discover.xml", nil)
+	req.Header.Set("Origin", "https://app.example")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if *calls != 1 {
+		t.Errorf("Expected a path outside the prefixes to pass through")
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no CORS headers outside the prefixes, got %q", got)
+	}
+}
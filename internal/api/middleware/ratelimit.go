@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"Coves/internal/api/xrpcerror"
+	"math"
 	"net/http"
+	"strconv"
 	"sync"
 	"time"
 )
@@ -37,14 +40,23 @@ func NewRateLimiter(requests int, window time.Duration) *RateLimiter {
 }
 
 // Middleware returns a rate limiting middleware
+// Every response carries RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset (seconds until
+// the window resets); refused requests also get Retry-After.
 func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Use IP address as client identifier
 		// In production, consider using authenticated user ID if available
 		clientID := getClientIP(r)
 
-		if !rl.allow(clientID) {
-			http.Error(w, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
+		allowed, remaining, resetIn := rl.allow(clientID)
+		resetSeconds := strconv.Itoa(int(math.Ceil(resetIn.Seconds())))
+		w.Header().Set("RateLimit-Limit", strconv.Itoa(rl.requests))
+		w.Header().Set("RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("RateLimit-Reset", resetSeconds)
+
+		if !allowed {
+			w.Header().Set("Retry-After", resetSeconds)
+			xrpcerror.WriteError(w, http.StatusTooManyRequests, xrpcerror.RateLimitExceeded, "Rate limit exceeded. Please try again later.")
 			return
 		}
 
@@ -52,8 +64,9 @@ func (rl *RateLimiter) Middleware(next http.Handler) http.Handler {
 	})
 }
 
-// allow checks if a client is allowed to make a request
-func (rl *RateLimiter) allow(clientID string) bool {
+// allow checks if a client is allowed to make a request, returning the requests left in the
+// client's window and how long until it resets
+func (rl *RateLimiter) allow(clientID string) (bool, int, time.Duration) {
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
@@ -66,24 +79,25 @@ func (rl *RateLimiter) allow(clientID string) bool {
 			count:     1,
 			resetTime: now.Add(rl.window),
 		}
-		return true
+		return true, rl.requests - 1, rl.window
 	}
 
 	// Check if window has expired
 	if now.After(client.resetTime) {
 		client.count = 1
 		client.resetTime = now.Add(rl.window)
-		return true
+		return true, rl.requests - 1, rl.window
 	}
 
 	// Check if under limit
+	resetIn := client.resetTime.Sub(now)
 	if client.count < rl.requests {
 		client.count++
-		return true
+		return true, rl.requests - client.count, resetIn
 	}
 
 	// Rate limit exceeded
-	return false
+	return false, 0, resetIn
 }
 
 // cleanup removes expired client entries periodically
@@ -2,35 +2,90 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/actor"
-	"Coves/internal/api/middleware"
+	"Coves/internal/core/aggregators"
 	"Coves/internal/core/blueskypost"
 	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/notifications"
 	"Coves/internal/core/posts"
 	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
-
-	"github.com/go-chi/chi/v5"
+	"net/http"
 )
 
 // RegisterActorRoutes registers actor-related XRPC endpoints
 func RegisterActorRoutes(
-	r chi.Router,
+	reg *Registrar,
 	postService posts.Service,
 	userService users.UserService,
 	voteService votes.Service,
 	blueskyService blueskypost.Service,
 	commentService comments.Service,
-	authMiddleware *middleware.OAuthAuthMiddleware,
+	communityService communities.Service,
+	communityRepo communities.Repository,
+	aggregatorRepo aggregators.Repository,
+	ageGate communities.AgeGate,
 ) {
 	// Create handlers
-	getPostsHandler := actor.NewGetPostsHandler(postService, userService, voteService, blueskyService)
+	getPostsHandler := actor.NewGetPostsHandler(postService, userService, voteService, blueskyService, communityRepo, aggregatorRepo)
+	if ageGate != nil {
+		getPostsHandler.SetAgeGate(ageGate)
+	}
 	getCommentsHandler := actor.NewGetCommentsHandler(commentService, userService, voteService)
+	getSubscriptionsHandler := actor.NewGetSubscriptionsHandler(communityService)
+
+	reg.Handle(
+		// GET /xrpc/social.coves.actor.getPosts
+		// Public endpoint with optional auth for viewer-specific state (vote state)
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.actor.getPosts", Handler: getPostsHandler.HandleGetPosts, Auth: AuthOptional},
+
+		// GET /xrpc/social.coves.actor.getComments
+		// Public endpoint with optional auth for viewer-specific state (vote state)
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.actor.getComments", Handler: getCommentsHandler.HandleGetComments, Auth: AuthOptional},
+
+		// GET /xrpc/social.coves.actor.getSubscriptions
+		// Requires authentication - lists the viewer's own subscribed communities
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.actor.getSubscriptions", Handler: getSubscriptionsHandler.HandleGetSubscriptions, Auth: AuthRequired},
+	)
+}
+
+// RegisterActorActivityRoutes registers the profile activity heatmap endpoint
+func RegisterActorActivityRoutes(reg *Registrar, activityService users.ActivityService, userService users.UserService) {
+	getActivityHandler := actor.NewGetActivityHandler(activityService, userService)
+
+	reg.Handle(
+		// GET /xrpc/social.coves.actor.getActivity
+		// Public endpoint; counts are the same for every viewer and cached per actor
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.actor.getActivity", Handler: getActivityHandler.HandleGetActivity, Auth: AuthPublic},
+	)
+}
+
+// RegisterThreadMuteRoutes registers the thread mute endpoints
+func RegisterThreadMuteRoutes(reg *Registrar, service notifications.ThreadMuteService) {
+	handler := actor.NewThreadMuteHandler(service)
+
+	reg.Handle(
+		// POST /xrpc/social.coves.actor.muteThread
+		// Requires authentication - stops reply and mention notifications from a post's thread
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.actor.muteThread", Handler: handler.HandleMuteThread, Auth: AuthRequired},
+
+		// POST /xrpc/social.coves.actor.unmuteThread
+		// Requires authentication
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.actor.unmuteThread", Handler: handler.HandleUnmuteThread, Auth: AuthRequired},
+	)
+}
+
+// RegisterDraftRoutes registers the comment draft endpoints
+func RegisterDraftRoutes(reg *Registrar, service comments.DraftService) {
+	handler := actor.NewDraftHandler(service)
 
-	// GET /xrpc/social.coves.actor.getPosts
-	// Public endpoint with optional auth for viewer-specific state (vote state)
-	r.With(authMiddleware.OptionalAuth).Get("/xrpc/social.coves.actor.getPosts", getPostsHandler.HandleGetPosts)
+	reg.Handle(
+		// POST /xrpc/social.coves.actor.putDraft
+		// Requires authentication - saves the caller's draft reply; rate limited since clients autosave
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.actor.putDraft", Handler: handler.HandlePutDraft, Auth: AuthRequired, RateLimit: RateLimitDraft},
 
-	// GET /xrpc/social.coves.actor.getComments
-	// Public endpoint with optional auth for viewer-specific state (vote state)
-	r.With(authMiddleware.OptionalAuth).Get("/xrpc/social.coves.actor.getComments", getCommentsHandler.HandleGetComments)
+		// GET /xrpc/social.coves.actor.getDraft
+		// Requires authentication - restores the caller's draft reply
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.actor.getDraft", Handler: handler.HandleGetDraft, Auth: AuthRequired},
+	)
 }
@@ -0,0 +1,137 @@
+package routes
+
+import (
+	"Coves/internal/api/handlers/admin"
+	"Coves/internal/core/audit"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/confusables"
+	"Coves/internal/core/consumergaps"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/federation"
+	"Coves/internal/core/moderation"
+	"Coves/internal/core/spamguard"
+	"Coves/internal/core/votes"
+	"net/http"
+)
+
+// RegisterAdminRoutes registers instance administration XRPC endpoints
+// Every route requires auth; adminDIDs lists who may call them. If empty, every request is rejected.
+// Successful admin and moderator actions are recorded to auditLog when it's non-nil.
+func RegisterAdminRoutes(
+	reg *Registrar,
+	federationService federation.Service,
+	voteRepo votes.Repository,
+	voterPolicy *votes.AccessPolicy,
+	discoverService discover.Service,
+	indexingMetrics admin.IndexingMetrics,
+	indexingStatus admin.IndexingStatus,
+	reservedNames admin.ReservedNames,
+	moderationService moderation.Service,
+	impersonationRepo communities.ImpersonationRepository,
+	spamQueue spamguard.QueueRepository,
+	provisionings admin.Provisionings,
+	auditLog audit.Service,
+	confusablesRepo confusables.Repository,
+	consumerGaps consumergaps.Repository,
+	adminDIDs []string,
+) {
+	admins := admin.NewAdmins(adminDIDs)
+	if auditLog != nil {
+		admins = admins.WithAudit(auditLog)
+	}
+	federationHandler := admin.NewFederationHandler(federationService, admins)
+	votesHandler := admin.NewVotesHandler(voteRepo, voterPolicy, admins)
+	metricsHandler := admin.NewMetricsHandler(indexingMetrics, admins)
+	indexingStatusHandler := admin.NewIndexingStatusHandler(indexingStatus, admins)
+	featuredHandler := admin.NewFeaturedCommunitiesHandler(discoverService, admins)
+	reservedNamesHandler := admin.NewReservedNamesHandler(reservedNames, admins)
+	moderationHandler := admin.NewModerationHandler(moderationService, admins)
+	impersonationHandler := admin.NewImpersonationHandler(impersonationRepo, admins)
+	spamHandler := admin.NewSpamHandler(spamQueue, admins)
+	provisioningHandler := admin.NewProvisioningHandler(provisionings, admins)
+	auditHandler := admin.NewAuditHandler(auditLog, admins)
+	confusablesHandler := admin.NewConfusablesHandler(confusablesRepo, admins)
+	consumerGapsHandler := admin.NewConsumerGapsHandler(consumerGaps, admins)
+
+	reg.Handle(
+		// Federation allow/deny rules for remote instances
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.listFederationRules", Handler: federationHandler.HandleListRules, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.createFederationRule", Handler: federationHandler.HandleCreateRule, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.deleteFederationRule", Handler: federationHandler.HandleDeleteRule, Auth: AuthRequired},
+
+		// Vote nullification for accounts caught manipulating votes
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.nullifyVotes", Handler: votesHandler.HandleNullifyVotes, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.restoreVotes", Handler: votesHandler.HandleRestoreVotes, Auth: AuthRequired},
+
+		// Indexing metrics (e.g. image posts indexed without alt text)
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.getMetrics", Handler: metricsHandler.HandleGetMetrics, Auth: AuthRequired},
+
+		// Which Jetstream consumer indexes each collection
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.getIndexingStatus", Handler: indexingStatusHandler.HandleGetIndexingStatus, Auth: AuthRequired},
+
+		// Featured communities shown on the logged-out front page
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.listFeaturedCommunities", Handler: featuredHandler.HandleList, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.addFeaturedCommunity", Handler: featuredHandler.HandleAdd, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.removeFeaturedCommunity", Handler: featuredHandler.HandleRemove, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.reorderFeaturedCommunities", Handler: featuredHandler.HandleReorder, Auth: AuthRequired},
+
+		// Community names no user may create (anti-squatting)
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.listReservedCommunityNames", Handler: reservedNamesHandler.HandleList, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.addReservedCommunityName", Handler: reservedNamesHandler.HandleAdd, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.removeReservedCommunityName", Handler: reservedNamesHandler.HandleRemove, Auth: AuthRequired},
+
+		// Post/comment visibility: removed, or author_only (hidden from everyone but the author)
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.setContentVisibility", Handler: moderationHandler.HandleSetVisibility, Auth: AuthRequired},
+
+		// Thread locks: locked posts accept no new comments
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.setThreadLock", Handler: moderationHandler.HandleSetThreadLock, Auth: AuthRequired},
+
+		// Post label overrides: apply or remove nsfw/spoiler/violence/gore over self and community labels
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.setPostLabel", Handler: moderationHandler.HandleSetPostLabel, Auth: AuthRequired},
+
+		// Record takedowns: hide a post or comment everywhere, deleting it upstream when it's
+		// in a community repo this instance provisioned
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.takedownRecord", Handler: moderationHandler.HandleTakedownRecord, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.reverseTakedown", Handler: moderationHandler.HandleReverseTakedown, Auth: AuthRequired},
+
+		// Comment edit history: the comment's community moderators may read it too, not just admins
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.moderation.getCommentHistory", Handler: moderationHandler.HandleGetCommentHistory, Auth: AuthRequired},
+
+		// Who voted on a post or comment: its author and community moderators may ask too, not just admins
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.moderation.getVoters", Handler: votesHandler.HandleGetVoters, Auth: AuthRequired},
+
+		// Brigade alerts: posts voted up or down by a burst of accounts new to the community,
+		// queued for the community's moderators (and admins) to dismiss or confirm
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.moderation.listBrigadeAlerts", Handler: moderationHandler.HandleListBrigadeAlerts, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.moderation.reviewBrigadeAlert", Handler: moderationHandler.HandleReviewBrigadeAlert, Auth: AuthRequired},
+
+		// Automod: community keyword/domain/regex-lite rules applied at index time, and the queue
+		// of content they held or flagged for the community's moderators (and admins)
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.moderation.getAutomod", Handler: moderationHandler.HandleGetAutomod, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.moderation.updateAutomod", Handler: moderationHandler.HandleUpdateAutomod, Auth: AuthRequired},
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.moderation.listAutomodQueue", Handler: moderationHandler.HandleListAutomodQueue, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.moderation.reviewAutomodAction", Handler: moderationHandler.HandleReviewAutomodAction, Auth: AuthRequired},
+
+		// Communities flagged at index time as impersonating another community
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.listImpersonationFlags", Handler: impersonationHandler.HandleList, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.reviewImpersonationFlag", Handler: impersonationHandler.HandleReview, Auth: AuthRequired},
+
+		// Posts the spam guard quarantined at index time, awaiting release or confirmation
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.listQuarantinedPosts", Handler: spamHandler.HandleList, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.reviewQuarantinedPost", Handler: spamHandler.HandleReview, Auth: AuthRequired},
+
+		// Community creations that stopped partway (PDS account created, community never stored)
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.listStuckProvisionings", Handler: provisioningHandler.HandleList, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.cleanupProvisioning", Handler: provisioningHandler.HandleCleanup, Auth: AuthRequired},
+
+		// Hash-chained log of admin and moderator actions, with optional chain verification
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.getAuditLog", Handler: auditHandler.HandleGetAuditLog, Auth: AuthRequired},
+
+		// Users and communities whose handle imitates another's with lookalike characters
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.listConfusableHandles", Handler: confusablesHandler.HandleList, Auth: AuthRequired},
+
+		// Windows of firehose events the Jetstream consumers missed; feeds report unfilled ones
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.admin.listConsumerGaps", Handler: consumerGapsHandler.HandleList, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.admin.markGapBackfilled", Handler: consumerGapsHandler.HandleMarkBackfilled, Auth: AuthRequired},
+	)
+}
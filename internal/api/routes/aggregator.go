@@ -2,21 +2,17 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/aggregator"
-	"Coves/internal/api/middleware"
 	"Coves/internal/atproto/identity"
 	"Coves/internal/core/aggregators"
 	"Coves/internal/core/communities"
 	"Coves/internal/core/users"
 	"net/http"
-	"time"
-
-	"github.com/go-chi/chi/v5"
 )
 
 // RegisterAggregatorRoutes registers aggregator-related XRPC endpoints
 // Following Bluesky's pattern for feed generators and labelers
 func RegisterAggregatorRoutes(
-	r chi.Router,
+	reg *Registrar,
 	aggregatorService aggregators.Service,
 	communityService communities.Service,
 	userService users.UserService,
@@ -26,30 +22,51 @@ func RegisterAggregatorRoutes(
 	getServicesHandler := aggregator.NewGetServicesHandler(aggregatorService)
 	getAuthorizationsHandler := aggregator.NewGetAuthorizationsHandler(aggregatorService)
 	listForCommunityHandler := aggregator.NewListForCommunityHandler(aggregatorService, communityService)
+	listServicesHandler := aggregator.NewListServicesHandler(aggregatorService)
+	getServiceHandler := aggregator.NewGetServiceHandler(aggregatorService)
+	validateRecordHandler := aggregator.NewValidateRecordHandler()
 
 	// Create registration handler
 	registerHandler := aggregator.NewRegisterHandler(userService, identityResolver)
 
-	// Query endpoints (public - no auth required)
-	// GET /xrpc/social.coves.aggregator.getServices?dids=did:plc:abc,did:plc:def
-	// Following app.bsky.feed.getFeedGenerators pattern
-	r.Get("/xrpc/social.coves.aggregator.getServices", getServicesHandler.HandleGetServices)
+	reg.Handle(
+		// Query endpoints (public - no auth required)
+		// GET /xrpc/social.coves.aggregator.getServices?dids=did:plc:abc,did:plc:def
+		// Following app.bsky.feed.getFeedGenerators pattern
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.aggregator.getServices", Handler: getServicesHandler.HandleGetServices, Auth: AuthPublic},
+
+		// GET /xrpc/social.coves.aggregator.getAuthorizations?aggregatorDid=did:plc:abc&enabledOnly=true
+		// Lists communities that authorized an aggregator
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.aggregator.getAuthorizations", Handler: getAuthorizationsHandler.HandleGetAuthorizations, Auth: AuthPublic},
+
+		// GET /xrpc/social.coves.aggregator.listForCommunity?communityDid=did:plc:xyz&enabledOnly=true
+		// Lists aggregators authorized by a community
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.aggregator.listForCommunity", Handler: listForCommunityHandler.HandleListForCommunity, Auth: AuthPublic},
+
+		// GET /xrpc/social.coves.aggregator.listServices?limit=50&cursor=50
+		// Public directory of aggregators, most widely used first
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.aggregator.listServices", Handler: listServicesHandler.HandleListServices, Auth: AuthPublic},
 
-	// GET /xrpc/social.coves.aggregator.getAuthorizations?aggregatorDid=did:plc:abc&enabledOnly=true
-	// Lists communities that authorized an aggregator
-	r.Get("/xrpc/social.coves.aggregator.getAuthorizations", getAuthorizationsHandler.HandleGetAuthorizations)
+		// GET /xrpc/social.coves.aggregator.getService?aggregator=did:plc:abc
+		// Directory detail: the aggregator and the communities that enabled it
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.aggregator.getService", Handler: getServiceHandler.HandleGetService, Auth: AuthPublic},
 
-	// GET /xrpc/social.coves.aggregator.listForCommunity?communityDid=did:plc:xyz&enabledOnly=true
-	// Lists aggregators authorized by a community
-	r.Get("/xrpc/social.coves.aggregator.listForCommunity", listForCommunityHandler.HandleListForCommunity)
+		// POST /xrpc/social.coves.aggregator.validateRecord
+		// Schema check of a post record for aggregator developers; reads nothing and writes nothing
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.aggregator.validateRecord", Handler: validateRecordHandler.HandleValidateRecord, Auth: AuthPublic},
 
-	// Registration endpoint (public - no auth required)
-	// Aggregators register themselves after creating their own PDS accounts
-	// POST /xrpc/social.coves.aggregator.register
-	// Rate limited to 10 requests per 10 minutes per IP to prevent abuse
-	registrationRateLimiter := middleware.NewRateLimiter(10, 10*time.Minute)
-	r.Post("/xrpc/social.coves.aggregator.register",
-		registrationRateLimiter.Middleware(http.HandlerFunc(registerHandler.HandleRegister)).ServeHTTP)
+		// Registration endpoint (public - no auth required)
+		// Aggregators register themselves after creating their own PDS accounts
+		// POST /xrpc/social.coves.aggregator.register
+		// Rate limited to 10 requests per 10 minutes per IP to prevent abuse
+		Route{
+			Method:    http.MethodPost,
+			Path:      "/xrpc/social.coves.aggregator.register",
+			Handler:   registerHandler.HandleRegister,
+			Auth:      AuthPublic,
+			RateLimit: RateLimitRegistration,
+		},
+	)
 
 	// Write endpoints (Phase 2 - require authentication and moderator permissions)
 	// TODO: Implement after Jetstream consumer is ready
@@ -60,10 +77,8 @@ func RegisterAggregatorRoutes(
 
 // RegisterAggregatorAPIKeyRoutes registers API key management endpoints for aggregators.
 // These endpoints require OAuth authentication and are only available to registered aggregators.
-// Call this function AFTER setting up the auth middleware.
 func RegisterAggregatorAPIKeyRoutes(
-	r chi.Router,
-	authMiddleware middleware.AuthMiddleware,
+	reg *Registrar,
 	apiKeyService aggregators.APIKeyServiceInterface,
 	aggregatorService aggregators.Service,
 ) {
@@ -73,24 +88,23 @@ func RegisterAggregatorAPIKeyRoutes(
 	revokeAPIKeyHandler := aggregator.NewRevokeAPIKeyHandler(apiKeyService, aggregatorService)
 	metricsHandler := aggregator.NewMetricsHandler(apiKeyService)
 
-	// API key management endpoints (require OAuth authentication)
-	// POST /xrpc/social.coves.aggregator.createApiKey
-	// Creates a new API key for the authenticated aggregator
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.aggregator.createApiKey",
-		createAPIKeyHandler.HandleCreateAPIKey)
-
-	// GET /xrpc/social.coves.aggregator.getApiKey
-	// Gets info about the authenticated aggregator's API key (not the key itself)
-	r.With(authMiddleware.RequireAuth).Get("/xrpc/social.coves.aggregator.getApiKey",
-		getAPIKeyHandler.HandleGetAPIKey)
-
-	// POST /xrpc/social.coves.aggregator.revokeApiKey
-	// Revokes the authenticated aggregator's API key
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.aggregator.revokeApiKey",
-		revokeAPIKeyHandler.HandleRevokeAPIKey)
-
-	// GET /xrpc/social.coves.aggregator.getMetrics
-	// Returns operational metrics for the API key service (internal monitoring endpoint)
-	// No authentication required - metrics are non-sensitive operational data
-	r.Get("/xrpc/social.coves.aggregator.getMetrics", metricsHandler.HandleMetrics)
+	reg.Handle(
+		// API key management endpoints (require OAuth authentication)
+		// POST /xrpc/social.coves.aggregator.createApiKey
+		// Creates a new API key for the authenticated aggregator
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.aggregator.createApiKey", Handler: createAPIKeyHandler.HandleCreateAPIKey, Auth: AuthRequired},
+
+		// GET /xrpc/social.coves.aggregator.getApiKey
+		// Gets info about the authenticated aggregator's API key (not the key itself)
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.aggregator.getApiKey", Handler: getAPIKeyHandler.HandleGetAPIKey, Auth: AuthRequired},
+
+		// POST /xrpc/social.coves.aggregator.revokeApiKey
+		// Revokes the authenticated aggregator's API key
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.aggregator.revokeApiKey", Handler: revokeAPIKeyHandler.HandleRevokeAPIKey, Auth: AuthRequired},
+
+		// GET /xrpc/social.coves.aggregator.getMetrics
+		// Returns operational metrics for the API key service (internal monitoring endpoint)
+		// No authentication required - metrics are non-sensitive operational data
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.aggregator.getMetrics", Handler: metricsHandler.HandleMetrics, Auth: AuthPublic},
+	)
 }
@@ -0,0 +1,377 @@
+package routes
+
+import (
+	"Coves/internal/api/handlers/admin"
+	"Coves/internal/api/middleware"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/auth/oauth"
+	"github.com/go-chi/chi/v5"
+)
+
+// authMatrix is the expected auth mode of every route the server registers
+// A route missing from this list, or registered with a different mode, fails the test:
+// update it deliberately when adding or changing a route.
+var authMatrix = map[string]AuthMode{
+	// Users and preferences
+	"GET /xrpc/social.coves.actor.getprofile":      AuthPublic,
+	"POST /xrpc/social.coves.actor.signup":         AuthPublic,
+	"POST /xrpc/social.coves.actor.deleteAccount":  AuthRequired,
+	"POST /xrpc/social.coves.actor.updateProfile":  AuthRequired,
+	"GET /xrpc/social.coves.actor.getPreferences":  AuthRequired,
+	"POST /xrpc/social.coves.actor.putPreferences": AuthRequired,
+
+	// Communities
+	"GET /xrpc/social.coves.community.get":                  AuthOptional,
+	"GET /xrpc/social.coves.community.list":                 AuthOptional,
+	"GET /xrpc/social.coves.community.search":               AuthPublic,
+	"POST /xrpc/social.coves.community.create":              AuthRequired,
+	"POST /xrpc/social.coves.community.update":              AuthRequired,
+	"POST /xrpc/social.coves.community.updateRules":         AuthRequired,
+	"POST /xrpc/social.coves.community.subscribe":           AuthRequired,
+	"POST /xrpc/social.coves.community.unsubscribe":         AuthRequired,
+	"POST /xrpc/social.coves.community.blockCommunity":      AuthRequired,
+	"POST /xrpc/social.coves.community.unblockCommunity":    AuthRequired,
+	"POST /xrpc/social.coves.community.delete":              AuthRequired,
+	"POST /xrpc/social.coves.community.undelete":            AuthRequired,
+	"POST /xrpc/social.coves.community.confirmAge":          AuthRequired,
+	"POST /xrpc/social.coves.community.schedulePost":        AuthRequired,
+	"GET /xrpc/social.coves.community.listScheduledPosts":   AuthRequired,
+	"POST /xrpc/social.coves.community.cancelScheduledPost": AuthRequired,
+
+	// Posts, votes and comments
+	"POST /xrpc/social.coves.community.post.create":        AuthService,
+	"POST /xrpc/social.coves.community.post.delete":        AuthService,
+	"GET /xrpc/social.coves.feed.checkDuplicate":           AuthRequired,
+	"POST /xrpc/social.coves.feed.vote.create":             AuthRequired,
+	"POST /xrpc/social.coves.feed.vote.delete":             AuthRequired,
+	"GET /xrpc/social.coves.community.comment.getComments": AuthOptional,
+	"POST /xrpc/social.coves.community.comment.create":     AuthRequired,
+	"POST /xrpc/social.coves.community.comment.update":     AuthRequired,
+	"POST /xrpc/social.coves.community.comment.delete":     AuthRequired,
+	"GET /xrpc/social.coves.community.comment.search":      AuthRequired,
+
+	// Feeds
+	"GET /xrpc/social.coves.communityFeed.getCommunity":       AuthOptional,
+	"GET /xrpc/social.coves.feed.getTimeline":                 AuthRequired,
+	"GET /xrpc/social.coves.feed.getPost":                     AuthOptional,
+	"GET /xrpc/social.coves.feed.getDiscover":                 AuthOptional,
+	"GET /xrpc/social.coves.discover.getFrontPage":            AuthOptional,
+	"GET /xrpc/social.coves.discover.getCategories":           AuthPublic,
+	"GET /xrpc/social.coves.discover.getSuggestedCommunities": AuthRequired,
+	"GET /xrpc/social.coves.feed.getDiscussions":              AuthOptional,
+	"GET /xrpc/social.coves.feed.translate":                   AuthRequired,
+	"GET /xrpc/social.coves.resolve.uri":                      AuthPublic,
+	"GET /feeds/community/{file}":                             AuthPublic,
+	"GET /feeds/discover.xml":                                 AuthPublic,
+	"GET /xrpc/social.coves.sync.subscribe":                   AuthOptional,
+	"GET /xrpc/social.coves.sync.listCommunities":             AuthPublic,
+
+	// Actors
+	"GET /xrpc/social.coves.actor.getPosts":         AuthOptional,
+	"GET /xrpc/social.coves.actor.getComments":      AuthOptional,
+	"GET /xrpc/social.coves.actor.getSubscriptions": AuthRequired,
+	"GET /xrpc/social.coves.actor.getActivity":      AuthPublic,
+	"POST /xrpc/social.coves.actor.muteThread":      AuthRequired,
+	"POST /xrpc/social.coves.actor.unmuteThread":    AuthRequired,
+	"POST /xrpc/social.coves.actor.putDraft":        AuthRequired,
+	"GET /xrpc/social.coves.actor.getDraft":         AuthRequired,
+
+	// Notifications
+	"GET /xrpc/social.coves.notification.listNotifications": AuthRequired,
+	"POST /xrpc/social.coves.notification.updateSeen":       AuthRequired,
+
+	// Instance administration
+	"GET /xrpc/social.coves.admin.listFederationRules":          AuthRequired,
+	"POST /xrpc/social.coves.admin.createFederationRule":        AuthRequired,
+	"POST /xrpc/social.coves.admin.deleteFederationRule":        AuthRequired,
+	"POST /xrpc/social.coves.admin.nullifyVotes":                AuthRequired,
+	"POST /xrpc/social.coves.admin.restoreVotes":                AuthRequired,
+	"GET /xrpc/social.coves.admin.getIndexingStatus":            AuthRequired,
+	"GET /xrpc/social.coves.admin.getMetrics":                   AuthRequired,
+	"GET /xrpc/social.coves.admin.listFeaturedCommunities":      AuthRequired,
+	"POST /xrpc/social.coves.admin.addFeaturedCommunity":        AuthRequired,
+	"POST /xrpc/social.coves.admin.removeFeaturedCommunity":     AuthRequired,
+	"POST /xrpc/social.coves.admin.reorderFeaturedCommunities":  AuthRequired,
+	"GET /xrpc/social.coves.admin.listReservedCommunityNames":   AuthRequired,
+	"POST /xrpc/social.coves.admin.addReservedCommunityName":    AuthRequired,
+	"POST /xrpc/social.coves.admin.removeReservedCommunityName": AuthRequired,
+	"POST /xrpc/social.coves.admin.setContentVisibility":        AuthRequired,
+	"POST /xrpc/social.coves.admin.setThreadLock":               AuthRequired,
+	"POST /xrpc/social.coves.admin.setPostLabel":                AuthRequired,
+	"POST /xrpc/social.coves.admin.takedownRecord":              AuthRequired,
+	"POST /xrpc/social.coves.admin.reverseTakedown":             AuthRequired,
+	"GET /xrpc/social.coves.moderation.getCommentHistory":       AuthRequired,
+	"GET /xrpc/social.coves.moderation.getVoters":               AuthRequired,
+	"GET /xrpc/social.coves.moderation.listBrigadeAlerts":       AuthRequired,
+	"POST /xrpc/social.coves.moderation.reviewBrigadeAlert":     AuthRequired,
+	"GET /xrpc/social.coves.moderation.getAutomod":              AuthRequired,
+	"POST /xrpc/social.coves.moderation.updateAutomod":          AuthRequired,
+	"GET /xrpc/social.coves.moderation.listAutomodQueue":        AuthRequired,
+	"POST /xrpc/social.coves.moderation.reviewAutomodAction":    AuthRequired,
+	"GET /xrpc/social.coves.admin.listImpersonationFlags":       AuthRequired,
+	"POST /xrpc/social.coves.admin.reviewImpersonationFlag":     AuthRequired,
+	"GET /xrpc/social.coves.admin.listQuarantinedPosts":         AuthRequired,
+	"POST /xrpc/social.coves.admin.reviewQuarantinedPost":       AuthRequired,
+	"GET /xrpc/social.coves.admin.listStuckProvisionings":       AuthRequired,
+	"POST /xrpc/social.coves.admin.cleanupProvisioning":         AuthRequired,
+	"GET /xrpc/social.coves.admin.getAuditLog":                  AuthRequired,
+	"GET /xrpc/social.coves.admin.listConfusableHandles":        AuthRequired,
+	"GET /xrpc/social.coves.admin.listConsumerGaps":             AuthRequired,
+	"POST /xrpc/social.coves.admin.markGapBackfilled":           AuthRequired,
+
+	// Aggregators
+	"GET /xrpc/social.coves.aggregator.getServices":       AuthPublic,
+	"GET /xrpc/social.coves.aggregator.getAuthorizations": AuthPublic,
+	"GET /xrpc/social.coves.aggregator.listForCommunity":  AuthPublic,
+	"GET /xrpc/social.coves.aggregator.listServices":      AuthPublic,
+	"GET /xrpc/social.coves.aggregator.getService":        AuthPublic,
+	"POST /xrpc/social.coves.aggregator.register":         AuthPublic,
+	"POST /xrpc/social.coves.aggregator.validateRecord":   AuthPublic,
+	"POST /xrpc/social.coves.aggregator.createApiKey":     AuthRequired,
+	"GET /xrpc/social.coves.aggregator.getApiKey":         AuthRequired,
+	"POST /xrpc/social.coves.aggregator.revokeApiKey":     AuthRequired,
+	"GET /xrpc/social.coves.aggregator.getMetrics":        AuthPublic,
+
+	// OAuth, well-known, web pages and health
+	"GET /oauth-client-metadata.json":             AuthPublic,
+	"GET /.well-known/oauth-protected-resource":   AuthPublic,
+	"GET /oauth/login":                            AuthPublic,
+	"GET /oauth/mobile/login":                     AuthPublic,
+	"GET /oauth/callback":                         AuthPublic,
+	"GET /app/oauth/callback":                     AuthPublic,
+	"POST /oauth/logout":                          AuthPublic,
+	"POST /oauth/refresh":                         AuthPublic,
+	"GET /.well-known/apple-app-site-association": AuthPublic,
+	"GET /.well-known/assetlinks.json":            AuthPublic,
+	"GET /.well-known/did.json":                   AuthPublic,
+	"GET /.well-known/nodeinfo":                   AuthPublic,
+	"GET /nodeinfo/2.1":                           AuthPublic,
+	"GET /xrpc/social.coves.instance.getStats":    AuthPublic,
+	"GET /img/{preset}/plain/{did}/{cid}":         AuthPublic,
+	"GET /img/identicon/{did}":                    AuthPublic,
+	"GET /":                                       AuthPublic,
+	"GET /delete-account":                         AuthPublic,
+	"POST /delete-account":                        AuthPublic,
+	"GET /delete-account/success":                 AuthPublic,
+	"GET /privacy":                                AuthPublic,
+	"GET /static/*":                               AuthPublic,
+	"GET /health":                                 AuthPublic,
+	"GET /xrpc/_health":                           AuthPublic,
+	"GET /health/ready":                           AuthPublic,
+}
+
+// fakeAuth answers for the auth middleware without calling the handler, naming the mode applied
+type fakeAuth struct {
+	mode AuthMode
+}
+
+func (f fakeAuth) RequireAuth(next http.Handler) http.Handler {
+	return f.answer(AuthRequired)
+}
+
+func (f fakeAuth) OptionalAuth(next http.Handler) http.Handler {
+	return f.answer(AuthOptional)
+}
+
+func (f fakeAuth) answer(mode AuthMode) http.Handler {
+	if f.mode != "" {
+		mode = f.mode
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Auth-Mode", string(mode))
+		w.WriteHeader(http.StatusTeapot)
+	})
+}
+
+// registerAll registers every route table the way cmd/server does, with no dependencies
+func registerAll(t *testing.T) (*chi.Mux, *Registrar) {
+	t.Helper()
+	r := chi.NewRouter()
+	reg := NewRegistrar(r, fakeAuth{})
+	reg.SetServiceAuth(fakeAuth{mode: AuthService})
+
+	RegisterUserRoutes(reg, nil, &oauth.ClientApp{})
+	RegisterPreferencesRoutes(reg, nil, &oauth.ClientApp{}, nil)
+	RegisterCommunityRoutes(reg, nil, nil, nil, nil)
+	RegisterPostRoutes(reg, nil)
+	RegisterGetPostRoutes(reg, nil, nil, nil, nil, nil, nil, nil)
+	RegisterVoteRoutes(reg, nil)
+	RegisterCommentRoutes(reg, nil, nil)
+	RegisterCommunityFeedRoutes(reg, nil, nil, nil, nil, nil, nil)
+	RegisterTimelineRoutes(reg, nil, nil, nil, nil, nil, nil)
+	RegisterDiscoverRoutes(reg, nil, nil, nil, nil, nil, nil, nil)
+	RegisterFeedRoutes(reg, nil)
+	RegisterTranslationRoutes(reg, nil)
+	RegisterResolveRoutes(reg, nil)
+	RegisterSuggestionRoutes(reg, nil)
+	RegisterScheduledPostRoutes(reg, nil)
+	RegisterDraftRoutes(reg, nil)
+	RegisterDirectoryRoutes(reg, nil)
+	RegisterInstanceRoutes(reg, nil, "", "")
+	RegisterActorRoutes(reg, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	RegisterActorActivityRoutes(reg, nil, nil)
+	RegisterNotificationRoutes(reg, nil)
+	RegisterThreadMuteRoutes(reg, nil)
+	RegisterLiveRoutes(reg, nil)
+	RegisterAdminRoutes(reg, nil, nil, nil, nil, nil, admin.IndexingStatus{}, nil, nil, nil, nil, nil, nil, nil, nil, nil)
+	RegisterAggregatorRoutes(reg, nil, nil, nil, nil)
+	RegisterAggregatorAPIKeyRoutes(reg, nil, nil)
+	RegisterOAuthRoutes(reg, nil)
+	RegisterWellKnownRoutes(reg)
+	RegisterDIDWebRoutes(reg, nil)
+	RegisterImageProxyRoutes(reg, nil)
+	RegisterWebRoutes(reg, nil, nil)
+	RegisterHealthRoutes(reg, nil)
+	return r, reg
+}
+
+func TestAuthMatrix(t *testing.T) {
+	r, reg := registerAll(t)
+
+	registered := make(map[string]bool)
+	for _, route := range reg.Routes() {
+		key := route.Method + " " + route.Path
+		if registered[key] {
+			t.Errorf("%s is registered twice", key)
+		}
+		registered[key] = true
+
+		want, ok := authMatrix[key]
+		if !ok {
+			t.Errorf("%s (%s) is missing from the auth matrix", key, route.Auth)
+			continue
+		}
+		if route.Auth != want {
+			t.Errorf("%s is registered with %s auth, want %s", key, route.Auth, want)
+		}
+	}
+	for key := range authMatrix {
+		if !registered[key] {
+			t.Errorf("%s is in the auth matrix but not registered", key)
+		}
+	}
+
+	// Every route on the router came through the registrar (OPTIONS are its preflights)
+	err := chi.Walk(r, func(method, path string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		if method != http.MethodOptions && !registered[method+" "+path] {
+			t.Errorf("%s %s was registered on the router directly", method, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walk failed: %v", err)
+	}
+}
+
+// TestAuthMatrix_MiddlewareApplied checks that requests hit the auth middleware the table names
+func TestAuthMatrix_MiddlewareApplied(t *testing.T) {
+	r, reg := registerAll(t)
+
+	for _, route := range reg.Routes() {
+		if route.Auth == AuthPublic {
+			continue
+		}
+		path := strings.ReplaceAll(route.Path, "{file}", "x.xml")
+		req := httptest.NewRequest(route.Method, path, nil)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if got := rec.Header().Get("X-Auth-Mode"); got != string(route.Auth) {
+			t.Errorf("%s %s went through %q auth, want %s", route.Method, route.Path, got, route.Auth)
+		}
+	}
+}
+
+func TestRegistrar_XRPCPreflight(t *testing.T) {
+	r, reg := registerAll(t)
+
+	for _, route := range reg.Routes() {
+		if !strings.HasPrefix(route.Path, "/xrpc/") {
+			continue
+		}
+		req := httptest.NewRequest(http.MethodOptions, route.Path, nil)
+		req.Header.Set("Origin", "https://client.example")
+		req.Header.Set("Access-Control-Request-Method", route.Method)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Errorf("OPTIONS %s returned %d, want 204", route.Path, rec.Code)
+		}
+		if rec.Header().Get("X-Auth-Mode") != "" {
+			t.Errorf("OPTIONS %s went through auth", route.Path)
+		}
+		if allow := rec.Header().Get("Allow"); !strings.Contains(allow, route.Method) {
+			t.Errorf("OPTIONS %s allows %q, want %s", route.Path, allow, route.Method)
+		}
+	}
+}
+
+func TestRegistrar_RejectsUnknownAuthMode(t *testing.T) {
+	reg := NewRegistrar(chi.NewRouter(), nil)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a route without an auth mode to panic")
+		}
+	}()
+	reg.Handle(Route{Method: http.MethodGet, Path: "/xrpc/social.coves.test", Handler: func(http.ResponseWriter, *http.Request) {}})
+}
+
+func TestRegistrar_RequiresAuthenticator(t *testing.T) {
+	reg := NewRegistrar(chi.NewRouter(), nil)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected an authenticated route on a registrar without auth to panic")
+		}
+	}()
+	reg.Handle(Route{Method: http.MethodGet, Path: "/xrpc/social.coves.test", Handler: func(http.ResponseWriter, *http.Request) {}, Auth: AuthRequired})
+}
+
+// TestCORS_PreflightBeforeRateLimitAndAuth mounts the server-wide CORS policy and rate limiter
+// the way cmd/server does and preflights an authenticated procedure
+func TestCORS_PreflightBeforeRateLimitAndAuth(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(middleware.NewCORS([]string{"https://app.example"}, "/xrpc/", "/oauth/").Middleware)
+	r.Use(middleware.NewRateLimiter(1, time.Minute).Middleware)
+	reg := NewRegistrar(r, fakeAuth{})
+	RegisterCommentRoutes(reg, nil, nil)
+
+	const path = "/xrpc/social.coves.community.comment.create"
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodOptions, path, nil)
+		req.Header.Set("Origin", "https://app.example")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("preflight %d returned %d, want 204", i, rec.Code)
+		}
+		if rec.Header().Get("X-Auth-Mode") != "" {
+			t.Fatalf("preflight %d went through auth", i)
+		}
+		if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example" {
+			t.Fatalf("preflight %d allowed origin %q", i, got)
+		}
+	}
+
+	// The actual request is then rate limited and authenticated as usual
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set("Origin", "https://app.example")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if got := rec.Header().Get("X-Auth-Mode"); got != string(AuthRequired) {
+		t.Errorf("POST went through %q auth, want %s", got, AuthRequired)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example" {
+		t.Errorf("POST allowed origin %q", got)
+	}
+}
@@ -2,34 +2,50 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/comments"
-	"Coves/internal/api/middleware"
 	commentsCore "Coves/internal/core/comments"
-
-	"github.com/go-chi/chi/v5"
+	"Coves/internal/core/communities"
+	"net/http"
 )
 
-// RegisterCommentRoutes registers comment-related XRPC endpoints on the router
+// RegisterCommentRoutes registers comment-related XRPC endpoints
 // Implements social.coves.community.comment.* lexicon endpoints
-// All write operations (create, update, delete) require authentication
-func RegisterCommentRoutes(r chi.Router, service commentsCore.Service, authMiddleware *middleware.OAuthAuthMiddleware) {
+// All write operations (create, update, delete) and moderator search require authentication
+// ageGate withholds comments in age-restricted communities from viewers who haven't confirmed their age.
+func RegisterCommentRoutes(reg *Registrar, service commentsCore.Service, ageGate communities.AgeGate) {
 	// Initialize handlers
+	getHandler := comments.NewGetCommentsHandler(comments.NewServiceAdapter(service))
+	if ageGate != nil {
+		getHandler.SetAgeGate(ageGate)
+	}
 	createHandler := comments.NewCreateCommentHandler(service)
 	updateHandler := comments.NewUpdateCommentHandler(service)
 	deleteHandler := comments.NewDeleteCommentHandler(service)
+	searchHandler := comments.NewSearchCommentsHandler(service)
+
+	reg.Handle(
+		// Query endpoint (GET) - optional auth for viewer state
+		// social.coves.community.comment.getComments - nested comment threads
+		// Stricter rate limit: nested thread queries are expensive
+		Route{
+			Method:    http.MethodGet,
+			Path:      "/xrpc/social.coves.community.comment.getComments",
+			Handler:   getHandler.HandleGetComments,
+			Auth:      AuthOptional,
+			RateLimit: RateLimitExpensive,
+		},
+
+		// Procedure endpoints (POST) - require authentication
+		// social.coves.community.comment.create - create a new comment on a post or another comment
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.comment.create", Handler: createHandler.HandleCreate, Auth: AuthRequired},
 
-	// Procedure endpoints (POST) - require authentication
-	// social.coves.community.comment.create - create a new comment on a post or another comment
-	r.With(authMiddleware.RequireAuth).Post(
-		"/xrpc/social.coves.community.comment.create",
-		createHandler.HandleCreate)
+		// social.coves.community.comment.update - update an existing comment's content
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.comment.update", Handler: updateHandler.HandleUpdate, Auth: AuthRequired},
 
-	// social.coves.community.comment.update - update an existing comment's content
-	r.With(authMiddleware.RequireAuth).Post(
-		"/xrpc/social.coves.community.comment.update",
-		updateHandler.HandleUpdate)
+		// social.coves.community.comment.delete - soft delete a comment
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.comment.delete", Handler: deleteHandler.HandleDelete, Auth: AuthRequired},
 
-	// social.coves.community.comment.delete - soft delete a comment
-	r.With(authMiddleware.RequireAuth).Post(
-		"/xrpc/social.coves.community.comment.delete",
-		deleteHandler.HandleDelete)
+		// Moderator tooling (GET) - requires authentication, authorized per community
+		// social.coves.community.comment.search - full-text search within a community's comments
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.community.comment.search", Handler: searchHandler.HandleSearch, Auth: AuthRequired},
+	)
 }
@@ -2,55 +2,72 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/community"
-	"Coves/internal/api/middleware"
 	"Coves/internal/core/communities"
-
-	"github.com/go-chi/chi/v5"
+	"net/http"
 )
 
-// RegisterCommunityRoutes registers community-related XRPC endpoints on the router
+// RegisterCommunityRoutes registers community-related XRPC endpoints
 // Implements social.coves.community.* lexicon endpoints
 // allowedCommunityCreators restricts who can create communities. If empty, anyone can create.
-func RegisterCommunityRoutes(r chi.Router, service communities.Service, repo communities.Repository, authMiddleware *middleware.OAuthAuthMiddleware, allowedCommunityCreators []string) {
+// ageGate flags age-restricted communities for viewers who haven't confirmed their age.
+func RegisterCommunityRoutes(reg *Registrar, service communities.Service, repo communities.Repository, allowedCommunityCreators []string, ageGate communities.AgeGate) {
 	// Initialize handlers
 	createHandler := community.NewCreateHandler(service, allowedCommunityCreators)
 	getHandler := community.NewGetHandler(service)
 	updateHandler := community.NewUpdateHandler(service)
+	updateRulesHandler := community.NewUpdateRulesHandler(service)
 	listHandler := community.NewListHandler(service, repo)
 	searchHandler := community.NewSearchHandler(service)
 	subscribeHandler := community.NewSubscribeHandler(service)
 	blockHandler := community.NewBlockHandler(service)
+	deleteHandler := community.NewDeleteHandler(service)
+	confirmAgeHandler := community.NewConfirmAgeHandler(ageGate)
+	if ageGate != nil {
+		getHandler.SetAgeGate(ageGate)
+	}
+
+	reg.Handle(
+		// Query endpoints (GET) - public access, optional auth for viewer state
+		// social.coves.community.get - get a single community by identifier
+		// Uses OptionalAuth to tell whether the viewer confirmed their age for NSFW communities
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.community.get", Handler: getHandler.HandleGet, Auth: AuthOptional},
+
+		// social.coves.community.list - list communities with filters
+		// Uses OptionalAuth to populate viewer.subscribed when authenticated
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.community.list", Handler: listHandler.HandleList, Auth: AuthOptional},
+
+		// social.coves.community.search - search communities
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.community.search", Handler: searchHandler.HandleSearch, Auth: AuthPublic},
 
-	// Query endpoints (GET) - public access, optional auth for viewer state
-	// social.coves.community.get - get a single community by identifier
-	r.Get("/xrpc/social.coves.community.get", getHandler.HandleGet)
+		// Procedure endpoints (POST) - require authentication
+		// social.coves.community.create - create a new community
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.create", Handler: createHandler.HandleCreate, Auth: AuthRequired},
 
-	// social.coves.community.list - list communities with filters
-	// Uses OptionalAuth to populate viewer.subscribed when authenticated
-	r.With(authMiddleware.OptionalAuth).Get("/xrpc/social.coves.community.list", listHandler.HandleList)
+		// social.coves.community.update - update an existing community
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.update", Handler: updateHandler.HandleUpdate, Auth: AuthRequired},
 
-	// social.coves.community.search - search communities
-	r.Get("/xrpc/social.coves.community.search", searchHandler.HandleSearch)
+		// social.coves.community.updateRules - replace a community's welcome/rules document (owner only)
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.updateRules", Handler: updateRulesHandler.HandleUpdateRules, Auth: AuthRequired},
 
-	// Procedure endpoints (POST) - require authentication
-	// social.coves.community.create - create a new community
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.community.create", createHandler.HandleCreate)
+		// social.coves.community.subscribe - subscribe to a community
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.subscribe", Handler: subscribeHandler.HandleSubscribe, Auth: AuthRequired},
 
-	// social.coves.community.update - update an existing community
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.community.update", updateHandler.HandleUpdate)
+		// social.coves.community.unsubscribe - unsubscribe from a community
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.unsubscribe", Handler: subscribeHandler.HandleUnsubscribe, Auth: AuthRequired},
 
-	// social.coves.community.subscribe - subscribe to a community
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.community.subscribe", subscribeHandler.HandleSubscribe)
+		// social.coves.community.blockCommunity - block a community
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.blockCommunity", Handler: blockHandler.HandleBlock, Auth: AuthRequired},
 
-	// social.coves.community.unsubscribe - unsubscribe from a community
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.community.unsubscribe", subscribeHandler.HandleUnsubscribe)
+		// social.coves.community.unblockCommunity - unblock a community
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.unblockCommunity", Handler: blockHandler.HandleUnblock, Auth: AuthRequired},
 
-	// social.coves.community.blockCommunity - block a community
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.community.blockCommunity", blockHandler.HandleBlock)
+		// social.coves.community.confirmAge - confirm the viewer is over 18 for an NSFW community
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.confirmAge", Handler: confirmAgeHandler.HandleConfirmAge, Auth: AuthRequired},
 
-	// social.coves.community.unblockCommunity - unblock a community
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.community.unblockCommunity", blockHandler.HandleUnblock)
+		// social.coves.community.delete - soft-delete a community (owner or instance admin)
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.delete", Handler: deleteHandler.HandleDelete, Auth: AuthRequired},
 
-	// TODO: Add delete handler when implemented
-	// r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.community.delete", deleteHandler.HandleDelete)
+		// social.coves.community.undelete - restore a deleted community within its grace period
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.undelete", Handler: deleteHandler.HandleUndelete, Auth: AuthRequired},
+	)
 }
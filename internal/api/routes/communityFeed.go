@@ -2,26 +2,33 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/communityFeed"
-	"Coves/internal/api/middleware"
+	"Coves/internal/core/aggregators"
 	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/communities"
 	"Coves/internal/core/communityFeeds"
 	"Coves/internal/core/votes"
-
-	"github.com/go-chi/chi/v5"
+	"net/http"
 )
 
 // RegisterCommunityFeedRoutes registers feed-related XRPC endpoints
 func RegisterCommunityFeedRoutes(
-	r chi.Router,
+	reg *Registrar,
 	feedService communityFeeds.Service,
 	voteService votes.Service,
 	blueskyService blueskypost.Service,
-	authMiddleware *middleware.OAuthAuthMiddleware,
+	communityRepo communities.Repository,
+	aggregatorRepo aggregators.Repository,
+	ageGate communities.AgeGate,
 ) {
 	// Create handlers
-	getCommunityHandler := communityFeed.NewGetCommunityHandler(feedService, voteService, blueskyService)
+	getCommunityHandler := communityFeed.NewGetCommunityHandler(feedService, voteService, blueskyService, communityRepo, aggregatorRepo)
+	if ageGate != nil {
+		getCommunityHandler.SetAgeGate(ageGate)
+	}
 
-	// GET /xrpc/social.coves.communityFeed.getCommunity
-	// Public endpoint with optional auth for viewer-specific state (vote state)
-	r.With(authMiddleware.OptionalAuth).Get("/xrpc/social.coves.communityFeed.getCommunity", getCommunityHandler.HandleGetCommunity)
+	reg.Handle(
+		// GET /xrpc/social.coves.communityFeed.getCommunity
+		// Public endpoint with optional auth for viewer-specific state (vote state, age confirmation)
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.communityFeed.getCommunity", Handler: getCommunityHandler.HandleGetCommunity, Auth: AuthOptional},
+	)
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"Coves/internal/api/handlers/directory"
+	directoryCore "Coves/internal/core/directory"
+	"net/http"
+)
+
+// RegisterDirectoryRoutes registers the instance-to-instance community directory endpoint
+// Public: peer instances poll it without credentials. Only public communities hosted here are listed.
+func RegisterDirectoryRoutes(reg *Registrar, service directoryCore.Service) {
+	listHandler := directory.NewListCommunitiesHandler(service)
+
+	reg.Handle(
+		// GET /xrpc/social.coves.sync.listCommunities?cursor=...
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.sync.listCommunities", Handler: listHandler.HandleListCommunities, Auth: AuthPublic},
+	)
+}
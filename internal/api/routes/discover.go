@@ -2,12 +2,13 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/discover"
-	"Coves/internal/api/middleware"
+	"Coves/internal/core/aggregators"
 	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/communities"
 	discoverCore "Coves/internal/core/discover"
+	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
-
-	"github.com/go-chi/chi/v5"
+	"net/http"
 )
 
 // RegisterDiscoverRoutes registers discover-related XRPC endpoints
@@ -18,20 +19,43 @@ import (
 // - Protected by global rate limiter: 100 requests/minute per IP (main.go:84)
 // - Query timeout enforced via context (prevents long-running queries)
 // - Result limit capped at 50 posts per request (validated in service layer)
-// - No caching currently implemented (future: 30-60s cache for hot feed)
+// - Discover feed is not cached; the front page is cached for 60s for anonymous visitors
+// - Category counts are the same for everyone and cached for 5 minutes
 func RegisterDiscoverRoutes(
-	r chi.Router,
+	reg *Registrar,
 	discoverService discoverCore.Service,
 	voteService votes.Service,
 	blueskyService blueskypost.Service,
-	authMiddleware *middleware.OAuthAuthMiddleware,
+	communityRepo communities.Repository,
+	aggregatorRepo aggregators.Repository,
+	preferencesRepo users.PreferencesRepository,
+	categoryRepo communities.CategoryRepository,
 ) {
 	// Create handlers
-	getDiscoverHandler := discover.NewGetDiscoverHandler(discoverService, voteService, blueskyService)
+	getDiscoverHandler := discover.NewGetDiscoverHandler(discoverService, voteService, blueskyService, communityRepo, aggregatorRepo)
+	getDiscoverHandler.SetPreferences(preferencesRepo)
+	getFrontPageHandler := discover.NewGetFrontPageHandler(discoverService, voteService, blueskyService, communityRepo, aggregatorRepo)
+	getDiscussionsHandler := discover.NewGetDiscussionsHandler(discoverService, voteService, blueskyService, communityRepo, aggregatorRepo)
+	getCategoriesHandler := discover.NewGetCategoriesHandler(categoryRepo)
+
+	reg.Handle(
+		// GET /xrpc/social.coves.feed.getDiscover
+		// Public endpoint with optional auth for viewer-specific state (vote state)
+		// Shows posts from ALL communities (not personalized)
+		// Rate limited: 100 req/min per IP via global middleware
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.feed.getDiscover", Handler: getDiscoverHandler.HandleGetDiscover, Auth: AuthOptional},
+
+		// GET /xrpc/social.coves.discover.getFrontPage
+		// Logged-out home page: featured communities blended with globally hot posts
+		// Falls back to discover-hot when no communities are featured
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.discover.getFrontPage", Handler: getFrontPageHandler.HandleGetFrontPage, Auth: AuthOptional},
+
+		// GET /xrpc/social.coves.feed.getDiscussions?url=...
+		// Posts in public communities linking to the URL, however it was spelled
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.feed.getDiscussions", Handler: getDiscussionsHandler.HandleGetDiscussions, Auth: AuthOptional},
 
-	// GET /xrpc/social.coves.feed.getDiscover
-	// Public endpoint with optional auth for viewer-specific state (vote state)
-	// Shows posts from ALL communities (not personalized)
-	// Rate limited: 100 req/min per IP via global middleware
-	r.With(authMiddleware.OptionalAuth).Get("/xrpc/social.coves.feed.getDiscover", getDiscoverHandler.HandleGetDiscover)
+		// GET /xrpc/social.coves.discover.getCategories
+		// The community category taxonomy with community counts, for browsing by category
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.discover.getCategories", Handler: getCategoriesHandler.HandleGetCategories, Auth: AuthPublic},
+	)
 }
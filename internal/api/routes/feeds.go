@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"Coves/internal/api/handlers/feeds"
+	"net/http"
+)
+
+// RegisterFeedRoutes registers the public RSS/Atom feeds
+// Rendered feeds are cached for feeds.FeedCacheTTL; the feed rate limit tier keeps misses
+// (and lookups of communities that don't exist) from hammering the database
+func RegisterFeedRoutes(reg *Registrar, handler *feeds.Handler) {
+	reg.Handle(
+		// GET /feeds/community/{handle}.xml
+		// Handles contain dots, so the .xml suffix is stripped by the handler
+		Route{Method: http.MethodGet, Path: "/feeds/community/{file}", Handler: handler.HandleCommunityFeed, Auth: AuthPublic, RateLimit: RateLimitFeed},
+
+		// GET /feeds/discover.xml
+		Route{Method: http.MethodGet, Path: "/feeds/discover.xml", Handler: handler.HandleDiscoverFeed, Auth: AuthPublic, RateLimit: RateLimitFeed},
+	)
+}
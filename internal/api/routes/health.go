@@ -0,0 +1,71 @@
+package routes
+
+import (
+	"Coves/internal/db/migrate"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+)
+
+// SchemaStatusProvider reports whether the database schema has pending migrations
+// Implemented by *migrate.Runner.
+type SchemaStatusProvider interface {
+	Status(ctx context.Context) (*migrate.Status, error)
+}
+
+// RegisterHealthRoutes registers the liveness checks used by load balancers and docker-compose,
+// and the readiness check that also reports pending migrations
+func RegisterHealthRoutes(reg *Registrar, schema SchemaStatusProvider) {
+	reg.Handle(
+		Route{Method: http.MethodGet, Path: "/health", Handler: handleHealth, Auth: AuthPublic},
+		Route{Method: http.MethodGet, Path: "/xrpc/_health", Handler: handleHealth, Auth: AuthPublic},
+		Route{Method: http.MethodGet, Path: "/health/ready", Handler: handleReady(schema), Auth: AuthPublic},
+	)
+}
+
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("OK")); err != nil {
+		log.Printf("Failed to write health check response: %v", err)
+	}
+}
+
+// readyResponse is the body of /health/ready
+type readyResponse struct {
+	Schema *migrate.Status `json:"schema,omitempty"`
+	Status string          `json:"status"`
+}
+
+// handleReady is ready when the database answers and its schema is the one the binary expects.
+// Any pending migration makes it unready; one held for an operator because it's destructive
+// is reported as migrations_pending so deploys can tell it from a version mismatch.
+func handleReady(schema SchemaStatusProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		response := readyResponse{Status: "ready"}
+		code := http.StatusOK
+
+		status, err := schema.Status(r.Context())
+		if err != nil {
+			log.Printf("Readiness check failed: %v", err)
+			response.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+		} else {
+			response.Schema = status
+			if versionErr := status.CheckVersion(); versionErr != nil {
+				response.Status = "schema_mismatch"
+				if errors.Is(versionErr, migrate.ErrDestructivePending) {
+					response.Status = "migrations_pending"
+				}
+				code = http.StatusServiceUnavailable
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			log.Printf("Failed to write readiness response: %v", err)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package routes
+
+import (
+	"Coves/internal/db/migrate"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// stubSchema reports a fixed schema status
+type stubSchema struct {
+	status *migrate.Status
+	err    error
+}
+
+func (s stubSchema) Status(ctx context.Context) (*migrate.Status, error) {
+	return s.status, s.err
+}
+
+func TestHandleReady(t *testing.T) {
+	migrations := []migrate.Migration{
+		{Name: "001_a.sql", Version: 1},
+		{Name: "002_drop_b.sql", Version: 2, Destructive: true},
+		{Name: "003_c.sql", Version: 3},
+	}
+
+	tests := []struct {
+		name       string
+		schema     stubSchema
+		wantCode   int
+		wantStatus string
+	}{
+		{name: "up to date", schema: stubSchema{status: migrate.NewStatus(migrations, 3)}, wantCode: http.StatusOK, wantStatus: "ready"},
+		{name: "held by a destructive migration", schema: stubSchema{status: migrate.NewStatus(migrations, 1)}, wantCode: http.StatusServiceUnavailable, wantStatus: "migrations_pending"},
+		{name: "behind with safe migrations pending", schema: stubSchema{status: migrate.NewStatus(migrations, 0)}, wantCode: http.StatusServiceUnavailable, wantStatus: "schema_mismatch"},
+		{name: "database unavailable", schema: stubSchema{err: errors.New("connection refused")}, wantCode: http.StatusServiceUnavailable, wantStatus: "unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handleReady(tt.schema)(w, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+
+			if w.Code != tt.wantCode {
+				t.Errorf("Expected status %d, got %d", tt.wantCode, w.Code)
+			}
+			var body readyResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if body.Status != tt.wantStatus {
+				t.Errorf("Expected %q, got %q", tt.wantStatus, body.Status)
+			}
+		})
+	}
+}
@@ -1,12 +1,12 @@
 package routes
 
 import (
-	"github.com/go-chi/chi/v5"
+	"net/http"
 
 	imageproxyhandlers "Coves/internal/api/handlers/imageproxy"
 )
 
-// RegisterImageProxyRoutes registers image proxy endpoints on the router.
+// RegisterImageProxyRoutes registers image proxy endpoints.
 // The image proxy serves transformed images from AT Protocol PDSes.
 //
 // Route: GET /img/{preset}/plain/{did}/{cid}
@@ -16,7 +16,14 @@ import (
 //   - did: DID of the user who owns the blob
 //   - cid: Content identifier of the blob
 //
-// The endpoint supports ETag-based caching with If-None-Match headers.
-func RegisterImageProxyRoutes(r chi.Router, handler *imageproxyhandlers.Handler) {
-	r.Get("/img/{preset}/plain/{did}/{cid}", handler.HandleImage)
+// Route: GET /img/identicon/{did}
+//
+// Serves the generated fallback avatar (SVG) for a user or community without an avatar blob.
+//
+// Both endpoints support ETag-based caching with If-None-Match headers.
+func RegisterImageProxyRoutes(reg *Registrar, handler *imageproxyhandlers.Handler) {
+	reg.Handle(
+		Route{Method: http.MethodGet, Path: "/img/{preset}/plain/{did}/{cid}", Handler: handler.HandleImage, Auth: AuthPublic},
+		Route{Method: http.MethodGet, Path: "/img/identicon/{did}", Handler: handler.HandleIdenticon, Auth: AuthPublic},
+	)
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	instanceHandlers "Coves/internal/api/handlers/instance"
+	"Coves/internal/core/instance"
+	"net/http"
+)
+
+// RegisterInstanceRoutes registers the public instance statistics and NodeInfo endpoints
+// Public: the stats back the transparency page and fediverse crawlers read NodeInfo.
+// The counts are cached by the service, so requests never run the aggregate queries directly.
+func RegisterInstanceRoutes(reg *Registrar, service instance.Service, baseURL, nodeName string) {
+	handler := instanceHandlers.NewHandler(service, baseURL, nodeName)
+
+	reg.Handle(
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.instance.getStats", Handler: handler.HandleGetStats, Auth: AuthPublic},
+
+		// NodeInfo discovery, then the document it links to
+		// Spec: https://github.com/jhass/nodeinfo/blob/main/PROTOCOL.md
+		Route{Method: http.MethodGet, Path: "/.well-known/nodeinfo", Handler: handler.HandleNodeInfoLinks, Auth: AuthPublic},
+		Route{Method: http.MethodGet, Path: instance.NodeInfoPath, Handler: handler.HandleNodeInfo, Auth: AuthPublic},
+	)
+}
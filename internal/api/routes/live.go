@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"Coves/internal/api/handlers/live"
+	"net/http"
+)
+
+// RegisterLiveRoutes registers the server-sent events stream of newly indexed content
+func RegisterLiveRoutes(reg *Registrar, handler *live.SubscribeHandler) {
+	reg.Handle(
+		// GET /xrpc/social.coves.sync.subscribe
+		// Optional auth: the timeline topic needs the viewer's subscriptions
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.sync.subscribe", Handler: handler.HandleSubscribe, Auth: AuthOptional},
+	)
+}
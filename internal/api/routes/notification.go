@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"Coves/internal/api/handlers/notification"
+	"Coves/internal/core/notifications"
+	"net/http"
+)
+
+// RegisterNotificationRoutes registers notification XRPC endpoints
+func RegisterNotificationRoutes(reg *Registrar, service notifications.Service) {
+	handler := notification.NewHandler(service)
+
+	reg.Handle(
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.notification.listNotifications", Handler: handler.HandleListNotifications, Auth: AuthRequired},
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.notification.updateSeen", Handler: handler.HandleUpdateSeen, Auth: AuthRequired},
+	)
+}
@@ -2,31 +2,63 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/post"
-	"Coves/internal/api/middleware"
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
 	"Coves/internal/core/posts"
-
-	"github.com/go-chi/chi/v5"
+	"Coves/internal/core/votes"
+	"net/http"
 )
 
-// RegisterPostRoutes registers post-related XRPC endpoints on the router
+// RegisterPostRoutes registers post-related XRPC endpoints
 // Implements social.coves.community.post.* lexicon endpoints
-// authMiddleware can be either OAuthAuthMiddleware or DualAuthMiddleware
-func RegisterPostRoutes(r chi.Router, service posts.Service, authMiddleware middleware.AuthMiddleware) {
+func RegisterPostRoutes(reg *Registrar, service posts.Service) {
 	// Initialize handlers
 	createHandler := post.NewCreateHandler(service)
 	deleteHandler := post.NewDeleteHandler(service)
+	checkDuplicateHandler := post.NewCheckDuplicateHandler(service)
+
+	reg.Handle(
+		// Procedure endpoints (POST) - require authentication
+		// social.coves.community.post.create - create a new post in a community
+		// Supports both OAuth (users) and service JWT / API key (aggregators) authentication
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.post.create", Handler: createHandler.HandleCreate, Auth: AuthService},
 
-	// Procedure endpoints (POST) - require authentication
-	// social.coves.community.post.create - create a new post in a community
-	// Supports both OAuth (users) and service JWT (aggregators) authentication
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.community.post.create", createHandler.HandleCreate)
+		// social.coves.community.post.delete - delete a post from a community
+		// Only post authors can delete their own posts
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.post.delete", Handler: deleteHandler.HandleDelete, Auth: AuthService},
 
-	// social.coves.community.post.delete - delete a post from a community
-	// Only post authors can delete their own posts
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.community.post.delete", deleteHandler.HandleDelete)
+		// GET /xrpc/social.coves.feed.checkDuplicate?community=...&url=...&title=...
+		// Composer's "already posted here" hint; rate limited since clients call it while typing
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.feed.checkDuplicate", Handler: checkDuplicateHandler.HandleCheckDuplicate, Auth: AuthRequired, RateLimit: RateLimitComposer},
+	)
 
 	// Future endpoints (Beta):
-	// r.Get("/xrpc/social.coves.community.post.get", getHandler.HandleGet)
-	// r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.community.post.update", updateHandler.HandleUpdate)
-	// r.Get("/xrpc/social.coves.community.post.list", listHandler.HandleList)
+	// social.coves.community.post.update (required)
+	// social.coves.community.post.list (public)
+}
+
+// RegisterGetPostRoutes registers the single post (permalink) endpoint
+// It hydrates the post like the feeds do, so it takes the same lookups as the actor routes.
+func RegisterGetPostRoutes(
+	reg *Registrar,
+	postService posts.Service,
+	commentService comments.Service,
+	voteService votes.Service,
+	blueskyService blueskypost.Service,
+	communityRepo communities.Repository,
+	aggregatorRepo aggregators.Repository,
+	ageGate communities.AgeGate,
+) {
+	getHandler := post.NewGetHandler(postService, commentService, voteService, blueskyService, communityRepo, aggregatorRepo)
+	if ageGate != nil {
+		getHandler.SetAgeGate(ageGate)
+	}
+
+	reg.Handle(
+		// GET /xrpc/social.coves.feed.getPost
+		// Public endpoint with optional auth for viewer-specific state (vote state, author_only posts, age confirmation)
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.feed.getPost", Handler: getHandler.HandleGetPost, Auth: AuthOptional},
+	)
 }
@@ -0,0 +1,209 @@
+package routes
+
+import (
+	"Coves/internal/api/middleware"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AuthMode is how a route treats the caller's credentials
+type AuthMode string
+
+const (
+	AuthPublic   AuthMode = "public"   // Credentials are never read
+	AuthOptional AuthMode = "optional" // Viewer state when signed in; anonymous otherwise
+	AuthRequired AuthMode = "required" // OAuth session required
+	AuthService  AuthMode = "service"  // OAuth session, service JWT or aggregator API key required
+)
+
+// RateLimitTier selects a per-IP limiter applied on top of the global one
+// Routes in the same tier share one limiter.
+type RateLimitTier string
+
+const (
+	RateLimitGlobal       RateLimitTier = ""             // Global limiter only
+	RateLimitExpensive    RateLimitTier = "expensive"    // Nested comment queries
+	RateLimitFeed         RateLimitTier = "feed"         // RSS/Atom feeds; readers poll far less often
+	RateLimitLogin        RateLimitTier = "login"        // OAuth login and callback (credential stuffing)
+	RateLimitLogout       RateLimitTier = "logout"       // OAuth logout
+	RateLimitRefresh      RateLimitTier = "refresh"      // OAuth token refresh
+	RateLimitRegistration RateLimitTier = "registration" // Aggregator self-registration
+	RateLimitTranslate    RateLimitTier = "translate"    // Machine translation; uncached requests call an external backend
+	RateLimitDraft        RateLimitTier = "draft"        // Comment draft autosave, so drafts aren't used as free storage
+	RateLimitComposer     RateLimitTier = "composer"     // Composer hints called while typing, e.g. the duplicate post check
+)
+
+// rateLimitTiers are the per-IP limits for each tier
+var rateLimitTiers = map[RateLimitTier]struct {
+	requests int
+	window   time.Duration
+}{
+	RateLimitExpensive:    {20, time.Minute},
+	RateLimitFeed:         {60, time.Minute},
+	RateLimitLogin:        {10, time.Minute},
+	RateLimitLogout:       {10, time.Minute},
+	RateLimitRefresh:      {20, time.Minute},
+	RateLimitRegistration: {10, 10 * time.Minute},
+	RateLimitTranslate:    {20, time.Minute},
+	RateLimitDraft:        {30, time.Minute},
+	RateLimitComposer:     {30, time.Minute},
+}
+
+// Route is one row of a route table
+type Route struct {
+	Handler   http.HandlerFunc
+	Method    string
+	Path      string
+	Auth      AuthMode
+	RateLimit RateLimitTier
+
+	// Use wraps the route outside its rate limit and auth, e.g. a route-specific CORS policy
+	Use []func(http.Handler) http.Handler
+}
+
+// Authenticator supplies the middlewares behind AuthRequired and AuthOptional routes
+type Authenticator interface {
+	RequireAuth(next http.Handler) http.Handler
+	OptionalAuth(next http.Handler) http.Handler
+}
+
+// Registrar mounts route tables on a router, applying each route's auth mode and rate limit
+// tier, and remembers every route so the auth matrix can be audited
+type Registrar struct {
+	router      chi.Router
+	auth        Authenticator
+	serviceAuth middleware.AuthMiddleware
+	limiters    map[RateLimitTier]*middleware.RateLimiter
+	preflights  map[string]*preflight
+	routes      []Route
+	mu          sync.Mutex
+}
+
+// NewRegistrar creates a registrar for r
+// auth may be nil when only public routes are registered. AuthService routes use auth too
+// until SetServiceAuth is called.
+func NewRegistrar(r chi.Router, auth Authenticator) *Registrar {
+	return &Registrar{
+		router:     r,
+		auth:       auth,
+		limiters:   make(map[RateLimitTier]*middleware.RateLimiter),
+		preflights: make(map[string]*preflight),
+	}
+}
+
+// SetServiceAuth sets the middleware for AuthService routes (OAuth, service JWT or API key)
+func (reg *Registrar) SetServiceAuth(serviceAuth middleware.AuthMiddleware) {
+	reg.serviceAuth = serviceAuth
+}
+
+// Handle mounts the routes
+// A route with an unknown auth mode or rate limit tier, or one needing auth the registrar
+// can't provide, panics: it is a wiring bug that must not start serving.
+func (reg *Registrar) Handle(routes ...Route) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	for _, route := range routes {
+		if route.Handler == nil || route.Method == "" || route.Path == "" {
+			panic(fmt.Sprintf("routes: incomplete route %s %s", route.Method, route.Path))
+		}
+
+		h := reg.withAuth(route)
+		if route.RateLimit != RateLimitGlobal {
+			h = reg.limiter(route).Middleware(h)
+		}
+		for i := len(route.Use) - 1; i >= 0; i-- {
+			h = route.Use[i](h)
+		}
+
+		reg.router.Method(route.Method, route.Path, h)
+		reg.routes = append(reg.routes, route)
+
+		if strings.HasPrefix(route.Path, "/xrpc/") {
+			reg.allowPreflight(route.Method, route.Path)
+		}
+	}
+}
+
+// Routes returns every route registered so far, in registration order
+func (reg *Registrar) Routes() []Route {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	return append([]Route(nil), reg.routes...)
+}
+
+func (reg *Registrar) withAuth(route Route) http.Handler {
+	h := http.Handler(route.Handler)
+	switch route.Auth {
+	case AuthPublic:
+		return h
+	case AuthOptional, AuthRequired:
+		if reg.auth == nil {
+			panic(fmt.Sprintf("routes: %s %s is %s auth but the registrar has no authenticator", route.Method, route.Path, route.Auth))
+		}
+		if route.Auth == AuthOptional {
+			return reg.auth.OptionalAuth(h)
+		}
+		return reg.auth.RequireAuth(h)
+	case AuthService:
+		if reg.serviceAuth != nil {
+			return reg.serviceAuth.RequireAuth(h)
+		}
+		if reg.auth == nil {
+			panic(fmt.Sprintf("routes: %s %s is service auth but the registrar has no authenticator", route.Method, route.Path))
+		}
+		return reg.auth.RequireAuth(h)
+	default:
+		panic(fmt.Sprintf("routes: %s %s has unknown auth mode %q", route.Method, route.Path, route.Auth))
+	}
+}
+
+func (reg *Registrar) limiter(route Route) *middleware.RateLimiter {
+	if limiter, ok := reg.limiters[route.RateLimit]; ok {
+		return limiter
+	}
+	tier, ok := rateLimitTiers[route.RateLimit]
+	if !ok {
+		panic(fmt.Sprintf("routes: %s %s has unknown rate limit tier %q", route.Method, route.Path, route.RateLimit))
+	}
+	limiter := middleware.NewRateLimiter(tier.requests, tier.window)
+	reg.limiters[route.RateLimit] = limiter
+	return limiter
+}
+
+// preflight answers OPTIONS for one XRPC path with the methods registered on it
+type preflight struct {
+	mu      sync.RWMutex
+	methods []string
+}
+
+// allowPreflight registers (once per path) the OPTIONS handler shared by every XRPC route
+// It answers before auth and per-route rate limits with the methods the path accepts.
+// Browser preflights from allowed origins are answered earlier by middleware.CORS.
+func (reg *Registrar) allowPreflight(method, path string) {
+	p, ok := reg.preflights[path]
+	if !ok {
+		p = &preflight{}
+		reg.preflights[path] = p
+		reg.router.Options(path, p.ServeHTTP)
+	}
+	p.mu.Lock()
+	p.methods = append(p.methods, method)
+	sort.Strings(p.methods)
+	p.mu.Unlock()
+}
+
+func (p *preflight) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.RLock()
+	allowed := strings.Join(append(append([]string(nil), p.methods...), http.MethodOptions), ", ")
+	p.mu.RUnlock()
+
+	w.Header().Set("Allow", allowed)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"Coves/internal/api/handlers/resolve"
+	"Coves/internal/core/permalinks"
+	"net/http"
+)
+
+// RegisterResolveRoutes registers the AT-URI to web path resolver
+func RegisterResolveRoutes(reg *Registrar, service permalinks.Service) {
+	handler := resolve.NewURIHandler(service)
+
+	reg.Handle(
+		// GET /xrpc/social.coves.resolve.uri?uri=...
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.resolve.uri", Handler: handler.HandleResolveURI, Auth: AuthPublic},
+	)
+}
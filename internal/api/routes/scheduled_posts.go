@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"Coves/internal/api/handlers/community"
+	"Coves/internal/core/scheduledposts"
+	"net/http"
+)
+
+// RegisterScheduledPostRoutes registers the post scheduling endpoints
+// Require authentication; the service only lets community moderators through.
+func RegisterScheduledPostRoutes(reg *Registrar, service scheduledposts.Service) {
+	handler := community.NewSchedulePostHandler(service)
+
+	reg.Handle(
+		// social.coves.community.schedulePost - schedule a post, optionally weekly
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.schedulePost", Handler: handler.HandleSchedulePost, Auth: AuthRequired},
+
+		// social.coves.community.listScheduledPosts - a community's pending and failed scheduled posts
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.community.listScheduledPosts", Handler: handler.HandleListScheduledPosts, Auth: AuthRequired},
+
+		// social.coves.community.cancelScheduledPost - stop a scheduled post from being published
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.community.cancelScheduledPost", Handler: handler.HandleCancelScheduledPost, Auth: AuthRequired},
+	)
+}
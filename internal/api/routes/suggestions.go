@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"Coves/internal/api/handlers/discover"
+	"Coves/internal/core/suggestions"
+	"net/http"
+)
+
+// RegisterSuggestionRoutes registers the community suggestions endpoint
+// Requires authentication: suggestions are read from the viewer's Bluesky follows on their PDS.
+// Results are cached per viewer for an hour, so the global rate limit is enough.
+func RegisterSuggestionRoutes(reg *Registrar, service suggestions.Service) {
+	handler := discover.NewGetSuggestedCommunitiesHandler(service)
+
+	reg.Handle(
+		// GET /xrpc/social.coves.discover.getSuggestedCommunities?limit=20
+		Route{
+			Method:  http.MethodGet,
+			Path:    "/xrpc/social.coves.discover.getSuggestedCommunities",
+			Handler: handler.HandleGetSuggestedCommunities,
+			Auth:    AuthRequired,
+		},
+	)
+}
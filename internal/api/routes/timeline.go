@@ -2,26 +2,32 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/timeline"
-	"Coves/internal/api/middleware"
+	"Coves/internal/core/aggregators"
 	"Coves/internal/core/blueskypost"
+	"Coves/internal/core/communities"
 	timelineCore "Coves/internal/core/timeline"
+	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
-
-	"github.com/go-chi/chi/v5"
+	"net/http"
 )
 
 // RegisterTimelineRoutes registers timeline-related XRPC endpoints
 func RegisterTimelineRoutes(
-	r chi.Router,
+	reg *Registrar,
 	timelineService timelineCore.Service,
 	voteService votes.Service,
 	blueskyService blueskypost.Service,
-	authMiddleware *middleware.OAuthAuthMiddleware,
+	communityRepo communities.Repository,
+	aggregatorRepo aggregators.Repository,
+	preferencesRepo users.PreferencesRepository,
 ) {
 	// Create handlers
-	getTimelineHandler := timeline.NewGetTimelineHandler(timelineService, voteService, blueskyService)
+	getTimelineHandler := timeline.NewGetTimelineHandler(timelineService, voteService, blueskyService, communityRepo, aggregatorRepo)
+	getTimelineHandler.SetPreferences(preferencesRepo)
 
-	// GET /xrpc/social.coves.feed.getTimeline
-	// Requires authentication - user must be logged in to see their timeline
-	r.With(authMiddleware.RequireAuth).Get("/xrpc/social.coves.feed.getTimeline", getTimelineHandler.HandleGetTimeline)
+	reg.Handle(
+		// GET /xrpc/social.coves.feed.getTimeline
+		// Requires authentication - user must be logged in to see their timeline
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.feed.getTimeline", Handler: getTimelineHandler.HandleGetTimeline, Auth: AuthRequired},
+	)
 }
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"Coves/internal/api/handlers/translation"
+	translationCore "Coves/internal/core/translation"
+	"net/http"
+)
+
+// RegisterTranslationRoutes registers the translate-on-demand endpoint
+// Requires authentication and has its own rate limit: every uncached request calls the
+// external translation backend.
+func RegisterTranslationRoutes(reg *Registrar, service translationCore.Service) {
+	handler := translation.NewTranslateHandler(service)
+
+	reg.Handle(
+		// GET /xrpc/social.coves.feed.translate?uri=...&lang=...
+		Route{
+			Method:    http.MethodGet,
+			Path:      "/xrpc/social.coves.feed.translate",
+			Handler:   handler.HandleTranslate,
+			Auth:      AuthRequired,
+			RateLimit: RateLimitTranslate,
+		},
+	)
+}
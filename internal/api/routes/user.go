@@ -2,7 +2,7 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/user"
-	"Coves/internal/api/middleware"
+	"Coves/internal/api/xrpcerror"
 	"Coves/internal/core/users"
 	"encoding/json"
 	"errors"
@@ -11,7 +11,6 @@ import (
 	"strings"
 
 	"github.com/bluesky-social/indigo/atproto/auth/oauth"
-	"github.com/go-chi/chi/v5"
 )
 
 // UserHandler handles user-related XRPC endpoints
@@ -35,31 +34,22 @@ type UserRouteOptions struct {
 	PDSClientFactory user.PDSClientFactory
 }
 
-// RegisterUserRoutes registers user-related XRPC endpoints on the router
+// RegisterUserRoutes registers user-related XRPC endpoints
 // Implements social.coves.actor.* lexicon endpoints
-func RegisterUserRoutes(r chi.Router, service users.UserService, authMiddleware *middleware.OAuthAuthMiddleware, oauthClient *oauth.ClientApp) {
-	RegisterUserRoutesWithOptions(r, service, authMiddleware, oauthClient, nil)
+func RegisterUserRoutes(reg *Registrar, service users.UserService, oauthClient *oauth.ClientApp) {
+	RegisterUserRoutesWithOptions(reg, service, oauthClient, nil)
 }
 
 // RegisterUserRoutesWithOptions registers user-related XRPC endpoints with optional configuration.
 // Use opts to inject test dependencies like custom PDS client factories.
-func RegisterUserRoutesWithOptions(r chi.Router, service users.UserService, authMiddleware *middleware.OAuthAuthMiddleware, oauthClient *oauth.ClientApp, opts *UserRouteOptions) {
+func RegisterUserRoutesWithOptions(reg *Registrar, service users.UserService, oauthClient *oauth.ClientApp, opts *UserRouteOptions) {
 	h := NewUserHandler(service)
 
-	// social.coves.actor.getprofile - query endpoint (public)
-	r.Get("/xrpc/social.coves.actor.getprofile", h.GetProfile)
-
-	// social.coves.actor.signup - procedure endpoint (public)
-	r.Post("/xrpc/social.coves.actor.signup", h.Signup)
-
-	// social.coves.actor.deleteAccount - procedure endpoint (authenticated)
-	// Deletes the authenticated user's account from the Coves AppView.
+	// social.coves.actor.deleteAccount deletes the authenticated user's account from the Coves AppView.
 	// This ONLY deletes AppView indexed data, NOT the user's atProto identity on their PDS.
 	deleteHandler := user.NewDeleteHandler(service)
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.actor.deleteAccount", deleteHandler.HandleDeleteAccount)
 
-	// social.coves.actor.updateProfile - procedure endpoint (authenticated)
-	// Updates the authenticated user's profile on their PDS (avatar, banner, displayName, bio).
+	// social.coves.actor.updateProfile updates the authenticated user's profile on their PDS (avatar, banner, displayName, bio).
 	// This writes directly to the user's PDS and the Jetstream consumer will index the change.
 	var updateProfileHandler *user.UpdateProfileHandler
 	if opts != nil && opts.PDSClientFactory != nil {
@@ -69,7 +59,42 @@ func RegisterUserRoutesWithOptions(r chi.Router, service users.UserService, auth
 		// Use OAuth client for DPoP-authenticated PDS requests (production)
 		updateProfileHandler = user.NewUpdateProfileHandler(oauthClient)
 	}
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.actor.updateProfile", updateProfileHandler.ServeHTTP)
+
+	reg.Handle(
+		// social.coves.actor.getprofile - query endpoint (public)
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.actor.getprofile", Handler: h.GetProfile, Auth: AuthPublic},
+
+		// social.coves.actor.signup - procedure endpoint (public)
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.actor.signup", Handler: h.Signup, Auth: AuthPublic},
+
+		// social.coves.actor.deleteAccount - procedure endpoint (authenticated)
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.actor.deleteAccount", Handler: deleteHandler.HandleDeleteAccount, Auth: AuthRequired},
+
+		// social.coves.actor.updateProfile - procedure endpoint (authenticated)
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.actor.updateProfile", Handler: updateProfileHandler.ServeHTTP, Auth: AuthRequired},
+	)
+}
+
+// RegisterPreferencesRoutes registers the viewer preferences endpoints (authenticated)
+// Preferences are written to the user's PDS, so opts may inject a PDS client factory like the
+// profile routes.
+func RegisterPreferencesRoutes(reg *Registrar, repo users.PreferencesRepository, oauthClient *oauth.ClientApp, opts *UserRouteOptions) {
+	var h *user.PreferencesHandler
+	if opts != nil && opts.PDSClientFactory != nil {
+		h = user.NewPreferencesHandlerWithFactory(repo, opts.PDSClientFactory)
+	} else {
+		h = user.NewPreferencesHandler(repo, oauthClient)
+	}
+
+	reg.Handle(
+		// social.coves.actor.getPreferences - query endpoint
+		// Stored preferences merged over server defaults
+		Route{Method: http.MethodGet, Path: "/xrpc/social.coves.actor.getPreferences", Handler: h.HandleGet, Auth: AuthRequired},
+
+		// social.coves.actor.putPreferences - procedure endpoint
+		// Writes the preferences record to the user's PDS
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.actor.putPreferences", Handler: h.HandlePut, Auth: AuthRequired},
+	)
 }
 
 // GetProfile handles social.coves.actor.getprofile
@@ -81,7 +106,7 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	// Get actor parameter (DID or handle)
 	actor := r.URL.Query().Get("actor")
 	if actor == "" {
-		writeXRPCError(w, "InvalidRequest", "actor parameter is required", http.StatusBadRequest)
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "actor parameter is required")
 		return
 	}
 
@@ -93,7 +118,7 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 		// Resolve handle to DID
 		resolvedDID, err := h.userService.ResolveHandleToDID(ctx, actor)
 		if err != nil {
-			writeXRPCError(w, "ProfileNotFound", "user not found", http.StatusNotFound)
+			xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.ProfileNotFound, "user not found")
 			return
 		}
 		did = resolvedDID
@@ -103,11 +128,11 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	profile, err := h.userService.GetProfile(ctx, did)
 	if err != nil {
 		if errors.Is(err, users.ErrUserNotFound) {
-			writeXRPCError(w, "ProfileNotFound", "user not found", http.StatusNotFound)
+			xrpcerror.WriteError(w, http.StatusNotFound, xrpcerror.ProfileNotFound, "user not found")
 			return
 		}
 		log.Printf("Failed to get profile for %s: %v", did, err)
-		writeXRPCError(w, "InternalError", "failed to get profile", http.StatusInternalServerError)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "failed to get profile")
 		return
 	}
 
@@ -115,7 +140,7 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	responseBytes, err := json.Marshal(profile)
 	if err != nil {
 		log.Printf("Failed to marshal profile response: %v", err)
-		writeXRPCError(w, "InternalError", "failed to encode response", http.StatusInternalServerError)
+		xrpcerror.WriteError(w, http.StatusInternalServerError, xrpcerror.InternalServerError, "failed to encode response")
 		return
 	}
 
@@ -125,18 +150,6 @@ func (h *UserHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// writeXRPCError writes a standardized XRPC error response
-func writeXRPCError(w http.ResponseWriter, errorName, message string, statusCode int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"error":   errorName,
-		"message": message,
-	}); err != nil {
-		log.Printf("Failed to encode error response: %v", err)
-	}
-}
-
 // Signup handles social.coves.actor.signup
 // Procedure endpoint that registers a new account on the Coves instance
 func (h *UserHandler) Signup(w http.ResponseWriter, r *http.Request) {
@@ -145,7 +158,7 @@ func (h *UserHandler) Signup(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req users.RegisterAccountRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+		xrpcerror.WriteError(w, http.StatusBadRequest, xrpcerror.InvalidRequest, "invalid request body")
 		return
 	}
 
@@ -192,49 +205,41 @@ func respondWithLexiconError(w http.ResponseWriter, err error) {
 	switch {
 	case errors.As(err, &invalidHandleErr):
 		statusCode = http.StatusBadRequest
-		errorName = "InvalidHandle"
+		errorName = xrpcerror.InvalidHandle
 		message = invalidHandleErr.Error()
 
 	case errors.As(err, &handleNotAvailableErr):
 		statusCode = http.StatusBadRequest
-		errorName = "HandleNotAvailable"
+		errorName = xrpcerror.HandleNotAvailable
 		message = handleNotAvailableErr.Error()
 
 	case errors.As(err, &invalidInviteCodeErr):
 		statusCode = http.StatusBadRequest
-		errorName = "InvalidInviteCode"
+		errorName = xrpcerror.InvalidInviteCode
 		message = invalidInviteCodeErr.Error()
 
 	case errors.As(err, &invalidEmailErr):
 		statusCode = http.StatusBadRequest
-		errorName = "InvalidEmail"
+		errorName = xrpcerror.InvalidEmail
 		message = invalidEmailErr.Error()
 
 	case errors.As(err, &weakPasswordErr):
 		statusCode = http.StatusBadRequest
-		errorName = "WeakPassword"
+		errorName = xrpcerror.WeakPassword
 		message = weakPasswordErr.Error()
 
 	case errors.As(err, &pdsErr):
 		// PDS errors get mapped based on status code
 		statusCode = pdsErr.StatusCode
-		errorName = "PDSError"
+		errorName = xrpcerror.PDSError
 		message = pdsErr.Message
 
 	default:
 		// Generic error handling (avoid leaking internal details)
 		statusCode = http.StatusInternalServerError
-		errorName = "InternalServerError"
+		errorName = xrpcerror.InternalServerError
 		message = "An error occurred while processing your request"
 	}
 
-	// XRPC error response format
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(statusCode)
-	if err := json.NewEncoder(w).Encode(map[string]interface{}{
-		"error":   errorName,
-		"message": message,
-	}); err != nil {
-		log.Printf("Failed to encode error response: %v", err)
-	}
+	xrpcerror.WriteError(w, statusCode, errorName, message)
 }
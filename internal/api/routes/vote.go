@@ -2,23 +2,23 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/vote"
-	"Coves/internal/api/middleware"
 	"Coves/internal/core/votes"
-
-	"github.com/go-chi/chi/v5"
+	"net/http"
 )
 
-// RegisterVoteRoutes registers vote-related XRPC endpoints on the router
+// RegisterVoteRoutes registers vote-related XRPC endpoints
 // Implements social.coves.feed.vote.* lexicon endpoints
-func RegisterVoteRoutes(r chi.Router, voteService votes.Service, authMiddleware *middleware.OAuthAuthMiddleware) {
+func RegisterVoteRoutes(reg *Registrar, voteService votes.Service) {
 	// Initialize handlers
 	createHandler := vote.NewCreateVoteHandler(voteService)
 	deleteHandler := vote.NewDeleteVoteHandler(voteService)
 
-	// Procedure endpoints (POST) - require authentication
-	// social.coves.feed.vote.create - create or update a vote on a post/comment
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.feed.vote.create", createHandler.HandleCreateVote)
+	reg.Handle(
+		// Procedure endpoints (POST) - require authentication
+		// social.coves.feed.vote.create - create or update a vote on a post/comment
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.feed.vote.create", Handler: createHandler.HandleCreateVote, Auth: AuthRequired},
 
-	// social.coves.feed.vote.delete - delete a vote from a post/comment
-	r.With(authMiddleware.RequireAuth).Post("/xrpc/social.coves.feed.vote.delete", deleteHandler.HandleDeleteVote)
+		// social.coves.feed.vote.delete - delete a vote from a post/comment
+		Route{Method: http.MethodPost, Path: "/xrpc/social.coves.feed.vote.delete", Handler: deleteHandler.HandleDeleteVote, Auth: AuthRequired},
+	)
 }
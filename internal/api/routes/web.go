@@ -3,8 +3,6 @@ package routes
 import (
 	"net/http"
 
-	"github.com/go-chi/chi/v5"
-
 	"Coves/internal/atproto/oauth"
 	"Coves/internal/core/users"
 	"Coves/internal/web"
@@ -12,7 +10,8 @@ import (
 
 // RegisterWebRoutes registers all web page routes for the Coves frontend.
 // This includes the landing page, account deletion flow, and static assets.
-func RegisterWebRoutes(r chi.Router, oauthClient *oauth.OAuthClient, userService users.UserService) {
+// The pages manage their own OAuth session cookies, so they're registered as public.
+func RegisterWebRoutes(reg *Registrar, oauthClient *oauth.OAuthClient, userService users.UserService) {
 	// Initialize templates
 	templates, err := web.NewTemplates()
 	if err != nil {
@@ -22,21 +21,22 @@ func RegisterWebRoutes(r chi.Router, oauthClient *oauth.OAuthClient, userService
 	// Create handlers
 	handlers := web.NewHandlers(templates, oauthClient, userService)
 
-	// Landing page
-	r.Get("/", handlers.LandingHandler)
+	// Serve static files from project's static directory
+	staticFiles := http.StripPrefix("/static/", http.FileServer(http.Dir("static")))
+
+	reg.Handle(
+		// Landing page
+		Route{Method: http.MethodGet, Path: "/", Handler: handlers.LandingHandler, Auth: AuthPublic},
 
-	// Account deletion flow
-	r.Get("/delete-account", handlers.DeleteAccountPageHandler)
-	r.Post("/delete-account", handlers.DeleteAccountSubmitHandler)
-	r.Get("/delete-account/success", handlers.DeleteAccountSuccessHandler)
+		// Account deletion flow
+		Route{Method: http.MethodGet, Path: "/delete-account", Handler: handlers.DeleteAccountPageHandler, Auth: AuthPublic},
+		Route{Method: http.MethodPost, Path: "/delete-account", Handler: handlers.DeleteAccountSubmitHandler, Auth: AuthPublic},
+		Route{Method: http.MethodGet, Path: "/delete-account/success", Handler: handlers.DeleteAccountSuccessHandler, Auth: AuthPublic},
 
-	// Legal pages
-	r.Get("/privacy", handlers.PrivacyHandler)
+		// Legal pages
+		Route{Method: http.MethodGet, Path: "/privacy", Handler: handlers.PrivacyHandler, Auth: AuthPublic},
 
-	// Static files (images, etc.)
-	r.Get("/static/*", func(w http.ResponseWriter, r *http.Request) {
-		// Serve from project's static directory
-		fs := http.StripPrefix("/static/", http.FileServer(http.Dir("static")))
-		fs.ServeHTTP(w, r)
-	})
+		// Static files (images, etc.)
+		Route{Method: http.MethodGet, Path: "/static/*", Handler: staticFiles.ServeHTTP, Auth: AuthPublic},
+	)
 }
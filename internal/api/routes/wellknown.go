@@ -2,24 +2,34 @@ package routes
 
 import (
 	"Coves/internal/api/handlers/wellknown"
-
-	"github.com/go-chi/chi/v5"
+	"Coves/internal/core/communities"
+	"net/http"
 )
 
 // RegisterWellKnownRoutes registers RFC 8615 well-known URI endpoints
 // These endpoints are used for service discovery and mobile app deep linking
 //
 // Spec: https://www.rfc-editor.org/rfc/rfc8615.html
-func RegisterWellKnownRoutes(r chi.Router) {
-	// iOS Universal Links configuration
-	// Required for cryptographically-bound deep linking on iOS
-	// Must be served at exact path /.well-known/apple-app-site-association
-	// Content-Type: application/json (no redirects allowed)
-	r.Get("/.well-known/apple-app-site-association", wellknown.HandleAppleAppSiteAssociation)
+func RegisterWellKnownRoutes(reg *Registrar) {
+	reg.Handle(
+		// iOS Universal Links configuration
+		// Required for cryptographically-bound deep linking on iOS
+		// Must be served at exact path /.well-known/apple-app-site-association
+		// Content-Type: application/json (no redirects allowed)
+		Route{Method: http.MethodGet, Path: "/.well-known/apple-app-site-association", Handler: wellknown.HandleAppleAppSiteAssociation, Auth: AuthPublic},
+
+		// Android App Links configuration
+		// Required for cryptographically-bound deep linking on Android
+		// Must be served at exact path /.well-known/assetlinks.json
+		// Content-Type: application/json (no redirects allowed)
+		Route{Method: http.MethodGet, Path: "/.well-known/assetlinks.json", Handler: wellknown.HandleAssetLinks, Auth: AuthPublic},
+	)
+}
 
-	// Android App Links configuration
-	// Required for cryptographically-bound deep linking on Android
-	// Must be served at exact path /.well-known/assetlinks.json
-	// Content-Type: application/json (no redirects allowed)
-	r.Get("/.well-known/assetlinks.json", wellknown.HandleAssetLinks)
+// RegisterDIDWebRoutes serves DID documents for this instance's did:web communities
+// Requests arrive on each community's own host (e.g., gaming.communities.example.com),
+// so the community DID domain must route to the AppView
+func RegisterDIDWebRoutes(reg *Registrar, docs communities.DIDWebRepository) {
+	handler := wellknown.NewDIDDocumentHandler(docs)
+	reg.Handle(Route{Method: http.MethodGet, Path: "/.well-known/did.json", Handler: handler.HandleDIDDocument, Auth: AuthPublic})
 }
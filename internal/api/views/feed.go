@@ -0,0 +1,70 @@
+package views
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/core/consumergaps"
+	"Coves/internal/core/posts"
+)
+
+// FeedItem is implemented by the feed view posts of every feed endpoint
+// (communityFeeds, timeline, discover)
+type FeedItem interface {
+	GetPost() *posts.PostView
+	// GetContext returns the feed reason and reply context, nil when absent
+	GetContext() (reason, reply interface{})
+}
+
+// BuildFeedResponse returns the feed response body for the requested version
+func BuildFeedResponse[T FeedItem](version Version, cursor *string, feed []T) interface{} {
+	switch version {
+	case V2:
+		return buildFeedV2(cursor, feed)
+	default:
+		return buildFeedV1(cursor, feed)
+	}
+}
+
+// BuildFeedResponseWithGaps is BuildFeedResponse for feeds that report consumer gaps
+// (getCommunity, getTimeline)
+func BuildFeedResponseWithGaps[T FeedItem](version Version, cursor *string, feed []T, gaps []*consumergaps.Gap) interface{} {
+	switch version {
+	case V2:
+		response := buildFeedV2(cursor, feed)
+		response.Gaps = gaps
+		return response
+	default:
+		response := buildFeedV1(cursor, feed)
+		response.Gaps = gaps
+		return response
+	}
+}
+
+// BuildPostResponse returns the getPost response body for the requested version
+func BuildPostResponse(version Version, post *posts.PostView, threads []*comments.ThreadViewComment) interface{} {
+	switch version {
+	case V2:
+		return &PostResponseV2{Post: PostV2(post), Comments: threadsV2(threads)}
+	default:
+		return &PostResponseV1{Post: PostV1(post), Comments: threadsV1(threads)}
+	}
+}
+
+// BuildCommentsResponse returns the getComments response body for the requested version
+func BuildCommentsResponse(version Version, resp *comments.GetCommentsResponse) interface{} {
+	switch version {
+	case V2:
+		return buildCommentsV2(resp)
+	default:
+		return buildCommentsV1(resp)
+	}
+}
+
+// BuildCommentThreadResponse returns the getComments permalink (uri) response body for the requested version
+func BuildCommentThreadResponse(version Version, resp *comments.GetCommentThreadResponse) interface{} {
+	switch version {
+	case V2:
+		return buildCommentThreadV2(resp)
+	default:
+		return buildCommentThreadV1(resp)
+	}
+}
@@ -0,0 +1,253 @@
+package views
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/core/consumergaps"
+	"Coves/internal/core/posts"
+	"time"
+)
+
+// The v1 shape is frozen. These projections list every v1 field, so fields added to the
+// core views reach v2 only unless they are added here on purpose.
+
+// FeedResponseV1 is the v1 feed response (getCommunity, getTimeline, getDiscover)
+type FeedResponseV1 struct {
+	Cursor *string             `json:"cursor,omitempty"`
+	Feed   []*FeedViewPostV1   `json:"feed"`
+	Gaps   []*consumergaps.Gap `json:"gaps,omitempty"`
+}
+
+// FeedViewPostV1 wraps a v1 post view with its feed context
+type FeedViewPostV1 struct {
+	Post   *PostViewV1 `json:"post"`
+	Reason interface{} `json:"reason,omitempty"`
+	Reply  interface{} `json:"reply,omitempty"`
+}
+
+// PostViewV1 is the v1 post view with flat author and viewer fields
+type PostViewV1 struct {
+	IndexedAt time.Time           `json:"indexedAt"`
+	CreatedAt time.Time           `json:"createdAt"`
+	Record    interface{}         `json:"record,omitempty"`
+	Embed     interface{}         `json:"embed,omitempty"`
+	Language  *string             `json:"language,omitempty"`
+	EditedAt  *time.Time          `json:"editedAt,omitempty"`
+	Viewer    *posts.ViewerState  `json:"viewer,omitempty"`
+	Via       *posts.ViaView      `json:"via,omitempty"`
+	Labels    *posts.LabelsView   `json:"labels,omitempty"`
+	Author    *posts.AuthorView   `json:"author"`
+	Stats     *PostStatsV1        `json:"stats,omitempty"`
+	Community *posts.CommunityRef `json:"community"`
+	RKey      string              `json:"rkey"`
+	CID       string              `json:"cid"`
+	URI       string              `json:"uri"`
+}
+
+// PostResponseV1 is the v1 getPost response
+type PostResponseV1 struct {
+	Post     *PostViewV1            `json:"post"`
+	Comments []*ThreadViewCommentV1 `json:"comments,omitempty"`
+}
+
+// PostStatsV1 is the v1 post stats; the comment activity breakdown is v2-only
+// Vote counts are nil inside the community's score hiding window
+type PostStatsV1 struct {
+	TagCounts    map[string]int `json:"tagCounts,omitempty"`
+	Upvotes      *int           `json:"upvotes"`
+	Downvotes    *int           `json:"downvotes"`
+	Score        *int           `json:"score"`
+	CommentCount int            `json:"commentCount"`
+	ShareCount   int            `json:"shareCount,omitempty"`
+	ScoreHidden  bool           `json:"scoreHidden,omitempty"`
+}
+
+// GetCommentsResponseV1 is the v1 getComments response; thread metadata is v2-only
+type GetCommentsResponseV1 struct {
+	Post     interface{}            `json:"post"`
+	Cursor   *string                `json:"cursor,omitempty"`
+	Comments []*ThreadViewCommentV1 `json:"comments"`
+}
+
+// ThreadViewCommentV1 is a v1 comment with its nested replies
+type ThreadViewCommentV1 struct {
+	Comment        *CommentViewV1         `json:"comment"`
+	Replies        []*ThreadViewCommentV1 `json:"replies,omitempty"`
+	HasMore        bool                   `json:"hasMore,omitempty"`
+	ContinueThread bool                   `json:"continueThread,omitempty"`
+}
+
+// CommentViewV1 is the v1 comment view with flat author and viewer fields
+type CommentViewV1 struct {
+	Embed          interface{}                  `json:"embed,omitempty"`
+	Record         interface{}                  `json:"record"`
+	Viewer         *comments.CommentViewerState `json:"viewer,omitempty"`
+	Author         *posts.AuthorView            `json:"author"`
+	Post           *comments.CommentRef         `json:"post"`
+	Parent         *comments.CommentRef         `json:"parent,omitempty"`
+	Stats          *comments.CommentStats       `json:"stats"`
+	CreatedAt      string                       `json:"createdAt"`
+	IndexedAt      string                       `json:"indexedAt"`
+	URI            string                       `json:"uri"`
+	CID            string                       `json:"cid"`
+	IsDeleted      bool                         `json:"isDeleted,omitempty"`
+	DeletionReason *string                      `json:"deletionReason,omitempty"`
+	DeletedAt      *string                      `json:"deletedAt,omitempty"`
+	LastEditedAt   *string                      `json:"lastEditedAt,omitempty"`
+	Edited         bool                         `json:"edited,omitempty"`
+	Collapsed      bool                         `json:"collapsed,omitempty"`
+}
+
+// GetCommentThreadResponseV1 is the v1 getComments permalink (uri) response
+type GetCommentThreadResponseV1 struct {
+	Post   interface{}      `json:"post"`
+	Cursor *string          `json:"cursor,omitempty"`
+	Thread *CommentThreadV1 `json:"thread"`
+}
+
+// CommentThreadV1 is a v1 comment with its ancestors and replies
+type CommentThreadV1 struct {
+	Focus            *CommentViewV1         `json:"focus"`
+	Ancestors        []*CommentViewV1       `json:"ancestors"`
+	Replies          []*ThreadViewCommentV1 `json:"replies"`
+	HasMoreAncestors bool                   `json:"hasMoreAncestors,omitempty"`
+}
+
+// buildFeedV1 converts feed items to the v1 feed shape
+func buildFeedV1[T FeedItem](cursor *string, feed []T) *FeedResponseV1 {
+	items := make([]*FeedViewPostV1, 0, len(feed))
+	for _, item := range feed {
+		reason, reply := item.GetContext()
+		items = append(items, &FeedViewPostV1{
+			Post:   PostV1(item.GetPost()),
+			Reason: reason,
+			Reply:  reply,
+		})
+	}
+	return &FeedResponseV1{Cursor: cursor, Feed: items}
+}
+
+// PostV1 converts a post view to the v1 shape
+func PostV1(post *posts.PostView) *PostViewV1 {
+	if post == nil {
+		return nil
+	}
+
+	view := &PostViewV1{
+		URI:       post.URI,
+		CID:       post.CID,
+		RKey:      post.RKey,
+		Author:    post.Author,
+		Community: post.Community,
+		Record:    post.Record,
+		Embed:     post.Embed,
+		Language:  post.Language,
+		CreatedAt: post.CreatedAt,
+		IndexedAt: post.IndexedAt,
+		EditedAt:  post.EditedAt,
+		Viewer:    post.Viewer,
+		Via:       post.Via,
+		Labels:    post.Labels,
+	}
+
+	if post.Stats != nil {
+		upvotes, downvotes, score := posts.VoteCounts(post.Stats.ScoreHidden, post.Stats.Upvotes, post.Stats.Downvotes, post.Stats.Score)
+		view.Stats = &PostStatsV1{
+			TagCounts:    post.Stats.TagCounts,
+			Upvotes:      upvotes,
+			Downvotes:    downvotes,
+			Score:        score,
+			CommentCount: post.Stats.CommentCount,
+			ShareCount:   post.Stats.ShareCount,
+			ScoreHidden:  post.Stats.ScoreHidden,
+		}
+	}
+
+	return view
+}
+
+// postV1 converts a comments response's post to the v1 shape when it's a post view
+func postV1(post interface{}) interface{} {
+	if view, ok := post.(*posts.PostView); ok {
+		return PostV1(view)
+	}
+	return post
+}
+
+// buildCommentsV1 converts a getComments response to the v1 shape
+func buildCommentsV1(resp *comments.GetCommentsResponse) *GetCommentsResponseV1 {
+	return &GetCommentsResponseV1{
+		Post:     postV1(resp.Post),
+		Cursor:   resp.Cursor,
+		Comments: threadsV1(resp.Comments),
+	}
+}
+
+// buildCommentThreadV1 converts a getComments permalink response to the v1 shape
+func buildCommentThreadV1(resp *comments.GetCommentThreadResponse) *GetCommentThreadResponseV1 {
+	out := &GetCommentThreadResponseV1{
+		Post:   postV1(resp.Post),
+		Cursor: resp.Cursor,
+	}
+	if resp.Thread != nil {
+		var ancestors []*CommentViewV1
+		if resp.Thread.Ancestors != nil {
+			ancestors = make([]*CommentViewV1, 0, len(resp.Thread.Ancestors))
+			for _, ancestor := range resp.Thread.Ancestors {
+				ancestors = append(ancestors, CommentV1(ancestor))
+			}
+		}
+		out.Thread = &CommentThreadV1{
+			Ancestors:        ancestors,
+			Focus:            CommentV1(resp.Thread.Focus),
+			Replies:          threadsV1(resp.Thread.Replies),
+			HasMoreAncestors: resp.Thread.HasMoreAncestors,
+		}
+	}
+	return out
+}
+
+// threadsV1 converts a comment thread tree to the v1 shape
+func threadsV1(threads []*comments.ThreadViewComment) []*ThreadViewCommentV1 {
+	if threads == nil {
+		return nil
+	}
+	out := make([]*ThreadViewCommentV1, 0, len(threads))
+	for _, thread := range threads {
+		if thread == nil {
+			continue
+		}
+		out = append(out, &ThreadViewCommentV1{
+			Comment:        CommentV1(thread.Comment),
+			Replies:        threadsV1(thread.Replies),
+			HasMore:        thread.HasMore,
+			ContinueThread: thread.ContinueThread,
+		})
+	}
+	return out
+}
+
+// CommentV1 converts a comment view to the v1 shape
+func CommentV1(comment *comments.CommentView) *CommentViewV1 {
+	if comment == nil {
+		return nil
+	}
+	return &CommentViewV1{
+		URI:            comment.URI,
+		CID:            comment.CID,
+		Author:         comment.Author,
+		Record:         comment.Record,
+		Embed:          comment.Embed,
+		Viewer:         comment.Viewer,
+		Post:           comment.Post,
+		Parent:         comment.Parent,
+		Stats:          comment.Stats,
+		CreatedAt:      comment.CreatedAt,
+		IndexedAt:      comment.IndexedAt,
+		IsDeleted:      comment.IsDeleted,
+		DeletionReason: comment.DeletionReason,
+		DeletedAt:      comment.DeletedAt,
+		LastEditedAt:   comment.LastEditedAt,
+		Edited:         comment.Edited,
+		Collapsed:      comment.Collapsed,
+	}
+}
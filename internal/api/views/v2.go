@@ -0,0 +1,324 @@
+package views
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/core/consumergaps"
+	"Coves/internal/core/posts"
+	"strings"
+	"time"
+)
+
+// FeedResponseV2 is the v2 feed response (getCommunity, getTimeline, getDiscover)
+type FeedResponseV2 struct {
+	Cursor *string             `json:"cursor,omitempty"`
+	Feed   []*FeedViewPostV2   `json:"feed"`
+	Gaps   []*consumergaps.Gap `json:"gaps,omitempty"`
+}
+
+// FeedViewPostV2 wraps a v2 post view with its feed context
+type FeedViewPostV2 struct {
+	Post   *PostViewV2 `json:"post"`
+	Reason interface{} `json:"reason,omitempty"`
+	Reply  interface{} `json:"reply,omitempty"`
+}
+
+// PostViewV2 is the v2 post view with nested author, embed, and viewer structures
+type PostViewV2 struct {
+	IndexedAt time.Time           `json:"indexedAt"`
+	CreatedAt time.Time           `json:"createdAt"`
+	Record    interface{}         `json:"record,omitempty"`
+	Embed     *EmbedViewV2        `json:"embed,omitempty"`
+	Language  *string             `json:"language,omitempty"`
+	EditedAt  *time.Time          `json:"editedAt,omitempty"`
+	Viewer    *PostViewerV2       `json:"viewer,omitempty"`
+	Via       *posts.ViaView      `json:"via,omitempty"`
+	Author    *AuthorViewV2       `json:"author"`
+	Stats     *posts.PostStats    `json:"stats,omitempty"`
+	Community *posts.CommunityRef `json:"community"`
+	RKey      string              `json:"rkey"`
+	CID       string              `json:"cid"`
+	URI       string              `json:"uri"`
+}
+
+// PostResponseV2 is the v2 getPost response
+type PostResponseV2 struct {
+	Post     *PostViewV2            `json:"post"`
+	Comments []*ThreadViewCommentV2 `json:"comments,omitempty"`
+}
+
+// AuthorViewV2 nests display fields under profile
+type AuthorViewV2 struct {
+	Profile        *AuthorProfileV2 `json:"profile,omitempty"`
+	Reputation     *int             `json:"reputation,omitempty"`
+	DID            string           `json:"did"`
+	Handle         string           `json:"handle"`
+	ConfusableFlag bool             `json:"confusableFlag,omitempty"`
+}
+
+// AuthorProfileV2 holds an author's display fields
+type AuthorProfileV2 struct {
+	DisplayName *string `json:"displayName,omitempty"`
+	Avatar      *string `json:"avatar,omitempty"`
+}
+
+// EmbedViewV2 tags an embed with its kind so clients can switch on it without parsing NSIDs
+type EmbedViewV2 struct {
+	View interface{} `json:"view"`
+	Type string      `json:"$type,omitempty"` // Full embed NSID, e.g. "social.coves.embed.external"
+	Kind string      `json:"kind"`            // Last NSID segment, e.g. "external"; "unknown" if untyped
+}
+
+// VoteViewV2 is the viewer's vote on a post or comment
+type VoteViewV2 struct {
+	Direction string `json:"direction"`
+	URI       string `json:"uri,omitempty"`
+}
+
+// PostViewerV2 is the viewer's relationship with a post
+type PostViewerV2 struct {
+	Vote     *VoteViewV2 `json:"vote,omitempty"`
+	SavedURI *string     `json:"savedUri,omitempty"`
+	Tags     []string    `json:"tags,omitempty"`
+	Saved    bool        `json:"saved"`
+}
+
+// GetCommentsResponseV2 is the v2 getComments response
+type GetCommentsResponseV2 struct {
+	Post     interface{}            `json:"post"`
+	Meta     *comments.ThreadMeta   `json:"meta,omitempty"`
+	Cursor   *string                `json:"cursor,omitempty"`
+	Comments []*ThreadViewCommentV2 `json:"comments"`
+}
+
+// ThreadViewCommentV2 is a v2 comment with its nested replies
+type ThreadViewCommentV2 struct {
+	Comment *CommentViewV2         `json:"comment"`
+	Replies []*ThreadViewCommentV2 `json:"replies,omitempty"`
+	HasMore bool                   `json:"hasMore,omitempty"`
+}
+
+// CommentViewV2 is the v2 comment view with nested author, embed, and viewer structures
+type CommentViewV2 struct {
+	Embed          *EmbedViewV2           `json:"embed,omitempty"`
+	Record         interface{}            `json:"record"`
+	Viewer         *CommentViewerV2       `json:"viewer,omitempty"`
+	Author         *AuthorViewV2          `json:"author"`
+	Post           *comments.CommentRef   `json:"post"`
+	Parent         *comments.CommentRef   `json:"parent,omitempty"`
+	Stats          *comments.CommentStats `json:"stats"`
+	DeletionReason *string                `json:"deletionReason,omitempty"`
+	DeletedAt      *string                `json:"deletedAt,omitempty"`
+	CreatedAt      string                 `json:"createdAt"`
+	IndexedAt      string                 `json:"indexedAt"`
+	URI            string                 `json:"uri"`
+	CID            string                 `json:"cid"`
+	IsDeleted      bool                   `json:"isDeleted,omitempty"`
+}
+
+// GetCommentThreadResponseV2 is the v2 getComments permalink (uri) response
+type GetCommentThreadResponseV2 struct {
+	Post   interface{}          `json:"post"`
+	Meta   *comments.ThreadMeta `json:"meta,omitempty"`
+	Cursor *string              `json:"cursor,omitempty"`
+	Thread *CommentThreadV2     `json:"thread"`
+}
+
+// CommentThreadV2 is a v2 comment with its ancestors and replies
+type CommentThreadV2 struct {
+	Focus            *CommentViewV2         `json:"focus"`
+	Ancestors        []*CommentViewV2       `json:"ancestors"`
+	Replies          []*ThreadViewCommentV2 `json:"replies"`
+	HasMoreAncestors bool                   `json:"hasMoreAncestors,omitempty"`
+}
+
+// CommentViewerV2 is the viewer's relationship with a comment
+type CommentViewerV2 struct {
+	Vote *VoteViewV2 `json:"vote,omitempty"`
+}
+
+// buildFeedV2 converts feed items to the v2 feed shape
+func buildFeedV2[T FeedItem](cursor *string, feed []T) *FeedResponseV2 {
+	items := make([]*FeedViewPostV2, 0, len(feed))
+	for _, item := range feed {
+		reason, reply := item.GetContext()
+		items = append(items, &FeedViewPostV2{
+			Post:   PostV2(item.GetPost()),
+			Reason: reason,
+			Reply:  reply,
+		})
+	}
+	return &FeedResponseV2{Cursor: cursor, Feed: items}
+}
+
+// PostV2 converts a post view to the v2 shape
+func PostV2(post *posts.PostView) *PostViewV2 {
+	if post == nil {
+		return nil
+	}
+
+	view := &PostViewV2{
+		URI:       post.URI,
+		CID:       post.CID,
+		RKey:      post.RKey,
+		Author:    authorV2(post.Author),
+		Community: post.Community,
+		Record:    post.Record,
+		Embed:     embedV2(post.Embed),
+		Stats:     post.Stats,
+		Language:  post.Language,
+		CreatedAt: post.CreatedAt,
+		IndexedAt: post.IndexedAt,
+		EditedAt:  post.EditedAt,
+		Via:       post.Via,
+	}
+
+	if post.Viewer != nil {
+		view.Viewer = &PostViewerV2{
+			Vote:     voteV2(post.Viewer.Vote, post.Viewer.VoteURI),
+			Saved:    post.Viewer.Saved,
+			SavedURI: post.Viewer.SavedURI,
+			Tags:     post.Viewer.Tags,
+		}
+	}
+
+	return view
+}
+
+// buildCommentsV2 converts a getComments response to the v2 shape
+func buildCommentsV2(resp *comments.GetCommentsResponse) *GetCommentsResponseV2 {
+	out := &GetCommentsResponseV2{
+		Post:     resp.Post,
+		Meta:     resp.Meta,
+		Cursor:   resp.Cursor,
+		Comments: threadsV2(resp.Comments),
+	}
+	if post, ok := resp.Post.(*posts.PostView); ok {
+		out.Post = PostV2(post)
+	}
+	return out
+}
+
+// buildCommentThreadV2 converts a getComments permalink response to the v2 shape
+func buildCommentThreadV2(resp *comments.GetCommentThreadResponse) *GetCommentThreadResponseV2 {
+	out := &GetCommentThreadResponseV2{
+		Post:   resp.Post,
+		Meta:   resp.Meta,
+		Cursor: resp.Cursor,
+	}
+	if post, ok := resp.Post.(*posts.PostView); ok {
+		out.Post = PostV2(post)
+	}
+	if resp.Thread != nil {
+		ancestors := make([]*CommentViewV2, 0, len(resp.Thread.Ancestors))
+		for _, ancestor := range resp.Thread.Ancestors {
+			ancestors = append(ancestors, CommentV2(ancestor))
+		}
+		replies := threadsV2(resp.Thread.Replies)
+		if replies == nil {
+			replies = []*ThreadViewCommentV2{}
+		}
+		out.Thread = &CommentThreadV2{
+			Ancestors:        ancestors,
+			Focus:            CommentV2(resp.Thread.Focus),
+			Replies:          replies,
+			HasMoreAncestors: resp.Thread.HasMoreAncestors,
+		}
+	}
+	return out
+}
+
+// threadsV2 converts a comment thread tree to the v2 shape
+func threadsV2(threads []*comments.ThreadViewComment) []*ThreadViewCommentV2 {
+	if threads == nil {
+		return nil
+	}
+	out := make([]*ThreadViewCommentV2, 0, len(threads))
+	for _, thread := range threads {
+		if thread == nil {
+			continue
+		}
+		out = append(out, &ThreadViewCommentV2{
+			Comment: CommentV2(thread.Comment),
+			Replies: threadsV2(thread.Replies),
+			HasMore: thread.HasMore,
+		})
+	}
+	return out
+}
+
+// CommentV2 converts a comment view to the v2 shape
+func CommentV2(comment *comments.CommentView) *CommentViewV2 {
+	if comment == nil {
+		return nil
+	}
+
+	view := &CommentViewV2{
+		URI:            comment.URI,
+		CID:            comment.CID,
+		Author:         authorV2(comment.Author),
+		Record:         comment.Record,
+		Embed:          embedV2(comment.Embed),
+		Post:           comment.Post,
+		Parent:         comment.Parent,
+		Stats:          comment.Stats,
+		CreatedAt:      comment.CreatedAt,
+		IndexedAt:      comment.IndexedAt,
+		IsDeleted:      comment.IsDeleted,
+		DeletionReason: comment.DeletionReason,
+		DeletedAt:      comment.DeletedAt,
+	}
+
+	if comment.Viewer != nil {
+		view.Viewer = &CommentViewerV2{Vote: voteV2(comment.Viewer.Vote, comment.Viewer.VoteURI)}
+	}
+
+	return view
+}
+
+// authorV2 nests the author's display fields under profile
+func authorV2(author *posts.AuthorView) *AuthorViewV2 {
+	if author == nil {
+		return nil
+	}
+	view := &AuthorViewV2{
+		DID:            author.DID,
+		Handle:         author.Handle,
+		Reputation:     author.Reputation,
+		ConfusableFlag: author.ConfusableFlag,
+	}
+	if author.DisplayName != nil || author.Avatar != nil {
+		view.Profile = &AuthorProfileV2{
+			DisplayName: author.DisplayName,
+			Avatar:      author.Avatar,
+		}
+	}
+	return view
+}
+
+// embedV2 wraps an embed with its type and kind
+func embedV2(embed interface{}) *EmbedViewV2 {
+	if embed == nil {
+		return nil
+	}
+	view := &EmbedViewV2{View: embed, Kind: "unknown"}
+	if embedMap, ok := embed.(map[string]interface{}); ok {
+		if embedType, ok := embedMap["$type"].(string); ok && embedType != "" {
+			view.Type = embedType
+			nsid, _, _ := strings.Cut(embedType, "#")
+			view.Kind = nsid[strings.LastIndex(nsid, ".")+1:]
+		}
+	}
+	return view
+}
+
+// voteV2 combines the flat v1 vote fields into a nested vote
+func voteV2(direction, uri *string) *VoteViewV2 {
+	if direction == nil {
+		return nil
+	}
+	vote := &VoteViewV2{Direction: *direction}
+	if uri != nil {
+		vote.URI = *uri
+	}
+	return vote
+}
@@ -0,0 +1,74 @@
+// Package views builds versioned JSON response bodies for the feed and comment endpoints.
+//
+// Clients pick a response shape with the "version" query parameter or an
+// "Accept: application/json; version=N" header (the query parameter wins).
+// Version 1 is today's shape and stays frozen (pinned by golden-file tests);
+// new fields and structures go into the latest version only.
+package views
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Version identifies a response shape
+type Version int
+
+const (
+	// V1 is the original flat post/comment view shape
+	V1 Version = 1
+	// V2 nests viewer state, embeds, and author profiles
+	V2 Version = 2
+
+	// DefaultVersion is used when the client doesn't ask for a version
+	DefaultVersion = V1
+	// LatestVersion is the newest supported version
+	LatestVersion = V2
+)
+
+// ErrUnsupportedVersion is returned when the client asks for a version we don't serve
+var ErrUnsupportedVersion = errors.New("unsupported response version")
+
+// ParseVersion determines the requested response version
+// The "version" query parameter takes precedence over the Accept header's version parameter.
+// Returns DefaultVersion when neither is present and an error wrapping
+// ErrUnsupportedVersion for unknown or malformed versions.
+func ParseVersion(r *http.Request) (Version, error) {
+	if raw := r.URL.Query().Get("version"); raw != "" {
+		return parseVersionValue(raw)
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil || mediaType != "application/json" {
+			continue
+		}
+		if raw, ok := params["version"]; ok {
+			return parseVersionValue(raw)
+		}
+	}
+
+	return DefaultVersion, nil
+}
+
+// parseVersionValue parses "2" (or "v2") into a supported Version
+func parseVersionValue(raw string) (Version, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(strings.TrimSpace(raw)), "v"))
+	if err != nil || n < int(V1) || n > int(LatestVersion) {
+		return 0, fmt.Errorf("%w %q: supported versions are 1-%d", ErrUnsupportedVersion, raw, LatestVersion)
+	}
+	return Version(n), nil
+}
+
+// ContentType returns the Content-Type for a response of the given version
+// V1 keeps the plain "application/json" existing clients expect.
+func ContentType(version Version) string {
+	if version == V1 {
+		return "application/json"
+	}
+	return fmt.Sprintf("application/json; version=%d", version)
+}
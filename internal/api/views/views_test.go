@@ -0,0 +1,325 @@
+package views
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/timeline"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Run `go test ./internal/api/views -update` to regenerate golden files.
+// Only do that for v2+ changes: the v1 shape must never change.
+var update = flag.Bool("update", false, "update golden files")
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		accept  string
+		want    Version
+		wantErr bool
+	}{
+		{name: "default", want: V1},
+		{name: "query v1", query: "1", want: V1},
+		{name: "query v2", query: "2", want: V2},
+		{name: "query with prefix", query: "v2", want: V2},
+		{name: "accept header", accept: "application/json; version=2", want: V2},
+		{name: "accept among others", accept: "text/html, application/json;version=2;q=0.9", want: V2},
+		{name: "accept without version", accept: "application/json", want: V1},
+		{name: "query wins over accept", query: "1", accept: "application/json; version=2", want: V1},
+		{name: "unknown query version", query: "3", wantErr: true},
+		{name: "zero", query: "0", wantErr: true},
+		{name: "malformed query version", query: "latest", wantErr: true},
+		{name: "unknown accept version", accept: "application/json; version=9", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/xrpc/social.coves.feed.getDiscover"
+			if tt.query != "" {
+				target += "?version=" + tt.query
+			}
+			req := httptest.NewRequest("GET", target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			got, err := ParseVersion(req)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnsupportedVersion) {
+					t.Errorf("Expected ErrUnsupportedVersion, got version=%d err=%v", got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected version %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestContentType(t *testing.T) {
+	if got := ContentType(V1); got != "application/json" {
+		t.Errorf("v1 must keep plain application/json, got %q", got)
+	}
+	if got := ContentType(V2); got != "application/json; version=2" {
+		t.Errorf("Unexpected v2 content type %q", got)
+	}
+}
+
+// fixtureTime is fixed so golden output is stable
+var fixtureTime = time.Date(2025, 11, 6, 12, 0, 0, 0, time.UTC)
+
+func strPtr(s string) *string { return &s }
+
+// fixturePost returns a post view with every field populated
+func fixturePost() *posts.PostView {
+	reputation := 42
+	language := "en"
+	edited := fixtureTime.Add(time.Hour)
+	lastActivity := fixtureTime.Add(2 * time.Hour)
+	return &posts.PostView{
+		URI:       "at://did:plc:community/social.coves.community.post/3kpost",
+		CID:       "bafypost",
+		RKey:      "3kpost",
+		IndexedAt: fixtureTime,
+		CreatedAt: fixtureTime,
+		EditedAt:  &edited,
+		Language:  &language,
+		Record: map[string]interface{}{
+			"$type": "social.coves.community.post",
+			"title": "Golden post",
+		},
+		Embed: map[string]interface{}{
+			"$type": "social.coves.embed.external",
+			"external": map[string]interface{}{
+				"uri":   "https://example.com",
+				"title": "Example",
+			},
+		},
+		Author: &posts.AuthorView{
+			DID:         "did:plc:author",
+			Handle:      "author.example.com",
+			DisplayName: strPtr("Author"),
+			Avatar:      strPtr("https://cdn.example.com/avatar.jpg"),
+			Reputation:  &reputation,
+		},
+		Community: &posts.CommunityRef{
+			DID:    "did:plc:community",
+			Handle: "c-golden.coves.social",
+			Name:   "golden",
+			Avatar: strPtr("https://cdn.example.com/community.jpg"),
+			PDSURL: "https://pds.example.com",
+		},
+		Stats: &posts.PostStats{
+			Upvotes:              10,
+			Downvotes:            2,
+			Score:                8,
+			CommentCount:         3,
+			TopLevelCommentCount: 2,
+			LastActivityAt:       &lastActivity,
+			ShareCount:           1,
+			TagCounts:            map[string]int{"funny": 2},
+		},
+		Viewer: &posts.ViewerState{
+			Vote:     strPtr("up"),
+			VoteURI:  strPtr("at://did:plc:viewer/social.coves.feed.vote/3kvote"),
+			Saved:    true,
+			SavedURI: strPtr("at://did:plc:viewer/social.coves.feed.save/3ksave"),
+			Tags:     []string{"funny"},
+		},
+		UpvoteCount:   10,
+		DownvoteCount: 2,
+		Score:         8,
+		CommentCount:  3,
+	}
+}
+
+func fixtureCursor() *string { return strPtr("cursor-abc") }
+
+func fixtureComments() *comments.GetCommentsResponse {
+	comment := func(rkey string, parent *comments.CommentRef, deleted bool) *comments.CommentView {
+		view := &comments.CommentView{
+			URI:       "at://did:plc:author/social.coves.community.comment/" + rkey,
+			CID:       "bafy" + rkey,
+			CreatedAt: fixtureTime.Format(time.RFC3339),
+			IndexedAt: fixtureTime.Format(time.RFC3339),
+			Record:    map[string]interface{}{"$type": "social.coves.community.comment", "content": "Comment " + rkey},
+			Author:    fixturePost().Author,
+			Post:      &comments.CommentRef{URI: fixturePost().URI, CID: fixturePost().CID},
+			Parent:    parent,
+			Stats:     &comments.CommentStats{Upvotes: 3, Downvotes: 1, Score: 2, ReplyCount: 1},
+			Viewer: &comments.CommentViewerState{
+				Vote:    strPtr("down"),
+				VoteURI: strPtr("at://did:plc:viewer/social.coves.feed.vote/" + rkey),
+			},
+			Embed: map[string]interface{}{"$type": "social.coves.embed.images#view", "images": []interface{}{}},
+		}
+		if deleted {
+			view.IsDeleted = true
+			view.DeletionReason = strPtr("author")
+			view.DeletedAt = strPtr(fixtureTime.Format(time.RFC3339))
+		}
+		return view
+	}
+
+	root := comment("3kroot", nil, false)
+	return &comments.GetCommentsResponse{
+		Post:   fixturePost(),
+		Meta:   &comments.ThreadMeta{TotalComments: 3, Participants: 2},
+		Cursor: fixtureCursor(),
+		Comments: []*comments.ThreadViewComment{
+			{
+				Comment: root,
+				Replies: []*comments.ThreadViewComment{
+					{Comment: comment("3kreply", &comments.CommentRef{URI: root.URI, CID: root.CID}, true), HasMore: true},
+				},
+			},
+		},
+	}
+}
+
+// assertGolden compares body's indented JSON against testdata/name
+func assertGolden(t *testing.T, name string, body interface{}) {
+	t.Helper()
+
+	got, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	got = append(got, '\n')
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s does not match golden file.\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestGolden_CommunityFeedV1(t *testing.T) {
+	resp := &communityFeeds.FeedResponse{
+		Cursor: fixtureCursor(),
+		Feed: []*communityFeeds.FeedViewPost{{
+			Post:   fixturePost(),
+			Reason: &communityFeeds.FeedReason{Type: "social.coves.communityFeed.defs#reasonPin"},
+			Reply: &communityFeeds.ReplyRef{
+				Root:   &communityFeeds.PostRef{URI: "at://did:plc:community/social.coves.community.post/3kroot", CID: "bafyroot"},
+				Parent: &communityFeeds.PostRef{URI: "at://did:plc:community/social.coves.community.post/3kparent", CID: "bafyparent"},
+			},
+		}},
+	}
+	assertGolden(t, "community_feed_v1.json", BuildFeedResponse(V1, resp.Cursor, resp.Feed))
+}
+
+func TestGolden_TimelineV1(t *testing.T) {
+	resp := &timeline.TimelineResponse{
+		Cursor: fixtureCursor(),
+		Feed: []*timeline.FeedViewPost{{
+			Post:   fixturePost(),
+			Reason: &timeline.FeedReason{Type: "social.coves.feed.defs#reasonCommunity"},
+		}},
+	}
+	assertGolden(t, "timeline_v1.json", BuildFeedResponse(V1, resp.Cursor, resp.Feed))
+}
+
+func TestGolden_DiscoverV1(t *testing.T) {
+	resp := &discover.DiscoverResponse{
+		Cursor: fixtureCursor(),
+		Feed:   []*discover.FeedViewPost{{Post: fixturePost()}},
+	}
+	assertGolden(t, "discover_v1.json", BuildFeedResponse(V1, resp.Cursor, resp.Feed))
+}
+
+func TestGolden_CommentsV1(t *testing.T) {
+	assertGolden(t, "comments_v1.json", BuildCommentsResponse(V1, fixtureComments()))
+}
+
+func TestGolden_FeedV2(t *testing.T) {
+	resp := &discover.DiscoverResponse{
+		Cursor: fixtureCursor(),
+		Feed: []*discover.FeedViewPost{{
+			Post:   fixturePost(),
+			Reason: &discover.FeedReason{Type: "social.coves.feed.defs#reasonCommunity"},
+		}},
+	}
+	assertGolden(t, "feed_v2.json", BuildFeedResponse(V2, resp.Cursor, resp.Feed))
+}
+
+func TestGolden_CommentsV2(t *testing.T) {
+	assertGolden(t, "comments_v2.json", BuildCommentsResponse(V2, fixtureComments()))
+}
+
+// fixtureCommentThread reuses the getComments fixture: its deleted reply becomes a stub
+// ancestor of a focus comment with no replies
+func fixtureCommentThread() *comments.GetCommentThreadResponse {
+	tree := fixtureComments()
+	root := tree.Comments[0]
+	stub := root.Replies[0].Comment
+	focus := *root.Comment
+	focus.URI = "at://did:plc:author/social.coves.community.comment/3kfocus"
+	focus.Parent = &comments.CommentRef{URI: stub.URI, CID: stub.CID}
+	return &comments.GetCommentThreadResponse{
+		Post:   tree.Post,
+		Meta:   tree.Meta,
+		Cursor: fixtureCursor(),
+		Thread: &comments.CommentThreadView{
+			Ancestors: []*comments.CommentView{root.Comment, stub},
+			Focus:     &focus,
+			Replies:   []*comments.ThreadViewComment{},
+		},
+	}
+}
+
+func TestGolden_CommentThreadV1(t *testing.T) {
+	assertGolden(t, "comment_thread_v1.json", BuildCommentThreadResponse(V1, fixtureCommentThread()))
+}
+
+func TestGolden_CommentThreadV2(t *testing.T) {
+	assertGolden(t, "comment_thread_v2.json", BuildCommentThreadResponse(V2, fixtureCommentThread()))
+}
+
+func TestBuildFeedResponse_V2OmitsAbsentContext(t *testing.T) {
+	resp := &timeline.TimelineResponse{Feed: []*timeline.FeedViewPost{{Post: &posts.PostView{URI: "at://x"}}}}
+	body, err := json.Marshal(BuildFeedResponse(V2, resp.Cursor, resp.Feed))
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if bytes.Contains(body, []byte(`"reason"`)) || bytes.Contains(body, []byte(`"reply"`)) {
+		t.Errorf("Expected absent reason/reply to be omitted, got %s", body)
+	}
+}
+
+func TestPostV1_HiddenScoresSerializeAsNull(t *testing.T) {
+	post := fixturePost()
+	post.Stats.Hide()
+	body, err := json.Marshal(PostV1(post).Stats)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	want := `{"tagCounts":{"funny":2},"upvotes":null,"downvotes":null,"score":null,"commentCount":3,"shareCount":1,"scoreHidden":true}`
+	if string(body) != want {
+		t.Errorf("Expected %s, got %s", want, body)
+	}
+}
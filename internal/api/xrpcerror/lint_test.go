@@ -0,0 +1,106 @@
+package xrpcerror
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// nonXRPCDirs serve plain-text or non-XRPC responses and may write errors directly
+var nonXRPCDirs = []string{
+	filepath.Join("handlers", "feeds"),
+	filepath.Join("handlers", "imageproxy"),
+	filepath.Join("handlers", "wellknown"),
+	"xrpcerror",
+}
+
+// rawErrorPatterns catch error responses written without WriteError
+var rawErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\bhttp\.Error\(`),
+	regexp.MustCompile(`"error"\s*:`),
+}
+
+// errorStringPatterns catch handlers deciding what an error is from its message instead of
+// errors.Is, which breaks silently when the message is reworded or wrapped
+var errorStringPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\.Error\(\)\s*(==|!=)`),
+	regexp.MustCompile(`(==|!=)\s*\w+\.Error\(\)`),
+	regexp.MustCompile(`strings\.(Contains|HasPrefix|HasSuffix|EqualFold)\(\s*(\w+\.Error\(\)|errStr|errMsg)`),
+}
+
+// TestNoRawErrorResponses fails when an API handler writes an error body by hand,
+// which is how responses drift from the {"error", "message"} shape
+func TestNoRawErrorResponses(t *testing.T) {
+	walkAPISources(t, nonXRPCDirs, func(rel string, line int, text string) {
+		for _, pattern := range rawErrorPatterns {
+			if pattern.MatchString(text) {
+				t.Errorf("%s:%d writes an error response directly; use xrpcerror.WriteError: %s",
+					rel, line, strings.TrimSpace(text))
+			}
+		}
+	})
+}
+
+// TestNoErrorStringMatching fails when an API or database source compares an error's message,
+// which is what sentinel errors, errors.Is and Postgres error codes are for
+func TestNoErrorStringMatching(t *testing.T) {
+	check := func(rel string, line int, text string) {
+		for _, pattern := range errorStringPatterns {
+			if pattern.MatchString(text) {
+				t.Errorf("%s:%d matches an error by its message; use errors.Is with a sentinel or a Postgres error code: %s",
+					rel, line, strings.TrimSpace(text))
+			}
+		}
+	}
+	walkAPISources(t, nil, check)
+	walkSources(t, filepath.Join("..", "..", "db"), nil, check)
+}
+
+// walkAPISources calls check with each line of the non-test Go sources under internal/api,
+// skipping the given directories
+func walkAPISources(t *testing.T, skipDirs []string, check func(rel string, line int, text string)) {
+	t.Helper()
+	walkSources(t, "..", skipDirs, check)
+}
+
+// walkSources calls check with each line of the non-test Go sources under root, skipping the
+// given directories (relative to root)
+func walkSources(t *testing.T, root string, skipDirs []string, check func(rel string, line int, text string)) {
+	t.Helper()
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		if d.IsDir() {
+			for _, dir := range skipDirs {
+				if rel == dir {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = f.Close() }()
+
+		scanner := bufio.NewScanner(f)
+		for line := 1; scanner.Scan(); line++ {
+			check(rel, line, scanner.Text())
+		}
+		return scanner.Err()
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk sources under %s: %v", root, err)
+	}
+}
@@ -0,0 +1,173 @@
+// Package xrpcerror writes XRPC error responses.
+//
+// Every error returned by the API uses the same JSON shape:
+//
+//	{"error": "CommunityNotFound", "message": "Community not found"}
+//
+// error is a stable, UpperCamelCase name clients can switch on; message is for humans and
+// may change. Invalid records also carry details, one entry per violated rule:
+//
+//	{"error": "InvalidRequest", "message": "...", "details": [{"field": "title", "code": "too_long", "message": "..."}]}
+//
+// Names are declared below so every name the API emits is listed in one place;
+// endpoint-specific names must match the errors declared in the endpoint's lexicon.
+package xrpcerror
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// Response is the body of an XRPC error response
+type Response struct {
+	Details interface{} `json:"details,omitempty"`
+	Error   string      `json:"error"`
+	Message string      `json:"message"`
+}
+
+// Generic error names shared by all endpoints
+const (
+	InvalidRequest      = "InvalidRequest"
+	InvalidCursor       = "InvalidCursor"
+	AuthRequired        = "AuthRequired"
+	AuthExpired         = "AuthExpired"
+	Forbidden           = "Forbidden"
+	NotAuthorized       = "NotAuthorized"
+	NotFound            = "NotFound"
+	AlreadyExists       = "AlreadyExists"
+	Conflict            = "Conflict"
+	MethodNotAllowed    = "MethodNotAllowed"
+	RequestTooLarge     = "RequestTooLarge"
+	RequestCanceled     = "RequestCanceled"
+	Timeout             = "Timeout"
+	RateLimitExceeded   = "RateLimitExceeded"
+	NotImplemented      = "NotImplemented"
+	UnsupportedVersion  = "UnsupportedVersion"
+	InternalServerError = "InternalServerError"
+)
+
+// Resource-specific not-found names
+const (
+	AccountNotFound      = "AccountNotFound"
+	ActorNotFound        = "ActorNotFound"
+	AggregatorNotFound   = "AggregatorNotFound"
+	ApiKeyNotFound       = "ApiKeyNotFound"
+	CommentNotFound      = "CommentNotFound"
+	CommunityNotFound    = "CommunityNotFound"
+	ParentNotFound       = "ParentNotFound"
+	PostNotFound         = "PostNotFound"
+	ProfileNotFound      = "ProfileNotFound"
+	ReservedNameNotFound = "ReservedNameNotFound"
+	RootNotFound         = "RootNotFound"
+	VoteNotFound         = "VoteNotFound"
+)
+
+// Authentication and session names
+const (
+	AdminRequired        = "AdminRequired"
+	AggregatorRequired   = "AggregatorRequired"
+	MissingCredentials   = "MissingCredentials"
+	MissingSession       = "MissingSession"
+	OAuthSessionRequired = "OAuthSessionRequired"
+	SessionError         = "SessionError"
+	SessionMismatch      = "SessionMismatch"
+)
+
+// Community and moderation names
+const (
+	AccountTooNew               = "AccountTooNew"
+	Banned                      = "Banned"
+	Blocked                     = "Blocked"
+	CommunityCreationIncomplete = "CommunityCreationIncomplete"
+	CommunityCreationRestricted = "CommunityCreationRestricted"
+	CommunityDeleted            = "CommunityDeleted"
+	CommunityNameReserved       = "CommunityNameReserved"
+	CommunitySuspended          = "CommunitySuspended"
+	FederationBlocked           = "FederationBlocked"
+	InvalidCommunityName        = "InvalidCommunityName"
+	NameTaken                   = "NameTaken"
+	RecordTakenDown             = "RecordTakenDown"
+	ThreadLocked                = "ThreadLocked"
+	TooManyCommunities          = "TooManyCommunities"
+)
+
+// Content validation names
+const (
+	ContentEmpty         = "ContentEmpty"
+	ContentRuleViolation = "ContentRuleViolation"
+	ContentTooLong       = "ContentTooLong"
+	InvalidLabels        = "InvalidLabels"
+	InvalidReply         = "InvalidReply"
+	InvalidSubject       = "InvalidSubject"
+)
+
+// Translation names
+const (
+	TranslationFailed      = "TranslationFailed"
+	TranslationUnavailable = "TranslationUnavailable"
+)
+
+// Profile and blob names
+const (
+	AvatarTooLarge     = "AvatarTooLarge"
+	BannerTooLarge     = "BannerTooLarge"
+	BioTooLong         = "BioTooLong"
+	BlobUploadFailed   = "BlobUploadFailed"
+	DisplayNameTooLong = "DisplayNameTooLong"
+	InvalidMimeType    = "InvalidMimeType"
+	PDSError           = "PDSError"
+)
+
+// Account and aggregator registration names
+const (
+	AlreadyRegistered        = "AlreadyRegistered"
+	ApiKeyAlreadyRevoked     = "ApiKeyAlreadyRevoked"
+	DIDResolutionFailed      = "DIDResolutionFailed"
+	DomainVerificationFailed = "DomainVerificationFailed"
+	HandleNotAvailable       = "HandleNotAvailable"
+	InvalidDID               = "InvalidDID"
+	InvalidEmail             = "InvalidEmail"
+	InvalidHandle            = "InvalidHandle"
+	InvalidInviteCode        = "InvalidInviteCode"
+	KeyGenerationFailed      = "KeyGenerationFailed"
+	RegistrationFailed       = "RegistrationFailed"
+	RevocationFailed         = "RevocationFailed"
+	WeakPassword             = "WeakPassword"
+)
+
+// encodeFailure is sent when a response body can't be encoded
+const encodeFailure = `{"error":"InternalServerError","message":"Failed to encode response"}`
+
+// WriteError writes an XRPC error response
+// The body is encoded before headers are sent so an encoding failure can't leave a partial response
+func WriteError(w http.ResponseWriter, status int, errName, message string) {
+	WriteErrorDetails(w, status, errName, message, nil)
+}
+
+// WriteErrorDetails writes an XRPC error response with machine-readable details
+// details is encoded as the details field and omitted when nil.
+func WriteErrorDetails(w http.ResponseWriter, status int, errName, message string, details interface{}) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(Response{Error: errName, Message: message, Details: details}); err != nil {
+		log.Printf("ERROR: Failed to encode error response: %v", err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = w.Write([]byte(encodeFailure))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("ERROR: Failed to write error response: %v", err)
+	}
+}
+
+// WriteEncodeFailure writes the response sent when a success body can't be encoded
+func WriteEncodeFailure(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write([]byte(encodeFailure))
+}
@@ -0,0 +1,76 @@
+package xrpcerror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWriteError(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteError(w, http.StatusNotFound, CommunityNotFound, `Community "x" not found`)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected Content-Type application/json, got %q", ct)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode body %q: %v", w.Body.String(), err)
+	}
+	if len(body) != 2 {
+		t.Errorf("Expected exactly error and message keys, got %v", body)
+	}
+	if body["error"] != CommunityNotFound {
+		t.Errorf("Expected error %q, got %q", CommunityNotFound, body["error"])
+	}
+	if body["message"] != `Community "x" not found` {
+		t.Errorf("Expected message to round-trip, got %q", body["message"])
+	}
+}
+
+func TestWriteErrorDetails(t *testing.T) {
+	w := httptest.NewRecorder()
+	details := []map[string]string{{"field": "title", "code": "too_long"}}
+	WriteErrorDetails(w, http.StatusBadRequest, InvalidRequest, "Invalid record", details)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+
+	var body struct {
+		Error   string              `json:"error"`
+		Message string              `json:"message"`
+		Details []map[string]string `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode body %q: %v", w.Body.String(), err)
+	}
+	if body.Error != InvalidRequest || body.Message != "Invalid record" {
+		t.Errorf("Unexpected error %q message %q", body.Error, body.Message)
+	}
+	if len(body.Details) != 1 || body.Details[0]["field"] != "title" || body.Details[0]["code"] != "too_long" {
+		t.Errorf("Expected details to round-trip, got %v", body.Details)
+	}
+}
+
+func TestWriteEncodeFailure(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteEncodeFailure(w)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status 500, got %d", w.Code)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode body %q: %v", w.Body.String(), err)
+	}
+	if resp.Error != InternalServerError || resp.Message == "" {
+		t.Errorf("Unexpected encode failure response: %+v", resp)
+	}
+}
@@ -0,0 +1,81 @@
+// Package aturi parses AT-URIs (at://authority/collection/rkey).
+//
+// Records reach the AppView from Jetstream, PDS listRecords responses, and client requests,
+// and not every producer writes AT-URIs the canonical way. Parse accepts the common variants
+// (an upper-case scheme, a missing scheme, a trailing slash, a query or fragment) and
+// validates what's left with the atproto syntax rules, so callers compare canonical values.
+package aturi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+// scheme is the canonical AT-URI scheme prefix
+const scheme = "at://"
+
+// ErrInvalid indicates a string is not an AT-URI
+var ErrInvalid = errors.New("invalid AT-URI")
+
+// URI is a parsed AT-URI
+// Collection and RKey are empty when the URI stops short of them.
+type URI struct {
+	Authority  string
+	Collection string
+	RKey       string
+}
+
+// String returns the canonical at:// form of u
+func (u URI) String() string {
+	s := scheme + u.Authority
+	if u.Collection != "" {
+		s += "/" + u.Collection
+		if u.RKey != "" {
+			s += "/" + u.RKey
+		}
+	}
+	return s
+}
+
+// Parse parses raw as an AT-URI
+// The scheme is matched case-insensitively and may be omitted; any other scheme is rejected.
+// A trailing slash, query, or fragment is dropped. The authority must be a DID or handle, the
+// collection an NSID, and the rkey a valid record key.
+func Parse(raw string) (URI, error) {
+	s := strings.TrimSpace(raw)
+	if len(s) >= len(scheme) && strings.EqualFold(s[:len(scheme)], scheme) {
+		s = s[len(scheme):]
+	} else if strings.Contains(s, "://") {
+		return URI{}, fmt.Errorf("%w: unsupported scheme in %q", ErrInvalid, raw)
+	}
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		s = s[:i]
+	}
+	s = strings.TrimSuffix(s, "/")
+	if s == "" {
+		return URI{}, fmt.Errorf("%w: missing authority in %q", ErrInvalid, raw)
+	}
+
+	parsed, err := syntax.ParseATURI(scheme + s)
+	if err != nil {
+		return URI{}, fmt.Errorf("%w: %q: %v", ErrInvalid, raw, err)
+	}
+	return URI{
+		Authority:  parsed.Authority().String(),
+		Collection: parsed.Collection().String(),
+		RKey:       parsed.RecordKey().String(),
+	}, nil
+}
+
+// Collection returns the collection NSID of uri, or "" if uri is not an AT-URI or has no
+// collection segment
+func Collection(uri string) string {
+	parsed, err := Parse(uri)
+	if err != nil {
+		return ""
+	}
+	return parsed.Collection
+}
@@ -0,0 +1,127 @@
+package aturi
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want URI
+	}{
+		{
+			name: "record",
+			raw:  "at://did:plc:abc123/social.coves.community.post/3kabc",
+			want: URI{Authority: "did:plc:abc123", Collection: "social.coves.community.post", RKey: "3kabc"},
+		},
+		{
+			name: "collection only",
+			raw:  "at://did:plc:abc123/social.coves.feed.vote",
+			want: URI{Authority: "did:plc:abc123", Collection: "social.coves.feed.vote"},
+		},
+		{
+			name: "authority only",
+			raw:  "at://did:web:coves.social",
+			want: URI{Authority: "did:web:coves.social"},
+		},
+		{
+			name: "handle authority",
+			raw:  "at://alice.coves.social/social.coves.community.comment/3kdef",
+			want: URI{Authority: "alice.coves.social", Collection: "social.coves.community.comment", RKey: "3kdef"},
+		},
+		{
+			name: "upper-case scheme",
+			raw:  "AT://did:plc:abc123/social.coves.community.post/3kabc",
+			want: URI{Authority: "did:plc:abc123", Collection: "social.coves.community.post", RKey: "3kabc"},
+		},
+		{
+			name: "missing scheme",
+			raw:  "did:plc:abc123/social.coves.community.post/3kabc",
+			want: URI{Authority: "did:plc:abc123", Collection: "social.coves.community.post", RKey: "3kabc"},
+		},
+		{
+			name: "trailing slash",
+			raw:  "at://did:plc:abc123/social.coves.community.post/3kabc/",
+			want: URI{Authority: "did:plc:abc123", Collection: "social.coves.community.post", RKey: "3kabc"},
+		},
+		{
+			name: "query and fragment",
+			raw:  "at://did:plc:abc123/social.coves.community.post/3kabc?cid=bafy#reply",
+			want: URI{Authority: "did:plc:abc123", Collection: "social.coves.community.post", RKey: "3kabc"},
+		},
+		{
+			name: "surrounding whitespace",
+			raw:  "  at://did:plc:abc123/social.coves.community.post/3kabc\n",
+			want: URI{Authority: "did:plc:abc123", Collection: "social.coves.community.post", RKey: "3kabc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.raw)
+			if err != nil {
+				t.Fatalf("Expected %q to parse, got: %v", tt.raw, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+	}{
+		{name: "empty", raw: ""},
+		{name: "scheme only", raw: "at://"},
+		{name: "other scheme", raw: "https://did:plc:abc123/social.coves.community.post/3kabc"},
+		{name: "authority not a DID or handle", raw: "at://not_an_identifier/social.coves.community.post/3kabc"},
+		{name: "collection not an NSID", raw: "at://did:plc:abc123/posts/3kabc"},
+		{name: "too many segments", raw: "at://did:plc:abc123/social.coves.community.post/3kabc/extra"},
+		{name: "invalid rkey", raw: "at://did:plc:abc123/social.coves.community.post/bad$key"},
+		{name: "empty segment", raw: "at://did:plc:abc123//3kabc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.raw)
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("Expected ErrInvalid for %q, got: %v", tt.raw, err)
+			}
+		})
+	}
+}
+
+func TestURI_String(t *testing.T) {
+	raw := "AT://did:plc:abc123/social.coves.community.post/3kabc/"
+	parsed, err := Parse(raw)
+	if err != nil {
+		t.Fatalf("Expected %q to parse, got: %v", raw, err)
+	}
+	if got, want := parsed.String(), "at://did:plc:abc123/social.coves.community.post/3kabc"; got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestCollection(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{uri: "at://did:plc:abc123/social.coves.community.post/3kabc", want: "social.coves.community.post"},
+		{uri: "did:plc:abc123/social.coves.community.comment/3kabc", want: "social.coves.community.comment"},
+		{uri: "at://did:plc:abc123", want: ""},
+		{uri: "https://example.com/social.coves.community.post/3kabc", want: ""},
+		{uri: "garbage", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := Collection(tt.uri); got != tt.want {
+			t.Errorf("Collection(%q): expected %q, got %q", tt.uri, tt.want, got)
+		}
+	}
+}
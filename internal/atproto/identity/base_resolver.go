@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -79,6 +80,7 @@ func (r *baseResolver) Resolve(ctx context.Context, identifier string) (*Identit
 		PDSURL:     pdsURL,
 		ResolvedAt: time.Now().UTC(),
 		Method:     MethodHTTPS, // Default - Indigo doesn't expose which method was used
+		Doc:        didDocument(ident),
 	}, nil
 }
 
@@ -110,23 +112,25 @@ func (r *baseResolver) ResolveDID(ctx context.Context, didStr string) (*DIDDocum
 		}
 	}
 
-	// Construct our DID document from Indigo's identity
+	return didDocument(ident), nil
+}
+
+// didDocument constructs our DID document from Indigo's identity
+// Indigo keeps services in a map, so they're sorted by ID to keep the document stable.
+func didDocument(ident *indigoIdentity.Identity) *DIDDocument {
 	doc := &DIDDocument{
 		DID:     ident.DID.String(),
-		Service: []Service{},
+		Service: make([]Service, 0, len(ident.Services)),
 	}
-
-	// Extract PDS service endpoint
-	pdsURL := ident.PDSEndpoint()
-	if pdsURL != "" {
+	for id, svc := range ident.Services {
 		doc.Service = append(doc.Service, Service{
-			ID:              "#atproto_pds",
-			Type:            "AtprotoPersonalDataServer",
-			ServiceEndpoint: pdsURL,
+			ID:              "#" + id,
+			Type:            svc.Type,
+			ServiceEndpoint: svc.URL,
 		})
 	}
-
-	return doc, nil
+	sort.Slice(doc.Service, func(i, j int) bool { return doc.Service[i].ID < doc.Service[j].ID })
+	return doc
 }
 
 // Purge is a no-op for base resolver (no caching)
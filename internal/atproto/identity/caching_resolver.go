@@ -2,46 +2,159 @@ package identity
 
 import (
 	"context"
+	"errors"
 	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	coreerrors "Coves/internal/core/errors"
+
+	"golang.org/x/sync/singleflight"
 )
 
-// cachingResolver wraps a base resolver with caching
+// cachingConfig configures newCachingResolver; see Config for the meaning of each field
+type cachingConfig struct {
+	TTL              time.Duration
+	MemoryTTL        time.Duration
+	MaxStale         time.Duration
+	MemoryMaxEntries int
+}
+
+// cachingResolver wraps a base resolver with two cache tiers
+// L1 is this process's memory; L2 is the identity_cache table shared by every instance, so a
+// restart or another instance doesn't re-resolve every DID from PLC. Concurrent misses for the
+// same identifier share one base resolution, and identities up to maxStale past their expiry
+// are served while a single background refresh runs.
 type cachingResolver struct {
-	base  Resolver
-	cache IdentityCache
+	base       Resolver
+	cache      IdentityCache // L2
+	memory     *memoryCache  // L1
+	now        func() time.Time
+	fetches    singleflight.Group
+	generation atomic.Uint64 // Bumped by Purge, so resolutions that raced it aren't cached
+	ttl        time.Duration
+	memoryTTL  time.Duration
+	maxStale   time.Duration
 }
 
 // newCachingResolver creates a new caching resolver
-func newCachingResolver(base Resolver, cache IdentityCache) Resolver {
+func newCachingResolver(base Resolver, cache IdentityCache, cfg cachingConfig) *cachingResolver {
 	return &cachingResolver{
-		base:  base,
-		cache: cache,
+		base:      base,
+		cache:     cache,
+		memory:    newMemoryCache(cfg.MemoryMaxEntries),
+		now:       time.Now,
+		ttl:       cfg.TTL,
+		memoryTTL: cfg.MemoryTTL,
+		maxStale:  cfg.MaxStale,
 	}
 }
 
 // Resolve resolves a handle or DID to complete identity information
-// First checks cache, then falls back to base resolver
+// Checks memory, then the shared cache, then falls back to the base resolver
 func (r *cachingResolver) Resolve(ctx context.Context, identifier string) (*Identity, error) {
-	// Try cache first
-	cached, err := r.cache.Get(ctx, identifier)
+	key := normalizeIdentifier(identifier)
+	if key == "" {
+		// Let the base resolver report the invalid identifier
+		return r.base.Resolve(ctx, identifier)
+	}
+
+	// L1: trusted for memoryTTL, after which the shared cache is checked for purges and
+	// refreshes made by other instances
+	if entry, ok := r.memory.get(key); ok && r.now().Sub(entry.storedAt) < r.memoryTTL {
+		if ident, ok := r.serve(key, entry.ident, entry.expiresAt); ok {
+			return ident, nil
+		}
+	}
+
+	// L2
+	cached, expiresAt, err := r.cache.GetWithExpiry(ctx, key, r.maxStale)
 	if err == nil {
-		// Cache hit - mark it as from cache
-		cached.Method = MethodCache
-		return cached, nil
+		r.remember(cached, expiresAt)
+		if ident, ok := r.serve(key, cached, expiresAt); ok {
+			return ident, nil
+		}
+	} else if miss := (*ErrCacheMiss)(nil); !errors.As(err, &miss) {
+		// Resolving without the cache beats failing
+		log.Printf("Warning: identity cache lookup failed for %s: %v", key, err)
+	}
+
+	return r.resolve(ctx, key)
+}
+
+// serve returns a copy of a cached identity that is fresh, or stale by no more than maxStale
+// A stale identity starts a background refresh; refreshes already in flight are joined.
+func (r *cachingResolver) serve(key string, cached *Identity, expiresAt time.Time) (*Identity, bool) {
+	now := r.now()
+	if !now.Before(expiresAt.Add(r.maxStale)) {
+		return nil, false
+	}
+	if !now.Before(expiresAt) {
+		r.fetches.DoChan(key, func() (interface{}, error) {
+			return r.fetch(context.Background(), key)
+		})
+	}
+	ident := *cached
+	ident.Method = MethodCache
+	return &ident, true
+}
+
+// resolve resolves key with the base resolver, sharing the resolution with concurrent callers
+func (r *cachingResolver) resolve(ctx context.Context, key string) (*Identity, error) {
+	// The resolution outlives a caller that gives up, so the others waiting on it still get a result
+	detached := context.WithoutCancel(ctx)
+	result := r.fetches.DoChan(key, func() (interface{}, error) {
+		return r.fetch(detached, key)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		ident := *res.Val.(*Identity)
+		return &ident, nil
 	}
+}
 
-	// Cache miss - resolve using base resolver
-	identity, err := r.base.Resolve(ctx, identifier)
+// fetch resolves key with the base resolver and caches the result in both tiers
+// An identity that no longer exists is purged; other failures keep a stale entry.
+func (r *cachingResolver) fetch(ctx context.Context, key string) (*Identity, error) {
+	generation := r.generation.Load()
+	ident, err := r.base.Resolve(ctx, key)
 	if err != nil {
+		if errors.Is(err, coreerrors.ErrNotFound) {
+			r.memory.purge(key)
+			if purgeErr := r.cache.Purge(ctx, key); purgeErr != nil {
+				log.Printf("Warning: failed to purge missing identity %s: %v", key, purgeErr)
+			}
+		}
 		return nil, err
 	}
 
+	// A purge while resolving may mean the answer is already out of date: return it, but
+	// leave the caches empty for the next caller
+	if r.generation.Load() != generation {
+		return ident, nil
+	}
+
 	// Cache the resolved identity (ignore cache errors, just log them)
-	if cacheErr := r.cache.Set(ctx, identity); cacheErr != nil {
-		log.Printf("Warning: failed to cache identity for %s: %v", identifier, cacheErr)
+	if cacheErr := r.cache.Set(ctx, ident); cacheErr != nil {
+		log.Printf("Warning: failed to cache identity for %s: %v", key, cacheErr)
 	}
+	r.remember(ident, r.now().Add(r.ttl))
+	return ident, nil
+}
 
-	return identity, nil
+// remember keeps ident in memory under its DID and handle, as the shared cache does
+func (r *cachingResolver) remember(ident *Identity, expiresAt time.Time) {
+	now := r.now()
+	r.memory.put(ident.DID, ident, now, expiresAt)
+	if ident.Handle != "" {
+		r.memory.put(normalizeIdentifier(ident.Handle), ident, now, expiresAt)
+	}
 }
 
 // ResolveHandle specifically resolves a handle to DID and PDS URL
@@ -55,30 +168,44 @@ func (r *cachingResolver) ResolveHandle(ctx context.Context, handle string) (did
 }
 
 // ResolveDID retrieves a DID document and extracts the PDS endpoint
+// Goes through the same caches as Resolve
 func (r *cachingResolver) ResolveDID(ctx context.Context, did string) (*DIDDocument, error) {
-	// Try to get from cache first
-	cached, err := r.cache.Get(ctx, did)
-	if err == nil {
-		// We have cached identity, construct a simple DID document
-		return &DIDDocument{
-			DID: cached.DID,
-			Service: []Service{
-				{
-					ID:              "#atproto_pds",
-					Type:            "AtprotoPersonalDataServer",
-					ServiceEndpoint: cached.PDSURL,
-				},
-			},
-		}, nil
+	if !strings.HasPrefix(strings.TrimSpace(did), "did:") {
+		// Let the base resolver report the invalid DID
+		return r.base.ResolveDID(ctx, did)
 	}
 
-	// Cache miss - use base resolver
-	return r.base.ResolveDID(ctx, did)
+	ident, err := r.Resolve(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	if ident.Doc != nil {
+		return ident.Doc, nil
+	}
+
+	// Cached before documents were kept: construct a simple DID document
+	return &DIDDocument{
+		DID: ident.DID,
+		Service: []Service{
+			{
+				ID:              "#atproto_pds",
+				Type:            "AtprotoPersonalDataServer",
+				ServiceEndpoint: ident.PDSURL,
+			},
+		},
+	}, nil
 }
 
-// Purge removes an identifier from the cache and propagates to base
+// Purge removes an identifier from both caches and propagates to base
+// The related handle or DID goes with it, and a resolution of the identifier already in flight
+// is neither joined nor cached.
 func (r *cachingResolver) Purge(ctx context.Context, identifier string) error {
-	// Purge from cache
+	key := normalizeIdentifier(identifier)
+	r.generation.Add(1)
+	r.fetches.Forget(key)
+	r.memory.purge(key)
+
+	// Purge from the shared cache
 	if err := r.cache.Purge(ctx, identifier); err != nil {
 		return err
 	}
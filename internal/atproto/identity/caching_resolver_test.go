@@ -0,0 +1,425 @@
+package identity
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeIdentityCache is an in-memory stand-in for the identity_cache table
+type fakeIdentityCache struct {
+	entries map[string]fakeCacheEntry
+	now     func() time.Time
+	ttl     time.Duration
+	gets    int
+	mu      sync.Mutex
+}
+
+type fakeCacheEntry struct {
+	expiresAt time.Time
+	ident     Identity
+}
+
+func newFakeIdentityCache(now func() time.Time, ttl time.Duration) *fakeIdentityCache {
+	return &fakeIdentityCache{entries: make(map[string]fakeCacheEntry), now: now, ttl: ttl}
+}
+
+func (c *fakeIdentityCache) Get(ctx context.Context, identifier string) (*Identity, error) {
+	ident, _, err := c.GetWithExpiry(ctx, identifier, 0)
+	return ident, err
+}
+
+func (c *fakeIdentityCache) GetWithExpiry(ctx context.Context, identifier string, maxStale time.Duration) (*Identity, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.gets++
+	key := normalizeIdentifier(identifier)
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expiresAt.Add(maxStale)) {
+		return nil, time.Time{}, &ErrCacheMiss{Identifier: key}
+	}
+	ident := entry.ident
+	ident.Method = MethodCache
+	return &ident, entry.expiresAt, nil
+}
+
+func (c *fakeIdentityCache) Set(ctx context.Context, ident *Identity) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := fakeCacheEntry{ident: *ident, expiresAt: c.now().Add(c.ttl)}
+	c.entries[ident.DID] = entry
+	if ident.Handle != "" {
+		c.entries[normalizeIdentifier(ident.Handle)] = entry
+	}
+	return nil
+}
+
+func (c *fakeIdentityCache) Delete(ctx context.Context, identifier string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, normalizeIdentifier(identifier))
+	return nil
+}
+
+func (c *fakeIdentityCache) Purge(ctx context.Context, identifier string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := normalizeIdentifier(identifier)
+	if entry, ok := c.entries[key]; ok {
+		delete(c.entries, entry.ident.DID)
+		delete(c.entries, normalizeIdentifier(entry.ident.Handle))
+	}
+	delete(c.entries, key)
+	return nil
+}
+
+func (c *fakeIdentityCache) getCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gets
+}
+
+// countingResolver resolves every identifier to one identity, optionally blocking until released
+type countingResolver struct {
+	release chan struct{}
+	ident   Identity
+	calls   atomic.Int32
+}
+
+func (r *countingResolver) Resolve(ctx context.Context, identifier string) (*Identity, error) {
+	r.calls.Add(1)
+	if r.release != nil {
+		<-r.release
+	}
+	ident := r.ident
+	ident.Method = MethodHTTPS
+	return &ident, nil
+}
+
+func (r *countingResolver) ResolveHandle(ctx context.Context, handle string) (string, string, error) {
+	ident, err := r.Resolve(ctx, handle)
+	if err != nil {
+		return "", "", err
+	}
+	return ident.DID, ident.PDSURL, nil
+}
+
+func (r *countingResolver) ResolveDID(ctx context.Context, did string) (*DIDDocument, error) {
+	ident, err := r.Resolve(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+	return ident.Doc, nil
+}
+
+func (r *countingResolver) Purge(ctx context.Context, identifier string) error {
+	return nil
+}
+
+// testClock is a settable clock shared by a resolver and its fake cache
+type testClock struct {
+	now time.Time
+	mu  sync.Mutex
+}
+
+func (c *testClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestCachingResolver(base Resolver, clock *testClock) (*cachingResolver, *fakeIdentityCache) {
+	cache := newFakeIdentityCache(clock.Now, time.Hour)
+	r := newCachingResolver(base, cache, cachingConfig{
+		TTL:              time.Hour,
+		MemoryTTL:        time.Minute,
+		MaxStale:         10 * time.Minute,
+		MemoryMaxEntries: 100,
+	})
+	r.now = clock.Now
+	return r, cache
+}
+
+var cachedIdentity = Identity{
+	DID:    "did:plc:alice",
+	Handle: "alice.bsky.social",
+	PDSURL: "https://pds.example.com",
+	Doc: &DIDDocument{
+		DID: "did:plc:alice",
+		Service: []Service{
+			{ID: "#atproto_pds", Type: "AtprotoPersonalDataServer", ServiceEndpoint: "https://pds.example.com"},
+		},
+	},
+}
+
+func TestCachingResolver_Tiers(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("miss resolves once and fills both tiers", func(t *testing.T) {
+		clock := &testClock{now: time.Now()}
+		base := &countingResolver{ident: cachedIdentity}
+		r, cache := newTestCachingResolver(base, clock)
+
+		ident, err := r.Resolve(ctx, "Alice.bsky.social")
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		if ident.DID != cachedIdentity.DID || ident.Method != MethodHTTPS {
+			t.Errorf("Resolve() = %+v, want freshly resolved identity", ident)
+		}
+		if _, err := cache.Get(ctx, cachedIdentity.DID); err != nil {
+			t.Errorf("shared cache missing DID after resolve: %v", err)
+		}
+		if r.memory.size() != 2 {
+			t.Errorf("memory holds %d identifiers, want handle and DID", r.memory.size())
+		}
+
+		// The DID was cached alongside the handle, so neither tier goes back to the base
+		gets := cache.getCount()
+		ident, err = r.Resolve(ctx, cachedIdentity.DID)
+		if err != nil {
+			t.Fatalf("Resolve(DID) error = %v", err)
+		}
+		if ident.Method != MethodCache {
+			t.Errorf("Resolve(DID) method = %s, want cache", ident.Method)
+		}
+		if cache.getCount() != gets {
+			t.Error("fresh memory entry should not consult the shared cache")
+		}
+		if base.calls.Load() != 1 {
+			t.Errorf("base resolved %d times, want 1", base.calls.Load())
+		}
+	})
+
+	t.Run("shared cache answers after a restart", func(t *testing.T) {
+		clock := &testClock{now: time.Now()}
+		base := &countingResolver{ident: cachedIdentity}
+		cache := newFakeIdentityCache(clock.Now, time.Hour)
+		if err := cache.Set(ctx, &cachedIdentity); err != nil {
+			t.Fatal(err)
+		}
+		r := newCachingResolver(base, cache, cachingConfig{
+			TTL: time.Hour, MemoryTTL: time.Minute, MaxStale: 10 * time.Minute, MemoryMaxEntries: 100,
+		})
+		r.now = clock.Now
+
+		doc, err := r.ResolveDID(ctx, cachedIdentity.DID)
+		if err != nil {
+			t.Fatalf("ResolveDID() error = %v", err)
+		}
+		if len(doc.Service) != 1 || doc.Service[0].ServiceEndpoint != cachedIdentity.PDSURL {
+			t.Errorf("ResolveDID() services = %+v, want cached document", doc.Service)
+		}
+		if base.calls.Load() != 0 {
+			t.Errorf("base resolved %d times, want 0", base.calls.Load())
+		}
+		if r.memory.size() != 2 {
+			t.Errorf("memory holds %d identifiers, want the shared cache hit promoted", r.memory.size())
+		}
+	})
+
+	t.Run("memory rechecks the shared cache after its TTL", func(t *testing.T) {
+		clock := &testClock{now: time.Now()}
+		base := &countingResolver{ident: cachedIdentity}
+		r, cache := newTestCachingResolver(base, clock)
+
+		if _, err := r.Resolve(ctx, cachedIdentity.DID); err != nil {
+			t.Fatal(err)
+		}
+		// Another instance purges the identity from the shared cache
+		if err := cache.Purge(ctx, cachedIdentity.DID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := r.Resolve(ctx, cachedIdentity.DID); err != nil {
+			t.Fatal(err)
+		}
+		if base.calls.Load() != 1 {
+			t.Fatalf("base resolved %d times before memory TTL, want 1", base.calls.Load())
+		}
+
+		clock.Advance(2 * time.Minute)
+		if _, err := r.Resolve(ctx, cachedIdentity.DID); err != nil {
+			t.Fatal(err)
+		}
+		if base.calls.Load() != 2 {
+			t.Errorf("base resolved %d times after memory TTL, want 2", base.calls.Load())
+		}
+	})
+}
+
+func TestCachingResolver_Stampede(t *testing.T) {
+	ctx := context.Background()
+	clock := &testClock{now: time.Now()}
+	base := &countingResolver{ident: cachedIdentity, release: make(chan struct{})}
+	r, _ := newTestCachingResolver(base, clock)
+
+	const callers = 50
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ident, err := r.Resolve(ctx, cachedIdentity.DID)
+			if err == nil && ident.DID != cachedIdentity.DID {
+				t.Errorf("Resolve() DID = %s", ident.DID)
+			}
+			errs <- err
+		}()
+	}
+
+	// Let every caller pile up on the miss before the one resolution completes
+	for base.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(base.release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Resolve() error = %v", err)
+		}
+	}
+	if base.calls.Load() != 1 {
+		t.Errorf("base resolved %d times for %d concurrent misses, want 1", base.calls.Load(), callers)
+	}
+}
+
+func TestCachingResolver_StaleWhileRevalidate(t *testing.T) {
+	ctx := context.Background()
+	clock := &testClock{now: time.Now()}
+	base := &countingResolver{ident: cachedIdentity}
+	r, _ := newTestCachingResolver(base, clock)
+
+	if _, err := r.Resolve(ctx, cachedIdentity.DID); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expired but within maxStale: served from cache while one refresh runs
+	base.release = make(chan struct{})
+	clock.Advance(time.Hour + 5*time.Minute)
+	for i := 0; i < 5; i++ {
+		ident, err := r.Resolve(ctx, cachedIdentity.DID)
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		if ident.Method != MethodCache {
+			t.Errorf("stale Resolve() method = %s, want cache", ident.Method)
+		}
+	}
+	close(base.release)
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if entry, ok := r.memory.get(cachedIdentity.DID); ok && entry.expiresAt.After(clock.Now()) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if base.calls.Load() != 2 {
+		t.Fatalf("base resolved %d times, want one background refresh", base.calls.Load())
+	}
+	if entry, ok := r.memory.get(cachedIdentity.DID); !ok || !entry.expiresAt.After(clock.Now()) {
+		t.Fatal("background refresh did not re-cache the identity")
+	}
+
+	// Past maxStale: callers wait for a fresh resolution
+	base.release = nil
+	clock.Advance(3 * time.Hour)
+	ident, err := r.Resolve(ctx, cachedIdentity.DID)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if ident.Method != MethodHTTPS {
+		t.Errorf("Resolve() past maxStale method = %s, want a fresh resolution", ident.Method)
+	}
+}
+
+func TestCachingResolver_Purge(t *testing.T) {
+	ctx := context.Background()
+	clock := &testClock{now: time.Now()}
+	base := &countingResolver{ident: cachedIdentity}
+	r, cache := newTestCachingResolver(base, clock)
+
+	if _, err := r.Resolve(ctx, cachedIdentity.Handle); err != nil {
+		t.Fatal(err)
+	}
+
+	// An identity event for the DID drops the handle with it, in both tiers
+	if err := r.Purge(ctx, cachedIdentity.DID); err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if r.memory.size() != 0 {
+		t.Errorf("memory holds %d identifiers after purge, want 0", r.memory.size())
+	}
+	if _, err := cache.Get(ctx, cachedIdentity.Handle); err == nil {
+		t.Error("shared cache still holds the handle after purging the DID")
+	}
+
+	if _, err := r.Resolve(ctx, cachedIdentity.Handle); err != nil {
+		t.Fatal(err)
+	}
+	if base.calls.Load() != 2 {
+		t.Errorf("base resolved %d times, want a re-resolution after purge", base.calls.Load())
+	}
+}
+
+func TestCachingResolver_PurgeDuringResolution(t *testing.T) {
+	ctx := context.Background()
+	clock := &testClock{now: time.Now()}
+	base := &countingResolver{ident: cachedIdentity, release: make(chan struct{})}
+	r, cache := newTestCachingResolver(base, clock)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.Resolve(ctx, cachedIdentity.DID)
+		done <- err
+	}()
+	for base.calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The in-flight answer may predate the event, so it must not be cached
+	if err := r.Purge(ctx, cachedIdentity.DID); err != nil {
+		t.Fatal(err)
+	}
+	close(base.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if _, err := cache.Get(ctx, cachedIdentity.DID); err == nil {
+		t.Error("resolution that raced a purge was cached")
+	}
+	if r.memory.size() != 0 {
+		t.Errorf("memory holds %d identifiers, want 0", r.memory.size())
+	}
+}
+
+func TestMemoryCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := newMemoryCache(2)
+	now := time.Now()
+	a := &Identity{DID: "did:plc:a"}
+	c.put("did:plc:a", a, now, now)
+	c.put("did:plc:b", &Identity{DID: "did:plc:b"}, now, now)
+	c.get("did:plc:a")
+	c.put("did:plc:c", &Identity{DID: "did:plc:c"}, now, now)
+
+	if _, ok := c.get("did:plc:b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if _, ok := c.get("did:plc:a"); !ok {
+		t.Error("recently used entry was evicted")
+	}
+	if c.size() != 2 {
+		t.Errorf("size() = %d, want 2", c.size())
+	}
+}
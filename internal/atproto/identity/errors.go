@@ -1,13 +1,22 @@
 package identity
 
-import "fmt"
+import (
+	"fmt"
+
+	coreerrors "Coves/internal/core/errors"
+)
 
 // ErrNotFound is returned when an identity cannot be resolved
+// It matches coreerrors.ErrNotFound, so callers can tell a missing identity from a failed lookup
 type ErrNotFound struct {
 	Identifier string
 	Reason     string
 }
 
+func (e *ErrNotFound) Is(target error) bool {
+	return target == coreerrors.ErrNotFound
+}
+
 func (e *ErrNotFound) Error() string {
 	if e.Reason != "" {
 		return fmt.Sprintf("identity not found: %s (%s)", e.Identifier, e.Reason)
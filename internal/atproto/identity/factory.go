@@ -6,19 +6,43 @@ import (
 	"time"
 )
 
+// Identity cache defaults
+const (
+	DefaultCacheTTL              = 24 * time.Hour
+	DefaultCacheMaxStale         = time.Hour
+	DefaultMemoryCacheTTL        = 5 * time.Minute
+	DefaultMemoryCacheMaxEntries = 50000
+)
+
 // Config holds configuration for the identity resolver
 type Config struct {
 	HTTPClient *http.Client
 	PLCURL     string
-	CacheTTL   time.Duration
+	CacheTTL   time.Duration // How long a resolved identity is served without refreshing
+
+	// CacheMaxStale is how long past CacheTTL an identity is still served while one refresh
+	// runs; negative disables. MemoryCacheTTL is how long this process serves an identity from
+	// memory before checking the shared cache, which bounds how long another instance's purge
+	// takes to reach it.
+	CacheMaxStale         time.Duration
+	MemoryCacheTTL        time.Duration
+	MemoryCacheMaxEntries int
+
+	// LocalDirectory and LocalDIDWebDomain are optional: when both are set, did:web
+	// identities under LocalDIDWebDomain are resolved from the directory instead of HTTPS
+	LocalDirectory    LocalDirectory
+	LocalDIDWebDomain string
 }
 
 // DefaultConfig returns a configuration with sensible defaults
 func DefaultConfig() Config {
 	return Config{
-		PLCURL:     "https://plc.directory",
-		CacheTTL:   24 * time.Hour, // Cache for 24 hours
-		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+		PLCURL:                "https://plc.directory",
+		CacheTTL:              DefaultCacheTTL,
+		CacheMaxStale:         DefaultCacheMaxStale,
+		MemoryCacheTTL:        DefaultMemoryCacheTTL,
+		MemoryCacheMaxEntries: DefaultMemoryCacheMaxEntries,
+		HTTPClient:            &http.Client{Timeout: 10 * time.Second},
 	}
 }
 
@@ -29,7 +53,18 @@ func NewResolver(db *sql.DB, config Config) Resolver {
 		config.PLCURL = "https://plc.directory"
 	}
 	if config.CacheTTL == 0 {
-		config.CacheTTL = 24 * time.Hour
+		config.CacheTTL = DefaultCacheTTL
+	}
+	if config.CacheMaxStale < 0 {
+		config.CacheMaxStale = 0
+	} else if config.CacheMaxStale == 0 {
+		config.CacheMaxStale = DefaultCacheMaxStale
+	}
+	if config.MemoryCacheTTL <= 0 {
+		config.MemoryCacheTTL = DefaultMemoryCacheTTL
+	}
+	if config.MemoryCacheMaxEntries <= 0 {
+		config.MemoryCacheMaxEntries = DefaultMemoryCacheMaxEntries
 	}
 	if config.HTTPClient == nil {
 		config.HTTPClient = &http.Client{Timeout: 10 * time.Second}
@@ -38,9 +73,19 @@ func NewResolver(db *sql.DB, config Config) Resolver {
 	// Create base resolver using Indigo
 	base := newBaseResolver(config.PLCURL, config.HTTPClient)
 
-	// Wrap with caching using PostgreSQL
+	// Wrap with caching: per-process memory in front of PostgreSQL
 	cache := NewPostgresCache(db, config.CacheTTL)
-	caching := newCachingResolver(base, cache)
+	caching := newCachingResolver(base, cache, cachingConfig{
+		TTL:              config.CacheTTL,
+		MemoryTTL:        config.MemoryCacheTTL,
+		MaxStale:         config.CacheMaxStale,
+		MemoryMaxEntries: config.MemoryCacheMaxEntries,
+	})
+
+	// Identities this instance hosts itself are answered locally, uncached
+	if config.LocalDirectory != nil && config.LocalDIDWebDomain != "" {
+		return newLocalResolver(caching, config.LocalDirectory, config.LocalDIDWebDomain)
+	}
 
 	// Future: could add rate limiting here if needed
 	// if config.MaxConcurrent > 0 {
@@ -0,0 +1,197 @@
+package identity
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	indigoIdentity "github.com/bluesky-social/indigo/atproto/identity"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrKeyFetchFailed is returned when an issuer's signing key couldn't be fetched for reasons
+// other than the DID not existing (PLC down, did:web host unreachable)
+// Callers should treat it as temporary: 503, not 401.
+var ErrKeyFetchFailed = errors.New("signing key fetch failed")
+
+// Key directory defaults
+const (
+	DefaultKeyTTL        = 5 * time.Minute
+	DefaultKeyMaxStale   = time.Hour
+	DefaultKeyMaxEntries = 10000
+)
+
+// KeyDirectoryConfig configures NewKeyDirectory
+// Zero values use the defaults above.
+type KeyDirectoryConfig struct {
+	TTL        time.Duration // How long a fetched identity is served without refreshing
+	MaxStale   time.Duration // How long past TTL an expired identity is still served while one refresh runs; negative disables
+	MaxEntries int           // Least recently used identities are evicted beyond this
+}
+
+// keyEntry is a cached DID lookup
+type keyEntry struct {
+	fetchedAt time.Time
+	ident     *indigoIdentity.Identity
+	did       syntax.DID
+}
+
+// KeyDirectory caches DID lookups for service auth, where each validated JWT needs its
+// issuer's signing key
+// Concurrent lookups of the same DID share one upstream fetch, and expired entries keep being
+// served (up to MaxStale) while a single background refresh runs, so a popular issuer's
+// entry expiring under load doesn't fan out into a burst of identical fetches.
+// Handle lookups pass straight through to the base directory.
+type KeyDirectory struct {
+	base       indigoIdentity.Directory
+	now        func() time.Time
+	entries    map[syntax.DID]*list.Element
+	lru        *list.List // front is most recently used
+	fetches    singleflight.Group
+	ttl        time.Duration
+	maxStale   time.Duration
+	maxEntries int
+	mu         sync.Mutex
+}
+
+// NewKeyDirectory wraps base with a bounded, deduplicating DID cache
+func NewKeyDirectory(base indigoIdentity.Directory, cfg KeyDirectoryConfig) *KeyDirectory {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultKeyTTL
+	}
+	if cfg.MaxStale < 0 {
+		cfg.MaxStale = 0
+	} else if cfg.MaxStale == 0 {
+		cfg.MaxStale = DefaultKeyMaxStale
+	}
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = DefaultKeyMaxEntries
+	}
+	return &KeyDirectory{
+		base:       base,
+		now:        time.Now,
+		entries:    make(map[syntax.DID]*list.Element),
+		lru:        list.New(),
+		ttl:        cfg.TTL,
+		maxStale:   cfg.MaxStale,
+		maxEntries: cfg.MaxEntries,
+	}
+}
+
+// LookupDID returns the DID's identity, fetching it at most once across concurrent callers
+func (d *KeyDirectory) LookupDID(ctx context.Context, did syntax.DID) (*indigoIdentity.Identity, error) {
+	if entry, ok := d.get(did); ok {
+		age := d.now().Sub(entry.fetchedAt)
+		if age < d.ttl {
+			return entry.ident, nil
+		}
+		if age < d.ttl+d.maxStale {
+			// Serve the expired entry; DoChan joins a refresh already in flight
+			d.fetches.DoChan(did.String(), func() (interface{}, error) {
+				return d.fetch(context.Background(), did)
+			})
+			return entry.ident, nil
+		}
+	}
+
+	// The fetch outlives a caller that gives up, so the others waiting on it still get a result
+	detached := context.WithoutCancel(ctx)
+	result := d.fetches.DoChan(did.String(), func() (interface{}, error) {
+		return d.fetch(detached, did)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-result:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.(*indigoIdentity.Identity), nil
+	}
+}
+
+// fetch looks did up in the base directory and caches the result
+// A DID that no longer exists is evicted; other failures keep a stale entry and are wrapped
+// in ErrKeyFetchFailed.
+func (d *KeyDirectory) fetch(ctx context.Context, did syntax.DID) (*indigoIdentity.Identity, error) {
+	ident, err := d.base.LookupDID(ctx, did)
+	if err != nil {
+		if errors.Is(err, indigoIdentity.ErrDIDNotFound) {
+			d.remove(did)
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %s: %w", ErrKeyFetchFailed, did, err)
+	}
+	d.put(did, ident)
+	return ident, nil
+}
+
+// Lookup resolves a DID through the cache, or a handle through the base directory
+func (d *KeyDirectory) Lookup(ctx context.Context, atid syntax.AtIdentifier) (*indigoIdentity.Identity, error) {
+	if did, err := atid.AsDID(); err == nil {
+		return d.LookupDID(ctx, did)
+	}
+	return d.base.Lookup(ctx, atid)
+}
+
+// LookupHandle resolves a handle through the base directory
+func (d *KeyDirectory) LookupHandle(ctx context.Context, handle syntax.Handle) (*indigoIdentity.Identity, error) {
+	return d.base.LookupHandle(ctx, handle)
+}
+
+// Purge drops a cached DID so the next lookup fetches it again
+// The service auth validator purges after a signature mismatch to pick up rotated keys.
+func (d *KeyDirectory) Purge(ctx context.Context, atid syntax.AtIdentifier) error {
+	if did, err := atid.AsDID(); err == nil {
+		d.remove(did)
+	}
+	return d.base.Purge(ctx, atid)
+}
+
+// Len returns the number of cached identities
+func (d *KeyDirectory) Len() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lru.Len()
+}
+
+func (d *KeyDirectory) get(did syntax.DID) (*keyEntry, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	elem, ok := d.entries[did]
+	if !ok {
+		return nil, false
+	}
+	d.lru.MoveToFront(elem)
+	return elem.Value.(*keyEntry), true
+}
+
+func (d *KeyDirectory) put(did syntax.DID, ident *indigoIdentity.Identity) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entry := &keyEntry{did: did, ident: ident, fetchedAt: d.now()}
+	if elem, ok := d.entries[did]; ok {
+		elem.Value = entry
+		d.lru.MoveToFront(elem)
+		return
+	}
+	d.entries[did] = d.lru.PushFront(entry)
+	for d.lru.Len() > d.maxEntries {
+		oldest := d.lru.Back()
+		d.lru.Remove(oldest)
+		delete(d.entries, oldest.Value.(*keyEntry).did)
+	}
+}
+
+func (d *KeyDirectory) remove(did syntax.DID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if elem, ok := d.entries[did]; ok {
+		d.lru.Remove(elem)
+		delete(d.entries, did)
+	}
+}
@@ -0,0 +1,267 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/atcrypto"
+	indigoauth "github.com/bluesky-social/indigo/atproto/auth"
+	indigoIdentity "github.com/bluesky-social/indigo/atproto/identity"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+// countingDirectory serves fixed identities and counts upstream DID lookups
+type countingDirectory struct {
+	identities map[syntax.DID]*indigoIdentity.Identity
+	err        error // returned instead of an identity when set
+	calls      atomic.Int32
+	mu         sync.Mutex
+}
+
+func (d *countingDirectory) LookupDID(ctx context.Context, did syntax.DID) (*indigoIdentity.Identity, error) {
+	d.calls.Add(1)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if ident, ok := d.identities[did]; ok {
+		return ident, nil
+	}
+	return nil, fmt.Errorf("%w: PLC directory 404", indigoIdentity.ErrDIDNotFound)
+}
+
+func (d *countingDirectory) setErr(err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.err = err
+}
+
+func (d *countingDirectory) LookupHandle(ctx context.Context, handle syntax.Handle) (*indigoIdentity.Identity, error) {
+	return nil, indigoIdentity.ErrHandleNotFound
+}
+
+func (d *countingDirectory) Lookup(ctx context.Context, atid syntax.AtIdentifier) (*indigoIdentity.Identity, error) {
+	did, err := atid.AsDID()
+	if err != nil {
+		return nil, indigoIdentity.ErrHandleNotFound
+	}
+	return d.LookupDID(ctx, did)
+}
+
+func (d *countingDirectory) Purge(ctx context.Context, atid syntax.AtIdentifier) error {
+	return nil
+}
+
+// waitForRefresh blocks until a background refresh of did started by a stale read finishes
+func waitForRefresh(dir *KeyDirectory, did syntax.DID) {
+	_, _, _ = dir.fetches.Do(did.String(), func() (interface{}, error) { return nil, nil })
+}
+
+func testIdentity(did syntax.DID) *indigoIdentity.Identity {
+	return &indigoIdentity.Identity{DID: did, Handle: "aggregator.test"}
+}
+
+func TestKeyDirectory_OneUpstreamFetchUnderLoad(t *testing.T) {
+	priv, err := atcrypto.GeneratePrivateKeyK256()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	pub, err := priv.PublicKey()
+	if err != nil {
+		t.Fatalf("Failed to get public key: %v", err)
+	}
+
+	issuer := syntax.DID("did:plc:aggregator")
+	ident := testIdentity(issuer)
+	ident.Keys = map[string]indigoIdentity.VerificationMethod{
+		"atproto": {Type: "Multikey", PublicKeyMultibase: pub.Multibase()},
+	}
+	base := &countingDirectory{identities: map[syntax.DID]*indigoIdentity.Identity{issuer: ident}}
+
+	validator := &indigoauth.ServiceAuthValidator{
+		Audience: "did:web:coves.social",
+		Dir:      NewKeyDirectory(base, KeyDirectoryConfig{}),
+	}
+	token, err := indigoauth.SignServiceAuth(issuer, "did:web:coves.social", time.Minute, nil, priv)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+
+	// Goroutines either join the in-flight fetch or find its result cached
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	start := make(chan struct{})
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			did, err := validator.Validate(context.Background(), token, nil)
+			if err == nil && did != issuer {
+				err = fmt.Errorf("validated as %s", did)
+			}
+			errs <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Validation failed: %v", err)
+		}
+	}
+	if calls := base.calls.Load(); calls != 1 {
+		t.Errorf("Expected exactly 1 upstream fetch, got %d", calls)
+	}
+
+	// A failed fetch surfaces through the validator, so the auth middleware can answer 503
+	other, err := atcrypto.GeneratePrivateKeyK256()
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	otherToken, err := indigoauth.SignServiceAuth("did:plc:other", "did:web:coves.social", time.Minute, nil, other)
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	base.setErr(errors.New("connection refused"))
+	if _, err := validator.Validate(context.Background(), otherToken, nil); !errors.Is(err, ErrKeyFetchFailed) {
+		t.Errorf("Expected ErrKeyFetchFailed from the validator, got %v", err)
+	}
+}
+
+func TestKeyDirectory_StaleWhileRevalidate(t *testing.T) {
+	did := syntax.DID("did:plc:aggregator")
+	base := &countingDirectory{
+		identities: map[syntax.DID]*indigoIdentity.Identity{did: testIdentity(did)},
+	}
+	dir := NewKeyDirectory(base, KeyDirectoryConfig{TTL: time.Minute, MaxStale: 10 * time.Minute})
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	dir.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := dir.LookupDID(ctx, did); err != nil {
+		t.Fatalf("Initial lookup failed: %v", err)
+	}
+
+	// Fresh: served from cache
+	now = now.Add(30 * time.Second)
+	if _, err := dir.LookupDID(ctx, did); err != nil {
+		t.Fatalf("Fresh lookup failed: %v", err)
+	}
+	if calls := base.calls.Load(); calls != 1 {
+		t.Fatalf("Expected the fresh entry served from cache, got %d fetches", calls)
+	}
+
+	// Expired but within MaxStale, upstream down: the stale entry is served and kept
+	base.setErr(errors.New("connection refused"))
+	now = now.Add(2 * time.Minute)
+	if ident, err := dir.LookupDID(ctx, did); err != nil || ident.DID != did {
+		t.Fatalf("Expected the stale entry while refreshing, got %v, %v", ident, err)
+	}
+	waitForRefresh(dir, did)
+	if dir.Len() != 1 {
+		t.Fatal("Expected a failed refresh to keep the stale entry")
+	}
+
+	// Upstream back: the next stale read refreshes in the background
+	base.setErr(nil)
+	if _, err := dir.LookupDID(ctx, did); err != nil {
+		t.Fatalf("Stale lookup failed: %v", err)
+	}
+	waitForRefresh(dir, did)
+	if calls := base.calls.Load(); calls != 3 {
+		t.Fatalf("Expected one refresh per stale read, got %d fetches", calls)
+	}
+
+	// The refresh restarted the TTL
+	now = now.Add(30 * time.Second)
+	if _, err := dir.LookupDID(ctx, did); err != nil {
+		t.Fatalf("Lookup after refresh failed: %v", err)
+	}
+	if calls := base.calls.Load(); calls != 3 {
+		t.Errorf("Expected the refreshed entry served from cache, got %d fetches", calls)
+	}
+
+	// Past MaxStale with upstream down: the caller waits for the fetch and gets its error
+	base.setErr(errors.New("connection refused"))
+	now = now.Add(time.Hour)
+	_, err := dir.LookupDID(ctx, did)
+	if !errors.Is(err, ErrKeyFetchFailed) {
+		t.Errorf("Expected ErrKeyFetchFailed past the staleness window, got %v", err)
+	}
+}
+
+func TestKeyDirectory_NotFoundIsNotAFetchFailure(t *testing.T) {
+	base := &countingDirectory{identities: map[syntax.DID]*indigoIdentity.Identity{}}
+	dir := NewKeyDirectory(base, KeyDirectoryConfig{})
+
+	_, err := dir.LookupDID(context.Background(), "did:plc:missing")
+	if !errors.Is(err, indigoIdentity.ErrDIDNotFound) {
+		t.Errorf("Expected ErrDIDNotFound, got %v", err)
+	}
+	if errors.Is(err, ErrKeyFetchFailed) {
+		t.Error("A missing DID shouldn't look like a fetch failure")
+	}
+}
+
+func TestKeyDirectory_EvictsLeastRecentlyUsed(t *testing.T) {
+	a, b, c := syntax.DID("did:plc:a"), syntax.DID("did:plc:b"), syntax.DID("did:plc:c")
+	base := &countingDirectory{identities: map[syntax.DID]*indigoIdentity.Identity{
+		a: testIdentity(a), b: testIdentity(b), c: testIdentity(c),
+	}}
+	dir := NewKeyDirectory(base, KeyDirectoryConfig{MaxEntries: 2})
+	ctx := context.Background()
+
+	for _, did := range []syntax.DID{a, b, a, c} {
+		if _, err := dir.LookupDID(ctx, did); err != nil {
+			t.Fatalf("Lookup of %s failed: %v", did, err)
+		}
+	}
+	if dir.Len() != 2 {
+		t.Fatalf("Expected 2 cached entries, got %d", dir.Len())
+	}
+
+	// b was least recently used when c arrived
+	before := base.calls.Load()
+	if _, err := dir.LookupDID(ctx, a); err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if base.calls.Load() != before {
+		t.Error("Expected a to still be cached")
+	}
+	if _, err := dir.LookupDID(ctx, b); err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if base.calls.Load() != before+1 {
+		t.Error("Expected b to have been evicted")
+	}
+}
+
+func TestKeyDirectory_PurgeRefetches(t *testing.T) {
+	did := syntax.DID("did:plc:aggregator")
+	base := &countingDirectory{identities: map[syntax.DID]*indigoIdentity.Identity{did: testIdentity(did)}}
+	dir := NewKeyDirectory(base, KeyDirectoryConfig{})
+	ctx := context.Background()
+
+	if _, err := dir.LookupDID(ctx, did); err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if err := dir.Purge(ctx, did.AtIdentifier()); err != nil {
+		t.Fatalf("Purge failed: %v", err)
+	}
+	if _, err := dir.LookupDID(ctx, did); err != nil {
+		t.Fatalf("Lookup failed: %v", err)
+	}
+	if calls := base.calls.Load(); calls != 2 {
+		t.Errorf("Expected a purged DID to be fetched again, got %d fetches", calls)
+	}
+}
@@ -0,0 +1,97 @@
+package identity
+
+import (
+	"context"
+	"strings"
+)
+
+// LocalDirectory looks up identities this instance hosts itself (did:web communities)
+// LookupLocal returns *ErrNotFound when the identifier isn't hosted here
+type LocalDirectory interface {
+	LookupLocal(ctx context.Context, identifier string) (*Identity, error)
+}
+
+// localResolver answers lookups for this instance's own did:web domain from the local
+// directory instead of fetching the DID document over HTTPS from ourselves
+type localResolver struct {
+	next   Resolver
+	local  LocalDirectory
+	domain string
+}
+
+// newLocalResolver wraps next so identities under domain resolve locally
+func newLocalResolver(next Resolver, local LocalDirectory, domain string) Resolver {
+	return &localResolver{
+		next:   next,
+		local:  local,
+		domain: strings.ToLower(domain),
+	}
+}
+
+// isLocalDID reports whether did is a did:web under our domain
+// Authoritative: a local DID that isn't in the directory doesn't exist
+func (r *localResolver) isLocalDID(did string) bool {
+	host, ok := strings.CutPrefix(strings.ToLower(did), "did:web:")
+	if !ok {
+		return false
+	}
+	return host == r.domain || strings.HasSuffix(host, "."+r.domain)
+}
+
+// Resolve resolves a handle or DID, answering locally hosted identities from the directory
+func (r *localResolver) Resolve(ctx context.Context, identifier string) (*Identity, error) {
+	identifier = strings.TrimSpace(identifier)
+
+	if r.isLocalDID(identifier) {
+		return r.local.LookupLocal(ctx, identifier)
+	}
+
+	// Handles of local did:web communities live on the instance domain, not the did:web
+	// domain, so try the directory and fall through to normal resolution on a miss
+	if !strings.HasPrefix(identifier, "did:") {
+		if ident, err := r.local.LookupLocal(ctx, identifier); err == nil {
+			return ident, nil
+		}
+	}
+
+	return r.next.Resolve(ctx, identifier)
+}
+
+// ResolveHandle specifically resolves a handle to DID and PDS URL
+func (r *localResolver) ResolveHandle(ctx context.Context, handle string) (did, pdsURL string, err error) {
+	ident, err := r.Resolve(ctx, handle)
+	if err != nil {
+		return "", "", err
+	}
+
+	return ident.DID, ident.PDSURL, nil
+}
+
+// ResolveDID retrieves a DID document and extracts the PDS endpoint
+func (r *localResolver) ResolveDID(ctx context.Context, did string) (*DIDDocument, error) {
+	if !r.isLocalDID(did) {
+		return r.next.ResolveDID(ctx, did)
+	}
+
+	ident, err := r.local.LookupLocal(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DIDDocument{
+		DID: ident.DID,
+		Service: []Service{
+			{
+				ID:              "#atproto_pds",
+				Type:            "AtprotoPersonalDataServer",
+				ServiceEndpoint: ident.PDSURL,
+			},
+		},
+	}, nil
+}
+
+// Purge removes an identifier from the underlying resolver's cache
+// Local identities are never cached, so there is nothing else to purge
+func (r *localResolver) Purge(ctx context.Context, identifier string) error {
+	return r.next.Purge(ctx, identifier)
+}
@@ -0,0 +1,128 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// fakeLocalDirectory serves a fixed set of local identities
+type fakeLocalDirectory struct {
+	identities map[string]*Identity
+}
+
+func (d *fakeLocalDirectory) LookupLocal(ctx context.Context, identifier string) (*Identity, error) {
+	if ident, ok := d.identities[identifier]; ok {
+		return ident, nil
+	}
+	return nil, &ErrNotFound{Identifier: identifier}
+}
+
+// recordingResolver records which identifiers fell through to remote resolution
+type recordingResolver struct {
+	resolved []string
+}
+
+func (r *recordingResolver) Resolve(ctx context.Context, identifier string) (*Identity, error) {
+	r.resolved = append(r.resolved, identifier)
+	return &Identity{DID: "did:plc:remote", Handle: identifier, Method: MethodHTTPS}, nil
+}
+
+func (r *recordingResolver) ResolveHandle(ctx context.Context, handle string) (string, string, error) {
+	ident, err := r.Resolve(ctx, handle)
+	if err != nil {
+		return "", "", err
+	}
+	return ident.DID, ident.PDSURL, nil
+}
+
+func (r *recordingResolver) ResolveDID(ctx context.Context, did string) (*DIDDocument, error) {
+	r.resolved = append(r.resolved, did)
+	return &DIDDocument{DID: did}, nil
+}
+
+func (r *recordingResolver) Purge(ctx context.Context, identifier string) error {
+	return nil
+}
+
+func TestLocalResolver(t *testing.T) {
+	local := &Identity{
+		DID:    "did:web:gaming.communities.coves.social",
+		Handle: "c-gaming.coves.social",
+		PDSURL: "https://pds.coves.social",
+		Method: MethodLocal,
+	}
+	dir := &fakeLocalDirectory{identities: map[string]*Identity{
+		local.DID:    local,
+		local.Handle: local,
+	}}
+	ctx := context.Background()
+
+	t.Run("local DID short-circuits", func(t *testing.T) {
+		next := &recordingResolver{}
+		r := newLocalResolver(next, dir, "communities.coves.social")
+
+		ident, err := r.Resolve(ctx, local.DID)
+		if err != nil {
+			t.Fatalf("Resolve() error = %v", err)
+		}
+		if ident.Handle != local.Handle || ident.Method != MethodLocal {
+			t.Errorf("Resolve() = %+v, want local identity", ident)
+		}
+
+		doc, err := r.ResolveDID(ctx, local.DID)
+		if err != nil {
+			t.Fatalf("ResolveDID() error = %v", err)
+		}
+		if len(doc.Service) != 1 || doc.Service[0].ServiceEndpoint != local.PDSURL {
+			t.Errorf("ResolveDID() services = %+v, want local PDS", doc.Service)
+		}
+		if len(next.resolved) != 0 {
+			t.Errorf("expected no remote lookups, got %v", next.resolved)
+		}
+	})
+
+	t.Run("unknown local DID is not found without a remote lookup", func(t *testing.T) {
+		next := &recordingResolver{}
+		r := newLocalResolver(next, dir, "communities.coves.social")
+
+		_, err := r.Resolve(ctx, "did:web:missing.communities.coves.social")
+		var notFound *ErrNotFound
+		if !errors.As(err, &notFound) {
+			t.Errorf("Resolve() error = %v, want ErrNotFound", err)
+		}
+		if len(next.resolved) != 0 {
+			t.Errorf("expected no remote lookups, got %v", next.resolved)
+		}
+	})
+
+	t.Run("local handle resolves locally", func(t *testing.T) {
+		next := &recordingResolver{}
+		r := newLocalResolver(next, dir, "communities.coves.social")
+
+		did, pdsURL, err := r.ResolveHandle(ctx, local.Handle)
+		if err != nil {
+			t.Fatalf("ResolveHandle() error = %v", err)
+		}
+		if did != local.DID || pdsURL != local.PDSURL {
+			t.Errorf("ResolveHandle() = %s, %s", did, pdsURL)
+		}
+		if len(next.resolved) != 0 {
+			t.Errorf("expected no remote lookups, got %v", next.resolved)
+		}
+	})
+
+	t.Run("other identities fall through", func(t *testing.T) {
+		next := &recordingResolver{}
+		r := newLocalResolver(next, dir, "communities.coves.social")
+
+		for _, id := range []string{"alice.bsky.social", "did:plc:abc123", "did:web:notcommunities.coves.social"} {
+			if _, err := r.Resolve(ctx, id); err != nil {
+				t.Fatalf("Resolve(%s) error = %v", id, err)
+			}
+		}
+		if len(next.resolved) != 3 {
+			t.Errorf("expected 3 remote lookups, got %v", next.resolved)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package identity
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// memoryEntry is an identity held in the in-memory cache under one identifier
+type memoryEntry struct {
+	storedAt  time.Time // When this process last read or resolved it
+	expiresAt time.Time // When the identity expires, as in the shared cache
+	ident     *Identity
+	key       string
+}
+
+// memoryCache is the per-process L1 in front of the shared Postgres cache
+// Entries are keyed by normalized identifier like identity_cache rows, and the least recently
+// used are evicted beyond maxEntries.
+type memoryCache struct {
+	entries    map[string]*list.Element
+	lru        *list.List // front is most recently used
+	maxEntries int
+	mu         sync.Mutex
+}
+
+func newMemoryCache(maxEntries int) *memoryCache {
+	return &memoryCache{
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		maxEntries: maxEntries,
+	}
+}
+
+// get returns the entry for key, fresh or not
+func (c *memoryCache) get(key string) (*memoryEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*memoryEntry), true
+}
+
+// put stores ident under key
+func (c *memoryCache) put(key string, ident *Identity, storedAt, expiresAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &memoryEntry{key: key, ident: ident, storedAt: storedAt, expiresAt: expiresAt}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = entry
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*memoryEntry).key)
+	}
+}
+
+// purge removes key and, like postgresCache.Purge, the other identifier of the identity
+// cached under it
+func (c *memoryCache) purge(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	ident := elem.Value.(*memoryEntry).ident
+	c.remove(key)
+	c.remove(ident.DID)
+	if ident.Handle != "" {
+		c.remove(normalizeIdentifier(ident.Handle))
+	}
+}
+
+// remove drops key; the caller holds mu
+func (c *memoryCache) remove(key string) {
+	if elem, ok := c.entries[key]; ok {
+		c.lru.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+// size returns the number of cached identifiers
+func (c *memoryCache) size() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
@@ -0,0 +1,86 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+// maxAuditLogSize bounds how much of a PLC audit log is read; long-lived accounts with many
+// rotations stay well under it
+const maxAuditLogSize = 1 << 20
+
+// PLCAuditLog reads when did:plc accounts were created from their PLC directory audit log
+// The directory stamps each operation as it accepts it, so unlike anything in the account's own
+// records the creation time can't be backdated by its owner.
+type PLCAuditLog struct {
+	httpClient *http.Client
+	plcURL     string
+}
+
+// NewPLCAuditLog creates an audit log reader for the PLC directory at plcURL
+func NewPLCAuditLog(plcURL string, httpClient *http.Client) *PLCAuditLog {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &PLCAuditLog{plcURL: strings.TrimSuffix(plcURL, "/"), httpClient: httpClient}
+}
+
+// plcAuditEntry is one operation in a PLC audit log
+type plcAuditEntry struct {
+	CreatedAt string `json:"createdAt"`
+	Nullified bool   `json:"nullified"`
+}
+
+// AccountCreatedAt returns when the account's genesis operation was accepted by the directory
+// Accounts without a PLC history, such as did:web, return ErrNotFound.
+func (l *PLCAuditLog) AccountCreatedAt(ctx context.Context, did string) (time.Time, error) {
+	parsed, err := syntax.ParseDID(did)
+	if err != nil {
+		return time.Time{}, &ErrInvalidIdentifier{Identifier: did, Reason: fmt.Sprintf("invalid DID format: %v", err)}
+	}
+	if parsed.Method() != "plc" {
+		return time.Time{}, &ErrNotFound{Identifier: did, Reason: "only did:plc accounts have a creation history"}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, l.plcURL+"/"+url.PathEscape(did)+"/log/audit", nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create PLC audit log request: %w", err)
+	}
+	resp, err := l.httpClient.Do(req)
+	if err != nil {
+		return time.Time{}, &ErrResolutionFailed{Identifier: did, Reason: err.Error()}
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return time.Time{}, &ErrNotFound{Identifier: did, Reason: fmt.Sprintf("PLC audit log returned %d", resp.StatusCode)}
+	case resp.StatusCode != http.StatusOK:
+		return time.Time{}, &ErrResolutionFailed{Identifier: did, Reason: fmt.Sprintf("PLC audit log returned %d", resp.StatusCode)}
+	}
+
+	var entries []plcAuditEntry
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxAuditLogSize)).Decode(&entries); err != nil {
+		return time.Time{}, &ErrResolutionFailed{Identifier: did, Reason: fmt.Sprintf("invalid PLC audit log: %v", err)}
+	}
+	// The log is in directory order, so the first operation still in effect is the genesis
+	for _, entry := range entries {
+		if entry.Nullified {
+			continue
+		}
+		createdAt, err := time.Parse(time.RFC3339, entry.CreatedAt)
+		if err != nil {
+			return time.Time{}, &ErrResolutionFailed{Identifier: did, Reason: fmt.Sprintf("invalid PLC operation time %q", entry.CreatedAt)}
+		}
+		return createdAt, nil
+	}
+	return time.Time{}, &ErrNotFound{Identifier: did, Reason: "PLC audit log is empty"}
+}
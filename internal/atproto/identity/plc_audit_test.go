@@ -0,0 +1,52 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+func TestPLCAuditLog_AccountCreatedAt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/did:plc:abc123/log/audit":
+			_, _ = w.Write([]byte(`[
+				{"did":"did:plc:abc123","nullified":false,"createdAt":"2024-02-01T10:00:00.000Z"},
+				{"did":"did:plc:abc123","nullified":false,"createdAt":"2026-05-01T10:00:00.000Z"}
+			]`))
+		case "/did:plc:down/log/audit":
+			w.WriteHeader(http.StatusBadGateway)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	auditLog := NewPLCAuditLog(server.URL+"/", server.Client())
+	ctx := context.Background()
+
+	createdAt, err := auditLog.AccountCreatedAt(ctx, "did:plc:abc123")
+	if err != nil {
+		t.Fatalf("AccountCreatedAt failed: %v", err)
+	}
+	if want := time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC); !createdAt.Equal(want) {
+		t.Errorf("Expected the genesis operation time %s, got %s", want, createdAt)
+	}
+
+	for _, did := range []string{"did:plc:missing", "did:web:example.com"} {
+		if _, err := auditLog.AccountCreatedAt(ctx, did); !errors.Is(err, coreerrors.ErrNotFound) {
+			t.Errorf("Expected %s to have no creation history, got %v", did, err)
+		}
+	}
+
+	_, err = auditLog.AccountCreatedAt(ctx, "did:plc:down")
+	var failed *ErrResolutionFailed
+	if !errors.As(err, &failed) {
+		t.Errorf("Expected a directory failure to be a resolution failure, got %v", err)
+	}
+}
@@ -3,6 +3,7 @@ package identity
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -25,38 +26,56 @@ func NewPostgresCache(db *sql.DB, ttl time.Duration) IdentityCache {
 
 // Get retrieves a cached identity by handle or DID
 func (r *postgresCache) Get(ctx context.Context, identifier string) (*Identity, error) {
+	i, _, err := r.GetWithExpiry(ctx, identifier, 0)
+	return i, err
+}
+
+// GetWithExpiry retrieves a cached identity that expired no more than maxStale ago, along with
+// its expiry
+func (r *postgresCache) GetWithExpiry(ctx context.Context, identifier string, maxStale time.Duration) (*Identity, time.Time, error) {
 	identifier = normalizeIdentifier(identifier)
 
 	query := `
-		SELECT did, handle, pds_url, resolved_at, resolution_method, expires_at
+		SELECT did, handle, pds_url, resolved_at, resolution_method, expires_at, doc
 		FROM identity_cache
-		WHERE identifier = $1 AND expires_at > NOW()
+		WHERE identifier = $1 AND expires_at > NOW() - make_interval(secs => $2)
 	`
 
 	var i Identity
 	var method string
 	var expiresAt time.Time
+	var doc []byte
 
-	err := r.db.QueryRowContext(ctx, query, identifier).Scan(
+	err := r.db.QueryRowContext(ctx, query, identifier, maxStale.Seconds()).Scan(
 		&i.DID,
 		&i.Handle,
 		&i.PDSURL,
 		&i.ResolvedAt,
 		&method,
 		&expiresAt,
+		&doc,
 	)
 
 	if err == sql.ErrNoRows {
-		return nil, &ErrCacheMiss{Identifier: identifier}
+		return nil, time.Time{}, &ErrCacheMiss{Identifier: identifier}
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to query identity cache: %w", err)
+		return nil, time.Time{}, fmt.Errorf("failed to query identity cache: %w", err)
+	}
+
+	if doc != nil {
+		i.Doc = &DIDDocument{}
+		if err := json.Unmarshal(doc, i.Doc); err != nil {
+			// Unreadable document: fall back to the one built from pds_url
+			log.Printf("Warning: invalid cached DID document for %s: %v", identifier, err)
+			i.Doc = nil
+		}
 	}
 
 	// Convert string method to ResolutionMethod type
 	i.Method = MethodCache // It's from cache now
 
-	return &i, nil
+	return &i, expiresAt, nil
 }
 
 // Set caches an identity bidirectionally (by handle and by DID)
@@ -67,9 +86,17 @@ func (r *postgresCache) Set(ctx context.Context, i *Identity) error {
 	log.Printf("[identity-cache] Caching: handle=%s, did=%s, expires=%s (TTL=%s)",
 		i.Handle, i.DID, expiresAt.Format(time.RFC3339), r.ttl)
 
+	var doc []byte
+	if i.Doc != nil {
+		var err error
+		if doc, err = json.Marshal(i.Doc); err != nil {
+			return fmt.Errorf("failed to encode DID document: %w", err)
+		}
+	}
+
 	query := `
-		INSERT INTO identity_cache (identifier, did, handle, pds_url, resolved_at, resolution_method, expires_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO identity_cache (identifier, did, handle, pds_url, resolved_at, resolution_method, expires_at, doc)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		ON CONFLICT (identifier)
 		DO UPDATE SET
 			did = EXCLUDED.did,
@@ -78,6 +105,7 @@ func (r *postgresCache) Set(ctx context.Context, i *Identity) error {
 			resolved_at = EXCLUDED.resolved_at,
 			resolution_method = EXCLUDED.resolution_method,
 			expires_at = EXCLUDED.expires_at,
+			doc = EXCLUDED.doc,
 			updated_at = NOW()
 	`
 
@@ -86,7 +114,7 @@ func (r *postgresCache) Set(ctx context.Context, i *Identity) error {
 		normalizedHandle := normalizeIdentifier(i.Handle)
 		_, err := r.db.ExecContext(ctx, query,
 			normalizedHandle, i.DID, i.Handle, i.PDSURL,
-			i.ResolvedAt, string(i.Method), expiresAt,
+			i.ResolvedAt, string(i.Method), expiresAt, doc,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to cache identity by handle: %w", err)
@@ -96,7 +124,7 @@ func (r *postgresCache) Set(ctx context.Context, i *Identity) error {
 	// Cache by DID
 	_, err := r.db.ExecContext(ctx, query,
 		i.DID, i.DID, i.Handle, i.PDSURL,
-		i.ResolvedAt, string(i.Method), expiresAt,
+		i.ResolvedAt, string(i.Method), expiresAt, doc,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to cache identity by DID: %w", err)
@@ -1,6 +1,9 @@
 package identity
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Resolver provides methods for resolving atProto identities
 type Resolver interface {
@@ -27,6 +30,10 @@ type IdentityCache interface {
 	// Get retrieves a cached identity by handle or DID
 	Get(ctx context.Context, identifier string) (*Identity, error)
 
+	// GetWithExpiry is Get, but also returns identities that expired no more than maxStale
+	// ago, along with when the identity expires (or expired)
+	GetWithExpiry(ctx context.Context, identifier string, maxStale time.Duration) (*Identity, time.Time, error)
+
 	// Set caches an identity with the given TTL
 	// This should cache bidirectionally (both handle and DID as keys)
 	Set(ctx context.Context, identity *Identity) error
@@ -9,6 +9,7 @@ const (
 	MethodCache ResolutionMethod = "cache"
 	MethodDNS   ResolutionMethod = "dns"
 	MethodHTTPS ResolutionMethod = "https"
+	MethodLocal ResolutionMethod = "local"
 )
 
 // Identity represents a fully resolved atProto identity
@@ -18,18 +19,19 @@ type Identity struct {
 	PDSURL     string           // Personal Data Server URL
 	ResolvedAt time.Time        // When this identity was resolved
 	Method     ResolutionMethod // How it was resolved (cache, DNS, HTTPS)
+	Doc        *DIDDocument     // The DID document it was resolved from; nil for local and older cached identities
 }
 
 // DIDDocument represents an AT Protocol DID document
 // For now, we only extract the PDS service endpoint
 type DIDDocument struct {
-	DID     string
-	Service []Service
+	DID     string    `json:"id"`
+	Service []Service `json:"service"`
 }
 
 // Service represents a service entry in a DID document
 type Service struct {
-	ID              string
-	Type            string
-	ServiceEndpoint string
+	ID              string `json:"id"`
+	Type            string `json:"type"`
+	ServiceEndpoint string `json:"serviceEndpoint"`
 }
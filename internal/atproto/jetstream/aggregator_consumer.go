@@ -4,15 +4,21 @@ import (
 	"Coves/internal/core/aggregators"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // AggregatorEventConsumer consumes aggregator-related events from Jetstream
 // Following Bluesky's pattern: feed generators (app.bsky.feed.generator) and labelers (app.bsky.labeler.service)
 type AggregatorEventConsumer struct {
-	repo aggregators.Repository // Repository for aggregator operations
+	repo   aggregators.Repository // Repository for aggregator operations
+	dlq    DeadLetterQueue        // Optional - rejected events are only logged when nil
+	replay *ReplayGuard           // Optional - stale updates are still skipped, but deletes leave no tombstone, when nil
 }
 
 // NewAggregatorEventConsumer creates a new Jetstream consumer for aggregator events
@@ -22,6 +28,16 @@ func NewAggregatorEventConsumer(repo aggregators.Repository) *AggregatorEventCon
 	}
 }
 
+// SetDeadLetterQueue configures where rejected events (mismatched $type, oversized records) are stored
+func (c *AggregatorEventConsumer) SetDeadLetterQueue(dlq DeadLetterQueue) {
+	c.dlq = dlq
+}
+
+// SetReplayGuard configures where skipped stale events are counted and deleted records' revs kept
+func (c *AggregatorEventConsumer) SetReplayGuard(guard *ReplayGuard) {
+	c.replay = guard
+}
+
 // HandleEvent processes a Jetstream event for aggregator records
 // This is called by the main Jetstream consumer when it receives commit events
 func (c *AggregatorEventConsumer) HandleEvent(ctx context.Context, event *JetstreamEvent) error {
@@ -36,6 +52,16 @@ func (c *AggregatorEventConsumer) HandleEvent(ctx context.Context, event *Jetstr
 	// IMPORTANT: Collection names refer to RECORD TYPES in repositories
 	// - social.coves.aggregator.service: Service declaration (in aggregator's own repo, rkey="self")
 	// - social.coves.aggregator.authorization: Authorization (in community's repo, any rkey)
+	switch commit.Collection {
+	case "social.coves.aggregator.service", "social.coves.aggregator.authorization":
+		if rejected, err := guardRecord(ctx, c.dlq, event); rejected {
+			return err
+		}
+	default:
+		// Not an aggregator-related collection
+		return nil
+	}
+
 	switch commit.Collection {
 	case "social.coves.aggregator.service":
 		return c.handleServiceDeclaration(ctx, event.Did, commit)
@@ -55,7 +81,7 @@ func (c *AggregatorEventConsumer) handleServiceDeclaration(ctx context.Context,
 		// Both create and update are handled the same way (upsert)
 		return c.upsertAggregator(ctx, did, commit)
 	case "delete":
-		return c.deleteAggregator(ctx, did)
+		return c.deleteAggregator(ctx, did, commit)
 	default:
 		log.Printf("Unknown operation for aggregator service: %s", commit.Operation)
 		return nil
@@ -95,14 +121,19 @@ func (c *AggregatorEventConsumer) upsertAggregator(ctx context.Context, did stri
 		return fmt.Errorf("failed to parse aggregator service: %w", err)
 	}
 
-	// Validate DID matches repo DID (security check)
-	if service.DID != "" && service.DID != did {
+	// A service declaration only counts in the aggregator's own repo (security check)
+	if service.DID != did {
 		return fmt.Errorf("service record DID (%s) does not match repo DID (%s)", service.DID, did)
 	}
 
 	// Build AT-URI for this record
 	uri := fmt.Sprintf("at://%s/social.coves.aggregator.service/self", did)
 
+	// A write replayed after the aggregator deleted its service declaration must not restore it
+	if deleted, err := c.replay.deletedAfter(ctx, commit, uri); err != nil || deleted {
+		return err
+	}
+
 	// Parse createdAt from service record
 	var createdAt time.Time
 	if service.CreatedAt != "" {
@@ -135,8 +166,12 @@ func (c *AggregatorEventConsumer) upsertAggregator(ctx context.Context, did stri
 		IndexedAt:     time.Now(),
 		RecordURI:     uri,
 		RecordCID:     commit.CID,
+		Rev:           commit.Rev,
 	}
 
+	// Preserve the full record so fields from newer lexicon versions can be backfilled
+	agg.RawRecord = marshalRawRecord(commit.Record)
+
 	// Handle config schema (JSONB)
 	if service.ConfigSchema != nil {
 		schemaBytes, err := json.Marshal(service.ConfigSchema)
@@ -148,6 +183,10 @@ func (c *AggregatorEventConsumer) upsertAggregator(ctx context.Context, did stri
 
 	// Create or update in database
 	if err := c.repo.CreateAggregator(ctx, agg); err != nil {
+		if errors.Is(err, aggregators.ErrStaleRevision) {
+			c.replay.skipped(commit, uri)
+			return nil
+		}
 		return fmt.Errorf("failed to index aggregator: %w", err)
 	}
 
@@ -156,7 +195,22 @@ func (c *AggregatorEventConsumer) upsertAggregator(ctx context.Context, did stri
 }
 
 // deleteAggregator removes an aggregator from the index
-func (c *AggregatorEventConsumer) deleteAggregator(ctx context.Context, did string) error {
+// The deleting rev is kept as a tombstone, so a replay of an older declaration doesn't re-index it
+func (c *AggregatorEventConsumer) deleteAggregator(ctx context.Context, did string, commit *CommitEvent) error {
+	uri := fmt.Sprintf("at://%s/social.coves.aggregator.service/self", did)
+
+	// A delete replayed after the aggregator declared its service again must not remove it
+	existing, err := c.repo.GetAggregator(ctx, did)
+	if err != nil && !aggregators.IsNotFound(err) {
+		return fmt.Errorf("failed to get existing aggregator: %w", err)
+	}
+	if existing != nil && c.replay.stale(commit, uri, existing.Rev) {
+		return nil
+	}
+	if err := c.replay.recordDelete(ctx, commit, uri); err != nil {
+		return err
+	}
+
 	// Delete from database (cascade deletes authorizations and posts via FK)
 	if err := c.repo.DeleteAggregator(ctx, did); err != nil {
 		// Log but don't fail if not found (idempotent delete)
@@ -192,6 +246,11 @@ func (c *AggregatorEventConsumer) upsertAuthorization(ctx context.Context, commu
 	// Build AT-URI for this record
 	uri := fmt.Sprintf("at://%s/social.coves.aggregator.authorization/%s", communityDID, commit.RKey)
 
+	// A write replayed after the community deleted the authorization must not restore it
+	if deleted, err := c.replay.deletedAfter(ctx, commit, uri); err != nil || deleted {
+		return err
+	}
+
 	// Parse createdAt from authorization record
 	var createdAt time.Time
 	if authRecord.CreatedAt != "" {
@@ -227,8 +286,16 @@ func (c *AggregatorEventConsumer) upsertAuthorization(ctx context.Context, commu
 		IndexedAt:     time.Now(),
 		RecordURI:     uri,
 		RecordCID:     commit.CID,
+		Rev:           commit.Rev,
 	}
 
+	// Post limits apply to the aggregator's next post; invalid values fall back to the defaults
+	auth.MaxPostsPerHour = validPostLimit(authRecord.MaxPostsPerHour, "maxPostsPerHour", uri)
+	auth.MaxPostsPerDay = validPostLimit(authRecord.MaxPostsPerDay, "maxPostsPerDay", uri)
+
+	// Preserve the full record so fields from newer lexicon versions can be backfilled
+	auth.RawRecord = marshalRawRecord(commit.Record)
+
 	// Handle config (JSONB)
 	if authRecord.Config != nil {
 		configBytes, err := json.Marshal(authRecord.Config)
@@ -240,6 +307,10 @@ func (c *AggregatorEventConsumer) upsertAuthorization(ctx context.Context, commu
 
 	// Create or update in database
 	if err := c.repo.CreateAuthorization(ctx, auth); err != nil {
+		if errors.Is(err, aggregators.ErrStaleRevision) {
+			c.replay.skipped(commit, uri)
+			return nil
+		}
 		return fmt.Errorf("failed to index authorization: %w", err)
 	}
 
@@ -253,6 +324,18 @@ func (c *AggregatorEventConsumer) deleteAuthorization(ctx context.Context, commu
 	// Build AT-URI to find the authorization
 	uri := fmt.Sprintf("at://%s/social.coves.aggregator.authorization/%s", communityDID, commit.RKey)
 
+	// A delete replayed after the authorization was written again must not remove it
+	existing, err := c.repo.GetAuthorizationByURI(ctx, uri)
+	if err != nil && !aggregators.IsNotFound(err) {
+		return fmt.Errorf("failed to get existing authorization: %w", err)
+	}
+	if existing != nil && c.replay.stale(commit, uri, existing.Rev) {
+		return nil
+	}
+	if err := c.replay.recordDelete(ctx, commit, uri); err != nil {
+		return err
+	}
+
 	// Delete from database
 	if err := c.repo.DeleteAuthorizationByURI(ctx, uri); err != nil {
 		// Log but don't fail if not found (idempotent delete)
@@ -294,27 +377,63 @@ func parseAggregatorService(record interface{}) (*AggregatorServiceRecord, error
 		return nil, fmt.Errorf("failed to unmarshal service record: %w", err)
 	}
 
-	// Validate required fields
-	if service.DisplayName == "" {
-		return nil, fmt.Errorf("displayName is required")
+	if err := validateAggregatorService(&service); err != nil {
+		return nil, err
 	}
 
 	return &service, nil
 }
 
+// validateAggregatorService enforces the service declaration limits shown in the public directory
+func validateAggregatorService(service *AggregatorServiceRecord) error {
+	if service.DID == "" {
+		return fmt.Errorf("did is required")
+	}
+	if strings.TrimSpace(service.DisplayName) == "" {
+		return fmt.Errorf("displayName is required")
+	}
+	if n := utf8.RuneCountInString(service.DisplayName); n > aggregators.MaxDisplayNameLength {
+		return fmt.Errorf("displayName is %d characters, maximum is %d", n, aggregators.MaxDisplayNameLength)
+	}
+	if n := utf8.RuneCountInString(service.Description); n > aggregators.MaxDescriptionLength {
+		return fmt.Errorf("description is %d characters, maximum is %d", n, aggregators.MaxDescriptionLength)
+	}
+	if service.SourceURL != "" {
+		u, err := url.Parse(service.SourceURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("sourceUrl must be an absolute http(s) URL")
+		}
+	}
+	return nil
+}
+
 // Note: extractBlobCID is defined in community_consumer.go and shared across consumers
 
 // AggregatorAuthorizationRecord represents the authorization record structure
 type AggregatorAuthorizationRecord struct {
-	Config       map[string]interface{} `json:"config,omitempty"`
-	Type         string                 `json:"$type"`
-	Aggregator   string                 `json:"aggregatorDid"`
-	CommunityDid string                 `json:"communityDid"`
-	CreatedBy    string                 `json:"createdBy"`
-	DisabledBy   string                 `json:"disabledBy,omitempty"`
-	DisabledAt   string                 `json:"disabledAt,omitempty"`
-	CreatedAt    string                 `json:"createdAt"`
-	Enabled      bool                   `json:"enabled"`
+	Config          map[string]interface{} `json:"config,omitempty"`
+	MaxPostsPerHour *int                   `json:"maxPostsPerHour,omitempty"`
+	MaxPostsPerDay  *int                   `json:"maxPostsPerDay,omitempty"`
+	Type            string                 `json:"$type"`
+	Aggregator      string                 `json:"aggregatorDid"`
+	CommunityDid    string                 `json:"communityDid"`
+	CreatedBy       string                 `json:"createdBy"`
+	DisabledBy      string                 `json:"disabledBy,omitempty"`
+	DisabledAt      string                 `json:"disabledAt,omitempty"`
+	CreatedAt       string                 `json:"createdAt"`
+	Enabled         bool                   `json:"enabled"`
+}
+
+// validPostLimit returns a post limit from an authorization record, or nil if it's unset or below 1
+func validPostLimit(limit *int, field, uri string) *int {
+	if limit == nil {
+		return nil
+	}
+	if *limit < 1 {
+		log.Printf("Warning: ignoring invalid %s %d for authorization %s", field, *limit, uri)
+		return nil
+	}
+	return limit
 }
 
 // parseAggregatorAuthorization parses an aggregator authorization record
@@ -0,0 +1,133 @@
+package jetstream
+
+import (
+	"Coves/internal/core/aggregators"
+	"context"
+	"strings"
+	"testing"
+)
+
+func validServiceRecord() map[string]interface{} {
+	return map[string]interface{}{
+		"$type":       "social.coves.aggregator.service",
+		"did":         "did:plc:aggregator",
+		"displayName": "RSS Bot",
+		"description": "Posts headlines from RSS feeds",
+		"sourceUrl":   "https://github.com/example/rss-bot",
+		"createdAt":   "2025-01-01T00:00:00Z",
+	}
+}
+
+func TestParseAggregatorService_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(record map[string]interface{})
+		wantErr string
+	}{
+		{name: "valid", modify: func(map[string]interface{}) {}},
+		{name: "no source URL", modify: func(r map[string]interface{}) { delete(r, "sourceUrl") }},
+		{name: "display name at limit", modify: func(r map[string]interface{}) { r["displayName"] = strings.Repeat("é", 64) }},
+		{name: "description at limit", modify: func(r map[string]interface{}) { r["description"] = strings.Repeat("d", 1000) }},
+
+		{name: "missing did", modify: func(r map[string]interface{}) { delete(r, "did") }, wantErr: "did is required"},
+		{name: "blank display name", modify: func(r map[string]interface{}) { r["displayName"] = "   " }, wantErr: "displayName is required"},
+		{name: "display name too long", modify: func(r map[string]interface{}) { r["displayName"] = strings.Repeat("é", 65) }, wantErr: "displayName"},
+		{name: "description too long", modify: func(r map[string]interface{}) { r["description"] = strings.Repeat("d", 1001) }, wantErr: "description"},
+		{name: "relative source URL", modify: func(r map[string]interface{}) { r["sourceUrl"] = "github.com/example" }, wantErr: "sourceUrl"},
+		{name: "non-http source URL", modify: func(r map[string]interface{}) { r["sourceUrl"] = "javascript:alert(1)" }, wantErr: "sourceUrl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := validServiceRecord()
+			tt.modify(record)
+			_, err := parseAggregatorService(record)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected record to be accepted, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected error containing %q, got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAggregatorConsumer_RejectsServiceInAnotherRepo(t *testing.T) {
+	// No repository: the record must be rejected before anything is written
+	consumer := NewAggregatorEventConsumer(nil)
+
+	event := newTestCommitEvent("did:plc:someone-else", "social.coves.aggregator.service", "self", validServiceRecord())
+	err := consumer.HandleEvent(context.Background(), event)
+	if err == nil || !strings.Contains(err.Error(), "does not match repo DID") {
+		t.Fatalf("expected a repo DID mismatch error, got: %v", err)
+	}
+}
+
+// authorizationCaptureRepo records the last authorization written by the consumer
+type authorizationCaptureRepo struct {
+	aggregators.Repository
+	auth *aggregators.Authorization
+}
+
+func (r *authorizationCaptureRepo) CreateAuthorization(ctx context.Context, auth *aggregators.Authorization) error {
+	r.auth = auth
+	return nil
+}
+
+func TestAggregatorConsumer_AuthorizationPostLimits(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	tests := []struct {
+		name     string
+		limits   map[string]interface{}
+		wantDay  *int
+		wantHour *int
+	}{
+		{name: "unset", limits: map[string]interface{}{}},
+		{name: "daily only", limits: map[string]interface{}{"maxPostsPerDay": 50}, wantDay: intPtr(50)},
+		{name: "both", limits: map[string]interface{}{"maxPostsPerDay": 50, "maxPostsPerHour": 5}, wantDay: intPtr(50), wantHour: intPtr(5)},
+		{name: "invalid values ignored", limits: map[string]interface{}{"maxPostsPerDay": 0, "maxPostsPerHour": -3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &authorizationCaptureRepo{}
+			consumer := NewAggregatorEventConsumer(repo)
+			record := map[string]interface{}{
+				"$type":         "social.coves.aggregator.authorization",
+				"aggregatorDid": "did:plc:aggregator",
+				"communityDid":  "did:plc:community",
+				"createdBy":     "did:plc:moderator",
+				"createdAt":     "2025-01-01T00:00:00Z",
+				"enabled":       true,
+			}
+			for field, value := range tt.limits {
+				record[field] = value
+			}
+
+			event := newTestCommitEvent("did:plc:community", "social.coves.aggregator.authorization", "self", record)
+			if err := consumer.HandleEvent(context.Background(), event); err != nil {
+				t.Fatalf("HandleEvent failed: %v", err)
+			}
+			if repo.auth == nil {
+				t.Fatal("expected the authorization to be indexed")
+			}
+			assertPostLimit(t, "maxPostsPerHour", tt.wantHour, repo.auth.MaxPostsPerHour)
+			assertPostLimit(t, "maxPostsPerDay", tt.wantDay, repo.auth.MaxPostsPerDay)
+		})
+	}
+}
+
+func assertPostLimit(t *testing.T, field string, want, got *int) {
+	t.Helper()
+	switch {
+	case want == nil && got != nil:
+		t.Errorf("expected %s to be unset, got %d", field, *got)
+	case want != nil && got == nil:
+		t.Errorf("expected %s %d, got unset", field, *want)
+	case want != nil && *want != *got:
+		t.Errorf("expected %s %d, got %d", field, *want, *got)
+	}
+}
@@ -0,0 +1,78 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/identity"
+	"Coves/internal/core/users"
+	"context"
+	"fmt"
+	"log"
+)
+
+// AuthorIndexer indexes authors the user consumer hasn't seen yet
+// Posts and comments can arrive from accounts whose identity or profile events were missed, or
+// before the user consumer catches up. Rather than rejecting the post, the author's DID is
+// resolved to a handle and PDS and a minimal users row is created with its profile pending;
+// the profile backfill fills in display name and avatar later.
+type AuthorIndexer struct {
+	userService users.UserService
+	resolver    interface {
+		Resolve(context.Context, string) (*identity.Identity, error)
+	}
+	profiles *ProfileBackfiller // Optional - pending profiles are left to the backfill sweep when nil
+}
+
+// NewAuthorIndexer creates an AuthorIndexer that resolves unknown DIDs with resolver
+// profiles may be nil.
+func NewAuthorIndexer(userService users.UserService, resolver interface {
+	Resolve(context.Context, string) (*identity.Identity, error)
+}, profiles *ProfileBackfiller,
+) *AuthorIndexer {
+	return &AuthorIndexer{
+		userService: userService,
+		resolver:    resolver,
+		profiles:    profiles,
+	}
+}
+
+// EnsureUser makes sure did has a users row, indexing it if it's unknown
+func (a *AuthorIndexer) EnsureUser(ctx context.Context, did string) error {
+	_, err := a.userService.GetUserByDID(ctx, did)
+	if err == nil {
+		return nil
+	}
+	if !users.IsNotFound(err) {
+		return fmt.Errorf("failed to look up author: %w", err)
+	}
+	_, err = a.IndexUser(ctx, did)
+	return err
+}
+
+// IndexUser creates a minimal users row for a DID that isn't indexed yet
+// Safe to race with other consumers: creating an existing user returns it unchanged.
+func (a *AuthorIndexer) IndexUser(ctx context.Context, did string) (*users.User, error) {
+	ident, err := a.resolver.Resolve(ctx, did)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve author %s: %w", did, err)
+	}
+	if ident == nil || ident.Handle == "" || ident.PDSURL == "" {
+		return nil, fmt.Errorf("author %s has no handle or PDS in its DID document", did)
+	}
+
+	user, err := a.userService.CreateUser(ctx, users.CreateUserRequest{
+		DID:            did,
+		Handle:         ident.Handle,
+		PDSURL:         ident.PDSURL,
+		ProfilePending: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to index author %s: %w", did, err)
+	}
+
+	if user.ProfilePending {
+		log.Printf("Indexed unknown author %s (%s); profile pending", did, ident.Handle)
+		if a.profiles != nil {
+			a.profiles.Enqueue(user)
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,107 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/identity"
+	"Coves/internal/core/users"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubAuthorResolver resolves DIDs from a fixed table and counts lookups
+type stubAuthorResolver struct {
+	identities map[string]*identity.Identity
+	calls      int
+}
+
+func (r *stubAuthorResolver) Resolve(ctx context.Context, identifier string) (*identity.Identity, error) {
+	r.calls++
+	ident, ok := r.identities[identifier]
+	if !ok {
+		return nil, errors.New("DID not found")
+	}
+	return ident, nil
+}
+
+// creatingUserService records the users CreateUser indexes
+type creatingUserService struct {
+	mockUserService
+	created []users.CreateUserRequest
+}
+
+func newCreatingUserService() *creatingUserService {
+	return &creatingUserService{mockUserService: *newMockUserService()}
+}
+
+func (s *creatingUserService) CreateUser(ctx context.Context, req users.CreateUserRequest) (*users.User, error) {
+	if existing, ok := s.users[req.DID]; ok {
+		return existing, nil
+	}
+	s.created = append(s.created, req)
+	user := &users.User{DID: req.DID, Handle: req.Handle, PDSURL: req.PDSURL, ProfilePending: req.ProfilePending}
+	s.users[req.DID] = user
+	return user, nil
+}
+
+func TestAuthorIndexer_IndexesUnknownAuthor(t *testing.T) {
+	userService := newCreatingUserService()
+	resolver := &stubAuthorResolver{identities: map[string]*identity.Identity{
+		"did:plc:newcomer": {DID: "did:plc:newcomer", Handle: "newcomer.example.com", PDSURL: "https://pds.example.com"},
+	}}
+	profiles := NewProfileBackfiller(&stubProfileStore{}, &stubProfileFetcher{}, time.Hour)
+	indexer := NewAuthorIndexer(userService, resolver, profiles)
+
+	if err := indexer.EnsureUser(context.Background(), "did:plc:newcomer"); err != nil {
+		t.Fatalf("EnsureUser failed: %v", err)
+	}
+
+	if len(userService.created) != 1 {
+		t.Fatalf("Expected one user to be created, got %d", len(userService.created))
+	}
+	req := userService.created[0]
+	if req.Handle != "newcomer.example.com" || req.PDSURL != "https://pds.example.com" {
+		t.Errorf("Expected the resolved handle and PDS, got %q and %q", req.Handle, req.PDSURL)
+	}
+	if !req.ProfilePending {
+		t.Error("Expected the user to be created with its profile pending")
+	}
+
+	select {
+	case queued := <-profiles.queue:
+		if queued.DID != "did:plc:newcomer" {
+			t.Errorf("Expected did:plc:newcomer to be queued for profile backfill, got %s", queued.DID)
+		}
+	default:
+		t.Error("Expected the new user to be queued for profile backfill")
+	}
+}
+
+func TestAuthorIndexer_KnownAuthorIsNotResolved(t *testing.T) {
+	userService := newCreatingUserService()
+	userService.users["did:plc:known"] = &users.User{DID: "did:plc:known", Handle: "known.example.com"}
+	resolver := &stubAuthorResolver{}
+	indexer := NewAuthorIndexer(userService, resolver, nil)
+
+	if err := indexer.EnsureUser(context.Background(), "did:plc:known"); err != nil {
+		t.Fatalf("EnsureUser failed: %v", err)
+	}
+	if resolver.calls != 0 {
+		t.Errorf("Expected no identity lookups for a known user, got %d", resolver.calls)
+	}
+	if len(userService.created) != 0 {
+		t.Errorf("Expected no users to be created, got %d", len(userService.created))
+	}
+}
+
+func TestAuthorIndexer_UnresolvableAuthor(t *testing.T) {
+	userService := newCreatingUserService()
+	indexer := NewAuthorIndexer(userService, &stubAuthorResolver{}, nil)
+
+	if err := indexer.EnsureUser(context.Background(), "did:plc:ghost"); err == nil {
+		t.Fatal("Expected an error for a DID that doesn't resolve")
+	}
+	if len(userService.created) != 0 {
+		t.Errorf("Expected no users to be created, got %d", len(userService.created))
+	}
+}
@@ -0,0 +1,89 @@
+package jetstream
+
+import (
+	"Coves/internal/core/automod"
+	"Coves/internal/core/posts"
+	"context"
+	"log"
+	"strings"
+)
+
+// linkFeatureType is the lexicon identifier of link facets
+const linkFeatureType = "social.coves.richtext.facet#link"
+
+// AutomodEvaluator checks new content against its community's automod rules.
+// Implemented by automod.Service.
+type AutomodEvaluator interface {
+	Evaluate(ctx context.Context, communityDID string, content automod.Content) (*automod.Match, error)
+	RecordAction(ctx context.Context, record *automod.ActionRecord) error
+}
+
+// SetAutomod configures the community rules new posts are checked against
+func (c *PostEventConsumer) SetAutomod(evaluator AutomodEvaluator) {
+	c.automod = evaluator
+}
+
+// SetAutomod configures the community rules new comments are checked against
+func (c *CommentEventConsumer) SetAutomod(evaluator AutomodEvaluator) {
+	c.automod = evaluator
+}
+
+// evaluateAutomod checks content against the community's rules
+// A failing check never blocks indexing; the content is indexed as if nothing matched.
+func evaluateAutomod(ctx context.Context, evaluator AutomodEvaluator, communityDID, subjectURI string, content automod.Content) *automod.Match {
+	if evaluator == nil {
+		return nil
+	}
+	match, err := evaluator.Evaluate(ctx, communityDID, content)
+	if err != nil {
+		log.Printf("Warning: Automod check failed for %s, indexing without it: %v", subjectURI, err)
+		return nil
+	}
+	return match
+}
+
+// recordAutomodAction stores the audit row of an action applied to newly indexed content
+// Best-effort: the content's status is already applied, so a failure is only logged.
+func recordAutomodAction(ctx context.Context, evaluator AutomodEvaluator, match *automod.Match, subjectURI, communityDID, authorDID string) {
+	record := automod.NewActionRecord(match, subjectURI, communityDID, authorDID)
+	if err := evaluator.RecordAction(ctx, record); err != nil {
+		log.Printf("Warning: Failed to record automod %s of %s: %v", match.Action(), subjectURI, err)
+		return
+	}
+	log.Printf("Automod %s on %s (%s rule %q in %s)",
+		match.Action(), subjectURI, match.Rule.MatchType, match.Rule.Pattern, communityDID)
+}
+
+// automodContent collects the text and links of a post or comment for rule evaluation
+func automodContent(text []string, facets []interface{}, embed map[string]interface{}) automod.Content {
+	content := automod.Content{Text: strings.Join(text, "\n"), Links: facetLinks(facets)}
+	if link := posts.ExternalEmbedURI(embed); link != "" {
+		content.Links = append(content.Links, link)
+	}
+	return content
+}
+
+// facetLinks returns the URIs of link facets
+func facetLinks(facets []interface{}) []string {
+	var links []string
+	for _, raw := range facets {
+		facet, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		features, _ := facet["features"].([]interface{})
+		for _, rawFeature := range features {
+			feature, ok := rawFeature.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if featureType, _ := feature["$type"].(string); featureType != linkFeatureType {
+				continue
+			}
+			if uri, _ := feature["uri"].(string); uri != "" {
+				links = append(links, uri)
+			}
+		}
+	}
+	return links
+}
@@ -0,0 +1,59 @@
+package jetstream
+
+import (
+	"Coves/internal/core/blobrefs"
+	"context"
+	"log"
+)
+
+// BlobReferenceTracker records which blobs indexed records reference, so the image proxy can
+// refuse blobs left referenced only by hidden content. Implemented by blobrefs.Repository.
+type BlobReferenceTracker interface {
+	SetReferences(ctx context.Context, recordURI string, cids []string) error
+	Refresh(ctx context.Context, recordURI string) error
+}
+
+// SetBlobReferences configures where the blobs referenced by indexed posts are tracked
+func (c *PostEventConsumer) SetBlobReferences(tracker BlobReferenceTracker) {
+	c.blobRefs = tracker
+}
+
+// SetBlobReferences configures where the blobs referenced by indexed comments are tracked
+func (c *CommentEventConsumer) SetBlobReferences(tracker BlobReferenceTracker) {
+	c.blobRefs = tracker
+}
+
+// SetBlobReferences configures where community avatars and banners are tracked as blob references
+func (c *CommunityEventConsumer) SetBlobReferences(tracker BlobReferenceTracker) {
+	c.blobRefs = tracker
+}
+
+// WithBlobReferences sets where user avatars and banners are tracked as blob references
+func WithBlobReferences(tracker BlobReferenceTracker) ConsumerOption {
+	return func(c *UserEventConsumer) {
+		c.blobRefs = tracker
+	}
+}
+
+// trackBlobReferences records the blobs an indexed record references
+// Best-effort: a failure is logged and never fails the event, leaving the record's blobs
+// unblockable until it's indexed again.
+func trackBlobReferences(ctx context.Context, tracker BlobReferenceTracker, uri string, record map[string]interface{}) {
+	if tracker == nil {
+		return
+	}
+	if err := tracker.SetReferences(ctx, uri, blobrefs.CIDs(record)); err != nil {
+		log.Printf("Warning: Failed to track blob references of %s: %v", uri, err)
+	}
+}
+
+// refreshBlobReferences re-evaluates the block state of a record's blobs after it was deleted
+// Best-effort, like trackBlobReferences.
+func refreshBlobReferences(ctx context.Context, tracker BlobReferenceTracker, uri string) {
+	if tracker == nil {
+		return
+	}
+	if err := tracker.Refresh(ctx, uri); err != nil {
+		log.Printf("Warning: Failed to refresh blob references of %s: %v", uri, err)
+	}
+}
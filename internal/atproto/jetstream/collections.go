@@ -0,0 +1,171 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/lexicon"
+	"Coves/internal/core/users"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ConsumerRoutes is what one Jetstream consumer handles: the record collections it indexes
+// and any non-commit event kinds (EventKindIdentity, EventKindAccount)
+// Subscribe URLs and dispatcher registrations are built from these, so a collection name is
+// written down once and checked against the lexicons at startup (CheckCollections).
+type ConsumerRoutes struct {
+	Name        string
+	Collections []string
+	EventKinds  []string
+}
+
+// Routes returns the collections followed by the event kinds, as JetstreamDispatcher.Register takes them
+func (c ConsumerRoutes) Routes() []string {
+	routes := make([]string, 0, len(c.Collections)+len(c.EventKinds))
+	routes = append(routes, c.Collections...)
+	return append(routes, c.EventKinds...)
+}
+
+// SubscribeURL returns the subscribe URL on baseURL for the consumer's collections
+// Any wantedCollections already in baseURL are replaced.
+func (c ConsumerRoutes) SubscribeURL(baseURL string) (string, error) {
+	return subscribeURL(baseURL, c.Collections, 0)
+}
+
+// Collections handled by each consumer
+// Collection names are repository record types, not XRPC methods: users subscribe to a
+// community by creating a social.coves.community.subscription record, while
+// social.coves.community.subscribe is the procedure that writes it.
+var (
+	UserConsumerRoutes = ConsumerRoutes{
+		Name:        "User",
+		Collections: []string{CovesProfileCollection, users.PreferencesCollection},
+		EventKinds:  []string{EventKindIdentity, EventKindAccount},
+	}
+	CommunityConsumerRoutes = ConsumerRoutes{
+		Name: "Community",
+		Collections: []string{
+			"social.coves.community.profile", "social.coves.community.subscription",
+			"social.coves.community.block", "social.coves.community.rules",
+		},
+	}
+	PostConsumerRoutes = ConsumerRoutes{
+		Name:        "Post",
+		Collections: []string{PostCollection},
+	}
+	AggregatorConsumerRoutes = ConsumerRoutes{
+		Name:        "Aggregator",
+		Collections: []string{"social.coves.aggregator.service", "social.coves.aggregator.authorization"},
+	}
+	VoteConsumerRoutes = ConsumerRoutes{
+		Name:        "Vote",
+		Collections: []string{"social.coves.feed.vote"},
+	}
+	CommentConsumerRoutes = ConsumerRoutes{
+		Name:        "Comment",
+		Collections: []string{CommentCollection},
+	}
+)
+
+// ConsumerRegistry lists every Jetstream consumer the server runs
+var ConsumerRegistry = []ConsumerRoutes{
+	UserConsumerRoutes,
+	CommunityConsumerRoutes,
+	PostConsumerRoutes,
+	AggregatorConsumerRoutes,
+	VoteConsumerRoutes,
+	CommentConsumerRoutes,
+}
+
+// CollectionConsumers maps each collection in registry to the name of the consumer handling it
+func CollectionConsumers(registry []ConsumerRoutes) map[string]string {
+	consumers := make(map[string]string)
+	for _, consumer := range registry {
+		for _, collection := range consumer.Collections {
+			consumers[collection] = consumer.Name
+		}
+	}
+	return consumers
+}
+
+// CheckCollections cross-references registry against the lexicon schemas
+// Every collection must be a record lexicon and belong to one consumer; every event kind must
+// be one Jetstream sends. The error lists each mismatch.
+func CheckCollections(registry []ConsumerRoutes, schemas lexicon.Schemas) error {
+	var problems []string
+	owners := make(map[string]string)
+	for _, consumer := range registry {
+		if len(consumer.Collections) == 0 && len(consumer.EventKinds) == 0 {
+			problems = append(problems, fmt.Sprintf("consumer %s handles no collections", consumer.Name))
+		}
+		for _, collection := range consumer.Collections {
+			if owner, ok := owners[collection]; ok {
+				problems = append(problems, fmt.Sprintf("consumer %s: %s is already handled by consumer %s", consumer.Name, collection, owner))
+				continue
+			}
+			owners[collection] = consumer.Name
+
+			schema, ok := schemas[collection]
+			switch {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("consumer %s: collection %s has no lexicon%s", consumer.Name, collection, suggestRecord(collection, schemas)))
+			case schema.MainType != lexicon.TypeRecord:
+				problems = append(problems, fmt.Sprintf("consumer %s: collection %s is a %s lexicon (%s), not a record%s",
+					consumer.Name, collection, mainTypeName(schema), schema.Path, suggestRecord(collection, schemas)))
+			}
+		}
+		for _, kind := range consumer.EventKinds {
+			if kind != EventKindIdentity && kind != EventKindAccount {
+				problems = append(problems, fmt.Sprintf("consumer %s: unknown event kind %q", consumer.Name, kind))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("jetstream collections don't match the lexicons:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+func mainTypeName(schema lexicon.Schema) string {
+	if schema.MainType == "" {
+		return "definitions-only"
+	}
+	return schema.MainType
+}
+
+// suggestRecord names the record lexicons in the same namespace as collection, for error messages
+func suggestRecord(collection string, schemas lexicon.Schemas) string {
+	namespace := collection[:strings.LastIndex(collection, ".")+1]
+	var records []string
+	for id, schema := range schemas {
+		if schema.MainType == lexicon.TypeRecord && namespace != "" && strings.HasPrefix(id, namespace) {
+			records = append(records, id)
+		}
+	}
+	if len(records) == 0 {
+		return ""
+	}
+	sort.Strings(records)
+	return " (records in " + strings.TrimSuffix(namespace, ".") + ": " + strings.Join(records, ", ") + ")"
+}
+
+// subscribeURL sets wantedCollections (and the replay cursor, when positive) on a Jetstream
+// subscribe URL
+func subscribeURL(baseURL string, collections []string, cursor int64) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid Jetstream URL: %w", err)
+	}
+
+	query := u.Query()
+	query.Del("wantedCollections")
+	for _, collection := range collections {
+		query.Add("wantedCollections", collection)
+	}
+	if cursor > 0 {
+		query.Set("cursor", strconv.FormatInt(cursor, 10))
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
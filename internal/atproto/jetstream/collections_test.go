@@ -0,0 +1,119 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/lexicon"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func loadLexicons(t *testing.T) lexicon.Schemas {
+	t.Helper()
+	schemas, err := lexicon.Load()
+	if err != nil {
+		t.Fatalf("lexicon.Load() error = %v", err)
+	}
+	return schemas
+}
+
+func TestCheckCollections_Registry(t *testing.T) {
+	if err := CheckCollections(ConsumerRegistry, loadLexicons(t)); err != nil {
+		t.Fatalf("consumer registry doesn't match the lexicons: %v", err)
+	}
+}
+
+func TestCheckCollections_Typos(t *testing.T) {
+	schemas := loadLexicons(t)
+
+	tests := []struct {
+		name     string
+		consumer ConsumerRoutes
+		want     []string
+	}{
+		{
+			name:     "procedure instead of record",
+			consumer: ConsumerRoutes{Name: "Community", Collections: []string{"social.coves.community.profile", "social.coves.community.subscribe"}},
+			want: []string{
+				"consumer Community: collection social.coves.community.subscribe is a procedure lexicon",
+				"social.coves.community.subscription",
+			},
+		},
+		{
+			name:     "wrong namespace",
+			consumer: ConsumerRoutes{Name: "Comment", Collections: []string{"social.coves.feed.comment"}},
+			want: []string{
+				"consumer Comment: collection social.coves.feed.comment has no lexicon",
+				"records in social.coves.feed: social.coves.feed.vote",
+			},
+		},
+		{
+			name:     "unknown event kind",
+			consumer: ConsumerRoutes{Name: "User", Collections: []string{CovesProfileCollection}, EventKinds: []string{"identities"}},
+			want:     []string{`consumer User: unknown event kind "identities"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := append([]ConsumerRoutes{}, ConsumerRegistry...)
+			for i := range registry {
+				if registry[i].Name == tt.consumer.Name {
+					registry[i] = tt.consumer
+				}
+			}
+
+			err := CheckCollections(registry, schemas)
+			if err == nil {
+				t.Fatal("CheckCollections() passed a registry with a typo")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q\ndoes not mention %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckCollections_DuplicateOwner(t *testing.T) {
+	registry := append([]ConsumerRoutes{}, ConsumerRegistry...)
+	registry = append(registry, ConsumerRoutes{Name: "Extra", Collections: []string{VoteConsumerRoutes.Collections[0]}})
+
+	err := CheckCollections(registry, loadLexicons(t))
+	if err == nil || !strings.Contains(err.Error(), "already handled by consumer Vote") {
+		t.Errorf("CheckCollections() error = %v, want a duplicate collection", err)
+	}
+}
+
+func TestConsumerRoutes_SubscribeURL(t *testing.T) {
+	// wantedCollections already in an override URL are replaced with the registered ones
+	wsURL, err := CommunityConsumerRoutes.SubscribeURL("wss://jetstream.example/subscribe?wantedCollections=social.coves.community.subscribe&compress=true")
+	if err != nil {
+		t.Fatalf("SubscribeURL() error = %v", err)
+	}
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Host != "jetstream.example" || u.Path != "/subscribe" || u.Query().Get("compress") != "true" {
+		t.Errorf("SubscribeURL() = %s, want the base URL kept", wsURL)
+	}
+	got := u.Query()["wantedCollections"]
+	if strings.Join(got, ",") != strings.Join(CommunityConsumerRoutes.Collections, ",") {
+		t.Errorf("wantedCollections = %v, want %v", got, CommunityConsumerRoutes.Collections)
+	}
+
+	if _, err := PostConsumerRoutes.SubscribeURL("://bad"); err == nil {
+		t.Error("SubscribeURL() accepted an invalid URL")
+	}
+}
+
+func TestCollectionConsumers(t *testing.T) {
+	consumers := CollectionConsumers(ConsumerRegistry)
+	if consumers["social.coves.community.block"] != "Community" || consumers[CommentCollection] != "Comment" {
+		t.Errorf("CollectionConsumers() = %v", consumers)
+	}
+	if _, ok := consumers[EventKindIdentity]; ok {
+		t.Error("event kinds are not collections")
+	}
+}
@@ -1,11 +1,16 @@
 package jetstream
 
 import (
+	"Coves/internal/atproto/identity"
 	"Coves/internal/atproto/utils"
+	"Coves/internal/core/automod"
 	"Coves/internal/core/comments"
+	"Coves/internal/core/live"
+	"Coves/internal/lexicon/validate"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -14,24 +19,26 @@ import (
 	"github.com/lib/pq"
 )
 
-// Constants for comment validation and processing
-const (
-	// CommentCollection is the lexicon collection identifier for comments
-	CommentCollection = "social.coves.community.comment"
-
-	// ATProtoScheme is the URI scheme for atProto AT-URIs
-	ATProtoScheme = "at://"
-
-	// MaxCommentContentBytes is the maximum allowed size for comment content
-	// Per lexicon: max 3000 graphemes, ~30000 bytes
-	MaxCommentContentBytes = 30000
-)
+// CommentCollection is the lexicon collection identifier for comments
+const CommentCollection = "social.coves.community.comment"
 
 // CommentEventConsumer consumes comment-related events from Jetstream
 // Handles CREATE, UPDATE, and DELETE operations for social.coves.community.comment
 type CommentEventConsumer struct {
-	commentRepo comments.Repository
-	db          *sql.DB // Direct DB access for atomic count updates
+	commentRepo     comments.Repository
+	dlq             DeadLetterQueue      // Optional - rejected events are only logged when nil
+	publisher       live.Publisher       // Optional - new comments aren't streamed to clients when nil
+	activity        ActivityRecorder     // Optional - commenters aren't counted as community actives when nil
+	automod         AutomodEvaluator     // Optional - community automod rules aren't applied when nil
+	mentionResolver *mentionResolver     // Optional - @handle mentions are not processed when nil
+	postFetcher     PostFetcher          // Optional - missing root posts are not backfilled when nil
+	postConsumer    *PostEventConsumer   // Indexes backfilled root posts (set with postFetcher)
+	authors         *AuthorIndexer       // Optional - unknown commenters are hydrated by DID only when nil
+	replay          *ReplayGuard         // Optional - stale events are still skipped, but not counted, when nil
+	blobRefs        BlobReferenceTracker // Optional - referenced blobs aren't tracked for the image proxy when nil
+	db              *sql.DB              // Direct DB access for atomic count updates
+	maxThreadDepth  int                  // Comments deeper than this are marked depth_exceeded
+	metrics         commentMetrics       // Out-of-order reconciliation counters, exposed to admins
 }
 
 // NewCommentEventConsumer creates a new Jetstream consumer for comment events
@@ -40,11 +47,40 @@ func NewCommentEventConsumer(
 	db *sql.DB,
 ) *CommentEventConsumer {
 	return &CommentEventConsumer{
-		commentRepo: commentRepo,
-		db:          db,
+		commentRepo:    commentRepo,
+		db:             db,
+		maxThreadDepth: comments.DefaultMaxThreadDepth,
 	}
 }
 
+// SetDeadLetterQueue configures where rejected events (mismatched $type, oversized records) are stored
+func (c *CommentEventConsumer) SetDeadLetterQueue(dlq DeadLetterQueue) {
+	c.dlq = dlq
+}
+
+// SetAuthorIndexer enables on-demand indexing of commenters the user consumer hasn't seen
+func (c *CommentEventConsumer) SetAuthorIndexer(authors *AuthorIndexer) {
+	c.authors = authors
+}
+
+// SetReplayGuard configures where skipped stale events are counted
+func (c *CommentEventConsumer) SetReplayGuard(guard *ReplayGuard) {
+	c.replay = guard
+}
+
+// SetPublisher configures where newly indexed comments are announced to live clients
+func (c *CommentEventConsumer) SetPublisher(publisher live.Publisher) {
+	c.publisher = publisher
+}
+
+// SetIdentityResolver enables @handle mention parsing, resolving handles with the given resolver
+func (c *CommentEventConsumer) SetIdentityResolver(resolver interface {
+	Resolve(context.Context, string) (*identity.Identity, error)
+},
+) {
+	c.mentionResolver = newMentionResolver(resolver)
+}
+
 // HandleEvent processes a Jetstream event for comment records
 func (c *CommentEventConsumer) HandleEvent(ctx context.Context, event *JetstreamEvent) error {
 	// We only care about commit events for comment records
@@ -56,6 +92,10 @@ func (c *CommentEventConsumer) HandleEvent(ctx context.Context, event *Jetstream
 
 	// Handle comment record operations
 	if commit.Collection == CommentCollection {
+		if rejected, err := guardRecord(ctx, c.dlq, event); rejected {
+			return err
+		}
+
 		switch commit.Operation {
 		case "create":
 			return c.createComment(ctx, event.Did, commit)
@@ -83,11 +123,25 @@ func (c *CommentEventConsumer) createComment(ctx context.Context, repoDID string
 	}
 
 	// SECURITY: Validate this is a legitimate comment event
-	if err := c.validateCommentEvent(ctx, repoDID, commentRecord); err != nil {
+	if err := c.validateCommentEvent(repoDID); err != nil {
 		log.Printf("🚨 SECURITY: Rejecting comment event: %v", err)
 		return err
 	}
 
+	// Backfill the root post if the firehose never delivered it
+	// Comments whose root can't be found are still stored, but hidden until it is indexed
+	rootIndexed, err := c.ensureRootPost(ctx, commentRecord.Reply.Root.URI)
+	if err != nil {
+		return err
+	}
+
+	// Comments don't reference users by FK, so an unresolvable commenter doesn't block indexing
+	if c.authors != nil {
+		if err := c.authors.EnsureUser(ctx, repoDID); err != nil {
+			log.Printf("Warning: failed to index commenter %s: %v", repoDID, err)
+		}
+	}
+
 	// Build AT-URI for this comment
 	// Format: at://commenter_did/social.coves.community.comment/rkey
 	uri := fmt.Sprintf("at://%s/social.coves.community.comment/%s", repoDID, commit.RKey)
@@ -102,10 +156,17 @@ func (c *CommentEventConsumer) createComment(ctx context.Context, repoDID string
 	// Serialize optional JSON fields
 	facetsJSON, embedJSON, labelsJSON := serializeOptionalFields(commentRecord)
 
+	// Resolve @handle mentions into facets and notification recipients
+	mentions := c.resolveCommentMentions(ctx, commentRecord.Content)
+	if mentions != nil {
+		facetsJSON = serializeFacets(mergeMentionFacets(commentRecord.Facets, mentions.Facets))
+	}
+
 	// Build comment entity
 	comment := &comments.Comment{
 		URI:           uri,
 		CID:           commit.CID,
+		Rev:           commit.Rev,
 		RKey:          commit.RKey,
 		CommenterDID:  repoDID, // Comment comes from user's repository
 		RootURI:       commentRecord.Reply.Root.URI,
@@ -117,14 +178,67 @@ func (c *CommentEventConsumer) createComment(ctx context.Context, repoDID string
 		Embed:         embedJSON,
 		ContentLabels: labelsJSON,
 		Langs:         commentRecord.Langs,
+		RawRecord:     marshalRawRecord(commit.Record),
 		CreatedAt:     createdAt,
 		IndexedAt:     time.Now(),
+		Orphaned:      !rootIndexed,
+	}
+	comment.DetectedLang, comment.LangConfidence = detectLanguage(commentRecord.Langs, commentRecord.Content)
+
+	// Community automod rules: removed and held comments are indexed hidden, flagged ones as normal
+	// The root post lives in its community's repository, so its authority is the community DID
+	var automodMatch *automod.Match
+	communityDID, _, uriErr := parsePostURI(comment.RootURI)
+	if uriErr == nil {
+		automodMatch = evaluateAutomod(ctx, c.automod, communityDID, uri,
+			automodContent([]string{commentRecord.Content}, commentRecord.Facets, commentRecord.Embed))
+	}
+	if automodMatch != nil {
+		switch automodMatch.Action() {
+		case automod.ActionRemove:
+			comment.Status = comments.StatusRemoved
+		case automod.ActionHold:
+			comment.Status = comments.StatusHeld
+		}
 	}
 
 	// Atomically: Index comment + Update parent counts
-	if err := c.indexCommentAndUpdateCounts(ctx, comment); err != nil {
+	if err := c.indexCommentAndUpdateCounts(ctx, comment, mentions); err != nil {
+		if errors.Is(err, comments.ErrStaleRevision) {
+			// A replayed create of a comment its author has since deleted
+			c.replay.skipped(commit, uri)
+			return nil
+		}
 		return fmt.Errorf("failed to index comment and update counts: %w", err)
 	}
+	trackBlobReferences(ctx, c.blobRefs, uri, commit.Record)
+
+	if comment.Status == comments.StatusRejected {
+		log.Printf("✓ Indexed rejected comment: %s (thread %s is locked)", uri, comment.RootURI)
+		return nil
+	}
+
+	if automodMatch != nil {
+		recordAutomodAction(ctx, c.automod, automodMatch, uri, communityDID, comment.CommenterDID)
+	}
+	if comment.Status != comments.StatusActive {
+		return nil
+	}
+
+	if comment.Orphaned {
+		log.Printf("✓ Indexed orphaned comment: %s (root post %s not found)", uri, comment.RootURI)
+		return nil
+	}
+
+	recordSubjectActivity(ctx, c.activity, comment.RootURI, comment.CommenterDID)
+
+	if c.publisher != nil {
+		c.publisher.Publish(live.PostTopic(comment.RootURI), live.Event{
+			Type:      live.EventComment,
+			URI:       uri,
+			ParentURI: comment.ParentURI,
+		})
+	}
 
 	log.Printf("✓ Indexed comment: %s (on %s)", uri, comment.ParentURI)
 	return nil
@@ -143,7 +257,7 @@ func (c *CommentEventConsumer) updateComment(ctx context.Context, repoDID string
 	}
 
 	// SECURITY: Validate this is a legitimate update
-	if err := c.validateCommentEvent(ctx, repoDID, commentRecord); err != nil {
+	if err := c.validateCommentEvent(repoDID); err != nil {
 		log.Printf("🚨 SECURITY: Rejecting comment update: %v", err)
 		return err
 	}
@@ -156,12 +270,17 @@ func (c *CommentEventConsumer) updateComment(ctx context.Context, repoDID string
 	if err != nil {
 		if err == comments.ErrCommentNotFound {
 			// Comment doesn't exist yet - might arrive out of order
-			log.Printf("Warning: Update event for non-existent comment: %s (will be indexed on CREATE)", uri)
+			log.Printf("Warning: Update event for non-existent or deleted comment: %s (will be indexed on CREATE)", uri)
 			return nil
 		}
 		return fmt.Errorf("failed to get existing comment for validation: %w", err)
 	}
 
+	// Jetstream can redeliver or reorder commits; an older version must not replace a newer one
+	if c.replay.stale(commit, uri, existingComment.Rev) {
+		return nil
+	}
+
 	// SECURITY: Threading references are IMMUTABLE after creation
 	// Reject updates that attempt to change root/parent (prevents thread hijacking)
 	if existingComment.RootURI != commentRecord.Reply.Root.URI ||
@@ -173,29 +292,57 @@ func (c *CommentEventConsumer) updateComment(ctx context.Context, repoDID string
 		log.Printf("  Incoming root: %s (CID: %s)", commentRecord.Reply.Root.URI, commentRecord.Reply.Root.CID)
 		log.Printf("  Existing parent: %s (CID: %s)", existingComment.ParentURI, existingComment.ParentCID)
 		log.Printf("  Incoming parent: %s (CID: %s)", commentRecord.Reply.Parent.URI, commentRecord.Reply.Parent.CID)
+		c.metrics.threadingRejections.Add(1)
 		return fmt.Errorf("comment threading references cannot be changed after creation")
 	}
 
 	// Serialize optional JSON fields
 	facetsJSON, embedJSON, labelsJSON := serializeOptionalFields(commentRecord)
 
+	// Resolve @handle mentions into facets and notification recipients
+	mentions := c.resolveCommentMentions(ctx, commentRecord.Content)
+	if mentions != nil {
+		facetsJSON = serializeFacets(mergeMentionFacets(commentRecord.Facets, mentions.Facets))
+	}
+
 	// Build comment update entity (preserves vote counts and created_at)
 	comment := &comments.Comment{
 		URI:           uri,
 		CID:           commit.CID,
+		Rev:           commit.Rev,
 		Content:       commentRecord.Content,
 		ContentFacets: facetsJSON,
 		Embed:         embedJSON,
 		ContentLabels: labelsJSON,
 		Langs:         commentRecord.Langs,
+		RawRecord:     marshalRawRecord(commit.Record),
 	}
+	comment.DetectedLang, comment.LangConfidence = detectLanguage(commentRecord.Langs, commentRecord.Content)
 
 	// Update the comment in repository
+	// A changed CID keeps the replaced version in the edit history moderators can read
 	if err := c.commentRepo.Update(ctx, comment); err != nil {
+		if errors.Is(err, comments.ErrStaleRevision) {
+			// A newer update was indexed while this one was being processed
+			c.replay.skipped(commit, uri)
+			return nil
+		}
 		return fmt.Errorf("failed to update comment: %w", err)
 	}
+	trackBlobReferences(ctx, c.blobRefs, uri, commit.Record)
 
-	log.Printf("✓ Updated comment: %s", uri)
+	// Notify users newly mentioned by the edit (already-notified users are deduped)
+	if mentions != nil {
+		if err := insertMentionNotifications(ctx, c.db, repoDID, uri, commit.CID, existingComment.RootURI, mentions.DIDs); err != nil {
+			return err
+		}
+	}
+
+	if existingComment.CID != commit.CID {
+		log.Printf("✓ Updated comment: %s (previous version %s kept in edit history)", uri, existingComment.CID)
+	} else {
+		log.Printf("✓ Updated comment: %s", uri)
+	}
 	return nil
 }
 
@@ -215,17 +362,24 @@ func (c *CommentEventConsumer) deleteComment(ctx context.Context, repoDID string
 		return fmt.Errorf("failed to get existing comment: %w", err)
 	}
 
+	// A delete replayed after the comment was recreated must not delete the newer comment
+	if c.replay.stale(commit, uri, existingComment.Rev) {
+		return nil
+	}
+
 	// Atomically: Soft-delete comment + Update parent counts
-	if err := c.deleteCommentAndUpdateCounts(ctx, existingComment); err != nil {
+	// The deleting rev stays on the row, so a replay of the original create isn't resurrected
+	if err := c.deleteCommentAndUpdateCounts(ctx, existingComment, commit.Rev); err != nil {
 		return fmt.Errorf("failed to delete comment and update counts: %w", err)
 	}
+	refreshBlobReferences(ctx, c.blobRefs, uri)
 
 	log.Printf("✓ Deleted comment: %s", uri)
 	return nil
 }
 
-// indexCommentAndUpdateCounts atomically indexes a comment and updates parent counts
-func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context, comment *comments.Comment) error {
+// indexCommentAndUpdateCounts atomically indexes a comment, creates mention and reply notifications, and updates parent counts
+func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context, comment *comments.Comment, mentions *commentMentions) error {
 	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -236,15 +390,22 @@ func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context,
 		}
 	}()
 
+	// 0. Work out the comment's thread depth, and whether its thread is locked
+	if err := c.placeInThread(ctx, tx, comment); err != nil {
+		return err
+	}
+
 	// 1. Check if comment exists and handle resurrection case
 	// In atProto, deleted records' rkeys become available - users can recreate with same rkey
 	// We must distinguish: idempotent replay (skip) vs resurrection (update + restore counts)
 	var existingID int64
 	var existingDeletedAt *time.Time
-	checkQuery := `SELECT id, deleted_at FROM comments WHERE uri = $1`
-	checkErr := tx.QueryRowContext(ctx, checkQuery, comment.URI).Scan(&existingID, &existingDeletedAt)
+	var existingRev string
+	checkQuery := `SELECT id, deleted_at, COALESCE(rev, '') FROM comments WHERE uri = $1`
+	checkErr := tx.QueryRowContext(ctx, checkQuery, comment.URI).Scan(&existingID, &existingDeletedAt, &existingRev)
 
 	var commentID int64
+	outcome := commentIndexOutcome{orphaned: comment.Orphaned}
 
 	if checkErr == nil {
 		// Comment exists
@@ -257,12 +418,19 @@ func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context,
 			return nil
 		}
 
+		// The row keeps the rev that deleted it: only a newer create is a genuine recreation,
+		// an older one is the original create replayed
+		if utils.IsStaleRev(comment.Rev, existingRev) {
+			return comments.ErrStaleRevision
+		}
+
 		// Comment was soft-deleted, now being recreated (resurrection)
 		// This is a NEW record with same rkey - update ALL fields including threading refs
 		// User may have deleted old comment and created a new one on a different parent/root
 		// Clear deletion metadata to restore the comment
 		log.Printf("Resurrecting previously deleted comment: %s", comment.URI)
 		commentID = existingID
+		outcome.resurrected = true
 
 		resurrectQuery := `
 			UPDATE comments
@@ -280,11 +448,21 @@ func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context,
 				langs = $11,
 				created_at = $12,
 				indexed_at = $13,
+				raw_record = $14,
 				deleted_at = NULL,
 				deletion_reason = NULL,
 				deleted_by = NULL,
-				reply_count = 0
-			WHERE id = $14
+				reply_count = 0,
+				orphaned = $16,
+				orphan_checked_at = CASE WHEN $16 THEN NOW() END,
+				depth = $17,
+				depth_exceeded = $18,
+				status = $19,
+				visibility_state = CASE WHEN $19 <> 'active' THEN 'removed' ELSE visibility_state END,
+				detected_lang = $20,
+				detected_lang_confidence = $21,
+				rev = NULLIF($22, '')
+			WHERE id = $15
 		`
 
 		_, err = tx.ExecContext(
@@ -302,7 +480,15 @@ func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context,
 			pq.Array(comment.Langs),
 			comment.CreatedAt,
 			time.Now(),
+			comment.RawRecord,
 			commentID,
+			comment.Orphaned,
+			comment.Depth,
+			comment.DepthExceeded,
+			comment.Status,
+			comment.DetectedLang,
+			comment.LangConfidence,
+			comment.Rev,
 		)
 		if err != nil {
 			return fmt.Errorf("failed to resurrect comment: %w", err)
@@ -317,12 +503,18 @@ func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context,
 				uri, cid, rkey, commenter_did,
 				root_uri, root_cid, parent_uri, parent_cid,
 				content, content_facets, embed, content_labels, langs,
-				created_at, indexed_at
+				created_at, indexed_at, raw_record,
+				orphaned, orphan_checked_at,
+				depth, depth_exceeded, status, visibility_state,
+				detected_lang, detected_lang_confidence, rev
 			) VALUES (
 				$1, $2, $3, $4,
 				$5, $6, $7, $8,
 				$9, $10, $11, $12, $13,
-				$14, $15
+				$14, $15, $16,
+				$17, CASE WHEN $17 THEN NOW() END,
+				$18, $19, $20, CASE WHEN $20 <> 'active' THEN 'removed' ELSE 'visible' END::content_visibility_state,
+				$21, $22, NULLIF($23, '')
 			)
 			ON CONFLICT (uri) DO NOTHING
 			RETURNING id
@@ -333,7 +525,10 @@ func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context,
 			comment.URI, comment.CID, comment.RKey, comment.CommenterDID,
 			comment.RootURI, comment.RootCID, comment.ParentURI, comment.ParentCID,
 			comment.Content, comment.ContentFacets, comment.Embed, comment.ContentLabels, pq.Array(comment.Langs),
-			comment.CreatedAt, time.Now(),
+			comment.CreatedAt, time.Now(), comment.RawRecord,
+			comment.Orphaned,
+			comment.Depth, comment.DepthExceeded, comment.Status,
+			comment.DetectedLang, comment.LangConfidence, comment.Rev,
 		).Scan(&commentID)
 		if err == sql.ErrNoRows {
 			// ON CONFLICT triggered - comment was inserted by concurrent process
@@ -353,53 +548,90 @@ func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context,
 		return fmt.Errorf("failed to check for existing comment: %w", checkErr)
 	}
 
+	// 1.1. Replies that arrived before this comment get their depth now
+	if err := c.propagateDepth(ctx, tx, comment); err != nil {
+		return err
+	}
+
+	// Rejected, held and removed comments are hidden: no notifications, and they don't count
+	// toward their thread
+	if comment.Status != comments.StatusActive {
+		if commitErr := tx.Commit(); commitErr != nil {
+			return fmt.Errorf("failed to commit transaction: %w", commitErr)
+		}
+		c.metrics.recordIndexed(outcome)
+		return nil
+	}
+
+	// 1.25. Notify mentioned users (deduped per comment by the notifications unique constraint)
+	if mentions != nil {
+		if err := insertMentionNotifications(ctx, tx, comment.CommenterDID, comment.URI, comment.CID, comment.RootURI, mentions.DIDs); err != nil {
+			return err
+		}
+	}
+
+	// 1.3. Notify the parent's author, grouped with other replies to the parent this hour
+	if err := insertReplyNotification(ctx, tx, comment, time.Now()); err != nil {
+		return err
+	}
+
 	// 1.5. Reconcile reply_count for this newly inserted comment
 	// In case any replies arrived out-of-order before this parent was indexed
+	// The earliest reply's indexed_at gives how long it waited for its parent. Replies to a
+	// resurrected comment were usually indexed before it was deleted, so they aren't counted
+	// as reconciled.
 	reconcileQuery := `
-		UPDATE comments
-		SET reply_count = (
-			SELECT COUNT(*)
+		WITH replies AS (
+			SELECT COUNT(*) AS reply_count, MIN(c.indexed_at) AS first_indexed_at
 			FROM comments c
-			WHERE c.parent_uri = $1 AND c.deleted_at IS NULL
+			WHERE c.parent_uri = $1 AND c.deleted_at IS NULL AND c.status = 'active'
 		)
+		UPDATE comments
+		SET reply_count = replies.reply_count
+		FROM replies
 		WHERE id = $2
+		RETURNING replies.reply_count, replies.first_indexed_at
 	`
-	_, reconcileErr := tx.ExecContext(ctx, reconcileQuery, comment.URI, commentID)
+	var replyCount int
+	var firstReplyIndexedAt sql.NullTime
+	reconcileErr := tx.QueryRowContext(ctx, reconcileQuery, comment.URI, commentID).Scan(&replyCount, &firstReplyIndexedAt)
 	if reconcileErr != nil {
 		log.Printf("Warning: Failed to reconcile reply_count for %s: %v", comment.URI, reconcileErr)
 		// Continue anyway - this is a best-effort reconciliation
+	} else if replyCount > 0 && !outcome.resurrected {
+		outcome.reconciled = true
+		outcome.lag = time.Since(firstReplyIndexedAt.Time)
+		log.Printf("Reconciled reply_count for %s: %d replies arrived first (earliest %s ago)", comment.URI, replyCount, outcome.lag.Round(time.Millisecond))
 	}
 
-	// 2. Update parent counts atomically
-	// Parent could be a post (increment comment_count) or a comment (increment reply_count)
-	// Parse collection from parent URI to determine target table
+	// 2. Update parent and root post counts atomically
+	// Replies to a comment bump the parent's reply_count. Every comment counts toward its
+	// root post's comment_count and last_activity_at; only direct comments on the post
+	// (parent == root) count toward top_level_comment_count.
 	//
-	// NOTE: Post comment_count reconciliation IS implemented in post_consumer.go:210-226
-	// When a comment arrives before its parent post, the post update below returns 0 rows
+	// NOTE: Post comment count reconciliation IS implemented in post_consumer.go
+	// When a comment arrives before its root post, the post update below returns 0 rows
 	// and we log a warning. Later, when the post is indexed, the post consumer reconciles
-	// comment_count by counting all pre-existing comments. This ensures accurate counts
+	// the counts from all pre-existing comments. This ensures accurate counts
 	// despite out-of-order Jetstream event delivery.
 	//
 	// Test coverage: TestPostConsumer_CommentCountReconciliation in post_consumer_test.go
 	collection := utils.ExtractCollectionFromURI(comment.ParentURI)
 
-	var updateQuery string
 	switch collection {
 	case "social.coves.community.post":
-		// Comment on post - update posts.comment_count
-		updateQuery = `
-			UPDATE posts
-			SET comment_count = comment_count + 1
-			WHERE uri = $1 AND deleted_at IS NULL
-		`
+		// Comment on post - counted on the root post below
 
 	case "social.coves.community.comment":
 		// Reply to comment - update comments.reply_count
-		updateQuery = `
+		updateQuery := `
 			UPDATE comments
 			SET reply_count = reply_count + 1
 			WHERE uri = $1 AND deleted_at IS NULL
 		`
+		if err := execCountUpdate(ctx, tx, updateQuery, comment.ParentURI); err != nil {
+			return fmt.Errorf("failed to update parent count: %w", err)
+		}
 
 	default:
 		// Unknown or unsupported parent collection
@@ -408,22 +640,22 @@ func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context,
 		if commitErr := tx.Commit(); commitErr != nil {
 			return fmt.Errorf("failed to commit transaction: %w", commitErr)
 		}
+		c.metrics.recordIndexed(outcome)
 		return nil
 	}
 
-	result, err := tx.ExecContext(ctx, updateQuery, comment.ParentURI)
-	if err != nil {
-		return fmt.Errorf("failed to update parent count: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to check update result: %w", err)
-	}
-
-	// If parent not found, that's OK (parent might not be indexed yet)
-	if rowsAffected == 0 {
-		log.Printf("Warning: Parent not found or deleted: %s (comment indexed anyway)", comment.ParentURI)
+	if utils.ExtractCollectionFromURI(comment.RootURI) == "social.coves.community.post" {
+		// Activity never moves backwards, and a future createdAt can't pin a post to the top
+		rootQuery := `
+			UPDATE posts
+			SET comment_count = comment_count + 1,
+				top_level_comment_count = top_level_comment_count + CASE WHEN $2 THEN 1 ELSE 0 END,
+				last_activity_at = GREATEST(last_activity_at, LEAST($3::timestamptz, NOW()))
+			WHERE uri = $1 AND deleted_at IS NULL
+		`
+		if err := execCountUpdate(ctx, tx, rootQuery, comment.RootURI, comment.ParentURI == comment.RootURI, comment.CreatedAt); err != nil {
+			return fmt.Errorf("failed to update post comment counts: %w", err)
+		}
 	}
 
 	// Commit transaction
@@ -431,13 +663,15 @@ func (c *CommentEventConsumer) indexCommentAndUpdateCounts(ctx context.Context,
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	c.metrics.recordIndexed(outcome)
 	return nil
 }
 
 // deleteCommentAndUpdateCounts atomically soft-deletes a comment and updates parent counts
 // Blanks content to preserve thread structure while respecting user privacy
 // The comment remains in the database but is shown as "[deleted]" in thread views
-func (c *CommentEventConsumer) deleteCommentAndUpdateCounts(ctx context.Context, comment *comments.Comment) error {
+// rev is the deleting commit's rev, kept on the row to tell replayed creates from recreations
+func (c *CommentEventConsumer) deleteCommentAndUpdateCounts(ctx context.Context, comment *comments.Comment, rev string) error {
 	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -471,28 +705,40 @@ func (c *CommentEventConsumer) deleteCommentAndUpdateCounts(ctx context.Context,
 		return nil
 	}
 
-	// 2. Decrement parent counts atomically
-	// Parent could be a post or comment - parse collection to determine target table
+	// Keep the deleting rev; rejected, held and removed comments were never counted
+	var status string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE comments SET rev = COALESCE(NULLIF($2, ''), rev)
+		WHERE uri = $1
+		RETURNING status`, comment.URI, rev).Scan(&status)
+	if err != nil {
+		return fmt.Errorf("failed to record comment deletion: %w", err)
+	}
+	if status != comments.StatusActive {
+		if commitErr := tx.Commit(); commitErr != nil {
+			return fmt.Errorf("failed to commit transaction: %w", commitErr)
+		}
+		return nil
+	}
+
+	// 2. Decrement parent and root post counts atomically
+	// last_activity_at is left alone - a deleted comment was still activity
 	collection := utils.ExtractCollectionFromURI(comment.ParentURI)
 
-	var updateQuery string
-	var result sql.Result
 	switch collection {
 	case "social.coves.community.post":
-		// Comment on post - decrement posts.comment_count
-		updateQuery = `
-			UPDATE posts
-			SET comment_count = GREATEST(0, comment_count - 1)
-			WHERE uri = $1 AND deleted_at IS NULL
-		`
+		// Comment on post - counted on the root post below
 
 	case "social.coves.community.comment":
 		// Reply to comment - decrement comments.reply_count
-		updateQuery = `
+		updateQuery := `
 			UPDATE comments
 			SET reply_count = GREATEST(0, reply_count - 1)
 			WHERE uri = $1 AND deleted_at IS NULL
 		`
+		if err := execCountUpdate(ctx, tx, updateQuery, comment.ParentURI); err != nil {
+			return fmt.Errorf("failed to update parent count: %w", err)
+		}
 
 	default:
 		// Unknown or unsupported parent collection
@@ -504,31 +750,47 @@ func (c *CommentEventConsumer) deleteCommentAndUpdateCounts(ctx context.Context,
 		return nil
 	}
 
-	result, err = tx.ExecContext(ctx, updateQuery, comment.ParentURI)
+	if utils.ExtractCollectionFromURI(comment.RootURI) == "social.coves.community.post" {
+		rootQuery := `
+			UPDATE posts
+			SET comment_count = GREATEST(0, comment_count - 1),
+				top_level_comment_count = GREATEST(0, top_level_comment_count - CASE WHEN $2 THEN 1 ELSE 0 END)
+			WHERE uri = $1 AND deleted_at IS NULL
+		`
+		if err := execCountUpdate(ctx, tx, rootQuery, comment.RootURI, comment.ParentURI == comment.RootURI); err != nil {
+			return fmt.Errorf("failed to update post comment counts: %w", err)
+		}
+	}
+
+	// Commit transaction
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// execCountUpdate runs a parent/root count update
+// A missing target is not an error: it may not be indexed yet, or may already be deleted
+func execCountUpdate(ctx context.Context, db execer, query, targetURI string, args ...interface{}) error {
+	result, err := db.ExecContext(ctx, query, append([]interface{}{targetURI}, args...)...)
 	if err != nil {
-		return fmt.Errorf("failed to update parent count: %w", err)
+		return err
 	}
 
-	rowsAffected, err = result.RowsAffected()
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return fmt.Errorf("failed to check update result: %w", err)
 	}
 
-	// If parent not found, that's OK (parent might be deleted)
 	if rowsAffected == 0 {
-		log.Printf("Warning: Parent not found or deleted: %s (comment deleted anyway)", comment.ParentURI)
-	}
-
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		log.Printf("Warning: Count target not found or deleted: %s", targetURI)
 	}
-
 	return nil
 }
 
 // validateCommentEvent performs security validation on comment events
-func (c *CommentEventConsumer) validateCommentEvent(ctx context.Context, repoDID string, comment *CommentRecordFromJetstream) error {
+func (c *CommentEventConsumer) validateCommentEvent(repoDID string) error {
 	// SECURITY: Comments MUST come from user repositories (repo owner = commenter DID)
 	// The repository owner (repoDID) IS the commenter - comments are stored in user repos.
 	//
@@ -543,70 +805,11 @@ func (c *CommentEventConsumer) validateCommentEvent(ctx context.Context, repoDID
 	// - Fake DIDs will fail PDS authentication
 
 	// Validate DID format (basic sanity check)
+	// The record itself was validated against the lexicon by guardRecord
 	if !strings.HasPrefix(repoDID, "did:") {
 		return fmt.Errorf("invalid commenter DID format: %s", repoDID)
 	}
 
-	// Validate content is not empty (required per lexicon)
-	if comment.Content == "" {
-		return fmt.Errorf("comment content is required")
-	}
-
-	// Validate content length (defensive check - PDS should enforce this)
-	// Per lexicon: max 3000 graphemes, ~30000 bytes
-	// We check bytes as a simple defensive measure
-	if len(comment.Content) > MaxCommentContentBytes {
-		return fmt.Errorf("comment content exceeds maximum length (%d bytes): got %d bytes", MaxCommentContentBytes, len(comment.Content))
-	}
-
-	// Validate reply references exist
-	if comment.Reply.Root.URI == "" || comment.Reply.Root.CID == "" {
-		return fmt.Errorf("invalid root reference: must have both URI and CID")
-	}
-
-	if comment.Reply.Parent.URI == "" || comment.Reply.Parent.CID == "" {
-		return fmt.Errorf("invalid parent reference: must have both URI and CID")
-	}
-
-	// Validate AT-URI structure for root and parent
-	if err := validateATURI(comment.Reply.Root.URI); err != nil {
-		return fmt.Errorf("invalid root URI: %w", err)
-	}
-
-	if err := validateATURI(comment.Reply.Parent.URI); err != nil {
-		return fmt.Errorf("invalid parent URI: %w", err)
-	}
-
-	return nil
-}
-
-// validateATURI performs basic structure validation on AT-URIs
-// Format: at://did:method:id/collection/rkey
-// This is defensive validation - we trust PDS but catch obviously malformed URIs
-func validateATURI(uri string) error {
-	if !strings.HasPrefix(uri, ATProtoScheme) {
-		return fmt.Errorf("must start with %s", ATProtoScheme)
-	}
-
-	// Remove at:// prefix and split by /
-	withoutScheme := strings.TrimPrefix(uri, ATProtoScheme)
-	parts := strings.Split(withoutScheme, "/")
-
-	// Must have at least 3 parts: did, collection, rkey
-	if len(parts) < 3 {
-		return fmt.Errorf("invalid structure (expected at://did/collection/rkey)")
-	}
-
-	// First part should be a DID
-	if !strings.HasPrefix(parts[0], "did:") {
-		return fmt.Errorf("repository identifier must be a DID")
-	}
-
-	// Collection and rkey should not be empty
-	if parts[1] == "" || parts[2] == "" {
-		return fmt.Errorf("collection and rkey cannot be empty")
-	}
-
 	return nil
 }
 
@@ -629,6 +832,12 @@ type ReplyRefFromJetstream struct {
 	Parent StrongRefFromJetstream `json:"parent"`
 }
 
+// StrongRefFromJetstream represents a strong reference (URI + CID)
+type StrongRefFromJetstream struct {
+	URI string `json:"uri"`
+	CID string `json:"cid"`
+}
+
 // parseCommentRecord parses a comment record from Jetstream event data
 func parseCommentRecord(record map[string]interface{}) (*CommentRecordFromJetstream, error) {
 	// Marshal to JSON and back for proper type conversion
@@ -642,13 +851,14 @@ func parseCommentRecord(record map[string]interface{}) (*CommentRecordFromJetstr
 		return nil, fmt.Errorf("failed to unmarshal comment record: %w", err)
 	}
 
-	// Validate required fields
-	if comment.Content == "" {
-		return nil, fmt.Errorf("comment record missing content field")
-	}
+	// Strip control and bidi override characters before anything reads the content
+	// The raw record is stored untouched for audit
+	comment.Content, comment.Facets = sanitizeContentFacets(comment.Content, comment.Facets)
 
-	if comment.CreatedAt == "" {
-		return nil, fmt.Errorf("comment record missing createdAt field")
+	// The lexicon validator checked the raw record; NFC normalization can grow the content,
+	// so the byte limit is enforced again on what gets indexed
+	if len(comment.Content) > validate.MaxCommentContentBytes {
+		return nil, fmt.Errorf("comment content exceeds maximum length (%d bytes): got %d bytes", validate.MaxCommentContentBytes, len(comment.Content))
 	}
 
 	return &comment, nil
@@ -658,12 +868,7 @@ func parseCommentRecord(record map[string]interface{}) (*CommentRecordFromJetstr
 // Returns nil pointers for empty/nil fields (DRY helper to avoid duplication)
 func serializeOptionalFields(commentRecord *CommentRecordFromJetstream) (facetsJSON, embedJSON, labelsJSON *string) {
 	// Serialize facets if present
-	if len(commentRecord.Facets) > 0 {
-		if facetsBytes, err := json.Marshal(commentRecord.Facets); err == nil {
-			facetsStr := string(facetsBytes)
-			facetsJSON = &facetsStr
-		}
-	}
+	facetsJSON = serializeFacets(commentRecord.Facets)
 
 	// Serialize embed if present
 	if len(commentRecord.Embed) > 0 {
@@ -683,3 +888,16 @@ func serializeOptionalFields(commentRecord *CommentRecordFromJetstream) (facetsJ
 
 	return facetsJSON, embedJSON, labelsJSON
 }
+
+// serializeFacets serializes facets to a JSON string, returning nil when there are none
+func serializeFacets(facets []interface{}) *string {
+	if len(facets) == 0 {
+		return nil
+	}
+	facetsBytes, err := json.Marshal(facets)
+	if err != nil {
+		return nil
+	}
+	facetsStr := string(facetsBytes)
+	return &facetsStr
+}
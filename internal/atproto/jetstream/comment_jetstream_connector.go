@@ -2,7 +2,6 @@ package jetstream
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"sync"
@@ -103,21 +102,18 @@ func (c *CommentJetstreamConnector) connect(ctx context.Context) error {
 		default:
 		}
 
-		_, message, err := conn.ReadMessage()
+		event, err := readEvent(conn)
 		if err != nil {
 			closeOnce.Do(func() { close(done) })
 			return fmt.Errorf("read error: %w", err)
 		}
-
-		// Parse Jetstream event
-		var event JetstreamEvent
-		if err := json.Unmarshal(message, &event); err != nil {
-			log.Printf("Failed to parse Jetstream event: %v", err)
+		if event == nil {
+			// Oversized or malformed message, already logged
 			continue
 		}
 
 		// Process event through consumer
-		if err := c.consumer.HandleEvent(ctx, &event); err != nil {
+		if err := c.consumer.HandleEvent(ctx, event); err != nil {
 			log.Printf("Failed to handle comment event: %v", err)
 			// Continue processing other events even if one fails
 		}
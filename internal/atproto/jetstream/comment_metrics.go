@@ -0,0 +1,111 @@
+package jetstream
+
+import (
+	"Coves/internal/core/comments"
+	"sync/atomic"
+	"time"
+)
+
+// reconcileLagBuckets are the upper bounds of the reconcile lag histogram
+// Jetstream usually reorders events across repos by milliseconds to seconds; the long tail
+// is replays and parents whose author was indexed late.
+var reconcileLagBuckets = []time.Duration{
+	100 * time.Millisecond,
+	time.Second,
+	10 * time.Second,
+	time.Minute,
+	10 * time.Minute,
+	time.Hour,
+	24 * time.Hour,
+}
+
+// commentMetrics holds the comment consumer's ordering counters
+type commentMetrics struct {
+	indexed             atomic.Int64
+	reconciledParents   atomic.Int64
+	orphansCreated      atomic.Int64
+	orphansResolved     atomic.Int64
+	threadingRejections atomic.Int64
+	resurrections       atomic.Int64
+	reconcileLag        lagHistogram
+}
+
+// commentIndexOutcome is what indexing one comment changed, recorded once its transaction commits
+type commentIndexOutcome struct {
+	resurrected bool
+	orphaned    bool
+	reconciled  bool          // Replies to the comment were indexed before it
+	lag         time.Duration // Since the earliest of those replies was indexed
+}
+
+func (m *commentMetrics) recordIndexed(outcome commentIndexOutcome) {
+	m.indexed.Add(1)
+	if outcome.resurrected {
+		m.resurrections.Add(1)
+	}
+	if outcome.orphaned {
+		m.orphansCreated.Add(1)
+	}
+	if outcome.reconciled {
+		m.reconciledParents.Add(1)
+		m.reconcileLag.observe(outcome.lag)
+	}
+}
+
+func (m *commentMetrics) snapshot() comments.ConsumerMetrics {
+	return comments.ConsumerMetrics{
+		Indexed:             m.indexed.Load(),
+		ReconciledParents:   m.reconciledParents.Load(),
+		OrphansCreated:      m.orphansCreated.Load(),
+		OrphansResolved:     m.orphansResolved.Load(),
+		ThreadingRejections: m.threadingRejections.Load(),
+		Resurrections:       m.resurrections.Load(),
+		ReconcileLag:        m.reconcileLag.snapshot(),
+	}
+}
+
+// lagHistogram counts durations into reconcileLagBuckets, plus an overflow bucket
+type lagHistogram struct {
+	counts [8]atomic.Int64 // len(reconcileLagBuckets) + 1
+	sumMs  atomic.Int64
+}
+
+func (h *lagHistogram) observe(lag time.Duration) {
+	if lag < 0 {
+		lag = 0
+	}
+	bucket := len(reconcileLagBuckets)
+	for i, bound := range reconcileLagBuckets {
+		if lag <= bound {
+			bucket = i
+			break
+		}
+	}
+	h.counts[bucket].Add(1)
+	h.sumMs.Add(lag.Milliseconds())
+}
+
+// snapshot returns the histogram with cumulative bucket counts
+func (h *lagHistogram) snapshot() comments.LagHistogram {
+	snapshot := comments.LagHistogram{
+		Buckets: make([]comments.LagBucket, 0, len(h.counts)),
+		SumMs:   h.sumMs.Load(),
+	}
+	var cumulative int64
+	for i := range h.counts {
+		cumulative += h.counts[i].Load()
+		le := "+Inf"
+		if i < len(reconcileLagBuckets) {
+			le = reconcileLagBuckets[i].String()
+		}
+		snapshot.Buckets = append(snapshot.Buckets, comments.LagBucket{LE: le, Count: cumulative})
+	}
+	snapshot.Count = cumulative
+	return snapshot
+}
+
+// CommentConsumerMetrics returns how often the comment consumer reconciled out-of-order
+// events since the process started
+func (c *CommentEventConsumer) CommentConsumerMetrics() comments.ConsumerMetrics {
+	return c.metrics.snapshot()
+}
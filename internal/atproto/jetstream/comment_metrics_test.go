@@ -0,0 +1,56 @@
+package jetstream
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommentMetrics_RecordIndexed(t *testing.T) {
+	var m commentMetrics
+	m.recordIndexed(commentIndexOutcome{})
+	m.recordIndexed(commentIndexOutcome{orphaned: true})
+	m.recordIndexed(commentIndexOutcome{resurrected: true})
+	m.recordIndexed(commentIndexOutcome{reconciled: true, lag: 50 * time.Millisecond})
+	m.recordIndexed(commentIndexOutcome{reconciled: true, lag: 3 * time.Second})
+	m.recordIndexed(commentIndexOutcome{reconciled: true, lag: 48 * time.Hour})
+
+	got := m.snapshot()
+	if got.Indexed != 6 || got.OrphansCreated != 1 || got.Resurrections != 1 || got.ReconciledParents != 3 {
+		t.Errorf("unexpected counters: %+v", got)
+	}
+
+	lag := got.ReconcileLag
+	if lag.Count != 3 {
+		t.Errorf("lag count = %d, want 3", lag.Count)
+	}
+	if want := int64(50 + 3000 + 48*3600*1000); lag.SumMs != want {
+		t.Errorf("lag sum = %dms, want %dms", lag.SumMs, want)
+	}
+	if len(lag.Buckets) != len(reconcileLagBuckets)+1 {
+		t.Fatalf("got %d buckets", len(lag.Buckets))
+	}
+	// Cumulative: 50ms is in every bucket, 3s from 10s on, 48h only in +Inf
+	want := map[string]int64{"100ms": 1, "1s": 1, "10s": 2, "24h0m0s": 2, "+Inf": 3}
+	for _, bucket := range lag.Buckets {
+		if count, ok := want[bucket.LE]; ok && bucket.Count != count {
+			t.Errorf("bucket %s = %d, want %d", bucket.LE, bucket.Count, count)
+		}
+	}
+	if last := lag.Buckets[len(lag.Buckets)-1]; last.LE != "+Inf" {
+		t.Errorf("last bucket = %q, want +Inf", last.LE)
+	}
+}
+
+func TestLagHistogram_BoundIsInclusive(t *testing.T) {
+	var h lagHistogram
+	h.observe(time.Second)
+	h.observe(-time.Second) // Clock skew counts as no lag
+
+	snapshot := h.snapshot()
+	if snapshot.Buckets[0].Count != 1 || snapshot.Buckets[1].Count != 2 {
+		t.Errorf("buckets = %+v", snapshot.Buckets)
+	}
+	if snapshot.SumMs != 1000 {
+		t.Errorf("sum = %dms, want 1000ms", snapshot.SumMs)
+	}
+}
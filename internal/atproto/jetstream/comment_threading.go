@@ -0,0 +1,87 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/utils"
+	"Coves/internal/core/comments"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// maxDepthWalk bounds depth propagation so a malformed parent cycle can't recurse forever
+const maxDepthWalk = 10000
+
+// SetMaxThreadDepth sets the depth past which comments are marked depth_exceeded
+// Values below 1 keep the default (comments.DefaultMaxThreadDepth)
+func (c *CommentEventConsumer) SetMaxThreadDepth(depth int) {
+	if depth < 1 {
+		depth = comments.DefaultMaxThreadDepth
+	}
+	c.maxThreadDepth = depth
+}
+
+// placeInThread sets the comment's depth and status from its parent and root post
+// Depth is the parent's depth + 1 (comments on the post are depth 1); it stays nil while the
+// parent comment hasn't been indexed, and is filled in by propagateDepth when the parent arrives
+// Comments on a locked post are still indexed, but as rejected; otherwise a status set by automod
+// is kept
+func (c *CommentEventConsumer) placeInThread(ctx context.Context, tx *sql.Tx, comment *comments.Comment) error {
+	if comment.Status == "" {
+		comment.Status = comments.StatusActive
+	}
+	var locked bool
+	err := tx.QueryRowContext(ctx, `SELECT locked FROM posts WHERE uri = $1`, comment.RootURI).Scan(&locked)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to check thread lock: %w", err)
+	}
+	if locked {
+		comment.Status = comments.StatusRejected
+	}
+
+	comment.Depth = nil
+	switch utils.ExtractCollectionFromURI(comment.ParentURI) {
+	case "social.coves.community.post":
+		depth := 1
+		comment.Depth = &depth
+	case "social.coves.community.comment":
+		var parentDepth sql.NullInt64
+		err := tx.QueryRowContext(ctx, `SELECT depth FROM comments WHERE uri = $1`, comment.ParentURI).Scan(&parentDepth)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to look up parent depth: %w", err)
+		}
+		if parentDepth.Valid {
+			depth := int(parentDepth.Int64) + 1
+			comment.Depth = &depth
+		}
+	}
+	comment.DepthExceeded = comment.Depth != nil && *comment.Depth > c.maxThreadDepth
+	return nil
+}
+
+// propagateDepth fills in the depth of replies that were indexed before this comment
+func (c *CommentEventConsumer) propagateDepth(ctx context.Context, tx *sql.Tx, comment *comments.Comment) error {
+	if comment.Depth == nil {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `
+		WITH RECURSIVE descendants AS (
+			SELECT id, uri, $2::int + 1 AS depth
+			FROM comments
+			WHERE parent_uri = $1 AND depth IS NULL
+			UNION ALL
+			SELECT c.id, c.uri, d.depth + 1
+			FROM comments c
+			JOIN descendants d ON c.parent_uri = d.uri
+			WHERE c.depth IS NULL AND d.depth < $4
+		)
+		UPDATE comments
+		SET depth = descendants.depth,
+			depth_exceeded = descendants.depth > $3
+		FROM descendants
+		WHERE comments.id = descendants.id
+	`, comment.URI, *comment.Depth, c.maxThreadDepth, maxDepthWalk)
+	if err != nil {
+		return fmt.Errorf("failed to propagate comment depth: %w", err)
+	}
+	return nil
+}
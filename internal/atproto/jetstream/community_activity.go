@@ -0,0 +1,51 @@
+package jetstream
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// ActivityRecorder records that a user was active in a community, for weekly/monthly actives.
+// Implemented by communities.ActivityRepository.
+type ActivityRecorder interface {
+	RecordActivity(ctx context.Context, communityDID, userDID string, at time.Time) error
+	RecordSubjectActivity(ctx context.Context, subjectURI, userDID string, at time.Time) error
+}
+
+// SetActivityRecorder configures where post authors are recorded as active in the community
+func (c *PostEventConsumer) SetActivityRecorder(recorder ActivityRecorder) {
+	c.activity = recorder
+}
+
+// SetActivityRecorder configures where commenters are recorded as active in the root post's community
+func (c *CommentEventConsumer) SetActivityRecorder(recorder ActivityRecorder) {
+	c.activity = recorder
+}
+
+// SetActivityRecorder configures where voters are recorded as active in the subject's community
+func (c *VoteEventConsumer) SetActivityRecorder(recorder ActivityRecorder) {
+	c.activity = recorder
+}
+
+// recordCommunityActivity records activity in a known community
+// Active user counts are best-effort: a failure is logged and never fails the event.
+func recordCommunityActivity(ctx context.Context, recorder ActivityRecorder, communityDID, userDID string) {
+	if recorder == nil {
+		return
+	}
+	if err := recorder.RecordActivity(ctx, communityDID, userDID, time.Now()); err != nil {
+		log.Printf("Warning: Failed to record activity of %s in %s: %v", userDID, communityDID, err)
+	}
+}
+
+// recordSubjectActivity records activity in the community of a post or comment
+// Best-effort, like recordCommunityActivity.
+func recordSubjectActivity(ctx context.Context, recorder ActivityRecorder, subjectURI, userDID string) {
+	if recorder == nil {
+		return
+	}
+	if err := recorder.RecordSubjectActivity(ctx, subjectURI, userDID, time.Now()); err != nil {
+		log.Printf("Warning: Failed to record activity of %s on %s: %v", userDID, subjectURI, err)
+	}
+}
@@ -23,17 +23,27 @@ type CommunityEventConsumer struct {
 	identityResolver interface {
 		Resolve(context.Context, string) (*identity.Identity, error)
 	} // For resolving handles from DIDs
-	httpClient       *http.Client                     // Shared HTTP client with connection pooling
-	didCache         *lru.Cache[string, cachedDIDDoc] // Bounded LRU cache for .well-known verification results
-	wellKnownLimiter *rate.Limiter                    // Rate limiter for .well-known fetches
-	instanceDID      string                           // DID of this Coves instance
-	skipVerification bool                             // Skip did:web verification (for dev mode)
+	httpClient        *http.Client                           // Shared HTTP client with connection pooling
+	didCache          *lru.Cache[string, cachedDIDDoc]       // Bounded LRU cache for .well-known verification results, keyed by domain and DID
+	wellKnownLimiter  *rate.Limiter                          // Rate limiter for .well-known fetches
+	dlq               DeadLetterQueue                        // Optional - rejected events are only logged when nil
+	federationPolicy  FederationPolicy                       // Optional - no instance is blocked when nil
+	subscriberCounts  *SubscriberCountBuffer                 // Optional - counts are updated per event when nil
+	hostVerifications communities.HostVerificationRepository // Optional - verification results only live in memory when nil
+	rules             communities.RulesRepository            // Optional - rules records are ignored when nil
+	replay            *ReplayGuard                           // Optional - stale updates are still skipped, but deletes leave no tombstone, when nil
+	blobRefs          BlobReferenceTracker                   // Optional - avatars and banners aren't tracked for the image proxy when nil
+	didDocumentURL    func(domain string) string             // Overridable for tests; defaults to https://{domain}/.well-known/did.json
+	verificationTTL   time.Duration                          // How long a successful verification is trusted
+	retryBackoff      time.Duration                          // Initial backoff between DID document fetch attempts
+	instanceDID       string                                 // DID of this Coves instance
+	skipVerification  bool                                   // Skip did:web verification (for dev mode)
 }
 
-// cachedDIDDoc represents a cached verification result with expiration
-type cachedDIDDoc struct {
-	expiresAt time.Time // When this cache entry expires
-	valid     bool      // Whether verification passed
+// FederationPolicy decides whether communities hosted by a remote instance are blocked
+// Implemented by federation.Service
+type FederationPolicy interface {
+	IsHostBlocked(ctx context.Context, hostedByDID string) (bool, error)
 }
 
 // NewCommunityEventConsumer creates a new Jetstream consumer for community events
@@ -99,6 +109,45 @@ func NewCommunityEventConsumer(repo communities.Repository, instanceDID string,
 	}
 }
 
+// SetDeadLetterQueue configures where rejected events (mismatched $type, oversized records) are stored
+func (c *CommunityEventConsumer) SetDeadLetterQueue(dlq DeadLetterQueue) {
+	c.dlq = dlq
+}
+
+// SetFederationPolicy configures the instance allow/deny policy applied when indexing communities
+func (c *CommunityEventConsumer) SetFederationPolicy(policy FederationPolicy) {
+	c.federationPolicy = policy
+}
+
+// SetSubscriberCountBuffer coalesces subscriber count updates through buf instead of
+// updating the community row in the same transaction as each subscription
+// The caller runs buf.Start and flushes it on shutdown
+func (c *CommunityEventConsumer) SetSubscriberCountBuffer(buf *SubscriberCountBuffer) {
+	c.subscriberCounts = buf
+}
+
+// SetRulesRepository enables indexing of social.coves.community.rules records
+func (c *CommunityEventConsumer) SetRulesRepository(rules communities.RulesRepository) {
+	c.rules = rules
+}
+
+// SetReplayGuard configures where skipped stale events are counted and deleted records' revs kept
+func (c *CommunityEventConsumer) SetReplayGuard(guard *ReplayGuard) {
+	c.replay = guard
+}
+
+// isFederationBlocked evaluates the federation policy for a community's hosting instance
+func (c *CommunityEventConsumer) isFederationBlocked(ctx context.Context, hostedByDID string) (bool, error) {
+	if c.federationPolicy == nil {
+		return false, nil
+	}
+	blocked, err := c.federationPolicy.IsHostBlocked(ctx, hostedByDID)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate federation policy: %w", err)
+	}
+	return blocked, nil
+}
+
 // HandleEvent processes a Jetstream event for community records
 // This is called by the main Jetstream consumer when it receives commit events
 func (c *CommunityEventConsumer) HandleEvent(ctx context.Context, event *JetstreamEvent) error {
@@ -114,9 +163,23 @@ func (c *CommunityEventConsumer) HandleEvent(ctx context.Context, event *Jetstre
 	// - social.coves.community.profile: Community profile records (in community's own repo)
 	// - social.coves.community.subscription: Subscription records (in user's repo)
 	// - social.coves.community.block: Block records (in user's repo)
+	// - social.coves.community.rules: Community rules document (in community's own repo)
 	//
 	// XRPC procedures (social.coves.community.subscribe/unsubscribe) are just HTTP endpoints
 	// that CREATE or DELETE records in these collections
+	switch commit.Collection {
+	case "social.coves.community.profile",
+		"social.coves.community.subscription",
+		"social.coves.community.block",
+		"social.coves.community.rules":
+		if rejected, err := guardRecord(ctx, c.dlq, event); rejected {
+			return err
+		}
+	default:
+		// Not a community-related collection
+		return nil
+	}
+
 	switch commit.Collection {
 	case "social.coves.community.profile":
 		return c.handleCommunityProfile(ctx, event.Did, commit)
@@ -126,6 +189,8 @@ func (c *CommunityEventConsumer) HandleEvent(ctx context.Context, event *Jetstre
 	case "social.coves.community.block":
 		// Handle both create (block) and delete (unblock) operations
 		return c.handleBlock(ctx, event.Did, commit)
+	case "social.coves.community.rules":
+		return c.handleCommunityRules(ctx, event)
 	default:
 		// Not a community-related collection
 		return nil
@@ -140,7 +205,7 @@ func (c *CommunityEventConsumer) handleCommunityProfile(ctx context.Context, did
 	case "update":
 		return c.updateCommunity(ctx, did, commit)
 	case "delete":
-		return c.deleteCommunity(ctx, did)
+		return c.deleteCommunity(ctx, did, commit)
 	default:
 		log.Printf("Unknown operation for community profile: %s", commit.Operation)
 		return nil
@@ -153,6 +218,11 @@ func (c *CommunityEventConsumer) createCommunity(ctx context.Context, did string
 		return fmt.Errorf("community profile create event missing record data")
 	}
 
+	// A create replayed after the community deleted its profile must not bring it back
+	if deleted, err := c.replay.deletedAfter(ctx, commit, communityProfileURI(did)); err != nil || deleted {
+		return err
+	}
+
 	// Parse the community profile record
 	profile, err := parseCommunityProfile(commit.Record)
 	if err != nil {
@@ -183,10 +253,17 @@ func (c *CommunityEventConsumer) createCommunity(ctx context.Context, did string
 
 	// SECURITY: Verify hostedBy claim matches handle domain
 	// This prevents malicious instances from claiming to host communities for domains they don't own
-	if err := c.verifyHostedByClaim(ctx, profile.Handle, profile.HostedBy); err != nil {
-		log.Printf("🚨 SECURITY: Rejecting community %s - hostedBy verification failed: %v", did, err)
-		log.Printf("    Handle: %s, HostedBy: %s", profile.Handle, profile.HostedBy)
-		return fmt.Errorf("hostedBy verification failed: %w", err)
+	// An instance that can't be reached proves nothing either way: the community is indexed
+	// hidden and ReverifyHostedBy lists it once the instance verifies
+	hostedByUnverified := false
+	if err := c.verifyHostedByClaim(ctx, did, profile.Handle, profile.HostedBy); err != nil {
+		if !isTransientVerificationError(err) || c.hostVerifications == nil {
+			log.Printf("🚨 SECURITY: Rejecting community %s - hostedBy verification failed: %v", did, err)
+			log.Printf("    Handle: %s, HostedBy: %s", profile.Handle, profile.HostedBy)
+			return fmt.Errorf("hostedBy verification failed: %w", err)
+		}
+		log.Printf("hostedBy verification for community %s unavailable, indexing it unverified until re-verification: %v", did, err)
+		hostedByUnverified = true
 	}
 
 	// Build AT-URI for this record
@@ -205,6 +282,22 @@ func (c *CommunityEventConsumer) createCommunity(ctx context.Context, did string
 	// V2: Community ALWAYS owns itself
 	ownerDID := did
 
+	// Communities on blocked instances are still indexed, but hidden from list/discover/search
+	federationBlocked, err := c.isFederationBlocked(ctx, profile.HostedBy)
+	if err != nil {
+		return err
+	}
+
+	// Communities that look like they impersonate another are indexed but hidden from
+	// list/discover/search until an admin reviews them
+	impersonationReason, err := c.detectImpersonation(ctx, did, profile.Handle, profile.HostedBy, profile)
+	if err != nil {
+		return err
+	}
+	if impersonationReason != "" {
+		log.Printf("🚨 SECURITY: Flagging community %s (%s) for impersonation review: %s", did, profile.Handle, impersonationReason)
+	}
+
 	// Create community entity
 	community := &communities.Community{
 		DID:                    did, // V2: Repository DID IS the community DID
@@ -219,6 +312,12 @@ func (c *CommunityEventConsumer) createCommunity(ctx context.Context, did string
 		AllowExternalDiscovery: profile.Federation.AllowExternalDiscovery,
 		ModerationType:         profile.ModerationType,
 		ContentWarnings:        profile.ContentWarnings,
+		Categories:             communities.NormalizeCategories(profile.Categories),
+		Flairs:                 communities.NormalizeFlairs(profile.Flairs),
+		PostingRules:           profile.PostingRules,
+		ScoreHidingHours:       communities.NormalizeScoreHidingHours(profile.ScoreHidingHours),
+		CollapseThreshold:      communities.NormalizeCollapseThreshold(profile.CollapseThreshold),
+		CrowdControl:           communities.NormalizeCrowdControl(profile.CrowdControl),
 		MemberCount:            profile.MemberCount,
 		SubscriberCount:        profile.SubscriberCount,
 		FederatedFrom:          profile.FederatedFrom,
@@ -227,8 +326,16 @@ func (c *CommunityEventConsumer) createCommunity(ctx context.Context, did string
 		UpdatedAt:              time.Now(),
 		RecordURI:              uri,
 		RecordCID:              commit.CID,
+		RecordRev:              commit.Rev,
+		FederationBlocked:      federationBlocked,
+		HostedByUnverified:     hostedByUnverified,
+		ImpersonationFlag:      impersonationReason != "",
+		ImpersonationReason:    impersonationReason,
 	}
 
+	// Preserve the full record so fields from newer lexicon versions can be backfilled
+	community.RawRecord = marshalRawRecord(commit.Record)
+
 	// Handle blobs (avatar/banner) if present
 	if avatarCID, ok := extractBlobCID(profile.Avatar); ok {
 		community.AvatarCID = avatarCID
@@ -252,11 +359,16 @@ func (c *CommunityEventConsumer) createCommunity(ctx context.Context, did string
 	if err != nil {
 		// Check if it already exists (idempotency)
 		if communities.IsConflict(err) {
+			// A community first learned from a peer's directory is taken over by its own record
+			if existing, getErr := c.repo.GetByDID(ctx, did); getErr == nil && existing.Remote {
+				return c.updateCommunity(ctx, did, commit)
+			}
 			log.Printf("Community already indexed: %s (%s)", community.Handle, community.DID)
 			return nil
 		}
 		return fmt.Errorf("failed to index community: %w", err)
 	}
+	trackBlobReferences(ctx, c.blobRefs, communityProfileURI(did), commit.Record)
 
 	log.Printf("Indexed new community: %s (%s)", community.Handle, community.DID)
 	return nil
@@ -279,6 +391,31 @@ func (c *CommunityEventConsumer) updateCommunity(ctx context.Context, did string
 		return fmt.Errorf("failed to parse community profile: %w", err)
 	}
 
+	// V2: Repository DID IS the community DID
+	// Get existing community using the repo DID
+	existing, err := c.repo.GetByDID(ctx, did)
+	if err != nil {
+		if communities.IsNotFound(err) {
+			// Community doesn't exist yet - treat as create
+			log.Printf("Community not found for update, creating: %s", did)
+			return c.createCommunity(ctx, did, commit)
+		}
+		return fmt.Errorf("failed to get existing community: %w", err)
+	}
+
+	// Jetstream can redeliver a commit or deliver updates out of order; only a newer
+	// version of the record may replace the indexed one. A peer directory entry has no
+	// rev of its own and is always superseded.
+	if !existing.Remote {
+		if commit.CID != "" && commit.CID == existing.RecordCID {
+			log.Printf("Community profile unchanged, skipping update: %s (cid=%s)", did, commit.CID)
+			return nil
+		}
+		if c.replay.stale(commit, communityProfileURI(did), existing.RecordRev) {
+			return nil
+		}
+	}
+
 	// atProto Best Practice: Handles are NOT stored in records (they're mutable, resolved from DIDs)
 	// If handle is missing from record (new atProto-compliant records), resolve it from PLC/DID
 	if profile.Handle == "" {
@@ -301,18 +438,6 @@ func (c *CommunityEventConsumer) updateCommunity(ctx context.Context, did string
 		}
 	}
 
-	// V2: Repository DID IS the community DID
-	// Get existing community using the repo DID
-	existing, err := c.repo.GetByDID(ctx, did)
-	if err != nil {
-		if communities.IsNotFound(err) {
-			// Community doesn't exist yet - treat as create
-			log.Printf("Community not found for update, creating: %s", did)
-			return c.createCommunity(ctx, did, commit)
-		}
-		return fmt.Errorf("failed to get existing community: %w", err)
-	}
-
 	// Update fields
 	existing.Handle = profile.Handle
 	existing.Name = profile.Name
@@ -322,37 +447,89 @@ func (c *CommunityEventConsumer) updateCommunity(ctx context.Context, did string
 	existing.AllowExternalDiscovery = profile.Federation.AllowExternalDiscovery
 	existing.ModerationType = profile.ModerationType
 	existing.ContentWarnings = profile.ContentWarnings
+	existing.Categories = communities.NormalizeCategories(profile.Categories)
+	existing.Flairs = communities.NormalizeFlairs(profile.Flairs)
+	existing.PostingRules = profile.PostingRules
+	existing.ScoreHidingHours = communities.NormalizeScoreHidingHours(profile.ScoreHidingHours)
+	existing.CollapseThreshold = communities.NormalizeCollapseThreshold(profile.CollapseThreshold)
+	existing.CrowdControl = communities.NormalizeCrowdControl(profile.CrowdControl)
 	existing.RecordCID = commit.CID
-
-	// Update blobs
-	if avatarCID, ok := extractBlobCID(profile.Avatar); ok {
-		existing.AvatarCID = avatarCID
-	}
-	if bannerCID, ok := extractBlobCID(profile.Banner); ok {
-		existing.BannerCID = bannerCID
-	}
-
-	// Update description facets
+	existing.RecordRev = commit.Rev
+	existing.RawRecord = marshalRawRecord(commit.Record)
+	if existing.Remote {
+		// The firehose record supersedes a peer directory entry
+		existing.Remote = false
+		existing.RecordURI = fmt.Sprintf("at://%s/social.coves.community.profile/self", did)
+	}
+
+	// The record is the whole profile: an optional field it leaves out was removed,
+	// so clear it rather than keeping the previously indexed value
+	existing.AvatarCID, _ = extractBlobCID(profile.Avatar)
+	existing.BannerCID, _ = extractBlobCID(profile.Banner)
+	existing.DescriptionFacets = nil
 	if profile.DescriptionFacets != nil {
 		facetsJSON, marshalErr := json.Marshal(profile.DescriptionFacets)
-		if marshalErr == nil {
+		if marshalErr != nil {
+			log.Printf("WARNING: Failed to marshal description facets for community %s: %v (facets will be omitted)", did, marshalErr)
+		} else {
 			existing.DescriptionFacets = facetsJSON
 		}
 	}
 
+	// Re-apply federation policy (hostedBy is immutable, but rules may have changed)
+	existing.FederationBlocked, err = c.isFederationBlocked(ctx, existing.HostedByDID)
+	if err != nil {
+		return err
+	}
+
+	// Re-run the impersonation heuristics on the new name and display name
+	// A reason an admin already cleared doesn't flag the community again
+	impersonationReason, err := c.detectImpersonation(ctx, did, existing.Handle, existing.HostedByDID, profile)
+	if err != nil {
+		return err
+	}
+	if impersonationReason == existing.ImpersonationCleared {
+		impersonationReason = ""
+	}
+	if impersonationReason != "" && !existing.ImpersonationFlag {
+		log.Printf("🚨 SECURITY: Flagging community %s (%s) for impersonation review: %s", did, existing.Handle, impersonationReason)
+	}
+	existing.ImpersonationFlag = impersonationReason != ""
+	existing.ImpersonationReason = impersonationReason
+
 	// Save updates
 	_, err = c.repo.Update(ctx, existing)
 	if err != nil {
 		return fmt.Errorf("failed to update community: %w", err)
 	}
+	trackBlobReferences(ctx, c.blobRefs, communityProfileURI(did), commit.Record)
 
 	log.Printf("Updated community: %s (%s)", existing.Handle, existing.DID)
 	return nil
 }
 
+// communityProfileURI returns the AT-URI of a community's profile record
+func communityProfileURI(did string) string {
+	return fmt.Sprintf("at://%s/social.coves.community.profile/self", did)
+}
+
 // deleteCommunity removes a community from the index
-func (c *CommunityEventConsumer) deleteCommunity(ctx context.Context, did string) error {
-	err := c.repo.Delete(ctx, did)
+// The deleting rev is kept as a tombstone, so a replay of an older create doesn't re-index it
+func (c *CommunityEventConsumer) deleteCommunity(ctx context.Context, did string, commit *CommitEvent) error {
+	uri := communityProfileURI(did)
+	existing, err := c.repo.GetByDID(ctx, did)
+	if err != nil && !communities.IsNotFound(err) {
+		return fmt.Errorf("failed to get existing community: %w", err)
+	}
+	// A delete replayed after the community recreated its profile must not remove it
+	if existing != nil && !existing.Remote && c.replay.stale(commit, uri, existing.RecordRev) {
+		return nil
+	}
+	if err := c.replay.recordDelete(ctx, commit, uri); err != nil {
+		return err
+	}
+
+	err = c.repo.Delete(ctx, did)
 	if err != nil {
 		if communities.IsNotFound(err) {
 			log.Printf("Community already deleted: %s", did)
@@ -360,6 +537,7 @@ func (c *CommunityEventConsumer) deleteCommunity(ctx context.Context, did string
 		}
 		return fmt.Errorf("failed to delete community: %w", err)
 	}
+	trackBlobReferences(ctx, c.blobRefs, uri, nil)
 
 	log.Printf("Deleted community: %s", did)
 	return nil
@@ -367,7 +545,8 @@ func (c *CommunityEventConsumer) deleteCommunity(ctx context.Context, did string
 
 // verifyHostedByClaim verifies that the community's hostedBy claim matches the handle domain
 // This prevents malicious instances from claiming to host communities for domains they don't own
-func (c *CommunityEventConsumer) verifyHostedByClaim(ctx context.Context, handle, hostedByDID string) error {
+// Instance-native did:web communities must also have their DID under the hosting instance's domain
+func (c *CommunityEventConsumer) verifyHostedByClaim(ctx context.Context, communityDID, handle, hostedByDID string) error {
 	// Skip verification in dev mode
 	if c.skipVerification {
 		return nil
@@ -399,6 +578,12 @@ func (c *CommunityEventConsumer) verifyHostedByClaim(ctx context.Context, handle
 		return fmt.Errorf("handle domain (%s) doesn't match hostedBy domain (%s)", handleDomain, hostedByDomain)
 	}
 
+	// did:web communities are served by their hosting instance, so the DID's host must be
+	// on the instance's domain - otherwise an instance could claim a did:web it doesn't serve
+	if err := verifyDIDWebCommunity(communityDID, hostedByDomain); err != nil {
+		return err
+	}
+
 	// SECURITY: Verify DID document exists and is valid (Bluesky-compatible security model)
 	// MANDATORY bidirectional verification: DID document must claim this handle in alsoKnownAs
 	// This matches Bluesky's security requirements and prevents domain impersonation
@@ -410,124 +595,26 @@ func (c *CommunityEventConsumer) verifyHostedByClaim(ctx context.Context, handle
 	return nil
 }
 
-// verifyDIDDocument fetches and validates the DID document from .well-known/did.json
-// Implements Bluesky's bidirectional verification model:
-//  1. Verify DID document exists at https://domain/.well-known/did.json
-//  2. Verify DID document ID matches claimed DID
-//  3. Verify DID document claims the handle in alsoKnownAs field
-//
-// Results are cached with TTL and rate-limited to prevent DoS attacks
-func (c *CommunityEventConsumer) verifyDIDDocument(ctx context.Context, did, domain, handle string) error {
-	// Skip verification in dev mode
-	if c.skipVerification {
+// verifyDIDWebCommunity checks a did:web community DID is hosted under hostedByDomain
+// did:plc communities have no domain to check and always pass
+func verifyDIDWebCommunity(communityDID, hostedByDomain string) error {
+	host, ok := strings.CutPrefix(communityDID, "did:web:")
+	if !ok {
 		return nil
 	}
 
-	// Check bounded LRU cache first (thread-safe, no locks needed)
-	if cached, ok := c.didCache.Get(did); ok {
-		// Check if cache entry is still valid (not expired)
-		if time.Now().Before(cached.expiresAt) {
-			if !cached.valid {
-				return fmt.Errorf("cached verification failure for %s", did)
-			}
-			log.Printf("✓ DID document verification (cached): %s", domain)
-			return nil
-		}
-		// Cache entry expired - remove it to free up space for fresh entries
-		c.didCache.Remove(did)
-	}
-
-	// Rate limit .well-known fetches to prevent DoS
-	if err := c.wellKnownLimiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limit exceeded for .well-known fetch: %w", err)
-	}
-
-	// Construct .well-known URL
-	didDocURL := fmt.Sprintf("https://%s/.well-known/did.json", domain)
-
-	// Create HTTP request with timeout
-	req, err := http.NewRequestWithContext(ctx, "GET", didDocURL, nil)
-	if err != nil {
-		// Cache the failure
-		c.cacheVerificationResult(did, false, 5*time.Minute)
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	// Fetch DID document using shared HTTP client
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		// Cache the failure (shorter TTL for network errors)
-		c.cacheVerificationResult(did, false, 5*time.Minute)
-		return fmt.Errorf("failed to fetch DID document from %s: %w", didDocURL, err)
-	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			log.Printf("Failed to close response body: %v", closeErr)
-		}
-	}()
-
-	// Verify HTTP status
-	if resp.StatusCode != http.StatusOK {
-		// Cache the failure
-		c.cacheVerificationResult(did, false, 5*time.Minute)
-		return fmt.Errorf("DID document returned HTTP %d from %s", resp.StatusCode, didDocURL)
+	// did:web encodes a port as %3A - only the host is relevant
+	host, _, _ = strings.Cut(strings.ToLower(host), "%3a")
+	if host == "" || strings.Contains(host, ":") {
+		return fmt.Errorf("invalid did:web community DID: %s", communityDID)
 	}
 
-	// Parse DID document
-	var didDoc struct {
-		ID          string   `json:"id"`
-		AlsoKnownAs []string `json:"alsoKnownAs"`
+	if didDomain := extractDomainFromHandle(host); didDomain != hostedByDomain {
+		return fmt.Errorf("did:web community domain (%s) doesn't match hostedBy domain (%s)", didDomain, hostedByDomain)
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&didDoc); err != nil {
-		// Cache the failure
-		c.cacheVerificationResult(did, false, 5*time.Minute)
-		return fmt.Errorf("failed to parse DID document JSON: %w", err)
-	}
-
-	// Verify DID document ID matches claimed DID
-	if didDoc.ID != did {
-		// Cache the failure
-		c.cacheVerificationResult(did, false, 5*time.Minute)
-		return fmt.Errorf("DID document ID (%s) doesn't match claimed DID (%s)", didDoc.ID, did)
-	}
-
-	// SECURITY: Bidirectional verification - DID document must claim this handle
-	// Prevents impersonation where someone points DNS to another user's DID
-	// Format: handle "coves.social" or "!community@coves.social" → check for "at://coves.social"
-	handleDomain := extractDomainFromHandle(handle)
-	expectedAlias := fmt.Sprintf("at://%s", handleDomain)
-
-	found := false
-	for _, alias := range didDoc.AlsoKnownAs {
-		if alias == expectedAlias {
-			found = true
-			break
-		}
-	}
-
-	if !found {
-		// Cache the failure
-		c.cacheVerificationResult(did, false, 5*time.Minute)
-		return fmt.Errorf("DID document does not claim handle domain %s in alsoKnownAs (expected %s, got %v)",
-			handleDomain, expectedAlias, didDoc.AlsoKnownAs)
-	}
-
-	// Cache the success (24 hour TTL - matches Bluesky recommendations)
-	c.cacheVerificationResult(did, true, 24*time.Hour)
-
-	log.Printf("✓ DID document verified: %s", domain)
 	return nil
 }
 
-// cacheVerificationResult stores a verification result in the bounded LRU cache with the given TTL
-// The LRU cache is thread-safe and automatically evicts least-recently-used entries when full
-func (c *CommunityEventConsumer) cacheVerificationResult(did string, valid bool, ttl time.Duration) {
-	c.didCache.Add(did, cachedDIDDoc{
-		valid:     valid,
-		expiresAt: time.Now().Add(ttl),
-	})
-}
-
 // extractDomainFromHandle extracts the registrable domain from a community handle
 // Handles both formats:
 //   - Bluesky-style: "!gaming@coves.social" → "coves.social"
@@ -617,6 +704,11 @@ func (c *CommunityEventConsumer) createSubscription(ctx context.Context, userDID
 	// The record lives in the USER's repository, but uses the communities namespace
 	uri := fmt.Sprintf("at://%s/social.coves.community.subscription/%s", userDID, commit.RKey)
 
+	// A create replayed after the user unsubscribed must not subscribe them again
+	if deleted, err := c.replay.deletedAfter(ctx, commit, uri); err != nil || deleted {
+		return err
+	}
+
 	// Create subscription entity
 	// Parse createdAt from record to preserve chronological ordering during replays
 	subscription := &communities.Subscription{
@@ -628,9 +720,29 @@ func (c *CommunityEventConsumer) createSubscription(ctx context.Context, userDID
 		RecordCID:         commit.CID,
 	}
 
-	// Use transactional method to ensure subscription and count are atomically updated
-	// This is idempotent - safe for Jetstream replays
-	_, err := c.repo.SubscribeWithCount(ctx, subscription)
+	// A subscription written through this AppView was already indexed (and counted) as pending;
+	// confirm it instead of counting it again
+	confirmed, err := c.repo.ConfirmSubscription(ctx, uri, commit.CID)
+	if err != nil {
+		return fmt.Errorf("failed to confirm subscription: %w", err)
+	}
+	if confirmed {
+		log.Printf("✓ Confirmed subscription: %s -> %s", userDID, communityDID)
+		return nil
+	}
+
+	if c.subscriberCounts != nil {
+		// Index the subscription now; the count change is coalesced with other events
+		// Duplicates come back as a conflict, so replays don't count twice
+		_, err = c.repo.Subscribe(ctx, subscription)
+		if err == nil {
+			c.subscriberCounts.Add(communityDID, 1)
+		}
+	} else {
+		// Use transactional method to ensure subscription and count are atomically updated
+		// This is idempotent - safe for Jetstream replays
+		_, err = c.repo.SubscribeWithCount(ctx, subscription)
+	}
 	if err != nil {
 		// If already exists, that's fine (idempotency)
 		if communities.IsConflict(err) {
@@ -653,6 +765,12 @@ func (c *CommunityEventConsumer) deleteSubscription(ctx context.Context, userDID
 	// Build AT-URI from the rkey
 	uri := fmt.Sprintf("at://%s/social.coves.community.subscription/%s", userDID, commit.RKey)
 
+	// Subscription rkeys are TIDs and never reused, so a delete is never older than the
+	// record it deletes; its rev only guards against a replayed create
+	if err := c.replay.recordDelete(ctx, commit, uri); err != nil {
+		return err
+	}
+
 	// Look up the subscription to get the community DID
 	// (DELETE operations don't include record data in Jetstream)
 	subscription, err := c.repo.GetSubscriptionByURI(ctx, uri)
@@ -665,9 +783,17 @@ func (c *CommunityEventConsumer) deleteSubscription(ctx context.Context, userDID
 		return fmt.Errorf("failed to find subscription for deletion: %w", err)
 	}
 
-	// Use transactional method to ensure unsubscribe and count are atomically updated
-	// This is idempotent - safe for Jetstream replays
-	err = c.repo.UnsubscribeWithCount(ctx, userDID, subscription.CommunityDID)
+	if c.subscriberCounts != nil {
+		// Only a delete that removed the row changes the count
+		err = c.repo.Unsubscribe(ctx, userDID, subscription.CommunityDID)
+		if err == nil {
+			c.subscriberCounts.Add(subscription.CommunityDID, -1)
+		}
+	} else {
+		// Use transactional method to ensure unsubscribe and count are atomically updated
+		// This is idempotent - safe for Jetstream replays
+		err = c.repo.UnsubscribeWithCount(ctx, userDID, subscription.CommunityDID)
+	}
 	if err != nil {
 		if communities.IsNotFound(err) {
 			log.Printf("Subscription already removed: %s -> %s", userDID, subscription.CommunityDID)
@@ -713,6 +839,11 @@ func (c *CommunityEventConsumer) createBlock(ctx context.Context, userDID string
 	// The record lives in the USER's repository
 	uri := fmt.Sprintf("at://%s/social.coves.community.block/%s", userDID, commit.RKey)
 
+	// A create replayed after the user unblocked the community must not block it again
+	if deleted, err := c.replay.deletedAfter(ctx, commit, uri); err != nil || deleted {
+		return err
+	}
+
 	// Create block entity
 	// Parse createdAt from record to preserve chronological ordering during replays
 	block := &communities.CommunityBlock{
@@ -723,9 +854,20 @@ func (c *CommunityEventConsumer) createBlock(ctx context.Context, userDID string
 		RecordCID:    commit.CID,
 	}
 
+	// A block written through this AppView was already indexed as pending; confirm it
+	confirmed, err := c.repo.ConfirmBlock(ctx, uri, commit.CID)
+	if err != nil {
+		return fmt.Errorf("failed to confirm block: %w", err)
+	}
+	if confirmed {
+		log.Printf("✓ Confirmed block: %s -> %s", userDID, communityDID)
+		return nil
+	}
+
 	// Index the block
-	// This is idempotent - safe for Jetstream replays
-	_, err := c.repo.BlockCommunity(ctx, block)
+	// This is idempotent - safe for Jetstream replays (and clears a pending flag left by a
+	// local write with a different record URI)
+	_, err = c.repo.BlockCommunity(ctx, block)
 	if err != nil {
 		// If already exists, that's fine (idempotency)
 		if communities.IsConflict(err) {
@@ -746,6 +888,11 @@ func (c *CommunityEventConsumer) deleteBlock(ctx context.Context, userDID string
 	// Build AT-URI from the rkey
 	uri := fmt.Sprintf("at://%s/social.coves.community.block/%s", userDID, commit.RKey)
 
+	// Like subscriptions, block rkeys are never reused; the rev only guards against a replayed create
+	if err := c.replay.recordDelete(ctx, commit, uri); err != nil {
+		return err
+	}
+
 	// Look up the block to get the community DID
 	// (DELETE operations don't include record data in Jetstream)
 	block, err := c.repo.GetBlockByURI(ctx, uri)
@@ -775,25 +922,31 @@ func (c *CommunityEventConsumer) deleteBlock(ctx context.Context, userDID string
 // Helper types and functions
 
 type CommunityProfile struct {
-	CreatedAt         time.Time              `json:"createdAt"`
-	Avatar            map[string]interface{} `json:"avatar"`
-	Banner            map[string]interface{} `json:"banner"`
-	CreatedBy         string                 `json:"createdBy"`
-	Visibility        string                 `json:"visibility"`
-	AtprotoHandle     string                 `json:"atprotoHandle"`
-	DisplayName       string                 `json:"displayName"`
-	Name              string                 `json:"name"`
-	Handle            string                 `json:"handle"`
-	HostedBy          string                 `json:"hostedBy"`
-	Description       string                 `json:"description"`
-	FederatedID       string                 `json:"federatedId"`
-	ModerationType    string                 `json:"moderationType"`
-	FederatedFrom     string                 `json:"federatedFrom"`
-	ContentWarnings   []string               `json:"contentWarnings"`
-	DescriptionFacets []interface{}          `json:"descriptionFacets"`
-	MemberCount       int                    `json:"memberCount"`
-	SubscriberCount   int                    `json:"subscriberCount"`
-	Federation        FederationConfig       `json:"federation"`
+	CreatedAt         time.Time                `json:"createdAt"`
+	Avatar            map[string]interface{}   `json:"avatar"`
+	Banner            map[string]interface{}   `json:"banner"`
+	CreatedBy         string                   `json:"createdBy"`
+	Visibility        string                   `json:"visibility"`
+	AtprotoHandle     string                   `json:"atprotoHandle"`
+	DisplayName       string                   `json:"displayName"`
+	Name              string                   `json:"name"`
+	Handle            string                   `json:"handle"`
+	HostedBy          string                   `json:"hostedBy"`
+	Description       string                   `json:"description"`
+	FederatedID       string                   `json:"federatedId"`
+	ModerationType    string                   `json:"moderationType"`
+	CrowdControl      string                   `json:"crowdControl"`
+	FederatedFrom     string                   `json:"federatedFrom"`
+	ContentWarnings   []string                 `json:"contentWarnings"`
+	Categories        []string                 `json:"categories"`
+	Flairs            []communities.Flair      `json:"flairs"`
+	PostingRules      communities.PostingRules `json:"postingRules"`
+	DescriptionFacets []interface{}            `json:"descriptionFacets"`
+	MemberCount       int                      `json:"memberCount"`
+	SubscriberCount   int                      `json:"subscriberCount"`
+	ScoreHidingHours  int                      `json:"scoreHidingHours"`
+	CollapseThreshold *int                     `json:"collapseThreshold"`
+	Federation        FederationConfig         `json:"federation"`
 }
 
 type FederationConfig struct {
@@ -0,0 +1,28 @@
+package jetstream
+
+import "testing"
+
+func TestVerifyDIDWebCommunity(t *testing.T) {
+	tests := []struct {
+		name           string
+		communityDID   string
+		hostedByDomain string
+		wantErr        bool
+	}{
+		{name: "did:plc is not domain-bound", communityDID: "did:plc:abc123", hostedByDomain: "coves.social"},
+		{name: "subdomain of the instance", communityDID: "did:web:gaming.communities.coves.social", hostedByDomain: "coves.social"},
+		{name: "port is ignored", communityDID: "did:web:gaming.coves.social%3A8443", hostedByDomain: "coves.social"},
+		{name: "another instance's domain", communityDID: "did:web:gaming.evil.example", hostedByDomain: "coves.social", wantErr: true},
+		{name: "path-based did:web", communityDID: "did:web:coves.social:gaming", hostedByDomain: "coves.social", wantErr: true},
+		{name: "empty host", communityDID: "did:web:", hostedByDomain: "coves.social", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyDIDWebCommunity(tt.communityDID, tt.hostedByDomain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("verifyDIDWebCommunity(%q, %q) error = %v, wantErr %v", tt.communityDID, tt.hostedByDomain, err, tt.wantErr)
+			}
+		})
+	}
+}
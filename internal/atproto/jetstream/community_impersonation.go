@@ -0,0 +1,53 @@
+package jetstream
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// maxImpersonationLookups bounds the handle lookups per profile, so a display name stuffed
+// with handles can't turn one event into many queries
+const maxImpersonationLookups = 5
+
+// detectImpersonation runs the impersonation heuristics on a community profile, returning
+// why it looks like it impersonates another community or "" when it doesn't
+// Claimed handles are looked up to find which belong to other verified communities: indexed,
+// not flagged or suspended themselves, and not on a blocked instance.
+func (c *CommunityEventConsumer) detectImpersonation(ctx context.Context, did, handle, hostedByDID string, profile *CommunityProfile) (string, error) {
+	claims := append(communities.ClaimedHandles(profile.Name), communities.ClaimedHandles(profile.DisplayName)...)
+
+	verified := make(map[string]bool)
+	for i, claim := range claims {
+		if i == maxImpersonationLookups {
+			break
+		}
+		if _, checked := verified[claim.Handle]; checked || claim.Handle == strings.ToLower(handle) {
+			continue
+		}
+		other, err := c.repo.GetByHandle(ctx, claim.Handle)
+		if err != nil {
+			if communities.IsNotFound(err) {
+				verified[claim.Handle] = false
+				continue
+			}
+			return "", fmt.Errorf("failed to look up claimed handle %s: %w", claim.Handle, err)
+		}
+		verified[claim.Handle] = other.DID != did && !other.ImpersonationFlag && !other.FederationBlocked && other.SuspendedAt == nil
+	}
+
+	// Without a did:web host (dev mode) there's no domain to compare claims against
+	var hostedByDomain string
+	if strings.HasPrefix(hostedByDID, "did:web:") {
+		hostedByDomain = strings.TrimPrefix(hostedByDID, "did:web:")
+	}
+
+	return communities.DetectImpersonation(communities.ImpersonationCheck{
+		Name:            profile.Name,
+		DisplayName:     profile.DisplayName,
+		Handle:          handle,
+		HostedByDomain:  hostedByDomain,
+		VerifiedHandles: verified,
+	}), nil
+}
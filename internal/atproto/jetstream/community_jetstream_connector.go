@@ -2,7 +2,6 @@ package jetstream
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"sync"
@@ -105,7 +104,7 @@ func (c *CommunityJetstreamConnector) connect(ctx context.Context) error {
 		case <-done:
 			return fmt.Errorf("connection closed")
 		default:
-			_, message, err := conn.ReadMessage()
+			event, err := readEvent(conn)
 			if err != nil {
 				closeOnce.Do(func() { close(done) })
 				return fmt.Errorf("read error: %w", err)
@@ -116,21 +115,15 @@ func (c *CommunityJetstreamConnector) connect(ctx context.Context) error {
 				log.Printf("Failed to set read deadline: %v", err)
 			}
 
-			if err := c.handleEvent(ctx, message); err != nil {
+			if event == nil {
+				// Oversized or malformed message, already logged
+				continue
+			}
+
+			if err := c.consumer.HandleEvent(ctx, event); err != nil {
 				log.Printf("Error handling community event: %v", err)
 				// Continue processing other events
 			}
 		}
 	}
 }
-
-// handleEvent processes a single Jetstream event
-func (c *CommunityJetstreamConnector) handleEvent(ctx context.Context, data []byte) error {
-	var event JetstreamEvent
-	if err := json.Unmarshal(data, &event); err != nil {
-		return fmt.Errorf("failed to parse event: %w", err)
-	}
-
-	// Pass to consumer's HandleEvent method
-	return c.consumer.HandleEvent(ctx, &event)
-}
@@ -0,0 +1,139 @@
+package jetstream
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/sanitize"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// handleCommunityRules processes social.coves.community.rules create/update/delete events
+// The rules record lives in the community's own repo, so event.Did is the community DID
+func (c *CommunityEventConsumer) handleCommunityRules(ctx context.Context, event *JetstreamEvent) error {
+	if c.rules == nil {
+		return nil
+	}
+
+	commit := event.Commit
+	if commit.RKey != "self" {
+		return fmt.Errorf("invalid community rules rkey: expected 'self', got '%s'", commit.RKey)
+	}
+
+	switch commit.Operation {
+	case "create", "update":
+		return c.indexCommunityRules(ctx, event)
+	case "delete":
+		return c.deleteCommunityRules(ctx, event)
+	default:
+		log.Printf("Unknown operation for community rules: %s", commit.Operation)
+		return nil
+	}
+}
+
+// indexCommunityRules sanitizes and stores a rules record
+// Oversize documents are rejected (dead-lettered when a DLQ is configured) rather than
+// truncated, since a cut-off rules document could change its meaning
+func (c *CommunityEventConsumer) indexCommunityRules(ctx context.Context, event *JetstreamEvent) error {
+	commit := event.Commit
+	if commit.Record == nil {
+		return fmt.Errorf("community rules event missing record data")
+	}
+
+	// A write replayed after the community deleted its rules must not restore them
+	uri := fmt.Sprintf("at://%s/social.coves.community.rules/self", event.Did)
+	if deleted, err := c.replay.deletedAfter(ctx, commit, uri); err != nil || deleted {
+		return err
+	}
+
+	markdown, _ := commit.Record["markdown"].(string)
+	if err := communities.ValidateRules(markdown, nil); err != nil {
+		return deadLetter(ctx, c.dlq, event, fmt.Errorf("rejecting rules for community %s: %w", event.Did, err))
+	}
+
+	rules := &communities.CommunityRules{
+		CommunityDID: event.Did,
+		Markdown:     sanitize.Markdown(markdown),
+		Rules:        parseTextRules(commit.Record["textRules"]),
+		RecordURI:    uri,
+		RecordCID:    commit.CID,
+		Rev:          commit.Rev,
+		UpdatedAt:    time.Now(),
+	}
+
+	if err := c.rules.UpsertRules(ctx, rules); err != nil {
+		if errors.Is(err, communities.ErrStaleRevision) {
+			c.replay.skipped(commit, uri)
+			return nil
+		}
+		return fmt.Errorf("failed to index community rules: %w", err)
+	}
+
+	log.Printf("Indexed rules for community %s (%d bytes, %d rules)", event.Did, len(rules.Markdown), len(rules.Rules))
+	return nil
+}
+
+// deleteCommunityRules removes a community's rules document, keeping the deleting rev as a tombstone
+func (c *CommunityEventConsumer) deleteCommunityRules(ctx context.Context, event *JetstreamEvent) error {
+	commit := event.Commit
+	uri := fmt.Sprintf("at://%s/social.coves.community.rules/self", event.Did)
+
+	// A delete replayed after the rules were written again must not remove them
+	existing, err := c.rules.GetRules(ctx, event.Did)
+	if err != nil && !errors.Is(err, communities.ErrRulesNotFound) {
+		return fmt.Errorf("failed to get community rules: %w", err)
+	}
+	if existing != nil && c.replay.stale(commit, uri, existing.Rev) {
+		return nil
+	}
+	if err := c.replay.recordDelete(ctx, commit, uri); err != nil {
+		return err
+	}
+
+	if err := c.rules.DeleteRules(ctx, event.Did); err != nil {
+		return fmt.Errorf("failed to delete community rules: %w", err)
+	}
+	log.Printf("Deleted rules for community %s", event.Did)
+	return nil
+}
+
+// parseTextRules extracts the active structured rules from a rules record
+// Records can come from any PDS, so invalid rules are dropped instead of rejecting the
+// whole document, and the list is capped at MaxRules
+func parseTextRules(value interface{}) []communities.Rule {
+	items, ok := value.([]interface{})
+	if !ok {
+		return []communities.Rule{}
+	}
+
+	rules := make([]communities.Rule, 0, min(len(items), communities.MaxRules))
+	for _, item := range items {
+		if len(rules) == communities.MaxRules {
+			break
+		}
+		ruleMap, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if active, ok := ruleMap["isActive"].(bool); ok && !active {
+			continue
+		}
+
+		rule := communities.Rule{}
+		rule.Title, _ = ruleMap["title"].(string)
+		rule.Description, _ = ruleMap["description"].(string)
+		if createdAt, ok := ruleMap["createdAt"].(string); ok {
+			if parsed, err := time.Parse(time.RFC3339, createdAt); err == nil {
+				rule.CreatedAt = parsed
+			}
+		}
+
+		if communities.ValidateRules("", []communities.Rule{rule}) != nil {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
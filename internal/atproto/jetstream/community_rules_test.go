@@ -0,0 +1,153 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/utils"
+	"Coves/internal/core/communities"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// memoryRulesRepo keeps indexed rules documents in memory
+type memoryRulesRepo struct {
+	rules map[string]*communities.CommunityRules
+}
+
+func newMemoryRulesRepo() *memoryRulesRepo {
+	return &memoryRulesRepo{rules: make(map[string]*communities.CommunityRules)}
+}
+
+func (m *memoryRulesRepo) GetRules(ctx context.Context, communityDID string) (*communities.CommunityRules, error) {
+	rules, ok := m.rules[communityDID]
+	if !ok {
+		return nil, communities.ErrRulesNotFound
+	}
+	return rules, nil
+}
+
+func (m *memoryRulesRepo) UpsertRules(ctx context.Context, rules *communities.CommunityRules) error {
+	if existing, ok := m.rules[rules.CommunityDID]; ok && utils.IsStaleRev(rules.Rev, existing.Rev) {
+		return communities.ErrStaleRevision
+	}
+	m.rules[rules.CommunityDID] = rules
+	return nil
+}
+
+func (m *memoryRulesRepo) DeleteRules(ctx context.Context, communityDID string) error {
+	delete(m.rules, communityDID)
+	return nil
+}
+
+func newRulesEvent(markdown string, textRules []interface{}) *JetstreamEvent {
+	record := map[string]interface{}{
+		"$type":    "social.coves.community.rules",
+		"markdown": markdown,
+	}
+	if textRules != nil {
+		record["textRules"] = textRules
+	}
+	return newTestCommitEvent("did:plc:community", "social.coves.community.rules", "self", record)
+}
+
+func TestCommunityRules_IndexesSanitizedMarkdown(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryRulesRepo()
+	consumer := NewCommunityEventConsumer(nil, "did:web:coves.social", true, nil)
+	consumer.SetRulesRepository(repo)
+
+	event := newRulesEvent("# Welcome\n<script>alert(1)</script>\n##### Be kind", []interface{}{
+		map[string]interface{}{"title": "No spam", "description": "Self-promotion is limited", "createdAt": "2024-01-01T00:00:00Z", "isActive": true},
+		map[string]interface{}{"title": "Retired rule", "createdAt": "2024-01-01T00:00:00Z", "isActive": false},
+		map[string]interface{}{"description": "Missing a title"},
+	})
+	if err := consumer.HandleEvent(ctx, event); err != nil {
+		t.Fatalf("Expected rules to be indexed, got %v", err)
+	}
+
+	stored, err := repo.GetRules(ctx, "did:plc:community")
+	if err != nil {
+		t.Fatalf("Expected rules to be stored: %v", err)
+	}
+	if stored.Markdown != "# Welcome\n\n### Be kind" {
+		t.Errorf("Expected sanitized markdown, got %q", stored.Markdown)
+	}
+	if len(stored.Rules) != 1 || stored.Rules[0].Title != "No spam" {
+		t.Errorf("Expected only the active, valid rule, got %+v", stored.Rules)
+	}
+	if stored.RecordURI != "at://did:plc:community/social.coves.community.rules/self" {
+		t.Errorf("Unexpected record URI %s", stored.RecordURI)
+	}
+
+	// Deleting the record removes the document
+	event.Commit.Operation = "delete"
+	event.Commit.Record = nil
+	if err := consumer.HandleEvent(ctx, event); err != nil {
+		t.Fatalf("Expected delete to succeed, got %v", err)
+	}
+	if _, err := repo.GetRules(ctx, "did:plc:community"); !errors.Is(err, communities.ErrRulesNotFound) {
+		t.Errorf("Expected rules to be deleted, got %v", err)
+	}
+}
+
+func TestCommunityRules_RejectsOversizeMarkdown(t *testing.T) {
+	ctx := context.Background()
+	oversize := strings.Repeat("a", communities.MaxRulesMarkdownBytes+1)
+
+	t.Run("without a dead letter queue", func(t *testing.T) {
+		repo := newMemoryRulesRepo()
+		consumer := NewCommunityEventConsumer(nil, "did:web:coves.social", true, nil)
+		consumer.SetRulesRepository(repo)
+
+		err := consumer.HandleEvent(ctx, newRulesEvent(oversize, nil))
+		if !errors.Is(err, communities.ErrRulesTooLarge) {
+			t.Fatalf("Expected ErrRulesTooLarge, got %v", err)
+		}
+		if len(repo.rules) != 0 {
+			t.Error("Oversize rules must not be indexed")
+		}
+	})
+
+	t.Run("dead-lettered when a queue is configured", func(t *testing.T) {
+		repo := newMemoryRulesRepo()
+		dlq := &mockDeadLetterQueue{}
+		consumer := NewCommunityEventConsumer(nil, "did:web:coves.social", true, nil)
+		consumer.SetRulesRepository(repo)
+		consumer.SetDeadLetterQueue(dlq)
+
+		if err := consumer.HandleEvent(ctx, newRulesEvent(oversize, nil)); err != nil {
+			t.Fatalf("Expected event to be dead-lettered, got %v", err)
+		}
+		if len(dlq.events) != 1 || !strings.Contains(dlq.reasons[0], communities.ErrRulesTooLarge.Error()) {
+			t.Errorf("Expected one dead-lettered event for oversize rules, got %v", dlq.reasons)
+		}
+		if len(repo.rules) != 0 {
+			t.Error("Oversize rules must not be indexed")
+		}
+	})
+
+	t.Run("exactly at the limit is accepted", func(t *testing.T) {
+		repo := newMemoryRulesRepo()
+		consumer := NewCommunityEventConsumer(nil, "did:web:coves.social", true, nil)
+		consumer.SetRulesRepository(repo)
+
+		if err := consumer.HandleEvent(ctx, newRulesEvent(oversize[1:], nil)); err != nil {
+			t.Fatalf("Expected rules at the size limit to be indexed, got %v", err)
+		}
+	})
+}
+
+func TestCommunityRules_RequiresSelfRkey(t *testing.T) {
+	repo := newMemoryRulesRepo()
+	consumer := NewCommunityEventConsumer(nil, "did:web:coves.social", true, nil)
+	consumer.SetRulesRepository(repo)
+
+	event := newRulesEvent("# Rules", nil)
+	event.Commit.RKey = "other"
+	if err := consumer.HandleEvent(context.Background(), event); err == nil {
+		t.Fatal("Expected non-self rkey to be rejected")
+	}
+	if len(repo.rules) != 0 {
+		t.Error("Rules with a non-self rkey must not be indexed")
+	}
+}
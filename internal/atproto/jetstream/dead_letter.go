@@ -0,0 +1,156 @@
+package jetstream
+
+import (
+	"Coves/internal/lexicon/validate"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ErrRecordTypeMismatch is returned when a record's $type doesn't match the collection it was written to
+var ErrRecordTypeMismatch = errors.New("record $type does not match collection")
+
+// DeadLetterQueue stores Jetstream events that consumers refuse to index
+// Dead-lettered events are kept for inspection and replay instead of being silently dropped
+type DeadLetterQueue interface {
+	Enqueue(ctx context.Context, event *JetstreamEvent, reason string) error
+}
+
+type postgresDeadLetterQueue struct {
+	db *sql.DB
+}
+
+// NewPostgresDeadLetterQueue creates a dead letter queue backed by the jetstream_dead_letters table
+func NewPostgresDeadLetterQueue(db *sql.DB) DeadLetterQueue {
+	return &postgresDeadLetterQueue{db: db}
+}
+
+// Enqueue stores the full event JSON along with the rejection reason
+func (q *postgresDeadLetterQueue) Enqueue(ctx context.Context, event *JetstreamEvent, reason string) error {
+	if event == nil || event.Commit == nil {
+		return fmt.Errorf("dead letter event missing commit data")
+	}
+
+	rawEvent, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter event: %w", err)
+	}
+
+	query := `
+		INSERT INTO jetstream_dead_letters (
+			did, collection, rkey, operation, cid, reason, raw_event
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	_, err = q.db.ExecContext(ctx, query,
+		event.Did,
+		event.Commit.Collection,
+		event.Commit.RKey,
+		event.Commit.Operation,
+		nullString(event.Commit.CID),
+		reason,
+		rawEvent,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to store dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// checkRecordType guards against records written under the wrong lexicon
+// Records without $type are tolerated (older clients omit it), but a $type naming a
+// different NSID than the collection means we'd be extracting fields from the wrong schema.
+// Unknown fields are NOT checked here - newer lexicon versions may add fields at any time.
+func checkRecordType(commit *CommitEvent) error {
+	if commit == nil || commit.Record == nil {
+		return nil
+	}
+
+	recordType, ok := commit.Record["$type"].(string)
+	if !ok || recordType == "" {
+		return nil
+	}
+
+	if recordType != commit.Collection {
+		return fmt.Errorf("%w: got %q, expected %q", ErrRecordTypeMismatch, recordType, commit.Collection)
+	}
+
+	return nil
+}
+
+// checkRecordSchema validates a record against the rules of its collection's lexicon
+// The returned *validate.Error lists every violation and the rules version that found them.
+// Validators only read the fields they know, so fields from newer lexicon versions pass.
+func checkRecordSchema(commit *CommitEvent) error {
+	if commit == nil || commit.Record == nil {
+		return nil
+	}
+	return validate.Record(commit.Collection, commit.Record)
+}
+
+// deadLetter routes a rejected event to the DLQ
+// Returns nil once the event is stored so the connector moves on; without a DLQ the
+// original error is returned and the event is only logged by the connector
+func deadLetter(ctx context.Context, dlq DeadLetterQueue, event *JetstreamEvent, cause error) error {
+	if dlq == nil {
+		return cause
+	}
+
+	if err := dlq.Enqueue(ctx, event, cause.Error()); err != nil {
+		log.Printf("Failed to dead-letter event %s/%s from %s: %v (original error: %v)",
+			event.Commit.Collection, event.Commit.RKey, event.Did, err, cause)
+		return cause
+	}
+
+	log.Printf("Dead-lettered event %s/%s from %s: %v",
+		event.Commit.Collection, event.Commit.RKey, event.Did, cause)
+	return nil
+}
+
+// guardRecord runs checkRecordType, checkRecordSize and checkRecordSchema for create/update
+// commits and dead-letters rejected records
+// Returns (true, err) when the event was rejected and must not be indexed
+func guardRecord(ctx context.Context, dlq DeadLetterQueue, event *JetstreamEvent) (bool, error) {
+	if event.Commit.Operation == "delete" {
+		return false, nil
+	}
+
+	if err := checkRecordType(event.Commit); err != nil {
+		return true, deadLetter(ctx, dlq, event, err)
+	}
+
+	if err := checkRecordSize(event.Commit); err != nil {
+		return true, deadLetter(ctx, dlq, event, err)
+	}
+
+	if err := checkRecordSchema(event.Commit); err != nil {
+		return true, deadLetter(ctx, dlq, event, err)
+	}
+
+	return false, nil
+}
+
+// marshalRawRecord serializes the record exactly as received (including unknown fields)
+// for storage in raw_record columns. Returns nil when there is no record.
+func marshalRawRecord(record map[string]interface{}) *string {
+	if record == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		log.Printf("Warning: Failed to marshal raw record (raw_record will be NULL): %v", err)
+		return nil
+	}
+
+	rawStr := string(raw)
+	return &rawStr
+}
+
+// nullString converts an empty string to a NULL column value
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,654 @@
+package jetstream
+
+import (
+	"Coves/internal/core/votes"
+	"Coves/internal/lexicon/validate"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockDeadLetterQueue records enqueued events for assertions
+type mockDeadLetterQueue struct {
+	enqueueErr error
+	events     []*JetstreamEvent
+	reasons    []string
+}
+
+func (m *mockDeadLetterQueue) Enqueue(ctx context.Context, event *JetstreamEvent, reason string) error {
+	if m.enqueueErr != nil {
+		return m.enqueueErr
+	}
+	m.events = append(m.events, event)
+	m.reasons = append(m.reasons, reason)
+	return nil
+}
+
+// newTestCommitEvent builds a commit event for the given collection and record
+func newTestCommitEvent(did, collection, rkey string, record map[string]interface{}) *JetstreamEvent {
+	return &JetstreamEvent{
+		Did:    did,
+		TimeUS: time.Now().UnixMicro(),
+		Kind:   "commit",
+		Commit: &CommitEvent{
+			Rev:        "rev123",
+			Operation:  "create",
+			Collection: collection,
+			RKey:       rkey,
+			CID:        "bafy123",
+			Record:     record,
+		},
+	}
+}
+
+func TestCheckRecordType(t *testing.T) {
+	tests := []struct {
+		record  map[string]interface{}
+		name    string
+		wantErr bool
+	}{
+		{
+			name:   "matching $type",
+			record: map[string]interface{}{"$type": "social.coves.feed.vote"},
+		},
+		{
+			name:   "missing $type is tolerated",
+			record: map[string]interface{}{"direction": "up"},
+		},
+		{
+			name:   "non-string $type is tolerated",
+			record: map[string]interface{}{"$type": 42},
+		},
+		{
+			name:    "mismatched $type",
+			record:  map[string]interface{}{"$type": "social.coves.community.post"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRecordType(&CommitEvent{Collection: "social.coves.feed.vote", Record: tt.record})
+			if tt.wantErr {
+				if !errors.Is(err, ErrRecordTypeMismatch) {
+					t.Errorf("Expected ErrRecordTypeMismatch, got: %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected no error, got: %v", err)
+			}
+		})
+	}
+}
+
+// TestConsumers_WrongTypeGoesToDeadLetterQueue verifies every record consumer rejects
+// a mismatched $type before touching its repositories (which are nil here)
+func TestConsumers_WrongTypeGoesToDeadLetterQueue(t *testing.T) {
+	wrongType := map[string]interface{}{
+		"$type":     "app.bsky.feed.post",
+		"createdAt": "2025-01-01T00:00:00Z",
+	}
+
+	type consumer interface {
+		HandleEvent(context.Context, *JetstreamEvent) error
+		SetDeadLetterQueue(DeadLetterQueue)
+	}
+
+	tests := []struct {
+		newConsumer func() consumer
+		name        string
+		collection  string
+		rkey        string
+	}{
+		{
+			name:        "post",
+			newConsumer: func() consumer { return NewPostEventConsumer(nil, nil, nil, nil) },
+			collection:  "social.coves.community.post",
+			rkey:        "post1",
+		},
+		{
+			name:        "comment",
+			newConsumer: func() consumer { return NewCommentEventConsumer(nil, nil) },
+			collection:  CommentCollection,
+			rkey:        "comment1",
+		},
+		{
+			name:        "vote",
+			newConsumer: func() consumer { return NewVoteEventConsumer(nil, nil, nil) },
+			collection:  "social.coves.feed.vote",
+			rkey:        "vote1",
+		},
+		{
+			name:        "community profile",
+			newConsumer: func() consumer { return NewCommunityEventConsumer(nil, "did:web:coves.social", true, nil) },
+			collection:  "social.coves.community.profile",
+			rkey:        "self",
+		},
+		{
+			name:        "community subscription",
+			newConsumer: func() consumer { return NewCommunityEventConsumer(nil, "did:web:coves.social", true, nil) },
+			collection:  "social.coves.community.subscription",
+			rkey:        "sub1",
+		},
+		{
+			name:        "aggregator service",
+			newConsumer: func() consumer { return NewAggregatorEventConsumer(nil) },
+			collection:  "social.coves.aggregator.service",
+			rkey:        "self",
+		},
+		{
+			name:        "aggregator authorization",
+			newConsumer: func() consumer { return NewAggregatorEventConsumer(nil) },
+			collection:  "social.coves.aggregator.authorization",
+			rkey:        "auth1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" with DLQ", func(t *testing.T) {
+			dlq := &mockDeadLetterQueue{}
+			c := tt.newConsumer()
+			c.SetDeadLetterQueue(dlq)
+
+			event := newTestCommitEvent("did:plc:author", tt.collection, tt.rkey, wrongType)
+			if err := c.HandleEvent(context.Background(), event); err != nil {
+				t.Fatalf("Expected nil error once dead-lettered, got: %v", err)
+			}
+
+			if len(dlq.events) != 1 {
+				t.Fatalf("Expected 1 dead-lettered event, got %d", len(dlq.events))
+			}
+			if dlq.events[0] != event {
+				t.Errorf("Expected the original event to be dead-lettered")
+			}
+		})
+
+		t.Run(tt.name+" without DLQ", func(t *testing.T) {
+			c := tt.newConsumer()
+
+			event := newTestCommitEvent("did:plc:author", tt.collection, tt.rkey, wrongType)
+			err := c.HandleEvent(context.Background(), event)
+			if !errors.Is(err, ErrRecordTypeMismatch) {
+				t.Errorf("Expected ErrRecordTypeMismatch, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestConsumers_DeadLetterQueueFailureReturnsCause(t *testing.T) {
+	dlq := &mockDeadLetterQueue{enqueueErr: errors.New("db down")}
+	c := NewVoteEventConsumer(nil, nil, nil)
+	c.SetDeadLetterQueue(dlq)
+
+	event := newTestCommitEvent("did:plc:author", "social.coves.feed.vote", "vote1",
+		map[string]interface{}{"$type": "social.coves.community.post"})
+
+	err := c.HandleEvent(context.Background(), event)
+	if !errors.Is(err, ErrRecordTypeMismatch) {
+		t.Errorf("Expected ErrRecordTypeMismatch when DLQ fails, got: %v", err)
+	}
+}
+
+func TestGuardRecord_SkipsDeletes(t *testing.T) {
+	dlq := &mockDeadLetterQueue{}
+	event := newTestCommitEvent("did:plc:community", "social.coves.aggregator.authorization", "auth1",
+		map[string]interface{}{"$type": "app.bsky.feed.post"})
+	event.Commit.Operation = "delete"
+
+	rejected, err := guardRecord(context.Background(), dlq, event)
+	if rejected || err != nil {
+		t.Errorf("Expected delete to bypass the type guard, got rejected=%v err=%v", rejected, err)
+	}
+	if len(dlq.events) != 0 {
+		t.Errorf("Expected no dead-lettered events, got %d", len(dlq.events))
+	}
+}
+
+// validTestRecords are well-formed records for each validated collection, keyed by collection
+func validTestRecords() map[string]map[string]interface{} {
+	ref := map[string]interface{}{"uri": "at://did:plc:community/social.coves.community.post/3kpost", "cid": "bafypost"}
+	return map[string]map[string]interface{}{
+		CommentCollection: {
+			"$type":     CommentCollection,
+			"content":   "Nice post",
+			"reply":     map[string]interface{}{"root": ref, "parent": ref},
+			"createdAt": "2025-01-01T00:00:00Z",
+		},
+		PostCollection: {
+			"$type":     PostCollection,
+			"community": "did:plc:author",
+			"author":    "did:plc:author",
+			"title":     "Hello",
+			"createdAt": "2025-01-01T00:00:00Z",
+		},
+		"social.coves.feed.vote": {
+			"$type":     "social.coves.feed.vote",
+			"subject":   ref,
+			"direction": "up",
+			"createdAt": "2025-01-01T00:00:00Z",
+		},
+		"social.coves.community.profile": {
+			"$type":     "social.coves.community.profile",
+			"name":      "gaming",
+			"hostedBy":  "did:web:coves.social",
+			"createdAt": "2025-01-01T00:00:00Z",
+		},
+		"social.coves.community.subscription": {
+			"$type":     "social.coves.community.subscription",
+			"subject":   "did:plc:community",
+			"createdAt": "2025-01-01T00:00:00Z",
+		},
+	}
+}
+
+func TestGuardRecord_AcceptsValidRecords(t *testing.T) {
+	for collection, record := range validTestRecords() {
+		t.Run(collection, func(t *testing.T) {
+			dlq := &mockDeadLetterQueue{}
+			event := newTestCommitEvent("did:plc:author", collection, "self", record)
+
+			rejected, err := guardRecord(context.Background(), dlq, event)
+			if rejected || err != nil {
+				t.Errorf("Expected a valid record to pass, got rejected=%v err=%v", rejected, err)
+			}
+			if len(dlq.events) != 0 {
+				t.Errorf("Expected no dead-lettered events, got reasons %v", dlq.reasons)
+			}
+		})
+	}
+}
+
+// TestGuardRecord_AcceptsUnknownFields runs every collection's validation path on records
+// from a newer lexicon version: unknown fields, at the top level and inside the objects the
+// validators inspect, must not get a record dead-lettered
+func TestGuardRecord_AcceptsUnknownFields(t *testing.T) {
+	records := validTestRecords()
+	records["social.coves.aggregator.service"] = map[string]interface{}{
+		"$type":       "social.coves.aggregator.service",
+		"did":         "did:plc:aggregator",
+		"displayName": "RSS Bot",
+		"createdAt":   "2025-01-01T00:00:00Z",
+	}
+	records["social.coves.aggregator.authorization"] = map[string]interface{}{
+		"$type":         "social.coves.aggregator.authorization",
+		"aggregatorDid": "did:plc:aggregator",
+		"communityDid":  "did:plc:community",
+		"createdBy":     "did:plc:mod",
+		"enabled":       true,
+		"createdAt":     "2025-01-01T00:00:00Z",
+	}
+
+	newerRef := map[string]interface{}{"uri": "at://did:plc:community/social.coves.community.post/3kpost", "cid": "bafypost", "weight": 2}
+	records[CommentCollection]["reply"] = map[string]interface{}{"root": newerRef, "parent": newerRef, "quote": newerRef}
+	records["social.coves.feed.vote"]["subject"] = newerRef
+	records["social.coves.community.profile"]["federation"] = map[string]interface{}{"allowExternalDiscovery": true, "newPolicy": "strict"}
+	records[PostCollection]["embed"] = map[string]interface{}{
+		"$type":    "social.coves.embed.external",
+		"external": map[string]interface{}{"uri": "https://example.com", "preview": map[string]interface{}{"newKey": true}},
+	}
+
+	for collection, record := range records {
+		t.Run(collection, func(t *testing.T) {
+			dlq := &mockDeadLetterQueue{}
+			event := newTestCommitEvent("did:plc:author", collection, "self", withExtraFields(record))
+
+			rejected, err := guardRecord(context.Background(), dlq, event)
+			if rejected || err != nil {
+				t.Errorf("Expected a record with unknown fields to pass, got rejected=%v err=%v", rejected, err)
+			}
+			if len(dlq.events) != 0 {
+				t.Errorf("Expected no dead-lettered events, got reasons %v", dlq.reasons)
+			}
+		})
+	}
+}
+
+// TestConsumers_InvalidRecordsGoToDeadLetterQueue replays the records each consumer rejected
+// before validation moved into the lexicon package: they are still rejected, now with the
+// violations as the dead letter reason
+func TestConsumers_InvalidRecordsGoToDeadLetterQueue(t *testing.T) {
+	type consumer interface {
+		HandleEvent(context.Context, *JetstreamEvent) error
+		SetDeadLetterQueue(DeadLetterQueue)
+	}
+	newComment := func() consumer { return NewCommentEventConsumer(nil, nil) }
+	newPost := func() consumer { return NewPostEventConsumer(nil, nil, nil, nil) }
+	newVote := func() consumer { return NewVoteEventConsumer(nil, nil, nil) }
+	newCommunity := func() consumer { return NewCommunityEventConsumer(nil, "did:web:coves.social", true, nil) }
+
+	tests := []struct {
+		newConsumer func() consumer
+		mutate      func(r map[string]interface{})
+		name        string
+		collection  string
+		wantReason  string
+	}{
+		{
+			name: "comment without content", newConsumer: newComment, collection: CommentCollection,
+			mutate:     func(r map[string]interface{}) { delete(r, "content") },
+			wantReason: "content: required",
+		},
+		{
+			name: "comment empty after sanitizing", newConsumer: newComment, collection: CommentCollection,
+			mutate:     func(r map[string]interface{}) { r["content"] = "\u202e\x07" },
+			wantReason: "content: required",
+		},
+		{
+			name: "oversized comment", newConsumer: newComment, collection: CommentCollection,
+			mutate:     func(r map[string]interface{}) { r["content"] = strings.Repeat("a", validate.MaxCommentContentBytes+1) },
+			wantReason: "content: too_long",
+		},
+		{
+			name: "comment without createdAt", newConsumer: newComment, collection: CommentCollection,
+			mutate:     func(r map[string]interface{}) { delete(r, "createdAt") },
+			wantReason: "createdAt: required",
+		},
+		{
+			name: "comment root without CID", newConsumer: newComment, collection: CommentCollection,
+			mutate: func(r map[string]interface{}) {
+				r["reply"] = map[string]interface{}{
+					"root":   map[string]interface{}{"uri": "at://did:plc:community/social.coves.community.post/3kpost"},
+					"parent": map[string]interface{}{"uri": "at://did:plc:community/social.coves.community.post/3kpost", "cid": "bafypost"},
+				}
+			},
+			wantReason: "reply.root.cid: required",
+		},
+		{
+			name: "comment parent with a malformed URI", newConsumer: newComment, collection: CommentCollection,
+			mutate: func(r map[string]interface{}) {
+				r["reply"] = map[string]interface{}{
+					"root":   map[string]interface{}{"uri": "at://did:plc:community/social.coves.community.post/3kpost", "cid": "bafypost"},
+					"parent": map[string]interface{}{"uri": "at://did:plc:community", "cid": "bafypost"},
+				}
+			},
+			wantReason: "reply.parent.uri: invalid_uri",
+		},
+		{
+			name: "post without community", newConsumer: newPost, collection: PostCollection,
+			mutate:     func(r map[string]interface{}) { delete(r, "community") },
+			wantReason: "community: required",
+		},
+		{
+			name: "post without author", newConsumer: newPost, collection: PostCollection,
+			mutate:     func(r map[string]interface{}) { delete(r, "author") },
+			wantReason: "author: required",
+		},
+		{
+			name: "post without createdAt", newConsumer: newPost, collection: PostCollection,
+			mutate:     func(r map[string]interface{}) { delete(r, "createdAt") },
+			wantReason: "createdAt: required",
+		},
+		{
+			name: "oversized post title", newConsumer: newPost, collection: PostCollection,
+			mutate:     func(r map[string]interface{}) { r["title"] = strings.Repeat("a", validate.MaxPostTitleBytes+1) },
+			wantReason: "title: too_long",
+		},
+		{
+			name: "oversized post content", newConsumer: newPost, collection: PostCollection,
+			mutate:     func(r map[string]interface{}) { r["content"] = strings.Repeat("a", validate.MaxPostContentBytes+1) },
+			wantReason: "content: too_long",
+		},
+		{
+			name: "vote with an invalid direction", newConsumer: newVote, collection: "social.coves.feed.vote",
+			mutate:     func(r map[string]interface{}) { r["direction"] = "sideways" },
+			wantReason: "direction: invalid_value",
+		},
+		{
+			name: "vote without subject", newConsumer: newVote, collection: "social.coves.feed.vote",
+			mutate:     func(r map[string]interface{}) { delete(r, "subject") },
+			wantReason: "subject: required",
+		},
+		{
+			name: "vote on a non AT-URI", newConsumer: newVote, collection: "social.coves.feed.vote",
+			mutate: func(r map[string]interface{}) {
+				r["subject"] = map[string]interface{}{"uri": "https://example.com/post", "cid": "bafypost"}
+			},
+			wantReason: "subject.uri: invalid_uri",
+		},
+		{
+			name: "profile with a malformed createdAt", newConsumer: newCommunity, collection: "social.coves.community.profile",
+			mutate:     func(r map[string]interface{}) { r["createdAt"] = "January 1st" },
+			wantReason: "createdAt: invalid_format",
+		},
+		{
+			name: "subscription without subject", newConsumer: newCommunity, collection: "social.coves.community.subscription",
+			mutate:     func(r map[string]interface{}) { delete(r, "subject") },
+			wantReason: "subject: required",
+		},
+	}
+
+	for _, tt := range tests {
+		record := validTestRecords()[tt.collection]
+		tt.mutate(record)
+
+		t.Run(tt.name+" with DLQ", func(t *testing.T) {
+			dlq := &mockDeadLetterQueue{}
+			c := tt.newConsumer()
+			c.SetDeadLetterQueue(dlq)
+
+			event := newTestCommitEvent("did:plc:author", tt.collection, "self", record)
+			if err := c.HandleEvent(context.Background(), event); err != nil {
+				t.Fatalf("Expected nil error once dead-lettered, got: %v", err)
+			}
+			if len(dlq.events) != 1 || dlq.events[0] != event {
+				t.Fatalf("Expected the event to be dead-lettered, got %d events", len(dlq.events))
+			}
+			reason := dlq.reasons[0]
+			if !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("Expected reason to contain %q, got %q", tt.wantReason, reason)
+			}
+			if !strings.Contains(reason, fmt.Sprintf("(rules v%d)", validate.RulesVersion)) {
+				t.Errorf("Expected reason to name the rules version, got %q", reason)
+			}
+		})
+
+		t.Run(tt.name+" without DLQ", func(t *testing.T) {
+			event := newTestCommitEvent("did:plc:author", tt.collection, "self", record)
+			err := tt.newConsumer().HandleEvent(context.Background(), event)
+			var recordErr *validate.Error
+			if !errors.As(err, &recordErr) {
+				t.Errorf("Expected a validation error, got: %v", err)
+			}
+		})
+	}
+}
+
+// extraNestedFields simulates fields added by a newer lexicon version
+func extraNestedFields() map[string]interface{} {
+	return map[string]interface{}{
+		"futureFeature": map[string]interface{}{
+			"enabled": true,
+			"options": []interface{}{
+				map[string]interface{}{"kind": "poll", "choices": []interface{}{"a", "b"}},
+			},
+		},
+		"futureScalar": 7,
+	}
+}
+
+func withExtraFields(record map[string]interface{}) map[string]interface{} {
+	for k, v := range extraNestedFields() {
+		record[k] = v
+	}
+	return record
+}
+
+func TestParseRecords_IgnoresUnknownFields(t *testing.T) {
+	t.Run("post", func(t *testing.T) {
+		record := withExtraFields(map[string]interface{}{
+			"$type":     "social.coves.community.post",
+			"community": "did:plc:community",
+			"author":    "did:plc:author",
+			"title":     "Hello",
+			"createdAt": "2025-01-01T00:00:00Z",
+			"embed": map[string]interface{}{
+				"$type":       "social.coves.embed.external",
+				"newEmbedKey": map[string]interface{}{"nested": true},
+			},
+		})
+		post, err := parsePostRecord(record)
+		if err != nil {
+			t.Fatalf("Expected post with extra fields to parse, got: %v", err)
+		}
+		if post.Title == nil || *post.Title != "Hello" {
+			t.Errorf("Expected title to be extracted, got: %v", post.Title)
+		}
+	})
+
+	t.Run("comment", func(t *testing.T) {
+		record := withExtraFields(map[string]interface{}{
+			"$type":   CommentCollection,
+			"content": "Nice",
+			"reply": map[string]interface{}{
+				"root":   map[string]interface{}{"uri": "at://did:plc:c/social.coves.community.post/1", "cid": "bafyroot", "extra": 1},
+				"parent": map[string]interface{}{"uri": "at://did:plc:c/social.coves.community.post/1", "cid": "bafyroot"},
+			},
+			"createdAt": "2025-01-01T00:00:00Z",
+		})
+		comment, err := parseCommentRecord(record)
+		if err != nil {
+			t.Fatalf("Expected comment with extra fields to parse, got: %v", err)
+		}
+		if comment.Reply.Root.CID != "bafyroot" {
+			t.Errorf("Expected root CID to be extracted, got: %s", comment.Reply.Root.CID)
+		}
+	})
+
+	t.Run("vote", func(t *testing.T) {
+		record := withExtraFields(map[string]interface{}{
+			"$type":     "social.coves.feed.vote",
+			"subject":   map[string]interface{}{"uri": "at://did:plc:c/social.coves.community.post/1", "cid": "bafypost", "weight": 2},
+			"direction": "up",
+			"createdAt": "2025-01-01T00:00:00Z",
+		})
+		vote, err := votes.ParseRecord(record)
+		if err != nil {
+			t.Fatalf("Expected vote with extra fields to parse, got: %v", err)
+		}
+		if vote.Direction != "up" {
+			t.Errorf("Expected direction up, got: %s", vote.Direction)
+		}
+	})
+
+	t.Run("community profile", func(t *testing.T) {
+		record := withExtraFields(map[string]interface{}{
+			"$type":      "social.coves.community.profile",
+			"name":       "gaming",
+			"hostedBy":   "did:web:coves.social",
+			"federation": map[string]interface{}{"allowExternalDiscovery": true, "newPolicy": "strict"},
+			"createdAt":  "2025-01-01T00:00:00Z",
+		})
+		profile, err := parseCommunityProfile(record)
+		if err != nil {
+			t.Fatalf("Expected profile with extra fields to parse, got: %v", err)
+		}
+		if !profile.Federation.AllowExternalDiscovery {
+			t.Errorf("Expected federation settings to be extracted")
+		}
+	})
+
+	t.Run("aggregator service", func(t *testing.T) {
+		record := withExtraFields(map[string]interface{}{
+			"$type":       "social.coves.aggregator.service",
+			"did":         "did:plc:aggregator",
+			"displayName": "RSS Bot",
+			"createdAt":   "2025-01-01T00:00:00Z",
+		})
+		service, err := parseAggregatorService(record)
+		if err != nil {
+			t.Fatalf("Expected service with extra fields to parse, got: %v", err)
+		}
+		if service.DisplayName != "RSS Bot" {
+			t.Errorf("Expected displayName to be extracted, got: %s", service.DisplayName)
+		}
+	})
+
+	t.Run("aggregator authorization", func(t *testing.T) {
+		record := withExtraFields(map[string]interface{}{
+			"$type":         "social.coves.aggregator.authorization",
+			"aggregatorDid": "did:plc:aggregator",
+			"communityDid":  "did:plc:community",
+			"createdBy":     "did:plc:mod",
+			"enabled":       true,
+			"createdAt":     "2025-01-01T00:00:00Z",
+		})
+		auth, err := parseAggregatorAuthorization(record)
+		if err != nil {
+			t.Fatalf("Expected authorization with extra fields to parse, got: %v", err)
+		}
+		if !auth.Enabled {
+			t.Errorf("Expected enabled to be extracted")
+		}
+	})
+}
+
+// TestParseRecords_LimitsApplyAfterSanitizing covers text that fits the byte limits as sent but
+// not once NFC normalization expands it (U+0958 decomposes from 3 bytes to 6)
+func TestParseRecords_LimitsApplyAfterSanitizing(t *testing.T) {
+	expanding := func(limit int) string { return strings.Repeat("\u0958", limit/3) }
+
+	t.Run("post title", func(t *testing.T) {
+		_, err := parsePostRecord(map[string]interface{}{"title": expanding(validate.MaxPostTitleBytes)})
+		if err == nil || !strings.Contains(err.Error(), "title exceeds maximum length") {
+			t.Errorf("Expected title limit error, got: %v", err)
+		}
+	})
+
+	t.Run("post content", func(t *testing.T) {
+		_, err := parsePostRecord(map[string]interface{}{"content": expanding(validate.MaxPostContentBytes)})
+		if err == nil || !strings.Contains(err.Error(), "content exceeds maximum length") {
+			t.Errorf("Expected content limit error, got: %v", err)
+		}
+	})
+
+	t.Run("comment content", func(t *testing.T) {
+		_, err := parseCommentRecord(map[string]interface{}{"content": expanding(validate.MaxCommentContentBytes)})
+		if err == nil || !strings.Contains(err.Error(), "content exceeds maximum length") {
+			t.Errorf("Expected content limit error, got: %v", err)
+		}
+	})
+
+	t.Run("normalized text within limits", func(t *testing.T) {
+		post, err := parsePostRecord(map[string]interface{}{"title": expanding(validate.MaxPostTitleBytes / 2)})
+		if err != nil {
+			t.Fatalf("Expected title within limit to parse, got: %v", err)
+		}
+		if len(*post.Title) != validate.MaxPostTitleBytes {
+			t.Errorf("Expected normalized title of %d bytes, got %d", validate.MaxPostTitleBytes, len(*post.Title))
+		}
+	})
+}
+
+func TestMarshalRawRecord_PreservesUnknownFields(t *testing.T) {
+	record := withExtraFields(map[string]interface{}{
+		"$type":   CommentCollection,
+		"content": "hello",
+	})
+
+	raw := marshalRawRecord(record)
+	if raw == nil {
+		t.Fatal("Expected raw record JSON, got nil")
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal([]byte(*raw), &decoded); err != nil {
+		t.Fatalf("Expected valid JSON, got: %v", err)
+	}
+
+	feature, ok := decoded["futureFeature"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Expected futureFeature to be preserved, got: %v", decoded["futureFeature"])
+	}
+	if feature["enabled"] != true {
+		t.Errorf("Expected nested unknown field to be preserved, got: %v", feature["enabled"])
+	}
+
+	if marshalRawRecord(nil) != nil {
+		t.Error("Expected nil raw record for nil input")
+	}
+}
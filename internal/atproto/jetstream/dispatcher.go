@@ -0,0 +1,319 @@
+package jetstream
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// DefaultDispatcherWorkers is the number of workers per registered consumer
+	DefaultDispatcherWorkers = 4
+
+	// DefaultDispatcherQueueSize bounds each worker's queue; a full queue stalls the read loop
+	DefaultDispatcherQueueSize = 256
+)
+
+// Non-commit event kinds a consumer can register for instead of a collection
+// Jetstream sends these regardless of wantedCollections
+const (
+	EventKindIdentity = "identity"
+	EventKindAccount  = "account"
+)
+
+// EventHandler processes a single Jetstream event
+// Implemented by the record consumers (CommunityEventConsumer, PostEventConsumer, ...)
+type EventHandler interface {
+	HandleEvent(ctx context.Context, event *JetstreamEvent) error
+}
+
+// consumerPool runs one consumer's events on a fixed set of workers
+// Events are assigned to workers by repo DID, so events from the same repo are handled in
+// order while different repos are handled concurrently
+type consumerPool struct {
+	handler EventHandler
+	name    string
+	queues  []chan *JetstreamEvent
+}
+
+// JetstreamDispatcher reads one Jetstream connection subscribed to every registered
+// collection and routes events to their consumers
+// Replaces one connection per consumer, so reconnects and the replay cursor are shared
+type JetstreamDispatcher struct {
+	routes    map[string]*consumerPool // collection or event kind -> pool
+	gaps      *gapDetector             // Optional - records windows a reconnect couldn't replay
+	baseURL   string
+	pools     []*consumerPool
+	workers   int
+	queueSize int
+	cursor    int64 // time_us of the last dispatched event, used to resume after a reconnect
+}
+
+// NewJetstreamDispatcher creates a dispatcher for the Jetstream subscribe endpoint at baseURL
+// (e.g. "ws://localhost:6008/subscribe"); wantedCollections are added from the registrations
+// workers and queueSize apply to each registered consumer (values below 1 use the defaults)
+func NewJetstreamDispatcher(baseURL string, workers, queueSize int) *JetstreamDispatcher {
+	if workers < 1 {
+		workers = DefaultDispatcherWorkers
+	}
+	if queueSize < 1 {
+		queueSize = DefaultDispatcherQueueSize
+	}
+	return &JetstreamDispatcher{
+		routes:    make(map[string]*consumerPool),
+		baseURL:   baseURL,
+		workers:   workers,
+		queueSize: queueSize,
+	}
+}
+
+// SetGapRecorder enables gap detection and saves the dispatcher's position, so a restart resumes
+// from it. A reconnect whose cursor is older than replayWindow and whose first event is more
+// than threshold past it is recorded as a window the consumers never saw.
+// Must be called before Start
+func (d *JetstreamDispatcher) SetGapRecorder(recorder GapRecorder, threshold, replayWindow time.Duration) {
+	d.gaps = newGapDetector(recorder, "jetstream", threshold, replayWindow)
+}
+
+// Register routes events to handler
+// Each route is a collection NSID (commit events) or EventKindIdentity/EventKindAccount
+// Must be called before Start; a route can only belong to one consumer
+func (d *JetstreamDispatcher) Register(name string, handler EventHandler, routes ...string) error {
+	if len(routes) == 0 {
+		return fmt.Errorf("consumer %s has no routes", name)
+	}
+	for _, route := range routes {
+		if existing, ok := d.routes[route]; ok {
+			return fmt.Errorf("route %s is already registered to consumer %s", route, existing.name)
+		}
+	}
+
+	pool := &consumerPool{
+		handler: handler,
+		name:    name,
+		queues:  make([]chan *JetstreamEvent, d.workers),
+	}
+	for i := range pool.queues {
+		pool.queues[i] = make(chan *JetstreamEvent, d.queueSize)
+	}
+	for _, route := range routes {
+		d.routes[route] = pool
+	}
+	d.pools = append(d.pools, pool)
+	return nil
+}
+
+// URL returns the subscribe URL for the union of registered collections
+// Includes the replay cursor once events have been dispatched
+func (d *JetstreamDispatcher) URL() (string, error) {
+	collections := make([]string, 0, len(d.routes))
+	for route := range d.routes {
+		if route != EventKindIdentity && route != EventKindAccount {
+			collections = append(collections, route)
+		}
+	}
+	sort.Strings(collections)
+	return subscribeURL(d.baseURL, collections, d.cursor)
+}
+
+// Start begins consuming events from Jetstream
+// Runs indefinitely, reconnecting on errors; returns after the workers have stopped
+func (d *JetstreamDispatcher) Start(ctx context.Context) error {
+	if cursor := d.gaps.load(ctx); cursor > d.cursor {
+		d.cursor = cursor
+	}
+	wsURL, err := d.URL()
+	if err != nil {
+		return err
+	}
+	log.Printf("Starting Jetstream dispatcher: %s (%d consumers)", wsURL, len(d.pools))
+
+	var wg sync.WaitGroup
+	d.startWorkers(ctx, &wg)
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Println("Jetstream dispatcher shutting down")
+			return ctx.Err()
+		default:
+			err := d.connect(ctx)
+			d.gaps.disconnected(ctx)
+			if err != nil {
+				log.Printf("Jetstream dispatcher connection error: %v. Retrying in 5s...", err)
+				select {
+				case <-ctx.Done():
+				case <-time.After(5 * time.Second):
+				}
+				continue
+			}
+		}
+	}
+}
+
+// startWorkers starts every consumer's workers; they stop when ctx is cancelled
+func (d *JetstreamDispatcher) startWorkers(ctx context.Context, wg *sync.WaitGroup) {
+	for _, pool := range d.pools {
+		for _, queue := range pool.queues {
+			wg.Add(1)
+			go func(pool *consumerPool, queue chan *JetstreamEvent) {
+				defer wg.Done()
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case event := <-queue:
+						if err := pool.handler.HandleEvent(ctx, event); err != nil {
+							log.Printf("Failed to handle %s event: %v", pool.name, err)
+							// Continue processing other events even if one fails
+						}
+					}
+				}
+			}(pool, queue)
+		}
+	}
+}
+
+// connect establishes the WebSocket connection and dispatches events until it fails
+func (d *JetstreamDispatcher) connect(ctx context.Context) error {
+	wsURL, err := d.URL()
+	if err != nil {
+		return err
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to Jetstream: %w", err)
+	}
+	defer func() {
+		if closeErr := conn.Close(); closeErr != nil {
+			log.Printf("Failed to close WebSocket connection: %v", closeErr)
+		}
+	}()
+
+	log.Println("Connected to Jetstream (dispatcher)")
+	d.gaps.reconnected(ctx, d.cursor)
+
+	// Set read deadline to detect connection issues
+	if err := conn.SetReadDeadline(time.Now().Add(60 * time.Second)); err != nil {
+		log.Printf("Failed to set read deadline: %v", err)
+	}
+
+	// Set pong handler to keep connection alive
+	// Pongs are handled on the read loop's goroutine, so they can mark the detector alive
+	conn.SetPongHandler(func(string) error {
+		if err := conn.SetReadDeadline(time.Now().Add(60 * time.Second)); err != nil {
+			log.Printf("Failed to set read deadline in pong handler: %v", err)
+		}
+		d.gaps.alive(ctx)
+		return nil
+	})
+
+	// Start ping ticker
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	done := make(chan struct{})
+	var closeOnce sync.Once // Ensure done channel is only closed once
+
+	// Ping goroutine
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(10*time.Second)); err != nil {
+					log.Printf("Failed to send ping: %v", err)
+					closeOnce.Do(func() { close(done) })
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+	defer closeOnce.Do(func() { close(done) })
+
+	// Unblock readEvent on shutdown
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = conn.Close()
+		case <-done:
+		}
+	}()
+
+	// Read loop
+	for {
+		select {
+		case <-done:
+			return fmt.Errorf("connection closed by ping failure")
+		default:
+		}
+
+		event, err := readEvent(conn)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("read error: %w", err)
+		}
+
+		// Reset read deadline on successful read
+		if err := conn.SetReadDeadline(time.Now().Add(60 * time.Second)); err != nil {
+			log.Printf("Failed to set read deadline: %v", err)
+		}
+
+		if event == nil {
+			// Oversized or malformed message, already logged
+			continue
+		}
+		d.gaps.observe(ctx, event.TimeUS)
+
+		if err := d.dispatch(ctx, event); err != nil {
+			return err
+		}
+	}
+}
+
+// dispatch queues an event on its consumer's worker for the event's repo DID
+// Blocks while that worker's queue is full, so a slow consumer applies backpressure to the
+// connection instead of events piling up in memory; a stall past the read deadline drops the
+// connection, which resumes from the cursor
+func (d *JetstreamDispatcher) dispatch(ctx context.Context, event *JetstreamEvent) error {
+	route := event.Kind
+	if event.Kind == "commit" {
+		if event.Commit == nil {
+			return nil
+		}
+		route = event.Commit.Collection
+	}
+
+	pool, ok := d.routes[route]
+	if ok {
+		queue := pool.queues[workerIndex(event.Did, len(pool.queues))]
+		select {
+		case queue <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if event.TimeUS > d.cursor {
+		d.cursor = event.TimeUS
+	}
+	return nil
+}
+
+// workerIndex maps a repo DID to one of n workers
+func workerIndex(did string, n int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(did))
+	return int(h.Sum32() % uint32(n))
+}
@@ -0,0 +1,293 @@
+package jetstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// recordingHandler records handled events; when release is set, each event waits on it
+type recordingHandler struct {
+	release chan struct{}
+	events  []*JetstreamEvent
+	mu      sync.Mutex
+}
+
+func (h *recordingHandler) HandleEvent(ctx context.Context, event *JetstreamEvent) error {
+	if h.release != nil {
+		select {
+		case <-h.release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+	return nil
+}
+
+func (h *recordingHandler) handled() []*JetstreamEvent {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]*JetstreamEvent(nil), h.events...)
+}
+
+// waitForEvents polls until handler has handled n events or fails the test
+func waitForEvents(t *testing.T, handler *recordingHandler, n int) []*JetstreamEvent {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if events := handler.handled(); len(events) >= n {
+			return events
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Timed out waiting for %d events, got %d", n, len(handler.handled()))
+	return nil
+}
+
+// newFakeJetstream serves events to every connection and records the subscribe queries
+func newFakeJetstream(t *testing.T, events []*JetstreamEvent) (*httptest.Server, chan url.Values) {
+	t.Helper()
+	queries := make(chan url.Values, 10)
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+
+		for _, event := range events {
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+		// Hold the connection open until the client goes away
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, queries
+}
+
+func wsURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/subscribe"
+}
+
+func TestJetstreamDispatcher_RoutesByCollection(t *testing.T) {
+	identity := &JetstreamEvent{Did: "did:plc:user", Kind: EventKindIdentity, TimeUS: 3, Identity: &IdentityEvent{Did: "did:plc:user", Handle: "user.test"}}
+	events := []*JetstreamEvent{
+		newTestCommitEvent("did:plc:community", "social.coves.community.post", "p1", nil),
+		newTestCommitEvent("did:plc:user", "social.coves.feed.vote", "v1", nil),
+		identity,
+		newTestCommitEvent("did:plc:user", "app.bsky.feed.post", "ignored", nil),
+		newTestCommitEvent("did:plc:user", "social.coves.feed.vote", "v2", nil),
+	}
+	server, queries := newFakeJetstream(t, events)
+
+	posts, votes, users := &recordingHandler{}, &recordingHandler{}, &recordingHandler{}
+	dispatcher := NewJetstreamDispatcher(wsURL(server), 2, 8)
+	if err := dispatcher.Register("post", posts, "social.coves.community.post"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.Register("vote", votes, "social.coves.feed.vote"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.Register("user", users, "social.coves.actor.profile", EventKindIdentity, EventKindAccount); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error, 1)
+	go func() { stopped <- dispatcher.Start(ctx) }()
+
+	query := <-queries
+	wanted := query["wantedCollections"]
+	expected := []string{"social.coves.actor.profile", "social.coves.community.post", "social.coves.feed.vote"}
+	if strings.Join(wanted, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected one subscription to %v, got %v", expected, wanted)
+	}
+
+	if got := waitForEvents(t, posts, 1); got[0].Commit.RKey != "p1" {
+		t.Errorf("Expected the post event, got %+v", got[0].Commit)
+	}
+	if got := waitForEvents(t, votes, 2); len(got) != 2 {
+		t.Errorf("Expected two vote events, got %d", len(got))
+	}
+	if got := waitForEvents(t, users, 1); got[0].Kind != EventKindIdentity {
+		t.Errorf("Expected the identity event, got %s", got[0].Kind)
+	}
+
+	cancel()
+	select {
+	case err := <-stopped:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled on shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Dispatcher did not stop after cancellation")
+	}
+}
+
+func TestJetstreamDispatcher_PreservesOrderPerDID(t *testing.T) {
+	const perDID = 50
+	dids := []string{"did:plc:alice", "did:plc:bob", "did:plc:carol", "did:plc:dave"}
+
+	var events []*JetstreamEvent
+	for i := 0; i < perDID; i++ {
+		for _, did := range dids {
+			events = append(events, newTestCommitEvent(did, "social.coves.feed.vote", fmt.Sprintf("%03d", i), nil))
+		}
+	}
+	server, _ := newFakeJetstream(t, events)
+
+	votes := &recordingHandler{}
+	dispatcher := NewJetstreamDispatcher(wsURL(server), 3, 4)
+	if err := dispatcher.Register("vote", votes, "social.coves.feed.vote"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = dispatcher.Start(ctx) }()
+
+	handled := waitForEvents(t, votes, len(events))
+	next := make(map[string]int)
+	for _, event := range handled {
+		want := fmt.Sprintf("%03d", next[event.Did])
+		if event.Commit.RKey != want {
+			t.Fatalf("Events for %s handled out of order: got %s, want %s", event.Did, event.Commit.RKey, want)
+		}
+		next[event.Did]++
+	}
+}
+
+func TestJetstreamDispatcher_Backpressure(t *testing.T) {
+	slow := &recordingHandler{release: make(chan struct{})}
+	fast := &recordingHandler{}
+	dispatcher := NewJetstreamDispatcher("ws://localhost:6008/subscribe", 1, 1)
+	if err := dispatcher.Register("comment", slow, "social.coves.community.comment"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.Register("vote", fast, "social.coves.feed.vote"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	dispatcher.startWorkers(ctx, &wg)
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	comment := func(rkey string) *JetstreamEvent {
+		return newTestCommitEvent("did:plc:user", "social.coves.community.comment", rkey, nil)
+	}
+
+	// The slow worker holds the first comment and its queue holds the second
+	if err := dispatcher.dispatch(ctx, comment("c1")); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.dispatch(ctx, newTestCommitEvent("did:plc:user", "social.coves.feed.vote", "v1", nil)); err != nil {
+		t.Fatal(err)
+	}
+	queue := dispatcher.routes["social.coves.community.comment"].queues[0]
+	for deadline := time.Now().Add(5 * time.Second); len(queue) > 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if err := dispatcher.dispatch(ctx, comment("c2")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The third comment can't be queued until the slow consumer catches up
+	dispatched := make(chan error, 1)
+	go func() { dispatched <- dispatcher.dispatch(ctx, comment("c3")) }()
+	select {
+	case err := <-dispatched:
+		t.Fatalf("Expected dispatch to block on a full queue, returned %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// Other consumers keep handling what was already dispatched to them
+	waitForEvents(t, fast, 1)
+
+	close(slow.release)
+	select {
+	case err := <-dispatched:
+		if err != nil {
+			t.Fatalf("Expected dispatch to succeed once the queue drained, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Dispatch stayed blocked after the slow consumer caught up")
+	}
+
+	handled := waitForEvents(t, slow, 3)
+	for i, event := range handled {
+		if want := fmt.Sprintf("c%d", i+1); event.Commit.RKey != want {
+			t.Errorf("Expected %s at position %d, got %s", want, i, event.Commit.RKey)
+		}
+	}
+}
+
+func TestJetstreamDispatcher_BlockedDispatchStopsOnCancel(t *testing.T) {
+	dispatcher := NewJetstreamDispatcher("ws://localhost:6008/subscribe", 1, 1)
+	if err := dispatcher.Register("comment", &recordingHandler{}, "social.coves.community.comment"); err != nil {
+		t.Fatal(err)
+	}
+
+	// No workers are running, so the second event can't be queued
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := dispatcher.dispatch(ctx, newTestCommitEvent("did:plc:user", "social.coves.community.comment", "c1", nil)); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+	err := dispatcher.dispatch(ctx, newTestCommitEvent("did:plc:user", "social.coves.community.comment", "c2", nil))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestJetstreamDispatcher_Register(t *testing.T) {
+	dispatcher := NewJetstreamDispatcher("ws://localhost:6008/subscribe?wantedCollections=stale", 0, 0)
+	if err := dispatcher.Register("post", &recordingHandler{}, "social.coves.community.post"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dispatcher.Register("other", &recordingHandler{}, "social.coves.community.post"); err == nil {
+		t.Error("Expected a route registered twice to be rejected")
+	}
+	if err := dispatcher.Register("empty", &recordingHandler{}); err == nil {
+		t.Error("Expected a consumer without routes to be rejected")
+	}
+
+	// Resuming after events were dispatched replays from the last one
+	event := newTestCommitEvent("did:plc:community", "social.coves.community.post", "p1", nil)
+	event.TimeUS = 1700000000000000
+	if err := dispatcher.dispatch(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+	got, err := dispatcher.URL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ws://localhost:6008/subscribe?cursor=1700000000000000&wantedCollections=social.coves.community.post"
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
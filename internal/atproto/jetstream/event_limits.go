@@ -0,0 +1,111 @@
+package jetstream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync/atomic"
+
+	"github.com/gorilla/websocket"
+)
+
+// DefaultMaxMessageBytes is the largest Jetstream message read off the wire by default
+const DefaultMaxMessageBytes = 1 << 20
+
+// ErrOversizedRecord is returned when a record is larger than its collection allows
+// Its message doubles as the dead-letter reason prefix.
+var ErrOversizedRecord = errors.New("OversizedRecord")
+
+// recordSizeLimits caps the encoded size of records per collection
+// Lexicon limits keep honest records far below these; anything larger is junk or abuse.
+var recordSizeLimits = map[string]int{
+	PostCollection:         200 << 10,
+	CommentCollection:      50 << 10,
+	CovesProfileCollection: 100 << 10,
+}
+
+// minRecordSizeLimit is the smallest cap; messages below it can't hold an oversized record
+const minRecordSizeLimit = 50 << 10
+
+var maxMessageBytes atomic.Int64
+
+func init() {
+	maxMessageBytes.Store(DefaultMaxMessageBytes)
+}
+
+// SetMaxMessageBytes sets the per-message read limit for all Jetstream connections
+// Non-positive values restore DefaultMaxMessageBytes.
+func SetMaxMessageBytes(n int64) {
+	if n <= 0 {
+		n = DefaultMaxMessageBytes
+	}
+	maxMessageBytes.Store(n)
+}
+
+// readEvent reads and parses the next Jetstream message from conn
+// Messages over the read limit are drained and malformed JSON is logged; both are skipped
+// with a nil event so one bad message doesn't drop the connection. Only errors reading from
+// the connection itself are returned.
+func readEvent(conn *websocket.Conn) (*JetstreamEvent, error) {
+	_, reader, err := conn.NextReader()
+	if err != nil {
+		return nil, err
+	}
+
+	limit := maxMessageBytes.Load()
+	message, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(message)) > limit {
+		skipped, err := io.Copy(io.Discard, reader)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Skipping oversized Jetstream message: %d bytes exceeds the %d byte limit",
+			int64(len(message))+skipped, limit)
+		return nil, nil
+	}
+
+	var event JetstreamEvent
+	if err := json.Unmarshal(message, &event); err != nil {
+		log.Printf("Skipping malformed Jetstream message: %v", err)
+		return nil, nil
+	}
+
+	if event.Commit != nil && event.Commit.Record != nil && len(message) > minRecordSizeLimit {
+		event.Commit.RecordSize = rawRecordSize(message)
+	}
+
+	return &event, nil
+}
+
+// rawRecordSize returns the encoded size of the commit record in a Jetstream message
+func rawRecordSize(message []byte) int {
+	var raw struct {
+		Commit struct {
+			Record json.RawMessage `json:"record"`
+		} `json:"commit"`
+	}
+	if err := json.Unmarshal(message, &raw); err != nil {
+		return 0
+	}
+	return len(raw.Commit.Record)
+}
+
+// checkRecordSize rejects records larger than their collection's cap
+// Only events read off a connection carry a size; others aren't checked.
+func checkRecordSize(commit *CommitEvent) error {
+	if commit == nil {
+		return nil
+	}
+
+	limit, ok := recordSizeLimits[commit.Collection]
+	if !ok || commit.RecordSize <= limit {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s record is %d bytes, limit is %d", ErrOversizedRecord, commit.Collection, commit.RecordSize, limit)
+}
@@ -0,0 +1,169 @@
+package jetstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+)
+
+// newRawFakeJetstream serves raw text messages to every connection and counts connections
+func newRawFakeJetstream(t *testing.T, messages [][]byte) (*httptest.Server, chan struct{}) {
+	t.Helper()
+	connections := make(chan struct{}, 10)
+	upgrader := websocket.Upgrader{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		connections <- struct{}{}
+		defer func() { _ = conn.Close() }()
+
+		for _, message := range messages {
+			if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+				return
+			}
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, connections
+}
+
+func mustMarshalJetstreamEvent(t *testing.T, event *JetstreamEvent) []byte {
+	t.Helper()
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// startTestDispatcher routes post events from the fake server to a recording handler
+func startTestDispatcher(t *testing.T, server *httptest.Server) *recordingHandler {
+	t.Helper()
+	posts := &recordingHandler{}
+	dispatcher := NewJetstreamDispatcher(wsURL(server), 1, 8)
+	if err := dispatcher.Register("post", posts, PostCollection); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = dispatcher.Start(ctx) }()
+	return posts
+}
+
+func TestReadEvent_SkipsOversizedAndMalformedMessages(t *testing.T) {
+	SetMaxMessageBytes(4096)
+	t.Cleanup(func() { SetMaxMessageBytes(DefaultMaxMessageBytes) })
+
+	messages := [][]byte{
+		mustMarshalJetstreamEvent(t, newTestCommitEvent("did:plc:community", PostCollection, "p1", nil)),
+		[]byte(`{"did":"did:plc:community","kind":"commit","padding":"` + strings.Repeat("x", 8192) + `"}`),
+		[]byte(`{"did":"did:plc:community","kind":"commit","commit":{`),
+		[]byte(`not json at all`),
+		mustMarshalJetstreamEvent(t, newTestCommitEvent("did:plc:community", PostCollection, "p2", nil)),
+	}
+	server, connections := newRawFakeJetstream(t, messages)
+	posts := startTestDispatcher(t, server)
+
+	got := waitForEvents(t, posts, 2)
+	if got[0].Commit.RKey != "p1" || got[1].Commit.RKey != "p2" {
+		t.Errorf("Expected p1 and p2 to be handled, got %s and %s", got[0].Commit.RKey, got[1].Commit.RKey)
+	}
+	if n := len(connections); n != 1 {
+		t.Errorf("Expected the connection to survive bad messages, got %d connections", n)
+	}
+}
+
+func TestReadEvent_DeadLettersOversizedRecords(t *testing.T) {
+	oversized := newTestCommitEvent("did:plc:community", PostCollection, "huge", map[string]interface{}{
+		"$type":   PostCollection,
+		"content": strings.Repeat("a", 250<<10),
+	})
+	small := newTestCommitEvent("did:plc:community", PostCollection, "small", map[string]interface{}{
+		"$type":   PostCollection,
+		"content": "fits",
+	})
+	server, connections := newRawFakeJetstream(t, [][]byte{
+		mustMarshalJetstreamEvent(t, oversized),
+		mustMarshalJetstreamEvent(t, small),
+	})
+	posts := startTestDispatcher(t, server)
+
+	got := waitForEvents(t, posts, 2)
+	if got[0].Commit.RecordSize <= recordSizeLimits[PostCollection] {
+		t.Fatalf("Expected the oversized record's size to be measured, got %d", got[0].Commit.RecordSize)
+	}
+	if got[1].Commit.RecordSize != 0 {
+		t.Errorf("Expected small messages not to be measured, got %d", got[1].Commit.RecordSize)
+	}
+	if n := len(connections); n != 1 {
+		t.Errorf("Expected a single connection, got %d", n)
+	}
+
+	dlq := &mockDeadLetterQueue{}
+	c := NewPostEventConsumer(nil, nil, nil, nil)
+	c.SetDeadLetterQueue(dlq)
+	if err := c.HandleEvent(context.Background(), got[0]); err != nil {
+		t.Fatalf("Expected nil error once dead-lettered, got: %v", err)
+	}
+	if len(dlq.reasons) != 1 || !strings.HasPrefix(dlq.reasons[0], "OversizedRecord") {
+		t.Errorf("Expected one OversizedRecord dead letter, got %v", dlq.reasons)
+	}
+}
+
+func TestCheckRecordSize(t *testing.T) {
+	tests := []struct {
+		name       string
+		collection string
+		size       int
+		wantErr    bool
+	}{
+		{name: "post under cap", collection: PostCollection, size: 200 << 10},
+		{name: "post over cap", collection: PostCollection, size: 200<<10 + 1, wantErr: true},
+		{name: "comment over cap", collection: CommentCollection, size: 50<<10 + 1, wantErr: true},
+		{name: "profile over cap", collection: CovesProfileCollection, size: 100<<10 + 1, wantErr: true},
+		{name: "uncapped collection", collection: "social.coves.feed.vote", size: 500 << 10},
+		{name: "unmeasured", collection: PostCollection},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkRecordSize(&CommitEvent{Collection: tt.collection, RecordSize: tt.size})
+			if tt.wantErr != errors.Is(err, ErrOversizedRecord) {
+				t.Errorf("checkRecordSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserConsumer_DeadLettersOversizedProfiles(t *testing.T) {
+	dlq := &mockDeadLetterQueue{}
+	consumer := NewUserEventConsumer(newMockUserService(), nil, "", "", WithDeadLetterQueue(dlq))
+
+	event := newTestCommitEvent("did:plc:user", CovesProfileCollection, "self", map[string]interface{}{
+		"$type":       CovesProfileCollection,
+		"displayName": "Too big",
+	})
+	event.Commit.RecordSize = 100<<10 + 1
+
+	if err := consumer.HandleEvent(context.Background(), event); err != nil {
+		t.Fatalf("Expected nil error once dead-lettered, got: %v", err)
+	}
+	if len(dlq.events) != 1 {
+		t.Fatalf("Expected 1 dead-lettered event, got %d", len(dlq.events))
+	}
+}
@@ -0,0 +1,157 @@
+package jetstream
+
+import (
+	"Coves/internal/core/consumergaps"
+	"context"
+	"log"
+	"time"
+)
+
+const (
+	// positionSaveInterval is how often a detector saves its position while events or pongs arrive
+	positionSaveInterval = 5 * time.Second
+
+	// resumeRewind is how far before the saved position a restart replays from, so events still
+	// queued for workers when the last process stopped are delivered again. Redelivered events
+	// are skipped by the replay guard.
+	resumeRewind = 30 * time.Second
+)
+
+// GapRecorder stores windows of events a connection never saw, and each consumer's position so
+// a restart can tell what it missed
+// Implemented by consumergaps.Repository
+type GapRecorder interface {
+	RecordGap(ctx context.Context, consumer string, startTimeUS, endTimeUS int64) error
+	SavePosition(ctx context.Context, consumer string, position consumergaps.Position) error
+	LoadPosition(ctx context.Context, consumer string) (consumergaps.Position, error)
+}
+
+// gapDetector notices when a connection resumes past a window of events it never saw
+// A connection that replays from a cursor misses events only when the cursor is older than
+// Jetstream's replay window; then the first event arrives more than threshold past the cursor
+// and the window in between is recorded. A connection without a cursor misses everything
+// while it's down, so the time between when the last connection was known to be up and when
+// the new one opened is recorded once it's longer than threshold. Quiet periods on a live
+// connection are never gaps. The position is saved as events and pongs arrive, so a restart
+// of the AppView resumes from the last one and is checked like a reconnect.
+// Not safe for concurrent use; each connection loop owns its detector.
+type gapDetector struct {
+	connectedAt  time.Time // last time a connection was known to be up, by this process or the last one
+	savedAt      time.Time
+	recorder     GapRecorder
+	now          func() time.Time
+	consumer     string
+	threshold    time.Duration
+	replayWindow time.Duration
+	lastSeen     int64 // time_us of the newest event seen, by this process or the last one
+	cursor       int64 // cursor the current connection replays from; 0 for none
+	resuming     bool  // the current connection replays from a cursor and hasn't seen an event yet
+}
+
+// newGapDetector returns a detector recording gaps longer than threshold under consumer
+// replayWindow is how far back Jetstream can replay; it only matters to connections with a
+// cursor. A nil recorder disables detection.
+func newGapDetector(recorder GapRecorder, consumer string, threshold, replayWindow time.Duration) *gapDetector {
+	return &gapDetector{
+		recorder:     recorder,
+		consumer:     consumer,
+		threshold:    threshold,
+		replayWindow: replayWindow,
+		now:          time.Now,
+	}
+}
+
+// load restores the position saved by the last process and returns the cursor to resume from,
+// or 0 if there's none
+func (g *gapDetector) load(ctx context.Context) int64 {
+	if g == nil || g.recorder == nil {
+		return 0
+	}
+	position, err := g.recorder.LoadPosition(ctx, g.consumer)
+	if err != nil {
+		log.Printf("Failed to load %s consumer position: %v", g.consumer, err)
+		return 0
+	}
+	g.connectedAt = position.ConnectedAt
+	if position.LastSeenUS <= 0 {
+		return 0
+	}
+	g.lastSeen = position.LastSeenUS
+	return max(position.LastSeenUS-resumeRewind.Microseconds(), 1)
+}
+
+// reconnected marks the start of a new connection replaying from cursor (0 for none)
+// Without a cursor the time the consumer was down is checked now; with one, the first event is.
+func (g *gapDetector) reconnected(ctx context.Context, cursor int64) {
+	if g == nil || g.recorder == nil {
+		return
+	}
+	now := g.now()
+	g.cursor = cursor
+	g.resuming = cursor > 0
+	if cursor <= 0 && !g.connectedAt.IsZero() && now.Sub(g.connectedAt) > g.threshold {
+		log.Printf("Jetstream %s consumer missed events: reconnected %s after the connection was last up",
+			g.consumer, now.Sub(g.connectedAt).Round(time.Second))
+		g.record(ctx, g.connectedAt.UnixMicro(), now.UnixMicro())
+	}
+	g.connectedAt = now
+}
+
+// observe records a gap if timeUS is the first event replayed from a cursor Jetstream no
+// longer had, then advances the position
+func (g *gapDetector) observe(ctx context.Context, timeUS int64) {
+	if g == nil || g.recorder == nil || timeUS <= 0 {
+		return
+	}
+	now := g.now()
+	if g.resuming {
+		g.resuming = false
+		cursorAge := now.Sub(time.UnixMicro(g.cursor))
+		if cursorAge > g.replayWindow && timeUS-g.cursor > g.threshold.Microseconds() {
+			log.Printf("Jetstream %s consumer missed events: replay from a %s old cursor resumed %s past it",
+				g.consumer, cursorAge.Round(time.Second), time.Duration(timeUS-g.cursor)*time.Microsecond)
+			g.record(ctx, g.cursor, timeUS)
+		}
+	}
+	if timeUS > g.lastSeen {
+		g.lastSeen = timeUS
+	}
+	g.alive(ctx)
+}
+
+// alive notes that the connection is up, saving the position every positionSaveInterval
+func (g *gapDetector) alive(ctx context.Context) {
+	if g == nil || g.recorder == nil {
+		return
+	}
+	g.connectedAt = g.now()
+	if g.connectedAt.Sub(g.savedAt) >= positionSaveInterval {
+		g.save(ctx)
+	}
+}
+
+// disconnected saves the position when a connection ends
+func (g *gapDetector) disconnected(ctx context.Context) {
+	if g == nil || g.recorder == nil {
+		return
+	}
+	g.save(ctx)
+}
+
+func (g *gapDetector) save(ctx context.Context) {
+	if g.lastSeen <= 0 && g.connectedAt.IsZero() {
+		return
+	}
+	g.savedAt = g.now()
+	position := consumergaps.Position{LastSeenUS: g.lastSeen, ConnectedAt: g.connectedAt}
+	// The connection's context may already be cancelled on shutdown; the save should still land
+	if err := g.recorder.SavePosition(context.WithoutCancel(ctx), g.consumer, position); err != nil {
+		log.Printf("Failed to save %s consumer position: %v", g.consumer, err)
+	}
+}
+
+func (g *gapDetector) record(ctx context.Context, startTimeUS, endTimeUS int64) {
+	if err := g.recorder.RecordGap(ctx, g.consumer, startTimeUS, endTimeUS); err != nil {
+		log.Printf("Failed to record %s consumer gap: %v", g.consumer, err)
+	}
+}
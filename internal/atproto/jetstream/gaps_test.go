@@ -0,0 +1,350 @@
+package jetstream
+
+import (
+	"Coves/internal/core/consumergaps"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type recordedGap struct {
+	consumer   string
+	start, end int64
+}
+
+// recordingGapRecorder records the gaps it's given and keeps positions in memory
+type recordingGapRecorder struct {
+	positions map[string]consumergaps.Position
+	gaps      []recordedGap
+	saves     int
+	mu        sync.Mutex
+}
+
+func (r *recordingGapRecorder) RecordGap(ctx context.Context, consumer string, startTimeUS, endTimeUS int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gaps = append(r.gaps, recordedGap{consumer: consumer, start: startTimeUS, end: endTimeUS})
+	return nil
+}
+
+func (r *recordingGapRecorder) SavePosition(ctx context.Context, consumer string, position consumergaps.Position) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.positions == nil {
+		r.positions = make(map[string]consumergaps.Position)
+	}
+	r.positions[consumer] = position
+	r.saves++
+	return nil
+}
+
+func (r *recordingGapRecorder) LoadPosition(ctx context.Context, consumer string) (consumergaps.Position, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.positions[consumer], nil
+}
+
+func (r *recordingGapRecorder) recorded() []recordedGap {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]recordedGap(nil), r.gaps...)
+}
+
+// newClosingFakeJetstream serves the nth connection the nth batch of events, then closes it
+func newClosingFakeJetstream(t *testing.T, batches [][]*JetstreamEvent) *httptest.Server {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	var mu sync.Mutex
+	connection := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		batch := connection
+		connection++
+		mu.Unlock()
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		if batch >= len(batches) {
+			return
+		}
+		for _, event := range batches[batch] {
+			if err := conn.WriteJSON(event); err != nil {
+				return
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func eventAt(rkey string, timeUS int64) *JetstreamEvent {
+	event := newTestCommitEvent("did:plc:community", "social.coves.community.post", rkey, nil)
+	event.TimeUS = timeUS
+	return event
+}
+
+// fakeClock is a settable clock for gap detectors
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+// gapStep is one thing a connection loop tells its detector
+type gapStep struct {
+	at      time.Duration // clock time relative to the test's start
+	kind    string        // "connect", "event", "pong" or "disconnect"
+	cursor  time.Duration // replay cursor for "connect", relative to start; 0 for none
+	eventAt time.Duration // event time for "event", relative to start
+}
+
+func TestGapDetector(t *testing.T) {
+	start := time.Now().Truncate(time.Second)
+	us := func(d time.Duration) int64 { return start.Add(d).UnixMicro() }
+
+	tests := []struct {
+		name  string
+		want  []recordedGap
+		steps []gapStep
+	}{
+		{
+			name:  "first connection is never a gap",
+			steps: []gapStep{{kind: "connect"}, {kind: "event"}},
+		},
+		{
+			name: "quiet period on a live connection",
+			steps: []gapStep{
+				{kind: "connect"}, {kind: "event"},
+				{at: 10 * time.Minute, kind: "event", eventAt: 10 * time.Minute},
+			},
+		},
+		{
+			name: "replay from a recent cursor resumes after a quiet period",
+			steps: []gapStep{
+				{kind: "connect"}, {kind: "event"}, {kind: "disconnect"},
+				{at: time.Minute, kind: "connect", cursor: time.Microsecond},
+				{at: time.Minute, kind: "event", eventAt: 10 * time.Minute},
+			},
+		},
+		{
+			name: "replay from a cursor older than the replay window",
+			steps: []gapStep{
+				{kind: "connect"}, {kind: "event"}, {kind: "disconnect"},
+				{at: 30 * time.Hour, kind: "connect", cursor: time.Microsecond},
+				{at: 30 * time.Hour, kind: "event", eventAt: 6 * time.Hour},
+			},
+			want: []recordedGap{{consumer: "test", start: us(time.Microsecond), end: us(6 * time.Hour)}},
+		},
+		{
+			name: "old cursor replayed in full",
+			steps: []gapStep{
+				{kind: "connect"}, {kind: "event"}, {kind: "disconnect"},
+				{at: 30 * time.Hour, kind: "connect", cursor: time.Microsecond},
+				{at: 30 * time.Hour, kind: "event", eventAt: time.Second},
+			},
+		},
+		{
+			name: "down past the threshold without a cursor",
+			steps: []gapStep{
+				{kind: "connect"}, {kind: "event"}, {at: time.Minute, kind: "disconnect"},
+				{at: 10 * time.Minute, kind: "connect"},
+			},
+			want: []recordedGap{{consumer: "test", start: us(0), end: us(10 * time.Minute)}},
+		},
+		{
+			name: "down within the threshold without a cursor",
+			steps: []gapStep{
+				{kind: "connect"}, {kind: "event"}, {kind: "disconnect"},
+				{at: time.Minute, kind: "connect"},
+			},
+		},
+		{
+			name: "quiet connection kept alive by pongs",
+			steps: []gapStep{
+				{kind: "connect"}, {kind: "event"},
+				{at: 9 * time.Minute, kind: "pong"}, {at: 9 * time.Minute, kind: "disconnect"},
+				{at: 10 * time.Minute, kind: "connect"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := &recordingGapRecorder{}
+			clock := &fakeClock{}
+			detector := newGapDetector(recorder, "test", 2*time.Minute, 24*time.Hour)
+			detector.now = clock.Now
+			ctx := context.Background()
+			for _, step := range tt.steps {
+				clock.now = start.Add(step.at)
+				switch step.kind {
+				case "connect":
+					var cursor int64
+					if step.cursor > 0 {
+						cursor = us(step.cursor)
+					}
+					detector.reconnected(ctx, cursor)
+				case "event":
+					detector.observe(ctx, us(step.eventAt))
+				case "pong":
+					detector.alive(ctx)
+				case "disconnect":
+					detector.disconnected(ctx)
+				}
+			}
+
+			got := recorder.recorded()
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d gaps, got %+v", len(tt.want), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Gap %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGapDetector_Restart(t *testing.T) {
+	start := time.Now().Truncate(time.Second)
+	ctx := context.Background()
+	recorder := &recordingGapRecorder{}
+	clock := &fakeClock{now: start}
+
+	// The last process saw an event and was up until a pong a minute later
+	before := newGapDetector(recorder, "test", 2*time.Minute, 24*time.Hour)
+	before.now = clock.Now
+	if cursor := before.load(ctx); cursor != 0 {
+		t.Fatalf("Expected no cursor without a saved position, got %d", cursor)
+	}
+	before.reconnected(ctx, 0)
+	before.observe(ctx, start.UnixMicro())
+	clock.now = start.Add(time.Minute)
+	before.alive(ctx)
+
+	// It restarts ten minutes later
+	clock.now = start.Add(11 * time.Minute)
+	after := newGapDetector(recorder, "test", 2*time.Minute, 24*time.Hour)
+	after.now = clock.Now
+	cursor := after.load(ctx)
+	if want := start.Add(-resumeRewind).UnixMicro(); cursor != want {
+		t.Errorf("Expected a restart to resume from %d, got %d", want, cursor)
+	}
+
+	// Without a cursor the time it was down is a gap
+	after.reconnected(ctx, 0)
+	gaps := recorder.recorded()
+	want := recordedGap{consumer: "test", start: start.Add(time.Minute).UnixMicro(), end: clock.now.UnixMicro()}
+	if len(gaps) != 1 || gaps[0] != want {
+		t.Errorf("Expected gap %+v, got %+v", want, gaps)
+	}
+}
+
+func TestGapDetector_SavesPositionPeriodically(t *testing.T) {
+	start := time.Now()
+	ctx := context.Background()
+	recorder := &recordingGapRecorder{}
+	clock := &fakeClock{now: start}
+	detector := newGapDetector(recorder, "test", 2*time.Minute, 24*time.Hour)
+	detector.now = clock.Now
+
+	detector.reconnected(ctx, 0)
+	for i := 0; i < 10; i++ {
+		detector.observe(ctx, start.UnixMicro()+int64(i))
+	}
+	if recorder.saves != 1 {
+		t.Errorf("Expected events within the save interval to save once, got %d saves", recorder.saves)
+	}
+
+	clock.now = start.Add(positionSaveInterval)
+	detector.observe(ctx, start.UnixMicro()+10)
+	detector.disconnected(ctx)
+	if recorder.saves != 3 {
+		t.Errorf("Expected a save after the interval and one on disconnect, got %d saves", recorder.saves)
+	}
+	if got := recorder.positions["test"].LastSeenUS; got != start.UnixMicro()+10 {
+		t.Errorf("Expected the newest event to be saved, got %d", got)
+	}
+}
+
+func TestGapDetector_NilIsDisabled(t *testing.T) {
+	ctx := context.Background()
+	var detector *gapDetector
+	detector.load(ctx)
+	detector.reconnected(ctx, 0)
+	detector.observe(ctx, time.Now().UnixMicro())
+	detector.alive(ctx)
+	detector.disconnected(ctx)
+
+	detector = newGapDetector(nil, "test", time.Minute, time.Hour)
+	detector.load(ctx)
+	detector.reconnected(ctx, 0)
+	detector.observe(ctx, time.Now().UnixMicro())
+	detector.alive(ctx)
+	detector.disconnected(ctx)
+}
+
+func TestJetstreamDispatcher_RecordsGapOnStaleCursor(t *testing.T) {
+	start := time.Now().Add(-time.Hour).UnixMicro()
+	resumed := time.Now().UnixMicro()
+	// The second connection asks to replay from the first one's last event, an hour old, but
+	// Jetstream only keeps half an hour and starts an hour later
+	server := newClosingFakeJetstream(t, [][]*JetstreamEvent{
+		{eventAt("p1", start), eventAt("p2", start+1)},
+		{eventAt("p3", resumed), eventAt("p4", resumed+1)},
+	})
+
+	posts := &recordingHandler{}
+	recorder := &recordingGapRecorder{}
+	dispatcher := NewJetstreamDispatcher(wsURL(server), 1, 8)
+	dispatcher.SetGapRecorder(recorder, 5*time.Minute, 30*time.Minute)
+	if err := dispatcher.Register("post", posts, "social.coves.community.post"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	dispatcher.startWorkers(ctx, &wg)
+	defer wg.Wait()
+	defer cancel()
+
+	// Each connection ends when the fake server closes it
+	if err := dispatcher.connect(ctx); err == nil {
+		t.Fatal("Expected the first connection to end with a read error")
+	}
+	if len(recorder.recorded()) != 0 {
+		t.Fatalf("Expected no gap on the first connection, got %+v", recorder.recorded())
+	}
+	resumeURL, err := dispatcher.URL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "cursor=" + strconv.FormatInt(start+1, 10); !strings.Contains(resumeURL, want) {
+		t.Errorf("Expected the reconnect to resume from %s, got %s", want, resumeURL)
+	}
+
+	if err := dispatcher.connect(ctx); err == nil {
+		t.Fatal("Expected the second connection to end with a read error")
+	}
+	waitForEvents(t, posts, 4)
+
+	gaps := recorder.recorded()
+	if len(gaps) != 1 {
+		t.Fatalf("Expected one gap, got %+v", gaps)
+	}
+	if want := (recordedGap{consumer: "jetstream", start: start + 1, end: resumed}); gaps[0] != want {
+		t.Errorf("Expected gap %+v, got %+v", want, gaps[0])
+	}
+}
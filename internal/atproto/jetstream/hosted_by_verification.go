@@ -0,0 +1,314 @@
+package jetstream
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultHostVerificationTTL is how long a successful hostedBy verification is trusted
+	// (24 hours matches Bluesky recommendations)
+	DefaultHostVerificationTTL = 24 * time.Hour
+
+	// hostVerificationFailureTTL is how long a definitive failure (mismatch, missing document)
+	// is remembered - short so a fixed DID document is picked up quickly
+	hostVerificationFailureTTL = 15 * time.Minute
+
+	// hostVerificationAttempts bounds DID document fetches per verification; only transient
+	// failures (network errors, 5xx, 429) are retried
+	hostVerificationAttempts = 3
+
+	defaultHostVerificationBackoff = 500 * time.Millisecond
+)
+
+// cachedDIDDoc represents a cached verification result with expiration
+type cachedDIDDoc struct {
+	expiresAt time.Time // When this cache entry expires
+	reason    string    // Why verification failed; empty when valid
+	valid     bool      // Whether verification passed
+}
+
+// transientVerificationError marks a DID document fetch failure that says nothing about
+// whether the document is valid (network error, server error, rate limit) - these are
+// retried and never cached, so a flaky instance isn't rejected for the failure TTL
+type transientVerificationError struct {
+	err error
+}
+
+func (e *transientVerificationError) Error() string { return e.err.Error() }
+func (e *transientVerificationError) Unwrap() error { return e.err }
+
+func isTransientVerificationError(err error) bool {
+	var transient *transientVerificationError
+	return errors.As(err, &transient)
+}
+
+// SetHostVerificationStore persists hostedBy verification results in store, so they survive
+// restarts and can be re-verified by ReverifyHostedBy
+// ttl is how long a successful verification is trusted; zero uses DefaultHostVerificationTTL
+func (c *CommunityEventConsumer) SetHostVerificationStore(store communities.HostVerificationRepository, ttl time.Duration) {
+	c.hostVerifications = store
+	c.verificationTTL = ttl
+}
+
+func (c *CommunityEventConsumer) successTTL() time.Duration {
+	if c.verificationTTL > 0 {
+		return c.verificationTTL
+	}
+	return DefaultHostVerificationTTL
+}
+
+func (c *CommunityEventConsumer) didDocURL(domain string) string {
+	if c.didDocumentURL != nil {
+		return c.didDocumentURL(domain)
+	}
+	return fmt.Sprintf("https://%s/.well-known/did.json", domain)
+}
+
+// verifyDIDDocument fetches and validates the DID document from .well-known/did.json
+// Implements Bluesky's bidirectional verification model:
+//  1. Verify DID document exists at https://domain/.well-known/did.json
+//  2. Verify DID document ID matches claimed DID
+//  3. Verify DID document claims the handle in alsoKnownAs field
+//
+// Results are cached per domain and DID (in memory, and in the host verification store when
+// configured) and fetches are rate-limited to prevent DoS attacks
+// Transient fetch failures are retried with backoff; only a definitive failure is cached
+func (c *CommunityEventConsumer) verifyDIDDocument(ctx context.Context, did, domain, handle string) error {
+	// Skip verification in dev mode
+	if c.skipVerification {
+		return nil
+	}
+
+	if cached, ok := c.cachedVerification(ctx, did, domain); ok {
+		if !cached.valid {
+			return fmt.Errorf("cached verification failure for %s: %s", did, cached.reason)
+		}
+		log.Printf("✓ DID document verification (cached): %s", domain)
+		return nil
+	}
+
+	err := c.fetchDIDDocumentWithRetry(ctx, did, domain, handle)
+	if isTransientVerificationError(err) {
+		return err
+	}
+	c.recordVerification(ctx, did, domain, err)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("✓ DID document verified: %s", domain)
+	return nil
+}
+
+// cachedVerification returns an unexpired verification result from the LRU cache, falling
+// back to the host verification store
+func (c *CommunityEventConsumer) cachedVerification(ctx context.Context, did, domain string) (cachedDIDDoc, bool) {
+	key := domain + "|" + did
+	now := time.Now()
+
+	// Check bounded LRU cache first (thread-safe, no locks needed)
+	if cached, ok := c.didCache.Get(key); ok {
+		if now.Before(cached.expiresAt) {
+			return cached, true
+		}
+		// Cache entry expired - remove it to free up space for fresh entries
+		c.didCache.Remove(key)
+	}
+
+	if c.hostVerifications == nil {
+		return cachedDIDDoc{}, false
+	}
+
+	stored, err := c.hostVerifications.GetHostVerification(ctx, domain, did)
+	if err != nil {
+		if !errors.Is(err, communities.ErrHostVerificationNotFound) {
+			log.Printf("WARNING: Failed to load host verification for %s: %v", did, err)
+		}
+		return cachedDIDDoc{}, false
+	}
+	if !now.Before(stored.ExpiresAt) {
+		return cachedDIDDoc{}, false
+	}
+
+	cached := cachedDIDDoc{valid: stored.Valid, reason: stored.Reason, expiresAt: stored.ExpiresAt}
+	c.didCache.Add(key, cached)
+	return cached, true
+}
+
+// recordVerification caches a definitive verification result (verifyErr nil on success)
+// in memory and in the host verification store
+func (c *CommunityEventConsumer) recordVerification(ctx context.Context, did, domain string, verifyErr error) {
+	now := time.Now()
+	result := cachedDIDDoc{valid: verifyErr == nil, expiresAt: now.Add(c.successTTL())}
+	if verifyErr != nil {
+		result.reason = verifyErr.Error()
+		result.expiresAt = now.Add(hostVerificationFailureTTL)
+	}
+
+	// The LRU cache is thread-safe and automatically evicts least-recently-used entries when full
+	c.didCache.Add(domain+"|"+did, result)
+
+	if c.hostVerifications == nil {
+		return
+	}
+	if err := c.hostVerifications.SaveHostVerification(ctx, &communities.HostVerification{
+		Domain:     domain,
+		DID:        did,
+		Valid:      result.valid,
+		Reason:     result.reason,
+		VerifiedAt: now,
+		ExpiresAt:  result.expiresAt,
+	}); err != nil {
+		log.Printf("WARNING: Failed to persist host verification for %s: %v", did, err)
+	}
+}
+
+// fetchDIDDocumentWithRetry verifies the DID document, retrying transient failures with
+// exponential backoff
+func (c *CommunityEventConsumer) fetchDIDDocumentWithRetry(ctx context.Context, did, domain, handle string) error {
+	backoff := c.retryBackoff
+	if backoff <= 0 {
+		backoff = defaultHostVerificationBackoff
+	}
+
+	var err error
+	for attempt := 1; attempt <= hostVerificationAttempts; attempt++ {
+		err = c.fetchDIDDocument(ctx, did, domain, handle)
+		if err == nil || !isTransientVerificationError(err) || attempt == hostVerificationAttempts {
+			return err
+		}
+
+		log.Printf("DID document fetch for %s failed (attempt %d/%d), retrying in %s: %v",
+			domain, attempt, hostVerificationAttempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			return &transientVerificationError{err: fmt.Errorf("DID document verification for %s cancelled: %w", domain, ctx.Err())}
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return err
+}
+
+// fetchDIDDocument makes a single verification attempt
+// Failures that don't prove the document invalid are returned as transientVerificationError
+func (c *CommunityEventConsumer) fetchDIDDocument(ctx context.Context, did, domain, handle string) error {
+	// Rate limit .well-known fetches to prevent DoS
+	if err := c.wellKnownLimiter.Wait(ctx); err != nil {
+		return &transientVerificationError{err: fmt.Errorf("rate limit exceeded for .well-known fetch: %w", err)}
+	}
+
+	didDocURL := c.didDocURL(domain)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, didDocURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Fetch DID document using shared HTTP client
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &transientVerificationError{err: fmt.Errorf("failed to fetch DID document from %s: %w", didDocURL, err)}
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Failed to close response body: %v", closeErr)
+		}
+	}()
+
+	// Verify HTTP status - server errors and throttling are worth retrying, anything else
+	// (404, 403, ...) means the instance doesn't serve a DID document
+	if resp.StatusCode != http.StatusOK {
+		statusErr := fmt.Errorf("DID document returned HTTP %d from %s", resp.StatusCode, didDocURL)
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusRequestTimeout {
+			return &transientVerificationError{err: statusErr}
+		}
+		return statusErr
+	}
+
+	// Parse DID document
+	var didDoc struct {
+		ID          string   `json:"id"`
+		AlsoKnownAs []string `json:"alsoKnownAs"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&didDoc); err != nil {
+		return fmt.Errorf("failed to parse DID document JSON: %w", err)
+	}
+
+	// Verify DID document ID matches claimed DID
+	if didDoc.ID != did {
+		return fmt.Errorf("DID document ID (%s) doesn't match claimed DID (%s)", didDoc.ID, did)
+	}
+
+	// SECURITY: Bidirectional verification - DID document must claim this handle
+	// Prevents impersonation where someone points DNS to another user's DID
+	// Format: handle "coves.social" or "!community@coves.social" → check for "at://coves.social"
+	handleDomain := extractDomainFromHandle(handle)
+	expectedAlias := fmt.Sprintf("at://%s", handleDomain)
+
+	for _, alias := range didDoc.AlsoKnownAs {
+		if alias == expectedAlias {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("DID document does not claim handle domain %s in alsoKnownAs (expected %s, got %v)",
+		handleDomain, expectedAlias, didDoc.AlsoKnownAs)
+}
+
+// ReverifyHostedBy re-checks the DID document of every instance hosting an indexed did:web
+// hostedBy community, bypassing the cache, and flags communities whose instance no longer
+// verifies (clearing the flag once it does again, which also lists communities indexed while
+// their instance couldn't be reached)
+// Instances that can't be reached are skipped until the next run rather than flagged
+// Returns the number of instances checked and communities newly flagged
+func (c *CommunityEventConsumer) ReverifyHostedBy(ctx context.Context) (checked, flagged int, err error) {
+	if c.skipVerification || c.hostVerifications == nil {
+		return 0, 0, nil
+	}
+
+	hostedByDIDs, err := c.hostVerifications.ListHostedByDIDs(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, hostedByDID := range hostedByDIDs {
+		if ctx.Err() != nil {
+			return checked, flagged, ctx.Err()
+		}
+
+		domain := strings.TrimPrefix(hostedByDID, "did:web:")
+		verifyCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		verifyErr := c.fetchDIDDocumentWithRetry(verifyCtx, hostedByDID, domain, domain)
+		cancel()
+
+		if isTransientVerificationError(verifyErr) {
+			log.Printf("Skipping hostedBy re-verification for %s: %v", hostedByDID, verifyErr)
+			continue
+		}
+		checked++
+		c.recordVerification(ctx, hostedByDID, domain, verifyErr)
+
+		changed, err := c.hostVerifications.SetHostedByUnverified(ctx, hostedByDID, verifyErr != nil)
+		if err != nil {
+			return checked, flagged, err
+		}
+		if verifyErr != nil {
+			flagged += changed
+			log.Printf("🚨 SECURITY: hostedBy instance %s no longer verifies (%d communities flagged): %v",
+				hostedByDID, changed, verifyErr)
+		} else if changed > 0 {
+			log.Printf("hostedBy instance %s verifies again (%d communities cleared)", hostedByDID, changed)
+		}
+	}
+
+	return checked, flagged, nil
+}
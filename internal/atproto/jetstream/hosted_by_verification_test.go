@@ -0,0 +1,319 @@
+package jetstream
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memoryHostVerifications keeps verification results and community flags in memory
+type memoryHostVerifications struct {
+	results    map[string]*communities.HostVerification
+	hostedBy   map[string][]string // hostedBy DID -> community DIDs
+	unverified map[string]bool     // community DID -> flagged
+	mu         sync.Mutex
+}
+
+func newMemoryHostVerifications() *memoryHostVerifications {
+	return &memoryHostVerifications{
+		results:    make(map[string]*communities.HostVerification),
+		hostedBy:   make(map[string][]string),
+		unverified: make(map[string]bool),
+	}
+}
+
+func (m *memoryHostVerifications) GetHostVerification(ctx context.Context, domain, did string) (*communities.HostVerification, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.results[domain+"|"+did]
+	if !ok {
+		return nil, communities.ErrHostVerificationNotFound
+	}
+	return v, nil
+}
+
+func (m *memoryHostVerifications) SaveHostVerification(ctx context.Context, v *communities.HostVerification) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[v.Domain+"|"+v.DID] = v
+	return nil
+}
+
+func (m *memoryHostVerifications) ListHostedByDIDs(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	dids := make([]string, 0, len(m.hostedBy))
+	for did := range m.hostedBy {
+		dids = append(dids, did)
+	}
+	return dids, nil
+}
+
+func (m *memoryHostVerifications) SetHostedByUnverified(ctx context.Context, hostedByDID string, unverified bool) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	changed := 0
+	for _, communityDID := range m.hostedBy[hostedByDID] {
+		if m.unverified[communityDID] != unverified {
+			m.unverified[communityDID] = unverified
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// didWebServer serves a DID document for did:web:{domain}, failing the first failures
+// requests with 503
+type didWebServer struct {
+	*httptest.Server
+	doc      map[string]interface{}
+	requests atomic.Int32
+	failures int32
+	mu       sync.Mutex
+}
+
+func newDIDWebServer(t *testing.T, domain string, failures int32) *didWebServer {
+	t.Helper()
+	s := &didWebServer{
+		failures: failures,
+		doc: map[string]interface{}{
+			"id":          "did:web:" + domain,
+			"alsoKnownAs": []string{"at://" + domain},
+		},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.requests.Add(1) <= s.failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(s.doc)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *didWebServer) setAlsoKnownAs(aliases ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.doc["alsoKnownAs"] = aliases
+}
+
+// newVerifyingConsumer returns a consumer that verifies DID documents against server
+func newVerifyingConsumer(server *didWebServer, store communities.HostVerificationRepository) *CommunityEventConsumer {
+	consumer := NewCommunityEventConsumer(nil, "did:web:coves.local", false, nil)
+	consumer.didDocumentURL = func(domain string) string { return server.URL + "/.well-known/did.json" }
+	consumer.retryBackoff = time.Millisecond
+	if store != nil {
+		consumer.SetHostVerificationStore(store, time.Hour)
+	}
+	return consumer
+}
+
+func TestVerifyDIDDocument_Success(t *testing.T) {
+	ctx := context.Background()
+	const domain = "coves.test"
+	server := newDIDWebServer(t, domain, 0)
+	store := newMemoryHostVerifications()
+	consumer := newVerifyingConsumer(server, store)
+
+	if err := consumer.verifyDIDDocument(ctx, "did:web:"+domain, domain, "c-gaming."+domain); err != nil {
+		t.Fatalf("Expected verification to pass, got %v", err)
+	}
+
+	stored, err := store.GetHostVerification(ctx, domain, "did:web:"+domain)
+	if err != nil {
+		t.Fatalf("Expected result to be persisted: %v", err)
+	}
+	if !stored.Valid || stored.ExpiresAt.Sub(stored.VerifiedAt) != time.Hour {
+		t.Errorf("Expected valid result with the configured TTL, got %+v", stored)
+	}
+
+	// A fresh consumer (e.g. after a restart) trusts the persisted result without fetching
+	restarted := newVerifyingConsumer(server, store)
+	if err := restarted.verifyDIDDocument(ctx, "did:web:"+domain, domain, "c-gaming."+domain); err != nil {
+		t.Fatalf("Expected persisted verification to pass, got %v", err)
+	}
+	if got := server.requests.Load(); got != 1 {
+		t.Errorf("Expected 1 DID document fetch, got %d", got)
+	}
+}
+
+func TestVerifyDIDDocument_Mismatch(t *testing.T) {
+	ctx := context.Background()
+	const domain = "coves.test"
+	server := newDIDWebServer(t, domain, 0)
+	server.setAlsoKnownAs("at://evil.test")
+	store := newMemoryHostVerifications()
+	consumer := newVerifyingConsumer(server, store)
+
+	if err := consumer.verifyDIDDocument(ctx, "did:web:"+domain, domain, "c-gaming."+domain); err == nil {
+		t.Fatal("Expected alsoKnownAs mismatch to be rejected")
+	}
+	if got := server.requests.Load(); got != 1 {
+		t.Errorf("Expected a mismatch not to be retried, got %d fetches", got)
+	}
+
+	stored, err := store.GetHostVerification(ctx, domain, "did:web:"+domain)
+	if err != nil {
+		t.Fatalf("Expected failure to be persisted: %v", err)
+	}
+	if stored.Valid || stored.Reason == "" {
+		t.Errorf("Expected invalid result with a reason, got %+v", stored)
+	}
+
+	// The cached failure is returned without another fetch
+	if err := consumer.verifyDIDDocument(ctx, "did:web:"+domain, domain, "c-gaming."+domain); err == nil {
+		t.Fatal("Expected cached failure")
+	}
+	if got := server.requests.Load(); got != 1 {
+		t.Errorf("Expected cached failure not to refetch, got %d fetches", got)
+	}
+}
+
+func TestVerifyDIDDocument_FlakyThenSuccess(t *testing.T) {
+	ctx := context.Background()
+	const domain = "coves.test"
+
+	t.Run("retries transient errors", func(t *testing.T) {
+		server := newDIDWebServer(t, domain, hostVerificationAttempts-1)
+		consumer := newVerifyingConsumer(server, nil)
+
+		if err := consumer.verifyDIDDocument(ctx, "did:web:"+domain, domain, "c-gaming."+domain); err != nil {
+			t.Fatalf("Expected verification to pass after retries, got %v", err)
+		}
+		if got := server.requests.Load(); got != hostVerificationAttempts {
+			t.Errorf("Expected %d fetches, got %d", hostVerificationAttempts, got)
+		}
+	})
+
+	t.Run("exhausted retries are not cached", func(t *testing.T) {
+		server := newDIDWebServer(t, domain, hostVerificationAttempts)
+		store := newMemoryHostVerifications()
+		consumer := newVerifyingConsumer(server, store)
+
+		if err := consumer.verifyDIDDocument(ctx, "did:web:"+domain, domain, "c-gaming."+domain); !isTransientVerificationError(err) {
+			t.Fatalf("Expected transient error once retries are exhausted, got %v", err)
+		}
+		if _, err := store.GetHostVerification(ctx, domain, "did:web:"+domain); !errors.Is(err, communities.ErrHostVerificationNotFound) {
+			t.Errorf("Expected transient failure not to be persisted, got %v", err)
+		}
+
+		// The instance recovers - the next event verifies immediately
+		if err := consumer.verifyDIDDocument(ctx, "did:web:"+domain, domain, "c-gaming."+domain); err != nil {
+			t.Errorf("Expected verification to pass once the instance recovers, got %v", err)
+		}
+	})
+}
+
+func TestReverifyHostedBy(t *testing.T) {
+	ctx := context.Background()
+	const domain = "coves.test"
+	server := newDIDWebServer(t, domain, 0)
+	store := newMemoryHostVerifications()
+	store.hostedBy["did:web:"+domain] = []string{"did:plc:gaming", "did:plc:music"}
+	consumer := newVerifyingConsumer(server, store)
+
+	checked, flagged, err := consumer.ReverifyHostedBy(ctx)
+	if err != nil || checked != 1 || flagged != 0 {
+		t.Fatalf("Expected 1 instance checked and none flagged, got checked=%d flagged=%d err=%v", checked, flagged, err)
+	}
+
+	// The instance drops its alias - both communities are flagged
+	server.setAlsoKnownAs()
+	if _, flagged, err = consumer.ReverifyHostedBy(ctx); err != nil || flagged != 2 {
+		t.Fatalf("Expected 2 communities flagged, got %d (err=%v)", flagged, err)
+	}
+	if !store.unverified["did:plc:gaming"] || !store.unverified["did:plc:music"] {
+		t.Error("Expected communities to be flagged unverified")
+	}
+
+	// The cached success didn't mask the failure, and the failure is what events now see
+	if err := consumer.verifyDIDDocument(ctx, "did:web:"+domain, domain, "c-gaming."+domain); err == nil {
+		t.Error("Expected events to see the re-verification failure")
+	}
+
+	// Fixed again - flags are cleared
+	server.setAlsoKnownAs("at://" + domain)
+	if _, flagged, err = consumer.ReverifyHostedBy(ctx); err != nil || flagged != 0 {
+		t.Fatalf("Expected nothing flagged, got %d (err=%v)", flagged, err)
+	}
+	if store.unverified["did:plc:gaming"] || store.unverified["did:plc:music"] {
+		t.Error("Expected flags to be cleared once the instance verifies")
+	}
+}
+
+// communityCaptureRepo records the communities the consumer indexes
+type communityCaptureRepo struct {
+	communities.Repository
+	created []*communities.Community
+}
+
+func (r *communityCaptureRepo) Create(ctx context.Context, community *communities.Community) (*communities.Community, error) {
+	r.created = append(r.created, community)
+	return community, nil
+}
+
+func TestCreateCommunity_UnreachableHostIndexedUnverified(t *testing.T) {
+	ctx := context.Background()
+	const domain = "coves.test"
+	record := map[string]interface{}{
+		"$type":      "social.coves.community.profile",
+		"handle":     "c-gaming." + domain,
+		"name":       "gaming",
+		"createdBy":  "did:plc:creator",
+		"hostedBy":   "did:web:" + domain,
+		"visibility": "public",
+		"createdAt":  time.Now().Format(time.RFC3339),
+	}
+
+	t.Run("indexed hidden until re-verification", func(t *testing.T) {
+		server := newDIDWebServer(t, domain, hostVerificationAttempts)
+		store := newMemoryHostVerifications()
+		consumer := newVerifyingConsumer(server, store)
+		repo := &communityCaptureRepo{}
+		consumer.repo = repo
+
+		event := newTestCommitEvent("did:plc:gaming", "social.coves.community.profile", "self", record)
+		if err := consumer.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("Expected the community to be indexed, got %v", err)
+		}
+		if len(repo.created) != 1 || !repo.created[0].HostedByUnverified {
+			t.Fatalf("Expected the community to be indexed unverified, got %+v", repo.created)
+		}
+
+		// The instance recovers and the next re-verification lists the community
+		store.hostedBy["did:web:"+domain] = []string{"did:plc:gaming"}
+		store.unverified["did:plc:gaming"] = true
+		if _, _, err := consumer.ReverifyHostedBy(ctx); err != nil {
+			t.Fatalf("ReverifyHostedBy failed: %v", err)
+		}
+		if store.unverified["did:plc:gaming"] {
+			t.Error("Expected the flag to be cleared once the instance verifies")
+		}
+	})
+
+	t.Run("rejected without a store to re-verify from", func(t *testing.T) {
+		server := newDIDWebServer(t, domain, hostVerificationAttempts)
+		consumer := newVerifyingConsumer(server, nil)
+		repo := &communityCaptureRepo{}
+		consumer.repo = repo
+
+		event := newTestCommitEvent("did:plc:gaming", "social.coves.community.profile", "self", record)
+		if err := consumer.HandleEvent(ctx, event); err == nil {
+			t.Fatal("Expected the event to fail so it can be retried")
+		}
+		if len(repo.created) != 0 {
+			t.Errorf("Expected nothing indexed, got %d", len(repo.created))
+		}
+	})
+}
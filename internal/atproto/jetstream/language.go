@@ -0,0 +1,16 @@
+package jetstream
+
+import "Coves/internal/core/translation"
+
+// detectLanguage detects the language of a record's text when the record doesn't declare langs
+// Returns nils, storing nothing, for records with langs and for unreliable detections.
+func detectLanguage(langs []string, texts ...string) (*string, *float64) {
+	if len(langs) > 0 {
+		return nil, nil
+	}
+	detection := translation.DetectLanguage(texts...)
+	if !detection.Reliable() {
+		return nil, nil
+	}
+	return &detection.Lang, &detection.Confidence
+}
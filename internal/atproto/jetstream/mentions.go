@@ -0,0 +1,388 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/identity"
+	"Coves/internal/validation/text"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+)
+
+// Constants for mention parsing and resolution
+const (
+	// MaxMentionsPerComment caps how many distinct handles are resolved (and notified) per comment
+	// Prevents a single comment from spamming notifications or hammering the identity resolver
+	MaxMentionsPerComment = 10
+
+	// mentionResolveConcurrency bounds parallel handle resolutions for a single comment
+	mentionResolveConcurrency = 4
+
+	// mentionNegativeCacheSize bounds the number of unresolvable handles remembered in memory
+	mentionNegativeCacheSize = 5000
+
+	// Negative cache TTLs: handles that don't exist are remembered longer than transient failures
+	mentionNotFoundTTL = 1 * time.Hour
+	mentionFailureTTL  = 1 * time.Minute
+
+	// maxHandleLength is the atProto limit for a full handle
+	maxHandleLength = 253
+
+	// facetType and mentionFeatureType are the lexicon identifiers used in stored facets
+	facetType          = "social.coves.richtext.facet"
+	mentionFeatureType = "social.coves.richtext.facet#mention"
+
+	// NotificationReasonMention is the notifications.reason value for comment mentions
+	NotificationReasonMention = "mention"
+)
+
+// handleRegex follows the atProto handle syntax: dot-separated labels of ASCII letters, digits
+// and hyphens (no leading/trailing hyphen, max 63 chars each), at least two labels, and a TLD
+// that starts with a letter
+var handleRegex = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)+[a-zA-Z]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// mentionSpan is an @handle mention found in comment content
+// ByteStart/ByteEnd cover the leading '@' and the handle, in UTF-8 bytes (end exclusive)
+type mentionSpan struct {
+	Handle    string // Lowercased handle without the '@'
+	ByteStart int
+	ByteEnd   int
+}
+
+// parseMentions extracts @handle mentions from text
+// A mention must start at the beginning of the text or after whitespace or '(' (so email
+// addresses don't match), and must not run directly into another word character.
+// Trailing dots are treated as sentence punctuation, not part of the handle.
+func parseMentions(text string) []mentionSpan {
+	var spans []mentionSpan
+
+	for i := 0; i < len(text); i++ {
+		if text[i] != '@' {
+			continue
+		}
+
+		if i > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(text[:i])
+			if !unicode.IsSpace(prev) && prev != '(' {
+				continue
+			}
+		}
+
+		end := i + 1
+		for end < len(text) && isHandleByte(text[end]) {
+			end++
+		}
+		for end > i+1 && text[end-1] == '.' {
+			end--
+		}
+
+		handle := text[i+1 : end]
+		if end < len(text) && text[end] != '.' {
+			// Handle runs into another word character (e.g. a non-ASCII letter or underscore)
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			if next == '_' || unicode.IsLetter(next) || unicode.IsDigit(next) {
+				i = end
+				continue
+			}
+		}
+
+		if len(handle) > maxHandleLength || !handleRegex.MatchString(handle) {
+			i = end - 1
+			continue
+		}
+
+		spans = append(spans, mentionSpan{
+			Handle:    strings.ToLower(handle),
+			ByteStart: i,
+			ByteEnd:   end,
+		})
+		i = end - 1
+	}
+
+	return spans
+}
+
+// isHandleByte reports whether b can appear inside an atProto handle
+func isHandleByte(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9') || b == '.' || b == '-'
+}
+
+// uniqueMentionHandles returns the distinct handles in document order, capped at max
+func uniqueMentionHandles(spans []mentionSpan, max int) []string {
+	seen := make(map[string]bool, len(spans))
+	handles := make([]string, 0, len(spans))
+	for _, span := range spans {
+		if seen[span.Handle] {
+			continue
+		}
+		if len(handles) >= max {
+			break
+		}
+		seen[span.Handle] = true
+		handles = append(handles, span.Handle)
+	}
+	return handles
+}
+
+// mentionResolver resolves mentioned handles to DIDs with bounded concurrency
+// Failed lookups are remembered in a bounded negative cache so repeated mentions of
+// nonexistent handles don't trigger a DNS/HTTPS lookup on every comment
+type mentionResolver struct {
+	resolver interface {
+		Resolve(context.Context, string) (*identity.Identity, error)
+	}
+	negativeCache *lru.Cache[string, time.Time] // handle -> expiry
+	concurrency   int
+}
+
+// newMentionResolver creates a mention resolver backed by the identity resolver
+func newMentionResolver(resolver interface {
+	Resolve(context.Context, string) (*identity.Identity, error)
+},
+) *mentionResolver {
+	cache, err := lru.New[string, time.Time](mentionNegativeCacheSize)
+	if err != nil {
+		// Only fails for a non-positive size
+		panic(fmt.Sprintf("cannot create mention negative cache: %v", err))
+	}
+	return &mentionResolver{
+		resolver:      resolver,
+		negativeCache: cache,
+		concurrency:   mentionResolveConcurrency,
+	}
+}
+
+// ResolveHandles resolves handles to DIDs, returning only the handles that resolved
+// Resolution is best-effort: failures are cached and logged, never returned
+func (m *mentionResolver) ResolveHandles(ctx context.Context, handles []string) map[string]string {
+	resolved := make(map[string]string, len(handles))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, m.concurrency)
+
+	for _, handle := range handles {
+		if expiresAt, ok := m.negativeCache.Get(handle); ok {
+			if time.Now().Before(expiresAt) {
+				continue
+			}
+			m.negativeCache.Remove(handle)
+		}
+
+		wg.Add(1)
+		go func(handle string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			ident, err := m.resolver.Resolve(ctx, handle)
+			if err != nil || ident == nil || ident.DID == "" {
+				ttl := mentionFailureTTL
+				var notFound *identity.ErrNotFound
+				var invalid *identity.ErrInvalidIdentifier
+				if errors.As(err, &notFound) || errors.As(err, &invalid) {
+					ttl = mentionNotFoundTTL
+				}
+				if ctx.Err() == nil {
+					m.negativeCache.Add(handle, time.Now().Add(ttl))
+				}
+				log.Printf("Mention handle did not resolve: %s: %v", handle, err)
+				return
+			}
+
+			mu.Lock()
+			resolved[handle] = ident.DID
+			mu.Unlock()
+		}(handle)
+	}
+
+	wg.Wait()
+	return resolved
+}
+
+// commentMentions is the result of processing mentions in a comment's content
+type commentMentions struct {
+	Facets []interface{} // Mention facets for resolved handles, in document order
+	DIDs   []string      // Distinct mentioned DIDs, in document order
+}
+
+// resolveCommentMentions parses and resolves the mentions in content
+// Returns nil when mention processing is disabled or the content has no resolvable mentions
+func (c *CommentEventConsumer) resolveCommentMentions(ctx context.Context, content string) *commentMentions {
+	if c.mentionResolver == nil {
+		return nil
+	}
+
+	spans := parseMentions(content)
+	if len(spans) == 0 {
+		return nil
+	}
+
+	handles := uniqueMentionHandles(spans, MaxMentionsPerComment)
+	resolved := c.mentionResolver.ResolveHandles(ctx, handles)
+	if len(resolved) == 0 {
+		return nil
+	}
+
+	result := &commentMentions{}
+	seenDIDs := make(map[string]bool, len(resolved))
+	for _, span := range spans {
+		did, ok := resolved[span.Handle]
+		if !ok {
+			continue
+		}
+		result.Facets = append(result.Facets, map[string]interface{}{
+			"$type": facetType,
+			"index": map[string]interface{}{
+				"byteStart": span.ByteStart,
+				"byteEnd":   span.ByteEnd,
+			},
+			"features": []interface{}{
+				map[string]interface{}{
+					"$type": mentionFeatureType,
+					"did":   did,
+				},
+			},
+		})
+		if !seenDIDs[did] {
+			seenDIDs[did] = true
+			result.DIDs = append(result.DIDs, did)
+		}
+	}
+
+	return result
+}
+
+// mergeMentionFacets combines record facets with server-resolved mention facets
+// Facets supplied by the author are kept as-is; a resolved mention is only added when its
+// byte range doesn't overlap an existing facet. The result is sorted by byteStart.
+func mergeMentionFacets(recordFacets, mentionFacets []interface{}) []interface{} {
+	merged := make([]interface{}, 0, len(recordFacets)+len(mentionFacets))
+	merged = append(merged, recordFacets...)
+
+	for _, mention := range mentionFacets {
+		start, end, _ := facetByteRange(mention)
+		overlaps := false
+		for _, existing := range recordFacets {
+			existingStart, existingEnd, ok := facetByteRange(existing)
+			if ok && start < existingEnd && existingStart < end {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			merged = append(merged, mention)
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		iStart, _, _ := facetByteRange(merged[i])
+		jStart, _, _ := facetByteRange(merged[j])
+		return iStart < jStart
+	})
+
+	return merged
+}
+
+// facetByteRange extracts index.byteStart/byteEnd from a facet decoded as JSON or built locally
+func facetByteRange(facet interface{}) (start, end int, ok bool) {
+	facetMap, isMap := facet.(map[string]interface{})
+	if !isMap {
+		return 0, 0, false
+	}
+	index, isMap := facetMap["index"].(map[string]interface{})
+	if !isMap {
+		return 0, 0, false
+	}
+	start, startOK := toInt(index["byteStart"])
+	end, endOK := toInt(index["byteEnd"])
+	return start, end, startOK && endOK
+}
+
+// sanitizeContentFacets sanitizes record content and moves facet byte ranges to match
+// Facets index into the author's original bytes, so each range is re-mapped onto the
+// sanitized text; facets that collapse to an empty range are dropped.
+func sanitizeContentFacets(content string, facets []interface{}) (string, []interface{}) {
+	sanitized := text.Sanitize(content)
+	if sanitized == content || len(facets) == 0 {
+		return sanitized, facets
+	}
+
+	remapped := make([]interface{}, 0, len(facets))
+	for _, facet := range facets {
+		start, end, ok := facetByteRange(facet)
+		if !ok {
+			remapped = append(remapped, facet)
+			continue
+		}
+		newStart, newEnd := text.MapOffset(content, start), text.MapOffset(content, end)
+		if newStart >= newEnd {
+			continue
+		}
+
+		facetMap := facet.(map[string]interface{})
+		copied := make(map[string]interface{}, len(facetMap))
+		for k, v := range facetMap {
+			copied[k] = v
+		}
+		copied["index"] = map[string]interface{}{"byteStart": newStart, "byteEnd": newEnd}
+		remapped = append(remapped, copied)
+	}
+	return sanitized, remapped
+}
+
+// toInt converts JSON-decoded (float64) or locally built (int) numbers
+func toInt(v interface{}) (int, bool) {
+	switch n := v.(type) {
+	case int:
+		return n, true
+	case float64:
+		return int(n), true
+	case json.Number:
+		i, err := n.Int64()
+		return int(i), err == nil
+	}
+	return 0, false
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertMentionNotifications creates one mention notification per mentioned DID
+// Self-mentions and recipients who muted the thread rooted at rootURI are skipped, and the
+// unique (recipient_did, reason, subject_uri) constraint dedupes replays and edits that
+// mention the same user again
+func insertMentionNotifications(ctx context.Context, db execer, authorDID, subjectURI, subjectCID, rootURI string, dids []string) error {
+	for _, did := range dids {
+		if did == authorDID {
+			continue
+		}
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO notifications (recipient_did, author_did, reason, subject_uri, subject_cid)
+			SELECT $1, $2, $3, $4, $5
+			WHERE NOT EXISTS (SELECT 1 FROM thread_mutes WHERE user_did = $1 AND root_uri = $6)
+			ON CONFLICT (recipient_did, reason, subject_uri) WHERE subject_parent_uri IS NULL DO NOTHING
+		`, did, authorDID, NotificationReasonMention, subjectURI, subjectCID, rootURI)
+		if err != nil {
+			return fmt.Errorf("failed to create mention notification for %s: %w", did, err)
+		}
+	}
+	return nil
+}
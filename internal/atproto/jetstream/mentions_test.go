@@ -0,0 +1,341 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/identity"
+	"Coves/internal/lexicon/validate"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []mentionSpan
+	}{
+		{
+			name: "single mention",
+			text: "hi @alice.bsky.social",
+			want: []mentionSpan{{Handle: "alice.bsky.social", ByteStart: 3, ByteEnd: 21}},
+		},
+		{
+			name: "mention at start is lowercased",
+			text: "@Alice.Coves.Social thanks",
+			want: []mentionSpan{{Handle: "alice.coves.social", ByteStart: 0, ByteEnd: 19}},
+		},
+		{
+			name: "byte offsets account for multibyte text before the mention",
+			text: "héllo 👋 @bob.test",
+			want: []mentionSpan{{Handle: "bob.test", ByteStart: 12, ByteEnd: 21}},
+		},
+		{
+			name: "trailing sentence punctuation is excluded",
+			text: "ask @bob.test. or @carol.test, or @dave.test!",
+			want: []mentionSpan{
+				{Handle: "bob.test", ByteStart: 4, ByteEnd: 13},
+				{Handle: "carol.test", ByteStart: 18, ByteEnd: 29},
+				{Handle: "dave.test", ByteStart: 34, ByteEnd: 44},
+			},
+		},
+		{
+			name: "mention inside parentheses",
+			text: "(cc @bob.test)",
+			want: []mentionSpan{{Handle: "bob.test", ByteStart: 4, ByteEnd: 13}},
+		},
+		{
+			name: "mention after opening paren",
+			text: "(@bob.test)",
+			want: []mentionSpan{{Handle: "bob.test", ByteStart: 1, ByteEnd: 10}},
+		},
+		{
+			name: "mention after non-breaking unicode space",
+			text: "hey @bob.test",
+			want: []mentionSpan{{Handle: "bob.test", ByteStart: 5, ByteEnd: 14}},
+		},
+		{
+			name: "punycode handle",
+			text: "@xn--caf-dma.example.com",
+			want: []mentionSpan{{Handle: "xn--caf-dma.example.com", ByteStart: 0, ByteEnd: 24}},
+		},
+		{
+			name: "email addresses are not mentions",
+			text: "mail me at alice@example.com",
+		},
+		{
+			name: "non-ASCII handle characters are not mentions",
+			text: "@café.example.com and @bob.tëst",
+		},
+		{
+			name: "single label is not a handle",
+			text: "@alice and @localhost.",
+		},
+		{
+			name: "TLD starting with a digit is not a handle",
+			text: "@alice.123",
+		},
+		{
+			name: "label with leading hyphen is not a handle",
+			text: "@-alice.test",
+		},
+		{
+			name: "underscore runs into handle",
+			text: "@alice.test_suffix",
+		},
+		{
+			name: "bare at sign",
+			text: "meet @ noon @",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMentions(tt.text)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d mentions, got %d: %+v", len(tt.want), len(got), got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Mention %d: expected %+v, got %+v", i, tt.want[i], got[i])
+				}
+				if tt.text[got[i].ByteStart] != '@' {
+					t.Errorf("Mention %d: byteStart does not point at '@'", i)
+				}
+			}
+		})
+	}
+}
+
+func TestUniqueMentionHandles_DedupesAndCaps(t *testing.T) {
+	var spans []mentionSpan
+	for i := 0; i < 15; i++ {
+		spans = append(spans, mentionSpan{Handle: fmt.Sprintf("user%d.test", i)})
+		spans = append(spans, mentionSpan{Handle: "user0.test"})
+	}
+
+	handles := uniqueMentionHandles(spans, MaxMentionsPerComment)
+	if len(handles) != MaxMentionsPerComment {
+		t.Fatalf("Expected %d handles, got %d", MaxMentionsPerComment, len(handles))
+	}
+	if handles[0] != "user0.test" || handles[1] != "user1.test" {
+		t.Errorf("Expected document order to be preserved, got %v", handles[:2])
+	}
+}
+
+// mockMentionIdentityResolver resolves handles from a fixed map and counts lookups
+type mockMentionIdentityResolver struct {
+	handles     map[string]string
+	calls       map[string]int
+	mu          sync.Mutex
+	inFlight    int32
+	maxInFlight int32
+}
+
+func (m *mockMentionIdentityResolver) Resolve(ctx context.Context, identifier string) (*identity.Identity, error) {
+	current := atomic.AddInt32(&m.inFlight, 1)
+	defer atomic.AddInt32(&m.inFlight, -1)
+	for {
+		max := atomic.LoadInt32(&m.maxInFlight)
+		if current <= max || atomic.CompareAndSwapInt32(&m.maxInFlight, max, current) {
+			break
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	m.mu.Lock()
+	m.calls[identifier]++
+	m.mu.Unlock()
+
+	did, ok := m.handles[identifier]
+	if !ok {
+		return nil, &identity.ErrNotFound{Identifier: identifier}
+	}
+	return &identity.Identity{DID: did, Handle: identifier}, nil
+}
+
+func TestMentionResolver_NegativeCacheAndConcurrency(t *testing.T) {
+	resolver := &mockMentionIdentityResolver{
+		handles: map[string]string{"alice.test": "did:plc:alice"},
+		calls:   make(map[string]int),
+	}
+	m := newMentionResolver(resolver)
+
+	handles := []string{"alice.test"}
+	for i := 0; i < 8; i++ {
+		handles = append(handles, fmt.Sprintf("ghost%d.test", i))
+	}
+
+	resolved := m.ResolveHandles(context.Background(), handles)
+	if len(resolved) != 1 || resolved["alice.test"] != "did:plc:alice" {
+		t.Fatalf("Expected only alice.test to resolve, got %v", resolved)
+	}
+	if resolver.maxInFlight > mentionResolveConcurrency {
+		t.Errorf("Expected at most %d concurrent resolutions, got %d", mentionResolveConcurrency, resolver.maxInFlight)
+	}
+
+	// Unresolvable handles are served from the negative cache on the next comment
+	m.ResolveHandles(context.Background(), handles)
+	if resolver.calls["ghost0.test"] != 1 {
+		t.Errorf("Expected unresolvable handle to be looked up once, got %d", resolver.calls["ghost0.test"])
+	}
+	if resolver.calls["alice.test"] != 2 {
+		t.Errorf("Expected resolvable handle to be looked up again, got %d", resolver.calls["alice.test"])
+	}
+
+	// Expired negative entries are retried
+	m.negativeCache.Add("ghost0.test", time.Now().Add(-time.Second))
+	m.ResolveHandles(context.Background(), []string{"ghost0.test"})
+	if resolver.calls["ghost0.test"] != 2 {
+		t.Errorf("Expected expired negative entry to be retried, got %d lookups", resolver.calls["ghost0.test"])
+	}
+}
+
+// failingIdentityResolver always fails with a transient error
+type failingIdentityResolver struct{}
+
+func (failingIdentityResolver) Resolve(ctx context.Context, identifier string) (*identity.Identity, error) {
+	return nil, errors.New("dns timeout")
+}
+
+func TestMentionResolver_TransientFailuresUseShortTTL(t *testing.T) {
+	m := newMentionResolver(failingIdentityResolver{})
+	m.ResolveHandles(context.Background(), []string{"alice.test"})
+
+	expiresAt, ok := m.negativeCache.Get("alice.test")
+	if !ok {
+		t.Fatal("Expected transient failure to be negatively cached")
+	}
+	if time.Until(expiresAt) > mentionFailureTTL {
+		t.Errorf("Expected transient failure TTL <= %v, got %v", mentionFailureTTL, time.Until(expiresAt))
+	}
+}
+
+func TestResolveCommentMentions_UnresolvableHandlesProduceNoFacets(t *testing.T) {
+	resolver := &mockMentionIdentityResolver{
+		handles: map[string]string{"alice.test": "did:plc:alice"},
+		calls:   make(map[string]int),
+	}
+	c := NewCommentEventConsumer(nil, nil)
+
+	// Disabled until an identity resolver is configured
+	if got := c.resolveCommentMentions(context.Background(), "hi @alice.test"); got != nil {
+		t.Fatalf("Expected no mentions without a resolver, got %+v", got)
+	}
+
+	c.SetIdentityResolver(resolver)
+
+	if got := c.resolveCommentMentions(context.Background(), "hi @nobody.test"); got != nil {
+		t.Errorf("Expected unresolvable mention to be dropped, got %+v", got)
+	}
+
+	got := c.resolveCommentMentions(context.Background(), "@alice.test and @nobody.test and @ALICE.test")
+	if got == nil {
+		t.Fatal("Expected resolved mentions")
+	}
+	if len(got.DIDs) != 1 || got.DIDs[0] != "did:plc:alice" {
+		t.Errorf("Expected a single deduped DID, got %v", got.DIDs)
+	}
+	if len(got.Facets) != 2 {
+		t.Fatalf("Expected a facet per resolved occurrence, got %d", len(got.Facets))
+	}
+	start, end, ok := facetByteRange(got.Facets[1])
+	if !ok || start != 33 || end != 44 {
+		t.Errorf("Expected second facet at [33,44), got [%d,%d)", start, end)
+	}
+}
+
+func TestMergeMentionFacets_KeepsRecordFacets(t *testing.T) {
+	recordFacets := []interface{}{
+		map[string]interface{}{
+			"index":    map[string]interface{}{"byteStart": float64(20), "byteEnd": float64(40)},
+			"features": []interface{}{map[string]interface{}{"$type": "social.coves.richtext.facet#link", "uri": "https://example.com"}},
+		},
+		map[string]interface{}{
+			"index":    map[string]interface{}{"byteStart": float64(0), "byteEnd": float64(9)},
+			"features": []interface{}{map[string]interface{}{"$type": mentionFeatureType, "did": "did:plc:client"}},
+		},
+	}
+	mentionFacets := []interface{}{
+		map[string]interface{}{"index": map[string]interface{}{"byteStart": 0, "byteEnd": 9}},
+		map[string]interface{}{"index": map[string]interface{}{"byteStart": 10, "byteEnd": 19}},
+	}
+
+	merged := mergeMentionFacets(recordFacets, mentionFacets)
+	if len(merged) != 3 {
+		t.Fatalf("Expected overlapping mention to be skipped, got %d facets", len(merged))
+	}
+
+	var starts []int
+	for _, facet := range merged {
+		start, _, _ := facetByteRange(facet)
+		starts = append(starts, start)
+	}
+	if starts[0] != 0 || starts[1] != 10 || starts[2] != 20 {
+		t.Errorf("Expected facets sorted by byteStart, got %v", starts)
+	}
+}
+
+func TestSanitizeContentFacets_RemapsByteRanges(t *testing.T) {
+	// The override and NUL before the link shift it back 4 bytes; the facet covering only
+	// the override collapses and is dropped
+	content := "see \u202e\x00https://example.com"
+	linkStart := len("see \u202e\x00")
+	facets := []interface{}{
+		map[string]interface{}{
+			"index":    map[string]interface{}{"byteStart": float64(linkStart), "byteEnd": float64(len(content))},
+			"features": []interface{}{map[string]interface{}{"$type": "social.coves.richtext.facet#link", "uri": "https://example.com"}},
+		},
+		map[string]interface{}{
+			"index": map[string]interface{}{"byteStart": float64(4), "byteEnd": float64(7)},
+		},
+	}
+
+	sanitized, remapped := sanitizeContentFacets(content, facets)
+	if sanitized != "see https://example.com" {
+		t.Fatalf("Unexpected sanitized content %q", sanitized)
+	}
+	if len(remapped) != 1 {
+		t.Fatalf("Expected the collapsed facet to be dropped, got %d facets", len(remapped))
+	}
+	start, end, ok := facetByteRange(remapped[0])
+	if !ok || sanitized[start:end] != "https://example.com" {
+		t.Errorf("Remapped facet [%d,%d) doesn't cover the link in %q", start, end, sanitized)
+	}
+	if orig, _, _ := facetByteRange(facets[0]); orig != linkStart {
+		t.Error("Original facets must not be modified")
+	}
+}
+
+func TestParseRecords_SanitizeText(t *testing.T) {
+	post, err := parsePostRecord(map[string]interface{}{
+		"$type":     PostCollection,
+		"community": "did:plc:community",
+		"author":    "did:plc:author",
+		"createdAt": "2025-01-01T00:00:00Z",
+		"title":     "Official \u202eannouncement\x07",
+		"content":   "cafe\u0301",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if *post.Title != "Official announcement" || *post.Content != "caf\u00e9" {
+		t.Errorf("Post text not sanitized: title %q, content %q", *post.Title, *post.Content)
+	}
+
+	err = checkRecordSchema(&CommitEvent{
+		Collection: CommentCollection,
+		Record: map[string]interface{}{
+			"$type":     CommentCollection,
+			"content":   "\u2066\x00\u2069",
+			"createdAt": "2025-01-01T00:00:00Z",
+		},
+	})
+	var recordErr *validate.Error
+	if !errors.As(err, &recordErr) || recordErr.Violations[0].Field != "content" || recordErr.Violations[0].Code != validate.CodeRequired {
+		t.Errorf("Expected a comment that is empty after sanitizing to be rejected, got: %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/communities"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+// PostCollection is the lexicon collection identifier for posts
+const PostCollection = "social.coves.community.post"
+
+// PostFetcher fetches a post record from the community repository that owns it
+// Implementations return an error wrapping pds.ErrNotFound when the record doesn't exist
+type PostFetcher interface {
+	FetchPost(ctx context.Context, postURI string) (*pds.RecordResponse, error)
+}
+
+// pdsPostFetcher fetches posts with com.atproto.repo.getRecord on the community's PDS
+type pdsPostFetcher struct {
+	communityRepo communities.Repository
+}
+
+// NewPDSPostFetcher creates a PostFetcher that reads from the PDS of the indexed community
+// Posts can only be indexed for known communities, so the PDS URL comes from the communities table
+func NewPDSPostFetcher(communityRepo communities.Repository) PostFetcher {
+	return &pdsPostFetcher{communityRepo: communityRepo}
+}
+
+// FetchPost fetches a post record from its community's PDS
+func (f *pdsPostFetcher) FetchPost(ctx context.Context, postURI string) (*pds.RecordResponse, error) {
+	communityDID, rkey, err := parsePostURI(postURI)
+	if err != nil {
+		return nil, err
+	}
+
+	community, err := f.communityRepo.GetByDID(ctx, communityDID)
+	if err != nil {
+		if communities.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: community %s is not indexed", pds.ErrNotFound, communityDID)
+		}
+		return nil, fmt.Errorf("failed to look up community: %w", err)
+	}
+	if community.PDSURL == "" {
+		return nil, fmt.Errorf("community %s has no PDS URL", communityDID)
+	}
+
+	client, err := pds.NewPublicClient(community.PDSURL, communityDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PDS client: %w", err)
+	}
+
+	return client.GetRecord(ctx, PostCollection, rkey)
+}
+
+// parsePostURI returns the community DID and rkey of a post AT-URI
+func parsePostURI(uri string) (string, string, error) {
+	aturi, err := syntax.ParseATURI(uri)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid post URI %q: %w", uri, err)
+	}
+	if aturi.Collection().String() != PostCollection {
+		return "", "", fmt.Errorf("not a post URI: %s", uri)
+	}
+	did, err := aturi.Authority().AsDID()
+	if err != nil {
+		return "", "", fmt.Errorf("post URI authority must be a DID: %s", uri)
+	}
+	return did.String(), aturi.RecordKey().String(), nil
+}
+
+// SetPostBackfill enables root post backfill: comments on a post the firehose never delivered
+// fetch it with fetcher and index it through postConsumer. Without it, such comments are
+// stored as orphaned until the post arrives.
+// Orphans postConsumer makes visible when it indexes their post count toward this consumer's metrics.
+func (c *CommentEventConsumer) SetPostBackfill(fetcher PostFetcher, postConsumer *PostEventConsumer) {
+	c.postFetcher = fetcher
+	c.postConsumer = postConsumer
+	postConsumer.orphansResolved = &c.metrics.orphansResolved
+}
+
+// ensureRootPost reports whether a comment's root post is indexed, backfilling it from the
+// community's PDS when it isn't. false means the PDS doesn't have the post and the comment
+// should be stored as orphaned; any other failure is returned so the comment isn't hidden
+// because of an outage.
+func (c *CommentEventConsumer) ensureRootPost(ctx context.Context, rootURI string) (bool, error) {
+	var exists bool
+	if err := c.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE uri = $1)`, rootURI).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check root post: %w", err)
+	}
+	if exists {
+		return true, nil
+	}
+
+	if c.postFetcher == nil || c.postConsumer == nil {
+		return false, nil
+	}
+
+	record, err := c.postFetcher.FetchPost(ctx, rootURI)
+	if err != nil {
+		if errors.Is(err, pds.ErrNotFound) {
+			log.Printf("Root post not found on PDS: %s", rootURI)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to fetch root post %s: %w", rootURI, err)
+	}
+
+	if err := c.postConsumer.BackfillPost(ctx, rootURI, record); err != nil {
+		return false, fmt.Errorf("failed to backfill root post %s: %w", rootURI, err)
+	}
+
+	log.Printf("✓ Backfilled root post from PDS: %s", rootURI)
+	return true, nil
+}
+
+// ResolveOrphanedComments retries root post resolution for orphaned comments
+// Checks up to limit root posts, least recently checked first, and returns how many were resolved.
+// Run periodically: posts can be backfilled once their community or author is indexed.
+func (c *CommentEventConsumer) ResolveOrphanedComments(ctx context.Context, limit int) (int, error) {
+	rows, err := c.db.QueryContext(ctx, `
+		SELECT root_uri
+		FROM comments
+		WHERE orphaned = TRUE
+		GROUP BY root_uri
+		ORDER BY MIN(orphan_checked_at) NULLS FIRST
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list orphaned comments: %w", err)
+	}
+	var rootURIs []string
+	for rows.Next() {
+		var rootURI string
+		if err := rows.Scan(&rootURI); err != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("failed to scan orphaned root: %w", err)
+		}
+		rootURIs = append(rootURIs, rootURI)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, fmt.Errorf("failed to list orphaned comments: %w", err)
+	}
+
+	resolved := 0
+	for _, rootURI := range rootURIs {
+		ok, err := c.ensureRootPost(ctx, rootURI)
+		if err != nil {
+			if ctx.Err() != nil {
+				return resolved, ctx.Err()
+			}
+			// Checked like a missing post, so one failing root doesn't hold up the rest
+			log.Printf("Warning: Failed to resolve orphaned comments on %s: %v", rootURI, err)
+		}
+		if !ok {
+			if _, err := c.db.ExecContext(ctx, `
+				UPDATE comments SET orphan_checked_at = NOW()
+				WHERE root_uri = $1 AND orphaned = TRUE
+			`, rootURI); err != nil {
+				return resolved, fmt.Errorf("failed to record orphan check: %w", err)
+			}
+			continue
+		}
+
+		// Indexing the post already clears the flag; this covers posts indexed by the
+		// firehose between the comment's root check and its insert
+		cleared, err := clearOrphanedComments(ctx, c.db, rootURI)
+		if err != nil {
+			return resolved, err
+		}
+		c.metrics.orphansResolved.Add(cleared)
+		resolved++
+	}
+
+	return resolved, nil
+}
+
+// clearOrphanedComments makes comments on a newly indexed post visible again
+// Returns how many comments were orphaned.
+func clearOrphanedComments(ctx context.Context, db execer, rootURI string) (int64, error) {
+	result, err := db.ExecContext(ctx, `
+		UPDATE comments SET orphaned = FALSE, orphan_checked_at = NULL
+		WHERE root_uri = $1 AND orphaned = TRUE
+	`, rootURI)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear orphaned comments: %w", err)
+	}
+	cleared, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check cleared orphaned comments: %w", err)
+	}
+	return cleared, nil
+}
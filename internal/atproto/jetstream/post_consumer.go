@@ -1,16 +1,25 @@
 package jetstream
 
 import (
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/automod"
 	"Coves/internal/core/communities"
+	"Coves/internal/core/hotrank"
+	"Coves/internal/core/live"
 	"Coves/internal/core/posts"
+	"Coves/internal/core/spamguard"
 	"Coves/internal/core/users"
+	"Coves/internal/lexicon/validate"
+	"Coves/internal/validation/text"
 	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log"
-	"strings"
+	"sync/atomic"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // PostEventConsumer consumes post-related events from Jetstream
@@ -20,7 +29,25 @@ type PostEventConsumer struct {
 	postRepo      posts.Repository
 	communityRepo communities.Repository
 	userService   users.UserService
-	db            *sql.DB // Direct DB access for atomic count reconciliation
+	dlq           DeadLetterQueue      // Optional - rejected events are only logged when nil
+	publisher     live.Publisher       // Optional - new posts aren't streamed to clients when nil
+	spamGuard     spamguard.Checker    // Optional - posts aren't screened for spam when nil
+	automod       AutomodEvaluator     // Optional - community automod rules aren't applied when nil
+	activity      ActivityRecorder     // Optional - authors aren't counted as community actives when nil
+	authors       *AuthorIndexer       // Optional - posts by unknown authors are rejected when nil
+	blobRefs      BlobReferenceTracker // Optional - referenced blobs aren't tracked for the image proxy when nil
+	db            *sql.DB              // Direct DB access for atomic count reconciliation
+
+	// postsWithoutAltText counts image posts indexed with at least one image missing alt text
+	// (communities without postingRules.requireAltText); exposed for accessibility metrics
+	postsWithoutAltText atomic.Int64
+
+	// postsQuarantined counts posts held for spam review at index time
+	postsQuarantined atomic.Int64
+
+	// orphansResolved counts orphaned comments made visible by indexing their post
+	// Optional - set by CommentEventConsumer.SetPostBackfill to the comment consumer's counter
+	orphansResolved *atomic.Int64
 }
 
 // NewPostEventConsumer creates a new Jetstream consumer for post events
@@ -38,6 +65,37 @@ func NewPostEventConsumer(
 	}
 }
 
+// SetDeadLetterQueue configures where rejected events (mismatched $type, oversized records) are stored
+func (c *PostEventConsumer) SetDeadLetterQueue(dlq DeadLetterQueue) {
+	c.dlq = dlq
+}
+
+// SetAuthorIndexer enables on-demand indexing of post authors the user consumer hasn't seen
+func (c *PostEventConsumer) SetAuthorIndexer(authors *AuthorIndexer) {
+	c.authors = authors
+}
+
+// SetPublisher configures where newly indexed posts are announced to live clients
+func (c *PostEventConsumer) SetPublisher(publisher live.Publisher) {
+	c.publisher = publisher
+}
+
+// SetSpamGuard configures the spam heuristics new posts are screened with
+func (c *PostEventConsumer) SetSpamGuard(guard spamguard.Checker) {
+	c.spamGuard = guard
+}
+
+// PostsIndexedWithoutAltText returns how many image posts were indexed with missing alt text
+// since the process started
+func (c *PostEventConsumer) PostsIndexedWithoutAltText() int64 {
+	return c.postsWithoutAltText.Load()
+}
+
+// PostsQuarantined returns how many posts were quarantined for spam review since the process started
+func (c *PostEventConsumer) PostsQuarantined() int64 {
+	return c.postsQuarantined.Load()
+}
+
 // HandleEvent processes a Jetstream event for post records
 // Handles CREATE and DELETE operations - UPDATE deferred until that feature exists
 func (c *PostEventConsumer) HandleEvent(ctx context.Context, event *JetstreamEvent) error {
@@ -50,6 +108,10 @@ func (c *PostEventConsumer) HandleEvent(ctx context.Context, event *JetstreamEve
 
 	// Handle post record operations
 	if commit.Collection == "social.coves.community.post" {
+		if rejected, err := guardRecord(ctx, c.dlq, event); rejected {
+			return err
+		}
+
 		switch commit.Operation {
 		case "create":
 			return c.createPost(ctx, event.Did, commit)
@@ -75,11 +137,28 @@ func (c *PostEventConsumer) createPost(ctx context.Context, repoDID string, comm
 	}
 
 	// SECURITY: Validate this is a legitimate post event
-	if err := c.validatePostEvent(ctx, repoDID, postRecord); err != nil {
+	community, err := c.validatePostEvent(ctx, repoDID, postRecord)
+	if err != nil {
 		log.Printf("🚨 SECURITY: Rejecting post event: %v", err)
 		return err
 	}
 
+	// Keep only tags from the community's flair set (unknown tags are dropped, not rejected)
+	tags := community.FilterPostTags(postRecord.Tags)
+	if dropped := len(postRecord.Tags) - len(tags); dropped > 0 {
+		log.Printf("Dropped %d unknown or duplicate tag(s) from post %s/%s", dropped, repoDID, commit.RKey)
+	}
+
+	// Image posts need alt text: rejected in communities that require it, counted otherwise
+	posts.NormalizeImageAlt(postRecord.Embed)
+	missingAlt := posts.CountImagesMissingAlt(postRecord.Embed)
+	if missingAlt > 0 && community.PostingRules.RequireAltText {
+		log.Printf("Rejecting post %s/%s: %d image(s) missing alt text (community requires alt text)",
+			repoDID, commit.RKey, missingAlt)
+		return posts.NewContentRuleViolation("requireAltText",
+			fmt.Sprintf("%d image(s) missing alt text", missingAlt))
+	}
+
 	// Build AT-URI for this post
 	// Format: at://community_did/social.coves.community.post/rkey
 	uri := fmt.Sprintf("at://%s/social.coves.community.post/%s", repoDID, commit.RKey)
@@ -103,6 +182,8 @@ func (c *PostEventConsumer) createPost(ctx context.Context, repoDID string, comm
 		Content:      postRecord.Content,
 		CreatedAt:    createdAt,
 		IndexedAt:    time.Now(),
+		RawRecord:    marshalRawRecord(commit.Record),
+		Tags:         tags,
 		// Stats remain at 0 (no votes yet)
 		UpvoteCount:   0,
 		DownvoteCount: 0,
@@ -127,24 +208,154 @@ func (c *PostEventConsumer) createPost(ctx context.Context, repoDID string, comm
 		}
 	}
 
+	// Hash the linked page so posts about it can be found however the link was spelled
+	if link := posts.ExternalEmbedURI(postRecord.Embed); link != "" {
+		if hash, hashErr := posts.LinkHash(link); hashErr == nil {
+			post.LinkHash = &hash
+		}
+	}
+
 	if postRecord.Labels != nil {
 		labelsJSON, marshalErr := json.Marshal(postRecord.Labels)
 		if marshalErr == nil {
 			labelsStr := string(labelsJSON)
 			post.ContentLabels = &labelsStr
 		}
+		// Index the labels feeds filter on; unknown and negated values stay in the JSON only
+		post.Labels = posts.IndexedLabels(postRecord.Labels)
+	}
+
+	// Records without langs get a detected language, reported as the post's language in feeds
+	var text []string
+	for _, field := range []*string{post.Title, post.Content} {
+		if field != nil {
+			text = append(text, *field)
+		}
+	}
+	post.DetectedLang, post.LangConfidence = detectLanguage(postRecord.Langs, text...)
+
+	// Spam waves: suspicious posts are indexed but quarantined (hidden) until an admin reviews them
+	c.screenForSpam(ctx, post)
+
+	// Community automod rules: removed and held posts are indexed hidden, flagged posts as normal
+	var automodMatch *automod.Match
+	if post.Status == "" {
+		automodMatch = evaluateAutomod(ctx, c.automod, post.CommunityDID, uri,
+			automodContent(text, postRecord.Facets, postRecord.Embed))
+	}
+	if automodMatch != nil {
+		switch automodMatch.Action() {
+		case automod.ActionRemove:
+			post.Status = posts.StatusRemoved
+		case automod.ActionHold:
+			post.Status = posts.StatusHeld
+		}
 	}
 
 	// Atomically: Index post + Reconcile comment count for out-of-order arrivals
-	if err := c.indexPostAndReconcileCounts(ctx, post); err != nil {
+	inserted, err := c.indexPostAndReconcileCounts(ctx, post)
+	if err != nil {
 		return fmt.Errorf("failed to index post and reconcile counts: %w", err)
 	}
+	trackBlobReferences(ctx, c.blobRefs, uri, commit.Record)
+
+	if missingAlt > 0 && inserted {
+		c.postsWithoutAltText.Add(1)
+		log.Printf("Warning: Indexed post %s with %d image(s) missing alt text", uri, missingAlt)
+	}
+
+	if post.Status == posts.StatusQuarantined && inserted {
+		c.postsQuarantined.Add(1)
+		log.Printf("Warning: Quarantined post %s for spam review (author: %s, signals: %v)",
+			uri, post.AuthorDID, post.QuarantineReasons)
+		return nil
+	}
+
+	if automodMatch != nil && inserted {
+		recordAutomodAction(ctx, c.automod, automodMatch, uri, post.CommunityDID, post.AuthorDID)
+	}
+	if post.Status != "" && post.Status != posts.StatusActive {
+		return nil
+	}
+
+	// Replays of an already indexed post aren't announced or counted again
+	if inserted {
+		recordCommunityActivity(ctx, c.activity, post.CommunityDID, post.AuthorDID)
+	}
+	if inserted && c.publisher != nil {
+		c.publisher.Publish(live.CommunityTopic(post.CommunityDID), live.Event{Type: live.EventPost, URI: uri})
+	}
 
 	log.Printf("✓ Indexed post: %s (author: %s, community: %s, rkey: %s)",
 		uri, post.AuthorDID, post.CommunityDID, commit.RKey)
 	return nil
 }
 
+// screenForSpam runs the spam guard on a post about to be indexed, marking it quarantined
+// when it scores too high
+// A failing check never blocks indexing; the post is indexed as normal.
+func (c *PostEventConsumer) screenForSpam(ctx context.Context, post *posts.Post) {
+	if c.spamGuard == nil {
+		return
+	}
+
+	candidate := spamguard.Post{
+		URI:          post.URI,
+		AuthorDID:    post.AuthorDID,
+		CommunityDID: post.CommunityDID,
+		CreatedAt:    post.CreatedAt,
+	}
+	if post.Title != nil {
+		candidate.Title = *post.Title
+	}
+	if post.Content != nil {
+		candidate.Content = *post.Content
+	}
+	if post.LinkHash != nil {
+		candidate.LinkHash = *post.LinkHash
+	}
+
+	verdict, err := c.spamGuard.Check(ctx, candidate)
+	if err != nil {
+		log.Printf("Warning: Spam check failed for %s, indexing without it: %v", post.URI, err)
+		return
+	}
+	if verdict.Quarantine {
+		post.Status = posts.StatusQuarantined
+		post.QuarantineReasons = verdict.Reasons
+	}
+}
+
+// BackfillPost indexes a post record fetched from its community's PDS
+// Used when a comment references a post the firehose never delivered. The record goes
+// through the same type check, lexicon validation and security validation as a firehose create.
+func (c *PostEventConsumer) BackfillPost(ctx context.Context, uri string, record *pds.RecordResponse) error {
+	if record == nil {
+		return fmt.Errorf("post backfill missing record data")
+	}
+
+	communityDID, rkey, err := parsePostURI(uri)
+	if err != nil {
+		return err
+	}
+
+	commit := &CommitEvent{
+		Operation:  "create",
+		Collection: PostCollection,
+		RKey:       rkey,
+		CID:        record.CID,
+		Record:     record.Value,
+	}
+	if err := checkRecordType(commit); err != nil {
+		return err
+	}
+	if err := checkRecordSchema(commit); err != nil {
+		return err
+	}
+
+	return c.createPost(ctx, communityDID, commit)
+}
+
 // deletePost handles post deletion events from Jetstream
 // Soft-deletes the post in AppView database by setting deleted_at timestamp
 func (c *PostEventConsumer) deletePost(ctx context.Context, repoDID string, commit *CommitEvent) error {
@@ -156,6 +367,7 @@ func (c *PostEventConsumer) deletePost(ctx context.Context, repoDID string, comm
 	if err := c.postRepo.SoftDelete(ctx, uri); err != nil {
 		return fmt.Errorf("failed to soft delete post: %w", err)
 	}
+	refreshBlobReferences(ctx, c.blobRefs, uri)
 
 	log.Printf("✓ Deleted post: %s (community: %s, rkey: %s)", uri, repoDID, commit.RKey)
 	return nil
@@ -163,10 +375,11 @@ func (c *PostEventConsumer) deletePost(ctx context.Context, repoDID string, comm
 
 // indexPostAndReconcileCounts atomically indexes a post and reconciles comment counts
 // This fixes the race condition where comments arrive before their parent post
-func (c *PostEventConsumer) indexPostAndReconcileCounts(ctx context.Context, post *posts.Post) error {
+// Returns false when the post was already indexed (Jetstream replay)
+func (c *PostEventConsumer) indexPostAndReconcileCounts(ctx context.Context, post *posts.Post) (bool, error) {
 	tx, err := c.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
@@ -192,15 +405,36 @@ func (c *PostEventConsumer) indexPostAndReconcileCounts(ctx context.Context, pos
 		labelsJSON.Valid = true
 	}
 
+	status := post.Status
+	if status == "" {
+		status = posts.StatusActive
+	}
+
+	// New posts rank in hot feeds before the next ranker run, aged against the ranker's current
+	// epoch so their score compares with the rest of the page
+	var epoch sql.NullTime
+	if err := tx.QueryRowContext(ctx, `SELECT scored_at FROM hot_rank_epoch`).Scan(&epoch); err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to read hot rank epoch: %w", err)
+	}
+	scoredAt := time.Now()
+	if epoch.Valid {
+		scoredAt = epoch.Time
+	}
+
+	// Quarantined, held and removed posts are hidden from feeds the same way moderators remove content
 	insertQuery := `
 		INSERT INTO posts (
 			uri, cid, rkey, author_did, community_did,
 			title, content, content_facets, embed, content_labels,
-			created_at, indexed_at
+			created_at, indexed_at, raw_record, tags, normalized_url_hash, labels,
+			status, quarantine_reasons, visibility_state, hot_score,
+			detected_lang, detected_lang_confidence
 		) VALUES (
 			$1, $2, $3, $4, $5,
 			$6, $7, $8, $9, $10,
-			$11, NOW()
+			$11, NOW(), $12, $13, $14, $15,
+			$16, $17, CASE WHEN $16 <> 'active' THEN 'removed' ELSE 'visible' END::content_visibility_state, $18,
+			$19, $20
 		)
 		ON CONFLICT (uri) DO NOTHING
 		RETURNING id
@@ -211,51 +445,96 @@ func (c *PostEventConsumer) indexPostAndReconcileCounts(ctx context.Context, pos
 		ctx, insertQuery,
 		post.URI, post.CID, post.RKey, post.AuthorDID, post.CommunityDID,
 		post.Title, post.Content, facetsJSON, embedJSON, labelsJSON,
-		post.CreatedAt,
+		post.CreatedAt, post.RawRecord, pq.Array(nonNilTags(post.Tags)), post.LinkHash,
+		pq.Array(nonNilTags(post.Labels)),
+		status, pq.Array(post.QuarantineReasons),
+		hotrank.Score(0, post.CreatedAt, scoredAt),
+		post.DetectedLang, post.LangConfidence,
 	).Scan(&postID)
 
 	// If no rows returned, post already exists (idempotent - OK for Jetstream replays)
 	if insertErr == sql.ErrNoRows {
 		log.Printf("Post already indexed: %s (idempotent)", post.URI)
 		if commitErr := tx.Commit(); commitErr != nil {
-			return fmt.Errorf("failed to commit transaction: %w", commitErr)
+			return false, fmt.Errorf("failed to commit transaction: %w", commitErr)
 		}
-		return nil
+		return false, nil
 	}
 
 	if insertErr != nil {
-		return fmt.Errorf("failed to insert post: %w", insertErr)
+		return false, fmt.Errorf("failed to insert post: %w", insertErr)
 	}
 
-	// 2. Reconcile comment_count for this newly inserted post
+	// 2. Reconcile comment counts and activity for this newly inserted post
 	// In case any comments arrived out-of-order before this post was indexed
 	// This is the CRITICAL FIX for the race condition identified in the PR review
 	reconcileQuery := `
 		UPDATE posts
-		SET comment_count = (
-			SELECT COUNT(*)
+		SET comment_count = agg.total,
+			top_level_comment_count = agg.top_level,
+			last_activity_at = agg.last_activity
+		FROM (
+			SELECT
+				COUNT(*) FILTER (WHERE c.deleted_at IS NULL AND c.status = 'active') AS total,
+				COUNT(*) FILTER (WHERE c.deleted_at IS NULL AND c.status = 'active' AND c.parent_uri = $1) AS top_level,
+				LEAST(MAX(c.created_at), NOW()) AS last_activity
 			FROM comments c
-			WHERE c.parent_uri = $1 AND c.deleted_at IS NULL
-		)
+			WHERE c.root_uri = $1
+		) agg
 		WHERE id = $2
 	`
 	_, reconcileErr := tx.ExecContext(ctx, reconcileQuery, post.URI, postID)
 	if reconcileErr != nil {
-		log.Printf("Warning: Failed to reconcile comment_count for %s: %v", post.URI, reconcileErr)
+		log.Printf("Warning: Failed to reconcile comment counts for %s: %v", post.URI, reconcileErr)
 		// Continue anyway - this is a best-effort reconciliation
 	}
 
+	// 3. Comments that arrived before this post were stored as orphaned; make them visible
+	resolvedOrphans, err := clearOrphanedComments(ctx, tx, post.URI)
+	if err != nil {
+		return false, err
+	}
+
+	// 4. Count the post toward its community
+	// Only reached when the insert added a row, so a replayed create doesn't count twice.
+	// Hidden posts count too: the count tracks live rows, which status changes don't affect
+	if _, countErr := tx.ExecContext(ctx, `UPDATE communities SET post_count = post_count + 1 WHERE did = $1`, post.CommunityDID); countErr != nil {
+		return false, fmt.Errorf("failed to update community post count: %w", countErr)
+	}
+
 	// Commit transaction
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return nil
+	// 5. Bump the community's last_post_at (drives "recentActivity" subscription sorting)
+	// createdAt is author-supplied, so clamp to NOW() to stop future timestamps pinning a community to the top
+	// GREATEST keeps the column monotonic when older posts are replayed out of order
+	// Hidden posts don't count as activity: spam shouldn't float a community to the top
+	// Runs after the commit: a failed statement would abort the transaction, and a stale sort
+	// key isn't worth dropping the post over
+	if status == posts.StatusActive {
+		activityQuery := `
+			UPDATE communities
+			SET last_post_at = GREATEST(COALESCE(last_post_at, '-infinity'::timestamptz), LEAST($2::timestamptz, NOW()))
+			WHERE did = $1
+		`
+		if _, activityErr := c.db.ExecContext(ctx, activityQuery, post.CommunityDID, post.CreatedAt); activityErr != nil {
+			log.Printf("Warning: Failed to update last_post_at of community %s: %v", post.CommunityDID, activityErr)
+			// Continue anyway - the post is indexed and the next one bumps it
+		}
+	}
+
+	if c.orphansResolved != nil {
+		c.orphansResolved.Add(resolvedOrphans)
+	}
+	return true, nil
 }
 
 // validatePostEvent performs security validation on post events
 // This prevents malicious actors from indexing fake posts
-func (c *PostEventConsumer) validatePostEvent(ctx context.Context, repoDID string, post *PostRecordFromJetstream) error {
+// Returns the post's community so callers can validate community-scoped fields (tags)
+func (c *PostEventConsumer) validatePostEvent(ctx context.Context, repoDID string, post *PostRecordFromJetstream) (*communities.Community, error) {
 	// CRITICAL SECURITY CHECK:
 	// Posts MUST come from community repositories, not user repositories
 	// This prevents users from creating posts that appear to be from communities they don't control
@@ -269,7 +548,7 @@ func (c *PostEventConsumer) validatePostEvent(ctx context.Context, repoDID strin
 	//   - We verify event.Did (repo owner) == post.community (claimed community)
 	//   - Reject if mismatch
 	if repoDID != post.Community {
-		return fmt.Errorf("repository DID (%s) doesn't match community DID (%s) - posts must come from community repos",
+		return nil, fmt.Errorf("repository DID (%s) doesn't match community DID (%s) - posts must come from community repos",
 			repoDID, post.Community)
 	}
 
@@ -277,35 +556,39 @@ func (c *PostEventConsumer) validatePostEvent(ctx context.Context, repoDID strin
 	// Posts MUST reference valid communities (enforced by FK constraint)
 	// If community isn't indexed yet, we must reject the post
 	// Jetstream will replay events, so the post will be indexed once community is ready
-	_, err := c.communityRepo.GetByDID(ctx, post.Community)
+	community, err := c.communityRepo.GetByDID(ctx, post.Community)
 	if err != nil {
 		if communities.IsNotFound(err) {
 			// Reject - community must be indexed before posts
 			// This maintains referential integrity and prevents orphaned posts
-			return fmt.Errorf("community not found: %s - cannot index post before community", post.Community)
+			return nil, fmt.Errorf("cannot index post before community %s: %w", post.Community, err)
 		}
 		// Database error or other issue
-		return fmt.Errorf("failed to verify community exists: %w", err)
+		return nil, fmt.Errorf("failed to verify community exists: %w", err)
 	}
 
 	// CRITICAL: Verify author exists in AppView
 	// Every post MUST have a valid author (enforced by FK constraint)
 	// Even though posts live in community repos, they belong to specific authors
-	// If author isn't indexed yet, we must reject the post
+	// If author isn't indexed yet, index them now or reject the post
 	_, err = c.userService.GetUserByDID(ctx, post.Author)
 	if err != nil {
-		// Check if it's a "not found" error using string matching
-		// (users package doesn't export IsNotFound)
-		if err.Error() == "user not found" || strings.Contains(err.Error(), "not found") {
+		if users.IsNotFound(err) {
+			if c.authors != nil {
+				if _, indexErr := c.authors.IndexUser(ctx, post.Author); indexErr != nil {
+					return nil, fmt.Errorf("cannot index post before author %s: %w", post.Author, indexErr)
+				}
+				return community, nil
+			}
 			// Reject - author must be indexed before posts
 			// This maintains referential integrity and prevents orphaned posts
-			return fmt.Errorf("author not found: %s - cannot index post before author", post.Author)
+			return nil, fmt.Errorf("cannot index post before author %s: %w", post.Author, err)
 		}
 		// Database error or other issue
-		return fmt.Errorf("failed to verify author exists: %w", err)
+		return nil, fmt.Errorf("failed to verify author exists: %w", err)
 	}
 
-	return nil
+	return community, nil
 }
 
 // PostRecordFromJetstream represents a post record as received from Jetstream
@@ -323,6 +606,8 @@ type PostRecordFromJetstream struct {
 	Author         string                 `json:"author"`
 	CreatedAt      string                 `json:"createdAt"`
 	Facets         []interface{}          `json:"facets,omitempty"`
+	Tags           []string               `json:"tags,omitempty"`
+	Langs          []string               `json:"langs,omitempty"`
 }
 
 // parsePostRecord converts a raw Jetstream record map to a PostRecordFromJetstream
@@ -338,16 +623,33 @@ func parsePostRecord(record map[string]interface{}) (*PostRecordFromJetstream, e
 		return nil, fmt.Errorf("failed to unmarshal post record: %w", err)
 	}
 
-	// Validate required fields
-	if post.Community == "" {
-		return nil, fmt.Errorf("post record missing community field")
+	// Strip control and bidi override characters before anything reads the text
+	// The raw record is stored untouched for audit
+	if post.Title != nil {
+		title := text.Sanitize(*post.Title)
+		post.Title = &title
 	}
-	if post.Author == "" {
-		return nil, fmt.Errorf("post record missing author field")
+	if post.Content != nil {
+		content, facets := sanitizeContentFacets(*post.Content, post.Facets)
+		post.Content, post.Facets = &content, facets
 	}
-	if post.CreatedAt == "" {
-		return nil, fmt.Errorf("post record missing createdAt field")
+
+	// The lexicon validator checked the raw record; NFC normalization can grow the text,
+	// so the byte limits are enforced again on what gets indexed
+	if post.Title != nil && len(*post.Title) > validate.MaxPostTitleBytes {
+		return nil, fmt.Errorf("post title exceeds maximum length (%d bytes): got %d bytes", validate.MaxPostTitleBytes, len(*post.Title))
+	}
+	if post.Content != nil && len(*post.Content) > validate.MaxPostContentBytes {
+		return nil, fmt.Errorf("post content exceeds maximum length (%d bytes): got %d bytes", validate.MaxPostContentBytes, len(*post.Content))
 	}
 
 	return &post, nil
 }
+
+// nonNilTags returns an empty slice for nil so the NOT NULL tags and labels columns get '{}'
+func nonNilTags(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -2,7 +2,6 @@ package jetstream
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"sync"
@@ -14,6 +13,7 @@ import (
 // PostJetstreamConnector handles WebSocket connection to Jetstream for post events
 type PostJetstreamConnector struct {
 	consumer *PostEventConsumer
+	gaps     *gapDetector // Optional - records windows missed while disconnected
 	wsURL    string
 }
 
@@ -25,10 +25,19 @@ func NewPostJetstreamConnector(consumer *PostEventConsumer, wsURL string) *PostJ
 	}
 }
 
+// SetGapRecorder enables gap detection: the connection carries no replay cursor, so any time
+// longer than threshold between the connection last being up and a reconnect, including a
+// restart, is recorded as a window of posts that were never indexed
+// Must be called before Start
+func (c *PostJetstreamConnector) SetGapRecorder(recorder GapRecorder, threshold time.Duration) {
+	c.gaps = newGapDetector(recorder, "post", threshold, 0)
+}
+
 // Start begins consuming events from Jetstream
 // Runs indefinitely, reconnecting on errors
 func (c *PostJetstreamConnector) Start(ctx context.Context) error {
 	log.Printf("Starting Jetstream post consumer: %s", c.wsURL)
+	c.gaps.load(ctx)
 
 	for {
 		select {
@@ -36,7 +45,9 @@ func (c *PostJetstreamConnector) Start(ctx context.Context) error {
 			log.Println("Jetstream post consumer shutting down")
 			return ctx.Err()
 		default:
-			if err := c.connect(ctx); err != nil {
+			err := c.connect(ctx)
+			c.gaps.disconnected(ctx)
+			if err != nil {
 				log.Printf("Jetstream post connection error: %v. Retrying in 5s...", err)
 				time.Sleep(5 * time.Second)
 				continue
@@ -58,6 +69,7 @@ func (c *PostJetstreamConnector) connect(ctx context.Context) error {
 	}()
 
 	log.Println("Connected to Jetstream (post consumer)")
+	c.gaps.reconnected(ctx, 0)
 
 	// Set read deadline to detect connection issues
 	if err := conn.SetReadDeadline(time.Now().Add(60 * time.Second)); err != nil {
@@ -65,10 +77,12 @@ func (c *PostJetstreamConnector) connect(ctx context.Context) error {
 	}
 
 	// Set pong handler to keep connection alive
+	// Pongs are handled on the read loop's goroutine, so they can mark the detector alive
 	conn.SetPongHandler(func(string) error {
 		if err := conn.SetReadDeadline(time.Now().Add(60 * time.Second)); err != nil {
 			log.Printf("Failed to set read deadline in pong handler: %v", err)
 		}
+		c.gaps.alive(ctx)
 		return nil
 	})
 
@@ -103,21 +117,19 @@ func (c *PostJetstreamConnector) connect(ctx context.Context) error {
 		default:
 		}
 
-		_, message, err := conn.ReadMessage()
+		event, err := readEvent(conn)
 		if err != nil {
 			closeOnce.Do(func() { close(done) })
 			return fmt.Errorf("read error: %w", err)
 		}
-
-		// Parse Jetstream event
-		var event JetstreamEvent
-		if err := json.Unmarshal(message, &event); err != nil {
-			log.Printf("Failed to parse Jetstream event: %v", err)
+		if event == nil {
+			// Oversized or malformed message, already logged
 			continue
 		}
+		c.gaps.observe(ctx, event.TimeUS)
 
 		// Process event through consumer
-		if err := c.consumer.HandleEvent(ctx, &event); err != nil {
+		if err := c.consumer.HandleEvent(ctx, event); err != nil {
 			log.Printf("Failed to handle post event: %v", err)
 			// Continue processing other events even if one fails
 		}
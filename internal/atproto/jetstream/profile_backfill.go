@@ -0,0 +1,153 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/users"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// BlueskyProfileCollection is the Bluesky profile record, used when a user has no Coves profile
+	BlueskyProfileCollection = "app.bsky.actor.profile"
+
+	// DefaultProfileBackfillSweepInterval is how often users left pending are retried
+	DefaultProfileBackfillSweepInterval = 10 * time.Minute
+
+	// profileBackfillQueueSize bounds queued users; overflow is picked up by the next sweep
+	profileBackfillQueueSize = 256
+
+	// profileBackfillSweepLimit is how many pending users one sweep loads
+	profileBackfillSweepLimit = 100
+)
+
+// ProfileFetcher fetches a user's profile record from their PDS
+// Implementations return an error wrapping pds.ErrNotFound when the user has no profile
+type ProfileFetcher interface {
+	FetchProfile(ctx context.Context, user *users.User) (map[string]interface{}, error)
+}
+
+// ProfileBackfillStore stores backfilled profiles
+// Implemented by users.UserRepository
+type ProfileBackfillStore interface {
+	ListPendingProfiles(ctx context.Context, limit int) ([]*users.User, error)
+	UpdateProfile(ctx context.Context, did string, input users.UpdateProfileInput) (*users.User, error)
+	MarkProfileFetched(ctx context.Context, did string) error
+}
+
+// pdsProfileFetcher reads profiles with com.atproto.repo.getRecord on the user's PDS
+type pdsProfileFetcher struct{}
+
+// NewPDSProfileFetcher creates a ProfileFetcher that prefers the user's social.coves.actor.profile
+// and falls back to their app.bsky.actor.profile
+func NewPDSProfileFetcher() ProfileFetcher {
+	return pdsProfileFetcher{}
+}
+
+// FetchProfile fetches a user's profile record from their PDS
+func (pdsProfileFetcher) FetchProfile(ctx context.Context, user *users.User) (map[string]interface{}, error) {
+	client, err := pds.NewPublicClient(user.PDSURL, user.DID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PDS client: %w", err)
+	}
+
+	record, err := client.GetRecord(ctx, CovesProfileCollection, "self")
+	if errors.Is(err, pds.ErrNotFound) {
+		record, err = client.GetRecord(ctx, BlueskyProfileCollection, "self")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record.Value, nil
+}
+
+// ProfileBackfiller fills in profiles of users indexed on demand by the AuthorIndexer
+// Queued users are fetched one at a time so a burst of new authors doesn't hammer their PDSes.
+// Users whose fetch failed or who didn't fit in the queue stay pending and are retried by the
+// periodic sweep.
+type ProfileBackfiller struct {
+	store         ProfileBackfillStore
+	fetcher       ProfileFetcher
+	queue         chan *users.User
+	sweepInterval time.Duration
+}
+
+// NewProfileBackfiller creates a profile backfiller that sweeps for pending users every sweepInterval
+func NewProfileBackfiller(store ProfileBackfillStore, fetcher ProfileFetcher, sweepInterval time.Duration) *ProfileBackfiller {
+	if sweepInterval <= 0 {
+		sweepInterval = DefaultProfileBackfillSweepInterval
+	}
+	return &ProfileBackfiller{
+		store:         store,
+		fetcher:       fetcher,
+		queue:         make(chan *users.User, profileBackfillQueueSize),
+		sweepInterval: sweepInterval,
+	}
+}
+
+// Enqueue queues a user's profile for backfill without blocking
+func (b *ProfileBackfiller) Enqueue(user *users.User) {
+	select {
+	case b.queue <- user:
+	default:
+		// Still flagged pending in the database; the next sweep retries it
+	}
+}
+
+// Start backfills queued users until ctx is cancelled, sweeping for pending users on start and
+// every sweep interval
+func (b *ProfileBackfiller) Start(ctx context.Context) {
+	ticker := time.NewTicker(b.sweepInterval)
+	defer ticker.Stop()
+
+	b.sweep(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case user := <-b.queue:
+			if err := b.Backfill(ctx, user); err != nil {
+				log.Printf("Warning: profile backfill failed for %s: %v", user.DID, err)
+			}
+		case <-ticker.C:
+			b.sweep(ctx)
+		}
+	}
+}
+
+// sweep backfills users left pending by failed fetches, a full queue, or a restart
+func (b *ProfileBackfiller) sweep(ctx context.Context) {
+	pending, err := b.store.ListPendingProfiles(ctx, profileBackfillSweepLimit)
+	if err != nil {
+		log.Printf("Warning: failed to list pending profiles: %v", err)
+		return
+	}
+	for _, user := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := b.Backfill(ctx, user); err != nil {
+			log.Printf("Warning: profile backfill failed for %s: %v", user.DID, err)
+		}
+	}
+}
+
+// Backfill fetches one user's profile and stores it
+// Users without any profile record are marked fetched so they aren't retried.
+func (b *ProfileBackfiller) Backfill(ctx context.Context, user *users.User) error {
+	record, err := b.fetcher.FetchProfile(ctx, user)
+	if errors.Is(err, pds.ErrNotFound) {
+		return b.store.MarkProfileFetched(ctx, user.DID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch profile: %w", err)
+	}
+
+	if _, err := b.store.UpdateProfile(ctx, user.DID, profileInputFromRecord(record)); err != nil {
+		return fmt.Errorf("failed to store profile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/users"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubProfileFetcher serves profile records from a fixed table
+type stubProfileFetcher struct {
+	records map[string]map[string]interface{}
+	errs    map[string]error
+}
+
+func (f *stubProfileFetcher) FetchProfile(ctx context.Context, user *users.User) (map[string]interface{}, error) {
+	if err, ok := f.errs[user.DID]; ok {
+		return nil, err
+	}
+	record, ok := f.records[user.DID]
+	if !ok {
+		return nil, fmt.Errorf("%w: no profile record", pds.ErrNotFound)
+	}
+	return record, nil
+}
+
+// stubProfileStore tracks which users still have a pending profile
+type stubProfileStore struct {
+	mu       sync.Mutex
+	pending  []*users.User
+	profiles map[string]users.UpdateProfileInput
+	fetched  map[string]bool
+}
+
+func newStubProfileStore(pending ...*users.User) *stubProfileStore {
+	return &stubProfileStore{
+		pending:  pending,
+		profiles: make(map[string]users.UpdateProfileInput),
+		fetched:  make(map[string]bool),
+	}
+}
+
+func (s *stubProfileStore) ListPendingProfiles(ctx context.Context, limit int) ([]*users.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var result []*users.User
+	for _, user := range s.pending {
+		if !s.fetched[user.DID] && len(result) < limit {
+			result = append(result, user)
+		}
+	}
+	return result, nil
+}
+
+func (s *stubProfileStore) UpdateProfile(ctx context.Context, did string, input users.UpdateProfileInput) (*users.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.profiles[did] = input
+	s.fetched[did] = true
+	return &users.User{DID: did}, nil
+}
+
+func (s *stubProfileStore) MarkProfileFetched(ctx context.Context, did string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetched[did] = true
+	return nil
+}
+
+func (s *stubProfileStore) isFetched(did string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetched[did]
+}
+
+func TestProfileBackfiller_Backfill(t *testing.T) {
+	ctx := context.Background()
+	alice := &users.User{DID: "did:plc:alice", Handle: "alice.example.com", PDSURL: "https://pds.example.com"}
+	bob := &users.User{DID: "did:plc:bob", Handle: "bob.example.com", PDSURL: "https://pds.example.com"}
+	carol := &users.User{DID: "did:plc:carol", Handle: "carol.example.com", PDSURL: "https://pds.example.com"}
+
+	fetcher := &stubProfileFetcher{
+		records: map[string]map[string]interface{}{
+			alice.DID: {
+				"$type":       BlueskyProfileCollection,
+				"displayName": "Alice",
+				"avatar": map[string]interface{}{
+					"$type":    "blob",
+					"ref":      map[string]interface{}{"$link": "bafyavatar"},
+					"mimeType": "image/jpeg",
+					"size":     1234,
+				},
+			},
+		},
+		errs: map[string]error{carol.DID: errors.New("connection refused")},
+	}
+	store := newStubProfileStore(alice, bob, carol)
+	backfiller := NewProfileBackfiller(store, fetcher, time.Hour)
+
+	t.Run("stores the fetched profile", func(t *testing.T) {
+		if err := backfiller.Backfill(ctx, alice); err != nil {
+			t.Fatalf("Backfill failed: %v", err)
+		}
+		profile := store.profiles[alice.DID]
+		if profile.DisplayName == nil || *profile.DisplayName != "Alice" {
+			t.Errorf("Expected display name Alice, got %v", profile.DisplayName)
+		}
+		if profile.AvatarCID == nil || *profile.AvatarCID != "bafyavatar" {
+			t.Errorf("Expected avatar CID bafyavatar, got %v", profile.AvatarCID)
+		}
+	})
+
+	t.Run("marks users without a profile record fetched", func(t *testing.T) {
+		if err := backfiller.Backfill(ctx, bob); err != nil {
+			t.Fatalf("Backfill failed: %v", err)
+		}
+		if !store.isFetched(bob.DID) {
+			t.Error("Expected a user with no profile record to be marked fetched")
+		}
+		if _, ok := store.profiles[bob.DID]; ok {
+			t.Error("Expected no profile to be stored")
+		}
+	})
+
+	t.Run("leaves users pending when the fetch fails", func(t *testing.T) {
+		if err := backfiller.Backfill(ctx, carol); err == nil {
+			t.Fatal("Expected the fetch error to be returned")
+		}
+		if store.isFetched(carol.DID) {
+			t.Error("Expected a failed fetch to leave the profile pending for the next sweep")
+		}
+	})
+}
+
+func TestProfileBackfiller_StartSweepsAndDrainsQueue(t *testing.T) {
+	// dave was left pending before a restart; erin is queued by the author indexer
+	dave := &users.User{DID: "did:plc:dave", PDSURL: "https://pds.example.com"}
+	erin := &users.User{DID: "did:plc:erin", PDSURL: "https://pds.example.com"}
+	fetcher := &stubProfileFetcher{records: map[string]map[string]interface{}{
+		dave.DID: {"displayName": "Dave"},
+		erin.DID: {"displayName": "Erin"},
+	}}
+	store := newStubProfileStore(dave)
+	backfiller := NewProfileBackfiller(store, fetcher, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go backfiller.Start(ctx)
+	backfiller.Enqueue(erin)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !store.isFetched(dave.DID) || !store.isFetched(erin.DID) {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected both profiles to be backfilled (dave: %v, erin: %v)", store.isFetched(dave.DID), store.isFetched(erin.DID))
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestProfileBackfiller_EnqueueDoesNotBlock(t *testing.T) {
+	backfiller := NewProfileBackfiller(newStubProfileStore(), &stubProfileFetcher{}, time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < profileBackfillQueueSize+10; i++ {
+			backfiller.Enqueue(&users.User{DID: fmt.Sprintf("did:plc:user%d", i)})
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Expected Enqueue to drop users once the queue is full instead of blocking")
+	}
+}
@@ -0,0 +1,213 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/utils"
+	"Coves/internal/core/communities"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+// memoryTombstones keeps the newest deleting rev per URI in memory
+type memoryTombstones struct {
+	revs map[string]string
+	mu   sync.Mutex
+}
+
+func newMemoryTombstones() *memoryTombstones {
+	return &memoryTombstones{revs: make(map[string]string)}
+}
+
+func (m *memoryTombstones) Record(ctx context.Context, uri, rev string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if rev == "" || utils.IsStaleRev(rev, m.revs[uri]) {
+		return nil
+	}
+	m.revs[uri] = rev
+	return nil
+}
+
+func (m *memoryTombstones) Rev(ctx context.Context, uri string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.revs[uri], nil
+}
+
+func (m *memoryTombstones) Prune(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+// testRev returns the rev of a commit made n seconds after a fixed time, so revs order by n
+func testRev(n int) string {
+	return syntax.NewTIDFromTime(time.Unix(1700000000+int64(n), 0), 0).String()
+}
+
+func TestReplayGuard_Stale(t *testing.T) {
+	commit := &CommitEvent{Collection: "social.coves.community.comment", Operation: "update", Rev: testRev(1)}
+
+	var disabled *ReplayGuard
+	if !disabled.stale(commit, "at://uri", testRev(2)) {
+		t.Error("A nil guard should still skip older events")
+	}
+	if len(disabled.StaleEventsSkipped()) != 0 {
+		t.Error("A nil guard shouldn't count skipped events")
+	}
+
+	guard := NewReplayGuard(nil)
+	tests := []struct {
+		name       string
+		indexedRev string
+		want       bool
+	}{
+		{"older than indexed", testRev(2), true},
+		{"same as indexed", testRev(1), true},
+		{"newer than indexed", testRev(0), false},
+		{"nothing indexed", "", false},
+		{"unparseable indexed rev", "rev123", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := guard.stale(commit, "at://uri", tt.indexedRev); got != tt.want {
+				t.Errorf("Expected stale=%v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	guard.skipped(&CommitEvent{Collection: "social.coves.actor.profile", Operation: "update"}, "at://profile")
+	counts := guard.StaleEventsSkipped()
+	if counts["social.coves.community.comment"] != 2 || counts["social.coves.actor.profile"] != 1 || len(counts) != 2 {
+		t.Errorf("Unexpected skip counts: %v", counts)
+	}
+}
+
+func TestReplayGuard_DeletedAfter(t *testing.T) {
+	ctx := context.Background()
+	tombstones := newMemoryTombstones()
+	guard := NewReplayGuard(tombstones)
+	uri := "at://did:plc:user/social.coves.community.subscription/abc"
+
+	deleteCommit := &CommitEvent{Collection: "social.coves.community.subscription", Operation: "delete", Rev: testRev(5)}
+	if err := guard.recordDelete(ctx, deleteCommit, uri); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		rev  string
+		want bool
+	}{
+		{testRev(4), true},
+		{testRev(5), true},
+		{testRev(6), false},
+		{"", false},
+	} {
+		create := &CommitEvent{Collection: "social.coves.community.subscription", Operation: "create", Rev: tt.rev}
+		deleted, err := guard.deletedAfter(ctx, create, uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if deleted != tt.want {
+			t.Errorf("Create at rev %q: expected deletedAfter=%v, got %v", tt.rev, tt.want, deleted)
+		}
+	}
+
+	other, err := guard.deletedAfter(ctx, &CommitEvent{Operation: "create", Rev: testRev(1)}, "at://did:plc:user/social.coves.community.subscription/other")
+	if err != nil || other {
+		t.Errorf("A record without a tombstone was never deleted, got %v, %v", other, err)
+	}
+
+	var disabled *ReplayGuard
+	if err := disabled.recordDelete(ctx, deleteCommit, uri); err != nil {
+		t.Error(err)
+	}
+	if deleted, _ := disabled.deletedAfter(ctx, &CommitEvent{Rev: testRev(1)}, uri); deleted {
+		t.Error("A nil guard keeps no tombstones")
+	}
+}
+
+// TestCommunityRules_StaleEvents replays each ordering of rules writes and deletes
+func TestCommunityRules_StaleEvents(t *testing.T) {
+	type step struct {
+		operation string
+		markdown  string
+		rev       int
+	}
+	tests := []struct {
+		name    string
+		want    string // Markdown left indexed; "" for none
+		steps   []step
+		skipped int64
+	}{
+		{
+			name:    "older update after a newer one",
+			steps:   []step{{"create", "v1", 1}, {"update", "v3", 3}, {"update", "v2", 2}},
+			want:    "v3",
+			skipped: 1,
+		},
+		{
+			name:    "redelivered update",
+			steps:   []step{{"create", "v1", 1}, {"update", "v2", 2}, {"update", "v2", 2}},
+			want:    "v2",
+			skipped: 1,
+		},
+		{
+			name:    "older create after a newer delete",
+			steps:   []step{{"create", "v1", 1}, {"delete", "", 2}, {"create", "v1", 1}},
+			skipped: 1,
+		},
+		{
+			name:    "delete arriving before its create",
+			steps:   []step{{"delete", "", 2}, {"create", "v1", 1}},
+			skipped: 1,
+		},
+		{
+			name:    "older delete after a newer write",
+			steps:   []step{{"create", "v1", 1}, {"update", "v3", 3}, {"delete", "", 2}},
+			want:    "v3",
+			skipped: 1,
+		},
+		{
+			name:  "recreated after a delete",
+			steps: []step{{"create", "v1", 1}, {"delete", "", 2}, {"create", "v3", 3}},
+			want:  "v3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			repo := newMemoryRulesRepo()
+			guard := NewReplayGuard(newMemoryTombstones())
+			consumer := NewCommunityEventConsumer(nil, "did:web:coves.social", true, nil)
+			consumer.SetRulesRepository(repo)
+			consumer.SetReplayGuard(guard)
+
+			for _, s := range tt.steps {
+				event := newRulesEvent(s.markdown, nil)
+				event.Commit.Operation = s.operation
+				event.Commit.Rev = testRev(s.rev)
+				if s.operation == "delete" {
+					event.Commit.Record = nil
+				}
+				if err := consumer.HandleEvent(ctx, event); err != nil {
+					t.Fatalf("%s at rev %d: %v", s.operation, s.rev, err)
+				}
+			}
+
+			stored, err := repo.GetRules(ctx, "did:plc:community")
+			switch {
+			case tt.want == "" && !errors.Is(err, communities.ErrRulesNotFound):
+				t.Errorf("Expected no rules, got %+v (err %v)", stored, err)
+			case tt.want != "" && (err != nil || stored.Markdown != tt.want):
+				t.Errorf("Expected rules %q, got %+v (err %v)", tt.want, stored, err)
+			}
+			if got := guard.StaleEventsSkipped()["social.coves.community.rules"]; got != tt.skipped {
+				t.Errorf("Expected %d skipped events, got %d", tt.skipped, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/utils"
+	"Coves/internal/core/comments"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// NotificationReasonReply is the notifications.reason value for replies to a post or comment
+const NotificationReasonReply = "reply"
+
+// replyBucket is the hour a reply's notification is grouped into
+// createdAt is author-supplied, so a future timestamp counts as now.
+func replyBucket(createdAt, now time.Time) time.Time {
+	if createdAt.After(now) {
+		createdAt = now
+	}
+	return createdAt.UTC().Truncate(time.Hour)
+}
+
+// insertReplyNotification notifies the parent's author of a new reply
+// Replies to the same parent in the same hour bucket collapse into one notification: the
+// upsert bumps reply_count, points the row at the newest reply and its author, and marks it
+// unread again. Self-replies, replies to parents that aren't indexed, and replies in threads
+// the parent's author muted don't notify.
+func insertReplyNotification(ctx context.Context, tx *sql.Tx, comment *comments.Comment, now time.Time) error {
+	var parentQuery string
+	switch utils.ExtractCollectionFromURI(comment.ParentURI) {
+	case "social.coves.community.post":
+		parentQuery = `SELECT author_did FROM posts WHERE uri = $1 AND deleted_at IS NULL`
+	case "social.coves.community.comment":
+		parentQuery = `SELECT commenter_did FROM comments WHERE uri = $1 AND deleted_at IS NULL`
+	default:
+		return nil
+	}
+
+	var recipientDID string
+	err := tx.QueryRowContext(ctx, parentQuery, comment.ParentURI).Scan(&recipientDID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up reply parent author: %w", err)
+	}
+	if recipientDID == comment.CommenterDID {
+		return nil
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO notifications (
+			recipient_did, author_did, reason, subject_uri, subject_cid,
+			subject_parent_uri, bucket_start, latest_actor_did, created_at, updated_at
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7::timestamptz, $2, $8::timestamptz, $8::timestamptz
+		WHERE NOT EXISTS (SELECT 1 FROM thread_mutes WHERE user_did = $1 AND root_uri = $9)
+		ON CONFLICT (recipient_did, reason, subject_parent_uri, bucket_start) WHERE subject_parent_uri IS NOT NULL
+		DO UPDATE SET
+			reply_count = notifications.reply_count + 1,
+			latest_actor_did = EXCLUDED.latest_actor_did,
+			subject_uri = EXCLUDED.subject_uri,
+			subject_cid = EXCLUDED.subject_cid,
+			updated_at = EXCLUDED.updated_at,
+			is_read = FALSE
+	`, recipientDID, comment.CommenterDID, NotificationReasonReply, comment.URI, comment.CID,
+		comment.ParentURI, replyBucket(comment.CreatedAt, now), now, comment.RootURI)
+	if err != nil {
+		return fmt.Errorf("failed to create reply notification for %s: %w", recipientDID, err)
+	}
+	return nil
+}
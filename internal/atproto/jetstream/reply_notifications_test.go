@@ -0,0 +1,30 @@
+package jetstream
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReplyBucket(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		want      time.Time
+	}{
+		{name: "start of the hour", createdAt: time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC), want: time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)},
+		{name: "end of the hour", createdAt: time.Date(2026, 3, 1, 11, 59, 59, 999, time.UTC), want: time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC)},
+		{name: "next hour", createdAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), want: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "other time zone", createdAt: time.Date(2026, 3, 1, 7, 15, 0, 0, time.FixedZone("EST", -5*3600)), want: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{name: "future dated counts as now", createdAt: now.Add(3 * time.Hour), want: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replyBucket(tt.createdAt, now); !got.Equal(tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package jetstream
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSubscriberCountFlushInterval bounds how stale a community's subscriber_count can get
+	DefaultSubscriberCountFlushInterval = 500 * time.Millisecond
+	// DefaultSubscriberCountMaxPending flushes early once this many subscription events are buffered
+	DefaultSubscriberCountMaxPending = 1000
+)
+
+// SubscriberCountStore applies coalesced subscriber count deltas
+// Implemented by communities.Repository
+type SubscriberCountStore interface {
+	AdjustSubscriberCounts(ctx context.Context, deltas map[string]int) error
+}
+
+// SubscriberCountBuffer coalesces subscriber count changes per community
+// A subscription spike on a popular community would otherwise serialize every event on the
+// same communities row; buffered deltas are written with one UPDATE per community per flush.
+// Counts lag the subscription rows by at most one flush interval.
+type SubscriberCountBuffer struct {
+	store      SubscriberCountStore
+	pending    map[string]int
+	flushNow   chan struct{}
+	interval   time.Duration
+	maxPending int
+	events     int
+	mu         sync.Mutex // guards pending and events
+	flushMu    sync.Mutex // serializes flushes so deltas are applied in order
+}
+
+// NewSubscriberCountBuffer creates a buffer that flushes every interval, or as soon as
+// maxPending subscription events are waiting
+func NewSubscriberCountBuffer(store SubscriberCountStore, interval time.Duration, maxPending int) *SubscriberCountBuffer {
+	if interval <= 0 {
+		interval = DefaultSubscriberCountFlushInterval
+	}
+	if maxPending <= 0 {
+		maxPending = DefaultSubscriberCountMaxPending
+	}
+	return &SubscriberCountBuffer{
+		store:      store,
+		pending:    make(map[string]int),
+		flushNow:   make(chan struct{}, 1),
+		interval:   interval,
+		maxPending: maxPending,
+	}
+}
+
+// Add records a subscriber count change for a community
+func (b *SubscriberCountBuffer) Add(communityDID string, delta int) {
+	b.mu.Lock()
+	b.pending[communityDID] += delta
+	b.events++
+	full := b.events >= b.maxPending
+	b.mu.Unlock()
+
+	if full {
+		// Non-blocking: a flush is already requested if the channel is full
+		select {
+		case b.flushNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush writes all pending deltas
+// On failure the deltas are put back so the next flush retries them
+func (b *SubscriberCountBuffer) Flush(ctx context.Context) error {
+	b.flushMu.Lock()
+	defer b.flushMu.Unlock()
+
+	b.mu.Lock()
+	deltas := b.pending
+	b.pending = make(map[string]int)
+	b.events = 0
+	b.mu.Unlock()
+
+	for did, delta := range deltas {
+		if delta == 0 {
+			delete(deltas, did)
+		}
+	}
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	if err := b.store.AdjustSubscriberCounts(ctx, deltas); err != nil {
+		b.mu.Lock()
+		for did, delta := range deltas {
+			b.pending[did] += delta
+		}
+		b.mu.Unlock()
+		return err
+	}
+
+	return nil
+}
+
+// Start flushes on the configured interval and whenever the pending threshold is reached
+// Blocks until ctx is cancelled; callers flush once more on shutdown
+func (b *SubscriberCountBuffer) Start(ctx context.Context) {
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-b.flushNow:
+		}
+
+		if err := b.Flush(ctx); err != nil {
+			log.Printf("Failed to flush subscriber counts (will retry): %v", err)
+		}
+	}
+}
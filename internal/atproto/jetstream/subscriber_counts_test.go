@@ -0,0 +1,117 @@
+package jetstream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockSubscriberCountStore records every flushed batch
+type mockSubscriberCountStore struct {
+	adjustErr error
+	batches   []map[string]int
+	mu        sync.Mutex
+}
+
+func (m *mockSubscriberCountStore) AdjustSubscriberCounts(ctx context.Context, deltas map[string]int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.adjustErr != nil {
+		return m.adjustErr
+	}
+	m.batches = append(m.batches, deltas)
+	return nil
+}
+
+func (m *mockSubscriberCountStore) total(communityDID string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sum := 0
+	for _, batch := range m.batches {
+		sum += batch[communityDID]
+	}
+	return sum
+}
+
+func TestSubscriberCountBuffer_CoalescesPerCommunity(t *testing.T) {
+	store := &mockSubscriberCountStore{}
+	buf := NewSubscriberCountBuffer(store, time.Hour, 1000)
+
+	for i := 0; i < 5; i++ {
+		buf.Add("did:plc:popular", 1)
+	}
+	buf.Add("did:plc:popular", -1)
+	buf.Add("did:plc:quiet", 1)
+	buf.Add("did:plc:quiet", -1)
+
+	if err := buf.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected flush error: %v", err)
+	}
+
+	if len(store.batches) != 1 {
+		t.Fatalf("Expected one batch, got %d", len(store.batches))
+	}
+	batch := store.batches[0]
+	if batch["did:plc:popular"] != 4 {
+		t.Errorf("Expected net delta 4, got %d", batch["did:plc:popular"])
+	}
+	if _, ok := batch["did:plc:quiet"]; ok {
+		t.Errorf("Expected zero net delta to be skipped, got %v", batch)
+	}
+
+	if err := buf.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected flush error: %v", err)
+	}
+	if len(store.batches) != 1 {
+		t.Errorf("Expected empty flush to skip the store, got %d batches", len(store.batches))
+	}
+}
+
+func TestSubscriberCountBuffer_RetriesFailedFlush(t *testing.T) {
+	store := &mockSubscriberCountStore{adjustErr: errors.New("database unavailable")}
+	buf := NewSubscriberCountBuffer(store, time.Hour, 1000)
+
+	buf.Add("did:plc:c1", 2)
+	if err := buf.Flush(context.Background()); err == nil {
+		t.Fatal("Expected flush error")
+	}
+
+	store.adjustErr = nil
+	buf.Add("did:plc:c1", 1)
+	if err := buf.Flush(context.Background()); err != nil {
+		t.Fatalf("Unexpected flush error: %v", err)
+	}
+
+	if got := store.total("did:plc:c1"); got != 3 {
+		t.Errorf("Expected failed deltas to be retried, got total %d", got)
+	}
+}
+
+func TestSubscriberCountBuffer_FlushesAtThreshold(t *testing.T) {
+	store := &mockSubscriberCountStore{}
+	buf := NewSubscriberCountBuffer(store, time.Hour, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go buf.Start(ctx)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf.Add("did:plc:popular", 1)
+		}()
+	}
+	wg.Wait()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for store.total("did:plc:popular") != 10 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected threshold flush before the interval, got total %d", store.total("did:plc:popular"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -3,6 +3,7 @@ package jetstream
 import (
 	"Coves/internal/atproto/identity"
 	"Coves/internal/core/users"
+	"Coves/internal/core/votes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -10,6 +11,7 @@ import (
 	"log"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -27,6 +29,19 @@ type SessionHandleUpdater interface {
 	UpdateHandleByDID(ctx context.Context, did, newHandle string) (int64, error)
 }
 
+// VoteNullifier neutralizes and restores all votes cast by an account.
+// Implemented by votes.Repository; used to react to account takedowns/suspensions.
+type VoteNullifier interface {
+	RemoveVotesByVoter(ctx context.Context, voterDID, reason string) (int, error)
+	RestoreVotesByVoter(ctx context.Context, voterDID string, reasons ...string) (int, error)
+}
+
+// AccountStatusRecorder records whether an account is active on its PDS.
+// Implemented by users.ActivityRepository; deactivated accounts show no profile activity.
+type AccountStatusRecorder interface {
+	SetDeactivated(ctx context.Context, did string, deactivated bool) error
+}
+
 // JetstreamEvent represents an event from the Jetstream firehose
 // Jetstream documentation: https://docs.bsky.app/docs/advanced-guides/jetstream
 type JetstreamEvent struct {
@@ -41,6 +56,7 @@ type JetstreamEvent struct {
 type AccountEvent struct {
 	Did    string `json:"did"`
 	Time   string `json:"time"`
+	Status string `json:"status,omitempty"` // Set when inactive: "takendown", "suspended", "deactivated", "deleted"
 	Seq    int64  `json:"seq"`
 	Active bool   `json:"active"`
 }
@@ -60,15 +76,29 @@ type CommitEvent struct {
 	RKey       string                 `json:"rkey"`
 	Record     map[string]interface{} `json:"record,omitempty"`
 	CID        string                 `json:"cid,omitempty"`
+	RecordSize int                    `json:"-"` // Encoded record size, set by readEvent for large messages
 }
 
 // UserEventConsumer consumes user-related events from Jetstream
 type UserEventConsumer struct {
 	userService          users.UserService
 	identityResolver     identity.Resolver
-	sessionHandleUpdater SessionHandleUpdater // Optional: updates OAuth sessions on handle change
+	sessionHandleUpdater SessionHandleUpdater        // Optional: updates OAuth sessions on handle change
+	voteNullifier        VoteNullifier               // Optional: neutralizes votes of taken-down/suspended accounts
+	accountStatus        AccountStatusRecorder       // Optional: records account deactivation
+	preferencesRepo      users.PreferencesRepository // Optional: indexes social.coves.actor.preferences records
+	dlq                  DeadLetterQueue             // Optional: rejected records are only logged when nil
+	replay               *ReplayGuard                // Optional: stale events are still skipped, but not counted, when nil
+	blobRefs             BlobReferenceTracker        // Optional: avatars and banners aren't tracked for the image proxy when nil
+	resolver             *timedResolver              // identityResolver with latency metrics
 	wsURL                string
 	pdsFilter            string // Optional: only index users from specific PDS
+
+	// Worker pool: events are sharded by DID across workers (see user_consumer_pool.go)
+	queues     []chan *JetstreamEvent
+	workers    int
+	queueDepth atomic.Int64
+	startOnce  sync.Once
 }
 
 // ConsumerOption is a functional option for configuring UserEventConsumer
@@ -82,24 +112,73 @@ func WithSessionHandleUpdater(updater SessionHandleUpdater) ConsumerOption {
 	}
 }
 
+// WithVoteNullifier sets the vote nullifier used when accounts are taken down or suspended
+// (and restored when they are reactivated). If not set, account status events are ignored.
+func WithVoteNullifier(nullifier VoteNullifier) ConsumerOption {
+	return func(c *UserEventConsumer) {
+		c.voteNullifier = nullifier
+	}
+}
+
+// WithAccountStatusRecorder sets where account active/inactive transitions are recorded.
+// If not set, account status is only used for vote nullification.
+func WithAccountStatusRecorder(recorder AccountStatusRecorder) ConsumerOption {
+	return func(c *UserEventConsumer) {
+		c.accountStatus = recorder
+	}
+}
+
+// WithPreferencesRepository sets the repository social.coves.actor.preferences records are
+// indexed into. If not set, preferences records are ignored.
+func WithPreferencesRepository(repo users.PreferencesRepository) ConsumerOption {
+	return func(c *UserEventConsumer) {
+		c.preferencesRepo = repo
+	}
+}
+
+// WithDeadLetterQueue sets where rejected profile and preferences records are stored.
+// If not set, rejected records are only logged.
+func WithDeadLetterQueue(dlq DeadLetterQueue) ConsumerOption {
+	return func(c *UserEventConsumer) {
+		c.dlq = dlq
+	}
+}
+
+// WithReplayGuard sets where skipped stale profile and preferences events are counted and
+// deleted preferences' revs kept. If not set, a replayed preferences create can restore
+// deleted preferences.
+func WithReplayGuard(guard *ReplayGuard) ConsumerOption {
+	return func(c *UserEventConsumer) {
+		c.replay = guard
+	}
+}
+
 // NewUserEventConsumer creates a new Jetstream consumer for user events
 func NewUserEventConsumer(userService users.UserService, identityResolver identity.Resolver, wsURL, pdsFilter string, opts ...ConsumerOption) *UserEventConsumer {
+	resolver := &timedResolver{Resolver: identityResolver}
 	c := &UserEventConsumer{
 		userService:      userService,
-		identityResolver: identityResolver,
+		identityResolver: resolver,
+		resolver:         resolver,
 		wsURL:            wsURL,
 		pdsFilter:        pdsFilter,
+		workers:          DefaultUserConsumerWorkers,
 	}
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.queues = make([]chan *JetstreamEvent, c.workers)
+	for i := range c.queues {
+		c.queues[i] = make(chan *JetstreamEvent, userConsumerQueueSize)
+	}
 	return c
 }
 
 // Start begins consuming events from Jetstream
 // Runs indefinitely, reconnecting on errors
 func (c *UserEventConsumer) Start(ctx context.Context) error {
-	log.Printf("Starting Jetstream user consumer: %s", c.wsURL)
+	log.Printf("Starting Jetstream user consumer: %s (%d workers)", c.wsURL, c.workers)
+	c.StartWorkers(ctx)
 
 	for {
 		select {
@@ -176,7 +255,7 @@ func (c *UserEventConsumer) connect(ctx context.Context) error {
 		case <-done:
 			return fmt.Errorf("connection closed")
 		default:
-			_, message, err := conn.ReadMessage()
+			event, err := readEvent(conn)
 			if err != nil {
 				closeOnce.Do(func() { close(done) })
 				return fmt.Errorf("read error: %w", err)
@@ -187,30 +266,40 @@ func (c *UserEventConsumer) connect(ctx context.Context) error {
 				log.Printf("Failed to set read deadline: %v", err)
 			}
 
-			if err := c.handleEvent(ctx, message); err != nil {
-				log.Printf("Error handling event: %v", err)
-				// Continue processing other events
+			if event == nil {
+				// Oversized or malformed message, already logged
+				continue
+			}
+			// Blocks while the event's worker is full (backpressure)
+			if err := c.Enqueue(ctx, event); err != nil {
+				return err
 			}
 		}
 	}
 }
 
-// handleEvent processes a single Jetstream event
+// handleEvent processes a single Jetstream event inline
 func (c *UserEventConsumer) handleEvent(ctx context.Context, data []byte) error {
 	var event JetstreamEvent
 	if err := json.Unmarshal(data, &event); err != nil {
 		return fmt.Errorf("failed to parse event: %w", err)
 	}
 
+	return c.HandleEvent(ctx, &event)
+}
+
+// HandleEvent processes a parsed Jetstream event
+// Used directly when the consumer is registered with a JetstreamDispatcher
+func (c *UserEventConsumer) HandleEvent(ctx context.Context, event *JetstreamEvent) error {
 	// We're interested in identity events (handle updates), account events (new users),
 	// and commit events (profile updates from social.coves.actor.profile)
 	switch event.Kind {
 	case "identity":
-		return c.handleIdentityEvent(ctx, &event)
+		return c.handleIdentityEvent(ctx, event)
 	case "account":
-		return c.handleAccountEvent(ctx, &event)
+		return c.handleAccountEvent(ctx, event)
 	case "commit":
-		return c.handleCommitEvent(ctx, &event)
+		return c.handleCommitEvent(ctx, event)
 	default:
 		// Ignore other event types
 		return nil
@@ -245,6 +334,8 @@ func (c *UserEventConsumer) handleIdentityEvent(ctx context.Context, event *Jets
 			// User doesn't exist in our database - skip this event
 			// They'll be indexed when they actually interact with Coves (OAuth login, signup, etc.)
 			// This prevents us from indexing millions of Bluesky users we don't care about
+			// Their identity may still be cached (mentions, community lookups), so drop it
+			c.purgeCachedIdentity(ctx, did)
 			return nil
 		}
 		// Database error - propagate so it can be retried
@@ -264,7 +355,7 @@ func (c *UserEventConsumer) handleIdentityEvent(ctx context.Context, event *Jets
 			return fmt.Errorf("failed to update handle: %w", updateErr)
 		}
 
-		// CRITICAL: Purge BOTH old handle and DID from cache
+		// CRITICAL: Purge the old handle from cache (the DID is purged below)
 		// Old handle: alice.bsky.social → did:plc:abc123 (must be removed)
 		if purgeErr := c.identityResolver.Purge(ctx, existingUser.Handle); purgeErr != nil {
 			slog.Error("CRITICAL: failed to purge old handle cache",
@@ -272,13 +363,6 @@ func (c *UserEventConsumer) handleIdentityEvent(ctx context.Context, event *Jets
 				slog.String("error", purgeErr.Error()))
 		}
 
-		// DID: did:plc:abc123 → alice.bsky.social (must be removed)
-		if purgeErr := c.identityResolver.Purge(ctx, did); purgeErr != nil {
-			slog.Error("CRITICAL: failed to purge DID cache",
-				slog.String("did", did),
-				slog.String("error", purgeErr.Error()))
-		}
-
 		// Update OAuth session handles to keep mobile/web sessions in sync
 		// Failure here causes users to see stale handles in their active sessions
 		if c.sessionHandleUpdater != nil {
@@ -297,9 +381,24 @@ func (c *UserEventConsumer) handleIdentityEvent(ctx context.Context, event *Jets
 		log.Printf("Handle unchanged for %s (%s)", handle, did)
 	}
 
+	// Identity events also announce PDS migrations and key rotations, so the DID is purged
+	// whether or not the handle changed
+	// DID: did:plc:abc123 → alice.bsky.social (must be removed)
+	c.purgeCachedIdentity(ctx, did)
+
 	return nil
 }
 
+// purgeCachedIdentity drops a DID, and the handle cached with it, from the identity cache
+// Failures are logged: the cached identity expires on its own.
+func (c *UserEventConsumer) purgeCachedIdentity(ctx context.Context, did string) {
+	if purgeErr := c.identityResolver.Purge(ctx, did); purgeErr != nil {
+		slog.Error("CRITICAL: failed to purge DID cache",
+			slog.String("did", did),
+			slog.String("error", purgeErr.Error()))
+	}
+}
+
 // handleAccountEvent processes account events (account creation/updates)
 func (c *UserEventConsumer) handleAccountEvent(ctx context.Context, event *JetstreamEvent) error {
 	if event.Account == nil {
@@ -311,25 +410,69 @@ func (c *UserEventConsumer) handleAccountEvent(ctx context.Context, event *Jetst
 		return fmt.Errorf("account event missing did")
 	}
 
-	// Account events don't include handle, so we skip them.
+	// Account events don't include handle, so they never create users.
 	// Users are indexed via OAuth login or signup, not from account events.
+	if c.accountStatus != nil {
+		if err := c.accountStatus.SetDeactivated(ctx, did, !event.Account.Active); err != nil {
+			return fmt.Errorf("failed to record account status: %w", err)
+		}
+	}
+
+	if c.voteNullifier == nil {
+		return nil
+	}
+
+	status := event.Account.Status
+	takenDown := !event.Account.Active && (status == votes.NullifiedTakendown || status == votes.NullifiedSuspended)
+	if !takenDown && !event.Account.Active {
+		// Deactivated/deleted accounts keep their votes
+		return nil
+	}
+
+	// Only process users who exist in our database (only they can have voted)
+	if _, err := c.userService.GetUserByDID(ctx, did); err != nil {
+		if errors.Is(err, users.ErrUserNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to check if user exists: %w", err)
+	}
+
+	if takenDown {
+		if _, err := c.voteNullifier.RemoveVotesByVoter(ctx, did, status); err != nil {
+			return fmt.Errorf("failed to nullify votes for %s account: %w", status, err)
+		}
+		return nil
+	}
+
+	// Reactivated: restore votes neutralized by a takedown/suspension (not by an admin)
+	if _, err := c.voteNullifier.RestoreVotesByVoter(ctx, did, votes.NullifiedTakendown, votes.NullifiedSuspended); err != nil {
+		return fmt.Errorf("failed to restore votes for reactivated account: %w", err)
+	}
 	return nil
 }
 
-// handleCommitEvent processes commit events for user profile updates
-// Only handles social.coves.actor.profile collection for users already in our database.
-// This syncs profile data (displayName, bio, avatar, banner) from Coves profiles.
+// handleCommitEvent processes commit events for user profile and preferences updates
+// Only handles social.coves.actor.profile and social.coves.actor.preferences for users
+// already in our database. Profiles sync displayName, bio, avatar and banner.
 func (c *UserEventConsumer) handleCommitEvent(ctx context.Context, event *JetstreamEvent) error {
 	if event.Commit == nil {
 		slog.Warn("received nil commit in handleCommitEvent (malformed event)", slog.String("did", event.Did))
 		return nil
 	}
 
-	// Only handle social.coves.actor.profile collection
-	if event.Commit.Collection != CovesProfileCollection {
+	isPreferences := event.Commit.Collection == users.PreferencesCollection
+	if event.Commit.Collection != CovesProfileCollection && (!isPreferences || c.preferencesRepo == nil) {
+		return nil
+	}
+	// Preferences are a singleton record; other rkeys aren't ours to interpret
+	if isPreferences && event.Commit.RKey != "self" {
 		return nil
 	}
 
+	if rejected, err := guardRecord(ctx, c.dlq, event); rejected {
+		return err
+	}
+
 	// Only process users who exist in our database
 	_, err := c.userService.GetUserByDID(ctx, event.Did)
 	if err != nil {
@@ -342,18 +485,21 @@ func (c *UserEventConsumer) handleCommitEvent(ctx context.Context, event *Jetstr
 		return fmt.Errorf("failed to check if user exists: %w", err)
 	}
 
+	if isPreferences {
+		return c.handlePreferencesCommit(ctx, event.Did, event.Commit)
+	}
+
 	switch event.Commit.Operation {
 	case "create", "update":
 		return c.handleProfileUpdate(ctx, event.Did, event.Commit)
 	case "delete":
-		return c.handleProfileDelete(ctx, event.Did)
+		return c.handleProfileDelete(ctx, event.Did, event.Commit)
 	default:
 		return nil
 	}
 }
 
 // handleProfileUpdate processes profile create/update operations
-// Extracts displayName, description (bio), avatar, and banner from the record
 func (c *UserEventConsumer) handleProfileUpdate(ctx context.Context, did string, commit *CommitEvent) error {
 	if commit.Record == nil {
 		slog.Warn("received nil record in profile commit (profile update silently dropped)",
@@ -362,55 +508,135 @@ func (c *UserEventConsumer) handleProfileUpdate(ctx context.Context, did string,
 		return nil
 	}
 
+	input := profileInputFromRecord(commit.Record)
+	input.Rev = commit.Rev
+	_, err := c.userService.UpdateProfile(ctx, did, input)
+	if err != nil {
+		if errors.Is(err, users.ErrStaleRevision) {
+			c.replay.skipped(commit, profileURI(did, commit))
+			return nil
+		}
+		return fmt.Errorf("failed to update user profile: %w", err)
+	}
+	trackBlobReferences(ctx, c.blobRefs, profileURI(did, commit), commit.Record)
+
+	log.Printf("Updated profile for user %s", did)
+	return nil
+}
+
+// profileInputFromRecord extracts displayName, description (bio), avatar, and banner from a
+// social.coves.actor.profile or app.bsky.actor.profile record
+func profileInputFromRecord(record map[string]interface{}) users.UpdateProfileInput {
 	input := users.UpdateProfileInput{}
 
 	// Extract displayName
-	if dn, ok := commit.Record["displayName"].(string); ok {
+	if dn, ok := record["displayName"].(string); ok {
 		input.DisplayName = &dn
 	}
 
 	// Extract description (bio)
-	if desc, ok := commit.Record["description"].(string); ok {
+	if desc, ok := record["description"].(string); ok {
 		input.Bio = &desc
 	}
 
 	// Extract avatar CID from blob ref structure
-	if avatarMap, ok := commit.Record["avatar"].(map[string]interface{}); ok {
+	if avatarMap, ok := record["avatar"].(map[string]interface{}); ok {
 		if cid, ok := extractBlobCID(avatarMap); ok {
 			input.AvatarCID = &cid
 		}
 	}
 
 	// Extract banner CID from blob ref structure
-	if bannerMap, ok := commit.Record["banner"].(map[string]interface{}); ok {
+	if bannerMap, ok := record["banner"].(map[string]interface{}); ok {
 		if cid, ok := extractBlobCID(bannerMap); ok {
 			input.BannerCID = &cid
 		}
 	}
 
-	_, err := c.userService.UpdateProfile(ctx, did, input)
-	if err != nil {
-		return fmt.Errorf("failed to update user profile: %w", err)
-	}
+	return input
+}
 
-	log.Printf("Updated profile for user %s", did)
-	return nil
+// profileURI returns the AT-URI of the profile record a commit wrote
+func profileURI(did string, commit *CommitEvent) string {
+	return fmt.Sprintf("at://%s/%s/%s", did, commit.Collection, commit.RKey)
 }
 
 // handleProfileDelete processes profile delete operations
-// Clears all profile fields by passing empty strings
-func (c *UserEventConsumer) handleProfileDelete(ctx context.Context, did string) error {
+// Clears all profile fields by passing empty strings. The user row keeps the deleting rev,
+// so a replay of an older profile write doesn't restore the fields.
+func (c *UserEventConsumer) handleProfileDelete(ctx context.Context, did string, commit *CommitEvent) error {
 	empty := ""
 	input := users.UpdateProfileInput{
 		DisplayName: &empty,
 		Bio:         &empty,
 		AvatarCID:   &empty,
 		BannerCID:   &empty,
+		Rev:         commit.Rev,
 	}
 	_, err := c.userService.UpdateProfile(ctx, did, input)
 	if err != nil {
+		if errors.Is(err, users.ErrStaleRevision) {
+			c.replay.skipped(commit, profileURI(did, commit))
+			return nil
+		}
 		return fmt.Errorf("failed to clear user profile: %w", err)
 	}
+	trackBlobReferences(ctx, c.blobRefs, profileURI(did, commit), nil)
 	log.Printf("Cleared profile for user %s", did)
 	return nil
 }
+
+// handlePreferencesCommit indexes a preferences record create/update or removes it on delete
+// Deletes leave a tombstone with their rev, so a replayed older write doesn't restore the record
+func (c *UserEventConsumer) handlePreferencesCommit(ctx context.Context, did string, commit *CommitEvent) error {
+	uri := fmt.Sprintf("at://%s/%s/%s", did, commit.Collection, commit.RKey)
+	switch commit.Operation {
+	case "create", "update":
+		if commit.Record == nil {
+			slog.Warn("received nil record in preferences commit (preferences update silently dropped)",
+				slog.String("did", did),
+				slog.String("operation", commit.Operation))
+			return nil
+		}
+		if deleted, err := c.replay.deletedAfter(ctx, commit, uri); err != nil || deleted {
+			return err
+		}
+		prefs, err := users.ParsePreferences(did, uri, commit.CID, commit.Record)
+		if err != nil {
+			// Malformed records can't become valid on retry
+			slog.Warn("skipping invalid preferences record",
+				slog.String("did", did),
+				slog.String("error", err.Error()))
+			return nil
+		}
+		prefs.Rev = commit.Rev
+		if err := c.preferencesRepo.Upsert(ctx, prefs); err != nil {
+			if errors.Is(err, users.ErrStaleRevision) {
+				c.replay.skipped(commit, uri)
+				return nil
+			}
+			return fmt.Errorf("failed to index preferences: %w", err)
+		}
+		log.Printf("Indexed preferences for user %s", did)
+		return nil
+	case "delete":
+		// A delete replayed after the preferences were written again must not remove them
+		existing, err := c.preferencesRepo.Get(ctx, did)
+		if err != nil && !errors.Is(err, users.ErrPreferencesNotFound) {
+			return fmt.Errorf("failed to get preferences: %w", err)
+		}
+		if existing != nil && c.replay.stale(commit, uri, existing.Rev) {
+			return nil
+		}
+		if err := c.replay.recordDelete(ctx, commit, uri); err != nil {
+			return err
+		}
+		if err := c.preferencesRepo.Delete(ctx, did); err != nil {
+			return fmt.Errorf("failed to delete preferences: %w", err)
+		}
+		log.Printf("Cleared preferences for user %s", did)
+		return nil
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,137 @@
+package jetstream
+
+import (
+	"Coves/internal/atproto/identity"
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultUserConsumerWorkers is the number of workers resolving user events concurrently
+	DefaultUserConsumerWorkers = 8
+
+	// userConsumerQueueSize bounds each worker's queue; a full queue stalls the read loop
+	userConsumerQueueSize = 64
+)
+
+// WithIdentityWorkers sets how many workers handle user events concurrently (default 8)
+// Identity lookups dominate a user event's handling time, so a burst of profile updates from a
+// busy PDS otherwise puts the consumer minutes behind. Values below 1 use the default.
+func WithIdentityWorkers(workers int) ConsumerOption {
+	return func(c *UserEventConsumer) {
+		if workers >= 1 {
+			c.workers = workers
+		}
+	}
+}
+
+// StartWorkers starts the worker pool that queued events are handled on
+// Events are assigned to workers by DID, so each account's events are applied in order while
+// different accounts resolve concurrently. Workers stop when ctx is cancelled; calling it again
+// is a no-op.
+func (c *UserEventConsumer) StartWorkers(ctx context.Context) {
+	c.startOnce.Do(func() {
+		for _, queue := range c.queues {
+			go func(queue chan *JetstreamEvent) {
+				for {
+					select {
+					case <-ctx.Done():
+						return
+					case event := <-queue:
+						if err := c.HandleEvent(ctx, event); err != nil {
+							log.Printf("Error handling user event: %v", err)
+						}
+						c.queueDepth.Add(-1)
+					}
+				}
+			}(queue)
+		}
+	})
+}
+
+// Enqueue queues an event on the worker for its DID
+// Blocks while that worker's queue is full, so a slow resolver applies backpressure to the
+// connection instead of events piling up in memory.
+func (c *UserEventConsumer) Enqueue(ctx context.Context, event *JetstreamEvent) error {
+	queue := c.queues[workerIndex(userEventDID(event), len(c.queues))]
+	c.queueDepth.Add(1)
+	select {
+	case queue <- event:
+		return nil
+	case <-ctx.Done():
+		c.queueDepth.Add(-1)
+		return ctx.Err()
+	}
+}
+
+// Queued returns an EventHandler that enqueues events on the worker pool instead of handling
+// them inline, for registering with a JetstreamDispatcher; start the pool with StartWorkers
+func (c *UserEventConsumer) Queued() EventHandler {
+	return queuedUserEvents{consumer: c}
+}
+
+type queuedUserEvents struct {
+	consumer *UserEventConsumer
+}
+
+func (q queuedUserEvents) HandleEvent(ctx context.Context, event *JetstreamEvent) error {
+	return q.consumer.Enqueue(ctx, event)
+}
+
+// UserEventQueueDepth returns the number of user events queued or being handled
+func (c *UserEventConsumer) UserEventQueueDepth() int64 {
+	return c.queueDepth.Load()
+}
+
+// IdentityResolutions returns how many identity lookups the consumer has made and their
+// total latency, since startup
+func (c *UserEventConsumer) IdentityResolutions() (int64, time.Duration) {
+	return c.resolver.count.Load(), time.Duration(c.resolver.nanos.Load())
+}
+
+// userEventDID returns the account an event belongs to
+func userEventDID(event *JetstreamEvent) string {
+	switch {
+	case event.Did != "":
+		return event.Did
+	case event.Identity != nil:
+		return event.Identity.Did
+	case event.Account != nil:
+		return event.Account.Did
+	}
+	return ""
+}
+
+// timedResolver records the latency of every identity lookup the consumer makes
+type timedResolver struct {
+	identity.Resolver
+	count atomic.Int64
+	nanos atomic.Int64
+}
+
+func (r *timedResolver) observe(start time.Time) {
+	r.count.Add(1)
+	r.nanos.Add(int64(time.Since(start)))
+}
+
+func (r *timedResolver) Resolve(ctx context.Context, identifier string) (*identity.Identity, error) {
+	defer r.observe(time.Now())
+	return r.Resolver.Resolve(ctx, identifier)
+}
+
+func (r *timedResolver) ResolveHandle(ctx context.Context, handle string) (string, string, error) {
+	defer r.observe(time.Now())
+	return r.Resolver.ResolveHandle(ctx, handle)
+}
+
+func (r *timedResolver) ResolveDID(ctx context.Context, did string) (*identity.DIDDocument, error) {
+	defer r.observe(time.Now())
+	return r.Resolver.ResolveDID(ctx, did)
+}
+
+func (r *timedResolver) Purge(ctx context.Context, identifier string) error {
+	defer r.observe(time.Now())
+	return r.Resolver.Purge(ctx, identifier)
+}
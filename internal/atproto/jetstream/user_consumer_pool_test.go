@@ -0,0 +1,152 @@
+package jetstream
+
+import (
+	"Coves/internal/core/users"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sleepyResolver is an identity resolver whose every lookup takes delay, like a PLC round trip
+type sleepyResolver struct {
+	mockIdentityResolverForUser
+	delay time.Duration
+}
+
+func (r *sleepyResolver) Purge(ctx context.Context, identifier string) error {
+	time.Sleep(r.delay)
+	return nil
+}
+
+// handleLogUserService records each account's handle updates in the order they were applied
+type handleLogUserService struct {
+	mockUserService
+	mu      sync.Mutex
+	handles map[string][]string
+}
+
+func newHandleLogUserService(dids []string) *handleLogUserService {
+	s := &handleLogUserService{mockUserService: *newMockUserService(), handles: make(map[string][]string)}
+	for _, did := range dids {
+		s.users[did] = &users.User{DID: did, Handle: did + ".old"}
+	}
+	return s
+}
+
+func (s *handleLogUserService) GetUserByDID(ctx context.Context, did string) (*users.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[did]
+	if !ok {
+		return nil, users.ErrUserNotFound
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (s *handleLogUserService) UpdateHandle(ctx context.Context, did, handle string) (*users.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[did].Handle = handle
+	s.handles[did] = append(s.handles[did], handle)
+	return s.users[did], nil
+}
+
+// runIdentityBurst queues rounds handle changes for each DID and waits for the pool to drain
+func runIdentityBurst(t *testing.T, workers int, dids []string, rounds int, delay time.Duration) (*handleLogUserService, *UserEventConsumer, time.Duration) {
+	t.Helper()
+	userService := newHandleLogUserService(dids)
+	consumer := NewUserEventConsumer(userService, &sleepyResolver{delay: delay}, "", "", WithIdentityWorkers(workers))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	consumer.StartWorkers(ctx)
+
+	start := time.Now()
+	for round := 0; round < rounds; round++ {
+		for _, did := range dids {
+			event := &JetstreamEvent{Did: did, Kind: "identity", Identity: &IdentityEvent{Did: did, Handle: fmt.Sprintf("%s.h%d", did, round)}}
+			if err := consumer.Enqueue(ctx, event); err != nil {
+				t.Fatalf("Enqueue failed: %v", err)
+			}
+		}
+	}
+	deadline := time.Now().Add(30 * time.Second)
+	for consumer.UserEventQueueDepth() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Pool didn't drain: %d events left", consumer.UserEventQueueDepth())
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return userService, consumer, time.Since(start)
+}
+
+func TestUserConsumerPool_ThroughputScalesWithWorkers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping load test in short mode")
+	}
+
+	dids := make([]string, 32)
+	for i := range dids {
+		dids[i] = fmt.Sprintf("did:plc:load%02d", i)
+	}
+	const rounds = 2
+	const delay = 5 * time.Millisecond // Each handle change purges two cache entries
+
+	_, _, serial := runIdentityBurst(t, 1, dids, rounds, delay)
+	_, consumer, pooled := runIdentityBurst(t, 8, dids, rounds, delay)
+
+	t.Logf("1 worker: %v, 8 workers: %v", serial, pooled)
+	if pooled*3 > serial {
+		t.Errorf("Expected 8 workers to be at least 3x faster than 1, got %v vs %v", pooled, serial)
+	}
+
+	count, latency := consumer.IdentityResolutions()
+	if want := int64(len(dids) * rounds * 2); count != want {
+		t.Errorf("Expected %d identity lookups recorded, got %d", want, count)
+	}
+	if latency < time.Duration(count)*delay {
+		t.Errorf("Expected at least %v of resolution latency recorded, got %v", time.Duration(count)*delay, latency)
+	}
+}
+
+func TestUserConsumerPool_PreservesPerDIDOrder(t *testing.T) {
+	dids := []string{"did:plc:alice", "did:plc:bob", "did:plc:carol", "did:plc:dave"}
+	const rounds = 25
+
+	userService, _, _ := runIdentityBurst(t, 8, dids, rounds, 0)
+
+	for _, did := range dids {
+		got := userService.handles[did]
+		if len(got) != rounds {
+			t.Fatalf("Expected %d handle updates for %s, got %d", rounds, did, len(got))
+		}
+		for round, handle := range got {
+			if want := fmt.Sprintf("%s.h%d", did, round); handle != want {
+				t.Fatalf("Updates for %s applied out of order: position %d is %s, want %s", did, round, handle, want)
+			}
+		}
+	}
+}
+
+func TestUserConsumerPool_Backpressure(t *testing.T) {
+	// Nothing drains the single worker's queue, so enqueueing blocks once it's full
+	consumer := NewUserEventConsumer(newMockUserService(), &mockIdentityResolverForUser{}, "", "", WithIdentityWorkers(1))
+	ctx := context.Background()
+	event := &JetstreamEvent{Did: "did:plc:alice", Kind: "identity"}
+	for i := 0; i < userConsumerQueueSize; i++ {
+		if err := consumer.Enqueue(ctx, event); err != nil {
+			t.Fatalf("Enqueue %d failed: %v", i, err)
+		}
+	}
+
+	blockedCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := consumer.Enqueue(blockedCtx, event); err != context.DeadlineExceeded {
+		t.Fatalf("Expected a full queue to block until the deadline, got %v", err)
+	}
+	if depth := consumer.UserEventQueueDepth(); depth != userConsumerQueueSize {
+		t.Errorf("Expected queue depth %d, got %d", userConsumerQueueSize, depth)
+	}
+}
@@ -704,6 +704,100 @@ func TestUserConsumer_PropagatesUpdateProfileError(t *testing.T) {
 	})
 }
 
+// mockVoteNullifier records vote nullification calls
+type mockVoteNullifier struct {
+	removed  []string
+	restored []string
+	reasons  [][]string
+}
+
+func (m *mockVoteNullifier) RemoveVotesByVoter(ctx context.Context, voterDID, reason string) (int, error) {
+	m.removed = append(m.removed, voterDID+":"+reason)
+	return 0, nil
+}
+
+func (m *mockVoteNullifier) RestoreVotesByVoter(ctx context.Context, voterDID string, reasons ...string) (int, error) {
+	m.restored = append(m.restored, voterDID)
+	m.reasons = append(m.reasons, reasons)
+	return 0, nil
+}
+
+func TestUserConsumer_AccountStatusNullifiesVotes(t *testing.T) {
+	mockService := newMockUserService()
+	mockService.users["did:plc:testuser"] = &users.User{DID: "did:plc:testuser", Handle: "testuser.bsky.social"}
+	nullifier := &mockVoteNullifier{}
+	consumer := NewUserEventConsumer(mockService, &mockIdentityResolverForUser{}, "wss://jetstream.example.com", "", WithVoteNullifier(nullifier))
+	ctx := context.Background()
+
+	accountEvent := func(did, status string, active bool) []byte {
+		return mustMarshalEvent(&JetstreamEvent{
+			Did:     did,
+			Kind:    "account",
+			Account: &AccountEvent{Did: did, Status: status, Active: active},
+		})
+	}
+
+	events := [][]byte{
+		accountEvent("did:plc:testuser", "takendown", false),
+		accountEvent("did:plc:testuser", "suspended", false),
+		accountEvent("did:plc:testuser", "deactivated", false), // user-initiated: ignored
+		accountEvent("did:plc:stranger", "takendown", false),   // not a Coves user: ignored
+		accountEvent("did:plc:testuser", "", true),
+	}
+	for _, data := range events {
+		if err := consumer.handleEvent(ctx, data); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if len(nullifier.removed) != 2 || nullifier.removed[0] != "did:plc:testuser:takendown" || nullifier.removed[1] != "did:plc:testuser:suspended" {
+		t.Errorf("Unexpected nullifications: %v", nullifier.removed)
+	}
+	if len(nullifier.restored) != 1 || nullifier.restored[0] != "did:plc:testuser" {
+		t.Fatalf("Expected one restore for the reactivated user, got %v", nullifier.restored)
+	}
+	// Reactivation must not undo an admin nullification
+	for _, reason := range nullifier.reasons[0] {
+		if reason == "admin" {
+			t.Errorf("Reactivation should only restore account-status nullifications, got reasons %v", nullifier.reasons[0])
+		}
+	}
+}
+
+// mockAccountStatusRecorder records account status transitions
+type mockAccountStatusRecorder struct {
+	deactivated map[string]bool
+}
+
+func (m *mockAccountStatusRecorder) SetDeactivated(ctx context.Context, did string, deactivated bool) error {
+	m.deactivated[did] = deactivated
+	return nil
+}
+
+func TestUserConsumer_AccountStatusRecorded(t *testing.T) {
+	recorder := &mockAccountStatusRecorder{deactivated: map[string]bool{}}
+	consumer := NewUserEventConsumer(newMockUserService(), &mockIdentityResolverForUser{}, "wss://jetstream.example.com", "", WithAccountStatusRecorder(recorder))
+	ctx := context.Background()
+
+	for _, account := range []*AccountEvent{
+		{Did: "did:plc:gone", Status: "deactivated", Active: false},
+		{Did: "did:plc:back", Status: "deactivated", Active: false},
+		{Did: "did:plc:back", Active: true},
+	} {
+		data := mustMarshalEvent(&JetstreamEvent{Did: account.Did, Kind: "account", Account: account})
+		if err := consumer.handleEvent(ctx, data); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+
+	if !recorder.deactivated["did:plc:gone"] {
+		t.Error("Expected did:plc:gone recorded as deactivated")
+	}
+	if recorder.deactivated["did:plc:back"] {
+		t.Error("Expected did:plc:back recorded as active again")
+	}
+}
+
 func TestExtractBlobCID(t *testing.T) {
 	t.Run("extracts CID from valid blob structure", func(t *testing.T) {
 		blob := map[string]interface{}{
@@ -814,3 +908,123 @@ func mustMarshalEvent(event *JetstreamEvent) []byte {
 	}
 	return data
 }
+
+// mockPreferencesRepo records indexed preferences in memory
+type mockPreferencesRepo struct {
+	prefs   map[string]*users.Preferences
+	deleted []string
+}
+
+func (m *mockPreferencesRepo) Upsert(ctx context.Context, prefs *users.Preferences) error {
+	m.prefs[prefs.UserDID] = prefs
+	return nil
+}
+
+func (m *mockPreferencesRepo) Get(ctx context.Context, userDID string) (*users.Preferences, error) {
+	if p, ok := m.prefs[userDID]; ok {
+		return p, nil
+	}
+	return nil, users.ErrPreferencesNotFound
+}
+
+func (m *mockPreferencesRepo) Delete(ctx context.Context, userDID string) error {
+	delete(m.prefs, userDID)
+	m.deleted = append(m.deleted, userDID)
+	return nil
+}
+
+func TestUserConsumer_HandlePreferencesCommit(t *testing.T) {
+	preferencesEvent := func(did, operation, rkey string, record map[string]interface{}) []byte {
+		return mustMarshalEvent(&JetstreamEvent{
+			Did:    did,
+			TimeUS: time.Now().UnixMicro(),
+			Kind:   "commit",
+			Commit: &CommitEvent{
+				Rev:        "rev123",
+				Operation:  operation,
+				Collection: users.PreferencesCollection,
+				RKey:       rkey,
+				CID:        "bafyprefs",
+				Record:     record,
+			},
+		})
+	}
+
+	newConsumer := func() (*UserEventConsumer, *mockPreferencesRepo) {
+		mockService := newMockUserService()
+		mockService.users["did:plc:testuser"] = &users.User{DID: "did:plc:testuser", Handle: "testuser.bsky.social"}
+		repo := &mockPreferencesRepo{prefs: make(map[string]*users.Preferences)}
+		consumer := NewUserEventConsumer(mockService, &mockIdentityResolverForUser{}, "wss://jetstream.example.com", "", WithPreferencesRepository(repo))
+		return consumer, repo
+	}
+	ctx := context.Background()
+
+	t.Run("indexes preferences and keeps unknown fields", func(t *testing.T) {
+		consumer, repo := newConsumer()
+		record := map[string]interface{}{
+			"$type":               users.PreferencesCollection,
+			"hideAggregatorPosts": true,
+			"futurePreference":    "kept",
+		}
+		if err := consumer.handleEvent(ctx, preferencesEvent("did:plc:testuser", "create", "self", record)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		prefs := repo.prefs["did:plc:testuser"]
+		if prefs == nil {
+			t.Fatal("Expected preferences to be indexed")
+		}
+		if !prefs.HideAggregatorPosts {
+			t.Error("Expected hideAggregatorPosts to be true")
+		}
+		if prefs.URI != "at://did:plc:testuser/social.coves.actor.preferences/self" || prefs.CID != "bafyprefs" {
+			t.Errorf("Unexpected record reference: %s %s", prefs.URI, prefs.CID)
+		}
+		var stored map[string]interface{}
+		if err := json.Unmarshal(prefs.Record, &stored); err != nil || stored["futurePreference"] != "kept" {
+			t.Errorf("Expected full record to be stored, got %s", prefs.Record)
+		}
+	})
+
+	t.Run("delete removes preferences", func(t *testing.T) {
+		consumer, repo := newConsumer()
+		if err := consumer.handleEvent(ctx, preferencesEvent("did:plc:testuser", "create", "self", map[string]interface{}{"hideAggregatorPosts": true})); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if err := consumer.handleEvent(ctx, preferencesEvent("did:plc:testuser", "delete", "self", nil)); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, ok := repo.prefs["did:plc:testuser"]; ok {
+			t.Error("Expected preferences to be deleted")
+		}
+	})
+
+	t.Run("skips invalid records, other rkeys, and unknown users", func(t *testing.T) {
+		consumer, repo := newConsumer()
+		events := [][]byte{
+			preferencesEvent("did:plc:testuser", "create", "self", map[string]interface{}{"hideAggregatorPosts": "yes"}),
+			preferencesEvent("did:plc:testuser", "create", "other", map[string]interface{}{"hideAggregatorPosts": true}),
+			preferencesEvent("did:plc:stranger", "create", "self", map[string]interface{}{"hideAggregatorPosts": true}),
+		}
+		for _, data := range events {
+			if err := consumer.handleEvent(ctx, data); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		if len(repo.prefs) != 0 {
+			t.Errorf("Expected nothing indexed, got %v", repo.prefs)
+		}
+	})
+
+	t.Run("ignores preferences without a repository", func(t *testing.T) {
+		mockService := newMockUserService()
+		mockService.users["did:plc:testuser"] = &users.User{DID: "did:plc:testuser", Handle: "testuser.bsky.social"}
+		consumer := NewUserEventConsumer(mockService, &mockIdentityResolverForUser{}, "wss://jetstream.example.com", "")
+		if err := consumer.handleEvent(ctx, preferencesEvent("did:plc:testuser", "create", "self", map[string]interface{}{"hideAggregatorPosts": true})); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(mockService.updatedCalls) != 0 {
+			t.Errorf("Preferences must not be treated as a profile update, got %d calls", len(mockService.updatedCalls))
+		}
+	})
+}
@@ -0,0 +1,87 @@
+// Package lexicon reads the lexicon documents checked into this directory
+// They're embedded in the binary so the server can check its Jetstream consumers subscribe to
+// collections that actually exist, without needing the source tree at runtime.
+package lexicon
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+//go:embed com/atproto/*/*.json social/coves/*/*.json social/coves/*/*/*.json
+var documents embed.FS
+
+// Main definition types
+const (
+	TypeRecord    = "record"
+	TypeQuery     = "query"
+	TypeProcedure = "procedure"
+)
+
+// Schema is a lexicon document's NSID and the type of its main definition
+// MainType is "" for documents with only secondary definitions (e.g. defs.json)
+type Schema struct {
+	ID       string
+	MainType string
+	Path     string // Relative to the lexicon root
+}
+
+// Schemas indexes lexicon documents by NSID
+type Schemas map[string]Schema
+
+// Load parses the lexicon documents embedded in the binary
+func Load() (Schemas, error) {
+	return Parse(documents)
+}
+
+// Parse parses every .json lexicon document under fsys
+// A document's id must match its path (social/coves/feed/vote.json is social.coves.feed.vote).
+func Parse(fsys fs.FS) (Schemas, error) {
+	schemas := make(Schemas)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(name) != ".json" {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("failed to read lexicon %s: %w", name, err)
+		}
+		var doc struct {
+			Defs map[string]struct {
+				Type string `json:"type"`
+			} `json:"defs"`
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("failed to parse lexicon %s: %w", name, err)
+		}
+
+		wantID := strings.ReplaceAll(strings.TrimSuffix(name, ".json"), "/", ".")
+		if doc.ID != wantID {
+			return fmt.Errorf("lexicon %s has id %q, want %q", name, doc.ID, wantID)
+		}
+		if existing, ok := schemas[doc.ID]; ok {
+			return fmt.Errorf("lexicon %s is defined by both %s and %s", doc.ID, existing.Path, name)
+		}
+		schemas[doc.ID] = Schema{ID: doc.ID, MainType: doc.Defs["main"].Type, Path: name}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return schemas, nil
+}
+
+// Record reports whether nsid is defined as a record type, i.e. something that can be a
+// repository collection
+func (s Schemas) Record(nsid string) bool {
+	return s[nsid].MainType == TypeRecord
+}
@@ -0,0 +1,64 @@
+package lexicon
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	schemas, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	tests := []struct {
+		nsid     string
+		mainType string
+	}{
+		{"social.coves.community.subscription", TypeRecord},
+		{"social.coves.community.subscribe", TypeProcedure},
+		{"social.coves.feed.vote", TypeRecord},
+		{"social.coves.community.comment", TypeRecord},
+		{"social.coves.community.get", TypeQuery},
+		{"social.coves.community.defs", ""},
+		{"com.atproto.repo.strongRef", "object"},
+	}
+	for _, tt := range tests {
+		schema, ok := schemas[tt.nsid]
+		if !ok {
+			t.Errorf("%s not loaded", tt.nsid)
+			continue
+		}
+		if schema.MainType != tt.mainType {
+			t.Errorf("%s main type = %q, want %q", tt.nsid, schema.MainType, tt.mainType)
+		}
+	}
+	if schemas.Record("social.coves.feed.comment") {
+		t.Error("social.coves.feed.comment should not be a record")
+	}
+}
+
+func TestParse_IDMustMatchPath(t *testing.T) {
+	fsys := fstest.MapFS{
+		"social/coves/feed/vote.json": {Data: []byte(`{"lexicon":1,"id":"social.coves.feed.votes","defs":{"main":{"type":"record"}}}`)},
+	}
+	_, err := Parse(fsys)
+	if err == nil || !strings.Contains(err.Error(), `want "social.coves.feed.vote"`) {
+		t.Errorf("Parse() error = %v, want an id mismatch", err)
+	}
+}
+
+func TestParse_IgnoresNonJSON(t *testing.T) {
+	fsys := fstest.MapFS{
+		"social/coves/README.md":      {Data: []byte("# not a lexicon")},
+		"social/coves/feed/vote.json": {Data: []byte(`{"lexicon":1,"id":"social.coves.feed.vote","defs":{"main":{"type":"record"}}}`)},
+	}
+	schemas, err := Parse(fsys)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(schemas) != 1 || !schemas.Record("social.coves.feed.vote") {
+		t.Errorf("Parse() = %+v, want the vote record only", schemas)
+	}
+}
@@ -6,6 +6,7 @@ package pds
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -39,6 +40,11 @@ type Client interface {
 	// If swapRecord CID is provided, the operation fails if the current CID doesn't match.
 	PutRecord(ctx context.Context, collection string, rkey string, record any, swapRecord string) (uri string, cid string, err error)
 
+	// ApplyWrites applies a batch of creates, updates and deletes to a repository in one commit.
+	// Either every write is applied or none is. repo is normally the authenticated user's DID.
+	// Returns one result per write, in order.
+	ApplyWrites(ctx context.Context, repo string, writes []WriteOp) ([]WriteResult, error)
+
 	// UploadBlob uploads binary data to the user's PDS repository.
 	// Returns a BlobRef that can be used in records.
 	// Note: The mimeType parameter is accepted for interface compatibility, but the PDS
@@ -73,6 +79,38 @@ type RecordResponse struct {
 	Value map[string]any
 }
 
+// WriteAction is the kind of change a WriteOp makes
+type WriteAction string
+
+const (
+	WriteActionCreate WriteAction = "create"
+	WriteActionUpdate WriteAction = "update"
+	WriteActionDelete WriteAction = "delete"
+)
+
+// MaxApplyWrites is the most writes the PDS accepts in one applyWrites call
+const MaxApplyWrites = 200
+
+// maxApplyWritesBodyBytes is the PDS limit on a JSON request body (150KB)
+const maxApplyWritesBodyBytes = 150 * 1024
+
+// WriteOp is a single change in an ApplyWrites batch.
+// Collection is always required. RKey is optional for creates (the PDS generates a TID)
+// and required for updates and deletes. Value is the record for creates and updates.
+type WriteOp struct {
+	Action     WriteAction
+	Collection string
+	RKey       string
+	Value      any
+}
+
+// WriteResult is the outcome of one WriteOp. URI and CID are empty for deletes.
+type WriteResult struct {
+	Action WriteAction
+	URI    string
+	CID    string
+}
+
 // client implements the Client interface using indigo's APIClient.
 // This single implementation works for both OAuth (DPoP) and password (Bearer) auth
 // because APIClient handles the authentication details internally.
@@ -97,6 +135,10 @@ func wrapAPIError(err error, operation string) error {
 	if errors.As(err, &apiErr) {
 		switch apiErr.StatusCode {
 		case 400:
+			// getRecord reports a missing record as 400 RecordNotFound rather than 404
+			if apiErr.Name == "RecordNotFound" {
+				return fmt.Errorf("%s: %w: %s", operation, ErrNotFound, apiErr.Message)
+			}
 			return fmt.Errorf("%s: %w: %s", operation, ErrBadRequest, apiErr.Message)
 		case 401:
 			return fmt.Errorf("%s: %w: %s", operation, ErrUnauthorized, apiErr.Message)
@@ -281,3 +323,103 @@ func (c *client) UploadBlob(ctx context.Context, data []byte, mimeType string) (
 		Size:     int(result.Blob.Size),
 	}, nil
 }
+
+// ApplyWrites applies a batch of writes to a repository in one commit.
+// The batch is validated before anything is sent; an invalid batch returns ErrBadRequest,
+// or ErrPayloadTooLarge when the encoded request would exceed the PDS body limit.
+func (c *client) ApplyWrites(ctx context.Context, repo string, writes []WriteOp) ([]WriteResult, error) {
+	payload, err := buildApplyWritesPayload(repo, writes)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Results []struct {
+			Type string `json:"$type"`
+			URI  string `json:"uri"`
+			CID  string `json:"cid"`
+		} `json:"results"`
+	}
+
+	err = c.apiClient.Post(ctx, syntax.NSID("com.atproto.repo.applyWrites"), payload, &result)
+	if err != nil {
+		return nil, wrapAPIError(err, "applyWrites")
+	}
+
+	// Results are positional; the PDS returns one per write. Older PDS versions omit them
+	// entirely, in which case only the actions are known.
+	results := make([]WriteResult, len(writes))
+	for i, write := range writes {
+		results[i].Action = write.Action
+		if i < len(result.Results) {
+			results[i].URI = result.Results[i].URI
+			results[i].CID = result.Results[i].CID
+		}
+	}
+
+	return results, nil
+}
+
+// buildApplyWritesPayload validates a batch and builds the com.atproto.repo.applyWrites body
+func buildApplyWritesPayload(repo string, writes []WriteOp) (map[string]any, error) {
+	if repo == "" {
+		return nil, fmt.Errorf("applyWrites: %w: repo is required", ErrBadRequest)
+	}
+	if len(writes) == 0 {
+		return nil, fmt.Errorf("applyWrites: %w: no writes", ErrBadRequest)
+	}
+	if len(writes) > MaxApplyWrites {
+		return nil, fmt.Errorf("applyWrites: %w: %d writes exceeds the limit of %d", ErrBadRequest, len(writes), MaxApplyWrites)
+	}
+
+	ops := make([]map[string]any, len(writes))
+	for i, write := range writes {
+		if write.Collection == "" {
+			return nil, fmt.Errorf("applyWrites: %w: write %d has no collection", ErrBadRequest, i)
+		}
+
+		op := map[string]any{
+			"$type":      "com.atproto.repo.applyWrites#" + string(write.Action),
+			"collection": write.Collection,
+		}
+		switch write.Action {
+		case WriteActionCreate:
+			if write.Value == nil {
+				return nil, fmt.Errorf("applyWrites: %w: create %d has no value", ErrBadRequest, i)
+			}
+			if write.RKey != "" {
+				op["rkey"] = write.RKey
+			}
+			op["value"] = write.Value
+		case WriteActionUpdate:
+			if write.RKey == "" || write.Value == nil {
+				return nil, fmt.Errorf("applyWrites: %w: update %d needs an rkey and a value", ErrBadRequest, i)
+			}
+			op["rkey"] = write.RKey
+			op["value"] = write.Value
+		case WriteActionDelete:
+			if write.RKey == "" {
+				return nil, fmt.Errorf("applyWrites: %w: delete %d has no rkey", ErrBadRequest, i)
+			}
+			op["rkey"] = write.RKey
+		default:
+			return nil, fmt.Errorf("applyWrites: %w: write %d has unknown action %q", ErrBadRequest, i, write.Action)
+		}
+		ops[i] = op
+	}
+
+	payload := map[string]any{
+		"repo":   repo,
+		"writes": ops,
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("applyWrites: %w: %v", ErrBadRequest, err)
+	}
+	if len(encoded) > maxApplyWritesBodyBytes {
+		return nil, fmt.Errorf("applyWrites: %w: request is %d bytes, limit is %d", ErrPayloadTooLarge, len(encoded), maxApplyWritesBodyBytes)
+	}
+
+	return payload, nil
+}
@@ -175,6 +175,38 @@ func TestNewFromAccessToken(t *testing.T) {
 	}
 }
 
+// TestNewPublicClient validates input checks and that public reads send no credentials.
+func TestNewPublicClient(t *testing.T) {
+	if _, err := NewPublicClient("", "did:plc:12345"); err == nil || !strings.Contains(err.Error(), "host is required") {
+		t.Errorf("expected host error, got %v", err)
+	}
+	if _, err := NewPublicClient("https://pds.example.com", ""); err == nil || !strings.Contains(err.Error(), "did is required") {
+		t.Errorf("expected did error, got %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("expected no Authorization header, got %q", auth)
+		}
+		if repo := r.URL.Query().Get("repo"); repo != "did:plc:community" {
+			t.Errorf("repo = %q, want did:plc:community", repo)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"RecordNotFound","message":"Could not locate record"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewPublicClient(server.URL, "did:plc:community")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = client.GetRecord(context.Background(), "social.coves.community.post", "3kmissing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound for RecordNotFound, got %v", err)
+	}
+}
+
 // TestNewFromPasswordAuth validates factory function input validation.
 func TestNewFromPasswordAuth(t *testing.T) {
 	tests := []struct {
@@ -958,6 +990,12 @@ func TestWrapAPIError(t *testing.T) {
 			operation: "createRecord",
 			wantTyped: ErrBadRequest,
 		},
+		{
+			name:      "400 RecordNotFound maps to ErrNotFound",
+			err:       &atclient.APIError{StatusCode: 400, Name: "RecordNotFound", Message: "Could not locate record"},
+			operation: "getRecord",
+			wantTyped: ErrNotFound,
+		},
 		{
 			name:      "409 maps to ErrConflict",
 			err:       &atclient.APIError{StatusCode: 409, Name: "InvalidSwap", Message: "Record CID mismatch"},
@@ -1403,3 +1441,176 @@ func TestClient_TypedErrors_PutRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_ApplyWrites(t *testing.T) {
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("expected POST request, got %s", r.Method)
+		}
+		if r.URL.Path != "/xrpc/com.atproto.repo.applyWrites" {
+			t.Errorf("path = %q, want /xrpc/com.atproto.repo.applyWrites", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"commit": map[string]any{"cid": "bafycommit", "rev": "3kjzl5kcb2s2v"},
+			"results": []any{
+				map[string]any{"$type": "com.atproto.repo.applyWrites#deleteResult"},
+				map[string]any{
+					"$type": "com.atproto.repo.applyWrites#createResult",
+					"uri":   "at://did:plc:test/social.coves.feed.vote/3kjzl5kcb2s2w",
+					"cid":   "bafycreated",
+				},
+				map[string]any{
+					"$type": "com.atproto.repo.applyWrites#updateResult",
+					"uri":   "at://did:plc:test/social.coves.actor.profile/self",
+					"cid":   "bafyupdated",
+				},
+			},
+		})
+	}))
+	defer server.Close()
+
+	apiClient := atclient.NewAPIClient(server.URL)
+	apiClient.Auth = &bearerAuth{token: "test-token"}
+	c := &client{apiClient: apiClient, did: "did:plc:test", host: server.URL}
+
+	results, err := c.ApplyWrites(context.Background(), "did:plc:test", []WriteOp{
+		{Action: WriteActionDelete, Collection: "social.coves.feed.vote", RKey: "3kjzl5kcb2s2v"},
+		{Action: WriteActionCreate, Collection: "social.coves.feed.vote", RKey: "3kjzl5kcb2s2w", Value: map[string]any{"direction": "down"}},
+		{Action: WriteActionUpdate, Collection: "social.coves.actor.profile", RKey: "self", Value: map[string]any{"displayName": "Test"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if payload["repo"] != "did:plc:test" {
+		t.Errorf("repo = %v, want did:plc:test", payload["repo"])
+	}
+	if _, exists := payload["validate"]; exists {
+		t.Error("validate should be left to the PDS default")
+	}
+	writes, ok := payload["writes"].([]any)
+	if !ok || len(writes) != 3 {
+		t.Fatalf("writes = %v, want 3 ops", payload["writes"])
+	}
+
+	del := writes[0].(map[string]any)
+	if del["$type"] != "com.atproto.repo.applyWrites#delete" || del["rkey"] != "3kjzl5kcb2s2v" {
+		t.Errorf("delete op = %v", del)
+	}
+	if _, exists := del["value"]; exists {
+		t.Error("delete op should not carry a value")
+	}
+	create := writes[1].(map[string]any)
+	if create["$type"] != "com.atproto.repo.applyWrites#create" || create["collection"] != "social.coves.feed.vote" {
+		t.Errorf("create op = %v", create)
+	}
+	if value, ok := create["value"].(map[string]any); !ok || value["direction"] != "down" {
+		t.Errorf("create value = %v", create["value"])
+	}
+	update := writes[2].(map[string]any)
+	if update["$type"] != "com.atproto.repo.applyWrites#update" || update["rkey"] != "self" {
+		t.Errorf("update op = %v", update)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3", len(results))
+	}
+	if results[0].Action != WriteActionDelete || results[0].URI != "" {
+		t.Errorf("results[0] = %+v", results[0])
+	}
+	if results[1].URI != "at://did:plc:test/social.coves.feed.vote/3kjzl5kcb2s2w" || results[1].CID != "bafycreated" {
+		t.Errorf("results[1] = %+v", results[1])
+	}
+	if results[2].Action != WriteActionUpdate || results[2].CID != "bafyupdated" {
+		t.Errorf("results[2] = %+v", results[2])
+	}
+}
+
+func TestBuildApplyWritesPayload_Validation(t *testing.T) {
+	vote := map[string]any{"direction": "up"}
+	tooMany := make([]WriteOp, MaxApplyWrites+1)
+	for i := range tooMany {
+		tooMany[i] = WriteOp{Action: WriteActionCreate, Collection: "social.coves.feed.vote", Value: vote}
+	}
+
+	tests := []struct {
+		name    string
+		repo    string
+		writes  []WriteOp
+		wantErr error
+	}{
+		{"missing repo", "", []WriteOp{{Action: WriteActionCreate, Collection: "c", Value: vote}}, ErrBadRequest},
+		{"no writes", "did:plc:test", nil, ErrBadRequest},
+		{"too many writes", "did:plc:test", tooMany, ErrBadRequest},
+		{"missing collection", "did:plc:test", []WriteOp{{Action: WriteActionCreate, Value: vote}}, ErrBadRequest},
+		{"create without value", "did:plc:test", []WriteOp{{Action: WriteActionCreate, Collection: "c"}}, ErrBadRequest},
+		{"update without rkey", "did:plc:test", []WriteOp{{Action: WriteActionUpdate, Collection: "c", Value: vote}}, ErrBadRequest},
+		{"delete without rkey", "did:plc:test", []WriteOp{{Action: WriteActionDelete, Collection: "c"}}, ErrBadRequest},
+		{"unknown action", "did:plc:test", []WriteOp{{Action: "upsert", Collection: "c", RKey: "r", Value: vote}}, ErrBadRequest},
+		{"oversized body", "did:plc:test", []WriteOp{{Action: WriteActionCreate, Collection: "c", Value: map[string]any{"content": strings.Repeat("x", maxApplyWritesBodyBytes)}}}, ErrPayloadTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := buildApplyWritesPayload(tt.repo, tt.writes)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected errors.Is(%v, %v) to be true", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Exactly the limit is fine, and a create may leave the rkey to the PDS
+	payload, err := buildApplyWritesPayload("did:plc:test", tooMany[:MaxApplyWrites])
+	if err != nil {
+		t.Fatalf("unexpected error at the limit: %v", err)
+	}
+	first := payload["writes"].([]map[string]any)[0]
+	if _, exists := first["rkey"]; exists {
+		t.Error("rkey should be omitted when empty")
+	}
+}
+
+// TestClient_TypedErrors_ApplyWrites tests that ApplyWrites returns typed errors.
+func TestClient_TypedErrors_ApplyWrites(t *testing.T) {
+	tests := []struct {
+		name         string
+		serverStatus int
+		wantErr      error
+	}{
+		{"401 returns ErrUnauthorized", http.StatusUnauthorized, ErrUnauthorized},
+		{"400 returns ErrBadRequest", http.StatusBadRequest, ErrBadRequest},
+		{"409 returns ErrConflict", http.StatusConflict, ErrConflict},
+		{"413 returns ErrPayloadTooLarge", http.StatusRequestEntityTooLarge, ErrPayloadTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.serverStatus)
+				json.NewEncoder(w).Encode(map[string]any{
+					"error":   "TestError",
+					"message": "Test error message",
+				})
+			}))
+			defer server.Close()
+
+			apiClient := atclient.NewAPIClient(server.URL)
+			apiClient.Auth = &bearerAuth{token: "test-token"}
+			c := &client{apiClient: apiClient, did: "did:plc:test", host: server.URL}
+
+			_, err := c.ApplyWrites(context.Background(), "did:plc:test", []WriteOp{
+				{Action: WriteActionDelete, Collection: "test.collection", RKey: "rkey"},
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected errors.Is(%v, %v) to be true", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -116,6 +116,25 @@ func NewFromAccessToken(host, did, accessToken string) (Client, error) {
 	}, nil
 }
 
+// NewPublicClient creates an unauthenticated PDS client for a repository.
+// Only public reads (GetRecord, ListRecords) succeed; writes fail with ErrUnauthorized.
+//
+// Used by the AppView to fetch records it missed on the firehose.
+func NewPublicClient(host, did string) (Client, error) {
+	if host == "" {
+		return nil, fmt.Errorf("host is required")
+	}
+	if did == "" {
+		return nil, fmt.Errorf("did is required")
+	}
+
+	return &client{
+		apiClient: atclient.NewAPIClient(host),
+		did:       did,
+		host:      host,
+	}, nil
+}
+
 // bearerAuth implements atclient.AuthMethod for simple Bearer token auth.
 // This is used for password-based sessions where DPoP is not required.
 type bearerAuth struct {
@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"Coves/internal/atproto/aturi"
 	"database/sql"
 	"strings"
 	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
 )
 
 // ExtractRKeyFromURI extracts the record key from an AT-URI
@@ -19,20 +22,10 @@ func ExtractRKeyFromURI(uri string) string {
 // ExtractCollectionFromURI extracts the collection from an AT-URI
 // Format: at://did/collection/rkey -> collection
 //
-// Returns:
-//   - Collection name (e.g., "social.coves.community.comment") if URI is well-formed
-//   - Empty string if URI is malformed or doesn't contain a collection segment
-//
-// Note: Empty string indicates "unknown/unsupported collection" and should be
-// handled as an invalid or unparseable URI by the caller. Callers should validate
-// the return value before using it for database queries or business logic.
+// Returns an empty string if the URI is malformed or has no collection segment; callers
+// treat that as an unknown/unsupported collection. See aturi.Parse for the accepted forms.
 func ExtractCollectionFromURI(uri string) string {
-	withoutScheme := strings.TrimPrefix(uri, "at://")
-	parts := strings.Split(withoutScheme, "/")
-	if len(parts) >= 2 {
-		return parts[1]
-	}
-	return ""
+	return aturi.Collection(uri)
 }
 
 // StringFromNull converts sql.NullString to string
@@ -44,6 +37,25 @@ func StringFromNull(ns sql.NullString) string {
 	return ""
 }
 
+// IsStaleRev reports whether a commit with repo revision incoming is no newer than the
+// indexed revision, so applying it would overwrite the record with an older (or the same) version
+// Revs are TIDs and order by time. An empty or unparseable rev on either side can't be
+// ordered and is never stale: rows indexed before revs were stored take the next update.
+func IsStaleRev(incoming, indexed string) bool {
+	if incoming == "" || indexed == "" {
+		return false
+	}
+	in, err := syntax.ParseTID(incoming)
+	if err != nil {
+		return false
+	}
+	stored, err := syntax.ParseTID(indexed)
+	if err != nil {
+		return false
+	}
+	return in.Integer() <= stored.Integer()
+}
+
 // ParseCreatedAt extracts and parses the createdAt timestamp from an atProto record
 // Falls back to time.Now() if the field is missing or invalid
 // This preserves chronological ordering during Jetstream replays and backfills
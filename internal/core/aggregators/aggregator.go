@@ -17,15 +17,38 @@ type Aggregator struct {
 	AvatarURL   string `json:"avatarUrl,omitempty" db:"avatar_url"`
 
 	// Metadata
-	MaintainerDID string `json:"maintainerDid,omitempty" db:"maintainer_did"`
-	SourceURL     string `json:"sourceUrl,omitempty" db:"source_url"`
-	RecordURI     string `json:"recordUri,omitempty" db:"record_uri"`
-	RecordCID     string `json:"recordCid,omitempty" db:"record_cid"`
-	ConfigSchema  []byte `json:"configSchema,omitempty" db:"config_schema"`
+	MaintainerDID string  `json:"maintainerDid,omitempty" db:"maintainer_did"`
+	SourceURL     string  `json:"sourceUrl,omitempty" db:"source_url"`
+	RecordURI     string  `json:"recordUri,omitempty" db:"record_uri"`
+	RecordCID     string  `json:"recordCid,omitempty" db:"record_cid"`
+	Rev           string  `json:"-" db:"rev"` // Repo rev of the commit that wrote the service record
+	ConfigSchema  []byte  `json:"configSchema,omitempty" db:"config_schema"`
+	RawRecord     *string `json:"-" db:"raw_record"` // Full service record JSON as received from the firehose
 
 	// Stats
 	CommunitiesUsing int `json:"communitiesUsing" db:"communities_using"`
 	PostsCreated     int `json:"postsCreated" db:"posts_created"`
+
+	// Hydrated by the directory queries (ListServices, GetService); empty elsewhere
+	MaintainerHandle string `json:"-" db:"-"` // Handle of MaintainerDID, when the maintainer is a known user
+	PDSURL           string `json:"-" db:"-"` // Aggregator's PDS, for building the avatar URL
+}
+
+// Service declaration limits, enforced when indexing social.coves.aggregator.service records
+const (
+	MaxDisplayNameLength = 64   // Characters
+	MaxDescriptionLength = 1000 // Characters
+)
+
+// AuthorizedCommunity is a community that has enabled an aggregator, for the public directory
+type AuthorizedCommunity struct {
+	AuthorizedAt time.Time
+	DID          string
+	Handle       string
+	Name         string
+	DisplayName  string
+	AvatarCID    string
+	PDSURL       string
 }
 
 // OAuthCredentials holds OAuth session data for aggregator authentication
@@ -113,18 +136,22 @@ func (c *AggregatorCredentials) IsOAuthTokenExpired() bool {
 // Authorization represents a community's authorization for an aggregator
 // Stored in community's repository: at://community_did/social.coves.aggregator.authorization/{rkey}
 type Authorization struct {
-	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
-	IndexedAt     time.Time  `json:"indexedAt" db:"indexed_at"`
-	DisabledAt    *time.Time `json:"disabledAt,omitempty" db:"disabled_at"`
-	AggregatorDID string     `json:"aggregatorDid" db:"aggregator_did"`
-	CommunityDID  string     `json:"communityDid" db:"community_did"`
-	CreatedBy     string     `json:"createdBy,omitempty" db:"created_by"`
-	DisabledBy    string     `json:"disabledBy,omitempty" db:"disabled_by"`
-	RecordURI     string     `json:"recordUri,omitempty" db:"record_uri"`
-	RecordCID     string     `json:"recordCid,omitempty" db:"record_cid"`
-	Config        []byte     `json:"config,omitempty" db:"config"`
-	ID            int        `json:"id" db:"id"`
-	Enabled       bool       `json:"enabled" db:"enabled"`
+	CreatedAt       time.Time  `json:"createdAt" db:"created_at"`
+	IndexedAt       time.Time  `json:"indexedAt" db:"indexed_at"`
+	DisabledAt      *time.Time `json:"disabledAt,omitempty" db:"disabled_at"`
+	MaxPostsPerHour *int       `json:"maxPostsPerHour,omitempty" db:"max_posts_per_hour"` // nil: DefaultMaxPostsPerHour
+	MaxPostsPerDay  *int       `json:"maxPostsPerDay,omitempty" db:"max_posts_per_day"`   // nil: no daily limit
+	AggregatorDID   string     `json:"aggregatorDid" db:"aggregator_did"`
+	CommunityDID    string     `json:"communityDid" db:"community_did"`
+	CreatedBy       string     `json:"createdBy,omitempty" db:"created_by"`
+	DisabledBy      string     `json:"disabledBy,omitempty" db:"disabled_by"`
+	RecordURI       string     `json:"recordUri,omitempty" db:"record_uri"`
+	RecordCID       string     `json:"recordCid,omitempty" db:"record_cid"`
+	Rev             string     `json:"-" db:"rev"` // Repo rev of the commit that wrote the record
+	Config          []byte     `json:"config,omitempty" db:"config"`
+	RawRecord       *string    `json:"-" db:"raw_record"` // Full record JSON as received from the firehose
+	ID              int        `json:"id" db:"id"`
+	Enabled         bool       `json:"enabled" db:"enabled"`
 }
 
 // AggregatorPost represents tracking of posts created by aggregators
@@ -138,6 +165,14 @@ type AggregatorPost struct {
 	ID            int       `json:"id" db:"id"`
 }
 
+// PostQuota is an aggregator's standing against one of its post limits in a community
+type PostQuota struct {
+	Window    string `json:"window"` // RateLimitWindowHour or RateLimitWindowDay
+	Limit     int    `json:"limit"`
+	Used      int    `json:"used"`
+	Remaining int    `json:"remaining"`
+}
+
 // EnableAggregatorRequest represents input for enabling an aggregator in a community
 type EnableAggregatorRequest struct {
 	CommunityDID   string                 `json:"communityDid"`     // Which community (resolved from identifier)
@@ -177,6 +212,24 @@ type GetAuthorizationsRequest struct {
 	Offset        int    `json:"offset"`
 }
 
+// ListServicesRequest represents query parameters for the public aggregator directory
+type ListServicesRequest struct {
+	Cursor string `json:"cursor,omitempty"` // Offset of the next page, from a previous response
+	Limit  int    `json:"limit"`
+}
+
+// ListServicesResponse is a page of the aggregator directory
+type ListServicesResponse struct {
+	Cursor      *string       `json:"cursor,omitempty"`
+	Aggregators []*Aggregator `json:"aggregators"`
+}
+
+// ServiceDetail is an aggregator with the communities that have enabled it
+type ServiceDetail struct {
+	Aggregator  *Aggregator
+	Communities []*AuthorizedCommunity
+}
+
 // ListForCommunityRequest represents query parameters for listing aggregators for a community
 type ListForCommunityRequest struct {
 	CommunityDID string `json:"communityDid"`          // Which community (resolved from identifier)
@@ -44,6 +44,11 @@ type mockRepository struct {
 	updateAPIKeyLastUsedFunc               func(ctx context.Context, did string) error
 	revokeAPIKeyFunc                       func(ctx context.Context, did string) error
 	listAggregatorsNeedingTokenRefreshFunc func(ctx context.Context, expiryBuffer time.Duration) ([]*AggregatorCredentials, error)
+	listServicesFunc                       func(ctx context.Context, limit, offset int) ([]*Aggregator, error)
+	listAuthorizedCommunitiesFunc          func(ctx context.Context, aggregatorDID string, limit int) ([]*AuthorizedCommunity, error)
+	getAuthorizationFunc                   func(ctx context.Context, aggregatorDID, communityDID string) (*Authorization, error)
+	countRecentPostsFunc                   func(ctx context.Context, aggregatorDID, communityDID string, since time.Time) (int, error)
+	getRecentPostsFunc                     func(ctx context.Context, aggregatorDID, communityDID string, since time.Time) ([]*AggregatorPost, error)
 }
 
 func (m *mockRepository) GetAggregator(ctx context.Context, did string) (*Aggregator, error) {
@@ -120,11 +125,36 @@ func (m *mockRepository) IsAggregator(ctx context.Context, did string) (bool, er
 	return false, nil
 }
 
+func (m *mockRepository) GetServicesByDIDs(ctx context.Context, dids []string) ([]*Aggregator, error) {
+	return nil, nil
+}
+
+func (m *mockRepository) ListServices(ctx context.Context, limit, offset int) ([]*Aggregator, error) {
+	if m.listServicesFunc != nil {
+		return m.listServicesFunc(ctx, limit, offset)
+	}
+	return nil, nil
+}
+
+func (m *mockRepository) GetService(ctx context.Context, did string) (*Aggregator, error) {
+	return m.GetAggregator(ctx, did)
+}
+
+func (m *mockRepository) ListAuthorizedCommunities(ctx context.Context, aggregatorDID string, limit int) ([]*AuthorizedCommunity, error) {
+	if m.listAuthorizedCommunitiesFunc != nil {
+		return m.listAuthorizedCommunitiesFunc(ctx, aggregatorDID, limit)
+	}
+	return nil, nil
+}
+
 func (m *mockRepository) CreateAuthorization(ctx context.Context, auth *Authorization) error {
 	return nil
 }
 
 func (m *mockRepository) GetAuthorization(ctx context.Context, aggregatorDID, communityDID string) (*Authorization, error) {
+	if m.getAuthorizationFunc != nil {
+		return m.getAuthorizationFunc(ctx, aggregatorDID, communityDID)
+	}
 	return nil, nil
 }
 
@@ -161,10 +191,16 @@ func (m *mockRepository) RecordAggregatorPost(ctx context.Context, aggregatorDID
 }
 
 func (m *mockRepository) CountRecentPosts(ctx context.Context, aggregatorDID, communityDID string, since time.Time) (int, error) {
+	if m.countRecentPostsFunc != nil {
+		return m.countRecentPostsFunc(ctx, aggregatorDID, communityDID, since)
+	}
 	return 0, nil
 }
 
 func (m *mockRepository) GetRecentPosts(ctx context.Context, aggregatorDID, communityDID string, since time.Time) ([]*AggregatorPost, error) {
+	if m.getRecentPostsFunc != nil {
+		return m.getRecentPostsFunc(ctx, aggregatorDID, communityDID, since)
+	}
 	return nil, nil
 }
 
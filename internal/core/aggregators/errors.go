@@ -3,29 +3,57 @@ package aggregators
 import (
 	"errors"
 	"fmt"
+	"time"
+
+	coreerrors "Coves/internal/core/errors"
 )
 
 // Domain errors
 var (
-	ErrAggregatorNotFound     = errors.New("aggregator not found")
-	ErrAuthorizationNotFound  = errors.New("authorization not found")
-	ErrNotAuthorized          = errors.New("aggregator not authorized for this community")
-	ErrAlreadyAuthorized      = errors.New("aggregator already authorized for this community")
+	ErrAggregatorNotFound     = coreerrors.Sentinel(coreerrors.ErrNotFound, "aggregator not found")
+	ErrAuthorizationNotFound  = coreerrors.Sentinel(coreerrors.ErrNotFound, "authorization not found")
+	ErrNotAuthorized          = coreerrors.Sentinel(coreerrors.ErrForbidden, "aggregator not authorized for this community")
+	ErrAlreadyAuthorized      = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "aggregator already authorized for this community")
 	ErrRateLimitExceeded      = errors.New("aggregator rate limit exceeded")
-	ErrInvalidConfig          = errors.New("invalid aggregator configuration")
-	ErrConfigSchemaValidation = errors.New("configuration does not match aggregator's schema")
-	ErrNotModerator           = errors.New("user is not a moderator of this community")
+	ErrInvalidConfig          = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid aggregator configuration")
+	ErrConfigSchemaValidation = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "configuration does not match aggregator's schema")
+	ErrNotModerator           = coreerrors.Sentinel(coreerrors.ErrForbidden, "user is not a moderator of this community")
 	ErrNotImplemented         = errors.New("feature not yet implemented") // For Phase 2 write-forward operations
+	ErrStaleRevision          = errors.New("aggregator record write is older than the indexed revision")
 
 	// API Key authentication errors
 	ErrAPIKeyRevoked         = errors.New("API key has been revoked")
 	ErrAPIKeyInvalid         = errors.New("invalid API key")
-	ErrAPIKeyNotFound        = errors.New("API key not found for this aggregator")
+	ErrAPIKeyNotFound        = coreerrors.Sentinel(coreerrors.ErrNotFound, "API key not found for this aggregator")
 	ErrOAuthTokenExpired     = errors.New("OAuth token has expired and needs refresh")
 	ErrOAuthRefreshFailed    = errors.New("failed to refresh OAuth token")
 	ErrOAuthSessionMismatch  = errors.New("OAuth session DID does not match aggregator DID")
 )
 
+// Rate limit windows named by RateLimitError
+const (
+	RateLimitWindowHour = "hour"
+	RateLimitWindowDay  = "day"
+)
+
+// RateLimitError reports which of an authorization's post limits tripped and when the
+// aggregator may post again
+// It matches ErrRateLimitExceeded with errors.Is.
+type RateLimitError struct {
+	ResetAt time.Time // When enough posts leave the window to allow another
+	Window  string    // RateLimitWindowHour or RateLimitWindowDay
+	Limit   int
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("aggregator rate limit exceeded: %d posts per %s, resets at %s",
+		e.Limit, e.Window, e.ResetAt.UTC().Format(time.RFC3339))
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimitExceeded
+}
+
 // ValidationError represents a validation error with field details
 type ValidationError struct {
 	Field   string
@@ -19,6 +19,15 @@ type Repository interface {
 	ListAggregators(ctx context.Context, limit, offset int) ([]*Aggregator, error)
 	IsAggregator(ctx context.Context, did string) (bool, error) // Fast check for post creation handler
 
+	// Public directory (hydrated with maintainer handle and PDS URL)
+	// ListServices orders by communities using the aggregator, then display name
+	ListServices(ctx context.Context, limit, offset int) ([]*Aggregator, error)
+	GetService(ctx context.Context, did string) (*Aggregator, error)
+	// GetServicesByDIDs returns the aggregators among dids, for attributing their posts in feeds
+	GetServicesByDIDs(ctx context.Context, dids []string) ([]*Aggregator, error)
+	// ListAuthorizedCommunities returns non-private communities with an enabled authorization
+	ListAuthorizedCommunities(ctx context.Context, aggregatorDID string, limit int) ([]*AuthorizedCommunity, error)
+
 	// Authorization CRUD (indexed from firehose)
 	CreateAuthorization(ctx context.Context, auth *Authorization) error
 	GetAuthorization(ctx context.Context, aggregatorDID, communityDID string) (*Authorization, error)
@@ -35,7 +44,7 @@ type Repository interface {
 	// Post tracking (for rate limiting and stats)
 	RecordAggregatorPost(ctx context.Context, aggregatorDID, communityDID, postURI, postCID string) error
 	CountRecentPosts(ctx context.Context, aggregatorDID, communityDID string, since time.Time) (int, error)
-	GetRecentPosts(ctx context.Context, aggregatorDID, communityDID string, since time.Time) ([]*AggregatorPost, error)
+	GetRecentPosts(ctx context.Context, aggregatorDID, communityDID string, since time.Time) ([]*AggregatorPost, error) // Newest first
 
 	// API Key Authentication
 	// GetByAPIKeyHash looks up an aggregator by their API key hash for authentication
@@ -71,6 +80,10 @@ type Service interface {
 	GetAggregators(ctx context.Context, dids []string) ([]*Aggregator, error)
 	ListAggregators(ctx context.Context, limit, offset int) ([]*Aggregator, error)
 
+	// Public directory (social.coves.aggregator.listServices / getService)
+	ListServices(ctx context.Context, req ListServicesRequest) (*ListServicesResponse, error)
+	GetService(ctx context.Context, did string) (*ServiceDetail, error)
+
 	// Authorization queries (read from AppView)
 	GetAuthorizationsForAggregator(ctx context.Context, req GetAuthorizationsRequest) ([]*Authorization, error)
 	ListAggregatorsForCommunity(ctx context.Context, req ListForCommunityRequest) ([]*Authorization, error)
@@ -83,6 +96,7 @@ type Service interface {
 	// Validation and authorization checks (used by post creation handler)
 	ValidateAggregatorPost(ctx context.Context, aggregatorDID, communityDID string) error // Checks authorization + rate limits
 	IsAggregator(ctx context.Context, did string) (bool, error)                           // Check if DID is a registered aggregator
+	GetPostQuota(ctx context.Context, aggregatorDID, communityDID string) ([]*PostQuota, error)
 
 	// Post tracking (called after successful post creation)
 	RecordAggregatorPost(ctx context.Context, aggregatorDID, communityDID, postURI, postCID string) error
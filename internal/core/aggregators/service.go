@@ -4,18 +4,23 @@ import (
 	"Coves/internal/core/communities"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/xeipuuv/gojsonschema"
 )
 
-// Rate limit constants
+// Rate limit and query constants
+// Post limits come from the community's authorization record (maxPostsPerHour, maxPostsPerDay);
+// these apply when the record doesn't set them.
 const (
-	RateLimitWindow   = 1 * time.Hour // Rolling 1-hour window for rate limit enforcement
-	RateLimitMaxPosts = 10            // Conservative limit for alpha: 10 posts/hour per community (prevents spam while allowing real-time updates)
-	DefaultQueryLimit = 50            // Balance between UX (reasonable page size) and server load
-	MaxQueryLimit     = 100           // Prevent abuse while allowing batch operations (e.g., fetching multiple aggregators at once)
+	HourlyRateLimitWindow  = 1 * time.Hour  // Rolling window for maxPostsPerHour
+	DailyRateLimitWindow   = 24 * time.Hour // Rolling window for maxPostsPerDay
+	DefaultMaxPostsPerHour = 10             // Conservative default: prevents spam while allowing real-time updates
+	DefaultQueryLimit      = 50             // Balance between UX (reasonable page size) and server load
+	MaxQueryLimit          = 100            // Prevent abuse while allowing batch operations (e.g., fetching multiple aggregators at once)
 )
 
 type aggregatorService struct {
@@ -72,6 +77,63 @@ func (s *aggregatorService) ListAggregators(ctx context.Context, limit, offset i
 	return s.repo.ListAggregators(ctx, limit, offset)
 }
 
+// ListServices returns a page of the public aggregator directory
+// The cursor is the offset of the next page, like social.coves.community.list
+func (s *aggregatorService) ListServices(ctx context.Context, req ListServicesRequest) (*ListServicesResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = DefaultQueryLimit
+	}
+	if req.Limit > MaxQueryLimit {
+		req.Limit = MaxQueryLimit
+	}
+
+	offset := 0
+	if req.Cursor != "" {
+		parsed, err := strconv.Atoi(req.Cursor)
+		if err != nil || parsed < 0 {
+			return nil, NewValidationError("cursor", "invalid cursor")
+		}
+		offset = parsed
+	}
+
+	aggs, err := s.repo.ListServices(ctx, req.Limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListServicesResponse{Aggregators: aggs}
+	if response.Aggregators == nil {
+		response.Aggregators = []*Aggregator{}
+	}
+	if len(aggs) == req.Limit {
+		next := strconv.Itoa(offset + len(aggs))
+		response.Cursor = &next
+	}
+	return response, nil
+}
+
+// GetService returns an aggregator with the communities that have enabled it
+func (s *aggregatorService) GetService(ctx context.Context, did string) (*ServiceDetail, error) {
+	if did == "" {
+		return nil, NewValidationError("aggregator", "aggregator DID is required")
+	}
+
+	agg, err := s.repo.GetService(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+
+	communities, err := s.repo.ListAuthorizedCommunities(ctx, did, MaxQueryLimit)
+	if err != nil {
+		return nil, err
+	}
+	if communities == nil {
+		communities = []*AuthorizedCommunity{}
+	}
+
+	return &ServiceDetail{Aggregator: agg, Communities: communities}, nil
+}
+
 // GetAuthorizationsForAggregator retrieves all communities that authorized an aggregator
 func (s *aggregatorService) GetAuthorizationsForAggregator(ctx context.Context, req GetAuthorizationsRequest) ([]*Authorization, error) {
 	if req.AggregatorDID == "" {
@@ -223,30 +285,116 @@ func (s *aggregatorService) UpdateAggregatorConfig(ctx context.Context, req Upda
 // ===== Validation and Authorization Checks =====
 
 // ValidateAggregatorPost validates that an aggregator can post to a community
-// Checks: 1) Authorization exists and is enabled, 2) Rate limit not exceeded
-// This is called by the post creation handler BEFORE writing to PDS
+// Checks: 1) Authorization exists and is enabled, 2) Neither of its post limits is reached
+// This is called by the post creation handler BEFORE writing to PDS. The authorization is read
+// on every call, so an updated record's limits apply to the next post.
 func (s *aggregatorService) ValidateAggregatorPost(ctx context.Context, aggregatorDID, communityDID string) error {
 	// Check authorization exists and is enabled
-	authorized, err := s.repo.IsAuthorized(ctx, aggregatorDID, communityDID)
+	auth, err := s.repo.GetAuthorization(ctx, aggregatorDID, communityDID)
+	if errors.Is(err, ErrAuthorizationNotFound) {
+		return ErrNotAuthorized
+	}
 	if err != nil {
 		return fmt.Errorf("failed to check authorization: %w", err)
 	}
-	if !authorized {
+	if !auth.Enabled {
 		return ErrNotAuthorized
 	}
 
-	// Check rate limit (10 posts per hour per community)
-	since := time.Now().Add(-RateLimitWindow)
-	recentPostCount, err := s.repo.CountRecentPosts(ctx, aggregatorDID, communityDID, since)
+	// When both windows are full, report the one the aggregator has to wait longest for
+	now := time.Now()
+	var exceeded *RateLimitError
+	for _, limit := range postLimits(auth) {
+		limitErr, err := s.checkPostLimit(ctx, aggregatorDID, communityDID, limit, now)
+		if err != nil {
+			return err
+		}
+		if limitErr != nil && (exceeded == nil || limitErr.ResetAt.After(exceeded.ResetAt)) {
+			exceeded = limitErr
+		}
+	}
+	if exceeded != nil {
+		return exceeded
+	}
+
+	return nil
+}
+
+// postLimit is one of an authorization's rolling post limits
+type postLimit struct {
+	name   string
+	window time.Duration
+	max    int
+}
+
+// postLimits returns the limits an authorization enforces
+func postLimits(auth *Authorization) []postLimit {
+	hourly := DefaultMaxPostsPerHour
+	if auth.MaxPostsPerHour != nil {
+		hourly = *auth.MaxPostsPerHour
+	}
+	limits := []postLimit{{name: RateLimitWindowHour, window: HourlyRateLimitWindow, max: hourly}}
+	if auth.MaxPostsPerDay != nil {
+		limits = append(limits, postLimit{name: RateLimitWindowDay, window: DailyRateLimitWindow, max: *auth.MaxPostsPerDay})
+	}
+	return limits
+}
+
+// checkPostLimit returns a RateLimitError when the window already holds limit.max posts
+func (s *aggregatorService) checkPostLimit(ctx context.Context, aggregatorDID, communityDID string, limit postLimit, now time.Time) (*RateLimitError, error) {
+	since := now.Add(-limit.window)
+	count, err := s.repo.CountRecentPosts(ctx, aggregatorDID, communityDID, since)
 	if err != nil {
-		return fmt.Errorf("failed to check rate limit: %w", err)
+		return nil, fmt.Errorf("failed to check %s rate limit: %w", limit.name, err)
+	}
+	if count < limit.max {
+		return nil, nil
 	}
 
-	if recentPostCount >= RateLimitMaxPosts {
-		return ErrRateLimitExceeded
+	// Another post is allowed once the newest limit.max posts are all that's left in the window,
+	// i.e. when the limit.max-th newest post ages out
+	resetAt := now.Add(limit.window)
+	recent, err := s.repo.GetRecentPosts(ctx, aggregatorDID, communityDID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check %s rate limit: %w", limit.name, err)
+	}
+	if limit.max > 0 && len(recent) >= limit.max {
+		resetAt = recent[limit.max-1].CreatedAt.Add(limit.window)
 	}
+	return &RateLimitError{Window: limit.name, Limit: limit.max, ResetAt: resetAt}, nil
+}
 
-	return nil
+// GetPostQuota reports how many posts each of the authorization's limits still allows
+// Like ValidateAggregatorPost it only reads the post log, so a dry run can show the quota
+// without consuming it.
+func (s *aggregatorService) GetPostQuota(ctx context.Context, aggregatorDID, communityDID string) ([]*PostQuota, error) {
+	auth, err := s.repo.GetAuthorization(ctx, aggregatorDID, communityDID)
+	if errors.Is(err, ErrAuthorizationNotFound) {
+		return nil, ErrNotAuthorized
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check authorization: %w", err)
+	}
+	if !auth.Enabled {
+		return nil, ErrNotAuthorized
+	}
+
+	now := time.Now()
+	limits := postLimits(auth)
+	quota := make([]*PostQuota, 0, len(limits))
+	for _, limit := range limits {
+		used, err := s.repo.CountRecentPosts(ctx, aggregatorDID, communityDID, now.Add(-limit.window))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s quota: %w", limit.name, err)
+		}
+		quota = append(quota, &PostQuota{
+			Window:    limit.name,
+			Limit:     limit.max,
+			Used:      used,
+			Remaining: max(limit.max-used, 0),
+		})
+	}
+	return quota, nil
 }
 
 // IsAggregator checks if a DID is a registered aggregator
@@ -0,0 +1,263 @@
+package aggregators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestListServices_Pagination(t *testing.T) {
+	var gotLimit, gotOffset int
+	repo := &mockRepository{
+		listServicesFunc: func(ctx context.Context, limit, offset int) ([]*Aggregator, error) {
+			gotLimit, gotOffset = limit, offset
+			// 5 aggregators in total
+			var page []*Aggregator
+			for i := offset; i < 5 && len(page) < limit; i++ {
+				page = append(page, &Aggregator{DID: fmt.Sprintf("did:plc:agg%d", i)})
+			}
+			return page, nil
+		},
+	}
+	service := NewAggregatorService(repo, nil)
+	ctx := context.Background()
+
+	first, err := service.ListServices(ctx, ListServicesRequest{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListServices: %v", err)
+	}
+	if len(first.Aggregators) != 2 || first.Cursor == nil || *first.Cursor != "2" {
+		t.Fatalf("expected a full first page with cursor 2, got %d aggregators, cursor %v", len(first.Aggregators), first.Cursor)
+	}
+
+	last, err := service.ListServices(ctx, ListServicesRequest{Limit: 2, Cursor: "4"})
+	if err != nil {
+		t.Fatalf("ListServices: %v", err)
+	}
+	if gotOffset != 4 || len(last.Aggregators) != 1 || last.Cursor != nil {
+		t.Fatalf("expected a final short page without cursor, got offset %d, %d aggregators, cursor %v", gotOffset, len(last.Aggregators), last.Cursor)
+	}
+
+	if _, err := service.ListServices(ctx, ListServicesRequest{Limit: 1000}); err != nil || gotLimit != MaxQueryLimit {
+		t.Errorf("expected limit to be capped at %d, got %d (err %v)", MaxQueryLimit, gotLimit, err)
+	}
+	if _, err := service.ListServices(ctx, ListServicesRequest{}); err != nil || gotLimit != DefaultQueryLimit {
+		t.Errorf("expected default limit %d, got %d (err %v)", DefaultQueryLimit, gotLimit, err)
+	}
+
+	for _, cursor := range []string{"abc", "-1"} {
+		if _, err := service.ListServices(ctx, ListServicesRequest{Cursor: cursor}); !IsValidationError(err) {
+			t.Errorf("cursor %q: expected validation error, got %v", cursor, err)
+		}
+	}
+}
+
+func TestGetService_ReturnsAuthorizedCommunities(t *testing.T) {
+	repo := &mockRepository{
+		getAggregatorFunc: func(ctx context.Context, did string) (*Aggregator, error) {
+			if did != "did:plc:agg" {
+				return nil, ErrAggregatorNotFound
+			}
+			return &Aggregator{DID: did, DisplayName: "RSS Bot"}, nil
+		},
+		listAuthorizedCommunitiesFunc: func(ctx context.Context, aggregatorDID string, limit int) ([]*AuthorizedCommunity, error) {
+			return []*AuthorizedCommunity{{DID: "did:plc:c1", Handle: "news.community.coves.test", Name: "news"}}, nil
+		},
+	}
+	service := NewAggregatorService(repo, nil)
+
+	detail, err := service.GetService(context.Background(), "did:plc:agg")
+	if err != nil {
+		t.Fatalf("GetService: %v", err)
+	}
+	if detail.Aggregator.DisplayName != "RSS Bot" || len(detail.Communities) != 1 {
+		t.Errorf("unexpected detail: %+v", detail)
+	}
+
+	if _, err := service.GetService(context.Background(), "did:plc:missing"); !errors.Is(err, ErrAggregatorNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if _, err := service.GetService(context.Background(), ""); !IsValidationError(err) {
+		t.Errorf("expected validation error for empty DID, got %v", err)
+	}
+}
+
+// postLogRepo serves an authorization and an aggregator's post history, newest first
+func postLogRepo(auth *Authorization, posted []time.Time) *mockRepository {
+	within := func(since time.Time) []*AggregatorPost {
+		var recent []*AggregatorPost
+		for _, createdAt := range posted {
+			if createdAt.After(since) {
+				recent = append(recent, &AggregatorPost{CreatedAt: createdAt})
+			}
+		}
+		return recent
+	}
+	return &mockRepository{
+		getAuthorizationFunc: func(ctx context.Context, aggregatorDID, communityDID string) (*Authorization, error) {
+			if auth == nil {
+				return nil, ErrAuthorizationNotFound
+			}
+			return auth, nil
+		},
+		countRecentPostsFunc: func(ctx context.Context, aggregatorDID, communityDID string, since time.Time) (int, error) {
+			return len(within(since)), nil
+		},
+		getRecentPostsFunc: func(ctx context.Context, aggregatorDID, communityDID string, since time.Time) ([]*AggregatorPost, error) {
+			return within(since), nil
+		},
+	}
+}
+
+// postedEvery returns n post times spaced interval apart, newest first, the newest at now-interval
+func postedEvery(now time.Time, n int, interval time.Duration) []time.Time {
+	posted := make([]time.Time, n)
+	for i := range posted {
+		posted[i] = now.Add(-time.Duration(i+1) * interval)
+	}
+	return posted
+}
+
+func TestValidateAggregatorPost_PostLimits(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	now := time.Now()
+
+	tests := []struct {
+		name       string
+		auth       *Authorization
+		posted     []time.Time
+		wantWindow string
+		wantLimit  int
+		wantReset  time.Time
+	}{
+		{
+			name:   "below default hourly limit",
+			auth:   &Authorization{Enabled: true},
+			posted: postedEvery(now, DefaultMaxPostsPerHour-1, time.Minute),
+		},
+		{
+			name:       "at default hourly limit",
+			auth:       &Authorization{Enabled: true},
+			posted:     postedEvery(now, DefaultMaxPostsPerHour, time.Minute),
+			wantWindow: RateLimitWindowHour,
+			wantLimit:  DefaultMaxPostsPerHour,
+			// The oldest of the 10 posts leaves the window first
+			wantReset: now.Add(-time.Duration(DefaultMaxPostsPerHour) * time.Minute).Add(time.Hour),
+		},
+		{
+			name:   "record raises hourly limit",
+			auth:   &Authorization{Enabled: true, MaxPostsPerHour: intPtr(20)},
+			posted: postedEvery(now, 19, time.Minute),
+		},
+		{
+			name:       "record lowers hourly limit",
+			auth:       &Authorization{Enabled: true, MaxPostsPerHour: intPtr(2)},
+			posted:     postedEvery(now, 5, time.Minute),
+			wantWindow: RateLimitWindowHour,
+			wantLimit:  2,
+			wantReset:  now.Add(-2 * time.Minute).Add(time.Hour),
+		},
+		{
+			name:   "below daily limit",
+			auth:   &Authorization{Enabled: true, MaxPostsPerDay: intPtr(5)},
+			posted: postedEvery(now, 4, 2*time.Hour),
+		},
+		{
+			name:       "at daily limit",
+			auth:       &Authorization{Enabled: true, MaxPostsPerDay: intPtr(5)},
+			posted:     postedEvery(now, 5, 2*time.Hour),
+			wantWindow: RateLimitWindowDay,
+			wantLimit:  5,
+			wantReset:  now.Add(-10 * time.Hour).Add(24 * time.Hour),
+		},
+		{
+			name:       "both exceeded reports the later reset",
+			auth:       &Authorization{Enabled: true, MaxPostsPerHour: intPtr(3), MaxPostsPerDay: intPtr(3)},
+			posted:     postedEvery(now, 3, time.Minute),
+			wantWindow: RateLimitWindowDay,
+			wantLimit:  3,
+			wantReset:  now.Add(-3 * time.Minute).Add(24 * time.Hour),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewAggregatorService(postLogRepo(tt.auth, tt.posted), nil)
+			err := service.ValidateAggregatorPost(context.Background(), "did:plc:agg", "did:plc:community")
+			if tt.wantWindow == "" {
+				if err != nil {
+					t.Fatalf("expected post to be allowed, got %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrRateLimitExceeded) {
+				t.Fatalf("expected rate limit error, got %v", err)
+			}
+			var limitErr *RateLimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("expected *RateLimitError, got %T", err)
+			}
+			if limitErr.Window != tt.wantWindow || limitErr.Limit != tt.wantLimit {
+				t.Errorf("expected %d per %s, got %d per %s", tt.wantLimit, tt.wantWindow, limitErr.Limit, limitErr.Window)
+			}
+			if !limitErr.ResetAt.Equal(tt.wantReset) {
+				t.Errorf("expected reset at %v, got %v", tt.wantReset, limitErr.ResetAt)
+			}
+		})
+	}
+}
+
+func TestValidateAggregatorPost_RequiresEnabledAuthorization(t *testing.T) {
+	for name, auth := range map[string]*Authorization{
+		"missing":  nil,
+		"disabled": {Enabled: false},
+	} {
+		service := NewAggregatorService(postLogRepo(auth, nil), nil)
+		err := service.ValidateAggregatorPost(context.Background(), "did:plc:agg", "did:plc:community")
+		if !errors.Is(err, ErrNotAuthorized) {
+			t.Errorf("%s authorization: expected ErrNotAuthorized, got %v", name, err)
+		}
+	}
+}
+
+func TestGetPostQuota(t *testing.T) {
+	intPtr := func(n int) *int { return &n }
+	now := time.Now()
+	auth := &Authorization{Enabled: true, MaxPostsPerHour: intPtr(3), MaxPostsPerDay: intPtr(5)}
+	repo := postLogRepo(auth, postedEvery(now, 4, 30*time.Minute))
+	service := NewAggregatorService(repo, nil)
+
+	quota, err := service.GetPostQuota(context.Background(), "did:plc:agg", "did:plc:community")
+	if err != nil {
+		t.Fatalf("GetPostQuota: %v", err)
+	}
+	want := []PostQuota{
+		{Window: RateLimitWindowHour, Limit: 3, Used: 1, Remaining: 2},
+		{Window: RateLimitWindowDay, Limit: 5, Used: 4, Remaining: 1},
+	}
+	if len(quota) != len(want) {
+		t.Fatalf("expected %d limits, got %d", len(want), len(quota))
+	}
+	for i := range want {
+		if *quota[i] != want[i] {
+			t.Errorf("quota[%d] = %+v, want %+v", i, *quota[i], want[i])
+		}
+	}
+
+	// An exhausted window reports no remaining posts rather than a negative count
+	auth.MaxPostsPerHour = intPtr(0)
+	quota, err = service.GetPostQuota(context.Background(), "did:plc:agg", "did:plc:community")
+	if err != nil {
+		t.Fatalf("GetPostQuota: %v", err)
+	}
+	if quota[0].Remaining != 0 {
+		t.Errorf("expected no hourly posts remaining, got %d", quota[0].Remaining)
+	}
+
+	if _, err := NewAggregatorService(postLogRepo(nil, nil), nil).GetPostQuota(context.Background(), "did:plc:agg", "did:plc:community"); !errors.Is(err, ErrNotAuthorized) {
+		t.Errorf("expected ErrNotAuthorized without an authorization, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+// Package audit keeps a tamper-evident log of admin and moderator actions.
+//
+// Every entry stores the hash of the entry before it and a hash over its own contents and
+// that previous hash, so the entries form a chain. Editing an entry changes its hash, and
+// deleting one leaves the next entry pointing at a hash that is no longer there; VerifyChain
+// walks the log and reports the first entry where either happened. Deleting the newest
+// entries leaves a shorter chain that still verifies, so the chain proves the log wasn't
+// rewritten, not that nothing was appended after what remains.
+//
+// Entries are recorded after the action succeeds. Recording failures are logged rather than
+// failing an action that already took effect.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+// Actions are the XRPC procedures that performed them
+const (
+	ActionCreateFederationRule       = "social.coves.admin.createFederationRule"
+	ActionDeleteFederationRule       = "social.coves.admin.deleteFederationRule"
+	ActionNullifyVotes               = "social.coves.admin.nullifyVotes"
+	ActionRestoreVotes               = "social.coves.admin.restoreVotes"
+	ActionAddFeaturedCommunity       = "social.coves.admin.addFeaturedCommunity"
+	ActionRemoveFeaturedCommunity    = "social.coves.admin.removeFeaturedCommunity"
+	ActionReorderFeaturedCommunities = "social.coves.admin.reorderFeaturedCommunities"
+	ActionAddReservedName            = "social.coves.admin.addReservedCommunityName"
+	ActionRemoveReservedName         = "social.coves.admin.removeReservedCommunityName"
+	ActionSetContentVisibility       = "social.coves.admin.setContentVisibility"
+	ActionSetThreadLock              = "social.coves.admin.setThreadLock"
+	ActionSetPostLabel               = "social.coves.admin.setPostLabel"
+	ActionTakedownRecord             = "social.coves.admin.takedownRecord"
+	ActionReverseTakedown            = "social.coves.admin.reverseTakedown"
+	ActionReviewImpersonationFlag    = "social.coves.admin.reviewImpersonationFlag"
+	ActionReviewQuarantinedPost      = "social.coves.admin.reviewQuarantinedPost"
+	ActionCleanupProvisioning        = "social.coves.admin.cleanupProvisioning"
+	ActionMarkGapBackfilled          = "social.coves.admin.markGapBackfilled"
+	ActionReviewBrigadeAlert         = "social.coves.moderation.reviewBrigadeAlert"
+	ActionUpdateAutomod              = "social.coves.moderation.updateAutomod"
+	ActionReviewAutomodAction        = "social.coves.moderation.reviewAutomodAction"
+	ActionDeleteCommunity            = "social.coves.community.delete"
+	ActionUndeleteCommunity          = "social.coves.community.undelete"
+	ActionUpdateCommunityRules       = "social.coves.community.updateRules"
+	ActionSchedulePost               = "social.coves.community.schedulePost"
+	ActionCancelScheduledPost        = "social.coves.community.cancelScheduledPost"
+)
+
+// Listing limits
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 100
+
+	// verifyBatchSize is how many entries VerifyChain reads at a time
+	verifyBatchSize = 1000
+)
+
+// ErrInvalidCursor is returned for a getAuditLog cursor that isn't an entry ID
+var ErrInvalidCursor = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid cursor")
+
+// Entry is one recorded action
+type Entry struct {
+	CreatedAt time.Time       `json:"createdAt"`
+	Details   json.RawMessage `json:"details,omitempty"`
+	ActorDID  string          `json:"actor"`
+	Action    string          `json:"action"`
+	Subject   string          `json:"subject"` // What the action applied to: an AT-URI, DID, name or ID
+	PrevHash  string          `json:"prevHash"`
+	RowHash   string          `json:"rowHash"`
+	ID        int64           `json:"id"`
+}
+
+// Filter selects entries for getAuditLog, newest first
+// Zero fields don't filter.
+type Filter struct {
+	Since    time.Time // Inclusive
+	Until    time.Time // Exclusive
+	ActorDID string
+	Action   string
+	Before   int64 // Only entries with a lower ID; the pagination cursor
+	Limit    int
+}
+
+// Verification is the result of walking the chain
+type Verification struct {
+	Problem  string `json:"problem,omitempty"`
+	Checked  int    `json:"checked"`
+	BrokenAt int64  `json:"brokenAt,omitempty"` // ID of the first entry that doesn't chain
+	Valid    bool   `json:"valid"`
+}
+
+// Hash returns the hash chaining e to the entry before it
+// It covers e.PrevHash and everything recorded about the action, but not the ID, which the
+// database assigns. Details are hashed in canonical form (compact, keys sorted), so the hash
+// survives the database reformatting the JSON.
+func Hash(e *Entry) (string, error) {
+	details, err := canonicalJSON(e.Details)
+	if err != nil {
+		return "", fmt.Errorf("failed to canonicalize audit details: %w", err)
+	}
+	content, err := json.Marshal([]interface{}{
+		e.PrevHash,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		e.ActorDID,
+		e.Action,
+		e.Subject,
+		details,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// canonicalJSON re-encodes raw with sorted keys and no insignificant whitespace
+// Empty input stays empty.
+func canonicalJSON(raw json.RawMessage) (json.RawMessage, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, nil
+	}
+	return json.Marshal(value)
+}
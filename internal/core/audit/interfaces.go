@@ -0,0 +1,40 @@
+package audit
+
+import (
+	"context"
+)
+
+// Recorder records actions
+// Admin handlers and the community and scheduled post services call it once an action succeeds.
+type Recorder interface {
+	// Record appends an entry for the actor's action on subject
+	// details is any JSON-encodable value (usually the request); nil records none.
+	Record(ctx context.Context, actorDID, action, subject string, details interface{})
+}
+
+// Repository stores the log
+// Implemented by postgres.NewAuditRepository.
+type Repository interface {
+	// Append links the entry to the newest one and stores it: it sets entry.PrevHash to the
+	// newest entry's RowHash ("" for the first entry), entry.RowHash to Hash(entry), and
+	// entry.ID. Appends are serialized so two entries never chain to the same predecessor.
+	Append(ctx context.Context, entry *Entry) error
+
+	// List returns entries matching the filter, newest first
+	List(ctx context.Context, filter Filter) ([]*Entry, error)
+
+	// ListAfter returns up to limit entries with an ID above afterID, oldest first
+	ListAfter(ctx context.Context, afterID int64, limit int) ([]*Entry, error)
+}
+
+// Service records actions and reads them back for admins
+type Service interface {
+	Recorder
+
+	// GetAuditLog lists entries matching the filter, newest first
+	GetAuditLog(ctx context.Context, filter Filter) ([]*Entry, error)
+
+	// VerifyChain walks the whole log, oldest first, and reports the first entry that was
+	// modified or whose predecessor was deleted
+	VerifyChain(ctx context.Context) (*Verification, error)
+}
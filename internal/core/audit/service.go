@@ -0,0 +1,103 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+type auditService struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates an audit log service storing entries in repo
+func NewService(repo Repository) Service {
+	return &auditService{repo: repo, now: time.Now}
+}
+
+// Record appends an entry, logging instead of returning failures
+// The context's cancellation is ignored: the action already happened and must be recorded
+// even if the caller's client has gone away.
+func (s *auditService) Record(ctx context.Context, actorDID, action, subject string, details interface{}) {
+	entry := &Entry{
+		ActorDID: actorDID,
+		Action:   action,
+		Subject:  subject,
+		// Postgres stores microseconds; truncating keeps the hash valid after a round trip
+		CreatedAt: s.now().UTC().Truncate(time.Microsecond),
+	}
+	if details != nil {
+		raw, err := json.Marshal(details)
+		if err != nil {
+			log.Printf("ERROR: Failed to encode audit details for %s by %s on %s: %v", action, actorDID, subject, err)
+		} else if entry.Details, err = canonicalJSON(raw); err != nil {
+			log.Printf("ERROR: Failed to canonicalize audit details for %s by %s on %s: %v", action, actorDID, subject, err)
+		}
+	}
+	if err := s.repo.Append(context.WithoutCancel(ctx), entry); err != nil {
+		log.Printf("ERROR: Failed to record audit entry %s by %s on %s: %v", action, actorDID, subject, err)
+	}
+}
+
+// GetAuditLog lists entries matching the filter, clamping its limit
+func (s *auditService) GetAuditLog(ctx context.Context, filter Filter) ([]*Entry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultListLimit
+	}
+	if filter.Limit > MaxListLimit {
+		filter.Limit = MaxListLimit
+	}
+	if filter.Before < 0 {
+		return nil, ErrInvalidCursor
+	}
+	entries, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// VerifyChain checks every entry's hash and its link to the entry before it
+func (s *auditService) VerifyChain(ctx context.Context) (*Verification, error) {
+	result := &Verification{Valid: true}
+	prevHash := ""
+	var afterID int64
+	for {
+		batch, err := s.repo.ListAfter(ctx, afterID, verifyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read audit log: %w", err)
+		}
+		for _, entry := range batch {
+			if problem := checkEntry(entry, prevHash); problem != "" {
+				result.Valid = false
+				result.BrokenAt = entry.ID
+				result.Problem = problem
+				return result, nil
+			}
+			result.Checked++
+			prevHash = entry.RowHash
+			afterID = entry.ID
+		}
+		if len(batch) < verifyBatchSize {
+			return result, nil
+		}
+	}
+}
+
+// checkEntry describes what's wrong with an entry given its predecessor's hash, or returns ""
+func checkEntry(entry *Entry, prevHash string) string {
+	if entry.PrevHash != prevHash {
+		return "previous hash does not match the entry before it; an entry was deleted or modified"
+	}
+	hash, err := Hash(entry)
+	if err != nil {
+		return fmt.Sprintf("entry cannot be hashed: %v", err)
+	}
+	if hash != entry.RowHash {
+		return "hash does not match the entry's contents; the entry was modified"
+	}
+	return ""
+}
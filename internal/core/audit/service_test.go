@@ -0,0 +1,226 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+)
+
+// memoryLog keeps entries in chain order
+type memoryLog struct {
+	entries []*Entry
+	nextID  int64
+}
+
+func (m *memoryLog) Append(ctx context.Context, entry *Entry) error {
+	entry.PrevHash = ""
+	if len(m.entries) > 0 {
+		entry.PrevHash = m.entries[len(m.entries)-1].RowHash
+	}
+	hash, err := Hash(entry)
+	if err != nil {
+		return err
+	}
+	entry.RowHash = hash
+	m.nextID++
+	entry.ID = m.nextID
+	stored := *entry
+	m.entries = append(m.entries, &stored)
+	return nil
+}
+
+func (m *memoryLog) List(ctx context.Context, filter Filter) ([]*Entry, error) {
+	var result []*Entry
+	for i := len(m.entries) - 1; i >= 0; i-- {
+		e := m.entries[i]
+		if (filter.ActorDID != "" && e.ActorDID != filter.ActorDID) ||
+			(filter.Action != "" && e.Action != filter.Action) ||
+			(!filter.Since.IsZero() && e.CreatedAt.Before(filter.Since)) ||
+			(!filter.Until.IsZero() && !e.CreatedAt.Before(filter.Until)) ||
+			(filter.Before > 0 && e.ID >= filter.Before) {
+			continue
+		}
+		result = append(result, e)
+		if len(result) == filter.Limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (m *memoryLog) ListAfter(ctx context.Context, afterID int64, limit int) ([]*Entry, error) {
+	i := sort.Search(len(m.entries), func(i int) bool { return m.entries[i].ID > afterID })
+	end := i + limit
+	if end > len(m.entries) {
+		end = len(m.entries)
+	}
+	return m.entries[i:end], nil
+}
+
+func newTestService(repo Repository, now *time.Time) *auditService {
+	return &auditService{repo: repo, now: func() time.Time { return *now }}
+}
+
+// recordSample records three actions a minute apart
+func recordSample(t *testing.T, svc *auditService, now *time.Time) {
+	t.Helper()
+	ctx := context.Background()
+	svc.Record(ctx, "did:plc:admin", ActionNullifyVotes, "did:plc:spammer", map[string]string{"did": "did:plc:spammer"})
+	*now = now.Add(time.Minute)
+	svc.Record(ctx, "did:plc:admin", ActionTakedownRecord, "at://did:plc:community/social.coves.community.post/3k", map[string]interface{}{"reason": "spam", "note": "ring"})
+	*now = now.Add(time.Minute)
+	svc.Record(ctx, "did:plc:mod", ActionReviewBrigadeAlert, "42", nil)
+}
+
+func TestRecord_ChainsEntries(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &memoryLog{}
+	svc := newTestService(repo, &now)
+	recordSample(t, svc, &now)
+
+	if len(repo.entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(repo.entries))
+	}
+	if repo.entries[0].PrevHash != "" {
+		t.Errorf("Expected the first entry to start the chain, got prevHash %q", repo.entries[0].PrevHash)
+	}
+	for i := 1; i < len(repo.entries); i++ {
+		if repo.entries[i].PrevHash != repo.entries[i-1].RowHash {
+			t.Errorf("Entry %d doesn't chain to the entry before it", i)
+		}
+	}
+	if string(repo.entries[1].Details) != `{"note":"ring","reason":"spam"}` {
+		t.Errorf("Expected canonical details, got %s", repo.entries[1].Details)
+	}
+	if repo.entries[2].Details != nil {
+		t.Errorf("Expected no details, got %s", repo.entries[2].Details)
+	}
+
+	result, err := svc.VerifyChain(context.Background())
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if !result.Valid || result.Checked != 3 {
+		t.Errorf("Expected a valid chain of 3, got %+v", result)
+	}
+}
+
+func TestVerifyChain_DetectsTampering(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func(repo *memoryLog)
+		wantBroken int64
+	}{
+		{
+			name:       "modified actor",
+			tamper:     func(repo *memoryLog) { repo.entries[1].ActorDID = "did:plc:someoneelse" },
+			wantBroken: 2,
+		},
+		{
+			name:       "modified details",
+			tamper:     func(repo *memoryLog) { repo.entries[1].Details = json.RawMessage(`{"note":"ring","reason":"legal"}`) },
+			wantBroken: 2,
+		},
+		{
+			name:       "modified timestamp",
+			tamper:     func(repo *memoryLog) { repo.entries[0].CreatedAt = repo.entries[0].CreatedAt.Add(time.Hour) },
+			wantBroken: 1,
+		},
+		{
+			name:       "deleted entry",
+			tamper:     func(repo *memoryLog) { repo.entries = append(repo.entries[:1], repo.entries[2:]...) },
+			wantBroken: 3,
+		},
+		{
+			name:       "deleted first entry",
+			tamper:     func(repo *memoryLog) { repo.entries = repo.entries[1:] },
+			wantBroken: 2,
+		},
+		{
+			name: "rehashed entry",
+			tamper: func(repo *memoryLog) {
+				// Fixing up the tampered entry's own hash still breaks the link from the next one
+				repo.entries[0].Subject = "did:plc:innocent"
+				repo.entries[0].RowHash, _ = Hash(repo.entries[0])
+			},
+			wantBroken: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+			repo := &memoryLog{}
+			svc := newTestService(repo, &now)
+			recordSample(t, svc, &now)
+			tt.tamper(repo)
+
+			result, err := svc.VerifyChain(context.Background())
+			if err != nil {
+				t.Fatalf("VerifyChain failed: %v", err)
+			}
+			if result.Valid || result.BrokenAt != tt.wantBroken || result.Problem == "" {
+				t.Errorf("Expected the chain broken at %d, got %+v", tt.wantBroken, result)
+			}
+		})
+	}
+}
+
+func TestVerifyChain_ToleratesReformattedDetails(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &memoryLog{}
+	svc := newTestService(repo, &now)
+	recordSample(t, svc, &now)
+
+	// JSONB hands details back with its own spacing and key order
+	repo.entries[1].Details = json.RawMessage(`{"reason": "spam", "note": "ring"}`)
+	result, err := svc.VerifyChain(context.Background())
+	if err != nil {
+		t.Fatalf("VerifyChain failed: %v", err)
+	}
+	if !result.Valid {
+		t.Errorf("Expected reformatted details to verify, got %+v", result)
+	}
+}
+
+func TestGetAuditLog_Filters(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	now := start
+	repo := &memoryLog{}
+	svc := newTestService(repo, &now)
+	recordSample(t, svc, &now)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		filter  Filter
+		wantIDs []int64
+	}{
+		{"all, newest first", Filter{}, []int64{3, 2, 1}},
+		{"by actor", Filter{ActorDID: "did:plc:admin"}, []int64{2, 1}},
+		{"by action", Filter{Action: ActionTakedownRecord}, []int64{2}},
+		{"time range", Filter{Since: start.Add(time.Minute), Until: start.Add(2 * time.Minute)}, []int64{2}},
+		{"page", Filter{Before: 3, Limit: 1}, []int64{2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := svc.GetAuditLog(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("GetAuditLog failed: %v", err)
+			}
+			var ids []int64
+			for _, e := range entries {
+				ids = append(ids, e.ID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("Expected %v, got %v", tt.wantIDs, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Fatalf("Expected %v, got %v", tt.wantIDs, ids)
+				}
+			}
+		})
+	}
+}
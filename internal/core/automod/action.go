@@ -0,0 +1,57 @@
+package automod
+
+import "time"
+
+// ReviewStatus is where an automod action is in the moderation queue
+type ReviewStatus string
+
+const (
+	// ReviewOpen actions (holds and flags) await a moderator
+	ReviewOpen ReviewStatus = "open"
+
+	// ReviewApproved content was fine: held content is made visible, flagged content stays up
+	ReviewApproved ReviewStatus = "approved"
+
+	// ReviewRemoved content stays hidden (held) or is hidden (flagged); remove actions are
+	// recorded with this status from the start
+	ReviewRemoved ReviewStatus = "removed"
+)
+
+// IsReview reports whether s is a status a moderator can review an action to
+func (s ReviewStatus) IsReview() bool {
+	return s == ReviewApproved || s == ReviewRemoved
+}
+
+// ActionRecord is the audit row of an automod action applied at index time
+// A post or comment has at most one; held and flagged content appears in the moderation
+// queue until a moderator reviews it.
+type ActionRecord struct {
+	CreatedAt    time.Time    `json:"createdAt"`
+	ReviewedAt   *time.Time   `json:"reviewedAt,omitempty"`
+	SubjectURI   string       `json:"subject"`
+	CommunityDID string       `json:"community"`
+	AuthorDID    string       `json:"author"`
+	Action       Action       `json:"action"`
+	MatchType    MatchType    `json:"matchType"`
+	Pattern      string       `json:"pattern"`
+	Status       ReviewStatus `json:"status"`
+	ReviewedBy   string       `json:"reviewedBy,omitempty"`
+	ID           int64        `json:"id"`
+}
+
+// NewActionRecord builds the audit row for content that tripped a rule
+func NewActionRecord(match *Match, subjectURI, communityDID, authorDID string) *ActionRecord {
+	status := ReviewOpen
+	if match.Rule.Action == ActionRemove {
+		status = ReviewRemoved
+	}
+	return &ActionRecord{
+		SubjectURI:   subjectURI,
+		CommunityDID: communityDID,
+		AuthorDID:    authorDID,
+		Action:       match.Rule.Action,
+		MatchType:    match.Rule.MatchType,
+		Pattern:      match.Rule.Pattern,
+		Status:       status,
+	}
+}
@@ -0,0 +1,174 @@
+package automod
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Content is the text and links of a post or comment being checked
+type Content struct {
+	Text  string   // Title and body
+	Links []string // Link facets and embeds; links written out in Text are found automatically
+}
+
+// Match is the rule content tripped
+type Match struct {
+	Rule Rule
+}
+
+// Action returns the action of the matched rule
+func (m *Match) Action() Action {
+	return m.Rule.Action
+}
+
+// compiledRule is a rule ready to run against content
+type compiledRule struct {
+	rule   Rule
+	regexp *regexp.Regexp // Keyword and regex-lite rules
+	domain string         // Domain rules
+}
+
+// RuleSet is a community's rules compiled for evaluation
+type RuleSet struct {
+	rules []compiledRule
+}
+
+// textLinkPattern finds links written out in plain text
+var textLinkPattern = regexp.MustCompile(`(?i)https?://[^\s<>"'()\[\]]+`)
+
+// Compile compiles a community's rules
+// Rules are validated when they're saved; one that no longer compiles is an error here.
+func Compile(rules []Rule) (*RuleSet, error) {
+	set := &RuleSet{rules: make([]compiledRule, 0, len(rules))}
+	for i, rule := range rules {
+		compiled, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		set.rules = append(set.rules, compiled)
+	}
+	return set, nil
+}
+
+func compileRule(rule Rule) (compiledRule, error) {
+	compiled := compiledRule{rule: rule}
+	switch rule.MatchType {
+	case MatchKeyword:
+		compiled.regexp = keywordRegexp(rule.Pattern)
+	case MatchDomain:
+		domain, err := normalizeDomain(rule.Pattern)
+		if err != nil {
+			return compiled, err
+		}
+		compiled.domain = domain
+	case MatchRegexLite:
+		re, err := compileRegexLite(rule.Pattern)
+		if err != nil {
+			return compiled, err
+		}
+		compiled.regexp = re
+	default:
+		return compiled, fmt.Errorf("matchType must be 'keyword', 'domain', or 'regex-lite'")
+	}
+	return compiled, nil
+}
+
+// keywordRegexp matches a keyword case-insensitively as a whole word
+// Word boundaries are only required at edges that are word characters, so "c++" or "#tag"
+// still match.
+func keywordRegexp(keyword string) *regexp.Regexp {
+	keyword = strings.TrimSpace(keyword)
+	pattern := regexp.QuoteMeta(keyword)
+	if first, _ := utf8.DecodeRuneInString(keyword); isWordRune(first) {
+		pattern = `\b` + pattern
+	}
+	if last, _ := utf8.DecodeLastRuneInString(keyword); isWordRune(last) {
+		pattern += `\b`
+	}
+	return regexp.MustCompile("(?i)" + pattern)
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// normalizeDomain reduces a domain pattern to a bare lowercase host
+// Schemes, a leading "www." and paths are dropped so "https://www.example.com/" works too.
+func normalizeDomain(pattern string) (string, error) {
+	domain := strings.ToLower(strings.TrimSpace(pattern))
+	if i := strings.Index(domain, "://"); i >= 0 {
+		domain = domain[i+3:]
+	}
+	if i := strings.IndexAny(domain, "/?#"); i >= 0 {
+		domain = domain[:i]
+	}
+	domain = strings.TrimPrefix(domain, "www.")
+	domain = strings.TrimSuffix(domain, ".")
+	if !strings.Contains(domain, ".") || strings.ContainsAny(domain, " \t:@") {
+		return "", fmt.Errorf("domain must be a hostname like example.com")
+	}
+	return domain, nil
+}
+
+// Evaluate returns the strictest rule the content trips, or nil
+// Among rules with the same action the first configured wins.
+func (s *RuleSet) Evaluate(content Content) *Match {
+	if s == nil || len(s.rules) == 0 {
+		return nil
+	}
+
+	var hosts []string
+	var best *compiledRule
+	for i := range s.rules {
+		rule := &s.rules[i]
+		if best != nil && rule.rule.Action.severity() <= best.rule.Action.severity() {
+			continue
+		}
+
+		matched := false
+		if rule.regexp != nil {
+			matched = rule.regexp.MatchString(content.Text)
+		} else {
+			if hosts == nil {
+				hosts = linkHosts(content)
+			}
+			matched = matchesDomain(hosts, rule.domain)
+		}
+		if matched {
+			best = rule
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+	return &Match{Rule: best.rule}
+}
+
+// linkHosts returns the lowercase hosts of every link in the content
+func linkHosts(content Content) []string {
+	links := append(append([]string(nil), content.Links...), textLinkPattern.FindAllString(content.Text, -1)...)
+	hosts := make([]string, 0, len(links))
+	for _, link := range links {
+		u, err := url.Parse(strings.TrimSpace(link))
+		if err != nil || u.Hostname() == "" {
+			continue
+		}
+		hosts = append(hosts, strings.TrimSuffix(strings.ToLower(u.Hostname()), "."))
+	}
+	return hosts
+}
+
+// matchesDomain reports whether any host is domain or one of its subdomains
+func matchesDomain(hosts []string, domain string) bool {
+	for _, host := range hosts {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package automod
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleSet_Evaluate(t *testing.T) {
+	rules, err := Compile([]Rule{
+		{Pattern: "crypto", MatchType: MatchKeyword, Action: ActionFlag},
+		{Pattern: "buy now", MatchType: MatchKeyword, Action: ActionHold},
+		{Pattern: "https://www.spam.example/", MatchType: MatchDomain, Action: ActionRemove},
+		{Pattern: "c++", MatchType: MatchKeyword, Action: ActionFlag},
+		{Pattern: `free\s+(money|coins)`, MatchType: MatchRegexLite, Action: ActionHold},
+	})
+	if err != nil {
+		t.Fatalf("Compile failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content Content
+		want    Action
+		pattern string
+	}{
+		{name: "keyword any case", content: Content{Text: "Love CRYPTO"}, want: ActionFlag, pattern: "crypto"},
+		{name: "keyword inside a word", content: Content{Text: "cryptography lecture"}},
+		{name: "multi-word keyword", content: Content{Text: "Buy now, limited offer"}, want: ActionHold, pattern: "buy now"},
+		{name: "keyword with symbols", content: Content{Text: "learning c++ today"}, want: ActionFlag, pattern: "c++"},
+		{name: "domain in links", content: Content{Links: []string{"https://spam.example/a"}}, want: ActionRemove},
+		{name: "subdomain", content: Content{Links: []string{"http://deals.SPAM.example"}}, want: ActionRemove},
+		{name: "domain written in text", content: Content{Text: "see https://spam.example/x."}, want: ActionRemove},
+		{name: "lookalike domain", content: Content{Links: []string{"https://notspam.example"}}},
+		{name: "domain as plain text", content: Content{Text: "spam.example is a domain"}},
+		{name: "regex-lite", content: Content{Text: "FREE   coins here"}, want: ActionHold, pattern: `free\s+(money|coins)`},
+		{name: "strictest wins", content: Content{Text: "crypto: buy now https://spam.example"}, want: ActionRemove},
+		{name: "first rule breaks ties", content: Content{Text: "crypto and c++"}, want: ActionFlag, pattern: "crypto"},
+		{name: "no match", content: Content{Text: "a perfectly normal post"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match := rules.Evaluate(tt.content)
+			if tt.want == "" {
+				if match != nil {
+					t.Fatalf("Expected no match, got %+v", match.Rule)
+				}
+				return
+			}
+			if match == nil {
+				t.Fatalf("Expected %s, got no match", tt.want)
+			}
+			if match.Action() != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, match.Action())
+			}
+			if tt.pattern != "" && match.Rule.Pattern != tt.pattern {
+				t.Errorf("Expected rule %q, got %q", tt.pattern, match.Rule.Pattern)
+			}
+		})
+	}
+
+	var empty *RuleSet
+	if empty.Evaluate(Content{Text: "anything"}) != nil {
+		t.Error("Expected a nil rule set to match nothing")
+	}
+}
+
+func TestValidateRules(t *testing.T) {
+	tooMany := make([]Rule, MaxRules+1)
+	for i := range tooMany {
+		tooMany[i] = Rule{Pattern: "word", MatchType: MatchKeyword, Action: ActionFlag}
+	}
+
+	tests := []struct {
+		name    string
+		rules   []Rule
+		wantErr bool
+	}{
+		{name: "empty", rules: []Rule{}},
+		{name: "one of each", rules: []Rule{
+			{Pattern: "spam", MatchType: MatchKeyword, Action: ActionRemove},
+			{Pattern: "example.com", MatchType: MatchDomain, Action: ActionHold},
+			{Pattern: `^promo\d{2,4}$`, MatchType: MatchRegexLite, Action: ActionFlag},
+		}},
+		{name: "too many rules", rules: tooMany, wantErr: true},
+		{name: "empty pattern", rules: []Rule{{Pattern: " ", MatchType: MatchKeyword, Action: ActionFlag}}, wantErr: true},
+		{name: "long pattern", rules: []Rule{{Pattern: strings.Repeat("a", MaxPatternLength+1), MatchType: MatchKeyword, Action: ActionFlag}}, wantErr: true},
+		{name: "unknown match type", rules: []Rule{{Pattern: "x", MatchType: "glob", Action: ActionFlag}}, wantErr: true},
+		{name: "unknown action", rules: []Rule{{Pattern: "x", MatchType: MatchKeyword, Action: "ban"}}, wantErr: true},
+		{name: "bare word domain", rules: []Rule{{Pattern: "localhost", MatchType: MatchDomain, Action: ActionFlag}}, wantErr: true},
+		{name: "invalid regex", rules: []Rule{{Pattern: "(unclosed", MatchType: MatchRegexLite, Action: ActionFlag}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRules(tt.rules)
+			if tt.wantErr {
+				if !IsValidationError(err) {
+					t.Fatalf("Expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected valid rules, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCompileRegexLite(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{pattern: `free\s+money`},
+		{pattern: `^(buy|sell)\b`},
+		{pattern: `[a-z0-9]+@spam\.example`},
+		{pattern: `x{2,10}`},
+		{pattern: `(a+)+`, wantErr: true},
+		{pattern: `(a|b*)*`, wantErr: true},
+		{pattern: `a{2,}`, wantErr: true},
+		{pattern: `a{1,11}`, wantErr: true},
+		{pattern: `(?s)a.b`, wantErr: true},
+		{pattern: `(?-i)Case`, wantErr: true},
+		{pattern: strings.Repeat(`(a|b)`, 60), wantErr: true},
+		{pattern: `(x{10}){0,10}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := compileRegexLite(tt.pattern)
+			if tt.wantErr && err == nil {
+				t.Fatal("Expected pattern to be rejected")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("Expected pattern to compile, got %v", err)
+			}
+		})
+	}
+
+	re, err := compileRegexLite(`free\s+money`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("FREE MONEY") {
+		t.Error("Expected regex-lite to match case-insensitively")
+	}
+}
@@ -0,0 +1,35 @@
+package automod
+
+import (
+	"errors"
+	"fmt"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+// ErrActionNotFound is returned when reviewing a queued action that doesn't exist or was already reviewed
+var ErrActionNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "automod action not found")
+
+// ValidationError represents a validation error with field details
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error: %s - %s", e.Field, e.Message)
+}
+
+// NewValidationError creates a new validation error
+func NewValidationError(field, message string) error {
+	return &ValidationError{
+		Field:   field,
+		Message: message,
+	}
+}
+
+// IsValidationError reports whether err is a ValidationError
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
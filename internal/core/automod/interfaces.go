@@ -0,0 +1,36 @@
+package automod
+
+import "context"
+
+// Repository stores communities' automod rules and the actions they took
+type Repository interface {
+	// GetRules returns a community's rules; communities that never configured automod have none
+	GetRules(ctx context.Context, communityDID string) ([]Rule, error)
+	// SetRules replaces a community's rules
+	SetRules(ctx context.Context, communityDID string, rules []Rule, updatedBy string) error
+	// RecordAction stores the audit row of an applied action; recording a subject again is a no-op
+	RecordAction(ctx context.Context, record *ActionRecord) error
+}
+
+// QueueRepository lets moderators review held and flagged content
+type QueueRepository interface {
+	// ListOpenActions returns a community's actions awaiting review, newest first
+	ListOpenActions(ctx context.Context, communityDID string, limit, offset int) ([]*ActionRecord, error)
+	// GetAction returns an action by ID, reviewed or not
+	GetAction(ctx context.Context, id int64) (*ActionRecord, error)
+	// ReviewAction approves or removes an open action's content and closes it in one
+	// transaction; ErrActionNotFound unless it's open
+	ReviewAction(ctx context.Context, id int64, status ReviewStatus, reviewerDID string) error
+}
+
+// Service evaluates content against community rules at index time and manages the rules
+type Service interface {
+	// Evaluate returns the strictest rule the content trips in the community, or nil
+	Evaluate(ctx context.Context, communityDID string, content Content) (*Match, error)
+	// RecordAction stores the audit row of an applied action
+	RecordAction(ctx context.Context, record *ActionRecord) error
+
+	GetRules(ctx context.Context, communityDID string) ([]Rule, error)
+	// UpdateRules validates and replaces a community's rules, dropping its compiled rules from the cache
+	UpdateRules(ctx context.Context, communityDID string, rules []Rule, actorDID string) error
+}
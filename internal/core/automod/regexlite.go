@@ -0,0 +1,87 @@
+package automod
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+)
+
+// Regex-lite complexity caps
+// Go's RE2 engine already runs in linear time, so these bound the size of the compiled
+// program rather than backtracking: every post and comment in a community runs through up to
+// MaxRules of them.
+const (
+	// maxRegexNodes bounds the parsed expression tree
+	maxRegexNodes = 100
+
+	// maxRegexRepeat bounds counted repetition (x{n,m}); each repeat copies the sub-expression
+	maxRegexRepeat = 10
+
+	// maxRegexInstructions bounds the compiled program
+	maxRegexInstructions = 1000
+)
+
+// compileRegexLite compiles a case-insensitive expression from a safe subset of the syntax
+// Allowed: literals, ., character classes, anchors, word boundaries, groups, alternation and
+// the ?, *, + and bounded {n,m} quantifiers. Quantified expressions can't themselves contain
+// a quantifier, and flags can't be set inline.
+func compileRegexLite(pattern string) (*regexp.Regexp, error) {
+	parsed, err := syntax.Parse(pattern, syntax.Perl|syntax.FoldCase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+
+	nodes := 0
+	if err := checkRegexLite(parsed, false, &nodes); err != nil {
+		return nil, err
+	}
+
+	prog, err := syntax.Compile(parsed.Simplify())
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+	if len(prog.Inst) > maxRegexInstructions {
+		return nil, errors.New("regular expression is too complex")
+	}
+
+	return regexp.Compile("(?i)" + pattern)
+}
+
+// checkRegexLite walks the parsed expression rejecting anything outside the safe subset
+func checkRegexLite(re *syntax.Regexp, quantified bool, nodes *int) error {
+	*nodes++
+	if *nodes > maxRegexNodes {
+		return errors.New("regular expression is too complex")
+	}
+	// Inline flags like (?s) show up as flags outside the ones the pattern was parsed with;
+	// (?-i) as literals that lost case folding
+	if re.Flags&^(syntax.Perl|syntax.FoldCase|syntax.WasDollar|syntax.NonGreedy) != 0 ||
+		(re.Op == syntax.OpLiteral && re.Flags&syntax.FoldCase == 0) {
+		return errors.New("inline flags are not supported")
+	}
+
+	switch re.Op {
+	case syntax.OpLiteral, syntax.OpCharClass, syntax.OpAnyCharNotNL, syntax.OpAnyChar,
+		syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary, syntax.OpEmptyMatch,
+		syntax.OpCapture, syntax.OpConcat, syntax.OpAlternate:
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		if quantified {
+			return errors.New("nested quantifiers are not supported")
+		}
+		if re.Op == syntax.OpRepeat && (re.Max == -1 || re.Max > maxRegexRepeat) {
+			return fmt.Errorf("counted repetition must have an upper bound of at most %d", maxRegexRepeat)
+		}
+		quantified = true
+	default:
+		return fmt.Errorf("unsupported regular expression syntax: %s", re)
+	}
+
+	for _, sub := range re.Sub {
+		if err := checkRegexLite(sub, quantified, nodes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package automod
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MatchType is how a rule's pattern is matched against content
+type MatchType string
+
+const (
+	// MatchKeyword matches a word or phrase anywhere in the text, ignoring case
+	MatchKeyword MatchType = "keyword"
+
+	// MatchDomain matches links to a domain or any of its subdomains
+	MatchDomain MatchType = "domain"
+
+	// MatchRegexLite matches a case-insensitive regular expression from a safe subset of the
+	// syntax (see compileRegexLite)
+	MatchRegexLite MatchType = "regex-lite"
+)
+
+// Action is what happens to content that trips a rule
+type Action string
+
+const (
+	// ActionRemove indexes the content hidden; moderators don't need to review it
+	ActionRemove Action = "remove"
+
+	// ActionHold indexes the content hidden until a moderator approves it from the queue
+	ActionHold Action = "hold"
+
+	// ActionFlag indexes the content as normal and queues it for a moderator to look at
+	ActionFlag Action = "flag"
+)
+
+// severity orders actions so the strictest matching rule wins
+func (a Action) severity() int {
+	switch a {
+	case ActionRemove:
+		return 3
+	case ActionHold:
+		return 2
+	case ActionFlag:
+		return 1
+	}
+	return 0
+}
+
+const (
+	// MaxRules is how many rules a community may configure
+	MaxRules = 50
+
+	// MaxPatternLength bounds a single rule's pattern
+	MaxPatternLength = 256
+)
+
+// Rule is one automod filter in a community's configuration
+type Rule struct {
+	Pattern   string    `json:"pattern"`
+	MatchType MatchType `json:"matchType"`
+	Action    Action    `json:"action"`
+}
+
+// ValidateRules checks a community's rule list from updateAutomod
+// Regex-lite patterns are compiled so unsafe or overly complex expressions are rejected up front.
+func ValidateRules(rules []Rule) error {
+	if len(rules) > MaxRules {
+		return NewValidationError("rules", fmt.Sprintf("at most %d rules are allowed", MaxRules))
+	}
+	for i, rule := range rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		if strings.TrimSpace(rule.Pattern) == "" {
+			return NewValidationError(field+".pattern", "pattern is required")
+		}
+		if len(rule.Pattern) > MaxPatternLength {
+			return NewValidationError(field+".pattern", fmt.Sprintf("pattern must be at most %d bytes", MaxPatternLength))
+		}
+		if rule.Action.severity() == 0 {
+			return NewValidationError(field+".action", "action must be 'remove', 'hold', or 'flag'")
+		}
+		if _, err := compileRule(rule); err != nil {
+			return NewValidationError(field+".pattern", err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package automod
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ruleCacheTTL bounds how long compiled rules are reused
+// Updates through this process invalidate the cache immediately; the TTL only matters for
+// rules updated by another AppView process sharing the database.
+const ruleCacheTTL = time.Minute
+
+type cachedRules struct {
+	loadedAt time.Time
+	rules    *RuleSet
+}
+
+type automodService struct {
+	repo  Repository
+	now   func() time.Time
+	cache map[string]cachedRules
+	mu    sync.Mutex
+}
+
+// NewAutomodService creates an automod service that caches compiled rules per community
+func NewAutomodService(repo Repository) Service {
+	return &automodService{
+		repo:  repo,
+		now:   time.Now,
+		cache: make(map[string]cachedRules),
+	}
+}
+
+// Evaluate checks content against the community's rules
+func (s *automodService) Evaluate(ctx context.Context, communityDID string, content Content) (*Match, error) {
+	rules, err := s.ruleSet(ctx, communityDID)
+	if err != nil {
+		return nil, err
+	}
+	return rules.Evaluate(content), nil
+}
+
+// ruleSet returns the community's compiled rules, loading them on a cache miss
+func (s *automodService) ruleSet(ctx context.Context, communityDID string) (*RuleSet, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[communityDID]
+	s.mu.Unlock()
+	if ok && s.now().Sub(cached.loadedAt) < ruleCacheTTL {
+		return cached.rules, nil
+	}
+
+	rules, err := s.repo.GetRules(ctx, communityDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load automod rules for %s: %w", communityDID, err)
+	}
+	compiled, err := Compile(rules)
+	if err != nil {
+		// Saved rules were validated, so this only happens if the safe subset was narrowed;
+		// evaluate nothing rather than block indexing for the community
+		log.Printf("Warning: automod rules for %s no longer compile, skipping: %v", communityDID, err)
+		compiled = &RuleSet{}
+	}
+
+	s.mu.Lock()
+	s.cache[communityDID] = cachedRules{rules: compiled, loadedAt: s.now()}
+	s.mu.Unlock()
+	return compiled, nil
+}
+
+// RecordAction stores the audit row of an applied action
+func (s *automodService) RecordAction(ctx context.Context, record *ActionRecord) error {
+	if err := s.repo.RecordAction(ctx, record); err != nil {
+		return fmt.Errorf("failed to record automod action on %s: %w", record.SubjectURI, err)
+	}
+	return nil
+}
+
+// GetRules returns a community's rules
+func (s *automodService) GetRules(ctx context.Context, communityDID string) ([]Rule, error) {
+	rules, err := s.repo.GetRules(ctx, communityDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get automod rules for %s: %w", communityDID, err)
+	}
+	return rules, nil
+}
+
+// UpdateRules validates and replaces a community's rules
+func (s *automodService) UpdateRules(ctx context.Context, communityDID string, rules []Rule, actorDID string) error {
+	if rules == nil {
+		rules = []Rule{}
+	}
+	if err := ValidateRules(rules); err != nil {
+		return err
+	}
+	if err := s.repo.SetRules(ctx, communityDID, rules, actorDID); err != nil {
+		return fmt.Errorf("failed to update automod rules for %s: %w", communityDID, err)
+	}
+
+	s.mu.Lock()
+	delete(s.cache, communityDID)
+	s.mu.Unlock()
+	return nil
+}
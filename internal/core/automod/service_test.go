@@ -0,0 +1,117 @@
+package automod
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// mockRepository keeps rules and actions in memory and counts rule loads
+type mockRepository struct {
+	rules   map[string][]Rule
+	actions []*ActionRecord
+	loads   int
+}
+
+func (m *mockRepository) GetRules(ctx context.Context, communityDID string) ([]Rule, error) {
+	m.loads++
+	return m.rules[communityDID], nil
+}
+
+func (m *mockRepository) SetRules(ctx context.Context, communityDID string, rules []Rule, updatedBy string) error {
+	m.rules[communityDID] = rules
+	return nil
+}
+
+func (m *mockRepository) RecordAction(ctx context.Context, record *ActionRecord) error {
+	m.actions = append(m.actions, record)
+	return nil
+}
+
+func TestService_CachesRulesUntilUpdated(t *testing.T) {
+	ctx := context.Background()
+	repo := &mockRepository{rules: map[string][]Rule{
+		"did:plc:community": {{Pattern: "spam", MatchType: MatchKeyword, Action: ActionHold}},
+	}}
+	svc := NewAutomodService(repo).(*automodService)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	content := Content{Text: "spam and eggs"}
+	for i := 0; i < 3; i++ {
+		match, err := svc.Evaluate(ctx, "did:plc:community", content)
+		if err != nil {
+			t.Fatalf("Evaluate failed: %v", err)
+		}
+		if match == nil || match.Action() != ActionHold {
+			t.Fatalf("Expected a hold, got %+v", match)
+		}
+	}
+	if repo.loads != 1 {
+		t.Errorf("Expected rules to be loaded once, got %d", repo.loads)
+	}
+
+	// Updating drops the cached rules straight away
+	if err := svc.UpdateRules(ctx, "did:plc:community", []Rule{
+		{Pattern: "spam", MatchType: MatchKeyword, Action: ActionRemove},
+	}, "did:plc:mod"); err != nil {
+		t.Fatalf("UpdateRules failed: %v", err)
+	}
+	match, err := svc.Evaluate(ctx, "did:plc:community", content)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if match == nil || match.Action() != ActionRemove {
+		t.Fatalf("Expected the updated rule to apply, got %+v", match)
+	}
+
+	// Rules changed by another process are picked up once the TTL passes
+	repo.rules["did:plc:community"] = nil
+	now = now.Add(ruleCacheTTL + time.Second)
+	match, err = svc.Evaluate(ctx, "did:plc:community", content)
+	if err != nil {
+		t.Fatalf("Evaluate failed: %v", err)
+	}
+	if match != nil {
+		t.Errorf("Expected no match after the rules were cleared, got %+v", match.Rule)
+	}
+	if repo.loads != 3 {
+		t.Errorf("Expected 3 loads, got %d", repo.loads)
+	}
+}
+
+func TestService_UpdateRulesValidates(t *testing.T) {
+	repo := &mockRepository{rules: map[string][]Rule{}}
+	svc := NewAutomodService(repo)
+
+	err := svc.UpdateRules(context.Background(), "did:plc:community", []Rule{
+		{Pattern: "(a*)*", MatchType: MatchRegexLite, Action: ActionFlag},
+	}, "did:plc:mod")
+	if !IsValidationError(err) {
+		t.Fatalf("Expected a validation error, got %v", err)
+	}
+	if _, saved := repo.rules["did:plc:community"]; saved {
+		t.Error("Expected invalid rules not to be saved")
+	}
+
+	if err := svc.UpdateRules(context.Background(), "did:plc:community", nil, "did:plc:mod"); err != nil {
+		t.Fatalf("Expected clearing rules to succeed, got %v", err)
+	}
+	if rules := repo.rules["did:plc:community"]; rules == nil || len(rules) != 0 {
+		t.Errorf("Expected an empty rule list to be saved, got %v", rules)
+	}
+}
+
+func TestNewActionRecord(t *testing.T) {
+	for action, want := range map[Action]ReviewStatus{
+		ActionRemove: ReviewRemoved,
+		ActionHold:   ReviewOpen,
+		ActionFlag:   ReviewOpen,
+	} {
+		match := &Match{Rule: Rule{Pattern: "x", MatchType: MatchKeyword, Action: action}}
+		record := NewActionRecord(match, "at://did:plc:c/social.coves.community.post/1", "did:plc:c", "did:plc:a")
+		if record.Status != want {
+			t.Errorf("%s: expected status %s, got %s", action, want, record.Status)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Package blobrefs tracks which indexed records reference each blob, so the image proxy can
+// refuse blobs whose every referencing record has been taken down, removed or deleted.
+package blobrefs
+
+import (
+	"context"
+	"sort"
+)
+
+// BlockList is what the image proxy checks before serving a blob
+type BlockList interface {
+	// IsBlocked reports whether every record referencing the blob is hidden
+	IsBlocked(ctx context.Context, cid string) (bool, error)
+}
+
+// Repository stores blob references and the resulting block list
+//
+// A blob is blocked while it has references and none of them is live. A post or comment
+// reference is live unless the record is deleted, taken down or removed by a moderator;
+// profile references are always live. Blobs without any reference are never blocked.
+type Repository interface {
+	BlockList
+
+	// SetReferences replaces the blobs the record references and refreshes the block state of
+	// every blob it referenced before or references now
+	SetReferences(ctx context.Context, recordURI string, cids []string) error
+	// Refresh re-evaluates the block state of the blobs the record references
+	// Called after the record is taken down, removed, deleted or reinstated.
+	Refresh(ctx context.Context, recordURI string) error
+}
+
+// CIDs returns the sorted, distinct CIDs of every blob in a record
+// Blobs nest anywhere (embed images, external thumbnails, avatars), so the whole record is walked.
+func CIDs(record map[string]interface{}) []string {
+	var cids []string
+	seen := make(map[string]bool)
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if cid, ok := blobCID(v); ok {
+				if !seen[cid] {
+					seen[cid] = true
+					cids = append(cids, cid)
+				}
+				return
+			}
+			for _, child := range v {
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(record)
+	sort.Strings(cids)
+	return cids
+}
+
+// blobCID extracts the CID from a blob reference
+// Blob format: {"$type": "blob", "ref": {"$link": "cid"}, "mimeType": "...", "size": 123}
+func blobCID(blob map[string]interface{}) (string, bool) {
+	if blobType, _ := blob["$type"].(string); blobType != "blob" {
+		return "", false
+	}
+	ref, ok := blob["ref"].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	link, ok := ref["$link"].(string)
+	return link, ok && link != ""
+}
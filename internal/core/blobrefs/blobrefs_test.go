@@ -0,0 +1,71 @@
+package blobrefs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func blob(cid string) map[string]interface{} {
+	return map[string]interface{}{
+		"$type":    "blob",
+		"ref":      map[string]interface{}{"$link": cid},
+		"mimeType": "image/jpeg",
+		"size":     1024,
+	}
+}
+
+func TestCIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		record map[string]interface{}
+		want   []string
+	}{
+		{
+			name: "image embed",
+			record: map[string]interface{}{
+				"$type": "social.coves.community.post",
+				"embed": map[string]interface{}{
+					"$type": "social.coves.embed.images",
+					"images": []interface{}{
+						map[string]interface{}{"image": blob("bafkreib"), "alt": "second"},
+						map[string]interface{}{"image": blob("bafkreia"), "alt": "first"},
+						map[string]interface{}{"image": blob("bafkreia"), "alt": "again"},
+					},
+				},
+			},
+			want: []string{"bafkreia", "bafkreib"},
+		},
+		{
+			name: "external thumbnail",
+			record: map[string]interface{}{
+				"embed": map[string]interface{}{
+					"$type":    "social.coves.embed.external",
+					"external": map[string]interface{}{"uri": "https://example.com", "thumb": blob("bafkreithumb")},
+				},
+			},
+			want: []string{"bafkreithumb"},
+		},
+		{
+			name:   "profile avatar and banner",
+			record: map[string]interface{}{"avatar": blob("bafkreiavatar"), "banner": blob("bafkreibanner")},
+			want:   []string{"bafkreiavatar", "bafkreibanner"},
+		},
+		{
+			name: "not blobs",
+			record: map[string]interface{}{
+				"title":  "No images here",
+				"ref":    map[string]interface{}{"$link": "bafkreinotablob"},
+				"legacy": map[string]interface{}{"cid": "bafkreilegacy", "mimeType": "image/png"},
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CIDs(tt.record); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CIDs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -38,6 +38,16 @@ func HydrateImageProxyURL(proxyBaseURL, preset, did, cid string) string {
 		url.PathEscape(did) + "/" + url.PathEscape(cid)
 }
 
+// HydrateIdenticonURL generates the URL of a DID's generated fallback avatar.
+// Format: {proxyBaseURL}/img/identicon/{did}
+// Returns empty string if did is empty.
+func HydrateIdenticonURL(proxyBaseURL, did string) string {
+	if did == "" {
+		return ""
+	}
+	return strings.TrimSuffix(proxyBaseURL, "/") + "/img/identicon/" + url.PathEscape(did)
+}
+
 // ImageURLConfig holds configuration for image URL generation.
 type ImageURLConfig struct {
 	ProxyEnabled bool   // Whether the image proxy is enabled
@@ -79,3 +89,21 @@ func HydrateImageURL(config ImageURLConfig, pdsURL, did, cid, preset string) str
 
 	return proxyURL
 }
+
+// HydrateAvatarURL generates an avatar URL like HydrateImageURL, falling back to the DID's
+// identicon when there's no avatar blob. Identicons are served by the image proxy, so
+// without it an account with no avatar still gets an empty string.
+func HydrateAvatarURL(config ImageURLConfig, pdsURL, did, cid, preset string) string {
+	if cid != "" {
+		return HydrateImageURL(config, pdsURL, did, cid, preset)
+	}
+	if !config.ProxyEnabled {
+		return ""
+	}
+
+	baseURL := config.ProxyBaseURL
+	if config.CDNURL != "" {
+		baseURL = config.CDNURL
+	}
+	return HydrateIdenticonURL(baseURL, did)
+}
@@ -272,3 +272,48 @@ func TestImageURLConfig(t *testing.T) {
 		t.Errorf("CDNURL = %q, want %q", config.CDNURL, "https://cdn.coves.social")
 	}
 }
+
+func TestHydrateAvatarURL(t *testing.T) {
+	enabled := ImageURLConfig{ProxyEnabled: true, ProxyBaseURL: "https://coves.social/"}
+	did := "did:plc:abc123"
+
+	tests := []struct {
+		name   string
+		config ImageURLConfig
+		cid    string
+		want   string
+	}{
+		{
+			name:   "avatar blob",
+			config: enabled,
+			cid:    "bafyreiabc123",
+			want:   HydrateImageProxyURL(enabled.ProxyBaseURL, "avatar", did, "bafyreiabc123"),
+		},
+		{
+			name:   "no avatar falls back to identicon",
+			config: enabled,
+			want:   "https://coves.social/img/identicon/did:plc:abc123",
+		},
+		{
+			name:   "identicon through CDN",
+			config: ImageURLConfig{ProxyEnabled: true, ProxyBaseURL: "https://coves.social", CDNURL: "https://cdn.coves.social"},
+			want:   "https://cdn.coves.social/img/identicon/did:plc:abc123",
+		},
+		{
+			name:   "no identicon without the proxy",
+			config: ImageURLConfig{ProxyEnabled: false},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HydrateAvatarURL(tt.config, "https://pds.example.com", did, tt.cid, "avatar"); got != tt.want {
+				t.Errorf("HydrateAvatarURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := HydrateIdenticonURL("", ""); got != "" {
+		t.Errorf("HydrateIdenticonURL with empty DID = %q, want empty", got)
+	}
+}
@@ -0,0 +1,49 @@
+package brigade
+
+import (
+	"time"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+// ErrAlertNotFound is returned when reviewing an alert that doesn't exist or was already reviewed
+var ErrAlertNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "brigade alert not found")
+
+// NotificationReason is the notifications.reason value moderators are notified of alerts with
+const NotificationReason = "brigade_alert"
+
+// AlertStatus is where an alert is in the moderation queue
+type AlertStatus string
+
+const (
+	// AlertOpen alerts await a moderator
+	AlertOpen AlertStatus = "open"
+
+	// AlertDismissed alerts were organic after all
+	AlertDismissed AlertStatus = "dismissed"
+
+	// AlertConfirmed alerts were a brigade; moderators act on the votes or post separately
+	AlertConfirmed AlertStatus = "confirmed"
+)
+
+// IsReview reports whether s is a status a moderator can review an alert to
+func (s AlertStatus) IsReview() bool {
+	return s == AlertDismissed || s == AlertConfirmed
+}
+
+// Alert flags a post whose votes looked like a brigade
+// A post is alerted at most once; the bucket is the one that tripped detection.
+type Alert struct {
+	BucketStart      time.Time   `json:"bucketStart"`
+	CreatedAt        time.Time   `json:"createdAt"`
+	ReviewedAt       *time.Time  `json:"reviewedAt,omitempty"`
+	PostURI          string      `json:"post"`
+	CommunityDID     string      `json:"community"`
+	Status           AlertStatus `json:"status"`
+	ReviewedBy       string      `json:"reviewedBy,omitempty"`
+	ID               int64       `json:"id"`
+	Votes            int         `json:"votes"`
+	Outsiders        int         `json:"outsiders"`
+	ZScore           float64     `json:"zScore,string"`           // Strings: lexicons have no float type
+	BaselineVelocity float64     `json:"baselineVelocity,string"` // The community's mean votes per post per bucket
+}
@@ -0,0 +1,83 @@
+package brigade
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Report summarizes one analyzer run
+type Report struct {
+	Assessed int // Buckets with enough votes to assess
+	Alerts   int // New alerts raised
+}
+
+// Analyzer looks for brigaded posts one bucket at a time
+// Run it every BucketSize: each run assesses the last complete bucket.
+type Analyzer struct {
+	repo Repository
+	cfg  Config
+}
+
+// NewAnalyzer creates an analyzer reading votes and recording alerts in repo
+func NewAnalyzer(repo Repository, cfg Config) *Analyzer {
+	return &Analyzer{repo: repo, cfg: cfg.withDefaults()}
+}
+
+// Config returns the analyzer's effective thresholds
+func (a *Analyzer) Config() Config {
+	return a.cfg
+}
+
+// Run assesses every busy post's votes in the last complete bucket against its community's
+// baseline, raising an alert for each brigade
+func (a *Analyzer) Run(ctx context.Context) (*Report, error) {
+	end := a.cfg.Now().UTC().Truncate(a.cfg.BucketSize)
+	start := end.Add(-a.cfg.BucketSize)
+
+	buckets, err := a.repo.ListBuckets(ctx, start, end, a.cfg.MinVotes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vote buckets: %w", err)
+	}
+
+	report := &Report{}
+	baselines := make(map[string]Baseline)
+	for _, bucket := range buckets {
+		baseline, ok := baselines[bucket.CommunityDID]
+		if !ok {
+			// The bucket being assessed isn't part of its own baseline
+			counts, err := a.repo.ListBucketCounts(ctx, bucket.CommunityDID, start.Add(-a.cfg.BaselineWindow), start, a.cfg.BucketSize)
+			if err != nil {
+				return report, fmt.Errorf("failed to get baseline for %s: %w", bucket.CommunityDID, err)
+			}
+			baseline = NewBaseline(counts)
+			baselines[bucket.CommunityDID] = baseline
+		}
+
+		report.Assessed++
+		verdict := Assess(a.cfg, *bucket, baseline)
+		if !verdict.Brigaded {
+			continue
+		}
+
+		created, err := a.repo.CreateAlert(ctx, &Alert{
+			BucketStart:      start,
+			PostURI:          bucket.PostURI,
+			CommunityDID:     bucket.CommunityDID,
+			Status:           AlertOpen,
+			Votes:            bucket.Votes,
+			Outsiders:        bucket.Outsiders,
+			ZScore:           verdict.ZScore,
+			BaselineVelocity: baseline.Mean,
+		})
+		if err != nil {
+			return report, fmt.Errorf("failed to create brigade alert for %s: %w", bucket.PostURI, err)
+		}
+		if created {
+			report.Alerts++
+			log.Printf("Brigade alert: %s got %d votes (%d from outsiders) in %s, z-score %.1f against a baseline of %.1f",
+				bucket.PostURI, bucket.Votes, bucket.Outsiders, a.cfg.BucketSize, verdict.ZScore, baseline.Mean)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,132 @@
+package brigade
+
+import (
+	"math"
+	"time"
+)
+
+// Default brigading thresholds
+const (
+	DefaultBucketSize       = 10 * time.Minute
+	DefaultBaselineWindow   = 7 * 24 * time.Hour
+	DefaultZScoreThreshold  = 3.0
+	DefaultOutsiderFraction = 0.6
+	DefaultMinVotes         = 10
+)
+
+// minStdDev floors the baseline's spread, so a community whose posts always get the same
+// few votes doesn't alert on one extra vote
+const minStdDev = 1.0
+
+// Config configures brigading detection
+// Zero values use the defaults above.
+type Config struct {
+	Now              func() time.Time // Clock deciding the last complete bucket; defaults to time.Now
+	BucketSize       time.Duration    // Vote velocity is votes per post per bucket
+	BaselineWindow   time.Duration    // How far back a community's baseline velocity looks
+	ZScoreThreshold  float64          // A bucket at least this many standard deviations above the baseline is anomalous
+	OutsiderFraction float64          // ...and is a brigade when at least this fraction of its voters are outsiders
+	MinVotes         int              // Buckets with fewer votes are never assessed
+}
+
+// DefaultConfig returns the default thresholds
+func DefaultConfig() Config {
+	return Config{
+		BucketSize:       DefaultBucketSize,
+		BaselineWindow:   DefaultBaselineWindow,
+		ZScoreThreshold:  DefaultZScoreThreshold,
+		OutsiderFraction: DefaultOutsiderFraction,
+		MinVotes:         DefaultMinVotes,
+	}
+}
+
+// withDefaults fills zero (or invalid) settings from DefaultConfig
+func (c Config) withDefaults() Config {
+	defaults := DefaultConfig()
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+	if c.BucketSize <= 0 {
+		c.BucketSize = defaults.BucketSize
+	}
+	if c.BaselineWindow <= 0 {
+		c.BaselineWindow = defaults.BaselineWindow
+	}
+	if c.ZScoreThreshold <= 0 {
+		c.ZScoreThreshold = defaults.ZScoreThreshold
+	}
+	if c.OutsiderFraction <= 0 || c.OutsiderFraction > 1 {
+		c.OutsiderFraction = defaults.OutsiderFraction
+	}
+	if c.MinVotes <= 0 {
+		c.MinVotes = defaults.MinVotes
+	}
+	return c
+}
+
+// Bucket is one post's votes during one bucket
+// Outsiders are voters who had never posted, commented or voted in the community before the
+// bucket's UTC day.
+type Bucket struct {
+	Start        time.Time
+	PostURI      string
+	CommunityDID string
+	Votes        int
+	Outsiders    int
+}
+
+// Baseline is a community's usual vote velocity: the spread of votes per post per bucket
+// over its recent buckets that had any votes
+type Baseline struct {
+	Mean    float64
+	StdDev  float64
+	Samples int
+}
+
+// NewBaseline summarizes per-bucket vote counts
+func NewBaseline(counts []int) Baseline {
+	baseline := Baseline{Samples: len(counts)}
+	if len(counts) == 0 {
+		return baseline
+	}
+	var sum float64
+	for _, count := range counts {
+		sum += float64(count)
+	}
+	baseline.Mean = sum / float64(len(counts))
+
+	var squares float64
+	for _, count := range counts {
+		diff := float64(count) - baseline.Mean
+		squares += diff * diff
+	}
+	baseline.StdDev = math.Sqrt(squares / float64(len(counts)))
+	return baseline
+}
+
+// ZScore is how many standard deviations votes is above the baseline's mean
+// A community with no history has a zero baseline, so any busy bucket stands out.
+func (b Baseline) ZScore(votes int) float64 {
+	return (float64(votes) - b.Mean) / math.Max(b.StdDev, minStdDev)
+}
+
+// Verdict is the outcome of assessing a bucket
+type Verdict struct {
+	ZScore           float64
+	OutsiderFraction float64
+	Brigaded         bool
+}
+
+// Assess decides whether a bucket is a brigade: anomalously fast voting, mostly by outsiders
+// Organic growth is fast too, but driven by the community's own members.
+func Assess(cfg Config, bucket Bucket, baseline Baseline) Verdict {
+	cfg = cfg.withDefaults()
+	verdict := Verdict{ZScore: baseline.ZScore(bucket.Votes)}
+	if bucket.Votes > 0 {
+		verdict.OutsiderFraction = float64(bucket.Outsiders) / float64(bucket.Votes)
+	}
+	verdict.Brigaded = bucket.Votes >= cfg.MinVotes &&
+		verdict.ZScore >= cfg.ZScoreThreshold &&
+		verdict.OutsiderFraction >= cfg.OutsiderFraction
+	return verdict
+}
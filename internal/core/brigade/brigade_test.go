@@ -0,0 +1,199 @@
+package brigade
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// syntheticVote is one vote in a generated stream
+type syntheticVote struct {
+	at       time.Time
+	post     string
+	outsider bool
+}
+
+// bucketize groups a vote stream into per-post buckets, like the repository does
+func bucketize(votes []syntheticVote, size time.Duration) map[time.Time]map[string]*Bucket {
+	buckets := make(map[time.Time]map[string]*Bucket)
+	for _, vote := range votes {
+		start := vote.at.Truncate(size)
+		if buckets[start] == nil {
+			buckets[start] = make(map[string]*Bucket)
+		}
+		bucket := buckets[start][vote.post]
+		if bucket == nil {
+			bucket = &Bucket{Start: start, PostURI: vote.post, CommunityDID: "did:plc:community"}
+			buckets[start][vote.post] = bucket
+		}
+		bucket.Votes++
+		if vote.outsider {
+			bucket.Outsiders++
+		}
+	}
+	return buckets
+}
+
+// baselineBefore builds the baseline from every bucket before start
+func baselineBefore(buckets map[time.Time]map[string]*Bucket, start time.Time) Baseline {
+	var counts []int
+	for bucketStart, posts := range buckets {
+		if !bucketStart.Before(start) {
+			continue
+		}
+		for _, bucket := range posts {
+			counts = append(counts, bucket.Votes)
+		}
+	}
+	return NewBaseline(counts)
+}
+
+// organicHistory is a week of a community's usual voting: a few members voting on a few posts
+// The counts vary between 2 and 7 votes per post per bucket.
+func organicHistory(start time.Time) []syntheticVote {
+	var votes []syntheticVote
+	for i := 0; i < 7*24*6; i += 5 {
+		bucketStart := start.Add(time.Duration(i) * DefaultBucketSize)
+		for n := 0; n < 2+i%6; n++ {
+			votes = append(votes, syntheticVote{at: bucketStart.Add(time.Duration(n) * time.Second), post: "at://post/old"})
+		}
+	}
+	return votes
+}
+
+func burst(start time.Time, post string, votes, outsiders int) []syntheticVote {
+	stream := make([]syntheticVote, 0, votes)
+	for n := 0; n < votes; n++ {
+		stream = append(stream, syntheticVote{at: start.Add(time.Duration(n) * time.Second), post: post, outsider: n < outsiders})
+	}
+	return stream
+}
+
+func TestNewBaseline(t *testing.T) {
+	empty := NewBaseline(nil)
+	assert.Zero(t, empty.Samples)
+	assert.Equal(t, 12.0, empty.ZScore(12), "without history every vote counts against a zero baseline")
+
+	baseline := NewBaseline([]int{2, 4, 4, 4, 5, 5, 7, 9})
+	assert.Equal(t, 8, baseline.Samples)
+	assert.InDelta(t, 5.0, baseline.Mean, 1e-9)
+	assert.InDelta(t, 2.0, baseline.StdDev, 1e-9)
+	assert.InDelta(t, 2.5, baseline.ZScore(10), 1e-9)
+
+	flat := NewBaseline([]int{3, 3, 3})
+	assert.Zero(t, flat.StdDev)
+	assert.InDelta(t, 1.0, flat.ZScore(4), 1e-9, "a flat baseline's spread is floored")
+	assert.False(t, math.IsInf(flat.ZScore(100), 0))
+}
+
+func TestAssess_SyntheticStreams(t *testing.T) {
+	cfg := DefaultConfig()
+	weekStart := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	now := weekStart.Add(7 * 24 * time.Hour)
+	history := organicHistory(weekStart)
+
+	tests := []struct {
+		name     string
+		stream   []syntheticVote
+		brigaded bool
+	}{
+		{"brigade: a burst from outsiders", burst(now, "at://post/target", 60, 50), true},
+		{"organic growth: a burst from members", burst(now, "at://post/popular", 60, 10), false},
+		{"a usual bucket from outsiders", burst(now, "at://post/quiet", 6, 6), false},
+		{"too few votes to assess", burst(now, "at://post/tiny", cfg.MinVotes-1, cfg.MinVotes-1), false},
+		{"outsiders right at the threshold", burst(now, "at://post/edge", 50, 30), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buckets := bucketize(append(append([]syntheticVote{}, history...), tt.stream...), cfg.BucketSize)
+			baseline := baselineBefore(buckets, now)
+			require.Positive(t, baseline.Samples)
+
+			bucket := buckets[now][tt.stream[0].post]
+			require.NotNil(t, bucket)
+			verdict := Assess(cfg, *bucket, baseline)
+			assert.Equal(t, tt.brigaded, verdict.Brigaded, "z=%.2f outsiders=%.2f", verdict.ZScore, verdict.OutsiderFraction)
+		})
+	}
+}
+
+func TestAssess_Thresholds(t *testing.T) {
+	baseline := NewBaseline([]int{5, 5, 6, 4})
+	bucket := Bucket{Votes: 20, Outsiders: 12}
+
+	assert.True(t, Assess(DefaultConfig(), bucket, baseline).Brigaded)
+	assert.False(t, Assess(Config{OutsiderFraction: 0.7}, bucket, baseline).Brigaded)
+	assert.False(t, Assess(Config{ZScoreThreshold: 50}, bucket, baseline).Brigaded)
+	assert.False(t, Assess(Config{MinVotes: 25}, bucket, baseline).Brigaded)
+}
+
+// fakeRepo serves fixed buckets and records alerts
+type fakeRepo struct {
+	counts  map[string][]int
+	alerted map[string]*Alert
+	buckets []*Bucket
+
+	start, end time.Time
+}
+
+func (f *fakeRepo) ListBuckets(ctx context.Context, start, end time.Time, minVotes int) ([]*Bucket, error) {
+	f.start, f.end = start, end
+	var result []*Bucket
+	for _, bucket := range f.buckets {
+		if bucket.Votes >= minVotes {
+			result = append(result, bucket)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeRepo) ListBucketCounts(ctx context.Context, communityDID string, since, until time.Time, bucketSize time.Duration) ([]int, error) {
+	return f.counts[communityDID], nil
+}
+
+func (f *fakeRepo) CreateAlert(ctx context.Context, alert *Alert) (bool, error) {
+	if _, ok := f.alerted[alert.PostURI]; ok {
+		return false, nil
+	}
+	f.alerted[alert.PostURI] = alert
+	return true, nil
+}
+
+func TestAnalyzer_Run(t *testing.T) {
+	now := time.Date(2026, 3, 9, 12, 34, 0, 0, time.UTC)
+	repo := &fakeRepo{
+		counts: map[string][]int{
+			"did:plc:quiet": {2, 3, 4, 3},
+			"did:plc:busy":  {40, 55, 60, 45},
+		},
+		alerted: make(map[string]*Alert),
+		buckets: []*Bucket{
+			{PostURI: "at://post/brigaded", CommunityDID: "did:plc:quiet", Votes: 40, Outsiders: 35},
+			{PostURI: "at://post/members", CommunityDID: "did:plc:quiet", Votes: 40, Outsiders: 4},
+			{PostURI: "at://post/usual", CommunityDID: "did:plc:busy", Votes: 50, Outsiders: 45},
+			{PostURI: "at://post/small", CommunityDID: "did:plc:quiet", Votes: 3, Outsiders: 3},
+		},
+	}
+	analyzer := NewAnalyzer(repo, Config{Now: func() time.Time { return now }})
+
+	report, err := analyzer.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 9, 12, 20, 0, 0, time.UTC), repo.start, "the last complete bucket is assessed")
+	assert.Equal(t, time.Date(2026, 3, 9, 12, 30, 0, 0, time.UTC), repo.end)
+	assert.Equal(t, 3, report.Assessed)
+	assert.Equal(t, 1, report.Alerts)
+	require.Contains(t, repo.alerted, "at://post/brigaded")
+	alert := repo.alerted["at://post/brigaded"]
+	assert.Equal(t, AlertOpen, alert.Status)
+	assert.Equal(t, repo.start, alert.BucketStart)
+	assert.InDelta(t, 3.0, alert.BaselineVelocity, 1e-9)
+
+	// The same post isn't alerted twice
+	report, err = analyzer.Run(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, report.Alerts)
+}
@@ -0,0 +1,21 @@
+package brigade
+
+import (
+	"context"
+	"time"
+)
+
+// Repository reads vote velocity and records brigade alerts
+type Repository interface {
+	// ListBuckets returns every post with at least minVotes votes indexed in [start, end),
+	// counting the voters who had no community activity before start's UTC day
+	ListBuckets(ctx context.Context, start, end time.Time, minVotes int) ([]*Bucket, error)
+
+	// ListBucketCounts returns the community's per-post vote counts for every bucket of the
+	// given size in [since, until) that had any votes
+	ListBucketCounts(ctx context.Context, communityDID string, since, until time.Time, bucketSize time.Duration) ([]int, error)
+
+	// CreateAlert records an alert and notifies the community's moderators in one transaction
+	// created is false when the post already has an alert.
+	CreateAlert(ctx context.Context, alert *Alert) (created bool, err error)
+}
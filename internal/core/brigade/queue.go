@@ -0,0 +1,13 @@
+package brigade
+
+import "context"
+
+// QueueRepository lets moderators review brigade alerts
+type QueueRepository interface {
+	// ListOpenAlerts returns a community's alerts awaiting review, newest first
+	ListOpenAlerts(ctx context.Context, communityDID string, limit, offset int) ([]*Alert, error)
+	// GetAlert returns an alert by ID, reviewed or not
+	GetAlert(ctx context.Context, id int64) (*Alert, error)
+	// ReviewAlert dismisses or confirms an open alert; ErrAlertNotFound unless it's open
+	ReviewAlert(ctx context.Context, id int64, status AlertStatus, reviewerDID string) error
+}
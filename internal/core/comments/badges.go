@@ -0,0 +1,62 @@
+package comments
+
+import (
+	"Coves/internal/core/posts"
+	"context"
+	"log/slog"
+)
+
+// threadBadges computes author badges for a post's comment thread
+// Everything in a thread belongs to the post's community, so one moderator set applies throughout
+type threadBadges struct {
+	moderators   map[string]bool
+	opDID        string
+	communityDID string
+}
+
+// newThreadBadges loads the moderators of the post's community for the thread's author badges
+// A failed lookup only drops the moderator badges
+func (s *commentService) newThreadBadges(ctx context.Context, postView *posts.PostView) *threadBadges {
+	badges := &threadBadges{}
+	if postView.Author != nil {
+		badges.opDID = postView.Author.DID
+	}
+	if postView.Community == nil {
+		return badges
+	}
+
+	badges.communityDID = postView.Community.DID
+	moderators, err := s.communityRepo.GetModeratorsForCommunities(ctx, []string{badges.communityDID})
+	if err != nil {
+		slog.Warn("failed to load community moderators for author badges",
+			"community_did", badges.communityDID, "error", err)
+		return badges
+	}
+	badges.moderators = moderators[badges.communityDID]
+	return badges
+}
+
+// badgePost sets the post author's badges
+func (b *threadBadges) badgePost(view *posts.PostView) {
+	if view == nil || view.Author == nil {
+		return
+	}
+	view.Author.Badges = posts.NewAuthorBadges(view.Author.DID, "", b.communityDID, b.moderators)
+}
+
+// badgeComment sets a comment author's badges
+// Deleted comment stubs don't attribute their author and are skipped
+func (b *threadBadges) badgeComment(view *CommentView) {
+	if view == nil || view.Author == nil || view.IsDeleted {
+		return
+	}
+	view.Author.Badges = posts.NewAuthorBadges(view.Author.DID, b.opDID, b.communityDID, b.moderators)
+}
+
+// badgeThreads applies badgeComment to every comment in the thread trees
+func (b *threadBadges) badgeThreads(threads []*ThreadViewComment) {
+	for _, thread := range threads {
+		b.badgeComment(thread.Comment)
+		b.badgeThreads(thread.Replies)
+	}
+}
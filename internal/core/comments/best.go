@@ -0,0 +1,88 @@
+package comments
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// wilsonZ is the z-score of the 95% confidence interval used by the best sort
+	wilsonZ = 1.96
+
+	// bestRecencyBonus is added to the best rank of a brand new comment, shrinking linearly
+	// to nothing at bestRecencyWindow, so new comments get a chance to collect votes
+	bestRecencyBonus  = 0.1
+	bestRecencyWindow = time.Hour
+
+	// bestTieTolerance is how close two best ranks are to count as tied for author diversity
+	bestTieTolerance = 0.01
+)
+
+// WilsonLowerBound returns the lower bound of the 95% Wilson score interval for the fraction
+// of upvotes, the "best" ranking from reddit. Comments with few votes rank below ones with
+// the same ratio and more votes; a comment with no votes ranks 0.
+// Must match the SQL in the postgres comment repository's best sort.
+func WilsonLowerBound(up, down int) float64 {
+	n := float64(up + down)
+	if n <= 0 {
+		return 0
+	}
+	p := float64(up) / n
+	z2 := wilsonZ * wilsonZ
+	return (p + z2/(2*n) - wilsonZ*math.Sqrt((p*(1-p)+z2/(4*n))/n)) / (1 + z2/n)
+}
+
+// BestRank returns a comment's rank in the best sort: its Wilson lower bound plus a small
+// bonus while it's under an hour old
+func BestRank(up, down int, age time.Duration) float64 {
+	rank := WilsonLowerBound(up, down)
+	if age < bestRecencyWindow {
+		if age < 0 {
+			age = 0
+		}
+		rank += bestRecencyBonus * (1 - float64(age)/float64(bestRecencyWindow))
+	}
+	return rank
+}
+
+// preferNewAuthors breaks ties in a best-sorted listing in favour of authors not already
+// shown earlier in it. Runs of comments whose ranks are within bestTieTolerance of the first
+// in the run are reordered so comments by unseen authors come first; the order is otherwise
+// kept.
+func preferNewAuthors(comments []*Comment, now time.Time) []*Comment {
+	if len(comments) < 2 {
+		return comments
+	}
+
+	ranks := make([]float64, len(comments))
+	for i, comment := range comments {
+		ranks[i] = BestRank(comment.UpvoteCount, comment.DownvoteCount, now.Sub(comment.CreatedAt))
+	}
+
+	result := make([]*Comment, 0, len(comments))
+	seen := make(map[string]bool, len(comments))
+	for start := 0; start < len(comments); {
+		end := start + 1
+		for end < len(comments) && math.Abs(ranks[start]-ranks[end]) <= bestTieTolerance {
+			end++
+		}
+
+		// Place the run's first comment by an unseen author, or its first comment if every
+		// author in it has already been shown
+		run := append([]*Comment(nil), comments[start:end]...)
+		for len(run) > 0 {
+			next := 0
+			for i, comment := range run {
+				if !seen[comment.CommenterDID] {
+					next = i
+					break
+				}
+			}
+			seen[run[next].CommenterDID] = true
+			result = append(result, run[next])
+			run = append(run[:next], run[next+1:]...)
+		}
+		start = end
+	}
+	return result
+}
@@ -0,0 +1,146 @@
+package comments
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWilsonLowerBound(t *testing.T) {
+	tests := []struct {
+		name     string
+		up, down int
+		want     float64
+	}{
+		{"no votes", 0, 0, 0},
+		{"only downvotes", 0, 5, 0},
+		{"single upvote", 1, 0, 0.2065},
+		{"ten upvotes", 10, 0, 0.7225},
+		{"hundred upvotes", 100, 0, 0.9630},
+		{"even split", 5, 5, 0.2366},
+		{"60 percent of 100", 60, 40, 0.5020},
+		{"60 percent of 1000", 600, 400, 0.5693},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, WilsonLowerBound(tt.up, tt.down), 0.0001)
+		})
+	}
+}
+
+func TestWilsonLowerBound_MoreVotesMoreConfidence(t *testing.T) {
+	// Same ratio, more votes ranks higher
+	assert.Greater(t, WilsonLowerBound(600, 400), WilsonLowerBound(60, 40))
+	assert.Greater(t, WilsonLowerBound(10, 0), WilsonLowerBound(1, 0))
+}
+
+func TestBestRank_RecencyBonus(t *testing.T) {
+	wilson := WilsonLowerBound(3, 1)
+
+	assert.InDelta(t, wilson+0.1, BestRank(3, 1, 0), 1e-9, "brand new comments get the full bonus")
+	assert.InDelta(t, wilson+0.05, BestRank(3, 1, 30*time.Minute), 1e-9, "the bonus shrinks over the first hour")
+	assert.InDelta(t, wilson, BestRank(3, 1, time.Hour), 1e-9)
+	assert.InDelta(t, wilson, BestRank(3, 1, 48*time.Hour), 1e-9)
+	assert.InDelta(t, wilson+0.1, BestRank(3, 1, -time.Minute), 1e-9, "clock skew counts as brand new")
+
+	// A new unvoted comment outranks an old one with a poor ratio, not a well-liked one
+	assert.Greater(t, BestRank(0, 0, time.Minute), BestRank(1, 3, 48*time.Hour))
+	assert.Less(t, BestRank(0, 0, time.Minute), BestRank(10, 0, 48*time.Hour))
+}
+
+func TestCommentService_GetComments_BestPrefersNewAuthors(t *testing.T) {
+	postURI := "at://did:plc:post123/app.bsky.feed.post/test"
+	authorDID := "did:plc:author123"
+	communityDID := "did:plc:community123"
+	old := time.Now().Add(-48 * time.Hour)
+
+	comment := func(rkey, commenterDID string, up int) *Comment {
+		c := createTestComment("at://"+commenterDID+"/social.coves.community.comment/"+rkey, commenterDID, "", postURI, postURI, 0)
+		c.UpvoteCount, c.DownvoteCount, c.Score = up, 0, up
+		c.CreatedAt = old
+		return c
+	}
+
+	tests := []struct {
+		name    string
+		sort    string
+		listing []*Comment
+		want    []string
+	}{
+		{
+			name: "tied comments by a new author move ahead",
+			sort: "best",
+			listing: []*Comment{
+				comment("a1", "did:plc:alice", 10),
+				comment("a2", "did:plc:alice", 3),
+				comment("a3", "did:plc:alice", 3),
+				comment("b1", "did:plc:bob", 3),
+			},
+			want: []string{"a1", "b1", "a2", "a3"},
+		},
+		{
+			name: "untied comments keep their order",
+			sort: "best",
+			listing: []*Comment{
+				comment("a1", "did:plc:alice", 10),
+				comment("a2", "did:plc:alice", 4),
+				comment("b1", "did:plc:bob", 3),
+			},
+			want: []string{"a1", "a2", "b1"},
+		},
+		{
+			name: "ties between seen authors keep their order",
+			sort: "best",
+			listing: []*Comment{
+				comment("a1", "did:plc:alice", 10),
+				comment("b1", "did:plc:bob", 10),
+				comment("b2", "did:plc:bob", 3),
+				comment("a2", "did:plc:alice", 3),
+			},
+			want: []string{"a1", "b1", "b2", "a2"},
+		},
+		{
+			name: "other sorts aren't reordered",
+			sort: "top",
+			listing: []*Comment{
+				comment("a1", "did:plc:alice", 10),
+				comment("a2", "did:plc:alice", 3),
+				comment("b1", "did:plc:bob", 3),
+			},
+			want: []string{"a1", "a2", "b1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			commentRepo := newMockCommentRepo()
+			postRepo := newMockPostRepo()
+			communityRepo := newMockCommunityRepo()
+			_ = postRepo.Create(context.Background(), createTestPost(postURI, authorDID, communityDID))
+			_, _ = communityRepo.Create(context.Background(), createTestCommunity(communityDID, "c-test.coves.social"))
+
+			commentRepo.listByParentWithHotRankFunc = func(ctx context.Context, parentURI, sort, timeframe string, limit int, cursor *string) ([]*Comment, *string, error) {
+				assert.Equal(t, tt.sort, sort)
+				return tt.listing, nil, nil
+			}
+			service := NewCommentService(commentRepo, newMockUserRepo(), postRepo, communityRepo, nil, nil, nil)
+
+			resp, err := service.GetComments(context.Background(), &GetCommentsRequest{
+				PostURI: postURI,
+				Sort:    tt.sort,
+				Limit:   50,
+			})
+			require.NoError(t, err)
+
+			got := make([]string, 0, len(resp.Comments))
+			for _, thread := range resp.Comments {
+				got = append(got, thread.Comment.URI[len(thread.Comment.URI)-2:])
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package comments
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/core/posts"
+	"context"
+	"log/slog"
+	"time"
+)
+
+// threadCollapse marks comments in a post's thread that clients should render collapsed
+// Everything in a thread belongs to the post's community, so one set of settings applies throughout
+type threadCollapse struct {
+	subscribed   map[string]bool
+	newAccounts  map[string]bool
+	crowdControl string
+	communityDID string
+	threshold    int
+}
+
+// newThreadCollapse loads the collapse settings of the post's community
+// Crowd control needs every author's subscription and account age, so it batch-loads both
+// for the comments about to be returned. A failed lookup turns crowd control off for the request.
+func (s *commentService) newThreadCollapse(ctx context.Context, postView *posts.PostView, views []*CommentView, threads []*ThreadViewComment) *threadCollapse {
+	collapse := &threadCollapse{
+		threshold:    postView.CollapseThreshold,
+		crowdControl: communities.NormalizeCrowdControl(postView.CrowdControl),
+	}
+	if postView.Community != nil {
+		collapse.communityDID = postView.Community.DID
+	}
+	if collapse.crowdControl == communities.CrowdControlOff || collapse.communityDID == "" {
+		collapse.crowdControl = communities.CrowdControlOff
+		return collapse
+	}
+
+	seen := make(map[string]bool)
+	var authorDIDs []string
+	addAuthor := func(view *CommentView) {
+		if view == nil || view.Author == nil || view.IsDeleted || seen[view.Author.DID] {
+			return
+		}
+		seen[view.Author.DID] = true
+		authorDIDs = append(authorDIDs, view.Author.DID)
+	}
+	for _, view := range views {
+		addAuthor(view)
+	}
+	walkThreads(threads, addAuthor)
+	if len(authorDIDs) == 0 {
+		return collapse
+	}
+
+	subscribed, err := s.communityRepo.GetSubscribedUserDIDs(ctx, collapse.communityDID, authorDIDs)
+	if err != nil {
+		slog.Warn("failed to load subscribers for crowd control",
+			"community_did", collapse.communityDID, "error", err)
+		collapse.crowdControl = communities.CrowdControlOff
+		return collapse
+	}
+	usersByDID, err := s.userRepo.GetByDIDs(ctx, authorDIDs)
+	if err != nil {
+		slog.Warn("failed to load accounts for crowd control",
+			"community_did", collapse.communityDID, "error", err)
+		collapse.crowdControl = communities.CrowdControlOff
+		return collapse
+	}
+
+	// Accounts Coves hasn't indexed have no known age and aren't treated as new
+	newAccountSince := time.Now().Add(-communities.CrowdControlNewAccountAge)
+	collapse.subscribed = subscribed
+	collapse.newAccounts = make(map[string]bool)
+	for did, user := range usersByDID {
+		if user.CreatedAt.After(newAccountSince) {
+			collapse.newAccounts[did] = true
+		}
+	}
+	return collapse
+}
+
+// collapseComment marks a comment collapsed under the community's settings
+// Run after score hiding: hidden scores never collapse a comment. The community's own
+// comments are exempt from crowd control. Deleted comment stubs are skipped.
+func (c *threadCollapse) collapseComment(view *CommentView) {
+	if view == nil || view.Stats == nil || view.Author == nil || view.IsDeleted {
+		return
+	}
+	crowdControl := c.crowdControl
+	if view.Author.DID == c.communityDID {
+		crowdControl = communities.CrowdControlOff
+	}
+	view.Collapsed = communities.CommentCollapsed(
+		c.threshold,
+		crowdControl,
+		view.Stats.Score,
+		!view.Stats.ScoreHidden,
+		c.subscribed[view.Author.DID],
+		c.newAccounts[view.Author.DID],
+	)
+}
+
+// collapseThreads applies collapseComment to every comment in the thread trees
+func (c *threadCollapse) collapseThreads(threads []*ThreadViewComment) {
+	walkThreads(threads, c.collapseComment)
+}
+
+// walkThreads calls fn on every comment in the thread trees
+func walkThreads(threads []*ThreadViewComment, fn func(*CommentView)) {
+	for _, thread := range threads {
+		fn(thread.Comment)
+		walkThreads(thread.Replies, fn)
+	}
+}
@@ -10,6 +10,18 @@ const (
 	DeletionReasonModerator = "moderator" // Community moderator removed the comment
 )
 
+// Comment status constants
+const (
+	StatusActive   = "active"   // Indexed normally
+	StatusRejected = "rejected" // Created on a locked post; indexed but hidden and not counted
+	StatusHeld     = "held"     // Held by automod for moderator review; hidden and not counted until approved
+	StatusRemoved  = "removed"  // Removed by automod or on review; hidden and not counted
+)
+
+// DefaultMaxThreadDepth is the default depth past which comments are collapsed into
+// "continue thread" links (comments directly on the post are depth 1)
+const DefaultMaxThreadDepth = 12
+
 // Comment represents a comment in the AppView database
 // Comments are indexed from the firehose after being written to user repositories
 type Comment struct {
@@ -19,8 +31,12 @@ type Comment struct {
 	DeletedAt       *time.Time `json:"deletedAt,omitempty" db:"deleted_at"`
 	DeletionReason  *string    `json:"deletionReason,omitempty" db:"deletion_reason"`
 	DeletedBy       *string    `json:"deletedBy,omitempty" db:"deleted_by"`
+	EditedAt        *time.Time `json:"editedAt,omitempty" db:"edited_at"` // Set when an update changed the record's CID
 	ContentLabels   *string    `json:"labels,omitempty" db:"content_labels"`
 	Embed           *string    `json:"embed,omitempty" db:"embed"`
+	RawRecord       *string    `json:"-" db:"raw_record"` // Full record JSON as received from the firehose
+	DetectedLang    *string    `json:"-" db:"detected_lang"`            // Language detected at index time when the record has no langs
+	LangConfidence  *float64   `json:"-" db:"detected_lang_confidence"` // Detector confidence (0-1) for DetectedLang
 	CommenterHandle string     `json:"commenterHandle,omitempty" db:"-"`
 	CommenterDID    string     `json:"commenterDid" db:"commenter_did"`
 	ParentURI       string     `json:"parentUri" db:"parent_uri"`
@@ -30,13 +46,20 @@ type Comment struct {
 	URI             string     `json:"uri" db:"uri"`
 	RootCID         string     `json:"rootCid" db:"root_cid"`
 	CID             string     `json:"cid" db:"cid"`
+	Rev             string     `json:"-" db:"rev"` // Repo revision of the commit that last wrote the record
 	RKey            string     `json:"rkey" db:"rkey"`
 	Langs           []string   `json:"langs,omitempty" db:"langs"`
+	Depth           *int       `json:"-" db:"depth"` // Nil until the parent is indexed
+	Status          string     `json:"-" db:"status"`
 	ID              int64      `json:"id" db:"id"`
 	UpvoteCount     int        `json:"upvoteCount" db:"upvote_count"`
 	DownvoteCount   int        `json:"downvoteCount" db:"downvote_count"`
 	Score           int        `json:"score" db:"score"`
 	ReplyCount      int        `json:"replyCount" db:"reply_count"`
+	Orphaned        bool       `json:"-" db:"orphaned"` // Root post unknown and not backfilled; hidden from getComments
+	DepthExceeded   bool       `json:"-" db:"depth_exceeded"` // Deeper than the max thread depth
+	CommunityDID     string    `json:"-" db:"-"` // Root post's community, set by ListByCommenterWithCursor
+	ScoreHidingHours int       `json:"-" db:"-"` // Community's score hiding window, set by ListByCommenterWithCursor
 }
 
 // CommentRecord represents the atProto record structure indexed from Jetstream
@@ -51,6 +74,7 @@ type CommentRecord struct {
 	CreatedAt string       `json:"createdAt"`
 	Facets    []interface{} `json:"facets,omitempty"`
 	Langs     []string     `json:"langs,omitempty"`
+	Imported  bool         `json:"imported,omitempty"`
 }
 
 // ReplyRef represents the threading structure from the comment lexicon
@@ -87,4 +111,17 @@ type ListByCommenterRequest struct {
 	CommunityDID *string // Optional: filter to comments in a specific community
 	Limit        int     // Max comments to return (1-100)
 	Cursor       *string // Pagination cursor from previous response
+	ViewerDID    string  // Viewer's DID ("" for anonymous); removed and author_only comments are hidden from others
+}
+
+// SearchRequest defines the parameters for full-text searching a community's comments
+// Used by social.coves.community.comment.search (moderator tooling)
+type SearchRequest struct {
+	AuthorDID      *string // Optional: only comments by this user
+	PostURI        *string // Optional: only comments on this post
+	Cursor         *string // Pagination cursor from previous response
+	CommunityDID   string  // Required: community whose posts the comments belong to
+	Query          string  // Required: web-search style query ("quoted phrases", -excluded)
+	Limit          int     // Max comments to return (1-100)
+	IncludeDeleted bool    // Include soft-deleted comments (matched on their content before deletion)
 }
@@ -1,9 +1,13 @@
 package comments
 
 import (
+	"Coves/internal/core/blobs"
 	"Coves/internal/core/communities"
+	"Coves/internal/core/notifications"
 	"Coves/internal/core/posts"
+	"Coves/internal/core/takedown"
 	"Coves/internal/core/users"
+	"Coves/internal/lexicon/validate"
 	"context"
 	"encoding/json"
 	"errors"
@@ -42,9 +46,13 @@ type PDSClientFactory func(ctx context.Context, session *oauth.ClientSessionData
 // Orchestrates repository calls and builds view models for API responses
 type Service interface {
 	// GetComments retrieves and builds a threaded comment tree for a post
-	// Supports hot, top, and new sorting with configurable depth and pagination
+	// Supports best, hot, top, and new sorting with configurable depth and pagination
 	GetComments(ctx context.Context, req *GetCommentsRequest) (*GetCommentsResponse, error)
 
+	// GetCommentThread retrieves a single comment with its parent chain and reply subtree
+	// Used for comment permalink views
+	GetCommentThread(ctx context.Context, req *GetCommentThreadRequest) (*GetCommentThreadResponse, error)
+
 	// GetActorComments retrieves comments by a user for their profile page
 	// Supports optional community filtering and cursor-based pagination
 	GetActorComments(ctx context.Context, req *GetActorCommentsRequest) (*GetActorCommentsResponse, error)
@@ -57,6 +65,10 @@ type Service interface {
 
 	// DeleteComment soft-deletes a comment
 	DeleteComment(ctx context.Context, session *oauth.ClientSessionData, req DeleteCommentRequest) error
+
+	// SearchComments full-text searches a community's comments
+	// Restricted to the community's owner and moderators, and instance admins
+	SearchComments(ctx context.Context, req *SearchCommentsRequest) (*SearchCommentsResponse, error)
 }
 
 // GetCommentsRequest defines the parameters for fetching comments
@@ -70,6 +82,18 @@ type GetCommentsRequest struct {
 	Limit     int
 }
 
+// GetCommentThreadRequest defines the parameters for fetching a single comment thread
+type GetCommentThreadRequest struct {
+	Cursor       *string // Paginates the focus comment's direct replies
+	ViewerDID    *string
+	CommentURI   string
+	Sort         string
+	Timeframe    string
+	ParentHeight int // Number of ancestors to include above the focus comment
+	Depth        int
+	Limit        int
+}
+
 // commentService implements the Service interface
 // Coordinates between repository layer and view model construction
 type commentService struct {
@@ -81,6 +105,19 @@ type commentService struct {
 	oauthStore       oauth.ClientAuthStore     // OAuth session store
 	logger           *slog.Logger              // Structured logger
 	pdsClientFactory PDSClientFactory          // Optional, for testing. If nil, uses OAuth.
+	instanceAdmins   map[string]bool           // May moderate (e.g. search) any community
+	threadMetaCache  *threadMetaCache          // Per-post comment and participant counts
+	takedowns        takedown.Checker          // Optional: admin takedowns of posts and comments
+	threadMutes      notifications.ThreadMuteChecker // Optional: viewers' thread mutes, for thread meta
+	drafts           DraftDeleter              // Optional: discards a comment's draft once it's written
+}
+
+// SetInstanceAdmins configures the instance admins, who may use moderator tooling in any community
+func (s *commentService) SetInstanceAdmins(adminDIDs []string) {
+	s.instanceAdmins = make(map[string]bool, len(adminDIDs))
+	for _, did := range adminDIDs {
+		s.instanceAdmins[did] = true
+	}
 }
 
 // NewCommentService creates a new comment service instance
@@ -98,13 +135,14 @@ func NewCommentService(
 		logger = slog.Default()
 	}
 	return &commentService{
-		commentRepo:   commentRepo,
-		userRepo:      userRepo,
-		postRepo:      postRepo,
-		communityRepo: communityRepo,
-		oauthClient:   oauthClient,
-		oauthStore:    oauthStore,
-		logger:        logger,
+		commentRepo:     commentRepo,
+		userRepo:        userRepo,
+		postRepo:        postRepo,
+		communityRepo:   communityRepo,
+		oauthClient:     oauthClient,
+		oauthStore:      oauthStore,
+		logger:          logger,
+		threadMetaCache: newThreadMetaCache(),
 	}
 }
 
@@ -128,7 +166,22 @@ func NewCommentServiceWithPDSFactory(
 		communityRepo:    communityRepo,
 		logger:           logger,
 		pdsClientFactory: factory,
+		threadMetaCache:  newThreadMetaCache(),
+	}
+}
+
+// checkCommunityNotDeleted returns communities.ErrCommunityDeleted when the post's community
+// has been deleted, so direct links to its posts stop resolving during the grace period
+// Lookup failures are left to buildPostView, which falls back to the DID.
+func (s *commentService) checkCommunityNotDeleted(ctx context.Context, post *posts.Post) error {
+	if s.communityRepo == nil {
+		return nil
+	}
+	community, err := s.communityRepo.GetByDID(ctx, post.CommunityDID)
+	if err == nil && community.DeletedAt != nil {
+		return communities.ErrCommunityDeleted
 	}
+	return nil
 }
 
 // GetComments retrieves comments for a post with threading and pagination
@@ -136,12 +189,12 @@ func NewCommentServiceWithPDSFactory(
 // 1. Validate input parameters and apply defaults
 // 2. Fetch top-level comments with specified sorting
 // 3. Recursively load nested replies up to depth limit
-// 4. Build view models with author info and stats
+// 4. Build view models with author info and stats (deleted comments become tombstones or are omitted)
 // 5. Return response with pagination cursor
 func (s *commentService) GetComments(ctx context.Context, req *GetCommentsRequest) (*GetCommentsResponse, error) {
 	// 1. Validate inputs and apply defaults/bounds FIRST (before expensive operations)
 	if err := validateGetCommentsRequest(req); err != nil {
-		return nil, fmt.Errorf("invalid request: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
 	}
 
 	// Add timeout to prevent runaway queries with deep nesting
@@ -149,6 +202,9 @@ func (s *commentService) GetComments(ctx context.Context, req *GetCommentsReques
 	defer cancel()
 
 	// 2. Fetch post for context
+	if err := s.checkNotTakenDown(ctx, req.PostURI); err != nil {
+		return nil, err
+	}
 	post, err := s.postRepo.GetByURI(ctx, req.PostURI)
 	if err != nil {
 		// Translate post not-found errors to comment-layer errors for proper HTTP status
@@ -157,19 +213,24 @@ func (s *commentService) GetComments(ctx context.Context, req *GetCommentsReques
 		}
 		return nil, fmt.Errorf("failed to fetch post: %w", err)
 	}
+	if err := s.checkCommunityNotDeleted(ctx, post); err != nil {
+		return nil, err
+	}
 
 	// Build post view for response (hydrates author handle and community name)
 	postView := s.buildPostView(ctx, post, req.ViewerDID)
 
 	// 3. Fetch top-level comments with pagination
 	// Uses repository's hot rank sorting and cursor-based pagination
-	topComments, nextCursor, err := s.commentRepo.ListByParentWithHotRank(
+	// Deleted comments are included so their live replies render under a tombstone
+	topComments, nextCursor, err := s.commentRepo.ListByParentWithHotRankWithDeleted(
 		ctx,
 		req.PostURI,
 		req.Sort,
 		req.Timeframe,
 		req.Limit,
 		req.Cursor,
+		viewerDIDOrEmpty(req.ViewerDID),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch top-level comments: %w", err)
@@ -177,16 +238,158 @@ func (s *commentService) GetComments(ctx context.Context, req *GetCommentsReques
 
 	// 4. Build threaded view with nested replies up to depth limit
 	// This iteratively loads child comments and builds the tree structure
-	threadViews := s.buildThreadViews(ctx, topComments, req.Depth, req.Sort, req.ViewerDID)
+	threadViews := s.buildThreadViews(ctx, topComments, req.Depth, req.Sort, req.ViewerDID, true)
+
+	// Null vote counts inside the community's score hiding window
+	hiding := s.threadScoreHiding(ctx, postView, req.ViewerDID)
+	hiding.hidePost(postView)
+	hiding.hideThreads(threadViews)
 
-	// 5. Return response with comments, post reference, and cursor
+	// Mark low-scoring comments and crowd-controlled authors as collapsed
+	collapse := s.newThreadCollapse(ctx, postView, nil, threadViews)
+	collapse.collapseThreads(threadViews)
+
+	badges := s.newThreadBadges(ctx, postView)
+	badges.badgePost(postView)
+	badges.badgeThreads(threadViews)
+
+	// 5. Return response with comments, post reference, thread summary, and cursor
 	return &GetCommentsResponse{
 		Comments: threadViews,
 		Post:     postView,
+		Meta:     s.threadMeta(ctx, post.URI, post.Locked, req.ViewerDID),
 		Cursor:   nextCursor,
 	}, nil
 }
 
+// GetCommentThread retrieves a comment with its ancestors and replies for permalink views
+// Algorithm:
+// 1. Validate input parameters and apply defaults
+// 2. Fetch the focus comment and its root post
+// 3. Fetch up to ParentHeight ancestors in one query (deleted ones become stubs)
+// 4. Fetch the focus comment's replies with sorting, pagination, and nesting like GetComments
+func (s *commentService) GetCommentThread(ctx context.Context, req *GetCommentThreadRequest) (*GetCommentThreadResponse, error) {
+	// 1. Validate inputs and apply defaults/bounds FIRST (before expensive operations)
+	if err := validateGetCommentThreadRequest(req); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
+	}
+
+	// Add timeout to prevent runaway queries with deep nesting
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// 2. Fetch the focus comment (deleted comments are still addressable by permalink, taken-down ones aren't)
+	if err := s.checkNotTakenDown(ctx, req.CommentURI); err != nil {
+		return nil, err
+	}
+	focus, err := s.commentRepo.GetByURIWithDeleted(ctx, req.CommentURI)
+	if err != nil {
+		if IsNotFound(err) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch comment: %w", err)
+	}
+
+	if err := s.checkNotTakenDown(ctx, focus.RootURI); err != nil {
+		return nil, err
+	}
+	post, err := s.postRepo.GetByURI(ctx, focus.RootURI)
+	if err != nil {
+		if posts.IsNotFound(err) {
+			return nil, ErrRootNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch post: %w", err)
+	}
+	if err := s.checkCommunityNotDeleted(ctx, post); err != nil {
+		return nil, err
+	}
+	postView := s.buildPostView(ctx, post, req.ViewerDID)
+
+	// 3. Fetch the parent chain
+	ancestors, err := s.commentRepo.GetAncestorsWithDeleted(ctx, req.CommentURI, req.ParentHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch comment ancestors: %w", err)
+	}
+
+	// The chain continues above the topmost ancestor unless it (or the focus) is top-level
+	top := focus
+	if len(ancestors) > 0 {
+		top = ancestors[0]
+	}
+	hasMoreAncestors := top.ParentURI != top.RootURI
+
+	chain := make([]*Comment, 0, len(ancestors)+1)
+	chain = append(chain, ancestors...)
+	chain = append(chain, focus)
+	voteStates, usersByDID := s.loadCommentViewData(ctx, chain, req.ViewerDID)
+	chainViews := make([]*CommentView, 0, len(chain))
+	for _, comment := range chain {
+		if comment.DeletedAt != nil {
+			chainViews = append(chainViews, s.buildDeletedCommentView(comment))
+		} else {
+			chainViews = append(chainViews, s.buildCommentView(comment, req.ViewerDID, voteStates, usersByDID))
+		}
+	}
+
+	// 4. Fetch direct replies with pagination, then nested replies up to depth limit
+	replies, nextCursor, err := s.commentRepo.ListByParentWithHotRankWithDeleted(
+		ctx,
+		req.CommentURI,
+		req.Sort,
+		req.Timeframe,
+		req.Limit,
+		req.Cursor,
+		viewerDIDOrEmpty(req.ViewerDID),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch replies: %w", err)
+	}
+
+	replyViews := s.buildThreadViews(ctx, replies, req.Depth, req.Sort, req.ViewerDID, false)
+
+	// Null vote counts inside the community's score hiding window
+	hiding := s.threadScoreHiding(ctx, postView, req.ViewerDID)
+	hiding.hidePost(postView)
+	for _, view := range chainViews {
+		hiding.hideComment(view)
+	}
+	hiding.hideThreads(replyViews)
+
+	collapse := s.newThreadCollapse(ctx, postView, chainViews, replyViews)
+	for _, view := range chainViews {
+		collapse.collapseComment(view)
+	}
+	collapse.collapseThreads(replyViews)
+
+	badges := s.newThreadBadges(ctx, postView)
+	badges.badgePost(postView)
+	for _, view := range chainViews {
+		badges.badgeComment(view)
+	}
+	badges.badgeThreads(replyViews)
+
+	return &GetCommentThreadResponse{
+		Post:   postView,
+		Meta:   s.threadMeta(ctx, post.URI, post.Locked, req.ViewerDID),
+		Cursor: nextCursor,
+		Thread: &CommentThreadView{
+			Ancestors:        chainViews[:len(ancestors)],
+			Focus:            chainViews[len(ancestors)],
+			Replies:          replyViews,
+			HasMoreAncestors: hasMoreAncestors,
+		},
+	}, nil
+}
+
+// viewerDIDOrEmpty returns the viewer's DID, or "" for anonymous viewers
+// Repository reads take the viewer by value so author_only comments can be shown to their author
+func viewerDIDOrEmpty(viewerDID *string) string {
+	if viewerDID == nil {
+		return ""
+	}
+	return *viewerDID
+}
+
 // buildThreadViews constructs threaded comment views with nested replies using batch loading
 // Uses batch queries to prevent N+1 query problem when loading nested replies
 // Loads replies level-by-level up to the specified depth limit
@@ -196,6 +399,7 @@ func (s *commentService) buildThreadViews(
 	remainingDepth int,
 	sort string,
 	viewerDID *string,
+	collapseDeep bool,
 ) []*ThreadViewComment {
 	// Always return an empty slice, never nil (important for JSON serialization)
 	result := make([]*ThreadViewComment, 0, len(comments))
@@ -204,50 +408,13 @@ func (s *commentService) buildThreadViews(
 		return result
 	}
 
-	// Batch fetch vote states for all comments at this level (Phase 2B)
-	var voteStates map[string]interface{}
-	if viewerDID != nil {
-		commentURIs := make([]string, 0, len(comments))
-		for _, comment := range comments {
-			if comment.DeletedAt == nil {
-				commentURIs = append(commentURIs, comment.URI)
-			}
-		}
-
-		if len(commentURIs) > 0 {
-			var err error
-			voteStates, err = s.commentRepo.GetVoteStateForComments(ctx, *viewerDID, commentURIs)
-			if err != nil {
-				// Log error but don't fail the request - vote state is optional
-				slog.Warn("failed to fetch vote states for comments", "error", err)
-			}
-		}
+	// The best sort breaks ties in favour of authors not already shown among these siblings
+	if sort == "best" {
+		comments = preferNewAuthors(comments, time.Now())
 	}
 
-	// Batch fetch user data for all comment authors (Phase 2C)
-	// Collect unique author DIDs to prevent duplicate queries
-	authorDIDs := make([]string, 0, len(comments))
-	seenDIDs := make(map[string]bool)
-	for _, comment := range comments {
-		if comment.DeletedAt == nil && !seenDIDs[comment.CommenterDID] {
-			authorDIDs = append(authorDIDs, comment.CommenterDID)
-			seenDIDs[comment.CommenterDID] = true
-		}
-	}
-
-	// Fetch all users in one query to avoid N+1 problem
-	var usersByDID map[string]*users.User
-	if len(authorDIDs) > 0 {
-		var err error
-		usersByDID, err = s.userRepo.GetByDIDs(ctx, authorDIDs)
-		if err != nil {
-			// Log error but don't fail the request - user data is optional
-			slog.Warn("failed to batch fetch users for comment authors", "error", err)
-			usersByDID = make(map[string]*users.User)
-		}
-	} else {
-		usersByDID = make(map[string]*users.User)
-	}
+	// Batch fetch vote states and authors for all comments at this level
+	voteStates, usersByDID := s.loadCommentViewData(ctx, comments, viewerDID)
 
 	// Build thread views for current level
 	threadViews := make([]*ThreadViewComment, 0, len(comments))
@@ -259,8 +426,8 @@ func (s *commentService) buildThreadViews(
 
 		// Build appropriate view based on deletion status
 		if comment.DeletedAt != nil {
-			// Deleted comment - build placeholder view to preserve thread structure
-			commentView = s.buildDeletedCommentView(comment)
+			// Deleted comment - build tombstone to preserve thread structure (pruned below if it has no live replies)
+			commentView = s.buildTombstoneView(comment)
 		} else {
 			// Active comment - build full view with author info and stats
 			commentView = s.buildCommentView(comment, viewerDID, voteStates, usersByDID)
@@ -276,19 +443,21 @@ func (s *commentService) buildThreadViews(
 		commentsByURI[comment.URI] = threadView
 
 		// Collect parent URIs that have replies and depth remaining
-		// Include deleted comments so their children are still loaded
-		if remainingDepth > 0 && comment.ReplyCount > 0 {
+		// Deleted comments are always collected: reply counts only count live replies, and a
+		// deleted reply may itself have live replies
+		if remainingDepth > 0 && (comment.ReplyCount > 0 || comment.DeletedAt != nil) {
 			parentsWithReplies = append(parentsWithReplies, comment.URI)
 		}
 	}
 
 	// Batch load all replies for this level in a single query
 	if len(parentsWithReplies) > 0 {
-		repliesByParent, err := s.commentRepo.ListByParentsBatch(
+		repliesByParent, err := s.commentRepo.ListByParentsBatchWithDeleted(
 			ctx,
 			parentsWithReplies,
 			sort,
 			DefaultRepliesPerParent,
+			viewerDIDOrEmpty(viewerDID),
 		)
 
 		// Process replies if batch query succeeded
@@ -297,20 +466,31 @@ func (s *commentService) buildThreadViews(
 			for parentURI, replies := range repliesByParent {
 				threadView := commentsByURI[parentURI]
 				if threadView != nil && len(replies) > 0 {
+					loaded := len(replies)
+
+					// Replies past the max thread depth are replaced by a "continue thread"
+					// link to the parent's permalink (getCommentThread shows them)
+					if collapseDeep {
+						replies, threadView.ContinueThread = withinThreadDepth(replies)
+					}
+
 					// Recursively build views for child comments
-					threadView.Replies = s.buildThreadViews(
-						ctx,
-						replies,
-						remainingDepth-1,
-						sort,
-						viewerDID,
-					)
+					if len(replies) > 0 {
+						threadView.Replies = s.buildThreadViews(
+							ctx,
+							replies,
+							remainingDepth-1,
+							sort,
+							viewerDID,
+							collapseDeep,
+						)
+					}
 
 					// Update HasMore based on actual reply count vs loaded count
 					// Get the original comment to check reply count
 					for _, comment := range comments {
 						if comment.URI == parentURI {
-							threadView.HasMore = comment.ReplyCount > len(replies)
+							threadView.HasMore = comment.ReplyCount > loaded
 							break
 						}
 					}
@@ -319,7 +499,75 @@ func (s *commentService) buildThreadViews(
 		}
 	}
 
-	return threadViews
+	return pruneTombstones(threadViews)
+}
+
+// withinThreadDepth drops replies past the max thread depth
+// Reports whether any were dropped
+func withinThreadDepth(replies []*Comment) ([]*Comment, bool) {
+	shown := make([]*Comment, 0, len(replies))
+	for _, reply := range replies {
+		if !reply.DepthExceeded {
+			shown = append(shown, reply)
+		}
+	}
+	return shown, len(shown) < len(replies)
+}
+
+// loadCommentViewData batch loads the viewer's vote states and author data for comments
+// Deleted comments are skipped since their stubs show neither
+// Failures are logged and yield empty data - both are optional for rendering
+func (s *commentService) loadCommentViewData(
+	ctx context.Context,
+	comments []*Comment,
+	viewerDID *string,
+) (map[string]interface{}, map[string]*users.User) {
+	// Batch fetch vote states (Phase 2B)
+	var voteStates map[string]interface{}
+	if viewerDID != nil {
+		commentURIs := make([]string, 0, len(comments))
+		for _, comment := range comments {
+			if comment.DeletedAt == nil {
+				commentURIs = append(commentURIs, comment.URI)
+			}
+		}
+
+		if len(commentURIs) > 0 {
+			var err error
+			voteStates, err = s.commentRepo.GetVoteStateForComments(ctx, *viewerDID, commentURIs)
+			if err != nil {
+				// Log error but don't fail the request - vote state is optional
+				slog.Warn("failed to fetch vote states for comments", "error", err)
+			}
+		}
+	}
+
+	// Batch fetch user data for all comment authors (Phase 2C)
+	// Collect unique author DIDs to prevent duplicate queries
+	authorDIDs := make([]string, 0, len(comments))
+	seenDIDs := make(map[string]bool)
+	for _, comment := range comments {
+		if comment.DeletedAt == nil && !seenDIDs[comment.CommenterDID] {
+			authorDIDs = append(authorDIDs, comment.CommenterDID)
+			seenDIDs[comment.CommenterDID] = true
+		}
+	}
+
+	// Fetch all users in one query to avoid N+1 problem
+	var usersByDID map[string]*users.User
+	if len(authorDIDs) > 0 {
+		var err error
+		usersByDID, err = s.userRepo.GetByDIDs(ctx, authorDIDs)
+		if err != nil {
+			// Log error but don't fail the request - user data is optional
+			slog.Warn("failed to batch fetch users for comment authors", "error", err)
+			usersByDID = make(map[string]*users.User)
+		}
+	} else {
+		usersByDID = make(map[string]*users.User)
+	}
+
+	return voteStates, usersByDID
 }
 
 // buildCommentView converts a Comment entity to a CommentView with full metadata
@@ -336,17 +584,21 @@ func (s *commentService) buildCommentView(
 	// CommenterHandle is hydrated by ListByParentWithHotRank via JOIN (fallback)
 	// Prefer handle from usersByDID map for consistency
 	authorHandle := comment.CommenterHandle
+	authorConfusable := false
+	var authorPDSURL, authorAvatarCID string
 	if user, found := usersByDID[comment.CommenterDID]; found {
 		authorHandle = user.Handle
+		authorConfusable = user.ConfusableWith != ""
+		authorPDSURL, authorAvatarCID = user.PDSURL, user.AvatarCID
 	}
 
 	authorView := &posts.AuthorView{
-		DID:    comment.CommenterDID,
-		Handle: authorHandle,
-		// DisplayName, Avatar, Reputation will be populated when user profile schema is extended
-		// Currently User model only has DID, Handle, PDSURL fields
+		DID:            comment.CommenterDID,
+		Handle:         authorHandle,
+		ConfusableFlag: authorConfusable,
+		// DisplayName, Reputation will be populated when user profile schema is extended
 		DisplayName: nil,
-		Avatar:      nil,
+		Avatar:      authorAvatar(authorPDSURL, comment.CommenterDID, authorAvatarCID),
 		Reputation:  nil,
 	}
 
@@ -419,19 +671,37 @@ func (s *commentService) buildCommentView(
 		}
 	}
 
+	// Readers see that a comment changed; what it said before is for moderators only
+	var lastEditedAt *string
+	if comment.EditedAt != nil {
+		editedAtStr := comment.EditedAt.Format(time.RFC3339)
+		lastEditedAt = &editedAtStr
+	}
+
 	return &CommentView{
-		URI:       comment.URI,
-		CID:       comment.CID,
-		Author:    authorView,
-		Record:    commentRecord,
-		Post:      postRef,
-		Parent:    parentRef,
-		Embed:     embed,
-		CreatedAt: comment.CreatedAt.Format(time.RFC3339),
-		IndexedAt: comment.IndexedAt.Format(time.RFC3339),
-		Stats:     stats,
-		Viewer:    viewer,
+		URI:          comment.URI,
+		CID:          comment.CID,
+		Author:       authorView,
+		Record:       commentRecord,
+		Post:         postRef,
+		Parent:       parentRef,
+		Embed:        embed,
+		CreatedAt:    comment.CreatedAt.Format(time.RFC3339),
+		IndexedAt:    comment.IndexedAt.Format(time.RFC3339),
+		Stats:        stats,
+		Viewer:       viewer,
+		Edited:       lastEditedAt != nil,
+		LastEditedAt: lastEditedAt,
+	}
+}
+
+// authorAvatar returns an author's avatar URL (avatar_small preset), falling back to their
+// identicon; nil when there's neither
+func authorAvatar(pdsURL, did, avatarCID string) *string {
+	if avatarURL := blobs.HydrateAvatarURL(communities.GetImageProxyConfig(), pdsURL, did, avatarCID, "avatar_small"); avatarURL != "" {
+		return &avatarURL
 	}
+	return nil
 }
 
 // buildDeletedCommentView creates a placeholder view for a deleted comment
@@ -440,11 +710,12 @@ func (s *commentService) buildCommentView(
 func (s *commentService) buildDeletedCommentView(comment *Comment) *CommentView {
 	// Build minimal author view - just DID for attribution
 	// Frontend will display "[deleted]" or "[deleted by @user]" based on deletion_reason
+	// The avatar is the DID's identicon, so the placeholder doesn't show the author's picture
 	authorView := &posts.AuthorView{
 		DID:         comment.CommenterDID,
 		Handle:      "", // Empty - frontend handles display
 		DisplayName: nil,
-		Avatar:      nil,
+		Avatar:      authorAvatar("", comment.CommenterDID, ""),
 		Reputation:  nil,
 	}
 
@@ -593,6 +864,16 @@ func (s *commentService) CreateComment(ctx context.Context, session *oauth.Clien
 		return nil, err
 	}
 
+	// Locked threads accept no new comments
+	// A root post that isn't indexed yet can't be locked, so it's left to the PDS
+	post, err := s.postRepo.GetByURI(ctx, req.Reply.Root.URI)
+	if err != nil && !posts.IsNotFound(err) {
+		return nil, fmt.Errorf("failed to check thread lock: %w", err)
+	}
+	if post != nil && post.Locked {
+		return nil, ErrThreadLocked
+	}
+
 	// Create PDS client for this session
 	pdsClient, err := s.getPDSClient(ctx, session)
 	if err != nil {
@@ -617,6 +898,11 @@ func (s *commentService) CreateComment(ctx context.Context, session *oauth.Clien
 		CreatedAt: time.Now().UTC().Format(time.RFC3339),
 	}
 
+	// Validate the record with the consumer's rules so it isn't written only to be refused indexing
+	if err := validate.Value(commentCollection, record); err != nil {
+		return nil, err
+	}
+
 	// Create the comment record on the user's PDS
 	uri, cid, err := pdsClient.CreateRecord(ctx, commentCollection, tid.String(), record)
 	if err != nil {
@@ -638,6 +924,8 @@ func (s *commentService) CreateComment(ctx context.Context, session *oauth.Clien
 		"root", req.Reply.Root.URI,
 		"parent", req.Reply.Parent.URI)
 
+	s.discardDraft(ctx, session.AccountDID.String(), req.Reply.Parent.URI)
+
 	return &CreateCommentResponse{
 		URI: uri,
 		CID: cid,
@@ -739,6 +1027,11 @@ func (s *commentService) UpdateComment(ctx context.Context, session *oauth.Clien
 		CreatedAt: createdAt, // Preserve original timestamp
 	}
 
+	// Validate the record with the consumer's rules so it isn't written only to be refused indexing
+	if err := validate.Value(commentCollection, updatedRecord); err != nil {
+		return nil, err
+	}
+
 	// Update the record on PDS with optimistic locking via swapRecord CID
 	uri, cid, err := pdsClient.PutRecord(ctx, commentCollection, rkey, updatedRecord, existingRecord.CID)
 	if err != nil {
@@ -867,20 +1160,24 @@ func (s *commentService) buildPostView(ctx context.Context, post *posts.Post, vi
 	// Build author view - fetch user to get handle (required by lexicon)
 	// The lexicon marks authorView.handle with format:"handle", so DIDs are invalid
 	authorHandle := post.AuthorDID // Fallback if user not found
+	authorConfusable := false
+	var authorPDSURL, authorAvatarCID string
 	if user, err := s.userRepo.GetByDID(ctx, post.AuthorDID); err == nil {
 		authorHandle = user.Handle
+		authorConfusable = user.ConfusableWith != ""
+		authorPDSURL, authorAvatarCID = user.PDSURL, user.AvatarCID
 	} else {
 		// Log warning but don't fail the entire request
 		slog.Warn("failed to fetch user for post author", "author_did", post.AuthorDID, "error", err)
 	}
 
 	authorView := &posts.AuthorView{
-		DID:    post.AuthorDID,
-		Handle: authorHandle,
-		// DisplayName, Avatar, Reputation will be populated when user profile schema is extended
-		// Currently User model only has DID, Handle, PDSURL fields
+		DID:            post.AuthorDID,
+		Handle:         authorHandle,
+		ConfusableFlag: authorConfusable,
+		// DisplayName, Reputation will be populated when user profile schema is extended
 		DisplayName: nil,
-		Avatar:      nil,
+		Avatar:      authorAvatar(authorPDSURL, post.AuthorDID, authorAvatarCID),
 		Reputation:  nil,
 	}
 
@@ -899,6 +1196,8 @@ func (s *commentService) buildPostView(ctx context.Context, post *posts.Post, vi
 			DID:    post.CommunityDID,
 			Handle: post.CommunityDID, // Fallback: use DID as handle
 			Name:   post.CommunityDID, // Fallback: use DID as name
+
+			CollapseThreshold: communities.DefaultCollapseThreshold,
 		}
 	}
 
@@ -935,6 +1234,11 @@ func (s *commentService) buildPostView(ctx context.Context, post *posts.Post, vi
 			avatarURL = &avatarURLString
 		}
 	}
+	if community.AvatarCID == "" {
+		if identicon := blobs.HydrateAvatarURL(communities.GetImageProxyConfig(), community.PDSURL, community.DID, "", "avatar_small"); identicon != "" {
+			avatarURL = &identicon
+		}
+	}
 
 	communityRef := &posts.CommunityRef{
 		DID:    post.CommunityDID,
@@ -945,10 +1249,12 @@ func (s *commentService) buildPostView(ctx context.Context, post *posts.Post, vi
 
 	// Build aggregated statistics
 	stats := &posts.PostStats{
-		Upvotes:      post.UpvoteCount,
-		Downvotes:    post.DownvoteCount,
-		Score:        post.Score,
-		CommentCount: post.CommentCount,
+		Upvotes:              post.UpvoteCount,
+		Downvotes:            post.DownvoteCount,
+		Score:                post.Score,
+		CommentCount:         post.CommentCount,
+		TopLevelCommentCount: post.TopLevelCommentCount,
+		LastActivityAt:       post.LastActivityAt,
 	}
 
 	// Build viewer state if authenticated
@@ -978,6 +1284,11 @@ func (s *commentService) buildPostView(ctx context.Context, post *posts.Post, vi
 		EditedAt:  post.EditedAt,
 		Stats:     stats,
 		Viewer:    viewer,
+		Labels:    posts.NewLabelsView(posts.EffectiveLabels(community.ContentWarnings, post.Labels, post.LabelOverrides)),
+
+		ScoreHidingHours:  community.ScoreHidingHours,
+		CollapseThreshold: community.CollapseThreshold,
+		CrowdControl:      community.CrowdControl,
 	}
 }
 
@@ -992,6 +1303,7 @@ func (s *commentService) buildPostRecord(post *posts.Post) *posts.PostRecord {
 		CreatedAt: post.CreatedAt.Format(time.RFC3339),
 		Title:     post.Title,
 		Content:   post.Content,
+		Tags:      post.Tags,
 	}
 
 	// TODO (Phase 2C): Parse JSON fields from database for complete record:
@@ -1014,7 +1326,7 @@ func (s *commentService) buildPostRecord(post *posts.Post) *posts.PostRecord {
 func (s *commentService) GetActorComments(ctx context.Context, req *GetActorCommentsRequest) (*GetActorCommentsResponse, error) {
 	// 1. Validate and normalize request
 	if err := validateGetActorCommentsRequest(req); err != nil {
-		return nil, fmt.Errorf("invalid request: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrInvalidRequest, err)
 	}
 
 	// Add timeout to prevent runaway queries
@@ -1052,6 +1364,9 @@ func (s *commentService) GetActorComments(ctx context.Context, req *GetActorComm
 		Limit:        req.Limit,
 		Cursor:       req.Cursor,
 	}
+	if req.ViewerDID != nil {
+		repoReq.ViewerDID = *req.ViewerDID
+	}
 
 	dbComments, nextCursor, err := s.commentRepo.ListByCommenterWithCursor(ctx, repoReq)
 	if err != nil {
@@ -1095,6 +1410,7 @@ func (s *commentService) GetActorComments(ctx context.Context, req *GetActorComm
 		commentView := s.buildCommentView(comment, req.ViewerDID, voteStates, usersByDID)
 		commentViews = append(commentViews, commentView)
 	}
+	s.hideActorCommentScores(ctx, dbComments, commentViews, req.ActorDID, req.ViewerDID)
 
 	// 5. Return response with comments and cursor
 	return &GetActorCommentsResponse{
@@ -1103,6 +1419,118 @@ func (s *commentService) GetActorComments(ctx context.Context, req *GetActorComm
 	}, nil
 }
 
+// SearchComments full-text searches a community's comments for moderators
+// Algorithm:
+// 1. Validate and normalize request parameters
+// 2. Resolve the community and check the viewer moderates it
+// 3. Search comments scoped to the community's posts, most relevant first
+// 4. Build CommentViews (deleted comments as stubs) with links to their posts
+func (s *commentService) SearchComments(ctx context.Context, req *SearchCommentsRequest) (*SearchCommentsResponse, error) {
+	// 1. Validate and normalize request
+	if err := validateSearchCommentsRequest(req); err != nil {
+		return nil, err
+	}
+
+	// Add timeout to prevent runaway queries
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// 2. Resolve community and authorize
+	var community *communities.Community
+	var err error
+	if strings.HasPrefix(req.Community, "did:") {
+		community, err = s.communityRepo.GetByDID(ctx, req.Community)
+	} else {
+		community, err = s.communityRepo.GetByHandle(ctx, req.Community)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.requireModerator(ctx, req.ViewerDID, community); err != nil {
+		return nil, err
+	}
+
+	// 3. Search comments
+	dbComments, nextCursor, err := s.commentRepo.Search(ctx, SearchRequest{
+		CommunityDID:   community.DID,
+		Query:          req.Query,
+		AuthorDID:      req.Author,
+		PostURI:        req.PostURI,
+		IncludeDeleted: req.IncludeDeleted,
+		Limit:          req.Limit,
+		Cursor:         req.Cursor,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search comments: %w", err)
+	}
+
+	// 4. Build comment views
+	viewerDID := req.ViewerDID
+	voteStates, usersByDID := s.loadCommentViewData(ctx, dbComments, &viewerDID)
+	commentViews := make([]*CommentView, 0, len(dbComments))
+	for _, comment := range dbComments {
+		if comment.DeletedAt != nil {
+			commentViews = append(commentViews, s.buildDeletedCommentView(comment))
+			continue
+		}
+		commentViews = append(commentViews, s.buildCommentView(comment, &viewerDID, voteStates, usersByDID))
+	}
+
+	return &SearchCommentsResponse{
+		Comments: commentViews,
+		Cursor:   nextCursor,
+	}, nil
+}
+
+// requireModerator returns ErrNotAuthorized unless userDID owns or moderates the community,
+// or is an instance admin
+func (s *commentService) requireModerator(ctx context.Context, userDID string, community *communities.Community) error {
+	if s.instanceAdmins[userDID] || community.CreatedByDID == userDID {
+		return nil
+	}
+
+	membership, err := s.communityRepo.GetMembership(ctx, userDID, community.DID)
+	if err != nil {
+		if errors.Is(err, communities.ErrMembershipNotFound) {
+			return ErrNotAuthorized
+		}
+		return fmt.Errorf("failed to check moderator status: %w", err)
+	}
+	if !membership.IsModerator {
+		return ErrNotAuthorized
+	}
+	return nil
+}
+
+// validateSearchCommentsRequest validates and normalizes comment search parameters
+func validateSearchCommentsRequest(req *SearchCommentsRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.ViewerDID == "" {
+		return ErrNotAuthorized
+	}
+	if req.Community == "" {
+		return fmt.Errorf("%w: community is required", ErrInvalidSearch)
+	}
+
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		return fmt.Errorf("%w: query is required", ErrInvalidSearch)
+	}
+
+	// Apply limit defaults and bounds (1-100, default 25)
+	if req.Limit <= 0 {
+		req.Limit = 25
+	}
+	if req.Limit > 100 {
+		req.Limit = 100
+	}
+
+	return nil
+}
+
 // validateGetActorCommentsRequest validates and normalizes request parameters
 // Applies default values and enforces bounds per API specification
 func validateGetActorCommentsRequest(req *GetActorCommentsRequest) error {
@@ -1165,16 +1593,17 @@ func validateGetCommentsRequest(req *GetCommentsRequest) error {
 
 	// Apply sort default and validate
 	if req.Sort == "" {
-		req.Sort = "hot"
+		req.Sort = "best"
 	}
 
 	validSorts := map[string]bool{
-		"hot": true,
-		"top": true,
-		"new": true,
+		"best": true,
+		"hot":  true,
+		"top":  true,
+		"new":  true,
 	}
 	if !validSorts[req.Sort] {
-		return fmt.Errorf("invalid sort: must be one of [hot, top, new], got '%s'", req.Sort)
+		return fmt.Errorf("invalid sort: must be one of [best, hot, top, new], got '%s'", req.Sort)
 	}
 
 	// Validate timeframe (only applies to "top" sort)
@@ -1194,3 +1623,44 @@ func validateGetCommentsRequest(req *GetCommentsRequest) error {
 
 	return nil
 }
+
+// validateGetCommentThreadRequest validates and normalizes comment thread parameters
+// Sort, timeframe, depth, and limit follow getComments; parentHeight is 0-100, default 10
+func validateGetCommentThreadRequest(req *GetCommentThreadRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+
+	if req.CommentURI == "" {
+		return errors.New("comment URI is required")
+	}
+
+	if !strings.HasPrefix(req.CommentURI, "at://") {
+		return errors.New("invalid AT-URI format: must start with 'at://'")
+	}
+
+	// Apply parent height defaults and bounds (0-100, default 10)
+	if req.ParentHeight < 0 {
+		req.ParentHeight = 10
+	}
+	if req.ParentHeight > 100 {
+		req.ParentHeight = 100
+	}
+
+	// Reuse getComments validation for the reply subtree parameters
+	repliesReq := &GetCommentsRequest{
+		PostURI:   req.CommentURI,
+		Sort:      req.Sort,
+		Timeframe: req.Timeframe,
+		Depth:     req.Depth,
+		Limit:     req.Limit,
+	}
+	if err := validateGetCommentsRequest(repliesReq); err != nil {
+		return err
+	}
+	req.Sort = repliesReq.Sort
+	req.Depth = repliesReq.Depth
+	req.Limit = repliesReq.Limit
+
+	return nil
+}
@@ -1,16 +1,22 @@
 package comments
 
 import (
+	"Coves/internal/core/blobs"
 	"Coves/internal/core/communities"
+	"Coves/internal/core/notifications"
 	"Coves/internal/core/posts"
+	"Coves/internal/core/takedown"
 	"Coves/internal/core/users"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Mock implementations for testing
@@ -22,6 +28,8 @@ type mockCommentRepo struct {
 	listByParentsBatchFunc        func(ctx context.Context, parentURIs []string, sort string, limitPerParent int) (map[string][]*Comment, error)
 	getVoteStateForCommentsFunc   func(ctx context.Context, viewerDID string, commentURIs []string) (map[string]interface{}, error)
 	listByCommenterWithCursorFunc func(ctx context.Context, req ListByCommenterRequest) ([]*Comment, *string, error)
+	searchFunc                    func(ctx context.Context, req SearchRequest) ([]*Comment, *string, error)
+	countByRootCalls              int
 }
 
 func newMockCommentRepo() *mockCommentRepo {
@@ -44,6 +52,13 @@ func (m *mockCommentRepo) Update(ctx context.Context, comment *Comment) error {
 }
 
 func (m *mockCommentRepo) GetByURI(ctx context.Context, uri string) (*Comment, error) {
+	if c, ok := m.comments[uri]; ok && c.DeletedAt == nil {
+		return c, nil
+	}
+	return nil, ErrCommentNotFound
+}
+
+func (m *mockCommentRepo) GetByURIWithDeleted(ctx context.Context, uri string) (*Comment, error) {
 	if c, ok := m.comments[uri]; ok {
 		return c, nil
 	}
@@ -85,14 +100,45 @@ func (m *mockCommentRepo) ListByRoot(ctx context.Context, rootURI string, limit,
 	return nil, nil
 }
 
+func (m *mockCommentRepo) ListByRootWithCursor(ctx context.Context, rootURI string, limit int, cursor *string) ([]*Comment, *string, error) {
+	return nil, nil, nil
+}
+
 func (m *mockCommentRepo) ListByParent(ctx context.Context, parentURI string, limit, offset int) ([]*Comment, error) {
 	return nil, nil
 }
 
+func (m *mockCommentRepo) ListByParentWithCursor(ctx context.Context, parentURI string, limit int, cursor *string) ([]*Comment, *string, error) {
+	return nil, nil, nil
+}
+
 func (m *mockCommentRepo) CountByParent(ctx context.Context, parentURI string) (int, error) {
 	return 0, nil
 }
 
+// CountByRoot counts like the real repository: deleted (including moderator-removed) and
+// rejected comments are excluded
+func (m *mockCommentRepo) CountByRoot(ctx context.Context, rootURI string) (int, error) {
+	m.countByRootCalls++
+	count := 0
+	for _, c := range m.comments {
+		if c.RootURI == rootURI && c.DeletedAt == nil && c.Status != StatusRejected {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockCommentRepo) CountParticipantsByRoot(ctx context.Context, rootURI string) (int, error) {
+	commenters := make(map[string]bool)
+	for _, c := range m.comments {
+		if c.RootURI == rootURI && c.DeletedAt == nil && c.Status != StatusRejected {
+			commenters[c.CommenterDID] = true
+		}
+	}
+	return len(commenters), nil
+}
+
 func (m *mockCommentRepo) ListByCommenter(ctx context.Context, commenterDID string, limit, offset int) ([]*Comment, error) {
 	return nil, nil
 }
@@ -104,6 +150,13 @@ func (m *mockCommentRepo) ListByCommenterWithCursor(ctx context.Context, req Lis
 	return []*Comment{}, nil, nil
 }
 
+func (m *mockCommentRepo) Search(ctx context.Context, req SearchRequest) ([]*Comment, *string, error) {
+	if m.searchFunc != nil {
+		return m.searchFunc(ctx, req)
+	}
+	return []*Comment{}, nil, nil
+}
+
 func (m *mockCommentRepo) ListByParentWithHotRank(
 	ctx context.Context,
 	parentURI string,
@@ -111,6 +164,7 @@ func (m *mockCommentRepo) ListByParentWithHotRank(
 	timeframe string,
 	limit int,
 	cursor *string,
+	viewerDID string,
 ) ([]*Comment, *string, error) {
 	if m.listByParentWithHotRankFunc != nil {
 		return m.listByParentWithHotRankFunc(ctx, parentURI, sort, timeframe, limit, cursor)
@@ -118,16 +172,40 @@ func (m *mockCommentRepo) ListByParentWithHotRank(
 	return []*Comment{}, nil, nil
 }
 
+func (m *mockCommentRepo) ListByParentWithHotRankWithDeleted(
+	ctx context.Context,
+	parentURI string,
+	sort string,
+	timeframe string,
+	limit int,
+	cursor *string,
+	viewerDID string,
+) ([]*Comment, *string, error) {
+	return m.ListByParentWithHotRank(ctx, parentURI, sort, timeframe, limit, cursor, viewerDID)
+}
+
 func (m *mockCommentRepo) GetByURIsBatch(ctx context.Context, uris []string) (map[string]*Comment, error) {
 	result := make(map[string]*Comment)
 	for _, uri := range uris {
-		if c, ok := m.comments[uri]; ok {
+		if c, ok := m.comments[uri]; ok && c.DeletedAt == nil {
 			result[uri] = c
 		}
 	}
 	return result, nil
 }
 
+func (m *mockCommentRepo) GetAncestorsWithDeleted(ctx context.Context, commentURI string, maxHeight int) ([]*Comment, error) {
+	var ancestors []*Comment
+	current, ok := m.comments[commentURI]
+	for ok && len(ancestors) < maxHeight {
+		current, ok = m.comments[current.ParentURI]
+		if ok {
+			ancestors = append([]*Comment{current}, ancestors...)
+		}
+	}
+	return ancestors, nil
+}
+
 func (m *mockCommentRepo) GetVoteStateForComments(ctx context.Context, viewerDID string, commentURIs []string) (map[string]interface{}, error) {
 	if m.getVoteStateForCommentsFunc != nil {
 		return m.getVoteStateForCommentsFunc(ctx, viewerDID, commentURIs)
@@ -135,11 +213,12 @@ func (m *mockCommentRepo) GetVoteStateForComments(ctx context.Context, viewerDID
 	return make(map[string]interface{}), nil
 }
 
-func (m *mockCommentRepo) ListByParentsBatch(
+func (m *mockCommentRepo) ListByParentsBatchWithDeleted(
 	ctx context.Context,
 	parentURIs []string,
 	sort string,
 	limitPerParent int,
+	viewerDID string,
 ) (map[string][]*Comment, error) {
 	if m.listByParentsBatchFunc != nil {
 		return m.listByParentsBatchFunc(ctx, parentURIs, sort, limitPerParent)
@@ -209,6 +288,14 @@ func (m *mockUserRepo) Delete(ctx context.Context, did string) error {
 	return nil
 }
 
+func (m *mockUserRepo) ListPendingProfiles(ctx context.Context, limit int) ([]*users.User, error) {
+	return nil, nil
+}
+
+func (m *mockUserRepo) MarkProfileFetched(ctx context.Context, did string) error {
+	return nil
+}
+
 func (m *mockUserRepo) UpdateProfile(ctx context.Context, did string, input users.UpdateProfileInput) (*users.User, error) {
 	user, exists := m.users[did]
 	if !exists {
@@ -257,20 +344,33 @@ func (m *mockPostRepo) GetByAuthor(ctx context.Context, req posts.GetAuthorPosts
 	return nil, nil, nil
 }
 
+func (m *mockPostRepo) GetViewByURI(ctx context.Context, uri, viewerDID string) (*posts.PostView, error) {
+	// Mock implementation - not used by comment service tests
+	return nil, posts.ErrNotFound
+}
+
 func (m *mockPostRepo) SoftDelete(ctx context.Context, uri string) error {
 	// Mock implementation - just delete from map
 	delete(m.posts, uri)
 	return nil
 }
 
+func (m *mockPostRepo) FindDuplicates(ctx context.Context, query posts.DuplicateQuery) ([]*posts.DuplicateMatch, error) {
+	return nil, nil
+}
+
 // mockCommunityRepo is a mock implementation of the communities.Repository interface
 type mockCommunityRepo struct {
 	communities map[string]*communities.Community
+	memberships map[string]*communities.Membership // keyed by userDID|communityDID
+	subscribers map[string]bool                    // keyed by userDID|communityDID
 }
 
 func newMockCommunityRepo() *mockCommunityRepo {
 	return &mockCommunityRepo{
 		communities: make(map[string]*communities.Community),
+		memberships: make(map[string]*communities.Membership),
+		subscribers: make(map[string]bool),
 	}
 }
 
@@ -309,8 +409,8 @@ func (m *mockCommunityRepo) UpdateCredentials(ctx context.Context, did, accessTo
 	return nil
 }
 
-func (m *mockCommunityRepo) List(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error) {
-	return nil, nil
+func (m *mockCommunityRepo) List(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, *string, error) {
+	return nil, nil, nil
 }
 
 func (m *mockCommunityRepo) Search(ctx context.Context, req communities.SearchCommunitiesRequest) ([]*communities.Community, int, error) {
@@ -341,18 +441,32 @@ func (m *mockCommunityRepo) GetSubscriptionByURI(ctx context.Context, recordURI
 	return nil, nil
 }
 
-func (m *mockCommunityRepo) ListSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*communities.Subscription, error) {
-	return nil, nil
+func (m *mockCommunityRepo) ListSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
 }
 
-func (m *mockCommunityRepo) ListSubscribers(ctx context.Context, communityDID string, limit, offset int) ([]*communities.Subscription, error) {
+func (m *mockCommunityRepo) ListSubscribedCommunities(ctx context.Context, req communities.GetSubscriptionsRequest) ([]*communities.SubscribedCommunity, error) {
 	return nil, nil
 }
 
+func (m *mockCommunityRepo) ListSubscribers(ctx context.Context, communityDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
+}
+
 func (m *mockCommunityRepo) GetSubscribedCommunityDIDs(ctx context.Context, userDID string, communityDIDs []string) (map[string]bool, error) {
 	return map[string]bool{}, nil
 }
 
+func (m *mockCommunityRepo) GetSubscribedUserDIDs(ctx context.Context, communityDID string, userDIDs []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	for _, did := range userDIDs {
+		if m.subscribers[did+"|"+communityDID] {
+			result[did] = true
+		}
+	}
+	return result, nil
+}
+
 func (m *mockCommunityRepo) BlockCommunity(ctx context.Context, block *communities.CommunityBlock) (*communities.CommunityBlock, error) {
 	return nil, nil
 }
@@ -377,12 +491,39 @@ func (m *mockCommunityRepo) IsBlocked(ctx context.Context, userDID, communityDID
 	return false, nil
 }
 
+func (m *mockCommunityRepo) SubscribePendingWithCount(ctx context.Context, subscription *communities.Subscription) (*communities.Subscription, error) {
+	return subscription, nil
+}
+
+func (m *mockCommunityRepo) ConfirmSubscription(ctx context.Context, recordURI, recordCID string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockCommunityRepo) DeleteUnconfirmedSubscriptions(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
+func (m *mockCommunityRepo) BlockCommunityPending(ctx context.Context, block *communities.CommunityBlock) (*communities.CommunityBlock, error) {
+	return block, nil
+}
+
+func (m *mockCommunityRepo) ConfirmBlock(ctx context.Context, recordURI, recordCID string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockCommunityRepo) DeleteUnconfirmedBlocks(ctx context.Context, before time.Time) (int, error) {
+	return 0, nil
+}
+
 func (m *mockCommunityRepo) CreateMembership(ctx context.Context, membership *communities.Membership) (*communities.Membership, error) {
 	return nil, nil
 }
 
 func (m *mockCommunityRepo) GetMembership(ctx context.Context, userDID, communityDID string) (*communities.Membership, error) {
-	return nil, nil
+	if membership, ok := m.memberships[userDID+"|"+communityDID]; ok {
+		return membership, nil
+	}
+	return nil, communities.ErrMembershipNotFound
 }
 
 func (m *mockCommunityRepo) UpdateMembership(ctx context.Context, membership *communities.Membership) (*communities.Membership, error) {
@@ -393,6 +534,40 @@ func (m *mockCommunityRepo) ListMembers(ctx context.Context, communityDID string
 	return nil, nil
 }
 
+func (m *mockCommunityRepo) GetModeratedCommunityDIDs(ctx context.Context, userDID string, communityDIDs []string) (map[string]bool, error) {
+	result := make(map[string]bool)
+	for _, did := range communityDIDs {
+		if community, ok := m.communities[did]; ok && community.CreatedByDID == userDID {
+			result[did] = true
+		}
+		if membership, ok := m.memberships[userDID+"|"+did]; ok && membership.IsModerator {
+			result[did] = true
+		}
+	}
+	return result, nil
+}
+
+func (m *mockCommunityRepo) GetModeratorsForCommunities(ctx context.Context, communityDIDs []string) (map[string]map[string]bool, error) {
+	result := make(map[string]map[string]bool)
+	add := func(communityDID, moderatorDID string) {
+		if result[communityDID] == nil {
+			result[communityDID] = make(map[string]bool)
+		}
+		result[communityDID][moderatorDID] = true
+	}
+	for _, did := range communityDIDs {
+		if community, ok := m.communities[did]; ok {
+			add(did, community.CreatedByDID)
+		}
+		for _, membership := range m.memberships {
+			if membership.CommunityDID == did && membership.IsModerator {
+				add(did, membership.UserDID)
+			}
+		}
+	}
+	return result, nil
+}
+
 func (m *mockCommunityRepo) CreateModerationAction(ctx context.Context, action *communities.ModerationAction) (*communities.ModerationAction, error) {
 	return nil, nil
 }
@@ -417,6 +592,18 @@ func (m *mockCommunityRepo) DecrementSubscriberCount(ctx context.Context, commun
 	return nil
 }
 
+func (m *mockCommunityRepo) AdjustSubscriberCounts(ctx context.Context, deltas map[string]int) error {
+	return nil
+}
+
+func (m *mockCommunityRepo) RecountSubscriberCounts(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (m *mockCommunityRepo) RecountPostCounts(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
 func (m *mockCommunityRepo) IncrementPostCount(ctx context.Context, communityDID string) error {
 	return nil
 }
@@ -797,6 +984,7 @@ func TestCommentService_GetComments_SortingOptions(t *testing.T) {
 		timeframe string
 		wantErr   bool
 	}{
+		{"best sorting", "best", "", false},
 		{"hot sorting", "hot", "", false},
 		{"top sorting", "top", "day", false},
 		{"new sorting", "new", "", false},
@@ -906,51 +1094,153 @@ func TestCommentService_buildThreadViews_EmptyInput(t *testing.T) {
 	service := NewCommentService(commentRepo, userRepo, postRepo, communityRepo, nil, nil, nil).(*commentService)
 
 	// Execute
-	result := service.buildThreadViews(context.Background(), []*Comment{}, 10, "hot", nil)
+	result := service.buildThreadViews(context.Background(), []*Comment{}, 10, "hot", nil, true)
 
 	// Verify - should return empty slice, not nil
 	assert.NotNil(t, result)
 	assert.Len(t, result, 0)
 }
 
-func TestCommentService_buildThreadViews_IncludesDeletedCommentsAsPlaceholders(t *testing.T) {
-	// Setup
-	commentRepo := newMockCommentRepo()
-	userRepo := newMockUserRepo()
-	postRepo := newMockPostRepo()
-	communityRepo := newMockCommunityRepo()
+func TestCommentService_GetComments_DeletedComments(t *testing.T) {
+	ctx := context.Background()
+	postURI := "at://did:plc:community123/social.coves.community.post/tombstones"
+
+	// Each case has a top-level comment and a reply: "top" is deleted (by its author or a
+	// moderator) and its reply "child" is live unless the case deletes it too
+	setup := func(t *testing.T, topReason *string, deleteChild bool) (*mockCommentRepo, Service) {
+		t.Helper()
+		commentRepo := newMockCommentRepo()
+		postRepo := newMockPostRepo()
+		_ = postRepo.Create(ctx, createTestPost(postURI, "did:plc:author123", "did:plc:community123"))
+
+		top := createTestComment("at://did:plc:alice/comment/top", "did:plc:alice", "alice.test", postURI, postURI, 1)
+		child := createTestComment("at://did:plc:bob/comment/child", "did:plc:bob", "bob.test", postURI, top.URI, 0)
+		live := createTestComment("at://did:plc:carol/comment/live", "did:plc:carol", "carol.test", postURI, postURI, 0)
+		for _, comment := range []*Comment{top, child, live} {
+			_ = commentRepo.Create(ctx, comment)
+		}
+		if topReason != nil {
+			_ = commentRepo.SoftDeleteWithReason(ctx, top.URI, *topReason, "did:plc:alice")
+		}
+		if deleteChild {
+			_ = commentRepo.SoftDeleteWithReason(ctx, child.URI, DeletionReasonAuthor, "did:plc:bob")
+			top.ReplyCount = 0 // Deleting a reply decrements its parent's reply count
+		}
 
+		repliesTo := func(parentURI string) []*Comment {
+			var replies []*Comment
+			for _, uri := range []string{top.URI, child.URI, live.URI} {
+				if commentRepo.comments[uri].ParentURI == parentURI {
+					replies = append(replies, commentRepo.comments[uri])
+				}
+			}
+			return replies
+		}
+		commentRepo.listByParentWithHotRankFunc = func(ctx context.Context, parentURI, sort, timeframe string, limit int, cursor *string) ([]*Comment, *string, error) {
+			return repliesTo(parentURI), nil, nil
+		}
+		commentRepo.listByParentsBatchFunc = func(ctx context.Context, parentURIs []string, sort string, limitPerParent int) (map[string][]*Comment, error) {
+			result := make(map[string][]*Comment)
+			for _, parentURI := range parentURIs {
+				result[parentURI] = repliesTo(parentURI)
+			}
+			return result, nil
+		}
+
+		return commentRepo, NewCommentService(commentRepo, newMockUserRepo(), postRepo, newMockCommunityRepo(), nil, nil, nil)
+	}
+
+	getComments := func(t *testing.T, service Service) *GetCommentsResponse {
+		resp, err := service.GetComments(ctx, &GetCommentsRequest{PostURI: postURI, Sort: "new", Depth: 10, Limit: 50})
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("deleted leaf is omitted", func(t *testing.T) {
+		reason := DeletionReasonAuthor
+		_, service := setup(t, &reason, true)
+
+		resp := getComments(t, service)
+		require.Len(t, resp.Comments, 1)
+		assert.Equal(t, "at://did:plc:carol/comment/live", resp.Comments[0].Comment.URI)
+		require.NotNil(t, resp.Meta)
+		assert.Equal(t, 1, resp.Meta.TotalComments)
+	})
+
+	t.Run("deleted comment with live replies is a tombstone", func(t *testing.T) {
+		reason := DeletionReasonAuthor
+		_, service := setup(t, &reason, false)
+
+		resp := getComments(t, service)
+		require.Len(t, resp.Comments, 2)
+		tombstone := resp.Comments[0]
+		assert.True(t, tombstone.Comment.IsDeleted)
+		assert.Nil(t, tombstone.Comment.Author)
+		record, ok := tombstone.Comment.Record.(*CommentRecord)
+		require.True(t, ok)
+		assert.Equal(t, TombstoneContentDeleted, record.Content)
+		assert.Equal(t, 1, tombstone.Comment.Stats.ReplyCount)
+
+		require.Len(t, tombstone.Replies, 1)
+		assert.Equal(t, "at://did:plc:bob/comment/child", tombstone.Replies[0].Comment.URI)
+		assert.False(t, tombstone.Replies[0].Comment.IsDeleted)
+		require.NotNil(t, tombstone.Replies[0].Comment.Author)
+
+		// Thread meta counts only live comments
+		require.NotNil(t, resp.Meta)
+		assert.Equal(t, 2, resp.Meta.TotalComments)
+		assert.Equal(t, 2, resp.Meta.Participants)
+	})
+
+	t.Run("moderator-removed comment with live replies is a removed tombstone", func(t *testing.T) {
+		reason := DeletionReasonModerator
+		_, service := setup(t, &reason, false)
+
+		resp := getComments(t, service)
+		require.Len(t, resp.Comments, 2)
+		tombstone := resp.Comments[0]
+		assert.True(t, tombstone.Comment.IsDeleted)
+		assert.Nil(t, tombstone.Comment.Author)
+		assert.Equal(t, DeletionReasonModerator, *tombstone.Comment.DeletionReason)
+		assert.Equal(t, TombstoneContentRemoved, tombstone.Comment.Record.(*CommentRecord).Content)
+		require.Len(t, tombstone.Replies, 1)
+		assert.Equal(t, "at://did:plc:bob/comment/child", tombstone.Replies[0].Comment.URI)
+	})
+
+	t.Run("deleted reply of a live comment is omitted", func(t *testing.T) {
+		commentRepo, service := setup(t, nil, true)
+		// A reply count not yet reconciled still loads the deleted reply, which is pruned
+		commentRepo.comments["at://did:plc:alice/comment/top"].ReplyCount = 1
+
+		resp := getComments(t, service)
+		require.Len(t, resp.Comments, 2)
+		top := resp.Comments[0]
+		assert.False(t, top.Comment.IsDeleted)
+		assert.Empty(t, top.Replies)
+	})
+}
+
+func TestCommentService_buildThreadViews_KeepsTombstoneAtDepthLimit(t *testing.T) {
 	postURI := "at://did:plc:post123/app.bsky.feed.post/test"
 	deletedAt := time.Now()
-	deletionReason := DeletionReasonAuthor
-
-	// Create a deleted comment
-	deletedComment := createTestComment("at://did:plc:commenter123/comment/1", "did:plc:commenter123", "commenter.test", postURI, postURI, 0)
-	deletedComment.DeletedAt = &deletedAt
-	deletedComment.DeletionReason = &deletionReason
-	deletedComment.Content = "" // Content is blanked on deletion
-
-	// Create a normal comment
-	normalComment := createTestComment("at://did:plc:commenter123/comment/2", "did:plc:commenter123", "commenter.test", postURI, postURI, 0)
-
-	service := NewCommentService(commentRepo, userRepo, postRepo, communityRepo, nil, nil, nil).(*commentService)
+	reason := DeletionReasonAuthor
 
-	// Execute
-	result := service.buildThreadViews(context.Background(), []*Comment{deletedComment, normalComment}, 10, "hot", nil)
-
-	// Verify - both comments should be included to preserve thread structure
-	assert.Len(t, result, 2)
+	// A deleted comment whose live replies are past the requested depth is kept so clients
+	// can load them; one with no live replies is dropped
+	withReplies := createTestComment("at://did:plc:commenter123/comment/1", "did:plc:commenter123", "commenter.test", postURI, postURI, 2)
+	withoutReplies := createTestComment("at://did:plc:commenter123/comment/2", "did:plc:commenter123", "commenter.test", postURI, postURI, 0)
+	for _, comment := range []*Comment{withReplies, withoutReplies} {
+		comment.DeletedAt = &deletedAt
+		comment.DeletionReason = &reason
+	}
 
-	// First comment should be the deleted one with placeholder info
-	assert.Equal(t, deletedComment.URI, result[0].Comment.URI)
-	assert.True(t, result[0].Comment.IsDeleted)
-	assert.Equal(t, DeletionReasonAuthor, *result[0].Comment.DeletionReason)
-	assert.Nil(t, result[0].Comment.Record) // Deleted comments have nil record
+	service := NewCommentService(newMockCommentRepo(), newMockUserRepo(), newMockPostRepo(), newMockCommunityRepo(), nil, nil, nil).(*commentService)
+	result := service.buildThreadViews(context.Background(), []*Comment{withReplies, withoutReplies}, 0, "hot", nil, true)
 
-	// Second comment should be the normal one
-	assert.Equal(t, normalComment.URI, result[1].Comment.URI)
-	assert.False(t, result[1].Comment.IsDeleted)
-	assert.Nil(t, result[1].Comment.DeletionReason)
+	require.Len(t, result, 1)
+	assert.Equal(t, withReplies.URI, result[0].Comment.URI)
+	assert.True(t, result[0].HasMore)
+	assert.Equal(t, TombstoneContentDeleted, result[0].Comment.Record.(*CommentRecord).Content)
 }
 
 func TestCommentService_buildThreadViews_WithNestedReplies(t *testing.T) {
@@ -980,7 +1270,7 @@ func TestCommentService_buildThreadViews_WithNestedReplies(t *testing.T) {
 	service := NewCommentService(commentRepo, userRepo, postRepo, communityRepo, nil, nil, nil).(*commentService)
 
 	// Execute with depth > 0 to load replies
-	result := service.buildThreadViews(context.Background(), []*Comment{parentComment}, 1, "hot", nil)
+	result := service.buildThreadViews(context.Background(), []*Comment{parentComment}, 1, "hot", nil, true)
 
 	// Verify
 	assert.Len(t, result, 1)
@@ -1007,7 +1297,7 @@ func TestCommentService_buildThreadViews_DepthLimit(t *testing.T) {
 	service := NewCommentService(commentRepo, userRepo, postRepo, communityRepo, nil, nil, nil).(*commentService)
 
 	// Execute with depth = 0 (should not load replies)
-	result := service.buildThreadViews(context.Background(), []*Comment{parentComment}, 0, "hot", nil)
+	result := service.buildThreadViews(context.Background(), []*Comment{parentComment}, 0, "hot", nil, true)
 
 	// Verify
 	assert.Len(t, result, 1)
@@ -1015,6 +1305,36 @@ func TestCommentService_buildThreadViews_DepthLimit(t *testing.T) {
 	assert.True(t, result[0].HasMore) // Should indicate more replies exist
 }
 
+func TestCommentService_buildThreadViews_CollapsesRepliesPastMaxDepth(t *testing.T) {
+	commentRepo := newMockCommentRepo()
+	postURI := "at://did:plc:post123/app.bsky.feed.post/test"
+	parentURI := "at://did:plc:commenter123/comment/12"
+
+	parentComment := createTestComment(parentURI, "did:plc:commenter123", "commenter.test", postURI, postURI, 2)
+	deepReply := createTestComment("at://did:plc:commenter123/comment/13", "did:plc:commenter123", "commenter.test", postURI, parentURI, 0)
+	deepReply.DepthExceeded = true
+	otherDeepReply := createTestComment("at://did:plc:commenter123/comment/14", "did:plc:commenter123", "commenter.test", postURI, parentURI, 0)
+	otherDeepReply.DepthExceeded = true
+
+	commentRepo.listByParentsBatchFunc = func(ctx context.Context, parentURIs []string, sort string, limitPerParent int) (map[string][]*Comment, error) {
+		return map[string][]*Comment{parentURI: {deepReply, otherDeepReply}}, nil
+	}
+
+	service := NewCommentService(commentRepo, newMockUserRepo(), newMockPostRepo(), newMockCommunityRepo(), nil, nil, nil).(*commentService)
+
+	// getComments replaces them with a "continue thread" link
+	collapsed := service.buildThreadViews(context.Background(), []*Comment{parentComment}, 3, "hot", nil, true)
+	assert.Len(t, collapsed, 1)
+	assert.Empty(t, collapsed[0].Replies)
+	assert.True(t, collapsed[0].ContinueThread)
+	assert.False(t, collapsed[0].HasMore, "every reply was loaded")
+
+	// getCommentThread, which the link points to, shows them
+	expanded := service.buildThreadViews(context.Background(), []*Comment{parentComment}, 3, "hot", nil, false)
+	assert.Len(t, expanded[0].Replies, 2)
+	assert.False(t, expanded[0].ContinueThread)
+}
+
 // Test suite for buildCommentView
 
 func TestCommentService_buildCommentView_BasicFields(t *testing.T) {
@@ -1049,6 +1369,42 @@ func TestCommentService_buildCommentView_BasicFields(t *testing.T) {
 	assert.Equal(t, 0, result.Stats.ReplyCount)
 }
 
+func TestCommentService_buildCommentView_HydratesAuthorAvatar(t *testing.T) {
+	communities.ResetImageProxyConfigForTesting()
+	communities.SetImageProxyConfig(blobs.ImageURLConfig{ProxyEnabled: true, ProxyBaseURL: "https://coves.social"})
+	t.Cleanup(communities.ResetImageProxyConfigForTesting)
+
+	postURI := "at://did:plc:post123/app.bsky.feed.post/test"
+	service := NewCommentService(newMockCommentRepo(), newMockUserRepo(), newMockPostRepo(), newMockCommunityRepo(), nil, nil, nil).(*commentService)
+	usersByDID := map[string]*users.User{
+		"did:plc:withavatar": {DID: "did:plc:withavatar", Handle: "withavatar.test", PDSURL: "https://pds.test", AvatarCID: "bafyavatar"},
+		"did:plc:noavatar":   {DID: "did:plc:noavatar", Handle: "noavatar.test", PDSURL: "https://pds.test"},
+	}
+
+	tests := []struct {
+		name       string
+		authorDID  string
+		wantAvatar string
+	}{
+		{name: "avatar blob", authorDID: "did:plc:withavatar", wantAvatar: "https://coves.social/img/avatar_small/plain/did:plc:withavatar/bafyavatar"},
+		{name: "no avatar falls back to identicon", authorDID: "did:plc:noavatar", wantAvatar: "https://coves.social/img/identicon/did:plc:noavatar"},
+		{name: "unknown author falls back to identicon", authorDID: "did:plc:unknown", wantAvatar: "https://coves.social/img/identicon/did:plc:unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			comment := createTestComment("at://"+tt.authorDID+"/comment/1", tt.authorDID, "author.test", postURI, postURI, 0)
+			result := service.buildCommentView(comment, nil, nil, usersByDID)
+			require.NotNil(t, result.Author.Avatar)
+			assert.Equal(t, tt.wantAvatar, *result.Author.Avatar)
+		})
+	}
+
+	// Deleted placeholders only show the identicon
+	deleted := service.buildDeletedCommentView(createTestComment("at://did:plc:withavatar/comment/2", "did:plc:withavatar", "withavatar.test", postURI, postURI, 0))
+	require.NotNil(t, deleted.Author.Avatar)
+	assert.Equal(t, "https://coves.social/img/identicon/did:plc:withavatar", *deleted.Author.Avatar)
+}
+
 func TestCommentService_buildCommentView_TopLevelComment(t *testing.T) {
 	// Setup
 	commentRepo := newMockCommentRepo()
@@ -1179,7 +1535,7 @@ func TestValidateGetCommentsRequest_Defaults(t *testing.T) {
 	assert.NoError(t, err)
 
 	// Check defaults applied
-	assert.Equal(t, "hot", req.Sort)
+	assert.Equal(t, "best", req.Sort)
 	// Depth 0 is valid (means no replies), only negative values get set to 10
 	assert.Equal(t, 0, req.Depth)
 	// Limit <= 0 gets set to 50
@@ -1503,6 +1859,126 @@ func TestBuildCommentView_EmptyStringVsNilHandling(t *testing.T) {
 	}
 }
 
+// Test suite for GetCommentThread
+
+func TestCommentService_GetCommentThread_AncestorsFocusAndReplies(t *testing.T) {
+	postURI := "at://did:plc:post123/app.bsky.feed.post/test"
+	commenterDID := "did:plc:commenter123"
+
+	commentRepo := newMockCommentRepo()
+	postRepo := newMockPostRepo()
+	_ = postRepo.Create(context.Background(), createTestPost(postURI, "did:plc:author123", "did:plc:community123"))
+
+	// post <- c1 <- c2 (removed by a moderator) <- c3 <- focus <- reply
+	parent := postURI
+	var chain []*Comment
+	for i := 1; i <= 4; i++ {
+		comment := createTestComment(fmt.Sprintf("at://did:plc:commenter123/comment/%d", i), commenterDID, "commenter.test", postURI, parent, 1)
+		_ = commentRepo.Create(context.Background(), comment)
+		chain = append(chain, comment)
+		parent = comment.URI
+	}
+	deletedAt := time.Now()
+	chain[1].DeletedAt = &deletedAt
+	chain[1].DeletionReason = strPtr(DeletionReasonModerator)
+	chain[1].Content = ""
+	focus := chain[3]
+
+	reply := createTestComment("at://did:plc:commenter123/comment/reply", commenterDID, "commenter.test", postURI, focus.URI, 0)
+	commentRepo.listByParentWithHotRankFunc = func(ctx context.Context, parentURI, sort, timeframe string, limit int, cursor *string) ([]*Comment, *string, error) {
+		if parentURI == focus.URI {
+			return []*Comment{reply}, nil, nil
+		}
+		return []*Comment{}, nil, nil
+	}
+
+	service := NewCommentService(commentRepo, newMockUserRepo(), postRepo, newMockCommunityRepo(), nil, nil, nil)
+
+	resp, err := service.GetCommentThread(context.Background(), &GetCommentThreadRequest{
+		CommentURI:   focus.URI,
+		ParentHeight: 10,
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, resp.Post)
+	assert.Equal(t, focus.URI, resp.Thread.Focus.URI)
+	assert.False(t, resp.Thread.HasMoreAncestors)
+
+	// Ancestors are root-first; the removed one is a stub, not a gap
+	if assert.Len(t, resp.Thread.Ancestors, 3) {
+		assert.Equal(t, chain[0].URI, resp.Thread.Ancestors[0].URI)
+		assert.Equal(t, chain[1].URI, resp.Thread.Ancestors[1].URI)
+		assert.True(t, resp.Thread.Ancestors[1].IsDeleted)
+		assert.Equal(t, DeletionReasonModerator, *resp.Thread.Ancestors[1].DeletionReason)
+		assert.Nil(t, resp.Thread.Ancestors[1].Record)
+		assert.Equal(t, chain[2].URI, resp.Thread.Ancestors[2].URI)
+		assert.False(t, resp.Thread.Ancestors[2].IsDeleted)
+	}
+
+	if assert.Len(t, resp.Thread.Replies, 1) {
+		assert.Equal(t, reply.URI, resp.Thread.Replies[0].Comment.URI)
+	}
+}
+
+func TestCommentService_GetCommentThread_ParentHeightLimit(t *testing.T) {
+	postURI := "at://did:plc:post123/app.bsky.feed.post/test"
+
+	commentRepo := newMockCommentRepo()
+	postRepo := newMockPostRepo()
+	_ = postRepo.Create(context.Background(), createTestPost(postURI, "did:plc:author123", "did:plc:community123"))
+
+	parent := postURI
+	for i := 1; i <= 5; i++ {
+		comment := createTestComment(fmt.Sprintf("at://did:plc:commenter123/comment/%d", i), "did:plc:commenter123", "commenter.test", postURI, parent, 1)
+		_ = commentRepo.Create(context.Background(), comment)
+		parent = comment.URI
+	}
+
+	service := NewCommentService(commentRepo, newMockUserRepo(), postRepo, newMockCommunityRepo(), nil, nil, nil)
+
+	resp, err := service.GetCommentThread(context.Background(), &GetCommentThreadRequest{
+		CommentURI:   parent,
+		ParentHeight: 2,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Thread.Ancestors, 2)
+	assert.Equal(t, "at://did:plc:commenter123/comment/3", resp.Thread.Ancestors[0].URI)
+	assert.True(t, resp.Thread.HasMoreAncestors)
+	assert.NotNil(t, resp.Thread.Replies)
+}
+
+func TestCommentService_GetCommentThread_CommentNotFound(t *testing.T) {
+	service := NewCommentService(newMockCommentRepo(), newMockUserRepo(), newMockPostRepo(), newMockCommunityRepo(), nil, nil, nil)
+
+	resp, err := service.GetCommentThread(context.Background(), &GetCommentThreadRequest{
+		CommentURI: "at://did:plc:commenter123/comment/missing",
+	})
+
+	assert.Nil(t, resp)
+	assert.ErrorIs(t, err, ErrCommentNotFound)
+}
+
+func TestValidateGetCommentThreadRequest_Defaults(t *testing.T) {
+	req := &GetCommentThreadRequest{
+		CommentURI:   "at://did:plc:commenter123/comment/1",
+		ParentHeight: -1,
+		Depth:        -1,
+	}
+
+	assert.NoError(t, validateGetCommentThreadRequest(req))
+	assert.Equal(t, 10, req.ParentHeight)
+	assert.Equal(t, 10, req.Depth)
+	assert.Equal(t, 50, req.Limit)
+	assert.Equal(t, "best", req.Sort)
+
+	req.ParentHeight = 500
+	assert.NoError(t, validateGetCommentThreadRequest(req))
+	assert.Equal(t, 100, req.ParentHeight)
+
+	assert.Error(t, validateGetCommentThreadRequest(&GetCommentThreadRequest{CommentURI: "not-a-uri"}))
+}
+
 // Helper function to create string pointers
 func strPtr(s string) *string {
 	return &s
@@ -1868,3 +2344,588 @@ func TestValidateGetActorCommentsRequest_BoundsEnforcement(t *testing.T) {
 		})
 	}
 }
+
+// Test suite for SearchComments
+
+func TestCommentService_SearchComments_Authorization(t *testing.T) {
+	const (
+		communityDID = "did:plc:community123"
+		ownerDID     = "did:plc:owner123"
+		modDID       = "did:plc:mod123"
+		memberDID    = "did:plc:member123"
+		adminDID     = "did:plc:admin123"
+		strangerDID  = "did:plc:stranger123"
+	)
+	postURI := "at://" + communityDID + "/social.coves.community.post/post1"
+
+	commentRepo := newMockCommentRepo()
+	communityRepo := newMockCommunityRepo()
+	communityRepo.communities[communityDID] = &communities.Community{
+		DID:          communityDID,
+		Handle:       "c-gaming.coves.social",
+		CreatedByDID: ownerDID,
+	}
+	communityRepo.memberships[modDID+"|"+communityDID] = &communities.Membership{UserDID: modDID, CommunityDID: communityDID, IsModerator: true}
+	communityRepo.memberships[memberDID+"|"+communityDID] = &communities.Membership{UserDID: memberDID, CommunityDID: communityDID}
+
+	var searched SearchRequest
+	commentRepo.searchFunc = func(ctx context.Context, req SearchRequest) ([]*Comment, *string, error) {
+		searched = req
+		return []*Comment{createTestComment("at://did:plc:author/comment/1", "did:plc:author", "author.test", postURI, postURI, 0)}, nil, nil
+	}
+
+	service := NewCommentService(commentRepo, newMockUserRepo(), newMockPostRepo(), communityRepo, nil, nil, nil)
+	service.(interface{ SetInstanceAdmins([]string) }).SetInstanceAdmins([]string{adminDID})
+
+	tests := []struct {
+		name      string
+		viewerDID string
+		allowed   bool
+	}{
+		{"owner", ownerDID, true},
+		{"moderator", modDID, true},
+		{"instance admin", adminDID, true},
+		{"member without moderator role", memberDID, false},
+		{"non-member", strangerDID, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := service.SearchComments(context.Background(), &SearchCommentsRequest{
+				ViewerDID: tt.viewerDID,
+				Community: "c-gaming.coves.social",
+				Query:     "spam",
+			})
+
+			if !tt.allowed {
+				assert.ErrorIs(t, err, ErrNotAuthorized)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, resp.Comments, 1)
+			assert.Equal(t, postURI, resp.Comments[0].Post.URI)
+			assert.Equal(t, communityDID, searched.CommunityDID)
+			assert.Equal(t, 25, searched.Limit)
+		})
+	}
+}
+
+func TestCommentService_SearchComments_DeletedCommentsAreStubs(t *testing.T) {
+	const communityDID = "did:plc:community123"
+	postURI := "at://" + communityDID + "/social.coves.community.post/post1"
+
+	commentRepo := newMockCommentRepo()
+	communityRepo := newMockCommunityRepo()
+	communityRepo.communities[communityDID] = &communities.Community{DID: communityDID, CreatedByDID: "did:plc:owner123"}
+
+	deletedAt := time.Now()
+	removed := createTestComment("at://did:plc:author/comment/1", "did:plc:author", "author.test", postURI, postURI, 0)
+	removed.DeletedAt = &deletedAt
+	commentRepo.searchFunc = func(ctx context.Context, req SearchRequest) ([]*Comment, *string, error) {
+		assert.True(t, req.IncludeDeleted)
+		return []*Comment{removed}, nil, nil
+	}
+
+	service := NewCommentService(commentRepo, newMockUserRepo(), newMockPostRepo(), communityRepo, nil, nil, nil)
+	resp, err := service.SearchComments(context.Background(), &SearchCommentsRequest{
+		ViewerDID:      "did:plc:owner123",
+		Community:      communityDID,
+		Query:          "spam",
+		IncludeDeleted: true,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, resp.Comments, 1)
+	assert.True(t, resp.Comments[0].IsDeleted)
+	assert.Nil(t, resp.Comments[0].Record)
+}
+
+func TestCommentService_SearchComments_Validation(t *testing.T) {
+	service := NewCommentService(newMockCommentRepo(), newMockUserRepo(), newMockPostRepo(), newMockCommunityRepo(), nil, nil, nil)
+
+	_, err := service.SearchComments(context.Background(), &SearchCommentsRequest{
+		ViewerDID: "did:plc:owner123",
+		Community: "did:plc:community123",
+		Query:     "   ",
+	})
+	assert.ErrorIs(t, err, ErrInvalidSearch)
+
+	_, err = service.SearchComments(context.Background(), &SearchCommentsRequest{
+		ViewerDID: "did:plc:owner123",
+		Community: "did:plc:missing",
+		Query:     "spam",
+	})
+	assert.ErrorIs(t, err, communities.ErrCommunityNotFound)
+}
+
+func TestCommentService_GetComments_ScoreHiding(t *testing.T) {
+	postURI := "at://did:plc:author123/social.coves.community.post/hidden"
+	communityDID := "did:plc:community123"
+	authorDID := "did:plc:author123"
+	commenterDID := "did:plc:commenter123"
+	modDID := "did:plc:mod123"
+
+	setup := func() Service {
+		commentRepo := newMockCommentRepo()
+		postRepo := newMockPostRepo()
+		communityRepo := newMockCommunityRepo()
+
+		post := createTestPost(postURI, authorDID, communityDID)
+		post.CreatedAt = time.Now().Add(-time.Hour)
+		_ = postRepo.Create(context.Background(), post)
+
+		community := createTestCommunity(communityDID, "c-test.coves.social")
+		community.ScoreHidingHours = 2
+		_, _ = communityRepo.Create(context.Background(), community)
+		communityRepo.memberships[modDID+"|"+communityDID] = &communities.Membership{UserDID: modDID, CommunityDID: communityDID, IsModerator: true}
+
+		fresh := createTestComment("at://did:plc:commenter123/comment/fresh", commenterDID, "commenter.test", postURI, postURI, 0)
+		fresh.CreatedAt = time.Now().Add(-30 * time.Minute)
+		old := createTestComment("at://did:plc:commenter123/comment/old", commenterDID, "commenter.test", postURI, postURI, 0)
+		old.CreatedAt = time.Now().Add(-3 * time.Hour)
+
+		commentRepo.listByParentWithHotRankFunc = func(ctx context.Context, parentURI, sort, timeframe string, limit int, cursor *string) ([]*Comment, *string, error) {
+			return []*Comment{fresh, old}, nil, nil
+		}
+
+		return NewCommentService(commentRepo, newMockUserRepo(), postRepo, communityRepo, nil, nil, nil)
+	}
+
+	getComments := func(t *testing.T, viewerDID *string) *GetCommentsResponse {
+		resp, err := setup().GetComments(context.Background(), &GetCommentsRequest{
+			PostURI: postURI, ViewerDID: viewerDID, Sort: "new", Depth: 10, Limit: 50,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Comments, 2)
+		return resp
+	}
+
+	t.Run("hidden from other viewers inside the window", func(t *testing.T) {
+		resp := getComments(t, nil)
+
+		postView := resp.Post.(*posts.PostView)
+		assert.True(t, postView.Stats.ScoreHidden)
+		assert.True(t, resp.Comments[0].Comment.Stats.ScoreHidden)
+		assert.False(t, resp.Comments[1].Comment.Stats.ScoreHidden, "comment older than the window shows counts")
+		assert.Equal(t, 5, resp.Comments[1].Comment.Stats.Upvotes)
+
+		body, err := json.Marshal(resp.Comments[0].Comment)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"upvotes":null`)
+		assert.Contains(t, string(body), `"score":null`)
+		assert.Contains(t, string(body), `"scoreHidden":true`)
+	})
+
+	t.Run("author sees own counts", func(t *testing.T) {
+		viewer := commenterDID
+		resp := getComments(t, &viewer)
+
+		assert.True(t, resp.Post.(*posts.PostView).Stats.ScoreHidden, "someone else's post stays hidden")
+		assert.False(t, resp.Comments[0].Comment.Stats.ScoreHidden)
+		assert.Equal(t, 5, resp.Comments[0].Comment.Stats.Upvotes)
+	})
+
+	t.Run("moderator sees all counts", func(t *testing.T) {
+		viewer := modDID
+		resp := getComments(t, &viewer)
+
+		assert.False(t, resp.Post.(*posts.PostView).Stats.ScoreHidden)
+		assert.False(t, resp.Comments[0].Comment.Stats.ScoreHidden)
+	})
+}
+
+func TestCommentService_AuthorBadges(t *testing.T) {
+	postURI := "at://did:plc:author123/social.coves.community.post/badges"
+	communityDID := "did:plc:community123"
+	authorDID := "did:plc:author123"
+	modDID := "did:plc:mod123"
+	creatorDID := "did:plc:creator"
+	commenterDID := "did:plc:commenter123"
+
+	commentRepo := newMockCommentRepo()
+	postRepo := newMockPostRepo()
+	communityRepo := newMockCommunityRepo()
+
+	_ = postRepo.Create(context.Background(), createTestPost(postURI, authorDID, communityDID))
+	_, _ = communityRepo.Create(context.Background(), createTestCommunity(communityDID, "c-test.coves.social"))
+	communityRepo.memberships[modDID+"|"+communityDID] = &communities.Membership{UserDID: modDID, CommunityDID: communityDID, IsModerator: true}
+
+	byOP := createTestComment("at://did:plc:author123/comment/op", authorDID, "author.test", postURI, postURI, 1)
+	byMod := createTestComment("at://did:plc:mod123/comment/mod", modDID, "mod.test", postURI, byOP.URI, 0)
+	byCommunity := createTestComment("at://did:plc:community123/comment/official", communityDID, "c-test.coves.social", postURI, postURI, 0)
+	byCreator := createTestComment("at://did:plc:creator/comment/creator", creatorDID, "creator.test", postURI, postURI, 0)
+	byOther := createTestComment("at://did:plc:commenter123/comment/other", commenterDID, "commenter.test", postURI, postURI, 0)
+	for _, comment := range []*Comment{byOP, byMod, byCommunity, byCreator, byOther} {
+		_ = commentRepo.Create(context.Background(), comment)
+	}
+
+	commentRepo.listByParentWithHotRankFunc = func(ctx context.Context, parentURI, sort, timeframe string, limit int, cursor *string) ([]*Comment, *string, error) {
+		if parentURI == postURI {
+			return []*Comment{byOP, byCommunity, byCreator, byOther}, nil, nil
+		}
+		return []*Comment{}, nil, nil
+	}
+	// Nested replies are loaded by the batch query
+	commentRepo.listByParentsBatchFunc = func(ctx context.Context, parentURIs []string, sort string, limitPerParent int) (map[string][]*Comment, error) {
+		return map[string][]*Comment{byOP.URI: {byMod}}, nil
+	}
+
+	service := NewCommentService(commentRepo, newMockUserRepo(), postRepo, communityRepo, nil, nil, nil)
+
+	t.Run("getComments", func(t *testing.T) {
+		resp, err := service.GetComments(context.Background(), &GetCommentsRequest{
+			PostURI: postURI, Sort: "new", Depth: 10, Limit: 50,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Comments, 4)
+
+		assert.Equal(t, &posts.AuthorBadges{IsOP: true}, resp.Comments[0].Comment.Author.Badges)
+		require.Len(t, resp.Comments[0].Replies, 1)
+		assert.Equal(t, &posts.AuthorBadges{IsModerator: true}, resp.Comments[0].Replies[0].Comment.Author.Badges, "nested replies are badged")
+		assert.Equal(t, &posts.AuthorBadges{IsCommunity: true}, resp.Comments[1].Comment.Author.Badges)
+		assert.Equal(t, &posts.AuthorBadges{IsModerator: true}, resp.Comments[2].Comment.Author.Badges, "the creator counts as a moderator")
+		assert.Nil(t, resp.Comments[3].Comment.Author.Badges)
+
+		// The post's author isn't OP of their own post
+		assert.Nil(t, resp.Post.(*posts.PostView).Author.Badges)
+
+		body, err := json.Marshal(resp.Comments[0].Comment)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"badges":{"isOP":true}`)
+		body, err = json.Marshal(resp.Comments[3].Comment)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), `"badges"`)
+	})
+
+	t.Run("permalink", func(t *testing.T) {
+		resp, err := service.GetCommentThread(context.Background(), &GetCommentThreadRequest{
+			CommentURI: byMod.URI, ParentHeight: 10,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, resp.Thread.Ancestors, 1)
+		assert.Equal(t, &posts.AuthorBadges{IsOP: true}, resp.Thread.Ancestors[0].Author.Badges)
+		assert.Equal(t, &posts.AuthorBadges{IsModerator: true}, resp.Thread.Focus.Author.Badges)
+	})
+
+	t.Run("moderator lookup failure drops only moderator badges", func(t *testing.T) {
+		failing := &failingModeratorsRepo{mockCommunityRepo: communityRepo}
+		service := NewCommentService(commentRepo, newMockUserRepo(), postRepo, failing, nil, nil, nil)
+
+		resp, err := service.GetComments(context.Background(), &GetCommentsRequest{
+			PostURI: postURI, Sort: "new", Depth: 10, Limit: 50,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &posts.AuthorBadges{IsOP: true}, resp.Comments[0].Comment.Author.Badges)
+		assert.Nil(t, resp.Comments[0].Replies[0].Comment.Author.Badges)
+		assert.Equal(t, &posts.AuthorBadges{IsCommunity: true}, resp.Comments[1].Comment.Author.Badges)
+	})
+}
+
+// failingModeratorsRepo fails moderator lookups
+type failingModeratorsRepo struct {
+	*mockCommunityRepo
+}
+
+func (m *failingModeratorsRepo) GetModeratorsForCommunities(ctx context.Context, communityDIDs []string) (map[string]map[string]bool, error) {
+	return nil, errors.New("database unavailable")
+}
+
+func TestCommentService_CollapseThreshold(t *testing.T) {
+	postURI := "at://did:plc:author123/social.coves.community.post/collapse"
+	communityDID := "did:plc:community123"
+	authorDID := "did:plc:author123"
+
+	newService := func(community *communities.Community, scores ...int) Service {
+		commentRepo := newMockCommentRepo()
+		postRepo := newMockPostRepo()
+		communityRepo := newMockCommunityRepo()
+		_ = postRepo.Create(context.Background(), createTestPost(postURI, authorDID, communityDID))
+		_, _ = communityRepo.Create(context.Background(), community)
+
+		var comments []*Comment
+		for i, score := range scores {
+			comment := createTestComment(fmt.Sprintf("at://did:plc:commenter123/comment/%d", i), "did:plc:commenter123", "commenter.test", postURI, postURI, 0)
+			comment.Score = score
+			comment.CreatedAt = time.Now().Add(-time.Minute)
+			comments = append(comments, comment)
+		}
+		commentRepo.listByParentWithHotRankFunc = func(ctx context.Context, parentURI, sort, timeframe string, limit int, cursor *string) ([]*Comment, *string, error) {
+			return comments, nil, nil
+		}
+		return NewCommentService(commentRepo, newMockUserRepo(), postRepo, communityRepo, nil, nil, nil)
+	}
+
+	t.Run("default threshold boundaries", func(t *testing.T) {
+		community := createTestCommunity(communityDID, "c-test.coves.social")
+		community.CollapseThreshold = communities.DefaultCollapseThreshold
+
+		resp, err := newService(community, -6, -5, -4).GetComments(context.Background(), &GetCommentsRequest{
+			PostURI: postURI, Sort: "new", Depth: 10, Limit: 50,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Comments, 3)
+
+		assert.True(t, resp.Comments[0].Comment.Collapsed, "score below the threshold is collapsed")
+		assert.False(t, resp.Comments[1].Comment.Collapsed, "score at the threshold is not collapsed")
+		assert.False(t, resp.Comments[2].Comment.Collapsed)
+
+		body, err := json.Marshal(resp.Comments[0].Comment)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"collapsed":true`)
+		body, err = json.Marshal(resp.Comments[1].Comment)
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), `"collapsed"`)
+	})
+
+	t.Run("configured threshold", func(t *testing.T) {
+		community := createTestCommunity(communityDID, "c-test.coves.social")
+		community.CollapseThreshold = -20
+
+		resp, err := newService(community, -21, -20, -6).GetComments(context.Background(), &GetCommentsRequest{
+			PostURI: postURI, Sort: "new", Depth: 10, Limit: 50,
+		})
+		require.NoError(t, err)
+		assert.True(t, resp.Comments[0].Comment.Collapsed)
+		assert.False(t, resp.Comments[1].Comment.Collapsed)
+		assert.False(t, resp.Comments[2].Comment.Collapsed)
+	})
+
+	t.Run("hidden scores never collapse", func(t *testing.T) {
+		community := createTestCommunity(communityDID, "c-test.coves.social")
+		community.CollapseThreshold = communities.DefaultCollapseThreshold
+		community.ScoreHidingHours = 24
+
+		resp, err := newService(community, -50).GetComments(context.Background(), &GetCommentsRequest{
+			PostURI: postURI, Sort: "new", Depth: 10, Limit: 50,
+		})
+		require.NoError(t, err)
+		require.True(t, resp.Comments[0].Comment.Stats.ScoreHidden)
+		assert.False(t, resp.Comments[0].Comment.Collapsed, "collapsing would leak the hidden score")
+	})
+}
+
+func TestCommentService_CrowdControl(t *testing.T) {
+	postURI := "at://did:plc:author123/social.coves.community.post/crowd"
+	communityDID := "did:plc:community123"
+	authorDID := "did:plc:author123"
+
+	// Authors by subscription and account age
+	subscriberOld := "did:plc:subold"
+	subscriberNew := "did:plc:subnew"
+	outsiderOld := "did:plc:outold"
+	outsiderNew := "did:plc:outnew"
+	authorDIDs := []string{subscriberOld, subscriberNew, outsiderOld, outsiderNew, communityDID}
+
+	getComments := func(t *testing.T, level string) map[string]bool {
+		commentRepo := newMockCommentRepo()
+		userRepo := newMockUserRepo()
+		postRepo := newMockPostRepo()
+		communityRepo := newMockCommunityRepo()
+		_ = postRepo.Create(context.Background(), createTestPost(postURI, authorDID, communityDID))
+
+		community := createTestCommunity(communityDID, "c-test.coves.social")
+		community.CollapseThreshold = communities.DefaultCollapseThreshold
+		community.CrowdControl = level
+		_, _ = communityRepo.Create(context.Background(), community)
+		communityRepo.subscribers[subscriberOld+"|"+communityDID] = true
+		communityRepo.subscribers[subscriberNew+"|"+communityDID] = true
+
+		for _, did := range []string{subscriberOld, outsiderOld} {
+			userRepo.users[did] = createTestUser(did, "old.test")
+		}
+		for _, did := range []string{subscriberNew, outsiderNew} {
+			user := createTestUser(did, "new.test")
+			user.CreatedAt = time.Now().Add(-time.Hour)
+			userRepo.users[did] = user
+		}
+
+		var comments []*Comment
+		for _, did := range authorDIDs {
+			comments = append(comments, createTestComment("at://"+did+"/comment/1", did, "author.test", postURI, postURI, 0))
+		}
+		commentRepo.listByParentWithHotRankFunc = func(ctx context.Context, parentURI, sort, timeframe string, limit int, cursor *string) ([]*Comment, *string, error) {
+			return comments, nil, nil
+		}
+
+		service := NewCommentService(commentRepo, userRepo, postRepo, communityRepo, nil, nil, nil)
+		resp, err := service.GetComments(context.Background(), &GetCommentsRequest{
+			PostURI: postURI, Sort: "new", Depth: 10, Limit: 50,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Comments, len(authorDIDs))
+
+		collapsed := make(map[string]bool)
+		for _, thread := range resp.Comments {
+			collapsed[thread.Comment.Author.DID] = thread.Comment.Collapsed
+		}
+		return collapsed
+	}
+
+	t.Run("off", func(t *testing.T) {
+		collapsed := getComments(t, communities.CrowdControlOff)
+		for _, did := range authorDIDs {
+			assert.False(t, collapsed[did], did)
+		}
+	})
+
+	t.Run("low collapses new accounts that aren't subscribed", func(t *testing.T) {
+		collapsed := getComments(t, communities.CrowdControlLow)
+		assert.False(t, collapsed[subscriberOld])
+		assert.False(t, collapsed[subscriberNew])
+		assert.False(t, collapsed[outsiderOld])
+		assert.True(t, collapsed[outsiderNew])
+		assert.False(t, collapsed[communityDID], "the community's own comments are exempt")
+	})
+
+	t.Run("high collapses non-subscribers and new accounts", func(t *testing.T) {
+		collapsed := getComments(t, communities.CrowdControlHigh)
+		assert.False(t, collapsed[subscriberOld])
+		assert.True(t, collapsed[subscriberNew])
+		assert.True(t, collapsed[outsiderOld])
+		assert.True(t, collapsed[outsiderNew])
+		assert.False(t, collapsed[communityDID], "the community's own comments are exempt")
+	})
+}
+
+func TestCommentService_GetComments_ThreadMeta(t *testing.T) {
+	ctx := context.Background()
+	postURI := "at://did:plc:post123/app.bsky.feed.post/test"
+
+	commentRepo := newMockCommentRepo()
+	postRepo := newMockPostRepo()
+	post := createTestPost(postURI, "did:plc:author123", "did:plc:community123")
+	post.Locked = true
+	_ = postRepo.Create(ctx, post)
+
+	// Alice comments twice and Bob once; Carol's comment is deleted, Dave's removed by a
+	// moderator, Erin's rejected, and a comment on another post doesn't count
+	addComment := func(name, commenterDID, rootURI string) *Comment {
+		comment := createTestComment("at://"+commenterDID+"/comment/"+name, commenterDID, name+".test", rootURI, rootURI, 0)
+		_ = commentRepo.Create(ctx, comment)
+		return comment
+	}
+	addComment("alice1", "did:plc:alice", postURI)
+	addComment("alice2", "did:plc:alice", postURI)
+	addComment("bob", "did:plc:bob", postURI)
+	_ = commentRepo.SoftDeleteWithReason(ctx, addComment("carol", "did:plc:carol", postURI).URI, DeletionReasonAuthor, "did:plc:carol")
+	_ = commentRepo.SoftDeleteWithReason(ctx, addComment("dave", "did:plc:dave", postURI).URI, DeletionReasonModerator, "did:plc:mod")
+	addComment("erin", "did:plc:erin", postURI).Status = StatusRejected
+	addComment("frank", "did:plc:frank", "at://did:plc:post123/app.bsky.feed.post/other")
+
+	service := NewCommentService(commentRepo, newMockUserRepo(), postRepo, newMockCommunityRepo(), nil, nil, nil)
+	getMeta := func() *ThreadMeta {
+		resp, err := service.GetComments(ctx, &GetCommentsRequest{PostURI: postURI, Sort: "new", Depth: 10, Limit: 50})
+		require.NoError(t, err)
+		return resp.Meta
+	}
+
+	meta := getMeta()
+	require.NotNil(t, meta)
+	assert.Equal(t, 3, meta.TotalComments, "deleted, removed and rejected comments are excluded")
+	assert.Equal(t, 2, meta.Participants)
+	assert.True(t, meta.Locked)
+
+	// The counts are cached; the locked flag isn't
+	addComment("bob2", "did:plc:bob", postURI)
+	post.Locked = false
+	meta = getMeta()
+	assert.Equal(t, 3, meta.TotalComments)
+	assert.False(t, meta.Locked)
+	assert.Equal(t, 1, commentRepo.countByRootCalls)
+
+	// The permalink view shares the cache
+	resp, err := service.GetCommentThread(ctx, &GetCommentThreadRequest{CommentURI: "at://did:plc:bob/comment/bob"})
+	require.NoError(t, err)
+	assert.Equal(t, meta, resp.Meta)
+	assert.Equal(t, 1, commentRepo.countByRootCalls)
+
+	// Once the entry expires the new comment is counted
+	cache := service.(*commentService).threadMetaCache
+	cache.now = func() time.Time { return time.Now().Add(ThreadMetaCacheTTL) }
+	meta = getMeta()
+	assert.Equal(t, 4, meta.TotalComments)
+	assert.Equal(t, 2, meta.Participants)
+	assert.Equal(t, 2, commentRepo.countByRootCalls)
+}
+
+// mockThreadMuteChecker reports the threads each viewer muted, keyed by viewer then root URI
+type mockThreadMuteChecker map[string]map[string]bool
+
+func (m mockThreadMuteChecker) IsThreadMuted(ctx context.Context, userDID, rootURI string) (bool, error) {
+	return m[userDID][rootURI], nil
+}
+
+func TestCommentService_GetComments_ThreadMetaViewerMute(t *testing.T) {
+	ctx := context.Background()
+	postURI := "at://did:plc:community123/social.coves.community.post/muted"
+
+	commentRepo := newMockCommentRepo()
+	postRepo := newMockPostRepo()
+	_ = postRepo.Create(ctx, createTestPost(postURI, "did:plc:author123", "did:plc:community123"))
+	_ = commentRepo.Create(ctx, createTestComment("at://did:plc:alice/comment/1", "did:plc:alice", "alice.test", postURI, postURI, 0))
+
+	service := NewCommentService(commentRepo, newMockUserRepo(), postRepo, newMockCommunityRepo(), nil, nil, nil)
+	getMeta := func(viewerDID *string) *ThreadMeta {
+		resp, err := service.GetComments(ctx, &GetCommentsRequest{PostURI: postURI, ViewerDID: viewerDID, Sort: "new", Depth: 10, Limit: 50})
+		require.NoError(t, err)
+		require.NotNil(t, resp.Meta)
+		return resp.Meta
+	}
+	muter, other := "did:plc:muter", "did:plc:other"
+
+	assert.Nil(t, getMeta(&muter).Viewer, "no viewer state without a mute checker")
+
+	service.(interface {
+		SetThreadMutes(notifications.ThreadMuteChecker)
+	}).SetThreadMutes(mockThreadMuteChecker{muter: {postURI: true}})
+
+	require.NotNil(t, getMeta(&muter).Viewer)
+	assert.True(t, getMeta(&muter).Viewer.ThreadMuted)
+	require.NotNil(t, getMeta(&other).Viewer)
+	assert.False(t, getMeta(&other).Viewer.ThreadMuted)
+	assert.Nil(t, getMeta(nil).Viewer, "anonymous viewers get no viewer state")
+}
+
+// mockTakedownChecker reports the active takedown reason by URI
+type mockTakedownChecker map[string]takedown.Reason
+
+func (m mockTakedownChecker) GetActive(ctx context.Context, uri string) (*takedown.Takedown, error) {
+	reason, ok := m[uri]
+	if !ok {
+		return nil, takedown.ErrTakedownNotFound
+	}
+	return &takedown.Takedown{SubjectURI: uri, Reason: reason, Note: "admin-only detail"}, nil
+}
+
+func TestCommentService_TakenDownRecords(t *testing.T) {
+	ctx := context.Background()
+	postURI := "at://did:plc:community123/social.coves.community.post/down"
+	commentURI := "at://did:plc:alice/social.coves.community.comment/down"
+
+	commentRepo := newMockCommentRepo()
+	postRepo := newMockPostRepo()
+	_ = postRepo.Create(ctx, createTestPost(postURI, "did:plc:author123", "did:plc:community123"))
+	_ = commentRepo.Create(ctx, createTestComment(commentURI, "did:plc:alice", "alice.test", postURI, postURI, 0))
+
+	service := NewCommentService(commentRepo, newMockUserRepo(), postRepo, newMockCommunityRepo(), nil, nil, nil)
+	service.(interface{ SetTakedowns(takedown.Checker) }).SetTakedowns(mockTakedownChecker{postURI: takedown.ReasonCopyright})
+
+	_, err := service.GetComments(ctx, &GetCommentsRequest{PostURI: postURI, Sort: "new", Depth: 10, Limit: 50})
+	var takenDown *takedown.TakenDownError
+	require.ErrorAs(t, err, &takenDown)
+	assert.Equal(t, takedown.ReasonCopyright, takenDown.Reason)
+	assert.NotContains(t, err.Error(), "admin-only detail", "only the reason category is reported")
+
+	// A comment permalink under a taken-down post is taken down with it
+	_, err = service.GetCommentThread(ctx, &GetCommentThreadRequest{CommentURI: commentURI, Sort: "new", Depth: 10, Limit: 50})
+	require.ErrorIs(t, err, takedown.ErrRecordTakenDown)
+
+	service.(interface{ SetTakedowns(takedown.Checker) }).SetTakedowns(mockTakedownChecker{commentURI: takedown.ReasonAbuse})
+	_, err = service.GetCommentThread(ctx, &GetCommentThreadRequest{CommentURI: commentURI, Sort: "new", Depth: 10, Limit: 50})
+	require.ErrorAs(t, err, &takenDown)
+	assert.Equal(t, takedown.ReasonAbuse, takenDown.Reason)
+
+	_, err = service.GetComments(ctx, &GetCommentsRequest{PostURI: postURI, Sort: "new", Depth: 10, Limit: 50})
+	require.NoError(t, err, "the post itself isn't taken down")
+}
@@ -3,6 +3,8 @@ package comments
 import (
 	"Coves/internal/atproto/pds"
 	"Coves/internal/core/blobs"
+	"Coves/internal/core/posts"
+	"Coves/internal/lexicon/validate"
 	"context"
 	"errors"
 	"fmt"
@@ -131,6 +133,10 @@ func (m *mockPDSClient) PutRecord(ctx context.Context, collection, rkey string,
 	return uri, cid, nil
 }
 
+func (m *mockPDSClient) ApplyWrites(ctx context.Context, repo string, writes []pds.WriteOp) ([]pds.WriteResult, error) {
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockPDSClient) UploadBlob(ctx context.Context, data []byte, mimeType string) (*blobs.BlobRef, error) {
 	// Return a mock blob reference - comments don't use blob uploads
 	return &blobs.BlobRef{
@@ -508,6 +514,41 @@ func TestCreateComment_InvalidReplyParentCID(t *testing.T) {
 	}
 }
 
+func TestCreateComment_ThreadLocked(t *testing.T) {
+	ctx := context.Background()
+	mockClient := newMockPDSClient("did:plc:test123")
+	factory := &mockPDSClientFactory{client: mockClient}
+
+	rootURI := "at://did:plc:author/social.coves.community.post/root123"
+	postRepo := newMockPostRepo()
+	postRepo.posts[rootURI] = &posts.Post{URI: rootURI, Locked: true}
+
+	service := NewCommentServiceWithPDSFactory(
+		newMockCommentRepo(),
+		newMockUserRepo(),
+		postRepo,
+		newMockCommunityRepo(),
+		nil,
+		factory.create,
+	)
+
+	req := CreateCommentRequest{
+		Reply: ReplyRef{
+			Root:   StrongRef{URI: rootURI, CID: "bafyroot"},
+			Parent: StrongRef{URI: rootURI, CID: "bafyroot"},
+		},
+		Content: "Too late",
+	}
+
+	_, err := service.CreateComment(ctx, createTestSession("did:plc:test123"), req)
+	if !errors.Is(err, ErrThreadLocked) {
+		t.Fatalf("Expected ErrThreadLocked, got: %v", err)
+	}
+	if len(mockClient.records[commentCollection]) != 0 {
+		t.Error("Expected no record to be written to the PDS for a locked thread")
+	}
+}
+
 func TestCreateComment_PDSError(t *testing.T) {
 	// Setup
 	ctx := context.Background()
@@ -1165,10 +1206,10 @@ func TestCreateComment_GraphemeCounting(t *testing.T) {
 		factory.create,
 	)
 
-	// Flag emoji 🇺🇸 is 2 runes but 1 grapheme
-	// 10000 flag emojis = 10000 graphemes but 20000 runes
+	// x with a combining acute accent is 2 runes but 1 grapheme (it has no precomposed form)
+	// 10000 of them = 10000 graphemes but 20000 runes, and exactly the lexicon's 30000 bytes
 	// This should succeed because we count graphemes
-	content := strings.Repeat("🇺🇸", 10000)
+	content := strings.Repeat("x\u0301", 10000)
 
 	req := CreateCommentRequest{
 		Reply: ReplyRef{
@@ -1213,7 +1254,9 @@ func TestCreateComment_GraphemeCounting(t *testing.T) {
 		t.Errorf("Expected ErrContentTooLong for 10001 graphemes, got: %v", err)
 	}
 
-	// Also test emoji with skin tone modifier: 👋🏽 is 2 runes but 1 grapheme
+	// Emoji with skin tone modifier: 👋🏽 is 2 runes but 1 grapheme, so 10000 of them pass the
+	// grapheme limit; at 80000 bytes they break the lexicon's byte limit the consumer enforces,
+	// so the record is refused before it is written
 	contentWithSkinTone := strings.Repeat("👋🏽", 10000)
 	reqWithSkinTone := CreateCommentRequest{
 		Reply: ReplyRef{
@@ -1230,7 +1273,8 @@ func TestCreateComment_GraphemeCounting(t *testing.T) {
 	}
 
 	_, err = service.CreateComment(ctx, session, reqWithSkinTone)
-	if err != nil {
-		t.Errorf("Expected success for 10000 graphemes with skin tone modifier, got error: %v", err)
+	var recordErr *validate.Error
+	if !errors.As(err, &recordErr) || recordErr.Violations[0].Field != "content" {
+		t.Errorf("Expected an invalid record error for 10000 graphemes over the byte limit, got: %v", err)
 	}
 }
@@ -0,0 +1,28 @@
+package comments
+
+// ConsumerMetrics counts how often the comment consumer had to repair Jetstream ordering
+// Counters are per process and reset on restart.
+type ConsumerMetrics struct {
+	Indexed             int64 `json:"indexed"`             // Comments inserted or resurrected
+	ReconciledParents   int64 `json:"reconciledParents"`   // Parents indexed after some of their replies
+	OrphansCreated      int64 `json:"orphansCreated"`      // Comments indexed before their root post
+	OrphansResolved     int64 `json:"orphansResolved"`     // Orphaned comments made visible once their root post was indexed
+	ThreadingRejections int64 `json:"threadingRejections"` // Updates refused for changing root or parent
+	Resurrections       int64 `json:"resurrections"`       // Deleted comments recreated with the same rkey
+
+	// ReconcileLag is how long the earliest reply waited for its parent, per reconciled parent
+	ReconcileLag LagHistogram `json:"reconcileLag"`
+}
+
+// LagHistogram is a cumulative histogram of durations
+type LagHistogram struct {
+	Buckets []LagBucket `json:"buckets"` // Ascending; the last bucket is "+Inf" and equals Count
+	Count   int64       `json:"count"`
+	SumMs   int64       `json:"sumMs"`
+}
+
+// LagBucket counts observations at or below an upper bound
+type LagBucket struct {
+	LE    string `json:"le"` // Upper bound, e.g. "1s", or "+Inf"
+	Count int64  `json:"count"`
+}
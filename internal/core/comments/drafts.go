@@ -0,0 +1,173 @@
+package comments
+
+import (
+	"Coves/internal/atproto/aturi"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/uniseg"
+)
+
+const (
+	// DraftTTL is how long a draft is kept after its last save
+	DraftTTL = 30 * 24 * time.Hour
+
+	// maxDraftBytes bounds a draft's size; a grapheme can be many bytes, so the grapheme
+	// cap alone would let a draft grow far past what a comment could hold
+	maxDraftBytes = maxCommentGraphemes * 10
+
+	// draftPostCollection is the collection of posts, which drafts may reply to directly
+	draftPostCollection = "social.coves.community.post"
+)
+
+// Draft is an unsent comment, saved server-side so it survives a crashed tab
+// A user has at most one draft per context: the post or comment being replied to.
+type Draft struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	UserDID   string    `json:"-"`
+	Context   string    `json:"context"`
+	Content   string    `json:"content"`
+}
+
+// PutDraftRequest saves the caller's draft for a context
+// Empty content discards the draft.
+type PutDraftRequest struct {
+	UserDID string `json:"-"`
+	Context string `json:"context"`
+	Content string `json:"content"`
+}
+
+// GetDraftRequest fetches the caller's draft for a context
+type GetDraftRequest struct {
+	UserDID string
+	Context string
+}
+
+// DraftDeleter discards drafts
+// CreateComment uses it to discard the draft of a comment once it's written.
+type DraftDeleter interface {
+	// DeleteDraft discards the user's draft for the context; deleting a missing draft is a no-op
+	DeleteDraft(ctx context.Context, userDID, contextURI string) error
+}
+
+// DraftRepository stores drafts
+// Implemented by postgres.NewDraftRepository.
+type DraftRepository interface {
+	DraftDeleter
+
+	// PutDraft creates or replaces the user's draft for the context
+	PutDraft(ctx context.Context, draft *Draft) error
+
+	// GetDraft returns the user's draft for the context, or ErrDraftNotFound
+	GetDraft(ctx context.Context, userDID, contextURI string) (*Draft, error)
+
+	// DeleteDraftsBefore discards drafts last saved before cutoff and returns how many
+	DeleteDraftsBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// DraftService saves and restores comment drafts
+type DraftService interface {
+	// PutDraft saves the draft, replacing any earlier one for the same context
+	PutDraft(ctx context.Context, req PutDraftRequest) (*Draft, error)
+
+	// GetDraft returns the caller's draft for the context, or ErrDraftNotFound
+	GetDraft(ctx context.Context, req GetDraftRequest) (*Draft, error)
+
+	// PruneExpiredDrafts discards drafts older than DraftTTL and returns how many
+	PruneExpiredDrafts(ctx context.Context) (int64, error)
+}
+
+type draftService struct {
+	repo DraftRepository
+	now  func() time.Time
+}
+
+// NewDraftService creates a draft service storing drafts in repo
+func NewDraftService(repo DraftRepository) DraftService {
+	return &draftService{repo: repo, now: time.Now}
+}
+
+// PutDraft saves req.Content as the caller's draft for req.Context
+func (s *draftService) PutDraft(ctx context.Context, req PutDraftRequest) (*Draft, error) {
+	contextURI, err := validateDraftContext(req.UserDID, req.Context)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Content) > maxDraftBytes || uniseg.GraphemeClusterCount(req.Content) > maxCommentGraphemes {
+		return nil, ErrContentTooLong
+	}
+
+	draft := &Draft{UserDID: req.UserDID, Context: contextURI, Content: req.Content, UpdatedAt: s.now().UTC()}
+	if strings.TrimSpace(req.Content) == "" {
+		if err := s.repo.DeleteDraft(ctx, req.UserDID, contextURI); err != nil {
+			return nil, fmt.Errorf("failed to discard draft: %w", err)
+		}
+		return draft, nil
+	}
+	if err := s.repo.PutDraft(ctx, draft); err != nil {
+		return nil, fmt.Errorf("failed to save draft: %w", err)
+	}
+	return draft, nil
+}
+
+// GetDraft returns the caller's draft for req.Context
+// A draft past DraftTTL is not found even before the pruner has discarded it.
+func (s *draftService) GetDraft(ctx context.Context, req GetDraftRequest) (*Draft, error) {
+	contextURI, err := validateDraftContext(req.UserDID, req.Context)
+	if err != nil {
+		return nil, err
+	}
+	draft, err := s.repo.GetDraft(ctx, req.UserDID, contextURI)
+	if err != nil {
+		return nil, err
+	}
+	if draft.UpdatedAt.Before(s.now().Add(-DraftTTL)) {
+		return nil, ErrDraftNotFound
+	}
+	return draft, nil
+}
+
+// PruneExpiredDrafts discards drafts not saved for DraftTTL
+func (s *draftService) PruneExpiredDrafts(ctx context.Context) (int64, error) {
+	pruned, err := s.repo.DeleteDraftsBefore(ctx, s.now().Add(-DraftTTL))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune expired drafts: %w", err)
+	}
+	return pruned, nil
+}
+
+// validateDraftContext checks the draft's owner and context, returning the context in
+// canonical form so the draft CreateComment discards is the one the client saved
+func validateDraftContext(userDID, contextURI string) (string, error) {
+	if userDID == "" {
+		return "", ErrNotAuthorized
+	}
+	parsed, err := aturi.Parse(contextURI)
+	if err != nil || parsed.RKey == "" || !strings.HasPrefix(parsed.Authority, "did:") ||
+		(parsed.Collection != draftPostCollection && parsed.Collection != commentCollection) {
+		return "", ErrInvalidDraftContext
+	}
+	return parsed.String(), nil
+}
+
+// SetDrafts enables discarding a comment's draft once the comment is written
+func (s *commentService) SetDrafts(drafts DraftDeleter) {
+	s.drafts = drafts
+}
+
+// discardDraft discards the commenter's draft for the comment's parent
+// Failures are logged: the comment is already written, and the draft expires eventually.
+func (s *commentService) discardDraft(ctx context.Context, userDID, parentURI string) {
+	if s.drafts == nil {
+		return
+	}
+	contextURI, err := validateDraftContext(userDID, parentURI)
+	if err != nil {
+		return
+	}
+	if err := s.drafts.DeleteDraft(ctx, userDID, contextURI); err != nil {
+		s.logger.Warn("failed to discard comment draft", "commenter", userDID, "context", contextURI, "error", err)
+	}
+}
@@ -0,0 +1,193 @@
+package comments
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryDrafts keeps drafts in a map keyed by user and context
+type memoryDrafts map[[2]string]Draft
+
+func (m memoryDrafts) PutDraft(ctx context.Context, draft *Draft) error {
+	m[[2]string{draft.UserDID, draft.Context}] = *draft
+	return nil
+}
+
+func (m memoryDrafts) GetDraft(ctx context.Context, userDID, contextURI string) (*Draft, error) {
+	draft, ok := m[[2]string{userDID, contextURI}]
+	if !ok {
+		return nil, ErrDraftNotFound
+	}
+	return &draft, nil
+}
+
+func (m memoryDrafts) DeleteDraft(ctx context.Context, userDID, contextURI string) error {
+	delete(m, [2]string{userDID, contextURI})
+	return nil
+}
+
+func (m memoryDrafts) DeleteDraftsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var deleted int64
+	for key, draft := range m {
+		if draft.UpdatedAt.Before(cutoff) {
+			delete(m, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+const draftPostURI = "at://did:plc:community/social.coves.community.post/3kroot"
+
+func newTestDraftService(repo memoryDrafts, now *time.Time) DraftService {
+	return &draftService{repo: repo, now: func() time.Time { return *now }}
+}
+
+func TestDraftService_PutOverwrites(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := memoryDrafts{}
+	service := newTestDraftService(repo, &now)
+
+	if _, err := service.PutDraft(ctx, PutDraftRequest{UserDID: "did:plc:me", Context: draftPostURI, Content: "First thoughts"}); err != nil {
+		t.Fatalf("PutDraft failed: %v", err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := service.PutDraft(ctx, PutDraftRequest{UserDID: "did:plc:me", Context: draftPostURI, Content: "Second thoughts"}); err != nil {
+		t.Fatalf("PutDraft failed: %v", err)
+	}
+	if len(repo) != 1 {
+		t.Fatalf("Expected one draft per context, got %d", len(repo))
+	}
+
+	draft, err := service.GetDraft(ctx, GetDraftRequest{UserDID: "did:plc:me", Context: draftPostURI})
+	if err != nil {
+		t.Fatalf("GetDraft failed: %v", err)
+	}
+	if draft.Content != "Second thoughts" || !draft.UpdatedAt.Equal(now) {
+		t.Errorf("Expected the latest save, got %+v", draft)
+	}
+	if _, err := service.GetDraft(ctx, GetDraftRequest{UserDID: "did:plc:other", Context: draftPostURI}); !errors.Is(err, ErrDraftNotFound) {
+		t.Errorf("Expected drafts to be private to their user, got %v", err)
+	}
+
+	// Clearing the text box discards the draft
+	if _, err := service.PutDraft(ctx, PutDraftRequest{UserDID: "did:plc:me", Context: draftPostURI, Content: "  "}); err != nil {
+		t.Fatalf("PutDraft failed: %v", err)
+	}
+	if len(repo) != 0 {
+		t.Errorf("Expected an empty draft to be discarded, got %d drafts", len(repo))
+	}
+}
+
+func TestDraftService_Expiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := memoryDrafts{}
+	service := newTestDraftService(repo, &now)
+
+	commentURI := "at://did:plc:alice/social.coves.community.comment/3kreply"
+	if _, err := service.PutDraft(ctx, PutDraftRequest{UserDID: "did:plc:me", Context: draftPostURI, Content: "Old"}); err != nil {
+		t.Fatalf("PutDraft failed: %v", err)
+	}
+	now = now.Add(DraftTTL - time.Hour)
+	if _, err := service.PutDraft(ctx, PutDraftRequest{UserDID: "did:plc:me", Context: commentURI, Content: "Recent"}); err != nil {
+		t.Fatalf("PutDraft failed: %v", err)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := service.GetDraft(ctx, GetDraftRequest{UserDID: "did:plc:me", Context: draftPostURI}); !errors.Is(err, ErrDraftNotFound) {
+		t.Errorf("Expected an expired draft to be hidden before pruning, got %v", err)
+	}
+
+	pruned, err := service.PruneExpiredDrafts(ctx)
+	if err != nil {
+		t.Fatalf("PruneExpiredDrafts failed: %v", err)
+	}
+	if pruned != 1 {
+		t.Errorf("Expected 1 expired draft pruned, got %d", pruned)
+	}
+	if _, err := service.GetDraft(ctx, GetDraftRequest{UserDID: "did:plc:me", Context: commentURI}); err != nil {
+		t.Errorf("Expected the recent draft to survive pruning, got %v", err)
+	}
+}
+
+func TestDraftService_Validation(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := memoryDrafts{}
+	service := newTestDraftService(repo, &now)
+
+	tests := []struct {
+		name string
+		req  PutDraftRequest
+		want error
+	}{
+		{"no user", PutDraftRequest{Context: draftPostURI, Content: "Hi"}, ErrNotAuthorized},
+		{"not an AT-URI", PutDraftRequest{UserDID: "did:plc:me", Context: "https://example.com/post", Content: "Hi"}, ErrInvalidDraftContext},
+		{"a profile", PutDraftRequest{UserDID: "did:plc:me", Context: "at://did:plc:alice/social.coves.actor.profile/self", Content: "Hi"}, ErrInvalidDraftContext},
+		{"handle authority", PutDraftRequest{UserDID: "did:plc:me", Context: "at://gaming.coves.social/social.coves.community.post/3kroot", Content: "Hi"}, ErrInvalidDraftContext},
+		{"too many graphemes", PutDraftRequest{UserDID: "did:plc:me", Context: draftPostURI, Content: strings.Repeat("a", maxCommentGraphemes+1)}, ErrContentTooLong},
+		{"too many bytes", PutDraftRequest{UserDID: "did:plc:me", Context: draftPostURI, Content: "a" + strings.Repeat("́", maxDraftBytes)}, ErrContentTooLong},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.PutDraft(ctx, tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("PutDraft: expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+	if len(repo) != 0 {
+		t.Errorf("Expected no drafts saved, got %d", len(repo))
+	}
+}
+
+func TestCreateComment_DiscardsDraft(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	drafts := memoryDrafts{}
+	draftService := newTestDraftService(drafts, &now)
+
+	commentURI := "at://did:plc:alice/social.coves.community.comment/3kreply"
+	for _, contextURI := range []string{draftPostURI, commentURI} {
+		if _, err := draftService.PutDraft(ctx, PutDraftRequest{UserDID: "did:plc:test123", Context: contextURI, Content: "Draft"}); err != nil {
+			t.Fatalf("PutDraft failed: %v", err)
+		}
+	}
+
+	mockClient := newMockPDSClient("did:plc:test123")
+	factory := &mockPDSClientFactory{client: mockClient}
+	service := NewCommentServiceWithPDSFactory(newMockCommentRepo(), newMockUserRepo(), newMockPostRepo(), newMockCommunityRepo(), nil, factory.create)
+	service.(*commentService).SetDrafts(drafts)
+
+	req := CreateCommentRequest{
+		Reply: ReplyRef{
+			Root:   StrongRef{URI: draftPostURI, CID: "bafyroot"},
+			Parent: StrongRef{URI: commentURI, CID: "bafyparent"},
+		},
+		Content: "Draft",
+	}
+
+	// A failed write keeps the draft
+	mockClient.createError = errors.New("PDS connection failed")
+	if _, err := service.CreateComment(ctx, createTestSession("did:plc:test123"), req); err == nil {
+		t.Fatal("Expected the PDS error")
+	}
+	if len(drafts) != 2 {
+		t.Fatalf("Expected drafts to survive a failed comment, got %d", len(drafts))
+	}
+
+	mockClient.createError = nil
+	if _, err := service.CreateComment(ctx, createTestSession("did:plc:test123"), req); err != nil {
+		t.Fatalf("CreateComment failed: %v", err)
+	}
+	if _, ok := drafts[[2]string{"did:plc:test123", commentURI}]; ok {
+		t.Error("Expected the reply's draft to be discarded")
+	}
+	if _, ok := drafts[[2]string{"did:plc:test123", draftPostURI}]; !ok {
+		t.Error("Expected the draft for the post itself to be kept")
+	}
+}
@@ -1,37 +1,62 @@
 package comments
 
-import "errors"
+import (
+	"errors"
+
+	coreerrors "Coves/internal/core/errors"
+)
 
 var (
 	// ErrCommentNotFound indicates the requested comment doesn't exist
-	ErrCommentNotFound = errors.New("comment not found")
+	ErrCommentNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "comment not found")
 
 	// ErrInvalidReply indicates the reply reference is malformed or invalid
-	ErrInvalidReply = errors.New("invalid reply reference")
+	ErrInvalidReply = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid reply reference")
 
 	// ErrParentNotFound indicates the parent post/comment doesn't exist
-	ErrParentNotFound = errors.New("parent post or comment not found")
+	ErrParentNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "parent post or comment not found")
 
 	// ErrRootNotFound indicates the root post doesn't exist
-	ErrRootNotFound = errors.New("root post not found")
+	ErrRootNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "root post not found")
 
 	// ErrContentTooLong indicates comment content exceeds 10000 graphemes
-	ErrContentTooLong = errors.New("comment content exceeds 10000 graphemes")
+	ErrContentTooLong = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "comment content exceeds 10000 graphemes")
 
 	// ErrContentEmpty indicates comment content is empty
-	ErrContentEmpty = errors.New("comment content is required")
+	ErrContentEmpty = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "comment content is required")
 
 	// ErrNotAuthorized indicates the user is not authorized to perform this action
-	ErrNotAuthorized = errors.New("not authorized")
+	ErrNotAuthorized = coreerrors.Sentinel(coreerrors.ErrForbidden, "not authorized")
 
 	// ErrBanned indicates the user is banned from the community
-	ErrBanned = errors.New("user is banned from this community")
+	ErrBanned = coreerrors.Sentinel(coreerrors.ErrForbidden, "user is banned from this community")
+
+	// ErrThreadLocked indicates the root post is locked and accepts no new comments
+	ErrThreadLocked = coreerrors.Sentinel(coreerrors.ErrForbidden, "thread is locked")
 
 	// ErrCommentAlreadyExists indicates a comment with this URI already exists
-	ErrCommentAlreadyExists = errors.New("comment already exists")
+	ErrCommentAlreadyExists = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "comment already exists")
 
 	// ErrConcurrentModification indicates the comment was modified since it was loaded
 	ErrConcurrentModification = errors.New("comment was modified by another operation")
+
+	// ErrStaleRevision indicates a firehose update is older than the indexed version of the comment
+	ErrStaleRevision = errors.New("comment update is older than the indexed revision")
+
+	// ErrInvalidSearch indicates a comment search is missing its community or query
+	ErrInvalidSearch = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid search request")
+
+	// ErrInvalidRequest indicates a read request failed validation; wraps the specific problem
+	ErrInvalidRequest = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid request")
+
+	// ErrDraftNotFound indicates the user has no draft for the context
+	ErrDraftNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "draft not found")
+
+	// ErrInvalidDraftContext indicates a draft's context isn't the AT-URI of a post or comment
+	ErrInvalidDraftContext = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "context must be the AT-URI of a post or comment")
+
+	// ErrInvalidCursor indicates a pagination cursor was malformed or not signed by this instance
+	ErrInvalidCursor = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid pagination cursor")
 )
 
 // IsNotFound checks if an error is a "not found" error
@@ -51,5 +76,7 @@ func IsConflict(err error) bool {
 func IsValidationError(err error) bool {
 	return errors.Is(err, ErrInvalidReply) ||
 		errors.Is(err, ErrContentTooLong) ||
-		errors.Is(err, ErrContentEmpty)
+		errors.Is(err, ErrContentEmpty) ||
+		errors.Is(err, ErrInvalidSearch) ||
+		errors.Is(err, ErrInvalidRequest)
 }
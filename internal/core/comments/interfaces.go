@@ -22,10 +22,14 @@ type Repository interface {
 	// Preserves vote counts and created_at timestamp
 	Update(ctx context.Context, comment *Comment) error
 
-	// GetByURI retrieves a comment by its AT-URI
+	// GetByURI retrieves a non-deleted comment by its AT-URI
 	// Used for Jetstream UPDATE/DELETE operations and queries
 	GetByURI(ctx context.Context, uri string) (*Comment, error)
 
+	// GetByURIWithDeleted retrieves a comment by its AT-URI, including soft-deleted comments
+	// Used by permalink views, which show deleted comments as "[deleted]" stubs
+	GetByURIWithDeleted(ctx context.Context, uri string) (*Comment, error)
+
 	// Delete soft-deletes a comment (sets deleted_at)
 	// Called by Jetstream consumer after comment is deleted from PDS
 	// Deprecated: Use SoftDeleteWithReason for new code to preserve thread structure
@@ -37,18 +41,32 @@ type Repository interface {
 	// deletedByDID: DID of the actor who performed the deletion
 	SoftDeleteWithReason(ctx context.Context, uri, reason, deletedByDID string) error
 
-	// ListByRoot retrieves all comments in a thread (flat)
-	// Used for fetching entire comment threads on posts
+	// ListByRoot retrieves all non-deleted comments in a thread (flat)
+	// Deprecated: Use ListByRootWithCursor for cursor-based pagination
 	ListByRoot(ctx context.Context, rootURI string, limit, offset int) ([]*Comment, error)
 
-	// ListByParent retrieves direct replies to a post or comment
-	// Used for building nested/threaded comment views
+	// ListByRootWithCursor pages through all non-deleted comments in a thread (flat), oldest first
+	// Used for fetching entire comment threads on posts; returns the next page cursor
+	ListByRootWithCursor(ctx context.Context, rootURI string, limit int, cursor *string) ([]*Comment, *string, error)
+
+	// ListByParent retrieves non-deleted direct replies to a post or comment
+	// Deprecated: Use ListByParentWithCursor for cursor-based pagination
 	ListByParent(ctx context.Context, parentURI string, limit, offset int) ([]*Comment, error)
 
+	// ListByParentWithCursor pages through non-deleted direct replies to a post or comment, oldest first
+	ListByParentWithCursor(ctx context.Context, parentURI string, limit int, cursor *string) ([]*Comment, *string, error)
+
 	// CountByParent counts direct replies to a post or comment
 	// Used for showing reply counts in threading UI
 	CountByParent(ctx context.Context, parentURI string) (int, error)
 
+	// CountByRoot counts a post's visible comments at any depth
+	// Deleted, removed and rejected comments are excluded
+	CountByRoot(ctx context.Context, rootURI string) (int, error)
+
+	// CountParticipantsByRoot counts the distinct commenters among a post's visible comments
+	CountParticipantsByRoot(ctx context.Context, rootURI string) (int, error)
+
 	// ListByCommenter retrieves all comments by a specific user
 	// Deprecated: Use ListByCommenterWithCursor for cursor-based pagination
 	ListByCommenter(ctx context.Context, commenterDID string, limit, offset int) ([]*Comment, error)
@@ -58,37 +76,63 @@ type Repository interface {
 	// Supports optional community filtering and returns next page cursor
 	ListByCommenterWithCursor(ctx context.Context, req ListByCommenterRequest) ([]*Comment, *string, error)
 
+	// Search full-text searches comments on a community's posts, most relevant first
+	// Excludes soft-deleted comments unless req.IncludeDeleted; returns next page cursor
+	Search(ctx context.Context, req SearchRequest) ([]*Comment, *string, error)
+
 	// ListByParentWithHotRank retrieves direct replies to a post or comment with sorting and pagination
-	// Supports hot, top, and new sorting with cursor-based pagination
+	// Supports best, hot, top, and new sorting with cursor-based pagination
 	// Returns comments with author info hydrated and next page cursor
+	// Hidden comments are excluded, except author_only comments when viewerDID is their author
 	ListByParentWithHotRank(
 		ctx context.Context,
 		parentURI string,
-		sort string, // "hot", "top", "new"
+		sort string, // "best", "hot", "top", "new"
 		timeframe string, // "hour", "day", "week", "month", "year", "all" (for "top" only)
 		limit int,
 		cursor *string,
+		viewerDID string, // "" for anonymous viewers
+	) ([]*Comment, *string, error)
+
+	// ListByParentWithHotRankWithDeleted is ListByParentWithHotRank including soft-deleted
+	// (and moderator-removed) replies, which comment trees render as tombstones
+	ListByParentWithHotRankWithDeleted(
+		ctx context.Context,
+		parentURI string,
+		sort string,
+		timeframe string,
+		limit int,
+		cursor *string,
+		viewerDID string,
 	) ([]*Comment, *string, error)
 
-	// GetByURIsBatch retrieves multiple comments by their AT-URIs in a single query
+	// GetByURIsBatch retrieves multiple non-deleted comments by their AT-URIs in a single query
 	// Returns map[uri]*Comment for efficient lookups
-	// Used for hydrating comment threads without N+1 queries
 	GetByURIsBatch(ctx context.Context, uris []string) (map[string]*Comment, error)
 
+	// GetAncestorsWithDeleted retrieves up to maxHeight parent comments of a comment in a single query
+	// Returns ancestors ordered root-first (the direct parent is last)
+	// Includes deleted comments so permalink views can show them as stubs
+	// The walk stops at the root post or at the first parent that hasn't been indexed
+	GetAncestorsWithDeleted(ctx context.Context, commentURI string, maxHeight int) ([]*Comment, error)
+
 	// GetVoteStateForComments retrieves the viewer's votes on a batch of comments
 	// Returns map[commentURI]*Vote for efficient lookups
 	// Future: Used when votes table is implemented
 	GetVoteStateForComments(ctx context.Context, viewerDID string, commentURIs []string) (map[string]interface{}, error)
 
-	// ListByParentsBatch retrieves direct replies to multiple parents in a single query
+	// ListByParentsBatchWithDeleted retrieves direct replies to multiple parents in a single query
 	// Returns map[parentURI][]*Comment grouped by parent
 	// Used to prevent N+1 queries when loading nested replies
+	// Includes deleted comments so nested threads keep their shape ("[deleted]" placeholders)
 	// Limits results per parent to avoid memory exhaustion
-	ListByParentsBatch(
+	// Hidden comments are excluded, except author_only comments when viewerDID is their author
+	ListByParentsBatchWithDeleted(
 		ctx context.Context,
 		parentURIs []string,
 		sort string,
 		limitPerParent int,
+		viewerDID string, // "" for anonymous viewers
 	) (map[string][]*Comment, error)
 }
 
@@ -0,0 +1,155 @@
+package comments
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/core/posts"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+)
+
+// scoreHiding decides which vote counts in a post's thread are hidden from the viewer
+// Everything in a thread belongs to the post's community, so one window applies throughout
+type scoreHiding struct {
+	now       time.Time
+	viewerDID string
+	hours     int
+	moderator bool // Moderators always see real counts
+}
+
+// threadScoreHiding builds the score hiding rules for a post's comment thread
+// The moderator lookup only runs when the community hides scores and the viewer is signed in
+func (s *commentService) threadScoreHiding(ctx context.Context, postView *posts.PostView, viewerDID *string) *scoreHiding {
+	hiding := &scoreHiding{
+		now:       time.Now(),
+		viewerDID: viewerDIDOrEmpty(viewerDID),
+		hours:     postView.ScoreHidingHours,
+	}
+	if hiding.hours == 0 || hiding.viewerDID == "" || postView.Community == nil {
+		return hiding
+	}
+
+	communityDID := postView.Community.DID
+	moderated, err := s.communityRepo.GetModeratedCommunityDIDs(ctx, hiding.viewerDID, []string{communityDID})
+	if err != nil {
+		// Hiding is the safe failure mode
+		slog.Warn("failed to check moderator status for score hiding",
+			"viewer_did", hiding.viewerDID, "community_did", communityDID, "error", err)
+		return hiding
+	}
+	hiding.moderator = moderated[communityDID]
+	return hiding
+}
+
+// hides reports whether counts on content by authorDID created at createdAt are hidden
+func (h *scoreHiding) hides(authorDID string, createdAt time.Time) bool {
+	if h.moderator || (h.viewerDID != "" && authorDID == h.viewerDID) {
+		return false
+	}
+	return communities.ScoresHidden(h.hours, createdAt, h.now)
+}
+
+// hidePost nulls the post's vote counts when they are hidden
+func (h *scoreHiding) hidePost(view *posts.PostView) {
+	if view == nil || view.Stats == nil || view.Author == nil {
+		return
+	}
+	if h.hides(view.Author.DID, view.CreatedAt) {
+		view.Stats.Hide()
+	}
+}
+
+// hideComment nulls a comment's vote counts when they are hidden
+// Deleted comment stubs carry no stats and are skipped
+func (h *scoreHiding) hideComment(view *CommentView) {
+	if view == nil || view.Stats == nil || view.Author == nil || view.IsDeleted {
+		return
+	}
+	createdAt, err := time.Parse(time.RFC3339, view.CreatedAt)
+	if err != nil {
+		return
+	}
+	if h.hides(view.Author.DID, createdAt) {
+		view.Stats.Hide()
+	}
+}
+
+// hideThreads applies hideComment to every comment in the thread trees
+func (h *scoreHiding) hideThreads(threads []*ThreadViewComment) {
+	if h.hours == 0 || h.moderator {
+		return
+	}
+	for _, thread := range threads {
+		h.hideComment(thread.Comment)
+		h.hideThreads(thread.Replies)
+	}
+}
+
+// hideActorCommentScores nulls vote counts on a user's comments inside their communities'
+// score hiding windows. The user sees their own counts; moderators see counts in their communities.
+func (s *commentService) hideActorCommentScores(
+	ctx context.Context,
+	dbComments []*Comment,
+	views []*CommentView,
+	actorDID string,
+	viewerDID *string,
+) {
+	viewer := viewerDIDOrEmpty(viewerDID)
+	if viewer != "" && viewer == actorDID {
+		return
+	}
+
+	now := time.Now()
+	var hiddenCommunities []string
+	seen := make(map[string]bool)
+	for _, comment := range dbComments {
+		if communities.ScoresHidden(comment.ScoreHidingHours, comment.CreatedAt, now) && !seen[comment.CommunityDID] {
+			seen[comment.CommunityDID] = true
+			hiddenCommunities = append(hiddenCommunities, comment.CommunityDID)
+		}
+	}
+	if len(hiddenCommunities) == 0 {
+		return
+	}
+
+	var moderated map[string]bool
+	if viewer != "" {
+		var err error
+		moderated, err = s.communityRepo.GetModeratedCommunityDIDs(ctx, viewer, hiddenCommunities)
+		if err != nil {
+			slog.Warn("failed to check moderator status for score hiding", "viewer_did", viewer, "error", err)
+		}
+	}
+
+	for i, comment := range dbComments {
+		if communities.ScoresHidden(comment.ScoreHidingHours, comment.CreatedAt, now) && !moderated[comment.CommunityDID] {
+			views[i].Stats.Hide()
+		}
+	}
+}
+
+// Hide drops the vote counts so they can't leak and serializes them as null
+func (s *CommentStats) Hide() {
+	s.Upvotes, s.Downvotes, s.Score = 0, 0, 0
+	s.ScoreHidden = true
+}
+
+// MarshalJSON serializes hidden vote counts as null
+// The field order matches CommentStats so responses are unchanged when nothing is hidden
+func (s CommentStats) MarshalJSON() ([]byte, error) {
+	upvotes, downvotes, score := posts.VoteCounts(s.ScoreHidden, s.Upvotes, s.Downvotes, s.Score)
+	return json.Marshal(struct {
+		Upvotes     *int `json:"upvotes"`
+		Downvotes   *int `json:"downvotes"`
+		Score       *int `json:"score"`
+		ReplyCount  int  `json:"replyCount"`
+		ScoreHidden bool `json:"scoreHidden,omitempty"`
+	}{
+		Upvotes:     upvotes,
+		Downvotes:   downvotes,
+		Score:       score,
+		ReplyCount:  s.ReplyCount,
+		ScoreHidden: s.ScoreHidden,
+	})
+}
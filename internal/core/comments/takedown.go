@@ -0,0 +1,31 @@
+package comments
+
+import (
+	"Coves/internal/core/takedown"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// SetTakedowns enables admin takedown checks on direct fetches of posts and comments
+// Without it taken-down records still drop out of listings, but fetching one by URI reports
+// plain not found rather than RecordTakenDown.
+func (s *commentService) SetTakedowns(checker takedown.Checker) {
+	s.takedowns = checker
+}
+
+// checkNotTakenDown returns a TakenDownError, carrying only the reason category, when the
+// record has an active takedown
+func (s *commentService) checkNotTakenDown(ctx context.Context, uri string) error {
+	if s.takedowns == nil {
+		return nil
+	}
+	active, err := s.takedowns.GetActive(ctx, uri)
+	if errors.Is(err, takedown.ErrTakedownNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check takedown of %s: %w", uri, err)
+	}
+	return &takedown.TakenDownError{Reason: active.Reason}
+}
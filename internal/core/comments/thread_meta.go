@@ -0,0 +1,131 @@
+package comments
+
+import (
+	"Coves/internal/core/notifications"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// ThreadMetaCacheTTL is how long a post's comment and participant counts are cached
+	// The comment consumer doesn't invalidate entries: a new comment shows up in the counts
+	// at most this long after it's indexed, which is fine for a header summary.
+	ThreadMetaCacheTTL = 30 * time.Second
+
+	// maxThreadMetaCacheEntries bounds the cache; expired entries are swept once it fills up
+	maxThreadMetaCacheEntries = 10000
+)
+
+// ThreadMeta summarizes a post's discussion for the comment view header
+type ThreadMeta struct {
+	TotalComments int                `json:"totalComments"`    // Visible comments at any depth
+	Participants  int                `json:"participants"`     // Distinct commenters among them
+	Locked        bool               `json:"locked"`           // Locked posts accept no new comments
+	Viewer        *ThreadViewerState `json:"viewer,omitempty"` // Authenticated viewers only
+}
+
+// ThreadViewerState is the viewer's relationship with the thread
+type ThreadViewerState struct {
+	ThreadMuted bool `json:"threadMuted"` // The viewer gets no reply or mention notifications from it
+}
+
+// threadCounts are the cached part of ThreadMeta
+type threadCounts struct {
+	expiresAt     time.Time
+	totalComments int
+	participants  int
+}
+
+// threadMetaCache caches each post's comment and participant counts for ThreadMetaCacheTTL
+type threadMetaCache struct {
+	now     func() time.Time
+	entries map[string]threadCounts
+	loads   singleflight.Group
+	mu      sync.RWMutex
+}
+
+func newThreadMetaCache() *threadMetaCache {
+	return &threadMetaCache{
+		now:     time.Now,
+		entries: make(map[string]threadCounts),
+	}
+}
+
+// SetThreadMutes enables the viewer's thread mute state in thread meta
+func (s *commentService) SetThreadMutes(checker notifications.ThreadMuteChecker) {
+	s.threadMutes = checker
+}
+
+// threadMeta returns the post's thread summary
+// The counts may be up to ThreadMetaCacheTTL stale; the locked flag is read from the post,
+// so locking takes effect immediately, and so is the viewer's mute state. Counting failures
+// are logged and leave meta out; a failed mute lookup leaves out only the viewer state.
+func (s *commentService) threadMeta(ctx context.Context, rootURI string, locked bool, viewerDID *string) *ThreadMeta {
+	counts, err := s.threadMetaCache.load(ctx, rootURI, s.commentRepo)
+	if err != nil {
+		s.logger.Warn("failed to load thread meta", "post", rootURI, "error", err)
+		return nil
+	}
+	meta := &ThreadMeta{
+		TotalComments: counts.totalComments,
+		Participants:  counts.participants,
+		Locked:        locked,
+	}
+
+	if s.threadMutes != nil && viewerDIDOrEmpty(viewerDID) != "" {
+		muted, err := s.threadMutes.IsThreadMuted(ctx, *viewerDID, rootURI)
+		if err != nil {
+			s.logger.Warn("failed to check thread mute", "post", rootURI, "viewer", *viewerDID, "error", err)
+		} else {
+			meta.Viewer = &ThreadViewerState{ThreadMuted: muted}
+		}
+	}
+	return meta
+}
+
+// load returns the post's counts, querying on a miss or expiry
+// Concurrent misses for the same post share one pair of queries.
+func (c *threadMetaCache) load(ctx context.Context, rootURI string, repo Repository) (threadCounts, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[rootURI]
+	c.mu.RUnlock()
+	if ok && c.now().Before(entry.expiresAt) {
+		return entry, nil
+	}
+
+	result, err, _ := c.loads.Do(rootURI, func() (interface{}, error) {
+		total, err := repo.CountByRoot(ctx, rootURI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count comments: %w", err)
+		}
+		participants, err := repo.CountParticipantsByRoot(ctx, rootURI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count participants: %w", err)
+		}
+		return c.put(rootURI, total, participants), nil
+	})
+	if err != nil {
+		return threadCounts{}, err
+	}
+	return result.(threadCounts), nil
+}
+
+func (c *threadMetaCache) put(rootURI string, total, participants int) threadCounts {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if len(c.entries) >= maxThreadMetaCacheEntries {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(c.entries, key)
+			}
+		}
+	}
+	entry := threadCounts{totalComments: total, participants: participants, expiresAt: now.Add(ThreadMetaCacheTTL)}
+	c.entries[rootURI] = entry
+	return entry
+}
@@ -0,0 +1,50 @@
+package comments
+
+import "time"
+
+// Tombstone content shown in place of a deleted comment's text
+const (
+	TombstoneContentDeleted = "[deleted]" // Deleted by its author
+	TombstoneContentRemoved = "[removed]" // Removed by a community moderator
+)
+
+// buildTombstoneView creates the view of a deleted comment inside a comment tree
+// Tombstones exist only so live replies keep their place in the thread: the author is null,
+// the record's content is "[deleted]" or "[removed]", and the reply count is preserved.
+func (s *commentService) buildTombstoneView(comment *Comment) *CommentView {
+	view := s.buildDeletedCommentView(comment)
+	view.Author = nil
+	view.Record = &CommentRecord{
+		Type: commentCollection,
+		Reply: ReplyRef{
+			Root:   StrongRef{URI: comment.RootURI, CID: comment.RootCID},
+			Parent: StrongRef{URI: comment.ParentURI, CID: comment.ParentCID},
+		},
+		Content:   tombstoneContent(comment),
+		CreatedAt: comment.CreatedAt.Format(time.RFC3339),
+	}
+	return view
+}
+
+// tombstoneContent returns "[removed]" for moderator removals and "[deleted]" otherwise
+func tombstoneContent(comment *Comment) string {
+	if comment.DeletionReason != nil && *comment.DeletionReason == DeletionReasonModerator {
+		return TombstoneContentRemoved
+	}
+	return TombstoneContentDeleted
+}
+
+// pruneTombstones drops tombstones with no live descendants from one level of a tree
+// Replies are built bottom-up, so a tombstone whose replies were all pruned has none left.
+// One whose replies weren't loaded (HasMore or ContinueThread) is kept: its reply count
+// only counts live replies.
+func pruneTombstones(threads []*ThreadViewComment) []*ThreadViewComment {
+	kept := threads[:0]
+	for _, thread := range threads {
+		if thread.Comment.IsDeleted && len(thread.Replies) == 0 && !thread.HasMore && !thread.ContinueThread {
+			continue
+		}
+		kept = append(kept, thread)
+	}
+	return kept
+}
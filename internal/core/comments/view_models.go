@@ -8,6 +8,7 @@ import (
 // Matches social.coves.community.comment.getComments#commentView lexicon
 // Used in thread views and get endpoints
 // For deleted comments, IsDeleted=true and content-related fields are empty/nil
+// In comment trees they are tombstones: null author and "[deleted]" or "[removed]" record content
 type CommentView struct {
 	Embed          interface{}         `json:"embed,omitempty"`
 	Record         interface{}         `json:"record"`
@@ -23,6 +24,9 @@ type CommentView struct {
 	IsDeleted      bool                `json:"isDeleted,omitempty"`
 	DeletionReason *string             `json:"deletionReason,omitempty"`
 	DeletedAt      *string             `json:"deletedAt,omitempty"`
+	LastEditedAt   *string             `json:"lastEditedAt,omitempty"`
+	Edited         bool                `json:"edited,omitempty"`    // The record changed after it was first indexed
+	Collapsed      bool                `json:"collapsed,omitempty"` // Below the community's collapse threshold or caught by crowd control
 }
 
 // ThreadViewComment represents a comment with its nested replies
@@ -32,6 +36,8 @@ type ThreadViewComment struct {
 	Comment *CommentView         `json:"comment"`
 	Replies []*ThreadViewComment `json:"replies,omitempty"` // Recursive nested replies
 	HasMore bool                 `json:"hasMore,omitempty"` // Indicates more replies exist
+	// ContinueThread means replies are past the max thread depth; clients link to this comment's permalink
+	ContinueThread bool `json:"continueThread,omitempty"`
 }
 
 // CommentRef is a minimal reference to a post or comment (URI + CID)
@@ -48,6 +54,9 @@ type CommentStats struct {
 	Downvotes  int `json:"downvotes"`
 	Score      int `json:"score"`
 	ReplyCount int `json:"replyCount"`
+	// ScoreHidden means the comment is inside its community's score hiding window;
+	// vote counts are zeroed and serialize as null
+	ScoreHidden bool `json:"scoreHidden,omitempty"`
 }
 
 // CommentViewerState represents the viewer's relationship with the comment
@@ -62,10 +71,30 @@ type CommentViewerState struct {
 // Includes the full comment thread tree and original post reference
 type GetCommentsResponse struct {
 	Post     interface{}          `json:"post"`
+	Meta     *ThreadMeta          `json:"meta,omitempty"`
 	Cursor   *string              `json:"cursor,omitempty"`
 	Comments []*ThreadViewComment `json:"comments"`
 }
 
+// CommentThreadView is the permalink view of a single comment
+// Ancestors are ordered root-first and end with the focus comment's parent.
+// Deleted or moderator-removed ancestors are included as stubs so the chain stays intact.
+type CommentThreadView struct {
+	Focus            *CommentView         `json:"focus"`
+	Ancestors        []*CommentView       `json:"ancestors"`
+	Replies          []*ThreadViewComment `json:"replies"`
+	HasMoreAncestors bool                 `json:"hasMoreAncestors,omitempty"` // Chain continues above the returned ancestors
+}
+
+// GetCommentThreadResponse represents the response for fetching a single comment thread
+// Returned by social.coves.community.comment.getComments when called with uri instead of post
+type GetCommentThreadResponse struct {
+	Post   interface{}        `json:"post"`
+	Meta   *ThreadMeta        `json:"meta,omitempty"`
+	Cursor *string            `json:"cursor,omitempty"` // Paginates the focus comment's direct replies
+	Thread *CommentThreadView `json:"thread"`
+}
+
 // GetActorCommentsRequest defines the parameters for fetching a user's comments
 // Used by social.coves.actor.getComments endpoint
 type GetActorCommentsRequest struct {
@@ -82,3 +111,24 @@ type GetActorCommentsResponse struct {
 	Comments []*CommentView `json:"comments"`
 	Cursor   *string        `json:"cursor,omitempty"`
 }
+
+// SearchCommentsRequest defines the parameters for searching a community's comments
+// Used by social.coves.community.comment.search (moderator tooling)
+type SearchCommentsRequest struct {
+	Author         *string // Optional: only comments by this user (DID)
+	PostURI        *string // Optional: only comments on this post
+	Cursor         *string // Pagination cursor from previous response
+	ViewerDID      string  // Required: the moderator making the request
+	Community      string  // Required: community handle or DID
+	Query          string  // Required: search query
+	Limit          int     // Max comments to return (1-100, default 25)
+	IncludeDeleted bool    // Include soft-deleted comments (returned as deleted stubs)
+}
+
+// SearchCommentsResponse represents the response for searching a community's comments
+// Matches social.coves.community.comment.search lexicon output
+// Each comment's post reference links to the post it was made on
+type SearchCommentsResponse struct {
+	Comments []*CommentView `json:"comments"`
+	Cursor   *string        `json:"cursor,omitempty"`
+}
@@ -0,0 +1,36 @@
+package communities
+
+import (
+	"context"
+	"time"
+)
+
+// Active user windows, in UTC days including today
+const (
+	WeeklyActiveDays  = 7
+	MonthlyActiveDays = 30
+
+	// ActivityRetention is how long per-day activity rows are kept; longer than the
+	// monthly window so a missed rollup never undercounts
+	ActivityRetention = 90 * 24 * time.Hour
+)
+
+// ActivityRepository tracks which users are active in which communities
+// Activity is recorded per UTC day, so a user who posts, comments and votes in a
+// community on the same day counts once.
+type ActivityRepository interface {
+	// RecordActivity notes that the user was active in the community on at's UTC day
+	// Repeats on the same day are ignored.
+	RecordActivity(ctx context.Context, communityDID, userDID string, at time.Time) error
+
+	// RecordSubjectActivity records activity in the community of a post or comment
+	// (a comment counts toward its root post's community); unknown subjects are ignored
+	RecordSubjectActivity(ctx context.Context, subjectURI, userDID string, at time.Time) error
+
+	// RollupActiveUsers recomputes every community's weekly and monthly active users as of
+	// now's UTC day; returns how many communities changed
+	RollupActiveUsers(ctx context.Context, now time.Time) (int64, error)
+
+	// PruneActivity deletes activity recorded on days before before; returns rows deleted
+	PruneActivity(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,97 @@
+package communities
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// ContentWarningNSFW is the community content warning for adult content
+const ContentWarningNSFW = "nsfw"
+
+// AgeConfirmationRequired reports whether viewers must confirm they're over 18 before seeing the
+// community's posts, which is the case for NSFW communities
+func (c *Community) AgeConfirmationRequired() bool {
+	return slices.Contains(c.ContentWarnings, ContentWarningNSFW)
+}
+
+// AgeConfirmationRepository stores viewers' one-time age confirmations per community
+// Implemented by postgres.NewAgeConfirmationRepository.
+type AgeConfirmationRepository interface {
+	// ConfirmAge records the confirmation; confirming twice keeps the first confirmed_at
+	ConfirmAge(ctx context.Context, userDID, communityDID string) error
+	// HasConfirmedAge reports whether userDID confirmed their age for the community
+	HasConfirmedAge(ctx context.Context, userDID, communityDID string) (bool, error)
+}
+
+// AgeGate decides whether a viewer sees an age-restricted community's posts
+// Gated viewers get the community's metadata but none of its posts. Anonymous viewers are
+// always gated; signed-in viewers are gated until they confirm once per community.
+type AgeGate interface {
+	// Gated reports whether viewerDID ("" when anonymous) must confirm their age first
+	Gated(ctx context.Context, viewerDID string, community *Community) (bool, error)
+	// GatedCommunity is Gated for a community DID or handle, returning the community too
+	GatedCommunity(ctx context.Context, viewerDID, identifier string) (*Community, bool, error)
+	// ConfirmAge records that userDID confirmed they're over 18 for the community
+	// Confirming for a community that isn't age-restricted is a no-op.
+	ConfirmAge(ctx context.Context, userDID, identifier string) error
+}
+
+type ageGate struct {
+	service Service
+	repo    AgeConfirmationRepository
+}
+
+// NewAgeGate creates an age gate resolving communities through service
+func NewAgeGate(service Service, repo AgeConfirmationRepository) AgeGate {
+	return &ageGate{service: service, repo: repo}
+}
+
+// Gated reports whether the viewer must confirm their age before seeing the community's posts
+func (g *ageGate) Gated(ctx context.Context, viewerDID string, community *Community) (bool, error) {
+	if !community.AgeConfirmationRequired() {
+		return false, nil
+	}
+	if viewerDID == "" {
+		return true, nil
+	}
+	confirmed, err := g.repo.HasConfirmedAge(ctx, viewerDID, community.DID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check age confirmation: %w", err)
+	}
+	return !confirmed, nil
+}
+
+// GatedCommunity resolves the community and reports whether the viewer is gated from its posts
+func (g *ageGate) GatedCommunity(ctx context.Context, viewerDID, identifier string) (*Community, bool, error) {
+	community, err := g.service.GetCommunity(ctx, identifier)
+	if err != nil {
+		return nil, false, err
+	}
+	gated, err := g.Gated(ctx, viewerDID, community)
+	if err != nil {
+		return nil, false, err
+	}
+	return community, gated, nil
+}
+
+// ConfirmAge records the viewer's age confirmation for an age-restricted community
+func (g *ageGate) ConfirmAge(ctx context.Context, userDID, identifier string) error {
+	if userDID == "" {
+		return ErrUnauthorized
+	}
+	if identifier == "" {
+		return NewValidationError("community", "required")
+	}
+	community, err := g.service.GetCommunity(ctx, identifier)
+	if err != nil {
+		return err
+	}
+	if !community.AgeConfirmationRequired() {
+		return nil
+	}
+	if err := g.repo.ConfirmAge(ctx, userDID, community.DID); err != nil {
+		return fmt.Errorf("failed to confirm age: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package communities
+
+import (
+	"Coves/internal/lexicon/validate"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Community categories for discovery
+// The taxonomy is fixed by social.coves.community.defs#category; a community lists up to
+// MaxCategories of them in its profile record. Records come from any PDS, so categories
+// outside the taxonomy are indexed as written but browse and count as CategoryOther.
+const (
+	CategoryArt           = "art"
+	CategoryBooks         = "books"
+	CategoryBusiness      = "business"
+	CategoryEducation     = "education"
+	CategoryEntertainment = "entertainment"
+	CategoryFood          = "food"
+	CategoryGaming        = "gaming"
+	CategoryHealth        = "health"
+	CategoryHobbies       = "hobbies"
+	CategoryMusic         = "music"
+	CategoryNews          = "news"
+	CategoryPolitics      = "politics"
+	CategoryScience       = "science"
+	CategorySports        = "sports"
+	CategoryTechnology    = "technology"
+	CategoryTravel        = "travel"
+
+	// CategoryOther is the bucket for categories outside the taxonomy
+	// It can be filtered on but not chosen, since it isn't a category of its own.
+	CategoryOther = "other"
+)
+
+// MaxCategories is how many categories a community can list
+const MaxCategories = validate.MaxProfileCategories
+
+// Categories is the taxonomy in browsing order, without CategoryOther
+var Categories = []string{
+	CategoryArt, CategoryBooks, CategoryBusiness, CategoryEducation, CategoryEntertainment,
+	CategoryFood, CategoryGaming, CategoryHealth, CategoryHobbies, CategoryMusic, CategoryNews,
+	CategoryPolitics, CategoryScience, CategorySports, CategoryTechnology, CategoryTravel,
+}
+
+// CategoryCount is the number of listed communities in a category
+type CategoryCount struct {
+	Category       string `json:"category"`
+	CommunityCount int    `json:"communityCount"`
+}
+
+// CategoryRepository counts communities by category for browsing
+type CategoryRepository interface {
+	// CountCommunities returns how many listed communities are in each category, keyed by
+	// category with unknown categories counted under CategoryOther
+	// A community counts once per category it lists, and once in CategoryOther however many
+	// unknown categories it has. Communities hidden from community.list aren't counted.
+	CountCommunities(ctx context.Context) (map[string]int, error)
+}
+
+// IsKnownCategory reports whether category is in the taxonomy
+func IsKnownCategory(category string) bool {
+	return slices.Contains(Categories, category)
+}
+
+// ValidateCategories checks the categories of a community.create or community.update request
+// Our own writes only use the taxonomy; unknown categories are only tolerated from the firehose.
+func ValidateCategories(categories []string) error {
+	if len(categories) > MaxCategories {
+		return NewValidationError("categories", fmt.Sprintf("at most %d categories allowed", MaxCategories))
+	}
+	for i, category := range categories {
+		if !IsKnownCategory(category) {
+			return NewValidationError(fmt.Sprintf("categories[%d]", i), fmt.Sprintf("unknown category %q; must be one of: %s", category, strings.Join(Categories, ", ")))
+		}
+		if slices.Contains(categories[:i], category) {
+			return NewValidationError(fmt.Sprintf("categories[%d]", i), fmt.Sprintf("duplicate category %q", category))
+		}
+	}
+	return nil
+}
+
+// ValidateCategoryFilter checks a category filter from community.list or community.search
+// An empty filter matches every community.
+func ValidateCategoryFilter(category string) error {
+	if category == "" || category == CategoryOther || IsKnownCategory(category) {
+		return nil
+	}
+	return NewValidationError("category", fmt.Sprintf("unknown category %q; must be one of: %s, %s", category, strings.Join(Categories, ", "), CategoryOther))
+}
+
+// NormalizeCategories returns the categories to index from a firehose profile record
+// Unknown categories are kept so they can count once the taxonomy grows; empty and duplicate
+// entries are dropped and the list is capped at MaxCategories.
+func NormalizeCategories(categories []string) []string {
+	result := make([]string, 0, min(len(categories), MaxCategories))
+	for _, category := range categories {
+		if len(result) == MaxCategories {
+			break
+		}
+		if category == "" || slices.Contains(result, category) {
+			continue
+		}
+		result = append(result, category)
+	}
+	return result
+}
+
+// CategoryBuckets returns the categories a community is browsed under: its known categories,
+// then CategoryOther if it lists any unknown ones
+func CategoryBuckets(categories []string) []string {
+	var buckets []string
+	other := false
+	for _, category := range categories {
+		switch {
+		case IsKnownCategory(category):
+			if !slices.Contains(buckets, category) {
+				buckets = append(buckets, category)
+			}
+		case category != "":
+			other = true
+		}
+	}
+	if other {
+		buckets = append(buckets, CategoryOther)
+	}
+	return buckets
+}
+
+// CategoryCounts lists every category in the taxonomy with its count, in browsing order and
+// followed by CategoryOther; categories missing from counts have no communities
+func CategoryCounts(counts map[string]int) []CategoryCount {
+	result := make([]CategoryCount, 0, len(Categories)+1)
+	for _, category := range Categories {
+		result = append(result, CategoryCount{Category: category, CommunityCount: counts[category]})
+	}
+	return append(result, CategoryCount{Category: CategoryOther, CommunityCount: counts[CategoryOther]})
+}
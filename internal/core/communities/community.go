@@ -71,6 +71,7 @@ type Community struct {
 	Name                   string    `json:"name" db:"name"`                 // Short name (e.g., "gardening")
 	DisplayHandle          string    `json:"displayHandle,omitempty" db:"-"` // UI hint: !gardening@coves.social (computed, not stored)
 	RecordCID              string    `json:"recordCid,omitempty" db:"record_cid"`
+	RecordRev              string    `json:"-" db:"rev"` // Repo revision of the commit that last wrote the profile record
 	FederatedID            string    `json:"federatedId,omitempty" db:"federated_id"`
 	PDSAccessToken         string    `json:"-" db:"pds_access_token"`
 	SigningKeyPEM          string    `json:"-" db:"signing_key_encrypted"`
@@ -81,13 +82,34 @@ type Community struct {
 	RotationKeyPEM         string    `json:"-" db:"rotation_key_encrypted"`
 	DID                    string    `json:"did" db:"did"`
 	ContentWarnings        []string  `json:"contentWarnings,omitempty" db:"content_warnings"`
+	Categories             []string  `json:"categories,omitempty" db:"categories"` // As written in the profile record, unknown ones included (see CategoryBuckets)
+	Flairs                 []Flair   `json:"flairs,omitempty" db:"flairs"` // Post flairs from the profile record (max 20)
+	PostingRules           PostingRules `json:"postingRules" db:"posting_rules"`
+	ScoreHidingHours       int          `json:"scoreHidingHours" db:"score_hiding_hours"` // Vote counts are hidden this long after posting (0-24)
+	CollapseThreshold      int          `json:"collapseThreshold" db:"collapse_threshold"` // Comments scoring below this are collapsed
+	CrowdControl           string       `json:"crowdControl" db:"crowd_control"`           // off, low or high
 	DescriptionFacets      []byte    `json:"descriptionFacets,omitempty" db:"description_facets"`
+	RawRecord              *string   `json:"-" db:"raw_record"` // Full profile record JSON as received from the firehose
 	PostCount              int       `json:"postCount" db:"post_count"`
 	SubscriberCount        int       `json:"subscriberCount" db:"subscriber_count"`
 	MemberCount            int       `json:"memberCount" db:"member_count"`
+	WeeklyActiveUsers      int       `json:"weeklyActiveUsers" db:"weekly_active_users"`   // Distinct users active in the last 7 days (nightly rollup)
+	MonthlyActiveUsers     int       `json:"monthlyActiveUsers" db:"monthly_active_users"` // Distinct users active in the last 30 days (nightly rollup)
 	ID                     int                    `json:"id" db:"id"`
 	AllowExternalDiscovery bool                   `json:"allowExternalDiscovery" db:"allow_external_discovery"`
 	Viewer                 *CommunityViewerState  `json:"viewer,omitempty" db:"-"`
+	LastPostAt             *time.Time             `json:"lastPostAt,omitempty" db:"last_post_at"` // Most recent post (maintained by post consumer)
+	FederationBlocked      bool                   `json:"-" db:"federation_blocked"`              // Hosting instance is blocked by federation policy
+	HostedByUnverified     bool                   `json:"-" db:"hosted_by_unverified"`            // Hosting instance's DID document couldn't be verified yet, or stopped verifying
+	ImpersonationFlag      bool                   `json:"-" db:"impersonation_flag"`              // Profile looks like it impersonates another community (see DetectImpersonation)
+	ImpersonationReason    string                 `json:"-" db:"impersonation_reason"`
+	ImpersonationCleared   string                 `json:"-" db:"impersonation_cleared_reason"` // Flag reason an admin reviewed and cleared
+	ConfusableWith         string                 `json:"-" db:"confusable_with"`              // DID of the community whose handle this handle imitates (homoglyphs)
+	SuspendedAt            *time.Time             `json:"-" db:"suspended_at"`                 // Set when an admin confirms impersonation
+	DeletedAt              *time.Time             `json:"-" db:"deleted_at"`                   // Set when the owner or an admin deletes the community
+	DeletedByDID           string                 `json:"-" db:"deleted_by_did"`
+	PDSDeactivatedAt       *time.Time             `json:"-" db:"pds_deactivated_at"` // Set once the grace period ends and the PDS account is deactivated
+	Remote                 bool                   `json:"-" db:"remote"`             // Learned from a peer instance's directory, not the firehose
 }
 
 // CommunityViewerState contains viewer-specific state for community list views.
@@ -112,6 +134,7 @@ type CommunityView struct {
 	DisplayHandle   string                `json:"displayHandle,omitempty"`
 	Avatar          string                `json:"avatar,omitempty"` // URL, not CID
 	Visibility      string                `json:"visibility,omitempty"`
+	Categories      []string              `json:"categories,omitempty"` // Category buckets
 	SubscriberCount int                   `json:"subscriberCount"`
 	MemberCount     int                   `json:"memberCount"`
 	PostCount       int                   `json:"postCount"`
@@ -121,25 +144,36 @@ type CommunityView struct {
 // CommunityViewDetailed is the full API view for single community lookups
 // Based on social.coves.community.defs#communityViewDetailed lexicon
 type CommunityViewDetailed struct {
-	DID                    string                `json:"did"`
-	Handle                 string                `json:"handle,omitempty"`
-	Name                   string                `json:"name"`
-	DisplayName            string                `json:"displayName,omitempty"`
-	DisplayHandle          string                `json:"displayHandle,omitempty"`
-	Description            string                `json:"description,omitempty"`
-	Avatar                 string                `json:"avatar,omitempty"` // URL
-	Banner                 string                `json:"banner,omitempty"` // URL
-	CreatedByDID           string                `json:"createdBy,omitempty"`
-	HostedByDID            string                `json:"hostedBy,omitempty"`
-	Visibility             string                `json:"visibility,omitempty"`
-	ModerationType         string                `json:"moderationType,omitempty"`
-	ContentWarnings        []string              `json:"contentWarnings,omitempty"`
-	CreatedAt              time.Time             `json:"createdAt"`
-	AllowExternalDiscovery bool                  `json:"allowExternalDiscovery"`
-	SubscriberCount        int                   `json:"subscriberCount"`
-	MemberCount            int                   `json:"memberCount"`
-	PostCount              int                   `json:"postCount"`
-	Viewer                 *CommunityViewerState `json:"viewer,omitempty"`
+	DID                     string                `json:"did"`
+	Handle                  string                `json:"handle,omitempty"`
+	Name                    string                `json:"name"`
+	DisplayName             string                `json:"displayName,omitempty"`
+	DisplayHandle           string                `json:"displayHandle,omitempty"`
+	Description             string                `json:"description,omitempty"`
+	Avatar                  string                `json:"avatar,omitempty"` // URL
+	Banner                  string                `json:"banner,omitempty"` // URL
+	CreatedByDID            string                `json:"createdBy,omitempty"`
+	HostedByDID             string                `json:"hostedBy,omitempty"`
+	Visibility              string                `json:"visibility,omitempty"`
+	ModerationType          string                `json:"moderationType,omitempty"`
+	ContentWarnings         []string              `json:"contentWarnings,omitempty"`
+	Categories              []string              `json:"categories,omitempty"` // Category buckets
+	Flairs                  []Flair               `json:"flairs,omitempty"`
+	PostingRules            PostingRules          `json:"postingRules"`
+	ScoreHidingHours        int                   `json:"scoreHidingHours"`
+	CollapseThreshold       int                   `json:"collapseThreshold"`
+	CrowdControl            string                `json:"crowdControl"`
+	Rules                   *CommunityRules       `json:"rules,omitempty"`         // Set by community.get when the community has a rules document
+	AgeConfirmationRequired bool                  `json:"ageConfirmationRequired"` // NSFW: viewers confirm they're over 18 once before seeing posts
+	Gated                   bool                  `json:"gated,omitempty"`         // Set when this viewer hasn't confirmed their age yet (see AgeGate)
+	CreatedAt               time.Time             `json:"createdAt"`
+	AllowExternalDiscovery  bool                  `json:"allowExternalDiscovery"`
+	SubscriberCount         int                   `json:"subscriberCount"`
+	MemberCount             int                   `json:"memberCount"`
+	PostCount               int                   `json:"postCount"`
+	WeeklyActiveUsers       int                   `json:"weeklyActiveUsers"`
+	MonthlyActiveUsers      int                   `json:"monthlyActiveUsers"`
+	Viewer                  *CommunityViewerState `json:"viewer,omitempty"`
 }
 
 // Subscription represents a lightweight feed follow (user subscribes to see posts)
@@ -153,6 +187,31 @@ type Subscription struct {
 	ID                int       `json:"id" db:"id"`
 }
 
+// SubscribedCommunity pairs a community with the viewer's subscription to it
+// Returned by social.coves.actor.getSubscriptions
+type SubscribedCommunity struct {
+	Community    *Community
+	Subscription *Subscription
+}
+
+// SubscribedCommunityView is the API view for a single subscription entry
+type SubscribedCommunityView struct {
+	SubscribedAt      time.Time      `json:"subscribedAt"`
+	LastPostAt        *time.Time     `json:"lastPostAt,omitempty"`
+	Community         *CommunityView `json:"community"`
+	ContentVisibility int            `json:"contentVisibility"`
+}
+
+// ToView converts a SubscribedCommunity to its API view
+func (s *SubscribedCommunity) ToView() *SubscribedCommunityView {
+	return &SubscribedCommunityView{
+		SubscribedAt:      s.Subscription.SubscribedAt,
+		LastPostAt:        s.Community.LastPostAt,
+		Community:         s.Community.ToCommunityView(),
+		ContentVisibility: s.Subscription.ContentVisibility,
+	}
+}
+
 // CommunityBlock represents a user blocking a community
 // Block records live in the user's repository (at://user_did/social.coves.community.block/{rkey})
 type CommunityBlock struct {
@@ -177,6 +236,13 @@ type Membership struct {
 	IsModerator       bool      `json:"isModerator" db:"is_moderator"`
 }
 
+// Moderation actions an instance can take against a community
+const (
+	ModerationActionDelist     = "delist"
+	ModerationActionQuarantine = "quarantine"
+	ModerationActionRemove     = "remove"
+)
+
 // ModerationAction represents a moderation action taken against a community
 type ModerationAction struct {
 	CreatedAt    time.Time  `json:"createdAt" db:"created_at"`
@@ -221,23 +287,53 @@ type UpdateCommunityRequest struct {
 	AllowExternalDiscovery *bool    `json:"allowExternalDiscovery,omitempty"`
 	ModerationType         *string  `json:"moderationType,omitempty"`
 	ContentWarnings        []string `json:"contentWarnings,omitempty"`
+	Categories             *[]string `json:"categories,omitempty"` // Replaces the categories when set; an empty list removes them
+	Flairs                 *[]Flair `json:"flairs,omitempty"` // Replaces the flair set when set; an empty list removes all flairs
+	PostingRules           *PostingRules `json:"postingRules,omitempty"` // Replaces the posting rules when set
+	ScoreHidingHours       *int          `json:"scoreHidingHours,omitempty"` // 0-24; 0 disables score hiding
+	CollapseThreshold      *int          `json:"collapseThreshold,omitempty"` // -1000 to 0
+	CrowdControl           *string       `json:"crowdControl,omitempty"`      // off, low or high
 }
 
 // ListCommunitiesRequest represents query parameters for listing communities
 type ListCommunitiesRequest struct {
-	Sort          string `json:"sort,omitempty"`          // Enum: popular, active, new, alphabetical
+	Sort          string `json:"sort,omitempty"`          // Enum: popular, active, new, alphabetical, relevance
 	Visibility    string `json:"visibility,omitempty"`    // Filter: public, unlisted, private
-	Category      string `json:"category,omitempty"`      // Optional: filter by category (future)
+	Category      string `json:"category,omitempty"`      // Optional: filter by category bucket (see ValidateCategoryFilter)
 	Language      string `json:"language,omitempty"`      // Optional: filter by language (future)
 	SubscriberDID string `json:"subscriberDid,omitempty"` // If set, filter to only subscribed communities
+	ViewerDID     string `json:"-"`                       // Authenticated viewer; required for the relevance sort
+	Cursor        string `json:"cursor,omitempty"`        // Signed keyset cursor (relevance sort only)
 	Limit         int    `json:"limit"`                   // 1-100, default 50
-	Offset        int    `json:"offset"`                  // Pagination offset
+	Offset        int    `json:"offset"`                  // Pagination offset (all other sorts)
+}
+
+// ListSortRelevance orders communities for the viewer: subscribed communities first (most
+// recent post first), then communities where people the viewer interacts with are active,
+// then everything else by subscribers. Without a viewer it falls back to "popular".
+const ListSortRelevance = "relevance"
+
+// Sort options for social.coves.actor.getSubscriptions
+const (
+	SubscriptionSortSubscribedAt   = "subscribedAt"   // Most recently subscribed first (default)
+	SubscriptionSortAlphabetical   = "alphabetical"   // Community name A-Z
+	SubscriptionSortRecentActivity = "recentActivity" // Most recent post first, communities without posts last
+)
+
+// GetSubscriptionsRequest represents query parameters for listing a user's subscribed communities
+type GetSubscriptionsRequest struct {
+	UserDID string `json:"userDid"`
+	Sort    string `json:"sort,omitempty"` // Enum: alphabetical, recentActivity, subscribedAt
+	Query   string `json:"q,omitempty"`    // Optional: filter by community name, display name, or handle
+	Limit   int    `json:"limit"`          // 1-100, default 50
+	Offset  int    `json:"offset"`         // Pagination offset
 }
 
 // SearchCommunitiesRequest represents query parameters for searching communities
 type SearchCommunitiesRequest struct {
 	Query      string `json:"query"`
 	Visibility string `json:"visibility,omitempty"`
+	Category   string `json:"category,omitempty"` // Optional: filter by category bucket (see ValidateCategoryFilter)
 	Limit      int    `json:"limit"`
 	Offset     int    `json:"offset"`
 }
@@ -298,8 +394,9 @@ func (c *Community) ToCommunityView() *CommunityView {
 		Name:            c.Name,
 		DisplayName:     c.DisplayName,
 		DisplayHandle:   c.GetDisplayHandle(),
-		Avatar:          blobs.HydrateImageURL(GetImageProxyConfig(), c.PDSURL, c.DID, c.AvatarCID, "avatar_small"),
+		Avatar:          blobs.HydrateAvatarURL(GetImageProxyConfig(), c.PDSURL, c.DID, c.AvatarCID, "avatar_small"),
 		Visibility:      c.Visibility,
+		Categories:      CategoryBuckets(c.Categories),
 		SubscriberCount: c.SubscriberCount,
 		MemberCount:     c.MemberCount,
 		PostCount:       c.PostCount,
@@ -313,25 +410,34 @@ func (c *Community) ToCommunityView() *CommunityView {
 // Uses avatar preset (80px) for detail views and banner preset for banners
 func (c *Community) ToCommunityViewDetailed() *CommunityViewDetailed {
 	view := &CommunityViewDetailed{
-		DID:                    c.DID,
-		Handle:                 c.Handle,
-		Name:                   c.Name,
-		DisplayName:            c.DisplayName,
-		DisplayHandle:          c.GetDisplayHandle(),
-		Description:            c.Description,
-		Avatar:                 blobs.HydrateImageURL(GetImageProxyConfig(), c.PDSURL, c.DID, c.AvatarCID, "avatar"),
-		Banner:                 blobs.HydrateImageURL(GetImageProxyConfig(), c.PDSURL, c.DID, c.BannerCID, "banner"),
-		CreatedByDID:           c.CreatedByDID,
-		HostedByDID:            c.HostedByDID,
-		Visibility:             c.Visibility,
-		ModerationType:         c.ModerationType,
-		ContentWarnings:        c.ContentWarnings,
-		CreatedAt:              c.CreatedAt,
-		AllowExternalDiscovery: c.AllowExternalDiscovery,
-		SubscriberCount:        c.SubscriberCount,
-		MemberCount:            c.MemberCount,
-		PostCount:              c.PostCount,
-		Viewer:                 c.Viewer,
+		DID:                     c.DID,
+		Handle:                  c.Handle,
+		Name:                    c.Name,
+		DisplayName:             c.DisplayName,
+		DisplayHandle:           c.GetDisplayHandle(),
+		Description:             c.Description,
+		Avatar:                  blobs.HydrateAvatarURL(GetImageProxyConfig(), c.PDSURL, c.DID, c.AvatarCID, "avatar"),
+		Banner:                  blobs.HydrateImageURL(GetImageProxyConfig(), c.PDSURL, c.DID, c.BannerCID, "banner"),
+		CreatedByDID:            c.CreatedByDID,
+		HostedByDID:             c.HostedByDID,
+		Visibility:              c.Visibility,
+		ModerationType:          c.ModerationType,
+		ContentWarnings:         c.ContentWarnings,
+		Categories:              CategoryBuckets(c.Categories),
+		Flairs:                  c.Flairs,
+		PostingRules:            c.PostingRules,
+		ScoreHidingHours:        c.ScoreHidingHours,
+		CollapseThreshold:       c.CollapseThreshold,
+		CrowdControl:            c.CrowdControl,
+		AgeConfirmationRequired: c.AgeConfirmationRequired(),
+		CreatedAt:               c.CreatedAt,
+		AllowExternalDiscovery:  c.AllowExternalDiscovery,
+		SubscriberCount:         c.SubscriberCount,
+		MemberCount:             c.MemberCount,
+		PostCount:               c.PostCount,
+		WeeklyActiveUsers:       c.WeeklyActiveUsers,
+		MonthlyActiveUsers:      c.MonthlyActiveUsers,
+		Viewer:                  c.Viewer,
 	}
 
 	return view
@@ -0,0 +1,274 @@
+package communities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Default creation policy limits
+const (
+	DefaultMaxCreations   = 2
+	DefaultCreationWindow = 24 * time.Hour
+	DefaultMinAccountAge  = 24 * time.Hour
+)
+
+// communityNameRegex is the creation policy's name syntax: 3-30 lowercase letters, digits, and
+// hyphens, not starting or ending with a hyphen
+var communityNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{1,28}[a-z0-9]$`)
+
+// builtInReservedNames can't be squatted or removed by admins
+var builtInReservedNames = map[string]bool{
+	"about":     true,
+	"admin":     true,
+	"api":       true,
+	"app":       true,
+	"coves":     true,
+	"help":      true,
+	"mod":       true,
+	"moderator": true,
+	"mods":      true,
+	"official":  true,
+	"root":      true,
+	"security":  true,
+	"settings":  true,
+	"staff":     true,
+	"support":   true,
+	"system":    true,
+	"www":       true,
+}
+
+// ReservedName is a community name no one may create
+type ReservedName struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Name      string    `json:"name"`
+	Reason    string    `json:"reason,omitempty"`
+	AddedBy   string    `json:"addedBy,omitempty"` // Admin DID; empty for built-in names
+	BuiltIn   bool      `json:"builtIn"`
+}
+
+// CreationPolicyRepository persists community creation history and admin-managed reserved names
+type CreationPolicyRepository interface {
+	// ClaimCreation records a creation in flight and returns its id, or ErrTooManyCommunities
+	// when the creator already has max creations (0 for no limit) since the given time
+	// Counting and recording are atomic per creator, so concurrent claims can't both pass.
+	ClaimCreation(ctx context.Context, creatorDID, name string, since time.Time, max int) (int64, error)
+	CompleteCreation(ctx context.Context, id int64, communityDID string) error
+	// ReleaseCreation removes the claim of a creation that failed, giving the slot back
+	ReleaseCreation(ctx context.Context, id int64) error
+
+	// GetAccountCreatedAt returns when the user was first indexed; ErrAccountTooNew if unknown
+	GetAccountCreatedAt(ctx context.Context, did string) (time.Time, error)
+
+	IsNameReserved(ctx context.Context, name string) (bool, error)
+	ListReservedNames(ctx context.Context) ([]*ReservedName, error)
+	AddReservedName(ctx context.Context, name *ReservedName) error
+	RemoveReservedName(ctx context.Context, name string) error
+}
+
+// CreationPolicyConfig configures community creation limits
+// Zero MaxCreations or MinAccountAge disables that limit
+type CreationPolicyConfig struct {
+	Now           func() time.Time // Clock for the rate window and account age; defaults to time.Now
+	AdminDIDs     []string         // Bypass the rate limit and account age (not name rules)
+	MaxCreations  int              // Communities a user may create per Window
+	Window        time.Duration
+	MinAccountAge time.Duration
+}
+
+// DefaultCreationPolicyConfig returns the default limits: 2 communities per 24h, accounts at least a day old
+func DefaultCreationPolicyConfig() CreationPolicyConfig {
+	return CreationPolicyConfig{
+		MaxCreations:  DefaultMaxCreations,
+		Window:        DefaultCreationWindow,
+		MinAccountAge: DefaultMinAccountAge,
+	}
+}
+
+// CreationPolicy decides whether a user may create a community with a given name
+// Stops handle squatting by limiting how fast and how early accounts can claim names
+type CreationPolicy struct {
+	repo          CreationPolicyRepository
+	now           func() time.Time
+	admins        map[string]bool
+	maxCreations  int
+	window        time.Duration
+	minAccountAge time.Duration
+}
+
+// NewCreationPolicy creates a creation policy backed by repo
+func NewCreationPolicy(repo CreationPolicyRepository, config CreationPolicyConfig) *CreationPolicy {
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	if config.Window <= 0 {
+		config.Window = DefaultCreationWindow
+	}
+
+	admins := make(map[string]bool, len(config.AdminDIDs))
+	for _, did := range config.AdminDIDs {
+		admins[did] = true
+	}
+
+	return &CreationPolicy{
+		repo:          repo,
+		now:           config.Now,
+		admins:        admins,
+		maxCreations:  config.MaxCreations,
+		window:        config.Window,
+		minAccountAge: config.MinAccountAge,
+	}
+}
+
+// ValidateCommunityName checks a name against the creation policy's syntax rules
+func ValidateCommunityName(name string) error {
+	if !communityNameRegex.MatchString(name) {
+		return ErrInvalidCommunityName
+	}
+	return nil
+}
+
+// Check returns nil if creatorDID may create a community called name
+// Each failure has its own error: ErrInvalidCommunityName, ErrCommunityNameReserved, or
+// ErrAccountTooNew. The rate limit is enforced by ClaimCreation.
+func (p *CreationPolicy) Check(ctx context.Context, creatorDID, name string) error {
+	if err := ValidateCommunityName(name); err != nil {
+		return err
+	}
+
+	reserved, err := p.isReserved(ctx, name)
+	if err != nil {
+		return err
+	}
+	if reserved {
+		return fmt.Errorf("%w: %s", ErrCommunityNameReserved, name)
+	}
+
+	if p.admins[creatorDID] {
+		return nil
+	}
+
+	now := p.now()
+
+	if p.minAccountAge > 0 {
+		createdAt, err := p.repo.GetAccountCreatedAt(ctx, creatorDID)
+		if err != nil {
+			return err
+		}
+		if now.Sub(createdAt) < p.minAccountAge {
+			return fmt.Errorf("%w: accounts must be at least %s old", ErrAccountTooNew, p.minAccountAge)
+		}
+	}
+
+	return nil
+}
+
+// CreationClaim is a community creation counted toward its creator's rate limit while in flight
+// Complete it once the community exists, or Release it if the creation fails.
+type CreationClaim struct {
+	repo CreationPolicyRepository
+	id   int64
+}
+
+// ClaimCreation counts a creation toward creatorDID's rate limit before it starts, failing
+// with ErrTooManyCommunities when the limit is reached
+// Admins are counted but never limited.
+func (p *CreationPolicy) ClaimCreation(ctx context.Context, creatorDID, name string) (*CreationClaim, error) {
+	limit := p.maxCreations
+	if p.admins[creatorDID] {
+		limit = 0
+	}
+
+	id, err := p.repo.ClaimCreation(ctx, creatorDID, name, p.now().Add(-p.window), limit)
+	if errors.Is(err, ErrTooManyCommunities) {
+		return nil, fmt.Errorf("%w: at most %d communities per %s", ErrTooManyCommunities, p.maxCreations, p.window)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim community creation: %w", err)
+	}
+	return &CreationClaim{repo: p.repo, id: id}, nil
+}
+
+// Complete records the community the claimed creation created
+func (c *CreationClaim) Complete(ctx context.Context, communityDID string) error {
+	return c.repo.CompleteCreation(ctx, c.id, communityDID)
+}
+
+// Release gives the claimed slot back after the creation failed
+func (c *CreationClaim) Release(ctx context.Context) {
+	// Release even if the request was canceled; otherwise the slot is held until the window passes
+	if err := c.repo.ReleaseCreation(context.WithoutCancel(ctx), c.id); err != nil {
+		log.Printf("WARNING: Failed to release community creation claim %d: %v", c.id, err)
+	}
+}
+
+func (p *CreationPolicy) isReserved(ctx context.Context, name string) (bool, error) {
+	if builtInReservedNames[name] {
+		return true, nil
+	}
+	reserved, err := p.repo.IsNameReserved(ctx, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to check reserved names: %w", err)
+	}
+	return reserved, nil
+}
+
+// ListReservedNames returns built-in and admin-managed reserved names, sorted by name
+func (p *CreationPolicy) ListReservedNames(ctx context.Context) ([]*ReservedName, error) {
+	custom, err := p.repo.ListReservedNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]*ReservedName, 0, len(builtInReservedNames)+len(custom))
+	for name := range builtInReservedNames {
+		names = append(names, &ReservedName{Name: name, BuiltIn: true})
+	}
+	for _, reserved := range custom {
+		if !builtInReservedNames[reserved.Name] {
+			names = append(names, reserved)
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool { return names[i].Name < names[j].Name })
+	return names, nil
+}
+
+// AddReservedName reserves a name on behalf of an admin
+func (p *CreationPolicy) AddReservedName(ctx context.Context, name, reason, adminDID string) (*ReservedName, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if err := ValidateCommunityName(name); err != nil {
+		return nil, NewValidationError("name", err.Error())
+	}
+	if builtInReservedNames[name] {
+		return &ReservedName{Name: name, BuiltIn: true}, nil
+	}
+
+	reserved := &ReservedName{Name: name, Reason: reason, AddedBy: adminDID}
+	if err := p.repo.AddReservedName(ctx, reserved); err != nil {
+		return nil, err
+	}
+	return reserved, nil
+}
+
+// RemoveReservedName releases an admin-managed reserved name
+func (p *CreationPolicy) RemoveReservedName(ctx context.Context, name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if builtInReservedNames[name] {
+		return ErrReservedNameBuiltIn
+	}
+	return p.repo.RemoveReservedName(ctx, name)
+}
+
+// IsCreationPolicyError reports whether err is a creation policy rejection
+func IsCreationPolicyError(err error) bool {
+	return errors.Is(err, ErrInvalidCommunityName) ||
+		errors.Is(err, ErrCommunityNameReserved) ||
+		errors.Is(err, ErrTooManyCommunities) ||
+		errors.Is(err, ErrAccountTooNew)
+}
@@ -0,0 +1,218 @@
+package communities
+
+import (
+	"Coves/internal/core/audit"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DeletionGracePeriod is how long a deleted community can be undeleted before its
+// PDS account is deactivated
+const DeletionGracePeriod = 14 * 24 * time.Hour
+
+// DeleteCommunityRequest deletes or undeletes a community via
+// social.coves.community.delete and social.coves.community.undelete
+type DeleteCommunityRequest struct {
+	CommunityDID string `json:"community"` // DID or handle
+	ActorDID     string `json:"-"`         // Set from the authenticated user
+}
+
+// DeletionRepository stores community deletion state
+type DeletionRepository interface {
+	// MarkDeleted soft-deletes a community; returns ErrCommunityDeleted if it already is
+	MarkDeleted(ctx context.Context, communityDID, deletedByDID string) (time.Time, error)
+
+	// Restore clears the deletion of a community deleted after deletedAfter whose PDS
+	// account is still active; returns ErrDeletionGracePeriodOver otherwise
+	Restore(ctx context.Context, communityDID string, deletedAfter time.Time) error
+
+	// ListDueForDeactivation returns communities deleted before deletedBefore whose PDS
+	// account hasn't been deactivated yet, oldest first
+	ListDueForDeactivation(ctx context.Context, deletedBefore time.Time, limit int) ([]string, error)
+
+	// MarkPDSDeactivated records that the community's PDS account was deactivated
+	MarkPDSDeactivated(ctx context.Context, communityDID string) error
+}
+
+// DeleteCommunity soft-deletes a community. It disappears from listings, discovery and
+// search immediately and its posts drop out of feeds; subscriptions are kept so an
+// undelete restores it as it was. The PDS account is deactivated once the grace period ends.
+// Only the community's creator or an instance admin may delete it; an admin deleting
+// someone else's community is recorded as a removal in the same transaction.
+func (s *communityService) DeleteCommunity(ctx context.Context, req DeleteCommunityRequest) (*Community, error) {
+	community, err := s.authorizeDeletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if community.DeletedAt != nil {
+		return nil, ErrCommunityDeleted
+	}
+
+	var deletedAt time.Time
+	err = s.withTx(ctx, func(ctx context.Context) error {
+		var err error
+		deletedAt, err = s.deletions.MarkDeleted(ctx, community.DID, req.ActorDID)
+		if err != nil {
+			return err
+		}
+		if community.CreatedByDID == req.ActorDID {
+			return nil
+		}
+		_, err = s.repo.CreateModerationAction(ctx, &ModerationAction{
+			CommunityDID: community.DID,
+			Action:       ModerationActionRemove,
+			Reason:       "deleted by instance admin " + req.ActorDID,
+			InstanceDID:  s.instanceDID,
+			CreatedAt:    time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record removal: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	community.DeletedAt = &deletedAt
+	community.DeletedByDID = req.ActorDID
+
+	log.Printf("[COMMUNITY-DELETE] Community: %s, Event: deleted, Actor: %s, DeactivatesAfter: %s",
+		community.DID, req.ActorDID, deletedAt.Add(DeletionGracePeriod).Format(time.RFC3339))
+	s.recordAudit(ctx, req.ActorDID, audit.ActionDeleteCommunity, community.DID, nil)
+	return community, nil
+}
+
+// UndeleteCommunity restores a deleted community within its grace period
+// Only the community's creator or an instance admin may undelete it.
+func (s *communityService) UndeleteCommunity(ctx context.Context, req DeleteCommunityRequest) (*Community, error) {
+	community, err := s.authorizeDeletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if community.DeletedAt == nil {
+		return nil, ErrCommunityNotDeleted
+	}
+
+	deadline := time.Now().Add(-DeletionGracePeriod)
+	if community.PDSDeactivatedAt != nil || community.DeletedAt.Before(deadline) {
+		return nil, ErrDeletionGracePeriodOver
+	}
+
+	// The repository re-checks the deadline so a deactivation racing this call wins cleanly
+	if err := s.deletions.Restore(ctx, community.DID, deadline); err != nil {
+		return nil, err
+	}
+	community.DeletedAt = nil
+	community.DeletedByDID = ""
+
+	log.Printf("[COMMUNITY-DELETE] Community: %s, Event: undeleted, Actor: %s", community.DID, req.ActorDID)
+	s.recordAudit(ctx, req.ActorDID, audit.ActionUndeleteCommunity, community.DID, nil)
+	return community, nil
+}
+
+// authorizeDeletion resolves the community and checks the actor may delete or undelete it
+func (s *communityService) authorizeDeletion(ctx context.Context, req DeleteCommunityRequest) (*Community, error) {
+	if s.deletions == nil {
+		return nil, fmt.Errorf("community deletion is not configured")
+	}
+	if req.CommunityDID == "" {
+		return nil, NewValidationError("community", "required")
+	}
+	if req.ActorDID == "" {
+		return nil, ErrUnauthorized
+	}
+
+	community, err := s.lookupCommunity(ctx, req.CommunityDID)
+	if err != nil {
+		return nil, err
+	}
+	if community.CreatedByDID != req.ActorDID && !s.instanceAdmins[req.ActorDID] {
+		return nil, ErrUnauthorized
+	}
+	return community, nil
+}
+
+// DeactivateDeletedCommunities deactivates the PDS accounts of up to limit communities
+// whose deletion grace period has ended, using each community's stored credentials
+// Returns how many were deactivated; a failure on one community doesn't stop the rest.
+func (s *communityService) DeactivateDeletedCommunities(ctx context.Context, limit int) (int, error) {
+	if s.deletions == nil {
+		return 0, nil
+	}
+
+	dids, err := s.deletions.ListDueForDeactivation(ctx, time.Now().Add(-DeletionGracePeriod), limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list communities due for deactivation: %w", err)
+	}
+
+	deactivated := 0
+	for _, did := range dids {
+		if err := s.deactivateCommunityAccount(ctx, did); err != nil {
+			log.Printf("[COMMUNITY-DELETE] Community: %s, Event: deactivation_failed, Error: %v", did, err)
+			continue
+		}
+		deactivated++
+		log.Printf("[COMMUNITY-DELETE] Community: %s, Event: pds_account_deactivated", did)
+	}
+	return deactivated, nil
+}
+
+// deactivateCommunityAccount deactivates one community's PDS account and records it
+func (s *communityService) deactivateCommunityAccount(ctx context.Context, did string) error {
+	community, err := s.repo.GetByDID(ctx, did)
+	if err != nil {
+		return fmt.Errorf("failed to get community: %w", err)
+	}
+
+	community, err = s.EnsureFreshToken(ctx, community)
+	if err != nil {
+		return fmt.Errorf("failed to get community credentials: %w", err)
+	}
+	if community.PDSAccessToken == "" {
+		return errors.New("community has no PDS credentials")
+	}
+
+	pdsURL := community.PDSURL
+	if pdsURL == "" {
+		pdsURL = s.pdsURL
+	}
+	if err := deactivatePDSAccount(ctx, pdsURL, community.PDSAccessToken); err != nil {
+		return err
+	}
+	return s.deletions.MarkPDSDeactivated(ctx, did)
+}
+
+// deactivatePDSAccount calls com.atproto.server.deactivateAccount with the account's own token
+// The endpoint returns an empty body, so callPDSWithAuth (which expects a uri/cid) isn't used.
+func deactivatePDSAccount(ctx context.Context, pdsURL, accessToken string) error {
+	endpoint := strings.TrimSuffix(pdsURL, "/") + "/xrpc/com.atproto.server.deactivateAccount"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader("{}"))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call PDS: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			log.Printf("Failed to close response body: %v", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("PDS returned status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
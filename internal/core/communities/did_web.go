@@ -0,0 +1,181 @@
+package communities
+
+import (
+	"Coves/internal/atproto/identity"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/api/atproto"
+	"github.com/bluesky-social/indigo/xrpc"
+)
+
+// DIDWebDocument is the data behind a did:web DID document this instance serves for one of
+// its own communities at https://{host}/.well-known/did.json
+type DIDWebDocument struct {
+	CreatedAt  time.Time
+	DID        string // did:web:{name}.{didWebDomain}
+	Handle     string // Community handle claimed in alsoKnownAs
+	SigningKey string // Public atproto signing key reserved on the PDS, did:key serialization
+	PDSURL     string // PDS hosting the community's repository
+}
+
+// DIDWebRepository stores the did:web documents served for instance-native communities
+type DIDWebRepository interface {
+	// Create stores a new document; ErrHandleTaken when the DID or handle already has one
+	Create(ctx context.Context, doc *DIDWebDocument) error
+	GetByDID(ctx context.Context, did string) (*DIDWebDocument, error)
+	GetByHandle(ctx context.Context, handle string) (*DIDWebDocument, error)
+	Delete(ctx context.Context, did string) error
+}
+
+// DIDWebForCommunity returns the did:web identity a community gets under didWebDomain
+// atproto only supports hostname-level did:web, so the community name is a subdomain
+// (did:web:gaming.communities.example.com) rather than a path segment
+func DIDWebForCommunity(communityName, didWebDomain string) string {
+	return fmt.Sprintf("did:web:%s.%s", strings.ToLower(communityName), strings.ToLower(didWebDomain))
+}
+
+// DIDWebProvisioner creates PDS accounts for communities with did:web identities served by
+// this AppView, so small instances don't depend on plc.directory
+//
+// Flow:
+// 1. Reserve a signing key for the did:web on the PDS (com.atproto.server.reserveSigningKey)
+// 2. Publish the DID document (handle, signing key, PDS endpoint) so the DID resolves
+// 3. Create the PDS account for the existing DID (com.atproto.server.createAccount with did)
+//
+// The PDS must accept account creation for did:web identities it doesn't control the domain of.
+// The PDS holds the signing key; the AppView only publishes the public half.
+type DIDWebProvisioner struct {
+	docs           DIDWebRepository
+	instanceDomain string
+	pdsURL         string
+	didWebDomain   string // Parent domain for community DIDs (e.g., communities.example.com)
+}
+
+// NewDIDWebProvisioner creates a provisioner for did:web communities under didWebDomain
+func NewDIDWebProvisioner(instanceDomain, pdsURL, didWebDomain string, docs DIDWebRepository) *DIDWebProvisioner {
+	return &DIDWebProvisioner{
+		docs:           docs,
+		instanceDomain: instanceDomain,
+		pdsURL:         pdsURL,
+		didWebDomain:   strings.ToLower(didWebDomain),
+	}
+}
+
+// ProvisionCommunityAccount creates a PDS account for a community with a did:web identity
+//
+// SECURITY: The returned credentials MUST be encrypted before database storage
+func (p *DIDWebProvisioner) ProvisionCommunityAccount(
+	ctx context.Context,
+	communityName string,
+) (*CommunityPDSAccount, error) {
+	if communityName == "" {
+		return nil, fmt.Errorf("community name is required")
+	}
+
+	// Same handle and email scheme as PDSAccountProvisioner - only the DID method differs
+	handle := fmt.Sprintf("c-%s.%s", NormalizeCommunityName(communityName), p.instanceDomain)
+	email := fmt.Sprintf("c-%s@%s", NormalizeCommunityName(communityName), p.instanceDomain)
+	did := DIDWebForCommunity(communityName, p.didWebDomain)
+
+	password, err := generateSecurePassword(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	// A name with a published document belongs to another community: reserving a key and
+	// publishing would replace its signing key and PDS, and the cleanup below would unpublish it
+	if _, err := p.docs.GetByDID(ctx, did); err == nil {
+		return nil, ErrHandleTaken
+	} else if !errors.Is(err, ErrDIDWebDocumentNotFound) {
+		return nil, fmt.Errorf("failed to check DID document for %s: %w", did, err)
+	}
+
+	client := &xrpc.Client{
+		Host: p.pdsURL,
+	}
+
+	// 1. Reserve the signing key the PDS will sign the community's repo with
+	reserved, err := atproto.ServerReserveSigningKey(ctx, client, &atproto.ServerReserveSigningKey_Input{
+		Did: &did,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve signing key for %s: %w", did, err)
+	}
+
+	// 2. Publish the DID document before account creation - the PDS resolves it to verify the DID
+	// Create never replaces a document, so one published concurrently for the name fails here
+	if err := p.docs.Create(ctx, &DIDWebDocument{
+		DID:        did,
+		Handle:     handle,
+		SigningKey: reserved.SigningKey,
+		PDSURL:     p.pdsURL,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to publish DID document for %s: %w", did, err)
+	}
+
+	// 3. Create the account for the existing DID
+	output, err := atproto.ServerCreateAccount(ctx, client, &atproto.ServerCreateAccount_Input{
+		Did:      &did,
+		Handle:   handle,
+		Email:    &email,
+		Password: &password,
+	})
+	if err != nil {
+		// Unpublish the document this call created so a retry isn't blocked by it
+		if deleteErr := p.docs.Delete(ctx, did); deleteErr != nil {
+			log.Printf("WARNING: Failed to remove DID document for %s after failed account creation: %v", did, deleteErr)
+		}
+		return nil, fmt.Errorf("PDS account creation failed for community %s: %w", communityName, err)
+	}
+
+	return &CommunityPDSAccount{
+		DID:          output.Did,
+		Handle:       output.Handle,
+		Email:        email,
+		Password:     password, // Cleartext - will be encrypted by repository
+		AccessToken:  output.AccessJwt,
+		RefreshToken: output.RefreshJwt,
+		PDSURL:       p.pdsURL,
+	}, nil
+}
+
+// didWebDirectory adapts a DIDWebRepository to identity.LocalDirectory
+type didWebDirectory struct {
+	docs DIDWebRepository
+}
+
+// NewDIDWebDirectory lets the identity resolver answer lookups for this instance's own
+// did:web communities without fetching their DID documents from ourselves
+func NewDIDWebDirectory(docs DIDWebRepository) identity.LocalDirectory {
+	return &didWebDirectory{docs: docs}
+}
+
+// LookupLocal resolves a locally hosted did:web DID or community handle
+func (d *didWebDirectory) LookupLocal(ctx context.Context, identifier string) (*identity.Identity, error) {
+	var doc *DIDWebDocument
+	var err error
+	if strings.HasPrefix(identifier, "did:") {
+		doc, err = d.docs.GetByDID(ctx, strings.ToLower(identifier))
+	} else {
+		doc, err = d.docs.GetByHandle(ctx, strings.ToLower(identifier))
+	}
+	if err != nil {
+		if errors.Is(err, ErrDIDWebDocumentNotFound) {
+			return nil, &identity.ErrNotFound{Identifier: identifier, Reason: "not hosted by this instance"}
+		}
+		return nil, &identity.ErrResolutionFailed{Identifier: identifier, Reason: err.Error()}
+	}
+
+	return &identity.Identity{
+		DID:        doc.DID,
+		Handle:     doc.Handle,
+		PDSURL:     doc.PDSURL,
+		ResolvedAt: time.Now().UTC(),
+		Method:     identity.MethodLocal,
+	}, nil
+}
@@ -3,48 +3,113 @@ package communities
 import (
 	"errors"
 	"fmt"
+
+	coreerrors "Coves/internal/core/errors"
 )
 
 // Domain errors for communities
 var (
 	// ErrCommunityNotFound is returned when a community doesn't exist
-	ErrCommunityNotFound = errors.New("community not found")
+	ErrCommunityNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "community not found")
 
 	// ErrCommunityAlreadyExists is returned when trying to create a community with duplicate DID
-	ErrCommunityAlreadyExists = errors.New("community already exists")
+	ErrCommunityAlreadyExists = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "community already exists")
 
 	// ErrHandleTaken is returned when a community handle is already in use
-	ErrHandleTaken = errors.New("community handle is already taken")
+	ErrHandleTaken = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "community handle is already taken")
 
 	// ErrInvalidHandle is returned when a handle doesn't match the required format
-	ErrInvalidHandle = errors.New("invalid community handle format")
+	ErrInvalidHandle = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid community handle format")
 
 	// ErrInvalidVisibility is returned when visibility value is not valid
-	ErrInvalidVisibility = errors.New("invalid visibility value")
+	ErrInvalidVisibility = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid visibility value")
 
 	// ErrUnauthorized is returned when a user lacks permission for an action
-	ErrUnauthorized = errors.New("unauthorized")
+	ErrUnauthorized = coreerrors.Sentinel(coreerrors.ErrForbidden, "unauthorized")
 
 	// ErrSubscriptionAlreadyExists is returned when user is already subscribed
-	ErrSubscriptionAlreadyExists = errors.New("already subscribed to this community")
+	ErrSubscriptionAlreadyExists = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "already subscribed to this community")
 
 	// ErrSubscriptionNotFound is returned when subscription doesn't exist
-	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubscriptionNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "subscription not found")
+
+	// ErrInvalidCursor is returned for a pagination cursor that is malformed or not signed by this instance
+	ErrInvalidCursor = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid pagination cursor")
 
 	// ErrBlockNotFound is returned when block doesn't exist
-	ErrBlockNotFound = errors.New("block not found")
+	ErrBlockNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "block not found")
 
 	// ErrBlockAlreadyExists is returned when user has already blocked the community
-	ErrBlockAlreadyExists = errors.New("community already blocked")
+	ErrBlockAlreadyExists = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "community already blocked")
+
+	// ErrMembershipAlreadyExists is returned when creating a membership that already exists
+	ErrMembershipAlreadyExists = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "membership already exists")
 
 	// ErrMembershipNotFound is returned when membership doesn't exist
-	ErrMembershipNotFound = errors.New("membership not found")
+	ErrMembershipNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "membership not found")
 
 	// ErrMemberBanned is returned when trying to perform action as banned member
-	ErrMemberBanned = errors.New("user is banned from this community")
+	ErrMemberBanned = coreerrors.Sentinel(coreerrors.ErrForbidden, "user is banned from this community")
+
+	// ErrFederationBlocked is returned when the community's hosting instance is blocked by federation policy
+	ErrFederationBlocked = coreerrors.Sentinel(coreerrors.ErrForbidden, "community is hosted on an instance blocked by federation policy")
+
+	// ErrCommunitySuspended is returned when an admin has suspended the community
+	ErrCommunitySuspended = coreerrors.Sentinel(coreerrors.ErrForbidden, "community has been suspended by this instance")
+
+	// ErrCommunityDeleted is returned when the community has been deleted by its owner or an admin
+	ErrCommunityDeleted = coreerrors.Sentinel(coreerrors.ErrNotFound, "community has been deleted")
+
+	// ErrCommunityNotDeleted is returned when undeleting a community that isn't deleted
+	ErrCommunityNotDeleted = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "community is not deleted")
+
+	// ErrDeletionGracePeriodOver is returned when undeleting a community after its grace period
+	ErrDeletionGracePeriodOver = coreerrors.Sentinel(coreerrors.ErrForbidden, "community deletion can no longer be undone")
+
+	// ErrCommunityNotFlagged is returned when reviewing a community that isn't flagged for impersonation
+	ErrCommunityNotFlagged = errors.New("community is not flagged for impersonation")
+
+	// ErrDIDWebDocumentNotFound is returned when no did:web document is hosted for a DID or handle
+	ErrDIDWebDocumentNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "did:web document not found")
+
+	// ErrHostVerificationNotFound is returned when no hostedBy verification result is stored
+	ErrHostVerificationNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "host verification not found")
+
+	// ErrInvalidCommunityName is returned when a name fails the creation policy's syntax rules
+	ErrInvalidCommunityName = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "community name must be 3-30 characters of lowercase letters, digits, and hyphens")
+
+	// ErrCommunityNameReserved is returned when a name is on the reserved list
+	ErrCommunityNameReserved = errors.New("community name is reserved")
+
+	// ErrReservedNameNotFound is returned when removing a name that isn't reserved
+	ErrReservedNameNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "reserved name not found")
+
+	// ErrReservedNameBuiltIn is returned when removing a hardcoded reserved name
+	ErrReservedNameBuiltIn = errors.New("built-in reserved names cannot be removed")
+
+	// ErrTooManyCommunities is returned when a user exceeds the community creation rate limit
+	ErrTooManyCommunities = errors.New("community creation limit reached")
+
+	// ErrAccountTooNew is returned when the creator's account is younger than the minimum age
+	ErrAccountTooNew = errors.New("account is too new to create communities")
+
+	// ErrRulesNotFound is returned when a community has no rules document
+	ErrRulesNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "community rules not found")
+
+	// ErrRulesTooLarge is returned when a rules document exceeds MaxRulesMarkdownBytes
+	ErrRulesTooLarge = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "community rules document too large")
+
+	// ErrStaleRevision is returned when a firehose write is older than the indexed version of the record
+	ErrStaleRevision = errors.New("community record write is older than the indexed revision")
+
+	// ErrProvisioningNotFound is returned when a community name has no creation in progress
+	ErrProvisioningNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "community provisioning not found")
+
+	// ErrProvisioningNotStuck is returned when cleaning up a creation that finished or may still be retried
+	ErrProvisioningNotStuck = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "community provisioning is not stuck")
 
 	// ErrInvalidInput is returned for general validation failures
-	ErrInvalidInput = errors.New("invalid input")
+	ErrInvalidInput = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid input")
 )
 
 // ValidationError wraps input validation errors with field details
@@ -78,11 +143,12 @@ func IsConflict(err error) bool {
 	return errors.Is(err, ErrCommunityAlreadyExists) ||
 		errors.Is(err, ErrHandleTaken) ||
 		errors.Is(err, ErrSubscriptionAlreadyExists) ||
-		errors.Is(err, ErrBlockAlreadyExists)
+		errors.Is(err, ErrBlockAlreadyExists) ||
+		errors.Is(err, ErrMembershipAlreadyExists)
 }
 
 // IsValidationError checks if error is a validation error
 func IsValidationError(err error) bool {
 	var valErr *ValidationError
-	return errors.As(err, &valErr) || errors.Is(err, ErrInvalidInput)
+	return errors.As(err, &valErr) || errors.Is(err, ErrInvalidInput) || errors.Is(err, ErrRulesTooLarge)
 }
@@ -0,0 +1,97 @@
+package communities
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"unicode/utf8"
+)
+
+const (
+	// MaxFlairs is the maximum number of flairs a community can define
+	MaxFlairs = 20
+
+	// MaxFlairNameLength is the maximum length of a flair name in characters
+	MaxFlairNameLength = 32
+)
+
+// flairColorPattern matches a hex color like "#1e90ff"
+var flairColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Flair is a post flair defined in the community profile record (e.g. "Discussion", "News")
+// Posts reference flairs by name in their tags array
+type Flair struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// ValidateFlairs checks a community's flair set for community.update
+// Returns a ValidationError for too many flairs, bad names or colors, and duplicate names
+func ValidateFlairs(flairs []Flair) error {
+	if len(flairs) > MaxFlairs {
+		return NewValidationError("flairs", fmt.Sprintf("at most %d flairs allowed", MaxFlairs))
+	}
+
+	seen := make(map[string]bool, len(flairs))
+	for i, flair := range flairs {
+		if err := validateFlair(flair); err != nil {
+			return NewValidationError(fmt.Sprintf("flairs[%d]", i), err.Error())
+		}
+		if seen[flair.Name] {
+			return NewValidationError(fmt.Sprintf("flairs[%d]", i), fmt.Sprintf("duplicate flair name %q", flair.Name))
+		}
+		seen[flair.Name] = true
+	}
+
+	return nil
+}
+
+// NormalizeFlairs returns the valid flairs from a firehose profile record
+// Records can come from any PDS, so invalid and duplicate flairs are dropped instead of
+// rejecting the whole profile, and the set is capped at MaxFlairs
+func NormalizeFlairs(flairs []Flair) []Flair {
+	result := make([]Flair, 0, min(len(flairs), MaxFlairs))
+	seen := make(map[string]bool, len(flairs))
+	for _, flair := range flairs {
+		if len(result) == MaxFlairs {
+			break
+		}
+		if validateFlair(flair) != nil || seen[flair.Name] {
+			continue
+		}
+		seen[flair.Name] = true
+		result = append(result, flair)
+	}
+	return result
+}
+
+// validateFlair checks a single flair's name and color
+func validateFlair(flair Flair) error {
+	if flair.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if utf8.RuneCountInString(flair.Name) > MaxFlairNameLength {
+		return fmt.Errorf("name must be at most %d characters", MaxFlairNameLength)
+	}
+	if !flairColorPattern.MatchString(flair.Color) {
+		return fmt.Errorf("color must be a hex color like #1e90ff")
+	}
+	return nil
+}
+
+// FilterPostTags returns the tags that match one of the community's flairs
+// Unknown tags are dropped rather than rejecting the post; order is kept and duplicates removed
+func (c *Community) FilterPostTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if c.HasFlair(tag) && !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
+
+// HasFlair reports whether the community currently defines a flair with this name
+func (c *Community) HasFlair(name string) bool {
+	return slices.ContainsFunc(c.Flairs, func(flair Flair) bool { return flair.Name == name })
+}
@@ -0,0 +1,31 @@
+package communities
+
+import (
+	"context"
+	"time"
+)
+
+// HostVerification is the result of verifying a hosting instance's did:web document
+// (the document exists and claims the instance's domain in alsoKnownAs)
+type HostVerification struct {
+	VerifiedAt time.Time
+	ExpiresAt  time.Time
+	Domain     string // Domain the DID document was fetched from
+	DID        string // hostedBy DID (did:web:{domain})
+	Reason     string // Why verification failed; empty when Valid
+	Valid      bool
+}
+
+// HostVerificationRepository persists hostedBy verification results so restarts don't
+// re-verify every instance, and flags communities whose host no longer verifies
+type HostVerificationRepository interface {
+	GetHostVerification(ctx context.Context, domain, did string) (*HostVerification, error)
+	SaveHostVerification(ctx context.Context, verification *HostVerification) error
+
+	// ListHostedByDIDs returns the distinct did:web hostedBy DIDs of indexed communities
+	ListHostedByDIDs(ctx context.Context) ([]string, error)
+
+	// SetHostedByUnverified flags (or clears) every community hosted by hostedByDID,
+	// returning how many communities changed
+	SetHostedByUnverified(ctx context.Context, hostedByDID string, unverified bool) (int, error)
+}
@@ -0,0 +1,110 @@
+package communities
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Community handles can be written in a name or display name as !name@domain, name@domain,
+// or the canonical c-name.domain. Matching is on the lowercased text.
+var (
+	atHandlePattern        = regexp.MustCompile(`(?:^|[^a-z0-9-])!?([a-z0-9][a-z0-9-]*)@((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,})`)
+	canonicalHandlePattern = regexp.MustCompile(`(?:^|[^a-z0-9-])c-([a-z0-9][a-z0-9-]*)\.((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z]{2,})`)
+)
+
+// ClaimedHandle is a community handle written into a community's name or display name
+type ClaimedHandle struct {
+	Handle string // Canonical form: c-{name}.{domain}
+	Domain string
+}
+
+// DisplayHandle returns the claimed handle in !name@domain form
+func (h ClaimedHandle) DisplayHandle() string {
+	name := strings.TrimSuffix(strings.TrimPrefix(h.Handle, "c-"), "."+h.Domain)
+	return fmt.Sprintf("!%s@%s", name, h.Domain)
+}
+
+// ClaimedHandles returns the distinct community handles written in s, in order of appearance
+func ClaimedHandles(s string) []ClaimedHandle {
+	s = strings.ToLower(s)
+
+	type match struct {
+		claim ClaimedHandle
+		at    int
+	}
+	var matches []match
+	for _, pattern := range []*regexp.Regexp{atHandlePattern, canonicalHandlePattern} {
+		for _, m := range pattern.FindAllStringSubmatchIndex(s, -1) {
+			name, domain := s[m[2]:m[3]], s[m[4]:m[5]]
+			matches = append(matches, match{
+				claim: ClaimedHandle{Handle: "c-" + name + "." + domain, Domain: domain},
+				at:    m[2],
+			})
+		}
+	}
+
+	// Two patterns, so merge back into text order
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].at < matches[j].at })
+
+	seen := make(map[string]bool, len(matches))
+	claims := make([]ClaimedHandle, 0, len(matches))
+	for _, m := range matches {
+		if !seen[m.claim.Handle] {
+			seen[m.claim.Handle] = true
+			claims = append(claims, m.claim)
+		}
+	}
+	return claims
+}
+
+// ImpersonationCheck is the input to DetectImpersonation
+type ImpersonationCheck struct {
+	VerifiedHandles map[string]bool // Claimed handles that belong to other verified communities
+	Name            string
+	DisplayName     string
+	Handle          string // The community's own canonical handle
+	HostedByDomain  string // Domain of the community's verified hostedBy did:web
+}
+
+// DetectImpersonation returns why a community profile looks like it impersonates another
+// community, or "" when it doesn't
+// A profile is flagged when its name or display name claims a handle on a domain other than
+// the one hosting it, or claims the handle of another verified community. Claiming its own
+// handle is fine.
+func DetectImpersonation(check ImpersonationCheck) string {
+	hostedBy := strings.ToLower(check.HostedByDomain)
+	own := strings.ToLower(check.Handle)
+
+	for _, field := range []struct{ label, text string }{
+		{"name", check.Name},
+		{"displayName", check.DisplayName},
+	} {
+		for _, claim := range ClaimedHandles(field.text) {
+			if claim.Handle == own {
+				continue
+			}
+			if hostedBy != "" && claim.Domain != hostedBy && !strings.HasSuffix(claim.Domain, "."+hostedBy) {
+				return fmt.Sprintf("%s claims %s but the community is hosted by %s", field.label, claim.DisplayHandle(), hostedBy)
+			}
+			if check.VerifiedHandles[claim.Handle] {
+				return fmt.Sprintf("%s claims the handle of verified community %s", field.label, claim.DisplayHandle())
+			}
+		}
+	}
+	return ""
+}
+
+// ImpersonationRepository lets admins review communities flagged as impersonating others
+type ImpersonationRepository interface {
+	// ListFlaggedCommunities returns flagged communities that aren't suspended, most recently updated first
+	ListFlaggedCommunities(ctx context.Context, limit, offset int) ([]*Community, error)
+
+	// ClearImpersonationFlag unflags a community; the cleared reason isn't flagged again
+	ClearImpersonationFlag(ctx context.Context, did string) error
+
+	// ConfirmImpersonation suspends a flagged community
+	ConfirmImpersonation(ctx context.Context, did string) error
+}
@@ -2,6 +2,7 @@ package communities
 
 import (
 	"context"
+	"time"
 
 	"github.com/bluesky-social/indigo/atproto/auth/oauth"
 )
@@ -20,7 +21,7 @@ type Repository interface {
 	UpdateCredentials(ctx context.Context, did, accessToken, refreshToken string) error
 
 	// Listing & Search
-	List(ctx context.Context, req ListCommunitiesRequest) ([]*Community, error)
+	List(ctx context.Context, req ListCommunitiesRequest) ([]*Community, *string, error) // Next cursor only for keyset sorts (relevance)
 	Search(ctx context.Context, req SearchCommunitiesRequest) ([]*Community, int, error)
 
 	// Subscriptions (lightweight feed follows)
@@ -30,9 +31,16 @@ type Repository interface {
 	UnsubscribeWithCount(ctx context.Context, userDID, communityDID string) error // Atomic: unsubscribe + decrement count
 	GetSubscription(ctx context.Context, userDID, communityDID string) (*Subscription, error)
 	GetSubscriptionByURI(ctx context.Context, recordURI string) (*Subscription, error) // For Jetstream delete operations
-	ListSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*Subscription, error)
-	ListSubscribers(ctx context.Context, communityDID string, limit, offset int) ([]*Subscription, error)
+	ListSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*Subscription, *string, error)
+	ListSubscribedCommunities(ctx context.Context, req GetSubscriptionsRequest) ([]*SubscribedCommunity, error) // Hydrated with community data
+	ListSubscribers(ctx context.Context, communityDID string, limit int, cursor *string) ([]*Subscription, *string, error)
 	GetSubscribedCommunityDIDs(ctx context.Context, userDID string, communityDIDs []string) (map[string]bool, error)
+	GetSubscribedUserDIDs(ctx context.Context, communityDID string, userDIDs []string) (map[string]bool, error) // Which of the users subscribe to the community
+
+	// Optimistic subscriptions, indexed from write-forward before the Jetstream event arrives
+	SubscribePendingWithCount(ctx context.Context, subscription *Subscription) (*Subscription, error) // Atomic: pending subscribe + increment count
+	ConfirmSubscription(ctx context.Context, recordURI, recordCID string) (bool, error)               // Clears the pending flag, reports whether a pending row matched
+	DeleteUnconfirmedSubscriptions(ctx context.Context, before time.Time) (int, error)                // Reaps pending rows and reverts counts
 
 	// Community Blocks
 	BlockCommunity(ctx context.Context, block *CommunityBlock) (*CommunityBlock, error)
@@ -42,11 +50,18 @@ type Repository interface {
 	ListBlockedCommunities(ctx context.Context, userDID string, limit, offset int) ([]*CommunityBlock, error)
 	IsBlocked(ctx context.Context, userDID, communityDID string) (bool, error)
 
+	// Optimistic blocks, indexed from write-forward before the Jetstream event arrives
+	BlockCommunityPending(ctx context.Context, block *CommunityBlock) (*CommunityBlock, error)
+	ConfirmBlock(ctx context.Context, recordURI, recordCID string) (bool, error)
+	DeleteUnconfirmedBlocks(ctx context.Context, before time.Time) (int, error)
+
 	// Memberships (active participation with reputation)
 	CreateMembership(ctx context.Context, membership *Membership) (*Membership, error)
 	GetMembership(ctx context.Context, userDID, communityDID string) (*Membership, error)
 	UpdateMembership(ctx context.Context, membership *Membership) (*Membership, error)
 	ListMembers(ctx context.Context, communityDID string, limit, offset int) ([]*Membership, error)
+	GetModeratedCommunityDIDs(ctx context.Context, userDID string, communityDIDs []string) (map[string]bool, error) // Communities the user created or moderates
+	GetModeratorsForCommunities(ctx context.Context, communityDIDs []string) (map[string]map[string]bool, error)    // Each community's creator and moderators, for author badges
 
 	// Moderation (V2 feature, prepared interface)
 	CreateModerationAction(ctx context.Context, action *ModerationAction) (*ModerationAction, error)
@@ -57,6 +72,9 @@ type Repository interface {
 	DecrementMemberCount(ctx context.Context, communityDID string) error
 	IncrementSubscriberCount(ctx context.Context, communityDID string) error
 	DecrementSubscriberCount(ctx context.Context, communityDID string) error
+	AdjustSubscriberCounts(ctx context.Context, deltas map[string]int) error // Applies coalesced deltas, one UPDATE per community
+	RecountSubscriberCounts(ctx context.Context) (int, error)                // Recomputes counts from subscriptions, returns communities corrected
+	RecountPostCounts(ctx context.Context) (int, error)                      // Recomputes counts from live posts, returns communities corrected
 	IncrementPostCount(ctx context.Context, communityDID string) error
 }
 
@@ -67,15 +85,21 @@ type Service interface {
 	CreateCommunity(ctx context.Context, req CreateCommunityRequest) (*Community, error)
 	GetCommunity(ctx context.Context, identifier string) (*Community, error) // identifier can be DID or handle
 	UpdateCommunity(ctx context.Context, req UpdateCommunityRequest) (*Community, error)
-	ListCommunities(ctx context.Context, req ListCommunitiesRequest) ([]*Community, error)
+	GetCommunityRules(ctx context.Context, communityDID string) (*CommunityRules, error)
+	UpdateCommunityRules(ctx context.Context, req UpdateRulesRequest) (*CommunityRules, error) // Owner-only, write-forward
+	DeleteCommunity(ctx context.Context, req DeleteCommunityRequest) (*Community, error)       // Owner or admin, soft delete
+	UndeleteCommunity(ctx context.Context, req DeleteCommunityRequest) (*Community, error)     // Within DeletionGracePeriod
+	ListCommunities(ctx context.Context, req ListCommunitiesRequest) ([]*Community, *string, error)
 	SearchCommunities(ctx context.Context, req SearchCommunitiesRequest) ([]*Community, int, error)
 
 	// Subscription operations (write-forward: creates record in user's PDS)
 	// OAuth session is passed for DPoP authentication to the user's PDS
 	SubscribeToCommunity(ctx context.Context, session *oauth.ClientSessionData, communityIdentifier string, contentVisibility int) (*Subscription, error)
 	UnsubscribeFromCommunity(ctx context.Context, session *oauth.ClientSessionData, communityIdentifier string) error
-	GetUserSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*Subscription, error)
-	GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit, offset int) ([]*Subscription, error)
+	GetUserSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*Subscription, *string, error)
+	GetSubscribedCommunities(ctx context.Context, req GetSubscriptionsRequest) ([]*SubscribedCommunity, error)
+	GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit int, cursor *string) ([]*Subscription, *string, error)
+	IsSubscribed(ctx context.Context, userDID, communityDID string) (bool, error)
 
 	// Block operations (write-forward: creates record in user's PDS)
 	// OAuth session is passed for DPoP authentication to the user's PDS
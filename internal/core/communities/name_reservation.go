@@ -0,0 +1,64 @@
+package communities
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// NameReservationTTL is how long a creation holds its name
+// Creations release the name when they finish; the TTL only frees names held by requests that
+// crashed, and must outlast a slow creation (account, blob uploads, profile record).
+const NameReservationTTL = 5 * time.Minute
+
+// NameReservationRepository holds community names while their creation is in flight, so
+// concurrent creations of one name can't both provision a PDS account
+// Unrelated to the creation policy's admin-reserved names, which are never creatable.
+type NameReservationRepository interface {
+	// Reserve holds name for token until expiresAt, taking over an expired reservation
+	// Returns ErrHandleTaken while another request's reservation holds the name.
+	Reserve(ctx context.Context, name, token string, expiresAt time.Time) error
+
+	// Release drops the reservation if token still holds it
+	Release(ctx context.Context, name, token string) error
+
+	// DeleteExpired removes reservations that expired before cutoff and returns how many
+	DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// NormalizeCommunityName returns the form of name its PDS handle is generated from
+// Names that normalize alike get the same handle, so they're reserved as one.
+func NormalizeCommunityName(name string) string {
+	return strings.ToLower(name)
+}
+
+// SetNameReservationRepository makes concurrent creations of one name fail fast
+// Without it, the second creation fails at the PDS on the handle the first one took.
+func (s *communityService) SetNameReservationRepository(reservations NameReservationRepository) {
+	s.nameReservations = reservations
+}
+
+// reserveName holds the community name for this creation and returns the func releasing it
+func (s *communityService) reserveName(ctx context.Context, name string) (func(), error) {
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, fmt.Errorf("failed to generate reservation token: %w", err)
+	}
+	token := hex.EncodeToString(tokenBytes)
+	normalized := NormalizeCommunityName(name)
+
+	if err := s.nameReservations.Reserve(ctx, normalized, token, time.Now().Add(NameReservationTTL)); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		// Release even if the request was canceled; otherwise the name is held until the TTL
+		if err := s.nameReservations.Release(context.WithoutCancel(ctx), normalized, token); err != nil {
+			log.Printf("[COMMUNITY-PROVISION] Name: %s, Event: release_failed, Error: %v", name, err)
+		}
+	}, nil
+}
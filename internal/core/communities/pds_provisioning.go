@@ -5,7 +5,6 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
-	"strings"
 
 	"github.com/bluesky-social/indigo/api/atproto"
 	comatproto "github.com/bluesky-social/indigo/api/atproto"
@@ -37,6 +36,12 @@ func (c *CommunityPDSAccount) GetPDSAccessToken() string {
 	return c.AccessToken
 }
 
+// AccountProvisioner creates the PDS account backing a new community
+// Implemented by PDSAccountProvisioner (did:plc) and DIDWebProvisioner (did:web)
+type AccountProvisioner interface {
+	ProvisionCommunityAccount(ctx context.Context, communityName string) (*CommunityPDSAccount, error)
+}
+
 // PDSAccountProvisioner creates PDS accounts for communities with PDS-managed DIDs
 type PDSAccountProvisioner struct {
 	instanceDomain string
@@ -82,11 +87,12 @@ func (p *PDSAccountProvisioner) ProvisionCommunityAccount(
 	// Format: c-{name}.{instance-domain}
 	// Example: "c-gaming.coves.social"
 	// Uses c- prefix to distinguish from user handles while keeping single-level subdomain
-	handle := fmt.Sprintf("c-%s.%s", strings.ToLower(communityName), p.instanceDomain)
+	// Normalized like name reservations, so names reserved as one get the same handle
+	handle := fmt.Sprintf("c-%s.%s", NormalizeCommunityName(communityName), p.instanceDomain)
 
 	// 2. Generate system email for PDS account management
 	// This email is used for account operations, not for user communication
-	email := fmt.Sprintf("c-%s@%s", strings.ToLower(communityName), p.instanceDomain)
+	email := fmt.Sprintf("c-%s@%s", NormalizeCommunityName(communityName), p.instanceDomain)
 
 	// 3. Generate secure random password (32 characters)
 	// This password is never shown to users - it's for Coves to authenticate as the community
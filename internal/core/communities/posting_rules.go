@@ -0,0 +1,10 @@
+package communities
+
+// PostingRules are constraints a community places on posts, set in the profile record
+// The post consumer enforces them at index time, since posts can reach a community's
+// repository without going through Coves' createPost endpoint
+type PostingRules struct {
+	// RequireAltText rejects image posts where any image has no alt text
+	// When false, such posts are indexed but counted in the alt text metrics
+	RequireAltText bool `json:"requireAltText"`
+}
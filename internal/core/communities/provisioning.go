@@ -0,0 +1,267 @@
+package communities
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Community creation progress, recorded so a creation interrupted by a PDS failure can resume
+const (
+	ProvisioningAccountCreated = "account_created" // PDS account exists; profile record not written yet
+	ProvisioningRecordWritten  = "record_written"  // Profile record written; community not stored yet
+	ProvisioningIndexed        = "indexed"         // Community stored with its credentials; creation finished
+)
+
+// StuckProvisioningAge is how long an unfinished creation is left for its creator to retry
+// before admins may clean it up
+const StuckProvisioningAge = 24 * time.Hour
+
+// Provisioning tracks one community creation through its steps
+type Provisioning struct {
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+	Name         string    `json:"name"`
+	CreatedByDID string    `json:"createdBy"`
+	DID          string    `json:"did"`
+	Handle       string    `json:"handle"`
+	State        string    `json:"state"`
+	LastError    string    `json:"lastError,omitempty"`
+	PDSURL       string    `json:"-"`
+	PDSEmail     string    `json:"-"`
+	PDSPassword  string    `json:"-"` // Cleartext; encrypted by the repository
+}
+
+// ProvisioningRepository stores community creation progress, keyed by community name
+type ProvisioningRepository interface {
+	// Save creates or replaces the provisioning for p.Name
+	Save(ctx context.Context, p *Provisioning) error
+
+	// Get returns ErrProvisioningNotFound if the name has no provisioning
+	Get(ctx context.Context, name string) (*Provisioning, error)
+
+	// ListStuck returns unfinished provisionings last updated before updatedBefore, oldest first
+	ListStuck(ctx context.Context, updatedBefore time.Time, limit int) ([]*Provisioning, error)
+
+	Delete(ctx context.Context, name string) error
+}
+
+// ProvisioningError reports a community creation that stopped partway
+// The PDS account exists; retrying the creation with the same name resumes from State.
+type ProvisioningError struct {
+	Err   error
+	Name  string
+	State string
+}
+
+func (e *ProvisioningError) Error() string {
+	return fmt.Sprintf("community %q creation incomplete (%s), retry to resume: %v", e.Name, e.State, e.Err)
+}
+
+func (e *ProvisioningError) Unwrap() error {
+	return e.Err
+}
+
+// errHandleNotResolved means the PDS doesn't know the handle (the account is gone)
+var errHandleNotResolved = errors.New("handle does not resolve")
+
+// SetProvisioningRepository enables resumable community creation
+// Without it, a creation that fails after the PDS account exists leaves the account orphaned.
+func (s *communityService) SetProvisioningRepository(provisionings ProvisioningRepository) {
+	s.provisionings = provisionings
+}
+
+// SetPDSAdminPassword configures the PDS admin password used to delete orphaned accounts
+// when admins clean up stuck provisionings
+func (s *communityService) SetPDSAdminPassword(password string) {
+	s.pdsAdminPassword = password
+}
+
+// resumeProvisioning returns the half-provisioned account left by the creator's earlier
+// attempt at this name, with a fresh session, or nils when there is nothing to resume
+func (s *communityService) resumeProvisioning(ctx context.Context, req CreateCommunityRequest) (*Provisioning, *CommunityPDSAccount, error) {
+	existing, err := s.provisionings.Get(ctx, req.Name)
+	if errors.Is(err, ErrProvisioningNotFound) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check community provisioning: %w", err)
+	}
+	if existing.State == ProvisioningIndexed {
+		// Finished earlier; a new attempt fails on the taken handle as usual
+		return nil, nil, nil
+	}
+	if existing.CreatedByDID != req.CreatedByDID {
+		return nil, nil, ErrHandleTaken
+	}
+
+	// The account must still be the one we provisioned; admins may have cleaned it up
+	did, err := resolvePDSHandle(ctx, existing.PDSURL, existing.Handle)
+	if errors.Is(err, errHandleNotResolved) {
+		log.Printf("[COMMUNITY-PROVISION] Name: %s, Event: stale_provisioning, Message: account is gone, starting over", req.Name)
+		if deleteErr := s.provisionings.Delete(ctx, req.Name); deleteErr != nil {
+			return nil, nil, fmt.Errorf("failed to clear stale provisioning: %w", deleteErr)
+		}
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, &ProvisioningError{Name: req.Name, State: existing.State, Err: err}
+	}
+	if did != existing.DID {
+		return nil, nil, ErrHandleTaken
+	}
+
+	accessToken, refreshToken, err := reauthenticateWithPassword(ctx, existing.PDSURL, existing.PDSEmail, existing.PDSPassword)
+	if err != nil {
+		return nil, nil, &ProvisioningError{Name: req.Name, State: existing.State, Err: err}
+	}
+
+	log.Printf("[COMMUNITY-PROVISION] Name: %s, Event: resumed, DID: %s, State: %s", req.Name, existing.DID, existing.State)
+	return existing, &CommunityPDSAccount{
+		DID:          existing.DID,
+		Handle:       existing.Handle,
+		Email:        existing.PDSEmail,
+		Password:     existing.PDSPassword,
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		PDSURL:       existing.PDSURL,
+	}, nil
+}
+
+// saveProvisioning records a creation step; nil progress means resumable creation is off
+// A failed write only costs resumability, so it's logged rather than failing the creation.
+func (s *communityService) saveProvisioning(ctx context.Context, progress *Provisioning, state string, stepErr error) {
+	if progress == nil {
+		return
+	}
+	progress.State = state
+	progress.LastError = ""
+	if stepErr != nil {
+		progress.LastError = stepErr.Error()
+	}
+	if err := s.provisionings.Save(ctx, progress); err != nil {
+		log.Printf("[COMMUNITY-PROVISION] Name: %s, Event: save_failed, State: %s, Error: %v", progress.Name, state, err)
+	}
+}
+
+// provisioningFailed records why a step failed and builds the error returned to the caller
+// Without resumable creation the step's error is returned as it was before.
+func (s *communityService) provisioningFailed(ctx context.Context, progress *Provisioning, err error) error {
+	if progress == nil {
+		return err
+	}
+	s.saveProvisioning(ctx, progress, progress.State, err)
+	log.Printf("[COMMUNITY-PROVISION] Name: %s, Event: incomplete, State: %s, Error: %v", progress.Name, progress.State, err)
+	return &ProvisioningError{Name: progress.Name, State: progress.State, Err: err}
+}
+
+// ListStuckProvisionings returns up to limit creations unfinished for StuckProvisioningAge, oldest first
+func (s *communityService) ListStuckProvisionings(ctx context.Context, limit int) ([]*Provisioning, error) {
+	if s.provisionings == nil {
+		return []*Provisioning{}, nil
+	}
+	return s.provisionings.ListStuck(ctx, time.Now().Add(-StuckProvisioningAge), limit)
+}
+
+// CleanupProvisioning deletes a stuck creation's orphaned PDS account and forgets it,
+// freeing the name. Creations updated within StuckProvisioningAge are left for their creator.
+func (s *communityService) CleanupProvisioning(ctx context.Context, name string) error {
+	if s.provisionings == nil {
+		return ErrProvisioningNotFound
+	}
+	progress, err := s.provisionings.Get(ctx, name)
+	if err != nil {
+		return err
+	}
+	if progress.State == ProvisioningIndexed || progress.UpdatedAt.After(time.Now().Add(-StuckProvisioningAge)) {
+		return ErrProvisioningNotStuck
+	}
+
+	// Only delete the account if the handle still points at it
+	did, err := resolvePDSHandle(ctx, progress.PDSURL, progress.Handle)
+	switch {
+	case errors.Is(err, errHandleNotResolved):
+		log.Printf("[COMMUNITY-PROVISION] Name: %s, Event: cleanup, Message: account already gone", name)
+	case err != nil:
+		return fmt.Errorf("failed to resolve orphaned account: %w", err)
+	case did == progress.DID:
+		if s.pdsAdminPassword == "" {
+			return fmt.Errorf("PDS admin password is not configured, cannot delete orphaned account %s", progress.DID)
+		}
+		if err := deletePDSAccountAsAdmin(ctx, progress.PDSURL, s.pdsAdminPassword, progress.DID); err != nil {
+			return err
+		}
+		log.Printf("[COMMUNITY-PROVISION] Name: %s, Event: cleanup, Message: deleted orphaned account %s", name, progress.DID)
+	}
+
+	return s.provisionings.Delete(ctx, name)
+}
+
+// resolvePDSHandle resolves a handle with the PDS's com.atproto.identity.resolveHandle
+// Returns errHandleNotResolved when the PDS answers that the handle is unknown.
+func resolvePDSHandle(ctx context.Context, pdsURL, handle string) (string, error) {
+	endpoint := strings.TrimSuffix(pdsURL, "/") + "/xrpc/com.atproto.identity.resolveHandle?handle=" + url.QueryEscape(handle)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call PDS: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusNotFound {
+		return "", errHandleNotResolved
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("PDS returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		DID string `json:"did"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil || result.DID == "" {
+		return "", fmt.Errorf("failed to parse resolveHandle response: %s", string(body))
+	}
+	return result.DID, nil
+}
+
+// deletePDSAccountAsAdmin deletes an account with com.atproto.admin.deleteAccount
+// The account's own deleteAccount needs an emailed token, so cleanup uses the PDS admin login.
+func deletePDSAccountAsAdmin(ctx context.Context, pdsURL, adminPassword, did string) error {
+	payload, err := json.Marshal(map[string]string{"did": did})
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	endpoint := strings.TrimSuffix(pdsURL, "/") + "/xrpc/com.atproto.admin.deleteAccount"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(string(payload)))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth("admin", adminPassword)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call PDS: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("PDS returned status %d deleting account %s: %s", resp.StatusCode, did, string(body))
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package communities
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// MaxRulesMarkdownBytes is the maximum size of a community's welcome/rules document
+	MaxRulesMarkdownBytes = 20000
+
+	// MaxRules is the maximum number of structured rules in a rules record
+	MaxRules = 20
+
+	// MaxRuleTitleLength and MaxRuleDescriptionLength bound a structured rule, in characters
+	MaxRuleTitleLength       = 256
+	MaxRuleDescriptionLength = 2000
+)
+
+// Rule is a structured community rule, shown alongside the markdown document
+// (e.g. in a report dialog as the reason for removal)
+type Rule struct {
+	CreatedAt   time.Time `json:"createdAt"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+}
+
+// CommunityRules is a community's welcome/rules document, indexed from the
+// social.coves.community.rules record (rkey self) in the community's repository
+// Markdown is sanitized before it's stored, so clients can render it as-is
+type CommunityRules struct {
+	UpdatedAt    time.Time `json:"updatedAt"`
+	CommunityDID string    `json:"-"`
+	Markdown     string    `json:"markdown,omitempty"`
+	RecordURI    string    `json:"uri"`
+	RecordCID    string    `json:"cid"`
+	Rev          string    `json:"-"` // Repo rev of the commit that wrote the record
+	Rules        []Rule    `json:"rules"`
+}
+
+// UpdateRulesRequest replaces a community's rules document via social.coves.community.updateRules
+type UpdateRulesRequest struct {
+	CommunityDID string `json:"community"`
+	UpdatedByDID string `json:"-"` // Set from the authenticated user
+	Markdown     string `json:"markdown"`
+	Rules        []Rule `json:"rules"`
+}
+
+// RulesRepository stores indexed community rules documents
+type RulesRepository interface {
+	GetRules(ctx context.Context, communityDID string) (*CommunityRules, error)
+	UpsertRules(ctx context.Context, rules *CommunityRules) error
+	DeleteRules(ctx context.Context, communityDID string) error
+}
+
+// ValidateRules checks a rules document's markdown size and structured rules
+// Returns ErrRulesTooLarge for oversize markdown and a ValidationError for bad rules
+func ValidateRules(markdown string, rules []Rule) error {
+	if len(markdown) > MaxRulesMarkdownBytes {
+		return fmt.Errorf("%w: markdown is %d bytes, at most %d allowed", ErrRulesTooLarge, len(markdown), MaxRulesMarkdownBytes)
+	}
+	if len(rules) > MaxRules {
+		return NewValidationError("rules", fmt.Sprintf("at most %d rules allowed", MaxRules))
+	}
+
+	for i, rule := range rules {
+		field := fmt.Sprintf("rules[%d]", i)
+		if strings.TrimSpace(rule.Title) == "" {
+			return NewValidationError(field, "title is required")
+		}
+		if utf8.RuneCountInString(rule.Title) > MaxRuleTitleLength {
+			return NewValidationError(field, fmt.Sprintf("title must be at most %d characters", MaxRuleTitleLength))
+		}
+		if utf8.RuneCountInString(rule.Description) > MaxRuleDescriptionLength {
+			return NewValidationError(field, fmt.Sprintf("description must be at most %d characters", MaxRuleDescriptionLength))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package communities
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxScoreHidingHours caps how long a community can hide vote counts on new content
+const MaxScoreHidingHours = 24
+
+// ValidateScoreHidingHours checks a score hiding window from community.update
+func ValidateScoreHidingHours(hours int) error {
+	if hours < 0 || hours > MaxScoreHidingHours {
+		return NewValidationError("scoreHidingHours", fmt.Sprintf("must be between 0 and %d", MaxScoreHidingHours))
+	}
+	return nil
+}
+
+// NormalizeScoreHidingHours clamps a score hiding window from a firehose profile record
+// Records can come from any PDS, so out-of-range values are clamped instead of rejected
+func NormalizeScoreHidingHours(hours int) int {
+	return min(max(hours, 0), MaxScoreHidingHours)
+}
+
+// ScoresHidden reports whether vote counts on content created at createdAt are still hidden
+// at now under a community's score hiding window. The window is half-open: counts become
+// visible exactly hours after creation.
+func ScoresHidden(hours int, createdAt, now time.Time) bool {
+	return hours > 0 && now.Sub(createdAt) < time.Duration(hours)*time.Hour
+}
@@ -4,7 +4,11 @@ import (
 	oauthclient "Coves/internal/atproto/oauth"
 	"Coves/internal/atproto/pds"
 	"Coves/internal/atproto/utils"
+	"Coves/internal/core/audit"
 	"Coves/internal/core/blobs"
+	"Coves/internal/db/txrunner"
+	"Coves/internal/lexicon/validate"
+	"Coves/internal/sanitize"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -17,6 +21,7 @@ import (
 	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/bluesky-social/indigo/atproto/auth/oauth"
 	"github.com/bluesky-social/indigo/atproto/syntax"
@@ -39,9 +44,33 @@ type PDSClientFactory func(ctx context.Context, session *oauth.ClientSessionData
 type communityService struct {
 	// Interfaces and pointers first (better alignment)
 	repo        Repository
-	provisioner *PDSAccountProvisioner
+	provisioner AccountProvisioner
 	blobService blobs.Service
 
+	// Optional creation limits (rate limit, account age, reserved names); nil disables them
+	creationPolicy *CreationPolicy
+
+	// Optional indexed rules documents; nil means no community has rules
+	rules RulesRepository
+
+	// Optional deletion state; nil disables DeleteCommunity and UndeleteCommunity
+	deletions DeletionRepository
+
+	// Instance admins may delete and undelete any community
+	instanceAdmins map[string]bool
+
+	// Optional transaction runner for multi-repository writes; nil runs them without one
+	txRunner txrunner.Runner
+
+	// Optional creation progress; nil makes creation non-resumable
+	provisionings ProvisioningRepository
+
+	// Optional names held by creations in flight; nil lets concurrent creations race at the PDS
+	nameReservations NameReservationRepository
+
+	// Optional audit log for deletions and rules updates; nil records nothing
+	auditLog audit.Recorder
+
 	// OAuth client for user PDS authentication (DPoP-based)
 	oauthClient      *oauthclient.OAuthClient
 	pdsClientFactory PDSClientFactory // Optional, for testing. If nil, uses OAuth.
@@ -56,6 +85,9 @@ type communityService struct {
 	instanceDomain string
 	pdsAccessToken string
 
+	// PDS admin password, used to delete orphaned accounts of stuck creations
+	pdsAdminPassword string
+
 	// Sync primitives last
 	mapMutex sync.RWMutex // Protects refreshMutexes map itself
 }
@@ -65,13 +97,17 @@ const (
 	// At 10,000 entries × 16 bytes = ~160KB memory (negligible overhead)
 	// Map can grow larger in production - even 100,000 entries = 1.6MB is acceptable
 	maxMutexCacheSize = 10000
+
+	// PendingConfirmationTimeout is how long an optimistically indexed subscription or block
+	// waits for its Jetstream event before the reaper assumes it was lost and reverts it
+	PendingConfirmationTimeout = 10 * time.Minute
 )
 
 // NewCommunityService creates a new community service with OAuth client for user authentication
 func NewCommunityService(
 	repo Repository,
 	pdsURL, instanceDID, instanceDomain string,
-	provisioner *PDSAccountProvisioner,
+	provisioner AccountProvisioner,
 	oauthClient *oauthclient.OAuthClient,
 	blobService blobs.Service,
 ) Service {
@@ -106,7 +142,7 @@ func NewCommunityService(
 func NewCommunityServiceWithPDSFactory(
 	repo Repository,
 	pdsURL, instanceDID, instanceDomain string,
-	provisioner *PDSAccountProvisioner,
+	provisioner AccountProvisioner,
 	factory PDSClientFactory,
 	blobService blobs.Service,
 ) Service {
@@ -128,6 +164,55 @@ func (s *communityService) SetPDSAccessToken(token string) {
 	s.pdsAccessToken = token
 }
 
+// SetCreationPolicy enables creation limits for CreateCommunity
+func (s *communityService) SetCreationPolicy(policy *CreationPolicy) {
+	s.creationPolicy = policy
+}
+
+// SetRulesRepository enables community rules documents for GetCommunityRules and UpdateCommunityRules
+func (s *communityService) SetRulesRepository(rules RulesRepository) {
+	s.rules = rules
+}
+
+// SetDeletionRepository enables DeleteCommunity, UndeleteCommunity and DeactivateDeletedCommunities
+func (s *communityService) SetDeletionRepository(deletions DeletionRepository) {
+	s.deletions = deletions
+}
+
+// SetTxRunner makes multi-repository writes (e.g. an admin deletion and its moderation
+// record) atomic; the repositories must run their queries through txrunner.From
+func (s *communityService) SetTxRunner(runner txrunner.Runner) {
+	s.txRunner = runner
+}
+
+// withTx runs fn in a transaction when a runner is configured
+func (s *communityService) withTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if s.txRunner == nil {
+		return fn(ctx)
+	}
+	return s.txRunner.WithTx(ctx, fn)
+}
+
+// SetAuditLog records community deletions, undeletions and rules updates to the audit log
+func (s *communityService) SetAuditLog(recorder audit.Recorder) {
+	s.auditLog = recorder
+}
+
+// recordAudit adds a successful action to the audit log, if one is configured
+func (s *communityService) recordAudit(ctx context.Context, actorDID, action, communityDID string, details interface{}) {
+	if s.auditLog != nil {
+		s.auditLog.Record(ctx, actorDID, action, communityDID, details)
+	}
+}
+
+// SetInstanceAdmins configures the instance admins, who may delete and undelete any community
+func (s *communityService) SetInstanceAdmins(adminDIDs []string) {
+	s.instanceAdmins = make(map[string]bool, len(adminDIDs))
+	for _, did := range adminDIDs {
+		s.instanceAdmins[did] = true
+	}
+}
+
 // getPDSClient creates a PDS client from an OAuth session.
 // If a custom factory was provided (for testing), uses that.
 // Otherwise, uses DPoP authentication via indigo's APIClient for proper OAuth token handling.
@@ -179,15 +264,77 @@ func (s *communityService) CreateCommunity(ctx context.Context, req CreateCommun
 		return nil, err
 	}
 
-	// V2: Provision a real PDS account for this community
-	// This calls com.atproto.server.createAccount internally
-	// The PDS will:
-	//   1. Generate a signing keypair (stored in PDS, we never see it)
-	//   2. Create a DID (did:plc:xxx)
-	//   3. Return credentials (DID, tokens)
-	pdsAccount, err := s.provisioner.ProvisionCommunityAccount(ctx, req.Name)
-	if err != nil {
-		return nil, fmt.Errorf("failed to provision PDS account for community: %w", err)
+	// Anti-squatting checks run before provisioning so rejected requests don't create PDS accounts
+	// The claim counts this creation toward the rate limit while it's in flight, so concurrent
+	// requests from one creator can't all pass it; a failed creation gives the slot back.
+	var claim *CreationClaim
+	if s.creationPolicy != nil {
+		if err := s.creationPolicy.Check(ctx, req.CreatedByDID, req.Name); err != nil {
+			return nil, err
+		}
+		var err error
+		claim, err = s.creationPolicy.ClaimCreation(ctx, req.CreatedByDID, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if claim != nil {
+				claim.Release(ctx)
+			}
+		}()
+	}
+
+	// Hold the name until this creation finishes, so a concurrent one fails here instead of at the PDS
+	if s.nameReservations != nil {
+		release, err := s.reserveName(ctx, req.Name)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	// Resume the creator's earlier attempt at this name if it stopped after the account was created
+	var progress *Provisioning
+	var pdsAccount *CommunityPDSAccount
+	if s.provisionings != nil {
+		var err error
+		progress, pdsAccount, err = s.resumeProvisioning(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resumed := pdsAccount != nil
+
+	if !resumed {
+		// V2: Provision a real PDS account for this community
+		// This calls com.atproto.server.createAccount internally
+		// The PDS will:
+		//   1. Generate a signing keypair (stored in PDS, we never see it)
+		//   2. Create a DID (did:plc:xxx)
+		//   3. Return credentials (DID, tokens)
+		// A name that already has a community is taken; provisioning it would collide with
+		// the live community's account (and, for did:web, its DID document)
+		if err := s.checkNameAvailable(ctx, req.Name); err != nil {
+			return nil, err
+		}
+		var err error
+		pdsAccount, err = s.provisioner.ProvisionCommunityAccount(ctx, req.Name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to provision PDS account for community: %w", err)
+		}
+
+		if s.provisionings != nil {
+			progress = &Provisioning{
+				Name:         req.Name,
+				CreatedByDID: req.CreatedByDID,
+				DID:          pdsAccount.DID,
+				Handle:       pdsAccount.Handle,
+				PDSURL:       pdsAccount.PDSURL,
+				PDSEmail:     pdsAccount.Email,
+				PDSPassword:  pdsAccount.Password,
+			}
+			s.saveProvisioning(ctx, progress, ProvisioningAccountCreated, nil)
+		}
 	}
 
 	// Validate the atProto handle
@@ -238,7 +385,7 @@ func (s *communityService) CreateCommunity(ctx context.Context, req CreateCommun
 		}
 		avatarRef, err := s.blobService.UploadBlob(ctx, pdsAccount, req.AvatarBlob, req.AvatarMimeType)
 		if err != nil {
-			return nil, fmt.Errorf("failed to upload avatar: %w", err)
+			return nil, s.provisioningFailed(ctx, progress, fmt.Errorf("failed to upload avatar: %w", err))
 		}
 		profile["avatar"] = map[string]interface{}{
 			"$type":    avatarRef.Type,
@@ -260,7 +407,7 @@ func (s *communityService) CreateCommunity(ctx context.Context, req CreateCommun
 		}
 		bannerRef, err := s.blobService.UploadBlob(ctx, pdsAccount, req.BannerBlob, req.BannerMimeType)
 		if err != nil {
-			return nil, fmt.Errorf("failed to upload banner: %w", err)
+			return nil, s.provisioningFailed(ctx, progress, fmt.Errorf("failed to upload banner: %w", err))
 		}
 		profile["banner"] = map[string]interface{}{
 			"$type":    bannerRef.Type,
@@ -272,10 +419,20 @@ func (s *communityService) CreateCommunity(ctx context.Context, req CreateCommun
 		bannerCID = bannerRef.Ref["$link"]
 	}
 
+	// Validate the record with the consumer's rules so it isn't written only to be refused indexing
+	if err := validate.Value(validate.CommunityProfileCollection, profile); err != nil {
+		return nil, err
+	}
+
 	// V2: Write to COMMUNITY's own repository (not instance repo!)
 	// Repository: at://COMMUNITY_DID/social.coves.community.profile/self
 	// Authenticate using community's access token
-	recordURI, recordCID, err := s.createRecordOnPDSAs(
+	// A resumed creation may already have written the record, so it's put (upserted) instead
+	writeRecord := s.createRecordOnPDSAs
+	if resumed {
+		writeRecord = s.putRecordOnPDSAs
+	}
+	recordURI, recordCID, err := writeRecord(
 		ctx,
 		pdsAccount.DID, // repo = community's DID (community owns its repo!)
 		"social.coves.community.profile",
@@ -284,8 +441,9 @@ func (s *communityService) CreateCommunity(ctx context.Context, req CreateCommun
 		pdsAccount.AccessToken, // authenticate as the community
 	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create community profile record: %w", err)
+		return nil, s.provisioningFailed(ctx, progress, fmt.Errorf("failed to create community profile record: %w", err))
 	}
+	s.saveProvisioning(ctx, progress, ProvisioningRecordWritten, nil)
 
 	// Build Community object with PDS credentials AND cryptographic keys
 	community := &Community{
@@ -323,8 +481,21 @@ func (s *communityService) CreateCommunity(ctx context.Context, req CreateCommun
 	// 1. Update the community profile later (using its own credentials)
 	// 2. Re-authenticate if access tokens expire
 	_, err = s.repo.Create(ctx, community)
+	if resumed && errors.Is(err, ErrCommunityAlreadyExists) {
+		// An earlier attempt stored it but stopped before recording that; refresh its credentials
+		err = s.repo.UpdateCredentials(ctx, community.DID, community.PDSAccessToken, community.PDSRefreshToken)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to persist community with credentials: %w", err)
+		return nil, s.provisioningFailed(ctx, progress, fmt.Errorf("failed to persist community with credentials: %w", err))
+	}
+	s.saveProvisioning(ctx, progress, ProvisioningIndexed, nil)
+
+	if claim != nil {
+		// Community already exists at this point - the claim counts even without its DID
+		if err := claim.Complete(ctx, community.DID); err != nil {
+			log.Printf("WARNING: Failed to record community creation for %s: %v", req.CreatedByDID, err)
+		}
+		claim = nil
 	}
 
 	return community, nil
@@ -336,7 +507,24 @@ func (s *communityService) CreateCommunity(ctx context.Context, req CreateCommun
 //   - Scoped handle: !name@instance
 //   - At-identifier: @c-name.domain
 //   - Canonical handle: c-name.domain
+//
+// Suspended communities return ErrCommunitySuspended and deleted ones ErrCommunityDeleted
 func (s *communityService) GetCommunity(ctx context.Context, identifier string) (*Community, error) {
+	community, err := s.lookupCommunity(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if community.SuspendedAt != nil {
+		return nil, ErrCommunitySuspended
+	}
+	if community.DeletedAt != nil {
+		return nil, ErrCommunityDeleted
+	}
+	return community, nil
+}
+
+// lookupCommunity fetches a community by any identifier form accepted by GetCommunity
+func (s *communityService) lookupCommunity(ctx context.Context, identifier string) (*Community, error) {
 	originalIdentifier := identifier
 	identifier = strings.TrimSpace(identifier)
 
@@ -406,6 +594,36 @@ func (s *communityService) UpdateCommunity(ctx context.Context, req UpdateCommun
 		return nil, NewValidationError("updatedByDid", "required")
 	}
 
+	if req.Flairs != nil {
+		if err := ValidateFlairs(*req.Flairs); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.ScoreHidingHours != nil {
+		if err := ValidateScoreHidingHours(*req.ScoreHidingHours); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.CollapseThreshold != nil {
+		if err := ValidateCollapseThreshold(*req.CollapseThreshold); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.CrowdControl != nil {
+		if err := ValidateCrowdControl(*req.CrowdControl); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Categories != nil {
+		if err := ValidateCategories(*req.Categories); err != nil {
+			return nil, err
+		}
+	}
+
 	// Get existing community
 	existing, err := s.repo.GetByDID(ctx, req.CommunityDID)
 	if err != nil {
@@ -511,6 +729,62 @@ func (s *communityService) UpdateCommunity(ctx context.Context, req UpdateCommun
 		profile["contentWarnings"] = existing.ContentWarnings
 	}
 
+	// Categories are replaced wholesale; unknown ones indexed from an older record are kept
+	// until the owner changes them
+	categories := existing.Categories
+	if req.Categories != nil {
+		categories = *req.Categories
+	}
+	if len(categories) > 0 {
+		profile["categories"] = categories
+	}
+
+	// Flairs are replaced wholesale; posts keep tags of removed flairs, but they no longer filter
+	if req.Flairs != nil {
+		profile["flairs"] = *req.Flairs
+	} else if len(existing.Flairs) > 0 {
+		profile["flairs"] = existing.Flairs
+	}
+
+	// Posting rules only apply to posts indexed after the change
+	postingRules := existing.PostingRules
+	if req.PostingRules != nil {
+		postingRules = *req.PostingRules
+	}
+	profile["postingRules"] = postingRules
+
+	scoreHidingHours := existing.ScoreHidingHours
+	if req.ScoreHidingHours != nil {
+		scoreHidingHours = *req.ScoreHidingHours
+	}
+	if scoreHidingHours > 0 {
+		profile["scoreHidingHours"] = scoreHidingHours
+	}
+
+	// The threshold is always written: a record without one means the default
+	collapseThreshold := existing.CollapseThreshold
+	if req.CollapseThreshold != nil {
+		collapseThreshold = *req.CollapseThreshold
+	}
+	profile["collapseThreshold"] = collapseThreshold
+
+	crowdControl := NormalizeCrowdControl(existing.CrowdControl)
+	if req.CrowdControl != nil {
+		crowdControl = *req.CrowdControl
+	}
+	if crowdControl != CrowdControlOff {
+		profile["crowdControl"] = crowdControl
+	}
+
+	// The consumer indexes the record as the whole profile and clears optional fields it
+	// omits, so carry over the blobs and facets this request doesn't replace
+	var previous map[string]interface{}
+	if existing.RawRecord != nil {
+		if err := json.Unmarshal([]byte(*existing.RawRecord), &previous); err != nil {
+			log.Printf("WARNING: Failed to parse indexed profile record for %s: %v (avatar, banner and facets not carried over)", existing.DID, err)
+		}
+	}
+
 	// Add blob references if uploaded
 	if avatarRef != nil {
 		profile["avatar"] = map[string]interface{}{
@@ -519,6 +793,8 @@ func (s *communityService) UpdateCommunity(ctx context.Context, req UpdateCommun
 			"mimeType": avatarRef.MimeType,
 			"size":     avatarRef.Size,
 		}
+	} else if avatar, ok := previous["avatar"]; ok {
+		profile["avatar"] = avatar
 	}
 
 	if bannerRef != nil {
@@ -528,6 +804,15 @@ func (s *communityService) UpdateCommunity(ctx context.Context, req UpdateCommun
 			"mimeType": bannerRef.MimeType,
 			"size":     bannerRef.Size,
 		}
+	} else if banner, ok := previous["banner"]; ok {
+		profile["banner"] = banner
+	}
+
+	// Facets index into the description text; they only survive if it's unchanged
+	if req.Description == nil {
+		if facets, ok := previous["descriptionFacets"]; ok {
+			profile["descriptionFacets"] = facets
+		}
 	}
 
 	// V2: Community profiles always use "self" as rkey
@@ -540,6 +825,11 @@ func (s *communityService) UpdateCommunity(ctx context.Context, req UpdateCommun
 		return nil, fmt.Errorf("community %s missing PDS credentials - cannot update", existing.DID)
 	}
 
+	// Validate the record with the consumer's rules so it isn't written only to be refused indexing
+	if err := validate.Value(validate.CommunityProfileCollection, profile); err != nil {
+		return nil, err
+	}
+
 	recordURI, recordCID, err := s.putRecordOnPDSAs(
 		ctx,
 		existing.DID, // repo = community's own DID (V2!)
@@ -573,6 +863,14 @@ func (s *communityService) UpdateCommunity(ctx context.Context, req UpdateCommun
 	if len(req.ContentWarnings) > 0 {
 		updated.ContentWarnings = req.ContentWarnings
 	}
+	if req.Flairs != nil {
+		updated.Flairs = *req.Flairs
+	}
+	updated.Categories = categories
+	updated.PostingRules = postingRules
+	updated.ScoreHidingHours = scoreHidingHours
+	updated.CollapseThreshold = collapseThreshold
+	updated.CrowdControl = crowdControl
 	updated.RecordURI = recordURI
 	updated.RecordCID = recordCID
 	updated.UpdatedAt = time.Now()
@@ -580,6 +878,106 @@ func (s *communityService) UpdateCommunity(ctx context.Context, req UpdateCommun
 	return &updated, nil
 }
 
+// GetCommunityRules returns a community's indexed rules document
+// Returns ErrRulesNotFound when the community hasn't published one
+func (s *communityService) GetCommunityRules(ctx context.Context, communityDID string) (*CommunityRules, error) {
+	if s.rules == nil {
+		return nil, ErrRulesNotFound
+	}
+	return s.rules.GetRules(ctx, communityDID)
+}
+
+// UpdateCommunityRules replaces a community's rules document via write-forward to the
+// community's PDS (social.coves.community.rules, rkey self)
+// Only the community's creator may update its rules
+// The returned document carries the sanitized markdown the consumer will index
+func (s *communityService) UpdateCommunityRules(ctx context.Context, req UpdateRulesRequest) (*CommunityRules, error) {
+	if req.CommunityDID == "" {
+		return nil, NewValidationError("community", "required")
+	}
+	if req.UpdatedByDID == "" {
+		return nil, NewValidationError("updatedByDid", "required")
+	}
+	if err := ValidateRules(req.Markdown, req.Rules); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.GetByDID(ctx, req.CommunityDID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Authorization before any PDS call
+	if existing.CreatedByDID != req.UpdatedByDID {
+		return nil, ErrUnauthorized
+	}
+
+	existing, err = s.EnsureFreshToken(ctx, existing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ensure fresh credentials: %w", err)
+	}
+	if existing.PDSAccessToken == "" {
+		return nil, fmt.Errorf("community %s missing PDS credentials - cannot update rules", existing.DID)
+	}
+
+	// Keep each rule's original createdAt when its title is unchanged
+	createdAt := make(map[string]time.Time)
+	if s.rules != nil {
+		if current, getErr := s.rules.GetRules(ctx, existing.DID); getErr == nil {
+			for _, rule := range current.Rules {
+				createdAt[rule.Title] = rule.CreatedAt
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	rules := make([]Rule, 0, len(req.Rules))
+	textRules := make([]map[string]interface{}, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		rule.CreatedAt = now
+		if at, ok := createdAt[rule.Title]; ok && !at.IsZero() {
+			rule.CreatedAt = at
+		}
+		rules = append(rules, rule)
+		textRules = append(textRules, map[string]interface{}{
+			"title":       rule.Title,
+			"description": rule.Description,
+			"createdAt":   rule.CreatedAt.Format(time.RFC3339),
+			"isActive":    true,
+		})
+	}
+
+	record := map[string]interface{}{
+		"$type":     "social.coves.community.rules",
+		"markdown":  req.Markdown,
+		"textRules": textRules,
+	}
+
+	recordURI, recordCID, err := s.putRecordOnPDSAs(
+		ctx,
+		existing.DID,
+		"social.coves.community.rules",
+		"self",
+		record,
+		existing.PDSAccessToken, // authenticate as the community
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update community rules on PDS: %w", err)
+	}
+
+	// AppView DB update happens via Jetstream consumer
+	updated := &CommunityRules{
+		CommunityDID: existing.DID,
+		Markdown:     sanitize.Markdown(req.Markdown),
+		Rules:        rules,
+		RecordURI:    recordURI,
+		RecordCID:    recordCID,
+		UpdatedAt:    now,
+	}
+	s.recordAudit(ctx, req.UpdatedByDID, audit.ActionUpdateCommunityRules, existing.DID, updated)
+	return updated, nil
+}
+
 // getOrCreateRefreshMutex returns a mutex for the given community DID
 // Thread-safe with read-lock fast path for existing entries
 // SAFETY: Does NOT evict entries to avoid race condition where:
@@ -723,12 +1121,17 @@ func (s *communityService) EnsureFreshToken(ctx context.Context, community *Comm
 }
 
 // ListCommunities queries AppView DB for communities with filters
-func (s *communityService) ListCommunities(ctx context.Context, req ListCommunitiesRequest) ([]*Community, error) {
+// The cursor is only returned for the relevance sort; other sorts page by offset
+func (s *communityService) ListCommunities(ctx context.Context, req ListCommunitiesRequest) ([]*Community, *string, error) {
 	// Set defaults
 	if req.Limit <= 0 || req.Limit > 100 {
 		req.Limit = 50
 	}
 
+	if err := ValidateCategoryFilter(req.Category); err != nil {
+		return nil, nil, err
+	}
+
 	return s.repo.List(ctx, req)
 }
 
@@ -743,6 +1146,10 @@ func (s *communityService) SearchCommunities(ctx context.Context, req SearchComm
 		req.Limit = 50
 	}
 
+	if err := ValidateCategoryFilter(req.Category); err != nil {
+		return nil, 0, err
+	}
+
 	return s.repo.Search(ctx, req)
 }
 
@@ -777,6 +1184,18 @@ func (s *communityService) SubscribeToCommunity(ctx context.Context, session *oa
 		return nil, ErrUnauthorized
 	}
 
+	// Instance federation policy: communities on blocked instances can't be subscribed to
+	if community.FederationBlocked {
+		return nil, ErrFederationBlocked
+	}
+
+	if community.SuspendedAt != nil {
+		return nil, ErrCommunitySuspended
+	}
+	if community.DeletedAt != nil {
+		return nil, ErrCommunityDeleted
+	}
+
 	// Create PDS client for this session (DPoP authentication)
 	pdsClient, err := s.getPDSClient(ctx, session)
 	if err != nil {
@@ -797,6 +1216,11 @@ func (s *communityService) SubscribeToCommunity(ctx context.Context, session *oa
 		"contentVisibility": contentVisibility,
 	}
 
+	// Validate the record with the consumer's rules so it isn't written only to be refused indexing
+	if err := validate.Value(validate.SubscriptionCollection, subRecord); err != nil {
+		return nil, err
+	}
+
 	// Write-forward: create subscription record in user's repo using DPoP-authenticated client
 	recordURI, recordCID, err := pdsClient.CreateRecord(ctx, "social.coves.community.subscription", tid.String(), subRecord)
 	if err != nil {
@@ -806,7 +1230,6 @@ func (s *communityService) SubscribeToCommunity(ctx context.Context, session *oa
 		return nil, fmt.Errorf("failed to create subscription on PDS: %w", err)
 	}
 
-	// Return subscription representation
 	subscription := &Subscription{
 		UserDID:           userDID,
 		CommunityDID:      communityDID,
@@ -816,6 +1239,13 @@ func (s *communityService) SubscribeToCommunity(ctx context.Context, session *oa
 		RecordCID:         recordCID,
 	}
 
+	// Index optimistically so the UI reflects the change before the Jetstream event arrives
+	// The consumer confirms the row by record URI; the PDS write already succeeded, so a
+	// failure here only delays visibility until the event is indexed
+	if _, indexErr := s.repo.SubscribePendingWithCount(ctx, subscription); indexErr != nil {
+		log.Printf("WARNING: Failed to index pending subscription %s: %v", recordURI, indexErr)
+	}
+
 	return subscription, nil
 }
 
@@ -861,30 +1291,65 @@ func (s *communityService) UnsubscribeFromCommunity(ctx context.Context, session
 		return fmt.Errorf("failed to delete subscription on PDS: %w", err)
 	}
 
+	// Remove from the index now; the Jetstream delete finds nothing left and is a no-op
+	if indexErr := s.repo.UnsubscribeWithCount(ctx, userDID, communityDID); indexErr != nil {
+		log.Printf("WARNING: Failed to remove subscription %s from index: %v", subscription.RecordURI, indexErr)
+	}
+
 	return nil
 }
 
 // GetUserSubscriptions queries AppView DB for user's subscriptions
-func (s *communityService) GetUserSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*Subscription, error) {
+// cursor is the one returned with the previous page, or nil for the first page
+func (s *communityService) GetUserSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*Subscription, *string, error) {
 	if limit <= 0 || limit > 100 {
 		limit = 50
 	}
 
-	return s.repo.ListSubscriptions(ctx, userDID, limit, offset)
+	return s.repo.ListSubscriptions(ctx, userDID, limit, cursor)
+}
+
+// GetSubscribedCommunities queries AppView DB for the user's subscriptions hydrated with community data
+func (s *communityService) GetSubscribedCommunities(ctx context.Context, req GetSubscriptionsRequest) ([]*SubscribedCommunity, error) {
+	if req.UserDID == "" {
+		return nil, NewValidationError("userDid", "required")
+	}
+
+	switch req.Sort {
+	case "":
+		req.Sort = SubscriptionSortSubscribedAt
+	case SubscriptionSortSubscribedAt, SubscriptionSortAlphabetical, SubscriptionSortRecentActivity:
+	default:
+		return nil, NewValidationError("sort", "must be one of: alphabetical, recentActivity, subscribedAt")
+	}
+
+	req.Query = strings.TrimSpace(req.Query)
+	if utf8.RuneCountInString(req.Query) > 64 {
+		return nil, NewValidationError("q", "must be 64 characters or less")
+	}
+
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 50
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	return s.repo.ListSubscribedCommunities(ctx, req)
 }
 
 // GetCommunitySubscribers queries AppView DB for community subscribers
-func (s *communityService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit, offset int) ([]*Subscription, error) {
+func (s *communityService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit int, cursor *string) ([]*Subscription, *string, error) {
 	communityDID, err := s.ResolveCommunityIdentifier(ctx, communityIdentifier)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if limit <= 0 || limit > 100 {
 		limit = 50
 	}
 
-	return s.repo.ListSubscribers(ctx, communityDID, limit, offset)
+	return s.repo.ListSubscribers(ctx, communityDID, limit, cursor)
 }
 
 // GetMembership retrieves membership info from AppView DB
@@ -911,6 +1376,17 @@ func (s *communityService) ListCommunityMembers(ctx context.Context, communityId
 	return s.repo.ListMembers(ctx, communityDID, limit, offset)
 }
 
+// IsSubscribed reports whether the user has an indexed subscription to the community
+func (s *communityService) IsSubscribed(ctx context.Context, userDID, communityDID string) (bool, error) {
+	if _, err := s.repo.GetSubscription(ctx, userDID, communityDID); err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
 // BlockCommunity blocks a community via write-forward to PDS
 // Uses OAuth session with DPoP authentication for secure PDS communication
 func (s *communityService) BlockCommunity(ctx context.Context, session *oauth.ClientSessionData, communityIdentifier string) (*CommunityBlock, error) {
@@ -979,7 +1455,6 @@ func (s *communityService) BlockCommunity(ctx context.Context, session *oauth.Cl
 		return nil, fmt.Errorf("failed to create block on PDS: %w", err)
 	}
 
-	// Return block representation
 	block := &CommunityBlock{
 		UserDID:      userDID,
 		CommunityDID: communityDID,
@@ -988,6 +1463,11 @@ func (s *communityService) BlockCommunity(ctx context.Context, session *oauth.Cl
 		RecordCID:    recordCID,
 	}
 
+	// Index optimistically so the block takes effect before the Jetstream event arrives
+	if _, indexErr := s.repo.BlockCommunityPending(ctx, block); indexErr != nil {
+		log.Printf("WARNING: Failed to index pending block %s: %v", recordURI, indexErr)
+	}
+
 	return block, nil
 }
 
@@ -1032,6 +1512,11 @@ func (s *communityService) UnblockCommunity(ctx context.Context, session *oauth.
 		return fmt.Errorf("failed to delete block on PDS: %w", err)
 	}
 
+	// Remove from the index now; the Jetstream delete finds nothing left and is a no-op
+	if indexErr := s.repo.UnblockCommunity(ctx, userDID, communityDID); indexErr != nil && !errors.Is(indexErr, ErrBlockNotFound) {
+		log.Printf("WARNING: Failed to remove block %s from index: %v", block.RecordURI, indexErr)
+	}
+
 	return nil
 }
 
@@ -1179,6 +1664,19 @@ func (s *communityService) resolveScopedIdentifier(ctx context.Context, scoped s
 	return community.DID, nil
 }
 
+// checkNameAvailable returns ErrHandleTaken when a community already has the name's handle
+func (s *communityService) checkNameAvailable(ctx context.Context, name string) error {
+	handle := fmt.Sprintf("c-%s.%s", NormalizeCommunityName(name), strings.ToLower(s.instanceDomain))
+	_, err := s.repo.GetByHandle(ctx, handle)
+	if err == nil {
+		return ErrHandleTaken
+	}
+	if IsNotFound(err) {
+		return nil
+	}
+	return fmt.Errorf("failed to check community name: %w", err)
+}
+
 // isLocalInstance checks if the provided domain matches this instance
 func (s *communityService) isLocalInstance(domain string) bool {
 	// Normalize both domains
@@ -1240,6 +1738,10 @@ func (s *communityService) validateCreateRequest(req CreateCommunityRequest) err
 		return NewValidationError("createdByDid", "required")
 	}
 
+	if err := ValidateCategories(req.Categories); err != nil {
+		return err
+	}
+
 	// hostedByDID is auto-populated by the service layer, no validation needed
 	// The handler ensures clients cannot provide this field
 
@@ -3,14 +3,19 @@ package communityFeeds
 import (
 	"errors"
 	"fmt"
+
+	coreerrors "Coves/internal/core/errors"
 )
 
 var (
 	// ErrCommunityNotFound is returned when the community doesn't exist
-	ErrCommunityNotFound = errors.New("community not found")
+	ErrCommunityNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "community not found")
 
 	// ErrInvalidCursor is returned when the pagination cursor is invalid
-	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	ErrInvalidCursor = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid pagination cursor")
+
+	// ErrModeratorOnly is returned when a non-moderator uses a moderator-only filter (minScore)
+	ErrModeratorOnly = coreerrors.Sentinel(coreerrors.ErrForbidden, "only community moderators can filter by score")
 )
 
 // ValidationError represents an input validation error
@@ -36,3 +41,8 @@ func IsValidationError(err error) bool {
 	var ve *ValidationError
 	return errors.As(err, &ve)
 }
+
+// IsNotFound checks if an error is a "not found" error
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrCommunityNotFound)
+}
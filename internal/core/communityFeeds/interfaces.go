@@ -13,6 +13,12 @@ type Service interface {
 	// GetAuthorFeed(ctx context.Context, authorDID string, limit int, cursor *string) (*FeedResponse, error)
 }
 
+// ModeratorLookup reports which communities a user created or moderates
+// Implemented by communities.Repository
+type ModeratorLookup interface {
+	GetModeratedCommunityDIDs(ctx context.Context, userDID string, communityDIDs []string) (map[string]bool, error)
+}
+
 // Repository defines the data access interface for feeds
 type Repository interface {
 	// GetCommunityFeed retrieves posts from a community with sorting and pagination
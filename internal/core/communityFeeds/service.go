@@ -2,24 +2,52 @@ package communityFeeds
 
 import (
 	"Coves/internal/core/communities"
+	"Coves/internal/core/consumergaps"
 	"context"
 	"fmt"
+	"log"
+	"time"
+	"unicode/utf8"
 )
 
 type feedService struct {
 	repo             Repository
 	communityService communities.Service
+	moderators       ModeratorLookup     // Optional - minScore is refused when nil
+	gaps             consumergaps.Lookup // Optional - responses report no gaps when nil
+}
+
+// ServiceOption configures optional feed service dependencies
+type ServiceOption func(*feedService)
+
+// WithModeratorLookup enables moderator-only filters (minScore)
+func WithModeratorLookup(moderators ModeratorLookup) ServiceOption {
+	return func(s *feedService) {
+		s.moderators = moderators
+	}
+}
+
+// WithGapLookup reports consumer gaps overlapping each page
+func WithGapLookup(gaps consumergaps.Lookup) ServiceOption {
+	return func(s *feedService) {
+		s.gaps = gaps
+	}
 }
 
 // NewCommunityFeedService creates a new feed service
 func NewCommunityFeedService(
 	repo Repository,
 	communityService communities.Service,
+	opts ...ServiceOption,
 ) Service {
-	return &feedService{
+	s := &feedService{
 		repo:             repo,
 		communityService: communityService,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // GetCommunityFeed retrieves posts from a community with sorting
@@ -44,6 +72,13 @@ func (s *feedService) GetCommunityFeed(ctx context.Context, req GetCommunityFeed
 	// 3. Update request with resolved DID
 	req.Community = communityDID
 
+	// minScore exposes scores inside score hiding windows, so it's for moderator triage only
+	if req.MinScore != nil {
+		if err := s.requireModerator(ctx, req.ViewerDID, communityDID); err != nil {
+			return nil, err
+		}
+	}
+
 	// 4. Fetch feed from repository (hydrated posts)
 	feedPosts, cursor, err := s.repo.GetCommunityFeed(ctx, req)
 	if err != nil {
@@ -54,9 +89,45 @@ func (s *feedService) GetCommunityFeed(ctx context.Context, req GetCommunityFeed
 	return &FeedResponse{
 		Feed:   feedPosts,
 		Cursor: cursor,
+		Gaps:   s.pageGaps(ctx, feedPosts, req.Cursor == nil),
 	}, nil
 }
 
+// pageGaps returns the consumer gaps the page overlaps
+// Best effort: a failed lookup is logged and the page is served without gaps.
+func (s *feedService) pageGaps(ctx context.Context, feedPosts []*FeedViewPost, firstPage bool) []*consumergaps.Gap {
+	if s.gaps == nil {
+		return nil
+	}
+	indexedAt := make([]time.Time, 0, len(feedPosts))
+	for _, feedPost := range feedPosts {
+		if feedPost.Post != nil {
+			indexedAt = append(indexedAt, feedPost.Post.IndexedAt)
+		}
+	}
+	gaps, err := consumergaps.ForPage(ctx, s.gaps, indexedAt, firstPage)
+	if err != nil {
+		log.Printf("Warning: failed to look up consumer gaps for community feed: %v", err)
+		return nil
+	}
+	return gaps
+}
+
+// requireModerator returns ErrModeratorOnly unless viewerDID created or moderates the community
+func (s *feedService) requireModerator(ctx context.Context, viewerDID, communityDID string) error {
+	if viewerDID == "" || s.moderators == nil {
+		return ErrModeratorOnly
+	}
+	moderated, err := s.moderators.GetModeratedCommunityDIDs(ctx, viewerDID, []string{communityDID})
+	if err != nil {
+		return fmt.Errorf("failed to check moderator status: %w", err)
+	}
+	if !moderated[communityDID] {
+		return ErrModeratorOnly
+	}
+	return nil
+}
+
 // validateRequest validates the feed request parameters
 func (s *feedService) validateRequest(req *GetCommunityFeedRequest) error {
 	// Validate community identifier
@@ -68,9 +139,9 @@ func (s *feedService) validateRequest(req *GetCommunityFeedRequest) error {
 	if req.Sort == "" {
 		req.Sort = "hot"
 	}
-	validSorts := map[string]bool{"hot": true, "top": true, "new": true}
+	validSorts := map[string]bool{"hot": true, "top": true, "new": true, "activity": true}
 	if !validSorts[req.Sort] {
-		return NewValidationError("sort", "sort must be one of: hot, top, new")
+		return NewValidationError("sort", "sort must be one of: hot, top, new, activity")
 	}
 
 	// Validate and set defaults for limit
@@ -93,5 +164,10 @@ func (s *feedService) validateRequest(req *GetCommunityFeedRequest) error {
 		return NewValidationError("timeframe", "timeframe must be one of: hour, day, week, month, year, all")
 	}
 
+	// Tags are flair names, so longer values can never match
+	if utf8.RuneCountInString(req.Tag) > communities.MaxFlairNameLength {
+		return NewValidationError("tag", fmt.Sprintf("tag must be at most %d characters", communities.MaxFlairNameLength))
+	}
+
 	return nil
 }
@@ -1,18 +1,22 @@
 package communityFeeds
 
 import (
+	"Coves/internal/core/consumergaps"
 	"Coves/internal/core/posts"
 	"time"
 )
 
 // GetCommunityFeedRequest represents input for fetching a community feed
 // Matches social.coves.communityFeed.getCommunity lexicon input
-// Alpha: Basic sorting only (hot, top, new) - no post type filtering
+// Alpha: Basic sorting only (hot, top, new) plus an optional flair tag filter
 type GetCommunityFeedRequest struct {
 	Cursor    *string `json:"cursor,omitempty"`
+	MinScore  *int    `json:"minScore,omitempty"` // Optional, moderators only: hide posts scoring below this (triage)
 	Community string  `json:"community"`
 	Sort      string  `json:"sort"`
 	Timeframe string  `json:"timeframe"`
+	Tag       string  `json:"tag,omitempty"` // Optional: only posts with this flair (must be a current community flair)
+	ViewerDID string  `json:"-"`             // Authenticated viewer, empty when anonymous (author_only posts are shown to their author)
 	Limit     int     `json:"limit"`
 }
 
@@ -21,6 +25,8 @@ type GetCommunityFeedRequest struct {
 type FeedResponse struct {
 	Cursor *string         `json:"cursor,omitempty"`
 	Feed   []*FeedViewPost `json:"feed"`
+	// Gaps are windows of posts the AppView may have missed that the page overlaps
+	Gaps []*consumergaps.Gap `json:"gaps,omitempty"`
 }
 
 // FeedViewPost wraps a post with additional feed context
@@ -36,6 +42,18 @@ func (f *FeedViewPost) GetPost() *posts.PostView {
 	return f.Post
 }
 
+// GetContext returns the feed reason and reply context for versioned views
+// Returns untyped nils when absent so they are omitted from JSON
+func (f *FeedViewPost) GetContext() (reason, reply interface{}) {
+	if f.Reason != nil {
+		reason = f.Reason
+	}
+	if f.Reply != nil {
+		reply = f.Reply
+	}
+	return reason, reply
+}
+
 // FeedReason is a union type for feed context
 // Can be reasonRepost or reasonPin
 type FeedReason struct {
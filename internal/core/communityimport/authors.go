@@ -0,0 +1,42 @@
+package communityimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AuthorMap maps source usernames to the DIDs of Coves accounts that have
+// claimed them. Authors missing from the map are attributed to the archive
+// account.
+type AuthorMap map[string]string
+
+// LoadAuthorMap reads a JSON object of {"sourceUsername": "did:plc:..."}.
+func LoadAuthorMap(path string) (AuthorMap, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read author map: %w", err)
+	}
+
+	var authors AuthorMap
+	if err := json.Unmarshal(data, &authors); err != nil {
+		return nil, fmt.Errorf("failed to parse author map: %w", err)
+	}
+
+	for name, did := range authors {
+		if !strings.HasPrefix(did, "did:") {
+			return nil, fmt.Errorf("author map: %q maps to invalid DID %q", name, did)
+		}
+	}
+
+	return authors, nil
+}
+
+// Resolve returns the DID for a source author, falling back to archiveDID.
+func (m AuthorMap) Resolve(author, archiveDID string) string {
+	if did, ok := m[author]; ok && author != "" {
+		return did
+	}
+	return archiveDID
+}
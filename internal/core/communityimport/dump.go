@@ -0,0 +1,209 @@
+// Package communityimport migrates an existing forum community (e.g. a subreddit
+// export) into a Coves community by writing the dump's posts and comments as
+// records on the PDS. The AppView indexes them through Jetstream like any other
+// write, so nothing here touches the database directly.
+package communityimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Post is a single post in an import dump.
+// See docs/COMMUNITY_IMPORT.md for the file format.
+type Post struct {
+	CreatedAt time.Time  `json:"created_at"`
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	Author    string     `json:"author,omitempty"`
+	Comments  []*Comment `json:"comments,omitempty"`
+}
+
+// Comment is a comment in an import dump. Replies nest to arbitrary depth.
+type Comment struct {
+	CreatedAt time.Time  `json:"created_at"`
+	ID        string     `json:"id"`
+	Body      string     `json:"body"`
+	Author    string     `json:"author,omitempty"`
+	Replies   []*Comment `json:"replies,omitempty"`
+}
+
+// Limits mirror the post and comment record lexicons so a bad dump fails
+// validation up front instead of partway through an import.
+const (
+	maxTitleLength   = 3000
+	maxBodyLength    = 100000
+	maxCommentLength = 30000
+)
+
+// ParseDump reads an import dump. The input may be either a JSON array of
+// posts or newline-delimited JSON with one post per line; the format is
+// detected from the first non-whitespace byte. Every post and comment is
+// validated, and source IDs must be unique across the whole dump because they
+// key the progress file.
+func ParseDump(r io.Reader) ([]*Post, error) {
+	br := bufio.NewReader(r)
+
+	first, err := peekNonSpace(br)
+	if err != nil {
+		return nil, err
+	}
+
+	var dump []*Post
+	if first == '[' {
+		if err := json.NewDecoder(br).Decode(&dump); err != nil {
+			return nil, fmt.Errorf("failed to decode dump array: %w", err)
+		}
+	} else {
+		dump, err = parseNDJSON(br)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(dump) == 0 {
+		return nil, fmt.Errorf("dump contains no posts")
+	}
+
+	seen := make(map[string]bool)
+	for i, post := range dump {
+		if post == nil {
+			return nil, fmt.Errorf("post %d: null entry", i)
+		}
+		if err := post.validate(seen); err != nil {
+			return nil, err
+		}
+	}
+
+	return dump, nil
+}
+
+func parseNDJSON(br *bufio.Reader) ([]*Post, error) {
+	var dump []*Post
+	scanner := bufio.NewScanner(br)
+	// Posts with large comment trees easily exceed the default 64KB line limit
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var post Post
+		if err := json.Unmarshal(text, &post); err != nil {
+			return nil, fmt.Errorf("line %d: invalid JSON: %w", line, err)
+		}
+		dump = append(dump, &post)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read dump: %w", err)
+	}
+	return dump, nil
+}
+
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			if err == io.EOF {
+				return 0, fmt.Errorf("dump is empty")
+			}
+			return 0, fmt.Errorf("failed to read dump: %w", err)
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		if err := br.UnreadByte(); err != nil {
+			return 0, err
+		}
+		return b, nil
+	}
+}
+
+func (p *Post) validate(seen map[string]bool) error {
+	if err := claimID(p.ID, seen); err != nil {
+		return fmt.Errorf("post %q: %w", p.ID, err)
+	}
+	if strings.TrimSpace(p.Title) == "" {
+		return fmt.Errorf("post %q: title is required", p.ID)
+	}
+	if len(p.Title) > maxTitleLength {
+		return fmt.Errorf("post %q: title exceeds %d bytes", p.ID, maxTitleLength)
+	}
+	if len(p.Body) > maxBodyLength {
+		return fmt.Errorf("post %q: body exceeds %d bytes", p.ID, maxBodyLength)
+	}
+	if p.URL != "" && !strings.HasPrefix(p.URL, "http://") && !strings.HasPrefix(p.URL, "https://") {
+		return fmt.Errorf("post %q: url must be http or https", p.ID)
+	}
+	if p.CreatedAt.IsZero() {
+		return fmt.Errorf("post %q: created_at is required", p.ID)
+	}
+	for _, c := range p.Comments {
+		if err := c.validate(seen); err != nil {
+			return fmt.Errorf("post %q: %w", p.ID, err)
+		}
+	}
+	return nil
+}
+
+func (c *Comment) validate(seen map[string]bool) error {
+	if c == nil {
+		return fmt.Errorf("null comment entry")
+	}
+	if err := claimID(c.ID, seen); err != nil {
+		return fmt.Errorf("comment %q: %w", c.ID, err)
+	}
+	if strings.TrimSpace(c.Body) == "" {
+		return fmt.Errorf("comment %q: body is required", c.ID)
+	}
+	if len(c.Body) > maxCommentLength {
+		return fmt.Errorf("comment %q: body exceeds %d bytes", c.ID, maxCommentLength)
+	}
+	if c.CreatedAt.IsZero() {
+		return fmt.Errorf("comment %q: created_at is required", c.ID)
+	}
+	for _, reply := range c.Replies {
+		if err := reply.validate(seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func claimID(id string, seen map[string]bool) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("id is required")
+	}
+	if seen[id] {
+		return fmt.Errorf("duplicate id")
+	}
+	seen[id] = true
+	return nil
+}
+
+// CountRecords returns the number of posts and comments in a dump.
+func CountRecords(dump []*Post) (postCount, commentCount int) {
+	var count func([]*Comment)
+	count = func(cs []*Comment) {
+		for _, c := range cs {
+			commentCount++
+			count(c.Replies)
+		}
+	}
+	for _, p := range dump {
+		postCount++
+		count(p.Comments)
+	}
+	return postCount, commentCount
+}
@@ -0,0 +1,60 @@
+package communityimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDump_Formats(t *testing.T) {
+	array := `[
+		{"id":"p1","title":"One","created_at":"2015-06-01T12:00:00Z"},
+		{"id":"p2","title":"Two","created_at":"2015-06-02T12:00:00Z","comments":[{"id":"c1","body":"hi","created_at":"2015-06-02T13:00:00Z"}]}
+	]`
+	ndjson := `{"id":"p1","title":"One","created_at":"2015-06-01T12:00:00Z"}
+
+{"id":"p2","title":"Two","created_at":"2015-06-02T12:00:00Z","comments":[{"id":"c1","body":"hi","created_at":"2015-06-02T13:00:00Z"}]}
+`
+	for name, input := range map[string]string{"array": array, "ndjson": ndjson} {
+		t.Run(name, func(t *testing.T) {
+			dump, err := ParseDump(strings.NewReader(input))
+			require.NoError(t, err)
+			postCount, commentCount := CountRecords(dump)
+			assert.Equal(t, 2, postCount)
+			assert.Equal(t, 1, commentCount)
+			assert.Equal(t, "p2", dump[1].ID)
+		})
+	}
+}
+
+func TestParseDump_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"empty", "  \n", "dump is empty"},
+		{"empty array", "[]", "no posts"},
+		{"missing id", `{"title":"x","created_at":"2015-06-01T12:00:00Z"}`, "id is required"},
+		{"missing title", `{"id":"p1","created_at":"2015-06-01T12:00:00Z"}`, "title is required"},
+		{"missing created_at", `{"id":"p1","title":"x"}`, "created_at is required"},
+		{"bad url", `{"id":"p1","title":"x","url":"javascript:alert(1)","created_at":"2015-06-01T12:00:00Z"}`, "url must be http"},
+		{"empty comment", `{"id":"p1","title":"x","created_at":"2015-06-01T12:00:00Z","comments":[{"id":"c1","body":" ","created_at":"2015-06-01T12:00:00Z"}]}`, "body is required"},
+		{
+			"duplicate id across post and reply",
+			`{"id":"p1","title":"x","created_at":"2015-06-01T12:00:00Z","comments":[{"id":"c1","body":"a","created_at":"2015-06-01T12:00:00Z","replies":[{"id":"p1","body":"b","created_at":"2015-06-01T12:00:00Z"}]}]}`,
+			"duplicate id",
+		},
+		{"bad json line", "{\"id\":\"p1\"\n", "line 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDump(strings.NewReader(tt.input))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
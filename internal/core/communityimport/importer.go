@@ -0,0 +1,333 @@
+package communityimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"golang.org/x/time/rate"
+
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/posts"
+)
+
+const (
+	postCollection    = "social.coves.community.post"
+	commentCollection = "social.coves.community.comment"
+
+	// maxRateLimitRetries bounds retries when the PDS answers 429
+	maxRateLimitRetries = 5
+)
+
+// ErrRecordMismatch is returned when the rkey an item maps to already holds a different record
+var ErrRecordMismatch = errors.New("a different record already exists at the item's record key")
+
+// ClientFunc returns a PDS client for a repo. It is called before every write
+// so implementations can refresh expiring credentials between writes.
+type ClientFunc func(ctx context.Context) (pds.Client, error)
+
+// IndexChecker reports whether the AppView has indexed a record.
+type IndexChecker interface {
+	IsIndexed(ctx context.Context, uri string) (bool, error)
+}
+
+// Config configures an Importer.
+type Config struct {
+	// Authors maps source usernames to Coves DIDs for post attribution (optional)
+	Authors AuthorMap
+	// CommunityClient writes posts into the community's repository
+	CommunityClient ClientFunc
+	// ArchiveClient writes comments into the archive account's repository.
+	// When nil, comments are deferred and can be imported by a later run.
+	ArchiveClient ClientFunc
+	// Progress records written items so runs can resume
+	Progress *Progress
+	// CommunityDID is the DID of the target community
+	CommunityDID string
+	// ArchiveDID is the placeholder account unmapped authors are attributed to
+	ArchiveDID string
+	// WritesPerSecond limits PDS write throughput (default 5)
+	WritesPerSecond float64
+}
+
+// Report summarises an import run.
+type Report struct {
+	PostsWritten     int
+	PostsSkipped     int
+	CommentsWritten  int
+	CommentsSkipped  int
+	CommentsDeferred int
+}
+
+// Importer writes a parsed dump into a community.
+type Importer struct {
+	limiter *rate.Limiter
+	cfg     Config
+}
+
+// NewImporter validates the config and creates an importer.
+func NewImporter(cfg Config) (*Importer, error) {
+	if !strings.HasPrefix(cfg.CommunityDID, "did:") {
+		return nil, fmt.Errorf("community DID is required")
+	}
+	if !strings.HasPrefix(cfg.ArchiveDID, "did:") {
+		return nil, fmt.Errorf("archive DID is required")
+	}
+	if cfg.CommunityClient == nil {
+		return nil, fmt.Errorf("community client is required")
+	}
+	if cfg.Progress == nil {
+		return nil, fmt.Errorf("progress is required")
+	}
+	if cfg.WritesPerSecond <= 0 {
+		cfg.WritesPerSecond = 5
+	}
+
+	return &Importer{
+		cfg:     cfg,
+		limiter: rate.NewLimiter(rate.Limit(cfg.WritesPerSecond), 1),
+	}, nil
+}
+
+// Run imports every post and comment in the dump that the progress file does
+// not already list. Items are written in dump order, parents before replies.
+func (i *Importer) Run(ctx context.Context, dump []*Post) (*Report, error) {
+	report := &Report{}
+	if i.cfg.ArchiveClient == nil {
+		log.Printf("[IMPORT] No archive credentials configured; comments will be deferred")
+	}
+
+	for _, post := range dump {
+		postRef, err := i.importPost(ctx, post, report)
+		if err != nil {
+			return report, fmt.Errorf("post %q: %w", post.ID, err)
+		}
+
+		for _, c := range post.Comments {
+			if err := i.importComment(ctx, c, postRef, postRef, report); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func (i *Importer) importPost(ctx context.Context, post *Post, report *Report) (WrittenRecord, error) {
+	if rec, ok := i.cfg.Progress.Get(post.ID); ok {
+		report.PostsSkipped++
+		return rec, nil
+	}
+
+	record := posts.PostRecord{
+		Type:      postCollection,
+		Community: i.cfg.CommunityDID,
+		Author:    i.cfg.Authors.Resolve(post.Author, i.cfg.ArchiveDID),
+		Title:     &post.Title,
+		CreatedAt: post.CreatedAt.UTC().Format(time.RFC3339),
+		Imported:  true,
+	}
+	if post.Body != "" {
+		record.Content = &post.Body
+	}
+	if post.URL != "" {
+		record.Embed = map[string]interface{}{
+			"$type": "social.coves.embed.external",
+			"external": map[string]interface{}{
+				"uri": post.URL,
+			},
+		}
+	}
+
+	rec, err := i.write(ctx, i.cfg.CommunityClient, postCollection, RecordKey(post.ID, post.CreatedAt), record)
+	if err != nil {
+		return WrittenRecord{}, err
+	}
+	if err := i.cfg.Progress.Record(post.ID, rec); err != nil {
+		return WrittenRecord{}, err
+	}
+
+	report.PostsWritten++
+	return rec, nil
+}
+
+func (i *Importer) importComment(ctx context.Context, c *Comment, root, parent WrittenRecord, report *Report) error {
+	rec, ok := i.cfg.Progress.Get(c.ID)
+	switch {
+	case ok:
+		report.CommentsSkipped++
+	case i.cfg.ArchiveClient == nil:
+		// Replies can't reference a parent that hasn't been written
+		report.CommentsDeferred += 1 + countReplies(c.Replies)
+		return nil
+	default:
+		record := comments.CommentRecord{
+			Type: commentCollection,
+			Reply: comments.ReplyRef{
+				Root:   comments.StrongRef{URI: root.URI, CID: root.CID},
+				Parent: comments.StrongRef{URI: parent.URI, CID: parent.CID},
+			},
+			Content:   c.Body,
+			CreatedAt: c.CreatedAt.UTC().Format(time.RFC3339),
+			Imported:  true,
+		}
+
+		var err error
+		rec, err = i.write(ctx, i.cfg.ArchiveClient, commentCollection, RecordKey(c.ID, c.CreatedAt), record)
+		if err != nil {
+			return fmt.Errorf("comment %q: %w", c.ID, err)
+		}
+		if err := i.cfg.Progress.Record(c.ID, rec); err != nil {
+			return err
+		}
+		report.CommentsWritten++
+	}
+
+	for _, reply := range c.Replies {
+		if err := i.importComment(ctx, reply, root, rec, report); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write creates a record at a deterministic rkey. If the same record already
+// exists there (e.g. the progress file was lost after a successful write) it is
+// adopted instead of overwritten, so re-running an import never duplicates or
+// clobbers records. A different record at the rkey fails with ErrRecordMismatch.
+func (i *Importer) write(ctx context.Context, clientFn ClientFunc, collection, rkey string, record any) (WrittenRecord, error) {
+	for attempt := 0; ; attempt++ {
+		if err := i.limiter.Wait(ctx); err != nil {
+			return WrittenRecord{}, err
+		}
+
+		client, err := clientFn(ctx)
+		if err != nil {
+			return WrittenRecord{}, fmt.Errorf("failed to get PDS client: %w", err)
+		}
+
+		existing, err := client.GetRecord(ctx, collection, rkey)
+		if err == nil {
+			same, compareErr := sameRecord(record, existing.Value)
+			if compareErr != nil {
+				return WrittenRecord{}, compareErr
+			}
+			if !same {
+				return WrittenRecord{}, fmt.Errorf("%w: %s", ErrRecordMismatch, existing.URI)
+			}
+			return WrittenRecord{URI: existing.URI, CID: existing.CID}, nil
+		}
+		if errors.Is(err, pds.ErrNotFound) {
+			var uri, cid string
+			uri, cid, err = client.CreateRecord(ctx, collection, rkey, record)
+			if err == nil {
+				return WrittenRecord{URI: uri, CID: cid}, nil
+			}
+		}
+
+		if !errors.Is(err, pds.ErrRateLimited) || attempt >= maxRateLimitRetries {
+			return WrittenRecord{}, err
+		}
+
+		backoff := time.Duration(attempt+1) * 2 * time.Second
+		log.Printf("[IMPORT] PDS rate limited, retrying in %s", backoff)
+		select {
+		case <-ctx.Done():
+			return WrittenRecord{}, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// sameRecord reports whether a record read back from the PDS holds the content
+// of the record about to be written, comparing both in their JSON form
+func sameRecord(record any, existing map[string]any) (bool, error) {
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return false, fmt.Errorf("failed to encode record: %w", err)
+	}
+	var want map[string]any
+	if err := json.Unmarshal(encoded, &want); err != nil {
+		return false, fmt.Errorf("failed to decode record: %w", err)
+	}
+	return reflect.DeepEqual(want, existing), nil
+}
+
+// RecordKey derives a stable TID rkey for a source item from its creation time
+// and a hash of its source ID. The same item always maps to the same rkey, and
+// the TID timestamp keeps imported records ordered by their original dates.
+func RecordKey(sourceID string, createdAt time.Time) string {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(sourceID))
+	// TID clock IDs are 10 bits
+	return syntax.NewTIDFromTime(createdAt, uint(h.Sum32()%1024)).String()
+}
+
+// URIs returns the AT-URIs of every item in the dump that the progress file
+// lists as written.
+func URIs(dump []*Post, progress *Progress) []string {
+	var uris []string
+	var walk func([]*Comment)
+	walk = func(cs []*Comment) {
+		for _, c := range cs {
+			if rec, ok := progress.Get(c.ID); ok {
+				uris = append(uris, rec.URI)
+			}
+			walk(c.Replies)
+		}
+	}
+	for _, p := range dump {
+		if rec, ok := progress.Get(p.ID); ok {
+			uris = append(uris, rec.URI)
+		}
+		walk(p.Comments)
+	}
+	return uris
+}
+
+// WaitForIndexing polls the checker until every URI is indexed or ctx ends.
+// It returns the URIs still missing when it gave up.
+func WaitForIndexing(ctx context.Context, checker IndexChecker, uris []string, interval time.Duration) ([]string, error) {
+	pending := uris
+	for {
+		var missing []string
+		for _, uri := range pending {
+			indexed, err := checker.IsIndexed(ctx, uri)
+			if err != nil {
+				if ctx.Err() != nil {
+					return pending, nil
+				}
+				return pending, fmt.Errorf("failed to check %s: %w", uri, err)
+			}
+			if !indexed {
+				missing = append(missing, uri)
+			}
+		}
+		if len(missing) == 0 {
+			return nil, nil
+		}
+		pending = missing
+
+		select {
+		case <-ctx.Done():
+			return pending, nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+func countReplies(cs []*Comment) int {
+	n := 0
+	for _, c := range cs {
+		n += 1 + countReplies(c.Replies)
+	}
+	return n
+}
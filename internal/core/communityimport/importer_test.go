@@ -0,0 +1,243 @@
+package communityimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/blobs"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/posts"
+)
+
+// fakeRepo is an in-memory PDS repository
+type fakeRepo struct {
+	records map[string]any
+	did     string
+	creates int
+}
+
+func newFakeRepo(did string) *fakeRepo {
+	return &fakeRepo{did: did, records: make(map[string]any)}
+}
+
+func (f *fakeRepo) uri(collection, rkey string) string {
+	return fmt.Sprintf("at://%s/%s/%s", f.did, collection, rkey)
+}
+
+func (f *fakeRepo) CreateRecord(_ context.Context, collection, rkey string, record any) (string, string, error) {
+	uri := f.uri(collection, rkey)
+	if _, ok := f.records[uri]; ok {
+		return "", "", pds.ErrConflict
+	}
+	f.records[uri] = record
+	f.creates++
+	return uri, "cid-" + rkey, nil
+}
+
+func (f *fakeRepo) GetRecord(_ context.Context, collection, rkey string) (*pds.RecordResponse, error) {
+	uri := f.uri(collection, rkey)
+	record, ok := f.records[uri]
+	if !ok {
+		return nil, pds.ErrNotFound
+	}
+	// Records come back from the PDS as JSON
+	encoded, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	var value map[string]any
+	if err := json.Unmarshal(encoded, &value); err != nil {
+		return nil, err
+	}
+	return &pds.RecordResponse{URI: uri, CID: "cid-" + rkey, Value: value}, nil
+}
+
+func (f *fakeRepo) DeleteRecord(context.Context, string, string) error { return nil }
+func (f *fakeRepo) ListRecords(context.Context, string, int, string) (*pds.ListRecordsResponse, error) {
+	return &pds.ListRecordsResponse{}, nil
+}
+
+func (f *fakeRepo) PutRecord(context.Context, string, string, any, string) (string, string, error) {
+	return "", "", fmt.Errorf("not implemented")
+}
+
+func (f *fakeRepo) ApplyWrites(context.Context, string, []pds.WriteOp) ([]pds.WriteResult, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (f *fakeRepo) UploadBlob(context.Context, []byte, string) (*blobs.BlobRef, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+func (f *fakeRepo) DID() string     { return f.did }
+func (f *fakeRepo) HostURL() string { return "http://pds.test" }
+
+func (f *fakeRepo) clientFunc() ClientFunc {
+	return func(context.Context) (pds.Client, error) { return f, nil }
+}
+
+const testDump = `[
+  {"id":"p1","title":"First","body":"hello","author":"alice","created_at":"2015-06-01T12:00:00Z","comments":[
+    {"id":"c1","body":"top","author":"bob","created_at":"2015-06-01T13:00:00Z","replies":[
+      {"id":"c2","body":"nested","author":"alice","created_at":"2015-06-01T14:00:00Z"}
+    ]}
+  ]},
+  {"id":"p2","title":"Link","url":"https://example.com/a","author":"carol","created_at":"2015-06-02T12:00:00Z"}
+]`
+
+func newTestImporter(t *testing.T, community, archive *fakeRepo, progressPath string) *Importer {
+	t.Helper()
+	progress, err := LoadProgress(progressPath)
+	require.NoError(t, err)
+
+	cfg := Config{
+		CommunityDID:    community.did,
+		ArchiveDID:      "did:plc:archive",
+		Authors:         AuthorMap{"alice": "did:plc:alice"},
+		CommunityClient: community.clientFunc(),
+		Progress:        progress,
+		WritesPerSecond: 1000,
+	}
+	if archive != nil {
+		cfg.ArchiveClient = archive.clientFunc()
+	}
+	importer, err := NewImporter(cfg)
+	require.NoError(t, err)
+	return importer
+}
+
+func TestImporter_Run(t *testing.T) {
+	dump, err := ParseDump(strings.NewReader(testDump))
+	require.NoError(t, err)
+
+	community := newFakeRepo("did:plc:community")
+	archive := newFakeRepo("did:plc:archive")
+	importer := newTestImporter(t, community, archive, filepath.Join(t.TempDir(), "progress.json"))
+
+	report, err := importer.Run(context.Background(), dump)
+	require.NoError(t, err)
+	assert.Equal(t, &Report{PostsWritten: 2, CommentsWritten: 2}, report)
+
+	p1 := community.records[community.uri(postCollection, RecordKey("p1", dump[0].CreatedAt))].(posts.PostRecord)
+	assert.Equal(t, "did:plc:alice", p1.Author, "mapped author should be used")
+	assert.Equal(t, "did:plc:community", p1.Community)
+	assert.Equal(t, "2015-06-01T12:00:00Z", p1.CreatedAt, "original timestamp should be preserved")
+	assert.True(t, p1.Imported)
+
+	p2 := community.records[community.uri(postCollection, RecordKey("p2", dump[1].CreatedAt))].(posts.PostRecord)
+	assert.Equal(t, "did:plc:archive", p2.Author, "unmapped author should fall back to the archive account")
+	assert.Nil(t, p2.Content)
+	assert.Equal(t, "social.coves.embed.external", p2.Embed["$type"])
+
+	c2 := archive.records[archive.uri(commentCollection, RecordKey("c2", dump[0].Comments[0].Replies[0].CreatedAt))].(comments.CommentRecord)
+	assert.True(t, c2.Imported)
+	assert.Equal(t, community.uri(postCollection, RecordKey("p1", dump[0].CreatedAt)), c2.Reply.Root.URI)
+	assert.Equal(t, archive.uri(commentCollection, RecordKey("c1", dump[0].Comments[0].CreatedAt)), c2.Reply.Parent.URI)
+}
+
+func TestImporter_Resume(t *testing.T) {
+	dump, err := ParseDump(strings.NewReader(testDump))
+	require.NoError(t, err)
+
+	progressPath := filepath.Join(t.TempDir(), "progress.json")
+	community := newFakeRepo("did:plc:community")
+	archive := newFakeRepo("did:plc:archive")
+
+	// First run without archive credentials defers all comments
+	report, err := newTestImporter(t, community, nil, progressPath).Run(context.Background(), dump)
+	require.NoError(t, err)
+	assert.Equal(t, &Report{PostsWritten: 2, CommentsDeferred: 2}, report)
+
+	// Second run picks up the posts from the progress file and writes the comments
+	report, err = newTestImporter(t, community, archive, progressPath).Run(context.Background(), dump)
+	require.NoError(t, err)
+	assert.Equal(t, &Report{PostsSkipped: 2, CommentsWritten: 2}, report)
+
+	// Third run is a no-op
+	report, err = newTestImporter(t, community, archive, progressPath).Run(context.Background(), dump)
+	require.NoError(t, err)
+	assert.Equal(t, &Report{PostsSkipped: 2, CommentsSkipped: 2}, report)
+	assert.Equal(t, 2, community.creates)
+	assert.Equal(t, 2, archive.creates)
+}
+
+func TestImporter_LostProgressAdoptsExistingRecords(t *testing.T) {
+	dump, err := ParseDump(strings.NewReader(testDump))
+	require.NoError(t, err)
+
+	community := newFakeRepo("did:plc:community")
+	archive := newFakeRepo("did:plc:archive")
+
+	_, err = newTestImporter(t, community, archive, filepath.Join(t.TempDir(), "a.json")).Run(context.Background(), dump)
+	require.NoError(t, err)
+
+	// A fresh progress file must not create duplicates
+	report, err := newTestImporter(t, community, archive, filepath.Join(t.TempDir(), "b.json")).Run(context.Background(), dump)
+	require.NoError(t, err)
+	assert.Equal(t, &Report{PostsWritten: 2, CommentsWritten: 2}, report)
+	assert.Equal(t, 2, community.creates)
+	assert.Equal(t, 2, archive.creates)
+}
+
+func TestImporter_DifferentRecordAtKeyFails(t *testing.T) {
+	dump, err := ParseDump(strings.NewReader(testDump))
+	require.NoError(t, err)
+
+	community := newFakeRepo("did:plc:community")
+	other := "Something else"
+	community.records[community.uri(postCollection, RecordKey("p1", dump[0].CreatedAt))] = posts.PostRecord{
+		Type:      postCollection,
+		Community: community.did,
+		Author:    "did:plc:alice",
+		Title:     &other,
+		CreatedAt: "2015-06-01T12:00:00Z",
+	}
+
+	report, err := newTestImporter(t, community, nil, filepath.Join(t.TempDir(), "progress.json")).Run(context.Background(), dump)
+	require.ErrorIs(t, err, ErrRecordMismatch)
+	assert.Equal(t, 0, report.PostsWritten)
+	assert.Equal(t, 0, community.creates)
+}
+
+func TestRecordKey(t *testing.T) {
+	created := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, RecordKey("p1", created), RecordKey("p1", created), "rkey must be deterministic")
+	assert.NotEqual(t, RecordKey("p1", created), RecordKey("p2", created))
+	assert.Less(t, RecordKey("p1", created), RecordKey("p1", created.Add(time.Second)), "rkeys should sort by creation time")
+}
+
+type fakeIndexChecker struct {
+	indexed map[string]int // uri -> number of checks before it reports indexed
+}
+
+func (f *fakeIndexChecker) IsIndexed(_ context.Context, uri string) (bool, error) {
+	if f.indexed[uri] > 0 {
+		f.indexed[uri]--
+		return false, nil
+	}
+	_, ok := f.indexed[uri]
+	return ok, nil
+}
+
+func TestWaitForIndexing(t *testing.T) {
+	checker := &fakeIndexChecker{indexed: map[string]int{"at://a": 0, "at://b": 2}}
+
+	missing, err := WaitForIndexing(context.Background(), checker, []string{"at://a", "at://b"}, time.Millisecond)
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	missing, err = WaitForIndexing(ctx, checker, []string{"at://a", "at://never"}, time.Millisecond)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"at://never"}, missing)
+}
@@ -0,0 +1,105 @@
+package communityimport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// WrittenRecord is a record the importer has already written to the PDS.
+type WrittenRecord struct {
+	URI string `json:"uri"`
+	CID string `json:"cid"`
+}
+
+// Progress tracks which source items have been written, keyed by source ID.
+// It is flushed to disk after every write so an interrupted import resumes
+// where it stopped; comments also need their parent's URI and CID from here
+// to build reply references on a resumed run.
+type Progress struct {
+	Records map[string]WrittenRecord `json:"records"`
+	path    string
+	mu      sync.Mutex
+}
+
+// LoadProgress reads the progress file at path, or starts an empty one if the
+// file does not exist yet.
+func LoadProgress(path string) (*Progress, error) {
+	p := &Progress{Records: make(map[string]WrittenRecord), path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read progress file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("failed to parse progress file: %w", err)
+	}
+	if p.Records == nil {
+		p.Records = make(map[string]WrittenRecord)
+	}
+	return p, nil
+}
+
+// Get returns the written record for a source ID.
+func (p *Progress) Get(sourceID string) (WrittenRecord, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rec, ok := p.Records[sourceID]
+	return rec, ok
+}
+
+// Len returns the number of recorded items.
+func (p *Progress) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.Records)
+}
+
+// Record marks a source ID as written and persists the progress file.
+func (p *Progress) Record(sourceID string, rec WrittenRecord) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.Records[sourceID] = rec
+	return p.save()
+}
+
+// save writes the file atomically (temp file + rename) so a crash mid-write
+// never leaves a truncated progress file behind. Must hold p.mu.
+func (p *Progress) save() error {
+	if p.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode progress: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(p.path), filepath.Base(p.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create progress temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("failed to write progress: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("failed to write progress: %w", err)
+	}
+	if err := os.Rename(tmpName, p.path); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("failed to replace progress file: %w", err)
+	}
+	return nil
+}
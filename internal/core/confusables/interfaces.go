@@ -0,0 +1,30 @@
+package confusables
+
+import (
+	"context"
+	"time"
+)
+
+// Kinds of account a Collision is between
+const (
+	KindUser      = "user"
+	KindCommunity = "community"
+)
+
+// Collision is an indexed handle flagged as confusable with another account's handle
+type Collision struct {
+	IndexedAt        time.Time `json:"indexedAt"`
+	Kind             string    `json:"kind"`
+	DID              string    `json:"did"`
+	Handle           string    `json:"handle"`
+	Skeleton         string    `json:"skeleton"`
+	ConfusableWith   string    `json:"confusableWith"`
+	ConfusableHandle string    `json:"confusableHandle"`
+}
+
+// Repository lists flagged handles for admin review
+// Flags are set by the user and community repositories when a handle is indexed.
+type Repository interface {
+	// ListCollisions returns flagged users and communities, most recently indexed first
+	ListCollisions(ctx context.Context, limit, offset int) ([]*Collision, error)
+}
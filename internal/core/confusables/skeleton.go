@@ -0,0 +1,73 @@
+// Package confusables detects handles that look like another handle but aren't it, such as
+// a Cyrillic 'о' standing in for a Latin 'o'.
+//
+// Detection follows the skeleton algorithm of UTS #39 (Unicode Security Mechanisms, section
+// 4): two strings are confusable when they have the same skeleton. The mapping table is a
+// bundled subset of Unicode's confusables.txt, limited to characters that imitate the
+// characters a handle can contain (a-z, 0-9, '-' and '.'), ASCII ones included: "rn" and
+// "m", "vv" and "w", '0' and 'o', '1' and 'l' share skeletons.
+package confusables
+
+import (
+	"strings"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Skeleton returns the UTS #39 skeleton of s: s in NFD, each character replaced by its
+// prototype from the confusables table, then NFD again.
+// It's pure and idempotent. Two different ASCII strings can share a skeleton when they look
+// alike ("modern" and "modem"), so a skeleton is for comparing, not for display.
+func Skeleton(s string) string {
+	decomposed := norm.NFD.String(s)
+
+	var b strings.Builder
+	b.Grow(len(decomposed))
+	for _, r := range decomposed {
+		if fold, ok := foldFullwidth(r); ok {
+			r = fold
+		}
+		if prototype, ok := prototypes[r]; ok {
+			b.WriteString(prototype)
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return norm.NFD.String(b.String())
+}
+
+// HandleSkeleton returns the skeleton of a handle as it's displayed
+// Handles are case-insensitive and stored as ASCII, with non-ASCII labels punycode-encoded
+// ("xn--..."); those labels are decoded first, since it's the decoded form clients show and
+// that imitates other handles. A label that isn't valid punycode is kept as it is.
+func HandleSkeleton(handle string) string {
+	labels := strings.Split(strings.ToLower(strings.TrimSpace(handle)), ".")
+	for i, label := range labels {
+		if !strings.HasPrefix(label, "xn--") {
+			continue
+		}
+		if decoded, err := idna.Punycode.ToUnicode(label); err == nil {
+			labels[i] = strings.ToLower(decoded)
+		}
+	}
+	return Skeleton(strings.Join(labels, "."))
+}
+
+// IsConfusable reports whether two handles look alike without being the same handle
+func IsConfusable(a, b string) bool {
+	if strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b)) {
+		return false
+	}
+	return HandleSkeleton(a) == HandleSkeleton(b)
+}
+
+// foldFullwidth maps fullwidth ASCII letters, digits and punctuation (U+FF01-U+FF5E) to ASCII
+// They're in confusables.txt one by one; a range check keeps the table short.
+func foldFullwidth(r rune) (rune, bool) {
+	if r >= 0xFF01 && r <= 0xFF5E {
+		return r - 0xFF01 + '!', true
+	}
+	return 0, false
+}
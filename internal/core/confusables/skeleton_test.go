@@ -0,0 +1,211 @@
+package confusables
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSkeleton_KnownHomoglyphs(t *testing.T) {
+	tests := []struct {
+		name     string
+		spoof    string
+		original string
+	}{
+		{"cyrillic a", "аpple", "apple"},
+		{"cyrillic o", "bоb", "bob"},
+		{"all cyrillic paypal", "раураӏ", "paypal"},
+		{"cyrillic e and s", "ѕесret", "secret"},
+		{"cyrillic i and j", "іј", "ij"},
+		{"cyrillic komi de and shha", "ԁigһt", "dight"},
+		{"cyrillic we and ha", "ԝх", "wx"},
+		{"cyrillic straight u", "үes", "yes"},
+		{"greek omicron", "gοοgle", "google"},
+		{"greek alpha and rho", "αρi", "api"},
+		{"greek nu and upsilon", "νυe", "vue"},
+		{"greek lunate sigma", "ϲat", "cat"},
+		{"armenian oh", "fօօ", "foo"},
+		{"armenian vo and seh", "ոս", "nu"},
+		{"armenian co", "ցit", "git"},
+		{"latin dotless i", "admın", "admin"},
+		{"latin script g", "ɡood", "good"},
+		{"latin alpha", "bɑd", "bad"},
+		{"latin small capitals", "ᴏᴠᴡᴢ", "ovwz"},
+		{"script small l", "heℓℓo", "hello"},
+		{"telugu zero as o", "hell౦", "hello"},
+		{"unicode hyphen", "well‐known", "well-known"},
+		{"minus sign", "a−b", "a-b"},
+		{"arabic-indic zero as dot", "coves٠social", "coves.social"},
+		{"fullwidth letters", "ａｄｍｉｎ", "admin"},
+		{"fullwidth digits", "user１２", "user12"},
+		{"mixed scripts in one string", "аррӏе.сom", "apple.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, want := Skeleton(tt.spoof), Skeleton(tt.original); got != want {
+				t.Errorf("Skeleton(%q) = %q, want %q (the skeleton of %q)", tt.spoof, got, want, tt.original)
+			}
+		})
+	}
+}
+
+func TestSkeleton_ASCIILookalikes(t *testing.T) {
+	tests := []struct {
+		name     string
+		spoof    string
+		original string
+		skeleton string
+	}{
+		{"zero and o", "b0b", "bob", "bob"},
+		{"one and l", "a1ice", "alice", "alice"},
+		{"rn and m", "modern", "modem", "rnodern"},
+		{"vv and w", "vvikipedia", "wikipedia", "vvikipedia"},
+		{"several in one handle", "c0rnrnunity", "community", "cornrnunity"},
+		{"fullwidth lookalikes fold first", "ｍ０", "mo", "rno"},
+		{"cyrillic we", "ԝeb", "vveb", "vveb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Skeleton(tt.spoof); got != tt.skeleton {
+				t.Errorf("Skeleton(%q) = %q, want %q", tt.spoof, got, tt.skeleton)
+			}
+			if got := Skeleton(tt.original); got != tt.skeleton {
+				t.Errorf("Skeleton(%q) = %q, want %q", tt.original, got, tt.skeleton)
+			}
+		})
+	}
+}
+
+func TestSkeleton_DistinguishesDifferentStrings(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{"different ascii", "apple", "appie"},
+		{"r and n apart", "mode-rn", "moden"},
+		// Accents survive decomposition; they're visible, not homoglyphs
+		{"accented e", "caf\u00e9", "cafe"},
+		{"combining acute", "cafe\u0301", "cafe"},
+		{"cyrillic letters with no latin twin", "жф", "zf"},
+		{"extra character", "аpples", "apple"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Skeleton(tt.a) == Skeleton(tt.b) {
+				t.Errorf("Skeleton(%q) == Skeleton(%q) = %q, want them to differ", tt.a, tt.b, Skeleton(tt.a))
+			}
+		})
+	}
+}
+
+func TestSkeleton_Normalization(t *testing.T) {
+	// Precomposed and decomposed forms of the same text share a skeleton
+	if Skeleton("caf\u00e9") != Skeleton("cafe\u0301") {
+		t.Error("Expected NFC and NFD forms to share a skeleton")
+	}
+	// A lookalike carrying an accent maps like the bare letter
+	if Skeleton("\u0430\u0301") != Skeleton("\u00e1") {
+		t.Errorf("Skeleton(cyrillic a + acute) = %q, want %q", Skeleton("\u0430\u0301"), Skeleton("\u00e1"))
+	}
+}
+
+func TestSkeleton_IsPureAndIdempotent(t *testing.T) {
+	inputs := []string{
+		"",
+		"alice.bsky.social",
+		"аррӏе.сom",
+		"caf\u00e9",
+		"ａｄｍｉｎ",
+		"日本語",
+		"mixed-οоօ-ᴏ",
+		"modern-vvw01",
+	}
+	for _, in := range inputs {
+		first := Skeleton(in)
+		if again := Skeleton(in); again != first {
+			t.Errorf("Skeleton(%q) changed between calls: %q then %q", in, first, again)
+		}
+		if twice := Skeleton(first); twice != first {
+			t.Errorf("Skeleton is not idempotent for %q: %q then %q", in, first, twice)
+		}
+		if !utf8.ValidString(first) {
+			t.Errorf("Skeleton(%q) is not valid UTF-8", in)
+		}
+	}
+}
+
+func TestSkeleton_ASCIIWithoutLookalikesIsItsOwnSkeleton(t *testing.T) {
+	for _, s := range []string{"alice.bsky.social", "c-gaping.coves.social", "a-b.c9.exa", "UPPER.case", "!#$%&"} {
+		if got := Skeleton(s); got != s {
+			t.Errorf("Skeleton(%q) = %q, want it unchanged", s, got)
+		}
+	}
+}
+
+func TestPrototypes_AreHandleCharacters(t *testing.T) {
+	for r, prototype := range prototypes {
+		for _, p := range prototype {
+			if !(p >= 'a' && p <= 'z' || p >= '0' && p <= '9' || p == '-' || p == '.') {
+				t.Errorf("prototype of U+%04X is %q, want only handle characters", r, prototype)
+			}
+			if _, mapped := prototypes[p]; mapped {
+				t.Errorf("prototype of U+%04X is %q, but %q maps further; resolve it", r, prototype, p)
+			}
+		}
+	}
+}
+
+func TestHandleSkeleton(t *testing.T) {
+	tests := []struct {
+		name   string
+		handle string
+		want   string
+	}{
+		{"ascii handle", "alice.bsky.social", "alice.bsky.social"},
+		{"case and space", "  Alice.BSKY.social ", "alice.bsky.social"},
+		// xn--pple-43d is "аpple" with a Cyrillic а
+		{"punycode label", "xn--pple-43d.com", "apple.corn"},
+		// xn--bb-jbc is "bοb" with a Greek omicron
+		{"punycode subdomain", "xn--bb-jbc.bsky.social", "bob.bsky.social"},
+		// xn--l-7sba6dbr is "раураl" with every letter but the l Cyrillic
+		{"all cyrillic label", "xn--l-7sba6dbr.com", "paypal.corn"},
+		{"uppercase punycode prefix", "XN--PPLE-43D.com", "apple.corn"},
+		{"invalid punycode is kept", "xn--zz.example", "xn--zz.exarnple"},
+		{"ascii lookalikes", "c0ves.socia1", "coves.social"},
+		{"unencoded lookalike", "аlice.bsky.social", "alice.bsky.social"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HandleSkeleton(tt.handle); got != tt.want {
+				t.Errorf("HandleSkeleton(%q) = %q, want %q", tt.handle, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsConfusable(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"xn--pple-43d.com", "apple.com", true},
+		{"apple.com", "xn--pple-43d.com", true},
+		{"xn--bb-jbc.bsky.social", "bob.bsky.social", true},
+		{"xn--l-7sba6dbr.com", "xn--pple-43d.com", false},
+		// The same handle isn't confusable with itself, whatever the case
+		{"apple.com", "Apple.com", false},
+		{"xn--pple-43d.com", "xn--pple-43d.com", false},
+		{"alice.bsky.social", "alice.bsky.socia1", true},
+		{"modem.coves.social", "modern.coves.social", true},
+		{"alice.bsky.social", "alicia.bsky.social", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsConfusable(tt.a, tt.b); got != tt.want {
+			t.Errorf("IsConfusable(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
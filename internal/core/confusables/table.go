@@ -0,0 +1,98 @@
+package confusables
+
+// prototypes maps characters that imitate handle characters to what they imitate
+// A subset of Unicode's confusables.txt (UTS #39 data, version 15.1): the entries whose
+// prototype is a lowercase ASCII letter, digit, '-' or '.', including the ASCII characters
+// that imitate other ASCII. Characters are listed as they appear after NFD, so precomposed
+// letters don't need their own entries. Fullwidth forms are folded by foldFullwidth instead.
+// Prototypes are resolved all the way, as in confusables.txt ('ԝ' imitates 'w', which is
+// "vv"), so no prototype contains a character in the table.
+var prototypes = map[rune]string{
+	// ASCII
+	'0': "o",  // U+0030 DIGIT ZERO (confusables.txt maps it to 'O'; handles are lowercase)
+	'1': "l",  // U+0031 DIGIT ONE
+	'm': "rn", // U+006D LATIN SMALL LETTER M
+	'w': "vv", // U+0077 LATIN SMALL LETTER W
+
+	// Cyrillic
+	'а': "a",  // U+0430 CYRILLIC SMALL LETTER A
+	'б': "6",  // U+0431 CYRILLIC SMALL LETTER BE
+	'с': "c",  // U+0441 CYRILLIC SMALL LETTER ES
+	'ԁ': "d",  // U+0501 CYRILLIC SMALL LETTER KOMI DE
+	'е': "e",  // U+0435 CYRILLIC SMALL LETTER IE
+	'ҽ': "e",  // U+04BD CYRILLIC SMALL LETTER ABKHASIAN CHE
+	'г': "r",  // U+0433 CYRILLIC SMALL LETTER GHE
+	'һ': "h",  // U+04BB CYRILLIC SMALL LETTER SHHA
+	'і': "i",  // U+0456 CYRILLIC SMALL LETTER BYELORUSSIAN-UKRAINIAN I
+	'ј': "j",  // U+0458 CYRILLIC SMALL LETTER JE
+	'ӏ': "l",  // U+04CF CYRILLIC SMALL LETTER PALOCHKA
+	'о': "o",  // U+043E CYRILLIC SMALL LETTER O
+	'р': "p",  // U+0440 CYRILLIC SMALL LETTER ER
+	'ԛ': "q",  // U+051B CYRILLIC SMALL LETTER QA
+	'ѕ': "s",  // U+0455 CYRILLIC SMALL LETTER DZE
+	'ѵ': "v",  // U+0475 CYRILLIC SMALL LETTER IZHITSA
+	'ԝ': "vv", // U+051D CYRILLIC SMALL LETTER WE
+	'х': "x",  // U+0445 CYRILLIC SMALL LETTER HA
+	'у': "y",  // U+0443 CYRILLIC SMALL LETTER U
+	'ү': "y",  // U+04AF CYRILLIC SMALL LETTER STRAIGHT U
+	'з': "3",  // U+0437 CYRILLIC SMALL LETTER ZE
+	'ӡ': "3",  // U+04E1 CYRILLIC SMALL LETTER ABKHASIAN DZE
+
+	// Greek
+	'α': "a", // U+03B1 GREEK SMALL LETTER ALPHA
+	'ϲ': "c", // U+03F2 GREEK LUNATE SIGMA SYMBOL
+	'ι': "i", // U+03B9 GREEK SMALL LETTER IOTA
+	'ϳ': "j", // U+03F3 GREEK LETTER YOT
+	'ο': "o", // U+03BF GREEK SMALL LETTER OMICRON
+	'σ': "o", // U+03C3 GREEK SMALL LETTER SIGMA
+	'ρ': "p", // U+03C1 GREEK SMALL LETTER RHO
+	'υ': "u", // U+03C5 GREEK SMALL LETTER UPSILON
+	'ν': "v", // U+03BD GREEK SMALL LETTER NU
+	'γ': "y", // U+03B3 GREEK SMALL LETTER GAMMA
+
+	// Armenian
+	'գ': "q", // U+0563 ARMENIAN SMALL LETTER GIM
+	'զ': "q", // U+0566 ARMENIAN SMALL LETTER ZA
+	'հ': "h", // U+0570 ARMENIAN SMALL LETTER HO
+	'ո': "n", // U+0578 ARMENIAN SMALL LETTER VO
+	'ռ': "n", // U+057C ARMENIAN SMALL LETTER RA
+	'ս': "u", // U+057D ARMENIAN SMALL LETTER SEH
+	'ց': "g", // U+0581 ARMENIAN SMALL LETTER CO
+	'օ': "o", // U+0585 ARMENIAN SMALL LETTER OH
+
+	// Latin lookalikes outside ASCII
+	'ı': "i",  // U+0131 LATIN SMALL LETTER DOTLESS I
+	'ɩ': "i",  // U+0269 LATIN SMALL LETTER IOTA
+	'ɪ': "i",  // U+026A LATIN LETTER SMALL CAPITAL I
+	'ɑ': "a",  // U+0251 LATIN SMALL LETTER ALPHA
+	'ɡ': "g",  // U+0261 LATIN SMALL LETTER SCRIPT G
+	'ȷ': "j",  // U+0237 LATIN SMALL LETTER DOTLESS J
+	'ǀ': "l",  // U+01C0 LATIN LETTER DENTAL CLICK
+	'ᴏ': "o",  // U+1D0F LATIN LETTER SMALL CAPITAL O
+	'ᴠ': "v",  // U+1D20 LATIN LETTER SMALL CAPITAL V
+	'ᴡ': "vv", // U+1D21 LATIN LETTER SMALL CAPITAL W
+	'ᴢ': "z",  // U+1D22 LATIN LETTER SMALL CAPITAL Z
+
+	// Letterlike symbols and dashes
+	'ℓ': "l", // U+2113 SCRIPT SMALL L
+	'ℯ': "e", // U+212F SCRIPT SMALL E
+	'ℴ': "o", // U+2134 SCRIPT SMALL O
+	'‐': "-", // U+2010 HYPHEN
+	'‑': "-", // U+2011 NON-BREAKING HYPHEN
+	'‒': "-", // U+2012 FIGURE DASH
+	'–': "-", // U+2013 EN DASH
+	'−': "-", // U+2212 MINUS SIGN
+	'˗': "-", // U+02D7 MODIFIER LETTER MINUS SIGN
+	'٠': ".", // U+0660 ARABIC-INDIC DIGIT ZERO
+	'۰': ".", // U+06F0 EXTENDED ARABIC-INDIC DIGIT ZERO
+	'܁': ".", // U+0701 SYRIAC SUPRALINEAR FULL STOP
+
+	// Digits
+	'౦': "o", // U+0C66 TELUGU DIGIT ZERO
+	'೦': "o", // U+0CE6 KANNADA DIGIT ZERO
+	'൦': "o", // U+0D66 MALAYALAM DIGIT ZERO
+	'໐': "o", // U+0ED0 LAO DIGIT ZERO
+	'၀': "o", // U+1040 MYANMAR DIGIT ZERO
+	'৪': "8", // U+09EA BENGALI DIGIT FOUR
+	'੪': "8", // U+0A6A GURMUKHI DIGIT FOUR
+}
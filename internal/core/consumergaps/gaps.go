@@ -0,0 +1,97 @@
+// Package consumergaps tracks windows of firehose events the Jetstream consumers never saw
+//
+// A gap opens when a connection resumes from a cursor older than Jetstream's replay window and
+// its first event is further past the cursor than the gap threshold, or when a connection with
+// no cursor to replay from was down for longer than the threshold. Either way records written
+// in between were never indexed. Each consumer's position is saved, so a restart of the
+// AppView resumes from it and is checked the same way. Feeds whose page overlaps an unfilled
+// gap report it, so clients can say posts may be missing.
+// An admin marks the gap backfilled once the records written during it have been reindexed.
+package consumergaps
+
+import (
+	coreerrors "Coves/internal/core/errors"
+	"context"
+	"time"
+)
+
+// DefaultThreshold is the shortest window recorded as a gap
+// A reconnect normally resumes within seconds; shorter windows are noise, not outages.
+const DefaultThreshold = 2 * time.Minute
+
+// DefaultReplayWindow is how far back Jetstream can replay from a cursor
+// Jetstream keeps 24 hours of events by default; an older cursor resumes at its oldest event.
+const DefaultReplayWindow = 24 * time.Hour
+
+// ErrGapNotFound is returned when marking a gap that doesn't exist
+var ErrGapNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "consumer gap not found")
+
+// Gap is a window of firehose events a consumer never saw
+// Start is the replay cursor or the last time the connection was known to be up, End the
+// first event after it or the time the connection reopened.
+type Gap struct {
+	BackfilledAt *time.Time `json:"backfilledAt,omitempty"`
+	Start        time.Time  `json:"start"`
+	End          time.Time  `json:"end"`
+	DetectedAt   time.Time  `json:"detectedAt"`
+	Consumer     string     `json:"consumer"`
+	ID           int64      `json:"id"`
+}
+
+// Position is where a consumer was in the firehose when it last saved
+type Position struct {
+	// ConnectedAt is the last time the consumer's connection was known to be up
+	ConnectedAt time.Time
+	// LastSeenUS is the time_us of the newest event the consumer saw
+	LastSeenUS int64
+}
+
+// Repository stores detected gaps and consumer positions
+type Repository interface {
+	// RecordGap stores a gap between two Jetstream time_us values
+	RecordGap(ctx context.Context, consumer string, startTimeUS, endTimeUS int64) error
+
+	// SavePosition stores the consumer's position, replacing the one saved before
+	SavePosition(ctx context.Context, consumer string, position Position) error
+
+	// LoadPosition returns the consumer's saved position, or a zero Position if it never saved one
+	LoadPosition(ctx context.Context, consumer string) (Position, error)
+
+	// ListUnfilled returns the gaps not yet backfilled that overlap [from, to], oldest first
+	ListUnfilled(ctx context.Context, from, to time.Time) ([]*Gap, error)
+
+	// List returns gaps newest first, including backfilled ones when includeBackfilled is set
+	List(ctx context.Context, includeBackfilled bool, limit, offset int) ([]*Gap, error)
+
+	// MarkBackfilled records that the gap's records have been reindexed, so feeds stop reporting it
+	// Returns ErrGapNotFound if the gap doesn't exist; marking it again is a no-op.
+	MarkBackfilled(ctx context.Context, id int64) (*Gap, error)
+}
+
+// Lookup finds the unfilled gaps a feed page overlaps
+// Implemented by Repository
+type Lookup interface {
+	ListUnfilled(ctx context.Context, from, to time.Time) ([]*Gap, error)
+}
+
+// ForPage returns the unfilled gaps overlapping a feed page, given when its posts were indexed
+// The page covers its oldest post to its newest; on the first page it runs to now, since
+// posts newer than the newest one shown could be missing too. An empty page has no gaps.
+func ForPage(ctx context.Context, lookup Lookup, indexedAt []time.Time, firstPage bool) ([]*Gap, error) {
+	if len(indexedAt) == 0 {
+		return nil, nil
+	}
+	from, to := indexedAt[0], indexedAt[0]
+	for _, t := range indexedAt[1:] {
+		if t.Before(from) {
+			from = t
+		}
+		if t.After(to) {
+			to = t
+		}
+	}
+	if now := time.Now(); firstPage && now.After(to) {
+		to = now
+	}
+	return lookup.ListUnfilled(ctx, from, to)
+}
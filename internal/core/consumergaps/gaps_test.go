@@ -0,0 +1,61 @@
+package consumergaps
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// windowLookup records the window it's asked about
+type windowLookup struct {
+	from, to time.Time
+	calls    int
+}
+
+func (l *windowLookup) ListUnfilled(ctx context.Context, from, to time.Time) ([]*Gap, error) {
+	l.from, l.to = from, to
+	l.calls++
+	return []*Gap{{ID: 1}}, nil
+}
+
+func TestForPage(t *testing.T) {
+	newest := time.Now().Add(-time.Hour)
+	oldest := newest.Add(-3 * time.Hour)
+	page := []time.Time{newest.Add(-time.Hour), oldest, newest}
+
+	t.Run("later page covers its posts", func(t *testing.T) {
+		lookup := &windowLookup{}
+		gaps, err := ForPage(context.Background(), lookup, page, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(gaps) != 1 {
+			t.Errorf("Expected the lookup's gaps, got %+v", gaps)
+		}
+		if !lookup.from.Equal(oldest) || !lookup.to.Equal(newest) {
+			t.Errorf("Expected window %s to %s, got %s to %s", oldest, newest, lookup.from, lookup.to)
+		}
+	})
+
+	t.Run("first page runs to now", func(t *testing.T) {
+		lookup := &windowLookup{}
+		before := time.Now()
+		if _, err := ForPage(context.Background(), lookup, page, true); err != nil {
+			t.Fatal(err)
+		}
+		if !lookup.from.Equal(oldest) || lookup.to.Before(before) {
+			t.Errorf("Expected window %s to now, got %s to %s", oldest, lookup.from, lookup.to)
+		}
+	})
+
+	t.Run("empty page has no gaps", func(t *testing.T) {
+		lookup := &windowLookup{}
+		gaps, err := ForPage(context.Background(), lookup, nil, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gaps != nil || lookup.calls != 0 {
+			t.Errorf("Expected no lookup for an empty page, got %+v after %d calls", gaps, lookup.calls)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package directory
+
+import (
+	"strings"
+	"time"
+
+	"Coves/internal/pagination"
+)
+
+const (
+	// DefaultLimit and MaxLimit bound a social.coves.sync.listCommunities page
+	DefaultLimit = 50
+	MaxLimit     = 100
+
+	// listingLag keeps rows updated in the last few seconds out of the listing, so a transaction
+	// that commits with an older updated_at can't land behind a cursor a peer already holds
+	listingLag = 5 * time.Second
+)
+
+// Entry statuses
+// Communities that stop being listed stay in the listing as tombstones, so peers that indexed
+// them learn to hide them instead of showing them forever.
+const (
+	StatusActive    = "active"
+	StatusSuspended = "suspended"
+	StatusDeleted   = "deleted"
+	StatusUnlisted  = "unlisted" // No longer public, or held for impersonation review
+)
+
+// Entry is one community in an instance's directory
+// Matches social.coves.sync.listCommunities#communityEntry. Tombstones (any status but active)
+// carry only the DID, hostedBy, status and updatedAt; an entry without a status is active.
+type Entry struct {
+	CreatedAt       time.Time `json:"createdAt,omitzero"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+	DID             string    `json:"did"`
+	Handle          string    `json:"handle,omitempty"`
+	Name            string    `json:"name,omitempty"`
+	DisplayName     string    `json:"displayName,omitempty"`
+	Description     string    `json:"description,omitempty"`
+	CreatedBy       string    `json:"createdBy,omitempty"`
+	HostedBy        string    `json:"hostedBy"`
+	Status          string    `json:"status,omitempty"`
+	SubscriberCount int       `json:"subscriberCount,omitempty"`
+	PostCount       int       `json:"postCount,omitempty"`
+}
+
+// Active reports whether the entry lists a community rather than withdrawing one
+func (e *Entry) Active() bool {
+	return e.Status == "" || e.Status == StatusActive
+}
+
+// tombstone returns the entry stripped to what peers need to withdraw the community
+func (e *Entry) tombstone() *Entry {
+	return &Entry{DID: e.DID, HostedBy: e.HostedBy, Status: e.Status, UpdatedAt: e.UpdatedAt}
+}
+
+// ListRequest is the input for social.coves.sync.listCommunities
+type ListRequest struct {
+	Cursor string
+	Limit  int
+}
+
+// ListResponse is the output of social.coves.sync.listCommunities
+// Cursor is the position after the last entry. Pollers keep it and pass it back to receive
+// only communities updated since; an empty page echoes the request's cursor.
+type ListResponse struct {
+	Cursor      *string  `json:"cursor,omitempty"`
+	Communities []*Entry `json:"communities"`
+}
+
+// Cursor is a position in the directory's (updated_at, did) order
+type Cursor struct {
+	UpdatedAt time.Time
+	DID       string
+}
+
+// Encode returns the opaque cursor string, sealed by cursors so peers can't forge a position
+func (c Cursor) Encode(cursors *pagination.Signer) string {
+	return cursors.Encode(c.UpdatedAt.UTC().Format(time.RFC3339Nano), c.DID)
+}
+
+// DecodeCursor opens a cursor from Encode
+func DecodeCursor(cursors *pagination.Signer, cursor string) (*Cursor, error) {
+	fields, err := cursors.Decode(cursor)
+	if err != nil || len(fields) != 2 {
+		return nil, NewValidationError("cursor", "invalid cursor")
+	}
+	updatedAt, did := fields[0], fields[1]
+	if !strings.HasPrefix(did, "did:") {
+		return nil, NewValidationError("cursor", "invalid cursor")
+	}
+	parsed, err := time.Parse(time.RFC3339Nano, updatedAt)
+	if err != nil {
+		return nil, NewValidationError("cursor", "invalid cursor")
+	}
+	return &Cursor{UpdatedAt: parsed, DID: did}, nil
+}
+
+// SyncReport summarises one poll of the configured peers
+type SyncReport struct {
+	PeersPolled int
+	PeersFailed int
+	Indexed     int // Remote communities inserted or refreshed
+	Withdrawn   int // Remote communities hidden because their peer listed a tombstone
+	Skipped     int // Entries rejected or owned by a local or firehose-indexed community
+}
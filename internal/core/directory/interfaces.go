@@ -0,0 +1,54 @@
+package directory
+
+import (
+	"context"
+	"time"
+
+	"Coves/internal/atproto/identity"
+)
+
+// Repository defines directory data access
+type Repository interface {
+	// ListHosted returns the communities hosted by hostedByDID in (updated_at, did) order,
+	// after the cursor and updated before the given time, each with its status: active for
+	// public communities, otherwise why it's withdrawn. Remote communities learned from peers
+	// are never listed.
+	ListHosted(ctx context.Context, hostedByDID string, after *Cursor, updatedBefore time.Time, limit int) ([]*Entry, error)
+
+	// UpsertRemote indexes a peer's community as a remote entry, deduplicated by DID
+	// A DID already indexed from the firehose is left alone and reports false.
+	UpsertRemote(ctx context.Context, entry *Entry, federationBlocked bool) (bool, error)
+
+	// WithdrawRemote hides a remote community its peer listed as a tombstone: deleted and
+	// suspended communities are marked deleted, unlisted ones unlisted. Only remote entries
+	// hosted by hostedByDID are touched; reports whether one was.
+	WithdrawRemote(ctx context.Context, did, hostedByDID, status string) (bool, error)
+
+	// GetPeerCursor returns the saved listing cursor for a peer ("" when never polled)
+	GetPeerCursor(ctx context.Context, peerURL string) (string, error)
+	// SavePeerCursor records how far a peer's directory has been read
+	SavePeerCursor(ctx context.Context, peerURL, cursor string) error
+}
+
+// HostPolicy decides whether communities hosted on an instance are blocked by federation policy
+// Implemented by the federation service.
+type HostPolicy interface {
+	IsHostBlocked(ctx context.Context, hostedByDID string) (bool, error)
+}
+
+// IdentityResolver resolves community DIDs, so a peer's claim to host one can be checked
+// against the PDS its DID document names
+// Implemented by identity.Resolver.
+type IdentityResolver interface {
+	Resolve(ctx context.Context, identifier string) (*identity.Identity, error)
+}
+
+// Service defines the community directory sync business logic
+type Service interface {
+	// ListCommunities returns a page of this instance's directory for peers
+	ListCommunities(ctx context.Context, req ListRequest) (*ListResponse, error)
+
+	// SyncPeers polls every configured peer's directory from its saved cursor and indexes
+	// the communities as remote entries. A failing peer doesn't stop the others.
+	SyncPeers(ctx context.Context) (*SyncReport, error)
+}
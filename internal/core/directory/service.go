@@ -0,0 +1,294 @@
+package directory
+
+import (
+	"Coves/internal/atproto/identity"
+	coreerrors "Coves/internal/core/errors"
+	"Coves/internal/core/federation"
+	"Coves/internal/pagination"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxPagesPerPeer bounds how much of one peer's directory a single sync reads
+	// The cursor is saved after every page, so the next sync picks up where this one stopped.
+	maxPagesPerPeer = 20
+
+	// maxResponseBytes bounds a peer's listing response
+	maxResponseBytes = 5 << 20
+
+	peerRequestTimeout = 30 * time.Second
+)
+
+type directoryService struct {
+	repo        Repository
+	policy      HostPolicy
+	identities  IdentityResolver
+	cursors     *pagination.Signer
+	httpClient  *http.Client
+	instanceDID string
+	peers       []string
+}
+
+// NewDirectoryService creates a new community directory service
+// instanceDID selects the communities this instance lists. peers are base URLs of other Coves
+// instances (https://other.example) whose directories SyncPeers indexes; identities checks
+// their claims and may be nil only without peers. policy may be nil. cursors seals the
+// listing's cursors.
+func NewDirectoryService(repo Repository, policy HostPolicy, identities IdentityResolver, cursors *pagination.Signer, instanceDID string, peers []string) Service {
+	normalized := make([]string, 0, len(peers))
+	for _, peer := range peers {
+		if peer = strings.TrimSuffix(strings.TrimSpace(peer), "/"); peer != "" {
+			normalized = append(normalized, peer)
+		}
+	}
+	return &directoryService{
+		repo:        repo,
+		policy:      policy,
+		identities:  identities,
+		cursors:     cursors,
+		httpClient:  &http.Client{Timeout: peerRequestTimeout},
+		instanceDID: instanceDID,
+		peers:       normalized,
+	}
+}
+
+// ListCommunities returns communities hosted here, in the order they were last updated
+func (s *directoryService) ListCommunities(ctx context.Context, req ListRequest) (*ListResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultLimit
+	}
+	if limit < 1 || limit > MaxLimit {
+		return nil, NewValidationError("limit", fmt.Sprintf("must be between 1 and %d", MaxLimit))
+	}
+
+	var after *Cursor
+	if req.Cursor != "" {
+		var err error
+		if after, err = DecodeCursor(s.cursors, req.Cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	entries, err := s.repo.ListHosted(ctx, s.instanceDID, after, time.Now().Add(-listingLag), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hosted communities: %w", err)
+	}
+
+	response := &ListResponse{Communities: make([]*Entry, 0, len(entries))}
+	for _, entry := range entries {
+		if !entry.Active() {
+			entry = entry.tombstone()
+		}
+		response.Communities = append(response.Communities, entry)
+	}
+	if len(entries) > 0 {
+		last := entries[len(entries)-1]
+		cursor := Cursor{UpdatedAt: last.UpdatedAt, DID: last.DID}.Encode(s.cursors)
+		response.Cursor = &cursor
+	} else if req.Cursor != "" {
+		response.Cursor = &req.Cursor
+	}
+	return response, nil
+}
+
+// SyncPeers indexes every peer's communities updated since the last sync
+func (s *directoryService) SyncPeers(ctx context.Context) (*SyncReport, error) {
+	report := &SyncReport{}
+	for _, peer := range s.peers {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		report.PeersPolled++
+		if err := s.syncPeer(ctx, peer, report); err != nil {
+			report.PeersFailed++
+			log.Printf("Directory sync: failed to sync peer %s: %v", peer, err)
+		}
+	}
+	return report, nil
+}
+
+// syncPeer reads a peer's directory from its saved cursor, saving the cursor after each page
+func (s *directoryService) syncPeer(ctx context.Context, peer string, report *SyncReport) error {
+	peerURL, err := url.Parse(peer)
+	if err != nil || peerURL.Hostname() == "" {
+		return fmt.Errorf("invalid peer URL")
+	}
+	peerHost := strings.ToLower(peerURL.Hostname())
+
+	cursor, err := s.repo.GetPeerCursor(ctx, peer)
+	if err != nil {
+		return err
+	}
+
+	for page := 0; page < maxPagesPerPeer; page++ {
+		listing, err := s.fetchPage(ctx, peer, cursor)
+		if err != nil {
+			return err
+		}
+
+		for _, entry := range listing.Communities {
+			if entry != nil && !entry.Active() {
+				withdrawn, err := s.withdrawEntry(ctx, peerHost, entry)
+				if err != nil {
+					return err
+				}
+				if withdrawn {
+					report.Withdrawn++
+				} else {
+					report.Skipped++
+				}
+				continue
+			}
+			indexed, err := s.indexEntry(ctx, peerHost, entry)
+			if err != nil {
+				return err
+			}
+			if indexed {
+				report.Indexed++
+			} else {
+				report.Skipped++
+			}
+		}
+
+		if listing.Cursor == nil || *listing.Cursor == cursor {
+			return nil
+		}
+		cursor = *listing.Cursor
+		if err := s.repo.SavePeerCursor(ctx, peer, cursor); err != nil {
+			return err
+		}
+		if len(listing.Communities) < MaxLimit {
+			return nil
+		}
+	}
+	return nil
+}
+
+// indexEntry stores one of a peer's communities as a remote entry
+// Peers may only speak for communities they host: an entry whose hostedBy isn't the peer's own
+// domain is skipped, as are communities claimed to be hosted here and communities whose DID
+// document names a PDS outside the peer's domain.
+func (s *directoryService) indexEntry(ctx context.Context, peerHost string, entry *Entry) (bool, error) {
+	if entry == nil || !validEntry(entry) {
+		return false, nil
+	}
+	if entry.HostedBy == s.instanceDID || federation.HostDomain(entry.HostedBy) != peerHost {
+		return false, nil
+	}
+	if hosted, err := s.hostsDID(ctx, peerHost, entry.DID); err != nil || !hosted {
+		return false, err
+	}
+
+	blocked := false
+	if s.policy != nil {
+		var err error
+		if blocked, err = s.policy.IsHostBlocked(ctx, entry.HostedBy); err != nil {
+			return false, fmt.Errorf("failed to evaluate federation policy: %w", err)
+		}
+	}
+
+	indexed, err := s.repo.UpsertRemote(ctx, entry, blocked)
+	if err != nil {
+		// One bad entry (e.g. a handle already taken here) shouldn't stall the peer
+		log.Printf("Directory sync: skipping remote community %s (%s): %v", entry.DID, entry.Handle, err)
+		return false, nil
+	}
+	return indexed, nil
+}
+
+// withdrawEntry hides a remote community its peer now lists as a tombstone
+// As with indexEntry, the tombstone only counts for communities the peer hosts; the repository
+// checks the stored entry was learned from the same host.
+func (s *directoryService) withdrawEntry(ctx context.Context, peerHost string, entry *Entry) (bool, error) {
+	switch entry.Status {
+	case StatusDeleted, StatusSuspended, StatusUnlisted:
+	default:
+		return false, nil
+	}
+	if !strings.HasPrefix(entry.DID, "did:") || entry.HostedBy == s.instanceDID ||
+		federation.HostDomain(entry.HostedBy) != peerHost {
+		return false, nil
+	}
+
+	withdrawn, err := s.repo.WithdrawRemote(ctx, entry.DID, entry.HostedBy, entry.Status)
+	if err != nil {
+		return false, fmt.Errorf("failed to withdraw remote community %s: %w", entry.DID, err)
+	}
+	return withdrawn, nil
+}
+
+// hostsDID reports whether did's DID document names a PDS on peerHost or one of its subdomains
+// Unresolvable DIDs aren't hosted anywhere; other lookup failures are returned so the page is
+// retried on the next sync instead of skipped for good.
+func (s *directoryService) hostsDID(ctx context.Context, peerHost, did string) (bool, error) {
+	if s.identities == nil {
+		return false, nil
+	}
+	ident, err := s.identities.Resolve(ctx, did)
+	if err != nil {
+		var invalid *identity.ErrInvalidIdentifier
+		if errors.Is(err, coreerrors.ErrNotFound) || errors.As(err, &invalid) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to resolve community %s: %w", did, err)
+	}
+	if ident.DID != did {
+		return false, nil
+	}
+
+	pdsURL, err := url.Parse(ident.PDSURL)
+	if err != nil {
+		return false, nil
+	}
+	pdsHost := strings.ToLower(pdsURL.Hostname())
+	return pdsHost == peerHost || strings.HasSuffix(pdsHost, "."+peerHost), nil
+}
+
+func validEntry(entry *Entry) bool {
+	return strings.HasPrefix(entry.DID, "did:") &&
+		strings.HasPrefix(entry.CreatedBy, "did:") &&
+		entry.Handle != "" && len(entry.Handle) <= 253 &&
+		entry.Name != "" &&
+		!entry.UpdatedAt.IsZero()
+}
+
+// fetchPage requests one page of a peer's directory
+func (s *directoryService) fetchPage(ctx context.Context, peer, cursor string) (*ListResponse, error) {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(MaxLimit))
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	endpoint := peer + "/xrpc/social.coves.sync.listCommunities?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch directory: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("peer returned status %d", resp.StatusCode)
+	}
+
+	var listing ListResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBytes)).Decode(&listing); err != nil {
+		return nil, fmt.Errorf("failed to decode directory: %w", err)
+	}
+	return &listing, nil
+}
@@ -0,0 +1,362 @@
+package directory
+
+import (
+	"Coves/internal/atproto/identity"
+	"Coves/internal/pagination"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRepo keeps an instance's hosted and remote communities in memory
+type fakeRepo struct {
+	remote   map[string]*Entry
+	local    map[string]bool // DIDs indexed from the firehose
+	blocked  map[string]bool
+	cursors  map[string]string
+	hosted   []*Entry
+	upserted int
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		remote:  make(map[string]*Entry),
+		local:   make(map[string]bool),
+		blocked: make(map[string]bool),
+		cursors: make(map[string]string),
+	}
+}
+
+func (f *fakeRepo) ListHosted(ctx context.Context, hostedByDID string, after *Cursor, updatedBefore time.Time, limit int) ([]*Entry, error) {
+	var result []*Entry
+	for _, entry := range f.hosted {
+		if entry.HostedBy != hostedByDID || !entry.UpdatedAt.Before(updatedBefore) {
+			continue
+		}
+		if after != nil && (entry.UpdatedAt.Before(after.UpdatedAt) ||
+			(entry.UpdatedAt.Equal(after.UpdatedAt) && entry.DID <= after.DID)) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].UpdatedAt.Equal(result[j].UpdatedAt) {
+			return result[i].UpdatedAt.Before(result[j].UpdatedAt)
+		}
+		return result[i].DID < result[j].DID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (f *fakeRepo) UpsertRemote(ctx context.Context, entry *Entry, federationBlocked bool) (bool, error) {
+	if f.local[entry.DID] {
+		return false, nil
+	}
+	copied := *entry
+	f.remote[entry.DID] = &copied
+	f.blocked[entry.DID] = federationBlocked
+	f.upserted++
+	return true, nil
+}
+
+func (f *fakeRepo) WithdrawRemote(ctx context.Context, did, hostedByDID, status string) (bool, error) {
+	entry, ok := f.remote[did]
+	if !ok || entry.HostedBy != hostedByDID {
+		return false, nil
+	}
+	entry.Status = status
+	return true, nil
+}
+
+func (f *fakeRepo) GetPeerCursor(ctx context.Context, peerURL string) (string, error) {
+	return f.cursors[peerURL], nil
+}
+
+func (f *fakeRepo) SavePeerCursor(ctx context.Context, peerURL, cursor string) error {
+	f.cursors[peerURL] = cursor
+	return nil
+}
+
+// blockHosts blocks every community hosted on the listed DIDs
+type blockHosts map[string]bool
+
+func (b blockHosts) IsHostBlocked(ctx context.Context, hostedByDID string) (bool, error) {
+	return b[hostedByDID], nil
+}
+
+// fakeIdentities resolves DIDs to the PDS their DID document names
+// DIDs without an entry resolve to fallback, or not at all when it's empty.
+type fakeIdentities struct {
+	pds      map[string]string
+	fallback string
+	err      error
+}
+
+func (f *fakeIdentities) Resolve(ctx context.Context, did string) (*identity.Identity, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	pdsURL, ok := f.pds[did]
+	if !ok {
+		pdsURL = f.fallback
+	}
+	if pdsURL == "" {
+		return nil, &identity.ErrNotFound{Identifier: did}
+	}
+	return &identity.Identity{DID: did, PDSURL: pdsURL}, nil
+}
+
+func newTestCursors(t *testing.T) *pagination.Signer {
+	t.Helper()
+	cursors, err := pagination.NewSigner("directory-test-secret", "")
+	require.NoError(t, err)
+	return cursors
+}
+
+func testEntry(did, hostedBy string, updatedAt time.Time) *Entry {
+	name := strings.TrimPrefix(did, "did:plc:")
+	return &Entry{
+		DID:       did,
+		Handle:    "c-" + name + ".example",
+		Name:      name,
+		CreatedBy: "did:plc:creator",
+		HostedBy:  hostedBy,
+		CreatedAt: updatedAt,
+		UpdatedAt: updatedAt,
+	}
+}
+
+func listDIDs(entries []*Entry) []string {
+	dids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		dids = append(dids, entry.DID)
+	}
+	return dids
+}
+
+func TestDirectoryService_ListCommunitiesIncremental(t *testing.T) {
+	const instanceDID = "did:web:coves.local"
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+
+	repo := newFakeRepo()
+	repo.hosted = []*Entry{
+		testEntry("did:plc:a", instanceDID, base),
+		testEntry("did:plc:b", instanceDID, base), // Same updated_at: ordered by DID
+		testEntry("did:plc:c", instanceDID, base.Add(time.Minute)),
+		testEntry("did:plc:elsewhere", "did:web:other.example", base),
+	}
+	service := NewDirectoryService(repo, nil, nil, newTestCursors(t), instanceDID, nil)
+	ctx := context.Background()
+
+	first, err := service.ListCommunities(ctx, ListRequest{Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"did:plc:a", "did:plc:b"}, listDIDs(first.Communities))
+	require.NotNil(t, first.Cursor)
+
+	second, err := service.ListCommunities(ctx, ListRequest{Limit: 2, Cursor: *first.Cursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"did:plc:c"}, listDIDs(second.Communities))
+	require.NotNil(t, second.Cursor)
+
+	t.Run("caught up poll echoes the cursor", func(t *testing.T) {
+		resp, err := service.ListCommunities(ctx, ListRequest{Cursor: *second.Cursor})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Communities)
+		require.NotNil(t, resp.Cursor)
+		assert.Equal(t, *second.Cursor, *resp.Cursor)
+	})
+
+	t.Run("only communities updated since the cursor are returned", func(t *testing.T) {
+		repo.hosted[0].UpdatedAt = base.Add(2 * time.Minute)
+
+		resp, err := service.ListCommunities(ctx, ListRequest{Cursor: *second.Cursor})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"did:plc:a"}, listDIDs(resp.Communities))
+	})
+
+	t.Run("very recent updates wait for the listing lag", func(t *testing.T) {
+		repo.hosted[1].UpdatedAt = time.Now()
+
+		resp, err := service.ListCommunities(ctx, ListRequest{Cursor: *second.Cursor})
+		require.NoError(t, err)
+		assert.NotContains(t, listDIDs(resp.Communities), "did:plc:b")
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := service.ListCommunities(ctx, ListRequest{Cursor: "not-a-cursor"})
+		assert.True(t, IsValidationError(err))
+		_, err = service.ListCommunities(ctx, ListRequest{Limit: MaxLimit + 1})
+		assert.True(t, IsValidationError(err))
+	})
+
+	t.Run("forged cursors are rejected", func(t *testing.T) {
+		otherInstance, err := pagination.NewSigner("another-instance-secret", "")
+		require.NoError(t, err)
+		forged := Cursor{UpdatedAt: base, DID: "did:plc:a"}.Encode(otherInstance)
+
+		_, err = service.ListCommunities(ctx, ListRequest{Cursor: forged})
+		assert.True(t, IsValidationError(err))
+	})
+
+	t.Run("withdrawn communities are listed as tombstones", func(t *testing.T) {
+		repo.hosted[2].UpdatedAt = base.Add(3 * time.Minute)
+		repo.hosted[2].Status = StatusSuspended
+		repo.hosted[2].Description = "hidden"
+
+		resp, err := service.ListCommunities(ctx, ListRequest{Cursor: *second.Cursor})
+		require.NoError(t, err)
+		require.Equal(t, []string{"did:plc:a", "did:plc:c"}, listDIDs(resp.Communities))
+		tombstone := resp.Communities[1]
+		assert.Equal(t, StatusSuspended, tombstone.Status)
+		assert.Equal(t, instanceDID, tombstone.HostedBy)
+		assert.Empty(t, tombstone.Handle)
+		assert.Empty(t, tombstone.Description, "tombstones don't carry the community's data")
+	})
+}
+
+// newPeer serves a directory service's listing like a remote Coves instance
+// The peer's communities are hosted on the test server's own domain.
+func newPeer(t *testing.T) (*httptest.Server, *fakeRepo, string) {
+	t.Helper()
+	repo := newFakeRepo()
+	var service Service
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/xrpc/social.coves.sync.listCommunities", r.URL.Path)
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		resp, err := service.ListCommunities(r.Context(), ListRequest{Limit: limit, Cursor: r.URL.Query().Get("cursor")})
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+
+	peerURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	peerDID := "did:web:" + peerURL.Hostname()
+	service = NewDirectoryService(repo, nil, nil, newTestCursors(t), peerDID, nil)
+	return server, repo, peerDID
+}
+
+func TestDirectoryService_SyncPeers(t *testing.T) {
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	peer, peerRepo, peerDID := newPeer(t)
+
+	// One more than a page, so the sync has to follow the cursor
+	for i := 0; i <= MaxLimit; i++ {
+		peerRepo.hosted = append(peerRepo.hosted, testEntry("did:plc:remote"+strconv.Itoa(i), peerDID, base.Add(time.Duration(i)*time.Second)))
+	}
+	// A peer can't speak for communities hosted elsewhere
+	peerRepo.hosted = append(peerRepo.hosted, testEntry("did:plc:spoofed", "did:web:victim.example", base))
+
+	repo := newFakeRepo()
+	repo.local["did:plc:remote0"] = true // Already indexed from the firehose
+	identities := &fakeIdentities{pds: map[string]string{}, fallback: peer.URL}
+	service := NewDirectoryService(repo, blockHosts{peerDID: true}, identities, newTestCursors(t), "did:web:coves.local", []string{peer.URL + "/"})
+	ctx := context.Background()
+
+	report, err := service.SyncPeers(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.PeersPolled)
+	assert.Zero(t, report.PeersFailed)
+	assert.Equal(t, MaxLimit, report.Indexed)
+	assert.Equal(t, 1, report.Skipped, "the firehose-indexed community is left alone")
+	assert.Len(t, repo.remote, MaxLimit)
+	assert.NotContains(t, repo.remote, "did:plc:remote0")
+	assert.NotContains(t, repo.remote, "did:plc:spoofed")
+	assert.True(t, repo.blocked["did:plc:remote1"], "federation policy applies to remote entries")
+	require.NotEmpty(t, repo.cursors[peer.URL], "the cursor is saved per peer")
+
+	t.Run("incremental poll only fetches updated communities", func(t *testing.T) {
+		repo.upserted = 0
+		report, err := service.SyncPeers(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, report.Indexed)
+
+		peerRepo.hosted[5].UpdatedAt = base.Add(time.Hour - time.Minute)
+		peerRepo.hosted[5].SubscriberCount = 42
+		report, err = service.SyncPeers(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Indexed)
+		assert.Equal(t, 1, repo.upserted)
+		assert.Equal(t, 42, repo.remote["did:plc:remote5"].SubscriberCount)
+		assert.Len(t, repo.remote, MaxLimit, "an update doesn't duplicate the entry")
+	})
+
+	t.Run("failing peer doesn't stop the others", func(t *testing.T) {
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		t.Cleanup(down.Close)
+
+		service := NewDirectoryService(newFakeRepo(), nil, identities, newTestCursors(t), "did:web:coves.local", []string{down.URL, peer.URL})
+		report, err := service.SyncPeers(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.PeersPolled)
+		assert.Equal(t, 1, report.PeersFailed)
+		assert.Equal(t, MaxLimit+1, report.Indexed)
+	})
+
+	t.Run("tombstones withdraw indexed communities", func(t *testing.T) {
+		peerRepo.hosted[6].UpdatedAt = base.Add(time.Hour - 30*time.Second)
+		peerRepo.hosted[6].Status = StatusDeleted
+		peerRepo.hosted[7].UpdatedAt = base.Add(time.Hour - 20*time.Second)
+		peerRepo.hosted[7].Status = StatusUnlisted
+
+		report, err := service.SyncPeers(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, report.Withdrawn)
+		assert.Equal(t, StatusDeleted, repo.remote["did:plc:remote6"].Status)
+		assert.Equal(t, StatusUnlisted, repo.remote["did:plc:remote7"].Status)
+	})
+}
+
+func TestDirectoryService_SyncPeersVerifiesDIDDocuments(t *testing.T) {
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	peer, peerRepo, peerDID := newPeer(t)
+	for _, did := range []string{"did:plc:hosted", "did:plc:elsewhere", "did:plc:unknown"} {
+		peerRepo.hosted = append(peerRepo.hosted, testEntry(did, peerDID, base))
+	}
+	ctx := context.Background()
+
+	t.Run("only communities whose PDS is on the peer are indexed", func(t *testing.T) {
+		repo := newFakeRepo()
+		identities := &fakeIdentities{pds: map[string]string{
+			"did:plc:hosted":    peer.URL,
+			"did:plc:elsewhere": "https://pds.victim.example",
+		}}
+		service := NewDirectoryService(repo, nil, identities, newTestCursors(t), "did:web:coves.local", []string{peer.URL})
+
+		report, err := service.SyncPeers(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Indexed)
+		assert.Equal(t, 2, report.Skipped)
+		assert.Contains(t, repo.remote, "did:plc:hosted")
+		assert.NotContains(t, repo.remote, "did:plc:elsewhere", "the DID document names another PDS")
+		assert.NotContains(t, repo.remote, "did:plc:unknown", "unresolvable DIDs are skipped")
+	})
+
+	t.Run("a failed lookup retries the page", func(t *testing.T) {
+		repo := newFakeRepo()
+		identities := &fakeIdentities{err: errors.New("plc directory unavailable")}
+		service := NewDirectoryService(repo, nil, identities, newTestCursors(t), "did:web:coves.local", []string{peer.URL})
+
+		report, err := service.SyncPeers(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.PeersFailed)
+		assert.Empty(t, repo.remote)
+		assert.Empty(t, repo.cursors[peer.URL], "the cursor stays put so the page is read again")
+	})
+}
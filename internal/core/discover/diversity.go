@@ -0,0 +1,158 @@
+package discover
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Diversity defaults
+const (
+	DefaultMaxConsecutive = 2   // Posts in a row from one community
+	DefaultMaxShare       = 0.3 // Fraction of a page from one community
+	DefaultOverFetch      = 2   // Candidates fetched per post on the page
+
+	// maxSkippedPosts bounds the posts a discover cursor carries as already shown
+	maxSkippedPosts = 100
+)
+
+// DiversityConfig keeps one large community from taking over a discover page
+// Posts over either limit are moved later on the page or to a later page. A zero limit disables it.
+type DiversityConfig struct {
+	MaxConsecutive int     // Most posts in a row from one community
+	MaxShare       float64 // Largest fraction of a page one community may take
+	OverFetch      int     // Candidates fetched per page slot, so there is something to reorder with
+}
+
+// DefaultDiversityConfig returns the default discover diversity limits
+func DefaultDiversityConfig() DiversityConfig {
+	return DiversityConfig{
+		MaxConsecutive: DefaultMaxConsecutive,
+		MaxShare:       DefaultMaxShare,
+		OverFetch:      DefaultOverFetch,
+	}
+}
+
+// Enabled reports whether either limit applies
+func (c DiversityConfig) Enabled() bool {
+	return c.MaxConsecutive > 0 || c.MaxShare > 0
+}
+
+// maxPerPage is the most posts one community may have on a page of limit posts
+func (c DiversityConfig) maxPerPage(limit int) int {
+	if c.MaxShare <= 0 {
+		return limit
+	}
+	return max(1, int(math.Floor(c.MaxShare*float64(limit))))
+}
+
+// Diversify picks a page of up to limit posts from candidates, given in rank order by their
+// community DIDs, and returns the page as indexes into candidates
+// Each slot gets the highest-ranked remaining candidate that keeps within the limits. A
+// candidate is only held back while another one can take its place, so a feed with nothing
+// but one community still fills its pages.
+func Diversify(communities []string, limit int, cfg DiversityConfig) []int {
+	page := make([]int, 0, min(limit, len(communities)))
+	used := make([]bool, len(communities))
+	perCommunity := make(map[string]int)
+	maxPerPage := cfg.maxPerPage(limit)
+	run := 0
+
+	fits := func(i int) bool {
+		community := communities[i]
+		if perCommunity[community] >= maxPerPage {
+			return false
+		}
+		return cfg.MaxConsecutive <= 0 || len(page) == 0 || run < cfg.MaxConsecutive ||
+			communities[page[len(page)-1]] != community
+	}
+
+	for len(page) < limit {
+		pick := -1
+		for i := range communities {
+			if !used[i] && fits(i) {
+				pick = i
+				break
+			}
+		}
+		if pick < 0 {
+			// Nothing keeps within the limits; take the highest-ranked remaining candidate
+			for i := range communities {
+				if !used[i] {
+					pick = i
+					break
+				}
+			}
+		}
+		if pick < 0 {
+			break
+		}
+
+		if len(page) > 0 && communities[page[len(page)-1]] == communities[pick] {
+			run++
+		} else {
+			run = 1
+		}
+		used[pick] = true
+		perCommunity[communities[pick]]++
+		page = append(page, pick)
+	}
+	return page
+}
+
+// discoverCursor is the repository cursor to resume from plus the posts past it that earlier
+// pages already showed
+// Diversify can show a post ahead of higher-ranked ones it holds back, so the repository cursor
+// stays at the last post before the first one held back, and the posts shown past it are
+// skipped when the next page is fetched.
+type discoverCursor struct {
+	skip  map[string]bool // postKey of posts already shown
+	after *string         // Repository cursor; nil for the first page
+}
+
+// encode returns the cursor string: the repository cursor, then "." and the skipped posts
+// Repository cursors are unpadded base64url, which never contains ".".
+func (c discoverCursor) encode(skipped []string) string {
+	after := ""
+	if c.after != nil {
+		after = *c.after
+	}
+	if len(skipped) == 0 {
+		return after
+	}
+	return after + "." + strings.Join(skipped, "")
+}
+
+// parseDiscoverCursor splits a cursor from discoverCursor.encode
+func parseDiscoverCursor(cursor *string) (discoverCursor, error) {
+	if cursor == nil || *cursor == "" {
+		return discoverCursor{}, nil
+	}
+	after, skipped, found := strings.Cut(*cursor, ".")
+	parsed := discoverCursor{}
+	if after != "" {
+		parsed.after = &after
+	}
+	if !found {
+		return parsed, nil
+	}
+	if skipped == "" || len(skipped)%postKeyLength != 0 || len(skipped)/postKeyLength > maxSkippedPosts {
+		return discoverCursor{}, fmt.Errorf("%w: malformed skip list", ErrInvalidCursor)
+	}
+	parsed.skip = make(map[string]bool, len(skipped)/postKeyLength)
+	for i := 0; i < len(skipped); i += postKeyLength {
+		parsed.skip[skipped[i:i+postKeyLength]] = true
+	}
+	return parsed, nil
+}
+
+// postKeyLength is the length of a postKey: 8 bytes of SHA-256, unpadded base64url
+const postKeyLength = 11
+
+// postKey is a short, cursor-safe stand-in for a post URI
+func postKey(uri string) string {
+	sum := sha256.Sum256([]byte(uri))
+	return base64.RawURLEncoding.EncodeToString(sum[:8])
+}
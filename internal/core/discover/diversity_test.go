@@ -0,0 +1,244 @@
+package discover
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"Coves/internal/core/posts"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pickedCommunities(communities []string, page []int) []string {
+	picked := make([]string, 0, len(page))
+	for _, i := range page {
+		picked = append(picked, communities[i])
+	}
+	return picked
+}
+
+// assertDiverse checks no community runs longer than maxRun or takes more than maxCount of page
+func assertDiverse(t *testing.T, page []string, maxRun, maxCount int) {
+	t.Helper()
+	counts := make(map[string]int)
+	run := 0
+	for i, community := range page {
+		if i > 0 && page[i-1] == community {
+			run++
+		} else {
+			run = 1
+		}
+		counts[community]++
+		assert.LessOrEqual(t, run, maxRun, "run of %s at position %d in %v", community, i, page)
+	}
+	for community, count := range counts {
+		assert.LessOrEqual(t, count, maxCount, "%s has %d posts in %v", community, count, page)
+	}
+}
+
+func TestDiversify(t *testing.T) {
+	cfg := DefaultDiversityConfig()
+
+	t.Run("caps runs and share of one big community", func(t *testing.T) {
+		communities := []string{"big", "big", "big", "big", "big", "big", "big", "big", "a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"}
+		page := Diversify(communities, 10, cfg)
+
+		require.Len(t, page, 10)
+		picked := pickedCommunities(communities, page)
+		assertDiverse(t, picked, 2, 3)
+		assert.Equal(t, []string{"big", "big", "a", "big", "b", "c", "d", "e", "f", "g"}, picked)
+	})
+
+	t.Run("keeps rank order when already diverse", func(t *testing.T) {
+		communities := []string{"a", "b", "a", "c", "b", "d"}
+		assert.Equal(t, []int{0, 1, 2, 3, 4, 5}, Diversify(communities, 10, cfg))
+	})
+
+	t.Run("fills the page when only one community has posts", func(t *testing.T) {
+		communities := []string{"only", "only", "only", "only", "only"}
+		assert.Equal(t, []int{0, 1, 2, 3, 4}, Diversify(communities, 5, cfg))
+	})
+
+	t.Run("relaxes only as far as needed", func(t *testing.T) {
+		// Three posts from others can break up big's run but not bring it under its share
+		communities := []string{"big", "big", "big", "big", "big", "big", "a", "b", "c", "big"}
+		picked := pickedCommunities(communities, Diversify(communities, 8, cfg))
+		assert.Equal(t, []string{"big", "big", "a", "b", "c", "big", "big", "big"}, picked)
+	})
+
+	t.Run("short candidate list", func(t *testing.T) {
+		assert.Equal(t, []int{0, 1}, Diversify([]string{"a", "a"}, 10, cfg))
+		assert.Empty(t, Diversify(nil, 10, cfg))
+	})
+
+	t.Run("disabled limits keep rank order", func(t *testing.T) {
+		communities := []string{"big", "big", "big", "a"}
+		assert.Equal(t, []int{0, 1, 2, 3}, Diversify(communities, 4, DiversityConfig{}))
+	})
+}
+
+func TestDiscoverCursor(t *testing.T) {
+	after := "cursorAfter"
+	encoded := discoverCursor{after: &after}.encode([]string{postKey("at://a"), postKey("at://b")})
+
+	parsed, err := parseDiscoverCursor(&encoded)
+	require.NoError(t, err)
+	require.NotNil(t, parsed.after)
+	assert.Equal(t, after, *parsed.after)
+	assert.True(t, parsed.skip[postKey("at://a")])
+	assert.True(t, parsed.skip[postKey("at://b")])
+
+	// Without skipped posts the cursor is just the repository's
+	plain := discoverCursor{after: &after}.encode(nil)
+	assert.Equal(t, after, plain)
+
+	for _, bad := range []string{"cursor.", "cursor.short", "cursor." + postKey("at://a") + "x"} {
+		_, err := parseDiscoverCursor(&bad)
+		assert.ErrorIs(t, err, ErrInvalidCursor, bad)
+	}
+}
+
+// rankedDiscoverRepo serves a fixed ranked feed; candidate cursors are the post's index
+type rankedDiscoverRepo struct {
+	Repository
+	feed []*FeedViewPost
+}
+
+func (r *rankedDiscoverRepo) GetDiscoverCandidates(ctx context.Context, req GetDiscoverRequest) ([]*DiscoverCandidate, error) {
+	start := 0
+	if req.Cursor != nil {
+		index, err := strconv.Atoi(*req.Cursor)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		start = index + 1
+	}
+	var candidates []*DiscoverCandidate
+	for i := start; i < len(r.feed) && len(candidates) < req.Limit; i++ {
+		candidates = append(candidates, &DiscoverCandidate{Post: r.feed[i], Cursor: strconv.Itoa(i)})
+	}
+	return candidates, nil
+}
+
+func TestGetDiscover_DiversePagination(t *testing.T) {
+	// A huge community dominates the top of the ranking; smaller ones trickle in below it
+	var feed []*FeedViewPost
+	add := func(community string) {
+		feed = append(feed, &FeedViewPost{Post: &posts.PostView{
+			URI:       fmt.Sprintf("at://%s/social.coves.community.post/%d", community, len(feed)),
+			Community: &posts.CommunityRef{DID: community},
+		}})
+	}
+	for i := 0; i < 10; i++ {
+		add("did:plc:huge")
+	}
+	for i := 0; i < 45; i++ {
+		if i%3 == 0 {
+			add("did:plc:huge")
+		}
+		add(fmt.Sprintf("did:plc:small%d", i%7))
+	}
+	service := NewDiscoverService(&rankedDiscoverRepo{feed: feed})
+
+	seen := make(map[string]bool)
+	var cursor *string
+	for pageNum := 1; pageNum <= 3; pageNum++ {
+		resp, err := service.GetDiscover(context.Background(), GetDiscoverRequest{Sort: "hot", Limit: 10, Cursor: cursor})
+		require.NoError(t, err)
+		require.Len(t, resp.Feed, 10, "page %d", pageNum)
+		require.NotNil(t, resp.Cursor, "page %d", pageNum)
+
+		page := make([]string, 0, len(resp.Feed))
+		for _, post := range resp.Feed {
+			assert.False(t, seen[post.Post.URI], "page %d repeats %s", pageNum, post.Post.URI)
+			seen[post.Post.URI] = true
+			page = append(page, post.Post.Community.DID)
+		}
+		assertDiverse(t, page, 2, 3)
+		cursor = resp.Cursor
+	}
+
+	// Every small-community post ranked within the pages served has been shown, and the
+	// huge community's held-back posts resume where they left off
+	for i, post := range feed[:20] {
+		if post.Post.Community.DID != "did:plc:huge" {
+			assert.True(t, seen[post.Post.URI], "post %d (%s) was skipped", i, post.Post.URI)
+		}
+	}
+
+	// Paging to the end shows every post exactly once
+	for cursor != nil {
+		resp, err := service.GetDiscover(context.Background(), GetDiscoverRequest{Sort: "hot", Limit: 10, Cursor: cursor})
+		require.NoError(t, err)
+		for _, post := range resp.Feed {
+			assert.False(t, seen[post.Post.URI], "repeats %s", post.Post.URI)
+			seen[post.Post.URI] = true
+		}
+		cursor = resp.Cursor
+	}
+	assert.Len(t, seen, len(feed))
+}
+
+func TestGetDiscover_HeldBackPostsStayBounded(t *testing.T) {
+	// Half the feed is one community, so posts keep being held back page after page
+	var feed []*FeedViewPost
+	for i := 0; i < 400; i++ {
+		community := "did:plc:huge"
+		if i%2 == 1 {
+			community = fmt.Sprintf("did:plc:small%d", i%9)
+		}
+		feed = append(feed, &FeedViewPost{Post: &posts.PostView{
+			URI:       fmt.Sprintf("at://%s/social.coves.community.post/%d", community, i),
+			Community: &posts.CommunityRef{DID: community},
+		}})
+	}
+	service := NewDiscoverService(&rankedDiscoverRepo{feed: feed})
+
+	seen := make(map[string]bool)
+	var cursor *string
+	for pages := 0; pages == 0 || cursor != nil; pages++ {
+		require.Less(t, pages, len(feed), "pagination never ends")
+		resp, err := service.GetDiscover(context.Background(), GetDiscoverRequest{Sort: "top", Limit: 10, Cursor: cursor})
+		require.NoError(t, err)
+		for _, post := range resp.Feed {
+			require.False(t, seen[post.Post.URI], "repeats %s", post.Post.URI)
+			seen[post.Post.URI] = true
+		}
+		if resp.Cursor != nil {
+			parsed, err := parseDiscoverCursor(resp.Cursor)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(parsed.skip), maxSkippedPosts)
+		}
+		cursor = resp.Cursor
+	}
+	assert.Len(t, seen, len(feed))
+}
+
+func TestGetDiscover_NewIsNotReordered(t *testing.T) {
+	var feed []*FeedViewPost
+	for i := 0; i < 5; i++ {
+		feed = append(feed, &FeedViewPost{Post: &posts.PostView{
+			URI:       fmt.Sprintf("at://did:plc:huge/social.coves.community.post/%d", i),
+			Community: &posts.CommunityRef{DID: "did:plc:huge"},
+		}})
+	}
+	repo := &newFeedRepo{rankedDiscoverRepo: rankedDiscoverRepo{feed: feed}}
+	resp, err := NewDiscoverService(repo).GetDiscover(context.Background(), GetDiscoverRequest{Sort: "new", Limit: 5})
+	require.NoError(t, err)
+	assert.Equal(t, feedURIs(feed), feedURIs(resp.Feed))
+	assert.True(t, repo.plainFetch)
+}
+
+// newFeedRepo records whether the service asked for a plain page
+type newFeedRepo struct {
+	rankedDiscoverRepo
+	plainFetch bool
+}
+
+func (r *newFeedRepo) GetDiscover(ctx context.Context, req GetDiscoverRequest) ([]*FeedViewPost, *string, error) {
+	r.plainFetch = true
+	return r.feed[:min(req.Limit, len(r.feed))], nil, nil
+}
@@ -0,0 +1,94 @@
+package discover
+
+import (
+	"sort"
+)
+
+const (
+	// DefaultFrontPageLimit is the number of posts on the front page when no limit is given
+	DefaultFrontPageLimit = 25
+
+	// MaxFrontPageLimit caps the front page size
+	// Anonymous pages are cached per limit, so this also bounds how many pages the cache holds
+	MaxFrontPageLimit = 50
+
+	// DefaultFeaturedWeight boosts featured communities over the global hot feed by default
+	DefaultFeaturedWeight = 2.0
+
+	// MaxFeaturedWeight keeps a single featured community from drowning out everything else
+	MaxFeaturedWeight = 10.0
+)
+
+// RankedPost is a feed post with the hot rank it was sorted by
+type RankedPost struct {
+	Post    *FeedViewPost
+	HotRank float64
+}
+
+// valid reports whether the ranked post has a post view to score
+func (r *RankedPost) valid() bool {
+	return r != nil && r.Post != nil && r.Post.Post != nil
+}
+
+// BlendFrontPage merges hot posts from featured communities with globally hot posts
+// Featured posts are scored by hot rank times their community's weight (1 when the
+// community has no weight); global posts keep their hot rank. A post in both lists counts
+// once, at its higher score. Ties fall back to newest first, then URI, so the order is stable.
+func BlendFrontPage(featured, global []*RankedPost, weights map[string]float64, limit int) []*FeedViewPost {
+	type scoredPost struct {
+		post  *FeedViewPost
+		score float64
+	}
+
+	byURI := make(map[string]*scoredPost, len(featured)+len(global))
+	add := func(post *FeedViewPost, score float64) {
+		if existing, ok := byURI[post.Post.URI]; ok {
+			existing.score = max(existing.score, score)
+			return
+		}
+		byURI[post.Post.URI] = &scoredPost{post: post, score: score}
+	}
+
+	for _, ranked := range featured {
+		if !ranked.valid() {
+			continue
+		}
+		weight := 1.0
+		if community := ranked.Post.Post.Community; community != nil {
+			if w, ok := weights[community.DID]; ok && w > 0 {
+				weight = w
+			}
+		}
+		add(ranked.Post, ranked.HotRank*weight)
+	}
+	for _, ranked := range global {
+		if ranked.valid() {
+			add(ranked.Post, ranked.HotRank)
+		}
+	}
+
+	scored := make([]*scoredPost, 0, len(byURI))
+	for _, sp := range byURI {
+		scored = append(scored, sp)
+	}
+	sort.Slice(scored, func(i, j int) bool {
+		a, b := scored[i], scored[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if !a.post.Post.CreatedAt.Equal(b.post.Post.CreatedAt) {
+			return a.post.Post.CreatedAt.After(b.post.Post.CreatedAt)
+		}
+		return a.post.Post.URI > b.post.Post.URI
+	})
+
+	if limit >= 0 && len(scored) > limit {
+		scored = scored[:limit]
+	}
+
+	feed := make([]*FeedViewPost, 0, len(scored))
+	for _, sp := range scored {
+		feed = append(feed, sp.post)
+	}
+	return feed
+}
@@ -0,0 +1,100 @@
+package discover
+
+import (
+	"testing"
+	"time"
+
+	"Coves/internal/core/posts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var blendBaseTime = time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func rankedPost(uri, communityDID string, hotRank float64, age time.Duration) *RankedPost {
+	return &RankedPost{
+		Post: &FeedViewPost{Post: &posts.PostView{
+			URI:       uri,
+			CreatedAt: blendBaseTime.Add(-age),
+			Community: &posts.CommunityRef{DID: communityDID},
+		}},
+		HotRank: hotRank,
+	}
+}
+
+func feedURIs(feed []*FeedViewPost) []string {
+	uris := make([]string, 0, len(feed))
+	for _, fp := range feed {
+		uris = append(uris, fp.Post.URI)
+	}
+	return uris
+}
+
+func TestBlendFrontPage(t *testing.T) {
+	t.Run("featured posts are boosted by weight", func(t *testing.T) {
+		featured := []*RankedPost{rankedPost("at://featured/1", "did:plc:featured", 3, time.Hour)}
+		global := []*RankedPost{
+			rankedPost("at://global/1", "did:plc:other", 5, time.Hour),
+			rankedPost("at://global/2", "did:plc:other", 7, time.Hour),
+		}
+
+		feed := BlendFrontPage(featured, global, map[string]float64{"did:plc:featured": 2}, 10)
+
+		// featured scores 3*2=6, between the two global posts
+		assert.Equal(t, []string{"at://global/2", "at://featured/1", "at://global/1"}, feedURIs(feed))
+	})
+
+	t.Run("featured posts without a weight are not boosted", func(t *testing.T) {
+		featured := []*RankedPost{rankedPost("at://featured/1", "did:plc:unknown", 3, time.Hour)}
+		global := []*RankedPost{rankedPost("at://global/1", "did:plc:other", 5, time.Hour)}
+
+		feed := BlendFrontPage(featured, global, map[string]float64{}, 10)
+
+		assert.Equal(t, []string{"at://global/1", "at://featured/1"}, feedURIs(feed))
+	})
+
+	t.Run("posts in both lists appear once at their higher score", func(t *testing.T) {
+		featured := []*RankedPost{rankedPost("at://shared/1", "did:plc:featured", 4, time.Hour)}
+		global := []*RankedPost{
+			rankedPost("at://shared/1", "did:plc:featured", 4, time.Hour),
+			rankedPost("at://global/1", "did:plc:other", 10, time.Hour),
+		}
+
+		feed := BlendFrontPage(featured, global, map[string]float64{"did:plc:featured": 3}, 10)
+
+		// shared post scores 4*3=12 from the featured list, beating the global post
+		assert.Equal(t, []string{"at://shared/1", "at://global/1"}, feedURIs(feed))
+	})
+
+	t.Run("ties break by newest then URI", func(t *testing.T) {
+		global := []*RankedPost{
+			rankedPost("at://a", "did:plc:other", 1, 2*time.Hour),
+			rankedPost("at://b", "did:plc:other", 1, time.Hour),
+			rankedPost("at://c", "did:plc:other", 1, 2*time.Hour),
+		}
+
+		feed := BlendFrontPage(nil, global, nil, 10)
+
+		assert.Equal(t, []string{"at://b", "at://c", "at://a"}, feedURIs(feed))
+	})
+
+	t.Run("truncates to limit", func(t *testing.T) {
+		global := []*RankedPost{
+			rankedPost("at://global/1", "did:plc:other", 3, time.Hour),
+			rankedPost("at://global/2", "did:plc:other", 2, time.Hour),
+			rankedPost("at://global/3", "did:plc:other", 1, time.Hour),
+		}
+
+		feed := BlendFrontPage(nil, global, nil, 2)
+
+		assert.Equal(t, []string{"at://global/1", "at://global/2"}, feedURIs(feed))
+	})
+
+	t.Run("skips posts without a view", func(t *testing.T) {
+		global := []*RankedPost{nil, {HotRank: 5}, rankedPost("at://global/1", "did:plc:other", 1, time.Hour)}
+
+		feed := BlendFrontPage(nil, global, nil, 10)
+
+		assert.Equal(t, []string{"at://global/1"}, feedURIs(feed))
+	})
+}
@@ -1,21 +1,32 @@
 package discover
 
 import (
+	"Coves/internal/core/posts"
 	"context"
 	"fmt"
+	"strings"
 )
 
 type discoverService struct {
-	repo Repository
+	repo      Repository
+	diversity DiversityConfig
 }
 
 // NewDiscoverService creates a new discover service
+// Hot and top pages are diversified with DefaultDiversityConfig; see SetDiversity.
 func NewDiscoverService(repo Repository) Service {
 	return &discoverService{
-		repo: repo,
+		repo:      repo,
+		diversity: DefaultDiversityConfig(),
 	}
 }
 
+// SetDiversity sets the limits on how much of a hot or top page one community may take
+// A config with neither limit set returns pages in plain rank order.
+func (s *discoverService) SetDiversity(cfg DiversityConfig) {
+	s.diversity = cfg
+}
+
 // GetDiscover retrieves posts from all communities (public feed)
 func (s *discoverService) GetDiscover(ctx context.Context, req GetDiscoverRequest) (*DiscoverResponse, error) {
 	// Validate request
@@ -23,6 +34,11 @@ func (s *discoverService) GetDiscover(ctx context.Context, req GetDiscoverReques
 		return nil, err
 	}
 
+	// New is strictly chronological; hot and top keep big communities from filling the page
+	if req.Sort != "new" && s.diversity.Enabled() {
+		return s.getDiverseDiscover(ctx, req)
+	}
+
 	// Fetch discover feed from repository (all posts from all communities)
 	feedPosts, cursor, err := s.repo.GetDiscover(ctx, req)
 	if err != nil {
@@ -36,6 +52,231 @@ func (s *discoverService) GetDiscover(ctx context.Context, req GetDiscoverReques
 	}, nil
 }
 
+// getDiverseDiscover fetches an over-sized window of ranked posts and picks the page with Diversify
+// Posts held back stay ahead of the returned cursor, so a later page shows them; posts shown
+// out of rank order are carried in the cursor so that page doesn't show them again.
+func (s *discoverService) getDiverseDiscover(ctx context.Context, req GetDiscoverRequest) (*DiscoverResponse, error) {
+	cursor, err := parseDiscoverCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Posts already shown don't count toward the over-fetch
+	fetch := req
+	fetch.Cursor = cursor.after
+	fetch.Limit = max(req.Limit, min(req.Limit*s.diversity.OverFetch, maxSkippedPosts)) + len(cursor.skip)
+	candidates, err := s.repo.GetDiscoverCandidates(ctx, fetch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get discover feed: %w", err)
+	}
+
+	shown := make([]bool, len(candidates))
+	pending := make([]int, 0, len(candidates))
+	communities := make([]string, 0, len(candidates))
+	for i, candidate := range candidates {
+		if cursor.skip[postKey(candidate.Post.Post.URI)] {
+			shown[i] = true
+			continue
+		}
+		pending = append(pending, i)
+		communities = append(communities, candidateCommunity(candidate))
+	}
+
+	// Once the posts shown ahead of the cursor fill it, pages go in rank order until the posts
+	// held back have been shown and the cursor catches up
+	diversity := s.diversity
+	if len(cursor.skip)+req.Limit > maxSkippedPosts {
+		diversity = DiversityConfig{}
+	}
+
+	feed := make([]*FeedViewPost, 0, req.Limit)
+	for _, j := range Diversify(communities, req.Limit, diversity) {
+		shown[pending[j]] = true
+		feed = append(feed, candidates[pending[j]].Post)
+	}
+
+	// Resume after the last post before the first one still to be shown
+	next := discoverCursor{after: cursor.after}
+	resume := 0
+	for resume < len(candidates) && shown[resume] {
+		next.after = &candidates[resume].Cursor
+		resume++
+	}
+	var skipped []string
+	remaining := len(candidates) == fetch.Limit
+	for i := resume; i < len(candidates); i++ {
+		if shown[i] {
+			skipped = append(skipped, postKey(candidates[i].Post.Post.URI))
+		} else {
+			remaining = true
+		}
+	}
+
+	resp := &DiscoverResponse{Feed: feed}
+	if remaining {
+		encoded := next.encode(skipped)
+		resp.Cursor = &encoded
+	}
+	return resp, nil
+}
+
+func candidateCommunity(candidate *DiscoverCandidate) string {
+	if candidate.Post.Post.Community == nil {
+		return ""
+	}
+	return candidate.Post.Post.Community.DID
+}
+
+// GetDiscussions finds posts linking to req.URL
+// The URL is canonicalized the same way as post links at index time, so e.g. youtu.be and
+// youtube.com/watch?v= links to the same video match
+func (s *discoverService) GetDiscussions(ctx context.Context, req GetDiscussionsRequest) (*DiscoverResponse, error) {
+	if req.URL == "" {
+		return nil, NewValidationError("url", "url is required")
+	}
+	linkHash, err := posts.LinkHash(req.URL)
+	if err != nil {
+		return nil, NewValidationError("url", err.Error())
+	}
+	req.LinkHash = linkHash
+
+	if req.Limit <= 0 {
+		req.Limit = 15
+	}
+	if req.Limit > 50 {
+		return nil, NewValidationError("limit", "limit must not exceed 50")
+	}
+
+	feedPosts, cursor, err := s.repo.GetDiscussions(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get discussions: %w", err)
+	}
+	if feedPosts == nil {
+		feedPosts = []*FeedViewPost{}
+	}
+
+	return &DiscoverResponse{
+		Feed:   feedPosts,
+		Cursor: cursor,
+	}, nil
+}
+
+// GetFrontPage builds the logged-out home feed
+// Hot posts from featured communities are weighted and blended with globally hot posts.
+// Falls back to the discover hot feed when no communities are featured.
+func (s *discoverService) GetFrontPage(ctx context.Context, req GetFrontPageRequest) (*DiscoverResponse, error) {
+	if req.Limit <= 0 {
+		req.Limit = DefaultFrontPageLimit
+	}
+	if req.Limit > MaxFrontPageLimit {
+		return nil, NewValidationError("limit", fmt.Sprintf("limit must not exceed %d", MaxFrontPageLimit))
+	}
+
+	featured, err := s.repo.ListFeaturedCommunities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list featured communities: %w", err)
+	}
+
+	if len(featured) == 0 {
+		feedPosts, _, err := s.repo.GetDiscover(ctx, GetDiscoverRequest{Sort: "hot", Limit: req.Limit})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get discover feed: %w", err)
+		}
+		return &DiscoverResponse{Feed: feedPosts}, nil
+	}
+
+	communityDIDs := make([]string, 0, len(featured))
+	weights := make(map[string]float64, len(featured))
+	for _, fc := range featured {
+		communityDIDs = append(communityDIDs, fc.CommunityDID)
+		weights[fc.CommunityDID] = fc.Weight
+	}
+
+	featuredPosts, err := s.repo.GetHotPosts(ctx, communityDIDs, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get featured community posts: %w", err)
+	}
+	globalPosts, err := s.repo.GetHotPosts(ctx, nil, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hot posts: %w", err)
+	}
+
+	return &DiscoverResponse{
+		Feed: BlendFrontPage(featuredPosts, globalPosts, weights, req.Limit),
+	}, nil
+}
+
+// ListFeaturedCommunities returns the front page's featured communities in order
+func (s *discoverService) ListFeaturedCommunities(ctx context.Context) ([]*FeaturedCommunity, error) {
+	featured, err := s.repo.ListFeaturedCommunities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list featured communities: %w", err)
+	}
+	return featured, nil
+}
+
+// AddFeaturedCommunity features a community on the front page (or updates its weight)
+func (s *discoverService) AddFeaturedCommunity(ctx context.Context, req AddFeaturedCommunityRequest) (*FeaturedCommunity, error) {
+	if !strings.HasPrefix(req.CommunityDID, "did:") {
+		return nil, NewValidationError("community", "community must be a DID")
+	}
+
+	weight := DefaultFeaturedWeight
+	if req.Weight != nil {
+		weight = *req.Weight
+	}
+	if weight <= 0 || weight > MaxFeaturedWeight {
+		return nil, NewValidationError("weight", fmt.Sprintf("weight must be greater than 0 and at most %g", MaxFeaturedWeight))
+	}
+
+	featured := &FeaturedCommunity{
+		CommunityDID: req.CommunityDID,
+		Weight:       weight,
+		AddedBy:      req.AddedBy,
+	}
+	if err := s.repo.AddFeaturedCommunity(ctx, featured); err != nil {
+		return nil, err
+	}
+	return featured, nil
+}
+
+// RemoveFeaturedCommunity stops featuring a community on the front page
+func (s *discoverService) RemoveFeaturedCommunity(ctx context.Context, communityDID string) error {
+	if communityDID == "" {
+		return NewValidationError("community", "community is required")
+	}
+	return s.repo.RemoveFeaturedCommunity(ctx, communityDID)
+}
+
+// ReorderFeaturedCommunities sets the order of the featured communities
+// communityDIDs must list every featured community exactly once
+func (s *discoverService) ReorderFeaturedCommunities(ctx context.Context, communityDIDs []string) ([]*FeaturedCommunity, error) {
+	current, err := s.repo.ListFeaturedCommunities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list featured communities: %w", err)
+	}
+
+	featured := make(map[string]bool, len(current))
+	for _, fc := range current {
+		featured[fc.CommunityDID] = true
+	}
+	if len(communityDIDs) != len(current) {
+		return nil, NewValidationError("communities", "must list every featured community exactly once")
+	}
+	seen := make(map[string]bool, len(communityDIDs))
+	for _, did := range communityDIDs {
+		if !featured[did] || seen[did] {
+			return nil, NewValidationError("communities", "must list every featured community exactly once")
+		}
+		seen[did] = true
+	}
+
+	if err := s.repo.ReorderFeaturedCommunities(ctx, communityDIDs); err != nil {
+		return nil, err
+	}
+	return s.ListFeaturedCommunities(ctx)
+}
+
 // validateRequest validates the discover request parameters
 func (s *discoverService) validateRequest(req *GetDiscoverRequest) error {
 	// Validate and set defaults for sort
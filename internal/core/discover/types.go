@@ -1,19 +1,56 @@
 package discover
 
 import (
+	coreerrors "Coves/internal/core/errors"
 	"Coves/internal/core/posts"
 	"context"
 	"errors"
+	"time"
 )
 
 // Repository defines discover data access interface
 type Repository interface {
 	GetDiscover(ctx context.Context, req GetDiscoverRequest) ([]*FeedViewPost, *string, error)
+
+	// GetDiscoverCandidates returns up to req.Limit discover posts in rank order, each with the
+	// cursor that resumes the feed right after it
+	GetDiscoverCandidates(ctx context.Context, req GetDiscoverRequest) ([]*DiscoverCandidate, error)
+
+	// GetDiscussions returns posts linking to the page with req.LinkHash in public
+	// communities, highest score first
+	GetDiscussions(ctx context.Context, req GetDiscussionsRequest) ([]*FeedViewPost, *string, error)
+
+	// GetHotPosts returns the hottest posts with their hot rank, limited to the given
+	// communities (all communities when communityDIDs is empty)
+	GetHotPosts(ctx context.Context, communityDIDs []string, limit int) ([]*RankedPost, error)
+
+	// Featured communities for the logged-out front page, ordered by position
+	ListFeaturedCommunities(ctx context.Context) ([]*FeaturedCommunity, error)
+	// AddFeaturedCommunity appends a community to the end of the list, or updates its weight
+	// if already featured. Returns ErrCommunityNotFound for unknown communities.
+	AddFeaturedCommunity(ctx context.Context, featured *FeaturedCommunity) error
+	// RemoveFeaturedCommunity returns ErrFeaturedCommunityNotFound if the community isn't featured
+	RemoveFeaturedCommunity(ctx context.Context, communityDID string) error
+	// ReorderFeaturedCommunities sets positions to the order of communityDIDs, which must
+	// list every featured community exactly once
+	ReorderFeaturedCommunities(ctx context.Context, communityDIDs []string) error
 }
 
 // Service defines discover business logic interface
 type Service interface {
 	GetDiscover(ctx context.Context, req GetDiscoverRequest) (*DiscoverResponse, error)
+
+	// GetDiscussions returns the posts discussing a link, however the link was spelled
+	GetDiscussions(ctx context.Context, req GetDiscussionsRequest) (*DiscoverResponse, error)
+
+	// GetFrontPage returns the logged-out home feed: hot posts from featured communities
+	// blended with globally hot posts, or discover-hot when nothing is featured
+	GetFrontPage(ctx context.Context, req GetFrontPageRequest) (*DiscoverResponse, error)
+
+	ListFeaturedCommunities(ctx context.Context) ([]*FeaturedCommunity, error)
+	AddFeaturedCommunity(ctx context.Context, req AddFeaturedCommunityRequest) (*FeaturedCommunity, error)
+	RemoveFeaturedCommunity(ctx context.Context, communityDID string) error
+	ReorderFeaturedCommunities(ctx context.Context, communityDIDs []string) ([]*FeaturedCommunity, error)
 }
 
 // GetDiscoverRequest represents input for fetching the discover feed
@@ -22,6 +59,18 @@ type GetDiscoverRequest struct {
 	Cursor    *string `json:"cursor,omitempty"`
 	Sort      string  `json:"sort"`
 	Timeframe string  `json:"timeframe"`
+	ViewerDID string  `json:"-"` // Authenticated viewer, empty when anonymous (author_only posts are shown to their author)
+	Limit     int     `json:"limit"`
+	// IncludeNSFW includes posts with an effective adult label (posts.AdultLabels)
+	IncludeNSFW bool `json:"includeNsfw"`
+}
+
+// GetDiscussionsRequest represents input for social.coves.feed.getDiscussions
+type GetDiscussionsRequest struct {
+	Cursor    *string `json:"cursor,omitempty"`
+	URL       string  `json:"url"`
+	LinkHash  string  `json:"-"` // Set by the service from URL
+	ViewerDID string  `json:"-"` // Authenticated viewer, empty when anonymous (author_only posts are shown to their author)
 	Limit     int     `json:"limit"`
 }
 
@@ -32,6 +81,29 @@ type DiscoverResponse struct {
 	Feed   []*FeedViewPost `json:"feed"`
 }
 
+// GetFrontPageRequest represents input for social.coves.discover.getFrontPage
+// The front page is a single ranked page (no cursor)
+type GetFrontPageRequest struct {
+	Limit int `json:"limit"`
+}
+
+// FeaturedCommunity is a community an instance admin features on the logged-out front page
+// Weight multiplies the hot rank of the community's posts when blending
+type FeaturedCommunity struct {
+	CreatedAt    time.Time `json:"createdAt"`
+	CommunityDID string    `json:"community"`
+	AddedBy      string    `json:"addedBy"`
+	Weight       float64   `json:"weight"`
+	Position     int       `json:"position"`
+}
+
+// AddFeaturedCommunityRequest is the input for social.coves.admin.addFeaturedCommunity
+type AddFeaturedCommunityRequest struct {
+	Weight       *float64 `json:"weight,omitempty"` // Defaults to DefaultFeaturedWeight
+	CommunityDID string   `json:"community"`
+	AddedBy      string   `json:"-"`
+}
+
 // FeedViewPost wraps a post with additional feed context
 type FeedViewPost struct {
 	Post   *posts.PostView `json:"post"`
@@ -39,11 +111,29 @@ type FeedViewPost struct {
 	Reply  *ReplyRef       `json:"reply,omitempty"`
 }
 
+// DiscoverCandidate is a ranked discover post and the cursor that resumes the feed after it
+type DiscoverCandidate struct {
+	Post   *FeedViewPost
+	Cursor string
+}
+
 // GetPost returns the underlying PostView for viewer state enrichment
 func (f *FeedViewPost) GetPost() *posts.PostView {
 	return f.Post
 }
 
+// GetContext returns the feed reason and reply context for versioned views
+// Returns untyped nils when absent so they are omitted from JSON
+func (f *FeedViewPost) GetContext() (reason, reply interface{}) {
+	if f.Reason != nil {
+		reason = f.Reason
+	}
+	if f.Reply != nil {
+		reply = f.Reply
+	}
+	return reason, reply
+}
+
 // FeedReason is a union type for feed context
 type FeedReason struct {
 	Repost    *ReasonRepost    `json:"-"`
@@ -76,7 +166,13 @@ type PostRef struct {
 
 // Errors
 var (
-	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidCursor = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid cursor")
+
+	// ErrCommunityNotFound is returned when featuring a community that isn't indexed
+	ErrCommunityNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "community not found")
+
+	// ErrFeaturedCommunityNotFound is returned when removing a community that isn't featured
+	ErrFeaturedCommunityNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "community is not featured")
 )
 
 // ValidationError represents a validation error with field context
@@ -99,6 +195,11 @@ func NewValidationError(field, message string) error {
 
 // IsValidationError checks if an error is a validation error
 func IsValidationError(err error) bool {
-	_, ok := err.(*ValidationError)
-	return ok
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// IsNotFound checks if an error is a "not found" error
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrCommunityNotFound) || errors.Is(err, ErrFeaturedCommunityNotFound)
 }
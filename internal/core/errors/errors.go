@@ -5,6 +5,9 @@ import (
 	"fmt"
 )
 
+// Error kinds shared by every core package
+// Package sentinels are declared with Sentinel so errors.Is matches both the sentinel and its
+// kind; the API maps kinds to HTTP statuses for errors it has no specific mapping for.
 var (
 	ErrNotFound         = errors.New("resource not found")
 	ErrAlreadyExists    = errors.New("resource already exists")
@@ -65,3 +68,59 @@ func NewNotFoundError(resource string, id interface{}) error {
 		ID:       id,
 	}
 }
+
+// kindError is a package sentinel error that also matches its kind
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+func (e *kindError) Is(target error) bool {
+	return target == e.kind
+}
+
+// Sentinel creates a package sentinel error of the given kind
+// The sentinel keeps its own message and identity; errors.Is(err, kind) also matches it,
+// through any number of %w wrappings.
+func Sentinel(kind error, message string) error {
+	return &kindError{kind: kind, message: message}
+}
+
+// SentinelMessage returns the message of the Sentinel error err wraps, without the context
+// added by wrapping, or "" when err doesn't wrap one
+func SentinelMessage(err error) string {
+	var sentinel *kindError
+	if !errors.As(err, &sentinel) {
+		return ""
+	}
+	return sentinel.message
+}
+
+// IsNotFound reports whether err is, or wraps, an error of kind ErrNotFound
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsAlreadyExists reports whether err is, or wraps, an error of kind ErrAlreadyExists
+func IsAlreadyExists(err error) bool {
+	return errors.Is(err, ErrAlreadyExists)
+}
+
+// IsForbidden reports whether err is, or wraps, an error of kind ErrForbidden
+func IsForbidden(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsUnauthorized reports whether err is, or wraps, an error of kind ErrUnauthorized
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsInvalidInput reports whether err is, or wraps, an error of kind ErrInvalidInput
+func IsInvalidInput(err error) bool {
+	return errors.Is(err, ErrInvalidInput)
+}
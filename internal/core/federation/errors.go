@@ -0,0 +1,46 @@
+package federation
+
+import (
+	"errors"
+	"fmt"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+// Domain errors
+var (
+	ErrRuleNotFound      = coreerrors.Sentinel(coreerrors.ErrNotFound, "federation rule not found")
+	ErrRuleAlreadyExists = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "a federation rule for this pattern already exists")
+)
+
+// ValidationError represents a validation error with field details
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error: %s - %s", e.Field, e.Message)
+}
+
+// NewValidationError creates a new validation error
+func NewValidationError(field, message string) error {
+	return &ValidationError{
+		Field:   field,
+		Message: message,
+	}
+}
+
+// Error classification helpers for handlers to map to HTTP status codes
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrRuleNotFound)
+}
+
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrRuleAlreadyExists)
+}
+
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
@@ -0,0 +1,158 @@
+package federation
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Rule modes
+const (
+	ModeAllow = "allow"
+	ModeDeny  = "deny"
+)
+
+// Rule is an instance-level federation policy entry for remote instances
+// Pattern is either an exact domain ("example.com") or a subdomain wildcard ("*.example.com")
+type Rule struct {
+	CreatedAt time.Time `json:"createdAt" db:"created_at"`
+	Pattern   string    `json:"pattern" db:"pattern"`
+	Mode      string    `json:"mode" db:"mode"`
+	Reason    string    `json:"reason,omitempty" db:"reason"`
+	CreatedBy string    `json:"createdBy" db:"created_by"`
+	ID        int64     `json:"id" db:"id"`
+}
+
+// CreateRuleRequest is the input for adding a federation rule
+type CreateRuleRequest struct {
+	Pattern   string `json:"pattern"`
+	Mode      string `json:"mode"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedBy string `json:"-"` // Set from the authenticated admin, never the request body
+}
+
+// CommunityHost is the minimal community projection used when re-evaluating policy
+type CommunityHost struct {
+	DID               string
+	HostedByDID       string
+	ID                int64
+	FederationBlocked bool
+}
+
+// domainLabelRegex matches a single DNS label (letters, digits, hyphens; no leading/trailing hyphen)
+var domainLabelRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// NormalizePattern lowercases and trims a pattern, and validates its syntax
+func NormalizePattern(pattern string) (string, error) {
+	pattern = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(pattern)), ".")
+	if pattern == "" {
+		return "", NewValidationError("pattern", "pattern is required")
+	}
+	if len(pattern) > 253 {
+		return "", NewValidationError("pattern", "pattern must be at most 253 characters")
+	}
+
+	domain := strings.TrimPrefix(pattern, "*.")
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", NewValidationError("pattern", "pattern must be a domain (example.com) or subdomain wildcard (*.example.com)")
+	}
+	for _, label := range labels {
+		if !domainLabelRegex.MatchString(label) {
+			return "", NewValidationError("pattern", "pattern must be a domain (example.com) or subdomain wildcard (*.example.com)")
+		}
+	}
+
+	return pattern, nil
+}
+
+// MatchesPattern reports whether domain matches a normalized pattern
+// "example.com" matches only example.com; "*.example.com" matches any subdomain of
+// example.com (a.example.com, a.b.example.com) but not example.com itself
+func MatchesPattern(pattern, domain string) bool {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.HasSuffix(domain, "."+suffix)
+	}
+	return domain == pattern
+}
+
+// patternSpecificity ranks patterns so the most specific matching rule wins
+// Exact domains always beat wildcards; longer wildcard suffixes beat shorter ones
+func patternSpecificity(pattern string) int {
+	if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+		return strings.Count(suffix, ".") + 1
+	}
+	return 1000
+}
+
+// HostDomain extracts the instance domain from a community's hostedBy DID
+// Returns "" for DIDs that don't carry a domain (e.g. did:plc)
+func HostDomain(hostedByDID string) string {
+	domain, ok := strings.CutPrefix(hostedByDID, "did:web:")
+	if !ok {
+		return ""
+	}
+	// did:web encodes ports as %3A and paths with ':' - only the host is relevant
+	if idx := strings.IndexAny(domain, ":%"); idx >= 0 {
+		domain = domain[:idx]
+	}
+	return strings.ToLower(domain)
+}
+
+// Policy is an evaluated set of federation rules
+//
+// Evaluation order for a remote domain:
+//  1. The most specific matching rule decides (allow or deny)
+//  2. With no matching rule, the domain is blocked only if any allow rule exists
+//     (allow rules switch the instance into allowlist mode)
+//
+// The local instance domain is never blocked.
+type Policy struct {
+	localDomain   string
+	rules         []*Rule
+	hasAllowRules bool
+}
+
+// NewPolicy builds a policy from rules; localDomain is always permitted
+func NewPolicy(rules []*Rule, localDomain string) *Policy {
+	p := &Policy{
+		localDomain: strings.ToLower(localDomain),
+		rules:       rules,
+	}
+	for _, rule := range rules {
+		if rule.Mode == ModeAllow {
+			p.hasAllowRules = true
+		}
+	}
+	return p
+}
+
+// Evaluate reports whether communities hosted on domain are blocked, and the deciding rule (if any)
+func (p *Policy) Evaluate(domain string) (bool, *Rule) {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	if domain == "" || domain == p.localDomain {
+		return false, nil
+	}
+
+	var best *Rule
+	for _, rule := range p.rules {
+		if !MatchesPattern(rule.Pattern, domain) {
+			continue
+		}
+		if best == nil || patternSpecificity(rule.Pattern) > patternSpecificity(best.Pattern) {
+			best = rule
+		}
+	}
+
+	if best != nil {
+		return best.Mode == ModeDeny, best
+	}
+	return p.hasAllowRules, nil
+}
+
+// IsHostBlocked evaluates the policy for a community's hostedBy DID
+func (p *Policy) IsHostBlocked(hostedByDID string) bool {
+	blocked, _ := p.Evaluate(HostDomain(hostedByDID))
+	return blocked
+}
@@ -0,0 +1,157 @@
+package federation
+
+import "testing"
+
+func TestNormalizePattern(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "Example.COM", want: "example.com"},
+		{input: "  *.Example.com. ", want: "*.example.com"},
+		{input: "*.a.b.example.com", want: "*.a.b.example.com"},
+		{input: "xn--caf-dma.example", want: "xn--caf-dma.example"},
+		{input: "", wantErr: true},
+		{input: "localhost", wantErr: true},
+		{input: "*", wantErr: true},
+		{input: "*.com", wantErr: true},
+		{input: "a.*.example.com", wantErr: true},
+		{input: "**.example.com", wantErr: true},
+		{input: "-bad.example.com", wantErr: true},
+		{input: "under_score.example.com", wantErr: true},
+		{input: "https://example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := NormalizePattern(tt.input)
+			if tt.wantErr {
+				if !IsValidationError(err) {
+					t.Errorf("Expected validation error for %q, got pattern %q err %v", tt.input, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error for %q: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMatchesPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		domain  string
+		want    bool
+	}{
+		// Exact domains
+		{pattern: "example.com", domain: "example.com", want: true},
+		{pattern: "example.com", domain: "EXAMPLE.com.", want: true},
+		{pattern: "example.com", domain: "sub.example.com", want: false},
+		{pattern: "example.com", domain: "badexample.com", want: false},
+		// Subdomain wildcards
+		{pattern: "*.example.com", domain: "a.example.com", want: true},
+		{pattern: "*.example.com", domain: "a.b.example.com", want: true},
+		{pattern: "*.example.com", domain: "example.com", want: false},
+		{pattern: "*.example.com", domain: "badexample.com", want: false},
+		{pattern: "*.example.com", domain: "example.com.evil.net", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" vs "+tt.domain, func(t *testing.T) {
+			if got := MatchesPattern(tt.pattern, tt.domain); got != tt.want {
+				t.Errorf("MatchesPattern(%q, %q) = %v, want %v", tt.pattern, tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostDomain(t *testing.T) {
+	tests := map[string]string{
+		"did:web:coves.social":           "coves.social",
+		"did:web:Remote.Example":         "remote.example",
+		"did:web:localhost%3A8080":       "localhost",
+		"did:web:example.com:user:alice": "example.com",
+		"did:plc:abc123":                 "",
+		"":                               "",
+	}
+	for did, want := range tests {
+		if got := HostDomain(did); got != want {
+			t.Errorf("HostDomain(%q) = %q, want %q", did, got, want)
+		}
+	}
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	t.Run("most specific rule wins", func(t *testing.T) {
+		policy := NewPolicy([]*Rule{
+			{ID: 1, Pattern: "spam.example", Mode: ModeDeny},
+			{ID: 2, Pattern: "*.evil.example", Mode: ModeDeny},
+			{ID: 3, Pattern: "good.evil.example", Mode: ModeAllow},
+		}, "coves.social")
+
+		cases := map[string]bool{
+			"spam.example":        true,
+			"sub.spam.example":    true, // exact rule doesn't match; unmatched is blocked in allowlist mode
+			"a.evil.example":      true,
+			"evil.example":        true,  // wildcard doesn't match the apex; blocked in allowlist mode
+			"good.evil.example":   false, // exact allow beats the wildcard deny
+			"other.example":       true,  // allow rule present: allowlist mode
+			"coves.social":        false, // local instance is never blocked
+			"":                    false,
+			"unrelated.somewhere": true,
+		}
+		for domain, want := range cases {
+			if got, _ := policy.Evaluate(domain); got != want {
+				t.Errorf("Evaluate(%q) = %v, want %v", domain, got, want)
+			}
+		}
+	})
+
+	t.Run("deny-only policy allows unmatched domains", func(t *testing.T) {
+		policy := NewPolicy([]*Rule{
+			{ID: 1, Pattern: "*.evil.example", Mode: ModeDeny},
+			{ID: 2, Pattern: "spam.example", Mode: ModeDeny},
+		}, "coves.social")
+		for _, domain := range []string{"friendly.example", "evil.example", "sub.spam.example"} {
+			if blocked, rule := policy.Evaluate(domain); blocked || rule != nil {
+				t.Errorf("Expected unmatched domain %q to be allowed, got blocked=%v rule=%v", domain, blocked, rule)
+			}
+		}
+		blocked, rule := policy.Evaluate("x.evil.example")
+		if !blocked || rule == nil || rule.ID != 1 {
+			t.Errorf("Expected deny rule 1 to block, got blocked=%v rule=%v", blocked, rule)
+		}
+	})
+
+	t.Run("longer wildcard beats shorter wildcard", func(t *testing.T) {
+		policy := NewPolicy([]*Rule{
+			{ID: 1, Pattern: "*.example", Mode: ModeDeny},
+			{ID: 2, Pattern: "*.trusted.example", Mode: ModeAllow},
+		}, "coves.social")
+		if blocked, _ := policy.Evaluate("a.trusted.example"); blocked {
+			t.Error("Expected *.trusted.example allow to win over *.example deny")
+		}
+		if blocked, _ := policy.Evaluate("a.other.example"); !blocked {
+			t.Error("Expected *.example deny to apply")
+		}
+	})
+
+	t.Run("empty policy blocks nothing", func(t *testing.T) {
+		policy := NewPolicy(nil, "coves.social")
+		if policy.IsHostBlocked("did:web:anything.example") {
+			t.Error("Expected empty policy to allow everything")
+		}
+	})
+
+	t.Run("hosts without a domain are never blocked", func(t *testing.T) {
+		allowlist := NewPolicy([]*Rule{{Pattern: "friendly.example", Mode: ModeAllow}}, "coves.social")
+		if allowlist.IsHostBlocked("did:plc:abc") {
+			t.Error("Expected did:plc host to be allowed (no domain to evaluate)")
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package federation
+
+import "context"
+
+// Repository defines the interface for federation policy persistence
+type Repository interface {
+	// Rule management
+	ListRules(ctx context.Context) ([]*Rule, error)
+	CreateRule(ctx context.Context, rule *Rule) (*Rule, error)
+	DeleteRule(ctx context.Context, id int64) error
+
+	// Re-evaluation support
+	// ListCommunityHosts pages through indexed communities by ID (keyset pagination)
+	ListCommunityHosts(ctx context.Context, afterID int64, limit int) ([]*CommunityHost, error)
+	// SetFederationBlocked updates communities.federation_blocked for the given DIDs
+	SetFederationBlocked(ctx context.Context, dids []string, blocked bool) error
+}
+
+// Service defines the interface for federation policy business logic
+type Service interface {
+	// Admin operations - rule changes schedule an asynchronous re-evaluation of indexed communities
+	ListRules(ctx context.Context) ([]*Rule, error)
+	CreateRule(ctx context.Context, req CreateRuleRequest) (*Rule, error)
+	DeleteRule(ctx context.Context, id int64) error
+
+	// IsHostBlocked reports whether communities with this hostedBy DID are blocked by policy
+	// Used by the community Jetstream consumer at index time
+	IsHostBlocked(ctx context.Context, hostedByDID string) (bool, error)
+
+	// ReevaluateCommunities applies the current policy to every indexed community
+	// Returns the number of communities whose blocked state changed
+	ReevaluateCommunities(ctx context.Context) (int, error)
+
+	// Start runs the background re-evaluation worker until ctx is cancelled
+	Start(ctx context.Context)
+}
@@ -0,0 +1,213 @@
+package federation
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	// policyRefreshInterval bounds how stale the in-memory policy can be
+	// Rule changes made through this service invalidate it immediately
+	policyRefreshInterval = 1 * time.Minute
+
+	// reevaluationBatchSize is how many communities are evaluated per page
+	reevaluationBatchSize = 500
+
+	// reevaluationTimeout bounds a single re-evaluation run
+	reevaluationTimeout = 10 * time.Minute
+)
+
+type federationService struct {
+	repo        Repository
+	policy      *Policy
+	loadedAt    time.Time
+	reevaluate  chan struct{} // Coalesces re-evaluation requests (buffer of 1)
+	localDomain string
+	generation  uint64 // Bumped on every rule change so in-flight loads don't cache stale rules
+	mu          sync.RWMutex
+}
+
+// NewFederationService creates a new federation policy service
+// localDomain is this instance's domain, which is never blocked
+func NewFederationService(repo Repository, localDomain string) Service {
+	return &federationService{
+		repo:        repo,
+		localDomain: localDomain,
+		reevaluate:  make(chan struct{}, 1),
+	}
+}
+
+// ListRules returns all federation rules
+func (s *federationService) ListRules(ctx context.Context) ([]*Rule, error) {
+	return s.repo.ListRules(ctx)
+}
+
+// CreateRule validates and stores a rule, then schedules re-evaluation
+func (s *federationService) CreateRule(ctx context.Context, req CreateRuleRequest) (*Rule, error) {
+	pattern, err := NormalizePattern(req.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	if req.Mode != ModeAllow && req.Mode != ModeDeny {
+		return nil, NewValidationError("mode", "mode must be 'allow' or 'deny'")
+	}
+	if len(req.Reason) > 500 {
+		return nil, NewValidationError("reason", "reason must be at most 500 characters")
+	}
+	if req.CreatedBy == "" {
+		return nil, NewValidationError("createdBy", "creator DID is required")
+	}
+
+	rule, err := s.repo.CreateRule(ctx, &Rule{
+		Pattern:   pattern,
+		Mode:      req.Mode,
+		Reason:    req.Reason,
+		CreatedBy: req.CreatedBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Federation rule created: %s %s by %s", rule.Mode, rule.Pattern, rule.CreatedBy)
+	s.invalidate()
+	return rule, nil
+}
+
+// DeleteRule removes a rule, then schedules re-evaluation
+func (s *federationService) DeleteRule(ctx context.Context, id int64) error {
+	if id <= 0 {
+		return NewValidationError("id", "rule ID is required")
+	}
+
+	if err := s.repo.DeleteRule(ctx, id); err != nil {
+		return err
+	}
+
+	log.Printf("Federation rule deleted: %d", id)
+	s.invalidate()
+	return nil
+}
+
+// IsHostBlocked reports whether communities with this hostedBy DID are blocked by policy
+func (s *federationService) IsHostBlocked(ctx context.Context, hostedByDID string) (bool, error) {
+	policy, err := s.currentPolicy(ctx)
+	if err != nil {
+		return false, err
+	}
+	return policy.IsHostBlocked(hostedByDID), nil
+}
+
+// ReevaluateCommunities applies the current policy to every indexed community
+func (s *federationService) ReevaluateCommunities(ctx context.Context) (int, error) {
+	rules, err := s.repo.ListRules(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load federation rules: %w", err)
+	}
+	policy := NewPolicy(rules, s.localDomain)
+
+	changed := 0
+	var afterID int64
+	for {
+		hosts, err := s.repo.ListCommunityHosts(ctx, afterID, reevaluationBatchSize)
+		if err != nil {
+			return changed, fmt.Errorf("failed to list communities: %w", err)
+		}
+		if len(hosts) == 0 {
+			break
+		}
+
+		var toBlock, toUnblock []string
+		for _, host := range hosts {
+			blocked := policy.IsHostBlocked(host.HostedByDID)
+			if blocked == host.FederationBlocked {
+				continue
+			}
+			if blocked {
+				toBlock = append(toBlock, host.DID)
+			} else {
+				toUnblock = append(toUnblock, host.DID)
+			}
+		}
+
+		if len(toBlock) > 0 {
+			if err := s.repo.SetFederationBlocked(ctx, toBlock, true); err != nil {
+				return changed, fmt.Errorf("failed to block communities: %w", err)
+			}
+		}
+		if len(toUnblock) > 0 {
+			if err := s.repo.SetFederationBlocked(ctx, toUnblock, false); err != nil {
+				return changed, fmt.Errorf("failed to unblock communities: %w", err)
+			}
+		}
+		changed += len(toBlock) + len(toUnblock)
+
+		afterID = hosts[len(hosts)-1].ID
+		if len(hosts) < reevaluationBatchSize {
+			break
+		}
+	}
+
+	return changed, nil
+}
+
+// Start runs the background re-evaluation worker until ctx is cancelled
+// Rule changes made while a run is in progress trigger exactly one follow-up run
+func (s *federationService) Start(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.reevaluate:
+			runCtx, cancel := context.WithTimeout(ctx, reevaluationTimeout)
+			changed, err := s.ReevaluateCommunities(runCtx)
+			cancel()
+			if err != nil {
+				log.Printf("ERROR: Federation policy re-evaluation failed after %d changes: %v", changed, err)
+				continue
+			}
+			log.Printf("Federation policy re-evaluated: %d communities changed", changed)
+		}
+	}
+}
+
+// invalidate drops the cached policy and schedules a re-evaluation
+func (s *federationService) invalidate() {
+	s.mu.Lock()
+	s.policy = nil
+	s.generation++
+	s.mu.Unlock()
+
+	select {
+	case s.reevaluate <- struct{}{}:
+	default:
+		// A run is already pending and will see this change
+	}
+}
+
+// currentPolicy returns the cached policy, reloading it when stale
+func (s *federationService) currentPolicy(ctx context.Context) (*Policy, error) {
+	s.mu.RLock()
+	policy, loadedAt, generation := s.policy, s.loadedAt, s.generation
+	s.mu.RUnlock()
+	if policy != nil && time.Since(loadedAt) < policyRefreshInterval {
+		return policy, nil
+	}
+
+	rules, err := s.repo.ListRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load federation rules: %w", err)
+	}
+	policy = NewPolicy(rules, s.localDomain)
+
+	s.mu.Lock()
+	if s.generation == generation {
+		s.policy = policy
+		s.loadedAt = time.Now()
+	}
+	s.mu.Unlock()
+
+	return policy, nil
+}
@@ -0,0 +1,248 @@
+package federation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockRepository is an in-memory federation repository
+type mockRepository struct {
+	rules       []*Rule
+	hosts       []*CommunityHost
+	nextID      int64
+	listCalls   int
+	updateCalls int
+	mu          sync.Mutex
+}
+
+func (m *mockRepository) ListRules(ctx context.Context) ([]*Rule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listCalls++
+	return append([]*Rule(nil), m.rules...), nil
+}
+
+func (m *mockRepository) CreateRule(ctx context.Context, rule *Rule) (*Rule, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, existing := range m.rules {
+		if existing.Pattern == rule.Pattern {
+			return nil, ErrRuleAlreadyExists
+		}
+	}
+	m.nextID++
+	rule.ID = m.nextID
+	rule.CreatedAt = time.Now()
+	m.rules = append(m.rules, rule)
+	return rule, nil
+}
+
+func (m *mockRepository) DeleteRule(ctx context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, rule := range m.rules {
+		if rule.ID == id {
+			m.rules = append(m.rules[:i], m.rules[i+1:]...)
+			return nil
+		}
+	}
+	return ErrRuleNotFound
+}
+
+func (m *mockRepository) ListCommunityHosts(ctx context.Context, afterID int64, limit int) ([]*CommunityHost, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var page []*CommunityHost
+	for _, host := range m.hosts {
+		if host.ID > afterID && len(page) < limit {
+			copied := *host
+			page = append(page, &copied)
+		}
+	}
+	return page, nil
+}
+
+func (m *mockRepository) SetFederationBlocked(ctx context.Context, dids []string, blocked bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updateCalls++
+	for _, did := range dids {
+		for _, host := range m.hosts {
+			if host.DID == did {
+				host.FederationBlocked = blocked
+			}
+		}
+	}
+	return nil
+}
+
+func (m *mockRepository) blockedDIDs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var dids []string
+	for _, host := range m.hosts {
+		if host.FederationBlocked {
+			dids = append(dids, host.DID)
+		}
+	}
+	sort.Strings(dids)
+	return dids
+}
+
+func newTestHosts(hostedBy ...string) []*CommunityHost {
+	hosts := make([]*CommunityHost, len(hostedBy))
+	for i, h := range hostedBy {
+		hosts[i] = &CommunityHost{ID: int64(i + 1), DID: fmt.Sprintf("did:plc:c%d", i+1), HostedByDID: h}
+	}
+	return hosts
+}
+
+func TestFederationService_CreateRuleValidation(t *testing.T) {
+	svc := NewFederationService(&mockRepository{}, "coves.social")
+	ctx := context.Background()
+
+	if _, err := svc.CreateRule(ctx, CreateRuleRequest{Pattern: "example.com", Mode: "block", CreatedBy: "did:plc:admin"}); !IsValidationError(err) {
+		t.Errorf("Expected validation error for invalid mode, got %v", err)
+	}
+	if _, err := svc.CreateRule(ctx, CreateRuleRequest{Pattern: "not a domain", Mode: ModeDeny, CreatedBy: "did:plc:admin"}); !IsValidationError(err) {
+		t.Errorf("Expected validation error for invalid pattern, got %v", err)
+	}
+
+	rule, err := svc.CreateRule(ctx, CreateRuleRequest{Pattern: "*.Example.com", Mode: ModeDeny, CreatedBy: "did:plc:admin"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rule.Pattern != "*.example.com" {
+		t.Errorf("Expected normalized pattern, got %q", rule.Pattern)
+	}
+
+	if _, err := svc.CreateRule(ctx, CreateRuleRequest{Pattern: "*.example.com", Mode: ModeAllow, CreatedBy: "did:plc:admin"}); !IsConflict(err) {
+		t.Errorf("Expected conflict for duplicate pattern, got %v", err)
+	}
+}
+
+func TestFederationService_ReevaluateCommunities(t *testing.T) {
+	repo := &mockRepository{
+		hosts: newTestHosts(
+			"did:web:coves.social",
+			"did:web:a.evil.example",
+			"did:web:friendly.example",
+			"did:web:b.evil.example",
+			"did:plc:nodomain",
+		),
+	}
+	svc := NewFederationService(repo, "coves.social")
+	ctx := context.Background()
+
+	rule, err := svc.CreateRule(ctx, CreateRuleRequest{Pattern: "*.evil.example", Mode: ModeDeny, CreatedBy: "did:plc:admin"})
+	if err != nil {
+		t.Fatalf("Failed to create rule: %v", err)
+	}
+
+	changed, err := svc.ReevaluateCommunities(ctx)
+	if err != nil {
+		t.Fatalf("Re-evaluation failed: %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("Expected 2 communities to change, got %d", changed)
+	}
+	if got := repo.blockedDIDs(); fmt.Sprint(got) != "[did:plc:c2 did:plc:c4]" {
+		t.Errorf("Unexpected blocked communities: %v", got)
+	}
+
+	// Re-running without rule changes is a no-op
+	changed, err = svc.ReevaluateCommunities(ctx)
+	if err != nil || changed != 0 {
+		t.Errorf("Expected idempotent re-evaluation, got changed=%d err=%v", changed, err)
+	}
+
+	// Removing the rule unblocks them again
+	if err := svc.DeleteRule(ctx, rule.ID); err != nil {
+		t.Fatalf("Failed to delete rule: %v", err)
+	}
+	changed, err = svc.ReevaluateCommunities(ctx)
+	if err != nil || changed != 2 {
+		t.Errorf("Expected 2 communities to be unblocked, got changed=%d err=%v", changed, err)
+	}
+	if got := repo.blockedDIDs(); len(got) != 0 {
+		t.Errorf("Expected no blocked communities, got %v", got)
+	}
+}
+
+func TestFederationService_ReevaluatePagesThroughCommunities(t *testing.T) {
+	hostedBy := make([]string, reevaluationBatchSize*2+7)
+	for i := range hostedBy {
+		hostedBy[i] = fmt.Sprintf("did:web:c%d.evil.example", i)
+	}
+	repo := &mockRepository{
+		hosts: newTestHosts(hostedBy...),
+		rules: []*Rule{{ID: 1, Pattern: "*.evil.example", Mode: ModeDeny}},
+	}
+	svc := NewFederationService(repo, "coves.social")
+
+	changed, err := svc.ReevaluateCommunities(context.Background())
+	if err != nil {
+		t.Fatalf("Re-evaluation failed: %v", err)
+	}
+	if changed != len(hostedBy) {
+		t.Errorf("Expected all %d communities to be blocked, got %d", len(hostedBy), changed)
+	}
+	if repo.updateCalls != 3 {
+		t.Errorf("Expected one batched update per page (3), got %d", repo.updateCalls)
+	}
+}
+
+func TestFederationService_RuleChangeTriggersBackgroundReevaluation(t *testing.T) {
+	repo := &mockRepository{
+		hosts: newTestHosts("did:web:spam.example", "did:web:friendly.example"),
+	}
+	svc := NewFederationService(repo, "coves.social")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Start(ctx)
+
+	if _, err := svc.CreateRule(ctx, CreateRuleRequest{Pattern: "spam.example", Mode: ModeDeny, CreatedBy: "did:plc:admin"}); err != nil {
+		t.Fatalf("Failed to create rule: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if got := repo.blockedDIDs(); len(got) == 1 && got[0] == "did:plc:c1" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Background re-evaluation did not block the community, blocked=%v", repo.blockedDIDs())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestFederationService_IsHostBlockedCachesPolicy(t *testing.T) {
+	repo := &mockRepository{}
+	svc := NewFederationService(repo, "coves.social")
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		blocked, err := svc.IsHostBlocked(ctx, "did:web:spam.example")
+		if err != nil || blocked {
+			t.Fatalf("Expected host to be allowed, got blocked=%v err=%v", blocked, err)
+		}
+	}
+	if repo.listCalls != 1 {
+		t.Errorf("Expected policy to be loaded once, got %d loads", repo.listCalls)
+	}
+
+	// Rule changes invalidate the cached policy immediately
+	if _, err := svc.CreateRule(ctx, CreateRuleRequest{Pattern: "spam.example", Mode: ModeDeny, CreatedBy: "did:plc:admin"}); err != nil {
+		t.Fatalf("Failed to create rule: %v", err)
+	}
+	blocked, err := svc.IsHostBlocked(ctx, "did:web:spam.example")
+	if err != nil || !blocked {
+		t.Errorf("Expected host to be blocked after rule change, got blocked=%v err=%v", blocked, err)
+	}
+}
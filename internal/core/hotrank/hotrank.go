@@ -0,0 +1,40 @@
+// Package hotrank computes the hot score posts are ranked by in hot feeds.
+//
+// Hot feeds order by posts.hot_score, a column kept fresh two ways: the vote consumer
+// rescores a post whenever its vote counts change, and the Ranker rescores every post from
+// the last Window on a short interval, since scores decay with age even without new votes.
+// Both call Score, so the stored values always follow the same formula.
+//
+// Every stored score is aged against one clock, the epoch. The Ranker publishes a new epoch
+// together with the scores it computed against it, and votes and new posts score against the
+// current epoch, so between runs hot_score is a fixed function of each post's votes. A hot
+// cursor records the epoch its page was ranked under; once the ranker has moved on, later
+// pages rank against the cursor's epoch with the same formula in SQL instead.
+package hotrank
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Window is how long after creation posts are rescored; older posts score 0
+	Window = 7 * 24 * time.Hour
+
+	// gravity is how quickly scores decay with age
+	gravity = 1.5
+)
+
+// Score returns a post's hot score at now: (score + 1) / (ageHours + 2)^1.5
+// Uses score + 1 so new posts with 0 votes still rank above 0. Posts older than Window score
+// 0: the ranker stops refreshing them, and a frozen score would outrank newer posts.
+// Matches the SQL formula in postgres/ranking.HotRankOf.
+func Score(score int, createdAt, now time.Time) float64 {
+	age := now.Sub(createdAt)
+	if age > Window {
+		return 0
+	}
+	// Posts dated in the future (clock skew) rank as brand new
+	ageHours := math.Max(age.Hours(), 0)
+	return float64(score+1) / math.Pow(ageHours+2, gravity)
+}
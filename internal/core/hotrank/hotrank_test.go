@@ -0,0 +1,123 @@
+package hotrank
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+	"time"
+)
+
+// referenceScore is the hot formula written out independently of Score
+func referenceScore(score int, ageHours float64) float64 {
+	return (float64(score) + 1) / math.Pow(ageHours+2, 1.5)
+}
+
+func TestScore(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		score int
+		age   time.Duration
+		want  float64
+	}{
+		{name: "brand new, no votes", score: 0, age: 0, want: 1 / math.Pow(2, 1.5)},
+		{name: "two hours, 7 votes", score: 7, age: 2 * time.Hour, want: 1},
+		{name: "a day old", score: 24, age: 24 * time.Hour, want: referenceScore(24, 24)},
+		{name: "downvoted", score: -5, age: time.Hour, want: referenceScore(-5, 1)},
+		{name: "last moment of the window", score: 100, age: Window, want: referenceScore(100, Window.Hours())},
+		{name: "aged out", score: 100, age: Window + time.Second, want: 0},
+		{name: "future dated ranks as new", score: 0, age: -time.Hour, want: 1 / math.Pow(2, 1.5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Score(tt.score, now.Add(-tt.age), now)
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// memoryStore holds post scores in memory, ordered like the Postgres store
+type memoryStore struct {
+	posts     []PostScore
+	scores    map[string]float64
+	cutoff    time.Time
+	epoch     time.Time
+	listings  int
+	publishes int
+}
+
+func (m *memoryStore) ListRecentPosts(ctx context.Context, after PostScore, limit int) ([]PostScore, error) {
+	m.listings++
+	var batch []PostScore
+	for _, post := range m.posts {
+		newer := post.CreatedAt.After(after.CreatedAt) ||
+			(post.CreatedAt.Equal(after.CreatedAt) && post.URI > after.URI)
+		if newer && len(batch) < limit {
+			batch = append(batch, post)
+		}
+	}
+	return batch, nil
+}
+
+func (m *memoryStore) PublishHotScores(ctx context.Context, scores map[string]float64, cutoff, epoch time.Time) error {
+	for uri, score := range scores {
+		m.scores[uri] = score
+	}
+	m.cutoff = cutoff
+	m.epoch = epoch
+	m.publishes++
+	return nil
+}
+
+func TestRanker_MatchesReferenceFormula(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Seeded dataset: two and a half batches of recent posts with varied scores and ages,
+	// several sharing a timestamp so paging has to break ties by uri
+	store := &memoryStore{scores: map[string]float64{}}
+	for i := 0; i < 2*batchSize+batchSize/2; i++ {
+		store.posts = append(store.posts, PostScore{
+			URI:       fmt.Sprintf("at://did:plc:c/social.coves.community.post/%05d", i),
+			Score:     (i*37)%200 - 20,
+			CreatedAt: now.Add(-time.Duration(i/3) * 7 * time.Minute),
+		})
+	}
+	sort.Slice(store.posts, func(i, j int) bool {
+		a, b := store.posts[i], store.posts[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.URI < b.URI
+	})
+
+	rescored, err := NewRanker(store).Run(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if rescored != len(store.posts) {
+		t.Fatalf("Expected %d posts rescored, got %d", len(store.posts), rescored)
+	}
+	if store.listings != 3 {
+		t.Errorf("Expected 3 batches, got %d", store.listings)
+	}
+	if store.publishes != 1 || !store.epoch.Equal(now) {
+		t.Errorf("Expected every batch published once at epoch %v, got %d publishes at %v", now, store.publishes, store.epoch)
+	}
+
+	for _, post := range store.posts {
+		ageHours := now.Sub(post.CreatedAt).Hours()
+		want := referenceScore(post.Score, ageHours)
+		if got, ok := store.scores[post.URI]; !ok || math.Abs(got-want) > 1e-12 {
+			t.Fatalf("%s (score %d, %.2fh old): expected %v, got %v", post.URI, post.Score, ageHours, want, got)
+		}
+	}
+	if !store.cutoff.Equal(now.Add(-Window)) {
+		t.Errorf("Expected posts before %v to be cleared, got %v", now.Add(-Window), store.cutoff)
+	}
+}
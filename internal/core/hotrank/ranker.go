@@ -0,0 +1,99 @@
+package hotrank
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	// DefaultInterval is how often the ranker rescores recent posts
+	DefaultInterval = 2 * time.Minute
+
+	// batchSize is how many posts the ranker reads and rescores per round trip
+	batchSize = 1000
+)
+
+// PostScore is what the ranker needs to rescore a post
+type PostScore struct {
+	CreatedAt time.Time
+	URI       string
+	Score     int
+}
+
+// Store reads and writes post hot scores
+// Implemented by postgres.NewHotRankRepository.
+type Store interface {
+	// ListRecentPosts returns up to limit live posts ordered by (created_at, uri), starting
+	// after the given post's key
+	ListRecentPosts(ctx context.Context, after PostScore, limit int) ([]PostScore, error)
+	// PublishHotScores stores hot scores by post uri, sets the hot score of posts created
+	// before cutoff to 0 and makes epoch the clock they were computed against, atomically
+	PublishHotScores(ctx context.Context, scores map[string]float64, cutoff, epoch time.Time) error
+}
+
+// Ranker recomputes the hot score of recent posts
+type Ranker struct {
+	store Store
+}
+
+// NewRanker creates a ranker writing to store
+func NewRanker(store Store) *Ranker {
+	return &Ranker{store: store}
+}
+
+// Run rescores every post created in the Window before now, zeroes posts that have aged out
+// of it and publishes now as the epoch, all at once so hot feeds never see a mix of clocks.
+// Returns the number of posts rescored.
+func (r *Ranker) Run(ctx context.Context, now time.Time) (int, error) {
+	// Postgres timestamps are microsecond precision; the epoch must round-trip exactly
+	now = now.UTC().Truncate(time.Microsecond)
+	cutoff := now.Add(-Window)
+	scores := make(map[string]float64)
+	after := PostScore{CreatedAt: cutoff}
+	for {
+		batch, err := r.store.ListRecentPosts(ctx, after, batchSize)
+		if err != nil {
+			return 0, fmt.Errorf("failed to list recent posts: %w", err)
+		}
+		for _, post := range batch {
+			scores[post.URI] = Score(post.Score, post.CreatedAt, now)
+		}
+
+		if len(batch) < batchSize {
+			break
+		}
+		after = batch[len(batch)-1]
+	}
+
+	if err := r.store.PublishHotScores(ctx, scores, cutoff, now); err != nil {
+		return 0, fmt.Errorf("failed to publish hot scores: %w", err)
+	}
+	return len(scores), nil
+}
+
+// Start runs the ranker now and then every interval until ctx is cancelled
+// A failed run is logged and retried on the next tick.
+func (r *Ranker) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	run := func() {
+		if _, err := r.Run(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			log.Printf("Hot rank job failed (will retry): %v", err)
+		}
+	}
+	run()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package imageproxy
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestIdenticon_IsDeterministic(t *testing.T) {
+	dids := []string{
+		"did:plc:z72i7hdynmk6r22z27h6tvur",
+		"did:plc:ewvi7nxzyoun6zhxrhs64oiz",
+		"did:web:coves.social",
+	}
+	for _, did := range dids {
+		first := Identicon(did)
+		for i := 0; i < 3; i++ {
+			if again := Identicon(did); !bytes.Equal(first, again) {
+				t.Fatalf("Identicon(%q) changed between calls", did)
+			}
+		}
+	}
+
+	if bytes.Equal(Identicon(dids[0]), Identicon(dids[1])) {
+		t.Error("Expected different DIDs to get different identicons")
+	}
+}
+
+func TestIdenticon_IsValidSVG(t *testing.T) {
+	for _, did := range []string{"did:plc:z72i7hdynmk6r22z27h6tvur", "did:web:example.com", ""} {
+		svg := Identicon(did)
+
+		decoder := xml.NewDecoder(bytes.NewReader(svg))
+		var root string
+		rects := 0
+		for {
+			token, err := decoder.Token()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				t.Fatalf("Identicon(%q) is not well-formed XML: %v\n%s", did, err, svg)
+			}
+			start, ok := token.(xml.StartElement)
+			if !ok {
+				continue
+			}
+			if root == "" {
+				root = start.Name.Local
+				if start.Name.Space != "http://www.w3.org/2000/svg" {
+					t.Errorf("Expected the SVG namespace, got %q", start.Name.Space)
+				}
+			}
+			if start.Name.Local == "rect" {
+				rects++
+			}
+		}
+		if root != "svg" {
+			t.Errorf("Expected an <svg> root element, got %q", root)
+		}
+		// The background plus at least one filled cell
+		if rects < 2 {
+			t.Errorf("Expected a background and at least one cell, got %d rects", rects)
+		}
+	}
+}
+
+func TestImageProxyService_GetIdenticon_UsesCache(t *testing.T) {
+	const did = "did:plc:z72i7hdynmk6r22z27h6tvur"
+	cache := NewMockCache()
+	service := mustNewService(t, cache, NewMockProcessor(nil, nil), NewMockFetcher(nil, nil), DefaultConfig())
+
+	data, err := service.GetIdenticon(did)
+	if err != nil {
+		t.Fatalf("GetIdenticon failed: %v", err)
+	}
+	if !bytes.Equal(data, Identicon(did)) {
+		t.Error("Expected the rendered identicon")
+	}
+
+	// Wait a bit for async cache write
+	time.Sleep(50 * time.Millisecond)
+	if _, found := cache.GetSetData(IdenticonCachePreset, did, IdenticonVersion); !found {
+		t.Fatal("Expected the identicon to be cached")
+	}
+
+	cache.SetCacheData(IdenticonCachePreset, did, IdenticonVersion, []byte("cached"))
+	data, err = service.GetIdenticon(did)
+	if err != nil {
+		t.Fatalf("GetIdenticon failed: %v", err)
+	}
+	if string(data) != "cached" {
+		t.Errorf("Expected the cached identicon, got %q", data)
+	}
+
+	if _, err := service.GetIdenticon("../etc/passwd"); !errors.Is(err, ErrInvalidDID) {
+		t.Errorf("Expected ErrInvalidDID, got %v", err)
+	}
+}
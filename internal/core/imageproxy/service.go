@@ -34,6 +34,10 @@ type Service interface {
 	// It checks the cache first, then fetches from the PDS if not cached,
 	// processes the image according to the preset, and stores in cache.
 	GetImage(ctx context.Context, preset, did, cid string, pdsURL string) ([]byte, error)
+
+	// GetIdenticon returns the generated fallback avatar for a DID, from the cache when
+	// it has already been rendered.
+	GetIdenticon(did string) ([]byte, error)
 }
 
 // ImageProxyService implements the Service interface and orchestrates
@@ -114,13 +118,44 @@ func (s *ImageProxyService) GetImage(ctx context.Context, presetName, did, cid s
 	}
 
 	// Step 5: Store in cache (async, don't block response)
+	s.cacheAsync(presetName, did, cid, processedData)
+
+	// Step 6: Return processed image
+	return processedData, nil
+}
+
+// GetIdenticon returns the identicon for a DID, rendering and caching it on a miss.
+// Identicons share the image cache with proxied blobs, keyed by IdenticonVersion in place
+// of a CID so a new rendering never serves stale entries.
+func (s *ImageProxyService) GetIdenticon(did string) ([]byte, error) {
+	if err := ValidateDID(did); err != nil {
+		return nil, err
+	}
+
+	cachedData, found, err := s.cache.Get(IdenticonCachePreset, did, IdenticonVersion)
+	if err != nil {
+		slog.Warn("[IMAGE-PROXY] cache read error, rendering identicon",
+			"did", did,
+			"error", err,
+		)
+	}
+	if found {
+		return cachedData, nil
+	}
+
+	data := Identicon(did)
+	s.cacheAsync(IdenticonCachePreset, did, IdenticonVersion, data)
+	return data, nil
+}
+
+// cacheAsync stores data in the cache without blocking the response
+func (s *ImageProxyService) cacheAsync(preset, did, cid string, data []byte) {
 	go func() {
-		// Use a background context since the original request context may be cancelled
-		if cacheErr := s.cache.Set(presetName, did, cid, processedData); cacheErr != nil {
+		if cacheErr := s.cache.Set(preset, did, cid, data); cacheErr != nil {
 			// Increment error counter for monitoring
 			cacheWriteErrors.Add(1)
 			slog.Error("[IMAGE-PROXY] async cache write failed",
-				"preset", presetName,
+				"preset", preset,
 				"did", did,
 				"cid", cid,
 				"error", cacheErr,
@@ -128,14 +163,11 @@ func (s *ImageProxyService) GetImage(ctx context.Context, presetName, did, cid s
 			)
 		} else {
 			slog.Debug("[IMAGE-PROXY] cached processed image",
-				"preset", presetName,
+				"preset", preset,
 				"did", did,
 				"cid", cid,
-				"size_bytes", len(processedData),
+				"size_bytes", len(data),
 			)
 		}
 	}()
-
-	// Step 6: Return processed image
-	return processedData, nil
 }
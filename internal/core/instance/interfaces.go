@@ -0,0 +1,46 @@
+package instance
+
+import (
+	"context"
+	"time"
+)
+
+// Counts are the aggregate queries behind the instance statistics
+type Counts struct {
+	Communities       int // Live communities hosted by this instance
+	RemoteCommunities int // Live communities hosted elsewhere and indexed here
+	Users             int // Every indexed user
+	LocalPosts        int // Live posts in communities hosted here
+	LocalComments     int // Live comments on posts in communities hosted here
+	Posts             Window
+	Comments          Window
+	ActiveUsers       ActiveUsers
+}
+
+// Window counts live posts or comments created in the last day and week, across all
+// indexed communities
+type Window struct {
+	Day  int `json:"day"`
+	Week int `json:"week"`
+}
+
+// ActiveUsers counts distinct authors of live posts and comments over recent windows
+type ActiveUsers struct {
+	Day      int `json:"day"`
+	Week     int `json:"week"`
+	Month    int `json:"month"`    // Last 30 days
+	HalfYear int `json:"halfYear"` // Last 180 days
+}
+
+// Repository runs the aggregate queries
+type Repository interface {
+	// GetCounts computes every count relative to now; instanceDID identifies communities
+	// hosted here
+	GetCounts(ctx context.Context, instanceDID string, now time.Time) (*Counts, error)
+}
+
+// Service serves the public instance statistics
+type Service interface {
+	// GetStats returns the instance statistics, recomputed at most once per StatsCacheTTL
+	GetStats(ctx context.Context) (*Stats, error)
+}
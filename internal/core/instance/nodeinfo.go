@@ -0,0 +1,94 @@
+package instance
+
+// NodeInfo schema 2.1, served at /nodeinfo/2.1 and linked from /.well-known/nodeinfo so
+// fediverse crawlers can list the instance
+// Spec: https://github.com/jhass/nodeinfo/blob/main/PROTOCOL.md
+const (
+	NodeInfoSchema      = "http://nodeinfo.diaspora.software/ns/schema/2.1"
+	NodeInfoPath        = "/nodeinfo/2.1"
+	NodeInfoContentType = `application/json; profile="http://nodeinfo.diaspora.software/ns/schema/2.1#"`
+)
+
+// NodeInfoLinks is the /.well-known/nodeinfo discovery document
+type NodeInfoLinks struct {
+	Links []NodeInfoLink `json:"links"`
+}
+
+// NodeInfoLink points a crawler at one NodeInfo document
+type NodeInfoLink struct {
+	Rel  string `json:"rel"`
+	Href string `json:"href"`
+}
+
+// NodeInfo is a NodeInfo 2.1 document
+type NodeInfo struct {
+	Metadata          map[string]interface{} `json:"metadata"`
+	Version           string                 `json:"version"`
+	Software          NodeInfoSoftware       `json:"software"`
+	Protocols         []string               `json:"protocols"`
+	Services          NodeInfoServices       `json:"services"`
+	Usage             NodeInfoUsage          `json:"usage"`
+	OpenRegistrations bool                   `json:"openRegistrations"`
+}
+
+// NodeInfoSoftware describes the server software
+type NodeInfoSoftware struct {
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Homepage string `json:"homepage,omitempty"`
+}
+
+// NodeInfoServices lists third-party services the instance talks to (none)
+type NodeInfoServices struct {
+	Inbound  []string `json:"inbound"`
+	Outbound []string `json:"outbound"`
+}
+
+// NodeInfoUsage holds the usage statistics
+type NodeInfoUsage struct {
+	Users         NodeInfoUsers `json:"users"`
+	LocalPosts    int           `json:"localPosts"`
+	LocalComments int           `json:"localComments"`
+}
+
+// NodeInfoUsers counts users; active means authored a post or comment in the window
+type NodeInfoUsers struct {
+	Total          int `json:"total"`
+	ActiveMonth    int `json:"activeMonth"`
+	ActiveHalfyear int `json:"activeHalfyear"`
+}
+
+// NewNodeInfoLinks returns the discovery document for an instance served at baseURL
+func NewNodeInfoLinks(baseURL string) *NodeInfoLinks {
+	return &NodeInfoLinks{Links: []NodeInfoLink{{Rel: NodeInfoSchema, Href: baseURL + NodeInfoPath}}}
+}
+
+// NewNodeInfo builds the NodeInfo document from the instance statistics
+// "atprotocol" isn't in the schema's protocol list, but is what other AT Protocol servers
+// publishing NodeInfo report. Accounts live on users' own PDSes, so registrations are closed.
+func NewNodeInfo(stats *Stats, nodeName, homepage string) *NodeInfo {
+	return &NodeInfo{
+		Version: "2.1",
+		Software: NodeInfoSoftware{
+			Name:     stats.Software.Name,
+			Version:  stats.Software.Version,
+			Homepage: homepage,
+		},
+		Protocols: []string{"atprotocol"},
+		Services:  NodeInfoServices{Inbound: []string{}, Outbound: []string{}},
+		Usage: NodeInfoUsage{
+			Users: NodeInfoUsers{
+				Total:          stats.Users,
+				ActiveMonth:    stats.ActiveUsers.Month,
+				ActiveHalfyear: stats.ActiveUsers.HalfYear,
+			},
+			LocalPosts:    stats.LocalPosts,
+			LocalComments: stats.LocalComments,
+		},
+		Metadata: map[string]interface{}{
+			"nodeName":    nodeName,
+			"communities": stats.Communities,
+			"federation":  stats.Federation,
+		},
+	}
+}
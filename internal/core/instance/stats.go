@@ -0,0 +1,158 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// StatsCacheTTL is how long computed counts are served before the queries run again
+// Public and unauthenticated, the endpoint must not let callers drive the aggregate queries.
+const StatsCacheTTL = 5 * time.Minute
+
+// SoftwareName is the software name reported in stats and NodeInfo
+const SoftwareName = "coves"
+
+// Version is the release version, set at build time with
+// -ldflags "-X Coves/internal/core/instance.Version=v1.2.3"
+var Version = "dev"
+
+// Stats is the social.coves.instance.getStats output
+type Stats struct {
+	GeneratedAt time.Time   `json:"generatedAt"` // When the counts were computed
+	StartedAt   time.Time   `json:"startedAt"`
+	Software    Software    `json:"software"`
+	Federation  Federation  `json:"federation"`
+	Communities int         `json:"communities"`
+	Users       int         `json:"users"`
+	Posts       Window      `json:"posts"`
+	Comments    Window      `json:"comments"`
+	ActiveUsers ActiveUsers `json:"activeUsers"`
+	Uptime      int64       `json:"uptimeSeconds"`
+
+	// Totals for NodeInfo's usage section
+	LocalPosts    int `json:"-"`
+	LocalComments int `json:"-"`
+}
+
+// Software identifies the running server
+type Software struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Federation summarizes this instance's view of the network
+type Federation struct {
+	RemoteCommunities int `json:"remoteCommunities"` // Communities hosted elsewhere and indexed here
+	Peers             int `json:"peers"`             // Directory sync peers configured
+}
+
+// Config describes the running instance
+type Config struct {
+	StartedAt   time.Time
+	InstanceDID string
+	Peers       int
+}
+
+type statsService struct {
+	repo   Repository
+	now    func() time.Time
+	cached *statsEntry
+	loads  singleflight.Group
+	config Config
+	mu     sync.RWMutex
+}
+
+// statsEntry is one computation of the counts
+type statsEntry struct {
+	counts      *Counts
+	generatedAt time.Time
+}
+
+// NewStatsService creates a stats service that caches the counts for StatsCacheTTL
+func NewStatsService(repo Repository, config Config) Service {
+	if config.StartedAt.IsZero() {
+		config.StartedAt = time.Now()
+	}
+	return &statsService{repo: repo, config: config, now: time.Now}
+}
+
+// GetStats returns the cached counts with the current uptime
+func (s *statsService) GetStats(ctx context.Context) (*Stats, error) {
+	entry, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	counts := entry.counts
+	return &Stats{
+		GeneratedAt:   entry.generatedAt.UTC(),
+		StartedAt:     s.config.StartedAt.UTC(),
+		Uptime:        int64(s.now().Sub(s.config.StartedAt).Seconds()),
+		Software:      Software{Name: SoftwareName, Version: SoftwareVersion()},
+		Communities:   counts.Communities,
+		Users:         counts.Users,
+		Posts:         counts.Posts,
+		Comments:      counts.Comments,
+		ActiveUsers:   counts.ActiveUsers,
+		LocalPosts:    counts.LocalPosts,
+		LocalComments: counts.LocalComments,
+		Federation: Federation{
+			RemoteCommunities: counts.RemoteCommunities,
+			Peers:             s.config.Peers,
+		},
+	}, nil
+}
+
+// load returns the cached counts, querying on a miss or expiry
+// Concurrent misses share one run of the queries.
+func (s *statsService) load(ctx context.Context) (*statsEntry, error) {
+	s.mu.RLock()
+	entry := s.cached
+	s.mu.RUnlock()
+	if entry != nil && s.now().Before(entry.generatedAt.Add(StatsCacheTTL)) {
+		return entry, nil
+	}
+
+	result, err, _ := s.loads.Do("stats", func() (interface{}, error) {
+		now := s.now()
+		counts, err := s.repo.GetCounts(ctx, s.config.InstanceDID, now)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compute instance stats: %w", err)
+		}
+		entry := &statsEntry{counts: counts, generatedAt: now}
+		s.mu.Lock()
+		s.cached = entry
+		s.mu.Unlock()
+		return entry, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*statsEntry), nil
+}
+
+// SoftwareVersion returns Version, or for development builds "dev+" and the VCS revision
+// the binary was built from when Go recorded one
+func SoftwareVersion() string {
+	if Version != "dev" {
+		return Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && setting.Value != "" {
+			revision := setting.Value
+			if len(revision) > 12 {
+				revision = revision[:12]
+			}
+			return Version + "+" + revision
+		}
+	}
+	return Version
+}
@@ -0,0 +1,124 @@
+package instance
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct {
+	err   error
+	calls int
+	mu    sync.Mutex
+}
+
+func (r *fakeRepo) GetCounts(_ context.Context, instanceDID string, _ time.Time) (*Counts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &Counts{
+		Communities:       3,
+		RemoteCommunities: 7,
+		Users:             100 + r.calls,
+		LocalPosts:        40,
+		LocalComments:     90,
+		Posts:             Window{Day: 2, Week: 10},
+		Comments:          Window{Day: 5, Week: 30},
+		ActiveUsers:       ActiveUsers{Day: 4, Week: 12, Month: 20, HalfYear: 50},
+	}, nil
+}
+
+func newTestService(repo Repository, now *time.Time) *statsService {
+	started := now.Add(-time.Hour)
+	svc := NewStatsService(repo, Config{StartedAt: started, InstanceDID: "did:web:coves.test", Peers: 2}).(*statsService)
+	svc.now = func() time.Time { return *now }
+	return svc
+}
+
+func TestGetStats(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := newTestService(&fakeRepo{}, &now)
+
+	stats, err := svc.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.Communities)
+	assert.Equal(t, 101, stats.Users)
+	assert.Equal(t, Window{Day: 2, Week: 10}, stats.Posts)
+	assert.Equal(t, Window{Day: 5, Week: 30}, stats.Comments)
+	assert.Equal(t, ActiveUsers{Day: 4, Week: 12, Month: 20, HalfYear: 50}, stats.ActiveUsers)
+	assert.Equal(t, Federation{RemoteCommunities: 7, Peers: 2}, stats.Federation)
+	assert.Equal(t, int64(3600), stats.Uptime)
+	assert.Equal(t, now, stats.GeneratedAt)
+	assert.Equal(t, SoftwareName, stats.Software.Name)
+	assert.NotEmpty(t, stats.Software.Version)
+}
+
+func TestGetStats_CachesCounts(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeRepo{}
+	svc := newTestService(repo, &now)
+	generated := now
+
+	_, err := svc.GetStats(context.Background())
+	require.NoError(t, err)
+
+	now = now.Add(StatsCacheTTL - time.Second)
+	stats, err := svc.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.calls, "counts within the TTL come from the cache")
+	assert.Equal(t, generated, stats.GeneratedAt)
+	assert.Equal(t, int64(3600+StatsCacheTTL.Seconds()-1), stats.Uptime, "uptime is current even from the cache")
+
+	now = now.Add(time.Second)
+	stats, err = svc.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, repo.calls, "expired counts are recomputed")
+	assert.Equal(t, 102, stats.Users)
+	assert.Equal(t, now, stats.GeneratedAt)
+}
+
+func TestGetStats_ConcurrentMissesQueryOnce(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeRepo{}
+	svc := newTestService(repo, &now)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := svc.GetStats(context.Background())
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// Whatever the interleaving, once cached the counts aren't queried again
+	calls := repo.calls
+	_, err := svc.GetStats(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, calls, repo.calls)
+}
+
+func TestGetStats_Error(t *testing.T) {
+	now := time.Now()
+	repo := &fakeRepo{err: errors.New("db down")}
+	svc := newTestService(repo, &now)
+
+	_, err := svc.GetStats(context.Background())
+	assert.Error(t, err)
+
+	// Failures aren't cached
+	repo.err = nil
+	_, err = svc.GetStats(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, repo.calls)
+}
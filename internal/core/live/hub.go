@@ -0,0 +1,194 @@
+package live
+
+import (
+	"strings"
+	"sync"
+)
+
+// Event types
+const (
+	EventPost    = "post"    // A new post in a community
+	EventComment = "comment" // A new comment in a post's thread
+)
+
+// Topic prefixes and names clients subscribe with
+const (
+	postTopicPrefix      = "post:"
+	communityTopicPrefix = "community:"
+
+	// TimelineTopic is the authenticated user's home feed: new posts in their subscribed communities
+	TimelineTopic = "timeline"
+)
+
+// DefaultBufferSize is how many undelivered events a subscription holds before it's dropped
+const DefaultBufferSize = 64
+
+// Event is a lightweight notice that something was indexed
+// Clients fetch the content itself through the regular read endpoints, which apply
+// visibility and moderation rules; events only carry identifiers.
+type Event struct {
+	Type      string `json:"type"`
+	URI       string `json:"uri"`
+	ParentURI string `json:"parentUri,omitempty"`
+	Topic     string `json:"topic"` // The topic the client subscribed to that matched
+}
+
+// PostTopic is the topic new comments anywhere in a post's thread are published to
+func PostTopic(postURI string) string {
+	return postTopicPrefix + postURI
+}
+
+// CommunityTopic is the topic new posts in a community are published to
+func CommunityTopic(communityDID string) string {
+	return communityTopicPrefix + communityDID
+}
+
+// TopicSubject returns the post URI or community DID a topic follows
+// Both are empty for the timeline and for topics that aren't valid.
+func TopicSubject(topic string) (postURI, communityDID string) {
+	if !ValidTopic(topic) {
+		return "", ""
+	}
+	if uri, ok := strings.CutPrefix(topic, postTopicPrefix); ok {
+		return uri, ""
+	}
+	if did, ok := strings.CutPrefix(topic, communityTopicPrefix); ok {
+		return "", did
+	}
+	return "", ""
+}
+
+// ValidTopic reports whether a client-supplied topic is one the hub publishes to
+func ValidTopic(topic string) bool {
+	switch {
+	case topic == TimelineTopic:
+		return true
+	case strings.HasPrefix(topic, postTopicPrefix):
+		return strings.HasPrefix(strings.TrimPrefix(topic, postTopicPrefix), "at://")
+	case strings.HasPrefix(topic, communityTopicPrefix):
+		return strings.HasPrefix(strings.TrimPrefix(topic, communityTopicPrefix), "did:")
+	default:
+		return false
+	}
+}
+
+// Publisher is what the Jetstream consumers publish into after indexing succeeds
+type Publisher interface {
+	Publish(topic string, event Event)
+}
+
+// Hub is an in-process pub/sub hub fanning indexed events out to connected clients
+// Publishing never blocks: a subscriber whose buffer is full is dropped rather than
+// holding up the consumer.
+type Hub struct {
+	topics     map[string]map[*Subscription]string // Published topic -> subscriber -> client topic
+	bufferSize int
+	mu         sync.RWMutex
+}
+
+// NewHub creates a hub whose subscriptions buffer up to bufferSize events
+func NewHub(bufferSize int) *Hub {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Hub{
+		topics:     make(map[string]map[*Subscription]string),
+		bufferSize: bufferSize,
+	}
+}
+
+// Subscription receives the events published to its topics
+type Subscription struct {
+	hub       *Hub
+	events    chan Event
+	dropped   chan struct{}
+	topics    map[string]string // Published topic -> client topic
+	closeOnce sync.Once
+}
+
+// Subscribe registers a subscription for the given topics
+// topics maps each published topic to the client topic reported on its events, so a
+// timeline subscription can listen to many communities while events say "timeline".
+func (h *Hub) Subscribe(topics map[string]string) *Subscription {
+	sub := &Subscription{
+		hub:     h,
+		events:  make(chan Event, h.bufferSize),
+		dropped: make(chan struct{}),
+		topics:  topics,
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for topic, clientTopic := range topics {
+		subs, ok := h.topics[topic]
+		if !ok {
+			subs = make(map[*Subscription]string)
+			h.topics[topic] = subs
+		}
+		subs[sub] = clientTopic
+	}
+	return sub
+}
+
+// Publish delivers event to every subscriber of topic without blocking
+func (h *Hub) Publish(topic string, event Event) {
+	var slow []*Subscription
+
+	h.mu.RLock()
+	for sub, clientTopic := range h.topics[topic] {
+		e := event
+		e.Topic = clientTopic
+		select {
+		case sub.events <- e:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		sub.drop()
+	}
+}
+
+// Subscribers returns the number of subscriptions listening to topic
+func (h *Hub) Subscribers(topic string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.topics[topic])
+}
+
+// Events returns the channel events are delivered on
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Dropped is closed when the subscription fell behind and was removed from the hub
+func (s *Subscription) Dropped() <-chan struct{} {
+	return s.dropped
+}
+
+// Close removes the subscription from the hub
+func (s *Subscription) Close() {
+	s.closeOnce.Do(s.unregister)
+}
+
+// drop removes a subscriber that couldn't keep up and signals it through Dropped
+func (s *Subscription) drop() {
+	s.closeOnce.Do(func() {
+		s.unregister()
+		close(s.dropped)
+	})
+}
+
+func (s *Subscription) unregister() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	for topic := range s.topics {
+		subs := s.hub.topics[topic]
+		delete(subs, s)
+		if len(subs) == 0 {
+			delete(s.hub.topics, topic)
+		}
+	}
+}
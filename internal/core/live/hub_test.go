@@ -0,0 +1,115 @@
+package live
+
+import (
+	"testing"
+)
+
+func TestHub_DeliversOnlySubscribedTopics(t *testing.T) {
+	hub := NewHub(4)
+	postTopic := PostTopic("at://did:plc:c/social.coves.community.post/1")
+	sub := hub.Subscribe(map[string]string{postTopic: postTopic})
+	defer sub.Close()
+
+	hub.Publish(PostTopic("at://did:plc:c/social.coves.community.post/2"), Event{Type: EventComment, URI: "at://other"})
+	hub.Publish(postTopic, Event{Type: EventComment, URI: "at://mine", ParentURI: "at://parent"})
+
+	select {
+	case event := <-sub.Events():
+		if event.URI != "at://mine" || event.Topic != postTopic || event.ParentURI != "at://parent" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	default:
+		t.Fatal("Expected an event for the subscribed topic")
+	}
+	select {
+	case event := <-sub.Events():
+		t.Errorf("Unsubscribed topic leaked: %+v", event)
+	default:
+	}
+}
+
+func TestHub_ReportsClientTopic(t *testing.T) {
+	hub := NewHub(4)
+	sub := hub.Subscribe(map[string]string{CommunityTopic("did:plc:c"): TimelineTopic})
+	defer sub.Close()
+
+	hub.Publish(CommunityTopic("did:plc:c"), Event{Type: EventPost, URI: "at://post"})
+	if event := <-sub.Events(); event.Topic != TimelineTopic {
+		t.Errorf("Expected the event tagged with the timeline topic, got %q", event.Topic)
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	hub := NewHub(2)
+	topic := CommunityTopic("did:plc:c")
+	slow := hub.Subscribe(map[string]string{topic: topic})
+	fast := hub.Subscribe(map[string]string{topic: topic})
+	defer fast.Close()
+
+	for i := 0; i < 2; i++ {
+		hub.Publish(topic, Event{Type: EventPost})
+		<-fast.Events()
+	}
+	hub.Publish(topic, Event{Type: EventPost}) // slow's buffer is already full
+
+	select {
+	case <-slow.Dropped():
+	default:
+		t.Fatal("Expected the slow subscriber to be dropped")
+	}
+	if n := hub.Subscribers(topic); n != 1 {
+		t.Errorf("Expected only the fast subscriber left, got %d", n)
+	}
+	if len(fast.Events()) != 1 {
+		t.Error("Expected the fast subscriber to still receive events")
+	}
+	slow.Close() // Closing after a drop is harmless
+}
+
+func TestHub_CloseUnregisters(t *testing.T) {
+	hub := NewHub(1)
+	topic := CommunityTopic("did:plc:c")
+	sub := hub.Subscribe(map[string]string{topic: topic})
+	sub.Close()
+	sub.Close()
+
+	if n := hub.Subscribers(topic); n != 0 {
+		t.Errorf("Expected no subscribers after Close, got %d", n)
+	}
+	hub.Publish(topic, Event{Type: EventPost}) // Must not block or panic
+}
+
+func TestValidTopic(t *testing.T) {
+	tests := map[string]bool{
+		"timeline":                      true,
+		"post:at://did:plc:c/x/1":       true,
+		"community:did:plc:c":           true,
+		"post:did:plc:c":                false,
+		"community:c-news.coves.social": false,
+		"timeline:did:plc:someone":      false,
+		"firehose":                      false,
+		"":                              false,
+	}
+	for topic, want := range tests {
+		if got := ValidTopic(topic); got != want {
+			t.Errorf("ValidTopic(%q) = %v, want %v", topic, got, want)
+		}
+	}
+}
+
+func TestTopicSubject(t *testing.T) {
+	tests := []struct {
+		topic, wantPost, wantCommunity string
+	}{
+		{topic: "post:at://did:plc:c/x/1", wantPost: "at://did:plc:c/x/1"},
+		{topic: "community:did:plc:c", wantCommunity: "did:plc:c"},
+		{topic: "timeline"},
+		{topic: "post:did:plc:c"},
+	}
+	for _, tt := range tests {
+		post, community := TopicSubject(tt.topic)
+		if post != tt.wantPost || community != tt.wantCommunity {
+			t.Errorf("TopicSubject(%q) = (%q, %q), want (%q, %q)", tt.topic, post, community, tt.wantPost, tt.wantCommunity)
+		}
+	}
+}
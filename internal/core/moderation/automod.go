@@ -0,0 +1,146 @@
+package moderation
+
+import (
+	"Coves/internal/core/automod"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// errAutomodDisabled is returned when automod isn't configured
+var errAutomodDisabled = errors.New("automod is not configured")
+
+// AutomodConfig is a community's automod rules
+type AutomodConfig struct {
+	CommunityDID string         `json:"community"`
+	Rules        []automod.Rule `json:"rules"`
+}
+
+// GetAutomodRequest asks for a community's automod rules
+type GetAutomodRequest struct {
+	CommunityDID string
+	ActorDID     string
+	IsAdmin      bool
+}
+
+// UpdateAutomodRequest replaces a community's automod rules
+type UpdateAutomodRequest struct {
+	CommunityDID string         `json:"community"`
+	Rules        []automod.Rule `json:"rules"`
+	ActorDID     string         `json:"-"`
+	IsAdmin      bool           `json:"-"`
+}
+
+// ListAutomodQueueRequest asks for a community's held and flagged content awaiting review
+type ListAutomodQueueRequest struct {
+	CommunityDID string
+	ActorDID     string
+	IsAdmin      bool
+	Limit        int
+	Offset       int
+}
+
+// ReviewAutomodActionRequest approves or removes held or flagged content
+type ReviewAutomodActionRequest struct {
+	ID       int64                `json:"id"`
+	Status   automod.ReviewStatus `json:"status"`
+	ActorDID string               `json:"-"`
+	IsAdmin  bool                 `json:"-"`
+}
+
+// SetAutomod enables automod rule editing and its moderation queue
+func (s *moderationService) SetAutomod(service automod.Service, queue automod.QueueRepository) {
+	s.automod = service
+	s.automodQueue = queue
+}
+
+// GetAutomod returns a community's automod rules to its moderators and instance admins
+func (s *moderationService) GetAutomod(ctx context.Context, req GetAutomodRequest) (*AutomodConfig, error) {
+	if err := s.authorizeAutomod(ctx, req.CommunityDID, req.ActorDID, req.IsAdmin); err != nil {
+		return nil, err
+	}
+
+	rules, err := s.automod.GetRules(ctx, req.CommunityDID)
+	if err != nil {
+		return nil, err
+	}
+	return &AutomodConfig{CommunityDID: req.CommunityDID, Rules: rules}, nil
+}
+
+// UpdateAutomod replaces a community's automod rules for its moderators and instance admins
+func (s *moderationService) UpdateAutomod(ctx context.Context, req UpdateAutomodRequest) (*AutomodConfig, error) {
+	if err := s.authorizeAutomod(ctx, req.CommunityDID, req.ActorDID, req.IsAdmin); err != nil {
+		return nil, err
+	}
+	if req.Rules == nil {
+		req.Rules = []automod.Rule{}
+	}
+
+	if err := s.automod.UpdateRules(ctx, req.CommunityDID, req.Rules, req.ActorDID); err != nil {
+		return nil, err
+	}
+
+	log.Printf("%s updated automod rules of %s (%d rules)", req.ActorDID, req.CommunityDID, len(req.Rules))
+	return &AutomodConfig{CommunityDID: req.CommunityDID, Rules: req.Rules}, nil
+}
+
+// ListAutomodQueue returns a community's held and flagged content to its moderators and instance admins
+func (s *moderationService) ListAutomodQueue(ctx context.Context, req ListAutomodQueueRequest) ([]*automod.ActionRecord, error) {
+	if err := s.authorizeAutomod(ctx, req.CommunityDID, req.ActorDID, req.IsAdmin); err != nil {
+		return nil, err
+	}
+	if s.automodQueue == nil {
+		return []*automod.ActionRecord{}, nil
+	}
+
+	records, err := s.automodQueue.ListOpenActions(ctx, req.CommunityDID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list automod queue for %s: %w", req.CommunityDID, err)
+	}
+	return records, nil
+}
+
+// ReviewAutomodAction approves or removes queued content for its community's moderators and
+// instance admins
+func (s *moderationService) ReviewAutomodAction(ctx context.Context, req ReviewAutomodActionRequest) error {
+	if !req.Status.IsReview() {
+		return NewValidationError("status", "status must be 'approved' or 'removed'")
+	}
+	if req.ActorDID == "" {
+		return NewValidationError("actor", "actor DID is required")
+	}
+	if s.automodQueue == nil {
+		return automod.ErrActionNotFound
+	}
+
+	record, err := s.automodQueue.GetAction(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeCommunityModerator(ctx, record.CommunityDID, req.ActorDID, req.IsAdmin); err != nil {
+		return err
+	}
+	if err := s.automodQueue.ReviewAction(ctx, req.ID, req.Status, req.ActorDID); err != nil {
+		return err
+	}
+	s.refreshBlobs(ctx, record.SubjectURI)
+
+	log.Printf("%s reviewed automod %s of %s: %s", req.ActorDID, record.Action, record.SubjectURI, req.Status)
+	return nil
+}
+
+// authorizeAutomod validates the community and actor and checks the actor may moderate it
+func (s *moderationService) authorizeAutomod(ctx context.Context, communityDID, actorDID string, isAdmin bool) error {
+	if !strings.HasPrefix(communityDID, "did:") {
+		return NewValidationError("community", "community must be a DID")
+	}
+	if actorDID == "" {
+		return NewValidationError("actor", "actor DID is required")
+	}
+	if s.automod == nil {
+		return errAutomodDisabled
+	}
+	return s.authorizeCommunityModerator(ctx, communityDID, actorDID, isAdmin)
+}
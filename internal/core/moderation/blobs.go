@@ -0,0 +1,25 @@
+package moderation
+
+import (
+	"Coves/internal/core/blobrefs"
+	"context"
+	"log"
+)
+
+// SetBlobReferences enables blocking the blobs of taken-down and removed content in the image proxy
+func (s *moderationService) SetBlobReferences(repo blobrefs.Repository) {
+	s.blobRefs = repo
+}
+
+// refreshBlobs re-evaluates whether the subject's blobs may still be served after its
+// visibility changed
+// The subject is already hidden or shown either way, so a failure is only logged; the blobs'
+// state catches up on the next change to any record referencing them.
+func (s *moderationService) refreshBlobs(ctx context.Context, subjectURI string) {
+	if s.blobRefs == nil {
+		return
+	}
+	if err := s.blobRefs.Refresh(ctx, subjectURI); err != nil {
+		log.Printf("Warning: failed to refresh blob block list for %s: %v", subjectURI, err)
+	}
+}
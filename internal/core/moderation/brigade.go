@@ -0,0 +1,97 @@
+package moderation
+
+import (
+	"Coves/internal/core/brigade"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// ListBrigadeAlertsRequest asks for a community's open brigade alerts
+type ListBrigadeAlertsRequest struct {
+	CommunityDID string
+	ActorDID     string // Who is asking
+	IsAdmin      bool   // Instance admins may read any community's queue
+	Limit        int
+	Offset       int
+}
+
+// ReviewBrigadeAlertRequest dismisses or confirms a brigade alert
+type ReviewBrigadeAlertRequest struct {
+	ID       int64               `json:"id"`
+	Status   brigade.AlertStatus `json:"status"`
+	ActorDID string              `json:"-"`
+	IsAdmin  bool                `json:"-"`
+}
+
+// SetBrigadeQueue enables the brigade alert moderation queue
+func (s *moderationService) SetBrigadeQueue(queue brigade.QueueRepository) {
+	s.brigadeQueue = queue
+}
+
+// ListBrigadeAlerts returns a community's open brigade alerts to its moderators and instance admins
+func (s *moderationService) ListBrigadeAlerts(ctx context.Context, req ListBrigadeAlertsRequest) ([]*brigade.Alert, error) {
+	if !strings.HasPrefix(req.CommunityDID, "did:") {
+		return nil, NewValidationError("community", "community must be a DID")
+	}
+	if req.ActorDID == "" {
+		return nil, NewValidationError("actor", "actor DID is required")
+	}
+	if err := s.authorizeCommunityModerator(ctx, req.CommunityDID, req.ActorDID, req.IsAdmin); err != nil {
+		return nil, err
+	}
+	if s.brigadeQueue == nil {
+		return []*brigade.Alert{}, nil
+	}
+
+	alerts, err := s.brigadeQueue.ListOpenAlerts(ctx, req.CommunityDID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list brigade alerts for %s: %w", req.CommunityDID, err)
+	}
+	return alerts, nil
+}
+
+// ReviewBrigadeAlert dismisses or confirms an open alert for the alerted community's moderators
+// and instance admins
+func (s *moderationService) ReviewBrigadeAlert(ctx context.Context, req ReviewBrigadeAlertRequest) error {
+	if !req.Status.IsReview() {
+		return NewValidationError("status", "status must be 'dismissed' or 'confirmed'")
+	}
+	if req.ActorDID == "" {
+		return NewValidationError("actor", "actor DID is required")
+	}
+	if s.brigadeQueue == nil {
+		return brigade.ErrAlertNotFound
+	}
+
+	alert, err := s.brigadeQueue.GetAlert(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeCommunityModerator(ctx, alert.CommunityDID, req.ActorDID, req.IsAdmin); err != nil {
+		return err
+	}
+	if err := s.brigadeQueue.ReviewAlert(ctx, req.ID, req.Status, req.ActorDID); err != nil {
+		return err
+	}
+
+	log.Printf("%s reviewed brigade alert %d on %s: %s", req.ActorDID, req.ID, alert.PostURI, req.Status)
+	return nil
+}
+
+// authorizeCommunityModerator returns ErrNotModerator unless actorDID moderates the community
+// or is an instance admin
+func (s *moderationService) authorizeCommunityModerator(ctx context.Context, communityDID, actorDID string, isAdmin bool) error {
+	if isAdmin {
+		return nil
+	}
+	isModerator, err := s.repo.IsCommunityModerator(ctx, communityDID, actorDID)
+	if err != nil {
+		return fmt.Errorf("failed to check moderator of %s: %w", communityDID, err)
+	}
+	if !isModerator {
+		return ErrNotModerator
+	}
+	return nil
+}
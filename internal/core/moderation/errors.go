@@ -0,0 +1,47 @@
+package moderation
+
+import (
+	"errors"
+	"fmt"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+// Domain errors
+var (
+	// ErrContentNotFound is returned when the subject post or comment isn't indexed
+	ErrContentNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "content not found")
+
+	// ErrNotModerator is returned when someone other than the community's moderators or an
+	// instance admin asks for moderator-only data
+	ErrNotModerator = coreerrors.Sentinel(coreerrors.ErrForbidden, "only community moderators and instance admins may do this")
+)
+
+// ValidationError represents a validation error with field details
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("validation error: %s - %s", e.Field, e.Message)
+}
+
+// NewValidationError creates a new validation error
+func NewValidationError(field, message string) error {
+	return &ValidationError{
+		Field:   field,
+		Message: message,
+	}
+}
+
+// IsValidationError reports whether err is a ValidationError
+func IsValidationError(err error) bool {
+	var validationErr *ValidationError
+	return errors.As(err, &validationErr)
+}
+
+// IsNotFound reports whether err is a "not found" error
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrContentNotFound)
+}
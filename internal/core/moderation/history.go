@@ -0,0 +1,34 @@
+package moderation
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// MaxRevisions is how many prior versions are kept per subject; older ones are pruned
+// as new edits are indexed
+const MaxRevisions = 10
+
+// Revision is a prior version of an edited post or comment
+// Revisions are keyed by subject URI, so posts and comments share the same storage.
+type Revision struct {
+	WrittenAt     time.Time       `json:"writtenAt"`  // When this version was indexed
+	ReplacedAt    time.Time       `json:"replacedAt"` // When the edit replacing it was indexed
+	CID           string          `json:"cid"`
+	Content       string          `json:"content"`
+	ContentFacets json.RawMessage `json:"facets,omitempty"`
+	Embed         json.RawMessage `json:"embed,omitempty"`
+}
+
+// GetCommentHistoryRequest asks for a comment's edit history
+type GetCommentHistoryRequest struct {
+	URI      string // AT-URI of the comment
+	ActorDID string // Who is asking
+	IsAdmin  bool   // Instance admins may read any comment's history
+}
+
+// CommentHistory is a comment's prior versions, newest first
+type CommentHistory struct {
+	URI       string      `json:"uri"`
+	Revisions []*Revision `json:"revisions"`
+}
@@ -0,0 +1,33 @@
+package moderation
+
+// LabelState is a moderator's decision about one content label on a post
+type LabelState string
+
+const (
+	// LabelApplied adds the label even if neither the author nor the community set it
+	LabelApplied LabelState = "applied"
+
+	// LabelRemoved drops the label even if the author or the community set it
+	LabelRemoved LabelState = "removed"
+
+	// LabelInherited clears the moderator's decision: the post's self-labels and its
+	// community's content warnings decide again
+	LabelInherited LabelState = "inherited"
+)
+
+// IsValid reports whether s is a known label state
+func (s LabelState) IsValid() bool {
+	switch s {
+	case LabelApplied, LabelRemoved, LabelInherited:
+		return true
+	}
+	return false
+}
+
+// SetPostLabelRequest overrides one of a post's content labels after the fact
+type SetPostLabelRequest struct {
+	Subject  string     `json:"subject"` // AT-URI of the post
+	Label    string     `json:"label"`   // One of the allowed post labels (posts.IsAllowedLabel)
+	State    LabelState `json:"state"`
+	ActorDID string     `json:"-"` // Moderator or admin making the change
+}
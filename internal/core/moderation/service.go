@@ -0,0 +1,137 @@
+package moderation
+
+import (
+	"Coves/internal/atproto/utils"
+	"Coves/internal/core/automod"
+	"Coves/internal/core/blobrefs"
+	"Coves/internal/core/brigade"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/takedown"
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+type moderationService struct {
+	repo         Repository
+	brigadeQueue brigade.QueueRepository // Optional: nil lists no brigade alerts
+	takedowns    takedown.Repository     // Optional: nil disables record takedowns
+	upstream     takedown.UpstreamDeleter
+	automod      automod.Service         // Optional: nil disables automod rule editing
+	automodQueue automod.QueueRepository // Optional: nil lists no automod actions
+	blobRefs     blobrefs.Repository     // Optional: nil keeps blobs of hidden content servable
+}
+
+// NewModerationService creates a new moderation service
+func NewModerationService(repo Repository) Service {
+	return &moderationService{repo: repo}
+}
+
+// SetVisibility validates the request and updates the subject's visibility state
+// The subject's collection decides whether it's a post or a comment
+func (s *moderationService) SetVisibility(ctx context.Context, req SetVisibilityRequest) error {
+	if !strings.HasPrefix(req.Subject, "at://") {
+		return NewValidationError("subject", "subject must be an AT-URI")
+	}
+	if !req.State.IsValid() {
+		return NewValidationError("state", "state must be 'visible', 'removed', or 'author_only'")
+	}
+	if req.ActorDID == "" {
+		return NewValidationError("actor", "actor DID is required")
+	}
+
+	var err error
+	switch utils.ExtractCollectionFromURI(req.Subject) {
+	case postCollection:
+		err = s.repo.SetPostVisibility(ctx, req.Subject, req.State, req.ActorDID)
+	case commentCollection:
+		err = s.repo.SetCommentVisibility(ctx, req.Subject, req.State, req.ActorDID)
+	default:
+		return NewValidationError("subject", "subject must be a post or comment")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set visibility of %s: %w", req.Subject, err)
+	}
+	s.refreshBlobs(ctx, req.Subject)
+
+	log.Printf("%s set visibility of %s to %s", req.ActorDID, req.Subject, req.State)
+	return nil
+}
+
+// SetThreadLock validates the request and locks or unlocks the post's comment thread
+func (s *moderationService) SetThreadLock(ctx context.Context, req SetThreadLockRequest) error {
+	if !strings.HasPrefix(req.Subject, "at://") {
+		return NewValidationError("subject", "subject must be an AT-URI")
+	}
+	if utils.ExtractCollectionFromURI(req.Subject) != postCollection {
+		return NewValidationError("subject", "only posts can be locked")
+	}
+	if req.ActorDID == "" {
+		return NewValidationError("actor", "actor DID is required")
+	}
+
+	if err := s.repo.SetPostLocked(ctx, req.Subject, req.Locked, req.ActorDID); err != nil {
+		return fmt.Errorf("failed to set thread lock of %s: %w", req.Subject, err)
+	}
+
+	log.Printf("%s set thread lock of %s to %t", req.ActorDID, req.Subject, req.Locked)
+	return nil
+}
+
+// SetPostLabel validates the request and applies, removes or clears a label override on a post
+func (s *moderationService) SetPostLabel(ctx context.Context, req SetPostLabelRequest) error {
+	if !strings.HasPrefix(req.Subject, "at://") {
+		return NewValidationError("subject", "subject must be an AT-URI")
+	}
+	if utils.ExtractCollectionFromURI(req.Subject) != postCollection {
+		return NewValidationError("subject", "only posts can be labeled")
+	}
+	if !posts.IsAllowedLabel(req.Label) {
+		return NewValidationError("label", "label must be 'nsfw', 'spoiler', 'violence', or 'gore'")
+	}
+	if !req.State.IsValid() {
+		return NewValidationError("state", "state must be 'applied', 'removed', or 'inherited'")
+	}
+	if req.ActorDID == "" {
+		return NewValidationError("actor", "actor DID is required")
+	}
+
+	var err error
+	if req.State == LabelInherited {
+		err = s.repo.ClearPostLabelOverride(ctx, req.Subject, req.Label)
+	} else {
+		err = s.repo.SetPostLabelOverride(ctx, req.Subject, req.Label, req.State == LabelRemoved, req.ActorDID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set label %s on %s: %w", req.Label, req.Subject, err)
+	}
+
+	log.Printf("%s set label %s on %s to %s", req.ActorDID, req.Label, req.Subject, req.State)
+	return nil
+}
+
+// GetCommentHistory returns a comment's prior versions to its community's moderators and instance admins
+func (s *moderationService) GetCommentHistory(ctx context.Context, req GetCommentHistoryRequest) (*CommentHistory, error) {
+	if !strings.HasPrefix(req.URI, "at://") || utils.ExtractCollectionFromURI(req.URI) != commentCollection {
+		return nil, NewValidationError("uri", "uri must be a comment AT-URI")
+	}
+	if req.ActorDID == "" {
+		return nil, NewValidationError("actor", "actor DID is required")
+	}
+
+	// Also confirms the comment is indexed, so admins get not-found for unknown URIs too
+	isModerator, err := s.repo.IsCommentModerator(ctx, req.URI, req.ActorDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check moderator of %s: %w", req.URI, err)
+	}
+	if !isModerator && !req.IsAdmin {
+		return nil, ErrNotModerator
+	}
+
+	revisions, err := s.repo.ListRevisions(ctx, req.URI, MaxRevisions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions of %s: %w", req.URI, err)
+	}
+	return &CommentHistory{URI: req.URI, Revisions: revisions}, nil
+}
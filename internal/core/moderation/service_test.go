@@ -0,0 +1,731 @@
+package moderation
+
+import (
+	"Coves/internal/core/automod"
+	"Coves/internal/core/blobrefs"
+	"Coves/internal/core/brigade"
+	"Coves/internal/core/takedown"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mockRepository records visibility changes, thread locks and label overrides by URI
+type mockRepository struct {
+	posts    map[string]VisibilityState
+	comments map[string]VisibilityState
+	locked   map[string]bool
+	labels   map[string]map[string]bool // uri -> label -> neg
+
+	moderators          map[string]map[string]bool // comment uri -> moderator DIDs (absent = not indexed)
+	communityModerators map[string]map[string]bool // community DID -> moderator DIDs
+	revisions           map[string][]*Revision
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{
+		posts:    make(map[string]VisibilityState),
+		comments: make(map[string]VisibilityState),
+		locked:   make(map[string]bool),
+		labels:   make(map[string]map[string]bool),
+
+		moderators:          make(map[string]map[string]bool),
+		communityModerators: make(map[string]map[string]bool),
+		revisions:           make(map[string][]*Revision),
+	}
+}
+
+func (m *mockRepository) SetPostVisibility(ctx context.Context, uri string, state VisibilityState, actorDID string) error {
+	if uri == "at://did:plc:author/social.coves.community.post/missing" {
+		return ErrContentNotFound
+	}
+	m.posts[uri] = state
+	return nil
+}
+
+func (m *mockRepository) SetCommentVisibility(ctx context.Context, uri string, state VisibilityState, actorDID string) error {
+	m.comments[uri] = state
+	return nil
+}
+
+func (m *mockRepository) SetPostLocked(ctx context.Context, uri string, locked bool, actorDID string) error {
+	if uri == "at://did:plc:author/social.coves.community.post/missing" {
+		return ErrContentNotFound
+	}
+	m.locked[uri] = locked
+	return nil
+}
+
+func (m *mockRepository) SetPostLabelOverride(ctx context.Context, uri, label string, neg bool, actorDID string) error {
+	if uri == "at://did:plc:author/social.coves.community.post/missing" {
+		return ErrContentNotFound
+	}
+	if m.labels[uri] == nil {
+		m.labels[uri] = make(map[string]bool)
+	}
+	m.labels[uri][label] = neg
+	return nil
+}
+
+func (m *mockRepository) ClearPostLabelOverride(ctx context.Context, uri, label string) error {
+	delete(m.labels[uri], label)
+	return nil
+}
+
+func (m *mockRepository) IsCommentModerator(ctx context.Context, uri, actorDID string) (bool, error) {
+	moderators, ok := m.moderators[uri]
+	if !ok {
+		return false, ErrContentNotFound
+	}
+	return moderators[actorDID], nil
+}
+
+func (m *mockRepository) IsCommunityModerator(ctx context.Context, communityDID, actorDID string) (bool, error) {
+	return m.communityModerators[communityDID][actorDID], nil
+}
+
+func (m *mockRepository) ListRevisions(ctx context.Context, subjectURI string, limit int) ([]*Revision, error) {
+	revisions := m.revisions[subjectURI]
+	if len(revisions) > limit {
+		revisions = revisions[:limit]
+	}
+	return revisions, nil
+}
+
+func TestSetVisibility_RoutesBySubjectCollection(t *testing.T) {
+	repo := newMockRepository()
+	service := NewModerationService(repo)
+	ctx := context.Background()
+
+	postURI := "at://did:plc:community/social.coves.community.post/abc"
+	commentURI := "at://did:plc:author/social.coves.community.comment/def"
+
+	if err := service.SetVisibility(ctx, SetVisibilityRequest{Subject: postURI, State: VisibilityAuthorOnly, ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("Expected post visibility to be set, got %v", err)
+	}
+	if err := service.SetVisibility(ctx, SetVisibilityRequest{Subject: commentURI, State: VisibilityRemoved, ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("Expected comment visibility to be set, got %v", err)
+	}
+
+	if repo.posts[postURI] != VisibilityAuthorOnly {
+		t.Errorf("Expected post to be author_only, got %q", repo.posts[postURI])
+	}
+	if repo.comments[commentURI] != VisibilityRemoved {
+		t.Errorf("Expected comment to be removed, got %q", repo.comments[commentURI])
+	}
+}
+
+func TestSetVisibility_Validation(t *testing.T) {
+	service := NewModerationService(newMockRepository())
+
+	tests := []struct {
+		name string
+		req  SetVisibilityRequest
+	}{
+		{name: "not an AT-URI", req: SetVisibilityRequest{Subject: "https://example.com", State: VisibilityRemoved, ActorDID: "did:plc:admin"}},
+		{name: "unknown state", req: SetVisibilityRequest{Subject: "at://did:plc:a/social.coves.community.post/x", State: "hidden", ActorDID: "did:plc:admin"}},
+		{name: "missing actor", req: SetVisibilityRequest{Subject: "at://did:plc:a/social.coves.community.post/x", State: VisibilityRemoved}},
+		{name: "unsupported collection", req: SetVisibilityRequest{Subject: "at://did:plc:a/social.coves.community.profile/self", State: VisibilityRemoved, ActorDID: "did:plc:admin"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := service.SetVisibility(context.Background(), tt.req); !IsValidationError(err) {
+				t.Errorf("Expected a validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSetVisibility_NotFound(t *testing.T) {
+	service := NewModerationService(newMockRepository())
+
+	err := service.SetVisibility(context.Background(), SetVisibilityRequest{
+		Subject:  "at://did:plc:author/social.coves.community.post/missing",
+		State:    VisibilityRemoved,
+		ActorDID: "did:plc:admin",
+	})
+	if !errors.Is(err, ErrContentNotFound) {
+		t.Errorf("Expected ErrContentNotFound, got %v", err)
+	}
+}
+
+func TestSetThreadLock(t *testing.T) {
+	repo := newMockRepository()
+	service := NewModerationService(repo)
+	ctx := context.Background()
+	postURI := "at://did:plc:community/social.coves.community.post/abc"
+
+	if err := service.SetThreadLock(ctx, SetThreadLockRequest{Subject: postURI, Locked: true, ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("Expected thread to be locked, got %v", err)
+	}
+	if !repo.locked[postURI] {
+		t.Error("Expected post to be locked")
+	}
+	if err := service.SetThreadLock(ctx, SetThreadLockRequest{Subject: postURI, ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("Expected thread to be unlocked, got %v", err)
+	}
+	if repo.locked[postURI] {
+		t.Error("Expected post to be unlocked")
+	}
+
+	invalid := []SetThreadLockRequest{
+		{Subject: "https://example.com", Locked: true, ActorDID: "did:plc:admin"},
+		{Subject: "at://did:plc:author/social.coves.community.comment/def", Locked: true, ActorDID: "did:plc:admin"},
+		{Subject: postURI, Locked: true},
+	}
+	for _, req := range invalid {
+		if err := service.SetThreadLock(ctx, req); !IsValidationError(err) {
+			t.Errorf("Expected a validation error for %+v, got %v", req, err)
+		}
+	}
+
+	err := service.SetThreadLock(ctx, SetThreadLockRequest{
+		Subject: "at://did:plc:author/social.coves.community.post/missing", Locked: true, ActorDID: "did:plc:admin",
+	})
+	if !errors.Is(err, ErrContentNotFound) {
+		t.Errorf("Expected ErrContentNotFound, got %v", err)
+	}
+}
+
+func TestSetPostLabel(t *testing.T) {
+	repo := newMockRepository()
+	service := NewModerationService(repo)
+	ctx := context.Background()
+	postURI := "at://did:plc:community/social.coves.community.post/abc"
+
+	if err := service.SetPostLabel(ctx, SetPostLabelRequest{Subject: postURI, Label: "nsfw", State: LabelApplied, ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("Expected label to be applied, got %v", err)
+	}
+	if err := service.SetPostLabel(ctx, SetPostLabelRequest{Subject: postURI, Label: "spoiler", State: LabelRemoved, ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("Expected label to be removed, got %v", err)
+	}
+	if neg, ok := repo.labels[postURI]["nsfw"]; !ok || neg {
+		t.Errorf("Expected nsfw applied, got %v (set: %v)", neg, ok)
+	}
+	if neg, ok := repo.labels[postURI]["spoiler"]; !ok || !neg {
+		t.Errorf("Expected spoiler removed, got %v (set: %v)", neg, ok)
+	}
+
+	// Inherited clears the override
+	if err := service.SetPostLabel(ctx, SetPostLabelRequest{Subject: postURI, Label: "nsfw", State: LabelInherited, ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("Expected override to be cleared, got %v", err)
+	}
+	if _, ok := repo.labels[postURI]["nsfw"]; ok {
+		t.Error("Expected nsfw override cleared")
+	}
+
+	tests := []struct {
+		name string
+		req  SetPostLabelRequest
+	}{
+		{"comment subject", SetPostLabelRequest{Subject: "at://did:plc:author/social.coves.community.comment/def", Label: "nsfw", State: LabelApplied, ActorDID: "did:plc:admin"}},
+		{"unknown label", SetPostLabelRequest{Subject: postURI, Label: "porn", State: LabelApplied, ActorDID: "did:plc:admin"}},
+		{"unknown state", SetPostLabelRequest{Subject: postURI, Label: "nsfw", State: "hidden", ActorDID: "did:plc:admin"}},
+		{"missing actor", SetPostLabelRequest{Subject: postURI, Label: "nsfw", State: LabelApplied}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := service.SetPostLabel(ctx, tt.req); !IsValidationError(err) {
+				t.Errorf("Expected validation error, got %v", err)
+			}
+		})
+	}
+
+	err := service.SetPostLabel(ctx, SetPostLabelRequest{Subject: "at://did:plc:author/social.coves.community.post/missing", Label: "gore", State: LabelApplied, ActorDID: "did:plc:admin"})
+	if !IsNotFound(err) {
+		t.Errorf("Expected not found for an unindexed post, got %v", err)
+	}
+}
+
+func TestGetCommentHistory_RestrictedToModeratorsAndAdmins(t *testing.T) {
+	repo := newMockRepository()
+	service := NewModerationService(repo)
+	ctx := context.Background()
+
+	commentURI := "at://did:plc:author/social.coves.community.comment/edited"
+	repo.moderators[commentURI] = map[string]bool{"did:plc:mod": true}
+	repo.revisions[commentURI] = []*Revision{{CID: "bafyv2", Content: "second"}, {CID: "bafyv1", Content: "first"}}
+
+	history, err := service.GetCommentHistory(ctx, GetCommentHistoryRequest{URI: commentURI, ActorDID: "did:plc:mod"})
+	if err != nil {
+		t.Fatalf("Expected the community moderator to read the history, got %v", err)
+	}
+	if len(history.Revisions) != 2 || history.Revisions[0].CID != "bafyv2" {
+		t.Errorf("Expected both revisions newest first, got %+v", history.Revisions)
+	}
+
+	if _, err := service.GetCommentHistory(ctx, GetCommentHistoryRequest{URI: commentURI, ActorDID: "did:plc:admin", IsAdmin: true}); err != nil {
+		t.Errorf("Expected an instance admin to read the history, got %v", err)
+	}
+
+	_, err = service.GetCommentHistory(ctx, GetCommentHistoryRequest{URI: commentURI, ActorDID: "did:plc:author"})
+	if !errors.Is(err, ErrNotModerator) {
+		t.Errorf("Expected ErrNotModerator for the comment's author, got %v", err)
+	}
+
+	missing := "at://did:plc:author/social.coves.community.comment/missing"
+	_, err = service.GetCommentHistory(ctx, GetCommentHistoryRequest{URI: missing, ActorDID: "did:plc:admin", IsAdmin: true})
+	if !IsNotFound(err) {
+		t.Errorf("Expected not found for an unindexed comment, got %v", err)
+	}
+
+	_, err = service.GetCommentHistory(ctx, GetCommentHistoryRequest{URI: "at://did:plc:c/social.coves.community.post/p", ActorDID: "did:plc:mod"})
+	if !IsValidationError(err) {
+		t.Errorf("Expected a validation error for a post URI, got %v", err)
+	}
+}
+
+// mockBrigadeQueue holds brigade alerts by ID
+type mockBrigadeQueue struct {
+	alerts map[int64]*brigade.Alert
+}
+
+func (m *mockBrigadeQueue) ListOpenAlerts(ctx context.Context, communityDID string, limit, offset int) ([]*brigade.Alert, error) {
+	alerts := []*brigade.Alert{}
+	for _, alert := range m.alerts {
+		if alert.CommunityDID == communityDID && alert.Status == brigade.AlertOpen {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+func (m *mockBrigadeQueue) GetAlert(ctx context.Context, id int64) (*brigade.Alert, error) {
+	alert, ok := m.alerts[id]
+	if !ok {
+		return nil, brigade.ErrAlertNotFound
+	}
+	return alert, nil
+}
+
+func (m *mockBrigadeQueue) ReviewAlert(ctx context.Context, id int64, status brigade.AlertStatus, reviewerDID string) error {
+	alert, ok := m.alerts[id]
+	if !ok || alert.Status != brigade.AlertOpen {
+		return brigade.ErrAlertNotFound
+	}
+	alert.Status = status
+	alert.ReviewedBy = reviewerDID
+	return nil
+}
+
+func TestBrigadeAlerts_RestrictedToCommunityModerators(t *testing.T) {
+	repo := newMockRepository()
+	repo.communityModerators["did:plc:community"] = map[string]bool{"did:plc:mod": true}
+	queue := &mockBrigadeQueue{alerts: map[int64]*brigade.Alert{
+		1: {ID: 1, PostURI: "at://did:plc:community/social.coves.community.post/a", CommunityDID: "did:plc:community", Status: brigade.AlertOpen},
+		2: {ID: 2, PostURI: "at://did:plc:other/social.coves.community.post/b", CommunityDID: "did:plc:other", Status: brigade.AlertOpen},
+	}}
+	service := NewModerationService(repo)
+	service.(interface{ SetBrigadeQueue(brigade.QueueRepository) }).SetBrigadeQueue(queue)
+	ctx := context.Background()
+
+	alerts, err := service.ListBrigadeAlerts(ctx, ListBrigadeAlertsRequest{CommunityDID: "did:plc:community", ActorDID: "did:plc:mod", Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected the community moderator to list alerts, got %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].ID != 1 {
+		t.Errorf("Expected only the community's alert, got %+v", alerts)
+	}
+
+	_, err = service.ListBrigadeAlerts(ctx, ListBrigadeAlertsRequest{CommunityDID: "did:plc:community", ActorDID: "did:plc:someone", Limit: 10})
+	if !errors.Is(err, ErrNotModerator) {
+		t.Errorf("Expected ErrNotModerator for a non-moderator, got %v", err)
+	}
+	if _, err := service.ListBrigadeAlerts(ctx, ListBrigadeAlertsRequest{CommunityDID: "did:plc:other", ActorDID: "did:plc:admin", IsAdmin: true, Limit: 10}); err != nil {
+		t.Errorf("Expected an instance admin to list any community's alerts, got %v", err)
+	}
+
+	// Moderating one community doesn't let you review another's alerts
+	err = service.ReviewBrigadeAlert(ctx, ReviewBrigadeAlertRequest{ID: 2, Status: brigade.AlertDismissed, ActorDID: "did:plc:mod"})
+	if !errors.Is(err, ErrNotModerator) {
+		t.Errorf("Expected ErrNotModerator for another community's alert, got %v", err)
+	}
+
+	if err := service.ReviewBrigadeAlert(ctx, ReviewBrigadeAlertRequest{ID: 1, Status: brigade.AlertConfirmed, ActorDID: "did:plc:mod"}); err != nil {
+		t.Fatalf("Expected the moderator to confirm the alert, got %v", err)
+	}
+	if queue.alerts[1].Status != brigade.AlertConfirmed || queue.alerts[1].ReviewedBy != "did:plc:mod" {
+		t.Errorf("Expected the alert confirmed by the moderator, got %+v", queue.alerts[1])
+	}
+
+	err = service.ReviewBrigadeAlert(ctx, ReviewBrigadeAlertRequest{ID: 1, Status: brigade.AlertDismissed, ActorDID: "did:plc:mod"})
+	if !errors.Is(err, brigade.ErrAlertNotFound) {
+		t.Errorf("Expected ErrAlertNotFound for a reviewed alert, got %v", err)
+	}
+	err = service.ReviewBrigadeAlert(ctx, ReviewBrigadeAlertRequest{ID: 2, Status: brigade.AlertOpen, ActorDID: "did:plc:admin", IsAdmin: true})
+	if !IsValidationError(err) {
+		t.Errorf("Expected a validation error for reopening, got %v", err)
+	}
+	_, err = service.ListBrigadeAlerts(ctx, ListBrigadeAlertsRequest{CommunityDID: "community", ActorDID: "did:plc:mod"})
+	if !IsValidationError(err) {
+		t.Errorf("Expected a validation error for a non-DID community, got %v", err)
+	}
+}
+
+// mockAutomodRepo holds automod rules by community and actions by ID
+type mockAutomodRepo struct {
+	rules   map[string][]automod.Rule
+	actions map[int64]*automod.ActionRecord
+}
+
+func (m *mockAutomodRepo) GetRules(ctx context.Context, communityDID string) ([]automod.Rule, error) {
+	return m.rules[communityDID], nil
+}
+
+func (m *mockAutomodRepo) SetRules(ctx context.Context, communityDID string, rules []automod.Rule, updatedBy string) error {
+	m.rules[communityDID] = rules
+	return nil
+}
+
+func (m *mockAutomodRepo) RecordAction(ctx context.Context, record *automod.ActionRecord) error {
+	return nil
+}
+
+func (m *mockAutomodRepo) ListOpenActions(ctx context.Context, communityDID string, limit, offset int) ([]*automod.ActionRecord, error) {
+	records := []*automod.ActionRecord{}
+	for _, record := range m.actions {
+		if record.CommunityDID == communityDID && record.Status == automod.ReviewOpen {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (m *mockAutomodRepo) GetAction(ctx context.Context, id int64) (*automod.ActionRecord, error) {
+	record, ok := m.actions[id]
+	if !ok {
+		return nil, automod.ErrActionNotFound
+	}
+	return record, nil
+}
+
+func (m *mockAutomodRepo) ReviewAction(ctx context.Context, id int64, status automod.ReviewStatus, reviewerDID string) error {
+	record, ok := m.actions[id]
+	if !ok || record.Status != automod.ReviewOpen {
+		return automod.ErrActionNotFound
+	}
+	record.Status = status
+	record.ReviewedBy = reviewerDID
+	return nil
+}
+
+func TestAutomod_RestrictedToCommunityModerators(t *testing.T) {
+	repo := newMockRepository()
+	repo.communityModerators["did:plc:community"] = map[string]bool{"did:plc:mod": true}
+	automodRepo := &mockAutomodRepo{
+		rules: make(map[string][]automod.Rule),
+		actions: map[int64]*automod.ActionRecord{
+			1: {ID: 1, SubjectURI: "at://did:plc:community/social.coves.community.post/a", CommunityDID: "did:plc:community", Action: automod.ActionHold, Status: automod.ReviewOpen},
+			2: {ID: 2, SubjectURI: "at://did:plc:other/social.coves.community.post/b", CommunityDID: "did:plc:other", Action: automod.ActionFlag, Status: automod.ReviewOpen},
+		},
+	}
+	service := NewModerationService(repo)
+	ctx := context.Background()
+	rules := []automod.Rule{{Pattern: "spam", MatchType: automod.MatchKeyword, Action: automod.ActionHold}}
+
+	_, err := service.UpdateAutomod(ctx, UpdateAutomodRequest{CommunityDID: "did:plc:community", Rules: rules, ActorDID: "did:plc:mod"})
+	if !errors.Is(err, errAutomodDisabled) {
+		t.Fatalf("Expected errAutomodDisabled before automod is configured, got %v", err)
+	}
+
+	service.(interface {
+		SetAutomod(automod.Service, automod.QueueRepository)
+	}).SetAutomod(automod.NewAutomodService(automodRepo), automodRepo)
+
+	config, err := service.UpdateAutomod(ctx, UpdateAutomodRequest{CommunityDID: "did:plc:community", Rules: rules, ActorDID: "did:plc:mod"})
+	if err != nil {
+		t.Fatalf("Expected the community moderator to update rules, got %v", err)
+	}
+	if len(config.Rules) != 1 || len(automodRepo.rules["did:plc:community"]) != 1 {
+		t.Errorf("Expected the rule saved, got %+v", automodRepo.rules)
+	}
+
+	_, err = service.UpdateAutomod(ctx, UpdateAutomodRequest{CommunityDID: "did:plc:community", Rules: rules, ActorDID: "did:plc:someone"})
+	if !errors.Is(err, ErrNotModerator) {
+		t.Errorf("Expected ErrNotModerator for a non-moderator, got %v", err)
+	}
+	_, err = service.UpdateAutomod(ctx, UpdateAutomodRequest{
+		CommunityDID: "did:plc:community",
+		Rules:        []automod.Rule{{Pattern: "x", MatchType: automod.MatchKeyword, Action: "ban"}},
+		ActorDID:     "did:plc:mod",
+	})
+	if !automod.IsValidationError(err) {
+		t.Errorf("Expected a validation error for an unknown action, got %v", err)
+	}
+
+	config, err = service.GetAutomod(ctx, GetAutomodRequest{CommunityDID: "did:plc:other", ActorDID: "did:plc:admin", IsAdmin: true})
+	if err != nil {
+		t.Fatalf("Expected an instance admin to read any community's rules, got %v", err)
+	}
+	if len(config.Rules) != 0 {
+		t.Errorf("Expected no rules for an unconfigured community, got %+v", config.Rules)
+	}
+
+	queued, err := service.ListAutomodQueue(ctx, ListAutomodQueueRequest{CommunityDID: "did:plc:community", ActorDID: "did:plc:mod", Limit: 10})
+	if err != nil {
+		t.Fatalf("Expected the community moderator to list the queue, got %v", err)
+	}
+	if len(queued) != 1 || queued[0].ID != 1 {
+		t.Errorf("Expected only the community's action, got %+v", queued)
+	}
+
+	// Moderating one community doesn't let you review another's queue
+	err = service.ReviewAutomodAction(ctx, ReviewAutomodActionRequest{ID: 2, Status: automod.ReviewApproved, ActorDID: "did:plc:mod"})
+	if !errors.Is(err, ErrNotModerator) {
+		t.Errorf("Expected ErrNotModerator for another community's action, got %v", err)
+	}
+	if err := service.ReviewAutomodAction(ctx, ReviewAutomodActionRequest{ID: 1, Status: automod.ReviewApproved, ActorDID: "did:plc:mod"}); err != nil {
+		t.Fatalf("Expected the moderator to approve the held post, got %v", err)
+	}
+	if automodRepo.actions[1].Status != automod.ReviewApproved || automodRepo.actions[1].ReviewedBy != "did:plc:mod" {
+		t.Errorf("Expected the action approved by the moderator, got %+v", automodRepo.actions[1])
+	}
+	err = service.ReviewAutomodAction(ctx, ReviewAutomodActionRequest{ID: 1, Status: automod.ReviewRemoved, ActorDID: "did:plc:mod"})
+	if !errors.Is(err, automod.ErrActionNotFound) {
+		t.Errorf("Expected ErrActionNotFound for a reviewed action, got %v", err)
+	}
+	err = service.ReviewAutomodAction(ctx, ReviewAutomodActionRequest{ID: 2, Status: automod.ReviewOpen, ActorDID: "did:plc:admin", IsAdmin: true})
+	if !IsValidationError(err) {
+		t.Errorf("Expected a validation error for reopening, got %v", err)
+	}
+}
+
+// mockTakedownRepo holds takedowns by subject, in creation order
+type mockTakedownRepo struct {
+	indexed map[string]bool
+	all     []*takedown.Takedown
+}
+
+func (m *mockTakedownRepo) active(uri string) *takedown.Takedown {
+	for _, t := range m.all {
+		if t.SubjectURI == uri && t.ReversedAt == nil {
+			return t
+		}
+	}
+	return nil
+}
+
+func (m *mockTakedownRepo) GetActive(ctx context.Context, uri string) (*takedown.Takedown, error) {
+	if t := m.active(uri); t != nil {
+		return t, nil
+	}
+	return nil, takedown.ErrTakedownNotFound
+}
+
+func (m *mockTakedownRepo) Takedown(ctx context.Context, t *takedown.Takedown) (bool, error) {
+	if !m.indexed[t.SubjectURI] {
+		return false, takedown.ErrSubjectNotFound
+	}
+	if active := m.active(t.SubjectURI); active != nil {
+		*t = *active
+		return false, nil
+	}
+	t.ID = int64(len(m.all) + 1)
+	stored := *t
+	m.all = append(m.all, &stored)
+	return true, nil
+}
+
+func (m *mockTakedownRepo) MarkUpstreamDeleted(ctx context.Context, id int64) error {
+	m.all[id-1].UpstreamDeleted = true
+	return nil
+}
+
+func (m *mockTakedownRepo) Reverse(ctx context.Context, uri, actorDID string) (*takedown.Takedown, error) {
+	t := m.active(uri)
+	if t == nil {
+		return nil, takedown.ErrTakedownNotFound
+	}
+	now := time.Now()
+	t.ReversedAt, t.ReversedBy = &now, actorDID
+	return t, nil
+}
+
+// mockUpstreamDeleter deletes records in the repos it hosts, failing the first fails attempts
+type mockUpstreamDeleter struct {
+	hosted  map[string]bool // repo DID -> hosted here
+	fails   int
+	deleted []string
+}
+
+func (m *mockUpstreamDeleter) DeleteRecord(ctx context.Context, uri string) (bool, error) {
+	if !m.hosted[strings.Split(strings.TrimPrefix(uri, "at://"), "/")[0]] {
+		return false, nil
+	}
+	if m.fails > 0 {
+		m.fails--
+		return false, errors.New("PDS unavailable")
+	}
+	m.deleted = append(m.deleted, uri)
+	return true, nil
+}
+
+func TestTakedownRecord_DeletesUpstreamOnlyForHostedRepos(t *testing.T) {
+	const (
+		post    = "at://did:plc:community/social.coves.community.post/p"
+		comment = "at://did:plc:user/social.coves.community.comment/c"
+	)
+	repo := &mockTakedownRepo{indexed: map[string]bool{post: true, comment: true}}
+	deleter := &mockUpstreamDeleter{hosted: map[string]bool{"did:plc:community": true}}
+	service := NewModerationService(newMockRepository())
+	service.(interface {
+		SetTakedowns(takedown.Repository, takedown.UpstreamDeleter)
+	}).SetTakedowns(repo, deleter)
+	ctx := context.Background()
+
+	result, err := service.TakedownRecord(ctx, TakedownRecordRequest{Subject: post, Reason: takedown.ReasonLegal, Note: "court order", ActorDID: "did:plc:admin"})
+	if err != nil {
+		t.Fatalf("TakedownRecord failed: %v", err)
+	}
+	if !result.UpstreamDeleted || len(deleter.deleted) != 1 || !repo.all[0].UpstreamDeleted {
+		t.Errorf("Expected the community post deleted upstream, got %+v (deleted %v)", result, deleter.deleted)
+	}
+	if result.CreatedBy != "did:plc:admin" || result.Reason != takedown.ReasonLegal {
+		t.Errorf("Expected the takedown attributed to the admin, got %+v", result)
+	}
+
+	// User repos aren't ours to write to: the takedown is local only
+	result, err = service.TakedownRecord(ctx, TakedownRecordRequest{Subject: comment, Reason: takedown.ReasonAbuse, ActorDID: "did:plc:admin"})
+	if err != nil {
+		t.Fatalf("TakedownRecord failed: %v", err)
+	}
+	if result.UpstreamDeleted || len(deleter.deleted) != 1 {
+		t.Errorf("Expected no upstream deletion for a user-repo comment, got %+v", result)
+	}
+
+	// Taking a record down again doesn't add to the audit trail
+	if _, err := service.TakedownRecord(ctx, TakedownRecordRequest{Subject: post, Reason: takedown.ReasonSpam, ActorDID: "did:plc:other"}); err != nil {
+		t.Fatalf("Repeated TakedownRecord failed: %v", err)
+	}
+	if len(repo.all) != 2 || len(deleter.deleted) != 1 {
+		t.Errorf("Expected a repeated takedown to be a no-op, got %d takedowns and %d deletions", len(repo.all), len(deleter.deleted))
+	}
+}
+
+func TestTakedownRecord_RetriesFailedUpstreamDeletion(t *testing.T) {
+	const post = "at://did:plc:community/social.coves.community.post/p"
+	repo := &mockTakedownRepo{indexed: map[string]bool{post: true}}
+	deleter := &mockUpstreamDeleter{hosted: map[string]bool{"did:plc:community": true}, fails: 1}
+	service := NewModerationService(newMockRepository())
+	service.(interface {
+		SetTakedowns(takedown.Repository, takedown.UpstreamDeleter)
+	}).SetTakedowns(repo, deleter)
+	ctx := context.Background()
+	req := TakedownRecordRequest{Subject: post, Reason: takedown.ReasonCopyright, ActorDID: "did:plc:admin"}
+
+	// The local takedown stands even when the PDS is unreachable
+	result, err := service.TakedownRecord(ctx, req)
+	if err != nil {
+		t.Fatalf("Expected the local takedown to succeed, got %v", err)
+	}
+	if result.UpstreamDeleted || repo.active(post) == nil {
+		t.Errorf("Expected a local-only takedown, got %+v", result)
+	}
+
+	result, err = service.TakedownRecord(ctx, req)
+	if err != nil {
+		t.Fatalf("Retry failed: %v", err)
+	}
+	if !result.UpstreamDeleted || len(deleter.deleted) != 1 || len(repo.all) != 1 {
+		t.Errorf("Expected the retry to delete upstream without a second takedown, got %+v", result)
+	}
+}
+
+func TestTakedownRecord_Validation(t *testing.T) {
+	const post = "at://did:plc:community/social.coves.community.post/p"
+	repo := &mockTakedownRepo{indexed: map[string]bool{post: true}}
+	service := NewModerationService(newMockRepository())
+	service.(interface {
+		SetTakedowns(takedown.Repository, takedown.UpstreamDeleter)
+	}).SetTakedowns(repo, nil)
+	ctx := context.Background()
+
+	invalid := []TakedownRecordRequest{
+		{Subject: "not-a-uri", Reason: takedown.ReasonLegal, ActorDID: "did:plc:admin"},
+		{Subject: "at://did:plc:user/social.coves.community.vote/v", Reason: takedown.ReasonLegal, ActorDID: "did:plc:admin"},
+		{Subject: post, Reason: "because", ActorDID: "did:plc:admin"},
+		{Subject: post, Reason: takedown.ReasonLegal},
+	}
+	for _, req := range invalid {
+		if _, err := service.TakedownRecord(ctx, req); !IsValidationError(err) {
+			t.Errorf("Expected a validation error for %+v, got %v", req, err)
+		}
+	}
+
+	_, err := service.TakedownRecord(ctx, TakedownRecordRequest{Subject: "at://did:plc:community/social.coves.community.post/missing", Reason: takedown.ReasonLegal, ActorDID: "did:plc:admin"})
+	if !errors.Is(err, takedown.ErrSubjectNotFound) {
+		t.Errorf("Expected ErrSubjectNotFound for an unindexed record, got %v", err)
+	}
+	_, err = service.ReverseTakedown(ctx, ReverseTakedownRequest{Subject: post, ActorDID: "did:plc:admin"})
+	if !errors.Is(err, takedown.ErrTakedownNotFound) {
+		t.Errorf("Expected ErrTakedownNotFound reversing a record that isn't down, got %v", err)
+	}
+
+	if _, err := service.TakedownRecord(ctx, TakedownRecordRequest{Subject: post, Reason: takedown.ReasonLegal, ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("TakedownRecord failed: %v", err)
+	}
+	reversed, err := service.ReverseTakedown(ctx, ReverseTakedownRequest{Subject: post, ActorDID: "did:plc:admin2"})
+	if err != nil {
+		t.Fatalf("ReverseTakedown failed: %v", err)
+	}
+	if reversed.ReversedAt == nil || reversed.ReversedBy != "did:plc:admin2" {
+		t.Errorf("Expected the reversal recorded, got %+v", reversed)
+	}
+}
+
+// mockBlobRefs records which subjects had their blobs refreshed
+type mockBlobRefs struct {
+	refreshed []string
+}
+
+func (m *mockBlobRefs) IsBlocked(ctx context.Context, cid string) (bool, error) { return false, nil }
+
+func (m *mockBlobRefs) SetReferences(ctx context.Context, uri string, cids []string) error {
+	return nil
+}
+
+func (m *mockBlobRefs) Refresh(ctx context.Context, uri string) error {
+	m.refreshed = append(m.refreshed, uri)
+	return nil
+}
+
+func TestVisibilityChanges_RefreshBlobs(t *testing.T) {
+	const (
+		post    = "at://did:plc:community/social.coves.community.post/p"
+		comment = "at://did:plc:user/social.coves.community.comment/c"
+	)
+	blobs := &mockBlobRefs{}
+	service := NewModerationService(newMockRepository())
+	service.(interface {
+		SetTakedowns(takedown.Repository, takedown.UpstreamDeleter)
+	}).SetTakedowns(&mockTakedownRepo{indexed: map[string]bool{post: true}}, nil)
+	service.(interface{ SetBlobReferences(blobrefs.Repository) }).SetBlobReferences(blobs)
+	ctx := context.Background()
+
+	if _, err := service.TakedownRecord(ctx, TakedownRecordRequest{Subject: post, Reason: takedown.ReasonLegal, ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("TakedownRecord failed: %v", err)
+	}
+	if _, err := service.ReverseTakedown(ctx, ReverseTakedownRequest{Subject: post, ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("ReverseTakedown failed: %v", err)
+	}
+	if err := service.SetVisibility(ctx, SetVisibilityRequest{Subject: comment, State: VisibilityRemoved, ActorDID: "did:plc:mod"}); err != nil {
+		t.Fatalf("SetVisibility failed: %v", err)
+	}
+
+	want := []string{post, post, comment}
+	if strings.Join(blobs.refreshed, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected blobs refreshed for %v, got %v", want, blobs.refreshed)
+	}
+
+	// A rejected change leaves the blobs alone
+	blobs.refreshed = nil
+	if err := service.SetVisibility(ctx, SetVisibilityRequest{Subject: "at://did:plc:author/social.coves.community.post/missing", State: VisibilityRemoved, ActorDID: "did:plc:mod"}); err == nil {
+		t.Fatal("Expected SetVisibility of a missing post to fail")
+	}
+	if len(blobs.refreshed) != 0 {
+		t.Errorf("Expected no refresh after a failed change, got %v", blobs.refreshed)
+	}
+}
@@ -0,0 +1,125 @@
+package moderation
+
+import (
+	"Coves/internal/atproto/utils"
+	"Coves/internal/core/takedown"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// TakedownRecordRequest takes down a post or comment instance-wide
+type TakedownRecordRequest struct {
+	Subject  string          `json:"subject"`
+	Reason   takedown.Reason `json:"reason"`
+	Note     string          `json:"note,omitempty"`
+	ActorDID string          `json:"-"`
+}
+
+// ReverseTakedownRequest reverses a record's active takedown
+type ReverseTakedownRequest struct {
+	Subject  string `json:"subject"`
+	ActorDID string `json:"-"`
+}
+
+// maxTakedownNoteLength bounds the admin-only note kept in the audit trail
+const maxTakedownNoteLength = 3000
+
+// errTakedownsDisabled is returned when no takedown repository is configured
+var errTakedownsDisabled = errors.New("record takedowns are not configured")
+
+// SetTakedowns enables record takedowns
+// deleter may be nil, in which case takedowns only apply locally.
+func (s *moderationService) SetTakedowns(repo takedown.Repository, deleter takedown.UpstreamDeleter) {
+	s.takedowns = repo
+	s.upstream = deleter
+}
+
+// TakedownRecord hides a post or comment from every read and records the takedown
+// When the record lives in a community repo this instance provisioned it's also deleted from
+// the PDS so it can't re-federate. Taking down a record that's already down returns the active
+// takedown, retrying the upstream deletion if it hadn't succeeded.
+func (s *moderationService) TakedownRecord(ctx context.Context, req TakedownRecordRequest) (*takedown.Takedown, error) {
+	if err := validateTakedownSubject(req.Subject); err != nil {
+		return nil, err
+	}
+	if !req.Reason.IsValid() {
+		return nil, NewValidationError("reason", "reason must be 'legal', 'copyright', 'abuse', 'spam', or 'other'")
+	}
+	if len(req.Note) > maxTakedownNoteLength {
+		return nil, NewValidationError("note", fmt.Sprintf("note must be at most %d characters", maxTakedownNoteLength))
+	}
+	if req.ActorDID == "" {
+		return nil, NewValidationError("actor", "actor DID is required")
+	}
+	if s.takedowns == nil {
+		return nil, errTakedownsDisabled
+	}
+
+	t := &takedown.Takedown{
+		SubjectURI: req.Subject,
+		Reason:     req.Reason,
+		Note:       req.Note,
+		CreatedBy:  req.ActorDID,
+	}
+	created, err := s.takedowns.Takedown(ctx, t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take down %s: %w", req.Subject, err)
+	}
+	if created {
+		log.Printf("%s took down %s (%s)", req.ActorDID, req.Subject, req.Reason)
+	}
+	s.refreshBlobs(ctx, req.Subject)
+
+	if s.upstream != nil && !t.UpstreamDeleted {
+		// The local takedown already hides the record; a failed upstream deletion is retried
+		// by taking the record down again
+		deleted, err := s.upstream.DeleteRecord(ctx, req.Subject)
+		if err != nil {
+			log.Printf("Warning: failed to delete taken-down %s from its PDS: %v", req.Subject, err)
+		} else if deleted {
+			if err := s.takedowns.MarkUpstreamDeleted(ctx, t.ID); err != nil {
+				log.Printf("Warning: failed to record upstream deletion of %s: %v", req.Subject, err)
+			}
+			t.UpstreamDeleted = true
+			log.Printf("Deleted taken-down %s from its PDS", req.Subject)
+		}
+	}
+	return t, nil
+}
+
+// ReverseTakedown shows a taken-down record again
+// Records already deleted from their PDS stay gone upstream; reversal only restores the local row.
+func (s *moderationService) ReverseTakedown(ctx context.Context, req ReverseTakedownRequest) (*takedown.Takedown, error) {
+	if err := validateTakedownSubject(req.Subject); err != nil {
+		return nil, err
+	}
+	if req.ActorDID == "" {
+		return nil, NewValidationError("actor", "actor DID is required")
+	}
+	if s.takedowns == nil {
+		return nil, takedown.ErrTakedownNotFound
+	}
+
+	t, err := s.takedowns.Reverse(ctx, req.Subject, req.ActorDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reverse takedown of %s: %w", req.Subject, err)
+	}
+	s.refreshBlobs(ctx, req.Subject)
+
+	log.Printf("%s reversed takedown of %s", req.ActorDID, req.Subject)
+	return t, nil
+}
+
+func validateTakedownSubject(subject string) error {
+	if !strings.HasPrefix(subject, "at://") {
+		return NewValidationError("subject", "subject must be an AT-URI")
+	}
+	switch utils.ExtractCollectionFromURI(subject) {
+	case postCollection, commentCollection:
+		return nil
+	}
+	return NewValidationError("subject", "subject must be a post or comment")
+}
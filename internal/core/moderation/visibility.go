@@ -0,0 +1,100 @@
+package moderation
+
+import (
+	"Coves/internal/core/automod"
+	"Coves/internal/core/brigade"
+	"Coves/internal/core/takedown"
+	"context"
+)
+
+// VisibilityState is the moderation visibility of a post or comment
+// It is independent of deletion: the record still exists, it's just not shown
+type VisibilityState string
+
+const (
+	// VisibilityVisible content is shown to everyone
+	VisibilityVisible VisibilityState = "visible"
+
+	// VisibilityRemoved content is hidden from everyone
+	VisibilityRemoved VisibilityState = "removed"
+
+	// VisibilityAuthorOnly content is hidden from everyone except its author, who sees it
+	// exactly as before - a "shadow ban" that defuses trolls without tipping them off
+	VisibilityAuthorOnly VisibilityState = "author_only"
+)
+
+// Subject collections whose visibility can be moderated
+const (
+	postCollection    = "social.coves.community.post"
+	commentCollection = "social.coves.community.comment"
+)
+
+// IsValid reports whether s is a known visibility state
+func (s VisibilityState) IsValid() bool {
+	switch s {
+	case VisibilityVisible, VisibilityRemoved, VisibilityAuthorOnly:
+		return true
+	}
+	return false
+}
+
+// SetVisibilityRequest changes the visibility of a post or comment
+type SetVisibilityRequest struct {
+	Subject  string          `json:"subject"` // AT-URI of the post or comment
+	State    VisibilityState `json:"state"`
+	ActorDID string          `json:"-"` // Moderator or admin making the change
+}
+
+// SetThreadLockRequest locks or unlocks a post's comment thread
+// Locked threads accept no new comments; existing comments stay as they are
+type SetThreadLockRequest struct {
+	Subject  string `json:"subject"` // AT-URI of the post
+	ActorDID string `json:"-"`       // Moderator or admin making the change
+	Locked   bool   `json:"locked"`
+}
+
+// Repository persists content visibility, thread locks and post label overrides
+// All methods return ErrContentNotFound when no indexed record has the URI
+type Repository interface {
+	SetPostVisibility(ctx context.Context, uri string, state VisibilityState, actorDID string) error
+	SetCommentVisibility(ctx context.Context, uri string, state VisibilityState, actorDID string) error
+	SetPostLocked(ctx context.Context, uri string, locked bool, actorDID string) error
+
+	// SetPostLabelOverride applies (neg false) or removes (neg true) a label regardless of the
+	// post's self-labels and its community's content warnings
+	SetPostLabelOverride(ctx context.Context, uri, label string, neg bool, actorDID string) error
+	// ClearPostLabelOverride drops the override so the label is inherited again
+	ClearPostLabelOverride(ctx context.Context, uri, label string) error
+
+	// IsCommentModerator reports whether actorDID moderates (or created) the community the
+	// comment's thread belongs to; deleted comments are included
+	IsCommentModerator(ctx context.Context, uri, actorDID string) (bool, error)
+	// IsCommunityModerator reports whether actorDID moderates (or created) the community
+	IsCommunityModerator(ctx context.Context, communityDID, actorDID string) (bool, error)
+	// ListRevisions returns up to limit prior versions of a subject, newest first
+	ListRevisions(ctx context.Context, subjectURI string, limit int) ([]*Revision, error)
+}
+
+// Service applies moderation actions to content
+// Used by the admin API; moderation actions from other sources should go through it too
+type Service interface {
+	SetVisibility(ctx context.Context, req SetVisibilityRequest) error
+	SetThreadLock(ctx context.Context, req SetThreadLockRequest) error
+	SetPostLabel(ctx context.Context, req SetPostLabelRequest) error
+	GetCommentHistory(ctx context.Context, req GetCommentHistoryRequest) (*CommentHistory, error)
+
+	// ListBrigadeAlerts and ReviewBrigadeAlert are the brigade alert moderation queue
+	ListBrigadeAlerts(ctx context.Context, req ListBrigadeAlertsRequest) ([]*brigade.Alert, error)
+	ReviewBrigadeAlert(ctx context.Context, req ReviewBrigadeAlertRequest) error
+
+	// GetAutomod and UpdateAutomod read and replace a community's automod rules;
+	// ListAutomodQueue and ReviewAutomodAction are its queue of held and flagged content
+	GetAutomod(ctx context.Context, req GetAutomodRequest) (*AutomodConfig, error)
+	UpdateAutomod(ctx context.Context, req UpdateAutomodRequest) (*AutomodConfig, error)
+	ListAutomodQueue(ctx context.Context, req ListAutomodQueueRequest) ([]*automod.ActionRecord, error)
+	ReviewAutomodAction(ctx context.Context, req ReviewAutomodActionRequest) error
+
+	// TakedownRecord and ReverseTakedown are instance admin takedowns of posts and comments
+	TakedownRecord(ctx context.Context, req TakedownRecordRequest) (*takedown.Takedown, error)
+	ReverseTakedown(ctx context.Context, req ReverseTakedownRequest) (*takedown.Takedown, error)
+}
@@ -0,0 +1,119 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+// Notification reasons (notifications.reason)
+const (
+	// ReasonMention notifies a user mentioned in a comment
+	ReasonMention = "mention"
+
+	// ReasonReply notifies a post or comment author of replies, grouped per parent per hour
+	ReasonReply = "reply"
+
+	// ReasonBrigadeAlert notifies community moderators of a brigade alert (brigade.NotificationReason)
+	ReasonBrigadeAlert = "brigade_alert"
+
+	// ReasonScheduledPostFailed notifies community moderators that a scheduled post keeps failing to publish
+	ReasonScheduledPostFailed = "scheduled_post_failed"
+)
+
+// MaxUpdateSeen caps how many notifications one updateSeen call can mark
+const MaxUpdateSeen = 100
+
+var (
+	// ErrUnauthorized is returned when the request has no recipient
+	ErrUnauthorized = coreerrors.Sentinel(coreerrors.ErrUnauthorized, "unauthorized")
+
+	// ErrNoNotifications is returned when updateSeen is called without ids
+	ErrNoNotifications = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "at least one notification id is required")
+
+	// ErrTooManyNotifications is returned when updateSeen is called with more than MaxUpdateSeen ids
+	ErrTooManyNotifications = coreerrors.Sentinel(coreerrors.ErrInvalidInput, fmt.Sprintf("at most %d notification ids can be marked seen at once", MaxUpdateSeen))
+)
+
+// Notification is one row of a user's notification list
+// A reply notification stands for every reply to SubjectParentURI in its hour bucket:
+// ReplyCount counts them, and Actor and SubjectURI are the newest.
+type Notification struct {
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	Actor            Actor     `json:"actor"`
+	Reason           string    `json:"reason"`
+	SubjectURI       string    `json:"subject"`
+	SubjectParentURI string    `json:"subjectParent,omitempty"`
+	URL              string    `json:"url,omitempty"` // Web path of the subject; "" when it can't be resolved
+	Text             string    `json:"text"`
+	ID               int64     `json:"id"`
+	ReplyCount       int       `json:"replyCount,omitempty"`
+	IsRead           bool      `json:"isRead"`
+}
+
+// Actor is the user whose action triggered a notification
+// Handle is empty when the actor isn't indexed.
+type Actor struct {
+	DID    string `json:"did"`
+	Handle string `json:"handle,omitempty"`
+}
+
+// Render sets the notification's display text, e.g. "alice.test replied" or
+// "alice.test and 2 others replied"
+func (n *Notification) Render() {
+	name := n.Actor.Handle
+	if name == "" {
+		name = n.Actor.DID
+	}
+
+	switch n.Reason {
+	case ReasonReply:
+		switch others := n.ReplyCount - 1; {
+		case others <= 0:
+			n.Text = name + " replied"
+		case others == 1:
+			n.Text = name + " and 1 other replied"
+		default:
+			n.Text = fmt.Sprintf("%s and %d others replied", name, others)
+		}
+	case ReasonMention:
+		n.Text = name + " mentioned you"
+	case ReasonBrigadeAlert:
+		n.Text = "A post in your community may be the target of a vote brigade"
+	case ReasonScheduledPostFailed:
+		n.Text = "A scheduled post in your community failed to publish"
+	default:
+		n.Text = ""
+	}
+}
+
+// ListNotificationsRequest is a page of the recipient's notifications, latest activity first
+type ListNotificationsRequest struct {
+	RecipientDID string
+	Limit        int
+	Offset       int
+}
+
+// UpdateSeenRequest marks notifications read
+// Marking a grouped reply notification marks every reply in the group seen.
+type UpdateSeenRequest struct {
+	RecipientDID string  `json:"-"`
+	IDs          []int64 `json:"ids"`
+}
+
+// Repository reads and updates notifications
+type Repository interface {
+	// List returns the recipient's notifications ordered by updated_at, newest first
+	List(ctx context.Context, recipientDID string, limit, offset int) ([]*Notification, error)
+	// MarkSeen marks the recipient's notifications with the given ids read, returning how many changed
+	MarkSeen(ctx context.Context, recipientDID string, ids []int64) (int64, error)
+}
+
+// Service lists notifications and marks them seen
+type Service interface {
+	ListNotifications(ctx context.Context, req ListNotificationsRequest) ([]*Notification, error)
+	UpdateSeen(ctx context.Context, req UpdateSeenRequest) error
+}
@@ -0,0 +1,161 @@
+package notifications
+
+import (
+	"Coves/internal/core/permalinks"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNotification_Render(t *testing.T) {
+	tests := []struct {
+		name         string
+		notification Notification
+		want         string
+	}{
+		{
+			name:         "single reply",
+			notification: Notification{Reason: ReasonReply, ReplyCount: 1, Actor: Actor{DID: "did:plc:alice", Handle: "alice.test"}},
+			want:         "alice.test replied",
+		},
+		{
+			name:         "two replies",
+			notification: Notification{Reason: ReasonReply, ReplyCount: 2, Actor: Actor{DID: "did:plc:alice", Handle: "alice.test"}},
+			want:         "alice.test and 1 other replied",
+		},
+		{
+			name:         "grouped replies",
+			notification: Notification{Reason: ReasonReply, ReplyCount: 4, Actor: Actor{DID: "did:plc:alice", Handle: "alice.test"}},
+			want:         "alice.test and 3 others replied",
+		},
+		{
+			name:         "unindexed actor falls back to DID",
+			notification: Notification{Reason: ReasonReply, ReplyCount: 1, Actor: Actor{DID: "did:plc:alice"}},
+			want:         "did:plc:alice replied",
+		},
+		{
+			name:         "mention",
+			notification: Notification{Reason: ReasonMention, Actor: Actor{DID: "did:plc:bob", Handle: "bob.test"}},
+			want:         "bob.test mentioned you",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.notification.Render()
+			if tt.notification.Text != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, tt.notification.Text)
+			}
+		})
+	}
+}
+
+// stubRepository records MarkSeen calls and serves a fixed list
+type stubRepository struct {
+	list   []*Notification
+	marked []int64
+}
+
+func (s *stubRepository) List(ctx context.Context, recipientDID string, limit, offset int) ([]*Notification, error) {
+	return s.list, nil
+}
+
+func (s *stubRepository) MarkSeen(ctx context.Context, recipientDID string, ids []int64) (int64, error) {
+	s.marked = append(s.marked, ids...)
+	return int64(len(ids)), nil
+}
+
+func TestService_ListNotificationsRendersText(t *testing.T) {
+	repo := &stubRepository{list: []*Notification{{Reason: ReasonReply, ReplyCount: 3, Actor: Actor{Handle: "alice.test"}}}}
+	service := NewNotificationService(repo, nil)
+
+	list, err := service.ListNotifications(context.Background(), ListNotificationsRequest{RecipientDID: "did:plc:me", Limit: 50})
+	if err != nil {
+		t.Fatalf("ListNotifications failed: %v", err)
+	}
+	if list[0].Text != "alice.test and 2 others replied" {
+		t.Errorf("Expected rendered text, got %q", list[0].Text)
+	}
+
+	if _, err := service.ListNotifications(context.Background(), ListNotificationsRequest{}); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized without a recipient, got %v", err)
+	}
+}
+
+// stubLinks resolves a fixed set of URIs
+type stubLinks struct {
+	paths map[string]string
+	err   error
+}
+
+func (s *stubLinks) Resolve(ctx context.Context, uri string) (*permalinks.Permalink, error) {
+	return nil, permalinks.ErrSubjectNotFound
+}
+
+func (s *stubLinks) ResolveAll(ctx context.Context, uris []string) (map[string]*permalinks.Permalink, error) {
+	resolved := make(map[string]*permalinks.Permalink)
+	for _, uri := range uris {
+		if path, ok := s.paths[uri]; ok {
+			resolved[uri] = &permalinks.Permalink{URI: uri, Path: path}
+		}
+	}
+	return resolved, s.err
+}
+
+func TestService_ListNotificationsAddsURLs(t *testing.T) {
+	const commentURI = "at://did:plc:alice/social.coves.community.comment/3kcomment"
+	repo := &stubRepository{list: []*Notification{
+		{Reason: ReasonMention, SubjectURI: commentURI},
+		{Reason: ReasonMention, SubjectURI: "at://did:plc:alice/social.coves.community.comment/3kunindexed"},
+	}}
+	links := &stubLinks{paths: map[string]string{commentURI: "/c/c-gaming.coves.local/post/3kpost/comment/3kcomment"}}
+	service := NewNotificationService(repo, links)
+
+	list, err := service.ListNotifications(context.Background(), ListNotificationsRequest{RecipientDID: "did:plc:me", Limit: 50})
+	if err != nil {
+		t.Fatalf("ListNotifications failed: %v", err)
+	}
+	if list[0].URL != "/c/c-gaming.coves.local/post/3kpost/comment/3kcomment" {
+		t.Errorf("Expected the comment's web path, got %q", list[0].URL)
+	}
+	if list[1].URL != "" {
+		t.Errorf("Expected no path for an unresolved subject, got %q", list[1].URL)
+	}
+
+	// A failed lookup leaves the paths out instead of failing the list
+	repo.list = []*Notification{{Reason: ReasonMention, SubjectURI: commentURI}}
+	links.err = errors.New("database unavailable")
+	list, err = service.ListNotifications(context.Background(), ListNotificationsRequest{RecipientDID: "did:plc:me", Limit: 50})
+	if err != nil {
+		t.Fatalf("Expected the list despite the failed lookup, got %v", err)
+	}
+	if list[0].URL != "" {
+		t.Errorf("Expected no path after a failed lookup, got %q", list[0].URL)
+	}
+}
+
+func TestService_UpdateSeenValidation(t *testing.T) {
+	repo := &stubRepository{}
+	service := NewNotificationService(repo, nil)
+	ctx := context.Background()
+
+	if err := service.UpdateSeen(ctx, UpdateSeenRequest{RecipientDID: "did:plc:me"}); !errors.Is(err, ErrNoNotifications) {
+		t.Errorf("Expected ErrNoNotifications, got %v", err)
+	}
+	if err := service.UpdateSeen(ctx, UpdateSeenRequest{RecipientDID: "did:plc:me", IDs: make([]int64, MaxUpdateSeen+1)}); !errors.Is(err, ErrTooManyNotifications) {
+		t.Errorf("Expected ErrTooManyNotifications, got %v", err)
+	}
+	if err := service.UpdateSeen(ctx, UpdateSeenRequest{IDs: []int64{1}}); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if len(repo.marked) != 0 {
+		t.Errorf("Expected no notifications marked, got %v", repo.marked)
+	}
+
+	if err := service.UpdateSeen(ctx, UpdateSeenRequest{RecipientDID: "did:plc:me", IDs: []int64{7, 9}}); err != nil {
+		t.Fatalf("UpdateSeen failed: %v", err)
+	}
+	if len(repo.marked) != 2 {
+		t.Errorf("Expected 2 notifications marked, got %v", repo.marked)
+	}
+}
@@ -0,0 +1,78 @@
+package notifications
+
+import (
+	"Coves/internal/core/permalinks"
+	"context"
+	"fmt"
+	"log"
+)
+
+type notificationService struct {
+	repo  Repository
+	links permalinks.Service
+}
+
+// NewNotificationService creates a new notification service
+// links may be nil: notifications are then listed without web paths.
+func NewNotificationService(repo Repository, links permalinks.Service) Service {
+	return &notificationService{repo: repo, links: links}
+}
+
+// ListNotifications returns a page of the recipient's notifications with display text rendered
+func (s *notificationService) ListNotifications(ctx context.Context, req ListNotificationsRequest) ([]*Notification, error) {
+	if req.RecipientDID == "" {
+		return nil, ErrUnauthorized
+	}
+
+	list, err := s.repo.List(ctx, req.RecipientDID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	for _, n := range list {
+		n.Render()
+	}
+	s.addURLs(ctx, list)
+	return list, nil
+}
+
+// addURLs sets each notification's subject web path
+// The paths are a convenience for clients, so a failed lookup leaves them out rather than
+// failing the list.
+func (s *notificationService) addURLs(ctx context.Context, list []*Notification) {
+	if s.links == nil || len(list) == 0 {
+		return
+	}
+	uris := make([]string, len(list))
+	for i, n := range list {
+		uris[i] = n.SubjectURI
+	}
+	resolved, err := s.links.ResolveAll(ctx, uris)
+	if err != nil {
+		log.Printf("WARNING: Failed to resolve notification permalinks: %v", err)
+		return
+	}
+	for _, n := range list {
+		if permalink, ok := resolved[n.SubjectURI]; ok {
+			n.URL = permalink.Path
+		}
+	}
+}
+
+// UpdateSeen marks the recipient's notifications read
+// Ids belonging to other users are ignored.
+func (s *notificationService) UpdateSeen(ctx context.Context, req UpdateSeenRequest) error {
+	if req.RecipientDID == "" {
+		return ErrUnauthorized
+	}
+	if len(req.IDs) == 0 {
+		return ErrNoNotifications
+	}
+	if len(req.IDs) > MaxUpdateSeen {
+		return ErrTooManyNotifications
+	}
+
+	if _, err := s.repo.MarkSeen(ctx, req.RecipientDID, req.IDs); err != nil {
+		return fmt.Errorf("failed to mark notifications seen: %w", err)
+	}
+	return nil
+}
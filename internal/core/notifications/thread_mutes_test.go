@@ -0,0 +1,83 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// memoryThreadMutes keeps mutes in a set keyed by user and root
+type memoryThreadMutes map[[2]string]bool
+
+func (m memoryThreadMutes) MuteThread(ctx context.Context, userDID, rootURI string) error {
+	m[[2]string{userDID, rootURI}] = true
+	return nil
+}
+
+func (m memoryThreadMutes) UnmuteThread(ctx context.Context, userDID, rootURI string) error {
+	delete(m, [2]string{userDID, rootURI})
+	return nil
+}
+
+func (m memoryThreadMutes) IsThreadMuted(ctx context.Context, userDID, rootURI string) (bool, error) {
+	return m[[2]string{userDID, rootURI}], nil
+}
+
+func TestThreadMuteService_MuteAndUnmute(t *testing.T) {
+	repo := memoryThreadMutes{}
+	service := NewThreadMuteService(repo)
+	ctx := context.Background()
+	root := "at://did:plc:community/social.coves.community.post/3kroot"
+
+	req := ThreadMuteRequest{UserDID: "did:plc:me", Root: root}
+	if err := service.MuteThread(ctx, req); err != nil {
+		t.Fatalf("MuteThread failed: %v", err)
+	}
+	if err := service.MuteThread(ctx, req); err != nil {
+		t.Fatalf("Muting twice should be a no-op, got %v", err)
+	}
+	if muted, _ := repo.IsThreadMuted(ctx, "did:plc:me", root); !muted {
+		t.Error("Expected the thread to be muted")
+	}
+	if muted, _ := repo.IsThreadMuted(ctx, "did:plc:other", root); muted {
+		t.Error("Expected the mute to apply to its user only")
+	}
+
+	if err := service.UnmuteThread(ctx, req); err != nil {
+		t.Fatalf("UnmuteThread failed: %v", err)
+	}
+	if muted, _ := repo.IsThreadMuted(ctx, "did:plc:me", root); muted {
+		t.Error("Expected the thread to be unmuted")
+	}
+}
+
+func TestThreadMuteService_Validation(t *testing.T) {
+	repo := memoryThreadMutes{}
+	service := NewThreadMuteService(repo)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  ThreadMuteRequest
+		want error
+	}{
+		{"no user", ThreadMuteRequest{Root: "at://did:plc:community/social.coves.community.post/3kroot"}, ErrUnauthorized},
+		{"not an AT-URI", ThreadMuteRequest{UserDID: "did:plc:me", Root: "https://example.com/post"}, ErrInvalidThreadRoot},
+		{"a comment", ThreadMuteRequest{UserDID: "did:plc:me", Root: "at://did:plc:alice/social.coves.community.comment/3kreply"}, ErrInvalidThreadRoot},
+		{"no rkey", ThreadMuteRequest{UserDID: "did:plc:me", Root: "at://did:plc:community/social.coves.community.post"}, ErrInvalidThreadRoot},
+		{"handle authority", ThreadMuteRequest{UserDID: "did:plc:me", Root: "at://gaming.coves.social/social.coves.community.post/3kroot"}, ErrInvalidThreadRoot},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := service.MuteThread(ctx, tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("MuteThread: expected %v, got %v", tt.want, err)
+			}
+			if err := service.UnmuteThread(ctx, tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("UnmuteThread: expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+	if len(repo) != 0 {
+		t.Errorf("Expected nothing stored, got %v", repo)
+	}
+}
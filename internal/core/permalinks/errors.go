@@ -0,0 +1,13 @@
+package permalinks
+
+import (
+	coreerrors "Coves/internal/core/errors"
+)
+
+var (
+	// ErrInvalidURI indicates the URI isn't the AT-URI of a post, comment or community
+	ErrInvalidURI = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "uri must be the AT-URI of a post, comment or community")
+
+	// ErrSubjectNotFound indicates the subject isn't indexed
+	ErrSubjectNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "subject not found")
+)
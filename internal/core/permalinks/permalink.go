@@ -0,0 +1,87 @@
+// Package permalinks maps AT-URIs of posts, comments and communities to their canonical web
+// paths, so clients and notifications can link to a subject without resolving it themselves.
+package permalinks
+
+import "context"
+
+// Subject types
+const (
+	SubjectPost      = "post"
+	SubjectComment   = "comment"
+	SubjectCommunity = "community"
+)
+
+// Permalink is the canonical web location of a subject
+// Deleted subjects, including taken-down and removed ones, keep their path so old links still
+// land on the "deleted" page.
+type Permalink struct {
+	URI     string `json:"uri"`
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// Subject is what building a permalink needs to know about an indexed subject
+type Subject struct {
+	Type            string
+	CommunityDID    string
+	CommunityHandle string // The community's current handle; "" when the community isn't indexed
+	PostRKey        string // Posts and comments; for comments, the thread's root post
+	CommentRKey     string // Comments only
+	Deleted         bool
+}
+
+// CommunityPath returns the web path of a community, addressed by handle or DID
+func CommunityPath(community string) string {
+	return "/c/" + community
+}
+
+// PostPath returns the web path of a post in a community
+func PostPath(community, postRKey string) string {
+	return CommunityPath(community) + "/post/" + postRKey
+}
+
+// CommentPath returns the web path of a comment in the thread of a post
+func CommentPath(community, postRKey, commentRKey string) string {
+	return PostPath(community, postRKey) + "/comment/" + commentRKey
+}
+
+// Build returns the permalink of subject
+// The community is addressed by its current handle, so links survive handle changes, or by
+// its DID when the community isn't indexed.
+func Build(uri string, subject *Subject) *Permalink {
+	community := subject.CommunityHandle
+	if community == "" {
+		community = subject.CommunityDID
+	}
+
+	var path string
+	switch subject.Type {
+	case SubjectComment:
+		path = CommentPath(community, subject.PostRKey, subject.CommentRKey)
+	case SubjectPost:
+		path = PostPath(community, subject.PostRKey)
+	default:
+		path = CommunityPath(community)
+	}
+	return &Permalink{URI: uri, Path: path, Type: subject.Type, Deleted: subject.Deleted}
+}
+
+// Repository looks up indexed subjects
+type Repository interface {
+	// GetSubjects returns the indexed subjects among the canonical AT-URIs, keyed by URI
+	// Deleted, taken-down and removed subjects are included as deleted; unindexed ones and
+	// those of suspended, federation-blocked or private communities are left out.
+	GetSubjects(ctx context.Context, uris []string) (map[string]*Subject, error)
+}
+
+// Service resolves AT-URIs to permalinks
+type Service interface {
+	// Resolve returns the permalink of a post, comment or community AT-URI
+	// Returns ErrInvalidURI for other URIs and ErrSubjectNotFound for unindexed subjects.
+	Resolve(ctx context.Context, uri string) (*Permalink, error)
+
+	// ResolveAll returns the permalinks of the given AT-URIs, keyed as given
+	// URIs that don't resolve are left out.
+	ResolveAll(ctx context.Context, uris []string) (map[string]*Permalink, error)
+}
@@ -0,0 +1,124 @@
+package permalinks
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		subject Subject
+		want    string
+	}{
+		{
+			name:    "post",
+			subject: Subject{Type: SubjectPost, CommunityDID: "did:plc:gaming", CommunityHandle: "c-gaming.coves.social", PostRKey: "3kpost"},
+			want:    "/c/c-gaming.coves.social/post/3kpost",
+		},
+		{
+			name:    "comment",
+			subject: Subject{Type: SubjectComment, CommunityDID: "did:plc:gaming", CommunityHandle: "c-gaming.coves.social", PostRKey: "3kpost", CommentRKey: "3kcomment"},
+			want:    "/c/c-gaming.coves.social/post/3kpost/comment/3kcomment",
+		},
+		{
+			name:    "community",
+			subject: Subject{Type: SubjectCommunity, CommunityDID: "did:plc:gaming", CommunityHandle: "c-gaming.coves.social"},
+			want:    "/c/c-gaming.coves.social",
+		},
+		{
+			name:    "unindexed community falls back to its DID",
+			subject: Subject{Type: SubjectComment, CommunityDID: "did:plc:gaming", PostRKey: "3kpost", CommentRKey: "3kcomment"},
+			want:    "/c/did:plc:gaming/post/3kpost/comment/3kcomment",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Build("at://uri", &tt.subject)
+			if got.Path != tt.want {
+				t.Errorf("Expected path %q, got %q", tt.want, got.Path)
+			}
+			if got.Type != tt.subject.Type || got.URI != "at://uri" {
+				t.Errorf("Expected type %q and the given URI, got %+v", tt.subject.Type, got)
+			}
+		})
+	}
+}
+
+func TestBuild_Deleted(t *testing.T) {
+	got := Build("at://uri", &Subject{Type: SubjectPost, CommunityHandle: "c-gaming.coves.social", PostRKey: "3kpost", Deleted: true})
+	if !got.Deleted || got.Path != "/c/c-gaming.coves.social/post/3kpost" {
+		t.Errorf("Expected the path marked deleted, got %+v", got)
+	}
+}
+
+// stubRepo serves fixed subjects and records the URIs it was asked for
+type stubRepo struct {
+	subjects map[string]*Subject
+	asked    []string
+}
+
+func (s *stubRepo) GetSubjects(ctx context.Context, uris []string) (map[string]*Subject, error) {
+	s.asked = append(s.asked, uris...)
+	found := make(map[string]*Subject)
+	for _, uri := range uris {
+		if subject, ok := s.subjects[uri]; ok {
+			found[uri] = subject
+		}
+	}
+	return found, nil
+}
+
+func TestService_Resolve(t *testing.T) {
+	repo := &stubRepo{subjects: map[string]*Subject{
+		"at://did:plc:gaming/social.coves.community.post/3kpost": {Type: SubjectPost, CommunityHandle: "c-gaming.coves.social", PostRKey: "3kpost"},
+		"at://did:plc:gaming": {Type: SubjectCommunity, CommunityHandle: "c-gaming.coves.social"},
+	}}
+	service := NewPermalinkService(repo)
+	ctx := context.Background()
+
+	got, err := service.Resolve(ctx, "AT://did:plc:gaming/social.coves.community.post/3kpost/")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if got.URI != "at://did:plc:gaming/social.coves.community.post/3kpost" {
+		t.Errorf("Expected the canonical URI, got %q", got.URI)
+	}
+
+	// A community's profile record resolves to the community
+	got, err = service.Resolve(ctx, "at://did:plc:gaming/social.coves.community.profile/self")
+	if err != nil || got.Path != "/c/c-gaming.coves.social" {
+		t.Errorf("Expected the community path, got %+v, %v", got, err)
+	}
+
+	for _, uri := range []string{"https://example.com", "at://did:plc:gaming/social.coves.community.vote/3kvote", "at://did:plc:gaming/social.coves.community.post"} {
+		if _, err := service.Resolve(ctx, uri); !errors.Is(err, ErrInvalidURI) {
+			t.Errorf("Resolve(%q): expected ErrInvalidURI, got %v", uri, err)
+		}
+	}
+	if _, err := service.Resolve(ctx, "at://did:plc:gaming/social.coves.community.post/3kmissing"); !errors.Is(err, ErrSubjectNotFound) {
+		t.Errorf("Expected ErrSubjectNotFound, got %v", err)
+	}
+}
+
+func TestService_ResolveAll(t *testing.T) {
+	const postURI = "at://did:plc:gaming/social.coves.community.post/3kpost"
+	repo := &stubRepo{subjects: map[string]*Subject{
+		postURI: {Type: SubjectPost, CommunityHandle: "c-gaming.coves.social", PostRKey: "3kpost"},
+	}}
+	service := NewPermalinkService(repo)
+
+	uris := []string{postURI, postURI, "at://did:plc:gaming/social.coves.community.post/3kpost/", "not a uri", "at://did:plc:gaming/social.coves.community.post/3kmissing"}
+	resolved, err := service.ResolveAll(context.Background(), uris)
+	if err != nil {
+		t.Fatalf("ResolveAll failed: %v", err)
+	}
+	if len(resolved) != 2 || resolved[postURI] == nil || resolved[postURI+"/"] == nil {
+		t.Errorf("Expected both spellings of the post keyed as given, got %v", resolved)
+	}
+	if len(repo.asked) != 2 {
+		t.Errorf("Expected each canonical URI looked up once, got %v", repo.asked)
+	}
+}
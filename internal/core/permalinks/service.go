@@ -0,0 +1,95 @@
+package permalinks
+
+import (
+	"Coves/internal/atproto/aturi"
+	"context"
+	"fmt"
+)
+
+const (
+	postCollection    = "social.coves.community.post"
+	commentCollection = "social.coves.community.comment"
+	profileCollection = "social.coves.community.profile"
+)
+
+type permalinkService struct {
+	repo Repository
+}
+
+// NewPermalinkService creates a new permalink service
+func NewPermalinkService(repo Repository) Service {
+	return &permalinkService{repo: repo}
+}
+
+// Resolve returns the permalink of one subject
+func (s *permalinkService) Resolve(ctx context.Context, uri string) (*Permalink, error) {
+	canonical, ok := canonicalSubjectURI(uri)
+	if !ok {
+		return nil, ErrInvalidURI
+	}
+	resolved, err := s.ResolveAll(ctx, []string{canonical})
+	if err != nil {
+		return nil, err
+	}
+	permalink, ok := resolved[canonical]
+	if !ok {
+		return nil, ErrSubjectNotFound
+	}
+	return permalink, nil
+}
+
+// ResolveAll looks up every resolvable URI in one repository call
+func (s *permalinkService) ResolveAll(ctx context.Context, uris []string) (map[string]*Permalink, error) {
+	canonicalOf := make(map[string]string, len(uris))
+	lookup := make([]string, 0, len(uris))
+	seen := make(map[string]bool, len(uris))
+	for _, uri := range uris {
+		canonical, ok := canonicalSubjectURI(uri)
+		if !ok {
+			continue
+		}
+		canonicalOf[uri] = canonical
+		if !seen[canonical] {
+			seen[canonical] = true
+			lookup = append(lookup, canonical)
+		}
+	}
+
+	resolved := make(map[string]*Permalink, len(canonicalOf))
+	if len(lookup) == 0 {
+		return resolved, nil
+	}
+	subjects, err := s.repo.GetSubjects(ctx, lookup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up permalink subjects: %w", err)
+	}
+	for uri, canonical := range canonicalOf {
+		if subject, ok := subjects[canonical]; ok {
+			resolved[uri] = Build(canonical, subject)
+		}
+	}
+	return resolved, nil
+}
+
+// canonicalSubjectURI returns the canonical form of a post, comment or community AT-URI
+// A community is addressed by its DID alone or by its profile record.
+func canonicalSubjectURI(uri string) (string, bool) {
+	parsed, err := aturi.Parse(uri)
+	if err != nil {
+		return "", false
+	}
+	switch parsed.Collection {
+	case postCollection, commentCollection:
+		if parsed.RKey == "" {
+			return "", false
+		}
+		return parsed.String(), true
+	case "", profileCollection:
+		if parsed.RKey != "" && parsed.RKey != "self" {
+			return "", false
+		}
+		return aturi.URI{Authority: parsed.Authority}.String(), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,52 @@
+package posts
+
+// AuthorBadges marks the author's role where their content appears
+// Matches social.coves.community.post.get#authorBadges
+type AuthorBadges struct {
+	IsOP        bool `json:"isOP,omitempty"`        // Comments only: the comment's author wrote the post
+	IsModerator bool `json:"isModerator,omitempty"` // The author created or moderates the community
+	IsCommunity bool `json:"isCommunity,omitempty"` // The author is the community itself
+}
+
+// NewAuthorBadges returns authorDID's badges on content in communityDID, or nil when it has none
+// opDID is the post's author for comments, "" for posts. moderators is the community's
+// moderator set (its creator included), as returned by GetModeratorsForCommunities.
+func NewAuthorBadges(authorDID, opDID, communityDID string, moderators map[string]bool) *AuthorBadges {
+	if authorDID == "" {
+		return nil
+	}
+	badges := AuthorBadges{
+		IsOP:        opDID != "" && authorDID == opDID,
+		IsModerator: moderators[authorDID],
+		IsCommunity: authorDID == communityDID,
+	}
+	if badges == (AuthorBadges{}) {
+		return nil
+	}
+	return &badges
+}
+
+// AddAuthorBadges sets the author badges on post views
+// moderators maps each community DID to its moderator set (GetModeratorsForCommunities)
+func AddAuthorBadges(views []*PostView, moderators map[string]map[string]bool) {
+	for _, view := range views {
+		if view == nil || view.Author == nil || view.Community == nil {
+			continue
+		}
+		view.Author.Badges = NewAuthorBadges(view.Author.DID, "", view.Community.DID, moderators[view.Community.DID])
+	}
+}
+
+// BadgeCommunities returns the distinct communities of the views, for the moderator lookup
+func BadgeCommunities(views []*PostView) []string {
+	seen := make(map[string]bool)
+	var communityDIDs []string
+	for _, view := range views {
+		if view == nil || view.Community == nil || seen[view.Community.DID] {
+			continue
+		}
+		seen[view.Community.DID] = true
+		communityDIDs = append(communityDIDs, view.Community.DID)
+	}
+	return communityDIDs
+}
@@ -0,0 +1,58 @@
+package posts
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuthorBadges(t *testing.T) {
+	moderators := map[string]bool{"did:plc:mod": true, "did:plc:op": true}
+
+	tests := []struct {
+		want      *AuthorBadges
+		name      string
+		authorDID string
+		opDID     string
+	}{
+		{name: "no badges", authorDID: "did:plc:someone", opDID: "did:plc:op", want: nil},
+		{name: "OP", authorDID: "did:plc:op", opDID: "did:plc:op", want: &AuthorBadges{IsOP: true, IsModerator: true}},
+		{name: "moderator", authorDID: "did:plc:mod", opDID: "did:plc:op", want: &AuthorBadges{IsModerator: true}},
+		{name: "community", authorDID: "did:plc:community", opDID: "did:plc:op", want: &AuthorBadges{IsCommunity: true}},
+		{name: "posts have no OP", authorDID: "did:plc:someone", opDID: "", want: nil},
+		{name: "empty author", authorDID: "", opDID: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewAuthorBadges(tt.authorDID, tt.opDID, "did:plc:community", moderators))
+		})
+	}
+}
+
+func TestAddAuthorBadges(t *testing.T) {
+	view := func(authorDID, communityDID string) *PostView {
+		return &PostView{
+			Author:    &AuthorView{DID: authorDID, Handle: "author.test"},
+			Community: &CommunityRef{DID: communityDID, Handle: "c-test.coves.social", Name: "test"},
+		}
+	}
+	modPost := view("did:plc:mod", "did:plc:a")
+	modElsewhere := view("did:plc:mod", "did:plc:b")
+	official := view("did:plc:b", "did:plc:b")
+	views := []*PostView{modPost, modElsewhere, official, nil}
+
+	assert.Equal(t, []string{"did:plc:a", "did:plc:b"}, BadgeCommunities(views))
+
+	AddAuthorBadges(views, map[string]map[string]bool{"did:plc:a": {"did:plc:mod": true}})
+
+	assert.Equal(t, &AuthorBadges{IsModerator: true}, modPost.Author.Badges)
+	assert.Nil(t, modElsewhere.Author.Badges, "moderators are badged in their own communities only")
+	assert.Equal(t, &AuthorBadges{IsCommunity: true}, official.Author.Badges)
+
+	body, err := json.Marshal(modPost.Author)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"did":"did:plc:mod","handle":"author.test","badges":{"isModerator":true}}`, string(body))
+}
@@ -12,7 +12,7 @@ import (
 
 // TransformBlobRefsToURLs transforms all blob references in a PostView to PDS URLs
 // This modifies the Embed field in-place, converting blob refs to direct URLs
-// The transformation only affects external embeds with thumbnail blobs
+// External embed thumbnails become URLs; image galleries get per-image URLs, alt text and dimensions
 func TransformBlobRefsToURLs(postView *PostView) {
 	if postView == nil || postView.Embed == nil {
 		return
@@ -38,11 +38,13 @@ func TransformBlobRefsToURLs(postView *PostView) {
 		return
 	}
 
-	// Only transform external embeds
-	if embedType == "social.coves.embed.external" {
+	switch embedType {
+	case "social.coves.embed.external":
 		if external, ok := embedMap["external"].(map[string]interface{}); ok {
 			transformThumbToURL(external, communityDID, pdsURL)
 		}
+	case ImagesEmbedType:
+		transformImagesToView(embedMap, communityDID, pdsURL)
 	}
 }
 
@@ -209,36 +209,30 @@ func TestTransformBlobRefsToURLs(t *testing.T) {
 		assert.Equal(t, "invalid-ref-format", thumb["ref"], "malformed ref should be unchanged")
 	})
 
-	t.Run("ignores non-external embed types", func(t *testing.T) {
+	t.Run("ignores embed types without hydration", func(t *testing.T) {
 		post := &PostView{
 			Community: &CommunityRef{
 				DID:    "did:plc:testcommunity",
 				PDSURL: "http://localhost:3001",
 			},
 			Embed: map[string]interface{}{
-				"$type": "social.coves.embed.images",
-				"images": []interface{}{
-					map[string]interface{}{
-						"image": map[string]interface{}{
-							"$type": "blob",
-							"ref": map[string]interface{}{
-								"$link": "bafyreib6tbnql2ux3whnfysbzabthaj2vvck53nimhbi5g5a7jgvgr5eqm",
-							},
-						},
+				"$type": "social.coves.embed.video",
+				"video": map[string]interface{}{
+					"$type": "blob",
+					"ref": map[string]interface{}{
+						"$link": "bafyreib6tbnql2ux3whnfysbzabthaj2vvck53nimhbi5g5a7jgvgr5eqm",
 					},
 				},
 			},
 		}
 
-		// Should not transform non-external embeds
+		// Should not transform video embeds
 		TransformBlobRefsToURLs(post)
 
-		// Verify images embed is unchanged
+		// Verify video embed is unchanged
 		embedMap := post.Embed.(map[string]interface{})
-		images := embedMap["images"].([]interface{})
-		imageObj := images[0].(map[string]interface{})
-		imageBlob := imageObj["image"].(map[string]interface{})
-		assert.Equal(t, "blob", imageBlob["$type"], "image blob should be unchanged")
+		videoBlob := embedMap["video"].(map[string]interface{})
+		assert.Equal(t, "blob", videoBlob["$type"], "video blob should be unchanged")
 	})
 }
 
@@ -0,0 +1,126 @@
+package posts
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/validation/text"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// DuplicateTitleSimilarity is the trigram similarity above which a title counts as a repost
+	// It is above pg_trgm's default similarity_threshold (0.3), so the % operator's index scan
+	// finds every candidate before similarity() narrows them down.
+	DuplicateTitleSimilarity = 0.5
+	MinDuplicateTitleLength  = 8  // Shorter titles ("Help", "Question") match too much to be a useful hint
+	MaxDuplicateMatches      = 10 // The composer shows a handful; more adds nothing
+)
+
+// Duplicate match reasons
+const (
+	DuplicateMatchURL   = "url"   // Same link after canonicalization
+	DuplicateMatchTitle = "title" // Similar title, no matching link
+)
+
+// CheckDuplicateRequest asks whether a post about to be submitted already exists in a community
+type CheckDuplicateRequest struct {
+	Community string // DID or handle of the community being posted to
+	URL       string // External link of the post; empty for text posts
+	Title     string
+	ViewerDID string
+}
+
+// DuplicateMatch is an existing post the composer warns about
+// Matches social.coves.feed.checkDuplicate#match
+type DuplicateMatch struct {
+	CreatedAt  time.Time `json:"createdAt"`
+	Title      *string   `json:"title,omitempty"`
+	URI        string    `json:"uri"`
+	Match      string    `json:"match"`           // DuplicateMatchURL or DuplicateMatchTitle
+	Score      *int      `json:"score,omitempty"` // Nil inside the community's score hiding window
+	Similarity float64   `json:"-"`               // Title similarity (0-1) for title matches; orders them
+}
+
+// DuplicateQuery is a CheckDuplicateRequest resolved for the repository
+type DuplicateQuery struct {
+	CommunityDID  string
+	LinkHash      string // Empty to skip link matching
+	Title         string // Empty to skip title matching
+	ViewerDID     string
+	MinSimilarity float64
+	Limit         int
+}
+
+// CheckDuplicate finds posts in the community sharing the link or a similar title
+// Link matches come first, then title matches by similarity. An empty result means the post is
+// clear to submit. The hint is advisory: CreatePost doesn't enforce it.
+func (s *postService) CheckDuplicate(ctx context.Context, req CheckDuplicateRequest) ([]*DuplicateMatch, error) {
+	if req.Community == "" {
+		return nil, NewValidationError("community", "community is required")
+	}
+	title := strings.TrimSpace(text.Sanitize(req.Title))
+	if req.URL == "" && title == "" {
+		return nil, NewValidationError("url", "url or title is required")
+	}
+
+	query := DuplicateQuery{
+		ViewerDID:     req.ViewerDID,
+		MinSimilarity: DuplicateTitleSimilarity,
+		Limit:         MaxDuplicateMatches,
+	}
+	if req.URL != "" {
+		linkHash, err := LinkHash(req.URL)
+		if err != nil {
+			return nil, NewValidationError("url", err.Error())
+		}
+		query.LinkHash = linkHash
+	}
+	if utf8.RuneCountInString(title) >= MinDuplicateTitleLength {
+		query.Title = title
+	}
+	if query.LinkHash == "" && query.Title == "" {
+		return []*DuplicateMatch{}, nil
+	}
+
+	communityDID, err := s.communityService.ResolveCommunityIdentifier(ctx, req.Community)
+	if err != nil {
+		if communities.IsNotFound(err) {
+			return nil, ErrCommunityNotFound
+		}
+		if communities.IsValidationError(err) {
+			return nil, NewValidationError("community", err.Error())
+		}
+		return nil, fmt.Errorf("failed to resolve community identifier: %w", err)
+	}
+	community, err := s.communityService.GetByDID(ctx, communityDID)
+	if err != nil {
+		if communities.IsNotFound(err) {
+			return nil, ErrCommunityNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch community: %w", err)
+	}
+	// Only someone who could post here learns what's been posted here
+	if err := s.checkUserCanPost(ctx, req.ViewerDID, community); err != nil {
+		return nil, err
+	}
+	query.CommunityDID = community.DID
+
+	matches, err := s.repo.FindDuplicates(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicate posts: %w", err)
+	}
+	if matches == nil {
+		matches = []*DuplicateMatch{}
+	}
+	// The hint is no way around score hiding; like the feeds, ranking isn't affected
+	now := time.Now()
+	for _, match := range matches {
+		if communities.ScoresHidden(community.ScoreHidingHours, match.CreatedAt, now) {
+			match.Score = nil
+		}
+	}
+	return matches, nil
+}
@@ -0,0 +1,117 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"Coves/internal/core/communities"
+)
+
+func TestCheckDuplicate(t *testing.T) {
+	community := &communities.Community{DID: "did:plc:community", Handle: "rust.community.coves.social", Visibility: "public"}
+
+	var got *DuplicateQuery
+	repo := &mockRepository{
+		findDuplicatesFunc: func(ctx context.Context, query DuplicateQuery) ([]*DuplicateMatch, error) {
+			got = &query
+			return nil, nil
+		},
+	}
+	service := NewPostService(repo, &mockCommunityService{community: community}, nil, nil, nil, nil, "")
+	ctx := context.Background()
+
+	t.Run("url is matched by its canonical hash", func(t *testing.T) {
+		got = nil
+		matches, err := service.CheckDuplicate(ctx, CheckDuplicateRequest{
+			Community: community.Handle,
+			URL:       "https://youtu.be/dQw4w9WgXcQ",
+			ViewerDID: "did:plc:viewer",
+		})
+		if err != nil {
+			t.Fatalf("CheckDuplicate: %v", err)
+		}
+		if matches == nil || len(matches) != 0 {
+			t.Errorf("Expected an empty, non-nil result, got %v", matches)
+		}
+		want, _ := LinkHash("https://www.youtube.com/watch?v=dQw4w9WgXcQ")
+		if got == nil || got.LinkHash != want || got.CommunityDID != community.DID || got.Title != "" {
+			t.Errorf("Expected the canonical link hash in the resolved community, got %+v", got)
+		}
+	})
+
+	t.Run("title only", func(t *testing.T) {
+		got = nil
+		if _, err := service.CheckDuplicate(ctx, CheckDuplicateRequest{Community: community.DID, Title: "  Rust 2.0 released  "}); err != nil {
+			t.Fatalf("CheckDuplicate: %v", err)
+		}
+		if got == nil || got.LinkHash != "" || got.Title != "Rust 2.0 released" || got.MinSimilarity != DuplicateTitleSimilarity {
+			t.Errorf("Expected a trimmed title match, got %+v", got)
+		}
+	})
+
+	t.Run("short title alone isn't checked", func(t *testing.T) {
+		got = nil
+		matches, err := service.CheckDuplicate(ctx, CheckDuplicateRequest{Community: community.DID, Title: "Help"})
+		if err != nil || len(matches) != 0 {
+			t.Fatalf("Expected no matches, got %v, %v", matches, err)
+		}
+		if got != nil {
+			t.Errorf("Expected no repository query, got %+v", got)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		for name, req := range map[string]CheckDuplicateRequest{
+			"no community":     {URL: "https://example.com"},
+			"no url or title":  {Community: community.DID},
+			"unparseable link": {Community: community.DID, URL: "not a url"},
+		} {
+			if _, err := service.CheckDuplicate(ctx, req); !IsValidationError(err) {
+				t.Errorf("%s: expected validation error, got %v", name, err)
+			}
+		}
+	})
+
+	t.Run("unknown community", func(t *testing.T) {
+		_, err := service.CheckDuplicate(ctx, CheckDuplicateRequest{Community: "did:plc:elsewhere", URL: "https://example.com"})
+		if !errors.Is(err, ErrCommunityNotFound) {
+			t.Errorf("Expected ErrCommunityNotFound, got %v", err)
+		}
+	})
+
+	t.Run("private community needs a subscription", func(t *testing.T) {
+		private := &communities.Community{DID: "did:plc:private", Visibility: "private"}
+		service := NewPostService(repo, &mockCommunityService{community: private}, nil, nil, nil, nil, "")
+		_, err := service.CheckDuplicate(ctx, CheckDuplicateRequest{Community: private.DID, URL: "https://example.com", ViewerDID: "did:plc:viewer"})
+		if !errors.Is(err, ErrNotAuthorized) {
+			t.Errorf("Expected ErrNotAuthorized, got %v", err)
+		}
+	})
+}
+
+func TestCheckDuplicate_HidesScoresInWindow(t *testing.T) {
+	community := &communities.Community{DID: "did:plc:community", Visibility: "public", ScoreHidingHours: 6}
+	score := func(n int) *int { return &n }
+	repo := &mockRepository{
+		findDuplicatesFunc: func(ctx context.Context, query DuplicateQuery) ([]*DuplicateMatch, error) {
+			return []*DuplicateMatch{
+				{URI: "at://did:plc:community/social.coves.community.post/new", Match: DuplicateMatchURL, Score: score(5), CreatedAt: time.Now().Add(-time.Hour)},
+				{URI: "at://did:plc:community/social.coves.community.post/old", Match: DuplicateMatchTitle, Score: score(9), CreatedAt: time.Now().Add(-72 * time.Hour)},
+			}, nil
+		},
+	}
+	service := NewPostService(repo, &mockCommunityService{community: community}, nil, nil, nil, nil, "")
+
+	matches, err := service.CheckDuplicate(context.Background(), CheckDuplicateRequest{Community: community.DID, URL: "https://example.com/story"})
+	if err != nil {
+		t.Fatalf("CheckDuplicate: %v", err)
+	}
+	if matches[0].Score != nil {
+		t.Errorf("Expected the new post's score to be hidden, got %d", *matches[0].Score)
+	}
+	if matches[1].Score == nil || *matches[1].Score != 9 {
+		t.Errorf("Expected the old post's score, got %v", matches[1].Score)
+	}
+}
@@ -3,34 +3,48 @@ package posts
 import (
 	"errors"
 	"fmt"
+
+	coreerrors "Coves/internal/core/errors"
 )
 
 // Sentinel errors for common post operations
 var (
 	// ErrCommunityNotFound is returned when the community doesn't exist in AppView
-	ErrCommunityNotFound = errors.New("community not found")
+	ErrCommunityNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "community not found")
+
+	// ErrCommunitySuspended is returned when an admin has suspended the community
+	ErrCommunitySuspended = coreerrors.Sentinel(coreerrors.ErrForbidden, "community has been suspended")
+
+	// ErrCommunityDeleted is returned when the community has been deleted
+	ErrCommunityDeleted = coreerrors.Sentinel(coreerrors.ErrNotFound, "community has been deleted")
+
+	// ErrCommunityFederationBlocked is returned when the community's hosting instance is blocked
+	ErrCommunityFederationBlocked = coreerrors.Sentinel(coreerrors.ErrForbidden, "community is hosted on a blocked instance")
 
 	// ErrNotAuthorized is returned when user isn't authorized to post in community
 	// (e.g., banned, private community without membership - Beta)
-	ErrNotAuthorized = errors.New("user not authorized to post in this community")
+	ErrNotAuthorized = coreerrors.Sentinel(coreerrors.ErrForbidden, "user not authorized to post in this community")
 
 	// ErrBanned is returned when user is banned from community (Beta)
-	ErrBanned = errors.New("user is banned from this community")
+	ErrBanned = coreerrors.Sentinel(coreerrors.ErrForbidden, "user is banned from this community")
 
 	// ErrInvalidContent is returned for general content violations
-	ErrInvalidContent = errors.New("invalid post content")
+	ErrInvalidContent = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid post content")
 
 	// ErrNotFound is returned when a post is not found by URI
-	ErrNotFound = errors.New("post not found")
+	ErrNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "post not found")
 
 	// ErrRateLimitExceeded is returned when an aggregator exceeds rate limits
 	ErrRateLimitExceeded = errors.New("rate limit exceeded")
 
 	// ErrInvalidCursor is returned when a pagination cursor is malformed
-	ErrInvalidCursor = errors.New("invalid pagination cursor")
+	ErrInvalidCursor = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid pagination cursor")
 
 	// ErrActorNotFound is returned when the requested actor does not exist
-	ErrActorNotFound = errors.New("actor not found")
+	ErrActorNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "actor not found")
+
+	// ErrAlreadyIndexed is returned when indexing a post whose URI is already indexed
+	ErrAlreadyIndexed = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "post already indexed")
 )
 
 // ValidationError represents a validation error with field context
@@ -100,33 +114,21 @@ func NewNotFoundError(resource, id string) error {
 	}
 }
 
+// Is makes NotFoundError match coreerrors.ErrNotFound
+func (e *NotFoundError) Is(target error) bool {
+	return target == coreerrors.ErrNotFound
+}
+
 // IsNotFound checks if error is a not found error
 func IsNotFound(err error) bool {
 	var notFoundErr *NotFoundError
-	return errors.As(err, &notFoundErr) || err == ErrCommunityNotFound || err == ErrNotFound
+	return errors.As(err, &notFoundErr) ||
+		errors.Is(err, ErrCommunityNotFound) ||
+		errors.Is(err, ErrNotFound) ||
+		errors.Is(err, ErrActorNotFound)
 }
 
 // IsConflict checks if error is due to duplicate/conflict
 func IsConflict(err error) bool {
-	if err == nil {
-		return false
-	}
-	// Check for common conflict indicators in error message
-	errStr := err.Error()
-	return contains(errStr, "already indexed") ||
-		contains(errStr, "duplicate key") ||
-		contains(errStr, "already exists")
-}
-
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && anySubstring(s, substr)
-}
-
-func anySubstring(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
+	return errors.Is(err, ErrAlreadyIndexed)
 }
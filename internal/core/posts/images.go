@@ -0,0 +1,115 @@
+package posts
+
+import (
+	"strings"
+
+	"Coves/internal/core/blobs"
+	"Coves/internal/core/communities"
+)
+
+// ImagesEmbedType is the $type of image gallery embeds
+const ImagesEmbedType = "social.coves.embed.images"
+
+// embedImages returns the image entries of a social.coves.embed.images embed, or nil
+// for any other embed type
+func embedImages(embed map[string]interface{}) []map[string]interface{} {
+	if embed == nil {
+		return nil
+	}
+	if embedType, _ := embed["$type"].(string); embedType != ImagesEmbedType {
+		return nil
+	}
+	items, ok := embed["images"].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	images := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		if image, ok := item.(map[string]interface{}); ok {
+			images = append(images, image)
+		}
+	}
+	return images
+}
+
+// CountImagesMissingAlt returns how many images in an images embed have no alt text
+// Whitespace-only alt text counts as missing. Returns 0 for other embed types.
+func CountImagesMissingAlt(embed map[string]interface{}) int {
+	missing := 0
+	for _, image := range embedImages(embed) {
+		alt, _ := image["alt"].(string)
+		if strings.TrimSpace(alt) == "" {
+			missing++
+		}
+	}
+	return missing
+}
+
+// NormalizeImageAlt trims each image's alt text and stores an explicit empty string
+// when there is none, so indexed embeds always carry alt alongside the image ref
+func NormalizeImageAlt(embed map[string]interface{}) {
+	for _, image := range embedImages(embed) {
+		alt, _ := image["alt"].(string)
+		image["alt"] = strings.TrimSpace(alt)
+	}
+}
+
+// transformImagesToView hydrates an images embed in-place for post views
+// Each image's blob ref becomes a full-size URL, plus a preview-size thumb URL, its alt text,
+// and width/height from aspectRatio so clients can reserve layout space
+func transformImagesToView(embed map[string]interface{}, communityDID, pdsURL string) {
+	config := communities.GetImageProxyConfig()
+	for _, image := range embedImages(embed) {
+		if cid := blobRefCID(image["image"]); cid != "" {
+			image["image"] = blobs.HydrateImageURL(config, pdsURL, communityDID, cid, "content_full")
+			image["thumb"] = blobs.HydrateImageURL(config, pdsURL, communityDID, cid, "content_preview")
+		}
+
+		alt, _ := image["alt"].(string)
+		image["alt"] = alt
+
+		if aspectRatio, ok := image["aspectRatio"].(map[string]interface{}); ok {
+			width, widthOK := positiveInt(aspectRatio["width"])
+			height, heightOK := positiveInt(aspectRatio["height"])
+			if widthOK && heightOK {
+				image["width"] = width
+				image["height"] = height
+			}
+		}
+	}
+}
+
+// blobRefCID extracts the CID from a blob ref ({"ref": {"$link": cid}}), or "" when the
+// value isn't a blob ref (e.g. an already-hydrated URL)
+func blobRefCID(value interface{}) string {
+	blob, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	ref, ok := blob["ref"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	cid, _ := ref["$link"].(string)
+	return cid
+}
+
+// positiveInt reads a JSON number as a positive int
+func positiveInt(value interface{}) (int, bool) {
+	switch n := value.(type) {
+	case float64:
+		if n >= 1 && n == float64(int(n)) {
+			return int(n), true
+		}
+	case int:
+		if n >= 1 {
+			return n, true
+		}
+	case int64:
+		if n >= 1 {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,122 @@
+package posts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(cid, alt string, aspectRatio map[string]interface{}) map[string]interface{} {
+	image := map[string]interface{}{
+		"image": map[string]interface{}{
+			"$type":    "blob",
+			"ref":      map[string]interface{}{"$link": cid},
+			"mimeType": "image/jpeg",
+			"size":     1000,
+		},
+	}
+	if alt != "" {
+		image["alt"] = alt
+	}
+	if aspectRatio != nil {
+		image["aspectRatio"] = aspectRatio
+	}
+	return image
+}
+
+func TestCountImagesMissingAlt(t *testing.T) {
+	tests := []struct {
+		embed map[string]interface{}
+		name  string
+		want  int
+	}{
+		{name: "nil embed", embed: nil, want: 0},
+		{
+			name: "external embed is ignored",
+			embed: map[string]interface{}{
+				"$type":    "social.coves.embed.external",
+				"external": map[string]interface{}{"uri": "https://example.com"},
+			},
+			want: 0,
+		},
+		{
+			name: "all images have alt",
+			embed: map[string]interface{}{
+				"$type":  ImagesEmbedType,
+				"images": []interface{}{testImage("bafy1", "A cat", nil), testImage("bafy2", "A dog", nil)},
+			},
+			want: 0,
+		},
+		{
+			name: "missing and whitespace-only alt",
+			embed: map[string]interface{}{
+				"$type":  ImagesEmbedType,
+				"images": []interface{}{testImage("bafy1", "A cat", nil), testImage("bafy2", "", nil), testImage("bafy3", "   ", nil)},
+			},
+			want: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CountImagesMissingAlt(tt.embed))
+		})
+	}
+}
+
+func TestNormalizeImageAlt(t *testing.T) {
+	embed := map[string]interface{}{
+		"$type":  ImagesEmbedType,
+		"images": []interface{}{testImage("bafy1", "  A cat  ", nil), testImage("bafy2", "", nil)},
+	}
+
+	NormalizeImageAlt(embed)
+
+	images := embed["images"].([]interface{})
+	assert.Equal(t, "A cat", images[0].(map[string]interface{})["alt"])
+	alt, ok := images[1].(map[string]interface{})["alt"]
+	require.True(t, ok, "missing alt should be stored as an empty string")
+	assert.Equal(t, "", alt)
+}
+
+func TestTransformBlobRefsToURLs_ImageGallery(t *testing.T) {
+	post := &PostView{
+		Community: &CommunityRef{
+			DID:    "did:plc:testcommunity",
+			PDSURL: "http://localhost:3001",
+		},
+		Embed: map[string]interface{}{
+			"$type": ImagesEmbedType,
+			"images": []interface{}{
+				// aspectRatio values arrive as float64 after JSON decoding
+				testImage("bafyone", "A red bicycle", map[string]interface{}{"width": float64(1600), "height": float64(900)}),
+				testImage("bafytwo", "", nil),
+			},
+		},
+	}
+
+	TransformBlobRefsToURLs(post)
+
+	images := post.Embed.(map[string]interface{})["images"].([]interface{})
+	require.Len(t, images, 2)
+
+	first := images[0].(map[string]interface{})
+	assert.Equal(t, "http://localhost:3001/xrpc/com.atproto.sync.getBlob?did=did%3Aplc%3Atestcommunity&cid=bafyone", first["image"])
+	assert.Equal(t, first["image"], first["thumb"], "without the image proxy both sizes use the PDS blob URL")
+	assert.Equal(t, "A red bicycle", first["alt"])
+	assert.Equal(t, 1600, first["width"])
+	assert.Equal(t, 900, first["height"])
+
+	second := images[1].(map[string]interface{})
+	assert.Equal(t, "http://localhost:3001/xrpc/com.atproto.sync.getBlob?did=did%3Aplc%3Atestcommunity&cid=bafytwo", second["image"])
+	assert.Equal(t, "", second["alt"], "views always carry alt, even when empty")
+	_, hasWidth := second["width"]
+	assert.False(t, hasWidth, "no dimensions without aspectRatio")
+
+	t.Run("idempotent on hydrated views", func(t *testing.T) {
+		TransformBlobRefsToURLs(post)
+		again := post.Embed.(map[string]interface{})["images"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, first["image"], again["image"])
+	})
+}
@@ -28,8 +28,16 @@ type Service interface {
 	// Flow: Validate URI -> Fetch community -> Verify author -> Delete from PDS
 	DeletePost(ctx context.Context, session *oauth.ClientSessionData, req DeletePostRequest) error
 
+	// GetPost retrieves a single post by AT-URI for its permalink page
+	// Taken-down posts return a takedown.TakenDownError; posts in suspended, deleted or
+	// federation-blocked communities return the matching community error
+	GetPost(ctx context.Context, req GetPostRequest) (*PostView, error)
+
+	// CheckDuplicate finds posts in a community with the same link or a similar title, for the
+	// composer's "already posted here" hint
+	CheckDuplicate(ctx context.Context, req CheckDuplicateRequest) ([]*DuplicateMatch, error)
+
 	// Future methods (Beta):
-	// GetPost(ctx context.Context, uri string, viewerDID *string) (*Post, error)
 	// UpdatePost(ctx context.Context, req UpdatePostRequest) (*Post, error)
 	// ListCommunityPosts(ctx context.Context, communityDID string, limit, offset int) ([]*Post, error)
 }
@@ -41,8 +49,8 @@ type Repository interface {
 	// Called by Jetstream consumer after post is created on PDS
 	Create(ctx context.Context, post *Post) error
 
-	// GetByURI retrieves a post by its AT-URI
-	// Used for E2E test verification and future GET endpoint
+	// GetByURI retrieves a non-deleted post by its AT-URI
+	// Returns ErrNotFound for soft-deleted posts
 	GetByURI(ctx context.Context, uri string) (*Post, error)
 
 	// GetByAuthor retrieves posts authored by a specific user
@@ -50,11 +58,20 @@ type Repository interface {
 	// Returns posts, cursor for pagination, and error
 	GetByAuthor(ctx context.Context, req GetAuthorPostsRequest) ([]*PostView, *string, error)
 
+	// GetViewByURI retrieves a single hydrated post view by AT-URI
+	// Applies the viewer's visibility rules; returns ErrNotFound for deleted, removed or
+	// taken-down posts. The community's state is left to the caller.
+	GetViewByURI(ctx context.Context, uri, viewerDID string) (*PostView, error)
+
 	// SoftDelete marks a post as deleted in the AppView database
 	// Called by Jetstream consumer after post is deleted from PDS
 	// Idempotent: Returns success if post already deleted
 	SoftDelete(ctx context.Context, uri string) error
 
+	// FindDuplicates returns the community's live posts matching the query's link hash, or
+	// whose title's trigram similarity to the query's title reaches MinSimilarity
+	FindDuplicates(ctx context.Context, query DuplicateQuery) ([]*DuplicateMatch, error)
+
 	// Future methods (Beta):
 	// Update(ctx context.Context, post *Post) error
 	// List(ctx context.Context, communityDID string, limit, offset int) ([]*Post, int, error)
@@ -0,0 +1,140 @@
+package posts
+
+import (
+	"slices"
+)
+
+// Content labels a post can carry: self-applied in its record, inherited from its community's
+// content warnings, or set by a moderator
+const (
+	LabelNSFW     = "nsfw"
+	LabelSpoiler  = "spoiler"
+	LabelViolence = "violence"
+	LabelGore     = "gore"
+)
+
+// Blur levels clients apply to labeled posts
+const (
+	BlurContent = "content" // Hide the whole post behind a warning
+	BlurMedia   = "media"   // Blur images and embeds
+)
+
+// allowedLabels are the label values posts are indexed with; others are dropped
+var allowedLabels = map[string]string{
+	LabelNSFW:     BlurMedia,
+	LabelSpoiler:  BlurContent,
+	LabelViolence: BlurMedia,
+	LabelGore:     BlurMedia,
+}
+
+// AdultLabels hide a post from feeds unless the viewer opts in with includeNsfw
+var AdultLabels = []string{LabelNSFW, LabelGore}
+
+// communityWarningLabels maps community content warnings to the post labels they imply
+// Communities warn about "spoilers"; posts are labeled "spoiler"
+var communityWarningLabels = map[string]string{
+	LabelNSFW:     LabelNSFW,
+	"spoilers":    LabelSpoiler,
+	LabelViolence: LabelViolence,
+	LabelGore:     LabelGore,
+}
+
+// IsAllowedLabel reports whether label is a known post label value
+func IsAllowedLabel(label string) bool {
+	_, ok := allowedLabels[label]
+	return ok
+}
+
+// IndexedLabels returns the allowed, non-negated label values of a record's self-labels,
+// sorted and without duplicates
+func IndexedLabels(selfLabels *SelfLabels) []string {
+	labels := []string{}
+	if selfLabels == nil {
+		return labels
+	}
+	for _, label := range selfLabels.Values {
+		if IsAllowedLabel(label.Val) && (label.Neg == nil || !*label.Neg) {
+			labels = append(labels, label.Val)
+		}
+	}
+	slices.Sort(labels)
+	return slices.Compact(labels)
+}
+
+// CommunityLabels returns the post labels a community's content warnings imply
+func CommunityLabels(contentWarnings []string) []string {
+	var labels []string
+	for _, warning := range contentWarnings {
+		if label, ok := communityWarningLabels[warning]; ok {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+// CommunityWarningsFor returns the community content warnings that imply label, sorted
+func CommunityWarningsFor(label string) []string {
+	var warnings []string
+	for warning, implied := range communityWarningLabels {
+		if implied == label {
+			warnings = append(warnings, warning)
+		}
+	}
+	slices.Sort(warnings)
+	return warnings
+}
+
+// LabelOverrides are the labels moderators applied to or removed from a post
+type LabelOverrides struct {
+	Applied []string
+	Removed []string
+}
+
+// EffectiveLabels merges a post's labels: its community's content warnings plus its own
+// self-labels, then moderator overrides, which take precedence over both
+// The result is sorted and without duplicates.
+func EffectiveLabels(communityWarnings, postLabels []string, overrides LabelOverrides) []string {
+	labels := append(CommunityLabels(communityWarnings), postLabels...)
+	labels = append(labels, overrides.Applied...)
+	labels = slices.DeleteFunc(labels, func(label string) bool {
+		return !IsAllowedLabel(label) || slices.Contains(overrides.Removed, label)
+	})
+	slices.Sort(labels)
+	return slices.Compact(labels)
+}
+
+// HasAdultLabel reports whether labels include one of AdultLabels
+func HasAdultLabel(labels []string) bool {
+	for _, label := range AdultLabels {
+		if slices.Contains(labels, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// LabelsView is a post's effective labels and how clients should blur it
+// Matches social.coves.community.post.get#labelsView
+type LabelsView struct {
+	Blur   string   `json:"blur,omitempty"` // BlurContent or BlurMedia; the strongest of the labels
+	Values []string `json:"values"`
+}
+
+// NewLabelsView returns the view of a post's effective labels, or nil when it has none
+func NewLabelsView(effective []string) *LabelsView {
+	if len(effective) == 0 {
+		return nil
+	}
+	view := &LabelsView{Values: effective}
+	for _, label := range effective {
+		switch allowedLabels[label] {
+		case BlurContent:
+			view.Blur = BlurContent
+		case BlurMedia:
+			if view.Blur == "" {
+				view.Blur = BlurMedia
+			}
+		}
+	}
+	return view
+}
@@ -0,0 +1,66 @@
+package posts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexedLabels(t *testing.T) {
+	neg := true
+	selfLabels := &SelfLabels{Values: []SelfLabel{
+		{Val: "spoiler"},
+		{Val: "nsfw"},
+		{Val: "porn"},            // unknown values aren't indexed
+		{Val: "gore", Neg: &neg}, // negated values aren't either
+		{Val: "spoiler"},         // duplicates collapse
+	}}
+
+	assert.Equal(t, []string{"nsfw", "spoiler"}, IndexedLabels(selfLabels))
+	assert.Equal(t, []string{}, IndexedLabels(nil))
+}
+
+func TestEffectiveLabels(t *testing.T) {
+	tests := []struct {
+		name      string
+		warnings  []string
+		post      []string
+		overrides LabelOverrides
+		want      []string
+	}{
+		{name: "no labels", want: []string{}},
+		{name: "self-labels only", post: []string{"spoiler"}, want: []string{"spoiler"}},
+		{name: "community NSFW applies to every post", warnings: []string{"nsfw"}, want: []string{"nsfw"}},
+		{name: "union of community and post", warnings: []string{"nsfw", "violence"}, post: []string{"spoiler", "nsfw"}, want: []string{"nsfw", "spoiler", "violence"}},
+		{name: "community spoilers warning implies spoiler label", warnings: []string{"spoilers"}, want: []string{"spoiler"}},
+		{name: "unknown community warnings are ignored", warnings: []string{"politics"}, want: []string{}},
+		{name: "moderator applies a label", post: []string{"spoiler"}, overrides: LabelOverrides{Applied: []string{"gore"}}, want: []string{"gore", "spoiler"}},
+		{name: "moderator removal beats self-label", post: []string{"nsfw", "spoiler"}, overrides: LabelOverrides{Removed: []string{"nsfw"}}, want: []string{"spoiler"}},
+		{name: "moderator removal beats community warning", warnings: []string{"nsfw"}, overrides: LabelOverrides{Removed: []string{"nsfw"}}, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EffectiveLabels(tt.warnings, tt.post, tt.overrides)
+			if len(tt.want) == 0 {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestHasAdultLabel(t *testing.T) {
+	assert.True(t, HasAdultLabel([]string{"nsfw"}))
+	assert.True(t, HasAdultLabel([]string{"gore", "spoiler"}))
+	assert.False(t, HasAdultLabel([]string{"spoiler", "violence"}))
+	assert.False(t, HasAdultLabel(nil))
+}
+
+func TestNewLabelsView(t *testing.T) {
+	assert.Nil(t, NewLabelsView(nil))
+	assert.Equal(t, &LabelsView{Blur: BlurMedia, Values: []string{"nsfw"}}, NewLabelsView([]string{"nsfw"}))
+	// Spoilers hide the whole post, which covers blurring its media too
+	assert.Equal(t, &LabelsView{Blur: BlurContent, Values: []string{"nsfw", "spoiler"}}, NewLabelsView([]string{"nsfw", "spoiler"}))
+}
@@ -0,0 +1,192 @@
+package posts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+// ExternalEmbedType is the $type of link embeds
+const ExternalEmbedType = "social.coves.embed.external"
+
+// ErrInvalidLinkURL is returned for URLs that can't be canonicalized (not absolute http(s))
+var ErrInvalidLinkURL = errors.New("URL must be an absolute http or https URL")
+
+// trackingParams are query parameters that never change what a link points to
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "yclid": true,
+	"igshid": true, "mc_cid": true, "mc_eid": true, "ref_src": true, "ref_url": true,
+	"_ga": true, "amp": true, "outputtype": true,
+}
+
+// youtubeHosts are the hosts serving youtube.com/watch?v= videos
+var youtubeHosts = map[string]bool{
+	"youtube.com": true, "music.youtube.com": true, "youtube-nocookie.com": true,
+}
+
+// CanonicalizeURL normalizes a link so different spellings of the same page compare equal
+//   - http and https are the same page; scheme and host are lowercased and "www."/"m."/"amp."
+//     host prefixes, default ports, fragments, trailing slashes and tracking parameters
+//     (utm_*, fbclid, ...) are dropped; the remaining query parameters are sorted
+//   - YouTube links (youtu.be/ID, /shorts/ID, /embed/ID, m.youtube.com) become
+//     youtube.com/watch?v=ID with every other parameter (timestamps, playlists) dropped
+//   - AMP links (google.com/amp/s/..., *.cdn.ampproject.org/c/s/..., trailing /amp) map
+//     to the canonical page
+//
+// The result is what link hashes are computed from, at index time and at lookup time
+func CanonicalizeURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", ErrInvalidLinkURL
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if (scheme != "http" && scheme != "https") || u.Hostname() == "" {
+		return "", ErrInvalidLinkURL
+	}
+
+	host := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	port := u.Port()
+	if port == "80" || port == "443" {
+		port = ""
+	}
+
+	// AMP caches wrap the real URL in their path; unwrap and start over
+	if inner, ok := unwrapAMPCache(host, u); ok {
+		return CanonicalizeURL(inner)
+	}
+
+	for _, prefix := range []string{"www.", "m.", "amp."} {
+		if trimmed := strings.TrimPrefix(host, prefix); trimmed != host && strings.Contains(trimmed, ".") {
+			host = trimmed
+			break
+		}
+	}
+
+	path := u.EscapedPath()
+	query := u.Query()
+
+	if videoID := youtubeVideoID(host, path, query); videoID != "" {
+		return "https://youtube.com/watch?v=" + url.QueryEscape(videoID), nil
+	}
+
+	// Publisher AMP pages live at .../amp or .../amp.html next to the canonical page
+	for _, suffix := range []string{"/amp/", "/amp", "/amp.html"} {
+		if strings.HasSuffix(path, suffix) {
+			path = strings.TrimSuffix(path, suffix)
+			break
+		}
+	}
+	path = strings.TrimRight(path, "/")
+
+	for name := range query {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(name)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("https://")
+	b.WriteString(host)
+	if port != "" {
+		b.WriteString(":" + port)
+	}
+	b.WriteString(path)
+	if len(query) > 0 {
+		b.WriteString("?" + query.Encode()) // Encode sorts by name
+	}
+	return b.String(), nil
+}
+
+// LinkHash returns the hash of a link's canonical form, used to find posts about the same page
+func LinkHash(raw string) (string, error) {
+	canonical, err := CanonicalizeURL(raw)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(canonical))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ExternalEmbedURI returns the link of a social.coves.embed.external embed, or "" for any
+// other embed type
+func ExternalEmbedURI(embed map[string]interface{}) string {
+	if embed == nil {
+		return ""
+	}
+	if embedType, _ := embed["$type"].(string); embedType != ExternalEmbedType {
+		return ""
+	}
+	external, ok := embed["external"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	uri, _ := external["uri"].(string)
+	return uri
+}
+
+// unwrapAMPCache returns the page URL wrapped by a Google AMP cache URL
+// (google.com/amp/s/example.com/a, example-com.cdn.ampproject.org/c/s/example.com/a)
+func unwrapAMPCache(host string, u *url.URL) (string, bool) {
+	var rest string
+	switch {
+	case (host == "google.com" || host == "www.google.com") && strings.HasPrefix(u.Path, "/amp/"):
+		rest = strings.TrimPrefix(u.Path, "/amp/")
+	case strings.HasSuffix(host, ".cdn.ampproject.org") || host == "cdn.ampproject.org":
+		// /c/ is a document, /v/ a viewer; a following s/ means the page is served over https
+		for _, prefix := range []string{"/c/", "/v/"} {
+			if strings.HasPrefix(u.Path, prefix) {
+				rest = strings.TrimPrefix(u.Path, prefix)
+			}
+		}
+	default:
+		return "", false
+	}
+	if rest == "" {
+		return "", false
+	}
+
+	scheme := "http://"
+	if strings.HasPrefix(rest, "s/") {
+		scheme = "https://"
+		rest = strings.TrimPrefix(rest, "s/")
+	}
+	inner := scheme + rest
+	if u.RawQuery != "" {
+		inner += "?" + u.RawQuery
+	}
+	return inner, true
+}
+
+// youtubeVideoID returns the video ID of a YouTube video link, or ""
+func youtubeVideoID(host, path string, query url.Values) string {
+	if host == "youtu.be" {
+		return firstPathSegment(path)
+	}
+	if !youtubeHosts[host] {
+		return ""
+	}
+	switch {
+	case path == "/watch":
+		return query.Get("v")
+	case strings.HasPrefix(path, "/shorts/"):
+		return firstPathSegment(strings.TrimPrefix(path, "/shorts"))
+	case strings.HasPrefix(path, "/embed/"):
+		return firstPathSegment(strings.TrimPrefix(path, "/embed"))
+	case strings.HasPrefix(path, "/live/"):
+		return firstPathSegment(strings.TrimPrefix(path, "/live"))
+	}
+	return ""
+}
+
+// firstPathSegment returns the first segment of an escaped path ("/abc/def" -> "abc")
+func firstPathSegment(path string) string {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	unescaped, err := url.PathUnescape(segment)
+	if err != nil {
+		return ""
+	}
+	return unescaped
+}
@@ -0,0 +1,76 @@
+package posts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalizeURL(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "scheme, host case and www", input: "HTTP://WWW.Example.com/Article", want: "https://example.com/Article"},
+		{name: "fragment and trailing slash", input: "https://example.com/article/#comments", want: "https://example.com/article"},
+		{name: "bare domain", input: "https://example.com/", want: "https://example.com"},
+		{name: "default port", input: "https://example.com:443/a", want: "https://example.com/a"},
+		{name: "custom port kept", input: "http://example.com:8080/a", want: "https://example.com:8080/a"},
+		{name: "tracking params dropped, others sorted", input: "https://example.com/a?utm_source=x&b=2&fbclid=abc&a=1", want: "https://example.com/a?a=1&b=2"},
+		{name: "mobile subdomain", input: "https://m.example.com/a", want: "https://example.com/a"},
+		{name: "short host is not stripped", input: "https://m.com/a", want: "https://m.com/a"},
+
+		{name: "youtube watch", input: "https://www.youtube.com/watch?v=dQw4w9WgXcQ&t=42s&list=PL1", want: "https://youtube.com/watch?v=dQw4w9WgXcQ"},
+		{name: "youtu.be", input: "https://youtu.be/dQw4w9WgXcQ?si=share", want: "https://youtube.com/watch?v=dQw4w9WgXcQ"},
+		{name: "youtube mobile", input: "https://m.youtube.com/watch?v=dQw4w9WgXcQ", want: "https://youtube.com/watch?v=dQw4w9WgXcQ"},
+		{name: "youtube shorts", input: "https://youtube.com/shorts/dQw4w9WgXcQ", want: "https://youtube.com/watch?v=dQw4w9WgXcQ"},
+		{name: "youtube embed", input: "https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ?start=10", want: "https://youtube.com/watch?v=dQw4w9WgXcQ"},
+		{name: "youtube channel is a normal page", input: "https://www.youtube.com/@channel/", want: "https://youtube.com/@channel"},
+
+		{name: "google amp viewer", input: "https://www.google.com/amp/s/www.example.com/news/story.amp.html", want: "https://example.com/news/story.amp.html"},
+		{name: "google amp viewer with amp path", input: "https://www.google.com/amp/s/example.com/news/story/amp/", want: "https://example.com/news/story"},
+		{name: "amp cache", input: "https://example-com.cdn.ampproject.org/c/s/example.com/news/story?amp=1", want: "https://example.com/news/story"},
+		{name: "amp subdomain", input: "https://amp.example.com/news/story", want: "https://example.com/news/story"},
+		{name: "trailing amp path", input: "https://example.com/news/story/amp", want: "https://example.com/news/story"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalizeURL(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCanonicalizeURL_Invalid(t *testing.T) {
+	for _, input := range []string{"", "example.com/a", "ftp://example.com/a", "javascript:alert(1)", "https://"} {
+		_, err := CanonicalizeURL(input)
+		assert.ErrorIs(t, err, ErrInvalidLinkURL, "input %q", input)
+	}
+}
+
+func TestLinkHash_SamePageSameHash(t *testing.T) {
+	a, err := LinkHash("https://youtu.be/dQw4w9WgXcQ")
+	require.NoError(t, err)
+	b, err := LinkHash("http://www.youtube.com/watch?v=dQw4w9WgXcQ#t=1")
+	require.NoError(t, err)
+	c, err := LinkHash("https://youtube.com/watch?v=other")
+	require.NoError(t, err)
+
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+	assert.Len(t, a, 64)
+}
+
+func TestExternalEmbedURI(t *testing.T) {
+	external := map[string]interface{}{
+		"$type":    ExternalEmbedType,
+		"external": map[string]interface{}{"uri": "https://example.com/a"},
+	}
+	assert.Equal(t, "https://example.com/a", ExternalEmbedURI(external))
+	assert.Empty(t, ExternalEmbedURI(map[string]interface{}{"$type": ImagesEmbedType}))
+	assert.Empty(t, ExternalEmbedURI(nil))
+}
@@ -1,6 +1,7 @@
 package posts
 
 import (
+	"Coves/internal/core/aggregators"
 	"time"
 )
 
@@ -17,28 +18,49 @@ type SelfLabel struct {
 	Val string `json:"val"`
 }
 
+// Post statuses
+const (
+	StatusActive      = "active"
+	StatusQuarantined = "quarantined" // Held for spam review at index time; hidden until released
+	StatusSpam        = "spam"        // Confirmed as spam by an admin; hidden
+	StatusHeld        = "held"        // Held by automod for moderator review; hidden until approved
+	StatusRemoved     = "removed"     // Removed by automod or on review; hidden
+)
+
 // Post represents a post in the AppView database
 // Posts are indexed from the firehose after being written to community repositories
 type Post struct {
-	CreatedAt     time.Time  `json:"createdAt" db:"created_at"`
-	IndexedAt     time.Time  `json:"indexedAt" db:"indexed_at"`
-	EditedAt      *time.Time `json:"editedAt,omitempty" db:"edited_at"`
-	Embed         *string    `json:"embed,omitempty" db:"embed"`
-	DeletedAt     *time.Time `json:"deletedAt,omitempty" db:"deleted_at"`
-	ContentLabels *string    `json:"labels,omitempty" db:"content_labels"`
-	Title         *string    `json:"title,omitempty" db:"title"`
-	Content       *string    `json:"content,omitempty" db:"content"`
-	ContentFacets *string    `json:"contentFacets,omitempty" db:"content_facets"`
-	CID           string     `json:"cid" db:"cid"`
-	CommunityDID  string     `json:"communityDid" db:"community_did"`
-	RKey          string     `json:"rkey" db:"rkey"`
-	URI           string     `json:"uri" db:"uri"`
-	AuthorDID     string     `json:"authorDid" db:"author_did"`
-	ID            int64      `json:"id" db:"id"`
-	UpvoteCount   int        `json:"upvoteCount" db:"upvote_count"`
-	DownvoteCount int        `json:"downvoteCount" db:"downvote_count"`
-	Score         int        `json:"score" db:"score"`
-	CommentCount  int        `json:"commentCount" db:"comment_count"`
+	CreatedAt            time.Time      `json:"createdAt" db:"created_at"`
+	IndexedAt            time.Time      `json:"indexedAt" db:"indexed_at"`
+	EditedAt             *time.Time     `json:"editedAt,omitempty" db:"edited_at"`
+	Embed                *string        `json:"embed,omitempty" db:"embed"`
+	DeletedAt            *time.Time     `json:"deletedAt,omitempty" db:"deleted_at"`
+	LastActivityAt       *time.Time     `json:"lastActivityAt,omitempty" db:"last_activity_at"` // Newest comment in the thread, kept after deletes
+	ContentLabels        *string        `json:"labels,omitempty" db:"content_labels"`
+	Title                *string        `json:"title,omitempty" db:"title"`
+	Content              *string        `json:"content,omitempty" db:"content"`
+	ContentFacets        *string        `json:"contentFacets,omitempty" db:"content_facets"`
+	RawRecord            *string        `json:"-" db:"raw_record"`               // Full record JSON as received from the firehose
+	LinkHash             *string        `json:"-" db:"normalized_url_hash"`      // LinkHash of the external embed's URL, nil without one
+	DetectedLang         *string        `json:"-" db:"detected_lang"`            // Language detected at index time when the record has no langs
+	LangConfidence       *float64       `json:"-" db:"detected_lang_confidence"` // Detector confidence (0-1) for DetectedLang
+	Tags                 []string       `json:"tags,omitempty" db:"tags"`        // Flair tags, validated against the community's flairs at index time
+	Labels               []string       `json:"-" db:"labels"`                   // Allowed, non-negated self-labels from ContentLabels
+	QuarantineReasons    []string       `json:"-" db:"quarantine_reasons"`       // Spam signals tripped when quarantined
+	LabelOverrides       LabelOverrides `json:"-"`                               // Moderator label overrides, loaded by GetByURI
+	CID                  string         `json:"cid" db:"cid"`
+	CommunityDID         string         `json:"communityDid" db:"community_did"`
+	RKey                 string         `json:"rkey" db:"rkey"`
+	URI                  string         `json:"uri" db:"uri"`
+	AuthorDID            string         `json:"authorDid" db:"author_did"`
+	Status               string         `json:"-" db:"status"` // StatusActive unless quarantined or caught by automod; empty means active
+	ID                   int64          `json:"id" db:"id"`
+	UpvoteCount          int            `json:"upvoteCount" db:"upvote_count"`
+	DownvoteCount        int            `json:"downvoteCount" db:"downvote_count"`
+	Score                int            `json:"score" db:"score"`
+	CommentCount         int            `json:"commentCount" db:"comment_count"`                   // Live comments anywhere in the thread
+	TopLevelCommentCount int            `json:"topLevelCommentCount" db:"top_level_comment_count"` // Live comments directly on the post
+	Locked               bool           `json:"locked,omitempty" db:"locked"`                      // Locked threads accept no new comments
 }
 
 // CreatePostRequest represents input for creating a new post
@@ -55,13 +77,27 @@ type CreatePostRequest struct {
 	Community      string                 `json:"community"`
 	AuthorDID      string                 `json:"authorDid"`
 	Facets         []interface{}          `json:"facets,omitempty"`
+	Tags           []string               `json:"tags,omitempty"`   // Flair names; tags not in the community's flair set are dropped
+	DryRun         bool                   `json:"dryRun,omitempty"` // Run every check and build the record without writing it
 }
 
 // CreatePostResponse represents the response from creating a post
 // Matches social.coves.community.post.create lexicon output schema
 type CreatePostResponse struct {
-	URI string `json:"uri"` // AT-URI of created post
-	CID string `json:"cid"` // CID of created post
+	DryRun *CreatePostDryRun `json:"dryRun,omitempty"` // Set instead of URI/CID for a dry run
+	URI    string            `json:"uri,omitempty"`    // AT-URI of created post
+	CID    string            `json:"cid,omitempty"`    // CID of created post
+}
+
+// CreatePostDryRun reports what a dry-run createPost would have written
+// Matches social.coves.community.post.create#dryRunResult
+type CreatePostDryRun struct {
+	Record                 PostRecord               `json:"record"`
+	PendingThumbnail       string                   `json:"pendingThumbnail,omitempty"` // URL a real post would upload as the embed's thumb
+	Repo                   string                   `json:"repo"`                       // Community DID whose repository the record would be written to
+	Collection             string                   `json:"collection"`
+	Quota                  []*aggregators.PostQuota `json:"quota,omitempty"` // Aggregators only; unchanged by the dry run
+	CredentialsNeedRefresh bool                     `json:"credentialsNeedRefresh"`
 }
 
 // DeletePostRequest represents input for deleting a post
@@ -85,38 +121,59 @@ type PostRecord struct {
 	Author         string                 `json:"author"`
 	CreatedAt      string                 `json:"createdAt"`
 	Facets         []interface{}          `json:"facets,omitempty"`
+	Tags           []string               `json:"tags,omitempty"`
+	Imported       bool                   `json:"imported,omitempty"`
 }
 
 // PostView represents the full view of a post with all metadata
 // Matches social.coves.community.post.get#postView lexicon
 // Used in feeds and get endpoints
 type PostView struct {
-	IndexedAt     time.Time     `json:"indexedAt"`
-	CreatedAt     time.Time     `json:"createdAt"`
-	Record        interface{}   `json:"record,omitempty"`
-	Embed         interface{}   `json:"embed,omitempty"`
-	Language      *string       `json:"language,omitempty"`
-	EditedAt      *time.Time    `json:"editedAt,omitempty"`
-	Viewer        *ViewerState  `json:"viewer,omitempty"`
-	Author        *AuthorView   `json:"author"`
-	Stats         *PostStats    `json:"stats,omitempty"`
-	Community     *CommunityRef `json:"community"`
-	RKey          string        `json:"rkey"`
-	CID           string        `json:"cid"`
-	URI           string        `json:"uri"`
-	UpvoteCount   int           `json:"-"`
-	DownvoteCount int           `json:"-"`
-	Score         int           `json:"-"`
-	CommentCount  int           `json:"-"`
+	IndexedAt            time.Time     `json:"indexedAt"`
+	CreatedAt            time.Time     `json:"createdAt"`
+	Record               interface{}   `json:"record,omitempty"`
+	Embed                interface{}   `json:"embed,omitempty"`
+	Language             *string       `json:"language,omitempty"`
+	EditedAt             *time.Time    `json:"editedAt,omitempty"`
+	LastActivityAt       *time.Time    `json:"-"`
+	Viewer               *ViewerState  `json:"viewer,omitempty"`
+	Via                  *ViaView      `json:"via,omitempty"`    // Set when an aggregator created the post
+	Labels               *LabelsView   `json:"labels,omitempty"` // Effective labels: community, self and moderator labels merged
+	Author               *AuthorView   `json:"author"`
+	Stats                *PostStats    `json:"stats,omitempty"`
+	Community            *CommunityRef `json:"community"`
+	RKey                 string        `json:"rkey"`
+	CID                  string        `json:"cid"`
+	URI                  string        `json:"uri"`
+	UpvoteCount          int           `json:"-"`
+	DownvoteCount        int           `json:"-"`
+	Score                int           `json:"-"`
+	CommentCount         int           `json:"-"`
+	TopLevelCommentCount int           `json:"-"`
+	ScoreHidingHours     int           `json:"-"` // Community's score hiding window, applied by HideScores
+	CollapseThreshold    int           `json:"-"` // Community's comment collapse settings, applied to comment threads
+	CrowdControl         string        `json:"-"`
 }
 
 // AuthorView represents author information in post views
 type AuthorView struct {
-	DisplayName *string `json:"displayName,omitempty"`
+	Badges      *AuthorBadges `json:"badges,omitempty"` // Set by AddAuthorBadges and comment hydration
+	DisplayName *string       `json:"displayName,omitempty"`
+	Avatar      *string       `json:"avatar,omitempty"`
+	Reputation  *int          `json:"reputation,omitempty"`
+	DID         string        `json:"did"`
+	Handle      string        `json:"handle"`
+	// ConfusableFlag is set when the handle imitates another user's handle with lookalike
+	// characters (see the confusables package), so clients can warn before trusting it
+	ConfusableFlag bool `json:"confusableFlag,omitempty"`
+}
+
+// ViaView attributes a post to the aggregator that created it
+// Matches social.coves.community.post.get#viaView
+type ViaView struct {
 	Avatar      *string `json:"avatar,omitempty"`
-	Reputation  *int    `json:"reputation,omitempty"`
 	DID         string  `json:"did"`
-	Handle      string  `json:"handle"`
+	DisplayName string  `json:"displayName"`
 }
 
 // CommunityRef represents minimal community info in post views
@@ -130,12 +187,15 @@ type CommunityRef struct {
 
 // PostStats represents aggregated statistics
 type PostStats struct {
-	TagCounts    map[string]int `json:"tagCounts,omitempty"`
-	Upvotes      int            `json:"upvotes"`
-	Downvotes    int            `json:"downvotes"`
-	Score        int            `json:"score"`
-	CommentCount int            `json:"commentCount"`
-	ShareCount   int            `json:"shareCount,omitempty"`
+	TagCounts            map[string]int `json:"tagCounts,omitempty"`
+	LastActivityAt       *time.Time     `json:"lastActivityAt,omitempty"` // Newest comment in the thread
+	Upvotes              int            `json:"upvotes"`
+	Downvotes            int            `json:"downvotes"`
+	Score                int            `json:"score"`
+	CommentCount         int            `json:"commentCount"`         // All live comments in the thread
+	TopLevelCommentCount int            `json:"topLevelCommentCount"` // Live comments directly on the post
+	ShareCount           int            `json:"shareCount,omitempty"`
+	ScoreHidden          bool           `json:"scoreHidden,omitempty"` // Vote counts are serialized as null
 }
 
 // ViewerState represents the viewer's relationship with the post
@@ -172,6 +232,13 @@ type GetAuthorPostsResponse struct {
 	Cursor *string         `json:"cursor,omitempty"`
 }
 
+// GetPostRequest represents input for fetching a single post
+// Matches social.coves.feed.getPost lexicon input
+type GetPostRequest struct {
+	URI       string // AT-URI of the post
+	ViewerDID string // Viewer's DID for author_only visibility; empty for anonymous viewers
+}
+
 // FeedViewPost matches social.coves.feed.defs#feedViewPost
 // Wraps a post with optional context about why it appears in a feed
 type FeedViewPost struct {
@@ -0,0 +1,87 @@
+package posts
+
+import (
+	"encoding/json"
+	"time"
+
+	"Coves/internal/core/communities"
+)
+
+// HideScores nulls the vote counts on posts inside their community's score hiding window
+// The post author and moderators of the post's community (moderated, keyed by community DID)
+// always see real counts. Only the serialized stats change - ranking uses the indexed counts.
+func HideScores(views []*PostView, viewerDID string, moderated map[string]bool, now time.Time) {
+	for _, view := range views {
+		if view == nil || view.Stats == nil || !communities.ScoresHidden(view.ScoreHidingHours, view.CreatedAt, now) {
+			continue
+		}
+		if viewerDID != "" && view.Author != nil && view.Author.DID == viewerDID {
+			continue
+		}
+		if view.Community != nil && moderated[view.Community.DID] {
+			continue
+		}
+		view.Stats.Hide()
+	}
+}
+
+// NeedsModeratorCheck returns the communities whose score hiding window covers at least one
+// of the views, excluding the viewer's own posts - only these need a moderator lookup
+func NeedsModeratorCheck(views []*PostView, viewerDID string, now time.Time) []string {
+	seen := make(map[string]bool)
+	var communityDIDs []string
+	for _, view := range views {
+		if view == nil || view.Community == nil || !communities.ScoresHidden(view.ScoreHidingHours, view.CreatedAt, now) {
+			continue
+		}
+		if view.Author != nil && view.Author.DID == viewerDID {
+			continue
+		}
+		if !seen[view.Community.DID] {
+			seen[view.Community.DID] = true
+			communityDIDs = append(communityDIDs, view.Community.DID)
+		}
+	}
+	return communityDIDs
+}
+
+// Hide drops the vote counts so they can't leak and serializes them as null
+func (s *PostStats) Hide() {
+	s.Upvotes, s.Downvotes, s.Score = 0, 0, 0
+	s.ScoreHidden = true
+}
+
+// MarshalJSON serializes hidden vote counts as null
+// The field order matches PostStats so responses are unchanged when nothing is hidden
+func (s PostStats) MarshalJSON() ([]byte, error) {
+	upvotes, downvotes, score := VoteCounts(s.ScoreHidden, s.Upvotes, s.Downvotes, s.Score)
+	return json.Marshal(struct {
+		TagCounts            map[string]int `json:"tagCounts,omitempty"`
+		LastActivityAt       *time.Time     `json:"lastActivityAt,omitempty"`
+		Upvotes              *int           `json:"upvotes"`
+		Downvotes            *int           `json:"downvotes"`
+		Score                *int           `json:"score"`
+		CommentCount         int            `json:"commentCount"`
+		TopLevelCommentCount int            `json:"topLevelCommentCount"`
+		ShareCount           int            `json:"shareCount,omitempty"`
+		ScoreHidden          bool           `json:"scoreHidden,omitempty"`
+	}{
+		TagCounts:            s.TagCounts,
+		LastActivityAt:       s.LastActivityAt,
+		Upvotes:              upvotes,
+		Downvotes:            downvotes,
+		Score:                score,
+		CommentCount:         s.CommentCount,
+		TopLevelCommentCount: s.TopLevelCommentCount,
+		ShareCount:           s.ShareCount,
+		ScoreHidden:          s.ScoreHidden,
+	})
+}
+
+// VoteCounts returns the vote counts to serialize, all nil when hidden
+func VoteCounts(hidden bool, upvotes, downvotes, score int) (*int, *int, *int) {
+	if hidden {
+		return nil, nil, nil
+	}
+	return &upvotes, &downvotes, &score
+}
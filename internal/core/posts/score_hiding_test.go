@@ -0,0 +1,111 @@
+package posts
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scoreHidingView(authorDID string, createdAt time.Time, hours int) *PostView {
+	return &PostView{
+		URI:              "at://" + authorDID + "/social.coves.community.post/1",
+		CreatedAt:        createdAt,
+		Author:           &AuthorView{DID: authorDID, Handle: "author.test"},
+		Community:        &CommunityRef{DID: "did:plc:community", Handle: "c-test.coves.social", Name: "test"},
+		ScoreHidingHours: hours,
+		Stats:            &PostStats{Upvotes: 4242, Downvotes: 1717, Score: 2525, CommentCount: 3},
+	}
+}
+
+func TestHideScores_WindowBoundary(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		age    time.Duration
+		hours  int
+		hidden bool
+	}{
+		{name: "just posted", age: 0, hours: 6, hidden: true},
+		{name: "one nanosecond before the window ends", age: 6*time.Hour - 1, hours: 6, hidden: true},
+		{name: "exactly at the window end", age: 6 * time.Hour, hours: 6, hidden: false},
+		{name: "after the window", age: 7 * time.Hour, hours: 6, hidden: false},
+		{name: "hiding disabled", age: 0, hours: 0, hidden: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			view := scoreHidingView("did:plc:author", now.Add(-tt.age), tt.hours)
+			HideScores([]*PostView{view}, "did:plc:viewer", nil, now)
+
+			assert.Equal(t, tt.hidden, view.Stats.ScoreHidden)
+			if !tt.hidden {
+				assert.Equal(t, 4242, view.Stats.Upvotes)
+				assert.Equal(t, 2525, view.Stats.Score)
+			}
+		})
+	}
+}
+
+func TestHideScores_AuthorAndModeratorSeeRealCounts(t *testing.T) {
+	now := time.Now()
+
+	own := scoreHidingView("did:plc:author", now, 24)
+	HideScores([]*PostView{own}, "did:plc:author", nil, now)
+	assert.False(t, own.Stats.ScoreHidden, "author sees their own counts")
+	assert.Equal(t, 4242, own.Stats.Upvotes)
+
+	modded := scoreHidingView("did:plc:author", now, 24)
+	HideScores([]*PostView{modded}, "did:plc:mod", map[string]bool{"did:plc:community": true}, now)
+	assert.False(t, modded.Stats.ScoreHidden, "moderators see real counts")
+
+	anonymous := scoreHidingView("did:plc:author", now, 24)
+	HideScores([]*PostView{anonymous}, "", nil, now)
+	assert.True(t, anonymous.Stats.ScoreHidden)
+}
+
+func TestNeedsModeratorCheck(t *testing.T) {
+	now := time.Now()
+	views := []*PostView{
+		scoreHidingView("did:plc:author", now, 6),
+		scoreHidingView("did:plc:viewer", now, 6),                   // Viewer's own post
+		scoreHidingView("did:plc:author", now.Add(-7*time.Hour), 6), // Window over
+	}
+
+	assert.Equal(t, []string{"did:plc:community"}, NeedsModeratorCheck(views, "did:plc:viewer", now))
+	assert.Empty(t, NeedsModeratorCheck(views[1:], "did:plc:viewer", now))
+}
+
+func TestPostStats_HiddenCountsNeverSerialized(t *testing.T) {
+	now := time.Now()
+	view := scoreHidingView("did:plc:author", now, 12)
+	HideScores([]*PostView{view}, "did:plc:viewer", nil, now)
+
+	body, err := json.Marshal(view)
+	require.NoError(t, err)
+
+	for _, raw := range []string{"4242", "1717", "2525"} {
+		assert.NotContains(t, string(body), raw)
+	}
+
+	var decoded struct {
+		Stats map[string]interface{} `json:"stats"`
+	}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	for _, field := range []string{"upvotes", "downvotes", "score"} {
+		value, present := decoded.Stats[field]
+		assert.True(t, present, "%s is present as null", field)
+		assert.Nil(t, value)
+	}
+	assert.Equal(t, true, decoded.Stats["scoreHidden"])
+	assert.Equal(t, float64(3), decoded.Stats["commentCount"])
+}
+
+func TestPostStats_VisibleCountsUnchanged(t *testing.T) {
+	body, err := json.Marshal(&PostStats{Upvotes: 5, Downvotes: 1, Score: 4, CommentCount: 2})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"upvotes":5,"downvotes":1,"score":4,"commentCount":2,"topLevelCommentCount":0}`, string(body))
+}
@@ -14,13 +14,16 @@ import (
 	"time"
 
 	"Coves/internal/api/middleware"
+	"Coves/internal/atproto/aturi"
 	"Coves/internal/atproto/pds"
 	"Coves/internal/atproto/utils"
 	"Coves/internal/core/aggregators"
 	"Coves/internal/core/blobs"
 	"Coves/internal/core/blueskypost"
 	"Coves/internal/core/communities"
+	"Coves/internal/core/takedown"
 	"Coves/internal/core/unfurl"
+	"Coves/internal/lexicon/validate"
 
 	"github.com/bluesky-social/indigo/atproto/auth/oauth"
 )
@@ -32,6 +35,7 @@ type postService struct {
 	blobService       blobs.Service
 	unfurlService     unfurl.Service
 	blueskyService    blueskypost.Service
+	takedowns         takedown.Checker
 	pdsURL            string
 }
 
@@ -62,11 +66,15 @@ func NewPostService(
 // 1. Validate input
 // 2. Check if author is an aggregator (server-side validation using DID from JWT)
 // 3. If aggregator: validate authorization and rate limits, skip membership checks
-// 4. If user: resolve community and perform membership/ban validation
-// 5. Build post record
-// 6. Write to community's PDS repository
-// 7. If aggregator: record post for rate limiting
-// 8. Return URI/CID (AppView indexes asynchronously via Jetstream)
+// 4. Resolve community and reject suspended or federation-blocked communities
+// 5. If user: check visibility (private communities need a subscription) and bans
+// 6. Build post record
+// 7. Write to community's PDS repository
+// 8. If aggregator: record post for rate limiting
+// 9. Return URI/CID (AppView indexes asynchronously via Jetstream)
+// Every rejection happens before anything is written to the PDS.
+// A dry run stops before step 7: it writes nothing to the PDS (no thumbnail blobs either),
+// leaves the community's credentials alone and doesn't count against the aggregator's quota.
 func (s *postService) CreatePost(ctx context.Context, req CreatePostRequest) (*CreatePostResponse, error) {
 	// 1. Validate basic input (before DID checks to give clear validation errors)
 	if err := s.validateCreateRequest(&req); err != nil {
@@ -124,6 +132,9 @@ func (s *postService) CreatePost(ctx context.Context, req CreatePostRequest) (*C
 	communityDID, err := s.communityService.ResolveCommunityIdentifier(ctx, req.Community)
 	if err != nil {
 		// Handle specific error types appropriately
+		// A community that's on its PDS but not yet indexed is not found too: its PDS
+		// credentials live in the index, so there's nothing to post with until Jetstream
+		// delivers it
 		if communities.IsNotFound(err) {
 			return nil, ErrCommunityNotFound
 		}
@@ -155,6 +166,17 @@ func (s *postService) CreatePost(ctx context.Context, req CreatePostRequest) (*C
 		return nil, fmt.Errorf("failed to fetch community: %w", err)
 	}
 
+	// Suspended, deleted and federation-blocked communities don't accept posts from anyone, aggregators included
+	if community.SuspendedAt != nil {
+		return nil, ErrCommunitySuspended
+	}
+	if community.DeletedAt != nil {
+		return nil, ErrCommunityDeleted
+	}
+	if community.FederationBlocked {
+		return nil, ErrCommunityFederationBlocked
+	}
+
 	// 7. Apply validation based on actor type (aggregator vs user)
 	if isTrustedAggregator {
 		// TRUSTED AGGREGATOR VALIDATION FLOW
@@ -169,17 +191,24 @@ func (s *postService) CreatePost(ctx context.Context, req CreatePostRequest) (*C
 		log.Printf("[POST-CREATE] Authorized aggregator detected: %s posting to community: %s", req.AuthorDID, communityDID)
 	} else {
 		// USER VALIDATION FLOW
-		// Check community visibility (Alpha: public/unlisted only)
-		// Beta will add membership checks for private communities
-		if community.Visibility == "private" {
-			return nil, ErrNotAuthorized
+		if err := s.checkUserCanPost(ctx, req.AuthorDID, community); err != nil {
+			return nil, err
 		}
 	}
 
 	// 8. Ensure community has fresh PDS credentials (token refresh if needed)
-	community, err = s.communityService.EnsureFreshToken(ctx, community)
-	if err != nil {
-		return nil, fmt.Errorf("failed to refresh community credentials: %w", err)
+	// A dry run only reports whether the real post would have to refresh them.
+	var credentialsNeedRefresh bool
+	if req.DryRun {
+		credentialsNeedRefresh, err = communities.NeedsRefresh(community.PDSAccessToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check community credentials: %w", err)
+		}
+	} else {
+		community, err = s.communityService.EnsureFreshToken(ctx, community)
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh community credentials: %w", err)
+		}
 	}
 
 	// 9. Build post record for PDS
@@ -192,6 +221,7 @@ func (s *postService) CreatePost(ctx context.Context, req CreatePostRequest) (*C
 		Facets:         req.Facets,
 		Embed:          req.Embed, // Start with user-provided embed
 		Labels:         req.Labels,
+		Tags:           community.FilterPostTags(req.Tags),
 		OriginalAuthor: req.OriginalAuthor,
 		FederatedFrom:  req.FederatedFrom,
 		Location:       req.Location,
@@ -199,6 +229,7 @@ func (s *postService) CreatePost(ctx context.Context, req CreatePostRequest) (*C
 	}
 
 	// 10. Validate and enhance external embeds
+	var pendingThumbnail string
 	if postRecord.Embed != nil {
 		embedType, typeOk := postRecord.Embed["$type"].(string)
 		if typeOk && embedType == "social.coves.embed.external" {
@@ -240,7 +271,9 @@ func (s *postService) CreatePost(ctx context.Context, req CreatePostRequest) (*C
 					if req.ThumbnailURL != nil && *req.ThumbnailURL != "" && isTrustedAggregator {
 						log.Printf("[AGGREGATOR-THUMB] Trusted aggregator provided thumbnail: %s", *req.ThumbnailURL)
 
-						if s.blobService != nil {
+						if req.DryRun {
+							pendingThumbnail = *req.ThumbnailURL
+						} else if s.blobService != nil {
 							blobCtx, blobCancel := context.WithTimeout(ctx, 15*time.Second)
 							defer blobCancel()
 
@@ -296,7 +329,9 @@ func (s *postService) CreatePost(ctx context.Context, req CreatePostRequest) (*C
 
 									// Upload thumbnail from unfurl if client didn't provide one
 									// (Thumb validation already happened above)
-									if external["thumb"] == nil {
+									if external["thumb"] == nil && req.DryRun {
+										pendingThumbnail = result.ThumbnailURL
+									} else if external["thumb"] == nil {
 										if result.ThumbnailURL != "" && s.blobService != nil {
 											blobCtx, blobCancel := context.WithTimeout(ctx, 15*time.Second)
 											defer blobCancel()
@@ -326,6 +361,22 @@ func (s *postService) CreatePost(ctx context.Context, req CreatePostRequest) (*C
 		}
 	}
 
+	// Enforce the community's alt text rule up front: the consumer would refuse to index the post
+	NormalizeImageAlt(postRecord.Embed)
+	if missing := CountImagesMissingAlt(postRecord.Embed); missing > 0 && community.PostingRules.RequireAltText {
+		return nil, NewContentRuleViolation("requireAltText",
+			fmt.Sprintf("this community requires alt text on every image (%d image(s) missing alt text)", missing))
+	}
+
+	// Validate the record with the consumer's rules so it isn't written only to be refused indexing
+	if err := validate.Value(validate.PostCollection, postRecord); err != nil {
+		return nil, err
+	}
+
+	if req.DryRun {
+		return s.dryRunResponse(ctx, req.AuthorDID, isOtherAggregator, postRecord, pendingThumbnail, credentialsNeedRefresh)
+	}
+
 	// 11. Write to community's PDS repository
 	uri, cid, err := s.createPostOnPDS(ctx, community, postRecord)
 	if err != nil {
@@ -351,15 +402,33 @@ func (s *postService) CreatePost(ctx context.Context, req CreatePostRequest) (*C
 	}, nil
 }
 
+// dryRunResponse reports what CreatePost would have written
+// Quota is read the same way ValidateAggregatorPost reads it, so it is what the aggregator has
+// left before this post; RecordAggregatorPost is never called.
+func (s *postService) dryRunResponse(ctx context.Context, authorDID string, isOtherAggregator bool, record PostRecord, pendingThumbnail string, credentialsNeedRefresh bool) (*CreatePostResponse, error) {
+	dryRun := &CreatePostDryRun{
+		Record:                 record,
+		PendingThumbnail:       pendingThumbnail,
+		Repo:                   record.Community,
+		Collection:             validate.PostCollection,
+		CredentialsNeedRefresh: credentialsNeedRefresh,
+	}
+	if isOtherAggregator && s.aggregatorService != nil {
+		quota, err := s.aggregatorService.GetPostQuota(ctx, authorDID, record.Community)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read aggregator quota: %w", err)
+		}
+		dryRun.Quota = quota
+	}
+
+	log.Printf("[POST-CREATE] Dry run: Author: %s (otherAggregator=%v), Community: %s", authorDID, isOtherAggregator, record.Community)
+	return &CreatePostResponse{DryRun: dryRun}, nil
+}
+
 // validateCreateRequest validates basic input requirements
+// Byte limits are checked up front; the built record gets the lexicon's full rules
+// (graphemes, tags, langs) in CreatePost before it is written.
 func (s *postService) validateCreateRequest(req *CreatePostRequest) error {
-	// Global content limits (from lexicon)
-	const (
-		maxContentLength  = 100000 // 100k characters - matches social.coves.community.post lexicon
-		maxTitleLength    = 3000   // 3k bytes
-		maxTitleGraphemes = 300    // 300 graphemes (simplified check)
-	)
-
 	// Validate community required
 	if req.Community == "" {
 		return NewValidationError("community", "community is required")
@@ -371,32 +440,23 @@ func (s *postService) validateCreateRequest(req *CreatePostRequest) error {
 	}
 
 	// Validate content length
-	if req.Content != nil && len(*req.Content) > maxContentLength {
+	if req.Content != nil && len(*req.Content) > validate.MaxPostContentBytes {
 		return NewValidationError("content",
-			fmt.Sprintf("content too long (max %d characters)", maxContentLength))
+			fmt.Sprintf("content too long (max %d characters)", validate.MaxPostContentBytes))
 	}
 
 	// Validate title length
-	if req.Title != nil {
-		if len(*req.Title) > maxTitleLength {
-			return NewValidationError("title",
-				fmt.Sprintf("title too long (max %d bytes)", maxTitleLength))
-		}
-		// Simplified grapheme check (actual implementation would need unicode library)
-		// For Alpha, byte length check is sufficient
+	if req.Title != nil && len(*req.Title) > validate.MaxPostTitleBytes {
+		return NewValidationError("title",
+			fmt.Sprintf("title too long (max %d bytes)", validate.MaxPostTitleBytes))
 	}
 
 	// Validate content labels are from known values
 	if req.Labels != nil {
-		validLabels := map[string]bool{
-			"nsfw":     true,
-			"spoiler":  true,
-			"violence": true,
-		}
 		for _, label := range req.Labels.Values {
-			if !validLabels[label.Val] {
+			if !IsAllowedLabel(label.Val) {
 				return NewValidationError("labels",
-					fmt.Sprintf("unknown content label: %s (valid: nsfw, spoiler, violence)", label.Val))
+					fmt.Sprintf("unknown content label: %s (valid: nsfw, spoiler, violence, gore)", label.Val))
 			}
 		}
 	}
@@ -404,6 +464,33 @@ func (s *postService) validateCreateRequest(req *CreatePostRequest) error {
 	return nil
 }
 
+// checkUserCanPost applies the community's visibility rules and bans to a regular user
+// Private communities only accept posts from subscribers (and their creator).
+func (s *postService) checkUserCanPost(ctx context.Context, userDID string, community *communities.Community) error {
+	if community.Visibility == "private" && community.CreatedByDID != userDID {
+		subscribed, err := s.communityService.IsSubscribed(ctx, userDID, community.DID)
+		if err != nil {
+			return fmt.Errorf("failed to check subscription: %w", err)
+		}
+		if !subscribed {
+			return ErrNotAuthorized
+		}
+	}
+
+	membership, err := s.communityService.GetMembership(ctx, userDID, community.DID)
+	if err != nil {
+		// No membership record means the user has never been banned
+		if communities.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to check membership: %w", err)
+	}
+	if membership.IsBanned {
+		return ErrBanned
+	}
+	return nil
+}
+
 // createPostOnPDS writes a post record to the community's PDS repository
 // Uses com.atproto.repo.createRecord endpoint
 func (s *postService) createPostOnPDS(
@@ -697,6 +784,70 @@ func validateDIDFormat(did string) error {
 	}
 }
 
+// GetPost retrieves a single post by AT-URI
+// Flow:
+// 1. Validate and canonicalize the URI
+// 2. Report an active admin takedown as RecordTakenDown rather than not found
+// 3. Fetch the hydrated post view (deleted and hidden posts are not found)
+// 4. Reject posts whose community is suspended, deleted or federation-blocked
+func (s *postService) GetPost(ctx context.Context, req GetPostRequest) (*PostView, error) {
+	uri, err := parsePostViewURI(req.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotTakenDown(ctx, uri); err != nil {
+		return nil, err
+	}
+
+	postView, err := s.repo.GetViewByURI(ctx, uri, req.ViewerDID)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get post: %w", err)
+	}
+
+	if postView.Community == nil {
+		return postView, nil
+	}
+	community, err := s.communityService.GetByDID(ctx, postView.Community.DID)
+	if err != nil {
+		if communities.IsNotFound(err) {
+			return nil, ErrCommunityNotFound
+		}
+		return nil, fmt.Errorf("failed to fetch community: %w", err)
+	}
+	// Same checks, in the same order, as CreatePost
+	if community.SuspendedAt != nil {
+		return nil, ErrCommunitySuspended
+	}
+	if community.DeletedAt != nil {
+		return nil, ErrCommunityDeleted
+	}
+	if community.FederationBlocked {
+		return nil, ErrCommunityFederationBlocked
+	}
+
+	return postView, nil
+}
+
+// parsePostViewURI validates a GetPost URI and returns its canonical form
+// The URI must name a record in the post collection.
+func parsePostViewURI(raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", NewValidationError("uri", "uri is required")
+	}
+	parsed, err := aturi.Parse(raw)
+	if err != nil {
+		return "", NewValidationError("uri", "uri must be a valid AT-URI")
+	}
+	if parsed.Collection != "social.coves.community.post" || parsed.RKey == "" {
+		return "", NewValidationError("uri", "uri must reference a social.coves.community.post record")
+	}
+	return parsed.String(), nil
+}
+
 // DeletePost deletes a post from the community's PDS repository
 // SECURITY: Only the post author can delete their own posts
 // Flow:
@@ -7,7 +7,9 @@ import (
 
 // mockRepository implements Repository for testing
 type mockRepository struct {
-	getByAuthorFunc func(ctx context.Context, req GetAuthorPostsRequest) ([]*PostView, *string, error)
+	getByAuthorFunc    func(ctx context.Context, req GetAuthorPostsRequest) ([]*PostView, *string, error)
+	getViewByURIFunc   func(ctx context.Context, uri, viewerDID string) (*PostView, error)
+	findDuplicatesFunc func(ctx context.Context, query DuplicateQuery) ([]*DuplicateMatch, error)
 }
 
 func (m *mockRepository) Create(ctx context.Context, post *Post) error {
@@ -25,10 +27,24 @@ func (m *mockRepository) GetByAuthor(ctx context.Context, req GetAuthorPostsRequ
 	return []*PostView{}, nil, nil
 }
 
+func (m *mockRepository) GetViewByURI(ctx context.Context, uri, viewerDID string) (*PostView, error) {
+	if m.getViewByURIFunc != nil {
+		return m.getViewByURIFunc(ctx, uri, viewerDID)
+	}
+	return nil, ErrNotFound
+}
+
 func (m *mockRepository) SoftDelete(ctx context.Context, uri string) error {
 	return nil
 }
 
+func (m *mockRepository) FindDuplicates(ctx context.Context, query DuplicateQuery) ([]*DuplicateMatch, error) {
+	if m.findDuplicatesFunc != nil {
+		return m.findDuplicatesFunc(ctx, query)
+	}
+	return nil, nil
+}
+
 func (m *mockRepository) Update(ctx context.Context, post *Post) error {
 	return nil
 }
@@ -0,0 +1,187 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/communities"
+)
+
+// mockCommunityService serves one indexed community
+// Methods CreatePost doesn't call fall through to the nil embedded interface and panic.
+type mockCommunityService struct {
+	communities.Service
+	community  *communities.Community
+	membership *communities.Membership
+	subscribed bool
+}
+
+func (m *mockCommunityService) ResolveCommunityIdentifier(ctx context.Context, identifier string) (string, error) {
+	if m.community == nil || (identifier != m.community.DID && identifier != m.community.Handle) {
+		return "", communities.ErrCommunityNotFound
+	}
+	return m.community.DID, nil
+}
+
+func (m *mockCommunityService) GetByDID(ctx context.Context, did string) (*communities.Community, error) {
+	if m.community == nil || did != m.community.DID {
+		return nil, communities.ErrCommunityNotFound
+	}
+	return m.community, nil
+}
+
+func (m *mockCommunityService) IsSubscribed(ctx context.Context, userDID, communityDID string) (bool, error) {
+	return m.subscribed, nil
+}
+
+func (m *mockCommunityService) GetMembership(ctx context.Context, userDID, communityIdentifier string) (*communities.Membership, error) {
+	if m.membership == nil {
+		return nil, communities.ErrMembershipNotFound
+	}
+	return m.membership, nil
+}
+
+func (m *mockCommunityService) EnsureFreshToken(ctx context.Context, community *communities.Community) (*communities.Community, error) {
+	return community, nil
+}
+
+// fakePDS answers profile lookups for brand-new communities and counts post writes
+type fakePDS struct {
+	server   *httptest.Server
+	profiles map[string]bool
+	writes   atomic.Int32
+}
+
+func newFakePDS(t *testing.T) *fakePDS {
+	pds := &fakePDS{profiles: map[string]bool{}}
+	pds.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.repo.getRecord":
+			if pds.profiles[r.URL.Query().Get("repo")] && r.URL.Query().Get("collection") == "social.coves.community.profile" {
+				_, _ = w.Write([]byte(`{"uri":"at://x/social.coves.community.profile/self","value":{}}`))
+				return
+			}
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"RecordNotFound"}`))
+		case "/xrpc/com.atproto.repo.createRecord":
+			pds.writes.Add(1)
+			_, _ = w.Write([]byte(`{"uri":"at://did:plc:community/social.coves.community.post/abc","cid":"bafypost"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(pds.server.Close)
+	return pds
+}
+
+func TestCreatePost_CommunityPreflight(t *testing.T) {
+	t.Setenv("TRUSTED_AGGREGATOR_DIDS", "")
+	t.Setenv("KAGI_AGGREGATOR_DID", "")
+
+	const author = "did:plc:author"
+	suspendedAt := time.Now()
+
+	tests := []struct {
+		name       string
+		community  *communities.Community
+		membership *communities.Membership
+		subscribed bool
+		newOnPDS   bool // profile exists on the PDS but not in the index
+		target     string
+		wantErr    error
+	}{
+		{
+			name:      "public community accepts posts",
+			community: &communities.Community{DID: "did:plc:community", Visibility: "public"},
+		},
+		{
+			name:    "unknown community",
+			target:  "did:plc:nowhere",
+			wantErr: ErrCommunityNotFound,
+		},
+		{
+			// There are no PDS credentials to post with until the community is indexed
+			name:     "brand-new community on its PDS but not indexed yet",
+			target:   "did:plc:brandnew",
+			newOnPDS: true,
+			wantErr:  ErrCommunityNotFound,
+		},
+		{
+			name:      "suspended community",
+			community: &communities.Community{DID: "did:plc:community", Visibility: "public", SuspendedAt: &suspendedAt},
+			wantErr:   ErrCommunitySuspended,
+		},
+		{
+			name:      "federation-blocked community",
+			community: &communities.Community{DID: "did:plc:community", Visibility: "public", FederationBlocked: true},
+			wantErr:   ErrCommunityFederationBlocked,
+		},
+		{
+			name:      "private community without subscription",
+			community: &communities.Community{DID: "did:plc:community", Visibility: "private"},
+			wantErr:   ErrNotAuthorized,
+		},
+		{
+			name:       "private community with subscription",
+			community:  &communities.Community{DID: "did:plc:community", Visibility: "private"},
+			subscribed: true,
+		},
+		{
+			name:      "private community creator",
+			community: &communities.Community{DID: "did:plc:community", Visibility: "private", CreatedByDID: author},
+		},
+		{
+			name:       "banned author",
+			community:  &communities.Community{DID: "did:plc:community", Visibility: "public"},
+			membership: &communities.Membership{UserDID: author, CommunityDID: "did:plc:community", IsBanned: true},
+			wantErr:    ErrBanned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pds := newFakePDS(t)
+			target := tt.target
+			if tt.community != nil {
+				tt.community.PDSURL = pds.server.URL
+				target = tt.community.DID
+			}
+			if tt.newOnPDS {
+				pds.profiles[target] = true
+			}
+
+			communityService := &mockCommunityService{
+				community:  tt.community,
+				membership: tt.membership,
+				subscribed: tt.subscribed,
+			}
+			service := NewPostService(&mockRepository{}, communityService, nil, nil, nil, nil, pds.server.URL)
+
+			content := "hello"
+			ctx := middleware.SetTestUserDID(context.Background(), author)
+			_, err := service.CreatePost(ctx, CreatePostRequest{Community: target, AuthorDID: author, Content: &content})
+
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("Expected post to be created, got %v", err)
+				}
+				if pds.writes.Load() != 1 {
+					t.Errorf("Expected 1 PDS write, got %d", pds.writes.Load())
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if pds.writes.Load() != 0 {
+				t.Errorf("Expected no PDS write after a rejection, got %d", pds.writes.Load())
+			}
+		})
+	}
+}
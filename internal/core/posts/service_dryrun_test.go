@@ -0,0 +1,200 @@
+package posts
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/blobs"
+	"Coves/internal/core/communities"
+	"Coves/internal/lexicon/validate"
+)
+
+// mockAggregatorService authorizes one aggregator and counts recorded posts
+// Methods CreatePost doesn't call fall through to the nil embedded interface and panic.
+type mockAggregatorService struct {
+	aggregators.Service
+	aggregatorDID string
+	validateErr   error
+	quota         []*aggregators.PostQuota
+	recorded      int
+}
+
+func (m *mockAggregatorService) IsAggregator(ctx context.Context, did string) (bool, error) {
+	return did == m.aggregatorDID, nil
+}
+
+func (m *mockAggregatorService) ValidateAggregatorPost(ctx context.Context, aggregatorDID, communityDID string) error {
+	return m.validateErr
+}
+
+func (m *mockAggregatorService) GetPostQuota(ctx context.Context, aggregatorDID, communityDID string) ([]*aggregators.PostQuota, error) {
+	return m.quota, nil
+}
+
+func (m *mockAggregatorService) RecordAggregatorPost(ctx context.Context, aggregatorDID, communityDID, postURI, postCID string) error {
+	m.recorded++
+	return nil
+}
+
+// countingBlobService counts thumbnail uploads
+type countingBlobService struct {
+	blobs.Service
+	uploads int
+}
+
+func (c *countingBlobService) UploadBlobFromURL(ctx context.Context, owner blobs.BlobOwner, imageURL string) (*blobs.BlobRef, error) {
+	c.uploads++
+	return &blobs.BlobRef{Type: "blob", MimeType: "image/jpeg", Size: 1}, nil
+}
+
+// testAccessToken returns an unsigned JWT expiring at exp
+func testAccessToken(exp time.Time) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, exp.Unix())))
+	return "eyJhbGciOiJub25lIn0." + payload + ".sig"
+}
+
+func TestCreatePost_DryRun(t *testing.T) {
+	const aggregatorDID = "did:plc:aggregator"
+	t.Setenv("TRUSTED_AGGREGATOR_DIDS", "")
+	t.Setenv("KAGI_AGGREGATOR_DID", "")
+
+	setup := func(t *testing.T, token string) (*fakePDS, *mockAggregatorService, Service) {
+		pds := newFakePDS(t)
+		community := &communities.Community{
+			DID:            "did:plc:community",
+			Visibility:     "public",
+			PDSURL:         pds.server.URL,
+			PDSAccessToken: token,
+		}
+		aggregatorService := &mockAggregatorService{
+			aggregatorDID: aggregatorDID,
+			quota:         []*aggregators.PostQuota{{Window: aggregators.RateLimitWindowHour, Limit: 10, Used: 3, Remaining: 7}},
+		}
+		service := NewPostService(&mockRepository{}, &mockCommunityService{community: community}, aggregatorService, nil, nil, nil, pds.server.URL)
+		return pds, aggregatorService, service
+	}
+	ctx := middleware.SetTestUserDID(context.Background(), aggregatorDID)
+	title := "Breaking news"
+
+	t.Run("reports the record without writing it", func(t *testing.T) {
+		pds, aggregatorService, service := setup(t, testAccessToken(time.Now().Add(time.Hour)))
+
+		resp, err := service.CreatePost(ctx, CreatePostRequest{Community: "did:plc:community", AuthorDID: aggregatorDID, Title: &title, DryRun: true})
+		if err != nil {
+			t.Fatalf("Expected dry run to pass, got %v", err)
+		}
+		if pds.writes.Load() != 0 {
+			t.Errorf("Expected no PDS write in a dry run, got %d", pds.writes.Load())
+		}
+		if aggregatorService.recorded != 0 {
+			t.Errorf("Expected no recorded aggregator post in a dry run, got %d", aggregatorService.recorded)
+		}
+		if resp.URI != "" || resp.CID != "" || resp.DryRun == nil {
+			t.Fatalf("Expected only a dry-run result, got %+v", resp)
+		}
+
+		dryRun := resp.DryRun
+		if dryRun.Repo != "did:plc:community" || dryRun.Collection != validate.PostCollection {
+			t.Errorf("Expected a write to %s in did:plc:community, got %s in %s", validate.PostCollection, dryRun.Collection, dryRun.Repo)
+		}
+		if dryRun.Record.Author != aggregatorDID || dryRun.Record.Title == nil || *dryRun.Record.Title != title {
+			t.Errorf("Unexpected record: %+v", dryRun.Record)
+		}
+		if len(dryRun.Quota) != 1 || dryRun.Quota[0].Remaining != 7 {
+			t.Errorf("Expected the untouched quota, got %+v", dryRun.Quota)
+		}
+		if dryRun.CredentialsNeedRefresh {
+			t.Error("Expected fresh credentials not to need a refresh")
+		}
+	})
+
+	t.Run("reports expiring credentials without refreshing them", func(t *testing.T) {
+		_, _, service := setup(t, testAccessToken(time.Now().Add(time.Minute)))
+
+		resp, err := service.CreatePost(ctx, CreatePostRequest{Community: "did:plc:community", AuthorDID: aggregatorDID, Title: &title, DryRun: true})
+		if err != nil {
+			t.Fatalf("Expected dry run to pass, got %v", err)
+		}
+		if !resp.DryRun.CredentialsNeedRefresh {
+			t.Error("Expected credentials expiring within the refresh buffer to be reported")
+		}
+	})
+
+	t.Run("rejections are the same as a real post", func(t *testing.T) {
+		pds, aggregatorService, service := setup(t, testAccessToken(time.Now().Add(time.Hour)))
+		aggregatorService.validateErr = &aggregators.RateLimitError{Window: aggregators.RateLimitWindowHour, Limit: 10, ResetAt: time.Now().Add(time.Minute)}
+
+		_, err := service.CreatePost(ctx, CreatePostRequest{Community: "did:plc:community", AuthorDID: aggregatorDID, Title: &title, DryRun: true})
+		if !errors.Is(err, aggregators.ErrRateLimitExceeded) {
+			t.Fatalf("Expected rate limit error, got %v", err)
+		}
+
+		long := string(make([]byte, validate.MaxPostTitleBytes+1))
+		aggregatorService.validateErr = nil
+		_, err = service.CreatePost(ctx, CreatePostRequest{Community: "did:plc:community", AuthorDID: aggregatorDID, Title: &long, DryRun: true})
+		if !IsValidationError(err) {
+			t.Fatalf("Expected validation error, got %v", err)
+		}
+		if pds.writes.Load() != 0 || aggregatorService.recorded != 0 {
+			t.Errorf("Expected no side effects, got %d PDS writes and %d recorded posts", pds.writes.Load(), aggregatorService.recorded)
+		}
+	})
+
+	t.Run("real post still writes and records", func(t *testing.T) {
+		pds, aggregatorService, service := setup(t, testAccessToken(time.Now().Add(time.Hour)))
+
+		resp, err := service.CreatePost(ctx, CreatePostRequest{Community: "did:plc:community", AuthorDID: aggregatorDID, Title: &title})
+		if err != nil {
+			t.Fatalf("Expected post to be created, got %v", err)
+		}
+		if resp.DryRun != nil || resp.URI == "" {
+			t.Errorf("Expected a created post, got %+v", resp)
+		}
+		if pds.writes.Load() != 1 || aggregatorService.recorded != 1 {
+			t.Errorf("Expected 1 PDS write and 1 recorded post, got %d and %d", pds.writes.Load(), aggregatorService.recorded)
+		}
+	})
+}
+
+func TestCreatePost_DryRunSkipsThumbnailUpload(t *testing.T) {
+	const trustedDID = "did:plc:trusted"
+	t.Setenv("TRUSTED_AGGREGATOR_DIDS", trustedDID)
+
+	pds := newFakePDS(t)
+	community := &communities.Community{
+		DID:            "did:plc:community",
+		Visibility:     "public",
+		PDSURL:         pds.server.URL,
+		PDSAccessToken: testAccessToken(time.Now().Add(time.Hour)),
+	}
+	blobService := &countingBlobService{}
+	service := NewPostService(&mockRepository{}, &mockCommunityService{community: community}, nil, blobService, nil, nil, pds.server.URL)
+
+	thumbnailURL := "https://example.com/thumb.jpg"
+	ctx := middleware.SetTestUserDID(context.Background(), trustedDID)
+	resp, err := service.CreatePost(ctx, CreatePostRequest{
+		Community: "did:plc:community",
+		AuthorDID: trustedDID,
+		Embed: map[string]interface{}{
+			"$type":    "social.coves.embed.external",
+			"external": map[string]interface{}{"uri": "https://example.com/story", "title": "Story"},
+		},
+		ThumbnailURL: &thumbnailURL,
+		DryRun:       true,
+	})
+	if err != nil {
+		t.Fatalf("Expected dry run to pass, got %v", err)
+	}
+	if blobService.uploads != 0 || pds.writes.Load() != 0 {
+		t.Errorf("Expected no uploads or writes, got %d uploads and %d writes", blobService.uploads, pds.writes.Load())
+	}
+	if resp.DryRun.PendingThumbnail != thumbnailURL {
+		t.Errorf("Expected pending thumbnail %s, got %q", thumbnailURL, resp.DryRun.PendingThumbnail)
+	}
+}
@@ -0,0 +1,159 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"Coves/internal/core/communities"
+	"Coves/internal/core/takedown"
+)
+
+// mockTakedownChecker reports an active takedown for the URIs it holds
+type mockTakedownChecker struct {
+	active map[string]*takedown.Takedown
+}
+
+func (m *mockTakedownChecker) GetActive(ctx context.Context, subjectURI string) (*takedown.Takedown, error) {
+	if t, ok := m.active[subjectURI]; ok {
+		return t, nil
+	}
+	return nil, takedown.ErrTakedownNotFound
+}
+
+func TestGetPost(t *testing.T) {
+	const (
+		postURI      = "at://did:plc:community/social.coves.community.post/3kpost"
+		communityDID = "did:plc:community"
+		viewer       = "did:plc:viewer"
+	)
+	suspendedAt := time.Now()
+	deletedAt := time.Now()
+
+	tests := []struct {
+		name      string
+		uri       string
+		community *communities.Community
+		takenDown bool
+		missing   bool
+		wantErr   error
+	}{
+		{
+			name:      "visible post",
+			uri:       postURI,
+			community: &communities.Community{DID: communityDID},
+		},
+		{
+			name:      "non-canonical URI",
+			uri:       "AT://did:plc:community/social.coves.community.post/3kpost/",
+			community: &communities.Community{DID: communityDID},
+		},
+		{
+			name:      "unknown or deleted post",
+			uri:       postURI,
+			community: &communities.Community{DID: communityDID},
+			missing:   true,
+			wantErr:   ErrNotFound,
+		},
+		{
+			name:      "suspended community",
+			uri:       postURI,
+			community: &communities.Community{DID: communityDID, SuspendedAt: &suspendedAt},
+			wantErr:   ErrCommunitySuspended,
+		},
+		{
+			name:      "deleted community",
+			uri:       postURI,
+			community: &communities.Community{DID: communityDID, DeletedAt: &deletedAt},
+			wantErr:   ErrCommunityDeleted,
+		},
+		{
+			name:      "federation-blocked community",
+			uri:       postURI,
+			community: &communities.Community{DID: communityDID, FederationBlocked: true},
+			wantErr:   ErrCommunityFederationBlocked,
+		},
+		{
+			name:    "community missing from the index",
+			uri:     postURI,
+			wantErr: ErrCommunityNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotURI, gotViewer string
+			repo := &mockRepository{
+				getViewByURIFunc: func(ctx context.Context, uri, viewerDID string) (*PostView, error) {
+					gotURI, gotViewer = uri, viewerDID
+					if tt.missing {
+						return nil, ErrNotFound
+					}
+					return &PostView{URI: uri, Community: &CommunityRef{DID: communityDID}}, nil
+				},
+			}
+			service := NewPostService(repo, &mockCommunityService{community: tt.community}, nil, nil, nil, nil, "")
+
+			post, err := service.GetPost(context.Background(), GetPostRequest{URI: tt.uri, ViewerDID: viewer})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected post, got %v", err)
+			}
+			if post.URI != postURI || gotURI != postURI {
+				t.Errorf("Expected lookup by canonical URI %s, got %s", postURI, gotURI)
+			}
+			if gotViewer != viewer {
+				t.Errorf("Expected viewer %s to be passed through, got %s", viewer, gotViewer)
+			}
+		})
+	}
+}
+
+func TestGetPost_TakenDown(t *testing.T) {
+	const postURI = "at://did:plc:community/social.coves.community.post/3kpost"
+
+	repo := &mockRepository{
+		getViewByURIFunc: func(ctx context.Context, uri, viewerDID string) (*PostView, error) {
+			t.Fatal("Taken-down post should not be loaded")
+			return nil, nil
+		},
+	}
+	service := NewPostService(repo, &mockCommunityService{}, nil, nil, nil, nil, "")
+	service.(*postService).SetTakedowns(&mockTakedownChecker{
+		active: map[string]*takedown.Takedown{postURI: {Reason: takedown.ReasonLegal}},
+	})
+
+	_, err := service.GetPost(context.Background(), GetPostRequest{URI: postURI})
+	var takenDown *takedown.TakenDownError
+	if !errors.As(err, &takenDown) {
+		t.Fatalf("Expected TakenDownError, got %v", err)
+	}
+	if takenDown.Reason != takedown.ReasonLegal {
+		t.Errorf("Expected reason legal, got %s", takenDown.Reason)
+	}
+}
+
+func TestGetPost_InvalidURI(t *testing.T) {
+	tests := map[string]string{
+		"empty":          "",
+		"not an AT-URI":  "https://coves.social/post/3kpost",
+		"comment URI":    "at://did:plc:user/social.coves.community.comment/3kcomment",
+		"collection URI": "at://did:plc:community/social.coves.community.post",
+	}
+
+	service := NewPostService(&mockRepository{}, &mockCommunityService{}, nil, nil, nil, nil, "")
+	for name, uri := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.GetPost(context.Background(), GetPostRequest{URI: uri})
+			if !IsValidationError(err) {
+				t.Errorf("Expected validation error for %q, got %v", uri, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package posts
+
+import (
+	"Coves/internal/core/takedown"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// SetTakedowns enables admin takedown checks on GetPost
+// Without it a taken-down post is reported as plain not found rather than RecordTakenDown.
+func (s *postService) SetTakedowns(checker takedown.Checker) {
+	s.takedowns = checker
+}
+
+// checkNotTakenDown returns a TakenDownError, carrying only the reason category, when the
+// post has an active takedown
+func (s *postService) checkNotTakenDown(ctx context.Context, uri string) error {
+	if s.takedowns == nil {
+		return nil
+	}
+	active, err := s.takedowns.GetActive(ctx, uri)
+	if errors.Is(err, takedown.ErrTakedownNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check takedown of %s: %w", uri, err)
+	}
+	return &takedown.TakenDownError{Reason: active.Reason}
+}
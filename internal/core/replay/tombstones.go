@@ -0,0 +1,31 @@
+// Package replay keeps redelivered firehose events from regressing the index
+//
+// Every commit carries its repo's rev, a TID that orders the repo's commits in time. Indexed
+// rows store the rev of the commit that last wrote them, and the Jetstream consumers skip any
+// event whose rev isn't newer. Records that are hard-deleted leave a tombstone holding the
+// deleting rev, so a create replayed after the delete doesn't bring the record back; a create
+// with a newer rev is a genuine recreation and is indexed.
+package replay
+
+import (
+	"context"
+	"time"
+)
+
+// TombstoneRetention is how long the rev of a deleted record is kept
+// Comfortably longer than Jetstream's replay window: an event older than this can't be
+// redelivered.
+const TombstoneRetention = 7 * 24 * time.Hour
+
+// Tombstones stores the revs of the commits that deleted hard-deleted records
+type Tombstones interface {
+	// Record stores the rev that deleted the record at uri
+	// An older rev than the one already stored is ignored, as is an empty rev.
+	Record(ctx context.Context, uri, rev string) error
+
+	// Rev returns the rev that deleted the record at uri, or "" if it has no tombstone
+	Rev(ctx context.Context, uri string) (string, error)
+
+	// Prune removes tombstones recorded before the cutoff and returns how many were removed
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
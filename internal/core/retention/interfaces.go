@@ -0,0 +1,16 @@
+package retention
+
+import (
+	"context"
+	"time"
+)
+
+// Repository deletes pruned content
+// Implemented by postgres.NewRetentionRepository, which knows this instance's DID and users.
+type Repository interface {
+	// PruneRemotePosts hard-deletes up to limit posts created before cutoff, taken in
+	// (created_at, uri) order after the given key, with their threads' comments and votes.
+	// Posts in communities this instance hosts, and posts its users interacted with, are
+	// skipped. An empty batch means nothing is left to prune.
+	PruneRemotePosts(ctx context.Context, cutoff time.Time, after PostKey, limit int) (Batch, error)
+}
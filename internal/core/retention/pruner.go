@@ -0,0 +1,106 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Pruner deletes remote posts that have outlived the retention window
+type Pruner struct {
+	repo Repository
+	cfg  Config
+}
+
+// NewPruner creates a pruner deleting through repo
+func NewPruner(repo Repository, cfg Config) *Pruner {
+	return &Pruner{repo: repo, cfg: cfg.withDefaults()}
+}
+
+// Config returns the pruner's effective settings
+func (p *Pruner) Config() Config {
+	return p.cfg
+}
+
+// Enabled reports whether a retention window is set
+func (p *Pruner) Enabled() bool {
+	return p.cfg.Window > 0
+}
+
+// Run prunes in batches of BatchSize, sleeping BatchPause between them, until no prunable
+// post is left. Returns what was pruned even when a batch fails or ctx is cancelled.
+func (p *Pruner) Run(ctx context.Context) (*Report, error) {
+	report := &Report{}
+	if !p.Enabled() {
+		return report, nil
+	}
+
+	cutoff := p.cfg.Now().Add(-p.cfg.Window)
+	after := PostKey{}
+	for {
+		batch, err := p.repo.PruneRemotePosts(ctx, cutoff, after, p.cfg.BatchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to prune remote posts: %w", err)
+		}
+		if batch.Posts == 0 {
+			return report, nil
+		}
+		report.add(batch)
+
+		if batch.Posts < int64(p.cfg.BatchSize) {
+			return report, nil
+		}
+		after = batch.Last
+
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+		case <-time.After(p.cfg.BatchPause):
+		}
+	}
+}
+
+// Start runs the pruner now and then every interval until ctx is cancelled
+// A failed run is logged and retried on the next tick.
+func (p *Pruner) Start(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	run := func() {
+		report, err := p.Run(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Remote content pruning failed (will retry): %v", err)
+		}
+		if report.Posts > 0 {
+			log.Printf("Remote content pruning: deleted %d posts, %d comments and %d votes older than %d days in %d batches, reclaiming ~%s",
+				report.Posts, report.Comments, report.Votes, int(p.cfg.Window.Hours()/24), report.Batches, formatBytes(report.Bytes))
+		}
+	}
+	run()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run()
+		}
+	}
+}
+
+// formatBytes renders a byte count with a binary unit, e.g. "1.5 MiB"
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,171 @@
+package retention
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// memoryRepo prunes posts held in (created_at, uri) order, like the Postgres repository
+type memoryRepo struct {
+	err     error
+	cutoffs []time.Time
+	posts   []PostKey
+	calls   int
+	failAt  int // 1-based call that returns err; 0 never fails
+}
+
+func (m *memoryRepo) PruneRemotePosts(ctx context.Context, cutoff time.Time, after PostKey, limit int) (Batch, error) {
+	m.calls++
+	m.cutoffs = append(m.cutoffs, cutoff)
+	if m.calls == m.failAt {
+		return Batch{}, m.err
+	}
+
+	var batch Batch
+	kept := m.posts[:0]
+	for _, post := range m.posts {
+		afterKey := post.CreatedAt.After(after.CreatedAt) ||
+			(post.CreatedAt.Equal(after.CreatedAt) && post.URI > after.URI)
+		if afterKey && post.CreatedAt.Before(cutoff) && batch.Posts < int64(limit) {
+			batch.Posts++
+			batch.Comments += 2
+			batch.Votes += 3
+			batch.Bytes += 100
+			batch.Last = post
+			continue
+		}
+		kept = append(kept, post)
+	}
+	m.posts = kept
+	return batch, nil
+}
+
+func seedPosts(now time.Time, n int, age time.Duration) []PostKey {
+	posts := make([]PostKey, n)
+	for i := range posts {
+		posts[i] = PostKey{
+			CreatedAt: now.Add(-age).Add(time.Duration(i/2) * time.Minute), // pairs share a timestamp
+			URI:       fmt.Sprintf("at://did:plc:remote/social.coves.community.post/%04d", i),
+		}
+	}
+	return posts
+}
+
+func TestPruner_Run_PrunesInBatches(t *testing.T) {
+	now := time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC)
+	repo := &memoryRepo{posts: append(seedPosts(now, 25, 40*24*time.Hour), seedPosts(now, 3, time.Hour)...)}
+
+	pruner := NewPruner(repo, Config{
+		Now:        func() time.Time { return now },
+		Window:     WindowDays(30),
+		BatchSize:  10,
+		BatchPause: time.Millisecond,
+	})
+	report, err := pruner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if report.Posts != 25 || report.Comments != 50 || report.Votes != 75 || report.Bytes != 2500 {
+		t.Errorf("Expected 25 posts, 50 comments, 75 votes and 2500 bytes, got %+v", report)
+	}
+	if report.Batches != 3 || repo.calls != 3 {
+		t.Errorf("Expected 3 batches in 3 calls, got %d batches in %d calls", report.Batches, repo.calls)
+	}
+	if len(repo.posts) != 3 {
+		t.Errorf("Expected the 3 posts inside the window to be kept, got %d", len(repo.posts))
+	}
+	for _, cutoff := range repo.cutoffs {
+		if want := now.Add(-30 * 24 * time.Hour); !cutoff.Equal(want) {
+			t.Errorf("Expected cutoff %s, got %s", want, cutoff)
+		}
+	}
+}
+
+func TestPruner_Run_StopsWhenNothingIsLeft(t *testing.T) {
+	now := time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC)
+	repo := &memoryRepo{posts: seedPosts(now, 10, 40*24*time.Hour)}
+
+	pruner := NewPruner(repo, Config{Now: func() time.Time { return now }, Window: WindowDays(30), BatchSize: 10, BatchPause: time.Millisecond})
+	report, err := pruner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	// A full batch may not be the last one, so one more (empty) batch confirms it
+	if report.Posts != 10 || report.Batches != 1 || repo.calls != 2 {
+		t.Errorf("Expected 10 posts in 1 batch over 2 calls, got %d posts in %d batches over %d calls", report.Posts, report.Batches, repo.calls)
+	}
+}
+
+func TestPruner_Run_Disabled(t *testing.T) {
+	repo := &memoryRepo{posts: seedPosts(time.Now(), 5, 400*24*time.Hour)}
+
+	pruner := NewPruner(repo, Config{Window: WindowDays(0)})
+	if pruner.Enabled() {
+		t.Error("Expected a zero window to disable pruning")
+	}
+	report, err := pruner.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if report.Posts != 0 || repo.calls != 0 {
+		t.Errorf("Expected nothing pruned without a window, got %d posts over %d calls", report.Posts, repo.calls)
+	}
+}
+
+func TestPruner_Run_ReportsPartialProgressOnError(t *testing.T) {
+	now := time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC)
+	repo := &memoryRepo{posts: seedPosts(now, 30, 40*24*time.Hour), failAt: 2, err: errors.New("lock timeout")}
+
+	pruner := NewPruner(repo, Config{Now: func() time.Time { return now }, Window: WindowDays(30), BatchSize: 10, BatchPause: time.Millisecond})
+	report, err := pruner.Run(context.Background())
+	if err == nil || !errors.Is(err, repo.err) {
+		t.Fatalf("Expected the repository error, got %v", err)
+	}
+	if report.Posts != 10 {
+		t.Errorf("Expected the first batch to be reported, got %d posts", report.Posts)
+	}
+}
+
+func TestPruner_Run_CancelledDuringPause(t *testing.T) {
+	now := time.Date(2026, 5, 1, 3, 0, 0, 0, time.UTC)
+	repo := &memoryRepo{posts: seedPosts(now, 30, 40*24*time.Hour)}
+
+	pruner := NewPruner(repo, Config{Now: func() time.Time { return now }, Window: WindowDays(30), BatchSize: 10, BatchPause: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	report, err := pruner.Run(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the run to stop when cancelled, got %v", err)
+	}
+	if report.Posts != 10 || repo.calls != 1 {
+		t.Errorf("Expected one batch before the pause, got %d posts over %d calls", report.Posts, repo.calls)
+	}
+}
+
+func TestConfig_Defaults(t *testing.T) {
+	cfg := NewPruner(&memoryRepo{}, Config{Window: -time.Hour, BatchSize: -1}).Config()
+	if cfg.Window != 0 || cfg.BatchSize != DefaultBatchSize || cfg.BatchPause != DefaultBatchPause || cfg.Now == nil {
+		t.Errorf("Expected defaults, got window %s, batch size %d, pause %s", cfg.Window, cfg.BatchSize, cfg.BatchPause)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1536:            "1.5 KiB",
+		5 * 1024 * 1024: "5.0 MiB",
+		3 << 30:         "3.0 GiB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("formatBytes(%d): expected %q, got %q", n, want, got)
+		}
+	}
+}
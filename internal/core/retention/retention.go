@@ -0,0 +1,97 @@
+// Package retention prunes remote communities' posts once they are older than the instance's
+// retention window.
+//
+// Self-hosters with small disks can set REMOTE_CONTENT_RETENTION_DAYS to index remote posts
+// for a limited time only. Each night the Pruner hard-deletes posts older than the window,
+// with their threads' comments and votes, except:
+//   - posts in communities hosted by this instance, and
+//   - posts one of the instance's users interacted with: wrote, commented on anywhere in the
+//     thread, or voted on (the post or any of its comments).
+//
+// The Repository decides who the instance's users are. Coves has no saved posts yet; once it
+// does, saves must protect posts the same way.
+//
+// Feeds paginate by keyset (the last post's sort key, not its row), so a cursor whose post
+// has since been pruned keeps working and continues from where that post sat.
+package retention
+
+import (
+	"time"
+)
+
+// Default pruning settings
+const (
+	// DefaultInterval is how often the pruner runs
+	DefaultInterval = 24 * time.Hour
+
+	// DefaultBatchSize is how many posts one delete statement removes at most
+	DefaultBatchSize = 200
+
+	// DefaultBatchPause is how long the pruner sleeps between batches, so replicas keep up
+	// and the feed queries aren't starved of locks
+	DefaultBatchPause = 2 * time.Second
+)
+
+// Config configures pruning
+// Zero batch settings use the defaults above.
+type Config struct {
+	Now        func() time.Time // Clock the window is measured from; defaults to time.Now
+	Window     time.Duration    // Remote posts older than this are pruned; 0 keeps them forever
+	BatchSize  int              // Posts deleted per statement
+	BatchPause time.Duration    // Sleep between batches
+}
+
+// WindowDays converts REMOTE_CONTENT_RETENTION_DAYS into a window; 0 keeps content forever
+func WindowDays(days int) time.Duration {
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// withDefaults fills zero (or invalid) batch settings
+func (c Config) withDefaults() Config {
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+	if c.Window < 0 {
+		c.Window = 0
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultBatchSize
+	}
+	if c.BatchPause <= 0 {
+		c.BatchPause = DefaultBatchPause
+	}
+	return c
+}
+
+// PostKey is a post's position in pruning order: (created_at, uri)
+type PostKey struct {
+	CreatedAt time.Time
+	URI       string
+}
+
+// Batch is what one delete statement removed
+type Batch struct {
+	Last     PostKey // Key of the last post deleted; the next batch starts after it
+	Posts    int64
+	Comments int64
+	Votes    int64
+	Bytes    int64 // On-disk size of the deleted rows, reclaimed once Postgres vacuums
+}
+
+// Report summarizes one pruning run
+type Report struct {
+	Posts    int64
+	Comments int64
+	Votes    int64
+	Bytes    int64
+	Batches  int
+}
+
+// add totals a batch into the report
+func (r *Report) add(b Batch) {
+	r.Posts += b.Posts
+	r.Comments += b.Comments
+	r.Votes += b.Votes
+	r.Bytes += b.Bytes
+	r.Batches++
+}
@@ -0,0 +1,39 @@
+package scheduledposts
+
+import (
+	"fmt"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+var (
+	// ErrNotModerator is returned when someone other than the community's moderators schedules,
+	// lists or cancels its posts
+	ErrNotModerator = coreerrors.Sentinel(coreerrors.ErrForbidden, "only community moderators can schedule posts")
+
+	// ErrScheduledPostNotFound is returned for unknown scheduled posts, and for ones that were
+	// already published or cancelled when cancelling
+	ErrScheduledPostNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "scheduled post not found")
+
+	// ErrCommunityNotHosted is returned for communities whose PDS credentials this instance doesn't hold
+	ErrCommunityNotHosted = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "posts can only be scheduled in communities hosted on this instance")
+
+	// ErrCommunityUnavailable is returned for suspended and deleted communities
+	ErrCommunityUnavailable = coreerrors.Sentinel(coreerrors.ErrForbidden, "community is suspended or deleted")
+
+	// ErrPublishAtInPast is returned when publishAt isn't in the future
+	ErrPublishAtInPast = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "publishAt must be in the future")
+
+	// ErrPublishAtTooFar is returned when publishAt is more than MaxScheduleAhead away
+	ErrPublishAtTooFar = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "publishAt must be within a year")
+
+	// ErrInvalidRecurrence is returned for recurrences other than none and weekly
+	ErrInvalidRecurrence = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "recurrence must be 'none' or 'weekly'")
+
+	// ErrEmptyPost is returned when the scheduled post has neither a title nor content
+	ErrEmptyPost = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "a scheduled post needs a title or content")
+
+	// ErrTooManyScheduled is returned when the community already has MaxPendingPerCommunity pending posts
+	ErrTooManyScheduled = coreerrors.Sentinel(coreerrors.ErrInvalidInput,
+		fmt.Sprintf("a community can have at most %d scheduled posts", MaxPendingPerCommunity))
+)
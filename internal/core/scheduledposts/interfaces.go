@@ -0,0 +1,91 @@
+package scheduledposts
+
+import (
+	"context"
+	"time"
+
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/communities"
+)
+
+// ScheduleRequest is the social.coves.community.schedulePost input
+type ScheduleRequest struct {
+	PublishAt  time.Time  `json:"publishAt"`
+	Post       Payload    `json:"post"`
+	Community  string     `json:"community"` // DID or handle
+	Recurrence Recurrence `json:"recurrence,omitempty"`
+	ActorDID   string     `json:"-"`
+}
+
+// ListRequest is a page of a community's pending and failed scheduled posts, soonest first
+type ListRequest struct {
+	Community string
+	ActorDID  string
+	Limit     int
+	Offset    int
+}
+
+// CancelRequest is the social.coves.community.cancelScheduledPost input
+type CancelRequest struct {
+	ActorDID string `json:"-"`
+	ID       int64  `json:"id"`
+}
+
+// Repository stores scheduled posts
+type Repository interface {
+	// Create inserts a pending scheduled post, setting its ID and CreatedAt
+	Create(ctx context.Context, post *ScheduledPost) error
+
+	// Get returns a scheduled post by ID, or ErrScheduledPostNotFound
+	Get(ctx context.Context, id int64) (*ScheduledPost, error)
+
+	// ListByCommunity returns the community's pending and failed posts ordered by PublishAt
+	ListByCommunity(ctx context.Context, communityDID string, limit, offset int) ([]*ScheduledPost, error)
+
+	// CountPending returns how many of the community's posts are pending
+	CountPending(ctx context.Context, communityDID string) (int, error)
+
+	// Cancel cancels a pending or failed post, returning ErrScheduledPostNotFound otherwise
+	Cancel(ctx context.Context, id int64, actorDID string) error
+
+	// ClaimDue returns up to limit pending posts whose NextAttemptAt is at or before now,
+	// pushing their NextAttemptAt to leaseUntil so no other publisher picks them up meanwhile
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*ScheduledPost, error)
+
+	// SaveAttempt stores the outcome of a publication attempt: status, PublishAt,
+	// NextAttemptAt, attempts, last error and last published post
+	// Posts cancelled while they were being published stay cancelled.
+	SaveAttempt(ctx context.Context, post *ScheduledPost) error
+
+	// NotifyModerators notifies the community's creator and moderators that the post at
+	// postURI (not yet written) keeps failing to publish
+	NotifyModerators(ctx context.Context, post *ScheduledPost, postURI string) error
+}
+
+// ModeratorChecker reports whether someone moderates a community
+type ModeratorChecker interface {
+	IsCommunityModerator(ctx context.Context, communityDID, actorDID string) (bool, error)
+}
+
+// Communities is the part of communities.Service scheduling uses to find communities and act
+// as their accounts
+type Communities interface {
+	ResolveCommunityIdentifier(ctx context.Context, identifier string) (string, error)
+	GetByDID(ctx context.Context, did string) (*communities.Community, error)
+	EnsureFreshToken(ctx context.Context, community *communities.Community) (*communities.Community, error)
+}
+
+// PDSClientFactory creates a PDS client acting as the community account
+type PDSClientFactory func(host, did, accessToken string) (pds.Client, error)
+
+// Service lets community moderators schedule posts
+type Service interface {
+	// SchedulePost stores a post to be published to the community at PublishAt
+	SchedulePost(ctx context.Context, req ScheduleRequest) (*ScheduledPost, error)
+
+	// ListScheduledPosts returns the community's pending and failed scheduled posts
+	ListScheduledPosts(ctx context.Context, req ListRequest) ([]*ScheduledPost, error)
+
+	// CancelScheduledPost stops a scheduled post from being published again
+	CancelScheduledPost(ctx context.Context, req CancelRequest) error
+}
@@ -0,0 +1,261 @@
+package scheduledposts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"Coves/internal/atproto/pds"
+)
+
+// Default publisher settings
+const (
+	DefaultInterval    = time.Minute
+	DefaultBatchSize   = 20
+	DefaultClaimLease  = 5 * time.Minute // Longer than a publication can take
+	DefaultRetryBase   = time.Minute
+	DefaultRetryMax    = time.Hour
+	DefaultAlertAfter  = 3  // Failed attempts before moderators are notified
+	DefaultMaxAttempts = 10 // Failed attempts before a publication is given up on
+)
+
+// errPermanent marks publication failures that retrying can't fix
+var errPermanent = errors.New("permanent failure")
+
+// Config configures the publisher
+// Zero values use the defaults above.
+type Config struct {
+	Now         func() time.Time // Clock deciding which posts are due; defaults to time.Now
+	Interval    time.Duration    // How often Start looks for due posts
+	BatchSize   int              // Posts published per run
+	ClaimLease  time.Duration    // How long a claimed post is hidden from other publishers
+	RetryBase   time.Duration    // Delay after the first failed attempt, doubling after each
+	RetryMax    time.Duration    // ...up to this
+	AlertAfter  int              // Moderators are notified once a publication has failed this many times
+	MaxAttempts int              // One-off posts fail, and weekly posts skip to the next week, after this many
+}
+
+// withDefaults fills zero (or invalid) settings with the defaults
+func (c Config) withDefaults() Config {
+	if c.Now == nil {
+		c.Now = time.Now
+	}
+	if c.Interval <= 0 {
+		c.Interval = DefaultInterval
+	}
+	if c.BatchSize <= 0 {
+		c.BatchSize = DefaultBatchSize
+	}
+	if c.ClaimLease <= 0 {
+		c.ClaimLease = DefaultClaimLease
+	}
+	if c.RetryBase <= 0 {
+		c.RetryBase = DefaultRetryBase
+	}
+	if c.RetryMax < c.RetryBase {
+		c.RetryMax = DefaultRetryMax
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = DefaultMaxAttempts
+	}
+	if c.AlertAfter <= 0 || c.AlertAfter > c.MaxAttempts {
+		c.AlertAfter = min(DefaultAlertAfter, c.MaxAttempts)
+	}
+	return c
+}
+
+// Report summarizes one publisher run
+type Report struct {
+	Published int // Publications written to the community's PDS
+	Retrying  int // Failed publications that will be retried
+	Failed    int // Publications given up on
+	Alerts    int // Moderator notifications sent
+}
+
+// Publisher publishes due scheduled posts by writing them to their community's repo with the
+// community's stored credentials, so they're indexed through the firehose like any other post
+type Publisher struct {
+	repo        Repository
+	communities Communities
+	moderators  ModeratorChecker
+	newClient   PDSClientFactory
+	cfg         Config
+}
+
+// NewPublisher creates a publisher writing to community PDSs over their access tokens
+// moderators decides whether a post's scheduler still moderates the community when it's due.
+func NewPublisher(repo Repository, communityService Communities, moderators ModeratorChecker, cfg Config) *Publisher {
+	return NewPublisherWithFactory(repo, communityService, moderators, pds.NewFromAccessToken, cfg)
+}
+
+// NewPublisherWithFactory is NewPublisher with a custom PDS client factory
+func NewPublisherWithFactory(repo Repository, communityService Communities, moderators ModeratorChecker, factory PDSClientFactory, cfg Config) *Publisher {
+	return &Publisher{repo: repo, communities: communityService, moderators: moderators, newClient: factory, cfg: cfg.withDefaults()}
+}
+
+// Config returns the publisher's effective settings
+func (p *Publisher) Config() Config {
+	return p.cfg
+}
+
+// Run publishes one batch of due posts
+// Each post's outcome is saved before the next is published; a failure to save stops the run.
+func (p *Publisher) Run(ctx context.Context) (*Report, error) {
+	now := p.cfg.Now()
+	due, err := p.repo.ClaimDue(ctx, now, now.Add(p.cfg.ClaimLease), p.cfg.BatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due scheduled posts: %w", err)
+	}
+
+	report := &Report{}
+	for _, post := range due {
+		if err := p.publish(ctx, post, now, report); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// Start runs the publisher every Interval until ctx is cancelled
+// A failed run is logged and retried on the next tick.
+func (p *Publisher) Start(ctx context.Context) {
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := p.Run(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Scheduled post publisher failed (will retry): %v", err)
+			}
+			if report != nil && (report.Published > 0 || report.Retrying > 0 || report.Failed > 0) {
+				log.Printf("Scheduled post publisher: published %d, retrying %d, failed %d, notified moderators of %d",
+					report.Published, report.Retrying, report.Failed, report.Alerts)
+			}
+		}
+	}
+}
+
+// publish writes one post's current publication and saves the outcome
+func (p *Publisher) publish(ctx context.Context, post *ScheduledPost, now time.Time, report *Report) error {
+	uri, err := p.write(ctx, post, now)
+	if err == nil {
+		report.Published++
+		post.LastPublishedURI = uri
+		post.LastPublishedAt = &now
+		post.LastError = ""
+		post.Attempts = 0
+		p.advance(post, now)
+		log.Printf("Published scheduled post %d to %s", post.ID, uri)
+	} else {
+		log.Printf("Failed to publish scheduled post %d to %s (attempt %d): %v", post.ID, post.CommunityDID, post.Attempts+1, err)
+		if err := p.recordFailure(ctx, post, err, now, report); err != nil {
+			return err
+		}
+	}
+
+	if err := p.repo.SaveAttempt(ctx, post); err != nil {
+		return fmt.Errorf("failed to save scheduled post %d: %w", post.ID, err)
+	}
+	return nil
+}
+
+// recordFailure counts a failed attempt, notifying moderators once it has failed AlertAfter
+// times, and schedules the retry or gives up
+func (p *Publisher) recordFailure(ctx context.Context, post *ScheduledPost, cause error, now time.Time, report *Report) error {
+	post.Attempts++
+	post.LastError = cause.Error()
+	if len(post.LastError) > maxErrorLength {
+		post.LastError = post.LastError[:maxErrorLength]
+	}
+
+	permanent := errors.Is(cause, errPermanent)
+	if post.Attempts == p.cfg.AlertAfter || (permanent && post.Attempts < p.cfg.AlertAfter) {
+		if err := p.repo.NotifyModerators(ctx, post, post.PostURI()); err != nil {
+			return fmt.Errorf("failed to notify moderators of scheduled post %d: %w", post.ID, err)
+		}
+		report.Alerts++
+	}
+
+	if permanent || post.Attempts >= p.cfg.MaxAttempts {
+		report.Failed++
+		if post.Recurrence == RecurrenceWeekly && !permanent {
+			post.Attempts = 0
+			p.advance(post, now)
+			return nil
+		}
+		post.Status = StatusFailed
+		return nil
+	}
+
+	report.Retrying++
+	post.NextAttemptAt = now.Add(p.backoff(post.Attempts))
+	return nil
+}
+
+// advance moves a weekly post to its next publication, and marks a one-off post published
+func (p *Publisher) advance(post *ScheduledPost, now time.Time) {
+	if post.Recurrence != RecurrenceWeekly {
+		post.Status = StatusPublished
+		return
+	}
+	post.PublishAt = nextOccurrence(post.PublishAt, now)
+	post.NextAttemptAt = post.PublishAt
+}
+
+// backoff is the delay before retrying after the given number of failed attempts
+func (p *Publisher) backoff(attempts int) time.Duration {
+	delay := p.cfg.RetryBase
+	for i := 1; i < attempts && delay < p.cfg.RetryMax; i++ {
+		delay *= 2
+	}
+	return min(delay, p.cfg.RetryMax)
+}
+
+// write creates the post record in the community's repo and returns its URI
+// The record key is fixed per publication, so a retry after a lost response finds the record
+// the PDS already wrote instead of creating a duplicate.
+func (p *Publisher) write(ctx context.Context, post *ScheduledPost, now time.Time) (string, error) {
+	community, err := p.communities.GetByDID(ctx, post.CommunityDID)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch community: %w", err)
+	}
+	if community.SuspendedAt != nil || community.DeletedAt != nil {
+		return "", fmt.Errorf("%w: community is suspended or deleted", errPermanent)
+	}
+	if community.PDSAccessToken == "" {
+		return "", fmt.Errorf("%w: no PDS credentials for community", errPermanent)
+	}
+	// The post is published as its scheduler's, so it's only published while they still
+	// moderate the community; a removed moderator's posts fail rather than go out later
+	isModerator, err := p.moderators.IsCommunityModerator(ctx, post.CommunityDID, post.CreatedByDID)
+	if err != nil {
+		return "", fmt.Errorf("failed to check moderator of %s: %w", post.CommunityDID, err)
+	}
+	if !isModerator {
+		return "", fmt.Errorf("%w: %s no longer moderates the community", errPermanent, post.CreatedByDID)
+	}
+
+	community, err = p.communities.EnsureFreshToken(ctx, community)
+	if err != nil {
+		return "", fmt.Errorf("failed to refresh community credentials: %w", err)
+	}
+	client, err := p.newClient(community.PDSURL, community.DID, community.PDSAccessToken)
+	if err != nil {
+		return "", fmt.Errorf("failed to create PDS client: %w", err)
+	}
+
+	rkey := post.RKey()
+	uri, _, err := client.CreateRecord(ctx, postCollection, rkey, post.Payload.Record(community, post.CreatedByDID, now))
+	if err != nil {
+		if existing, getErr := client.GetRecord(ctx, postCollection, rkey); getErr == nil {
+			return existing.URI, nil
+		}
+		return "", fmt.Errorf("failed to write post to PDS: %w", err)
+	}
+	return uri, nil
+}
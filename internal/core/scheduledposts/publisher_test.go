@@ -0,0 +1,470 @@
+package scheduledposts
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/blobs"
+	"Coves/internal/core/communities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCommunityDID = "did:plc:weeklythreads"
+	testModeratorDID = "did:plc:moderator"
+)
+
+// fakeRepo keeps scheduled posts in memory
+type fakeRepo struct {
+	posts    map[int64]*ScheduledPost
+	notified []string // Post URIs moderators were notified about
+	nextID   int64
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{posts: make(map[int64]*ScheduledPost)}
+}
+
+func (r *fakeRepo) Create(_ context.Context, post *ScheduledPost) error {
+	r.nextID++
+	post.ID = r.nextID
+	post.CreatedAt = time.Now()
+	stored := *post
+	r.posts[post.ID] = &stored
+	return nil
+}
+
+func (r *fakeRepo) Get(_ context.Context, id int64) (*ScheduledPost, error) {
+	post, ok := r.posts[id]
+	if !ok {
+		return nil, ErrScheduledPostNotFound
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (r *fakeRepo) ListByCommunity(_ context.Context, communityDID string, limit, offset int) ([]*ScheduledPost, error) {
+	list := []*ScheduledPost{}
+	for _, post := range r.posts {
+		if post.CommunityDID == communityDID && (post.Status == StatusPending || post.Status == StatusFailed) {
+			copied := *post
+			list = append(list, &copied)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].PublishAt.Before(list[j].PublishAt) })
+	if offset >= len(list) {
+		return []*ScheduledPost{}, nil
+	}
+	list = list[offset:]
+	if len(list) > limit {
+		list = list[:limit]
+	}
+	return list, nil
+}
+
+func (r *fakeRepo) CountPending(_ context.Context, communityDID string) (int, error) {
+	count := 0
+	for _, post := range r.posts {
+		if post.CommunityDID == communityDID && post.Status == StatusPending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeRepo) Cancel(_ context.Context, id int64, _ string) error {
+	post, ok := r.posts[id]
+	if !ok || (post.Status != StatusPending && post.Status != StatusFailed) {
+		return ErrScheduledPostNotFound
+	}
+	post.Status = StatusCancelled
+	return nil
+}
+
+func (r *fakeRepo) ClaimDue(_ context.Context, now, leaseUntil time.Time, limit int) ([]*ScheduledPost, error) {
+	var due []*ScheduledPost
+	for _, post := range r.posts {
+		if post.Status == StatusPending && !post.NextAttemptAt.After(now) {
+			post.NextAttemptAt = leaseUntil
+			copied := *post
+			due = append(due, &copied)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].PublishAt.Before(due[j].PublishAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
+func (r *fakeRepo) SaveAttempt(_ context.Context, post *ScheduledPost) error {
+	stored, ok := r.posts[post.ID]
+	if !ok || stored.Status != StatusPending {
+		return nil
+	}
+	copied := *post
+	r.posts[post.ID] = &copied
+	return nil
+}
+
+func (r *fakeRepo) NotifyModerators(_ context.Context, _ *ScheduledPost, postURI string) error {
+	r.notified = append(r.notified, postURI)
+	return nil
+}
+
+// fakeCommunities serves one community hosted on this instance
+type fakeCommunities struct {
+	community *communities.Community
+}
+
+func newFakeCommunities() *fakeCommunities {
+	return &fakeCommunities{community: &communities.Community{
+		DID:            testCommunityDID,
+		Handle:         "weeklythreads.community.coves.social",
+		PDSURL:         "https://pds.coves.social",
+		PDSAccessToken: "community-token",
+	}}
+}
+
+func (f *fakeCommunities) ResolveCommunityIdentifier(_ context.Context, identifier string) (string, error) {
+	if identifier == f.community.DID || identifier == f.community.Handle {
+		return f.community.DID, nil
+	}
+	return "", communities.ErrCommunityNotFound
+}
+
+func (f *fakeCommunities) GetByDID(_ context.Context, did string) (*communities.Community, error) {
+	if did != f.community.DID {
+		return nil, communities.ErrCommunityNotFound
+	}
+	copied := *f.community
+	return &copied, nil
+}
+
+func (f *fakeCommunities) EnsureFreshToken(_ context.Context, community *communities.Community) (*communities.Community, error) {
+	return community, nil
+}
+
+// mockPDSClient records created records; createErr fails every create
+type mockPDSClient struct {
+	records   map[string]map[string]any // rkey -> record
+	createErr error
+	creates   int
+}
+
+func newMockPDSClient() *mockPDSClient {
+	return &mockPDSClient{records: make(map[string]map[string]any)}
+}
+
+func (m *mockPDSClient) CreateRecord(_ context.Context, collection, rkey string, record any) (string, string, error) {
+	m.creates++
+	if m.createErr != nil {
+		return "", "", m.createErr
+	}
+	if _, exists := m.records[rkey]; exists {
+		return "", "", pds.ErrConflict
+	}
+	m.records[rkey] = map[string]any{"record": record}
+	return "at://" + testCommunityDID + "/" + collection + "/" + rkey, "bafypost", nil
+}
+
+func (m *mockPDSClient) GetRecord(_ context.Context, collection, rkey string) (*pds.RecordResponse, error) {
+	record, ok := m.records[rkey]
+	if !ok {
+		return nil, pds.ErrNotFound
+	}
+	return &pds.RecordResponse{URI: "at://" + testCommunityDID + "/" + collection + "/" + rkey, CID: "bafypost", Value: record}, nil
+}
+
+func (m *mockPDSClient) DeleteRecord(context.Context, string, string) error { return nil }
+
+func (m *mockPDSClient) ListRecords(context.Context, string, int, string) (*pds.ListRecordsResponse, error) {
+	return &pds.ListRecordsResponse{}, nil
+}
+
+func (m *mockPDSClient) PutRecord(context.Context, string, string, any, string) (string, string, error) {
+	return "", "", nil
+}
+
+func (m *mockPDSClient) ApplyWrites(context.Context, string, []pds.WriteOp) ([]pds.WriteResult, error) {
+	return nil, nil
+}
+
+func (m *mockPDSClient) UploadBlob(context.Context, []byte, string) (*blobs.BlobRef, error) {
+	return nil, nil
+}
+
+func (m *mockPDSClient) DID() string     { return testCommunityDID }
+func (m *mockPDSClient) HostURL() string { return "https://pds.coves.social" }
+
+// testClock is a settable clock
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+type publisherFixture struct {
+	repo       *fakeRepo
+	client     *mockPDSClient
+	clock      *testClock
+	moderators fakeModerators
+	publisher  *Publisher
+}
+
+func newPublisherFixture(t *testing.T) *publisherFixture {
+	t.Helper()
+	f := &publisherFixture{
+		repo:       newFakeRepo(),
+		client:     newMockPDSClient(),
+		clock:      &testClock{now: time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)},
+		moderators: fakeModerators{testCommunityDID + " " + testModeratorDID: true},
+	}
+	factory := func(host, did, accessToken string) (pds.Client, error) {
+		assert.Equal(t, "community-token", accessToken)
+		return f.client, nil
+	}
+	f.publisher = NewPublisherWithFactory(f.repo, newFakeCommunities(), f.moderators, factory, Config{Now: f.clock.Now})
+	return f
+}
+
+// schedule stores a pending post due at publishAt
+func (f *publisherFixture) schedule(t *testing.T, publishAt time.Time, recurrence Recurrence) *ScheduledPost {
+	t.Helper()
+	title := "Weekly discussion thread"
+	post := &ScheduledPost{
+		CommunityDID:  testCommunityDID,
+		CreatedByDID:  testModeratorDID,
+		Payload:       Payload{Title: &title},
+		PublishAt:     publishAt,
+		NextAttemptAt: publishAt,
+		Recurrence:    recurrence,
+		Status:        StatusPending,
+	}
+	require.NoError(t, f.repo.Create(context.Background(), post))
+	return post
+}
+
+func (f *publisherFixture) run(t *testing.T) *Report {
+	t.Helper()
+	report, err := f.publisher.Run(context.Background())
+	require.NoError(t, err)
+	return report
+}
+
+func TestPublisher_PublishesDuePosts(t *testing.T) {
+	f := newPublisherFixture(t)
+	due := f.schedule(t, f.clock.now.Add(-time.Second), RecurrenceNone)
+	later := f.schedule(t, f.clock.now.Add(time.Hour), RecurrenceNone)
+
+	report := f.run(t)
+	assert.Equal(t, 1, report.Published)
+	assert.Equal(t, 1, f.client.creates)
+
+	published := f.repo.posts[due.ID]
+	assert.Equal(t, StatusPublished, published.Status)
+	assert.Equal(t, due.PostURI(), published.LastPublishedURI)
+	require.NotNil(t, published.LastPublishedAt)
+	assert.Equal(t, StatusPending, f.repo.posts[later.ID].Status)
+
+	// The published record is the moderator's post in the community
+	record := f.client.records[due.RKey()]["record"]
+	require.NotNil(t, record)
+	assert.Contains(t, published.LastPublishedURI, testCommunityDID)
+
+	// Published posts aren't published again
+	report = f.run(t)
+	assert.Equal(t, 0, report.Published)
+	assert.Equal(t, 1, f.client.creates)
+}
+
+func TestPublisher_PublishesOverduePostsOnce(t *testing.T) {
+	f := newPublisherFixture(t)
+	// Due three days ago, e.g. while the AppView was down
+	overdue := f.schedule(t, f.clock.now.Add(-72*time.Hour), RecurrenceNone)
+
+	report := f.run(t)
+	assert.Equal(t, 1, report.Published)
+	assert.Equal(t, StatusPublished, f.repo.posts[overdue.ID].Status)
+
+	f.clock.now = f.clock.now.Add(time.Minute)
+	assert.Equal(t, 0, f.run(t).Published)
+}
+
+func TestPublisher_WeeklyPostsAreRescheduled(t *testing.T) {
+	f := newPublisherFixture(t)
+	first := f.clock.now
+	weekly := f.schedule(t, first, RecurrenceWeekly)
+
+	require.Equal(t, 1, f.run(t).Published)
+	stored := f.repo.posts[weekly.ID]
+	assert.Equal(t, StatusPending, stored.Status)
+	assert.Equal(t, first.Add(7*24*time.Hour), stored.PublishAt)
+	assert.Equal(t, stored.PublishAt, stored.NextAttemptAt)
+	firstURI := stored.LastPublishedURI
+
+	// Nothing more until next week
+	f.clock.now = first.Add(6 * 24 * time.Hour)
+	assert.Equal(t, 0, f.run(t).Published)
+
+	// Next week's post gets its own record key
+	f.clock.now = first.Add(7 * 24 * time.Hour)
+	require.Equal(t, 1, f.run(t).Published)
+	stored = f.repo.posts[weekly.ID]
+	assert.NotEqual(t, firstURI, stored.LastPublishedURI)
+	assert.Equal(t, first.Add(14*24*time.Hour), stored.PublishAt)
+	assert.Len(t, f.client.records, 2)
+}
+
+func TestPublisher_OverdueWeeklyPostSkipsMissedWeeks(t *testing.T) {
+	f := newPublisherFixture(t)
+	// Due 17 days ago: publish once now, then resume the weekly slot
+	start := f.clock.now.Add(-17 * 24 * time.Hour)
+	weekly := f.schedule(t, start, RecurrenceWeekly)
+
+	require.Equal(t, 1, f.run(t).Published)
+	stored := f.repo.posts[weekly.ID]
+	assert.Equal(t, start.Add(21*24*time.Hour), stored.PublishAt)
+	assert.True(t, stored.PublishAt.After(f.clock.now))
+	assert.Equal(t, 0, f.run(t).Published)
+}
+
+func TestPublisher_RetriesWithBackoffAndNotifiesModerators(t *testing.T) {
+	f := newPublisherFixture(t)
+	f.client.createErr = errors.New("PDS request failed: connection refused")
+	post := f.schedule(t, f.clock.now, RecurrenceNone)
+
+	wantDelays := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute}
+	for attempt, delay := range wantDelays {
+		report := f.run(t)
+		require.Equal(t, 1, report.Retrying, "attempt %d", attempt+1)
+		stored := f.repo.posts[post.ID]
+		assert.Equal(t, attempt+1, stored.Attempts)
+		assert.Equal(t, f.clock.now.Add(delay), stored.NextAttemptAt)
+		assert.Contains(t, stored.LastError, "connection refused")
+
+		// Not due again before the backoff is up
+		f.clock.now = f.clock.now.Add(delay - time.Second)
+		assert.Equal(t, 0, f.run(t).Retrying)
+		f.clock.now = f.clock.now.Add(time.Second)
+	}
+
+	// Moderators hear about it once, after the third failure
+	assert.Equal(t, []string{post.PostURI()}, f.repo.notified)
+
+	// The PDS recovers: the post is published and the error cleared
+	f.client.createErr = nil
+	require.Equal(t, 1, f.run(t).Published)
+	stored := f.repo.posts[post.ID]
+	assert.Equal(t, StatusPublished, stored.Status)
+	assert.Empty(t, stored.LastError)
+	assert.Len(t, f.repo.notified, 1)
+}
+
+func TestPublisher_GivesUpAfterMaxAttempts(t *testing.T) {
+	f := newPublisherFixture(t)
+	f.publisher = NewPublisherWithFactory(f.repo, newFakeCommunities(), f.moderators, func(string, string, string) (pds.Client, error) {
+		return f.client, nil
+	}, Config{Now: f.clock.Now, MaxAttempts: 2, AlertAfter: 1, RetryBase: time.Minute, RetryMax: time.Minute})
+	f.client.createErr = errors.New("PDS returned error 500")
+	oneOff := f.schedule(t, f.clock.now, RecurrenceNone)
+	weekly := f.schedule(t, f.clock.now, RecurrenceWeekly)
+
+	report := f.run(t)
+	assert.Equal(t, 2, report.Retrying)
+	assert.Equal(t, 2, report.Alerts)
+
+	f.clock.now = f.clock.now.Add(time.Minute)
+	report = f.run(t)
+	assert.Equal(t, 2, report.Failed)
+
+	assert.Equal(t, StatusFailed, f.repo.posts[oneOff.ID].Status)
+
+	// A weekly post gives up on this week only
+	stored := f.repo.posts[weekly.ID]
+	assert.Equal(t, StatusPending, stored.Status)
+	assert.Equal(t, 0, stored.Attempts)
+	assert.Equal(t, weekly.PublishAt.Add(7*24*time.Hour), stored.PublishAt)
+}
+
+func TestPublisher_FindsRecordWrittenBeforeLostResponse(t *testing.T) {
+	f := newPublisherFixture(t)
+	post := f.schedule(t, f.clock.now, RecurrenceNone)
+	// An earlier attempt wrote the record, but its response never arrived
+	f.client.records[post.RKey()] = map[string]any{}
+
+	require.Equal(t, 1, f.run(t).Published)
+	assert.Equal(t, post.PostURI(), f.repo.posts[post.ID].LastPublishedURI)
+	assert.Len(t, f.client.records, 1)
+}
+
+func TestPublisher_SuspendedCommunityFailsImmediately(t *testing.T) {
+	f := newPublisherFixture(t)
+	communityService := newFakeCommunities()
+	suspendedAt := f.clock.now
+	communityService.community.SuspendedAt = &suspendedAt
+	f.publisher = NewPublisherWithFactory(f.repo, communityService, f.moderators, func(string, string, string) (pds.Client, error) {
+		return f.client, nil
+	}, Config{Now: f.clock.Now})
+	post := f.schedule(t, f.clock.now, RecurrenceWeekly)
+
+	report := f.run(t)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Alerts)
+	assert.Equal(t, StatusFailed, f.repo.posts[post.ID].Status)
+	assert.True(t, strings.Contains(f.repo.posts[post.ID].LastError, "suspended"))
+	assert.Equal(t, 0, f.client.creates)
+}
+
+func TestPublisher_FormerModeratorFailsImmediately(t *testing.T) {
+	f := newPublisherFixture(t)
+	post := f.schedule(t, f.clock.now, RecurrenceWeekly)
+	// The moderator who scheduled the post is removed before it's due
+	delete(f.moderators, testCommunityDID+" "+testModeratorDID)
+
+	report := f.run(t)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Alerts)
+	assert.Equal(t, StatusFailed, f.repo.posts[post.ID].Status)
+	assert.Contains(t, f.repo.posts[post.ID].LastError, "no longer moderates")
+	assert.Equal(t, 0, f.client.creates)
+}
+
+func TestPublisher_CancelledWhilePublishingStaysCancelled(t *testing.T) {
+	f := newPublisherFixture(t)
+	post := f.schedule(t, f.clock.now, RecurrenceWeekly)
+	claimed, err := f.repo.ClaimDue(context.Background(), f.clock.now, f.clock.now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, claimed, 1)
+
+	require.NoError(t, f.repo.Cancel(context.Background(), post.ID, testModeratorDID))
+	require.NoError(t, f.publisher.publish(context.Background(), claimed[0], f.clock.now, &Report{}))
+	assert.Equal(t, StatusCancelled, f.repo.posts[post.ID].Status)
+}
+
+func TestNextOccurrence(t *testing.T) {
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+	tests := []struct {
+		name string
+		now  time.Time
+		want time.Time
+	}{
+		{"on time", start, start.Add(week)},
+		{"a day late", start.Add(24 * time.Hour), start.Add(week)},
+		{"exactly a week late", start.Add(week), start.Add(2 * week)},
+		{"three weeks and a bit late", start.Add(3*week + time.Hour), start.Add(4 * week)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nextOccurrence(start, tt.now))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package scheduledposts
+
+import (
+	"time"
+
+	"Coves/internal/core/communities"
+	"Coves/internal/core/posts"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+// postCollection is the collection scheduled posts are published to
+const postCollection = "social.coves.community.post"
+
+// Scheduling limits
+const (
+	// MaxScheduleAhead is how far in the future a post can be scheduled
+	MaxScheduleAhead = 365 * 24 * time.Hour
+
+	// MaxPendingPerCommunity caps a community's scheduled posts that haven't been published or cancelled
+	MaxPendingPerCommunity = 50
+
+	DefaultListLimit = 50
+	MaxListLimit     = 100
+
+	// maxErrorLength truncates the stored error of a failed publish
+	maxErrorLength = 500
+)
+
+// Recurrence is how often a scheduled post is published
+type Recurrence string
+
+const (
+	RecurrenceNone   Recurrence = "none"   // Published once
+	RecurrenceWeekly Recurrence = "weekly" // Published every week at the same time, until cancelled
+)
+
+// IsValid reports whether the recurrence is known
+func (r Recurrence) IsValid() bool {
+	return r == RecurrenceNone || r == RecurrenceWeekly
+}
+
+// Status is where a scheduled post is in its lifecycle
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // Waiting for its next publication, or retrying one
+	StatusPublished Status = "published" // One-off post that was published
+	StatusCancelled Status = "cancelled" // Cancelled by a moderator
+	StatusFailed    Status = "failed"    // One-off post the publisher gave up on
+)
+
+// Payload is the post a moderator schedules: the author-controlled fields of the post record
+// The community, author and createdAt are filled in when it's published.
+type Payload struct {
+	Title   *string                `json:"title,omitempty"`
+	Content *string                `json:"content,omitempty"`
+	Embed   map[string]interface{} `json:"embed,omitempty"`
+	Labels  *posts.SelfLabels      `json:"labels,omitempty"`
+	Facets  []interface{}          `json:"facets,omitempty"`
+	Tags    []string               `json:"tags,omitempty"` // Flair names; tags not in the community's flair set are dropped when published
+}
+
+// Record builds the post record published to the community's repo
+// Embeds are published as given: links aren't unfurled and thumbnails aren't uploaded.
+func (p Payload) Record(community *communities.Community, authorDID string, createdAt time.Time) posts.PostRecord {
+	return posts.PostRecord{
+		Type:      postCollection,
+		Community: community.DID,
+		Author:    authorDID,
+		Title:     p.Title,
+		Content:   p.Content,
+		Facets:    p.Facets,
+		Embed:     p.Embed,
+		Labels:    p.Labels,
+		Tags:      community.FilterPostTags(p.Tags),
+		CreatedAt: createdAt.UTC().Format(time.RFC3339),
+	}
+}
+
+// ScheduledPost is a post waiting to be published to a community by the publisher
+type ScheduledPost struct {
+	PublishAt        time.Time  `json:"publishAt"` // The next (or only) publication
+	NextAttemptAt    time.Time  `json:"-"`         // PublishAt, or later while retrying a failed publication
+	CreatedAt        time.Time  `json:"createdAt"`
+	LastPublishedAt  *time.Time `json:"lastPublishedAt,omitempty"`
+	Payload          Payload    `json:"post"`
+	CommunityDID     string     `json:"community"`
+	CreatedByDID     string     `json:"createdBy"` // Moderator who scheduled it; the published post's author
+	Recurrence       Recurrence `json:"recurrence"`
+	Status           Status     `json:"status"`
+	LastError        string     `json:"lastError,omitempty"`
+	LastPublishedURI string     `json:"lastPublishedUri,omitempty"`
+	ID               int64      `json:"id"`
+	Attempts         int        `json:"attempts"` // Failed attempts at the current publication
+}
+
+// RKey is the record key of the post for the current publication
+// It's derived from the schedule and PublishAt so a retry after a lost PDS response writes
+// (or finds) the same record instead of publishing a duplicate.
+func (s *ScheduledPost) RKey() string {
+	return syntax.NewTIDFromTime(s.PublishAt, uint(s.ID%1024)).String()
+}
+
+// PostURI is the AT-URI the current publication is (or will be) published at
+func (s *ScheduledPost) PostURI() string {
+	return "at://" + s.CommunityDID + "/" + postCollection + "/" + s.RKey()
+}
+
+// nextOccurrence returns the first weekly occurrence of publishAt after now
+// A weekly post overdue by several weeks is published once, not once per missed week.
+func nextOccurrence(publishAt, now time.Time) time.Time {
+	const week = 7 * 24 * time.Hour
+	next := publishAt.Add(week)
+	if next.After(now) {
+		return next
+	}
+	missed := now.Sub(next)/week + 1
+	return next.Add(missed * week)
+}
@@ -0,0 +1,177 @@
+package scheduledposts
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"Coves/internal/core/audit"
+	"Coves/internal/core/communities"
+	"Coves/internal/lexicon/validate"
+)
+
+type scheduleService struct {
+	repo        Repository
+	communities Communities
+	moderators  ModeratorChecker
+	auditLog    audit.Recorder // Optional; nil records nothing
+	now         func() time.Time
+}
+
+// NewService creates a scheduling service; moderators decides who may schedule a community's posts
+func NewService(repo Repository, communityService Communities, moderators ModeratorChecker) Service {
+	return &scheduleService{repo: repo, communities: communityService, moderators: moderators, now: time.Now}
+}
+
+// SetAuditLog records scheduling and cancellation to the audit log
+func (s *scheduleService) SetAuditLog(recorder audit.Recorder) {
+	s.auditLog = recorder
+}
+
+// recordAudit adds a successful action on a scheduled post to the audit log, if one is configured
+func (s *scheduleService) recordAudit(ctx context.Context, actorDID, action string, post *ScheduledPost) {
+	if s.auditLog != nil {
+		s.auditLog.Record(ctx, actorDID, action, strconv.FormatInt(post.ID, 10), post)
+	}
+}
+
+// SchedulePost validates the post as it would be published and stores it
+// Only communities this instance holds PDS credentials for can schedule posts, since the
+// publisher writes to the community's repo as the community.
+func (s *scheduleService) SchedulePost(ctx context.Context, req ScheduleRequest) (*ScheduledPost, error) {
+	if req.Recurrence == "" {
+		req.Recurrence = RecurrenceNone
+	}
+	if !req.Recurrence.IsValid() {
+		return nil, ErrInvalidRecurrence
+	}
+	now := s.now()
+	if !req.PublishAt.After(now) {
+		return nil, ErrPublishAtInPast
+	}
+	if req.PublishAt.Sub(now) > MaxScheduleAhead {
+		return nil, ErrPublishAtTooFar
+	}
+	if (req.Post.Title == nil || *req.Post.Title == "") && (req.Post.Content == nil || *req.Post.Content == "") {
+		return nil, ErrEmptyPost
+	}
+
+	community, err := s.authorize(ctx, req.Community, req.ActorDID)
+	if err != nil {
+		return nil, err
+	}
+	if community.PDSAccessToken == "" {
+		return nil, ErrCommunityNotHosted
+	}
+	if community.SuspendedAt != nil || community.DeletedAt != nil {
+		return nil, ErrCommunityUnavailable
+	}
+
+	// Validate with the consumer's rules now, rather than failing at publication time
+	if err := validate.Value(validate.PostCollection, req.Post.Record(community, req.ActorDID, req.PublishAt)); err != nil {
+		return nil, err
+	}
+
+	pending, err := s.repo.CountPending(ctx, community.DID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count scheduled posts: %w", err)
+	}
+	if pending >= MaxPendingPerCommunity {
+		return nil, ErrTooManyScheduled
+	}
+
+	post := &ScheduledPost{
+		CommunityDID:  community.DID,
+		CreatedByDID:  req.ActorDID,
+		Payload:       req.Post,
+		PublishAt:     req.PublishAt.UTC(),
+		NextAttemptAt: req.PublishAt.UTC(),
+		Recurrence:    req.Recurrence,
+		Status:        StatusPending,
+	}
+	if err := s.repo.Create(ctx, post); err != nil {
+		return nil, fmt.Errorf("failed to store scheduled post: %w", err)
+	}
+
+	log.Printf("%s scheduled post %d in %s for %s (recurrence: %s)",
+		req.ActorDID, post.ID, community.DID, post.PublishAt.Format(time.RFC3339), post.Recurrence)
+	s.recordAudit(ctx, req.ActorDID, audit.ActionSchedulePost, post)
+	return post, nil
+}
+
+// ListScheduledPosts returns a page of the community's pending and failed scheduled posts
+func (s *scheduleService) ListScheduledPosts(ctx context.Context, req ListRequest) ([]*ScheduledPost, error) {
+	if req.Limit <= 0 {
+		req.Limit = DefaultListLimit
+	}
+	if req.Limit > MaxListLimit {
+		req.Limit = MaxListLimit
+	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
+
+	community, err := s.authorize(ctx, req.Community, req.ActorDID)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.repo.ListByCommunity(ctx, community.DID, req.Limit, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled posts: %w", err)
+	}
+	return list, nil
+}
+
+// CancelScheduledPost cancels a pending or failed scheduled post
+// A weekly post's earlier publications stay published.
+func (s *scheduleService) CancelScheduledPost(ctx context.Context, req CancelRequest) error {
+	post, err := s.repo.Get(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkModerator(ctx, post.CommunityDID, req.ActorDID); err != nil {
+		return err
+	}
+	if err := s.repo.Cancel(ctx, post.ID, req.ActorDID); err != nil {
+		return err
+	}
+
+	log.Printf("%s cancelled scheduled post %d in %s", req.ActorDID, post.ID, post.CommunityDID)
+	s.recordAudit(ctx, req.ActorDID, audit.ActionCancelScheduledPost, post)
+	return nil
+}
+
+// authorize resolves the community and checks the actor moderates it
+func (s *scheduleService) authorize(ctx context.Context, identifier, actorDID string) (*communities.Community, error) {
+	if identifier == "" {
+		return nil, communities.NewValidationError("community", "community is required")
+	}
+	communityDID, err := s.communities.ResolveCommunityIdentifier(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkModerator(ctx, communityDID, actorDID); err != nil {
+		return nil, err
+	}
+	community, err := s.communities.GetByDID(ctx, communityDID)
+	if err != nil {
+		return nil, err
+	}
+	return community, nil
+}
+
+func (s *scheduleService) checkModerator(ctx context.Context, communityDID, actorDID string) error {
+	if actorDID == "" {
+		return ErrNotModerator
+	}
+	isModerator, err := s.moderators.IsCommunityModerator(ctx, communityDID, actorDID)
+	if err != nil {
+		return fmt.Errorf("failed to check moderator of %s: %w", communityDID, err)
+	}
+	if !isModerator {
+		return ErrNotModerator
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package scheduledposts
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	coreerrors "Coves/internal/core/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeModerators reports a fixed set of moderators per community
+type fakeModerators map[string]bool
+
+func (f fakeModerators) IsCommunityModerator(_ context.Context, communityDID, actorDID string) (bool, error) {
+	return f[communityDID+" "+actorDID], nil
+}
+
+func newTestService(repo *fakeRepo, communityService *fakeCommunities, now time.Time) *scheduleService {
+	svc := NewService(repo, communityService, fakeModerators{testCommunityDID + " " + testModeratorDID: true}).(*scheduleService)
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestSchedulePost(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	title := "Weekly discussion thread"
+	content := "What are you working on this week?"
+	valid := ScheduleRequest{
+		Community:  "weeklythreads.community.coves.social",
+		ActorDID:   testModeratorDID,
+		PublishAt:  now.Add(24 * time.Hour),
+		Recurrence: RecurrenceWeekly,
+		Post:       Payload{Title: &title, Content: &content},
+	}
+
+	t.Run("stores a pending post for a moderator", func(t *testing.T) {
+		repo := newFakeRepo()
+		post, err := newTestService(repo, newFakeCommunities(), now).SchedulePost(context.Background(), valid)
+		require.NoError(t, err)
+		assert.Equal(t, testCommunityDID, post.CommunityDID)
+		assert.Equal(t, StatusPending, post.Status)
+		assert.Equal(t, valid.PublishAt, post.NextAttemptAt)
+		assert.Contains(t, repo.posts, post.ID)
+	})
+
+	t.Run("recurrence defaults to none", func(t *testing.T) {
+		req := valid
+		req.Recurrence = ""
+		post, err := newTestService(newFakeRepo(), newFakeCommunities(), now).SchedulePost(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, RecurrenceNone, post.Recurrence)
+	})
+
+	longTitle := strings.Repeat("a", 400)
+	tests := []struct {
+		modify  func(*ScheduleRequest)
+		wantErr error
+		name    string
+	}{
+		{name: "not a moderator", modify: func(r *ScheduleRequest) { r.ActorDID = "did:plc:member" }, wantErr: ErrNotModerator},
+		{name: "in the past", modify: func(r *ScheduleRequest) { r.PublishAt = now.Add(-time.Minute) }, wantErr: ErrPublishAtInPast},
+		{name: "too far ahead", modify: func(r *ScheduleRequest) { r.PublishAt = now.Add(MaxScheduleAhead + time.Hour) }, wantErr: ErrPublishAtTooFar},
+		{name: "unknown recurrence", modify: func(r *ScheduleRequest) { r.Recurrence = "daily" }, wantErr: ErrInvalidRecurrence},
+		{name: "empty post", modify: func(r *ScheduleRequest) { r.Post = Payload{} }, wantErr: ErrEmptyPost},
+		{name: "invalid record", modify: func(r *ScheduleRequest) { r.Post = Payload{Title: &longTitle} }, wantErr: coreerrors.ErrInvalidInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			tt.modify(&req)
+			_, err := newTestService(newFakeRepo(), newFakeCommunities(), now).SchedulePost(context.Background(), req)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+
+	t.Run("community not hosted here", func(t *testing.T) {
+		communityService := newFakeCommunities()
+		communityService.community.PDSAccessToken = ""
+		_, err := newTestService(newFakeRepo(), communityService, now).SchedulePost(context.Background(), valid)
+		assert.ErrorIs(t, err, ErrCommunityNotHosted)
+	})
+
+	t.Run("too many pending posts", func(t *testing.T) {
+		repo := newFakeRepo()
+		svc := newTestService(repo, newFakeCommunities(), now)
+		for i := 0; i < MaxPendingPerCommunity; i++ {
+			_, err := svc.SchedulePost(context.Background(), valid)
+			require.NoError(t, err)
+		}
+		_, err := svc.SchedulePost(context.Background(), valid)
+		assert.ErrorIs(t, err, ErrTooManyScheduled)
+	})
+}
+
+func TestListAndCancelScheduledPosts(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := newFakeRepo()
+	svc := newTestService(repo, newFakeCommunities(), now)
+	content := "Thread"
+	schedule := func(after time.Duration) *ScheduledPost {
+		post, err := svc.SchedulePost(context.Background(), ScheduleRequest{
+			Community: testCommunityDID,
+			ActorDID:  testModeratorDID,
+			PublishAt: now.Add(after),
+			Post:      Payload{Content: &content},
+		})
+		require.NoError(t, err)
+		return post
+	}
+	later := schedule(48 * time.Hour)
+	sooner := schedule(24 * time.Hour)
+
+	list, err := svc.ListScheduledPosts(context.Background(), ListRequest{Community: testCommunityDID, ActorDID: testModeratorDID})
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, sooner.ID, list[0].ID)
+
+	_, err = svc.ListScheduledPosts(context.Background(), ListRequest{Community: testCommunityDID, ActorDID: "did:plc:member"})
+	assert.ErrorIs(t, err, ErrNotModerator)
+
+	err = svc.CancelScheduledPost(context.Background(), CancelRequest{ID: later.ID, ActorDID: "did:plc:member"})
+	assert.ErrorIs(t, err, ErrNotModerator)
+
+	require.NoError(t, svc.CancelScheduledPost(context.Background(), CancelRequest{ID: later.ID, ActorDID: testModeratorDID}))
+	list, err = svc.ListScheduledPosts(context.Background(), ListRequest{Community: testCommunityDID, ActorDID: testModeratorDID})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, sooner.ID, list[0].ID)
+
+	// Cancelling twice, or an unknown post, is not found
+	err = svc.CancelScheduledPost(context.Background(), CancelRequest{ID: later.ID, ActorDID: testModeratorDID})
+	assert.ErrorIs(t, err, ErrScheduledPostNotFound)
+	err = svc.CancelScheduledPost(context.Background(), CancelRequest{ID: 999, ActorDID: testModeratorDID})
+	assert.ErrorIs(t, err, ErrScheduledPostNotFound)
+}
@@ -0,0 +1,301 @@
+package spamguard
+
+import (
+	coreerrors "Coves/internal/core/errors"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default spam heuristics
+const (
+	DefaultNewAccountAge         = 24 * time.Hour
+	DefaultEstablishedAccountAge = 30 * 24 * time.Hour
+	DefaultDuplicateWindow       = 10 * time.Minute
+	DefaultDuplicateThreshold    = 3
+	DefaultRateWindow            = time.Minute
+	DefaultRateThreshold         = 5
+	DefaultQuarantineScore       = 3
+)
+
+// Signals a post can trip, reported as quarantine reasons
+const (
+	ReasonNewAccount      = "new_account"      // Author's account is younger than NewAccountAge
+	ReasonLinkOnly        = "link_only"        // A link with no body text
+	ReasonRepeatedContent = "repeated_content" // Same link or text posted to many communities in DuplicateWindow
+	ReasonHighRate        = "high_rate"        // Author posted more than RateThreshold times in RateWindow
+)
+
+// Signal weights; a single weak signal never quarantines a post on its own at the default score
+const (
+	newAccountWeight      = 1
+	linkOnlyWeight        = 1
+	repeatedContentWeight = 2
+	highRateWeight        = 2
+)
+
+// Config configures the spam heuristics
+// Zero values use the defaults above.
+type Config struct {
+	Now                   func() time.Time // Clock posts are indexed by; defaults to time.Now
+	AdminDIDs             []string         // Instance admins bypass the guard
+	NewAccountAge         time.Duration    // Accounts younger than this trip new_account
+	EstablishedAccountAge time.Duration    // Accounts at least this old bypass the guard
+	DuplicateWindow       time.Duration
+	DuplicateThreshold    int // Same content in more than this many communities trips repeated_content
+	RateWindow            time.Duration
+	RateThreshold         int // More posts than this per RateWindow trips high_rate
+	QuarantineScore       int // Posts scoring at least this are quarantined
+}
+
+// DefaultConfig returns the default heuristics
+func DefaultConfig() Config {
+	return Config{
+		NewAccountAge:         DefaultNewAccountAge,
+		EstablishedAccountAge: DefaultEstablishedAccountAge,
+		DuplicateWindow:       DefaultDuplicateWindow,
+		DuplicateThreshold:    DefaultDuplicateThreshold,
+		RateWindow:            DefaultRateWindow,
+		RateThreshold:         DefaultRateThreshold,
+		QuarantineScore:       DefaultQuarantineScore,
+	}
+}
+
+// Post is what the guard needs to know about a post being indexed
+type Post struct {
+	URI          string
+	AuthorDID    string
+	CommunityDID string
+	Title        string
+	Content      string
+	LinkHash     string    // posts.LinkHash of the external embed's URL, empty without one
+	CreatedAt    time.Time // The record's createdAt; zero when it has none
+}
+
+// Signals are the measurements a post is scored on
+type Signals struct {
+	AccountAge  time.Duration
+	Duplicates  int // Communities the same content was posted to in DuplicateWindow, this one included
+	RecentPosts int // Posts by the author in RateWindow, this one included
+	LinkOnly    bool
+}
+
+// Verdict is the outcome of screening a post
+type Verdict struct {
+	Reasons    []string `json:"reasons"`
+	Score      int      `json:"score"`
+	Quarantine bool     `json:"quarantine"`
+}
+
+// Checker screens posts at index time
+type Checker interface {
+	Check(ctx context.Context, post Post) (*Verdict, error)
+}
+
+// Guard scores posts from new accounts against spam-wave heuristics
+// Established accounts and instance admins are never screened.
+type Guard struct {
+	repo      Repository
+	history   AccountHistory
+	now       func() time.Time
+	admins    map[string]bool
+	cfg       Config
+	pruneMu   sync.Mutex
+	lastPrune time.Time
+}
+
+// NewGuard creates a guard recording post fingerprints in repo
+func NewGuard(repo Repository, cfg Config) *Guard {
+	defaults := DefaultConfig()
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	if cfg.NewAccountAge <= 0 {
+		cfg.NewAccountAge = defaults.NewAccountAge
+	}
+	if cfg.EstablishedAccountAge <= 0 {
+		cfg.EstablishedAccountAge = defaults.EstablishedAccountAge
+	}
+	if cfg.DuplicateWindow <= 0 {
+		cfg.DuplicateWindow = defaults.DuplicateWindow
+	}
+	if cfg.DuplicateThreshold <= 0 {
+		cfg.DuplicateThreshold = defaults.DuplicateThreshold
+	}
+	if cfg.RateWindow <= 0 {
+		cfg.RateWindow = defaults.RateWindow
+	}
+	if cfg.RateThreshold <= 0 {
+		cfg.RateThreshold = defaults.RateThreshold
+	}
+	if cfg.QuarantineScore <= 0 {
+		cfg.QuarantineScore = defaults.QuarantineScore
+	}
+
+	admins := make(map[string]bool, len(cfg.AdminDIDs))
+	for _, did := range cfg.AdminDIDs {
+		admins[did] = true
+	}
+
+	return &Guard{repo: repo, now: cfg.Now, admins: admins, cfg: cfg}
+}
+
+// SetAccountHistory sets where account creation times are looked up
+// Without one, or for accounts it has no history for, an account's age is measured from when
+// this AppView first indexed it.
+func (g *Guard) SetAccountHistory(history AccountHistory) {
+	g.history = history
+}
+
+// Score turns signals into a verdict
+func (g *Guard) Score(signals Signals) *Verdict {
+	verdict := &Verdict{}
+	trip := func(reason string, weight int) {
+		verdict.Reasons = append(verdict.Reasons, reason)
+		verdict.Score += weight
+	}
+
+	if signals.AccountAge < g.cfg.NewAccountAge {
+		trip(ReasonNewAccount, newAccountWeight)
+	}
+	if signals.LinkOnly {
+		trip(ReasonLinkOnly, linkOnlyWeight)
+	}
+	if signals.Duplicates > g.cfg.DuplicateThreshold {
+		trip(ReasonRepeatedContent, repeatedContentWeight)
+	}
+	if signals.RecentPosts > g.cfg.RateThreshold {
+		trip(ReasonHighRate, highRateWeight)
+	}
+
+	verdict.Quarantine = verdict.Score >= g.cfg.QuarantineScore
+	return verdict
+}
+
+// Check records the post and scores it against the author's recent activity and the
+// same content posted elsewhere
+// The post is placed at its record's createdAt, clamped to now since authors control it, so a
+// backlog replayed at once isn't mistaken for a burst. Replays of an already recorded post
+// aren't counted twice.
+func (g *Guard) Check(ctx context.Context, post Post) (*Verdict, error) {
+	if g.admins[post.AuthorDID] {
+		return &Verdict{}, nil
+	}
+
+	now := g.now()
+	postedAt := now
+	if !post.CreatedAt.IsZero() && post.CreatedAt.Before(now) {
+		postedAt = post.CreatedAt
+	}
+
+	accountAge, err := g.accountAge(ctx, post.AuthorDID, postedAt)
+	if err != nil {
+		return nil, err
+	}
+	if accountAge >= g.cfg.EstablishedAccountAge {
+		return &Verdict{}, nil
+	}
+
+	fingerprint := Fingerprint(post)
+	if err := g.repo.RecordPost(ctx, post.URI, post.AuthorDID, post.CommunityDID, fingerprint, postedAt); err != nil {
+		return nil, fmt.Errorf("failed to record post fingerprint: %w", err)
+	}
+
+	signals := Signals{
+		AccountAge: accountAge,
+		LinkOnly:   post.LinkHash != "" && strings.TrimSpace(post.Content) == "",
+	}
+	if fingerprint != "" {
+		signals.Duplicates, err = g.repo.CountCommunitiesWithFingerprint(ctx, fingerprint, postedAt.Add(-g.cfg.DuplicateWindow), postedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count repeated content: %w", err)
+		}
+	}
+	signals.RecentPosts, err = g.repo.CountPostsByAuthor(ctx, post.AuthorDID, postedAt.Add(-g.cfg.RateWindow), postedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recent posts: %w", err)
+	}
+
+	g.pruneIfDue(ctx, now)
+	return g.Score(signals), nil
+}
+
+// accountAge returns how old the author's account was when they posted
+// The creation time comes from the account's identity history, saved after the first lookup;
+// when there's no history or it can't be reached, from when the author was first indexed.
+// Authors we haven't indexed yet are as new as it gets.
+func (g *Guard) accountAge(ctx context.Context, did string, postedAt time.Time) (time.Duration, error) {
+	createdAt, found, err := g.repo.GetAccountCreatedAt(ctx, did)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get account age: %w", err)
+	}
+
+	if !found && g.history != nil {
+		createdAt, err = g.history.AccountCreatedAt(ctx, did)
+		switch {
+		case err == nil:
+			found = true
+			// Best effort: an unsaved creation time is looked up again on the author's next post
+			if saveErr := g.repo.SaveAccountCreatedAt(ctx, did, createdAt); saveErr != nil {
+				log.Printf("Warning: Failed to save account creation time for %s: %v", did, saveErr)
+			}
+		case errors.Is(err, coreerrors.ErrNotFound):
+			// No history to go on, such as a did:web account
+		default:
+			log.Printf("Warning: Failed to look up account creation time for %s, using first indexed time: %v", did, err)
+		}
+	}
+
+	if !found {
+		createdAt, found, err = g.repo.GetFirstIndexedAt(ctx, did)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get account age: %w", err)
+		}
+		if !found {
+			return 0, nil
+		}
+	}
+	return postedAt.Sub(createdAt), nil
+}
+
+// pruneIfDue drops fingerprints older than both windows, at most once per window
+func (g *Guard) pruneIfDue(ctx context.Context, now time.Time) {
+	retention := g.cfg.DuplicateWindow
+	if g.cfg.RateWindow > retention {
+		retention = g.cfg.RateWindow
+	}
+
+	g.pruneMu.Lock()
+	due := now.Sub(g.lastPrune) >= retention
+	if due {
+		g.lastPrune = now
+	}
+	g.pruneMu.Unlock()
+
+	if due {
+		// Best effort: stale rows only cost space, the counts are windowed anyway
+		if err := g.repo.PruneBefore(ctx, now.Add(-retention)); err != nil {
+			log.Printf("Warning: Failed to prune spam fingerprints: %v", err)
+		}
+	}
+}
+
+// Fingerprint identifies a post's content across communities: its link when it has one,
+// otherwise its case- and whitespace-normalized text. Empty posts have no fingerprint.
+func Fingerprint(post Post) string {
+	if post.LinkHash != "" {
+		return "link:" + post.LinkHash
+	}
+	text := strings.Join(strings.Fields(strings.ToLower(post.Title+" "+post.Content)), " ")
+	if text == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(text))
+	return "text:" + hex.EncodeToString(sum[:])
+}
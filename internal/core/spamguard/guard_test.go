@@ -0,0 +1,323 @@
+package spamguard
+
+import (
+	coreerrors "Coves/internal/core/errors"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// mockRepository keeps recorded posts in memory
+type mockRepository struct {
+	accounts map[string]time.Time // creation times saved from identity history
+	indexed  map[string]time.Time // when users were first indexed
+	posts    map[string]recordedPost
+	pruned   time.Time
+}
+
+type recordedPost struct {
+	at           time.Time
+	authorDID    string
+	communityDID string
+	fingerprint  string
+}
+
+func newMockRepository() *mockRepository {
+	return &mockRepository{
+		accounts: make(map[string]time.Time),
+		indexed:  make(map[string]time.Time),
+		posts:    make(map[string]recordedPost),
+	}
+}
+
+func (m *mockRepository) GetAccountCreatedAt(ctx context.Context, did string) (time.Time, bool, error) {
+	createdAt, ok := m.accounts[did]
+	return createdAt, ok, nil
+}
+
+func (m *mockRepository) SaveAccountCreatedAt(ctx context.Context, did string, createdAt time.Time) error {
+	m.accounts[did] = createdAt
+	return nil
+}
+
+func (m *mockRepository) GetFirstIndexedAt(ctx context.Context, did string) (time.Time, bool, error) {
+	indexedAt, ok := m.indexed[did]
+	return indexedAt, ok, nil
+}
+
+func (m *mockRepository) RecordPost(ctx context.Context, uri, authorDID, communityDID, fingerprint string, at time.Time) error {
+	if _, ok := m.posts[uri]; !ok {
+		m.posts[uri] = recordedPost{at: at, authorDID: authorDID, communityDID: communityDID, fingerprint: fingerprint}
+	}
+	return nil
+}
+
+func (m *mockRepository) CountCommunitiesWithFingerprint(ctx context.Context, fingerprint string, since, until time.Time) (int, error) {
+	seen := make(map[string]bool)
+	for _, p := range m.posts {
+		if p.fingerprint == fingerprint && !p.at.Before(since) && !p.at.After(until) {
+			seen[p.communityDID] = true
+		}
+	}
+	return len(seen), nil
+}
+
+func (m *mockRepository) CountPostsByAuthor(ctx context.Context, authorDID string, since, until time.Time) (int, error) {
+	count := 0
+	for _, p := range m.posts {
+		if p.authorDID == authorDID && !p.at.Before(since) && !p.at.After(until) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockRepository) PruneBefore(ctx context.Context, before time.Time) error {
+	m.pruned = before
+	return nil
+}
+
+// stubHistory serves fixed account creation times and counts lookups
+type stubHistory struct {
+	created map[string]time.Time
+	err     error
+	lookups int
+}
+
+func (h *stubHistory) AccountCreatedAt(ctx context.Context, did string) (time.Time, error) {
+	h.lookups++
+	if h.err != nil {
+		return time.Time{}, h.err
+	}
+	createdAt, ok := h.created[did]
+	if !ok {
+		return time.Time{}, coreerrors.ErrNotFound
+	}
+	return createdAt, nil
+}
+
+func TestScore(t *testing.T) {
+	guard := NewGuard(newMockRepository(), Config{})
+
+	tests := []struct {
+		name           string
+		wantReasons    []string
+		signals        Signals
+		wantScore      int
+		wantQuarantine bool
+	}{
+		{
+			name:    "week-old account posting normally",
+			signals: Signals{AccountAge: 7 * 24 * time.Hour, Duplicates: 1, RecentPosts: 1},
+		},
+		{
+			name:        "new account with a link-only post",
+			signals:     Signals{AccountAge: time.Hour, LinkOnly: true, Duplicates: 1, RecentPosts: 1},
+			wantReasons: []string{ReasonNewAccount, ReasonLinkOnly},
+			wantScore:   2,
+		},
+		{
+			name:           "new account repeating a link across communities",
+			signals:        Signals{AccountAge: time.Hour, LinkOnly: true, Duplicates: 4, RecentPosts: 4},
+			wantReasons:    []string{ReasonNewAccount, ReasonLinkOnly, ReasonRepeatedContent},
+			wantScore:      4,
+			wantQuarantine: true,
+		},
+		{
+			name:           "new account posting in a burst",
+			signals:        Signals{AccountAge: time.Hour, Duplicates: 1, RecentPosts: 6},
+			wantReasons:    []string{ReasonNewAccount, ReasonHighRate},
+			wantScore:      3,
+			wantQuarantine: true,
+		},
+		{
+			name:        "older account repeating content at the threshold",
+			signals:     Signals{AccountAge: 3 * 24 * time.Hour, Duplicates: 3, RecentPosts: 5},
+			wantReasons: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict := guard.Score(tt.signals)
+			if verdict.Score != tt.wantScore || verdict.Quarantine != tt.wantQuarantine {
+				t.Errorf("Expected score %d (quarantine %v), got %+v", tt.wantScore, tt.wantQuarantine, verdict)
+			}
+			if !reflect.DeepEqual(verdict.Reasons, tt.wantReasons) {
+				t.Errorf("Expected reasons %v, got %v", tt.wantReasons, verdict.Reasons)
+			}
+		})
+	}
+}
+
+func TestScore_ConfigurableThresholds(t *testing.T) {
+	guard := NewGuard(newMockRepository(), Config{DuplicateThreshold: 1, QuarantineScore: 2})
+	verdict := guard.Score(Signals{AccountAge: 7 * 24 * time.Hour, Duplicates: 2, RecentPosts: 1})
+	if !verdict.Quarantine {
+		t.Errorf("Expected a lower threshold and score to quarantine repeated content, got %+v", verdict)
+	}
+}
+
+func TestCheck_SpamBurstFromNewAccount(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := newMockRepository()
+	repo.accounts["did:plc:fresh"] = now.Add(-time.Hour)
+	guard := NewGuard(repo, Config{Now: func() time.Time { return now }})
+	ctx := context.Background()
+
+	post := func(i int) *Verdict {
+		verdict, err := guard.Check(ctx, Post{
+			URI:          "at://did:plc:c" + string(rune('a'+i)) + "/social.coves.community.post/1",
+			AuthorDID:    "did:plc:fresh",
+			CommunityDID: "did:plc:c" + string(rune('a'+i)),
+			Title:        "Great deal",
+			LinkHash:     "samehash",
+		})
+		if err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		return verdict
+	}
+
+	for i := 0; i < DefaultDuplicateThreshold; i++ {
+		if verdict := post(i); verdict.Quarantine {
+			t.Fatalf("Post %d quarantined before the threshold: %+v", i, verdict)
+		}
+	}
+	if verdict := post(DefaultDuplicateThreshold); !verdict.Quarantine {
+		t.Errorf("Expected the post past the duplicate threshold to be quarantined, got %+v", verdict)
+	}
+
+	// A replay of the same post isn't counted again
+	before := len(repo.posts)
+	post(0)
+	if len(repo.posts) != before {
+		t.Error("Expected a replayed post not to be recorded twice")
+	}
+	if repo.pruned.IsZero() {
+		t.Error("Expected old fingerprints to be pruned")
+	}
+}
+
+func TestCheck_Bypasses(t *testing.T) {
+	now := time.Now()
+	repo := newMockRepository()
+	repo.indexed["did:plc:veteran"] = now.Add(-365 * 24 * time.Hour)
+	guard := NewGuard(repo, Config{AdminDIDs: []string{"did:plc:admin"}, Now: func() time.Time { return now }})
+
+	for _, did := range []string{"did:plc:veteran", "did:plc:admin"} {
+		verdict, err := guard.Check(context.Background(), Post{URI: "at://x/" + did, AuthorDID: did, CommunityDID: "did:plc:c", LinkHash: "h"})
+		if err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		if verdict.Score != 0 {
+			t.Errorf("Expected %s to bypass the guard, got %+v", did, verdict)
+		}
+	}
+	if len(repo.posts) != 0 {
+		t.Error("Expected bypassed posts not to be recorded")
+	}
+}
+
+func TestCheck_UnknownAuthorIsNew(t *testing.T) {
+	guard := NewGuard(newMockRepository(), Config{})
+	verdict, err := guard.Check(context.Background(), Post{URI: "at://x/1", AuthorDID: "did:plc:unknown", CommunityDID: "did:plc:c", Content: "hello"})
+	if err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if !reflect.DeepEqual(verdict.Reasons, []string{ReasonNewAccount}) {
+		t.Errorf("Expected an unindexed author to count as a new account, got %v", verdict.Reasons)
+	}
+}
+
+func TestCheck_AccountAgeFromIdentityHistory(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := newMockRepository()
+	// First indexed today, but the account is a year old
+	repo.indexed["did:plc:migrated"] = now.Add(-time.Hour)
+	history := &stubHistory{created: map[string]time.Time{"did:plc:migrated": now.Add(-365 * 24 * time.Hour)}}
+	guard := NewGuard(repo, Config{Now: func() time.Time { return now }})
+	guard.SetAccountHistory(history)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		verdict, err := guard.Check(ctx, Post{URI: "at://x/" + string(rune('a'+i)), AuthorDID: "did:plc:migrated", CommunityDID: "did:plc:c", LinkHash: "h"})
+		if err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		if verdict.Score != 0 {
+			t.Errorf("Expected an established account to bypass the guard, got %+v", verdict)
+		}
+	}
+	if history.lookups != 1 {
+		t.Errorf("Expected the creation time to be looked up once and saved, got %d lookups", history.lookups)
+	}
+
+	// A fresh account with no history, or one whose directory can't be reached, falls back to
+	// when it was first indexed
+	repo.indexed["did:web:fresh.example"] = now.Add(-time.Hour)
+	repo.indexed["did:plc:unreachable"] = now.Add(-time.Hour)
+	history.err = errors.New("connection refused")
+	for _, did := range []string{"did:web:fresh.example", "did:plc:unreachable"} {
+		verdict, err := guard.Check(ctx, Post{URI: "at://x/" + did, AuthorDID: did, CommunityDID: "did:plc:c", Content: "hello"})
+		if err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		if !reflect.DeepEqual(verdict.Reasons, []string{ReasonNewAccount}) {
+			t.Errorf("Expected %s to be screened as a new account, got %v", did, verdict.Reasons)
+		}
+	}
+	if _, saved := repo.accounts["did:plc:unreachable"]; saved {
+		t.Error("Expected a failed lookup not to be saved")
+	}
+}
+
+func TestCheck_UsesRecordCreatedAt(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := newMockRepository()
+	repo.indexed["did:plc:fresh"] = now.Add(-48 * time.Hour)
+	guard := NewGuard(repo, Config{Now: func() time.Time { return now }})
+	ctx := context.Background()
+
+	// A backlog posted an hour apart and replayed at once isn't a burst
+	for i := 0; i <= DefaultRateThreshold; i++ {
+		verdict, err := guard.Check(ctx, Post{
+			URI:          "at://x/backlog" + string(rune('a'+i)),
+			AuthorDID:    "did:plc:fresh",
+			CommunityDID: "did:plc:c",
+			Content:      "post " + string(rune('a'+i)),
+			CreatedAt:    now.Add(-time.Duration(10-i) * time.Hour),
+		})
+		if err != nil {
+			t.Fatalf("Check failed: %v", err)
+		}
+		if verdict.Quarantine {
+			t.Fatalf("Expected a replayed backlog not to trip high_rate, got %+v", verdict)
+		}
+	}
+
+	// A createdAt in the future is clamped to now rather than escaping the window
+	future := Post{URI: "at://x/future", AuthorDID: "did:plc:fresh", CommunityDID: "did:plc:c", Content: "later", CreatedAt: now.Add(24 * time.Hour)}
+	if _, err := guard.Check(ctx, future); err != nil {
+		t.Fatalf("Check failed: %v", err)
+	}
+	if at := repo.posts[future.URI].at; !at.Equal(now) {
+		t.Errorf("Expected a future createdAt to be recorded at %s, got %s", now, at)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a := Fingerprint(Post{Title: "Buy  NOW", Content: "cheap\nstuff"})
+	b := Fingerprint(Post{Title: "buy now", Content: "Cheap stuff "})
+	if a == "" || a != b {
+		t.Errorf("Expected normalized text to share a fingerprint, got %q and %q", a, b)
+	}
+	if Fingerprint(Post{Title: "Buy now", LinkHash: "h"}) != "link:h" {
+		t.Error("Expected posts with a link to be fingerprinted by the link")
+	}
+	if Fingerprint(Post{}) != "" {
+		t.Error("Expected empty posts to have no fingerprint")
+	}
+}
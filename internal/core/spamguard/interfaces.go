@@ -0,0 +1,44 @@
+package spamguard
+
+import (
+	"context"
+	"time"
+)
+
+// Repository records recent posts so the guard can spot repeated content and posting bursts
+type Repository interface {
+	// GetAccountCreatedAt returns when the account was created, as saved by SaveAccountCreatedAt;
+	// found is false until it has been
+	GetAccountCreatedAt(ctx context.Context, did string) (createdAt time.Time, found bool, err error)
+	// SaveAccountCreatedAt remembers when an account was created, from its identity history
+	SaveAccountCreatedAt(ctx context.Context, did string, createdAt time.Time) error
+	// GetFirstIndexedAt returns when the user was first indexed; found is false for unknown users
+	GetFirstIndexedAt(ctx context.Context, did string) (indexedAt time.Time, found bool, err error)
+
+	// RecordPost stores a post's fingerprint; recording the same post URI again is a no-op
+	RecordPost(ctx context.Context, uri, authorDID, communityDID, fingerprint string, at time.Time) error
+	// CountCommunitiesWithFingerprint counts distinct communities the fingerprint was posted to
+	// between since and until, inclusive
+	CountCommunitiesWithFingerprint(ctx context.Context, fingerprint string, since, until time.Time) (int, error)
+	// CountPostsByAuthor counts the author's recorded posts between since and until, inclusive
+	CountPostsByAuthor(ctx context.Context, authorDID string, since, until time.Time) (int, error)
+	// PruneBefore drops fingerprints recorded before the given time
+	PruneBefore(ctx context.Context, before time.Time) error
+}
+
+// QueueRepository lets admins review quarantined posts
+// Release and Confirm return ErrPostNotQuarantined unless the post is awaiting review.
+type QueueRepository interface {
+	ListQuarantined(ctx context.Context, limit, offset int) ([]*QuarantinedPost, error)
+	// Release makes a post visible as if it had never been quarantined
+	Release(ctx context.Context, uri string) error
+	// Confirm marks the post as spam; it stays hidden and leaves the queue
+	Confirm(ctx context.Context, uri string) error
+}
+
+// AccountHistory looks up when an account was created from its identity's history
+// Implemented by identity.PLCAuditLog; accounts without a history, such as did:web, return an
+// error matching coreerrors.ErrNotFound.
+type AccountHistory interface {
+	AccountCreatedAt(ctx context.Context, did string) (time.Time, error)
+}
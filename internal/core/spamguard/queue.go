@@ -0,0 +1,22 @@
+package spamguard
+
+import (
+	"time"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+// ErrPostNotQuarantined is returned when reviewing a post that isn't awaiting review
+var ErrPostNotQuarantined = coreerrors.Sentinel(coreerrors.ErrNotFound, "post is not quarantined")
+
+// QuarantinedPost is a post held back from feeds until an admin reviews it
+type QuarantinedPost struct {
+	IndexedAt    time.Time `json:"indexedAt"`
+	Title        *string   `json:"title,omitempty"`
+	Content      *string   `json:"content,omitempty"`
+	URI          string    `json:"uri"`
+	CID          string    `json:"cid"`
+	AuthorDID    string    `json:"authorDid"`
+	CommunityDID string    `json:"communityDid"`
+	Reasons      []string  `json:"reasons"`
+}
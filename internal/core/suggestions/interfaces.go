@@ -0,0 +1,71 @@
+package suggestions
+
+import (
+	"context"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/auth/oauth"
+
+	"Coves/internal/core/communities"
+)
+
+// Suggestion limits
+const (
+	DefaultLimit   = 20
+	MaxSuggestions = 50 // Also the page size cached per viewer
+
+	// MaxFollows caps how many of the viewer's follow records are read from their PDS
+	MaxFollows = 1000
+
+	// CacheTTL is how long a viewer's suggestions are served before their follows are read again
+	CacheTTL = time.Hour
+)
+
+// Where suggestions came from
+const (
+	SourceFollows  = "follows"  // Communities the viewer's follows subscribe to or are active in
+	SourceTrending = "trending" // Most active communities; used when the follows give nothing
+)
+
+// Suggestion is a community suggested to the viewer
+type Suggestion struct {
+	Community     *communities.Community
+	FollowedUsers int // Followed accounts subscribed to or recently active in the community; 0 when trending
+}
+
+// SuggestedCommunityView is a suggestion in the social.coves.discover.getSuggestedCommunities output
+type SuggestedCommunityView struct {
+	Community     *communities.CommunityView `json:"community"`
+	FollowedUsers int                        `json:"followedUsers"`
+}
+
+// GetSuggestedCommunitiesResponse is the social.coves.discover.getSuggestedCommunities output
+type GetSuggestedCommunitiesResponse struct {
+	Source      string                    `json:"source"`
+	Communities []*SuggestedCommunityView `json:"communities"`
+}
+
+// Repository intersects a follow graph with the users and communities indexed here
+// Only public communities that are listed (not federation blocked, flagged, suspended or
+// deleted) are suggested, and never ones the viewer subscribes to or has blocked.
+type Repository interface {
+	// FilterIndexedUsers returns the DIDs that belong to users indexed on this instance
+	FilterIndexedUsers(ctx context.Context, dids []string) ([]string, error)
+
+	// RankByFollowedUsers returns communities that any of userDIDs subscribe to or were active
+	// in since the given time, ranked by how many of them did
+	RankByFollowedUsers(ctx context.Context, viewerDID string, userDIDs []string, since time.Time, limit int) ([]*Suggestion, error)
+
+	// ListTrending returns communities ranked by weekly, then monthly, active users
+	ListTrending(ctx context.Context, viewerDID string, limit int) ([]*communities.Community, error)
+
+	// GetExcludedCommunityDIDs reports which of communityDIDs the viewer subscribes to or has blocked
+	GetExcludedCommunityDIDs(ctx context.Context, viewerDID string, communityDIDs []string) (map[string]bool, error)
+}
+
+// Service suggests communities to join from the viewer's Bluesky follows
+type Service interface {
+	// GetSuggestedCommunities returns up to limit suggestions for the session's account
+	// Falls back to trending communities when the follows can't be read or match nothing.
+	GetSuggestedCommunities(ctx context.Context, session *oauth.ClientSessionData, limit int) (*GetSuggestedCommunitiesResponse, error)
+}
@@ -0,0 +1,272 @@
+package suggestions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/auth/oauth"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"golang.org/x/sync/singleflight"
+
+	oauthclient "Coves/internal/atproto/oauth"
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/communities"
+)
+
+const (
+	// followCollection is the Bluesky collection holding the viewer's follow records
+	followCollection = "app.bsky.graph.follow"
+	followsPageSize  = 100
+
+	// maxCacheEntries bounds the cache; expired entries are swept once it fills up
+	maxCacheEntries = 10000
+)
+
+// PDSClientFactory creates PDS clients from session data.
+// Used to allow injection of different auth mechanisms (OAuth for production, password for tests).
+type PDSClientFactory func(ctx context.Context, session *oauth.ClientSessionData) (pds.Client, error)
+
+type suggestionService struct {
+	repo             Repository
+	oauthClient      *oauthclient.OAuthClient
+	pdsClientFactory PDSClientFactory // Optional, for testing. If nil, uses OAuth.
+	now              func() time.Time
+	entries          map[string]cacheEntry
+	loads            singleflight.Group
+	mu               sync.RWMutex
+}
+
+// cacheEntry is a viewer's ranked suggestions from one read of their follows
+type cacheEntry struct {
+	expiresAt   time.Time
+	source      string
+	suggestions []*Suggestion
+}
+
+// NewService creates a suggestion service that reads follows from the viewer's PDS over OAuth
+func NewService(repo Repository, oauthClient *oauthclient.OAuthClient) Service {
+	return &suggestionService{
+		repo:        repo,
+		oauthClient: oauthClient,
+		now:         time.Now,
+		entries:     make(map[string]cacheEntry),
+	}
+}
+
+// NewServiceWithPDSFactory creates a suggestion service with a custom PDS client factory.
+// This is primarily for testing with password-based authentication.
+func NewServiceWithPDSFactory(repo Repository, factory PDSClientFactory) Service {
+	return &suggestionService{
+		repo:             repo,
+		pdsClientFactory: factory,
+		now:              time.Now,
+		entries:          make(map[string]cacheEntry),
+	}
+}
+
+// getPDSClient creates a PDS client from an OAuth session.
+// If a custom factory was provided (for testing), uses that.
+func (s *suggestionService) getPDSClient(ctx context.Context, session *oauth.ClientSessionData) (pds.Client, error) {
+	if s.pdsClientFactory != nil {
+		return s.pdsClientFactory(ctx, session)
+	}
+
+	if s.oauthClient == nil || s.oauthClient.ClientApp == nil {
+		return nil, fmt.Errorf("OAuth client not configured")
+	}
+
+	client, err := pds.NewFromOAuthSession(ctx, s.oauthClient.ClientApp, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create PDS client: %w", err)
+	}
+
+	return client, nil
+}
+
+// GetSuggestedCommunities returns the viewer's suggestions, served from the per-viewer cache when fresh
+// Communities the viewer subscribed to or blocked since the suggestions were ranked are dropped
+// on every call. When the follows can't be read the trending communities are served instead
+// and nothing is cached, so the next call tries the PDS again.
+func (s *suggestionService) GetSuggestedCommunities(ctx context.Context, session *oauth.ClientSessionData, limit int) (*GetSuggestedCommunitiesResponse, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxSuggestions {
+		limit = MaxSuggestions
+	}
+	viewerDID := session.AccountDID.String()
+
+	entry, err := s.load(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.dropExcluded(ctx, viewerDID, entry.suggestions)
+	if err != nil {
+		return nil, err
+	}
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+
+	views := make([]*SuggestedCommunityView, len(suggestions))
+	for i, suggestion := range suggestions {
+		views[i] = &SuggestedCommunityView{
+			Community:     suggestion.Community.ToCommunityView(),
+			FollowedUsers: suggestion.FollowedUsers,
+		}
+	}
+	return &GetSuggestedCommunitiesResponse{Source: entry.source, Communities: views}, nil
+}
+
+// load returns the viewer's ranked suggestions, reading their follows on a miss or expiry
+// Concurrent misses for the same viewer share one read of the follows.
+func (s *suggestionService) load(ctx context.Context, session *oauth.ClientSessionData) (cacheEntry, error) {
+	viewerDID := session.AccountDID.String()
+
+	s.mu.RLock()
+	entry, ok := s.entries[viewerDID]
+	s.mu.RUnlock()
+	if ok && s.now().Before(entry.expiresAt) {
+		return entry, nil
+	}
+
+	result, err, _ := s.loads.Do(viewerDID, func() (interface{}, error) {
+		follows, err := s.fetchFollows(ctx, session)
+		if err != nil {
+			log.Printf("Warning: failed to read follows for %s, suggesting trending communities: %v", viewerDID, err)
+			return s.trending(ctx, viewerDID)
+		}
+
+		suggestions, err := s.rankFollows(ctx, viewerDID, follows)
+		if err != nil {
+			return nil, err
+		}
+		entry := cacheEntry{source: SourceFollows, suggestions: suggestions}
+		if len(suggestions) == 0 {
+			if entry, err = s.trending(ctx, viewerDID); err != nil {
+				return nil, err
+			}
+		}
+		s.put(viewerDID, entry)
+		return entry, nil
+	})
+	if err != nil {
+		return cacheEntry{}, err
+	}
+	return result.(cacheEntry), nil
+}
+
+// fetchFollows pages through the viewer's follow records and returns the followed DIDs
+// Stops after MaxFollows records; follows of the viewer themselves and malformed records are skipped.
+func (s *suggestionService) fetchFollows(ctx context.Context, session *oauth.ClientSessionData) ([]string, error) {
+	client, err := s.getPDSClient(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	viewerDID := session.AccountDID.String()
+	seen := make(map[string]bool)
+	var follows []string
+	cursor := ""
+	for read := 0; read < MaxFollows; {
+		limit := followsPageSize
+		if remaining := MaxFollows - read; remaining < limit {
+			limit = remaining
+		}
+		page, err := client.ListRecords(ctx, followCollection, limit, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("listRecords failed: %w", err)
+		}
+		for _, rec := range page.Records {
+			read++
+			subject, _ := rec.Value["subject"].(string)
+			if _, err := syntax.ParseDID(subject); err != nil || subject == viewerDID || seen[subject] {
+				continue
+			}
+			seen[subject] = true
+			follows = append(follows, subject)
+		}
+		if page.Cursor == "" || len(page.Records) == 0 {
+			break
+		}
+		cursor = page.Cursor
+	}
+	return follows, nil
+}
+
+// rankFollows ranks communities by how many of the followed accounts indexed here are
+// subscribed or were active there in the last month
+func (s *suggestionService) rankFollows(ctx context.Context, viewerDID string, follows []string) ([]*Suggestion, error) {
+	if len(follows) == 0 {
+		return nil, nil
+	}
+	indexed, err := s.repo.FilterIndexedUsers(ctx, follows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match followed accounts: %w", err)
+	}
+	if len(indexed) == 0 {
+		return nil, nil
+	}
+
+	since := s.now().AddDate(0, 0, -communities.MonthlyActiveDays)
+	suggestions, err := s.repo.RankByFollowedUsers(ctx, viewerDID, indexed, since, MaxSuggestions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank communities: %w", err)
+	}
+	return suggestions, nil
+}
+
+// trending returns the most active communities as suggestions
+func (s *suggestionService) trending(ctx context.Context, viewerDID string) (cacheEntry, error) {
+	trending, err := s.repo.ListTrending(ctx, viewerDID, MaxSuggestions)
+	if err != nil {
+		return cacheEntry{}, fmt.Errorf("failed to list trending communities: %w", err)
+	}
+	suggestions := make([]*Suggestion, len(trending))
+	for i, community := range trending {
+		suggestions[i] = &Suggestion{Community: community}
+	}
+	return cacheEntry{source: SourceTrending, suggestions: suggestions}, nil
+}
+
+// dropExcluded removes communities the viewer now subscribes to or has blocked
+func (s *suggestionService) dropExcluded(ctx context.Context, viewerDID string, suggestions []*Suggestion) ([]*Suggestion, error) {
+	if len(suggestions) == 0 {
+		return []*Suggestion{}, nil
+	}
+	dids := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		dids[i] = suggestion.Community.DID
+	}
+	excluded, err := s.repo.GetExcludedCommunityDIDs(ctx, viewerDID, dids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check subscriptions and blocks: %w", err)
+	}
+
+	kept := make([]*Suggestion, 0, len(suggestions))
+	for _, suggestion := range suggestions {
+		if !excluded[suggestion.Community.DID] {
+			kept = append(kept, suggestion)
+		}
+	}
+	return kept, nil
+}
+
+func (s *suggestionService) put(viewerDID string, entry cacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if len(s.entries) >= maxCacheEntries {
+		for key, cached := range s.entries {
+			if !now.Before(cached.expiresAt) {
+				delete(s.entries, key)
+			}
+		}
+	}
+	entry.expiresAt = now.Add(CacheTTL)
+	s.entries[viewerDID] = entry
+}
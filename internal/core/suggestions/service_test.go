@@ -0,0 +1,361 @@
+package suggestions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/auth/oauth"
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/blobs"
+	"Coves/internal/core/communities"
+)
+
+const viewerDID = "did:plc:viewer"
+
+// mockPDSClient serves the viewer's follow records in pages
+type mockPDSClient struct {
+	follows   []string
+	listError error
+	listCalls int
+}
+
+func (m *mockPDSClient) ListRecords(ctx context.Context, collection string, limit int, cursor string) (*pds.ListRecordsResponse, error) {
+	m.listCalls++
+	if m.listError != nil {
+		return nil, m.listError
+	}
+	if collection != followCollection {
+		return &pds.ListRecordsResponse{}, nil
+	}
+	start := 0
+	if cursor != "" {
+		start, _ = strconv.Atoi(cursor)
+	}
+	end := start + limit
+	if end > len(m.follows) {
+		end = len(m.follows)
+	}
+	resp := &pds.ListRecordsResponse{}
+	for i := start; i < end; i++ {
+		resp.Records = append(resp.Records, pds.RecordEntry{
+			URI:   fmt.Sprintf("at://%s/%s/%d", viewerDID, followCollection, i),
+			Value: map[string]any{"$type": followCollection, "subject": m.follows[i]},
+		})
+	}
+	if end < len(m.follows) {
+		resp.Cursor = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+func (m *mockPDSClient) CreateRecord(ctx context.Context, collection, rkey string, record any) (string, string, error) {
+	return "", "", errors.New("not implemented")
+}
+
+func (m *mockPDSClient) DeleteRecord(ctx context.Context, collection, rkey string) error {
+	return errors.New("not implemented")
+}
+
+func (m *mockPDSClient) GetRecord(ctx context.Context, collection, rkey string) (*pds.RecordResponse, error) {
+	return nil, pds.ErrNotFound
+}
+
+func (m *mockPDSClient) PutRecord(ctx context.Context, collection, rkey string, record any, swapRecord string) (string, string, error) {
+	return "", "", errors.New("not implemented")
+}
+
+func (m *mockPDSClient) ApplyWrites(ctx context.Context, repo string, writes []pds.WriteOp) ([]pds.WriteResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockPDSClient) UploadBlob(ctx context.Context, data []byte, mimeType string) (*blobs.BlobRef, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockPDSClient) DID() string     { return viewerDID }
+func (m *mockPDSClient) HostURL() string { return "https://pds.test.local" }
+
+// fakeRepo ranks seeded subscriptions and activity in memory the way the postgres repo does
+type fakeRepo struct {
+	users         map[string]bool
+	communities   map[string]*communities.Community
+	subscriptions map[string][]string    // user DID -> community DIDs
+	activity      map[string][]time.Time // "communityDID|userDID" -> active days
+	blocks        map[string][]string    // user DID -> community DIDs
+	rankedWith    []string               // userDIDs passed to the last RankByFollowedUsers call
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		users:         make(map[string]bool),
+		communities:   make(map[string]*communities.Community),
+		subscriptions: make(map[string][]string),
+		activity:      make(map[string][]time.Time),
+		blocks:        make(map[string][]string),
+	}
+}
+
+func (f *fakeRepo) addCommunity(did string, weeklyActives int) {
+	f.communities[did] = &communities.Community{DID: did, Name: did, Handle: "c-" + did + ".coves.test", Visibility: "public", WeeklyActiveUsers: weeklyActives}
+}
+
+func (f *fakeRepo) active(communityDID, userDID string, day time.Time) {
+	key := communityDID + "|" + userDID
+	f.activity[key] = append(f.activity[key], day)
+}
+
+func (f *fakeRepo) FilterIndexedUsers(ctx context.Context, dids []string) ([]string, error) {
+	indexed := []string{}
+	for _, did := range dids {
+		if f.users[did] {
+			indexed = append(indexed, did)
+		}
+	}
+	return indexed, nil
+}
+
+func (f *fakeRepo) excluded(viewer, communityDID string) bool {
+	for _, did := range append(append([]string{}, f.subscriptions[viewer]...), f.blocks[viewer]...) {
+		if did == communityDID {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *fakeRepo) RankByFollowedUsers(ctx context.Context, viewer string, userDIDs []string, since time.Time, limit int) ([]*Suggestion, error) {
+	f.rankedWith = userDIDs
+	followed := make(map[string]map[string]bool)
+	mark := func(communityDID, userDID string) {
+		if followed[communityDID] == nil {
+			followed[communityDID] = make(map[string]bool)
+		}
+		followed[communityDID][userDID] = true
+	}
+	for _, user := range userDIDs {
+		for _, communityDID := range f.subscriptions[user] {
+			mark(communityDID, user)
+		}
+		for communityDID := range f.communities {
+			for _, day := range f.activity[communityDID+"|"+user] {
+				if !day.Before(since) {
+					mark(communityDID, user)
+				}
+			}
+		}
+	}
+
+	result := []*Suggestion{}
+	for communityDID, users := range followed {
+		if f.excluded(viewer, communityDID) {
+			continue
+		}
+		result = append(result, &Suggestion{Community: f.communities[communityDID], FollowedUsers: len(users)})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].FollowedUsers != result[j].FollowedUsers {
+			return result[i].FollowedUsers > result[j].FollowedUsers
+		}
+		return result[i].Community.DID < result[j].Community.DID
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (f *fakeRepo) ListTrending(ctx context.Context, viewer string, limit int) ([]*communities.Community, error) {
+	result := []*communities.Community{}
+	for did, community := range f.communities {
+		if !f.excluded(viewer, did) {
+			result = append(result, community)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].WeeklyActiveUsers > result[j].WeeklyActiveUsers })
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (f *fakeRepo) GetExcludedCommunityDIDs(ctx context.Context, viewer string, communityDIDs []string) (map[string]bool, error) {
+	excluded := make(map[string]bool)
+	for _, did := range communityDIDs {
+		if f.excluded(viewer, did) {
+			excluded[did] = true
+		}
+	}
+	return excluded, nil
+}
+
+func testSession() *oauth.ClientSessionData {
+	return &oauth.ClientSessionData{AccountDID: syntax.DID(viewerDID), SessionID: "test-session", HostURL: "https://pds.test.local"}
+}
+
+func newTestService(repo Repository, client *mockPDSClient) *suggestionService {
+	return NewServiceWithPDSFactory(repo, func(ctx context.Context, session *oauth.ClientSessionData) (pds.Client, error) {
+		return client, nil
+	}).(*suggestionService)
+}
+
+func communityDIDs(resp *GetSuggestedCommunitiesResponse) []string {
+	dids := make([]string, len(resp.Communities))
+	for i, view := range resp.Communities {
+		dids[i] = view.Community.DID
+	}
+	return dids
+}
+
+// seededRepo indexes four users the viewer may follow:
+// alice subscribes to golang and is active in rust, bob is active in golang and was active in
+// cooking two months ago, carol subscribes to cooking and to two communities the viewer has
+// blocked or already subscribes to, and dave has done nothing here.
+func seededRepo(now time.Time) *fakeRepo {
+	repo := newFakeRepo()
+	for _, did := range []string{"did:plc:golang", "did:plc:rust", "did:plc:cooking", "did:plc:blocked", "did:plc:mine"} {
+		repo.addCommunity(did, 1)
+	}
+	repo.addCommunity("did:plc:quiet", 0)
+	for _, did := range []string{"did:plc:alice", "did:plc:bob", "did:plc:carol", "did:plc:dave"} {
+		repo.users[did] = true
+	}
+	repo.subscriptions["did:plc:alice"] = []string{"did:plc:golang"}
+	repo.active("did:plc:rust", "did:plc:alice", now.AddDate(0, 0, -2))
+	repo.active("did:plc:golang", "did:plc:bob", now.AddDate(0, 0, -1))
+	repo.active("did:plc:cooking", "did:plc:bob", now.AddDate(0, 0, -60))
+	repo.subscriptions["did:plc:carol"] = []string{"did:plc:cooking", "did:plc:blocked", "did:plc:mine"}
+	repo.subscriptions[viewerDID] = []string{"did:plc:mine"}
+	repo.blocks[viewerDID] = []string{"did:plc:blocked"}
+	return repo
+}
+
+func TestGetSuggestedCommunities_RanksByFollowedUsers(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := seededRepo(now)
+	client := &mockPDSClient{follows: []string{
+		"did:plc:alice", "did:plc:bob", "did:plc:carol", "did:plc:dave",
+		"did:plc:alice",       // Duplicate follow records count once
+		"did:plc:notindexed",  // Bluesky-only accounts are ignored
+		"not-a-did",           // Malformed subjects are skipped
+		viewerDID,             // Following yourself isn't a signal
+		"did:plc:nobodyelse1", // Unknown here
+	}}
+	service := newTestService(repo, client)
+	service.now = func() time.Time { return now }
+
+	resp, err := service.GetSuggestedCommunities(context.Background(), testSession(), 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, SourceFollows, resp.Source)
+	// golang: alice subscribes and bob is active; cooking: carol subscribes (bob's activity is too
+	// old); rust: alice is active. Blocked and already-subscribed communities are left out.
+	assert.Equal(t, []string{"did:plc:golang", "did:plc:cooking", "did:plc:rust"}, communityDIDs(resp))
+	assert.Equal(t, 2, resp.Communities[0].FollowedUsers)
+	assert.Equal(t, 1, resp.Communities[1].FollowedUsers)
+	assert.ElementsMatch(t, []string{"did:plc:alice", "did:plc:bob", "did:plc:carol", "did:plc:dave"}, repo.rankedWith)
+}
+
+func TestGetSuggestedCommunities_PagesFollowsUpToCap(t *testing.T) {
+	follows := make([]string, MaxFollows+250)
+	for i := range follows {
+		follows[i] = fmt.Sprintf("did:plc:follow%04d", i)
+	}
+	repo := newFakeRepo()
+	repo.addCommunity("did:plc:golang", 1)
+	// Only a follow past the cap subscribes anywhere
+	repo.users[follows[MaxFollows+10]] = true
+	repo.subscriptions[follows[MaxFollows+10]] = []string{"did:plc:golang"}
+	repo.users[follows[MaxFollows-1]] = true
+
+	client := &mockPDSClient{follows: follows}
+	service := newTestService(repo, client)
+
+	resp, err := service.GetSuggestedCommunities(context.Background(), testSession(), 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, MaxFollows/followsPageSize, client.listCalls, "stops paging at the cap")
+	assert.Equal(t, []string{follows[MaxFollows-1]}, repo.rankedWith)
+	// Nothing ranked from the follows read, so trending communities are suggested instead
+	assert.Equal(t, SourceTrending, resp.Source)
+	assert.Equal(t, []string{"did:plc:golang"}, communityDIDs(resp))
+}
+
+func TestGetSuggestedCommunities_FallsBackToTrendingWhenPDSFails(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := seededRepo(now)
+	repo.communities["did:plc:rust"].WeeklyActiveUsers = 50
+	client := &mockPDSClient{follows: []string{"did:plc:alice"}, listError: errors.New("PDS unavailable")}
+	service := newTestService(repo, client)
+	service.now = func() time.Time { return now }
+
+	resp, err := service.GetSuggestedCommunities(context.Background(), testSession(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, SourceTrending, resp.Source)
+	require.Len(t, resp.Communities, 2)
+	assert.Equal(t, "did:plc:rust", resp.Communities[0].Community.DID)
+	assert.NotContains(t, communityDIDs(resp), "did:plc:mine")
+	assert.NotContains(t, communityDIDs(resp), "did:plc:blocked")
+
+	// The fallback isn't cached: once the PDS recovers the follows are used
+	client.listError = nil
+	resp, err = service.GetSuggestedCommunities(context.Background(), testSession(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, SourceFollows, resp.Source)
+	assert.Equal(t, []string{"did:plc:golang", "did:plc:rust"}, communityDIDs(resp))
+}
+
+func TestGetSuggestedCommunities_FallsBackToTrendingWhenFactoryFails(t *testing.T) {
+	repo := seededRepo(time.Now())
+	service := NewServiceWithPDSFactory(repo, func(ctx context.Context, session *oauth.ClientSessionData) (pds.Client, error) {
+		return nil, errors.New("session expired")
+	})
+
+	resp, err := service.GetSuggestedCommunities(context.Background(), testSession(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, SourceTrending, resp.Source)
+	assert.Len(t, resp.Communities, 4)
+}
+
+func TestGetSuggestedCommunities_CachesPerViewerForAnHour(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	repo := seededRepo(now)
+	client := &mockPDSClient{follows: []string{"did:plc:alice", "did:plc:bob"}}
+	service := newTestService(repo, client)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	resp, err := service.GetSuggestedCommunities(ctx, testSession(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"did:plc:golang", "did:plc:rust"}, communityDIDs(resp))
+	calls := client.listCalls
+
+	// New follows aren't read until the entry expires, but subscribing drops a suggestion at once
+	client.follows = append(client.follows, "did:plc:carol")
+	repo.subscriptions[viewerDID] = append(repo.subscriptions[viewerDID], "did:plc:golang")
+	now = now.Add(CacheTTL - time.Minute)
+	resp, err = service.GetSuggestedCommunities(ctx, testSession(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, calls, client.listCalls)
+	assert.Equal(t, []string{"did:plc:rust"}, communityDIDs(resp))
+
+	// Smaller limits are served from the same entry
+	resp, err = service.GetSuggestedCommunities(ctx, testSession(), 1)
+	require.NoError(t, err)
+	assert.Len(t, resp.Communities, 1)
+	assert.Equal(t, calls, client.listCalls)
+
+	now = now.Add(2 * time.Minute)
+	resp, err = service.GetSuggestedCommunities(ctx, testSession(), 10)
+	require.NoError(t, err)
+	assert.Greater(t, client.listCalls, calls)
+	assert.Equal(t, []string{"did:plc:cooking", "did:plc:rust"}, communityDIDs(resp))
+}
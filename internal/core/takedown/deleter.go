@@ -0,0 +1,77 @@
+package takedown
+
+import (
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/communities"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+// CommunityCredentials is the part of communities.Service the deleter uses to act as a
+// community account
+type CommunityCredentials interface {
+	GetByDID(ctx context.Context, did string) (*communities.Community, error)
+	EnsureFreshToken(ctx context.Context, community *communities.Community) (*communities.Community, error)
+}
+
+// PDSClientFactory creates a PDS client acting as the community account
+type PDSClientFactory func(host, did, accessToken string) (pds.Client, error)
+
+type communityRecordDeleter struct {
+	communities CommunityCredentials
+	newClient   PDSClientFactory
+}
+
+// NewCommunityRecordDeleter deletes records from the repos of communities this instance
+// provisioned, using their stored PDS credentials
+func NewCommunityRecordDeleter(communityService CommunityCredentials) UpstreamDeleter {
+	return NewCommunityRecordDeleterWithFactory(communityService, pds.NewFromAccessToken)
+}
+
+// NewCommunityRecordDeleterWithFactory is NewCommunityRecordDeleter with a custom PDS client factory
+func NewCommunityRecordDeleterWithFactory(communityService CommunityCredentials, factory PDSClientFactory) UpstreamDeleter {
+	return &communityRecordDeleter{communities: communityService, newClient: factory}
+}
+
+// DeleteRecord deletes the record when its repo is a community account we hold credentials for
+// User repos, and communities learned from the firehose or peer directories, aren't ours to
+// write to. A record that's already gone from the PDS counts as deleted.
+func (d *communityRecordDeleter) DeleteRecord(ctx context.Context, subjectURI string) (bool, error) {
+	aturi, err := syntax.ParseATURI(subjectURI)
+	if err != nil {
+		return false, fmt.Errorf("invalid subject URI %q: %w", subjectURI, err)
+	}
+	did, err := aturi.Authority().AsDID()
+	if err != nil {
+		return false, nil
+	}
+
+	community, err := d.communities.GetByDID(ctx, did.String())
+	if err != nil {
+		if communities.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to look up repo owner: %w", err)
+	}
+	if community.PDSAccessToken == "" {
+		return false, nil
+	}
+
+	community, err = d.communities.EnsureFreshToken(ctx, community)
+	if err != nil {
+		return false, fmt.Errorf("failed to refresh community credentials: %w", err)
+	}
+	client, err := d.newClient(community.PDSURL, community.DID, community.PDSAccessToken)
+	if err != nil {
+		return false, fmt.Errorf("failed to create PDS client: %w", err)
+	}
+
+	err = client.DeleteRecord(ctx, aturi.Collection().String(), aturi.RecordKey().String())
+	if err != nil && !errors.Is(err, pds.ErrNotFound) {
+		return false, fmt.Errorf("failed to delete record from PDS: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,31 @@
+package takedown
+
+import "context"
+
+// Checker looks up a record's active takedown
+type Checker interface {
+	// GetActive returns the record's active takedown, or ErrTakedownNotFound
+	GetActive(ctx context.Context, subjectURI string) (*Takedown, error)
+}
+
+// Repository records takedowns and hides their subjects
+type Repository interface {
+	Checker
+
+	// Takedown hides the record and inserts t into the audit trail, filling in its ID and
+	// CreatedAt. If the record is already taken down it loads the active takedown into t
+	// instead and returns false. Returns ErrSubjectNotFound when no post or comment has the URI.
+	Takedown(ctx context.Context, t *Takedown) (bool, error)
+	// MarkUpstreamDeleted records that the takedown's subject was deleted from its PDS
+	MarkUpstreamDeleted(ctx context.Context, id int64) error
+	// Reverse completes the record's active takedown and shows the record again
+	// Returns ErrTakedownNotFound when there's no active takedown.
+	Reverse(ctx context.Context, subjectURI, actorDID string) (*Takedown, error)
+}
+
+// UpstreamDeleter deletes taken-down records from repositories this instance controls
+type UpstreamDeleter interface {
+	// DeleteRecord deletes the record from its PDS when its repo is one we hold credentials
+	// for, reporting whether it did; records in other repos are left alone
+	DeleteRecord(ctx context.Context, subjectURI string) (bool, error)
+}
@@ -0,0 +1,67 @@
+package takedown
+
+import (
+	"time"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+var (
+	// ErrRecordTakenDown is matched by TakenDownError, returned when fetching taken-down content
+	ErrRecordTakenDown = coreerrors.Sentinel(coreerrors.ErrNotFound, "record taken down")
+
+	// ErrSubjectNotFound is returned when taking down a URI no indexed post or comment has
+	ErrSubjectNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "post or comment not found")
+
+	// ErrTakedownNotFound is returned when reversing a record with no active takedown
+	ErrTakedownNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "no active takedown for record")
+)
+
+// Reason is the public category of a takedown
+// It's the only detail clients see; the admin's note stays in the audit trail.
+type Reason string
+
+const (
+	ReasonLegal     Reason = "legal"
+	ReasonCopyright Reason = "copyright"
+	ReasonAbuse     Reason = "abuse"
+	ReasonSpam      Reason = "spam"
+	ReasonOther     Reason = "other"
+)
+
+// IsValid reports whether r is a known takedown category
+func (r Reason) IsValid() bool {
+	switch r {
+	case ReasonLegal, ReasonCopyright, ReasonAbuse, ReasonSpam, ReasonOther:
+		return true
+	}
+	return false
+}
+
+// Takedown is one admin takedown of a post or comment, and its reversal if any
+type Takedown struct {
+	CreatedAt       time.Time  `json:"createdAt"`
+	ReversedAt      *time.Time `json:"reversedAt,omitempty"`
+	SubjectURI      string     `json:"subject"`
+	Reason          Reason     `json:"reason"`
+	Note            string     `json:"note,omitempty"`
+	CreatedBy       string     `json:"createdBy"`
+	ReversedBy      string     `json:"reversedBy,omitempty"`
+	ID              int64      `json:"id"`
+	UpstreamDeleted bool       `json:"upstreamDeleted"` // Also deleted from a community repo this instance hosts
+}
+
+// TakenDownError is returned when fetching a record with an active takedown
+// It carries the reason category only, which is all clients are told.
+type TakenDownError struct {
+	Reason Reason
+}
+
+func (e *TakenDownError) Error() string {
+	return "record taken down: " + string(e.Reason)
+}
+
+// Is makes TakenDownError match ErrRecordTakenDown and its kind
+func (e *TakenDownError) Is(target error) bool {
+	return target == ErrRecordTakenDown || target == coreerrors.ErrNotFound
+}
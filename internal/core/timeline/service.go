@@ -1,19 +1,37 @@
 package timeline
 
 import (
+	"Coves/internal/core/consumergaps"
 	"context"
 	"fmt"
+	"log"
+	"time"
 )
 
 type timelineService struct {
 	repo Repository
+	gaps consumergaps.Lookup // Optional - responses report no gaps when nil
+}
+
+// ServiceOption configures optional timeline service dependencies
+type ServiceOption func(*timelineService)
+
+// WithGapLookup reports consumer gaps overlapping each page
+func WithGapLookup(gaps consumergaps.Lookup) ServiceOption {
+	return func(s *timelineService) {
+		s.gaps = gaps
+	}
 }
 
 // NewTimelineService creates a new timeline service
-func NewTimelineService(repo Repository) Service {
-	return &timelineService{
+func NewTimelineService(repo Repository, opts ...ServiceOption) Service {
+	s := &timelineService{
 		repo: repo,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // GetTimeline retrieves posts from all communities the user subscribes to
@@ -38,9 +56,30 @@ func (s *timelineService) GetTimeline(ctx context.Context, req GetTimelineReques
 	return &TimelineResponse{
 		Feed:   feedPosts,
 		Cursor: cursor,
+		Gaps:   s.pageGaps(ctx, feedPosts, req.Cursor == nil),
 	}, nil
 }
 
+// pageGaps returns the consumer gaps the page overlaps
+// Best effort: a failed lookup is logged and the page is served without gaps.
+func (s *timelineService) pageGaps(ctx context.Context, feedPosts []*FeedViewPost, firstPage bool) []*consumergaps.Gap {
+	if s.gaps == nil {
+		return nil
+	}
+	indexedAt := make([]time.Time, 0, len(feedPosts))
+	for _, feedPost := range feedPosts {
+		if feedPost.Post != nil {
+			indexedAt = append(indexedAt, feedPost.Post.IndexedAt)
+		}
+	}
+	gaps, err := consumergaps.ForPage(ctx, s.gaps, indexedAt, firstPage)
+	if err != nil {
+		log.Printf("Warning: failed to look up consumer gaps for timeline: %v", err)
+		return nil
+	}
+	return gaps
+}
+
 // validateRequest validates the timeline request parameters
 func (s *timelineService) validateRequest(req *GetTimelineRequest) error {
 	// Validate and set defaults for sort
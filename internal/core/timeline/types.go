@@ -1,6 +1,8 @@
 package timeline
 
 import (
+	"Coves/internal/core/consumergaps"
+	coreerrors "Coves/internal/core/errors"
 	"Coves/internal/core/posts"
 	"context"
 	"errors"
@@ -25,6 +27,8 @@ type GetTimelineRequest struct {
 	Sort      string  `json:"sort"`
 	Timeframe string  `json:"timeframe"`
 	Limit     int     `json:"limit"`
+	// IncludeNSFW includes posts with an effective adult label (posts.AdultLabels)
+	IncludeNSFW bool `json:"includeNsfw"`
 }
 
 // TimelineResponse represents paginated timeline output
@@ -32,6 +36,8 @@ type GetTimelineRequest struct {
 type TimelineResponse struct {
 	Cursor *string         `json:"cursor,omitempty"`
 	Feed   []*FeedViewPost `json:"feed"`
+	// Gaps are windows of posts the AppView may have missed that the page overlaps
+	Gaps []*consumergaps.Gap `json:"gaps,omitempty"`
 }
 
 // FeedViewPost wraps a post with additional feed context
@@ -47,6 +53,18 @@ func (f *FeedViewPost) GetPost() *posts.PostView {
 	return f.Post
 }
 
+// GetContext returns the feed reason and reply context for versioned views
+// Returns untyped nils when absent so they are omitted from JSON
+func (f *FeedViewPost) GetContext() (reason, reply interface{}) {
+	if f.Reason != nil {
+		reason = f.Reason
+	}
+	if f.Reply != nil {
+		reply = f.Reply
+	}
+	return reason, reply
+}
+
 // FeedReason is a union type for feed context
 // Future: Can be reasonRepost or reasonCommunity
 type FeedReason struct {
@@ -81,8 +99,8 @@ type PostRef struct {
 
 // Errors
 var (
-	ErrInvalidCursor = errors.New("invalid cursor")
-	ErrUnauthorized  = errors.New("unauthorized")
+	ErrInvalidCursor = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid cursor")
+	ErrUnauthorized  = coreerrors.Sentinel(coreerrors.ErrUnauthorized, "unauthorized")
 )
 
 // ValidationError represents a validation error with field context
@@ -105,6 +123,11 @@ func NewValidationError(field, message string) error {
 
 // IsValidationError checks if an error is a validation error
 func IsValidationError(err error) bool {
-	_, ok := err.(*ValidationError)
-	return ok
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+// IsUnauthorized checks if an error is an authentication error
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
 }
@@ -0,0 +1,121 @@
+package translation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	backendRequestTimeout = 15 * time.Second
+
+	// maxBackendResponseBytes bounds a translation backend response
+	maxBackendResponseBytes = 1 << 20
+)
+
+// HTTPBackend calls a LibreTranslate-compatible /translate endpoint
+// The request is {"q": [...], "source": "en" or "auto", "target": "de", "format": "text",
+// "api_key": "..."}; the response is {"translatedText": [...], "detectedLanguage": ...}.
+type HTTPBackend struct {
+	httpClient *http.Client
+	endpoint   string
+	apiKey     string
+}
+
+// NewHTTPBackend creates a backend posting to endpoint (e.g. https://translate.example/translate)
+// apiKey may be empty for backends that don't require one.
+func NewHTTPBackend(endpoint, apiKey string) *HTTPBackend {
+	return &HTTPBackend{
+		httpClient: &http.Client{Timeout: backendRequestTimeout},
+		endpoint:   endpoint,
+		apiKey:     apiKey,
+	}
+}
+
+type backendRequestBody struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	APIKey string   `json:"api_key,omitempty"`
+}
+
+type backendResponseBody struct {
+	DetectedLanguage json.RawMessage `json:"detectedLanguage"`
+	Error            string          `json:"error"`
+	TranslatedText   []string        `json:"translatedText"`
+}
+
+type detectedLanguage struct {
+	Language string `json:"language"`
+}
+
+// Translate sends the texts to the backend in one request
+func (b *HTTPBackend) Translate(ctx context.Context, req BackendRequest) (*BackendResponse, error) {
+	source := req.SourceLang
+	if source == "" {
+		source = "auto"
+	}
+	body, err := json.Marshal(backendRequestBody{
+		Q:      req.Texts,
+		Source: source,
+		Target: req.TargetLang,
+		Format: "text",
+		APIKey: b.apiKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode translation request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, b.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create translation request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := b.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("translation request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBackendResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read translation response: %w", err)
+	}
+	var decoded backendResponseBody
+	decodeErr := json.Unmarshal(data, &decoded)
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && decoded.Error != "" {
+			return nil, fmt.Errorf("translation backend returned %d: %s", resp.StatusCode, decoded.Error)
+		}
+		return nil, fmt.Errorf("translation backend returned %d", resp.StatusCode)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("failed to decode translation response: %w", decodeErr)
+	}
+
+	return &BackendResponse{
+		Texts:      decoded.TranslatedText,
+		SourceLang: decoded.detectedSource(),
+	}, nil
+}
+
+// detectedSource returns the detected language of the first text
+// LibreTranslate reports one object for a single text and an array for a batch.
+func (r backendResponseBody) detectedSource() string {
+	var batch []detectedLanguage
+	if json.Unmarshal(r.DetectedLanguage, &batch) == nil && len(batch) > 0 {
+		return batch[0].Language
+	}
+	var single detectedLanguage
+	if json.Unmarshal(r.DetectedLanguage, &single) == nil {
+		return single.Language
+	}
+	return ""
+}
@@ -0,0 +1,214 @@
+package translation
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Detection thresholds
+const (
+	// MinConfidence is the confidence a detection needs before it is stored for a post or comment
+	MinConfidence = 0.6
+
+	minLatinLetters = 12 // Shorter Latin-script text is too little to tell languages apart
+	minCJKLetters   = 4  // Han, kana and Hangul carry a word or more per character
+
+	// fullConfidenceTrigrams is how many trigrams a text needs before its length stops
+	// discounting the confidence
+	fullConfidenceTrigrams = 40
+
+	// fullCoverage is the share of a text's trigrams found in a language's sample above which
+	// coverage stops discounting the confidence; the samples are short, so even text in that
+	// language misses many
+	fullCoverage = 0.6
+)
+
+// Detection is the language detected for a text
+type Detection struct {
+	Lang       string  // ISO 639-1 code; "" when the language couldn't be determined
+	Confidence float64 // 0 to 1
+}
+
+// Reliable reports whether the detection is confident enough to store
+func (d Detection) Reliable() bool {
+	return d.Lang != "" && d.Confidence >= MinConfidence
+}
+
+// DetectLanguage guesses the language of texts (e.g. a post's title and content) taken together
+// Scripts used by a single language here (Greek, Hebrew, Hangul, kana, ...) decide on their
+// own; Latin-script text is scored against a trigram model of the languages in latinSamples.
+// Links and @mentions are ignored. Text too short to judge returns an empty Detection.
+func DetectLanguage(texts ...string) Detection {
+	words := detectionWords(strings.Join(texts, "\n"))
+
+	scripts := make(map[*unicode.RangeTable]int)
+	letters := 0
+	ukrainian := false
+	for _, word := range words {
+		for _, r := range word {
+			for _, script := range detectionScripts {
+				if unicode.Is(script, r) {
+					scripts[script]++
+					break
+				}
+			}
+			if strings.ContainsRune("іїєґ", r) {
+				ukrainian = true
+			}
+			letters++
+		}
+	}
+	if letters == 0 {
+		return Detection{}
+	}
+
+	var dominant *unicode.RangeTable
+	for _, script := range detectionScripts {
+		if dominant == nil || scripts[script] > scripts[dominant] {
+			dominant = script
+		}
+	}
+	cjk := scripts[unicode.Han] + scripts[unicode.Hiragana] + scripts[unicode.Katakana] + scripts[unicode.Hangul]
+	share := func(n int) float64 { return float64(n) / float64(letters) }
+
+	switch {
+	case dominant == unicode.Latin:
+		if scripts[unicode.Latin] < minLatinLetters {
+			return Detection{}
+		}
+		return detectLatin(words, share(scripts[unicode.Latin]))
+	case cjk >= minCJKLetters && share(cjk) > 0.5:
+		// Japanese mixes kana into Han text; Chinese never uses it
+		kana := scripts[unicode.Hiragana] + scripts[unicode.Katakana]
+		switch {
+		case scripts[unicode.Hangul] > kana+scripts[unicode.Han]:
+			return Detection{Lang: "ko", Confidence: share(scripts[unicode.Hangul])}
+		case kana > 0:
+			return Detection{Lang: "ja", Confidence: share(kana + scripts[unicode.Han])}
+		default:
+			return Detection{Lang: "zh", Confidence: share(scripts[unicode.Han])}
+		}
+	case dominant == unicode.Cyrillic && scripts[dominant] >= minLatinLetters:
+		// Cyrillic text without the letters only Ukrainian uses is taken as Russian
+		if ukrainian {
+			return Detection{Lang: "uk", Confidence: share(scripts[dominant])}
+		}
+		return Detection{Lang: "ru", Confidence: share(scripts[dominant])}
+	case scriptLanguages[dominant] != "" && scripts[dominant] >= minLatinLetters:
+		return Detection{Lang: scriptLanguages[dominant], Confidence: share(scripts[dominant])}
+	}
+	return Detection{}
+}
+
+// detectionScripts are the scripts DetectLanguage counts letters in
+var detectionScripts = []*unicode.RangeTable{
+	unicode.Latin, unicode.Cyrillic, unicode.Greek, unicode.Arabic, unicode.Hebrew,
+	unicode.Devanagari, unicode.Thai, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul,
+}
+
+// scriptLanguages are the scripts that name their language outright
+var scriptLanguages = map[*unicode.RangeTable]string{
+	unicode.Greek:      "el",
+	unicode.Arabic:     "ar",
+	unicode.Hebrew:     "he",
+	unicode.Devanagari: "hi",
+	unicode.Thai:       "th",
+}
+
+// detectionWords lowercases text and splits it into runs of letters, dropping links and mentions
+func detectionWords(text string) []string {
+	var words []string
+	for _, field := range strings.Fields(text) {
+		if strings.Contains(field, "://") || strings.HasPrefix(field, "www.") || strings.HasPrefix(field, "@") {
+			continue
+		}
+		words = append(words, strings.FieldsFunc(strings.ToLower(field), func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})...)
+	}
+	return words
+}
+
+// wordTrigrams returns the trigrams of a word padded with a space on each side
+func wordTrigrams(word string) []string {
+	runes := []rune(" " + word + " ")
+	trigrams := make([]string, 0, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		trigrams = append(trigrams, string(runes[i:i+3]))
+	}
+	return trigrams
+}
+
+// trigramModel holds trigram counts per language
+type trigramModel struct {
+	counts     map[string]map[string]int
+	totals     map[string]int
+	vocabulary int
+}
+
+var latinModel = newTrigramModel(latinSamples)
+
+func newTrigramModel(samples map[string]string) *trigramModel {
+	model := &trigramModel{
+		counts: make(map[string]map[string]int),
+		totals: make(map[string]int),
+	}
+	vocabulary := make(map[string]bool)
+	for lang, sample := range samples {
+		counts := make(map[string]int)
+		for _, word := range detectionWords(sample) {
+			for _, trigram := range wordTrigrams(word) {
+				counts[trigram]++
+				model.totals[lang]++
+				vocabulary[trigram] = true
+			}
+		}
+		model.counts[lang] = counts
+	}
+	model.vocabulary = len(vocabulary)
+	return model
+}
+
+// detectLatin scores Latin-script words against the trigram model (naive Bayes, add-one smoothing)
+// Confidence is the best language's posterior probability, scaled down by how few of the
+// text's trigrams that language's sample contains (few for languages the model doesn't know),
+// for short text, and by the share of its letters that aren't Latin.
+func detectLatin(words []string, latinShare float64) Detection {
+	var trigrams []string
+	for _, word := range words {
+		trigrams = append(trigrams, wordTrigrams(word)...)
+	}
+
+	scores := make(map[string]float64, len(latinModel.counts))
+	best := ""
+	for lang, counts := range latinModel.counts {
+		denominator := math.Log(float64(latinModel.totals[lang] + latinModel.vocabulary))
+		score := 0.0
+		for _, trigram := range trigrams {
+			score += math.Log(float64(counts[trigram]+1)) - denominator
+		}
+		scores[lang] = score
+		if best == "" || score > scores[best] || (score == scores[best] && lang < best) {
+			best = lang
+		}
+	}
+
+	// Posterior of the best language, computed relative to it so the exponents can't underflow
+	sum := 0.0
+	for _, score := range scores {
+		sum += math.Exp(score - scores[best])
+	}
+	posterior := 1 / sum
+
+	seen := 0
+	for _, trigram := range trigrams {
+		if latinModel.counts[best][trigram] > 0 {
+			seen++
+		}
+	}
+	coverage := math.Min(1, float64(seen)/float64(len(trigrams))/fullCoverage)
+	length := math.Min(1, float64(len(trigrams))/fullConfidenceTrigrams)
+
+	return Detection{Lang: best, Confidence: posterior * coverage * length * latinShare}
+}
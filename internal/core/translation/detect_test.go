@@ -0,0 +1,67 @@
+package translation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		lang string
+	}{
+		{"I just finished reading this book and honestly the ending was a disappointment", "en"},
+		{"Acabo de terminar este libro y la verdad es que el final me decepcionó bastante", "es"},
+		{"Je viens de finir ce livre et franchement la fin m'a beaucoup déçu", "fr"},
+		{"Ich habe dieses Buch gerade fertig gelesen und ehrlich gesagt war das Ende enttäuschend", "de"},
+		{"Ho appena finito di leggere questo libro e onestamente il finale è stato una delusione", "it"},
+		{"Acabei de ler este livro e sinceramente o final foi uma desilusão", "pt"},
+		{"Ik heb dit boek net uitgelezen en eerlijk gezegd viel het einde tegen", "nl"},
+		{"Właśnie skończyłem czytać tę książkę i szczerze mówiąc zakończenie było rozczarowujące", "pl"},
+		{"Я только что дочитал эту книгу, и честно говоря, концовка разочаровала", "ru"},
+		{"Я щойно дочитав цю книжку, і чесно кажучи, фінал розчарував", "uk"},
+		{"Μόλις τελείωσα αυτό το βιβλίο και ειλικρινά το τέλος ήταν απογοητευτικό", "el"},
+		{"この本を読み終えたけど、正直に言うと結末にはがっかりした", "ja"},
+		{"我刚读完这本书，说实话结局让人失望", "zh"},
+		{"방금 이 책을 다 읽었는데 솔직히 결말이 실망스러웠어요", "ko"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			detection := DetectLanguage(tt.text)
+			assert.Equal(t, tt.lang, detection.Lang)
+			assert.True(t, detection.Reliable(), "confidence %.2f", detection.Confidence)
+		})
+	}
+}
+
+func TestDetectLanguage_TitleAndContentTogether(t *testing.T) {
+	detection := DetectLanguage("Nuevo horario", "La biblioteca cierra ahora a las seis de la tarde, mucho antes que antes")
+	assert.Equal(t, "es", detection.Lang)
+	assert.True(t, detection.Reliable())
+}
+
+func TestDetectLanguage_Undetermined(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"lol ok",
+		"Great news!",
+		"12345 !!! :)",
+		"https://example.com/some/long/path @alice.bsky.social",
+	} {
+		assert.Equal(t, Detection{}, DetectLanguage(text), text)
+	}
+}
+
+func TestDetectLanguage_UnknownLatinLanguageIsUnreliable(t *testing.T) {
+	// Swedish isn't modeled; whatever it is closest to shouldn't be stored
+	detection := DetectLanguage("Jag har precis läst klart den här boken och ärligt talat var slutet en besvikelse")
+	assert.False(t, detection.Reliable(), "%s %.2f", detection.Lang, detection.Confidence)
+}
+
+func TestDetectLanguage_ShortTextIsLessConfident(t *testing.T) {
+	short := DetectLanguage("Thanks for the help")
+	long := DetectLanguage("Thanks for the help, I would never have found the library without your directions")
+	assert.Equal(t, "en", long.Lang)
+	assert.Less(t, short.Confidence, long.Confidence)
+}
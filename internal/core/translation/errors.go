@@ -0,0 +1,24 @@
+package translation
+
+import (
+	"errors"
+
+	coreerrors "Coves/internal/core/errors"
+)
+
+var (
+	// ErrNotConfigured indicates no translation backend is configured on this instance
+	ErrNotConfigured = errors.New("translation is not configured")
+
+	// ErrBackendFailed indicates the translation backend failed or returned an unusable response
+	ErrBackendFailed = errors.New("translation backend failed")
+
+	// ErrInvalidSubject indicates the subject isn't a post or comment AT-URI
+	ErrInvalidSubject = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "subject must be a post or comment AT-URI")
+
+	// ErrInvalidLanguage indicates the target language isn't a language tag
+	ErrInvalidLanguage = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "target language must be a BCP-47 language tag")
+
+	// ErrSubjectNotFound indicates the post or comment isn't indexed or isn't visible
+	ErrSubjectNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "post or comment not found")
+)
@@ -0,0 +1,72 @@
+package translation
+
+import (
+	"context"
+	"time"
+)
+
+// Subject is the text of one version of a post or comment
+type Subject struct {
+	Title   *string // Posts only
+	URI     string
+	CID     string
+	Content string
+	Lang    string // The record's first langs entry, else the detected language; "" when unknown
+}
+
+// Translation is a subject version's text in another language
+// Cached per (SubjectURI, SubjectCID, TargetLang): an edit changes the CID, so translations of
+// the previous text are never served for the new one.
+type Translation struct {
+	CreatedAt  time.Time `json:"-"`
+	Title      *string   `json:"title,omitempty"`
+	SubjectURI string    `json:"uri"`
+	SubjectCID string    `json:"cid"`
+	SourceLang string    `json:"sourceLang,omitempty"` // "" when neither the subject nor the backend knew
+	TargetLang string    `json:"targetLang"`
+	Content    string    `json:"content"`
+}
+
+// Repository loads subjects and caches their translations
+type Repository interface {
+	// GetSubject returns the current version of a post or comment anyone may read
+	// Returns ErrSubjectNotFound for unknown, deleted, removed or hidden subjects.
+	GetSubject(ctx context.Context, uri string) (*Subject, error)
+
+	// GetTranslation returns the cached translation of a subject version, or nil when none is cached
+	GetTranslation(ctx context.Context, uri, cid, targetLang string) (*Translation, error)
+
+	// SaveTranslation caches a translation, dropping any cached for earlier versions of the subject
+	SaveTranslation(ctx context.Context, translation *Translation) error
+}
+
+// BackendRequest is a batch of texts to translate
+type BackendRequest struct {
+	Texts      []string
+	SourceLang string // "" lets the backend detect it
+	TargetLang string
+}
+
+// BackendResponse holds the translated texts, in request order
+type BackendResponse struct {
+	Texts      []string
+	SourceLang string // Detected by the backend when the request didn't say; may be ""
+}
+
+// Backend is an external machine translation service
+type Backend interface {
+	Translate(ctx context.Context, req BackendRequest) (*BackendResponse, error)
+}
+
+// TranslateRequest asks for a post or comment in another language
+type TranslateRequest struct {
+	SubjectURI string
+	TargetLang string
+}
+
+// Service translates posts and comments on demand
+type Service interface {
+	// Translate returns the subject's current text in the target language
+	// Returns ErrNotConfigured when the instance has no translation backend.
+	Translate(ctx context.Context, req TranslateRequest) (*Translation, error)
+}
@@ -0,0 +1,103 @@
+package translation
+
+// Training text for the Latin-script languages DetectLanguage tells apart
+// Plain, everyday prose of the kind posted in communities: news, questions, opinions. The
+// trigram model is built from it once, at package init.
+var latinSamples = map[string]string{
+	"en": `The city council met on Tuesday evening to discuss the new bike lanes that have been
+planned for the main street. Many people who live in the area said they were worried about
+parking, while others thanked the council for finally taking safety seriously. Does anyone
+know when the work is supposed to start? I have been waiting for this for years and I think
+it will make a huge difference for the children who ride to school every morning. The weather
+this weekend should be nice, so we are going to the park with some friends and their dogs.
+If you have any recommendations for a good place to eat nearby, please let me know in the
+comments. I would also like to hear what you think about the changes to the library hours,
+because they are closing much earlier than they used to. Thank you all for being such a
+helpful and friendly community, it really means a lot to me and my family.`,
+
+	"es": `El ayuntamiento se reunió el martes por la tarde para hablar de los nuevos carriles
+para bicicletas que se han planeado en la calle principal. Muchas personas que viven en la
+zona dijeron que estaban preocupadas por el aparcamiento, mientras que otras agradecieron que
+por fin se tome en serio la seguridad. ¿Alguien sabe cuándo se supone que empiezan las obras?
+Llevo años esperando esto y creo que será una gran diferencia para los niños que van al
+colegio en bicicleta cada mañana. El tiempo este fin de semana debería ser bueno, así que
+vamos a ir al parque con unos amigos y sus perros. Si tenéis alguna recomendación de un buen
+sitio para comer cerca, por favor decídmelo en los comentarios. También me gustaría saber qué
+pensáis de los cambios en el horario de la biblioteca, porque ahora cierran mucho antes que
+antes. Gracias a todos por ser una comunidad tan amable y útil, de verdad significa mucho
+para mí y para mi familia.`,
+
+	"fr": `Le conseil municipal s'est réuni mardi soir pour discuter des nouvelles pistes
+cyclables prévues dans la rue principale. Beaucoup de gens qui habitent dans le quartier ont
+dit qu'ils étaient inquiets pour le stationnement, tandis que d'autres ont remercié le conseil
+de prendre enfin la sécurité au sérieux. Est-ce que quelqu'un sait quand les travaux sont
+censés commencer ? J'attends cela depuis des années et je pense que cela fera une énorme
+différence pour les enfants qui vont à l'école à vélo chaque matin. Le temps ce week-end
+devrait être beau, alors nous allons au parc avec des amis et leurs chiens. Si vous avez des
+recommandations pour un bon endroit où manger près d'ici, dites-le-moi dans les commentaires.
+J'aimerais aussi savoir ce que vous pensez des changements dans les horaires de la
+bibliothèque, parce qu'elle ferme beaucoup plus tôt qu'avant. Merci à tous d'être une
+communauté aussi serviable et sympathique, cela compte vraiment beaucoup pour moi et pour ma
+famille.`,
+
+	"de": `Der Stadtrat hat sich am Dienstagabend getroffen, um über die neuen Radwege zu
+sprechen, die für die Hauptstraße geplant sind. Viele Leute, die in der Gegend wohnen, sagten,
+dass sie sich Sorgen um die Parkplätze machen, während andere dem Rat dafür dankten, dass er
+die Sicherheit endlich ernst nimmt. Weiß jemand, wann die Arbeiten beginnen sollen? Ich warte
+schon seit Jahren darauf und ich glaube, dass es für die Kinder, die jeden Morgen mit dem
+Fahrrad zur Schule fahren, einen großen Unterschied machen wird. Das Wetter soll an diesem
+Wochenende schön werden, also gehen wir mit ein paar Freunden und ihren Hunden in den Park.
+Wenn ihr Empfehlungen für ein gutes Restaurant in der Nähe habt, schreibt es mir bitte in die
+Kommentare. Ich würde auch gerne wissen, was ihr von den neuen Öffnungszeiten der Bibliothek
+haltet, weil sie jetzt viel früher schließt als sonst. Vielen Dank an alle, dass ihr so eine
+hilfsbereite und freundliche Gemeinschaft seid, das bedeutet mir und meiner Familie wirklich
+sehr viel.`,
+
+	"it": `Il consiglio comunale si è riunito martedì sera per discutere delle nuove piste
+ciclabili previste nella strada principale. Molte persone che abitano nella zona hanno detto
+di essere preoccupate per i parcheggi, mentre altre hanno ringraziato il consiglio per aver
+finalmente preso sul serio la sicurezza. Qualcuno sa quando dovrebbero iniziare i lavori?
+Aspetto questo da anni e penso che farà una grande differenza per i bambini che vanno a
+scuola in bicicletta ogni mattina. Il tempo questo fine settimana dovrebbe essere bello,
+quindi andiamo al parco con degli amici e i loro cani. Se avete qualche consiglio per un buon
+posto dove mangiare qui vicino, fatemelo sapere nei commenti. Mi piacerebbe anche sapere cosa
+ne pensate dei cambiamenti negli orari della biblioteca, perché adesso chiude molto prima di
+una volta. Grazie a tutti per essere una comunità così disponibile e gentile, significa
+davvero molto per me e per la mia famiglia.`,
+
+	"pt": `A câmara municipal reuniu-se na terça-feira à noite para discutir as novas
+ciclovias que foram planeadas para a rua principal. Muitas pessoas que moram na zona disseram
+que estavam preocupadas com o estacionamento, enquanto outras agradeceram à câmara por
+finalmente levar a segurança a sério. Alguém sabe quando é que as obras devem começar? Estou
+à espera disto há anos e acho que vai fazer uma grande diferença para as crianças que vão
+para a escola de bicicleta todas as manhãs. O tempo neste fim de semana deve estar bom, por
+isso vamos ao parque com uns amigos e os seus cães. Se tiverem alguma recomendação de um bom
+sítio para comer aqui perto, digam-me nos comentários. Também gostava de saber o que acham
+das mudanças no horário da biblioteca, porque agora fecha muito mais cedo do que antes.
+Obrigado a todos por serem uma comunidade tão prestável e simpática, isso significa muito
+para mim e para a minha família. Não sei se vocês também viram a notícia sobre a nova
+estação, mas parece que não vai abrir este ano.`,
+
+	"nl": `De gemeenteraad kwam dinsdagavond bijeen om te praten over de nieuwe fietspaden die
+voor de hoofdstraat gepland zijn. Veel mensen die in de buurt wonen zeiden dat ze zich zorgen
+maken over het parkeren, terwijl anderen de raad bedankten omdat de veiligheid eindelijk
+serieus wordt genomen. Weet iemand wanneer het werk zou moeten beginnen? Ik wacht hier al
+jaren op en ik denk dat het een groot verschil zal maken voor de kinderen die elke ochtend
+met de fiets naar school gaan. Het weer dit weekend zou mooi moeten zijn, dus we gaan met een
+paar vrienden en hun honden naar het park. Als jullie tips hebben voor een goede plek om in
+de buurt te eten, laat het me dan weten in de reacties. Ik zou ook graag horen wat jullie
+vinden van de nieuwe openingstijden van de bibliotheek, want ze sluiten nu veel eerder dan
+vroeger. Bedankt allemaal dat jullie zo'n behulpzame en vriendelijke gemeenschap zijn, het
+betekent echt veel voor mij en mijn familie.`,
+
+	"pl": `Rada miejska spotkała się we wtorek wieczorem, aby omówić nowe ścieżki rowerowe
+zaplanowane na głównej ulicy. Wiele osób mieszkających w okolicy mówiło, że martwi się o
+miejsca parkingowe, a inni dziękowali radzie za to, że w końcu poważnie traktuje
+bezpieczeństwo. Czy ktoś wie, kiedy mają się zacząć prace? Czekam na to od lat i myślę, że to
+będzie ogromna różnica dla dzieci, które codziennie rano jeżdżą rowerem do szkoły. Pogoda w
+ten weekend ma być ładna, więc idziemy do parku ze znajomymi i ich psami. Jeśli macie jakieś
+polecenia dobrego miejsca, gdzie można zjeść w pobliżu, napiszcie mi w komentarzach. Chętnie
+też usłyszę, co myślicie o zmianach godzin otwarcia biblioteki, bo teraz zamykają dużo
+wcześniej niż kiedyś. Dziękuję wszystkim za to, że jesteście tak pomocną i przyjazną
+społecznością, to naprawdę wiele znaczy dla mnie i mojej rodziny.`,
+}
@@ -0,0 +1,129 @@
+package translation
+
+import (
+	"Coves/internal/atproto/aturi"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	postCollection    = "social.coves.community.post"
+	commentCollection = "social.coves.community.comment"
+)
+
+type translationService struct {
+	repo    Repository
+	backend Backend
+}
+
+// NewTranslationService creates a new translation service
+// backend may be nil: Translate then returns ErrNotConfigured.
+func NewTranslationService(repo Repository, backend Backend) Service {
+	return &translationService{
+		repo:    repo,
+		backend: backend,
+	}
+}
+
+// Translate returns the subject's current text in the target language
+// Subjects already in the target language come back unchanged without calling the backend.
+// Otherwise a cached translation of the current version is served, or the backend is asked
+// and its answer cached.
+func (s *translationService) Translate(ctx context.Context, req TranslateRequest) (*Translation, error) {
+	if s.backend == nil {
+		return nil, ErrNotConfigured
+	}
+
+	uri, err := aturi.Parse(req.SubjectURI)
+	if err != nil || uri.RKey == "" || (uri.Collection != postCollection && uri.Collection != commentCollection) {
+		return nil, ErrInvalidSubject
+	}
+	tag, err := language.Parse(req.TargetLang)
+	if err != nil {
+		return nil, ErrInvalidLanguage
+	}
+	target := tag.String()
+
+	subject, err := s.repo.GetSubject(ctx, uri.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if sameLanguage(subject.Lang, target) {
+		return &Translation{
+			SubjectURI: subject.URI,
+			SubjectCID: subject.CID,
+			SourceLang: subject.Lang,
+			TargetLang: target,
+			Title:      subject.Title,
+			Content:    subject.Content,
+			CreatedAt:  time.Now(),
+		}, nil
+	}
+
+	cached, err := s.repo.GetTranslation(ctx, subject.URI, subject.CID, target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cached translation: %w", err)
+	}
+	if cached != nil {
+		return cached, nil
+	}
+
+	texts := []string{subject.Content}
+	if subject.Title != nil {
+		texts = append(texts, *subject.Title)
+	}
+	resp, err := s.backend.Translate(ctx, BackendRequest{Texts: texts, SourceLang: subject.Lang, TargetLang: target})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrBackendFailed, err)
+	}
+	if len(resp.Texts) != len(texts) {
+		return nil, fmt.Errorf("%w: sent %d texts, got %d back", ErrBackendFailed, len(texts), len(resp.Texts))
+	}
+
+	translation := &Translation{
+		SubjectURI: subject.URI,
+		SubjectCID: subject.CID,
+		SourceLang: subject.Lang,
+		TargetLang: target,
+		Content:    resp.Texts[0],
+		CreatedAt:  time.Now(),
+	}
+	if subject.Title != nil {
+		translation.Title = &resp.Texts[1]
+	}
+	if translation.SourceLang == "" {
+		translation.SourceLang = resp.SourceLang
+	}
+
+	// A translation that can't be cached is still served; the next request asks the backend again
+	if err := s.repo.SaveTranslation(ctx, translation); err != nil {
+		log.Printf("Warning: failed to cache translation of %s into %s: %v", subject.URI, target, err)
+	}
+	return translation, nil
+}
+
+// sameLanguage reports whether source and target are the same language and script, ignoring
+// region ("en" and "en-GB", but not "zh-Hans" and "zh-Hant"); an unknown source never matches
+func sameLanguage(source, target string) bool {
+	if source == "" {
+		return false
+	}
+	sourceTag, err := language.Parse(source)
+	if err != nil {
+		return false
+	}
+	targetTag, err := language.Parse(target)
+	if err != nil {
+		return false
+	}
+	sourceBase, _ := sourceTag.Base()
+	targetBase, _ := targetTag.Base()
+	sourceScript, _ := sourceTag.Script()
+	targetScript, _ := targetTag.Script()
+	return sourceBase == targetBase && sourceScript == targetScript
+}
@@ -0,0 +1,212 @@
+package translation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	coreerrors "Coves/internal/core/errors"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testPostURI = "at://did:plc:community/social.coves.community.post/3kpost"
+
+type fakeRepo struct {
+	subjects     map[string]*Subject
+	translations map[string]*Translation
+	saveErr      error
+	saves        int
+}
+
+func newFakeRepo(subjects ...*Subject) *fakeRepo {
+	repo := &fakeRepo{subjects: make(map[string]*Subject), translations: make(map[string]*Translation)}
+	for _, s := range subjects {
+		repo.subjects[s.URI] = s
+	}
+	return repo
+}
+
+func (r *fakeRepo) GetSubject(_ context.Context, uri string) (*Subject, error) {
+	subject, ok := r.subjects[uri]
+	if !ok {
+		return nil, ErrSubjectNotFound
+	}
+	copied := *subject
+	return &copied, nil
+}
+
+func (r *fakeRepo) GetTranslation(_ context.Context, uri, cid, targetLang string) (*Translation, error) {
+	return r.translations[uri+"|"+cid+"|"+targetLang], nil
+}
+
+func (r *fakeRepo) SaveTranslation(_ context.Context, t *Translation) error {
+	r.saves++
+	if r.saveErr != nil {
+		return r.saveErr
+	}
+	for key, cached := range r.translations {
+		if cached.SubjectURI == t.SubjectURI && cached.SubjectCID != t.SubjectCID {
+			delete(r.translations, key)
+		}
+	}
+	r.translations[t.SubjectURI+"|"+t.SubjectCID+"|"+t.TargetLang] = t
+	return nil
+}
+
+type fakeBackend struct {
+	err      error
+	requests []BackendRequest
+	detected string
+}
+
+func (b *fakeBackend) Translate(_ context.Context, req BackendRequest) (*BackendResponse, error) {
+	b.requests = append(b.requests, req)
+	if b.err != nil {
+		return nil, b.err
+	}
+	texts := make([]string, len(req.Texts))
+	for i, text := range req.Texts {
+		texts[i] = "[" + req.TargetLang + "] " + text
+	}
+	return &BackendResponse{Texts: texts, SourceLang: b.detected}, nil
+}
+
+func ptr(s string) *string { return &s }
+
+func testSubject() *Subject {
+	return &Subject{
+		URI:     testPostURI,
+		CID:     "bafyv1",
+		Title:   ptr("Hola"),
+		Content: "Buenos días a todos",
+		Lang:    "es",
+	}
+}
+
+func TestTranslate_NotConfigured(t *testing.T) {
+	service := NewTranslationService(newFakeRepo(testSubject()), nil)
+
+	_, err := service.Translate(context.Background(), TranslateRequest{SubjectURI: testPostURI, TargetLang: "en"})
+	assert.ErrorIs(t, err, ErrNotConfigured)
+}
+
+func TestTranslate_InvalidInput(t *testing.T) {
+	service := NewTranslationService(newFakeRepo(testSubject()), &fakeBackend{})
+
+	tests := []struct {
+		name    string
+		req     TranslateRequest
+		wantErr error
+	}{
+		{"not an at-uri", TranslateRequest{SubjectURI: "https://example.com", TargetLang: "en"}, ErrInvalidSubject},
+		{"not a post or comment", TranslateRequest{SubjectURI: "at://did:plc:user/social.coves.community.profile/self", TargetLang: "en"}, ErrInvalidSubject},
+		{"no rkey", TranslateRequest{SubjectURI: "at://did:plc:user/social.coves.community.post", TargetLang: "en"}, ErrInvalidSubject},
+		{"invalid language", TranslateRequest{SubjectURI: testPostURI, TargetLang: "not a language"}, ErrInvalidLanguage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Translate(context.Background(), tt.req)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.ErrorIs(t, err, coreerrors.ErrInvalidInput)
+		})
+	}
+}
+
+func TestTranslate_SubjectNotFound(t *testing.T) {
+	service := NewTranslationService(newFakeRepo(), &fakeBackend{})
+
+	_, err := service.Translate(context.Background(), TranslateRequest{SubjectURI: testPostURI, TargetLang: "en"})
+	assert.ErrorIs(t, err, coreerrors.ErrNotFound)
+}
+
+func TestTranslate_TranslatesAndCaches(t *testing.T) {
+	repo := newFakeRepo(testSubject())
+	backend := &fakeBackend{}
+	service := NewTranslationService(repo, backend)
+
+	result, err := service.Translate(context.Background(), TranslateRequest{SubjectURI: testPostURI, TargetLang: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, "[en] Buenos días a todos", result.Content)
+	require.NotNil(t, result.Title)
+	assert.Equal(t, "[en] Hola", *result.Title)
+	assert.Equal(t, "es", result.SourceLang)
+	assert.Equal(t, "en", result.TargetLang)
+	assert.Equal(t, "bafyv1", result.SubjectCID)
+	require.Len(t, backend.requests, 1)
+	assert.Equal(t, BackendRequest{Texts: []string{"Buenos días a todos", "Hola"}, SourceLang: "es", TargetLang: "en"}, backend.requests[0])
+
+	again, err := service.Translate(context.Background(), TranslateRequest{SubjectURI: testPostURI, TargetLang: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, result.Content, again.Content)
+	assert.Len(t, backend.requests, 1, "cached translation should not call the backend")
+}
+
+func TestTranslate_EditInvalidatesCache(t *testing.T) {
+	repo := newFakeRepo(testSubject())
+	backend := &fakeBackend{}
+	service := NewTranslationService(repo, backend)
+
+	_, err := service.Translate(context.Background(), TranslateRequest{SubjectURI: testPostURI, TargetLang: "en"})
+	require.NoError(t, err)
+
+	// An edit changes the CID and the text
+	repo.subjects[testPostURI].CID = "bafyv2"
+	repo.subjects[testPostURI].Content = "Buenas noches"
+
+	result, err := service.Translate(context.Background(), TranslateRequest{SubjectURI: testPostURI, TargetLang: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, "[en] Buenas noches", result.Content)
+	assert.Equal(t, "bafyv2", result.SubjectCID)
+	assert.Len(t, backend.requests, 2)
+	assert.Len(t, repo.translations, 1, "translations of the old version should be dropped")
+}
+
+func TestTranslate_SameLanguageSkipsBackend(t *testing.T) {
+	subject := testSubject()
+	subject.Lang = "en-GB"
+	backend := &fakeBackend{}
+	repo := newFakeRepo(subject)
+	service := NewTranslationService(repo, backend)
+
+	result, err := service.Translate(context.Background(), TranslateRequest{SubjectURI: testPostURI, TargetLang: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, subject.Content, result.Content)
+	assert.Empty(t, backend.requests)
+	assert.Zero(t, repo.saves)
+}
+
+func TestTranslate_UnknownSourceUsesDetectedLanguage(t *testing.T) {
+	subject := testSubject()
+	subject.Lang = ""
+	subject.Title = nil
+	backend := &fakeBackend{detected: "es"}
+	service := NewTranslationService(newFakeRepo(subject), backend)
+
+	result, err := service.Translate(context.Background(), TranslateRequest{SubjectURI: testPostURI, TargetLang: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, "es", result.SourceLang)
+	assert.Nil(t, result.Title)
+	assert.Equal(t, []string{"Buenos días a todos"}, backend.requests[0].Texts)
+	assert.Empty(t, backend.requests[0].SourceLang)
+}
+
+func TestTranslate_BackendFailure(t *testing.T) {
+	repo := newFakeRepo(testSubject())
+	service := NewTranslationService(repo, &fakeBackend{err: errors.New("connection refused")})
+
+	_, err := service.Translate(context.Background(), TranslateRequest{SubjectURI: testPostURI, TargetLang: "en"})
+	assert.ErrorIs(t, err, ErrBackendFailed)
+	assert.Zero(t, repo.saves)
+}
+
+func TestTranslate_CacheFailureStillServes(t *testing.T) {
+	repo := newFakeRepo(testSubject())
+	repo.saveErr = errors.New("db down")
+	service := NewTranslationService(repo, &fakeBackend{})
+
+	result, err := service.Translate(context.Background(), TranslateRequest{SubjectURI: testPostURI, TargetLang: "en"})
+	require.NoError(t, err)
+	assert.Equal(t, "[en] Buenos días a todos", result.Content)
+}
@@ -0,0 +1,188 @@
+package users
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Activity heatmap limits
+const (
+	DefaultActivityDays = 365
+	MaxActivityDays     = 366 // A leap year; also caps the response at 366 entries
+	ActivityCacheTTL    = time.Hour
+
+	// maxActivityCacheEntries bounds the cache; expired entries are swept once it fills up
+	maxActivityCacheEntries = 10000
+)
+
+// ActivityDay holds one UTC day's post and comment counts
+type ActivityDay struct {
+	Date     string `json:"date"` // YYYY-MM-DD in UTC
+	Posts    int    `json:"posts"`
+	Comments int    `json:"comments"`
+}
+
+// GetActivityResponse is the social.coves.actor.getActivity output
+// Days with no activity are omitted; clients fill them in when drawing the heatmap.
+type GetActivityResponse struct {
+	Days []ActivityDay `json:"days"`
+}
+
+// ActivityRepository counts an actor's contributions and tracks account deactivation
+type ActivityRepository interface {
+	// CountPostsByDay returns the actor's non-deleted, visible post counts keyed by UTC day
+	// (YYYY-MM-DD), for posts created at or after since
+	CountPostsByDay(ctx context.Context, did string, since time.Time) (map[string]int, error)
+	// CountCommentsByDay is CountPostsByDay for comments
+	CountCommentsByDay(ctx context.Context, did string, since time.Time) (map[string]int, error)
+
+	// IsDeactivated reports whether the account is deactivated (unknown DIDs are not)
+	IsDeactivated(ctx context.Context, did string) (bool, error)
+	// SetDeactivated records an account status change from the firehose
+	// DIDs that aren't indexed are ignored.
+	SetDeactivated(ctx context.Context, did string, deactivated bool) error
+}
+
+// ActivityService serves the profile activity heatmap
+type ActivityService interface {
+	// GetActivity returns the actor's daily post and comment counts over the last days UTC
+	// days, including today. Deactivated accounts have no activity.
+	GetActivity(ctx context.Context, did string, days int) (*GetActivityResponse, error)
+}
+
+type activityService struct {
+	repo    ActivityRepository
+	now     func() time.Time
+	entries map[string]activityEntry
+	loads   singleflight.Group
+	mu      sync.RWMutex
+}
+
+// activityEntry is an actor's cached activity over the last MaxActivityDays
+type activityEntry struct {
+	expiresAt time.Time
+	days      []ActivityDay // Oldest first
+}
+
+// NewActivityService creates an activity service that caches each actor's counts for an hour
+func NewActivityService(repo ActivityRepository) ActivityService {
+	return &activityService{
+		repo:    repo,
+		now:     time.Now,
+		entries: make(map[string]activityEntry),
+	}
+}
+
+// GetActivity returns the actor's activity, served from the per-actor cache when fresh
+// Deactivation is checked on every call so a deactivated account disappears immediately
+// rather than when its cache entry expires.
+func (s *activityService) GetActivity(ctx context.Context, did string, days int) (*GetActivityResponse, error) {
+	if days <= 0 {
+		days = DefaultActivityDays
+	}
+	if days > MaxActivityDays {
+		days = MaxActivityDays
+	}
+
+	deactivated, err := s.repo.IsDeactivated(ctx, did)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check account status: %w", err)
+	}
+	if deactivated {
+		return &GetActivityResponse{Days: []ActivityDay{}}, nil
+	}
+
+	all, err := s.load(ctx, did)
+	if err != nil {
+		return nil, err
+	}
+
+	// The cache holds the longest window; narrower requests take its tail
+	cutoff := activityWindowStart(s.now(), days).Format(time.DateOnly)
+	start := sort.Search(len(all), func(i int) bool { return all[i].Date >= cutoff })
+	return &GetActivityResponse{Days: append([]ActivityDay{}, all[start:]...)}, nil
+}
+
+// load returns the actor's activity over MaxActivityDays, querying on a miss or expiry
+// Concurrent misses for the same actor share one pair of queries.
+func (s *activityService) load(ctx context.Context, did string) ([]ActivityDay, error) {
+	s.mu.RLock()
+	entry, ok := s.entries[did]
+	s.mu.RUnlock()
+	if ok && s.now().Before(entry.expiresAt) {
+		return entry.days, nil
+	}
+
+	result, err, _ := s.loads.Do(did, func() (interface{}, error) {
+		since := activityWindowStart(s.now(), MaxActivityDays)
+		postCounts, err := s.repo.CountPostsByDay(ctx, did, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count posts: %w", err)
+		}
+		commentCounts, err := s.repo.CountCommentsByDay(ctx, did, since)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count comments: %w", err)
+		}
+		days := MergeActivity(postCounts, commentCounts)
+		s.put(did, days)
+		return days, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]ActivityDay), nil
+}
+
+func (s *activityService) put(did string, days []ActivityDay) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if len(s.entries) >= maxActivityCacheEntries {
+		for key, entry := range s.entries {
+			if !now.Before(entry.expiresAt) {
+				delete(s.entries, key)
+			}
+		}
+	}
+	s.entries[did] = activityEntry{days: days, expiresAt: now.Add(ActivityCacheTTL)}
+}
+
+// activityWindowStart returns midnight UTC at the start of a window of days days ending today
+func activityWindowStart(now time.Time, days int) time.Time {
+	y, m, d := now.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC).AddDate(0, 0, -(days - 1))
+}
+
+// MergeActivity combines per-day post and comment counts into days sorted oldest first
+// Days with neither posts nor comments are left out.
+func MergeActivity(postCounts, commentCounts map[string]int) []ActivityDay {
+	byDate := make(map[string]*ActivityDay, len(postCounts)+len(commentCounts))
+	day := func(date string) *ActivityDay {
+		if d, ok := byDate[date]; ok {
+			return d
+		}
+		d := &ActivityDay{Date: date}
+		byDate[date] = d
+		return d
+	}
+	for date, n := range postCounts {
+		day(date).Posts += n
+	}
+	for date, n := range commentCounts {
+		day(date).Comments += n
+	}
+
+	days := make([]ActivityDay, 0, len(byDate))
+	for _, d := range byDate {
+		if d.Posts > 0 || d.Comments > 0 {
+			days = append(days, *d)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Date < days[j].Date })
+	return days
+}
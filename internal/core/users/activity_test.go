@@ -0,0 +1,156 @@
+package users
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeActivityRepo serves canned per-day counts and records the windows it was asked for
+type fakeActivityRepo struct {
+	posts       map[string]int
+	comments    map[string]int
+	deactivated bool
+	sinces      []time.Time
+}
+
+func (r *fakeActivityRepo) CountPostsByDay(ctx context.Context, did string, since time.Time) (map[string]int, error) {
+	r.sinces = append(r.sinces, since)
+	return filterSince(r.posts, since), nil
+}
+
+func (r *fakeActivityRepo) CountCommentsByDay(ctx context.Context, did string, since time.Time) (map[string]int, error) {
+	return filterSince(r.comments, since), nil
+}
+
+func (r *fakeActivityRepo) IsDeactivated(ctx context.Context, did string) (bool, error) {
+	return r.deactivated, nil
+}
+
+func (r *fakeActivityRepo) SetDeactivated(ctx context.Context, did string, deactivated bool) error {
+	r.deactivated = deactivated
+	return nil
+}
+
+func filterSince(counts map[string]int, since time.Time) map[string]int {
+	cutoff := since.Format(time.DateOnly)
+	out := make(map[string]int)
+	for day, n := range counts {
+		if day >= cutoff {
+			out[day] = n
+		}
+	}
+	return out
+}
+
+func newTestActivityService(repo ActivityRepository, now *time.Time) *activityService {
+	service := NewActivityService(repo).(*activityService)
+	service.now = func() time.Time { return *now }
+	return service
+}
+
+func TestActivity_WindowStartsAtUTCMidnight(t *testing.T) {
+	repo := &fakeActivityRepo{
+		posts: map[string]int{"2026-02-28": 2, "2026-03-01": 1},
+	}
+	// 00:30 UTC on March 1st is still February 28th in New York
+	now := time.Date(2026, 2, 28, 19, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	service := newTestActivityService(repo, &now)
+
+	resp, err := service.GetActivity(context.Background(), "did:plc:alice", 1)
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if len(resp.Days) != 1 || resp.Days[0].Date != "2026-03-01" {
+		t.Fatalf("Expected only the current UTC day, got %+v", resp.Days)
+	}
+
+	resp, err = service.GetActivity(context.Background(), "did:plc:alice", 2)
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if len(resp.Days) != 2 || resp.Days[0].Date != "2026-02-28" {
+		t.Fatalf("Expected the window to reach back over the month boundary, got %+v", resp.Days)
+	}
+
+	// The repository is always asked for the full window, starting at midnight UTC
+	want := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	if len(repo.sinces) != 1 || !repo.sinces[0].Equal(want) {
+		t.Errorf("Expected one query since %v, got %v", want, repo.sinces)
+	}
+}
+
+func TestActivity_CachedPerActorForAnHour(t *testing.T) {
+	repo := &fakeActivityRepo{posts: map[string]int{"2026-03-01": 1}}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := newTestActivityService(repo, &now)
+	ctx := context.Background()
+
+	for _, days := range []int{365, 30, 7} {
+		if _, err := service.GetActivity(ctx, "did:plc:alice", days); err != nil {
+			t.Fatalf("GetActivity failed: %v", err)
+		}
+	}
+	if len(repo.sinces) != 1 {
+		t.Fatalf("Expected windows of any size served from one cached load, got %d loads", len(repo.sinces))
+	}
+
+	if _, err := service.GetActivity(ctx, "did:plc:bob", 365); err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if len(repo.sinces) != 2 {
+		t.Fatalf("Expected a separate load per actor, got %d loads", len(repo.sinces))
+	}
+
+	now = now.Add(ActivityCacheTTL)
+	repo.posts["2026-03-01"] = 5
+	resp, err := service.GetActivity(ctx, "did:plc:alice", 365)
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if len(repo.sinces) != 3 || len(resp.Days) != 1 || resp.Days[0].Posts != 5 {
+		t.Errorf("Expected the expired entry reloaded, got %d loads and %+v", len(repo.sinces), resp.Days)
+	}
+}
+
+func TestActivity_DeactivatedAccountIsEmpty(t *testing.T) {
+	repo := &fakeActivityRepo{posts: map[string]int{"2026-03-01": 3}}
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := newTestActivityService(repo, &now)
+	ctx := context.Background()
+
+	if resp, err := service.GetActivity(ctx, "did:plc:alice", 365); err != nil || len(resp.Days) != 1 {
+		t.Fatalf("Expected activity before deactivation, got %+v, %v", resp, err)
+	}
+
+	// Deactivation applies immediately, even with a cached entry
+	repo.deactivated = true
+	resp, err := service.GetActivity(ctx, "did:plc:alice", 365)
+	if err != nil {
+		t.Fatalf("GetActivity failed: %v", err)
+	}
+	if resp.Days == nil || len(resp.Days) != 0 {
+		t.Errorf("Expected an empty (non-nil) day list, got %#v", resp.Days)
+	}
+}
+
+func TestMergeActivity(t *testing.T) {
+	days := MergeActivity(
+		map[string]int{"2026-01-31": 2, "2026-02-01": 1, "2025-12-31": 0},
+		map[string]int{"2026-02-01": 4, "2026-01-01": 3},
+	)
+
+	want := []ActivityDay{
+		{Date: "2026-01-01", Comments: 3},
+		{Date: "2026-01-31", Posts: 2},
+		{Date: "2026-02-01", Posts: 1, Comments: 4},
+	}
+	if len(days) != len(want) {
+		t.Fatalf("Expected %d days (zero days omitted), got %+v", len(want), days)
+	}
+	for i := range want {
+		if days[i] != want[i] {
+			t.Errorf("Day %d: expected %+v, got %+v", i, want[i], days[i])
+		}
+	}
+}
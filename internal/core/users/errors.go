@@ -3,15 +3,26 @@ package users
 import (
 	"errors"
 	"fmt"
+
+	coreerrors "Coves/internal/core/errors"
 )
 
 // Sentinel errors for common user operations
 var (
 	// ErrUserNotFound is returned when a user lookup finds no matching record
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "user not found")
+
+	// ErrUserAlreadyExists is returned when creating a user whose DID is already indexed
+	ErrUserAlreadyExists = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "user already exists")
 
 	// ErrHandleAlreadyTaken is returned when attempting to use a handle that belongs to another user
-	ErrHandleAlreadyTaken = errors.New("handle already taken")
+	ErrHandleAlreadyTaken = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "handle already taken")
+
+	// ErrPreferencesNotFound is returned when a user has no indexed preferences record
+	ErrPreferencesNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "preferences not found")
+
+	// ErrStaleRevision is returned when a firehose write is older than the indexed version of the record
+	ErrStaleRevision = errors.New("user record write is older than the indexed revision")
 )
 
 // Domain errors for user service operations
@@ -80,3 +91,13 @@ func (e *InvalidDIDError) Error() string {
 	}
 	return fmt.Sprintf("invalid DID %q: must start with 'did:'", e.DID)
 }
+
+// IsNotFound checks if an error is a "not found" error
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrPreferencesNotFound)
+}
+
+// IsConflict checks if an error is a conflict error (duplicate)
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrUserAlreadyExists) || errors.Is(err, ErrHandleAlreadyTaken)
+}
@@ -5,11 +5,15 @@ import "context"
 // UpdateProfileInput contains the fields that can be updated on a user's profile.
 // Nil values mean "don't change this field" - only non-nil values are updated.
 // Empty string values (*string pointing to "") will clear the field in the database.
+// Rev is the repo rev of the firehose commit that wrote (or deleted) the profile record; an
+// update with a rev that isn't newer than the stored one fails with ErrStaleRevision. Writes
+// that don't come from a commit leave it empty.
 type UpdateProfileInput struct {
 	DisplayName *string
 	Bio         *string
 	AvatarCID   *string
 	BannerCID   *string
+	Rev         string
 }
 
 // UserRepository defines the interface for user data persistence
@@ -51,6 +55,13 @@ type UserRepository interface {
 	// Returns ErrUserNotFound if the user does not exist.
 	UpdateProfile(ctx context.Context, did string, input UpdateProfileInput) (*User, error)
 
+	// ListPendingProfiles returns up to limit users whose profile hasn't been fetched yet, oldest first.
+	// UpdateProfile and MarkProfileFetched clear the flag.
+	ListPendingProfiles(ctx context.Context, limit int) ([]*User, error)
+
+	// MarkProfileFetched records that a user's profile was fetched, for users with no profile record
+	MarkProfileFetched(ctx context.Context, did string) error
+
 	// Delete removes a user and all associated data from the AppView database.
 	// This performs a cascading delete across all tables that reference the user's DID.
 	// The operation is atomic - either all data is deleted or none.
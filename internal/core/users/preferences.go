@@ -0,0 +1,209 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+// PreferencesCollection is the atProto collection for a user's Coves viewing preferences.
+// The record lives at rkey "self" in the user's repo.
+const PreferencesCollection = "social.coves.actor.preferences"
+
+// Preferences is the AppView's view of a user's social.coves.actor.preferences record.
+// Record holds the whole record so preferences the AppView doesn't know about yet survive
+// re-indexing; known fields are extracted for querying.
+type Preferences struct {
+	IndexedAt           time.Time       `json:"indexedAt"`
+	UserDID             string          `json:"userDid"`
+	URI                 string          `json:"uri"`
+	CID                 string          `json:"cid"`
+	Rev                 string          `json:"-"` // Repo rev of the commit that wrote the record
+	Record              json.RawMessage `json:"record"`
+	HideAggregatorPosts bool            `json:"hideAggregatorPosts"`
+}
+
+// ParsePreferences builds Preferences from a preferences record, extracting the known fields.
+// Known fields with the wrong type are rejected rather than silently ignored.
+func ParsePreferences(userDID, uri, cid string, record map[string]interface{}) (*Preferences, error) {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode preferences record: %w", err)
+	}
+
+	prefs := &Preferences{
+		UserDID: userDID,
+		URI:     uri,
+		CID:     cid,
+		Record:  raw,
+	}
+
+	if v, ok := record["hideAggregatorPosts"]; ok && v != nil {
+		hide, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("hideAggregatorPosts must be a boolean, got %T", v)
+		}
+		prefs.HideAggregatorPosts = hide
+	}
+
+	return prefs, nil
+}
+
+// Server-side preference defaults and limits
+// New preference keys get a default here so users whose records predate them see sane values.
+const (
+	DefaultFeedSort      = "hot"
+	MaxLanguages         = 10
+	MaxHiddenCommunities = 500
+)
+
+// validFeedSorts are the sorts shared by the timeline and discover feeds
+var validFeedSorts = map[string]bool{"hot": true, "top": true, "new": true}
+
+// ErrInvalidPreferences wraps every preferences validation failure
+var ErrInvalidPreferences = errors.New("invalid preferences")
+
+// ViewerPreferences are a user's effective preferences: their stored values merged over
+// the server defaults. Matches social.coves.actor.getPreferences output.
+type ViewerPreferences struct {
+	DefaultFeedSort     string   `json:"defaultFeedSort"`
+	Languages           []string `json:"languages"`         // BCP-47 tags the user reads; empty means all
+	HiddenCommunities   []string `json:"hiddenCommunities"` // Community DIDs left out of the timeline and discover feeds
+	HideSeen            bool     `json:"hideSeen"`
+	IncludeNSFW         bool     `json:"includeNsfw"`
+	HideAggregatorPosts bool     `json:"hideAggregatorPosts"`
+}
+
+// DefaultViewerPreferences returns the preferences of a user who never set any
+func DefaultViewerPreferences() *ViewerPreferences {
+	return &ViewerPreferences{
+		DefaultFeedSort:   DefaultFeedSort,
+		Languages:         []string{},
+		HiddenCommunities: []string{},
+	}
+}
+
+// MergePreferences layers a stored preferences record over the server defaults
+// A nil record yields the defaults. Stored values that are missing, mistyped or no longer
+// valid keep the default for that key, so one bad value doesn't reset the rest.
+func MergePreferences(record json.RawMessage) *ViewerPreferences {
+	merged := DefaultViewerPreferences()
+	if len(record) == 0 {
+		return merged
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(record, &fields); err != nil {
+		return merged
+	}
+
+	var sort string
+	if json.Unmarshal(fields["defaultFeedSort"], &sort) == nil && validFeedSorts[sort] {
+		merged.DefaultFeedSort = sort
+	}
+	var langs []string
+	if json.Unmarshal(fields["languages"], &langs) == nil && langs != nil && validateLanguages(langs) == nil {
+		merged.Languages = langs
+	}
+	var hidden []string
+	if json.Unmarshal(fields["hiddenCommunities"], &hidden) == nil && hidden != nil && validateHiddenCommunities(hidden) == nil {
+		merged.HiddenCommunities = hidden
+	}
+	unmarshalBool(fields["hideSeen"], &merged.HideSeen)
+	unmarshalBool(fields["includeNsfw"], &merged.IncludeNSFW)
+	unmarshalBool(fields["hideAggregatorPosts"], &merged.HideAggregatorPosts)
+
+	return merged
+}
+
+// unmarshalBool sets dst only when raw is a JSON boolean
+func unmarshalBool(raw json.RawMessage, dst *bool) {
+	var v *bool
+	if json.Unmarshal(raw, &v) == nil && v != nil {
+		*dst = *v
+	}
+}
+
+// PreferencesUpdate is a putPreferences request
+// Absent fields keep their stored value.
+type PreferencesUpdate struct {
+	DefaultFeedSort     *string  `json:"defaultFeedSort,omitempty"`
+	HideSeen            *bool    `json:"hideSeen,omitempty"`
+	IncludeNSFW         *bool    `json:"includeNsfw,omitempty"`
+	HideAggregatorPosts *bool    `json:"hideAggregatorPosts,omitempty"`
+	Languages           []string `json:"languages,omitempty"`
+	HiddenCommunities   []string `json:"hiddenCommunities,omitempty"`
+}
+
+// Validate checks every field the update sets
+func (u *PreferencesUpdate) Validate() error {
+	if u.DefaultFeedSort != nil && !validFeedSorts[*u.DefaultFeedSort] {
+		return fmt.Errorf("%w: defaultFeedSort must be one of hot, top, new", ErrInvalidPreferences)
+	}
+	if err := validateLanguages(u.Languages); err != nil {
+		return err
+	}
+	return validateHiddenCommunities(u.HiddenCommunities)
+}
+
+// Apply writes the update into a preferences record, leaving unknown keys untouched
+func (u *PreferencesUpdate) Apply(record map[string]interface{}) {
+	if u.DefaultFeedSort != nil {
+		record["defaultFeedSort"] = *u.DefaultFeedSort
+	}
+	if u.HideSeen != nil {
+		record["hideSeen"] = *u.HideSeen
+	}
+	if u.IncludeNSFW != nil {
+		record["includeNsfw"] = *u.IncludeNSFW
+	}
+	if u.HideAggregatorPosts != nil {
+		record["hideAggregatorPosts"] = *u.HideAggregatorPosts
+	}
+	if u.Languages != nil {
+		record["languages"] = u.Languages
+	}
+	if u.HiddenCommunities != nil {
+		record["hiddenCommunities"] = u.HiddenCommunities
+	}
+}
+
+func validateLanguages(langs []string) error {
+	if len(langs) > MaxLanguages {
+		return fmt.Errorf("%w: at most %d languages", ErrInvalidPreferences, MaxLanguages)
+	}
+	for _, lang := range langs {
+		if _, err := language.Parse(lang); err != nil {
+			return fmt.Errorf("%w: %q is not a language tag", ErrInvalidPreferences, lang)
+		}
+	}
+	return nil
+}
+
+func validateHiddenCommunities(dids []string) error {
+	if len(dids) > MaxHiddenCommunities {
+		return fmt.Errorf("%w: at most %d hidden communities", ErrInvalidPreferences, MaxHiddenCommunities)
+	}
+	for _, did := range dids {
+		if !strings.HasPrefix(did, "did:") {
+			return fmt.Errorf("%w: hidden community %q must be a DID", ErrInvalidPreferences, did)
+		}
+	}
+	return nil
+}
+
+// PreferencesRepository persists indexed preferences records
+type PreferencesRepository interface {
+	// Upsert stores the user's preferences, replacing any previous record
+	// Returns ErrStaleRevision, without writing, when prefs.Rev is not newer than the stored rev.
+	Upsert(ctx context.Context, prefs *Preferences) error
+	// Get returns the user's preferences, or ErrPreferencesNotFound
+	Get(ctx context.Context, userDID string) (*Preferences, error)
+	// Delete removes the user's preferences; deleting missing preferences is not an error
+	Delete(ctx context.Context, userDID string) error
+}
@@ -0,0 +1,99 @@
+package users
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePreferences(t *testing.T) {
+	const uri = "at://did:plc:user/social.coves.actor.preferences/self"
+
+	t.Run("extracts known fields and keeps the full record", func(t *testing.T) {
+		prefs, err := ParsePreferences("did:plc:user", uri, "bafyprefs", map[string]interface{}{
+			"$type":               PreferencesCollection,
+			"hideAggregatorPosts": true,
+			"somethingNew":        float64(3),
+		})
+		require.NoError(t, err)
+		assert.True(t, prefs.HideAggregatorPosts)
+		assert.Equal(t, "did:plc:user", prefs.UserDID)
+		assert.Equal(t, uri, prefs.URI)
+		assert.Equal(t, "bafyprefs", prefs.CID)
+
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal(prefs.Record, &record))
+		assert.Equal(t, float64(3), record["somethingNew"])
+	})
+
+	t.Run("missing fields default to off", func(t *testing.T) {
+		prefs, err := ParsePreferences("did:plc:user", uri, "bafyprefs", map[string]interface{}{})
+		require.NoError(t, err)
+		assert.False(t, prefs.HideAggregatorPosts)
+	})
+
+	t.Run("rejects wrongly typed known fields", func(t *testing.T) {
+		_, err := ParsePreferences("did:plc:user", uri, "bafyprefs", map[string]interface{}{
+			"hideAggregatorPosts": "true",
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestMergePreferences(t *testing.T) {
+	t.Run("no record yields the server defaults", func(t *testing.T) {
+		assert.Equal(t, DefaultViewerPreferences(), MergePreferences(nil))
+	})
+
+	t.Run("stored values override defaults and missing keys keep them", func(t *testing.T) {
+		merged := MergePreferences(json.RawMessage(`{
+			"defaultFeedSort": "new",
+			"includeNsfw": true,
+			"hiddenCommunities": ["did:plc:noisy"]
+		}`))
+		assert.Equal(t, "new", merged.DefaultFeedSort)
+		assert.True(t, merged.IncludeNSFW)
+		assert.Equal(t, []string{"did:plc:noisy"}, merged.HiddenCommunities)
+		assert.False(t, merged.HideSeen)
+		assert.Equal(t, []string{}, merged.Languages)
+	})
+
+	t.Run("invalid stored values fall back per key", func(t *testing.T) {
+		merged := MergePreferences(json.RawMessage(`{
+			"defaultFeedSort": "controversial",
+			"hideSeen": "yes",
+			"languages": ["not a tag!"],
+			"hideAggregatorPosts": true
+		}`))
+		assert.Equal(t, DefaultFeedSort, merged.DefaultFeedSort)
+		assert.False(t, merged.HideSeen)
+		assert.Equal(t, []string{}, merged.Languages)
+		assert.True(t, merged.HideAggregatorPosts)
+	})
+}
+
+func TestPreferencesUpdate(t *testing.T) {
+	sort := "top"
+	on := true
+	update := &PreferencesUpdate{DefaultFeedSort: &sort, HideSeen: &on, Languages: []string{"en", "pt-BR"}}
+	require.NoError(t, update.Validate())
+
+	record := map[string]interface{}{"somethingNew": "kept", "includeNsfw": true}
+	update.Apply(record)
+	assert.Equal(t, "top", record["defaultFeedSort"])
+	assert.Equal(t, true, record["hideSeen"])
+	assert.Equal(t, true, record["includeNsfw"], "fields absent from the update keep their stored value")
+	assert.Equal(t, "kept", record["somethingNew"])
+
+	bad := "best"
+	invalid := []*PreferencesUpdate{
+		{DefaultFeedSort: &bad},
+		{Languages: []string{"english please"}},
+		{HiddenCommunities: []string{"c-news.coves.social"}},
+	}
+	for _, u := range invalid {
+		assert.ErrorIs(t, u.Validate(), ErrInvalidPreferences)
+	}
+}
@@ -72,16 +72,17 @@ func (s *userService) CreateUser(ctx context.Context, req CreateUserRequest) (*U
 	req.PDSURL = strings.TrimSpace(req.PDSURL)
 
 	user := &User{
-		DID:    req.DID,
-		Handle: req.Handle,
-		PDSURL: req.PDSURL,
+		DID:            req.DID,
+		Handle:         req.Handle,
+		PDSURL:         req.PDSURL,
+		ProfilePending: req.ProfilePending,
 	}
 
 	// Try to create the user
 	createdUser, err := s.userRepo.Create(ctx, user)
 	if err != nil {
 		// If user with this DID already exists, fetch and return it (idempotent behavior)
-		if strings.Contains(err.Error(), "user with DID already exists") {
+		if errors.Is(err, ErrUserAlreadyExists) {
 			existingUser, getErr := s.userRepo.GetByDID(ctx, req.DID)
 			if getErr != nil {
 				return nil, fmt.Errorf("user exists but failed to fetch: %w", getErr)
@@ -297,9 +298,9 @@ func (s *userService) GetProfile(ctx context.Context, did string) (*ProfileViewD
 	}
 
 	// Transform avatar/banner CIDs to URLs using image proxy config
-	// Uses 'avatar' preset (160x160) for profile detail view
+	// Uses 'avatar' preset (160x160) for profile detail view; no avatar falls back to an identicon
 	config := communities.GetImageProxyConfig()
-	profile.Avatar = blobs.HydrateImageURL(config, user.PDSURL, user.DID, user.AvatarCID, "avatar")
+	profile.Avatar = blobs.HydrateAvatarURL(config, user.PDSURL, user.DID, user.AvatarCID, "avatar")
 	profile.Banner = blobs.HydrateImageURL(config, user.PDSURL, user.DID, user.BannerCID, "banner")
 
 	return profile, nil
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"Coves/internal/atproto/identity"
+	"Coves/internal/core/blobs"
+	"Coves/internal/core/communities"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -79,6 +81,19 @@ func (m *MockUserRepository) UpdateProfile(ctx context.Context, did string, inpu
 	return args.Get(0).(*User), args.Error(1)
 }
 
+func (m *MockUserRepository) ListPendingProfiles(ctx context.Context, limit int) ([]*User, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*User), args.Error(1)
+}
+
+func (m *MockUserRepository) MarkProfileFetched(ctx context.Context, did string) error {
+	args := m.Called(ctx, did)
+	return args.Error(0)
+}
+
 // MockIdentityResolver is a mock implementation of identity.Resolver
 type MockIdentityResolver struct {
 	mock.Mock
@@ -595,6 +610,37 @@ func TestGetProfile_WithNoCIDsOrProfile(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// TestGetProfile_NoAvatarFallsBackToIdenticon tests that a user without an avatar blob gets
+// their identicon when the image proxy is enabled
+func TestGetProfile_NoAvatarFallsBackToIdenticon(t *testing.T) {
+	communities.ResetImageProxyConfigForTesting()
+	communities.SetImageProxyConfig(blobs.ImageURLConfig{ProxyEnabled: true, ProxyBaseURL: "https://coves.social"})
+	t.Cleanup(communities.ResetImageProxyConfigForTesting)
+
+	mockRepo := new(MockUserRepository)
+	mockResolver := new(MockIdentityResolver)
+
+	testDID := "did:plc:noavatar"
+	testUser := &User{
+		DID:       testDID,
+		Handle:    "noavatar.test",
+		PDSURL:    "https://test.pds",
+		CreatedAt: time.Now(),
+	}
+
+	mockRepo.On("GetByDID", mock.Anything, testDID).Return(testUser, nil)
+	mockRepo.On("GetProfileStats", mock.Anything, testDID).Return(&ProfileStats{}, nil)
+
+	service := NewUserService(mockRepo, mockResolver, "https://default.pds")
+	profile, err := service.GetProfile(context.Background(), testDID)
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://coves.social/img/identicon/did:plc:noavatar", profile.Avatar)
+	assert.Empty(t, profile.Banner, "banners have no fallback")
+
+	mockRepo.AssertExpectations(t)
+}
+
 // TestGetProfile_WithEmptyPDSURL tests GetProfile does not create URLs when PDSURL is empty
 func TestGetProfile_WithEmptyPDSURL(t *testing.T) {
 	mockRepo := new(MockUserRepository)
@@ -17,6 +17,12 @@ type User struct {
 	Bio         string    `json:"bio,omitempty" db:"bio"`
 	AvatarCID   string    `json:"avatarCid,omitempty" db:"avatar_cid"`
 	BannerCID   string    `json:"bannerCid,omitempty" db:"banner_cid"`
+	// ConfusableWith is the DID of the user whose handle this user's handle imitates (a
+	// homoglyph lookalike), or "" when it doesn't. Hydrated author views carry it as a flag.
+	ConfusableWith string `json:"-" db:"confusable_with"`
+	// ProfilePending is set for users indexed on demand whose profile hasn't been fetched yet
+	// (profile_fetched = false); the profile backfill fills in display name, avatar etc.
+	ProfilePending bool `json:"-" db:"-"`
 }
 
 // CreateUserRequest represents the input for creating a new user
@@ -24,6 +30,9 @@ type CreateUserRequest struct {
 	DID    string `json:"did"`
 	Handle string `json:"handle"`
 	PDSURL string `json:"pdsUrl"` // User's PDS host URL
+	// ProfilePending creates the user with profile_fetched = false, for users indexed from
+	// their posts or comments before any profile event was seen
+	ProfilePending bool `json:"-"`
 }
 
 // RegisterAccountRequest represents the input for registering a new account on the PDS
@@ -0,0 +1,112 @@
+package votes
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Votes are public records on their voters' PDSes, but the AppView only exposes counts: who
+// voted on a subject is limited to the people who need it to review brigading. The repository
+// lists voters only for a VoterAccess, and only AccessPolicy hands those out, so a new endpoint
+// can't enumerate voters without going through the policy.
+
+// VoterRole is why the access policy let a caller see who voted on a subject
+type VoterRole string
+
+const (
+	VoterRoleAuthor    VoterRole = "author"    // The subject's author, for their own content
+	VoterRoleModerator VoterRole = "moderator" // A moderator of the subject's community
+	VoterRoleAdmin     VoterRole = "admin"     // An instance admin
+)
+
+// Voter is one vote on a subject, as shown to the access policy's allowed callers
+type Voter struct {
+	CreatedAt time.Time `json:"createdAt"`
+	DID       string    `json:"did"`
+	Direction string    `json:"direction"`
+}
+
+// SubjectOwner is who wrote a vote subject and the community it belongs to
+type SubjectOwner struct {
+	AuthorDID    string
+	CommunityDID string
+}
+
+// SubjectOwnerLookup finds the author and community of an indexed post or comment
+// Implemented by the vote repository; returns ErrSubjectNotFound for unknown subjects.
+type SubjectOwnerLookup interface {
+	GetSubjectOwner(ctx context.Context, subjectURI string) (*SubjectOwner, error)
+}
+
+// ModeratorChecker reports whether someone moderates a community
+type ModeratorChecker interface {
+	IsCommunityModerator(ctx context.Context, communityDID, actorDID string) (bool, error)
+}
+
+// VoterAccess is the access policy's permission for one caller to list who voted on one subject
+// The zero value grants nothing; its fields are unexported so only AccessPolicy can fill them.
+type VoterAccess struct {
+	subjectURI string
+	callerDID  string
+	role       VoterRole
+}
+
+// SubjectURI is the subject whose voters may be listed, or "" for the zero VoterAccess
+func (a VoterAccess) SubjectURI() string { return a.subjectURI }
+
+// CallerDID is who the access was granted to
+func (a VoterAccess) CallerDID() string { return a.callerDID }
+
+// Role is why access was granted
+func (a VoterAccess) Role() VoterRole { return a.role }
+
+// AccessPolicy decides who may see who voted on a subject: the subject's author, the
+// moderators of its community and instance admins. Everyone else gets counts only.
+type AccessPolicy struct {
+	subjects   SubjectOwnerLookup
+	moderators ModeratorChecker
+	admins     map[string]bool
+}
+
+// NewAccessPolicy creates a voter access policy
+func NewAccessPolicy(subjects SubjectOwnerLookup, moderators ModeratorChecker, adminDIDs []string) *AccessPolicy {
+	admins := make(map[string]bool, len(adminDIDs))
+	for _, did := range adminDIDs {
+		if did != "" {
+			admins[did] = true
+		}
+	}
+	return &AccessPolicy{subjects: subjects, moderators: moderators, admins: admins}
+}
+
+// AuthorizeVoters returns a VoterAccess for callerDID on subjectURI, or ErrVotersForbidden
+func (p *AccessPolicy) AuthorizeVoters(ctx context.Context, callerDID, subjectURI string) (VoterAccess, error) {
+	if callerDID == "" {
+		return VoterAccess{}, ErrVotersForbidden
+	}
+	if subjectURI == "" {
+		return VoterAccess{}, ErrInvalidSubject
+	}
+	if p.admins[callerDID] {
+		return VoterAccess{subjectURI: subjectURI, callerDID: callerDID, role: VoterRoleAdmin}, nil
+	}
+
+	owner, err := p.subjects.GetSubjectOwner(ctx, subjectURI)
+	if err != nil {
+		return VoterAccess{}, err
+	}
+	if owner.AuthorDID == callerDID {
+		return VoterAccess{subjectURI: subjectURI, callerDID: callerDID, role: VoterRoleAuthor}, nil
+	}
+	if owner.CommunityDID != "" && p.moderators != nil {
+		isModerator, err := p.moderators.IsCommunityModerator(ctx, owner.CommunityDID, callerDID)
+		if err != nil {
+			return VoterAccess{}, fmt.Errorf("failed to check moderator of %s: %w", owner.CommunityDID, err)
+		}
+		if isModerator {
+			return VoterAccess{subjectURI: subjectURI, callerDID: callerDID, role: VoterRoleModerator}, nil
+		}
+	}
+	return VoterAccess{}, ErrVotersForbidden
+}
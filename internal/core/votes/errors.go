@@ -1,23 +1,55 @@
 package votes
 
-import "errors"
+import (
+	"errors"
+
+	coreerrors "Coves/internal/core/errors"
+	"Coves/internal/lexicon/validate"
+)
 
 var (
 	// ErrVoteNotFound indicates the requested vote doesn't exist
-	ErrVoteNotFound = errors.New("vote not found")
+	ErrVoteNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "vote not found")
 
 	// ErrInvalidDirection indicates the vote direction is not "up" or "down"
-	ErrInvalidDirection = errors.New("invalid vote direction: must be 'up' or 'down'")
+	ErrInvalidDirection = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid vote direction: must be 'up' or 'down'")
 
 	// ErrInvalidSubject indicates the subject URI is malformed or invalid
-	ErrInvalidSubject = errors.New("invalid subject URI")
+	ErrInvalidSubject = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid subject URI")
+
+	// ErrInvalidRecord indicates a vote record is malformed or not in the voter's repository
+	ErrInvalidRecord = coreerrors.Sentinel(coreerrors.ErrInvalidInput, "invalid vote record")
 
 	// ErrVoteAlreadyExists indicates a vote already exists on this subject
-	ErrVoteAlreadyExists = errors.New("vote already exists")
+	ErrVoteAlreadyExists = coreerrors.Sentinel(coreerrors.ErrAlreadyExists, "vote already exists")
 
 	// ErrNotAuthorized indicates the user is not authorized to perform this action
-	ErrNotAuthorized = errors.New("not authorized")
+	ErrNotAuthorized = coreerrors.Sentinel(coreerrors.ErrForbidden, "not authorized")
 
 	// ErrBanned indicates the user is banned from the community
-	ErrBanned = errors.New("user is banned from this community")
+	ErrBanned = coreerrors.Sentinel(coreerrors.ErrForbidden, "user is banned from this community")
+
+	// ErrVotersForbidden indicates the caller may only see a subject's vote counts, not who voted
+	ErrVotersForbidden = coreerrors.Sentinel(coreerrors.ErrForbidden, "only the author, community moderators and instance admins may see who voted")
+
+	// ErrSubjectNotFound indicates the vote subject isn't an indexed post or comment
+	ErrSubjectNotFound = coreerrors.Sentinel(coreerrors.ErrNotFound, "vote subject not found")
 )
+
+// IsNotFound checks if an error is a "not found" error
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrVoteNotFound)
+}
+
+// IsConflict checks if an error is a conflict/already exists error
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrVoteAlreadyExists)
+}
+
+// IsValidationError checks if an error is a validation error
+// Vote records rejected by lexicon validation before reaching the indexer count too.
+func IsValidationError(err error) bool {
+	var recordErr *validate.Error
+	return errors.Is(err, ErrInvalidDirection) || errors.Is(err, ErrInvalidSubject) || errors.Is(err, ErrInvalidRecord) ||
+		(errors.As(err, &recordErr) && recordErr.Collection == voteCollection)
+}
@@ -0,0 +1,429 @@
+package votes
+
+import (
+	"Coves/internal/atproto/aturi"
+	"Coves/internal/core/hotrank"
+	"Coves/internal/db/txrunner"
+	"Coves/internal/lexicon/validate"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	// postCollection and commentCollection are the subject collections whose counters votes update
+	postCollection    = "social.coves.community.post"
+	commentCollection = "social.coves.community.comment"
+)
+
+// lockVoterSharedSQL takes the per-voter advisory lock in shared mode
+// Vote nullification (Repository.RemoveVotesByVoter/RestoreVotesByVoter) takes it
+// exclusively, so a vote is never indexed halfway through a nullification batch.
+// NOTE: Keep in sync with lockVoterSQL in internal/db/postgres/vote_repo_nullify.go
+const lockVoterSharedSQL = `SELECT pg_advisory_xact_lock_shared(hashtextextended('votes:' || $1, 0))`
+
+// Record is a vote record as stored in a voter's repository, with its location
+// The Jetstream consumer builds it from a commit event, the reindex tool from listRecords.
+type Record struct {
+	Value map[string]interface{}
+	URI   string
+	CID   string
+}
+
+// Indexer writes votes into the AppView database and keeps subject counters in step
+// It is the single place vote records are validated, mapped to the table of their subject,
+// and counted, so every path that indexes votes produces the same database state. Callers
+// own the transaction: each method runs its statements on the tx it is given.
+type Indexer struct{}
+
+// NewIndexer creates a vote indexer
+func NewIndexer() *Indexer {
+	return &Indexer{}
+}
+
+// IndexVote validates record and indexes it as voterDID's vote
+// Any other active vote by the voter on the same subject is stale (a missed delete) and is
+// replaced. Votes from nullified voters are stored neutralized without being counted.
+// Returns the vote and whether it was newly inserted; replays of an indexed vote return false.
+// Invalid records return an error satisfying IsValidationError.
+func (ix *Indexer) IndexVote(ctx context.Context, tx txrunner.Querier, voterDID string, record Record) (*Vote, bool, error) {
+	vote, err := buildVote(voterDID, record)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// 0. Serialize with vote nullification for this voter, then check whether
+	// the voter's votes are currently neutralized
+	if _, err := tx.ExecContext(ctx, lockVoterSharedSQL, vote.VoterDID); err != nil {
+		return nil, false, fmt.Errorf("failed to lock voter: %w", err)
+	}
+	var nullified bool
+	if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM nullified_voters WHERE voter_did = $1)`, vote.VoterDID).Scan(&nullified); err != nil {
+		return nil, false, fmt.Errorf("failed to check voter nullification: %w", err)
+	}
+
+	// 1. Replace any active vote with a different URI on the same subject. This handles cases where:
+	// - User voted on another client and we missed the delete event
+	// - Vote was reindexed but user created a new vote with different rkey
+	// - Any other state mismatch between PDS and AppView
+	if err := ix.removeStaleVotes(ctx, tx, vote); err != nil {
+		return nil, false, err
+	}
+
+	// 2. Nullified voter: store the vote already neutralized (restorable) without counting it
+	if nullified {
+		inserted, err := ix.insertNullifiedVote(ctx, tx, vote)
+		if err != nil {
+			return nil, false, err
+		}
+		return vote, inserted, nil
+	}
+
+	// 3. Index the vote (idempotent with ON CONFLICT DO NOTHING)
+	query := `
+		INSERT INTO votes (
+			uri, cid, rkey, voter_did,
+			subject_uri, subject_cid, direction,
+			created_at, indexed_at, raw_record
+		) VALUES (
+			$1, $2, $3, $4,
+			$5, $6, $7,
+			$8, NOW(), $9
+		)
+		ON CONFLICT (uri) DO NOTHING
+		RETURNING id
+	`
+	err = tx.QueryRowContext(
+		ctx, query,
+		vote.URI, vote.CID, vote.RKey, vote.VoterDID,
+		vote.SubjectURI, vote.SubjectCID, vote.Direction,
+		vote.CreatedAt, vote.RawRecord,
+	).Scan(&vote.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Already indexed (Jetstream replay or a second reindex): nothing to count
+		return vote, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to insert vote: %w", err)
+	}
+
+	// 4. Count the vote on its subject
+	if err := adjustCounts(ctx, tx, vote.SubjectURI, vote.Direction, 1); err != nil {
+		return nil, false, err
+	}
+	return vote, true, nil
+}
+
+// RemoveVote soft-deletes the active vote at uri and uncounts it from its subject
+// Returns the removed vote, or ErrVoteNotFound if there is no active vote at uri (never
+// indexed, already deleted, or neutralized by voter nullification).
+func (ix *Indexer) RemoveVote(ctx context.Context, tx txrunner.Querier, uri string) (*Vote, error) {
+	vote := &Vote{URI: uri}
+	err := tx.QueryRowContext(ctx, `
+		UPDATE votes
+		SET deleted_at = NOW()
+		WHERE uri = $1 AND deleted_at IS NULL
+		RETURNING id, voter_did, subject_uri, subject_cid, direction
+	`, uri).Scan(&vote.ID, &vote.VoterDID, &vote.SubjectURI, &vote.SubjectCID, &vote.Direction)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrVoteNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete vote: %w", err)
+	}
+
+	if err := adjustCounts(ctx, tx, vote.SubjectURI, vote.Direction, -1); err != nil {
+		return nil, err
+	}
+	return vote, nil
+}
+
+// ForgetNullifiedVote clears the nullified marker of the vote at uri, deleted by its author
+// while voterDID's votes were neutralized, so RestoreVotesByVoter doesn't resurrect it
+// A restore that committed before the voter lock was taken may already have made the vote
+// active again; callers RemoveVote in a new transaction afterwards to catch that.
+func (ix *Indexer) ForgetNullifiedVote(ctx context.Context, tx txrunner.Querier, voterDID, uri string) error {
+	if _, err := tx.ExecContext(ctx, lockVoterSharedSQL, voterDID); err != nil {
+		return fmt.Errorf("failed to lock voter: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE votes SET nullified_at = NULL WHERE uri = $1 AND nullified_at IS NOT NULL`, uri); err != nil {
+		return fmt.Errorf("failed to clear nullified vote: %w", err)
+	}
+	return nil
+}
+
+// removeStaleVotes soft-deletes the voter's other active votes on vote's subject and
+// uncounts them
+func (ix *Indexer) removeStaleVotes(ctx context.Context, tx txrunner.Querier, vote *Vote) error {
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE votes
+		SET deleted_at = NOW()
+		WHERE voter_did = $1
+		  AND subject_uri = $2
+		  AND deleted_at IS NULL
+		  AND uri != $3
+		RETURNING direction
+	`, vote.VoterDID, vote.SubjectURI, vote.URI)
+	if err != nil {
+		return fmt.Errorf("failed to soft-delete existing votes: %w", err)
+	}
+	var staleDirections []string
+	for rows.Next() {
+		var direction string
+		if err := rows.Scan(&direction); err != nil {
+			_ = rows.Close()
+			return fmt.Errorf("failed to scan stale vote: %w", err)
+		}
+		staleDirections = append(staleDirections, direction)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("failed to close stale votes: %w", err)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read stale votes: %w", err)
+	}
+
+	for _, direction := range staleDirections {
+		if err := adjustCounts(ctx, tx, vote.SubjectURI, direction, -1); err != nil {
+			return err
+		}
+		log.Printf("Cleaned up stale vote for %s on %s (was %s)", vote.VoterDID, vote.SubjectURI, direction)
+	}
+	return nil
+}
+
+// insertNullifiedVote stores a vote from a nullified voter as soft-deleted and nullified
+// Counters are left untouched; RestoreVotesByVoter counts the vote if the voter is restored.
+// Returns true if the vote was newly inserted.
+func (ix *Indexer) insertNullifiedVote(ctx context.Context, tx txrunner.Querier, vote *Vote) (bool, error) {
+	// The new vote supersedes any older neutralized vote on the same subject
+	supersedeQuery := `
+		UPDATE votes
+		SET nullified_at = NULL
+		WHERE voter_did = $1
+		  AND subject_uri = $2
+		  AND nullified_at IS NOT NULL
+		  AND uri != $3
+	`
+	if _, err := tx.ExecContext(ctx, supersedeQuery, vote.VoterDID, vote.SubjectURI, vote.URI); err != nil {
+		return false, fmt.Errorf("failed to supersede nullified votes: %w", err)
+	}
+
+	query := `
+		INSERT INTO votes (
+			uri, cid, rkey, voter_did,
+			subject_uri, subject_cid, direction,
+			created_at, indexed_at, raw_record,
+			deleted_at, nullified_at
+		) VALUES (
+			$1, $2, $3, $4,
+			$5, $6, $7,
+			$8, NOW(), $9,
+			NOW(), NOW()
+		)
+		ON CONFLICT (uri) DO NOTHING
+	`
+	result, err := tx.ExecContext(
+		ctx, query,
+		vote.URI, vote.CID, vote.RKey, vote.VoterDID,
+		vote.SubjectURI, vote.SubjectCID, vote.Direction,
+		vote.CreatedAt, vote.RawRecord,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert nullified vote: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check insert result: %w", err)
+	}
+
+	if rowsAffected > 0 {
+		log.Printf("Indexed vote from nullified voter without counting it: %s", vote.URI)
+	}
+	return rowsAffected > 0, nil
+}
+
+// violationError maps a vote record violation to the sentinel error callers match on
+func violationError(v validate.Violation) error {
+	switch {
+	case v.Field == "direction":
+		return ErrInvalidDirection
+	case strings.HasPrefix(v.Field, "subject"):
+		return ErrInvalidSubject
+	default:
+		return ErrInvalidRecord
+	}
+}
+
+// subjectTable maps a vote subject to the table holding its counters
+// Returns "" for subjects in other collections: their votes are indexed but not counted.
+func subjectTable(subjectURI string) string {
+	switch aturi.Collection(subjectURI) {
+	case postCollection:
+		return "posts"
+	case commentCollection:
+		return "comments"
+	default:
+		return ""
+	}
+}
+
+// adjustCounts adds delta to the subject's counter for direction, recomputes its score, and
+// rescores posts for hot feeds
+// Counters never go below 0. Missing or deleted subjects are left alone; the vote row is
+// the source of truth and the subject's counters are recounted if it is indexed later.
+func adjustCounts(ctx context.Context, tx txrunner.Querier, subjectURI, direction string, delta int) error {
+	table := subjectTable(subjectURI)
+	if table == "" {
+		log.Printf("Vote subject has unsupported collection: %s (counts not updated)", subjectURI)
+		return nil
+	}
+
+	// table is one of two constants above, never input
+	var query string
+	if direction == "up" {
+		query = fmt.Sprintf(`
+			UPDATE %s
+			SET upvote_count = GREATEST(0, upvote_count + $2),
+			    score = GREATEST(0, upvote_count + $2) - downvote_count
+			WHERE uri = $1 AND deleted_at IS NULL
+		`, table)
+	} else {
+		query = fmt.Sprintf(`
+			UPDATE %s
+			SET downvote_count = GREATEST(0, downvote_count + $2),
+			    score = upvote_count - GREATEST(0, downvote_count + $2)
+			WHERE uri = $1 AND deleted_at IS NULL
+		`, table)
+	}
+
+	result, err := tx.ExecContext(ctx, query, subjectURI, delta)
+	if err != nil {
+		return fmt.Errorf("failed to update vote counts: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check update result: %w", err)
+	}
+	if rowsAffected == 0 {
+		log.Printf("Warning: Vote subject not found or deleted: %s (vote indexed anyway)", subjectURI)
+		return nil
+	}
+
+	if table == "posts" {
+		return rescorePost(ctx, tx, subjectURI)
+	}
+	return nil
+}
+
+// rescorePost updates a post's hot score after its vote counts changed
+// Between ranker runs this keeps hot feeds responsive to votes without waiting for the
+// next full rescore. The score is aged against the ranker's current epoch, so it stays
+// comparable with every other post's; if the ranker publishes a new epoch in between, its
+// score for the post stands.
+func rescorePost(ctx context.Context, tx txrunner.Querier, postURI string) error {
+	var score int
+	var createdAt time.Time
+	var epoch sql.NullTime
+	err := tx.QueryRowContext(ctx,
+		`SELECT score, created_at, (SELECT scored_at FROM hot_rank_epoch)
+		FROM posts WHERE uri = $1 AND deleted_at IS NULL`, postURI,
+	).Scan(&score, &createdAt, &epoch)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read post score: %w", err)
+	}
+	at := time.Now()
+	if epoch.Valid {
+		at = epoch.Time
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE posts SET hot_score = $2
+		WHERE uri = $1 AND (SELECT scored_at FROM hot_rank_epoch) IS NOT DISTINCT FROM $3`,
+		postURI, hotrank.Score(score, createdAt, at), epoch,
+	); err != nil {
+		return fmt.Errorf("failed to update post hot score: %w", err)
+	}
+	return nil
+}
+
+// buildVote validates a vote record and converts it to the vote voterDID cast
+func buildVote(voterDID string, record Record) (*Vote, error) {
+	// Votes live in the voter's own repository, so the record's AT-URI must be there too.
+	// We do NOT require the voter to be indexed: vote events may arrive before the voter's
+	// identity event, and the PDS already authenticated the write.
+	if !strings.HasPrefix(voterDID, "did:") {
+		return nil, fmt.Errorf("%w: invalid voter DID format: %s", ErrInvalidRecord, voterDID)
+	}
+	location, err := aturi.Parse(record.URI)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+	}
+	if location.Authority != voterDID || location.Collection != voteCollection || location.RKey == "" {
+		return nil, fmt.Errorf("%w: %s is not a vote in %s's repository", ErrInvalidRecord, record.URI, voterDID)
+	}
+
+	if violations := validate.ValidateVote(record.Value); len(violations) > 0 {
+		return nil, fmt.Errorf("%w: %w", violationError(violations[0]), validate.Check(voteCollection, violations))
+	}
+	value, err := ParseRecord(record.Value)
+	if err != nil {
+		return nil, err
+	}
+	subject, err := aturi.Parse(value.Subject.URI)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSubject, err)
+	}
+
+	createdAt, err := time.Parse(time.RFC3339, value.CreatedAt)
+	if err != nil {
+		log.Printf("Warning: Failed to parse vote createdAt timestamp, using current time: %v", err)
+		createdAt = time.Now()
+	}
+
+	var rawRecord *string
+	if raw, err := json.Marshal(record.Value); err != nil {
+		log.Printf("Warning: Failed to marshal raw vote record (raw_record will be NULL): %v", err)
+	} else {
+		rawStr := string(raw)
+		rawRecord = &rawStr
+	}
+
+	return &Vote{
+		URI:        location.String(),
+		CID:        record.CID,
+		RKey:       location.RKey,
+		VoterDID:   voterDID,
+		SubjectURI: subject.String(),
+		SubjectCID: value.Subject.CID,
+		Direction:  value.Direction,
+		CreatedAt:  createdAt,
+		IndexedAt:  time.Now(),
+		RawRecord:  rawRecord,
+	}, nil
+}
+
+// ParseRecord extracts a vote record from its raw repository value
+// Unknown fields are ignored so records written by newer lexicon versions still index.
+func ParseRecord(value map[string]interface{}) (*VoteRecord, error) {
+	subject, ok := value["subject"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: missing or invalid subject field", ErrInvalidSubject)
+	}
+
+	record := &VoteRecord{}
+	record.Type, _ = value["$type"].(string)
+	record.Subject.URI, _ = subject["uri"].(string)
+	record.Subject.CID, _ = subject["cid"].(string)
+	record.Direction, _ = value["direction"].(string)
+	record.CreatedAt, _ = value["createdAt"].(string)
+	return record, nil
+}
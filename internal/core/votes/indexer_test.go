@@ -0,0 +1,146 @@
+package votes
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func validVoteRecord() Record {
+	return Record{
+		URI: "at://did:plc:voter/social.coves.feed.vote/3kvote",
+		CID: "bafyvote",
+		Value: map[string]interface{}{
+			"$type":     "social.coves.feed.vote",
+			"subject":   map[string]interface{}{"uri": "at://did:plc:community/social.coves.community.post/3kpost", "cid": "bafypost"},
+			"direction": "up",
+			"createdAt": "2025-01-01T00:00:00Z",
+		},
+	}
+}
+
+func TestBuildVote(t *testing.T) {
+	vote, err := buildVote("did:plc:voter", validVoteRecord())
+	if err != nil {
+		t.Fatalf("Expected valid record to build, got: %v", err)
+	}
+
+	if vote.URI != "at://did:plc:voter/social.coves.feed.vote/3kvote" || vote.RKey != "3kvote" || vote.CID != "bafyvote" {
+		t.Errorf("Unexpected vote location: %s %s %s", vote.URI, vote.RKey, vote.CID)
+	}
+	if vote.SubjectURI != "at://did:plc:community/social.coves.community.post/3kpost" || vote.SubjectCID != "bafypost" {
+		t.Errorf("Unexpected subject: %s %s", vote.SubjectURI, vote.SubjectCID)
+	}
+	if vote.VoterDID != "did:plc:voter" || vote.Direction != "up" {
+		t.Errorf("Unexpected voter or direction: %s %s", vote.VoterDID, vote.Direction)
+	}
+	if !vote.CreatedAt.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected createdAt from the record, got %v", vote.CreatedAt)
+	}
+	if vote.RawRecord == nil {
+		t.Error("Expected the raw record to be kept")
+	}
+}
+
+func TestBuildVote_CanonicalizesSubject(t *testing.T) {
+	record := validVoteRecord()
+	record.Value["subject"] = map[string]interface{}{"uri": "AT://did:plc:community/social.coves.community.post/3kpost/", "cid": "bafypost"}
+
+	vote, err := buildVote("did:plc:voter", record)
+	if err != nil {
+		t.Fatalf("Expected record to build, got: %v", err)
+	}
+	if vote.SubjectURI != "at://did:plc:community/social.coves.community.post/3kpost" {
+		t.Errorf("Expected canonical subject URI, got %s", vote.SubjectURI)
+	}
+}
+
+func TestBuildVote_Invalid(t *testing.T) {
+	tests := []struct {
+		mutate   func(r *Record)
+		want     error
+		name     string
+		voterDID string
+	}{
+		{
+			name:     "voter is not a DID",
+			voterDID: "alice.coves.social",
+			mutate:   func(r *Record) {},
+			want:     ErrInvalidRecord,
+		},
+		{
+			name:   "vote in another repository",
+			mutate: func(r *Record) { r.URI = "at://did:plc:other/social.coves.feed.vote/3kvote" },
+			want:   ErrInvalidRecord,
+		},
+		{
+			name:   "record in another collection",
+			mutate: func(r *Record) { r.URI = "at://did:plc:voter/social.coves.community.post/3kvote" },
+			want:   ErrInvalidRecord,
+		},
+		{
+			name:   "missing rkey",
+			mutate: func(r *Record) { r.URI = "at://did:plc:voter/social.coves.feed.vote" },
+			want:   ErrInvalidRecord,
+		},
+		{
+			name:   "invalid direction",
+			mutate: func(r *Record) { r.Value["direction"] = "sideways" },
+			want:   ErrInvalidDirection,
+		},
+		{
+			name:   "missing subject",
+			mutate: func(r *Record) { delete(r.Value, "subject") },
+			want:   ErrInvalidSubject,
+		},
+		{
+			name: "subject without CID",
+			mutate: func(r *Record) {
+				r.Value["subject"] = map[string]interface{}{"uri": "at://did:plc:community/social.coves.community.post/3kpost"}
+			},
+			want: ErrInvalidSubject,
+		},
+		{
+			name: "subject is not an AT-URI",
+			mutate: func(r *Record) {
+				r.Value["subject"] = map[string]interface{}{"uri": "https://example.com/post", "cid": "bafypost"}
+			},
+			want: ErrInvalidSubject,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := validVoteRecord()
+			tt.mutate(&record)
+			voterDID := tt.voterDID
+			if voterDID == "" {
+				voterDID = "did:plc:voter"
+			}
+
+			_, err := buildVote(voterDID, record)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got: %v", tt.want, err)
+			}
+			if !IsValidationError(err) {
+				t.Errorf("Expected a validation error, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestSubjectTable(t *testing.T) {
+	tests := map[string]string{
+		"at://did:plc:c/social.coves.community.post/3k":    "posts",
+		"at://did:plc:c/social.coves.community.comment/3k": "comments",
+		"did:plc:c/social.coves.community.post/3k":         "posts",
+		"at://did:plc:c/app.bsky.feed.post/3k":             "",
+		"not a uri":                                        "",
+	}
+
+	for uri, want := range tests {
+		if got := subjectTable(uri); got != want {
+			t.Errorf("subjectTable(%q): expected %q, got %q", uri, want, got)
+		}
+	}
+}
@@ -26,11 +26,28 @@ type Repository interface {
 	// Called by Jetstream consumer after vote is deleted from PDS
 	Delete(ctx context.Context, uri string) error
 
-	// ListBySubject retrieves all votes on a specific post/comment
-	// Future: Used for vote detail views
-	ListBySubject(ctx context.Context, subjectURI string, limit, offset int) ([]*Vote, error)
+	// ListVotersForSubject lists who voted on access.SubjectURI(), newest first
+	// Only AccessPolicy grants access; the zero VoterAccess returns ErrVotersForbidden. There is
+	// deliberately no unguarded way to list a subject's voters.
+	ListVotersForSubject(ctx context.Context, access VoterAccess, limit, offset int) ([]*Voter, error)
+
+	// GetSubjectOwner finds the author and community of a post or comment (see AccessPolicy)
+	GetSubjectOwner(ctx context.Context, subjectURI string) (*SubjectOwner, error)
 
 	// ListByVoter retrieves all votes by a specific user
 	// Future: Used for user voting history
 	ListByVoter(ctx context.Context, voterDID string, limit, offset int) ([]*Vote, error)
+
+	// RemoveVotesByVoter neutralizes every active vote cast by voterDID
+	// Votes are soft-deleted and marked as nullified, and the upvote/downvote/score
+	// counters of the affected posts and comments are corrected, in batched transactions.
+	// The voter stays nullified until RestoreVotesByVoter, so votes indexed in the
+	// meantime don't count either. Safe to re-run; returns the number of votes neutralized.
+	RemoveVotesByVoter(ctx context.Context, voterDID, reason string) (int, error)
+
+	// RestoreVotesByVoter reverses RemoveVotesByVoter, re-applying counters
+	// If reasons are given, the voter is only restored when it was nullified for
+	// one of them (e.g. reactivation must not undo an admin nullification).
+	// Returns the number of votes restored.
+	RestoreVotesByVoter(ctx context.Context, voterDID string, reasons ...string) (int, error)
 }
@@ -166,23 +166,35 @@ func (s *voteService) CreateVote(ctx context.Context, session *oauth.ClientSessi
 			}, nil
 		}
 
-		// Different direction - delete old vote first, then create new one
-		if err := pdsClient.DeleteRecord(ctx, voteCollection, existing.RKey); err != nil {
-			s.logger.Error("failed to delete existing vote on PDS",
+		// Different direction - replace the old vote with the new one in a single commit,
+		// so a failure can't leave the user with no vote or with both
+		uri, cid, err := s.replaceVoteRecord(ctx, pdsClient, existing, req)
+		if err != nil {
+			s.logger.Error("failed to replace vote on PDS",
 				"error", err,
 				"voter", session.AccountDID,
-				"rkey", existing.RKey)
+				"rkey", existing.RKey,
+				"subject", req.Subject.URI)
 			if pds.IsAuthError(err) {
 				return nil, ErrNotAuthorized
 			}
-			return nil, fmt.Errorf("failed to delete existing vote: %w", err)
+			return nil, fmt.Errorf("failed to replace vote: %w", err)
 		}
 
-		s.logger.Info("deleted existing vote before creating new direction",
+		s.logger.Info("vote direction changed",
 			"voter", session.AccountDID,
 			"subject", req.Subject.URI,
 			"old_direction", existing.Direction,
-			"new_direction", req.Direction)
+			"new_direction", req.Direction,
+			"uri", uri,
+			"cid", cid)
+
+		s.cacheVote(session.AccountDID.String(), req, uri)
+
+		return &CreateVoteResponse{
+			URI: uri,
+			CID: cid,
+		}, nil
 	}
 
 	// Create new vote
@@ -206,14 +218,7 @@ func (s *voteService) CreateVote(ctx context.Context, session *oauth.ClientSessi
 		"uri", uri,
 		"cid", cid)
 
-	// Update cache - add the new vote
-	if s.cache != nil {
-		s.cache.SetVote(session.AccountDID.String(), req.Subject.URI, &CachedVote{
-			Direction: req.Direction,
-			URI:       uri,
-			RKey:      extractRKeyFromURI(uri),
-		})
-	}
+	s.cacheVote(session.AccountDID.String(), req, uri)
 
 	return &CreateVoteResponse{
 		URI: uri,
@@ -284,8 +289,38 @@ func (s *voteService) createVoteRecord(ctx context.Context, pdsClient pds.Client
 	// Generate TID for the record key
 	tid := syntax.NewTIDNow(0)
 
-	// Build vote record following the lexicon schema
-	record := VoteRecord{
+	uri, cid, err := pdsClient.CreateRecord(ctx, voteCollection, tid.String(), newVoteRecord(req))
+	if err != nil {
+		return "", "", fmt.Errorf("createRecord failed: %w", err)
+	}
+
+	return uri, cid, nil
+}
+
+// replaceVoteRecord deletes an existing vote and writes the new one in one applyWrites commit
+func (s *voteService) replaceVoteRecord(ctx context.Context, pdsClient pds.Client, existing *existingVote, req CreateVoteRequest) (string, string, error) {
+	tid := syntax.NewTIDNow(0)
+
+	results, err := pdsClient.ApplyWrites(ctx, pdsClient.DID(), []pds.WriteOp{
+		{Action: pds.WriteActionDelete, Collection: voteCollection, RKey: existing.RKey},
+		{Action: pds.WriteActionCreate, Collection: voteCollection, RKey: tid.String(), Value: newVoteRecord(req)},
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("applyWrites failed: %w", err)
+	}
+
+	created := results[1]
+	if created.URI == "" {
+		// The PDS didn't report per-write results; the URI follows from the rkey we chose
+		created.URI = fmt.Sprintf("at://%s/%s/%s", pdsClient.DID(), voteCollection, tid.String())
+	}
+
+	return created.URI, created.CID, nil
+}
+
+// newVoteRecord builds a vote record following the lexicon schema
+func newVoteRecord(req CreateVoteRequest) VoteRecord {
+	return VoteRecord{
 		Type: voteCollection,
 		Subject: StrongRef{
 			URI: req.Subject.URI,
@@ -294,13 +329,17 @@ func (s *voteService) createVoteRecord(ctx context.Context, pdsClient pds.Client
 		Direction: req.Direction,
 		CreatedAt: time.Now().UTC().Format(time.RFC3339),
 	}
+}
 
-	uri, cid, err := pdsClient.CreateRecord(ctx, voteCollection, tid.String(), record)
-	if err != nil {
-		return "", "", fmt.Errorf("createRecord failed: %w", err)
+// cacheVote records the user's new vote in the vote cache
+func (s *voteService) cacheVote(userDID string, req CreateVoteRequest, uri string) {
+	if s.cache != nil {
+		s.cache.SetVote(userDID, req.Subject.URI, &CachedVote{
+			Direction: req.Direction,
+			URI:       uri,
+			RKey:      extractRKeyFromURI(uri),
+		})
 	}
-
-	return uri, cid, nil
 }
 
 // existingVote represents a vote record found on the PDS
@@ -10,6 +10,7 @@ type Vote struct {
 	CreatedAt  time.Time  `json:"createdAt" db:"created_at"`
 	IndexedAt  time.Time  `json:"indexedAt" db:"indexed_at"`
 	DeletedAt  *time.Time `json:"deletedAt,omitempty" db:"deleted_at"`
+	RawRecord  *string    `json:"-" db:"raw_record"` // Full record JSON as received from the firehose
 	URI        string     `json:"uri" db:"uri"`
 	CID        string     `json:"cid" db:"cid"`
 	RKey       string     `json:"rkey" db:"rkey"`
@@ -35,3 +36,10 @@ type StrongRef struct {
 	URI string `json:"uri"`
 	CID string `json:"cid"`
 }
+
+// Reasons a voter's votes can be nullified (stored in nullified_voters.reason)
+const (
+	NullifiedTakendown = "takendown" // Account taken down by its PDS/relay
+	NullifiedSuspended = "suspended" // Account suspended by its PDS/relay
+	NullifiedByAdmin   = "admin"     // Instance admin via social.coves.admin.nullifyVotes
+)
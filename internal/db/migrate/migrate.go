@@ -0,0 +1,160 @@
+// Package migrate applies the schema migrations in internal/db/migrations under a policy.
+//
+// A migration whose Up section carries the annotation
+//
+//	-- +coves:destructive
+//
+// drops or rewrites data or holds long locks: DROP, column type changes, SET NOT NULL,
+// backfills that UPDATE or DELETE existing rows, and constraints added to existing tables, which
+// check every row while writes are blocked. Creating an index or a table doesn't qualify.
+// Migrations before 028 ran on every deployment before the policy existed and aren't annotated.
+// Destructive migrations only run when explicitly allowed: the server allows them in
+// development, while in production they are left for an operator to run deliberately with
+// cmd/migrate. Migrations apply in order, so everything after a held destructive migration
+// waits with it. The binary's code may depend on any of them, so the server refuses to start
+// until they're applied.
+package migrate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pressly/goose/v3"
+)
+
+// DestructiveAnnotation marks a migration that must not run automatically in production
+const DestructiveAnnotation = "-- +coves:destructive"
+
+// gooseDownAnnotation starts the Down section of a goose SQL migration
+const gooseDownAnnotation = "-- +goose Down"
+
+var (
+	// ErrSchemaMismatch is returned when the database schema isn't the version the binary expects
+	ErrSchemaMismatch = errors.New("database schema version mismatch")
+
+	// ErrDestructivePending is returned, wrapped in ErrSchemaMismatch, when the database is
+	// behind only because a destructive migration is waiting for an operator
+	ErrDestructivePending = errors.New("destructive migration pending")
+)
+
+// Migration is a migration file and its classification
+type Migration struct {
+	Name        string `json:"name"`
+	Version     int64  `json:"version"`
+	Destructive bool   `json:"destructive"`
+}
+
+// Status compares the database schema with the migrations shipped with the binary
+type Status struct {
+	// Blocked is the first pending migration that needs --allow-destructive, if any
+	Blocked *Migration `json:"blocked,omitempty"`
+	// Pending are the migrations not yet applied, oldest first
+	Pending []Migration `json:"pending"`
+	// Current is the version the database is at
+	Current int64 `json:"current"`
+	// Expected is the version of the newest migration the binary ships
+	Expected int64 `json:"expected"`
+}
+
+// Collect reads and classifies the SQL migrations in dir, oldest first
+func Collect(dir string) ([]Migration, error) {
+	collected, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect migrations from %s: %w", dir, err)
+	}
+
+	migrations := make([]Migration, 0, len(collected))
+	for _, m := range collected {
+		destructive, err := isDestructive(m.Source)
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, Migration{
+			Name:        filepath.Base(m.Source),
+			Version:     m.Version,
+			Destructive: destructive,
+		})
+	}
+	return migrations, nil
+}
+
+// isDestructive reports whether a migration's Up section carries the destructive annotation
+func isDestructive(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read migration %s: %w", path, err)
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, gooseDownAnnotation) {
+			return false, nil
+		}
+		if line == DestructiveAnnotation {
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read migration %s: %w", path, err)
+	}
+	return false, nil
+}
+
+// NewStatus works out which migrations are pending for a database at version current
+func NewStatus(migrations []Migration, current int64) *Status {
+	status := &Status{Current: current, Pending: []Migration{}}
+	for i, m := range migrations {
+		if m.Version > status.Expected {
+			status.Expected = m.Version
+		}
+		if m.Version <= current {
+			continue
+		}
+		status.Pending = append(status.Pending, m)
+		if m.Destructive && status.Blocked == nil {
+			status.Blocked = &migrations[i]
+		}
+	}
+	return status
+}
+
+// Target returns the version to migrate up to: everything pending, or everything before the
+// first destructive migration unless allowDestructive is set
+func (s *Status) Target(allowDestructive bool) int64 {
+	if allowDestructive || s.Blocked == nil {
+		return s.Expected
+	}
+	target := s.Current
+	for _, m := range s.Pending {
+		if m.Version >= s.Blocked.Version {
+			break
+		}
+		target = m.Version
+	}
+	return target
+}
+
+// CheckVersion fails when the database isn't at the version the binary expects, including
+// when it's held back by a destructive migration: the binary's queries may need that migration
+// or the ones after it, so it isn't safe to serve until an operator applies them.
+func (s *Status) CheckVersion() error {
+	if s.Current > s.Expected {
+		return fmt.Errorf("%w: database is at %d, newer than this binary's %d", ErrSchemaMismatch, s.Current, s.Expected)
+	}
+	if len(s.Pending) == 0 {
+		return nil
+	}
+	if s.Blocked != nil && s.Pending[0].Version == s.Blocked.Version {
+		return fmt.Errorf("%w: %w: %s must be applied with cmd/migrate up --allow-destructive (%d migrations behind this binary's %d)",
+			ErrSchemaMismatch, ErrDestructivePending, s.Blocked.Name, len(s.Pending), s.Expected)
+	}
+	return fmt.Errorf("%w: database is at %d, %d migrations behind this binary's %d",
+		ErrSchemaMismatch, s.Current, len(s.Pending), s.Expected)
+}
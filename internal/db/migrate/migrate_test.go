@@ -0,0 +1,193 @@
+package migrate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func writeMigration(t *testing.T, dir, name, body string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCollect_ClassifiesMigrations(t *testing.T) {
+	dir := t.TempDir()
+	writeMigration(t, dir, "001_create_widgets.sql", `-- +goose Up
+CREATE TABLE widgets (id BIGSERIAL PRIMARY KEY, name TEXT);
+
+-- +goose Down
+-- +coves:destructive
+DROP TABLE widgets;
+`)
+	writeMigration(t, dir, "002_drop_widget_name.sql", `-- +goose Up
+-- +coves:destructive
+ALTER TABLE widgets DROP COLUMN name;
+
+-- +goose Down
+ALTER TABLE widgets ADD COLUMN name TEXT;
+`)
+	writeMigration(t, dir, "003_mention_annotation.sql", `-- +goose Up
+-- Not destructive, despite mentioning -- +coves:destructive in a comment
+CREATE INDEX idx_widgets_id ON widgets (id);
+
+-- +goose Down
+DROP INDEX idx_widgets_id;
+`)
+
+	migrations, err := Collect(dir)
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	want := []Migration{
+		{Name: "001_create_widgets.sql", Version: 1},
+		{Name: "002_drop_widget_name.sql", Version: 2, Destructive: true},
+		{Name: "003_mention_annotation.sql", Version: 3},
+	}
+	if len(migrations) != len(want) {
+		t.Fatalf("Expected %d migrations, got %d", len(want), len(migrations))
+	}
+	for i := range want {
+		if migrations[i] != want[i] {
+			t.Errorf("Expected %+v, got %+v", want[i], migrations[i])
+		}
+	}
+}
+
+func TestCollect_RepoMigrations(t *testing.T) {
+	migrations, err := Collect("../migrations")
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("Expected the repo's migrations to be collected")
+	}
+}
+
+// rewritingStatements match Up statements that rewrite or check every existing row of a table
+var rewritingStatements = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\bUPDATE\s+\w+(\s+\w+)?\s+SET\b`),
+	regexp.MustCompile(`(?i)\bDELETE\s+FROM\b`),
+	regexp.MustCompile(`(?i)\bTRUNCATE\b`),
+	regexp.MustCompile(`(?i)\bDROP\s+(COLUMN|TABLE)\b`),
+	regexp.MustCompile(`(?i)\bALTER\s+COLUMN\s+\w+\s+(SET\s+DATA\s+)?TYPE\b`),
+	regexp.MustCompile(`(?i)\bSET\s+NOT\s+NULL\b`),
+	regexp.MustCompile(`(?i)\bADD\s+CONSTRAINT\b`),
+	regexp.MustCompile(`(?i)\bADD\s+COLUMN\b[^;]*\bCHECK\b`),
+}
+
+var (
+	functionBody = regexp.MustCompile(`(?s)\$\$.*?\$\$`)
+	sqlComment   = regexp.MustCompile(`--[^\n]*`)
+)
+
+// firstPolicedVersion is the oldest migration the destructive policy applies to
+const firstPolicedVersion = 28
+
+// TestCollect_RepoMigrationsAnnotated fails when a migration in the repo rewrites existing rows
+// without the destructive annotation, so it would run unattended in production
+func TestCollect_RepoMigrationsAnnotated(t *testing.T) {
+	migrations, err := Collect("../migrations")
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	destructive := 0
+	for _, m := range migrations {
+		if m.Destructive {
+			destructive++
+		}
+		if m.Version < firstPolicedVersion {
+			continue
+		}
+		body, err := os.ReadFile(filepath.Join("../migrations", m.Name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		up, _, _ := strings.Cut(string(body), gooseDownAnnotation)
+		up = sqlComment.ReplaceAllString(functionBody.ReplaceAllString(up, ""), "")
+		for _, re := range rewritingStatements {
+			if match := re.FindString(up); match != "" && !m.Destructive {
+				t.Errorf("%s runs %q but isn't annotated %s", m.Name, match, DestructiveAnnotation)
+			}
+		}
+	}
+	if destructive == 0 {
+		t.Error("Expected some of the repo's migrations to be classified destructive")
+	}
+}
+
+// testMigrations has a destructive migration in the middle
+var testMigrations = []Migration{
+	{Name: "001_a.sql", Version: 1},
+	{Name: "002_b.sql", Version: 2},
+	{Name: "003_drop_c.sql", Version: 3, Destructive: true},
+	{Name: "004_d.sql", Version: 4},
+}
+
+func TestStatus_Target(t *testing.T) {
+	tests := []struct {
+		name             string
+		current          int64
+		allowDestructive bool
+		want             int64
+	}{
+		{name: "production stops before destructive", current: 0, want: 2},
+		{name: "production with only destructive next", current: 2, want: 2},
+		{name: "dev applies everything", current: 0, allowDestructive: true, want: 4},
+		{name: "past the destructive migration", current: 3, want: 4},
+		{name: "up to date", current: 4, want: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := NewStatus(testMigrations, tt.current)
+			if got := status.Target(tt.allowDestructive); got != tt.want {
+				t.Errorf("Expected target %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestStatus_CheckVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  int64
+		mismatch bool
+		held     bool
+	}{
+		{name: "up to date", current: 4},
+		{name: "held by a destructive migration", current: 2, mismatch: true, held: true},
+		{name: "behind with safe migrations pending", current: 1, mismatch: true},
+		{name: "newer than the binary", current: 5, mismatch: true},
+		{name: "empty database", current: 0, mismatch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewStatus(testMigrations, tt.current).CheckVersion()
+			if tt.mismatch && !errors.Is(err, ErrSchemaMismatch) {
+				t.Fatalf("Expected ErrSchemaMismatch, got %v", err)
+			}
+			if !tt.mismatch && err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if held := errors.Is(err, ErrDestructivePending); held != tt.held {
+				t.Errorf("Expected ErrDestructivePending=%t, got %v", tt.held, err)
+			}
+		})
+	}
+
+	status := NewStatus(testMigrations, 2)
+	if status.Blocked == nil || status.Blocked.Name != "003_drop_c.sql" {
+		t.Errorf("Expected 003_drop_c.sql to be blocked, got %+v", status.Blocked)
+	}
+	if len(status.Pending) != 2 || status.Expected != 4 {
+		t.Errorf("Expected 2 pending migrations up to 4, got %d up to %d", len(status.Pending), status.Expected)
+	}
+}
@@ -0,0 +1,83 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/pressly/goose/v3"
+)
+
+// Runner applies one directory's migrations to a database
+type Runner struct {
+	db         *sql.DB
+	dir        string
+	migrations []Migration
+}
+
+// NewRunner collects and classifies the migrations in dir
+func NewRunner(db *sql.DB, dir string) (*Runner, error) {
+	if err := goose.SetDialect("postgres"); err != nil {
+		return nil, fmt.Errorf("failed to set goose dialect: %w", err)
+	}
+	migrations, err := Collect(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Runner{db: db, dir: dir, migrations: migrations}, nil
+}
+
+// Status compares the database with the collected migrations
+func (r *Runner) Status(ctx context.Context) (*Status, error) {
+	current, err := goose.GetDBVersionContext(ctx, r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	return NewStatus(r.migrations, current), nil
+}
+
+// Up applies pending migrations, stopping before the first destructive one unless
+// allowDestructive is set. It returns the status after migrating.
+func (r *Runner) Up(ctx context.Context, allowDestructive bool) (*Status, error) {
+	status, err := r.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if target := status.Target(allowDestructive); target > status.Current {
+		if err := goose.UpToContext(ctx, r.db, r.dir, target); err != nil {
+			return nil, fmt.Errorf("failed to migrate to %d: %w", target, err)
+		}
+	}
+	return r.Status(ctx)
+}
+
+// UpOne applies the next pending migration. A destructive one is refused unless
+// allowDestructive is set.
+func (r *Runner) UpOne(ctx context.Context, allowDestructive bool) (*Migration, error) {
+	status, err := r.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(status.Pending) == 0 {
+		return nil, nil
+	}
+	next := status.Pending[0]
+	if next.Destructive && !allowDestructive {
+		return nil, fmt.Errorf("migration %s is destructive: rerun with --allow-destructive to apply it", next.Name)
+	}
+	if err := goose.UpToContext(ctx, r.db, r.dir, next.Version); err != nil {
+		return nil, fmt.Errorf("failed to apply %s: %w", next.Name, err)
+	}
+	return &next, nil
+}
+
+// LogBlocked explains a held destructive migration in the server log
+func LogBlocked(status *Status) {
+	if status.Blocked == nil {
+		return
+	}
+	log.Printf("⚠️  %d migrations pending: %s is destructive and won't run automatically in production",
+		len(status.Pending), status.Blocked.Name)
+	log.Printf("   Apply it deliberately with: go run ./cmd/migrate up --allow-destructive")
+}
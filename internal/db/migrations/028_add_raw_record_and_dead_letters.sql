@@ -0,0 +1,49 @@
+-- +goose Up
+-- Preserve the full record JSON as received from Jetstream
+-- Lets future migrations re-extract fields from newer lexicon versions without replaying the firehose
+ALTER TABLE posts ADD COLUMN raw_record JSONB;
+ALTER TABLE comments ADD COLUMN raw_record JSONB;
+ALTER TABLE votes ADD COLUMN raw_record JSONB;
+ALTER TABLE communities ADD COLUMN raw_record JSONB;
+ALTER TABLE aggregators ADD COLUMN raw_record JSONB;
+ALTER TABLE aggregator_authorizations ADD COLUMN raw_record JSONB;
+
+COMMENT ON COLUMN posts.raw_record IS 'Full record JSON from the firehose (includes fields not yet extracted into columns)';
+COMMENT ON COLUMN comments.raw_record IS 'Full record JSON from the firehose (includes fields not yet extracted into columns)';
+COMMENT ON COLUMN votes.raw_record IS 'Full record JSON from the firehose (includes fields not yet extracted into columns)';
+COMMENT ON COLUMN communities.raw_record IS 'Full profile record JSON from the firehose (includes fields not yet extracted into columns)';
+COMMENT ON COLUMN aggregators.raw_record IS 'Full service record JSON from the firehose (includes fields not yet extracted into columns)';
+COMMENT ON COLUMN aggregator_authorizations.raw_record IS 'Full authorization record JSON from the firehose (includes fields not yet extracted into columns)';
+
+-- Dead letter queue for Jetstream events that cannot be indexed
+-- e.g. records whose $type does not match the collection they were written to
+CREATE TABLE jetstream_dead_letters (
+    id BIGSERIAL PRIMARY KEY,
+    did TEXT NOT NULL,
+    collection TEXT NOT NULL,
+    rkey TEXT NOT NULL,
+    operation TEXT NOT NULL,
+    cid TEXT,
+    reason TEXT NOT NULL,
+    raw_event JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_jetstream_dead_letters_collection ON jetstream_dead_letters(collection, created_at DESC);
+CREATE INDEX idx_jetstream_dead_letters_did ON jetstream_dead_letters(did);
+
+COMMENT ON TABLE jetstream_dead_letters IS 'Jetstream events rejected by consumers, kept for inspection and replay';
+COMMENT ON COLUMN jetstream_dead_letters.reason IS 'Why the event was rejected (e.g. $type mismatch)';
+COMMENT ON COLUMN jetstream_dead_letters.raw_event IS 'Full Jetstream event JSON as received';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_jetstream_dead_letters_did;
+DROP INDEX IF EXISTS idx_jetstream_dead_letters_collection;
+DROP TABLE IF EXISTS jetstream_dead_letters;
+
+ALTER TABLE aggregator_authorizations DROP COLUMN IF EXISTS raw_record;
+ALTER TABLE aggregators DROP COLUMN IF EXISTS raw_record;
+ALTER TABLE communities DROP COLUMN IF EXISTS raw_record;
+ALTER TABLE votes DROP COLUMN IF EXISTS raw_record;
+ALTER TABLE comments DROP COLUMN IF EXISTS raw_record;
+ALTER TABLE posts DROP COLUMN IF EXISTS raw_record;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +coves:destructive
+-- Track the most recent post per community for "recentActivity" subscription sorting
+-- Maintained by the post Jetstream consumer when a post is indexed
+ALTER TABLE communities ADD COLUMN last_post_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN communities.last_post_at IS 'Timestamp of the most recent post in this community (NULL if no posts)';
+
+-- Backfill from already indexed posts
+UPDATE communities c
+SET last_post_at = p.latest
+FROM (
+    SELECT community_did, MAX(LEAST(created_at, NOW())) AS latest
+    FROM posts
+    WHERE deleted_at IS NULL
+    GROUP BY community_did
+) p
+WHERE c.did = p.community_did;
+
+CREATE INDEX idx_communities_last_post_at ON communities(last_post_at DESC NULLS LAST);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_communities_last_post_at;
+ALTER TABLE communities DROP COLUMN IF EXISTS last_post_at;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- Notifications for users (currently @handle mentions in comments)
+-- Created by the comment Jetstream consumer when a mentioned handle resolves to a DID
+CREATE TABLE notifications (
+    id BIGSERIAL PRIMARY KEY,
+    recipient_did TEXT NOT NULL,           -- User being notified
+    author_did TEXT NOT NULL,              -- User whose action triggered the notification
+    reason TEXT NOT NULL,                  -- 'mention'
+    subject_uri TEXT NOT NULL,             -- AT-URI of the record that triggered the notification
+    subject_cid TEXT,                      -- CID of that record when the notification was created
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_notification_reason CHECK (reason IN ('mention')),
+    -- One notification per recipient per record (dedupes repeated mentions, replays, and edits)
+    CONSTRAINT unique_notification UNIQUE (recipient_did, reason, subject_uri)
+);
+
+CREATE INDEX idx_notifications_recipient ON notifications(recipient_did, created_at DESC);
+CREATE INDEX idx_notifications_unread ON notifications(recipient_did) WHERE is_read = FALSE;
+
+COMMENT ON TABLE notifications IS 'User notifications generated at index time (mentions)';
+COMMENT ON COLUMN notifications.subject_uri IS 'AT-URI of the record that triggered the notification (e.g. the mentioning comment)';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_notifications_unread;
+DROP INDEX IF EXISTS idx_notifications_recipient;
+DROP TABLE IF EXISTS notifications;
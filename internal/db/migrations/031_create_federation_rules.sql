@@ -0,0 +1,29 @@
+-- +goose Up
+-- Instance-level federation policy for remote instances' communities
+-- Patterns are exact domains ("example.com") or subdomain wildcards ("*.example.com")
+CREATE TABLE federation_rules (
+    id BIGSERIAL PRIMARY KEY,
+    pattern TEXT NOT NULL,
+    mode TEXT NOT NULL,
+    reason TEXT,
+    created_by TEXT NOT NULL,              -- DID of the admin who added the rule
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_federation_mode CHECK (mode IN ('allow', 'deny')),
+    CONSTRAINT unique_federation_pattern UNIQUE (pattern)
+);
+
+COMMENT ON TABLE federation_rules IS 'Allow/deny rules for remote instance domains (evaluated against community hostedBy)';
+COMMENT ON COLUMN federation_rules.pattern IS 'Exact domain or *.domain wildcard (matches subdomains only)';
+
+-- Communities hosted on a denied instance stay indexed but are hidden from list/discover/search
+ALTER TABLE communities ADD COLUMN federation_blocked BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN communities.federation_blocked IS 'True when the hostedBy instance is blocked by federation_rules';
+
+CREATE INDEX idx_communities_federation_blocked ON communities(did) WHERE federation_blocked = TRUE;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_communities_federation_blocked;
+ALTER TABLE communities DROP COLUMN IF EXISTS federation_blocked;
+DROP TABLE IF EXISTS federation_rules;
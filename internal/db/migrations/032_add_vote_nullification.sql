@@ -0,0 +1,28 @@
+-- +goose Up
+-- Neutralize votes cast by suspended/taken-down accounts (or by admin action)
+-- Nullified votes are soft-deleted (deleted_at set) AND marked with nullified_at,
+-- which distinguishes them from votes the user deleted and lets them be restored later
+ALTER TABLE votes ADD COLUMN nullified_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN votes.nullified_at IS 'Set when the vote was neutralized by voter nullification (restorable); NULL otherwise';
+
+-- At most one restorable vote per voter per subject, mirroring unique_voter_subject_active
+-- Also serves the voter lookup used when restoring
+CREATE UNIQUE INDEX unique_voter_subject_nullified ON votes(voter_did, subject_uri) WHERE nullified_at IS NOT NULL;
+
+-- Voters whose votes are currently neutralized
+-- The vote consumer checks this table so votes indexed while nullified don't count either
+CREATE TABLE nullified_voters (
+    voter_did TEXT PRIMARY KEY,
+    reason TEXT NOT NULL,                  -- 'takendown', 'suspended', or 'admin'
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT valid_nullification_reason CHECK (reason IN ('takendown', 'suspended', 'admin'))
+);
+
+COMMENT ON TABLE nullified_voters IS 'Accounts whose votes are excluded from post/comment counters';
+
+-- +goose Down
+DROP TABLE IF EXISTS nullified_voters;
+DROP INDEX IF EXISTS unique_voter_subject_nullified;
+ALTER TABLE votes DROP COLUMN IF EXISTS nullified_at;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- Post flair: communities define a set of flairs in their profile record,
+-- and posts carry tags drawn from that set (validated by the post consumer)
+ALTER TABLE communities ADD COLUMN flairs JSONB NOT NULL DEFAULT '[]'::jsonb;
+
+COMMENT ON COLUMN communities.flairs IS 'Post flairs defined by the community: [{"name": "...", "color": "#rrggbb"}] (max 20)';
+
+ALTER TABLE posts ADD COLUMN tags TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN posts.tags IS 'Flair tags from the community flair set at index time; kept when a flair is later removed';
+
+-- GIN index for the community feed tag filter (tags @> ARRAY[tag])
+CREATE INDEX idx_posts_tags ON posts USING GIN (tags);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_posts_tags;
+ALTER TABLE posts DROP COLUMN IF EXISTS tags;
+ALTER TABLE communities DROP COLUMN IF EXISTS flairs;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- Orphaned comments reference a root post that isn't indexed and couldn't be backfilled
+-- from the community's PDS. They are stored (so nothing is lost) but hidden from getComments
+-- until the post is indexed, either by the firehose or by the periodic retry job.
+ALTER TABLE comments ADD COLUMN orphaned BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE comments ADD COLUMN orphan_checked_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN comments.orphaned IS 'Root post unknown and backfill failed; excluded from thread queries until the post is indexed';
+COMMENT ON COLUMN comments.orphan_checked_at IS 'Last time the orphan retry job tried to backfill the root post';
+
+-- Partial index: the retry job and post indexing only look at orphaned rows, which should be rare
+CREATE INDEX idx_comments_orphaned_root ON comments(root_uri, orphan_checked_at) WHERE orphaned = TRUE;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_comments_orphaned_root;
+ALTER TABLE comments DROP COLUMN IF EXISTS orphan_checked_at;
+ALTER TABLE comments DROP COLUMN IF EXISTS orphaned;
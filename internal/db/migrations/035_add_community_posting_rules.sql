@@ -0,0 +1,9 @@
+-- +goose Up
+-- Posting rules from the community profile record (e.g. requireAltText for image posts),
+-- enforced by the post consumer at index time
+ALTER TABLE communities ADD COLUMN posting_rules JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+COMMENT ON COLUMN communities.posting_rules IS 'Posting rules from the profile record: {"requireAltText": bool}';
+
+-- +goose Down
+ALTER TABLE communities DROP COLUMN IF EXISTS posting_rules;
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Communities an instance admin features on the logged-out front page (social.coves.discover.getFrontPage)
+CREATE TABLE instance_featured_communities (
+    community_did TEXT PRIMARY KEY REFERENCES communities(did) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    weight DOUBLE PRECISION NOT NULL DEFAULT 2.0,
+    added_by TEXT NOT NULL,                -- DID of the admin who featured the community
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT positive_featured_weight CHECK (weight > 0)
+);
+
+COMMENT ON TABLE instance_featured_communities IS 'Featured communities for the logged-out front page, ordered by position';
+COMMENT ON COLUMN instance_featured_communities.weight IS 'Multiplier applied to the hot rank of the community''s posts when blending the front page';
+
+CREATE INDEX idx_instance_featured_communities_position ON instance_featured_communities(position);
+
+-- +goose Down
+DROP TABLE IF EXISTS instance_featured_communities;
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +coves:destructive
+-- Feed cards show "12 comments, last activity 2h ago". comment_count now counts every live
+-- comment in the thread (previously only direct comments on the post), top_level_comment_count
+-- counts direct comments, and last_activity_at records the newest comment in the thread.
+-- last_activity_at is not rolled back when comments are deleted.
+ALTER TABLE posts ADD COLUMN top_level_comment_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN last_activity_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN posts.comment_count IS 'Live comments anywhere in the thread (root_uri = post)';
+COMMENT ON COLUMN posts.top_level_comment_count IS 'Live comments replying directly to the post';
+COMMENT ON COLUMN posts.last_activity_at IS 'Newest comment in the thread, including deleted ones; NULL until the first comment';
+
+-- Backfill from existing comments
+UPDATE posts p
+SET comment_count = agg.total,
+    top_level_comment_count = agg.top_level,
+    last_activity_at = agg.last_activity
+FROM (
+    SELECT
+        root_uri,
+        COUNT(*) FILTER (WHERE deleted_at IS NULL) AS total,
+        COUNT(*) FILTER (WHERE deleted_at IS NULL AND parent_uri = root_uri) AS top_level,
+        LEAST(MAX(created_at), NOW()) AS last_activity
+    FROM comments
+    GROUP BY root_uri
+) agg
+WHERE p.uri = agg.root_uri;
+
+-- Community feed "activity" sort: posts without comments rank by creation time
+CREATE INDEX idx_posts_community_activity
+    ON posts(community_did, (COALESCE(last_activity_at, created_at)) DESC, uri DESC)
+    WHERE deleted_at IS NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_posts_community_activity;
+ALTER TABLE posts DROP COLUMN IF EXISTS last_activity_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS top_level_comment_count;
+UPDATE posts p
+SET comment_count = (
+    SELECT COUNT(*) FROM comments c WHERE c.parent_uri = p.uri AND c.deleted_at IS NULL
+);
+COMMENT ON COLUMN posts.comment_count IS NULL;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- DID documents for instance-native did:web communities, served by the AppView at
+-- https://{host}/.well-known/did.json. Rows are written before the PDS account exists
+-- (the PDS resolves the DID during createAccount), so there is no FK to communities.
+CREATE TABLE community_did_web_documents (
+    did TEXT PRIMARY KEY,
+    handle TEXT NOT NULL UNIQUE,
+    signing_key TEXT NOT NULL,             -- Public atproto signing key reserved on the PDS (did:key)
+    pds_url TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT did_web_document_did_format CHECK (did LIKE 'did:web:%')
+);
+
+COMMENT ON TABLE community_did_web_documents IS 'did:web DID documents the AppView serves for its own communities';
+
+-- +goose Down
+DROP TABLE IF EXISTS community_did_web_documents;
@@ -0,0 +1,29 @@
+-- +goose Up
+-- Community creation history for the per-user creation rate limit. No FK to communities:
+-- deleting a community must not hand its creator back a creation slot.
+CREATE TABLE community_creations (
+    id BIGSERIAL PRIMARY KEY,
+    creator_did TEXT NOT NULL,
+    community_did TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_community_creations_creator ON community_creations(creator_did, created_at DESC);
+
+-- Admin-managed reserved community names (built-in names are hardcoded in the AppView)
+CREATE TABLE reserved_community_names (
+    name TEXT PRIMARY KEY,
+    reason TEXT,
+    added_by TEXT,                         -- Admin DID that reserved the name
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT reserved_community_name_lowercase CHECK (name = LOWER(name))
+);
+
+COMMENT ON TABLE community_creations IS 'Communities created through this instance, for creation rate limiting';
+COMMENT ON TABLE reserved_community_names IS 'Community names no user may create';
+
+-- +goose Down
+DROP TABLE IF EXISTS reserved_community_names;
+DROP TABLE IF EXISTS community_creations;
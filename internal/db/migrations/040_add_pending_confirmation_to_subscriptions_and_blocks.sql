@@ -0,0 +1,21 @@
+-- +goose Up
+-- Optimistic indexing for write-forward subscribes and blocks: the AppView indexes the record
+-- as soon as the PDS write succeeds, flagged pending until the Jetstream event confirms it.
+-- Pending rows whose event never arrives are reaped (and subscriber counts reverted).
+ALTER TABLE community_subscriptions ADD COLUMN pending_confirmation BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE community_blocks ADD COLUMN pending_confirmation BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Reaper scans and Jetstream confirmation (matches on record_uri)
+CREATE INDEX idx_subscriptions_pending ON community_subscriptions(subscribed_at) WHERE pending_confirmation;
+CREATE INDEX idx_blocks_pending ON community_blocks(blocked_at) WHERE pending_confirmation;
+CREATE INDEX idx_subscriptions_record_uri ON community_subscriptions(record_uri);
+
+COMMENT ON COLUMN community_subscriptions.pending_confirmation IS 'Indexed from a local write-forward; cleared when the Jetstream event arrives';
+COMMENT ON COLUMN community_blocks.pending_confirmation IS 'Indexed from a local write-forward; cleared when the Jetstream event arrives';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_subscriptions_record_uri;
+DROP INDEX IF EXISTS idx_blocks_pending;
+DROP INDEX IF EXISTS idx_subscriptions_pending;
+ALTER TABLE community_blocks DROP COLUMN IF EXISTS pending_confirmation;
+ALTER TABLE community_subscriptions DROP COLUMN IF EXISTS pending_confirmation;
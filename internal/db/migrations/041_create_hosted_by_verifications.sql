@@ -0,0 +1,27 @@
+-- +goose Up
+-- Cached did:web hostedBy verification results, so restarts don't re-fetch every
+-- instance's DID document. Only definitive results are stored - network failures aren't.
+CREATE TABLE hosted_by_verifications (
+    domain TEXT NOT NULL,
+    did TEXT NOT NULL,
+    valid BOOLEAN NOT NULL,
+    failure_reason TEXT,
+    verified_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+
+    PRIMARY KEY (domain, did)
+);
+
+-- Set by the periodic re-verification job when a community's hostedBy instance no longer
+-- verifies, cleared when it verifies again
+ALTER TABLE communities ADD COLUMN hosted_by_unverified BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_communities_hosted_by_unverified ON communities(did) WHERE hosted_by_unverified = TRUE;
+
+COMMENT ON TABLE hosted_by_verifications IS 'did:web DID document verification results for community hosting instances';
+COMMENT ON COLUMN communities.hosted_by_unverified IS 'True when the hostedBy instance failed its latest re-verification';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_communities_hosted_by_unverified;
+ALTER TABLE communities DROP COLUMN IF EXISTS hosted_by_unverified;
+DROP TABLE IF EXISTS hosted_by_verifications;
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +coves:destructive
+-- Full-text search over comment content for moderator tooling (social.coves.community.comment.search)
+-- Maintained by trigger rather than generated from content: soft deletes blank content, but
+-- moderators still need to find removed rule-breaking comments with includeDeleted
+ALTER TABLE comments ADD COLUMN content_search tsvector;
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION update_comment_content_search()
+RETURNS TRIGGER AS $$
+BEGIN
+    -- Keep the last live content's vector when a soft delete blanks the content
+    IF TG_OP = 'INSERT' OR NEW.deleted_at IS NULL THEN
+        NEW.content_search := to_tsvector('english', COALESCE(NEW.content, ''));
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_update_comment_content_search
+    BEFORE INSERT OR UPDATE OF content ON comments
+    FOR EACH ROW
+    EXECUTE FUNCTION update_comment_content_search();
+
+-- Backfill existing live comments (deleted comments have already lost their content)
+UPDATE comments SET content_search = to_tsvector('english', COALESCE(content, ''));
+
+CREATE INDEX idx_comments_content_search ON comments USING gin(content_search);
+
+COMMENT ON COLUMN comments.content_search IS 'Full-text search vector of the comment content, kept after soft delete';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_comments_content_search;
+DROP TRIGGER IF EXISTS trigger_update_comment_content_search ON comments;
+DROP FUNCTION IF EXISTS update_comment_content_search();
+ALTER TABLE comments DROP COLUMN IF EXISTS content_search;
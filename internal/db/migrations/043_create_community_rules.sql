@@ -0,0 +1,18 @@
+-- +goose Up
+-- Community welcome/rules documents, indexed from social.coves.community.rules records (rkey self)
+-- Markdown is sanitized by the consumer before it's stored
+CREATE TABLE community_rules (
+    community_did TEXT PRIMARY KEY REFERENCES communities(did) ON DELETE CASCADE,
+    markdown TEXT NOT NULL DEFAULT '',
+    rules JSONB NOT NULL DEFAULT '[]'::jsonb,  -- [{title, description, createdAt}]
+    record_uri TEXT NOT NULL,
+    record_cid TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+
+    CONSTRAINT community_rules_markdown_size CHECK (octet_length(markdown) <= 20000)
+);
+
+COMMENT ON TABLE community_rules IS 'Sanitized community rules documents from social.coves.community.rules records';
+
+-- +goose Down
+DROP TABLE IF EXISTS community_rules;
@@ -0,0 +1,37 @@
+-- +goose Up
+-- Moderation visibility for posts and comments, separate from deletion:
+--   visible     - shown to everyone
+--   removed     - hidden from everyone
+--   author_only - hidden from everyone except the author, who sees it as if nothing happened
+--                 (defuses trolls without tipping them off)
+CREATE TYPE content_visibility_state AS ENUM ('visible', 'removed', 'author_only');
+
+ALTER TABLE posts
+    ADD COLUMN visibility_state content_visibility_state NOT NULL DEFAULT 'visible',
+    ADD COLUMN visibility_updated_by TEXT,
+    ADD COLUMN visibility_updated_at TIMESTAMPTZ;
+
+ALTER TABLE comments
+    ADD COLUMN visibility_state content_visibility_state NOT NULL DEFAULT 'visible',
+    ADD COLUMN visibility_updated_by TEXT,
+    ADD COLUMN visibility_updated_at TIMESTAMPTZ;
+
+-- Moderation queues list hidden content; the vast majority of rows are visible
+CREATE INDEX idx_posts_hidden ON posts(visibility_state) WHERE visibility_state <> 'visible';
+CREATE INDEX idx_comments_hidden ON comments(visibility_state) WHERE visibility_state <> 'visible';
+
+COMMENT ON COLUMN posts.visibility_state IS 'Moderation visibility: visible, removed, or author_only (shown only to the author)';
+COMMENT ON COLUMN comments.visibility_state IS 'Moderation visibility: visible, removed, or author_only (shown only to the author)';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_comments_hidden;
+DROP INDEX IF EXISTS idx_posts_hidden;
+ALTER TABLE comments
+    DROP COLUMN IF EXISTS visibility_updated_at,
+    DROP COLUMN IF EXISTS visibility_updated_by,
+    DROP COLUMN IF EXISTS visibility_state;
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS visibility_updated_at,
+    DROP COLUMN IF EXISTS visibility_updated_by,
+    DROP COLUMN IF EXISTS visibility_state;
+DROP TYPE IF EXISTS content_visibility_state;
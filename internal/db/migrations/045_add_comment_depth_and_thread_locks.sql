@@ -0,0 +1,52 @@
+-- +goose Up
+-- +coves:destructive
+-- Thread depth: comments directly on a post are depth 1, replies are their parent's depth + 1
+-- NULL means the parent hasn't been indexed yet; the depth is filled in when it arrives
+-- depth_exceeded marks comments past the max thread depth, rendered as "continue thread" links
+ALTER TABLE comments
+    ADD COLUMN depth INT,
+    ADD COLUMN depth_exceeded BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'rejected'));
+
+-- Locked posts accept no new comments
+ALTER TABLE posts
+    ADD COLUMN locked BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN locked_by TEXT,
+    ADD COLUMN locked_at TIMESTAMPTZ;
+
+-- Depth propagation walks a parent's descendants, including deleted ones
+CREATE INDEX idx_comments_parent_all ON comments(parent_uri);
+
+-- Backfill depth for existing comments (default max thread depth of 12)
+-- The depth cap stops the walk on malformed parent cycles
+WITH RECURSIVE tree AS (
+    SELECT c.id, c.uri, 1 AS depth
+    FROM comments c
+    WHERE c.parent_uri = c.root_uri
+    UNION ALL
+    SELECT c.id, c.uri, t.depth + 1
+    FROM comments c
+    JOIN tree t ON c.parent_uri = t.uri
+    WHERE t.depth < 10000
+)
+UPDATE comments
+SET depth = tree.depth,
+    depth_exceeded = tree.depth > 12
+FROM tree
+WHERE comments.id = tree.id;
+
+COMMENT ON COLUMN comments.depth IS 'Thread depth (1 = comment on the post); NULL until the parent is indexed';
+COMMENT ON COLUMN comments.depth_exceeded IS 'Deeper than the max thread depth; shown as a continue-thread link';
+COMMENT ON COLUMN comments.status IS 'active, or rejected (created on a locked post; indexed but hidden)';
+COMMENT ON COLUMN posts.locked IS 'Locked threads accept no new comments';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_comments_parent_all;
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS locked_at,
+    DROP COLUMN IF EXISTS locked_by,
+    DROP COLUMN IF EXISTS locked;
+ALTER TABLE comments
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS depth_exceeded,
+    DROP COLUMN IF EXISTS depth;
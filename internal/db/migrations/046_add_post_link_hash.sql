@@ -0,0 +1,14 @@
+-- +goose Up
+-- SHA-256 of the canonical form of a post's external link (posts.CanonicalizeURL), so posts
+-- about the same page can be found however the link was spelled
+-- NULL for posts without a link; posts indexed before this migration are hashed at startup
+ALTER TABLE posts ADD COLUMN normalized_url_hash TEXT;
+
+CREATE INDEX idx_posts_normalized_url_hash ON posts(normalized_url_hash, score DESC)
+    WHERE normalized_url_hash IS NOT NULL AND deleted_at IS NULL;
+
+COMMENT ON COLUMN posts.normalized_url_hash IS 'SHA-256 (hex) of the canonicalized external embed URL';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_posts_normalized_url_hash;
+ALTER TABLE posts DROP COLUMN IF EXISTS normalized_url_hash;
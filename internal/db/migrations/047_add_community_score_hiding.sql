@@ -0,0 +1,12 @@
+-- +goose Up
+-- +coves:destructive
+-- Score hiding window from the community profile record: vote counts on posts and comments
+-- younger than this many hours are hidden from everyone but the author and moderators
+ALTER TABLE communities
+    ADD COLUMN score_hiding_hours INT NOT NULL DEFAULT 0
+    CHECK (score_hiding_hours BETWEEN 0 AND 24);
+
+COMMENT ON COLUMN communities.score_hiding_hours IS 'Hours after posting during which vote counts are hidden (0 = never hidden)';
+
+-- +goose Down
+ALTER TABLE communities DROP COLUMN IF EXISTS score_hiding_hours;
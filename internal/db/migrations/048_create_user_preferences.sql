@@ -0,0 +1,21 @@
+-- +goose Up
+-- Viewer preferences from the social.coves.actor.preferences record (rkey "self")
+-- The full record is kept as JSONB so new preferences don't need a migration; fields the
+-- AppView filters on are extracted into columns when the record is indexed
+CREATE TABLE user_preferences (
+    user_did TEXT PRIMARY KEY REFERENCES users(did) ON DELETE CASCADE,
+    record_uri TEXT NOT NULL,
+    record_cid TEXT NOT NULL,
+    preferences JSONB NOT NULL DEFAULT '{}',
+    hide_aggregator_posts BOOLEAN NOT NULL DEFAULT FALSE,
+    indexed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Timeline and discover only consult rows that hide something
+CREATE INDEX idx_user_preferences_hide_aggregator_posts ON user_preferences(user_did) WHERE hide_aggregator_posts;
+
+COMMENT ON COLUMN user_preferences.preferences IS 'Full preferences record as received from the firehose';
+COMMENT ON COLUMN user_preferences.hide_aggregator_posts IS 'Exclude aggregator-authored posts from the timeline and discover feeds';
+
+-- +goose Down
+DROP TABLE IF EXISTS user_preferences;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Communities whose name or display name claims another instance's domain or a verified
+-- community's handle are flagged at index time and hidden from list/search/discover until an
+-- admin reviews them. Clearing remembers the reason so re-indexing doesn't re-flag for it;
+-- confirming suspends the community.
+ALTER TABLE communities
+    ADD COLUMN impersonation_flag BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN impersonation_reason TEXT,
+    ADD COLUMN impersonation_cleared_reason TEXT,
+    ADD COLUMN suspended_at TIMESTAMPTZ;
+
+CREATE INDEX idx_communities_impersonation_flag ON communities(updated_at DESC) WHERE impersonation_flag = TRUE AND suspended_at IS NULL;
+
+COMMENT ON COLUMN communities.impersonation_flag IS 'True when the profile looks like it impersonates another community';
+COMMENT ON COLUMN communities.impersonation_reason IS 'Why the impersonation heuristics flagged the community';
+COMMENT ON COLUMN communities.impersonation_cleared_reason IS 'Flag reason an admin reviewed and cleared; not re-flagged while it is unchanged';
+COMMENT ON COLUMN communities.suspended_at IS 'When an admin suspended the community (confirmed impersonation)';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_communities_impersonation_flag;
+ALTER TABLE communities
+    DROP COLUMN IF EXISTS suspended_at,
+    DROP COLUMN IF EXISTS impersonation_cleared_reason,
+    DROP COLUMN IF EXISTS impersonation_reason,
+    DROP COLUMN IF EXISTS impersonation_flag;
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Composite indexes matching the feed keyset cursors
+-- Feeds page with row comparisons like (created_at, uri) < ($1, $2), which Postgres can only
+-- turn into an index range scan when an index covers every column of the key in the same
+-- order. Without the trailing uri, deep pages re-read every post with the same timestamp
+-- or score before finding where the cursor left off.
+
+-- Discover "new" (all communities)
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_created_uri
+ON posts(created_at DESC, uri DESC)
+WHERE deleted_at IS NULL;
+
+-- Discover "top" (all communities)
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_score_created_uri
+ON posts(score DESC, created_at DESC, uri DESC)
+WHERE deleted_at IS NULL;
+
+-- Community feed and timeline "new"; supersedes idx_posts_community_created
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_community_created_uri
+ON posts(community_did, created_at DESC, uri DESC)
+WHERE deleted_at IS NULL;
+
+-- Community feed and timeline "top"; supersedes idx_posts_community_score
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_community_score_uri
+ON posts(community_did, score DESC, created_at DESC, uri DESC)
+WHERE deleted_at IS NULL;
+
+DROP INDEX CONCURRENTLY IF EXISTS idx_posts_community_created;
+DROP INDEX CONCURRENTLY IF EXISTS idx_posts_community_score;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_community_created
+ON posts(community_did, created_at DESC)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_community_score
+ON posts(community_did, score DESC, created_at DESC)
+WHERE deleted_at IS NULL;
+
+DROP INDEX CONCURRENTLY IF EXISTS idx_posts_community_score_uri;
+DROP INDEX CONCURRENTLY IF EXISTS idx_posts_community_created_uri;
+DROP INDEX CONCURRENTLY IF EXISTS idx_posts_score_created_uri;
+DROP INDEX CONCURRENTLY IF EXISTS idx_posts_created_uri;
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +coves:destructive
+-- Content labels (nsfw, spoiler, violence, gore) for feed filtering and blurring
+-- A post's effective labels are its community's content warnings plus the allowed labels the
+-- author self-applied in the record, with moderator overrides taking precedence over both.
+-- labels is the indexed copy of the record's non-negated, allowed self-labels; content_labels
+-- keeps the record's selfLabels as written.
+ALTER TABLE posts
+    ADD COLUMN labels TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE posts
+SET labels = ARRAY(
+    SELECT DISTINCT l->>'val'
+    FROM jsonb_array_elements(content_labels->'values') l
+    WHERE l->>'val' IN ('nsfw', 'spoiler', 'violence', 'gore')
+        AND COALESCE((l->>'neg')::boolean, FALSE) = FALSE
+    ORDER BY 1
+)
+WHERE jsonb_typeof(content_labels->'values') = 'array';
+
+COMMENT ON COLUMN posts.labels IS 'Allowed, non-negated self-labels from the record (nsfw, spoiler, violence, gore)';
+
+-- Labels moderators add to or remove from a post after the fact
+-- neg = TRUE removes the label even when self-applied or inherited from the community
+CREATE TABLE post_label_overrides (
+    post_uri TEXT NOT NULL REFERENCES posts(uri) ON DELETE CASCADE,
+    label TEXT NOT NULL CHECK (label IN ('nsfw', 'spoiler', 'violence', 'gore')),
+    neg BOOLEAN NOT NULL DEFAULT FALSE,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (post_uri, label)
+);
+
+COMMENT ON TABLE post_label_overrides IS 'Moderator label decisions on posts, merged over self and community labels at hydration';
+COMMENT ON COLUMN post_label_overrides.neg IS 'True removes the label; false applies it';
+
+-- +goose Down
+DROP TABLE IF EXISTS post_label_overrides;
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS labels;
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Support for the social.coves.actor.getActivity profile heatmap
+-- Account status comes from firehose account events; deactivated accounts show no activity
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN users.deactivated_at IS 'When the account went inactive on its PDS (deactivated, taken down, suspended); NULL while active';
+
+-- Per-day comment counts scan one author's live comments by date, like
+-- idx_posts_author_created does for posts
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_comments_commenter_created
+ON comments(commenter_did, created_at DESC)
+WHERE deleted_at IS NULL;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+DROP INDEX CONCURRENTLY IF EXISTS idx_comments_commenter_created;
+ALTER TABLE users DROP COLUMN IF EXISTS deactivated_at;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Edit history for moderators (social.coves.moderation.getCommentHistory)
+-- Revisions are keyed by subject URI rather than tied to the comments table so
+-- post edits can share the same storage once post updates are indexed.
+CREATE TABLE content_revisions (
+    id BIGSERIAL PRIMARY KEY,
+    subject_uri TEXT NOT NULL,               -- AT-URI of the edited record
+    cid TEXT NOT NULL,                       -- CID of the replaced version
+    content TEXT,                            -- Text of the replaced version
+    content_facets JSONB,
+    embed JSONB,
+    written_at TIMESTAMPTZ NOT NULL,         -- When the replaced version was indexed or last edited
+    replaced_at TIMESTAMPTZ NOT NULL DEFAULT NOW() -- When the edit that replaced it was indexed
+);
+
+-- History reads and pruning walk one subject's revisions newest first
+CREATE INDEX idx_content_revisions_subject ON content_revisions(subject_uri, id DESC);
+
+COMMENT ON TABLE content_revisions IS 'Prior versions of edited records, newest kept; capped per subject by the indexer';
+
+ALTER TABLE comments ADD COLUMN edited_at TIMESTAMPTZ;
+
+COMMENT ON COLUMN comments.edited_at IS 'When the most recent edit that changed the record was indexed; NULL if never edited';
+
+-- +goose Down
+ALTER TABLE comments DROP COLUMN IF EXISTS edited_at;
+DROP TABLE IF EXISTS content_revisions;
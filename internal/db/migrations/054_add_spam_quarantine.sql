@@ -0,0 +1,35 @@
+-- +goose Up
+-- +coves:destructive
+-- Spam heuristics at index time (internal/core/spamguard)
+-- Quarantined posts are indexed but hidden (visibility_state = 'removed') until an admin
+-- releases them or confirms them as spam.
+ALTER TABLE posts
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'quarantined', 'spam')),
+    ADD COLUMN quarantine_reasons TEXT[];
+
+-- The admin review queue lists quarantined posts newest first
+CREATE INDEX idx_posts_quarantined ON posts(indexed_at DESC) WHERE status = 'quarantined';
+
+COMMENT ON COLUMN posts.status IS 'active, quarantined (held for spam review; hidden), or spam (confirmed by an admin; hidden)';
+COMMENT ON COLUMN posts.quarantine_reasons IS 'Spam signals the post tripped when it was quarantined';
+
+-- Rolling record of recent posts by accounts the guard screens
+-- Rows older than the guard's windows are pruned as new posts arrive
+CREATE TABLE spam_fingerprints (
+    post_uri TEXT PRIMARY KEY,
+    author_did TEXT NOT NULL,
+    community_did TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,              -- Link hash or normalized text hash; '' when the post has neither
+    seen_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_spam_fingerprints_fingerprint ON spam_fingerprints(fingerprint, seen_at);
+CREATE INDEX idx_spam_fingerprints_author ON spam_fingerprints(author_did, seen_at);
+CREATE INDEX idx_spam_fingerprints_seen ON spam_fingerprints(seen_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS spam_fingerprints;
+DROP INDEX IF EXISTS idx_posts_quarantined;
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS quarantine_reasons,
+    DROP COLUMN IF EXISTS status;
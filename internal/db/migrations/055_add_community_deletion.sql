@@ -0,0 +1,24 @@
+-- +goose Up
+-- Owner-initiated community deletion (social.coves.community.delete)
+-- Deleted communities disappear from lists, search and feeds immediately but keep their rows,
+-- posts and subscriptions. After the grace period a background job deactivates the community's
+-- PDS account; until then social.coves.community.undelete restores everything.
+ALTER TABLE communities
+    ADD COLUMN deleted_at TIMESTAMPTZ,
+    ADD COLUMN deleted_by_did TEXT,
+    ADD COLUMN pds_deactivated_at TIMESTAMPTZ;
+
+-- The deactivation job scans deleted communities whose PDS account is still active
+CREATE INDEX idx_communities_pending_deactivation ON communities(deleted_at)
+    WHERE deleted_at IS NOT NULL AND pds_deactivated_at IS NULL;
+
+COMMENT ON COLUMN communities.deleted_at IS 'When the owner or an admin deleted the community; NULL if not deleted';
+COMMENT ON COLUMN communities.deleted_by_did IS 'Who deleted the community';
+COMMENT ON COLUMN communities.pds_deactivated_at IS 'When the grace period ended and the community PDS account was deactivated';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_communities_pending_deactivation;
+ALTER TABLE communities
+    DROP COLUMN IF EXISTS pds_deactivated_at,
+    DROP COLUMN IF EXISTS deleted_by_did,
+    DROP COLUMN IF EXISTS deleted_at;
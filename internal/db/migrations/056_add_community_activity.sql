@@ -0,0 +1,32 @@
+-- +goose Up
+-- Unique active users per community (weekly/monthly actives)
+-- Post, comment and vote consumers record one row per user per community per UTC day;
+-- a nightly rollup counts distinct users over the last 7 and 30 days onto the community row.
+-- Rows older than the retention window (90 days) are pruned by the same job.
+CREATE TABLE community_activity (
+    community_did TEXT NOT NULL,
+    user_did TEXT NOT NULL,
+    day DATE NOT NULL,
+    PRIMARY KEY (community_did, day, user_did)
+);
+
+-- Retention pruning deletes by day across all communities
+CREATE INDEX idx_community_activity_day ON community_activity(day);
+
+ALTER TABLE communities
+    ADD COLUMN weekly_active_users INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN monthly_active_users INTEGER NOT NULL DEFAULT 0;
+
+-- The "active" list sort ranks by weekly actives
+CREATE INDEX idx_communities_weekly_active ON communities(weekly_active_users DESC);
+
+COMMENT ON TABLE community_activity IS 'Users who posted, commented or voted in a community, one row per UTC day; kept 90 days';
+COMMENT ON COLUMN communities.weekly_active_users IS 'Distinct users active in the last 7 UTC days, as of the last rollup';
+COMMENT ON COLUMN communities.monthly_active_users IS 'Distinct users active in the last 30 UTC days, as of the last rollup';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_communities_weekly_active;
+ALTER TABLE communities
+    DROP COLUMN IF EXISTS monthly_active_users,
+    DROP COLUMN IF EXISTS weekly_active_users;
+DROP TABLE IF EXISTS community_activity;
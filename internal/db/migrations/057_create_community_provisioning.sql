@@ -0,0 +1,29 @@
+-- +goose Up
+-- Progress of community creations, so one interrupted by a PDS failure can resume
+-- CreateCommunity provisions a PDS account, writes the profile record, then stores the
+-- community. A retry with the same name resumes the half-provisioned account instead of
+-- failing on the handle the first attempt already took. Admins clean up creations that
+-- never finished (see social.coves.admin.listStuckProvisionings).
+CREATE TABLE community_provisioning (
+    name TEXT PRIMARY KEY,
+    created_by_did TEXT NOT NULL,
+    community_did TEXT NOT NULL,
+    handle TEXT NOT NULL,
+    pds_url TEXT NOT NULL,
+    pds_email TEXT NOT NULL,
+    pds_password_encrypted BYTEA NOT NULL,  -- pgp_sym_encrypt, like communities.pds_password_encrypted
+    state TEXT NOT NULL CHECK (state IN ('account_created', 'record_written', 'indexed')),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Admins list unfinished creations, oldest first
+CREATE INDEX idx_community_provisioning_stuck ON community_provisioning(updated_at)
+    WHERE state <> 'indexed';
+
+COMMENT ON COLUMN community_provisioning.state IS 'account_created (PDS account exists), record_written (profile record written), indexed (community stored; done)';
+COMMENT ON COLUMN community_provisioning.last_error IS 'Why the last attempt stopped, for admins';
+
+-- +goose Down
+DROP TABLE IF EXISTS community_provisioning;
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +coves:destructive
+-- Comment collapsing from the community profile record: thread views mark comments scoring
+-- below collapse_threshold as collapsed, and crowd control also collapses comments from
+-- non-subscribers and brand-new accounts
+ALTER TABLE communities
+    ADD COLUMN collapse_threshold INT NOT NULL DEFAULT -5
+    CHECK (collapse_threshold BETWEEN -1000 AND 0),
+    ADD COLUMN crowd_control TEXT NOT NULL DEFAULT 'off'
+    CHECK (crowd_control IN ('off', 'low', 'high'));
+
+COMMENT ON COLUMN communities.collapse_threshold IS 'Comments scoring below this are collapsed in thread views';
+COMMENT ON COLUMN communities.crowd_control IS 'off, low (collapse new non-subscribers) or high (collapse non-subscribers and new accounts)';
+
+-- +goose Down
+ALTER TABLE communities DROP COLUMN IF EXISTS crowd_control;
+ALTER TABLE communities DROP COLUMN IF EXISTS collapse_threshold;
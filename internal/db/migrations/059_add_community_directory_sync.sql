@@ -0,0 +1,23 @@
+-- +goose Up
+-- Communities learned from peer instances' directories (social.coves.sync.listCommunities)
+-- rather than the firehose. Remote rows are searchable and discoverable but never re-listed
+-- in this instance's own directory; a firehose event for the same DID takes the row over.
+ALTER TABLE communities
+    ADD COLUMN remote BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN communities.remote IS 'Indexed from a peer instance directory, not the firehose';
+
+-- Directory listing keyset: (updated_at, did) over communities indexed from the firehose
+CREATE INDEX idx_communities_directory ON communities (updated_at, did) WHERE NOT remote;
+
+-- How far each peer's directory has been read
+CREATE TABLE directory_sync_peers (
+    peer_url TEXT PRIMARY KEY,
+    cursor TEXT NOT NULL,
+    synced_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS directory_sync_peers;
+DROP INDEX IF EXISTS idx_communities_directory;
+ALTER TABLE communities DROP COLUMN IF EXISTS remote;
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +coves:destructive
+-- Brigading alerts: posts whose vote velocity spiked far above their community's baseline,
+-- driven by accounts that had never interacted with the community before
+-- The brigade analyzer job assesses one 10-minute bucket per run, raises at most one alert per
+-- post, and notifies the community's moderators. Moderators review alerts in the moderation
+-- queue (social.coves.moderation.listBrigadeAlerts).
+CREATE TABLE brigade_alerts (
+    id BIGSERIAL PRIMARY KEY,
+    post_uri TEXT NOT NULL UNIQUE,
+    community_did TEXT NOT NULL REFERENCES communities(did) ON DELETE CASCADE,
+    bucket_start TIMESTAMPTZ NOT NULL,      -- Start of the bucket that tripped detection
+    votes INTEGER NOT NULL,                 -- Votes on the post during that bucket
+    outsiders INTEGER NOT NULL,             -- ...from voters with no earlier activity in the community
+    z_score DOUBLE PRECISION NOT NULL,
+    baseline_velocity DOUBLE PRECISION NOT NULL, -- Community's mean votes per post per bucket
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'dismissed', 'confirmed')),
+    reviewed_by_did TEXT,
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The moderation queue lists a community's open alerts, newest first
+CREATE INDEX idx_brigade_alerts_open ON brigade_alerts(community_did, created_at DESC) WHERE status = 'open';
+
+-- The analyzer scans votes by arrival time
+CREATE INDEX idx_votes_indexed_at ON votes(indexed_at) WHERE deleted_at IS NULL;
+
+-- Moderators are notified of new alerts
+ALTER TABLE notifications DROP CONSTRAINT valid_notification_reason;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_reason CHECK (reason IN ('mention', 'brigade_alert'));
+
+COMMENT ON TABLE brigade_alerts IS 'Posts flagged for anomalous vote velocity from accounts new to the community';
+
+-- +goose Down
+DELETE FROM notifications WHERE reason = 'brigade_alert';
+ALTER TABLE notifications DROP CONSTRAINT valid_notification_reason;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_reason CHECK (reason IN ('mention'));
+DROP INDEX IF EXISTS idx_votes_indexed_at;
+DROP TABLE IF EXISTS brigade_alerts;
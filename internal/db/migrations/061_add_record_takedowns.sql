@@ -0,0 +1,34 @@
+-- +goose Up
+-- Admin takedowns of individual posts and comments (social.coves.admin.takedownRecord)
+-- A taken-down record is excluded from every read regardless of its visibility state, and
+-- direct fetches report RecordTakenDown. Posts in community repos this instance provisioned
+-- are also deleted from the PDS; user-repo content can only be taken down locally.
+ALTER TABLE posts ADD COLUMN taken_down_at TIMESTAMPTZ;
+ALTER TABLE comments ADD COLUMN taken_down_at TIMESTAMPTZ;
+
+-- Audit trail: one row per takedown, completed when it's reversed
+-- A record can be taken down again after a reversal, so subjects may repeat.
+CREATE TABLE record_takedowns (
+    id BIGSERIAL PRIMARY KEY,
+    subject_uri TEXT NOT NULL,
+    reason TEXT NOT NULL CHECK (reason IN ('legal', 'copyright', 'abuse', 'spam', 'other')),
+    note TEXT NOT NULL DEFAULT '',            -- Admin-only detail; never shown to clients
+    upstream_deleted BOOLEAN NOT NULL DEFAULT FALSE, -- Also deleted from a community repo we host
+    created_by_did TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    reversed_by_did TEXT,
+    reversed_at TIMESTAMPTZ
+);
+
+-- At most one active takedown per record; direct fetches look it up by subject
+CREATE UNIQUE INDEX idx_record_takedowns_active ON record_takedowns(subject_uri) WHERE reversed_at IS NULL;
+CREATE INDEX idx_record_takedowns_subject ON record_takedowns(subject_uri, created_at DESC);
+
+COMMENT ON COLUMN posts.taken_down_at IS 'Set while an admin takedown is in effect; the post is hidden everywhere';
+COMMENT ON COLUMN comments.taken_down_at IS 'Set while an admin takedown is in effect; the comment is hidden everywhere';
+COMMENT ON TABLE record_takedowns IS 'Audit trail of admin takedowns and their reversals';
+
+-- +goose Down
+DROP TABLE IF EXISTS record_takedowns;
+ALTER TABLE comments DROP COLUMN IF EXISTS taken_down_at;
+ALTER TABLE posts DROP COLUMN IF EXISTS taken_down_at;
@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Composite indexes matching the comment and subscription listing keyset cursors
+-- These listings used to page with OFFSET, which re-reads every earlier row on deep pages and
+-- repeats or skips rows inserted between fetches. They now page with row comparisons like
+-- (created_at, id) > ($3, $4), which only become index range scans when an index covers
+-- every column of the key in the same order (see 050_add_feed_keyset_indexes.sql).
+
+-- Thread listing by root (oldest first); supersedes idx_comments_root
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_comments_root_created_id
+ON comments(root_uri, created_at, id)
+WHERE deleted_at IS NULL;
+
+-- Direct replies by parent (oldest first); supersedes idx_comments_parent
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_comments_parent_created_id
+ON comments(parent_uri, created_at, id)
+WHERE deleted_at IS NULL;
+
+-- Profile comment history (newest first)
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_comments_commenter_created_uri
+ON comments(commenter_did, created_at DESC, uri DESC)
+WHERE deleted_at IS NULL;
+
+-- A user's subscriptions and a community's subscribers (newest first)
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_subscriptions_user_subscribed_id
+ON community_subscriptions(user_did, subscribed_at DESC, id DESC);
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_subscriptions_community_subscribed_id
+ON community_subscriptions(community_did, subscribed_at DESC, id DESC);
+
+DROP INDEX CONCURRENTLY IF EXISTS idx_comments_root;
+DROP INDEX CONCURRENTLY IF EXISTS idx_comments_parent;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_comments_root
+ON comments(root_uri, created_at DESC)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_comments_parent
+ON comments(parent_uri, created_at DESC)
+WHERE deleted_at IS NULL;
+
+DROP INDEX CONCURRENTLY IF EXISTS idx_subscriptions_community_subscribed_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_subscriptions_user_subscribed_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_comments_commenter_created_uri;
+DROP INDEX CONCURRENTLY IF EXISTS idx_comments_parent_created_id;
+DROP INDEX CONCURRENTLY IF EXISTS idx_comments_root_created_id;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Authors indexed on demand from their posts and comments
+-- The post and comment consumers create a minimal users row (DID, handle, PDS) for authors the
+-- user consumer hasn't seen, flagged profile_fetched = false. The profile backfill fetches
+-- their profile record from the PDS and clears the flag. Existing users already got their
+-- profile from Jetstream profile events.
+ALTER TABLE users ADD COLUMN profile_fetched BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- The profile backfill sweeps pending users, oldest first
+CREATE INDEX idx_users_profile_pending ON users(created_at) WHERE NOT profile_fetched;
+
+COMMENT ON COLUMN users.profile_fetched IS 'False for users indexed from a post or comment until their profile is backfilled from the PDS';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_users_profile_pending;
+ALTER TABLE users DROP COLUMN IF EXISTS profile_fetched;
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +coves:destructive
+-- Per-community auto-moderation (internal/core/automod)
+-- Moderators configure keyword, domain and regex-lite rules; the post and comment consumers
+-- check new content against them at index time and remove it, hold it for review, or flag it.
+CREATE TABLE community_automod_rules (
+    community_did TEXT PRIMARY KEY REFERENCES communities(did) ON DELETE CASCADE,
+    rules JSONB NOT NULL DEFAULT '[]',     -- [{pattern, matchType, action}], at most 50
+    updated_by TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Audit trail of actions automod took; open rows (holds and flags) are the moderation queue
+CREATE TABLE automod_actions (
+    id BIGSERIAL PRIMARY KEY,
+    subject_uri TEXT NOT NULL UNIQUE,       -- Post or comment AT-URI
+    community_did TEXT NOT NULL REFERENCES communities(did) ON DELETE CASCADE,
+    author_did TEXT NOT NULL,
+    action TEXT NOT NULL CHECK (action IN ('remove', 'hold', 'flag')),
+    match_type TEXT NOT NULL,
+    pattern TEXT NOT NULL,                  -- The rule's pattern when it matched
+    status TEXT NOT NULL CHECK (status IN ('open', 'approved', 'removed')),
+    reviewed_by_did TEXT,
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The moderation queue lists a community's open actions, newest first
+CREATE INDEX idx_automod_actions_open ON automod_actions(community_did, created_at DESC) WHERE status = 'open';
+
+-- Held content is hidden until approved; removed content stays hidden
+-- Both are indexed with visibility_state = 'removed' and don't count toward thread totals.
+ALTER TABLE posts DROP CONSTRAINT posts_status_check;
+ALTER TABLE posts ADD CONSTRAINT posts_status_check
+    CHECK (status IN ('active', 'quarantined', 'spam', 'held', 'removed'));
+ALTER TABLE comments DROP CONSTRAINT comments_status_check;
+ALTER TABLE comments ADD CONSTRAINT comments_status_check
+    CHECK (status IN ('active', 'rejected', 'held', 'removed'));
+
+COMMENT ON COLUMN posts.status IS 'active, quarantined (held for spam review; hidden), spam (confirmed by an admin; hidden), held (held by automod for moderator review; hidden) or removed (removed by automod; hidden)';
+COMMENT ON COLUMN comments.status IS 'active, rejected (created on a locked post), held (held by automod for moderator review) or removed (removed by automod); all but active are hidden';
+
+-- +goose Down
+UPDATE comments SET status = 'rejected' WHERE status IN ('held', 'removed');
+UPDATE posts SET status = 'spam' WHERE status IN ('held', 'removed');
+ALTER TABLE comments DROP CONSTRAINT comments_status_check;
+ALTER TABLE comments ADD CONSTRAINT comments_status_check CHECK (status IN ('active', 'rejected'));
+ALTER TABLE posts DROP CONSTRAINT posts_status_check;
+ALTER TABLE posts ADD CONSTRAINT posts_status_check CHECK (status IN ('active', 'quarantined', 'spam'));
+DROP TABLE IF EXISTS automod_actions;
+DROP TABLE IF EXISTS community_automod_rules;
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +coves:destructive
+-- Per-community aggregator post limits from the authorization record
+-- ValidateAggregatorPost enforces both over rolling windows; NULL hourly means the default
+-- (10 posts an hour), NULL daily means no daily limit.
+ALTER TABLE aggregator_authorizations
+    ADD COLUMN max_posts_per_hour INTEGER CHECK (max_posts_per_hour > 0),
+    ADD COLUMN max_posts_per_day INTEGER CHECK (max_posts_per_day > 0);
+
+-- Authorizations indexed before this migration keep their limits in raw_record
+UPDATE aggregator_authorizations
+SET max_posts_per_hour = CASE WHEN raw_record->>'maxPostsPerHour' ~ '^[1-9][0-9]{0,8}$'
+        THEN (raw_record->>'maxPostsPerHour')::INTEGER END,
+    max_posts_per_day = CASE WHEN raw_record->>'maxPostsPerDay' ~ '^[1-9][0-9]{0,8}$'
+        THEN (raw_record->>'maxPostsPerDay')::INTEGER END
+WHERE raw_record ? 'maxPostsPerHour' OR raw_record ? 'maxPostsPerDay';
+
+COMMENT ON COLUMN aggregator_authorizations.max_posts_per_hour IS 'Most posts the aggregator may make in the community in any rolling hour; NULL for the default of 10';
+COMMENT ON COLUMN aggregator_authorizations.max_posts_per_day IS 'Most posts the aggregator may make in the community in any rolling 24 hours; NULL for no daily limit';
+
+-- +goose Down
+ALTER TABLE aggregator_authorizations DROP COLUMN IF EXISTS max_posts_per_day;
+ALTER TABLE aggregator_authorizations DROP COLUMN IF EXISTS max_posts_per_hour;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Precomputed hot score for hot feeds
+-- Hot sort used to compute (score + 1) / (age_hours + 2)^1.5 for every candidate row on every
+-- request, which no index can serve. hot_score stores the same formula (hotrank.Score): the
+-- vote consumer rescores a post when its votes change and the ranker job rescores the last
+-- 7 days every few minutes. Older posts score 0.
+-- The ranker fills the column when the server starts; until then hot feeds fall back to
+-- created_at order through the index tiebreakers.
+ALTER TABLE posts ADD COLUMN IF NOT EXISTS hot_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN posts.hot_score IS 'Hot rank maintained by the vote consumer and ranker job (hotrank.Score); 0 once a post is older than 7 days';
+
+-- Discover "hot" (all communities)
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_hot_score
+ON posts(hot_score DESC, created_at DESC, uri DESC)
+WHERE deleted_at IS NULL;
+
+-- Community feed "hot"
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_community_hot_score
+ON posts(community_did, hot_score DESC, created_at DESC, uri DESC)
+WHERE deleted_at IS NULL;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+DROP INDEX CONCURRENTLY IF EXISTS idx_posts_community_hot_score;
+DROP INDEX CONCURRENTLY IF EXISTS idx_posts_hot_score;
+ALTER TABLE posts DROP COLUMN IF EXISTS hot_score;
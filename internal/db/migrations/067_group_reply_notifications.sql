@@ -0,0 +1,58 @@
+-- +goose Up
+-- +coves:destructive
+-- Reply notifications, grouped per parent per hour
+-- Replies to the same post or comment within an hour bucket collapse into one row: the comment
+-- consumer upserts on (recipient, reason, subject_parent_uri, bucket_start), bumping reply_count
+-- and moving latest_actor_did, subject_uri and updated_at to the newest reply. Mentions and
+-- brigade alerts stay one row per record (subject_parent_uri NULL).
+ALTER TABLE notifications
+    ADD COLUMN subject_parent_uri TEXT,
+    ADD COLUMN bucket_start TIMESTAMPTZ,
+    ADD COLUMN reply_count INTEGER NOT NULL DEFAULT 1,
+    ADD COLUMN latest_actor_did TEXT,
+    ADD COLUMN updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+UPDATE notifications SET updated_at = created_at;
+
+ALTER TABLE notifications ADD CONSTRAINT grouped_notification_bucket
+    CHECK ((subject_parent_uri IS NULL) = (bucket_start IS NULL));
+
+ALTER TABLE notifications DROP CONSTRAINT valid_notification_reason;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_reason CHECK (reason IN ('mention', 'brigade_alert', 'reply'));
+
+-- One notification per recipient per record for ungrouped reasons, one per group for replies
+ALTER TABLE notifications DROP CONSTRAINT unique_notification;
+CREATE UNIQUE INDEX unique_notification ON notifications(recipient_did, reason, subject_uri)
+    WHERE subject_parent_uri IS NULL;
+CREATE UNIQUE INDEX unique_notification_group ON notifications(recipient_did, reason, subject_parent_uri, bucket_start)
+    WHERE subject_parent_uri IS NOT NULL;
+
+-- listNotifications orders by the group's latest activity
+DROP INDEX IF EXISTS idx_notifications_recipient;
+CREATE INDEX idx_notifications_recipient ON notifications(recipient_did, updated_at DESC, id DESC);
+
+COMMENT ON COLUMN notifications.subject_parent_uri IS 'For grouped reply notifications, the post or comment replied to; NULL for ungrouped reasons';
+COMMENT ON COLUMN notifications.bucket_start IS 'Start of the hour the grouped replies were created in';
+COMMENT ON COLUMN notifications.reply_count IS 'Replies collapsed into this notification';
+COMMENT ON COLUMN notifications.latest_actor_did IS 'Author of the newest reply in the group';
+
+-- +goose Down
+DELETE FROM notifications WHERE reason = 'reply';
+
+DROP INDEX IF EXISTS idx_notifications_recipient;
+CREATE INDEX idx_notifications_recipient ON notifications(recipient_did, created_at DESC);
+
+DROP INDEX IF EXISTS unique_notification_group;
+DROP INDEX IF EXISTS unique_notification;
+ALTER TABLE notifications ADD CONSTRAINT unique_notification UNIQUE (recipient_did, reason, subject_uri);
+
+ALTER TABLE notifications DROP CONSTRAINT valid_notification_reason;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_reason CHECK (reason IN ('mention', 'brigade_alert'));
+
+ALTER TABLE notifications DROP CONSTRAINT grouped_notification_bucket;
+ALTER TABLE notifications
+    DROP COLUMN updated_at,
+    DROP COLUMN latest_actor_did,
+    DROP COLUMN reply_count,
+    DROP COLUMN bucket_start,
+    DROP COLUMN subject_parent_uri;
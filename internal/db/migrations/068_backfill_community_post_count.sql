@@ -0,0 +1,23 @@
+-- +goose Up
+-- +coves:destructive
+-- communities.post_count has existed since 005 but was never maintained
+-- The post consumer now increments it when a post is indexed and SoftDelete decrements it;
+-- backfill from live posts so existing communities start from the right number
+UPDATE communities c
+SET post_count = actual.post_count
+FROM (
+    SELECT c2.did, COUNT(p.id) AS post_count
+    FROM communities c2
+    LEFT JOIN posts p ON p.community_did = c2.did AND p.deleted_at IS NULL
+    GROUP BY c2.did
+) actual
+WHERE c.did = actual.did AND c.post_count IS DISTINCT FROM actual.post_count;
+
+ALTER TABLE communities ALTER COLUMN post_count SET DEFAULT 0;
+ALTER TABLE communities ALTER COLUMN post_count SET NOT NULL;
+
+COMMENT ON COLUMN communities.post_count IS 'Live (not deleted) posts in the community, maintained by the post consumer';
+
+-- +goose Down
+ALTER TABLE communities ALTER COLUMN post_count DROP NOT NULL;
+COMMENT ON COLUMN communities.post_count IS NULL;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Remote content retention (REMOTE_CONTENT_RETENTION_DAYS) hard-deletes old remote posts with
+-- every comment in their threads and every vote on them, soft-deleted rows included. The
+-- existing root and subject indexes only cover live rows, so deleting a thread would scan
+-- the whole table.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_comments_root_all ON comments(root_uri);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_votes_subject_all ON votes(subject_uri);
+
+-- +goose Down
+-- +goose NO TRANSACTION
+DROP INDEX CONCURRENTLY IF EXISTS idx_votes_subject_all;
+DROP INDEX CONCURRENTLY IF EXISTS idx_comments_root_all;
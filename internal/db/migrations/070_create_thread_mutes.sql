@@ -0,0 +1,17 @@
+-- +goose Up
+-- Threads users muted with social.coves.actor.muteThread
+-- The comment consumer skips reply and mention notifications whose thread root the recipient
+-- muted; notifications created before the mute are kept. Mutes are local to this instance for
+-- now (no PDS record).
+CREATE TABLE thread_mutes (
+    user_did TEXT NOT NULL REFERENCES users(did) ON DELETE CASCADE,
+    root_uri TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_did, root_uri)
+);
+
+COMMENT ON TABLE thread_mutes IS 'Post threads a user stopped receiving reply and mention notifications for';
+COMMENT ON COLUMN thread_mutes.root_uri IS 'AT-URI of the muted thread''s root post, as stored in comments.root_uri';
+
+-- +goose Down
+DROP TABLE IF EXISTS thread_mutes;
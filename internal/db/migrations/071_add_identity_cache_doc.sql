@@ -0,0 +1,12 @@
+-- +goose Up
+-- Shared identity cache (L2 behind each process's in-memory L1)
+-- The resolver now keeps the DID document with each cached identity, so ResolveDID is answered
+-- from the cache instead of going back to PLC, and serves rows up to IDENTITY_CACHE_MAX_STALE
+-- past expires_at while one refresh runs. Rows cached before this migration have no document
+-- and fall back to one built from pds_url.
+ALTER TABLE identity_cache ADD COLUMN doc JSONB;
+
+COMMENT ON COLUMN identity_cache.doc IS 'The DID document the identity was resolved from (id and services); NULL for older rows';
+
+-- +goose Down
+ALTER TABLE identity_cache DROP COLUMN IF EXISTS doc;
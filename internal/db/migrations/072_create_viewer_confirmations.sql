@@ -0,0 +1,15 @@
+-- +goose Up
+-- One-time "I'm over 18" confirmations for NSFW communities (social.coves.community.confirmAge)
+-- community.get, the community feed and getPost withhold an NSFW community's posts from
+-- signed-in viewers without a row here; anonymous viewers are always gated.
+CREATE TABLE viewer_confirmations (
+    user_did TEXT NOT NULL REFERENCES users(did) ON DELETE CASCADE,
+    community_did TEXT NOT NULL REFERENCES communities(did) ON DELETE CASCADE,
+    confirmed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_did, community_did)
+);
+
+COMMENT ON TABLE viewer_confirmations IS 'Viewers who confirmed their age for an age-restricted (NSFW) community';
+
+-- +goose Down
+DROP TABLE IF EXISTS viewer_confirmations;
@@ -0,0 +1,37 @@
+-- +goose Up
+-- Languages detected at index time for posts and comments whose record has no langs
+-- The consumers run translation.DetectLanguage over the title and content and store the result
+-- only when it is reliable (confidence >= translation.MinConfidence); records with langs keep
+-- these NULL. Feeds report the record's first langs entry, else detected_lang, as the post's
+-- language.
+ALTER TABLE posts
+    ADD COLUMN detected_lang TEXT,
+    ADD COLUMN detected_lang_confidence REAL;
+
+ALTER TABLE comments
+    ADD COLUMN detected_lang TEXT,
+    ADD COLUMN detected_lang_confidence REAL;
+
+COMMENT ON COLUMN posts.detected_lang IS 'ISO 639-1 language detected from the text when the record has no langs';
+COMMENT ON COLUMN comments.detected_lang IS 'ISO 639-1 language detected from the text when the record has no langs';
+
+-- Machine translations served by social.coves.feed.translate
+-- Keyed by the subject's CID so an edit (which changes the CID) never serves the old text's
+-- translation; caching a translation of the new version drops the stale ones.
+CREATE TABLE translations (
+    subject_uri TEXT NOT NULL,
+    subject_cid TEXT NOT NULL,
+    target_lang TEXT NOT NULL,
+    source_lang TEXT,
+    title TEXT,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subject_uri, subject_cid, target_lang)
+);
+
+COMMENT ON TABLE translations IS 'Cached machine translations of post and comment versions';
+
+-- +goose Down
+DROP TABLE IF EXISTS translations;
+ALTER TABLE comments DROP COLUMN IF EXISTS detected_lang_confidence, DROP COLUMN IF EXISTS detected_lang;
+ALTER TABLE posts DROP COLUMN IF EXISTS detected_lang_confidence, DROP COLUMN IF EXISTS detected_lang;
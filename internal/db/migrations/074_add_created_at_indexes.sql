@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Recent comment counts for social.coves.instance.getStats scan live rows by creation time
+-- across all communities; posts already have idx_posts_created_uri (migration 050)
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_comments_created
+ON comments(created_at DESC)
+WHERE deleted_at IS NULL;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+DROP INDEX CONCURRENTLY IF EXISTS idx_comments_created;
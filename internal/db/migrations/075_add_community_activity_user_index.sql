@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Community suggestions look up where a set of followed users were active, which the
+-- (community_did, day, user_did) primary key can't serve
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_community_activity_user
+ON community_activity(user_did, day);
+
+-- +goose Down
+-- +goose NO TRANSACTION
+DROP INDEX CONCURRENTLY IF EXISTS idx_community_activity_user;
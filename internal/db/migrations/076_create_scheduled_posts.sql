@@ -0,0 +1,48 @@
+-- +goose Up
+-- +coves:destructive
+-- Posts community moderators schedule for later publication (social.coves.community.schedulePost)
+-- The publisher job claims due rows every minute and writes the post to the community's repo
+-- with the community's stored credentials, so it's indexed through the firehose like any other
+-- post. Weekly posts move publish_at on a week after each publication; failed publications
+-- retry with backoff from next_attempt_at, and moderators are notified after repeated failures.
+CREATE TABLE scheduled_posts (
+    id BIGSERIAL PRIMARY KEY,
+    community_did TEXT NOT NULL REFERENCES communities(did) ON DELETE CASCADE,
+    created_by_did TEXT NOT NULL,              -- Moderator who scheduled it; the published post's author
+    payload JSONB NOT NULL,                    -- Title, content, embed, labels, facets and tags
+    publish_at TIMESTAMPTZ NOT NULL,           -- Next (or only) publication
+    next_attempt_at TIMESTAMPTZ NOT NULL,      -- publish_at, or later while retrying or claimed
+    recurrence TEXT NOT NULL DEFAULT 'none' CHECK (recurrence IN ('none', 'weekly')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'published', 'cancelled', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,       -- Failed attempts at the current publication
+    last_error TEXT,
+    last_published_uri TEXT,
+    last_published_at TIMESTAMPTZ,
+    cancelled_by_did TEXT,
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- The publisher claims pending posts by next attempt
+CREATE INDEX idx_scheduled_posts_due ON scheduled_posts(next_attempt_at) WHERE status = 'pending';
+
+-- listScheduledPosts lists a community's pending and failed posts by publication time
+CREATE INDEX idx_scheduled_posts_community ON scheduled_posts(community_did, publish_at)
+    WHERE status IN ('pending', 'failed');
+
+-- Moderators are notified of publications that keep failing
+ALTER TABLE notifications DROP CONSTRAINT valid_notification_reason;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_reason
+    CHECK (reason IN ('mention', 'brigade_alert', 'reply', 'scheduled_post_failed'));
+
+COMMENT ON TABLE scheduled_posts IS 'Posts scheduled by community moderators, published to the community repo when due';
+COMMENT ON COLUMN scheduled_posts.payload IS 'Author-controlled post record fields; community, author and createdAt are set at publication';
+
+-- +goose Down
+DELETE FROM notifications WHERE reason = 'scheduled_post_failed';
+ALTER TABLE notifications DROP CONSTRAINT valid_notification_reason;
+ALTER TABLE notifications ADD CONSTRAINT valid_notification_reason CHECK (reason IN ('mention', 'brigade_alert', 'reply'));
+DROP INDEX IF EXISTS idx_scheduled_posts_community;
+DROP INDEX IF EXISTS idx_scheduled_posts_due;
+DROP TABLE IF EXISTS scheduled_posts;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Unsent comments saved with social.coves.actor.putDraft, so a crashed tab doesn't lose them
+-- One draft per user and context (the post or comment being replied to). createComment
+-- discards the draft for its parent once the comment is written; drafts not saved for 30
+-- days are pruned by the hourly cleanup job.
+CREATE TABLE comment_drafts (
+    user_did TEXT NOT NULL REFERENCES users(did) ON DELETE CASCADE,
+    context_uri TEXT NOT NULL,
+    content TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_did, context_uri)
+);
+
+CREATE INDEX idx_comment_drafts_updated_at ON comment_drafts(updated_at);
+
+COMMENT ON TABLE comment_drafts IS 'Unsent comment drafts, one per user and reply context';
+COMMENT ON COLUMN comment_drafts.context_uri IS 'Canonical AT-URI of the post or comment the draft replies to';
+
+-- +goose Down
+DROP TABLE IF EXISTS comment_drafts;
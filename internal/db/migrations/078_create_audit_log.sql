@@ -0,0 +1,43 @@
+-- +goose Up
+-- Tamper-evident log of admin and moderator actions
+-- Each row stores the previous row's hash and a SHA-256 over its own contents and that hash
+-- (computed by audit.Hash), so editing or deleting a row breaks the chain at the next row.
+-- social.coves.admin.getAuditLog with verifyChain walks it. The trigger below rejects
+-- updates and deletes from the application role; the chain catches anyone who gets past it.
+CREATE TABLE audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_did TEXT NOT NULL,
+    action TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    details JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    prev_hash TEXT NOT NULL,
+    row_hash TEXT NOT NULL UNIQUE
+);
+
+CREATE INDEX idx_audit_log_actor ON audit_log(actor_did, id DESC);
+CREATE INDEX idx_audit_log_action ON audit_log(action, id DESC);
+CREATE INDEX idx_audit_log_created_at ON audit_log(created_at);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION reject_audit_log_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER trigger_audit_log_append_only
+    BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW
+    EXECUTE FUNCTION reject_audit_log_change();
+
+COMMENT ON TABLE audit_log IS 'Hash-chained, append-only log of admin and moderator actions';
+COMMENT ON COLUMN audit_log.action IS 'NSID of the XRPC procedure that performed the action';
+COMMENT ON COLUMN audit_log.prev_hash IS 'row_hash of the previous row, empty for the first row';
+
+-- +goose Down
+DROP TRIGGER IF EXISTS trigger_audit_log_append_only ON audit_log;
+DROP FUNCTION IF EXISTS reject_audit_log_change();
+DROP TABLE IF EXISTS audit_log;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Repo revision (TID) of the firehose commit that last wrote each community profile and comment
+-- Jetstream can deliver a record's updates out of order (reconnects, replays); the consumers
+-- skip any update whose rev is not newer than the stored one, so an older profile can't
+-- overwrite a newer one. NULL for rows indexed before this migration: the first update
+-- after deploy is always applied.
+ALTER TABLE communities ADD COLUMN rev TEXT;
+ALTER TABLE comments ADD COLUMN rev TEXT;
+
+COMMENT ON COLUMN communities.rev IS 'Repo revision of the commit that last wrote the profile record';
+COMMENT ON COLUMN comments.rev IS 'Repo revision of the commit that last wrote the comment record';
+
+-- +goose Down
+ALTER TABLE comments DROP COLUMN IF EXISTS rev;
+ALTER TABLE communities DROP COLUMN IF EXISTS rev;
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +coves:destructive
+-- UTS #39 skeletons of user and community handles, for spotting lookalike handles
+-- (a Cyrillic 'о' in an otherwise Latin handle). A handle whose skeleton matches another
+-- account's handle is flagged with confusable_with = that account's DID; hydrated author
+-- views carry the flag so clients can warn. Handles that are their own skeleton (plain
+-- ASCII, no lookalikes) are never flagged.
+--
+-- The skeleton of a handle without punycode labels is the handle itself, so existing rows
+-- are backfilled here; handles with xn-- labels get theirs when next indexed.
+ALTER TABLE users
+    ADD COLUMN handle_skeleton TEXT,
+    ADD COLUMN confusable_with TEXT;
+ALTER TABLE communities
+    ADD COLUMN handle_skeleton TEXT,
+    ADD COLUMN confusable_with TEXT;
+
+UPDATE users SET handle_skeleton = LOWER(handle) WHERE handle NOT LIKE '%xn--%';
+UPDATE communities SET handle_skeleton = LOWER(handle) WHERE handle NOT LIKE '%xn--%';
+
+CREATE INDEX idx_users_handle_skeleton ON users(handle_skeleton);
+CREATE INDEX idx_communities_handle_skeleton ON communities(handle_skeleton);
+CREATE INDEX idx_users_confusable ON users(updated_at DESC) WHERE confusable_with IS NOT NULL;
+CREATE INDEX idx_communities_confusable ON communities(updated_at DESC) WHERE confusable_with IS NOT NULL;
+
+COMMENT ON COLUMN users.handle_skeleton IS 'UTS #39 skeleton of the handle, punycode labels decoded';
+COMMENT ON COLUMN users.confusable_with IS 'DID of the user whose handle this handle imitates';
+COMMENT ON COLUMN communities.handle_skeleton IS 'UTS #39 skeleton of the handle, punycode labels decoded';
+COMMENT ON COLUMN communities.confusable_with IS 'DID of the community whose handle this handle imitates';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_communities_confusable;
+DROP INDEX IF EXISTS idx_users_confusable;
+DROP INDEX IF EXISTS idx_communities_handle_skeleton;
+DROP INDEX IF EXISTS idx_users_handle_skeleton;
+ALTER TABLE communities
+    DROP COLUMN IF EXISTS confusable_with,
+    DROP COLUMN IF EXISTS handle_skeleton;
+ALTER TABLE users
+    DROP COLUMN IF EXISTS confusable_with,
+    DROP COLUMN IF EXISTS handle_skeleton;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Windows of firehose events a Jetstream consumer never saw
+-- Recorded when a connection resumes and its first event is further past the last event seen
+-- than the gap threshold. Times are Jetstream time_us (microseconds since the epoch). Feeds
+-- whose page overlaps a gap that isn't backfilled report it to clients.
+CREATE TABLE consumer_gaps (
+    id BIGSERIAL PRIMARY KEY,
+    consumer TEXT NOT NULL,
+    start_time_us BIGINT NOT NULL,
+    end_time_us BIGINT NOT NULL,
+    detected_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    backfilled_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT consumer_gaps_window CHECK (end_time_us > start_time_us)
+);
+
+CREATE INDEX idx_consumer_gaps_unfilled ON consumer_gaps(start_time_us, end_time_us)
+    WHERE backfilled_at IS NULL;
+
+COMMENT ON TABLE consumer_gaps IS 'Windows of firehose events missed by a Jetstream consumer';
+COMMENT ON COLUMN consumer_gaps.start_time_us IS 'time_us of the last event seen before the gap';
+COMMENT ON COLUMN consumer_gaps.end_time_us IS 'time_us of the first event seen after the gap';
+COMMENT ON COLUMN consumer_gaps.backfilled_at IS 'When an admin marked the window backfilled; NULL while posts may be missing';
+
+-- +goose Down
+DROP TABLE IF EXISTS consumer_gaps;
@@ -0,0 +1,42 @@
+-- +goose Up
+-- Replay protection for every record the Jetstream consumers can update or delete
+-- Jetstream redelivers events when a connection resumes from an overlapping cursor, and an
+-- old update or delete arriving after a newer one must not regress the index. Each updatable
+-- table stores the repo rev (TID) of the commit that last wrote it, as communities.rev and
+-- comments.rev already do (079); writes with a rev that isn't newer are skipped. NULL for rows
+-- indexed before this migration: the next write is always applied.
+ALTER TABLE users ADD COLUMN profile_rev TEXT;
+ALTER TABLE user_preferences ADD COLUMN rev TEXT;
+ALTER TABLE community_rules ADD COLUMN rev TEXT;
+ALTER TABLE aggregators ADD COLUMN rev TEXT;
+ALTER TABLE aggregator_authorizations ADD COLUMN rev TEXT;
+
+COMMENT ON COLUMN users.profile_rev IS 'Repo revision of the commit that last wrote or deleted the profile record';
+COMMENT ON COLUMN user_preferences.rev IS 'Repo revision of the commit that last wrote the preferences record';
+COMMENT ON COLUMN community_rules.rev IS 'Repo revision of the commit that last wrote the rules record';
+COMMENT ON COLUMN aggregators.rev IS 'Repo revision of the commit that last wrote the service record';
+COMMENT ON COLUMN aggregator_authorizations.rev IS 'Repo revision of the commit that last wrote the authorization record';
+
+-- Revs of the commits that deleted hard-deleted records
+-- Once the row is gone there's nothing to compare a replayed create against, so the delete
+-- leaves its rev here: a create with an older rev is a replay and is skipped, while a create
+-- with a newer rev recreates the record. Soft-deleted rows (posts, comments, votes) keep their
+-- own rev instead. Pruned once older than anything Jetstream would replay.
+CREATE TABLE record_tombstones (
+    uri TEXT PRIMARY KEY,
+    rev TEXT NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_record_tombstones_deleted_at ON record_tombstones(deleted_at);
+
+COMMENT ON TABLE record_tombstones IS 'Repo revisions of the commits that deleted hard-deleted records';
+COMMENT ON COLUMN record_tombstones.rev IS 'Newest rev that deleted the record; older creates are replays';
+
+-- +goose Down
+DROP TABLE IF EXISTS record_tombstones;
+ALTER TABLE aggregator_authorizations DROP COLUMN IF EXISTS rev;
+ALTER TABLE aggregators DROP COLUMN IF EXISTS rev;
+ALTER TABLE community_rules DROP COLUMN IF EXISTS rev;
+ALTER TABLE user_preferences DROP COLUMN IF EXISTS rev;
+ALTER TABLE users DROP COLUMN IF EXISTS profile_rev;
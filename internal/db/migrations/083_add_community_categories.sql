@@ -0,0 +1,26 @@
+-- +goose Up
+-- +coves:destructive
+-- Categories a community lists in its profile record, for browsing by category
+-- Stored as written, including values outside the taxonomy in
+-- social.coves.community.defs#category: those browse and count as "other", and start counting
+-- under their own name if the taxonomy grows to include them.
+ALTER TABLE communities ADD COLUMN categories TEXT[] NOT NULL DEFAULT '{}';
+
+-- Profiles indexed before this migration already carry their categories in the raw record
+UPDATE communities
+SET categories = ARRAY(
+    SELECT DISTINCT category
+    FROM jsonb_array_elements_text(raw_record->'categories') AS category
+    WHERE category <> ''
+)
+WHERE jsonb_typeof(raw_record->'categories') = 'array'
+  AND jsonb_array_length(raw_record->'categories') BETWEEN 1 AND 3;
+
+-- Category filters are containment queries (categories @> ARRAY['gaming'])
+CREATE INDEX idx_communities_categories ON communities USING GIN (categories);
+
+COMMENT ON COLUMN communities.categories IS 'Categories from the profile record as written (at most 3); unknown values browse as other';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_communities_categories;
+ALTER TABLE communities DROP COLUMN IF EXISTS categories;
@@ -0,0 +1,21 @@
+-- +goose Up
+-- Community names held by creations in flight
+-- Two creations of the same name both pass the existence checks and both try to create the
+-- PDS account; the loser fails with a PDS error, possibly after partial work. CreateCommunity
+-- inserts the name here before provisioning, so the second creation fails fast with NameTaken,
+-- and deletes it when it finishes. Rows left by crashed requests expire and are reaped.
+-- Not to be confused with reserved_community_names, which admins reserve permanently.
+CREATE TABLE community_name_reservations (
+    name TEXT PRIMARY KEY CHECK (name = lower(name)),  -- Normalized like handles (NormalizeCommunityName)
+    token TEXT NOT NULL,
+    reserved_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+-- The reaper deletes expired reservations
+CREATE INDEX idx_community_name_reservations_expires ON community_name_reservations(expires_at);
+
+COMMENT ON COLUMN community_name_reservations.token IS 'Identifies the holding request, so a request whose reservation expired and was taken over cannot release the new holder''s';
+
+-- +goose Down
+DROP TABLE IF EXISTS community_name_reservations;
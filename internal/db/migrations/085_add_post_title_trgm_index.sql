@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Trigram index on post titles for the composer's duplicate check (social.coves.feed.checkDuplicate)
+-- The check filters with the % operator, which this index answers; pg_trgm was enabled in 005.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_title_trgm
+ON posts USING gin(title gin_trgm_ops)
+WHERE deleted_at IS NULL AND title IS NOT NULL;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+DROP INDEX CONCURRENTLY IF EXISTS idx_posts_title_trgm;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- Blob reference tracking for the image proxy
+-- Consumers record which blobs each indexed post, comment and profile references. When a
+-- takedown, moderator removal or deletion leaves a blob without any live reference, it's added
+-- to blocked_blobs and the proxy refuses to serve it; reinstating the content unblocks it.
+CREATE TABLE blob_references (
+    blob_cid TEXT NOT NULL,
+    record_uri TEXT NOT NULL,
+    PRIMARY KEY (blob_cid, record_uri)
+);
+
+-- Records replace or refresh their references by URI
+CREATE INDEX idx_blob_references_record ON blob_references(record_uri);
+
+CREATE TABLE blocked_blobs (
+    cid TEXT PRIMARY KEY,
+    blocked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE blob_references IS 'Blobs referenced by indexed posts, comments and profiles';
+COMMENT ON TABLE blocked_blobs IS 'Blobs whose every reference is taken down, removed or deleted; the image proxy refuses them';
+
+-- +goose Down
+DROP TABLE IF EXISTS blocked_blobs;
+DROP TABLE IF EXISTS blob_references;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Creations are claimed before the community's account is provisioned, so the rate limit
+-- counts requests in flight; community_did stays NULL until the creation finishes.
+-- Failed creations delete their claim; a NULL left behind is a creation that crashed.
+ALTER TABLE community_creations ALTER COLUMN community_did DROP NOT NULL;
+
+COMMENT ON COLUMN community_creations.community_did IS 'Created community; NULL while the creation is in flight';
+
+-- +goose Down
+DELETE FROM community_creations WHERE community_did IS NULL;
+ALTER TABLE community_creations ALTER COLUMN community_did SET NOT NULL;
+COMMENT ON COLUMN community_creations.community_did IS NULL;
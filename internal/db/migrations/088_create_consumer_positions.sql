@@ -0,0 +1,17 @@
+-- +goose Up
+-- Where each Jetstream consumer was in the firehose, saved every few seconds while connected
+-- A restart resumes from last_seen_time_us, and a gap is recorded when it can't replay that
+-- far back or, for consumers without a replay cursor, when it was down past the gap threshold.
+CREATE TABLE consumer_positions (
+    consumer TEXT PRIMARY KEY,
+    last_seen_time_us BIGINT NOT NULL,
+    connected_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE consumer_positions IS 'Last saved firehose position of each Jetstream consumer';
+COMMENT ON COLUMN consumer_positions.last_seen_time_us IS 'time_us of the newest event the consumer saw';
+COMMENT ON COLUMN consumer_positions.connected_at IS 'Last time the consumer''s connection was known to be up';
+
+-- +goose Down
+DROP TABLE IF EXISTS consumer_positions;
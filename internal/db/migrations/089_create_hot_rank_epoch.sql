@@ -0,0 +1,17 @@
+-- +goose Up
+-- The clock posts.hot_score was last computed against
+-- The ranker rescores every recent post against one clock and publishes it here in the same
+-- transaction; votes and new posts score against it too. Hot cursors carry the epoch they were
+-- issued under, so a later page can tell whether the stored scores still match it or it has to
+-- rank against the cursor's epoch instead.
+CREATE TABLE hot_rank_epoch (
+    singleton BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (singleton),
+    scored_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+INSERT INTO hot_rank_epoch (scored_at) VALUES (NOW());
+
+COMMENT ON TABLE hot_rank_epoch IS 'Single row: the clock the current posts.hot_score values were computed against';
+
+-- +goose Down
+DROP TABLE IF EXISTS hot_rank_epoch;
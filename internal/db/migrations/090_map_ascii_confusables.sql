@@ -0,0 +1,20 @@
+-- +goose Up
+-- +coves:destructive
+-- Handle skeletons now map the ASCII lookalikes in confusables.txt too ('0' to 'o', '1' to
+-- 'l', 'm' to "rn", 'w' to "vv"), so "c0ves.social" and "coves.social" share a skeleton.
+-- Every stored skeleton only lacks those mappings, so they're applied here in SQL. Existing
+-- handles that now collide are flagged when they're next indexed.
+UPDATE users
+SET handle_skeleton = REPLACE(REPLACE(REPLACE(REPLACE(handle_skeleton, 'm', 'rn'), 'w', 'vv'), '0', 'o'), '1', 'l')
+WHERE handle_skeleton ~ '[mw01]';
+
+UPDATE communities
+SET handle_skeleton = REPLACE(REPLACE(REPLACE(REPLACE(handle_skeleton, 'm', 'rn'), 'w', 'vv'), '0', 'o'), '1', 'l')
+WHERE handle_skeleton ~ '[mw01]';
+
+-- +goose Down
+-- Skeletons can't be unmapped ("rn" may have been 'm'); recompute them from the handles
+UPDATE users SET handle_skeleton = LOWER(handle) WHERE handle NOT LIKE '%xn--%';
+UPDATE users SET handle_skeleton = NULL WHERE handle LIKE '%xn--%';
+UPDATE communities SET handle_skeleton = LOWER(handle) WHERE handle NOT LIKE '%xn--%';
+UPDATE communities SET handle_skeleton = NULL WHERE handle LIKE '%xn--%';
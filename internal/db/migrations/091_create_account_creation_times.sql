@@ -0,0 +1,15 @@
+-- +goose Up
+-- When accounts were created, from their identity history (the PLC audit log for did:plc)
+-- The spam guard measures account age from these instead of from when this AppView first
+-- indexed the user. A creation time never changes, so each is looked up once.
+CREATE TABLE account_creation_times (
+    did TEXT PRIMARY KEY,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE account_creation_times IS 'Account creation times from identity history, cached for the spam guard';
+COMMENT ON COLUMN account_creation_times.created_at IS 'When the account''s genesis operation was accepted by its PLC directory';
+
+-- +goose Down
+DROP TABLE IF EXISTS account_creation_times;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose NO TRANSACTION
+-- Migration 074 used to create idx_posts_created, which duplicates the leading column of
+-- idx_posts_created_uri (migration 050) and only added write overhead. 074 no longer creates it;
+-- this drops it from databases that already ran the old version.
+DROP INDEX CONCURRENTLY IF EXISTS idx_posts_created;
+
+-- +goose Down
+-- +goose NO TRANSACTION
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_posts_created
+ON posts(created_at DESC)
+WHERE deleted_at IS NULL;
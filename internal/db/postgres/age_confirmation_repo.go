@@ -0,0 +1,42 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type postgresAgeConfirmationRepo struct {
+	db *sql.DB
+}
+
+// NewAgeConfirmationRepository creates a PostgreSQL repository for community age confirmations
+func NewAgeConfirmationRepository(db *sql.DB) communities.AgeConfirmationRepository {
+	return &postgresAgeConfirmationRepo{db: db}
+}
+
+// ConfirmAge records the confirmation, keeping the original confirmed_at when already confirmed
+func (r *postgresAgeConfirmationRepo) ConfirmAge(ctx context.Context, userDID, communityDID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO viewer_confirmations (user_did, community_did)
+		VALUES ($1, $2)
+		ON CONFLICT (user_did, community_did) DO NOTHING`,
+		userDID, communityDID)
+	if err != nil {
+		return fmt.Errorf("failed to record age confirmation for %s: %w", communityDID, err)
+	}
+	return nil
+}
+
+// HasConfirmedAge reports whether the user confirmed their age for the community
+func (r *postgresAgeConfirmationRepo) HasConfirmedAge(ctx context.Context, userDID, communityDID string) (bool, error) {
+	var confirmed bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM viewer_confirmations WHERE user_did = $1 AND community_did = $2)`,
+		userDID, communityDID).Scan(&confirmed)
+	if err != nil {
+		return false, fmt.Errorf("failed to check age confirmation for %s: %w", communityDID, err)
+	}
+	return confirmed, nil
+}
@@ -21,13 +21,15 @@ func NewAggregatorRepository(db *sql.DB) aggregators.Repository {
 // ===== Aggregator CRUD Operations =====
 
 // CreateAggregator indexes a new aggregator service declaration from the firehose
+// Returns ErrStaleRevision, without writing, when agg.Rev is not newer than the indexed rev.
 func (r *postgresAggregatorRepo) CreateAggregator(ctx context.Context, agg *aggregators.Aggregator) error {
 	query := `
 		INSERT INTO aggregators (
 			did, display_name, description, avatar_url, config_schema,
-			maintainer_did, source_url, created_at, indexed_at, record_uri, record_cid
+			maintainer_did, source_url, created_at, indexed_at, record_uri, record_cid,
+			raw_record, rev
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NULLIF($13, '')
 		)
 		ON CONFLICT (did) DO UPDATE SET
 			display_name = EXCLUDED.display_name,
@@ -39,7 +41,10 @@ func (r *postgresAggregatorRepo) CreateAggregator(ctx context.Context, agg *aggr
 			created_at = EXCLUDED.created_at,
 			indexed_at = EXCLUDED.indexed_at,
 			record_uri = EXCLUDED.record_uri,
-			record_cid = EXCLUDED.record_cid`
+			record_cid = EXCLUDED.record_cid,
+			raw_record = EXCLUDED.raw_record,
+			rev = COALESCE(EXCLUDED.rev, aggregators.rev)
+		WHERE ` + revIsNewer("EXCLUDED.rev", "aggregators.rev")
 
 	var configSchema interface{}
 	if len(agg.ConfigSchema) > 0 {
@@ -48,7 +53,7 @@ func (r *postgresAggregatorRepo) CreateAggregator(ctx context.Context, agg *aggr
 		configSchema = nil
 	}
 
-	_, err := r.db.ExecContext(ctx, query,
+	result, err := r.db.ExecContext(ctx, query,
 		agg.DID,
 		agg.DisplayName,
 		nullString(agg.Description),
@@ -60,11 +65,21 @@ func (r *postgresAggregatorRepo) CreateAggregator(ctx context.Context, agg *aggr
 		agg.IndexedAt,
 		nullString(agg.RecordURI),
 		nullString(agg.RecordCID),
+		agg.RawRecord,
+		agg.Rev,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create aggregator: %w", err)
 	}
 
+	written, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if written == 0 {
+		return aggregators.ErrStaleRevision
+	}
+
 	return nil
 }
 
@@ -75,7 +90,7 @@ func (r *postgresAggregatorRepo) GetAggregator(ctx context.Context, did string)
 		SELECT
 			did, display_name, description, avatar_url, config_schema,
 			maintainer_did, source_url, communities_using, posts_created,
-			created_at, indexed_at, record_uri, record_cid
+			created_at, indexed_at, record_uri, record_cid, COALESCE(rev, '')
 		FROM aggregators
 		WHERE did = $1`
 
@@ -97,6 +112,7 @@ func (r *postgresAggregatorRepo) GetAggregator(ctx context.Context, did string)
 		&agg.IndexedAt,
 		&recordURI,
 		&recordCID,
+		&agg.Rev,
 	)
 
 	if err == sql.ErrNoRows {
@@ -345,14 +361,16 @@ func (r *postgresAggregatorRepo) IsAggregator(ctx context.Context, did string) (
 // ===== Authorization CRUD Operations =====
 
 // CreateAuthorization indexes a new authorization from the firehose
+// Returns ErrStaleRevision, without writing, when auth.Rev is not newer than the indexed rev.
 func (r *postgresAggregatorRepo) CreateAuthorization(ctx context.Context, auth *aggregators.Authorization) error {
 	query := `
 		INSERT INTO aggregator_authorizations (
 			aggregator_did, community_did, enabled, config,
 			created_at, created_by, disabled_at, disabled_by,
-			indexed_at, record_uri, record_cid
+			indexed_at, record_uri, record_cid, raw_record,
+			max_posts_per_hour, max_posts_per_day, rev
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, NULLIF($15, '')
 		)
 		ON CONFLICT (aggregator_did, community_did) DO UPDATE SET
 			enabled = EXCLUDED.enabled,
@@ -363,7 +381,12 @@ func (r *postgresAggregatorRepo) CreateAuthorization(ctx context.Context, auth *
 			disabled_by = EXCLUDED.disabled_by,
 			indexed_at = EXCLUDED.indexed_at,
 			record_uri = EXCLUDED.record_uri,
-			record_cid = EXCLUDED.record_cid
+			record_cid = EXCLUDED.record_cid,
+			raw_record = EXCLUDED.raw_record,
+			max_posts_per_hour = EXCLUDED.max_posts_per_hour,
+			max_posts_per_day = EXCLUDED.max_posts_per_day,
+			rev = COALESCE(EXCLUDED.rev, aggregator_authorizations.rev)
+		WHERE ` + revIsNewer("EXCLUDED.rev", "aggregator_authorizations.rev") + `
 		RETURNING id`
 
 	var config interface{}
@@ -392,10 +415,17 @@ func (r *postgresAggregatorRepo) CreateAuthorization(ctx context.Context, auth *
 		auth.IndexedAt,
 		nullString(auth.RecordURI),
 		nullString(auth.RecordCID),
+		auth.RawRecord,
+		auth.MaxPostsPerHour,
+		auth.MaxPostsPerDay,
+		auth.Rev,
 	).Scan(&auth.ID)
+	if err == sql.ErrNoRows {
+		// The conflicting row was written by a newer commit
+		return aggregators.ErrStaleRevision
+	}
 	if err != nil {
-		// Check for foreign key violations
-		if strings.Contains(err.Error(), "fk_aggregator") {
+		if constraint, ok := constraintViolation(err, pqForeignKeyViolation); ok && constraint == "fk_aggregator" {
 			return aggregators.ErrAggregatorNotFound
 		}
 		return fmt.Errorf("failed to create authorization: %w", err)
@@ -410,7 +440,7 @@ func (r *postgresAggregatorRepo) GetAuthorization(ctx context.Context, aggregato
 		SELECT
 			id, aggregator_did, community_did, enabled, config,
 			created_at, created_by, disabled_at, disabled_by,
-			indexed_at, record_uri, record_cid
+			indexed_at, record_uri, record_cid, max_posts_per_hour, max_posts_per_day
 		FROM aggregator_authorizations
 		WHERE aggregator_did = $1 AND community_did = $2`
 
@@ -418,6 +448,7 @@ func (r *postgresAggregatorRepo) GetAuthorization(ctx context.Context, aggregato
 	var config []byte
 	var createdBy, disabledBy, recordURI, recordCID sql.NullString
 	var disabledAt sql.NullTime
+	var maxPerHour, maxPerDay sql.NullInt64
 
 	err := r.db.QueryRowContext(ctx, query, aggregatorDID, communityDID).Scan(
 		&auth.ID,
@@ -432,6 +463,8 @@ func (r *postgresAggregatorRepo) GetAuthorization(ctx context.Context, aggregato
 		&auth.IndexedAt,
 		&recordURI,
 		&recordCID,
+		&maxPerHour,
+		&maxPerDay,
 	)
 
 	if err == sql.ErrNoRows {
@@ -450,6 +483,8 @@ func (r *postgresAggregatorRepo) GetAuthorization(ctx context.Context, aggregato
 	}
 	auth.RecordURI = recordURI.String
 	auth.RecordCID = recordCID.String
+	auth.MaxPostsPerHour = nullablePostLimit(maxPerHour)
+	auth.MaxPostsPerDay = nullablePostLimit(maxPerDay)
 	if config != nil {
 		auth.Config = config
 	}
@@ -463,7 +498,7 @@ func (r *postgresAggregatorRepo) GetAuthorizationByURI(ctx context.Context, reco
 		SELECT
 			id, aggregator_did, community_did, enabled, config,
 			created_at, created_by, disabled_at, disabled_by,
-			indexed_at, record_uri, record_cid
+			indexed_at, record_uri, record_cid, max_posts_per_hour, max_posts_per_day, COALESCE(rev, '')
 		FROM aggregator_authorizations
 		WHERE record_uri = $1`
 
@@ -471,6 +506,7 @@ func (r *postgresAggregatorRepo) GetAuthorizationByURI(ctx context.Context, reco
 	var config []byte
 	var createdBy, disabledBy, recordURIField, recordCID sql.NullString
 	var disabledAt sql.NullTime
+	var maxPerHour, maxPerDay sql.NullInt64
 
 	err := r.db.QueryRowContext(ctx, query, recordURI).Scan(
 		&auth.ID,
@@ -485,6 +521,9 @@ func (r *postgresAggregatorRepo) GetAuthorizationByURI(ctx context.Context, reco
 		&auth.IndexedAt,
 		&recordURIField,
 		&recordCID,
+		&maxPerHour,
+		&maxPerDay,
+		&auth.Rev,
 	)
 
 	if err == sql.ErrNoRows {
@@ -503,6 +542,8 @@ func (r *postgresAggregatorRepo) GetAuthorizationByURI(ctx context.Context, reco
 	}
 	auth.RecordURI = recordURIField.String
 	auth.RecordCID = recordCID.String
+	auth.MaxPostsPerHour = nullablePostLimit(maxPerHour)
+	auth.MaxPostsPerDay = nullablePostLimit(maxPerDay)
 	if config != nil {
 		auth.Config = config
 	}
@@ -522,7 +563,9 @@ func (r *postgresAggregatorRepo) UpdateAuthorization(ctx context.Context, auth *
 			disabled_by = $8,
 			indexed_at = $9,
 			record_uri = $10,
-			record_cid = $11
+			record_cid = $11,
+			max_posts_per_hour = $12,
+			max_posts_per_day = $13
 		WHERE aggregator_did = $1 AND community_did = $2`
 
 	var config interface{}
@@ -551,6 +594,8 @@ func (r *postgresAggregatorRepo) UpdateAuthorization(ctx context.Context, auth *
 		auth.IndexedAt,
 		nullString(auth.RecordURI),
 		nullString(auth.RecordCID),
+		auth.MaxPostsPerHour,
+		auth.MaxPostsPerDay,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update authorization: %w", err)
@@ -615,7 +660,7 @@ func (r *postgresAggregatorRepo) ListAuthorizationsForAggregator(ctx context.Con
 		SELECT
 			id, aggregator_did, community_did, enabled, config,
 			created_at, created_by, disabled_at, disabled_by,
-			indexed_at, record_uri, record_cid
+			indexed_at, record_uri, record_cid, max_posts_per_hour, max_posts_per_day
 		FROM aggregator_authorizations
 		WHERE aggregator_did = $1`
 
@@ -645,7 +690,7 @@ func (r *postgresAggregatorRepo) ListAuthorizationsForCommunity(ctx context.Cont
 		SELECT
 			id, aggregator_did, community_did, enabled, config,
 			created_at, created_by, disabled_at, disabled_by,
-			indexed_at, record_uri, record_cid
+			indexed_at, record_uri, record_cid, max_posts_per_hour, max_posts_per_day
 		FROM aggregator_authorizations
 		WHERE community_did = $1`
 
@@ -1283,6 +1328,7 @@ func scanAuthorizations(rows *sql.Rows) ([]*aggregators.Authorization, error) {
 		var config []byte
 		var createdBy, disabledBy, recordURI, recordCID sql.NullString
 		var disabledAt sql.NullTime
+		var maxPerHour, maxPerDay sql.NullInt64
 
 		err := rows.Scan(
 			&auth.ID,
@@ -1297,6 +1343,8 @@ func scanAuthorizations(rows *sql.Rows) ([]*aggregators.Authorization, error) {
 			&auth.IndexedAt,
 			&recordURI,
 			&recordCID,
+			&maxPerHour,
+			&maxPerDay,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan authorization: %w", err)
@@ -1311,6 +1359,8 @@ func scanAuthorizations(rows *sql.Rows) ([]*aggregators.Authorization, error) {
 		}
 		auth.RecordURI = recordURI.String
 		auth.RecordCID = recordCID.String
+		auth.MaxPostsPerHour = nullablePostLimit(maxPerHour)
+		auth.MaxPostsPerDay = nullablePostLimit(maxPerDay)
 		if config != nil {
 			auth.Config = config
 		}
@@ -1324,3 +1374,12 @@ func scanAuthorizations(rows *sql.Rows) ([]*aggregators.Authorization, error) {
 
 	return auths, nil
 }
+
+// nullablePostLimit maps an unset authorization post limit column to nil
+func nullablePostLimit(limit sql.NullInt64) *int {
+	if !limit.Valid {
+		return nil
+	}
+	n := int(limit.Int64)
+	return &n
+}
@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"Coves/internal/core/aggregators"
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+// directorySelect selects aggregators hydrated with their maintainer's handle and their PDS
+// Aggregators and maintainers are users; either may be missing from users, hence LEFT JOINs
+const directorySelect = `
+	SELECT
+		a.did, a.display_name, a.description, a.avatar_url, a.config_schema,
+		a.maintainer_did, a.source_url, a.communities_using, a.posts_created,
+		a.created_at, a.indexed_at, a.record_uri, a.record_cid,
+		m.handle, u.pds_url
+	FROM aggregators a
+	LEFT JOIN users m ON m.did = a.maintainer_did
+	LEFT JOIN users u ON u.did = a.did`
+
+// ListServices retrieves a page of the aggregator directory
+// DID breaks ties so offset pages are stable
+func (r *postgresAggregatorRepo) ListServices(ctx context.Context, limit, offset int) ([]*aggregators.Aggregator, error) {
+	query := directorySelect + `
+		ORDER BY a.communities_using DESC, a.display_name ASC, a.did ASC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list aggregator services: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var aggs []*aggregators.Aggregator
+	for rows.Next() {
+		agg, err := scanDirectoryAggregator(rows)
+		if err != nil {
+			return nil, err
+		}
+		aggs = append(aggs, agg)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating aggregator services: %w", err)
+	}
+
+	return aggs, nil
+}
+
+// GetService retrieves a single aggregator hydrated for the directory
+func (r *postgresAggregatorRepo) GetService(ctx context.Context, did string) (*aggregators.Aggregator, error) {
+	agg, err := scanDirectoryAggregator(r.db.QueryRowContext(ctx, directorySelect+` WHERE a.did = $1`, did))
+	if err == sql.ErrNoRows {
+		return nil, aggregators.ErrAggregatorNotFound
+	}
+	return agg, err
+}
+
+// GetServicesByDIDs is the batched IsAggregator check behind post attribution
+// DIDs that aren't aggregators are simply absent from the result
+func (r *postgresAggregatorRepo) GetServicesByDIDs(ctx context.Context, dids []string) ([]*aggregators.Aggregator, error) {
+	if len(dids) == 0 {
+		return []*aggregators.Aggregator{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, directorySelect+` WHERE a.did = ANY($1)`, pq.Array(dids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get aggregator services: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var aggs []*aggregators.Aggregator
+	for rows.Next() {
+		agg, err := scanDirectoryAggregator(rows)
+		if err != nil {
+			return nil, err
+		}
+		aggs = append(aggs, agg)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating aggregator services: %w", err)
+	}
+
+	return aggs, nil
+}
+
+// ListAuthorizedCommunities retrieves the communities with an enabled authorization for an aggregator
+// Private communities are left out: the directory is public
+func (r *postgresAggregatorRepo) ListAuthorizedCommunities(ctx context.Context, aggregatorDID string, limit int) ([]*aggregators.AuthorizedCommunity, error) {
+	query := `
+		SELECT c.did, c.handle, c.name, c.display_name, c.avatar_cid, c.pds_url, aa.created_at
+		FROM aggregator_authorizations aa
+		JOIN communities c ON c.did = aa.community_did
+		WHERE aa.aggregator_did = $1
+			AND aa.enabled = true
+			AND c.visibility != 'private'
+			AND c.federation_blocked = FALSE
+		ORDER BY c.subscriber_count DESC, c.did ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, aggregatorDID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list authorized communities: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []*aggregators.AuthorizedCommunity
+	for rows.Next() {
+		community := &aggregators.AuthorizedCommunity{}
+		var displayName, avatarCID, pdsURL sql.NullString
+		if err := rows.Scan(
+			&community.DID,
+			&community.Handle,
+			&community.Name,
+			&displayName,
+			&avatarCID,
+			&pdsURL,
+			&community.AuthorizedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan authorized community: %w", err)
+		}
+		community.DisplayName = displayName.String
+		community.AvatarCID = avatarCID.String
+		community.PDSURL = pdsURL.String
+		result = append(result, community)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating authorized communities: %w", err)
+	}
+
+	return result, nil
+}
+
+// scanDirectoryAggregator scans a directorySelect row
+// Returns sql.ErrNoRows unwrapped so single-row lookups can map it to not found
+func scanDirectoryAggregator(row interface{ Scan(...interface{}) error }) (*aggregators.Aggregator, error) {
+	agg := &aggregators.Aggregator{}
+	var description, avatarCID, maintainerDID, sourceURL, recordURI, recordCID sql.NullString
+	var maintainerHandle, pdsURL sql.NullString
+	var configSchema []byte
+
+	err := row.Scan(
+		&agg.DID,
+		&agg.DisplayName,
+		&description,
+		&avatarCID,
+		&configSchema,
+		&maintainerDID,
+		&sourceURL,
+		&agg.CommunitiesUsing,
+		&agg.PostsCreated,
+		&agg.CreatedAt,
+		&agg.IndexedAt,
+		&recordURI,
+		&recordCID,
+		&maintainerHandle,
+		&pdsURL,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan aggregator service: %w", err)
+	}
+
+	agg.Description = description.String
+	agg.AvatarURL = avatarCID.String
+	agg.MaintainerDID = maintainerDID.String
+	agg.SourceURL = sourceURL.String
+	agg.RecordURI = recordURI.String
+	agg.RecordCID = recordCID.String
+	agg.MaintainerHandle = maintainerHandle.String
+	agg.PDSURL = pdsURL.String
+	if configSchema != nil {
+		agg.ConfigSchema = configSchema
+	}
+
+	return agg, nil
+}
@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"Coves/internal/core/audit"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// lockAuditLogSQL serializes appends, so each entry chains to the one committed before it
+const lockAuditLogSQL = `SELECT pg_advisory_xact_lock(hashtextextended('audit_log', 0))`
+
+const auditEntryColumns = `id, actor_did, action, subject, details, created_at, prev_hash, row_hash`
+
+type postgresAuditRepo struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a PostgreSQL repository for the audit log
+func NewAuditRepository(db *sql.DB) audit.Repository {
+	return &postgresAuditRepo{db: db}
+}
+
+// Append chains the entry to the newest one and inserts it
+func (r *postgresAuditRepo) Append(ctx context.Context, entry *audit.Entry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, lockAuditLogSQL); err != nil {
+		return fmt.Errorf("failed to lock audit log: %w", err)
+	}
+
+	entry.PrevHash = ""
+	err = tx.QueryRowContext(ctx, `SELECT row_hash FROM audit_log ORDER BY id DESC LIMIT 1`).Scan(&entry.PrevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read last audit entry: %w", err)
+	}
+	if entry.RowHash, err = audit.Hash(entry); err != nil {
+		return err
+	}
+
+	var details interface{}
+	if len(entry.Details) > 0 {
+		details = []byte(entry.Details)
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO audit_log (actor_did, action, subject, details, created_at, prev_hash, row_hash)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id`,
+		entry.ActorDID, entry.Action, entry.Subject, details, entry.CreatedAt, entry.PrevHash, entry.RowHash,
+	).Scan(&entry.ID)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit audit entry: %w", err)
+	}
+	return nil
+}
+
+// List returns entries matching the filter, newest first
+func (r *postgresAuditRepo) List(ctx context.Context, filter audit.Filter) ([]*audit.Entry, error) {
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.ActorDID != "" {
+		where("actor_did = $%d", filter.ActorDID)
+	}
+	if filter.Action != "" {
+		where("action = $%d", filter.Action)
+	}
+	if !filter.Since.IsZero() {
+		where("created_at >= $%d", filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		where("created_at < $%d", filter.Until)
+	}
+	if filter.Before > 0 {
+		where("id < $%d", filter.Before)
+	}
+
+	query := `SELECT ` + auditEntryColumns + ` FROM audit_log`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(` ORDER BY id DESC LIMIT $%d`, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	return scanAuditEntries(rows)
+}
+
+// ListAfter returns entries after afterID in chain order
+func (r *postgresAuditRepo) ListAfter(ctx context.Context, afterID int64, limit int) ([]*audit.Entry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+auditEntryColumns+` FROM audit_log
+		WHERE id > $1
+		ORDER BY id
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return scanAuditEntries(rows)
+}
+
+// scanAuditEntries reads every row of an audit log query
+func scanAuditEntries(rows *sql.Rows) ([]*audit.Entry, error) {
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	entries := []*audit.Entry{}
+	for rows.Next() {
+		entry := &audit.Entry{}
+		var details []byte
+		if err := rows.Scan(&entry.ID, &entry.ActorDID, &entry.Action, &entry.Subject, &details,
+			&entry.CreatedAt, &entry.PrevHash, &entry.RowHash); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		if len(details) > 0 {
+			entry.Details = details
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating audit log: %w", err)
+	}
+	return entries, nil
+}
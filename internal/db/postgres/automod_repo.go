@@ -0,0 +1,268 @@
+package postgres
+
+import (
+	"Coves/internal/atproto/utils"
+	"Coves/internal/core/automod"
+	"Coves/internal/core/communities"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+)
+
+type postgresAutomodRepo struct {
+	db *sql.DB
+}
+
+// NewAutomodRepository creates a PostgreSQL repository for community automod rules and actions
+func NewAutomodRepository(db *sql.DB) automod.Repository {
+	return &postgresAutomodRepo{db: db}
+}
+
+// NewAutomodQueueRepository creates a PostgreSQL repository for reviewing held and flagged content
+func NewAutomodQueueRepository(db *sql.DB) automod.QueueRepository {
+	return &postgresAutomodRepo{db: db}
+}
+
+// GetRules returns a community's rules, or none if it never configured automod
+func (r *postgresAutomodRepo) GetRules(ctx context.Context, communityDID string) ([]automod.Rule, error) {
+	var raw []byte
+	err := r.db.QueryRowContext(ctx, `
+		SELECT rules FROM community_automod_rules WHERE community_did = $1`, communityDID).Scan(&raw)
+	if errors.Is(err, sql.ErrNoRows) {
+		return []automod.Rule{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get automod rules: %w", err)
+	}
+
+	rules := []automod.Rule{}
+	if err := json.Unmarshal(raw, &rules); err != nil {
+		return nil, fmt.Errorf("failed to decode automod rules: %w", err)
+	}
+	return rules, nil
+}
+
+// SetRules replaces a community's rules
+func (r *postgresAutomodRepo) SetRules(ctx context.Context, communityDID string, rules []automod.Rule, updatedBy string) error {
+	raw, err := json.Marshal(rules)
+	if err != nil {
+		return fmt.Errorf("failed to encode automod rules: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO community_automod_rules (community_did, rules, updated_by, updated_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (community_did) DO UPDATE
+		SET rules = EXCLUDED.rules, updated_by = EXCLUDED.updated_by, updated_at = NOW()`,
+		communityDID, raw, updatedBy)
+	if err != nil {
+		if _, ok := constraintViolation(err, pqForeignKeyViolation); ok {
+			return communities.ErrCommunityNotFound
+		}
+		return fmt.Errorf("failed to set automod rules: %w", err)
+	}
+	return nil
+}
+
+// RecordAction stores an action's audit row, ignoring replays of the same subject
+func (r *postgresAutomodRepo) RecordAction(ctx context.Context, record *automod.ActionRecord) error {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO automod_actions (subject_uri, community_did, author_did, action, match_type, pattern, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (subject_uri) DO NOTHING
+		RETURNING id, created_at`,
+		record.SubjectURI, record.CommunityDID, record.AuthorDID,
+		string(record.Action), string(record.MatchType), record.Pattern, string(record.Status),
+	).Scan(&record.ID, &record.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record automod action: %w", err)
+	}
+	return nil
+}
+
+const automodActionColumns = `
+	id, subject_uri, community_did, author_did, action, match_type, pattern, status,
+	reviewed_by_did, reviewed_at, created_at`
+
+func scanAutomodAction(row interface{ Scan(...any) error }) (*automod.ActionRecord, error) {
+	var record automod.ActionRecord
+	var action, matchType, status string
+	var reviewedBy sql.NullString
+	var reviewedAt sql.NullTime
+	if err := row.Scan(
+		&record.ID, &record.SubjectURI, &record.CommunityDID, &record.AuthorDID,
+		&action, &matchType, &record.Pattern, &status,
+		&reviewedBy, &reviewedAt, &record.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	record.Action = automod.Action(action)
+	record.MatchType = automod.MatchType(matchType)
+	record.Status = automod.ReviewStatus(status)
+	record.ReviewedBy = reviewedBy.String
+	if reviewedAt.Valid {
+		record.ReviewedAt = &reviewedAt.Time
+	}
+	return &record, nil
+}
+
+// ListOpenActions returns a community's held and flagged content awaiting review, newest first
+func (r *postgresAutomodRepo) ListOpenActions(ctx context.Context, communityDID string, limit, offset int) ([]*automod.ActionRecord, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+automodActionColumns+`
+		FROM automod_actions
+		WHERE community_did = $1 AND status = 'open'
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, communityDID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list automod actions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	records := []*automod.ActionRecord{}
+	for rows.Next() {
+		record, err := scanAutomodAction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan automod action: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating automod actions: %w", err)
+	}
+	return records, nil
+}
+
+// GetAction returns an action by ID
+func (r *postgresAutomodRepo) GetAction(ctx context.Context, id int64) (*automod.ActionRecord, error) {
+	record, err := scanAutomodAction(r.db.QueryRowContext(ctx, `
+		SELECT`+automodActionColumns+`
+		FROM automod_actions
+		WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, automod.ErrActionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get automod action: %w", err)
+	}
+	return record, nil
+}
+
+// ReviewAction approves or removes an open action's content and closes the action
+// Approving held content makes it visible; removing flagged content hides it. Comment thread
+// counts are recounted since only active comments count toward them.
+func (r *postgresAutomodRepo) ReviewAction(ctx context.Context, id int64, status automod.ReviewStatus, reviewerDID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	var subjectURI string
+	err = tx.QueryRowContext(ctx, `
+		SELECT subject_uri FROM automod_actions
+		WHERE id = $1 AND status = 'open'
+		FOR UPDATE`, id).Scan(&subjectURI)
+	if errors.Is(err, sql.ErrNoRows) {
+		return automod.ErrActionNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to lock automod action: %w", err)
+	}
+
+	var table string
+	switch utils.ExtractCollectionFromURI(subjectURI) {
+	case "social.coves.community.post":
+		table = "posts"
+	case "social.coves.community.comment":
+		table = "comments"
+	default:
+		return fmt.Errorf("automod action %d has an unsupported subject: %s", id, subjectURI)
+	}
+
+	// table is one of two constants, never user input
+	var contentQuery string
+	if status == automod.ReviewApproved {
+		contentQuery = `UPDATE ` + table + ` SET status = 'active', visibility_state = 'visible'
+			WHERE uri = $1 AND status = 'held'`
+	} else {
+		contentQuery = `UPDATE ` + table + ` SET status = 'removed', visibility_state = 'removed'
+			WHERE uri = $1 AND status IN ('active', 'held')`
+	}
+	result, err := tx.ExecContext(ctx, contentQuery, subjectURI)
+	if err != nil {
+		return fmt.Errorf("failed to update automod subject: %w", err)
+	}
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check automod subject update: %w", err)
+	}
+	if changed > 0 && table == "comments" {
+		if err := recountCommentThread(ctx, tx, subjectURI); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE automod_actions
+		SET status = $2, reviewed_by_did = $3, reviewed_at = NOW()
+		WHERE id = $1`, id, string(status), reviewerDID); err != nil {
+		return fmt.Errorf("failed to review automod action: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// recountCommentThread recounts the root post's comment totals and the parent comment's
+// replies after a comment starts or stops counting
+func recountCommentThread(ctx context.Context, tx *sql.Tx, commentURI string) error {
+	var rootURI, parentURI string
+	if err := tx.QueryRowContext(ctx, `SELECT root_uri, parent_uri FROM comments WHERE uri = $1`, commentURI).
+		Scan(&rootURI, &parentURI); err != nil {
+		return fmt.Errorf("failed to look up comment thread: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE posts
+		SET comment_count = (
+				SELECT COUNT(*) FROM comments
+				WHERE root_uri = $1 AND deleted_at IS NULL AND status = 'active'
+			),
+			top_level_comment_count = (
+				SELECT COUNT(*) FROM comments
+				WHERE parent_uri = $1 AND deleted_at IS NULL AND status = 'active'
+			)
+		WHERE uri = $1`, rootURI); err != nil {
+		return fmt.Errorf("failed to recount post comments: %w", err)
+	}
+
+	if parentURI != rootURI {
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE comments
+			SET reply_count = (
+				SELECT COUNT(*) FROM comments
+				WHERE parent_uri = $1 AND deleted_at IS NULL AND status = 'active'
+			)
+			WHERE uri = $1`, parentURI); err != nil {
+			return fmt.Errorf("failed to recount comment replies: %w", err)
+		}
+	}
+	return nil
+}
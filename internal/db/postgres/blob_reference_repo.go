@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"Coves/internal/core/blobrefs"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+type postgresBlobReferenceRepo struct {
+	db *sql.DB
+}
+
+// NewBlobReferenceRepository creates a PostgreSQL repository for blob references and blocked blobs
+func NewBlobReferenceRepository(db *sql.DB) blobrefs.Repository {
+	return &postgresBlobReferenceRepo{db: db}
+}
+
+// liveReference is true for a reference from a record readers can still see
+// References to anything but posts and comments (profiles) join neither table and are live.
+const liveReference = `
+	(p.uri IS NULL OR (p.deleted_at IS NULL AND p.taken_down_at IS NULL AND p.visibility_state <> 'removed'))
+	AND (c.uri IS NULL OR (c.deleted_at IS NULL AND c.taken_down_at IS NULL AND c.visibility_state <> 'removed'))`
+
+// IsBlocked reports whether the blob is on the block list
+func (r *postgresBlobReferenceRepo) IsBlocked(ctx context.Context, cid string) (bool, error) {
+	var blocked bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM blocked_blobs WHERE cid = $1)`, cid).Scan(&blocked)
+	if err != nil {
+		return false, fmt.Errorf("failed to check blob block list: %w", err)
+	}
+	return blocked, nil
+}
+
+// SetReferences replaces the record's blob references and refreshes the affected blobs in one transaction
+func (r *postgresBlobReferenceRepo) SetReferences(ctx context.Context, recordURI string, cids []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	if cids == nil {
+		cids = []string{}
+	}
+	// Blobs the record stops referencing are refreshed too: their last live reference may be gone
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM blob_references
+		WHERE record_uri = $1 AND NOT (blob_cid = ANY($2))
+		RETURNING blob_cid`, recordURI, pq.Array(cids))
+	if err != nil {
+		return fmt.Errorf("failed to remove blob references: %w", err)
+	}
+	affected, err := scanCIDs(rows)
+	if err != nil {
+		return fmt.Errorf("failed to remove blob references: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO blob_references (blob_cid, record_uri)
+		SELECT cid, $1 FROM unnest($2::text[]) AS cid
+		ON CONFLICT DO NOTHING`, recordURI, pq.Array(cids)); err != nil {
+		return fmt.Errorf("failed to insert blob references: %w", err)
+	}
+
+	if err := refreshBlockedBlobs(ctx, tx, append(affected, cids...)); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit blob references: %w", err)
+	}
+	return nil
+}
+
+// Refresh re-evaluates the block state of every blob the record references
+func (r *postgresBlobReferenceRepo) Refresh(ctx context.Context, recordURI string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	rows, err := tx.QueryContext(ctx, `SELECT blob_cid FROM blob_references WHERE record_uri = $1`, recordURI)
+	if err != nil {
+		return fmt.Errorf("failed to list blob references: %w", err)
+	}
+	cids, err := scanCIDs(rows)
+	if err != nil {
+		return fmt.Errorf("failed to list blob references: %w", err)
+	}
+	if len(cids) == 0 {
+		return nil
+	}
+
+	if err := refreshBlockedBlobs(ctx, tx, cids); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit blob block list: %w", err)
+	}
+	return nil
+}
+
+// blobReferenceSources are the tables whose raw records reference blobs, with the SQL for each
+// row's record URI; user profiles keep only their avatar and banner CIDs and are picked up on
+// their next profile write
+var blobReferenceSources = []struct {
+	table string
+	uri   string
+}{
+	{table: "posts", uri: "t.uri"},
+	{table: "comments", uri: "t.uri"},
+	{table: "communities", uri: "'at://' || t.did || '/social.coves.community.profile/self'"},
+}
+
+// BackfillReferences records the blobs of posts, comments and community profiles indexed
+// before blob references existed
+// Walks each table in id order, batchSize at a time, skipping records without blobs or with
+// references already. Returns how many records were backfilled.
+func (r *postgresBlobReferenceRepo) BackfillReferences(ctx context.Context, batchSize int) (int, error) {
+	backfilled := 0
+	for _, source := range blobReferenceSources {
+		n, err := r.backfillReferences(ctx, source.table, source.uri, batchSize)
+		backfilled += n
+		if err != nil {
+			return backfilled, err
+		}
+	}
+	return backfilled, nil
+}
+
+// backfillReferences backfills the blob references of one table's records
+func (r *postgresBlobReferenceRepo) backfillReferences(ctx context.Context, table, uriExpr string, batchSize int) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT t.id, %[2]s, t.raw_record
+		FROM %[1]s t
+		WHERE t.id > $1
+			AND t.raw_record IS NOT NULL
+			AND jsonb_path_exists(t.raw_record, '$.** ? (@."$type" == "blob")')
+			AND NOT EXISTS (SELECT 1 FROM blob_references r WHERE r.record_uri = %[2]s)
+		ORDER BY t.id
+		LIMIT $2
+	`, table, uriExpr)
+
+	backfilled := 0
+	var lastID int64
+	for {
+		rows, err := r.db.QueryContext(ctx, query, lastID, batchSize)
+		if err != nil {
+			return backfilled, fmt.Errorf("failed to query %s to backfill: %w", table, err)
+		}
+
+		references := make(map[string][]string)
+		count := 0
+		for rows.Next() {
+			var id int64
+			var uri string
+			var recordJSON []byte
+			if err := rows.Scan(&id, &uri, &recordJSON); err != nil {
+				_ = rows.Close()
+				return backfilled, fmt.Errorf("failed to scan %s record: %w", table, err)
+			}
+			count++
+			lastID = id
+
+			var record map[string]interface{}
+			if err := json.Unmarshal(recordJSON, &record); err != nil {
+				continue
+			}
+			if cids := blobrefs.CIDs(record); len(cids) > 0 {
+				references[uri] = cids
+			}
+		}
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+		if err := rows.Err(); err != nil {
+			return backfilled, fmt.Errorf("error iterating %s to backfill: %w", table, err)
+		}
+
+		for uri, cids := range references {
+			if err := r.SetReferences(ctx, uri, cids); err != nil {
+				return backfilled, err
+			}
+			backfilled++
+		}
+
+		if count < batchSize {
+			return backfilled, nil
+		}
+	}
+}
+
+// refreshBlockedBlobs blocks the given blobs that have references but no live one, and
+// unblocks the rest
+func refreshBlockedBlobs(ctx context.Context, tx *sql.Tx, cids []string) error {
+	if len(cids) == 0 {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT r.blob_cid
+		FROM blob_references r
+		LEFT JOIN posts p ON p.uri = r.record_uri
+		LEFT JOIN comments c ON c.uri = r.record_uri
+		WHERE r.blob_cid = ANY($1)
+		GROUP BY r.blob_cid
+		HAVING NOT bool_or(`+liveReference+`)`, pq.Array(cids))
+	if err != nil {
+		return fmt.Errorf("failed to find unreferenced blobs: %w", err)
+	}
+	blocked, err := scanCIDs(rows)
+	if err != nil {
+		return fmt.Errorf("failed to find unreferenced blobs: %w", err)
+	}
+	if blocked == nil {
+		blocked = []string{}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO blocked_blobs (cid)
+		SELECT unnest($1::text[])
+		ON CONFLICT (cid) DO NOTHING`, pq.Array(blocked)); err != nil {
+		return fmt.Errorf("failed to block blobs: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM blocked_blobs
+		WHERE cid = ANY($1) AND NOT (cid = ANY($2))`, pq.Array(cids), pq.Array(blocked)); err != nil {
+		return fmt.Errorf("failed to unblock blobs: %w", err)
+	}
+	return nil
+}
+
+// scanCIDs reads a single-column result of CIDs and closes the rows
+func scanCIDs(rows *sql.Rows) ([]string, error) {
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	var cids []string
+	for rows.Next() {
+		var cid string
+		if err := rows.Scan(&cid); err != nil {
+			return nil, err
+		}
+		cids = append(cids, cid)
+	}
+	return cids, rows.Err()
+}
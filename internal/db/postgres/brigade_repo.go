@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"Coves/internal/core/brigade"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+type postgresBrigadeRepo struct {
+	db *sql.DB
+}
+
+// NewBrigadeRepository creates a PostgreSQL repository for brigading detection
+func NewBrigadeRepository(db *sql.DB) brigade.Repository {
+	return &postgresBrigadeRepo{db: db}
+}
+
+// NewBrigadeQueueRepository creates a PostgreSQL repository for reviewing brigade alerts
+func NewBrigadeQueueRepository(db *sql.DB) brigade.QueueRepository {
+	return &postgresBrigadeRepo{db: db}
+}
+
+// ListBuckets returns busy posts' votes indexed in [start, end) with their outsider counts
+// A voter is an outsider unless they have community activity on an earlier UTC day.
+func (r *postgresBrigadeRepo) ListBuckets(ctx context.Context, start, end time.Time, minVotes int) ([]*brigade.Bucket, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT v.subject_uri, p.community_did, COUNT(*),
+			COUNT(*) FILTER (WHERE NOT EXISTS (
+				SELECT 1 FROM community_activity a
+				WHERE a.community_did = p.community_did AND a.user_did = v.voter_did AND a.day < $4::date
+			))
+		FROM votes v
+		JOIN posts p ON p.uri = v.subject_uri
+		WHERE v.indexed_at >= $1 AND v.indexed_at < $2
+			AND v.deleted_at IS NULL
+			AND p.deleted_at IS NULL
+		GROUP BY v.subject_uri, p.community_did
+		HAVING COUNT(*) >= $3`,
+		start, end, minVotes, activityDay(start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list vote buckets: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	buckets := []*brigade.Bucket{}
+	for rows.Next() {
+		bucket := &brigade.Bucket{Start: start}
+		if err := rows.Scan(&bucket.PostURI, &bucket.CommunityDID, &bucket.Votes, &bucket.Outsiders); err != nil {
+			return nil, fmt.Errorf("failed to scan vote bucket: %w", err)
+		}
+		buckets = append(buckets, bucket)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating vote buckets: %w", err)
+	}
+	return buckets, nil
+}
+
+// ListBucketCounts returns the community's non-empty per-post vote counts per bucket in [since, until)
+func (r *postgresBrigadeRepo) ListBucketCounts(ctx context.Context, communityDID string, since, until time.Time, bucketSize time.Duration) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT COUNT(*)
+		FROM votes v
+		JOIN posts p ON p.uri = v.subject_uri
+		WHERE p.community_did = $1
+			AND v.indexed_at >= $2 AND v.indexed_at < $3
+			AND v.deleted_at IS NULL
+		GROUP BY v.subject_uri, FLOOR(EXTRACT(EPOCH FROM v.indexed_at) / $4)`,
+		communityDID, since, until, bucketSize.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bucket counts: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	counts := []int{}
+	for rows.Next() {
+		var count int
+		if err := rows.Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to scan bucket count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating bucket counts: %w", err)
+	}
+	return counts, nil
+}
+
+// CreateAlert records the alert and notifies the community's creator and moderators
+func (r *postgresBrigadeRepo) CreateAlert(ctx context.Context, alert *brigade.Alert) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO brigade_alerts (post_uri, community_did, bucket_start, votes, outsiders, z_score, baseline_velocity)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (post_uri) DO NOTHING
+		RETURNING id, created_at`,
+		alert.PostURI, alert.CommunityDID, alert.BucketStart, alert.Votes, alert.Outsiders, alert.ZScore, alert.BaselineVelocity,
+	).Scan(&alert.ID, &alert.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to insert brigade alert: %w", err)
+	}
+
+	// The notification comes from the community itself, about the brigaded post
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO notifications (recipient_did, author_did, reason, subject_uri, subject_cid)
+		SELECT recipient, $1, $2, $3, (SELECT cid FROM posts WHERE uri = $3)
+		FROM (
+			SELECT created_by_did AS recipient FROM communities WHERE did = $1
+			UNION
+			SELECT user_did FROM community_memberships WHERE community_did = $1 AND is_moderator = TRUE
+		) moderators
+		ON CONFLICT (recipient_did, reason, subject_uri) WHERE subject_parent_uri IS NULL DO NOTHING`,
+		alert.CommunityDID, brigade.NotificationReason, alert.PostURI)
+	if err != nil {
+		return false, fmt.Errorf("failed to notify moderators of brigade alert: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+const brigadeAlertColumns = `
+	id, post_uri, community_did, bucket_start, votes, outsiders, z_score, baseline_velocity,
+	status, COALESCE(reviewed_by_did, ''), reviewed_at, created_at`
+
+func scanBrigadeAlert(scanner interface{ Scan(...interface{}) error }) (*brigade.Alert, error) {
+	alert := &brigade.Alert{}
+	var status string
+	var reviewedAt sql.NullTime
+	if err := scanner.Scan(
+		&alert.ID, &alert.PostURI, &alert.CommunityDID, &alert.BucketStart, &alert.Votes, &alert.Outsiders,
+		&alert.ZScore, &alert.BaselineVelocity, &status, &alert.ReviewedBy, &reviewedAt, &alert.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	alert.Status = brigade.AlertStatus(status)
+	if reviewedAt.Valid {
+		alert.ReviewedAt = &reviewedAt.Time
+	}
+	return alert, nil
+}
+
+// ListOpenAlerts returns a community's alerts awaiting review, newest first
+func (r *postgresBrigadeRepo) ListOpenAlerts(ctx context.Context, communityDID string, limit, offset int) ([]*brigade.Alert, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+brigadeAlertColumns+`
+		FROM brigade_alerts
+		WHERE community_did = $1 AND status = 'open'
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3`, communityDID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list brigade alerts: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	alerts := []*brigade.Alert{}
+	for rows.Next() {
+		alert, err := scanBrigadeAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan brigade alert: %w", err)
+		}
+		alerts = append(alerts, alert)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating brigade alerts: %w", err)
+	}
+	return alerts, nil
+}
+
+// GetAlert returns an alert by ID
+func (r *postgresBrigadeRepo) GetAlert(ctx context.Context, id int64) (*brigade.Alert, error) {
+	alert, err := scanBrigadeAlert(r.db.QueryRowContext(ctx, `
+		SELECT`+brigadeAlertColumns+`
+		FROM brigade_alerts
+		WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, brigade.ErrAlertNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get brigade alert: %w", err)
+	}
+	return alert, nil
+}
+
+// ReviewAlert dismisses or confirms an open alert
+func (r *postgresBrigadeRepo) ReviewAlert(ctx context.Context, id int64, status brigade.AlertStatus, reviewerDID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE brigade_alerts
+		SET status = $2, reviewed_by_did = $3, reviewed_at = NOW()
+		WHERE id = $1 AND status = 'open'`, id, string(status), reviewerDID)
+	if err != nil {
+		return fmt.Errorf("failed to review brigade alert: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check review result: %w", err)
+	}
+	if rowsAffected == 0 {
+		return brigade.ErrAlertNotFound
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"Coves/internal/core/comments"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type postgresDraftRepo struct {
+	db *sql.DB
+}
+
+// NewDraftRepository creates a PostgreSQL repository for comment drafts
+func NewDraftRepository(db *sql.DB) comments.DraftRepository {
+	return &postgresDraftRepo{db: db}
+}
+
+// PutDraft creates or replaces the user's draft for the context
+func (r *postgresDraftRepo) PutDraft(ctx context.Context, draft *comments.Draft) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO comment_drafts (user_did, context_uri, content, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_did, context_uri) DO UPDATE
+		SET content = EXCLUDED.content, updated_at = EXCLUDED.updated_at`,
+		draft.UserDID, draft.Context, draft.Content, draft.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save draft for %s: %w", draft.Context, err)
+	}
+	return nil
+}
+
+// GetDraft returns the user's draft for the context
+func (r *postgresDraftRepo) GetDraft(ctx context.Context, userDID, contextURI string) (*comments.Draft, error) {
+	draft := &comments.Draft{UserDID: userDID, Context: contextURI}
+	err := r.db.QueryRowContext(ctx, `
+		SELECT content, updated_at FROM comment_drafts
+		WHERE user_did = $1 AND context_uri = $2`,
+		userDID, contextURI).Scan(&draft.Content, &draft.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, comments.ErrDraftNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get draft for %s: %w", contextURI, err)
+	}
+	return draft, nil
+}
+
+// DeleteDraft removes the draft if there is one
+func (r *postgresDraftRepo) DeleteDraft(ctx context.Context, userDID, contextURI string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM comment_drafts WHERE user_did = $1 AND context_uri = $2`, userDID, contextURI)
+	if err != nil {
+		return fmt.Errorf("failed to delete draft for %s: %w", contextURI, err)
+	}
+	return nil
+}
+
+// DeleteDraftsBefore removes drafts last saved before cutoff
+func (r *postgresDraftRepo) DeleteDraftsBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM comment_drafts WHERE updated_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired drafts: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count expired drafts: %w", err)
+	}
+	return deleted, nil
+}
@@ -1,24 +1,29 @@
 package postgres
 
 import (
+	"Coves/internal/atproto/utils"
 	"Coves/internal/core/comments"
+	"Coves/internal/pagination"
 	"context"
 	"database/sql"
-	"encoding/base64"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/lib/pq"
 )
 
 type postgresCommentRepo struct {
-	db *sql.DB
+	db      *sql.DB
+	cursors *pagination.Signer
 }
 
 // NewCommentRepository creates a new PostgreSQL comment repository
-func NewCommentRepository(db *sql.DB) comments.Repository {
-	return &postgresCommentRepo{db: db}
+// cursors signs the pagination cursors returned by the list queries
+func NewCommentRepository(db *sql.DB, cursors *pagination.Signer) comments.Repository {
+	return &postgresCommentRepo{db: db, cursors: cursors}
 }
 
 // Create inserts a new comment into the comments table
@@ -56,7 +61,7 @@ func (r *postgresCommentRepo) Create(ctx context.Context, comment *comments.Comm
 
 	if err != nil {
 		// Check for unique constraint violation
-		if strings.Contains(err.Error(), "duplicate key") {
+		if _, ok := constraintViolation(err, pqUniqueViolation); ok {
 			return comments.ErrCommentAlreadyExists
 		}
 
@@ -69,7 +74,48 @@ func (r *postgresCommentRepo) Create(ctx context.Context, comment *comments.Comm
 // Update modifies an existing comment's content fields
 // Called by Jetstream consumer after comment is updated on PDS
 // Preserves vote counts and created_at timestamp
+// When the CID changes, the replaced version is kept in content_revisions for moderators
+// and edited_at is set; replays of an already-indexed update leave history untouched.
+// Returns ErrStaleRevision, without writing, when comment.Rev is not newer than the indexed rev.
 func (r *postgresCommentRepo) Update(ctx context.Context, comment *comments.Comment) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	// Lock the row so concurrent updates record their revisions in order
+	var prior contentRevision
+	var priorRev string
+	err = tx.QueryRowContext(ctx, `
+		SELECT cid, content, content_facets, embed, COALESCE(edited_at, indexed_at), COALESCE(rev, '')
+		FROM comments
+		WHERE uri = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, comment.URI).Scan(&prior.CID, &prior.Content, &prior.ContentFacets, &prior.Embed, &prior.WrittenAt, &priorRev)
+	if err == sql.ErrNoRows {
+		return comments.ErrCommentNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get comment for update: %w", err)
+	}
+
+	// Checked under the lock: two out-of-order updates racing each other can't both win
+	if utils.IsStaleRev(comment.Rev, priorRev) {
+		return comments.ErrStaleRevision
+	}
+
+	edited := prior.CID != comment.CID
+	if edited {
+		if err := recordRevision(ctx, tx, comment.URI, prior); err != nil {
+			return err
+		}
+	}
+
 	query := `
 		UPDATE comments
 		SET
@@ -78,12 +124,17 @@ func (r *postgresCommentRepo) Update(ctx context.Context, comment *comments.Comm
 			content_facets = $3,
 			embed = $4,
 			content_labels = $5,
-			langs = $6
-		WHERE uri = $7 AND deleted_at IS NULL
-		RETURNING id, indexed_at, created_at, upvote_count, downvote_count, score, reply_count
+			langs = $6,
+			raw_record = COALESCE($7::jsonb, raw_record),
+			edited_at = CASE WHEN $9 THEN NOW() ELSE edited_at END,
+			detected_lang = $10,
+			detected_lang_confidence = $11,
+			rev = COALESCE(NULLIF($12, ''), rev)
+		WHERE uri = $8 AND deleted_at IS NULL
+		RETURNING id, indexed_at, created_at, upvote_count, downvote_count, score, reply_count, edited_at
 	`
 
-	err := r.db.QueryRowContext(
+	err = tx.QueryRowContext(
 		ctx, query,
 		comment.CID,
 		comment.Content,
@@ -91,7 +142,12 @@ func (r *postgresCommentRepo) Update(ctx context.Context, comment *comments.Comm
 		comment.Embed,
 		comment.ContentLabels,
 		pq.Array(comment.Langs),
+		comment.RawRecord,
 		comment.URI,
+		edited,
+		comment.DetectedLang,
+		comment.LangConfidence,
+		comment.Rev,
 	).Scan(
 		&comment.ID,
 		&comment.IndexedAt,
@@ -100,6 +156,7 @@ func (r *postgresCommentRepo) Update(ctx context.Context, comment *comments.Comm
 		&comment.DownvoteCount,
 		&comment.Score,
 		&comment.ReplyCount,
+		&comment.EditedAt,
 	)
 
 	if err == sql.ErrNoRows {
@@ -109,22 +166,36 @@ func (r *postgresCommentRepo) Update(ctx context.Context, comment *comments.Comm
 		return fmt.Errorf("failed to update comment: %w", err)
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit comment update: %w", err)
+	}
+
 	return nil
 }
 
-// GetByURI retrieves a comment by its AT-URI
+// GetByURI retrieves a non-deleted comment by its AT-URI
 // Used by Jetstream consumer for UPDATE/DELETE operations
 func (r *postgresCommentRepo) GetByURI(ctx context.Context, uri string) (*comments.Comment, error) {
-	query := `
+	return r.getByURI(ctx, uri, excludeDeleted)
+}
+
+// GetByURIWithDeleted retrieves a comment by its AT-URI, including soft-deleted comments
+// Used by permalink views, which show deleted comments as "[deleted]" stubs
+func (r *postgresCommentRepo) GetByURIWithDeleted(ctx context.Context, uri string) (*comments.Comment, error) {
+	return r.getByURI(ctx, uri, includeDeleted)
+}
+
+func (r *postgresCommentRepo) getByURI(ctx context.Context, uri string, scope deletedScope) (*comments.Comment, error) {
+	query := fmt.Sprintf(`
 		SELECT
 			id, uri, cid, rkey, commenter_did,
 			root_uri, root_cid, parent_uri, parent_cid,
 			content, content_facets, embed, content_labels, langs,
-			created_at, indexed_at, deleted_at, deletion_reason, deleted_by,
-			upvote_count, downvote_count, score, reply_count
+			created_at, indexed_at, deleted_at, deletion_reason, deleted_by, edited_at,
+			upvote_count, downvote_count, score, reply_count, orphaned, COALESCE(rev, '')
 		FROM comments
-		WHERE uri = $1
-	`
+		WHERE uri = $1 AND %s
+	`, scope.filter(""))
 
 	var comment comments.Comment
 	var langs pq.StringArray
@@ -133,8 +204,8 @@ func (r *postgresCommentRepo) GetByURI(ctx context.Context, uri string) (*commen
 		&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
 		&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
 		&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
-		&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy,
-		&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
+		&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
+		&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount, &comment.Orphaned, &comment.Rev,
 	)
 
 	if err == sql.ErrNoRows {
@@ -233,20 +304,22 @@ func (r *postgresCommentRepo) SoftDeleteWithReasonTx(ctx context.Context, tx *sq
 
 // ListByRoot retrieves all comments in a thread (flat), including deleted ones
 // Used for fetching entire comment threads on posts
-// Includes deleted comments to preserve thread structure (shown as "[deleted]" placeholders)
+// Excludes deleted comments; threaded views get placeholders from ListByParentsBatchWithDeleted
+//
+// Deprecated: Use ListByRootWithCursor; offsets skip or repeat rows inserted mid-pagination
 func (r *postgresCommentRepo) ListByRoot(ctx context.Context, rootURI string, limit, offset int) ([]*comments.Comment, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
 			id, uri, cid, rkey, commenter_did,
 			root_uri, root_cid, parent_uri, parent_cid,
 			content, content_facets, embed, content_labels, langs,
-			created_at, indexed_at, deleted_at, deletion_reason, deleted_by,
+			created_at, indexed_at, deleted_at, deletion_reason, deleted_by, edited_at,
 			upvote_count, downvote_count, score, reply_count
 		FROM comments
-		WHERE root_uri = $1
+		WHERE root_uri = $1 AND %s
 		ORDER BY created_at ASC
 		LIMIT $2 OFFSET $3
-	`
+	`, notDeleted(""))
 
 	rows, err := r.db.QueryContext(ctx, query, rootURI, limit, offset)
 	if err != nil {
@@ -267,7 +340,7 @@ func (r *postgresCommentRepo) ListByRoot(ctx context.Context, rootURI string, li
 			&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
 			&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
 			&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
-			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy,
+			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
 			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
 		)
 		if err != nil {
@@ -285,22 +358,23 @@ func (r *postgresCommentRepo) ListByRoot(ctx context.Context, rootURI string, li
 	return result, nil
 }
 
-// ListByParent retrieves direct replies to a post or comment, including deleted ones
-// Used for building nested/threaded comment views
-// Includes deleted comments to preserve thread structure (shown as "[deleted]" placeholders)
+// ListByParent retrieves non-deleted direct replies to a post or comment
+// Threaded views get "[deleted]" placeholders from ListByParentsBatchWithDeleted instead
+//
+// Deprecated: Use ListByParentWithCursor; offsets skip or repeat rows inserted mid-pagination
 func (r *postgresCommentRepo) ListByParent(ctx context.Context, parentURI string, limit, offset int) ([]*comments.Comment, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
 			id, uri, cid, rkey, commenter_did,
 			root_uri, root_cid, parent_uri, parent_cid,
 			content, content_facets, embed, content_labels, langs,
-			created_at, indexed_at, deleted_at, deletion_reason, deleted_by,
+			created_at, indexed_at, deleted_at, deletion_reason, deleted_by, edited_at,
 			upvote_count, downvote_count, score, reply_count
 		FROM comments
-		WHERE parent_uri = $1
+		WHERE parent_uri = $1 AND %s
 		ORDER BY created_at ASC
 		LIMIT $2 OFFSET $3
-	`
+	`, notDeleted(""))
 
 	rows, err := r.db.QueryContext(ctx, query, parentURI, limit, offset)
 	if err != nil {
@@ -321,7 +395,7 @@ func (r *postgresCommentRepo) ListByParent(ctx context.Context, parentURI string
 			&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
 			&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
 			&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
-			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy,
+			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
 			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
 		)
 		if err != nil {
@@ -339,14 +413,122 @@ func (r *postgresCommentRepo) ListByParent(ctx context.Context, parentURI string
 	return result, nil
 }
 
+// ListByRootWithCursor pages through a thread's non-deleted comments (flat), oldest first
+// Keyset-paginated on (created_at, id), so comments indexed between page fetches are never
+// returned twice or skipped
+func (r *postgresCommentRepo) ListByRootWithCursor(ctx context.Context, rootURI string, limit int, cursor *string) ([]*comments.Comment, *string, error) {
+	return r.listThreadPage(ctx, "root_uri", rootURI, limit, cursor)
+}
+
+// ListByParentWithCursor pages through a post or comment's non-deleted direct replies, oldest first
+func (r *postgresCommentRepo) ListByParentWithCursor(ctx context.Context, parentURI string, limit int, cursor *string) ([]*comments.Comment, *string, error) {
+	return r.listThreadPage(ctx, "parent_uri", parentURI, limit, cursor)
+}
+
+// listThreadPage lists one page of comments matching column = uri, oldest first
+// column is always a literal from the callers above, never user input.
+func (r *postgresCommentRepo) listThreadPage(ctx context.Context, column, uri string, limit int, cursor *string) ([]*comments.Comment, *string, error) {
+	// Parameter numbering: $1=uri, $2=limit+1 (for pagination detection), then cursor values
+	args := []interface{}{uri, limit + 1}
+	var cursorFilter string
+	if cursor != nil && *cursor != "" {
+		createdAt, id, err := r.parseThreadCursor(*cursor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", comments.ErrInvalidCursor, err)
+		}
+		cursorFilter = "AND (created_at, id) > ($3, $4)"
+		args = append(args, createdAt, id)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			id, uri, cid, rkey, commenter_did,
+			root_uri, root_cid, parent_uri, parent_cid,
+			content, content_facets, embed, content_labels, langs,
+			created_at, indexed_at, deleted_at, deletion_reason, deleted_by, edited_at,
+			upvote_count, downvote_count, score, reply_count
+		FROM comments
+		WHERE %s = $1 AND %s
+			%s
+		ORDER BY created_at ASC, id ASC
+		LIMIT $2
+	`, column, notDeleted(""), cursorFilter)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list comments by %s: %w", column, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	var result []*comments.Comment
+	for rows.Next() {
+		var comment comments.Comment
+		var langs pq.StringArray
+
+		err := rows.Scan(
+			&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
+			&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
+			&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
+			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
+			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+
+		comment.Langs = langs
+		result = append(result, &comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	var nextCursor *string
+	if len(result) > limit && limit > 0 {
+		result = result[:limit]
+		last := result[len(result)-1]
+		cursorStr := r.cursors.Encode(last.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00"), strconv.FormatInt(last.ID, 10))
+		nextCursor = &cursorStr
+	}
+
+	return result, nextCursor, nil
+}
+
+// parseThreadCursor decodes a thread listing cursor
+// Cursor fields: createdAt, id
+func (r *postgresCommentRepo) parseThreadCursor(cursor string) (string, int64, error) {
+	parts, err := r.cursors.Decode(cursor)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(parts) != 2 {
+		return "", 0, fmt.Errorf("invalid cursor format")
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, parts[0]); err != nil {
+		return "", 0, fmt.Errorf("invalid cursor timestamp")
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || id <= 0 {
+		return "", 0, fmt.Errorf("invalid cursor id")
+	}
+
+	return parts[0], id, nil
+}
+
 // CountByParent counts direct replies to a post or comment
 // Used for showing reply counts in threading UI
 func (r *postgresCommentRepo) CountByParent(ctx context.Context, parentURI string) (int, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT COUNT(*)
 		FROM comments
-		WHERE parent_uri = $1 AND deleted_at IS NULL
-	`
+		WHERE parent_uri = $1 AND %s
+	`, notDeleted(""))
 
 	var count int
 	err := r.db.QueryRowContext(ctx, query, parentURI).Scan(&count)
@@ -357,21 +539,56 @@ func (r *postgresCommentRepo) CountByParent(ctx context.Context, parentURI strin
 	return count, nil
 }
 
+// CountByRoot counts a post's visible comments at any depth
+// Rejected comments are indexed as removed, so the visibility check excludes them too
+func (r *postgresCommentRepo) CountByRoot(ctx context.Context, rootURI string) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM comments c
+		WHERE c.root_uri = $1 AND %s AND %s
+	`, notDeleted("c"), visibleToEveryone("c"))
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, rootURI).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count comments by root: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountParticipantsByRoot counts the distinct commenters among a post's visible comments
+func (r *postgresCommentRepo) CountParticipantsByRoot(ctx context.Context, rootURI string) (int, error) {
+	query := fmt.Sprintf(`
+		SELECT COUNT(DISTINCT c.commenter_did)
+		FROM comments c
+		WHERE c.root_uri = $1 AND %s AND %s
+	`, notDeleted("c"), visibleToEveryone("c"))
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, rootURI).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count participants by root: %w", err)
+	}
+
+	return count, nil
+}
+
 // ListByCommenter retrieves all active comments by a specific user
 // Used for user comment history - filters out deleted comments
 func (r *postgresCommentRepo) ListByCommenter(ctx context.Context, commenterDID string, limit, offset int) ([]*comments.Comment, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
 			id, uri, cid, rkey, commenter_did,
 			root_uri, root_cid, parent_uri, parent_cid,
 			content, content_facets, embed, content_labels, langs,
-			created_at, indexed_at, deleted_at, deletion_reason, deleted_by,
+			created_at, indexed_at, deleted_at, deletion_reason, deleted_by, edited_at,
 			upvote_count, downvote_count, score, reply_count
 		FROM comments
-		WHERE commenter_did = $1 AND deleted_at IS NULL
+		WHERE commenter_did = $1 AND %s
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
-	`
+	`, notDeleted(""))
 
 	rows, err := r.db.QueryContext(ctx, query, commenterDID, limit, offset)
 	if err != nil {
@@ -392,7 +609,7 @@ func (r *postgresCommentRepo) ListByCommenter(ctx context.Context, commenterDID
 			&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
 			&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
 			&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
-			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy,
+			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
 			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
 		)
 		if err != nil {
@@ -423,7 +640,7 @@ func (r *postgresCommentRepo) ListByCommenterWithCursor(ctx context.Context, req
 
 	// Build community filter if provided
 	// Parameter numbering: $1=commenterDID, $2=limit+1 (for pagination detection)
-	// Cursor values (if present) use $3 and $4, community DID comes after
+	// Cursor values (if present) use $3 and $4, community DID comes after, then the viewer DID
 	var communityFilter string
 	var communityValue []interface{}
 	paramOffset := 2 + len(cursorValues) // Start after $1, $2, and any cursor params
@@ -432,6 +649,7 @@ func (r *postgresCommentRepo) ListByCommenterWithCursor(ctx context.Context, req
 		communityFilter = fmt.Sprintf("AND c.root_uri IN (SELECT uri FROM posts WHERE community_did = $%d)", paramOffset)
 		communityValue = append(communityValue, *req.CommunityDID)
 	}
+	viewerParam := fmt.Sprintf("$%d", paramOffset+1)
 
 	// Build complete query with JOINs and filters
 	// LEFT JOIN prevents data loss when user record hasn't been indexed yet
@@ -440,23 +658,28 @@ func (r *postgresCommentRepo) ListByCommenterWithCursor(ctx context.Context, req
 			c.id, c.uri, c.cid, c.rkey, c.commenter_did,
 			c.root_uri, c.root_cid, c.parent_uri, c.parent_cid,
 			c.content, c.content_facets, c.embed, c.content_labels, c.langs,
-			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by,
+			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by, c.edited_at,
 			c.upvote_count, c.downvote_count, c.score, c.reply_count,
-			COALESCE(u.handle, c.commenter_did) as author_handle
+			COALESCE(u.handle, c.commenter_did) as author_handle,
+			COALESCE(p.community_did, ''), COALESCE(co.score_hiding_hours, 0)
 		FROM comments c
 		LEFT JOIN users u ON c.commenter_did = u.did
+		LEFT JOIN posts p ON c.root_uri = p.uri
+		LEFT JOIN communities co ON p.community_did = co.did
 		WHERE c.commenter_did = $1
-			AND c.deleted_at IS NULL
+			AND %s
+			AND %s
 			%s
 			%s
 		ORDER BY c.created_at DESC, c.uri DESC
 		LIMIT $2
-	`, communityFilter, cursorFilter)
+	`, notDeleted("c"), visibleTo("c", "commenter_did", viewerParam), communityFilter, cursorFilter)
 
 	// Prepare query arguments
 	args := []interface{}{req.CommenterDID, req.Limit + 1} // +1 to detect next page
 	args = append(args, cursorValues...)
 	args = append(args, communityValue...)
+	args = append(args, req.ViewerDID)
 
 	// Execute query
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -480,9 +703,9 @@ func (r *postgresCommentRepo) ListByCommenterWithCursor(ctx context.Context, req
 			&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
 			&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
 			&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
-			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy,
+			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
 			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
-			&authorHandle,
+			&authorHandle, &comment.CommunityDID, &comment.ScoreHidingHours,
 		)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to scan comment: %w", err)
@@ -510,7 +733,7 @@ func (r *postgresCommentRepo) ListByCommenterWithCursor(ctx context.Context, req
 }
 
 // parseCommenterCursor decodes pagination cursor for commenter comments
-// Cursor format: createdAt|uri (same as "new" sort for other comment queries)
+// Cursor fields: createdAt, uri (same as "new" sort for other comment queries)
 //
 // IMPORTANT: This function returns a filter string with hardcoded parameter numbers ($3, $4).
 // The caller (ListByCommenterWithCursor) must ensure parameters are ordered as:
@@ -521,20 +744,11 @@ func (r *postgresCommentRepo) parseCommenterCursor(cursor *string) (string, []in
 		return "", nil, nil
 	}
 
-	// Validate cursor size to prevent DoS via massive base64 strings
-	const maxCursorSize = 1024
-	if len(*cursor) > maxCursorSize {
-		return "", nil, fmt.Errorf("cursor too large: maximum %d bytes", maxCursorSize)
-	}
-
-	// Decode base64 cursor
-	decoded, err := base64.URLEncoding.DecodeString(*cursor)
+	// Cursor fields: createdAt, uri
+	parts, err := r.cursors.Decode(*cursor)
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid cursor encoding")
+		return "", nil, err
 	}
-
-	// Parse cursor: createdAt|uri
-	parts := strings.Split(string(decoded), "|")
 	if len(parts) != 2 {
 		return "", nil, fmt.Errorf("invalid cursor format")
 	}
@@ -551,19 +765,148 @@ func (r *postgresCommentRepo) parseCommenterCursor(cursor *string) (string, []in
 	return filter, []interface{}{createdAt, uri}, nil
 }
 
-// buildCommenterCursor creates pagination cursor from last comment
-// Uses createdAt|uri format for stable pagination
+// buildCommenterCursor creates a signed pagination cursor from last comment
+// Uses createdAt, uri fields for stable pagination
 func (r *postgresCommentRepo) buildCommenterCursor(comment *comments.Comment) string {
-	cursorStr := fmt.Sprintf("%s|%s",
-		comment.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00"),
-		comment.URI)
-	return base64.URLEncoding.EncodeToString([]byte(cursorStr))
+	return r.cursors.Encode(comment.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00"), comment.URI)
+}
+
+// Search full-text searches comments on a community's posts
+// Comments are scoped to the community through their root post, so comments on another
+// community's posts are never returned. Ordered by relevance (ts_rank), then newest first.
+func (r *postgresCommentRepo) Search(ctx context.Context, req comments.SearchRequest) ([]*comments.Comment, *string, error) {
+	// Parameter numbering: $1=communityDID, $2=query, $3=limit+1, then optional filters
+	args := []interface{}{req.CommunityDID, req.Query, req.Limit + 1}
+	var filters strings.Builder
+
+	filters.WriteString(" AND " + notTakenDown("c"))
+	if !req.IncludeDeleted {
+		filters.WriteString(" AND " + notDeleted("c"))
+	}
+	if req.AuthorDID != nil && *req.AuthorDID != "" {
+		args = append(args, *req.AuthorDID)
+		fmt.Fprintf(&filters, " AND c.commenter_did = $%d", len(args))
+	}
+	if req.PostURI != nil && *req.PostURI != "" {
+		args = append(args, *req.PostURI)
+		fmt.Fprintf(&filters, " AND c.root_uri = $%d", len(args))
+	}
+
+	if req.Cursor != nil && *req.Cursor != "" {
+		rank, createdAt, uri, err := r.parseSearchCursor(*req.Cursor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", comments.ErrInvalidCursor, err)
+		}
+		args = append(args, rank, createdAt, uri)
+		n := len(args)
+		fmt.Fprintf(&filters, " AND (ts_rank(c.content_search, q.query), c.created_at, c.uri) < ($%d::real, $%d::timestamptz, $%d)", n-2, n-1, n)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			c.id, c.uri, c.cid, c.rkey, c.commenter_did,
+			c.root_uri, c.root_cid, c.parent_uri, c.parent_cid,
+			c.content, c.content_facets, c.embed, c.content_labels, c.langs,
+			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by, c.edited_at,
+			c.upvote_count, c.downvote_count, c.score, c.reply_count,
+			COALESCE(u.handle, c.commenter_did) as author_handle,
+			ts_rank(c.content_search, q.query) as rank
+		FROM comments c
+		JOIN posts p ON p.uri = c.root_uri
+		CROSS JOIN websearch_to_tsquery('english', $2) AS q(query)
+		LEFT JOIN users u ON c.commenter_did = u.did
+		WHERE p.community_did = $1
+			AND c.content_search @@ q.query
+			%s
+		ORDER BY rank DESC, c.created_at DESC, c.uri DESC
+		LIMIT $3
+	`, filters.String())
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search comments: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	var result []*comments.Comment
+	var ranks []float32
+	for rows.Next() {
+		var comment comments.Comment
+		var langs pq.StringArray
+		var authorHandle string
+		var rank float32
+
+		err := rows.Scan(
+			&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
+			&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
+			&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
+			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
+			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
+			&authorHandle, &rank,
+		)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+
+		comment.Langs = langs
+		comment.CommenterHandle = authorHandle
+		result = append(result, &comment)
+		ranks = append(ranks, rank)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating comments: %w", err)
+	}
+
+	var nextCursor *string
+	if len(result) > req.Limit && req.Limit > 0 {
+		result = result[:req.Limit]
+		last := result[len(result)-1]
+		cursorStr := r.cursors.Encode(
+			strconv.FormatFloat(float64(ranks[req.Limit-1]), 'g', -1, 32),
+			last.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00"),
+			last.URI,
+		)
+		nextCursor = &cursorStr
+	}
+
+	return result, nextCursor, nil
+}
+
+// parseSearchCursor decodes a comment search cursor
+// Cursor fields: rank, createdAt, uri
+func (r *postgresCommentRepo) parseSearchCursor(cursor string) (float32, string, string, error) {
+	parts, err := r.cursors.Decode(cursor)
+	if err != nil {
+		return 0, "", "", err
+	}
+	if len(parts) != 3 {
+		return 0, "", "", fmt.Errorf("invalid cursor format")
+	}
+
+	rank, err := strconv.ParseFloat(parts[0], 32)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("invalid cursor rank")
+	}
+	if _, err := time.Parse(time.RFC3339Nano, parts[1]); err != nil {
+		return 0, "", "", fmt.Errorf("invalid cursor timestamp")
+	}
+	if !strings.HasPrefix(parts[2], "at://") {
+		return 0, "", "", fmt.Errorf("invalid cursor URI")
+	}
+
+	return float32(rank), parts[1], parts[2], nil
 }
 
 // ListByParentWithHotRank retrieves direct replies to a post or comment with sorting and pagination
 // Supports three sort modes: hot (Lemmy algorithm), top (by score + timeframe), and new (by created_at)
 // Uses cursor-based pagination with composite keys for consistent ordering
 // Hydrates author info (handle, display_name, avatar) via JOIN with users table
+// Hidden comments are excluded, except author_only comments when viewerDID is their author
 func (r *postgresCommentRepo) ListByParentWithHotRank(
 	ctx context.Context,
 	parentURI string,
@@ -571,6 +914,34 @@ func (r *postgresCommentRepo) ListByParentWithHotRank(
 	timeframe string,
 	limit int,
 	cursor *string,
+	viewerDID string,
+) ([]*comments.Comment, *string, error) {
+	return r.listByParentWithHotRank(ctx, parentURI, sort, timeframe, limit, cursor, viewerDID, excludeDeleted)
+}
+
+// ListByParentWithHotRankWithDeleted is ListByParentWithHotRank including soft-deleted replies
+// Used by comment trees, which render deleted comments with live replies as tombstones
+func (r *postgresCommentRepo) ListByParentWithHotRankWithDeleted(
+	ctx context.Context,
+	parentURI string,
+	sort string,
+	timeframe string,
+	limit int,
+	cursor *string,
+	viewerDID string,
+) ([]*comments.Comment, *string, error) {
+	return r.listByParentWithHotRank(ctx, parentURI, sort, timeframe, limit, cursor, viewerDID, includeDeleted)
+}
+
+func (r *postgresCommentRepo) listByParentWithHotRank(
+	ctx context.Context,
+	parentURI string,
+	sort string,
+	timeframe string,
+	limit int,
+	cursor *string,
+	viewerDID string,
+	scope deletedScope,
 ) ([]*comments.Comment, *string, error) {
 	// Build ORDER BY clause and time filter based on sort type
 	orderBy, timeFilter := r.buildCommentSortClause(sort, timeframe)
@@ -581,62 +952,45 @@ func (r *postgresCommentRepo) ListByParentWithHotRank(
 		return nil, nil, fmt.Errorf("invalid cursor: %w", err)
 	}
 
-	// Build SELECT clause - compute hot_rank for "hot" sort
-	// Hot rank formula (Lemmy algorithm):
-	// log(greatest(2, score + 2)) / power(((EXTRACT(EPOCH FROM (NOW() - created_at)) / 3600) + 2), 1.8)
-	//
-	// This formula:
-	// - Gives logarithmic weight to score (prevents high-score dominance)
-	// - Decays over time with power 1.8 (faster than linear, slower than quadratic)
-	// - Uses hours as time unit (3600 seconds)
-	// - Adds constants to prevent division by zero and ensure positive values
-	var selectClause string
-	if sort == "hot" {
-		selectClause = `
+	// Build SELECT clause - compute the rank for "hot" and "best" sorts
+	selectClause := fmt.Sprintf(`
 		SELECT
 			c.id, c.uri, c.cid, c.rkey, c.commenter_did,
 			c.root_uri, c.root_cid, c.parent_uri, c.parent_cid,
 			c.content, c.content_facets, c.embed, c.content_labels, c.langs,
-			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by,
-			c.upvote_count, c.downvote_count, c.score, c.reply_count,
-			log(greatest(2, c.score + 2)) / power(((EXTRACT(EPOCH FROM (NOW() - c.created_at)) / 3600) + 2), 1.8) as hot_rank,
+			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by, c.edited_at,
+			c.upvote_count, c.downvote_count, c.score, c.reply_count, c.depth_exceeded,
+			%s as sort_rank,
 			COALESCE(u.handle, c.commenter_did) as author_handle
-		FROM comments c`
-	} else {
-		selectClause = `
-		SELECT
-			c.id, c.uri, c.cid, c.rkey, c.commenter_did,
-			c.root_uri, c.root_cid, c.parent_uri, c.parent_cid,
-			c.content, c.content_facets, c.embed, c.content_labels, c.langs,
-			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by,
-			c.upvote_count, c.downvote_count, c.score, c.reply_count,
-			NULL::numeric as hot_rank,
-			COALESCE(u.handle, c.commenter_did) as author_handle
-		FROM comments c`
-	}
+		FROM comments c`, commentSortRankExpr(sort))
 
 	// Build complete query with JOINs and filters
 	// LEFT JOIN prevents data loss when user record hasn't been indexed yet (out-of-order Jetstream events)
-	// Excludes deleted top-level comments - deleted nested comments are preserved via ListByParentsBatch
+	// Excludes orphaned comments (root post never indexed) so they can't surface under another thread
 	query := fmt.Sprintf(`
 		%s
 		LEFT JOIN users u ON c.commenter_did = u.did
 		WHERE c.parent_uri = $1
-			AND c.deleted_at IS NULL
+			AND %s
+			AND %s
+			AND NOT c.orphaned
 			%s
 			%s
 		ORDER BY %s
 		LIMIT $2
-	`, selectClause, timeFilter, cursorFilter, orderBy)
+	`, selectClause, scope.filter("c"), visibleTo("c", "commenter_did", fmt.Sprintf("$%d", 3+len(cursorValues))),
+		timeFilter, cursorFilter, orderBy)
 
 	// Prepare query arguments
+	// Viewer DID comes last, so author_only comments are shown to their author only
 	args := []interface{}{parentURI, limit + 1} // +1 to detect next page
 	args = append(args, cursorValues...)
+	args = append(args, viewerDID)
 
 	// Execute query
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to query comments with hot rank: %w", err)
+		return nil, nil, fmt.Errorf("failed to query comments with sort rank: %w", err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -646,20 +1000,20 @@ func (r *postgresCommentRepo) ListByParentWithHotRank(
 
 	// Scan results
 	var result []*comments.Comment
-	var hotRanks []float64
+	var sortRanks []float64
 	for rows.Next() {
 		var comment comments.Comment
 		var langs pq.StringArray
-		var hotRank sql.NullFloat64
+		var sortRank sql.NullFloat64
 		var authorHandle string
 
 		err := rows.Scan(
 			&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
 			&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
 			&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
-			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy,
-			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
-			&hotRank, &authorHandle,
+			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
+			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount, &comment.DepthExceeded,
+			&sortRank, &authorHandle,
 		)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to scan comment: %w", err)
@@ -668,12 +1022,12 @@ func (r *postgresCommentRepo) ListByParentWithHotRank(
 		comment.Langs = langs
 		comment.CommenterHandle = authorHandle
 
-		// Store hot_rank for cursor building
-		hotRankValue := 0.0
-		if hotRank.Valid {
-			hotRankValue = hotRank.Float64
+		// Store sort_rank for cursor building
+		sortRankValue := 0.0
+		if sortRank.Valid {
+			sortRankValue = sortRank.Float64
 		}
-		hotRanks = append(hotRanks, hotRankValue)
+		sortRanks = append(sortRanks, sortRankValue)
 
 		result = append(result, &comment)
 	}
@@ -686,23 +1040,58 @@ func (r *postgresCommentRepo) ListByParentWithHotRank(
 	var nextCursor *string
 	if len(result) > limit && limit > 0 {
 		result = result[:limit]
-		hotRanks = hotRanks[:limit]
+		sortRanks = sortRanks[:limit]
 		lastComment := result[len(result)-1]
-		lastHotRank := hotRanks[len(hotRanks)-1]
-		cursorStr := r.buildCommentCursor(lastComment, sort, lastHotRank)
+		lastSortRank := sortRanks[len(sortRanks)-1]
+		cursorStr := r.buildCommentCursor(lastComment, sort, lastSortRank)
 		nextCursor = &cursorStr
 	}
 
 	return result, nextCursor, nil
 }
 
+const (
+	// commentHotRankExpr is the hot rank (Lemmy algorithm):
+	// - Gives logarithmic weight to score (prevents high-score dominance)
+	// - Decays over time with power 1.8 (faster than linear, slower than quadratic)
+	// - Uses hours as time unit (3600 seconds)
+	// - Adds constants to prevent division by zero and ensure positive values
+	commentHotRankExpr = `log(greatest(2, c.score + 2)) / power(((EXTRACT(EPOCH FROM (NOW() - c.created_at)) / 3600) + 2), 1.8)`
+
+	// commentBestRankExpr is the best rank: the lower bound of the 95% Wilson score interval for
+	// the fraction of upvotes, plus a bonus of up to 0.1 shrinking to nothing over the first hour
+	// Must match comments.BestRank, which the service uses to break ties by author
+	commentBestRankExpr = `((CASE WHEN c.upvote_count + c.downvote_count = 0 THEN 0
+		ELSE ((c.upvote_count + 1.9208) / (c.upvote_count + c.downvote_count)
+			- 1.96 * sqrt(c.upvote_count::float8 * c.downvote_count / (c.upvote_count + c.downvote_count) + 0.9604) / (c.upvote_count + c.downvote_count))
+			/ (1 + 3.8416 / (c.upvote_count + c.downvote_count))
+		END)
+		+ 0.1 * greatest(0, 1 - EXTRACT(EPOCH FROM (NOW() - c.created_at)) / 3600))::float8`
+)
+
+// commentSortRankExpr returns the SQL computing a comment's rank for sort
+// Sorts that order by columns alone have no rank.
+func commentSortRankExpr(sort string) string {
+	switch sort {
+	case "best":
+		return commentBestRankExpr
+	case "top", "new":
+		return "NULL::float8"
+	default:
+		return commentHotRankExpr
+	}
+}
+
 // buildCommentSortClause returns the ORDER BY SQL and optional time filter
 func (r *postgresCommentRepo) buildCommentSortClause(sort, timeframe string) (string, string) {
 	var orderBy string
 	switch sort {
+	case "best":
+		// Best rank DESC, then score DESC as tiebreaker, then created_at DESC, then uri DESC
+		orderBy = `sort_rank DESC, c.score DESC, c.created_at DESC, c.uri DESC`
 	case "hot":
 		// Hot rank DESC, then score DESC as tiebreaker, then created_at DESC, then uri DESC
-		orderBy = `hot_rank DESC, c.score DESC, c.created_at DESC, c.uri DESC`
+		orderBy = `sort_rank DESC, c.score DESC, c.created_at DESC, c.uri DESC`
 	case "top":
 		// Score DESC, then created_at DESC, then uri DESC
 		orderBy = `c.score DESC, c.created_at DESC, c.uri DESC`
@@ -711,7 +1100,7 @@ func (r *postgresCommentRepo) buildCommentSortClause(sort, timeframe string) (st
 		orderBy = `c.created_at DESC, c.uri DESC`
 	default:
 		// Default to hot
-		orderBy = `hot_rank DESC, c.score DESC, c.created_at DESC, c.uri DESC`
+		orderBy = `sort_rank DESC, c.score DESC, c.created_at DESC, c.uri DESC`
 	}
 
 	// Add time filter for "top" sort
@@ -754,27 +1143,19 @@ func (r *postgresCommentRepo) parseCommentCursor(cursor *string, sort string) (s
 		return "", nil, nil
 	}
 
-	// Validate cursor size to prevent DoS via massive base64 strings
-	const maxCursorSize = 1024
-	if len(*cursor) > maxCursorSize {
-		return "", nil, fmt.Errorf("cursor too large: maximum %d bytes", maxCursorSize)
-	}
-
-	// Decode base64 cursor
-	decoded, err := base64.URLEncoding.DecodeString(*cursor)
+	// Cursor fields by sort type:
+	//   best: bestRank, score, createdAt, uri
+	//   hot: hotRank, score, createdAt, uri
+	//   top: score, createdAt, uri
+	//   new: createdAt, uri
+	parts, err := r.cursors.Decode(*cursor)
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid cursor encoding")
+		return "", nil, err
 	}
 
-	// Parse cursor based on sort type using | delimiter
-	// Format: hotRank|score|createdAt|uri (for hot)
-	//         score|createdAt|uri (for top)
-	//         createdAt|uri (for new)
-	parts := strings.Split(string(decoded), "|")
-
 	switch sort {
 	case "new":
-		// Cursor format: createdAt|uri
+		// Cursor fields: createdAt, uri
 		if len(parts) != 2 {
 			return "", nil, fmt.Errorf("invalid cursor format for new sort")
 		}
@@ -791,7 +1172,7 @@ func (r *postgresCommentRepo) parseCommentCursor(cursor *string, sort string) (s
 		return filter, []interface{}{createdAt, uri}, nil
 
 	case "top":
-		// Cursor format: score|createdAt|uri
+		// Cursor fields: score, createdAt, uri
 		if len(parts) != 3 {
 			return "", nil, fmt.Errorf("invalid cursor format for top sort")
 		}
@@ -814,21 +1195,21 @@ func (r *postgresCommentRepo) parseCommentCursor(cursor *string, sort string) (s
 		filter := `AND (c.score < $3 OR (c.score = $3 AND c.created_at < $4) OR (c.score = $3 AND c.created_at = $4 AND c.uri < $5))`
 		return filter, []interface{}{score, createdAt, uri}, nil
 
-	case "hot":
-		// Cursor format: hotRank|score|createdAt|uri
+	case "hot", "best":
+		// Cursor fields: rank (hot or best), score, createdAt, uri
 		if len(parts) != 4 {
-			return "", nil, fmt.Errorf("invalid cursor format for hot sort")
+			return "", nil, fmt.Errorf("invalid cursor format for %s sort", sort)
 		}
 
-		hotRankStr := parts[0]
+		rankStr := parts[0]
 		scoreStr := parts[1]
 		createdAt := parts[2]
 		uri := parts[3]
 
-		// Parse hot_rank as float
-		hotRank := 0.0
-		if _, err := fmt.Sscanf(hotRankStr, "%f", &hotRank); err != nil {
-			return "", nil, fmt.Errorf("invalid cursor hot rank")
+		// Parse rank as float
+		rank := 0.0
+		if _, err := fmt.Sscanf(rankStr, "%g", &rank); err != nil {
+			return "", nil, fmt.Errorf("invalid cursor %s rank", sort)
 		}
 
 		// Parse score as integer
@@ -842,60 +1223,46 @@ func (r *postgresCommentRepo) parseCommentCursor(cursor *string, sort string) (s
 			return "", nil, fmt.Errorf("invalid cursor URI")
 		}
 
-		// Use computed hot_rank expression in comparison
-		hotRankExpr := `log(greatest(2, c.score + 2)) / power(((EXTRACT(EPOCH FROM (NOW() - c.created_at)) / 3600) + 2), 1.8)`
+		// Use computed rank expression in comparison
+		rankExpr := commentSortRankExpr(sort)
 		filter := fmt.Sprintf(`AND ((%s < $3 OR (%s = $3 AND c.score < $4) OR (%s = $3 AND c.score = $4 AND c.created_at < $5) OR (%s = $3 AND c.score = $4 AND c.created_at = $5 AND c.uri < $6)) AND c.uri != $7)`,
-			hotRankExpr, hotRankExpr, hotRankExpr, hotRankExpr)
-		return filter, []interface{}{hotRank, score, createdAt, uri, uri}, nil
+			rankExpr, rankExpr, rankExpr, rankExpr)
+		return filter, []interface{}{rank, score, createdAt, uri, uri}, nil
 
 	default:
 		return "", nil, nil
 	}
 }
 
-// buildCommentCursor creates pagination cursor from last comment
-func (r *postgresCommentRepo) buildCommentCursor(comment *comments.Comment, sort string, hotRank float64) string {
-	var cursorStr string
-	const delimiter = "|"
+// buildCommentCursor creates a signed pagination cursor from last comment
+func (r *postgresCommentRepo) buildCommentCursor(comment *comments.Comment, sort string, sortRank float64) string {
+	createdAt := comment.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00")
 
 	switch sort {
 	case "new":
-		// Format: createdAt|uri
-		cursorStr = fmt.Sprintf("%s%s%s",
-			comment.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00"),
-			delimiter,
-			comment.URI)
+		// Fields: createdAt, uri
+		return r.cursors.Encode(createdAt, comment.URI)
 
 	case "top":
-		// Format: score|createdAt|uri
-		cursorStr = fmt.Sprintf("%d%s%s%s%s",
-			comment.Score,
-			delimiter,
-			comment.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00"),
-			delimiter,
-			comment.URI)
+		// Fields: score, createdAt, uri
+		return r.cursors.Encode(strconv.Itoa(comment.Score), createdAt, comment.URI)
 
 	case "hot":
-		// Format: hotRank|score|createdAt|uri
-		cursorStr = fmt.Sprintf("%f%s%d%s%s%s%s",
-			hotRank,
-			delimiter,
-			comment.Score,
-			delimiter,
-			comment.CreatedAt.Format("2006-01-02T15:04:05.999999999Z07:00"),
-			delimiter,
-			comment.URI)
+		// Fields: hotRank, score, createdAt, uri
+		return r.cursors.Encode(fmt.Sprintf("%f", sortRank), strconv.Itoa(comment.Score), createdAt, comment.URI)
+
+	case "best":
+		// Fields: bestRank, score, createdAt, uri
+		// Best ranks are compared exactly, so the rank keeps full precision
+		return r.cursors.Encode(strconv.FormatFloat(sortRank, 'g', -1, 64), strconv.Itoa(comment.Score), createdAt, comment.URI)
 
 	default:
-		cursorStr = comment.URI
+		return r.cursors.Encode(comment.URI)
 	}
-
-	return base64.URLEncoding.EncodeToString([]byte(cursorStr))
 }
 
-// GetByURIsBatch retrieves multiple comments by their AT-URIs in a single query
+// GetByURIsBatch retrieves multiple non-deleted comments by their AT-URIs in a single query
 // Returns map[uri]*Comment for efficient lookups without N+1 queries
-// Includes deleted comments to preserve thread structure
 func (r *postgresCommentRepo) GetByURIsBatch(ctx context.Context, uris []string) (map[string]*comments.Comment, error) {
 	if len(uris) == 0 {
 		return make(map[string]*comments.Comment), nil
@@ -903,19 +1270,18 @@ func (r *postgresCommentRepo) GetByURIsBatch(ctx context.Context, uris []string)
 
 	// LEFT JOIN prevents data loss when user record hasn't been indexed yet (out-of-order Jetstream events)
 	// COALESCE falls back to DID when handle is NULL (user not yet in users table)
-	// Includes deleted comments to preserve thread structure (shown as "[deleted]" placeholders)
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
 			c.id, c.uri, c.cid, c.rkey, c.commenter_did,
 			c.root_uri, c.root_cid, c.parent_uri, c.parent_cid,
 			c.content, c.content_facets, c.embed, c.content_labels, c.langs,
-			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by,
+			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by, c.edited_at,
 			c.upvote_count, c.downvote_count, c.score, c.reply_count,
 			COALESCE(u.handle, c.commenter_did) as author_handle
 		FROM comments c
 		LEFT JOIN users u ON c.commenter_did = u.did
-		WHERE c.uri = ANY($1)
-	`
+		WHERE c.uri = ANY($1) AND %s
+	`, notDeleted("c"))
 
 	rows, err := r.db.QueryContext(ctx, query, pq.Array(uris))
 	if err != nil {
@@ -937,7 +1303,7 @@ func (r *postgresCommentRepo) GetByURIsBatch(ctx context.Context, uris []string)
 			&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
 			&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
 			&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
-			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy,
+			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
 			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
 			&authorHandle,
 		)
@@ -956,14 +1322,97 @@ func (r *postgresCommentRepo) GetByURIsBatch(ctx context.Context, uris []string)
 	return result, nil
 }
 
-// ListByParentsBatch retrieves direct replies to multiple parents in a single query
+// GetAncestorsWithDeleted retrieves up to maxHeight parent comments of a comment in a single query
+// Walks parent_uri with a recursive CTE instead of one lookup per level
+// Returns ancestors ordered root-first; includes deleted comments to preserve the chain
+// Taken-down ancestors are returned as moderator-removed stubs so their content never leaks
+func (r *postgresCommentRepo) GetAncestorsWithDeleted(ctx context.Context, commentURI string, maxHeight int) ([]*comments.Comment, error) {
+	if maxHeight <= 0 {
+		return []*comments.Comment{}, nil
+	}
+
+	// The recursion ends naturally at the root post (its URI isn't in the comments table)
+	// or at a parent that hasn't been indexed yet; height bounds it and guards against cycles
+	query := `
+		WITH RECURSIVE ancestors AS (
+			SELECT p.uri, p.parent_uri, 1 AS height
+			FROM comments c
+			JOIN comments p ON p.uri = c.parent_uri
+			WHERE c.uri = $1
+
+			UNION ALL
+
+			SELECT p.uri, p.parent_uri, a.height + 1
+			FROM ancestors a
+			JOIN comments p ON p.uri = a.parent_uri
+			WHERE a.height < $2
+		)
+		SELECT
+			c.id, c.uri, c.cid, c.rkey, c.commenter_did,
+			c.root_uri, c.root_cid, c.parent_uri, c.parent_cid,
+			c.content, c.content_facets, c.embed, c.content_labels, c.langs,
+			c.created_at, c.indexed_at,
+			CASE WHEN c.taken_down_at IS NULL THEN c.deleted_at ELSE COALESCE(c.deleted_at, c.taken_down_at) END,
+			CASE WHEN c.taken_down_at IS NULL THEN c.deletion_reason ELSE 'moderator' END,
+			c.deleted_by, c.edited_at,
+			c.upvote_count, c.downvote_count, c.score, c.reply_count,
+			COALESCE(u.handle, c.commenter_did) as author_handle
+		FROM ancestors a
+		JOIN comments c ON c.uri = a.uri
+		LEFT JOIN users u ON c.commenter_did = u.did
+		ORDER BY a.height DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, commentURI, maxHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get comment ancestors: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+	}()
+
+	result := make([]*comments.Comment, 0, maxHeight)
+	for rows.Next() {
+		var comment comments.Comment
+		var langs pq.StringArray
+		var authorHandle string
+
+		err := rows.Scan(
+			&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
+			&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
+			&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
+			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
+			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
+			&authorHandle,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+
+		comment.Langs = langs
+		result = append(result, &comment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating comment ancestors: %w", err)
+	}
+
+	return result, nil
+}
+
+// ListByParentsBatchWithDeleted retrieves direct replies to multiple parents in a single query
 // Groups results by parent URI to prevent N+1 queries when loading nested replies
 // Uses window functions to limit results per parent efficiently
-func (r *postgresCommentRepo) ListByParentsBatch(
+// Hidden comments (and so their subtrees) are excluded, except author_only comments when
+// viewerDID is their author
+func (r *postgresCommentRepo) ListByParentsBatchWithDeleted(
 	ctx context.Context,
 	parentURIs []string,
 	sort string,
 	limitPerParent int,
+	viewerDID string,
 ) (map[string][]*comments.Comment, error) {
 	if len(parentURIs) == 0 {
 		return make(map[string][]*comments.Comment), nil
@@ -971,58 +1420,32 @@ func (r *postgresCommentRepo) ListByParentsBatch(
 
 	// Build ORDER BY clause based on sort type
 	// windowOrderBy must inline expressions (can't use SELECT aliases in window functions)
-	var windowOrderBy string
-	var selectClause string
-	switch sort {
-	case "hot":
-		selectClause = `
+	rankExpr := commentSortRankExpr(sort)
+	selectClause := fmt.Sprintf(`
 			c.id, c.uri, c.cid, c.rkey, c.commenter_did,
 			c.root_uri, c.root_cid, c.parent_uri, c.parent_cid,
 			c.content, c.content_facets, c.embed, c.content_labels, c.langs,
-			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by,
-			c.upvote_count, c.downvote_count, c.score, c.reply_count,
-			log(greatest(2, c.score + 2)) / power(((EXTRACT(EPOCH FROM (NOW() - c.created_at)) / 3600) + 2), 1.8) as hot_rank,
-			COALESCE(u.handle, c.commenter_did) as author_handle`
-		// CRITICAL: Must inline hot_rank formula - PostgreSQL doesn't allow SELECT aliases in window ORDER BY
-		windowOrderBy = `log(greatest(2, c.score + 2)) / power(((EXTRACT(EPOCH FROM (NOW() - c.created_at)) / 3600) + 2), 1.8) DESC, c.score DESC, c.created_at DESC`
+			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by, c.edited_at,
+			c.upvote_count, c.downvote_count, c.score, c.reply_count, c.depth_exceeded,
+			%s as sort_rank,
+			COALESCE(u.handle, c.commenter_did) as author_handle`, rankExpr)
+	var windowOrderBy string
+	switch sort {
 	case "top":
-		selectClause = `
-			c.id, c.uri, c.cid, c.rkey, c.commenter_did,
-			c.root_uri, c.root_cid, c.parent_uri, c.parent_cid,
-			c.content, c.content_facets, c.embed, c.content_labels, c.langs,
-			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by,
-			c.upvote_count, c.downvote_count, c.score, c.reply_count,
-			NULL::numeric as hot_rank,
-			COALESCE(u.handle, c.commenter_did) as author_handle`
 		windowOrderBy = `c.score DESC, c.created_at DESC`
 	case "new":
-		selectClause = `
-			c.id, c.uri, c.cid, c.rkey, c.commenter_did,
-			c.root_uri, c.root_cid, c.parent_uri, c.parent_cid,
-			c.content, c.content_facets, c.embed, c.content_labels, c.langs,
-			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by,
-			c.upvote_count, c.downvote_count, c.score, c.reply_count,
-			NULL::numeric as hot_rank,
-			COALESCE(u.handle, c.commenter_did) as author_handle`
 		windowOrderBy = `c.created_at DESC`
 	default:
-		// Default to hot
-		selectClause = `
-			c.id, c.uri, c.cid, c.rkey, c.commenter_did,
-			c.root_uri, c.root_cid, c.parent_uri, c.parent_cid,
-			c.content, c.content_facets, c.embed, c.content_labels, c.langs,
-			c.created_at, c.indexed_at, c.deleted_at, c.deletion_reason, c.deleted_by,
-			c.upvote_count, c.downvote_count, c.score, c.reply_count,
-			log(greatest(2, c.score + 2)) / power(((EXTRACT(EPOCH FROM (NOW() - c.created_at)) / 3600) + 2), 1.8) as hot_rank,
-			COALESCE(u.handle, c.commenter_did) as author_handle`
-		// CRITICAL: Must inline hot_rank formula - PostgreSQL doesn't allow SELECT aliases in window ORDER BY
-		windowOrderBy = `log(greatest(2, c.score + 2)) / power(((EXTRACT(EPOCH FROM (NOW() - c.created_at)) / 3600) + 2), 1.8) DESC, c.score DESC, c.created_at DESC`
+		// hot and best (and hot by default)
+		// CRITICAL: Must inline the rank formula - PostgreSQL doesn't allow SELECT aliases in window ORDER BY
+		windowOrderBy = rankExpr + ` DESC, c.score DESC, c.created_at DESC`
 	}
 
 	// Use window function to limit results per parent
 	// This is more efficient than LIMIT in a subquery per parent
 	// LEFT JOIN prevents data loss when user record hasn't been indexed yet (out-of-order Jetstream events)
 	// Includes deleted comments to preserve thread structure (shown as "[deleted]" placeholders)
+	// Orphaned comments (root post never indexed) are excluded
 	query := fmt.Sprintf(`
 		WITH ranked_comments AS (
 			SELECT
@@ -1034,20 +1457,22 @@ func (r *postgresCommentRepo) ListByParentsBatch(
 			FROM comments c
 			LEFT JOIN users u ON c.commenter_did = u.did
 			WHERE c.parent_uri = ANY($1)
+				AND NOT c.orphaned
+				AND %s
 		)
 		SELECT
 			id, uri, cid, rkey, commenter_did,
 			root_uri, root_cid, parent_uri, parent_cid,
 			content, content_facets, embed, content_labels, langs,
-			created_at, indexed_at, deleted_at, deletion_reason, deleted_by,
-			upvote_count, downvote_count, score, reply_count,
-			hot_rank, author_handle
+			created_at, indexed_at, deleted_at, deletion_reason, deleted_by, edited_at,
+			upvote_count, downvote_count, score, reply_count, depth_exceeded,
+			sort_rank, author_handle
 		FROM ranked_comments
 		WHERE rn <= $2
 		ORDER BY parent_uri, rn
-	`, selectClause, windowOrderBy)
+	`, selectClause, windowOrderBy, visibleTo("c", "commenter_did", "$3"))
 
-	rows, err := r.db.QueryContext(ctx, query, pq.Array(parentURIs), limitPerParent)
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(parentURIs), limitPerParent, viewerDID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to batch query comments by parents: %w", err)
 	}
@@ -1062,16 +1487,16 @@ func (r *postgresCommentRepo) ListByParentsBatch(
 	for rows.Next() {
 		var comment comments.Comment
 		var langs pq.StringArray
-		var hotRank sql.NullFloat64
+		var sortRank sql.NullFloat64
 		var authorHandle string
 
 		err := rows.Scan(
 			&comment.ID, &comment.URI, &comment.CID, &comment.RKey, &comment.CommenterDID,
 			&comment.RootURI, &comment.RootCID, &comment.ParentURI, &comment.ParentCID,
 			&comment.Content, &comment.ContentFacets, &comment.Embed, &comment.ContentLabels, &langs,
-			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy,
-			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount,
-			&hotRank, &authorHandle,
+			&comment.CreatedAt, &comment.IndexedAt, &comment.DeletedAt, &comment.DeletionReason, &comment.DeletedBy, &comment.EditedAt,
+			&comment.UpvoteCount, &comment.DownvoteCount, &comment.Score, &comment.ReplyCount, &comment.DepthExceeded,
+			&sortRank, &authorHandle,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan comment: %w", err)
@@ -1103,17 +1528,17 @@ func (r *postgresCommentRepo) GetVoteStateForComments(ctx context.Context, viewe
 	// Query votes table for viewer's votes on these comments
 	// Note: This assumes votes table exists and is being indexed
 	// If votes table doesn't exist yet, this query will fail gracefully
-	query := `
+	query := fmt.Sprintf(`
 		SELECT subject_uri, direction, uri
 		FROM votes
-		WHERE voter_did = $1 AND subject_uri = ANY($2) AND deleted_at IS NULL
-	`
+		WHERE voter_did = $1 AND subject_uri = ANY($2) AND %s
+	`, notDeleted(""))
 
 	rows, err := r.db.QueryContext(ctx, query, viewerDID, pq.Array(commentURIs))
 	if err != nil {
 		// If votes table doesn't exist yet, return empty map instead of error
 		// This allows the API to work before votes indexing is fully implemented
-		if strings.Contains(err.Error(), "does not exist") {
+		if _, ok := constraintViolation(err, pqUndefinedTable); ok {
 			log.Printf("WARN: Votes table does not exist, returning empty vote state for %d comments", len(commentURIs))
 			return make(map[string]interface{}), nil
 		}
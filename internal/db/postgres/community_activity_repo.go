@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/db/txrunner"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type postgresCommunityActivityRepo struct {
+	db *sql.DB
+}
+
+// NewCommunityActivityRepository creates a new PostgreSQL repository for community active users
+func NewCommunityActivityRepository(db *sql.DB) communities.ActivityRepository {
+	return &postgresCommunityActivityRepo{db: db}
+}
+
+// RecordActivity inserts the user's activity row for the UTC day, once per day
+func (r *postgresCommunityActivityRepo) RecordActivity(ctx context.Context, communityDID, userDID string, at time.Time) error {
+	query := `
+		INSERT INTO community_activity (community_did, user_did, day)
+		VALUES ($1, $2, $3::date)
+		ON CONFLICT DO NOTHING`
+
+	if _, err := txrunner.From(ctx, r.db).ExecContext(ctx, query, communityDID, userDID, activityDay(at)); err != nil {
+		return fmt.Errorf("failed to record community activity: %w", err)
+	}
+	return nil
+}
+
+// RecordSubjectActivity looks up the subject's community and records the activity there
+// Comments resolve through their root post, which carries the community.
+func (r *postgresCommunityActivityRepo) RecordSubjectActivity(ctx context.Context, subjectURI, userDID string, at time.Time) error {
+	query := `
+		INSERT INTO community_activity (community_did, user_did, day)
+		SELECT p.community_did, $2, $3::date
+		FROM posts p
+		WHERE p.uri = $1
+		   OR p.uri = (SELECT c.root_uri FROM comments c WHERE c.uri = $1)
+		LIMIT 1
+		ON CONFLICT DO NOTHING`
+
+	if _, err := txrunner.From(ctx, r.db).ExecContext(ctx, query, subjectURI, userDID, activityDay(at)); err != nil {
+		return fmt.Errorf("failed to record community activity: %w", err)
+	}
+	return nil
+}
+
+// RollupActiveUsers counts distinct users per community over the weekly and monthly windows
+// Communities with no recent activity are reset to zero; unchanged rows aren't rewritten.
+func (r *postgresCommunityActivityRepo) RollupActiveUsers(ctx context.Context, now time.Time) (int64, error) {
+	query := `
+		WITH actives AS (
+			SELECT community_did,
+				COUNT(DISTINCT user_did) FILTER (WHERE day > $1::date - $2::int) AS weekly,
+				COUNT(DISTINCT user_did) AS monthly
+			FROM community_activity
+			WHERE day > $1::date - $3::int
+			GROUP BY community_did
+		)
+		UPDATE communities c
+		SET weekly_active_users = COALESCE(a.weekly, 0),
+			monthly_active_users = COALESCE(a.monthly, 0)
+		FROM communities c2
+		LEFT JOIN actives a ON a.community_did = c2.did
+		WHERE c.id = c2.id
+		  AND (c.weekly_active_users <> COALESCE(a.weekly, 0)
+		       OR c.monthly_active_users <> COALESCE(a.monthly, 0))`
+
+	result, err := r.db.ExecContext(ctx, query, activityDay(now),
+		communities.WeeklyActiveDays, communities.MonthlyActiveDays)
+	if err != nil {
+		return 0, fmt.Errorf("failed to roll up community active users: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rollup result: %w", err)
+	}
+	return updated, nil
+}
+
+// PruneActivity deletes activity rows older than the retention cutoff
+func (r *postgresCommunityActivityRepo) PruneActivity(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM community_activity WHERE day < $1::date`, activityDay(before))
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune community activity: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check prune result: %w", err)
+	}
+	return deleted, nil
+}
+
+// activityDay formats t's UTC calendar day, so the server's time zone never decides the bucket
+func activityDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+type postgresCommunityCategoryRepo struct {
+	db *sql.DB
+}
+
+// NewCommunityCategoryRepository creates a new PostgreSQL repository for community category counts
+func NewCommunityCategoryRepository(db *sql.DB) communities.CategoryRepository {
+	return &postgresCommunityCategoryRepo{db: db}
+}
+
+// categoryFilter returns the condition that the categories column holds a category in the
+// bucket, and its argument as placeholder $argNum
+// A known category is a containment test the GIN index answers; CategoryOther matches
+// communities listing anything outside the taxonomy.
+func categoryFilter(column, category string, argNum int) (string, interface{}) {
+	if category == communities.CategoryOther {
+		return fmt.Sprintf("NOT (%s <@ $%d::text[])", column, argNum), pq.Array(communities.Categories)
+	}
+	return fmt.Sprintf("%s @> $%d::text[]", column, argNum), pq.Array([]string{category})
+}
+
+// CountCommunities counts listed communities per category bucket
+// The exclusions match community.list, so a category's count is what filtering the list by it
+// returns.
+func (r *postgresCommunityCategoryRepo) CountCommunities(ctx context.Context) (map[string]int, error) {
+	query := `
+		SELECT bucket, COUNT(DISTINCT c.id)
+		FROM communities c
+		CROSS JOIN LATERAL (
+			SELECT CASE WHEN category = ANY($1::text[]) THEN category ELSE $2 END AS bucket
+			FROM unnest(c.categories) AS category
+		) buckets
+		WHERE c.federation_blocked = FALSE
+		AND c.hosted_by_unverified = FALSE
+			AND c.impersonation_flag = FALSE
+			AND c.suspended_at IS NULL
+			AND c.deleted_at IS NULL
+		GROUP BY bucket`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(communities.Categories), communities.CategoryOther)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count communities by category: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var bucket string
+		var count int
+		if err := rows.Scan(&bucket, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan category count: %w", err)
+		}
+		counts[bucket] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating category counts: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+type postgresCreationPolicyRepo struct {
+	db *sql.DB
+}
+
+// NewCommunityCreationPolicyRepository creates a new PostgreSQL repository for community creation limits
+func NewCommunityCreationPolicyRepository(db *sql.DB) communities.CreationPolicyRepository {
+	return &postgresCreationPolicyRepo{db: db}
+}
+
+// lockCreatorCreationsSQL serializes creation claims per creator, so concurrent claims can't
+// both count under the limit
+const lockCreatorCreationsSQL = `SELECT pg_advisory_xact_lock(hashtextextended('community_creations:' || $1, 0))`
+
+// ClaimCreation counts the creator's creations since the given time and records a new one in
+// the same transaction, under a per-creator lock
+func (r *postgresCreationPolicyRepo) ClaimCreation(ctx context.Context, creatorDID, name string, since time.Time, max int) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, lockCreatorCreationsSQL, creatorDID); err != nil {
+		return 0, fmt.Errorf("failed to lock community creations: %w", err)
+	}
+
+	if max > 0 {
+		var count int
+		err := tx.QueryRowContext(ctx, `
+			SELECT COUNT(*)
+			FROM community_creations
+			WHERE creator_did = $1 AND created_at > $2`, creatorDID, since).Scan(&count)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count community creations: %w", err)
+		}
+		if count >= max {
+			return 0, communities.ErrTooManyCommunities
+		}
+	}
+
+	var id int64
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO community_creations (creator_did, name)
+		VALUES ($1, $2)
+		RETURNING id`, creatorDID, name).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to record community creation: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit community creation: %w", err)
+	}
+	return id, nil
+}
+
+// CompleteCreation stores the DID of the community a claimed creation created
+func (r *postgresCreationPolicyRepo) CompleteCreation(ctx context.Context, id int64, communityDID string) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE community_creations SET community_did = $2 WHERE id = $1`, id, communityDID)
+	if err != nil {
+		return fmt.Errorf("failed to complete community creation: %w", err)
+	}
+	return nil
+}
+
+// ReleaseCreation removes the claim of a creation that failed, unless it was completed
+func (r *postgresCreationPolicyRepo) ReleaseCreation(ctx context.Context, id int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM community_creations WHERE id = $1 AND community_did IS NULL`, id)
+	if err != nil {
+		return fmt.Errorf("failed to release community creation: %w", err)
+	}
+	return nil
+}
+
+// GetAccountCreatedAt returns when the user was first indexed by this AppView
+func (r *postgresCreationPolicyRepo) GetAccountCreatedAt(ctx context.Context, did string) (time.Time, error) {
+	var createdAt time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT created_at FROM users WHERE did = $1`, did).Scan(&createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Unindexed accounts have no age we can vouch for
+		return time.Time{}, communities.ErrAccountTooNew
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get account creation time: %w", err)
+	}
+	return createdAt, nil
+}
+
+// IsNameReserved reports whether an admin has reserved the name
+func (r *postgresCreationPolicyRepo) IsNameReserved(ctx context.Context, name string) (bool, error) {
+	var reserved bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM reserved_community_names WHERE name = LOWER($1))`, name,
+	).Scan(&reserved)
+	if err != nil {
+		return false, fmt.Errorf("failed to check reserved name: %w", err)
+	}
+	return reserved, nil
+}
+
+// ListReservedNames returns admin-reserved names ordered by name
+func (r *postgresCreationPolicyRepo) ListReservedNames(ctx context.Context) ([]*communities.ReservedName, error) {
+	query := `
+		SELECT name, COALESCE(reason, ''), COALESCE(added_by, ''), created_at
+		FROM reserved_community_names
+		ORDER BY name`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reserved names: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var names []*communities.ReservedName
+	for rows.Next() {
+		reserved := &communities.ReservedName{}
+		if err := rows.Scan(&reserved.Name, &reserved.Reason, &reserved.AddedBy, &reserved.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reserved name: %w", err)
+		}
+		names = append(names, reserved)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating reserved names: %w", err)
+	}
+	return names, nil
+}
+
+// AddReservedName reserves a name, updating the reason if it is already reserved
+func (r *postgresCreationPolicyRepo) AddReservedName(ctx context.Context, name *communities.ReservedName) error {
+	query := `
+		INSERT INTO reserved_community_names (name, reason, added_by)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''))
+		ON CONFLICT (name) DO UPDATE SET
+			reason = EXCLUDED.reason,
+			added_by = EXCLUDED.added_by
+		RETURNING created_at`
+
+	if err := r.db.QueryRowContext(ctx, query, name.Name, name.Reason, name.AddedBy).Scan(&name.CreatedAt); err != nil {
+		return fmt.Errorf("failed to reserve name: %w", err)
+	}
+	return nil
+}
+
+// RemoveReservedName releases an admin-reserved name
+func (r *postgresCreationPolicyRepo) RemoveReservedName(ctx context.Context, name string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM reserved_community_names WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("failed to remove reserved name: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check delete result: %w", err)
+	}
+	if rows == 0 {
+		return communities.ErrReservedNameNotFound
+	}
+	return nil
+}
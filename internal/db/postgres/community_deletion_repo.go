@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/db/txrunner"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type postgresCommunityDeletionRepo struct {
+	db *sql.DB
+}
+
+// NewCommunityDeletionRepository creates a new PostgreSQL repository for community deletion state
+func NewCommunityDeletionRepository(db *sql.DB) communities.DeletionRepository {
+	return &postgresCommunityDeletionRepo{db: db}
+}
+
+// MarkDeleted soft-deletes a community that isn't already deleted
+func (r *postgresCommunityDeletionRepo) MarkDeleted(ctx context.Context, communityDID, deletedByDID string) (time.Time, error) {
+	query := `
+		UPDATE communities
+		SET deleted_at = NOW(), deleted_by_did = $2, updated_at = NOW()
+		WHERE did = $1 AND deleted_at IS NULL
+		RETURNING deleted_at`
+
+	var deletedAt time.Time
+	err := txrunner.From(ctx, r.db).QueryRowContext(ctx, query, communityDID, deletedByDID).Scan(&deletedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, communities.ErrCommunityDeleted
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to mark community deleted: %w", err)
+	}
+	return deletedAt, nil
+}
+
+// Restore clears a deletion that is still inside its grace period
+func (r *postgresCommunityDeletionRepo) Restore(ctx context.Context, communityDID string, deletedAfter time.Time) error {
+	query := `
+		UPDATE communities
+		SET deleted_at = NULL, deleted_by_did = NULL, updated_at = NOW()
+		WHERE did = $1 AND deleted_at > $2 AND pds_deactivated_at IS NULL`
+
+	result, err := txrunner.From(ctx, r.db).ExecContext(ctx, query, communityDID, deletedAfter)
+	if err != nil {
+		return fmt.Errorf("failed to restore community: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check restore result: %w", err)
+	}
+	if rows == 0 {
+		return communities.ErrDeletionGracePeriodOver
+	}
+	return nil
+}
+
+// ListDueForDeactivation returns deleted communities past the cutoff whose PDS account is still active
+func (r *postgresCommunityDeletionRepo) ListDueForDeactivation(ctx context.Context, deletedBefore time.Time, limit int) ([]string, error) {
+	query := `
+		SELECT did
+		FROM communities
+		WHERE deleted_at IS NOT NULL AND deleted_at <= $1 AND pds_deactivated_at IS NULL
+		ORDER BY deleted_at ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, deletedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list communities due for deactivation: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var dids []string
+	for rows.Next() {
+		var did string
+		if err := rows.Scan(&did); err != nil {
+			return nil, fmt.Errorf("failed to scan community DID: %w", err)
+		}
+		dids = append(dids, did)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating communities due for deactivation: %w", err)
+	}
+	return dids, nil
+}
+
+// MarkPDSDeactivated records that the community's PDS account was deactivated
+func (r *postgresCommunityDeletionRepo) MarkPDSDeactivated(ctx context.Context, communityDID string) error {
+	query := `UPDATE communities SET pds_deactivated_at = NOW() WHERE did = $1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.ExecContext(ctx, query, communityDID)
+	if err != nil {
+		return fmt.Errorf("failed to mark community PDS account deactivated: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check deactivation result: %w", err)
+	}
+	if rows == 0 {
+		return communities.ErrCommunityNotFound
+	}
+	return nil
+}
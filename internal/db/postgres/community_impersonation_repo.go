@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+type postgresImpersonationRepo struct {
+	db *sql.DB
+}
+
+// NewImpersonationRepository creates a new PostgreSQL repository for reviewing communities
+// flagged as impersonating others
+func NewImpersonationRepository(db *sql.DB) communities.ImpersonationRepository {
+	return &postgresImpersonationRepo{db: db}
+}
+
+// ListFlaggedCommunities returns flagged communities awaiting review, most recently updated first
+func (r *postgresImpersonationRepo) ListFlaggedCommunities(ctx context.Context, limit, offset int) ([]*communities.Community, error) {
+	query := `
+		SELECT did, handle, name, COALESCE(display_name, ''), hosted_by_did,
+			COALESCE(impersonation_reason, ''), federation_blocked, created_at, updated_at
+		FROM communities
+		WHERE impersonation_flag = TRUE AND suspended_at IS NULL
+		ORDER BY updated_at DESC, did ASC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list flagged communities: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	result := []*communities.Community{}
+	for rows.Next() {
+		c := &communities.Community{ImpersonationFlag: true}
+		if err := rows.Scan(&c.DID, &c.Handle, &c.Name, &c.DisplayName, &c.HostedByDID,
+			&c.ImpersonationReason, &c.FederationBlocked, &c.CreatedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan flagged community: %w", err)
+		}
+		result = append(result, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating flagged communities: %w", err)
+	}
+	return result, nil
+}
+
+// ClearImpersonationFlag unflags a community and remembers the cleared reason, so indexing
+// the same profile again doesn't re-flag it
+func (r *postgresImpersonationRepo) ClearImpersonationFlag(ctx context.Context, did string) error {
+	query := `
+		UPDATE communities
+		SET impersonation_flag = FALSE,
+			impersonation_cleared_reason = impersonation_reason,
+			impersonation_reason = NULL,
+			updated_at = NOW()
+		WHERE did = $1 AND impersonation_flag = TRUE AND suspended_at IS NULL`
+
+	return r.review(ctx, query, did)
+}
+
+// ConfirmImpersonation suspends a flagged community
+func (r *postgresImpersonationRepo) ConfirmImpersonation(ctx context.Context, did string) error {
+	query := `
+		UPDATE communities
+		SET suspended_at = NOW(), updated_at = NOW()
+		WHERE did = $1 AND impersonation_flag = TRUE AND suspended_at IS NULL`
+
+	return r.review(ctx, query, did)
+}
+
+// review runs a review update, distinguishing unknown communities from ones not awaiting review
+func (r *postgresImpersonationRepo) review(ctx context.Context, query, did string) error {
+	result, err := r.db.ExecContext(ctx, query, did)
+	if err != nil {
+		return fmt.Errorf("failed to review flagged community: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check review result: %w", err)
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM communities WHERE did = $1)`, did).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check community: %w", err)
+	}
+	if !exists {
+		return communities.ErrCommunityNotFound
+	}
+	return communities.ErrCommunityNotFlagged
+}
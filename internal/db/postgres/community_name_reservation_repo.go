@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type postgresCommunityNameReservationRepo struct {
+	db *sql.DB
+}
+
+// NewCommunityNameReservationRepository creates a new PostgreSQL repository for names held by
+// community creations in flight
+func NewCommunityNameReservationRepository(db *sql.DB) communities.NameReservationRepository {
+	return &postgresCommunityNameReservationRepo{db: db}
+}
+
+// Reserve inserts the reservation, or takes over one that has expired
+// A concurrent insert of the same name waits on the primary key until the first commits, then
+// finds it unexpired and updates nothing.
+func (r *postgresCommunityNameReservationRepo) Reserve(ctx context.Context, name, token string, expiresAt time.Time) error {
+	query := `
+		INSERT INTO community_name_reservations (name, token, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (name) DO UPDATE SET
+			token = EXCLUDED.token,
+			reserved_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE community_name_reservations.expires_at <= NOW()`
+
+	result, err := r.db.ExecContext(ctx, query, name, token, expiresAt)
+	if err != nil {
+		return fmt.Errorf("failed to reserve community name: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check reservation result: %w", err)
+	}
+	if rows == 0 {
+		return communities.ErrHandleTaken
+	}
+	return nil
+}
+
+// Release deletes the reservation if token still holds it
+func (r *postgresCommunityNameReservationRepo) Release(ctx context.Context, name, token string) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM community_name_reservations WHERE name = $1 AND token = $2`, name, token)
+	if err != nil {
+		return fmt.Errorf("failed to release community name: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired deletes reservations that expired before cutoff
+// Uses idx_community_name_reservations_expires
+func (r *postgresCommunityNameReservationRepo) DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM community_name_reservations WHERE expires_at < $1`, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired name reservations: %w", err)
+	}
+	return result.RowsAffected()
+}
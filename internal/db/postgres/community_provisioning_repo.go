@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type postgresCommunityProvisioningRepo struct {
+	db *sql.DB
+}
+
+// NewCommunityProvisioningRepository creates a new PostgreSQL repository for community creation progress
+func NewCommunityProvisioningRepository(db *sql.DB) communities.ProvisioningRepository {
+	return &postgresCommunityProvisioningRepo{db: db}
+}
+
+// provisioningColumns selects a provisioning row, decrypting the account password
+const provisioningColumns = `
+	name, created_by_did, community_did, handle, pds_url, pds_email,
+	pgp_sym_decrypt(pds_password_encrypted, (SELECT encode(key_data, 'hex') FROM encryption_keys WHERE id = 1)),
+	state, COALESCE(last_error, ''), created_at, updated_at`
+
+// Save upserts the provisioning for the community name
+func (r *postgresCommunityProvisioningRepo) Save(ctx context.Context, p *communities.Provisioning) error {
+	query := `
+		INSERT INTO community_provisioning (
+			name, created_by_did, community_did, handle, pds_url, pds_email,
+			pds_password_encrypted, state, last_error
+		) VALUES (
+			$1, $2, $3, $4, $5, $6,
+			pgp_sym_encrypt($7, (SELECT encode(key_data, 'hex') FROM encryption_keys WHERE id = 1)),
+			$8, $9
+		)
+		ON CONFLICT (name) DO UPDATE SET
+			created_by_did = EXCLUDED.created_by_did,
+			community_did = EXCLUDED.community_did,
+			handle = EXCLUDED.handle,
+			pds_url = EXCLUDED.pds_url,
+			pds_email = EXCLUDED.pds_email,
+			pds_password_encrypted = EXCLUDED.pds_password_encrypted,
+			state = EXCLUDED.state,
+			last_error = EXCLUDED.last_error,
+			updated_at = NOW()
+		RETURNING created_at, updated_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		p.Name, p.CreatedByDID, p.DID, p.Handle, p.PDSURL, p.PDSEmail,
+		p.PDSPassword, p.State, nullString(p.LastError),
+	).Scan(&p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save community provisioning: %w", err)
+	}
+	return nil
+}
+
+// Get returns the provisioning for a community name
+func (r *postgresCommunityProvisioningRepo) Get(ctx context.Context, name string) (*communities.Provisioning, error) {
+	query := `SELECT ` + provisioningColumns + ` FROM community_provisioning WHERE name = $1`
+
+	p, err := scanProvisioning(r.db.QueryRowContext(ctx, query, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, communities.ErrProvisioningNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get community provisioning: %w", err)
+	}
+	return p, nil
+}
+
+// ListStuck returns unfinished provisionings not touched since updatedBefore, oldest first
+// Uses idx_community_provisioning_stuck
+func (r *postgresCommunityProvisioningRepo) ListStuck(ctx context.Context, updatedBefore time.Time, limit int) ([]*communities.Provisioning, error) {
+	query := `SELECT ` + provisioningColumns + `
+		FROM community_provisioning
+		WHERE state <> 'indexed' AND updated_at < $1
+		ORDER BY updated_at ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, updatedBefore, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stuck community provisionings: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	result := []*communities.Provisioning{}
+	for rows.Next() {
+		p, scanErr := scanProvisioning(rows)
+		if scanErr != nil {
+			return nil, fmt.Errorf("failed to scan community provisioning: %w", scanErr)
+		}
+		result = append(result, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating community provisionings: %w", err)
+	}
+	return result, nil
+}
+
+// Delete forgets the provisioning for a community name
+func (r *postgresCommunityProvisioningRepo) Delete(ctx context.Context, name string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM community_provisioning WHERE name = $1`, name)
+	if err != nil {
+		return fmt.Errorf("failed to delete community provisioning: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check delete result: %w", err)
+	}
+	if rows == 0 {
+		return communities.ErrProvisioningNotFound
+	}
+	return nil
+}
+
+func scanProvisioning(row interface{ Scan(...any) error }) (*communities.Provisioning, error) {
+	p := &communities.Provisioning{}
+	err := row.Scan(
+		&p.Name, &p.CreatedByDID, &p.DID, &p.Handle, &p.PDSURL, &p.PDSEmail,
+		&p.PDSPassword, &p.State, &p.LastError, &p.CreatedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
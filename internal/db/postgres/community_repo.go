@@ -2,8 +2,11 @@ package postgres
 
 import (
 	"Coves/internal/core/communities"
+	"Coves/internal/core/confusables"
+	"Coves/internal/pagination"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"log"
 	"strings"
@@ -12,12 +15,14 @@ import (
 )
 
 type postgresCommunityRepo struct {
-	db *sql.DB
+	db      *sql.DB
+	cursors *pagination.Signer
 }
 
 // NewCommunityRepository creates a new PostgreSQL community repository
-func NewCommunityRepository(db *sql.DB) communities.Repository {
-	return &postgresCommunityRepo{db: db}
+// cursors signs the subscription listing cursors
+func NewCommunityRepository(db *sql.DB, cursors *pagination.Signer) communities.Repository {
+	return &postgresCommunityRepo{db: db, cursors: cursors}
 }
 
 // Create inserts a new community into the communities table
@@ -36,7 +41,9 @@ func (r *postgresCommunityRepo) Create(ctx context.Context, community *communiti
 			visibility, allow_external_discovery, moderation_type, content_warnings,
 			member_count, subscriber_count, post_count,
 			federated_from, federated_id, created_at, updated_at,
-			record_uri, record_cid
+			record_uri, record_cid, raw_record, federation_blocked, flairs, posting_rules,
+			score_hiding_hours, impersonation_flag, impersonation_reason,
+			collapse_threshold, crowd_control, rev, handle_skeleton, categories, hosted_by_unverified
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
 			$12,
@@ -45,7 +52,8 @@ func (r *postgresCommunityRepo) Create(ctx context.Context, community *communiti
 			CASE WHEN $15 != '' THEN pgp_sym_encrypt($15, (SELECT encode(key_data, 'hex') FROM encryption_keys WHERE id = 1)) ELSE NULL END,
 			$16,
 			$17, $18, $19, $20,
-			$21, $22, $23, $24, $25, $26, $27, $28, $29
+			$21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33,
+			$34, $35, $36, $37, $38, $39, $40, COALESCE($41::text[], '{}'), $42
 		)
 		RETURNING id, created_at, updated_at`
 
@@ -57,6 +65,7 @@ func (r *postgresCommunityRepo) Create(ctx context.Context, community *communiti
 		descFacets = nil
 	}
 
+	skeleton := confusables.HandleSkeleton(community.Handle)
 	err := r.db.QueryRowContext(ctx, query,
 		community.DID,
 		community.Handle, // Always non-empty - constructed by AppView consumer
@@ -89,20 +98,37 @@ func (r *postgresCommunityRepo) Create(ctx context.Context, community *communiti
 		community.UpdatedAt,
 		nullString(community.RecordURI),
 		nullString(community.RecordCID),
+		community.RawRecord,
+		community.FederationBlocked,
+		marshalFlairs(community.Flairs),
+		marshalPostingRules(community.PostingRules),
+		community.ScoreHidingHours,
+		community.ImpersonationFlag,
+		nullString(community.ImpersonationReason),
+		community.CollapseThreshold,
+		communities.NormalizeCrowdControl(community.CrowdControl),
+		nullString(community.RecordRev),
+		skeleton,
+		pq.Array(community.Categories),
+		community.HostedByUnverified,
 	).Scan(&community.ID, &community.CreatedAt, &community.UpdatedAt)
 	if err != nil {
 		// Check for unique constraint violations
-		if strings.Contains(err.Error(), "duplicate key") {
-			if strings.Contains(err.Error(), "communities_did_key") {
+		if constraint, ok := constraintViolation(err, pqUniqueViolation); ok {
+			switch constraint {
+			case "communities_did_key":
 				return nil, communities.ErrCommunityAlreadyExists
-			}
-			if strings.Contains(err.Error(), "communities_handle_key") {
+			case "communities_handle_key":
 				return nil, communities.ErrHandleTaken
 			}
 		}
 		return nil, fmt.Errorf("failed to create community: %w", err)
 	}
 
+	if community.ConfusableWith, err = flagConfusableHandle(ctx, r.db, communitiesConfusableTable, community.DID, community.Handle, skeleton); err != nil {
+		return nil, err
+	}
+
 	return community, nil
 }
 
@@ -136,14 +162,19 @@ func (r *postgresCommunityRepo) GetByDID(ctx context.Context, did string) (*comm
 			visibility, allow_external_discovery, moderation_type, content_warnings,
 			member_count, subscriber_count, post_count,
 			federated_from, federated_id, created_at, updated_at,
-			record_uri, record_cid
+			record_uri, record_cid, COALESCE(rev, ''), federation_blocked, flairs, posting_rules,
+			score_hiding_hours, impersonation_flag, COALESCE(impersonation_reason, ''),
+			COALESCE(impersonation_cleared_reason, ''), suspended_at,
+			deleted_at, COALESCE(deleted_by_did, ''), pds_deactivated_at,
+			weekly_active_users, monthly_active_users,
+			collapse_threshold, crowd_control, remote, raw_record, categories
 		FROM communities
 		WHERE did = $1`
 
 	var displayName, description, avatarCID, bannerCID, moderationType sql.NullString
 	var federatedFrom, federatedID, recordURI, recordCID sql.NullString
 	var pdsEmail, pdsPassword, pdsAccessToken, pdsRefreshToken, pdsURL sql.NullString
-	var descFacets []byte
+	var descFacets, flairsJSON, postingRulesJSON []byte
 	var contentWarnings []string
 
 	err := r.db.QueryRowContext(ctx, query, did).Scan(
@@ -158,7 +189,13 @@ func (r *postgresCommunityRepo) GetByDID(ctx context.Context, did string) (*comm
 		&community.MemberCount, &community.SubscriberCount, &community.PostCount,
 		&federatedFrom, &federatedID,
 		&community.CreatedAt, &community.UpdatedAt,
-		&recordURI, &recordCID,
+		&recordURI, &recordCID, &community.RecordRev, &community.FederationBlocked, &flairsJSON, &postingRulesJSON,
+		&community.ScoreHidingHours, &community.ImpersonationFlag, &community.ImpersonationReason,
+		&community.ImpersonationCleared, &community.SuspendedAt,
+		&community.DeletedAt, &community.DeletedByDID, &community.PDSDeactivatedAt,
+		&community.WeeklyActiveUsers, &community.MonthlyActiveUsers,
+		&community.CollapseThreshold, &community.CrowdControl, &community.Remote, &community.RawRecord,
+		pq.Array(&community.Categories),
 	)
 
 	if err == sql.ErrNoRows {
@@ -187,6 +224,8 @@ func (r *postgresCommunityRepo) GetByDID(ctx context.Context, did string) (*comm
 	community.FederatedID = federatedID.String
 	community.RecordURI = recordURI.String
 	community.RecordCID = recordCID.String
+	community.Flairs = unmarshalFlairs(community.DID, flairsJSON)
+	community.PostingRules = unmarshalPostingRules(community.DID, postingRulesJSON)
 	if descFacets != nil {
 		community.DescriptionFacets = descFacets
 	}
@@ -203,13 +242,18 @@ func (r *postgresCommunityRepo) GetByHandle(ctx context.Context, handle string)
 			visibility, allow_external_discovery, moderation_type, content_warnings,
 			member_count, subscriber_count, post_count,
 			federated_from, federated_id, created_at, updated_at,
-			record_uri, record_cid
+			record_uri, record_cid, COALESCE(rev, ''), federation_blocked, flairs, posting_rules,
+			score_hiding_hours, impersonation_flag, COALESCE(impersonation_reason, ''),
+			COALESCE(impersonation_cleared_reason, ''), suspended_at,
+			deleted_at, COALESCE(deleted_by_did, ''), pds_deactivated_at,
+			weekly_active_users, monthly_active_users,
+			collapse_threshold, crowd_control, remote, categories
 		FROM communities
 		WHERE handle = $1`
 
 	var displayName, description, avatarCID, bannerCID, moderationType sql.NullString
 	var federatedFrom, federatedID, recordURI, recordCID sql.NullString
-	var descFacets []byte
+	var descFacets, flairsJSON, postingRulesJSON []byte
 	var contentWarnings []string
 
 	err := r.db.QueryRowContext(ctx, query, handle).Scan(
@@ -222,7 +266,13 @@ func (r *postgresCommunityRepo) GetByHandle(ctx context.Context, handle string)
 		&community.MemberCount, &community.SubscriberCount, &community.PostCount,
 		&federatedFrom, &federatedID,
 		&community.CreatedAt, &community.UpdatedAt,
-		&recordURI, &recordCID,
+		&recordURI, &recordCID, &community.RecordRev, &community.FederationBlocked, &flairsJSON, &postingRulesJSON,
+		&community.ScoreHidingHours, &community.ImpersonationFlag, &community.ImpersonationReason,
+		&community.ImpersonationCleared, &community.SuspendedAt,
+		&community.DeletedAt, &community.DeletedByDID, &community.PDSDeactivatedAt,
+		&community.WeeklyActiveUsers, &community.MonthlyActiveUsers,
+		&community.CollapseThreshold, &community.CrowdControl, &community.Remote,
+		pq.Array(&community.Categories),
 	)
 
 	if err == sql.ErrNoRows {
@@ -243,6 +293,8 @@ func (r *postgresCommunityRepo) GetByHandle(ctx context.Context, handle string)
 	community.FederatedID = federatedID.String
 	community.RecordURI = recordURI.String
 	community.RecordCID = recordCID.String
+	community.Flairs = unmarshalFlairs(community.DID, flairsJSON)
+	community.PostingRules = unmarshalPostingRules(community.DID, postingRulesJSON)
 	if descFacets != nil {
 		community.DescriptionFacets = descFacets
 	}
@@ -259,7 +311,12 @@ func (r *postgresCommunityRepo) Update(ctx context.Context, community *communiti
 			visibility = $7, allow_external_discovery = $8,
 			moderation_type = $9, content_warnings = $10,
 			updated_at = NOW(),
-			record_uri = $11, record_cid = $12
+			record_uri = $11, record_cid = $12,
+			raw_record = COALESCE($13::jsonb, raw_record),
+			federation_blocked = $14, flairs = $15, posting_rules = $16,
+			score_hiding_hours = $17, impersonation_flag = $18, impersonation_reason = $19,
+			collapse_threshold = $20, crowd_control = $21, remote = $22,
+			rev = COALESCE(NULLIF($23, ''), rev), categories = COALESCE($24::text[], '{}')
 		WHERE did = $1
 		RETURNING updated_at`
 
@@ -271,6 +328,7 @@ func (r *postgresCommunityRepo) Update(ctx context.Context, community *communiti
 		descFacets = nil
 	}
 
+	// A nil RawRecord keeps the stored raw record (non-firehose updates)
 	err := r.db.QueryRowContext(ctx, query,
 		community.DID,
 		nullString(community.DisplayName),
@@ -284,6 +342,18 @@ func (r *postgresCommunityRepo) Update(ctx context.Context, community *communiti
 		pq.Array(community.ContentWarnings),
 		nullString(community.RecordURI),
 		nullString(community.RecordCID),
+		community.RawRecord,
+		community.FederationBlocked,
+		marshalFlairs(community.Flairs),
+		marshalPostingRules(community.PostingRules),
+		community.ScoreHidingHours,
+		community.ImpersonationFlag,
+		nullString(community.ImpersonationReason),
+		community.CollapseThreshold,
+		communities.NormalizeCrowdControl(community.CrowdControl),
+		community.Remote,
+		community.RecordRev, // Only firehose updates carry a rev; others keep the stored one
+		pq.Array(community.Categories),
 	).Scan(&community.UpdatedAt)
 
 	if err == sql.ErrNoRows {
@@ -343,10 +413,28 @@ func (r *postgresCommunityRepo) Delete(ctx context.Context, did string) error {
 	return nil
 }
 
+// listedCommunityColumns are the community columns (aliased c) scanned by scanListedCommunity
+const listedCommunityColumns = `
+	c.id, c.did, c.handle, c.name, c.display_name, c.description, c.description_facets,
+	c.avatar_cid, c.banner_cid, c.owner_did, c.created_by_did, c.hosted_by_did,
+	c.visibility, c.allow_external_discovery, c.moderation_type, c.content_warnings,
+	c.member_count, c.subscriber_count, c.post_count,
+	c.federated_from, c.federated_id, c.created_at, c.updated_at,
+	c.record_uri, c.record_cid, c.pds_url,
+	c.weekly_active_users, c.monthly_active_users, c.categories, c.federation_blocked`
+
 // List retrieves communities with filtering and pagination
-func (r *postgresCommunityRepo) List(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error) {
+// The relevance sort with a viewer pages by signed keyset cursor and returns the next one;
+// every other sort pages by offset and returns a nil cursor.
+func (r *postgresCommunityRepo) List(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, *string, error) {
+	if req.Sort == communities.ListSortRelevance && req.ViewerDID != "" {
+		return r.listByRelevance(ctx, req)
+	}
+
 	// Build query with filters
-	whereClauses := []string{}
+	// Communities on instances blocked by federation policy or whose hostedBy claim isn't
+	// verified, flagged as impersonating another community, suspended or deleted are never listed
+	whereClauses := []string{"c.federation_blocked = FALSE", "c.hosted_by_unverified = FALSE", "c.impersonation_flag = FALSE", "c.suspended_at IS NULL", "c.deleted_at IS NULL"}
 	args := []interface{}{}
 	argCount := 1
 
@@ -364,8 +452,12 @@ func (r *postgresCommunityRepo) List(ctx context.Context, req communities.ListCo
 		argCount++
 	}
 
-	// TODO: Add category filter when DB schema supports it
-	// if req.Category != "" { ... }
+	if req.Category != "" {
+		clause, arg := categoryFilter("c.categories", req.Category, argCount)
+		whereClauses = append(whereClauses, clause)
+		args = append(args, arg)
+		argCount++
+	}
 
 	// TODO: Add language filter when DB schema supports it
 	// if req.Language != "" { ... }
@@ -384,8 +476,8 @@ func (r *postgresCommunityRepo) List(ctx context.Context, req communities.ListCo
 		sortColumn = "c.subscriber_count"
 		sortOrder = "DESC"
 	case "active":
-		// Most posts/activity
-		sortColumn = "c.post_count"
+		// Most unique weekly actives, then monthly actives and posts for quiet communities
+		sortColumn = "c.weekly_active_users DESC, c.monthly_active_users DESC, c.post_count"
 		sortOrder = "DESC"
 	case "new":
 		// Recently created
@@ -403,24 +495,19 @@ func (r *postgresCommunityRepo) List(ctx context.Context, req communities.ListCo
 
 	// Get communities with pagination
 	query := fmt.Sprintf(`
-		SELECT c.id, c.did, c.handle, c.name, c.display_name, c.description, c.description_facets,
-			c.avatar_cid, c.banner_cid, c.owner_did, c.created_by_did, c.hosted_by_did,
-			c.visibility, c.allow_external_discovery, c.moderation_type, c.content_warnings,
-			c.member_count, c.subscriber_count, c.post_count,
-			c.federated_from, c.federated_id, c.created_at, c.updated_at,
-			c.record_uri, c.record_cid, c.pds_url
+		SELECT %s
 		FROM communities c
 		%s
 		%s
 		ORDER BY %s %s
 		LIMIT $%d OFFSET $%d`,
-		joinClause, whereClause, sortColumn, sortOrder, argCount, argCount+1)
+		listedCommunityColumns, joinClause, whereClause, sortColumn, sortOrder, argCount, argCount+1)
 
 	args = append(args, req.Limit, req.Offset)
 
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list communities: %w", err)
+		return nil, nil, fmt.Errorf("failed to list communities: %w", err)
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
@@ -430,52 +517,64 @@ func (r *postgresCommunityRepo) List(ctx context.Context, req communities.ListCo
 
 	result := []*communities.Community{}
 	for rows.Next() {
-		community := &communities.Community{}
-		var displayName, description, avatarCID, bannerCID, moderationType sql.NullString
-		var federatedFrom, federatedID, recordURI, recordCID, pdsURL sql.NullString
-		var descFacets []byte
-		var contentWarnings []string
-
-		scanErr := rows.Scan(
-			&community.ID, &community.DID, &community.Handle, &community.Name,
-			&displayName, &description, &descFacets,
-			&avatarCID, &bannerCID,
-			&community.OwnerDID, &community.CreatedByDID, &community.HostedByDID,
-			&community.Visibility, &community.AllowExternalDiscovery,
-			&moderationType, pq.Array(&contentWarnings),
-			&community.MemberCount, &community.SubscriberCount, &community.PostCount,
-			&federatedFrom, &federatedID,
-			&community.CreatedAt, &community.UpdatedAt,
-			&recordURI, &recordCID, &pdsURL,
-		)
+		community, scanErr := scanListedCommunity(rows)
 		if scanErr != nil {
-			return nil, fmt.Errorf("failed to scan community: %w", scanErr)
+			return nil, nil, scanErr
 		}
-
-		// Map nullable fields
-		community.DisplayName = displayName.String
-		community.Description = description.String
-		community.AvatarCID = avatarCID.String
-		community.BannerCID = bannerCID.String
-		community.ModerationType = moderationType.String
-		community.ContentWarnings = contentWarnings
-		community.FederatedFrom = federatedFrom.String
-		community.FederatedID = federatedID.String
-		community.RecordURI = recordURI.String
-		community.RecordCID = recordCID.String
-		community.PDSURL = pdsURL.String
-		if descFacets != nil {
-			community.DescriptionFacets = descFacets
-		}
-
 		result = append(result, community)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating communities: %w", err)
+		return nil, nil, fmt.Errorf("error iterating communities: %w", err)
 	}
 
-	return result, nil
+	return result, nil, nil
+}
+
+// scanListedCommunity scans listedCommunityColumns, followed by any extra columns the
+// query selects into extra
+func scanListedCommunity(rows *sql.Rows, extra ...interface{}) (*communities.Community, error) {
+	community := &communities.Community{}
+	var displayName, description, avatarCID, bannerCID, moderationType sql.NullString
+	var federatedFrom, federatedID, recordURI, recordCID, pdsURL sql.NullString
+	var descFacets []byte
+	var contentWarnings []string
+
+	dest := []interface{}{
+		&community.ID, &community.DID, &community.Handle, &community.Name,
+		&displayName, &description, &descFacets,
+		&avatarCID, &bannerCID,
+		&community.OwnerDID, &community.CreatedByDID, &community.HostedByDID,
+		&community.Visibility, &community.AllowExternalDiscovery,
+		&moderationType, pq.Array(&contentWarnings),
+		&community.MemberCount, &community.SubscriberCount, &community.PostCount,
+		&federatedFrom, &federatedID,
+		&community.CreatedAt, &community.UpdatedAt,
+		&recordURI, &recordCID, &pdsURL,
+		&community.WeeklyActiveUsers, &community.MonthlyActiveUsers, pq.Array(&community.Categories),
+		&community.FederationBlocked,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return nil, fmt.Errorf("failed to scan community: %w", err)
+	}
+
+	// Map nullable fields
+	community.DisplayName = displayName.String
+	community.Description = description.String
+	community.AvatarCID = avatarCID.String
+	community.BannerCID = bannerCID.String
+	community.ModerationType = moderationType.String
+	community.ContentWarnings = contentWarnings
+	community.FederatedFrom = federatedFrom.String
+	community.FederatedID = federatedID.String
+	community.RecordURI = recordURI.String
+	community.RecordCID = recordCID.String
+	community.PDSURL = pdsURL.String
+	if descFacets != nil {
+		community.DescriptionFacets = descFacets
+	}
+
+	return community, nil
 }
 
 // Search searches communities by name/description using fuzzy matching
@@ -483,6 +582,11 @@ func (r *postgresCommunityRepo) Search(ctx context.Context, req communities.Sear
 	// Build query with fuzzy search and visibility filter
 	whereClauses := []string{
 		"(name ILIKE '%' || $1 || '%' OR description ILIKE '%' || $1 || '%')",
+		"federation_blocked = FALSE",   // Hide communities on instances blocked by federation policy
+		"hosted_by_unverified = FALSE", // Hide communities whose hosting instance isn't verified
+		"impersonation_flag = FALSE",   // Hide communities awaiting impersonation review
+		"suspended_at IS NULL",
+		"deleted_at IS NULL", // Hide communities deleted by their owner, even inside the grace period
 	}
 	args := []interface{}{req.Query}
 	argCount := 2
@@ -493,6 +597,13 @@ func (r *postgresCommunityRepo) Search(ctx context.Context, req communities.Sear
 		argCount++
 	}
 
+	if req.Category != "" {
+		clause, arg := categoryFilter("categories", req.Category, argCount)
+		whereClauses = append(whereClauses, clause)
+		args = append(args, arg)
+		argCount++
+	}
+
 	whereClause := "WHERE " + strings.Join(whereClauses, " AND ")
 
 	// Get total count
@@ -505,17 +616,18 @@ func (r *postgresCommunityRepo) Search(ctx context.Context, req communities.Sear
 
 	// Search with relevance ranking using pg_trgm similarity
 	// Filter out results with very low relevance (< 0.2) to avoid noise
+	// Equally relevant communities rank by size, then by how much has been posted
 	query := fmt.Sprintf(`
 		SELECT id, did, handle, name, display_name, description, description_facets,
 			avatar_cid, banner_cid, owner_did, created_by_did, hosted_by_did,
 			visibility, allow_external_discovery, moderation_type, content_warnings,
 			member_count, subscriber_count, post_count,
 			federated_from, federated_id, created_at, updated_at,
-			record_uri, record_cid, pds_url,
+			record_uri, record_cid, pds_url, categories, federation_blocked,
 			similarity(name, $1) + similarity(COALESCE(description, ''), $1) as relevance
 		FROM communities
 		%s AND (similarity(name, $1) + similarity(COALESCE(description, ''), $1)) > 0.2
-		ORDER BY relevance DESC, member_count DESC
+		ORDER BY relevance DESC, member_count DESC, post_count DESC
 		LIMIT $%d OFFSET $%d`,
 		whereClause, argCount, argCount+1)
 
@@ -550,8 +662,8 @@ func (r *postgresCommunityRepo) Search(ctx context.Context, req communities.Sear
 			&community.MemberCount, &community.SubscriberCount, &community.PostCount,
 			&federatedFrom, &federatedID,
 			&community.CreatedAt, &community.UpdatedAt,
-			&recordURI, &recordCID, &pdsURL,
-			&relevance,
+			&recordURI, &recordCID, &pdsURL, pq.Array(&community.Categories),
+			&community.FederationBlocked, &relevance,
 		)
 		if scanErr != nil {
 			return nil, 0, fmt.Errorf("failed to scan community: %w", scanErr)
@@ -587,3 +699,53 @@ func (r *postgresCommunityRepo) Search(ctx context.Context, req communities.Sear
 func nullString(s string) sql.NullString {
 	return sql.NullString{String: s, Valid: s != ""}
 }
+
+// marshalFlairs encodes a flair set for the flairs JSONB column (never NULL)
+func marshalFlairs(flairs []communities.Flair) []byte {
+	if len(flairs) == 0 {
+		return []byte("[]")
+	}
+	data, err := json.Marshal(flairs)
+	if err != nil {
+		return []byte("[]")
+	}
+	return data
+}
+
+// unmarshalFlairs decodes the flairs JSONB column, returning nil for an empty set
+func unmarshalFlairs(communityDID string, data []byte) []communities.Flair {
+	if len(data) == 0 {
+		return nil
+	}
+	var flairs []communities.Flair
+	if err := json.Unmarshal(data, &flairs); err != nil {
+		log.Printf("WARNING: Failed to parse flairs for community %s: %v", communityDID, err)
+		return nil
+	}
+	if len(flairs) == 0 {
+		return nil
+	}
+	return flairs
+}
+
+// marshalPostingRules encodes posting rules for the posting_rules JSONB column
+func marshalPostingRules(rules communities.PostingRules) []byte {
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return []byte("{}")
+	}
+	return data
+}
+
+// unmarshalPostingRules decodes the posting_rules JSONB column, defaulting to no rules
+func unmarshalPostingRules(communityDID string, data []byte) communities.PostingRules {
+	var rules communities.PostingRules
+	if len(data) == 0 {
+		return rules
+	}
+	if err := json.Unmarshal(data, &rules); err != nil {
+		log.Printf("WARNING: Failed to parse posting rules for community %s: %v", communityDID, err)
+		return communities.PostingRules{}
+	}
+	return rules
+}
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"time"
 )
 
 // BlockCommunity creates a new block record (idempotent)
@@ -16,7 +17,8 @@ func (r *postgresCommunityRepo) BlockCommunity(ctx context.Context, block *commu
 		ON CONFLICT (user_did, community_did) DO UPDATE SET
 			record_uri = EXCLUDED.record_uri,
 			record_cid = EXCLUDED.record_cid,
-			blocked_at = EXCLUDED.blocked_at
+			blocked_at = EXCLUDED.blocked_at,
+			pending_confirmation = FALSE
 		RETURNING id, blocked_at`
 
 	err := r.db.QueryRowContext(ctx, query,
@@ -33,6 +35,73 @@ func (r *postgresCommunityRepo) BlockCommunity(ctx context.Context, block *commu
 	return block, nil
 }
 
+// BlockCommunityPending indexes a block written through the AppView before its Jetstream
+// event arrives, flagged pending so the reaper can remove it if the event never does
+// An existing block (e.g. the event won the race) is left untouched
+func (r *postgresCommunityRepo) BlockCommunityPending(ctx context.Context, block *communities.CommunityBlock) (*communities.CommunityBlock, error) {
+	query := `
+		INSERT INTO community_blocks (user_did, community_did, blocked_at, record_uri, record_cid, pending_confirmation)
+		VALUES ($1, $2, $3, $4, $5, TRUE)
+		ON CONFLICT (user_did, community_did) DO NOTHING
+		RETURNING id, blocked_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		block.UserDID,
+		block.CommunityDID,
+		block.BlockedAt,
+		block.RecordURI,
+		block.RecordCID,
+	).Scan(&block.ID, &block.BlockedAt)
+	if err == sql.ErrNoRows {
+		return r.GetBlock(ctx, block.UserDID, block.CommunityDID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pending block: %w", err)
+	}
+
+	return block, nil
+}
+
+// ConfirmBlock clears the pending flag on a locally indexed block when its Jetstream event
+// arrives, matching on the record URI
+// Returns false if no pending block has that URI
+func (r *postgresCommunityRepo) ConfirmBlock(ctx context.Context, recordURI, recordCID string) (bool, error) {
+	query := `
+		UPDATE community_blocks
+		SET pending_confirmation = FALSE, record_cid = $2
+		WHERE record_uri = $1 AND pending_confirmation`
+
+	result, err := r.db.ExecContext(ctx, query, recordURI, recordCID)
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm block: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check confirm result: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// DeleteUnconfirmedBlocks removes pending blocks indexed before the cutoff whose Jetstream
+// event never arrived
+func (r *postgresCommunityRepo) DeleteUnconfirmedBlocks(ctx context.Context, before time.Time) (int, error) {
+	query := `DELETE FROM community_blocks WHERE pending_confirmation AND blocked_at < $1`
+
+	result, err := r.db.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete unconfirmed blocks: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check delete result: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
 // UnblockCommunity removes a block record
 func (r *postgresCommunityRepo) UnblockCommunity(ctx context.Context, userDID, communityDID string) error {
 	query := `DELETE FROM community_blocks WHERE user_did = $1 AND community_did = $2`
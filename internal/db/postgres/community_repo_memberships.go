@@ -2,6 +2,7 @@ package postgres
 
 import (
 	"Coves/internal/core/communities"
+	"Coves/internal/db/txrunner"
 	"context"
 	"database/sql"
 	"fmt"
@@ -30,10 +31,10 @@ func (r *postgresCommunityRepo) CreateMembership(ctx context.Context, membership
 		membership.IsModerator,
 	).Scan(&membership.ID, &membership.JoinedAt, &membership.LastActiveAt)
 	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
-			return nil, fmt.Errorf("membership already exists")
+		if _, ok := constraintViolation(err, pqUniqueViolation); ok {
+			return nil, communities.ErrMembershipAlreadyExists
 		}
-		if strings.Contains(err.Error(), "foreign key") {
+		if _, ok := constraintViolation(err, pqForeignKeyViolation); ok {
 			return nil, communities.ErrCommunityNotFound
 		}
 		return nil, fmt.Errorf("failed to create membership: %w", err)
@@ -154,6 +155,109 @@ func (r *postgresCommunityRepo) ListMembers(ctx context.Context, communityDID st
 	return result, nil
 }
 
+// GetModeratedCommunityDIDs returns which of the given communities userDID created or moderates
+func (r *postgresCommunityRepo) GetModeratedCommunityDIDs(ctx context.Context, userDID string, communityDIDs []string) (map[string]bool, error) {
+	if userDID == "" || len(communityDIDs) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	// Build query with placeholders for IN clause
+	placeholders := make([]string, len(communityDIDs))
+	args := make([]interface{}, len(communityDIDs)+1)
+	args[0] = userDID
+	for i, did := range communityDIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+		args[i+1] = did
+	}
+	in := strings.Join(placeholders, ", ")
+
+	query := fmt.Sprintf(`
+		SELECT did FROM communities
+		WHERE created_by_did = $1 AND did IN (%[1]s)
+		UNION
+		SELECT community_did FROM community_memberships
+		WHERE user_did = $1 AND is_moderator = TRUE AND community_did IN (%[1]s)`,
+		in)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderated communities: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	result := make(map[string]bool)
+	for rows.Next() {
+		var communityDID string
+		if err := rows.Scan(&communityDID); err != nil {
+			return nil, fmt.Errorf("failed to scan community DID: %w", err)
+		}
+		result[communityDID] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating moderated communities: %w", err)
+	}
+
+	return result, nil
+}
+
+// GetModeratorsForCommunities returns each community's creator and moderators, keyed by community DID
+// Communities without moderators are absent from the result
+func (r *postgresCommunityRepo) GetModeratorsForCommunities(ctx context.Context, communityDIDs []string) (map[string]map[string]bool, error) {
+	if len(communityDIDs) == 0 {
+		return map[string]map[string]bool{}, nil
+	}
+
+	// Build query with placeholders for IN clause
+	placeholders := make([]string, len(communityDIDs))
+	args := make([]interface{}, len(communityDIDs))
+	for i, did := range communityDIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = did
+	}
+	in := strings.Join(placeholders, ", ")
+
+	query := fmt.Sprintf(`
+		SELECT did, created_by_did FROM communities
+		WHERE did IN (%[1]s)
+		UNION
+		SELECT community_did, user_did FROM community_memberships
+		WHERE is_moderator = TRUE AND community_did IN (%[1]s)`,
+		in)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get community moderators: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	result := make(map[string]map[string]bool)
+	for rows.Next() {
+		var communityDID, moderatorDID string
+		if err := rows.Scan(&communityDID, &moderatorDID); err != nil {
+			return nil, fmt.Errorf("failed to scan community moderator: %w", err)
+		}
+		if result[communityDID] == nil {
+			result[communityDID] = make(map[string]bool)
+		}
+		result[communityDID][moderatorDID] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating community moderators: %w", err)
+	}
+
+	return result, nil
+}
+
 // CreateModerationAction records a moderation action
 func (r *postgresCommunityRepo) CreateModerationAction(ctx context.Context, action *communities.ModerationAction) (*communities.ModerationAction, error) {
 	query := `
@@ -163,7 +267,7 @@ func (r *postgresCommunityRepo) CreateModerationAction(ctx context.Context, acti
 		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id, created_at`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err := txrunner.From(ctx, r.db).QueryRowContext(ctx, query,
 		action.CommunityDID,
 		action.Action,
 		nullString(action.Reason),
@@ -173,7 +277,7 @@ func (r *postgresCommunityRepo) CreateModerationAction(ctx context.Context, acti
 		action.ExpiresAt,
 	).Scan(&action.ID, &action.CreatedAt)
 	if err != nil {
-		if strings.Contains(err.Error(), "foreign key") {
+		if _, ok := constraintViolation(err, pqForeignKeyViolation); ok {
 			return nil, communities.ErrCommunityNotFound
 		}
 		return nil, fmt.Errorf("failed to create moderation action: %w", err)
@@ -276,3 +380,32 @@ func (r *postgresCommunityRepo) IncrementPostCount(ctx context.Context, communit
 	}
 	return nil
 }
+
+// RecountPostCounts recomputes post_count from live (not deleted) posts
+// The post consumer keeps the count current; this repairs drift from events processed
+// before it counted, or lost to a crash mid-transaction
+func (r *postgresCommunityRepo) RecountPostCounts(ctx context.Context) (int, error) {
+	query := `
+		WITH actual AS (
+			SELECT c.did, COUNT(p.id) AS post_count
+			FROM communities c
+			LEFT JOIN posts p ON p.community_did = c.did AND p.deleted_at IS NULL
+			GROUP BY c.did
+		)
+		UPDATE communities c
+		SET post_count = actual.post_count
+		FROM actual
+		WHERE c.did = actual.did AND c.post_count <> actual.post_count`
+
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to recount posts: %w", err)
+	}
+
+	corrected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check recount result: %w", err)
+	}
+
+	return int(corrected), nil
+}
@@ -0,0 +1,212 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// relevanceWindow is how far back interactions and activity count toward the relevance sort
+const relevanceWindow = 30 * 24 * time.Hour
+
+// Relevance strata, in listing order
+const (
+	relevanceSubscribed = 0 // The viewer subscribes; ranked by most recent post
+	relevanceNetwork    = 1 // People the viewer interacts with are active here; ranked by how many
+	relevanceRest       = 2 // Everything else; ranked by subscribers
+)
+
+// listByRelevance lists communities in the order described by communities.ListSortRelevance
+// Every row gets a stratum and a rank key within it from one CASE ranking, so pages are cut
+// with a (stratum, rank key, id) keyset and never repeat or skip a community at a stratum
+// boundary. A community that moves between strata while the viewer pages (they subscribe,
+// say) may still appear twice, as with any keyset over live data.
+//
+// "People the viewer interacts with" are, within relevanceWindow: authors the viewer replied
+// to or upvoted, and people who replied to the viewer.
+func (r *postgresCommunityRepo) listByRelevance(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, *string, error) {
+	since := time.Now().Add(-relevanceWindow)
+
+	// Communities on instances blocked by federation policy or whose hostedBy claim isn't
+	// verified, flagged as impersonating another community, suspended or deleted are never listed
+	whereClauses := []string{"c.federation_blocked = FALSE", "c.hosted_by_unverified = FALSE", "c.impersonation_flag = FALSE", "c.suspended_at IS NULL", "c.deleted_at IS NULL"}
+	args := []interface{}{req.ViewerDID, since, activityDay(since)}
+	argCount := 4
+
+	joinClause := ""
+	if req.SubscriberDID != "" {
+		joinClause = fmt.Sprintf("INNER JOIN community_subscriptions sub ON c.did = sub.community_did AND sub.user_did = $%d", argCount)
+		args = append(args, req.SubscriberDID)
+		argCount++
+	}
+
+	if req.Visibility != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf("c.visibility = $%d", argCount))
+		args = append(args, req.Visibility)
+		argCount++
+	}
+
+	if req.Category != "" {
+		clause, arg := categoryFilter("c.categories", req.Category, argCount)
+		whereClauses = append(whereClauses, clause)
+		args = append(args, arg)
+		argCount++
+	}
+
+	cursorFilter := ""
+	if req.Cursor != "" {
+		stratum, rankKey, id, err := r.parseRelevanceCursor(req.Cursor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", communities.ErrInvalidCursor, err)
+		}
+		cursorFilter = fmt.Sprintf(`WHERE c.stratum > $%[1]d
+			OR (c.stratum = $%[1]d AND (c.rank_key < $%[2]d OR (c.rank_key = $%[2]d AND c.id < $%[3]d)))`,
+			argCount, argCount+1, argCount+2)
+		args = append(args, stratum, rankKey, id)
+		argCount += 3
+	}
+
+	query := fmt.Sprintf(`
+		WITH interacted AS (
+			-- Authors the viewer replied to
+			SELECT parent.commenter_did AS user_did
+			FROM comments mine
+			INNER JOIN comments parent ON parent.uri = mine.parent_uri
+			WHERE mine.commenter_did = $1 AND mine.created_at >= $2
+			UNION
+			SELECT p.author_did
+			FROM comments mine
+			INNER JOIN posts p ON p.uri = mine.parent_uri
+			WHERE mine.commenter_did = $1 AND mine.created_at >= $2
+			UNION
+			-- People who replied to the viewer
+			SELECT reply.commenter_did
+			FROM comments mine
+			INNER JOIN comments reply ON reply.parent_uri = mine.uri
+			WHERE mine.commenter_did = $1 AND reply.created_at >= $2
+			UNION
+			SELECT reply.commenter_did
+			FROM posts p
+			INNER JOIN comments reply ON reply.parent_uri = p.uri
+			WHERE p.author_did = $1 AND reply.created_at >= $2
+			UNION
+			-- Authors the viewer upvoted
+			SELECT cm.commenter_did
+			FROM votes v
+			INNER JOIN comments cm ON cm.uri = v.subject_uri
+			WHERE v.voter_did = $1 AND v.direction = 'up' AND v.deleted_at IS NULL AND v.created_at >= $2
+			UNION
+			SELECT p.author_did
+			FROM votes v
+			INNER JOIN posts p ON p.uri = v.subject_uri
+			WHERE v.voter_did = $1 AND v.direction = 'up' AND v.deleted_at IS NULL AND v.created_at >= $2
+		),
+		network_activity AS (
+			SELECT a.community_did, COUNT(DISTINCT a.user_did) AS active_users
+			FROM community_activity a
+			INNER JOIN interacted i ON i.user_did = a.user_did
+			WHERE a.user_did <> $1 AND a.day >= $3::date
+			GROUP BY a.community_did
+		),
+		ranked AS (
+			SELECT c.*,
+				CASE
+					WHEN s.community_did IS NOT NULL THEN %[1]d
+					WHEN n.active_users IS NOT NULL THEN %[2]d
+					ELSE %[3]d
+				END AS stratum,
+				(CASE
+					WHEN s.community_did IS NOT NULL THEN COALESCE(EXTRACT(EPOCH FROM c.last_post_at), 0)
+					WHEN n.active_users IS NOT NULL THEN n.active_users
+					ELSE c.subscriber_count
+				END)::double precision AS rank_key
+			FROM communities c
+			LEFT JOIN community_subscriptions s ON s.community_did = c.did AND s.user_did = $1
+			LEFT JOIN network_activity n ON n.community_did = c.did
+			%[4]s
+			WHERE %[5]s
+		)
+		SELECT %[6]s, c.stratum, c.rank_key
+		FROM ranked c
+		%[7]s
+		ORDER BY c.stratum ASC, c.rank_key DESC, c.id DESC
+		LIMIT $%[8]d`,
+		relevanceSubscribed, relevanceNetwork, relevanceRest,
+		joinClause, strings.Join(whereClauses, " AND "),
+		listedCommunityColumns, cursorFilter, argCount)
+
+	args = append(args, req.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list communities by relevance: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	result := []*communities.Community{}
+	var strata []int
+	var rankKeys []float64
+	for rows.Next() {
+		var stratum int
+		var rankKey float64
+		community, scanErr := scanListedCommunity(rows, &stratum, &rankKey)
+		if scanErr != nil {
+			return nil, nil, scanErr
+		}
+		result = append(result, community)
+		strata = append(strata, stratum)
+		rankKeys = append(rankKeys, rankKey)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating communities: %w", err)
+	}
+
+	var nextCursor *string
+	if len(result) > req.Limit && req.Limit > 0 {
+		result = result[:req.Limit]
+		last := req.Limit - 1
+		cursorStr := r.cursors.Encode(
+			strconv.Itoa(strata[last]),
+			strconv.FormatFloat(rankKeys[last], 'g', -1, 64),
+			strconv.Itoa(result[last].ID),
+		)
+		nextCursor = &cursorStr
+	}
+
+	return result, nextCursor, nil
+}
+
+// parseRelevanceCursor decodes a relevance listing cursor
+// Cursor fields: stratum, rank key, id
+func (r *postgresCommunityRepo) parseRelevanceCursor(cursor string) (int, float64, int, error) {
+	parts, err := r.cursors.Decode(cursor)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("invalid cursor format")
+	}
+
+	stratum, err := strconv.Atoi(parts[0])
+	if err != nil || stratum < relevanceSubscribed || stratum > relevanceRest {
+		return 0, 0, 0, fmt.Errorf("invalid cursor stratum")
+	}
+	rankKey, err := strconv.ParseFloat(parts[1], 64)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("invalid cursor rank")
+	}
+	id, err := strconv.Atoi(parts[2])
+	if err != nil || id <= 0 {
+		return 0, 0, 0, fmt.Errorf("invalid cursor id")
+	}
+
+	return stratum, rankKey, id, nil
+}
@@ -2,11 +2,15 @@ package postgres
 
 import (
 	"Coves/internal/core/communities"
+	"Coves/internal/db/txrunner"
 	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Subscribe creates a new subscription record
@@ -16,7 +20,7 @@ func (r *postgresCommunityRepo) Subscribe(ctx context.Context, subscription *com
 		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id, subscribed_at`
 
-	err := r.db.QueryRowContext(ctx, query,
+	err := txrunner.From(ctx, r.db).QueryRowContext(ctx, query,
 		subscription.UserDID,
 		subscription.CommunityDID,
 		subscription.SubscribedAt,
@@ -25,10 +29,10 @@ func (r *postgresCommunityRepo) Subscribe(ctx context.Context, subscription *com
 		subscription.ContentVisibility,
 	).Scan(&subscription.ID, &subscription.SubscribedAt)
 	if err != nil {
-		if strings.Contains(err.Error(), "duplicate key") {
+		if _, ok := constraintViolation(err, pqUniqueViolation); ok {
 			return nil, communities.ErrSubscriptionAlreadyExists
 		}
-		if strings.Contains(err.Error(), "foreign key") {
+		if _, ok := constraintViolation(err, pqForeignKeyViolation); ok {
 			return nil, communities.ErrCommunityNotFound
 		}
 		return nil, fmt.Errorf("failed to create subscription: %w", err)
@@ -40,9 +44,101 @@ func (r *postgresCommunityRepo) Subscribe(ctx context.Context, subscription *com
 // SubscribeWithCount atomically creates subscription and increments subscriber count
 // This is idempotent - safe for Jetstream replays
 func (r *postgresCommunityRepo) SubscribeWithCount(ctx context.Context, subscription *communities.Subscription) (*communities.Subscription, error) {
+	return r.subscribeWithCount(ctx, subscription, false)
+}
+
+// SubscribePendingWithCount indexes a subscription written through the AppView before its
+// Jetstream event arrives, flagged pending so the reaper can revert it if the event never does
+// An existing row (e.g. the event won the race) is left untouched and not counted again
+func (r *postgresCommunityRepo) SubscribePendingWithCount(ctx context.Context, subscription *communities.Subscription) (*communities.Subscription, error) {
+	return r.subscribeWithCount(ctx, subscription, true)
+}
+
+func (r *postgresCommunityRepo) subscribeWithCount(ctx context.Context, subscription *communities.Subscription, pending bool) (*communities.Subscription, error) {
+	err := txrunner.WithTx(ctx, r.db, func(ctx context.Context) error {
+		q := txrunner.From(ctx, r.db)
+
+		// Insert subscription with ON CONFLICT DO NOTHING for idempotency
+		query := `
+			INSERT INTO community_subscriptions (user_did, community_did, subscribed_at, record_uri, record_cid, content_visibility, pending_confirmation)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (user_did, community_did) DO NOTHING
+			RETURNING id, subscribed_at, content_visibility`
+
+		err := q.QueryRowContext(ctx, query,
+			subscription.UserDID,
+			subscription.CommunityDID,
+			subscription.SubscribedAt,
+			nullString(subscription.RecordURI),
+			nullString(subscription.RecordCID),
+			subscription.ContentVisibility,
+			pending,
+		).Scan(&subscription.ID, &subscription.SubscribedAt, &subscription.ContentVisibility)
+
+		// If no rows returned, subscription already existed (idempotent behavior)
+		if err == sql.ErrNoRows {
+			// Get existing subscription; don't increment count
+			query = `SELECT id, subscribed_at, content_visibility FROM community_subscriptions WHERE user_did = $1 AND community_did = $2`
+			err = q.QueryRowContext(ctx, query, subscription.UserDID, subscription.CommunityDID).Scan(&subscription.ID, &subscription.SubscribedAt, &subscription.ContentVisibility)
+			if err != nil {
+				return fmt.Errorf("failed to get existing subscription: %w", err)
+			}
+			return nil
+		}
+
+		if err != nil {
+			if _, ok := constraintViolation(err, pqForeignKeyViolation); ok {
+				return communities.ErrCommunityNotFound
+			}
+			return fmt.Errorf("failed to create subscription: %w", err)
+		}
+
+		// Increment subscriber count only if insert succeeded
+		incrementQuery := `
+			UPDATE communities
+			SET subscriber_count = subscriber_count + 1, updated_at = NOW()
+			WHERE did = $1`
+
+		if _, err := q.ExecContext(ctx, incrementQuery, subscription.CommunityDID); err != nil {
+			return fmt.Errorf("failed to increment subscriber count: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// ConfirmSubscription clears the pending flag on a locally indexed subscription when its
+// Jetstream event arrives, matching on the record URI
+// Returns false if no pending subscription has that URI
+func (r *postgresCommunityRepo) ConfirmSubscription(ctx context.Context, recordURI, recordCID string) (bool, error) {
+	query := `
+		UPDATE community_subscriptions
+		SET pending_confirmation = FALSE, record_cid = COALESCE($2, record_cid)
+		WHERE record_uri = $1 AND pending_confirmation`
+
+	result, err := r.db.ExecContext(ctx, query, recordURI, nullString(recordCID))
+	if err != nil {
+		return false, fmt.Errorf("failed to confirm subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check confirm result: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// DeleteUnconfirmedSubscriptions removes pending subscriptions indexed before the cutoff whose
+// Jetstream event never arrived, and reverts the subscriber counts they added
+func (r *postgresCommunityRepo) DeleteUnconfirmedSubscriptions(ctx context.Context, before time.Time) (int, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 	defer func() {
 		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
@@ -50,67 +146,66 @@ func (r *postgresCommunityRepo) SubscribeWithCount(ctx context.Context, subscrip
 		}
 	}()
 
-	// Insert subscription with ON CONFLICT DO NOTHING for idempotency
 	query := `
-		INSERT INTO community_subscriptions (user_did, community_did, subscribed_at, record_uri, record_cid, content_visibility)
-		VALUES ($1, $2, $3, $4, $5, $6)
-		ON CONFLICT (user_did, community_did) DO NOTHING
-		RETURNING id, subscribed_at, content_visibility`
-
-	err = tx.QueryRowContext(ctx, query,
-		subscription.UserDID,
-		subscription.CommunityDID,
-		subscription.SubscribedAt,
-		nullString(subscription.RecordURI),
-		nullString(subscription.RecordCID),
-		subscription.ContentVisibility,
-	).Scan(&subscription.ID, &subscription.SubscribedAt, &subscription.ContentVisibility)
+		WITH reaped AS (
+			DELETE FROM community_subscriptions
+			WHERE pending_confirmation AND subscribed_at < $1
+			RETURNING community_did
+		)
+		SELECT community_did, COUNT(*) FROM reaped GROUP BY community_did ORDER BY community_did`
 
-	// If no rows returned, subscription already existed (idempotent behavior)
-	if err == sql.ErrNoRows {
-		// Get existing subscription
-		query = `SELECT id, subscribed_at, content_visibility FROM community_subscriptions WHERE user_did = $1 AND community_did = $2`
-		err = tx.QueryRowContext(ctx, query, subscription.UserDID, subscription.CommunityDID).Scan(&subscription.ID, &subscription.SubscribedAt, &subscription.ContentVisibility)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get existing subscription: %w", err)
-		}
-		// Don't increment count - subscription already existed
-		if commitErr := tx.Commit(); commitErr != nil {
-			return nil, fmt.Errorf("failed to commit transaction: %w", commitErr)
-		}
-		return subscription, nil
+	rows, err := tx.QueryContext(ctx, query, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete unconfirmed subscriptions: %w", err)
 	}
 
-	if err != nil {
-		if strings.Contains(err.Error(), "foreign key") {
-			return nil, communities.ErrCommunityNotFound
+	// Rows come back in DID order, so concurrent reapers take row locks in the same order
+	type reapedCount struct {
+		communityDID string
+		count        int
+	}
+	var reaped []reapedCount
+	total := 0
+	for rows.Next() {
+		var rc reapedCount
+		if scanErr := rows.Scan(&rc.communityDID, &rc.count); scanErr != nil {
+			_ = rows.Close()
+			return 0, fmt.Errorf("failed to scan unconfirmed subscription: %w", scanErr)
 		}
-		return nil, fmt.Errorf("failed to create subscription: %w", err)
+		reaped = append(reaped, rc)
+		total += rc.count
+	}
+	if err = rows.Err(); err != nil {
+		_ = rows.Close()
+		return 0, fmt.Errorf("error iterating unconfirmed subscriptions: %w", err)
+	}
+	if err = rows.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close rows: %w", err)
 	}
 
-	// Increment subscriber count only if insert succeeded
-	incrementQuery := `
+	decrementQuery := `
 		UPDATE communities
-		SET subscriber_count = subscriber_count + 1, updated_at = NOW()
+		SET subscriber_count = GREATEST(0, subscriber_count - $2), updated_at = NOW()
 		WHERE did = $1`
 
-	_, err = tx.ExecContext(ctx, incrementQuery, subscription.CommunityDID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to increment subscriber count: %w", err)
+	for _, rc := range reaped {
+		if _, err := tx.ExecContext(ctx, decrementQuery, rc.communityDID, rc.count); err != nil {
+			return 0, fmt.Errorf("failed to revert subscriber count for %s: %w", rc.communityDID, err)
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return subscription, nil
+	return total, nil
 }
 
 // Unsubscribe removes a subscription record
 func (r *postgresCommunityRepo) Unsubscribe(ctx context.Context, userDID, communityDID string) error {
 	query := `DELETE FROM community_subscriptions WHERE user_did = $1 AND community_did = $2`
 
-	result, err := r.db.ExecContext(ctx, query, userDID, communityDID)
+	result, err := txrunner.From(ctx, r.db).ExecContext(ctx, query, userDID, communityDID)
 	if err != nil {
 		return fmt.Errorf("failed to unsubscribe: %w", err)
 	}
@@ -130,6 +225,53 @@ func (r *postgresCommunityRepo) Unsubscribe(ctx context.Context, userDID, commun
 // UnsubscribeWithCount atomically removes subscription and decrements subscriber count
 // This is idempotent - safe for Jetstream replays
 func (r *postgresCommunityRepo) UnsubscribeWithCount(ctx context.Context, userDID, communityDID string) error {
+	return txrunner.WithTx(ctx, r.db, func(ctx context.Context) error {
+		q := txrunner.From(ctx, r.db)
+
+		// Delete subscription
+		deleteQuery := `DELETE FROM community_subscriptions WHERE user_did = $1 AND community_did = $2`
+		result, err := q.ExecContext(ctx, deleteQuery, userDID, communityDID)
+		if err != nil {
+			return fmt.Errorf("failed to unsubscribe: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to check unsubscribe result: %w", err)
+		}
+
+		// If no rows deleted, subscription didn't exist (idempotent - not an error)
+		if rowsAffected == 0 {
+			return nil
+		}
+
+		// Decrement subscriber count only if delete succeeded
+		decrementQuery := `
+			UPDATE communities
+			SET subscriber_count = GREATEST(0, subscriber_count - 1), updated_at = NOW()
+			WHERE did = $1`
+
+		if _, err := q.ExecContext(ctx, decrementQuery, communityDID); err != nil {
+			return fmt.Errorf("failed to decrement subscriber count: %w", err)
+		}
+		return nil
+	})
+}
+
+// AdjustSubscriberCounts applies coalesced subscriber count deltas in one transaction
+// Each community gets a single UPDATE, taken in DID order so concurrent flushes can't deadlock
+func (r *postgresCommunityRepo) AdjustSubscriberCounts(ctx context.Context, deltas map[string]int) error {
+	dids := make([]string, 0, len(deltas))
+	for did, delta := range deltas {
+		if delta != 0 {
+			dids = append(dids, did)
+		}
+	}
+	if len(dids) == 0 {
+		return nil
+	}
+	sort.Strings(dids)
+
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
@@ -140,42 +282,51 @@ func (r *postgresCommunityRepo) UnsubscribeWithCount(ctx context.Context, userDI
 		}
 	}()
 
-	// Delete subscription
-	deleteQuery := `DELETE FROM community_subscriptions WHERE user_did = $1 AND community_did = $2`
-	result, err := tx.ExecContext(ctx, deleteQuery, userDID, communityDID)
-	if err != nil {
-		return fmt.Errorf("failed to unsubscribe: %w", err)
-	}
+	query := `
+		UPDATE communities
+		SET subscriber_count = GREATEST(0, subscriber_count + $2), updated_at = NOW()
+		WHERE did = $1`
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to check unsubscribe result: %w", err)
+	for _, did := range dids {
+		if _, err := tx.ExecContext(ctx, query, did, deltas[did]); err != nil {
+			return fmt.Errorf("failed to adjust subscriber count for %s: %w", did, err)
+		}
 	}
 
-	// If no rows deleted, subscription didn't exist (idempotent - not an error)
-	if rowsAffected == 0 {
-		if commitErr := tx.Commit(); commitErr != nil {
-			return fmt.Errorf("failed to commit transaction: %w", commitErr)
-		}
-		return nil
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	// Decrement subscriber count only if delete succeeded
-	decrementQuery := `
-		UPDATE communities
-		SET subscriber_count = GREATEST(0, subscriber_count - 1), updated_at = NOW()
-		WHERE did = $1`
+	return nil
+}
+
+// RecountSubscriberCounts recomputes subscriber_count from community_subscriptions
+// Repairs drift left by deltas that were never flushed (e.g. a crash between flushes),
+// so it must run before the community consumer starts buffering new deltas
+func (r *postgresCommunityRepo) RecountSubscriberCounts(ctx context.Context) (int, error) {
+	query := `
+		WITH actual AS (
+			SELECT c.did, COUNT(s.id) AS subscriber_count
+			FROM communities c
+			LEFT JOIN community_subscriptions s ON s.community_did = c.did
+			GROUP BY c.did
+		)
+		UPDATE communities c
+		SET subscriber_count = actual.subscriber_count, updated_at = NOW()
+		FROM actual
+		WHERE c.did = actual.did AND c.subscriber_count <> actual.subscriber_count`
 
-	_, err = tx.ExecContext(ctx, decrementQuery, communityDID)
+	result, err := r.db.ExecContext(ctx, query)
 	if err != nil {
-		return fmt.Errorf("failed to decrement subscriber count: %w", err)
+		return 0, fmt.Errorf("failed to recount subscribers: %w", err)
 	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	corrected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check recount result: %w", err)
 	}
 
-	return nil
+	return int(corrected), nil
 }
 
 // GetSubscription retrieves a specific subscription
@@ -245,18 +396,55 @@ func (r *postgresCommunityRepo) GetSubscriptionByURI(ctx context.Context, record
 	return subscription, nil
 }
 
-// ListSubscriptions retrieves all subscriptions for a user
-func (r *postgresCommunityRepo) ListSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*communities.Subscription, error) {
-	query := `
-		SELECT id, user_did, community_did, subscribed_at, record_uri, record_cid, content_visibility
-		FROM community_subscriptions
-		WHERE user_did = $1
-		ORDER BY subscribed_at DESC
-		LIMIT $2 OFFSET $3`
+// ListSubscriptions pages through a user's subscriptions, newest first
+func (r *postgresCommunityRepo) ListSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return r.listSubscriptionPage(ctx, "user_did", userDID, limit, cursor)
+}
+
+// ListSubscribedCommunities retrieves a user's subscriptions joined with community data
+// Supports sorting by subscribedAt (default), alphabetical, or recentActivity (communities.last_post_at)
+// and an optional case-insensitive filter on name, display name, and handle
+func (r *postgresCommunityRepo) ListSubscribedCommunities(ctx context.Context, req communities.GetSubscriptionsRequest) ([]*communities.SubscribedCommunity, error) {
+	whereClauses := []string{"s.user_did = $1"}
+	args := []interface{}{req.UserDID}
+	argCount := 2
+
+	if req.Query != "" {
+		whereClauses = append(whereClauses, fmt.Sprintf(
+			"(c.name ILIKE $%[1]d OR c.display_name ILIKE $%[1]d OR c.handle ILIKE $%[1]d)",
+			argCount))
+		args = append(args, "%"+escapeLikePattern(req.Query)+"%")
+		argCount++
+	}
 
-	rows, err := r.db.QueryContext(ctx, query, userDID, limit, offset)
+	// Every sort ends with a unique column so offset pagination is stable
+	var orderBy string
+	switch req.Sort {
+	case communities.SubscriptionSortAlphabetical:
+		orderBy = "c.name ASC, c.did ASC"
+	case communities.SubscriptionSortRecentActivity:
+		orderBy = "c.last_post_at DESC NULLS LAST, s.subscribed_at DESC, s.id DESC"
+	default:
+		orderBy = "s.subscribed_at DESC, s.id DESC"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT s.id, s.user_did, s.community_did, s.subscribed_at, s.record_uri, s.record_cid, s.content_visibility,
+			c.id, c.did, c.handle, c.name, c.display_name, c.description, c.avatar_cid, c.banner_cid,
+			c.visibility, c.member_count, c.subscriber_count, c.post_count,
+			c.created_at, c.updated_at, c.last_post_at, c.pds_url, c.federation_blocked
+		FROM community_subscriptions s
+		JOIN communities c ON c.did = s.community_did
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`,
+		strings.Join(whereClauses, " AND "), orderBy, argCount, argCount+1)
+
+	args = append(args, req.Limit, req.Offset)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+		return nil, fmt.Errorf("failed to list subscribed communities: %w", err)
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
@@ -264,10 +452,13 @@ func (r *postgresCommunityRepo) ListSubscriptions(ctx context.Context, userDID s
 		}
 	}()
 
-	result := []*communities.Subscription{}
+	result := []*communities.SubscribedCommunity{}
 	for rows.Next() {
 		subscription := &communities.Subscription{}
+		community := &communities.Community{}
 		var recordURI, recordCID sql.NullString
+		var displayName, description, avatarCID, bannerCID, pdsURL sql.NullString
+		var lastPostAt sql.NullTime
 
 		scanErr := rows.Scan(
 			&subscription.ID,
@@ -277,36 +468,78 @@ func (r *postgresCommunityRepo) ListSubscriptions(ctx context.Context, userDID s
 			&recordURI,
 			&recordCID,
 			&subscription.ContentVisibility,
+			&community.ID, &community.DID, &community.Handle, &community.Name,
+			&displayName, &description, &avatarCID, &bannerCID,
+			&community.Visibility, &community.MemberCount, &community.SubscriberCount, &community.PostCount,
+			&community.CreatedAt, &community.UpdatedAt, &lastPostAt, &pdsURL, &community.FederationBlocked,
 		)
 		if scanErr != nil {
-			return nil, fmt.Errorf("failed to scan subscription: %w", scanErr)
+			return nil, fmt.Errorf("failed to scan subscribed community: %w", scanErr)
 		}
 
 		subscription.RecordURI = recordURI.String
 		subscription.RecordCID = recordCID.String
 
-		result = append(result, subscription)
+		community.DisplayName = displayName.String
+		community.Description = description.String
+		community.AvatarCID = avatarCID.String
+		community.BannerCID = bannerCID.String
+		community.PDSURL = pdsURL.String
+		if lastPostAt.Valid {
+			community.LastPostAt = &lastPostAt.Time
+		}
+
+		result = append(result, &communities.SubscribedCommunity{
+			Community:    community,
+			Subscription: subscription,
+		})
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating subscriptions: %w", err)
+		return nil, fmt.Errorf("error iterating subscribed communities: %w", err)
 	}
 
 	return result, nil
 }
 
-// ListSubscribers retrieves all subscribers for a community
-func (r *postgresCommunityRepo) ListSubscribers(ctx context.Context, communityDID string, limit, offset int) ([]*communities.Subscription, error) {
-	query := `
+// escapeLikePattern escapes LIKE/ILIKE wildcards so user input matches literally
+// Backslash is Postgres' default LIKE escape character
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// ListSubscribers pages through a community's subscribers, newest first
+func (r *postgresCommunityRepo) ListSubscribers(ctx context.Context, communityDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return r.listSubscriptionPage(ctx, "community_did", communityDID, limit, cursor)
+}
+
+// listSubscriptionPage lists one page of subscriptions matching column = did
+// Keyset-paginated on (subscribed_at, id), so subscriptions created between page fetches are
+// never returned twice or skipped. column is always a literal from the callers above.
+func (r *postgresCommunityRepo) listSubscriptionPage(ctx context.Context, column, did string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	// Parameter numbering: $1=did, $2=limit+1 (for pagination detection), then cursor values
+	args := []interface{}{did, limit + 1}
+	var cursorFilter string
+	if cursor != nil && *cursor != "" {
+		subscribedAt, id, err := r.parseSubscriptionCursor(*cursor)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", communities.ErrInvalidCursor, err)
+		}
+		cursorFilter = "AND (subscribed_at, id) < ($3, $4)"
+		args = append(args, subscribedAt, id)
+	}
+
+	query := fmt.Sprintf(`
 		SELECT id, user_did, community_did, subscribed_at, record_uri, record_cid, content_visibility
 		FROM community_subscriptions
-		WHERE community_did = $1
-		ORDER BY subscribed_at DESC
-		LIMIT $2 OFFSET $3`
+		WHERE %s = $1
+			%s
+		ORDER BY subscribed_at DESC, id DESC
+		LIMIT $2`, column, cursorFilter)
 
-	rows, err := r.db.QueryContext(ctx, query, communityDID, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list subscribers: %w", err)
+		return nil, nil, fmt.Errorf("failed to list subscriptions: %w", err)
 	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil {
@@ -329,7 +562,7 @@ func (r *postgresCommunityRepo) ListSubscribers(ctx context.Context, communityDI
 			&subscription.ContentVisibility,
 		)
 		if scanErr != nil {
-			return nil, fmt.Errorf("failed to scan subscriber: %w", scanErr)
+			return nil, nil, fmt.Errorf("failed to scan subscription: %w", scanErr)
 		}
 
 		subscription.RecordURI = recordURI.String
@@ -339,10 +572,40 @@ func (r *postgresCommunityRepo) ListSubscribers(ctx context.Context, communityDI
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating subscribers: %w", err)
+		return nil, nil, fmt.Errorf("error iterating subscriptions: %w", err)
 	}
 
-	return result, nil
+	var nextCursor *string
+	if len(result) > limit && limit > 0 {
+		result = result[:limit]
+		last := result[len(result)-1]
+		cursorStr := r.cursors.Encode(last.SubscribedAt.Format("2006-01-02T15:04:05.999999999Z07:00"), strconv.Itoa(last.ID))
+		nextCursor = &cursorStr
+	}
+
+	return result, nextCursor, nil
+}
+
+// parseSubscriptionCursor decodes a subscription listing cursor
+// Cursor fields: subscribedAt, id
+func (r *postgresCommunityRepo) parseSubscriptionCursor(cursor string) (string, int, error) {
+	parts, err := r.cursors.Decode(cursor)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(parts) != 2 {
+		return "", 0, fmt.Errorf("invalid cursor format")
+	}
+
+	if _, err := time.Parse(time.RFC3339Nano, parts[0]); err != nil {
+		return "", 0, fmt.Errorf("invalid cursor timestamp")
+	}
+	id, err := strconv.Atoi(parts[1])
+	if err != nil || id <= 0 {
+		return "", 0, fmt.Errorf("invalid cursor id")
+	}
+
+	return parts[0], id, nil
 }
 
 // GetSubscribedCommunityDIDs returns a map of community DIDs that the user is subscribed to
@@ -392,3 +655,50 @@ func (r *postgresCommunityRepo) GetSubscribedCommunityDIDs(ctx context.Context,
 
 	return result, nil
 }
+
+// GetSubscribedUserDIDs returns a map of the user DIDs that subscribe to the community
+// Batch lookup for crowd control in comment threads
+func (r *postgresCommunityRepo) GetSubscribedUserDIDs(ctx context.Context, communityDID string, userDIDs []string) (map[string]bool, error) {
+	if len(userDIDs) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	placeholders := make([]string, len(userDIDs))
+	args := make([]interface{}, len(userDIDs)+1)
+	args[0] = communityDID
+	for i, did := range userDIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+2)
+		args[i+1] = did
+	}
+
+	query := fmt.Sprintf(`
+		SELECT user_did
+		FROM community_subscriptions
+		WHERE community_did = $1 AND user_did IN (%s)`,
+		strings.Join(placeholders, ", "))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get community subscribers: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	result := make(map[string]bool)
+	for rows.Next() {
+		var userDID string
+		if err := rows.Scan(&userDID); err != nil {
+			return nil, fmt.Errorf("failed to scan user DID: %w", err)
+		}
+		result[userDID] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating community subscribers: %w", err)
+	}
+
+	return result, nil
+}
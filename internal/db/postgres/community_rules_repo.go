@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+type postgresCommunityRulesRepo struct {
+	db *sql.DB
+}
+
+// NewCommunityRulesRepository creates a new PostgreSQL repository for community rules documents
+func NewCommunityRulesRepository(db *sql.DB) communities.RulesRepository {
+	return &postgresCommunityRulesRepo{db: db}
+}
+
+// GetRules returns a community's indexed rules document
+func (r *postgresCommunityRulesRepo) GetRules(ctx context.Context, communityDID string) (*communities.CommunityRules, error) {
+	query := `
+		SELECT community_did, markdown, rules, record_uri, record_cid, COALESCE(rev, ''), updated_at
+		FROM community_rules
+		WHERE community_did = $1`
+
+	rules := &communities.CommunityRules{}
+	var rulesJSON []byte
+	err := r.db.QueryRowContext(ctx, query, communityDID).Scan(
+		&rules.CommunityDID, &rules.Markdown, &rulesJSON, &rules.RecordURI, &rules.RecordCID, &rules.Rev, &rules.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, communities.ErrRulesNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get community rules: %w", err)
+	}
+
+	if err := json.Unmarshal(rulesJSON, &rules.Rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal community rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpsertRules stores a community's rules document, replacing any previous version
+// Returns ErrStaleRevision, without writing, when rules.Rev is not newer than the indexed rev.
+func (r *postgresCommunityRulesRepo) UpsertRules(ctx context.Context, rules *communities.CommunityRules) error {
+	ruleList := rules.Rules
+	if ruleList == nil {
+		ruleList = []communities.Rule{}
+	}
+	rulesJSON, err := json.Marshal(ruleList)
+	if err != nil {
+		return fmt.Errorf("failed to marshal community rules: %w", err)
+	}
+
+	query := `
+		INSERT INTO community_rules (community_did, markdown, rules, record_uri, record_cid, rev, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		ON CONFLICT (community_did) DO UPDATE SET
+			markdown = EXCLUDED.markdown,
+			rules = EXCLUDED.rules,
+			record_uri = EXCLUDED.record_uri,
+			record_cid = EXCLUDED.record_cid,
+			rev = COALESCE(EXCLUDED.rev, community_rules.rev),
+			updated_at = EXCLUDED.updated_at
+		WHERE ` + revIsNewer("EXCLUDED.rev", "community_rules.rev")
+
+	result, err := r.db.ExecContext(ctx, query,
+		rules.CommunityDID, rules.Markdown, rulesJSON, rules.RecordURI, rules.RecordCID, rules.Rev, rules.UpdatedAt,
+	)
+	if err != nil {
+		if _, ok := constraintViolation(err, pqForeignKeyViolation); ok {
+			return communities.ErrCommunityNotFound
+		}
+		return fmt.Errorf("failed to upsert community rules: %w", err)
+	}
+	written, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if written == 0 {
+		return communities.ErrStaleRevision
+	}
+	return nil
+}
+
+// DeleteRules removes a community's rules document (idempotent)
+func (r *postgresCommunityRulesRepo) DeleteRules(ctx context.Context, communityDID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM community_rules WHERE community_did = $1`, communityDID); err != nil {
+		return fmt.Errorf("failed to delete community rules: %w", err)
+	}
+	return nil
+}
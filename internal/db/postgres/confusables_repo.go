@@ -0,0 +1,123 @@
+package postgres
+
+import (
+	"Coves/internal/core/confusables"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// confusableTable describes a table whose handles are checked for lookalikes
+type confusableTable struct {
+	name string
+	// verified restricts which rows count as the genuine handle a lookalike imitates
+	verified string
+}
+
+var (
+	usersConfusableTable = confusableTable{name: "users", verified: "o.confusable_with IS NULL"}
+	// A community only counts when it would pass the impersonation lookup: not flagged,
+	// suspended or on a blocked or unverified instance
+	communitiesConfusableTable = confusableTable{
+		name:     "communities",
+		verified: "o.confusable_with IS NULL AND o.impersonation_flag = FALSE AND o.suspended_at IS NULL AND o.federation_blocked = FALSE AND o.hosted_by_unverified = FALSE",
+	}
+)
+
+// flagConfusableHandle records whether did's freshly indexed handle imitates another account's
+// handle in the same table, returning the DID it imitates or "".
+// A handle is flagged against the account sharing its skeleton that looks most genuine: one
+// without punycode labels, then the oldest. A handle without punycode labels is never
+// flagged against one with them; when nothing older imitates it, the punycode lookalikes
+// indexed earlier are flagged against it instead. Flags pointing at did are cleared when its
+// handle no longer shares their skeleton.
+func flagConfusableHandle(ctx context.Context, db *sql.DB, table confusableTable, did, handle, skeleton string) (string, error) {
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET confusable_with = NULL
+		WHERE confusable_with = $1 AND handle_skeleton IS DISTINCT FROM $2`, table.name),
+		did, skeleton); err != nil {
+		return "", fmt.Errorf("failed to clear stale confusable flags: %w", err)
+	}
+
+	plain := !strings.Contains(strings.ToLower(handle), "xn--")
+	var confusableWith sql.NullString
+	err := db.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE %[1]s SET confusable_with = (
+			SELECT o.did FROM %[1]s o
+			WHERE o.handle_skeleton = $2 AND o.did <> $1 AND %[2]s
+				AND (NOT $3 OR o.handle NOT LIKE '%%xn--%%')
+			ORDER BY (o.handle NOT LIKE '%%xn--%%') DESC, o.created_at ASC, o.did ASC
+			LIMIT 1
+		)
+		WHERE did = $1
+		RETURNING confusable_with`, table.name, table.verified),
+		did, skeleton, plain).Scan(&confusableWith)
+	if err != nil && err != sql.ErrNoRows {
+		return "", fmt.Errorf("failed to flag confusable handle: %w", err)
+	}
+
+	if plain && !confusableWith.Valid {
+		if _, err := db.ExecContext(ctx, fmt.Sprintf(`
+			UPDATE %s o SET confusable_with = $1
+			WHERE o.did <> $1 AND o.handle_skeleton = $2 AND o.handle LIKE '%%xn--%%' AND o.confusable_with IS NULL`,
+			table.name), did, skeleton); err != nil {
+			return "", fmt.Errorf("failed to flag lookalike handles: %w", err)
+		}
+	}
+	return confusableWith.String, nil
+}
+
+type postgresConfusablesRepo struct {
+	db *sql.DB
+}
+
+// NewConfusablesRepository creates a new PostgreSQL repository for reviewing lookalike handles
+func NewConfusablesRepository(db *sql.DB) confusables.Repository {
+	return &postgresConfusablesRepo{db: db}
+}
+
+// ListCollisions returns flagged users and communities, most recently indexed first
+func (r *postgresConfusablesRepo) ListCollisions(ctx context.Context, limit, offset int) ([]*confusables.Collision, error) {
+	query := `
+		SELECT kind, did, handle, handle_skeleton, confusable_with, confusable_handle, updated_at
+		FROM (
+			SELECT 'user' AS kind, u.did, u.handle, COALESCE(u.handle_skeleton, '') AS handle_skeleton,
+				u.confusable_with, COALESCE(o.handle, '') AS confusable_handle, u.updated_at
+			FROM users u
+			LEFT JOIN users o ON o.did = u.confusable_with
+			WHERE u.confusable_with IS NOT NULL
+			UNION ALL
+			SELECT 'community', c.did, c.handle, COALESCE(c.handle_skeleton, ''),
+				c.confusable_with, COALESCE(o.handle, ''), c.updated_at
+			FROM communities c
+			LEFT JOIN communities o ON o.did = c.confusable_with
+			WHERE c.confusable_with IS NOT NULL AND c.deleted_at IS NULL
+		) flagged
+		ORDER BY updated_at DESC, did ASC
+		LIMIT $1 OFFSET $2`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list confusable handles: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	result := []*confusables.Collision{}
+	for rows.Next() {
+		c := &confusables.Collision{}
+		if err := rows.Scan(&c.Kind, &c.DID, &c.Handle, &c.Skeleton, &c.ConfusableWith, &c.ConfusableHandle, &c.IndexedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan confusable handle: %w", err)
+		}
+		result = append(result, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating confusable handles: %w", err)
+	}
+	return result, nil
+}
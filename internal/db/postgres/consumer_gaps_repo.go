@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"Coves/internal/core/consumergaps"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+type postgresConsumerGapsRepo struct {
+	db *sql.DB
+}
+
+// NewConsumerGapsRepository creates a new PostgreSQL repository for Jetstream consumer gaps
+func NewConsumerGapsRepository(db *sql.DB) consumergaps.Repository {
+	return &postgresConsumerGapsRepo{db: db}
+}
+
+const consumerGapColumns = `id, consumer, start_time_us, end_time_us, detected_at, backfilled_at`
+
+// RecordGap stores a gap between two Jetstream time_us values
+func (r *postgresConsumerGapsRepo) RecordGap(ctx context.Context, consumer string, startTimeUS, endTimeUS int64) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO consumer_gaps (consumer, start_time_us, end_time_us)
+		VALUES ($1, $2, $3)`,
+		consumer, startTimeUS, endTimeUS)
+	if err != nil {
+		return fmt.Errorf("failed to record consumer gap: %w", err)
+	}
+	return nil
+}
+
+// SavePosition stores the consumer's position, replacing the one saved before
+func (r *postgresConsumerGapsRepo) SavePosition(ctx context.Context, consumer string, position consumergaps.Position) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO consumer_positions (consumer, last_seen_time_us, connected_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (consumer) DO UPDATE SET
+			last_seen_time_us = EXCLUDED.last_seen_time_us,
+			connected_at = EXCLUDED.connected_at,
+			updated_at = NOW()`,
+		consumer, position.LastSeenUS, position.ConnectedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save consumer position: %w", err)
+	}
+	return nil
+}
+
+// LoadPosition returns the consumer's saved position, or a zero Position if it never saved one
+func (r *postgresConsumerGapsRepo) LoadPosition(ctx context.Context, consumer string) (consumergaps.Position, error) {
+	var position consumergaps.Position
+	err := r.db.QueryRowContext(ctx, `
+		SELECT last_seen_time_us, connected_at FROM consumer_positions WHERE consumer = $1`,
+		consumer).Scan(&position.LastSeenUS, &position.ConnectedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return consumergaps.Position{}, nil
+	}
+	if err != nil {
+		return consumergaps.Position{}, fmt.Errorf("failed to load consumer position: %w", err)
+	}
+	return position, nil
+}
+
+// ListUnfilled returns the gaps not yet backfilled that overlap [from, to], oldest first
+func (r *postgresConsumerGapsRepo) ListUnfilled(ctx context.Context, from, to time.Time) ([]*consumergaps.Gap, error) {
+	return r.query(ctx, `
+		SELECT `+consumerGapColumns+`
+		FROM consumer_gaps
+		WHERE backfilled_at IS NULL AND start_time_us <= $2 AND end_time_us >= $1
+		ORDER BY start_time_us ASC, id ASC`,
+		from.UnixMicro(), to.UnixMicro())
+}
+
+// List returns gaps newest first, including backfilled ones when includeBackfilled is set
+func (r *postgresConsumerGapsRepo) List(ctx context.Context, includeBackfilled bool, limit, offset int) ([]*consumergaps.Gap, error) {
+	return r.query(ctx, `
+		SELECT `+consumerGapColumns+`
+		FROM consumer_gaps
+		WHERE $1 OR backfilled_at IS NULL
+		ORDER BY start_time_us DESC, id DESC
+		LIMIT $2 OFFSET $3`,
+		includeBackfilled, limit, offset)
+}
+
+// MarkBackfilled records that the gap's records have been reindexed
+// Keeps the original backfilled_at when the gap was already marked.
+func (r *postgresConsumerGapsRepo) MarkBackfilled(ctx context.Context, id int64) (*consumergaps.Gap, error) {
+	gaps, err := r.query(ctx, `
+		UPDATE consumer_gaps SET backfilled_at = COALESCE(backfilled_at, NOW())
+		WHERE id = $1
+		RETURNING `+consumerGapColumns,
+		id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark consumer gap backfilled: %w", err)
+	}
+	if len(gaps) == 0 {
+		return nil, consumergaps.ErrGapNotFound
+	}
+	return gaps[0], nil
+}
+
+// query runs a query selecting consumerGapColumns and scans the rows
+func (r *postgresConsumerGapsRepo) query(ctx context.Context, query string, args ...interface{}) ([]*consumergaps.Gap, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query consumer gaps: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	result := []*consumergaps.Gap{}
+	for rows.Next() {
+		var (
+			gap                    consumergaps.Gap
+			startTimeUS, endTimeUS int64
+			backfilledAt           sql.NullTime
+		)
+		if err := rows.Scan(&gap.ID, &gap.Consumer, &startTimeUS, &endTimeUS, &gap.DetectedAt, &backfilledAt); err != nil {
+			return nil, fmt.Errorf("failed to scan consumer gap: %w", err)
+		}
+		gap.Start = time.UnixMicro(startTimeUS).UTC()
+		gap.End = time.UnixMicro(endTimeUS).UTC()
+		if backfilledAt.Valid {
+			gap.BackfilledAt = &backfilledAt.Time
+		}
+		result = append(result, &gap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating consumer gaps: %w", err)
+	}
+	return result, nil
+}
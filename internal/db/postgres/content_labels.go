@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"fmt"
+	"strings"
+
+	"Coves/internal/core/posts"
+)
+
+// Content label convention
+//
+// A post's effective labels are its community's content warnings plus its indexed self-labels
+// (posts.labels), with moderator overrides in post_label_overrides taking precedence over both.
+// Reads select postLabelColumns and merge them with posts.EffectiveLabels; feeds that hide
+// adult content filter with adultContentHidden, which applies the same precedence in SQL.
+
+// postLabelColumns select a post's labels, its community's content warnings and its moderator
+// overrides (applied, then removed). Queries select posts as p and communities as c.
+const postLabelColumns = `
+			p.labels, c.content_warnings,
+			ARRAY(SELECT o.label FROM post_label_overrides o WHERE o.post_uri = p.uri AND NOT o.neg) as labels_applied,
+			ARRAY(SELECT o.label FROM post_label_overrides o WHERE o.post_uri = p.uri AND o.neg) as labels_removed`
+
+// labelColumns scans postLabelColumns
+type labelColumns struct {
+	post, community, applied, removed []string
+}
+
+// view merges the scanned labels into the post's labels view
+func (l *labelColumns) view() *posts.LabelsView {
+	return posts.NewLabelsView(posts.EffectiveLabels(l.community, l.post,
+		posts.LabelOverrides{Applied: l.applied, Removed: l.removed}))
+}
+
+// effectiveLabelCondition returns the condition that label is one of a post's effective labels
+// An override decides on its own; otherwise the self-label or a community warning implying it does.
+func effectiveLabelCondition(label string) string {
+	var warnings []string
+	for _, warning := range posts.CommunityWarningsFor(label) {
+		warnings = append(warnings, fmt.Sprintf("'%s'", warning))
+	}
+	return fmt.Sprintf(`COALESCE(
+				(SELECT NOT o.neg FROM post_label_overrides o WHERE o.post_uri = p.uri AND o.label = '%[1]s'),
+				'%[1]s' = ANY(p.labels) OR c.content_warnings && ARRAY[%[2]s]::text[])`,
+		label, strings.Join(warnings, ", "))
+}
+
+// adultContentHidden returns the condition that leaves out posts with an effective adult label
+// (posts.AdultLabels), or "" when the viewer opted in to NSFW content
+func adultContentHidden(includeNSFW bool) string {
+	if includeNSFW {
+		return ""
+	}
+	conditions := make([]string, len(posts.AdultLabels))
+	for i, label := range posts.AdultLabels {
+		conditions[i] = effectiveLabelCondition(label)
+	}
+	return "AND NOT (" + strings.Join(conditions, " OR ") + ")"
+}
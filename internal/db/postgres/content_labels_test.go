@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAdultContentHidden(t *testing.T) {
+	if cond := adultContentHidden(true); cond != "" {
+		t.Errorf("Expected no condition when NSFW is included, got %q", cond)
+	}
+
+	cond := adultContentHidden(false)
+	for _, want := range []string{
+		"AND NOT (",
+		"'nsfw' = ANY(p.labels)",
+		"'gore' = ANY(p.labels)",
+		"c.content_warnings && ARRAY['nsfw']::text[]",
+		"o.label = 'nsfw'",
+	} {
+		if !strings.Contains(cond, want) {
+			t.Errorf("Expected condition to contain %q, got:\n%s", want, cond)
+		}
+	}
+	if strings.Contains(cond, "'spoiler'") {
+		t.Errorf("Spoilers aren't adult content, got:\n%s", cond)
+	}
+}
+
+func TestLabelColumnsView(t *testing.T) {
+	labels := labelColumns{
+		post:      []string{"spoiler"},
+		community: []string{"nsfw"},
+		removed:   []string{"nsfw"},
+	}
+	view := labels.view()
+	if view == nil || len(view.Values) != 1 || view.Values[0] != "spoiler" {
+		t.Errorf("Expected the override to remove the community's nsfw label, got %+v", view)
+	}
+	if (&labelColumns{}).view() != nil {
+		t.Error("Expected no view for an unlabeled post")
+	}
+}
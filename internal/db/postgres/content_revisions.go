@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"Coves/internal/core/moderation"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// contentRevision is the version of a post or comment an edit is about to replace
+type contentRevision struct {
+	WrittenAt     time.Time
+	ContentFacets *string
+	Embed         *string
+	CID           string
+	Content       string
+}
+
+// recordRevision keeps the replaced version of subjectURI and prunes all but the newest
+// moderation.MaxRevisions; call it in the same transaction as the update
+func recordRevision(ctx context.Context, tx *sql.Tx, subjectURI string, prior contentRevision) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO content_revisions (subject_uri, cid, content, content_facets, embed, written_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		subjectURI, prior.CID, prior.Content, prior.ContentFacets, prior.Embed, prior.WrittenAt)
+	if err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM content_revisions
+		WHERE subject_uri = $1 AND id NOT IN (
+			SELECT id FROM content_revisions
+			WHERE subject_uri = $1
+			ORDER BY id DESC
+			LIMIT $2
+		)`,
+		subjectURI, moderation.MaxRevisions)
+	if err != nil {
+		return fmt.Errorf("failed to prune revisions: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type postgresDIDWebRepo struct {
+	db *sql.DB
+}
+
+// NewDIDWebRepository creates a new PostgreSQL repository for community did:web documents
+func NewDIDWebRepository(db *sql.DB) communities.DIDWebRepository {
+	return &postgresDIDWebRepo{db: db}
+}
+
+// Create stores a new did:web document
+// An existing document for the DID or handle is never replaced: it belongs to a live (or
+// provisioning) community, so the conflict is reported as ErrHandleTaken.
+func (r *postgresDIDWebRepo) Create(ctx context.Context, doc *communities.DIDWebDocument) error {
+	query := `
+		INSERT INTO community_did_web_documents (did, handle, signing_key, pds_url)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at`
+
+	if err := r.db.QueryRowContext(ctx, query, doc.DID, doc.Handle, doc.SigningKey, doc.PDSURL).Scan(&doc.CreatedAt); err != nil {
+		if _, ok := constraintViolation(err, pqUniqueViolation); ok {
+			return communities.ErrHandleTaken
+		}
+		return fmt.Errorf("failed to store did:web document: %w", err)
+	}
+	return nil
+}
+
+// GetByDID returns the document for a did:web DID
+func (r *postgresDIDWebRepo) GetByDID(ctx context.Context, did string) (*communities.DIDWebDocument, error) {
+	return r.get(ctx, `did = $1`, did)
+}
+
+// GetByHandle returns the document claiming a community handle
+func (r *postgresDIDWebRepo) GetByHandle(ctx context.Context, handle string) (*communities.DIDWebDocument, error) {
+	return r.get(ctx, `handle = $1`, handle)
+}
+
+func (r *postgresDIDWebRepo) get(ctx context.Context, where string, arg string) (*communities.DIDWebDocument, error) {
+	query := `
+		SELECT did, handle, signing_key, pds_url, created_at
+		FROM community_did_web_documents
+		WHERE ` + where
+
+	doc := &communities.DIDWebDocument{}
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(&doc.DID, &doc.Handle, &doc.SigningKey, &doc.PDSURL, &doc.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, communities.ErrDIDWebDocumentNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get did:web document: %w", err)
+	}
+	return doc, nil
+}
+
+// Delete removes the document for a did:web DID
+func (r *postgresDIDWebRepo) Delete(ctx context.Context, did string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM community_did_web_documents WHERE did = $1`, did)
+	if err != nil {
+		return fmt.Errorf("failed to delete did:web document: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check delete result: %w", err)
+	}
+	if rows == 0 {
+		return communities.ErrDIDWebDocumentNotFound
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package postgres
+
+import (
+	"Coves/internal/core/confusables"
+	"Coves/internal/core/directory"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type postgresDirectoryRepo struct {
+	db *sql.DB
+}
+
+// NewDirectoryRepository creates a new PostgreSQL repository for community directory sync
+func NewDirectoryRepository(db *sql.DB) directory.Repository {
+	return &postgresDirectoryRepo{db: db}
+}
+
+// ListHosted returns a page of the communities this instance lists in its directory
+// Every hosted community is listed, withdrawn ones with the reason as their status, so peers
+// hear about suspensions and deletions. Uses idx_communities_directory; a nil cursor starts
+// from the beginning.
+func (r *postgresDirectoryRepo) ListHosted(ctx context.Context, hostedByDID string, after *directory.Cursor, updatedBefore time.Time, limit int) ([]*directory.Entry, error) {
+	afterUpdatedAt, afterDID := time.Time{}, ""
+	if after != nil {
+		afterUpdatedAt, afterDID = after.UpdatedAt, after.DID
+	}
+
+	query := `
+		SELECT did, handle, name, COALESCE(display_name, ''), COALESCE(description, ''),
+			created_by_did, hosted_by_did, subscriber_count, post_count, created_at, updated_at,
+			CASE
+				WHEN deleted_at IS NOT NULL THEN $6
+				WHEN suspended_at IS NOT NULL THEN $7
+				WHEN visibility <> 'public' OR impersonation_flag THEN $8
+				ELSE $9
+			END
+		FROM communities
+		WHERE NOT remote
+			AND hosted_by_did = $1
+			AND updated_at < $2
+			AND (updated_at, did) > ($3, $4)
+		ORDER BY updated_at ASC, did ASC
+		LIMIT $5`
+
+	rows, err := r.db.QueryContext(ctx, query, hostedByDID, updatedBefore, afterUpdatedAt, afterDID, limit,
+		directory.StatusDeleted, directory.StatusSuspended, directory.StatusUnlisted, directory.StatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory communities: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	entries := []*directory.Entry{}
+	for rows.Next() {
+		entry := &directory.Entry{}
+		if err := rows.Scan(
+			&entry.DID, &entry.Handle, &entry.Name, &entry.DisplayName, &entry.Description,
+			&entry.CreatedBy, &entry.HostedBy, &entry.SubscriberCount, &entry.PostCount,
+			&entry.CreatedAt, &entry.UpdatedAt, &entry.Status,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan directory community: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating directory communities: %w", err)
+	}
+	return entries, nil
+}
+
+// UpsertRemote inserts or refreshes a remote community, deduplicated by DID
+// The conflict update only touches rows that are still remote, so communities indexed from
+// the firehose keep their data. A community its peer lists again is shown again, unless it
+// was suspended here.
+func (r *postgresDirectoryRepo) UpsertRemote(ctx context.Context, entry *directory.Entry, federationBlocked bool) (bool, error) {
+	query := `
+		INSERT INTO communities (
+			did, handle, name, display_name, description, owner_did, created_by_did, hosted_by_did,
+			visibility, subscriber_count, post_count, created_at, updated_at, federation_blocked, remote,
+			handle_skeleton
+		) VALUES (
+			$1, $2, $3, $4, $5, $1, $6, $7,
+			'public', $8, $9, $10, NOW(), $11, TRUE,
+			$12
+		)
+		ON CONFLICT (did) DO UPDATE SET
+			handle = EXCLUDED.handle,
+			handle_skeleton = EXCLUDED.handle_skeleton,
+			name = EXCLUDED.name,
+			display_name = EXCLUDED.display_name,
+			description = EXCLUDED.description,
+			hosted_by_did = EXCLUDED.hosted_by_did,
+			subscriber_count = EXCLUDED.subscriber_count,
+			post_count = EXCLUDED.post_count,
+			federation_blocked = EXCLUDED.federation_blocked,
+			visibility = 'public',
+			deleted_at = NULL,
+			updated_at = NOW()
+		WHERE communities.remote`
+
+	skeleton := confusables.HandleSkeleton(entry.Handle)
+	result, err := r.db.ExecContext(ctx, query,
+		entry.DID, entry.Handle, entry.Name,
+		nullString(entry.DisplayName), nullString(entry.Description),
+		entry.CreatedBy, entry.HostedBy,
+		entry.SubscriberCount, entry.PostCount, entry.CreatedAt,
+		federationBlocked, skeleton,
+	)
+	if err != nil {
+		if constraint, ok := constraintViolation(err, pqUniqueViolation); ok && constraint == "communities_handle_key" {
+			return false, fmt.Errorf("handle %s is already taken by another community", entry.Handle)
+		}
+		return false, fmt.Errorf("failed to upsert remote community: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check upsert result: %w", err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+	if _, err := flagConfusableHandle(ctx, r.db, communitiesConfusableTable, entry.DID, entry.Handle, skeleton); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// WithdrawRemote hides a remote community its peer withdrew from the directory
+// Deleted and suspended communities are marked deleted; unlisted ones keep their data but
+// leave public listings.
+func (r *postgresDirectoryRepo) WithdrawRemote(ctx context.Context, did, hostedByDID, status string) (bool, error) {
+	var set string
+	switch status {
+	case directory.StatusDeleted, directory.StatusSuspended:
+		set = "deleted_at = COALESCE(deleted_at, NOW())"
+	case directory.StatusUnlisted:
+		set = "visibility = 'unlisted'"
+	default:
+		return false, fmt.Errorf("cannot withdraw a community with status %q", status)
+	}
+
+	query := `
+		UPDATE communities
+		SET ` + set + `, updated_at = NOW()
+		WHERE did = $1 AND hosted_by_did = $2 AND remote`
+
+	result, err := r.db.ExecContext(ctx, query, did, hostedByDID)
+	if err != nil {
+		return false, fmt.Errorf("failed to withdraw remote community: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to check withdraw result: %w", err)
+	}
+	return rows > 0, nil
+}
+
+// GetPeerCursor returns how far a peer's directory has been read
+func (r *postgresDirectoryRepo) GetPeerCursor(ctx context.Context, peerURL string) (string, error) {
+	var cursor string
+	err := r.db.QueryRowContext(ctx, `SELECT cursor FROM directory_sync_peers WHERE peer_url = $1`, peerURL).Scan(&cursor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get peer cursor: %w", err)
+	}
+	return cursor, nil
+}
+
+// SavePeerCursor records a peer's directory cursor
+func (r *postgresDirectoryRepo) SavePeerCursor(ctx context.Context, peerURL, cursor string) error {
+	query := `
+		INSERT INTO directory_sync_peers (peer_url, cursor, synced_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (peer_url) DO UPDATE SET cursor = EXCLUDED.cursor, synced_at = NOW()`
+
+	if _, err := r.db.ExecContext(ctx, query, peerURL, cursor); err != nil {
+		return fmt.Errorf("failed to save peer cursor: %w", err)
+	}
+	return nil
+}
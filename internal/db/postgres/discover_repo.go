@@ -2,6 +2,8 @@ package postgres
 
 import (
 	"Coves/internal/core/discover"
+	"Coves/internal/db/postgres/ranking"
+	"Coves/internal/pagination"
 	"context"
 	"database/sql"
 	"fmt"
@@ -12,87 +14,81 @@ type postgresDiscoverRepo struct {
 	*feedRepoBase
 }
 
-// sortClauses maps sort types to safe SQL ORDER BY clauses
-// Note: Hot ranking uses (score + 1) to ensure new posts with 0 votes still appear
-// (otherwise 0/time_decay = 0 and they sink to the bottom)
-var discoverSortClauses = map[string]string{
-	"hot": `((p.score + 1) / POWER(EXTRACT(EPOCH FROM (NOW() - p.created_at))/3600 + 2, 1.5)) DESC, p.created_at DESC, p.uri DESC`,
-	"top": `p.score DESC, p.created_at DESC, p.uri DESC`,
-	"new": `p.created_at DESC, p.uri DESC`,
-}
-
-// hotRankExpression for discover feed
-// Uses (score + 1) so new posts with 0 votes still get a positive rank
-const discoverHotRankExpression = `((p.score + 1) / POWER(EXTRACT(EPOCH FROM (NOW() - p.created_at))/3600 + 2, 1.5))`
-
 // NewDiscoverRepository creates a new PostgreSQL discover repository
-func NewDiscoverRepository(db *sql.DB, cursorSecret string) discover.Repository {
+func NewDiscoverRepository(db *sql.DB, cursors *pagination.Signer) discover.Repository {
 	return &postgresDiscoverRepo{
-		feedRepoBase: newFeedRepoBase(db, discoverHotRankExpression, discoverSortClauses, cursorSecret),
+		feedRepoBase: newFeedRepoBase(db, ranking.SortClauses(), cursors),
 	}
 }
 
 // GetDiscover retrieves posts from ALL communities (public feed)
 func (r *postgresDiscoverRepo) GetDiscover(ctx context.Context, req discover.GetDiscoverRequest) ([]*discover.FeedViewPost, *string, error) {
-	// Capture query time for stable cursor generation (used for hot sort pagination)
-	queryTime := time.Now()
+	limit := req.Limit
+	req.Limit++ // +1 to check for next page
+	candidates, err := r.GetDiscoverCandidates(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Build ORDER BY clause based on sort type
-	orderBy, timeFilter := r.buildSortClause(req.Sort, req.Timeframe)
+	var feedPosts []*discover.FeedViewPost
+	for _, candidate := range candidates {
+		feedPosts = append(feedPosts, candidate.Post)
+	}
 
-	// Build cursor filter for pagination
-	// Discover uses $2+ for cursor params (after $1=limit)
-	cursorFilter, cursorValues, err := r.feedRepoBase.parseCursor(req.Cursor, req.Sort, 2)
-	if err != nil {
-		return nil, nil, discover.ErrInvalidCursor
+	// Handle pagination cursor
+	var cursor *string
+	if len(candidates) > limit && limit > 0 {
+		feedPosts = feedPosts[:limit]
+		cursor = &candidates[limit-1].Cursor
 	}
 
-	// Build the main query
-	var selectClause string
-	if req.Sort == "hot" {
-		selectClause = fmt.Sprintf(`
-		SELECT
-			p.uri, p.cid, p.rkey,
-			p.author_did, u.handle as author_handle,
-			p.community_did, c.handle as community_handle, c.name as community_name, c.avatar_cid as community_avatar, c.pds_url as community_pds_url,
-			p.title, p.content, p.content_facets, p.embed, p.content_labels,
-			p.created_at, p.edited_at, p.indexed_at,
-			p.upvote_count, p.downvote_count, p.score, p.comment_count,
-			%s as hot_rank
-		FROM posts p`, discoverHotRankExpression)
-	} else {
-		selectClause = `
-		SELECT
-			p.uri, p.cid, p.rkey,
-			p.author_did, u.handle as author_handle,
-			p.community_did, c.handle as community_handle, c.name as community_name, c.avatar_cid as community_avatar, c.pds_url as community_pds_url,
-			p.title, p.content, p.content_facets, p.embed, p.content_labels,
-			p.created_at, p.edited_at, p.indexed_at,
-			p.upvote_count, p.downvote_count, p.score, p.comment_count,
-			NULL::numeric as hot_rank
-		FROM posts p`
+	return feedPosts, cursor, nil
+}
+
+// GetDiscoverCandidates returns up to req.Limit discover posts in rank order with the cursor
+// resuming after each one
+func (r *postgresDiscoverRepo) GetDiscoverCandidates(ctx context.Context, req discover.GetDiscoverRequest) ([]*discover.DiscoverCandidate, error) {
+	// Build ordering and keyset filter for the page
+	// Discover uses $2+ for page params (after $1=limit)
+	page, err := r.feedRepoBase.buildPage(ctx, req.Cursor, req.Sort, req.Timeframe, 2, time.Now())
+	if err != nil {
+		return nil, discover.ErrInvalidCursor
 	}
 
 	// No subscription filter - show ALL posts from ALL communities
+	viewerParam := fmt.Sprintf("$%d", 2+len(page.args))
 	query := fmt.Sprintf(`
 		%s
 		INNER JOIN users u ON p.author_did = u.did
 		INNER JOIN communities c ON p.community_did = c.did
-		WHERE p.deleted_at IS NULL
+		WHERE %s
+			AND %s
+			AND %s
+			AND %s
+			AND c.federation_blocked = FALSE
+			AND c.hosted_by_unverified = FALSE
+			AND c.impersonation_flag = FALSE
+			AND c.suspended_at IS NULL
+			AND c.deleted_at IS NULL
+			%s
 			%s
 			%s
 		ORDER BY %s
 		LIMIT $1
-	`, selectClause, timeFilter, cursorFilter, orderBy)
+	`, page.selectClause(), notDeleted("p"), visibleTo("p", "author_did", viewerParam), aggregatorPostsAllowedFor("p", viewerParam),
+		communityNotHiddenBy("p", viewerParam), adultContentHidden(req.IncludeNSFW), page.timeFilter, page.filter, page.orderBy)
 
 	// Prepare query arguments
-	args := []interface{}{req.Limit + 1} // +1 to check for next page
-	args = append(args, cursorValues...)
+	// Viewer DID comes last, so author_only posts are shown to their author only and
+	// aggregator posts and hidden communities are dropped for viewers who hide them
+	args := []interface{}{req.Limit}
+	args = append(args, page.args...)
+	args = append(args, req.ViewerDID)
 
 	// Execute query
 	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to query discover feed: %w", err)
+		return nil, fmt.Errorf("failed to query discover feed: %w", err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -101,31 +97,21 @@ func (r *postgresDiscoverRepo) GetDiscover(ctx context.Context, req discover.Get
 	}()
 
 	// Scan results
-	var feedPosts []*discover.FeedViewPost
-	var hotRanks []float64
+	var candidates []*discover.DiscoverCandidate
 	for rows.Next() {
 		postView, hotRank, err := r.feedRepoBase.scanFeedPost(rows)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to scan discover post: %w", err)
+			return nil, fmt.Errorf("failed to scan discover post: %w", err)
 		}
-		feedPosts = append(feedPosts, &discover.FeedViewPost{Post: postView})
-		hotRanks = append(hotRanks, hotRank)
+		candidates = append(candidates, &discover.DiscoverCandidate{
+			Post:   &discover.FeedViewPost{Post: postView},
+			Cursor: r.feedRepoBase.buildCursor(postView, page, req.Timeframe, hotRank),
+		})
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, nil, fmt.Errorf("error iterating discover results: %w", err)
+		return nil, fmt.Errorf("error iterating discover results: %w", err)
 	}
 
-	// Handle pagination cursor
-	var cursor *string
-	if len(feedPosts) > req.Limit && req.Limit > 0 {
-		feedPosts = feedPosts[:req.Limit]
-		hotRanks = hotRanks[:req.Limit]
-		lastPost := feedPosts[len(feedPosts)-1].Post
-		lastHotRank := hotRanks[len(hotRanks)-1]
-		cursorStr := r.feedRepoBase.buildCursor(lastPost, req.Sort, lastHotRank, queryTime)
-		cursor = &cursorStr
-	}
-
-	return feedPosts, cursor, nil
+	return candidates, nil
 }
@@ -0,0 +1,74 @@
+package postgres
+
+import (
+	"Coves/internal/core/discover"
+	"Coves/internal/db/postgres/ranking"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// GetDiscussions returns posts whose link hashes to req.LinkHash, highest score first
+// Only public communities are searched; paginated with "top" cursors
+func (r *postgresDiscoverRepo) GetDiscussions(ctx context.Context, req discover.GetDiscussionsRequest) ([]*discover.FeedViewPost, *string, error) {
+	// $1=hash, $2=limit, then cursor params, then the viewer
+	page, err := r.feedRepoBase.buildPage(ctx, req.Cursor, ranking.SortTop, "", 3, time.Now())
+	if err != nil {
+		return nil, nil, discover.ErrInvalidCursor
+	}
+
+	query := fmt.Sprintf(`
+		%s
+		INNER JOIN users u ON p.author_did = u.did
+		INNER JOIN communities c ON p.community_did = c.did
+		WHERE p.normalized_url_hash = $1
+			AND %s
+			AND %s
+			AND c.visibility = 'public'
+			AND c.federation_blocked = FALSE
+			AND c.hosted_by_unverified = FALSE
+			AND c.impersonation_flag = FALSE
+			AND c.suspended_at IS NULL
+			AND c.deleted_at IS NULL
+			%s
+		ORDER BY %s
+		LIMIT $2
+	`, page.selectClause(), notDeleted("p"), visibleTo("p", "author_did", fmt.Sprintf("$%d", 3+len(page.args))),
+		page.filter, page.orderBy)
+
+	args := []interface{}{req.LinkHash, req.Limit + 1} // +1 to check for next page
+	args = append(args, page.args...)
+	args = append(args, req.ViewerDID)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query discussions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	feedPosts := []*discover.FeedViewPost{}
+	for rows.Next() {
+		postView, _, err := r.feedRepoBase.scanFeedPost(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to scan discussion post: %w", err)
+		}
+		feedPosts = append(feedPosts, &discover.FeedViewPost{Post: postView})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("error iterating discussions: %w", err)
+	}
+
+	var cursor *string
+	if len(feedPosts) > req.Limit && req.Limit > 0 {
+		feedPosts = feedPosts[:req.Limit]
+		cursorStr := r.feedRepoBase.buildCursor(feedPosts[len(feedPosts)-1].Post, page, "", 0)
+		cursor = &cursorStr
+	}
+
+	return feedPosts, cursor, nil
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"Coves/internal/core/discover"
+	"Coves/internal/db/postgres/ranking"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+// GetHotPosts returns the hottest posts with their hot rank
+// Limited to communityDIDs when given, otherwise drawn from all communities
+// Built for the logged-out front page, so only visible posts without adult labels are returned
+func (r *postgresDiscoverRepo) GetHotPosts(ctx context.Context, communityDIDs []string, limit int) ([]*discover.RankedPost, error) {
+	communityFilter := ""
+	args := []interface{}{limit}
+	if len(communityDIDs) > 0 {
+		communityFilter = "AND p.community_did = ANY($2)"
+		args = append(args, pq.Array(communityDIDs))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s,
+			%s as hot_rank
+		FROM posts p
+		INNER JOIN users u ON p.author_did = u.did
+		INNER JOIN communities c ON p.community_did = c.did
+		WHERE %s
+			AND %s
+			AND c.federation_blocked = FALSE
+			AND c.hosted_by_unverified = FALSE
+			AND c.impersonation_flag = FALSE
+			AND c.suspended_at IS NULL
+			AND c.deleted_at IS NULL
+			%s
+			%s
+		ORDER BY %s
+		LIMIT $1
+	`, feedPostColumns, ranking.HotRankExpression(), notDeleted("p"), visibleToEveryone("p"), adultContentHidden(false),
+		communityFilter, r.sortClauses[ranking.SortHot])
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query hot posts: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	var ranked []*discover.RankedPost
+	for rows.Next() {
+		postView, hotRank, err := r.feedRepoBase.scanFeedPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan hot post: %w", err)
+		}
+		ranked = append(ranked, &discover.RankedPost{
+			Post:    &discover.FeedViewPost{Post: postView},
+			HotRank: hotRank,
+		})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hot posts: %w", err)
+	}
+
+	return ranked, nil
+}
+
+// ListFeaturedCommunities returns featured communities in front page order
+func (r *postgresDiscoverRepo) ListFeaturedCommunities(ctx context.Context) ([]*discover.FeaturedCommunity, error) {
+	query := `
+		SELECT community_did, position, weight, added_by, created_at
+		FROM instance_featured_communities
+		ORDER BY position ASC, created_at ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list featured communities: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	featured := []*discover.FeaturedCommunity{}
+	for rows.Next() {
+		fc := &discover.FeaturedCommunity{}
+		if err := rows.Scan(&fc.CommunityDID, &fc.Position, &fc.Weight, &fc.AddedBy, &fc.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan featured community: %w", err)
+		}
+		featured = append(featured, fc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating featured communities: %w", err)
+	}
+
+	return featured, nil
+}
+
+// AddFeaturedCommunity appends a community to the featured list
+// Re-adding a featured community keeps its position and updates its weight
+func (r *postgresDiscoverRepo) AddFeaturedCommunity(ctx context.Context, featured *discover.FeaturedCommunity) error {
+	query := `
+		INSERT INTO instance_featured_communities (community_did, position, weight, added_by)
+		VALUES ($1, (SELECT COALESCE(MAX(position), -1) + 1 FROM instance_featured_communities), $2, $3)
+		ON CONFLICT (community_did) DO UPDATE SET weight = EXCLUDED.weight
+		RETURNING position, added_by, created_at`
+
+	err := r.db.QueryRowContext(ctx, query, featured.CommunityDID, featured.Weight, featured.AddedBy).
+		Scan(&featured.Position, &featured.AddedBy, &featured.CreatedAt)
+	if err != nil {
+		if _, ok := constraintViolation(err, pqForeignKeyViolation); ok {
+			return discover.ErrCommunityNotFound
+		}
+		return fmt.Errorf("failed to add featured community: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveFeaturedCommunity removes a community from the featured list
+func (r *postgresDiscoverRepo) RemoveFeaturedCommunity(ctx context.Context, communityDID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM instance_featured_communities WHERE community_did = $1`, communityDID)
+	if err != nil {
+		return fmt.Errorf("failed to remove featured community: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check remove result: %w", err)
+	}
+	if rowsAffected == 0 {
+		return discover.ErrFeaturedCommunityNotFound
+	}
+
+	return nil
+}
+
+// ReorderFeaturedCommunities assigns positions in the order given
+func (r *postgresDiscoverRepo) ReorderFeaturedCommunities(ctx context.Context, communityDIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	for position, did := range communityDIDs {
+		result, err := tx.ExecContext(ctx,
+			`UPDATE instance_featured_communities SET position = $2 WHERE community_did = $1`,
+			did, position)
+		if err != nil {
+			return fmt.Errorf("failed to reorder featured communities: %w", err)
+		}
+		if rowsAffected, err := result.RowsAffected(); err == nil && rowsAffected == 0 {
+			return discover.ErrFeaturedCommunityNotFound
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"Coves/internal/core/federation"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+type postgresFederationRepo struct {
+	db *sql.DB
+}
+
+// NewFederationRepository creates a new PostgreSQL federation policy repository
+func NewFederationRepository(db *sql.DB) federation.Repository {
+	return &postgresFederationRepo{db: db}
+}
+
+// ListRules returns all federation rules, oldest first
+func (r *postgresFederationRepo) ListRules(ctx context.Context) ([]*federation.Rule, error) {
+	query := `
+		SELECT id, pattern, mode, reason, created_by, created_at
+		FROM federation_rules
+		ORDER BY id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list federation rules: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	rules := []*federation.Rule{}
+	for rows.Next() {
+		rule := &federation.Rule{}
+		var reason sql.NullString
+		if err := rows.Scan(&rule.ID, &rule.Pattern, &rule.Mode, &reason, &rule.CreatedBy, &rule.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan federation rule: %w", err)
+		}
+		rule.Reason = reason.String
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating federation rules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// CreateRule inserts a federation rule
+func (r *postgresFederationRepo) CreateRule(ctx context.Context, rule *federation.Rule) (*federation.Rule, error) {
+	query := `
+		INSERT INTO federation_rules (pattern, mode, reason, created_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`
+
+	err := r.db.QueryRowContext(ctx, query,
+		rule.Pattern, rule.Mode, nullString(rule.Reason), rule.CreatedBy,
+	).Scan(&rule.ID, &rule.CreatedAt)
+	if err != nil {
+		if constraint, ok := constraintViolation(err, pqUniqueViolation); ok && constraint == "unique_federation_pattern" {
+			return nil, federation.ErrRuleAlreadyExists
+		}
+		return nil, fmt.Errorf("failed to create federation rule: %w", err)
+	}
+
+	return rule, nil
+}
+
+// DeleteRule removes a federation rule by ID
+func (r *postgresFederationRepo) DeleteRule(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM federation_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete federation rule: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check delete result: %w", err)
+	}
+	if rowsAffected == 0 {
+		return federation.ErrRuleNotFound
+	}
+
+	return nil
+}
+
+// ListCommunityHosts pages through indexed communities ordered by ID
+func (r *postgresFederationRepo) ListCommunityHosts(ctx context.Context, afterID int64, limit int) ([]*federation.CommunityHost, error) {
+	query := `
+		SELECT id, did, hosted_by_did, federation_blocked
+		FROM communities
+		WHERE id > $1
+		ORDER BY id ASC
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list community hosts: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	hosts := []*federation.CommunityHost{}
+	for rows.Next() {
+		host := &federation.CommunityHost{}
+		if err := rows.Scan(&host.ID, &host.DID, &host.HostedByDID, &host.FederationBlocked); err != nil {
+			return nil, fmt.Errorf("failed to scan community host: %w", err)
+		}
+		hosts = append(hosts, host)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating community hosts: %w", err)
+	}
+
+	return hosts, nil
+}
+
+// SetFederationBlocked updates the blocked flag for the given communities
+func (r *postgresFederationRepo) SetFederationBlocked(ctx context.Context, dids []string, blocked bool) error {
+	if len(dids) == 0 {
+		return nil
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE communities
+		SET federation_blocked = $2
+		WHERE did = ANY($1)
+	`, pq.Array(dids), blocked)
+	if err != nil {
+		return fmt.Errorf("failed to update federation_blocked: %w", err)
+	}
+
+	return nil
+}
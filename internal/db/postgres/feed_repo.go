@@ -2,6 +2,8 @@ package postgres
 
 import (
 	"Coves/internal/core/communityFeeds"
+	"Coves/internal/db/postgres/ranking"
+	"Coves/internal/pagination"
 	"context"
 	"database/sql"
 	"fmt"
@@ -12,87 +14,79 @@ type postgresFeedRepo struct {
 	*feedRepoBase
 }
 
-// sortClauses maps sort types to safe SQL ORDER BY clauses
-// This whitelist prevents SQL injection via dynamic ORDER BY construction
-// Note: Hot ranking uses (score + 1) to ensure new posts with 0 votes still appear
-var communityFeedSortClauses = map[string]string{
-	"hot": `((p.score + 1) / POWER(EXTRACT(EPOCH FROM (NOW() - p.created_at))/3600 + 2, 1.5)) DESC, p.created_at DESC, p.uri DESC`,
-	"top": `p.score DESC, p.created_at DESC, p.uri DESC`,
-	"new": `p.created_at DESC, p.uri DESC`,
+// communityFeedSortClauses adds the community-only "activity" sort to the shared feed sorts
+func communityFeedSortClauses() map[string]string {
+	clauses := ranking.SortClauses()
+	// Most recently commented first
+	clauses["activity"] = postActivityExpression + ` DESC, p.uri DESC`
+	return clauses
 }
 
-// hotRankExpression is the SQL expression for computing the hot rank
-// NOTE: Uses NOW() which means hot_rank changes over time - this is expected behavior
-// for hot sorting (posts naturally age out). Slight time drift between cursor creation
-// and usage may cause minor reordering but won't drop posts entirely (unlike using raw score).
-// Uses (score + 1) so new posts with 0 votes still get a positive rank
-const communityFeedHotRankExpression = `((p.score + 1) / POWER(EXTRACT(EPOCH FROM (NOW() - p.created_at))/3600 + 2, 1.5))`
-
 // NewCommunityFeedRepository creates a new PostgreSQL feed repository
-func NewCommunityFeedRepository(db *sql.DB, cursorSecret string) communityFeeds.Repository {
+func NewCommunityFeedRepository(db *sql.DB, cursors *pagination.Signer) communityFeeds.Repository {
 	return &postgresFeedRepo{
-		feedRepoBase: newFeedRepoBase(db, communityFeedHotRankExpression, communityFeedSortClauses, cursorSecret),
+		feedRepoBase: newFeedRepoBase(db, communityFeedSortClauses(), cursors),
 	}
 }
 
 // GetCommunityFeed retrieves posts from a community with sorting and pagination
 // Single query with JOINs for optimal performance
 func (r *postgresFeedRepo) GetCommunityFeed(ctx context.Context, req communityFeeds.GetCommunityFeedRequest) ([]*communityFeeds.FeedViewPost, *string, error) {
-	// Capture query time for stable cursor generation (used for hot sort pagination)
-	queryTime := time.Now()
-
-	// Build ORDER BY clause based on sort type
-	orderBy, timeFilter := r.feedRepoBase.buildSortClause(req.Sort, req.Timeframe)
-
-	// Build cursor filter for pagination
-	// Community feed uses $3+ for cursor params (after $1=community and $2=limit)
-	cursorFilter, cursorValues, err := r.feedRepoBase.parseCursor(req.Cursor, req.Sort, 3)
+	// Build ordering and keyset filter for the page
+	// Community feed uses $3+ for page params (after $1=community and $2=limit)
+	page, err := r.feedRepoBase.buildPage(ctx, req.Cursor, req.Sort, req.Timeframe, 3, time.Now())
 	if err != nil {
 		return nil, nil, communityFeeds.ErrInvalidCursor
 	}
 
-	// Build the main query
-	// For hot sort, we need to compute and return the hot_rank for cursor building
-	var selectClause string
-	if req.Sort == "hot" {
-		selectClause = fmt.Sprintf(`
-		SELECT
-			p.uri, p.cid, p.rkey,
-			p.author_did, u.handle as author_handle,
-			p.community_did, c.handle as community_handle, c.name as community_name, c.avatar_cid as community_avatar, c.pds_url as community_pds_url,
-			p.title, p.content, p.content_facets, p.embed, p.content_labels,
-			p.created_at, p.edited_at, p.indexed_at,
-			p.upvote_count, p.downvote_count, p.score, p.comment_count,
-			%s as hot_rank
-		FROM posts p`, communityFeedHotRankExpression)
-	} else {
-		selectClause = `
-		SELECT
-			p.uri, p.cid, p.rkey,
-			p.author_did, u.handle as author_handle,
-			p.community_did, c.handle as community_handle, c.name as community_name, c.avatar_cid as community_avatar, c.pds_url as community_pds_url,
-			p.title, p.content, p.content_facets, p.embed, p.content_labels,
-			p.created_at, p.edited_at, p.indexed_at,
-			p.upvote_count, p.downvote_count, p.score, p.comment_count,
-			NULL::numeric as hot_rank
-		FROM posts p`
+	// Build tag filter (after cursor params)
+	// Only tags that are still a community flair filter: posts keep the tag string when a flair
+	// is removed, but filtering on it returns nothing
+	nextParam := 3 + len(page.args)
+	var tagFilter string
+	if req.Tag != "" {
+		tagFilter = fmt.Sprintf(
+			`AND p.tags @> ARRAY[$%d::text] AND c.flairs @> jsonb_build_array(jsonb_build_object('name', $%d::text))`,
+			nextParam, nextParam)
+		nextParam++
+	}
+
+	// Build score filter (moderator triage; the service checks the viewer may use it)
+	var scoreFilter string
+	if req.MinScore != nil {
+		scoreFilter = fmt.Sprintf(`AND p.score >= $%d`, nextParam)
+		nextParam++
 	}
 
+	// Viewer DID comes last, so author_only posts are shown to their author only
+	viewerParam := fmt.Sprintf("$%d", nextParam)
+
 	query := fmt.Sprintf(`
 		%s
 		INNER JOIN users u ON p.author_did = u.did
 		INNER JOIN communities c ON p.community_did = c.did
 		WHERE p.community_did = $1
-			AND p.deleted_at IS NULL
+			AND c.deleted_at IS NULL
+			AND %s
+			AND %s
+			%s
+			%s
 			%s
 			%s
 		ORDER BY %s
 		LIMIT $2
-	`, selectClause, timeFilter, cursorFilter, orderBy)
+	`, page.selectClause(), notDeleted("p"), visibleTo("p", "author_did", viewerParam), page.timeFilter, page.filter, tagFilter, scoreFilter, page.orderBy)
 
 	// Prepare query arguments
 	args := []interface{}{req.Community, req.Limit + 1} // +1 to check for next page
-	args = append(args, cursorValues...)
+	args = append(args, page.args...)
+	if req.Tag != "" {
+		args = append(args, req.Tag)
+	}
+	if req.MinScore != nil {
+		args = append(args, *req.MinScore)
+	}
+	args = append(args, req.ViewerDID)
 
 	// Execute query
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -129,7 +123,7 @@ func (r *postgresFeedRepo) GetCommunityFeed(ctx context.Context, req communityFe
 		hotRanks = hotRanks[:req.Limit]
 		lastPost := feedPosts[len(feedPosts)-1].Post
 		lastHotRank := hotRanks[len(hotRanks)-1]
-		cursorStr := r.feedRepoBase.buildCursor(lastPost, req.Sort, lastHotRank, queryTime)
+		cursorStr := r.feedRepoBase.buildCursor(lastPost, page, req.Timeframe, lastHotRank)
 		cursor = &cursorStr
 	}
 
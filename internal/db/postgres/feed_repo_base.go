@@ -1,44 +1,88 @@
 package postgres
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
+	"context"
 	"database/sql"
-	"encoding/base64"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 	"time"
 
 	"Coves/internal/core/blobs"
 	"Coves/internal/core/communities"
 	"Coves/internal/core/posts"
+	"Coves/internal/db/postgres/ranking"
+	"Coves/internal/pagination"
+
+	"github.com/lib/pq"
 )
 
+// postActivityExpression orders posts by their newest comment, falling back to creation time
+// Matches the idx_posts_community_activity expression so the "activity" sort can use it
+const postActivityExpression = `COALESCE(p.last_activity_at, p.created_at)`
+
+// feedPostColumns are the columns scanFeedPost reads, less the trailing hot_rank
+// Queries select posts as p, users as u and communities as c.
+const feedPostColumns = `
+			p.uri, p.cid, p.rkey,
+			p.author_did, u.handle as author_handle, u.confusable_with IS NOT NULL as author_confusable, u.avatar_cid as author_avatar, u.pds_url as author_pds_url,
+			p.community_did, c.handle as community_handle, c.name as community_name, c.avatar_cid as community_avatar, c.pds_url as community_pds_url,
+			p.title, p.content, p.content_facets, p.embed, p.content_labels, p.tags,
+			p.created_at, p.edited_at, p.indexed_at,
+			p.upvote_count, p.downvote_count, p.score, p.comment_count, p.top_level_comment_count, p.last_activity_at, c.score_hiding_hours,
+			` + postLanguageColumn + `,` +
+	postLabelColumns
+
+// postLanguageColumn selects a post's language: the record's first langs entry, else the
+// language detected at index time. Queries select posts as p.
+const postLanguageColumn = `COALESCE(p.raw_record->'langs'->>0, p.detected_lang) as language`
+
 // feedRepoBase contains shared logic for timeline and discover feed repositories
 // This eliminates ~85% code duplication and ensures bug fixes apply to both feeds
 //
+// PAGINATION:
+// Feeds use strict keyset pagination. A cursor carries the last post's sort key, ending in its
+// uri so the key is unique, and the next page is WHERE (key) < (cursor) ORDER BY key DESC.
+// Row comparisons match the composite indexes below column for column, so page 50 is an
+// index range scan just like page 1, and posts inserted between requests can't shift
+// pages the way an OFFSET would.
+//
 // DATABASE INDEXES REQUIRED:
-// The feed queries rely on these indexes (created in migration 011_create_posts_table.sql):
+// The feed queries rely on these indexes (migrations 011, 037 and 050):
+//
+// 1. idx_posts_community_created_uri ON posts(community_did, created_at DESC, uri DESC) WHERE deleted_at IS NULL
+//   - Used by: Timeline and community feed for "new" sort
+//
+// 2. idx_posts_community_score_uri ON posts(community_did, score DESC, created_at DESC, uri DESC) WHERE deleted_at IS NULL
+//   - Used by: Timeline and community feed for "top" sort
 //
-// 1. idx_posts_community_created ON posts(community_did, created_at DESC) WHERE deleted_at IS NULL
-//   - Used by: Both timeline and discover for "new" sort
-//   - Covers: Community filtering + chronological ordering + soft delete filter
+// 3. idx_posts_created_uri ON posts(created_at DESC, uri DESC) WHERE deleted_at IS NULL
+//   - Used by: Discover for "new" sort (no community filter)
 //
-// 2. idx_posts_community_score ON posts(community_did, score DESC, created_at DESC) WHERE deleted_at IS NULL
-//   - Used by: Both timeline and discover for "top" sort
-//   - Covers: Community filtering + score ordering + tie-breaking + soft delete filter
+// 4. idx_posts_score_created_uri ON posts(score DESC, created_at DESC, uri DESC) WHERE deleted_at IS NULL
+//   - Used by: Discover for "top" sort (no community filter)
 //
-// 3. idx_subscriptions_user_community ON community_subscriptions(user_did, community_did)
+// 5. idx_subscriptions_user_community ON community_subscriptions(user_did, community_did)
 //   - Used by: Timeline feed (JOIN with subscriptions)
 //   - Covers: User subscription lookup
 //
-// 4. Hot sort uses computed expression: (score / POWER(age_hours + 2, 1.5))
-//   - Cannot be indexed directly (computed at query time)
-//   - Uses idx_posts_community_created for base ordering
-//   - Performance: ~10-20ms for timeline, ~8-15ms for discover (acceptable for alpha)
+// 6. idx_posts_community_activity ON posts(community_did, COALESCE(last_activity_at, created_at) DESC, uri DESC)
+//   - Used by: Community feed "activity" sort (migration 037)
+//   - Covers: Most recently commented ordering; posts without comments fall back to created_at
+//
+// 7. idx_posts_hot_score ON posts(hot_score DESC, created_at DESC, uri DESC) WHERE deleted_at IS NULL
+//   - Used by: Discover for "hot" sort (migration 066)
+//   - hot_score is precomputed by the vote consumer and ranker job (hotrank.Score), all
+//     against the epoch in hot_rank_epoch; hot cursors carry that epoch, and a page whose
+//     cursor predates the ranker's latest run ranks with ranking.HotRank at the cursor's
+//     epoch instead, so a rescore between pages can't duplicate or skip posts
+//
+// 8. idx_posts_community_hot_score ON posts(community_did, hot_score DESC, created_at DESC, uri DESC) WHERE deleted_at IS NULL
+//   - Used by: Timeline and community feed for "hot" sort (migration 066)
+//   - With ranking.SetLiveHotRank(true) hot sort computes ranking.HotRank per row instead,
+//     ranked against a clock pinned by the first page so every page sees the same order
 //
 // PERFORMANCE NOTES:
 // - All queries use single execution (no N+1)
@@ -47,269 +91,298 @@ import (
 // - Cursor pagination is stable (no offset drift)
 // - Limit+1 pattern checks for next page without extra query
 type feedRepoBase struct {
-	db                *sql.DB
-	hotRankExpression string
-	sortClauses       map[string]string
-	cursorSecret      string // HMAC secret for cursor integrity protection
+	db          *sql.DB
+	sortClauses map[string]string
+	cursors     *pagination.Signer // Encrypts cursors for integrity and so hidden scores stay unreadable
+	hotEpoch    func(ctx context.Context) (time.Time, error)
 }
 
 // newFeedRepoBase creates a new base repository with shared feed logic
-func newFeedRepoBase(db *sql.DB, hotRankExpr string, sortClauses map[string]string, cursorSecret string) *feedRepoBase {
-	return &feedRepoBase{
-		db:                db,
-		hotRankExpression: hotRankExpr,
-		sortClauses:       sortClauses,
-		cursorSecret:      cursorSecret,
+// sortClauses is usually ranking.SortClauses(), plus any feed-specific sorts
+func newFeedRepoBase(db *sql.DB, sortClauses map[string]string, cursors *pagination.Signer) *feedRepoBase {
+	r := &feedRepoBase{
+		db:          db,
+		sortClauses: sortClauses,
+		cursors:     cursors,
 	}
+	r.hotEpoch = r.loadHotEpoch
+	return r
 }
 
-// buildSortClause returns the ORDER BY SQL and optional time filter
-// Uses whitelist map to prevent SQL injection via dynamic ORDER BY
-func (r *feedRepoBase) buildSortClause(sort, timeframe string) (string, string) {
+// loadHotEpoch returns the time every stored hot_score is aged against, or the zero time
+// before the ranker's first run
+func (r *feedRepoBase) loadHotEpoch(ctx context.Context) (time.Time, error) {
+	var epoch time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT scored_at FROM hot_rank_epoch`).Scan(&epoch)
+	if err == sql.ErrNoRows {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to load hot rank epoch: %w", err)
+	}
+	return epoch, nil
+}
+
+// feedPage is the SQL for one page of a keyset-paginated feed
+type feedPage struct {
+	clock      time.Time     // Hot rank clock: the score epoch or live query time of the first page, carried by hot cursors
+	sort       string        // Sort the page is ordered by (unknown sorts fall back to hot)
+	hotRank    string        // Hot rank select expression: hot_score or the live formula; NULL for other sorts
+	orderBy    string        // ORDER BY clause, from the sortClauses whitelist
+	timeFilter string        // Top sort timeframe condition
+	filter     string        // Keyset condition after the cursor; "" on the first page
+	args       []interface{} // Values for $paramOffset onwards, referenced by hotRank and filter
+}
+
+// selectClause returns the SELECT ... FROM posts p clause for the page
+func (p *feedPage) selectClause() string {
+	return fmt.Sprintf(`
+		SELECT %s,
+			%s as hot_rank
+		FROM posts p`, feedPostColumns, p.hotRank)
+}
+
+// buildPage decodes the cursor and builds the page's ordering and keyset filter
+// paramOffset is the first free parameter number ($2 for discover, $3 for timeline); the
+// page's args take $paramOffset onwards. now is the query time, which becomes the hot rank
+// clock on the first live page (stored hot pages start from the ranker's score epoch).
+func (r *feedRepoBase) buildPage(ctx context.Context, cursor *string, sort, timeframe string, paramOffset int, now time.Time) (*feedPage, error) {
 	// Use whitelist map for ORDER BY clause (defense-in-depth against SQL injection)
-	orderBy := r.sortClauses[sort]
-	if orderBy == "" {
-		orderBy = r.sortClauses["hot"] // safe default
+	if r.sortClauses[sort] == "" {
+		sort = ranking.SortHot // safe default
+	}
+	page := &feedPage{
+		clock:   now.UTC().Truncate(time.Microsecond), // Postgres timestamps are microsecond precision
+		sort:    sort,
+		hotRank: "NULL::float8",
+		orderBy: r.sortClauses[sort],
 	}
 
 	// Add time filter for "top" sort
-	var timeFilter string
-	if sort == "top" {
-		timeFilter = r.buildTimeFilter(timeframe)
+	if sort == ranking.SortTop {
+		page.timeFilter = ranking.TimeFilter("p", timeframe)
+	}
+
+	key, err := r.parseCursor(cursor, sort, timeframe)
+	if err != nil {
+		return nil, err
+	}
+
+	// Hot cursors pin the clock the first page was ranked against; without it a post's rank
+	// drifts between requests and the keyset no longer matches the order
+	clockParam := ""
+	if sort == ranking.SortHot {
+		var epoch time.Time
+		if !ranking.LiveHotRank() {
+			if epoch, err = r.hotEpoch(ctx); err != nil {
+				return nil, err
+			}
+			if !epoch.IsZero() {
+				page.clock = epoch
+			}
+		}
+		if key != nil {
+			page.clock = key.clock
+		}
+		page.hotRank = ranking.HotScoreColumn
+		// Stored scores only match the clock while the ranker hasn't published since; once it
+		// has, or before its first run, the rank is computed at the clock instead
+		if ranking.LiveHotRank() || !page.clock.Equal(epoch) {
+			clockParam = fmt.Sprintf("$%d::timestamptz", paramOffset)
+			page.hotRank = ranking.HotRank("p", clockParam)
+			page.args = append(page.args, page.clock)
+			paramOffset++
+		}
+		page.orderBy = ranking.HotSortClause(page.hotRank)
+	}
+
+	if key == nil {
+		return page, nil
 	}
 
-	return orderBy, timeFilter
+	// Row comparisons list the key in ORDER BY order, so Postgres can seek the matching index
+	placeholders := make([]string, len(key.values))
+	for i := range key.values {
+		placeholders[i] = fmt.Sprintf("$%d%s", paramOffset+i, key.casts[i])
+	}
+	if clockParam != "" {
+		// The cursor post's rank is recomputed with the same SQL as the rows it's compared to,
+		// from the vote score it had, so the post itself compares equal rather than off by a
+		// rounding difference
+		placeholders[0] = ranking.HotRankOf(fmt.Sprintf("$%d::int", paramOffset), placeholders[1], clockParam)
+		key.values[0] = key.score
+	}
+	page.filter = fmt.Sprintf("AND (%s) < (%s)", strings.Join(key.columns(page), ", "), strings.Join(placeholders, ", "))
+	page.args = append(page.args, key.values...)
+	return page, nil
 }
 
-// buildTimeFilter returns SQL filter for timeframe
-func (r *feedRepoBase) buildTimeFilter(timeframe string) string {
-	if timeframe == "" || timeframe == "all" {
+// cursorTimeframe is the timeframe a cursor is bound to; only top sort is bounded by one
+func cursorTimeframe(sort, timeframe string) string {
+	if sort != ranking.SortTop {
 		return ""
 	}
+	return timeframe
+}
+
+// cursorKey is a decoded keyset cursor: the last post's sort key values
+type cursorKey struct {
+	clock  time.Time     // Hot sort only: the clock the key's hot rank was computed against
+	score  int           // Hot sort only: the vote score the key's hot rank was computed from
+	sort   string        // Sort the key belongs to
+	values []interface{} // Key values in ORDER BY order, always ending with the uri
+	casts  []string      // Parameter casts for values
+}
 
-	var interval string
-	switch timeframe {
-	case "hour":
-		interval = "1 hour"
-	case "day":
-		interval = "1 day"
-	case "week":
-		interval = "1 week"
-	case "month":
-		interval = "1 month"
-	case "year":
-		interval = "1 year"
+// columns returns the SQL expressions the key values compare against
+func (k *cursorKey) columns(page *feedPage) []string {
+	switch k.sort {
+	case ranking.SortNew:
+		return []string{"p.created_at", "p.uri"}
+	case "activity":
+		return []string{postActivityExpression, "p.uri"}
+	case ranking.SortTop:
+		return []string{"p.score", "p.created_at", "p.uri"}
 	default:
-		return ""
+		return []string{page.hotRank, "p.created_at", "p.uri"}
 	}
-
-	return fmt.Sprintf("AND p.created_at > NOW() - INTERVAL '%s'", interval)
 }
 
-// parseCursor decodes and validates pagination cursor
-// paramOffset is the starting parameter number for cursor values ($2 for discover, $3 for timeline)
-func (r *feedRepoBase) parseCursor(cursor *string, sort string, paramOffset int) (string, []interface{}, error) {
+// parseCursor decodes and validates pagination cursor, returning nil for the first page
+// Cursors are bound to the sort and timeframe they were issued for, so a top-day cursor can't
+// be replayed against top-week (the keyset would silently skip posts)
+func (r *feedRepoBase) parseCursor(cursor *string, sort, timeframe string) (*cursorKey, error) {
 	if cursor == nil || *cursor == "" {
-		return "", nil, nil
+		return nil, nil
 	}
 
-	// Decode base64 cursor
-	decoded, err := base64.StdEncoding.DecodeString(*cursor)
+	fields, err := r.cursors.Decode(*cursor)
 	if err != nil {
-		return "", nil, fmt.Errorf("invalid cursor encoding")
+		return nil, err
 	}
-
-	// Parse cursor: payload::signature
-	parts := strings.Split(string(decoded), "::")
-	if len(parts) < 2 {
-		return "", nil, fmt.Errorf("invalid cursor format")
-	}
-
-	// Verify HMAC signature
-	signatureHex := parts[len(parts)-1]
-	payload := strings.Join(parts[:len(parts)-1], "::")
-
-	expectedMAC := hmac.New(sha256.New, []byte(r.cursorSecret))
-	expectedMAC.Write([]byte(payload))
-	expectedSignature := hex.EncodeToString(expectedMAC.Sum(nil))
-
-	if !hmac.Equal([]byte(signatureHex), []byte(expectedSignature)) {
-		return "", nil, fmt.Errorf("invalid cursor signature")
+	if len(fields) < 2 || fields[0] != sort || fields[1] != cursorTimeframe(sort, timeframe) {
+		return nil, fmt.Errorf("cursor was issued for a different sort or timeframe")
 	}
+	fields = fields[2:]
 
-	// Parse payload based on sort type
-	payloadParts := strings.Split(payload, "::")
-
+	key := &cursorKey{sort: sort}
 	switch sort {
-	case "new":
-		// Cursor format: timestamp::uri
-		if len(payloadParts) != 2 {
-			return "", nil, fmt.Errorf("invalid cursor format")
-		}
-
-		createdAt := payloadParts[0]
-		uri := payloadParts[1]
-
-		// Validate timestamp format
-		if _, err := time.Parse(time.RFC3339Nano, createdAt); err != nil {
-			return "", nil, fmt.Errorf("invalid cursor timestamp")
+	case "new", "activity":
+		// Cursor fields: timestamp (created_at or activity), uri
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid cursor format for %s sort", sort)
 		}
-
-		// Validate URI format (must be AT-URI)
-		if !strings.HasPrefix(uri, "at://") {
-			return "", nil, fmt.Errorf("invalid cursor URI")
+		if _, err := time.Parse(time.RFC3339Nano, fields[0]); err != nil {
+			return nil, fmt.Errorf("invalid cursor timestamp")
 		}
-
-		filter := fmt.Sprintf(`AND (p.created_at < $%d OR (p.created_at = $%d AND p.uri < $%d))`,
-			paramOffset, paramOffset, paramOffset+1)
-		return filter, []interface{}{createdAt, uri}, nil
+		key.values = []interface{}{fields[0]}
+		key.casts = []string{"::timestamptz"}
 
 	case "top":
-		// Cursor format: score::timestamp::uri
-		if len(payloadParts) != 3 {
-			return "", nil, fmt.Errorf("invalid cursor format for %s sort", sort)
+		// Cursor fields: score, timestamp, uri
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid cursor format for %s sort", sort)
 		}
-
-		scoreStr := payloadParts[0]
-		createdAt := payloadParts[1]
-		uri := payloadParts[2]
-
-		// Validate score is numeric
-		score := 0
-		if _, err := fmt.Sscanf(scoreStr, "%d", &score); err != nil {
-			return "", nil, fmt.Errorf("invalid cursor score")
+		score, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor score")
 		}
-
-		// Validate timestamp format
-		if _, err := time.Parse(time.RFC3339Nano, createdAt); err != nil {
-			return "", nil, fmt.Errorf("invalid cursor timestamp")
+		if _, err := time.Parse(time.RFC3339Nano, fields[1]); err != nil {
+			return nil, fmt.Errorf("invalid cursor timestamp")
 		}
-
-		// Validate URI format (must be AT-URI)
-		if !strings.HasPrefix(uri, "at://") {
-			return "", nil, fmt.Errorf("invalid cursor URI")
-		}
-
-		filter := fmt.Sprintf(`AND (p.score < $%d OR (p.score = $%d AND p.created_at < $%d) OR (p.score = $%d AND p.created_at = $%d AND p.uri < $%d))`,
-			paramOffset, paramOffset, paramOffset+1, paramOffset, paramOffset+1, paramOffset+2)
-		return filter, []interface{}{score, createdAt, uri}, nil
+		key.values = []interface{}{score, fields[1]}
+		key.casts = []string{"", "::timestamptz"}
 
 	case "hot":
-		// Cursor format: created_at::uri::cursor_timestamp
-		// CRITICAL: cursor_timestamp is when the cursor was created, used for stable hot_rank comparison
-		// This prevents pagination bugs caused by hot_rank drift when NOW() changes between requests
-		//
-		// PRECISION FIX: We DON'T use hot_rank in the cursor comparison at all!
-		// Instead, we use (created_at, uri) as the cursor key, which are deterministic values stored in DB.
-		// The hot sort ORDER BY is: hot_rank DESC, created_at DESC, uri DESC
-		// For posts with the same hot_rank, created_at and uri provide stable ordering.
-		//
-		// This works because:
-		// 1. Posts with very different hot_ranks will be separated by created_at anyway
-		// 2. Posts with similar hot_ranks (same score, close creation times) will be ordered by created_at, uri
-		// 3. The cursor_timestamp ensures hot_rank is computed consistently across pages
-		if len(payloadParts) != 3 {
-			return "", nil, fmt.Errorf("invalid cursor format for hot sort")
+		// Cursor fields: hot_rank, created_at, uri, clock, score
+		// While the clock is the current score epoch the hot rank is compared directly: it is
+		// float8 on both sides, computed against the same clock, and the cursor keeps its
+		// shortest exact decimal form. Otherwise buildPage recomputes it from the score.
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("invalid cursor format for hot sort")
 		}
-
-		createdAt := payloadParts[0]
-		uri := payloadParts[1]
-		cursorTimestamp := payloadParts[2]
-
-		// Validate created_at timestamp format
-		if _, err := time.Parse(time.RFC3339Nano, createdAt); err != nil {
-			return "", nil, fmt.Errorf("invalid cursor created_at timestamp")
+		hotRank, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor hot rank")
 		}
-
-		// Validate URI format (must be AT-URI)
-		if !strings.HasPrefix(uri, "at://") {
-			return "", nil, fmt.Errorf("invalid cursor URI")
+		if _, err := time.Parse(time.RFC3339Nano, fields[1]); err != nil {
+			return nil, fmt.Errorf("invalid cursor created_at timestamp")
 		}
-
-		// Validate cursor timestamp format
-		if _, err := time.Parse(time.RFC3339Nano, cursorTimestamp); err != nil {
-			return "", nil, fmt.Errorf("invalid cursor timestamp")
+		clock, err := time.Parse(time.RFC3339Nano, fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor timestamp")
 		}
-
-		// CRITICAL: Use cursor_timestamp instead of NOW() for stable hot_rank comparison
-		// This ensures posts don't drift across page boundaries due to time passing
-		stableHotRankExpr := fmt.Sprintf(
-			`((p.score + 1) / POWER(EXTRACT(EPOCH FROM ($%d::timestamptz - p.created_at))/3600 + 2, 1.5))`,
-			paramOffset+2)
-
-		// Filter by cursor position in the hot-sorted result set
-		// The ORDER BY is: hot_rank DESC, created_at DESC, uri DESC
-		// We need posts that come AFTER the cursor position in this ordering.
-		//
-		// A post comes after the cursor if ANY of:
-		// 1. It has a lower hot_rank (hot_rank DESC means lower values come later)
-		// 2. Same hot_rank AND lower created_at
-		// 3. Same hot_rank AND same created_at AND lower uri
-		//
-		// To avoid floating-point comparison issues with hot_rank, we use a subquery
-		// to get the cursor post's hot_rank and compare using the SAME expression
-		cursorHotRankExpr := fmt.Sprintf(
-			`((cursor_post.score + 1) / POWER(EXTRACT(EPOCH FROM ($%d::timestamptz - cursor_post.created_at))/3600 + 2, 1.5))`,
-			paramOffset+2)
-
-		// Use a subquery to find the cursor post and compare hot_ranks using identical expressions
-		// This ensures floating-point values are computed the same way on both sides
-		filter := fmt.Sprintf(`AND (
-			%s < (SELECT %s FROM posts cursor_post WHERE cursor_post.uri = $%d)
-			OR (%s = (SELECT %s FROM posts cursor_post WHERE cursor_post.uri = $%d) AND p.created_at < $%d)
-			OR (%s = (SELECT %s FROM posts cursor_post WHERE cursor_post.uri = $%d) AND p.created_at = $%d AND p.uri < $%d)
-		)`,
-			stableHotRankExpr, cursorHotRankExpr, paramOffset+1,
-			stableHotRankExpr, cursorHotRankExpr, paramOffset+1, paramOffset,
-			stableHotRankExpr, cursorHotRankExpr, paramOffset+1, paramOffset, paramOffset+1)
-		return filter, []interface{}{createdAt, uri, cursorTimestamp}, nil
+		score, err := strconv.Atoi(fields[4])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor score")
+		}
+		key.clock = clock
+		key.score = score
+		key.values = []interface{}{hotRank, fields[1]}
+		key.casts = []string{"::float8", "::timestamptz"}
+		fields = fields[:3]
 
 	default:
-		return "", nil, nil
+		return nil, fmt.Errorf("unsupported sort for cursor: %s", sort)
 	}
+
+	// Validate URI format (must be AT-URI)
+	uri := fields[len(fields)-1]
+	if !strings.HasPrefix(uri, "at://") {
+		return nil, fmt.Errorf("invalid cursor URI")
+	}
+	key.values = append(key.values, uri)
+	key.casts = append(key.casts, "")
+	return key, nil
 }
 
-// buildCursor creates HMAC-signed pagination cursor from last post
-// SECURITY: Cursor is signed with HMAC-SHA256 to prevent manipulation
-// queryTime is the timestamp when the query was executed, used for stable hot_rank comparison
-func (r *feedRepoBase) buildCursor(post *posts.PostView, sort string, hotRank float64, queryTime time.Time) string {
-	var payload string
-	// Use :: as delimiter following Bluesky convention
-	const delimiter = "::"
+// buildCursor creates an encrypted pagination cursor from the last post of a page
+// SECURITY: Cursor is sealed with the current cursor secret, so clients can neither forge it
+// nor read the score or hot rank it carries while scores are hidden
+// hotRank is the post's rank as returned by the page query; hot cursors also carry the page
+// clock and the post's vote score
+// The sort and timeframe lead the signed fields; parseCursor rejects cursors issued for others
+func (r *feedRepoBase) buildCursor(post *posts.PostView, page *feedPage, timeframe string, hotRank float64) string {
+	fields := []string{page.sort, cursorTimeframe(page.sort, timeframe)}
 
-	switch sort {
+	switch page.sort {
 	case "new":
-		// Format: timestamp::uri
-		payload = fmt.Sprintf("%s%s%s", post.CreatedAt.Format(time.RFC3339Nano), delimiter, post.URI)
+		fields = append(fields, post.CreatedAt.Format(time.RFC3339Nano), post.URI)
+
+	case "activity":
+		activityAt := post.CreatedAt
+		if post.LastActivityAt != nil {
+			activityAt = *post.LastActivityAt
+		}
+		fields = append(fields, activityAt.Format(time.RFC3339Nano), post.URI)
 
 	case "top":
-		// Format: score::timestamp::uri
 		score := 0
 		if post.Stats != nil {
 			score = post.Stats.Score
 		}
-		payload = fmt.Sprintf("%d%s%s%s%s", score, delimiter, post.CreatedAt.Format(time.RFC3339Nano), delimiter, post.URI)
+		fields = append(fields, strconv.Itoa(score), post.CreatedAt.Format(time.RFC3339Nano), post.URI)
 
 	case "hot":
-		// Format: created_at::uri::cursor_timestamp
-		// CRITICAL: Include cursor_timestamp for stable hot_rank comparison across requests
-		// NOTE: We don't store hot_rank in the cursor - we use the post's URI to look it up
-		// This avoids floating-point precision issues between cursor storage and comparison
-		payload = fmt.Sprintf("%s%s%s%s%s", post.CreatedAt.Format(time.RFC3339Nano), delimiter, post.URI, delimiter, queryTime.Format(time.RFC3339Nano))
-
-	default:
-		payload = post.URI
+		score := 0
+		if post.Stats != nil {
+			score = post.Stats.Score
+		}
+		// 'g' with precision -1 is the shortest form that parses back to the same float64
+		fields = append(fields, strconv.FormatFloat(hotRank, 'g', -1, 64),
+			post.CreatedAt.Format(time.RFC3339Nano), post.URI, page.clock.Format(time.RFC3339Nano),
+			strconv.Itoa(score))
 	}
 
-	// Sign the payload with HMAC-SHA256
-	mac := hmac.New(sha256.New, []byte(r.cursorSecret))
-	mac.Write([]byte(payload))
-	signature := hex.EncodeToString(mac.Sum(nil))
-
-	// Append signature to payload
-	signed := payload + delimiter + signature
+	return r.cursors.Encode(fields...)
+}
 
-	return base64.StdEncoding.EncodeToString([]byte(signed))
+// hydrateAuthorAvatar sets a post author's avatar URL from their avatar CID (avatar_small
+// preset for feed lists), falling back to their identicon
+func hydrateAuthorAvatar(author *posts.AuthorView, pdsURL, avatarCID sql.NullString) {
+	if avatarURL := blobs.HydrateAvatarURL(communities.GetImageProxyConfig(), pdsURL.String, author.DID, avatarCID.String, "avatar_small"); avatarURL != "" {
+		author.Avatar = &avatarURL
+	}
 }
 
 // scanFeedPost scans a database row into a PostView
@@ -322,20 +395,28 @@ func (r *feedRepoBase) scanFeedPost(rows *sql.Rows) (*posts.PostView, float64, e
 		title, content  sql.NullString
 		facets, embed   sql.NullString
 		labelsJSON      sql.NullString
+		tags            []string
 		editedAt        sql.NullTime
+		authorAvatar    sql.NullString
+		authorPDSURL    sql.NullString
 		communityHandle sql.NullString
 		communityAvatar sql.NullString
 		communityPDSURL sql.NullString
 		hotRank         sql.NullFloat64
+		language        sql.NullString
+		labels          labelColumns
 	)
 
 	err := rows.Scan(
 		&postView.URI, &postView.CID, &postView.RKey,
-		&authorView.DID, &authorView.Handle,
+		&authorView.DID, &authorView.Handle, &authorView.ConfusableFlag, &authorAvatar, &authorPDSURL,
 		&communityRef.DID, &communityHandle, &communityRef.Name, &communityAvatar, &communityPDSURL,
-		&title, &content, &facets, &embed, &labelsJSON,
+		&title, &content, &facets, &embed, &labelsJSON, pq.Array(&tags),
 		&postView.CreatedAt, &editedAt, &postView.IndexedAt,
 		&postView.UpvoteCount, &postView.DownvoteCount, &postView.Score, &postView.CommentCount,
+		&postView.TopLevelCommentCount, &postView.LastActivityAt, &postView.ScoreHidingHours,
+		&language,
+		pq.Array(&labels.post), pq.Array(&labels.community), pq.Array(&labels.applied), pq.Array(&labels.removed),
 		&hotRank,
 	)
 	if err != nil {
@@ -343,14 +424,16 @@ func (r *feedRepoBase) scanFeedPost(rows *sql.Rows) (*posts.PostView, float64, e
 	}
 
 	// Build author view
+	hydrateAuthorAvatar(&authorView, authorPDSURL, authorAvatar)
 	postView.Author = &authorView
+	postView.Language = nullStringPtr(language)
 
 	// Build community ref
 	if communityHandle.Valid {
 		communityRef.Handle = communityHandle.String
 	}
 	// Hydrate avatar CID to URL using image proxy config (avatar_small preset for feed lists)
-	if avatarURL := blobs.HydrateImageURL(communities.GetImageProxyConfig(), communityPDSURL.String, communityRef.DID, communityAvatar.String, "avatar_small"); avatarURL != "" {
+	if avatarURL := blobs.HydrateAvatarURL(communities.GetImageProxyConfig(), communityPDSURL.String, communityRef.DID, communityAvatar.String, "avatar_small"); avatarURL != "" {
 		communityRef.Avatar = &avatarURL
 	}
 	if communityPDSURL.Valid {
@@ -386,12 +469,16 @@ func (r *feedRepoBase) scanFeedPost(rows *sql.Rows) (*posts.PostView, float64, e
 
 	// Build stats
 	postView.Stats = &posts.PostStats{
-		Upvotes:      postView.UpvoteCount,
-		Downvotes:    postView.DownvoteCount,
-		Score:        postView.Score,
-		CommentCount: postView.CommentCount,
+		Upvotes:              postView.UpvoteCount,
+		Downvotes:            postView.DownvoteCount,
+		Score:                postView.Score,
+		CommentCount:         postView.CommentCount,
+		TopLevelCommentCount: postView.TopLevelCommentCount,
+		LastActivityAt:       postView.LastActivityAt,
 	}
 
+	postView.Labels = labels.view()
+
 	// Build the record (required by lexicon)
 	record := map[string]interface{}{
 		"$type":     "social.coves.community.post",
@@ -428,6 +515,10 @@ func (r *feedRepoBase) scanFeedPost(rows *sql.Rows) (*posts.PostView, float64, e
 		}
 	}
 
+	if len(tags) > 0 {
+		record["tags"] = tags
+	}
+
 	postView.Record = record
 
 	// Return the computed hot_rank (0.0 if NULL for non-hot sorts)
@@ -0,0 +1,299 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"Coves/internal/core/posts"
+	"Coves/internal/db/postgres/ranking"
+	"Coves/internal/pagination"
+)
+
+// testHotEpoch is the ranker's score epoch in cursor tests
+var testHotEpoch = time.Date(2025, 11, 6, 12, 30, 0, 0, time.UTC)
+
+// newCursorTestRepo returns a feed repo without a database whose stored hot scores were last
+// published at epoch (the zero time for never)
+func newCursorTestRepo(sortClauses map[string]string, signer *pagination.Signer, epoch time.Time) *feedRepoBase {
+	repo := newFeedRepoBase(nil, sortClauses, signer)
+	repo.hotEpoch = func(context.Context) (time.Time, error) { return epoch, nil }
+	return repo
+}
+
+// firstPage builds the first page for sort, as a feed query would before issuing a cursor
+func firstPage(t *testing.T, repo *feedRepoBase, sort, timeframe string, now time.Time) *feedPage {
+	t.Helper()
+	page, err := repo.buildPage(context.Background(), nil, sort, timeframe, 2, now)
+	if err != nil {
+		t.Fatalf("buildPage failed: %v", err)
+	}
+	return page
+}
+
+func TestFeedCursor_SecretRotation(t *testing.T) {
+	newRepo := func(secret, previous string) *feedRepoBase {
+		signer, err := pagination.NewSigner(secret, previous)
+		if err != nil {
+			t.Fatalf("NewSigner failed: %v", err)
+		}
+		return newCursorTestRepo(ranking.SortClauses(), signer, testHotEpoch)
+	}
+
+	post := &posts.PostView{
+		URI:       "at://did:plc:community/social.coves.community.post/3kpost",
+		CreatedAt: time.Date(2025, 11, 6, 12, 0, 0, 0, time.UTC),
+		Stats:     &posts.PostStats{Score: 7},
+	}
+	queryTime := time.Date(2025, 11, 6, 13, 0, 0, 0, time.UTC)
+
+	before := newRepo("old-secret", "")
+	during := newRepo("new-secret", "old-secret")
+	after := newRepo("new-secret", "")
+
+	for _, sort := range []string{"new", "top", "hot"} {
+		t.Run(sort, func(t *testing.T) {
+			oldCursor := before.buildCursor(post, firstPage(t, before, sort, "week", queryTime), "week", 0.5)
+
+			page, err := during.buildPage(context.Background(), &oldCursor, sort, "week", 2, time.Now())
+			if err != nil {
+				t.Fatalf("Expected old cursor to be accepted during rotation, got %v", err)
+			}
+			if page.filter == "" || len(page.args) == 0 {
+				t.Fatalf("Expected a cursor filter, got %q %v", page.filter, page.args)
+			}
+
+			// The next page is signed with the new secret, so it survives dropping the old one
+			nextCursor := during.buildCursor(post, page, "week", 0.5)
+			if _, err := after.parseCursor(&nextCursor, sort, "week"); err != nil {
+				t.Errorf("Expected re-signed cursor to be accepted, got %v", err)
+			}
+
+			if _, err := after.parseCursor(&oldCursor, sort, "week"); err == nil {
+				t.Error("Expected old cursor to be rejected once the previous secret is removed")
+			}
+		})
+	}
+}
+
+func TestFeedCursor_RejectsFieldCountMismatch(t *testing.T) {
+	signer, err := pagination.NewSigner("secret", "")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	repo := newCursorTestRepo(ranking.SortClauses(), signer, testHotEpoch)
+
+	// A validly signed "new" cursor replayed against "top" must not be accepted
+	cursor := signer.Encode("top", "", time.Now().Format(time.RFC3339Nano), "at://did:plc:c/social.coves.community.post/3k")
+	if _, err := repo.parseCursor(&cursor, "top", ""); err == nil {
+		t.Error("Expected cursor for another sort to be rejected")
+	}
+}
+
+func TestFeedCursor_BoundToSortAndTimeframe(t *testing.T) {
+	signer, err := pagination.NewSigner("secret", "")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	repo := newCursorTestRepo(ranking.SortClauses(), signer, testHotEpoch)
+	post := &posts.PostView{
+		URI:       "at://did:plc:community/social.coves.community.post/3kpost",
+		CreatedAt: time.Date(2025, 11, 6, 12, 0, 0, 0, time.UTC),
+		Stats:     &posts.PostStats{Score: 7},
+	}
+	queryTime := time.Now()
+
+	topDay := repo.buildCursor(post, firstPage(t, repo, "top", "day", queryTime), "day", 0)
+	if _, err := repo.parseCursor(&topDay, "top", "day"); err != nil {
+		t.Fatalf("Expected cursor to be accepted for its own sort and timeframe, got %v", err)
+	}
+	if _, err := repo.parseCursor(&topDay, "top", "week"); err == nil {
+		t.Error("Expected top-day cursor to be rejected for top-week")
+	}
+
+	// Only top is bounded by a timeframe, so other sorts ignore it
+	hot := repo.buildCursor(post, firstPage(t, repo, "hot", "day", queryTime), "day", 0.5)
+	if _, err := repo.parseCursor(&hot, "hot", ""); err != nil {
+		t.Errorf("Expected hot cursor to ignore the timeframe, got %v", err)
+	}
+	if _, err := repo.parseCursor(&hot, "new", ""); err == nil {
+		t.Error("Expected hot cursor to be rejected for new sort")
+	}
+}
+
+func TestFeedCursor_RowValueKeyset(t *testing.T) {
+	signer, err := pagination.NewSigner("secret", "")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	repo := newCursorTestRepo(communityFeedSortClauses(), signer, testHotEpoch)
+	lastActivity := time.Date(2025, 11, 6, 14, 0, 0, 0, time.UTC)
+	post := &posts.PostView{
+		URI:            "at://did:plc:community/social.coves.community.post/3kpost",
+		CreatedAt:      time.Date(2025, 11, 6, 12, 0, 0, 0, time.UTC),
+		LastActivityAt: &lastActivity,
+		Stats:          &posts.PostStats{Score: 7},
+	}
+
+	tests := []struct {
+		sort       string
+		wantFilter string
+		wantArgs   int
+	}{
+		{sort: "new", wantFilter: "AND (p.created_at, p.uri) < ($3::timestamptz, $4)", wantArgs: 2},
+		{sort: "top", wantFilter: "AND (p.score, p.created_at, p.uri) < ($3, $4::timestamptz, $5)", wantArgs: 3},
+		{sort: "activity", wantFilter: "AND (" + postActivityExpression + ", p.uri) < ($3::timestamptz, $4)", wantArgs: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			cursor := repo.buildCursor(post, firstPage(t, repo, tt.sort, "all", time.Now()), "all", 0)
+			page, err := repo.buildPage(context.Background(), &cursor, tt.sort, "all", 3, time.Now())
+			if err != nil {
+				t.Fatalf("buildPage failed: %v", err)
+			}
+			if page.filter != tt.wantFilter {
+				t.Errorf("Expected filter %q, got %q", tt.wantFilter, page.filter)
+			}
+			if len(page.args) != tt.wantArgs {
+				t.Errorf("Expected %d args, got %v", tt.wantArgs, page.args)
+			}
+		})
+	}
+}
+
+// useLiveHotRank switches hot feeds to the live SQL formula for the rest of the test
+func useLiveHotRank(t *testing.T) {
+	ranking.SetLiveHotRank(true)
+	t.Cleanup(func() { ranking.SetLiveHotRank(false) })
+}
+
+func TestFeedCursor_HotPinsRankAndClock(t *testing.T) {
+	useLiveHotRank(t)
+	signer, err := pagination.NewSigner("secret", "")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	repo := newCursorTestRepo(ranking.SortClauses(), signer, testHotEpoch)
+	post := &posts.PostView{
+		URI:       "at://did:plc:community/social.coves.community.post/3kpost",
+		CreatedAt: time.Date(2025, 11, 6, 12, 0, 0, 0, time.UTC),
+		Stats:     &posts.PostStats{Score: 4},
+	}
+
+	// Nanoseconds are dropped: Postgres would round them, ranking the next page differently
+	now := time.Date(2025, 11, 6, 13, 0, 0, 123456789, time.UTC)
+	first := firstPage(t, repo, "hot", "", now)
+	if want := now.Truncate(time.Microsecond); !first.clock.Equal(want) || first.args[0] != want {
+		t.Fatalf("Expected first page clock %v, got %v (args %v)", want, first.clock, first.args)
+	}
+
+	cursor := repo.buildCursor(post, first, "", 0.25)
+
+	// A later request ranks against the first page's clock, not its own
+	page, err := repo.buildPage(context.Background(), &cursor, "hot", "", 2, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("buildPage failed: %v", err)
+	}
+	if !page.clock.Equal(first.clock) {
+		t.Errorf("Expected clock %v carried from the cursor, got %v", first.clock, page.clock)
+	}
+	if len(page.args) != 4 || page.args[1] != 4 {
+		t.Fatalf("Expected args [clock, score 4, created_at, uri], got %v", page.args)
+	}
+	anchor := ranking.HotRankOf("$3::int", "$4::timestamptz", "$2::timestamptz")
+	if want := "AND (" + page.hotRank + ", p.created_at, p.uri) < (" + anchor + ", $4::timestamptz, $5)"; page.filter != want {
+		t.Errorf("Expected filter %q, got %q", want, page.filter)
+	}
+	if !strings.Contains(page.orderBy, page.hotRank) {
+		t.Errorf("Expected ORDER BY to use the pinned hot rank, got %q", page.orderBy)
+	}
+}
+
+func TestFeedCursor_HotUsesPrecomputedScore(t *testing.T) {
+	signer, err := pagination.NewSigner("secret", "")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	repo := newCursorTestRepo(ranking.SortClauses(), signer, testHotEpoch)
+	post := &posts.PostView{
+		URI:       "at://did:plc:community/social.coves.community.post/3kpost",
+		CreatedAt: time.Date(2025, 11, 6, 12, 0, 0, 0, time.UTC),
+		Stats:     &posts.PostStats{Score: 4},
+	}
+
+	first := firstPage(t, repo, "hot", "", time.Now())
+	if first.hotRank != ranking.HotScoreColumn || len(first.args) != 0 {
+		t.Fatalf("Expected the first page to rank by %s without a clock arg, got %q %v", ranking.HotScoreColumn, first.hotRank, first.args)
+	}
+	if !first.clock.Equal(testHotEpoch) {
+		t.Errorf("Expected the first page clock to be the score epoch %v, got %v", testHotEpoch, first.clock)
+	}
+	if first.orderBy != "p.hot_score DESC, p.created_at DESC, p.uri DESC" {
+		t.Errorf("Expected ORDER BY on hot_score, got %q", first.orderBy)
+	}
+
+	hotRank := 1.0 / 3 // no short decimal form, so the cursor must keep every digit
+	cursor := repo.buildCursor(post, first, "", hotRank)
+	page, err := repo.buildPage(context.Background(), &cursor, "hot", "", 2, time.Now())
+	if err != nil {
+		t.Fatalf("buildPage failed: %v", err)
+	}
+	if want := "AND (p.hot_score, p.created_at, p.uri) < ($2::float8, $3::timestamptz, $4)"; page.filter != want {
+		t.Errorf("Expected filter %q, got %q", want, page.filter)
+	}
+	if len(page.args) != 3 || page.args[0] != hotRank {
+		t.Errorf("Expected args [%v, created_at, uri], got %v", hotRank, page.args)
+	}
+
+	// Cursors stay valid across the rollout flag: a live cursor pages the precomputed feed
+	useLiveHotRank(t)
+	live := newCursorTestRepo(ranking.SortClauses(), signer, testHotEpoch)
+	if !strings.Contains(live.sortClauses["hot"], "POWER(") {
+		t.Errorf("Expected the live formula in the hot sort clause, got %q", live.sortClauses["hot"])
+	}
+	liveCursor := live.buildCursor(post, firstPage(t, live, "hot", "", time.Now()), "", 0.25)
+	ranking.SetLiveHotRank(false)
+	if _, err := repo.buildPage(context.Background(), &liveCursor, "hot", "", 2, time.Now()); err != nil {
+		t.Errorf("Expected a live hot cursor to be accepted, got %v", err)
+	}
+}
+
+func TestFeedCursor_HotSurvivesRescore(t *testing.T) {
+	signer, err := pagination.NewSigner("secret", "")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	post := &posts.PostView{
+		URI:       "at://did:plc:community/social.coves.community.post/3kpost",
+		CreatedAt: time.Date(2025, 11, 6, 12, 0, 0, 0, time.UTC),
+		Stats:     &posts.PostStats{Score: 4},
+	}
+	before := newCursorTestRepo(ranking.SortClauses(), signer, testHotEpoch)
+	cursor := before.buildCursor(post, firstPage(t, before, "hot", "", time.Now()), "", 0.25)
+
+	// The ranker publishes new scores between pages: the next page ranks at the old epoch
+	// instead of keysetting the cursor's rank against rescored rows
+	after := newCursorTestRepo(ranking.SortClauses(), signer, testHotEpoch.Add(15*time.Minute))
+	page, err := after.buildPage(context.Background(), &cursor, "hot", "", 2, time.Now())
+	if err != nil {
+		t.Fatalf("buildPage failed: %v", err)
+	}
+	if want := ranking.HotRank("p", "$2::timestamptz"); page.hotRank != want {
+		t.Errorf("Expected hot rank computed at the cursor's epoch, got %q", page.hotRank)
+	}
+	if len(page.args) != 4 || !page.args[0].(time.Time).Equal(testHotEpoch) || page.args[1] != 4 {
+		t.Fatalf("Expected args [epoch, score 4, created_at, uri], got %v", page.args)
+	}
+	if !strings.Contains(page.filter, "< ("+ranking.HotRankOf("$3::int", "$4::timestamptz", "$2::timestamptz")+",") {
+		t.Errorf("Expected the cursor post's rank recomputed at the epoch, got %q", page.filter)
+	}
+
+	// Before the ranker's first run stored scores have no common epoch, so hot ranks live
+	unranked := newCursorTestRepo(ranking.SortClauses(), signer, time.Time{})
+	now := time.Date(2025, 11, 6, 13, 0, 0, 0, time.UTC)
+	first := firstPage(t, unranked, "hot", "", now)
+	if first.hotRank == ranking.HotScoreColumn || !first.clock.Equal(now) {
+		t.Errorf("Expected a live rank at %v without an epoch, got %q at %v", now, first.hotRank, first.clock)
+	}
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+)
+
+type postgresHostVerificationRepo struct {
+	db *sql.DB
+}
+
+// NewHostVerificationRepository creates a new PostgreSQL repository for hostedBy verification results
+func NewHostVerificationRepository(db *sql.DB) communities.HostVerificationRepository {
+	return &postgresHostVerificationRepo{db: db}
+}
+
+// GetHostVerification returns the stored verification result for a domain and DID
+func (r *postgresHostVerificationRepo) GetHostVerification(ctx context.Context, domain, did string) (*communities.HostVerification, error) {
+	query := `
+		SELECT domain, did, valid, COALESCE(failure_reason, ''), verified_at, expires_at
+		FROM hosted_by_verifications
+		WHERE domain = $1 AND did = $2`
+
+	v := &communities.HostVerification{}
+	err := r.db.QueryRowContext(ctx, query, domain, did).Scan(&v.Domain, &v.DID, &v.Valid, &v.Reason, &v.VerifiedAt, &v.ExpiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, communities.ErrHostVerificationNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get host verification: %w", err)
+	}
+	return v, nil
+}
+
+// SaveHostVerification stores a verification result, replacing any previous result
+func (r *postgresHostVerificationRepo) SaveHostVerification(ctx context.Context, v *communities.HostVerification) error {
+	query := `
+		INSERT INTO hosted_by_verifications (domain, did, valid, failure_reason, verified_at, expires_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+		ON CONFLICT (domain, did) DO UPDATE SET
+			valid = EXCLUDED.valid,
+			failure_reason = EXCLUDED.failure_reason,
+			verified_at = EXCLUDED.verified_at,
+			expires_at = EXCLUDED.expires_at`
+
+	if _, err := r.db.ExecContext(ctx, query, v.Domain, v.DID, v.Valid, v.Reason, v.VerifiedAt, v.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to save host verification: %w", err)
+	}
+	return nil
+}
+
+// ListHostedByDIDs returns the distinct did:web hostedBy DIDs of indexed communities
+func (r *postgresHostVerificationRepo) ListHostedByDIDs(ctx context.Context) ([]string, error) {
+	query := `
+		SELECT DISTINCT hosted_by_did
+		FROM communities
+		WHERE hosted_by_did LIKE 'did:web:%'
+		ORDER BY hosted_by_did`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list hostedBy DIDs: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	dids := []string{}
+	for rows.Next() {
+		var did string
+		if err := rows.Scan(&did); err != nil {
+			return nil, fmt.Errorf("failed to scan hostedBy DID: %w", err)
+		}
+		dids = append(dids, did)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating hostedBy DIDs: %w", err)
+	}
+	return dids, nil
+}
+
+// SetHostedByUnverified flags or clears every community hosted by hostedByDID
+func (r *postgresHostVerificationRepo) SetHostedByUnverified(ctx context.Context, hostedByDID string, unverified bool) (int, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE communities
+		SET hosted_by_unverified = $2
+		WHERE hosted_by_did = $1 AND hosted_by_unverified <> $2
+	`, hostedByDID, unverified)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update hosted_by_unverified: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check update result: %w", err)
+	}
+	return int(rows), nil
+}
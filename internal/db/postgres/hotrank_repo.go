@@ -0,0 +1,109 @@
+package postgres
+
+import (
+	"Coves/internal/core/hotrank"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type postgresHotRankRepo struct {
+	db *sql.DB
+}
+
+// NewHotRankRepository creates a PostgreSQL store for post hot scores
+func NewHotRankRepository(db *sql.DB) hotrank.Store {
+	return &postgresHotRankRepo{db: db}
+}
+
+// ListRecentPosts returns a batch of live posts after a (created_at, uri) key, oldest first
+// The row comparison is a range scan of idx_posts_created_uri.
+func (r *postgresHotRankRepo) ListRecentPosts(ctx context.Context, after hotrank.PostScore, limit int) ([]hotrank.PostScore, error) {
+	query := `
+		SELECT uri, score, created_at
+		FROM posts
+		WHERE (created_at, uri) > ($1, $2) AND deleted_at IS NULL
+		ORDER BY created_at, uri
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, after.CreatedAt, after.URI, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent posts: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	var batch []hotrank.PostScore
+	for rows.Next() {
+		var post hotrank.PostScore
+		if err := rows.Scan(&post.URI, &post.Score, &post.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan post score: %w", err)
+		}
+		batch = append(batch, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating post scores: %w", err)
+	}
+	return batch, nil
+}
+
+// hotScoreChunk is how many hot scores PublishHotScores writes per statement
+const hotScoreChunk = 1000
+
+// PublishHotScores writes hot scores, zeroes posts created before cutoff and sets the epoch in
+// one transaction, so hot feeds see every score from a run or none of them
+func (r *postgresHotRankRepo) PublishHotScores(ctx context.Context, scores map[string]float64, cutoff, epoch time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	uris := make([]string, 0, len(scores))
+	values := make([]float64, 0, len(scores))
+	for uri, score := range scores {
+		uris = append(uris, uri)
+		values = append(values, score)
+	}
+	// Unchanged rows are skipped
+	for start := 0; start < len(uris); start += hotScoreChunk {
+		end := min(start+hotScoreChunk, len(uris))
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE posts p
+			SET hot_score = v.hot_score
+			FROM unnest($1::text[], $2::float8[]) AS v(uri, hot_score)
+			WHERE p.uri = v.uri AND p.hot_score IS DISTINCT FROM v.hot_score`,
+			pq.Array(uris[start:end]), pq.Array(values[start:end])); err != nil {
+			return fmt.Errorf("failed to set hot scores: %w", err)
+		}
+	}
+
+	// Both ranges of hot_score are index seeks on idx_posts_hot_score, so this stays cheap
+	// however many old posts there are
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE posts
+		SET hot_score = 0
+		WHERE (hot_score > 0 OR hot_score < 0) AND created_at < $1 AND deleted_at IS NULL`,
+		cutoff); err != nil {
+		return fmt.Errorf("failed to clear aged out hot scores: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE hot_rank_epoch SET scored_at = $1`, epoch); err != nil {
+		return fmt.Errorf("failed to set hot rank epoch: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit hot scores: %w", err)
+	}
+	return nil
+}
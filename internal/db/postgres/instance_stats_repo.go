@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"Coves/internal/core/instance"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type postgresInstanceStatsRepo struct {
+	db *sql.DB
+}
+
+// NewInstanceStatsRepository creates a PostgreSQL repository for the public instance statistics
+func NewInstanceStatsRepository(db *sql.DB) instance.Repository {
+	return &postgresInstanceStatsRepo{db: db}
+}
+
+// instanceTotalsQuery counts communities, users and local content
+// Parameters: $1 instance DID
+var instanceTotalsQuery = `
+	SELECT
+		(SELECT COUNT(*) FROM communities WHERE hosted_by_did = $1 AND deleted_at IS NULL),
+		(SELECT COUNT(*) FROM communities WHERE hosted_by_did <> $1 AND deleted_at IS NULL),
+		(SELECT COUNT(*) FROM users),
+		(SELECT COUNT(*)
+			FROM posts p
+			JOIN communities c ON c.did = p.community_did
+			WHERE c.hosted_by_did = $1 AND ` + notDeleted("p") + ` AND ` + notTakenDown("p") + `),
+		(SELECT COUNT(*)
+			FROM comments cm
+			JOIN posts p ON p.uri = cm.root_uri
+			JOIN communities c ON c.did = p.community_did
+			WHERE c.hosted_by_did = $1 AND ` + notDeleted("cm") + ` AND ` + notTakenDown("cm") + `)`
+
+// instanceActivityQuery counts recent posts, comments and their distinct authors in one pass
+// over the last 180 days, using idx_posts_created_uri (migration 050) and idx_comments_created (migration 074)
+// Parameters: $1 half year, $2 month, $3 week, $4 day ago
+var instanceActivityQuery = `
+	WITH activity AS (
+		SELECT p.author_did AS did, p.created_at, TRUE AS is_post
+		FROM posts p
+		WHERE p.created_at >= $1 AND ` + notDeleted("p") + ` AND ` + notTakenDown("p") + `
+		UNION ALL
+		SELECT cm.commenter_did, cm.created_at, FALSE
+		FROM comments cm
+		WHERE cm.created_at >= $1 AND ` + notDeleted("cm") + ` AND ` + notTakenDown("cm") + `
+	)
+	SELECT
+		COUNT(*) FILTER (WHERE is_post AND created_at >= $4),
+		COUNT(*) FILTER (WHERE is_post AND created_at >= $3),
+		COUNT(*) FILTER (WHERE NOT is_post AND created_at >= $4),
+		COUNT(*) FILTER (WHERE NOT is_post AND created_at >= $3),
+		COUNT(DISTINCT did) FILTER (WHERE created_at >= $4),
+		COUNT(DISTINCT did) FILTER (WHERE created_at >= $3),
+		COUNT(DISTINCT did) FILTER (WHERE created_at >= $2),
+		COUNT(DISTINCT did)
+	FROM activity`
+
+// GetCounts runs the totals and the recent activity queries
+// There's no sign-in activity table, so active users are those who posted or commented.
+func (r *postgresInstanceStatsRepo) GetCounts(ctx context.Context, instanceDID string, now time.Time) (*instance.Counts, error) {
+	var counts instance.Counts
+	if err := r.db.QueryRowContext(ctx, instanceTotalsQuery, instanceDID).Scan(
+		&counts.Communities, &counts.RemoteCommunities, &counts.Users, &counts.LocalPosts, &counts.LocalComments,
+	); err != nil {
+		return nil, fmt.Errorf("failed to count instance totals: %w", err)
+	}
+
+	if err := r.db.QueryRowContext(ctx, instanceActivityQuery,
+		now.AddDate(0, 0, -180), now.AddDate(0, 0, -30), now.AddDate(0, 0, -7), now.AddDate(0, 0, -1),
+	).Scan(
+		&counts.Posts.Day, &counts.Posts.Week,
+		&counts.Comments.Day, &counts.Comments.Week,
+		&counts.ActiveUsers.Day, &counts.ActiveUsers.Week, &counts.ActiveUsers.Month, &counts.ActiveUsers.HalfYear,
+	); err != nil {
+		return nil, fmt.Errorf("failed to count recent activity: %w", err)
+	}
+	return &counts, nil
+}
@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"Coves/internal/core/moderation"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+type postgresModerationRepo struct {
+	db *sql.DB
+}
+
+// NewModerationRepository creates a new PostgreSQL repository for content visibility and thread locks
+func NewModerationRepository(db *sql.DB) moderation.Repository {
+	return &postgresModerationRepo{db: db}
+}
+
+// SetPostVisibility updates a post's visibility state
+func (r *postgresModerationRepo) SetPostVisibility(ctx context.Context, uri string, state moderation.VisibilityState, actorDID string) error {
+	return r.setVisibility(ctx, "posts", uri, state, actorDID)
+}
+
+// SetCommentVisibility updates a comment's visibility state
+func (r *postgresModerationRepo) SetCommentVisibility(ctx context.Context, uri string, state moderation.VisibilityState, actorDID string) error {
+	return r.setVisibility(ctx, "comments", uri, state, actorDID)
+}
+
+// setVisibility updates the visibility state of a row in table ("posts" or "comments")
+func (r *postgresModerationRepo) setVisibility(ctx context.Context, table, uri string, state moderation.VisibilityState, actorDID string) error {
+	query := fmt.Sprintf(`
+		UPDATE %s
+		SET visibility_state = $2, visibility_updated_by = $3, visibility_updated_at = NOW()
+		WHERE uri = $1`, table)
+
+	result, err := r.db.ExecContext(ctx, query, uri, string(state), actorDID)
+	if err != nil {
+		return fmt.Errorf("failed to update %s visibility: %w", table, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check update result: %w", err)
+	}
+	if rows == 0 {
+		return moderation.ErrContentNotFound
+	}
+	return nil
+}
+
+// SetPostLocked locks or unlocks a post's comment thread
+// locked_by and locked_at record the last change, including unlocks
+func (r *postgresModerationRepo) SetPostLocked(ctx context.Context, uri string, locked bool, actorDID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE posts
+		SET locked = $2, locked_by = $3, locked_at = NOW()
+		WHERE uri = $1`, uri, locked, actorDID)
+	if err != nil {
+		return fmt.Errorf("failed to update post lock: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check update result: %w", err)
+	}
+	if rows == 0 {
+		return moderation.ErrContentNotFound
+	}
+	return nil
+}
+
+// SetPostLabelOverride applies or removes a label on a post, replacing any earlier override
+func (r *postgresModerationRepo) SetPostLabelOverride(ctx context.Context, uri, label string, neg bool, actorDID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO post_label_overrides (post_uri, label, neg, created_by)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (post_uri, label) DO UPDATE
+		SET neg = EXCLUDED.neg, created_by = EXCLUDED.created_by, created_at = NOW()`,
+		uri, label, neg, actorDID)
+	if err != nil {
+		if _, ok := constraintViolation(err, pqForeignKeyViolation); ok {
+			return moderation.ErrContentNotFound
+		}
+		return fmt.Errorf("failed to set post label override: %w", err)
+	}
+	return nil
+}
+
+// ClearPostLabelOverride deletes a post's override for label
+// Clearing a label with no override succeeds as long as the post is indexed
+func (r *postgresModerationRepo) ClearPostLabelOverride(ctx context.Context, uri, label string) error {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `
+		WITH cleared AS (
+			DELETE FROM post_label_overrides WHERE post_uri = $1 AND label = $2
+		)
+		SELECT EXISTS(SELECT 1 FROM posts WHERE uri = $1)`, uri, label).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to clear post label override: %w", err)
+	}
+	if !exists {
+		return moderation.ErrContentNotFound
+	}
+	return nil
+}
+
+// IsCommentModerator reports whether actorDID created or moderates the community of the comment's root post
+// Returns ErrContentNotFound when the comment isn't indexed
+func (r *postgresModerationRepo) IsCommentModerator(ctx context.Context, uri, actorDID string) (bool, error) {
+	var isModerator bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			EXISTS (
+				SELECT 1 FROM communities co
+				WHERE co.did = p.community_did AND co.created_by_did = $2
+			) OR EXISTS (
+				SELECT 1 FROM community_memberships m
+				WHERE m.community_did = p.community_did AND m.user_did = $2 AND m.is_moderator = TRUE
+			)
+		FROM comments c
+		LEFT JOIN posts p ON p.uri = c.root_uri
+		WHERE c.uri = $1`, uri, actorDID).Scan(&isModerator)
+	if err == sql.ErrNoRows {
+		return false, moderation.ErrContentNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check comment moderator: %w", err)
+	}
+	return isModerator, nil
+}
+
+// IsCommunityModerator reports whether actorDID created or moderates the community
+func (r *postgresModerationRepo) IsCommunityModerator(ctx context.Context, communityDID, actorDID string) (bool, error) {
+	var isModerator bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT
+			EXISTS (
+				SELECT 1 FROM communities
+				WHERE did = $1 AND created_by_did = $2
+			) OR EXISTS (
+				SELECT 1 FROM community_memberships
+				WHERE community_did = $1 AND user_did = $2 AND is_moderator = TRUE
+			)`, communityDID, actorDID).Scan(&isModerator)
+	if err != nil {
+		return false, fmt.Errorf("failed to check community moderator: %w", err)
+	}
+	return isModerator, nil
+}
+
+// ListRevisions returns up to limit prior versions of a post or comment, newest first
+func (r *postgresModerationRepo) ListRevisions(ctx context.Context, subjectURI string, limit int) ([]*moderation.Revision, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT cid, COALESCE(content, ''), content_facets, embed, written_at, replaced_at
+		FROM content_revisions
+		WHERE subject_uri = $1
+		ORDER BY id DESC
+		LIMIT $2`, subjectURI, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	revisions := []*moderation.Revision{}
+	for rows.Next() {
+		var revision moderation.Revision
+		var facets, embed []byte
+		if err := rows.Scan(&revision.CID, &revision.Content, &facets, &embed, &revision.WrittenAt, &revision.ReplacedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		revision.ContentFacets = facets
+		revision.Embed = embed
+		revisions = append(revisions, &revision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating revisions: %w", err)
+	}
+	return revisions, nil
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"Coves/internal/core/notifications"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+type postgresNotificationRepo struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository creates a new PostgreSQL notification repository
+func NewNotificationRepository(db *sql.DB) notifications.Repository {
+	return &postgresNotificationRepo{db: db}
+}
+
+// List returns the recipient's notifications, latest activity first
+// The actor is the newest reply's author for grouped replies, the record author otherwise.
+func (r *postgresNotificationRepo) List(ctx context.Context, recipientDID string, limit, offset int) ([]*notifications.Notification, error) {
+	query := `
+		SELECT n.id, n.reason, n.subject_uri, COALESCE(n.subject_parent_uri, ''),
+			COALESCE(n.latest_actor_did, n.author_did), COALESCE(u.handle, ''),
+			n.reply_count, n.is_read, n.created_at, n.updated_at
+		FROM notifications n
+		LEFT JOIN users u ON u.did = COALESCE(n.latest_actor_did, n.author_did)
+		WHERE n.recipient_did = $1
+		ORDER BY n.updated_at DESC, n.id DESC
+		LIMIT $2 OFFSET $3`
+
+	rows, err := r.db.QueryContext(ctx, query, recipientDID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	list := []*notifications.Notification{}
+	for rows.Next() {
+		var n notifications.Notification
+		if err := rows.Scan(
+			&n.ID, &n.Reason, &n.SubjectURI, &n.SubjectParentURI,
+			&n.Actor.DID, &n.Actor.Handle,
+			&n.ReplyCount, &n.IsRead, &n.CreatedAt, &n.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan notification: %w", err)
+		}
+		if n.Reason != notifications.ReasonReply {
+			n.ReplyCount = 0
+		}
+		list = append(list, &n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notifications: %w", err)
+	}
+	return list, nil
+}
+
+// MarkSeen marks the recipient's notifications read
+// A grouped reply notification is one row, so this marks the whole group.
+func (r *postgresNotificationRepo) MarkSeen(ctx context.Context, recipientDID string, ids []int64) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE notifications
+		SET is_read = TRUE
+		WHERE recipient_did = $1 AND id = ANY($2) AND is_read = FALSE`,
+		recipientDID, pq.Array(ids))
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications seen: %w", err)
+	}
+	marked, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count notifications marked seen: %w", err)
+	}
+	return marked, nil
+}
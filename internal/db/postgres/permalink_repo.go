@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"Coves/internal/atproto/aturi"
+	"Coves/internal/core/permalinks"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/lib/pq"
+)
+
+type postgresPermalinkRepo struct {
+	db *sql.DB
+}
+
+// NewPermalinkRepository creates a PostgreSQL repository for permalink subjects
+func NewPermalinkRepository(db *sql.DB) permalinks.Repository {
+	return &postgresPermalinkRepo{db: db}
+}
+
+// hiddenCommunity is true for a joined community whose subjects readers can't see at all:
+// suspended, on a federation-blocked instance, or private. An unindexed community (c.did NULL)
+// isn't hidden.
+const hiddenCommunity = `(c.suspended_at IS NOT NULL OR c.federation_blocked OR c.visibility = 'private')`
+
+// GetSubjects looks up posts, comments and communities by URI, deleted ones included
+// Handles come from the communities table, so they're current even if a community's handle
+// changed after the subject was written. Taken-down and moderator-removed content is reported
+// as deleted, and subjects of hidden communities are left out.
+func (r *postgresPermalinkRepo) GetSubjects(ctx context.Context, uris []string) (map[string]*permalinks.Subject, error) {
+	var postURIs, commentURIs, communityDIDs []string
+	for _, uri := range uris {
+		parsed, err := aturi.Parse(uri)
+		if err != nil {
+			continue
+		}
+		switch parsed.Collection {
+		case "social.coves.community.post":
+			postURIs = append(postURIs, uri)
+		case "social.coves.community.comment":
+			commentURIs = append(commentURIs, uri)
+		case "":
+			communityDIDs = append(communityDIDs, parsed.Authority)
+		}
+	}
+
+	subjects := make(map[string]*permalinks.Subject, len(uris))
+	if len(postURIs) > 0 {
+		err := r.query(ctx, `
+			SELECT p.uri, p.community_did, COALESCE(c.handle, ''), p.rkey,
+				p.deleted_at IS NOT NULL OR p.taken_down_at IS NOT NULL OR p.visibility_state = 'removed'
+					OR c.deleted_at IS NOT NULL
+			FROM posts p
+			LEFT JOIN communities c ON c.did = p.community_did
+			WHERE p.uri = ANY($1) AND NOT COALESCE(`+hiddenCommunity+`, FALSE)`,
+			postURIs, func(rows *sql.Rows) error {
+				var uri string
+				subject := permalinks.Subject{Type: permalinks.SubjectPost}
+				if err := rows.Scan(&uri, &subject.CommunityDID, &subject.CommunityHandle, &subject.PostRKey, &subject.Deleted); err != nil {
+					return fmt.Errorf("failed to scan post permalink: %w", err)
+				}
+				subjects[uri] = &subject
+				return nil
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(commentURIs) > 0 {
+		// The thread's post may not be indexed; its URI still names the community and rkey
+		err := r.query(ctx, `
+			SELECT cm.uri, cm.root_uri, COALESCE(c.handle, ''), cm.rkey,
+				cm.deleted_at IS NOT NULL OR cm.taken_down_at IS NOT NULL OR cm.visibility_state = 'removed'
+					OR c.deleted_at IS NOT NULL
+			FROM comments cm
+			LEFT JOIN posts p ON p.uri = cm.root_uri
+			LEFT JOIN communities c ON c.did = p.community_did
+			WHERE cm.uri = ANY($1) AND NOT COALESCE(`+hiddenCommunity+`, FALSE)`,
+			commentURIs, func(rows *sql.Rows) error {
+				var uri, rootURI string
+				subject := permalinks.Subject{Type: permalinks.SubjectComment}
+				if err := rows.Scan(&uri, &rootURI, &subject.CommunityHandle, &subject.CommentRKey, &subject.Deleted); err != nil {
+					return fmt.Errorf("failed to scan comment permalink: %w", err)
+				}
+				root, err := aturi.Parse(rootURI)
+				if err != nil {
+					log.Printf("Skipping permalink of comment %s with invalid root %q: %v", uri, rootURI, err)
+					return nil
+				}
+				subject.CommunityDID = root.Authority
+				subject.PostRKey = root.RKey
+				subjects[uri] = &subject
+				return nil
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(communityDIDs) > 0 {
+		err := r.query(ctx, `
+			SELECT c.did, c.handle, c.deleted_at IS NOT NULL
+			FROM communities c
+			WHERE c.did = ANY($1) AND NOT `+hiddenCommunity,
+			communityDIDs, func(rows *sql.Rows) error {
+				subject := permalinks.Subject{Type: permalinks.SubjectCommunity}
+				if err := rows.Scan(&subject.CommunityDID, &subject.CommunityHandle, &subject.Deleted); err != nil {
+					return fmt.Errorf("failed to scan community permalink: %w", err)
+				}
+				subjects[aturi.URI{Authority: subject.CommunityDID}.String()] = &subject
+				return nil
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return subjects, nil
+}
+
+// query runs a permalink subject query over a URI or DID list, passing each row to scan
+func (r *postgresPermalinkRepo) query(ctx context.Context, query string, arg []string, scan func(*sql.Rows) error) error {
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(arg))
+	if err != nil {
+		return fmt.Errorf("failed to look up permalink subjects: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating permalink subjects: %w", err)
+	}
+	return nil
+}
@@ -3,7 +3,6 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -13,15 +12,20 @@ import (
 	"Coves/internal/core/blobs"
 	"Coves/internal/core/communities"
 	"Coves/internal/core/posts"
+	"Coves/internal/pagination"
+
+	"github.com/lib/pq"
 )
 
 type postgresPostRepo struct {
-	db *sql.DB
+	db      *sql.DB
+	cursors *pagination.Signer
 }
 
 // NewPostRepository creates a new PostgreSQL post repository
-func NewPostRepository(db *sql.DB) posts.Repository {
-	return &postgresPostRepo{db: db}
+// cursors seals the author feed's pagination cursors.
+func NewPostRepository(db *sql.DB, cursors *pagination.Signer) posts.Repository {
+	return &postgresPostRepo{db: db, cursors: cursors}
 }
 
 // Create inserts a new post into the posts table
@@ -53,11 +57,11 @@ func (r *postgresPostRepo) Create(ctx context.Context, post *posts.Post) error {
 		INSERT INTO posts (
 			uri, cid, rkey, author_did, community_did,
 			title, content, content_facets, embed, content_labels,
-			created_at, indexed_at
+			created_at, indexed_at, tags, labels
 		) VALUES (
 			$1, $2, $3, $4, $5,
 			$6, $7, $8, $9, $10,
-			$11, NOW()
+			$11, NOW(), COALESCE($12, '{}'::text[]), COALESCE($13, '{}'::text[])
 		)
 		RETURNING id, indexed_at
 	`
@@ -66,21 +70,21 @@ func (r *postgresPostRepo) Create(ctx context.Context, post *posts.Post) error {
 		ctx, query,
 		post.URI, post.CID, post.RKey, post.AuthorDID, post.CommunityDID,
 		post.Title, post.Content, facetsJSON, embedJSON, labelsJSON,
-		post.CreatedAt,
+		post.CreatedAt, pq.Array(post.Tags), pq.Array(post.Labels),
 	).Scan(&post.ID, &post.IndexedAt)
 	if err != nil {
 		// Check for duplicate URI (post already indexed)
-		if strings.Contains(err.Error(), "duplicate key") && strings.Contains(err.Error(), "posts_uri_key") {
-			return fmt.Errorf("post already indexed: %s", post.URI)
+		if constraint, ok := constraintViolation(err, pqUniqueViolation); ok && constraint == "posts_uri_key" {
+			return fmt.Errorf("%w: %s", posts.ErrAlreadyIndexed, post.URI)
 		}
 
 		// Check for foreign key violations
-		if strings.Contains(err.Error(), "violates foreign key constraint") {
-			if strings.Contains(err.Error(), "fk_author") {
-				return fmt.Errorf("author DID not found: %s", post.AuthorDID)
-			}
-			if strings.Contains(err.Error(), "fk_community") {
-				return fmt.Errorf("community DID not found: %s", post.CommunityDID)
+		if constraint, ok := constraintViolation(err, pqForeignKeyViolation); ok {
+			switch constraint {
+			case "fk_author":
+				return fmt.Errorf("author %s: %w", post.AuthorDID, posts.ErrActorNotFound)
+			case "fk_community":
+				return fmt.Errorf("community %s: %w", post.CommunityDID, posts.ErrCommunityNotFound)
 			}
 		}
 
@@ -90,18 +94,21 @@ func (r *postgresPostRepo) Create(ctx context.Context, post *posts.Post) error {
 	return nil
 }
 
-// GetByURI retrieves a post by its AT-URI
-// Used for E2E test verification and future GET endpoint
+// GetByURI retrieves a non-deleted post by its AT-URI
+// Soft-deleted posts return posts.ErrNotFound
 func (r *postgresPostRepo) GetByURI(ctx context.Context, uri string) (*posts.Post, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
 			id, uri, cid, rkey, author_did, community_did,
 			title, content, content_facets, embed, content_labels,
 			created_at, edited_at, indexed_at, deleted_at,
-			upvote_count, downvote_count, score, comment_count
+			upvote_count, downvote_count, score, comment_count, top_level_comment_count, last_activity_at, tags,
+			locked, labels,
+			ARRAY(SELECT o.label FROM post_label_overrides o WHERE o.post_uri = posts.uri AND NOT o.neg),
+			ARRAY(SELECT o.label FROM post_label_overrides o WHERE o.post_uri = posts.uri AND o.neg)
 		FROM posts
-		WHERE uri = $1
-	`
+		WHERE uri = $1 AND %s
+	`, notDeleted(""))
 
 	var post posts.Post
 	var facetsJSON, embedJSON, labelsJSON sql.NullString
@@ -112,6 +119,9 @@ func (r *postgresPostRepo) GetByURI(ctx context.Context, uri string) (*posts.Pos
 		&post.Title, &post.Content, &facetsJSON, &embedJSON, &labelsJSON,
 		&post.CreatedAt, &post.EditedAt, &post.IndexedAt, &post.DeletedAt,
 		&post.UpvoteCount, &post.DownvoteCount, &post.Score, &post.CommentCount,
+		&post.TopLevelCommentCount, &post.LastActivityAt, pq.Array(&post.Tags),
+		&post.Locked, pq.Array(&post.Labels),
+		pq.Array(&post.LabelOverrides.Applied), pq.Array(&post.LabelOverrides.Removed),
 	)
 
 	if err == sql.ErrNoRows {
@@ -142,12 +152,15 @@ func (r *postgresPostRepo) GetByURI(ctx context.Context, uri string) (*posts.Pos
 // Returns []*PostView, next cursor, and error
 func (r *postgresPostRepo) GetByAuthor(ctx context.Context, req posts.GetAuthorPostsRequest) ([]*posts.PostView, *string, error) {
 	// Build WHERE clauses based on filters
+	// Removed and author_only posts (including quarantined and automod-held ones) are only
+	// listed for their author
 	whereConditions := []string{
 		"p.author_did = $1",
-		"p.deleted_at IS NULL",
+		notDeleted("p"),
+		visibleTo("p", "author_did", "$2"),
 	}
-	args := []interface{}{req.ActorDID}
-	paramIndex := 2
+	args := []interface{}{req.ActorDID, req.ViewerDID}
+	paramIndex := 3
 
 	// Optional community filter
 	if req.Community != "" {
@@ -197,18 +210,21 @@ func (r *postgresPostRepo) GetByAuthor(ctx context.Context, req posts.GetAuthorP
 	query := fmt.Sprintf(`
 		SELECT
 			p.uri, p.cid, p.rkey,
-			p.author_did, u.handle as author_handle,
+			p.author_did, u.handle as author_handle, u.confusable_with IS NOT NULL as author_confusable, u.avatar_cid as author_avatar, u.pds_url as author_pds_url,
 			p.community_did, c.handle as community_handle, c.name as community_name, c.avatar_cid as community_avatar, c.pds_url as community_pds_url,
-			p.title, p.content, p.content_facets, p.embed, p.content_labels,
+			p.title, p.content, p.content_facets, p.embed, p.content_labels, p.tags,
 			p.created_at, p.edited_at, p.indexed_at,
-			p.upvote_count, p.downvote_count, p.score, p.comment_count
+			p.upvote_count, p.downvote_count, p.score, p.comment_count, p.top_level_comment_count, p.last_activity_at, c.score_hiding_hours,
+			%s,
+			%s
 		FROM posts p
 		INNER JOIN users u ON p.author_did = u.did
 		INNER JOIN communities c ON p.community_did = c.did
 		WHERE %s
+			AND c.deleted_at IS NULL
 		ORDER BY p.created_at DESC, p.uri DESC
 		LIMIT $%d
-	`, whereClause, paramIndex)
+	`, postLanguageColumn, postLabelColumns, whereClause, paramIndex)
 
 	// Execute query
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -247,9 +263,52 @@ func (r *postgresPostRepo) GetByAuthor(ctx context.Context, req posts.GetAuthorP
 	return postViews, cursor, nil
 }
 
+// GetViewByURI retrieves a single hydrated post view by AT-URI
+// Deleted, taken-down and hidden posts (author_only posts to anyone but their author) return
+// posts.ErrNotFound. Unlike the author feed it doesn't filter on the community's state, so the
+// service can say why a post in a deleted or suspended community is unavailable.
+func (r *postgresPostRepo) GetViewByURI(ctx context.Context, uri, viewerDID string) (*posts.PostView, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			p.uri, p.cid, p.rkey,
+			p.author_did, u.handle as author_handle, u.confusable_with IS NOT NULL as author_confusable, u.avatar_cid as author_avatar, u.pds_url as author_pds_url,
+			p.community_did, c.handle as community_handle, c.name as community_name, c.avatar_cid as community_avatar, c.pds_url as community_pds_url,
+			p.title, p.content, p.content_facets, p.embed, p.content_labels, p.tags,
+			p.created_at, p.edited_at, p.indexed_at,
+			p.upvote_count, p.downvote_count, p.score, p.comment_count, p.top_level_comment_count, p.last_activity_at, c.score_hiding_hours,
+			%s,
+			%s
+		FROM posts p
+		INNER JOIN users u ON p.author_did = u.did
+		INNER JOIN communities c ON p.community_did = c.did
+		WHERE p.uri = $1 AND %s AND %s
+	`, postLanguageColumn, postLabelColumns, notDeleted("p"), visibleTo("p", "author_did", "$2"))
+
+	rows, err := r.db.QueryContext(ctx, query, uri, viewerDID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query post view: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("failed to close rows", "error", err)
+		}
+	}()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("failed to query post view: %w", err)
+		}
+		return nil, posts.ErrNotFound
+	}
+	postView, err := r.scanAuthorPost(rows)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan post view: %w", err)
+	}
+	return postView, nil
+}
+
 // parseAuthorPostsCursor decodes pagination cursor for author posts
-// Cursor format: base64(created_at|uri)
-// Uses simple | delimiter since this is an internal cursor (not signed like feed cursors)
+// Cursor fields: created_at, uri, sealed with the cursor signer like the feed cursors
 // Returns filter clause, arguments, and error. Error is returned for malformed cursors
 // to provide clear feedback rather than silently returning the first page.
 func (r *postgresPostRepo) parseAuthorPostsCursor(cursor *string, paramOffset int) (string, []interface{}, error) {
@@ -257,20 +316,11 @@ func (r *postgresPostRepo) parseAuthorPostsCursor(cursor *string, paramOffset in
 		return "", nil, nil
 	}
 
-	// Validate cursor size to prevent DoS via massive base64 strings
-	const maxCursorSize = 512
-	if len(*cursor) > maxCursorSize {
-		return "", nil, fmt.Errorf("%w: cursor exceeds maximum length", posts.ErrInvalidCursor)
-	}
-
-	// Decode base64 cursor
-	decoded, err := base64.URLEncoding.DecodeString(*cursor)
+	// Decode bounds the cursor's size before decrypting it
+	parts, err := r.cursors.Decode(*cursor)
 	if err != nil {
-		return "", nil, fmt.Errorf("%w: invalid base64 encoding", posts.ErrInvalidCursor)
+		return "", nil, fmt.Errorf("%w: %w", posts.ErrInvalidCursor, err)
 	}
-
-	// Parse cursor: created_at|uri
-	parts := strings.Split(string(decoded), "|")
 	if len(parts) != 2 {
 		return "", nil, fmt.Errorf("%w: malformed cursor format", posts.ErrInvalidCursor)
 	}
@@ -295,21 +345,28 @@ func (r *postgresPostRepo) parseAuthorPostsCursor(cursor *string, paramOffset in
 	return filter, []interface{}{createdAt, uri}, nil
 }
 
-// buildAuthorPostsCursor creates pagination cursor from last post
-// Cursor format: base64(created_at|uri)
+// buildAuthorPostsCursor creates a sealed pagination cursor from last post
+// Cursor fields: created_at, uri
 func (r *postgresPostRepo) buildAuthorPostsCursor(post *posts.PostView) string {
-	cursorStr := fmt.Sprintf("%s|%s", post.CreatedAt.Format(time.RFC3339Nano), post.URI)
-	return base64.URLEncoding.EncodeToString([]byte(cursorStr))
+	return r.cursors.Encode(post.CreatedAt.Format(time.RFC3339Nano), post.URI)
 }
 
-// SoftDelete marks a post as deleted by setting deleted_at
+// SoftDelete marks a post as deleted by setting deleted_at, and takes it off its community's post_count
 // Called by Jetstream consumer after post is deleted from PDS
 // Idempotent: Returns success if post already deleted or doesn't exist
 func (r *postgresPostRepo) SoftDelete(ctx context.Context, uri string) error {
+	// The deleted_at guard makes a replayed delete a no-op, so the count drops once
 	query := `
-		UPDATE posts
-		SET deleted_at = NOW()
-		WHERE uri = $1 AND deleted_at IS NULL
+		WITH deleted AS (
+			UPDATE posts
+			SET deleted_at = NOW()
+			WHERE uri = $1 AND deleted_at IS NULL
+			RETURNING community_did
+		)
+		UPDATE communities c
+		SET post_count = GREATEST(0, c.post_count - 1)
+		FROM deleted
+		WHERE c.did = deleted.community_did
 	`
 	_, err := r.db.ExecContext(ctx, query, uri)
 	if err != nil {
@@ -327,25 +384,36 @@ func (r *postgresPostRepo) scanAuthorPost(rows *sql.Rows) (*posts.PostView, erro
 		title, content  sql.NullString
 		facets, embed   sql.NullString
 		labelsJSON      sql.NullString
+		tags            []string
 		editedAt        sql.NullTime
+		authorAvatar    sql.NullString
+		authorPDSURL    sql.NullString
 		communityHandle sql.NullString
 		communityAvatar sql.NullString
 		communityPDSURL sql.NullString
+		language        sql.NullString
+		labels          labelColumns
 	)
 
 	err := rows.Scan(
 		&postView.URI, &postView.CID, &postView.RKey,
-		&authorView.DID, &authorView.Handle,
+		&authorView.DID, &authorView.Handle, &authorView.ConfusableFlag, &authorAvatar, &authorPDSURL,
 		&communityRef.DID, &communityHandle, &communityRef.Name, &communityAvatar, &communityPDSURL,
-		&title, &content, &facets, &embed, &labelsJSON,
+		&title, &content, &facets, &embed, &labelsJSON, pq.Array(&tags),
 		&postView.CreatedAt, &editedAt, &postView.IndexedAt,
 		&postView.UpvoteCount, &postView.DownvoteCount, &postView.Score, &postView.CommentCount,
+		&postView.TopLevelCommentCount, &postView.LastActivityAt, &postView.ScoreHidingHours,
+		&language,
+		pq.Array(&labels.post), pq.Array(&labels.community), pq.Array(&labels.applied), pq.Array(&labels.removed),
 	)
 	if err != nil {
 		return nil, err
 	}
+	postView.Labels = labels.view()
+	postView.Language = nullStringPtr(language)
 
 	// Build author view
+	hydrateAuthorAvatar(&authorView, authorPDSURL, authorAvatar)
 	postView.Author = &authorView
 
 	// Build community ref
@@ -353,7 +421,7 @@ func (r *postgresPostRepo) scanAuthorPost(rows *sql.Rows) (*posts.PostView, erro
 		communityRef.Handle = communityHandle.String
 	}
 	// Hydrate avatar CID to URL using image proxy config (avatar_small preset for post views)
-	if avatarURL := blobs.HydrateImageURL(communities.GetImageProxyConfig(), communityPDSURL.String, communityRef.DID, communityAvatar.String, "avatar_small"); avatarURL != "" {
+	if avatarURL := blobs.HydrateAvatarURL(communities.GetImageProxyConfig(), communityPDSURL.String, communityRef.DID, communityAvatar.String, "avatar_small"); avatarURL != "" {
 		communityRef.Avatar = &avatarURL
 	}
 	if communityPDSURL.Valid {
@@ -394,10 +462,12 @@ func (r *postgresPostRepo) scanAuthorPost(rows *sql.Rows) (*posts.PostView, erro
 
 	// Build stats
 	postView.Stats = &posts.PostStats{
-		Upvotes:      postView.UpvoteCount,
-		Downvotes:    postView.DownvoteCount,
-		Score:        postView.Score,
-		CommentCount: postView.CommentCount,
+		Upvotes:              postView.UpvoteCount,
+		Downvotes:            postView.DownvoteCount,
+		Score:                postView.Score,
+		CommentCount:         postView.CommentCount,
+		TopLevelCommentCount: postView.TopLevelCommentCount,
+		LastActivityAt:       postView.LastActivityAt,
 	}
 
 	// Build the record (required by lexicon)
@@ -435,6 +505,10 @@ func (r *postgresPostRepo) scanAuthorPost(rows *sql.Rows) (*posts.PostView, erro
 		}
 	}
 
+	if len(tags) > 0 {
+		record["tags"] = tags
+	}
+
 	postView.Record = record
 
 	return &postView, nil
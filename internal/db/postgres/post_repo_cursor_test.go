@@ -4,19 +4,26 @@ import (
 	"context"
 	"database/sql"
 	"encoding/base64"
+	"errors"
 	"testing"
 	"time"
 
 	"Coves/internal/core/posts"
+	"Coves/internal/pagination"
 )
 
-func TestParseAuthorPostsCursor(t *testing.T) {
-	repo := &postgresPostRepo{db: nil} // db not needed for cursor parsing
-
-	// Helper to create a valid cursor
-	makeCursor := func(timestamp, uri string) string {
-		return base64.URLEncoding.EncodeToString([]byte(timestamp + "|" + uri))
+// newCursorTestPostRepo returns a post repository without a database, for cursor tests
+func newCursorTestPostRepo(t *testing.T) (*postgresPostRepo, *pagination.Signer) {
+	t.Helper()
+	signer, err := pagination.NewSigner("secret", "")
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
 	}
+	return &postgresPostRepo{cursors: signer}, signer
+}
+
+func TestParseAuthorPostsCursor(t *testing.T) {
+	repo, signer := newCursorTestPostRepo(t)
 
 	validTimestamp := time.Now().Format(time.RFC3339Nano)
 	validURI := "at://did:plc:test123/social.coves.community.post/abc123"
@@ -42,41 +49,54 @@ func TestParseAuthorPostsCursor(t *testing.T) {
 		},
 		{
 			name:       "valid cursor",
-			cursor:     strPtr(makeCursor(validTimestamp, validURI)),
+			cursor:     strPtr(signer.Encode(validTimestamp, validURI)),
 			wantFilter: true,
 			wantErr:    false,
 		},
 		{
 			name:       "cursor too long",
-			cursor:     strPtr(makeCursor(validTimestamp, string(make([]byte, 600)))),
+			cursor:     strPtr(signer.Encode(validTimestamp, string(make([]byte, 1200)))),
 			wantFilter: false,
 			wantErr:    true,
 			errMsg:     "exceeds maximum length",
 		},
 		{
-			name:       "invalid base64",
-			cursor:     strPtr("not-valid-base64!!!"),
+			name:       "unsigned base64 cursor",
+			cursor:     strPtr(base64.URLEncoding.EncodeToString([]byte(validTimestamp + "|" + validURI))),
+			wantFilter: false,
+			wantErr:    true,
+			errMsg:     "invalid",
+		},
+		{
+			name: "signed with another secret",
+			cursor: func() *string {
+				other, err := pagination.NewSigner("other-secret", "")
+				if err != nil {
+					t.Fatalf("NewSigner failed: %v", err)
+				}
+				return strPtr(other.Encode(validTimestamp, validURI))
+			}(),
 			wantFilter: false,
 			wantErr:    true,
-			errMsg:     "invalid base64",
+			errMsg:     "invalid signature",
 		},
 		{
-			name:       "missing pipe delimiter",
-			cursor:     strPtr(base64.URLEncoding.EncodeToString([]byte("no-pipe-here"))),
+			name:       "wrong field count",
+			cursor:     strPtr(signer.Encode(validURI)),
 			wantFilter: false,
 			wantErr:    true,
 			errMsg:     "malformed cursor format",
 		},
 		{
 			name:       "invalid timestamp",
-			cursor:     strPtr(base64.URLEncoding.EncodeToString([]byte("not-a-timestamp|" + validURI))),
+			cursor:     strPtr(signer.Encode("not-a-timestamp", validURI)),
 			wantFilter: false,
 			wantErr:    true,
 			errMsg:     "invalid timestamp",
 		},
 		{
 			name:       "invalid URI format",
-			cursor:     strPtr(base64.URLEncoding.EncodeToString([]byte(validTimestamp + "|not-an-at-uri"))),
+			cursor:     strPtr(signer.Encode(validTimestamp, "not-an-at-uri")),
 			wantFilter: false,
 			wantErr:    true,
 			errMsg:     "invalid URI format",
@@ -89,17 +109,16 @@ func TestParseAuthorPostsCursor(t *testing.T) {
 
 			if tt.wantErr {
 				if err == nil {
-					t.Errorf("parseAuthorPostsCursor() = nil error, want error containing %q", tt.errMsg)
-				} else if !posts.IsValidationError(err) && err != posts.ErrInvalidCursor {
-					// Check if error wraps ErrInvalidCursor
-					if tt.errMsg != "" && !containsStr(err.Error(), tt.errMsg) {
-						t.Errorf("parseAuthorPostsCursor() error = %v, want error containing %q", err, tt.errMsg)
-					}
+					t.Fatalf("parseAuthorPostsCursor() = nil error, want error containing %q", tt.errMsg)
 				}
-			} else {
-				if err != nil {
-					t.Errorf("parseAuthorPostsCursor() = %v, want nil error", err)
+				if !errors.Is(err, posts.ErrInvalidCursor) {
+					t.Errorf("parseAuthorPostsCursor() error = %v, want it to wrap ErrInvalidCursor", err)
+				}
+				if !containsStr(err.Error(), tt.errMsg) {
+					t.Errorf("parseAuthorPostsCursor() error = %v, want error containing %q", err, tt.errMsg)
 				}
+			} else if err != nil {
+				t.Errorf("parseAuthorPostsCursor() = %v, want nil error", err)
 			}
 
 			if tt.wantFilter {
@@ -119,7 +138,7 @@ func TestParseAuthorPostsCursor(t *testing.T) {
 }
 
 func TestBuildAuthorPostsCursor(t *testing.T) {
-	repo := &postgresPostRepo{db: nil}
+	repo, signer := newCursorTestPostRepo(t)
 
 	now := time.Now()
 	post := &posts.PostView{
@@ -129,27 +148,21 @@ func TestBuildAuthorPostsCursor(t *testing.T) {
 
 	cursor := repo.buildAuthorPostsCursor(post)
 
-	// Decode and verify cursor
-	decoded, err := base64.URLEncoding.DecodeString(cursor)
+	// The cursor is sealed, so its fields can't be read or edited without the secret
+	if containsStr(cursor, "did:plc:test123") {
+		t.Errorf("Cursor should not expose the URI, got %q", cursor)
+	}
+	fields, err := signer.Decode(cursor)
 	if err != nil {
 		t.Fatalf("Failed to decode cursor: %v", err)
 	}
-
-	// Should contain timestamp|uri
-	decodedStr := string(decoded)
-	if !containsStr(decodedStr, "|") {
-		t.Errorf("Cursor should contain '|' delimiter, got %q", decodedStr)
-	}
-	if !containsStr(decodedStr, post.URI) {
-		t.Errorf("Cursor should contain URI, got %q", decodedStr)
-	}
-	if !containsStr(decodedStr, now.Format(time.RFC3339Nano)) {
-		t.Errorf("Cursor should contain timestamp, got %q", decodedStr)
+	if len(fields) != 2 || fields[0] != now.Format(time.RFC3339Nano) || fields[1] != post.URI {
+		t.Errorf("Cursor fields = %q, want [created_at, uri]", fields)
 	}
 }
 
 func TestBuildAndParseCursorRoundTrip(t *testing.T) {
-	repo := &postgresPostRepo{db: nil}
+	repo, _ := newCursorTestPostRepo(t)
 
 	now := time.Now()
 	post := &posts.PostView{
@@ -231,10 +244,18 @@ func (m *mockPostRepository) GetByAuthor(ctx context.Context, req posts.GetAutho
 	return nil, nil, nil
 }
 
+func (m *mockPostRepository) GetViewByURI(ctx context.Context, uri, viewerDID string) (*posts.PostView, error) {
+	return nil, posts.ErrNotFound
+}
+
 func (m *mockPostRepository) SoftDelete(ctx context.Context, uri string) error {
 	return nil
 }
 
+func (m *mockPostRepository) FindDuplicates(ctx context.Context, query posts.DuplicateQuery) ([]*posts.DuplicateMatch, error) {
+	return nil, nil
+}
+
 func (m *mockPostRepository) Update(ctx context.Context, post *posts.Post) error {
 	return nil
 }
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"Coves/internal/core/posts"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// BackfillLinkHashes hashes the links of posts indexed before normalized_url_hash existed
+// Walks link posts without a hash in id order, batchSize at a time; posts whose link can't be
+// canonicalized are skipped. Returns how many posts were hashed.
+func (r *postgresPostRepo) BackfillLinkHashes(ctx context.Context, batchSize int) (int, error) {
+	hashed := 0
+	var lastID int64
+	for {
+		rows, err := r.db.QueryContext(ctx, `
+			SELECT id, embed
+			FROM posts
+			WHERE id > $1
+				AND normalized_url_hash IS NULL
+				AND embed->>'$type' = $2
+			ORDER BY id
+			LIMIT $3
+		`, lastID, posts.ExternalEmbedType, batchSize)
+		if err != nil {
+			return hashed, fmt.Errorf("failed to query posts to hash: %w", err)
+		}
+
+		hashes := make(map[int64]string)
+		count := 0
+		for rows.Next() {
+			var id int64
+			var embedJSON []byte
+			if err := rows.Scan(&id, &embedJSON); err != nil {
+				_ = rows.Close()
+				return hashed, fmt.Errorf("failed to scan post: %w", err)
+			}
+			count++
+			lastID = id
+
+			var embed map[string]interface{}
+			if err := json.Unmarshal(embedJSON, &embed); err != nil {
+				continue
+			}
+			if hash, err := posts.LinkHash(posts.ExternalEmbedURI(embed)); err == nil {
+				hashes[id] = hash
+			}
+		}
+		if err := rows.Close(); err != nil {
+			log.Printf("Failed to close rows: %v", err)
+		}
+		if err := rows.Err(); err != nil {
+			return hashed, fmt.Errorf("error iterating posts to hash: %w", err)
+		}
+
+		for id, hash := range hashes {
+			if _, err := r.db.ExecContext(ctx, `UPDATE posts SET normalized_url_hash = $2 WHERE id = $1`, id, hash); err != nil {
+				return hashed, fmt.Errorf("failed to store link hash: %w", err)
+			}
+			hashed++
+		}
+
+		if count < batchSize {
+			return hashed, nil
+		}
+	}
+}
+
+// FindDuplicates returns the community's live posts with query.LinkHash, or a title within
+// query.MinSimilarity of query.Title, link matches first
+// The % prefilter lets the title trigram index find candidates; it uses pg_trgm's
+// similarity_threshold, which MinSimilarity is expected to be above.
+func (r *postgresPostRepo) FindDuplicates(ctx context.Context, query posts.DuplicateQuery) ([]*posts.DuplicateMatch, error) {
+	sqlQuery := fmt.Sprintf(`
+		SELECT uri, title, score, created_at, url_match, title_similarity
+		FROM (
+			SELECT p.uri, p.title, p.score, p.created_at,
+				COALESCE($2::text <> '' AND p.normalized_url_hash = $2, FALSE) AS url_match,
+				CASE WHEN $3::text <> '' AND p.title IS NOT NULL THEN similarity(p.title, $3) ELSE 0 END AS title_similarity
+			FROM posts p
+			WHERE p.community_did = $1
+				AND %s
+				AND %s
+				AND (
+					($2 <> '' AND p.normalized_url_hash = $2)
+					OR ($3 <> '' AND p.title %% $3)
+				)
+		) candidates
+		WHERE url_match OR title_similarity >= $5
+		ORDER BY url_match DESC, title_similarity DESC, created_at DESC
+		LIMIT $6`,
+		notDeleted("p"), visibleTo("p", "author_did", "$4"))
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery,
+		query.CommunityDID, query.LinkHash, query.Title, query.ViewerDID, query.MinSimilarity, query.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query duplicate posts: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	var matches []*posts.DuplicateMatch
+	for rows.Next() {
+		var match posts.DuplicateMatch
+		var title sql.NullString
+		var urlMatch bool
+		var score int
+		var similarity float64
+		if err := rows.Scan(&match.URI, &title, &score, &match.CreatedAt, &urlMatch, &similarity); err != nil {
+			return nil, fmt.Errorf("failed to scan duplicate post: %w", err)
+		}
+		if title.Valid {
+			match.Title = &title.String
+		}
+		match.Score = &score
+		if urlMatch {
+			match.Match = posts.DuplicateMatchURL
+		} else {
+			match.Match = posts.DuplicateMatchTitle
+			match.Similarity = similarity
+		}
+		matches = append(matches, &match)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating duplicate posts: %w", err)
+	}
+	return matches, nil
+}
@@ -0,0 +1,27 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Postgres error codes repositories map to domain errors
+const (
+	pqUniqueViolation     pq.ErrorCode = "23505"
+	pqForeignKeyViolation pq.ErrorCode = "23503"
+	pqCheckViolation      pq.ErrorCode = "23514"
+	pqUndefinedTable      pq.ErrorCode = "42P01"
+)
+
+// constraintViolation reports whether err is a Postgres error with the given code, returning the
+// violated constraint's name (for unique indexes, the index name; empty for errors that aren't
+// constraint violations)
+// Repositories use it instead of matching error strings, which change with the server's locale.
+func constraintViolation(err error, code pq.ErrorCode) (string, bool) {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != code {
+		return "", false
+	}
+	return pqErr.Constraint, true
+}
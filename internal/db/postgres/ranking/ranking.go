@@ -0,0 +1,101 @@
+// Package ranking holds the SQL that orders posts in feeds
+// The community, timeline and discover repositories all rank with these expressions, so a
+// post sits in the same relative position whichever feed it appears in.
+package ranking
+
+import (
+	"Coves/internal/core/hotrank"
+	"fmt"
+	"sync/atomic"
+)
+
+// Sort types shared by every post feed
+const (
+	SortHot = "hot"
+	SortTop = "top"
+	SortNew = "new"
+)
+
+// HotScoreColumn is the precomputed hot rank of posts aliased as p (see hotrank.Score)
+const HotScoreColumn = "p.hot_score"
+
+// liveHotRank switches hot feeds back to ranking with the SQL formula on every request
+// Kept while posts.hot_score rolls out so the two orders can be compared.
+var liveHotRank atomic.Bool
+
+// SetLiveHotRank ranks hot feeds with the live SQL formula (true) or posts.hot_score (false)
+func SetLiveHotRank(enabled bool) {
+	liveHotRank.Store(enabled)
+}
+
+// LiveHotRank reports whether hot feeds rank with the live SQL formula
+func LiveHotRank() bool {
+	return liveHotRank.Load()
+}
+
+// HotRank returns the live hot rank of posts aliased as alias, aged relative to at
+// at is a SQL timestamp expression: NOW() for live queries, or a cursor's bound parameter
+// so a page boundary is ranked against a fixed clock.
+// Uses (score + 1) so new posts with 0 votes still get a positive rank (otherwise
+// 0/time_decay = 0 and they sink to the bottom)
+// The rank is float8 so it survives a round trip through a cursor exactly: the same row
+// ranked against the same clock compares equal to the value the previous page returned.
+func HotRank(alias, at string) string {
+	return HotRankOf(alias+".score", alias+".created_at", at)
+}
+
+// HotRankOf returns the hot rank of a post with the given score and created_at, aged relative
+// to at; all three are SQL expressions. Ranks a cursor's post with exactly the arithmetic
+// HotRank ranks rows with, so the post's own row compares equal to it.
+// Posts older than hotrank.Window rank 0 and posts dated in the future rank as brand new.
+// NOTE: Keep in sync with hotrank.Score, which computes posts.hot_score
+func HotRankOf(score, createdAt, at string) string {
+	return fmt.Sprintf(`(CASE WHEN %[2]s < %[3]s - INTERVAL '%[4]d seconds' THEN 0::float8 `+
+		`ELSE (%[1]s + 1)::float8 / POWER(GREATEST(EXTRACT(EPOCH FROM (%[3]s - %[2]s))::float8/3600, 0) + 2, 1.5::float8) END)`,
+		score, createdAt, at, int64(hotrank.Window.Seconds()))
+}
+
+// HotRankExpression returns the hot rank of posts aliased as p: posts.hot_score, or the
+// live formula against NOW() while SetLiveHotRank is on
+func HotRankExpression() string {
+	if LiveHotRank() {
+		return HotRank("p", "NOW()")
+	}
+	return HotScoreColumn
+}
+
+// HotSortClause returns the hot ORDER BY clause for posts aliased as p, ranked by hotRank
+// (HotScoreColumn or a HotRank expression)
+func HotSortClause(hotRank string) string {
+	return hotRank + ` DESC, p.created_at DESC, p.uri DESC`
+}
+
+// SortClauses maps sort types to ORDER BY clauses for posts aliased as p
+// This whitelist prevents SQL injection via dynamic ORDER BY construction.
+// Every clause ends in p.uri so the order is total and keyset cursors are stable.
+func SortClauses() map[string]string {
+	return map[string]string{
+		SortHot: HotSortClause(HotRankExpression()),
+		SortTop: `p.score DESC, p.created_at DESC, p.uri DESC`,
+		SortNew: `p.created_at DESC, p.uri DESC`,
+	}
+}
+
+// timeframeIntervals maps top-sort timeframes to Postgres intervals; "all" has no bound
+var timeframeIntervals = map[string]string{
+	"hour":  "1 hour",
+	"day":   "1 day",
+	"week":  "1 week",
+	"month": "1 month",
+	"year":  "1 year",
+}
+
+// TimeFilter returns the condition limiting posts aliased as alias to timeframe
+// Returns "" for "all", empty and unknown timeframes (services validate timeframes first).
+func TimeFilter(alias, timeframe string) string {
+	interval, ok := timeframeIntervals[timeframe]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("AND %s.created_at > NOW() - INTERVAL '%s'", alias, interval)
+}
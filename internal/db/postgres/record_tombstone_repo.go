@@ -0,0 +1,70 @@
+package postgres
+
+import (
+	"Coves/internal/core/replay"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type postgresRecordTombstoneRepo struct {
+	db *sql.DB
+}
+
+// NewRecordTombstoneRepository creates a new PostgreSQL repository for the revs of deleted records
+func NewRecordTombstoneRepository(db *sql.DB) replay.Tombstones {
+	return &postgresRecordTombstoneRepo{db: db}
+}
+
+// revIsNewer returns the condition that a write with rev incoming may replace one with rev
+// stored; both are SQL expressions that may be NULL
+// The SQL counterpart of utils.IsStaleRev: revs are TIDs, whose base32 alphabet sorts in
+// byte order, so comparing them in the C collation orders them by time. A missing rev on
+// either side can't be ordered and never blocks the write.
+func revIsNewer(incoming, stored string) string {
+	return fmt.Sprintf(`(%[1]s IS NULL OR %[2]s IS NULL OR %[1]s COLLATE "C" > %[2]s COLLATE "C")`, incoming, stored)
+}
+
+// Record stores the rev that deleted the record at uri, keeping the newest
+func (r *postgresRecordTombstoneRepo) Record(ctx context.Context, uri, rev string) error {
+	if rev == "" {
+		return nil
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO record_tombstones (uri, rev)
+		VALUES ($1, $2)
+		ON CONFLICT (uri) DO UPDATE SET rev = EXCLUDED.rev, deleted_at = NOW()
+		WHERE `+revIsNewer("EXCLUDED.rev", "record_tombstones.rev"),
+		uri, rev)
+	if err != nil {
+		return fmt.Errorf("failed to record tombstone for %s: %w", uri, err)
+	}
+	return nil
+}
+
+// Rev returns the rev that deleted the record at uri, or "" if it has no tombstone
+func (r *postgresRecordTombstoneRepo) Rev(ctx context.Context, uri string) (string, error) {
+	var rev string
+	err := r.db.QueryRowContext(ctx, `SELECT rev FROM record_tombstones WHERE uri = $1`, uri).Scan(&rev)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get tombstone for %s: %w", uri, err)
+	}
+	return rev, nil
+}
+
+// Prune removes tombstones recorded before the cutoff
+func (r *postgresRecordTombstoneRepo) Prune(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM record_tombstones WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune record tombstones: %w", err)
+	}
+	pruned, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return pruned, nil
+}
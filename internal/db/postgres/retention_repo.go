@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"Coves/internal/core/retention"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type postgresRetentionRepo struct {
+	db             *sql.DB
+	instanceDID    string
+	instancePDSURL string
+}
+
+// NewRetentionRepository creates a PostgreSQL repository for remote content retention
+// Communities hosted by instanceDID are never pruned. The instance's users are everyone who
+// has signed in here (an OAuth session) or whose account lives on instancePDSURL.
+func NewRetentionRepository(db *sql.DB, instanceDID, instancePDSURL string) retention.Repository {
+	return &postgresRetentionRepo{db: db, instanceDID: instanceDID, instancePDSURL: instancePDSURL}
+}
+
+// pruneRemotePostsQuery deletes one batch of prunable posts in a single statement
+// doomed picks the batch; the data-modifying CTEs below it all run to completion, so a
+// thread's comments, votes and revisions go with its post or not at all. Soft-deleted rows
+// are deleted too: they are the same stale remote content. Thread deletes use the
+// unfiltered idx_comments_root_all and idx_votes_subject_all (migration 069).
+//
+// Parameters: $1 cutoff, $2/$3 the (created_at, uri) key to start after, $4 limit,
+// $5 instance DID, $6 instance PDS URL
+const pruneRemotePostsQuery = `
+	WITH local_users AS (
+		SELECT s.did FROM oauth_sessions s
+		UNION
+		SELECT u.did FROM users u WHERE u.pds_url = $6
+	),
+	doomed AS (
+		SELECT p.uri, p.created_at
+		FROM posts p
+		JOIN communities c ON c.did = p.community_did
+		WHERE p.created_at < $1
+		  AND (p.created_at, p.uri) > ($2, $3)
+		  AND c.hosted_by_did <> $5
+		  AND NOT EXISTS (SELECT 1 FROM local_users lu WHERE lu.did = p.author_did)
+		  AND NOT EXISTS (
+			SELECT 1 FROM comments cm
+			JOIN local_users lu ON lu.did = cm.commenter_did
+			WHERE cm.root_uri = p.uri)
+		  AND NOT EXISTS (
+			SELECT 1 FROM votes v
+			JOIN local_users lu ON lu.did = v.voter_did
+			WHERE v.subject_uri = p.uri)
+		  AND NOT EXISTS (
+			SELECT 1 FROM comments cm
+			JOIN votes v ON v.subject_uri = cm.uri
+			JOIN local_users lu ON lu.did = v.voter_did
+			WHERE cm.root_uri = p.uri)
+		ORDER BY p.created_at, p.uri
+		LIMIT $4
+	),
+	deleted_comments AS (
+		DELETE FROM comments cm
+		USING doomed d
+		WHERE cm.root_uri = d.uri
+		RETURNING cm.uri, pg_column_size(cm.*) AS bytes
+	),
+	deleted_votes AS (
+		DELETE FROM votes v
+		WHERE v.subject_uri IN (SELECT uri FROM doomed UNION ALL SELECT uri FROM deleted_comments)
+		RETURNING pg_column_size(v.*) AS bytes
+	),
+	deleted_revisions AS (
+		DELETE FROM content_revisions r
+		WHERE r.subject_uri IN (SELECT uri FROM doomed UNION ALL SELECT uri FROM deleted_comments)
+		RETURNING pg_column_size(r.*) AS bytes
+	),
+	deleted_posts AS (
+		DELETE FROM posts p
+		USING doomed d
+		WHERE p.uri = d.uri
+		RETURNING p.community_did, p.deleted_at IS NULL AS live, pg_column_size(p.*) AS bytes
+	),
+	recounted AS (
+		UPDATE communities c
+		SET post_count = GREATEST(c.post_count - n.live_posts, 0)
+		FROM (
+			SELECT community_did, COUNT(*) AS live_posts
+			FROM deleted_posts
+			WHERE live
+			GROUP BY community_did
+		) n
+		WHERE c.did = n.community_did
+	)
+	SELECT
+		(SELECT COUNT(*) FROM deleted_posts),
+		(SELECT COUNT(*) FROM deleted_comments),
+		(SELECT COUNT(*) FROM deleted_votes),
+		COALESCE((SELECT SUM(bytes) FROM deleted_posts), 0)
+			+ COALESCE((SELECT SUM(bytes) FROM deleted_comments), 0)
+			+ COALESCE((SELECT SUM(bytes) FROM deleted_votes), 0)
+			+ COALESCE((SELECT SUM(bytes) FROM deleted_revisions), 0),
+		last.created_at,
+		last.uri
+	FROM (SELECT 1) one
+	LEFT JOIN LATERAL (
+		SELECT created_at, uri FROM doomed ORDER BY created_at DESC, uri DESC LIMIT 1
+	) last ON TRUE`
+
+// PruneRemotePosts deletes one batch of remote posts older than cutoff that no local user
+// interacted with, with their comments, votes and revisions, and decrements the communities'
+// post counts for the live posts among them
+func (r *postgresRetentionRepo) PruneRemotePosts(ctx context.Context, cutoff time.Time, after retention.PostKey, limit int) (retention.Batch, error) {
+	var (
+		batch   retention.Batch
+		lastAt  sql.NullTime
+		lastURI sql.NullString
+	)
+	err := r.db.QueryRowContext(ctx, pruneRemotePostsQuery,
+		cutoff, after.CreatedAt, after.URI, limit, r.instanceDID, r.instancePDSURL,
+	).Scan(&batch.Posts, &batch.Comments, &batch.Votes, &batch.Bytes, &lastAt, &lastURI)
+	if err != nil {
+		return retention.Batch{}, fmt.Errorf("failed to prune remote posts: %w", err)
+	}
+	if lastAt.Valid {
+		batch.Last = retention.PostKey{CreatedAt: lastAt.Time, URI: lastURI.String}
+	}
+	return batch, nil
+}
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"Coves/internal/core/notifications"
+	"Coves/internal/core/scheduledposts"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+type postgresScheduledPostRepo struct {
+	db *sql.DB
+}
+
+// NewScheduledPostRepository creates a PostgreSQL repository for scheduled posts
+func NewScheduledPostRepository(db *sql.DB) scheduledposts.Repository {
+	return &postgresScheduledPostRepo{db: db}
+}
+
+const scheduledPostColumns = `
+	id, community_did, created_by_did, payload, publish_at, next_attempt_at, recurrence, status,
+	attempts, COALESCE(last_error, ''), COALESCE(last_published_uri, ''), last_published_at, created_at`
+
+func scanScheduledPost(scanner interface{ Scan(...interface{}) error }) (*scheduledposts.ScheduledPost, error) {
+	post := &scheduledposts.ScheduledPost{}
+	var payload []byte
+	var recurrence, status string
+	var lastPublishedAt sql.NullTime
+	if err := scanner.Scan(
+		&post.ID, &post.CommunityDID, &post.CreatedByDID, &payload, &post.PublishAt, &post.NextAttemptAt,
+		&recurrence, &status, &post.Attempts, &post.LastError, &post.LastPublishedURI, &lastPublishedAt, &post.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(payload, &post.Payload); err != nil {
+		return nil, fmt.Errorf("failed to decode payload of scheduled post %d: %w", post.ID, err)
+	}
+	post.Recurrence = scheduledposts.Recurrence(recurrence)
+	post.Status = scheduledposts.Status(status)
+	if lastPublishedAt.Valid {
+		post.LastPublishedAt = &lastPublishedAt.Time
+	}
+	return post, nil
+}
+
+// scanScheduledPosts reads every row of a scheduled post query
+func scanScheduledPosts(rows *sql.Rows) ([]*scheduledposts.ScheduledPost, error) {
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	list := []*scheduledposts.ScheduledPost{}
+	for rows.Next() {
+		post, err := scanScheduledPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan scheduled post: %w", err)
+		}
+		list = append(list, post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating scheduled posts: %w", err)
+	}
+	return list, nil
+}
+
+// Create inserts a pending scheduled post
+func (r *postgresScheduledPostRepo) Create(ctx context.Context, post *scheduledposts.ScheduledPost) error {
+	payload, err := json.Marshal(post.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode scheduled post payload: %w", err)
+	}
+	err = r.db.QueryRowContext(ctx, `
+		INSERT INTO scheduled_posts (community_did, created_by_did, payload, publish_at, next_attempt_at, recurrence)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at`,
+		post.CommunityDID, post.CreatedByDID, payload, post.PublishAt, post.NextAttemptAt, string(post.Recurrence),
+	).Scan(&post.ID, &post.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert scheduled post: %w", err)
+	}
+	return nil
+}
+
+// Get returns a scheduled post by ID
+func (r *postgresScheduledPostRepo) Get(ctx context.Context, id int64) (*scheduledposts.ScheduledPost, error) {
+	post, err := scanScheduledPost(r.db.QueryRowContext(ctx, `
+		SELECT`+scheduledPostColumns+`
+		FROM scheduled_posts
+		WHERE id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, scheduledposts.ErrScheduledPostNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled post: %w", err)
+	}
+	return post, nil
+}
+
+// ListByCommunity returns the community's pending and failed posts, soonest first
+func (r *postgresScheduledPostRepo) ListByCommunity(ctx context.Context, communityDID string, limit, offset int) ([]*scheduledposts.ScheduledPost, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+scheduledPostColumns+`
+		FROM scheduled_posts
+		WHERE community_did = $1 AND status IN ('pending', 'failed')
+		ORDER BY publish_at, id
+		LIMIT $2 OFFSET $3`, communityDID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scheduled posts: %w", err)
+	}
+	return scanScheduledPosts(rows)
+}
+
+// CountPending returns how many of the community's posts are pending
+func (r *postgresScheduledPostRepo) CountPending(ctx context.Context, communityDID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM scheduled_posts
+		WHERE community_did = $1 AND status = 'pending'`, communityDID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count scheduled posts: %w", err)
+	}
+	return count, nil
+}
+
+// Cancel cancels a pending or failed post
+func (r *postgresScheduledPostRepo) Cancel(ctx context.Context, id int64, actorDID string) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE scheduled_posts
+		SET status = 'cancelled', cancelled_by_did = $2, cancelled_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status IN ('pending', 'failed')`, id, actorDID)
+	if err != nil {
+		return fmt.Errorf("failed to cancel scheduled post: %w", err)
+	}
+	cancelled, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check cancelled scheduled post: %w", err)
+	}
+	if cancelled == 0 {
+		return scheduledposts.ErrScheduledPostNotFound
+	}
+	return nil
+}
+
+// ClaimDue leases due pending posts to this publisher
+// SKIP LOCKED lets publishers on several instances claim disjoint batches.
+func (r *postgresScheduledPostRepo) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]*scheduledposts.ScheduledPost, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH due AS (
+			SELECT id FROM scheduled_posts
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE scheduled_posts s
+			SET next_attempt_at = $2, updated_at = NOW()
+			FROM due
+			WHERE s.id = due.id
+			RETURNING s.*
+		)
+		SELECT`+scheduledPostColumns+`
+		FROM claimed
+		ORDER BY publish_at, id`, now, leaseUntil, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim due scheduled posts: %w", err)
+	}
+	return scanScheduledPosts(rows)
+}
+
+// SaveAttempt stores the outcome of a publication attempt unless the post was cancelled meanwhile
+func (r *postgresScheduledPostRepo) SaveAttempt(ctx context.Context, post *scheduledposts.ScheduledPost) error {
+	var lastError, lastPublishedURI sql.NullString
+	if post.LastError != "" {
+		lastError = sql.NullString{String: post.LastError, Valid: true}
+	}
+	if post.LastPublishedURI != "" {
+		lastPublishedURI = sql.NullString{String: post.LastPublishedURI, Valid: true}
+	}
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE scheduled_posts
+		SET status = $2, publish_at = $3, next_attempt_at = $4, attempts = $5, last_error = $6,
+			last_published_uri = $7, last_published_at = $8, updated_at = NOW()
+		WHERE id = $1 AND status = 'pending'`,
+		post.ID, string(post.Status), post.PublishAt, post.NextAttemptAt, post.Attempts, lastError,
+		lastPublishedURI, post.LastPublishedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save scheduled post attempt: %w", err)
+	}
+	return nil
+}
+
+// NotifyModerators notifies the community's creator and moderators of a failing publication
+// The notification comes from the community itself, about the post it couldn't publish.
+func (r *postgresScheduledPostRepo) NotifyModerators(ctx context.Context, post *scheduledposts.ScheduledPost, postURI string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO notifications (recipient_did, author_did, reason, subject_uri)
+		SELECT recipient, $1, $2, $3
+		FROM (
+			SELECT created_by_did AS recipient FROM communities WHERE did = $1
+			UNION
+			SELECT user_did FROM community_memberships WHERE community_did = $1 AND is_moderator = TRUE
+		) moderators
+		WHERE recipient IS NOT NULL
+		ON CONFLICT (recipient_did, reason, subject_uri) WHERE subject_parent_uri IS NULL DO NOTHING`,
+		post.CommunityDID, notifications.ReasonScheduledPostFailed, postURI)
+	if err != nil {
+		return fmt.Errorf("failed to notify moderators of scheduled post: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package postgres
+
+// Soft-delete convention
+//
+// posts, comments and votes are soft-deleted (deleted_at is set) rather than removed, so
+// Jetstream replays can be told apart from resurrected records. Every read in this package
+// excludes soft-deleted rows with notDeleted. Methods that return them carry a WithDeleted
+// suffix and exist only for callers that need them, such as comment threads rendering
+// "[deleted]" placeholders. Communities, users and aggregators are hard-deleted.
+
+// notDeleted returns the condition that excludes soft-deleted rows
+// alias qualifies the column ("p" gives "p.deleted_at IS NULL"); pass "" for an unqualified column
+func notDeleted(alias string) string {
+	if alias == "" {
+		return "deleted_at IS NULL"
+	}
+	return alias + ".deleted_at IS NULL"
+}
+
+// deletedScope selects whether a shared query implementation returns soft-deleted rows
+type deletedScope bool
+
+const (
+	excludeDeleted deletedScope = false
+	includeDeleted deletedScope = true
+)
+
+// filter returns the WHERE condition for the scope
+func (s deletedScope) filter(alias string) string {
+	if s == includeDeleted {
+		return "TRUE"
+	}
+	return notDeleted(alias)
+}
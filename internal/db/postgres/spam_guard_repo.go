@@ -0,0 +1,181 @@
+package postgres
+
+import (
+	"Coves/internal/core/spamguard"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type postgresSpamGuardRepo struct {
+	db *sql.DB
+}
+
+// NewSpamGuardRepository creates a PostgreSQL repository for the spam guard's rolling post record
+func NewSpamGuardRepository(db *sql.DB) spamguard.Repository {
+	return &postgresSpamGuardRepo{db: db}
+}
+
+// NewSpamQueueRepository creates a PostgreSQL repository for reviewing quarantined posts
+func NewSpamQueueRepository(db *sql.DB) spamguard.QueueRepository {
+	return &postgresSpamGuardRepo{db: db}
+}
+
+// GetAccountCreatedAt returns the account's creation time saved from its identity history
+func (r *postgresSpamGuardRepo) GetAccountCreatedAt(ctx context.Context, did string) (time.Time, bool, error) {
+	var createdAt time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT created_at FROM account_creation_times WHERE did = $1`, did).Scan(&createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to get account creation time: %w", err)
+	}
+	return createdAt, true, nil
+}
+
+// SaveAccountCreatedAt saves an account's creation time from its identity history
+func (r *postgresSpamGuardRepo) SaveAccountCreatedAt(ctx context.Context, did string, createdAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO account_creation_times (did, created_at)
+		VALUES ($1, $2)
+		ON CONFLICT (did) DO UPDATE SET created_at = EXCLUDED.created_at, recorded_at = NOW()`,
+		did, createdAt)
+	if err != nil {
+		return fmt.Errorf("failed to save account creation time: %w", err)
+	}
+	return nil
+}
+
+// GetFirstIndexedAt returns when the user was first indexed by this AppView
+func (r *postgresSpamGuardRepo) GetFirstIndexedAt(ctx context.Context, did string) (time.Time, bool, error) {
+	var indexedAt time.Time
+	err := r.db.QueryRowContext(ctx, `SELECT created_at FROM users WHERE did = $1`, did).Scan(&indexedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("failed to get user index time: %w", err)
+	}
+	return indexedAt, true, nil
+}
+
+// RecordPost stores a post's fingerprint, ignoring replays of the same post
+func (r *postgresSpamGuardRepo) RecordPost(ctx context.Context, uri, authorDID, communityDID, fingerprint string, at time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO spam_fingerprints (post_uri, author_did, community_did, fingerprint, seen_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (post_uri) DO NOTHING`,
+		uri, authorDID, communityDID, fingerprint, at)
+	if err != nil {
+		return fmt.Errorf("failed to record spam fingerprint: %w", err)
+	}
+	return nil
+}
+
+// CountCommunitiesWithFingerprint counts distinct communities the content was posted to between since and until
+func (r *postgresSpamGuardRepo) CountCommunitiesWithFingerprint(ctx context.Context, fingerprint string, since, until time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT community_did)
+		FROM spam_fingerprints
+		WHERE fingerprint = $1 AND seen_at >= $2 AND seen_at <= $3`,
+		fingerprint, since, until).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count repeated content: %w", err)
+	}
+	return count, nil
+}
+
+// CountPostsByAuthor counts the author's recorded posts between since and until
+func (r *postgresSpamGuardRepo) CountPostsByAuthor(ctx context.Context, authorDID string, since, until time.Time) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM spam_fingerprints
+		WHERE author_did = $1 AND seen_at >= $2 AND seen_at <= $3`,
+		authorDID, since, until).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recent posts: %w", err)
+	}
+	return count, nil
+}
+
+// PruneBefore drops fingerprints recorded before the given time
+func (r *postgresSpamGuardRepo) PruneBefore(ctx context.Context, before time.Time) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM spam_fingerprints WHERE seen_at < $1`, before); err != nil {
+		return fmt.Errorf("failed to prune spam fingerprints: %w", err)
+	}
+	return nil
+}
+
+// ListQuarantined returns posts awaiting spam review, most recently indexed first
+func (r *postgresSpamGuardRepo) ListQuarantined(ctx context.Context, limit, offset int) ([]*spamguard.QuarantinedPost, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT uri, cid, author_did, community_did, title, content,
+			COALESCE(quarantine_reasons, '{}'), indexed_at
+		FROM posts
+		WHERE status = 'quarantined' AND deleted_at IS NULL
+		ORDER BY indexed_at DESC, uri
+		LIMIT $1 OFFSET $2`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list quarantined posts: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	quarantined := []*spamguard.QuarantinedPost{}
+	for rows.Next() {
+		var post spamguard.QuarantinedPost
+		var reasons pq.StringArray
+		if err := rows.Scan(&post.URI, &post.CID, &post.AuthorDID, &post.CommunityDID,
+			&post.Title, &post.Content, &reasons, &post.IndexedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan quarantined post: %w", err)
+		}
+		post.Reasons = reasons
+		quarantined = append(quarantined, &post)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating quarantined posts: %w", err)
+	}
+	return quarantined, nil
+}
+
+// Release makes a quarantined post visible
+func (r *postgresSpamGuardRepo) Release(ctx context.Context, uri string) error {
+	return r.review(ctx, `
+		UPDATE posts
+		SET status = 'active', visibility_state = 'visible'
+		WHERE uri = $1 AND status = 'quarantined'`, uri)
+}
+
+// Confirm marks a quarantined post as spam; it stays hidden
+func (r *postgresSpamGuardRepo) Confirm(ctx context.Context, uri string) error {
+	return r.review(ctx, `
+		UPDATE posts
+		SET status = 'spam'
+		WHERE uri = $1 AND status = 'quarantined'`, uri)
+}
+
+func (r *postgresSpamGuardRepo) review(ctx context.Context, query, uri string) error {
+	result, err := r.db.ExecContext(ctx, query, uri)
+	if err != nil {
+		return fmt.Errorf("failed to review quarantined post: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check review result: %w", err)
+	}
+	if rows == 0 {
+		return spamguard.ErrPostNotQuarantined
+	}
+	return nil
+}
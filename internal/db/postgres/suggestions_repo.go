@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/core/suggestions"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type postgresSuggestionsRepo struct {
+	db *sql.DB
+}
+
+// NewSuggestionsRepository creates a new PostgreSQL repository for community suggestions
+func NewSuggestionsRepository(db *sql.DB) suggestions.Repository {
+	return &postgresSuggestionsRepo{db: db}
+}
+
+// suggestableCommunity filters community rows (aliased c) down to ones that may be suggested
+// to the viewer in $1: public, listed, and neither subscribed to nor blocked by the viewer
+const suggestableCommunity = `
+	c.visibility = 'public'
+	AND c.federation_blocked = FALSE
+	AND c.hosted_by_unverified = FALSE
+	AND c.impersonation_flag = FALSE
+	AND c.suspended_at IS NULL
+	AND c.deleted_at IS NULL
+	AND NOT EXISTS (SELECT 1 FROM community_subscriptions s WHERE s.user_did = $1 AND s.community_did = c.did)
+	AND NOT EXISTS (SELECT 1 FROM community_blocks b WHERE b.user_did = $1 AND b.community_did = c.did)`
+
+// suggestionColumns are the community columns needed for a community view
+const suggestionColumns = `
+	c.did, c.handle, c.name, c.display_name, c.avatar_cid, c.pds_url, c.visibility,
+	c.subscriber_count, c.member_count, c.post_count,
+	c.weekly_active_users, c.monthly_active_users, c.federation_blocked`
+
+// FilterIndexedUsers returns the DIDs that belong to users indexed on this instance
+func (r *postgresSuggestionsRepo) FilterIndexedUsers(ctx context.Context, dids []string) ([]string, error) {
+	if len(dids) == 0 {
+		return []string{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `SELECT did FROM users WHERE did = ANY($1)`, pq.Array(dids))
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter indexed users: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	indexed := []string{}
+	for rows.Next() {
+		var did string
+		if err := rows.Scan(&did); err != nil {
+			return nil, fmt.Errorf("failed to scan user DID: %w", err)
+		}
+		indexed = append(indexed, did)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating indexed users: %w", err)
+	}
+	return indexed, nil
+}
+
+// RankByFollowedUsers counts distinct followed users per community across subscriptions and
+// recent activity in one query, then joins the top communities
+// Ties go to the community with more weekly actives, then subscribers.
+func (r *postgresSuggestionsRepo) RankByFollowedUsers(ctx context.Context, viewerDID string, userDIDs []string, since time.Time, limit int) ([]*suggestions.Suggestion, error) {
+	if len(userDIDs) == 0 {
+		return []*suggestions.Suggestion{}, nil
+	}
+
+	query := `
+		WITH followed AS (
+			SELECT community_did, user_did
+			FROM community_subscriptions
+			WHERE user_did = ANY($2)
+			UNION
+			SELECT community_did, user_did
+			FROM community_activity
+			WHERE user_did = ANY($2) AND day >= $3::date
+		),
+		counts AS (
+			SELECT community_did, COUNT(DISTINCT user_did) AS followed_users
+			FROM followed
+			GROUP BY community_did
+		)
+		SELECT ` + suggestionColumns + `, f.followed_users
+		FROM counts f
+		INNER JOIN communities c ON c.did = f.community_did
+		WHERE ` + suggestableCommunity + `
+		ORDER BY f.followed_users DESC, c.weekly_active_users DESC, c.subscriber_count DESC, c.did
+		LIMIT $4`
+
+	rows, err := r.db.QueryContext(ctx, query, viewerDID, pq.Array(userDIDs), activityDay(since), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to rank communities by followed users: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	result := []*suggestions.Suggestion{}
+	for rows.Next() {
+		suggestion := &suggestions.Suggestion{}
+		community, err := scanSuggestedCommunity(rows, &suggestion.FollowedUsers)
+		if err != nil {
+			return nil, err
+		}
+		suggestion.Community = community
+		result = append(result, suggestion)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating ranked communities: %w", err)
+	}
+	return result, nil
+}
+
+// ListTrending returns communities ranked like the "active" community list sort
+func (r *postgresSuggestionsRepo) ListTrending(ctx context.Context, viewerDID string, limit int) ([]*communities.Community, error) {
+	query := `
+		SELECT ` + suggestionColumns + `
+		FROM communities c
+		WHERE ` + suggestableCommunity + `
+		ORDER BY c.weekly_active_users DESC, c.monthly_active_users DESC, c.post_count DESC, c.did
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, viewerDID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trending communities: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	result := []*communities.Community{}
+	for rows.Next() {
+		community, err := scanSuggestedCommunity(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, community)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trending communities: %w", err)
+	}
+	return result, nil
+}
+
+// GetExcludedCommunityDIDs reports which of communityDIDs the viewer subscribes to or has blocked
+func (r *postgresSuggestionsRepo) GetExcludedCommunityDIDs(ctx context.Context, viewerDID string, communityDIDs []string) (map[string]bool, error) {
+	if len(communityDIDs) == 0 {
+		return map[string]bool{}, nil
+	}
+
+	query := `
+		SELECT community_did FROM community_subscriptions WHERE user_did = $1 AND community_did = ANY($2)
+		UNION
+		SELECT community_did FROM community_blocks WHERE user_did = $1 AND community_did = ANY($2)`
+
+	rows, err := r.db.QueryContext(ctx, query, viewerDID, pq.Array(communityDIDs))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscribed and blocked communities: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			log.Printf("Failed to close rows: %v", closeErr)
+		}
+	}()
+
+	excluded := make(map[string]bool)
+	for rows.Next() {
+		var did string
+		if err := rows.Scan(&did); err != nil {
+			return nil, fmt.Errorf("failed to scan community DID: %w", err)
+		}
+		excluded[did] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating subscribed and blocked communities: %w", err)
+	}
+	return excluded, nil
+}
+
+// scanSuggestedCommunity scans suggestionColumns, followed by any extra destinations
+func scanSuggestedCommunity(rows *sql.Rows, extra ...interface{}) (*communities.Community, error) {
+	community := &communities.Community{}
+	var displayName, avatarCID, pdsURL sql.NullString
+	dest := []interface{}{
+		&community.DID, &community.Handle, &community.Name, &displayName, &avatarCID, &pdsURL,
+		&community.Visibility,
+		&community.SubscriberCount, &community.MemberCount, &community.PostCount,
+		&community.WeeklyActiveUsers, &community.MonthlyActiveUsers, &community.FederationBlocked,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return nil, fmt.Errorf("failed to scan suggested community: %w", err)
+	}
+	community.DisplayName = displayName.String
+	community.AvatarCID = avatarCID.String
+	community.PDSURL = pdsURL.String
+	return community, nil
+}
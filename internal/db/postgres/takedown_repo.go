@@ -0,0 +1,184 @@
+package postgres
+
+import (
+	"Coves/internal/atproto/utils"
+	"Coves/internal/core/takedown"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+)
+
+type postgresTakedownRepo struct {
+	db *sql.DB
+}
+
+// NewTakedownRepository creates a PostgreSQL repository for admin record takedowns
+func NewTakedownRepository(db *sql.DB) takedown.Repository {
+	return &postgresTakedownRepo{db: db}
+}
+
+// takedownTable returns the table a takedown subject is indexed in
+func takedownTable(subjectURI string) (string, error) {
+	switch utils.ExtractCollectionFromURI(subjectURI) {
+	case "social.coves.community.post":
+		return "posts", nil
+	case "social.coves.community.comment":
+		return "comments", nil
+	}
+	return "", takedown.ErrSubjectNotFound
+}
+
+const takedownColumns = `
+	id, subject_uri, reason, note, upstream_deleted, created_by_did, created_at,
+	COALESCE(reversed_by_did, ''), reversed_at`
+
+func scanTakedown(scanner interface{ Scan(...interface{}) error }) (*takedown.Takedown, error) {
+	t := &takedown.Takedown{}
+	var reason string
+	var reversedAt sql.NullTime
+	if err := scanner.Scan(
+		&t.ID, &t.SubjectURI, &reason, &t.Note, &t.UpstreamDeleted, &t.CreatedBy, &t.CreatedAt,
+		&t.ReversedBy, &reversedAt,
+	); err != nil {
+		return nil, err
+	}
+	t.Reason = takedown.Reason(reason)
+	if reversedAt.Valid {
+		t.ReversedAt = &reversedAt.Time
+	}
+	return t, nil
+}
+
+// GetActive returns the record's active takedown
+func (r *postgresTakedownRepo) GetActive(ctx context.Context, subjectURI string) (*takedown.Takedown, error) {
+	t, err := scanTakedown(r.db.QueryRowContext(ctx, `
+		SELECT`+takedownColumns+`
+		FROM record_takedowns
+		WHERE subject_uri = $1 AND reversed_at IS NULL`, subjectURI))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, takedown.ErrTakedownNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get takedown: %w", err)
+	}
+	return t, nil
+}
+
+// Takedown hides the post or comment and records the takedown in one transaction
+// Soft-deleted records can be taken down too, so they stay hidden if they're resurrected.
+func (r *postgresTakedownRepo) Takedown(ctx context.Context, t *takedown.Takedown) (bool, error) {
+	table, err := takedownTable(t.SubjectURI)
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	var alreadyTakenDown bool
+	err = tx.QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT taken_down_at IS NOT NULL FROM %s WHERE uri = $1 FOR UPDATE`, table),
+		t.SubjectURI).Scan(&alreadyTakenDown)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, takedown.ErrSubjectNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to lock takedown subject: %w", err)
+	}
+
+	if alreadyTakenDown {
+		active, err := scanTakedown(tx.QueryRowContext(ctx, `
+			SELECT`+takedownColumns+`
+			FROM record_takedowns
+			WHERE subject_uri = $1 AND reversed_at IS NULL`, t.SubjectURI))
+		if err != nil {
+			return false, fmt.Errorf("failed to get active takedown: %w", err)
+		}
+		*t = *active
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET taken_down_at = NOW() WHERE uri = $1`, table), t.SubjectURI); err != nil {
+		return false, fmt.Errorf("failed to take down %s: %w", table, err)
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO record_takedowns (subject_uri, reason, note, created_by_did)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at`,
+		t.SubjectURI, string(t.Reason), t.Note, t.CreatedBy,
+	).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to record takedown: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// MarkUpstreamDeleted records that the takedown's subject was deleted from its PDS
+func (r *postgresTakedownRepo) MarkUpstreamDeleted(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE record_takedowns SET upstream_deleted = TRUE WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to mark takedown upstream deleted: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check update result: %w", err)
+	}
+	if rows == 0 {
+		return takedown.ErrTakedownNotFound
+	}
+	return nil
+}
+
+// Reverse completes the record's active takedown and unhides the record in one transaction
+func (r *postgresTakedownRepo) Reverse(ctx context.Context, subjectURI, actorDID string) (*takedown.Takedown, error) {
+	table, err := takedownTable(subjectURI)
+	if err != nil {
+		return nil, takedown.ErrTakedownNotFound
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	t, err := scanTakedown(tx.QueryRowContext(ctx, `
+		UPDATE record_takedowns
+		SET reversed_by_did = $2, reversed_at = NOW()
+		WHERE subject_uri = $1 AND reversed_at IS NULL
+		RETURNING`+takedownColumns, subjectURI, actorDID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, takedown.ErrTakedownNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reverse takedown: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE %s SET taken_down_at = NULL WHERE uri = $1`, table), subjectURI); err != nil {
+		return nil, fmt.Errorf("failed to restore %s: %w", table, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return t, nil
+}
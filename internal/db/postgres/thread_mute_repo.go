@@ -0,0 +1,51 @@
+package postgres
+
+import (
+	"Coves/internal/core/notifications"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type postgresThreadMuteRepo struct {
+	db *sql.DB
+}
+
+// NewThreadMuteRepository creates a PostgreSQL repository for thread mutes
+func NewThreadMuteRepository(db *sql.DB) notifications.ThreadMuteRepository {
+	return &postgresThreadMuteRepo{db: db}
+}
+
+// MuteThread records the mute, keeping the original created_at when already muted
+func (r *postgresThreadMuteRepo) MuteThread(ctx context.Context, userDID, rootURI string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO thread_mutes (user_did, root_uri)
+		VALUES ($1, $2)
+		ON CONFLICT (user_did, root_uri) DO NOTHING`,
+		userDID, rootURI)
+	if err != nil {
+		return fmt.Errorf("failed to mute thread %s: %w", rootURI, err)
+	}
+	return nil
+}
+
+// UnmuteThread removes the mute if there is one
+func (r *postgresThreadMuteRepo) UnmuteThread(ctx context.Context, userDID, rootURI string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM thread_mutes WHERE user_did = $1 AND root_uri = $2`, userDID, rootURI)
+	if err != nil {
+		return fmt.Errorf("failed to unmute thread %s: %w", rootURI, err)
+	}
+	return nil
+}
+
+// IsThreadMuted reports whether the user muted the thread
+func (r *postgresThreadMuteRepo) IsThreadMuted(ctx context.Context, userDID, rootURI string) (bool, error) {
+	var muted bool
+	err := r.db.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM thread_mutes WHERE user_did = $1 AND root_uri = $2)`,
+		userDID, rootURI).Scan(&muted)
+	if err != nil {
+		return false, fmt.Errorf("failed to check thread mute on %s: %w", rootURI, err)
+	}
+	return muted, nil
+}
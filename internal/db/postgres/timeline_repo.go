@@ -2,6 +2,8 @@ package postgres
 
 import (
 	"Coves/internal/core/timeline"
+	"Coves/internal/db/postgres/ranking"
+	"Coves/internal/pagination"
 	"context"
 	"database/sql"
 	"fmt"
@@ -12,70 +14,23 @@ type postgresTimelineRepo struct {
 	*feedRepoBase
 }
 
-// sortClauses maps sort types to safe SQL ORDER BY clauses
-// This whitelist prevents SQL injection via dynamic ORDER BY construction
-// Note: Hot ranking uses (score + 1) to ensure new posts with 0 votes still appear
-var timelineSortClauses = map[string]string{
-	"hot": `((p.score + 1) / POWER(EXTRACT(EPOCH FROM (NOW() - p.created_at))/3600 + 2, 1.5)) DESC, p.created_at DESC, p.uri DESC`,
-	"top": `p.score DESC, p.created_at DESC, p.uri DESC`,
-	"new": `p.created_at DESC, p.uri DESC`,
-}
-
-// hotRankExpression is the SQL expression for computing the hot rank
-// NOTE: Uses NOW() which means hot_rank changes over time - this is expected behavior
-// Uses (score + 1) so new posts with 0 votes still get a positive rank
-const timelineHotRankExpression = `((p.score + 1) / POWER(EXTRACT(EPOCH FROM (NOW() - p.created_at))/3600 + 2, 1.5))`
-
 // NewTimelineRepository creates a new PostgreSQL timeline repository
-func NewTimelineRepository(db *sql.DB, cursorSecret string) timeline.Repository {
+func NewTimelineRepository(db *sql.DB, cursors *pagination.Signer) timeline.Repository {
 	return &postgresTimelineRepo{
-		feedRepoBase: newFeedRepoBase(db, timelineHotRankExpression, timelineSortClauses, cursorSecret),
+		feedRepoBase: newFeedRepoBase(db, ranking.SortClauses(), cursors),
 	}
 }
 
 // GetTimeline retrieves posts from all communities the user subscribes to
 // Single query with JOINs for optimal performance
 func (r *postgresTimelineRepo) GetTimeline(ctx context.Context, req timeline.GetTimelineRequest) ([]*timeline.FeedViewPost, *string, error) {
-	// Capture query time for stable cursor generation (used for hot sort pagination)
-	queryTime := time.Now()
-
-	// Build ORDER BY clause based on sort type
-	orderBy, timeFilter := r.buildSortClause(req.Sort, req.Timeframe)
-
-	// Build cursor filter for pagination
-	// Timeline uses $3+ for cursor params (after $1=userDID and $2=limit)
-	cursorFilter, cursorValues, err := r.feedRepoBase.parseCursor(req.Cursor, req.Sort, 3)
+	// Build ordering and keyset filter for the page
+	// Timeline uses $3+ for page params (after $1=userDID and $2=limit)
+	page, err := r.feedRepoBase.buildPage(ctx, req.Cursor, req.Sort, req.Timeframe, 3, time.Now())
 	if err != nil {
 		return nil, nil, timeline.ErrInvalidCursor
 	}
 
-	// Build the main query
-	// For hot sort, we need to compute and return the hot_rank for cursor building
-	var selectClause string
-	if req.Sort == "hot" {
-		selectClause = fmt.Sprintf(`
-		SELECT
-			p.uri, p.cid, p.rkey,
-			p.author_did, u.handle as author_handle,
-			p.community_did, c.handle as community_handle, c.name as community_name, c.avatar_cid as community_avatar, c.pds_url as community_pds_url,
-			p.title, p.content, p.content_facets, p.embed, p.content_labels,
-			p.created_at, p.edited_at, p.indexed_at,
-			p.upvote_count, p.downvote_count, p.score, p.comment_count,
-			%s as hot_rank
-		FROM posts p`, timelineHotRankExpression)
-	} else {
-		selectClause = `
-		SELECT
-			p.uri, p.cid, p.rkey,
-			p.author_did, u.handle as author_handle,
-			p.community_did, c.handle as community_handle, c.name as community_name, c.avatar_cid as community_avatar, c.pds_url as community_pds_url,
-			p.title, p.content, p.content_facets, p.embed, p.content_labels,
-			p.created_at, p.edited_at, p.indexed_at,
-			p.upvote_count, p.downvote_count, p.score, p.comment_count,
-			NULL::numeric as hot_rank
-		FROM posts p`
-	}
-
 	// Join with community_subscriptions to get posts from subscribed communities
 	query := fmt.Sprintf(`
 		%s
@@ -83,16 +38,23 @@ func (r *postgresTimelineRepo) GetTimeline(ctx context.Context, req timeline.Get
 		INNER JOIN communities c ON p.community_did = c.did
 		INNER JOIN community_subscriptions cs ON p.community_did = cs.community_did
 		WHERE cs.user_did = $1
-			AND p.deleted_at IS NULL
+			AND c.deleted_at IS NULL
+			AND %s
+			AND %s
+			AND %s
+			AND %s
+			%s
 			%s
 			%s
 		ORDER BY %s
 		LIMIT $2
-	`, selectClause, timeFilter, cursorFilter, orderBy)
+	`, page.selectClause(), notDeleted("p"), visibleTo("p", "author_did", "$1"), aggregatorPostsAllowedFor("p", "$1"),
+		communityNotHiddenBy("p", "$1"),
+		adultContentHidden(req.IncludeNSFW), page.timeFilter, page.filter, page.orderBy)
 
 	// Prepare query arguments
 	args := []interface{}{req.UserDID, req.Limit + 1} // +1 to check for next page
-	args = append(args, cursorValues...)
+	args = append(args, page.args...)
 
 	// Execute query
 	rows, err := r.db.QueryContext(ctx, query, args...)
@@ -129,7 +91,7 @@ func (r *postgresTimelineRepo) GetTimeline(ctx context.Context, req timeline.Get
 		hotRanks = hotRanks[:req.Limit]
 		lastPost := feedPosts[len(feedPosts)-1].Post
 		lastHotRank := hotRanks[len(hotRanks)-1]
-		cursorStr := r.feedRepoBase.buildCursor(lastPost, req.Sort, lastHotRank, queryTime)
+		cursorStr := r.feedRepoBase.buildCursor(lastPost, page, req.Timeframe, lastHotRank)
 		cursor = &cursorStr
 	}
 
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"Coves/internal/atproto/aturi"
+	"Coves/internal/core/translation"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+type postgresTranslationRepo struct {
+	db *sql.DB
+}
+
+// NewTranslationRepository creates a PostgreSQL repository for translation subjects and the
+// translation cache
+func NewTranslationRepository(db *sql.DB) translation.Repository {
+	return &postgresTranslationRepo{db: db}
+}
+
+// GetSubject returns the current text of a post or comment visible to everyone
+// The language is the record's first langs entry, else the one detected at index time.
+func (r *postgresTranslationRepo) GetSubject(ctx context.Context, uri string) (*translation.Subject, error) {
+	var query string
+	switch aturi.Collection(uri) {
+	case "social.coves.community.post":
+		query = fmt.Sprintf(`
+			SELECT p.uri, p.cid, p.title, COALESCE(p.content, ''), %s
+			FROM posts p
+			WHERE p.uri = $1 AND %s AND %s`,
+			postLanguageColumn, notDeleted("p"), visibleToEveryone("p"))
+	case "social.coves.community.comment":
+		query = fmt.Sprintf(`
+			SELECT c.uri, c.cid, NULL, c.content, COALESCE(c.langs[1], c.detected_lang)
+			FROM comments c
+			WHERE c.uri = $1 AND %s AND %s`,
+			notDeleted("c"), visibleToEveryone("c"))
+	default:
+		return nil, translation.ErrSubjectNotFound
+	}
+
+	var (
+		subject translation.Subject
+		title   sql.NullString
+		lang    sql.NullString
+	)
+	err := r.db.QueryRowContext(ctx, query, uri).Scan(&subject.URI, &subject.CID, &title, &subject.Content, &lang)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, translation.ErrSubjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translation subject %s: %w", uri, err)
+	}
+	subject.Title = nullStringPtr(title)
+	subject.Lang = lang.String
+	return &subject, nil
+}
+
+// GetTranslation returns the cached translation of one version of a subject, or nil
+func (r *postgresTranslationRepo) GetTranslation(ctx context.Context, uri, cid, targetLang string) (*translation.Translation, error) {
+	result := translation.Translation{SubjectURI: uri, SubjectCID: cid, TargetLang: targetLang}
+	var title, sourceLang sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT source_lang, title, content, created_at
+		FROM translations
+		WHERE subject_uri = $1 AND subject_cid = $2 AND target_lang = $3`,
+		uri, cid, targetLang).Scan(&sourceLang, &title, &result.Content, &result.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get translation of %s: %w", uri, err)
+	}
+	result.SourceLang = sourceLang.String
+	result.Title = nullStringPtr(title)
+	return &result, nil
+}
+
+// SaveTranslation caches a translation and drops those of other versions of the subject
+// Concurrent requests for the same version keep whichever was stored first.
+func (r *postgresTranslationRepo) SaveTranslation(ctx context.Context, t *translation.Translation) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM translations WHERE subject_uri = $1 AND subject_cid <> $2`,
+		t.SubjectURI, t.SubjectCID); err != nil {
+		return fmt.Errorf("failed to drop stale translations of %s: %w", t.SubjectURI, err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO translations (subject_uri, subject_cid, target_lang, source_lang, title, content, created_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7)
+		ON CONFLICT (subject_uri, subject_cid, target_lang) DO NOTHING`,
+		t.SubjectURI, t.SubjectCID, t.TargetLang, t.SourceLang, t.Title, t.Content, t.CreatedAt); err != nil {
+		return fmt.Errorf("failed to cache translation of %s: %w", t.SubjectURI, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit translation: %w", err)
+	}
+	return nil
+}
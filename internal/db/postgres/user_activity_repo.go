@@ -0,0 +1,102 @@
+package postgres
+
+import (
+	"Coves/internal/core/users"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type postgresUserActivityRepo struct {
+	db *sql.DB
+}
+
+// NewUserActivityRepository creates a new PostgreSQL user activity repository
+func NewUserActivityRepository(db *sql.DB) users.ActivityRepository {
+	return &postgresUserActivityRepo{db: db}
+}
+
+// CountPostsByDay groups the author's live, visible posts by UTC day
+// Uses idx_posts_author_created
+func (r *postgresUserActivityRepo) CountPostsByDay(ctx context.Context, did string, since time.Time) (map[string]int, error) {
+	query := `
+		SELECT to_char(p.created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, COUNT(*)
+		FROM posts p
+		WHERE p.author_did = $1
+		  AND p.created_at >= $2
+		  AND p.deleted_at IS NULL
+		  AND ` + visibleToEveryone("p") + `
+		GROUP BY day`
+
+	counts, err := r.countByDay(ctx, query, did, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count posts by day: %w", err)
+	}
+	return counts, nil
+}
+
+// CountCommentsByDay groups the commenter's live, visible comments by UTC day
+// Uses idx_comments_commenter_created
+func (r *postgresUserActivityRepo) CountCommentsByDay(ctx context.Context, did string, since time.Time) (map[string]int, error) {
+	query := `
+		SELECT to_char(c.created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD') AS day, COUNT(*)
+		FROM comments c
+		WHERE c.commenter_did = $1
+		  AND c.created_at >= $2
+		  AND c.deleted_at IS NULL
+		  AND ` + visibleToEveryone("c") + `
+		GROUP BY day`
+
+	counts, err := r.countByDay(ctx, query, did, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count comments by day: %w", err)
+	}
+	return counts, nil
+}
+
+func (r *postgresUserActivityRepo) countByDay(ctx context.Context, query, did string, since time.Time) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, query, did, since.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var day string
+		var count int
+		if err := rows.Scan(&day, &count); err != nil {
+			return nil, err
+		}
+		counts[day] = count
+	}
+	return counts, rows.Err()
+}
+
+// IsDeactivated reports whether the user's account is marked inactive
+func (r *postgresUserActivityRepo) IsDeactivated(ctx context.Context, did string) (bool, error) {
+	var deactivated bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT deactivated_at IS NOT NULL FROM users WHERE did = $1`, did).Scan(&deactivated)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check deactivation for %s: %w", did, err)
+	}
+	return deactivated, nil
+}
+
+// SetDeactivated marks the user's account inactive, or active again
+// An account that is already inactive keeps its original deactivation time.
+func (r *postgresUserActivityRepo) SetDeactivated(ctx context.Context, did string, deactivated bool) error {
+	query := `UPDATE users SET deactivated_at = NULL WHERE did = $1 AND deactivated_at IS NOT NULL`
+	if deactivated {
+		query = `UPDATE users SET deactivated_at = NOW() WHERE did = $1 AND deactivated_at IS NULL`
+	}
+	if _, err := r.db.ExecContext(ctx, query, did); err != nil {
+		return fmt.Errorf("failed to update deactivation for %s: %w", did, err)
+	}
+	return nil
+}
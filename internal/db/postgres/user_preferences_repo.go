@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"Coves/internal/core/users"
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type postgresUserPreferencesRepo struct {
+	db *sql.DB
+}
+
+// NewUserPreferencesRepository creates a new PostgreSQL user preferences repository
+func NewUserPreferencesRepository(db *sql.DB) users.PreferencesRepository {
+	return &postgresUserPreferencesRepo{db: db}
+}
+
+// Upsert stores a user's preferences record
+// The record is always replaced whole, but only by a newer commit: an out-of-order event
+// for an older version returns ErrStaleRevision without writing
+func (r *postgresUserPreferencesRepo) Upsert(ctx context.Context, prefs *users.Preferences) error {
+	query := `
+		INSERT INTO user_preferences (user_did, record_uri, record_cid, preferences, hide_aggregator_posts, rev, indexed_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NOW())
+		ON CONFLICT (user_did) DO UPDATE SET
+			record_uri = EXCLUDED.record_uri,
+			record_cid = EXCLUDED.record_cid,
+			preferences = EXCLUDED.preferences,
+			hide_aggregator_posts = EXCLUDED.hide_aggregator_posts,
+			rev = COALESCE(EXCLUDED.rev, user_preferences.rev),
+			indexed_at = NOW()
+		WHERE ` + revIsNewer("EXCLUDED.rev", "user_preferences.rev")
+
+	record := prefs.Record
+	if len(record) == 0 {
+		record = []byte("{}")
+	}
+
+	result, err := r.db.ExecContext(ctx, query,
+		prefs.UserDID, prefs.URI, prefs.CID, record, prefs.HideAggregatorPosts, prefs.Rev)
+	if err != nil {
+		return fmt.Errorf("failed to upsert preferences for %s: %w", prefs.UserDID, err)
+	}
+	written, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if written == 0 {
+		return users.ErrStaleRevision
+	}
+	return nil
+}
+
+// Get retrieves a user's preferences
+func (r *postgresUserPreferencesRepo) Get(ctx context.Context, userDID string) (*users.Preferences, error) {
+	query := `
+		SELECT user_did, record_uri, record_cid, COALESCE(rev, ''), preferences, hide_aggregator_posts, indexed_at
+		FROM user_preferences
+		WHERE user_did = $1`
+
+	prefs := &users.Preferences{}
+	var record []byte
+	err := r.db.QueryRowContext(ctx, query, userDID).Scan(
+		&prefs.UserDID, &prefs.URI, &prefs.CID, &prefs.Rev, &record, &prefs.HideAggregatorPosts, &prefs.IndexedAt)
+	if err == sql.ErrNoRows {
+		return nil, users.ErrPreferencesNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preferences for %s: %w", userDID, err)
+	}
+	prefs.Record = record
+
+	return prefs, nil
+}
+
+// Delete removes a user's preferences
+func (r *postgresUserPreferencesRepo) Delete(ctx context.Context, userDID string) error {
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM user_preferences WHERE user_did = $1`, userDID); err != nil {
+		return fmt.Errorf("failed to delete preferences for %s: %w", userDID, err)
+	}
+	return nil
+}
+
+// aggregatorPostsAllowedFor returns the condition that drops aggregator-authored posts for
+// viewers whose preferences hide them
+// alias qualifies the post columns and viewerParam is the viewer DID's placeholder; anonymous
+// viewers bind "" and have no preferences, so they see everything
+func aggregatorPostsAllowedFor(alias, viewerParam string) string {
+	return fmt.Sprintf(`NOT (
+			EXISTS (SELECT 1 FROM aggregators agg WHERE agg.did = %[1]s.author_did)
+			AND EXISTS (SELECT 1 FROM user_preferences up WHERE up.user_did = %[2]s AND up.hide_aggregator_posts)
+		)`, alias, viewerParam)
+}
+
+// communityNotHiddenBy returns the condition that drops posts from communities listed in the
+// viewer's hiddenCommunities preference
+// alias qualifies the post columns and viewerParam is the viewer DID's placeholder; anonymous
+// viewers bind "" and hide nothing
+func communityNotHiddenBy(alias, viewerParam string) string {
+	return fmt.Sprintf(`NOT EXISTS (
+			SELECT 1 FROM user_preferences up
+			WHERE up.user_did = %[2]s
+				AND jsonb_typeof(up.preferences->'hiddenCommunities') = 'array'
+				AND up.preferences->'hiddenCommunities' ? %[1]s.community_did
+		)`, alias, viewerParam)
+}
@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"Coves/internal/core/confusables"
 	"Coves/internal/core/users"
 	"context"
 	"database/sql"
@@ -23,37 +24,42 @@ func NewUserRepository(db *sql.DB) users.UserRepository {
 // Create inserts a new user into the users table
 func (r *postgresUserRepo) Create(ctx context.Context, user *users.User) (*users.User, error) {
 	query := `
-		INSERT INTO users (did, handle, pds_url)
-		VALUES ($1, $2, $3)
+		INSERT INTO users (did, handle, pds_url, profile_fetched, handle_skeleton)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING did, handle, pds_url, created_at, updated_at`
 
-	err := r.db.QueryRowContext(ctx, query, user.DID, user.Handle, user.PDSURL).
+	skeleton := confusables.HandleSkeleton(user.Handle)
+	err := r.db.QueryRowContext(ctx, query, user.DID, user.Handle, user.PDSURL, !user.ProfilePending, skeleton).
 		Scan(&user.DID, &user.Handle, &user.PDSURL, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
 		// Check for unique constraint violations
-		if strings.Contains(err.Error(), "duplicate key") {
-			if strings.Contains(err.Error(), "users_pkey") {
-				return nil, fmt.Errorf("user with DID already exists")
-			}
-			if strings.Contains(err.Error(), "users_handle_key") {
+		if constraint, ok := constraintViolation(err, pqUniqueViolation); ok {
+			switch constraint {
+			case "users_pkey":
+				return nil, users.ErrUserAlreadyExists
+			case "users_handle_key":
 				return nil, users.ErrHandleAlreadyTaken
 			}
 		}
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	if user.ConfusableWith, err = flagConfusableHandle(ctx, r.db, usersConfusableTable, user.DID, user.Handle, skeleton); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
 // GetByDID retrieves a user by their DID
 func (r *postgresUserRepo) GetByDID(ctx context.Context, did string) (*users.User, error) {
 	user := &users.User{}
-	query := `SELECT did, handle, pds_url, created_at, updated_at, display_name, bio, avatar_cid, banner_cid FROM users WHERE did = $1`
+	query := `SELECT did, handle, pds_url, created_at, updated_at, display_name, bio, avatar_cid, banner_cid, confusable_with FROM users WHERE did = $1`
 
-	var displayName, bio, avatarCID, bannerCID sql.NullString
+	var displayName, bio, avatarCID, bannerCID, confusableWith sql.NullString
 	err := r.db.QueryRowContext(ctx, query, did).
 		Scan(&user.DID, &user.Handle, &user.PDSURL, &user.CreatedAt, &user.UpdatedAt,
-			&displayName, &bio, &avatarCID, &bannerCID)
+			&displayName, &bio, &avatarCID, &bannerCID, &confusableWith)
 
 	if err == sql.ErrNoRows {
 		return nil, users.ErrUserNotFound
@@ -66,6 +72,7 @@ func (r *postgresUserRepo) GetByDID(ctx context.Context, did string) (*users.Use
 	user.Bio = bio.String
 	user.AvatarCID = avatarCID.String
 	user.BannerCID = bannerCID.String
+	user.ConfusableWith = confusableWith.String
 
 	return user, nil
 }
@@ -73,12 +80,12 @@ func (r *postgresUserRepo) GetByDID(ctx context.Context, did string) (*users.Use
 // GetByHandle retrieves a user by their handle
 func (r *postgresUserRepo) GetByHandle(ctx context.Context, handle string) (*users.User, error) {
 	user := &users.User{}
-	query := `SELECT did, handle, pds_url, created_at, updated_at, display_name, bio, avatar_cid, banner_cid FROM users WHERE handle = $1`
+	query := `SELECT did, handle, pds_url, created_at, updated_at, display_name, bio, avatar_cid, banner_cid, confusable_with FROM users WHERE handle = $1`
 
-	var displayName, bio, avatarCID, bannerCID sql.NullString
+	var displayName, bio, avatarCID, bannerCID, confusableWith sql.NullString
 	err := r.db.QueryRowContext(ctx, query, handle).
 		Scan(&user.DID, &user.Handle, &user.PDSURL, &user.CreatedAt, &user.UpdatedAt,
-			&displayName, &bio, &avatarCID, &bannerCID)
+			&displayName, &bio, &avatarCID, &bannerCID, &confusableWith)
 
 	if err == sql.ErrNoRows {
 		return nil, users.ErrUserNotFound
@@ -91,6 +98,7 @@ func (r *postgresUserRepo) GetByHandle(ctx context.Context, handle string) (*use
 	user.Bio = bio.String
 	user.AvatarCID = avatarCID.String
 	user.BannerCID = bannerCID.String
+	user.ConfusableWith = confusableWith.String
 
 	return user, nil
 }
@@ -100,12 +108,13 @@ func (r *postgresUserRepo) UpdateHandle(ctx context.Context, did, newHandle stri
 	user := &users.User{}
 	query := `
 		UPDATE users
-		SET handle = $2, updated_at = NOW()
+		SET handle = $2, handle_skeleton = $3, updated_at = NOW()
 		WHERE did = $1
 		RETURNING did, handle, pds_url, created_at, updated_at, display_name, bio, avatar_cid, banner_cid`
 
+	skeleton := confusables.HandleSkeleton(newHandle)
 	var displayName, bio, avatarCID, bannerCID sql.NullString
-	err := r.db.QueryRowContext(ctx, query, did, newHandle).
+	err := r.db.QueryRowContext(ctx, query, did, newHandle, skeleton).
 		Scan(&user.DID, &user.Handle, &user.PDSURL, &user.CreatedAt, &user.UpdatedAt,
 			&displayName, &bio, &avatarCID, &bannerCID)
 
@@ -114,7 +123,7 @@ func (r *postgresUserRepo) UpdateHandle(ctx context.Context, did, newHandle stri
 	}
 	if err != nil {
 		// Check for unique constraint violation on handle
-		if strings.Contains(err.Error(), "duplicate key") && strings.Contains(err.Error(), "users_handle_key") {
+		if constraint, ok := constraintViolation(err, pqUniqueViolation); ok && constraint == "users_handle_key" {
 			return nil, users.ErrHandleAlreadyTaken
 		}
 		return nil, fmt.Errorf("failed to update handle: %w", err)
@@ -125,6 +134,10 @@ func (r *postgresUserRepo) UpdateHandle(ctx context.Context, did, newHandle stri
 	user.AvatarCID = avatarCID.String
 	user.BannerCID = bannerCID.String
 
+	if user.ConfusableWith, err = flagConfusableHandle(ctx, r.db, usersConfusableTable, user.DID, user.Handle, skeleton); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
@@ -153,7 +166,7 @@ func (r *postgresUserRepo) GetByDIDs(ctx context.Context, dids []string) (map[st
 
 	// Build parameterized query with IN clause
 	// Use ANY($1) for PostgreSQL array support with pq.Array() for type conversion
-	query := `SELECT did, handle, pds_url, created_at, updated_at, display_name, bio, avatar_cid, banner_cid FROM users WHERE did = ANY($1)`
+	query := `SELECT did, handle, pds_url, created_at, updated_at, display_name, bio, avatar_cid, banner_cid, confusable_with FROM users WHERE did = ANY($1)`
 
 	rows, err := r.db.QueryContext(ctx, query, pq.Array(dids))
 	if err != nil {
@@ -169,9 +182,9 @@ func (r *postgresUserRepo) GetByDIDs(ctx context.Context, dids []string) (map[st
 	result := make(map[string]*users.User, len(dids))
 	for rows.Next() {
 		user := &users.User{}
-		var displayName, bio, avatarCID, bannerCID sql.NullString
+		var displayName, bio, avatarCID, bannerCID, confusableWith sql.NullString
 		err := rows.Scan(&user.DID, &user.Handle, &user.PDSURL, &user.CreatedAt, &user.UpdatedAt,
-			&displayName, &bio, &avatarCID, &bannerCID)
+			&displayName, &bio, &avatarCID, &bannerCID, &confusableWith)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user row: %w", err)
 		}
@@ -179,6 +192,7 @@ func (r *postgresUserRepo) GetByDIDs(ctx context.Context, dids []string) (map[st
 		user.Bio = bio.String
 		user.AvatarCID = avatarCID.String
 		user.BannerCID = bannerCID.String
+		user.ConfusableWith = confusableWith.String
 		result[user.DID] = user
 	}
 
@@ -201,14 +215,14 @@ func (r *postgresUserRepo) GetProfileStats(ctx context.Context, did string) (*us
 	// Reputation represents historical contributions, while membership_count
 	// reflects current active community access. A banned user keeps their
 	// earned reputation but loses the membership count.
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
-			(SELECT COUNT(*) FROM posts WHERE author_did = $1 AND deleted_at IS NULL) as post_count,
-			(SELECT COUNT(*) FROM comments WHERE commenter_did = $1 AND deleted_at IS NULL) as comment_count,
+			(SELECT COUNT(*) FROM posts WHERE author_did = $1 AND %[1]s) as post_count,
+			(SELECT COUNT(*) FROM comments WHERE commenter_did = $1 AND %[1]s) as comment_count,
 			(SELECT COUNT(*) FROM community_subscriptions WHERE user_did = $1) as community_count,
 			(SELECT COUNT(*) FROM community_memberships WHERE user_did = $1 AND is_banned = false) as membership_count,
 			(SELECT COALESCE(SUM(reputation_score), 0) FROM community_memberships WHERE user_did = $1) as reputation
-	`
+	`, notDeleted(""))
 
 	stats := &users.ProfileStats{}
 	err := r.db.QueryRowContext(ctx, query, did).Scan(
@@ -315,7 +329,8 @@ func (r *postgresUserRepo) Delete(ctx context.Context, did string) error {
 // Nil values in the input mean "don't change this field" - only non-nil values are updated.
 // Empty string values will clear the field in the database.
 // Returns the updated user with all fields populated.
-// Returns ErrUserNotFound if the user does not exist.
+// Returns ErrUserNotFound if the user does not exist, and ErrStaleRevision, without writing,
+// when input.Rev is not newer than the rev of the last profile commit.
 func (r *postgresUserRepo) UpdateProfile(ctx context.Context, did string, input users.UpdateProfileInput) (*users.User, error) {
 	// Validate DID format
 	if !strings.HasPrefix(did, "did:") {
@@ -323,7 +338,8 @@ func (r *postgresUserRepo) UpdateProfile(ctx context.Context, did string, input
 	}
 
 	// Build dynamic UPDATE query based on which fields are provided
-	setClauses := []string{"updated_at = NOW()"}
+	// Any profile write means the profile no longer needs backfilling
+	setClauses := []string{"updated_at = NOW()", "profile_fetched = TRUE"}
 	args := []interface{}{}
 	argNum := 1
 
@@ -348,15 +364,23 @@ func (r *postgresUserRepo) UpdateProfile(ctx context.Context, did string, input
 		argNum++
 	}
 
-	// Add the DID as the final parameter for the WHERE clause
+	// A write from a firehose commit only replaces a profile written by an older one
+	whereClause := fmt.Sprintf("did = $%d", argNum)
 	args = append(args, did)
+	argNum++
+	if input.Rev != "" {
+		revParam := fmt.Sprintf("$%d::text", argNum)
+		setClauses = append(setClauses, "profile_rev = "+revParam)
+		whereClause += " AND " + revIsNewer(revParam, "profile_rev")
+		args = append(args, input.Rev)
+	}
 
 	query := fmt.Sprintf(`
 		UPDATE users
 		SET %s
-		WHERE did = $%d
+		WHERE %s
 		RETURNING did, handle, pds_url, created_at, updated_at, display_name, bio, avatar_cid, banner_cid`,
-		strings.Join(setClauses, ", "), argNum)
+		strings.Join(setClauses, ", "), whereClause)
 
 	user := &users.User{}
 	var displayNameVal, bioVal, avatarCIDVal, bannerCIDVal sql.NullString
@@ -366,6 +390,17 @@ func (r *postgresUserRepo) UpdateProfile(ctx context.Context, did string, input
 			&displayNameVal, &bioVal, &avatarCIDVal, &bannerCIDVal)
 
 	if err == sql.ErrNoRows {
+		if input.Rev == "" {
+			return nil, users.ErrUserNotFound
+		}
+		// Either the user doesn't exist or a newer commit wrote the profile
+		var exists bool
+		if err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE did = $1)`, did).Scan(&exists); err != nil {
+			return nil, fmt.Errorf("failed to check user existence: %w", err)
+		}
+		if exists {
+			return nil, users.ErrStaleRevision
+		}
 		return nil, users.ErrUserNotFound
 	}
 	if err != nil {
@@ -379,3 +414,54 @@ func (r *postgresUserRepo) UpdateProfile(ctx context.Context, did string, input
 
 	return user, nil
 }
+
+// ListPendingProfiles returns users indexed on demand whose profile hasn't been fetched yet
+// Oldest first
+func (r *postgresUserRepo) ListPendingProfiles(ctx context.Context, limit int) ([]*users.User, error) {
+	query := `
+		SELECT did, handle, pds_url, created_at, updated_at
+		FROM users
+		WHERE NOT profile_fetched
+		ORDER BY created_at ASC
+		LIMIT $1`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending profiles: %w", err)
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil {
+			slog.Warn("failed to close rows", slog.String("error", closeErr.Error()))
+		}
+	}()
+
+	var result []*users.User
+	for rows.Next() {
+		user := &users.User{ProfilePending: true}
+		if err := rows.Scan(&user.DID, &user.Handle, &user.PDSURL, &user.CreatedAt, &user.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan pending profile: %w", err)
+		}
+		result = append(result, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating pending profiles: %w", err)
+	}
+
+	return result, nil
+}
+
+// MarkProfileFetched clears a user's pending profile flag
+func (r *postgresUserRepo) MarkProfileFetched(ctx context.Context, did string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET profile_fetched = TRUE, updated_at = NOW() WHERE did = $1`, did)
+	if err != nil {
+		return fmt.Errorf("failed to mark profile fetched: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check mark profile fetched result: %w", err)
+	}
+	if rows == 0 {
+		return users.ErrUserNotFound
+	}
+	return nil
+}
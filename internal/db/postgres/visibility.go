@@ -0,0 +1,36 @@
+package postgres
+
+import "fmt"
+
+// Visibility state convention
+//
+// posts and comments carry a moderation visibility_state independent of soft deletion:
+// visible, removed (hidden from everyone) or author_only (hidden from everyone except the
+// author, who sees the content as normal). Feed, timeline, discover and comment thread
+// reads apply visibleTo with the viewer's DID bound to a query parameter; anonymous viewers
+// bind "" and so only ever see visible content.
+//
+// An admin takedown (taken_down_at) hides a record from everyone, author included, and is
+// tracked separately so reversing it restores whatever visibility state the record had.
+
+// visibleTo returns the condition that hides removed content, and author_only content from
+// everyone but its author
+// alias qualifies the columns, authorColumn names the author DID column ("author_did" for
+// posts, "commenter_did" for comments) and viewerParam is the viewer DID's placeholder ("$4")
+func visibleTo(alias, authorColumn, viewerParam string) string {
+	return fmt.Sprintf(
+		"(%[1]s.visibility_state = 'visible' OR (%[1]s.visibility_state = 'author_only' AND %[1]s.%[2]s = %[3]s)) AND %[4]s",
+		alias, authorColumn, viewerParam, notTakenDown(alias))
+}
+
+// visibleToEveryone returns the condition for viewer-independent reads (e.g. the cached
+// logged-out front page), which only show visible content
+func visibleToEveryone(alias string) string {
+	return alias + ".visibility_state = 'visible' AND " + notTakenDown(alias)
+}
+
+// notTakenDown returns the condition that hides records under an admin takedown
+// Reads that show an author their own content regardless of visibility state still apply it.
+func notTakenDown(alias string) string {
+	return alias + ".taken_down_at IS NULL"
+}
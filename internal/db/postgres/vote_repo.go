@@ -49,12 +49,12 @@ func (r *postgresVoteRepo) Create(ctx context.Context, vote *votes.Vote) error {
 
 	if err != nil {
 		// Check for unique constraint violation (voter + subject)
-		if strings.Contains(err.Error(), "duplicate key") && strings.Contains(err.Error(), "unique_voter_subject") {
+		if constraint, ok := constraintViolation(err, pqUniqueViolation); ok && strings.HasPrefix(constraint, "unique_voter_subject") {
 			return votes.ErrVoteAlreadyExists
 		}
 
 		// Check for DID format constraint violation
-		if strings.Contains(err.Error(), "chk_voter_did_format") {
+		if constraint, ok := constraintViolation(err, pqCheckViolation); ok && constraint == "chk_voter_did_format" {
 			return fmt.Errorf("invalid voter DID format: %s", vote.VoterDID)
 		}
 
@@ -68,14 +68,14 @@ func (r *postgresVoteRepo) Create(ctx context.Context, vote *votes.Vote) error {
 // Used by Jetstream consumer for DELETE operations
 // Returns ErrVoteNotFound for soft-deleted votes
 func (r *postgresVoteRepo) GetByURI(ctx context.Context, uri string) (*votes.Vote, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
 			id, uri, cid, rkey, voter_did,
 			subject_uri, subject_cid, direction,
 			created_at, indexed_at, deleted_at
 		FROM votes
-		WHERE uri = $1 AND deleted_at IS NULL
-	`
+		WHERE uri = $1 AND %s
+	`, notDeleted(""))
 
 	var vote votes.Vote
 
@@ -98,14 +98,14 @@ func (r *postgresVoteRepo) GetByURI(ctx context.Context, uri string) (*votes.Vot
 // GetByVoterAndSubject retrieves a user's vote on a specific subject
 // Used by service to check existing vote state before creating/toggling
 func (r *postgresVoteRepo) GetByVoterAndSubject(ctx context.Context, voterDID, subjectURI string) (*votes.Vote, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
 			id, uri, cid, rkey, voter_did,
 			subject_uri, subject_cid, direction,
 			created_at, indexed_at, deleted_at
 		FROM votes
-		WHERE voter_did = $1 AND subject_uri = $2 AND deleted_at IS NULL
-	`
+		WHERE voter_did = $1 AND subject_uri = $2 AND %s
+	`, notDeleted(""))
 
 	var vote votes.Vote
 
@@ -153,60 +153,78 @@ func (r *postgresVoteRepo) Delete(ctx context.Context, uri string) error {
 	return nil
 }
 
-// ListBySubject retrieves all active votes on a specific post/comment
-// Future: Used for vote detail views
-func (r *postgresVoteRepo) ListBySubject(ctx context.Context, subjectURI string, limit, offset int) ([]*votes.Vote, error) {
-	query := `
-		SELECT
-			id, uri, cid, rkey, voter_did,
-			subject_uri, subject_cid, direction,
-			created_at, indexed_at, deleted_at
+// ListVotersForSubject lists the active votes on a subject the access policy opened up
+func (r *postgresVoteRepo) ListVotersForSubject(ctx context.Context, access votes.VoterAccess, limit, offset int) ([]*votes.Voter, error) {
+	if access.SubjectURI() == "" {
+		return nil, votes.ErrVotersForbidden
+	}
+
+	query := fmt.Sprintf(`
+		SELECT voter_did, direction, created_at
 		FROM votes
-		WHERE subject_uri = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
+		WHERE subject_uri = $1 AND %s
+		ORDER BY created_at DESC, id DESC
 		LIMIT $2 OFFSET $3
-	`
+	`, notDeleted(""))
 
-	rows, err := r.db.QueryContext(ctx, query, subjectURI, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, access.SubjectURI(), limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list votes by subject: %w", err)
+		return nil, fmt.Errorf("failed to list voters for subject: %w", err)
 	}
 	defer func() { _ = rows.Close() }()
 
-	var result []*votes.Vote
+	result := []*votes.Voter{}
 	for rows.Next() {
-		var vote votes.Vote
-		err := rows.Scan(
-			&vote.ID, &vote.URI, &vote.CID, &vote.RKey, &vote.VoterDID,
-			&vote.SubjectURI, &vote.SubjectCID, &vote.Direction,
-			&vote.CreatedAt, &vote.IndexedAt, &vote.DeletedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan vote: %w", err)
+		var voter votes.Voter
+		if err := rows.Scan(&voter.DID, &voter.Direction, &voter.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan voter: %w", err)
 		}
-		result = append(result, &vote)
+		result = append(result, &voter)
 	}
 
 	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating votes: %w", err)
+		return nil, fmt.Errorf("error iterating voters: %w", err)
 	}
 
 	return result, nil
 }
 
+// GetSubjectOwner finds the author and community of an indexed post or comment
+// A comment's community is its root post's; deleted subjects still have owners.
+func (r *postgresVoteRepo) GetSubjectOwner(ctx context.Context, subjectURI string) (*votes.SubjectOwner, error) {
+	var owner votes.SubjectOwner
+	var communityDID sql.NullString
+	err := r.db.QueryRowContext(ctx, `
+		SELECT author_did, community_did FROM posts WHERE uri = $1
+		UNION ALL
+		SELECT c.commenter_did, p.community_did
+		FROM comments c
+		LEFT JOIN posts p ON p.uri = c.root_uri
+		WHERE c.uri = $1
+		LIMIT 1`, subjectURI).Scan(&owner.AuthorDID, &communityDID)
+	if err == sql.ErrNoRows {
+		return nil, votes.ErrSubjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vote subject owner: %w", err)
+	}
+	owner.CommunityDID = communityDID.String
+	return &owner, nil
+}
+
 // ListByVoter retrieves all active votes by a specific user
 // Future: Used for user voting history
 func (r *postgresVoteRepo) ListByVoter(ctx context.Context, voterDID string, limit, offset int) ([]*votes.Vote, error) {
-	query := `
+	query := fmt.Sprintf(`
 		SELECT
 			id, uri, cid, rkey, voter_did,
 			subject_uri, subject_cid, direction,
 			created_at, indexed_at, deleted_at
 		FROM votes
-		WHERE voter_did = $1 AND deleted_at IS NULL
+		WHERE voter_did = $1 AND %s
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
-	`
+	`, notDeleted(""))
 
 	rows, err := r.db.QueryContext(ctx, query, voterDID, limit, offset)
 	if err != nil {
@@ -0,0 +1,361 @@
+package postgres
+
+import (
+	"Coves/internal/atproto/utils"
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+
+	"github.com/lib/pq"
+)
+
+// voteNullifyBatchSize bounds how many votes are neutralized/restored per transaction
+// Keeps row locks on hot posts short while the live vote consumer keeps writing
+const voteNullifyBatchSize = 250
+
+// lockVoterSQL takes the per-voter advisory lock shared with the vote indexer
+// The indexer takes it in shared mode for every vote it indexes, so batches here never
+// interleave with a vote being indexed for the same voter.
+// NOTE: Keep in sync with lockVoterSharedSQL in internal/core/votes/indexer.go
+const lockVoterSQL = `SELECT pg_advisory_xact_lock(hashtextextended('votes:' || $1, 0))`
+
+// voteCounterDelta is the aggregated counter change for one post or comment
+type voteCounterDelta struct {
+	up   int
+	down int
+}
+
+// RemoveVotesByVoter neutralizes every active vote cast by voterDID
+// The voter is recorded in nullified_voters first so votes indexed while the batches run
+// (or afterwards) are stored already neutralized, then active votes are processed in
+// batches until none remain. Re-running after a failure picks up where it stopped.
+func (r *postgresVoteRepo) RemoveVotesByVoter(ctx context.Context, voterDID, reason string) (int, error) {
+	if err := r.markVoterNullified(ctx, voterDID, reason); err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for {
+		n, err := r.nullifyVoteBatch(ctx, voterDID)
+		if err != nil {
+			return total, fmt.Errorf("failed to nullify votes for %s after %d votes: %w", voterDID, total, err)
+		}
+		if n == 0 {
+			break
+		}
+		total += n
+		log.Printf("Nullifying votes by %s: %d neutralized so far", voterDID, total)
+	}
+
+	log.Printf("✓ Nullified %d votes by %s (reason: %s)", total, voterDID, reason)
+	return total, nil
+}
+
+// RestoreVotesByVoter reverses RemoveVotesByVoter
+// The nullified_voters entry is removed first so new votes count normally again, then
+// nullified votes are restored in batches. If the voter has no entry (e.g. a previous
+// restore was interrupted) any remaining nullified votes are still restored.
+func (r *postgresVoteRepo) RestoreVotesByVoter(ctx context.Context, voterDID string, reasons ...string) (int, error) {
+	restorable, err := r.unmarkVoterNullified(ctx, voterDID, reasons)
+	if err != nil {
+		return 0, err
+	}
+	if !restorable {
+		return 0, nil
+	}
+
+	total := 0
+	for {
+		n, superseded, err := r.restoreVoteBatch(ctx, voterDID)
+		if err != nil {
+			return total, fmt.Errorf("failed to restore votes for %s after %d votes: %w", voterDID, total, err)
+		}
+		if n == 0 && superseded == 0 {
+			break
+		}
+		total += n
+		log.Printf("Restoring votes by %s: %d restored so far", voterDID, total)
+	}
+
+	if total > 0 {
+		log.Printf("✓ Restored %d votes by %s", total, voterDID)
+	}
+	return total, nil
+}
+
+// markVoterNullified records the voter in nullified_voters
+// An admin nullification takes precedence over an account-status one so that
+// reactivating the account doesn't undo it.
+func (r *postgresVoteRepo) markVoterNullified(ctx context.Context, voterDID, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	// Waits for in-flight vote inserts by this voter; later ones will see the entry
+	if _, err := tx.ExecContext(ctx, lockVoterSQL, voterDID); err != nil {
+		return fmt.Errorf("failed to lock voter: %w", err)
+	}
+
+	query := `
+		INSERT INTO nullified_voters (voter_did, reason, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (voter_did) DO UPDATE
+		SET reason = EXCLUDED.reason
+		WHERE EXCLUDED.reason = 'admin'
+	`
+	if _, err := tx.ExecContext(ctx, query, voterDID, reason); err != nil {
+		return fmt.Errorf("failed to mark voter as nullified: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// unmarkVoterNullified removes the voter from nullified_voters
+// Returns false when the voter is nullified for a reason not listed in reasons.
+func (r *postgresVoteRepo) unmarkVoterNullified(ctx context.Context, voterDID string, reasons []string) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, lockVoterSQL, voterDID); err != nil {
+		return false, fmt.Errorf("failed to lock voter: %w", err)
+	}
+
+	var reason string
+	err = tx.QueryRowContext(ctx, `SELECT reason FROM nullified_voters WHERE voter_did = $1`, voterDID).Scan(&reason)
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to get voter nullification: %w", err)
+	}
+
+	if err == nil {
+		if len(reasons) > 0 && !slices.Contains(reasons, reason) {
+			return false, nil
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM nullified_voters WHERE voter_did = $1`, voterDID); err != nil {
+			return false, fmt.Errorf("failed to unmark nullified voter: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// nullifyVoteBatch neutralizes up to voteNullifyBatchSize active votes and corrects counters
+// Returns the number of votes neutralized (0 when none remain)
+func (r *postgresVoteRepo) nullifyVoteBatch(ctx context.Context, voterDID string) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, lockVoterSQL, voterDID); err != nil {
+		return 0, fmt.Errorf("failed to lock voter: %w", err)
+	}
+
+	// Processed votes no longer match deleted_at IS NULL, so no cursor is needed
+	query := `
+		UPDATE votes
+		SET deleted_at = NOW(), nullified_at = NOW()
+		WHERE id IN (
+			SELECT id FROM votes
+			WHERE voter_did = $1 AND deleted_at IS NULL
+			ORDER BY id
+			LIMIT $2
+		)
+		RETURNING subject_uri, direction
+	`
+	deltas, n, err := collectVoteDeltas(ctx, tx, -1, query, voterDID, voteNullifyBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	if err := applyVoteCounterDeltas(ctx, tx, deltas); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return n, nil
+}
+
+// restoreVoteBatch restores up to voteNullifyBatchSize nullified votes and re-applies counters
+// Returns the number of votes restored and the number dropped as superseded (both 0 when none remain)
+func (r *postgresVoteRepo) restoreVoteBatch(ctx context.Context, voterDID string) (int, int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+			log.Printf("Failed to rollback transaction: %v", rollbackErr)
+		}
+	}()
+
+	if _, err := tx.ExecContext(ctx, lockVoterSQL, voterDID); err != nil {
+		return 0, 0, fmt.Errorf("failed to lock voter: %w", err)
+	}
+
+	// A vote indexed after the voter was un-nullified supersedes the nullified one
+	// on the same subject: drop the nullified marker instead of restoring it
+	supersededQuery := `
+		UPDATE votes v
+		SET nullified_at = NULL
+		WHERE v.voter_did = $1
+		  AND v.nullified_at IS NOT NULL
+		  AND EXISTS (
+			SELECT 1 FROM votes active
+			WHERE active.voter_did = v.voter_did
+			  AND active.subject_uri = v.subject_uri
+			  AND active.deleted_at IS NULL
+		  )
+	`
+	superseded, err := tx.ExecContext(ctx, supersededQuery, voterDID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to clear superseded nullified votes: %w", err)
+	}
+	skipped, err := superseded.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to check superseded votes: %w", err)
+	}
+
+	query := `
+		UPDATE votes
+		SET deleted_at = NULL, nullified_at = NULL
+		WHERE id IN (
+			SELECT id FROM votes
+			WHERE voter_did = $1 AND nullified_at IS NOT NULL
+			ORDER BY id
+			LIMIT $2
+		)
+		RETURNING subject_uri, direction
+	`
+	deltas, n, err := collectVoteDeltas(ctx, tx, 1, query, voterDID, voteNullifyBatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := applyVoteCounterDeltas(ctx, tx, deltas); err != nil {
+		return 0, 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return n, skipped, nil
+}
+
+// collectVoteDeltas runs a vote UPDATE ... RETURNING subject_uri, direction and
+// aggregates the returned votes into per-subject counter deltas multiplied by sign
+func collectVoteDeltas(ctx context.Context, tx *sql.Tx, sign int, query string, args ...interface{}) (map[string]*voteCounterDelta, int, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to update votes: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	deltas := make(map[string]*voteCounterDelta)
+	n := 0
+	for rows.Next() {
+		var subjectURI, direction string
+		if err := rows.Scan(&subjectURI, &direction); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan vote: %w", err)
+		}
+		delta, ok := deltas[subjectURI]
+		if !ok {
+			delta = &voteCounterDelta{}
+			deltas[subjectURI] = delta
+		}
+		if direction == "up" {
+			delta.up += sign
+		} else {
+			delta.down += sign
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating votes: %w", err)
+	}
+
+	return deltas, n, nil
+}
+
+// applyVoteCounterDeltas applies aggregated counter corrections to posts and comments
+// Rows are locked in URI order before updating so concurrent batches for different
+// voters can't deadlock; the vote consumer only ever locks a single subject row.
+// Deltas are applied relative to the current values, so concurrent increments from
+// the live vote consumer are preserved.
+func applyVoteCounterDeltas(ctx context.Context, tx *sql.Tx, deltas map[string]*voteCounterDelta) error {
+	byTable := map[string][]string{}
+	for uri := range deltas {
+		switch utils.ExtractCollectionFromURI(uri) {
+		case "social.coves.community.post":
+			byTable["posts"] = append(byTable["posts"], uri)
+		case "social.coves.community.comment":
+			byTable["comments"] = append(byTable["comments"], uri)
+		default:
+			// Unsupported collection: vote state changed, no denormalized counts to fix
+		}
+	}
+
+	for _, table := range []string{"comments", "posts"} {
+		uris := byTable[table]
+		if len(uris) == 0 {
+			continue
+		}
+		sort.Strings(uris)
+
+		ups := make([]int64, len(uris))
+		downs := make([]int64, len(uris))
+		for i, uri := range uris {
+			ups[i] = int64(deltas[uri].up)
+			downs[i] = int64(deltas[uri].down)
+		}
+
+		lockQuery := fmt.Sprintf(`SELECT uri FROM %s WHERE uri = ANY($1) ORDER BY uri FOR UPDATE`, table)
+		if _, err := tx.ExecContext(ctx, lockQuery, pq.Array(uris)); err != nil {
+			return fmt.Errorf("failed to lock %s: %w", table, err)
+		}
+
+		updateQuery := fmt.Sprintf(`
+			UPDATE %s t
+			SET upvote_count = GREATEST(0, t.upvote_count + d.up),
+			    downvote_count = GREATEST(0, t.downvote_count + d.down),
+			    score = GREATEST(0, t.upvote_count + d.up) - GREATEST(0, t.downvote_count + d.down)
+			FROM unnest($1::text[], $2::bigint[], $3::bigint[]) AS d(uri, up, down)
+			WHERE t.uri = d.uri AND t.deleted_at IS NULL
+		`, table)
+		if _, err := tx.ExecContext(ctx, updateQuery, pq.Array(uris), pq.Array(ups), pq.Array(downs)); err != nil {
+			return fmt.Errorf("failed to update %s vote counts: %w", table, err)
+		}
+	}
+
+	return nil
+}
@@ -314,7 +314,7 @@ func TestVoteRepo_Delete_Idempotent(t *testing.T) {
 	assert.NoError(t, err, "Deleting already deleted vote should be idempotent")
 }
 
-func TestVoteRepo_ListBySubject(t *testing.T) {
+func TestVoteRepo_ListVotersForSubject(t *testing.T) {
 	db := setupTestDB(t)
 	defer func() { _ = db.Close() }()
 	defer cleanupVotes(t, db)
@@ -354,10 +354,15 @@ func TestVoteRepo_ListBySubject(t *testing.T) {
 	require.NoError(t, repo.Create(ctx, vote1))
 	require.NoError(t, repo.Create(ctx, vote2))
 
-	// List votes
-	result, err := repo.ListBySubject(ctx, subjectURI, 10, 0)
+	// Voters are only listed through the access policy
+	_, err := repo.ListVotersForSubject(ctx, votes.VoterAccess{}, 10, 0)
+	assert.ErrorIs(t, err, votes.ErrVotersForbidden)
+
+	access, err := votes.NewAccessPolicy(repo, nil, []string{"did:plc:testadmin"}).AuthorizeVoters(ctx, "did:plc:testadmin", subjectURI)
+	require.NoError(t, err)
+	result, err := repo.ListVotersForSubject(ctx, access, 10, 0)
 	assert.NoError(t, err)
-	assert.Len(t, result, 2, "Should find 2 votes on subject")
+	assert.Len(t, result, 2, "Should find 2 voters on subject")
 }
 
 func TestVoteRepo_ListByVoter(t *testing.T) {
@@ -0,0 +1,72 @@
+package statearchive
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ArchiveKeySize is the length of the archive key in bytes (AES-256)
+const ArchiveKeySize = 32
+
+// errSealedTooShort is returned for sealed data shorter than a nonce
+var errSealedTooShort = errors.New("sealed data too short")
+
+// DecodeArchiveKey decodes a base64 archive key, as generated by `openssl rand -base64 32`
+func DecodeArchiveKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("invalid archive key encoding: %w", err)
+	}
+	if len(key) != ArchiveKeySize {
+		return nil, fmt.Errorf("archive key must be %d bytes, got %d", ArchiveKeySize, len(key))
+	}
+	return key, nil
+}
+
+// seal encrypts plaintext with AES-256-GCM
+// Output format: nonce || ciphertext || tag
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts data produced by seal, failing if it was sealed with another key or altered
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errSealedTooShort
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt (wrong archive key?): %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,355 @@
+// Package statearchive exports and imports the AppView's operational metadata when an
+// instance moves to new hardware: directory sync cursors, OAuth sessions, federation rules
+// and admin configuration. Content tables (posts, comments, votes, communities, users) are
+// left to pg_dump; these are the small tables that are easy to forget and painful to lose.
+//
+// An archive is versioned JSON. Rows are captured with row_to_json and restored with
+// json_populate_record, so every column round-trips exactly. OAuth sessions carry live tokens
+// and are sealed with AES-256-GCM under an archive key the operator supplies on both sides.
+package statearchive
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pressly/goose/v3"
+
+	"Coves/internal/db/migrate"
+)
+
+// FormatVersion is the archive layout this package writes and reads
+const FormatVersion = 1
+
+var (
+	// ErrUnsupportedFormat is returned for archives written by an incompatible version of this package
+	ErrUnsupportedFormat = errors.New("unsupported state archive format")
+
+	// ErrUnknownScope is returned for a scope or table name this package doesn't know
+	ErrUnknownScope = errors.New("unknown state archive scope")
+
+	// ErrTableNotEmpty is returned when an import would overwrite rows without Force
+	ErrTableNotEmpty = errors.New("table is not empty")
+
+	// ErrArchiveKeyRequired is returned when a sealed table is exported or imported without a key
+	ErrArchiveKeyRequired = errors.New("archive key required for sealed tables")
+)
+
+// table is one exported table
+type table struct {
+	name    string
+	orderBy string // Stable row order, so repeated exports of the same state are identical
+	serial  string // Column backed by a sequence, advanced past the imported rows on import
+	sealed  bool   // Rows hold credentials and are encrypted with the archive key
+}
+
+// scope is a named group of tables exported together
+type scope struct {
+	name   string
+	tables []table
+}
+
+// scopes lists every exportable scope in import order
+// The Jetstream consumers don't persist cursors (they resume from live), so the only
+// cursors kept in the database are the directory sync peers'.
+var scopes = []scope{
+	{name: "cursors", tables: []table{
+		{name: "directory_sync_peers", orderBy: "peer_url"},
+	}},
+	{name: "oauth", tables: []table{
+		{name: "oauth_sessions", orderBy: "id", serial: "id", sealed: true},
+	}},
+	{name: "federation", tables: []table{
+		{name: "federation_rules", orderBy: "id", serial: "id"},
+	}},
+	{name: "admin", tables: []table{
+		{name: "reserved_community_names", orderBy: "name"},
+		{name: "instance_featured_communities", orderBy: "position, community_did"},
+		{name: "record_takedowns", orderBy: "id", serial: "id"},
+	}},
+}
+
+// ScopeNames returns the exportable scopes in import order
+func ScopeNames() []string {
+	names := make([]string, 0, len(scopes))
+	for _, s := range scopes {
+		names = append(names, s.name)
+	}
+	return names
+}
+
+// ParseScopes splits a comma-separated scope list, returning every scope for "" or "all"
+func ParseScopes(list string) ([]string, error) {
+	list = strings.TrimSpace(list)
+	if list == "" || list == "all" {
+		return ScopeNames(), nil
+	}
+	requested := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := findScope(name); !ok {
+			return nil, fmt.Errorf("%w: %q (valid: %s)", ErrUnknownScope, name, strings.Join(ScopeNames(), ", "))
+		}
+		requested[name] = true
+	}
+	// Keep import order regardless of the order given
+	names := make([]string, 0, len(requested))
+	for _, s := range scopes {
+		if requested[s.name] {
+			names = append(names, s.name)
+		}
+	}
+	return names, nil
+}
+
+func findScope(name string) (scope, bool) {
+	for _, s := range scopes {
+		if s.name == name {
+			return s, true
+		}
+	}
+	return scope{}, false
+}
+
+// findTable looks an archived table up among the known ones
+// Table names from an archive are only ever used in SQL after this check.
+func findTable(name string) (table, bool) {
+	for _, s := range scopes {
+		for _, t := range s.tables {
+			if t.name == name {
+				return t, true
+			}
+		}
+	}
+	return table{}, false
+}
+
+// Archive is a snapshot of the selected scopes
+type Archive struct {
+	CreatedAt     time.Time   `json:"createdAt"`
+	Scopes        []string    `json:"scopes"`
+	Tables        []TableDump `json:"tables"`
+	SchemaVersion int64       `json:"schemaVersion"` // Source database's migration version
+	FormatVersion int         `json:"formatVersion"`
+}
+
+// TableDump holds one table's rows, each a row_to_json object
+// Sealed tables carry the encrypted JSON array of rows in Sealed instead of Rows.
+type TableDump struct {
+	Name     string            `json:"name"`
+	Rows     []json.RawMessage `json:"rows,omitempty"`
+	Sealed   []byte            `json:"sealed,omitempty"`
+	RowCount int               `json:"rowCount"`
+}
+
+// ExportOptions selects what to export
+type ExportOptions struct {
+	Scopes     []string // Scope names; see ScopeNames
+	ArchiveKey []byte   // 32-byte key sealing credential tables; required if one is selected
+}
+
+// Export reads the selected scopes into an archive
+// All tables are read in one repeatable-read transaction, so the archive is consistent.
+func Export(ctx context.Context, db *sql.DB, opts ExportOptions) (*Archive, error) {
+	version, err := goose.GetDBVersionContext(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin export transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	archive := &Archive{
+		FormatVersion: FormatVersion,
+		SchemaVersion: version,
+		CreatedAt:     time.Now().UTC(),
+		Scopes:        []string{},
+		Tables:        []TableDump{},
+	}
+	for _, name := range opts.Scopes {
+		s, ok := findScope(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownScope, name)
+		}
+		for _, t := range s.tables {
+			dump, err := exportTable(ctx, tx, t, opts.ArchiveKey)
+			if err != nil {
+				return nil, err
+			}
+			archive.Tables = append(archive.Tables, *dump)
+		}
+		archive.Scopes = append(archive.Scopes, s.name)
+	}
+	return archive, nil
+}
+
+func exportTable(ctx context.Context, tx *sql.Tx, t table, key []byte) (*TableDump, error) {
+	if t.sealed && len(key) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrArchiveKeyRequired, t.name)
+	}
+
+	// t comes from the scopes list, never from input
+	query := fmt.Sprintf(`SELECT row_to_json(t)::text FROM %s t ORDER BY %s`, t.name, t.orderBy)
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", t.name, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	dump := &TableDump{Name: t.name, Rows: []json.RawMessage{}}
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", t.name, err)
+		}
+		dump.Rows = append(dump.Rows, json.RawMessage(row))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", t.name, err)
+	}
+	dump.RowCount = len(dump.Rows)
+
+	if t.sealed {
+		plaintext, err := json.Marshal(dump.Rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s rows: %w", t.name, err)
+		}
+		dump.Sealed, err = seal(key, plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("failed to seal %s: %w", t.name, err)
+		}
+		dump.Rows = nil
+	}
+	return dump, nil
+}
+
+// ImportOptions controls how an archive is restored
+type ImportOptions struct {
+	ArchiveKey []byte // Key the archive's credential tables were sealed with
+	Force      bool   // Replace the rows of non-empty tables instead of refusing
+}
+
+// TableResult reports how many rows were restored into a table
+type TableResult struct {
+	Name     string
+	Rows     int
+	Replaced int // Rows deleted first because of Force
+}
+
+// Import restores an archive in a single transaction
+// The database must be at the archive's schema version, so every column lines up. Tables
+// that already hold rows are refused unless opts.Force is set, in which case their rows are
+// replaced. Nothing is written if any table fails.
+func Import(ctx context.Context, db *sql.DB, archive *Archive, opts ImportOptions) ([]TableResult, error) {
+	if archive.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("%w: archive is format %d, this binary reads %d",
+			ErrUnsupportedFormat, archive.FormatVersion, FormatVersion)
+	}
+	version, err := goose.GetDBVersionContext(ctx, db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	if version != archive.SchemaVersion {
+		return nil, fmt.Errorf("%w: archive was exported at %d, database is at %d; migrate both to the same version first",
+			migrate.ErrSchemaMismatch, archive.SchemaVersion, version)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin import transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	results := make([]TableResult, 0, len(archive.Tables))
+	for _, dump := range archive.Tables {
+		t, ok := findTable(dump.Name)
+		if !ok {
+			return nil, fmt.Errorf("%w: table %q", ErrUnknownScope, dump.Name)
+		}
+		result, err := importTable(ctx, tx, t, dump, opts)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return results, nil
+}
+
+func importTable(ctx context.Context, tx *sql.Tx, t table, dump TableDump, opts ImportOptions) (*TableResult, error) {
+	rows, err := dumpRows(t, dump, opts.ArchiveKey)
+	if err != nil {
+		return nil, err
+	}
+	result := &TableResult{Name: t.name, Rows: len(rows)}
+
+	var existing int
+	if err := tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s`, t.name)).Scan(&existing); err != nil {
+		return nil, fmt.Errorf("failed to count %s: %w", t.name, err)
+	}
+	if existing > 0 {
+		if !opts.Force {
+			return nil, fmt.Errorf("%w: %s has %d rows; rerun with --force to replace them", ErrTableNotEmpty, t.name, existing)
+		}
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s`, t.name)); err != nil {
+			return nil, fmt.Errorf("failed to clear %s: %w", t.name, err)
+		}
+		result.Replaced = existing
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %[1]s SELECT * FROM json_populate_record(NULL::%[1]s, $1::json)`, t.name)
+	for i, row := range rows {
+		if _, err := tx.ExecContext(ctx, insert, string(row)); err != nil {
+			return nil, fmt.Errorf("failed to restore %s row %d: %w", t.name, i, err)
+		}
+	}
+
+	if t.serial != "" {
+		// Continue the sequence after the restored rows; is_called=false makes an empty table start at 1
+		advance := fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('%[1]s', '%[2]s'), COALESCE(MAX(%[2]s), 0) + 1, false) FROM %[1]s`,
+			t.name, t.serial)
+		if _, err := tx.ExecContext(ctx, advance); err != nil {
+			return nil, fmt.Errorf("failed to advance %s.%s sequence: %w", t.name, t.serial, err)
+		}
+	}
+	return result, nil
+}
+
+// dumpRows returns a table's rows, opening them if they're sealed
+func dumpRows(t table, dump TableDump, key []byte) ([]json.RawMessage, error) {
+	var rows []json.RawMessage
+	if t.sealed {
+		if len(dump.Rows) > 0 {
+			return nil, fmt.Errorf("%s must be sealed but the archive holds it in plain text", t.name)
+		}
+		if len(dump.Sealed) == 0 {
+			return []json.RawMessage{}, nil
+		}
+		if len(key) == 0 {
+			return nil, fmt.Errorf("%w: %s", ErrArchiveKeyRequired, t.name)
+		}
+		plaintext, err := open(key, dump.Sealed)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", t.name, err)
+		}
+		if err := json.Unmarshal(plaintext, &rows); err != nil {
+			return nil, fmt.Errorf("failed to decode %s rows: %w", t.name, err)
+		}
+	} else {
+		rows = dump.Rows
+	}
+	if len(rows) != dump.RowCount {
+		return nil, fmt.Errorf("%s holds %d rows but the archive records %d", t.name, len(rows), dump.RowCount)
+	}
+	return rows, nil
+}
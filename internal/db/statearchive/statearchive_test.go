@@ -0,0 +1,112 @@
+package statearchive
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(fill byte) []byte {
+	return bytes.Repeat([]byte{fill}, ArchiveKeySize)
+}
+
+func TestSealOpen(t *testing.T) {
+	plaintext := []byte(`[{"did":"did:plc:alice","access_token":"secret"}]`)
+
+	sealed, err := seal(testKey(1), plaintext)
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "secret")
+
+	opened, err := open(testKey(1), sealed)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	_, err = open(testKey(2), sealed)
+	assert.Error(t, err, "a different key must not open the archive")
+
+	sealed[len(sealed)-1] ^= 0xff
+	_, err = open(testKey(1), sealed)
+	assert.Error(t, err, "altered data must not open")
+
+	_, err = open(testKey(1), []byte{1, 2})
+	assert.ErrorIs(t, err, errSealedTooShort)
+}
+
+func TestDecodeArchiveKey(t *testing.T) {
+	key, err := DecodeArchiveKey(base64.StdEncoding.EncodeToString(testKey(7)) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, testKey(7), key)
+
+	_, err = DecodeArchiveKey(base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+
+	_, err = DecodeArchiveKey("not base64!")
+	assert.Error(t, err)
+}
+
+func TestParseScopes(t *testing.T) {
+	all, err := ParseScopes("")
+	require.NoError(t, err)
+	assert.Equal(t, ScopeNames(), all)
+
+	all, err = ParseScopes("all")
+	require.NoError(t, err)
+	assert.Equal(t, ScopeNames(), all)
+
+	// Scopes come back in import order, deduplicated
+	selected, err := ParseScopes("admin, cursors,admin")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cursors", "admin"}, selected)
+
+	_, err = ParseScopes("cursors,posts")
+	assert.ErrorIs(t, err, ErrUnknownScope)
+}
+
+func TestDumpRows(t *testing.T) {
+	sessions, _ := findTable("oauth_sessions")
+	rules, _ := findTable("federation_rules")
+	rows := []json.RawMessage{json.RawMessage(`{"id":1}`), json.RawMessage(`{"id":2}`)}
+
+	t.Run("plain", func(t *testing.T) {
+		got, err := dumpRows(rules, TableDump{Name: rules.name, Rows: rows, RowCount: 2}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, rows, got)
+
+		_, err = dumpRows(rules, TableDump{Name: rules.name, Rows: rows, RowCount: 3}, nil)
+		assert.Error(t, err, "a row count mismatch means a truncated archive")
+	})
+
+	t.Run("sealed", func(t *testing.T) {
+		plaintext, err := json.Marshal(rows)
+		require.NoError(t, err)
+		sealed, err := seal(testKey(1), plaintext)
+		require.NoError(t, err)
+		dump := TableDump{Name: sessions.name, Sealed: sealed, RowCount: 2}
+
+		got, err := dumpRows(sessions, dump, testKey(1))
+		require.NoError(t, err)
+		assert.Equal(t, rows, got)
+
+		_, err = dumpRows(sessions, dump, nil)
+		assert.ErrorIs(t, err, ErrArchiveKeyRequired)
+
+		_, err = dumpRows(sessions, dump, testKey(2))
+		assert.Error(t, err)
+	})
+
+	t.Run("sealed table in plain text is refused", func(t *testing.T) {
+		_, err := dumpRows(sessions, TableDump{Name: sessions.name, Rows: rows, RowCount: 2}, testKey(1))
+		assert.Error(t, err)
+	})
+}
+
+func TestImport_RejectsOtherFormats(t *testing.T) {
+	// The format check runs before the database is touched
+	_, err := Import(context.Background(), nil, &Archive{FormatVersion: FormatVersion + 1}, ImportOptions{})
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
@@ -0,0 +1,97 @@
+// Package txrunner runs multi-repository writes in a single database transaction.
+//
+// WithTx stores the transaction in the context it hands to its callback. Repositories
+// run their queries through From(ctx, r.db), so any repository method called with that
+// context joins the transaction instead of using its own connection. Repository methods
+// that need several statements to be atomic wrap them in WithTx too: called on their own
+// they get a transaction of their own, called inside a service's WithTx they join it.
+package txrunner
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+)
+
+// Querier is the subset of *sql.DB and *sql.Tx that repositories run queries through
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Runner runs a function in a transaction
+// Services depend on Runner rather than *sql.DB so they can be tested without a database.
+type Runner interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txKey struct{}
+
+// From returns the transaction started by WithTx for ctx, or db when there is none
+func From(ctx context.Context, db Querier) Querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// InTx reports whether ctx carries a transaction started by WithTx
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return ok
+}
+
+// WithTx runs fn in a transaction, committing when it returns nil and rolling back when
+// it returns an error or panics (the panic is re-raised after the rollback)
+// When ctx already carries a transaction, fn joins it and the outermost WithTx decides
+// whether to commit, so an error anywhere rolls back the whole unit of work.
+func WithTx(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) (err error) {
+	if InTx(ctx) {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			rollback(tx)
+			panic(p)
+		}
+		if err != nil {
+			rollback(tx)
+		}
+	}()
+
+	if err = fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func rollback(tx *sql.Tx) {
+	if rollbackErr := tx.Rollback(); rollbackErr != nil && rollbackErr != sql.ErrTxDone {
+		log.Printf("Failed to rollback transaction: %v", rollbackErr)
+	}
+}
+
+type runner struct {
+	db *sql.DB
+}
+
+// NewRunner creates a Runner that starts transactions on db
+func NewRunner(db *sql.DB) Runner {
+	return &runner{db: db}
+}
+
+// WithTx runs fn in a transaction on the runner's database
+func (r *runner) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return WithTx(ctx, r.db, fn)
+}
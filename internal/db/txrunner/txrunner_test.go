@@ -0,0 +1,159 @@
+package txrunner
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// recordingDriver is a database/sql driver that records transaction outcomes
+// Every statement succeeds; nothing is stored.
+type recordingDriver struct {
+	mu        sync.Mutex
+	begins    int
+	commits   int
+	rollbacks int
+	execs     int
+}
+
+func (d *recordingDriver) Open(string) (driver.Conn, error) { return &recordingConn{d: d}, nil }
+
+type recordingConn struct{ d *recordingDriver }
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepare not supported")
+}
+func (c *recordingConn) Close() error { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.begins++
+	return &recordingTx{d: c.d}, nil
+}
+
+func (c *recordingConn) ExecContext(context.Context, string, []driver.NamedValue) (driver.Result, error) {
+	c.d.mu.Lock()
+	defer c.d.mu.Unlock()
+	c.d.execs++
+	return driver.RowsAffected(1), nil
+}
+
+type recordingTx struct{ d *recordingDriver }
+
+func (t *recordingTx) Commit() error {
+	t.d.mu.Lock()
+	defer t.d.mu.Unlock()
+	t.d.commits++
+	return nil
+}
+
+func (t *recordingTx) Rollback() error {
+	t.d.mu.Lock()
+	defer t.d.mu.Unlock()
+	t.d.rollbacks++
+	return nil
+}
+
+func openRecordingDB(t *testing.T) (*sql.DB, *recordingDriver) {
+	t.Helper()
+	d := &recordingDriver{}
+	name := "txrunner-recording-" + t.Name()
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("Failed to open recording database: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db, d
+}
+
+func TestWithTx_CommitsOnSuccess(t *testing.T) {
+	db, d := openRecordingDB(t)
+
+	err := WithTx(context.Background(), db, func(ctx context.Context) error {
+		if !InTx(ctx) {
+			t.Error("Expected the callback context to carry the transaction")
+		}
+		if _, ok := From(ctx, db).(*sql.Tx); !ok {
+			t.Error("Expected From to return the transaction inside WithTx")
+		}
+		_, err := From(ctx, db).ExecContext(ctx, "UPDATE a")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("WithTx failed: %v", err)
+	}
+	if d.begins != 1 || d.commits != 1 || d.rollbacks != 0 || d.execs != 1 {
+		t.Errorf("Expected one committed transaction, got %+v", d)
+	}
+}
+
+func TestWithTx_RollsBackOnError(t *testing.T) {
+	db, d := openRecordingDB(t)
+	injected := errors.New("injected")
+
+	err := WithTx(context.Background(), db, func(ctx context.Context) error {
+		if _, err := From(ctx, db).ExecContext(ctx, "UPDATE a"); err != nil {
+			return err
+		}
+		return injected
+	})
+	if !errors.Is(err, injected) {
+		t.Fatalf("Expected the callback's error, got %v", err)
+	}
+	if d.commits != 0 || d.rollbacks != 1 {
+		t.Errorf("Expected a rollback and no commit, got %+v", d)
+	}
+}
+
+func TestWithTx_RollsBackOnPanic(t *testing.T) {
+	db, d := openRecordingDB(t)
+
+	defer func() {
+		if p := recover(); p != "injected" {
+			t.Errorf("Expected the panic to be re-raised, got %v", p)
+		}
+		if d.commits != 0 || d.rollbacks != 1 {
+			t.Errorf("Expected a rollback and no commit, got %+v", d)
+		}
+	}()
+
+	_ = WithTx(context.Background(), db, func(ctx context.Context) error {
+		panic("injected")
+	})
+}
+
+func TestWithTx_NestedJoinsOuter(t *testing.T) {
+	db, d := openRecordingDB(t)
+	injected := errors.New("injected")
+
+	err := WithTx(context.Background(), db, func(ctx context.Context) error {
+		outer := From(ctx, db)
+		if err := WithTx(ctx, db, func(ctx context.Context) error {
+			if From(ctx, db) != outer {
+				t.Error("Expected the nested call to join the outer transaction")
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		// A failure after the nested unit completed still rolls it back
+		return injected
+	})
+	if !errors.Is(err, injected) {
+		t.Fatalf("Expected the callback's error, got %v", err)
+	}
+	if d.begins != 1 || d.commits != 0 || d.rollbacks != 1 {
+		t.Errorf("Expected a single rolled back transaction, got %+v", d)
+	}
+}
+
+func TestFrom_WithoutTransaction(t *testing.T) {
+	db, _ := openRecordingDB(t)
+	if From(context.Background(), db) != Querier(db) {
+		t.Error("Expected From to return the database outside a transaction")
+	}
+}
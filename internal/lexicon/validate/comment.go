@@ -0,0 +1,65 @@
+package validate
+
+import (
+	"Coves/internal/validation/text"
+)
+
+// MaxCommentContentBytes is the maximum size of comment content
+// Per lexicon: max 3000 graphemes, ~30000 bytes. Bytes are checked after sanitizing so NFC
+// growth counts.
+const MaxCommentContentBytes = 30000
+
+// ValidateComment checks a social.coves.community.comment record
+func ValidateComment(record map[string]interface{}) []Violation {
+	var v violations
+
+	// Content is checked as the consumer stores it: sanitized
+	if content, ok := v.str(record, "", "content"); ok {
+		content = text.Sanitize(content)
+		switch {
+		case content == "":
+			v.add("content", CodeRequired, "content is required")
+		case len(content) > MaxCommentContentBytes:
+			v.add("content", CodeTooLong, "content exceeds maximum length (%d bytes): got %d bytes", MaxCommentContentBytes, len(content))
+		}
+	}
+	v.requiredStr(record, "", "createdAt")
+
+	// Threading references: both root and parent are strong references to AT-URIs
+	reply, ok := v.object(record, "", "reply")
+	switch {
+	case !ok:
+	case reply == nil:
+		v.add("reply", CodeRequired, "reply is required")
+	default:
+		v.replyRef(reply, "reply", "root")
+		v.replyRef(reply, "reply", "parent")
+	}
+
+	v.array(record, "", "facets")
+	v.object(record, "", "embed")
+	v.stringList(record, "", "langs")
+
+	return v
+}
+
+// replyRef checks one strong reference of a comment's reply
+func (v *violations) replyRef(reply map[string]interface{}, parent, field string) {
+	ref, ok := v.object(reply, parent, field)
+	if !ok {
+		return
+	}
+	refPath := path(parent, field)
+	if ref == nil {
+		v.add(refPath, CodeRequired, "%s reference must have both URI and CID", field)
+		return
+	}
+
+	uri := v.requiredStr(ref, refPath, "uri")
+	v.requiredStr(ref, refPath, "cid")
+	if uri != "" {
+		if err := atURIShape(uri); err != nil {
+			v.add(path(refPath, "uri"), CodeInvalidURI, "invalid %s URI: %v", field, err)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func validComment() map[string]interface{} {
+	return map[string]interface{}{
+		"$type":   CommentCollection,
+		"content": "Nice post",
+		"reply": map[string]interface{}{
+			"root":   map[string]interface{}{"uri": "at://did:plc:community/social.coves.community.post/3kpost", "cid": "bafyroot"},
+			"parent": map[string]interface{}{"uri": "at://did:plc:alice/social.coves.community.comment/3kc1", "cid": "bafyparent"},
+		},
+		"facets":    []interface{}{},
+		"langs":     []interface{}{"en"},
+		"createdAt": "2025-01-01T00:00:00Z",
+	}
+}
+
+func setReplyRef(field string, ref interface{}) func(map[string]interface{}) {
+	return func(r map[string]interface{}) {
+		r["reply"].(map[string]interface{})[field] = ref
+	}
+}
+
+func TestValidateComment(t *testing.T) {
+	runRuleCases(t, validComment, ValidateComment, []ruleCase{
+		{
+			name:   "unknown fields are ignored",
+			mutate: func(r map[string]interface{}) { r["futureFeature"] = map[string]interface{}{"enabled": true} },
+		},
+		{
+			name:   "missing content",
+			mutate: func(r map[string]interface{}) { delete(r, "content") },
+			want:   []string{"content:required"},
+		},
+		{
+			name:   "content empty after sanitizing",
+			mutate: func(r map[string]interface{}) { r["content"] = "\u2066\x00\u2069" },
+			want:   []string{"content:required"},
+		},
+		{
+			name:   "content not a string",
+			mutate: func(r map[string]interface{}) { r["content"] = 42.0 },
+			want:   []string{"content:invalid_type"},
+		},
+		{
+			name:   "content at the byte limit",
+			mutate: func(r map[string]interface{}) { r["content"] = strings.Repeat("a", MaxCommentContentBytes) },
+		},
+		{
+			name:   "content over the byte limit",
+			mutate: func(r map[string]interface{}) { r["content"] = strings.Repeat("a", MaxCommentContentBytes+1) },
+			want:   []string{"content:too_long"},
+		},
+		{
+			name: "content over the byte limit only after NFC normalization",
+			mutate: func(r map[string]interface{}) {
+				// U+0958 is a composition exclusion: NFC decomposes it, growing 3 bytes to 6
+				r["content"] = strings.Repeat("\u0958", MaxCommentContentBytes/6+1)
+			},
+			want: []string{"content:too_long"},
+		},
+		{
+			name:   "missing createdAt",
+			mutate: func(r map[string]interface{}) { delete(r, "createdAt") },
+			want:   []string{"createdAt:required"},
+		},
+		{
+			name:   "missing reply",
+			mutate: func(r map[string]interface{}) { delete(r, "reply") },
+			want:   []string{"reply:required"},
+		},
+		{
+			name:   "reply not an object",
+			mutate: func(r map[string]interface{}) { r["reply"] = "at://did:plc:c/social.coves.community.post/1" },
+			want:   []string{"reply:invalid_type"},
+		},
+		{
+			name:   "missing root",
+			mutate: func(r map[string]interface{}) { delete(r["reply"].(map[string]interface{}), "root") },
+			want:   []string{"reply.root:required"},
+		},
+		{
+			name:   "root without CID",
+			mutate: setReplyRef("root", map[string]interface{}{"uri": "at://did:plc:c/social.coves.community.post/1"}),
+			want:   []string{"reply.root.cid:required"},
+		},
+		{
+			name:   "parent without URI",
+			mutate: setReplyRef("parent", map[string]interface{}{"cid": "bafyparent"}),
+			want:   []string{"reply.parent.uri:required"},
+		},
+		{
+			name:   "parent not an object",
+			mutate: setReplyRef("parent", "bafyparent"),
+			want:   []string{"reply.parent:invalid_type"},
+		},
+		{
+			name:   "root URI without the at scheme",
+			mutate: setReplyRef("root", map[string]interface{}{"uri": "https://did:plc:c/social.coves.community.post/1", "cid": "bafyroot"}),
+			want:   []string{"reply.root.uri:invalid_uri"},
+		},
+		{
+			name:   "root URI missing the rkey",
+			mutate: setReplyRef("root", map[string]interface{}{"uri": "at://did:plc:c/social.coves.community.post", "cid": "bafyroot"}),
+			want:   []string{"reply.root.uri:invalid_uri"},
+		},
+		{
+			name:   "parent URI in a handle's repository",
+			mutate: setReplyRef("parent", map[string]interface{}{"uri": "at://alice.coves.social/social.coves.community.comment/1", "cid": "bafyparent"}),
+			want:   []string{"reply.parent.uri:invalid_uri"},
+		},
+		{
+			name:   "parent URI with an empty collection",
+			mutate: setReplyRef("parent", map[string]interface{}{"uri": "at://did:plc:c//1", "cid": "bafyparent"}),
+			want:   []string{"reply.parent.uri:invalid_uri"},
+		},
+		{
+			name:   "facets not an array",
+			mutate: func(r map[string]interface{}) { r["facets"] = map[string]interface{}{} },
+			want:   []string{"facets:invalid_type"},
+		},
+		{
+			name:   "embed not an object",
+			mutate: func(r map[string]interface{}) { r["embed"] = "image.png" },
+			want:   []string{"embed:invalid_type"},
+		},
+		{
+			name:   "langs not strings",
+			mutate: func(r map[string]interface{}) { r["langs"] = []interface{}{"en", 1.0} },
+			want:   []string{"langs:invalid_type"},
+		},
+		{
+			name: "every violation is reported",
+			mutate: func(r map[string]interface{}) {
+				delete(r, "content")
+				delete(r, "createdAt")
+				delete(r, "reply")
+			},
+			want: []string{"content:required", "createdAt:required", "reply:required"},
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package validate
+
+import (
+	"time"
+)
+
+// MaxProfileCategories is how many categories a community profile can list
+const MaxProfileCategories = 3
+
+// profileStringFields are the community profile fields indexed as strings
+var profileStringFields = []string{
+	"name", "displayName", "description", "handle", "atprotoHandle",
+	"createdBy", "hostedBy", "visibility", "moderationType", "crowdControl",
+	"federatedFrom", "federatedId",
+}
+
+// profileIntegerFields are the community profile fields indexed as integers
+var profileIntegerFields = []string{
+	"memberCount", "subscriberCount", "scoreHidingHours", "collapseThreshold",
+}
+
+// ValidateCommunityProfile checks a social.coves.community.profile record
+// The profile's rkey and the repository it was written to are checked by the consumer.
+func ValidateCommunityProfile(record map[string]interface{}) []Violation {
+	var v violations
+
+	for _, field := range profileStringFields {
+		v.str(record, "", field)
+	}
+	for _, field := range profileIntegerFields {
+		v.integer(record, "", field)
+	}
+
+	if createdAt, ok := v.str(record, "", "createdAt"); ok && createdAt != "" {
+		if _, err := time.Parse(time.RFC3339, createdAt); err != nil {
+			v.add("createdAt", CodeInvalidFormat, "createdAt must be an RFC 3339 datetime")
+		}
+	}
+
+	v.object(record, "", "avatar")
+	v.object(record, "", "banner")
+	v.object(record, "", "postingRules")
+	v.array(record, "", "flairs")
+	v.array(record, "", "descriptionFacets")
+	v.stringList(record, "", "contentWarnings")
+	// Categories outside the taxonomy are indexed and browse as "other"; only the count is limited
+	if categories, ok := v.stringList(record, "", "categories"); ok && len(categories) > MaxProfileCategories {
+		v.add("categories", CodeTooMany, "at most %d categories are allowed: got %d", MaxProfileCategories, len(categories))
+	}
+	if federation, ok := v.object(record, "", "federation"); ok && federation != nil {
+		v.boolean(federation, "federation", "allowExternalDiscovery")
+	}
+
+	return v
+}
+
+// ValidateSubscription checks a social.coves.community.subscription record
+// contentVisibility isn't checked: out-of-range values are clamped when indexed.
+func ValidateSubscription(record map[string]interface{}) []Violation {
+	var v violations
+
+	subject := v.requiredStr(record, "", "subject")
+	v.did("subject", subject)
+	v.requiredStr(record, "", "createdAt")
+
+	return v
+}
@@ -0,0 +1,167 @@
+package validate
+
+import (
+	"testing"
+)
+
+func validCommunityProfile() map[string]interface{} {
+	return map[string]interface{}{
+		"$type":            CommunityProfileCollection,
+		"name":             "gaming",
+		"displayName":      "Gaming",
+		"description":      "Games and gaming",
+		"createdBy":        "did:plc:creator",
+		"hostedBy":         "did:web:coves.social",
+		"visibility":       "public",
+		"avatar":           map[string]interface{}{"$type": "blob", "ref": map[string]interface{}{"$link": "bafyavatar"}},
+		"contentWarnings":  []interface{}{"nsfw"},
+		"flairs":           []interface{}{},
+		"scoreHidingHours": 2.0,
+		"federation":       map[string]interface{}{"allowExternalDiscovery": true},
+		"createdAt":        "2025-01-01T00:00:00Z",
+	}
+}
+
+func TestValidateCommunityProfile(t *testing.T) {
+	runRuleCases(t, validCommunityProfile, ValidateCommunityProfile, []ruleCase{
+		{
+			name:   "unknown fields are ignored",
+			mutate: func(r map[string]interface{}) { r["federation"].(map[string]interface{})["newPolicy"] = "strict" },
+		},
+		{
+			name: "null fields are treated as unset",
+			mutate: func(r map[string]interface{}) {
+				r["description"] = nil
+				r["avatar"] = nil
+			},
+		},
+		{
+			name:   "createdAt is optional",
+			mutate: func(r map[string]interface{}) { delete(r, "createdAt") },
+		},
+		{
+			name:   "name not a string",
+			mutate: func(r map[string]interface{}) { r["name"] = 7.0 },
+			want:   []string{"name:invalid_type"},
+		},
+		{
+			name:   "hostedBy not a string",
+			mutate: func(r map[string]interface{}) { r["hostedBy"] = map[string]interface{}{} },
+			want:   []string{"hostedBy:invalid_type"},
+		},
+		{
+			name:   "createdAt not RFC 3339",
+			mutate: func(r map[string]interface{}) { r["createdAt"] = "2025-01-01" },
+			want:   []string{"createdAt:invalid_format"},
+		},
+		{
+			name:   "createdAt not a string",
+			mutate: func(r map[string]interface{}) { r["createdAt"] = 1735689600.0 },
+			want:   []string{"createdAt:invalid_type"},
+		},
+		{
+			name:   "fractional count",
+			mutate: func(r map[string]interface{}) { r["scoreHidingHours"] = 1.5 },
+			want:   []string{"scoreHidingHours:invalid_type"},
+		},
+		{
+			name:   "count not a number",
+			mutate: func(r map[string]interface{}) { r["collapseThreshold"] = "-5" },
+			want:   []string{"collapseThreshold:invalid_type"},
+		},
+		{
+			name:   "avatar not a blob object",
+			mutate: func(r map[string]interface{}) { r["avatar"] = "bafyavatar" },
+			want:   []string{"avatar:invalid_type"},
+		},
+		{
+			name:   "banner not a blob object",
+			mutate: func(r map[string]interface{}) { r["banner"] = 1.0 },
+			want:   []string{"banner:invalid_type"},
+		},
+		{
+			name:   "postingRules not an object",
+			mutate: func(r map[string]interface{}) { r["postingRules"] = []interface{}{} },
+			want:   []string{"postingRules:invalid_type"},
+		},
+		{
+			name:   "flairs not an array",
+			mutate: func(r map[string]interface{}) { r["flairs"] = map[string]interface{}{} },
+			want:   []string{"flairs:invalid_type"},
+		},
+		{
+			name:   "descriptionFacets not an array",
+			mutate: func(r map[string]interface{}) { r["descriptionFacets"] = "facets" },
+			want:   []string{"descriptionFacets:invalid_type"},
+		},
+		{
+			name:   "contentWarnings not strings",
+			mutate: func(r map[string]interface{}) { r["contentWarnings"] = []interface{}{true} },
+			want:   []string{"contentWarnings:invalid_type"},
+		},
+		{
+			name:   "categories outside the taxonomy are accepted",
+			mutate: func(r map[string]interface{}) { r["categories"] = []interface{}{"gaming", "knitting"} },
+		},
+		{
+			name:   "categories not strings",
+			mutate: func(r map[string]interface{}) { r["categories"] = []interface{}{"gaming", 3.0} },
+			want:   []string{"categories:invalid_type"},
+		},
+		{
+			name:   "too many categories",
+			mutate: func(r map[string]interface{}) { r["categories"] = []interface{}{"art", "books", "food", "music"} },
+			want:   []string{"categories:too_many"},
+		},
+		{
+			name:   "federation not an object",
+			mutate: func(r map[string]interface{}) { r["federation"] = true },
+			want:   []string{"federation:invalid_type"},
+		},
+		{
+			name: "allowExternalDiscovery not a boolean",
+			mutate: func(r map[string]interface{}) {
+				r["federation"] = map[string]interface{}{"allowExternalDiscovery": "yes"}
+			},
+			want: []string{"federation.allowExternalDiscovery:invalid_type"},
+		},
+	})
+}
+
+func validSubscription() map[string]interface{} {
+	return map[string]interface{}{
+		"$type":             SubscriptionCollection,
+		"subject":           "did:plc:community",
+		"contentVisibility": 3.0,
+		"createdAt":         "2025-01-01T00:00:00Z",
+	}
+}
+
+func TestValidateSubscription(t *testing.T) {
+	runRuleCases(t, validSubscription, ValidateSubscription, []ruleCase{
+		{
+			name:   "out-of-range contentVisibility is left to clamping",
+			mutate: func(r map[string]interface{}) { r["contentVisibility"] = 9.0 },
+		},
+		{
+			name:   "missing subject",
+			mutate: func(r map[string]interface{}) { delete(r, "subject") },
+			want:   []string{"subject:required"},
+		},
+		{
+			name:   "subject not a string",
+			mutate: func(r map[string]interface{}) { r["subject"] = map[string]interface{}{"did": "did:plc:community"} },
+			want:   []string{"subject:invalid_type"},
+		},
+		{
+			name:   "subject not a DID",
+			mutate: func(r map[string]interface{}) { r["subject"] = "gaming.coves.social" },
+			want:   []string{"subject:invalid_format"},
+		},
+		{
+			name:   "missing createdAt",
+			mutate: func(r map[string]interface{}) { delete(r, "createdAt") },
+			want:   []string{"createdAt:required"},
+		},
+	})
+}
@@ -0,0 +1,155 @@
+package validate
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+)
+
+// violations collects the violations of one record
+type violations []Violation
+
+func (v *violations) add(field, code, format string, args ...interface{}) {
+	*v = append(*v, Violation{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+// path joins a parent field path and a child field name
+func path(parent, field string) string {
+	if parent == "" {
+		return field
+	}
+	return parent + "." + field
+}
+
+// str returns the string at obj[field]
+// Missing and null fields are returned as "". ok is false when the field holds another type,
+// which is recorded as a violation.
+func (v *violations) str(obj map[string]interface{}, parent, field string) (string, bool) {
+	raw, present := obj[field]
+	if !present || raw == nil {
+		return "", true
+	}
+	s, ok := raw.(string)
+	if !ok {
+		v.add(path(parent, field), CodeInvalidType, "%s must be a string", field)
+		return "", false
+	}
+	return s, true
+}
+
+// requiredStr is str with a violation for missing and empty strings
+func (v *violations) requiredStr(obj map[string]interface{}, parent, field string) string {
+	s, ok := v.str(obj, parent, field)
+	if ok && s == "" {
+		v.add(path(parent, field), CodeRequired, "%s is required", field)
+	}
+	return s
+}
+
+// object returns the object at obj[field], nil when missing or null
+// ok is false when the field holds another type, which is recorded as a violation.
+func (v *violations) object(obj map[string]interface{}, parent, field string) (map[string]interface{}, bool) {
+	raw, present := obj[field]
+	if !present || raw == nil {
+		return nil, true
+	}
+	m, ok := raw.(map[string]interface{})
+	if !ok {
+		v.add(path(parent, field), CodeInvalidType, "%s must be an object", field)
+		return nil, false
+	}
+	return m, true
+}
+
+// array returns the array at obj[field], nil when missing or null
+// ok is false when the field holds another type, which is recorded as a violation.
+func (v *violations) array(obj map[string]interface{}, parent, field string) ([]interface{}, bool) {
+	raw, present := obj[field]
+	if !present || raw == nil {
+		return nil, true
+	}
+	a, ok := raw.([]interface{})
+	if !ok {
+		v.add(path(parent, field), CodeInvalidType, "%s must be an array", field)
+		return nil, false
+	}
+	return a, true
+}
+
+// stringList returns the array of strings at obj[field]
+// ok is false when the field or one of its items has another type.
+func (v *violations) stringList(obj map[string]interface{}, parent, field string) ([]interface{}, bool) {
+	a, ok := v.array(obj, parent, field)
+	if !ok {
+		return nil, false
+	}
+	for _, item := range a {
+		if _, isString := item.(string); !isString {
+			v.add(path(parent, field), CodeInvalidType, "%s must be an array of strings", field)
+			return nil, false
+		}
+	}
+	return a, true
+}
+
+// integer checks that obj[field], when set, is a whole number
+func (v *violations) integer(obj map[string]interface{}, parent, field string) {
+	raw, present := obj[field]
+	if !present || raw == nil {
+		return
+	}
+	n, ok := raw.(float64)
+	if !ok || n != math.Trunc(n) {
+		v.add(path(parent, field), CodeInvalidType, "%s must be an integer", field)
+	}
+}
+
+// boolean checks that obj[field], when set, is a boolean
+func (v *violations) boolean(obj map[string]interface{}, parent, field string) {
+	raw, present := obj[field]
+	if !present || raw == nil {
+		return
+	}
+	if _, ok := raw.(bool); !ok {
+		v.add(path(parent, field), CodeInvalidType, "%s must be a boolean", field)
+	}
+}
+
+// did checks that s, when set, is a DID
+func (v *violations) did(field, s string) {
+	if s == "" {
+		return
+	}
+	if _, err := syntax.ParseDID(s); err != nil {
+		v.add(field, CodeInvalidFormat, "%s must be a DID", field)
+	}
+}
+
+// atURIShape checks the structure of an AT-URI: at://did:method:id/collection/rkey
+// This is defensive validation - we trust the PDS but catch obviously malformed URIs.
+func atURIShape(uri string) error {
+	const scheme = "at://"
+	if !strings.HasPrefix(uri, scheme) {
+		return fmt.Errorf("must start with %s", scheme)
+	}
+
+	// Must have at least 3 parts: did, collection, rkey
+	parts := strings.Split(strings.TrimPrefix(uri, scheme), "/")
+	if len(parts) < 3 {
+		return fmt.Errorf("invalid structure (expected at://did/collection/rkey)")
+	}
+
+	// First part should be a DID
+	if !strings.HasPrefix(parts[0], "did:") {
+		return fmt.Errorf("repository identifier must be a DID")
+	}
+
+	// Collection and rkey should not be empty
+	if parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("collection and rkey cannot be empty")
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package validate
+
+import (
+	"Coves/internal/validation/text"
+
+	"github.com/rivo/uniseg"
+)
+
+// Post text limits from the social.coves.community.post lexicon
+// Enforced after sanitizing, so NFC growth counts.
+const (
+	MaxPostTitleBytes       = 3000
+	MaxPostTitleGraphemes   = 300
+	MaxPostContentBytes     = 100000
+	MaxPostContentGraphemes = 10000
+	MaxPostLangs            = 3
+	MaxPostTags             = 8
+)
+
+// ValidatePost checks a social.coves.community.post record
+// Whether the repository is the post's community is checked by the consumer, which knows
+// where the record was written.
+func ValidatePost(record map[string]interface{}) []Violation {
+	var v violations
+
+	v.requiredStr(record, "", "community")
+	author := v.requiredStr(record, "", "author")
+	v.did("author", author)
+	v.requiredStr(record, "", "createdAt")
+
+	v.text(record, "title", MaxPostTitleBytes, MaxPostTitleGraphemes)
+	v.text(record, "content", MaxPostContentBytes, MaxPostContentGraphemes)
+
+	if langs, ok := v.stringList(record, "", "langs"); ok && len(langs) > MaxPostLangs {
+		v.add("langs", CodeTooMany, "at most %d langs are allowed: got %d", MaxPostLangs, len(langs))
+	}
+	if tags, ok := v.stringList(record, "", "tags"); ok && len(tags) > MaxPostTags {
+		v.add("tags", CodeTooMany, "at most %d tags are allowed: got %d", MaxPostTags, len(tags))
+	}
+
+	v.array(record, "", "facets")
+	v.object(record, "", "embed")
+	v.object(record, "", "labels")
+
+	return v
+}
+
+// text checks an optional text field against its byte and grapheme limits, after sanitizing
+func (v *violations) text(record map[string]interface{}, field string, maxBytes, maxGraphemes int) {
+	s, ok := v.str(record, "", field)
+	if !ok || s == "" {
+		return
+	}
+	s = text.Sanitize(s)
+	if len(s) > maxBytes {
+		v.add(field, CodeTooLong, "%s exceeds maximum length (%d bytes): got %d bytes", field, maxBytes, len(s))
+		return
+	}
+	if graphemes := uniseg.GraphemeClusterCount(s); graphemes > maxGraphemes {
+		v.add(field, CodeTooLong, "%s exceeds maximum length (%d graphemes): got %d graphemes", field, maxGraphemes, graphemes)
+	}
+}
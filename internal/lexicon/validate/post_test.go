@@ -0,0 +1,144 @@
+package validate
+
+import (
+	"strings"
+	"testing"
+)
+
+func validPost() map[string]interface{} {
+	return map[string]interface{}{
+		"$type":     PostCollection,
+		"community": "did:plc:community",
+		"author":    "did:plc:author",
+		"title":     "Hello",
+		"content":   "World",
+		"tags":      []interface{}{"news"},
+		"langs":     []interface{}{"en"},
+		"embed":     map[string]interface{}{"$type": "social.coves.embed.external"},
+		"labels":    map[string]interface{}{"values": []interface{}{}},
+		"createdAt": "2025-01-01T00:00:00Z",
+	}
+}
+
+func stringList(n int) []interface{} {
+	list := make([]interface{}, n)
+	for i := range list {
+		list[i] = "x"
+	}
+	return list
+}
+
+func TestValidatePost(t *testing.T) {
+	runRuleCases(t, validPost, ValidatePost, []ruleCase{
+		{
+			name:   "unknown fields are ignored",
+			mutate: func(r map[string]interface{}) { r["futureFeature"] = []interface{}{1.0} },
+		},
+		{
+			name: "title and content are optional",
+			mutate: func(r map[string]interface{}) {
+				delete(r, "title")
+				delete(r, "content")
+			},
+		},
+		{
+			name:   "community may be a handle",
+			mutate: func(r map[string]interface{}) { r["community"] = "gaming.coves.social" },
+		},
+		{
+			name:   "missing community",
+			mutate: func(r map[string]interface{}) { delete(r, "community") },
+			want:   []string{"community:required"},
+		},
+		{
+			name:   "missing author",
+			mutate: func(r map[string]interface{}) { r["author"] = "" },
+			want:   []string{"author:required"},
+		},
+		{
+			name:   "author not a DID",
+			mutate: func(r map[string]interface{}) { r["author"] = "alice.coves.social" },
+			want:   []string{"author:invalid_format"},
+		},
+		{
+			name:   "missing createdAt",
+			mutate: func(r map[string]interface{}) { delete(r, "createdAt") },
+			want:   []string{"createdAt:required"},
+		},
+		{
+			name:   "title not a string",
+			mutate: func(r map[string]interface{}) { r["title"] = true },
+			want:   []string{"title:invalid_type"},
+		},
+		{
+			name:   "title at the grapheme limit",
+			mutate: func(r map[string]interface{}) { r["title"] = strings.Repeat("a", MaxPostTitleGraphemes) },
+		},
+		{
+			name:   "title over the grapheme limit",
+			mutate: func(r map[string]interface{}) { r["title"] = strings.Repeat("a", MaxPostTitleGraphemes+1) },
+			want:   []string{"title:too_long"},
+		},
+		{
+			name:   "title over the byte limit",
+			mutate: func(r map[string]interface{}) { r["title"] = strings.Repeat("a", MaxPostTitleBytes+1) },
+			want:   []string{"title:too_long"},
+		},
+		{
+			name: "grapheme clusters count once",
+			mutate: func(r map[string]interface{}) {
+				// x and a combining acute accent have no precomposed form, so stay two code points
+				r["title"] = strings.Repeat("x\u0301", MaxPostTitleGraphemes)
+			},
+		},
+		{
+			name:   "content over the grapheme limit",
+			mutate: func(r map[string]interface{}) { r["content"] = strings.Repeat("a", MaxPostContentGraphemes+1) },
+			want:   []string{"content:too_long"},
+		},
+		{
+			name:   "content over the byte limit",
+			mutate: func(r map[string]interface{}) { r["content"] = strings.Repeat("\U0001F600", MaxPostContentBytes/4+1) },
+			want:   []string{"content:too_long"},
+		},
+		{
+			name:   "forbidden characters don't count",
+			mutate: func(r map[string]interface{}) { r["title"] = strings.Repeat("a\u202e", MaxPostTitleGraphemes) },
+		},
+		{
+			name:   "too many langs",
+			mutate: func(r map[string]interface{}) { r["langs"] = stringList(MaxPostLangs + 1) },
+			want:   []string{"langs:too_many"},
+		},
+		{
+			name:   "langs not an array",
+			mutate: func(r map[string]interface{}) { r["langs"] = "en" },
+			want:   []string{"langs:invalid_type"},
+		},
+		{
+			name:   "too many tags",
+			mutate: func(r map[string]interface{}) { r["tags"] = stringList(MaxPostTags + 1) },
+			want:   []string{"tags:too_many"},
+		},
+		{
+			name:   "tags not strings",
+			mutate: func(r map[string]interface{}) { r["tags"] = []interface{}{map[string]interface{}{}} },
+			want:   []string{"tags:invalid_type"},
+		},
+		{
+			name:   "facets not an array",
+			mutate: func(r map[string]interface{}) { r["facets"] = "link" },
+			want:   []string{"facets:invalid_type"},
+		},
+		{
+			name:   "embed not an object",
+			mutate: func(r map[string]interface{}) { r["embed"] = []interface{}{} },
+			want:   []string{"embed:invalid_type"},
+		},
+		{
+			name:   "labels not an object",
+			mutate: func(r map[string]interface{}) { r["labels"] = []interface{}{"nsfw"} },
+			want:   []string{"labels:invalid_type"},
+		},
+	})
+}
@@ -0,0 +1,117 @@
+// Package validate checks Coves records against the rules of their lexicon.
+//
+// Records reach the AppView from PDSes we don't control (Jetstream, backfills) and are built
+// by our own write handlers before they are written to a PDS. Both sides validate with the
+// same per-collection functions, so a record the write path accepts is one the consumers
+// index, and a record the consumers reject is rejected for the same reason everywhere.
+//
+// Validators return every violation they find rather than stopping at the first, so API
+// clients can fix all fields at once and dead-lettered events say everything that was wrong.
+package validate
+
+import (
+	coreerrors "Coves/internal/core/errors"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RulesVersion identifies the rule set records are validated with
+// It is included in every error so dead-lettered events say which rules rejected them, and
+// events rejected by an older version can be found and replayed after the rules change.
+//
+//	v1: checks moved from the Jetstream consumers and the vote indexer, unchanged
+//	v2: posts require a DID author and enforce the lexicon's grapheme, langs and tags limits;
+//	    subscriptions require a DID subject and createdAt
+//	v3: community profiles list at most three categories, as strings
+const RulesVersion = 3
+
+// Collections with validators
+const (
+	CommentCollection          = "social.coves.community.comment"
+	PostCollection             = "social.coves.community.post"
+	CommunityProfileCollection = "social.coves.community.profile"
+	SubscriptionCollection     = "social.coves.community.subscription"
+	VoteCollection             = "social.coves.feed.vote"
+)
+
+// Violation codes
+const (
+	CodeRequired      = "required"
+	CodeInvalidType   = "invalid_type"
+	CodeInvalidFormat = "invalid_format"
+	CodeInvalidURI    = "invalid_uri"
+	CodeInvalidValue  = "invalid_value"
+	CodeTooLong       = "too_long"
+	CodeTooMany       = "too_many"
+)
+
+// Violation is one rule a record breaks
+// Field is the dotted path of the offending field (reply.root.uri), Code one of the Code
+// constants, and Message a human-readable explanation.
+type Violation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error reports the violations of an invalid record
+// It matches coreerrors.ErrInvalidInput, so handlers without a specific mapping answer 400.
+type Error struct {
+	Collection string
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = fmt.Sprintf("%s: %s: %s", v.Field, v.Code, v.Message)
+	}
+	return fmt.Sprintf("invalid %s record (rules v%d): %s", e.Collection, RulesVersion, strings.Join(parts, "; "))
+}
+
+// Is reports whether target is the invalid input error kind
+func (e *Error) Is(target error) bool {
+	return target == coreerrors.ErrInvalidInput
+}
+
+// Check returns an *Error for the violations of a collection's record, or nil if there are none
+func Check(collection string, violations []Violation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	return &Error{Collection: collection, Violations: violations}
+}
+
+// validators maps each collection to its validator
+var validators = map[string]func(map[string]interface{}) []Violation{
+	CommentCollection:          ValidateComment,
+	PostCollection:             ValidatePost,
+	CommunityProfileCollection: ValidateCommunityProfile,
+	SubscriptionCollection:     ValidateSubscription,
+	VoteCollection:             ValidateVote,
+}
+
+// Record validates a decoded record written to collection
+// Collections without a validator are accepted.
+func Record(collection string, record map[string]interface{}) error {
+	validator, ok := validators[collection]
+	if !ok {
+		return nil
+	}
+	return Check(collection, validator(record))
+}
+
+// Value validates a record the AppView is about to write, such as a struct or a map of Go
+// values, by validating its JSON form exactly as a consumer will decode it
+func Value(collection string, record interface{}) error {
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s record: %w", collection, err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return fmt.Errorf("failed to decode %s record: %w", collection, err)
+	}
+	return Record(collection, decoded)
+}
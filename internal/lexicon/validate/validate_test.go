@@ -0,0 +1,107 @@
+package validate
+
+import (
+	coreerrors "Coves/internal/core/errors"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ruleCase is one record mutation and the violations it must produce, as "field:code"
+type ruleCase struct {
+	mutate func(record map[string]interface{})
+	name   string
+	want   []string
+}
+
+// runRuleCases applies each case to a fresh valid record and checks the violations found
+func runRuleCases(t *testing.T, valid func() map[string]interface{}, validator func(map[string]interface{}) []Violation, cases []ruleCase) {
+	t.Helper()
+
+	if got := validator(valid()); len(got) != 0 {
+		t.Fatalf("Expected the valid record to pass, got: %v", got)
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			record := valid()
+			tt.mutate(record)
+
+			got := make([]string, 0)
+			for _, v := range validator(record) {
+				if v.Message == "" {
+					t.Errorf("Violation %s:%s has no message", v.Field, v.Code)
+				}
+				got = append(got, v.Field+":"+v.Code)
+			}
+			want := tt.want
+			if want == nil {
+				want = []string{}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected violations %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func TestError(t *testing.T) {
+	err := Check(CommentCollection, []Violation{
+		{Field: "content", Code: CodeRequired, Message: "content is required"},
+		{Field: "reply.root.cid", Code: CodeRequired, Message: "cid is required"},
+	})
+
+	want := fmt.Sprintf("invalid social.coves.community.comment record (rules v%d): content: required: content is required; reply.root.cid: required: cid is required", RulesVersion)
+	if err.Error() != want {
+		t.Errorf("Expected %q, got %q", want, err.Error())
+	}
+	if !errors.Is(err, coreerrors.ErrInvalidInput) {
+		t.Error("Expected the error to match ErrInvalidInput")
+	}
+	var verr *Error
+	if !errors.As(fmt.Errorf("wrapped: %w", err), &verr) || len(verr.Violations) != 2 {
+		t.Errorf("Expected the violations to be reachable through wrapping, got %v", verr)
+	}
+
+	if err := Check(CommentCollection, nil); err != nil {
+		t.Errorf("Expected no error without violations, got: %v", err)
+	}
+}
+
+func TestRecord_DispatchesByCollection(t *testing.T) {
+	empty := map[string]interface{}{}
+
+	for _, collection := range []string{CommentCollection, PostCollection, SubscriptionCollection, VoteCollection} {
+		err := Record(collection, empty)
+		var verr *Error
+		if !errors.As(err, &verr) || verr.Collection != collection {
+			t.Errorf("Expected %s violations for an empty record, got: %v", collection, err)
+		}
+	}
+
+	if err := Record("social.coves.community.block", empty); err != nil {
+		t.Errorf("Expected collections without a validator to be accepted, got: %v", err)
+	}
+}
+
+func TestValue_ValidatesTheJSONForm(t *testing.T) {
+	type subscription struct {
+		Subject   string `json:"subject"`
+		CreatedAt string `json:"createdAt"`
+	}
+
+	if err := Value(SubscriptionCollection, subscription{Subject: "did:plc:community", CreatedAt: "2025-01-01T00:00:00Z"}); err != nil {
+		t.Errorf("Expected a valid struct to pass, got: %v", err)
+	}
+
+	err := Value(SubscriptionCollection, subscription{Subject: "gaming.coves.social"})
+	if err == nil || !strings.Contains(err.Error(), "subject: invalid_format") || !strings.Contains(err.Error(), "createdAt: required") {
+		t.Errorf("Expected subject and createdAt violations, got: %v", err)
+	}
+
+	if err := Value(SubscriptionCollection, make(chan int)); err == nil || errors.As(err, new(*Error)) {
+		t.Errorf("Expected an encoding error for an unencodable value, got: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package validate
+
+import (
+	"Coves/internal/atproto/aturi"
+)
+
+// ValidateVote checks a social.coves.feed.vote record
+// Whether the record lives in the voter's repository is checked by the vote indexer.
+func ValidateVote(record map[string]interface{}) []Violation {
+	var v violations
+
+	direction, ok := v.str(record, "", "direction")
+	if ok && direction != "up" && direction != "down" {
+		v.add("direction", CodeInvalidValue, "direction must be 'up' or 'down': got %q", direction)
+	}
+
+	// Subjects are strong references: both URI and CID are required
+	subject, ok := v.object(record, "", "subject")
+	switch {
+	case !ok:
+	case subject == nil:
+		v.add("subject", CodeRequired, "subject is required")
+	default:
+		uri := v.requiredStr(subject, "subject", "uri")
+		v.requiredStr(subject, "subject", "cid")
+		if uri != "" {
+			if _, err := aturi.Parse(uri); err != nil {
+				v.add("subject.uri", CodeInvalidURI, "%v", err)
+			}
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,79 @@
+package validate
+
+import (
+	"testing"
+)
+
+func validVote() map[string]interface{} {
+	return map[string]interface{}{
+		"$type":     VoteCollection,
+		"subject":   map[string]interface{}{"uri": "at://did:plc:community/social.coves.community.post/3kpost", "cid": "bafypost"},
+		"direction": "up",
+		"createdAt": "2025-01-01T00:00:00Z",
+	}
+}
+
+func TestValidateVote(t *testing.T) {
+	runRuleCases(t, validVote, ValidateVote, []ruleCase{
+		{
+			name:   "downvote",
+			mutate: func(r map[string]interface{}) { r["direction"] = "down" },
+		},
+		{
+			name: "subject URI in a non-canonical form",
+			mutate: func(r map[string]interface{}) {
+				r["subject"] = map[string]interface{}{"uri": "AT://did:plc:community/social.coves.community.post/3kpost/", "cid": "bafypost"}
+			},
+		},
+		{
+			name:   "unparseable createdAt is tolerated",
+			mutate: func(r map[string]interface{}) { r["createdAt"] = "yesterday" },
+		},
+		{
+			name:   "invalid direction",
+			mutate: func(r map[string]interface{}) { r["direction"] = "sideways" },
+			want:   []string{"direction:invalid_value"},
+		},
+		{
+			name:   "missing direction",
+			mutate: func(r map[string]interface{}) { delete(r, "direction") },
+			want:   []string{"direction:invalid_value"},
+		},
+		{
+			name:   "direction not a string",
+			mutate: func(r map[string]interface{}) { r["direction"] = 1.0 },
+			want:   []string{"direction:invalid_type"},
+		},
+		{
+			name:   "missing subject",
+			mutate: func(r map[string]interface{}) { delete(r, "subject") },
+			want:   []string{"subject:required"},
+		},
+		{
+			name: "subject not an object",
+			mutate: func(r map[string]interface{}) {
+				r["subject"] = "at://did:plc:community/social.coves.community.post/3kpost"
+			},
+			want: []string{"subject:invalid_type"},
+		},
+		{
+			name: "subject without CID",
+			mutate: func(r map[string]interface{}) {
+				r["subject"] = map[string]interface{}{"uri": "at://did:plc:community/social.coves.community.post/3kpost"}
+			},
+			want: []string{"subject.cid:required"},
+		},
+		{
+			name:   "subject without URI",
+			mutate: func(r map[string]interface{}) { r["subject"] = map[string]interface{}{"cid": "bafypost"} },
+			want:   []string{"subject.uri:required"},
+		},
+		{
+			name: "subject is not an AT-URI",
+			mutate: func(r map[string]interface{}) {
+				r["subject"] = map[string]interface{}{"uri": "https://example.com/post", "cid": "bafypost"}
+			},
+			want: []string{"subject.uri:invalid_uri"},
+		},
+	})
+}
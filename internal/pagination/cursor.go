@@ -0,0 +1,182 @@
+// Package pagination encrypts and verifies opaque pagination cursors.
+//
+// Cursors carry keyset values (timestamps, scores, URIs) that repositories splice into
+// SQL filters, so they are authenticated to stop clients crafting arbitrary ones. They are
+// also encrypted: a score cursor issued inside a community's score hiding window must not
+// reveal the hidden score.
+//
+// Wire format (base64url, unpadded):
+//
+//	keyID (1 byte) | nonce (12 bytes) | AES-256-GCM(key, nonce, payload, keyID)
+//
+// The payload is a list of uvarint length-prefixed fields. The key ID names the secret
+// that sealed the cursor, so CURSOR_SECRET can be rotated without breaking in-flight
+// cursors: new cursors are always sealed with the current secret, and cursors sealed
+// with CURSOR_SECRET_PREVIOUS keep verifying until that secret is removed.
+package pagination
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// MaxCursorLength bounds encoded cursors to prevent DoS via huge cursor strings
+const MaxCursorLength = 1024
+
+// nonceSize and tagSize are the AES-GCM nonce and authentication tag lengths
+const (
+	nonceSize = 12
+	tagSize   = 16
+)
+
+var (
+	// ErrInvalidCursor is returned for malformed, tampered, or unknown-key cursors
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrMissingSecret is returned when a signer is created without a current secret
+	ErrMissingSecret = errors.New("cursor secret is required")
+)
+
+// signingKey is a cursor secret's AEAD with its derived key ID
+type signingKey struct {
+	aead cipher.AEAD
+	id   byte
+}
+
+// Signer seals and opens encrypted cursors
+// Safe for concurrent use; it holds no mutable state after construction
+type Signer struct {
+	keys    map[byte][]signingKey
+	current signingKey
+}
+
+// NewSigner creates a cursor signer
+// secret seals new cursors; previousSecret (optional) is only accepted for verification
+// and should be removed once cursors sealed with it have expired from clients
+func NewSigner(secret, previousSecret string) (*Signer, error) {
+	if secret == "" {
+		return nil, ErrMissingSecret
+	}
+
+	current, err := newSigningKey(secret)
+	if err != nil {
+		return nil, err
+	}
+	s := &Signer{
+		current: current,
+		keys:    map[byte][]signingKey{current.id: {current}},
+	}
+
+	if previousSecret != "" && previousSecret != secret {
+		previous, err := newSigningKey(previousSecret)
+		if err != nil {
+			return nil, err
+		}
+		// Key IDs are one byte, so two secrets can collide. Both stay under the same ID and
+		// verification tries at most two keys, which keeps lookup O(1).
+		s.keys[previous.id] = append(s.keys[previous.id], previous)
+	}
+
+	return s, nil
+}
+
+// newSigningKey derives an AES-256 key from the secret
+func newSigningKey(secret string) (signingKey, error) {
+	key := sha256.Sum256([]byte("coves-cursor-key:" + secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return signingKey{}, fmt.Errorf("failed to create cursor cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return signingKey{}, fmt.Errorf("failed to create cursor cipher: %w", err)
+	}
+	return signingKey{aead: aead, id: keyID(secret)}, nil
+}
+
+// keyID derives a key ID from the secret itself so that the ID stays stable when the secret
+// moves from CURSOR_SECRET to CURSOR_SECRET_PREVIOUS
+func keyID(secret string) byte {
+	sum := sha256.Sum256([]byte("coves-cursor-key-id:" + secret))
+	return sum[0]
+}
+
+// Encode seals fields with the current secret and returns the cursor string
+func (s *Signer) Encode(fields ...string) string {
+	var payload []byte
+	for _, field := range fields {
+		payload = binary.AppendUvarint(payload, uint64(len(field)))
+		payload = append(payload, field...)
+	}
+
+	buf := make([]byte, 1+nonceSize, 1+nonceSize+len(payload)+tagSize)
+	buf[0] = s.current.id
+	if _, err := rand.Read(buf[1:]); err != nil {
+		// crypto/rand only fails when the OS entropy source is unusable
+		panic(fmt.Sprintf("pagination: failed to read nonce: %v", err))
+	}
+	buf = s.current.aead.Seal(buf, buf[1:], payload, buf[:1])
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// Decode verifies and decrypts a cursor and returns its fields
+// All failures wrap ErrInvalidCursor
+func (s *Signer) Decode(cursor string) ([]string, error) {
+	if len(cursor) > MaxCursorLength {
+		return nil, fmt.Errorf("%w: exceeds maximum length", ErrInvalidCursor)
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid encoding", ErrInvalidCursor)
+	}
+	if len(raw) < 1+nonceSize+tagSize {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidCursor)
+	}
+
+	payload, ok := s.open(raw)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid signature", ErrInvalidCursor)
+	}
+
+	fields, err := decodeFields(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCursor, err)
+	}
+	return fields, nil
+}
+
+// open decrypts raw with the secrets registered for its key ID
+func (s *Signer) open(raw []byte) ([]byte, bool) {
+	keyID, nonce, sealed := raw[:1], raw[1:1+nonceSize], raw[1+nonceSize:]
+	for _, key := range s.keys[keyID[0]] {
+		if payload, err := key.aead.Open(nil, nonce, sealed, keyID); err == nil {
+			return payload, true
+		}
+	}
+	return nil, false
+}
+
+// decodeFields parses uvarint length-prefixed fields
+func decodeFields(payload []byte) ([]string, error) {
+	var fields []string
+	for len(payload) > 0 {
+		length, n := binary.Uvarint(payload)
+		if n <= 0 {
+			return nil, errors.New("malformed field length")
+		}
+		payload = payload[n:]
+		if length > uint64(len(payload)) {
+			return nil, errors.New("truncated field")
+		}
+		fields = append(fields, string(payload[:length]))
+		payload = payload[length:]
+	}
+	return fields, nil
+}
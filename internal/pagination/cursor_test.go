@@ -0,0 +1,200 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func mustSigner(t testing.TB, secret, previous string) *Signer {
+	t.Helper()
+	s, err := NewSigner(secret, previous)
+	if err != nil {
+		t.Fatalf("NewSigner failed: %v", err)
+	}
+	return s
+}
+
+func TestNewSigner_RequiresSecret(t *testing.T) {
+	if _, err := NewSigner("", "old-secret"); !errors.Is(err, ErrMissingSecret) {
+		t.Errorf("Expected ErrMissingSecret, got %v", err)
+	}
+}
+
+func TestSigner_RoundTrip(t *testing.T) {
+	s := mustSigner(t, "secret", "")
+
+	tests := []struct {
+		name   string
+		fields []string
+	}{
+		{name: "no fields", fields: nil},
+		{name: "feed new", fields: []string{"2025-11-06T12:00:00.123456789Z", "at://did:plc:c/social.coves.community.post/3k"}},
+		{name: "comment hot", fields: []string{"0.123456", "42", "2025-11-06T12:00:00Z", "at://did:plc:a/social.coves.community.comment/3k"}},
+		{name: "empty field", fields: []string{"", "x"}},
+		{name: "old delimiters in values", fields: []string{"a::b", "c|d"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor := s.Encode(tt.fields...)
+			got, err := s.Decode(cursor)
+			if err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if len(got) != len(tt.fields) || (len(got) > 0 && !reflect.DeepEqual(got, tt.fields)) {
+				t.Errorf("Expected %q, got %q", tt.fields, got)
+			}
+		})
+	}
+}
+
+func TestSigner_RejectsTamperedCursors(t *testing.T) {
+	s := mustSigner(t, "secret", "")
+	cursor := s.Encode("2025-11-06T12:00:00Z", "at://did:plc:c/social.coves.community.post/3k")
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		t.Fatalf("Failed to decode cursor: %v", err)
+	}
+
+	flip := func(i int) string {
+		b := append([]byte(nil), raw...)
+		b[i] ^= 0x01
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "flipped key ID", cursor: flip(0)},
+		{name: "flipped nonce byte", cursor: flip(5)},
+		{name: "flipped payload byte", cursor: flip(1 + nonceSize)},
+		{name: "flipped tag byte", cursor: flip(len(raw) - 1)},
+		{name: "truncated tag", cursor: base64.RawURLEncoding.EncodeToString(raw[:len(raw)-1])},
+		{name: "tag only", cursor: base64.RawURLEncoding.EncodeToString(raw[len(raw)-tagSize:])},
+		{name: "empty", cursor: ""},
+		{name: "invalid base64", cursor: "not-base64!!!"},
+		{name: "padded base64", cursor: cursor + "=="},
+		{name: "legacy feed cursor", cursor: base64.StdEncoding.EncodeToString([]byte("2025-01-01T00:00:00Z::at://x::deadbeef"))},
+		{name: "too long", cursor: strings.Repeat("A", MaxCursorLength+1)},
+		{name: "signed with another secret", cursor: mustSigner(t, "other", "").Encode("2025-11-06T12:00:00Z", "at://x")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.Decode(tt.cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("Expected ErrInvalidCursor, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSigner_HidesFields(t *testing.T) {
+	s := mustSigner(t, "secret", "")
+	first := s.Encode("top", "day", "1234", "at://did:plc:c/social.coves.community.post/3k")
+	raw, err := base64.RawURLEncoding.DecodeString(first)
+	if err != nil {
+		t.Fatalf("Failed to decode cursor: %v", err)
+	}
+	if strings.Contains(string(raw), "1234") || strings.Contains(string(raw), "did:plc:c") {
+		t.Errorf("Expected cursor fields to be encrypted, got %q", raw)
+	}
+	if first == s.Encode("top", "day", "1234", "at://did:plc:c/social.coves.community.post/3k") {
+		t.Error("Expected a fresh nonce per cursor")
+	}
+}
+
+func TestSigner_Rotation(t *testing.T) {
+	before := mustSigner(t, "old-secret", "")
+	during := mustSigner(t, "new-secret", "old-secret")
+	after := mustSigner(t, "new-secret", "")
+
+	oldCursor := before.Encode("page", "1")
+
+	t.Run("old cursor accepted while previous secret is configured", func(t *testing.T) {
+		fields, err := during.Decode(oldCursor)
+		if err != nil {
+			t.Fatalf("Expected old cursor to verify, got %v", err)
+		}
+		if !reflect.DeepEqual(fields, []string{"page", "1"}) {
+			t.Errorf("Unexpected fields %q", fields)
+		}
+	})
+
+	t.Run("next page is signed with the current secret", func(t *testing.T) {
+		next := during.Encode("page", "2")
+		if _, err := after.Decode(next); err != nil {
+			t.Errorf("Expected re-signed cursor to verify once the previous secret is removed, got %v", err)
+		}
+		if _, err := before.Decode(next); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected re-signed cursor not to verify with the old secret, got %v", err)
+		}
+	})
+
+	t.Run("old cursor rejected once previous secret is removed", func(t *testing.T) {
+		if _, err := after.Decode(oldCursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("Expected ErrInvalidCursor, got %v", err)
+		}
+	})
+
+	t.Run("key ID is stable across rotation", func(t *testing.T) {
+		if keyID("old-secret") != before.current.id {
+			t.Error("Expected key ID to be derived from the secret")
+		}
+	})
+}
+
+func TestSigner_KeyIDCollision(t *testing.T) {
+	// Find a previous secret whose key ID collides with the current one
+	current := keyID("current")
+	previous := ""
+	for i := 0; previous == ""; i++ {
+		candidate := "previous-" + strings.Repeat("x", i)
+		if keyID(candidate) == current {
+			previous = candidate
+		}
+	}
+
+	s := mustSigner(t, "current", previous)
+	if len(s.keys[current]) != 2 {
+		t.Fatalf("Expected both secrets under key ID %d, got %d", current, len(s.keys[current]))
+	}
+
+	for _, secret := range []string{"current", previous} {
+		cursor := mustSigner(t, secret, "").Encode("x")
+		if _, err := s.Decode(cursor); err != nil {
+			t.Errorf("Expected cursor signed with %q to verify, got %v", secret, err)
+		}
+	}
+}
+
+func FuzzSignerDecode(f *testing.F) {
+	s := mustSigner(f, "fuzz-secret", "fuzz-previous")
+	f.Add(s.Encode("2025-11-06T12:00:00Z", "at://did:plc:c/social.coves.community.post/3k"))
+	f.Add(s.Encode())
+	f.Add(mustSigner(f, "fuzz-previous", "").Encode("a", "b", "c"))
+	f.Add("")
+	f.Add("AAAA")
+	f.Add(base64.StdEncoding.EncodeToString([]byte("2025-01-01T00:00:00Z::at://x::sig")))
+
+	f.Fuzz(func(t *testing.T, cursor string) {
+		fields, err := s.Decode(cursor)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("Decode error must wrap ErrInvalidCursor, got %v", err)
+			}
+			return
+		}
+		// Anything that verifies must re-encode to a cursor with the same fields
+		again, err := s.Decode(s.Encode(fields...))
+		if err != nil {
+			t.Fatalf("Re-encoded cursor failed to decode: %v", err)
+		}
+		if len(again) != len(fields) || (len(fields) > 0 && !reflect.DeepEqual(again, fields)) {
+			t.Fatalf("Round trip changed fields: %q -> %q", fields, again)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+// Package sanitize makes user-supplied text safe to hand to clients for rendering
+package sanitize
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// MaxHeadingDepth is the deepest markdown heading kept; deeper ATX headings are flattened to it
+// so community documents can't produce a wall of tiny headings that mimics the app's own UI
+const MaxHeadingDepth = 3
+
+var (
+	// Elements whose content is dangerous or meaningless once the tags are gone
+	// Go's regexp has no backreferences, so each element gets its own pattern
+	dangerousElements = func() []*regexp.Regexp {
+		names := []string{"script", "style", "iframe", "object", "embed", "noscript", "template", "textarea", "title", "svg", "math"}
+		patterns := make([]*regexp.Regexp, 0, len(names))
+		for _, name := range names {
+			// An unclosed element swallows the rest of the document, as a browser would
+			patterns = append(patterns, regexp.MustCompile(`(?is)<`+name+`\b.*?(?:</`+name+`\s*>|$)`))
+		}
+		return patterns
+	}()
+
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?(?:-->|$)`)
+
+	// Any remaining tag, declaration or processing instruction, including unterminated ones
+	htmlTagPattern = regexp.MustCompile(`(?s)<[/!?]?[a-zA-Z][^<>]*(?:>|$)`)
+
+	// CommonMark autolinks (<https://example.com>) look like tags but are kept when safe
+	autolinkPattern = regexp.MustCompile(`^<([a-zA-Z][a-zA-Z0-9+.\-]{1,31}):[^\s<>]*>$`)
+
+	fencePattern       = regexp.MustCompile("^ {0,3}(```+|~~~+)")
+	deepHeadingPattern = regexp.MustCompile(`^( {0,3})#{4,6}([ \t]|$)`)
+
+	// Inline link and image destinations: [text](destination "title"), allowing one level of
+	// balanced parentheses inside the destination as CommonMark does
+	inlineLinkPattern = regexp.MustCompile(`\]\(((?:[^()]|\([^()]*\))*)\)`)
+
+	// Link reference definitions: [label]: destination
+	referenceDefinitionPattern = regexp.MustCompile(`^ {0,3}\[[^\]]+\]:[ \t]*(\S+)`)
+
+	// Whitespace and control characters browsers ignore inside a URL scheme
+	schemeNoisePattern = regexp.MustCompile(`[\x00-\x20\x7f]+`)
+)
+
+// unsafeSchemes are link schemes that execute code or embed content when followed
+var unsafeSchemes = []string{"javascript:", "vbscript:", "data:", "file:"}
+
+// Markdown sanitizes a markdown document for rendering by clients:
+//   - raw HTML is stripped (script/style and similar elements with their content)
+//   - headings deeper than MaxHeadingDepth are flattened to MaxHeadingDepth
+//   - links and autolinks using javascript:, vbscript:, data: or file: schemes are removed
+//
+// Fenced code blocks are left untouched since renderers display them as literal text
+// The result is always no longer than the input
+func Markdown(input string) string {
+	text := strings.ReplaceAll(input, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	text = strings.ReplaceAll(text, "\x00", "")
+
+	var out strings.Builder
+	out.Grow(len(text))
+
+	var prose []string
+	flushProse := func() {
+		if len(prose) > 0 {
+			out.WriteString(sanitizeProse(strings.Join(prose, "\n")))
+			out.WriteString("\n")
+			prose = prose[:0]
+		}
+	}
+
+	lines := strings.Split(text, "\n")
+	fence := ""
+	for _, line := range lines {
+		if fence != "" {
+			out.WriteString(line)
+			out.WriteString("\n")
+			if strings.HasPrefix(strings.TrimLeft(line, " "), fence) {
+				fence = ""
+			}
+			continue
+		}
+		if m := fencePattern.FindStringSubmatch(line); m != nil {
+			flushProse()
+			fence = m[1]
+			out.WriteString(line)
+			out.WriteString("\n")
+			continue
+		}
+		prose = append(prose, line)
+	}
+	flushProse()
+
+	return strings.TrimSpace(out.String())
+}
+
+// sanitizeProse sanitizes markdown outside fenced code blocks
+// HTML is stripped across line boundaries before headings and links are handled per line
+func sanitizeProse(text string) string {
+	for _, pattern := range dangerousElements {
+		text = pattern.ReplaceAllString(text, "")
+	}
+	text = htmlCommentPattern.ReplaceAllString(text, "")
+	text = htmlTagPattern.ReplaceAllStringFunc(text, func(tag string) string {
+		if m := autolinkPattern.FindStringSubmatch(tag); m != nil && !isUnsafeURL(m[1]+":") {
+			return tag
+		}
+		return ""
+	})
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = deepHeadingPattern.ReplaceAllString(line, "${1}"+strings.Repeat("#", MaxHeadingDepth)+"${2}")
+
+		if m := referenceDefinitionPattern.FindStringSubmatch(line); m != nil && isUnsafeURL(m[1]) {
+			line = ""
+		}
+
+		line = inlineLinkPattern.ReplaceAllStringFunc(line, func(link string) string {
+			destination := inlineLinkPattern.FindStringSubmatch(link)[1]
+			if isUnsafeURL(destination) {
+				return "]()"
+			}
+			return link
+		})
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// isUnsafeURL reports whether a link destination uses an unsafe scheme
+// Entities are decoded and whitespace removed first, since renderers and browsers do the same
+// ("&#106;avascript:" and "java\tscript:" are both javascript: links)
+func isUnsafeURL(destination string) bool {
+	normalized := strings.TrimPrefix(strings.TrimSpace(destination), "<")
+	normalized = schemeNoisePattern.ReplaceAllString(html.UnescapeString(normalized), "")
+	normalized = strings.ToLower(normalized)
+	for _, scheme := range unsafeSchemes {
+		if strings.HasPrefix(normalized, scheme) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMarkdown_StripsHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "script with content", input: "Hello<script>alert('x')</script> world", want: "Hello world"},
+		{name: "uppercase script across lines", input: "Hi\n<SCRIPT type=\"text/javascript\">\nsteal()\n</SCRIPT>\nbye", want: "Hi\n\nbye"},
+		{name: "unclosed script swallows the rest", input: "Rules\n<script>alert(1)\n# More", want: "Rules"},
+		{name: "style block", input: "<style>body{display:none}</style>Text", want: "Text"},
+		{name: "iframe", input: "<iframe src=\"https://evil.example\"></iframe>Text", want: "Text"},
+		{name: "event handler attributes", input: "<img src=x onerror=alert(1)>Text", want: "Text"},
+		{name: "formatting tags keep their text", input: "<b>Be</b> <i>kind</i>", want: "Be kind"},
+		{name: "html comment", input: "Visible<!-- <script>alert(1)</script> -->Text", want: "VisibleText"},
+		{name: "tag split across lines", input: "<a\nhref=\"javascript:alert(1)\">click</a>", want: "click"},
+		{name: "comparison operators are not tags", input: "Posts with score < 5 or > 10", want: "Posts with score < 5 or > 10"},
+		{name: "safe autolink kept", input: "See <https://coves.social/rules>", want: "See <https://coves.social/rules>"},
+		{name: "javascript autolink removed", input: "See <javascript:alert(1)>", want: "See"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.input); got != tt.want {
+				t.Errorf("Markdown(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdown_HeadingDepth(t *testing.T) {
+	input := "# Title\n## Section\n### Sub\n#### Deep\n###### Deepest\n####not a heading"
+	want := "# Title\n## Section\n### Sub\n### Deep\n### Deepest\n####not a heading"
+
+	if got := Markdown(input); got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdown_UnsafeLinks(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "javascript link", input: "[click](javascript:alert(1))", want: "[click]()"},
+		{name: "mixed case and whitespace", input: "[click]( JaVa\tScript:alert(1))", want: "[click]()"},
+		{name: "entity encoded scheme", input: "[click](&#106;avascript:alert(1))", want: "[click]()"},
+		{name: "data image", input: "![x](data:image/svg+xml;base64,PHN2Zz4=)", want: "![x]()"},
+		{name: "reference definition", input: "[click][1]\n\n[1]: javascript:alert(1)", want: "[click][1]"},
+		{name: "https link kept", input: "[rules](https://coves.social/rules)", want: "[rules](https://coves.social/rules)"},
+		{name: "relative link kept", input: "[wiki](/c/gaming/wiki)", want: "[wiki](/c/gaming/wiki)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Markdown(tt.input); got != tt.want {
+				t.Errorf("Markdown(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkdown_FencedCodeUntouched(t *testing.T) {
+	input := "Example:\n```html\n<script>alert(1)</script>\n#### not a heading\n```\n<b>after</b>"
+	want := "Example:\n```html\n<script>alert(1)</script>\n#### not a heading\n```\nafter"
+
+	if got := Markdown(input); got != want {
+		t.Errorf("Markdown() = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdown_NeverGrows(t *testing.T) {
+	input := strings.Repeat("#### Rule\r\n<p>text</p>\r\n", 50)
+	if got := Markdown(input); len(got) > len(input) {
+		t.Errorf("Expected sanitized output (%d bytes) to be no longer than input (%d bytes)", len(got), len(input))
+	}
+}
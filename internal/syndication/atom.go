@@ -0,0 +1,76 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// AtomContentType is the Content-Type of RenderAtom output
+const AtomContentType = "application/atom+xml; charset=utf-8"
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Link      atomLink    `xml:"link"`
+	Author    *atomAuthor `xml:"author,omitempty"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Summary   string      `xml:"summary,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// RenderAtom renders the feed as an Atom 1.0 document
+func RenderAtom(feed *Feed) ([]byte, error) {
+	updated := feed.latest()
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	doc := atomFeed{
+		ID:       feed.ID,
+		Title:    feed.Title,
+		Subtitle: feed.Description,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Links:    []atomLink{{Href: feed.Link, Rel: "alternate", Type: "text/html"}},
+		Entries:  make([]atomEntry, 0, len(feed.Items)),
+	}
+	if feed.SelfLink != "" {
+		doc.Links = append(doc.Links, atomLink{Href: feed.SelfLink, Rel: "self", Type: "application/atom+xml"})
+	}
+
+	for _, item := range feed.Items {
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Link:      atomLink{Href: item.Link, Rel: "alternate"},
+			Published: item.Published.UTC().Format(time.RFC3339),
+			Updated:   item.modified().UTC().Format(time.RFC3339),
+			Summary:   item.Excerpt,
+		}
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return encode(doc)
+}
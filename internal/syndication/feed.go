@@ -0,0 +1,69 @@
+// Package syndication renders RSS 2.0 and Atom feeds for readers that follow
+// communities and the front page outside the app
+package syndication
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ExcerptLength is the maximum length of an item excerpt, in characters
+const ExcerptLength = 280
+
+// Feed is a rendered feed: a channel (RSS) or feed (Atom) with its items
+type Feed struct {
+	Updated     time.Time
+	ID          string // Stable feed identifier (Atom id)
+	Title       string
+	Link        string // Page the feed describes
+	SelfLink    string // URL of the feed itself
+	Description string
+	Items       []Item
+}
+
+// Item is a single post in a feed
+type Item struct {
+	Published time.Time
+	Updated   time.Time // Zero when never edited
+	ID        string    // Stable item identifier (the post's at:// URI)
+	Title     string
+	Link      string // Post permalink
+	Author    string // Author handle
+	Excerpt   string
+}
+
+// Excerpt returns plain text suitable for an item summary: whitespace is collapsed and text
+// longer than ExcerptLength is cut at a word boundary and ends with an ellipsis
+func Excerpt(text string) string {
+	text = strings.Join(strings.FieldsFunc(text, unicode.IsSpace), " ")
+	if utf8.RuneCountInString(text) <= ExcerptLength {
+		return text
+	}
+	runes := []rune(text)[:ExcerptLength]
+	cut := string(runes)
+	if i := strings.LastIndexByte(cut, ' '); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " .,;:") + "…"
+}
+
+// latest returns when the feed last changed: the newest item, or the feed's own Updated
+func (f *Feed) latest() time.Time {
+	updated := f.Updated
+	for _, item := range f.Items {
+		if t := item.modified(); t.After(updated) {
+			updated = t
+		}
+	}
+	return updated
+}
+
+// modified returns when the item last changed
+func (i *Item) modified() time.Time {
+	if i.Updated.After(i.Published) {
+		return i.Updated
+	}
+	return i.Published
+}
@@ -0,0 +1,96 @@
+package syndication
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// RSSContentType is the Content-Type of RenderRSS output
+const RSSContentType = "application/rss+xml; charset=utf-8"
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string       `xml:"title"`
+	Link          string       `xml:"link"`
+	Description   string       `xml:"description"`
+	SelfLink      *rssAtomLink `xml:"atom:link,omitempty"`
+	LastBuildDate string       `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem    `xml:"item"`
+}
+
+// rssAtomLink is the atom:link rel="self" RSS readers use to find the canonical feed URL
+type rssAtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	Creator     string  `xml:"dc:creator,omitempty"` // RSS <author> must be an email address
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// RenderRSS renders the feed as an RSS 2.0 document
+func RenderRSS(feed *Feed) ([]byte, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       feed.Title,
+			Link:        feed.Link,
+			Description: feed.Description,
+			Items:       make([]rssItem, 0, len(feed.Items)),
+		},
+	}
+	if feed.SelfLink != "" {
+		doc.Channel.SelfLink = &rssAtomLink{Href: feed.SelfLink, Rel: "self", Type: "application/rss+xml"}
+	}
+	if updated := feed.latest(); !updated.IsZero() {
+		doc.Channel.LastBuildDate = updated.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, item := range feed.Items {
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID},
+			Creator:     item.Author,
+			PubDate:     item.Published.UTC().Format(time.RFC1123Z),
+			Description: item.Excerpt,
+		})
+	}
+
+	return encode(doc)
+}
+
+// encode marshals an XML document with its declaration
+func encode(doc interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
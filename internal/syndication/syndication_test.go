@@ -0,0 +1,197 @@
+package syndication
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() *Feed {
+	published := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &Feed{
+		ID:          "https://coves.test/c/gardening.community.coves.test",
+		Title:       "Gardening",
+		Link:        "https://coves.test/c/gardening.community.coves.test",
+		SelfLink:    "https://coves.test/feeds/community/gardening.community.coves.test.xml",
+		Description: "Growing things <together>",
+		Items: []Item{
+			{
+				ID:        "at://did:plc:c1/social.coves.community.post/1",
+				Title:     "Tomatoes & peppers",
+				Link:      "https://coves.test/c/gardening.community.coves.test/post/1",
+				Author:    "alice.test",
+				Published: published,
+				Updated:   published.Add(time.Hour),
+				Excerpt:   "Planting <b>early</b>",
+			},
+			{
+				ID:        "at://did:plc:c1/social.coves.community.post/2",
+				Title:     "Compost",
+				Link:      "https://coves.test/c/gardening.community.coves.test/post/2",
+				Author:    "bob.test",
+				Published: published.Add(-time.Hour),
+			},
+		},
+	}
+}
+
+func TestRenderRSS(t *testing.T) {
+	body, err := RenderRSS(testFeed())
+	if err != nil {
+		t.Fatalf("RenderRSS: %v", err)
+	}
+	if !strings.HasPrefix(string(body), xml.Header) {
+		t.Error("expected an XML declaration")
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"rss"`
+		Version string   `xml:"version,attr"`
+		Channel struct {
+			// Before Link: an unqualified field also matches atom:link
+			AtomLink struct {
+				Href string `xml:"href,attr"`
+				Rel  string `xml:"rel,attr"`
+			} `xml:"http://www.w3.org/2005/Atom link"`
+			Title         string `xml:"title"`
+			Link          string `xml:"link"`
+			Description   string `xml:"description"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Title string `xml:"title"`
+				Link  string `xml:"link"`
+				GUID  struct {
+					Value       string `xml:",chardata"`
+					IsPermaLink string `xml:"isPermaLink,attr"`
+				} `xml:"guid"`
+				Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+				PubDate     string `xml:"pubDate"`
+				Description string `xml:"description"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, body)
+	}
+
+	if doc.Version != "2.0" {
+		t.Errorf("version = %q, want 2.0", doc.Version)
+	}
+	ch := doc.Channel
+	if ch.Title != "Gardening" || ch.Description != "Growing things <together>" {
+		t.Errorf("unexpected channel: %+v", ch)
+	}
+	if ch.AtomLink.Rel != "self" || !strings.HasSuffix(ch.AtomLink.Href, ".xml") {
+		t.Errorf("unexpected self link: %+v", ch.AtomLink)
+	}
+	if ch.LastBuildDate != "Sun, 01 Mar 2026 13:00:00 +0000" {
+		t.Errorf("lastBuildDate = %q, want the newest item edit", ch.LastBuildDate)
+	}
+	if len(ch.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(ch.Items))
+	}
+	item := ch.Items[0]
+	if item.Title != "Tomatoes & peppers" || item.Description != "Planting <b>early</b>" {
+		t.Errorf("text was not round-tripped: %+v", item)
+	}
+	if item.GUID.Value != "at://did:plc:c1/social.coves.community.post/1" || item.GUID.IsPermaLink != "false" {
+		t.Errorf("unexpected guid: %+v", item.GUID)
+	}
+	if item.Creator != "alice.test" {
+		t.Errorf("creator = %q", item.Creator)
+	}
+	if _, err := time.Parse(time.RFC1123Z, item.PubDate); err != nil {
+		t.Errorf("pubDate %q is not RFC 822: %v", item.PubDate, err)
+	}
+}
+
+func TestRenderAtom(t *testing.T) {
+	body, err := RenderAtom(testFeed())
+	if err != nil {
+		t.Fatalf("RenderAtom: %v", err)
+	}
+
+	var doc struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Title   string   `xml:"title"`
+		Updated string   `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			ID        string `xml:"id"`
+			Title     string `xml:"title"`
+			Published string `xml:"published"`
+			Updated   string `xml:"updated"`
+			Summary   string `xml:"summary"`
+			Author    struct {
+				Name string `xml:"name"`
+			} `xml:"author"`
+			Link struct {
+				Href string `xml:"href,attr"`
+			} `xml:"link"`
+		} `xml:"entry"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("output is not valid Atom: %v\n%s", err, body)
+	}
+
+	if doc.ID == "" || doc.Title != "Gardening" {
+		t.Errorf("unexpected feed header: id=%q title=%q", doc.ID, doc.Title)
+	}
+	if doc.Updated != "2026-03-01T13:00:00Z" {
+		t.Errorf("updated = %q, want the newest item edit", doc.Updated)
+	}
+	rels := map[string]string{}
+	for _, link := range doc.Links {
+		rels[link.Rel] = link.Href
+	}
+	if rels["alternate"] == "" || rels["self"] == "" {
+		t.Errorf("expected alternate and self links, got %v", rels)
+	}
+	if len(doc.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(doc.Entries))
+	}
+	entry := doc.Entries[0]
+	if entry.Author.Name != "alice.test" || entry.Link.Href == "" || entry.Summary != "Planting <b>early</b>" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Published != "2026-03-01T12:00:00Z" || entry.Updated != "2026-03-01T13:00:00Z" {
+		t.Errorf("unexpected entry dates: published=%q updated=%q", entry.Published, entry.Updated)
+	}
+	if doc.Entries[1].Updated != doc.Entries[1].Published {
+		t.Error("unedited entries are updated when published")
+	}
+}
+
+func TestRender_EmptyFeed(t *testing.T) {
+	feed := &Feed{ID: "https://coves.test/", Title: "Front page", Link: "https://coves.test/"}
+	for name, render := range map[string]func(*Feed) ([]byte, error){"rss": RenderRSS, "atom": RenderAtom} {
+		body, err := render(feed)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var v struct{}
+		if err := xml.Unmarshal(body, &v); err != nil {
+			t.Errorf("%s: empty feed is not valid XML: %v", name, err)
+		}
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	if got := Excerpt("  short\n\ntext  "); got != "short text" {
+		t.Errorf("Excerpt collapsed whitespace to %q", got)
+	}
+
+	long := strings.Repeat("word ", 100)
+	got := Excerpt(long)
+	if !strings.HasSuffix(got, "word…") {
+		t.Errorf("long text should be cut at a word boundary, got %q", got)
+	}
+	if n := len([]rune(got)); n > ExcerptLength+1 {
+		t.Errorf("excerpt has %d characters, want at most %d", n, ExcerptLength+1)
+	}
+}
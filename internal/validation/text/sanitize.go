@@ -0,0 +1,82 @@
+// Package text sanitizes user-authored text indexed from the firehose
+//
+// Records arrive from PDSes we don't control, so titles and content can carry characters
+// that are valid UTF-8 but harmful once rendered: bidi overrides that visually reorder a
+// handle or link, and raw control characters that break some clients' JSON handling.
+// Sanitize removes them and normalizes to NFC so that equal-looking text is stored equally.
+package text
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// IsForbidden reports whether Sanitize removes r
+//
+// Forbidden are C0 and C1 control characters (and DEL) other than newline and tab, the
+// bidi embedding/override characters U+202A–U+202E, and the bidi isolates U+2066–U+2069.
+// Zero-width joiners and the LRM/RLM marks are kept: emoji sequences and right-to-left
+// languages need them, and they can't reorder text on their own.
+func IsForbidden(r rune) bool {
+	switch {
+	case r == '\n' || r == '\t':
+		return false
+	case unicode.IsControl(r):
+		return true
+	case r >= 0x202A && r <= 0x202E:
+		return true
+	case r >= 0x2066 && r <= 0x2069:
+		return true
+	}
+	return false
+}
+
+// Sanitize removes forbidden characters from s and normalizes the result to NFC
+// Invalid UTF-8 is replaced with U+FFFD. The output is deterministic, and sanitizing it
+// again returns it unchanged.
+func Sanitize(s string) string {
+	if !needsSanitizing(s) {
+		return s
+	}
+
+	s = strings.ToValidUTF8(s, string(utf8.RuneError))
+	s = strings.Map(func(r rune) rune {
+		if IsForbidden(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return norm.NFC.String(s)
+}
+
+// MapOffset maps a byte offset in s to the corresponding byte offset in Sanitize(s)
+// Used to keep facet byte ranges pointing at the same text after sanitizing. Offsets
+// inside a multi-byte character are moved to its start, and offsets out of range are clamped.
+func MapOffset(s string, offset int) int {
+	if offset <= 0 {
+		return 0
+	}
+	if offset >= len(s) {
+		return len(Sanitize(s))
+	}
+	for offset > 0 && !utf8.RuneStart(s[offset]) {
+		offset--
+	}
+	return len(Sanitize(s[:offset]))
+}
+
+// needsSanitizing is the fast path: most text is valid NFC with no forbidden characters
+func needsSanitizing(s string) bool {
+	if !utf8.ValidString(s) {
+		return true
+	}
+	for _, r := range s {
+		if IsForbidden(r) {
+			return true
+		}
+	}
+	return !norm.NFC.IsNormalString(s)
+}
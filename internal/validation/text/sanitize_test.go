@@ -0,0 +1,121 @@
+package text
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+func TestIsForbidden(t *testing.T) {
+	// Every code point, so a change to the forbidden set shows up here
+	for r := rune(0); r <= utf8.MaxRune; r++ {
+		want := (r < 0x20 && r != '\n' && r != '\t') ||
+			(r >= 0x7F && r <= 0x9F) ||
+			(r >= 0x202A && r <= 0x202E) ||
+			(r >= 0x2066 && r <= 0x2069)
+		if got := IsForbidden(r); got != want {
+			t.Fatalf("IsForbidden(%U) = %v, want %v", r, got, want)
+		}
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain text unchanged", input: "Hello, world!", want: "Hello, world!"},
+		{name: "newline and tab kept", input: "line one\n\tline two", want: "line one\n\tline two"},
+		{name: "carriage return removed", input: "windows\r\nline", want: "windows\nline"},
+		{name: "null and escape removed", input: "a\x00b\x1b[31mc", want: "ab[31mc"},
+		{name: "DEL removed", input: "a\x7fb", want: "ab"},
+		{name: "C1 controls removed", input: "a\u0085b\u009bc", want: "abc"},
+		{name: "RTL override removed", input: "@alice\u202eevil.com", want: "@aliceevil.com"},
+		{name: "all embeddings and overrides removed", input: "\u202a\u202b\u202c\u202d\u202ex", want: "x"},
+		{name: "isolates removed", input: "\u2066a\u2067b\u2068c\u2069", want: "abc"},
+		{name: "decomposed accent composed to NFC", input: "cafe\u0301", want: "caf\u00e9"},
+		{name: "zero-width joiner kept in emoji", input: "\U0001f469\u200d\U0001f4bb", want: "\U0001f469\u200d\U0001f4bb"},
+		{name: "RTL marks kept", input: "\u05e9\u05dc\u05d5\u05dd\u200fabc", want: "\u05e9\u05dc\u05d5\u05dd\u200fabc"},
+		{name: "invalid UTF-8 replaced", input: "a\xffb", want: "a\ufffdb"},
+		{name: "only forbidden characters", input: "\x00\u202e\x01", want: ""},
+		{name: "empty", input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sanitize(tt.input); got != tt.want {
+				t.Errorf("Sanitize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitize_RemovesEveryForbiddenCodePoint(t *testing.T) {
+	for r := rune(0); r <= utf8.MaxRune; r++ {
+		if !IsForbidden(r) {
+			continue
+		}
+		input := "a" + string(r) + "b"
+		if got := Sanitize(input); got != "ab" {
+			t.Fatalf("Sanitize(%q) = %q, want %q", input, got, "ab")
+		}
+	}
+}
+
+func TestMapOffset(t *testing.T) {
+	// "@bob" starts at byte 7 in the original and byte 4 once the override is gone
+	original := "hi \u202e @bob"
+	sanitized := Sanitize(original)
+	start := strings.Index(original, "@bob")
+
+	got := MapOffset(original, start)
+	if sanitized[got:got+4] != "@bob" {
+		t.Errorf("MapOffset(%d) = %d, which points at %q", start, got, sanitized[got:])
+	}
+	if end := MapOffset(original, len(original)); end != len(sanitized) {
+		t.Errorf("End offset = %d, want %d", end, len(sanitized))
+	}
+
+	// Offsets inside a character move to its start; out-of-range offsets are clamped
+	if got := MapOffset("\u00e9", 1); got != 0 {
+		t.Errorf("Offset inside a character = %d, want 0", got)
+	}
+	if got := MapOffset("abc", -5); got != 0 {
+		t.Errorf("Negative offset = %d, want 0", got)
+	}
+	if got := MapOffset("abc", 99); got != 3 {
+		t.Errorf("Offset past the end = %d, want 3", got)
+	}
+}
+
+func FuzzSanitize(f *testing.F) {
+	f.Add("plain")
+	f.Add("@alice\u202eevil.com")
+	f.Add("a\x00\r\n\u0085\u2066b")
+	f.Add("cafe\u0301 \U0001f469\u200d\U0001f4bb")
+	f.Add("\xff\xfe")
+
+	f.Fuzz(func(t *testing.T, s string) {
+		out := Sanitize(s)
+		if !utf8.ValidString(out) {
+			t.Fatalf("Sanitize(%q) returned invalid UTF-8 %q", s, out)
+		}
+		for _, r := range out {
+			if IsForbidden(r) {
+				t.Fatalf("Sanitize(%q) = %q contains forbidden %U", s, out, r)
+			}
+		}
+		if !norm.NFC.IsNormalString(out) {
+			t.Fatalf("Sanitize(%q) = %q is not NFC", s, out)
+		}
+		if again := Sanitize(out); again != out {
+			t.Fatalf("Sanitize is not idempotent: %q -> %q -> %q", s, out, again)
+		}
+		if end := MapOffset(s, len(s)); end != len(out) {
+			t.Fatalf("MapOffset(len) = %d, want %d", end, len(out))
+		}
+	})
+}
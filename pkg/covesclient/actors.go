@@ -0,0 +1,74 @@
+package covesclient
+
+import (
+	"context"
+	"net/url"
+)
+
+// SubscriptionsParams are the parameters for social.coves.actor.getSubscriptions.
+type SubscriptionsParams struct {
+	Sort   string
+	Query  string // filter by community name
+	Cursor string
+	Limit  int
+}
+
+// SignupResponse is the output of social.coves.actor.signup.
+type SignupResponse struct {
+	DID        string `json:"did"`
+	Handle     string `json:"handle"`
+	AccessJwt  string `json:"accessJwt"`
+	RefreshJwt string `json:"refreshJwt"`
+}
+
+// GetProfile fetches an actor's profile by DID or handle.
+func (c *Client) GetProfile(ctx context.Context, actor string) (*ProfileViewDetailed, error) {
+	var out ProfileViewDetailed
+	if err := c.query(ctx, "social.coves.actor.getprofile", url.Values{"actor": {actor}}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetSubscriptions lists the communities the authenticated user subscribes to.
+func (c *Client) GetSubscriptions(ctx context.Context, p SubscriptionsParams) (*GetSubscriptionsResponse, error) {
+	params := url.Values{}
+	setString(params, "sort", p.Sort)
+	setString(params, "q", p.Query)
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "limit", p.Limit)
+
+	var out GetSubscriptionsResponse
+	if err := c.query(ctx, "social.coves.actor.getSubscriptions", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateProfile updates the authenticated user's profile. Nil fields are left unchanged.
+func (c *Client) UpdateProfile(ctx context.Context, req UpdateProfileRequest) (*UpdateProfileResponse, error) {
+	var out UpdateProfileResponse
+	if err := c.procedure(ctx, "social.coves.actor.updateProfile", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteAccount deletes the authenticated user's Coves data. The user's PDS
+// account and records are not affected.
+func (c *Client) DeleteAccount(ctx context.Context) (*DeleteAccountResponse, error) {
+	var out DeleteAccountResponse
+	if err := c.procedure(ctx, "social.coves.actor.deleteAccount", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Signup creates an account on the instance's PDS.
+func (c *Client) Signup(ctx context.Context, req RegisterAccountRequest) (*SignupResponse, error) {
+	var out SignupResponse
+	if err := c.procedure(ctx, "social.coves.actor.signup", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
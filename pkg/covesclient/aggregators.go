@@ -0,0 +1,131 @@
+package covesclient
+
+import (
+	"context"
+	"net/url"
+	"strings"
+)
+
+// AuthorizationsParams are the parameters for social.coves.aggregator.getAuthorizations.
+type AuthorizationsParams struct {
+	AggregatorDID string // required
+	EnabledOnly   bool
+	Limit         int
+	Offset        int
+}
+
+// ListForCommunityParams are the parameters for social.coves.aggregator.listForCommunity.
+type ListForCommunityParams struct {
+	Community   string // DID or handle (required)
+	EnabledOnly bool
+	Limit       int
+}
+
+// ListServicesParams are the parameters for social.coves.aggregator.listServices.
+type ListServicesParams struct {
+	Cursor string
+	Limit  int
+}
+
+// GetServicesResponse is the output of social.coves.aggregator.getServices.
+// The client always requests detailed views.
+type GetServicesResponse struct {
+	Views []*AggregatorViewDetailed `json:"views"`
+}
+
+// GetServices fetches aggregator service declarations by DID.
+func (c *Client) GetServices(ctx context.Context, dids ...string) (*GetServicesResponse, error) {
+	params := url.Values{"dids": {strings.Join(dids, ",")}, "detailed": {"true"}}
+
+	var out GetServicesResponse
+	if err := c.query(ctx, "social.coves.aggregator.getServices", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetService fetches a single aggregator from the public directory.
+func (c *Client) GetService(ctx context.Context, aggregatorDID string) (*GetServiceResponse, error) {
+	var out GetServiceResponse
+	if err := c.query(ctx, "social.coves.aggregator.getService", url.Values{"aggregator": {aggregatorDID}}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListServices pages through the public aggregator directory.
+func (c *Client) ListServices(ctx context.Context, p ListServicesParams) (*ListServicesResponse, error) {
+	params := url.Values{}
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "limit", p.Limit)
+
+	var out ListServicesResponse
+	if err := c.query(ctx, "social.coves.aggregator.listServices", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAuthorizations lists the communities that have authorized an aggregator.
+func (c *Client) GetAuthorizations(ctx context.Context, p AuthorizationsParams) (*GetAuthorizationsResponse, error) {
+	params := url.Values{"aggregatorDid": {p.AggregatorDID}}
+	setBool(params, "enabledOnly", p.EnabledOnly)
+	setInt(params, "limit", p.Limit)
+	setInt(params, "offset", p.Offset)
+
+	var out GetAuthorizationsResponse
+	if err := c.query(ctx, "social.coves.aggregator.getAuthorizations", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListForCommunity lists the aggregators authorized in a community.
+func (c *Client) ListForCommunity(ctx context.Context, p ListForCommunityParams) (*ListForCommunityResponse, error) {
+	params := url.Values{"community": {p.Community}}
+	setBool(params, "enabledOnly", p.EnabledOnly)
+	setInt(params, "limit", p.Limit)
+
+	var out ListForCommunityResponse
+	if err := c.query(ctx, "social.coves.aggregator.listForCommunity", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RegisterAggregator registers an aggregator DID after verifying its domain.
+func (c *Client) RegisterAggregator(ctx context.Context, req RegisterAggregatorRequest) (*RegisterAggregatorResult, error) {
+	var out RegisterAggregatorResult
+	if err := c.procedure(ctx, "social.coves.aggregator.register", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateAPIKey creates an API key for the authenticated aggregator, replacing
+// any existing key. The plain-text key is only returned once.
+func (c *Client) CreateAPIKey(ctx context.Context) (*CreateAPIKeyResponse, error) {
+	var out CreateAPIKeyResponse
+	if err := c.procedure(ctx, "social.coves.aggregator.createApiKey", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetAPIKey returns metadata about the authenticated aggregator's API key.
+func (c *Client) GetAPIKey(ctx context.Context) (*GetAPIKeyResponse, error) {
+	var out GetAPIKeyResponse
+	if err := c.query(ctx, "social.coves.aggregator.getApiKey", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeAPIKey revokes the authenticated aggregator's API key.
+func (c *Client) RevokeAPIKey(ctx context.Context) (*RevokeAPIKeyResponse, error) {
+	var out RevokeAPIKeyResponse
+	if err := c.procedure(ctx, "social.coves.aggregator.revokeApiKey", nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,223 @@
+package covesclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout      = 30 * time.Second
+	defaultMaxRetries   = 3
+	defaultMaxRetryWait = time.Minute
+	defaultUserAgent    = "covesclient-go"
+
+	// maxErrorBodyBytes bounds how much of a non-JSON error body is kept in Error.Message
+	maxErrorBodyBytes = 512
+)
+
+// Client calls the Coves XRPC API. It is safe for concurrent use.
+type Client struct {
+	httpClient   *http.Client
+	baseURL      string
+	token        string
+	userAgent    string
+	maxRetries   int
+	maxRetryWait time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithBearerToken authenticates requests with an OAuth session token, sent as
+// "Authorization: Bearer <token>".
+func WithBearerToken(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithAPIKey authenticates requests with an aggregator API key (ckapi_...).
+// API keys use the same Authorization header as session tokens.
+func WithAPIKey(key string) Option {
+	return func(c *Client) { c.token = key }
+}
+
+// WithHTTPClient sets the underlying HTTP client (default: 30s timeout).
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithMaxRetries sets how many times a rate-limited (HTTP 429) request is
+// retried before the error is returned (default 3, 0 disables retries).
+func WithMaxRetries(n int) Option {
+	return func(c *Client) { c.maxRetries = n }
+}
+
+// WithMaxRetryWait caps how long the client sleeps before a single retry,
+// whatever the server's Retry-After asks for (default 1 minute).
+func WithMaxRetryWait(d time.Duration) Option {
+	return func(c *Client) { c.maxRetryWait = d }
+}
+
+// New creates a client for the Coves instance at baseURL (e.g. "https://coves.social").
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("covesclient: invalid base URL %q", baseURL)
+	}
+
+	c := &Client{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		userAgent:    defaultUserAgent,
+		maxRetries:   defaultMaxRetries,
+		maxRetryWait: defaultMaxRetryWait,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// WithToken returns a copy of the client that authenticates with token.
+// An empty token returns an unauthenticated copy.
+func (c *Client) WithToken(token string) *Client {
+	clone := *c
+	clone.token = token
+	return &clone
+}
+
+// query calls an XRPC query (GET) endpoint.
+func (c *Client) query(ctx context.Context, nsid string, params url.Values, out any) error {
+	return c.do(ctx, http.MethodGet, nsid, params, nil, out)
+}
+
+// procedure calls an XRPC procedure (POST) endpoint with a JSON body.
+func (c *Client) procedure(ctx context.Context, nsid string, in, out any) error {
+	body := []byte("{}")
+	if in != nil {
+		var err error
+		body, err = json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("covesclient: %s: failed to encode request: %w", nsid, err)
+		}
+	}
+	return c.do(ctx, http.MethodPost, nsid, nil, body, out)
+}
+
+func (c *Client) do(ctx context.Context, method, nsid string, params url.Values, body []byte, out any) error {
+	endpoint := c.baseURL + "/xrpc/" + nsid
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	for attempt := 0; ; attempt++ {
+		var bodyReader io.Reader
+		if body != nil {
+			bodyReader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, endpoint, bodyReader)
+		if err != nil {
+			return fmt.Errorf("covesclient: %s: %w", nsid, err)
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("User-Agent", c.userAgent)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("covesclient: %s: %w", nsid, err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.maxRetries {
+			wait := c.retryDelay(resp.Header.Get("Retry-After"), attempt)
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return fmt.Errorf("covesclient: %s: %w", nsid, ctx.Err())
+			case <-timer.C:
+			}
+			continue
+		}
+
+		return c.handleResponse(nsid, resp, out)
+	}
+}
+
+func (c *Client) handleResponse(nsid string, resp *http.Response, out any) error {
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return parseError(nsid, resp)
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("covesclient: %s: failed to decode response: %w", nsid, err)
+	}
+	return nil
+}
+
+// retryDelay honours Retry-After (delta-seconds or HTTP-date) and otherwise
+// backs off exponentially from one second.
+func (c *Client) retryDelay(retryAfter string, attempt int) time.Duration {
+	wait := time.Second << attempt
+	if retryAfter != "" {
+		if secs, err := strconv.Atoi(strings.TrimSpace(retryAfter)); err == nil && secs >= 0 {
+			wait = time.Duration(secs) * time.Second
+		} else if at, err := http.ParseTime(retryAfter); err == nil {
+			wait = time.Until(at)
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > c.maxRetryWait {
+		wait = c.maxRetryWait
+	}
+	return wait
+}
+
+// setString adds a query parameter when value is non-empty.
+func setString(params url.Values, key, value string) {
+	if value != "" {
+		params.Set(key, value)
+	}
+}
+
+// setInt adds a query parameter when value is positive.
+func setInt(params url.Values, key string, value int) {
+	if value > 0 {
+		params.Set(key, strconv.Itoa(value))
+	}
+}
+
+// setBool adds a query parameter when value is true.
+func setBool(params url.Values, key string, value bool) {
+	if value {
+		params.Set(key, "true")
+	}
+}
@@ -0,0 +1,296 @@
+package covesclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"Coves/internal/api/xrpcerror"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := New(server.URL, opts...)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return client
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "coves.social", "ftp://coves.social", "https://"} {
+		if _, err := New(baseURL); err == nil {
+			t.Errorf("New(%q) expected error", baseURL)
+		}
+	}
+}
+
+func TestClient_SendsBearerToken(t *testing.T) {
+	var gotAuth, gotUA string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotUA = r.Header.Get("User-Agent")
+		writeJSON(w, http.StatusOK, map[string]any{"feed": []any{}})
+	}, WithAPIKey("ckapi_abc123"), WithUserAgent("my-bot/1.0"))
+
+	if _, err := client.GetTimeline(context.Background(), FeedParams{}); err != nil {
+		t.Fatalf("GetTimeline() error = %v", err)
+	}
+	if gotAuth != "Bearer ckapi_abc123" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer ckapi_abc123")
+	}
+	if gotUA != "my-bot/1.0" {
+		t.Errorf("User-Agent = %q, want %q", gotUA, "my-bot/1.0")
+	}
+
+	// WithToken("") drops auth without affecting the original client
+	if _, err := client.WithToken("").GetTimeline(context.Background(), FeedParams{}); err != nil {
+		t.Fatalf("GetTimeline() error = %v", err)
+	}
+	if gotAuth != "" {
+		t.Errorf("Authorization = %q, want none", gotAuth)
+	}
+	if client.token != "ckapi_abc123" {
+		t.Errorf("WithToken modified the original client")
+	}
+}
+
+func TestClient_QueryParams(t *testing.T) {
+	var gotPath string
+	var gotQuery map[string][]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.Query()
+		writeJSON(w, http.StatusOK, map[string]any{"feed": []any{}, "cursor": "next"})
+	})
+
+	resp, err := client.GetCommunityFeed(context.Background(), CommunityFeedParams{
+		Community:  "c-gaming.coves.social",
+		Tag:        "News & Updates",
+		FeedParams: FeedParams{Sort: "top", Timeframe: "week", Limit: 25},
+	})
+	if err != nil {
+		t.Fatalf("GetCommunityFeed() error = %v", err)
+	}
+
+	if gotPath != "/xrpc/social.coves.communityFeed.getCommunity" {
+		t.Errorf("path = %q", gotPath)
+	}
+	want := map[string]string{
+		"community": "c-gaming.coves.social",
+		"tag":       "News & Updates",
+		"sort":      "top",
+		"timeframe": "week",
+		"limit":     "25",
+	}
+	for key, value := range want {
+		if got := gotQuery[key]; len(got) != 1 || got[0] != value {
+			t.Errorf("query %s = %v, want %q", key, got, value)
+		}
+	}
+	if _, ok := gotQuery["cursor"]; ok {
+		t.Errorf("unset cursor should not be sent")
+	}
+	if resp.Cursor == nil || *resp.Cursor != "next" {
+		t.Errorf("cursor = %v, want next", resp.Cursor)
+	}
+}
+
+func TestClient_ProcedureBody(t *testing.T) {
+	var gotMethod, gotContentType string
+	var gotBody CreateVoteInput
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotContentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+		writeJSON(w, http.StatusOK, map[string]string{"uri": "at://did:plc:voter/social.coves.feed.vote/abc", "cid": "bafyvote"})
+	}, WithBearerToken("token"))
+
+	out, err := client.Vote(context.Background(), "at://did:plc:author/social.coves.community.post/xyz", "bafypost", VoteUp)
+	if err != nil {
+		t.Fatalf("Vote() error = %v", err)
+	}
+	if gotMethod != http.MethodPost || gotContentType != "application/json" {
+		t.Errorf("method/content-type = %s %s", gotMethod, gotContentType)
+	}
+	if gotBody.Subject.URI != "at://did:plc:author/social.coves.community.post/xyz" || gotBody.Subject.CID != "bafypost" || gotBody.Direction != "up" {
+		t.Errorf("body = %+v", gotBody)
+	}
+	if out.URI != "at://did:plc:voter/social.coves.feed.vote/abc" {
+		t.Errorf("uri = %q", out.URI)
+	}
+}
+
+func TestClient_RetriesRateLimitWithRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	var firstAt, secondAt time.Time
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			firstAt = time.Now()
+			w.Header().Set("Retry-After", "1")
+			writeJSON(w, http.StatusTooManyRequests, xrpcerror.Response{Error: xrpcerror.RateLimitExceeded, Message: "slow down"})
+		default:
+			secondAt = time.Now()
+			writeJSON(w, http.StatusOK, map[string]any{"communities": []any{}})
+		}
+	})
+
+	if _, err := client.ListCommunities(context.Background(), ListCommunitiesParams{}); err != nil {
+		t.Fatalf("ListCommunities() error = %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("calls = %d, want 2", calls.Load())
+	}
+	if wait := secondAt.Sub(firstAt); wait < 900*time.Millisecond {
+		t.Errorf("retried after %v, want >= 1s per Retry-After", wait)
+	}
+}
+
+func TestClient_RateLimitRetriesExhausted(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "0")
+		writeJSON(w, http.StatusTooManyRequests, xrpcerror.Response{Error: xrpcerror.RateLimitExceeded, Message: "slow down"})
+	}, WithMaxRetries(2))
+
+	_, err := client.GetDiscover(context.Background(), FeedParams{})
+	if !IsRateLimited(err) || !errors.Is(err, ErrRateLimitExceeded) {
+		t.Fatalf("error = %v, want rate limit error", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3 (1 + 2 retries)", calls.Load())
+	}
+}
+
+func TestClient_ContextCancelledWhileWaitingToRetry(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "30")
+		writeJSON(w, http.StatusTooManyRequests, xrpcerror.Response{Error: xrpcerror.RateLimitExceeded})
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetFrontPage(ctx, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("call took %v, should stop waiting when the context ends", elapsed)
+	}
+}
+
+func TestClient_ErrorMapping(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		target    error
+		notFound  bool
+		authError bool
+	}{
+		{
+			name:     "community not found",
+			status:   http.StatusNotFound,
+			body:     `{"error":"CommunityNotFound","message":"community not found"}`,
+			target:   ErrCommunityNotFound,
+			notFound: true,
+		},
+		{
+			name:      "auth required",
+			status:    http.StatusUnauthorized,
+			body:      `{"error":"AuthRequired","message":"Authentication required"}`,
+			target:    ErrAuthRequired,
+			authError: true,
+		},
+		{
+			name:      "thread locked",
+			status:    http.StatusForbidden,
+			body:      `{"error":"ThreadLocked","message":"thread is locked"}`,
+			target:    ErrThreadLocked,
+			authError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			_, err := client.GetCommunity(context.Background(), "c-missing.coves.social")
+			if !errors.Is(err, tt.target) {
+				t.Fatalf("errors.Is(%v, %v) = false", err, tt.target)
+			}
+			if errors.Is(err, ErrPostNotFound) {
+				t.Errorf("error should not match a different XRPC name")
+			}
+			if IsNotFound(err) != tt.notFound || IsAuthError(err) != tt.authError {
+				t.Errorf("IsNotFound=%v IsAuthError=%v", IsNotFound(err), IsAuthError(err))
+			}
+
+			var xerr *Error
+			if !errors.As(err, &xerr) || xerr.NSID != "social.coves.community.get" || xerr.StatusCode != tt.status {
+				t.Errorf("error = %#v", err)
+			}
+		})
+	}
+}
+
+func TestClient_NonXRPCErrorBody(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	})
+
+	_, err := client.GetCommunity(context.Background(), "c-test.coves.social")
+	var xerr *Error
+	if !errors.As(err, &xerr) {
+		t.Fatalf("error = %v, want *Error", err)
+	}
+	if xerr.Name != "" || xerr.Message != "bad gateway" || xerr.StatusCode != http.StatusBadGateway {
+		t.Errorf("error = %#v", xerr)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	c := &Client{maxRetryWait: 10 * time.Second}
+
+	tests := []struct {
+		name       string
+		retryAfter string
+		attempt    int
+		want       time.Duration
+	}{
+		{"seconds", "3", 0, 3 * time.Second},
+		{"capped", "120", 0, 10 * time.Second},
+		{"backoff without header", "", 2, 4 * time.Second},
+		{"invalid header falls back to backoff", "soon", 1, 2 * time.Second},
+		{"date in the past", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.retryDelay(tt.retryAfter, tt.attempt); got != tt.want {
+				t.Errorf("retryDelay(%q, %d) = %v, want %v", tt.retryAfter, tt.attempt, got, tt.want)
+			}
+		})
+	}
+}
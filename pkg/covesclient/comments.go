@@ -0,0 +1,146 @@
+package covesclient
+
+import (
+	"context"
+	"net/url"
+)
+
+// CommentsParams are the parameters for fetching a post's comment tree.
+type CommentsParams struct {
+	Post      string // post AT-URI (required)
+	Sort      string // hot (default), top, new
+	Timeframe string // with sort=top: hour, day, week, month, year, all
+	Cursor    string
+	Depth     int // reply nesting depth; 0 uses the server default
+	Limit     int
+}
+
+// CommentThreadParams are the parameters for fetching a single comment's permalink thread.
+type CommentThreadParams struct {
+	URI          string // comment AT-URI (required)
+	Sort         string
+	Timeframe    string
+	Cursor       string
+	Depth        int
+	Limit        int
+	ParentHeight int // ancestors to include; 0 uses the server default
+}
+
+// CommentsResponse is the output of social.coves.community.comment.getComments for a post.
+type CommentsResponse struct {
+	Post     *PostView            `json:"post"`
+	Cursor   *string              `json:"cursor,omitempty"`
+	Comments []*ThreadViewComment `json:"comments"`
+}
+
+// CommentThreadResponse is the output of social.coves.community.comment.getComments for a comment permalink.
+type CommentThreadResponse struct {
+	Post   *PostView          `json:"post"`
+	Cursor *string            `json:"cursor,omitempty"`
+	Thread *CommentThreadView `json:"thread"`
+}
+
+// ActorCommentsParams are the parameters for social.coves.actor.getComments.
+type ActorCommentsParams struct {
+	Actor     string // DID or handle (required)
+	Community string
+	Cursor    string
+	Limit     int
+}
+
+// SearchCommentsParams are the parameters for social.coves.community.comment.search.
+type SearchCommentsParams struct {
+	Community      string // DID or handle (required)
+	Query          string // required
+	Author         string // author DID
+	PostURI        string // only comments on this post
+	IncludeDeleted bool
+	Cursor         string
+	Limit          int
+}
+
+// GetComments returns a post's comment tree.
+func (c *Client) GetComments(ctx context.Context, p CommentsParams) (*CommentsResponse, error) {
+	params := url.Values{"post": {p.Post}}
+	setString(params, "sort", p.Sort)
+	setString(params, "timeframe", p.Timeframe)
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "depth", p.Depth)
+	setInt(params, "limit", p.Limit)
+
+	var out CommentsResponse
+	if err := c.query(ctx, "social.coves.community.comment.getComments", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetCommentThread returns a comment with its ancestors and replies.
+func (c *Client) GetCommentThread(ctx context.Context, p CommentThreadParams) (*CommentThreadResponse, error) {
+	params := url.Values{"uri": {p.URI}}
+	setString(params, "sort", p.Sort)
+	setString(params, "timeframe", p.Timeframe)
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "depth", p.Depth)
+	setInt(params, "limit", p.Limit)
+	setInt(params, "parentHeight", p.ParentHeight)
+
+	var out CommentThreadResponse
+	if err := c.query(ctx, "social.coves.community.comment.getComments", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateComment replies to a post or comment as the authenticated user.
+func (c *Client) CreateComment(ctx context.Context, in CreateCommentInput) (*CreateCommentOutput, error) {
+	var out CreateCommentOutput
+	if err := c.procedure(ctx, "social.coves.community.comment.create", in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateComment edits one of the authenticated user's comments.
+func (c *Client) UpdateComment(ctx context.Context, in UpdateCommentInput) (*UpdateCommentOutput, error) {
+	var out UpdateCommentOutput
+	if err := c.procedure(ctx, "social.coves.community.comment.update", in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteComment deletes one of the authenticated user's comments.
+func (c *Client) DeleteComment(ctx context.Context, uri string) error {
+	return c.procedure(ctx, "social.coves.community.comment.delete", DeleteCommentInput{URI: uri}, nil)
+}
+
+// GetActorComments returns the comments an actor has authored.
+func (c *Client) GetActorComments(ctx context.Context, p ActorCommentsParams) (*CommentsPage, error) {
+	params := url.Values{"actor": {p.Actor}}
+	setString(params, "community", p.Community)
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "limit", p.Limit)
+
+	var out CommentsPage
+	if err := c.query(ctx, "social.coves.actor.getComments", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchComments searches comment text within a community.
+func (c *Client) SearchComments(ctx context.Context, p SearchCommentsParams) (*CommentsPage, error) {
+	params := url.Values{"community": {p.Community}, "q": {p.Query}}
+	setString(params, "author", p.Author)
+	setString(params, "postUri", p.PostURI)
+	setBool(params, "includeDeleted", p.IncludeDeleted)
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "limit", p.Limit)
+
+	var out CommentsPage
+	if err := c.query(ctx, "social.coves.community.comment.search", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
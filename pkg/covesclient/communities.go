@@ -0,0 +1,165 @@
+package covesclient
+
+import (
+	"context"
+	"net/url"
+)
+
+// ListCommunitiesParams are the parameters for social.coves.community.list.
+type ListCommunitiesParams struct {
+	Sort       string // popular (default), active, new, alphabetical
+	Visibility string // public, unlisted, private
+	Category   string
+	Language   string
+	Cursor     string
+	Limit      int  // 1-100, default 50
+	Subscribed bool // only communities the authenticated viewer subscribes to
+}
+
+// ListCommunitiesResponse is the output of social.coves.community.list.
+type ListCommunitiesResponse struct {
+	Cursor      string           `json:"cursor"`
+	Communities []*CommunityView `json:"communities"`
+}
+
+// SearchCommunitiesParams are the parameters for social.coves.community.search.
+type SearchCommunitiesParams struct {
+	Query      string // required
+	Visibility string
+	Cursor     string
+	Limit      int
+}
+
+// SearchCommunitiesResponse is the output of social.coves.community.search.
+// Cursor is the offset of the next page.
+type SearchCommunitiesResponse struct {
+	Communities []*CommunityView `json:"communities"`
+	Cursor      int              `json:"cursor"`
+	Total       int              `json:"total"`
+}
+
+// CommunityRecordResponse is the output of social.coves.community.create and update.
+type CommunityRecordResponse struct {
+	URI    string `json:"uri"`
+	CID    string `json:"cid"`
+	DID    string `json:"did"`
+	Handle string `json:"handle"`
+}
+
+// SubscribeResponse is the output of social.coves.community.subscribe.
+type SubscribeResponse struct {
+	URI      string `json:"uri"`
+	CID      string `json:"cid"`
+	Existing bool   `json:"existing"`
+}
+
+// BlockResponse is the output of social.coves.community.blockCommunity.
+type BlockResponse struct {
+	Block struct {
+		RecordURI string `json:"recordUri"`
+		RecordCID string `json:"recordCid"`
+	} `json:"block"`
+}
+
+type communityInput struct {
+	Community         string `json:"community"`
+	ContentVisibility int    `json:"contentVisibility,omitempty"`
+}
+
+// GetCommunity fetches a community by DID or handle, including its rules.
+func (c *Client) GetCommunity(ctx context.Context, community string) (*CommunityViewDetailed, error) {
+	var out CommunityViewDetailed
+	if err := c.query(ctx, "social.coves.community.get", url.Values{"community": {community}}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListCommunities lists communities.
+func (c *Client) ListCommunities(ctx context.Context, p ListCommunitiesParams) (*ListCommunitiesResponse, error) {
+	params := url.Values{}
+	setString(params, "sort", p.Sort)
+	setString(params, "visibility", p.Visibility)
+	setString(params, "category", p.Category)
+	setString(params, "language", p.Language)
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "limit", p.Limit)
+	setBool(params, "subscribed", p.Subscribed)
+
+	var out ListCommunitiesResponse
+	if err := c.query(ctx, "social.coves.community.list", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SearchCommunities searches communities by name and description.
+func (c *Client) SearchCommunities(ctx context.Context, p SearchCommunitiesParams) (*SearchCommunitiesResponse, error) {
+	params := url.Values{"q": {p.Query}}
+	setString(params, "visibility", p.Visibility)
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "limit", p.Limit)
+
+	var out SearchCommunitiesResponse
+	if err := c.query(ctx, "social.coves.community.search", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateCommunity creates a community owned by the authenticated user.
+func (c *Client) CreateCommunity(ctx context.Context, req CreateCommunityRequest) (*CommunityRecordResponse, error) {
+	var out CommunityRecordResponse
+	if err := c.procedure(ctx, "social.coves.community.create", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateCommunity updates a community's profile. Only set fields are changed.
+func (c *Client) UpdateCommunity(ctx context.Context, req UpdateCommunityRequest) (*CommunityRecordResponse, error) {
+	var out CommunityRecordResponse
+	if err := c.procedure(ctx, "social.coves.community.update", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateCommunityRules replaces a community's rules document.
+func (c *Client) UpdateCommunityRules(ctx context.Context, req UpdateRulesRequest) (*CommunityRules, error) {
+	var out CommunityRules
+	if err := c.procedure(ctx, "social.coves.community.updateRules", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Subscribe subscribes the authenticated user to a community.
+// contentVisibility is the 1-5 feed slider; 0 uses the server default.
+func (c *Client) Subscribe(ctx context.Context, community string, contentVisibility int) (*SubscribeResponse, error) {
+	var out SubscribeResponse
+	in := communityInput{Community: community, ContentVisibility: contentVisibility}
+	if err := c.procedure(ctx, "social.coves.community.subscribe", in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Unsubscribe removes the authenticated user's subscription to a community.
+func (c *Client) Unsubscribe(ctx context.Context, community string) error {
+	return c.procedure(ctx, "social.coves.community.unsubscribe", communityInput{Community: community}, nil)
+}
+
+// BlockCommunity blocks a community for the authenticated user.
+func (c *Client) BlockCommunity(ctx context.Context, community string) (*BlockResponse, error) {
+	var out BlockResponse
+	if err := c.procedure(ctx, "social.coves.community.blockCommunity", communityInput{Community: community}, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnblockCommunity removes the authenticated user's block of a community.
+func (c *Client) UnblockCommunity(ctx context.Context, community string) error {
+	return c.procedure(ctx, "social.coves.community.unblockCommunity", communityInput{Community: community}, nil)
+}
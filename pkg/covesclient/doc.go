@@ -0,0 +1,24 @@
+// Package covesclient is a typed Go client for the Coves XRPC API.
+//
+// Create a client with the instance's base URL and, for authenticated
+// endpoints, an OAuth session token or an aggregator API key:
+//
+//	client, err := covesclient.New("https://coves.social", covesclient.WithBearerToken(token))
+//	feed, err := client.GetCommunityFeed(ctx, covesclient.CommunityFeedParams{Community: "c-golang.coves.social"})
+//
+// Request and response types are the SDK's own copies of the API's wire
+// shapes, checked against the server handlers' structs by the package tests.
+// Responses use the default (v1) shapes.
+//
+// Rate-limited requests (HTTP 429) are retried automatically, waiting as long
+// as the Retry-After header asks (capped by WithMaxRetryWait) or backing off
+// exponentially when it is absent. Every method takes a context that bounds
+// the whole call, including time spent waiting to retry.
+//
+// API errors are returned as *Error and can be matched by XRPC error name
+// with errors.Is (errors.Is(err, covesclient.ErrPostNotFound)) or by class
+// with IsNotFound, IsAuthError and IsRateLimited.
+//
+// Instance administration endpoints (social.coves.admin.*) and internal
+// monitoring endpoints are not covered.
+package covesclient
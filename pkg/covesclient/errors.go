@@ -0,0 +1,114 @@
+package covesclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"Coves/internal/api/xrpcerror"
+)
+
+// Error is an XRPC error returned by the API.
+//
+// Name is the stable XRPC error name (e.g. "CommunityNotFound") and is what
+// callers should switch on; Message is for humans and may change.
+// Compare against the sentinel errors below with errors.Is:
+//
+//	if errors.Is(err, covesclient.ErrCommunityNotFound) { ... }
+type Error struct {
+	Name       string
+	Message    string
+	NSID       string
+	StatusCode int
+}
+
+func (e *Error) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("covesclient: %s: HTTP %d: %s", e.NSID, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("covesclient: %s: %s (HTTP %d): %s", e.NSID, e.Name, e.StatusCode, e.Message)
+}
+
+// Is matches sentinel errors by XRPC error name.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Name != "" && t.Name == e.Name
+}
+
+// Sentinel errors for the XRPC error names callers most often handle.
+// The names are shared with the server so they can't drift.
+var (
+	ErrInvalidRequest    = &Error{Name: xrpcerror.InvalidRequest}
+	ErrInvalidCursor     = &Error{Name: xrpcerror.InvalidCursor}
+	ErrAuthRequired      = &Error{Name: xrpcerror.AuthRequired}
+	ErrAuthExpired       = &Error{Name: xrpcerror.AuthExpired}
+	ErrForbidden         = &Error{Name: xrpcerror.Forbidden}
+	ErrNotAuthorized     = &Error{Name: xrpcerror.NotAuthorized}
+	ErrNotFound          = &Error{Name: xrpcerror.NotFound}
+	ErrAlreadyExists     = &Error{Name: xrpcerror.AlreadyExists}
+	ErrRateLimitExceeded = &Error{Name: xrpcerror.RateLimitExceeded}
+
+	ErrActorNotFound      = &Error{Name: xrpcerror.ActorNotFound}
+	ErrAggregatorNotFound = &Error{Name: xrpcerror.AggregatorNotFound}
+	ErrCommentNotFound    = &Error{Name: xrpcerror.CommentNotFound}
+	ErrCommunityNotFound  = &Error{Name: xrpcerror.CommunityNotFound}
+	ErrPostNotFound       = &Error{Name: xrpcerror.PostNotFound}
+	ErrProfileNotFound    = &Error{Name: xrpcerror.ProfileNotFound}
+	ErrVoteNotFound       = &Error{Name: xrpcerror.VoteNotFound}
+
+	ErrBanned       = &Error{Name: xrpcerror.Banned}
+	ErrBlocked      = &Error{Name: xrpcerror.Blocked}
+	ErrNameTaken    = &Error{Name: xrpcerror.NameTaken}
+	ErrThreadLocked = &Error{Name: xrpcerror.ThreadLocked}
+)
+
+// IsNotFound reports whether err is any XRPC not-found error (HTTP 404).
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsAuthError reports whether err is an authentication or authorization failure (HTTP 401/403).
+func IsAuthError(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized) || hasStatus(err, http.StatusForbidden)
+}
+
+// IsRateLimited reports whether err is a rate limit that outlasted the client's retries (HTTP 429).
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+func hasStatus(err error, status int) bool {
+	var xerr *Error
+	return errors.As(err, &xerr) && xerr.StatusCode == status
+}
+
+// parseError builds an *Error from a non-2xx response. Bodies that aren't the
+// XRPC {"error", "message"} shape (e.g. from a proxy) keep a truncated copy of
+// the body as the message.
+func parseError(nsid string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	xerr := &Error{NSID: nsid, StatusCode: resp.StatusCode}
+
+	var payload xrpcerror.Response
+	if json.Unmarshal(body, &payload) == nil && payload.Error != "" {
+		xerr.Name = payload.Error
+		xerr.Message = payload.Message
+		return xerr
+	}
+
+	msg := strings.TrimSpace(string(body))
+	if len(msg) > maxErrorBodyBytes {
+		msg = msg[:maxErrorBodyBytes]
+	}
+	if msg == "" {
+		msg = http.StatusText(resp.StatusCode)
+	}
+	xerr.Message = msg
+	return xerr
+}
@@ -0,0 +1,34 @@
+package covesclient_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"Coves/pkg/covesclient"
+)
+
+func Example() {
+	client, err := covesclient.New("https://coves.social",
+		covesclient.WithBearerToken(os.Getenv("COVES_TOKEN")))
+	if err != nil {
+		log.Fatal(err)
+	}
+	ctx := context.Background()
+
+	feed, err := client.GetCommunityFeed(ctx, covesclient.CommunityFeedParams{
+		Community:  "c-gaming.coves.social",
+		FeedParams: covesclient.FeedParams{Sort: "new", Limit: 10},
+	})
+	if errors.Is(err, covesclient.ErrCommunityNotFound) {
+		log.Fatal("no such community")
+	} else if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, item := range feed.Feed {
+		fmt.Println(item.Post.URI)
+	}
+}
@@ -0,0 +1,99 @@
+package covesclient
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// FeedParams are the common feed parameters.
+type FeedParams struct {
+	Sort      string // hot, top, new
+	Timeframe string // with sort=top: hour, day, week, month, year, all
+	Cursor    string
+	Limit     int
+}
+
+func (p FeedParams) values() url.Values {
+	params := url.Values{}
+	setString(params, "sort", p.Sort)
+	setString(params, "timeframe", p.Timeframe)
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "limit", p.Limit)
+	return params
+}
+
+// CommunityFeedParams are the parameters for social.coves.communityFeed.getCommunity.
+type CommunityFeedParams struct {
+	Community string // DID or handle (required)
+	Tag       string // only posts with this flair
+	MinScore  *int   // only posts scoring at least this; moderators only
+	FeedParams
+}
+
+// DiscussionsParams are the parameters for social.coves.feed.getDiscussions.
+type DiscussionsParams struct {
+	URL    string // the link to find discussions of (required)
+	Cursor string
+	Limit  int
+}
+
+// GetCommunityFeed returns a community's posts.
+func (c *Client) GetCommunityFeed(ctx context.Context, p CommunityFeedParams) (*FeedResponse, error) {
+	params := p.values()
+	params.Set("community", p.Community)
+	setString(params, "tag", p.Tag)
+	if p.MinScore != nil {
+		params.Set("minScore", strconv.Itoa(*p.MinScore))
+	}
+
+	var out FeedResponse
+	if err := c.query(ctx, "social.coves.communityFeed.getCommunity", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetTimeline returns posts from the authenticated user's subscribed communities.
+func (c *Client) GetTimeline(ctx context.Context, p FeedParams) (*FeedResponse, error) {
+	var out FeedResponse
+	if err := c.query(ctx, "social.coves.feed.getTimeline", p.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDiscover returns posts from all public communities.
+func (c *Client) GetDiscover(ctx context.Context, p FeedParams) (*FeedResponse, error) {
+	var out FeedResponse
+	if err := c.query(ctx, "social.coves.feed.getDiscover", p.values(), &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetFrontPage returns the instance front page. It is a single ranked page
+// with no cursor; limit 0 uses the server default.
+func (c *Client) GetFrontPage(ctx context.Context, limit int) (*FeedResponse, error) {
+	params := url.Values{}
+	setInt(params, "limit", limit)
+
+	var out FeedResponse
+	if err := c.query(ctx, "social.coves.discover.getFrontPage", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetDiscussions returns the posts that link to a URL.
+func (c *Client) GetDiscussions(ctx context.Context, p DiscussionsParams) (*FeedResponse, error) {
+	params := url.Values{"url": {p.URL}}
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "limit", p.Limit)
+
+	var out FeedResponse
+	if err := c.query(ctx, "social.coves.feed.getDiscussions", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,71 @@
+package covesclient
+
+import (
+	"context"
+	"net/url"
+)
+
+// AuthorPostsParams are the parameters for social.coves.actor.getPosts.
+type AuthorPostsParams struct {
+	Actor     string // DID or handle (required)
+	Filter    string // posts_with_replies (default), posts_no_replies, posts_with_media
+	Community string // only posts in this community (DID or handle)
+	Cursor    string
+	Limit     int
+}
+
+// PostParams are the parameters for social.coves.feed.getPost.
+type PostParams struct {
+	URI             string // post AT-URI (required)
+	IncludeComments bool   // include the first top-level comments, sorted hot
+	CommentLimit    int    // 0 uses the server default
+}
+
+// PostResponse is the output of social.coves.feed.getPost.
+type PostResponse struct {
+	Post     *PostView            `json:"post"`
+	Comments []*ThreadViewComment `json:"comments,omitempty"`
+}
+
+// CreatePost creates a post in a community as the authenticated user.
+// AuthorDID must be left empty; the server sets it from the session.
+func (c *Client) CreatePost(ctx context.Context, req CreatePostRequest) (*CreatePostResponse, error) {
+	var out CreatePostResponse
+	if err := c.procedure(ctx, "social.coves.community.post.create", req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeletePost deletes one of the authenticated user's posts.
+func (c *Client) DeletePost(ctx context.Context, uri string) error {
+	return c.procedure(ctx, "social.coves.community.post.delete", DeletePostInput{URI: uri}, nil)
+}
+
+// GetPost returns a single post, optionally with its first top-level comments.
+func (c *Client) GetPost(ctx context.Context, p PostParams) (*PostResponse, error) {
+	params := url.Values{"uri": {p.URI}}
+	setBool(params, "includeComments", p.IncludeComments)
+	setInt(params, "commentLimit", p.CommentLimit)
+
+	var out PostResponse
+	if err := c.query(ctx, "social.coves.feed.getPost", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetActorPosts returns the posts an actor has authored.
+func (c *Client) GetActorPosts(ctx context.Context, p AuthorPostsParams) (*FeedResponse, error) {
+	params := url.Values{"actor": {p.Actor}}
+	setString(params, "filter", p.Filter)
+	setString(params, "community", p.Community)
+	setString(params, "cursor", p.Cursor)
+	setInt(params, "limit", p.Limit)
+
+	var out FeedResponse
+	if err := c.query(ctx, "social.coves.actor.getPosts", params, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,660 @@
+package covesclient
+
+import "time"
+
+// The request and response types below are the API's wire shapes. They are
+// declared here rather than imported from the server so the SDK doesn't pull
+// in server packages; types_test.go checks them against the structs the
+// handlers encode and decode so they can't drift.
+// Shapes the handlers build inline (maps) are declared in the endpoint files.
+
+// StrongRef is a reference to a record at a specific version.
+type StrongRef struct {
+	URI string `json:"uri"`
+	CID string `json:"cid"`
+}
+
+// ReplyRef locates a reply in its thread.
+type ReplyRef struct {
+	Root   StrongRef `json:"root"`
+	Parent StrongRef `json:"parent"`
+}
+
+// Communities
+
+// CommunityView is the summary of a community used in lists and search.
+type CommunityView struct {
+	DID             string                `json:"did"`
+	Handle          string                `json:"handle,omitempty"`
+	Name            string                `json:"name"`
+	DisplayName     string                `json:"displayName,omitempty"`
+	DisplayHandle   string                `json:"displayHandle,omitempty"`
+	Avatar          string                `json:"avatar,omitempty"` // URL
+	Visibility      string                `json:"visibility,omitempty"`
+	Categories      []string              `json:"categories,omitempty"`
+	SubscriberCount int                   `json:"subscriberCount"`
+	MemberCount     int                   `json:"memberCount"`
+	PostCount       int                   `json:"postCount"`
+	Viewer          *CommunityViewerState `json:"viewer,omitempty"`
+}
+
+// CommunityViewDetailed is a full community profile, as returned by social.coves.community.get.
+type CommunityViewDetailed struct {
+	CreatedAt               time.Time             `json:"createdAt"`
+	DID                     string                `json:"did"`
+	Handle                  string                `json:"handle,omitempty"`
+	Name                    string                `json:"name"`
+	DisplayName             string                `json:"displayName,omitempty"`
+	DisplayHandle           string                `json:"displayHandle,omitempty"`
+	Description             string                `json:"description,omitempty"`
+	Avatar                  string                `json:"avatar,omitempty"` // URL
+	Banner                  string                `json:"banner,omitempty"` // URL
+	CreatedByDID            string                `json:"createdBy,omitempty"`
+	HostedByDID             string                `json:"hostedBy,omitempty"`
+	Visibility              string                `json:"visibility,omitempty"`
+	ModerationType          string                `json:"moderationType,omitempty"`
+	ContentWarnings         []string              `json:"contentWarnings,omitempty"`
+	Categories              []string              `json:"categories,omitempty"`
+	Flairs                  []Flair               `json:"flairs,omitempty"`
+	PostingRules            PostingRules          `json:"postingRules"`
+	ScoreHidingHours        int                   `json:"scoreHidingHours"`
+	CollapseThreshold       int                   `json:"collapseThreshold"`
+	CrowdControl            string                `json:"crowdControl"`
+	Rules                   *CommunityRules       `json:"rules,omitempty"`
+	AgeConfirmationRequired bool                  `json:"ageConfirmationRequired"`
+	Gated                   bool                  `json:"gated,omitempty"` // The viewer hasn't confirmed their age yet
+	AllowExternalDiscovery  bool                  `json:"allowExternalDiscovery"`
+	SubscriberCount         int                   `json:"subscriberCount"`
+	MemberCount             int                   `json:"memberCount"`
+	PostCount               int                   `json:"postCount"`
+	WeeklyActiveUsers       int                   `json:"weeklyActiveUsers"`
+	MonthlyActiveUsers      int                   `json:"monthlyActiveUsers"`
+	Viewer                  *CommunityViewerState `json:"viewer,omitempty"`
+}
+
+// CommunityViewerState is the authenticated viewer's relationship to a community.
+type CommunityViewerState struct {
+	Subscribed *bool `json:"subscribed,omitempty"`
+	Member     *bool `json:"member,omitempty"`
+}
+
+// SubscribedCommunityView is one of the viewer's subscriptions.
+type SubscribedCommunityView struct {
+	SubscribedAt      time.Time      `json:"subscribedAt"`
+	LastPostAt        *time.Time     `json:"lastPostAt,omitempty"`
+	Community         *CommunityView `json:"community"`
+	ContentVisibility int            `json:"contentVisibility"`
+}
+
+// Flair is a post tag a community offers.
+type Flair struct {
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// PostingRules are the checks a community applies to new posts.
+type PostingRules struct {
+	RequireAltText bool `json:"requireAltText"`
+}
+
+// CommunityRules is a community's rules document.
+type CommunityRules struct {
+	UpdatedAt time.Time       `json:"updatedAt"`
+	Markdown  string          `json:"markdown,omitempty"`
+	RecordURI string          `json:"uri"`
+	RecordCID string          `json:"cid"`
+	Rules     []CommunityRule `json:"rules"`
+}
+
+// CommunityRule is a single numbered rule.
+type CommunityRule struct {
+	CreatedAt   time.Time `json:"createdAt"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+}
+
+// CreateCommunityRequest is the input of social.coves.community.create.
+// The creator is the authenticated user.
+type CreateCommunityRequest struct {
+	Name                   string   `json:"name"`
+	DisplayName            string   `json:"displayName,omitempty"`
+	Description            string   `json:"description"`
+	Language               string   `json:"language,omitempty"`
+	Visibility             string   `json:"visibility"`
+	AvatarBlob             []byte   `json:"avatarBlob,omitempty"`
+	BannerBlob             []byte   `json:"bannerBlob,omitempty"`
+	AvatarMimeType         string   `json:"avatarMimeType,omitempty"`
+	BannerMimeType         string   `json:"bannerMimeType,omitempty"`
+	Rules                  []string `json:"rules,omitempty"`
+	Categories             []string `json:"categories,omitempty"`
+	AllowExternalDiscovery bool     `json:"allowExternalDiscovery"`
+}
+
+// UpdateCommunityRequest is the input of social.coves.community.update.
+// Nil fields are left unchanged.
+type UpdateCommunityRequest struct {
+	CommunityDID           string        `json:"communityDid"`
+	DisplayName            *string       `json:"displayName,omitempty"`
+	Description            *string       `json:"description,omitempty"`
+	AvatarBlob             []byte        `json:"avatarBlob,omitempty"`
+	BannerBlob             []byte        `json:"bannerBlob,omitempty"`
+	AvatarMimeType         string        `json:"avatarMimeType,omitempty"`
+	BannerMimeType         string        `json:"bannerMimeType,omitempty"`
+	Visibility             *string       `json:"visibility,omitempty"`
+	AllowExternalDiscovery *bool         `json:"allowExternalDiscovery,omitempty"`
+	ModerationType         *string       `json:"moderationType,omitempty"`
+	ContentWarnings        []string      `json:"contentWarnings,omitempty"`
+	Categories             *[]string     `json:"categories,omitempty"` // An empty list removes them
+	Flairs                 *[]Flair      `json:"flairs,omitempty"`     // An empty list removes them
+	PostingRules           *PostingRules `json:"postingRules,omitempty"`
+	ScoreHidingHours       *int          `json:"scoreHidingHours,omitempty"`  // 0-24; 0 disables score hiding
+	CollapseThreshold      *int          `json:"collapseThreshold,omitempty"` // -1000 to 0
+	CrowdControl           *string       `json:"crowdControl,omitempty"`      // off, low or high
+}
+
+// UpdateRulesRequest is the input of social.coves.community.updateRules.
+type UpdateRulesRequest struct {
+	CommunityDID string          `json:"community"`
+	Markdown     string          `json:"markdown"`
+	Rules        []CommunityRule `json:"rules"`
+}
+
+// Posts and feeds
+
+// PostView is a post as the API presents it.
+type PostView struct {
+	IndexedAt time.Time     `json:"indexedAt"`
+	CreatedAt time.Time     `json:"createdAt"`
+	Record    any           `json:"record,omitempty"`
+	Embed     any           `json:"embed,omitempty"`
+	Language  *string       `json:"language,omitempty"`
+	EditedAt  *time.Time    `json:"editedAt,omitempty"`
+	Viewer    *ViewerState  `json:"viewer,omitempty"`
+	Via       *ViaView      `json:"via,omitempty"`    // Set when an aggregator created the post
+	Labels    *LabelsView   `json:"labels,omitempty"` // Community, self and moderator labels merged
+	Author    *AuthorView   `json:"author"`
+	Stats     *PostStats    `json:"stats,omitempty"`
+	Community *CommunityRef `json:"community"`
+	RKey      string        `json:"rkey"`
+	CID       string        `json:"cid"`
+	URI       string        `json:"uri"`
+}
+
+// PostStats are a post's vote and comment counts.
+// While ScoreHidden is set the vote counts are sent as null and decode as zero.
+type PostStats struct {
+	TagCounts            map[string]int `json:"tagCounts,omitempty"`
+	LastActivityAt       *time.Time     `json:"lastActivityAt,omitempty"`
+	Upvotes              int            `json:"upvotes"`
+	Downvotes            int            `json:"downvotes"`
+	Score                int            `json:"score"`
+	CommentCount         int            `json:"commentCount"`
+	TopLevelCommentCount int            `json:"topLevelCommentCount"`
+	ShareCount           int            `json:"shareCount,omitempty"`
+	ScoreHidden          bool           `json:"scoreHidden,omitempty"`
+}
+
+// ViewerState is the authenticated viewer's relationship to a post.
+type ViewerState struct {
+	Vote     *string  `json:"vote,omitempty"`
+	VoteURI  *string  `json:"voteUri,omitempty"`
+	SavedURI *string  `json:"savedUri,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Saved    bool     `json:"saved"`
+}
+
+// ViaView is the aggregator that created a post.
+type ViaView struct {
+	Avatar      *string `json:"avatar,omitempty"`
+	DID         string  `json:"did"`
+	DisplayName string  `json:"displayName"`
+}
+
+// LabelsView are the effective labels on a post.
+type LabelsView struct {
+	Blur   string   `json:"blur,omitempty"`
+	Values []string `json:"values"`
+}
+
+// AuthorView is the author of a post or comment.
+type AuthorView struct {
+	Badges         *AuthorBadges `json:"badges,omitempty"`
+	DisplayName    *string       `json:"displayName,omitempty"`
+	Avatar         *string       `json:"avatar,omitempty"`
+	Reputation     *int          `json:"reputation,omitempty"`
+	DID            string        `json:"did"`
+	Handle         string        `json:"handle"`
+	ConfusableFlag bool          `json:"confusableFlag,omitempty"` // The handle imitates another user's
+}
+
+// AuthorBadges mark an author's role in the thread or community.
+type AuthorBadges struct {
+	IsOP        bool `json:"isOP,omitempty"`
+	IsModerator bool `json:"isModerator,omitempty"`
+	IsCommunity bool `json:"isCommunity,omitempty"`
+}
+
+// CommunityRef is the community a post belongs to.
+type CommunityRef struct {
+	Avatar *string `json:"avatar,omitempty"`
+	DID    string  `json:"did"`
+	Handle string  `json:"handle"`
+	Name   string  `json:"name"`
+}
+
+// SelfLabels are the labels an author puts on their own post.
+type SelfLabels struct {
+	Values []SelfLabel `json:"values"`
+}
+
+// SelfLabel is a single self-applied label.
+type SelfLabel struct {
+	Neg *bool  `json:"neg,omitempty"`
+	Val string `json:"val"`
+}
+
+// CreatePostRequest is the input of social.coves.community.post.create.
+// The author is the authenticated user.
+type CreatePostRequest struct {
+	OriginalAuthor any            `json:"originalAuthor,omitempty"`
+	FederatedFrom  any            `json:"federatedFrom,omitempty"`
+	Location       any            `json:"location,omitempty"`
+	Title          *string        `json:"title,omitempty"`
+	Content        *string        `json:"content,omitempty"`
+	Embed          map[string]any `json:"embed,omitempty"`
+	ThumbnailURL   *string        `json:"thumbnailUrl,omitempty"`
+	Labels         *SelfLabels    `json:"labels,omitempty"`
+	Community      string         `json:"community"`
+	Facets         []any          `json:"facets,omitempty"`
+	Tags           []string       `json:"tags,omitempty"`   // Flair names; unknown ones are dropped
+	DryRun         bool           `json:"dryRun,omitempty"` // Run every check without writing the post
+}
+
+// CreatePostResponse is the output of social.coves.community.post.create.
+type CreatePostResponse struct {
+	DryRun *CreatePostDryRun `json:"dryRun,omitempty"` // Set instead of URI/CID for a dry run
+	URI    string            `json:"uri,omitempty"`
+	CID    string            `json:"cid,omitempty"`
+}
+
+// CreatePostDryRun is the record a dry run would have written.
+type CreatePostDryRun struct {
+	Record                 map[string]any `json:"record"`
+	PendingThumbnail       string         `json:"pendingThumbnail,omitempty"`
+	Repo                   string         `json:"repo"`
+	Collection             string         `json:"collection"`
+	Quota                  []*PostQuota   `json:"quota,omitempty"` // Aggregators only
+	CredentialsNeedRefresh bool           `json:"credentialsNeedRefresh"`
+}
+
+// PostQuota is an aggregator's posting allowance in one window.
+type PostQuota struct {
+	Window    string `json:"window"` // hour or day
+	Limit     int    `json:"limit"`
+	Used      int    `json:"used"`
+	Remaining int    `json:"remaining"`
+}
+
+// DeletePostInput is the input of social.coves.community.post.delete.
+type DeletePostInput struct {
+	URI string `json:"uri"`
+}
+
+// FeedResponse is a page of a feed.
+type FeedResponse struct {
+	Cursor *string         `json:"cursor,omitempty"`
+	Feed   []*FeedViewPost `json:"feed"`
+	Gaps   []*Gap          `json:"gaps,omitempty"` // Community feed and timeline only
+}
+
+// FeedViewPost is a post in a feed.
+type FeedViewPost struct {
+	Post   *PostView   `json:"post"`
+	Reason *FeedReason `json:"reason,omitempty"`
+	Reply  *ReplyRef   `json:"reply,omitempty"`
+}
+
+// FeedReason is why a post appears in a feed.
+type FeedReason struct {
+	Type   string        `json:"$type"`
+	Repost *ReasonRepost `json:"repost,omitempty"`
+	Pin    *ReasonPin    `json:"pin,omitempty"`
+}
+
+// ReasonRepost is a repost reason.
+type ReasonRepost struct {
+	By        *AuthorView `json:"by"`
+	IndexedAt string      `json:"indexedAt"`
+}
+
+// ReasonPin is a pinned post reason.
+type ReasonPin struct {
+	Community *CommunityRef `json:"community"`
+}
+
+// Gap is a window of posts the instance may have missed, overlapped by a feed page.
+type Gap struct {
+	BackfilledAt *time.Time `json:"backfilledAt,omitempty"`
+	Start        time.Time  `json:"start"`
+	End          time.Time  `json:"end"`
+	DetectedAt   time.Time  `json:"detectedAt"`
+	Consumer     string     `json:"consumer"`
+	ID           int64      `json:"id"`
+}
+
+// Comments
+
+// CommentView is a comment as the API presents it.
+type CommentView struct {
+	Embed          any                 `json:"embed,omitempty"`
+	Record         any                 `json:"record"`
+	Viewer         *CommentViewerState `json:"viewer,omitempty"`
+	Author         *AuthorView         `json:"author"`
+	Post           *StrongRef          `json:"post"`
+	Parent         *StrongRef          `json:"parent,omitempty"`
+	Stats          *CommentStats       `json:"stats"`
+	CreatedAt      string              `json:"createdAt"`
+	IndexedAt      string              `json:"indexedAt"`
+	URI            string              `json:"uri"`
+	CID            string              `json:"cid"`
+	IsDeleted      bool                `json:"isDeleted,omitempty"`
+	DeletionReason *string             `json:"deletionReason,omitempty"`
+	DeletedAt      *string             `json:"deletedAt,omitempty"`
+	LastEditedAt   *string             `json:"lastEditedAt,omitempty"`
+	Edited         bool                `json:"edited,omitempty"`
+	Collapsed      bool                `json:"collapsed,omitempty"` // Below the community's collapse threshold
+}
+
+// CommentStats are a comment's vote and reply counts.
+// While ScoreHidden is set the vote counts are sent as null and decode as zero.
+type CommentStats struct {
+	Upvotes     int  `json:"upvotes"`
+	Downvotes   int  `json:"downvotes"`
+	Score       int  `json:"score"`
+	ReplyCount  int  `json:"replyCount"`
+	ScoreHidden bool `json:"scoreHidden,omitempty"`
+}
+
+// CommentViewerState is the authenticated viewer's vote on a comment.
+type CommentViewerState struct {
+	Vote    *string `json:"vote,omitempty"` // "up" or "down"
+	VoteURI *string `json:"voteUri,omitempty"`
+}
+
+// ThreadViewComment is a comment with its nested replies.
+type ThreadViewComment struct {
+	Comment        *CommentView         `json:"comment"`
+	Replies        []*ThreadViewComment `json:"replies,omitempty"`
+	HasMore        bool                 `json:"hasMore,omitempty"`
+	ContinueThread bool                 `json:"continueThread,omitempty"` // Replies are past the max depth; follow the permalink
+}
+
+// CommentThreadView is a comment permalink: the comment, its ancestors and its replies.
+type CommentThreadView struct {
+	Focus            *CommentView         `json:"focus"`
+	Ancestors        []*CommentView       `json:"ancestors"`
+	Replies          []*ThreadViewComment `json:"replies"`
+	HasMoreAncestors bool                 `json:"hasMoreAncestors,omitempty"`
+}
+
+// CreateCommentInput is the input of social.coves.community.comment.create.
+type CreateCommentInput struct {
+	Reply   ReplyRef `json:"reply"`
+	Content string   `json:"content"`
+	Facets  []any    `json:"facets,omitempty"`
+	Embed   any      `json:"embed,omitempty"`
+	Langs   []string `json:"langs,omitempty"`
+	Labels  any      `json:"labels,omitempty"`
+}
+
+// CreateCommentOutput is the output of social.coves.community.comment.create.
+type CreateCommentOutput = StrongRef
+
+// UpdateCommentInput is the input of social.coves.community.comment.update.
+type UpdateCommentInput struct {
+	URI     string   `json:"uri"`
+	Content string   `json:"content"`
+	Facets  []any    `json:"facets,omitempty"`
+	Embed   any      `json:"embed,omitempty"`
+	Langs   []string `json:"langs,omitempty"`
+	Labels  any      `json:"labels,omitempty"`
+}
+
+// UpdateCommentOutput is the output of social.coves.community.comment.update.
+type UpdateCommentOutput = StrongRef
+
+// DeleteCommentInput is the input of social.coves.community.comment.delete.
+type DeleteCommentInput struct {
+	URI string `json:"uri"`
+}
+
+// CommentsPage is a page of an actor's comments or of comment search results.
+type CommentsPage struct {
+	Comments []*CommentView `json:"comments"`
+	Cursor   *string        `json:"cursor,omitempty"`
+}
+
+// Votes
+
+// CreateVoteInput is the input of social.coves.feed.vote.create.
+type CreateVoteInput struct {
+	Subject   StrongRef `json:"subject"`
+	Direction string    `json:"direction"`
+}
+
+// CreateVoteOutput is the output of social.coves.feed.vote.create.
+type CreateVoteOutput = StrongRef
+
+// DeleteVoteInput is the input of social.coves.feed.vote.delete.
+type DeleteVoteInput struct {
+	Subject StrongRef `json:"subject"`
+}
+
+// Actors
+
+// ProfileViewDetailed is a user's profile.
+type ProfileViewDetailed struct {
+	CreatedAt   time.Time     `json:"createdAt"`
+	Stats       *ProfileStats `json:"stats,omitempty"`
+	DID         string        `json:"did"`
+	Handle      string        `json:"handle,omitempty"`
+	DisplayName string        `json:"displayName,omitempty"`
+	Bio         string        `json:"description,omitempty"`
+	Avatar      string        `json:"avatar,omitempty"` // URL
+	Banner      string        `json:"banner,omitempty"` // URL
+}
+
+// ProfileStats are a user's activity counts.
+type ProfileStats struct {
+	PostCount       int `json:"postCount"`
+	CommentCount    int `json:"commentCount"`
+	CommunityCount  int `json:"communityCount"` // Communities subscribed to
+	Reputation      int `json:"reputation"`
+	MembershipCount int `json:"membershipCount"`
+}
+
+// RegisterAccountRequest is the input of social.coves.actor.signup.
+type RegisterAccountRequest struct {
+	Handle     string `json:"handle"`
+	Email      string `json:"email"`
+	Password   string `json:"password"`
+	InviteCode string `json:"inviteCode,omitempty"`
+}
+
+// UpdateProfileRequest is the input of social.coves.actor.updateProfile.
+type UpdateProfileRequest struct {
+	DisplayName    *string `json:"displayName,omitempty"`
+	Bio            *string `json:"bio,omitempty"`
+	AvatarBlob     []byte  `json:"avatarBlob,omitempty"`
+	AvatarMimeType string  `json:"avatarMimeType,omitempty"`
+	BannerBlob     []byte  `json:"bannerBlob,omitempty"`
+	BannerMimeType string  `json:"bannerMimeType,omitempty"`
+}
+
+// UpdateProfileResponse is the output of social.coves.actor.updateProfile.
+type UpdateProfileResponse = StrongRef
+
+// DeleteAccountResponse is the output of social.coves.actor.deleteAccount.
+type DeleteAccountResponse struct {
+	Success bool   `json:"success"`
+	Message string `json:"message,omitempty"`
+}
+
+// GetSubscriptionsResponse is the output of social.coves.actor.getSubscriptions.
+type GetSubscriptionsResponse struct {
+	Cursor        string                     `json:"cursor,omitempty"`
+	Subscriptions []*SubscribedCommunityView `json:"subscriptions"`
+}
+
+// Aggregators
+
+// AggregatorView is an aggregator's service declaration.
+type AggregatorView struct {
+	DID           string  `json:"did"`
+	DisplayName   string  `json:"displayName"`
+	Description   *string `json:"description,omitempty"`
+	Avatar        *string `json:"avatar,omitempty"`
+	ConfigSchema  any     `json:"configSchema,omitempty"`
+	SourceURL     *string `json:"sourceUrl,omitempty"`
+	MaintainerDID *string `json:"maintainer,omitempty"`
+	CreatedAt     string  `json:"createdAt"`
+	RecordURI     string  `json:"recordUri"`
+}
+
+// AggregatorViewDetailed is an aggregator with its usage stats.
+type AggregatorViewDetailed struct {
+	DID           string          `json:"did"`
+	DisplayName   string          `json:"displayName"`
+	Description   *string         `json:"description,omitempty"`
+	Avatar        *string         `json:"avatar,omitempty"`
+	ConfigSchema  any             `json:"configSchema,omitempty"`
+	SourceURL     *string         `json:"sourceUrl,omitempty"`
+	MaintainerDID *string         `json:"maintainer,omitempty"`
+	CreatedAt     string          `json:"createdAt"`
+	RecordURI     string          `json:"recordUri"`
+	Stats         AggregatorStats `json:"stats"`
+}
+
+// AggregatorStats are an aggregator's usage counts.
+type AggregatorStats struct {
+	CommunitiesUsing int `json:"communitiesUsing"`
+	PostsCreated     int `json:"postsCreated"`
+}
+
+// CommunityAuthView is a community's authorization of an aggregator, seen from the aggregator.
+type CommunityAuthView struct {
+	Config          any            `json:"config,omitempty"`
+	MaxPostsPerHour *int           `json:"maxPostsPerHour,omitempty"`
+	MaxPostsPerDay  *int           `json:"maxPostsPerDay,omitempty"`
+	Aggregator      AggregatorView `json:"aggregator"`
+	CreatedAt       string         `json:"createdAt"`
+	RecordURI       string         `json:"recordUri,omitempty"`
+	Enabled         bool           `json:"enabled"`
+}
+
+// AuthorizationView is an aggregator's authorization, seen from the community.
+type AuthorizationView struct {
+	Config          any     `json:"config,omitempty"`
+	MaxPostsPerHour *int    `json:"maxPostsPerHour,omitempty"`
+	MaxPostsPerDay  *int    `json:"maxPostsPerDay,omitempty"`
+	CommunityHandle *string `json:"communityHandle,omitempty"`
+	CommunityName   *string `json:"communityName,omitempty"`
+	CreatedBy       *string `json:"createdBy,omitempty"`
+	DisabledAt      *string `json:"disabledAt,omitempty"`
+	DisabledBy      *string `json:"disabledBy,omitempty"`
+	AggregatorDID   string  `json:"aggregatorDid"`
+	CommunityDID    string  `json:"communityDid"`
+	CreatedAt       string  `json:"createdAt"`
+	RecordURI       string  `json:"recordUri,omitempty"`
+	Enabled         bool    `json:"enabled"`
+}
+
+// GetAuthorizationsResponse is the output of social.coves.aggregator.getAuthorizations.
+type GetAuthorizationsResponse struct {
+	Cursor         *string             `json:"cursor,omitempty"`
+	Authorizations []CommunityAuthView `json:"authorizations"`
+}
+
+// ListForCommunityResponse is the output of social.coves.aggregator.listForCommunity.
+type ListForCommunityResponse struct {
+	Cursor      *string             `json:"cursor,omitempty"`
+	Aggregators []AuthorizationView `json:"aggregators"`
+}
+
+// ServiceView is an aggregator in the public directory.
+type ServiceView struct {
+	Description      *string `json:"description,omitempty"`
+	Avatar           *string `json:"avatar,omitempty"`
+	SourceURL        *string `json:"sourceUrl,omitempty"`
+	MaintainerDID    *string `json:"maintainer,omitempty"`
+	MaintainerHandle *string `json:"maintainerHandle,omitempty"`
+	DID              string  `json:"did"`
+	DisplayName      string  `json:"displayName"`
+	CreatedAt        string  `json:"createdAt"`
+	RecordURI        string  `json:"recordUri"`
+	CommunitiesUsing int     `json:"communitiesUsing"`
+}
+
+// AuthorizedCommunityView is a community that has authorized a directory aggregator.
+type AuthorizedCommunityView struct {
+	DisplayName  *string `json:"displayName,omitempty"`
+	Avatar       *string `json:"avatar,omitempty"`
+	DID          string  `json:"did"`
+	Handle       string  `json:"handle"`
+	Name         string  `json:"name"`
+	AuthorizedAt string  `json:"authorizedAt"`
+}
+
+// ListServicesResponse is the output of social.coves.aggregator.listServices.
+type ListServicesResponse struct {
+	Cursor   *string       `json:"cursor,omitempty"`
+	Services []ServiceView `json:"services"`
+}
+
+// GetServiceResponse is the output of social.coves.aggregator.getService.
+type GetServiceResponse struct {
+	ConfigSchema any                       `json:"configSchema,omitempty"`
+	Communities  []AuthorizedCommunityView `json:"communities"`
+	Service      ServiceView               `json:"service"`
+}
+
+// RegisterAggregatorRequest is the input of social.coves.aggregator.register.
+type RegisterAggregatorRequest struct {
+	DID    string `json:"did"`
+	Domain string `json:"domain"`
+}
+
+// RegisterAggregatorResult is the output of social.coves.aggregator.register.
+type RegisterAggregatorResult struct {
+	DID     string `json:"did"`
+	Handle  string `json:"handle"`
+	Message string `json:"message"`
+}
+
+// CreateAPIKeyResponse is the output of social.coves.aggregator.createApiKey.
+type CreateAPIKeyResponse struct {
+	Key       string `json:"key"`       // Shown once
+	KeyPrefix string `json:"keyPrefix"` // First 12 characters, for identification
+	DID       string `json:"did"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// GetAPIKeyResponse is the output of social.coves.aggregator.getApiKey.
+type GetAPIKeyResponse struct {
+	HasKey  bool        `json:"hasKey"`
+	KeyInfo *APIKeyView `json:"keyInfo,omitempty"` // Set when HasKey is true
+}
+
+// APIKeyView is an aggregator API key's metadata.
+type APIKeyView struct {
+	Prefix     string  `json:"prefix"`
+	CreatedAt  string  `json:"createdAt"`
+	LastUsedAt *string `json:"lastUsedAt,omitempty"`
+	IsRevoked  bool    `json:"isRevoked"`
+	RevokedAt  *string `json:"revokedAt,omitempty"`
+}
+
+// RevokeAPIKeyResponse is the output of social.coves.aggregator.revokeApiKey.
+type RevokeAPIKeyResponse struct {
+	RevokedAt string `json:"revokedAt"`
+}
@@ -0,0 +1,176 @@
+package covesclient
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	actorAPI "Coves/internal/api/handlers/actor"
+	aggregatorAPI "Coves/internal/api/handlers/aggregator"
+	commentAPI "Coves/internal/api/handlers/comments"
+	postAPI "Coves/internal/api/handlers/post"
+	userAPI "Coves/internal/api/handlers/user"
+	voteAPI "Coves/internal/api/handlers/vote"
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/consumergaps"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/timeline"
+	"Coves/internal/core/users"
+)
+
+// TestWireTypesMatchServer fails when a handler's request or response struct gains, loses or
+// renames a JSON field without the SDK's copy following
+func TestWireTypesMatchServer(t *testing.T) {
+	tests := []struct {
+		sdk        any
+		server     any
+		serverOnly []string // Set by the server from the session; clients must not send them
+		sdkOnly    []string // Fields this endpoint leaves out of a type the SDK shares across endpoints
+	}{
+		// Communities
+		{sdk: CommunityView{}, server: communities.CommunityView{}},
+		{sdk: CommunityViewDetailed{}, server: communities.CommunityViewDetailed{}},
+		{sdk: CommunityViewerState{}, server: communities.CommunityViewerState{}},
+		{sdk: SubscribedCommunityView{}, server: communities.SubscribedCommunityView{}},
+		{sdk: Flair{}, server: communities.Flair{}},
+		{sdk: PostingRules{}, server: communities.PostingRules{}},
+		{sdk: CommunityRules{}, server: communities.CommunityRules{}},
+		{sdk: CommunityRule{}, server: communities.Rule{}},
+		{sdk: CreateCommunityRequest{}, server: communities.CreateCommunityRequest{}, serverOnly: []string{"createdByDid", "hostedByDid"}},
+		{sdk: UpdateCommunityRequest{}, server: communities.UpdateCommunityRequest{}, serverOnly: []string{"updatedByDid"}},
+		{sdk: UpdateRulesRequest{}, server: communities.UpdateRulesRequest{}},
+
+		// Posts and feeds
+		{sdk: PostView{}, server: posts.PostView{}},
+		{sdk: PostStats{}, server: posts.PostStats{}},
+		{sdk: ViewerState{}, server: posts.ViewerState{}},
+		{sdk: ViaView{}, server: posts.ViaView{}},
+		{sdk: LabelsView{}, server: posts.LabelsView{}},
+		{sdk: AuthorView{}, server: posts.AuthorView{}},
+		{sdk: AuthorBadges{}, server: posts.AuthorBadges{}},
+		{sdk: CommunityRef{}, server: posts.CommunityRef{}},
+		{sdk: SelfLabels{}, server: posts.SelfLabels{}},
+		{sdk: SelfLabel{}, server: posts.SelfLabel{}},
+		{sdk: CreatePostRequest{}, server: posts.CreatePostRequest{}, serverOnly: []string{"authorDid"}},
+		{sdk: CreatePostResponse{}, server: posts.CreatePostResponse{}},
+		{sdk: CreatePostDryRun{}, server: posts.CreatePostDryRun{}},
+		{sdk: PostQuota{}, server: aggregators.PostQuota{}},
+		{sdk: DeletePostInput{}, server: postAPI.DeletePostInput{}},
+		{sdk: FeedResponse{}, server: communityFeeds.FeedResponse{}},
+		{sdk: FeedResponse{}, server: timeline.TimelineResponse{}},
+		{sdk: FeedResponse{}, server: discover.DiscoverResponse{}, sdkOnly: []string{"gaps"}},
+		{sdk: FeedResponse{}, server: posts.GetAuthorPostsResponse{}, sdkOnly: []string{"gaps"}},
+		{sdk: FeedViewPost{}, server: posts.FeedViewPost{}},
+		{sdk: FeedReason{}, server: posts.FeedReason{}},
+		{sdk: ReasonRepost{}, server: posts.ReasonRepost{}},
+		{sdk: ReasonPin{}, server: posts.ReasonPin{}},
+		{sdk: ReplyRef{}, server: posts.ReplyRef{}},
+		{sdk: StrongRef{}, server: posts.PostRef{}},
+		{sdk: Gap{}, server: consumergaps.Gap{}},
+
+		// Comments
+		{sdk: CommentView{}, server: comments.CommentView{}},
+		{sdk: CommentStats{}, server: comments.CommentStats{}},
+		{sdk: CommentViewerState{}, server: comments.CommentViewerState{}},
+		{sdk: StrongRef{}, server: comments.CommentRef{}},
+		{sdk: ThreadViewComment{}, server: comments.ThreadViewComment{}},
+		{sdk: CommentThreadView{}, server: comments.CommentThreadView{}},
+		{sdk: CommentsPage{}, server: comments.GetActorCommentsResponse{}},
+		{sdk: CommentsPage{}, server: comments.SearchCommentsResponse{}},
+		{sdk: CreateCommentInput{}, server: commentAPI.CreateCommentInput{}},
+		{sdk: ReplyRef{}, server: commentAPI.CreateCommentInput{}.Reply},
+		{sdk: StrongRef{}, server: commentAPI.CreateCommentOutput{}},
+		{sdk: UpdateCommentInput{}, server: commentAPI.UpdateCommentInput{}},
+		{sdk: StrongRef{}, server: commentAPI.UpdateCommentOutput{}},
+		{sdk: DeleteCommentInput{}, server: commentAPI.DeleteCommentInput{}},
+
+		// Votes
+		{sdk: CreateVoteInput{}, server: voteAPI.CreateVoteInput{}},
+		{sdk: StrongRef{}, server: voteAPI.CreateVoteInput{}.Subject},
+		{sdk: StrongRef{}, server: voteAPI.CreateVoteOutput{}},
+		{sdk: DeleteVoteInput{}, server: voteAPI.DeleteVoteInput{}},
+
+		// Actors
+		{sdk: ProfileViewDetailed{}, server: users.ProfileViewDetailed{}},
+		{sdk: ProfileStats{}, server: users.ProfileStats{}},
+		{sdk: RegisterAccountRequest{}, server: users.RegisterAccountRequest{}},
+		{sdk: UpdateProfileRequest{}, server: userAPI.UpdateProfileRequest{}},
+		{sdk: StrongRef{}, server: userAPI.UpdateProfileResponse{}},
+		{sdk: DeleteAccountResponse{}, server: userAPI.DeleteAccountResponse{}},
+		{sdk: GetSubscriptionsResponse{}, server: actorAPI.GetSubscriptionsResponse{}},
+
+		// Aggregators
+		{sdk: AggregatorView{}, server: aggregatorAPI.AggregatorView{}},
+		{sdk: AggregatorViewDetailed{}, server: aggregatorAPI.AggregatorViewDetailed{}},
+		{sdk: AggregatorStats{}, server: aggregatorAPI.AggregatorStats{}},
+		{sdk: CommunityAuthView{}, server: aggregatorAPI.CommunityAuthView{}},
+		{sdk: AuthorizationView{}, server: aggregatorAPI.AuthorizationView{}},
+		{sdk: GetAuthorizationsResponse{}, server: aggregatorAPI.GetAuthorizationsResponse{}},
+		{sdk: ListForCommunityResponse{}, server: aggregatorAPI.ListForCommunityResponse{}},
+		{sdk: ServiceView{}, server: aggregatorAPI.ServiceView{}},
+		{sdk: AuthorizedCommunityView{}, server: aggregatorAPI.AuthorizedCommunityView{}},
+		{sdk: ListServicesResponse{}, server: aggregatorAPI.ListServicesResponse{}},
+		{sdk: GetServiceResponse{}, server: aggregatorAPI.GetServiceResponse{}},
+		{sdk: RegisterAggregatorRequest{}, server: aggregatorAPI.RegisterRequest{}},
+		{sdk: RegisterAggregatorResult{}, server: aggregatorAPI.RegisterResponse{}},
+		{sdk: CreateAPIKeyResponse{}, server: aggregatorAPI.CreateAPIKeyResponse{}},
+		{sdk: GetAPIKeyResponse{}, server: aggregatorAPI.GetAPIKeyResponse{}},
+		{sdk: APIKeyView{}, server: aggregatorAPI.APIKeyView{}},
+		{sdk: RevokeAPIKeyResponse{}, server: aggregatorAPI.RevokeAPIKeyResponse{}},
+	}
+
+	for _, tt := range tests {
+		serverType := reflect.TypeOf(tt.server)
+		name := serverType.String()
+		if serverType.Name() == "" {
+			name = "inline " + reflect.TypeOf(tt.sdk).Name()
+		}
+		t.Run(name, func(t *testing.T) {
+			want := jsonFields(serverType)
+			for _, f := range tt.serverOnly {
+				delete(want, f)
+			}
+			for _, f := range tt.sdkOnly {
+				want[f] = true
+			}
+			got := jsonFields(reflect.TypeOf(tt.sdk))
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%T fields = %v, want %v (from %s)", tt.sdk, keys(got), keys(want), serverType)
+			}
+		})
+	}
+}
+
+// jsonFields returns the JSON names of a struct's encoded fields
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fields[name] = true
+	}
+	return fields
+}
+
+func keys(m map[string]bool) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,32 @@
+package covesclient
+
+import "context"
+
+// Vote directions.
+const (
+	VoteUp   = "up"
+	VoteDown = "down"
+)
+
+// Vote casts the authenticated user's vote on a post or comment.
+// Voting the same direction again toggles the vote off.
+func (c *Client) Vote(ctx context.Context, subjectURI, subjectCID, direction string) (*CreateVoteOutput, error) {
+	var in CreateVoteInput
+	in.Subject.URI = subjectURI
+	in.Subject.CID = subjectCID
+	in.Direction = direction
+
+	var out CreateVoteOutput
+	if err := c.procedure(ctx, "social.coves.feed.vote.create", in, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteVote removes the authenticated user's vote on a post or comment.
+func (c *Client) DeleteVote(ctx context.Context, subjectURI, subjectCID string) error {
+	var in DeleteVoteInput
+	in.Subject.URI = subjectURI
+	in.Subject.CID = subjectCID
+	return c.procedure(ctx, "social.coves.feed.vote.delete", in, nil)
+}
@@ -96,7 +96,8 @@ func TestRateLimiting_E2E_GeneralEndpoints(t *testing.T) {
 		handler.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusTooManyRequests, rr.Code, "Should return 429 Too Many Requests")
-		assert.Equal(t, "text/plain; charset=utf-8", rr.Header().Get("Content-Type"))
+		assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+		assert.Contains(t, rr.Body.String(), `"error":"RateLimitExceeded"`)
 	})
 
 	t.Run("Rate limits are per-client (IP isolation)", func(t *testing.T) {
@@ -261,34 +262,36 @@ func TestRateLimiting_E2E_AggregatorPosts(t *testing.T) {
 
 // TestRateLimiting_E2E_RateLimitHeaders tests that rate limit information is included in responses
 func TestRateLimiting_E2E_RateLimitHeaders(t *testing.T) {
-	t.Run("Current implementation does not include rate limit headers", func(t *testing.T) {
-		// CURRENT STATE: The middleware does not set rate limit headers
-		// FUTURE ENHANCEMENT: Add headers like:
-		// - X-RateLimit-Limit: Maximum requests allowed
-		// - X-RateLimit-Remaining: Requests remaining in window
-		// - X-RateLimit-Reset: Time when limit resets (Unix timestamp)
-		// - Retry-After: Seconds until limit resets (on 429 responses)
-
-		limiter := middleware.NewRateLimiter(5, 1*time.Minute)
+	t.Run("Responses include rate limit headers", func(t *testing.T) {
+		limiter := middleware.NewRateLimiter(2, 1*time.Minute)
 		testHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
 		})
 		handler := limiter.Middleware(testHandler)
 
-		req := httptest.NewRequest("GET", "/test", nil)
-		req.RemoteAddr = "192.168.1.120:12345"
-		rr := httptest.NewRecorder()
+		serve := func() *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", "/test", nil)
+			req.RemoteAddr = "192.168.1.120:12345"
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+			return rr
+		}
 
-		handler.ServeHTTP(rr, req)
+		rr := serve()
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+		assert.Equal(t, "1", rr.Header().Get("RateLimit-Remaining"))
+		assert.Equal(t, "60", rr.Header().Get("RateLimit-Reset"))
+		assert.Equal(t, "", rr.Header().Get("Retry-After"), "Retry-After is only sent when refused")
 
-		// Document current behavior: no rate limit headers
-		assert.Equal(t, "", rr.Header().Get("X-RateLimit-Limit"), "Currently no rate limit headers")
-		assert.Equal(t, "", rr.Header().Get("X-RateLimit-Remaining"), "Currently no rate limit headers")
-		assert.Equal(t, "", rr.Header().Get("X-RateLimit-Reset"), "Currently no rate limit headers")
-		assert.Equal(t, "", rr.Header().Get("Retry-After"), "Currently no Retry-After header")
+		rr = serve()
+		assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
 
-		t.Log("NOTE: Rate limit headers are not implemented yet. This is acceptable for Alpha.")
-		t.Log("Consider adding rate limit headers in a future enhancement.")
+		rr = serve()
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "0", rr.Header().Get("RateLimit-Remaining"))
+		assert.NotEmpty(t, rr.Header().Get("Retry-After"))
+		assert.Equal(t, rr.Header().Get("RateLimit-Reset"), rr.Header().Get("Retry-After"))
 	})
 
 	t.Run("429 response includes error message", func(t *testing.T) {
@@ -502,7 +505,7 @@ func TestRateLimiting_E2E_DifferentMethods(t *testing.T) {
 //
 // Rate Limit Response Behavior:
 //    - Status Code: 429 Too Many Requests
-//    - Error Message: 'Rate limit exceeded. Please try again later.'
+//    - Body: {"error": "RateLimitExceeded", "message": "Rate limit exceeded. Please try again later."}
 //    - Headers: Not implemented (acceptable for Alpha)
 //
 // Client Identification (priority order):
@@ -0,0 +1,195 @@
+package integration
+
+import (
+	"Coves/internal/api/routes"
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestActorGetSubscriptions covers social.coves.actor.getSubscriptions sorting, filtering,
+// and the recentActivity ordering maintained by the post consumer via communities.last_post_at
+func TestActorGetSubscriptions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	suffix := uniqueTestID()
+
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	userRepo := postgres.NewUserRepository(db)
+	userService := users.NewUserService(userRepo, nil, getTestPDSURL())
+	postConsumer := jetstream.NewPostEventConsumer(postRepo, communityRepo, userService, db)
+	communityService := communities.NewCommunityService(communityRepo, getTestPDSURL(), getTestInstanceDID(), "coves.social", nil, nil, nil)
+
+	subscriber := createTestUser(t, db, fmt.Sprintf("subs-%s.test", suffix), fmt.Sprintf("did:plc:subs%s", suffix))
+	author := createTestUser(t, db, fmt.Sprintf("subsauthor-%s.test", suffix), fmt.Sprintf("did:plc:subsauthor%s", suffix))
+
+	// Names chosen so alphabetical, subscribedAt, and recentActivity orders all differ
+	names := []string{"bravo-" + suffix, "alpha-" + suffix, "charlie-" + suffix}
+	communityDIDs := make(map[string]string, len(names))
+	baseTime := time.Now().Add(-time.Hour)
+	for i, name := range names {
+		did, err := createFeedTestCommunity(db, ctx, name, "subsowner"+suffix)
+		require.NoError(t, err)
+		communityDIDs[name] = did
+
+		_, err = db.ExecContext(ctx, `
+			INSERT INTO community_subscriptions (user_did, community_did, subscribed_at, content_visibility)
+			VALUES ($1, $2, $3, $4)
+		`, subscriber.DID, did, baseTime.Add(time.Duration(i)*time.Minute), i+1)
+		require.NoError(t, err)
+	}
+
+	list := func(t *testing.T, sort, q string) []string {
+		t.Helper()
+		results, err := communityService.GetSubscribedCommunities(ctx, communities.GetSubscriptionsRequest{
+			UserDID: subscriber.DID,
+			Sort:    sort,
+			Query:   q,
+			Limit:   10,
+		})
+		require.NoError(t, err)
+		ordered := make([]string, len(results))
+		for i, r := range results {
+			ordered[i] = r.Community.Name
+		}
+		return ordered
+	}
+
+	indexPost := func(t *testing.T, communityName string) {
+		t.Helper()
+		communityDID := communityDIDs[communityName]
+		err := postConsumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  communityDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev",
+				Operation:  "create",
+				Collection: "social.coves.community.post",
+				RKey:       generateTID(),
+				CID:        "bafypost",
+				Record: map[string]interface{}{
+					"$type":     "social.coves.community.post",
+					"community": communityDID,
+					"author":    author.DID,
+					"title":     "Activity in " + communityName,
+					"createdAt": time.Now().Format(time.RFC3339Nano),
+				},
+			},
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("subscribedAt is the default sort", func(t *testing.T) {
+		assert.Equal(t, []string{names[2], names[1], names[0]}, list(t, "", ""))
+	})
+
+	t.Run("alphabetical sort", func(t *testing.T) {
+		assert.Equal(t, []string{"alpha-" + suffix, "bravo-" + suffix, "charlie-" + suffix}, list(t, communities.SubscriptionSortAlphabetical, ""))
+	})
+
+	t.Run("q filters by name and treats wildcards literally", func(t *testing.T) {
+		assert.Equal(t, []string{"charlie-" + suffix}, list(t, "", "CHARLIE"))
+		assert.Empty(t, list(t, "", "%"))
+	})
+
+	t.Run("recentActivity ordering updates when a post is indexed", func(t *testing.T) {
+		indexPost(t, "alpha-"+suffix)
+		ordered := list(t, communities.SubscriptionSortRecentActivity, "")
+		require.Len(t, ordered, 3)
+		assert.Equal(t, "alpha-"+suffix, ordered[0], "community with the only post should sort first")
+
+		// A newer post elsewhere moves that community to the top
+		time.Sleep(10 * time.Millisecond)
+		indexPost(t, "charlie-"+suffix)
+		ordered = list(t, communities.SubscriptionSortRecentActivity, "")
+		assert.Equal(t, []string{"charlie-" + suffix, "alpha-" + suffix, "bravo-" + suffix}, ordered)
+	})
+
+	t.Run("future createdAt is clamped to index time", func(t *testing.T) {
+		communityDID := communityDIDs["bravo-"+suffix]
+		err := postConsumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  communityDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev",
+				Operation:  "create",
+				Collection: "social.coves.community.post",
+				RKey:       generateTID(),
+				CID:        "bafypost",
+				Record: map[string]interface{}{
+					"$type":     "social.coves.community.post",
+					"community": communityDID,
+					"author":    author.DID,
+					"title":     "From the future",
+					"createdAt": time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		var lastPostAt time.Time
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT last_post_at FROM communities WHERE did = $1`, communityDID).Scan(&lastPostAt))
+		assert.False(t, lastPostAt.After(time.Now().Add(time.Minute)), "last_post_at must not be in the future")
+	})
+
+	t.Run("XRPC endpoint returns hydrated subscriptions", func(t *testing.T) {
+		authMiddleware, token := CreateTestOAuthMiddleware(subscriber.DID)
+		r := chi.NewRouter()
+		routes.RegisterActorRoutes(routes.NewRegistrar(r, authMiddleware), nil, userService, nil, nil, nil, communityService, nil, nil, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getSubscriptions?sort=alphabetical&limit=2", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var resp struct {
+			Cursor        string `json:"cursor"`
+			Subscriptions []struct {
+				SubscribedAt time.Time `json:"subscribedAt"`
+				Community    struct {
+					Handle          string `json:"handle"`
+					Name            string `json:"name"`
+					SubscriberCount int    `json:"subscriberCount"`
+				} `json:"community"`
+				ContentVisibility int `json:"contentVisibility"`
+			} `json:"subscriptions"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Len(t, resp.Subscriptions, 2)
+		assert.Equal(t, "alpha-"+suffix, resp.Subscriptions[0].Community.Name)
+		assert.NotEmpty(t, resp.Subscriptions[0].Community.Handle)
+		assert.Equal(t, 2, resp.Subscriptions[0].ContentVisibility)
+		assert.False(t, resp.Subscriptions[0].SubscribedAt.IsZero())
+		assert.Equal(t, "2", resp.Cursor)
+
+		// Unauthenticated requests are rejected
+		req = httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.actor.getSubscriptions", nil)
+		rec = httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
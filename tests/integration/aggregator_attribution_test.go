@@ -0,0 +1,178 @@
+package integration
+
+import (
+	"Coves/internal/api/handlers/communityFeed"
+	"Coves/internal/api/handlers/discover"
+	"Coves/internal/api/handlers/timeline"
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	discoverCore "Coves/internal/core/discover"
+	timelineCore "Coves/internal/core/timeline"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAggregatorAttribution_HidePreference tests that aggregator posts carry "via" attribution,
+// and that hideAggregatorPosts removes them from the timeline and discover but not community feeds
+func TestAggregatorAttribution_HidePreference(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	userDID := fmt.Sprintf("did:plc:prefuser-%d", testID)
+	aggregatorDID := fmt.Sprintf("did:plc:rssbot-%d", testID)
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO users (did, handle, pds_url)
+		VALUES ($1, $2, $3)
+	`, userDID, fmt.Sprintf("prefuser-%d.test", testID), "https://bsky.social")
+	require.NoError(t, err)
+
+	aggregatorRepo := postgres.NewAggregatorRepository(db)
+	require.NoError(t, aggregatorRepo.CreateAggregator(ctx, &aggregators.Aggregator{
+		DID:         aggregatorDID,
+		DisplayName: "RSS Bot",
+		AvatarURL:   "bafyavatar",
+		PDSURL:      "https://pds.example.com",
+		CreatedAt:   time.Now(),
+		IndexedAt:   time.Now(),
+		RecordURI:   fmt.Sprintf("at://%s/social.coves.aggregator.service/self", aggregatorDID),
+		RecordCID:   "bafyservice",
+	}))
+
+	communityDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("news-%d", testID), fmt.Sprintf("owner-%d.test", testID))
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO community_subscriptions (user_did, community_did, content_visibility)
+		VALUES ($1, $2, 3)
+	`, userDID, communityDID)
+	require.NoError(t, err)
+
+	humanPostURI := createTestPost(t, db, communityDID, "did:plc:alice", "Human post", 10, time.Now().Add(-1*time.Hour))
+	botPostURI := createTestPost(t, db, communityDID, aggregatorDID, "Aggregator post", 10, time.Now().Add(-30*time.Minute))
+
+	timelineHandler := timeline.NewGetTimelineHandler(
+		timelineCore.NewTimelineService(postgres.NewTimelineRepository(db, newTestCursorSigner())),
+		nil, nil, nil, aggregatorRepo)
+	discoverHandler := discover.NewGetDiscoverHandler(
+		discoverCore.NewDiscoverService(postgres.NewDiscoverRepository(db, newTestCursorSigner())),
+		nil, nil, nil, aggregatorRepo)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	communityService := communities.NewCommunityServiceWithPDSFactory(
+		communityRepo, "http://localhost:3001", "did:web:test.coves.social", "test.coves.social", nil, nil, nil)
+	communityHandler := communityFeed.NewGetCommunityHandler(
+		communityFeeds.NewCommunityFeedService(postgres.NewCommunityFeedRepository(db, newTestCursorSigner()), communityService),
+		nil, nil, nil, aggregatorRepo)
+
+	// feedURIs calls a feed handler as the user and returns the post URIs and the bot post's via DID
+	feedURIs := func(handle http.HandlerFunc, url string) ([]string, string) {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		req = req.WithContext(middleware.SetTestUserDID(req.Context(), userDID))
+		rec := httptest.NewRecorder()
+		handle(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response struct {
+			Feed []struct {
+				Post struct {
+					Via *struct {
+						DID         string `json:"did"`
+						DisplayName string `json:"displayName"`
+					} `json:"via"`
+					URI string `json:"uri"`
+				} `json:"post"`
+			} `json:"feed"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+
+		var uris []string
+		var viaDID string
+		for _, item := range response.Feed {
+			uris = append(uris, item.Post.URI)
+			if item.Post.URI == botPostURI && item.Post.Via != nil {
+				viaDID = item.Post.Via.DID
+				assert.Equal(t, "RSS Bot", item.Post.Via.DisplayName)
+			}
+			if item.Post.URI == humanPostURI {
+				assert.Nil(t, item.Post.Via, "Human posts must not be attributed")
+			}
+		}
+		return uris, viaDID
+	}
+
+	timelineURL := "/xrpc/social.coves.feed.getTimeline?sort=new&limit=50"
+	discoverURL := "/xrpc/social.coves.feed.getDiscover?sort=new&limit=50"
+	communityURL := fmt.Sprintf("/xrpc/social.coves.communityFeed.getCommunity?community=%s&sort=new&limit=50", communityDID)
+
+	t.Run("aggregator posts are attributed by default", func(t *testing.T) {
+		for _, tc := range []struct {
+			handle http.HandlerFunc
+			url    string
+		}{
+			{timelineHandler.HandleGetTimeline, timelineURL},
+			{discoverHandler.HandleGetDiscover, discoverURL},
+			{communityHandler.HandleGetCommunity, communityURL},
+		} {
+			uris, viaDID := feedURIs(tc.handle, tc.url)
+			assert.Contains(t, uris, humanPostURI, tc.url)
+			assert.Contains(t, uris, botPostURI, tc.url)
+			assert.Equal(t, aggregatorDID, viaDID, tc.url)
+		}
+	})
+
+	prefsRepo := postgres.NewUserPreferencesRepository(db)
+	prefs, err := users.ParsePreferences(userDID, fmt.Sprintf("at://%s/%s/self", userDID, users.PreferencesCollection), "bafyprefs",
+		map[string]interface{}{"hideAggregatorPosts": true})
+	require.NoError(t, err)
+	require.NoError(t, prefsRepo.Upsert(ctx, prefs))
+
+	t.Run("preference is stored", func(t *testing.T) {
+		stored, err := prefsRepo.Get(ctx, userDID)
+		require.NoError(t, err)
+		assert.True(t, stored.HideAggregatorPosts)
+		assert.Equal(t, "bafyprefs", stored.CID)
+	})
+
+	t.Run("hidden from timeline and discover", func(t *testing.T) {
+		uris, _ := feedURIs(timelineHandler.HandleGetTimeline, timelineURL)
+		assert.Contains(t, uris, humanPostURI)
+		assert.NotContains(t, uris, botPostURI)
+
+		uris, _ = feedURIs(discoverHandler.HandleGetDiscover, discoverURL)
+		assert.Contains(t, uris, humanPostURI)
+		assert.NotContains(t, uris, botPostURI)
+	})
+
+	t.Run("community feed still shows aggregator posts", func(t *testing.T) {
+		uris, viaDID := feedURIs(communityHandler.HandleGetCommunity, communityURL)
+		assert.Contains(t, uris, botPostURI)
+		assert.Equal(t, aggregatorDID, viaDID)
+	})
+
+	t.Run("deleting preferences restores aggregator posts", func(t *testing.T) {
+		require.NoError(t, prefsRepo.Delete(ctx, userDID))
+		_, err := prefsRepo.Get(ctx, userDID)
+		assert.ErrorIs(t, err, users.ErrPreferencesNotFound)
+
+		uris, _ := feedURIs(timelineHandler.HandleGetTimeline, timelineURL)
+		assert.Contains(t, uris, botPostURI)
+	})
+}
@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/communities"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestAggregatorDirectory checks the hydrated directory queries: maintainer handle and PDS
+// from users, and authorized communities limited to enabled, non-private ones
+func TestAggregatorDirectory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	aggRepo := postgres.NewAggregatorRepository(db)
+	commRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	ctx := context.Background()
+
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	aggregatorDID := generateTestDID(suffix + "agg")
+	maintainer := createTestUser(t, db, "maintainer"+suffix+".test", generateTestDID(suffix+"maint"))
+	if _, err := db.ExecContext(ctx,
+		`INSERT INTO users (did, handle, pds_url) VALUES ($1, $2, 'https://pds.example.com')`,
+		aggregatorDID, "agg"+suffix+".test",
+	); err != nil {
+		t.Fatalf("Failed to create aggregator user: %v", err)
+	}
+
+	agg := &aggregators.Aggregator{
+		DID:           aggregatorDID,
+		DisplayName:   "Directory Bot",
+		AvatarURL:     "bafyavatar",
+		MaintainerDID: maintainer.DID,
+		CreatedAt:     time.Now(),
+		IndexedAt:     time.Now(),
+		RecordURI:     fmt.Sprintf("at://%s/social.coves.aggregator.service/self", aggregatorDID),
+		RecordCID:     "bagdirectory",
+	}
+	if err := aggRepo.CreateAggregator(ctx, agg); err != nil {
+		t.Fatalf("Failed to create aggregator: %v", err)
+	}
+
+	authorize := func(name, visibility string, enabled bool) {
+		t.Helper()
+		communityDID := generateTestDID(suffix + name)
+		if _, err := commRepo.Create(ctx, &communities.Community{
+			DID:         communityDID,
+			Handle:      fmt.Sprintf("%s-%s.community.coves.local", name, suffix),
+			Name:        name,
+			OwnerDID:    "did:web:coves.local",
+			HostedByDID: "did:web:coves.local",
+			Visibility:  visibility,
+			CreatedAt:   time.Now(),
+			UpdatedAt:   time.Now(),
+		}); err != nil {
+			t.Fatalf("Failed to create community: %v", err)
+		}
+		if err := aggRepo.CreateAuthorization(ctx, &aggregators.Authorization{
+			AggregatorDID: aggregatorDID,
+			CommunityDID:  communityDID,
+			Enabled:       enabled,
+			CreatedBy:     "did:plc:moderator",
+			CreatedAt:     time.Now(),
+			IndexedAt:     time.Now(),
+			RecordURI:     fmt.Sprintf("at://%s/social.coves.aggregator.authorization/%s", communityDID, name),
+			RecordCID:     "bagauth" + name,
+		}); err != nil {
+			t.Fatalf("Failed to create authorization: %v", err)
+		}
+	}
+	authorize("enabled", "public", true)
+	authorize("unlisted", "unlisted", true)
+	authorize("disabled", "public", false)
+	authorize("private", "private", true)
+
+	service, err := aggRepo.GetService(ctx, aggregatorDID)
+	if err != nil {
+		t.Fatalf("GetService: %v", err)
+	}
+	if service.MaintainerHandle != maintainer.Handle {
+		t.Errorf("expected maintainer handle %q, got %q", maintainer.Handle, service.MaintainerHandle)
+	}
+	if service.PDSURL != "https://pds.example.com" {
+		t.Errorf("expected aggregator PDS URL, got %q", service.PDSURL)
+	}
+	if service.CommunitiesUsing != 3 {
+		t.Errorf("expected 3 communities using (all enabled authorizations), got %d", service.CommunitiesUsing)
+	}
+
+	authorized, err := aggRepo.ListAuthorizedCommunities(ctx, aggregatorDID, 10)
+	if err != nil {
+		t.Fatalf("ListAuthorizedCommunities: %v", err)
+	}
+	names := map[string]bool{}
+	for _, c := range authorized {
+		names[c.Name] = true
+	}
+	if len(authorized) != 2 || !names["enabled"] || !names["unlisted"] {
+		t.Errorf("expected the enabled public and unlisted communities, got %v", names)
+	}
+
+	if _, err := aggRepo.GetService(ctx, generateTestDID(suffix+"missing")); !aggregators.IsNotFound(err) {
+		t.Errorf("expected not found for unknown aggregator, got %v", err)
+	}
+
+	// Pages don't overlap
+	var seen []string
+	for offset := 0; ; offset += 2 {
+		page, err := aggRepo.ListServices(ctx, 2, offset)
+		if err != nil {
+			t.Fatalf("ListServices: %v", err)
+		}
+		for _, a := range page {
+			seen = append(seen, a.DID)
+		}
+		if len(page) < 2 {
+			break
+		}
+	}
+	count := 0
+	for _, did := range seen {
+		if did == aggregatorDID {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected the aggregator exactly once across pages, got %d", count)
+	}
+}
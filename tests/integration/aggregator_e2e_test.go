@@ -60,8 +60,8 @@ func TestAggregator_E2E_WithJetstream(t *testing.T) {
 
 	// Setup repositories
 	aggregatorRepo := postgres.NewAggregatorRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
 
 	// Setup services
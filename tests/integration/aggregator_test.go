@@ -6,6 +6,7 @@ import (
 	"Coves/internal/db/postgres"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -178,7 +179,7 @@ func TestAggregatorAuthorization_Create(t *testing.T) {
 	}()
 
 	aggRepo := postgres.NewAggregatorRepository(db)
-	commRepo := postgres.NewCommunityRepository(db)
+	commRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("creates authorization successfully", func(t *testing.T) {
@@ -337,7 +338,7 @@ func TestAggregatorAuthorization_IsAuthorized(t *testing.T) {
 	}()
 
 	aggRepo := postgres.NewAggregatorRepository(db)
-	commRepo := postgres.NewCommunityRepository(db)
+	commRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	uniqueSuffix := fmt.Sprintf("%d", time.Now().UnixNano())
@@ -475,7 +476,7 @@ func TestAggregatorService_PostCreationIntegration(t *testing.T) {
 	}()
 
 	aggRepo := postgres.NewAggregatorRepository(db)
-	commRepo := postgres.NewCommunityRepository(db)
+	commRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	aggService := aggregators.NewAggregatorService(aggRepo, nil) // nil community service for this test
 	ctx := context.Background()
@@ -574,7 +575,7 @@ func TestAggregatorService_RateLimiting(t *testing.T) {
 	}()
 
 	aggRepo := postgres.NewAggregatorRepository(db)
-	commRepo := postgres.NewCommunityRepository(db)
+	commRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	aggService := aggregators.NewAggregatorService(aggRepo, nil)
 	ctx := context.Background()
@@ -653,6 +654,56 @@ func TestAggregatorService_RateLimiting(t *testing.T) {
 			t.Errorf("Expected rate limit error after 10 posts, got: %v", err)
 		}
 	})
+
+	t.Run("updated record limits apply to the next post", func(t *testing.T) {
+		hourly, daily := 20, 12
+		auth.MaxPostsPerHour = &hourly
+		auth.MaxPostsPerDay = &daily
+		if err := aggRepo.CreateAuthorization(ctx, auth); err != nil {
+			t.Fatalf("Failed to update authorization: %v", err)
+		}
+
+		stored, err := aggRepo.GetAuthorization(ctx, aggregatorDID, communityDID)
+		if err != nil {
+			t.Fatalf("Failed to get authorization: %v", err)
+		}
+		if stored.MaxPostsPerHour == nil || *stored.MaxPostsPerHour != 20 || stored.MaxPostsPerDay == nil || *stored.MaxPostsPerDay != 12 {
+			t.Fatalf("Expected stored limits 20/hour and 12/day, got %v/%v", stored.MaxPostsPerHour, stored.MaxPostsPerDay)
+		}
+
+		// 10 posts are under both raised limits
+		if err := aggService.ValidateAggregatorPost(ctx, aggregatorDID, communityDID); err != nil {
+			t.Errorf("Expected validation to pass under raised limits, got: %v", err)
+		}
+	})
+
+	t.Run("enforces daily limit outside the hourly window", func(t *testing.T) {
+		// Age the existing posts out of the hourly window but keep them in the daily one
+		if _, err := db.ExecContext(ctx, `
+			UPDATE aggregator_posts SET created_at = NOW() - INTERVAL '2 hours'
+			WHERE aggregator_did = $1 AND community_did = $2`, aggregatorDID, communityDID); err != nil {
+			t.Fatalf("Failed to age posts: %v", err)
+		}
+		for i := 11; i < 13; i++ {
+			postURI := fmt.Sprintf("at://%s/social.coves.community.post/post%d", communityDID, i)
+			if err := aggRepo.RecordAggregatorPost(ctx, aggregatorDID, communityDID, postURI, "bafy123"); err != nil {
+				t.Fatalf("Failed to record post %d: %v", i, err)
+			}
+		}
+
+		// 2 posts this hour, 12 today (12 >= 12)
+		err := aggService.ValidateAggregatorPost(ctx, aggregatorDID, communityDID)
+		var limitErr *aggregators.RateLimitError
+		if !errors.As(err, &limitErr) {
+			t.Fatalf("Expected daily rate limit error, got: %v", err)
+		}
+		if limitErr.Window != aggregators.RateLimitWindowDay || limitErr.Limit != 12 {
+			t.Errorf("Expected 12 posts per day, got %d per %s", limitErr.Limit, limitErr.Window)
+		}
+		if wait := time.Until(limitErr.ResetAt); wait < 21*time.Hour || wait > 22*time.Hour {
+			t.Errorf("Expected reset about 22 hours from now, got %v", limitErr.ResetAt)
+		}
+	})
 }
 
 // TestAggregatorPostService_Integration tests the posts service integration
@@ -716,7 +767,7 @@ func TestAggregatorTriggers(t *testing.T) {
 	}()
 
 	aggRepo := postgres.NewAggregatorRepository(db)
-	commRepo := postgres.NewCommunityRepository(db)
+	commRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	uniqueSuffix := fmt.Sprintf("%d", time.Now().UnixNano())
@@ -830,7 +881,7 @@ func TestAggregatorAuthorization_DisabledAtField(t *testing.T) {
 	}()
 
 	aggRepo := postgres.NewAggregatorRepository(db)
-	commRepo := postgres.NewCommunityRepository(db)
+	commRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	uniqueSuffix := fmt.Sprintf("%d", time.Now().UnixNano())
@@ -0,0 +1,82 @@
+package integration
+
+import (
+	"Coves/internal/core/audit"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAuditLog_ChainSurvivesStorageAndDetectsTampering verifies entries chain in Postgres,
+// that the table rejects updates, and that an entry edited behind the trigger's back is found
+func TestAuditLog_ChainSurvivesStorageAndDetectsTampering(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	suffix := uniqueTestID()
+	adminDID := fmt.Sprintf("did:plc:auditadmin%s", suffix)
+	service := audit.NewService(postgres.NewAuditRepository(db))
+
+	service.Record(ctx, adminDID, audit.ActionNullifyVotes, "did:plc:spammer"+suffix, map[string]interface{}{"nullified": 12})
+	service.Record(ctx, adminDID, audit.ActionTakedownRecord, "at://did:plc:c/social.coves.community.post/3k"+suffix,
+		map[string]string{"reason": "spam", "note": "vote ring"})
+	service.Record(ctx, adminDID, audit.ActionRemoveReservedName, "news"+suffix, nil)
+
+	entries, err := service.GetAuditLog(ctx, audit.Filter{ActorDID: adminDID})
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, audit.ActionRemoveReservedName, entries[0].Action, "newest first")
+	assert.Equal(t, entries[1].RowHash, entries[0].PrevHash)
+	assert.Equal(t, entries[2].RowHash, entries[1].PrevHash)
+
+	page, err := service.GetAuditLog(ctx, audit.Filter{ActorDID: adminDID, Before: entries[0].ID, Limit: 1})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, entries[1].ID, page[0].ID)
+
+	// JSONB reorders and respaces details; the chain must still verify
+	result, err := service.VerifyChain(ctx)
+	require.NoError(t, err)
+	require.True(t, result.Valid, "chain should verify: %+v", result)
+
+	// The table is append-only
+	_, err = db.ExecContext(ctx, `UPDATE audit_log SET subject = 'did:plc:innocent' WHERE id = $1`, entries[2].ID)
+	require.Error(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM audit_log WHERE id = $1`, entries[2].ID)
+	require.Error(t, err)
+
+	// Someone with enough privileges to bypass the trigger edits an entry
+	tamper := func(subject string) {
+		t.Helper()
+		_, err := db.ExecContext(ctx, `ALTER TABLE audit_log DISABLE TRIGGER trigger_audit_log_append_only`)
+		require.NoError(t, err)
+		defer func() {
+			if _, err := db.ExecContext(ctx, `ALTER TABLE audit_log ENABLE TRIGGER trigger_audit_log_append_only`); err != nil {
+				t.Errorf("Failed to re-enable the audit log trigger: %v", err)
+			}
+		}()
+		_, err = db.ExecContext(ctx, `UPDATE audit_log SET subject = $1 WHERE id = $2`, subject, entries[2].ID)
+		require.NoError(t, err)
+	}
+	tamper("did:plc:innocent")
+	defer tamper(entries[2].Subject)
+
+	result, err = service.VerifyChain(ctx)
+	require.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, entries[2].ID, result.BrokenAt)
+	assert.NotEmpty(t, result.Problem)
+}
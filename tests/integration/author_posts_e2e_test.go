@@ -9,11 +9,11 @@ import (
 	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
 	"Coves/internal/db/postgres"
+	"Coves/pkg/covesclient"
 	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -27,7 +27,7 @@ import (
 
 // getPostTitleFromView extracts title from PostView.Record.
 // Fails the test if Record structure is invalid (should not happen in valid responses).
-func getPostTitleFromView(t *testing.T, pv *posts.PostView) string {
+func getPostTitleFromView(t *testing.T, pv *covesclient.PostView) string {
 	t.Helper()
 	if pv.Record == nil {
 		t.Fatalf("getPostTitleFromView: Record is nil for post URI %s", pv.URI)
@@ -81,9 +81,9 @@ func TestGetAuthorPosts_E2E_Success(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup repositories
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	voteRepo := postgres.NewVoteRepository(db)
 
 	// Setup services
@@ -128,31 +128,17 @@ func TestGetAuthorPosts_E2E_Success(t *testing.T) {
 
 	// Setup HTTP server with XRPC routes
 	r := chi.NewRouter()
-	routes.RegisterActorRoutes(r, postService, userService, voteService, nil, nil, e2eAuth.OAuthAuthMiddleware)
+	routes.RegisterActorRoutes(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), postService, userService, voteService, nil, nil, nil, nil, nil, nil)
 	httpServer := httptest.NewServer(r)
 	defer httpServer.Close()
+	client := newTestCovesClient(t, httpServer.URL)
 
 	// Test 1: Get posts by DID
 	t.Run("Get posts by DID", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s&limit=10", httpServer.URL, userDID), nil)
-		req.Header.Set("Authorization", "Bearer "+token)
-
-		resp, err := http.DefaultClient.Do(req)
+		response, err := client.WithToken(token).GetActorPosts(ctx, covesclient.AuthorPostsParams{Actor: userDID, Limit: 10})
 		if err != nil {
 			t.Fatalf("Failed to GET author posts: %v", err)
 		}
-		defer func() { _ = resp.Body.Close() }()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-		}
-
-		var response posts.GetAuthorPostsResponse
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&response); decodeErr != nil {
-			t.Fatalf("Failed to decode response: %v", decodeErr)
-		}
 
 		if len(response.Feed) != 5 {
 			t.Errorf("Expected 5 posts, got %d", len(response.Feed))
@@ -172,24 +158,10 @@ func TestGetAuthorPosts_E2E_Success(t *testing.T) {
 
 	// Test 2: Get posts by handle
 	t.Run("Get posts by handle", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s&limit=5", httpServer.URL, testUserHandle), nil)
-
-		resp, err := http.DefaultClient.Do(req)
+		response, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{Actor: testUserHandle, Limit: 5})
 		if err != nil {
 			t.Fatalf("Failed to GET author posts by handle: %v", err)
 		}
-		defer func() { _ = resp.Body.Close() }()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-		}
-
-		var response posts.GetAuthorPostsResponse
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&response); decodeErr != nil {
-			t.Fatalf("Failed to decode response: %v", decodeErr)
-		}
 
 		if len(response.Feed) != 5 {
 			t.Errorf("Expected 5 posts, got %d", len(response.Feed))
@@ -201,20 +173,11 @@ func TestGetAuthorPosts_E2E_Success(t *testing.T) {
 	// Test 3: Pagination with cursor
 	t.Run("Pagination with cursor", func(t *testing.T) {
 		// First page
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s&limit=3", httpServer.URL, userDID), nil)
-
-		resp, err := http.DefaultClient.Do(req)
+		firstPage, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{Actor: userDID, Limit: 3})
 		if err != nil {
 			t.Fatalf("Failed to GET first page: %v", err)
 		}
 
-		var firstPage posts.GetAuthorPostsResponse
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&firstPage); decodeErr != nil {
-			t.Fatalf("Failed to decode first page: %v", decodeErr)
-		}
-		_ = resp.Body.Close()
-
 		if len(firstPage.Feed) != 3 {
 			t.Errorf("Expected 3 posts on first page, got %d", len(firstPage.Feed))
 		}
@@ -223,20 +186,14 @@ func TestGetAuthorPosts_E2E_Success(t *testing.T) {
 		}
 
 		// Second page using cursor
-		req2, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s&limit=3&cursor=%s",
-				httpServer.URL, userDID, *firstPage.Cursor), nil)
-
-		resp2, err := http.DefaultClient.Do(req2)
+		secondPage, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{
+			Actor:  userDID,
+			Limit:  3,
+			Cursor: *firstPage.Cursor,
+		})
 		if err != nil {
 			t.Fatalf("Failed to GET second page: %v", err)
 		}
-		defer func() { _ = resp2.Body.Close() }()
-
-		var secondPage posts.GetAuthorPostsResponse
-		if decodeErr := json.NewDecoder(resp2.Body).Decode(&secondPage); decodeErr != nil {
-			t.Fatalf("Failed to decode second page: %v", decodeErr)
-		}
 
 		if len(secondPage.Feed) != 2 {
 			t.Errorf("Expected 2 posts on second page, got %d", len(secondPage.Feed))
@@ -259,21 +216,13 @@ func TestGetAuthorPosts_E2E_Success(t *testing.T) {
 
 	// Test 4: Actor not found
 	t.Run("Actor not found", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s", httpServer.URL, "did:plc:nonexistent123"), nil)
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			t.Fatalf("Failed to send request: %v", err)
-		}
-		defer func() { _ = resp.Body.Close() }()
+		_, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{Actor: "did:plc:nonexistent123"})
 
 		// The actor exists as a valid DID format but has no posts - should return empty feed
 		// If you want 404, you'd need a user existence check in the service
 		// For now, we expect 200 with empty feed (Bluesky-compatible behavior)
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			t.Logf("Response: %s", string(body))
+		if err != nil {
+			t.Logf("Response: %v", err)
 		}
 
 		t.Logf("SUCCESS: Non-existent actor handled correctly")
@@ -300,9 +249,9 @@ func TestGetAuthorPosts_FilterLogic(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup repositories and services
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	voteRepo := postgres.NewVoteRepository(db)
 
 	resolver := identity.NewResolver(db, identity.DefaultConfig())
@@ -340,31 +289,20 @@ func TestGetAuthorPosts_FilterLogic(t *testing.T) {
 	// Setup HTTP server
 	e2eAuth := NewE2EOAuthMiddleware()
 	r := chi.NewRouter()
-	routes.RegisterActorRoutes(r, postService, userService, voteService, nil, nil, e2eAuth.OAuthAuthMiddleware)
+	routes.RegisterActorRoutes(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), postService, userService, voteService, nil, nil, nil, nil, nil, nil)
 	httpServer := httptest.NewServer(r)
 	defer httpServer.Close()
+	client := newTestCovesClient(t, httpServer.URL)
 
 	// Test: posts_with_media filter
 	t.Run("Filter posts_with_media", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s&filter=posts_with_media",
-				httpServer.URL, testUserDID), nil)
-
-		resp, err := http.DefaultClient.Do(req)
+		response, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{
+			Actor:  testUserDID,
+			Filter: posts.FilterPostsWithMedia,
+		})
 		if err != nil {
 			t.Fatalf("Failed to GET filtered posts: %v", err)
 		}
-		defer func() { _ = resp.Body.Close() }()
-
-		if resp.StatusCode != http.StatusOK {
-			body, _ := io.ReadAll(resp.Body)
-			t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-		}
-
-		var response posts.GetAuthorPostsResponse
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&response); decodeErr != nil {
-			t.Fatalf("Failed to decode response: %v", decodeErr)
-		}
 
 		// Should only return the post with embed
 		if len(response.Feed) != 1 {
@@ -383,20 +321,13 @@ func TestGetAuthorPosts_FilterLogic(t *testing.T) {
 
 	// Test: posts_with_replies (default - returns all)
 	t.Run("Filter posts_with_replies (default)", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s&filter=posts_with_replies",
-				httpServer.URL, testUserDID), nil)
-
-		resp, err := http.DefaultClient.Do(req)
+		response, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{
+			Actor:  testUserDID,
+			Filter: posts.FilterPostsWithReplies,
+		})
 		if err != nil {
 			t.Fatalf("Failed to GET filtered posts: %v", err)
 		}
-		defer func() { _ = resp.Body.Close() }()
-
-		var response posts.GetAuthorPostsResponse
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&response); decodeErr != nil {
-			t.Fatalf("Failed to decode response: %v", decodeErr)
-		}
 
 		// Should return all posts
 		if len(response.Feed) != 2 {
@@ -408,19 +339,9 @@ func TestGetAuthorPosts_FilterLogic(t *testing.T) {
 
 	// Test: Invalid filter returns error
 	t.Run("Invalid filter returns error", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s&filter=invalid_filter",
-				httpServer.URL, testUserDID), nil)
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			t.Fatalf("Failed to send request: %v", err)
-		}
-		defer func() { _ = resp.Body.Close() }()
-
-		if resp.StatusCode != http.StatusBadRequest {
-			body, _ := io.ReadAll(resp.Body)
-			t.Errorf("Expected 400 for invalid filter, got %d: %s", resp.StatusCode, string(body))
+		_, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{Actor: testUserDID, Filter: "invalid_filter"})
+		if !errors.Is(err, covesclient.ErrInvalidRequest) {
+			t.Errorf("Expected InvalidRequest for invalid filter, got %v", err)
 		}
 
 		t.Logf("SUCCESS: Invalid filter correctly rejected")
@@ -439,9 +360,9 @@ func TestGetAuthorPosts_ServiceErrors(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup services
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	voteRepo := postgres.NewVoteRepository(db)
 
 	resolver := identity.NewResolver(db, identity.DefaultConfig())
@@ -461,24 +382,16 @@ func TestGetAuthorPosts_ServiceErrors(t *testing.T) {
 	// Setup HTTP server
 	e2eAuth := NewE2EOAuthMiddleware()
 	r := chi.NewRouter()
-	routes.RegisterActorRoutes(r, postService, userService, voteService, nil, nil, e2eAuth.OAuthAuthMiddleware)
+	routes.RegisterActorRoutes(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), postService, userService, voteService, nil, nil, nil, nil, nil, nil)
 	httpServer := httptest.NewServer(r)
 	defer httpServer.Close()
+	client := newTestCovesClient(t, httpServer.URL)
 
 	// Test: Missing actor parameter
 	t.Run("Missing actor parameter", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts", httpServer.URL), nil)
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			t.Fatalf("Failed to send request: %v", err)
-		}
-		defer func() { _ = resp.Body.Close() }()
-
-		if resp.StatusCode != http.StatusBadRequest {
-			body, _ := io.ReadAll(resp.Body)
-			t.Errorf("Expected 400 for missing actor, got %d: %s", resp.StatusCode, string(body))
+		_, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{})
+		if !errors.Is(err, covesclient.ErrInvalidRequest) {
+			t.Errorf("Expected InvalidRequest for missing actor, got %v", err)
 		}
 
 		t.Logf("SUCCESS: Missing actor parameter correctly rejected")
@@ -486,19 +399,11 @@ func TestGetAuthorPosts_ServiceErrors(t *testing.T) {
 
 	// Test: Invalid DID format
 	t.Run("Invalid DID format", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s", httpServer.URL, "not-a-did"), nil)
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			t.Fatalf("Failed to send request: %v", err)
-		}
-		defer func() { _ = resp.Body.Close() }()
+		_, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{Actor: "not-a-did"})
 
 		// Invalid DIDs that don't resolve should return 404 (actor not found)
-		if resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusBadRequest {
-			body, _ := io.ReadAll(resp.Body)
-			t.Errorf("Expected 404 or 400 for invalid DID, got %d: %s", resp.StatusCode, string(body))
+		if !covesclient.IsNotFound(err) && !errors.Is(err, covesclient.ErrInvalidRequest) {
+			t.Errorf("Expected 404 or 400 for invalid DID, got %v", err)
 		}
 
 		t.Logf("SUCCESS: Invalid DID format handled")
@@ -506,19 +411,11 @@ func TestGetAuthorPosts_ServiceErrors(t *testing.T) {
 
 	// Test: Invalid cursor
 	t.Run("Invalid cursor", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s&cursor=%s",
-				httpServer.URL, testUserDID, "invalid-cursor-format"), nil)
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			t.Fatalf("Failed to send request: %v", err)
-		}
-		defer func() { _ = resp.Body.Close() }()
+		_, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{Actor: testUserDID, Cursor: "invalid-cursor-format"})
 
-		if resp.StatusCode != http.StatusBadRequest {
-			body, _ := io.ReadAll(resp.Body)
-			t.Errorf("Expected 400 for invalid cursor, got %d: %s", resp.StatusCode, string(body))
+		var xerr *covesclient.Error
+		if !errors.As(err, &xerr) || xerr.StatusCode != http.StatusBadRequest {
+			t.Errorf("Expected 400 for invalid cursor, got %v", err)
 		}
 
 		t.Logf("SUCCESS: Invalid cursor correctly rejected")
@@ -526,19 +423,12 @@ func TestGetAuthorPosts_ServiceErrors(t *testing.T) {
 
 	// Test: Community filter with non-existent community
 	t.Run("Non-existent community filter", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s&community=%s",
-				httpServer.URL, testUserDID, "did:plc:nonexistentcommunity"), nil)
-
-		resp, err := http.DefaultClient.Do(req)
-		if err != nil {
-			t.Fatalf("Failed to send request: %v", err)
-		}
-		defer func() { _ = resp.Body.Close() }()
-
-		if resp.StatusCode != http.StatusNotFound {
-			body, _ := io.ReadAll(resp.Body)
-			t.Errorf("Expected 404 for non-existent community, got %d: %s", resp.StatusCode, string(body))
+		_, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{
+			Actor:     testUserDID,
+			Community: "did:plc:nonexistentcommunity",
+		})
+		if !covesclient.IsNotFound(err) {
+			t.Errorf("Expected 404 for non-existent community, got %v", err)
 		}
 
 		t.Logf("SUCCESS: Non-existent community correctly rejected")
@@ -558,9 +448,9 @@ func TestGetAuthorPosts_WithJetstreamIndexing(t *testing.T) {
 	pdsURL := getTestPDSURL()
 
 	// Setup repositories
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	voteRepo := postgres.NewVoteRepository(db)
 
 	// Setup services
@@ -624,23 +514,15 @@ func TestGetAuthorPosts_WithJetstreamIndexing(t *testing.T) {
 		// Verify post is now queryable via GetAuthorPosts
 		e2eAuth := NewE2EOAuthMiddleware()
 		r := chi.NewRouter()
-		routes.RegisterActorRoutes(r, postService, userService, voteService, nil, nil, e2eAuth.OAuthAuthMiddleware)
+		routes.RegisterActorRoutes(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), postService, userService, voteService, nil, nil, nil, nil, nil, nil)
 		httpServer := httptest.NewServer(r)
 		defer httpServer.Close()
 
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s", httpServer.URL, userDID), nil)
-
-		resp, err := http.DefaultClient.Do(req)
+		client := newTestCovesClient(t, httpServer.URL)
+		response, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{Actor: userDID})
 		if err != nil {
 			t.Fatalf("Failed to GET author posts: %v", err)
 		}
-		defer func() { _ = resp.Body.Close() }()
-
-		var response posts.GetAuthorPostsResponse
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&response); decodeErr != nil {
-			t.Fatalf("Failed to decode response: %v", decodeErr)
-		}
 
 		if len(response.Feed) != 1 {
 			t.Errorf("Expected 1 post, got %d", len(response.Feed))
@@ -669,9 +551,9 @@ func TestGetAuthorPosts_CommunityFilter(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup services
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	voteRepo := postgres.NewVoteRepository(db)
 
 	resolver := identity.NewResolver(db, identity.DefaultConfig())
@@ -697,26 +579,17 @@ func TestGetAuthorPosts_CommunityFilter(t *testing.T) {
 	// Setup HTTP server
 	e2eAuth := NewE2EOAuthMiddleware()
 	r := chi.NewRouter()
-	routes.RegisterActorRoutes(r, postService, userService, voteService, nil, nil, e2eAuth.OAuthAuthMiddleware)
+	routes.RegisterActorRoutes(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), postService, userService, voteService, nil, nil, nil, nil, nil, nil)
 	httpServer := httptest.NewServer(r)
 	defer httpServer.Close()
+	client := newTestCovesClient(t, httpServer.URL)
 
 	// Test: Filter by community 1
 	t.Run("Filter by community 1", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s&community=%s",
-				httpServer.URL, testUserDID, community1DID), nil)
-
-		resp, err := http.DefaultClient.Do(req)
+		response, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{Actor: testUserDID, Community: community1DID})
 		if err != nil {
 			t.Fatalf("Failed to GET posts: %v", err)
 		}
-		defer func() { _ = resp.Body.Close() }()
-
-		var response posts.GetAuthorPostsResponse
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&response); decodeErr != nil {
-			t.Fatalf("Failed to decode response: %v", decodeErr)
-		}
 
 		if len(response.Feed) != 2 {
 			t.Errorf("Expected 2 posts in community 1, got %d", len(response.Feed))
@@ -734,19 +607,10 @@ func TestGetAuthorPosts_CommunityFilter(t *testing.T) {
 
 	// Test: No filter returns all posts
 	t.Run("No filter returns all posts", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet,
-			fmt.Sprintf("%s/xrpc/social.coves.actor.getPosts?actor=%s", httpServer.URL, testUserDID), nil)
-
-		resp, err := http.DefaultClient.Do(req)
+		response, err := client.GetActorPosts(ctx, covesclient.AuthorPostsParams{Actor: testUserDID})
 		if err != nil {
 			t.Fatalf("Failed to GET posts: %v", err)
 		}
-		defer func() { _ = resp.Body.Close() }()
-
-		var response posts.GetAuthorPostsResponse
-		if decodeErr := json.NewDecoder(resp.Body).Decode(&response); decodeErr != nil {
-			t.Fatalf("Failed to decode response: %v", decodeErr)
-		}
 
 		if len(response.Feed) != 3 {
 			t.Errorf("Expected 3 total posts, got %d", len(response.Feed))
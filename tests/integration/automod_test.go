@@ -0,0 +1,246 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/automod"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// automodTestRules removes links to a spam domain, holds a keyword and flags a regex-lite pattern
+var automodTestRules = []automod.Rule{
+	{Pattern: "spam.example", MatchType: automod.MatchDomain, Action: automod.ActionRemove},
+	{Pattern: "buy now", MatchType: automod.MatchKeyword, Action: automod.ActionHold},
+	{Pattern: `free\s+crypto`, MatchType: automod.MatchRegexLite, Action: automod.ActionFlag},
+}
+
+// TestPostConsumer_Automod indexes a post per automod action, then reviews the queue
+func TestPostConsumer_Automod(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	userService := users.NewUserService(postgres.NewUserRepository(db), nil, getTestPDSURL())
+	automodService := automod.NewAutomodService(postgres.NewAutomodRepository(db))
+	queue := postgres.NewAutomodQueueRepository(db)
+	consumer := jetstream.NewPostEventConsumer(postRepo, communityRepo, userService, db)
+	consumer.SetAutomod(automodService)
+
+	testID := uniqueTestID()
+	author := createTestUser(t, db, "automod"+testID+".test", "did:plc:automod"+testID)
+	communityDID, err := createFeedTestCommunity(db, ctx, "automod"+testID, "automodowner"+testID+".test")
+	require.NoError(t, err)
+	require.NoError(t, automodService.UpdateRules(ctx, communityDID, automodTestRules, "did:plc:mod"+testID))
+
+	indexPost := func(title string, embedURI string) string {
+		rkey := generateTID()
+		record := map[string]interface{}{
+			"$type":     "social.coves.community.post",
+			"community": communityDID,
+			"author":    author.DID,
+			"title":     title,
+			"createdAt": time.Now().Format(time.RFC3339),
+		}
+		if embedURI != "" {
+			record["embed"] = map[string]interface{}{
+				"$type":    "social.coves.embed.external",
+				"external": map[string]interface{}{"uri": embedURI},
+			}
+		}
+		require.NoError(t, consumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  communityDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev-" + rkey,
+				Operation:  "create",
+				Collection: "social.coves.community.post",
+				RKey:       rkey,
+				CID:        "bafy" + rkey,
+				Record:     record,
+			},
+		}))
+		return fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, rkey)
+	}
+	status := func(uri string) (string, string) {
+		var postStatus, visibility string
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT status, visibility_state FROM posts WHERE uri = $1`, uri).Scan(&postStatus, &visibility))
+		return postStatus, visibility
+	}
+	actionOf := func(uri string) (string, string) {
+		var action, actionStatus string
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT action, status FROM automod_actions WHERE subject_uri = $1`, uri).Scan(&action, &actionStatus))
+		return action, actionStatus
+	}
+
+	removed := indexPost("Great deal", "https://www.spam.example/offer")
+	held := indexPost("Buy now while stocks last", "")
+	flagged := indexPost("Free   crypto giveaway", "")
+	clean := indexPost("Weekly discussion thread", "https://news.example/story")
+
+	tests := []struct {
+		uri            string
+		wantStatus     string
+		wantVisibility string
+		wantAction     string
+		wantReview     string
+	}{
+		{uri: removed, wantStatus: "removed", wantVisibility: "removed", wantAction: "remove", wantReview: "removed"},
+		{uri: held, wantStatus: "held", wantVisibility: "removed", wantAction: "hold", wantReview: "open"},
+		{uri: flagged, wantStatus: "active", wantVisibility: "visible", wantAction: "flag", wantReview: "open"},
+	}
+	for _, tt := range tests {
+		postStatus, visibility := status(tt.uri)
+		assert.Equal(t, tt.wantStatus, postStatus, tt.uri)
+		assert.Equal(t, tt.wantVisibility, visibility, tt.uri)
+		action, review := actionOf(tt.uri)
+		assert.Equal(t, tt.wantAction, action, tt.uri)
+		assert.Equal(t, tt.wantReview, review, tt.uri)
+	}
+
+	postStatus, visibility := status(clean)
+	assert.Equal(t, "active", postStatus)
+	assert.Equal(t, "visible", visibility)
+	var cleanActions int
+	require.NoError(t, db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM automod_actions WHERE subject_uri = $1`, clean).Scan(&cleanActions))
+	assert.Zero(t, cleanActions)
+
+	// Held and flagged posts await review; removed ones don't
+	open, err := queue.ListOpenActions(ctx, communityDID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	assert.Equal(t, flagged, open[0].SubjectURI, "newest first")
+	assert.Equal(t, held, open[1].SubjectURI)
+
+	// Approving the hold publishes the post; removing the flag hides it
+	require.NoError(t, queue.ReviewAction(ctx, open[1].ID, automod.ReviewApproved, "did:plc:mod"+testID))
+	require.NoError(t, queue.ReviewAction(ctx, open[0].ID, automod.ReviewRemoved, "did:plc:mod"+testID))
+
+	postStatus, visibility = status(held)
+	assert.Equal(t, "active", postStatus)
+	assert.Equal(t, "visible", visibility)
+	postStatus, visibility = status(flagged)
+	assert.Equal(t, "removed", postStatus)
+	assert.Equal(t, "removed", visibility)
+
+	assert.ErrorIs(t, queue.ReviewAction(ctx, open[1].ID, automod.ReviewRemoved, "did:plc:mod"+testID),
+		automod.ErrActionNotFound, "reviewed actions can't be reviewed again")
+	open, err = queue.ListOpenActions(ctx, communityDID, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, open)
+
+	// Rule changes apply to the next post without waiting for the cache to expire
+	require.NoError(t, automodService.UpdateRules(ctx, communityDID, []automod.Rule{
+		{Pattern: "discussion", MatchType: automod.MatchKeyword, Action: automod.ActionHold},
+	}, "did:plc:mod"+testID))
+	postStatus, _ = status(indexPost("Another discussion thread", ""))
+	assert.Equal(t, "held", postStatus)
+	postStatus, _ = status(indexPost("Buy now while stocks last", ""))
+	assert.Equal(t, "active", postStatus, "removed rules no longer apply")
+}
+
+// TestCommentConsumer_Automod indexes a comment per automod action and checks that only live
+// comments count toward the thread
+func TestCommentConsumer_Automod(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	automodService := automod.NewAutomodService(postgres.NewAutomodRepository(db))
+	queue := postgres.NewAutomodQueueRepository(db)
+	consumer := jetstream.NewCommentEventConsumer(postgres.NewCommentRepository(db, newTestCursorSigner()), db)
+	consumer.SetAutomod(automodService)
+
+	testID := uniqueTestID()
+	user := createTestUser(t, db, "automodc"+testID+".test", "did:plc:automodc"+testID)
+	communityDID, err := createFeedTestCommunity(db, ctx, "automodc"+testID, "automodcowner"+testID+".test")
+	require.NoError(t, err)
+	postURI := createTestPost(t, db, communityDID, user.DID, "Automod thread", 0, time.Now().Add(-time.Hour))
+	require.NoError(t, automodService.UpdateRules(ctx, communityDID, automodTestRules, "did:plc:mod"+testID))
+
+	indexComment := func(content string, facets []interface{}) string {
+		rkey := generateTID()
+		event := commentEvent(user.DID, rkey, postURI, postURI, time.Now().Add(-time.Minute))
+		event.Commit.Record["content"] = content
+		if facets != nil {
+			event.Commit.Record["facets"] = facets
+		}
+		require.NoError(t, consumer.HandleEvent(ctx, event))
+		return fmt.Sprintf("at://%s/social.coves.community.comment/%s", user.DID, rkey)
+	}
+	status := func(uri string) (string, string) {
+		var commentStatus, visibility string
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT status, visibility_state FROM comments WHERE uri = $1`, uri).Scan(&commentStatus, &visibility))
+		return commentStatus, visibility
+	}
+	commentCount := func() int {
+		var count int
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT comment_count FROM posts WHERE uri = $1`, postURI).Scan(&count))
+		return count
+	}
+
+	// The spam domain is only in a link facet, not the text
+	removed := indexComment("check this out", []interface{}{
+		map[string]interface{}{
+			"index": map[string]interface{}{"byteStart": 6, "byteEnd": 14},
+			"features": []interface{}{map[string]interface{}{
+				"$type": "social.coves.richtext.facet#link",
+				"uri":   "https://spam.example/x",
+			}},
+		},
+	})
+	held := indexComment("You should BUY NOW", nil)
+	flagged := indexComment("free crypto here", nil)
+	indexComment("A normal reply", nil)
+
+	for uri, want := range map[string][2]string{
+		removed: {"removed", "removed"},
+		held:    {"held", "removed"},
+		flagged: {"active", "visible"},
+	} {
+		commentStatus, visibility := status(uri)
+		assert.Equal(t, want[0], commentStatus, uri)
+		assert.Equal(t, want[1], visibility, uri)
+	}
+	assert.Equal(t, 2, commentCount(), "only the flagged and clean comments count")
+
+	open, err := queue.ListOpenActions(ctx, communityDID, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, open, 2)
+	assert.Equal(t, held, open[1].SubjectURI)
+	assert.Equal(t, user.DID, open[1].AuthorDID)
+
+	// Approving the held comment counts it toward the thread
+	require.NoError(t, queue.ReviewAction(ctx, open[1].ID, automod.ReviewApproved, "did:plc:mod"+testID))
+	commentStatus, visibility := status(held)
+	assert.Equal(t, "active", commentStatus)
+	assert.Equal(t, "visible", visibility)
+	assert.Equal(t, 3, commentCount())
+
+	// Removing the flagged comment stops it counting
+	require.NoError(t, queue.ReviewAction(ctx, open[0].ID, automod.ReviewRemoved, "did:plc:mod"+testID))
+	commentStatus, _ = status(flagged)
+	assert.Equal(t, "removed", commentStatus)
+	assert.Equal(t, 2, commentCount())
+}
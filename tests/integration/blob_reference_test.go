@@ -0,0 +1,184 @@
+package integration
+
+import (
+	imageproxyhandlers "Coves/internal/api/handlers/imageproxy"
+	"Coves/internal/atproto/identity"
+	"Coves/internal/core/blobrefs"
+	"Coves/internal/core/moderation"
+	"Coves/internal/core/takedown"
+	"Coves/internal/db/postgres"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubImageService serves every blob as the same few bytes
+type stubImageService struct{}
+
+func (stubImageService) GetImage(ctx context.Context, preset, did, cid, pdsURL string) ([]byte, error) {
+	return []byte{0xFF, 0xD8, 0xFF, 0xE0}, nil
+}
+
+func (stubImageService) GetIdenticon(did string) ([]byte, error) { return nil, nil }
+
+// stubPDSResolver resolves every DID to the same PDS
+type stubPDSResolver struct{ identity.Resolver }
+
+func (stubPDSResolver) ResolveDID(ctx context.Context, did string) (*identity.DIDDocument, error) {
+	return &identity.DIDDocument{
+		DID:     did,
+		Service: []identity.Service{{Type: "AtprotoPersonalDataServer", ServiceEndpoint: "https://pds.example.com"}},
+	}, nil
+}
+
+// TestBlobReferences_BlockedWhenLastReferenceHidden checks that a blob shared by two posts is
+// served while either post is visible, refused once the last one is hidden, and served again
+// when the content is reinstated
+func TestBlobReferences_BlockedWhenLastReferenceHidden(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	blobRepo := postgres.NewBlobReferenceRepository(db)
+	service := moderation.NewModerationService(postgres.NewModerationRepository(db))
+	service.(interface {
+		SetTakedowns(takedown.Repository, takedown.UpstreamDeleter)
+	}).SetTakedowns(postgres.NewTakedownRepository(db), nil)
+	service.(interface {
+		SetBlobReferences(blobrefs.Repository)
+	}).SetBlobReferences(blobRepo)
+
+	handler := imageproxyhandlers.NewHandler(stubImageService{}, stubPDSResolver{})
+	handler.SetBlockList(blobRepo)
+	router := chi.NewRouter()
+	router.Get("/img/{preset}/plain/{did}/{cid}", handler.HandleImage)
+
+	testID := uniqueTestID()
+	communityDID, err := createFeedTestCommunity(db, ctx, "blobs"+testID, "blobowner"+testID+".test")
+	require.NoError(t, err)
+	author := "did:plc:blobauthor" + testID
+	now := time.Now()
+	first := createTestPost(t, db, communityDID, author, "First post with the image", 0, now)
+	second := createTestPost(t, db, communityDID, author, "Second post with the image", 0, now)
+
+	shared := "bafkreisharedblob" + testID
+	secondOnly := "bafkreisecondonly" + testID
+	require.NoError(t, blobRepo.SetReferences(ctx, first, []string{shared}))
+	require.NoError(t, blobRepo.SetReferences(ctx, second, []string{shared, secondOnly}))
+
+	proxyStatus := func(t *testing.T, cid string) int {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/img/avatar/plain/did:plc:z72i7hdynmk6r22z27h6tvur/"+cid, nil))
+		return w.Code
+	}
+	assertBlocked := func(t *testing.T, cid string, want bool) {
+		t.Helper()
+		blocked, err := blobRepo.IsBlocked(ctx, cid)
+		require.NoError(t, err)
+		assert.Equal(t, want, blocked, "blocked state of %s", cid)
+	}
+
+	// One of two references taken down: the blob is still live through the other post
+	_, err = service.TakedownRecord(ctx, moderation.TakedownRecordRequest{Subject: first, Reason: takedown.ReasonCopyright, ActorDID: "did:plc:admin"})
+	require.NoError(t, err)
+	assertBlocked(t, shared, false)
+	assert.Equal(t, http.StatusOK, proxyStatus(t, shared))
+
+	// Last reference removed: the blob is refused
+	require.NoError(t, service.SetVisibility(ctx, moderation.SetVisibilityRequest{Subject: second, State: moderation.VisibilityRemoved, ActorDID: "did:plc:mod"}))
+	assertBlocked(t, shared, true)
+	assertBlocked(t, secondOnly, true)
+	assert.Equal(t, http.StatusUnavailableForLegalReasons, proxyStatus(t, shared))
+	assert.Equal(t, http.StatusUnavailableForLegalReasons, proxyStatus(t, secondOnly))
+
+	// Reinstating the taken-down post unblocks only the blob it references
+	_, err = service.ReverseTakedown(ctx, moderation.ReverseTakedownRequest{Subject: first, ActorDID: "did:plc:admin"})
+	require.NoError(t, err)
+	assertBlocked(t, shared, false)
+	assertBlocked(t, secondOnly, true)
+	assert.Equal(t, http.StatusOK, proxyStatus(t, shared))
+
+	require.NoError(t, service.SetVisibility(ctx, moderation.SetVisibilityRequest{Subject: second, State: moderation.VisibilityVisible, ActorDID: "did:plc:mod"}))
+	assertBlocked(t, secondOnly, false)
+
+	t.Run("profile references are always live", func(t *testing.T) {
+		avatar := "bafkreiavatar" + testID
+		profile := "at://" + author + "/social.coves.actor.profile/self"
+		require.NoError(t, blobRepo.SetReferences(ctx, profile, []string{avatar}))
+		require.NoError(t, blobRepo.SetReferences(ctx, second, []string{shared, secondOnly, avatar}))
+
+		require.NoError(t, service.SetVisibility(ctx, moderation.SetVisibilityRequest{Subject: second, State: moderation.VisibilityRemoved, ActorDID: "did:plc:mod"}))
+		assertBlocked(t, avatar, false)
+		assertBlocked(t, secondOnly, true)
+	})
+
+	t.Run("a blob dropped from every record isn't blocked", func(t *testing.T) {
+		require.NoError(t, blobRepo.SetReferences(ctx, second, nil))
+		assertBlocked(t, secondOnly, false)
+	})
+}
+
+// TestBlobReferences_Backfill checks that records indexed before blob references existed get
+// their references, so a blob only hidden content uses is blocked
+func TestBlobReferences_Backfill(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	blobRepo := postgres.NewBlobReferenceRepository(db)
+	backfiller := blobRepo.(interface {
+		BackfillReferences(ctx context.Context, batchSize int) (int, error)
+	})
+
+	testID := uniqueTestID()
+	communityDID, err := createFeedTestCommunity(db, ctx, "backfill"+testID, "backfillowner"+testID+".test")
+	require.NoError(t, err)
+	author := "did:plc:backfillauthor" + testID
+	removed := createTestPost(t, db, communityDID, author, "Removed before references existed", 0, time.Now())
+	visible := createTestPost(t, db, communityDID, author, "Visible without blobs", 0, time.Now())
+
+	cid := "bafkreibackfilled" + testID
+	_, err = db.ExecContext(ctx, `
+		UPDATE posts SET visibility_state = 'removed', raw_record = jsonb_build_object(
+			'embed', jsonb_build_object('images', jsonb_build_array(jsonb_build_object(
+				'image', jsonb_build_object('$type', 'blob', 'ref', jsonb_build_object('$link', $2::text))))))
+		WHERE uri = $1`, removed, cid)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE posts SET raw_record = '{"text": "no blobs"}' WHERE uri = $1`, visible)
+	require.NoError(t, err)
+
+	// A small batch size exercises the paging
+	backfilled, err := backfiller.BackfillReferences(ctx, 1)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, backfilled, 1)
+
+	blocked, err := blobRepo.IsBlocked(ctx, cid)
+	require.NoError(t, err)
+	assert.True(t, blocked, "a blob only a removed post references should be blocked")
+
+	var references int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM blob_references WHERE record_uri = $1`, visible).Scan(&references))
+	assert.Zero(t, references, "a record without blobs has no references")
+
+	// Records with references already are skipped on the next run
+	_, err = backfiller.BackfillReferences(ctx, 500)
+	require.NoError(t, err)
+	var stillThere int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM blob_references WHERE record_uri = $1`, removed).Scan(&stillThere))
+	assert.Equal(t, 1, stillThere)
+}
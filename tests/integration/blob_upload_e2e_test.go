@@ -63,8 +63,8 @@ func TestBlobUpload_E2E_PostWithImages(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup repositories
-	communityRepo := postgres.NewCommunityRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
 
 	// Setup services (pdsURL already declared in health check above)
@@ -382,8 +382,8 @@ func TestBlobUpload_E2E_CommentWithImage(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup repositories
-	communityRepo := postgres.NewCommunityRepository(db)
-	commentRepo := postgres.NewCommentRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 
 	// Setup services (pdsURL already declared in health check above)
 	blobService := blobs.NewBlobService(pdsURL)
@@ -528,7 +528,7 @@ func TestBlobUpload_Validation(t *testing.T) {
 	db := setupTestDB(t)
 	defer func() { _ = db.Close() }()
 
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	blobService := blobs.NewBlobService(getTestPDSURL())
 	community := createTestCommunityWithBlobCredentials(t, communityRepo, "validation")
 	ctx := context.Background()
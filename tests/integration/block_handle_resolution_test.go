@@ -42,7 +42,7 @@ func TestBlockHandler_HandleResolution(t *testing.T) {
 	ctx := context.Background()
 
 	// Set up repositories and services
-	communityRepo := postgresRepo.NewCommunityRepository(db)
+	communityRepo := postgresRepo.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		getTestPDSURL(),
@@ -280,7 +280,7 @@ func TestUnblockHandler_HandleResolution(t *testing.T) {
 	ctx := context.Background()
 
 	// Set up repositories and services
-	communityRepo := postgresRepo.NewCommunityRepository(db)
+	communityRepo := postgresRepo.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		getTestPDSURL(),
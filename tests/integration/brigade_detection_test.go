@@ -0,0 +1,157 @@
+package integration
+
+import (
+	"Coves/internal/core/brigade"
+	"Coves/internal/core/moderation"
+	"Coves/internal/db/postgres"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insertBrigadeTestVotes inserts votes on a post, indexed at the given time, one per voter
+func insertBrigadeTestVotes(t *testing.T, db *sql.DB, postURI string, voters []string, indexedAt time.Time) {
+	t.Helper()
+	for _, voter := range voters {
+		rkey := generateTID()
+		_, err := db.Exec(`
+			INSERT INTO votes (uri, cid, rkey, voter_did, subject_uri, subject_cid, direction, created_at, indexed_at)
+			VALUES ($1, 'bafyvote', $2, $3, $4, 'bafypost', 'up', $5, $5)`,
+			fmt.Sprintf("at://%s/social.coves.feed.vote/%s", voter, rkey), rkey, voter, postURI, indexedAt)
+		require.NoError(t, err)
+	}
+}
+
+func brigadeTestDIDs(prefix string, testID int64, n int) []string {
+	dids := make([]string, n)
+	for i := range dids {
+		dids[i] = fmt.Sprintf("did:plc:%s%d-%d", prefix, i, testID)
+	}
+	return dids
+}
+
+// TestBrigadeDetection_BrigadeVsOrganicGrowth simulates a week of a community's usual voting,
+// then one bucket where one post is swarmed by outsiders and another takes off among members
+func TestBrigadeDetection_BrigadeVsOrganicGrowth(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	ownerHandle := fmt.Sprintf("brigadeowner-%d.test", testID)
+	communityDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("brigade-%d", testID), ownerHandle)
+	require.NoError(t, err)
+	ownerDID := "did:plc:" + ownerHandle
+	moderatorDID := fmt.Sprintf("did:plc:brigademod-%d", testID)
+	_, err = db.Exec(`INSERT INTO community_memberships (user_did, community_did, is_moderator) VALUES ($1, $2, TRUE)`, moderatorDID, communityDID)
+	require.NoError(t, err)
+
+	// A bucket well in the past, so votes other tests index now don't land in it
+	bucketStart := time.Date(2021, 6, 14, 15, 0, 0, 0, time.UTC).Add(time.Duration(testID%1000) * 24 * time.Hour)
+	authorDID := fmt.Sprintf("did:plc:brigadeauthor-%d", testID)
+	oldPost := createTestPost(t, db, communityDID, authorDID, "Last week's post", 0, bucketStart.Add(-7*24*time.Hour))
+	targetPost := createTestPost(t, db, communityDID, authorDID, "Brigaded post", 0, bucketStart.Add(-time.Hour))
+	popularPost := createTestPost(t, db, communityDID, authorDID, "Popular post", 0, bucketStart.Add(-time.Hour))
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM votes WHERE subject_uri IN ($1, $2, $3)`, oldPost, targetPost, popularPost)
+		_, _ = db.Exec(`DELETE FROM community_activity WHERE community_did = $1`, communityDID)
+		_, _ = db.Exec(`DELETE FROM notifications WHERE subject_uri IN ($1, $2)`, targetPost, popularPost)
+	})
+
+	// Members have been active in the community before the bucket's day
+	members := brigadeTestDIDs("member", testID, 40)
+	activity := postgres.NewCommunityActivityRepository(db)
+	for _, member := range members {
+		require.NoError(t, activity.RecordActivity(ctx, communityDID, member, bucketStart.AddDate(0, 0, -3)))
+	}
+
+	// The baseline: a few votes per bucket over the past week (one vote per voter per post)
+	for day := 1; day <= 6; day++ {
+		for hour := 0; hour < 24; hour += 4 {
+			at := bucketStart.Add(-time.Duration(day)*24*time.Hour + time.Duration(hour)*time.Hour)
+			insertBrigadeTestVotes(t, db, oldPost, brigadeTestDIDs(fmt.Sprintf("regular%d-%d-", day, hour), testID, 2+(day+hour)%4), at)
+		}
+	}
+
+	// The bucket: outsiders swarm one post, members pile onto another
+	outsiders := brigadeTestDIDs("outsider", testID, 30)
+	insertBrigadeTestVotes(t, db, targetPost, outsiders, bucketStart.Add(2*time.Minute))
+	insertBrigadeTestVotes(t, db, targetPost, members[:5], bucketStart.Add(3*time.Minute))
+	insertBrigadeTestVotes(t, db, popularPost, members[5:40], bucketStart.Add(4*time.Minute))
+	insertBrigadeTestVotes(t, db, popularPost, outsiders[:3], bucketStart.Add(5*time.Minute))
+
+	analyzer := brigade.NewAnalyzer(postgres.NewBrigadeRepository(db), brigade.Config{
+		Now: func() time.Time { return bucketStart.Add(12 * time.Minute) },
+	})
+	report, err := analyzer.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Assessed)
+	assert.Equal(t, 1, report.Alerts)
+
+	// Moderators are notified once, about the brigaded post only
+	var notified []string
+	rows, err := db.Query(`SELECT recipient_did FROM notifications WHERE reason = $1 AND subject_uri = $2 ORDER BY recipient_did`,
+		brigade.NotificationReason, targetPost)
+	require.NoError(t, err)
+	for rows.Next() {
+		var did string
+		require.NoError(t, rows.Scan(&did))
+		notified = append(notified, did)
+	}
+	require.NoError(t, rows.Err())
+	_ = rows.Close()
+	assert.ElementsMatch(t, []string{ownerDID, moderatorDID}, notified)
+
+	var popularNotifications int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM notifications WHERE subject_uri = $1`, popularPost).Scan(&popularNotifications))
+	assert.Zero(t, popularNotifications, "organic growth is not a brigade")
+
+	// A second run over the same bucket doesn't alert again
+	report, err = analyzer.Run(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, report.Alerts)
+
+	// The alert is in the community's moderation queue
+	moderationService := moderation.NewModerationService(postgres.NewModerationRepository(db))
+	moderationService.(interface{ SetBrigadeQueue(brigade.QueueRepository) }).SetBrigadeQueue(postgres.NewBrigadeQueueRepository(db))
+
+	alerts, err := moderationService.ListBrigadeAlerts(ctx, moderation.ListBrigadeAlertsRequest{
+		CommunityDID: communityDID, ActorDID: moderatorDID, Limit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, alerts, 1)
+	alert := alerts[0]
+	assert.Equal(t, targetPost, alert.PostURI)
+	assert.Equal(t, 35, alert.Votes)
+	assert.Equal(t, 30, alert.Outsiders)
+	assert.True(t, alert.BucketStart.Equal(bucketStart))
+	assert.Greater(t, alert.ZScore, brigade.DefaultZScoreThreshold)
+
+	_, err = moderationService.ListBrigadeAlerts(ctx, moderation.ListBrigadeAlertsRequest{
+		CommunityDID: communityDID, ActorDID: outsiders[0], Limit: 10,
+	})
+	assert.ErrorIs(t, err, moderation.ErrNotModerator)
+
+	require.NoError(t, moderationService.ReviewBrigadeAlert(ctx, moderation.ReviewBrigadeAlertRequest{
+		ID: alert.ID, Status: brigade.AlertConfirmed, ActorDID: ownerDID,
+	}))
+	alerts, err = moderationService.ListBrigadeAlerts(ctx, moderation.ListBrigadeAlertsRequest{
+		CommunityDID: communityDID, ActorDID: moderatorDID, Limit: 10,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, alerts, "reviewed alerts leave the queue")
+
+	err = moderationService.ReviewBrigadeAlert(ctx, moderation.ReviewBrigadeAlertRequest{
+		ID: alert.ID, Status: brigade.AlertDismissed, ActorDID: ownerDID,
+	})
+	assert.ErrorIs(t, err, brigade.ErrAlertNotFound)
+}
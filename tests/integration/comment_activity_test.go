@@ -0,0 +1,179 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/db/postgres"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commentEvent builds a comment create event replying to parentURI in the thread rooted at rootURI
+func commentEvent(authorDID, rkey, rootURI, parentURI string, createdAt time.Time) *jetstream.JetstreamEvent {
+	return &jetstream.JetstreamEvent{
+		Did:  authorDID,
+		Kind: "commit",
+		Commit: &jetstream.CommitEvent{
+			Rev:        "test-rev",
+			Operation:  "create",
+			Collection: "social.coves.community.comment",
+			RKey:       rkey,
+			CID:        "bafy" + rkey,
+			Record: map[string]interface{}{
+				"$type":   "social.coves.community.comment",
+				"content": "comment " + rkey,
+				"reply": map[string]interface{}{
+					"root":   map[string]interface{}{"uri": rootURI, "cid": "bafyroot"},
+					"parent": map[string]interface{}{"uri": parentURI, "cid": "bafyparent"},
+				},
+				"createdAt": createdAt.Format(time.RFC3339),
+			},
+		},
+	}
+}
+
+// TestCommentConsumer_TopLevelAndTotalCounts checks that nested replies count toward the
+// thread total but not the top-level count, and that deletes don't roll back activity
+func TestCommentConsumer_TopLevelAndTotalCounts(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+
+	suffix := uniqueTestID()
+	user := createTestUser(t, db, "activity"+suffix+".test", "did:plc:activity"+suffix)
+	communityDID, err := createFeedTestCommunity(db, ctx, "activity"+suffix, "owner"+suffix+".test")
+	require.NoError(t, err)
+	postURI := createTestPost(t, db, communityDID, user.DID, "Activity post", 0, time.Now().Add(-time.Hour))
+
+	postCounts := func() (total, topLevel int, lastActivity sql.NullTime) {
+		t.Helper()
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT comment_count, top_level_comment_count, last_activity_at FROM posts WHERE uri = $1`, postURI,
+		).Scan(&total, &topLevel, &lastActivity))
+		return total, topLevel, lastActivity
+	}
+
+	commentURI := func(rkey string) string {
+		return fmt.Sprintf("at://%s/social.coves.community.comment/%s", user.DID, rkey)
+	}
+
+	topRKey := generateTID()
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, topRKey, postURI, postURI, time.Now().Add(-10*time.Minute))))
+
+	replyRKey := generateTID()
+	replyAt := time.Now().Add(-5 * time.Minute).Truncate(time.Second)
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, replyRKey, postURI, commentURI(topRKey), replyAt)))
+
+	nestedRKey := generateTID()
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, nestedRKey, postURI, commentURI(replyRKey), time.Now().Add(-20*time.Minute))))
+
+	total, topLevel, lastActivity := postCounts()
+	assert.Equal(t, 3, total, "every comment in the thread counts toward the total")
+	assert.Equal(t, 1, topLevel, "only direct comments on the post are top-level")
+	require.True(t, lastActivity.Valid)
+	assert.True(t, lastActivity.Time.Equal(replyAt), "an older comment arriving late must not move activity backwards, got %v", lastActivity.Time)
+
+	t.Run("future createdAt is capped at indexing time", func(t *testing.T) {
+		rkey := generateTID()
+		require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, rkey, postURI, postURI, time.Now().Add(24*time.Hour))))
+
+		total, topLevel, lastActivity := postCounts()
+		assert.Equal(t, 4, total)
+		assert.Equal(t, 2, topLevel)
+		assert.False(t, lastActivity.Time.After(time.Now().Add(time.Minute)), "got %v", lastActivity.Time)
+
+		require.NoError(t, consumer.HandleEvent(ctx, deleteCommentEvent(user.DID, rkey)))
+	})
+
+	t.Run("deletes decrement counts but keep activity", func(t *testing.T) {
+		_, _, before := postCounts()
+
+		require.NoError(t, consumer.HandleEvent(ctx, deleteCommentEvent(user.DID, nestedRKey)))
+		total, topLevel, lastActivity := postCounts()
+		assert.Equal(t, 2, total)
+		assert.Equal(t, 1, topLevel)
+		assert.True(t, lastActivity.Time.Equal(before.Time))
+
+		require.NoError(t, consumer.HandleEvent(ctx, deleteCommentEvent(user.DID, topRKey)))
+		total, topLevel, _ = postCounts()
+		assert.Equal(t, 1, total, "the reply under the deleted comment is still live")
+		assert.Equal(t, 0, topLevel)
+	})
+}
+
+// TestCommunityFeed_ActivitySort checks the community feed lists the most recently
+// commented posts first and paginates across them
+func TestCommunityFeed_ActivitySort(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+
+	suffix := uniqueTestID()
+	user := createTestUser(t, db, "activitysort"+suffix+".test", "did:plc:activitysort"+suffix)
+	communityDID, err := createFeedTestCommunity(db, ctx, "activitysort"+suffix, "owner"+suffix+".test")
+	require.NoError(t, err)
+
+	now := time.Now()
+	oldPost := createTestPost(t, db, communityDID, user.DID, "Old but discussed", 0, now.Add(-3*time.Hour))
+	middlePost := createTestPost(t, db, communityDID, user.DID, "Quiet", 0, now.Add(-2*time.Hour))
+	newPost := createTestPost(t, db, communityDID, user.DID, "New with an old comment", 0, now.Add(-30*time.Minute))
+
+	// The old post has the newest comment, so it leads; the quiet post ranks by creation time
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, generateTID(), oldPost, oldPost, now.Add(-time.Minute))))
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, generateTID(), newPost, newPost, now.Add(-20*time.Minute))))
+
+	var uris []string
+	var cursor *string
+	for page := 0; page < 3; page++ {
+		feed, next, err := feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+			Community: communityDID,
+			Sort:      "activity",
+			Limit:     2,
+			Cursor:    cursor,
+		})
+		require.NoError(t, err)
+		for _, item := range feed {
+			uris = append(uris, item.Post.URI)
+		}
+		if next == nil {
+			break
+		}
+		cursor = next
+	}
+
+	assert.Equal(t, []string{oldPost, newPost, middlePost}, uris)
+}
+
+// deleteCommentEvent builds a comment delete event
+func deleteCommentEvent(authorDID, rkey string) *jetstream.JetstreamEvent {
+	return &jetstream.JetstreamEvent{
+		Did:  authorDID,
+		Kind: "commit",
+		Commit: &jetstream.CommitEvent{
+			Operation:  "delete",
+			Collection: "social.coves.community.comment",
+			RKey:       rkey,
+		},
+	}
+}
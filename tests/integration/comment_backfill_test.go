@@ -0,0 +1,211 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPostFetcher serves post records from memory; unknown URIs return pds.ErrNotFound
+// URIs in failures fail with their error, like an unreachable PDS
+type stubPostFetcher struct {
+	records  map[string]*pds.RecordResponse
+	failures map[string]error
+}
+
+func (f *stubPostFetcher) FetchPost(ctx context.Context, postURI string) (*pds.RecordResponse, error) {
+	if err := f.failures[postURI]; err != nil {
+		return nil, err
+	}
+	record, ok := f.records[postURI]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", pds.ErrNotFound, postURI)
+	}
+	return record, nil
+}
+
+// TestCommentConsumer_RootPostBackfill covers comments whose root post the firehose never
+// delivered: the post is fetched and indexed first, or the comment is stored as orphaned
+func TestCommentConsumer_RootPostBackfill(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	userService := users.NewUserService(postgres.NewUserRepository(db), nil, getTestPDSURL())
+	postConsumer := jetstream.NewPostEventConsumer(postgres.NewPostRepository(db, newTestCursorSigner()), communityRepo, userService, db)
+
+	fetcher := &stubPostFetcher{records: map[string]*pds.RecordResponse{}, failures: map[string]error{}}
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+	consumer.SetPostBackfill(fetcher, postConsumer)
+
+	testID := uniqueTestID()
+	author := createTestUser(t, db, "backfill"+testID+".test", "did:plc:backfill"+testID)
+	communityDID, err := createFeedTestCommunity(db, ctx, "backfillcomm"+testID, "ownerbackfill"+testID+".test")
+	require.NoError(t, err)
+
+	remotePost := func() string {
+		rkey := generateTID()
+		uri := fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, rkey)
+		fetcher.records[uri] = &pds.RecordResponse{
+			URI: uri,
+			CID: "bafy" + rkey,
+			Value: map[string]any{
+				"$type":     "social.coves.community.post",
+				"community": communityDID,
+				"author":    author.DID,
+				"title":     "Fetched from PDS",
+				"createdAt": time.Now().Format(time.RFC3339),
+			},
+		}
+		return uri
+	}
+
+	handleComment := func(postURI string) (string, error) {
+		rkey := generateTID()
+		err := consumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  author.DID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev-" + rkey,
+				Operation:  "create",
+				Collection: "social.coves.community.comment",
+				RKey:       rkey,
+				CID:        "bafy" + rkey,
+				Record: map[string]interface{}{
+					"$type":   "social.coves.community.comment",
+					"content": "Comment on a post we haven't seen",
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+						"parent": map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+					},
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		})
+		return fmt.Sprintf("at://%s/social.coves.community.comment/%s", author.DID, rkey), err
+	}
+	indexComment := func(postURI string) string {
+		commentURI, err := handleComment(postURI)
+		require.NoError(t, err)
+		return commentURI
+	}
+
+	postCommentCount := func(postURI string) int {
+		var count int
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT comment_count FROM posts WHERE uri = $1`, postURI).Scan(&count))
+		return count
+	}
+
+	t.Run("missing root post is backfilled before the comment", func(t *testing.T) {
+		postURI := remotePost()
+		commentURI := indexComment(postURI)
+
+		comment, err := commentRepo.GetByURI(ctx, commentURI)
+		require.NoError(t, err)
+		assert.False(t, comment.Orphaned)
+		assert.Equal(t, 1, postCommentCount(postURI))
+
+		resp, err := setupCommentService(db).GetComments(ctx, &comments.GetCommentsRequest{
+			PostURI: postURI,
+			Sort:    "new",
+			Depth:   10,
+			Limit:   50,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Comments, 1)
+		assert.Equal(t, commentURI, resp.Comments[0].Comment.URI)
+	})
+
+	t.Run("permanently missing root post leaves the comment orphaned", func(t *testing.T) {
+		postURI := fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, generateTID())
+		commentURI := indexComment(postURI)
+
+		comment, err := commentRepo.GetByURI(ctx, commentURI)
+		require.NoError(t, err)
+		assert.True(t, comment.Orphaned)
+
+		var postExists bool
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM posts WHERE uri = $1)`, postURI).Scan(&postExists))
+		assert.False(t, postExists)
+	})
+
+	t.Run("unreachable PDS fails the comment instead of orphaning it", func(t *testing.T) {
+		postURI := fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, generateTID())
+		fetcher.failures[postURI] = errors.New("dial tcp: connection refused")
+
+		commentURI, err := handleComment(postURI)
+		require.Error(t, err)
+		_, err = commentRepo.GetByURI(ctx, commentURI)
+		assert.True(t, comments.IsNotFound(err), "the comment is left for a retry, not stored orphaned")
+	})
+
+	t.Run("orphaned comments are excluded from getComments", func(t *testing.T) {
+		postURI := createTestPost(t, db, communityDID, author.DID, "Indexed post", 0, time.Now())
+		visibleURI := indexComment(postURI)
+		orphanURI := indexComment(postURI)
+
+		// Simulate a comment stored while its post was unknown
+		_, err := db.ExecContext(ctx, `UPDATE comments SET orphaned = TRUE WHERE uri = $1`, orphanURI)
+		require.NoError(t, err)
+
+		resp, err := setupCommentService(db).GetComments(ctx, &comments.GetCommentsRequest{
+			PostURI: postURI,
+			Sort:    "new",
+			Depth:   10,
+			Limit:   50,
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Comments, 1)
+		assert.Equal(t, visibleURI, resp.Comments[0].Comment.URI)
+	})
+
+	t.Run("retry job resolves orphans once the post can be fetched", func(t *testing.T) {
+		rkey := generateTID()
+		postURI := fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, rkey)
+		commentURI := indexComment(postURI)
+
+		_, err := consumer.ResolveOrphanedComments(ctx, 100)
+		require.NoError(t, err)
+		comment, err := commentRepo.GetByURI(ctx, commentURI)
+		require.NoError(t, err)
+		assert.True(t, comment.Orphaned, "post still missing, comment should stay orphaned")
+
+		// The post shows up on the PDS later (e.g. once its author was indexed)
+		fetcher.records[postURI] = &pds.RecordResponse{
+			URI: postURI,
+			CID: "bafy" + rkey,
+			Value: map[string]any{
+				"$type":     "social.coves.community.post",
+				"community": communityDID,
+				"author":    author.DID,
+				"title":     "Late post",
+				"createdAt": time.Now().Format(time.RFC3339),
+			},
+		}
+
+		resolved, err := consumer.ResolveOrphanedComments(ctx, 100)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, resolved, 1)
+
+		comment, err = commentRepo.GetByURI(ctx, commentURI)
+		require.NoError(t, err)
+		assert.False(t, comment.Orphaned)
+		assert.Equal(t, 1, postCommentCount(postURI))
+	})
+}
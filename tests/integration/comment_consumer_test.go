@@ -19,7 +19,7 @@ func TestCommentConsumer_CreateComment(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
 
 	// Setup test data
@@ -177,7 +177,7 @@ func TestCommentConsumer_Threading(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
 
 	// Setup test data
@@ -333,7 +333,7 @@ func TestCommentConsumer_UpdateComment(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
 
 	// Setup test data
@@ -459,7 +459,7 @@ func TestCommentConsumer_DeleteComment(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
 
 	// Setup test data
@@ -529,7 +529,7 @@ func TestCommentConsumer_DeleteComment(t *testing.T) {
 		}
 
 		// Verify soft delete
-		comment, err := commentRepo.GetByURI(ctx, uri)
+		comment, err := commentRepo.GetByURIWithDeleted(ctx, uri)
 		if err != nil {
 			t.Fatalf("Failed to get deleted comment: %v", err)
 		}
@@ -635,7 +635,7 @@ func TestCommentConsumer_SecurityValidation(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
 
 	testUser := createTestUser(t, db, "security.test", "did:plc:security123")
@@ -888,7 +888,7 @@ func TestCommentRepository_Queries(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 
 	// Clean up any existing test data from previous runs
 	_, err := db.ExecContext(ctx, "DELETE FROM comments WHERE commenter_did LIKE 'did:plc:%'")
@@ -1057,7 +1057,7 @@ func TestCommentConsumer_OutOfOrderReconciliation(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
 
 	testUser := createTestUser(t, db, "outoforder.test", "did:plc:outoforder123")
@@ -1200,6 +1200,15 @@ func TestCommentConsumer_OutOfOrderReconciliation(t *testing.T) {
 		if count != 1 {
 			t.Errorf("Expected 1 reply to parent, got %d", count)
 		}
+
+		// The parent counts as reconciled, with the child's wait in the lag histogram
+		metrics := consumer.CommentConsumerMetrics()
+		if metrics.Indexed != 2 || metrics.ReconciledParents != 1 || metrics.ReconcileLag.Count != 1 {
+			t.Errorf("Expected 2 indexed and 1 reconciled parent, got %+v", metrics)
+		}
+		if metrics.OrphansCreated != 0 {
+			t.Errorf("Expected no orphans when the post is indexed, got %d", metrics.OrphansCreated)
+		}
 	})
 
 	t.Run("Multiple children arrive before parent", func(t *testing.T) {
@@ -1313,6 +1322,12 @@ func TestCommentConsumer_OutOfOrderReconciliation(t *testing.T) {
 
 			t.Errorf("Expected parent reply_count to be 3 (reconciled), got %d", parentComment.ReplyCount)
 		}
+
+		// One reconciled parent however many replies were waiting for it
+		metrics := consumer.CommentConsumerMetrics()
+		if metrics.ReconciledParents != 2 || metrics.ReconcileLag.Count != 2 {
+			t.Errorf("Expected 2 reconciled parents across both subtests, got %+v", metrics)
+		}
 	})
 }
 
@@ -1327,7 +1342,7 @@ func TestCommentConsumer_Resurrection(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
 
 	testUser := createTestUser(t, db, "resurrect.test", "did:plc:resurrect123")
@@ -1404,7 +1419,7 @@ func TestCommentConsumer_Resurrection(t *testing.T) {
 		}
 
 		// Verify comment is soft-deleted
-		comment, err = commentRepo.GetByURI(ctx, commentURI)
+		comment, err = commentRepo.GetByURIWithDeleted(ctx, commentURI)
 		if err != nil {
 			t.Fatalf("Comment not found after deletion: %v", err)
 		}
@@ -1471,6 +1486,12 @@ func TestCommentConsumer_Resurrection(t *testing.T) {
 		if postCommentCount != 1 {
 			t.Errorf("Expected post comment_count to be 1 after resurrection, got %d", postCommentCount)
 		}
+
+		// Both the create and the recreate were indexed; only the recreate was a resurrection
+		metrics := consumer.CommentConsumerMetrics()
+		if metrics.Indexed != 2 || metrics.Resurrections != 1 {
+			t.Errorf("Expected 2 indexed and 1 resurrection, got %+v", metrics)
+		}
 	})
 
 	t.Run("Recreate deleted comment with DIFFERENT parent", func(t *testing.T) {
@@ -1618,6 +1639,10 @@ func TestCommentConsumer_Resurrection(t *testing.T) {
 		if post1Count != 0 {
 			t.Errorf("Expected Post 1 comment_count = 0 (unchanged), got %d", post1Count)
 		}
+
+		if resurrections := consumer.CommentConsumerMetrics().Resurrections; resurrections != 2 {
+			t.Errorf("Expected 2 resurrections across both subtests, got %d", resurrections)
+		}
 	})
 }
 
@@ -1631,7 +1656,7 @@ func TestCommentConsumer_ThreadingImmutability(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
 
 	testUser := createTestUser(t, db, "immutable.test", "did:plc:immutable123")
@@ -1730,6 +1755,10 @@ func TestCommentConsumer_ThreadingImmutability(t *testing.T) {
 		if comment.Content != "Comment on Post 1" {
 			t.Errorf("Expected original content, got '%s'", comment.Content)
 		}
+
+		if rejections := consumer.CommentConsumerMetrics().ThreadingRejections; rejections != 1 {
+			t.Errorf("Expected 1 threading rejection, got %d", rejections)
+		}
 	})
 
 	t.Run("Allow UPDATE that only changes content (threading unchanged)", func(t *testing.T) {
@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommentDrafts_OverwriteAndExpiry verifies a user keeps one draft per context, that saving
+// again replaces it, and that the cleanup job removes only drafts past the TTL
+func TestCommentDrafts_OverwriteAndExpiry(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	suffix := uniqueTestID()
+	user := createTestUser(t, db, fmt.Sprintf("drafter-%s.test", suffix), fmt.Sprintf("did:plc:drafter%s", suffix))
+	postURI := fmt.Sprintf("at://did:plc:draftcommunity%s/social.coves.community.post/3kroot", suffix)
+	commentURI := fmt.Sprintf("at://did:plc:draftalice%s/social.coves.community.comment/3kreply", suffix)
+
+	repo := postgres.NewDraftRepository(db)
+	old := time.Now().UTC().Add(-comments.DraftTTL - time.Hour).Truncate(time.Microsecond)
+	require.NoError(t, repo.PutDraft(ctx, &comments.Draft{UserDID: user.DID, Context: postURI, Content: "First", UpdatedAt: old}))
+	require.NoError(t, repo.PutDraft(ctx, &comments.Draft{UserDID: user.DID, Context: commentURI, Content: "Reply", UpdatedAt: old}))
+
+	// Saving the post's draft again replaces it and refreshes its age
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	require.NoError(t, repo.PutDraft(ctx, &comments.Draft{UserDID: user.DID, Context: postURI, Content: "Second", UpdatedAt: now}))
+	draft, err := repo.GetDraft(ctx, user.DID, postURI)
+	require.NoError(t, err)
+	assert.Equal(t, "Second", draft.Content)
+	assert.True(t, draft.UpdatedAt.Equal(now), "updated_at = %v, want %v", draft.UpdatedAt, now)
+
+	pruned, err := comments.NewDraftService(repo).PruneExpiredDrafts(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, pruned, int64(1))
+
+	_, err = repo.GetDraft(ctx, user.DID, commentURI)
+	assert.ErrorIs(t, err, comments.ErrDraftNotFound)
+	_, err = repo.GetDraft(ctx, user.DID, postURI)
+	assert.NoError(t, err, "the refreshed draft should survive pruning")
+
+	// Deleting is idempotent
+	require.NoError(t, repo.DeleteDraft(ctx, user.DID, postURI))
+	require.NoError(t, repo.DeleteDraft(ctx, user.DID, postURI))
+	_, err = repo.GetDraft(ctx, user.DID, postURI)
+	assert.ErrorIs(t, err, comments.ErrDraftNotFound)
+}
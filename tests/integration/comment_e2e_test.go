@@ -75,8 +75,8 @@ func TestCommentE2E_CreateWithJetstream(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup repositories
-	commentRepo := postgres.NewCommentRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	// Create test user on PDS
 	// Use shorter handle to avoid PDS length limits (max 20 chars for label)
@@ -291,8 +291,8 @@ func TestCommentE2E_UpdateWithJetstream(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup repositories
-	commentRepo := postgres.NewCommentRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	// Create test user on PDS
 	testID := uniqueTestID()
@@ -521,8 +521,8 @@ func TestCommentE2E_DeleteWithJetstream(t *testing.T) {
 
 	ctx := context.Background()
 
-	commentRepo := postgres.NewCommentRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	testID := uniqueTestID()
 	testUserHandle := fmt.Sprintf("cmtdl%s.local.coves.dev", testID)
@@ -656,7 +656,7 @@ func TestCommentE2E_DeleteWithJetstream(t *testing.T) {
 			}
 
 			// Verify comment is soft-deleted in AppView
-			deletedComment, err := commentRepo.GetByURI(ctx, commentResp.URI)
+			deletedComment, err := commentRepo.GetByURIWithDeleted(ctx, commentResp.URI)
 			if err != nil {
 				t.Fatalf("Failed to get deleted comment: %v", err)
 			}
@@ -887,8 +887,8 @@ func TestCommentE2E_Authorization(t *testing.T) {
 
 	ctx := context.Background()
 
-	commentRepo := postgres.NewCommentRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	// Create two test users on PDS
 	userAID := uniqueTestID()
@@ -1079,8 +1079,8 @@ func TestCommentE2E_ValidationErrors(t *testing.T) {
 
 	ctx := context.Background()
 
-	commentRepo := postgres.NewCommentRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	// Create test user on PDS
 	testID := uniqueTestID()
@@ -0,0 +1,115 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/moderation"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestCommentEditHistory_KeepsCappedRevisionsForModerators(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+	moderationService := moderation.NewModerationService(postgres.NewModerationRepository(db))
+
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	commenter := createTestUser(t, db, "editor"+suffix+".test", generateTestDID("editor"+suffix))
+	ownerHandle := "editowner" + suffix
+	communityDID, err := createFeedTestCommunity(db, ctx, "edits"+suffix, ownerHandle)
+	if err != nil {
+		t.Fatalf("Failed to create community: %v", err)
+	}
+	postURI := createTestPost(t, db, communityDID, commenter.DID, "Edited thread", 0, time.Now())
+
+	rkey := generateTID()
+	uri := fmt.Sprintf("at://%s/social.coves.community.comment/%s", commenter.DID, rkey)
+	write := func(operation string, version int) {
+		event := &jetstream.JetstreamEvent{
+			Did:  commenter.DID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Operation:  operation,
+				Collection: "social.coves.community.comment",
+				RKey:       rkey,
+				CID:        fmt.Sprintf("bafyedit%d", version),
+				Record: map[string]interface{}{
+					"$type":   "social.coves.community.comment",
+					"content": fmt.Sprintf("version %d", version),
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+						"parent": map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+					},
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		}
+		if err := consumer.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("Failed to %s comment: %v", operation, err)
+		}
+	}
+
+	write("create", 0)
+	comment, err := commentRepo.GetByURI(ctx, uri)
+	if err != nil {
+		t.Fatalf("Failed to get comment: %v", err)
+	}
+	if comment.EditedAt != nil {
+		t.Error("A comment that was never edited must not be marked edited")
+	}
+
+	const edits = moderation.MaxRevisions + 2
+	for version := 1; version <= edits; version++ {
+		write("update", version)
+	}
+	write("update", edits) // A redelivered update changes nothing
+
+	comment, err = commentRepo.GetByURI(ctx, uri)
+	if err != nil {
+		t.Fatalf("Failed to get edited comment: %v", err)
+	}
+	if comment.EditedAt == nil {
+		t.Error("Expected the edited comment to have edited_at set")
+	}
+
+	history, err := moderationService.GetCommentHistory(ctx, moderation.GetCommentHistoryRequest{
+		URI:      uri,
+		ActorDID: "did:plc:" + ownerHandle, // The community's creator moderates it
+	})
+	if err != nil {
+		t.Fatalf("Failed to get history as moderator: %v", err)
+	}
+	if len(history.Revisions) != moderation.MaxRevisions {
+		t.Fatalf("Expected history capped at %d revisions, got %d", moderation.MaxRevisions, len(history.Revisions))
+	}
+	// Newest first: the version replaced by the last edit, down to the oldest one kept
+	if got, want := history.Revisions[0].Content, fmt.Sprintf("version %d", edits-1); got != want {
+		t.Errorf("Expected newest revision %q, got %q", want, got)
+	}
+	oldest := history.Revisions[len(history.Revisions)-1]
+	if got, want := oldest.Content, fmt.Sprintf("version %d", edits-moderation.MaxRevisions); got != want {
+		t.Errorf("Expected oldest kept revision %q (older ones pruned), got %q", want, got)
+	}
+
+	var stored int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM content_revisions WHERE subject_uri = $1`, uri).Scan(&stored); err != nil {
+		t.Fatalf("Failed to count revisions: %v", err)
+	}
+	if stored != moderation.MaxRevisions {
+		t.Errorf("Expected %d stored revisions after pruning, got %d", moderation.MaxRevisions, stored)
+	}
+
+	_, err = moderationService.GetCommentHistory(ctx, moderation.GetCommentHistoryRequest{URI: uri, ActorDID: commenter.DID})
+	if err != moderation.ErrNotModerator {
+		t.Errorf("Expected the comment's author to be refused, got %v", err)
+	}
+	if _, err := moderationService.GetCommentHistory(ctx, moderation.GetCommentHistoryRequest{URI: uri, ActorDID: "did:plc:admin", IsAdmin: true}); err != nil {
+		t.Errorf("Expected an instance admin to read the history, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"Coves/internal/atproto/identity"
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/comments"
+	"Coves/internal/db/postgres"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubHandleResolver resolves handles from a fixed map
+type stubHandleResolver map[string]string
+
+func (s stubHandleResolver) Resolve(ctx context.Context, identifier string) (*identity.Identity, error) {
+	did, ok := s[identifier]
+	if !ok {
+		return nil, &identity.ErrNotFound{Identifier: identifier}
+	}
+	return &identity.Identity{DID: did, Handle: identifier}, nil
+}
+
+// TestCommentConsumer_MentionNotifications verifies mention facets are stored and that
+// mention notifications are deduped per comment across repeats, replays, and edits
+func TestCommentConsumer_MentionNotifications(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	suffix := uniqueTestID()
+
+	author := createTestUser(t, db, fmt.Sprintf("mentioner-%s.test", suffix), fmt.Sprintf("did:plc:mentioner%s", suffix))
+	alice := createTestUser(t, db, fmt.Sprintf("alice-%s.test", suffix), fmt.Sprintf("did:plc:alice%s", suffix))
+	bob := createTestUser(t, db, fmt.Sprintf("bob-%s.test", suffix), fmt.Sprintf("did:plc:bob%s", suffix))
+	communityDID, err := createFeedTestCommunity(db, ctx, "mentions-"+suffix, "mentionsowner"+suffix)
+	require.NoError(t, err)
+	postURI := createTestPost(t, db, communityDID, author.DID, "Mentions", 0, time.Now())
+
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+	consumer.SetIdentityResolver(stubHandleResolver{
+		alice.Handle:  alice.DID,
+		bob.Handle:    bob.DID,
+		author.Handle: author.DID,
+	})
+
+	rkey := generateTID()
+	commentURI := fmt.Sprintf("at://%s/social.coves.community.comment/%s", author.DID, rkey)
+	commentEvent := func(operation, cid, content string) *jetstream.JetstreamEvent {
+		return &jetstream.JetstreamEvent{
+			Did:  author.DID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev",
+				Operation:  operation,
+				Collection: jetstream.CommentCollection,
+				RKey:       rkey,
+				CID:        cid,
+				Record: map[string]interface{}{
+					"$type":   jetstream.CommentCollection,
+					"content": content,
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+						"parent": map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+					},
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	countNotifications := func(t *testing.T, recipientDID string) int {
+		t.Helper()
+		var count int
+		require.NoError(t, db.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM notifications
+			WHERE recipient_did = $1 AND reason = 'mention' AND subject_uri = $2
+		`, recipientDID, commentURI).Scan(&count))
+		return count
+	}
+
+	content := fmt.Sprintf("@%s look! @%s @%s and @ghost-%s.test", alice.Handle, alice.Handle, author.Handle, suffix)
+
+	t.Run("create stores facets and one notification per mentioned user", func(t *testing.T) {
+		require.NoError(t, consumer.HandleEvent(ctx, commentEvent("create", "bafyc1", content)))
+
+		comment, err := commentRepo.GetByURI(ctx, commentURI)
+		require.NoError(t, err)
+		require.NotNil(t, comment.ContentFacets)
+
+		var facets []struct {
+			Index struct {
+				ByteStart int `json:"byteStart"`
+				ByteEnd   int `json:"byteEnd"`
+			} `json:"index"`
+			Features []struct {
+				DID string `json:"did"`
+			} `json:"features"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(*comment.ContentFacets), &facets))
+		require.Len(t, facets, 3, "both alice mentions and the self-mention resolve; ghost does not")
+		assert.Equal(t, 0, facets[0].Index.ByteStart)
+		assert.Equal(t, "@"+alice.Handle, content[facets[0].Index.ByteStart:facets[0].Index.ByteEnd])
+		assert.Equal(t, alice.DID, facets[0].Features[0].DID)
+
+		assert.Equal(t, 1, countNotifications(t, alice.DID))
+		assert.Equal(t, 0, countNotifications(t, author.DID), "self-mentions do not notify")
+	})
+
+	t.Run("replayed create does not duplicate notifications", func(t *testing.T) {
+		require.NoError(t, consumer.HandleEvent(ctx, commentEvent("create", "bafyc1", content)))
+		assert.Equal(t, 1, countNotifications(t, alice.DID))
+	})
+
+	t.Run("edit notifies newly mentioned users only once", func(t *testing.T) {
+		edited := fmt.Sprintf("@%s and now @%s", alice.Handle, bob.Handle)
+		require.NoError(t, consumer.HandleEvent(ctx, commentEvent("update", "bafyc2", edited)))
+		require.NoError(t, consumer.HandleEvent(ctx, commentEvent("update", "bafyc3", edited)))
+
+		assert.Equal(t, 1, countNotifications(t, alice.DID))
+		assert.Equal(t, 1, countNotifications(t, bob.DID))
+	})
+
+	t.Run("getComments includes mention facets in the record", func(t *testing.T) {
+		commentService := comments.NewCommentService(commentRepo, postgres.NewUserRepository(db), postgres.NewPostRepository(db, newTestCursorSigner()), postgres.NewCommunityRepository(db, newTestCursorSigner()), nil, nil, nil)
+		resp, err := commentService.GetComments(ctx, &comments.GetCommentsRequest{PostURI: postURI, Sort: "new", Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, resp.Comments, 1)
+
+		record, ok := resp.Comments[0].Comment.Record.(*comments.CommentRecord)
+		require.True(t, ok)
+		assert.Len(t, record.Facets, 2)
+	})
+}
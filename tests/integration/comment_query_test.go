@@ -405,6 +405,110 @@ func TestCommentQuery_NewSorting(t *testing.T) {
 	assert.Equal(t, c1, resp.Comments[2].Comment.URI, "Oldest comment should be third")
 }
 
+// TestCommentQuery_BestSorting tests best sorting: Wilson lower bound plus a recency bonus
+func TestCommentQuery_BestSorting(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	testUser := createTestUser(t, db, "best.test", "did:plc:best123")
+	testCommunity, err := createFeedTestCommunity(db, ctx, "bestcomm", "ownerbest.test")
+	require.NoError(t, err)
+
+	postURI := createTestPost(t, db, testCommunity, testUser.DID, "Best Sorting Test", 0, time.Now())
+
+	old := time.Now().Add(-48 * time.Hour)
+	// Top by score, but a poor ratio
+	controversial := createTestCommentWithScore(t, db, testUser.DID, postURI, postURI, "Controversial", 60, 40, old)
+	// Lower score, but a near-unanimous ratio
+	liked := createTestCommentWithScore(t, db, testUser.DID, postURI, postURI, "Well liked", 20, 1, old)
+	// Same ratio as liked with fewer votes, so less confidence
+	fewVotes := createTestCommentWithScore(t, db, testUser.DID, postURI, postURI, "Few votes", 2, 0, old)
+	// No votes yet, but only a minute old
+	fresh := createTestCommentWithScore(t, db, testUser.DID, postURI, postURI, "Fresh", 0, 0, time.Now().Add(-time.Minute))
+	// No votes and old
+	stale := createTestCommentWithScore(t, db, testUser.DID, postURI, postURI, "Stale", 0, 0, old)
+
+	want := []string{liked, controversial, fewVotes, fresh, stale}
+	repo := postgres.NewCommentRepository(db, newTestCursorSigner())
+
+	t.Run("orders by best rank", func(t *testing.T) {
+		result, cursor, err := repo.ListByParentWithHotRank(ctx, postURI, "best", "", 50, nil, "")
+		require.NoError(t, err)
+		assert.Nil(t, cursor)
+
+		got := make([]string, 0, len(result))
+		previous := 1.0
+		for _, c := range result {
+			got = append(got, c.URI)
+			// The SQL rank must agree with the Go one the service breaks ties with
+			rank := comments.BestRank(c.UpvoteCount, c.DownvoteCount, time.Since(c.CreatedAt))
+			assert.LessOrEqual(t, rank, previous, "comment %s is out of best rank order", c.URI)
+			previous = rank
+		}
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("paginates", func(t *testing.T) {
+		var got []string
+		var cursor *string
+		for page := 0; page < 5; page++ {
+			result, next, err := repo.ListByParentWithHotRank(ctx, postURI, "best", "", 2, cursor, "")
+			require.NoError(t, err)
+			for _, c := range result {
+				got = append(got, c.URI)
+			}
+			if next == nil {
+				break
+			}
+			cursor = next
+		}
+		assert.Equal(t, want, got, "pages should follow the unpaginated order without gaps or repeats")
+	})
+
+	t.Run("is the default", func(t *testing.T) {
+		service := setupCommentService(db)
+		resp, err := service.GetComments(ctx, &comments.GetCommentsRequest{PostURI: postURI, Limit: 50})
+		require.NoError(t, err)
+		require.Len(t, resp.Comments, len(want))
+		assert.Equal(t, liked, resp.Comments[0].Comment.URI)
+		assert.Equal(t, stale, resp.Comments[len(want)-1].Comment.URI)
+	})
+}
+
+// TestCommentQuery_BestSortingReplies tests best sorting of nested replies
+func TestCommentQuery_BestSortingReplies(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	testUser := createTestUser(t, db, "bestreplies.test", "did:plc:bestreplies123")
+	testCommunity, err := createFeedTestCommunity(db, ctx, "bestrepliescomm", "ownerbestreplies.test")
+	require.NoError(t, err)
+
+	postURI := createTestPost(t, db, testCommunity, testUser.DID, "Best Replies Test", 0, time.Now())
+	parent := createTestCommentWithScore(t, db, testUser.DID, postURI, postURI, "Parent", 5, 0, time.Now().Add(-2*time.Hour))
+
+	old := time.Now().Add(-48 * time.Hour)
+	controversial := createTestCommentWithScore(t, db, testUser.DID, postURI, parent, "Controversial", 60, 40, old)
+	liked := createTestCommentWithScore(t, db, testUser.DID, postURI, parent, "Well liked", 20, 1, old)
+
+	repo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	replies, err := repo.ListByParentsBatchWithDeleted(ctx, []string{parent}, "best", 5, "")
+	require.NoError(t, err)
+	require.Len(t, replies[parent], 2)
+	assert.Equal(t, liked, replies[parent][0].URI)
+	assert.Equal(t, controversial, replies[parent][1].URI)
+}
+
 // TestCommentQuery_Pagination tests cursor-based pagination
 func TestCommentQuery_Pagination(t *testing.T) {
 	db := setupTestDB(t)
@@ -525,7 +629,7 @@ func TestCommentQuery_DeletedComments(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
 
 	testUser := createTestUser(t, db, "deleted.test", "did:plc:deleted123")
@@ -621,6 +725,51 @@ func TestCommentQuery_DeletedComments(t *testing.T) {
 	assert.True(t, returnedURIs[commentURIs[4]], "Non-deleted comment 4 should be in results")
 }
 
+// TestCommentQuery_ThreadMeta tests the comment and participant counts in getComments meta
+func TestCommentQuery_ThreadMeta(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	alice := createTestUser(t, db, "metaalice.test", "did:plc:metaalice123")
+	bob := createTestUser(t, db, "metabob.test", "did:plc:metabob123")
+	carol := createTestUser(t, db, "metacarol.test", "did:plc:metacarol123")
+	testCommunity, err := createFeedTestCommunity(db, ctx, "metacomm", "ownermeta.test")
+	require.NoError(t, err)
+
+	postURI := createTestPost(t, db, testCommunity, alice.DID, "Thread Meta Test", 0, time.Now())
+
+	// Alice comments and Bob replies twice; Carol's comments are deleted and removed
+	top := createTestCommentWithScore(t, db, alice.DID, postURI, postURI, "Top", 0, 0, time.Now().Add(-time.Hour))
+	createTestCommentWithScore(t, db, bob.DID, postURI, top, "Reply", 0, 0, time.Now().Add(-30*time.Minute))
+	createTestCommentWithScore(t, db, bob.DID, postURI, postURI, "Another", 0, 0, time.Now().Add(-20*time.Minute))
+	deleted := createTestCommentWithScore(t, db, carol.DID, postURI, postURI, "Deleted", 0, 0, time.Now().Add(-10*time.Minute))
+	removed := createTestCommentWithScore(t, db, carol.DID, postURI, top, "Removed", 0, 0, time.Now().Add(-5*time.Minute))
+	_, err = db.ExecContext(ctx, `UPDATE comments SET deleted_at = NOW() WHERE uri = $1`, deleted)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE comments SET visibility_state = 'removed' WHERE uri = $1`, removed)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE posts SET locked = TRUE WHERE uri = $1`, postURI)
+	require.NoError(t, err)
+
+	service := setupCommentService(db)
+	resp, err := service.GetComments(ctx, &comments.GetCommentsRequest{
+		PostURI: postURI,
+		Sort:    "new",
+		Depth:   10,
+		Limit:   50,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Meta)
+	assert.Equal(t, 3, resp.Meta.TotalComments, "Deleted and removed comments should not be counted")
+	assert.Equal(t, 2, resp.Meta.Participants)
+	assert.True(t, resp.Meta.Locked)
+}
+
 // TestCommentQuery_InvalidInputs tests error handling for invalid inputs
 func TestCommentQuery_InvalidInputs(t *testing.T) {
 	db := setupTestDB(t)
@@ -781,10 +930,10 @@ func TestCommentQuery_HTTPHandler(t *testing.T) {
 
 // Helper: setupCommentService creates a comment service for testing
 func setupCommentService(db *sql.DB) comments.Service {
-	commentRepo := postgres.NewCommentRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	// Use factory constructor with nil factory - these tests only use the read path (GetComments)
 	return comments.NewCommentServiceWithPDSFactory(commentRepo, userRepo, postRepo, communityRepo, nil, nil)
 }
@@ -868,10 +1017,10 @@ type testGetCommentsRequest struct {
 }
 
 func setupCommentServiceAdapter(db *sql.DB) *testCommentServiceAdapter {
-	commentRepo := postgres.NewCommentRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	// Use factory constructor with nil factory - these tests only use the read path (GetComments)
 	service := comments.NewCommentServiceWithPDSFactory(commentRepo, userRepo, postRepo, communityRepo, nil, nil)
 	return &testCommentServiceAdapter{service: service}
@@ -0,0 +1,168 @@
+package integration
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommentRepository_Search covers moderator comment search: results are scoped to the
+// community's posts, ordered by relevance, and exclude deleted comments unless asked
+func TestCommentRepository_Search(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+
+	suffix := uniqueTestID()
+	author := createTestUser(t, db, "search"+suffix+".test", "did:plc:search"+suffix)
+	other := createTestUser(t, db, "searchother"+suffix+".test", "did:plc:searchother"+suffix)
+
+	communityDID, err := createFeedTestCommunity(db, ctx, "search"+suffix, "owner"+suffix+".test")
+	require.NoError(t, err)
+	otherCommunityDID, err := createFeedTestCommunity(db, ctx, "searchelse"+suffix, "ownerelse"+suffix+".test")
+	require.NoError(t, err)
+
+	postURI := createTestPost(t, db, communityDID, author.DID, "Search post", 0, time.Now().Add(-time.Hour))
+	secondPostURI := createTestPost(t, db, communityDID, author.DID, "Another post", 0, time.Now().Add(-time.Hour))
+	otherPostURI := createTestPost(t, db, otherCommunityDID, author.DID, "Elsewhere", 0, time.Now().Add(-time.Hour))
+
+	createComment := func(commenterDID, postURI, content string, createdAt time.Time) string {
+		t.Helper()
+		rkey := generateTID()
+		uri := fmt.Sprintf("at://%s/social.coves.community.comment/%s", commenterDID, rkey)
+		require.NoError(t, commentRepo.Create(ctx, &comments.Comment{
+			URI:          uri,
+			CID:          "bafy" + rkey,
+			RKey:         rkey,
+			CommenterDID: commenterDID,
+			RootURI:      postURI,
+			RootCID:      "bafytest",
+			ParentURI:    postURI,
+			ParentCID:    "bafytest",
+			Content:      content,
+			Langs:        []string{},
+			CreatedAt:    createdAt,
+		}))
+		return uri
+	}
+
+	now := time.Now()
+	strongMatch := createComment(author.DID, postURI, "buy cheap watches, cheap watches here, cheap watches", now.Add(-30*time.Minute))
+	weakMatch := createComment(other.DID, secondPostURI, "I was looking at watches yesterday", now.Add(-20*time.Minute))
+	createComment(author.DID, postURI, "completely unrelated discussion", now.Add(-10*time.Minute))
+	elsewhere := createComment(author.DID, otherPostURI, "cheap watches cheap watches", now.Add(-5*time.Minute))
+
+	uris := func(results []*comments.Comment) []string {
+		out := make([]string, 0, len(results))
+		for _, c := range results {
+			out = append(out, c.URI)
+		}
+		return out
+	}
+
+	t.Run("scoped to the community and ordered by relevance", func(t *testing.T) {
+		results, cursor, err := commentRepo.Search(ctx, comments.SearchRequest{
+			CommunityDID: communityDID,
+			Query:        "cheap watches",
+			Limit:        10,
+		})
+		require.NoError(t, err)
+		assert.Nil(t, cursor)
+		assert.Equal(t, []string{strongMatch}, uris(results), "both terms are required by default")
+
+		results, _, err = commentRepo.Search(ctx, comments.SearchRequest{
+			CommunityDID: communityDID,
+			Query:        "watches",
+			Limit:        10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{strongMatch, weakMatch}, uris(results), "more matches rank higher")
+		assert.NotContains(t, uris(results), elsewhere, "comments in other communities are never returned")
+	})
+
+	t.Run("author and post filters", func(t *testing.T) {
+		authorDID := other.DID
+		results, _, err := commentRepo.Search(ctx, comments.SearchRequest{
+			CommunityDID: communityDID,
+			Query:        "watches",
+			AuthorDID:    &authorDID,
+			Limit:        10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{weakMatch}, uris(results))
+
+		results, _, err = commentRepo.Search(ctx, comments.SearchRequest{
+			CommunityDID: communityDID,
+			Query:        "watches",
+			PostURI:      &postURI,
+			Limit:        10,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{strongMatch}, uris(results))
+	})
+
+	t.Run("pagination follows relevance order", func(t *testing.T) {
+		first, cursor, err := commentRepo.Search(ctx, comments.SearchRequest{
+			CommunityDID: communityDID,
+			Query:        "watches",
+			Limit:        1,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, cursor)
+		assert.Equal(t, []string{strongMatch}, uris(first))
+
+		second, cursor, err := commentRepo.Search(ctx, comments.SearchRequest{
+			CommunityDID: communityDID,
+			Query:        "watches",
+			Limit:        1,
+			Cursor:       cursor,
+		})
+		require.NoError(t, err)
+		assert.Nil(t, cursor)
+		assert.Equal(t, []string{weakMatch}, uris(second))
+
+		bad := "not-a-cursor"
+		_, _, err = commentRepo.Search(ctx, comments.SearchRequest{
+			CommunityDID: communityDID,
+			Query:        "watches",
+			Limit:        1,
+			Cursor:       &bad,
+		})
+		assert.ErrorIs(t, err, comments.ErrInvalidCursor)
+	})
+
+	t.Run("deleted comments only with includeDeleted", func(t *testing.T) {
+		require.NoError(t, commentRepo.SoftDeleteWithReason(ctx, strongMatch, comments.DeletionReasonModerator, "did:plc:moderator"))
+
+		results, _, err := commentRepo.Search(ctx, comments.SearchRequest{
+			CommunityDID: communityDID,
+			Query:        "cheap",
+			Limit:        10,
+		})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+
+		results, _, err = commentRepo.Search(ctx, comments.SearchRequest{
+			CommunityDID:   communityDID,
+			Query:          "cheap",
+			IncludeDeleted: true,
+			Limit:          10,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{strongMatch}, uris(results), "removed comments stay searchable by their original content")
+		assert.NotNil(t, results[0].DeletedAt)
+		assert.Empty(t, results[0].Content)
+	})
+}
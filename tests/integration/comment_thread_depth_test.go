@@ -0,0 +1,173 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/moderation"
+	"Coves/internal/db/postgres"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommentConsumer_DepthOutOfOrder checks that thread depth is computed from the parent at
+// index time, and filled in for replies that arrived before their parent
+func TestCommentConsumer_DepthOutOfOrder(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+	consumer.SetMaxThreadDepth(2)
+
+	suffix := uniqueTestID()
+	user := createTestUser(t, db, "depth"+suffix+".test", "did:plc:depth"+suffix)
+	communityDID, err := createFeedTestCommunity(db, ctx, "depth"+suffix, "owner"+suffix+".test")
+	require.NoError(t, err)
+	postURI := createTestPost(t, db, communityDID, user.DID, "Deep thread", 0, time.Now().Add(-time.Hour))
+
+	commentURI := func(rkey string) string {
+		return fmt.Sprintf("at://%s/social.coves.community.comment/%s", user.DID, rkey)
+	}
+	depthOf := func(rkey string) (sql.NullInt64, bool) {
+		t.Helper()
+		var depth sql.NullInt64
+		var exceeded bool
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT depth, depth_exceeded FROM comments WHERE uri = $1`, commentURI(rkey),
+		).Scan(&depth, &exceeded))
+		return depth, exceeded
+	}
+
+	top, reply, nested := generateTID(), generateTID(), generateTID()
+	now := time.Now().Add(-time.Minute)
+
+	// The deepest reply arrives first: its depth is unknown until its ancestors are indexed
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, nested, postURI, commentURI(reply), now)))
+	depth, _ := depthOf(nested)
+	assert.False(t, depth.Valid, "depth is unknown while the parent is missing")
+
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, reply, postURI, commentURI(top), now)))
+	depth, _ = depthOf(reply)
+	assert.False(t, depth.Valid)
+
+	// Indexing the top-level comment fills in the whole chain
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, top, postURI, postURI, now)))
+	for rkey, want := range map[string]int64{top: 1, reply: 2, nested: 3} {
+		depth, exceeded := depthOf(rkey)
+		require.True(t, depth.Valid, "depth of %s should be known", rkey)
+		assert.Equal(t, want, depth.Int64)
+		assert.Equal(t, want > 2, exceeded, "only comments past the max depth are marked")
+	}
+
+	// A reply to an indexed comment gets its depth straight away
+	later := generateTID()
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, later, postURI, commentURI(reply), now)))
+	depth, exceeded := depthOf(later)
+	assert.Equal(t, int64(3), depth.Int64)
+	assert.True(t, exceeded)
+
+	// Replies past the max depth come back flagged so threads can collapse them
+	replies, err := commentRepo.ListByParentsBatchWithDeleted(ctx, []string{commentURI(reply)}, "new", 10, "")
+	require.NoError(t, err)
+	require.Len(t, replies[commentURI(reply)], 2)
+	for _, c := range replies[commentURI(reply)] {
+		assert.True(t, c.DepthExceeded)
+	}
+}
+
+// TestCommentConsumer_LockedThread checks that firehose comments on a locked post are indexed
+// as rejected: hidden, and not counted toward the thread
+func TestCommentConsumer_LockedThread(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+	moderationService := moderation.NewModerationService(postgres.NewModerationRepository(db))
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+
+	suffix := uniqueTestID()
+	user := createTestUser(t, db, "locked"+suffix+".test", "did:plc:locked"+suffix)
+	communityDID, err := createFeedTestCommunity(db, ctx, "locked"+suffix, "owner"+suffix+".test")
+	require.NoError(t, err)
+	postURI := createTestPost(t, db, communityDID, user.DID, "Locked thread", 0, time.Now().Add(-time.Hour))
+
+	commentURI := func(rkey string) string {
+		return fmt.Sprintf("at://%s/social.coves.community.comment/%s", user.DID, rkey)
+	}
+	commentCount := func() int {
+		t.Helper()
+		var count int
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT comment_count FROM posts WHERE uri = $1`, postURI).Scan(&count))
+		return count
+	}
+
+	before := generateTID()
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, before, postURI, postURI, time.Now())))
+	require.Equal(t, 1, commentCount())
+
+	require.NoError(t, moderationService.SetThreadLock(ctx, moderation.SetThreadLockRequest{
+		Subject: postURI, Locked: true, ActorDID: "did:plc:admin",
+	}))
+	post, err := postRepo.GetByURI(ctx, postURI)
+	require.NoError(t, err)
+	assert.True(t, post.Locked)
+
+	after := generateTID()
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, after, postURI, commentURI(before), time.Now())))
+
+	var status, visibility string
+	require.NoError(t, db.QueryRowContext(ctx,
+		`SELECT status, visibility_state FROM comments WHERE uri = $1`, commentURI(after),
+	).Scan(&status, &visibility))
+	assert.Equal(t, comments.StatusRejected, status)
+	assert.Equal(t, "removed", visibility)
+	assert.Equal(t, 1, commentCount(), "rejected comments don't count toward the thread")
+
+	replies, err := commentRepo.ListByParentsBatchWithDeleted(ctx, []string{commentURI(before)}, "new", 10, user.DID)
+	require.NoError(t, err)
+	assert.Empty(t, replies[commentURI(before)], "rejected comments are hidden, even from their author")
+
+	// Deleting a rejected comment leaves the counts alone
+	deleteEvent := commentEvent(user.DID, after, postURI, commentURI(before), time.Now())
+	deleteEvent.Commit.Operation = "delete"
+	deleteEvent.Commit.Record = nil
+	require.NoError(t, consumer.HandleEvent(ctx, deleteEvent))
+	assert.Equal(t, 1, commentCount())
+
+	// Existing comments are untouched and new ones are accepted again once unlocked
+	require.NoError(t, moderationService.SetThreadLock(ctx, moderation.SetThreadLockRequest{
+		Subject: postURI, Locked: false, ActorDID: "did:plc:admin",
+	}))
+	reopened := generateTID()
+	require.NoError(t, consumer.HandleEvent(ctx, commentEvent(user.DID, reopened, postURI, postURI, time.Now())))
+	require.NoError(t, db.QueryRowContext(ctx,
+		`SELECT status FROM comments WHERE uri = $1`, commentURI(reopened),
+	).Scan(&status))
+	assert.Equal(t, comments.StatusActive, status)
+	assert.Equal(t, 2, commentCount())
+}
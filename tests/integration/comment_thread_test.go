@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommentThread_DeepAncestorChain walks a 60-level reply chain with the recursive CTE
+// and checks ordering, height limits, and that removed ancestors don't break the chain
+func TestCommentThread_DeepAncestorChain(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+
+	testID := uniqueTestID()
+	testUser := createTestUser(t, db, "thread"+testID+".test", "did:plc:thread"+testID)
+	testCommunity, err := createFeedTestCommunity(db, ctx, "threadcomm"+testID, "ownerthread"+testID+".test")
+	require.NoError(t, err)
+
+	postURI := createTestPost(t, db, testCommunity, testUser.DID, "Deep Thread Test", 0, time.Now())
+
+	const chainLength = 60
+	chain := make([]string, chainLength)
+	parent := postURI
+	for i := range chain {
+		chain[i] = createTestCommentWithScore(t, db, testUser.DID, postURI, parent, fmt.Sprintf("Level %d", i), 0, 0, time.Now())
+		parent = chain[i]
+	}
+	leaf := chain[chainLength-1]
+
+	// Remove one ancestor the way a moderator would
+	removed := chain[20]
+	require.NoError(t, commentRepo.SoftDeleteWithReason(ctx, removed, comments.DeletionReasonModerator, "did:plc:moderator"))
+
+	t.Run("full chain is root-first and includes removed comments", func(t *testing.T) {
+		ancestors, err := commentRepo.GetAncestorsWithDeleted(ctx, leaf, 100)
+		require.NoError(t, err)
+		require.Len(t, ancestors, chainLength-1)
+
+		for i, ancestor := range ancestors {
+			assert.Equal(t, chain[i], ancestor.URI, "ancestor %d out of order", i)
+		}
+		assert.NotNil(t, ancestors[20].DeletedAt)
+	})
+
+	t.Run("height limits the walk to the nearest ancestors", func(t *testing.T) {
+		ancestors, err := commentRepo.GetAncestorsWithDeleted(ctx, leaf, 50)
+		require.NoError(t, err)
+		require.Len(t, ancestors, 50)
+		assert.Equal(t, chain[chainLength-51], ancestors[0].URI)
+		assert.Equal(t, chain[chainLength-2], ancestors[49].URI)
+	})
+
+	t.Run("top-level comment has no ancestors", func(t *testing.T) {
+		ancestors, err := commentRepo.GetAncestorsWithDeleted(ctx, chain[0], 10)
+		require.NoError(t, err)
+		assert.Empty(t, ancestors)
+	})
+
+	t.Run("unknown comment has no ancestors", func(t *testing.T) {
+		ancestors, err := commentRepo.GetAncestorsWithDeleted(ctx, "at://did:plc:nobody/social.coves.community.comment/missing", 10)
+		require.NoError(t, err)
+		assert.Empty(t, ancestors)
+	})
+
+	t.Run("service renders the removed ancestor as a stub", func(t *testing.T) {
+		service := setupCommentService(db)
+		resp, err := service.GetCommentThread(ctx, &comments.GetCommentThreadRequest{
+			CommentURI:   chain[30],
+			ParentHeight: 100,
+		})
+		require.NoError(t, err)
+
+		require.Len(t, resp.Thread.Ancestors, 30)
+		assert.False(t, resp.Thread.HasMoreAncestors)
+		assert.Equal(t, chain[30], resp.Thread.Focus.URI)
+
+		stub := resp.Thread.Ancestors[20]
+		assert.Equal(t, removed, stub.URI)
+		assert.True(t, stub.IsDeleted)
+		assert.Equal(t, comments.DeletionReasonModerator, *stub.DeletionReason)
+		assert.Nil(t, stub.Record)
+
+		require.Len(t, resp.Thread.Replies, 1)
+		assert.Equal(t, chain[31], resp.Thread.Replies[0].Comment.URI)
+	})
+}
@@ -21,7 +21,7 @@ func TestCommentVote_CreateAndUpdate(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	voteRepo := postgres.NewVoteRepository(db)
 	userRepo := postgres.NewUserRepository(db)
 	userService := users.NewUserService(userRepo, nil, "http://localhost:3001")
@@ -329,11 +329,11 @@ func TestCommentVote_ViewerState(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	voteRepo := postgres.NewVoteRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	userService := users.NewUserService(userRepo, nil, "http://localhost:3001")
 
 	voteConsumer := jetstream.NewVoteEventConsumer(voteRepo, userService, db)
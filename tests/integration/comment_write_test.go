@@ -70,8 +70,8 @@ func TestCommentWrite_CreateTopLevelComment(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup repositories
-	commentRepo := postgres.NewCommentRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	// Setup service with password-based PDS client factory for E2E testing
 	// CommentPDSClientFactory creates a PDS client for comment operations
@@ -291,8 +291,8 @@ func TestCommentWrite_CreateNestedReply(t *testing.T) {
 	pdsURL := getTestPDSURL()
 
 	// Setup repositories and service
-	commentRepo := postgres.NewCommentRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	// CommentPDSClientFactory creates a PDS client for comment operations
 	commentPDSFactory := func(ctx context.Context, session *oauthlib.ClientSessionData) (pds.Client, error) {
@@ -441,7 +441,7 @@ func TestCommentWrite_UpdateComment(t *testing.T) {
 	pdsURL := getTestPDSURL()
 
 	// Setup repositories and service
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 
 	// CommentPDSClientFactory creates a PDS client for comment operations
 	commentPDSFactory := func(ctx context.Context, session *oauthlib.ClientSessionData) (pds.Client, error) {
@@ -561,7 +561,7 @@ func TestCommentWrite_DeleteComment(t *testing.T) {
 	pdsURL := getTestPDSURL()
 
 	// Setup repositories and service
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 
 	// CommentPDSClientFactory creates a PDS client for comment operations
 	commentPDSFactory := func(ctx context.Context, session *oauthlib.ClientSessionData) (pds.Client, error) {
@@ -831,7 +831,7 @@ func TestCommentWrite_ConcurrentModificationDetection(t *testing.T) {
 	pdsURL := getTestPDSURL()
 
 	// Setup repositories and service
-	commentRepo := postgres.NewCommentRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 
 	commentPDSFactory := func(ctx context.Context, session *oauthlib.ClientSessionData) (pds.Client, error) {
 		if session.AccessToken == "" {
@@ -0,0 +1,109 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommunityActivity_CountsUserOncePerDay tests that a user who posts, comments and votes
+// in a community on the same day is one active user, and that the windows are by UTC day
+func TestCommunityActivity_CountsUserOncePerDay(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	communityDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("actives-%d", testID), fmt.Sprintf("activesowner-%d.test", testID))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM community_activity WHERE community_did = $1`, communityDID)
+	})
+
+	repo := postgres.NewCommunityActivityRepository(db)
+	activeDID := fmt.Sprintf("did:plc:active-%d", testID)
+	lurkerDID := fmt.Sprintf("did:plc:monthly-%d", testID)
+	createTestUser(t, db, fmt.Sprintf("active-%d.test", testID), activeDID)
+
+	now := time.Now().UTC()
+	postURI := createTestPost(t, db, communityDID, activeDID, "Active user post", 0, now)
+	commentURI := createTestCommentWithScore(t, db, activeDID, postURI, postURI, "Active user comment", 0, 0, now)
+
+	// Post, comment and vote on the same day: one row
+	require.NoError(t, repo.RecordActivity(ctx, communityDID, activeDID, now))
+	require.NoError(t, repo.RecordSubjectActivity(ctx, commentURI, activeDID, now))
+
+	voteConsumer := jetstream.NewVoteEventConsumer(postgres.NewVoteRepository(db),
+		users.NewUserService(postgres.NewUserRepository(db), nil, getTestPDSURL()), db)
+	voteConsumer.SetActivityRecorder(repo)
+	require.NoError(t, voteConsumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+		Did:  activeDID,
+		Kind: "commit",
+		Commit: &jetstream.CommitEvent{
+			Rev:        "test-rev",
+			Operation:  "create",
+			Collection: "social.coves.feed.vote",
+			RKey:       generateTID(),
+			CID:        "bafyactivevote",
+			Record: map[string]interface{}{
+				"$type":     "social.coves.feed.vote",
+				"subject":   map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+				"direction": "up",
+				"createdAt": now.Format(time.RFC3339),
+			},
+		},
+	}))
+
+	// Another day in the same week adds a row but not another weekly active
+	require.NoError(t, repo.RecordActivity(ctx, communityDID, activeDID, now.AddDate(0, 0, -3)))
+	// Active within the month but not the week
+	require.NoError(t, repo.RecordActivity(ctx, communityDID, lurkerDID, now.AddDate(0, 0, -20)))
+	// Outside both windows
+	require.NoError(t, repo.RecordActivity(ctx, communityDID, fmt.Sprintf("did:plc:gone-%d", testID), now.AddDate(0, 0, -45)))
+	// Unknown subjects are ignored
+	require.NoError(t, repo.RecordSubjectActivity(ctx, "at://did:plc:nobody/social.coves.community.post/none", activeDID, now))
+
+	var rows int
+	require.NoError(t, db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM community_activity WHERE community_did = $1 AND user_did = $2 AND day = $3::date`,
+		communityDID, activeDID, now.Format("2006-01-02")).Scan(&rows))
+	assert.Equal(t, 1, rows, "posting, commenting and voting on one day is one activity row")
+
+	_, err = repo.RollupActiveUsers(ctx, now)
+	require.NoError(t, err)
+
+	community, err := postgres.NewCommunityRepository(db, newTestCursorSigner()).GetByDID(ctx, communityDID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, community.WeeklyActiveUsers)
+	assert.Equal(t, 2, community.MonthlyActiveUsers)
+
+	// Retention keeps rows well past the monthly window
+	_, err = repo.PruneActivity(ctx, now.Add(-communities.ActivityRetention))
+	require.NoError(t, err)
+	require.NoError(t, db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM community_activity WHERE community_did = $1`, communityDID).Scan(&rows))
+	assert.Equal(t, 4, rows)
+
+	// A later rollup forgets pruned users
+	_, err = repo.PruneActivity(ctx, now.AddDate(0, 0, -10))
+	require.NoError(t, err)
+
+	_, err = repo.RollupActiveUsers(ctx, now)
+	require.NoError(t, err)
+	community, err = postgres.NewCommunityRepository(db, newTestCursorSigner()).GetByDID(ctx, communityDID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, community.WeeklyActiveUsers)
+	assert.Equal(t, 1, community.MonthlyActiveUsers)
+}
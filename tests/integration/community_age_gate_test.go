@@ -0,0 +1,148 @@
+package integration
+
+import (
+	"Coves/internal/api/handlers/community"
+	"Coves/internal/api/handlers/communityFeed"
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/db/postgres"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommunityAgeGate verifies NSFW communities withhold their posts from anonymous viewers and
+// from signed-in viewers until they confirm their age, while still describing the community
+func TestCommunityAgeGate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	communityService := communities.NewCommunityService(communityRepo, getTestPDSURL(), getTestInstanceDID(), "coves.social", nil, nil, nil)
+	feedService := communityFeeds.NewCommunityFeedService(postgres.NewCommunityFeedRepository(db, newTestCursorSigner()), communityService)
+	gate := communities.NewAgeGate(communityService, postgres.NewAgeConfirmationRepository(db))
+
+	getHandler := community.NewGetHandler(communityService)
+	getHandler.SetAgeGate(gate)
+	feedHandler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
+	feedHandler.SetAgeGate(gate)
+	confirmHandler := community.NewConfirmAgeHandler(gate)
+
+	testID := uniqueTestID()
+	viewer := createTestUser(t, db, "agegate"+testID+".test", "did:plc:agegate"+testID)
+	nsfwDID, err := createFeedTestCommunity(db, ctx, "nsfw"+testID, "ownernsfw"+testID+".test")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE communities SET content_warnings = ARRAY['nsfw'] WHERE did = $1`, nsfwDID)
+	require.NoError(t, err)
+	sfwDID, err := createFeedTestCommunity(db, ctx, "sfw"+testID, "ownersfw"+testID+".test")
+	require.NoError(t, err)
+	createTestPost(t, db, nsfwDID, viewer.DID, "Adult post", 1, time.Now())
+	createTestPost(t, db, sfwDID, viewer.DID, "Family post", 1, time.Now())
+
+	withViewer := func(req *http.Request, viewerDID string) *http.Request {
+		if viewerDID == "" {
+			return req
+		}
+		return req.WithContext(middleware.SetTestUserDID(req.Context(), viewerDID))
+	}
+
+	getCommunity := func(t *testing.T, communityDID, viewerDID string) communities.CommunityViewDetailed {
+		t.Helper()
+		req := withViewer(httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.community.get?community="+communityDID, nil), viewerDID)
+		w := httptest.NewRecorder()
+		getHandler.HandleGet(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var view communities.CommunityViewDetailed
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &view))
+		return view
+	}
+
+	type feedBody struct {
+		Community *communities.CommunityViewDetailed `json:"community"`
+		Feed      []json.RawMessage                  `json:"feed"`
+		Gated     bool                               `json:"gated"`
+	}
+	getFeed := func(t *testing.T, communityDID, viewerDID string) feedBody {
+		t.Helper()
+		req := withViewer(httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.communityFeed.getCommunity?sort=new&community="+communityDID, nil), viewerDID)
+		w := httptest.NewRecorder()
+		feedHandler.HandleGetCommunity(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var body feedBody
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return body
+	}
+
+	confirm := func(t *testing.T, communityDID, viewerDID string) int {
+		t.Helper()
+		req := withViewer(httptest.NewRequest(http.MethodPost, "/xrpc/social.coves.community.confirmAge",
+			strings.NewReader(`{"community":"`+communityDID+`"}`)), viewerDID)
+		w := httptest.NewRecorder()
+		confirmHandler.HandleConfirmAge(w, req)
+		return w.Code
+	}
+
+	t.Run("anonymous viewers are always gated", func(t *testing.T) {
+		view := getCommunity(t, nsfwDID, "")
+		assert.True(t, view.AgeConfirmationRequired)
+		assert.True(t, view.Gated)
+
+		feed := getFeed(t, nsfwDID, "")
+		assert.True(t, feed.Gated)
+		assert.Empty(t, feed.Feed)
+		require.NotNil(t, feed.Community)
+		assert.Equal(t, nsfwDID, feed.Community.DID)
+
+		assert.Equal(t, http.StatusUnauthorized, confirm(t, nsfwDID, ""))
+	})
+
+	t.Run("unconfirmed viewers are gated", func(t *testing.T) {
+		assert.True(t, getCommunity(t, nsfwDID, viewer.DID).Gated)
+		feed := getFeed(t, nsfwDID, viewer.DID)
+		assert.True(t, feed.Gated)
+		assert.Empty(t, feed.Feed)
+	})
+
+	t.Run("confirmed viewers see posts", func(t *testing.T) {
+		require.Equal(t, http.StatusOK, confirm(t, nsfwDID, viewer.DID))
+		// Confirming again is harmless
+		require.Equal(t, http.StatusOK, confirm(t, nsfwDID, viewer.DID))
+
+		view := getCommunity(t, nsfwDID, viewer.DID)
+		assert.True(t, view.AgeConfirmationRequired)
+		assert.False(t, view.Gated)
+
+		feed := getFeed(t, nsfwDID, viewer.DID)
+		assert.False(t, feed.Gated)
+		assert.Nil(t, feed.Community)
+		assert.Len(t, feed.Feed, 1)
+
+		// The confirmation is per community and per viewer
+		assert.True(t, getFeed(t, nsfwDID, "").Gated)
+	})
+
+	t.Run("communities without an nsfw warning are never gated", func(t *testing.T) {
+		view := getCommunity(t, sfwDID, "")
+		assert.False(t, view.AgeConfirmationRequired)
+		assert.False(t, view.Gated)
+		assert.Len(t, getFeed(t, sfwDID, "").Feed, 1)
+
+		require.Equal(t, http.StatusOK, confirm(t, sfwDID, viewer.DID))
+		var rows int
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM viewer_confirmations WHERE community_did = $1`, sfwDID).Scan(&rows))
+		assert.Zero(t, rows, "confirming a community that isn't age-restricted records nothing")
+	})
+}
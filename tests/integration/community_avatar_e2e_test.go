@@ -106,7 +106,7 @@ func TestCommunityAvatarE2E_CreateWithAvatar(t *testing.T) {
 	identityResolver := identity.NewResolver(db, identityConfig)
 
 	// Setup services
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	provisioner := communities.NewPDSAccountProvisioner("coves.social", pdsURL)
 	blobService := blobs.NewBlobService(pdsURL)
 
@@ -347,7 +347,7 @@ func TestCommunityAvatarE2E_UpdateWithAvatar(t *testing.T) {
 	identityResolver := identity.NewResolver(db, identityConfig)
 
 	// Setup services
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	provisioner := communities.NewPDSAccountProvisioner("coves.social", pdsURL)
 	blobService := blobs.NewBlobService(pdsURL)
 
@@ -716,7 +716,7 @@ func TestCommunityAvatarE2E_UpdateWithBanner(t *testing.T) {
 	identityResolver := identity.NewResolver(db, identityConfig)
 
 	// Setup services
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	provisioner := communities.NewPDSAccountProvisioner("coves.social", pdsURL)
 	blobService := blobs.NewBlobService(pdsURL)
 
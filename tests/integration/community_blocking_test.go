@@ -429,7 +429,7 @@ func TestCommunityBlocking_GetBlock(t *testing.T) {
 // Helper functions for blocking tests
 
 func createBlockingTestCommunityRepo(t *testing.T, db *sql.DB) communities.Repository {
-	return postgresRepo.NewCommunityRepository(db)
+	return postgresRepo.NewCommunityRepository(db, newTestCursorSigner())
 }
 
 func createBlockingTestCommunity(t *testing.T, repo communities.Repository, name, did string) *communities.Community {
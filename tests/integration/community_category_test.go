@@ -0,0 +1,144 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommunityCategories(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	categoryRepo := postgres.NewCommunityCategoryRepository(db)
+	base := time.Now()
+
+	before, err := categoryRepo.CountCommunities(ctx)
+	require.NoError(t, err)
+
+	// index creates a community from a profile record listing categories
+	index := func(t *testing.T, prefix string, categories []interface{}) string {
+		t.Helper()
+		suffix := uniqueTestID()
+		did := generateTestDID(suffix)
+		name := fmt.Sprintf("%s-%s", prefix, suffix)
+		resolver := newMockIdentityResolver()
+		resolver.resolutions[did] = fmt.Sprintf("c-%s.coves.local", name)
+		consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.local", true, resolver)
+
+		record := map[string]interface{}{
+			"name":        name,
+			"displayName": "Category " + prefix,
+			"owner":       "did:web:coves.local",
+			"createdBy":   "did:plc:user123",
+			"hostedBy":    "did:web:coves.local",
+			"visibility":  "public",
+			"federation":  map[string]interface{}{"allowExternalDiscovery": true},
+			"createdAt":   base.Format(time.RFC3339),
+		}
+		if categories != nil {
+			record["categories"] = categories
+		}
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "create", revAt(base, 0), "bafycat", record)))
+		return did
+	}
+
+	gamingDID := index(t, "cat-gaming", []interface{}{"gaming", "technology"})
+	knittingDID := index(t, "cat-knitting", []interface{}{"knitting", "crochet"})
+	mixedDID := index(t, "cat-mixed", []interface{}{"music", "knitting"})
+	index(t, "cat-none", nil)
+
+	t.Run("unknown categories are stored and bucketed as other", func(t *testing.T) {
+		community, err := repo.GetByDID(ctx, knittingDID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"knitting", "crochet"}, community.Categories)
+		assert.Equal(t, []string{communities.CategoryOther}, community.ToCommunityView().Categories)
+
+		community, err = repo.GetByDID(ctx, mixedDID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"music", communities.CategoryOther}, community.ToCommunityViewDetailed().Categories)
+	})
+
+	t.Run("counts include each community once per bucket", func(t *testing.T) {
+		after, err := categoryRepo.CountCommunities(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, after[communities.CategoryGaming]-before[communities.CategoryGaming])
+		assert.Equal(t, 1, after[communities.CategoryTechnology]-before[communities.CategoryTechnology])
+		assert.Equal(t, 1, after[communities.CategoryMusic]-before[communities.CategoryMusic])
+		assert.Equal(t, 2, after[communities.CategoryOther]-before[communities.CategoryOther], "two unknown categories still count once")
+	})
+
+	t.Run("counts match the filtered list", func(t *testing.T) {
+		counts, err := categoryRepo.CountCommunities(ctx)
+		require.NoError(t, err)
+		for _, category := range append([]string{communities.CategoryOther}, communities.Categories...) {
+			listed, _, err := repo.List(ctx, communities.ListCommunitiesRequest{Sort: "popular", Category: category, Limit: 100000})
+			require.NoError(t, err)
+			assert.Equal(t, counts[category], len(listed), "count for %s", category)
+		}
+	})
+
+	t.Run("list and search filter by category", func(t *testing.T) {
+		listedDIDs := func(req communities.ListCommunitiesRequest) []string {
+			req.Limit = 100000
+			listed, _, err := repo.List(ctx, req)
+			require.NoError(t, err)
+			dids := make([]string, len(listed))
+			for i, c := range listed {
+				dids[i] = c.DID
+			}
+			return dids
+		}
+
+		gaming := listedDIDs(communities.ListCommunitiesRequest{Sort: "active", Category: communities.CategoryGaming})
+		assert.Contains(t, gaming, gamingDID)
+		assert.NotContains(t, gaming, knittingDID)
+
+		other := listedDIDs(communities.ListCommunitiesRequest{Sort: "new", Category: communities.CategoryOther})
+		assert.Contains(t, other, knittingDID)
+		assert.Contains(t, other, mixedDID)
+		assert.NotContains(t, other, gamingDID)
+
+		found, _, err := repo.Search(ctx, communities.SearchCommunitiesRequest{Query: "cat-", Category: communities.CategoryMusic, Limit: 100})
+		require.NoError(t, err)
+		for _, c := range found {
+			assert.Contains(t, c.Categories, communities.CategoryMusic)
+		}
+	})
+
+	t.Run("profile update replaces categories", func(t *testing.T) {
+		community, err := repo.GetByDID(ctx, gamingDID)
+		require.NoError(t, err)
+		community.Categories = []string{communities.CategoryScience}
+		_, err = repo.Update(ctx, community)
+		require.NoError(t, err)
+
+		community, err = repo.GetByDID(ctx, gamingDID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{communities.CategoryScience}, community.Categories)
+
+		community.Categories = nil
+		_, err = repo.Update(ctx, community)
+		require.NoError(t, err)
+		community, err = repo.GetByDID(ctx, gamingDID)
+		require.NoError(t, err)
+		assert.Empty(t, community.Categories)
+	})
+}
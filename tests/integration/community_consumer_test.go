@@ -20,7 +20,7 @@ func TestCommunityConsumer_HandleCommunityProfile(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("creates community from firehose event", func(t *testing.T) {
@@ -231,7 +231,7 @@ func TestCommunityConsumer_HandleSubscription(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("creates subscription from event", func(t *testing.T) {
@@ -321,7 +321,7 @@ func TestCommunityConsumer_IgnoresNonCommunityEvents(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	// Use mock resolver (though these tests don't create communities, so it won't be called)
 	mockResolver := newMockIdentityResolver()
 	consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.local", true, mockResolver)
@@ -411,7 +411,7 @@ func TestCommunityConsumer_PLCHandleResolution(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("resolves handle from PLC successfully", func(t *testing.T) {
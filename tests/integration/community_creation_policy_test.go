@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/db/postgres"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestCreationPolicyRepo_ConcurrentClaims checks that concurrent creation claims from one
+// creator can't pass the rate limit together
+func TestCreationPolicyRepo_ConcurrentClaims(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewCommunityCreationPolicyRepository(db)
+	creator := "did:plc:creator" + uniqueTestID()
+	since := time.Now().Add(-time.Hour)
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM community_creations WHERE creator_did = $1`, creator)
+	})
+
+	const attempts, limit = 10, 2
+	var wg sync.WaitGroup
+	results := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.ClaimCreation(ctx, creator, "burst", since, limit)
+			results <- err
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	claimed := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			claimed++
+		case !errors.Is(err, communities.ErrTooManyCommunities):
+			t.Errorf("Unexpected claim error: %v", err)
+		}
+	}
+	if claimed != limit {
+		t.Errorf("Expected %d claims to pass the limit, got %d", limit, claimed)
+	}
+
+	t.Run("released claims free their slot", func(t *testing.T) {
+		var id int64
+		if err := db.QueryRow(`SELECT id FROM community_creations WHERE creator_did = $1 LIMIT 1`, creator).Scan(&id); err != nil {
+			t.Fatalf("Failed to find a claim: %v", err)
+		}
+		if err := repo.ReleaseCreation(ctx, id); err != nil {
+			t.Fatalf("ReleaseCreation failed: %v", err)
+		}
+		if _, err := repo.ClaimCreation(ctx, creator, "burst", since, limit); err != nil {
+			t.Errorf("Expected a claim after the release, got %v", err)
+		}
+	})
+
+	t.Run("completed claims aren't released", func(t *testing.T) {
+		var id int64
+		if err := db.QueryRow(`SELECT id FROM community_creations WHERE creator_did = $1 LIMIT 1`, creator).Scan(&id); err != nil {
+			t.Fatalf("Failed to find a claim: %v", err)
+		}
+		if err := repo.CompleteCreation(ctx, id, "did:plc:created"); err != nil {
+			t.Fatalf("CompleteCreation failed: %v", err)
+		}
+		if err := repo.ReleaseCreation(ctx, id); err != nil {
+			t.Fatalf("ReleaseCreation failed: %v", err)
+		}
+		if _, err := repo.ClaimCreation(ctx, creator, "burst", since, limit); !errors.Is(err, communities.ErrTooManyCommunities) {
+			t.Errorf("Expected the completed creation to keep counting, got %v", err)
+		}
+	})
+}
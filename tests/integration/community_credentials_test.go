@@ -18,7 +18,7 @@ func TestCommunityRepository_CredentialPersistence(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("persists PDS credentials on create", func(t *testing.T) {
@@ -129,7 +129,7 @@ func TestCommunityRepository_EncryptedCredentials(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("credentials are encrypted in database", func(t *testing.T) {
@@ -246,7 +246,7 @@ func TestCommunityRepository_V2OwnershipModel(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("V2 communities are self-owned", func(t *testing.T) {
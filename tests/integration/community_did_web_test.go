@@ -0,0 +1,199 @@
+package integration
+
+import (
+	"Coves/internal/api/routes"
+	"Coves/internal/atproto/identity"
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"Coves/internal/db/postgres"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommunityDIDWeb_E2E provisions an instance-native did:web community against a fake PDS,
+// checks the AppView serves its DID document, and indexes it through the community consumer
+// with the handle resolved locally rather than over HTTPS
+func TestCommunityDIDWeb_E2E(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	const (
+		instanceDomain = "coves.local"
+		didWebDomain   = "communities.coves.local"
+		signingKey     = "did:key:zQ3shXjHeiBuRCKmM36cuYnm7YEMzhGnCmCyW92sRJ9pribSF"
+	)
+
+	didWebRepo := postgres.NewDIDWebRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+
+	// AppView serving did:web documents
+	appViewRouter := chi.NewRouter()
+	routes.RegisterDIDWebRoutes(routes.NewRegistrar(appViewRouter, nil), didWebRepo)
+	appView := httptest.NewServer(appViewRouter)
+	defer appView.Close()
+
+	// Fake PDS: reserves a signing key, and on createAccount resolves the DID document from
+	// the AppView the way a real PDS would before accepting an existing did:web
+	var resolvedDoc map[string]interface{}
+	var reservations int
+	pds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&input)
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.server.reserveSigningKey":
+			reservations++
+			_ = json.NewEncoder(w).Encode(map[string]string{"signingKey": signingKey})
+
+		case "/xrpc/com.atproto.server.createAccount":
+			did, _ := input["did"].(string)
+			req, err := http.NewRequest(http.MethodGet, appView.URL+"/.well-known/did.json", nil)
+			if err != nil {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			req.Host = strings.TrimPrefix(did, "did:web:")
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil || resp.StatusCode != http.StatusOK {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(map[string]string{"error": "InvalidRequest", "message": "DID document not resolvable"})
+				return
+			}
+			defer func() { _ = resp.Body.Close() }()
+			_ = json.NewDecoder(resp.Body).Decode(&resolvedDoc)
+
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"did":        did,
+				"handle":     input["handle"].(string),
+				"accessJwt":  "access-token",
+				"refreshJwt": "refresh-token",
+			})
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer pds.Close()
+
+	name := "web" + uniqueTestID()
+	provisioner := communities.NewDIDWebProvisioner(instanceDomain, pds.URL, didWebDomain, didWebRepo)
+	account, err := provisioner.ProvisionCommunityAccount(ctx, name)
+	require.NoError(t, err)
+	defer func() { _ = didWebRepo.Delete(ctx, account.DID) }()
+
+	expectedDID := fmt.Sprintf("did:web:%s.%s", name, didWebDomain)
+	expectedHandle := fmt.Sprintf("c-%s.%s", name, instanceDomain)
+	assert.Equal(t, expectedDID, account.DID)
+	assert.Equal(t, expectedHandle, account.Handle)
+
+	// The PDS saw the published document during account creation
+	require.NotNil(t, resolvedDoc, "PDS should have resolved the DID document")
+	assert.Equal(t, expectedDID, resolvedDoc["id"])
+	assert.Equal(t, []interface{}{"at://" + expectedHandle}, resolvedDoc["alsoKnownAs"])
+
+	// Provisioning a taken name fails before touching the PDS and leaves the live document alone
+	published, err := didWebRepo.GetByDID(ctx, expectedDID)
+	require.NoError(t, err)
+	_, err = provisioner.ProvisionCommunityAccount(ctx, name)
+	require.ErrorIs(t, err, communities.ErrHandleTaken)
+	assert.Equal(t, 1, reservations, "a taken name must not reserve a new signing key")
+	current, err := didWebRepo.GetByDID(ctx, expectedDID)
+	require.NoError(t, err)
+	assert.Equal(t, published, current)
+
+	// The repository never replaces a published document either
+	err = didWebRepo.Create(ctx, &communities.DIDWebDocument{DID: expectedDID, Handle: "c-other.coves.local", SigningKey: "did:key:zOther", PDSURL: "https://evil.example"})
+	require.ErrorIs(t, err, communities.ErrHandleTaken)
+
+	// Index the community; the record has no handle, so the consumer resolves it locally
+	resolver := identity.NewResolver(db, identity.Config{
+		LocalDirectory:    communities.NewDIDWebDirectory(didWebRepo),
+		LocalDIDWebDomain: didWebDomain,
+	})
+	consumer := jetstream.NewCommunityEventConsumer(communityRepo, "did:web:"+instanceDomain, true, resolver)
+
+	event := &jetstream.JetstreamEvent{
+		Did:    account.DID,
+		TimeUS: time.Now().UnixMicro(),
+		Kind:   "commit",
+		Commit: &jetstream.CommitEvent{
+			Rev:        "rev-didweb",
+			Operation:  "create",
+			Collection: "social.coves.community.profile",
+			RKey:       "self",
+			CID:        "bafydidweb",
+			Record: map[string]interface{}{
+				"$type":      "social.coves.community.profile",
+				"name":       name,
+				"createdBy":  "did:plc:creator",
+				"hostedBy":   "did:web:" + instanceDomain,
+				"visibility": "public",
+				"federation": map[string]interface{}{
+					"allowExternalDiscovery": true,
+				},
+				"createdAt": time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+	require.NoError(t, consumer.HandleEvent(ctx, event))
+
+	community, err := communityRepo.GetByDID(ctx, account.DID)
+	require.NoError(t, err)
+	assert.Equal(t, expectedHandle, community.Handle)
+	assert.Equal(t, "did:web:"+instanceDomain, community.HostedByDID)
+}
+
+// recordingProvisioner fails the test if a community account is provisioned
+type recordingProvisioner struct {
+	t *testing.T
+}
+
+func (p recordingProvisioner) ProvisionCommunityAccount(ctx context.Context, name string) (*communities.CommunityPDSAccount, error) {
+	p.t.Errorf("Expected no account to be provisioned for %s", name)
+	return nil, fmt.Errorf("unexpected provisioning")
+}
+
+// TestCreateCommunity_TakenNameIsNotProvisioned checks a name that already has a community is
+// rejected before the provisioner (and, for did:web, the community's DID document) is touched
+func TestCreateCommunity_TakenNameIsNotProvisioned(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	const instanceDomain = "coves.local"
+	name := "taken" + uniqueTestID()
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO communities (did, name, owner_did, created_by_did, hosted_by_did, handle, pds_url, created_at)
+		VALUES ($1, $2, $1, 'did:plc:owner', $3, $4, 'http://localhost:3001', NOW())
+	`, "did:web:"+name+".communities."+instanceDomain, name, "did:web:"+instanceDomain, "c-"+name+"."+instanceDomain)
+	require.NoError(t, err)
+
+	service := communities.NewCommunityService(postgres.NewCommunityRepository(db, newTestCursorSigner()),
+		"http://localhost:3001", "did:web:"+instanceDomain, instanceDomain, recordingProvisioner{t: t}, nil, nil)
+
+	_, err = service.CreateCommunity(ctx, communities.CreateCommunityRequest{
+		Name:         name,
+		CreatedByDID: "did:plc:squatter",
+	})
+	require.ErrorIs(t, err, communities.ErrHandleTaken)
+}
@@ -8,10 +8,11 @@ import (
 	"Coves/internal/core/communities"
 	"Coves/internal/core/users"
 	"Coves/internal/db/postgres"
-	"bytes"
+	"Coves/pkg/covesclient"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -101,7 +102,7 @@ func TestCommunity_E2E(t *testing.T) {
 	}()
 
 	// Setup dependencies
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	// Create a fresh test account on PDS (similar to user_journey_e2e_test pattern)
 	// Use unique handle to avoid conflicts between test runs
@@ -163,9 +164,10 @@ func TestCommunity_E2E(t *testing.T) {
 
 	// Setup HTTP server with XRPC routes
 	r := chi.NewRouter()
-	routes.RegisterCommunityRoutes(r, communityService, communityRepo, e2eAuth.OAuthAuthMiddleware, nil) // nil = allow all community creators
+	routes.RegisterCommunityRoutes(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), communityService, communityRepo, nil, nil) // nil = allow all community creators
 	httpServer := httptest.NewServer(r)
 	defer httpServer.Close()
+	client := newTestCovesClient(t, httpServer.URL)
 
 	ctx := context.Background()
 
@@ -374,59 +376,20 @@ func TestCommunity_E2E(t *testing.T) {
 			// NOTE: Both createdByDid and hostedByDid are derived server-side:
 			//   - createdByDid: from JWT token (authenticated user)
 			//   - hostedByDid: from instance configuration (security: prevents spoofing)
-			createReq := map[string]interface{}{
-				"name":                   fmt.Sprintf("xrpc-%d", time.Now().Unix()),
-				"displayName":            "XRPC E2E Test",
-				"description":            "Testing true end-to-end flow",
-				"visibility":             "public",
-				"allowExternalDiscovery": true,
+			createReq := covesclient.CreateCommunityRequest{
+				Name:                   fmt.Sprintf("xrpc-%d", time.Now().Unix()),
+				DisplayName:            "XRPC E2E Test",
+				Description:            "Testing true end-to-end flow",
+				Visibility:             "public",
+				AllowExternalDiscovery: true,
 			}
 
-			reqBody, marshalErr := json.Marshal(createReq)
-			if marshalErr != nil {
-				t.Fatalf("Failed to marshal request: %v", marshalErr)
-			}
-
-			// Step 1: Client POSTs to XRPC endpoint with JWT authentication
+			// Step 1: Client POSTs to XRPC endpoint with OAuth authentication
 			t.Logf("📡 Client → POST /xrpc/social.coves.community.create")
-			t.Logf("   Request: %s", string(reqBody))
-
-			req, err := http.NewRequest(http.MethodPost,
-				httpServer.URL+"/xrpc/social.coves.community.create",
-				bytes.NewBuffer(reqBody))
-			if err != nil {
-				t.Fatalf("Failed to create request: %v", err)
-			}
-			req.Header.Set("Content-Type", "application/json")
-			// Use OAuth token for Coves API authentication
-			req.Header.Set("Authorization", "Bearer "+token)
 
-			resp, err := http.DefaultClient.Do(req)
+			createResp, err := client.WithToken(token).CreateCommunity(ctx, createReq)
 			if err != nil {
-				t.Fatalf("Failed to POST: %v", err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, readErr := io.ReadAll(resp.Body)
-				if readErr != nil {
-					t.Fatalf("Expected 200, got %d (failed to read body: %v)", resp.StatusCode, readErr)
-				}
-				t.Logf("❌ XRPC Create Failed")
-				t.Logf("   Status: %d", resp.StatusCode)
-				t.Logf("   Response: %s", string(body))
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var createResp struct {
-				URI    string `json:"uri"`
-				CID    string `json:"cid"`
-				DID    string `json:"did"`
-				Handle string `json:"handle"`
-			}
-
-			if err := json.NewDecoder(resp.Body).Decode(&createResp); err != nil {
-				t.Fatalf("Failed to decode create response: %v", err)
+				t.Fatalf("XRPC create failed: %v", err)
 			}
 
 			t.Logf("✅ XRPC response received:")
@@ -452,16 +415,16 @@ func TestCommunity_E2E(t *testing.T) {
 					Record: map[string]interface{}{
 						// Note: No 'did' or 'handle' in record (atProto best practice)
 						// These are mutable and resolved from DIDs, not stored in immutable records
-						"name":        createReq["name"],
-						"displayName": createReq["displayName"],
-						"description": createReq["description"],
-						"visibility":  createReq["visibility"],
+						"name":        createReq.Name,
+						"displayName": createReq.DisplayName,
+						"description": createReq.Description,
+						"visibility":  createReq.Visibility,
 						// Server-side derives these from JWT auth (instanceDID is the authenticated user)
 						"owner":     instanceDID,
 						"createdBy": instanceDID,
 						"hostedBy":  instanceDID,
 						"federation": map[string]interface{}{
-							"allowExternalDiscovery": createReq["allowExternalDiscovery"],
+							"allowExternalDiscovery": createReq.AllowExternalDiscovery,
 						},
 						"createdAt": time.Now().Format(time.RFC3339),
 					},
@@ -499,25 +462,10 @@ func TestCommunity_E2E(t *testing.T) {
 			community := createAndIndexCommunity(t, communityService, consumer, instanceDID, pdsURL)
 
 			// GET via HTTP endpoint
-			resp, err := http.Get(fmt.Sprintf("%s/xrpc/social.coves.community.get?community=%s",
-				httpServer.URL, community.DID))
+			getCommunity, err := client.GetCommunity(ctx, community.DID)
 			if err != nil {
 				t.Fatalf("Failed to GET: %v", err)
 			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, readErr := io.ReadAll(resp.Body)
-				if readErr != nil {
-					t.Fatalf("Expected 200, got %d (failed to read body: %v)", resp.StatusCode, readErr)
-				}
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var getCommunity communities.Community
-			if err := json.NewDecoder(resp.Body).Decode(&getCommunity); err != nil {
-				t.Fatalf("Failed to decode get response: %v", err)
-			}
 
 			t.Logf("Retrieved via XRPC HTTP endpoint:")
 			t.Logf("   DID:         %s", getCommunity.DID)
@@ -534,29 +482,10 @@ func TestCommunity_E2E(t *testing.T) {
 				createAndIndexCommunity(t, communityService, consumer, instanceDID, pdsURL)
 			}
 
-			resp, err := http.Get(fmt.Sprintf("%s/xrpc/social.coves.community.list?limit=10",
-				httpServer.URL))
+			listResp, err := client.ListCommunities(ctx, covesclient.ListCommunitiesParams{Limit: 10})
 			if err != nil {
 				t.Fatalf("Failed to GET list: %v", err)
 			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, readErr := io.ReadAll(resp.Body)
-				if readErr != nil {
-					t.Fatalf("Expected 200, got %d (failed to read body: %v)", resp.StatusCode, readErr)
-				}
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var listResp struct {
-				Cursor      string                  `json:"cursor"`
-				Communities []communities.Community `json:"communities"`
-			}
-
-			if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
-				t.Fatalf("Failed to decode list response: %v", err)
-			}
 
 			t.Logf("✅ Listed %d communities via XRPC", len(listResp.Communities))
 
@@ -566,81 +495,35 @@ func TestCommunity_E2E(t *testing.T) {
 		})
 
 		t.Run("List with sort=popular (default)", func(t *testing.T) {
-			resp, err := http.Get(fmt.Sprintf("%s/xrpc/social.coves.community.list?sort=popular&limit=10",
-				httpServer.URL))
+			listResp, err := client.ListCommunities(ctx, covesclient.ListCommunitiesParams{Sort: "popular", Limit: 10})
 			if err != nil {
 				t.Fatalf("Failed to GET list with sort=popular: %v", err)
 			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var listResp struct {
-				Cursor      string                  `json:"cursor"`
-				Communities []communities.Community `json:"communities"`
-			}
-			if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
-				t.Fatalf("Failed to decode response: %v", err)
-			}
 
 			t.Logf("✅ Listed %d communities sorted by popular (subscriber_count DESC)", len(listResp.Communities))
 		})
 
 		t.Run("List with sort=active", func(t *testing.T) {
-			resp, err := http.Get(fmt.Sprintf("%s/xrpc/social.coves.community.list?sort=active&limit=10",
-				httpServer.URL))
-			if err != nil {
+			if _, err := client.ListCommunities(ctx, covesclient.ListCommunitiesParams{Sort: "active", Limit: 10}); err != nil {
 				t.Fatalf("Failed to GET list with sort=active: %v", err)
 			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
 
 			t.Logf("✅ Listed communities sorted by active (post_count DESC)")
 		})
 
 		t.Run("List with sort=new", func(t *testing.T) {
-			resp, err := http.Get(fmt.Sprintf("%s/xrpc/social.coves.community.list?sort=new&limit=10",
-				httpServer.URL))
-			if err != nil {
+			if _, err := client.ListCommunities(ctx, covesclient.ListCommunitiesParams{Sort: "new", Limit: 10}); err != nil {
 				t.Fatalf("Failed to GET list with sort=new: %v", err)
 			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
 
 			t.Logf("✅ Listed communities sorted by new (created_at DESC)")
 		})
 
 		t.Run("List with sort=alphabetical", func(t *testing.T) {
-			resp, err := http.Get(fmt.Sprintf("%s/xrpc/social.coves.community.list?sort=alphabetical&limit=10",
-				httpServer.URL))
+			listResp, err := client.ListCommunities(ctx, covesclient.ListCommunitiesParams{Sort: "alphabetical", Limit: 10})
 			if err != nil {
 				t.Fatalf("Failed to GET list with sort=alphabetical: %v", err)
 			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var listResp struct {
-				Cursor      string                  `json:"cursor"`
-				Communities []communities.Community `json:"communities"`
-			}
-			if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
-				t.Fatalf("Failed to decode response: %v", err)
-			}
 
 			// Verify alphabetical ordering
 			if len(listResp.Communities) > 1 {
@@ -656,41 +539,19 @@ func TestCommunity_E2E(t *testing.T) {
 		})
 
 		t.Run("List with invalid sort value", func(t *testing.T) {
-			resp, err := http.Get(fmt.Sprintf("%s/xrpc/social.coves.community.list?sort=invalid&limit=10",
-				httpServer.URL))
-			if err != nil {
-				t.Fatalf("Failed to GET list with invalid sort: %v", err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusBadRequest {
-				body, _ := io.ReadAll(resp.Body)
-				t.Fatalf("Expected 400 for invalid sort, got %d: %s", resp.StatusCode, string(body))
+			_, err := client.ListCommunities(ctx, covesclient.ListCommunitiesParams{Sort: "invalid", Limit: 10})
+			if !errors.Is(err, covesclient.ErrInvalidRequest) {
+				t.Fatalf("Expected InvalidRequest for invalid sort, got %v", err)
 			}
 
 			t.Logf("✅ Rejected invalid sort value with 400")
 		})
 
 		t.Run("List with visibility filter", func(t *testing.T) {
-			resp, err := http.Get(fmt.Sprintf("%s/xrpc/social.coves.community.list?visibility=public&limit=10",
-				httpServer.URL))
+			listResp, err := client.ListCommunities(ctx, covesclient.ListCommunitiesParams{Visibility: "public", Limit: 10})
 			if err != nil {
 				t.Fatalf("Failed to GET list with visibility filter: %v", err)
 			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var listResp struct {
-				Cursor      string                  `json:"cursor"`
-				Communities []communities.Community `json:"communities"`
-			}
-			if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
-				t.Fatalf("Failed to decode response: %v", err)
-			}
 
 			// Verify all communities have public visibility
 			for _, comm := range listResp.Communities {
@@ -705,41 +566,18 @@ func TestCommunity_E2E(t *testing.T) {
 
 		t.Run("List with default sort (no parameter)", func(t *testing.T) {
 			// Should default to sort=popular
-			resp, err := http.Get(fmt.Sprintf("%s/xrpc/social.coves.community.list?limit=10",
-				httpServer.URL))
-			if err != nil {
+			if _, err := client.ListCommunities(ctx, covesclient.ListCommunitiesParams{Limit: 10}); err != nil {
 				t.Fatalf("Failed to GET list with default sort: %v", err)
 			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
 
 			t.Logf("✅ List defaults to popular sort when no sort parameter provided")
 		})
 
 		t.Run("List with limit bounds validation", func(t *testing.T) {
 			// Test limit > 100 (should clamp to 100)
-			resp, err := http.Get(fmt.Sprintf("%s/xrpc/social.coves.community.list?limit=500",
-				httpServer.URL))
+			listResp, err := client.ListCommunities(ctx, covesclient.ListCommunitiesParams{Limit: 500})
 			if err != nil {
-				t.Fatalf("Failed to GET list with limit=500: %v", err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, _ := io.ReadAll(resp.Body)
-				t.Fatalf("Expected 200 (clamped limit), got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var listResp struct {
-				Cursor      string                  `json:"cursor"`
-				Communities []communities.Community `json:"communities"`
-			}
-			if err := json.NewDecoder(resp.Body).Decode(&listResp); err != nil {
-				t.Fatalf("Failed to decode response: %v", err)
+				t.Fatalf("Expected clamped limit for limit=500, got %v", err)
 			}
 
 			if len(listResp.Communities) > 100 {
@@ -763,55 +601,13 @@ func TestCommunity_E2E(t *testing.T) {
 
 			// Subscribe to the community with contentVisibility=5 (test max visibility)
 			// NOTE: HTTP API uses "community" field, but atProto record uses "subject" internally
-			subscribeReq := map[string]interface{}{
-				"community":         community.DID,
-				"contentVisibility": 5, // Test with max visibility
-			}
-
-			reqBody, marshalErr := json.Marshal(subscribeReq)
-			if marshalErr != nil {
-				t.Fatalf("Failed to marshal subscribe request: %v", marshalErr)
-			}
-
-			// POST subscribe request
+			// POST subscribe request with max visibility
 			t.Logf("📡 Client → POST /xrpc/social.coves.community.subscribe")
 			t.Logf("   Subscribing to community: %s", community.DID)
 
-			req, err := http.NewRequest(http.MethodPost,
-				httpServer.URL+"/xrpc/social.coves.community.subscribe",
-				bytes.NewBuffer(reqBody))
+			subscribeResp, err := client.WithToken(token).Subscribe(ctx, community.DID, 5)
 			if err != nil {
-				t.Fatalf("Failed to create request: %v", err)
-			}
-			req.Header.Set("Content-Type", "application/json")
-			// Use OAuth token for Coves API authentication
-			req.Header.Set("Authorization", "Bearer "+token)
-
-			resp, err := http.DefaultClient.Do(req)
-			if err != nil {
-				t.Fatalf("Failed to POST subscribe: %v", err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, readErr := io.ReadAll(resp.Body)
-				if readErr != nil {
-					t.Fatalf("Expected 200, got %d (failed to read body: %v)", resp.StatusCode, readErr)
-				}
-				t.Logf("❌ XRPC Subscribe Failed")
-				t.Logf("   Status: %d", resp.StatusCode)
-				t.Logf("   Response: %s", string(body))
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var subscribeResp struct {
-				URI      string `json:"uri"`
-				CID      string `json:"cid"`
-				Existing bool   `json:"existing"`
-			}
-
-			if err := json.NewDecoder(resp.Body).Decode(&subscribeResp); err != nil {
-				t.Fatalf("Failed to decode subscribe response: %v", err)
+				t.Fatalf("XRPC subscribe failed: %v", err)
 			}
 
 			t.Logf("✅ XRPC subscribe response received:")
@@ -1007,60 +803,13 @@ func TestCommunity_E2E(t *testing.T) {
 			t.Logf("📝 Subscription created and indexed: %s", subscription.RecordURI)
 
 			// Now unsubscribe via XRPC endpoint
-			unsubscribeReq := map[string]interface{}{
-				"community": community.DID,
-			}
-
-			reqBody, marshalErr := json.Marshal(unsubscribeReq)
-			if marshalErr != nil {
-				t.Fatalf("Failed to marshal unsubscribe request: %v", marshalErr)
-			}
-
-			// POST unsubscribe request
 			t.Logf("📡 Client → POST /xrpc/social.coves.community.unsubscribe")
 			t.Logf("   Unsubscribing from community: %s", community.DID)
 
-			req, err := http.NewRequest(http.MethodPost,
-				httpServer.URL+"/xrpc/social.coves.community.unsubscribe",
-				bytes.NewBuffer(reqBody))
-			if err != nil {
-				t.Fatalf("Failed to create request: %v", err)
-			}
-			req.Header.Set("Content-Type", "application/json")
-			// Use OAuth token for Coves API authentication
-			req.Header.Set("Authorization", "Bearer "+token)
-
-			resp, err := http.DefaultClient.Do(req)
-			if err != nil {
-				t.Fatalf("Failed to POST unsubscribe: %v", err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, readErr := io.ReadAll(resp.Body)
-				if readErr != nil {
-					t.Fatalf("Expected 200, got %d (failed to read body: %v)", resp.StatusCode, readErr)
-				}
-				t.Logf("❌ XRPC Unsubscribe Failed")
-				t.Logf("   Status: %d", resp.StatusCode)
-				t.Logf("   Response: %s", string(body))
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var unsubscribeResp struct {
-				Success bool `json:"success"`
-			}
-
-			if err := json.NewDecoder(resp.Body).Decode(&unsubscribeResp); err != nil {
-				t.Fatalf("Failed to decode unsubscribe response: %v", err)
-			}
-
-			t.Logf("✅ XRPC unsubscribe response received:")
-			t.Logf("   Success: %v", unsubscribeResp.Success)
-
-			if !unsubscribeResp.Success {
-				t.Errorf("Expected success: true, got: %v", unsubscribeResp.Success)
+			if err := client.WithToken(token).Unsubscribe(ctx, community.DID); err != nil {
+				t.Fatalf("XRPC unsubscribe failed: %v", err)
 			}
+			t.Logf("✅ XRPC unsubscribe succeeded")
 
 			// Verify the subscription record was deleted from PDS
 			t.Logf("🔍 Verifying subscription record deleted from PDS...")
@@ -1146,48 +895,9 @@ func TestCommunity_E2E(t *testing.T) {
 			community := createAndIndexCommunity(t, communityService, consumer, instanceDID, pdsURL)
 
 			t.Logf("🚫 Blocking community via XRPC endpoint...")
-			blockReq := map[string]interface{}{
-				"community": community.DID,
-			}
-
-			blockJSON, err := json.Marshal(blockReq)
-			if err != nil {
-				t.Fatalf("Failed to marshal block request: %v", err)
-			}
-
-			req, err := http.NewRequest("POST", httpServer.URL+"/xrpc/social.coves.community.blockCommunity", bytes.NewBuffer(blockJSON))
-			if err != nil {
-				t.Fatalf("Failed to create block request: %v", err)
-			}
-			req.Header.Set("Content-Type", "application/json")
-			req.Header.Set("Authorization", "Bearer "+token)
-
-			resp, err := http.DefaultClient.Do(req)
+			blockResp, err := client.WithToken(token).BlockCommunity(ctx, community.DID)
 			if err != nil {
-				t.Fatalf("Failed to POST block: %v", err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, readErr := io.ReadAll(resp.Body)
-				if readErr != nil {
-					t.Fatalf("Expected 200, got %d (failed to read body: %v)", resp.StatusCode, readErr)
-				}
-				t.Logf("❌ XRPC Block Failed")
-				t.Logf("   Status: %d", resp.StatusCode)
-				t.Logf("   Response: %s", string(body))
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var blockResp struct {
-				Block struct {
-					RecordURI string `json:"recordUri"`
-					RecordCID string `json:"recordCid"`
-				} `json:"block"`
-			}
-
-			if err := json.NewDecoder(resp.Body).Decode(&blockResp); err != nil {
-				t.Fatalf("Failed to decode block response: %v", err)
+				t.Fatalf("XRPC block failed: %v", err)
 			}
 
 			t.Logf("✅ XRPC block response received:")
@@ -1267,36 +977,10 @@ func TestCommunity_E2E(t *testing.T) {
 
 			// Block the community
 			t.Logf("🚫 Blocking community first...")
-			blockReq := map[string]interface{}{
-				"community": community.DID,
-			}
-			blockJSON, err := json.Marshal(blockReq)
-			if err != nil {
-				t.Fatalf("Failed to marshal block request: %v", err)
-			}
-
-			blockHttpReq, err := http.NewRequest("POST", httpServer.URL+"/xrpc/social.coves.community.blockCommunity", bytes.NewBuffer(blockJSON))
-			if err != nil {
-				t.Fatalf("Failed to create block request: %v", err)
-			}
-			blockHttpReq.Header.Set("Content-Type", "application/json")
-			blockHttpReq.Header.Set("Authorization", "Bearer "+token)
-
-			blockResp, err := http.DefaultClient.Do(blockHttpReq)
+			blockRespData, err := client.WithToken(token).BlockCommunity(ctx, community.DID)
 			if err != nil {
-				t.Fatalf("Failed to POST block: %v", err)
-			}
-
-			var blockRespData struct {
-				Block struct {
-					RecordURI string `json:"recordUri"`
-				} `json:"block"`
-			}
-			if err := json.NewDecoder(blockResp.Body).Decode(&blockRespData); err != nil {
-				func() { _ = blockResp.Body.Close() }()
-				t.Fatalf("Failed to decode block response: %v", err)
+				t.Fatalf("XRPC block failed: %v", err)
 			}
-			func() { _ = blockResp.Body.Close() }()
 
 			rkey := ""
 			if uriParts := strings.Split(blockRespData.Block.RecordURI, "/"); len(uriParts) >= 4 {
@@ -1326,49 +1010,8 @@ func TestCommunity_E2E(t *testing.T) {
 
 			// Now unblock the community
 			t.Logf("✅ Unblocking community via XRPC endpoint...")
-			unblockReq := map[string]interface{}{
-				"community": community.DID,
-			}
-
-			unblockJSON, err := json.Marshal(unblockReq)
-			if err != nil {
-				t.Fatalf("Failed to marshal unblock request: %v", err)
-			}
-
-			req, err := http.NewRequest("POST", httpServer.URL+"/xrpc/social.coves.community.unblockCommunity", bytes.NewBuffer(unblockJSON))
-			if err != nil {
-				t.Fatalf("Failed to create unblock request: %v", err)
-			}
-			req.Header.Set("Content-Type", "application/json")
-			req.Header.Set("Authorization", "Bearer "+token)
-
-			resp, err := http.DefaultClient.Do(req)
-			if err != nil {
-				t.Fatalf("Failed to POST unblock: %v", err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, readErr := io.ReadAll(resp.Body)
-				if readErr != nil {
-					t.Fatalf("Expected 200, got %d (failed to read body: %v)", resp.StatusCode, readErr)
-				}
-				t.Logf("❌ XRPC Unblock Failed")
-				t.Logf("   Status: %d", resp.StatusCode)
-				t.Logf("   Response: %s", string(body))
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var unblockResp struct {
-				Success bool `json:"success"`
-			}
-
-			if err := json.NewDecoder(resp.Body).Decode(&unblockResp); err != nil {
-				t.Fatalf("Failed to decode unblock response: %v", err)
-			}
-
-			if !unblockResp.Success {
-				t.Errorf("Expected success: true, got: %v", unblockResp.Success)
+			if err := client.WithToken(token).UnblockCommunity(ctx, community.DID); err != nil {
+				t.Fatalf("XRPC unblock failed: %v", err)
 			}
 
 			// Verify the block record was deleted from PDS
@@ -1432,32 +1075,10 @@ func TestCommunity_E2E(t *testing.T) {
 			community := createAndIndexCommunity(t, communityService, consumer, instanceDID, pdsURL)
 
 			t.Logf("🔒 Attempting to block community without auth token...")
-			blockReq := map[string]interface{}{
-				"community": community.DID,
-			}
-
-			blockJSON, err := json.Marshal(blockReq)
-			if err != nil {
-				t.Fatalf("Failed to marshal block request: %v", err)
-			}
-
-			req, err := http.NewRequest("POST", httpServer.URL+"/xrpc/social.coves.community.blockCommunity", bytes.NewBuffer(blockJSON))
-			if err != nil {
-				t.Fatalf("Failed to create block request: %v", err)
-			}
-			req.Header.Set("Content-Type", "application/json")
-			// NO Authorization header
-
-			resp, err := http.DefaultClient.Do(req)
-			if err != nil {
-				t.Fatalf("Failed to POST block: %v", err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			// Should fail with 401 Unauthorized
-			if resp.StatusCode != http.StatusUnauthorized {
-				body, _ := io.ReadAll(resp.Body)
-				t.Errorf("Expected 401 Unauthorized, got %d: %s", resp.StatusCode, string(body))
+			// No token: should fail with 401 Unauthorized
+			_, err := client.BlockCommunity(ctx, community.DID)
+			if !errors.Is(err, covesclient.ErrAuthRequired) {
+				t.Errorf("Expected AuthRequired, got %v", err)
 			} else {
 				t.Logf("✅ Block correctly rejected without authentication (401)")
 			}
@@ -1473,58 +1094,14 @@ func TestCommunity_E2E(t *testing.T) {
 			newVisibility := "unlisted"
 
 			// NOTE: updatedByDid is derived from JWT token, not provided in request
-			updateReq := map[string]interface{}{
-				"communityDid": community.DID,
-				"displayName":  newDisplayName,
-				"description":  newDescription,
-				"visibility":   newVisibility,
-			}
-
-			reqBody, marshalErr := json.Marshal(updateReq)
-			if marshalErr != nil {
-				t.Fatalf("Failed to marshal update request: %v", marshalErr)
-			}
-
-			// POST update request with JWT authentication
-			t.Logf("📡 Client → POST /xrpc/social.coves.community.update")
-			t.Logf("   Updating community: %s", community.DID)
-
-			req, err := http.NewRequest(http.MethodPost,
-				httpServer.URL+"/xrpc/social.coves.community.update",
-				bytes.NewBuffer(reqBody))
-			if err != nil {
-				t.Fatalf("Failed to create request: %v", err)
-			}
-			req.Header.Set("Content-Type", "application/json")
-			// Use OAuth token for Coves API authentication
-			req.Header.Set("Authorization", "Bearer "+token)
-
-			resp, err := http.DefaultClient.Do(req)
+			updateResp, err := client.WithToken(token).UpdateCommunity(ctx, covesclient.UpdateCommunityRequest{
+				CommunityDID: community.DID,
+				DisplayName:  &newDisplayName,
+				Description:  &newDescription,
+				Visibility:   &newVisibility,
+			})
 			if err != nil {
-				t.Fatalf("Failed to POST update: %v", err)
-			}
-			defer func() { _ = resp.Body.Close() }()
-
-			if resp.StatusCode != http.StatusOK {
-				body, readErr := io.ReadAll(resp.Body)
-				if readErr != nil {
-					t.Fatalf("Expected 200, got %d (failed to read body: %v)", resp.StatusCode, readErr)
-				}
-				t.Logf("❌ XRPC Update Failed")
-				t.Logf("   Status: %d", resp.StatusCode)
-				t.Logf("   Response: %s", string(body))
-				t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-			}
-
-			var updateResp struct {
-				URI    string `json:"uri"`
-				CID    string `json:"cid"`
-				DID    string `json:"did"`
-				Handle string `json:"handle"`
-			}
-
-			if err := json.NewDecoder(resp.Body).Decode(&updateResp); err != nil {
-				t.Fatalf("Failed to decode update response: %v", err)
+				t.Fatalf("XRPC update failed: %v", err)
 			}
 
 			t.Logf("✅ XRPC update response received:")
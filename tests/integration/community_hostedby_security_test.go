@@ -21,7 +21,7 @@ func TestHostedByVerification_DomainMatching(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("rejects community with mismatched hostedBy domain", func(t *testing.T) {
@@ -246,7 +246,7 @@ func TestBidirectionalDIDVerification(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("accepts community with valid bidirectional verification", func(t *testing.T) {
@@ -400,7 +400,7 @@ func TestExtractDomainFromHandle(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	testCases := []struct {
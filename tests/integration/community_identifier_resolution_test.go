@@ -27,7 +27,7 @@ func TestCommunityIdentifierResolution(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	// Get configuration from environment
@@ -227,7 +227,7 @@ func TestResolveScopedIdentifier_InputValidation(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	pdsURL := os.Getenv("PDS_URL")
@@ -406,7 +406,7 @@ func TestIdentifierResolution_ErrorContext(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	pdsURL := os.Getenv("PDS_URL")
@@ -473,7 +473,7 @@ func TestGetCommunity_IdentifierResolution(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	pdsURL := os.Getenv("PDS_URL")
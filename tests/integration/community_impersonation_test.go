@@ -0,0 +1,209 @@
+package integration
+
+import (
+	"Coves/internal/api/handlers/admin"
+	"Coves/internal/api/middleware"
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// communityProfileEvent builds a community profile commit for the impersonation tests
+func communityProfileEvent(did, operation, handle, name, displayName, hostedBy string) *jetstream.JetstreamEvent {
+	return &jetstream.JetstreamEvent{
+		Did:    did,
+		TimeUS: time.Now().UnixMicro(),
+		Kind:   "commit",
+		Commit: &jetstream.CommitEvent{
+			Rev:        "rev" + operation,
+			Operation:  operation,
+			Collection: "social.coves.community.profile",
+			RKey:       "self",
+			CID:        "bafy" + operation,
+			Record: map[string]interface{}{
+				"handle":      handle,
+				"name":        name,
+				"displayName": displayName,
+				"createdBy":   "did:plc:creator",
+				"hostedBy":    hostedBy,
+				"visibility":  "public",
+				"federation":  map[string]interface{}{"allowExternalDiscovery": true},
+				"createdAt":   time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+}
+
+func TestCommunityImpersonation_FlaggedAtIndexTime(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.social", true, nil)
+
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	name := "announce" + suffix
+
+	// The real community, hosted on coves.social
+	realDID := generateTestDID("real" + suffix)
+	if err := consumer.HandleEvent(ctx, communityProfileEvent(realDID, "create",
+		"c-"+name+".coves.social", name, "Announcements", "did:web:coves.social")); err != nil {
+		t.Fatalf("Failed to index real community: %v", err)
+	}
+
+	// An impostor on another instance claiming the real community's handle
+	fakeDID := generateTestDID("fake" + suffix)
+	if err := consumer.HandleEvent(ctx, communityProfileEvent(fakeDID, "create",
+		"c-"+name+".evil.example", name, "Official !"+name+"@coves.social", "did:web:evil.example")); err != nil {
+		t.Fatalf("Failed to index impostor: %v", err)
+	}
+
+	genuine, err := repo.GetByDID(ctx, realDID)
+	if err != nil {
+		t.Fatalf("Failed to get real community: %v", err)
+	}
+	if genuine.ImpersonationFlag {
+		t.Errorf("Real community should not be flagged, got reason %q", genuine.ImpersonationReason)
+	}
+
+	// Still reachable by DID, but flagged with a reason
+	fake, err := repo.GetByDID(ctx, fakeDID)
+	if err != nil {
+		t.Fatalf("Flagged community should still be reachable by DID: %v", err)
+	}
+	if !fake.ImpersonationFlag || !strings.Contains(fake.ImpersonationReason, "!"+name+"@coves.social") {
+		t.Fatalf("Expected impostor flagged for claiming !%s@coves.social, got flag=%v reason=%q",
+			name, fake.ImpersonationFlag, fake.ImpersonationReason)
+	}
+
+	// Hidden from search and list
+	found, _, err := repo.Search(ctx, communities.SearchCommunitiesRequest{Query: name, Limit: 10})
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(found) != 1 || found[0].DID != realDID {
+		t.Errorf("Expected only the real community in search results, got %d results", len(found))
+	}
+	listed, _, err := repo.List(ctx, communities.ListCommunitiesRequest{Sort: "new", Limit: 100})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	for _, c := range listed {
+		if c.DID == fakeDID {
+			t.Error("Flagged community should not be listed")
+		}
+	}
+
+	// Renaming to something harmless clears the flag on the next update
+	if err := consumer.HandleEvent(ctx, communityProfileEvent(fakeDID, "update",
+		"c-"+name+".evil.example", name, "Evil Example Announcements", "did:web:evil.example")); err != nil {
+		t.Fatalf("Failed to index update: %v", err)
+	}
+	fake, err = repo.GetByDID(ctx, fakeDID)
+	if err != nil {
+		t.Fatalf("Failed to get community: %v", err)
+	}
+	if fake.ImpersonationFlag {
+		t.Errorf("Expected flag cleared after rename, got reason %q", fake.ImpersonationReason)
+	}
+}
+
+func TestCommunityImpersonation_AdminReview(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.social", true, nil)
+	handler := admin.NewImpersonationHandler(postgres.NewImpersonationRepository(db), admin.NewAdmins([]string{"did:plc:admin"}))
+	communityService := communities.NewCommunityServiceWithPDSFactory(
+		repo, "http://localhost:3001", "did:web:test.coves.social", "test.coves.social", nil, nil, nil)
+
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	flagEvent := func(did, operation, name string) *jetstream.JetstreamEvent {
+		return communityProfileEvent(did, operation, "c-"+name+".evil.example", name,
+			"!"+name+"@coves.social mirror", "did:web:evil.example")
+	}
+
+	review := func(did, action string) *httptest.ResponseRecorder {
+		body := fmt.Sprintf(`{"community":%q,"action":%q}`, did, action)
+		req := httptest.NewRequest(http.MethodPost, "/xrpc/social.coves.admin.reviewImpersonationFlag", strings.NewReader(body))
+		req = req.WithContext(middleware.SetTestUserDID(req.Context(), "did:plc:admin"))
+		w := httptest.NewRecorder()
+		handler.HandleReview(w, req)
+		return w
+	}
+
+	t.Run("clear keeps the community unflagged on re-index", func(t *testing.T) {
+		did := generateTestDID("clear" + suffix)
+		name := "mirror" + suffix
+		if err := consumer.HandleEvent(ctx, flagEvent(did, "create", name)); err != nil {
+			t.Fatalf("Failed to index community: %v", err)
+		}
+
+		listReq := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.admin.listImpersonationFlags?limit=100", nil)
+		listReq = listReq.WithContext(middleware.SetTestUserDID(listReq.Context(), "did:plc:admin"))
+		w := httptest.NewRecorder()
+		handler.HandleList(w, listReq)
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), did) {
+			t.Fatalf("Expected flagged community in review list, got %d: %s", w.Code, w.Body.String())
+		}
+
+		if w := review(did, admin.ImpersonationActionClear); w.Code != http.StatusOK {
+			t.Fatalf("Expected clear to succeed, got %d: %s", w.Code, w.Body.String())
+		}
+
+		// The same profile indexed again isn't flagged for the reason an admin cleared
+		if err := consumer.HandleEvent(ctx, flagEvent(did, "update", name)); err != nil {
+			t.Fatalf("Failed to re-index community: %v", err)
+		}
+		community, err := repo.GetByDID(ctx, did)
+		if err != nil {
+			t.Fatalf("Failed to get community: %v", err)
+		}
+		if community.ImpersonationFlag {
+			t.Errorf("Cleared community was flagged again: %q", community.ImpersonationReason)
+		}
+
+		if w := review(did, admin.ImpersonationActionConfirm); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected 400 reviewing an unflagged community, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("confirm suspends the community", func(t *testing.T) {
+		did := generateTestDID("confirm" + suffix)
+		name := "impostor" + suffix
+		if err := consumer.HandleEvent(ctx, flagEvent(did, "create", name)); err != nil {
+			t.Fatalf("Failed to index community: %v", err)
+		}
+
+		if w := review(did, admin.ImpersonationActionConfirm); w.Code != http.StatusOK {
+			t.Fatalf("Expected confirm to succeed, got %d: %s", w.Code, w.Body.String())
+		}
+
+		community, err := repo.GetByDID(ctx, did)
+		if err != nil {
+			t.Fatalf("Failed to get community: %v", err)
+		}
+		if community.SuspendedAt == nil {
+			t.Fatal("Expected confirmed community to be suspended")
+		}
+		if _, err := communityService.GetCommunity(ctx, did); err != communities.ErrCommunitySuspended {
+			t.Errorf("Expected ErrCommunitySuspended from GetCommunity, got %v", err)
+		}
+	})
+
+	t.Run("unknown community", func(t *testing.T) {
+		if w := review(generateTestDID("missing"+suffix), admin.ImpersonationActionClear); w.Code != http.StatusNotFound {
+			t.Errorf("Expected 404, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
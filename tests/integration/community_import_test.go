@@ -0,0 +1,244 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/atproto/pds"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/communityimport"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// importIndexChecker reports whether imported records have reached the AppView tables
+type importIndexChecker struct {
+	postRepo    posts.Repository
+	commentRepo comments.Repository
+}
+
+func (c *importIndexChecker) IsIndexed(ctx context.Context, uri string) (bool, error) {
+	if strings.Contains(uri, "/social.coves.community.comment/") {
+		_, err := c.commentRepo.GetByURI(ctx, uri)
+		if comments.IsNotFound(err) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+	_, err := c.postRepo.GetByURI(ctx, uri)
+	if posts.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// TestCommunityImport_LivePDS imports a small fixture into a freshly provisioned
+// community on the dev PDS, then checks the records, resume/idempotency, and
+// that the consumers index the imported records.
+func TestCommunityImport_LivePDS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping live PDS import test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	pdsURL := getTestPDSURL()
+	healthResp, err := http.Get(pdsURL + "/xrpc/_health")
+	if err != nil {
+		t.Skipf("PDS not running at %s: %v", pdsURL, err)
+	}
+	_ = healthResp.Body.Close()
+
+	instanceHandle := os.Getenv("PDS_INSTANCE_HANDLE")
+	instancePassword := os.Getenv("PDS_INSTANCE_PASSWORD")
+	if instanceHandle == "" {
+		instanceHandle = "testuser123.local.coves.dev"
+	}
+	if instancePassword == "" {
+		instancePassword = "test-password-123"
+	}
+	_, instanceDID, err := authenticateWithPDS(pdsURL, instanceHandle, instancePassword)
+	if err != nil {
+		t.Skipf("Failed to authenticate with PDS (may not be configured): %v", err)
+	}
+	instanceDomain := "coves.social"
+	if strings.HasPrefix(instanceDID, "did:web:") {
+		instanceDomain = strings.TrimPrefix(instanceDID, "did:web:")
+	}
+
+	ctx := context.Background()
+
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	userService := users.NewUserService(postgres.NewUserRepository(db), nil, pdsURL)
+
+	communityService := communities.NewCommunityServiceWithPDSFactory(
+		communityRepo,
+		pdsURL,
+		instanceDID,
+		instanceDomain,
+		communities.NewPDSAccountProvisioner(instanceDomain, pdsURL),
+		nil,
+		nil,
+	)
+
+	// Archive account: a real PDS account so comments can be written to its repo
+	archiveHandle := fmt.Sprintf("arc%d.local.coves.dev", time.Now().UnixNano()%1000000)
+	archivePassword := "test-password-123"
+	_, archiveDID, err := createPDSAccount(pdsURL, archiveHandle, fmt.Sprintf("archive-%d@test.local", time.Now().Unix()), archivePassword)
+	if err != nil {
+		t.Skipf("PDS not available: %v", err)
+	}
+	createTestUser(t, db, archiveHandle, archiveDID)
+
+	community, err := communityService.CreateCommunity(ctx, communities.CreateCommunityRequest{
+		Name:                   fmt.Sprintf("imp%d", time.Now().UnixNano()%1000000),
+		DisplayName:            "Import Test Community",
+		Description:            "Target community for import testing",
+		CreatedByDID:           archiveDID,
+		Visibility:             "public",
+		AllowExternalDiscovery: true,
+	})
+	require.NoError(t, err, "Failed to provision community on PDS")
+
+	f, err := os.Open("testdata/community_import.ndjson")
+	require.NoError(t, err)
+	dump, err := communityimport.ParseDump(f)
+	_ = f.Close()
+	require.NoError(t, err)
+
+	communityClient := func(ctx context.Context) (pds.Client, error) {
+		fresh, err := communityService.EnsureFreshToken(ctx, community)
+		if err != nil {
+			return nil, err
+		}
+		return pds.NewFromAccessToken(fresh.PDSURL, fresh.DID, fresh.PDSAccessToken)
+	}
+	archiveClient := func(ctx context.Context) (pds.Client, error) {
+		return pds.NewFromPasswordAuth(ctx, pdsURL, archiveHandle, archivePassword)
+	}
+
+	newImporter := func(progressPath string) (*communityimport.Importer, *communityimport.Progress) {
+		progress, err := communityimport.LoadProgress(progressPath)
+		require.NoError(t, err)
+		importer, err := communityimport.NewImporter(communityimport.Config{
+			CommunityDID:    community.DID,
+			ArchiveDID:      archiveDID,
+			CommunityClient: communityClient,
+			ArchiveClient:   archiveClient,
+			Progress:        progress,
+			WritesPerSecond: 20,
+		})
+		require.NoError(t, err)
+		return importer, progress
+	}
+
+	progressPath := filepath.Join(t.TempDir(), "progress.json")
+	importer, progress := newImporter(progressPath)
+
+	report, err := importer.Run(ctx, dump)
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.PostsWritten)
+	assert.Equal(t, 2, report.CommentsWritten)
+
+	t.Run("records are tagged and keep original timestamps", func(t *testing.T) {
+		client, err := communityClient(ctx)
+		require.NoError(t, err)
+
+		rec, err := client.GetRecord(ctx, "social.coves.community.post", communityimport.RecordKey("t3_imp001", dump[0].CreatedAt))
+		require.NoError(t, err)
+		assert.Equal(t, true, rec.Value["imported"])
+		assert.Equal(t, archiveDID, rec.Value["author"])
+		assert.Equal(t, "2015-06-01T12:00:00Z", rec.Value["createdAt"])
+	})
+
+	t.Run("re-running is idempotent", func(t *testing.T) {
+		// Same progress file: nothing is written
+		again, _ := newImporter(progressPath)
+		report, err := again.Run(ctx, dump)
+		require.NoError(t, err)
+		assert.Equal(t, &communityimport.Report{PostsSkipped: 2, CommentsSkipped: 2}, report)
+
+		// Lost progress file: existing records are adopted, not duplicated
+		fresh, _ := newImporter(filepath.Join(t.TempDir(), "lost.json"))
+		_, err = fresh.Run(ctx, dump)
+		require.NoError(t, err)
+
+		client, err := communityClient(ctx)
+		require.NoError(t, err)
+		list, err := client.ListRecords(ctx, "social.coves.community.post", 100, "")
+		require.NoError(t, err)
+		assert.Len(t, list.Records, 2, "community repo should hold exactly the imported posts")
+	})
+
+	t.Run("imported records are indexed", func(t *testing.T) {
+		postConsumer := jetstream.NewPostEventConsumer(postRepo, communityRepo, userService, db)
+		commentConsumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+
+		uris := communityimport.URIs(dump, progress)
+		require.Len(t, uris, 4)
+
+		// Replay the PDS records through the consumers in dump order (parents first)
+		for _, uri := range uris {
+			parts := strings.Split(strings.TrimPrefix(uri, "at://"), "/")
+			require.Len(t, parts, 3)
+			repoDID, collection, rkey := parts[0], parts[1], parts[2]
+
+			clientFn := communityClient
+			if repoDID == archiveDID {
+				clientFn = archiveClient
+			}
+			client, err := clientFn(ctx)
+			require.NoError(t, err)
+			rec, err := client.GetRecord(ctx, collection, rkey)
+			require.NoError(t, err)
+
+			event := &jetstream.JetstreamEvent{
+				Did:  repoDID,
+				Kind: "commit",
+				Commit: &jetstream.CommitEvent{
+					Rev:        "import-rev",
+					Operation:  "create",
+					Collection: collection,
+					RKey:       rkey,
+					CID:        rec.CID,
+					Record:     rec.Value,
+				},
+			}
+			if collection == "social.coves.community.post" {
+				require.NoError(t, postConsumer.HandleEvent(ctx, event))
+			} else {
+				require.NoError(t, commentConsumer.HandleEvent(ctx, event))
+			}
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		missing, err := communityimport.WaitForIndexing(waitCtx,
+			&importIndexChecker{postRepo: postRepo, commentRepo: commentRepo}, uris, 100*time.Millisecond)
+		require.NoError(t, err)
+		assert.Empty(t, missing)
+
+		indexed, err := postRepo.GetByURI(ctx, uris[0])
+		require.NoError(t, err)
+		assert.Equal(t, 2015, indexed.CreatedAt.Year(), "historical createdAt should survive indexing")
+		assert.Equal(t, 2, indexed.CommentCount)
+	})
+}
@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommunityRepo_ListByRelevance seeds one community per relevance stratum ordering and
+// checks the order of the first page and that paging with the cursor visits every community
+// exactly once, in order
+func TestCommunityRepo_ListByRelevance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	suffix := uniqueTestID()
+	now := time.Now().UTC()
+
+	community := func(name string) string {
+		did, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("%s-%s", name, suffix), fmt.Sprintf("relowner-%s.test", suffix))
+		require.NoError(t, err)
+		return did
+	}
+	subOld := community("rel-subold")
+	subNew := community("rel-subnew")
+	netTwo := community("rel-nettwo")
+	netOne := community("rel-netone")
+	restBig := community("rel-restbig")
+	restSmall := community("rel-restsmall")
+	hub := community("rel-hub")
+
+	exec := func(query string, args ...interface{}) {
+		_, err := db.ExecContext(ctx, query, args...)
+		require.NoError(t, err)
+	}
+	exec(`UPDATE communities SET last_post_at = $2 WHERE did = $1`, subOld, now.Add(-48*time.Hour))
+	exec(`UPDATE communities SET last_post_at = $2 WHERE did = $1`, subNew, now.Add(-time.Hour))
+	exec(`UPDATE communities SET subscriber_count = 10 WHERE did = $1`, restBig)
+	exec(`UPDATE communities SET subscriber_count = 5 WHERE did = $1`, restSmall)
+
+	viewer := createTestUser(t, db, fmt.Sprintf("relviewer%s.test", suffix), fmt.Sprintf("did:plc:relviewer%s", suffix)).DID
+	alice := createTestUser(t, db, fmt.Sprintf("relalice%s.test", suffix), fmt.Sprintf("did:plc:relalice%s", suffix)).DID
+	bob := createTestUser(t, db, fmt.Sprintf("relbob%s.test", suffix), fmt.Sprintf("did:plc:relbob%s", suffix)).DID
+	stranger := createTestUser(t, db, fmt.Sprintf("relstranger%s.test", suffix), fmt.Sprintf("did:plc:relstranger%s", suffix)).DID
+
+	exec(`INSERT INTO community_subscriptions (user_did, community_did) VALUES ($1, $2)`, viewer, subOld)
+	exec(`INSERT INTO community_subscriptions (user_did, community_did) VALUES ($1, $2)`, viewer, subNew)
+
+	// The viewer replies to alice's post, and bob replies to the viewer
+	comment := func(commenterDID, rootURI, parentURI string) string {
+		rkey := generateTID()
+		uri := fmt.Sprintf("at://%s/social.coves.community.comment/%s", commenterDID, rkey)
+		exec(`
+			INSERT INTO comments (uri, cid, rkey, commenter_did, root_uri, root_cid, parent_uri, parent_cid, content, created_at)
+			VALUES ($1, 'bafycomment', $2, $3, $4, 'bafytest', $5, 'bafytest', 'Reply', $6)`,
+			uri, rkey, commenterDID, rootURI, parentURI, now)
+		return uri
+	}
+	alicePost := createTestPost(t, db, hub, alice, "Relevance hub", 0, now)
+	viewerReply := comment(viewer, alicePost, alicePost)
+	comment(bob, alicePost, viewerReply)
+
+	// Alice and bob are active in netTwo, alice alone in netOne; a stranger's activity doesn't count
+	activity := postgres.NewCommunityActivityRepository(db)
+	require.NoError(t, activity.RecordActivity(ctx, netTwo, alice, now))
+	require.NoError(t, activity.RecordActivity(ctx, netTwo, bob, now))
+	require.NoError(t, activity.RecordActivity(ctx, netOne, alice, now))
+	require.NoError(t, activity.RecordActivity(ctx, restBig, stranger, now))
+
+	req := communities.ListCommunitiesRequest{Sort: communities.ListSortRelevance, ViewerDID: viewer, Limit: 3}
+
+	t.Run("first page lists subscriptions then the viewer's network", func(t *testing.T) {
+		page, cursor, err := repo.List(ctx, req)
+		require.NoError(t, err)
+		require.NotNil(t, cursor)
+		require.Len(t, page, 3)
+		assert.Equal(t, []string{subNew, subOld, netTwo}, []string{page[0].DID, page[1].DID, page[2].DID})
+
+		next := req
+		next.Cursor = *cursor
+		page, _, err = repo.List(ctx, next)
+		require.NoError(t, err)
+		require.NotEmpty(t, page)
+		assert.Equal(t, netOne, page[0].DID)
+	})
+
+	t.Run("paging visits every community once", func(t *testing.T) {
+		seen := map[string]bool{}
+		var order []string
+		page := req
+		for i := 0; i < 1000; i++ {
+			result, cursor, err := repo.List(ctx, page)
+			require.NoError(t, err)
+			for _, c := range result {
+				require.False(t, seen[c.DID], "community %s listed twice", c.DID)
+				seen[c.DID] = true
+				order = append(order, c.DID)
+			}
+			if cursor == nil {
+				break
+			}
+			page.Cursor = *cursor
+		}
+
+		// Other tests' communities can sit between ours in the last stratum; keep only ours
+		seeded := map[string]bool{subOld: true, subNew: true, netTwo: true, netOne: true, restBig: true, restSmall: true}
+		var ours []string
+		for _, did := range order {
+			if seeded[did] {
+				ours = append(ours, did)
+			}
+		}
+		assert.Equal(t, []string{subNew, subOld, netTwo, netOne, restBig, restSmall}, ours)
+	})
+
+	t.Run("a tampered cursor is rejected", func(t *testing.T) {
+		bad := req
+		bad.Cursor = "not-a-cursor"
+		_, _, err := repo.List(ctx, bad)
+		assert.ErrorIs(t, err, communities.ErrInvalidCursor)
+	})
+}
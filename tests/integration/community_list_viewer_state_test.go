@@ -27,7 +27,7 @@ func TestCommunityList_ViewerState(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	// Create test communities
@@ -203,7 +203,23 @@ func (m *mockCommunityService) UpdateCommunity(ctx context.Context, req communit
 	return nil, fmt.Errorf("not implemented")
 }
 
-func (m *mockCommunityService) ListCommunities(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, error) {
+func (m *mockCommunityService) GetCommunityRules(ctx context.Context, communityDID string) (*communities.CommunityRules, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockCommunityService) UpdateCommunityRules(ctx context.Context, req communities.UpdateRulesRequest) (*communities.CommunityRules, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockCommunityService) DeleteCommunity(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockCommunityService) UndeleteCommunity(ctx context.Context, req communities.DeleteCommunityRequest) (*communities.Community, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockCommunityService) ListCommunities(ctx context.Context, req communities.ListCommunitiesRequest) ([]*communities.Community, *string, error) {
 	return m.repo.List(ctx, req)
 }
 
@@ -219,14 +235,22 @@ func (m *mockCommunityService) UnsubscribeFromCommunity(ctx context.Context, ses
 	return fmt.Errorf("not implemented")
 }
 
-func (m *mockCommunityService) GetUserSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*communities.Subscription, error) {
-	return nil, fmt.Errorf("not implemented")
+func (m *mockCommunityService) GetUserSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, fmt.Errorf("not implemented")
 }
 
-func (m *mockCommunityService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit, offset int) ([]*communities.Subscription, error) {
+func (m *mockCommunityService) GetSubscribedCommunities(ctx context.Context, req communities.GetSubscriptionsRequest) ([]*communities.SubscribedCommunity, error) {
 	return nil, fmt.Errorf("not implemented")
 }
 
+func (m *mockCommunityService) GetCommunitySubscribers(ctx context.Context, communityIdentifier string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, fmt.Errorf("not implemented")
+}
+
+func (m *mockCommunityService) IsSubscribed(ctx context.Context, userDID, communityDID string) (bool, error) {
+	return false, fmt.Errorf("not implemented")
+}
+
 func (m *mockCommunityService) BlockCommunity(ctx context.Context, session *oauth.ClientSessionData, communityIdentifier string) (*communities.CommunityBlock, error) {
 	return nil, fmt.Errorf("not implemented")
 }
@@ -0,0 +1,93 @@
+package integration
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/db/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommunityNameReservations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	repo := postgres.NewCommunityNameReservationRepository(db)
+	expiresAt := time.Now().Add(communities.NameReservationTTL)
+
+	t.Run("exactly one of many simultaneous reservations wins", func(t *testing.T) {
+		name := "race-" + uniqueTestID()
+		const attempts = 8
+		errs := make([]error, attempts)
+		start := make(chan struct{})
+		var wg sync.WaitGroup
+		for i := 0; i < attempts; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				<-start
+				errs[i] = repo.Reserve(ctx, name, fmt.Sprintf("token-%d", i), expiresAt)
+			}(i)
+		}
+		close(start)
+		wg.Wait()
+
+		won := 0
+		for _, err := range errs {
+			if err == nil {
+				won++
+				continue
+			}
+			assert.True(t, errors.Is(err, communities.ErrHandleTaken), "unexpected error %v", err)
+		}
+		assert.Equal(t, 1, won)
+	})
+
+	t.Run("only the holder releases the name", func(t *testing.T) {
+		name := "release-" + uniqueTestID()
+		require.NoError(t, repo.Reserve(ctx, name, "holder", expiresAt))
+
+		require.NoError(t, repo.Release(ctx, name, "someone-else"))
+		assert.ErrorIs(t, repo.Reserve(ctx, name, "second", expiresAt), communities.ErrHandleTaken)
+
+		require.NoError(t, repo.Release(ctx, name, "holder"))
+		assert.NoError(t, repo.Reserve(ctx, name, "second", expiresAt))
+	})
+
+	t.Run("expired reservations are taken over and reaped", func(t *testing.T) {
+		takenOver := "expired-" + uniqueTestID()
+		reaped := "reaped-" + uniqueTestID()
+		require.NoError(t, repo.Reserve(ctx, takenOver, "crashed", time.Now().Add(-time.Minute)))
+		require.NoError(t, repo.Reserve(ctx, reaped, "crashed", time.Now().Add(-time.Minute)))
+
+		// A crashed request's reservation doesn't block the name even before the reaper runs
+		require.NoError(t, repo.Reserve(ctx, takenOver, "retry", expiresAt))
+		require.NoError(t, repo.Release(ctx, takenOver, "crashed"))
+		assert.ErrorIs(t, repo.Reserve(ctx, takenOver, "third", expiresAt), communities.ErrHandleTaken, "stale holder must not release the new reservation")
+
+		deleted, err := repo.DeleteExpired(ctx, time.Now())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, deleted, int64(1))
+
+		var remaining int
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM community_name_reservations WHERE name = ANY($1::text[])`,
+			fmt.Sprintf("{%s,%s}", takenOver, reaped)).Scan(&remaining))
+		assert.Equal(t, 1, remaining, "only the live reservation should remain")
+	})
+}
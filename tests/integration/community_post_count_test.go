@@ -0,0 +1,179 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostConsumer_CommunityPostCount verifies the post consumer counts each post once, even
+// when Jetstream replays the create or delete event
+func TestPostConsumer_CommunityPostCount(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	suffix := uniqueTestID()
+
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	userService := users.NewUserService(postgres.NewUserRepository(db), nil, getTestPDSURL())
+	consumer := jetstream.NewPostEventConsumer(postgres.NewPostRepository(db, newTestCursorSigner()), communityRepo, userService, db)
+
+	author := createTestUser(t, db, fmt.Sprintf("postcount-%s.test", suffix), fmt.Sprintf("did:plc:postcount%s", suffix))
+	communityDID, err := createFeedTestCommunity(db, ctx, "postcount-"+suffix, "postcountowner"+suffix)
+	require.NoError(t, err)
+
+	postEvent := func(operation, rkey string) *jetstream.JetstreamEvent {
+		event := &jetstream.JetstreamEvent{
+			Did:  communityDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev",
+				Operation:  operation,
+				Collection: "social.coves.community.post",
+				RKey:       rkey,
+				CID:        "bafy" + rkey,
+			},
+		}
+		if operation == "create" {
+			event.Commit.Record = map[string]interface{}{
+				"$type":     "social.coves.community.post",
+				"community": communityDID,
+				"author":    author.DID,
+				"title":     "Counted",
+				"content":   "Counts toward the community",
+				"createdAt": time.Now().Format(time.RFC3339),
+			}
+		}
+		return event
+	}
+
+	postCount := func(t *testing.T) int {
+		t.Helper()
+		community, err := communityRepo.GetByDID(ctx, communityDID)
+		require.NoError(t, err)
+		return community.PostCount
+	}
+
+	first, second := generateTID(), generateTID()
+
+	t.Run("a duplicate create event counts once", func(t *testing.T) {
+		require.NoError(t, consumer.HandleEvent(ctx, postEvent("create", first)))
+		require.NoError(t, consumer.HandleEvent(ctx, postEvent("create", first)))
+		assert.Equal(t, 1, postCount(t))
+
+		require.NoError(t, consumer.HandleEvent(ctx, postEvent("create", second)))
+		assert.Equal(t, 2, postCount(t))
+	})
+
+	t.Run("a duplicate delete event uncounts once", func(t *testing.T) {
+		require.NoError(t, consumer.HandleEvent(ctx, postEvent("delete", first)))
+		require.NoError(t, consumer.HandleEvent(ctx, postEvent("delete", first)))
+		assert.Equal(t, 1, postCount(t))
+	})
+
+	t.Run("community views expose the count", func(t *testing.T) {
+		community, err := communityRepo.GetByDID(ctx, communityDID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, community.ToCommunityView().PostCount)
+		assert.Equal(t, 1, community.ToCommunityViewDetailed().PostCount)
+	})
+}
+
+// TestCommunityPostCount_BackfillAndRecount verifies the migration backfill and the startup
+// recount both restore post_count from live posts
+func TestCommunityPostCount_BackfillAndRecount(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	suffix := uniqueTestID()
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+
+	// Same-length names so search scores them equally relevant
+	busyDID, err := createFeedTestCommunity(db, ctx, "pcbusy"+suffix, "busyowner"+suffix)
+	require.NoError(t, err)
+	quietDID, err := createFeedTestCommunity(db, ctx, "pcidle"+suffix, "quietowner"+suffix)
+	require.NoError(t, err)
+
+	// createTestPost writes rows directly, bypassing the consumer's count
+	authorDID := "did:plc:backfill" + suffix
+	for i := 0; i < 3; i++ {
+		createTestPost(t, db, busyDID, authorDID, fmt.Sprintf("Post %d", i), 0, time.Now())
+	}
+	deletedURI := createTestPost(t, db, busyDID, authorDID, "Deleted", 0, time.Now())
+	_, err = db.ExecContext(ctx, `UPDATE posts SET deleted_at = NOW() WHERE uri = $1`, deletedURI)
+	require.NoError(t, err)
+
+	assertCounts := func(t *testing.T, busy, quiet int) {
+		t.Helper()
+		for did, want := range map[string]int{busyDID: busy, quietDID: quiet} {
+			community, err := communityRepo.GetByDID(ctx, did)
+			require.NoError(t, err)
+			assert.Equal(t, want, community.PostCount, did)
+		}
+	}
+	corrupt := func(t *testing.T) {
+		t.Helper()
+		_, err := db.ExecContext(ctx, `UPDATE communities SET post_count = 42 WHERE did = ANY($1)`, "{"+busyDID+","+quietDID+"}")
+		require.NoError(t, err)
+	}
+
+	t.Run("migration backfill counts live posts", func(t *testing.T) {
+		migration, err := os.ReadFile("../../internal/db/migrations/068_backfill_community_post_count.sql")
+		require.NoError(t, err)
+		up, _, found := strings.Cut(string(migration), "-- +goose Down")
+		require.True(t, found)
+
+		corrupt(t)
+		_, err = db.ExecContext(ctx, up)
+		require.NoError(t, err)
+		assertCounts(t, 3, 0)
+	})
+
+	t.Run("recount repairs drift", func(t *testing.T) {
+		corrupt(t)
+		corrected, err := communityRepo.RecountPostCounts(ctx)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, corrected, 2)
+		assertCounts(t, 3, 0)
+	})
+
+	t.Run("search breaks relevance ties by post count", func(t *testing.T) {
+		results, _, err := communityRepo.Search(ctx, communities.SearchCommunitiesRequest{Query: suffix, Limit: 10})
+		require.NoError(t, err)
+		var order []string
+		for _, c := range results {
+			if c.DID == busyDID || c.DID == quietDID {
+				order = append(order, c.DID)
+			}
+		}
+		assert.Equal(t, []string{busyDID, quietDID}, order)
+	})
+}
@@ -20,7 +20,7 @@ func TestCommunityRepository_PasswordEncryption(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("encrypts and decrypts password correctly", func(t *testing.T) {
@@ -144,7 +144,7 @@ func TestCommunityService_NameValidation(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	provisioner := communities.NewPDSAccountProvisioner("test.local", "http://localhost:3001")
 	service := communities.NewCommunityServiceWithPDSFactory(
 		repo,
@@ -312,7 +312,7 @@ func TestPasswordSecurity(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("generates unique passwords", func(t *testing.T) {
@@ -393,7 +393,7 @@ func TestPasswordSecurity(t *testing.T) {
 			}
 		}()
 
-		repo := postgres.NewCommunityRepository(db)
+		repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 		uniqueSuffix := fmt.Sprintf("%d", time.Now().UnixNano())
 
 		// Create a community with a known password
@@ -451,7 +451,7 @@ func TestConcurrentProvisioning(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("prevents duplicate community creation", func(t *testing.T) {
@@ -708,7 +708,7 @@ func TestTokenValidation(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("validates access token storage", func(t *testing.T) {
@@ -17,7 +17,7 @@ func TestCommunityRepository_Create(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("creates community successfully", func(t *testing.T) {
@@ -128,7 +128,7 @@ func TestCommunityRepository_GetByDID(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("retrieves existing community", func(t *testing.T) {
@@ -186,7 +186,7 @@ func TestCommunityRepository_GetByHandle(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("retrieves community by handle", func(t *testing.T) {
@@ -232,7 +232,7 @@ func TestCommunityRepository_Subscriptions(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	// Create a community for subscription tests
@@ -326,7 +326,7 @@ func TestCommunityRepository_List(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	t.Run("lists communities with pagination", func(t *testing.T) {
@@ -358,7 +358,7 @@ func TestCommunityRepository_List(t *testing.T) {
 			Offset: 0,
 		}
 
-		results, err := repo.List(ctx, req)
+		results, _, err := repo.List(ctx, req)
 		if err != nil {
 			t.Fatalf("Failed to list communities: %v", err)
 		}
@@ -395,7 +395,7 @@ func TestCommunityRepository_List(t *testing.T) {
 			Visibility: "public",
 		}
 
-		results, err := repo.List(ctx, req)
+		results, _, err := repo.List(ctx, req)
 		if err != nil {
 			t.Fatalf("Failed to list public communities: %v", err)
 		}
@@ -417,7 +417,7 @@ func TestCommunityRepository_GetSubscribedCommunityDIDs(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	ctx := context.Background()
 
 	// Create test communities
@@ -535,7 +535,7 @@ func TestCommunityRepository_GetSubscribedCommunityDIDs(t *testing.T) {
 // 		}
 // 	}()
 //
-// 	repo := postgres.NewCommunityRepository(db)
+// 	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 // 	ctx := context.Background()
 //
 // 	t.Run("searches communities by name", func(t *testing.T) {
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"Coves/internal/db/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestCommunityRules_Indexing covers rules records flowing from Jetstream into community_rules
+func TestCommunityRules_Indexing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	repo := createTestCommunityRepo(t, db)
+	rulesRepo := postgres.NewCommunityRulesRepository(db)
+	consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.local", true, nil)
+	consumer.SetRulesRepository(rulesRepo)
+
+	community := createTestCommunity(t, repo, "rules", fmt.Sprintf("did:plc:rules-%d", time.Now().UnixNano()))
+
+	rulesEvent := func(did, operation string, record map[string]interface{}) *jetstream.JetstreamEvent {
+		return &jetstream.JetstreamEvent{
+			Did:    did,
+			Kind:   "commit",
+			TimeUS: time.Now().UnixMicro(),
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev-rules",
+				Operation:  operation,
+				Collection: "social.coves.community.rules",
+				RKey:       "self",
+				CID:        "bafyrules" + operation,
+				Record:     record,
+			},
+		}
+	}
+
+	record := map[string]interface{}{
+		"$type":    "social.coves.community.rules",
+		"markdown": "# Welcome\n<iframe src=\"https://evil.example\"></iframe>Read the rules.",
+		"textRules": []interface{}{
+			map[string]interface{}{"title": "Be kind", "description": "No personal attacks", "createdAt": "2024-01-01T00:00:00Z", "isActive": true},
+		},
+	}
+	if err := consumer.HandleEvent(ctx, rulesEvent(community.DID, "create", record)); err != nil {
+		t.Fatalf("Failed to index rules: %v", err)
+	}
+
+	stored, err := rulesRepo.GetRules(ctx, community.DID)
+	if err != nil {
+		t.Fatalf("Failed to get rules: %v", err)
+	}
+	if stored.Markdown != "# Welcome\nRead the rules." {
+		t.Errorf("Expected sanitized markdown, got %q", stored.Markdown)
+	}
+	if len(stored.Rules) != 1 || stored.Rules[0].Title != "Be kind" || stored.Rules[0].CreatedAt.IsZero() {
+		t.Errorf("Unexpected structured rules: %+v", stored.Rules)
+	}
+
+	// Updates replace the document
+	record["markdown"] = "Updated"
+	record["textRules"] = []interface{}{}
+	if err := consumer.HandleEvent(ctx, rulesEvent(community.DID, "update", record)); err != nil {
+		t.Fatalf("Failed to update rules: %v", err)
+	}
+	stored, err = rulesRepo.GetRules(ctx, community.DID)
+	if err != nil {
+		t.Fatalf("Failed to get rules: %v", err)
+	}
+	if stored.Markdown != "Updated" || len(stored.Rules) != 0 || stored.RecordCID != "bafyrulesupdate" {
+		t.Errorf("Expected the updated document, got %+v", stored)
+	}
+
+	// Rules for a community that isn't indexed yet are retried rather than stored
+	err = consumer.HandleEvent(ctx, rulesEvent("did:plc:rules-unknown", "create", record))
+	if !errors.Is(err, communities.ErrCommunityNotFound) {
+		t.Errorf("Expected ErrCommunityNotFound for an unindexed community, got %v", err)
+	}
+
+	if err := consumer.HandleEvent(ctx, rulesEvent(community.DID, "delete", nil)); err != nil {
+		t.Fatalf("Failed to delete rules: %v", err)
+	}
+	if _, err := rulesRepo.GetRules(ctx, community.DID); !errors.Is(err, communities.ErrRulesNotFound) {
+		t.Errorf("Expected rules to be deleted, got %v", err)
+	}
+}
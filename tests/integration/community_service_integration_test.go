@@ -51,7 +51,7 @@ func TestCommunityService_CreateWithRealPDS(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	t.Run("creates community with real PDS provisioning", func(t *testing.T) {
 		// Create provisioner and service (production code path)
@@ -306,7 +306,7 @@ func TestCommunityService_UpdateWithRealPDS(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	provisioner := communities.NewPDSAccountProvisioner("coves.social", pdsURL)
 	service := communities.NewCommunityServiceWithPDSFactory(
@@ -503,7 +503,7 @@ func TestPasswordAuthentication(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	provisioner := communities.NewPDSAccountProvisioner("coves.social", pdsURL)
 	service := communities.NewCommunityServiceWithPDSFactory(
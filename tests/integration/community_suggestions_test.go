@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"Coves/internal/core/suggestions"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSuggestionsRepo_RanksByFollowedUsers seeds subscriptions and activity for followed users
+// and checks communities are ranked by distinct followed users, without the viewer's own
+// subscriptions, blocks, or communities that aren't listed
+func TestSuggestionsRepo_RanksByFollowedUsers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	now := time.Now().UTC()
+
+	community := func(name string) string {
+		did, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("%s-%d", name, testID), fmt.Sprintf("suggestowner-%d.test", testID))
+		require.NoError(t, err)
+		return did
+	}
+	popular := community("sugg-popular")
+	niche := community("sugg-niche")
+	stale := community("sugg-stale")
+	subscribed := community("sugg-subscribed")
+	blocked := community("sugg-blocked")
+	private := community("sugg-private")
+	_, err := db.ExecContext(ctx, `UPDATE communities SET visibility = 'private' WHERE did = $1`, private)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE communities SET weekly_active_users = 100 WHERE did = $1`, subscribed)
+	require.NoError(t, err)
+
+	viewer := fmt.Sprintf("did:plc:suggviewer-%d", testID)
+	alice := fmt.Sprintf("did:plc:suggalice-%d", testID)
+	bob := fmt.Sprintf("did:plc:suggbob-%d", testID)
+	createTestUser(t, db, fmt.Sprintf("suggviewer-%d.test", testID), viewer)
+	createTestUser(t, db, fmt.Sprintf("suggalice-%d.test", testID), alice)
+	createTestUser(t, db, fmt.Sprintf("suggbob-%d.test", testID), bob)
+
+	subscribe := func(userDID, communityDID string) {
+		_, err := db.ExecContext(ctx, `INSERT INTO community_subscriptions (user_did, community_did) VALUES ($1, $2)`, userDID, communityDID)
+		require.NoError(t, err)
+	}
+	activity := postgres.NewCommunityActivityRepository(db)
+
+	// popular: alice subscribes and is active, bob is active -> 2
+	subscribe(alice, popular)
+	require.NoError(t, activity.RecordActivity(ctx, popular, alice, now))
+	require.NoError(t, activity.RecordActivity(ctx, popular, bob, now.AddDate(0, 0, -3)))
+	// niche: bob subscribes -> 1
+	subscribe(bob, niche)
+	// stale: alice was active too long ago -> not suggested
+	require.NoError(t, activity.RecordActivity(ctx, stale, alice, now.AddDate(0, 0, -45)))
+	// Excluded whatever the follows do
+	subscribe(alice, subscribed)
+	subscribe(bob, subscribed)
+	subscribe(viewer, subscribed)
+	subscribe(alice, blocked)
+	subscribe(alice, private)
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO community_blocks (user_did, community_did, record_uri, record_cid)
+		VALUES ($1, $2, $3, 'bafyblock')`, viewer, blocked, fmt.Sprintf("at://%s/social.coves.community.block/sugg", viewer))
+	require.NoError(t, err)
+
+	all := []string{popular, niche, stale, subscribed, blocked, private}
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM community_activity WHERE community_did = ANY($1)`, pq.Array(all))
+		_, _ = db.Exec(`DELETE FROM community_blocks WHERE user_did = $1`, viewer)
+		_, _ = db.Exec(`DELETE FROM community_subscriptions WHERE community_did = ANY($1)`, pq.Array(all))
+		_, _ = db.Exec(`DELETE FROM communities WHERE did = ANY($1)`, pq.Array(all))
+	})
+
+	repo := postgres.NewSuggestionsRepository(db)
+
+	indexed, err := repo.FilterIndexedUsers(ctx, []string{alice, bob, "did:plc:bluesky-only"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{alice, bob}, indexed)
+
+	ranked, err := repo.RankByFollowedUsers(ctx, viewer, indexed, now.AddDate(0, 0, -30), suggestions.MaxSuggestions)
+	require.NoError(t, err)
+	require.Len(t, ranked, 2)
+	assert.Equal(t, popular, ranked[0].Community.DID)
+	assert.Equal(t, 2, ranked[0].FollowedUsers)
+	assert.Equal(t, niche, ranked[1].Community.DID)
+	assert.Equal(t, 1, ranked[1].FollowedUsers)
+
+	excluded, err := repo.GetExcludedCommunityDIDs(ctx, viewer, all)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{subscribed: true, blocked: true}, excluded)
+
+	trending, err := repo.ListTrending(ctx, viewer, 500)
+	require.NoError(t, err)
+	for _, c := range trending {
+		assert.NotContains(t, []string{subscribed, blocked, private}, c.DID)
+	}
+}
@@ -89,7 +89,7 @@ func TestCommunityUpdateE2E_WithJetstream(t *testing.T) {
 	identityResolver := identity.NewResolver(db, identityConfig)
 
 	// Setup services
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	provisioner := communities.NewPDSAccountProvisioner("coves.social", pdsURL)
 	blobService := blobs.NewBlobService(pdsURL)
 	communityService := communities.NewCommunityServiceWithPDSFactory(
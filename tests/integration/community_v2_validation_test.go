@@ -19,7 +19,7 @@ func TestCommunityConsumer_V2RKeyValidation(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	// Skip verification in tests
 	// Pass nil for identity resolver - not needed since consumer constructs handles from DIDs
 	consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.local", true, nil)
@@ -248,7 +248,7 @@ func TestCommunityConsumer_HandleField(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	// Skip verification in tests
 	// Pass nil for identity resolver - not needed since consumer constructs handles from DIDs
 	consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.local", true, nil)
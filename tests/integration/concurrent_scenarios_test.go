@@ -29,7 +29,7 @@ func TestConcurrentVoting_MultipleUsersOnSamePost(t *testing.T) {
 
 	ctx := context.Background()
 	voteRepo := postgres.NewVoteRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
 	userService := users.NewUserService(userRepo, nil, "http://localhost:3001")
 	voteConsumer := jetstream.NewVoteEventConsumer(voteRepo, userService, db)
@@ -339,10 +339,10 @@ func TestConcurrentCommenting_MultipleUsersOnSamePost(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	commentRepo := postgres.NewCommentRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	commentConsumer := jetstream.NewCommentEventConsumer(commentRepo, db)
 
 	fixedTime := time.Date(2025, 11, 16, 12, 0, 0, 0, time.UTC)
@@ -601,7 +601,7 @@ func TestConcurrentCommunityCreation_DuplicateHandle(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	t.Run("Concurrent creation with same handle should fail", func(t *testing.T) {
 		const numAttempts = 10
@@ -740,7 +740,7 @@ func TestConcurrentSubscription_RaceConditions(t *testing.T) {
 	}()
 
 	ctx := context.Background()
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	consumer := jetstream.NewCommunityEventConsumer(communityRepo, "did:web:coves.local", true, nil)
 
 	// Create test community
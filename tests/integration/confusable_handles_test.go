@@ -0,0 +1,174 @@
+package integration
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/core/confusables"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The lookalike handles below sit on "xn--cves-55d.social", which is "cоves.social" with a
+// Cyrillic о: every handle on it imitates the same handle on coves.social.
+const lookalikeDomain = "xn--cves-55d.social"
+
+// confusableWith reads the confusable_with column for a DID in table
+func confusableWith(t *testing.T, db *sql.DB, table, did string) string {
+	t.Helper()
+	var flagged sql.NullString
+	err := db.QueryRow(fmt.Sprintf(`SELECT confusable_with FROM %s WHERE did = $1`, table), did).Scan(&flagged)
+	require.NoError(t, err)
+	return flagged.String
+}
+
+func TestConfusableHandles_UserFlaggedOnIndex(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewUserRepository(db)
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+
+	// The lookalike is indexed first, while there's nothing for it to imitate
+	lookalikeDID := generateTestDID("look" + suffix)
+	lookalike, err := repo.Create(ctx, &users.User{DID: lookalikeDID, Handle: "bob" + suffix + "." + lookalikeDomain, PDSURL: getTestPDSURL()})
+	require.NoError(t, err)
+	assert.Empty(t, lookalike.ConfusableWith)
+
+	genuineDID := generateTestDID("bob" + suffix)
+	genuine, err := repo.Create(ctx, &users.User{DID: genuineDID, Handle: "bob" + suffix + ".coves.social", PDSURL: getTestPDSURL()})
+	require.NoError(t, err)
+	assert.Empty(t, genuine.ConfusableWith, "a handle without punycode isn't flagged against one with it")
+
+	// Indexing the genuine handle flags the lookalike indexed before it
+	fetched, err := repo.GetByDID(ctx, lookalikeDID)
+	require.NoError(t, err)
+	assert.Equal(t, genuineDID, fetched.ConfusableWith)
+
+	// A handle change to an unrelated handle clears the flag...
+	_, err = repo.UpdateHandle(ctx, lookalikeDID, "carol"+suffix+"."+lookalikeDomain)
+	require.NoError(t, err)
+	assert.Empty(t, confusableWith(t, db, "users", lookalikeDID))
+
+	// ...and changing back flags it straight away
+	moved, err := repo.UpdateHandle(ctx, lookalikeDID, "bob"+suffix+"."+lookalikeDomain)
+	require.NoError(t, err)
+	assert.Equal(t, genuineDID, moved.ConfusableWith)
+
+	// The genuine account moving away releases the lookalike
+	_, err = repo.UpdateHandle(ctx, genuineDID, "robert"+suffix+".coves.social")
+	require.NoError(t, err)
+	assert.Empty(t, confusableWith(t, db, "users", lookalikeDID))
+}
+
+func TestConfusableHandles_CommunityFlaggedOnIndex(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	name := "news" + suffix
+
+	newCommunity := func(did, handle, hostedBy string) *communities.Community {
+		return &communities.Community{
+			DID:                    did,
+			Handle:                 handle,
+			Name:                   name,
+			OwnerDID:               hostedBy,
+			CreatedByDID:           "did:plc:creator",
+			HostedByDID:            hostedBy,
+			Visibility:             "public",
+			AllowExternalDiscovery: true,
+			CreatedAt:              time.Now(),
+			UpdatedAt:              time.Now(),
+		}
+	}
+
+	genuineDID := generateTestDID("real" + suffix)
+	genuine, err := repo.Create(ctx, newCommunity(genuineDID, "c-"+name+".coves.social", "did:web:coves.social"))
+	require.NoError(t, err)
+	assert.Empty(t, genuine.ConfusableWith)
+
+	lookalikeDID := generateTestDID("fake" + suffix)
+	lookalike, err := repo.Create(ctx, newCommunity(lookalikeDID, "c-"+name+"."+lookalikeDomain, "did:web:"+lookalikeDomain))
+	require.NoError(t, err)
+	assert.Equal(t, genuineDID, lookalike.ConfusableWith)
+	assert.Equal(t, genuineDID, confusableWith(t, db, "communities", lookalikeDID))
+
+	collisions, err := postgres.NewConfusablesRepository(db).ListCollisions(ctx, 100, 0)
+	require.NoError(t, err)
+	var found *confusables.Collision
+	for _, c := range collisions {
+		if c.DID == lookalikeDID {
+			found = c
+		}
+	}
+	require.NotNil(t, found, "flagged community should be listed for review")
+	assert.Equal(t, confusables.KindCommunity, found.Kind)
+	assert.Equal(t, "c-"+name+".coves.social", found.ConfusableHandle)
+	assert.Equal(t, confusables.HandleSkeleton("c-"+name+".coves.social"), found.Skeleton)
+}
+
+func TestConfusableHandles_ASCIILookalikes(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewUserRepository(db)
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+
+	genuineDID := generateTestDID("modern" + suffix)
+	genuine, err := repo.Create(ctx, &users.User{DID: genuineDID, Handle: "modern" + suffix + ".coves.social", PDSURL: getTestPDSURL()})
+	require.NoError(t, err)
+	assert.Empty(t, genuine.ConfusableWith)
+
+	// A newer ASCII handle that only looks alike is flagged against the older one
+	lookalikeDID := generateTestDID("modem" + suffix)
+	lookalike, err := repo.Create(ctx, &users.User{DID: lookalikeDID, Handle: "modem" + suffix + ".c0ves.socia1", PDSURL: getTestPDSURL()})
+	require.NoError(t, err)
+	assert.Equal(t, genuineDID, lookalike.ConfusableWith)
+
+	// Re-indexing the older handle leaves it unflagged
+	_, err = repo.UpdateHandle(ctx, genuineDID, "modern"+suffix+".coves.social")
+	require.NoError(t, err)
+	assert.Empty(t, confusableWith(t, db, "users", genuineDID))
+	assert.Equal(t, genuineDID, confusableWith(t, db, "users", lookalikeDID))
+}
+
+func TestConfusableHandles_MarkedInAuthorView(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	userRepo := postgres.NewUserRepository(db)
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+
+	genuineDID := generateTestDID("dan" + suffix)
+	_, err := userRepo.Create(ctx, &users.User{DID: genuineDID, Handle: "dan" + suffix + ".coves.social", PDSURL: getTestPDSURL()})
+	require.NoError(t, err)
+	lookalikeDID := generateTestDID("notdan" + suffix)
+	_, err = userRepo.Create(ctx, &users.User{DID: lookalikeDID, Handle: "dan" + suffix + "." + lookalikeDomain, PDSURL: getTestPDSURL()})
+	require.NoError(t, err)
+
+	communityDID, err := createFeedTestCommunity(db, ctx, "confusable"+suffix, "owner"+suffix+".test")
+	require.NoError(t, err)
+	spoofURI := createTestPost(t, db, communityDID, lookalikeDID, "Definitely dan", 1, time.Now())
+	genuineURI := createTestPost(t, db, communityDID, genuineDID, "Actually dan", 1, time.Now())
+
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	spoof, err := postRepo.GetViewByURI(ctx, spoofURI, "")
+	require.NoError(t, err)
+	assert.True(t, spoof.Author.ConfusableFlag, "lookalike author should be marked")
+
+	genuinePost, err := postRepo.GetViewByURI(ctx, genuineURI, "")
+	require.NoError(t, err)
+	assert.False(t, genuinePost.Author.ConfusableFlag)
+}
@@ -0,0 +1,146 @@
+package integration
+
+import (
+	"Coves/internal/api/handlers/timeline"
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/consumergaps"
+	"Coves/internal/db/postgres"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	timelineCore "Coves/internal/core/timeline"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gapIDs returns the IDs of gaps
+func gapIDs(gaps []*consumergaps.Gap) []int64 {
+	ids := make([]int64, 0, len(gaps))
+	for _, gap := range gaps {
+		ids = append(ids, gap.ID)
+	}
+	return ids
+}
+
+// TestConsumerGaps_ReportedInTimeline records gaps as the dispatcher would after a reconnect
+// with a stale cursor, and checks the timeline reports the ones its page overlaps
+func TestConsumerGaps_ReportedInTimeline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	gapsRepo := postgres.NewConsumerGapsRepository(db)
+	timelineService := timelineCore.NewTimelineService(postgres.NewTimelineRepository(db, newTestCursorSigner()), timelineCore.WithGapLookup(gapsRepo))
+	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil, nil, nil)
+
+	testID := time.Now().UnixNano()
+	userDID := fmt.Sprintf("did:plc:gapuser-%d", testID)
+	_, err := db.ExecContext(ctx, `INSERT INTO users (did, handle, pds_url) VALUES ($1, $2, $3)`,
+		userDID, fmt.Sprintf("gapuser-%d.test", testID), "https://bsky.social")
+	require.NoError(t, err)
+
+	communityDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("gaps-%d", testID), fmt.Sprintf("gapowner-%d.test", testID))
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO community_subscriptions (user_did, community_did, content_visibility)
+		VALUES ($1, $2, 3)`, userDID, communityDID)
+	require.NoError(t, err)
+
+	// Posts indexed three and one hours ago; the page covers three hours ago to now
+	now := time.Now()
+	for _, age := range []time.Duration{3 * time.Hour, time.Hour} {
+		uri := createTestPost(t, db, communityDID, "did:plc:gapauthor", "Post", 1, now.Add(-age))
+		_, err = db.ExecContext(ctx, `UPDATE posts SET indexed_at = created_at WHERE uri = $1`, uri)
+		require.NoError(t, err)
+	}
+
+	window := func(from, to time.Duration) (int64, int64) {
+		return now.Add(-from).UnixMicro(), now.Add(-to).UnixMicro()
+	}
+	insideStart, insideEnd := window(2*time.Hour, 90*time.Minute)
+	require.NoError(t, gapsRepo.RecordGap(ctx, "jetstream", insideStart, insideEnd))
+	// A gap two days ago is outside the page
+	outsideStart, outsideEnd := window(48*time.Hour, 47*time.Hour)
+	require.NoError(t, gapsRepo.RecordGap(ctx, "jetstream", outsideStart, outsideEnd))
+
+	unfilled, err := gapsRepo.ListUnfilled(ctx, now.Add(-3*time.Hour), now)
+	require.NoError(t, err)
+	var overlapping *consumergaps.Gap
+	for _, gap := range unfilled {
+		if gap.Start.UnixMicro() == insideStart && gap.End.UnixMicro() == insideEnd {
+			overlapping = gap
+		}
+	}
+	require.NotNil(t, overlapping, "the gap inside the page should be unfilled")
+
+	getTimeline := func() *timelineCore.TimelineResponse {
+		req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getTimeline?sort=new&limit=10", nil)
+		req = req.WithContext(middleware.SetTestUserDID(req.Context(), userDID))
+		rec := httptest.NewRecorder()
+		handler.HandleGetTimeline(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response timelineCore.TimelineResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return &response
+	}
+
+	response := getTimeline()
+	require.Len(t, response.Feed, 2)
+	assert.Contains(t, gapIDs(response.Gaps), overlapping.ID, "timeline should report the gap its page overlaps")
+	for _, gap := range response.Gaps {
+		assert.True(t, gap.End.After(now.Add(-3*time.Hour)), "reported gap %d is outside the page", gap.ID)
+	}
+
+	// Once backfilled, the gap is no longer reported
+	marked, err := gapsRepo.MarkBackfilled(ctx, overlapping.ID)
+	require.NoError(t, err)
+	require.NotNil(t, marked.BackfilledAt)
+	assert.NotContains(t, gapIDs(getTimeline().Gaps), overlapping.ID)
+
+	// Marking again keeps the original time
+	again, err := gapsRepo.MarkBackfilled(ctx, overlapping.ID)
+	require.NoError(t, err)
+	assert.True(t, again.BackfilledAt.Equal(*marked.BackfilledAt))
+
+	_, err = gapsRepo.MarkBackfilled(ctx, -1)
+	assert.ErrorIs(t, err, consumergaps.ErrGapNotFound)
+}
+
+// TestConsumerGaps_Positions saves and reloads consumer positions, as a restart would
+func TestConsumerGaps_Positions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	gapsRepo := postgres.NewConsumerGapsRepository(db)
+	consumer := fmt.Sprintf("test-%d", time.Now().UnixNano())
+
+	position, err := gapsRepo.LoadPosition(ctx, consumer)
+	require.NoError(t, err)
+	assert.Equal(t, consumergaps.Position{}, position, "a consumer that never saved has no position")
+
+	first := consumergaps.Position{LastSeenUS: time.Now().UnixMicro(), ConnectedAt: time.Now().Truncate(time.Microsecond)}
+	require.NoError(t, gapsRepo.SavePosition(ctx, consumer, first))
+	second := consumergaps.Position{LastSeenUS: first.LastSeenUS + 1000, ConnectedAt: first.ConnectedAt.Add(time.Second)}
+	require.NoError(t, gapsRepo.SavePosition(ctx, consumer, second))
+
+	position, err = gapsRepo.LoadPosition(ctx, consumer)
+	require.NoError(t, err)
+	assert.Equal(t, second.LastSeenUS, position.LastSeenUS)
+	assert.True(t, second.ConnectedAt.Equal(position.ConnectedAt), "expected %s, got %s", second.ConnectedAt, position.ConnectedAt)
+}
@@ -0,0 +1,207 @@
+package integration
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/moderation"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/timeline"
+	"Coves/internal/db/postgres"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContentVisibility_AuthorOnly checks that author_only posts and comments stay visible to
+// their author while other users and anonymous viewers see them as removed
+func TestContentVisibility_AuthorOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	signer := newTestCursorSigner()
+	feedRepo := postgres.NewCommunityFeedRepository(db, signer)
+	timelineRepo := postgres.NewTimelineRepository(db, signer)
+	discoverRepo := postgres.NewDiscoverRepository(db, signer)
+	commentRepo := postgres.NewCommentRepository(db, signer)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	moderationService := moderation.NewModerationService(postgres.NewModerationRepository(db))
+
+	suffix := uniqueTestID()
+	author := createTestUser(t, db, "shadow"+suffix+".test", "did:plc:shadow"+suffix)
+	other := createTestUser(t, db, "bystander"+suffix+".test", "did:plc:bystander"+suffix)
+	communityDID, err := createFeedTestCommunity(db, ctx, "shadow"+suffix, "owner"+suffix+".test")
+	require.NoError(t, err)
+
+	for _, did := range []string{author.DID, other.DID} {
+		_, err = db.ExecContext(ctx, `
+			INSERT INTO community_subscriptions (user_did, community_did, subscribed_at)
+			VALUES ($1, $2, NOW())
+		`, did, communityDID)
+		require.NoError(t, err)
+	}
+
+	now := time.Now()
+	livePost := createTestPost(t, db, communityDID, other.DID, "Live", 0, now.Add(-time.Minute))
+	hiddenPost := createTestPost(t, db, communityDID, author.DID, "Shadow-banned", 0, now)
+	removedPost := createTestPost(t, db, communityDID, author.DID, "Removed", 0, now)
+	liveComment := createTestCommentWithScore(t, db, other.DID, livePost, livePost, "live", 0, 0, now.Add(-time.Minute))
+	hiddenComment := createTestCommentWithScore(t, db, author.DID, livePost, livePost, "hidden", 0, 0, now)
+	hiddenReply := createTestCommentWithScore(t, db, author.DID, livePost, liveComment, "hidden reply", 0, 0, now)
+	removedComment := createTestCommentWithScore(t, db, author.DID, livePost, livePost, "removed", 0, 0, now)
+
+	setVisibility := func(subject string, state moderation.VisibilityState) {
+		t.Helper()
+		require.NoError(t, moderationService.SetVisibility(ctx, moderation.SetVisibilityRequest{
+			Subject: subject, State: state, ActorDID: "did:plc:admin",
+		}))
+	}
+	setVisibility(hiddenPost, moderation.VisibilityAuthorOnly)
+	setVisibility(removedPost, moderation.VisibilityRemoved)
+	setVisibility(hiddenComment, moderation.VisibilityAuthorOnly)
+	setVisibility(hiddenReply, moderation.VisibilityAuthorOnly)
+	setVisibility(removedComment, moderation.VisibilityRemoved)
+
+	commentURIs := func(list []*comments.Comment) []string {
+		uris := make([]string, 0, len(list))
+		for _, c := range list {
+			uris = append(uris, c.URI)
+		}
+		return uris
+	}
+
+	viewers := []struct {
+		name       string
+		did        string
+		seesHidden bool
+	}{
+		{name: "author", did: author.DID, seesHidden: true},
+		{name: "other user", did: other.DID},
+		{name: "anonymous"},
+	}
+
+	for _, viewer := range viewers {
+		t.Run(viewer.name, func(t *testing.T) {
+			checkPosts := func(t *testing.T, uris []string) {
+				t.Helper()
+				assert.Contains(t, uris, livePost)
+				assert.NotContains(t, uris, removedPost, "removed posts are hidden from everyone")
+				if viewer.seesHidden {
+					assert.Contains(t, uris, hiddenPost)
+				} else {
+					assert.NotContains(t, uris, hiddenPost)
+				}
+			}
+
+			t.Run("community feed", func(t *testing.T) {
+				feed, _, err := feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+					Community: communityDID, Sort: "new", ViewerDID: viewer.did, Limit: 50,
+				})
+				require.NoError(t, err)
+				var uris []string
+				for _, item := range feed {
+					uris = append(uris, item.Post.URI)
+				}
+				checkPosts(t, uris)
+			})
+
+			t.Run("discover", func(t *testing.T) {
+				feed, _, err := discoverRepo.GetDiscover(ctx, discover.GetDiscoverRequest{
+					Sort: "new", ViewerDID: viewer.did, Limit: 50,
+				})
+				require.NoError(t, err)
+				var uris []string
+				for _, item := range feed {
+					uris = append(uris, item.Post.URI)
+				}
+				checkPosts(t, uris)
+			})
+
+			if viewer.did != "" {
+				t.Run("timeline", func(t *testing.T) {
+					feed, _, err := timelineRepo.GetTimeline(ctx, timeline.GetTimelineRequest{
+						UserDID: viewer.did, Sort: "new", Limit: 50,
+					})
+					require.NoError(t, err)
+					var uris []string
+					for _, item := range feed {
+						uris = append(uris, item.Post.URI)
+					}
+					checkPosts(t, uris)
+				})
+			}
+
+			t.Run("comments", func(t *testing.T) {
+				top, _, err := commentRepo.ListByParentWithHotRank(ctx, livePost, "new", "all", 50, nil, viewer.did)
+				require.NoError(t, err)
+				replies, err := commentRepo.ListByParentsBatchWithDeleted(ctx, []string{liveComment}, "new", 10, viewer.did)
+				require.NoError(t, err)
+
+				assert.Contains(t, commentURIs(top), liveComment)
+				if viewer.seesHidden {
+					assert.Contains(t, commentURIs(top), hiddenComment)
+					assert.Contains(t, commentURIs(replies[liveComment]), hiddenReply)
+				} else {
+					assert.NotContains(t, commentURIs(top), hiddenComment)
+					assert.NotContains(t, commentURIs(replies[liveComment]), hiddenReply)
+				}
+			})
+
+			t.Run("author profile posts", func(t *testing.T) {
+				authored, _, err := postRepo.GetByAuthor(ctx, posts.GetAuthorPostsRequest{
+					ActorDID: author.DID, ViewerDID: viewer.did, Limit: 50,
+				})
+				require.NoError(t, err)
+				var uris []string
+				for _, post := range authored {
+					uris = append(uris, post.URI)
+				}
+				assert.NotContains(t, uris, removedPost, "removed posts are hidden from everyone")
+				if viewer.seesHidden {
+					assert.Contains(t, uris, hiddenPost)
+				} else {
+					assert.NotContains(t, uris, hiddenPost)
+				}
+			})
+
+			t.Run("author profile comments", func(t *testing.T) {
+				history, _, err := commentRepo.ListByCommenterWithCursor(ctx, comments.ListByCommenterRequest{
+					CommenterDID: author.DID, ViewerDID: viewer.did, Limit: 50,
+				})
+				require.NoError(t, err)
+				assert.NotContains(t, commentURIs(history), removedComment, "removed comments are hidden from everyone")
+				if viewer.seesHidden {
+					assert.Contains(t, commentURIs(history), hiddenComment)
+					assert.Contains(t, commentURIs(history), hiddenReply)
+				} else {
+					assert.NotContains(t, commentURIs(history), hiddenComment)
+					assert.NotContains(t, commentURIs(history), hiddenReply)
+				}
+			})
+		})
+	}
+
+	// Restoring visibility shows the content to everyone again
+	setVisibility(hiddenPost, moderation.VisibilityVisible)
+	feed, _, err := feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+		Community: communityDID, Sort: "new", Limit: 50,
+	})
+	require.NoError(t, err)
+	var uris []string
+	for _, item := range feed {
+		uris = append(uris, item.Post.URI)
+	}
+	assert.Contains(t, uris, hiddenPost)
+}
@@ -0,0 +1,178 @@
+package integration
+
+import (
+	"Coves/internal/core/directory"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDirectoryRepo_ListHostedIsIncremental tests that the directory listing pages by
+// (updated_at, did) and only returns this instance's non-remote communities, withdrawn ones as
+// tombstones
+func TestDirectoryRepo_ListHostedIsIncremental(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	hostedBy := fmt.Sprintf("did:web:dir-%d.test", testID)
+	base := time.Now().Add(-time.Hour).UTC().Truncate(time.Microsecond)
+
+	insert := func(name string, updatedAt time.Time, extra string) string {
+		did := fmt.Sprintf("did:plc:dir-%s-%d", name, testID)
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO communities (did, name, owner_did, created_by_did, hosted_by_did, handle, created_at, updated_at)
+			VALUES ($1, $2, $1, 'did:plc:dircreator', $3, $4, $5, $5)`,
+			did, name, hostedBy, fmt.Sprintf("c-%s-%d.dir.test", name, testID), updatedAt)
+		require.NoError(t, err)
+		if extra != "" {
+			_, err = db.ExecContext(ctx, `UPDATE communities SET `+extra+` WHERE did = $1`, did)
+			require.NoError(t, err)
+		}
+		return did
+	}
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM communities WHERE hosted_by_did = $1`, hostedBy)
+	})
+
+	first := insert("first", base, "")
+	second := insert("second", base.Add(time.Minute), "")
+	private := insert("private", base, "visibility = 'private'")
+	insert("remote", base, "remote = TRUE")
+	insert("recent", time.Now(), "")
+
+	repo := postgres.NewDirectoryRepository(db)
+	before := time.Now().Add(-time.Second)
+
+	page, err := repo.ListHosted(ctx, hostedBy, nil, before, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, first, page[0].DID)
+	assert.Equal(t, directory.StatusActive, page[0].Status)
+
+	after := &directory.Cursor{UpdatedAt: page[0].UpdatedAt, DID: page[0].DID}
+	page, err = repo.ListHosted(ctx, hostedBy, after, before, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 2, "remote and not-yet-settled communities aren't listed")
+	assert.Equal(t, private, page[0].DID)
+	assert.Equal(t, directory.StatusUnlisted, page[0].Status, "private communities are listed as tombstones")
+	assert.Equal(t, second, page[1].DID)
+
+	// Touching a community moves it past the cursor
+	after = &directory.Cursor{UpdatedAt: page[1].UpdatedAt, DID: page[1].DID}
+	_, err = db.ExecContext(ctx, `UPDATE communities SET updated_at = $2 WHERE did = $1`, first, base.Add(2*time.Minute))
+	require.NoError(t, err)
+	page, err = repo.ListHosted(ctx, hostedBy, after, before, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, first, page[0].DID)
+
+	// Suspending a community lists it again as a tombstone
+	after = &directory.Cursor{UpdatedAt: page[0].UpdatedAt, DID: page[0].DID}
+	_, err = db.ExecContext(ctx, `UPDATE communities SET suspended_at = NOW(), updated_at = $2 WHERE did = $1`, second, base.Add(3*time.Minute))
+	require.NoError(t, err)
+	page, err = repo.ListHosted(ctx, hostedBy, after, before, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, second, page[0].DID)
+	assert.Equal(t, directory.StatusSuspended, page[0].Status)
+}
+
+// TestDirectoryRepo_UpsertRemoteDedup tests that remote entries are deduplicated by DID and
+// never overwrite a community indexed from the firehose
+func TestDirectoryRepo_UpsertRemoteDedup(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	hostedBy := fmt.Sprintf("did:web:peer-%d.test", testID)
+	t.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM communities WHERE hosted_by_did = $1`, hostedBy)
+		_, _ = db.Exec(`DELETE FROM directory_sync_peers WHERE peer_url = $1`, "https://"+hostedBy)
+	})
+
+	repo := postgres.NewDirectoryRepository(db)
+	entry := &directory.Entry{
+		DID:             fmt.Sprintf("did:plc:remote-%d", testID),
+		Handle:          fmt.Sprintf("c-remote-%d.peer.test", testID),
+		Name:            "remote",
+		CreatedBy:       "did:plc:remotecreator",
+		HostedBy:        hostedBy,
+		SubscriberCount: 3,
+		CreatedAt:       time.Now().Add(-time.Hour),
+		UpdatedAt:       time.Now().Add(-time.Minute),
+	}
+
+	indexed, err := repo.UpsertRemote(ctx, entry, false)
+	require.NoError(t, err)
+	assert.True(t, indexed)
+
+	entry.SubscriberCount = 9
+	indexed, err = repo.UpsertRemote(ctx, entry, true)
+	require.NoError(t, err)
+	assert.True(t, indexed)
+
+	var rows, subscribers int
+	var remote, blocked bool
+	require.NoError(t, db.QueryRowContext(ctx, `
+		SELECT COUNT(*) OVER (), subscriber_count, remote, federation_blocked
+		FROM communities WHERE did = $1`, entry.DID).Scan(&rows, &subscribers, &remote, &blocked))
+	assert.Equal(t, 1, rows)
+	assert.Equal(t, 9, subscribers)
+	assert.True(t, remote)
+	assert.True(t, blocked)
+
+	// A tombstone hides the entry, but only when it comes from the community's host
+	withdrawn, err := repo.WithdrawRemote(ctx, entry.DID, "did:web:someone-else.test", directory.StatusDeleted)
+	require.NoError(t, err)
+	assert.False(t, withdrawn)
+	withdrawn, err = repo.WithdrawRemote(ctx, entry.DID, hostedBy, directory.StatusDeleted)
+	require.NoError(t, err)
+	assert.True(t, withdrawn)
+	var deleted bool
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT deleted_at IS NOT NULL FROM communities WHERE did = $1`, entry.DID).Scan(&deleted))
+	assert.True(t, deleted)
+
+	// Listed again, it's shown again
+	indexed, err = repo.UpsertRemote(ctx, entry, true)
+	require.NoError(t, err)
+	assert.True(t, indexed)
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT deleted_at IS NOT NULL FROM communities WHERE did = $1`, entry.DID).Scan(&deleted))
+	assert.False(t, deleted)
+
+	// Once the firehose has indexed the community, the directory no longer touches it
+	_, err = db.ExecContext(ctx, `UPDATE communities SET remote = FALSE, subscriber_count = 1 WHERE did = $1`, entry.DID)
+	require.NoError(t, err)
+	entry.SubscriberCount = 50
+	indexed, err = repo.UpsertRemote(ctx, entry, false)
+	require.NoError(t, err)
+	assert.False(t, indexed)
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT subscriber_count FROM communities WHERE did = $1`, entry.DID).Scan(&subscribers))
+	assert.Equal(t, 1, subscribers)
+
+	// Peer cursors round-trip
+	peerURL := "https://" + hostedBy
+	cursor, err := repo.GetPeerCursor(ctx, peerURL)
+	require.NoError(t, err)
+	assert.Empty(t, cursor)
+	require.NoError(t, repo.SavePeerCursor(ctx, peerURL, "abc"))
+	require.NoError(t, repo.SavePeerCursor(ctx, peerURL, "def"))
+	cursor, err = repo.GetPeerCursor(ctx, peerURL)
+	require.NoError(t, err)
+	assert.Equal(t, "def", cursor)
+}
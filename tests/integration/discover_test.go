@@ -78,9 +78,9 @@ func TestGetDiscover_ShowsAllCommunities(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	discoverRepo := postgres.NewDiscoverRepository(db, "test-cursor-secret")
+	discoverRepo := postgres.NewDiscoverRepository(db, newTestCursorSigner())
 	discoverService := discoverCore.NewDiscoverService(discoverRepo)
-	handler := discover.NewGetDiscoverHandler(discoverService, nil, nil) // nil vote/bluesky services - tests don't need them
+	handler := discover.NewGetDiscoverHandler(discoverService, nil, nil, nil, nil) // nil vote/bluesky services - tests don't need them
 
 	ctx := context.Background()
 	testID := time.Now().UnixNano()
@@ -149,9 +149,9 @@ func TestGetDiscover_NoAuthRequired(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	discoverRepo := postgres.NewDiscoverRepository(db, "test-cursor-secret")
+	discoverRepo := postgres.NewDiscoverRepository(db, newTestCursorSigner())
 	discoverService := discoverCore.NewDiscoverService(discoverRepo)
-	handler := discover.NewGetDiscoverHandler(discoverService, nil, nil) // nil vote/bluesky services - tests don't need them
+	handler := discover.NewGetDiscoverHandler(discoverService, nil, nil, nil, nil) // nil vote/bluesky services - tests don't need them
 
 	ctx := context.Background()
 	testID := time.Now().UnixNano()
@@ -196,9 +196,9 @@ func TestGetDiscover_HotSort(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	discoverRepo := postgres.NewDiscoverRepository(db, "test-cursor-secret")
+	discoverRepo := postgres.NewDiscoverRepository(db, newTestCursorSigner())
 	discoverService := discoverCore.NewDiscoverService(discoverRepo)
-	handler := discover.NewGetDiscoverHandler(discoverService, nil, nil) // nil vote/bluesky services
+	handler := discover.NewGetDiscoverHandler(discoverService, nil, nil, nil, nil) // nil vote/bluesky services
 
 	ctx := context.Background()
 	testID := time.Now().UnixNano()
@@ -246,9 +246,9 @@ func TestGetDiscover_Pagination(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	discoverRepo := postgres.NewDiscoverRepository(db, "test-cursor-secret")
+	discoverRepo := postgres.NewDiscoverRepository(db, newTestCursorSigner())
 	discoverService := discoverCore.NewDiscoverService(discoverRepo)
-	handler := discover.NewGetDiscoverHandler(discoverService, nil, nil)
+	handler := discover.NewGetDiscoverHandler(discoverService, nil, nil, nil, nil)
 
 	ctx := context.Background()
 	testID := time.Now().UnixNano()
@@ -303,9 +303,9 @@ func TestGetDiscover_LimitValidation(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	discoverRepo := postgres.NewDiscoverRepository(db, "test-cursor-secret")
+	discoverRepo := postgres.NewDiscoverRepository(db, newTestCursorSigner())
 	discoverService := discoverCore.NewDiscoverService(discoverRepo)
-	handler := discover.NewGetDiscoverHandler(discoverService, nil, nil)
+	handler := discover.NewGetDiscoverHandler(discoverService, nil, nil, nil, nil)
 
 	t.Run("Limit exceeds maximum", func(t *testing.T) {
 		req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getDiscover?sort=new&limit=100", nil)
@@ -350,9 +350,9 @@ func TestGetDiscover_ViewerVoteState(t *testing.T) {
 	mockVotes.AddVote(viewerDID, post2URI, "down", "at://"+viewerDID+"/social.coves.vote/vote2")
 
 	// Setup handler with mock vote service
-	discoverRepo := postgres.NewDiscoverRepository(db, "test-cursor-secret")
+	discoverRepo := postgres.NewDiscoverRepository(db, newTestCursorSigner())
 	discoverService := discoverCore.NewDiscoverService(discoverRepo)
-	handler := discover.NewGetDiscoverHandler(discoverService, mockVotes, nil)
+	handler := discover.NewGetDiscoverHandler(discoverService, mockVotes, nil, nil, nil)
 
 	// Create request with authenticated user context
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getDiscover?sort=new&limit=50", nil)
@@ -434,9 +434,9 @@ func TestGetDiscover_NoViewerStateWithoutAuth(t *testing.T) {
 	mockVotes.AddVote("did:plc:someuser", postURI, "up", "at://did:plc:someuser/social.coves.vote/vote1")
 
 	// Setup handler with mock vote service
-	discoverRepo := postgres.NewDiscoverRepository(db, "test-cursor-secret")
+	discoverRepo := postgres.NewDiscoverRepository(db, newTestCursorSigner())
 	discoverService := discoverCore.NewDiscoverService(discoverRepo)
-	handler := discover.NewGetDiscoverHandler(discoverService, mockVotes, nil)
+	handler := discover.NewGetDiscoverHandler(discoverService, mockVotes, nil, nil, nil)
 
 	// Create request WITHOUT auth context
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getDiscover?sort=new&limit=50", nil)
@@ -0,0 +1,162 @@
+package integration
+
+import (
+	"Coves/internal/core/timeline"
+	"Coves/internal/db/postgres"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// seedKeysetTimeline creates a user subscribed to a fresh community
+func seedKeysetTimeline(t testing.TB, db *sql.DB, ctx context.Context, name string) (userDID, communityDID string) {
+	t.Helper()
+
+	testID := time.Now().UnixNano()
+	userDID = fmt.Sprintf("did:plc:keyset-user-%d", testID)
+	if _, err := db.ExecContext(ctx, `INSERT INTO users (did, handle, pds_url) VALUES ($1, $2, $3)`,
+		userDID, fmt.Sprintf("keyset-%d.test", testID), "https://bsky.social"); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	communityDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("%s-%d", name, testID), fmt.Sprintf("owner-%d.test", testID))
+	if err != nil {
+		t.Fatalf("Failed to create community: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO community_subscriptions (user_did, community_did, content_visibility)
+		VALUES ($1, $2, 3)
+	`, userDID, communityDID); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	return userDID, communityDID
+}
+
+func TestGetTimeline_KeysetFullWalk(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+	repo := postgres.NewTimelineRepository(db, newTestCursorSigner())
+
+	for _, sort := range []string{"new", "top", "hot"} {
+		t.Run(sort, func(t *testing.T) {
+			userDID, communityDID := seedKeysetTimeline(t, db, ctx, "keyset-"+sort)
+
+			// Ties on created_at and score, so only the uri separates many posts
+			base := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+			seeded := make(map[string]bool)
+			for i := 0; i < 40; i++ {
+				createdAt := base.Add(time.Duration(i/4) * time.Hour)
+				uri := createTestPost(t, db, communityDID, "did:plc:keysetauthor", fmt.Sprintf("Post %d", i), i%3, createdAt)
+				seeded[uri] = true
+			}
+
+			seen := make(map[string]bool)
+			var cursor *string
+			for page := 0; ; page++ {
+				if page > 20 {
+					t.Fatal("Walk did not terminate")
+				}
+				feed, next, err := repo.GetTimeline(ctx, timeline.GetTimelineRequest{
+					UserDID: userDID, Sort: sort, Timeframe: "all", Limit: 7, Cursor: cursor,
+				})
+				if err != nil {
+					t.Fatalf("Page %d failed: %v", page, err)
+				}
+				for _, item := range feed {
+					if seen[item.Post.URI] {
+						t.Errorf("Post %s returned twice (page %d)", item.Post.URI, page)
+					}
+					seen[item.Post.URI] = true
+				}
+
+				// New posts arriving between requests land ahead of the cursor, not in later pages
+				createTestPost(t, db, communityDID, "did:plc:keysetauthor", fmt.Sprintf("Late %d", page), 0, time.Now())
+
+				if next == nil {
+					break
+				}
+				cursor = next
+			}
+
+			for uri := range seeded {
+				if !seen[uri] {
+					t.Errorf("Post %s was skipped", uri)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkGetTimeline_KeysetDepth compares the first page against page 50 of a 200k post
+// timeline; with keyset cursors the two should cost about the same
+func BenchmarkGetTimeline_KeysetDepth(b *testing.B) {
+	const (
+		postCount = 200000
+		limit     = 25
+	)
+
+	ctx := context.Background()
+	db := setupTestDB(b)
+	b.Cleanup(func() { _ = db.Close() })
+	repo := postgres.NewTimelineRepository(db, newTestCursorSigner())
+	userDID, communityDID := seedKeysetTimeline(b, db, ctx, "keyset-bench")
+
+	authorDID := "did:plc:keysetbench"
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO users (did, handle, pds_url) VALUES ($1, $2, $3) ON CONFLICT (did) DO NOTHING
+	`, authorDID, "keysetbench.test", "https://bsky.social"); err != nil {
+		b.Fatalf("Failed to create author: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `
+		INSERT INTO posts (uri, cid, rkey, author_did, community_did, title, created_at, score, upvote_count)
+		SELECT $1 || '/social.coves.community.post/bench' || n, 'bafybench', 'bench' || n, $2, $1,
+			'Bench ' || n, NOW() - (n || ' seconds')::interval, n % 50, n % 50
+		FROM generate_series(1, $3) AS n
+	`, communityDID, authorDID, postCount); err != nil {
+		b.Fatalf("Failed to seed posts: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `ANALYZE posts`); err != nil {
+		b.Fatalf("Failed to analyze posts: %v", err)
+	}
+	b.Cleanup(func() {
+		_, _ = db.Exec(`DELETE FROM posts WHERE community_did = $1`, communityDID)
+	})
+
+	for _, sort := range []string{"new", "top"} {
+		// Walk to page 50 once, outside the timer
+		var deep *string
+		for page := 1; page < 50; page++ {
+			_, next, err := repo.GetTimeline(ctx, timeline.GetTimelineRequest{
+				UserDID: userDID, Sort: sort, Timeframe: "all", Limit: limit, Cursor: deep,
+			})
+			if err != nil || next == nil {
+				b.Fatalf("Failed to reach page %d: %v", page+1, err)
+			}
+			deep = next
+		}
+
+		for _, bc := range []struct {
+			name   string
+			cursor *string
+		}{
+			{name: sort + "/page1", cursor: nil},
+			{name: sort + "/page50", cursor: deep},
+		} {
+			b.Run(bc.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, _, err := repo.GetTimeline(ctx, timeline.GetTimelineRequest{
+						UserDID: userDID, Sort: sort, Timeframe: "all", Limit: limit, Cursor: bc.cursor,
+					}); err != nil {
+						b.Fatalf("GetTimeline failed: %v", err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -46,8 +46,8 @@ func TestGetCommunityFeed_Hot(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -58,7 +58,7 @@ func TestGetCommunityFeed_Hot(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Setup test data: community, users, and posts
 	ctx := context.Background()
@@ -125,8 +125,8 @@ func TestGetCommunityFeed_Top_WithTimeframe(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -137,7 +137,7 @@ func TestGetCommunityFeed_Top_WithTimeframe(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Setup test data
 	ctx := context.Background()
@@ -193,6 +193,85 @@ func TestGetCommunityFeed_Top_WithTimeframe(t *testing.T) {
 	})
 }
 
+// TestCommunityFeedRepo_TopTimeframes tests that top-day and top-week rank different time
+// buckets, that cursors can't cross timeframes, and the moderator-only minScore filter
+func TestCommunityFeedRepo_TopTimeframes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	ownerHandle := fmt.Sprintf("owner-%d.test", testID)
+	communityDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("buckets-%d", testID), ownerHandle)
+	require.NoError(t, err)
+
+	// One post per bucket; older posts score higher so a wider timeframe changes the leader
+	hourURI := createTestPost(t, db, communityDID, "did:plc:alice", "This hour", 5, time.Now().Add(-30*time.Minute))
+	dayURI := createTestPost(t, db, communityDID, "did:plc:alice", "Earlier today", 10, time.Now().Add(-5*time.Hour))
+	weekURI := createTestPost(t, db, communityDID, "did:plc:bob", "This week", 50, time.Now().Add(-3*24*time.Hour))
+	monthURI := createTestPost(t, db, communityDID, "did:plc:bob", "This month", 100, time.Now().Add(-20*24*time.Hour))
+
+	topURIs := func(timeframe string, minScore *int) []string {
+		feedPosts, _, err := feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+			Community: communityDID, Sort: "top", Timeframe: timeframe, MinScore: minScore, Limit: 10,
+		})
+		require.NoError(t, err)
+		uris := make([]string, 0, len(feedPosts))
+		for _, fp := range feedPosts {
+			uris = append(uris, fp.Post.URI)
+		}
+		return uris
+	}
+
+	assert.Equal(t, []string{dayURI, hourURI}, topURIs("day", nil))
+	assert.Equal(t, []string{weekURI, dayURI, hourURI}, topURIs("week", nil))
+	assert.Equal(t, []string{monthURI, weekURI, dayURI, hourURI}, topURIs("all", nil))
+
+	minScore := 10
+	assert.Equal(t, []string{weekURI, dayURI}, topURIs("week", &minScore))
+
+	t.Run("cursor is bound to its timeframe", func(t *testing.T) {
+		_, cursor, err := feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+			Community: communityDID, Sort: "top", Timeframe: "day", Limit: 1,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, cursor)
+
+		_, _, err = feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+			Community: communityDID, Sort: "top", Timeframe: "week", Limit: 1, Cursor: cursor,
+		})
+		assert.ErrorIs(t, err, communityFeeds.ErrInvalidCursor)
+	})
+
+	t.Run("minScore is for moderators only", func(t *testing.T) {
+		communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+		communityService := communities.NewCommunityServiceWithPDSFactory(
+			communityRepo, "http://localhost:3001", "did:web:test.coves.social", "test.coves.social", nil, nil, nil)
+		feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService,
+			communityFeeds.WithModeratorLookup(communityRepo))
+
+		req := communityFeeds.GetCommunityFeedRequest{Community: communityDID, Sort: "new", MinScore: &minScore}
+
+		_, err := feedService.GetCommunityFeed(ctx, req)
+		assert.ErrorIs(t, err, communityFeeds.ErrModeratorOnly, "anonymous viewers can't filter by score")
+
+		req.ViewerDID = "did:plc:alice"
+		_, err = feedService.GetCommunityFeed(ctx, req)
+		assert.ErrorIs(t, err, communityFeeds.ErrModeratorOnly, "members can't filter by score")
+
+		req.ViewerDID = "did:plc:" + ownerHandle
+		response, err := feedService.GetCommunityFeed(ctx, req)
+		require.NoError(t, err)
+		assert.Len(t, response.Feed, 3)
+	})
+}
+
 // TestGetCommunityFeed_New tests chronological sorting
 func TestGetCommunityFeed_New(t *testing.T) {
 	if testing.Short() {
@@ -203,8 +282,8 @@ func TestGetCommunityFeed_New(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -215,7 +294,7 @@ func TestGetCommunityFeed_New(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Setup test data
 	ctx := context.Background()
@@ -261,8 +340,8 @@ func TestGetCommunityFeed_Pagination(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -273,7 +352,7 @@ func TestGetCommunityFeed_Pagination(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Setup test data with many posts
 	ctx := context.Background()
@@ -354,8 +433,8 @@ func TestGetCommunityFeed_InvalidCommunity(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -366,7 +445,7 @@ func TestGetCommunityFeed_InvalidCommunity(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Request feed for non-existent community
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.communityFeed.getCommunity?community=did:plc:nonexistent&sort=hot&limit=10", nil)
@@ -392,8 +471,8 @@ func TestGetCommunityFeed_InvalidCursor(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -404,7 +483,7 @@ func TestGetCommunityFeed_InvalidCursor(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Setup test community
 	ctx := context.Background()
@@ -450,8 +529,8 @@ func TestGetCommunityFeed_EmptyFeed(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -462,7 +541,7 @@ func TestGetCommunityFeed_EmptyFeed(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Create community with no posts
 	ctx := context.Background()
@@ -496,8 +575,8 @@ func TestGetCommunityFeed_LimitValidation(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -508,7 +587,7 @@ func TestGetCommunityFeed_LimitValidation(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Setup test community
 	ctx := context.Background()
@@ -551,8 +630,8 @@ func TestGetCommunityFeed_HotPaginationBug(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -563,7 +642,7 @@ func TestGetCommunityFeed_HotPaginationBug(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Setup test data
 	ctx := context.Background()
@@ -654,8 +733,8 @@ func TestGetCommunityFeed_HotCursorPrecision(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -666,7 +745,7 @@ func TestGetCommunityFeed_HotCursorPrecision(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Setup test data
 	ctx := context.Background()
@@ -758,8 +837,8 @@ func TestGetCommunityFeed_HotCursorTimeDrift(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -770,7 +849,7 @@ func TestGetCommunityFeed_HotCursorTimeDrift(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Setup test data
 	ctx := context.Background()
@@ -861,8 +940,8 @@ func TestGetCommunityFeed_BlobURLTransformation(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	feedRepo := postgres.NewCommunityFeedRepository(db, "test-cursor-secret")
-	communityRepo := postgres.NewCommunityRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -873,7 +952,7 @@ func TestGetCommunityFeed_BlobURLTransformation(t *testing.T) {
 		nil,
 	)
 	feedService := communityFeeds.NewCommunityFeedService(feedRepo, communityService)
-	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
 
 	// Setup test data
 	ctx := context.Background()
@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"Coves/internal/core/communities"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/takedown"
+	"Coves/internal/db/postgres"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetPost_AvailabilityStates loads posts through the getPost service path and checks each
+// way a post can be unavailable reports its own error
+func TestGetPost_AvailabilityStates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	suffix := uniqueTestID()
+
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	communityService := communities.NewCommunityService(communityRepo, getTestPDSURL(), getTestInstanceDID(), "coves.social", nil, nil, nil)
+	takedownRepo := postgres.NewTakedownRepository(db)
+	postService := posts.NewPostService(postgres.NewPostRepository(db, newTestCursorSigner()), communityService, nil, nil, nil, nil, getTestPDSURL())
+	postService.(interface{ SetTakedowns(takedown.Checker) }).SetTakedowns(takedownRepo)
+
+	author := createTestUser(t, db, "getpost"+suffix+".test", "did:plc:getpost"+suffix)
+	communityDID, err := createFeedTestCommunity(db, ctx, "getpost"+suffix, "getpostowner"+suffix+".test")
+	require.NoError(t, err)
+
+	now := time.Now()
+	visible := createTestPost(t, db, communityDID, author.DID, "Visible", 4, now.Add(-time.Minute))
+
+	t.Run("visible post is hydrated", func(t *testing.T) {
+		post, err := postService.GetPost(ctx, posts.GetPostRequest{URI: visible})
+		require.NoError(t, err)
+		assert.Equal(t, visible, post.URI)
+		require.NotNil(t, post.Author)
+		assert.Equal(t, author.DID, post.Author.DID)
+		require.NotNil(t, post.Community)
+		assert.Equal(t, communityDID, post.Community.DID)
+		require.NotNil(t, post.Stats)
+		assert.Equal(t, 4, post.Stats.Score)
+	})
+
+	t.Run("unknown post", func(t *testing.T) {
+		_, err := postService.GetPost(ctx, posts.GetPostRequest{URI: visible + "missing"})
+		assert.ErrorIs(t, err, posts.ErrNotFound)
+	})
+
+	t.Run("deleted post", func(t *testing.T) {
+		deleted := createTestPost(t, db, communityDID, author.DID, "Deleted", 0, now)
+		_, err := db.ExecContext(ctx, `UPDATE posts SET deleted_at = NOW() WHERE uri = $1`, deleted)
+		require.NoError(t, err)
+
+		_, err = postService.GetPost(ctx, posts.GetPostRequest{URI: deleted})
+		assert.ErrorIs(t, err, posts.ErrNotFound)
+	})
+
+	t.Run("author_only post is visible to its author only", func(t *testing.T) {
+		hidden := createTestPost(t, db, communityDID, author.DID, "Shadowed", 0, now)
+		_, err := db.ExecContext(ctx, `UPDATE posts SET visibility_state = 'author_only' WHERE uri = $1`, hidden)
+		require.NoError(t, err)
+
+		_, err = postService.GetPost(ctx, posts.GetPostRequest{URI: hidden, ViewerDID: "did:plc:someoneelse"})
+		assert.ErrorIs(t, err, posts.ErrNotFound)
+		post, err := postService.GetPost(ctx, posts.GetPostRequest{URI: hidden, ViewerDID: author.DID})
+		require.NoError(t, err)
+		assert.Equal(t, hidden, post.URI)
+	})
+
+	t.Run("taken-down post", func(t *testing.T) {
+		down := createTestPost(t, db, communityDID, author.DID, "Taken down", 0, now)
+		_, err := takedownRepo.Takedown(ctx, &takedown.Takedown{
+			SubjectURI: down, Reason: takedown.ReasonCopyright, CreatedBy: "did:plc:admin",
+		})
+		require.NoError(t, err)
+
+		_, err = postService.GetPost(ctx, posts.GetPostRequest{URI: down, ViewerDID: author.DID})
+		var takenDown *takedown.TakenDownError
+		require.True(t, errors.As(err, &takenDown), "expected TakenDownError, got %v", err)
+		assert.Equal(t, takedown.ReasonCopyright, takenDown.Reason)
+	})
+
+	t.Run("community states", func(t *testing.T) {
+		tests := []struct {
+			want   error
+			name   string
+			update string
+		}{
+			{name: "suspended", update: `UPDATE communities SET suspended_at = NOW() WHERE did = $1`, want: posts.ErrCommunitySuspended},
+			{name: "deleted", update: `UPDATE communities SET deleted_at = NOW() WHERE did = $1`, want: posts.ErrCommunityDeleted},
+			{name: "federation-blocked", update: `UPDATE communities SET federation_blocked = TRUE WHERE did = $1`, want: posts.ErrCommunityFederationBlocked},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				did, err := createFeedTestCommunity(db, ctx, tt.name+suffix, tt.name+"owner"+suffix+".test")
+				require.NoError(t, err)
+				uri := createTestPost(t, db, did, author.DID, "In a "+tt.name+" community", 0, now)
+				_, err = db.ExecContext(ctx, tt.update, did)
+				require.NoError(t, err)
+
+				_, err = postService.GetPost(ctx, posts.GetPostRequest{URI: uri})
+				assert.ErrorIs(t, err, tt.want)
+			})
+		}
+	})
+}
@@ -5,8 +5,11 @@ import (
 	"Coves/internal/atproto/oauth"
 	"Coves/internal/atproto/pds"
 	"Coves/internal/core/communities"
+	"Coves/internal/core/hotrank"
 	"Coves/internal/core/users"
 	"Coves/internal/core/votes"
+	"Coves/internal/pagination"
+	"Coves/pkg/covesclient"
 	"bytes"
 	"context"
 	"database/sql"
@@ -41,6 +44,29 @@ func getTestInstanceDID() string {
 	return instanceDID
 }
 
+// testCursorSecret signs pagination cursors in integration tests
+const testCursorSecret = "test-cursor-secret"
+
+// newTestCursorSigner returns the cursor signer passed to feed and comment repositories
+func newTestCursorSigner() *pagination.Signer {
+	signer, err := pagination.NewSigner(testCursorSecret, "")
+	if err != nil {
+		panic(err)
+	}
+	return signer
+}
+
+// newTestCovesClient returns an unauthenticated API client for a test server.
+// Use WithToken to make authenticated calls.
+func newTestCovesClient(t *testing.T, baseURL string) *covesclient.Client {
+	t.Helper()
+	client, err := covesclient.New(baseURL, covesclient.WithMaxRetries(0))
+	if err != nil {
+		t.Fatalf("Failed to create API client: %v", err)
+	}
+	return client
+}
+
 // createTestUser creates a test user in the database for use in integration tests
 // Returns the created user or fails the test
 func createTestUser(t *testing.T, db *sql.DB, handle, did string) *users.User {
@@ -286,11 +312,11 @@ func createTestPost(t *testing.T, db *sql.DB, communityDID, authorDID, title str
 	rkey := fmt.Sprintf("post-%d", time.Now().UnixNano())
 	uri := fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, rkey)
 
-	// Insert post
+	// Insert post, with the hot score the vote consumer and ranker would maintain
 	_, err := db.ExecContext(ctx, `
-		INSERT INTO posts (uri, cid, rkey, author_did, community_did, title, created_at, score, upvote_count)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
-	`, uri, "bafytest", rkey, authorDID, communityDID, title, createdAt, score, score)
+		INSERT INTO posts (uri, cid, rkey, author_did, community_did, title, created_at, score, upvote_count, hot_score)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, uri, "bafytest", rkey, authorDID, communityDID, title, createdAt, score, score, hotrank.Score(score, createdAt, time.Now()))
 	if err != nil {
 		t.Fatalf("Failed to create test post: %v", err)
 	}
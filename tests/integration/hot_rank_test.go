@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/hotrank"
+	"Coves/internal/db/postgres"
+	"Coves/internal/db/postgres/ranking"
+	"context"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestHotRanker_MatchesReferenceFormula seeds posts across and beyond the ranking window, runs
+// the ranker, and checks hot_score against the reference formula and the live SQL ranking
+func TestHotRanker_MatchesReferenceFormula(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+	ctx := context.Background()
+
+	testID := uniqueTestID()
+	communityDID, err := createFeedTestCommunity(db, ctx, "hotrank"+testID, "hotrankowner"+testID+".test")
+	require.NoError(t, err)
+
+	now := time.Now().Truncate(time.Microsecond)
+	type seed struct {
+		age   time.Duration
+		score int
+	}
+	seeds := []seed{
+		{age: 10 * time.Minute, score: 0},
+		{age: 3 * time.Hour, score: 25},
+		{age: 20 * time.Hour, score: 300},
+		{age: 50 * time.Hour, score: -4},
+		{age: 6 * 24 * time.Hour, score: 1000},
+		{age: 8 * 24 * time.Hour, score: 5000}, // aged out
+	}
+	uris := make([]string, len(seeds))
+	for i, s := range seeds {
+		uris[i] = createTestPost(t, db, communityDID, "did:plc:hotrankauthor"+testID, fmt.Sprintf("Post %d", i), s.score, now.Add(-s.age))
+	}
+	// Stale scores the ranker must overwrite, including on the aged-out post
+	_, err = db.ExecContext(ctx, `UPDATE posts SET hot_score = 42 WHERE community_did = $1`, communityDID)
+	require.NoError(t, err)
+
+	rescored, err := hotrank.NewRanker(postgres.NewHotRankRepository(db)).Run(ctx, now)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, rescored, len(seeds)-1)
+
+	liveRank := ranking.HotRank("p", "$2::timestamptz")
+	for i, s := range seeds {
+		var stored, live float64
+		require.NoError(t, db.QueryRowContext(ctx,
+			fmt.Sprintf(`SELECT p.hot_score, %s FROM posts p WHERE p.uri = $1`, liveRank), uris[i], now,
+		).Scan(&stored, &live))
+
+		if s.age > hotrank.Window {
+			assert.Zero(t, stored, "aged out post %d", i)
+			continue
+		}
+		ageHours := s.age.Hours()
+		want := float64(s.score+1) / math.Pow(ageHours+2, 1.5)
+		assert.InDelta(t, want, stored, 1e-12, "post %d against the reference formula", i)
+		assert.InDelta(t, live, stored, 1e-12, "post %d against the live SQL formula", i)
+	}
+
+	// Both hot paths order the community feed the same way
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	hotOrder := func() []string {
+		feed, _, err := feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+			Community: communityDID, Sort: "hot", Limit: 50,
+		})
+		require.NoError(t, err)
+		order := make([]string, 0, len(feed))
+		for _, item := range feed {
+			order = append(order, item.Post.URI)
+		}
+		return order
+	}
+	precomputed := hotOrder()
+	ranking.SetLiveHotRank(true)
+	t.Cleanup(func() { ranking.SetLiveHotRank(false) })
+	live := hotOrder()
+	require.Len(t, precomputed, len(seeds))
+	// The aged-out post is last on both: the stored score and the live formula are 0 past the window
+	assert.Equal(t, live, precomputed)
+}
@@ -55,7 +55,7 @@ func TestImageProxy_E2E(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup repositories and services
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	// Setup identity resolver with local PLC
 	plcURL := os.Getenv("PLC_DIRECTORY_URL")
@@ -178,7 +178,7 @@ func TestImageProxy_E2E(t *testing.T) {
 
 		// Verify Cache-Control header
 		cacheControl := resp.Header.Get("Cache-Control")
-		expectedCacheControl := "public, max-age=31536000, immutable"
+		expectedCacheControl := "public, max-age=3600"
 		assert.Equal(t, expectedCacheControl, cacheControl, "Cache-Control header should be correct")
 
 		// Verify ETag header is present and matches expected format
@@ -390,7 +390,7 @@ func TestImageProxy_CacheHit(t *testing.T) {
 	ctx := context.Background()
 
 	// Setup repositories and services
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	// Setup identity resolver
 	plcURL := os.Getenv("PLC_DIRECTORY_URL")
@@ -521,7 +521,7 @@ func createImageProxyTestServerWithCache(t *testing.T, pdsURL string, identityRe
 
 	// Create router and register routes
 	r := chi.NewRouter()
-	routes.RegisterImageProxyRoutes(r, handler)
+	routes.RegisterImageProxyRoutes(routes.NewRegistrar(r, nil), handler)
 
 	return httptest.NewServer(r)
 }
@@ -582,7 +582,7 @@ func TestImageProxy_MockPDS(t *testing.T) {
 	handler := imageproxy.NewHandler(service, mockResolver)
 
 	r := chi.NewRouter()
-	routes.RegisterImageProxyRoutes(r, handler)
+	routes.RegisterImageProxyRoutes(routes.NewRegistrar(r, nil), handler)
 	testServer := httptest.NewServer(r)
 	defer testServer.Close()
 
@@ -674,7 +674,7 @@ func TestImageProxy_ErrorHandling(t *testing.T) {
 	handler := imageproxy.NewHandler(service, mockResolver)
 
 	r := chi.NewRouter()
-	routes.RegisterImageProxyRoutes(r, handler)
+	routes.RegisterImageProxyRoutes(routes.NewRegistrar(r, nil), handler)
 	testServer := httptest.NewServer(r)
 	defer testServer.Close()
 
@@ -697,7 +697,7 @@ func TestImageProxy_ErrorHandling(t *testing.T) {
 
 		errorHandler := imageproxy.NewHandler(service, errorResolver)
 		errorRouter := chi.NewRouter()
-		routes.RegisterImageProxyRoutes(errorRouter, errorHandler)
+		routes.RegisterImageProxyRoutes(routes.NewRegistrar(errorRouter, nil), errorHandler)
 		errorServer := httptest.NewServer(errorRouter)
 		defer errorServer.Close()
 
@@ -781,7 +781,7 @@ func TestImageProxy_UnsupportedFormat(t *testing.T) {
 	handler := imageproxy.NewHandler(service, mockResolver)
 
 	r := chi.NewRouter()
-	routes.RegisterImageProxyRoutes(r, handler)
+	routes.RegisterImageProxyRoutes(routes.NewRegistrar(r, nil), handler)
 	testServer := httptest.NewServer(r)
 	defer testServer.Close()
 
@@ -849,7 +849,7 @@ func TestImageProxy_LargeImage(t *testing.T) {
 	handler := imageproxy.NewHandler(service, mockResolver)
 
 	r := chi.NewRouter()
-	routes.RegisterImageProxyRoutes(r, handler)
+	routes.RegisterImageProxyRoutes(routes.NewRegistrar(r, nil), handler)
 	testServer := httptest.NewServer(r)
 	defer testServer.Close()
 
@@ -915,7 +915,7 @@ func TestImageProxy_ResponseJSON(t *testing.T) {
 	handler := imageproxy.NewHandler(service, mockResolver)
 
 	r := chi.NewRouter()
-	routes.RegisterImageProxyRoutes(r, handler)
+	routes.RegisterImageProxyRoutes(routes.NewRegistrar(r, nil), handler)
 	testServer := httptest.NewServer(r)
 	defer testServer.Close()
 
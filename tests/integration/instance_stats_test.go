@@ -0,0 +1,56 @@
+package integration
+
+import (
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestInstanceStats_CountsLocalContentAndRecentActivity(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewInstanceStatsRepository(db)
+	instanceDID := getTestInstanceDID()
+
+	before, err := repo.GetCounts(ctx, instanceDID, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to get counts: %v", err)
+	}
+
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	author := createTestUser(t, db, "statsauthor"+suffix+".test", generateTestDID("statsauthor"+suffix))
+	communityDID, err := createFeedTestCommunity(db, ctx, "stats"+suffix, "statsowner"+suffix)
+	if err != nil {
+		t.Fatalf("Failed to create community: %v", err)
+	}
+	createTestPost(t, db, communityDID, author.DID, "Fresh post", 0, time.Now().Add(-time.Hour))
+	createTestPost(t, db, communityDID, author.DID, "Older post", 0, time.Now().Add(-3*24*time.Hour))
+
+	after, err := repo.GetCounts(ctx, instanceDID, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to get counts: %v", err)
+	}
+
+	if got := after.Communities - before.Communities; got != 1 {
+		t.Errorf("Expected 1 more hosted community, got %d", got)
+	}
+	if got := after.LocalPosts - before.LocalPosts; got != 2 {
+		t.Errorf("Expected 2 more local posts, got %d", got)
+	}
+	if got := after.Posts.Day - before.Posts.Day; got != 1 {
+		t.Errorf("Expected 1 more post in the last day, got %d", got)
+	}
+	if got := after.Posts.Week - before.Posts.Week; got != 2 {
+		t.Errorf("Expected 2 more posts in the last week, got %d", got)
+	}
+	if after.ActiveUsers.Day < 1 || after.ActiveUsers.HalfYear < after.ActiveUsers.Month || after.ActiveUsers.Month < after.ActiveUsers.Week {
+		t.Errorf("Expected nested active user windows with the author counted, got %+v", after.ActiveUsers)
+	}
+	if after.Users <= before.Users {
+		t.Errorf("Expected more indexed users, got %d then %d", before.Users, after.Users)
+	}
+}
@@ -0,0 +1,210 @@
+package integration
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/db/postgres"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKeysetPagination_CommentListings pages through a thread while new comments arrive between
+// fetches. Comments share timestamps so the id tie-break is exercised too.
+func TestKeysetPagination_CommentListings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+
+	testID := uniqueTestID()
+	author := createTestUser(t, db, "keyset"+testID+".test", "did:plc:keyset"+testID)
+	communityDID, err := createFeedTestCommunity(db, ctx, "keyset"+testID, "keysetowner"+testID+".test")
+	require.NoError(t, err)
+	postURI := createTestPost(t, db, communityDID, author.DID, "keyset", 0, time.Now())
+
+	base := time.Now().UTC().Truncate(time.Second)
+	newComment := func(createdAt time.Time) string {
+		rkey := generateTID()
+		comment := &comments.Comment{
+			URI:          fmt.Sprintf("at://%s/social.coves.community.comment/%s", author.DID, rkey),
+			CID:          "bafy" + rkey,
+			RKey:         rkey,
+			CommenterDID: author.DID,
+			RootURI:      postURI,
+			RootCID:      "bafytest",
+			ParentURI:    postURI,
+			ParentCID:    "bafytest",
+			Content:      rkey,
+			Langs:        []string{},
+			CreatedAt:    createdAt,
+		}
+		require.NoError(t, commentRepo.Create(ctx, comment))
+		return comment.URI
+	}
+
+	var want []string
+	for i := 0; i < 7; i++ {
+		// Pairs of comments share a timestamp
+		want = append(want, newComment(base.Add(time.Duration(i/2)*time.Second)))
+	}
+
+	listings := map[string]func(cursor *string) ([]*comments.Comment, *string, error){
+		"ListByRootWithCursor": func(cursor *string) ([]*comments.Comment, *string, error) {
+			return commentRepo.ListByRootWithCursor(ctx, postURI, 2, cursor)
+		},
+		"ListByParentWithCursor": func(cursor *string) ([]*comments.Comment, *string, error) {
+			return commentRepo.ListByParentWithCursor(ctx, postURI, 2, cursor)
+		},
+	}
+
+	for name, list := range listings {
+		t.Run(name, func(t *testing.T) {
+			seen := make(map[string]bool)
+			var got []string
+			var cursor *string
+			for page := 0; ; page++ {
+				require.Less(t, page, 20, "pagination didn't terminate")
+				list, next, err := list(cursor)
+				require.NoError(t, err)
+				for _, c := range list {
+					require.False(t, seen[c.URI], "comment %s returned twice", c.URI)
+					seen[c.URI] = true
+					got = append(got, c.URI)
+				}
+				if next == nil {
+					break
+				}
+				cursor = next
+
+				// A reply arriving mid-pagination sorts after everything paged so far
+				want = append(want, newComment(base.Add(time.Hour+time.Duration(len(want))*time.Second)))
+			}
+			assert.Equal(t, want, got, "expected every comment exactly once, oldest first")
+		})
+	}
+
+	t.Run("rejects an invalid cursor", func(t *testing.T) {
+		bogus := "not-a-cursor"
+		_, _, err := commentRepo.ListByRootWithCursor(ctx, postURI, 2, &bogus)
+		assert.True(t, errors.Is(err, comments.ErrInvalidCursor), "expected ErrInvalidCursor, got %v", err)
+	})
+}
+
+// TestKeysetPagination_SubscriptionListings pages through a user's subscriptions and a
+// community's subscribers while new subscriptions arrive between fetches. New subscriptions
+// sort first, which with OFFSET shifted earlier rows onto the next page and returned them twice.
+func TestKeysetPagination_SubscriptionListings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+
+	testID := uniqueTestID()
+	userDID := "did:plc:keysetsubs" + testID
+	communityDIDs := make([]string, 8)
+	for i := range communityDIDs {
+		did, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("keysetsubs%d%s", i, testID), fmt.Sprintf("keysetsubsowner%d%s.test", i, testID))
+		require.NoError(t, err)
+		communityDIDs[i] = did
+	}
+
+	base := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	subscribe := func(userDID, communityDID string, subscribedAt time.Time) {
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO community_subscriptions (user_did, community_did, subscribed_at)
+			VALUES ($1, $2, $3)
+		`, userDID, communityDID, subscribedAt)
+		require.NoError(t, err)
+	}
+
+	// pageAll pages through a listing, subscribing via insert after every page, and returns
+	// the IDs it saw in order
+	pageAll := func(t *testing.T, list func(cursor *string) ([]*communities.Subscription, *string, error), insert func(page int)) []int {
+		seen := make(map[int]bool)
+		var got []int
+		var cursor *string
+		for page := 0; ; page++ {
+			require.Less(t, page, 20, "pagination didn't terminate")
+			subs, next, err := list(cursor)
+			require.NoError(t, err)
+			for _, sub := range subs {
+				require.False(t, seen[sub.ID], "subscription %d returned twice", sub.ID)
+				seen[sub.ID] = true
+				got = append(got, sub.ID)
+			}
+			if next == nil {
+				return got
+			}
+			cursor = next
+			insert(page)
+		}
+	}
+
+	t.Run("ListSubscriptions", func(t *testing.T) {
+		// The first five share two timestamps
+		for i := 0; i < 5; i++ {
+			subscribe(userDID, communityDIDs[i], base.Add(time.Duration(i/3)*time.Second))
+		}
+		want, _, err := communityRepo.ListSubscriptions(ctx, userDID, 100, nil)
+		require.NoError(t, err)
+		require.Len(t, want, 5)
+
+		got := pageAll(t, func(cursor *string) ([]*communities.Subscription, *string, error) {
+			return communityRepo.ListSubscriptions(ctx, userDID, 2, cursor)
+		}, func(page int) {
+			if 5+page < len(communityDIDs) {
+				subscribe(userDID, communityDIDs[5+page], time.Now())
+			}
+		})
+
+		wantIDs := make([]int, 0, len(want))
+		for _, sub := range want {
+			wantIDs = append(wantIDs, sub.ID)
+		}
+		assert.Equal(t, wantIDs, got, "expected every earlier subscription exactly once, newest first")
+	})
+
+	t.Run("ListSubscribers", func(t *testing.T) {
+		communityDID := communityDIDs[0]
+		for i := 0; i < 5; i++ {
+			subscribe(fmt.Sprintf("did:plc:keysetsubscriber%d%s", i, testID), communityDID, base.Add(time.Duration(i/3)*time.Second))
+		}
+		want, _, err := communityRepo.ListSubscribers(ctx, communityDID, 100, nil)
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, len(want), 5)
+
+		got := pageAll(t, func(cursor *string) ([]*communities.Subscription, *string, error) {
+			return communityRepo.ListSubscribers(ctx, communityDID, 2, cursor)
+		}, func(page int) {
+			subscribe(fmt.Sprintf("did:plc:keysetlate%d%s", page, testID), communityDID, time.Now())
+		})
+
+		wantIDs := make([]int, 0, len(want))
+		for _, sub := range want {
+			wantIDs = append(wantIDs, sub.ID)
+		}
+		assert.Equal(t, wantIDs, got, "expected every earlier subscriber exactly once, newest first")
+	})
+
+	t.Run("rejects an invalid cursor", func(t *testing.T) {
+		bogus := "not-a-cursor"
+		_, _, err := communityRepo.ListSubscribers(ctx, communityDIDs[0], 2, &bogus)
+		assert.True(t, errors.Is(err, communities.ErrInvalidCursor), "expected ErrInvalidCursor, got %v", err)
+	})
+}
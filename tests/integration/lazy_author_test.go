@@ -0,0 +1,165 @@
+package integration
+
+import (
+	"Coves/internal/atproto/identity"
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lazyAuthorProfileFetcher serves a Bluesky-style profile for every user
+type lazyAuthorProfileFetcher struct{}
+
+func (lazyAuthorProfileFetcher) FetchProfile(ctx context.Context, user *users.User) (map[string]interface{}, error) {
+	return map[string]interface{}{
+		"$type":       jetstream.BlueskyProfileCollection,
+		"displayName": "Display " + user.Handle,
+	}, nil
+}
+
+// TestPostConsumer_IndexesUnknownAuthors indexes a post and a comment by accounts the user
+// consumer never saw, then backfills their profiles
+func TestPostConsumer_IndexesUnknownAuthors(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	userRepo := postgres.NewUserRepository(db)
+	userService := users.NewUserService(userRepo, nil, getTestPDSURL())
+
+	// The stub resolver derives a handle from the DID, like a PLC lookup would return
+	resolver := &mockAggregatorIdentityResolver{
+		resolveFunc: func(ctx context.Context, did string) (*identity.Identity, error) {
+			return &identity.Identity{
+				DID:    did,
+				Handle: strings.TrimPrefix(did, "did:plc:") + ".lazy.test",
+				PDSURL: "https://pds.lazy.test",
+			}, nil
+		},
+	}
+	profiles := jetstream.NewProfileBackfiller(userRepo, lazyAuthorProfileFetcher{}, time.Hour)
+	authors := jetstream.NewAuthorIndexer(userService, resolver, profiles)
+
+	postConsumer := jetstream.NewPostEventConsumer(postRepo, communityRepo, userService, db)
+	commentConsumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+
+	testID := uniqueTestID()
+	communityDID, err := createFeedTestCommunity(db, ctx, "lazyauthor"+testID, "lazyowner"+testID+".test")
+	require.NoError(t, err)
+
+	postAuthor := "did:plc:lazypost" + testID
+	commenter := "did:plc:lazycomment" + testID
+	postRkey := generateTID()
+	postURI := fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, postRkey)
+	postEvent := &jetstream.JetstreamEvent{
+		Did:  communityDID,
+		Kind: "commit",
+		Commit: &jetstream.CommitEvent{
+			Rev:        "post-rev",
+			Operation:  "create",
+			Collection: "social.coves.community.post",
+			RKey:       postRkey,
+			CID:        "bafylazypost",
+			Record: map[string]interface{}{
+				"$type":     "social.coves.community.post",
+				"community": communityDID,
+				"author":    postAuthor,
+				"title":     "Post by an unknown author",
+				"createdAt": time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+
+	t.Run("rejects posts by unknown authors without an indexer", func(t *testing.T) {
+		err := postConsumer.HandleEvent(ctx, postEvent)
+		require.Error(t, err)
+		_, err = postRepo.GetByURI(ctx, postURI)
+		assert.Error(t, err)
+	})
+
+	postConsumer.SetAuthorIndexer(authors)
+	commentConsumer.SetAuthorIndexer(authors)
+
+	t.Run("indexes the post and a handle-only author", func(t *testing.T) {
+		require.NoError(t, postConsumer.HandleEvent(ctx, postEvent))
+
+		post, err := postRepo.GetByURI(ctx, postURI)
+		require.NoError(t, err)
+		assert.Equal(t, postAuthor, post.AuthorDID)
+
+		user, err := userRepo.GetByDID(ctx, postAuthor)
+		require.NoError(t, err)
+		assert.Equal(t, "lazypost"+testID+".lazy.test", user.Handle)
+		assert.Equal(t, "https://pds.lazy.test", user.PDSURL)
+		assert.Empty(t, user.DisplayName, "profile shouldn't be known before the backfill")
+	})
+
+	t.Run("indexes unknown commenters", func(t *testing.T) {
+		commentRkey := generateTID()
+		err := commentConsumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  commenter,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "comment-rev",
+				Operation:  "create",
+				Collection: "social.coves.community.comment",
+				RKey:       commentRkey,
+				CID:        "bafylazycomment",
+				Record: map[string]interface{}{
+					"$type":   "social.coves.community.comment",
+					"content": "Comment by an unknown author",
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": postURI, "cid": "bafylazypost"},
+						"parent": map[string]interface{}{"uri": postURI, "cid": "bafylazypost"},
+					},
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		user, err := userRepo.GetByDID(ctx, commenter)
+		require.NoError(t, err)
+		assert.Equal(t, "lazycomment"+testID+".lazy.test", user.Handle)
+	})
+
+	t.Run("backfills pending profiles", func(t *testing.T) {
+		pending, err := userRepo.ListPendingProfiles(ctx, 1000)
+		require.NoError(t, err)
+		pendingDIDs := make(map[string]bool)
+		for _, user := range pending {
+			pendingDIDs[user.DID] = true
+		}
+		require.True(t, pendingDIDs[postAuthor], "expected the post author's profile to be pending")
+		require.True(t, pendingDIDs[commenter], "expected the commenter's profile to be pending")
+
+		for _, did := range []string{postAuthor, commenter} {
+			user, err := userRepo.GetByDID(ctx, did)
+			require.NoError(t, err)
+			require.NoError(t, profiles.Backfill(ctx, user))
+
+			user, err = userRepo.GetByDID(ctx, did)
+			require.NoError(t, err)
+			assert.Equal(t, "Display "+user.Handle, user.DisplayName)
+
+			var fetched bool
+			require.NoError(t, db.QueryRowContext(ctx, `SELECT profile_fetched FROM users WHERE did = $1`, did).Scan(&fetched))
+			assert.True(t, fetched)
+		}
+	})
+}
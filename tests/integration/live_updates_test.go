@@ -0,0 +1,77 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/live"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLiveUpdates_CommentConsumerPublishesAfterIndexing(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	hub := live.NewHub(8)
+	consumer := jetstream.NewCommentEventConsumer(postgres.NewCommentRepository(db, newTestCursorSigner()), db)
+	consumer.SetPublisher(hub)
+
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	commenter := createTestUser(t, db, "live"+suffix+".test", generateTestDID("live"+suffix))
+	communityDID, err := createFeedTestCommunity(db, ctx, "live"+suffix, "liveowner"+suffix)
+	if err != nil {
+		t.Fatalf("Failed to create community: %v", err)
+	}
+	watched := createTestPost(t, db, communityDID, commenter.DID, "Watched", 0, time.Now())
+	other := createTestPost(t, db, communityDID, commenter.DID, "Other", 0, time.Now())
+
+	sub := hub.Subscribe(map[string]string{live.PostTopic(watched): live.PostTopic(watched)})
+	defer sub.Close()
+
+	comment := func(rootURI string) string {
+		rkey := generateTID()
+		event := &jetstream.JetstreamEvent{
+			Did:  commenter.DID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Operation:  "create",
+				Collection: "social.coves.community.comment",
+				RKey:       rkey,
+				CID:        "bafylive" + rkey,
+				Record: map[string]interface{}{
+					"$type":   "social.coves.community.comment",
+					"content": "live",
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": rootURI, "cid": "bafypost"},
+						"parent": map[string]interface{}{"uri": rootURI, "cid": "bafypost"},
+					},
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		}
+		if err := consumer.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("Failed to index comment: %v", err)
+		}
+		return fmt.Sprintf("at://%s/social.coves.community.comment/%s", commenter.DID, rkey)
+	}
+
+	comment(other)
+	uri := comment(watched)
+
+	select {
+	case event := <-sub.Events():
+		if event.Type != live.EventComment || event.URI != uri || event.ParentURI != watched {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	default:
+		t.Fatal("Expected an event for the watched post once the comment was indexed")
+	}
+	select {
+	case event := <-sub.Events():
+		t.Errorf("Comment on another post leaked: %+v", event)
+	default:
+	}
+}
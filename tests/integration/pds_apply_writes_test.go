@@ -0,0 +1,89 @@
+package integration
+
+import (
+	"Coves/internal/atproto/pds"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPDSClient_ApplyWrites runs batches of creates, updates and deletes against the dev PDS
+func TestPDSClient_ApplyWrites(t *testing.T) {
+	// Skip in short mode since this requires real PDS
+	if testing.Short() {
+		t.Skip("Skipping E2E test in short mode")
+	}
+
+	pdsURL := getTestPDSURL()
+	healthResp, err := http.Get(pdsURL + "/xrpc/_health")
+	if err != nil {
+		t.Skipf("PDS not running at %s: %v", pdsURL, err)
+	}
+	_ = healthResp.Body.Close()
+
+	ctx := context.Background()
+	handle := fmt.Sprintf("aw%d.local.coves.dev", time.Now().UnixNano()%1000000)
+	accessToken, userDID, err := createPDSAccount(pdsURL, handle, fmt.Sprintf("applywrites-%d@test.local", time.Now().UnixNano()), "test-password-123")
+	require.NoError(t, err)
+
+	client, err := pds.NewFromAccessToken(pdsURL, userDID, accessToken)
+	require.NoError(t, err)
+
+	const collection = "social.coves.feed.vote"
+	vote := func(direction string) map[string]any {
+		return map[string]any{
+			"$type":     collection,
+			"subject":   map[string]any{"uri": "at://did:plc:test/social.coves.community.post/3kjzl5kcb2s2v", "cid": "bafyreigbtj4x7ip5legnfznufuopl4sg4knzc2cof6duas4b3q2fy6swua"},
+			"direction": direction,
+			"createdAt": time.Now().UTC().Format(time.RFC3339),
+		}
+	}
+	first := syntax.NewTIDNow(0).String()
+	second := syntax.NewTIDNow(0).String()
+
+	results, err := client.ApplyWrites(ctx, userDID, []pds.WriteOp{
+		{Action: pds.WriteActionCreate, Collection: collection, RKey: first, Value: vote("up")},
+		{Action: pds.WriteActionCreate, Collection: collection, RKey: second, Value: vote("up")},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, fmt.Sprintf("at://%s/%s/%s", userDID, collection, first), results[0].URI)
+	assert.NotEmpty(t, results[1].CID)
+
+	// Replace the first vote and flip the second in one commit
+	third := syntax.NewTIDNow(0).String()
+	results, err = client.ApplyWrites(ctx, userDID, []pds.WriteOp{
+		{Action: pds.WriteActionDelete, Collection: collection, RKey: first},
+		{Action: pds.WriteActionCreate, Collection: collection, RKey: third, Value: vote("down")},
+		{Action: pds.WriteActionUpdate, Collection: collection, RKey: second, Value: vote("down")},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, pds.WriteActionDelete, results[0].Action)
+	assert.Empty(t, results[0].URI)
+
+	_, err = client.GetRecord(ctx, collection, first)
+	assert.True(t, errors.Is(err, pds.ErrNotFound), "deleted vote should be gone, got %v", err)
+	for _, rkey := range []string{second, third} {
+		record, getErr := client.GetRecord(ctx, collection, rkey)
+		require.NoError(t, getErr)
+		assert.Equal(t, "down", record.Value["direction"])
+	}
+
+	// Too many writes never reach the PDS
+	tooMany := make([]pds.WriteOp, pds.MaxApplyWrites+1)
+	for i := range tooMany {
+		tooMany[i] = pds.WriteOp{Action: pds.WriteActionCreate, Collection: collection, Value: vote("up")}
+	}
+	_, err = client.ApplyWrites(ctx, userDID, tooMany)
+	assert.True(t, errors.Is(err, pds.ErrBadRequest), "got %v", err)
+	assert.True(t, strings.Contains(err.Error(), "limit"), "got %v", err)
+}
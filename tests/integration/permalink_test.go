@@ -0,0 +1,140 @@
+package integration
+
+import (
+	"Coves/internal/atproto/aturi"
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/permalinks"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermalinks_ResolveIndexedSubjects(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+	service := permalinks.NewPermalinkService(postgres.NewPermalinkRepository(db))
+
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	commenter := createTestUser(t, db, "linker"+suffix+".test", generateTestDID("linker"+suffix))
+	communityDID, err := createFeedTestCommunity(db, ctx, "links"+suffix, "linkowner"+suffix)
+	require.NoError(t, err)
+	postURI := createTestPost(t, db, communityDID, commenter.DID, "Permalinked thread", 0, time.Now())
+	parsedPost, err := aturi.Parse(postURI)
+	require.NoError(t, err)
+	postRKey := parsedPost.RKey
+
+	commentRKey := generateTID()
+	commentURI := fmt.Sprintf("at://%s/social.coves.community.comment/%s", commenter.DID, commentRKey)
+	require.NoError(t, consumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+		Did:  commenter.DID,
+		Kind: "commit",
+		Commit: &jetstream.CommitEvent{
+			Operation:  "create",
+			Collection: "social.coves.community.comment",
+			RKey:       commentRKey,
+			CID:        "bafycomment",
+			Record: map[string]interface{}{
+				"$type":   "social.coves.community.comment",
+				"content": "Linking here",
+				"reply": map[string]interface{}{
+					"root":   map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+					"parent": map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+				},
+				"createdAt": time.Now().Format(time.RFC3339),
+			},
+		},
+	}))
+
+	var handle string
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT handle FROM communities WHERE did = $1`, communityDID).Scan(&handle))
+
+	t.Run("each subject type", func(t *testing.T) {
+		post, err := service.Resolve(ctx, postURI)
+		require.NoError(t, err)
+		assert.Equal(t, "/c/"+handle+"/post/"+postRKey, post.Path)
+		assert.Equal(t, permalinks.SubjectPost, post.Type)
+
+		comment, err := service.Resolve(ctx, commentURI)
+		require.NoError(t, err)
+		assert.Equal(t, "/c/"+handle+"/post/"+postRKey+"/comment/"+commentRKey, comment.Path)
+		assert.Equal(t, permalinks.SubjectComment, comment.Type)
+
+		community, err := service.Resolve(ctx, "at://"+communityDID)
+		require.NoError(t, err)
+		assert.Equal(t, "/c/"+handle, community.Path)
+	})
+
+	t.Run("renamed community uses its current handle", func(t *testing.T) {
+		renamed := "c-renamed" + suffix + ".coves.local"
+		_, err := db.ExecContext(ctx, `UPDATE communities SET handle = $1 WHERE did = $2`, renamed, communityDID)
+		require.NoError(t, err)
+
+		comment, err := service.Resolve(ctx, commentURI)
+		require.NoError(t, err)
+		assert.Equal(t, "/c/"+renamed+"/post/"+postRKey+"/comment/"+commentRKey, comment.Path)
+	})
+
+	t.Run("deleted subjects keep their path", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, `UPDATE comments SET deleted_at = NOW() WHERE uri = $1`, commentURI)
+		require.NoError(t, err)
+
+		comment, err := service.Resolve(ctx, commentURI)
+		require.NoError(t, err)
+		assert.True(t, comment.Deleted)
+		assert.Contains(t, comment.Path, "/comment/"+commentRKey)
+	})
+
+	t.Run("moderated subjects are reported as deleted", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, `UPDATE posts SET taken_down_at = NOW() WHERE uri = $1`, postURI)
+		require.NoError(t, err)
+		post, err := service.Resolve(ctx, postURI)
+		require.NoError(t, err)
+		assert.True(t, post.Deleted)
+
+		_, err = db.ExecContext(ctx, `UPDATE posts SET taken_down_at = NULL, visibility_state = 'removed' WHERE uri = $1`, postURI)
+		require.NoError(t, err)
+		post, err = service.Resolve(ctx, postURI)
+		require.NoError(t, err)
+		assert.True(t, post.Deleted)
+
+		_, err = db.ExecContext(ctx, `UPDATE posts SET visibility_state = 'visible' WHERE uri = $1`, postURI)
+		require.NoError(t, err)
+	})
+
+	t.Run("subjects of hidden communities are not found", func(t *testing.T) {
+		for _, hide := range []string{
+			`UPDATE communities SET suspended_at = NOW() WHERE did = $1`,
+			`UPDATE communities SET federation_blocked = TRUE WHERE did = $1`,
+			`UPDATE communities SET visibility = 'private' WHERE did = $1`,
+		} {
+			_, err := db.ExecContext(ctx, hide, communityDID)
+			require.NoError(t, err)
+			for _, uri := range []string{"at://" + communityDID, postURI, commentURI} {
+				_, err := service.Resolve(ctx, uri)
+				assert.ErrorIs(t, err, permalinks.ErrSubjectNotFound, "%s after %s", uri, hide)
+			}
+			_, err = db.ExecContext(ctx, `
+				UPDATE communities SET suspended_at = NULL, federation_blocked = FALSE, visibility = 'public'
+				WHERE did = $1`, communityDID)
+			require.NoError(t, err)
+		}
+
+		post, err := service.Resolve(ctx, postURI)
+		require.NoError(t, err)
+		assert.False(t, post.Deleted)
+	})
+
+	t.Run("unindexed subjects are not found", func(t *testing.T) {
+		_, err := service.Resolve(ctx, fmt.Sprintf("at://%s/social.coves.community.comment/%s", commenter.DID, generateTID()))
+		assert.ErrorIs(t, err, permalinks.ErrSubjectNotFound)
+	})
+}
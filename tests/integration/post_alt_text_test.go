@@ -0,0 +1,141 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostConsumer_ImageAltText indexes image posts with and without alt text in
+// communities that warn (default) and reject (postingRules.requireAltText)
+func TestPostConsumer_ImageAltText(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	userService := users.NewUserService(postgres.NewUserRepository(db), nil, getTestPDSURL())
+	consumer := jetstream.NewPostEventConsumer(postRepo, communityRepo, userService, db)
+
+	testID := uniqueTestID()
+	author := createTestUser(t, db, "alt"+testID+".test", "did:plc:alt"+testID)
+
+	warnDID, err := createFeedTestCommunity(db, ctx, "altwarn"+testID, "ownerwarn"+testID+".test")
+	require.NoError(t, err)
+	strictDID, err := createFeedTestCommunity(db, ctx, "altstrict"+testID, "ownerstrict"+testID+".test")
+	require.NoError(t, err)
+
+	strict, err := communityRepo.GetByDID(ctx, strictDID)
+	require.NoError(t, err)
+	strict.PostingRules = communities.PostingRules{RequireAltText: true}
+	_, err = communityRepo.Update(ctx, strict)
+	require.NoError(t, err)
+
+	image := func(cid, alt string) map[string]interface{} {
+		img := map[string]interface{}{
+			"image": map[string]interface{}{
+				"$type":    "blob",
+				"ref":      map[string]interface{}{"$link": cid},
+				"mimeType": "image/jpeg",
+				"size":     1000,
+			},
+			"aspectRatio": map[string]interface{}{"width": 4, "height": 3},
+		}
+		if alt != "" {
+			img["alt"] = alt
+		}
+		return img
+	}
+
+	indexPost := func(communityDID string, images ...interface{}) (string, error) {
+		rkey := generateTID()
+		err := consumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  communityDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev-" + rkey,
+				Operation:  "create",
+				Collection: "social.coves.community.post",
+				RKey:       rkey,
+				CID:        "bafy" + rkey,
+				Record: map[string]interface{}{
+					"$type":     "social.coves.community.post",
+					"community": communityDID,
+					"author":    author.DID,
+					"title":     "Gallery",
+					"embed": map[string]interface{}{
+						"$type":  "social.coves.embed.images",
+						"images": images,
+					},
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		})
+		return fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, rkey), err
+	}
+
+	storedImages := func(uri string) []interface{} {
+		post, err := postRepo.GetByURI(ctx, uri)
+		require.NoError(t, err)
+		require.NotNil(t, post.Embed)
+		var embed map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(*post.Embed), &embed))
+		images, ok := embed["images"].([]interface{})
+		require.True(t, ok)
+		return images
+	}
+
+	t.Run("warn mode indexes and counts posts missing alt", func(t *testing.T) {
+		before := consumer.PostsIndexedWithoutAltText()
+
+		uri, err := indexPost(warnDID, image("bafyone", " A sunset "), image("bafytwo", ""))
+		require.NoError(t, err)
+
+		images := storedImages(uri)
+		require.Len(t, images, 2)
+		assert.Equal(t, "A sunset", images[0].(map[string]interface{})["alt"])
+		assert.Equal(t, "", images[1].(map[string]interface{})["alt"])
+		assert.Equal(t, before+1, consumer.PostsIndexedWithoutAltText())
+	})
+
+	t.Run("posts with alt on every image are not counted", func(t *testing.T) {
+		before := consumer.PostsIndexedWithoutAltText()
+
+		_, err := indexPost(warnDID, image("bafythree", "A mountain"))
+		require.NoError(t, err)
+		assert.Equal(t, before, consumer.PostsIndexedWithoutAltText())
+	})
+
+	t.Run("reject mode refuses posts missing alt", func(t *testing.T) {
+		before := consumer.PostsIndexedWithoutAltText()
+
+		uri, err := indexPost(strictDID, image("bafyfour", "A lake"), image("bafyfive", "   "))
+		require.Error(t, err)
+		assert.True(t, posts.IsContentRuleViolation(err), "expected a content rule violation, got %v", err)
+
+		_, err = postRepo.GetByURI(ctx, uri)
+		assert.Error(t, err, "rejected post should not be indexed")
+		assert.Equal(t, before, consumer.PostsIndexedWithoutAltText())
+	})
+
+	t.Run("reject mode indexes posts with alt on every image", func(t *testing.T) {
+		uri, err := indexPost(strictDID, image("bafysix", "A forest"))
+		require.NoError(t, err)
+		assert.Len(t, storedImages(uri), 1)
+	})
+}
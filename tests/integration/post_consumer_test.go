@@ -27,9 +27,9 @@ func TestPostConsumer_CommentCountReconciliation(t *testing.T) {
 	ctx := context.Background()
 
 	// Set up repositories and consumers
-	postRepo := postgres.NewPostRepository(db)
-	commentRepo := postgres.NewCommentRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	userRepo := postgres.NewUserRepository(db)
 	userService := users.NewUserService(userRepo, nil, getTestPDSURL())
 
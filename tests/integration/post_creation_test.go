@@ -33,7 +33,7 @@ func TestPostCreation_Basic(t *testing.T) {
 	resolver := identity.NewResolver(db, identity.DefaultConfig())
 	userService := users.NewUserService(userRepo, resolver, "http://localhost:3001")
 
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	// Note: Provisioner not needed for this test (we're not actually creating communities)
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
@@ -45,7 +45,7 @@ func TestPostCreation_Basic(t *testing.T) {
 		nil, // blobService
 	)
 
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	postService := posts.NewPostService(postRepo, communityService, nil, nil, nil, nil, "http://localhost:3001") // nil aggregatorService, blobService, unfurlService, blueskyService for user-only tests
 
 	ctx := context.Background()
@@ -310,7 +310,7 @@ func TestPostRepository_Create(t *testing.T) {
 	// Setup: Create test user and community
 	ctx := context.Background()
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	testUserDID := generateTestDID("postauthor2")
 	_, err := userRepo.Create(ctx, &users.User{
@@ -332,7 +332,7 @@ func TestPostRepository_Create(t *testing.T) {
 	})
 	require.NoError(t, err)
 
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	t.Run("Insert post successfully", func(t *testing.T) {
 		content := "Test post content"
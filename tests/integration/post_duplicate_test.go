@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"Coves/internal/core/posts"
+	"Coves/internal/db/postgres"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostRepo_FindDuplicates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewPostRepository(db, newTestCursorSigner())
+	testID := uniqueTestID()
+
+	communityDID, err := createFeedTestCommunity(db, ctx, "dupcheck"+testID, "dupowner"+testID+".test")
+	require.NoError(t, err)
+	otherCommunityDID, err := createFeedTestCommunity(db, ctx, "dupother"+testID, "dupowner"+testID+".test")
+	require.NoError(t, err)
+	author := "did:plc:dupauthor" + testID
+
+	linkHash, err := posts.LinkHash("https://www.example.com/story?id=" + testID)
+	require.NoError(t, err)
+	setLink := func(uri string) {
+		_, err := db.ExecContext(ctx, `UPDATE posts SET normalized_url_hash = $1 WHERE uri = $2`, linkHash, uri)
+		require.NoError(t, err)
+	}
+
+	base := time.Now().Add(-72 * time.Hour)
+	linked := createTestPost(t, db, communityDID, author, "Completely different headline", 12, base)
+	setLink(linked)
+	similar := createTestPost(t, db, communityDID, author, "Rust 2.0 has been released today", 3, base.Add(time.Hour))
+	createTestPost(t, db, communityDID, author, "Gardening tips for the spring", 5, base)
+	elsewhere := createTestPost(t, db, otherCommunityDID, author, "Rust 2.0 has been released today", 40, base)
+	setLink(elsewhere)
+
+	find := func(t *testing.T, query posts.DuplicateQuery) []*posts.DuplicateMatch {
+		t.Helper()
+		query.MinSimilarity = posts.DuplicateTitleSimilarity
+		query.Limit = posts.MaxDuplicateMatches
+		matches, err := repo.FindDuplicates(ctx, query)
+		require.NoError(t, err)
+		return matches
+	}
+
+	t.Run("url match", func(t *testing.T) {
+		matches := find(t, posts.DuplicateQuery{CommunityDID: communityDID, LinkHash: linkHash})
+		require.Len(t, matches, 1)
+		assert.Equal(t, linked, matches[0].URI)
+		assert.Equal(t, posts.DuplicateMatchURL, matches[0].Match)
+		require.NotNil(t, matches[0].Score)
+		assert.Equal(t, 12, *matches[0].Score)
+		assert.WithinDuration(t, base, matches[0].CreatedAt, time.Second)
+	})
+
+	t.Run("title-only fuzzy match", func(t *testing.T) {
+		matches := find(t, posts.DuplicateQuery{CommunityDID: communityDID, Title: "Rust 2.0 released today"})
+		require.Len(t, matches, 1)
+		assert.Equal(t, similar, matches[0].URI)
+		assert.Equal(t, posts.DuplicateMatchTitle, matches[0].Match)
+		assert.GreaterOrEqual(t, matches[0].Similarity, posts.DuplicateTitleSimilarity)
+	})
+
+	t.Run("link matches come before title matches", func(t *testing.T) {
+		matches := find(t, posts.DuplicateQuery{CommunityDID: communityDID, LinkHash: linkHash, Title: "Rust 2.0 has been released today"})
+		require.Len(t, matches, 2)
+		assert.Equal(t, linked, matches[0].URI)
+		assert.Equal(t, similar, matches[1].URI)
+	})
+
+	t.Run("unrelated title doesn't match", func(t *testing.T) {
+		assert.Empty(t, find(t, posts.DuplicateQuery{CommunityDID: communityDID, Title: "Weekly knitting thread"}))
+	})
+
+	t.Run("other communities' posts don't match", func(t *testing.T) {
+		emptyDID, err := createFeedTestCommunity(db, ctx, "dupempty"+testID, "dupowner"+testID+".test")
+		require.NoError(t, err)
+		assert.Empty(t, find(t, posts.DuplicateQuery{CommunityDID: emptyDID, LinkHash: linkHash, Title: "Rust 2.0 has been released today"}))
+
+		for _, m := range find(t, posts.DuplicateQuery{CommunityDID: communityDID, LinkHash: linkHash, Title: "Rust 2.0 has been released today"}) {
+			assert.NotEqual(t, elsewhere, m.URI)
+		}
+	})
+}
@@ -56,8 +56,8 @@ func TestPostCreation_E2E_WithJetstream(t *testing.T) {
 
 	// Setup repositories
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	// Setup user service for post consumer
 	identityConfig := identity.DefaultConfig()
@@ -387,8 +387,8 @@ func TestPostCreation_E2E_LivePDS(t *testing.T) {
 	}
 
 	// Setup repositories and services
-	communityRepo := postgres.NewCommunityRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	// Setup PDS account provisioner for community creation
 	provisioner := communities.NewPDSAccountProvisioner(instanceDomain, pdsURL)
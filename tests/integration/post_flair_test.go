@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"Coves/internal/api/handlers/communityFeed"
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostFlairs_TagValidationAndFeedFilter indexes tagged posts through the consumer,
+// filters the community feed by flair, and checks that removing a flair stops it filtering
+// while posts keep their stored tags
+func TestPostFlairs_TagValidationAndFeedFilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	userService := users.NewUserService(postgres.NewUserRepository(db), nil, getTestPDSURL())
+	postConsumer := jetstream.NewPostEventConsumer(postRepo, communityRepo, userService, db)
+
+	communityService := communities.NewCommunityServiceWithPDSFactory(
+		communityRepo,
+		"http://localhost:3001",
+		"did:web:test.coves.social",
+		"test.coves.social",
+		nil,
+		nil,
+		nil,
+	)
+	feedService := communityFeeds.NewCommunityFeedService(postgres.NewCommunityFeedRepository(db, newTestCursorSigner()), communityService)
+	handler := communityFeed.NewGetCommunityHandler(feedService, nil, nil, nil, nil)
+
+	testID := uniqueTestID()
+	author := createTestUser(t, db, "flair"+testID+".test", "did:plc:flair"+testID)
+	communityDID, err := createFeedTestCommunity(db, ctx, "flaircomm"+testID, "ownerflair"+testID+".test")
+	require.NoError(t, err)
+
+	community, err := communityRepo.GetByDID(ctx, communityDID)
+	require.NoError(t, err)
+	community.Flairs = []communities.Flair{
+		{Name: "News", Color: "#1e90ff"},
+		{Name: "Discussion", Color: "#00ff00"},
+	}
+	_, err = communityRepo.Update(ctx, community)
+	require.NoError(t, err)
+
+	indexPost := func(title string, tags []interface{}) string {
+		rkey := generateTID()
+		record := map[string]interface{}{
+			"$type":     "social.coves.community.post",
+			"community": communityDID,
+			"author":    author.DID,
+			"title":     title,
+			"createdAt": time.Now().Format(time.RFC3339),
+		}
+		if tags != nil {
+			record["tags"] = tags
+		}
+		err := postConsumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  communityDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev-" + rkey,
+				Operation:  "create",
+				Collection: "social.coves.community.post",
+				RKey:       rkey,
+				CID:        "bafy" + rkey,
+				Record:     record,
+			},
+		})
+		require.NoError(t, err)
+		return fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, rkey)
+	}
+
+	newsURI := indexPost("Tagged news", []interface{}{"News", "Memes", "News"})
+	discussionURI := indexPost("Tagged discussion", []interface{}{"Discussion"})
+	untaggedURI := indexPost("Untagged", nil)
+
+	getFeed := func(query string) (int, *communityFeeds.FeedResponse) {
+		req := httptest.NewRequest(http.MethodGet,
+			fmt.Sprintf("/xrpc/social.coves.communityFeed.getCommunity?community=%s&sort=new&limit=10%s", communityDID, query), nil)
+		rec := httptest.NewRecorder()
+		handler.HandleGetCommunity(rec, req)
+		if rec.Code != http.StatusOK {
+			return rec.Code, nil
+		}
+		var resp communityFeeds.FeedResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		return rec.Code, &resp
+	}
+
+	feedURIs := func(resp *communityFeeds.FeedResponse) []string {
+		uris := make([]string, 0, len(resp.Feed))
+		for _, item := range resp.Feed {
+			uris = append(uris, item.Post.URI)
+		}
+		return uris
+	}
+
+	t.Run("unknown and duplicate tags are dropped at indexing", func(t *testing.T) {
+		post, err := postRepo.GetByURI(ctx, newsURI)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"News"}, post.Tags)
+
+		untagged, err := postRepo.GetByURI(ctx, untaggedURI)
+		require.NoError(t, err)
+		assert.Empty(t, untagged.Tags)
+	})
+
+	t.Run("feed filters by tag", func(t *testing.T) {
+		code, resp := getFeed("&tag=News")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{newsURI}, feedURIs(resp))
+
+		record, ok := resp.Feed[0].Post.Record.(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, []interface{}{"News"}, record["tags"])
+
+		code, resp = getFeed("")
+		require.Equal(t, http.StatusOK, code)
+		assert.ElementsMatch(t, []string{newsURI, discussionURI, untaggedURI}, feedURIs(resp))
+	})
+
+	t.Run("tag that is not a flair matches nothing", func(t *testing.T) {
+		code, resp := getFeed("&tag=Memes")
+		require.Equal(t, http.StatusOK, code)
+		assert.Empty(t, resp.Feed)
+	})
+
+	t.Run("removed flair stops filtering but posts keep the tag", func(t *testing.T) {
+		community, err := communityRepo.GetByDID(ctx, communityDID)
+		require.NoError(t, err)
+		community.Flairs = []communities.Flair{{Name: "Discussion", Color: "#00ff00"}}
+		_, err = communityRepo.Update(ctx, community)
+		require.NoError(t, err)
+
+		post, err := postRepo.GetByURI(ctx, newsURI)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"News"}, post.Tags)
+
+		code, resp := getFeed("&tag=News")
+		require.Equal(t, http.StatusOK, code)
+		assert.Empty(t, resp.Feed)
+
+		code, resp = getFeed("&tag=Discussion")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{discussionURI}, feedURIs(resp))
+	})
+}
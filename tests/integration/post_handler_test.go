@@ -31,7 +31,7 @@ func TestPostHandler_SecurityValidation(t *testing.T) {
 	}()
 
 	// Setup services
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -42,7 +42,7 @@ func TestPostHandler_SecurityValidation(t *testing.T) {
 		nil,
 	)
 
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	postService := posts.NewPostService(postRepo, communityService, nil, nil, nil, nil, "http://localhost:3001") // nil optional services
 
 	// Create handler
@@ -401,7 +401,7 @@ func TestPostHandler_SpecialCharacters(t *testing.T) {
 	}()
 
 	// Setup services
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -412,7 +412,7 @@ func TestPostHandler_SpecialCharacters(t *testing.T) {
 		nil,
 	)
 
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	postService := posts.NewPostService(postRepo, communityService, nil, nil, nil, nil, "http://localhost:3001") // nil optional services
 
 	handler := post.NewCreateHandler(postService)
@@ -487,7 +487,7 @@ func TestPostService_DIDValidationSecurity(t *testing.T) {
 	}()
 
 	// Setup services
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -498,7 +498,7 @@ func TestPostService_DIDValidationSecurity(t *testing.T) {
 		nil,
 	)
 
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	postService := posts.NewPostService(postRepo, communityService, nil, nil, nil, nil, "http://localhost:3001")
 
 	t.Run("Reject posts when context DID is missing", func(t *testing.T) {
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"Coves/internal/api/handlers/discover"
+	discoverCore "Coves/internal/core/discover"
+	"Coves/internal/core/moderation"
+	"Coves/internal/db/postgres"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetDiscover_EffectiveLabels checks the includeNsfw filter and labels view against
+// community warnings, self-labels and moderator overrides
+func TestGetDiscover_EffectiveLabels(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	handler := discover.NewGetDiscoverHandler(
+		discoverCore.NewDiscoverService(postgres.NewDiscoverRepository(db, newTestCursorSigner())), nil, nil, nil, nil)
+	moderationService := moderation.NewModerationService(postgres.NewModerationRepository(db))
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+
+	safeDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("safe-%d", testID), fmt.Sprintf("alice-%d.test", testID))
+	require.NoError(t, err)
+	adultDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("adult-%d", testID), fmt.Sprintf("bob-%d.test", testID))
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE communities SET content_warnings = ARRAY['nsfw'] WHERE did = $1`, adultDID)
+	require.NoError(t, err)
+
+	plainURI := createTestPost(t, db, safeDID, "did:plc:alice", "Plain", 1, time.Now())
+	spoilerURI := createTestPost(t, db, safeDID, "did:plc:alice", "Spoiler", 1, time.Now())
+	selfNSFWURI := createTestPost(t, db, safeDID, "did:plc:alice", "Self-labeled", 1, time.Now())
+	inheritedURI := createTestPost(t, db, adultDID, "did:plc:bob", "Inherited", 1, time.Now())
+	clearedURI := createTestPost(t, db, adultDID, "did:plc:bob", "Cleared by a moderator", 1, time.Now())
+	appliedURI := createTestPost(t, db, safeDID, "did:plc:alice", "Labeled by a moderator", 1, time.Now())
+
+	_, err = db.ExecContext(ctx, `UPDATE posts SET labels = ARRAY['spoiler'] WHERE uri = $1`, spoilerURI)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE posts SET labels = ARRAY['nsfw'] WHERE uri = $1`, selfNSFWURI)
+	require.NoError(t, err)
+	require.NoError(t, moderationService.SetPostLabel(ctx, moderation.SetPostLabelRequest{
+		Subject: clearedURI, Label: "nsfw", State: moderation.LabelRemoved, ActorDID: "did:plc:admin"}))
+	require.NoError(t, moderationService.SetPostLabel(ctx, moderation.SetPostLabelRequest{
+		Subject: appliedURI, Label: "gore", State: moderation.LabelApplied, ActorDID: "did:plc:admin"}))
+
+	fetch := func(query string) map[string][]string {
+		req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getDiscover?sort=new&limit=100"+query, nil)
+		rec := httptest.NewRecorder()
+		handler.HandleGetDiscover(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response discoverCore.DiscoverResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		labels := make(map[string][]string)
+		for _, item := range response.Feed {
+			labels[item.Post.URI] = []string{}
+			if item.Post.Labels != nil {
+				labels[item.Post.URI] = item.Post.Labels.Values
+			}
+		}
+		return labels
+	}
+
+	// Default: adult-labeled posts are left out, however they got the label
+	hidden := fetch("")
+	assert.Contains(t, hidden, plainURI)
+	assert.Equal(t, []string{"spoiler"}, hidden[spoilerURI])
+	assert.Equal(t, []string{}, hidden[clearedURI], "moderator removal beats the community warning")
+	assert.NotContains(t, hidden, selfNSFWURI)
+	assert.NotContains(t, hidden, inheritedURI)
+	assert.NotContains(t, hidden, appliedURI)
+
+	// includeNsfw=true: everything, with effective labels
+	all := fetch("&includeNsfw=true")
+	assert.Equal(t, []string{"nsfw"}, all[selfNSFWURI])
+	assert.Equal(t, []string{"nsfw"}, all[inheritedURI])
+	assert.Equal(t, []string{"gore"}, all[appliedURI])
+}
@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/spamguard"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPostConsumer_SpamBurstQuarantined simulates a fresh account posting the same link to
+// many communities within a minute, then reviews the quarantined posts as an admin
+func TestPostConsumer_SpamBurstQuarantined(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	userService := users.NewUserService(postgres.NewUserRepository(db), nil, getTestPDSURL())
+	consumer := jetstream.NewPostEventConsumer(postRepo, communityRepo, userService, db)
+	consumer.SetSpamGuard(spamguard.NewGuard(postgres.NewSpamGuardRepository(db), spamguard.DefaultConfig()))
+	queue := postgres.NewSpamQueueRepository(db)
+
+	testID := uniqueTestID()
+	spammer := createTestUser(t, db, "spam"+testID+".test", "did:plc:spam"+testID)
+	veteran := createTestUser(t, db, "veteran"+testID+".test", "did:plc:veteran"+testID)
+	_, err := db.ExecContext(ctx, `UPDATE users SET created_at = NOW() - INTERVAL '1 year' WHERE did = $1`, veteran.DID)
+	require.NoError(t, err)
+
+	const burst = spamguard.DefaultDuplicateThreshold + 3
+	communityDIDs := make([]string, burst)
+	for i := range communityDIDs {
+		communityDIDs[i], err = createFeedTestCommunity(db, ctx, fmt.Sprintf("spam%d%s", i, testID), fmt.Sprintf("spamowner%d%s.test", i, testID))
+		require.NoError(t, err)
+	}
+
+	link := "https://deals.example/" + testID
+	indexPost := func(communityDID, authorDID string) string {
+		rkey := generateTID()
+		err := consumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  communityDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev-" + rkey,
+				Operation:  "create",
+				Collection: "social.coves.community.post",
+				RKey:       rkey,
+				CID:        "bafy" + rkey,
+				Record: map[string]interface{}{
+					"$type":     "social.coves.community.post",
+					"community": communityDID,
+					"author":    authorDID,
+					"title":     "Amazing deal",
+					"embed": map[string]interface{}{
+						"$type":    "social.coves.embed.external",
+						"external": map[string]interface{}{"uri": link},
+					},
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		})
+		require.NoError(t, err)
+		return fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, rkey)
+	}
+
+	status := func(uri string) (string, string) {
+		var postStatus, visibility string
+		require.NoError(t, db.QueryRowContext(ctx,
+			`SELECT status, visibility_state FROM posts WHERE uri = $1`, uri).Scan(&postStatus, &visibility))
+		return postStatus, visibility
+	}
+
+	before := consumer.PostsQuarantined()
+	uris := make([]string, burst)
+	for i, communityDID := range communityDIDs {
+		uris[i] = indexPost(communityDID, spammer.DID)
+	}
+
+	// A new account posting a bare link: account age and link-only alone stay under the score;
+	// once the link has been posted to more communities than the threshold it's quarantined
+	for i, uri := range uris {
+		postStatus, visibility := status(uri)
+		if i < spamguard.DefaultDuplicateThreshold {
+			assert.Equal(t, "active", postStatus, "post %d", i)
+			assert.Equal(t, "visible", visibility, "post %d", i)
+		} else {
+			assert.Equal(t, "quarantined", postStatus, "post %d", i)
+			assert.Equal(t, "removed", visibility, "post %d", i)
+		}
+	}
+	quarantinedCount := int64(burst - spamguard.DefaultDuplicateThreshold)
+	assert.Equal(t, before+quarantinedCount, consumer.PostsQuarantined())
+
+	// Established accounts posting the same link aren't screened
+	veteranURI := indexPost(communityDIDs[0], veteran.DID)
+	postStatus, _ := status(veteranURI)
+	assert.Equal(t, "active", postStatus)
+
+	quarantined, err := queue.ListQuarantined(ctx, 100, 0)
+	require.NoError(t, err)
+	queued := make(map[string][]string)
+	for _, post := range quarantined {
+		queued[post.URI] = post.Reasons
+	}
+	last := uris[burst-1]
+	require.Contains(t, queued, last)
+	assert.Contains(t, queued[last], spamguard.ReasonRepeatedContent)
+	assert.Contains(t, queued[last], spamguard.ReasonNewAccount)
+
+	// Admin review: release one, confirm another
+	released, confirmed := uris[burst-1], uris[burst-2]
+	require.NoError(t, queue.Release(ctx, released))
+	require.NoError(t, queue.Confirm(ctx, confirmed))
+
+	postStatus, visibility := status(released)
+	assert.Equal(t, "active", postStatus)
+	assert.Equal(t, "visible", visibility)
+	postStatus, visibility = status(confirmed)
+	assert.Equal(t, "spam", postStatus)
+	assert.Equal(t, "removed", visibility)
+
+	assert.ErrorIs(t, queue.Release(ctx, released), spamguard.ErrPostNotQuarantined)
+}
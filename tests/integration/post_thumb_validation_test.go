@@ -54,7 +54,7 @@ func TestPostHandler_ThumbValidation(t *testing.T) {
 	}()
 
 	// Setup services
-	communityRepo := postgres.NewCommunityRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 	communityService := communities.NewCommunityServiceWithPDSFactory(
 		communityRepo,
 		"http://localhost:3001",
@@ -65,7 +65,7 @@ func TestPostHandler_ThumbValidation(t *testing.T) {
 		nil,
 	)
 
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	// No blobService or unfurlService for these validation tests
 	postService := posts.NewPostService(postRepo, communityService, nil, nil, nil, nil, "http://localhost:3001")
 
@@ -36,8 +36,8 @@ func TestPostUnfurl_Streamable(t *testing.T) {
 
 	// Setup repositories and services
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	unfurlRepo := unfurl.NewRepository(db)
 
 	// Setup identity resolver and services
@@ -343,8 +343,8 @@ func TestPostUnfurl_UnsupportedURL(t *testing.T) {
 
 	// Setup services
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	identityConfig := identity.DefaultConfig()
 	identityResolver := identity.NewResolver(db, identityConfig)
@@ -447,8 +447,8 @@ func TestPostUnfurl_UserProvidedMetadata(t *testing.T) {
 
 	// Setup
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	unfurlRepo := unfurl.NewRepository(db)
 
 	identityConfig := identity.DefaultConfig()
@@ -562,8 +562,8 @@ func TestPostUnfurl_MissingEmbedType(t *testing.T) {
 
 	// Setup
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	unfurlRepo := unfurl.NewRepository(db)
 
 	identityConfig := identity.DefaultConfig()
@@ -858,8 +858,8 @@ func TestPostUnfurl_E2E_WithJetstream(t *testing.T) {
 
 	// Setup repositories
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 	unfurlRepo := unfurl.NewRepository(db)
 
 	// Setup services
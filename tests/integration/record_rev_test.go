@@ -0,0 +1,215 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revAt returns the repo rev a commit made at base+offset would carry
+func revAt(base time.Time, offset time.Duration) string {
+	return syntax.NewTIDFromTime(base.Add(offset), 0).String()
+}
+
+func revProfileEvent(did, operation, rev, cid string, record map[string]interface{}) *jetstream.JetstreamEvent {
+	return &jetstream.JetstreamEvent{
+		Did:    did,
+		TimeUS: time.Now().UnixMicro(),
+		Kind:   "commit",
+		Commit: &jetstream.CommitEvent{
+			Rev:        rev,
+			Operation:  operation,
+			Collection: "social.coves.community.profile",
+			RKey:       "self",
+			CID:        cid,
+			Record:     record,
+		},
+	}
+}
+
+func TestCommunityConsumer_ProfileUpdatesByRev(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	ctx := context.Background()
+	base := time.Now()
+
+	newCommunity := func(t *testing.T, prefix string) (string, *jetstream.CommunityEventConsumer, func(displayName string, extra map[string]interface{}) map[string]interface{}) {
+		t.Helper()
+		suffix := uniqueTestID()
+		did := generateTestDID(suffix)
+		name := fmt.Sprintf("%s-%s", prefix, suffix)
+		resolver := newMockIdentityResolver()
+		resolver.resolutions[did] = fmt.Sprintf("c-%s.coves.local", name)
+		consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.local", true, resolver)
+
+		record := func(displayName string, extra map[string]interface{}) map[string]interface{} {
+			r := map[string]interface{}{
+				"name":        name,
+				"displayName": displayName,
+				"owner":       "did:web:coves.local",
+				"createdBy":   "did:plc:user123",
+				"hostedBy":    "did:web:coves.local",
+				"visibility":  "public",
+				"federation":  map[string]interface{}{"allowExternalDiscovery": true},
+				"createdAt":   base.Format(time.RFC3339),
+			}
+			for k, v := range extra {
+				r[k] = v
+			}
+			return r
+		}
+		return did, consumer, record
+	}
+
+	t.Run("out-of-order updates never replace a newer profile", func(t *testing.T) {
+		did, consumer, record := newCommunity(t, "rev-order")
+
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "create", revAt(base, 0), "bafycreate", record("First", nil))))
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "update", revAt(base, 2*time.Second), "bafynewest", record("Newest", nil))))
+
+		// The update committed in between arrives last
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "update", revAt(base, time.Second), "bafymiddle", record("Middle", nil))))
+
+		community, err := repo.GetByDID(ctx, did)
+		require.NoError(t, err)
+		assert.Equal(t, "Newest", community.DisplayName)
+		assert.Equal(t, "bafynewest", community.RecordCID)
+		assert.Equal(t, revAt(base, 2*time.Second), community.RecordRev)
+
+		// Redelivering the indexed commit is a no-op, even without a rev
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "update", "", "bafynewest", record("Replayed", nil))))
+		community, err = repo.GetByDID(ctx, did)
+		require.NoError(t, err)
+		assert.Equal(t, "Newest", community.DisplayName)
+	})
+
+	t.Run("update clears optional fields the record omits", func(t *testing.T) {
+		did, consumer, record := newCommunity(t, "rev-clear")
+
+		blob := func(cid string) map[string]interface{} {
+			return map[string]interface{}{
+				"$type":    "blob",
+				"ref":      map[string]interface{}{"$link": cid},
+				"mimeType": "image/png",
+				"size":     1024,
+			}
+		}
+		full := record("Full", map[string]interface{}{
+			"description": "Has everything",
+			"avatar":      blob("bafkreiavatar"),
+			"banner":      blob("bafkreibanner"),
+			"descriptionFacets": []interface{}{
+				map[string]interface{}{
+					"index":    map[string]interface{}{"byteStart": 0, "byteEnd": 3},
+					"features": []interface{}{map[string]interface{}{"$type": "social.coves.richtext.facet#link", "uri": "https://example.com"}},
+				},
+			},
+		})
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "create", revAt(base, 0), "bafyfull", full)))
+
+		community, err := repo.GetByDID(ctx, did)
+		require.NoError(t, err)
+		require.Equal(t, "bafkreiavatar", community.AvatarCID)
+		require.Equal(t, "bafkreibanner", community.BannerCID)
+		require.NotEmpty(t, community.DescriptionFacets)
+
+		// The profile was rewritten without a description, avatar, banner or facets
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "update", revAt(base, time.Second), "bafybare", record("Bare", nil))))
+
+		community, err = repo.GetByDID(ctx, did)
+		require.NoError(t, err)
+		assert.Equal(t, "Bare", community.DisplayName)
+		assert.Empty(t, community.Description)
+		assert.Empty(t, community.AvatarCID)
+		assert.Empty(t, community.BannerCID)
+		assert.Empty(t, community.DescriptionFacets)
+
+		// A stale copy of the full record doesn't bring them back
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "update", revAt(base, 0), "bafyfullagain", full)))
+		community, err = repo.GetByDID(ctx, did)
+		require.NoError(t, err)
+		assert.Equal(t, "Bare", community.DisplayName)
+		assert.Empty(t, community.AvatarCID)
+	})
+}
+
+func TestCommentConsumer_UpdatesByRev(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+
+	suffix := uniqueTestID()
+	testUser := createTestUser(t, db, fmt.Sprintf("revedit%s.test", suffix), fmt.Sprintf("did:plc:revedit%s", suffix))
+	testCommunity, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("revcomm%s", suffix), fmt.Sprintf("revowner%s.test", suffix))
+	require.NoError(t, err)
+	testPostURI := createTestPost(t, db, testCommunity, testUser.DID, "Rev Test", 0, time.Now())
+
+	rkey := generateTID()
+	uri := fmt.Sprintf("at://%s/social.coves.community.comment/%s", testUser.DID, rkey)
+	base := time.Now()
+
+	event := func(operation, rev, cid, content string) *jetstream.JetstreamEvent {
+		return &jetstream.JetstreamEvent{
+			Did:  testUser.DID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        rev,
+				Operation:  operation,
+				Collection: "social.coves.community.comment",
+				RKey:       rkey,
+				CID:        cid,
+				Record: map[string]interface{}{
+					"content": content,
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": testPostURI, "cid": "bafypost"},
+						"parent": map[string]interface{}{"uri": testPostURI, "cid": "bafypost"},
+					},
+					"createdAt": base.Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	require.NoError(t, consumer.HandleEvent(ctx, event("create", revAt(base, 0), "bafyv1", "First version")))
+	require.NoError(t, consumer.HandleEvent(ctx, event("update", revAt(base, 2*time.Second), "bafyv3", "Third version")))
+	require.NoError(t, consumer.HandleEvent(ctx, event("update", revAt(base, time.Second), "bafyv2", "Second version")))
+
+	comment, err := commentRepo.GetByURI(ctx, uri)
+	require.NoError(t, err)
+	assert.Equal(t, "Third version", comment.Content)
+	assert.Equal(t, "bafyv3", comment.CID)
+	assert.Equal(t, revAt(base, 2*time.Second), comment.Rev)
+
+	// The stale update left no entry in the edit history
+	var revisions int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM content_revisions WHERE subject_uri = $1`, uri).Scan(&revisions))
+	assert.Equal(t, 1, revisions, "only the v1 -> v3 edit should be recorded")
+}
@@ -0,0 +1,338 @@
+package integration
+
+import (
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/moderation"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/takedown"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bluesky-social/indigo/atproto/syntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRecordTakedown_AuditTrailAndExclusion takes down a post and a comment, checks they drop
+// out of every read (their author's included), that direct fetches report the reason only, and
+// that reversal restores them while the audit trail keeps both actions
+func TestRecordTakedown_AuditTrailAndExclusion(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	signer := newTestCursorSigner()
+	feedRepo := postgres.NewCommunityFeedRepository(db, signer)
+	discoverRepo := postgres.NewDiscoverRepository(db, signer)
+	commentRepo := postgres.NewCommentRepository(db, signer)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	takedownRepo := postgres.NewTakedownRepository(db)
+	moderationService := moderation.NewModerationService(postgres.NewModerationRepository(db))
+	moderationService.(interface {
+		SetTakedowns(takedown.Repository, takedown.UpstreamDeleter)
+	}).SetTakedowns(takedownRepo, nil)
+	commentService := comments.NewCommentService(commentRepo, postgres.NewUserRepository(db), postRepo, postgres.NewCommunityRepository(db, newTestCursorSigner()), nil, nil, nil)
+	commentService.(interface{ SetTakedowns(takedown.Checker) }).SetTakedowns(takedownRepo)
+
+	suffix := uniqueTestID()
+	author := createTestUser(t, db, "takedown"+suffix+".test", "did:plc:takedown"+suffix)
+	communityDID, err := createFeedTestCommunity(db, ctx, "takedown"+suffix, "owner"+suffix+".test")
+	require.NoError(t, err)
+
+	now := time.Now()
+	livePost := createTestPost(t, db, communityDID, author.DID, "Live", 0, now.Add(-time.Minute))
+	downPost := createTestPost(t, db, communityDID, author.DID, "Taken down", 0, now)
+	liveComment := createTestCommentWithScore(t, db, author.DID, livePost, livePost, "live", 0, 0, now.Add(-time.Minute))
+	downComment := createTestCommentWithScore(t, db, author.DID, livePost, livePost, "taken down", 0, 0, now)
+	downReply := createTestCommentWithScore(t, db, author.DID, livePost, downComment, "reply to taken down", 0, 0, now)
+
+	// Shadow-ban the post first: reversing the takedown must leave it author_only
+	require.NoError(t, moderationService.SetVisibility(ctx, moderation.SetVisibilityRequest{
+		Subject: downPost, State: moderation.VisibilityAuthorOnly, ActorDID: "did:plc:admin",
+	}))
+
+	result, err := moderationService.TakedownRecord(ctx, moderation.TakedownRecordRequest{
+		Subject: downPost, Reason: takedown.ReasonCopyright, Note: "DMCA notice #42", ActorDID: "did:plc:admin",
+	})
+	require.NoError(t, err)
+	assert.NotZero(t, result.ID)
+	assert.False(t, result.UpstreamDeleted)
+	_, err = moderationService.TakedownRecord(ctx, moderation.TakedownRecordRequest{
+		Subject: downComment, Reason: takedown.ReasonAbuse, ActorDID: "did:plc:admin",
+	})
+	require.NoError(t, err)
+
+	// Taking a record down twice returns the active takedown
+	again, err := moderationService.TakedownRecord(ctx, moderation.TakedownRecordRequest{
+		Subject: downPost, Reason: takedown.ReasonSpam, ActorDID: "did:plc:admin2",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, result.ID, again.ID)
+	assert.Equal(t, takedown.ReasonCopyright, again.Reason)
+
+	feedURIs := func(viewerDID string) []string {
+		feed, _, err := feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+			Community: communityDID, Sort: "new", ViewerDID: viewerDID, Limit: 50,
+		})
+		require.NoError(t, err)
+		var uris []string
+		for _, item := range feed {
+			uris = append(uris, item.Post.URI)
+		}
+		return uris
+	}
+	commentURIs := func(list []*comments.Comment) []string {
+		uris := make([]string, 0, len(list))
+		for _, c := range list {
+			uris = append(uris, c.URI)
+		}
+		return uris
+	}
+
+	t.Run("excluded from reads", func(t *testing.T) {
+		// Even the author, who sees their author_only content, doesn't see taken-down content
+		for _, viewer := range []string{"", author.DID} {
+			uris := feedURIs(viewer)
+			assert.Contains(t, uris, livePost)
+			assert.NotContains(t, uris, downPost)
+		}
+
+		disc, _, err := discoverRepo.GetDiscover(ctx, discover.GetDiscoverRequest{Sort: "new", ViewerDID: author.DID, Limit: 50})
+		require.NoError(t, err)
+		for _, item := range disc {
+			assert.NotEqual(t, downPost, item.Post.URI)
+		}
+
+		authored, _, err := postRepo.GetByAuthor(ctx, posts.GetAuthorPostsRequest{ActorDID: author.DID, Limit: 50})
+		require.NoError(t, err)
+		for _, post := range authored {
+			assert.NotEqual(t, downPost, post.URI)
+		}
+
+		top, _, err := commentRepo.ListByParentWithHotRank(ctx, livePost, "new", "all", 50, nil, author.DID)
+		require.NoError(t, err)
+		assert.Contains(t, commentURIs(top), liveComment)
+		assert.NotContains(t, commentURIs(top), downComment)
+
+		byCommenter, _, err := commentRepo.ListByCommenterWithCursor(ctx, comments.ListByCommenterRequest{CommenterDID: author.DID, Limit: 50})
+		require.NoError(t, err)
+		assert.NotContains(t, commentURIs(byCommenter), downComment)
+
+		// Replies keep their thread, but the taken-down parent is only a stub
+		ancestors, err := commentRepo.GetAncestorsWithDeleted(ctx, downReply, 5)
+		require.NoError(t, err)
+		require.Len(t, ancestors, 1)
+		assert.NotNil(t, ancestors[0].DeletedAt)
+		require.NotNil(t, ancestors[0].DeletionReason)
+		assert.Equal(t, comments.DeletionReasonModerator, *ancestors[0].DeletionReason)
+	})
+
+	t.Run("direct fetch reports the reason only", func(t *testing.T) {
+		_, err := commentService.GetComments(ctx, &comments.GetCommentsRequest{PostURI: downPost, Sort: "new", Depth: 10, Limit: 50})
+		var takenDown *takedown.TakenDownError
+		require.ErrorAs(t, err, &takenDown)
+		assert.Equal(t, takedown.ReasonCopyright, takenDown.Reason)
+		assert.NotContains(t, err.Error(), "DMCA")
+
+		_, err = commentService.GetCommentThread(ctx, &comments.GetCommentThreadRequest{CommentURI: downComment, Sort: "new", Depth: 10, Limit: 50})
+		require.ErrorAs(t, err, &takenDown)
+		assert.Equal(t, takedown.ReasonAbuse, takenDown.Reason)
+	})
+
+	t.Run("reversal and audit trail", func(t *testing.T) {
+		reversed, err := moderationService.ReverseTakedown(ctx, moderation.ReverseTakedownRequest{Subject: downPost, ActorDID: "did:plc:admin2"})
+		require.NoError(t, err)
+		assert.Equal(t, result.ID, reversed.ID)
+		require.NotNil(t, reversed.ReversedAt)
+
+		assert.NotContains(t, feedURIs(""), downPost, "the post is still author_only")
+		assert.Contains(t, feedURIs(author.DID), downPost)
+
+		_, err = moderationService.ReverseTakedown(ctx, moderation.ReverseTakedownRequest{Subject: downPost, ActorDID: "did:plc:admin2"})
+		require.ErrorIs(t, err, takedown.ErrTakedownNotFound)
+
+		// A second takedown adds a second audit row
+		_, err = moderationService.TakedownRecord(ctx, moderation.TakedownRecordRequest{
+			Subject: downPost, Reason: takedown.ReasonLegal, ActorDID: "did:plc:admin",
+		})
+		require.NoError(t, err)
+
+		rows, err := db.QueryContext(ctx, `
+			SELECT reason, note, created_by_did, COALESCE(reversed_by_did, ''), reversed_at IS NOT NULL
+			FROM record_takedowns WHERE subject_uri = $1 ORDER BY id`, downPost)
+		require.NoError(t, err)
+		defer func() { _ = rows.Close() }()
+
+		type auditRow struct {
+			reason, note, createdBy, reversedBy string
+			reversed                            bool
+		}
+		var trail []auditRow
+		for rows.Next() {
+			var r auditRow
+			require.NoError(t, rows.Scan(&r.reason, &r.note, &r.createdBy, &r.reversedBy, &r.reversed))
+			trail = append(trail, r)
+		}
+		require.NoError(t, rows.Err())
+		assert.Equal(t, []auditRow{
+			{reason: "copyright", note: "DMCA notice #42", createdBy: "did:plc:admin", reversedBy: "did:plc:admin2", reversed: true},
+			{reason: "legal", createdBy: "did:plc:admin"},
+		}, trail)
+	})
+
+	_, err = moderationService.TakedownRecord(ctx, moderation.TakedownRecordRequest{
+		Subject: "at://" + communityDID + "/social.coves.community.post/missing", Reason: takedown.ReasonLegal, ActorDID: "did:plc:admin",
+	})
+	require.ErrorIs(t, err, takedown.ErrSubjectNotFound)
+}
+
+// TestRecordTakedown_DeletesCommunityPostFromPDS takes down a post in a community this instance
+// provisioned on the dev PDS and checks it's deleted upstream, while a comment in a user's repo
+// is only taken down locally
+func TestRecordTakedown_DeletesCommunityPostFromPDS(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping live PDS test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+
+	ctx := context.Background()
+	pdsURL := getTestPDSURL()
+
+	healthResp, err := http.Get(pdsURL + "/xrpc/_health")
+	if err != nil {
+		t.Skipf("PDS not available: %v", err)
+	}
+	_ = healthResp.Body.Close()
+
+	instanceHandle := os.Getenv("PDS_INSTANCE_HANDLE")
+	instancePassword := os.Getenv("PDS_INSTANCE_PASSWORD")
+	if instanceHandle == "" {
+		instanceHandle = "testuser123.local.coves.dev"
+	}
+	if instancePassword == "" {
+		instancePassword = "test-password-123"
+	}
+	_, instanceDID, err := authenticateWithPDS(pdsURL, instanceHandle, instancePassword)
+	if err != nil {
+		t.Skipf("Failed to authenticate with PDS: %v", err)
+	}
+	instanceDomain := "local.coves.dev"
+	if strings.HasPrefix(instanceDID, "did:web:") {
+		instanceDomain = strings.TrimPrefix(instanceDID, "did:web:")
+	}
+
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	communityService := communities.NewCommunityServiceWithPDSFactory(
+		communityRepo,
+		pdsURL,
+		instanceDID,
+		instanceDomain,
+		communities.NewPDSAccountProvisioner(instanceDomain, pdsURL),
+		nil,
+		nil,
+	)
+	postService := posts.NewPostService(postRepo, communityService, nil, nil, nil, nil, pdsURL)
+
+	ownerHandle := fmt.Sprintf("takedownowner%d.local.coves.dev", time.Now().UnixNano()%1000000)
+	ownerEmail := fmt.Sprintf("takedownowner-%d@test.local", time.Now().Unix())
+	_, ownerDID, err := createPDSAccount(pdsURL, ownerHandle, ownerEmail, "password123")
+	if err != nil {
+		t.Skipf("Failed to create owner account: %v", err)
+	}
+	owner := createTestUser(t, db, ownerHandle, ownerDID)
+
+	community, err := communityService.CreateCommunity(ctx, communities.CreateCommunityRequest{
+		Name:         fmt.Sprintf("takedown%d", time.Now().UnixNano()%1000000),
+		DisplayName:  "Takedown Test Community",
+		Description:  "Testing record takedowns",
+		CreatedByDID: owner.DID,
+		Visibility:   "public",
+	})
+	require.NoError(t, err)
+
+	title := "Post to take down"
+	content := "This post is deleted from the community's repo"
+	created, err := postService.CreatePost(
+		middleware.SetTestUserDID(ctx, owner.DID),
+		posts.CreatePostRequest{Community: community.DID, Title: &title, Content: &content, AuthorDID: owner.DID},
+	)
+	require.NoError(t, err)
+	require.True(t, recordExistsOnPDS(t, pdsURL, created.URI), "the post should be on the PDS")
+
+	// Index the post and a user-repo comment without waiting on Jetstream
+	rkey := created.URI[strings.LastIndex(created.URI, "/")+1:]
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO posts (uri, cid, rkey, author_did, community_did, title, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, NOW())
+		ON CONFLICT (uri) DO NOTHING`,
+		created.URI, created.CID, rkey, owner.DID, community.DID, title)
+	require.NoError(t, err)
+	comment := createTestCommentWithScore(t, db, owner.DID, created.URI, created.URI, "user-repo comment", 0, 0, time.Now())
+
+	moderationService := moderation.NewModerationService(postgres.NewModerationRepository(db))
+	moderationService.(interface {
+		SetTakedowns(takedown.Repository, takedown.UpstreamDeleter)
+	}).SetTakedowns(postgres.NewTakedownRepository(db), takedown.NewCommunityRecordDeleter(communityService))
+
+	result, err := moderationService.TakedownRecord(ctx, moderation.TakedownRecordRequest{
+		Subject: created.URI, Reason: takedown.ReasonLegal, ActorDID: "did:plc:admin",
+	})
+	require.NoError(t, err)
+	assert.True(t, result.UpstreamDeleted)
+	assert.False(t, recordExistsOnPDS(t, pdsURL, created.URI), "the post should be deleted from the PDS")
+
+	var upstreamDeleted bool
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT upstream_deleted FROM record_takedowns WHERE id = $1`, result.ID).Scan(&upstreamDeleted))
+	assert.True(t, upstreamDeleted)
+
+	// Retaking an upstream-deleted record doesn't touch the PDS again
+	again, err := moderationService.TakedownRecord(ctx, moderation.TakedownRecordRequest{
+		Subject: created.URI, Reason: takedown.ReasonLegal, ActorDID: "did:plc:admin",
+	})
+	require.NoError(t, err)
+	assert.True(t, again.UpstreamDeleted)
+
+	commentResult, err := moderationService.TakedownRecord(ctx, moderation.TakedownRecordRequest{
+		Subject: comment, Reason: takedown.ReasonAbuse, ActorDID: "did:plc:admin",
+	})
+	require.NoError(t, err)
+	assert.False(t, commentResult.UpstreamDeleted, "user repos can't be written to")
+}
+
+// recordExistsOnPDS reports whether the PDS serves the record
+func recordExistsOnPDS(t *testing.T, pdsURL, uri string) bool {
+	t.Helper()
+	aturi, err := syntax.ParseATURI(uri)
+	require.NoError(t, err)
+
+	query := url.Values{}
+	query.Set("repo", aturi.Authority().String())
+	query.Set("collection", aturi.Collection().String())
+	query.Set("rkey", aturi.RecordKey().String())
+	resp, err := http.Get(pdsURL + "/xrpc/com.atproto.repo.getRecord?" + query.Encode())
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	return resp.StatusCode == http.StatusOK
+}
@@ -0,0 +1,185 @@
+package integration
+
+import (
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/retention"
+	"Coves/internal/db/postgres"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insertRetentionTestUser indexes a user whose account lives on pdsURL
+func insertRetentionTestUser(t *testing.T, db *sql.DB, did, pdsURL string) {
+	t.Helper()
+	_, err := db.Exec(`
+		INSERT INTO users (did, handle, pds_url) VALUES ($1, $2, $3)
+		ON CONFLICT (did) DO NOTHING`, did, did[len("did:plc:"):]+".test", pdsURL)
+	require.NoError(t, err)
+}
+
+// insertRetentionTestComment indexes a comment replying to a post, returning its URI
+func insertRetentionTestComment(t *testing.T, db *sql.DB, commenterDID, postURI string, createdAt time.Time) string {
+	t.Helper()
+	rkey := generateTID()
+	uri := fmt.Sprintf("at://%s/social.coves.community.comment/%s", commenterDID, rkey)
+	_, err := db.Exec(`
+		INSERT INTO comments (uri, cid, rkey, commenter_did, root_uri, root_cid, parent_uri, parent_cid, content, created_at)
+		VALUES ($1, 'bafycomment', $2, $3, $4, 'bafytest', $4, 'bafytest', 'Old news', $5)`,
+		uri, rkey, commenterDID, postURI, createdAt)
+	require.NoError(t, err)
+	return uri
+}
+
+// insertRetentionTestVote indexes an upvote on a post or comment
+func insertRetentionTestVote(t *testing.T, db *sql.DB, voterDID, subjectURI string, createdAt time.Time) {
+	t.Helper()
+	rkey := generateTID()
+	_, err := db.Exec(`
+		INSERT INTO votes (uri, cid, rkey, voter_did, subject_uri, subject_cid, direction, created_at)
+		VALUES ($1, 'bafyvote', $2, $3, $4, 'bafytest', 'up', $5)`,
+		fmt.Sprintf("at://%s/social.coves.feed.vote/%s", voterDID, rkey), rkey, voterDID, subjectURI, createdAt)
+	require.NoError(t, err)
+}
+
+// TestRemoteContentRetention_LocalInteractionProtectsPosts seeds old remote posts, some of
+// which the instance's users wrote, commented on or voted on, and checks only the untouched
+// ones are pruned, thread and all
+func TestRemoteContentRetention_LocalInteractionProtectsPosts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+	ctx := context.Background()
+
+	testID := uniqueTestID()
+	remotePDS := "https://pds.remote-" + testID + ".example"
+
+	// A clock far in the past, so the window only covers posts this test creates
+	now := time.Date(2003, 3, 1, 0, 0, 0, 0, time.UTC)
+	old := now.Add(-40 * 24 * time.Hour)
+
+	// Accounts: one remote, one signed in here, one on the instance PDS
+	remoteDID := "did:plc:retentionremote" + testID
+	signedInDID := "did:plc:retentionsignedin" + testID
+	instancePDSDID := "did:plc:retentionlocal" + testID
+	insertRetentionTestUser(t, db, remoteDID, remotePDS)
+	insertRetentionTestUser(t, db, signedInDID, remotePDS)
+	insertRetentionTestUser(t, db, instancePDSDID, getTestPDSURL())
+	_, err := db.Exec(`
+		INSERT INTO oauth_sessions (did, handle, pds_url, access_token, refresh_token, dpop_private_jwk, auth_server_iss, expires_at, session_id)
+		VALUES ($1, $2, $3, 'test_access', 'test_refresh', '{}', 'https://auth.test', NOW() + INTERVAL '1 day', 'retention')`,
+		signedInDID, "retentionsignedin"+testID+".test", remotePDS)
+	require.NoError(t, err)
+	t.Cleanup(func() { _, _ = db.Exec(`DELETE FROM oauth_sessions WHERE did = $1`, signedInDID) })
+
+	// A community hosted by another instance, and one hosted here
+	remoteCommunityDID := "did:plc:community-retentionremote" + testID
+	_, err = db.Exec(`
+		INSERT INTO communities (did, name, owner_did, created_by_did, hosted_by_did, handle, pds_url, created_at)
+		VALUES ($1, $2, $3, $3, 'did:web:remote.example', $4, $5, NOW())`,
+		remoteCommunityDID, "retentionremote"+testID, remoteDID, "retentionremote"+testID+".remote.example", remotePDS)
+	require.NoError(t, err)
+	localCommunityDID, err := createFeedTestCommunity(db, ctx, "retentionlocal"+testID, "retentionowner"+testID+".test")
+	require.NoError(t, err)
+
+	post := func(communityDID, title string, createdAt time.Time) string {
+		return createTestPost(t, db, communityDID, remoteDID, title, 0, createdAt)
+	}
+
+	// Untouched: pruned with its remote comments and votes
+	untouched := post(remoteCommunityDID, "Untouched", old.Add(5*time.Minute))
+	untouchedComment := insertRetentionTestComment(t, db, remoteDID, untouched, old)
+	insertRetentionTestVote(t, db, remoteDID, untouched, old)
+	insertRetentionTestVote(t, db, remoteDID, untouchedComment, old)
+
+	// Protected by local interaction
+	votedOn := post(remoteCommunityDID, "Voted on by a signed-in user", old.Add(4*time.Minute))
+	insertRetentionTestVote(t, db, signedInDID, votedOn, old)
+	commentedOn := post(remoteCommunityDID, "Commented on by an instance PDS account", old.Add(3*time.Minute))
+	insertRetentionTestComment(t, db, instancePDSDID, commentedOn, old)
+	commentVotedOn := post(remoteCommunityDID, "A comment voted on locally", old.Add(2*time.Minute))
+	remoteReply := insertRetentionTestComment(t, db, remoteDID, commentVotedOn, old)
+	insertRetentionTestVote(t, db, signedInDID, remoteReply, old)
+	localAuthor := createTestPost(t, db, remoteCommunityDID, instancePDSDID, "Written here", 0, old.Add(time.Minute))
+
+	// Protected by age and by hosting
+	recent := post(remoteCommunityDID, "Recent", now.Add(-5*24*time.Hour))
+	hostedHere := post(localCommunityDID, "Hosted here", old)
+
+	_, err = db.Exec(`UPDATE communities SET post_count = 6 WHERE did = $1`, remoteCommunityDID)
+	require.NoError(t, err)
+
+	// The first page of the community's new feed ends on the post about to be pruned
+	feedRepo := postgres.NewCommunityFeedRepository(db, newTestCursorSigner())
+	firstPage, cursor, err := feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+		Community: remoteCommunityDID, Sort: "new", Limit: 2,
+	})
+	require.NoError(t, err)
+	require.Len(t, firstPage, 2)
+	require.Equal(t, untouched, firstPage[1].Post.URI)
+	require.NotNil(t, cursor)
+
+	pruner := retention.NewPruner(postgres.NewRetentionRepository(db, getTestInstanceDID(), getTestPDSURL()), retention.Config{
+		Now:        func() time.Time { return now },
+		Window:     retention.WindowDays(30),
+		BatchSize:  2,
+		BatchPause: time.Millisecond,
+	})
+	report, err := pruner.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Posts)
+	assert.Equal(t, int64(1), report.Comments)
+	assert.Equal(t, int64(2), report.Votes)
+	assert.Positive(t, report.Bytes)
+
+	exists := func(table, uri string) bool {
+		var found bool
+		require.NoError(t, db.QueryRow(fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s WHERE uri = $1)`, table), uri).Scan(&found))
+		return found
+	}
+	assert.False(t, exists("posts", untouched), "untouched remote post")
+	assert.False(t, exists("comments", untouchedComment), "comment in the pruned thread")
+	var votesLeft int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM votes WHERE subject_uri = ANY($1)`, "{"+untouched+","+untouchedComment+"}").Scan(&votesLeft))
+	assert.Zero(t, votesLeft, "votes in the pruned thread")
+
+	for name, uri := range map[string]string{
+		"voted on locally":           votedOn,
+		"commented on locally":       commentedOn,
+		"comment voted on locally":   commentVotedOn,
+		"written by a local account": localAuthor,
+		"inside the window":          recent,
+		"hosted here":                hostedHere,
+	} {
+		assert.True(t, exists("posts", uri), name)
+	}
+	assert.True(t, exists("comments", remoteReply), "remote comment in a protected thread")
+
+	var postCount int
+	require.NoError(t, db.QueryRow(`SELECT post_count FROM communities WHERE did = $1`, remoteCommunityDID).Scan(&postCount))
+	assert.Equal(t, 5, postCount)
+
+	// The cursor still points at the pruned post's position, so the next page carries on
+	nextPage, _, err := feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{
+		Community: remoteCommunityDID, Sort: "new", Limit: 10, Cursor: cursor,
+	})
+	require.NoError(t, err)
+	nextURIs := make([]string, 0, len(nextPage))
+	for _, item := range nextPage {
+		nextURIs = append(nextURIs, item.Post.URI)
+	}
+	assert.Equal(t, []string{votedOn, commentedOn, commentVotedOn, localAuthor}, nextURIs)
+
+	// A second run finds nothing left to prune
+	report, err = pruner.Run(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, report.Posts)
+}
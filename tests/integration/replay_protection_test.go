@@ -0,0 +1,276 @@
+package integration
+
+import (
+	"Coves/internal/atproto/identity"
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordTombstones(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	tombstones := postgres.NewRecordTombstoneRepository(db)
+	uri := fmt.Sprintf("at://did:plc:tomb%s/social.coves.community.subscription/%s", uniqueTestID(), generateTID())
+	base := time.Now()
+
+	rev, err := tombstones.Rev(ctx, uri)
+	require.NoError(t, err)
+	assert.Empty(t, rev, "a record that was never deleted has no tombstone")
+
+	require.NoError(t, tombstones.Record(ctx, uri, revAt(base, 2*time.Second)))
+	require.NoError(t, tombstones.Record(ctx, uri, revAt(base, time.Second)))
+	require.NoError(t, tombstones.Record(ctx, uri, ""))
+
+	rev, err = tombstones.Rev(ctx, uri)
+	require.NoError(t, err)
+	assert.Equal(t, revAt(base, 2*time.Second), rev, "the newest deleting rev should be kept")
+
+	_, err = tombstones.Prune(ctx, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	rev, err = tombstones.Rev(ctx, uri)
+	require.NoError(t, err)
+	assert.NotEmpty(t, rev, "recent tombstones survive pruning")
+
+	pruned, err := tombstones.Prune(ctx, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, pruned, int64(1))
+	rev, err = tombstones.Rev(ctx, uri)
+	require.NoError(t, err)
+	assert.Empty(t, rev)
+}
+
+func TestCommentConsumer_ReplayAfterDelete(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	guard := jetstream.NewReplayGuard(postgres.NewRecordTombstoneRepository(db))
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+	consumer.SetReplayGuard(guard)
+
+	suffix := uniqueTestID()
+	testUser := createTestUser(t, db, fmt.Sprintf("replay%s.test", suffix), fmt.Sprintf("did:plc:replay%s", suffix))
+	testCommunity, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("replaycomm%s", suffix), fmt.Sprintf("replayowner%s.test", suffix))
+	require.NoError(t, err)
+	testPostURI := createTestPost(t, db, testCommunity, testUser.DID, "Replay Test", 0, time.Now())
+
+	rkey := generateTID()
+	uri := fmt.Sprintf("at://%s/social.coves.community.comment/%s", testUser.DID, rkey)
+	base := time.Now()
+
+	event := func(operation, rev, cid string) *jetstream.JetstreamEvent {
+		return &jetstream.JetstreamEvent{
+			Did:  testUser.DID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        rev,
+				Operation:  operation,
+				Collection: "social.coves.community.comment",
+				RKey:       rkey,
+				CID:        cid,
+				Record: map[string]interface{}{
+					"content": "Replayed comment",
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": testPostURI, "cid": "bafypost"},
+						"parent": map[string]interface{}{"uri": testPostURI, "cid": "bafypost"},
+					},
+					"createdAt": base.Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	require.NoError(t, consumer.HandleEvent(ctx, event("create", revAt(base, 0), "bafyv1")))
+	require.NoError(t, consumer.HandleEvent(ctx, event("delete", revAt(base, time.Second), "")))
+
+	// The create is redelivered after the delete
+	require.NoError(t, consumer.HandleEvent(ctx, event("create", revAt(base, 0), "bafyv1")))
+	_, err = commentRepo.GetByURI(ctx, uri)
+	assert.Error(t, err, "a replayed create must not resurrect a deleted comment")
+
+	var replyCount int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT reply_count FROM posts WHERE uri = $1`, testPostURI).Scan(&replyCount))
+	assert.Equal(t, 0, replyCount)
+
+	assert.Equal(t, int64(1), guard.StaleEventsSkipped()["social.coves.community.comment"])
+}
+
+func TestCommunityConsumer_ReplayAfterDelete(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	base := time.Now()
+
+	t.Run("replayed profile create after delete is skipped", func(t *testing.T) {
+		suffix := uniqueTestID()
+		did := generateTestDID(suffix)
+		name := fmt.Sprintf("replay-%s", suffix)
+		resolver := newMockIdentityResolver()
+		resolver.resolutions[did] = fmt.Sprintf("c-%s.coves.local", name)
+		guard := jetstream.NewReplayGuard(postgres.NewRecordTombstoneRepository(db))
+		consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.local", true, resolver)
+		consumer.SetReplayGuard(guard)
+
+		record := map[string]interface{}{
+			"name":        name,
+			"displayName": "Replay",
+			"owner":       "did:web:coves.local",
+			"createdBy":   "did:plc:user123",
+			"hostedBy":    "did:web:coves.local",
+			"visibility":  "public",
+			"federation":  map[string]interface{}{"allowExternalDiscovery": true},
+			"createdAt":   base.Format(time.RFC3339),
+		}
+
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "create", revAt(base, 0), "bafycreate", record)))
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "delete", revAt(base, time.Second), "", nil)))
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "create", revAt(base, 0), "bafycreate", record)))
+
+		_, err := repo.GetByDID(ctx, did)
+		assert.Error(t, err, "a replayed create must not bring back a deleted community")
+		assert.Equal(t, int64(1), guard.StaleEventsSkipped()["social.coves.community.profile"])
+
+		// A create committed after the delete is a genuine recreation
+		require.NoError(t, consumer.HandleEvent(ctx, revProfileEvent(did, "create", revAt(base, 2*time.Second), "bafyrecreate", record)))
+		community, err := repo.GetByDID(ctx, did)
+		require.NoError(t, err)
+		assert.Equal(t, "bafyrecreate", community.RecordCID)
+	})
+
+	t.Run("replayed subscription after unsubscribe is skipped", func(t *testing.T) {
+		suffix := uniqueTestID()
+		community := createTestCommunity(t, repo, "replay-sub", generateTestDID(suffix))
+		guard := jetstream.NewReplayGuard(postgres.NewRecordTombstoneRepository(db))
+		consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.local", true, nil)
+		consumer.SetReplayGuard(guard)
+
+		userDID := fmt.Sprintf("did:plc:replaysub%s", suffix)
+		rkey := generateTID()
+		event := func(operation, rev string) *jetstream.JetstreamEvent {
+			return &jetstream.JetstreamEvent{
+				Did:  userDID,
+				Kind: "commit",
+				Commit: &jetstream.CommitEvent{
+					Rev:        rev,
+					Operation:  operation,
+					Collection: "social.coves.community.subscription",
+					RKey:       rkey,
+					CID:        "bafysub",
+					Record: map[string]interface{}{
+						"$type":     "social.coves.community.subscription",
+						"subject":   community.DID,
+						"createdAt": base.Format(time.RFC3339),
+					},
+				},
+			}
+		}
+
+		require.NoError(t, consumer.HandleEvent(ctx, event("create", revAt(base, 0))))
+		require.NoError(t, consumer.HandleEvent(ctx, event("delete", revAt(base, time.Second))))
+		require.NoError(t, consumer.HandleEvent(ctx, event("create", revAt(base, 0))))
+
+		_, err := repo.GetSubscription(ctx, userDID, community.DID)
+		assert.Error(t, err, "a replayed subscribe must not undo the unsubscribe")
+		assert.Equal(t, int64(1), guard.StaleEventsSkipped()["social.coves.community.subscription"])
+	})
+}
+
+func TestUserConsumer_PreferencesReplay(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	prefsRepo := postgres.NewUserPreferencesRepository(db)
+	guard := jetstream.NewReplayGuard(postgres.NewRecordTombstoneRepository(db))
+	resolver := identity.NewResolver(db, identity.DefaultConfig())
+	userService := users.NewUserService(postgres.NewUserRepository(db), resolver, getTestPDSURL())
+	consumer := jetstream.NewUserEventConsumer(userService, resolver, "", "",
+		jetstream.WithPreferencesRepository(prefsRepo),
+		jetstream.WithReplayGuard(guard))
+
+	suffix := uniqueTestID()
+	testUser := createTestUser(t, db, fmt.Sprintf("replayprefs%s.test", suffix), fmt.Sprintf("did:plc:replayprefs%s", suffix))
+	base := time.Now()
+
+	event := func(operation, rev string, hide bool) *jetstream.JetstreamEvent {
+		commit := &jetstream.CommitEvent{
+			Rev:        rev,
+			Operation:  operation,
+			Collection: "social.coves.actor.preferences",
+			RKey:       "self",
+		}
+		if operation != "delete" {
+			commit.CID = "bafyprefs"
+			commit.Record = map[string]interface{}{"hideAggregatorPosts": hide}
+		}
+		return &jetstream.JetstreamEvent{Did: testUser.DID, Kind: "commit", Commit: commit}
+	}
+
+	require.NoError(t, consumer.HandleEvent(ctx, event("create", revAt(base, 0), false)))
+	require.NoError(t, consumer.HandleEvent(ctx, event("update", revAt(base, 2*time.Second), true)))
+
+	// The update committed in between arrives last
+	require.NoError(t, consumer.HandleEvent(ctx, event("update", revAt(base, time.Second), false)))
+	prefs, err := prefsRepo.Get(ctx, testUser.DID)
+	require.NoError(t, err)
+	assert.True(t, prefs.HideAggregatorPosts)
+	assert.Equal(t, revAt(base, 2*time.Second), prefs.Rev)
+
+	// A delete older than the indexed record is skipped
+	require.NoError(t, consumer.HandleEvent(ctx, event("delete", revAt(base, time.Second), false)))
+	_, err = prefsRepo.Get(ctx, testUser.DID)
+	require.NoError(t, err)
+
+	// A write replayed after the delete doesn't restore the preferences
+	require.NoError(t, consumer.HandleEvent(ctx, event("delete", revAt(base, 3*time.Second), false)))
+	require.NoError(t, consumer.HandleEvent(ctx, event("update", revAt(base, 2*time.Second), true)))
+	_, err = prefsRepo.Get(ctx, testUser.DID)
+	assert.ErrorIs(t, err, users.ErrPreferencesNotFound)
+
+	assert.Equal(t, int64(3), guard.StaleEventsSkipped()["social.coves.actor.preferences"])
+}
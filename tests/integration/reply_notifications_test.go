@@ -0,0 +1,193 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/notifications"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCommentConsumer_ReplyNotificationGrouping verifies replies to the same parent collapse
+// into one notification per hour bucket, and that marking a group read covers every reply in it
+func TestCommentConsumer_ReplyNotificationGrouping(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	suffix := uniqueTestID()
+
+	op := createTestUser(t, db, fmt.Sprintf("op-%s.test", suffix), fmt.Sprintf("did:plc:op%s", suffix))
+	alice := createTestUser(t, db, fmt.Sprintf("alice-%s.test", suffix), fmt.Sprintf("did:plc:alice%s", suffix))
+	bob := createTestUser(t, db, fmt.Sprintf("bob-%s.test", suffix), fmt.Sprintf("did:plc:bob%s", suffix))
+	carol := createTestUser(t, db, fmt.Sprintf("carol-%s.test", suffix), fmt.Sprintf("did:plc:carol%s", suffix))
+	communityDID, err := createFeedTestCommunity(db, ctx, "replies-"+suffix, "repliesowner"+suffix)
+	require.NoError(t, err)
+	postURI := createTestPost(t, db, communityDID, op.DID, "Replies", 0, time.Now())
+
+	consumer := jetstream.NewCommentEventConsumer(postgres.NewCommentRepository(db, newTestCursorSigner()), db)
+	service := notifications.NewNotificationService(postgres.NewNotificationRepository(db), nil)
+
+	reply := func(t *testing.T, authorDID, parentURI string, createdAt time.Time) string {
+		t.Helper()
+		rkey := generateTID()
+		require.NoError(t, consumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  authorDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev",
+				Operation:  "create",
+				Collection: jetstream.CommentCollection,
+				RKey:       rkey,
+				CID:        "bafy" + rkey,
+				Record: map[string]interface{}{
+					"$type":   jetstream.CommentCollection,
+					"content": "A reply",
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+						"parent": map[string]interface{}{"uri": parentURI, "cid": "bafyparent"},
+					},
+					"createdAt": createdAt.Format(time.RFC3339),
+				},
+			},
+		}))
+		return fmt.Sprintf("at://%s/social.coves.community.comment/%s", authorDID, rkey)
+	}
+
+	list := func(t *testing.T, recipientDID string) []*notifications.Notification {
+		t.Helper()
+		result, err := service.ListNotifications(ctx, notifications.ListNotificationsRequest{RecipientDID: recipientDID, Limit: 50})
+		require.NoError(t, err)
+		return result
+	}
+
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+
+	t.Run("a single reply renders as X replied", func(t *testing.T) {
+		reply(t, alice.DID, postURI, hour.Add(5*time.Minute))
+
+		got := list(t, op.DID)
+		require.Len(t, got, 1)
+		assert.Equal(t, notifications.ReasonReply, got[0].Reason)
+		assert.Equal(t, postURI, got[0].SubjectParentURI)
+		assert.Equal(t, 1, got[0].ReplyCount)
+		assert.Equal(t, alice.Handle+" replied", got[0].Text)
+	})
+
+	var groupID int64
+	t.Run("replies within the hour collapse into one notification", func(t *testing.T) {
+		reply(t, bob.DID, postURI, hour.Add(30*time.Minute))
+		latest := reply(t, carol.DID, postURI, hour.Add(59*time.Minute))
+
+		got := list(t, op.DID)
+		require.Len(t, got, 1)
+		assert.Equal(t, 3, got[0].ReplyCount)
+		assert.Equal(t, carol.DID, got[0].Actor.DID, "the newest reply's author is the actor")
+		assert.Equal(t, latest, got[0].SubjectURI)
+		assert.Equal(t, carol.Handle+" and 2 others replied", got[0].Text)
+		assert.False(t, got[0].IsRead)
+		groupID = got[0].ID
+	})
+
+	t.Run("a self-reply does not notify", func(t *testing.T) {
+		reply(t, op.DID, postURI, hour.Add(40*time.Minute))
+
+		got := list(t, op.DID)
+		require.Len(t, got, 1)
+		assert.Equal(t, 3, got[0].ReplyCount)
+	})
+
+	t.Run("reading the group marks every reply in it seen", func(t *testing.T) {
+		require.NoError(t, service.UpdateSeen(ctx, notifications.UpdateSeenRequest{RecipientDID: op.DID, IDs: []int64{groupID}}))
+
+		got := list(t, op.DID)
+		require.Len(t, got, 1)
+		assert.True(t, got[0].IsRead)
+	})
+
+	t.Run("another user cannot mark the group seen", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, `UPDATE notifications SET is_read = FALSE WHERE id = $1`, groupID)
+		require.NoError(t, err)
+		require.NoError(t, service.UpdateSeen(ctx, notifications.UpdateSeenRequest{RecipientDID: alice.DID, IDs: []int64{groupID}}))
+
+		assert.False(t, list(t, op.DID)[0].IsRead)
+		require.NoError(t, service.UpdateSeen(ctx, notifications.UpdateSeenRequest{RecipientDID: op.DID, IDs: []int64{groupID}}))
+	})
+
+	t.Run("a reply in the next hour starts a new group", func(t *testing.T) {
+		reply(t, alice.DID, postURI, hour.Add(time.Hour))
+
+		got := list(t, op.DID)
+		require.Len(t, got, 2)
+		assert.Equal(t, 1, got[0].ReplyCount, "the newest group is listed first")
+		assert.Equal(t, alice.Handle+" replied", got[0].Text)
+		assert.False(t, got[0].IsRead)
+		assert.Equal(t, groupID, got[1].ID)
+		assert.Equal(t, 3, got[1].ReplyCount)
+		assert.True(t, got[1].IsRead, "the earlier group stays read")
+	})
+
+	t.Run("a late reply in a read group makes it unread again", func(t *testing.T) {
+		reply(t, bob.DID, postURI, hour.Add(45*time.Minute))
+
+		got := list(t, op.DID)
+		require.Len(t, got, 2)
+		assert.Equal(t, groupID, got[0].ID, "the group moves to the top")
+		assert.Equal(t, 4, got[0].ReplyCount)
+		assert.Equal(t, bob.Handle+" and 3 others replied", got[0].Text)
+		assert.False(t, got[0].IsRead)
+	})
+
+	t.Run("replies to a comment notify the comment's author, grouped separately", func(t *testing.T) {
+		aliceComment := reply(t, alice.DID, postURI, hour.Add(2*time.Hour))
+		reply(t, bob.DID, aliceComment, hour.Add(2*time.Hour+time.Minute))
+		reply(t, carol.DID, aliceComment, hour.Add(2*time.Hour+2*time.Minute))
+
+		got := list(t, alice.DID)
+		require.Len(t, got, 1)
+		assert.Equal(t, aliceComment, got[0].SubjectParentURI)
+		assert.Equal(t, carol.Handle+" and 1 other replied", got[0].Text)
+	})
+
+	t.Run("a replayed reply is not counted twice", func(t *testing.T) {
+		var before int
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT COALESCE(SUM(reply_count), 0) FROM notifications WHERE recipient_did = $1`, op.DID).Scan(&before))
+
+		rkey := generateTID()
+		event := &jetstream.JetstreamEvent{
+			Did:  bob.DID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev: "rev", Operation: "create", Collection: jetstream.CommentCollection, RKey: rkey, CID: "bafyreplay",
+				Record: map[string]interface{}{
+					"$type":   jetstream.CommentCollection,
+					"content": "Replayed",
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+						"parent": map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+					},
+					"createdAt": hour.Add(time.Hour + 10*time.Minute).Format(time.RFC3339),
+				},
+			},
+		}
+		require.NoError(t, consumer.HandleEvent(ctx, event))
+		require.NoError(t, consumer.HandleEvent(ctx, event))
+
+		var after int
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT COALESCE(SUM(reply_count), 0) FROM notifications WHERE recipient_did = $1`, op.DID).Scan(&after))
+		assert.Equal(t, before+1, after)
+	})
+}
@@ -0,0 +1,340 @@
+package integration
+
+import (
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/discover"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/timeline"
+	"Coves/internal/core/votes"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSoftDelete_RepositoryReadsExcludeDeleted seeds a soft-deleted post, comment and vote next
+// to live ones and checks every repository read hides the deleted rows. Only *WithDeleted
+// methods may return them. New repository methods fail this test until they are covered here.
+func TestSoftDelete_RepositoryReadsExcludeDeleted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	cursors := newTestCursorSigner()
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	commentRepo := postgres.NewCommentRepository(db, cursors)
+	voteRepo := postgres.NewVoteRepository(db)
+	userRepo := postgres.NewUserRepository(db)
+	feedRepo := postgres.NewCommunityFeedRepository(db, cursors)
+	timelineRepo := postgres.NewTimelineRepository(db, cursors)
+	discoverRepo := postgres.NewDiscoverRepository(db, cursors)
+
+	testID := uniqueTestID()
+	author := createTestUser(t, db, "softdel"+testID+".test", "did:plc:softdel"+testID)
+	communityDID, err := createFeedTestCommunity(db, ctx, "softdel"+testID, "softdelowner"+testID+".test")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO community_subscriptions (user_did, community_did, subscribed_at)
+		VALUES ($1, $2, NOW())
+	`, author.DID, communityDID)
+	require.NoError(t, err)
+
+	// Slightly in the future so both posts lead the global "new" feeds
+	postTime := time.Now().Add(time.Minute)
+	livePost := createTestPost(t, db, communityDID, author.DID, "live", 1, postTime)
+	deletedPost := createTestPost(t, db, communityDID, author.DID, "deleted", 100, postTime)
+	require.NoError(t, postRepo.SoftDelete(ctx, deletedPost))
+
+	newComment := func(rkey, parentURI string) *comments.Comment {
+		comment := &comments.Comment{
+			URI:          fmt.Sprintf("at://%s/social.coves.community.comment/%s", author.DID, rkey),
+			CID:          "bafy" + rkey,
+			RKey:         rkey,
+			CommenterDID: author.DID,
+			RootURI:      livePost,
+			RootCID:      "bafytest",
+			ParentURI:    parentURI,
+			ParentCID:    "bafytest",
+			Content:      rkey,
+			Langs:        []string{},
+			CreatedAt:    time.Now(),
+		}
+		require.NoError(t, commentRepo.Create(ctx, comment))
+		return comment
+	}
+	liveComment := newComment(generateTID(), livePost)
+	deletedComment := newComment(generateTID(), livePost)
+	nestedReply := newComment(generateTID(), deletedComment.URI)
+	require.NoError(t, commentRepo.SoftDeleteWithReason(ctx, deletedComment.URI, comments.DeletionReasonAuthor, author.DID))
+
+	newVote := func(subjectURI string) *votes.Vote {
+		rkey := generateTID()
+		vote := &votes.Vote{
+			URI:        fmt.Sprintf("at://%s/social.coves.feed.vote/%s", author.DID, rkey),
+			CID:        "bafy" + rkey,
+			RKey:       rkey,
+			VoterDID:   author.DID,
+			SubjectURI: subjectURI,
+			SubjectCID: "bafytest",
+			Direction:  "up",
+			CreatedAt:  time.Now(),
+		}
+		require.NoError(t, voteRepo.Create(ctx, vote))
+		return vote
+	}
+	liveVote := newVote(livePost)
+	deletedVote := newVote(liveComment.URI)
+	require.NoError(t, voteRepo.Delete(ctx, deletedVote.URI))
+
+	covered := make(map[string]bool)
+	read := func(name string, check func(t *testing.T)) {
+		covered[name] = true
+		t.Run(name, check)
+	}
+
+	// Posts
+	read("posts.GetByURI", func(t *testing.T) {
+		_, err := postRepo.GetByURI(ctx, livePost)
+		require.NoError(t, err)
+		_, err = postRepo.GetByURI(ctx, deletedPost)
+		assert.True(t, posts.IsNotFound(err), "expected not found, got %v", err)
+	})
+	read("posts.GetByAuthor", func(t *testing.T) {
+		feed, _, err := postRepo.GetByAuthor(ctx, posts.GetAuthorPostsRequest{ActorDID: author.DID, Limit: 50})
+		require.NoError(t, err)
+		uris := make([]string, 0, len(feed))
+		for _, p := range feed {
+			uris = append(uris, p.URI)
+		}
+		assert.Contains(t, uris, livePost)
+		assert.NotContains(t, uris, deletedPost)
+	})
+	read("posts.FindDuplicates", func(t *testing.T) {
+		linkHash := "softdeletedup" + communityDID
+		_, err := db.ExecContext(ctx, `UPDATE posts SET normalized_url_hash = $1 WHERE uri IN ($2, $3)`,
+			linkHash, livePost, deletedPost)
+		require.NoError(t, err)
+		matches, err := postRepo.FindDuplicates(ctx, posts.DuplicateQuery{CommunityDID: communityDID, LinkHash: linkHash, Limit: 10})
+		require.NoError(t, err)
+		uris := make([]string, 0, len(matches))
+		for _, m := range matches {
+			uris = append(uris, m.URI)
+		}
+		assert.Equal(t, []string{livePost}, uris)
+	})
+
+	// Comments
+	commentURIs := func(list []*comments.Comment) []string {
+		uris := make([]string, 0, len(list))
+		for _, c := range list {
+			uris = append(uris, c.URI)
+		}
+		return uris
+	}
+	read("comments.GetByURI", func(t *testing.T) {
+		_, err := commentRepo.GetByURI(ctx, liveComment.URI)
+		require.NoError(t, err)
+		_, err = commentRepo.GetByURI(ctx, deletedComment.URI)
+		assert.True(t, comments.IsNotFound(err), "expected not found, got %v", err)
+	})
+	read("comments.ListByRoot", func(t *testing.T) {
+		list, err := commentRepo.ListByRoot(ctx, livePost, 100, 0)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{liveComment.URI, nestedReply.URI}, commentURIs(list))
+	})
+	read("comments.ListByParent", func(t *testing.T) {
+		list, err := commentRepo.ListByParent(ctx, livePost, 100, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{liveComment.URI}, commentURIs(list))
+	})
+	read("comments.ListByRootWithCursor", func(t *testing.T) {
+		list, _, err := commentRepo.ListByRootWithCursor(ctx, livePost, 100, nil)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{liveComment.URI, nestedReply.URI}, commentURIs(list))
+	})
+	read("comments.ListByParentWithCursor", func(t *testing.T) {
+		list, _, err := commentRepo.ListByParentWithCursor(ctx, livePost, 100, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{liveComment.URI}, commentURIs(list))
+	})
+	read("comments.CountByParent", func(t *testing.T) {
+		count, err := commentRepo.CountByParent(ctx, livePost)
+		require.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+	read("comments.ListByCommenter", func(t *testing.T) {
+		list, err := commentRepo.ListByCommenter(ctx, author.DID, 100, 0)
+		require.NoError(t, err)
+		assert.NotContains(t, commentURIs(list), deletedComment.URI)
+		assert.Contains(t, commentURIs(list), liveComment.URI)
+	})
+	read("comments.ListByCommenterWithCursor", func(t *testing.T) {
+		list, _, err := commentRepo.ListByCommenterWithCursor(ctx, comments.ListByCommenterRequest{CommenterDID: author.DID, Limit: 100})
+		require.NoError(t, err)
+		assert.NotContains(t, commentURIs(list), deletedComment.URI)
+		assert.Contains(t, commentURIs(list), liveComment.URI)
+	})
+	read("comments.ListByParentWithHotRank", func(t *testing.T) {
+		for _, sort := range []string{"hot", "top", "new"} {
+			list, _, err := commentRepo.ListByParentWithHotRank(ctx, livePost, sort, "all", 100, nil, "")
+			require.NoError(t, err)
+			assert.Equal(t, []string{liveComment.URI}, commentURIs(list), "sort=%s", sort)
+		}
+	})
+	read("comments.GetByURIsBatch", func(t *testing.T) {
+		batch, err := commentRepo.GetByURIsBatch(ctx, []string{liveComment.URI, deletedComment.URI})
+		require.NoError(t, err)
+		assert.Contains(t, batch, liveComment.URI)
+		assert.NotContains(t, batch, deletedComment.URI)
+	})
+	read("comments.GetVoteStateForComments", func(t *testing.T) {
+		state, err := commentRepo.GetVoteStateForComments(ctx, author.DID, []string{liveComment.URI})
+		require.NoError(t, err)
+		assert.Empty(t, state, "deleted vote should not appear as viewer state")
+	})
+
+	// WithDeleted variants return soft-deleted comments as thread placeholders
+	read("comments.GetByURIWithDeleted", func(t *testing.T) {
+		comment, err := commentRepo.GetByURIWithDeleted(ctx, deletedComment.URI)
+		require.NoError(t, err)
+		assert.NotNil(t, comment.DeletedAt)
+	})
+	read("comments.GetAncestorsWithDeleted", func(t *testing.T) {
+		ancestors, err := commentRepo.GetAncestorsWithDeleted(ctx, nestedReply.URI, 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{deletedComment.URI}, commentURIs(ancestors))
+	})
+	read("comments.ListByParentWithHotRankWithDeleted", func(t *testing.T) {
+		for _, sort := range []string{"hot", "top", "new"} {
+			list, _, err := commentRepo.ListByParentWithHotRankWithDeleted(ctx, livePost, sort, "all", 100, nil, "")
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{liveComment.URI, deletedComment.URI}, commentURIs(list), "sort=%s", sort)
+		}
+	})
+	read("comments.ListByParentsBatchWithDeleted", func(t *testing.T) {
+		byParent, err := commentRepo.ListByParentsBatchWithDeleted(ctx, []string{livePost}, "new", 10, "")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{liveComment.URI, deletedComment.URI}, commentURIs(byParent[livePost]))
+	})
+
+	// Votes
+	read("votes.GetByURI", func(t *testing.T) {
+		_, err := voteRepo.GetByURI(ctx, liveVote.URI)
+		require.NoError(t, err)
+		_, err = voteRepo.GetByURI(ctx, deletedVote.URI)
+		assert.ErrorIs(t, err, votes.ErrVoteNotFound)
+	})
+	read("votes.GetByVoterAndSubject", func(t *testing.T) {
+		_, err := voteRepo.GetByVoterAndSubject(ctx, author.DID, liveComment.URI)
+		assert.ErrorIs(t, err, votes.ErrVoteNotFound)
+	})
+	read("votes.ListVotersForSubject", func(t *testing.T) {
+		access, err := votes.NewAccessPolicy(voteRepo, nil, nil).AuthorizeVoters(ctx, author.DID, liveComment.URI)
+		require.NoError(t, err)
+		list, err := voteRepo.ListVotersForSubject(ctx, access, 100, 0)
+		require.NoError(t, err)
+		assert.Empty(t, list)
+	})
+	read("votes.ListByVoter", func(t *testing.T) {
+		list, err := voteRepo.ListByVoter(ctx, author.DID, 100, 0)
+		require.NoError(t, err)
+		require.Len(t, list, 1)
+		assert.Equal(t, liveVote.URI, list[0].URI)
+	})
+
+	// Feeds
+	read("communityFeeds.GetCommunityFeed", func(t *testing.T) {
+		feed, _, err := feedRepo.GetCommunityFeed(ctx, communityFeeds.GetCommunityFeedRequest{Community: communityDID, Sort: "new", Limit: 50})
+		require.NoError(t, err)
+		uris := make([]string, 0, len(feed))
+		for _, fp := range feed {
+			uris = append(uris, fp.Post.URI)
+		}
+		assert.Equal(t, []string{livePost}, uris)
+	})
+	read("timeline.GetTimeline", func(t *testing.T) {
+		feed, _, err := timelineRepo.GetTimeline(ctx, timeline.GetTimelineRequest{UserDID: author.DID, Sort: "new", Limit: 50})
+		require.NoError(t, err)
+		uris := make([]string, 0, len(feed))
+		for _, fp := range feed {
+			uris = append(uris, fp.Post.URI)
+		}
+		assert.Equal(t, []string{livePost}, uris)
+	})
+	read("discover.GetDiscover", func(t *testing.T) {
+		feed, _, err := discoverRepo.GetDiscover(ctx, discover.GetDiscoverRequest{Sort: "new", Limit: 50})
+		require.NoError(t, err)
+		uris := make([]string, 0, len(feed))
+		for _, fp := range feed {
+			uris = append(uris, fp.Post.URI)
+		}
+		assert.Contains(t, uris, livePost)
+		assert.NotContains(t, uris, deletedPost)
+	})
+	read("discover.GetHotPosts", func(t *testing.T) {
+		ranked, err := discoverRepo.GetHotPosts(ctx, []string{communityDID}, 50)
+		require.NoError(t, err)
+		uris := make([]string, 0, len(ranked))
+		for _, r := range ranked {
+			uris = append(uris, r.Post.Post.URI)
+		}
+		assert.Equal(t, []string{livePost}, uris)
+	})
+	read("discover.GetDiscussions", func(t *testing.T) {
+		linkHash := "softdelete" + communityDID
+		_, err := db.ExecContext(ctx, `UPDATE posts SET normalized_url_hash = $1 WHERE uri IN ($2, $3)`,
+			linkHash, livePost, deletedPost)
+		require.NoError(t, err)
+		feed, _, err := discoverRepo.GetDiscussions(ctx, discover.GetDiscussionsRequest{LinkHash: linkHash, Limit: 50})
+		require.NoError(t, err)
+		uris := make([]string, 0, len(feed))
+		for _, fp := range feed {
+			uris = append(uris, fp.Post.URI)
+		}
+		assert.Equal(t, []string{livePost}, uris)
+	})
+
+	// Users
+	read("users.GetProfileStats", func(t *testing.T) {
+		stats, err := userRepo.GetProfileStats(ctx, author.DID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.PostCount)
+		assert.Equal(t, 2, stats.CommentCount)
+	})
+
+	// Every repository method must be covered above or exempt: writes, and reads of tables
+	// that are never soft-deleted (featured communities)
+	exempt := map[string]bool{
+		"posts.Create": true, "posts.SoftDelete": true,
+		"comments.Create": true, "comments.Update": true, "comments.Delete": true, "comments.SoftDeleteWithReason": true,
+		"votes.Create": true, "votes.Delete": true, "votes.RemoveVotesByVoter": true, "votes.RestoreVotesByVoter": true,
+		"discover.ListFeaturedCommunities": true, "discover.AddFeaturedCommunity": true,
+		"discover.RemoveFeaturedCommunity": true, "discover.ReorderFeaturedCommunities": true,
+	}
+	repositories := map[string]reflect.Type{
+		"posts":          reflect.TypeOf((*posts.Repository)(nil)).Elem(),
+		"comments":       reflect.TypeOf((*comments.Repository)(nil)).Elem(),
+		"votes":          reflect.TypeOf((*votes.Repository)(nil)).Elem(),
+		"communityFeeds": reflect.TypeOf((*communityFeeds.Repository)(nil)).Elem(),
+		"timeline":       reflect.TypeOf((*timeline.Repository)(nil)).Elem(),
+		"discover":       reflect.TypeOf((*discover.Repository)(nil)).Elem(),
+	}
+	for pkg, iface := range repositories {
+		for i := 0; i < iface.NumMethod(); i++ {
+			name := pkg + "." + iface.Method(i).Name
+			if !exempt[name] && !covered[name] {
+				t.Errorf("%s has no soft-delete coverage; add a check to this test", name)
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Coves/internal/db/statearchive"
+)
+
+// TestStateArchive_RoundTrip exports cursors, OAuth sessions and federation rules, restores
+// them into the emptied tables and checks every row comes back byte-equal
+func TestStateArchive_RoundTrip(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() { _ = db.Close() }()
+	ctx := context.Background()
+
+	tables := []string{"directory_sync_peers", "oauth_sessions", "federation_rules"}
+	clearTables := func() {
+		for _, table := range tables {
+			_, err := db.ExecContext(ctx, "DELETE FROM "+table)
+			require.NoError(t, err)
+		}
+	}
+	clearTables()
+	t.Cleanup(clearTables)
+
+	_, err := db.ExecContext(ctx, `
+		INSERT INTO directory_sync_peers (peer_url, cursor, synced_at) VALUES
+			('https://peer-one.example', '2026-01-02T03:04:05.123456Z::did:plc:one', '2026-01-02T03:04:05.987654Z'),
+			('https://peer-two.example', 'opaque-cursor-two', NOW())`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO oauth_sessions (did, handle, pds_url, access_token, refresh_token, dpop_private_jwk, auth_server_iss, expires_at, session_id, dpop_pds_nonce)
+		VALUES
+			('did:plc:archivealice', 'alice.test', 'https://pds.test', 'alice-access-token', 'alice-refresh-token',
+				'{"kty":"EC","crv":"P-256","d":"secret-d"}', 'https://auth.test', NOW() + INTERVAL '1 day', 'session-a', 'nonce-a'),
+			('did:plc:archivebob', 'bob.test', 'https://pds.test', 'bob-access-token', 'bob-refresh-token',
+				'{}', 'https://auth.test', NOW() + INTERVAL '2 days', 'session-b', NULL)`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO federation_rules (pattern, mode, reason, created_by)
+		VALUES ('*.spam.example', 'deny', 'spam', 'did:plc:admin'), ('friend.example', 'allow', NULL, 'did:plc:admin')`)
+	require.NoError(t, err)
+
+	before := snapshotTables(t, db, tables)
+
+	key := bytes.Repeat([]byte{0x42}, statearchive.ArchiveKeySize)
+	scopes, err := statearchive.ParseScopes("cursors,oauth,federation")
+	require.NoError(t, err)
+	archive, err := statearchive.Export(ctx, db, statearchive.ExportOptions{Scopes: scopes, ArchiveKey: key})
+	require.NoError(t, err)
+
+	// Write and read the archive as the commands do; tokens never appear in it
+	encoded, err := json.Marshal(archive)
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "alice-access-token")
+	assert.NotContains(t, string(encoded), "secret-d")
+	assert.Contains(t, string(encoded), "peer-one.example")
+	var restored statearchive.Archive
+	require.NoError(t, json.Unmarshal(encoded, &restored))
+
+	t.Run("refuses non-empty tables without force", func(t *testing.T) {
+		_, err := statearchive.Import(ctx, db, &restored, statearchive.ImportOptions{ArchiveKey: key})
+		assert.ErrorIs(t, err, statearchive.ErrTableNotEmpty)
+		assert.Equal(t, before, snapshotTables(t, db, tables))
+	})
+
+	t.Run("refuses the wrong key and writes nothing", func(t *testing.T) {
+		clearTables()
+		wrongKey := bytes.Repeat([]byte{0x24}, statearchive.ArchiveKeySize)
+		_, err := statearchive.Import(ctx, db, &restored, statearchive.ImportOptions{ArchiveKey: wrongKey})
+		assert.Error(t, err)
+		for _, rows := range snapshotTables(t, db, tables) {
+			assert.Empty(t, rows)
+		}
+	})
+
+	t.Run("restores rows byte-equal", func(t *testing.T) {
+		clearTables()
+		results, err := statearchive.Import(ctx, db, &restored, statearchive.ImportOptions{ArchiveKey: key})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Equal(t, statearchive.TableResult{Name: "oauth_sessions", Rows: 2}, results[1])
+		assert.Equal(t, before, snapshotTables(t, db, tables))
+
+		// Sequences continue past the restored ids
+		var maxID, newID int64
+		require.NoError(t, db.QueryRowContext(ctx, `SELECT MAX(id) FROM federation_rules`).Scan(&maxID))
+		require.NoError(t, db.QueryRowContext(ctx, `
+			INSERT INTO federation_rules (pattern, mode, created_by) VALUES ('new.example', 'deny', 'did:plc:admin')
+			RETURNING id`).Scan(&newID))
+		assert.Greater(t, newID, maxID)
+		_, err = db.ExecContext(ctx, `DELETE FROM federation_rules WHERE id = $1`, newID)
+		require.NoError(t, err)
+	})
+
+	t.Run("force replaces existing rows", func(t *testing.T) {
+		_, err := db.ExecContext(ctx, `UPDATE directory_sync_peers SET cursor = 'moved-on'`)
+		require.NoError(t, err)
+
+		results, err := statearchive.Import(ctx, db, &restored, statearchive.ImportOptions{ArchiveKey: key, Force: true})
+		require.NoError(t, err)
+		assert.Equal(t, 2, results[0].Replaced)
+		assert.Equal(t, before, snapshotTables(t, db, tables))
+	})
+
+	t.Run("refuses another schema version", func(t *testing.T) {
+		other := restored
+		other.SchemaVersion--
+		_, err := statearchive.Import(ctx, db, &other, statearchive.ImportOptions{ArchiveKey: key, Force: true})
+		assert.Error(t, err)
+	})
+}
+
+// snapshotTables returns each table's rows as row_to_json text in a stable order
+func snapshotTables(t *testing.T, db *sql.DB, tables []string) map[string][]string {
+	t.Helper()
+	snapshot := make(map[string][]string)
+	for _, table := range tables {
+		rows, err := db.Query(`SELECT row_to_json(t)::text FROM ` + table + ` t ORDER BY row_to_json(t)::text`)
+		require.NoError(t, err)
+		snapshot[table] = []string{}
+		for rows.Next() {
+			var row string
+			require.NoError(t, rows.Scan(&row))
+			snapshot[table] = append(snapshot[table], row)
+		}
+		require.NoError(t, rows.Err())
+		_ = rows.Close()
+	}
+	return snapshot
+}
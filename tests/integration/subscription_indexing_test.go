@@ -6,6 +6,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -461,6 +462,118 @@ func TestSubscriptionIndexing_SubscriberCount(t *testing.T) {
 	})
 }
 
+// TestSubscriptionIndexing_CoalescedSubscriberCount hammers one community with concurrent
+// subscribe events while deltas are buffered, then checks the flushed count is exact
+func TestSubscriptionIndexing_CoalescedSubscriberCount(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	repo := createTestCommunityRepo(t, db)
+	consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.local", true, nil)
+
+	// Flush on a short interval and a small threshold so flushes overlap with inserts
+	buffer := jetstream.NewSubscriberCountBuffer(repo, 20*time.Millisecond, 50)
+	consumer.SetSubscriberCountBuffer(buffer)
+	flushCtx, cancelFlush := context.WithCancel(ctx)
+	flusherDone := make(chan struct{})
+	go func() {
+		buffer.Start(flushCtx)
+		close(flusherDone)
+	}()
+
+	runID := time.Now().UnixNano()
+	testDID := fmt.Sprintf("did:plc:test-coalesce-%d", runID)
+	community := createTestCommunity(t, repo, "test-coalesced-count", testDID)
+
+	const subscribers = 1000
+	newEvent := func(i int) *jetstream.JetstreamEvent {
+		return &jetstream.JetstreamEvent{
+			Did:    fmt.Sprintf("did:plc:coalesce-user-%d-%d", runID, i),
+			Kind:   "commit",
+			TimeUS: time.Now().UnixMicro(),
+			Commit: &jetstream.CommitEvent{
+				Rev:        fmt.Sprintf("rev-%d", i),
+				Operation:  "create",
+				Collection: "social.coves.community.subscription",
+				RKey:       "coalesce",
+				CID:        fmt.Sprintf("bafycoalesce%d", i),
+				Record: map[string]interface{}{
+					"$type":     "social.coves.community.subscription",
+					"subject":   community.DID,
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	// A deadlock would leave workers blocked; fail on a timeout instead of hanging the suite
+	var wg sync.WaitGroup
+	errs := make(chan error, subscribers)
+	sem := make(chan struct{}, 50)
+	for i := 0; i < subscribers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := consumer.HandleEvent(ctx, newEvent(i)); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(60 * time.Second):
+		t.Fatal("Timed out waiting for subscription events; possible deadlock")
+	}
+	close(errs)
+	for err := range errs {
+		t.Errorf("Failed to handle subscription: %v", err)
+	}
+
+	// Replaying the first event must not count the subscriber twice
+	if err := consumer.HandleEvent(ctx, newEvent(0)); err != nil {
+		t.Fatalf("Failed to replay subscription: %v", err)
+	}
+
+	cancelFlush()
+	<-flusherDone
+	if err := buffer.Flush(ctx); err != nil {
+		t.Fatalf("Failed to flush subscriber counts: %v", err)
+	}
+
+	comm, err := repo.GetByDID(ctx, community.DID)
+	if err != nil {
+		t.Fatalf("Failed to get community: %v", err)
+	}
+	if comm.SubscriberCount != subscribers {
+		t.Errorf("Expected subscriber count %d, got %d", subscribers, comm.SubscriberCount)
+	}
+
+	corrected, err := repo.RecountSubscriberCounts(ctx)
+	if err != nil {
+		t.Fatalf("Failed to recount subscribers: %v", err)
+	}
+	comm, err = repo.GetByDID(ctx, community.DID)
+	if err != nil {
+		t.Fatalf("Failed to get community: %v", err)
+	}
+	if comm.SubscriberCount != subscribers {
+		t.Errorf("Expected recount to keep %d subscribers, got %d (corrected %d communities)", subscribers, comm.SubscriberCount, corrected)
+	}
+}
+
 // Helper functions
 
 func createTestCommunity(t *testing.T, repo communities.Repository, name, did string) *communities.Community {
@@ -494,7 +607,7 @@ func createTestCommunity(t *testing.T, repo communities.Repository, name, did st
 func createTestCommunityRepo(t *testing.T, db interface{}) communities.Repository {
 	t.Helper()
 	// Import the postgres package to create a repo
-	return postgresRepo.NewCommunityRepository(db.(*sql.DB))
+	return postgresRepo.NewCommunityRepository(db.(*sql.DB), newTestCursorSigner())
 }
 
 func cleanupTestDB(t *testing.T, db interface{}) {
@@ -0,0 +1,235 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// TestPendingSubscriptions covers subscriptions and blocks indexed optimistically after a
+// write-forward, before their Jetstream events arrive
+func TestPendingSubscriptions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ctx := context.Background()
+	db := setupTestDB(t)
+	defer cleanupTestDB(t, db)
+
+	repo := createTestCommunityRepo(t, db)
+	consumer := jetstream.NewCommunityEventConsumer(repo, "did:web:coves.local", true, nil)
+
+	subscriptionEvent := func(userDID, rkey, communityDID string) *jetstream.JetstreamEvent {
+		return &jetstream.JetstreamEvent{
+			Did:    userDID,
+			Kind:   "commit",
+			TimeUS: time.Now().UnixMicro(),
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev-" + rkey,
+				Operation:  "create",
+				Collection: "social.coves.community.subscription",
+				RKey:       rkey,
+				CID:        "bafyconfirmed" + rkey,
+				Record: map[string]interface{}{
+					"$type":             "social.coves.community.subscription",
+					"subject":           communityDID,
+					"createdAt":         time.Now().Format(time.RFC3339),
+					"contentVisibility": float64(3),
+				},
+			},
+		}
+	}
+
+	pendingSubscription := func(userDID, rkey, communityDID string, at time.Time) *communities.Subscription {
+		return &communities.Subscription{
+			UserDID:           userDID,
+			CommunityDID:      communityDID,
+			ContentVisibility: 3,
+			SubscribedAt:      at,
+			RecordURI:         fmt.Sprintf("at://%s/social.coves.community.subscription/%s", userDID, rkey),
+			RecordCID:         "bafylocal" + rkey,
+		}
+	}
+
+	subscriberCount := func(t *testing.T, communityDID string) int {
+		t.Helper()
+		community, err := repo.GetByDID(ctx, communityDID)
+		if err != nil {
+			t.Fatalf("Failed to get community: %v", err)
+		}
+		return community.SubscriberCount
+	}
+
+	isPending := func(t *testing.T, table, recordURI string) bool {
+		t.Helper()
+		var pending bool
+		query := fmt.Sprintf(`SELECT pending_confirmation FROM %s WHERE record_uri = $1`, table)
+		if err := db.QueryRowContext(ctx, query, recordURI).Scan(&pending); err != nil {
+			t.Fatalf("Failed to read pending flag: %v", err)
+		}
+		return pending
+	}
+
+	t.Run("event confirms a pending subscription without double counting", func(t *testing.T) {
+		community := createTestCommunity(t, repo, "pending-confirm", fmt.Sprintf("did:plc:pending-confirm-%d", time.Now().UnixNano()))
+		userDID := "did:plc:pending-user-confirm"
+		sub := pendingSubscription(userDID, "confirm1", community.DID, time.Now())
+
+		if _, err := repo.SubscribePendingWithCount(ctx, sub); err != nil {
+			t.Fatalf("Failed to index pending subscription: %v", err)
+		}
+		if got := subscriberCount(t, community.DID); got != 1 {
+			t.Fatalf("Expected count 1 after local write, got %d", got)
+		}
+		if !isPending(t, "community_subscriptions", sub.RecordURI) {
+			t.Fatal("Expected subscription to be pending before the event arrives")
+		}
+
+		if err := consumer.HandleEvent(ctx, subscriptionEvent(userDID, "confirm1", community.DID)); err != nil {
+			t.Fatalf("Failed to handle subscription event: %v", err)
+		}
+
+		if got := subscriberCount(t, community.DID); got != 1 {
+			t.Errorf("Expected count to stay 1 after confirmation, got %d", got)
+		}
+		if isPending(t, "community_subscriptions", sub.RecordURI) {
+			t.Error("Expected pending flag to be cleared by the event")
+		}
+
+		stored, err := repo.GetSubscription(ctx, userDID, community.DID)
+		if err != nil {
+			t.Fatalf("Failed to get subscription: %v", err)
+		}
+		if stored.RecordCID != "bafyconfirmedconfirm1" {
+			t.Errorf("Expected CID from the event, got %s", stored.RecordCID)
+		}
+
+		// A confirmed subscription is never reaped
+		reaped, err := repo.DeleteUnconfirmedSubscriptions(ctx, time.Now().Add(time.Hour))
+		if err != nil {
+			t.Fatalf("Failed to reap: %v", err)
+		}
+		if _, err := repo.GetSubscription(ctx, userDID, community.DID); err != nil {
+			t.Errorf("Confirmed subscription should survive the reaper (reaped %d): %v", reaped, err)
+		}
+	})
+
+	t.Run("never-confirmed subscription is reaped and its count reverted", func(t *testing.T) {
+		community := createTestCommunity(t, repo, "pending-revert", fmt.Sprintf("did:plc:pending-revert-%d", time.Now().UnixNano()))
+		userDID := "did:plc:pending-user-revert"
+		subscribedAt := time.Now().Add(-communities.PendingConfirmationTimeout - time.Minute)
+
+		if _, err := repo.SubscribePendingWithCount(ctx, pendingSubscription(userDID, "lost1", community.DID, subscribedAt)); err != nil {
+			t.Fatalf("Failed to index pending subscription: %v", err)
+		}
+		if got := subscriberCount(t, community.DID); got != 1 {
+			t.Fatalf("Expected count 1 after local write, got %d", got)
+		}
+
+		// Still within the timeout from the reaper's point of view
+		if _, err := repo.DeleteUnconfirmedSubscriptions(ctx, subscribedAt.Add(-time.Minute)); err != nil {
+			t.Fatalf("Failed to reap: %v", err)
+		}
+		if _, err := repo.GetSubscription(ctx, userDID, community.DID); err != nil {
+			t.Fatalf("Pending subscription reaped before its timeout: %v", err)
+		}
+
+		if _, err := repo.DeleteUnconfirmedSubscriptions(ctx, time.Now().Add(-communities.PendingConfirmationTimeout)); err != nil {
+			t.Fatalf("Failed to reap: %v", err)
+		}
+		if _, err := repo.GetSubscription(ctx, userDID, community.DID); !communities.IsNotFound(err) {
+			t.Errorf("Expected unconfirmed subscription to be reaped, got %v", err)
+		}
+		if got := subscriberCount(t, community.DID); got != 0 {
+			t.Errorf("Expected count reverted to 0, got %d", got)
+		}
+	})
+
+	t.Run("event racing ahead of the local write is counted once", func(t *testing.T) {
+		community := createTestCommunity(t, repo, "pending-race", fmt.Sprintf("did:plc:pending-race-%d", time.Now().UnixNano()))
+		userDID := "did:plc:pending-user-race"
+
+		if err := consumer.HandleEvent(ctx, subscriptionEvent(userDID, "race1", community.DID)); err != nil {
+			t.Fatalf("Failed to handle subscription event: %v", err)
+		}
+		sub := pendingSubscription(userDID, "race1", community.DID, time.Now())
+		if _, err := repo.SubscribePendingWithCount(ctx, sub); err != nil {
+			t.Fatalf("Local write after the event should be a no-op, got %v", err)
+		}
+
+		if got := subscriberCount(t, community.DID); got != 1 {
+			t.Errorf("Expected count 1, got %d", got)
+		}
+		if isPending(t, "community_subscriptions", sub.RecordURI) {
+			t.Error("Late local write must not mark a confirmed subscription pending")
+		}
+
+		// Replaying the event changes nothing
+		if err := consumer.HandleEvent(ctx, subscriptionEvent(userDID, "race1", community.DID)); err != nil {
+			t.Fatalf("Failed to replay subscription event: %v", err)
+		}
+		if got := subscriberCount(t, community.DID); got != 1 {
+			t.Errorf("Expected count 1 after replay, got %d", got)
+		}
+	})
+
+	t.Run("blocks are confirmed and reaped the same way", func(t *testing.T) {
+		userDID := "did:plc:pending-user-block"
+		confirmed := createTestCommunity(t, repo, "pending-block", fmt.Sprintf("did:plc:pending-block-%d", time.Now().UnixNano()))
+		lost := createTestCommunity(t, repo, "pending-block-lost", fmt.Sprintf("did:plc:pending-block-lost-%d", time.Now().UnixNano()))
+		stale := time.Now().Add(-communities.PendingConfirmationTimeout - time.Minute)
+
+		confirmedURI := fmt.Sprintf("at://%s/social.coves.community.block/block1", userDID)
+		for _, block := range []*communities.CommunityBlock{
+			{UserDID: userDID, CommunityDID: confirmed.DID, BlockedAt: stale, RecordURI: confirmedURI, RecordCID: "bafylocalblock1"},
+			{UserDID: userDID, CommunityDID: lost.DID, BlockedAt: stale, RecordURI: fmt.Sprintf("at://%s/social.coves.community.block/block2", userDID), RecordCID: "bafylocalblock2"},
+		} {
+			if _, err := repo.BlockCommunityPending(ctx, block); err != nil {
+				t.Fatalf("Failed to index pending block: %v", err)
+			}
+		}
+
+		blocked, err := repo.IsBlocked(ctx, userDID, confirmed.DID)
+		if err != nil || !blocked {
+			t.Fatalf("Expected pending block to take effect immediately (blocked=%v, err=%v)", blocked, err)
+		}
+
+		event := &jetstream.JetstreamEvent{
+			Did:    userDID,
+			Kind:   "commit",
+			TimeUS: time.Now().UnixMicro(),
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev-block1",
+				Operation:  "create",
+				Collection: "social.coves.community.block",
+				RKey:       "block1",
+				CID:        "bafyconfirmedblock1",
+				Record: map[string]interface{}{
+					"$type":     "social.coves.community.block",
+					"subject":   confirmed.DID,
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		}
+		if err := consumer.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("Failed to handle block event: %v", err)
+		}
+		if isPending(t, "community_blocks", confirmedURI) {
+			t.Error("Expected pending flag to be cleared by the event")
+		}
+
+		if _, err := repo.DeleteUnconfirmedBlocks(ctx, time.Now().Add(-communities.PendingConfirmationTimeout)); err != nil {
+			t.Fatalf("Failed to reap blocks: %v", err)
+		}
+		if blocked, _ := repo.IsBlocked(ctx, userDID, confirmed.DID); !blocked {
+			t.Error("Confirmed block should survive the reaper")
+		}
+		if blocked, _ := repo.IsBlocked(ctx, userDID, lost.DID); blocked {
+			t.Error("Unconfirmed block should be reaped")
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/notifications"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestThreadMute_StopsNewNotifications verifies a muted thread sends its muter no new reply or
+// mention notifications, that the ones from before the mute stay, and that unmuting resumes them
+func TestThreadMute_StopsNewNotifications(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	suffix := uniqueTestID()
+
+	op := createTestUser(t, db, fmt.Sprintf("mutingop-%s.test", suffix), fmt.Sprintf("did:plc:mutingop%s", suffix))
+	alice := createTestUser(t, db, fmt.Sprintf("mutealice-%s.test", suffix), fmt.Sprintf("did:plc:mutealice%s", suffix))
+	bob := createTestUser(t, db, fmt.Sprintf("mutebob-%s.test", suffix), fmt.Sprintf("did:plc:mutebob%s", suffix))
+	communityDID, err := createFeedTestCommunity(db, ctx, "mutes-"+suffix, "mutesowner"+suffix)
+	require.NoError(t, err)
+	postURI := createTestPost(t, db, communityDID, op.DID, "Muted thread", 0, time.Now())
+	otherPostURI := createTestPost(t, db, communityDID, op.DID, "Other thread", 0, time.Now())
+
+	consumer := jetstream.NewCommentEventConsumer(postgres.NewCommentRepository(db, newTestCursorSigner()), db)
+	consumer.SetIdentityResolver(stubHandleResolver{op.Handle: op.DID})
+	muteService := notifications.NewThreadMuteService(postgres.NewThreadMuteRepository(db))
+
+	// Replies land in separate hour buckets so each one is its own notification
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-6 * time.Hour)
+	reply := func(t *testing.T, authorDID, rootURI, content string, createdAt time.Time) {
+		t.Helper()
+		rkey := generateTID()
+		require.NoError(t, consumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  authorDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev",
+				Operation:  "create",
+				Collection: jetstream.CommentCollection,
+				RKey:       rkey,
+				CID:        "bafy" + rkey,
+				Record: map[string]interface{}{
+					"$type":   jetstream.CommentCollection,
+					"content": content,
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": rootURI, "cid": "bafypost"},
+						"parent": map[string]interface{}{"uri": rootURI, "cid": "bafypost"},
+					},
+					"createdAt": createdAt.Format(time.RFC3339),
+				},
+			},
+		}))
+	}
+
+	count := func(t *testing.T, reason string) int {
+		t.Helper()
+		var n int
+		require.NoError(t, db.QueryRowContext(ctx, `
+			SELECT COUNT(*) FROM notifications
+			WHERE recipient_did = $1 AND reason = $2
+		`, op.DID, reason).Scan(&n))
+		return n
+	}
+
+	mention := fmt.Sprintf("cc @%s", op.Handle)
+	reply(t, alice.DID, postURI, "Before the mute", hour)
+	reply(t, alice.DID, postURI, mention, hour.Add(time.Hour))
+	require.Equal(t, 2, count(t, "reply"))
+	require.Equal(t, 1, count(t, "mention"))
+
+	require.NoError(t, muteService.MuteThread(ctx, notifications.ThreadMuteRequest{UserDID: op.DID, Root: postURI}))
+
+	t.Run("a mute stops new replies and mentions", func(t *testing.T) {
+		reply(t, bob.DID, postURI, "After the mute", hour.Add(2*time.Hour))
+		reply(t, bob.DID, postURI, mention, hour.Add(3*time.Hour))
+
+		assert.Equal(t, 2, count(t, "reply"), "only the replies from before the mute")
+		assert.Equal(t, 1, count(t, "mention"), "only the mention from before the mute")
+	})
+
+	t.Run("other threads still notify", func(t *testing.T) {
+		reply(t, bob.DID, otherPostURI, "Elsewhere", hour.Add(2*time.Hour))
+		assert.Equal(t, 3, count(t, "reply"))
+	})
+
+	t.Run("unmuting resumes notifications", func(t *testing.T) {
+		require.NoError(t, muteService.UnmuteThread(ctx, notifications.ThreadMuteRequest{UserDID: op.DID, Root: postURI}))
+
+		reply(t, bob.DID, postURI, mention, hour.Add(4*time.Hour))
+		assert.Equal(t, 4, count(t, "reply"))
+		assert.Equal(t, 2, count(t, "mention"))
+	})
+}
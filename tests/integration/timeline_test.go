@@ -28,9 +28,9 @@ func TestGetTimeline_Basic(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	timelineRepo := postgres.NewTimelineRepository(db, "test-cursor-secret")
+	timelineRepo := postgres.NewTimelineRepository(db, newTestCursorSigner())
 	timelineService := timelineCore.NewTimelineService(timelineRepo)
-	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil)
+	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil, nil, nil)
 
 	ctx := context.Background()
 	testID := time.Now().UnixNano()
@@ -117,9 +117,9 @@ func TestGetTimeline_HotSort(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	timelineRepo := postgres.NewTimelineRepository(db, "test-cursor-secret")
+	timelineRepo := postgres.NewTimelineRepository(db, newTestCursorSigner())
 	timelineService := timelineCore.NewTimelineService(timelineRepo)
-	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil)
+	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil, nil, nil)
 
 	ctx := context.Background()
 	testID := time.Now().UnixNano()
@@ -188,9 +188,9 @@ func TestGetTimeline_Pagination(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	timelineRepo := postgres.NewTimelineRepository(db, "test-cursor-secret")
+	timelineRepo := postgres.NewTimelineRepository(db, newTestCursorSigner())
 	timelineService := timelineCore.NewTimelineService(timelineRepo)
-	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil)
+	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil, nil, nil)
 
 	ctx := context.Background()
 	testID := time.Now().UnixNano()
@@ -264,9 +264,9 @@ func TestGetTimeline_EmptyWhenNoSubscriptions(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	timelineRepo := postgres.NewTimelineRepository(db, "test-cursor-secret")
+	timelineRepo := postgres.NewTimelineRepository(db, newTestCursorSigner())
 	timelineService := timelineCore.NewTimelineService(timelineRepo)
-	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil)
+	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil, nil, nil)
 
 	ctx := context.Background()
 	testID := time.Now().UnixNano()
@@ -306,9 +306,9 @@ func TestGetTimeline_Unauthorized(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	timelineRepo := postgres.NewTimelineRepository(db, "test-cursor-secret")
+	timelineRepo := postgres.NewTimelineRepository(db, newTestCursorSigner())
 	timelineService := timelineCore.NewTimelineService(timelineRepo)
-	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil)
+	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil, nil, nil)
 
 	// Request timeline WITHOUT auth context
 	req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getTimeline?sort=new&limit=10", nil)
@@ -322,7 +322,7 @@ func TestGetTimeline_Unauthorized(t *testing.T) {
 	err := json.Unmarshal(rec.Body.Bytes(), &errorResp)
 	require.NoError(t, err)
 
-	assert.Equal(t, "AuthenticationRequired", errorResp["error"])
+	assert.Equal(t, "AuthRequired", errorResp["error"])
 }
 
 // TestGetTimeline_LimitValidation tests limit parameter validation
@@ -335,9 +335,9 @@ func TestGetTimeline_LimitValidation(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	timelineRepo := postgres.NewTimelineRepository(db, "test-cursor-secret")
+	timelineRepo := postgres.NewTimelineRepository(db, newTestCursorSigner())
 	timelineService := timelineCore.NewTimelineService(timelineRepo)
-	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil)
+	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil, nil, nil)
 
 	ctx := context.Background()
 	testID := time.Now().UnixNano()
@@ -386,9 +386,9 @@ func TestGetTimeline_MultiCommunity_E2E(t *testing.T) {
 	t.Cleanup(func() { _ = db.Close() })
 
 	// Setup services
-	timelineRepo := postgres.NewTimelineRepository(db, "test-cursor-secret")
+	timelineRepo := postgres.NewTimelineRepository(db, newTestCursorSigner())
 	timelineService := timelineCore.NewTimelineService(timelineRepo)
-	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil)
+	handler := timeline.NewGetTimelineHandler(timelineService, nil, nil, nil, nil)
 
 	ctx := context.Background()
 	testID := time.Now().UnixNano()
@@ -105,7 +105,7 @@ func TestTokenRefresh_UpdateCredentials(t *testing.T) {
 		}
 	}()
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	// Create a test community first
 	community := &communities.Community{
@@ -179,7 +179,7 @@ func TestTokenRefresh_E2E_UpdateAfterTokenRefresh(t *testing.T) {
 	// For now, we'll test the token expiration detection logic
 	// Full E2E test with PDS will be added in manual testing phase
 
-	repo := postgres.NewCommunityRepository(db)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
 
 	// Create community with expiring token
 	expiringToken := createTestJWT(time.Now().Add(2 * time.Minute)) // Expires in 2 minutes
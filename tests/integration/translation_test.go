@@ -0,0 +1,122 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/translation"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTranslation_DetectsLanguageAndCachesPerVersion(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
+	consumer := jetstream.NewCommentEventConsumer(commentRepo, db)
+	translationRepo := postgres.NewTranslationRepository(db)
+
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	commenter := createTestUser(t, db, "polyglot"+suffix+".test", generateTestDID("polyglot"+suffix))
+	communityDID, err := createFeedTestCommunity(db, ctx, "langs"+suffix, "langowner"+suffix)
+	if err != nil {
+		t.Fatalf("Failed to create community: %v", err)
+	}
+	postURI := createTestPost(t, db, communityDID, commenter.DID, "Multilingual thread", 0, time.Now())
+
+	rkey := generateTID()
+	uri := fmt.Sprintf("at://%s/social.coves.community.comment/%s", commenter.DID, rkey)
+	write := func(operation, cid, content string) {
+		event := &jetstream.JetstreamEvent{
+			Did:  commenter.DID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Operation:  operation,
+				Collection: "social.coves.community.comment",
+				RKey:       rkey,
+				CID:        cid,
+				Record: map[string]interface{}{
+					"$type":   "social.coves.community.comment",
+					"content": content,
+					"reply": map[string]interface{}{
+						"root":   map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+						"parent": map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+					},
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		}
+		if err := consumer.HandleEvent(ctx, event); err != nil {
+			t.Fatalf("Failed to %s comment: %v", operation, err)
+		}
+	}
+
+	// The record declares no langs, so the language is detected from the content
+	write("create", "bafyv1", "Ich habe das Buch gestern gelesen und finde es wirklich sehr gut, weil die Geschichte spannend ist.")
+
+	var detected *string
+	var confidence *float64
+	if err := db.QueryRowContext(ctx, `SELECT detected_lang, detected_lang_confidence FROM comments WHERE uri = $1`, uri).
+		Scan(&detected, &confidence); err != nil {
+		t.Fatalf("Failed to read detected language: %v", err)
+	}
+	if detected == nil || *detected != "de" {
+		t.Fatalf("Expected detected_lang de, got %v", detected)
+	}
+	if confidence == nil || *confidence < translation.MinConfidence {
+		t.Errorf("Expected a confidence of at least %v, got %v", translation.MinConfidence, confidence)
+	}
+
+	subject, err := translationRepo.GetSubject(ctx, uri)
+	if err != nil {
+		t.Fatalf("Failed to get subject: %v", err)
+	}
+	if subject.Lang != "de" || subject.CID != "bafyv1" {
+		t.Errorf("Expected subject in de at bafyv1, got %q at %q", subject.Lang, subject.CID)
+	}
+
+	if err := translationRepo.SaveTranslation(ctx, &translation.Translation{
+		SubjectURI: uri, SubjectCID: "bafyv1", SourceLang: "de", TargetLang: "en",
+		Content: "I read the book yesterday", CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("Failed to save translation: %v", err)
+	}
+	cached, err := translationRepo.GetTranslation(ctx, uri, "bafyv1", "en")
+	if err != nil || cached == nil {
+		t.Fatalf("Expected cached translation, got %v (err %v)", cached, err)
+	}
+
+	// An edit changes the CID: the old translation is no longer served for the new version
+	write("update", "bafyv2", "Ich habe das Buch heute noch einmal gelesen und finde es jetzt nicht mehr so gut wie gestern.")
+	subject, err = translationRepo.GetSubject(ctx, uri)
+	if err != nil {
+		t.Fatalf("Failed to get edited subject: %v", err)
+	}
+	if subject.CID != "bafyv2" {
+		t.Fatalf("Expected edited subject at bafyv2, got %q", subject.CID)
+	}
+	cached, err = translationRepo.GetTranslation(ctx, uri, subject.CID, "en")
+	if err != nil {
+		t.Fatalf("Failed to read translation cache: %v", err)
+	}
+	if cached != nil {
+		t.Error("Expected no cached translation for the edited version")
+	}
+
+	if err := translationRepo.SaveTranslation(ctx, &translation.Translation{
+		SubjectURI: uri, SubjectCID: "bafyv2", SourceLang: "de", TargetLang: "en",
+		Content: "I read the book again today", CreatedAt: time.Now(),
+	}); err != nil {
+		t.Fatalf("Failed to save translation of edited version: %v", err)
+	}
+	var stored int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM translations WHERE subject_uri = $1`, uri).Scan(&stored); err != nil {
+		t.Fatalf("Failed to count translations: %v", err)
+	}
+	if stored != 1 {
+		t.Errorf("Expected translations of the old version to be dropped, got %d rows", stored)
+	}
+}
@@ -0,0 +1,181 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"Coves/internal/db/txrunner"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errInjected = errors.New("injected failure")
+
+// TestWithTx_SubscriptionRollsBack tests that a subscription and its count bump made
+// inside a failed unit of work are both rolled back
+func TestWithTx_SubscriptionRollsBack(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	communityDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("txsub-%d", testID), fmt.Sprintf("txowner-%d.test", testID))
+	require.NoError(t, err)
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	subscriberDID := fmt.Sprintf("did:plc:txsubscriber-%d", testID)
+
+	subscribe := func(ctx context.Context) error {
+		_, err := repo.SubscribeWithCount(ctx, &communities.Subscription{
+			UserDID:           subscriberDID,
+			CommunityDID:      communityDID,
+			SubscribedAt:      time.Now(),
+			ContentVisibility: 3,
+		})
+		return err
+	}
+
+	t.Run("error", func(t *testing.T) {
+		err := txrunner.WithTx(ctx, db, func(ctx context.Context) error {
+			require.NoError(t, subscribe(ctx))
+			return errInjected
+		})
+		require.ErrorIs(t, err, errInjected)
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_ = txrunner.WithTx(ctx, db, func(ctx context.Context) error {
+				require.NoError(t, subscribe(ctx))
+				panic("injected panic")
+			})
+		})
+	})
+
+	_, err = repo.GetSubscription(ctx, subscriberDID, communityDID)
+	assert.ErrorIs(t, err, communities.ErrSubscriptionNotFound, "rolled back subscription must not exist")
+	community, err := repo.GetByDID(ctx, communityDID)
+	require.NoError(t, err)
+	assert.Equal(t, 0, community.SubscriberCount, "rolled back count bump must not stick")
+
+	// The same write outside a failed unit of work commits as before
+	require.NoError(t, subscribe(ctx))
+	community, err = repo.GetByDID(ctx, communityDID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, community.SubscriberCount)
+}
+
+// failingModerationRepo fails to record moderation actions, after the deletion has been written
+type failingModerationRepo struct {
+	communities.Repository
+}
+
+func (r *failingModerationRepo) CreateModerationAction(ctx context.Context, action *communities.ModerationAction) (*communities.ModerationAction, error) {
+	return nil, errInjected
+}
+
+// TestCommunityDeletion_AdminRemovalIsAtomic tests that an admin deletion is rolled back
+// when its moderation record can't be written
+func TestCommunityDeletion_AdminRemovalIsAtomic(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	communityDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("txdel-%d", testID), fmt.Sprintf("txdelowner-%d.test", testID))
+	require.NoError(t, err)
+
+	repo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	service := communities.NewCommunityService(&failingModerationRepo{Repository: repo},
+		getTestPDSURL(), getTestInstanceDID(), "coves.social", nil, nil, nil)
+	configurable := service.(interface {
+		SetDeletionRepository(communities.DeletionRepository)
+		SetInstanceAdmins([]string)
+		SetTxRunner(txrunner.Runner)
+	})
+	configurable.SetDeletionRepository(postgres.NewCommunityDeletionRepository(db))
+	configurable.SetInstanceAdmins([]string{"did:plc:txadmin"})
+	configurable.SetTxRunner(txrunner.NewRunner(db))
+
+	_, err = service.DeleteCommunity(ctx, communities.DeleteCommunityRequest{CommunityDID: communityDID, ActorDID: "did:plc:txadmin"})
+	require.ErrorIs(t, err, errInjected)
+
+	community, err := repo.GetByDID(ctx, communityDID)
+	require.NoError(t, err)
+	assert.Nil(t, community.DeletedAt, "deletion must be rolled back with the failed moderation record")
+}
+
+// TestWithTx_VoteReplacementRollsBack tests that a vote replacing an earlier one is
+// rolled back together with the soft-delete and count changes it made
+func TestWithTx_VoteReplacementRollsBack(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	voterDID := fmt.Sprintf("did:plc:txvoter-%d", testID)
+	createTestUser(t, db, fmt.Sprintf("txvoter-%d.test", testID), voterDID)
+	communityDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("txvote-%d", testID), fmt.Sprintf("txvoteowner-%d.test", testID))
+	require.NoError(t, err)
+	postURI := createTestPost(t, db, communityDID, voterDID, "Vote target", 0, time.Now())
+
+	voteRepo := postgres.NewVoteRepository(db)
+	consumer := jetstream.NewVoteEventConsumer(voteRepo, users.NewUserService(postgres.NewUserRepository(db), nil, getTestPDSURL()), db)
+	voteEvent := func(rkey, direction string) *jetstream.JetstreamEvent {
+		return &jetstream.JetstreamEvent{
+			Did:  voterDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "test-rev",
+				Operation:  "create",
+				Collection: "social.coves.feed.vote",
+				RKey:       rkey,
+				CID:        "bafyvote" + rkey,
+				Record: map[string]interface{}{
+					"$type":     "social.coves.feed.vote",
+					"subject":   map[string]interface{}{"uri": postURI, "cid": "bafypost"},
+					"direction": direction,
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		}
+	}
+
+	firstRKey, secondRKey := generateTID(), generateTID()
+	require.NoError(t, consumer.HandleEvent(ctx, voteEvent(firstRKey, "up")))
+
+	err = txrunner.WithTx(ctx, db, func(ctx context.Context) error {
+		require.NoError(t, consumer.HandleEvent(ctx, voteEvent(secondRKey, "down")))
+		return errInjected
+	})
+	require.ErrorIs(t, err, errInjected)
+
+	first, err := voteRepo.GetByURI(ctx, fmt.Sprintf("at://%s/social.coves.feed.vote/%s", voterDID, firstRKey))
+	require.NoError(t, err, "the replaced vote must be active again")
+	assert.Nil(t, first.DeletedAt)
+	_, err = voteRepo.GetByURI(ctx, fmt.Sprintf("at://%s/social.coves.feed.vote/%s", voterDID, secondRKey))
+	assert.Error(t, err, "the replacing vote must not be indexed")
+
+	var upvotes, downvotes int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT upvote_count, downvote_count FROM posts WHERE uri = $1`, postURI).Scan(&upvotes, &downvotes))
+	assert.Equal(t, 1, upvotes)
+	assert.Equal(t, 0, downvotes)
+}
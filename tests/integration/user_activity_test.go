@@ -0,0 +1,113 @@
+package integration
+
+import (
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestUserActivity_CountsByUTCDay(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewUserActivityRepository(db)
+
+	suffix := fmt.Sprintf("%d", time.Now().UnixNano())
+	authorDID := generateTestDID("activity" + suffix)
+	communityDID, err := createFeedTestCommunity(db, ctx, "activity"+suffix, "activityowner"+suffix)
+	if err != nil {
+		t.Fatalf("Failed to create community: %v", err)
+	}
+
+	// Either side of midnight UTC at the end of January; 23:30 UTC on the 31st is already
+	// February 1st in Tokyo, so local time must not decide the bucket
+	tokyo := time.FixedZone("JST", 9*60*60)
+	lastOfJanuary := time.Date(2026, 1, 31, 23, 30, 0, 0, time.UTC)
+	firstOfFebruary := time.Date(2026, 2, 1, 9, 15, 0, 0, tokyo) // 00:15 UTC
+
+	createTestPost(t, db, communityDID, authorDID, "late", 0, lastOfJanuary.In(tokyo))
+	createTestPost(t, db, communityDID, authorDID, "early", 0, firstOfFebruary)
+	deleted := createTestPost(t, db, communityDID, authorDID, "deleted", 0, firstOfFebruary)
+	removed := createTestPost(t, db, communityDID, authorDID, "removed", 0, firstOfFebruary)
+	if _, err := db.ExecContext(ctx, `UPDATE posts SET deleted_at = NOW() WHERE uri = $1`, deleted); err != nil {
+		t.Fatalf("Failed to delete post: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, `UPDATE posts SET visibility_state = 'removed' WHERE uri = $1`, removed); err != nil {
+		t.Fatalf("Failed to remove post: %v", err)
+	}
+
+	rootURI := createTestPost(t, db, communityDID, "did:plc:someoneelse"+suffix, "root", 0, lastOfJanuary)
+	createTestCommentWithScore(t, db, authorDID, rootURI, rootURI, "one", 0, 0, lastOfJanuary)
+	createTestCommentWithScore(t, db, authorDID, rootURI, rootURI, "two", 0, 0, firstOfFebruary)
+	createTestCommentWithScore(t, db, authorDID, rootURI, rootURI, "three", 0, 0, firstOfFebruary)
+
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	postCounts, err := repo.CountPostsByDay(ctx, authorDID, since)
+	if err != nil {
+		t.Fatalf("CountPostsByDay failed: %v", err)
+	}
+	commentCounts, err := repo.CountCommentsByDay(ctx, authorDID, since)
+	if err != nil {
+		t.Fatalf("CountCommentsByDay failed: %v", err)
+	}
+
+	days := users.MergeActivity(postCounts, commentCounts)
+	want := []users.ActivityDay{
+		{Date: "2026-01-31", Posts: 1, Comments: 1},
+		{Date: "2026-02-01", Posts: 1, Comments: 2},
+	}
+	if len(days) != len(want) {
+		t.Fatalf("Expected %+v, got %+v", want, days)
+	}
+	for i := range want {
+		if days[i] != want[i] {
+			t.Errorf("Day %d: expected %+v, got %+v", i, want[i], days[i])
+		}
+	}
+
+	// Nothing before the window start is counted
+	postCounts, err = repo.CountPostsByDay(ctx, authorDID, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CountPostsByDay failed: %v", err)
+	}
+	if len(postCounts) != 1 || postCounts["2026-02-01"] != 1 {
+		t.Errorf("Expected only February 1st, got %v", postCounts)
+	}
+}
+
+func TestUserActivity_Deactivation(t *testing.T) {
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	repo := postgres.NewUserActivityRepository(db)
+
+	did := generateTestDID(fmt.Sprintf("deactivated%d", time.Now().UnixNano()))
+	createTestUser(t, db, fmt.Sprintf("deactivated%d.test", time.Now().UnixNano()), did)
+
+	if err := repo.SetDeactivated(ctx, did, true); err != nil {
+		t.Fatalf("SetDeactivated failed: %v", err)
+	}
+	if deactivated, err := repo.IsDeactivated(ctx, did); err != nil || !deactivated {
+		t.Fatalf("Expected account deactivated, got %v, %v", deactivated, err)
+	}
+
+	if err := repo.SetDeactivated(ctx, did, false); err != nil {
+		t.Fatalf("SetDeactivated failed: %v", err)
+	}
+	if deactivated, err := repo.IsDeactivated(ctx, did); err != nil || deactivated {
+		t.Fatalf("Expected account reactivated, got %v, %v", deactivated, err)
+	}
+
+	// Unknown accounts are neither an error nor deactivated
+	if err := repo.SetDeactivated(ctx, "did:plc:nobodyhere", true); err != nil {
+		t.Errorf("SetDeactivated of an unknown DID should be a no-op, got %v", err)
+	}
+	if deactivated, err := repo.IsDeactivated(ctx, "did:plc:nobodyhere"); err != nil || deactivated {
+		t.Errorf("Expected unknown DID not deactivated, got %v, %v", deactivated, err)
+	}
+}
@@ -8,10 +8,9 @@ import (
 	"Coves/internal/core/posts"
 	"Coves/internal/core/users"
 	"Coves/internal/db/postgres"
-	"bytes"
+	"Coves/pkg/covesclient"
 	"context"
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -98,11 +97,11 @@ func TestFullUserJourney_E2E(t *testing.T) {
 
 	// Setup repositories
 	userRepo := postgres.NewUserRepository(db)
-	communityRepo := postgres.NewCommunityRepository(db)
-	postRepo := postgres.NewPostRepository(db)
-	commentRepo := postgres.NewCommentRepository(db)
+	communityRepo := postgres.NewCommunityRepository(db, newTestCursorSigner())
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
+	commentRepo := postgres.NewCommentRepository(db, newTestCursorSigner())
 	voteRepo := postgres.NewVoteRepository(db)
-	timelineRepo := postgres.NewTimelineRepository(db, "test-cursor-secret")
+	timelineRepo := postgres.NewTimelineRepository(db, newTestCursorSigner())
 
 	// Setup identity resolution
 	plcURL := os.Getenv("PLC_DIRECTORY_URL")
@@ -143,11 +142,13 @@ func TestFullUserJourney_E2E(t *testing.T) {
 	// Setup HTTP server with all routes using OAuth middleware
 	e2eAuth := NewE2EOAuthMiddleware()
 	r := chi.NewRouter()
-	routes.RegisterCommunityRoutes(r, communityService, communityRepo, e2eAuth.OAuthAuthMiddleware, nil) // nil = allow all community creators
-	routes.RegisterPostRoutes(r, postService, e2eAuth.OAuthAuthMiddleware)
-	routes.RegisterTimelineRoutes(r, timelineService, nil, nil, e2eAuth.OAuthAuthMiddleware)
+	reg := routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware)
+	routes.RegisterCommunityRoutes(reg, communityService, communityRepo, nil, nil) // nil = allow all community creators
+	routes.RegisterPostRoutes(reg, postService)
+	routes.RegisterTimelineRoutes(reg, timelineService, nil, nil, nil, nil, nil)
 	httpServer := httptest.NewServer(r)
 	defer httpServer.Close()
+	client := newTestCovesClient(t, httpServer.URL)
 
 	// Cleanup test data from previous runs (clean up ALL journey test data)
 	timestamp := time.Now().Unix()
@@ -234,34 +235,14 @@ func TestFullUserJourney_E2E(t *testing.T) {
 		shortTS := timestamp % 10000
 		communityName := fmt.Sprintf("gj%d", shortTS) // "gj9261" = 6 chars -> handle = 29 chars
 
-		createReq := map[string]interface{}{
-			"name":                   communityName,
-			"displayName":            "Gaming Journey Community",
-			"description":            "Testing full user journey E2E",
-			"visibility":             "public",
-			"allowExternalDiscovery": true,
-		}
-
-		reqBody, _ := json.Marshal(createReq)
-		req, _ := http.NewRequest(http.MethodPost,
-			httpServer.URL+"/xrpc/social.coves.community.create",
-			bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+userAAPIToken)
-
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-
-		require.Equal(t, http.StatusOK, resp.StatusCode, "Community creation should succeed")
-
-		var createResp struct {
-			URI    string `json:"uri"`
-			CID    string `json:"cid"`
-			DID    string `json:"did"`
-			Handle string `json:"handle"`
-		}
-		require.NoError(t, json.NewDecoder(resp.Body).Decode(&createResp))
+		createResp, err := client.WithToken(userAAPIToken).CreateCommunity(ctx, covesclient.CreateCommunityRequest{
+			Name:                   communityName,
+			DisplayName:            "Gaming Journey Community",
+			Description:            "Testing full user journey E2E",
+			Visibility:             "public",
+			AllowExternalDiscovery: true,
+		})
+		require.NoError(t, err, "Community creation should succeed")
 
 		communityDID = createResp.DID
 		communityHandle = createResp.Handle
@@ -320,27 +301,12 @@ func TestFullUserJourney_E2E(t *testing.T) {
 		title := "My First Gaming Post"
 		content := "This is an E2E test post from the user journey!"
 
-		createReq := map[string]interface{}{
-			"community": communityDID,
-			"title":     title,
-			"content":   content,
-		}
-
-		reqBody, _ := json.Marshal(createReq)
-		req, _ := http.NewRequest(http.MethodPost,
-			httpServer.URL+"/xrpc/social.coves.community.post.create",
-			bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+userAAPIToken)
-
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-
-		require.Equal(t, http.StatusOK, resp.StatusCode, "Post creation should succeed")
-
-		var createResp posts.CreatePostResponse
-		require.NoError(t, json.NewDecoder(resp.Body).Decode(&createResp))
+		createResp, err := client.WithToken(userAAPIToken).CreatePost(ctx, covesclient.CreatePostRequest{
+			Community: communityDID,
+			Title:     &title,
+			Content:   &content,
+		})
+		require.NoError(t, err, "Post creation should succeed")
 
 		postURI = createResp.URI
 		postCID = createResp.CID
@@ -434,29 +400,8 @@ func TestFullUserJourney_E2E(t *testing.T) {
 		require.NoError(t, err)
 		initialCount := initialCommunity.SubscriberCount
 
-		subscribeReq := map[string]interface{}{
-			"community":         communityDID,
-			"contentVisibility": 5,
-		}
-
-		reqBody, _ := json.Marshal(subscribeReq)
-		req, _ := http.NewRequest(http.MethodPost,
-			httpServer.URL+"/xrpc/social.coves.community.subscribe",
-			bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+userBAPIToken)
-
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-
-		require.Equal(t, http.StatusOK, resp.StatusCode, "Subscription should succeed")
-
-		var subscribeResp struct {
-			URI string `json:"uri"`
-			CID string `json:"cid"`
-		}
-		require.NoError(t, json.NewDecoder(resp.Body).Decode(&subscribeResp))
+		subscribeResp, err := client.WithToken(userBAPIToken).Subscribe(ctx, communityDID, 5)
+		require.NoError(t, err, "Subscription should succeed")
 
 		t.Logf("✅ Subscription created: %s", subscribeResp.URI)
 
@@ -676,19 +621,9 @@ func TestFullUserJourney_E2E(t *testing.T) {
 	t.Run("9. User B - Verify Timeline Feed Shows Subscribed Community Posts", func(t *testing.T) {
 		t.Log("\n📰 Part 9: User B checks timeline feed...")
 
-		// Use HTTP client to properly go through auth middleware with Bearer token
-		req, _ := http.NewRequest(http.MethodGet,
-			httpServer.URL+"/xrpc/social.coves.feed.getTimeline?sort=new&limit=10", nil)
-		req.Header.Set("Authorization", "Bearer "+userBAPIToken)
-
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-
-		require.Equal(t, http.StatusOK, resp.StatusCode, "Timeline request should succeed")
-
-		var response timelineCore.TimelineResponse
-		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		// Go through the auth middleware with User B's token
+		response, err := client.WithToken(userBAPIToken).GetTimeline(ctx, covesclient.FeedParams{Sort: "new", Limit: 10})
+		require.NoError(t, err, "Timeline request should succeed")
 
 		// User B should see the post from the community they subscribed to
 		require.NotEmpty(t, response.Feed, "Timeline should contain posts")
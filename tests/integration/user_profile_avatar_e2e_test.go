@@ -1,16 +1,15 @@
 package integration
 
 import (
-	"Coves/internal/api/handlers/user"
 	"Coves/internal/api/routes"
 	"Coves/internal/atproto/identity"
 	"Coves/internal/atproto/jetstream"
 	"Coves/internal/core/users"
 	"Coves/internal/db/postgres"
+	"Coves/pkg/covesclient"
 	"bytes"
 	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
 	"image"
 	"image/color"
@@ -121,7 +120,7 @@ func TestUserProfileAvatarE2E_UpdateWithAvatar(t *testing.T) {
 	// Setup HTTP server with all user routes using password-based PDS client for E2E tests
 	e2eAuth := NewE2EOAuthMiddleware()
 	r := chi.NewRouter()
-	routes.RegisterUserRoutesWithOptions(r, userService, e2eAuth.OAuthAuthMiddleware, nil, &routes.UserRouteOptions{
+	routes.RegisterUserRoutesWithOptions(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), userService, nil, &routes.UserRouteOptions{
 		PDSClientFactory: UserProfilePasswordAuthPDSClientFactory(),
 	})
 	httpServer := httptest.NewServer(r)
@@ -152,6 +151,7 @@ func TestUserProfileAvatarE2E_UpdateWithAvatar(t *testing.T) {
 
 		// Register user with OAuth middleware using real PDS token
 		userAPIToken := e2eAuth.AddUserWithPDSToken(userDID, userToken, pdsURL)
+		client := newTestCovesClient(t, httpServer.URL).WithToken(userAPIToken)
 
 		// Verify user has no avatar initially
 		initialProfile, err := userService.GetProfile(ctx, userDID)
@@ -215,28 +215,15 @@ func TestUserProfileAvatarE2E_UpdateWithAvatar(t *testing.T) {
 		// Build update profile request
 		displayName := "Avatar Test User"
 		bio := "Testing avatar upload E2E"
-		updateReq := user.UpdateProfileRequest{
+		updateReq := covesclient.UpdateProfileRequest{
 			DisplayName:    &displayName,
 			Bio:            &bio,
 			AvatarBlob:     avatarData,
 			AvatarMimeType: "image/png",
 		}
 
-		reqBody, _ := json.Marshal(updateReq)
-		req, _ := http.NewRequest(http.MethodPost,
-			httpServer.URL+"/xrpc/social.coves.actor.updateProfile",
-			bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+userAPIToken)
-
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-
-		require.Equal(t, http.StatusOK, resp.StatusCode, "Update profile should succeed")
-
-		var updateResp user.UpdateProfileResponse
-		require.NoError(t, json.NewDecoder(resp.Body).Decode(&updateResp))
+		updateResp, err := client.UpdateProfile(ctx, updateReq)
+		require.NoError(t, err, "Update profile should succeed")
 
 		t.Logf("Profile update written to PDS:")
 		t.Logf("   URI: %s", updateResp.URI)
@@ -415,7 +402,7 @@ func TestUserProfileAvatarE2E_UpdateWithBanner(t *testing.T) {
 	// Setup HTTP server using password-based PDS client for E2E tests
 	e2eAuth := NewE2EOAuthMiddleware()
 	r := chi.NewRouter()
-	routes.RegisterUserRoutesWithOptions(r, userService, e2eAuth.OAuthAuthMiddleware, nil, &routes.UserRouteOptions{
+	routes.RegisterUserRoutesWithOptions(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), userService, nil, &routes.UserRouteOptions{
 		PDSClientFactory: UserProfilePasswordAuthPDSClientFactory(),
 	})
 	httpServer := httptest.NewServer(r)
@@ -443,6 +430,7 @@ func TestUserProfileAvatarE2E_UpdateWithBanner(t *testing.T) {
 
 		// Register user with OAuth middleware
 		userAPIToken := e2eAuth.AddUserWithPDSToken(userDID, userToken, pdsURL)
+		client := newTestCovesClient(t, httpServer.URL).WithToken(userAPIToken)
 
 		// Verify no banner initially
 		initialProfile, err := userService.GetProfile(ctx, userDID)
@@ -494,27 +482,14 @@ func TestUserProfileAvatarE2E_UpdateWithBanner(t *testing.T) {
 
 		// Build update profile request with banner
 		displayName := "Banner Test User"
-		updateReq := user.UpdateProfileRequest{
+		updateReq := covesclient.UpdateProfileRequest{
 			DisplayName:    &displayName,
 			BannerBlob:     bannerData,
 			BannerMimeType: "image/png",
 		}
 
-		reqBody, _ := json.Marshal(updateReq)
-		req, _ := http.NewRequest(http.MethodPost,
-			httpServer.URL+"/xrpc/social.coves.actor.updateProfile",
-			bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+userAPIToken)
-
-		resp, err := http.DefaultClient.Do(req)
-		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-
-		require.Equal(t, http.StatusOK, resp.StatusCode, "Update profile should succeed")
-
-		var updateResp user.UpdateProfileResponse
-		require.NoError(t, json.NewDecoder(resp.Body).Decode(&updateResp))
+		updateResp, err := client.UpdateProfile(ctx, updateReq)
+		require.NoError(t, err, "Update profile should succeed")
 
 		t.Logf("Profile update written to PDS: URI=%s, CID=%s", updateResp.URI, updateResp.CID)
 
@@ -651,7 +626,7 @@ func TestUserProfileAvatarE2E_UpdateDisplayNameAndBio(t *testing.T) {
 	// Setup HTTP server using password-based PDS client for E2E tests
 	e2eAuth := NewE2EOAuthMiddleware()
 	r := chi.NewRouter()
-	routes.RegisterUserRoutesWithOptions(r, userService, e2eAuth.OAuthAuthMiddleware, nil, &routes.UserRouteOptions{
+	routes.RegisterUserRoutesWithOptions(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), userService, nil, &routes.UserRouteOptions{
 		PDSClientFactory: UserProfilePasswordAuthPDSClientFactory(),
 	})
 	httpServer := httptest.NewServer(r)
@@ -674,6 +649,7 @@ func TestUserProfileAvatarE2E_UpdateDisplayNameAndBio(t *testing.T) {
 		// Index user in AppView
 		_ = createTestUser(t, db, userHandle, userDID)
 		userAPIToken := e2eAuth.AddUserWithPDSToken(userDID, userToken, pdsURL)
+		client := newTestCovesClient(t, httpServer.URL).WithToken(userAPIToken)
 
 		// Subscribe to Jetstream
 		eventChan := make(chan *jetstream.JetstreamEvent, 10)
@@ -717,23 +693,13 @@ func TestUserProfileAvatarE2E_UpdateDisplayNameAndBio(t *testing.T) {
 		// Update with only text fields
 		displayName := "Text Update Test User"
 		bio := "This is my test bio for E2E testing"
-		updateReq := user.UpdateProfileRequest{
+		updateReq := covesclient.UpdateProfileRequest{
 			DisplayName: &displayName,
 			Bio:         &bio,
 		}
 
-		reqBody, _ := json.Marshal(updateReq)
-		req, _ := http.NewRequest(http.MethodPost,
-			httpServer.URL+"/xrpc/social.coves.actor.updateProfile",
-			bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+userAPIToken)
-
-		resp, err := http.DefaultClient.Do(req)
+		_, err = client.UpdateProfile(ctx, updateReq)
 		require.NoError(t, err)
-		defer func() { _ = resp.Body.Close() }()
-
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 
 		// Wait for Jetstream event
 		var realEvent *jetstream.JetstreamEvent
@@ -847,7 +813,7 @@ func TestUserProfileAvatarE2E_ReplaceAvatar(t *testing.T) {
 	// Setup HTTP server using password-based PDS client for E2E tests
 	e2eAuth := NewE2EOAuthMiddleware()
 	r := chi.NewRouter()
-	routes.RegisterUserRoutesWithOptions(r, userService, e2eAuth.OAuthAuthMiddleware, nil, &routes.UserRouteOptions{
+	routes.RegisterUserRoutesWithOptions(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), userService, nil, &routes.UserRouteOptions{
 		PDSClientFactory: UserProfilePasswordAuthPDSClientFactory(),
 	})
 	httpServer := httptest.NewServer(r)
@@ -929,13 +895,14 @@ func TestUserProfileAvatarE2E_ReplaceAvatar(t *testing.T) {
 		// Index user in AppView
 		_ = createTestUser(t, db, userHandle, userDID)
 		userAPIToken := e2eAuth.AddUserWithPDSToken(userDID, userToken, pdsURL)
+		client := newTestCovesClient(t, httpServer.URL).WithToken(userAPIToken)
 
 		// STEP 1: Create initial avatar (red square)
 		t.Logf("\n Step 1: Setting initial avatar (red)...")
 
 		initialAvatarData := createTestAvatarPNG(100, 100, color.RGBA{255, 0, 0, 255})
 		displayName := "Replace Avatar Test"
-		updateReq := user.UpdateProfileRequest{
+		updateReq := covesclient.UpdateProfileRequest{
 			DisplayName:    &displayName,
 			AvatarBlob:     initialAvatarData,
 			AvatarMimeType: "image/png",
@@ -946,17 +913,8 @@ func TestUserProfileAvatarE2E_ReplaceAvatar(t *testing.T) {
 			time.Sleep(500 * time.Millisecond)
 		}()
 
-		reqBody, _ := json.Marshal(updateReq)
-		req, _ := http.NewRequest(http.MethodPost,
-			httpServer.URL+"/xrpc/social.coves.actor.updateProfile",
-			bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set("Authorization", "Bearer "+userAPIToken)
-
-		resp, err := http.DefaultClient.Do(req)
+		_, err = client.UpdateProfile(ctx, updateReq)
 		require.NoError(t, err)
-		_ = resp.Body.Close()
-		require.Equal(t, http.StatusOK, resp.StatusCode)
 
 		// Wait for initial avatar event
 		initialAvatarCID, initialEvent := waitForProfileEvent(t, userDID, 15*time.Second)
@@ -983,22 +941,13 @@ func TestUserProfileAvatarE2E_ReplaceAvatar(t *testing.T) {
 		t.Logf("\n Step 2: Replacing avatar with new one (green)...")
 
 		newAvatarData := createTestAvatarPNG(100, 100, color.RGBA{0, 255, 0, 255})
-		updateReq2 := user.UpdateProfileRequest{
+		updateReq2 := covesclient.UpdateProfileRequest{
 			AvatarBlob:     newAvatarData,
 			AvatarMimeType: "image/png",
 		}
 
-		reqBody2, _ := json.Marshal(updateReq2)
-		req2, _ := http.NewRequest(http.MethodPost,
-			httpServer.URL+"/xrpc/social.coves.actor.updateProfile",
-			bytes.NewBuffer(reqBody2))
-		req2.Header.Set("Content-Type", "application/json")
-		req2.Header.Set("Authorization", "Bearer "+userAPIToken)
-
-		resp2, err := http.DefaultClient.Do(req2)
+		_, err = client.UpdateProfile(ctx, updateReq2)
 		require.NoError(t, err)
-		_ = resp2.Body.Close()
-		require.Equal(t, http.StatusOK, resp2.StatusCode)
 
 		// Wait for replacement avatar event
 		newAvatarCID, newEvent := waitForProfileEvent(t, userDID, 15*time.Second)
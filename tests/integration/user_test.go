@@ -47,7 +47,7 @@ func TestMain(m *testing.M) {
 	os.Exit(m.Run())
 }
 
-func setupTestDB(t *testing.T) *sql.DB {
+func setupTestDB(t testing.TB) *sql.DB {
 	// Build connection string from environment variables (set by .env.dev)
 	testUser := os.Getenv("POSTGRES_TEST_USER")
 	testPassword := os.Getenv("POSTGRES_TEST_PASSWORD")
@@ -238,7 +238,7 @@ func TestGetProfileEndpoint(t *testing.T) {
 	// Set up HTTP router with auth middleware
 	r := chi.NewRouter()
 	authMiddleware, _ := CreateTestOAuthMiddleware("did:plc:testuser")
-	routes.RegisterUserRoutesWithOptions(r, userService, authMiddleware, nil, testUserRouteOptions())
+	routes.RegisterUserRoutesWithOptions(routes.NewRegistrar(r, authMiddleware), userService, nil, testUserRouteOptions())
 
 	// Test 1: Get profile by DID
 	t.Run("Get Profile By DID", func(t *testing.T) {
@@ -867,7 +867,7 @@ func TestGetProfile_NonExistentDID(t *testing.T) {
 	t.Run("HTTP endpoint returns 404 for non-existent DID", func(t *testing.T) {
 		r := chi.NewRouter()
 		authMiddleware, _ := CreateTestOAuthMiddleware("did:plc:testuser")
-		routes.RegisterUserRoutesWithOptions(r, userService, authMiddleware, nil, testUserRouteOptions())
+		routes.RegisterUserRoutesWithOptions(routes.NewRegistrar(r, authMiddleware), userService, nil, testUserRouteOptions())
 
 		req := httptest.NewRequest("GET", "/xrpc/social.coves.actor.getprofile?actor=did:plc:nonexistentuser12345", nil)
 		w := httptest.NewRecorder()
@@ -917,7 +917,7 @@ func TestProfileStatsEndpoint(t *testing.T) {
 	// Set up HTTP router with auth middleware
 	r := chi.NewRouter()
 	authMiddleware, _ := CreateTestOAuthMiddleware("did:plc:testuser")
-	routes.RegisterUserRoutesWithOptions(r, userService, authMiddleware, nil, testUserRouteOptions())
+	routes.RegisterUserRoutesWithOptions(routes.NewRegistrar(r, authMiddleware), userService, nil, testUserRouteOptions())
 
 	t.Run("Response includes stats object", func(t *testing.T) {
 		req := httptest.NewRequest("GET", "/xrpc/social.coves.actor.getprofile?actor="+testDID, nil)
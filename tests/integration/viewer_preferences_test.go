@@ -0,0 +1,83 @@
+package integration
+
+import (
+	"Coves/internal/api/handlers/discover"
+	"Coves/internal/api/middleware"
+	"Coves/internal/core/users"
+	"Coves/internal/db/postgres"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	discoverCore "Coves/internal/core/discover"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestViewerPreferences_HiddenCommunities tests that hiddenCommunities drops a community's
+// posts from discover for that viewer only
+func TestViewerPreferences_HiddenCommunities(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	t.Cleanup(func() { _ = db.Close() })
+
+	ctx := context.Background()
+	testID := time.Now().UnixNano()
+	viewerDID := fmt.Sprintf("did:plc:hider-%d", testID)
+
+	noisyDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("noisy-%d", testID), fmt.Sprintf("noisyowner-%d.test", testID))
+	require.NoError(t, err)
+	quietDID, err := createFeedTestCommunity(db, ctx, fmt.Sprintf("quiet-%d", testID), fmt.Sprintf("quietowner-%d.test", testID))
+	require.NoError(t, err)
+	noisyPost := createTestPost(t, db, noisyDID, "did:plc:alice", "Noisy", 10, time.Now().Add(-time.Minute))
+	quietPost := createTestPost(t, db, quietDID, "did:plc:alice", "Quiet", 10, time.Now().Add(-time.Minute))
+
+	prefsRepo := postgres.NewUserPreferencesRepository(db)
+	prefs, err := users.ParsePreferences(viewerDID, fmt.Sprintf("at://%s/%s/self", viewerDID, users.PreferencesCollection), "bafyprefs",
+		map[string]interface{}{"hiddenCommunities": []interface{}{noisyDID}, "defaultFeedSort": "new"})
+	require.NoError(t, err)
+	require.NoError(t, prefsRepo.Upsert(ctx, prefs))
+
+	handler := discover.NewGetDiscoverHandler(
+		discoverCore.NewDiscoverService(postgres.NewDiscoverRepository(db, newTestCursorSigner())),
+		nil, nil, nil, nil)
+	handler.SetPreferences(prefsRepo)
+
+	discoverURIs := func(viewer string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/xrpc/social.coves.feed.getDiscover?limit=50", nil)
+		if viewer != "" {
+			req = req.WithContext(middleware.SetTestUserDID(req.Context(), viewer))
+		}
+		rec := httptest.NewRecorder()
+		handler.HandleGetDiscover(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		var response struct {
+			Feed []struct {
+				Post struct {
+					URI string `json:"uri"`
+				} `json:"post"`
+			} `json:"feed"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		var uris []string
+		for _, item := range response.Feed {
+			uris = append(uris, item.Post.URI)
+		}
+		return uris
+	}
+
+	uris := discoverURIs(viewerDID)
+	assert.Contains(t, uris, quietPost)
+	assert.NotContains(t, uris, noisyPost, "hidden community must be left out for the viewer")
+
+	assert.Contains(t, discoverURIs(""), noisyPost, "other viewers still see the community")
+}
@@ -6,7 +6,7 @@ import (
 	"Coves/internal/atproto/utils"
 	"Coves/internal/core/votes"
 	"Coves/internal/db/postgres"
-	"bytes"
+	"Coves/pkg/covesclient"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -78,7 +78,7 @@ func TestVoteE2E_CreateUpvote(t *testing.T) {
 
 	// Setup repositories
 	voteRepo := postgres.NewVoteRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	// Setup services with password-based PDS client factory for E2E testing
 	voteService := votes.NewServiceWithPDSFactory(voteRepo, nil, nil, PasswordAuthPDSClientFactory())
@@ -113,7 +113,7 @@ func TestVoteE2E_CreateUpvote(t *testing.T) {
 
 	// Setup HTTP server with XRPC routes
 	r := chi.NewRouter()
-	routes.RegisterVoteRoutes(r, voteService, e2eAuth.OAuthAuthMiddleware)
+	routes.RegisterVoteRoutes(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), voteService)
 	httpServer := httptest.NewServer(r)
 	defer httpServer.Close()
 
@@ -125,52 +125,10 @@ func TestVoteE2E_CreateUpvote(t *testing.T) {
 	// ====================================================================================
 	t.Logf("\n📝 Creating upvote via XRPC endpoint...")
 
-	voteReq := map[string]interface{}{
-		"subject": map[string]interface{}{
-			"uri": postURI,
-			"cid": postCID,
-		},
-		"direction": "up",
-	}
-
-	reqBody, marshalErr := json.Marshal(voteReq)
-	if marshalErr != nil {
-		t.Fatalf("Failed to marshal request: %v", marshalErr)
-	}
-
-	req, err := http.NewRequest(http.MethodPost,
-		httpServer.URL+"/xrpc/social.coves.feed.vote.create",
-		bytes.NewBuffer(reqBody))
-	if err != nil {
-		t.Fatalf("Failed to create request: %v", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	resp, err := http.DefaultClient.Do(req)
+	client := newTestCovesClient(t, httpServer.URL).WithToken(token)
+	voteResp, err := client.Vote(ctx, postURI, postCID, covesclient.VoteUp)
 	if err != nil {
-		t.Fatalf("Failed to POST vote: %v", err)
-	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		body, readErr := io.ReadAll(resp.Body)
-		if readErr != nil {
-			t.Fatalf("Expected 200, got %d (failed to read body: %v)", resp.StatusCode, readErr)
-		}
-		t.Logf("XRPC Vote Failed")
-		t.Logf("   Status: %d", resp.StatusCode)
-		t.Logf("   Response: %s", string(body))
-		t.Fatalf("Expected 200, got %d: %s", resp.StatusCode, string(body))
-	}
-
-	var voteResp struct {
-		URI string `json:"uri"`
-		CID string `json:"cid"`
-	}
-
-	if decodeErr := json.NewDecoder(resp.Body).Decode(&voteResp); decodeErr != nil {
-		t.Fatalf("Failed to decode vote response: %v", decodeErr)
+		t.Fatalf("Failed to create vote: %v", err)
 	}
 
 	t.Logf("✅ XRPC response received:")
@@ -302,7 +260,7 @@ func TestVoteE2E_ToggleSameDirection(t *testing.T) {
 
 	// Setup repositories and services
 	voteRepo := postgres.NewVoteRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	voteService := votes.NewServiceWithPDSFactory(voteRepo, nil, nil, PasswordAuthPDSClientFactory())
 
@@ -328,7 +286,7 @@ func TestVoteE2E_ToggleSameDirection(t *testing.T) {
 	token := e2eAuth.AddUserWithPDSToken(userDID, pdsAccessToken, pdsURL)
 
 	r := chi.NewRouter()
-	routes.RegisterVoteRoutes(r, voteService, e2eAuth.OAuthAuthMiddleware)
+	routes.RegisterVoteRoutes(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), voteService)
 	httpServer := httptest.NewServer(r)
 	defer httpServer.Close()
 
@@ -336,37 +294,12 @@ func TestVoteE2E_ToggleSameDirection(t *testing.T) {
 
 	// First upvote
 	t.Logf("\n📝 Creating first upvote...")
-	voteReq := map[string]interface{}{
-		"subject": map[string]interface{}{
-			"uri": postURI,
-			"cid": postCID,
-		},
-		"direction": "up",
-	}
-
-	reqBody, _ := json.Marshal(voteReq)
-	req, _ := http.NewRequest(http.MethodPost,
-		httpServer.URL+"/xrpc/social.coves.feed.vote.create",
-		bytes.NewBuffer(reqBody))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	resp, err := http.DefaultClient.Do(req)
+	client := newTestCovesClient(t, httpServer.URL).WithToken(token)
+	firstVoteResp, err := client.Vote(ctx, postURI, postCID, covesclient.VoteUp)
 	if err != nil {
 		t.Fatalf("Failed to create first vote: %v", err)
 	}
 
-	var firstVoteResp struct {
-		URI string `json:"uri"`
-		CID string `json:"cid"`
-	}
-	if decodeErr := json.NewDecoder(resp.Body).Decode(&firstVoteResp); decodeErr != nil {
-		t.Fatalf("Failed to decode first vote response: %v", decodeErr)
-	}
-	if closeErr := resp.Body.Close(); closeErr != nil {
-		t.Logf("Failed to close response body: %v", closeErr)
-	}
-
 	t.Logf("✅ First vote created: %s", firstVoteResp.URI)
 
 	// Index first vote
@@ -398,26 +331,9 @@ func TestVoteE2E_ToggleSameDirection(t *testing.T) {
 
 	// Second upvote (same direction) - should toggle off (delete)
 	t.Logf("\n📝 Creating second upvote (toggle off)...")
-	req2, _ := http.NewRequest(http.MethodPost,
-		httpServer.URL+"/xrpc/social.coves.feed.vote.create",
-		bytes.NewBuffer(reqBody))
-	req2.Header.Set("Content-Type", "application/json")
-	req2.Header.Set("Authorization", "Bearer "+token)
-
-	resp2, err := http.DefaultClient.Do(req2)
-	if err != nil {
+	if _, err := client.Vote(ctx, postURI, postCID, covesclient.VoteUp); err != nil {
 		t.Fatalf("Failed to toggle vote: %v", err)
 	}
-	defer func() {
-		if closeErr := resp2.Body.Close(); closeErr != nil {
-			t.Logf("Failed to close response body: %v", closeErr)
-		}
-	}()
-
-	if resp2.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp2.Body)
-		t.Fatalf("Expected 200, got %d: %s", resp2.StatusCode, string(body))
-	}
 
 	t.Logf("✅ Second vote request completed (toggle)")
 
@@ -471,7 +387,7 @@ func TestVoteE2E_ToggleDifferentDirection(t *testing.T) {
 
 	// Setup repositories and services
 	voteRepo := postgres.NewVoteRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	voteService := votes.NewServiceWithPDSFactory(voteRepo, nil, nil, PasswordAuthPDSClientFactory())
 
@@ -497,7 +413,7 @@ func TestVoteE2E_ToggleDifferentDirection(t *testing.T) {
 	token := e2eAuth.AddUserWithPDSToken(userDID, pdsAccessToken, pdsURL)
 
 	r := chi.NewRouter()
-	routes.RegisterVoteRoutes(r, voteService, e2eAuth.OAuthAuthMiddleware)
+	routes.RegisterVoteRoutes(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), voteService)
 	httpServer := httptest.NewServer(r)
 	defer httpServer.Close()
 
@@ -505,35 +421,11 @@ func TestVoteE2E_ToggleDifferentDirection(t *testing.T) {
 
 	// Create upvote
 	t.Logf("\n📝 Creating upvote...")
-	upvoteReq := map[string]interface{}{
-		"subject": map[string]interface{}{
-			"uri": postURI,
-			"cid": postCID,
-		},
-		"direction": "up",
-	}
-
-	reqBody, _ := json.Marshal(upvoteReq)
-	req, _ := http.NewRequest(http.MethodPost,
-		httpServer.URL+"/xrpc/social.coves.feed.vote.create",
-		bytes.NewBuffer(reqBody))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	resp, err := http.DefaultClient.Do(req)
+	client := newTestCovesClient(t, httpServer.URL).WithToken(token)
+	upvoteResp, err := client.Vote(ctx, postURI, postCID, covesclient.VoteUp)
 	if err != nil {
 		t.Fatalf("Failed to create upvote: %v", err)
 	}
-	var upvoteResp struct {
-		URI string `json:"uri"`
-		CID string `json:"cid"`
-	}
-	if decodeErr := json.NewDecoder(resp.Body).Decode(&upvoteResp); decodeErr != nil {
-		t.Fatalf("Failed to decode upvote response: %v", decodeErr)
-	}
-	if closeErr := resp.Body.Close(); closeErr != nil {
-		t.Logf("Failed to close response body: %v", closeErr)
-	}
 
 	// Index upvote
 	rkey := utils.ExtractRKeyFromURI(upvoteResp.URI)
@@ -566,39 +458,14 @@ func TestVoteE2E_ToggleDifferentDirection(t *testing.T) {
 
 	// Change to downvote
 	t.Logf("\n📝 Changing to downvote...")
-	downvoteReq := map[string]interface{}{
-		"subject": map[string]interface{}{
-			"uri": postURI,
-			"cid": postCID,
-		},
-		"direction": "down",
-	}
-
-	reqBody2, _ := json.Marshal(downvoteReq)
-	req2, _ := http.NewRequest(http.MethodPost,
-		httpServer.URL+"/xrpc/social.coves.feed.vote.create",
-		bytes.NewBuffer(reqBody2))
-	req2.Header.Set("Content-Type", "application/json")
-	req2.Header.Set("Authorization", "Bearer "+token)
-
-	resp2, err := http.DefaultClient.Do(req2)
+	downvoteResp, err := client.Vote(ctx, postURI, postCID, covesclient.VoteDown)
 	if err != nil {
 		t.Fatalf("Failed to create downvote: %v", err)
 	}
-	var downvoteResp struct {
-		URI string `json:"uri"`
-		CID string `json:"cid"`
-	}
-	if decodeErr := json.NewDecoder(resp2.Body).Decode(&downvoteResp); decodeErr != nil {
-		t.Fatalf("Failed to decode downvote response: %v", decodeErr)
-	}
-	if closeErr := resp2.Body.Close(); closeErr != nil {
-		t.Logf("Failed to close response body: %v", closeErr)
-	}
 
-	// The service flow for direction change is:
-	// 1. DELETE old vote on PDS
-	// 2. CREATE new vote with NEW rkey on PDS
+	// The service changes direction with one applyWrites commit that:
+	// 1. DELETEs the old vote on PDS
+	// 2. CREATEs the new vote with a NEW rkey on PDS
 	// So we simulate DELETE + CREATE events (not UPDATE)
 
 	// Simulate Jetstream DELETE event for old vote
@@ -696,7 +563,7 @@ func TestVoteE2E_DeleteVote(t *testing.T) {
 
 	// Setup repositories and services
 	voteRepo := postgres.NewVoteRepository(db)
-	postRepo := postgres.NewPostRepository(db)
+	postRepo := postgres.NewPostRepository(db, newTestCursorSigner())
 
 	voteService := votes.NewServiceWithPDSFactory(voteRepo, nil, nil, PasswordAuthPDSClientFactory())
 
@@ -722,7 +589,7 @@ func TestVoteE2E_DeleteVote(t *testing.T) {
 	token := e2eAuth.AddUserWithPDSToken(userDID, pdsAccessToken, pdsURL)
 
 	r := chi.NewRouter()
-	routes.RegisterVoteRoutes(r, voteService, e2eAuth.OAuthAuthMiddleware)
+	routes.RegisterVoteRoutes(routes.NewRegistrar(r, e2eAuth.OAuthAuthMiddleware), voteService)
 	httpServer := httptest.NewServer(r)
 	defer httpServer.Close()
 
@@ -730,35 +597,11 @@ func TestVoteE2E_DeleteVote(t *testing.T) {
 
 	// Create vote first
 	t.Logf("\n📝 Creating vote to delete...")
-	voteReq := map[string]interface{}{
-		"subject": map[string]interface{}{
-			"uri": postURI,
-			"cid": postCID,
-		},
-		"direction": "up",
-	}
-
-	reqBody, _ := json.Marshal(voteReq)
-	req, _ := http.NewRequest(http.MethodPost,
-		httpServer.URL+"/xrpc/social.coves.feed.vote.create",
-		bytes.NewBuffer(reqBody))
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+token)
-
-	resp, err := http.DefaultClient.Do(req)
+	client := newTestCovesClient(t, httpServer.URL).WithToken(token)
+	voteResp, err := client.Vote(ctx, postURI, postCID, covesclient.VoteUp)
 	if err != nil {
 		t.Fatalf("Failed to create vote: %v", err)
 	}
-	var voteResp struct {
-		URI string `json:"uri"`
-		CID string `json:"cid"`
-	}
-	if decodeErr := json.NewDecoder(resp.Body).Decode(&voteResp); decodeErr != nil {
-		t.Fatalf("Failed to decode vote response: %v", decodeErr)
-	}
-	if closeErr := resp.Body.Close(); closeErr != nil {
-		t.Logf("Failed to close response body: %v", closeErr)
-	}
 
 	// Index vote
 	rkey := utils.ExtractRKeyFromURI(voteResp.URI)
@@ -791,44 +634,9 @@ func TestVoteE2E_DeleteVote(t *testing.T) {
 
 	// Delete vote via XRPC
 	t.Logf("\n📝 Deleting vote via XRPC...")
-	deleteReq := map[string]interface{}{
-		"subject": map[string]interface{}{
-			"uri": postURI,
-			"cid": postCID,
-		},
-	}
-
-	deleteBody, _ := json.Marshal(deleteReq)
-	deleteHttpReq, _ := http.NewRequest(http.MethodPost,
-		httpServer.URL+"/xrpc/social.coves.feed.vote.delete",
-		bytes.NewBuffer(deleteBody))
-	deleteHttpReq.Header.Set("Content-Type", "application/json")
-	deleteHttpReq.Header.Set("Authorization", "Bearer "+token)
-
-	deleteResp, err := http.DefaultClient.Do(deleteHttpReq)
-	if err != nil {
+	if err := client.DeleteVote(ctx, postURI, postCID); err != nil {
 		t.Fatalf("Failed to delete vote: %v", err)
 	}
-	defer func() {
-		if closeErr := deleteResp.Body.Close(); closeErr != nil {
-			t.Logf("Failed to close response body: %v", closeErr)
-		}
-	}()
-
-	if deleteResp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(deleteResp.Body)
-		t.Fatalf("Delete failed: status %d, body: %s", deleteResp.StatusCode, string(body))
-	}
-
-	// Per lexicon, delete returns empty object {}
-	var deleteRespBody map[string]interface{}
-	if decodeErr := json.NewDecoder(deleteResp.Body).Decode(&deleteRespBody); decodeErr != nil {
-		t.Fatalf("Failed to decode delete response: %v", decodeErr)
-	}
-
-	if len(deleteRespBody) != 0 {
-		t.Errorf("Expected empty object per lexicon, got %v", deleteRespBody)
-	}
 
 	t.Logf("✅ Delete vote request succeeded")
 
@@ -0,0 +1,242 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/users"
+	"Coves/internal/core/votes"
+	"Coves/internal/db/postgres"
+	"Coves/internal/db/txrunner"
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// voteFixture is one vote record, as it appears both in a Jetstream commit and in a
+// listRecords page read by the reindex-votes tool
+type voteFixture struct {
+	voterDID   string
+	rkey       string
+	subjectURI string
+	direction  string
+	createdAt  time.Time
+	invalid    bool
+}
+
+func (f voteFixture) value() map[string]interface{} {
+	return map[string]interface{}{
+		"$type":     "social.coves.feed.vote",
+		"subject":   map[string]interface{}{"uri": f.subjectURI, "cid": "bafysubject"},
+		"direction": f.direction,
+		"createdAt": f.createdAt.Format(time.RFC3339),
+	}
+}
+
+// voteRow is the indexed state of one vote
+type voteRow struct {
+	CreatedAt  time.Time
+	RawRecord  sql.NullString
+	URI        string
+	CID        string
+	RKey       string
+	VoterDID   string
+	SubjectURI string
+	SubjectCID string
+	Direction  string
+	Deleted    bool
+	Nullified  bool
+}
+
+// subjectRow is the counter state of one post or comment
+type subjectRow struct {
+	URI       string
+	Upvotes   int
+	Downvotes int
+	Score     int
+	HotScore  float64
+}
+
+// TestVoteIndexer_ConsumerAndReindexAgree runs one fixture through the Jetstream vote consumer
+// and through the reindex-votes tool's path, and checks both leave identical votes and counters
+func TestVoteIndexer_ConsumerAndReindexAgree(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	suffix := uniqueTestID()
+
+	voteRepo := postgres.NewVoteRepository(db)
+	userService := users.NewUserService(postgres.NewUserRepository(db), nil, getTestPDSURL())
+	consumer := jetstream.NewVoteEventConsumer(voteRepo, userService, db)
+
+	communityDID, err := createFeedTestCommunity(db, ctx, "voteindex"+suffix, "voteindexowner"+suffix)
+	require.NoError(t, err)
+	authorDID := "did:plc:voteindexauthor" + suffix
+	post1 := createTestPost(t, db, communityDID, authorDID, "First", 0, time.Now().Add(-time.Hour))
+	post2 := createTestPost(t, db, communityDID, authorDID, "Second", 0, time.Now().Add(-2*time.Hour))
+	comment := fmt.Sprintf("at://%s/social.coves.community.comment/voteindex%s", authorDID, suffix)
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO comments (uri, cid, rkey, commenter_did, root_uri, root_cid, parent_uri, parent_cid, content, created_at)
+		VALUES ($1, 'bafycomment', $2, $3, $4, 'bafytest', $4, 'bafytest', 'Voted on', NOW())
+	`, comment, "voteindex"+suffix, authorDID, post1)
+	require.NoError(t, err)
+	foreign := "at://did:plc:elsewhere/app.bsky.feed.post/3kforeign"
+
+	alice := "did:plc:voteindexalice" + suffix
+	bob := "did:plc:voteindexbob" + suffix
+	carol := "did:plc:voteindexcarol" + suffix
+	voters := []string{alice, bob, carol}
+
+	// Carol's votes are neutralized: stored, but never counted
+	_, err = voteRepo.RemoveVotesByVoter(ctx, carol, votes.NullifiedByAdmin)
+	require.NoError(t, err)
+
+	at := time.Now().Add(-30 * time.Minute).UTC().Truncate(time.Second)
+	fixture := []voteFixture{
+		{voterDID: alice, rkey: generateTID(), subjectURI: post1, direction: "up", createdAt: at},
+		{voterDID: bob, rkey: generateTID(), subjectURI: post1, direction: "down", createdAt: at},
+		{voterDID: carol, rkey: generateTID(), subjectURI: post1, direction: "up", createdAt: at},
+		// Alice's first vote on post2 is stale: a later record on the same subject replaces it
+		{voterDID: alice, rkey: generateTID(), subjectURI: post2, direction: "down", createdAt: at},
+		{voterDID: alice, rkey: generateTID(), subjectURI: post2, direction: "up", createdAt: at.Add(time.Minute)},
+		{voterDID: bob, rkey: generateTID(), subjectURI: comment, direction: "up", createdAt: at},
+		{voterDID: alice, rkey: generateTID(), subjectURI: comment, direction: "down", createdAt: at},
+		// Indexed, but there's nothing to count it on
+		{voterDID: bob, rkey: generateTID(), subjectURI: foreign, direction: "up", createdAt: at},
+		{voterDID: bob, rkey: generateTID(), subjectURI: post2, direction: "sideways", createdAt: at, invalid: true},
+	}
+	// A replayed record is indexed once
+	fixture = append(fixture, fixture[0])
+	subjects := []string{post1, post2, comment}
+
+	snapshot := func(t *testing.T) ([]voteRow, []subjectRow) {
+		t.Helper()
+		rows, err := db.QueryContext(ctx, `
+			SELECT uri, cid, rkey, voter_did, subject_uri, subject_cid, direction, created_at, raw_record,
+			       deleted_at IS NOT NULL, nullified_at IS NOT NULL
+			FROM votes WHERE voter_did = ANY($1) ORDER BY uri
+		`, pq.Array(voters))
+		require.NoError(t, err)
+		defer func() { _ = rows.Close() }()
+		var voteRows []voteRow
+		for rows.Next() {
+			var r voteRow
+			require.NoError(t, rows.Scan(&r.URI, &r.CID, &r.RKey, &r.VoterDID, &r.SubjectURI, &r.SubjectCID,
+				&r.Direction, &r.CreatedAt, &r.RawRecord, &r.Deleted, &r.Nullified))
+			r.CreatedAt = r.CreatedAt.UTC()
+			voteRows = append(voteRows, r)
+		}
+		require.NoError(t, rows.Err())
+
+		var subjectRows []subjectRow
+		for _, uri := range subjects {
+			r := subjectRow{URI: uri}
+			err := db.QueryRowContext(ctx, `
+				SELECT upvote_count, downvote_count, score, hot_score FROM posts WHERE uri = $1
+				UNION ALL
+				SELECT upvote_count, downvote_count, score, 0 FROM comments WHERE uri = $1
+			`, uri).Scan(&r.Upvotes, &r.Downvotes, &r.Score, &r.HotScore)
+			require.NoError(t, err)
+			subjectRows = append(subjectRows, r)
+		}
+		return voteRows, subjectRows
+	}
+
+	reset := func(t *testing.T) {
+		t.Helper()
+		_, err := db.ExecContext(ctx, `DELETE FROM votes WHERE voter_did = ANY($1)`, pq.Array(voters))
+		require.NoError(t, err)
+		_, err = db.ExecContext(ctx, `UPDATE posts SET upvote_count = 0, downvote_count = 0, score = 0, hot_score = 0 WHERE uri = ANY($1)`, pq.Array(subjects))
+		require.NoError(t, err)
+		_, err = db.ExecContext(ctx, `UPDATE comments SET upvote_count = 0, downvote_count = 0, score = 0 WHERE uri = ANY($1)`, pq.Array(subjects))
+		require.NoError(t, err)
+	}
+
+	// Path 1: the Jetstream consumer
+	for _, f := range fixture {
+		err := consumer.HandleEvent(ctx, &jetstream.JetstreamEvent{
+			Did:  f.voterDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Rev:        "rev",
+				Operation:  "create",
+				Collection: "social.coves.feed.vote",
+				RKey:       f.rkey,
+				CID:        "bafy" + f.rkey,
+				Record:     f.value(),
+			},
+		})
+		if f.invalid {
+			require.Error(t, err)
+			assert.True(t, votes.IsValidationError(err))
+		} else {
+			require.NoError(t, err)
+		}
+	}
+	consumerVotes, consumerSubjects := snapshot(t)
+
+	t.Run("consumer state matches the fixture", func(t *testing.T) {
+		require.Len(t, consumerVotes, 8, "every valid record is stored once")
+		assert.Equal(t, subjectRow{URI: post1, Upvotes: 1, Downvotes: 1, Score: 0}, withoutHotScore(consumerSubjects[0]))
+		assert.Equal(t, subjectRow{URI: post2, Upvotes: 1, Downvotes: 0, Score: 1}, withoutHotScore(consumerSubjects[1]))
+		assert.Equal(t, subjectRow{URI: comment, Upvotes: 1, Downvotes: 1, Score: 0}, withoutHotScore(consumerSubjects[2]))
+		assert.Greater(t, consumerSubjects[1].HotScore, 0.0, "votes rescore posts")
+
+		for _, v := range consumerVotes {
+			if v.VoterDID == carol {
+				assert.True(t, v.Deleted && v.Nullified, "a nullified voter's vote is stored neutralized")
+			}
+		}
+	})
+
+	// Path 2: the reindex-votes tool, one transaction per listRecords entry
+	reset(t)
+	indexer := votes.NewIndexer()
+	for _, f := range fixture {
+		err := txrunner.WithTx(ctx, db, func(ctx context.Context) error {
+			_, _, err := indexer.IndexVote(ctx, txrunner.From(ctx, db), f.voterDID, votes.Record{
+				URI:   fmt.Sprintf("at://%s/social.coves.feed.vote/%s", f.voterDID, f.rkey),
+				CID:   "bafy" + f.rkey,
+				Value: f.value(),
+			})
+			return err
+		})
+		if f.invalid {
+			require.Error(t, err)
+		} else {
+			require.NoError(t, err)
+		}
+	}
+	reindexVotes, reindexSubjects := snapshot(t)
+
+	t.Run("reindex produces the same votes", func(t *testing.T) {
+		assert.Equal(t, consumerVotes, reindexVotes)
+	})
+
+	t.Run("reindex produces the same counters", func(t *testing.T) {
+		require.Len(t, reindexSubjects, len(consumerSubjects))
+		for i := range consumerSubjects {
+			assert.Equal(t, withoutHotScore(consumerSubjects[i]), withoutHotScore(reindexSubjects[i]))
+			// Hot scores decay with time, so runs moments apart differ in the last digits
+			assert.InDelta(t, consumerSubjects[i].HotScore, reindexSubjects[i].HotScore, 1e-6, consumerSubjects[i].URI)
+		}
+	})
+}
+
+func withoutHotScore(r subjectRow) subjectRow {
+	r.HotScore = 0
+	return r
+}
@@ -0,0 +1,214 @@
+package integration
+
+import (
+	"Coves/internal/atproto/jetstream"
+	"Coves/internal/core/users"
+	"Coves/internal/core/votes"
+	"Coves/internal/db/postgres"
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestVoteNullification_ExactCounters seeds a few hundred votes, nullifies the voter
+// while other voters keep voting on the same posts, and restores them, checking every
+// post's counters against the expected totals at each step
+func TestVoteNullification_ExactCounters(t *testing.T) {
+	db := setupTestDB(t)
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Failed to close database: %v", err)
+		}
+	}()
+
+	ctx := context.Background()
+	voteRepo := postgres.NewVoteRepository(db)
+	userService := users.NewUserService(postgres.NewUserRepository(db), nil, "http://localhost:3001")
+	voteConsumer := jetstream.NewVoteEventConsumer(voteRepo, userService, db)
+
+	testID := uniqueTestID()
+	ownerHandle := "nullifyowner" + testID
+	communityDID, err := createFeedTestCommunity(db, ctx, "nullify"+testID, ownerHandle)
+	if err != nil {
+		t.Fatalf("Failed to create community: %v", err)
+	}
+	authorDID := fmt.Sprintf("did:plc:%s", ownerHandle)
+
+	manipulator := fmt.Sprintf("did:plc:manipulator%s", testID)
+	bystander := fmt.Sprintf("did:plc:bystander%s", testID)
+	latecomer := fmt.Sprintf("did:plc:latecomer%s", testID)
+
+	const numPosts = 300
+	postURIs := make([]string, numPosts)
+	for i := range postURIs {
+		rkey := fmt.Sprintf("nullify%s-%d", testID, i)
+		postURIs[i] = fmt.Sprintf("at://%s/social.coves.community.post/%s", communityDID, rkey)
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO posts (uri, cid, rkey, author_did, community_did, title, created_at)
+			VALUES ($1, 'bafypost', $2, $3, $4, 'Nullification test', NOW())
+		`, postURIs[i], rkey, authorDID, communityDID)
+		if err != nil {
+			t.Fatalf("Failed to create post %d: %v", i, err)
+		}
+	}
+
+	vote := func(voterDID, rkey, subjectURI, direction string) {
+		t.Helper()
+		event := &jetstream.JetstreamEvent{
+			Did:  voterDID,
+			Kind: "commit",
+			Commit: &jetstream.CommitEvent{
+				Operation:  "create",
+				Collection: "social.coves.feed.vote",
+				RKey:       rkey,
+				CID:        "bafyvote",
+				Record: map[string]interface{}{
+					"$type":     "social.coves.feed.vote",
+					"subject":   map[string]interface{}{"uri": subjectURI, "cid": "bafypost"},
+					"direction": direction,
+					"createdAt": time.Now().Format(time.RFC3339),
+				},
+			},
+		}
+		if err := voteConsumer.HandleEvent(ctx, event); err != nil {
+			t.Errorf("Failed to index vote %s by %s: %v", rkey, voterDID, err)
+		}
+	}
+
+	// manipulator: down on every 3rd post, up on the rest; bystander: up on even posts
+	manipulatorDirection := func(i int) string {
+		if i%3 == 0 {
+			return "down"
+		}
+		return "up"
+	}
+	for i, uri := range postURIs {
+		vote(manipulator, fmt.Sprintf("m%d", i), uri, manipulatorDirection(i))
+		if i%2 == 0 {
+			vote(bystander, fmt.Sprintf("b%d", i), uri, "up")
+		}
+	}
+
+	type counts struct{ up, down int }
+	expected := func(withManipulator, withLatecomer bool) []counts {
+		want := make([]counts, numPosts)
+		for i := range want {
+			if i%2 == 0 {
+				want[i].up++ // bystander
+			}
+			if withLatecomer && i%5 == 0 {
+				want[i].down++
+			}
+			if withManipulator {
+				if manipulatorDirection(i) == "up" {
+					want[i].up++
+				} else {
+					want[i].down++
+				}
+			}
+		}
+		return want
+	}
+	assertCounts := func(step string, want []counts) {
+		t.Helper()
+		mismatches := 0
+		for i, uri := range postURIs {
+			var up, down, score int
+			if err := db.QueryRowContext(ctx, `SELECT upvote_count, downvote_count, score FROM posts WHERE uri = $1`, uri).Scan(&up, &down, &score); err != nil {
+				t.Fatalf("%s: failed to read counts: %v", step, err)
+			}
+			if up != want[i].up || down != want[i].down || score != want[i].up-want[i].down {
+				mismatches++
+				if mismatches <= 5 {
+					t.Errorf("%s: post %d counts up=%d down=%d score=%d, want up=%d down=%d", step, i, up, down, score, want[i].up, want[i].down)
+				}
+			}
+		}
+		if mismatches > 5 {
+			t.Errorf("%s: %d posts with wrong counters", step, mismatches)
+		}
+	}
+
+	assertCounts("seeded", expected(true, false))
+
+	// Nullify while another voter is voting on the same posts
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < numPosts; i += 5 {
+			vote(latecomer, fmt.Sprintf("l%d", i), postURIs[i], "down")
+		}
+	}()
+	nullified, err := voteRepo.RemoveVotesByVoter(ctx, manipulator, votes.NullifiedByAdmin)
+	wg.Wait()
+	if err != nil {
+		t.Fatalf("RemoveVotesByVoter failed: %v", err)
+	}
+	if nullified != numPosts {
+		t.Errorf("Expected %d votes nullified, got %d", numPosts, nullified)
+	}
+	assertCounts("nullified", expected(false, true))
+
+	// Re-running is a no-op
+	if n, err := voteRepo.RemoveVotesByVoter(ctx, manipulator, votes.NullifiedByAdmin); err != nil || n != 0 {
+		t.Errorf("Expected idempotent re-run, got n=%d err=%v", n, err)
+	}
+
+	// Votes indexed while nullified don't count; votes deleted while nullified stay deleted
+	extraRKey := "m-extra"
+	extraPost := postURIs[1]
+	vote(manipulator, extraRKey, extraPost, "down") // supersedes the nullified up vote on post 1
+	deleteEvent := &jetstream.JetstreamEvent{
+		Did:  manipulator,
+		Kind: "commit",
+		Commit: &jetstream.CommitEvent{
+			Operation:  "delete",
+			Collection: "social.coves.feed.vote",
+			RKey:       "m2",
+		},
+	}
+	if err := voteConsumer.HandleEvent(ctx, deleteEvent); err != nil {
+		t.Fatalf("Failed to delete nullified vote: %v", err)
+	}
+	assertCounts("votes while nullified", expected(false, true))
+
+	// Reactivating the account must not undo an admin nullification
+	if n, err := voteRepo.RestoreVotesByVoter(ctx, manipulator, votes.NullifiedTakendown, votes.NullifiedSuspended); err != nil || n != 0 {
+		t.Errorf("Expected account-status restore to skip admin nullification, got n=%d err=%v", n, err)
+	}
+	assertCounts("reactivation skipped", expected(false, true))
+
+	restored, err := voteRepo.RestoreVotesByVoter(ctx, manipulator)
+	if err != nil {
+		t.Fatalf("RestoreVotesByVoter failed: %v", err)
+	}
+	// 300 original - 1 superseded - 1 deleted + 1 new
+	if restored != numPosts-1 {
+		t.Errorf("Expected %d votes restored, got %d", numPosts-1, restored)
+	}
+
+	want := expected(true, true)
+	want[1] = counts{up: want[1].up - 1, down: want[1].down + 1} // up replaced by the new down vote
+	want[2] = counts{up: want[2].up - 1, down: want[2].down}     // deleted by its author
+	assertCounts("restored", want)
+
+	if active := countActiveVotes(t, db, manipulator); active != numPosts-1 {
+		t.Errorf("Expected %d active votes after restore, got %d", numPosts-1, active)
+	}
+	if _, err := voteRepo.GetByURI(ctx, fmt.Sprintf("at://%s/social.coves.feed.vote/%s", manipulator, extraRKey)); err != nil {
+		t.Errorf("Expected vote indexed while nullified to be active after restore: %v", err)
+	}
+}
+
+func countActiveVotes(t *testing.T, db *sql.DB, voterDID string) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM votes WHERE voter_did = $1 AND deleted_at IS NULL`, voterDID).Scan(&n); err != nil {
+		t.Fatalf("Failed to count votes: %v", err)
+	}
+	return n
+}
@@ -0,0 +1,144 @@
+package unit
+
+import (
+	"Coves/internal/core/communities"
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestValidateCategories(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories []string
+		wantErr    bool
+	}{
+		{name: "no categories", categories: nil},
+		{name: "known categories", categories: []string{communities.CategoryGaming, communities.CategoryScience}},
+		{name: "maximum categories", categories: []string{"art", "books", "music"}},
+		{name: "too many categories", categories: []string{"art", "books", "music", "news"}, wantErr: true},
+		{name: "unknown category", categories: []string{"knitting"}, wantErr: true},
+		{name: "other can't be chosen", categories: []string{communities.CategoryOther}, wantErr: true},
+		{name: "duplicate category", categories: []string{"music", "music"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := communities.ValidateCategories(tt.categories)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			var validationErr *communities.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateCategoryFilter(t *testing.T) {
+	for _, category := range []string{"", communities.CategoryGaming, communities.CategoryOther} {
+		if err := communities.ValidateCategoryFilter(category); err != nil {
+			t.Errorf("ValidateCategoryFilter(%q) = %v, want nil", category, err)
+		}
+	}
+	if err := communities.ValidateCategoryFilter("knitting"); err == nil {
+		t.Error("Expected an unknown category filter to be rejected")
+	}
+}
+
+func TestNormalizeCategories(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{name: "missing", in: nil, want: []string{}},
+		{name: "unknown categories are kept", in: []string{"gaming", "knitting"}, want: []string{"gaming", "knitting"}},
+		{name: "empty and duplicate entries are dropped", in: []string{"", "music", "music", "news"}, want: []string{"music", "news"}},
+		{name: "capped at the maximum", in: []string{"art", "books", "food", "music"}, want: []string{"art", "books", "food"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := communities.NormalizeCategories(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NormalizeCategories(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategoryBuckets(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{name: "no categories", in: nil, want: nil},
+		{name: "known categories", in: []string{"science", "gaming"}, want: []string{"science", "gaming"}},
+		{name: "unknown category is other", in: []string{"knitting"}, want: []string{"other"}},
+		{name: "unknown categories share one other bucket", in: []string{"knitting", "gaming", "crochet"}, want: []string{"gaming", "other"}},
+		{name: "other as written is an unknown category", in: []string{"other"}, want: []string{"other"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := communities.CategoryBuckets(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CategoryBuckets(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCategoryCounts(t *testing.T) {
+	counts := communities.CategoryCounts(map[string]int{"gaming": 4, "other": 2})
+
+	if len(counts) != len(communities.Categories)+1 {
+		t.Fatalf("Expected every category plus other, got %d entries", len(counts))
+	}
+	for i, category := range communities.Categories {
+		if counts[i].Category != category {
+			t.Errorf("counts[%d] = %q, want %q", i, counts[i].Category, category)
+		}
+	}
+	last := counts[len(counts)-1]
+	if last.Category != communities.CategoryOther || last.CommunityCount != 2 {
+		t.Errorf("Expected other last with 2 communities, got %+v", last)
+	}
+	for _, c := range counts {
+		want := 0
+		if c.Category == communities.CategoryGaming {
+			want = 4
+		}
+		if c.Category != communities.CategoryOther && c.CommunityCount != want {
+			t.Errorf("%s has %d communities, want %d", c.Category, c.CommunityCount, want)
+		}
+	}
+}
+
+// The taxonomy in Go must match the one clients read from the lexicon
+func TestCategories_MatchLexicon(t *testing.T) {
+	data, err := os.ReadFile("../../internal/atproto/lexicon/social/coves/community/defs.json")
+	if err != nil {
+		t.Fatalf("Failed to read community defs: %v", err)
+	}
+	var doc struct {
+		Defs struct {
+			Category struct {
+				KnownValues []string `json:"knownValues"`
+			} `json:"category"`
+		} `json:"defs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Failed to parse community defs: %v", err)
+	}
+
+	if !reflect.DeepEqual(doc.Defs.Category.KnownValues, communities.Categories) {
+		t.Errorf("Lexicon categories %v don't match communities.Categories %v", doc.Defs.Category.KnownValues, communities.Categories)
+	}
+}
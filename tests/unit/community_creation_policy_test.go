@@ -0,0 +1,328 @@
+package unit
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeCreationPolicyRepo keeps creation history and reserved names in memory
+type fakeCreationPolicyRepo struct {
+	accounts  map[string]time.Time
+	creations map[int64]*fakeCreation
+	reserved  map[string]*communities.ReservedName
+	now       func() time.Time
+	nextID    int64
+}
+
+type fakeCreation struct {
+	at           time.Time
+	creatorDID   string
+	communityDID string
+}
+
+func newFakeCreationPolicyRepo(now func() time.Time) *fakeCreationPolicyRepo {
+	return &fakeCreationPolicyRepo{
+		accounts:  make(map[string]time.Time),
+		creations: make(map[int64]*fakeCreation),
+		reserved:  make(map[string]*communities.ReservedName),
+		now:       now,
+	}
+}
+
+func (f *fakeCreationPolicyRepo) ClaimCreation(ctx context.Context, creatorDID, name string, since time.Time, max int) (int64, error) {
+	count := 0
+	for _, c := range f.creations {
+		if c.creatorDID == creatorDID && c.at.After(since) {
+			count++
+		}
+	}
+	if max > 0 && count >= max {
+		return 0, communities.ErrTooManyCommunities
+	}
+	f.nextID++
+	f.creations[f.nextID] = &fakeCreation{at: f.now(), creatorDID: creatorDID}
+	return f.nextID, nil
+}
+
+func (f *fakeCreationPolicyRepo) CompleteCreation(ctx context.Context, id int64, communityDID string) error {
+	f.creations[id].communityDID = communityDID
+	return nil
+}
+
+func (f *fakeCreationPolicyRepo) ReleaseCreation(ctx context.Context, id int64) error {
+	if c, ok := f.creations[id]; ok && c.communityDID == "" {
+		delete(f.creations, id)
+	}
+	return nil
+}
+
+func (f *fakeCreationPolicyRepo) GetAccountCreatedAt(ctx context.Context, did string) (time.Time, error) {
+	createdAt, ok := f.accounts[did]
+	if !ok {
+		return time.Time{}, communities.ErrAccountTooNew
+	}
+	return createdAt, nil
+}
+
+func (f *fakeCreationPolicyRepo) IsNameReserved(ctx context.Context, name string) (bool, error) {
+	_, ok := f.reserved[name]
+	return ok, nil
+}
+
+func (f *fakeCreationPolicyRepo) ListReservedNames(ctx context.Context) ([]*communities.ReservedName, error) {
+	names := make([]*communities.ReservedName, 0, len(f.reserved))
+	for _, reserved := range f.reserved {
+		names = append(names, reserved)
+	}
+	return names, nil
+}
+
+func (f *fakeCreationPolicyRepo) AddReservedName(ctx context.Context, name *communities.ReservedName) error {
+	name.CreatedAt = f.now()
+	f.reserved[name.Name] = name
+	return nil
+}
+
+func (f *fakeCreationPolicyRepo) RemoveReservedName(ctx context.Context, name string) error {
+	if _, ok := f.reserved[name]; !ok {
+		return communities.ErrReservedNameNotFound
+	}
+	delete(f.reserved, name)
+	return nil
+}
+
+// testClock is a settable clock for CreationPolicyConfig.Now
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time { return c.now }
+
+func newTestCreationPolicy(t *testing.T) (*communities.CreationPolicy, *fakeCreationPolicyRepo, *testClock) {
+	t.Helper()
+	clock := &testClock{now: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	repo := newFakeCreationPolicyRepo(clock.Now)
+
+	config := communities.DefaultCreationPolicyConfig()
+	config.Now = clock.Now
+	config.AdminDIDs = []string{"did:plc:admin"}
+	return communities.NewCreationPolicy(repo, config), repo, clock
+}
+
+func TestCreationPolicy_NameSyntax(t *testing.T) {
+	policy, repo, clock := newTestCreationPolicy(t)
+	repo.accounts["did:plc:user"] = clock.now.Add(-30 * 24 * time.Hour)
+
+	tests := []struct {
+		name  string
+		valid bool
+	}{
+		{name: "gaming", valid: true},
+		{name: "rust-lang", valid: true},
+		{name: "abc", valid: true},
+		{name: "a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5", valid: true},   // 30 chars
+		{name: "a1b2c3d4e5f6g7h8i9j0k1l2m3n4o5p", valid: false}, // 31 chars
+		{name: "ab", valid: false},
+		{name: "Gaming", valid: false},
+		{name: "under_score", valid: false},
+		{name: "-gaming", valid: false},
+		{name: "gaming-", valid: false},
+		{name: "", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(context.Background(), "did:plc:user", tt.name)
+			if tt.valid && err != nil {
+				t.Errorf("Expected %q to be allowed, got %v", tt.name, err)
+			}
+			if !tt.valid && !errors.Is(err, communities.ErrInvalidCommunityName) {
+				t.Errorf("Expected ErrInvalidCommunityName for %q, got %v", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestCreationPolicy_ReservedNames(t *testing.T) {
+	ctx := context.Background()
+	policy, repo, clock := newTestCreationPolicy(t)
+	repo.accounts["did:plc:user"] = clock.now.Add(-30 * 24 * time.Hour)
+
+	t.Run("built-in names are reserved for everyone", func(t *testing.T) {
+		for _, did := range []string{"did:plc:user", "did:plc:admin"} {
+			if err := policy.Check(ctx, did, "admin"); !errors.Is(err, communities.ErrCommunityNameReserved) {
+				t.Errorf("Expected ErrCommunityNameReserved for %s, got %v", did, err)
+			}
+		}
+	})
+
+	t.Run("admin-reserved names are rejected", func(t *testing.T) {
+		if _, err := policy.AddReservedName(ctx, " Announcements ", "instance news", "did:plc:admin"); err != nil {
+			t.Fatalf("AddReservedName failed: %v", err)
+		}
+		if err := policy.Check(ctx, "did:plc:user", "announcements"); !errors.Is(err, communities.ErrCommunityNameReserved) {
+			t.Errorf("Expected ErrCommunityNameReserved, got %v", err)
+		}
+
+		if err := policy.RemoveReservedName(ctx, "announcements"); err != nil {
+			t.Fatalf("RemoveReservedName failed: %v", err)
+		}
+		if err := policy.Check(ctx, "did:plc:user", "announcements"); err != nil {
+			t.Errorf("Expected released name to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("built-in names cannot be removed", func(t *testing.T) {
+		if err := policy.RemoveReservedName(ctx, "api"); !errors.Is(err, communities.ErrReservedNameBuiltIn) {
+			t.Errorf("Expected ErrReservedNameBuiltIn, got %v", err)
+		}
+	})
+
+	t.Run("list includes built-in and admin names", func(t *testing.T) {
+		if _, err := policy.AddReservedName(ctx, "news", "", "did:plc:admin"); err != nil {
+			t.Fatalf("AddReservedName failed: %v", err)
+		}
+		names, err := policy.ListReservedNames(ctx)
+		if err != nil {
+			t.Fatalf("ListReservedNames failed: %v", err)
+		}
+
+		found := map[string]bool{}
+		for _, reserved := range names {
+			found[reserved.Name] = reserved.BuiltIn
+		}
+		if builtIn, ok := found["mod"]; !ok || !builtIn {
+			t.Error("Expected built-in name 'mod' in list")
+		}
+		if builtIn, ok := found["news"]; !ok || builtIn {
+			t.Error("Expected admin-reserved name 'news' in list")
+		}
+	})
+}
+
+func TestCreationPolicy_MinAccountAge(t *testing.T) {
+	ctx := context.Background()
+	policy, repo, clock := newTestCreationPolicy(t)
+	repo.accounts["did:plc:new"] = clock.now.Add(-time.Hour)
+	repo.accounts["did:plc:old"] = clock.now.Add(-25 * time.Hour)
+
+	if err := policy.Check(ctx, "did:plc:new", "gaming"); !errors.Is(err, communities.ErrAccountTooNew) {
+		t.Errorf("Expected ErrAccountTooNew for hour-old account, got %v", err)
+	}
+	if err := policy.Check(ctx, "did:plc:unknown", "gaming"); !errors.Is(err, communities.ErrAccountTooNew) {
+		t.Errorf("Expected ErrAccountTooNew for unindexed account, got %v", err)
+	}
+	if err := policy.Check(ctx, "did:plc:old", "gaming"); err != nil {
+		t.Errorf("Expected day-old account to be allowed, got %v", err)
+	}
+
+	// The new account ages into eligibility
+	clock.now = clock.now.Add(23 * time.Hour)
+	if err := policy.Check(ctx, "did:plc:new", "gaming"); err != nil {
+		t.Errorf("Expected account to be allowed after 24h, got %v", err)
+	}
+}
+
+func TestCreationPolicy_RateLimit(t *testing.T) {
+	ctx := context.Background()
+	policy, repo, clock := newTestCreationPolicy(t)
+	repo.accounts["did:plc:user"] = clock.now.Add(-30 * 24 * time.Hour)
+
+	for i, name := range []string{"first", "second"} {
+		claim, err := policy.ClaimCreation(ctx, "did:plc:user", name)
+		if err != nil {
+			t.Fatalf("Creation %d should be allowed, got %v", i+1, err)
+		}
+		if err := claim.Complete(ctx, "did:plc:c"+name); err != nil {
+			t.Fatalf("Complete failed: %v", err)
+		}
+		clock.now = clock.now.Add(time.Hour)
+	}
+
+	if _, err := policy.ClaimCreation(ctx, "did:plc:user", "third"); !errors.Is(err, communities.ErrTooManyCommunities) {
+		t.Errorf("Expected ErrTooManyCommunities, got %v", err)
+	}
+
+	// The first creation leaves the 24h window
+	clock.now = clock.now.Add(23 * time.Hour)
+	if _, err := policy.ClaimCreation(ctx, "did:plc:user", "third"); err != nil {
+		t.Errorf("Expected creation to be allowed once the window passes, got %v", err)
+	}
+}
+
+func TestCreationPolicy_ClaimsInFlight(t *testing.T) {
+	ctx := context.Background()
+	policy, _, _ := newTestCreationPolicy(t)
+
+	// Claims count before their creations finish, so a burst can't outrun the limit
+	first, err := policy.ClaimCreation(ctx, "did:plc:user", "first")
+	if err != nil {
+		t.Fatalf("First claim should be allowed, got %v", err)
+	}
+	if _, err := policy.ClaimCreation(ctx, "did:plc:user", "second"); err != nil {
+		t.Fatalf("Second claim should be allowed, got %v", err)
+	}
+	if _, err := policy.ClaimCreation(ctx, "did:plc:user", "third"); !errors.Is(err, communities.ErrTooManyCommunities) {
+		t.Errorf("Expected ErrTooManyCommunities while two creations are in flight, got %v", err)
+	}
+
+	// A failed creation gives its slot back
+	first.Release(ctx)
+	if _, err := policy.ClaimCreation(ctx, "did:plc:user", "third"); err != nil {
+		t.Errorf("Expected a released claim to free its slot, got %v", err)
+	}
+}
+
+func TestCreationPolicy_AdminBypass(t *testing.T) {
+	ctx := context.Background()
+	policy, _, _ := newTestCreationPolicy(t)
+
+	// Admin has no indexed account and many recent creations
+	for i := 0; i < 5; i++ {
+		if _, err := policy.ClaimCreation(ctx, "did:plc:admin", "community"); err != nil {
+			t.Fatalf("Expected admin to bypass the rate limit, got %v", err)
+		}
+	}
+	if err := policy.Check(ctx, "did:plc:admin", "gaming"); err != nil {
+		t.Errorf("Expected admin to bypass account age and rate limit, got %v", err)
+	}
+	if err := policy.Check(ctx, "did:plc:admin", "Gaming"); !errors.Is(err, communities.ErrInvalidCommunityName) {
+		t.Errorf("Expected admins to still follow name syntax, got %v", err)
+	}
+}
+
+// countingProvisioner records whether CreateCommunity reached account provisioning
+type countingProvisioner struct {
+	calls int
+}
+
+func (p *countingProvisioner) ProvisionCommunityAccount(ctx context.Context, name string) (*communities.CommunityPDSAccount, error) {
+	p.calls++
+	return nil, errors.New("provisioning not available in unit tests")
+}
+
+func TestCommunityService_CreationPolicyRunsBeforeProvisioning(t *testing.T) {
+	policy, _, _ := newTestCreationPolicy(t)
+	provisioner := &countingProvisioner{}
+
+	// No repository: a rejected request must fail before any persistence
+	service := communities.NewCommunityService(nil, "http://localhost:3001",
+		"did:web:coves.local", "coves.local", provisioner, nil, nil)
+	service.(interface {
+		SetCreationPolicy(*communities.CreationPolicy)
+	}).SetCreationPolicy(policy)
+
+	_, err := service.CreateCommunity(context.Background(), communities.CreateCommunityRequest{
+		Name:         "gaming",
+		CreatedByDID: "did:plc:brand-new",
+	})
+	if !errors.Is(err, communities.ErrAccountTooNew) {
+		t.Fatalf("Expected ErrAccountTooNew, got %v", err)
+	}
+	if provisioner.calls != 0 {
+		t.Errorf("Expected no PDS account to be provisioned, got %d calls", provisioner.calls)
+	}
+}
@@ -0,0 +1,242 @@
+package unit
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// deletionTestRepo serves community lookups from memory; other Repository methods are unused
+type deletionTestRepo struct {
+	communities.Repository
+	byDID   map[string]*communities.Community
+	actions []*communities.ModerationAction
+}
+
+func (r *deletionTestRepo) CreateModerationAction(ctx context.Context, action *communities.ModerationAction) (*communities.ModerationAction, error) {
+	r.actions = append(r.actions, action)
+	return action, nil
+}
+
+func (r *deletionTestRepo) GetByDID(ctx context.Context, did string) (*communities.Community, error) {
+	if c, ok := r.byDID[did]; ok {
+		return c, nil
+	}
+	return nil, communities.ErrCommunityNotFound
+}
+
+func (r *deletionTestRepo) GetByHandle(ctx context.Context, handle string) (*communities.Community, error) {
+	for _, c := range r.byDID {
+		if c.Handle == handle {
+			return c, nil
+		}
+	}
+	return nil, communities.ErrCommunityNotFound
+}
+
+// fakeDeletionRepo applies deletion state to the communities held by a deletionTestRepo
+type fakeDeletionRepo struct {
+	repo *deletionTestRepo
+}
+
+func (f *fakeDeletionRepo) MarkDeleted(ctx context.Context, communityDID, deletedByDID string) (time.Time, error) {
+	c, ok := f.repo.byDID[communityDID]
+	if !ok {
+		return time.Time{}, communities.ErrCommunityNotFound
+	}
+	if c.DeletedAt != nil {
+		return time.Time{}, communities.ErrCommunityDeleted
+	}
+	now := time.Now()
+	c.DeletedAt = &now
+	c.DeletedByDID = deletedByDID
+	return now, nil
+}
+
+func (f *fakeDeletionRepo) Restore(ctx context.Context, communityDID string, deletedAfter time.Time) error {
+	c, ok := f.repo.byDID[communityDID]
+	if !ok || c.DeletedAt == nil || !c.DeletedAt.After(deletedAfter) || c.PDSDeactivatedAt != nil {
+		return communities.ErrDeletionGracePeriodOver
+	}
+	c.DeletedAt = nil
+	c.DeletedByDID = ""
+	return nil
+}
+
+func (f *fakeDeletionRepo) ListDueForDeactivation(ctx context.Context, deletedBefore time.Time, limit int) ([]string, error) {
+	var dids []string
+	for did, c := range f.repo.byDID {
+		if c.DeletedAt != nil && !c.DeletedAt.After(deletedBefore) && c.PDSDeactivatedAt == nil {
+			dids = append(dids, did)
+		}
+	}
+	return dids, nil
+}
+
+func (f *fakeDeletionRepo) MarkPDSDeactivated(ctx context.Context, communityDID string) error {
+	now := time.Now()
+	f.repo.byDID[communityDID].PDSDeactivatedAt = &now
+	return nil
+}
+
+// unexpiredAccessToken builds an unsigned JWT that EnsureFreshToken won't try to refresh
+func unexpiredAccessToken() string {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"ES256K","typ":"JWT"}`))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"exp":%d}`, time.Now().Add(time.Hour).Unix())))
+	return header + "." + payload + ".c2ln"
+}
+
+func newDeletionTestService(t *testing.T, pdsURL string) (communities.Service, *deletionTestRepo) {
+	t.Helper()
+	repo := &deletionTestRepo{byDID: make(map[string]*communities.Community)}
+	repo.byDID["did:plc:gaming"] = &communities.Community{
+		DID:            "did:plc:gaming",
+		Handle:         "c-gaming.coves.local",
+		Name:           "gaming",
+		CreatedByDID:   "did:plc:owner",
+		PDSURL:         pdsURL,
+		PDSAccessToken: unexpiredAccessToken(),
+	}
+
+	service := communities.NewCommunityService(repo, pdsURL, "did:web:coves.local", "coves.local", nil, nil, nil)
+	service.(interface {
+		SetDeletionRepository(communities.DeletionRepository)
+	}).SetDeletionRepository(&fakeDeletionRepo{repo: repo})
+	service.(interface{ SetInstanceAdmins([]string) }).SetInstanceAdmins([]string{"did:plc:admin"})
+	return service, repo
+}
+
+func TestCommunityService_DeleteAndRestore(t *testing.T) {
+	service, repo := newDeletionTestService(t, "http://localhost:3001")
+	ctx := context.Background()
+	req := communities.DeleteCommunityRequest{CommunityDID: "did:plc:gaming", ActorDID: "did:plc:owner"}
+
+	deleted, err := service.DeleteCommunity(ctx, req)
+	if err != nil {
+		t.Fatalf("DeleteCommunity failed: %v", err)
+	}
+	if deleted.DeletedAt == nil || deleted.DeletedByDID != "did:plc:owner" {
+		t.Errorf("Expected the community to be marked deleted by the owner, got %+v", deleted)
+	}
+	if len(repo.actions) != 0 {
+		t.Errorf("Expected no moderation record for an owner deletion, got %+v", repo.actions)
+	}
+
+	if _, err := service.GetCommunity(ctx, "did:plc:gaming"); !errors.Is(err, communities.ErrCommunityDeleted) {
+		t.Errorf("Expected ErrCommunityDeleted from GetCommunity, got %v", err)
+	}
+	if _, err := service.DeleteCommunity(ctx, req); !errors.Is(err, communities.ErrCommunityDeleted) {
+		t.Errorf("Expected deleting twice to return ErrCommunityDeleted, got %v", err)
+	}
+
+	restored, err := service.UndeleteCommunity(ctx, req)
+	if err != nil {
+		t.Fatalf("UndeleteCommunity failed: %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("Expected the restored community to have no deletion time, got %v", restored.DeletedAt)
+	}
+	if _, err := service.GetCommunity(ctx, "did:plc:gaming"); err != nil {
+		t.Errorf("Expected the restored community to be visible, got %v", err)
+	}
+
+	if _, err := service.UndeleteCommunity(ctx, req); !errors.Is(err, communities.ErrCommunityNotDeleted) {
+		t.Errorf("Expected undeleting a live community to return ErrCommunityNotDeleted, got %v", err)
+	}
+}
+
+func TestCommunityService_DeleteAuthorization(t *testing.T) {
+	service, repo := newDeletionTestService(t, "http://localhost:3001")
+	ctx := context.Background()
+
+	_, err := service.DeleteCommunity(ctx, communities.DeleteCommunityRequest{CommunityDID: "did:plc:gaming", ActorDID: "did:plc:stranger"})
+	if !errors.Is(err, communities.ErrUnauthorized) {
+		t.Errorf("Expected a non-owner to be rejected, got %v", err)
+	}
+
+	if _, err := service.DeleteCommunity(ctx, communities.DeleteCommunityRequest{CommunityDID: "did:plc:gaming", ActorDID: "did:plc:admin"}); err != nil {
+		t.Fatalf("Expected an instance admin to delete the community, got %v", err)
+	}
+	if len(repo.actions) != 1 || repo.actions[0].Action != communities.ModerationActionRemove {
+		t.Errorf("Expected an admin deletion to be recorded as a removal, got %+v", repo.actions)
+	}
+
+	_, err = service.UndeleteCommunity(ctx, communities.DeleteCommunityRequest{CommunityDID: "did:plc:gaming", ActorDID: "did:plc:stranger"})
+	if !errors.Is(err, communities.ErrUnauthorized) {
+		t.Errorf("Expected a non-owner undelete to be rejected, got %v", err)
+	}
+	if _, err := service.UndeleteCommunity(ctx, communities.DeleteCommunityRequest{CommunityDID: "did:plc:gaming", ActorDID: "did:plc:owner"}); err != nil {
+		t.Errorf("Expected the owner to restore an admin deletion, got %v", err)
+	}
+}
+
+func TestCommunityService_RestoreAfterGracePeriod(t *testing.T) {
+	var deactivateAuth string
+	mockPDS := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/xrpc/com.atproto.server.deactivateAccount" {
+			t.Errorf("Unexpected PDS call to %s", r.URL.Path)
+		}
+		deactivateAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockPDS.Close()
+
+	service, repo := newDeletionTestService(t, mockPDS.URL)
+	ctx := context.Background()
+	community := repo.byDID["did:plc:gaming"]
+	expired := time.Now().Add(-communities.DeletionGracePeriod - time.Hour)
+	community.DeletedAt = &expired
+
+	req := communities.DeleteCommunityRequest{CommunityDID: "did:plc:gaming", ActorDID: "did:plc:owner"}
+	if _, err := service.UndeleteCommunity(ctx, req); !errors.Is(err, communities.ErrDeletionGracePeriodOver) {
+		t.Errorf("Expected ErrDeletionGracePeriodOver past the grace period, got %v", err)
+	}
+
+	deactivator := service.(interface {
+		DeactivateDeletedCommunities(ctx context.Context, limit int) (int, error)
+	})
+	deactivated, err := deactivator.DeactivateDeletedCommunities(ctx, 10)
+	if err != nil {
+		t.Fatalf("DeactivateDeletedCommunities failed: %v", err)
+	}
+	if deactivated != 1 || community.PDSDeactivatedAt == nil {
+		t.Fatalf("Expected the community's PDS account to be deactivated, got %d", deactivated)
+	}
+	if deactivateAuth != "Bearer "+community.PDSAccessToken {
+		t.Errorf("Expected the community's own credentials to be used, got %q", deactivateAuth)
+	}
+
+	// Already deactivated communities aren't picked up again
+	if deactivated, _ := deactivator.DeactivateDeletedCommunities(ctx, 10); deactivated != 0 {
+		t.Errorf("Expected no further deactivations, got %d", deactivated)
+	}
+}
+
+func TestCommunityService_DeactivationWaitsForGracePeriod(t *testing.T) {
+	mockPDS := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Expected no PDS call inside the grace period, got %s", r.URL.Path)
+	}))
+	defer mockPDS.Close()
+
+	service, repo := newDeletionTestService(t, mockPDS.URL)
+	ctx := context.Background()
+	if _, err := service.DeleteCommunity(ctx, communities.DeleteCommunityRequest{CommunityDID: "did:plc:gaming", ActorDID: "did:plc:owner"}); err != nil {
+		t.Fatalf("DeleteCommunity failed: %v", err)
+	}
+
+	deactivated, err := service.(interface {
+		DeactivateDeletedCommunities(ctx context.Context, limit int) (int, error)
+	}).DeactivateDeletedCommunities(ctx, 10)
+	if err != nil || deactivated != 0 {
+		t.Errorf("Expected nothing to deactivate, got %d (%v)", deactivated, err)
+	}
+	if repo.byDID["did:plc:gaming"].PDSDeactivatedAt != nil {
+		t.Error("Expected the PDS account to stay active during the grace period")
+	}
+}
@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"Coves/internal/core/communities"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateFlairs(t *testing.T) {
+	tooMany := make([]communities.Flair, communities.MaxFlairs+1)
+	for i := range tooMany {
+		tooMany[i] = communities.Flair{Name: fmt.Sprintf("Flair %d", i), Color: "#000000"}
+	}
+
+	tests := []struct {
+		name    string
+		flairs  []communities.Flair
+		wantErr bool
+	}{
+		{name: "empty set", flairs: nil},
+		{name: "valid set", flairs: []communities.Flair{{Name: "News", Color: "#1e90ff"}, {Name: "Discussion", Color: "#ABCDEF"}}},
+		{name: "maximum flairs", flairs: tooMany[:communities.MaxFlairs]},
+		{name: "too many flairs", flairs: tooMany, wantErr: true},
+		{name: "empty name", flairs: []communities.Flair{{Name: "", Color: "#1e90ff"}}, wantErr: true},
+		{name: "name too long", flairs: []communities.Flair{{Name: strings.Repeat("a", communities.MaxFlairNameLength+1), Color: "#1e90ff"}}, wantErr: true},
+		{name: "named color", flairs: []communities.Flair{{Name: "News", Color: "blue"}}, wantErr: true},
+		{name: "short hex color", flairs: []communities.Flair{{Name: "News", Color: "#fff"}}, wantErr: true},
+		{name: "duplicate name", flairs: []communities.Flair{{Name: "News", Color: "#1e90ff"}, {Name: "News", Color: "#ff0000"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := communities.ValidateFlairs(tt.flairs)
+			if !tt.wantErr {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			var validationErr *communities.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeFlairs(t *testing.T) {
+	flairs := []communities.Flair{
+		{Name: "News", Color: "#1e90ff"},
+		{Name: "", Color: "#1e90ff"},
+		{Name: "Bad Color", Color: "red"},
+		{Name: "News", Color: "#ff0000"},
+		{Name: "Discussion", Color: "#00ff00"},
+	}
+
+	got := communities.NormalizeFlairs(flairs)
+	want := []communities.Flair{{Name: "News", Color: "#1e90ff"}, {Name: "Discussion", Color: "#00ff00"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+
+	many := make([]communities.Flair, communities.MaxFlairs+5)
+	for i := range many {
+		many[i] = communities.Flair{Name: fmt.Sprintf("Flair %d", i), Color: "#000000"}
+	}
+	if got := communities.NormalizeFlairs(many); len(got) != communities.MaxFlairs {
+		t.Errorf("Expected flairs capped at %d, got %d", communities.MaxFlairs, len(got))
+	}
+
+	if got := communities.NormalizeFlairs(nil); got == nil || len(got) != 0 {
+		t.Errorf("Expected empty non-nil slice, got %#v", got)
+	}
+}
+
+func TestCommunity_FilterPostTags(t *testing.T) {
+	community := &communities.Community{
+		Flairs: []communities.Flair{{Name: "News", Color: "#1e90ff"}, {Name: "Discussion", Color: "#00ff00"}},
+	}
+
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "no tags", tags: nil, want: []string{}},
+		{name: "all known", tags: []string{"Discussion", "News"}, want: []string{"Discussion", "News"}},
+		{name: "unknown tags dropped", tags: []string{"Memes", "News", "news"}, want: []string{"News"}},
+		{name: "duplicates removed", tags: []string{"News", "News", "Discussion"}, want: []string{"News", "Discussion"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := community.FilterPostTags(tt.tags); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	noFlairs := &communities.Community{}
+	if got := noFlairs.FilterPostTags([]string{"News"}); len(got) != 0 {
+		t.Errorf("Expected community without flairs to drop all tags, got %v", got)
+	}
+}
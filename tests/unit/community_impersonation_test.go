@@ -0,0 +1,127 @@
+package unit
+
+import (
+	"Coves/internal/core/communities"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClaimedHandles(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{name: "no handles", text: "Official Coves Announcements", want: nil},
+		{name: "scoped handle", text: "Mirror of !announcements@coves.social", want: []string{"c-announcements.coves.social"}},
+		{name: "handle without bang", text: "news@coves.social", want: []string{"c-news.coves.social"}},
+		{name: "canonical handle", text: "c-news.coves.social official", want: []string{"c-news.coves.social"}},
+		{name: "uppercase is lowercased", text: "!News@Coves.Social", want: []string{"c-news.coves.social"}},
+		{name: "text order across forms", text: "c-b.example.com and !a@example.com", want: []string{"c-b.example.com", "c-a.example.com"}},
+		{name: "duplicates collapsed", text: "!a@example.com !a@example.com c-a.example.com", want: []string{"c-a.example.com"}},
+		{name: "domain needs a TLD", text: "!a@localhost", want: nil},
+		{name: "canonical form needs a dotted domain", text: "c-foo.bar", want: nil},
+		{name: "c- inside a word is not a handle", text: "magic-show.example.com", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, claim := range communities.ClaimedHandles(tt.text) {
+				got = append(got, claim.Handle)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ClaimedHandles(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectImpersonation(t *testing.T) {
+	verified := map[string]bool{"c-announcements.coves.social": true}
+
+	tests := []struct {
+		name        string
+		check       communities.ImpersonationCheck
+		wantFlagged bool
+		wantReason  string
+	}{
+		{
+			name:  "plain display name",
+			check: communities.ImpersonationCheck{Name: "gardening", DisplayName: "Gardening", Handle: "c-gardening.evil.example", HostedByDomain: "evil.example"},
+		},
+		{
+			name:  "own handle in display name",
+			check: communities.ImpersonationCheck{Name: "gardening", DisplayName: "Gardening (!gardening@evil.example)", Handle: "c-gardening.evil.example", HostedByDomain: "evil.example"},
+		},
+		{
+			name:  "handle on own domain that isn't a verified community",
+			check: communities.ImpersonationCheck{Name: "news", DisplayName: "Also see !other@evil.example", Handle: "c-news.evil.example", HostedByDomain: "evil.example"},
+		},
+		{
+			name:        "scoped handle on another instance",
+			check:       communities.ImpersonationCheck{Name: "news", DisplayName: "!announcements@coves.social", Handle: "c-news.evil.example", HostedByDomain: "evil.example"},
+			wantFlagged: true,
+			wantReason:  "displayName claims !announcements@coves.social but the community is hosted by evil.example",
+		},
+		{
+			name:        "canonical handle on another instance in the name",
+			check:       communities.ImpersonationCheck{Name: "c-announcements.coves.social", DisplayName: "News", Handle: "c-news.evil.example", HostedByDomain: "evil.example"},
+			wantFlagged: true,
+			wantReason:  "name claims",
+		},
+		{
+			name:        "mixed case domain",
+			check:       communities.ImpersonationCheck{Name: "news", DisplayName: "Official !Announcements@COVES.social", Handle: "c-news.evil.example", HostedByDomain: "evil.example"},
+			wantFlagged: true,
+		},
+		{
+			name:        "lookalike subdomain of another instance",
+			check:       communities.ImpersonationCheck{Name: "news", DisplayName: "!news@coves.social.evil.example", Handle: "c-news.evil.example", HostedByDomain: "evil.example"},
+			wantFlagged: false, // it's under the hosting domain, so it isn't claiming coves.social
+		},
+		{
+			name:        "parent domain of the host",
+			check:       communities.ImpersonationCheck{Name: "news", DisplayName: "!news@example", Handle: "c-news.evil.example", HostedByDomain: "evil.example"},
+			wantFlagged: false, // "example" has no TLD, so it isn't a handle
+		},
+		{
+			name:        "domain that merely ends with the host's name",
+			check:       communities.ImpersonationCheck{Name: "news", DisplayName: "!news@notevil.example", Handle: "c-news.evil.example", HostedByDomain: "evil.example"},
+			wantFlagged: true,
+		},
+		{
+			name: "verified community handle on the same instance",
+			check: communities.ImpersonationCheck{
+				Name: "announcements2", DisplayName: "!announcements@coves.social", Handle: "c-announcements2.coves.social",
+				HostedByDomain: "coves.social", VerifiedHandles: verified,
+			},
+			wantFlagged: true,
+			wantReason:  "displayName claims the handle of verified community !announcements@coves.social",
+		},
+		{
+			name: "verified community claiming itself",
+			check: communities.ImpersonationCheck{
+				Name: "announcements", DisplayName: "!announcements@coves.social", Handle: "c-announcements.coves.social",
+				HostedByDomain: "coves.social", VerifiedHandles: verified,
+			},
+		},
+		{
+			name:  "no hosting domain only checks verified handles",
+			check: communities.ImpersonationCheck{Name: "news", DisplayName: "!news@elsewhere.example", Handle: "c-news.dev.local"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason := communities.DetectImpersonation(tt.check)
+			if flagged := reason != ""; flagged != tt.wantFlagged {
+				t.Fatalf("DetectImpersonation() = %q, want flagged=%v", reason, tt.wantFlagged)
+			}
+			if !strings.HasPrefix(reason, tt.wantReason) {
+				t.Errorf("DetectImpersonation() = %q, want prefix %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package unit
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeNameReservationRepo keeps name reservations in memory
+type fakeNameReservationRepo struct {
+	byName map[string]string // name -> token
+	mu     sync.Mutex
+}
+
+func newFakeNameReservationRepo() *fakeNameReservationRepo {
+	return &fakeNameReservationRepo{byName: make(map[string]string)}
+}
+
+func (f *fakeNameReservationRepo) Reserve(ctx context.Context, name, token string, expiresAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.byName[name]; ok {
+		return communities.ErrHandleTaken
+	}
+	f.byName[name] = token
+	return nil
+}
+
+func (f *fakeNameReservationRepo) Release(ctx context.Context, name, token string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.byName[name] == token {
+		delete(f.byName, name)
+	}
+	return nil
+}
+
+func (f *fakeNameReservationRepo) DeleteExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeNameReservationRepo) held() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.byName)
+}
+
+// gatedProvisioner blocks account provisioning until gate is closed
+type gatedProvisioner struct {
+	gate   chan struct{}
+	err    error
+	pdsURL string
+	calls  int32
+}
+
+func (p *gatedProvisioner) ProvisionCommunityAccount(ctx context.Context, name string) (*communities.CommunityPDSAccount, error) {
+	atomic.AddInt32(&p.calls, 1)
+	<-p.gate
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &communities.CommunityPDSAccount{
+		DID:          "did:plc:" + name,
+		Handle:       "c-" + name + ".coves.local",
+		Email:        "c-" + name + "@coves.local",
+		Password:     "secret",
+		AccessToken:  unexpiredAccessToken(),
+		RefreshToken: "refresh",
+		PDSURL:       p.pdsURL,
+	}, nil
+}
+
+func newReservationTestService(t *testing.T, provisioner communities.AccountProvisioner) (communities.Service, *fakeNameReservationRepo) {
+	t.Helper()
+	pds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"uri":"at://did:plc:gaming/social.coves.community.profile/self","cid":"bafyprofile"}`))
+	}))
+	t.Cleanup(pds.Close)
+	if gated, ok := provisioner.(*gatedProvisioner); ok {
+		gated.pdsURL = pds.URL
+	}
+
+	repo := &provisioningTestRepo{byDID: make(map[string]*communities.Community)}
+	reservations := newFakeNameReservationRepo()
+	service := communities.NewCommunityService(repo, pds.URL, "did:web:coves.local", "coves.local", provisioner, nil, nil)
+	service.(interface {
+		SetNameReservationRepository(communities.NameReservationRepository)
+	}).SetNameReservationRepository(reservations)
+	return service, reservations
+}
+
+func TestCommunityService_ConcurrentCreatesOfOneName(t *testing.T) {
+	provisioner := &gatedProvisioner{gate: make(chan struct{})}
+	service, reservations := newReservationTestService(t, provisioner)
+	released := false
+	t.Cleanup(func() {
+		if !released {
+			close(provisioner.gate)
+		}
+	})
+
+	// Both creations start together; the names differ only in case, so they'd get the same handle
+	type result struct {
+		community *communities.Community
+		err       error
+	}
+	results := make(chan result, 2)
+	start := make(chan struct{})
+	for _, name := range []string{"gaming", "Gaming"} {
+		go func(name string) {
+			<-start
+			community, err := service.CreateCommunity(context.Background(), communities.CreateCommunityRequest{
+				Name:         name,
+				CreatedByDID: "did:plc:creator-" + name,
+			})
+			results <- result{community: community, err: err}
+		}(name)
+	}
+	close(start)
+
+	// The loser fails while the winner is still provisioning, without reaching the PDS
+	select {
+	case loser := <-results:
+		if !errors.Is(loser.err, communities.ErrHandleTaken) {
+			t.Fatalf("Expected ErrHandleTaken for the second creation, got %v", loser.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected one creation to fail fast while the other holds the name")
+	}
+	if calls := atomic.LoadInt32(&provisioner.calls); calls != 1 {
+		t.Errorf("Expected one PDS account to be provisioned, got %d", calls)
+	}
+
+	close(provisioner.gate)
+	released = true
+	select {
+	case winner := <-results:
+		if winner.err != nil {
+			t.Fatalf("Expected the first creation to succeed, got %v", winner.err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the first creation to finish")
+	}
+
+	if held := reservations.held(); held != 0 {
+		t.Errorf("Expected the reservation to be released after success, %d still held", held)
+	}
+}
+
+func TestCommunityService_ReservationReleasedOnFailure(t *testing.T) {
+	provisioner := &gatedProvisioner{gate: make(chan struct{}), err: errors.New("PDS unavailable")}
+	close(provisioner.gate)
+	service, reservations := newReservationTestService(t, provisioner)
+
+	req := communities.CreateCommunityRequest{Name: "gaming", CreatedByDID: "did:plc:creator"}
+	for attempt := 1; attempt <= 2; attempt++ {
+		_, err := service.CreateCommunity(context.Background(), req)
+		if errors.Is(err, communities.ErrHandleTaken) {
+			t.Fatalf("Attempt %d: expected the failed attempt's reservation to be released", attempt)
+		}
+		if err == nil {
+			t.Fatalf("Attempt %d: expected the provisioning failure", attempt)
+		}
+	}
+	if held := reservations.held(); held != 0 {
+		t.Errorf("Expected no reservations held, got %d", held)
+	}
+	if calls := atomic.LoadInt32(&provisioner.calls); calls != 2 {
+		t.Errorf("Expected both attempts to reach provisioning, got %d", calls)
+	}
+}
+
+func TestNormalizeCommunityName(t *testing.T) {
+	for name, want := range map[string]string{"gaming": "gaming", "Gaming": "gaming", "RETRO-Games-2": "retro-games-2"} {
+		if got := communities.NormalizeCommunityName(name); got != want {
+			t.Errorf("NormalizeCommunityName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package unit
+
+import (
+	"Coves/internal/core/communities"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeProvisioningRepo keeps provisionings in memory
+type fakeProvisioningRepo struct {
+	byName map[string]*communities.Provisioning
+}
+
+func (f *fakeProvisioningRepo) Save(ctx context.Context, p *communities.Provisioning) error {
+	saved := *p
+	if existing, ok := f.byName[p.Name]; ok {
+		saved.CreatedAt = existing.CreatedAt
+	} else {
+		saved.CreatedAt = time.Now()
+	}
+	saved.UpdatedAt = time.Now()
+	f.byName[p.Name] = &saved
+	return nil
+}
+
+func (f *fakeProvisioningRepo) Get(ctx context.Context, name string) (*communities.Provisioning, error) {
+	p, ok := f.byName[name]
+	if !ok {
+		return nil, communities.ErrProvisioningNotFound
+	}
+	copied := *p
+	return &copied, nil
+}
+
+func (f *fakeProvisioningRepo) ListStuck(ctx context.Context, updatedBefore time.Time, limit int) ([]*communities.Provisioning, error) {
+	result := []*communities.Provisioning{}
+	for _, p := range f.byName {
+		if p.State != communities.ProvisioningIndexed && p.UpdatedAt.Before(updatedBefore) && len(result) < limit {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeProvisioningRepo) Delete(ctx context.Context, name string) error {
+	if _, ok := f.byName[name]; !ok {
+		return communities.ErrProvisioningNotFound
+	}
+	delete(f.byName, name)
+	return nil
+}
+
+// provisioningTestRepo stores created communities in memory; other Repository methods are unused
+type provisioningTestRepo struct {
+	communities.Repository
+	byDID map[string]*communities.Community
+	mu    sync.Mutex
+}
+
+func (r *provisioningTestRepo) Create(ctx context.Context, community *communities.Community) (*communities.Community, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.byDID[community.DID]; ok {
+		return nil, communities.ErrCommunityAlreadyExists
+	}
+	r.byDID[community.DID] = community
+	return community, nil
+}
+
+func (r *provisioningTestRepo) GetByHandle(ctx context.Context, handle string) (*communities.Community, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.byDID {
+		if c.Handle == handle {
+			return c, nil
+		}
+	}
+	return nil, communities.ErrCommunityNotFound
+}
+
+// fixedAccountProvisioner hands out the same PDS account per name and counts how often it's asked
+type fixedAccountProvisioner struct {
+	pdsURL string
+	calls  int
+}
+
+func (p *fixedAccountProvisioner) ProvisionCommunityAccount(ctx context.Context, name string) (*communities.CommunityPDSAccount, error) {
+	p.calls++
+	return &communities.CommunityPDSAccount{
+		DID:          "did:plc:" + name,
+		Handle:       "c-" + name + ".coves.local",
+		Email:        "community-" + name + "@coves.local",
+		Password:     "secret",
+		AccessToken:  unexpiredAccessToken(),
+		RefreshToken: "refresh",
+		PDSURL:       p.pdsURL,
+	}, nil
+}
+
+// newFlakyPDS serves a PDS whose createRecord fails; putRecord and sessions work
+func newFlakyPDS(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var createRecordCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.repo.createRecord":
+			atomic.AddInt32(&createRecordCalls, 1)
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`{"error":"UpstreamFailure"}`))
+		case "/xrpc/com.atproto.repo.putRecord":
+			_, _ = w.Write([]byte(`{"uri":"at://did:plc:gardening/social.coves.community.profile/self","cid":"bafyprofile"}`))
+		case "/xrpc/com.atproto.server.createSession":
+			_, _ = w.Write([]byte(`{"did":"did:plc:gardening","handle":"c-gardening.coves.local","accessJwt":"` + unexpiredAccessToken() + `","refreshJwt":"refresh2"}`))
+		case "/xrpc/com.atproto.identity.resolveHandle":
+			_, _ = w.Write([]byte(`{"did":"did:plc:gardening"}`))
+		default:
+			t.Errorf("unexpected PDS call %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &createRecordCalls
+}
+
+func newProvisioningTestService(pdsURL string, provisioner communities.AccountProvisioner) (communities.Service, *provisioningTestRepo, *fakeProvisioningRepo) {
+	repo := &provisioningTestRepo{byDID: make(map[string]*communities.Community)}
+	provisionings := &fakeProvisioningRepo{byName: make(map[string]*communities.Provisioning)}
+	service := communities.NewCommunityService(repo, pdsURL, "did:web:coves.local", "coves.local", provisioner, nil, nil)
+	service.(interface {
+		SetProvisioningRepository(communities.ProvisioningRepository)
+	}).SetProvisioningRepository(provisionings)
+	return service, repo, provisionings
+}
+
+func TestCommunityService_CreateResumesAfterPDSFailure(t *testing.T) {
+	pds, createRecordCalls := newFlakyPDS(t)
+	provisioner := &fixedAccountProvisioner{pdsURL: pds.URL}
+	service, repo, provisionings := newProvisioningTestService(pds.URL, provisioner)
+	ctx := context.Background()
+
+	req := communities.CreateCommunityRequest{
+		Name:         "gardening",
+		CreatedByDID: "did:plc:creator",
+	}
+
+	_, err := service.CreateCommunity(ctx, req)
+	var provisioningErr *communities.ProvisioningError
+	if !errors.As(err, &provisioningErr) {
+		t.Fatalf("expected ProvisioningError, got %v", err)
+	}
+	if provisioningErr.State != communities.ProvisioningAccountCreated {
+		t.Errorf("expected state %s, got %s", communities.ProvisioningAccountCreated, provisioningErr.State)
+	}
+	saved, err := provisionings.Get(ctx, "gardening")
+	if err != nil {
+		t.Fatalf("expected provisioning to be recorded: %v", err)
+	}
+	if saved.LastError == "" {
+		t.Error("expected the failure to be recorded")
+	}
+
+	// Someone else can't take over the half-provisioned name
+	_, err = service.CreateCommunity(ctx, communities.CreateCommunityRequest{Name: "gardening", CreatedByDID: "did:plc:other"})
+	if !errors.Is(err, communities.ErrHandleTaken) {
+		t.Errorf("expected ErrHandleTaken for another creator, got %v", err)
+	}
+
+	// The creator's retry reuses the account and puts the profile record
+	community, err := service.CreateCommunity(ctx, req)
+	if err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if community.DID != "did:plc:gardening" {
+		t.Errorf("expected resumed DID, got %s", community.DID)
+	}
+	if community.PDSRefreshToken != "refresh2" {
+		t.Errorf("expected a fresh session, got refresh token %q", community.PDSRefreshToken)
+	}
+	if provisioner.calls != 1 {
+		t.Errorf("expected one PDS account, provisioned %d", provisioner.calls)
+	}
+	if calls := atomic.LoadInt32(createRecordCalls); calls != 1 {
+		t.Errorf("expected createRecord only on the first attempt, got %d calls", calls)
+	}
+	if _, ok := repo.byDID["did:plc:gardening"]; !ok {
+		t.Error("expected community to be stored")
+	}
+
+	saved, err = provisionings.Get(ctx, "gardening")
+	if err != nil {
+		t.Fatalf("expected provisioning to remain: %v", err)
+	}
+	if saved.State != communities.ProvisioningIndexed || saved.LastError != "" {
+		t.Errorf("expected indexed with no error, got %s (%q)", saved.State, saved.LastError)
+	}
+}
+
+func TestCommunityService_CleanupProvisioning(t *testing.T) {
+	var deleted int32
+	pds := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/xrpc/com.atproto.identity.resolveHandle":
+			_, _ = w.Write([]byte(`{"did":"did:plc:stuck"}`))
+		case "/xrpc/com.atproto.admin.deleteAccount":
+			if user, pass, ok := r.BasicAuth(); !ok || user != "admin" || pass != "pds-admin" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			atomic.AddInt32(&deleted, 1)
+		default:
+			t.Errorf("unexpected PDS call %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(pds.Close)
+
+	service, _, provisionings := newProvisioningTestService(pds.URL, &fixedAccountProvisioner{pdsURL: pds.URL})
+	service.(interface{ SetPDSAdminPassword(string) }).SetPDSAdminPassword("pds-admin")
+	ctx := context.Background()
+
+	provisionings.byName["stuck"] = &communities.Provisioning{
+		Name: "stuck", DID: "did:plc:stuck", Handle: "c-stuck.coves.local", PDSURL: pds.URL,
+		State: communities.ProvisioningAccountCreated, UpdatedAt: time.Now().Add(-2 * communities.StuckProvisioningAge),
+	}
+	provisionings.byName["recent"] = &communities.Provisioning{
+		Name: "recent", DID: "did:plc:recent", Handle: "c-recent.coves.local", PDSURL: pds.URL,
+		State: communities.ProvisioningRecordWritten, UpdatedAt: time.Now(),
+	}
+
+	stuck, err := service.(interface {
+		ListStuckProvisionings(ctx context.Context, limit int) ([]*communities.Provisioning, error)
+	}).ListStuckProvisionings(ctx, 10)
+	if err != nil {
+		t.Fatalf("ListStuckProvisionings failed: %v", err)
+	}
+	if len(stuck) != 1 || stuck[0].Name != "stuck" {
+		t.Fatalf("expected only the stuck provisioning, got %d", len(stuck))
+	}
+
+	cleanup := service.(interface {
+		CleanupProvisioning(ctx context.Context, name string) error
+	}).CleanupProvisioning
+	if err := cleanup(ctx, "recent"); !errors.Is(err, communities.ErrProvisioningNotStuck) {
+		t.Errorf("expected ErrProvisioningNotStuck for a recent creation, got %v", err)
+	}
+	if err := cleanup(ctx, "stuck"); err != nil {
+		t.Fatalf("CleanupProvisioning failed: %v", err)
+	}
+	if atomic.LoadInt32(&deleted) != 1 {
+		t.Error("expected the orphaned account to be deleted")
+	}
+	if _, err := provisionings.Get(ctx, "stuck"); !errors.Is(err, communities.ErrProvisioningNotFound) {
+		t.Errorf("expected provisioning to be forgotten, got %v", err)
+	}
+}
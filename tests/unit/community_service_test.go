@@ -108,14 +108,18 @@ func (m *mockCommunityRepo) GetSubscriptionByURI(ctx context.Context, recordURI
 	return nil, communities.ErrSubscriptionNotFound
 }
 
-func (m *mockCommunityRepo) ListSubscriptions(ctx context.Context, userDID string, limit, offset int) ([]*communities.Subscription, error) {
-	return nil, nil
+func (m *mockCommunityRepo) ListSubscriptions(ctx context.Context, userDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
 }
 
-func (m *mockCommunityRepo) ListSubscribers(ctx context.Context, communityDID string, limit, offset int) ([]*communities.Subscription, error) {
+func (m *mockCommunityRepo) ListSubscribedCommunities(ctx context.Context, req communities.GetSubscriptionsRequest) ([]*communities.SubscribedCommunity, error) {
 	return nil, nil
 }
 
+func (m *mockCommunityRepo) ListSubscribers(ctx context.Context, communityDID string, limit int, cursor *string) ([]*communities.Subscription, *string, error) {
+	return nil, nil, nil
+}
+
 func (m *mockCommunityRepo) BlockCommunity(ctx context.Context, block *communities.CommunityBlock) (*communities.CommunityBlock, error) {
 	return block, nil
 }
@@ -180,6 +184,18 @@ func (m *mockCommunityRepo) DecrementSubscriberCount(ctx context.Context, commun
 	return nil
 }
 
+func (m *mockCommunityRepo) AdjustSubscriberCounts(ctx context.Context, deltas map[string]int) error {
+	return nil
+}
+
+func (m *mockCommunityRepo) RecountSubscriberCounts(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
+func (m *mockCommunityRepo) RecountPostCounts(ctx context.Context) (int, error) {
+	return 0, nil
+}
+
 func (m *mockCommunityRepo) IncrementPostCount(ctx context.Context, communityDID string) error {
 	return nil
 }
@@ -0,0 +1,98 @@
+package unit
+
+import (
+	"Coves/internal/core/aggregators"
+	"Coves/internal/core/comments"
+	"Coves/internal/core/communities"
+	"Coves/internal/core/communityFeeds"
+	"Coves/internal/core/discover"
+	coreerrors "Coves/internal/core/errors"
+	"Coves/internal/core/federation"
+	"Coves/internal/core/moderation"
+	"Coves/internal/core/posts"
+	"Coves/internal/core/timeline"
+	"Coves/internal/core/users"
+	"Coves/internal/core/votes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// wrapTwice wraps err the way a repository and then a service would
+func wrapTwice(err error) error {
+	return fmt.Errorf("service: %w", fmt.Errorf("repository: %w", err))
+}
+
+func TestErrorKinds_MatchThroughWrapping(t *testing.T) {
+	tests := []struct {
+		sentinel error
+		kind     error
+		isHelper func(error) bool
+		name     string
+	}{
+		{name: "aggregator not found", sentinel: aggregators.ErrAggregatorNotFound, kind: coreerrors.ErrNotFound, isHelper: aggregators.IsNotFound},
+		{name: "aggregator already authorized", sentinel: aggregators.ErrAlreadyAuthorized, kind: coreerrors.ErrAlreadyExists, isHelper: aggregators.IsConflict},
+		{name: "comment not found", sentinel: comments.ErrCommentNotFound, kind: coreerrors.ErrNotFound, isHelper: comments.IsNotFound},
+		{name: "comment invalid request", sentinel: comments.ErrInvalidRequest, kind: coreerrors.ErrInvalidInput, isHelper: comments.IsValidationError},
+		{name: "comment thread locked", sentinel: comments.ErrThreadLocked, kind: coreerrors.ErrForbidden},
+		{name: "community not found", sentinel: communities.ErrCommunityNotFound, kind: coreerrors.ErrNotFound, isHelper: communities.IsNotFound},
+		{name: "membership already exists", sentinel: communities.ErrMembershipAlreadyExists, kind: coreerrors.ErrAlreadyExists, isHelper: communities.IsConflict},
+		{name: "community feed not found", sentinel: communityFeeds.ErrCommunityNotFound, kind: coreerrors.ErrNotFound, isHelper: communityFeeds.IsNotFound},
+		{name: "discover invalid cursor", sentinel: discover.ErrInvalidCursor, kind: coreerrors.ErrInvalidInput},
+		{name: "featured community not found", sentinel: discover.ErrFeaturedCommunityNotFound, kind: coreerrors.ErrNotFound, isHelper: discover.IsNotFound},
+		{name: "federation rule not found", sentinel: federation.ErrRuleNotFound, kind: coreerrors.ErrNotFound, isHelper: federation.IsNotFound},
+		{name: "moderation content not found", sentinel: moderation.ErrContentNotFound, kind: coreerrors.ErrNotFound, isHelper: moderation.IsNotFound},
+		{name: "post not found", sentinel: posts.ErrNotFound, kind: coreerrors.ErrNotFound, isHelper: posts.IsNotFound},
+		{name: "post already indexed", sentinel: posts.ErrAlreadyIndexed, kind: coreerrors.ErrAlreadyExists, isHelper: posts.IsConflict},
+		{name: "timeline unauthorized", sentinel: timeline.ErrUnauthorized, kind: coreerrors.ErrUnauthorized, isHelper: timeline.IsUnauthorized},
+		{name: "user not found", sentinel: users.ErrUserNotFound, kind: coreerrors.ErrNotFound, isHelper: users.IsNotFound},
+		{name: "user already exists", sentinel: users.ErrUserAlreadyExists, kind: coreerrors.ErrAlreadyExists, isHelper: users.IsConflict},
+		{name: "vote not found", sentinel: votes.ErrVoteNotFound, kind: coreerrors.ErrNotFound, isHelper: votes.IsNotFound},
+		{name: "vote invalid direction", sentinel: votes.ErrInvalidDirection, kind: coreerrors.ErrInvalidInput, isHelper: votes.IsValidationError},
+		{name: "vote invalid record", sentinel: votes.ErrInvalidRecord, kind: coreerrors.ErrInvalidInput, isHelper: votes.IsValidationError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := wrapTwice(tt.sentinel)
+			if !errors.Is(err, tt.sentinel) {
+				t.Error("Expected wrapped error to match its sentinel")
+			}
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("Expected wrapped error to match kind %v", tt.kind)
+			}
+			if tt.isHelper != nil && !tt.isHelper(err) {
+				t.Error("Expected the package Is* helper to match the wrapped error")
+			}
+			if got := coreerrors.SentinelMessage(err); got != tt.sentinel.Error() {
+				t.Errorf("Expected sentinel message %q, got %q", tt.sentinel.Error(), got)
+			}
+		})
+	}
+}
+
+func TestErrorKinds_SentinelsStayDistinct(t *testing.T) {
+	// Sentinels of the same kind share a kind, not an identity
+	if errors.Is(wrapTwice(posts.ErrCommunityNotFound), communities.ErrCommunityNotFound) {
+		t.Error("posts.ErrCommunityNotFound should not match communities.ErrCommunityNotFound")
+	}
+	if errors.Is(users.ErrUserNotFound, users.ErrPreferencesNotFound) {
+		t.Error("users.ErrUserNotFound should not match users.ErrPreferencesNotFound")
+	}
+	if errors.Is(votes.ErrVoteNotFound, coreerrors.ErrAlreadyExists) {
+		t.Error("votes.ErrVoteNotFound should not match another kind")
+	}
+	if coreerrors.IsNotFound(errors.New("resource not found")) {
+		t.Error("An error with the same message but no kind should not match")
+	}
+}
+
+func TestErrorKinds_NotFoundErrorType(t *testing.T) {
+	err := wrapTwice(posts.NewNotFoundError("post", "at://did:plc:alice/social.coves.community.post/1"))
+	if !posts.IsNotFound(err) {
+		t.Error("Expected posts.IsNotFound to match a wrapped NotFoundError")
+	}
+	if !coreerrors.IsNotFound(err) {
+		t.Error("Expected a wrapped NotFoundError to be of kind ErrNotFound")
+	}
+}